@@ -0,0 +1,135 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package amqp contains the domain concept definitions needed to support
+// Mainflux AMQP 1.0 adapter service functionality.
+package amqp
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/payload"
+	"github.com/mainflux/mainflux/things"
+	"github.com/mainflux/mainflux/things/keys"
+	broker "github.com/nats-io/nats.go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	// ErrFailedMessagePublish indicates that message publishing failed.
+	ErrFailedMessagePublish = errors.New("failed to publish message")
+
+	// ErrFailedSubscription indicates that client couldn't subscribe to specified channel.
+	ErrFailedSubscription = errors.New("failed to subscribe to a channel")
+
+	// ErrFailedConnection indicates that service couldn't connect to message broker.
+	ErrFailedConnection = errors.New("failed to connect to message broker")
+)
+
+// PubSub represents the message broker the messages are exchanged through.
+type PubSub interface {
+	mainflux.MessagePublisher
+
+	// Subscribe subscribes to the messages published to the channel
+	// subtopic. It returns the messages and the function canceling the
+	// subscription.
+	Subscribe(string, string) (<-chan mainflux.RawMessage, func(), error)
+}
+
+// Service specifies AMQP adapter service API.
+type Service interface {
+	PubSub
+
+	// Identify returns the ID of the thing identified by the provided key.
+	Identify(context.Context, string) (string, error)
+
+	// Authorize returns the ID of the thing identified by the provided key
+	// if it is allowed the action on the channel.
+	Authorize(context.Context, string, string, string) (string, error)
+}
+
+var _ Service = (*adapterService)(nil)
+
+type adapterService struct {
+	pubsub PubSub
+	things mainflux.ThingsServiceClient
+}
+
+// New instantiates the AMQP adapter implementation.
+func New(pubsub PubSub, things mainflux.ThingsServiceClient) Service {
+	return &adapterService{
+		pubsub: pubsub,
+		things: things,
+	}
+}
+
+func (as *adapterService) Publish(ctx context.Context, token string, msg mainflux.RawMessage) error {
+	if err := as.pubsub.Publish(ctx, token, msg); err != nil {
+		switch err {
+		case broker.ErrConnectionClosed, broker.ErrInvalidConnection:
+			return ErrFailedConnection
+		case payload.ErrTooLarge:
+			return err
+		default:
+			return ErrFailedMessagePublish
+		}
+	}
+
+	return nil
+}
+
+func (as *adapterService) Subscribe(chanID, subtopic string) (<-chan mainflux.RawMessage, func(), error) {
+	msgs, cancel, err := as.pubsub.Subscribe(chanID, subtopic)
+	if err != nil {
+		return nil, nil, ErrFailedSubscription
+	}
+
+	return msgs, cancel, nil
+}
+
+func (as *adapterService) Identify(ctx context.Context, key string) (string, error) {
+	if keys.Malformed(key) {
+		return "", things.ErrUnauthorizedAccess
+	}
+
+	id, err := as.things.Identify(ctx, &mainflux.Token{Value: key})
+	if err != nil {
+		return "", toAuthError(err)
+	}
+
+	return id.GetValue(), nil
+}
+
+func (as *adapterService) Authorize(ctx context.Context, key, chanID, action string) (string, error) {
+	if keys.Malformed(key) {
+		return "", things.ErrUnauthorizedAccess
+	}
+
+	ar := &mainflux.AccessReq{
+		Token:  key,
+		ChanID: chanID,
+		Action: action,
+	}
+	id, err := as.things.CanAccess(ctx, ar)
+	if err != nil {
+		return "", toAuthError(err)
+	}
+
+	return id.GetValue(), nil
+}
+
+// toAuthError maps the rejected credentials to the unauthorized access error,
+// keeping the errors of the unavailable things service.
+func toAuthError(err error) error {
+	if e, ok := status.FromError(err); ok {
+		switch e.Code() {
+		case codes.PermissionDenied, codes.NotFound, codes.Unauthenticated:
+			return things.ErrUnauthorizedAccess
+		}
+	}
+
+	return err
+}
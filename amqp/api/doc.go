@@ -0,0 +1,6 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package api contains the AMQP 1.0 server of the adapter, along with the
+// middlewares of the adapter service.
+package api
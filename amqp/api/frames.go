@@ -0,0 +1,632 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+const (
+	frameHeaderSize = 8
+	// minMaxFrameSize is the MIN-MAX-FRAME-SIZE of AMQP 1.0 section 2.7.1.
+	minMaxFrameSize = 512
+
+	frameAMQP byte = 0x00
+	frameSASL byte = 0x01
+)
+
+// Protocol headers of the SASL and AMQP layers (AMQP 1.0 section 2.2).
+var (
+	saslHeader = []byte{'A', 'M', 'Q', 'P', 3, 1, 0, 0}
+	amqpHeader = []byte{'A', 'M', 'Q', 'P', 0, 1, 0, 0}
+)
+
+var (
+	errFrameSize       = errors.New("invalid frame size")
+	errMalformedFrame  = errors.New("malformed frame")
+	errUnexpectedFrame = errors.New("unexpected frame")
+)
+
+// Error conditions sent to the clients (AMQP 1.0 section 2.8.15).
+const (
+	condInternalError   symbol = "amqp:internal-error"
+	condNotFound        symbol = "amqp:not-found"
+	condUnauthorized    symbol = "amqp:unauthorized-access"
+	condDecodeError     symbol = "amqp:decode-error"
+	condNotAllowed      symbol = "amqp:not-allowed"
+	condInvalidField    symbol = "amqp:invalid-field"
+	condFramingError    symbol = "amqp:connection:framing-error"
+	condMessageTooLarge symbol = "amqp:link:message-size-exceeded"
+)
+
+// frame is the AMQP or SASL frame. The heartbeat frames have no body.
+type frame struct {
+	typ     byte
+	channel uint16
+	body    described
+	payload []byte
+	empty   bool
+}
+
+// readFrame reads the frame not larger than the provided size.
+func readFrame(r io.Reader, maxSize uint32) (frame, error) {
+	var hdr [frameHeaderSize]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return frame{}, err
+	}
+
+	size := binary.BigEndian.Uint32(hdr[0:4])
+	doff := uint32(hdr[4]) * 4
+	if size < frameHeaderSize || size > maxSize || doff < frameHeaderSize || doff > size {
+		return frame{}, errFrameSize
+	}
+
+	data := make([]byte, size-frameHeaderSize)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return frame{}, err
+	}
+
+	f := frame{
+		typ:     hdr[5],
+		channel: binary.BigEndian.Uint16(hdr[6:8]),
+	}
+
+	// Extended header is ignored.
+	data = data[doff-frameHeaderSize:]
+	if len(data) == 0 {
+		f.empty = true
+		return f, nil
+	}
+
+	d := &decoder{buf: data}
+	v, err := d.value(0)
+	if err != nil {
+		return frame{}, err
+	}
+	body, ok := v.(described)
+	if !ok {
+		return frame{}, errMalformedFrame
+	}
+	f.body = body
+	f.payload = d.buf
+
+	return f, nil
+}
+
+// encodeFrame returns the encoded frame carrying the provided performative
+// and payload, or the heartbeat frame if the performative is nil.
+func encodeFrame(typ byte, channel uint16, body performative, payload []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Write(make([]byte, frameHeaderSize))
+	if body != nil {
+		if err := encode(&buf, body.described()); err != nil {
+			return nil, err
+		}
+	}
+	buf.Write(payload)
+
+	b := buf.Bytes()
+	binary.BigEndian.PutUint32(b[0:4], uint32(len(b)))
+	b[4] = 2
+	b[5] = typ
+	binary.BigEndian.PutUint16(b[6:8], channel)
+	return b, nil
+}
+
+// performative is the body of the frame sent to the client.
+type performative interface {
+	described() described
+}
+
+// fields is the list of the fields of the performative or the other
+// described list, whose absent trailing fields are null.
+type fields []interface{}
+
+func toFields(v interface{}) (fields, error) {
+	switch v := v.(type) {
+	case []interface{}:
+		return fields(v), nil
+	case nil:
+		return fields{}, nil
+	default:
+		return nil, errMalformedFrame
+	}
+}
+
+func (f fields) get(i int) interface{} {
+	if i >= len(f) {
+		return nil
+	}
+	return f[i]
+}
+
+// uint returns the unsigned integer field, or the default if it's null.
+func (f fields) uint(i int, def uint64) (uint64, error) {
+	switch v := f.get(i).(type) {
+	case nil:
+		return def, nil
+	case uint8:
+		return uint64(v), nil
+	case uint16:
+		return uint64(v), nil
+	case uint32:
+		return uint64(v), nil
+	case uint64:
+		return v, nil
+	default:
+		return 0, errMalformedFrame
+	}
+}
+
+func (f fields) uint32(i int, def uint32) (uint32, error) {
+	v, err := f.uint(i, uint64(def))
+	if err != nil || v > 0xffffffff {
+		return 0, errMalformedFrame
+	}
+	return uint32(v), nil
+}
+
+func (f fields) bool(i int) (bool, error) {
+	switch v := f.get(i).(type) {
+	case nil:
+		return false, nil
+	case bool:
+		return v, nil
+	default:
+		return false, errMalformedFrame
+	}
+}
+
+func (f fields) string(i int) (string, error) {
+	switch v := f.get(i).(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case symbol:
+		return string(v), nil
+	default:
+		return "", errMalformedFrame
+	}
+}
+
+func (f fields) binary(i int) ([]byte, error) {
+	switch v := f.get(i).(type) {
+	case nil:
+		return nil, nil
+	case []byte:
+		return v, nil
+	default:
+		return nil, errMalformedFrame
+	}
+}
+
+// list returns the described list, trimming the trailing null fields.
+func list(desc uint64, items ...interface{}) described {
+	n := len(items)
+	for n > 0 && items[n-1] == nil {
+		n--
+	}
+	return described{descriptor: desc, value: items[:n]}
+}
+
+// optional returns nil for the zero value, so that the field is omitted.
+func optional(v interface{}, set bool) interface{} {
+	if !set {
+		return nil
+	}
+	return v
+}
+
+type amqpError struct {
+	condition   symbol
+	description string
+}
+
+func (e *amqpError) value() interface{} {
+	if e == nil {
+		return nil
+	}
+	return list(descError, e.condition, optional(e.description, e.description != ""))
+}
+
+func (e *amqpError) Error() string {
+	return string(e.condition) + ": " + e.description
+}
+
+type saslMechanisms struct {
+	mechanisms symbol
+}
+
+func (m saslMechanisms) described() described {
+	return list(descSASLMechs, m.mechanisms)
+}
+
+type saslInit struct {
+	mechanism       string
+	initialResponse []byte
+}
+
+func decodeSASLInit(v interface{}) (saslInit, error) {
+	f, err := toFields(v)
+	if err != nil {
+		return saslInit{}, err
+	}
+
+	var si saslInit
+	if si.mechanism, err = f.string(0); err != nil {
+		return saslInit{}, err
+	}
+	if si.initialResponse, err = f.binary(1); err != nil {
+		return saslInit{}, err
+	}
+	return si, nil
+}
+
+func (si saslInit) described() described {
+	return list(descSASLInit, symbol(si.mechanism), si.initialResponse)
+}
+
+// SASL outcome codes (AMQP 1.0 section 5.3.3.6).
+const (
+	saslOK   uint8 = 0
+	saslAuth uint8 = 1
+	saslSys  uint8 = 2
+)
+
+type saslOutcome struct {
+	code uint8
+}
+
+func (o saslOutcome) described() described {
+	return list(descSASLOutcome, o.code)
+}
+
+type open struct {
+	containerID  string
+	maxFrameSize uint32
+	channelMax   uint16
+	idleTimeout  uint32
+}
+
+func decodeOpen(v interface{}) (open, error) {
+	f, err := toFields(v)
+	if err != nil {
+		return open{}, err
+	}
+
+	var o open
+	if o.containerID, err = f.string(0); err != nil {
+		return open{}, err
+	}
+	if o.maxFrameSize, err = f.uint32(2, 0xffffffff); err != nil {
+		return open{}, err
+	}
+	cm, err := f.uint(3, 0xffff)
+	if err != nil || cm > 0xffff {
+		return open{}, errMalformedFrame
+	}
+	o.channelMax = uint16(cm)
+	if o.idleTimeout, err = f.uint32(4, 0); err != nil {
+		return open{}, err
+	}
+	return o, nil
+}
+
+func (o open) described() described {
+	return list(descOpen, o.containerID, nil, o.maxFrameSize, o.channelMax, optional(o.idleTimeout, o.idleTimeout > 0))
+}
+
+type begin struct {
+	remoteChannel  *uint16
+	nextOutgoingID uint32
+	incomingWindow uint32
+	outgoingWindow uint32
+	handleMax      uint32
+}
+
+func decodeBegin(v interface{}) (begin, error) {
+	f, err := toFields(v)
+	if err != nil {
+		return begin{}, err
+	}
+
+	var b begin
+	if b.nextOutgoingID, err = f.uint32(1, 0); err != nil {
+		return begin{}, err
+	}
+	if b.incomingWindow, err = f.uint32(2, 0); err != nil {
+		return begin{}, err
+	}
+	if b.outgoingWindow, err = f.uint32(3, 0); err != nil {
+		return begin{}, err
+	}
+	if b.handleMax, err = f.uint32(4, 0xffffffff); err != nil {
+		return begin{}, err
+	}
+	return b, nil
+}
+
+func (b begin) described() described {
+	var rc interface{}
+	if b.remoteChannel != nil {
+		rc = *b.remoteChannel
+	}
+	return list(descBegin, rc, b.nextOutgoingID, b.incomingWindow, b.outgoingWindow, b.handleMax)
+}
+
+// Link roles and settlement modes (AMQP 1.0 section 2.8).
+const (
+	roleSender   = false
+	roleReceiver = true
+
+	sndSettled uint8 = 1
+	sndMixed   uint8 = 2
+)
+
+type attach struct {
+	name                 string
+	handle               uint32
+	role                 bool
+	sndSettleMode        uint8
+	source               *string
+	target               *string
+	initialDeliveryCount uint32
+	maxMessageSize       uint64
+}
+
+func decodeAttach(v interface{}) (attach, error) {
+	f, err := toFields(v)
+	if err != nil {
+		return attach{}, err
+	}
+
+	var a attach
+	if a.name, err = f.string(0); err != nil {
+		return attach{}, err
+	}
+	if a.handle, err = f.uint32(1, 0); err != nil {
+		return attach{}, err
+	}
+	if a.role, err = f.bool(2); err != nil {
+		return attach{}, err
+	}
+	mode, err := f.uint(3, uint64(sndMixed))
+	if err != nil {
+		return attach{}, err
+	}
+	a.sndSettleMode = uint8(mode)
+	if a.source, err = terminusAddress(f.get(5), descSource); err != nil {
+		return attach{}, err
+	}
+	if a.target, err = terminusAddress(f.get(6), descTarget); err != nil {
+		return attach{}, err
+	}
+	if a.initialDeliveryCount, err = f.uint32(9, 0); err != nil {
+		return attach{}, err
+	}
+	if a.maxMessageSize, err = f.uint(10, 0); err != nil {
+		return attach{}, err
+	}
+	return a, nil
+}
+
+// terminusAddress returns the address of the source or target, or nil if
+// the terminus is absent.
+func terminusAddress(v interface{}, desc uint64) (*string, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	d, ok := v.(described)
+	if !ok || d.descriptor != desc {
+		return nil, errMalformedFrame
+	}
+	f, err := toFields(d.value)
+	if err != nil {
+		return nil, err
+	}
+	addr, err := f.string(0)
+	if err != nil {
+		return nil, err
+	}
+	return &addr, nil
+}
+
+func terminus(desc uint64, addr *string) interface{} {
+	if addr == nil {
+		return nil
+	}
+	return list(desc, *addr)
+}
+
+func (a attach) described() described {
+	var idc interface{}
+	if a.role == roleSender {
+		idc = a.initialDeliveryCount
+	}
+	return list(descAttach, a.name, a.handle, a.role, a.sndSettleMode, nil,
+		terminus(descSource, a.source), terminus(descTarget, a.target), nil, nil, idc,
+		optional(a.maxMessageSize, a.maxMessageSize > 0))
+}
+
+type flow struct {
+	nextIncomingID *uint32
+	incomingWindow uint32
+	nextOutgoingID uint32
+	outgoingWindow uint32
+	handle         *uint32
+	deliveryCount  *uint32
+	linkCredit     *uint32
+	drain          bool
+	echo           bool
+}
+
+func decodeFlow(v interface{}) (flow, error) {
+	f, err := toFields(v)
+	if err != nil {
+		return flow{}, err
+	}
+
+	var fl flow
+	if fl.nextIncomingID, err = optionalUint32(f, 0); err != nil {
+		return flow{}, err
+	}
+	if fl.incomingWindow, err = f.uint32(1, 0); err != nil {
+		return flow{}, err
+	}
+	if fl.nextOutgoingID, err = f.uint32(2, 0); err != nil {
+		return flow{}, err
+	}
+	if fl.outgoingWindow, err = f.uint32(3, 0); err != nil {
+		return flow{}, err
+	}
+	if fl.handle, err = optionalUint32(f, 4); err != nil {
+		return flow{}, err
+	}
+	if fl.deliveryCount, err = optionalUint32(f, 5); err != nil {
+		return flow{}, err
+	}
+	if fl.linkCredit, err = optionalUint32(f, 6); err != nil {
+		return flow{}, err
+	}
+	if fl.drain, err = f.bool(8); err != nil {
+		return flow{}, err
+	}
+	if fl.echo, err = f.bool(9); err != nil {
+		return flow{}, err
+	}
+	return fl, nil
+}
+
+func optionalUint32(f fields, i int) (*uint32, error) {
+	if f.get(i) == nil {
+		return nil, nil
+	}
+	v, err := f.uint32(i, 0)
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+func uint32Value(v *uint32) interface{} {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
+
+func (fl flow) described() described {
+	return list(descFlow, uint32Value(fl.nextIncomingID), fl.incomingWindow, fl.nextOutgoingID,
+		fl.outgoingWindow, uint32Value(fl.handle), uint32Value(fl.deliveryCount),
+		uint32Value(fl.linkCredit), nil, optional(fl.drain, fl.drain), optional(fl.echo, fl.echo))
+}
+
+type transfer struct {
+	handle      uint32
+	deliveryID  *uint32
+	deliveryTag []byte
+	settled     bool
+	more        bool
+	aborted     bool
+}
+
+func decodeTransfer(v interface{}) (transfer, error) {
+	f, err := toFields(v)
+	if err != nil {
+		return transfer{}, err
+	}
+
+	var t transfer
+	if t.handle, err = f.uint32(0, 0); err != nil {
+		return transfer{}, err
+	}
+	if t.deliveryID, err = optionalUint32(f, 1); err != nil {
+		return transfer{}, err
+	}
+	if t.deliveryTag, err = f.binary(2); err != nil {
+		return transfer{}, err
+	}
+	if t.settled, err = f.bool(4); err != nil {
+		return transfer{}, err
+	}
+	if t.more, err = f.bool(5); err != nil {
+		return transfer{}, err
+	}
+	if t.aborted, err = f.bool(9); err != nil {
+		return transfer{}, err
+	}
+	return t, nil
+}
+
+func (t transfer) described() described {
+	return list(descTransfer, t.handle, uint32Value(t.deliveryID), t.deliveryTag, optional(uint32(0), t.deliveryID != nil),
+		optional(t.settled, t.settled), optional(t.more, t.more))
+}
+
+type disposition struct {
+	role    bool
+	first   uint32
+	last    uint32
+	settled bool
+	state   interface{}
+}
+
+func (d disposition) described() described {
+	return list(descDisposition, d.role, d.first, optional(d.last, d.last != d.first), d.settled, d.state)
+}
+
+func accepted() interface{} {
+	return list(descAccepted)
+}
+
+func rejected(e *amqpError) interface{} {
+	return list(descRejected, e.value())
+}
+
+type detach struct {
+	handle uint32
+	closed bool
+	err    *amqpError
+}
+
+func decodeDetach(v interface{}) (detach, error) {
+	f, err := toFields(v)
+	if err != nil {
+		return detach{}, err
+	}
+
+	var d detach
+	if d.handle, err = f.uint32(0, 0); err != nil {
+		return detach{}, err
+	}
+	if d.closed, err = f.bool(1); err != nil {
+		return detach{}, err
+	}
+	return d, nil
+}
+
+func (d detach) described() described {
+	return list(descDetach, d.handle, d.closed, d.err.value())
+}
+
+type end struct {
+	err *amqpError
+}
+
+func (e end) described() described {
+	return list(descEnd, e.err.value())
+}
+
+type closeFrame struct {
+	err *amqpError
+}
+
+func (c closeFrame) described() described {
+	return list(descClose, c.err.value())
+}
@@ -0,0 +1,133 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/mainflux/mainflux"
+)
+
+// contentTypeField is the index of the content type in the properties.
+const contentTypeField = 6
+
+var errMalformedMessage = errors.New("malformed message")
+
+// decodeMessage returns the message carrying the payload of the data or
+// value section of the AMQP message. The content type property is used as the
+// message content type, and the string application properties are kept as the
+// message metadata.
+func decodeMessage(data []byte) (mainflux.RawMessage, error) {
+	var msg mainflux.RawMessage
+
+	d := &decoder{buf: data}
+	for len(d.buf) > 0 {
+		v, err := d.value(0)
+		if err != nil {
+			return mainflux.RawMessage{}, err
+		}
+		section, ok := v.(described)
+		if !ok {
+			return mainflux.RawMessage{}, errMalformedMessage
+		}
+
+		switch section.descriptor {
+		case descProperties:
+			f, err := toFields(section.value)
+			if err != nil {
+				return mainflux.RawMessage{}, errMalformedMessage
+			}
+			if msg.ContentType, err = f.string(contentTypeField); err != nil {
+				return mainflux.RawMessage{}, errMalformedMessage
+			}
+		case descAppProperties:
+			props, ok := section.value.(amqpMap)
+			if !ok {
+				return mainflux.RawMessage{}, errMalformedMessage
+			}
+			msg.Metadata = metadata(props)
+		case descData:
+			b, ok := section.value.([]byte)
+			if !ok {
+				return mainflux.RawMessage{}, errMalformedMessage
+			}
+			// Multiple data sections make up the single payload.
+			msg.Payload = append(msg.Payload, b...)
+		case descAMQPValue:
+			switch val := section.value.(type) {
+			case []byte:
+				msg.Payload = val
+			case string:
+				msg.Payload = []byte(val)
+			default:
+				return mainflux.RawMessage{}, errMalformedMessage
+			}
+		case descHeader, descDeliveryAnnot, descMessageAnnot, descFooter:
+		default:
+			// Sequence sections don't map to the payload.
+			return mainflux.RawMessage{}, errMalformedMessage
+		}
+	}
+
+	return msg, nil
+}
+
+func metadata(props amqpMap) map[string]string {
+	md := make(map[string]string)
+	for _, p := range props {
+		key, ok := p.key.(string)
+		if !ok {
+			continue
+		}
+		switch val := p.value.(type) {
+		case string:
+			md[key] = val
+		case nil:
+		default:
+			md[key] = fmt.Sprint(val)
+		}
+	}
+
+	if len(md) == 0 {
+		return nil
+	}
+	return md
+}
+
+// encodeMessage returns the AMQP message carrying the message payload in the
+// data section, with the content type property and the application
+// properties holding the message metadata.
+func encodeMessage(msg mainflux.RawMessage) ([]byte, error) {
+	var buf bytes.Buffer
+
+	if msg.ContentType != "" {
+		props := make([]interface{}, contentTypeField+1)
+		props[contentTypeField] = symbol(msg.ContentType)
+		if err := encode(&buf, list(descProperties, props...)); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(msg.Metadata) > 0 {
+		props := make(amqpMap, 0, len(msg.Metadata))
+		for key, val := range msg.Metadata {
+			props = append(props, pair{key: key, value: val})
+		}
+		if err := encode(&buf, described{descriptor: descAppProperties, value: props}); err != nil {
+			return nil, err
+		}
+	}
+
+	payload := msg.Payload
+	if payload == nil {
+		payload = []byte{}
+	}
+	if err := encode(&buf, described{descriptor: descData, value: payload}); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,68 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// +build !test
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/amqp"
+)
+
+var _ amqp.Service = (*metricsMiddleware)(nil)
+
+type metricsMiddleware struct {
+	counter metrics.Counter
+	latency metrics.Histogram
+	svc     amqp.Service
+}
+
+// MetricsMiddleware instruments adapter by tracking request count and latency.
+func MetricsMiddleware(svc amqp.Service, counter metrics.Counter, latency metrics.Histogram) amqp.Service {
+	return &metricsMiddleware{
+		counter: counter,
+		latency: latency,
+		svc:     svc,
+	}
+}
+
+func (mm *metricsMiddleware) Publish(ctx context.Context, token string, msg mainflux.RawMessage) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "publish").Add(1)
+		mm.latency.With("method", "publish").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.Publish(ctx, token, msg)
+}
+
+func (mm *metricsMiddleware) Subscribe(chanID, subtopic string) (<-chan mainflux.RawMessage, func(), error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "subscribe").Add(1)
+		mm.latency.With("method", "subscribe").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.Subscribe(chanID, subtopic)
+}
+
+func (mm *metricsMiddleware) Identify(ctx context.Context, key string) (string, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "identify").Add(1)
+		mm.latency.With("method", "identify").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.Identify(ctx, key)
+}
+
+func (mm *metricsMiddleware) Authorize(ctx context.Context, key, chanID, action string) (string, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "authorize").Add(1)
+		mm.latency.With("method", "authorize").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.Authorize(ctx, key, chanID, action)
+}
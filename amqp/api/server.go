@@ -0,0 +1,953 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/amqp"
+	log "github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/payload"
+	"github.com/mainflux/mainflux/things"
+)
+
+const (
+	protocol    = "amqp"
+	containerID = "mainflux-amqp"
+	mechPlain   = "PLAIN"
+
+	// maxFrameSize is the largest frame accepted from the clients.
+	maxFrameSize = 64 * 1024
+	// maxMessageSize is the largest message accepted from the clients.
+	maxMessageSize = 1024 * 1024
+	// channelMax is the largest channel, limiting the number of sessions.
+	channelMax = 15
+	// handleMax is the largest link handle, limiting the number of links
+	// per session.
+	handleMax = 63
+	// sessionWindow is the number of the transfer frames the client can
+	// send before the adapter extends the session window.
+	sessionWindow = 2048
+	// linkCredit is the number of the messages the client can send before
+	// the adapter issues more credit to the link.
+	linkCredit = 256
+	// idleTimeout is the idle timeout of the connection, after which the
+	// connection is closed if no frame is received.
+	idleTimeout = time.Minute
+	// handshakeTimeout limits the duration of the connection handshake.
+	handshakeTimeout = 10 * time.Second
+	// writeTimeout limits the duration of the frame writes.
+	writeTimeout = 10 * time.Second
+	// authTimeout limits the duration of the things service calls.
+	authTimeout = time.Second
+)
+
+var (
+	errMalformedAddress  = errors.New("malformed address")
+	errMalformedSubtopic = errors.New("malformed subtopic")
+	errConnectionClosed  = errors.New("connection closed")
+
+	addressRegExp = regexp.MustCompile(`^/?channels/([\w\-]+)/messages(/[^?]*)?$`)
+)
+
+// Serve accepts the AMQP 1.0 connections on the listener and serves them
+// using the provided service. Clients authenticate with the SASL PLAIN
+// mechanism, passing the thing key as the password, and attach the links to
+// the addresses of the channel messages, i.e. /channels/<id>/messages with
+// the optional subtopic, where the links sending the messages publish them
+// and the links receiving the messages subscribe to them.
+func Serve(ln net.Listener, svc amqp.Service, logger log.Logger) error {
+	for {
+		nc, err := ln.Accept()
+		if err != nil {
+			if neterr, ok := err.(net.Error); ok && neterr.Temporary() {
+				time.Sleep(5 * time.Millisecond)
+				continue
+			}
+			return err
+		}
+
+		c := newConn(nc, svc, logger)
+		go c.serve()
+	}
+}
+
+type conn struct {
+	nc     net.Conn
+	r      *bufio.Reader
+	svc    amqp.Service
+	logger log.Logger
+
+	key     string
+	thingID string
+
+	// mu guards the state of the sessions and links, and the writes.
+	mu           sync.Mutex
+	cond         *sync.Cond
+	closed       bool
+	maxFrameSize uint32
+	channelMax   uint16
+	sessions     map[uint16]*session
+	channels     map[uint16]bool
+}
+
+type session struct {
+	local  uint16
+	remote uint16
+
+	nextIncomingID       uint32
+	incomingWindow       uint32
+	nextOutgoingID       uint32
+	remoteIncomingWindow uint32
+	nextDeliveryID       uint32
+
+	links map[uint32]*link
+}
+
+type link struct {
+	name     string
+	handle   uint32
+	role     bool
+	chanID   string
+	subtopic string
+
+	deliveryCount uint32
+	credit        uint32
+	drain         bool
+	// Refused link is detached by the adapter, which waits for the client
+	// to detach it as well.
+	refused  bool
+	detached bool
+
+	// Deliveries sent in multiple transfers are buffered until the last
+	// transfer is received.
+	delivery *transfer
+	buffer   []byte
+
+	msgs   <-chan mainflux.RawMessage
+	cancel func()
+	done   chan struct{}
+}
+
+func newConn(nc net.Conn, svc amqp.Service, logger log.Logger) *conn {
+	c := &conn{
+		nc:           nc,
+		r:            bufio.NewReader(nc),
+		svc:          svc,
+		logger:       logger,
+		maxFrameSize: minMaxFrameSize,
+		sessions:     make(map[uint16]*session),
+		channels:     make(map[uint16]bool),
+	}
+	c.cond = sync.NewCond(&c.mu)
+	return c
+}
+
+func (c *conn) serve() {
+	defer c.close()
+
+	c.nc.SetDeadline(time.Now().Add(handshakeTimeout))
+	if err := c.authenticate(); err != nil {
+		c.logger.Warn(fmt.Sprintf("Failed to authenticate AMQP client %s: %s", c.nc.RemoteAddr(), err))
+		return
+	}
+
+	remoteIdle, err := c.open()
+	if err != nil {
+		c.logger.Warn(fmt.Sprintf("Failed to open AMQP connection with %s: %s", c.nc.RemoteAddr(), err))
+		return
+	}
+	c.nc.SetDeadline(time.Time{})
+
+	if remoteIdle > 0 {
+		go c.heartbeat(remoteIdle / 2)
+	}
+
+	c.logger.Debug(fmt.Sprintf("Opened AMQP connection of thing %s", c.thingID))
+	for {
+		c.nc.SetReadDeadline(time.Now().Add(2 * idleTimeout))
+		f, err := readFrame(c.r, maxFrameSize)
+		if err != nil {
+			if err != io.EOF && !c.isClosed() {
+				c.fail(&amqpError{condition: condFramingError, description: err.Error()})
+			}
+			return
+		}
+
+		if f.empty {
+			continue
+		}
+
+		if err := c.handle(f); err != nil {
+			if err == errConnectionClosed {
+				return
+			}
+			c.fail(toAMQPError(err))
+			return
+		}
+	}
+}
+
+func (c *conn) isClosed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closed
+}
+
+// close releases the links and closes the connection.
+func (c *conn) close() {
+	c.mu.Lock()
+	c.closed = true
+	for _, s := range c.sessions {
+		c.endLinks(s)
+	}
+	c.cond.Broadcast()
+	c.mu.Unlock()
+
+	c.nc.Close()
+	c.logger.Debug(fmt.Sprintf("Closed AMQP connection of thing %s", c.thingID))
+}
+
+// fail closes the connection with the error.
+func (c *conn) fail(e *amqpError) {
+	c.logger.Warn(fmt.Sprintf("Closing AMQP connection of thing %s: %s", c.thingID, e))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.write(frameAMQP, 0, closeFrame{err: e}, nil)
+}
+
+// write sends the frame, and must be called with the mutex held.
+func (c *conn) write(typ byte, channel uint16, body performative, payload []byte) error {
+	b, err := encodeFrame(typ, channel, body, payload)
+	if err != nil {
+		return err
+	}
+
+	c.nc.SetWriteDeadline(time.Now().Add(writeTimeout))
+	_, err = c.nc.Write(b)
+	return err
+}
+
+func (c *conn) heartbeat(period time.Duration) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		c.mu.Lock()
+		if c.closed {
+			c.mu.Unlock()
+			return
+		}
+		err := c.write(frameAMQP, 0, nil, nil)
+		c.mu.Unlock()
+		if err != nil {
+			return
+		}
+	}
+}
+
+// authenticate performs the SASL exchange, which identifies the thing by
+// the key sent as the PLAIN password.
+func (c *conn) authenticate() error {
+	hdr := make([]byte, len(saslHeader))
+	if _, err := io.ReadFull(c.r, hdr); err != nil {
+		return err
+	}
+
+	// Clients not using SASL are told the supported protocol header.
+	if _, err := c.nc.Write(saslHeader); err != nil {
+		return err
+	}
+	if !bytes.Equal(hdr, saslHeader) {
+		return errUnexpectedFrame
+	}
+
+	c.mu.Lock()
+	err := c.write(frameSASL, 0, saslMechanisms{mechanisms: mechPlain}, nil)
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	f, err := readFrame(c.r, minMaxFrameSize)
+	if err != nil {
+		return err
+	}
+	if f.typ != frameSASL || f.body.descriptor != descSASLInit {
+		return errUnexpectedFrame
+	}
+	si, err := decodeSASLInit(f.body.value)
+	if err != nil {
+		return err
+	}
+
+	code := saslOK
+	key, err := plainKey(si)
+	if err == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), authTimeout)
+		c.thingID, err = c.svc.Identify(ctx, key)
+		cancel()
+	}
+	switch err {
+	case nil:
+		c.key = key
+	case things.ErrUnauthorizedAccess:
+		code = saslAuth
+	default:
+		code = saslSys
+	}
+
+	c.mu.Lock()
+	werr := c.write(frameSASL, 0, saslOutcome{code: code}, nil)
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if werr != nil {
+		return werr
+	}
+
+	if _, err := io.ReadFull(c.r, hdr); err != nil {
+		return err
+	}
+	if _, err := c.nc.Write(amqpHeader); err != nil {
+		return err
+	}
+	if !bytes.Equal(hdr, amqpHeader) {
+		return errUnexpectedFrame
+	}
+
+	return nil
+}
+
+// plainKey returns the key sent using the PLAIN mechanism (RFC 4616). The
+// key is the password, or the user name if the password is empty.
+func plainKey(si saslInit) (string, error) {
+	if si.mechanism != mechPlain {
+		return "", things.ErrUnauthorizedAccess
+	}
+
+	parts := strings.Split(string(si.initialResponse), "\x00")
+	if len(parts) != 3 {
+		return "", things.ErrUnauthorizedAccess
+	}
+	if parts[2] != "" {
+		return parts[2], nil
+	}
+	return parts[1], nil
+}
+
+// open exchanges the open frames, and returns the idle timeout of the client.
+func (c *conn) open() (time.Duration, error) {
+	f, err := readFrame(c.r, minMaxFrameSize)
+	if err != nil {
+		return 0, err
+	}
+	if f.typ != frameAMQP || f.body.descriptor != descOpen {
+		return 0, errUnexpectedFrame
+	}
+	o, err := decodeOpen(f.body.value)
+	if err != nil {
+		return 0, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.maxFrameSize = maxFrameSize
+	if o.maxFrameSize < c.maxFrameSize {
+		c.maxFrameSize = o.maxFrameSize
+	}
+	if c.maxFrameSize < minMaxFrameSize {
+		c.maxFrameSize = minMaxFrameSize
+	}
+	c.channelMax = channelMax
+	if o.channelMax < c.channelMax {
+		c.channelMax = o.channelMax
+	}
+
+	reply := open{
+		containerID:  containerID,
+		maxFrameSize: maxFrameSize,
+		channelMax:   c.channelMax,
+		idleTimeout:  uint32(idleTimeout / time.Millisecond),
+	}
+	if err := c.write(frameAMQP, 0, reply, nil); err != nil {
+		return 0, err
+	}
+
+	return time.Duration(o.idleTimeout) * time.Millisecond, nil
+}
+
+func toAMQPError(err error) *amqpError {
+	switch err {
+	case errMalformedFrame, errMalformedValue, errMalformedMessage:
+		return &amqpError{condition: condDecodeError, description: err.Error()}
+	case errUnexpectedFrame:
+		return &amqpError{condition: condNotAllowed, description: err.Error()}
+	}
+	if e, ok := err.(*amqpError); ok {
+		return e
+	}
+	return &amqpError{condition: condInternalError, description: err.Error()}
+}
+
+// handle handles the frame received on the open connection.
+func (c *conn) handle(f frame) error {
+	if f.typ != frameAMQP {
+		return errUnexpectedFrame
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if f.body.descriptor == descBegin {
+		return c.begin(f)
+	}
+	if f.body.descriptor == descClose {
+		c.write(frameAMQP, 0, closeFrame{}, nil)
+		return errConnectionClosed
+	}
+
+	s, ok := c.sessions[f.channel]
+	if !ok {
+		return &amqpError{condition: condNotFound, description: fmt.Sprintf("session on channel %d not found", f.channel)}
+	}
+
+	switch f.body.descriptor {
+	case descAttach:
+		return c.attach(s, f)
+	case descFlow:
+		return c.flow(s, f)
+	case descTransfer:
+		return c.transfer(s, f)
+	case descDisposition:
+		// Messages are sent settled, and the messages received are
+		// settled by the adapter, so the dispositions are ignored.
+		return nil
+	case descDetach:
+		return c.detach(s, f)
+	case descEnd:
+		c.endLinks(s)
+		delete(c.sessions, s.remote)
+		delete(c.channels, s.local)
+		return c.write(frameAMQP, s.local, end{}, nil)
+	default:
+		return errUnexpectedFrame
+	}
+}
+
+func (c *conn) begin(f frame) error {
+	if _, ok := c.sessions[f.channel]; ok {
+		return &amqpError{condition: condNotAllowed, description: "session already begun"}
+	}
+
+	b, err := decodeBegin(f.body.value)
+	if err != nil {
+		return err
+	}
+
+	local := uint16(0)
+	for c.channels[local] {
+		if local == c.channelMax {
+			return &amqpError{condition: condFramingError, description: "channel max exceeded"}
+		}
+		local++
+	}
+
+	s := &session{
+		local:                local,
+		remote:               f.channel,
+		nextIncomingID:       b.nextOutgoingID,
+		incomingWindow:       sessionWindow,
+		remoteIncomingWindow: b.incomingWindow,
+		links:                make(map[uint32]*link),
+	}
+	c.sessions[f.channel] = s
+	c.channels[local] = true
+
+	remote := f.channel
+	reply := begin{
+		remoteChannel:  &remote,
+		nextOutgoingID: s.nextOutgoingID,
+		incomingWindow: s.incomingWindow,
+		outgoingWindow: sessionWindow,
+		handleMax:      handleMax,
+	}
+	return c.write(frameAMQP, s.local, reply, nil)
+}
+
+// endLinks cancels the subscriptions of the session links.
+func (c *conn) endLinks(s *session) {
+	for _, l := range s.links {
+		c.release(l)
+	}
+	s.links = make(map[uint32]*link)
+}
+
+func (c *conn) release(l *link) {
+	if l.detached {
+		return
+	}
+
+	l.detached = true
+	if l.cancel != nil {
+		l.cancel()
+		close(l.done)
+	}
+	c.cond.Broadcast()
+}
+
+func (c *conn) attach(s *session, f frame) error {
+	a, err := decodeAttach(f.body.value)
+	if err != nil {
+		return err
+	}
+	if a.handle > handleMax {
+		return &amqpError{condition: condFramingError, description: "handle max exceeded"}
+	}
+	if _, ok := s.links[a.handle]; ok {
+		return &amqpError{condition: condNotAllowed, description: "handle in use"}
+	}
+
+	l := &link{
+		name:   a.name,
+		handle: a.handle,
+		role:   !a.role,
+	}
+
+	// Adapter receives the messages on the links whose client role is
+	// sender, and sends them on the links whose client role is receiver.
+	addr, action := a.target, things.Publish
+	if l.role == roleSender {
+		addr, action = a.source, things.Subscribe
+	}
+
+	reply := attach{
+		name:   a.name,
+		handle: a.handle,
+		role:   l.role,
+	}
+
+	var e *amqpError
+	l.chanID, l.subtopic, err = parseAddress(addr)
+	if err == nil && action == things.Publish && wildcard(l.subtopic) {
+		err = errMalformedSubtopic
+	}
+	switch err {
+	case nil:
+		e = c.authorize(l.chanID, action)
+	default:
+		e = &amqpError{condition: condInvalidField, description: err.Error()}
+	}
+
+	if e == nil && l.role == roleSender {
+		msgs, cancel, err := c.svc.Subscribe(l.chanID, l.subtopic)
+		if err != nil {
+			e = &amqpError{condition: condInternalError, description: err.Error()}
+		} else {
+			l.msgs, l.cancel, l.done = msgs, cancel, make(chan struct{})
+			go c.deliver(s, l)
+		}
+	}
+
+	// Refused links are attached without the terminus and detached with
+	// the error (AMQP 1.0 section 2.6.3).
+	if e == nil {
+		reply.source, reply.target = a.source, a.target
+	}
+	if l.role == roleSender {
+		reply.sndSettleMode = sndSettled
+	} else {
+		reply.sndSettleMode = a.sndSettleMode
+		reply.maxMessageSize = maxMessageSize
+		l.deliveryCount = a.initialDeliveryCount
+	}
+
+	if err := c.write(frameAMQP, s.local, reply, nil); err != nil {
+		c.release(l)
+		return err
+	}
+
+	s.links[a.handle] = l
+	if e != nil {
+		c.logger.Warn(fmt.Sprintf("Refused AMQP link %s of thing %s: %s", a.name, c.thingID, e))
+		l.refused = true
+		c.release(l)
+		return c.write(frameAMQP, s.local, detach{handle: a.handle, closed: true, err: e}, nil)
+	}
+
+	if l.role == roleReceiver {
+		l.credit = linkCredit
+		return c.writeFlow(s, l)
+	}
+
+	return nil
+}
+
+// authorize checks whether the thing is allowed the action on the channel.
+func (c *conn) authorize(chanID, action string) *amqpError {
+	ctx, cancel := context.WithTimeout(context.Background(), authTimeout)
+	defer cancel()
+
+	if _, err := c.svc.Authorize(ctx, c.key, chanID, action); err != nil {
+		if err == things.ErrUnauthorizedAccess {
+			return &amqpError{condition: condUnauthorized, description: fmt.Sprintf("thing not allowed to %s to channel", action)}
+		}
+		return &amqpError{condition: condInternalError, description: err.Error()}
+	}
+
+	return nil
+}
+
+// parseAddress returns the channel and the subtopic of the address.
+func parseAddress(addr *string) (string, string, error) {
+	if addr == nil {
+		return "", "", errMalformedAddress
+	}
+
+	parts := addressRegExp.FindStringSubmatch(*addr)
+	if len(parts) < 2 {
+		return "", "", errMalformedAddress
+	}
+
+	subtopic, err := parseSubtopic(parts[2])
+	if err != nil {
+		return "", "", err
+	}
+
+	return parts[1], subtopic, nil
+}
+
+// wildcard reports whether the subtopic contains a wildcard element, which
+// can't be published to.
+func wildcard(subtopic string) bool {
+	for _, elem := range strings.Split(subtopic, ".") {
+		if elem == "*" || elem == ">" {
+			return true
+		}
+	}
+	return false
+}
+
+func parseSubtopic(subtopic string) (string, error) {
+	if subtopic == "" {
+		return subtopic, nil
+	}
+
+	subtopic = strings.Replace(subtopic, "/", ".", -1)
+
+	elems := strings.Split(subtopic, ".")
+	filteredElems := []string{}
+	for _, elem := range elems {
+		if elem == "" {
+			continue
+		}
+
+		if len(elem) > 1 && (strings.Contains(elem, "*") || strings.Contains(elem, ">")) {
+			return "", errMalformedSubtopic
+		}
+
+		filteredElems = append(filteredElems, elem)
+	}
+
+	return strings.Join(filteredElems, "."), nil
+}
+
+func (c *conn) detach(s *session, f frame) error {
+	d, err := decodeDetach(f.body.value)
+	if err != nil {
+		return err
+	}
+
+	l, ok := s.links[d.handle]
+	if !ok {
+		return &amqpError{condition: condNotFound, description: fmt.Sprintf("link with handle %d not found", d.handle)}
+	}
+
+	c.release(l)
+	delete(s.links, d.handle)
+	if l.refused {
+		return nil
+	}
+	return c.write(frameAMQP, s.local, detach{handle: d.handle, closed: d.closed}, nil)
+}
+
+// writeFlow sends the flow frame carrying the session state, and the state
+// of the link unless it's nil.
+func (c *conn) writeFlow(s *session, l *link) error {
+	nextIncomingID := s.nextIncomingID
+	fl := flow{
+		nextIncomingID: &nextIncomingID,
+		incomingWindow: s.incomingWindow,
+		nextOutgoingID: s.nextOutgoingID,
+		outgoingWindow: sessionWindow,
+	}
+
+	if l != nil {
+		handle, deliveryCount, credit := l.handle, l.deliveryCount, l.credit
+		fl.handle = &handle
+		fl.deliveryCount = &deliveryCount
+		fl.linkCredit = &credit
+		fl.drain = l.drain
+	}
+
+	return c.write(frameAMQP, s.local, fl, nil)
+}
+
+func (c *conn) flow(s *session, f frame) error {
+	fl, err := decodeFlow(f.body.value)
+	if err != nil {
+		return err
+	}
+
+	// Session starts sending the transfers with the ID 0.
+	nextIncomingID := uint32(0)
+	if fl.nextIncomingID != nil {
+		nextIncomingID = *fl.nextIncomingID
+	}
+	s.remoteIncomingWindow = nextIncomingID + fl.incomingWindow - s.nextOutgoingID
+
+	if fl.handle == nil {
+		c.cond.Broadcast()
+		if fl.echo {
+			return c.writeFlow(s, nil)
+		}
+		return nil
+	}
+
+	l, ok := s.links[*fl.handle]
+	if !ok {
+		return &amqpError{condition: condNotFound, description: fmt.Sprintf("link with handle %d not found", *fl.handle)}
+	}
+
+	if l.role == roleSender && fl.linkCredit != nil {
+		deliveryCount := uint32(0)
+		if fl.deliveryCount != nil {
+			deliveryCount = *fl.deliveryCount
+		}
+		l.credit = deliveryCount + *fl.linkCredit - l.deliveryCount
+		l.drain = fl.drain
+		c.cond.Broadcast()
+
+		if l.drain {
+			return c.drainLink(s, l)
+		}
+	}
+
+	if fl.echo {
+		return c.writeFlow(s, l)
+	}
+	return nil
+}
+
+func (c *conn) transfer(s *session, f frame) error {
+	t, err := decodeTransfer(f.body.value)
+	if err != nil {
+		return err
+	}
+
+	s.nextIncomingID++
+	if s.incomingWindow > 0 {
+		s.incomingWindow--
+	}
+	if s.incomingWindow < sessionWindow/2 {
+		s.incomingWindow = sessionWindow
+		if err := c.writeFlow(s, nil); err != nil {
+			return err
+		}
+	}
+
+	l, ok := s.links[t.handle]
+	if !ok || l.role != roleReceiver {
+		return &amqpError{condition: condNotFound, description: fmt.Sprintf("receiving link with handle %d not found", t.handle)}
+	}
+	// Transfers sent before the refusal was received are dropped.
+	if l.refused {
+		return nil
+	}
+
+	if l.delivery == nil {
+		if t.deliveryID == nil {
+			return errMalformedFrame
+		}
+		l.delivery = &t
+		l.buffer = nil
+	}
+
+	if t.aborted {
+		l.delivery = nil
+		l.buffer = nil
+		return nil
+	}
+
+	if len(l.buffer)+len(f.payload) > maxMessageSize {
+		return &amqpError{condition: condMessageTooLarge, description: "message size exceeded"}
+	}
+	l.buffer = append(l.buffer, f.payload...)
+	if t.more {
+		return nil
+	}
+
+	delivery, payload := l.delivery, l.buffer
+	l.delivery, l.buffer = nil, nil
+	l.deliveryCount++
+	if l.credit > 0 {
+		l.credit--
+	}
+
+	state := c.publish(l, payload)
+	if !delivery.settled && !t.settled {
+		d := disposition{
+			role:    roleReceiver,
+			first:   *delivery.deliveryID,
+			last:    *delivery.deliveryID,
+			settled: true,
+			state:   state,
+		}
+		if err := c.write(frameAMQP, s.local, d, nil); err != nil {
+			return err
+		}
+	}
+
+	if l.credit < linkCredit/2 {
+		l.credit = linkCredit
+		return c.writeFlow(s, l)
+	}
+	return nil
+}
+
+// publish publishes the message received on the link, and returns the
+// delivery state of the message.
+func (c *conn) publish(l *link, data []byte) interface{} {
+	msg, err := decodeMessage(data)
+	if err != nil {
+		return rejected(&amqpError{condition: condDecodeError, description: err.Error()})
+	}
+
+	msg.Channel = l.chanID
+	msg.Subtopic = l.subtopic
+	msg.Publisher = c.thingID
+	msg.Protocol = protocol
+
+	if err := c.svc.Publish(context.Background(), c.key, msg); err != nil {
+		if err == payload.ErrTooLarge {
+			return rejected(&amqpError{condition: condMessageTooLarge, description: err.Error()})
+		}
+		return rejected(&amqpError{condition: condInternalError, description: err.Error()})
+	}
+
+	return accepted()
+}
+
+// deliver sends the messages of the subscription to the link, as long as the
+// link has the credit, until the link is detached.
+func (c *conn) deliver(s *session, l *link) {
+	for {
+		var msg mainflux.RawMessage
+		select {
+		case msg = <-l.msgs:
+		case <-l.done:
+			return
+		}
+
+		payload, err := encodeMessage(msg)
+		if err != nil {
+			c.logger.Warn(fmt.Sprintf("Failed to encode message for thing %s: %s", c.thingID, err))
+			continue
+		}
+
+		c.mu.Lock()
+		for !l.detached && !c.closed && (l.credit == 0 || s.remoteIncomingWindow == 0) {
+			c.cond.Wait()
+		}
+		if l.detached || c.closed {
+			c.mu.Unlock()
+			return
+		}
+
+		err = c.send(s, l, payload)
+		if err == nil && l.drain {
+			err = c.drainLink(s, l)
+		}
+		c.mu.Unlock()
+		if err != nil {
+			c.logger.Warn(fmt.Sprintf("Failed to send message to thing %s: %s", c.thingID, err))
+			return
+		}
+	}
+}
+
+// drainLink uses up the credit of the drained link once there are no more
+// messages to send, and must be called with the mutex held.
+func (c *conn) drainLink(s *session, l *link) error {
+	if l.credit == 0 || len(l.msgs) > 0 {
+		return nil
+	}
+
+	l.deliveryCount += l.credit
+	l.credit = 0
+	return c.writeFlow(s, l)
+}
+
+// send sends the settled delivery, splitting it into the transfers fitting
+// the frame size, and must be called with the mutex held.
+func (c *conn) send(s *session, l *link, payload []byte) error {
+	deliveryID := s.nextDeliveryID
+	tag := make([]byte, 4)
+	tag[0], tag[1], tag[2], tag[3] = byte(deliveryID>>24), byte(deliveryID>>16), byte(deliveryID>>8), byte(deliveryID)
+
+	t := transfer{
+		handle:      l.handle,
+		deliveryID:  &deliveryID,
+		deliveryTag: tag,
+		settled:     true,
+	}
+
+	for {
+		hdr, err := encodeFrame(frameAMQP, s.local, t, nil)
+		if err != nil {
+			return err
+		}
+		// The header size grows by the more flag at most.
+		room := int(c.maxFrameSize) - len(hdr) - 1
+		t.more = len(payload) > room
+		chunk := payload
+		if t.more {
+			chunk = payload[:room]
+		}
+
+		if err := c.write(frameAMQP, s.local, t, chunk); err != nil {
+			return err
+		}
+		s.nextOutgoingID++
+		if s.remoteIncomingWindow > 0 {
+			s.remoteIncomingWindow--
+		}
+
+		payload = payload[len(chunk):]
+		if !t.more {
+			break
+		}
+		// Continuation transfers carry the handle only.
+		t = transfer{handle: l.handle, settled: true}
+	}
+
+	s.nextDeliveryID++
+	l.deliveryCount++
+	l.credit--
+	return nil
+}
@@ -0,0 +1,375 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/amqp"
+	"github.com/mainflux/mainflux/amqp/mocks"
+	log "github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/payload"
+	paymocks "github.com/mainflux/mainflux/payload/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	thingKey   = "thing-key"
+	thingID    = "1"
+	chanID     = "1"
+	wrongID    = "2"
+	maxPayload = 64
+)
+
+// limitedPubSub enforces the maximal payload size of the published messages,
+// while the subscriptions are served unchanged.
+type limitedPubSub struct {
+	amqp.PubSub
+	pub mainflux.MessagePublisher
+}
+
+func (ps limitedPubSub) Publish(ctx context.Context, token string, msg mainflux.RawMessage) error {
+	return ps.pub.Publish(ctx, token, msg)
+}
+
+func newServer(t *testing.T) (amqp.Service, string) {
+	logger, err := log.New(ioutil.Discard, "info")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	pubsub := mocks.NewPubSub()
+	pub, err := payload.New(pubsub, payload.Config{MaxSize: maxPayload, Policy: payload.Reject}, nil, paymocks.NewCounter(), logger)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	things := mocks.NewThingsClient(map[string]string{thingKey: thingID}, map[string][]string{thingID: {chanID}})
+	svc := amqp.New(limitedPubSub{PubSub: pubsub, pub: pub}, things)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	go Serve(ln, svc, logger)
+
+	return svc, ln.Addr().String()
+}
+
+type client struct {
+	t  *testing.T
+	nc net.Conn
+	r  *bufio.Reader
+}
+
+func dial(t *testing.T, addr string) *client {
+	nc, err := net.Dial("tcp", addr)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	nc.SetDeadline(time.Now().Add(5 * time.Second))
+	return &client{t: t, nc: nc, r: bufio.NewReader(nc)}
+}
+
+func (c *client) header(hdr []byte) []byte {
+	_, err := c.nc.Write(hdr)
+	require.Nil(c.t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	reply := make([]byte, len(hdr))
+	_, err = io.ReadFull(c.r, reply)
+	require.Nil(c.t, err, fmt.Sprintf("unexpected error: %s", err))
+	return reply
+}
+
+func (c *client) write(typ byte, body performative, payload []byte) {
+	b, err := encodeFrame(typ, 0, body, payload)
+	require.Nil(c.t, err, fmt.Sprintf("unexpected error: %s", err))
+	_, err = c.nc.Write(b)
+	require.Nil(c.t, err, fmt.Sprintf("unexpected error: %s", err))
+}
+
+func (c *client) read() frame {
+	for {
+		f, err := readFrame(c.r, maxFrameSize)
+		require.Nil(c.t, err, fmt.Sprintf("unexpected error: %s", err))
+		if !f.empty {
+			return f
+		}
+	}
+}
+
+// authenticate performs the SASL exchange, and returns the outcome code.
+func (c *client) authenticate(key string) uint8 {
+	assert.Equal(c.t, saslHeader, c.header(saslHeader), "expected SASL protocol header")
+	assert.Equal(c.t, descSASLMechs, c.read().body.descriptor, "expected SASL mechanisms")
+
+	c.write(frameSASL, saslInit{mechanism: mechPlain, initialResponse: []byte("\x00" + thingID + "\x00" + key)}, nil)
+	f := c.read()
+	require.Equal(c.t, descSASLOutcome, f.body.descriptor, "expected SASL outcome")
+	fs, _ := toFields(f.body.value)
+	code, _ := fs.uint(0, 0)
+	return uint8(code)
+}
+
+// connect opens the connection and begins the session.
+func (c *client) connect(key string) {
+	require.Equal(c.t, saslOK, c.authenticate(key), "expected successful authentication")
+	assert.Equal(c.t, amqpHeader, c.header(amqpHeader), "expected AMQP protocol header")
+
+	c.write(frameAMQP, open{containerID: "client", maxFrameSize: maxFrameSize, channelMax: 1}, nil)
+	require.Equal(c.t, descOpen, c.read().body.descriptor, "expected open")
+
+	c.write(frameAMQP, begin{incomingWindow: sessionWindow, outgoingWindow: sessionWindow, handleMax: handleMax}, nil)
+	require.Equal(c.t, descBegin, c.read().body.descriptor, "expected begin")
+}
+
+// attach attaches the link, and returns the error the link is refused with.
+func (c *client) attach(handle uint32, role bool, addr string) *amqpError {
+	a := attach{name: fmt.Sprintf("link-%d", handle), handle: handle, role: role}
+	if role == roleSender {
+		a.target = &addr
+	} else {
+		a.source = &addr
+	}
+	c.write(frameAMQP, a, nil)
+
+	f := c.read()
+	require.Equal(c.t, descAttach, f.body.descriptor, "expected attach")
+	reply, err := decodeAttach(f.body.value)
+	require.Nil(c.t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	if reply.source != nil || reply.target != nil {
+		return nil
+	}
+
+	f = c.read()
+	require.Equal(c.t, descDetach, f.body.descriptor, "expected detach")
+	fs, _ := toFields(f.body.value)
+	e, ok := fs.get(2).(described)
+	require.True(c.t, ok, "expected detach error")
+	efs, _ := toFields(e.value)
+	cond, _ := efs.string(0)
+	return &amqpError{condition: symbol(cond)}
+}
+
+func condition(e *amqpError) symbol {
+	if e == nil {
+		return ""
+	}
+	return e.condition
+}
+
+func TestAuthenticate(t *testing.T) {
+	_, addr := newServer(t)
+
+	cases := []struct {
+		desc string
+		key  string
+		code uint8
+	}{
+		{
+			desc: "authenticate with valid key",
+			key:  thingKey,
+			code: saslOK,
+		},
+		{
+			desc: "authenticate with invalid key",
+			key:  "invalid",
+			code: saslAuth,
+		},
+		{
+			desc: "authenticate with empty key",
+			key:  "",
+			code: saslAuth,
+		},
+		{
+			desc: "authenticate with unavailable things service",
+			key:  mocks.ServiceErrToken,
+			code: saslSys,
+		},
+	}
+
+	for _, tc := range cases {
+		c := dial(t, addr)
+		code := c.authenticate(tc.key)
+		assert.Equal(t, tc.code, code, fmt.Sprintf("%s: expected outcome %d got %d", tc.desc, tc.code, code))
+		c.nc.Close()
+	}
+}
+
+func TestPublish(t *testing.T) {
+	svc, addr := newServer(t)
+	msgs, cancel, err := svc.Subscribe(chanID, "sensors.temperature")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	defer cancel()
+
+	c := dial(t, addr)
+	defer c.nc.Close()
+	c.connect(thingKey)
+
+	payload, err := encodeMessage(mainflux.RawMessage{
+		ContentType: mainflux.SenMLJSON,
+		Payload:     []byte(`[{"n":"temperature","v":21}]`),
+		Metadata:    map[string]string{"gateway": "gw-1"},
+	})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	oversized, err := encodeMessage(mainflux.RawMessage{
+		ContentType: mainflux.SenMLJSON,
+		Payload:     bytes.Repeat([]byte("a"), maxPayload+1),
+	})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc    string
+		addr    string
+		payload []byte
+		cond    symbol
+		state   uint64
+		errCond symbol
+	}{
+		{
+			desc:    "publish to connected channel",
+			addr:    fmt.Sprintf("/channels/%s/messages/sensors/temperature", chanID),
+			payload: payload,
+			state:   descAccepted,
+		},
+		{
+			desc:    "publish malformed message to connected channel",
+			addr:    fmt.Sprintf("/channels/%s/messages/sensors/temperature", chanID),
+			payload: []byte{0x00, 0x53},
+			state:   descRejected,
+			errCond: condDecodeError,
+		},
+		{
+			desc:    "publish oversized message to connected channel",
+			addr:    fmt.Sprintf("/channels/%s/messages/sensors/temperature", chanID),
+			payload: oversized,
+			state:   descRejected,
+			errCond: condMessageTooLarge,
+		},
+		{
+			desc: "publish to unconnected channel",
+			addr: fmt.Sprintf("/channels/%s/messages", wrongID),
+			cond: condUnauthorized,
+		},
+		{
+			desc: "publish to malformed address",
+			addr: "/channels",
+			cond: condInvalidField,
+		},
+		{
+			desc: "publish to malformed subtopic",
+			addr: fmt.Sprintf("/channels/%s/messages/sensors*", chanID),
+			cond: condInvalidField,
+		},
+		{
+			desc: "publish to wildcard subtopic",
+			addr: fmt.Sprintf("/channels/%s/messages/sensors/>", chanID),
+			cond: condInvalidField,
+		},
+	}
+
+	for i, tc := range cases {
+		handle := uint32(i)
+		cond := condition(c.attach(handle, roleSender, tc.addr))
+		assert.Equal(t, tc.cond, cond, fmt.Sprintf("%s: expected condition %s got %s", tc.desc, tc.cond, cond))
+		if tc.cond != "" {
+			c.write(frameAMQP, detach{handle: handle, closed: true}, nil)
+			continue
+		}
+
+		f := c.read()
+		assert.Equal(t, descFlow, f.body.descriptor, fmt.Sprintf("%s: expected flow", tc.desc))
+
+		deliveryID := handle
+		c.write(frameAMQP, transfer{handle: handle, deliveryID: &deliveryID, deliveryTag: []byte{byte(i)}}, tc.payload)
+		f = c.read()
+		require.Equal(t, descDisposition, f.body.descriptor, fmt.Sprintf("%s: expected disposition", tc.desc))
+		fs, _ := toFields(f.body.value)
+		state, _ := fs.get(4).(described)
+		assert.Equal(t, tc.state, state.descriptor, fmt.Sprintf("%s: expected state %d got %d", tc.desc, tc.state, state.descriptor))
+
+		if tc.state == descRejected {
+			sfs, _ := toFields(state.value)
+			e, _ := sfs.get(0).(described)
+			efs, _ := toFields(e.value)
+			cond, _ := efs.string(0)
+			assert.Equal(t, string(tc.errCond), cond, fmt.Sprintf("%s: expected condition %s got %s", tc.desc, tc.errCond, cond))
+		}
+
+		if tc.state == descAccepted {
+			msg := <-msgs
+			assert.Equal(t, chanID, msg.Channel, fmt.Sprintf("%s: expected channel %s got %s", tc.desc, chanID, msg.Channel))
+			assert.Equal(t, "sensors.temperature", msg.Subtopic, fmt.Sprintf("%s: expected subtopic sensors.temperature got %s", tc.desc, msg.Subtopic))
+			assert.Equal(t, thingID, msg.Publisher, fmt.Sprintf("%s: expected publisher %s got %s", tc.desc, thingID, msg.Publisher))
+			assert.Equal(t, protocol, msg.Protocol, fmt.Sprintf("%s: expected protocol %s got %s", tc.desc, protocol, msg.Protocol))
+			assert.Equal(t, mainflux.SenMLJSON, msg.ContentType, fmt.Sprintf("%s: expected content type %s got %s", tc.desc, mainflux.SenMLJSON, msg.ContentType))
+			assert.Equal(t, "gw-1", msg.Metadata["gateway"], fmt.Sprintf("%s: expected metadata gw-1 got %s", tc.desc, msg.Metadata["gateway"]))
+		}
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	svc, addr := newServer(t)
+
+	c := dial(t, addr)
+	defer c.nc.Close()
+	c.connect(thingKey)
+
+	cases := []struct {
+		desc     string
+		addr     string
+		subtopic string
+		cond     symbol
+	}{
+		{
+			desc: "subscribe to connected channel",
+			addr: fmt.Sprintf("/channels/%s/messages", chanID),
+		},
+		{
+			desc:     "subscribe to connected channel subtopic",
+			addr:     fmt.Sprintf("channels/%s/messages/commands", chanID),
+			subtopic: "commands",
+		},
+		{
+			desc: "subscribe to unconnected channel",
+			addr: fmt.Sprintf("/channels/%s/messages", wrongID),
+			cond: condUnauthorized,
+		},
+	}
+
+	for i, tc := range cases {
+		handle := uint32(i)
+		cond := condition(c.attach(handle, roleReceiver, tc.addr))
+		assert.Equal(t, tc.cond, cond, fmt.Sprintf("%s: expected condition %s got %s", tc.desc, tc.cond, cond))
+		if tc.cond != "" {
+			c.write(frameAMQP, detach{handle: handle, closed: true}, nil)
+			continue
+		}
+
+		deliveryCount, credit := uint32(0), uint32(1)
+		c.write(frameAMQP, flow{incomingWindow: sessionWindow, outgoingWindow: sessionWindow, handle: &handle, deliveryCount: &deliveryCount, linkCredit: &credit, echo: true}, nil)
+		f := c.read()
+		require.Equal(t, descFlow, f.body.descriptor, fmt.Sprintf("%s: expected flow", tc.desc))
+
+		sent := mainflux.RawMessage{
+			Channel:     chanID,
+			Subtopic:    tc.subtopic,
+			ContentType: mainflux.SenMLJSON,
+			Payload:     []byte(fmt.Sprintf(`[{"n":"command","vs":"%d"}]`, i)),
+		}
+		err := svc.Publish(context.Background(), "", sent)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+
+		f = c.read()
+		require.Equal(t, descTransfer, f.body.descriptor, fmt.Sprintf("%s: expected transfer", tc.desc))
+		msg, err := decodeMessage(f.payload)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		assert.Equal(t, sent.Payload, msg.Payload, fmt.Sprintf("%s: expected payload %s got %s", tc.desc, sent.Payload, msg.Payload))
+		assert.Equal(t, sent.ContentType, msg.ContentType, fmt.Sprintf("%s: expected content type %s got %s", tc.desc, sent.ContentType, msg.ContentType))
+	}
+}
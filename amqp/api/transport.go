@@ -0,0 +1,25 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/go-zoo/bone"
+	"github.com/mainflux/mainflux"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// MakeHTTPHandler returns http handler with version, discovery and metrics
+// endpoints of the adapter.
+func MakeHTTPHandler() http.Handler {
+	b := bone.New()
+	b.GetFunc("/version", mainflux.Version(protocol))
+	b.GetFunc(mainflux.DiscoveryPath, mainflux.Discovery(protocol, mainflux.Capabilities{
+		Limits: mainflux.Limits{MaxPayload: maxMessageSize},
+	}))
+	b.Handle("/metrics", promhttp.Handler())
+
+	return b
+}
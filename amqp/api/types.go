@@ -0,0 +1,511 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// maxDepth limits the nesting of the compound values, so that the malformed
+// frames can't exhaust the stack.
+const maxDepth = 32
+
+var errMalformedValue = errors.New("malformed AMQP value")
+
+// symbol is the AMQP symbolic value, e.g. the mechanism or error condition.
+type symbol string
+
+// described is the AMQP described value. The symbolic descriptors of the
+// known types are converted to their numeric codes.
+type described struct {
+	descriptor uint64
+	value      interface{}
+}
+
+// pair is the entry of the AMQP map, whose keys are compared as values.
+type pair struct {
+	key   interface{}
+	value interface{}
+}
+
+// amqpMap keeps the entries of the AMQP map in order, since the keys can be
+// of the types which aren't comparable in Go.
+type amqpMap []pair
+
+// Descriptor codes of the performatives, SASL frames, message sections and
+// delivery states used by the adapter (AMQP 1.0 sections 2.7, 3.2, 3.4, 4.5
+// and 5.3).
+const (
+	descError         uint64 = 0x1d
+	descAccepted      uint64 = 0x24
+	descRejected      uint64 = 0x25
+	descReleased      uint64 = 0x26
+	descSource        uint64 = 0x28
+	descTarget        uint64 = 0x29
+	descOpen          uint64 = 0x10
+	descBegin         uint64 = 0x11
+	descAttach        uint64 = 0x12
+	descFlow          uint64 = 0x13
+	descTransfer      uint64 = 0x14
+	descDisposition   uint64 = 0x15
+	descDetach        uint64 = 0x16
+	descEnd           uint64 = 0x17
+	descClose         uint64 = 0x18
+	descSASLMechs     uint64 = 0x40
+	descSASLInit      uint64 = 0x41
+	descSASLOutcome   uint64 = 0x44
+	descHeader        uint64 = 0x70
+	descDeliveryAnnot uint64 = 0x71
+	descMessageAnnot  uint64 = 0x72
+	descProperties    uint64 = 0x73
+	descAppProperties uint64 = 0x74
+	descData          uint64 = 0x75
+	descAMQPSequence  uint64 = 0x76
+	descAMQPValue     uint64 = 0x77
+	descFooter        uint64 = 0x78
+)
+
+var symbolicDescriptors = map[symbol]uint64{
+	"amqp:error:list":                 descError,
+	"amqp:accepted:list":              descAccepted,
+	"amqp:rejected:list":              descRejected,
+	"amqp:released:list":              descReleased,
+	"amqp:source:list":                descSource,
+	"amqp:target:list":                descTarget,
+	"amqp:open:list":                  descOpen,
+	"amqp:begin:list":                 descBegin,
+	"amqp:attach:list":                descAttach,
+	"amqp:flow:list":                  descFlow,
+	"amqp:transfer:list":              descTransfer,
+	"amqp:disposition:list":           descDisposition,
+	"amqp:detach:list":                descDetach,
+	"amqp:end:list":                   descEnd,
+	"amqp:close:list":                 descClose,
+	"amqp:sasl-mechanisms:list":       descSASLMechs,
+	"amqp:sasl-init:list":             descSASLInit,
+	"amqp:sasl-outcome:list":          descSASLOutcome,
+	"amqp:header:list":                descHeader,
+	"amqp:delivery-annotations:map":   descDeliveryAnnot,
+	"amqp:message-annotations:map":    descMessageAnnot,
+	"amqp:properties:list":            descProperties,
+	"amqp:application-properties:map": descAppProperties,
+	"amqp:data:binary":                descData,
+	"amqp:amqp-sequence:list":         descAMQPSequence,
+	"amqp:amqp-value:*":               descAMQPValue,
+	"amqp:footer:map":                 descFooter,
+}
+
+// decoder decodes the AMQP values of the type system (AMQP 1.0 section 1).
+type decoder struct {
+	buf []byte
+}
+
+func (d *decoder) next(n int) ([]byte, error) {
+	if n < 0 || len(d.buf) < n {
+		return nil, errMalformedValue
+	}
+
+	b := d.buf[:n]
+	d.buf = d.buf[n:]
+	return b, nil
+}
+
+func (d *decoder) byte() (byte, error) {
+	b, err := d.next(1)
+	if err != nil {
+		return 0, err
+	}
+	return b[0], nil
+}
+
+// size reads the size and count of the compound value, whose width is given
+// by the constructor.
+func (d *decoder) size(wide bool) (int, error) {
+	if !wide {
+		b, err := d.byte()
+		return int(b), err
+	}
+
+	b, err := d.next(4)
+	if err != nil {
+		return 0, err
+	}
+	n := binary.BigEndian.Uint32(b)
+	if n > math.MaxInt32 {
+		return 0, errMalformedValue
+	}
+	return int(n), nil
+}
+
+// value decodes the next value.
+func (d *decoder) value(depth int) (interface{}, error) {
+	if depth > maxDepth {
+		return nil, errMalformedValue
+	}
+
+	code, err := d.byte()
+	if err != nil {
+		return nil, err
+	}
+
+	if code != 0x00 {
+		return d.primitive(code, depth)
+	}
+
+	desc, err := d.value(depth + 1)
+	if err != nil {
+		return nil, err
+	}
+	val, err := d.value(depth + 1)
+	if err != nil {
+		return nil, err
+	}
+
+	return described{descriptor: descriptorCode(desc), value: val}, nil
+}
+
+func descriptorCode(desc interface{}) uint64 {
+	switch d := desc.(type) {
+	case uint64:
+		return d
+	case symbol:
+		return symbolicDescriptors[d]
+	default:
+		return 0
+	}
+}
+
+// primitive decodes the value of the type given by the constructor code.
+func (d *decoder) primitive(code byte, depth int) (interface{}, error) {
+	switch code {
+	case 0x40:
+		return nil, nil
+	case 0x41:
+		return true, nil
+	case 0x42:
+		return false, nil
+	case 0x56:
+		b, err := d.byte()
+		return b != 0, err
+	case 0x50:
+		return d.byte()
+	case 0x51:
+		b, err := d.byte()
+		return int8(b), err
+	case 0x60, 0x61:
+		b, err := d.next(2)
+		if err != nil {
+			return nil, err
+		}
+		if code == 0x61 {
+			return int16(binary.BigEndian.Uint16(b)), nil
+		}
+		return binary.BigEndian.Uint16(b), nil
+	case 0x43:
+		return uint32(0), nil
+	case 0x52:
+		b, err := d.byte()
+		return uint32(b), err
+	case 0x54:
+		b, err := d.byte()
+		return int32(int8(b)), err
+	case 0x70, 0x71, 0x72, 0x73, 0x74:
+		b, err := d.next(4)
+		if err != nil {
+			return nil, err
+		}
+		v := binary.BigEndian.Uint32(b)
+		switch code {
+		case 0x70:
+			return v, nil
+		case 0x71:
+			return int32(v), nil
+		case 0x72:
+			return math.Float32frombits(v), nil
+		default:
+			// Chars and decimals are kept as raw bits.
+			return v, nil
+		}
+	case 0x44:
+		return uint64(0), nil
+	case 0x53:
+		b, err := d.byte()
+		return uint64(b), err
+	case 0x55:
+		b, err := d.byte()
+		return int64(int8(b)), err
+	case 0x80, 0x81, 0x82, 0x83, 0x84:
+		b, err := d.next(8)
+		if err != nil {
+			return nil, err
+		}
+		v := binary.BigEndian.Uint64(b)
+		switch code {
+		case 0x80:
+			return v, nil
+		case 0x81:
+			return int64(v), nil
+		case 0x82:
+			return math.Float64frombits(v), nil
+		case 0x83:
+			ms := int64(v)
+			return time.Unix(ms/1000, (ms%1000)*int64(time.Millisecond)).UTC(), nil
+		default:
+			return v, nil
+		}
+	case 0x94, 0x98:
+		b, err := d.next(16)
+		if err != nil {
+			return nil, err
+		}
+		return append([]byte(nil), b...), nil
+	case 0xa0, 0xb0, 0xa1, 0xb1, 0xa3, 0xb3:
+		n, err := d.size(code&0xf0 == 0xb0)
+		if err != nil {
+			return nil, err
+		}
+		b, err := d.next(n)
+		if err != nil {
+			return nil, err
+		}
+		switch code {
+		case 0xa0, 0xb0:
+			return append([]byte(nil), b...), nil
+		case 0xa1, 0xb1:
+			return string(b), nil
+		default:
+			return symbol(b), nil
+		}
+	case 0x45:
+		return []interface{}{}, nil
+	case 0xc0, 0xd0, 0xc1, 0xd1:
+		return d.compound(code, depth)
+	case 0xe0, 0xf0:
+		return d.array(code == 0xf0, depth)
+	default:
+		return nil, errMalformedValue
+	}
+}
+
+// compound decodes the list or the map.
+func (d *decoder) compound(code byte, depth int) (interface{}, error) {
+	wide := code == 0xd0 || code == 0xd1
+	size, err := d.size(wide)
+	if err != nil {
+		return nil, err
+	}
+	body, err := d.next(size)
+	if err != nil {
+		return nil, err
+	}
+
+	inner := &decoder{buf: body}
+	count, err := inner.size(wide)
+	if err != nil {
+		return nil, err
+	}
+	// Each element takes one byte at least.
+	if count > len(inner.buf) {
+		return nil, errMalformedValue
+	}
+
+	items := make([]interface{}, count)
+	for i := range items {
+		if items[i], err = inner.value(depth + 1); err != nil {
+			return nil, err
+		}
+	}
+
+	if code == 0xc0 || code == 0xd0 {
+		return items, nil
+	}
+
+	if count%2 != 0 {
+		return nil, errMalformedValue
+	}
+	m := make(amqpMap, 0, count/2)
+	for i := 0; i < count; i += 2 {
+		m = append(m, pair{key: items[i], value: items[i+1]})
+	}
+	return m, nil
+}
+
+// array decodes the array, whose elements share the single constructor.
+func (d *decoder) array(wide bool, depth int) (interface{}, error) {
+	size, err := d.size(wide)
+	if err != nil {
+		return nil, err
+	}
+	body, err := d.next(size)
+	if err != nil {
+		return nil, err
+	}
+
+	inner := &decoder{buf: body}
+	count, err := inner.size(wide)
+	if err != nil {
+		return nil, err
+	}
+	if count > len(inner.buf) {
+		return nil, errMalformedValue
+	}
+
+	code, err := inner.byte()
+	if err != nil {
+		return nil, err
+	}
+	var desc interface{}
+	if code == 0x00 {
+		if desc, err = inner.value(depth + 1); err != nil {
+			return nil, err
+		}
+		if code, err = inner.byte(); err != nil {
+			return nil, err
+		}
+	}
+
+	items := make([]interface{}, count)
+	for i := range items {
+		v, err := inner.primitive(code, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		if desc != nil {
+			v = described{descriptor: descriptorCode(desc), value: v}
+		}
+		items[i] = v
+	}
+
+	return items, nil
+}
+
+// encode appends the encoded value to the buffer.
+func encode(buf *bytes.Buffer, v interface{}) error {
+	switch v := v.(type) {
+	case nil:
+		buf.WriteByte(0x40)
+	case bool:
+		if v {
+			buf.WriteByte(0x41)
+		} else {
+			buf.WriteByte(0x42)
+		}
+	case uint8:
+		buf.Write([]byte{0x50, v})
+	case uint16:
+		buf.WriteByte(0x60)
+		writeUint16(buf, v)
+	case uint32:
+		switch {
+		case v == 0:
+			buf.WriteByte(0x43)
+		case v < 256:
+			buf.Write([]byte{0x52, byte(v)})
+		default:
+			buf.WriteByte(0x70)
+			writeUint32(buf, v)
+		}
+	case uint64:
+		switch {
+		case v == 0:
+			buf.WriteByte(0x44)
+		case v < 256:
+			buf.Write([]byte{0x53, byte(v)})
+		default:
+			buf.WriteByte(0x80)
+			writeUint64(buf, v)
+		}
+	case int32:
+		buf.WriteByte(0x71)
+		writeUint32(buf, uint32(v))
+	case int64:
+		buf.WriteByte(0x81)
+		writeUint64(buf, uint64(v))
+	case time.Time:
+		buf.WriteByte(0x83)
+		writeUint64(buf, uint64(v.UnixNano()/int64(time.Millisecond)))
+	case []byte:
+		encodeVariable(buf, 0xa0, v)
+	case string:
+		encodeVariable(buf, 0xa1, []byte(v))
+	case symbol:
+		encodeVariable(buf, 0xa3, []byte(v))
+	case described:
+		buf.WriteByte(0x00)
+		encode(buf, v.descriptor)
+		return encode(buf, v.value)
+	case []interface{}:
+		if len(v) == 0 {
+			buf.WriteByte(0x45)
+			return nil
+		}
+		return encodeCompound(buf, 0xc0, v)
+	case amqpMap:
+		items := make([]interface{}, 0, 2*len(v))
+		for _, p := range v {
+			items = append(items, p.key, p.value)
+		}
+		return encodeCompound(buf, 0xc1, items)
+	default:
+		return fmt.Errorf("unsupported AMQP value type %T", v)
+	}
+
+	return nil
+}
+
+// encodeVariable encodes the binary, string or symbol, using the one byte
+// size if possible.
+func encodeVariable(buf *bytes.Buffer, code byte, b []byte) {
+	if len(b) < 256 {
+		buf.Write([]byte{code, byte(len(b))})
+	} else {
+		buf.WriteByte(code | 0x10)
+		writeUint32(buf, uint32(len(b)))
+	}
+	buf.Write(b)
+}
+
+// encodeCompound encodes the list or the map, using the one byte size and
+// count if possible.
+func encodeCompound(buf *bytes.Buffer, code byte, items []interface{}) error {
+	var body bytes.Buffer
+	for _, item := range items {
+		if err := encode(&body, item); err != nil {
+			return err
+		}
+	}
+
+	if body.Len()+1 < 256 && len(items) < 256 {
+		buf.Write([]byte{code, byte(body.Len() + 1), byte(len(items))})
+	} else {
+		buf.WriteByte(code | 0x10)
+		writeUint32(buf, uint32(body.Len()+4))
+		writeUint32(buf, uint32(len(items)))
+	}
+	buf.Write(body.Bytes())
+	return nil
+}
+
+func writeUint16(buf *bytes.Buffer, v uint16) {
+	var b [2]byte
+	binary.BigEndian.PutUint16(b[:], v)
+	buf.Write(b[:])
+}
+
+func writeUint32(buf *bytes.Buffer, v uint32) {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], v)
+	buf.Write(b[:])
+}
+
+func writeUint64(buf *bytes.Buffer, v uint64) {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], v)
+	buf.Write(b[:])
+}
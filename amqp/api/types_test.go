@@ -0,0 +1,69 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	cases := []struct {
+		desc  string
+		value interface{}
+	}{
+		{desc: "encode null", value: nil},
+		{desc: "encode boolean", value: true},
+		{desc: "encode small uint", value: uint32(7)},
+		{desc: "encode uint", value: uint32(70000)},
+		{desc: "encode ulong", value: uint64(1 << 40)},
+		{desc: "encode int", value: int32(-5)},
+		{desc: "encode string", value: "temperature"},
+		{desc: "encode long string", value: strings.Repeat("a", 300)},
+		{desc: "encode symbol", value: symbol("amqp:not-found")},
+		{desc: "encode binary", value: []byte{1, 2, 3}},
+		{desc: "encode empty list", value: []interface{}{}},
+		{desc: "encode list", value: []interface{}{"a", uint32(1), nil, true}},
+		{desc: "encode map", value: amqpMap{{key: "gateway", value: "gw-1"}}},
+		{desc: "encode described list", value: list(descAccepted, "a")},
+	}
+
+	for _, tc := range cases {
+		var buf bytes.Buffer
+		err := encode(&buf, tc.value)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+
+		d := &decoder{buf: buf.Bytes()}
+		v, err := d.value(0)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		assert.Equal(t, tc.value, v, fmt.Sprintf("%s: expected %v got %v", tc.desc, tc.value, v))
+		assert.Empty(t, d.buf, fmt.Sprintf("%s: expected whole value decoded", tc.desc))
+	}
+}
+
+func TestDecodeMalformed(t *testing.T) {
+	nested := bytes.Repeat([]byte{0x00, 0x53, 0x01}, maxDepth+1)
+
+	cases := []struct {
+		desc string
+		data []byte
+	}{
+		{desc: "decode empty data", data: []byte{}},
+		{desc: "decode unknown constructor", data: []byte{0xff}},
+		{desc: "decode truncated string", data: []byte{0xa1, 0x05, 'a'}},
+		{desc: "decode list with too large count", data: []byte{0xc0, 0x02, 0xff, 0x40}},
+		{desc: "decode map with odd count", data: []byte{0xc1, 0x02, 0x01, 0x40}},
+		{desc: "decode too deeply nested value", data: nested},
+	}
+
+	for _, tc := range cases {
+		d := &decoder{buf: tc.data}
+		_, err := d.value(0)
+		assert.Equal(t, errMalformedValue, err, fmt.Sprintf("%s: expected %s got %s", tc.desc, errMalformedValue, err))
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/amqp"
+)
+
+var _ amqp.PubSub = (*pubSub)(nil)
+
+type pubSub struct {
+	mu   sync.Mutex
+	subs map[string]map[int]chan mainflux.RawMessage
+	next int
+}
+
+// NewPubSub returns mock broker delivering the published messages to the
+// subscriptions of their channel subtopic.
+func NewPubSub() amqp.PubSub {
+	return &pubSub{subs: make(map[string]map[int]chan mainflux.RawMessage)}
+}
+
+func (ps *pubSub) Publish(_ context.Context, _ string, msg mainflux.RawMessage) error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	for _, msgs := range ps.subs[key(msg.Channel, msg.Subtopic)] {
+		select {
+		case msgs <- msg:
+		default:
+		}
+	}
+
+	return nil
+}
+
+func (ps *pubSub) Subscribe(chanID, subtopic string) (<-chan mainflux.RawMessage, func(), error) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	k := key(chanID, subtopic)
+	if ps.subs[k] == nil {
+		ps.subs[k] = make(map[int]chan mainflux.RawMessage)
+	}
+
+	id := ps.next
+	ps.next++
+	msgs := make(chan mainflux.RawMessage, 10)
+	ps.subs[k][id] = msgs
+
+	cancel := func() {
+		ps.mu.Lock()
+		defer ps.mu.Unlock()
+		delete(ps.subs[k], id)
+	}
+
+	return msgs, cancel, nil
+}
+
+func key(chanID, subtopic string) string {
+	return fmt.Sprintf("%s.%s", chanID, subtopic)
+}
@@ -0,0 +1,109 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/mainflux/mainflux"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var _ mainflux.ThingsServiceClient = (*thingsClient)(nil)
+
+// ServiceErrToken is used to simulate internal server error.
+const ServiceErrToken = "unavailable"
+
+type thingsClient struct {
+	things map[string]string
+	conns  map[string][]string
+}
+
+// NewThingsClient returns mock implementation of things service client. The
+// things are identified by their keys, and are connected to the channels
+// listed by their IDs.
+func NewThingsClient(things map[string]string, conns map[string][]string) mainflux.ThingsServiceClient {
+	return &thingsClient{
+		things: things,
+		conns:  conns,
+	}
+}
+
+func (tc thingsClient) CanAccess(ctx context.Context, req *mainflux.AccessReq, opts ...grpc.CallOption) (*mainflux.ThingID, error) {
+	id, err := tc.Identify(ctx, &mainflux.Token{Value: req.GetToken()})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, chanID := range tc.conns[id.GetValue()] {
+		if chanID == req.GetChanID() {
+			return id, nil
+		}
+	}
+
+	return nil, status.Error(codes.PermissionDenied, "thing not connected to channel")
+}
+
+func (tc thingsClient) CanAccessByID(context.Context, *mainflux.AccessByIDReq, ...grpc.CallOption) (*empty.Empty, error) {
+	panic("not implemented")
+}
+
+func (tc thingsClient) Identify(_ context.Context, req *mainflux.Token, _ ...grpc.CallOption) (*mainflux.ThingID, error) {
+	// Since there is no appropriate way to simulate internal server error,
+	// we had to use this obscure approach. ErrorToken simulates gRPC
+	// call which returns internal server error.
+	if req.GetValue() == ServiceErrToken {
+		return nil, status.Error(codes.Internal, "internal server error")
+	}
+
+	id, ok := tc.things[req.GetValue()]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "entity does not exist")
+	}
+
+	return &mainflux.ThingID{Value: id}, nil
+}
+
+func (tc thingsClient) Owner(context.Context, *mainflux.ThingID, ...grpc.CallOption) (*mainflux.UserID, error) {
+	panic("not implemented")
+}
+
+func (tc thingsClient) Retention(context.Context, *mainflux.ChannelID, ...grpc.CallOption) (*mainflux.Retention, error) {
+	panic("not implemented")
+}
+
+func (tc thingsClient) Region(context.Context, *mainflux.ChannelID, ...grpc.CallOption) (*mainflux.Region, error) {
+	panic("not implemented")
+}
+
+func (tc thingsClient) Alias(context.Context, *mainflux.ChannelID, ...grpc.CallOption) (*mainflux.ChannelAlias, error) {
+	panic("not implemented")
+}
+
+func (tc thingsClient) ResolveAlias(context.Context, *mainflux.ChannelAlias, ...grpc.CallOption) (*mainflux.ChannelID, error) {
+	panic("not implemented")
+}
+
+func (tc thingsClient) Ordering(context.Context, *mainflux.ChannelID, ...grpc.CallOption) (*mainflux.Ordering, error) {
+	panic("not implemented")
+}
+
+func (tc thingsClient) Transformer(context.Context, *mainflux.ChannelID, ...grpc.CallOption) (*mainflux.Transformer, error) {
+	panic("not implemented")
+}
+
+func (tc thingsClient) PayloadSchema(context.Context, *mainflux.PayloadSchemaReq, ...grpc.CallOption) (*mainflux.PayloadSchema, error) {
+	panic("not implemented")
+}
+
+func (tc thingsClient) KeyDigest(context.Context, *mainflux.ThingID, ...grpc.CallOption) (*mainflux.KeyDigest, error) {
+	panic("not implemented")
+}
+
+func (tc thingsClient) Changes(context.Context, *mainflux.ChangesReq, ...grpc.CallOption) (mainflux.ThingsService_ChangesClient, error) {
+	panic("not implemented")
+}
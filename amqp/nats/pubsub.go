@@ -0,0 +1,84 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package nats contains NATS message publisher and subscriber implementation.
+package nats
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/amqp"
+	log "github.com/mainflux/mainflux/logger"
+	mfnats "github.com/mainflux/mainflux/nats"
+	broker "github.com/nats-io/nats.go"
+)
+
+const (
+	prefix = "channel"
+	// bufferSize is the number of the messages buffered per subscription,
+	// which aren't delivered to the receiver yet.
+	bufferSize = 256
+)
+
+var _ amqp.PubSub = (*natsPubSub)(nil)
+
+type natsPubSub struct {
+	nc            *broker.Conn
+	subjectPrefix string
+	logger        log.Logger
+}
+
+// New instantiates NATS message publisher and subscriber. The subjects are
+// prefixed with the deployment subject prefix, unless it is empty.
+func New(nc *broker.Conn, subjectPrefix string, logger log.Logger) amqp.PubSub {
+	return &natsPubSub{
+		nc:            nc,
+		subjectPrefix: subjectPrefix,
+		logger:        logger,
+	}
+}
+
+func (ps *natsPubSub) fmtSubject(chanID, subtopic string) string {
+	subject := fmt.Sprintf("%s.%s", prefix, chanID)
+	if subtopic != "" {
+		subject = fmt.Sprintf("%s.%s", subject, subtopic)
+	}
+	return mfnats.Subject(ps.subjectPrefix, subject)
+}
+
+func (ps *natsPubSub) Publish(_ context.Context, _ string, msg mainflux.RawMessage) error {
+	data, err := proto.Marshal(&msg)
+	if err != nil {
+		return err
+	}
+
+	return ps.nc.Publish(ps.fmtSubject(msg.Channel, msg.Subtopic), data)
+}
+
+func (ps *natsPubSub) Subscribe(chanID, subtopic string) (<-chan mainflux.RawMessage, func(), error) {
+	msgs := make(chan mainflux.RawMessage, bufferSize)
+
+	sub, err := ps.nc.Subscribe(ps.fmtSubject(chanID, subtopic), func(m *broker.Msg) {
+		var msg mainflux.RawMessage
+		if err := proto.Unmarshal(m.Data, &msg); err != nil {
+			ps.logger.Warn(fmt.Sprintf("Failed to deserialize received message: %s", err))
+			return
+		}
+
+		// Messages are dropped once the receiver doesn't keep up, since
+		// the subscriptions are delivered at most once.
+		select {
+		case msgs <- msg:
+		default:
+			ps.logger.Warn(fmt.Sprintf("Dropped message of channel %s for slow receiver", chanID))
+		}
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return msgs, func() { sub.Unsubscribe() }, nil
+}
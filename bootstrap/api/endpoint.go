@@ -255,3 +255,25 @@ func stateEndpoint(svc bootstrap.Service) endpoint.Endpoint {
 		return stateRes{}, nil
 	}
 }
+
+func bulkStateEndpoint(svc bootstrap.Service) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(bulkStateReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		ids, err := svc.BulkChangeState(req.key, req.filter(), req.State)
+		if err != nil {
+			return nil, err
+		}
+
+		res := bulkStateRes{
+			Total:  uint64(len(ids)),
+			Things: ids,
+		}
+
+		return res, nil
+	}
+}
@@ -178,7 +178,7 @@ func newThingsService(users mainflux.UsersServiceClient) things.Service {
 }
 
 func newThingsServer(svc things.Service) *httptest.Server {
-	mux := thingsapi.MakeHandler(mocktracer.New(), svc)
+	mux := thingsapi.MakeHandler(mocktracer.New(), svc, 0)
 	return httptest.NewServer(mux)
 }
 
@@ -1377,3 +1377,114 @@ type configPage struct {
 	Limit   uint64   `json:"limit"`
 	Configs []config `json:"configs"`
 }
+
+func TestBulkChangeState(t *testing.T) {
+	users := mocks.NewUsersService(map[string]string{validToken: email})
+
+	ts := newThingsServer(newThingsService(users))
+	svc := newService(users, nil, ts.URL)
+	bs := newBootstrapServer(svc)
+
+	num := 3
+	for i := 0; i < num; i++ {
+		c := newConfig([]bootstrap.Channel{bootstrap.Channel{ID: "1"}})
+		c.ExternalID = fmt.Sprintf("bulk-%d", i)
+		_, err := svc.Add(validToken, c)
+		require.Nil(t, err, fmt.Sprintf("Saving config expected to succeed: %s.\n", err))
+	}
+
+	byPrefix := fmt.Sprintf(`{"state": %d, "filter": {"external_id_prefix": "bulk-"}}`, bootstrap.Active)
+	byState := fmt.Sprintf(`{"state": %d, "filter": {"state": %d}}`, bootstrap.Inactive, bootstrap.Active)
+
+	cases := []struct {
+		desc        string
+		auth        string
+		body        string
+		contentType string
+		status      int
+		total       uint64
+	}{
+		{
+			desc:        "bulk change state unauthorized",
+			auth:        invalidToken,
+			body:        byPrefix,
+			contentType: contentType,
+			status:      http.StatusForbidden,
+		},
+		{
+			desc:        "bulk change state with invalid content type",
+			auth:        validToken,
+			body:        byPrefix,
+			contentType: "",
+			status:      http.StatusUnsupportedMediaType,
+		},
+		{
+			desc:        "bulk change state without filter",
+			auth:        validToken,
+			body:        fmt.Sprintf(`{"state": %d}`, bootstrap.Active),
+			contentType: contentType,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "bulk change state with invalid filter state",
+			auth:        validToken,
+			body:        fmt.Sprintf(`{"state": %d, "filter": {"state": %d}}`, bootstrap.Active, -3),
+			contentType: contentType,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "bulk change state to invalid value",
+			auth:        validToken,
+			body:        fmt.Sprintf(`{"state": %d, "filter": {"external_id_prefix": "bulk-"}}`, -3),
+			contentType: contentType,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "bulk change state with invalid data",
+			auth:        validToken,
+			body:        "",
+			contentType: contentType,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "bulk change state by external ID prefix",
+			auth:        validToken,
+			body:        byPrefix,
+			contentType: contentType,
+			status:      http.StatusOK,
+			total:       uint64(num),
+		},
+		{
+			desc:        "bulk change state by state",
+			auth:        validToken,
+			body:        byState,
+			contentType: contentType,
+			status:      http.StatusOK,
+			total:       uint64(num),
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      bs.Client(),
+			method:      http.MethodPut,
+			url:         fmt.Sprintf("%s/things/state", bs.URL),
+			token:       tc.auth,
+			contentType: tc.contentType,
+			body:        strings.NewReader(tc.body),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+
+		if res.StatusCode != http.StatusOK {
+			continue
+		}
+		var body struct {
+			Total uint64 `json:"total"`
+		}
+		err = json.NewDecoder(res.Body).Decode(&body)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.total, body.Total, fmt.Sprintf("%s: expected total %d got %d", tc.desc, tc.total, body.Total))
+	}
+}
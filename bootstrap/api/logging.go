@@ -142,6 +142,19 @@ func (lm *loggingMiddleware) ChangeState(key, id string, state bootstrap.State)
 	return lm.svc.ChangeState(key, id, state)
 }
 
+func (lm *loggingMiddleware) BulkChangeState(key string, filter bootstrap.Filter, state bootstrap.State) (ids []string, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method bulk_change_state for key %s changed %d things to state %s and took %s to complete", key, len(ids), state, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.BulkChangeState(key, filter, state)
+}
+
 func (lm *loggingMiddleware) UpdateChannelHandler(channel bootstrap.Channel) (err error) {
 	defer func(begin time.Time) {
 		message := fmt.Sprintf("Method update_channel_handler for channel %s took %s to complete", channel.ID, time.Since(begin))
@@ -111,6 +111,15 @@ func (mm *metricsMiddleware) ChangeState(id, key string, state bootstrap.State)
 	return mm.svc.ChangeState(id, key, state)
 }
 
+func (mm *metricsMiddleware) BulkChangeState(key string, filter bootstrap.Filter, state bootstrap.State) (ids []string, err error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "bulk_change_state").Add(1)
+		mm.latency.With("method", "bulk_change_state").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.BulkChangeState(key, filter, state)
+}
+
 func (mm *metricsMiddleware) UpdateChannelHandler(channel bootstrap.Channel) (err error) {
 	defer func(begin time.Time) {
 		mm.counter.With("method", "update_channel").Add(1)
@@ -166,3 +166,58 @@ func (req changeStateReq) validate() error {
 
 	return nil
 }
+
+type stateFilter struct {
+	ExternalIDPrefix string                 `json:"external_id_prefix,omitempty"`
+	Channel          string                 `json:"channel,omitempty"`
+	State            *bootstrap.State       `json:"state,omitempty"`
+	Metadata         map[string]interface{} `json:"metadata,omitempty"`
+}
+
+type bulkStateReq struct {
+	key    string
+	State  bootstrap.State `json:"state"`
+	Filter stateFilter     `json:"filter"`
+}
+
+func (req bulkStateReq) validate() error {
+	if req.key == "" {
+		return bootstrap.ErrUnauthorizedAccess
+	}
+
+	if req.State != bootstrap.Inactive &&
+		req.State != bootstrap.Active {
+		return bootstrap.ErrMalformedEntity
+	}
+
+	// Require at least one criterion to prevent accidental
+	// state change of all the owner's Configs.
+	f := req.Filter
+	if f.ExternalIDPrefix == "" && f.Channel == "" && f.State == nil && len(f.Metadata) == 0 {
+		return bootstrap.ErrMalformedEntity
+	}
+
+	if f.State != nil &&
+		*f.State != bootstrap.Inactive &&
+		*f.State != bootstrap.Active {
+		return bootstrap.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+func (req bulkStateReq) filter() bootstrap.Filter {
+	filter := bootstrap.Filter{
+		FullMatch:        make(map[string]string),
+		PartialMatch:     make(map[string]string),
+		ExternalIDPrefix: req.Filter.ExternalIDPrefix,
+		Channel:          req.Filter.Channel,
+		Metadata:         req.Filter.Metadata,
+	}
+
+	if req.Filter.State != nil {
+		filter.FullMatch["state"] = req.Filter.State.String()
+	}
+
+	return filter
+}
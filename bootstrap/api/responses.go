@@ -17,6 +17,7 @@ var (
 	_ mainflux.Response = (*stateRes)(nil)
 	_ mainflux.Response = (*viewRes)(nil)
 	_ mainflux.Response = (*listRes)(nil)
+	_ mainflux.Response = (*bulkStateRes)(nil)
 )
 
 type removeRes struct{}
@@ -142,3 +143,20 @@ func (res stateRes) Headers() map[string]string {
 func (res stateRes) Empty() bool {
 	return true
 }
+
+type bulkStateRes struct {
+	Total  uint64   `json:"total"`
+	Things []string `json:"things"`
+}
+
+func (res bulkStateRes) Code() int {
+	return http.StatusOK
+}
+
+func (res bulkStateRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res bulkStateRes) Empty() bool {
+	return false
+}
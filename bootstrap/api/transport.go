@@ -94,6 +94,12 @@ func MakeHandler(svc bootstrap.Service, reader bootstrap.ConfigReader) http.Hand
 		encodeSecureRes,
 		opts...))
 
+	r.Put("/things/state", kithttp.NewServer(
+		bulkStateEndpoint(svc),
+		decodeBulkStateRequest,
+		encodeResponse,
+		opts...))
+
 	r.Put("/things/state/:id", kithttp.NewServer(
 		stateEndpoint(svc),
 		decodeStateRequest,
@@ -107,6 +113,10 @@ func MakeHandler(svc bootstrap.Service, reader bootstrap.ConfigReader) http.Hand
 		opts...))
 
 	r.GetFunc("/version", mainflux.Version("bootstrap"))
+	r.GetFunc(mainflux.DiscoveryPath, mainflux.Discovery("bootstrap", mainflux.Capabilities{
+		ContentTypes: []string{contentType},
+		Limits:       mainflux.Limits{MaxPageSize: maxLimit},
+	}))
 	r.Handle("/metrics", promhttp.Handler())
 
 	return r
@@ -202,7 +212,10 @@ func decodeListRequest(_ context.Context, r *http.Request) (interface{}, error)
 		return nil, err
 	}
 
-	filter := parseFilter(q)
+	filter, err := parseFilter(q)
+	if err != nil {
+		return nil, err
+	}
 
 	req := listReq{
 		key:    r.Header.Get("Authorization"),
@@ -237,6 +250,19 @@ func decodeStateRequest(_ context.Context, r *http.Request) (interface{}, error)
 	return req, nil
 }
 
+func decodeBulkStateRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+
+	req := bulkStateReq{key: r.Header.Get("Authorization")}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
 func decodeEntityRequest(_ context.Context, r *http.Request) (interface{}, error) {
 	req := entityReq{
 		key: r.Header.Get("Authorization"),
@@ -341,10 +367,12 @@ func parsePagePrams(q url.Values) (uint64, uint64, error) {
 	return offset, limit, nil
 }
 
-func parseFilter(values url.Values) bootstrap.Filter {
+func parseFilter(values url.Values) (bootstrap.Filter, error) {
 	ret := bootstrap.Filter{
-		FullMatch:    make(map[string]string),
-		PartialMatch: make(map[string]string),
+		FullMatch:        make(map[string]string),
+		PartialMatch:     make(map[string]string),
+		ExternalIDPrefix: values.Get("external_id_prefix"),
+		Channel:          values.Get("channel"),
 	}
 	for k := range values {
 		if contains(fullMatch, k) {
@@ -355,7 +383,13 @@ func parseFilter(values url.Values) bootstrap.Filter {
 		}
 	}
 
-	return ret
+	if m := values.Get("metadata"); m != "" {
+		if err := json.Unmarshal([]byte(m), &ret.Metadata); err != nil {
+			return bootstrap.Filter{}, errInvalidQueryParams
+		}
+	}
+
+	return ret, nil
 }
 
 func contains(l []string, s string) bool {
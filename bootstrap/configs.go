@@ -31,10 +31,17 @@ type Channel struct {
 }
 
 // Filter is used for the search filters.
+// ExternalIDPrefix matches Configs whose external ID starts with the prefix.
+// Channel matches Configs connected to the Channel with the given ID.
+// Metadata matches Configs connected to at least one Channel whose metadata
+// contains all the given key-value pairs.
 type Filter struct {
-	Unknown      bool
-	FullMatch    map[string]string
-	PartialMatch map[string]string
+	Unknown          bool
+	FullMatch        map[string]string
+	PartialMatch     map[string]string
+	ExternalIDPrefix string
+	Channel          string
+	Metadata         map[string]interface{}
 }
 
 // ConfigsPage contains page related metadata as well as list of Configs that
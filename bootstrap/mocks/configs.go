@@ -4,6 +4,7 @@
 package mocks
 
 import (
+	"reflect"
 	"sort"
 	"strconv"
 	"strings"
@@ -110,6 +111,9 @@ func (crm *configRepositoryMock) RetrieveAll(key string, filter bootstrap.Filter
 		id, _ := strconv.ParseUint(v.MFThing, 10, 64)
 		if (state == emptyState || v.State == state) &&
 			(name == "" || strings.Index(strings.ToLower(v.Name), name) != notFoundIdx) &&
+			strings.HasPrefix(v.ExternalID, filter.ExternalIDPrefix) &&
+			(filter.Channel == "" || connected(v, filter.Channel)) &&
+			(len(filter.Metadata) == 0 || matchesMetadata(v, filter.Metadata)) &&
 			v.Owner == key {
 			if id >= first && id < last {
 				configs = append(configs, v)
@@ -362,3 +366,28 @@ func (crm *configRepositoryMock) DisconnectThing(channelID, thingID string) erro
 
 	return nil
 }
+
+func connected(cfg bootstrap.Config, channel string) bool {
+	for _, ch := range cfg.MFChannels {
+		if ch.ID == channel {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesMetadata(cfg bootstrap.Config, metadata map[string]interface{}) bool {
+	for _, ch := range cfg.MFChannels {
+		match := true
+		for k, v := range metadata {
+			if !reflect.DeepEqual(ch.Metadata[k], v) {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
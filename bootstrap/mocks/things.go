@@ -7,6 +7,7 @@ import (
 	"context"
 	"strconv"
 	"sync"
+	"time"
 
 	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/things"
@@ -68,7 +69,7 @@ func (svc *mainfluxThings) ViewThing(_ context.Context, owner, id string) (thing
 	return things.Thing{}, things.ErrNotFound
 }
 
-func (svc *mainfluxThings) Connect(_ context.Context, owner, chanID, thingID string) error {
+func (svc *mainfluxThings) Connect(_ context.Context, owner, chanID, thingID string, _ []string) error {
 	svc.mu.Lock()
 	defer svc.mu.Unlock()
 
@@ -162,7 +163,63 @@ func (svc *mainfluxThings) UpdateKey(context.Context, string, string, string) er
 	panic("not implemented")
 }
 
-func (svc *mainfluxThings) ListThings(context.Context, string, uint64, uint64, string, things.Metadata) (things.ThingsPage, error) {
+func (svc *mainfluxThings) RotateKey(context.Context, string, string) (string, error) {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) ListThings(context.Context, string, things.PageQuery) (things.ThingsPage, error) {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) RestoreThing(context.Context, string, string) error {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) PurgeThing(context.Context, string, string) error {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) ShareThing(context.Context, string, string, string, string) error {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) UnshareThing(context.Context, string, string, string) error {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) ShareThingWithUser(context.Context, string, string, string, string) error {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) UnshareThingWithUser(context.Context, string, string, string) error {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) ExportThings(context.Context, string, func(things.Thing) error) error {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) ImportThings(context.Context, string, []things.Thing) ([]things.Thing, error) {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) ExportChannels(context.Context, string, func(things.Channel) error) error {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) ImportChannels(context.Context, string, []things.Channel) ([]things.Channel, error) {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) ExportConnections(context.Context, string, func(things.Connection) error) error {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) ImportConnections(context.Context, string, []things.Connection) ([]things.Connection, error) {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) SearchChannels(context.Context, string, string, uint64, uint64) (things.ChannelsPage, error) {
 	panic("not implemented")
 }
 
@@ -170,6 +227,10 @@ func (svc *mainfluxThings) ListChannelsByThing(context.Context, string, string,
 	panic("not implemented")
 }
 
+func (svc *mainfluxThings) SearchThings(context.Context, string, string, uint64, uint64) (things.ThingsPage, error) {
+	panic("not implemented")
+}
+
 func (svc *mainfluxThings) ListThingsByChannel(context.Context, string, string, uint64, uint64) (things.ThingsPage, error) {
 	panic("not implemented")
 }
@@ -178,11 +239,15 @@ func (svc *mainfluxThings) CreateChannel(context.Context, string, things.Channel
 	panic("not implemented")
 }
 
+func (svc *mainfluxThings) CreateDirectChannel(context.Context, string, string, string) (things.Channel, error) {
+	panic("not implemented")
+}
+
 func (svc *mainfluxThings) UpdateChannel(context.Context, string, things.Channel) error {
 	panic("not implemented")
 }
 
-func (svc *mainfluxThings) ListChannels(context.Context, string, uint64, uint64, string, things.Metadata) (things.ChannelsPage, error) {
+func (svc *mainfluxThings) ListChannels(context.Context, string, things.PageQuery) (things.ChannelsPage, error) {
 	panic("not implemented")
 }
 
@@ -190,11 +255,35 @@ func (svc *mainfluxThings) RemoveChannel(context.Context, string, string) error
 	panic("not implemented")
 }
 
-func (svc *mainfluxThings) CanAccess(context.Context, string, string) (string, error) {
+func (svc *mainfluxThings) RestoreChannel(context.Context, string, string) error {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) PurgeChannel(context.Context, string, string) error {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) ShareChannel(context.Context, string, string, string, string) error {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) UnshareChannel(context.Context, string, string, string) error {
 	panic("not implemented")
 }
 
-func (svc *mainfluxThings) CanAccessByID(context.Context, string, string) error {
+func (svc *mainfluxThings) ShareChannelWithUser(context.Context, string, string, string, string) error {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) UnshareChannelWithUser(context.Context, string, string, string) error {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) CanAccess(context.Context, string, string, string) (string, error) {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) CanAccessByID(context.Context, string, string, string) error {
 	panic("not implemented")
 }
 
@@ -202,6 +291,90 @@ func (svc *mainfluxThings) Identify(context.Context, string) (string, error) {
 	panic("not implemented")
 }
 
+func (svc *mainfluxThings) Owner(context.Context, string) (string, error) {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) KeyDigest(context.Context, string) (string, error) {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) Retention(context.Context, string) (things.Retention, error) {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) Region(context.Context, string) (string, error) {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) Alias(context.Context, string) (string, error) {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) ResolveAlias(context.Context, string) (string, error) {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) Ordering(context.Context, string) (bool, error) {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) Transformer(context.Context, string) (things.Transformer, error) {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) UpdateQuota(context.Context, string, things.Quota) error {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) ViewQuota(context.Context, string, string) (things.Quota, things.Usage, error) {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) RemoveQuota(context.Context, string, string) error {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) TransferOwnership(context.Context, string, string, string) error {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) SaveSchema(context.Context, string, things.MetadataSchema) error {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) ViewSchema(context.Context, string, string) (things.MetadataSchema, error) {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) RemoveSchema(context.Context, string, string) error {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) SavePayloadSchema(context.Context, string, things.PayloadSchema) error {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) ListPayloadSchemas(context.Context, string, string) ([]things.PayloadSchema, error) {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) RemovePayloadSchema(context.Context, string, string, string) error {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) PayloadSchema(context.Context, string, string) (things.PayloadSchema, error) {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) ListChanges(context.Context, string, string, uint64) (things.ChangesPage, error) {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) ViewAPIUsage(context.Context, string, time.Time, time.Time) ([]things.APIUsage, error) {
+	panic("not implemented")
+}
+
 func findIndex(list []string, val string) int {
 	for i, v := range list {
 		if v == val {
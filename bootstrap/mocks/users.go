@@ -28,3 +28,14 @@ func (svc usersServiceMock) Identify(ctx context.Context, in *mainflux.Token, op
 	}
 	return nil, users.ErrUnauthorizedAccess
 }
+
+func (svc usersServiceMock) Groups(ctx context.Context, in *mainflux.Token, opts ...grpc.CallOption) (*mainflux.GroupIDs, error) {
+	if _, ok := svc.users[in.Value]; ok {
+		return &mainflux.GroupIDs{}, nil
+	}
+	return nil, users.ErrUnauthorizedAccess
+}
+
+func (svc usersServiceMock) Disabled(ctx context.Context, in *mainflux.UserID, opts ...grpc.CallOption) (*mainflux.UserStatus, error) {
+	return &mainflux.UserStatus{}, nil
+}
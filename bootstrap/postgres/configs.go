@@ -29,6 +29,9 @@ const (
 
 var _ bootstrap.ConfigRepository = (*configRepository)(nil)
 
+// likeEscaper escapes LIKE wildcards so that the value is matched literally.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
 type configRepository struct {
 	db  *sqlx.DB
 	log logger.Logger
@@ -480,6 +483,27 @@ func (cr configRepository) retrieveAll(key string, filter bootstrap.Filter) (str
 		params = append(params, v)
 		counter++
 	}
+	if filter.ExternalIDPrefix != "" {
+		queries = append(queries, fmt.Sprintf("external_id LIKE $%d || '%%'", counter))
+		params = append(params, likeEscaper.Replace(filter.ExternalIDPrefix))
+		counter++
+	}
+	if filter.Channel != "" {
+		queries = append(queries, fmt.Sprintf(`mainflux_thing IN (SELECT config_id FROM connections
+			WHERE config_owner = $1 AND channel_id = $%d)`, counter))
+		params = append(params, filter.Channel)
+		counter++
+	}
+	if len(filter.Metadata) > 0 {
+		metadata, err := json.Marshal(filter.Metadata)
+		if err == nil {
+			queries = append(queries, fmt.Sprintf(`mainflux_thing IN (SELECT conn.config_id FROM connections conn
+				JOIN channels ch ON ch.mainflux_channel = conn.channel_id AND ch.owner = conn.channel_owner
+				WHERE conn.config_owner = $1 AND ch.metadata::jsonb @> $%d::jsonb)`, counter))
+			params = append(params, string(metadata))
+			counter++
+		}
+	}
 
 	f := strings.Join(queries, " AND ")
 
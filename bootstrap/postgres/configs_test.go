@@ -168,6 +168,7 @@ func TestRetrieveAll(t *testing.T) {
 
 		if i%2 == 0 {
 			c.State = bootstrap.Active
+			c.ExternalID = fmt.Sprintf("even-%s", c.ExternalID)
 		}
 
 		if i > 0 {
@@ -223,6 +224,38 @@ func TestRetrieveAll(t *testing.T) {
 			filter: bootstrap.Filter{PartialMatch: map[string]string{"name": "1"}},
 			size:   1,
 		},
+		{
+			desc:   "retrieve by external ID prefix",
+			owner:  config.Owner,
+			offset: 0,
+			limit:  uint64(numConfigs),
+			filter: bootstrap.Filter{ExternalIDPrefix: "even-"},
+			size:   numConfigs / 2,
+		},
+		{
+			desc:   "retrieve by channel",
+			owner:  config.Owner,
+			offset: 0,
+			limit:  uint64(numConfigs),
+			filter: bootstrap.Filter{Channel: channels[0]},
+			size:   numConfigs,
+		},
+		{
+			desc:   "retrieve by channel metadata",
+			owner:  config.Owner,
+			offset: 0,
+			limit:  uint64(numConfigs),
+			filter: bootstrap.Filter{Metadata: map[string]interface{}{"meta": 2.0}},
+			size:   numConfigs,
+		},
+		{
+			desc:   "retrieve by non-matching channel metadata",
+			owner:  config.Owner,
+			offset: 0,
+			limit:  uint64(numConfigs),
+			filter: bootstrap.Filter{Metadata: map[string]interface{}{"meta": 3.0}},
+			size:   0,
+		},
 	}
 	for _, tc := range cases {
 		ret := repo.RetrieveAll(tc.owner, tc.filter, tc.offset, tc.limit)
@@ -35,13 +35,13 @@ type EventStore interface {
 
 type eventStore struct {
 	svc      bootstrap.Service
-	client   *redis.Client
+	client   redis.UniversalClient
 	consumer string
 	logger   logger.Logger
 }
 
 // NewEventStore returns new event store instance.
-func NewEventStore(svc bootstrap.Service, client *redis.Client, consumer string, log logger.Logger) EventStore {
+func NewEventStore(svc bootstrap.Service, client redis.UniversalClient, consumer string, log logger.Logger) EventStore {
 	return eventStore{
 		svc:      svc,
 		client:   client,
@@ -103,7 +103,7 @@ func decodeRemoveThing(event map[string]interface{}) removeEvent {
 func decodeUpdateChannel(event map[string]interface{}) updateChannelEvent {
 	strmeta := read(event, "metadata", "{}")
 	var metadata map[string]interface{}
-	if err := json.Unmarshal([]byte(strmeta), metadata); err != nil {
+	if err := json.Unmarshal([]byte(strmeta), &metadata); err != nil {
 		metadata = map[string]interface{}{}
 	}
 
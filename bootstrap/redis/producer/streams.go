@@ -19,12 +19,12 @@ var _ bootstrap.Service = (*eventStore)(nil)
 
 type eventStore struct {
 	svc    bootstrap.Service
-	client *redis.Client
+	client redis.UniversalClient
 }
 
 // NewEventStoreMiddleware returns wrapper around bootstrap service that sends
 // events to event store.
-func NewEventStoreMiddleware(svc bootstrap.Service, client *redis.Client) bootstrap.Service {
+func NewEventStoreMiddleware(svc bootstrap.Service, client redis.UniversalClient) bootstrap.Service {
 	return eventStore{
 		svc:    svc,
 		client: client,
@@ -151,6 +151,22 @@ func (es eventStore) ChangeState(key, id string, state bootstrap.State) error {
 	return nil
 }
 
+func (es eventStore) BulkChangeState(key string, filter bootstrap.Filter, state bootstrap.State) ([]string, error) {
+	ids, err := es.svc.BulkChangeState(key, filter, state)
+
+	for _, id := range ids {
+		ev := changeStateEvent{
+			mfThing:   id,
+			state:     state,
+			timestamp: time.Now(),
+		}
+
+		es.add(ev)
+	}
+
+	return ids, err
+}
+
 func (es eventStore) RemoveConfigHandler(id string) error {
 	return es.svc.RemoveConfigHandler(id)
 }
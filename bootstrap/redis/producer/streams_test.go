@@ -87,7 +87,7 @@ func newThingsService(users mainflux.UsersServiceClient) things.Service {
 }
 
 func newThingsServer(svc things.Service) *httptest.Server {
-	mux := httpapi.MakeHandler(mocktracer.New(), svc)
+	mux := httpapi.MakeHandler(mocktracer.New(), svc, 0)
 	return httptest.NewServer(mux)
 }
 func TestAdd(t *testing.T) {
@@ -546,3 +546,70 @@ func test(t *testing.T, expected, actual map[string]interface{}, description str
 		assert.Equal(t, expected, actual, fmt.Sprintf("%s: expected %v got %v\n", description, expected, actual))
 	}
 }
+
+func TestBulkChangeState(t *testing.T) {
+	redisClient.FlushAll().Err()
+
+	users := mocks.NewUsersService(map[string]string{validToken: email})
+	server := newThingsServer(newThingsService(users))
+	svc := newService(users, server.URL)
+	svc = producer.NewEventStoreMiddleware(svc, redisClient)
+
+	c := config
+
+	saved, err := svc.Add(validToken, c)
+	require.Nil(t, err, fmt.Sprintf("Saving config expected to succeed: %s.\n", err))
+	redisClient.FlushAll().Err()
+
+	cases := []struct {
+		desc   string
+		key    string
+		filter bootstrap.Filter
+		state  bootstrap.State
+		err    error
+		event  map[string]interface{}
+	}{
+		{
+			desc:   "bulk change state to active",
+			key:    validToken,
+			filter: bootstrap.Filter{ExternalIDPrefix: saved.ExternalID},
+			state:  bootstrap.Active,
+			err:    nil,
+			event: map[string]interface{}{
+				"thing_id":  saved.MFThing,
+				"state":     bootstrap.Active.String(),
+				"timestamp": time.Now().Unix(),
+				"operation": thingStateChange,
+			},
+		},
+		{
+			desc:   "bulk change state invalid credentials",
+			key:    "",
+			filter: bootstrap.Filter{ExternalIDPrefix: saved.ExternalID},
+			state:  bootstrap.Inactive,
+			err:    bootstrap.ErrUnauthorizedAccess,
+			event:  nil,
+		},
+	}
+
+	lastID := "0"
+	for _, tc := range cases {
+		_, err := svc.BulkChangeState(tc.key, tc.filter, tc.state)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+
+		streams := redisClient.XRead(&redis.XReadArgs{
+			Streams: []string{streamID, lastID},
+			Count:   1,
+			Block:   time.Second,
+		}).Val()
+
+		var event map[string]interface{}
+		if len(streams) > 0 && len(streams[0].Messages) > 0 {
+			msg := streams[0].Messages[0]
+			event = msg.Values
+			lastID = msg.ID
+		}
+
+		test(t, tc.event, event, tc.desc)
+	}
+}
@@ -11,10 +11,14 @@ import (
 	"errors"
 	"time"
 
+	"github.com/gofrs/uuid"
 	"github.com/mainflux/mainflux"
 	mfsdk "github.com/mainflux/mainflux/sdk/go"
 )
 
+// bulkPageSize is the number of Configs retrieved at once during bulk operations.
+const bulkPageSize uint64 = 100
+
 var (
 	// ErrNotFound indicates a non-existent entity request.
 	ErrNotFound = errors.New("non-existent entity")
@@ -68,6 +72,11 @@ type Service interface {
 	// ChangeState changes state of the Thing with given ID and owner.
 	ChangeState(string, string, State) error
 
+	// BulkChangeState changes state of all the Things of the given owner
+	// that match the filter. IDs of the changed Things are returned, even
+	// if the operation fails part way through.
+	BulkChangeState(string, Filter, State) ([]string, error)
+
 	// Methods RemoveConfig, UpdateChannel, and RemoveChannel are used as
 	// handlers for events. That's why these methods surpass ownership check.
 
@@ -299,6 +308,51 @@ func (bs bootstrapService) ChangeState(key, id string, state State) error {
 		return nil
 	}
 
+	return bs.changeState(key, cfg, state)
+}
+
+func (bs bootstrapService) BulkChangeState(key string, filter Filter, state State) ([]string, error) {
+	owner, err := bs.identify(key)
+	if err != nil {
+		return nil, err
+	}
+
+	// Collect the matching Configs first, since changing the state
+	// may change the result of the filter and shift the pages.
+	var ids []string
+	for offset := uint64(0); ; offset += bulkPageSize {
+		page := bs.configs.RetrieveAll(owner, filter, offset, bulkPageSize)
+		for _, cfg := range page.Configs {
+			if cfg.State != state {
+				ids = append(ids, cfg.MFThing)
+			}
+		}
+
+		if offset+bulkPageSize >= page.Total {
+			break
+		}
+	}
+
+	changed := []string{}
+	for _, id := range ids {
+		cfg, err := bs.configs.RetrieveByID(owner, id)
+		if err != nil {
+			if err == ErrNotFound {
+				continue
+			}
+			return changed, err
+		}
+
+		if err := bs.changeState(key, cfg, state); err != nil {
+			return changed, err
+		}
+		changed = append(changed, id)
+	}
+
+	return changed, nil
+}
+
+func (bs bootstrapService) changeState(key string, cfg Config, state State) error {
 	switch state {
 	case Active:
 		for _, c := range cfg.MFChannels {
@@ -317,7 +371,7 @@ func (bs bootstrapService) ChangeState(key, id string, state State) error {
 		}
 	}
 
-	return bs.configs.ChangeState(owner, id, state)
+	return bs.configs.ChangeState(cfg.Owner, cfg.MFThing, state)
 }
 
 func (bs bootstrapService) UpdateChannelHandler(channel Channel) error {
@@ -349,12 +403,20 @@ func (bs bootstrapService) identify(token string) (string, error) {
 }
 
 // Method thing retrieves Mainflux Thing creating one if an empty ID is passed.
+// Key of the created thing is generated here, since it can't be retrieved if
+// Things service stores thing keys hashed.
 func (bs bootstrapService) thing(key, id string) (mfsdk.Thing, error) {
 	thingID := id
-	var err error
+	var thingKey string
 
 	if id == "" {
-		thingID, err = bs.sdk.CreateThing(mfsdk.Thing{}, key)
+		uid, err := uuid.NewV4()
+		if err != nil {
+			return mfsdk.Thing{}, err
+		}
+		thingKey = uid.String()
+
+		thingID, err = bs.sdk.CreateThing(mfsdk.Thing{Key: thingKey}, key)
 		if err != nil {
 			return mfsdk.Thing{}, err
 		}
@@ -373,6 +435,10 @@ func (bs bootstrapService) thing(key, id string) (mfsdk.Thing, error) {
 		return mfsdk.Thing{}, ErrThings
 	}
 
+	if thingKey != "" {
+		thing.Key = thingKey
+	}
+
 	return thing, nil
 }
 
@@ -79,7 +79,7 @@ func newThingsService(users mainflux.UsersServiceClient) things.Service {
 }
 
 func newThingsServer(svc things.Service) *httptest.Server {
-	mux := httpapi.MakeHandler(mocktracer.New(), svc)
+	mux := httpapi.MakeHandler(mocktracer.New(), svc, 0)
 	return httptest.NewServer(mux)
 }
 
@@ -267,7 +267,7 @@ func TestUpdateCert(t *testing.T) {
 	}{
 		{
 			desc:       "update certs for the valid config",
-			thingKey:   saved.MFKey,
+			thingKey:   saved.MFThing,
 			clientCert: "newCert",
 			clientKey:  "newKey",
 			caCert:     "newCert",
@@ -286,7 +286,7 @@ func TestUpdateCert(t *testing.T) {
 		},
 		{
 			desc:       "update config cert with wrong credentials",
-			thingKey:   saved.MFKey,
+			thingKey:   saved.MFThing,
 			clientCert: "newCert",
 			clientKey:  "newKey",
 			caCert:     "newCert",
@@ -669,6 +669,98 @@ func TestChangeState(t *testing.T) {
 	}
 }
 
+func TestBulkChangeState(t *testing.T) {
+	users := mocks.NewUsersService(map[string]string{validToken: email})
+
+	server := newThingsServer(newThingsService(users))
+	svc := newService(users, server.URL)
+
+	prefixed := 5
+	for i := 0; i < prefixed+2; i++ {
+		c := config
+		c.ExternalID = fmt.Sprintf("bulk-%d", i)
+		if i >= prefixed {
+			c.ExternalID = fmt.Sprintf("other-%d", i)
+		}
+		_, err := svc.Add(validToken, c)
+		require.Nil(t, err, fmt.Sprintf("Saving config expected to succeed: %s.\n", err))
+	}
+
+	active := bootstrap.Active.String()
+
+	cases := []struct {
+		desc    string
+		filter  bootstrap.Filter
+		state   bootstrap.State
+		key     string
+		changed int
+		err     error
+	}{
+		{
+			desc:    "bulk change state with wrong credentials",
+			filter:  bootstrap.Filter{ExternalIDPrefix: "bulk-"},
+			state:   bootstrap.Active,
+			key:     invalidToken,
+			changed: 0,
+			err:     bootstrap.ErrUnauthorizedAccess,
+		},
+		{
+			desc:    "bulk change state by external ID prefix",
+			filter:  bootstrap.Filter{ExternalIDPrefix: "bulk-"},
+			state:   bootstrap.Active,
+			key:     validToken,
+			changed: prefixed,
+			err:     nil,
+		},
+		{
+			desc:    "bulk change state to current state",
+			filter:  bootstrap.Filter{ExternalIDPrefix: "bulk-"},
+			state:   bootstrap.Active,
+			key:     validToken,
+			changed: 0,
+			err:     nil,
+		},
+		{
+			desc:    "bulk change state by state",
+			filter:  bootstrap.Filter{FullMatch: map[string]string{"state": active}},
+			state:   bootstrap.Inactive,
+			key:     validToken,
+			changed: prefixed,
+			err:     nil,
+		},
+		{
+			desc:    "bulk change state by channel",
+			filter:  bootstrap.Filter{Channel: channel.ID},
+			state:   bootstrap.Active,
+			key:     validToken,
+			changed: prefixed + 2,
+			err:     nil,
+		},
+		{
+			desc:    "bulk change state by non-matching metadata",
+			filter:  bootstrap.Filter{Metadata: map[string]interface{}{"meta": "other"}},
+			state:   bootstrap.Inactive,
+			key:     validToken,
+			changed: 0,
+			err:     nil,
+		},
+		{
+			desc:    "bulk change state by metadata",
+			filter:  bootstrap.Filter{Metadata: map[string]interface{}{"meta": "data"}},
+			state:   bootstrap.Inactive,
+			key:     validToken,
+			changed: prefixed + 2,
+			err:     nil,
+		},
+	}
+
+	for _, tc := range cases {
+		ids, err := svc.BulkChangeState(tc.key, tc.filter, tc.state)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.changed, len(ids), fmt.Sprintf("%s: expected %d changed got %d\n", tc.desc, tc.changed, len(ids)))
+	}
+}
+
 func TestUpdateChannelHandler(t *testing.T) {
 	users := mocks.NewUsersService(map[string]string{validToken: email})
 
@@ -0,0 +1,124 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/mainflux/mainflux/certs"
+)
+
+func issueCertEndpoint(svc certs.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(issueCertReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		ttl := time.Duration(req.TTL) * time.Second
+		c, err := svc.IssueCert(ctx, req.token, req.ThingID, ttl)
+		if err != nil {
+			return nil, err
+		}
+
+		res := issueCertRes{
+			viewCertRes: toCertRes(c),
+			PrivateKey:  c.PrivateKey,
+			IssuingCA:   c.IssuingCA,
+		}
+
+		return res, nil
+	}
+}
+
+func viewCertEndpoint(svc certs.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewCertReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		c, err := svc.ViewCert(ctx, req.token, req.serial)
+		if err != nil {
+			return nil, err
+		}
+
+		return toCertRes(c), nil
+	}
+}
+
+func listCertsEndpoint(svc certs.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listCertsReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		page, err := svc.ListCerts(ctx, req.token, req.thingID, req.offset, req.limit)
+		if err != nil {
+			return nil, err
+		}
+
+		res := certsPageRes{
+			Total:  page.Total,
+			Offset: page.Offset,
+			Limit:  page.Limit,
+			Certs:  []viewCertRes{},
+		}
+		for _, c := range page.Certs {
+			res.Certs = append(res.Certs, toCertRes(c))
+		}
+
+		return res, nil
+	}
+}
+
+func revokeCertEndpoint(svc certs.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewCertReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RevokeCert(ctx, req.token, req.serial); err != nil {
+			return nil, err
+		}
+
+		return revokeRes{}, nil
+	}
+}
+
+func statusEndpoint(svc certs.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(statusReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		status, err := svc.Status(ctx, req.serial)
+		if err != nil {
+			return nil, err
+		}
+
+		return statusRes{Serial: req.serial, Status: status}, nil
+	}
+}
+
+func crlEndpoint(svc certs.Service) endpoint.Endpoint {
+	return func(ctx context.Context, _ interface{}) (interface{}, error) {
+		crl, err := svc.CRL(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return crlRes{crl: crl}, nil
+	}
+}
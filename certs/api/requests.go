@@ -0,0 +1,78 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import "github.com/mainflux/mainflux/certs"
+
+const maxLimitSize = 100
+
+type apiReq interface {
+	validate() error
+}
+
+type issueCertReq struct {
+	token   string
+	ThingID string `json:"thing_id"`
+	TTL     uint64 `json:"ttl,omitempty"`
+}
+
+func (req issueCertReq) validate() error {
+	if req.token == "" {
+		return certs.ErrUnauthorizedAccess
+	}
+
+	if req.ThingID == "" {
+		return certs.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type viewCertReq struct {
+	token  string
+	serial string
+}
+
+func (req viewCertReq) validate() error {
+	if req.token == "" {
+		return certs.ErrUnauthorizedAccess
+	}
+
+	if req.serial == "" {
+		return certs.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type listCertsReq struct {
+	token   string
+	thingID string
+	offset  uint64
+	limit   uint64
+}
+
+func (req listCertsReq) validate() error {
+	if req.token == "" {
+		return certs.ErrUnauthorizedAccess
+	}
+
+	if req.limit == 0 || req.limit > maxLimitSize {
+		return certs.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type statusReq struct {
+	serial string
+}
+
+func (req statusReq) validate() error {
+	if req.serial == "" {
+		return certs.ErrMalformedEntity
+	}
+
+	return nil
+}
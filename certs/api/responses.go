@@ -0,0 +1,134 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/certs"
+)
+
+var (
+	_ mainflux.Response = (*issueCertRes)(nil)
+	_ mainflux.Response = (*viewCertRes)(nil)
+	_ mainflux.Response = (*certsPageRes)(nil)
+	_ mainflux.Response = (*revokeRes)(nil)
+	_ mainflux.Response = (*statusRes)(nil)
+)
+
+type issueCertRes struct {
+	viewCertRes
+	PrivateKey string `json:"private_key"`
+	IssuingCA  string `json:"issuing_ca"`
+}
+
+func (res issueCertRes) Code() int {
+	return http.StatusCreated
+}
+
+func (res issueCertRes) Headers() map[string]string {
+	return map[string]string{
+		"Location": fmt.Sprintf("/certs/%s", res.Serial),
+	}
+}
+
+func (res issueCertRes) Empty() bool {
+	return false
+}
+
+type viewCertRes struct {
+	Serial      string     `json:"serial"`
+	ThingID     string     `json:"thing_id"`
+	Certificate string     `json:"certificate"`
+	Expires     time.Time  `json:"expires"`
+	Revoked     bool       `json:"revoked"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty"`
+}
+
+func (res viewCertRes) Code() int {
+	return http.StatusOK
+}
+
+func (res viewCertRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res viewCertRes) Empty() bool {
+	return false
+}
+
+func toCertRes(c certs.Cert) viewCertRes {
+	res := viewCertRes{
+		Serial:      c.Serial,
+		ThingID:     c.ThingID,
+		Certificate: c.Certificate,
+		Expires:     c.Expires,
+		Revoked:     c.Revoked,
+	}
+
+	if c.Revoked {
+		at := c.RevokedAt
+		res.RevokedAt = &at
+	}
+
+	return res
+}
+
+type certsPageRes struct {
+	Total  uint64        `json:"total"`
+	Offset uint64        `json:"offset"`
+	Limit  uint64        `json:"limit"`
+	Certs  []viewCertRes `json:"certs"`
+}
+
+func (res certsPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res certsPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res certsPageRes) Empty() bool {
+	return false
+}
+
+type revokeRes struct{}
+
+func (res revokeRes) Code() int {
+	return http.StatusNoContent
+}
+
+func (res revokeRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res revokeRes) Empty() bool {
+	return true
+}
+
+type statusRes struct {
+	Serial string `json:"serial"`
+	Status string `json:"status"`
+}
+
+func (res statusRes) Code() int {
+	return http.StatusOK
+}
+
+func (res statusRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res statusRes) Empty() bool {
+	return false
+}
+
+// crlRes isn't encoded as JSON, since servers expect the plain PEM.
+type crlRes struct {
+	crl []byte
+}
@@ -0,0 +1,238 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/go-zoo/bone"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/certs"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	contentType = "application/json"
+	crlType     = "application/x-pem-file"
+	offset      = "offset"
+	limit       = "limit"
+	thing       = "thing"
+
+	defOffset = 0
+	defLimit  = 10
+)
+
+var (
+	errUnsupportedContentType = errors.New("unsupported content type")
+	errInvalidQueryParams     = errors.New("invalid query params")
+)
+
+// MakeHandler returns a HTTP handler for API endpoints.
+func MakeHandler(svc certs.Service) http.Handler {
+	opts := []kithttp.ServerOption{
+		kithttp.ServerErrorEncoder(encodeError),
+	}
+
+	r := bone.New()
+
+	r.Post("/certs", kithttp.NewServer(
+		issueCertEndpoint(svc),
+		decodeIssueCert,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/certs/:serial/status", kithttp.NewServer(
+		statusEndpoint(svc),
+		decodeStatus,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/certs/:serial", kithttp.NewServer(
+		viewCertEndpoint(svc),
+		decodeView,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Delete("/certs/:serial", kithttp.NewServer(
+		revokeCertEndpoint(svc),
+		decodeView,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/certs", kithttp.NewServer(
+		listCertsEndpoint(svc),
+		decodeListCerts,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/crl", kithttp.NewServer(
+		crlEndpoint(svc),
+		decodeCRL,
+		encodeCRL,
+		opts...,
+	))
+
+	r.GetFunc("/version", mainflux.Version("certs"))
+	r.GetFunc(mainflux.DiscoveryPath, mainflux.Discovery("certs", mainflux.Capabilities{
+		ContentTypes: []string{contentType},
+		Limits:       mainflux.Limits{MaxPageSize: maxLimitSize},
+	}))
+	r.Handle("/metrics", promhttp.Handler())
+
+	return r
+}
+
+func decodeIssueCert(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+
+	req := issueCertReq{token: r.Header.Get("Authorization")}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func decodeView(_ context.Context, r *http.Request) (interface{}, error) {
+	req := viewCertReq{
+		token:  r.Header.Get("Authorization"),
+		serial: bone.GetValue(r, "serial"),
+	}
+
+	return req, nil
+}
+
+func decodeListCerts(_ context.Context, r *http.Request) (interface{}, error) {
+	o, err := readUintQuery(r, offset, defOffset)
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := readUintQuery(r, limit, defLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := readStringQuery(r, thing)
+	if err != nil {
+		return nil, err
+	}
+
+	req := listCertsReq{
+		token:   r.Header.Get("Authorization"),
+		thingID: t,
+		offset:  o,
+		limit:   l,
+	}
+
+	return req, nil
+}
+
+func decodeStatus(_ context.Context, r *http.Request) (interface{}, error) {
+	return statusReq{serial: bone.GetValue(r, "serial")}, nil
+}
+
+func decodeCRL(_ context.Context, _ *http.Request) (interface{}, error) {
+	return nil, nil
+}
+
+func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", contentType)
+
+	if ar, ok := response.(mainflux.Response); ok {
+		for k, v := range ar.Headers() {
+			w.Header().Set(k, v)
+		}
+
+		w.WriteHeader(ar.Code())
+
+		if ar.Empty() {
+			return nil
+		}
+	}
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+func encodeCRL(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", crlType)
+
+	_, err := w.Write(response.(crlRes).crl)
+	return err
+}
+
+func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+	w.Header().Set("Content-Type", contentType)
+
+	switch err {
+	case certs.ErrMalformedEntity:
+		w.WriteHeader(http.StatusBadRequest)
+	case certs.ErrUnauthorizedAccess:
+		w.WriteHeader(http.StatusForbidden)
+	case certs.ErrNotFound:
+		w.WriteHeader(http.StatusNotFound)
+	case errUnsupportedContentType:
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+	case errInvalidQueryParams:
+		w.WriteHeader(http.StatusBadRequest)
+	case io.ErrUnexpectedEOF:
+		w.WriteHeader(http.StatusBadRequest)
+	case io.EOF:
+		w.WriteHeader(http.StatusBadRequest)
+	default:
+		switch err.(type) {
+		case *json.SyntaxError:
+			w.WriteHeader(http.StatusBadRequest)
+		case *json.UnmarshalTypeError:
+			w.WriteHeader(http.StatusBadRequest)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}
+}
+
+func readUintQuery(r *http.Request, key string, def uint64) (uint64, error) {
+	vals := bone.GetQuery(r, key)
+	if len(vals) > 1 {
+		return 0, errInvalidQueryParams
+	}
+
+	if len(vals) == 0 {
+		return def, nil
+	}
+
+	val, err := strconv.ParseUint(vals[0], 10, 64)
+	if err != nil {
+		return 0, errInvalidQueryParams
+	}
+
+	return val, nil
+}
+
+func readStringQuery(r *http.Request, key string) (string, error) {
+	vals := bone.GetQuery(r, key)
+	if len(vals) > 1 {
+		return "", errInvalidQueryParams
+	}
+
+	if len(vals) == 0 {
+		return "", nil
+	}
+
+	return vals[0], nil
+}
@@ -0,0 +1,119 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package certs
+
+import (
+	"context"
+	"time"
+)
+
+// Supported certificate statuses.
+const (
+	// Good status indicates that the certificate is valid.
+	Good = "good"
+
+	// Revoked status indicates that the certificate has been revoked.
+	Revoked = "revoked"
+
+	// Expired status indicates that the certificate validity period is over.
+	Expired = "expired"
+
+	// Unknown status indicates that the certificate wasn't issued by the
+	// service.
+	Unknown = "unknown"
+)
+
+// Cert represents the client certificate issued to the thing. The common
+// name of the certificate is the thing ID.
+type Cert struct {
+	Serial      string
+	ThingID     string
+	Owner       string
+	Certificate string
+	Expires     time.Time
+	Revoked     bool
+	RevokedAt   time.Time
+
+	// PrivateKey and IssuingCA are PEM encoded and set only when the
+	// certificate is issued. Private key is never persisted, so it can't be
+	// retrieved afterwards.
+	PrivateKey string
+	IssuingCA  string
+}
+
+// CertsPage contains page related metadata as well as list of certificates
+// that belong to this page.
+type CertsPage struct {
+	Total  uint64
+	Offset uint64
+	Limit  uint64
+	Certs  []Cert
+}
+
+// Config contains the validity periods of the issued certificates.
+type Config struct {
+	// TTL is used unless the validity period is provided on issuance.
+	TTL time.Duration
+
+	// MaxTTL is the longest validity period which can be requested.
+	MaxTTL time.Duration
+}
+
+// Status returns the status of the certificate at the provided moment.
+func (c Cert) Status(at time.Time) string {
+	switch {
+	case c.Revoked:
+		return Revoked
+	case at.After(c.Expires):
+		return Expired
+	default:
+		return Good
+	}
+}
+
+// CertRepository specifies a certificate persistence API.
+type CertRepository interface {
+	// Save persists the certificate. A non-nil error is returned to indicate
+	// operation failure.
+	Save(context.Context, Cert) error
+
+	// RetrieveBySerial retrieves the certificate having the provided serial
+	// number.
+	RetrieveBySerial(context.Context, string) (Cert, error)
+
+	// RetrieveByOwner retrieves the subset of certificates owned by the
+	// specified user, optionally limited to the ones issued to the thing.
+	RetrieveByOwner(ctx context.Context, owner, thingID string, offset, limit uint64) (CertsPage, error)
+
+	// RetrieveRevoked retrieves all the revoked certificates which haven't
+	// expired yet.
+	RetrieveRevoked(context.Context) ([]Cert, error)
+
+	// Revoke marks the certificate having the provided serial number as
+	// revoked at the provided moment.
+	Revoke(context.Context, string, time.Time) error
+}
+
+// CA specifies an API of the certificate authority signing the client
+// certificates.
+type CA interface {
+	// Issue generates the key pair and issues the certificate to the thing,
+	// valid for the provided period.
+	Issue(ctx context.Context, thingID string, ttl time.Duration) (Cert, error)
+
+	// Revoke notifies the CA that the certificate has been revoked.
+	Revoke(ctx context.Context, serial string) error
+
+	// CRL returns the PEM encoded certificate revocation list. CA which
+	// doesn't track the revocations itself lists the provided certificates.
+	CRL(ctx context.Context, revoked []Cert) ([]byte, error)
+}
+
+// Things specifies an API for checking the thing ownership, so that the
+// users can't obtain the certificates of the other users things.
+type Things interface {
+	// Authorize returns ErrNotFound if the thing doesn't exist or isn't
+	// owned by the user identified by the provided key.
+	Authorize(token, thingID string) error
+}
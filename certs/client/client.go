@@ -0,0 +1,66 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package client contains the client of the certs service status endpoint,
+// used by the adapters verifying the client certificates.
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Client checks the status of the certificates issued by the certs service.
+type Client interface {
+	// Status returns the status of the certificate having the provided
+	// serial number.
+	Status(ctx context.Context, serial string) (string, error)
+}
+
+type statusRes struct {
+	Status string `json:"status"`
+}
+
+type client struct {
+	url  string
+	http *http.Client
+}
+
+// New returns the client of the certs service listening on the provided base
+// URL.
+func New(baseURL string, timeout time.Duration) Client {
+	return client{
+		url:  strings.TrimSuffix(baseURL, "/"),
+		http: &http.Client{Timeout: timeout},
+	}
+}
+
+func (c client) Status(ctx context.Context, serial string) (string, error) {
+	u := fmt.Sprintf("%s/certs/%s/status", c.url, url.PathEscape(serial))
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := c.http.Do(req.WithContext(ctx))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("certs service responded with status %d", resp.StatusCode)
+	}
+
+	var res statusRes
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return "", err
+	}
+
+	return res.Status, nil
+}
@@ -0,0 +1,10 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package certs contains the domain concept definitions needed to support
+// Mainflux certs service functionality. Certs service issues x.509 client
+// certificates bound to the thing IDs, which the devices use to authenticate
+// over mutual TLS instead of the thing keys. Certificates are signed either by
+// the embedded CA or by the Vault PKI secrets engine, and can be revoked at
+// any time.
+package certs
@@ -0,0 +1,62 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mainflux/mainflux/certs"
+)
+
+var _ certs.CA = (*caMock)(nil)
+
+type caMock struct {
+	mu      sync.Mutex
+	counter int
+	revoked map[string]bool
+}
+
+// NewCA creates mock of CA which issues placeholder certificates having
+// sequential serial numbers, and lists the revoked serials as the CRL.
+func NewCA() certs.CA {
+	return &caMock{revoked: make(map[string]bool)}
+}
+
+func (ca *caMock) Issue(_ context.Context, thingID string, ttl time.Duration) (certs.Cert, error) {
+	ca.mu.Lock()
+	defer ca.mu.Unlock()
+
+	ca.counter++
+	cert := certs.Cert{
+		Serial:      fmt.Sprintf("00:%02x", ca.counter),
+		ThingID:     thingID,
+		Certificate: fmt.Sprintf("cert-%s", thingID),
+		PrivateKey:  fmt.Sprintf("key-%s", thingID),
+		IssuingCA:   "ca",
+		Expires:     time.Now().Add(ttl).UTC(),
+	}
+
+	return cert, nil
+}
+
+func (ca *caMock) Revoke(_ context.Context, serial string) error {
+	ca.mu.Lock()
+	defer ca.mu.Unlock()
+
+	ca.revoked[serial] = true
+	return nil
+}
+
+func (ca *caMock) CRL(_ context.Context, revoked []certs.Cert) ([]byte, error) {
+	serials := []string{}
+	for _, c := range revoked {
+		serials = append(serials, c.Serial)
+	}
+
+	return []byte(strings.Join(serials, "\n")), nil
+}
@@ -0,0 +1,120 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mainflux/mainflux/certs"
+)
+
+var _ certs.CertRepository = (*certRepositoryMock)(nil)
+
+type certRepositoryMock struct {
+	mu    sync.Mutex
+	certs map[string]certs.Cert
+}
+
+// NewCertRepository creates in-memory certificate repository.
+func NewCertRepository() certs.CertRepository {
+	return &certRepositoryMock{
+		certs: make(map[string]certs.Cert),
+	}
+}
+
+func (crm *certRepositoryMock) Save(_ context.Context, c certs.Cert) error {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	if _, ok := crm.certs[c.Serial]; ok {
+		return certs.ErrMalformedEntity
+	}
+
+	c.PrivateKey = ""
+	c.IssuingCA = ""
+	crm.certs[c.Serial] = c
+	return nil
+}
+
+func (crm *certRepositoryMock) RetrieveBySerial(_ context.Context, serial string) (certs.Cert, error) {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	c, ok := crm.certs[serial]
+	if !ok {
+		return certs.Cert{}, certs.ErrNotFound
+	}
+
+	return c, nil
+}
+
+func (crm *certRepositoryMock) RetrieveByOwner(_ context.Context, owner, thingID string, offset, limit uint64) (certs.CertsPage, error) {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	items := crm.filter(func(c certs.Cert) bool {
+		return c.Owner == owner && (thingID == "" || c.ThingID == thingID)
+	})
+	page := certs.CertsPage{
+		Total:  uint64(len(items)),
+		Offset: offset,
+		Limit:  limit,
+		Certs:  []certs.Cert{},
+	}
+
+	if offset >= uint64(len(items)) {
+		return page, nil
+	}
+
+	end := offset + limit
+	if end > uint64(len(items)) {
+		end = uint64(len(items))
+	}
+	page.Certs = items[offset:end]
+
+	return page, nil
+}
+
+func (crm *certRepositoryMock) RetrieveRevoked(_ context.Context) ([]certs.Cert, error) {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	now := time.Now()
+	return crm.filter(func(c certs.Cert) bool {
+		return c.Revoked && c.Expires.After(now)
+	}), nil
+}
+
+func (crm *certRepositoryMock) Revoke(_ context.Context, serial string, at time.Time) error {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	c, ok := crm.certs[serial]
+	if !ok {
+		return certs.ErrNotFound
+	}
+
+	c.Revoked = true
+	c.RevokedAt = at
+	crm.certs[serial] = c
+	return nil
+}
+
+func (crm *certRepositoryMock) filter(match func(certs.Cert) bool) []certs.Cert {
+	items := []certs.Cert{}
+	for _, c := range crm.certs {
+		if match(c) {
+			items = append(items, c)
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Serial < items[j].Serial
+	})
+
+	return items
+}
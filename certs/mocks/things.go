@@ -0,0 +1,26 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import "github.com/mainflux/mainflux/certs"
+
+var _ certs.Things = (*thingsMock)(nil)
+
+type thingsMock struct {
+	things map[string]string
+}
+
+// NewThings creates mock of things API. Things are mapped to the keys of the
+// users owning them.
+func NewThings(things map[string]string) certs.Things {
+	return thingsMock{things}
+}
+
+func (tm thingsMock) Authorize(token, thingID string) error {
+	if tm.things[thingID] != token {
+		return certs.ErrNotFound
+	}
+
+	return nil
+}
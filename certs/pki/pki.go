@@ -0,0 +1,186 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package pki contains the certificate authority embedded in the certs
+// service, which signs the client certificates using the configured CA
+// certificate and key.
+package pki
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/mainflux/mainflux/certs"
+)
+
+const (
+	serialBits = 128
+
+	// Certificates are backdated, so that they are accepted by the servers
+	// having their clocks slightly behind.
+	backdate = time.Minute
+
+	certType = "CERTIFICATE"
+	keyType  = "EC PRIVATE KEY"
+	crlType  = "X509 CRL"
+)
+
+var (
+	errInvalidKey    = errors.New("CA key can't be used for signing")
+	errInvalidSerial = errors.New("invalid certificate serial number")
+)
+
+var _ certs.CA = (*ca)(nil)
+
+type ca struct {
+	cert   *x509.Certificate
+	key    crypto.Signer
+	crlTTL time.Duration
+}
+
+// New returns the CA signing the certificates using the provided CA
+// certificate and key. Revocation lists are valid for the provided period,
+// so they have to be fetched more often than that.
+func New(cert *x509.Certificate, key crypto.Signer, crlTTL time.Duration) certs.CA {
+	return ca{
+		cert:   cert,
+		key:    key,
+		crlTTL: crlTTL,
+	}
+}
+
+// Load reads the PEM encoded CA certificate and key from the provided files
+// and returns the CA using them.
+func Load(certFile, keyFile string, crlTTL time.Duration) (certs.CA, error) {
+	pair, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	cert, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return nil, err
+	}
+
+	key, ok := pair.PrivateKey.(crypto.Signer)
+	if !ok {
+		return nil, errInvalidKey
+	}
+
+	return New(cert, key, crlTTL), nil
+}
+
+func (c ca) Issue(_ context.Context, thingID string, ttl time.Duration) (certs.Cert, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return certs.Cert{}, err
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), serialBits))
+	if err != nil {
+		return certs.Cert{}, err
+	}
+
+	now := time.Now()
+	tmpl := x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: thingID},
+		NotBefore:    now.Add(-backdate),
+		NotAfter:     now.Add(ttl),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, &tmpl, c.cert, key.Public(), c.key)
+	if err != nil {
+		return certs.Cert{}, err
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return certs.Cert{}, err
+	}
+
+	cert := certs.Cert{
+		Serial:      FormatSerial(serial),
+		ThingID:     thingID,
+		Certificate: encode(certType, der),
+		PrivateKey:  encode(keyType, keyDER),
+		IssuingCA:   encode(certType, c.cert.Raw),
+		Expires:     tmpl.NotAfter.UTC(),
+	}
+
+	return cert, nil
+}
+
+func (c ca) Revoke(context.Context, string) error {
+	return nil
+}
+
+func (c ca) CRL(_ context.Context, revoked []certs.Cert) ([]byte, error) {
+	entries := []x509.RevocationListEntry{}
+	for _, cert := range revoked {
+		serial, err := ParseSerial(cert.Serial)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, x509.RevocationListEntry{
+			SerialNumber:   serial,
+			RevocationTime: cert.RevokedAt,
+		})
+	}
+
+	now := time.Now()
+	tmpl := x509.RevocationList{
+		RevokedCertificateEntries: entries,
+		Number:                    big.NewInt(now.Unix()),
+		ThisUpdate:                now,
+		NextUpdate:                now.Add(c.crlTTL),
+	}
+
+	der, err := x509.CreateRevocationList(rand.Reader, &tmpl, c.cert, c.key)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(encode(crlType, der)), nil
+}
+
+// FormatSerial returns the serial number as colon separated hex bytes, which
+// is the format used by Vault.
+func FormatSerial(serial *big.Int) string {
+	b := serial.Bytes()
+	parts := make([]string, len(b))
+	for i := range b {
+		parts[i] = hex.EncodeToString(b[i : i+1])
+	}
+
+	return strings.Join(parts, ":")
+}
+
+// ParseSerial parses the serial number formatted by FormatSerial.
+func ParseSerial(serial string) (*big.Int, error) {
+	n, ok := new(big.Int).SetString(strings.ReplaceAll(serial, ":", ""), 16)
+	if !ok {
+		return nil, errInvalidSerial
+	}
+
+	return n, nil
+}
+
+func encode(typ string, der []byte) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der}))
+}
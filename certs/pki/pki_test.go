@@ -0,0 +1,112 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package pki_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/certs"
+	"github.com/mainflux/mainflux/certs/pki"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const thingID = "513d02d2-16c1-4f23-98be-9e12f8fee898"
+
+func newCA(t *testing.T) (certs.CA, *x509.Certificate) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	tmpl := x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Mainflux CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &tmpl, &tmpl, key.Public(), key)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	cert, err := x509.ParseCertificate(der)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	return pki.New(cert, key, time.Hour), cert
+}
+
+func TestIssue(t *testing.T) {
+	ca, caCert := newCA(t)
+
+	c, err := ca.Issue(context.Background(), thingID, time.Hour)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	block, _ := pem.Decode([]byte(c.Certificate))
+	require.NotNil(t, block, "expected PEM encoded certificate")
+	cert, err := x509.ParseCertificate(block.Bytes)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	assert.Equal(t, thingID, cert.Subject.CommonName, fmt.Sprintf("expected common name %s got %s", thingID, cert.Subject.CommonName))
+	assert.Equal(t, c.Serial, pki.FormatSerial(cert.SerialNumber), fmt.Sprintf("expected serial %s got %s", c.Serial, pki.FormatSerial(cert.SerialNumber)))
+	assert.Nil(t, cert.CheckSignatureFrom(caCert), "expected certificate to be signed by CA")
+
+	pool := x509.NewCertPool()
+	pool.AddCert(caCert)
+	_, err = cert.Verify(x509.VerifyOptions{Roots: pool, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}})
+	assert.Nil(t, err, fmt.Sprintf("expected client certificate to be valid: %s", err))
+
+	block, _ = pem.Decode([]byte(c.PrivateKey))
+	require.NotNil(t, block, "expected PEM encoded private key")
+	_, err = x509.ParseECPrivateKey(block.Bytes)
+	assert.Nil(t, err, fmt.Sprintf("expected valid private key: %s", err))
+}
+
+func TestCRL(t *testing.T) {
+	ca, caCert := newCA(t)
+
+	c, err := ca.Issue(context.Background(), thingID, time.Hour)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	c.Revoked = true
+	c.RevokedAt = time.Now()
+
+	data, err := ca.CRL(context.Background(), []certs.Cert{c})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	block, _ := pem.Decode(data)
+	require.NotNil(t, block, "expected PEM encoded CRL")
+	crl, err := x509.ParseRevocationList(block.Bytes)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Nil(t, crl.CheckSignatureFrom(caCert), "expected CRL to be signed by CA")
+	require.Len(t, crl.RevokedCertificateEntries, 1, "expected one revoked certificate")
+
+	serial := pki.FormatSerial(crl.RevokedCertificateEntries[0].SerialNumber)
+	assert.Equal(t, c.Serial, serial, fmt.Sprintf("expected revoked serial %s got %s", c.Serial, serial))
+}
+
+func TestParseSerial(t *testing.T) {
+	cases := map[string]struct {
+		serial string
+		valid  bool
+	}{
+		"parse valid serial":   {serial: "1f:a0:03", valid: true},
+		"parse invalid serial": {serial: "zz:01", valid: false},
+	}
+
+	for desc, tc := range cases {
+		n, err := pki.ParseSerial(tc.serial)
+		assert.Equal(t, tc.valid, err == nil, fmt.Sprintf("%s: unexpected error: %v", desc, err))
+		if err == nil {
+			assert.Equal(t, tc.serial, pki.FormatSerial(n), fmt.Sprintf("%s: expected %s got %s", desc, tc.serial, pki.FormatSerial(n)))
+		}
+	}
+}
@@ -0,0 +1,177 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq" // required for DB access
+	"github.com/mainflux/mainflux/certs"
+)
+
+const errDuplicate = "unique_violation"
+
+var _ certs.CertRepository = (*certRepository)(nil)
+
+type certRepository struct {
+	db *sqlx.DB
+}
+
+// NewCertRepository instantiates a PostgreSQL implementation of certificate
+// repository.
+func NewCertRepository(db *sqlx.DB) certs.CertRepository {
+	return &certRepository{
+		db: db,
+	}
+}
+
+func (cr certRepository) Save(ctx context.Context, c certs.Cert) error {
+	q := `INSERT INTO certs (serial, thing_id, owner, certificate, expires_at, revoked, revoked_at)
+	      VALUES (:serial, :thing_id, :owner, :certificate, :expires_at, :revoked, :revoked_at);`
+
+	if _, err := cr.db.NamedExecContext(ctx, q, toDBCert(c)); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == errDuplicate {
+			return certs.ErrMalformedEntity
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (cr certRepository) RetrieveBySerial(ctx context.Context, serial string) (certs.Cert, error) {
+	q := `SELECT serial, thing_id, owner, certificate, expires_at, revoked, revoked_at
+	      FROM certs WHERE serial = $1;`
+
+	var dbc dbCert
+	if err := cr.db.QueryRowxContext(ctx, q, serial).StructScan(&dbc); err != nil {
+		if err == sql.ErrNoRows {
+			return certs.Cert{}, certs.ErrNotFound
+		}
+		return certs.Cert{}, err
+	}
+
+	return toCert(dbc), nil
+}
+
+func (cr certRepository) RetrieveByOwner(ctx context.Context, owner, thingID string, offset, limit uint64) (certs.CertsPage, error) {
+	q := `SELECT serial, thing_id, owner, certificate, expires_at, revoked, revoked_at
+	      FROM certs WHERE owner = $1 AND ($2 = '' OR thing_id = $2)
+	      ORDER BY serial LIMIT $3 OFFSET $4;`
+
+	items, err := cr.retrieve(ctx, q, owner, thingID, limit, offset)
+	if err != nil {
+		return certs.CertsPage{}, err
+	}
+
+	cq := `SELECT COUNT(*) FROM certs WHERE owner = $1 AND ($2 = '' OR thing_id = $2);`
+
+	var total uint64
+	if err := cr.db.GetContext(ctx, &total, cq, owner, thingID); err != nil {
+		return certs.CertsPage{}, err
+	}
+
+	page := certs.CertsPage{
+		Total:  total,
+		Offset: offset,
+		Limit:  limit,
+		Certs:  items,
+	}
+
+	return page, nil
+}
+
+func (cr certRepository) RetrieveRevoked(ctx context.Context) ([]certs.Cert, error) {
+	q := `SELECT serial, thing_id, owner, certificate, expires_at, revoked, revoked_at
+	      FROM certs WHERE revoked AND expires_at > $1 ORDER BY revoked_at;`
+
+	return cr.retrieve(ctx, q, time.Now().UTC())
+}
+
+func (cr certRepository) Revoke(ctx context.Context, serial string, at time.Time) error {
+	q := `UPDATE certs SET revoked = TRUE, revoked_at = $2 WHERE serial = $1;`
+
+	res, err := cr.db.ExecContext(ctx, q, serial, at.UTC())
+	if err != nil {
+		return err
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if cnt == 0 {
+		return certs.ErrNotFound
+	}
+
+	return nil
+}
+
+func (cr certRepository) retrieve(ctx context.Context, q string, args ...interface{}) ([]certs.Cert, error) {
+	rows, err := cr.db.QueryxContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []certs.Cert{}
+	for rows.Next() {
+		var dbc dbCert
+		if err := rows.StructScan(&dbc); err != nil {
+			return nil, err
+		}
+		items = append(items, toCert(dbc))
+	}
+
+	return items, rows.Err()
+}
+
+type dbCert struct {
+	Serial      string     `db:"serial"`
+	ThingID     string     `db:"thing_id"`
+	Owner       string     `db:"owner"`
+	Certificate string     `db:"certificate"`
+	ExpiresAt   time.Time  `db:"expires_at"`
+	Revoked     bool       `db:"revoked"`
+	RevokedAt   *time.Time `db:"revoked_at"`
+}
+
+func toDBCert(c certs.Cert) dbCert {
+	dbc := dbCert{
+		Serial:      c.Serial,
+		ThingID:     c.ThingID,
+		Owner:       c.Owner,
+		Certificate: c.Certificate,
+		ExpiresAt:   c.Expires.UTC(),
+		Revoked:     c.Revoked,
+	}
+
+	if c.Revoked {
+		at := c.RevokedAt.UTC()
+		dbc.RevokedAt = &at
+	}
+
+	return dbc
+}
+
+func toCert(dbc dbCert) certs.Cert {
+	c := certs.Cert{
+		Serial:      dbc.Serial,
+		ThingID:     dbc.ThingID,
+		Owner:       dbc.Owner,
+		Certificate: dbc.Certificate,
+		Expires:     dbc.ExpiresAt.UTC(),
+		Revoked:     dbc.Revoked,
+	}
+
+	if dbc.RevokedAt != nil {
+		c.RevokedAt = dbc.RevokedAt.UTC()
+	}
+
+	return c
+}
@@ -0,0 +1,146 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/certs"
+	"github.com/mainflux/mainflux/certs/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	serial      = "00:01"
+	otherSerial = "00:02"
+	wrongSerial = "00:99"
+	owner       = "user@example.com"
+	thingID     = "1"
+	otherThing  = "2"
+)
+
+func newCert(serial, thingID string) certs.Cert {
+	return certs.Cert{
+		Serial:      serial,
+		ThingID:     thingID,
+		Owner:       owner,
+		Certificate: "certificate",
+		Expires:     time.Now().Add(time.Hour).UTC().Truncate(time.Millisecond),
+	}
+}
+
+func TestCertSave(t *testing.T) {
+	repo := postgres.NewCertRepository(db)
+	c := newCert(serial, thingID)
+
+	cases := []struct {
+		desc string
+		cert certs.Cert
+		err  error
+	}{
+		{
+			desc: "save new certificate",
+			cert: c,
+			err:  nil,
+		},
+		{
+			desc: "save duplicate certificate",
+			cert: c,
+			err:  certs.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		err := repo.Save(context.Background(), tc.cert)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	db.Exec("DELETE FROM certs")
+}
+
+func TestCertRetrieveBySerial(t *testing.T) {
+	repo := postgres.NewCertRepository(db)
+	c := newCert(serial, thingID)
+	err := repo.Save(context.Background(), c)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	defer db.Exec("DELETE FROM certs")
+
+	cases := map[string]struct {
+		serial string
+		err    error
+	}{
+		"retrieve existing certificate":     {serial: serial, err: nil},
+		"retrieve non-existing certificate": {serial: wrongSerial, err: certs.ErrNotFound},
+	}
+
+	for desc, tc := range cases {
+		saved, err := repo.RetrieveBySerial(context.Background(), tc.serial)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+		if err == nil {
+			assert.Equal(t, c, saved, fmt.Sprintf("%s: expected %v got %v\n", desc, c, saved))
+		}
+	}
+}
+
+func TestCertRetrieveByOwner(t *testing.T) {
+	repo := postgres.NewCertRepository(db)
+	for _, c := range []certs.Cert{newCert(serial, thingID), newCert(otherSerial, otherThing)} {
+		err := repo.Save(context.Background(), c)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+	defer db.Exec("DELETE FROM certs")
+
+	cases := map[string]struct {
+		owner   string
+		thingID string
+		offset  uint64
+		limit   uint64
+		size    int
+		total   uint64
+	}{
+		"retrieve all certificates":           {owner: owner, offset: 0, limit: 10, size: 2, total: 2},
+		"retrieve certificates of the thing":  {owner: owner, thingID: otherThing, offset: 0, limit: 10, size: 1, total: 1},
+		"retrieve certificates with offset":   {owner: owner, offset: 1, limit: 10, size: 1, total: 2},
+		"retrieve certificates of other user": {owner: "other@example.com", offset: 0, limit: 10, size: 0, total: 0},
+	}
+
+	for desc, tc := range cases {
+		page, err := repo.RetrieveByOwner(context.Background(), tc.owner, tc.thingID, tc.offset, tc.limit)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", desc, err))
+		assert.Equal(t, tc.size, len(page.Certs), fmt.Sprintf("%s: expected %d got %d\n", desc, tc.size, len(page.Certs)))
+		assert.Equal(t, tc.total, page.Total, fmt.Sprintf("%s: expected total %d got %d\n", desc, tc.total, page.Total))
+	}
+}
+
+func TestCertRevoke(t *testing.T) {
+	repo := postgres.NewCertRepository(db)
+	for _, c := range []certs.Cert{newCert(serial, thingID), newCert(otherSerial, otherThing)} {
+		err := repo.Save(context.Background(), c)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+	defer db.Exec("DELETE FROM certs")
+
+	cases := map[string]struct {
+		serial string
+		err    error
+	}{
+		"revoke existing certificate":     {serial: serial, err: nil},
+		"revoke non-existing certificate": {serial: wrongSerial, err: certs.ErrNotFound},
+	}
+
+	for desc, tc := range cases {
+		err := repo.Revoke(context.Background(), tc.serial, time.Now())
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+
+	revoked, err := repo.RetrieveRevoked(context.Background())
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	require.Len(t, revoked, 1, "expected one revoked certificate")
+	assert.Equal(t, serial, revoked[0].Serial, fmt.Sprintf("expected revoked %s got %s\n", serial, revoked[0].Serial))
+	assert.True(t, revoked[0].Revoked, "expected certificate to be revoked")
+}
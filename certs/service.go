@@ -0,0 +1,180 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package certs
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/mainflux/mainflux"
+)
+
+var (
+	// ErrMalformedEntity indicates malformed entity specification.
+	ErrMalformedEntity = errors.New("malformed entity specification")
+
+	// ErrUnauthorizedAccess indicates missing or invalid credentials provided
+	// when accessing a protected resource.
+	ErrUnauthorizedAccess = errors.New("missing or invalid credentials provided")
+
+	// ErrNotFound indicates a non-existent entity request.
+	ErrNotFound = errors.New("non-existent entity")
+)
+
+// Service specifies an API that must be fulfilled by the domain service
+// implementation, and all of its decorators (e.g. logging & metrics).
+type Service interface {
+	// IssueCert issues the certificate to the thing identified by the
+	// provided ID, that belongs to the user identified by the provided key.
+	// Zero validity period is replaced with the default one.
+	IssueCert(ctx context.Context, token, thingID string, ttl time.Duration) (Cert, error)
+
+	// ViewCert retrieves the certificate having the provided serial number,
+	// that belongs to the user identified by the provided key.
+	ViewCert(ctx context.Context, token, serial string) (Cert, error)
+
+	// ListCerts retrieves subset of certificates that belong to the user
+	// identified by the provided key, optionally limited to the thing.
+	ListCerts(ctx context.Context, token, thingID string, offset, limit uint64) (CertsPage, error)
+
+	// RevokeCert revokes the certificate having the provided serial number,
+	// that belongs to the user identified by the provided key.
+	RevokeCert(ctx context.Context, token, serial string) error
+
+	// CRL returns the PEM encoded revocation list. It is public, since it is
+	// fetched by the servers verifying the client certificates.
+	CRL(context.Context) ([]byte, error)
+
+	// Status returns the status of the certificate having the provided
+	// serial number. It is public for the same reason as CRL.
+	Status(ctx context.Context, serial string) (string, error)
+}
+
+var _ Service = (*certsService)(nil)
+
+type certsService struct {
+	users  mainflux.UsersServiceClient
+	things Things
+	certs  CertRepository
+	ca     CA
+	cfg    Config
+}
+
+// New instantiates the certs service implementation.
+func New(users mainflux.UsersServiceClient, things Things, certs CertRepository, ca CA, cfg Config) Service {
+	return &certsService{
+		users:  users,
+		things: things,
+		certs:  certs,
+		ca:     ca,
+		cfg:    cfg,
+	}
+}
+
+func (cs *certsService) IssueCert(ctx context.Context, token, thingID string, ttl time.Duration) (Cert, error) {
+	owner, err := cs.identify(ctx, token)
+	if err != nil {
+		return Cert{}, err
+	}
+
+	if ttl == 0 {
+		ttl = cs.cfg.TTL
+	}
+
+	if thingID == "" || ttl < 0 || (cs.cfg.MaxTTL > 0 && ttl > cs.cfg.MaxTTL) {
+		return Cert{}, ErrMalformedEntity
+	}
+
+	if err := cs.things.Authorize(token, thingID); err != nil {
+		return Cert{}, err
+	}
+
+	c, err := cs.ca.Issue(ctx, thingID, ttl)
+	if err != nil {
+		return Cert{}, err
+	}
+	c.ThingID = thingID
+	c.Owner = owner
+
+	if err := cs.certs.Save(ctx, c); err != nil {
+		return Cert{}, err
+	}
+
+	return c, nil
+}
+
+func (cs *certsService) ViewCert(ctx context.Context, token, serial string) (Cert, error) {
+	owner, err := cs.identify(ctx, token)
+	if err != nil {
+		return Cert{}, err
+	}
+
+	c, err := cs.certs.RetrieveBySerial(ctx, serial)
+	if err != nil {
+		return Cert{}, err
+	}
+
+	if c.Owner != owner {
+		return Cert{}, ErrNotFound
+	}
+
+	return c, nil
+}
+
+func (cs *certsService) ListCerts(ctx context.Context, token, thingID string, offset, limit uint64) (CertsPage, error) {
+	owner, err := cs.identify(ctx, token)
+	if err != nil {
+		return CertsPage{}, err
+	}
+
+	return cs.certs.RetrieveByOwner(ctx, owner, thingID, offset, limit)
+}
+
+func (cs *certsService) RevokeCert(ctx context.Context, token, serial string) error {
+	c, err := cs.ViewCert(ctx, token, serial)
+	if err != nil {
+		return err
+	}
+
+	if c.Revoked {
+		return nil
+	}
+
+	if err := cs.ca.Revoke(ctx, serial); err != nil {
+		return err
+	}
+
+	return cs.certs.Revoke(ctx, serial, time.Now())
+}
+
+func (cs *certsService) CRL(ctx context.Context) ([]byte, error) {
+	revoked, err := cs.certs.RetrieveRevoked(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return cs.ca.CRL(ctx, revoked)
+}
+
+func (cs *certsService) Status(ctx context.Context, serial string) (string, error) {
+	c, err := cs.certs.RetrieveBySerial(ctx, serial)
+	switch err {
+	case nil:
+		return c.Status(time.Now()), nil
+	case ErrNotFound:
+		return Unknown, nil
+	default:
+		return "", err
+	}
+}
+
+func (cs *certsService) identify(ctx context.Context, token string) (string, error) {
+	res, err := cs.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return "", ErrUnauthorizedAccess
+	}
+
+	return res.GetValue(), nil
+}
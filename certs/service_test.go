@@ -0,0 +1,233 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package certs_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/certs"
+	"github.com/mainflux/mainflux/certs/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	wrongValue = "wrong-value"
+	email      = "user@example.com"
+	token      = "token"
+	otherEmail = "other@example.com"
+	otherToken = "other"
+	thingID    = "1"
+	otherThing = "2"
+)
+
+var cfg = certs.Config{
+	TTL:    time.Hour,
+	MaxTTL: 24 * time.Hour,
+}
+
+func newService() certs.Service {
+	users := mocks.NewUsersService(map[string]string{token: email, otherToken: otherEmail})
+	things := mocks.NewThings(map[string]string{thingID: token, otherThing: otherToken})
+
+	return certs.New(users, things, mocks.NewCertRepository(), mocks.NewCA(), cfg)
+}
+
+func TestIssueCert(t *testing.T) {
+	svc := newService()
+
+	cases := []struct {
+		desc    string
+		token   string
+		thingID string
+		ttl     time.Duration
+		err     error
+	}{
+		{
+			desc:    "issue certificate with default validity",
+			token:   token,
+			thingID: thingID,
+			ttl:     0,
+			err:     nil,
+		},
+		{
+			desc:    "issue certificate with custom validity",
+			token:   token,
+			thingID: thingID,
+			ttl:     2 * time.Hour,
+			err:     nil,
+		},
+		{
+			desc:    "issue certificate exceeding max validity",
+			token:   token,
+			thingID: thingID,
+			ttl:     48 * time.Hour,
+			err:     certs.ErrMalformedEntity,
+		},
+		{
+			desc:    "issue certificate to other user thing",
+			token:   token,
+			thingID: otherThing,
+			err:     certs.ErrNotFound,
+		},
+		{
+			desc:    "issue certificate without thing",
+			token:   token,
+			thingID: "",
+			err:     certs.ErrMalformedEntity,
+		},
+		{
+			desc:    "issue certificate with invalid credentials",
+			token:   wrongValue,
+			thingID: thingID,
+			err:     certs.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		c, err := svc.IssueCert(context.Background(), tc.token, tc.thingID, tc.ttl)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err == nil {
+			assert.Equal(t, tc.thingID, c.ThingID, fmt.Sprintf("%s: expected thing %s got %s\n", tc.desc, tc.thingID, c.ThingID))
+			assert.NotEmpty(t, c.PrivateKey, fmt.Sprintf("%s: expected private key to be returned", tc.desc))
+		}
+	}
+}
+
+func TestViewCert(t *testing.T) {
+	svc := newService()
+	c, err := svc.IssueCert(context.Background(), token, thingID, 0)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := map[string]struct {
+		token  string
+		serial string
+		err    error
+	}{
+		"view existing certificate":     {token: token, serial: c.Serial, err: nil},
+		"view other user certificate":   {token: otherToken, serial: c.Serial, err: certs.ErrNotFound},
+		"view non-existing certificate": {token: token, serial: wrongValue, err: certs.ErrNotFound},
+		"view with invalid credentials": {token: wrongValue, serial: c.Serial, err: certs.ErrUnauthorizedAccess},
+	}
+
+	for desc, tc := range cases {
+		saved, err := svc.ViewCert(context.Background(), tc.token, tc.serial)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+		if err == nil {
+			assert.Empty(t, saved.PrivateKey, fmt.Sprintf("%s: expected private key not to be stored", desc))
+		}
+	}
+}
+
+func TestListCerts(t *testing.T) {
+	svc := newService()
+	for i := 0; i < 3; i++ {
+		_, err := svc.IssueCert(context.Background(), token, thingID, 0)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+	_, err := svc.IssueCert(context.Background(), otherToken, otherThing, 0)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := map[string]struct {
+		token   string
+		thingID string
+		offset  uint64
+		limit   uint64
+		size    int
+		err     error
+	}{
+		"list all certificates":            {token: token, offset: 0, limit: 10, size: 3, err: nil},
+		"list certificates of the thing":   {token: token, thingID: thingID, offset: 0, limit: 10, size: 3, err: nil},
+		"list certificates of other thing": {token: token, thingID: otherThing, offset: 0, limit: 10, size: 0, err: nil},
+		"list certificates with limit":     {token: token, offset: 1, limit: 1, size: 1, err: nil},
+		"list with invalid credentials":    {token: wrongValue, offset: 0, limit: 10, size: 0, err: certs.ErrUnauthorizedAccess},
+	}
+
+	for desc, tc := range cases {
+		page, err := svc.ListCerts(context.Background(), tc.token, tc.thingID, tc.offset, tc.limit)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+		assert.Equal(t, tc.size, len(page.Certs), fmt.Sprintf("%s: expected %d got %d\n", desc, tc.size, len(page.Certs)))
+	}
+}
+
+func TestRevokeCert(t *testing.T) {
+	svc := newService()
+	c, err := svc.IssueCert(context.Background(), token, thingID, 0)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		token  string
+		serial string
+		err    error
+	}{
+		{
+			desc:   "revoke other user certificate",
+			token:  otherToken,
+			serial: c.Serial,
+			err:    certs.ErrNotFound,
+		},
+		{
+			desc:   "revoke with invalid credentials",
+			token:  wrongValue,
+			serial: c.Serial,
+			err:    certs.ErrUnauthorizedAccess,
+		},
+		{
+			desc:   "revoke existing certificate",
+			token:  token,
+			serial: c.Serial,
+			err:    nil,
+		},
+		{
+			desc:   "revoke revoked certificate",
+			token:  token,
+			serial: c.Serial,
+			err:    nil,
+		},
+		{
+			desc:   "revoke non-existing certificate",
+			token:  token,
+			serial: wrongValue,
+			err:    certs.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.RevokeCert(context.Background(), tc.token, tc.serial)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	crl, err := svc.CRL(context.Background())
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, c.Serial, string(crl), fmt.Sprintf("expected CRL to list %s got %s", c.Serial, crl))
+}
+
+func TestStatus(t *testing.T) {
+	svc := newService()
+	good, err := svc.IssueCert(context.Background(), token, thingID, 0)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	revoked, err := svc.IssueCert(context.Background(), token, thingID, 0)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = svc.RevokeCert(context.Background(), token, revoked.Serial)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := map[string]struct {
+		serial string
+		status string
+	}{
+		"status of valid certificate":        {serial: good.Serial, status: certs.Good},
+		"status of revoked certificate":      {serial: revoked.Serial, status: certs.Revoked},
+		"status of non-existing certificate": {serial: wrongValue, status: certs.Unknown},
+	}
+
+	for desc, tc := range cases {
+		status, err := svc.Status(context.Background(), tc.serial)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", desc, err))
+		assert.Equal(t, tc.status, status, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.status, status))
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package things contains the things ownership check backed by the Mainflux
+// SDK.
+package things
+
+import (
+	"github.com/mainflux/mainflux/certs"
+	mfsdk "github.com/mainflux/mainflux/sdk/go"
+)
+
+var _ certs.Things = (*things)(nil)
+
+type things struct {
+	sdk mfsdk.SDK
+}
+
+// New returns things API client backed by the provided SDK. Since the things
+// service returns only the things owned by the user, the thing is owned if it
+// can be retrieved.
+func New(sdk mfsdk.SDK) certs.Things {
+	return things{sdk: sdk}
+}
+
+func (t things) Authorize(token, thingID string) error {
+	_, err := t.sdk.Thing(thingID, token)
+	switch err {
+	case nil:
+		return nil
+	case mfsdk.ErrUnauthorized:
+		return certs.ErrUnauthorizedAccess
+	case mfsdk.ErrNotFound:
+		return certs.ErrNotFound
+	default:
+		return err
+	}
+}
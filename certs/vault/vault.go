@@ -0,0 +1,142 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package vault contains the certificate authority backed by the Vault PKI
+// secrets engine.
+package vault
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mainflux/mainflux/certs"
+)
+
+const (
+	tokenHeader = "X-Vault-Token"
+	contentType = "application/json"
+)
+
+var _ certs.CA = (*ca)(nil)
+
+type ca struct {
+	addr   string
+	token  string
+	mount  string
+	role   string
+	client *http.Client
+}
+
+// New returns the CA issuing the certificates using the role of the PKI
+// secrets engine mounted at the provided path. The role has to allow any
+// common name, since it is set to the thing ID.
+func New(addr, token, mount, role string, timeout time.Duration) certs.CA {
+	return ca{
+		addr:   strings.TrimSuffix(addr, "/"),
+		token:  token,
+		mount:  strings.Trim(mount, "/"),
+		role:   role,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+type issueReq struct {
+	CommonName string `json:"common_name"`
+	TTL        string `json:"ttl"`
+}
+
+type issueRes struct {
+	Data struct {
+		Certificate string `json:"certificate"`
+		PrivateKey  string `json:"private_key"`
+		IssuingCA   string `json:"issuing_ca"`
+		Serial      string `json:"serial_number"`
+		Expiration  int64  `json:"expiration"`
+	} `json:"data"`
+}
+
+type revokeReq struct {
+	Serial string `json:"serial_number"`
+}
+
+func (c ca) Issue(ctx context.Context, thingID string, ttl time.Duration) (certs.Cert, error) {
+	req := issueReq{
+		CommonName: thingID,
+		TTL:        fmt.Sprintf("%ds", int64(ttl/time.Second)),
+	}
+
+	body, err := c.send(ctx, http.MethodPost, fmt.Sprintf("issue/%s", c.role), req)
+	if err != nil {
+		return certs.Cert{}, err
+	}
+
+	var res issueRes
+	if err := json.Unmarshal(body, &res); err != nil {
+		return certs.Cert{}, err
+	}
+
+	cert := certs.Cert{
+		Serial:      res.Data.Serial,
+		ThingID:     thingID,
+		Certificate: res.Data.Certificate,
+		PrivateKey:  res.Data.PrivateKey,
+		IssuingCA:   res.Data.IssuingCA,
+		Expires:     time.Unix(res.Data.Expiration, 0).UTC(),
+	}
+
+	return cert, nil
+}
+
+func (c ca) Revoke(ctx context.Context, serial string) error {
+	_, err := c.send(ctx, http.MethodPost, "revoke", revokeReq{Serial: serial})
+	return err
+}
+
+func (c ca) CRL(ctx context.Context, _ []certs.Cert) ([]byte, error) {
+	return c.send(ctx, http.MethodGet, "crl/pem", nil)
+}
+
+func (c ca) send(ctx context.Context, method, path string, payload interface{}) ([]byte, error) {
+	var body []byte
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		body = data
+	}
+
+	url := fmt.Sprintf("%s/v1/%s/%s", c.addr, c.mount, path)
+	req, err := http.NewRequest(method, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set(tokenHeader, c.token)
+	if payload != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return nil, fmt.Errorf("vault responded with status %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+
+	return data, nil
+}
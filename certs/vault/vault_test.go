@@ -0,0 +1,99 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package vault_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/certs/vault"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	vaultToken = "vault-token"
+	thingID    = "513d02d2-16c1-4f23-98be-9e12f8fee898"
+	serial     = "1f:a0:03"
+	crl        = "-----BEGIN X509 CRL-----\n-----END X509 CRL-----\n"
+)
+
+func newServer(t *testing.T, revoked *[]string) *httptest.Server {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/v1/pki/issue/mainflux", func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]string
+		err := json.NewDecoder(r.Body).Decode(&req)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		assert.Equal(t, thingID, req["common_name"], fmt.Sprintf("expected common name %s got %s", thingID, req["common_name"]))
+		assert.Equal(t, "3600s", req["ttl"], fmt.Sprintf("expected ttl 3600s got %s", req["ttl"]))
+
+		fmt.Fprintf(w, `{"data":{"certificate":"cert","private_key":"key","issuing_ca":"ca","serial_number":"%s","expiration":1700000000}}`, serial)
+	})
+
+	mux.HandleFunc("/v1/pki/revoke", func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]string
+		err := json.NewDecoder(r.Body).Decode(&req)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		*revoked = append(*revoked, req["serial_number"])
+	})
+
+	mux.HandleFunc("/v1/pki/crl/pem", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, crl)
+	})
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != vaultToken {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	}))
+}
+
+func TestIssue(t *testing.T) {
+	revoked := []string{}
+	ts := newServer(t, &revoked)
+	defer ts.Close()
+
+	cases := map[string]struct {
+		token string
+		err   bool
+	}{
+		"issue certificate":                    {token: vaultToken, err: false},
+		"issue certificate with invalid token": {token: "wrong", err: true},
+	}
+
+	for desc, tc := range cases {
+		ca := vault.New(ts.URL, tc.token, "/pki/", "mainflux", time.Second)
+		c, err := ca.Issue(context.Background(), thingID, time.Hour)
+		assert.Equal(t, tc.err, err != nil, fmt.Sprintf("%s: unexpected error: %v", desc, err))
+		if err == nil {
+			assert.Equal(t, serial, c.Serial, fmt.Sprintf("%s: expected serial %s got %s", desc, serial, c.Serial))
+			assert.Equal(t, "key", c.PrivateKey, fmt.Sprintf("%s: expected private key to be returned", desc))
+			assert.Equal(t, time.Unix(1700000000, 0).UTC(), c.Expires, fmt.Sprintf("%s: unexpected expiration %s", desc, c.Expires))
+		}
+	}
+}
+
+func TestRevokeAndCRL(t *testing.T) {
+	revoked := []string{}
+	ts := newServer(t, &revoked)
+	defer ts.Close()
+
+	ca := vault.New(ts.URL, vaultToken, "pki", "mainflux", time.Second)
+
+	err := ca.Revoke(context.Background(), serial)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, []string{serial}, revoked, fmt.Sprintf("expected %s to be revoked got %v", serial, revoked))
+
+	data, err := ca.CRL(context.Background(), nil)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, crl, string(data), fmt.Sprintf("expected CRL %s got %s", crl, data))
+}
@@ -0,0 +1,438 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"time"
+
+	mfxsdk "github.com/mainflux/mainflux/sdk/go"
+	"github.com/spf13/cobra"
+)
+
+const (
+	backupVersion = 1
+
+	manifestFile    = "manifest.json"
+	usersFile       = "users.json"
+	thingsFile      = "things.json"
+	channelsFile    = "channels.json"
+	connectionsFile = "connections.json"
+	configsFile     = "configs.json"
+)
+
+var (
+	errUnsupportedVersion = errors.New("unsupported backup version")
+	errMissingPassword    = errors.New("password of the restored users is required")
+)
+
+var (
+	// skipBootstrap excludes bootstrap configurations from backup and
+	// restore, for the deployments without bootstrap service.
+	skipBootstrap bool
+	// restorePassword is the password of the restored users, since the
+	// passwords can't be exported.
+	restorePassword string
+)
+
+type manifest struct {
+	Version int       `json:"version"`
+	Created time.Time `json:"created"`
+}
+
+type backupUser struct {
+	Email    string                 `json:"email"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	Disabled bool                   `json:"disabled,omitempty"`
+	Role     string                 `json:"role,omitempty"`
+}
+
+// archive holds the contents of the backup archive, keyed by file name.
+type archive map[string][]byte
+
+func (a archive) put(name string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	a[name] = data
+
+	return nil
+}
+
+// get decodes the file, leaving the value intact if the file is missing.
+func (a archive) get(name string, v interface{}) error {
+	data, ok := a[name]
+	if !ok {
+		return nil
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+func writeArchive(file string, a archive) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gw)
+
+	names := []string{}
+	for name := range a {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	now := time.Now()
+	for _, name := range names {
+		data := a[name]
+		hdr := &tar.Header{
+			Name:    name,
+			Mode:    0600,
+			Size:    int64(len(data)),
+			ModTime: now,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := tw.Write(data); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+
+	return gw.Close()
+}
+
+func readArchive(file string) (archive, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	defer gr.Close()
+
+	a := archive{}
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+		a[hdr.Name] = data
+	}
+
+	var m manifest
+	if err := a.get(manifestFile, &m); err != nil {
+		return nil, err
+	}
+	if m.Version != backupVersion {
+		return nil, errUnsupportedVersion
+	}
+
+	return a, nil
+}
+
+func listUsers(token string) ([]mfxsdk.User, error) {
+	users := []mfxsdk.User{}
+	it := sdk.UsersIterator(token)
+	for it.Next() {
+		users = append(users, it.User())
+	}
+
+	return users, it.Err()
+}
+
+func listConfigs(token string) ([]mfxsdk.BootstrapConfig, error) {
+	configs := []mfxsdk.BootstrapConfig{}
+	it := sdk.BootstrapIterator(token)
+	for it.Next() {
+		configs = append(configs, it.Config())
+	}
+
+	return configs, it.Err()
+}
+
+// backupEntities exports the entities of the user into the user directory
+// of the archive.
+func backupEntities(a archive, email, token string) error {
+	dir := path.Join("users", email)
+
+	things, err := sdk.ExportThings(token)
+	if err != nil {
+		return err
+	}
+	if err := a.put(path.Join(dir, thingsFile), things); err != nil {
+		return err
+	}
+
+	channels, err := sdk.ExportChannels(token)
+	if err != nil {
+		return err
+	}
+	if err := a.put(path.Join(dir, channelsFile), channels); err != nil {
+		return err
+	}
+
+	conns, err := sdk.ExportConnections(token)
+	if err != nil {
+		return err
+	}
+	if err := a.put(path.Join(dir, connectionsFile), conns); err != nil {
+		return err
+	}
+
+	if skipBootstrap {
+		return nil
+	}
+
+	configs, err := listConfigs(token)
+	if err != nil {
+		return err
+	}
+
+	return a.put(path.Join(dir, configsFile), configs)
+}
+
+// restoreEntities imports the entities of the user from the user directory
+// of the archive. Things, channels and connections are imported before the
+// bootstrap configurations that refer to them.
+func restoreEntities(a archive, email, token string) error {
+	dir := path.Join("users", email)
+
+	things := []mfxsdk.Thing{}
+	if err := a.get(path.Join(dir, thingsFile), &things); err != nil {
+		return err
+	}
+	if _, err := sdk.ImportThings(things, token); err != nil {
+		return err
+	}
+
+	channels := []mfxsdk.Channel{}
+	if err := a.get(path.Join(dir, channelsFile), &channels); err != nil {
+		return err
+	}
+	if _, err := sdk.ImportChannels(channels, token); err != nil {
+		return err
+	}
+
+	conns := []mfxsdk.Connection{}
+	if err := a.get(path.Join(dir, connectionsFile), &conns); err != nil {
+		return err
+	}
+	if _, err := sdk.ImportConnections(conns, token); err != nil {
+		return err
+	}
+
+	if skipBootstrap {
+		return nil
+	}
+
+	configs := []mfxsdk.BootstrapConfig{}
+	if err := a.get(path.Join(dir, configsFile), &configs); err != nil {
+		return err
+	}
+
+	for _, cfg := range configs {
+		state := cfg.State
+		cfg.State = 0
+		if _, err := sdk.AddBootstrap(cfg, token); err != nil {
+			return err
+		}
+
+		if state != 0 {
+			if err := sdk.UpdateBootstrapState(cfg.ThingID, state, token); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// NewBackupCmd returns backup command.
+func NewBackupCmd() *cobra.Command {
+	cmd := cobra.Command{
+		Use:   "backup",
+		Short: "backup <archive_file> <admin_auth_token>",
+		Long: `Exports users, things, channels, connections and bootstrap configurations
+into the archive. Entities of disabled users can't be exported, and thing keys
+are exported only if Things service doesn't store them hashed.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) != 2 {
+				logUsage(cmd.Short)
+				return
+			}
+
+			users, err := listUsers(args[1])
+			if err != nil {
+				logError(err)
+				return
+			}
+
+			a := archive{}
+			if err := a.put(manifestFile, manifest{Version: backupVersion, Created: time.Now().UTC()}); err != nil {
+				logError(err)
+				return
+			}
+
+			backup := []backupUser{}
+			for i, u := range users {
+				bu := backupUser{
+					Email:    u.Email,
+					Metadata: u.Metadata,
+					Disabled: u.Disabled,
+				}
+
+				if bu.Role, err = sdk.Role(u.Email, args[1]); err != nil {
+					logError(err)
+					return
+				}
+				backup = append(backup, bu)
+
+				if u.Disabled {
+					logProgress(i+1, len(users), fmt.Sprintf("user %s is disabled, entities skipped", u.Email))
+					continue
+				}
+
+				token, err := sdk.Impersonate(u.Email, args[1])
+				if err != nil {
+					logError(err)
+					return
+				}
+
+				if err := backupEntities(a, u.Email, token); err != nil {
+					logError(err)
+					return
+				}
+
+				logProgress(i+1, len(users), fmt.Sprintf("user %s", u.Email))
+			}
+
+			if err := a.put(usersFile, backup); err != nil {
+				logError(err)
+				return
+			}
+
+			if err := writeArchive(args[0], a); err != nil {
+				logError(err)
+				return
+			}
+
+			logOK()
+		},
+	}
+
+	cmd.Flags().BoolVar(&skipBootstrap, "skip-bootstrap", false, "Skip bootstrap configurations")
+
+	return &cmd
+}
+
+// NewRestoreCmd returns restore command.
+func NewRestoreCmd() *cobra.Command {
+	cmd := cobra.Command{
+		Use:   "restore",
+		Short: "restore <archive_file> <admin_auth_token> --password <users_password>",
+		Long: `Imports users, things, channels, connections and bootstrap configurations
+from the archive. Missing users are registered with the provided password, and
+the keys of the things exported without keys are generated.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) != 2 {
+				logUsage(cmd.Short)
+				return
+			}
+
+			if restorePassword == "" {
+				logError(errMissingPassword)
+				return
+			}
+
+			a, err := readArchive(args[0])
+			if err != nil {
+				logError(err)
+				return
+			}
+
+			users := []backupUser{}
+			if err := a.get(usersFile, &users); err != nil {
+				logError(err)
+				return
+			}
+
+			for i, u := range users {
+				user := mfxsdk.User{
+					Email:    u.Email,
+					Password: restorePassword,
+					Metadata: u.Metadata,
+				}
+				if err := sdk.CreateUser(user); err != nil && err != mfxsdk.ErrConflict {
+					logError(err)
+					return
+				}
+
+				if u.Role != "" {
+					if err := sdk.AssignRole(u.Email, u.Role, args[1]); err != nil {
+						logError(err)
+						return
+					}
+				}
+
+				token, err := sdk.Impersonate(u.Email, args[1])
+				if err != nil {
+					logError(err)
+					return
+				}
+
+				if err := restoreEntities(a, u.Email, token); err != nil {
+					logError(err)
+					return
+				}
+
+				if u.Disabled {
+					if err := sdk.DisableUser(u.Email, args[1]); err != nil {
+						logError(err)
+						return
+					}
+				}
+
+				logProgress(i+1, len(users), fmt.Sprintf("user %s", u.Email))
+			}
+
+			logOK()
+		},
+	}
+
+	cmd.Flags().BoolVar(&skipBootstrap, "skip-bootstrap", false, "Skip bootstrap configurations")
+	cmd.Flags().StringVar(&restorePassword, "password", "", "Password of the registered users")
+
+	return &cmd
+}
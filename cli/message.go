@@ -3,26 +3,227 @@
 
 package cli
 
-import "github.com/spf13/cobra"
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/template"
+	"time"
 
-const contentTypeSenml = "application/senml+json"
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/fatih/color"
+	"github.com/gorilla/websocket"
+	prettyjson "github.com/hokaccha/go-prettyjson"
+	"github.com/spf13/cobra"
+)
+
+const (
+	contentTypeSenml = "application/senml+json"
+
+	protocolWS   = "ws"
+	protocolMQTT = "mqtt"
+	mqttTimeout  = 5 * time.Second
+)
+
+var (
+	errUnsupportedProtocol = errors.New("unsupported protocol, use ws or mqtt")
+	errMissingThingID      = errors.New("thing ID is required for MQTT subscription")
+	errMQTTTimeout         = errors.New("timeout while communicating with MQTT adapter")
+)
+
+var (
+	// msgCount is the number of messages sent from the template.
+	msgCount uint = 1
+	// msgInterval is the pause between two sent messages.
+	msgInterval = time.Second
+	// tailProtocol is the protocol used to subscribe to the channel.
+	tailProtocol = protocolWS
+	// wsURL is the URL of the WebSocket adapter.
+	wsURL = "ws://localhost/ws"
+	// mqttURL is the URL of the MQTT adapter.
+	mqttURL = "tcp://localhost:1883"
+	// thingID identifies the thing subscribing over MQTT.
+	thingID = ""
+)
+
+// messageData holds the values available to the message template.
+type messageData struct {
+	Seq  uint
+	Time float64
+}
+
+var templateFuncs = template.FuncMap{
+	"rand": func(min, max float64) float64 {
+		return min + rand.Float64()*(max-min)
+	},
+}
+
+func renderMessage(tmpl *template.Template, seq uint) (string, error) {
+	data := messageData{
+		Seq:  seq,
+		Time: float64(time.Now().UnixNano()) / float64(time.Second),
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// splitChannel splits <channel_id>[.<subtopic>...] into the channel ID and
+// the subtopic path.
+func splitChannel(chanName string) (string, string) {
+	parts := strings.SplitN(chanName, ".", 2)
+	if len(parts) == 2 {
+		return parts[0], strings.Replace(parts[1], ".", "/", -1)
+	}
+
+	return parts[0], ""
+}
+
+func messagesPath(chanName string) string {
+	chanID, subtopic := splitChannel(chanName)
+	path := fmt.Sprintf("channels/%s/messages", chanID)
+	if subtopic != "" {
+		path = fmt.Sprintf("%s/%s", path, subtopic)
+	}
+
+	return path
+}
+
+func logMessage(topic string, payload []byte) {
+	fmt.Printf(color.BlueString("%s %s\n"), time.Now().Format(time.RFC3339), topic)
+	if pj, err := prettyjson.Format(payload); err == nil {
+		payload = pj
+	}
+	fmt.Printf("%s\n\n", string(payload))
+}
+
+func tailWS(chanName, key string) error {
+	u := fmt.Sprintf("%s/%s?authorization=%s", strings.TrimSuffix(wsURL, "/"), messagesPath(chanName), url.QueryEscape(key))
+
+	conn, _, err := websocket.DefaultDialer.Dial(u, nil)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	done := make(chan struct{})
+	go func() {
+		waitInterrupt()
+		close(done)
+		conn.Close()
+	}()
+
+	topic := messagesPath(chanName)
+	for {
+		_, payload, err := conn.ReadMessage()
+		if err != nil {
+			select {
+			case <-done:
+				return nil
+			default:
+				return err
+			}
+		}
+		logMessage(topic, payload)
+	}
+}
+
+func tailMQTT(chanName, key string) error {
+	if thingID == "" {
+		return errMissingThingID
+	}
+
+	opts := mqtt.NewClientOptions().
+		AddBroker(mqttURL).
+		SetClientID(fmt.Sprintf("mainflux-cli-%d", time.Now().UnixNano())).
+		SetUsername(thingID).
+		SetPassword(key)
+
+	c := mqtt.NewClient(opts)
+	token := c.Connect()
+	if !token.WaitTimeout(mqttTimeout) {
+		return errMQTTTimeout
+	}
+	if err := token.Error(); err != nil {
+		return err
+	}
+	defer c.Disconnect(0)
+
+	topic := messagesPath(chanName)
+	if _, subtopic := splitChannel(chanName); subtopic == "" {
+		topic = fmt.Sprintf("%s/#", topic)
+	}
+
+	token = c.Subscribe(topic, 0, func(_ mqtt.Client, m mqtt.Message) {
+		logMessage(m.Topic(), m.Payload())
+	})
+	if !token.WaitTimeout(mqttTimeout) {
+		return errMQTTTimeout
+	}
+	if err := token.Error(); err != nil {
+		return err
+	}
+
+	waitInterrupt()
+	return nil
+}
+
+func waitInterrupt() {
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, syscall.SIGINT, syscall.SIGTERM)
+	<-c
+}
 
 var cmdMessages = []cobra.Command{
 	cobra.Command{
 		Use:   "send",
 		Short: "send <channel_id>[.<subtopic>...] <JSON_string> <thing_key>",
-		Long:  `Sends message on the channel`,
+		Long: `Sends message on the channel. Message is a template which can use
+the message sequence number {{.Seq}}, the current time in seconds {{.Time}}
+and random values within bounds {{rand <min> <max>}}.`,
 		Run: func(cmd *cobra.Command, args []string) {
-			if len(args) != 3 {
+			if len(args) != 3 || msgCount == 0 {
 				logUsage(cmd.Short)
 				return
 			}
 
-			if err := sdk.SendMessage(args[0], args[1], args[2]); err != nil {
+			tmpl, err := template.New("message").Funcs(templateFuncs).Parse(args[1])
+			if err != nil {
 				logError(err)
 				return
 			}
 
+			for i := uint(0); i < msgCount; i++ {
+				if i > 0 {
+					time.Sleep(msgInterval)
+				}
+
+				msg, err := renderMessage(tmpl, i)
+				if err != nil {
+					logError(err)
+					return
+				}
+
+				if err := sdk.SendMessage(args[0], msg, args[2]); err != nil {
+					logError(err)
+					return
+				}
+
+				if msgCount > 1 {
+					logProgress(int(i+1), int(msgCount), msg)
+				}
+			}
+
 			logOK()
 		},
 	},
@@ -45,16 +246,41 @@ var cmdMessages = []cobra.Command{
 			logJSON(m)
 		},
 	},
+	cobra.Command{
+		Use:   "tail",
+		Short: "tail <channel_id>[.<subtopic>...] <thing_key>",
+		Long:  `Prints channel messages as they arrive, until interrupted`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) != 2 {
+				logUsage(cmd.Short)
+				return
+			}
+
+			var err error
+			switch tailProtocol {
+			case protocolWS:
+				err = tailWS(args[0], args[1])
+			case protocolMQTT:
+				err = tailMQTT(args[0], args[1])
+			default:
+				err = errUnsupportedProtocol
+			}
+
+			if err != nil {
+				logError(err)
+			}
+		},
+	},
 }
 
 // NewMessagesCmd returns messages command.
 func NewMessagesCmd() *cobra.Command {
 	cmd := cobra.Command{
 		Use:   "messages",
-		Short: "Send or read messages",
-		Long:  `Send or read messages using the http-adapter and the configured database reader`,
+		Short: "Send, read or tail messages",
+		Long:  `Send or read messages using the http-adapter and the configured database reader, or tail messages over WebSocket or MQTT`,
 		Run: func(cmd *cobra.Command, args []string) {
-			logUsage("messages [send | read]")
+			logUsage("messages [send | read | tail]")
 		},
 	}
 
@@ -62,5 +288,12 @@ func NewMessagesCmd() *cobra.Command {
 		cmd.AddCommand(&cmdMessages[i])
 	}
 
+	cmd.PersistentFlags().UintVar(&msgCount, "count", msgCount, "Number of messages to send")
+	cmd.PersistentFlags().DurationVar(&msgInterval, "interval", msgInterval, "Interval between sent messages")
+	cmd.PersistentFlags().StringVar(&tailProtocol, "protocol", tailProtocol, "Protocol used to tail messages: ws or mqtt")
+	cmd.PersistentFlags().StringVar(&wsURL, "ws-url", wsURL, "Mainflux WebSocket adapter URL")
+	cmd.PersistentFlags().StringVar(&mqttURL, "mqtt-url", mqttURL, "Mainflux MQTT adapter URL")
+	cmd.PersistentFlags().StringVar(&thingID, "thing-id", thingID, "Thing ID used to tail messages over MQTT")
+
 	return &cmd
 }
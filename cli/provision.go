@@ -6,143 +6,303 @@ package cli
 import (
 	"bufio"
 	"encoding/csv"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 
 	"github.com/docker/docker/pkg/namesgenerator"
+	"github.com/gofrs/uuid"
 	mfxsdk "github.com/mainflux/mainflux/sdk/go"
 	"github.com/spf13/cobra"
 )
 
-var errMalformedCSV = errors.New("malformed CSV")
+const jsonExt = ".json"
 
-func createThing(name, token string) (mfxsdk.Thing, error) {
-	id, err := sdk.CreateThing(mfxsdk.Thing{Name: name}, token)
+var (
+	errMalformedCSV        = errors.New("malformed CSV")
+	errMalformedConnection = errors.New("connection must contain thing ID and at least one channel ID")
+)
+
+var (
+	// provisionFile is the path of CSV or JSON file describing the entities.
+	provisionFile string
+	// provisionDryRun validates the file and reports the progress without
+	// creating the entities.
+	provisionDryRun bool
+)
+
+// connection maps the thing to the channels it should be connected to.
+type connection struct {
+	ThingID    string   `json:"thing_id"`
+	ChannelIDs []string `json:"channel_ids"`
+}
+
+// createThing creates the thing with the generated key, since the key can't
+// be retrieved if Things service stores thing keys hashed.
+func createThing(th mfxsdk.Thing, token string) (mfxsdk.Thing, error) {
+	if th.Key == "" {
+		key, err := uuid.NewV4()
+		if err != nil {
+			return mfxsdk.Thing{}, err
+		}
+		th.Key = key.String()
+	}
+
+	id, err := sdk.CreateThing(th, token)
 	if err != nil {
 		return mfxsdk.Thing{}, err
 	}
+	th.ID = id
+
+	return th, nil
+}
 
-	t, err := sdk.Thing(id, token)
+func createChannel(ch mfxsdk.Channel, token string) (mfxsdk.Channel, error) {
+	id, err := sdk.CreateChannel(ch, token)
 	if err != nil {
-		return mfxsdk.Thing{}, err
+		return mfxsdk.Channel{}, err
 	}
+	ch.ID = id
+
+	return ch, nil
+}
 
-	m := mfxsdk.Thing{
-		ID:   id,
-		Name: name,
-		Key:  t.Key,
+// readThings reads things from JSON array of things, or from CSV file with
+// thing name and optional key in each row.
+func readThings(path string) ([]mfxsdk.Thing, error) {
+	things := []mfxsdk.Thing{}
+	if filepath.Ext(path) == jsonExt {
+		err := readJSON(path, &things)
+		return things, err
 	}
 
-	return m, nil
+	rows, err := readCSV(path)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range rows {
+		th := mfxsdk.Thing{Name: r[0]}
+		if len(r) > 1 {
+			th.Key = r[1]
+		}
+		things = append(things, th)
+	}
+
+	return things, nil
 }
 
-func createChannel(name, token string) (mfxsdk.Channel, error) {
-	id, err := sdk.CreateChannel(mfxsdk.Channel{Name: name}, token)
+// readChannels reads channels from JSON array of channels, or from CSV file
+// with channel name in each row.
+func readChannels(path string) ([]mfxsdk.Channel, error) {
+	channels := []mfxsdk.Channel{}
+	if filepath.Ext(path) == jsonExt {
+		err := readJSON(path, &channels)
+		return channels, err
+	}
+
+	rows, err := readCSV(path)
 	if err != nil {
-		return mfxsdk.Channel{}, nil
+		return nil, err
 	}
 
-	c := mfxsdk.Channel{
-		ID:   id,
-		Name: name,
+	for _, r := range rows {
+		channels = append(channels, mfxsdk.Channel{Name: r[0]})
 	}
 
-	return c, nil
+	return channels, nil
+}
+
+// readConnections reads connections from JSON array of connections, or from
+// CSV file with thing ID followed by channel IDs in each row.
+func readConnections(path string) ([]connection, error) {
+	conns := []connection{}
+	if filepath.Ext(path) == jsonExt {
+		if err := readJSON(path, &conns); err != nil {
+			return nil, err
+		}
+	} else {
+		rows, err := readCSV(path)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, r := range rows {
+			conns = append(conns, connection{ThingID: r[0], ChannelIDs: r[1:]})
+		}
+	}
+
+	for _, c := range conns {
+		if c.ThingID == "" || len(c.ChannelIDs) == 0 {
+			return nil, errMalformedConnection
+		}
+		for _, ch := range c.ChannelIDs {
+			if ch == "" {
+				return nil, errMalformedConnection
+			}
+		}
+	}
+
+	return conns, nil
+}
+
+func readJSON(path string, v interface{}) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return json.NewDecoder(f).Decode(v)
+}
+
+func readCSV(path string) ([][]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(bufio.NewReader(f))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	rows := [][]string{}
+	for {
+		r, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if len(r) < 1 || r[0] == "" {
+			return nil, errMalformedCSV
+		}
+
+		rows = append(rows, r)
+	}
+
+	return rows, nil
+}
+
+// progress reports the progress of bulk provisioning, marking the entries
+// which are only validated in dry-run mode.
+func progress(i, n int, format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	if provisionDryRun {
+		msg = fmt.Sprintf("%s (dry run)", msg)
+	}
+	logProgress(i, n, msg)
 }
 
 var cmdProvision = []cobra.Command{
 	cobra.Command{
 		Use:   "things",
-		Short: "things <things_csv> <user_token>",
-		Long:  `Provisions things`,
+		Short: "things --file <things_file> <user_token>",
+		Long:  `Provisions things from CSV or JSON file`,
 		Run: func(cmd *cobra.Command, args []string) {
-			things := []mfxsdk.Thing{}
-
-			if len(args) != 2 {
+			if len(args) != 1 || provisionFile == "" {
 				logUsage(cmd.Short)
 				return
 			}
 
-			c, err := os.Open(args[0])
+			things, err := readThings(provisionFile)
 			if err != nil {
 				logError(err)
 				return
 			}
-			reader := csv.NewReader(bufio.NewReader(c))
-
-			for {
-				l, err := reader.Read()
-				if err == io.EOF {
-					break
-				}
-				if err != nil {
-					logError(err)
-					return
-				}
 
-				if len(l) < 1 {
-					logError(errMalformedCSV)
-					return
-				}
-
-				m, err := createThing(l[0], args[1])
-				if err != nil {
-					logError(err)
-					return
+			created := []mfxsdk.Thing{}
+			for i, th := range things {
+				if !provisionDryRun {
+					if th, err = createThing(th, args[0]); err != nil {
+						break
+					}
 				}
-
-				things = append(things, m)
+				created = append(created, th)
+				progress(i+1, len(things), "thing %s", th.Name)
 			}
 
-			logJSON(things)
+			logJSON(created)
+			if err != nil {
+				logError(err)
+			}
 		},
 	},
 	cobra.Command{
 		Use:   "channels",
-		Short: "channels <channels_csv> <user_token>",
-		Long:  `Provisions channels`,
+		Short: "channels --file <channels_file> <user_token>",
+		Long:  `Provisions channels from CSV or JSON file`,
 		Run: func(cmd *cobra.Command, args []string) {
-			channels := []mfxsdk.Channel{}
-
-			if len(args) != 2 {
+			if len(args) != 1 || provisionFile == "" {
 				logUsage(cmd.Short)
 				return
 			}
 
-			c, err := os.Open(args[0])
+			channels, err := readChannels(provisionFile)
 			if err != nil {
 				logError(err)
 				return
 			}
-			reader := csv.NewReader(bufio.NewReader(c))
 
-			for {
-				l, err := reader.Read()
-				if err == io.EOF {
-					break
-				}
-				if err != nil {
-					logError(err)
-					return
+			created := []mfxsdk.Channel{}
+			for i, ch := range channels {
+				if !provisionDryRun {
+					if ch, err = createChannel(ch, args[0]); err != nil {
+						break
+					}
 				}
+				created = append(created, ch)
+				progress(i+1, len(channels), "channel %s", ch.Name)
+			}
 
-				if len(l) < 1 {
-					logError(errMalformedCSV)
-					return
-				}
+			logJSON(created)
+			if err != nil {
+				logError(err)
+			}
+		},
+	},
+	cobra.Command{
+		Use:   "connect",
+		Short: "connect --file <connections_file> <user_token>",
+		Long:  `Connects things to channels from CSV or JSON file`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) != 1 || provisionFile == "" {
+				logUsage(cmd.Short)
+				return
+			}
 
-				c, err := createChannel(l[0], args[1])
-				if err != nil {
-					logError(err)
-					return
-				}
+			conns, err := readConnections(provisionFile)
+			if err != nil {
+				logError(err)
+				return
+			}
 
-				channels = append(channels, c)
+			total := 0
+			for _, c := range conns {
+				total += len(c.ChannelIDs)
+			}
+
+			done := 0
+			for _, c := range conns {
+				for _, ch := range c.ChannelIDs {
+					if !provisionDryRun {
+						if err := sdk.ConnectThing(c.ThingID, ch, args[0]); err != nil {
+							logError(err)
+							return
+						}
+					}
+					done++
+					progress(done, total, "thing %s to channel %s", c.ThingID, ch)
+				}
 			}
 
-			logJSON(channels)
+			logOK()
 		},
 	},
 	cobra.Command{
@@ -183,7 +343,7 @@ var cmdProvision = []cobra.Command{
 			for i := 0; i < numThings; i++ {
 				n := fmt.Sprintf("d%d", i)
 
-				m, err := createThing(n, ut)
+				m, err := createThing(mfxsdk.Thing{Name: n}, ut)
 				if err != nil {
 					logError(err)
 					return
@@ -194,7 +354,7 @@ var cmdProvision = []cobra.Command{
 			// Create channels
 			for i := 0; i < numChan; i++ {
 				n := fmt.Sprintf("c%d", i)
-				c, err := createChannel(n, ut)
+				c, err := createChannel(mfxsdk.Channel{Name: n}, ut)
 				if err != nil {
 					logError(err)
 					return
@@ -231,12 +391,15 @@ func NewProvisionCmd() *cobra.Command {
 	cmd := cobra.Command{
 		Use:   "provision",
 		Short: "Provision things and channels from config file",
-		Long:  `Provision things and channels: use csv config file to provision things and channels`,
+		Long:  `Provision things and channels: use CSV or JSON file to provision things, channels and connections`,
 	}
 
 	for i := range cmdProvision {
 		cmd.AddCommand(&cmdProvision[i])
 	}
 
+	cmd.PersistentFlags().StringVarP(&provisionFile, "file", "f", "", "CSV or JSON file describing the entities")
+	cmd.PersistentFlags().BoolVarP(&provisionDryRun, "dry-run", "d", false, "Validate the file without provisioning")
+
 	return &cmd
 }
@@ -0,0 +1,104 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"encoding/json"
+
+	mfxsdk "github.com/mainflux/mainflux/sdk/go"
+	"github.com/spf13/cobra"
+)
+
+var cmdSimulations = []cobra.Command{
+	cobra.Command{
+		Use:   "start",
+		Short: "start <JSON_simulation> <user_auth_token>",
+		Long:  `Start new simulation of virtual devices`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) != 2 {
+				logUsage(cmd.Short)
+				return
+			}
+
+			var sim mfxsdk.Simulation
+			if err := json.Unmarshal([]byte(args[0]), &sim); err != nil {
+				logError(err)
+				return
+			}
+
+			id, err := sdk.StartSimulation(sim, args[1])
+			if err != nil {
+				logError(err)
+				return
+			}
+
+			logCreated(id)
+		},
+	},
+	cobra.Command{
+		Use:   "get",
+		Short: "get [all | <simulation_id>] <user_auth_token>",
+		Long:  `Get all running simulations or simulation by id`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) != 2 {
+				logUsage(cmd.Short)
+				return
+			}
+
+			if args[0] == "all" {
+				l, err := sdk.Simulations(args[1])
+				if err != nil {
+					logError(err)
+					return
+				}
+				logJSON(l)
+				return
+			}
+
+			s, err := sdk.Simulation(args[0], args[1])
+			if err != nil {
+				logError(err)
+				return
+			}
+
+			logJSON(s)
+		},
+	},
+	cobra.Command{
+		Use:   "stop",
+		Short: "stop <simulation_id> <user_auth_token>",
+		Long:  `Stop running simulation`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) != 2 {
+				logUsage(cmd.Short)
+				return
+			}
+
+			if err := sdk.StopSimulation(args[0], args[1]); err != nil {
+				logError(err)
+				return
+			}
+
+			logOK()
+		},
+	},
+}
+
+// NewSimulationsCmd returns simulations command.
+func NewSimulationsCmd() *cobra.Command {
+	cmd := cobra.Command{
+		Use:   "simulations",
+		Short: "Device simulations management",
+		Long:  `Device simulations management: start, get or stop simulation of virtual devices`,
+		Run: func(cmd *cobra.Command, args []string) {
+			logUsage("simulations [start | get | stop]")
+		},
+	}
+
+	for i := range cmdSimulations {
+		cmd.AddCommand(&cmdSimulations[i])
+	}
+
+	return &cmd
+}
@@ -6,6 +6,7 @@ package cli
 import (
 	"encoding/json"
 	"fmt"
+	"os"
 
 	"github.com/fatih/color"
 	prettyjson "github.com/hokaccha/go-prettyjson"
@@ -53,3 +54,7 @@ func logOK() {
 func logCreated(e string) {
 	fmt.Printf(color.BlueString("\ncreated: %s\n\n"), e)
 }
+
+func logProgress(i, n int, e string) {
+	fmt.Fprintf(os.Stderr, color.CyanString("[%d/%d] %s\n"), i, n, e)
+}
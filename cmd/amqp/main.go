@@ -0,0 +1,361 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	"github.com/mainflux/mainflux"
+	adapter "github.com/mainflux/mainflux/amqp"
+	"github.com/mainflux/mainflux/amqp/api"
+	"github.com/mainflux/mainflux/amqp/nats"
+	"github.com/mainflux/mainflux/logger"
+	mfnats "github.com/mainflux/mainflux/nats"
+	"github.com/mainflux/mainflux/payload"
+	"github.com/mainflux/mainflux/things/api/auth/callback"
+	thingsapi "github.com/mainflux/mainflux/things/api/auth/grpc"
+	"github.com/mainflux/mainflux/writers/s3"
+	broker "github.com/nats-io/nats.go"
+	opentracing "github.com/opentracing/opentracing-go"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+	jconfig "github.com/uber/jaeger-client-go/config"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+const (
+	defClientTLS         = "false"
+	defCACerts           = ""
+	defPort              = "5672"
+	defHTTPPort          = "8204"
+	defLogLevel          = "error"
+	defServerCert        = ""
+	defServerKey         = ""
+	defNatsURL           = broker.DefaultURL
+	defNatsCreds         = ""
+	defNatsNKeySeed      = ""
+	defNatsCACerts       = ""
+	defNatsClientCert    = ""
+	defNatsClientKey     = ""
+	defNatsSubjectPrefix = ""
+	defThingsURL         = "localhost:8181"
+	defJaegerURL         = ""
+	defThingsTimeout     = "1" // in seconds
+	defCallbackURL       = ""
+	defCallbackTimeout   = "1" // in seconds
+	defMaxPayload        = "0"
+	defOversizePolicy    = "reject"
+	defSpillEndpoint     = ""
+	defSpillRegion       = "us-east-1"
+	defSpillBucket       = "spilled-payloads"
+	defSpillAccessKey    = ""
+	defSpillSecretKey    = ""
+	defSpillTimeout      = "10s"
+
+	envClientTLS         = "MF_AMQP_ADAPTER_CLIENT_TLS"
+	envCACerts           = "MF_AMQP_ADAPTER_CA_CERTS"
+	envPort              = "MF_AMQP_ADAPTER_PORT"
+	envHTTPPort          = "MF_AMQP_ADAPTER_HTTP_PORT"
+	envLogLevel          = "MF_AMQP_ADAPTER_LOG_LEVEL"
+	envServerCert        = "MF_AMQP_ADAPTER_SERVER_CERT"
+	envServerKey         = "MF_AMQP_ADAPTER_SERVER_KEY"
+	envNatsURL           = "MF_NATS_URL"
+	envNatsCreds         = "MF_NATS_CREDS"
+	envNatsNKeySeed      = "MF_NATS_NKEY_SEED"
+	envNatsCACerts       = "MF_NATS_CA_CERTS"
+	envNatsClientCert    = "MF_NATS_CLIENT_CERT"
+	envNatsClientKey     = "MF_NATS_CLIENT_KEY"
+	envNatsSubjectPrefix = "MF_NATS_SUBJECT_PREFIX"
+	envThingsURL         = "MF_THINGS_URL"
+	envJaegerURL         = "MF_JAEGER_URL"
+	envThingsTimeout     = "MF_AMQP_ADAPTER_THINGS_TIMEOUT"
+	envCallbackURL       = "MF_AMQP_ADAPTER_AUTH_CALLBACK_URL"
+	envCallbackTimeout   = "MF_AMQP_ADAPTER_AUTH_CALLBACK_TIMEOUT"
+	envMaxPayload        = "MF_AMQP_ADAPTER_MAX_PAYLOAD"
+	envOversizePolicy    = "MF_AMQP_ADAPTER_OVERSIZE_POLICY"
+	envSpillEndpoint     = "MF_AMQP_ADAPTER_SPILL_S3_ENDPOINT"
+	envSpillRegion       = "MF_AMQP_ADAPTER_SPILL_S3_REGION"
+	envSpillBucket       = "MF_AMQP_ADAPTER_SPILL_S3_BUCKET"
+	envSpillAccessKey    = "MF_AMQP_ADAPTER_SPILL_S3_ACCESS_KEY"
+	envSpillSecretKey    = "MF_AMQP_ADAPTER_SPILL_S3_SECRET_KEY"
+	envSpillTimeout      = "MF_AMQP_ADAPTER_SPILL_S3_TIMEOUT"
+)
+
+type config struct {
+	clientTLS       bool
+	caCerts         string
+	thingsURL       string
+	natsConfig      mfnats.Config
+	logLevel        string
+	port            string
+	httpPort        string
+	serverCert      string
+	serverKey       string
+	jaegerURL       string
+	thingsTimeout   time.Duration
+	callbackURL     string
+	callbackTimeout time.Duration
+	payload         payload.Config
+	spillConfig     s3.Config
+	spillTimeout    time.Duration
+}
+
+func main() {
+	cfg := loadConfig()
+
+	logger, err := logger.New(os.Stdout, cfg.logLevel)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	nc, err := mfnats.Connect(cfg.natsConfig)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to NATS: %s", err))
+		os.Exit(1)
+	}
+	defer nc.Close()
+
+	conn := connectToThings(cfg, logger)
+	defer conn.Close()
+
+	thingsTracer, thingsCloser := initJaeger("things", cfg.jaegerURL, logger)
+	defer thingsCloser.Close()
+
+	cc := thingsapi.NewClient(conn, thingsTracer, cfg.thingsTimeout)
+	cc = callback.NewClient(cc, cfg.callbackURL, cfg.callbackTimeout)
+
+	pubsub := nats.New(nc, cfg.natsConfig.Prefix, logger)
+	if cfg.payload.MaxSize > 0 {
+		pubsub = limitedPubSub{PubSub: pubsub, pub: newPayloadPublisher(cfg, pubsub, logger)}
+	}
+
+	svc := adapter.New(pubsub, cc)
+	svc = api.LoggingMiddleware(svc, logger)
+	svc = api.MetricsMiddleware(
+		svc,
+		kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: "amqp_adapter",
+			Subsystem: "api",
+			Name:      "request_count",
+			Help:      "Number of requests received.",
+		}, []string{"method"}),
+		kitprometheus.NewSummaryFrom(stdprometheus.SummaryOpts{
+			Namespace: "amqp_adapter",
+			Subsystem: "api",
+			Name:      "request_latency_microseconds",
+			Help:      "Total duration of requests in microseconds.",
+		}, []string{"method"}),
+	)
+
+	errs := make(chan error, 3)
+
+	go startHTTPServer(cfg.httpPort, logger, errs)
+	go startAMQPServer(svc, cfg, logger, errs)
+
+	go func() {
+		c := make(chan os.Signal, 1)
+		signal.Notify(c, syscall.SIGINT)
+		errs <- fmt.Errorf("%s", <-c)
+	}()
+
+	err = <-errs
+	logger.Error(fmt.Sprintf("AMQP adapter terminated: %s", err))
+}
+
+func loadConfig() config {
+	tls, err := strconv.ParseBool(mainflux.Env(envClientTLS, defClientTLS))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envClientTLS)
+	}
+
+	timeout, err := strconv.ParseInt(mainflux.Env(envThingsTimeout, defThingsTimeout), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envThingsTimeout, err.Error())
+	}
+
+	cbTimeout, err := strconv.ParseInt(mainflux.Env(envCallbackTimeout, defCallbackTimeout), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envCallbackTimeout, err.Error())
+	}
+
+	natsConfig := mfnats.Config{
+		URL:        mainflux.Env(envNatsURL, defNatsURL),
+		Creds:      mainflux.Env(envNatsCreds, defNatsCreds),
+		NKeySeed:   mainflux.Env(envNatsNKeySeed, defNatsNKeySeed),
+		CACerts:    mainflux.Env(envNatsCACerts, defNatsCACerts),
+		ClientCert: mainflux.Env(envNatsClientCert, defNatsClientCert),
+		ClientKey:  mainflux.Env(envNatsClientKey, defNatsClientKey),
+		Prefix:     mainflux.Env(envNatsSubjectPrefix, defNatsSubjectPrefix),
+	}
+
+	maxPayload, err := strconv.Atoi(mainflux.Env(envMaxPayload, defMaxPayload))
+	if err != nil || maxPayload < 0 {
+		log.Fatalf("Invalid value passed for %s\n", envMaxPayload)
+	}
+
+	policy, err := payload.ParsePolicy(mainflux.Env(envOversizePolicy, defOversizePolicy))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envOversizePolicy, err.Error())
+	}
+
+	spillTimeout, err := time.ParseDuration(mainflux.Env(envSpillTimeout, defSpillTimeout))
+	if err != nil || spillTimeout <= 0 {
+		log.Fatalf("Invalid value passed for %s\n", envSpillTimeout)
+	}
+
+	spillConfig := s3.Config{
+		Endpoint:  mainflux.Env(envSpillEndpoint, defSpillEndpoint),
+		Region:    mainflux.Env(envSpillRegion, defSpillRegion),
+		Bucket:    mainflux.Env(envSpillBucket, defSpillBucket),
+		AccessKey: mainflux.Env(envSpillAccessKey, defSpillAccessKey),
+		SecretKey: mainflux.Env(envSpillSecretKey, defSpillSecretKey),
+	}
+
+	return config{
+		clientTLS:       tls,
+		caCerts:         mainflux.Env(envCACerts, defCACerts),
+		thingsURL:       mainflux.Env(envThingsURL, defThingsURL),
+		natsConfig:      natsConfig,
+		logLevel:        mainflux.Env(envLogLevel, defLogLevel),
+		port:            mainflux.Env(envPort, defPort),
+		httpPort:        mainflux.Env(envHTTPPort, defHTTPPort),
+		serverCert:      mainflux.Env(envServerCert, defServerCert),
+		serverKey:       mainflux.Env(envServerKey, defServerKey),
+		jaegerURL:       mainflux.Env(envJaegerURL, defJaegerURL),
+		thingsTimeout:   time.Duration(timeout) * time.Second,
+		callbackURL:     mainflux.Env(envCallbackURL, defCallbackURL),
+		callbackTimeout: time.Duration(cbTimeout) * time.Second,
+		payload:         payload.Config{MaxSize: maxPayload, Policy: policy},
+		spillConfig:     spillConfig,
+		spillTimeout:    spillTimeout,
+	}
+}
+
+// newPayloadPublisher enforces the maximal payload size of the published
+// messages. Spill policy stores the oversized payloads to the object store.
+func newPayloadPublisher(cfg config, pub mainflux.MessagePublisher, logger logger.Logger) mainflux.MessagePublisher {
+	var store payload.Store
+	if cfg.payload.Policy == payload.Spill {
+		store = s3.NewClient(cfg.spillConfig, &http.Client{Timeout: cfg.spillTimeout})
+	}
+
+	violations := kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+		Namespace: "amqp_adapter",
+		Subsystem: "payload",
+		Name:      "oversized_count",
+		Help:      "Number of messages exceeding the maximal payload size.",
+	}, []string{"policy"})
+
+	lp, err := payload.New(pub, cfg.payload, store, violations, logger)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to create payload size limiter: %s", err))
+		os.Exit(1)
+	}
+
+	return lp
+}
+
+func connectToThings(cfg config, logger logger.Logger) *grpc.ClientConn {
+	var opts []grpc.DialOption
+	if cfg.clientTLS {
+		if cfg.caCerts != "" {
+			tpc, err := credentials.NewClientTLSFromFile(cfg.caCerts, "")
+			if err != nil {
+				logger.Error(fmt.Sprintf("Failed to load certs: %s", err))
+				os.Exit(1)
+			}
+			opts = append(opts, grpc.WithTransportCredentials(tpc))
+		}
+	} else {
+		logger.Info("gRPC communication is not encrypted")
+		opts = append(opts, grpc.WithInsecure())
+	}
+
+	conn, err := grpc.Dial(cfg.thingsURL, opts...)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to things service: %s", err))
+		os.Exit(1)
+	}
+	return conn
+}
+
+func initJaeger(svcName, url string, logger logger.Logger) (opentracing.Tracer, io.Closer) {
+	if url == "" {
+		return opentracing.NoopTracer{}, ioutil.NopCloser(nil)
+	}
+
+	tracer, closer, err := jconfig.Configuration{
+		ServiceName: svcName,
+		Sampler: &jconfig.SamplerConfig{
+			Type:  "const",
+			Param: 1,
+		},
+		Reporter: &jconfig.ReporterConfig{
+			LocalAgentHostPort: url,
+			LogSpans:           true,
+		},
+	}.NewTracer()
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to init Jaeger client: %s", err))
+		os.Exit(1)
+	}
+
+	return tracer, closer
+}
+
+func startHTTPServer(port string, logger logger.Logger, errs chan error) {
+	p := fmt.Sprintf(":%s", port)
+	logger.Info(fmt.Sprintf("AMQP adapter HTTP service started, exposed port %s", port))
+	errs <- http.ListenAndServe(p, api.MakeHTTPHandler())
+}
+
+func startAMQPServer(svc adapter.Service, cfg config, logger logger.Logger, errs chan error) {
+	p := fmt.Sprintf(":%s", cfg.port)
+
+	var listener net.Listener
+	var err error
+	if cfg.serverCert != "" || cfg.serverKey != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.serverCert, cfg.serverKey)
+		if err != nil {
+			logger.Error(fmt.Sprintf("Failed to load AMQP adapter certificates: %s", err))
+			os.Exit(1)
+		}
+		listener, err = tls.Listen("tcp", p, &tls.Config{Certificates: []tls.Certificate{cert}})
+		logger.Info(fmt.Sprintf("AMQP adapter service started using amqps on port %s with cert %s key %s",
+			cfg.port, cfg.serverCert, cfg.serverKey))
+	} else {
+		listener, err = net.Listen("tcp", p)
+		logger.Info(fmt.Sprintf("AMQP adapter service started using amqp on port %s", cfg.port))
+	}
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to listen on port %s: %s", cfg.port, err))
+		os.Exit(1)
+	}
+
+	errs <- api.Serve(listener, svc, logger)
+}
+
+// limitedPubSub enforces the maximal payload size of the published messages,
+// while the subscriptions are served unchanged.
+type limitedPubSub struct {
+	adapter.PubSub
+	pub mainflux.MessagePublisher
+}
+
+func (ps limitedPubSub) Publish(ctx context.Context, token string, msg mainflux.RawMessage) error {
+	return ps.pub.Publish(ctx, token, msg)
+}
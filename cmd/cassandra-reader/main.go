@@ -17,12 +17,15 @@ import (
 	"time"
 
 	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	"github.com/go-redis/redis"
 	"github.com/gocql/gocql"
 	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/logger"
 	"github.com/mainflux/mainflux/readers"
 	"github.com/mainflux/mainflux/readers/api"
 	"github.com/mainflux/mainflux/readers/cassandra"
+	subtopicsredis "github.com/mainflux/mainflux/readers/redis"
+	mfredis "github.com/mainflux/mainflux/redis"
 	thingsapi "github.com/mainflux/mainflux/things/api/auth/grpc"
 	opentracing "github.com/opentracing/opentracing-go"
 	stdprometheus "github.com/prometheus/client_golang/prometheus"
@@ -46,6 +49,9 @@ const (
 	defCACerts       = ""
 	defJaegerURL     = ""
 	defThingsTimeout = "1" // in seconds
+	defSubtopicsURL  = ""
+	defSubtopicsPass = ""
+	defSubtopicsDB   = "0"
 
 	envLogLevel      = "MF_CASSANDRA_READER_LOG_LEVEL"
 	envPort          = "MF_CASSANDRA_READER_PORT"
@@ -59,6 +65,9 @@ const (
 	envCACerts       = "MF_CASSANDRA_READER_CA_CERTS"
 	envJaegerURL     = "MF_JAEGER_URL"
 	envThingsTimeout = "MF_CASSANDRA_READER_THINGS_TIMEOUT"
+	envSubtopicsURL  = "MF_CASSANDRA_READER_SUBTOPICS_URL"
+	envSubtopicsPass = "MF_CASSANDRA_READER_SUBTOPICS_PASS"
+	envSubtopicsDB   = "MF_CASSANDRA_READER_SUBTOPICS_DB"
 )
 
 type config struct {
@@ -70,6 +79,9 @@ type config struct {
 	caCerts       string
 	jaegerURL     string
 	thingsTimeout time.Duration
+	subtopicsURL  string
+	subtopicsPass string
+	subtopicsDB   string
 }
 
 func main() {
@@ -92,9 +104,16 @@ func main() {
 	tc := thingsapi.NewClient(conn, thingsTracer, cfg.thingsTimeout)
 	repo := newService(session, logger)
 
+	var subtopics readers.SubtopicRepository
+	if cfg.subtopicsURL != "" {
+		subtopicsClient := connectToRedis(cfg.subtopicsURL, cfg.subtopicsPass, cfg.subtopicsDB, logger)
+		defer subtopicsClient.Close()
+		subtopics = subtopicsredis.NewSubtopicRepository(subtopicsClient)
+	}
+
 	errs := make(chan error, 2)
 
-	go startHTTPServer(repo, tc, cfg.port, errs, logger)
+	go startHTTPServer(repo, subtopics, tc, cfg.port, errs, logger)
 
 	go func() {
 		c := make(chan os.Signal)
@@ -139,6 +158,9 @@ func loadConfig() config {
 		caCerts:       mainflux.Env(envCACerts, defCACerts),
 		jaegerURL:     mainflux.Env(envJaegerURL, defJaegerURL),
 		thingsTimeout: time.Duration(timeout) * time.Second,
+		subtopicsURL:  mainflux.Env(envSubtopicsURL, defSubtopicsURL),
+		subtopicsPass: mainflux.Env(envSubtopicsPass, defSubtopicsPass),
+		subtopicsDB:   mainflux.Env(envSubtopicsDB, defSubtopicsDB),
 	}
 }
 
@@ -152,6 +174,22 @@ func connectToCassandra(dbCfg cassandra.DBConfig, logger logger.Logger) *gocql.S
 	return session
 }
 
+func connectToRedis(redisURL, redisPass, redisDB string, logger logger.Logger) redis.UniversalClient {
+	db, err := strconv.Atoi(redisDB)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to subtopics store: %s", err))
+		os.Exit(1)
+	}
+
+	client, err := mfredis.Connect(redisURL, redisPass, db)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to subtopics store: %s", err))
+		os.Exit(1)
+	}
+
+	return client
+}
+
 func connectToThings(cfg config, logger logger.Logger) *grpc.ClientConn {
 	var opts []grpc.DialOption
 	if cfg.clientTLS {
@@ -222,8 +260,8 @@ func newService(session *gocql.Session, logger logger.Logger) readers.MessageRep
 	return repo
 }
 
-func startHTTPServer(repo readers.MessageRepository, tc mainflux.ThingsServiceClient, port string, errs chan error, logger logger.Logger) {
+func startHTTPServer(repo readers.MessageRepository, subtopics readers.SubtopicRepository, tc mainflux.ThingsServiceClient, port string, errs chan error, logger logger.Logger) {
 	p := fmt.Sprintf(":%s", port)
 	logger.Info(fmt.Sprintf("Cassandra reader service started, exposed port %s", port))
-	errs <- http.ListenAndServe(p, api.MakeHandler(repo, tc, "cassandra-reader"))
+	errs <- http.ListenAndServe(p, api.MakeHandler(repo, subtopics, tc, "cassandra-reader"))
 }
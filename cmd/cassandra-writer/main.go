@@ -13,50 +13,137 @@ import (
 	"strconv"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/BurntSushi/toml"
 	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	"github.com/go-redis/redis"
 	"github.com/gocql/gocql"
 	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/logger"
+	mfnats "github.com/mainflux/mainflux/nats"
+	mfredis "github.com/mainflux/mainflux/redis"
+	thingsapi "github.com/mainflux/mainflux/things/api/auth/grpc"
+	"github.com/mainflux/mainflux/tracing"
 	"github.com/mainflux/mainflux/writers"
 	"github.com/mainflux/mainflux/writers/api"
 	"github.com/mainflux/mainflux/writers/cassandra"
-	nats "github.com/nats-io/go-nats"
+	"github.com/mainflux/mainflux/writers/deadletter"
+	"github.com/mainflux/mainflux/writers/enrich"
+	subtopicsredis "github.com/mainflux/mainflux/writers/redis"
+	nats "github.com/nats-io/nats.go"
+	opentracing "github.com/opentracing/opentracing-go"
 	stdprometheus "github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
 const (
 	svcName = "cassandra-writer"
 	sep     = ","
 
-	defNatsURL     = nats.DefaultURL
-	defLogLevel    = "error"
-	defPort        = "8180"
-	defCluster     = "127.0.0.1"
-	defKeyspace    = "mainflux"
-	defDBUsername  = ""
-	defDBPassword  = ""
-	defDBPort      = "9042"
-	defChanCfgPath = "/config/channels.toml"
-
-	envNatsURL     = "MF_NATS_URL"
-	envLogLevel    = "MF_CASSANDRA_WRITER_LOG_LEVEL"
-	envPort        = "MF_CASSANDRA_WRITER_PORT"
-	envCluster     = "MF_CASSANDRA_WRITER_DB_CLUSTER"
-	envKeyspace    = "MF_CASSANDRA_WRITER_DB_KEYSPACE"
-	envDBUsername  = "MF_CASSANDRA_WRITER_DB_USERNAME"
-	envDBPassword  = "MF_CASSANDRA_WRITER_DB_PASSWORD"
-	envDBPort      = "MF_CASSANDRA_WRITER_DB_PORT"
-	envChanCfgPath = "MF_CASSANDRA_WRITER_CHANNELS_CONFIG"
+	defNatsURL           = nats.DefaultURL
+	defJaegerURL         = ""
+	defNatsCreds         = ""
+	defNatsNKeySeed      = ""
+	defNatsCACerts       = ""
+	defNatsClientCert    = ""
+	defNatsClientKey     = ""
+	defNatsSubjectPrefix = ""
+	defNatsJetStream     = "false"
+	defNatsStream        = "MAINFLUX_MESSAGES"
+	defNatsStreamMaxAge  = "24h"
+	defNatsAckWait       = "30s"
+	defNatsMaxDeliver    = "0"
+	defLogLevel          = "error"
+	defPort              = "8180"
+	defCluster           = "127.0.0.1"
+	defKeyspace          = "mainflux"
+	defDBUsername        = ""
+	defDBPassword        = ""
+	defDBPort            = "9042"
+	defChanCfgPath       = "/config/channels.toml"
+	defThingsURL         = ""
+	defClientTLS         = "false"
+	defCACerts           = ""
+	defThingsTimeout     = "1" // in seconds
+	defRetentionRefresh  = "1m"
+	defReapPeriod        = "1h"
+	defBatchSize         = "1"
+	defBatchInterval     = "1s"
+	defEnrichURL         = ""
+	defEnrichTimeout     = "1s"
+	defEnrichBypass      = "true"
+	defDLQSubject        = ""
+	defDLQFile           = ""
+	defSubtopicsURL      = ""
+	defSubtopicsPass     = ""
+	defSubtopicsDB       = "0"
+
+	envNatsURL           = "MF_NATS_URL"
+	envJaegerURL         = "MF_JAEGER_URL"
+	envNatsCreds         = "MF_NATS_CREDS"
+	envNatsNKeySeed      = "MF_NATS_NKEY_SEED"
+	envNatsCACerts       = "MF_NATS_CA_CERTS"
+	envNatsClientCert    = "MF_NATS_CLIENT_CERT"
+	envNatsClientKey     = "MF_NATS_CLIENT_KEY"
+	envNatsSubjectPrefix = "MF_NATS_SUBJECT_PREFIX"
+	envNatsJetStream     = "MF_NATS_JETSTREAM"
+	envNatsStream        = "MF_NATS_JETSTREAM_STREAM"
+	envNatsStreamMaxAge  = "MF_NATS_JETSTREAM_MAX_AGE"
+	envNatsAckWait       = "MF_NATS_JETSTREAM_ACK_WAIT"
+	envNatsMaxDeliver    = "MF_NATS_JETSTREAM_MAX_DELIVER"
+	envLogLevel          = "MF_CASSANDRA_WRITER_LOG_LEVEL"
+	envPort              = "MF_CASSANDRA_WRITER_PORT"
+	envCluster           = "MF_CASSANDRA_WRITER_DB_CLUSTER"
+	envKeyspace          = "MF_CASSANDRA_WRITER_DB_KEYSPACE"
+	envDBUsername        = "MF_CASSANDRA_WRITER_DB_USERNAME"
+	envDBPassword        = "MF_CASSANDRA_WRITER_DB_PASSWORD"
+	envDBPort            = "MF_CASSANDRA_WRITER_DB_PORT"
+	envChanCfgPath       = "MF_CASSANDRA_WRITER_CHANNELS_CONFIG"
+	envThingsURL         = "MF_THINGS_URL"
+	envClientTLS         = "MF_CASSANDRA_WRITER_CLIENT_TLS"
+	envCACerts           = "MF_CASSANDRA_WRITER_CA_CERTS"
+	envThingsTimeout     = "MF_CASSANDRA_WRITER_THINGS_TIMEOUT"
+	envRetentionRefresh  = "MF_CASSANDRA_WRITER_RETENTION_REFRESH"
+	envReapPeriod        = "MF_CASSANDRA_WRITER_REAP_PERIOD"
+	envBatchSize         = "MF_CASSANDRA_WRITER_BATCH_SIZE"
+	envBatchInterval     = "MF_CASSANDRA_WRITER_BATCH_INTERVAL"
+	envEnrichURL         = "MF_CASSANDRA_WRITER_ENRICH_URL"
+	envEnrichTimeout     = "MF_CASSANDRA_WRITER_ENRICH_TIMEOUT"
+	envEnrichBypass      = "MF_CASSANDRA_WRITER_ENRICH_BYPASS"
+	envDLQSubject        = "MF_CASSANDRA_WRITER_DLQ_SUBJECT"
+	envDLQFile           = "MF_CASSANDRA_WRITER_DLQ_FILE"
+	envSubtopicsURL      = "MF_CASSANDRA_WRITER_SUBTOPICS_URL"
+	envSubtopicsPass     = "MF_CASSANDRA_WRITER_SUBTOPICS_PASS"
+	envSubtopicsDB       = "MF_CASSANDRA_WRITER_SUBTOPICS_DB"
 )
 
 type config struct {
-	natsURL  string
-	logLevel string
-	port     string
-	dbCfg    cassandra.DBConfig
-	channels map[string]bool
+	natsConfig       mfnats.Config
+	jaegerURL        string
+	jetStream        bool
+	jsConfig         writers.JetStreamConfig
+	logLevel         string
+	port             string
+	dbCfg            cassandra.DBConfig
+	channels         map[string]bool
+	thingsURL        string
+	clientTLS        bool
+	caCerts          string
+	thingsTimeout    time.Duration
+	retentionRefresh time.Duration
+	reapPeriod       time.Duration
+	batchSize        int
+	batchInterval    time.Duration
+	enrichURL        string
+	enrichTimeout    time.Duration
+	enrichBypass     bool
+	dlqSubject       string
+	dlqFile          string
+	subtopicsURL     string
+	subtopicsPass    string
+	subtopicsDB      string
 }
 
 func main() {
@@ -67,14 +154,36 @@ func main() {
 		log.Fatalf(err.Error())
 	}
 
-	nc := connectToNATS(cfg.natsURL, logger)
+	nc := connectToNATS(cfg.natsConfig, logger)
 	defer nc.Close()
 
 	session := connectToCassandra(cfg.dbCfg, logger)
 	defer session.Close()
 
 	repo := newService(session, logger)
-	if err := writers.Start(nc, repo, svcName, cfg.channels, logger); err != nil {
+	if cfg.subtopicsURL != "" {
+		subtopicsClient := connectToRedis(cfg.subtopicsURL, cfg.subtopicsPass, cfg.subtopicsDB, logger)
+		defer subtopicsClient.Close()
+		repo = writers.NewSubtopicTracker(repo, subtopicsredis.NewSubtopicRepository(subtopicsClient), logger)
+	}
+	if cfg.dlqSubject != "" || cfg.dlqFile != "" {
+		repo = writers.NewDeadLetterRepository(repo, newDeadLetters(cfg, nc), logger)
+	}
+	repo = writers.NewBatcher(repo, cfg.batchSize, cfg.batchInterval, logger)
+	if cfg.enrichURL != "" {
+		repo = newEnrichingRepository(repo, cfg, logger)
+	}
+	var retainer writers.Retainer
+	if cfg.thingsURL != "" {
+		retention := cassandra.NewRetentionRepository(session)
+		retainer = newRetainer(cfg, retention, logger)
+		go startReaping(retention, cfg.reapPeriod, logger)
+	}
+
+	tracer, closer := tracing.Init(svcName, cfg.jaegerURL, logger)
+	defer closer.Close()
+
+	if err := startWriter(nc, repo, retainer, cfg, tracer, logger); err != nil {
 		logger.Error(fmt.Sprintf("Failed to create Cassandra writer: %s", err))
 	}
 
@@ -106,13 +215,109 @@ func loadConfig() config {
 		Port:     dbPort,
 	}
 
+	tls, err := strconv.ParseBool(mainflux.Env(envClientTLS, defClientTLS))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envClientTLS)
+	}
+
+	timeout, err := strconv.ParseInt(mainflux.Env(envThingsTimeout, defThingsTimeout), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envThingsTimeout, err.Error())
+	}
+
+	retentionRefresh, err := time.ParseDuration(mainflux.Env(envRetentionRefresh, defRetentionRefresh))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envRetentionRefresh)
+	}
+
+	reapPeriod, err := time.ParseDuration(mainflux.Env(envReapPeriod, defReapPeriod))
+	if err != nil || reapPeriod <= 0 {
+		log.Fatalf("Invalid value passed for %s\n", envReapPeriod)
+	}
+
+	batchSize, err := strconv.Atoi(mainflux.Env(envBatchSize, defBatchSize))
+	if err != nil || batchSize <= 0 {
+		log.Fatalf("Invalid value passed for %s\n", envBatchSize)
+	}
+
+	batchInterval, err := time.ParseDuration(mainflux.Env(envBatchInterval, defBatchInterval))
+	if err != nil || batchInterval <= 0 {
+		log.Fatalf("Invalid value passed for %s\n", envBatchInterval)
+	}
+
+	enrichTimeout, err := time.ParseDuration(mainflux.Env(envEnrichTimeout, defEnrichTimeout))
+	if err != nil || enrichTimeout <= 0 {
+		log.Fatalf("Invalid value passed for %s\n", envEnrichTimeout)
+	}
+
+	enrichBypass, err := strconv.ParseBool(mainflux.Env(envEnrichBypass, defEnrichBypass))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envEnrichBypass)
+	}
+
 	chanCfgPath := mainflux.Env(envChanCfgPath, defChanCfgPath)
+	jetStream, err := strconv.ParseBool(mainflux.Env(envNatsJetStream, defNatsJetStream))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envNatsJetStream)
+	}
+
+	streamMaxAge, err := time.ParseDuration(mainflux.Env(envNatsStreamMaxAge, defNatsStreamMaxAge))
+	if err != nil || streamMaxAge < 0 {
+		log.Fatalf("Invalid value passed for %s\n", envNatsStreamMaxAge)
+	}
+
+	ackWait, err := time.ParseDuration(mainflux.Env(envNatsAckWait, defNatsAckWait))
+	if err != nil || ackWait <= 0 {
+		log.Fatalf("Invalid value passed for %s\n", envNatsAckWait)
+	}
+
+	maxDeliver, err := strconv.Atoi(mainflux.Env(envNatsMaxDeliver, defNatsMaxDeliver))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envNatsMaxDeliver)
+	}
+
+	jsConfig := writers.JetStreamConfig{
+		Stream:     mainflux.Env(envNatsStream, defNatsStream),
+		MaxAge:     streamMaxAge,
+		AckWait:    ackWait,
+		MaxDeliver: maxDeliver,
+	}
+
+	natsConfig := mfnats.Config{
+		URL:        mainflux.Env(envNatsURL, defNatsURL),
+		Creds:      mainflux.Env(envNatsCreds, defNatsCreds),
+		NKeySeed:   mainflux.Env(envNatsNKeySeed, defNatsNKeySeed),
+		CACerts:    mainflux.Env(envNatsCACerts, defNatsCACerts),
+		ClientCert: mainflux.Env(envNatsClientCert, defNatsClientCert),
+		ClientKey:  mainflux.Env(envNatsClientKey, defNatsClientKey),
+		Prefix:     mainflux.Env(envNatsSubjectPrefix, defNatsSubjectPrefix),
+	}
+
 	return config{
-		natsURL:  mainflux.Env(envNatsURL, defNatsURL),
-		logLevel: mainflux.Env(envLogLevel, defLogLevel),
-		port:     mainflux.Env(envPort, defPort),
-		dbCfg:    dbCfg,
-		channels: loadChansConfig(chanCfgPath),
+		natsConfig:       natsConfig,
+		jaegerURL:        mainflux.Env(envJaegerURL, defJaegerURL),
+		jetStream:        jetStream,
+		jsConfig:         jsConfig,
+		logLevel:         mainflux.Env(envLogLevel, defLogLevel),
+		port:             mainflux.Env(envPort, defPort),
+		dbCfg:            dbCfg,
+		channels:         loadChansConfig(chanCfgPath),
+		thingsURL:        mainflux.Env(envThingsURL, defThingsURL),
+		clientTLS:        tls,
+		caCerts:          mainflux.Env(envCACerts, defCACerts),
+		thingsTimeout:    time.Duration(timeout) * time.Second,
+		retentionRefresh: retentionRefresh,
+		reapPeriod:       reapPeriod,
+		batchSize:        batchSize,
+		batchInterval:    batchInterval,
+		enrichURL:        mainflux.Env(envEnrichURL, defEnrichURL),
+		enrichTimeout:    enrichTimeout,
+		enrichBypass:     enrichBypass,
+		dlqSubject:       mainflux.Env(envDLQSubject, defDLQSubject),
+		dlqFile:          mainflux.Env(envDLQFile, defDLQFile),
+		subtopicsURL:     mainflux.Env(envSubtopicsURL, defSubtopicsURL),
+		subtopicsPass:    mainflux.Env(envSubtopicsPass, defSubtopicsPass),
+		subtopicsDB:      mainflux.Env(envSubtopicsDB, defSubtopicsDB),
 	}
 }
 
@@ -143,8 +348,8 @@ func loadChansConfig(chanConfigPath string) map[string]bool {
 	return chans
 }
 
-func connectToNATS(url string, logger logger.Logger) *nats.Conn {
-	nc, err := nats.Connect(url)
+func connectToNATS(cfg mfnats.Config, logger logger.Logger) *nats.Conn {
+	nc, err := mfnats.Connect(cfg)
 	if err != nil {
 		logger.Error(fmt.Sprintf("Failed to connect to NATS: %s", err))
 		os.Exit(1)
@@ -190,3 +395,92 @@ func startHTTPServer(port string, errs chan error, logger logger.Logger) {
 	logger.Info(fmt.Sprintf("Cassandra writer service started, exposed port %s", port))
 	errs <- http.ListenAndServe(p, api.MakeHandler(svcName))
 }
+
+func newDeadLetters(cfg config, nc *nats.Conn) writers.DeadLetters {
+	if cfg.dlqSubject != "" {
+		return deadletter.NewNATSDeadLetters(nc, mfnats.Subject(cfg.natsConfig.Prefix, cfg.dlqSubject))
+	}
+
+	return deadletter.NewFileDeadLetters(cfg.dlqFile)
+}
+
+func newEnrichingRepository(repo writers.MessageRepository, cfg config, logger logger.Logger) writers.MessageRepository {
+	enricher, err := enrich.New(cfg.enrichURL, cfg.enrichTimeout)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to create message enricher: %s", err))
+		os.Exit(1)
+	}
+
+	return writers.NewEnrichingRepository(repo, enricher, cfg.enrichTimeout, cfg.enrichBypass, logger)
+}
+
+func newRetainer(cfg config, repo writers.RetentionRepository, logger logger.Logger) writers.Retainer {
+	conn := connectToThings(cfg, logger)
+	tc := thingsapi.NewClient(conn, opentracing.NoopTracer{}, cfg.thingsTimeout)
+	return writers.NewRetainer(tc, repo, cfg.retentionRefresh, logger)
+}
+
+func connectToRedis(redisURL, redisPass, redisDB string, logger logger.Logger) redis.UniversalClient {
+	db, err := strconv.Atoi(redisDB)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to subtopics store: %s", err))
+		os.Exit(1)
+	}
+
+	client, err := mfredis.Connect(redisURL, redisPass, db)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to subtopics store: %s", err))
+		os.Exit(1)
+	}
+
+	return client
+}
+
+func connectToThings(cfg config, logger logger.Logger) *grpc.ClientConn {
+	var opts []grpc.DialOption
+	if cfg.clientTLS {
+		if cfg.caCerts != "" {
+			tpc, err := credentials.NewClientTLSFromFile(cfg.caCerts, "")
+			if err != nil {
+				logger.Error(fmt.Sprintf("Failed to load certs: %s", err))
+				os.Exit(1)
+			}
+			opts = append(opts, grpc.WithTransportCredentials(tpc))
+		}
+	} else {
+		logger.Info("gRPC communication is not encrypted")
+		opts = append(opts, grpc.WithInsecure())
+	}
+
+	conn, err := grpc.Dial(cfg.thingsURL, opts...)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to things service: %s", err))
+		os.Exit(1)
+	}
+	return conn
+}
+
+func startReaping(repo writers.RetentionRepository, period time.Duration, logger logger.Logger) {
+	logger.Info(fmt.Sprintf("Reaping messages outside of channels retention every %s", period))
+	for {
+		if err := repo.Reap(time.Now()); err != nil {
+			logger.Error(fmt.Sprintf("Failed to reap messages: %s", err))
+		}
+		time.Sleep(period)
+	}
+}
+
+func startWriter(nc *nats.Conn, repo writers.MessageRepository, retainer writers.Retainer, cfg config, tracer opentracing.Tracer, logger logger.Logger) error {
+	if !cfg.jetStream {
+		return writers.Start(nc, cfg.natsConfig.Prefix, repo, retainer, svcName, cfg.channels, tracer, logger)
+	}
+
+	lag := kitprometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+		Namespace: "cassandra",
+		Subsystem: "message_writer",
+		Name:      "consumer_lag",
+		Help:      "Number of messages in the stream not delivered to the writer yet.",
+	}, []string{})
+
+	return writers.StartJetStream(nc, cfg.jsConfig, cfg.natsConfig.Prefix, repo, retainer, svcName, cfg.channels, lag, tracer, logger)
+}
@@ -0,0 +1,304 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	"github.com/jmoiron/sqlx"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/certs"
+	"github.com/mainflux/mainflux/certs/api"
+	"github.com/mainflux/mainflux/certs/pki"
+	"github.com/mainflux/mainflux/certs/postgres"
+	"github.com/mainflux/mainflux/certs/things"
+	"github.com/mainflux/mainflux/certs/vault"
+	"github.com/mainflux/mainflux/logger"
+	mfsdk "github.com/mainflux/mainflux/sdk/go"
+	usersapi "github.com/mainflux/mainflux/users/api/grpc"
+	opentracing "github.com/opentracing/opentracing-go"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+const (
+	pkiBackend   = "pki"
+	vaultBackend = "vault"
+
+	defLogLevel      = "error"
+	defHTTPPort      = "8210"
+	defDBHost        = "localhost"
+	defDBPort        = "5432"
+	defDBUser        = "mainflux"
+	defDBPass        = "mainflux"
+	defDBName        = "certs"
+	defDBSSLMode     = "disable"
+	defDBSSLCert     = ""
+	defDBSSLKey      = ""
+	defDBSSLRootCert = ""
+	defUsersURL      = "localhost:8181"
+	defUsersTimeout  = "1" // in seconds
+	defClientTLS     = "false"
+	defCACerts       = ""
+	defBaseURL       = "http://localhost"
+	defThingsPrefix  = ""
+	defTTL           = "8760h"
+	defMaxTTL        = "43800h"
+	defCABackend     = pkiBackend
+	defCACert        = "ca.crt"
+	defCAKey         = "ca.key"
+	defCRLTTL        = "24h"
+	defVaultAddr     = "http://localhost:8200"
+	defVaultToken    = ""
+	defVaultPKIPath  = "pki"
+	defVaultRole     = "mainflux"
+	defVaultTimeout  = "5s"
+
+	envLogLevel      = "MF_CERTS_LOG_LEVEL"
+	envHTTPPort      = "MF_CERTS_HTTP_PORT"
+	envDBHost        = "MF_CERTS_DB_HOST"
+	envDBPort        = "MF_CERTS_DB_PORT"
+	envDBUser        = "MF_CERTS_DB_USER"
+	envDBPass        = "MF_CERTS_DB_PASS"
+	envDBName        = "MF_CERTS_DB"
+	envDBSSLMode     = "MF_CERTS_DB_SSL_MODE"
+	envDBSSLCert     = "MF_CERTS_DB_SSL_CERT"
+	envDBSSLKey      = "MF_CERTS_DB_SSL_KEY"
+	envDBSSLRootCert = "MF_CERTS_DB_SSL_ROOT_CERT"
+	envUsersURL      = "MF_USERS_URL"
+	envUsersTimeout  = "MF_CERTS_USERS_TIMEOUT"
+	envClientTLS     = "MF_CERTS_CLIENT_TLS"
+	envCACerts       = "MF_CERTS_CA_CERTS"
+	envBaseURL       = "MF_SDK_BASE_URL"
+	envThingsPrefix  = "MF_SDK_THINGS_PREFIX"
+	envTTL           = "MF_CERTS_TTL"
+	envMaxTTL        = "MF_CERTS_MAX_TTL"
+	envCABackend     = "MF_CERTS_CA_BACKEND"
+	envCACert        = "MF_CERTS_SIGN_CA_CERT"
+	envCAKey         = "MF_CERTS_SIGN_CA_KEY"
+	envCRLTTL        = "MF_CERTS_CRL_TTL"
+	envVaultAddr     = "MF_CERTS_VAULT_ADDR"
+	envVaultToken    = "MF_CERTS_VAULT_TOKEN"
+	envVaultPKIPath  = "MF_CERTS_VAULT_PKI_PATH"
+	envVaultRole     = "MF_CERTS_VAULT_ROLE"
+	envVaultTimeout  = "MF_CERTS_VAULT_TIMEOUT"
+)
+
+type config struct {
+	logLevel     string
+	httpPort     string
+	dbConfig     postgres.Config
+	usersURL     string
+	usersTimeout time.Duration
+	clientTLS    bool
+	caCerts      string
+	baseURL      string
+	thingsPrefix string
+	certsConfig  certs.Config
+	caBackend    string
+	caCert       string
+	caKey        string
+	crlTTL       time.Duration
+	vaultAddr    string
+	vaultToken   string
+	vaultPKIPath string
+	vaultRole    string
+	vaultTimeout time.Duration
+}
+
+func main() {
+	cfg := loadConfig()
+
+	logger, err := logger.New(os.Stdout, cfg.logLevel)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	db := connectToDB(cfg.dbConfig, logger)
+	defer db.Close()
+
+	conn := connectToUsers(cfg, logger)
+	defer conn.Close()
+
+	users := usersapi.NewClient(opentracing.NoopTracer{}, conn, cfg.usersTimeout)
+	svc := newService(users, db, cfg, logger)
+
+	errs := make(chan error, 2)
+
+	go startHTTPServer(svc, cfg.httpPort, logger, errs)
+
+	go func() {
+		c := make(chan os.Signal, 1)
+		signal.Notify(c, syscall.SIGINT)
+		errs <- fmt.Errorf("%s", <-c)
+	}()
+
+	err = <-errs
+	logger.Error(fmt.Sprintf("Certs service terminated: %s", err))
+}
+
+func loadConfig() config {
+	tls, err := strconv.ParseBool(mainflux.Env(envClientTLS, defClientTLS))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envClientTLS)
+	}
+
+	timeout, err := strconv.ParseInt(mainflux.Env(envUsersTimeout, defUsersTimeout), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envUsersTimeout, err.Error())
+	}
+
+	ttl, err := time.ParseDuration(mainflux.Env(envTTL, defTTL))
+	if err != nil || ttl <= 0 {
+		log.Fatalf("Invalid value passed for %s\n", envTTL)
+	}
+
+	maxTTL, err := time.ParseDuration(mainflux.Env(envMaxTTL, defMaxTTL))
+	if err != nil || maxTTL < ttl {
+		log.Fatalf("Invalid value passed for %s\n", envMaxTTL)
+	}
+
+	crlTTL, err := time.ParseDuration(mainflux.Env(envCRLTTL, defCRLTTL))
+	if err != nil || crlTTL <= 0 {
+		log.Fatalf("Invalid value passed for %s\n", envCRLTTL)
+	}
+
+	vaultTimeout, err := time.ParseDuration(mainflux.Env(envVaultTimeout, defVaultTimeout))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envVaultTimeout, err.Error())
+	}
+
+	backend := mainflux.Env(envCABackend, defCABackend)
+	if backend != pkiBackend && backend != vaultBackend {
+		log.Fatalf("Invalid value passed for %s\n", envCABackend)
+	}
+
+	dbConfig := postgres.Config{
+		Host:        mainflux.Env(envDBHost, defDBHost),
+		Port:        mainflux.Env(envDBPort, defDBPort),
+		User:        mainflux.Env(envDBUser, defDBUser),
+		Pass:        mainflux.Env(envDBPass, defDBPass),
+		Name:        mainflux.Env(envDBName, defDBName),
+		SSLMode:     mainflux.Env(envDBSSLMode, defDBSSLMode),
+		SSLCert:     mainflux.Env(envDBSSLCert, defDBSSLCert),
+		SSLKey:      mainflux.Env(envDBSSLKey, defDBSSLKey),
+		SSLRootCert: mainflux.Env(envDBSSLRootCert, defDBSSLRootCert),
+	}
+
+	return config{
+		logLevel:     mainflux.Env(envLogLevel, defLogLevel),
+		httpPort:     mainflux.Env(envHTTPPort, defHTTPPort),
+		dbConfig:     dbConfig,
+		usersURL:     mainflux.Env(envUsersURL, defUsersURL),
+		usersTimeout: time.Duration(timeout) * time.Second,
+		clientTLS:    tls,
+		caCerts:      mainflux.Env(envCACerts, defCACerts),
+		baseURL:      mainflux.Env(envBaseURL, defBaseURL),
+		thingsPrefix: mainflux.Env(envThingsPrefix, defThingsPrefix),
+		certsConfig:  certs.Config{TTL: ttl, MaxTTL: maxTTL},
+		caBackend:    backend,
+		caCert:       mainflux.Env(envCACert, defCACert),
+		caKey:        mainflux.Env(envCAKey, defCAKey),
+		crlTTL:       crlTTL,
+		vaultAddr:    mainflux.Env(envVaultAddr, defVaultAddr),
+		vaultToken:   mainflux.Env(envVaultToken, defVaultToken),
+		vaultPKIPath: mainflux.Env(envVaultPKIPath, defVaultPKIPath),
+		vaultRole:    mainflux.Env(envVaultRole, defVaultRole),
+		vaultTimeout: vaultTimeout,
+	}
+}
+
+func connectToDB(dbConfig postgres.Config, logger logger.Logger) *sqlx.DB {
+	db, err := postgres.Connect(dbConfig)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to Postgres: %s", err))
+		os.Exit(1)
+	}
+	return db
+}
+
+func connectToUsers(cfg config, logger logger.Logger) *grpc.ClientConn {
+	var opts []grpc.DialOption
+	if cfg.clientTLS {
+		if cfg.caCerts != "" {
+			tpc, err := credentials.NewClientTLSFromFile(cfg.caCerts, "")
+			if err != nil {
+				logger.Error(fmt.Sprintf("Failed to create tls credentials: %s", err))
+				os.Exit(1)
+			}
+			opts = append(opts, grpc.WithTransportCredentials(tpc))
+		}
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+		logger.Info("gRPC communication is not encrypted")
+	}
+
+	conn, err := grpc.Dial(cfg.usersURL, opts...)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to users service: %s", err))
+		os.Exit(1)
+	}
+
+	return conn
+}
+
+func newCA(cfg config, logger logger.Logger) certs.CA {
+	if cfg.caBackend == vaultBackend {
+		logger.Info(fmt.Sprintf("Issuing certificates using Vault PKI mounted at %s", cfg.vaultPKIPath))
+		return vault.New(cfg.vaultAddr, cfg.vaultToken, cfg.vaultPKIPath, cfg.vaultRole, cfg.vaultTimeout)
+	}
+
+	ca, err := pki.Load(cfg.caCert, cfg.caKey, cfg.crlTTL)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to load CA certificate and key: %s", err))
+		os.Exit(1)
+	}
+
+	return ca
+}
+
+func newService(users mainflux.UsersServiceClient, db *sqlx.DB, cfg config, logger logger.Logger) certs.Service {
+	sdk := mfsdk.NewSDK(mfsdk.Config{
+		BaseURL:      cfg.baseURL,
+		ThingsPrefix: cfg.thingsPrefix,
+	})
+
+	repo := postgres.NewCertRepository(db)
+	ca := newCA(cfg, logger)
+
+	svc := certs.New(users, things.New(sdk), repo, ca, cfg.certsConfig)
+	svc = api.LoggingMiddleware(svc, logger)
+	svc = api.MetricsMiddleware(
+		svc,
+		kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: "certs",
+			Subsystem: "api",
+			Name:      "request_count",
+			Help:      "Number of requests received.",
+		}, []string{"method"}),
+		kitprometheus.NewSummaryFrom(stdprometheus.SummaryOpts{
+			Namespace: "certs",
+			Subsystem: "api",
+			Name:      "request_latency_microseconds",
+			Help:      "Total duration of requests in microseconds.",
+		}, []string{"method"}),
+	)
+
+	return svc
+}
+
+func startHTTPServer(svc certs.Service, port string, logger logger.Logger, errs chan error) {
+	p := fmt.Sprintf(":%s", port)
+	logger.Info(fmt.Sprintf("Certs service started, exposed port %s", port))
+	errs <- http.ListenAndServe(p, api.MakeHandler(svc))
+}
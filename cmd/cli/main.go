@@ -17,6 +17,8 @@ func main() {
 		BaseURL:           "http://localhost",
 		ReaderURL:         "http://localhost:8905",
 		ReaderPrefix:      "",
+		SimulatorURL:      "http://localhost:8196",
+		BootstrapURL:      "http://localhost:8202",
 		UsersPrefix:       "",
 		ThingsPrefix:      "",
 		HTTPAdapterPrefix: "http",
@@ -41,6 +43,9 @@ func main() {
 	channelsCmd := cli.NewChannelsCmd()
 	messagesCmd := cli.NewMessagesCmd()
 	provisionCmd := cli.NewProvisionCmd()
+	simulationsCmd := cli.NewSimulationsCmd()
+	backupCmd := cli.NewBackupCmd()
+	restoreCmd := cli.NewRestoreCmd()
 
 	// Root Commands
 	rootCmd.AddCommand(versionCmd)
@@ -49,6 +54,9 @@ func main() {
 	rootCmd.AddCommand(channelsCmd)
 	rootCmd.AddCommand(messagesCmd)
 	rootCmd.AddCommand(provisionCmd)
+	rootCmd.AddCommand(simulationsCmd)
+	rootCmd.AddCommand(backupCmd)
+	rootCmd.AddCommand(restoreCmd)
 
 	// Root Flags
 	rootCmd.PersistentFlags().StringVarP(
@@ -83,6 +91,22 @@ func main() {
 		"Mainflux http adapter prefix",
 	)
 
+	rootCmd.PersistentFlags().StringVarP(
+		&sdkConf.SimulatorURL,
+		"simulator-url",
+		"s",
+		sdkConf.SimulatorURL,
+		"Mainflux simulator service URL",
+	)
+
+	rootCmd.PersistentFlags().StringVarP(
+		&sdkConf.BootstrapURL,
+		"bootstrap-url",
+		"b",
+		sdkConf.BootstrapURL,
+		"Mainflux bootstrap service URL",
+	)
+
 	rootCmd.PersistentFlags().StringVarP(
 		&msgContentType,
 		"content-type",
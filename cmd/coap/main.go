@@ -4,9 +4,8 @@
 package main
 
 import (
+	"context"
 	"fmt"
-	"io"
-	"io/ioutil"
 	"log"
 	"net/http"
 	"os"
@@ -15,55 +14,173 @@ import (
 	"syscall"
 	"time"
 
-	gocoap "github.com/dustin/go-coap"
 	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	"github.com/go-redis/redis"
 	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/coap"
 	"github.com/mainflux/mainflux/coap/api"
 	"github.com/mainflux/mainflux/coap/nats"
 	logger "github.com/mainflux/mainflux/logger"
+	mfnats "github.com/mainflux/mainflux/nats"
+	"github.com/mainflux/mainflux/ordering"
+	orderingredis "github.com/mainflux/mainflux/ordering/redis"
+	"github.com/mainflux/mainflux/payload"
+	"github.com/mainflux/mainflux/pkg/events/consumer"
+	"github.com/mainflux/mainflux/presence"
+	"github.com/mainflux/mainflux/presence/redis/producer"
+	mfredis "github.com/mainflux/mainflux/redis"
+	"github.com/mainflux/mainflux/routing"
+	routingnats "github.com/mainflux/mainflux/routing/nats"
+	"github.com/mainflux/mainflux/things/api/auth/cache"
+	cacheredis "github.com/mainflux/mainflux/things/api/auth/cache/redis"
+	"github.com/mainflux/mainflux/things/api/auth/callback"
 	thingsapi "github.com/mainflux/mainflux/things/api/auth/grpc"
-	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/mainflux/mainflux/tracing"
+	"github.com/mainflux/mainflux/writers/s3"
 	stdprometheus "github.com/prometheus/client_golang/prometheus"
-	jconfig "github.com/uber/jaeger-client-go/config"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 
-	broker "github.com/nats-io/go-nats"
+	broker "github.com/nats-io/nats.go"
 )
 
 const (
-	defPort          = "5683"
-	defNatsURL       = broker.DefaultURL
-	defThingsURL     = "localhost:8181"
-	defLogLevel      = "error"
-	defClientTLS     = "false"
-	defCACerts       = ""
-	defPingPeriod    = "12"
-	defJaegerURL     = ""
-	defThingsTimeout = "1" // in seconds
-
-	envPort          = "MF_COAP_ADAPTER_PORT"
-	envNatsURL       = "MF_NATS_URL"
-	envThingsURL     = "MF_THINGS_URL"
-	envLogLevel      = "MF_COAP_ADAPTER_LOG_LEVEL"
-	envClientTLS     = "MF_COAP_ADAPTER_CLIENT_TLS"
-	envCACerts       = "MF_COAP_ADAPTER_CA_CERTS"
-	envPingPeriod    = "MF_COAP_ADAPTER_PING_PERIOD"
-	envJaegerURL     = "MF_JAEGER_URL"
-	envThingsTimeout = "MF_COAP_ADAPTER_THINGS_TIMEOUT"
+	defPort              = "5683"
+	defNatsURL           = broker.DefaultURL
+	defNatsCreds         = ""
+	defNatsNKeySeed      = ""
+	defNatsCACerts       = ""
+	defNatsClientCert    = ""
+	defNatsClientKey     = ""
+	defNatsSubjectPrefix = ""
+	defThingsURL         = "localhost:8181"
+	defLogLevel          = "error"
+	defClientTLS         = "false"
+	defCACerts           = ""
+	defPingPeriod        = "12"
+	defJaegerURL         = ""
+	defThingsTimeout     = "1" // in seconds
+	defAuthCacheSize     = "0"
+	defAuthCacheTTL      = "1m"
+	defAuthCacheURL      = ""
+	defAuthCachePass     = ""
+	defAuthCacheDB       = "0"
+	defThingsESURL       = "localhost:6379"
+	defThingsESPass      = ""
+	defThingsESDB        = "0"
+	defCallbackURL       = ""
+	defCallbackTimeout   = "1" // in seconds
+	defRegion            = ""
+	defFederationLinks   = ""
+	defRegionRefresh     = "1m"
+	defLinkProbePeriod   = "10s"
+	defSequencerURL      = ""
+	defSequencerPass     = ""
+	defSequencerDB       = "0"
+	defOrderingRefresh   = "1m"
+	defMaxPayload        = "0"
+	defOversizePolicy    = "reject"
+	defSpillEndpoint     = ""
+	defSpillRegion       = "us-east-1"
+	defSpillBucket       = "spilled-payloads"
+	defSpillAccessKey    = ""
+	defSpillSecretKey    = ""
+	defSpillTimeout      = "10s"
+	defESURL             = ""
+	defESPass            = ""
+	defESDB              = "0"
+	defDTLSPort          = "5684"
+	defDTLSSecret        = ""
+	defPlaintext         = "true"
+
+	envPort              = "MF_COAP_ADAPTER_PORT"
+	envNatsURL           = "MF_NATS_URL"
+	envNatsCreds         = "MF_NATS_CREDS"
+	envNatsNKeySeed      = "MF_NATS_NKEY_SEED"
+	envNatsCACerts       = "MF_NATS_CA_CERTS"
+	envNatsClientCert    = "MF_NATS_CLIENT_CERT"
+	envNatsClientKey     = "MF_NATS_CLIENT_KEY"
+	envNatsSubjectPrefix = "MF_NATS_SUBJECT_PREFIX"
+	envThingsURL         = "MF_THINGS_URL"
+	envLogLevel          = "MF_COAP_ADAPTER_LOG_LEVEL"
+	envClientTLS         = "MF_COAP_ADAPTER_CLIENT_TLS"
+	envCACerts           = "MF_COAP_ADAPTER_CA_CERTS"
+	envPingPeriod        = "MF_COAP_ADAPTER_PING_PERIOD"
+	envJaegerURL         = "MF_JAEGER_URL"
+	envThingsTimeout     = "MF_COAP_ADAPTER_THINGS_TIMEOUT"
+	envAuthCacheSize     = "MF_COAP_ADAPTER_AUTH_CACHE_SIZE"
+	envAuthCacheTTL      = "MF_COAP_ADAPTER_AUTH_CACHE_TTL"
+	envAuthCacheURL      = "MF_COAP_ADAPTER_AUTH_CACHE_URL"
+	envAuthCachePass     = "MF_COAP_ADAPTER_AUTH_CACHE_PASS"
+	envAuthCacheDB       = "MF_COAP_ADAPTER_AUTH_CACHE_DB"
+	envThingsESURL       = "MF_COAP_ADAPTER_THINGS_ES_URL"
+	envThingsESPass      = "MF_COAP_ADAPTER_THINGS_ES_PASS"
+	envThingsESDB        = "MF_COAP_ADAPTER_THINGS_ES_DB"
+	envCallbackURL       = "MF_COAP_ADAPTER_AUTH_CALLBACK_URL"
+	envCallbackTimeout   = "MF_COAP_ADAPTER_AUTH_CALLBACK_TIMEOUT"
+	envRegion            = "MF_COAP_ADAPTER_REGION"
+	envFederationLinks   = "MF_COAP_ADAPTER_FEDERATION_LINKS"
+	envRegionRefresh     = "MF_COAP_ADAPTER_REGION_REFRESH"
+	envLinkProbePeriod   = "MF_COAP_ADAPTER_LINK_PROBE_PERIOD"
+	envSequencerURL      = "MF_COAP_ADAPTER_SEQUENCER_URL"
+	envSequencerPass     = "MF_COAP_ADAPTER_SEQUENCER_PASS"
+	envSequencerDB       = "MF_COAP_ADAPTER_SEQUENCER_DB"
+	envOrderingRefresh   = "MF_COAP_ADAPTER_ORDERING_REFRESH"
+	envMaxPayload        = "MF_COAP_ADAPTER_MAX_PAYLOAD"
+	envOversizePolicy    = "MF_COAP_ADAPTER_OVERSIZE_POLICY"
+	envSpillEndpoint     = "MF_COAP_ADAPTER_SPILL_S3_ENDPOINT"
+	envSpillRegion       = "MF_COAP_ADAPTER_SPILL_S3_REGION"
+	envSpillBucket       = "MF_COAP_ADAPTER_SPILL_S3_BUCKET"
+	envSpillAccessKey    = "MF_COAP_ADAPTER_SPILL_S3_ACCESS_KEY"
+	envSpillSecretKey    = "MF_COAP_ADAPTER_SPILL_S3_SECRET_KEY"
+	envSpillTimeout      = "MF_COAP_ADAPTER_SPILL_S3_TIMEOUT"
+	envESURL             = "MF_COAP_ADAPTER_ES_URL"
+	envESPass            = "MF_COAP_ADAPTER_ES_PASS"
+	envESDB              = "MF_COAP_ADAPTER_ES_DB"
+	envDTLSPort          = "MF_COAP_ADAPTER_DTLS_PORT"
+	envDTLSSecret        = "MF_COAP_ADAPTER_DTLS_PSK_SECRET"
+	envPlaintext         = "MF_COAP_ADAPTER_PLAINTEXT"
+
+	eventStream = "mainflux.coap"
 )
 
 type config struct {
-	port          string
-	natsURL       string
-	thingsURL     string
-	logLevel      string
-	clientTLS     bool
-	caCerts       string
-	pingPeriod    time.Duration
-	jaegerURL     string
-	thingsTimeout time.Duration
+	port            string
+	natsConfig      mfnats.Config
+	thingsURL       string
+	logLevel        string
+	clientTLS       bool
+	caCerts         string
+	pingPeriod      time.Duration
+	jaegerURL       string
+	thingsTimeout   time.Duration
+	authCacheSize   int
+	authCacheTTL    time.Duration
+	authCacheURL    string
+	authCachePass   string
+	authCacheDB     string
+	thingsESURL     string
+	thingsESPass    string
+	thingsESDB      string
+	callbackURL     string
+	callbackTimeout time.Duration
+	region          string
+	federationLinks map[string][]string
+	regionRefresh   time.Duration
+	linkProbePeriod time.Duration
+	sequencerURL    string
+	sequencerPass   string
+	sequencerDB     string
+	orderingRefresh time.Duration
+	payload         payload.Config
+	spillConfig     s3.Config
+	spillTimeout    time.Duration
+	esURL           string
+	esPass          string
+	esDB            string
+	dtlsPort        string
+	dtlsSecret      string
+	plaintext       bool
 }
 
 func main() {
@@ -74,7 +191,7 @@ func main() {
 		log.Fatalf(err.Error())
 	}
 
-	nc, err := broker.Connect(cfg.natsURL)
+	nc, err := mfnats.Connect(cfg.natsConfig)
 	if err != nil {
 		logger.Error(fmt.Sprintf("Failed to connect to NATS: %s", err))
 		os.Exit(1)
@@ -84,12 +201,31 @@ func main() {
 	conn := connectToThings(cfg, logger)
 	defer conn.Close()
 
-	thingsTracer, thingsCloser := initJaeger("things", cfg.jaegerURL, logger)
+	tracer, closer := tracing.Init("coap_adapter", cfg.jaegerURL, logger)
+	defer closer.Close()
+
+	thingsTracer, thingsCloser := tracing.Init("things", cfg.jaegerURL, logger)
 	defer thingsCloser.Close()
 
 	cc := thingsapi.NewClient(conn, thingsTracer, cfg.thingsTimeout)
+	if cfg.authCacheSize > 0 || cfg.authCacheURL != "" {
+		cc = newAuthCache(cfg, cc, logger)
+	}
+	cc = callback.NewClient(cc, cfg.callbackURL, cfg.callbackTimeout)
 	respChan := make(chan string, 10000)
-	pubsub := nats.New(nc)
+	pubsub := nats.New(nc, cfg.natsConfig.Prefix)
+	if cfg.region != "" {
+		pubsub = routedPubSub{Broker: pubsub, router: newRouter(cfg, pubsub, cc, logger)}
+	}
+	if cfg.sequencerURL != "" {
+		seqClient := connectToRedis(cfg.sequencerURL, cfg.sequencerPass, cfg.sequencerDB, "sequencer", logger)
+		defer seqClient.Close()
+		pubsub = sequencedPubSub{Broker: pubsub, pub: ordering.New(pubsub, cc, orderingredis.NewSequencer(seqClient), cfg.orderingRefresh, logger)}
+	}
+	if cfg.payload.MaxSize > 0 {
+		pubsub = limitedPubSub{Broker: pubsub, pub: newPayloadPublisher(cfg, pubsub, logger)}
+	}
+	pubsub = tracedPubSub{Broker: pubsub, pub: tracing.NewPublisher(pubsub, tracer, "publish")}
 	svc := coap.New(pubsub, cc, respChan)
 	svc = api.LoggingMiddleware(svc, logger)
 
@@ -109,10 +245,23 @@ func main() {
 		}, []string{"method"}),
 	)
 
-	errs := make(chan error, 2)
+	var events presence.EventPublisher
+	if cfg.esURL != "" {
+		esClient := connectToRedis(cfg.esURL, cfg.esPass, cfg.esDB, "event", logger)
+		defer esClient.Close()
+		events = producer.NewPublisher(esClient, eventStream)
+	}
+
+	handler := api.MakeCOAPHandler(svc, cc, events, logger, respChan, cfg.pingPeriod)
+	errs := make(chan error, 3)
 
 	go startHTTPServer(cfg.port, logger, errs)
-	go startCOAPServer(cfg, svc, cc, respChan, logger, errs)
+	if cfg.plaintext {
+		go startCOAPServer(cfg, handler, logger, errs)
+	}
+	if cfg.dtlsSecret != "" {
+		go startDTLSServer(cfg, handler, logger, errs)
+	}
 
 	go func() {
 		c := make(chan os.Signal)
@@ -144,16 +293,121 @@ func loadConfig() config {
 		log.Fatalf("Invalid %s value: %s", envThingsTimeout, err.Error())
 	}
 
+	authCacheSize, err := strconv.Atoi(mainflux.Env(envAuthCacheSize, defAuthCacheSize))
+	if err != nil || authCacheSize < 0 {
+		log.Fatalf("Invalid value passed for %s\n", envAuthCacheSize)
+	}
+
+	authCacheTTL, err := time.ParseDuration(mainflux.Env(envAuthCacheTTL, defAuthCacheTTL))
+	if err != nil || authCacheTTL <= 0 {
+		log.Fatalf("Invalid value passed for %s\n", envAuthCacheTTL)
+	}
+
+	cbTimeout, err := strconv.ParseInt(mainflux.Env(envCallbackTimeout, defCallbackTimeout), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envCallbackTimeout, err.Error())
+	}
+
+	links, err := routing.ParseLinks(mainflux.Env(envFederationLinks, defFederationLinks))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envFederationLinks, err.Error())
+	}
+
+	regionRefresh, err := time.ParseDuration(mainflux.Env(envRegionRefresh, defRegionRefresh))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envRegionRefresh, err.Error())
+	}
+
+	linkProbePeriod, err := time.ParseDuration(mainflux.Env(envLinkProbePeriod, defLinkProbePeriod))
+	if err != nil || linkProbePeriod <= 0 {
+		log.Fatalf("Invalid value passed for %s\n", envLinkProbePeriod)
+	}
+
+	orderingRefresh, err := time.ParseDuration(mainflux.Env(envOrderingRefresh, defOrderingRefresh))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envOrderingRefresh, err.Error())
+	}
+
+	plaintext, err := strconv.ParseBool(mainflux.Env(envPlaintext, defPlaintext))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envPlaintext)
+	}
+
+	dtlsSecret := mainflux.Env(envDTLSSecret, defDTLSSecret)
+	if !plaintext && dtlsSecret == "" {
+		log.Fatalf("%s must be set if %s is false", envDTLSSecret, envPlaintext)
+	}
+
+	natsConfig := mfnats.Config{
+		URL:        mainflux.Env(envNatsURL, defNatsURL),
+		Creds:      mainflux.Env(envNatsCreds, defNatsCreds),
+		NKeySeed:   mainflux.Env(envNatsNKeySeed, defNatsNKeySeed),
+		CACerts:    mainflux.Env(envNatsCACerts, defNatsCACerts),
+		ClientCert: mainflux.Env(envNatsClientCert, defNatsClientCert),
+		ClientKey:  mainflux.Env(envNatsClientKey, defNatsClientKey),
+		Prefix:     mainflux.Env(envNatsSubjectPrefix, defNatsSubjectPrefix),
+	}
+
+	maxPayload, err := strconv.Atoi(mainflux.Env(envMaxPayload, defMaxPayload))
+	if err != nil || maxPayload < 0 {
+		log.Fatalf("Invalid value passed for %s\n", envMaxPayload)
+	}
+
+	policy, err := payload.ParsePolicy(mainflux.Env(envOversizePolicy, defOversizePolicy))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envOversizePolicy, err.Error())
+	}
+
+	spillTimeout, err := time.ParseDuration(mainflux.Env(envSpillTimeout, defSpillTimeout))
+	if err != nil || spillTimeout <= 0 {
+		log.Fatalf("Invalid value passed for %s\n", envSpillTimeout)
+	}
+
+	spillConfig := s3.Config{
+		Endpoint:  mainflux.Env(envSpillEndpoint, defSpillEndpoint),
+		Region:    mainflux.Env(envSpillRegion, defSpillRegion),
+		Bucket:    mainflux.Env(envSpillBucket, defSpillBucket),
+		AccessKey: mainflux.Env(envSpillAccessKey, defSpillAccessKey),
+		SecretKey: mainflux.Env(envSpillSecretKey, defSpillSecretKey),
+	}
+
 	return config{
-		thingsURL:     mainflux.Env(envThingsURL, defThingsURL),
-		natsURL:       mainflux.Env(envNatsURL, defNatsURL),
-		port:          mainflux.Env(envPort, defPort),
-		logLevel:      mainflux.Env(envLogLevel, defLogLevel),
-		clientTLS:     tls,
-		caCerts:       mainflux.Env(envCACerts, defCACerts),
-		pingPeriod:    time.Duration(pp),
-		jaegerURL:     mainflux.Env(envJaegerURL, defJaegerURL),
-		thingsTimeout: time.Duration(timeout) * time.Second,
+		thingsURL:       mainflux.Env(envThingsURL, defThingsURL),
+		natsConfig:      natsConfig,
+		port:            mainflux.Env(envPort, defPort),
+		logLevel:        mainflux.Env(envLogLevel, defLogLevel),
+		clientTLS:       tls,
+		caCerts:         mainflux.Env(envCACerts, defCACerts),
+		pingPeriod:      time.Duration(pp),
+		jaegerURL:       mainflux.Env(envJaegerURL, defJaegerURL),
+		thingsTimeout:   time.Duration(timeout) * time.Second,
+		authCacheSize:   authCacheSize,
+		authCacheTTL:    authCacheTTL,
+		authCacheURL:    mainflux.Env(envAuthCacheURL, defAuthCacheURL),
+		authCachePass:   mainflux.Env(envAuthCachePass, defAuthCachePass),
+		authCacheDB:     mainflux.Env(envAuthCacheDB, defAuthCacheDB),
+		thingsESURL:     mainflux.Env(envThingsESURL, defThingsESURL),
+		thingsESPass:    mainflux.Env(envThingsESPass, defThingsESPass),
+		thingsESDB:      mainflux.Env(envThingsESDB, defThingsESDB),
+		callbackURL:     mainflux.Env(envCallbackURL, defCallbackURL),
+		callbackTimeout: time.Duration(cbTimeout) * time.Second,
+		region:          mainflux.Env(envRegion, defRegion),
+		federationLinks: links,
+		regionRefresh:   regionRefresh,
+		linkProbePeriod: linkProbePeriod,
+		sequencerURL:    mainflux.Env(envSequencerURL, defSequencerURL),
+		sequencerPass:   mainflux.Env(envSequencerPass, defSequencerPass),
+		sequencerDB:     mainflux.Env(envSequencerDB, defSequencerDB),
+		orderingRefresh: orderingRefresh,
+		payload:         payload.Config{MaxSize: maxPayload, Policy: policy},
+		spillConfig:     spillConfig,
+		spillTimeout:    spillTimeout,
+		esURL:           mainflux.Env(envESURL, defESURL),
+		esPass:          mainflux.Env(envESPass, defESPass),
+		esDB:            mainflux.Env(envESDB, defESDB),
+		dtlsPort:        mainflux.Env(envDTLSPort, defDTLSPort),
+		dtlsSecret:      dtlsSecret,
+		plaintext:       plaintext,
 	}
 }
 
@@ -181,38 +435,165 @@ func connectToThings(cfg config, logger logger.Logger) *grpc.ClientConn {
 	return conn
 }
 
-func initJaeger(svcName, url string, logger logger.Logger) (opentracing.Tracer, io.Closer) {
-	if url == "" {
-		return opentracing.NoopTracer{}, ioutil.NopCloser(nil)
+func startHTTPServer(port string, logger logger.Logger, errs chan error) {
+	p := fmt.Sprintf(":%s", port)
+	logger.Info(fmt.Sprintf("CoAP service started, exposed port %s", port))
+	errs <- http.ListenAndServe(p, api.MakeHTTPHandler())
+}
+
+func startCOAPServer(cfg config, handler api.Handler, l logger.Logger, errs chan error) {
+	p := fmt.Sprintf(":%s", cfg.port)
+	l.Info(fmt.Sprintf("CoAP adapter service started, exposed port %s", cfg.port))
+	errs <- api.ListenAndServe(p, handler)
+}
+
+func startDTLSServer(cfg config, handler api.Handler, l logger.Logger, errs chan error) {
+	p := fmt.Sprintf(":%s", cfg.dtlsPort)
+	l.Info(fmt.Sprintf("CoAP adapter DTLS service started, exposed port %s", cfg.dtlsPort))
+	errs <- api.ListenAndServeDTLS(p, []byte(cfg.dtlsSecret), handler)
+}
+
+func connectToRedis(redisURL, redisPass, redisDB, store string, logger logger.Logger) redis.UniversalClient {
+	db, err := strconv.Atoi(redisDB)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to %s store: %s", store, err))
+		os.Exit(1)
 	}
 
-	tracer, closer, err := jconfig.Configuration{
-		ServiceName: svcName,
-		Sampler: &jconfig.SamplerConfig{
-			Type:  "const",
-			Param: 1,
-		},
-		Reporter: &jconfig.ReporterConfig{
-			LocalAgentHostPort: url,
-			LogSpans:           true,
-		},
-	}.NewTracer()
+	client, err := mfredis.Connect(redisURL, redisPass, db)
 	if err != nil {
-		logger.Error(fmt.Sprintf("Failed to init Jaeger client: %s", err))
+		logger.Error(fmt.Sprintf("Failed to connect to %s store: %s", store, err))
 		os.Exit(1)
 	}
 
-	return tracer, closer
+	return client
 }
 
-func startHTTPServer(port string, logger logger.Logger, errs chan error) {
-	p := fmt.Sprintf(":%s", port)
-	logger.Info(fmt.Sprintf("CoAP service started, exposed port %s", port))
-	errs <- http.ListenAndServe(p, api.MakeHTTPHandler())
+// newAuthCache caches the accesses granted by the things service, keeping the
+// cache up to date by consuming the things events. The shared cache is
+// invalidated by a single instance of the group, while each instance keeping
+// its own cache reads all the events without the consumer group.
+func newAuthCache(cfg config, cc mainflux.ThingsServiceClient, logger logger.Logger) mainflux.ThingsServiceClient {
+	store := cache.NewLRUStore(cfg.authCacheSize, cfg.authCacheTTL)
+	var group, instance string
+	if cfg.authCacheURL != "" {
+		cacheClient := connectToRedis(cfg.authCacheURL, cfg.authCachePass, cfg.authCacheDB, "auth cache", logger)
+		store = cacheredis.NewStore(cacheClient, cfg.authCacheTTL)
+
+		group = "mainflux.coap-adapter.auth-cache"
+		var err error
+		if instance, err = os.Hostname(); err != nil {
+			instance = strconv.FormatInt(time.Now().UnixNano(), 10)
+		}
+	}
+	client := cache.NewClient(cc, store)
+
+	esClient := connectToRedis(cfg.thingsESURL, cfg.thingsESPass, cfg.thingsESDB, "things event", logger)
+	c := consumer.New(esClient, consumer.Config{
+		Stream:   consumer.ThingsStream,
+		Group:    group,
+		Consumer: instance,
+	}, logger)
+
+	go func() {
+		if err := c.Consume(context.Background(), cache.Handler(client)); err != nil {
+			logger.Error(fmt.Sprintf("Failed to consume things events: %s", err))
+		}
+	}()
+
+	return client
 }
 
-func startCOAPServer(cfg config, svc coap.Service, auth mainflux.ThingsServiceClient, respChan chan<- string, l logger.Logger, errs chan error) {
-	p := fmt.Sprintf(":%s", cfg.port)
-	l.Info(fmt.Sprintf("CoAP adapter service started, exposed port %s", cfg.port))
-	errs <- gocoap.ListenAndServe("udp", p, api.MakeCOAPHandler(svc, auth, l, respChan, cfg.pingPeriod))
+// newPayloadPublisher enforces the maximal payload size of the published
+// messages. Spill policy stores the oversized payloads to the object store.
+func newPayloadPublisher(cfg config, pub mainflux.MessagePublisher, logger logger.Logger) mainflux.MessagePublisher {
+	var store payload.Store
+	if cfg.payload.Policy == payload.Spill {
+		store = s3.NewClient(cfg.spillConfig, &http.Client{Timeout: cfg.spillTimeout})
+	}
+
+	violations := kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+		Namespace: "coap_adapter",
+		Subsystem: "payload",
+		Name:      "oversized_count",
+		Help:      "Number of messages exceeding the maximal payload size.",
+	}, []string{"policy"})
+
+	lp, err := payload.New(pub, cfg.payload, store, violations, logger)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to create payload size limiter: %s", err))
+		os.Exit(1)
+	}
+
+	return lp
+}
+
+func newRouter(cfg config, local mainflux.MessagePublisher, tc mainflux.ThingsServiceClient, logger logger.Logger) routing.Router {
+	links := make(map[string][]routing.Link)
+	for region, urls := range cfg.federationLinks {
+		for _, url := range urls {
+			nc, err := broker.Connect(url)
+			if err != nil {
+				logger.Error(fmt.Sprintf("Failed to connect to NATS of region %s: %s", region, err))
+				os.Exit(1)
+			}
+			links[region] = append(links[region], routingnats.NewLink(nc, cfg.natsConfig.Prefix, cfg.linkProbePeriod))
+		}
+	}
+
+	router := routing.New(cfg.region, local, links, tc, cfg.regionRefresh, logger)
+	go startProbing(router, cfg.linkProbePeriod, logger)
+	return router
+}
+
+func startProbing(router routing.Router, period time.Duration, logger logger.Logger) {
+	logger.Info(fmt.Sprintf("Probing federation links every %s", period))
+	for {
+		router.Probe()
+		time.Sleep(period)
+	}
+}
+
+// routedPubSub routes the published messages to the clusters of the channels
+// regions, while the subscriptions are served by the local broker.
+type routedPubSub struct {
+	coap.Broker
+	router routing.Router
+}
+
+func (ps routedPubSub) Publish(ctx context.Context, token string, msg mainflux.RawMessage) error {
+	return ps.router.Publish(ctx, token, msg)
+}
+
+// sequencedPubSub assigns the sequence numbers to the messages published to
+// the ordered channels, while the subscriptions are served unchanged.
+type sequencedPubSub struct {
+	coap.Broker
+	pub mainflux.MessagePublisher
+}
+
+func (ps sequencedPubSub) Publish(ctx context.Context, token string, msg mainflux.RawMessage) error {
+	return ps.pub.Publish(ctx, token, msg)
+}
+
+// limitedPubSub enforces the maximal payload size of the published messages,
+// while the subscriptions are served unchanged.
+type limitedPubSub struct {
+	coap.Broker
+	pub mainflux.MessagePublisher
+}
+
+func (ps limitedPubSub) Publish(ctx context.Context, token string, msg mainflux.RawMessage) error {
+	return ps.pub.Publish(ctx, token, msg)
+}
+
+// tracedPubSub traces the published messages, while the subscriptions are
+// served unchanged.
+type tracedPubSub struct {
+	coap.Broker
+	pub mainflux.MessagePublisher
+}
+
+func (ps tracedPubSub) Publish(ctx context.Context, token string, msg mainflux.RawMessage) error {
+	return ps.pub.Publish(ctx, token, msg)
 }
@@ -0,0 +1,161 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/logger"
+	mfnats "github.com/mainflux/mainflux/nats"
+	"github.com/mainflux/mainflux/writers"
+	"github.com/mainflux/mainflux/writers/deadletter"
+	nats "github.com/nats-io/nats.go"
+)
+
+const (
+	replaySuffix = ".replay"
+
+	defNatsURL           = nats.DefaultURL
+	defNatsCreds         = ""
+	defNatsNKeySeed      = ""
+	defNatsCACerts       = ""
+	defNatsClientCert    = ""
+	defNatsClientKey     = ""
+	defNatsSubjectPrefix = ""
+	defLogLevel          = "info"
+	defFile              = ""
+	defWriter            = ""
+
+	envNatsURL           = "MF_NATS_URL"
+	envNatsCreds         = "MF_NATS_CREDS"
+	envNatsNKeySeed      = "MF_NATS_NKEY_SEED"
+	envNatsCACerts       = "MF_NATS_CA_CERTS"
+	envNatsClientCert    = "MF_NATS_CLIENT_CERT"
+	envNatsClientKey     = "MF_NATS_CLIENT_KEY"
+	envNatsSubjectPrefix = "MF_NATS_SUBJECT_PREFIX"
+	envLogLevel          = "MF_DLQ_REPLAYER_LOG_LEVEL"
+	envFile              = "MF_DLQ_REPLAYER_FILE"
+	envWriter            = "MF_DLQ_REPLAYER_WRITER"
+)
+
+type config struct {
+	natsConfig mfnats.Config
+	logLevel   string
+	file       string
+	writer     string
+}
+
+func main() {
+	cfg := loadConfig()
+
+	logger, err := logger.New(os.Stdout, cfg.logLevel)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	path, err := claimSpillFile(cfg.file)
+	if os.IsNotExist(err) {
+		logger.Info(fmt.Sprintf("No dead letters found in %s", cfg.file))
+		return
+	}
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to claim spill file: %s", err))
+		os.Exit(1)
+	}
+
+	msgs, err := deadletter.ReadFile(path)
+	switch err {
+	case nil:
+	case io.ErrUnexpectedEOF:
+		logger.Warn(fmt.Sprintf("Spill file %s ends with incomplete message, replaying the complete ones", path))
+	default:
+		logger.Error(fmt.Sprintf("Failed to read spill file: %s", err))
+		os.Exit(1)
+	}
+
+	nc, err := mfnats.Connect(cfg.natsConfig)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to NATS: %s", err))
+		os.Exit(1)
+	}
+	defer nc.Close()
+
+	subject := mfnats.Subject(cfg.natsConfig.Prefix, writers.ReplaySubject(cfg.writer))
+	if err := replay(nc, subject, msgs); err != nil {
+		logger.Error(fmt.Sprintf("Failed to replay dead letters: %s", err))
+		os.Exit(1)
+	}
+
+	if err := os.Remove(path); err != nil {
+		logger.Error(fmt.Sprintf("Failed to remove replayed spill file: %s", err))
+		os.Exit(1)
+	}
+
+	logger.Info(fmt.Sprintf("Replayed %d dead letters to %s", len(msgs), subject))
+}
+
+func loadConfig() config {
+	natsConfig := mfnats.Config{
+		URL:        mainflux.Env(envNatsURL, defNatsURL),
+		Creds:      mainflux.Env(envNatsCreds, defNatsCreds),
+		NKeySeed:   mainflux.Env(envNatsNKeySeed, defNatsNKeySeed),
+		CACerts:    mainflux.Env(envNatsCACerts, defNatsCACerts),
+		ClientCert: mainflux.Env(envNatsClientCert, defNatsClientCert),
+		ClientKey:  mainflux.Env(envNatsClientKey, defNatsClientKey),
+		Prefix:     mainflux.Env(envNatsSubjectPrefix, defNatsSubjectPrefix),
+	}
+
+	cfg := config{
+		natsConfig: natsConfig,
+		logLevel:   mainflux.Env(envLogLevel, defLogLevel),
+		file:       mainflux.Env(envFile, defFile),
+		writer:     mainflux.Env(envWriter, defWriter),
+	}
+
+	if cfg.file == "" {
+		log.Fatalf("Missing value for %s\n", envFile)
+	}
+
+	if cfg.writer == "" {
+		log.Fatalf("Missing value for %s\n", envWriter)
+	}
+
+	return cfg
+}
+
+// claimSpillFile moves the spill file away from the writer, which keeps
+// appending the new dead letters to the original path. The spill file left
+// by the interrupted replay is claimed instead, if there is one.
+func claimSpillFile(path string) (string, error) {
+	replayPath := path + replaySuffix
+	if _, err := os.Stat(replayPath); err == nil {
+		return replayPath, nil
+	}
+
+	if err := os.Rename(path, replayPath); err != nil {
+		return "", err
+	}
+
+	return replayPath, nil
+}
+
+func replay(nc *nats.Conn, subject string, msgs []mainflux.Message) error {
+	for _, msg := range msgs {
+		data, err := proto.Marshal(&msg)
+		if err != nil {
+			return err
+		}
+
+		if err := nc.Publish(subject, data); err != nil {
+			return err
+		}
+	}
+
+	return nc.Flush()
+}
@@ -0,0 +1,313 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	r "github.com/go-redis/redis"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/egress"
+	"github.com/mainflux/mainflux/egress/api"
+	"github.com/mainflux/mainflux/egress/brokers"
+	"github.com/mainflux/mainflux/egress/nats"
+	"github.com/mainflux/mainflux/egress/redis"
+	"github.com/mainflux/mainflux/egress/things"
+	"github.com/mainflux/mainflux/logger"
+	mfnats "github.com/mainflux/mainflux/nats"
+	mfredis "github.com/mainflux/mainflux/redis"
+	mfsdk "github.com/mainflux/mainflux/sdk/go"
+	"github.com/mainflux/mainflux/things/uuid"
+	usersapi "github.com/mainflux/mainflux/users/api/grpc"
+	broker "github.com/nats-io/nats.go"
+	opentracing "github.com/opentracing/opentracing-go"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+const (
+	defLogLevel          = "error"
+	defHTTPPort          = "8195"
+	defNatsURL           = broker.DefaultURL
+	defNatsCreds         = ""
+	defNatsNKeySeed      = ""
+	defNatsCACerts       = ""
+	defNatsClientCert    = ""
+	defNatsClientKey     = ""
+	defNatsSubjectPrefix = ""
+	defDBURL             = "localhost:6379"
+	defDBPass            = ""
+	defDBDB              = "0"
+	defESURL             = "localhost:6379"
+	defESPass            = ""
+	defESDB              = "0"
+	defInstanceName      = "egress"
+	defUsersURL          = "localhost:8181"
+	defUsersTimeout      = "1" // in seconds
+	defClientTLS         = "false"
+	defCACerts           = ""
+	defBaseURL           = "http://localhost"
+	defThingsPrefix      = ""
+	defAllowedHosts      = ""
+	defQueueSize         = "100"
+
+	envLogLevel          = "MF_EGRESS_LOG_LEVEL"
+	envHTTPPort          = "MF_EGRESS_HTTP_PORT"
+	envNatsURL           = "MF_NATS_URL"
+	envNatsCreds         = "MF_NATS_CREDS"
+	envNatsNKeySeed      = "MF_NATS_NKEY_SEED"
+	envNatsCACerts       = "MF_NATS_CA_CERTS"
+	envNatsClientCert    = "MF_NATS_CLIENT_CERT"
+	envNatsClientKey     = "MF_NATS_CLIENT_KEY"
+	envNatsSubjectPrefix = "MF_NATS_SUBJECT_PREFIX"
+	envDBURL             = "MF_EGRESS_DB_URL"
+	envDBPass            = "MF_EGRESS_DB_PASS"
+	envDBDB              = "MF_EGRESS_DB"
+	envESURL             = "MF_EGRESS_ES_URL"
+	envESPass            = "MF_EGRESS_ES_PASS"
+	envESDB              = "MF_EGRESS_ES_DB"
+	envInstanceName      = "MF_EGRESS_INSTANCE_NAME"
+	envUsersURL          = "MF_USERS_URL"
+	envUsersTimeout      = "MF_EGRESS_USERS_TIMEOUT"
+	envClientTLS         = "MF_EGRESS_CLIENT_TLS"
+	envCACerts           = "MF_EGRESS_CA_CERTS"
+	envBaseURL           = "MF_SDK_BASE_URL"
+	envThingsPrefix      = "MF_SDK_THINGS_PREFIX"
+	envAllowedHosts      = "MF_EGRESS_ALLOWED_HOSTS"
+	envQueueSize         = "MF_EGRESS_QUEUE_SIZE"
+)
+
+type config struct {
+	logLevel     string
+	httpPort     string
+	natsConfig   mfnats.Config
+	dbURL        string
+	dbPass       string
+	dbDB         string
+	esURL        string
+	esPass       string
+	esDB         string
+	instanceName string
+	usersURL     string
+	usersTimeout time.Duration
+	clientTLS    bool
+	caCerts      string
+	baseURL      string
+	thingsPrefix string
+	egressConfig egress.Config
+	queueSize    int
+}
+
+func main() {
+	cfg := loadConfig()
+
+	logger, err := logger.New(os.Stdout, cfg.logLevel)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	nc := connectToNATS(cfg.natsConfig, logger)
+	defer nc.Close()
+
+	dbClient := connectToRedis(cfg.dbURL, cfg.dbPass, cfg.dbDB, logger)
+	defer dbClient.Close()
+
+	esClient := connectToRedis(cfg.esURL, cfg.esPass, cfg.esDB, logger)
+	defer esClient.Close()
+
+	conn := connectToUsers(cfg, logger)
+	defer conn.Close()
+
+	users := usersapi.NewClient(opentracing.NoopTracer{}, conn, cfg.usersTimeout)
+	svc := newService(users, dbClient, esClient, cfg, logger)
+
+	if err := svc.Reload(context.Background()); err != nil {
+		logger.Error(fmt.Sprintf("Failed to load egress rules: %s", err))
+		os.Exit(1)
+	}
+
+	if err := nats.Subscribe(svc, nc, cfg.natsConfig.Prefix, logger); err != nil {
+		logger.Error(fmt.Sprintf("Failed to subscribe to NATS: %s", err))
+		os.Exit(1)
+	}
+
+	go subscribeToES(svc, esClient, redis.RulesStream, cfg.instanceName, logger)
+	go subscribeToES(svc, esClient, redis.ThingsStream, cfg.instanceName, logger)
+
+	errs := make(chan error, 2)
+
+	go startHTTPServer(svc, cfg.httpPort, logger, errs)
+
+	go func() {
+		c := make(chan os.Signal, 1)
+		signal.Notify(c, syscall.SIGINT)
+		errs <- fmt.Errorf("%s", <-c)
+	}()
+
+	err = <-errs
+	logger.Error(fmt.Sprintf("Egress service terminated: %s", err))
+}
+
+func loadConfig() config {
+	tls, err := strconv.ParseBool(mainflux.Env(envClientTLS, defClientTLS))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envClientTLS)
+	}
+
+	timeout, err := strconv.ParseInt(mainflux.Env(envUsersTimeout, defUsersTimeout), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envUsersTimeout, err.Error())
+	}
+
+	queueSize, err := strconv.Atoi(mainflux.Env(envQueueSize, defQueueSize))
+	if err != nil || queueSize <= 0 {
+		log.Fatalf("Invalid value passed for %s\n", envQueueSize)
+	}
+
+	var hosts []string
+	for _, h := range strings.Split(mainflux.Env(envAllowedHosts, defAllowedHosts), ",") {
+		if h = strings.TrimSpace(h); h != "" {
+			hosts = append(hosts, h)
+		}
+	}
+
+	natsConfig := mfnats.Config{
+		URL:        mainflux.Env(envNatsURL, defNatsURL),
+		Creds:      mainflux.Env(envNatsCreds, defNatsCreds),
+		NKeySeed:   mainflux.Env(envNatsNKeySeed, defNatsNKeySeed),
+		CACerts:    mainflux.Env(envNatsCACerts, defNatsCACerts),
+		ClientCert: mainflux.Env(envNatsClientCert, defNatsClientCert),
+		ClientKey:  mainflux.Env(envNatsClientKey, defNatsClientKey),
+		Prefix:     mainflux.Env(envNatsSubjectPrefix, defNatsSubjectPrefix),
+	}
+
+	return config{
+		logLevel:     mainflux.Env(envLogLevel, defLogLevel),
+		httpPort:     mainflux.Env(envHTTPPort, defHTTPPort),
+		natsConfig:   natsConfig,
+		dbURL:        mainflux.Env(envDBURL, defDBURL),
+		dbPass:       mainflux.Env(envDBPass, defDBPass),
+		dbDB:         mainflux.Env(envDBDB, defDBDB),
+		esURL:        mainflux.Env(envESURL, defESURL),
+		esPass:       mainflux.Env(envESPass, defESPass),
+		esDB:         mainflux.Env(envESDB, defESDB),
+		instanceName: mainflux.Env(envInstanceName, defInstanceName),
+		usersURL:     mainflux.Env(envUsersURL, defUsersURL),
+		usersTimeout: time.Duration(timeout) * time.Second,
+		clientTLS:    tls,
+		caCerts:      mainflux.Env(envCACerts, defCACerts),
+		baseURL:      mainflux.Env(envBaseURL, defBaseURL),
+		thingsPrefix: mainflux.Env(envThingsPrefix, defThingsPrefix),
+		egressConfig: egress.Config{Hosts: hosts},
+		queueSize:    queueSize,
+	}
+}
+
+func connectToNATS(cfg mfnats.Config, logger logger.Logger) *broker.Conn {
+	nc, err := mfnats.Connect(cfg)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to NATS: %s", err))
+		os.Exit(1)
+	}
+
+	return nc
+}
+
+func connectToRedis(redisURL, redisPass, redisDB string, logger logger.Logger) r.UniversalClient {
+	db, err := strconv.Atoi(redisDB)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to redis: %s", err))
+		os.Exit(1)
+	}
+
+	client, err := mfredis.Connect(redisURL, redisPass, db)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to redis: %s", err))
+		os.Exit(1)
+	}
+
+	return client
+}
+
+func connectToUsers(cfg config, logger logger.Logger) *grpc.ClientConn {
+	var opts []grpc.DialOption
+	if cfg.clientTLS {
+		if cfg.caCerts != "" {
+			tpc, err := credentials.NewClientTLSFromFile(cfg.caCerts, "")
+			if err != nil {
+				logger.Error(fmt.Sprintf("Failed to create tls credentials: %s", err))
+				os.Exit(1)
+			}
+			opts = append(opts, grpc.WithTransportCredentials(tpc))
+		}
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+		logger.Info("gRPC communication is not encrypted")
+	}
+
+	conn, err := grpc.Dial(cfg.usersURL, opts...)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to users service: %s", err))
+		os.Exit(1)
+	}
+
+	return conn
+}
+
+func newService(users mainflux.UsersServiceClient, dbClient, esClient r.UniversalClient, cfg config, logger logger.Logger) egress.Service {
+	sdk := mfsdk.NewSDK(mfsdk.Config{
+		BaseURL:      cfg.baseURL,
+		ThingsPrefix: cfg.thingsPrefix,
+	})
+
+	channels := things.New(sdk)
+	rules := redis.NewRuleRepository(dbClient)
+	forwarder := brokers.New(fmt.Sprintf("mainflux-egress-%s", cfg.instanceName), cfg.queueSize, logger)
+
+	svc := egress.New(users, channels, rules, forwarder, uuid.New(), cfg.egressConfig)
+	svc = redis.NewEventStoreMiddleware(svc, esClient)
+	svc = api.LoggingMiddleware(svc, logger)
+	svc = api.MetricsMiddleware(
+		svc,
+		kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: "egress",
+			Subsystem: "api",
+			Name:      "request_count",
+			Help:      "Number of requests received.",
+		}, []string{"method"}),
+		kitprometheus.NewSummaryFrom(stdprometheus.SummaryOpts{
+			Namespace: "egress",
+			Subsystem: "api",
+			Name:      "request_latency_microseconds",
+			Help:      "Total duration of requests in microseconds.",
+		}, []string{"method"}),
+	)
+
+	return svc
+}
+
+func subscribeToES(svc egress.Service, client r.UniversalClient, stream, consumer string, logger logger.Logger) {
+	eventStore := redis.NewEventStore(svc, client, consumer, logger)
+	logger.Info(fmt.Sprintf("Subscribed to Redis Event Store stream %s", stream))
+	if err := eventStore.Subscribe(stream); err != nil {
+		logger.Warn(fmt.Sprintf("Egress service failed to subscribe to event sourcing: %s", err))
+	}
+}
+
+func startHTTPServer(svc egress.Service, port string, logger logger.Logger, errs chan error) {
+	p := fmt.Sprintf(":%s", port)
+	logger.Info(fmt.Sprintf("Egress service started, exposed port %s", port))
+	errs <- http.ListenAndServe(p, api.MakeHandler(svc))
+}
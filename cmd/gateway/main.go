@@ -0,0 +1,186 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/gateway"
+	"github.com/mainflux/mainflux/logger"
+	usersapi "github.com/mainflux/mainflux/users/api/grpc"
+	opentracing "github.com/opentracing/opentracing-go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+const (
+	defLogLevel     = "error"
+	defHTTPPort     = "8199"
+	defServerCert   = ""
+	defServerKey    = ""
+	defRoutes       = ""
+	defAuthRules    = "/things,/channels,/groups,GET /users,!/things/bootstrap"
+	defRateLimit    = "0" // in requests per second
+	defRateBurst    = "10"
+	defUsersURL     = "localhost:8181"
+	defUsersTimeout = "1" // in seconds
+	defClientTLS    = "false"
+	defCACerts      = ""
+
+	envLogLevel     = "MF_GATEWAY_LOG_LEVEL"
+	envHTTPPort     = "MF_GATEWAY_HTTP_PORT"
+	envServerCert   = "MF_GATEWAY_SERVER_CERT"
+	envServerKey    = "MF_GATEWAY_SERVER_KEY"
+	envRoutes       = "MF_GATEWAY_ROUTES"
+	envAuthRules    = "MF_GATEWAY_AUTH_RULES"
+	envRateLimit    = "MF_GATEWAY_RATE_LIMIT"
+	envRateBurst    = "MF_GATEWAY_RATE_BURST"
+	envUsersURL     = "MF_USERS_URL"
+	envUsersTimeout = "MF_GATEWAY_USERS_TIMEOUT"
+	envClientTLS    = "MF_GATEWAY_CLIENT_TLS"
+	envCACerts      = "MF_GATEWAY_CA_CERTS"
+)
+
+type config struct {
+	logLevel     string
+	httpPort     string
+	serverCert   string
+	serverKey    string
+	routes       []gateway.Route
+	authRules    []gateway.AuthRule
+	rateLimit    float64
+	rateBurst    int
+	usersURL     string
+	usersTimeout time.Duration
+	clientTLS    bool
+	caCerts      string
+}
+
+func main() {
+	cfg := loadConfig()
+
+	logger, err := logger.New(os.Stdout, cfg.logLevel)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	conn := connectToUsers(cfg, logger)
+	defer conn.Close()
+
+	users := usersapi.NewClient(opentracing.NoopTracer{}, conn, cfg.usersTimeout)
+
+	h := gateway.NewRouter(cfg.routes)
+	h = gateway.Authenticate(h, users, cfg.authRules, cfg.usersTimeout)
+	if cfg.rateLimit > 0 {
+		h = gateway.RateLimit(h, cfg.rateLimit, cfg.rateBurst)
+	}
+	h = gateway.Log(h, logger)
+
+	errs := make(chan error, 2)
+
+	go startHTTPServer(h, cfg.httpPort, cfg.serverCert, cfg.serverKey, logger, errs)
+
+	go func() {
+		c := make(chan os.Signal, 1)
+		signal.Notify(c, syscall.SIGINT)
+		errs <- fmt.Errorf("%s", <-c)
+	}()
+
+	err = <-errs
+	logger.Error(fmt.Sprintf("Gateway terminated: %s", err))
+}
+
+func loadConfig() config {
+	tls, err := strconv.ParseBool(mainflux.Env(envClientTLS, defClientTLS))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envClientTLS)
+	}
+
+	timeout, err := strconv.ParseInt(mainflux.Env(envUsersTimeout, defUsersTimeout), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envUsersTimeout, err.Error())
+	}
+
+	routes, err := gateway.ParseRoutes(mainflux.Env(envRoutes, defRoutes))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envRoutes, err.Error())
+	}
+	if len(routes) == 0 {
+		log.Fatalf("No routes set in %s", envRoutes)
+	}
+
+	rules, err := gateway.ParseAuthRules(mainflux.Env(envAuthRules, defAuthRules))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envAuthRules, err.Error())
+	}
+
+	rateLimit, err := strconv.ParseFloat(mainflux.Env(envRateLimit, defRateLimit), 64)
+	if err != nil || rateLimit < 0 {
+		log.Fatalf("Invalid value passed for %s\n", envRateLimit)
+	}
+
+	rateBurst, err := strconv.Atoi(mainflux.Env(envRateBurst, defRateBurst))
+	if err != nil || rateBurst < 1 {
+		log.Fatalf("Invalid value passed for %s\n", envRateBurst)
+	}
+
+	return config{
+		logLevel:     mainflux.Env(envLogLevel, defLogLevel),
+		httpPort:     mainflux.Env(envHTTPPort, defHTTPPort),
+		serverCert:   mainflux.Env(envServerCert, defServerCert),
+		serverKey:    mainflux.Env(envServerKey, defServerKey),
+		routes:       routes,
+		authRules:    rules,
+		rateLimit:    rateLimit,
+		rateBurst:    rateBurst,
+		usersURL:     mainflux.Env(envUsersURL, defUsersURL),
+		usersTimeout: time.Duration(timeout) * time.Second,
+		clientTLS:    tls,
+		caCerts:      mainflux.Env(envCACerts, defCACerts),
+	}
+}
+
+func connectToUsers(cfg config, logger logger.Logger) *grpc.ClientConn {
+	var opts []grpc.DialOption
+	if cfg.clientTLS {
+		if cfg.caCerts != "" {
+			tpc, err := credentials.NewClientTLSFromFile(cfg.caCerts, "")
+			if err != nil {
+				logger.Error(fmt.Sprintf("Failed to create tls credentials: %s", err))
+				os.Exit(1)
+			}
+			opts = append(opts, grpc.WithTransportCredentials(tpc))
+		}
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+		logger.Info("gRPC communication is not encrypted")
+	}
+
+	conn, err := grpc.Dial(cfg.usersURL, opts...)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to users service: %s", err))
+		os.Exit(1)
+	}
+
+	return conn
+}
+
+func startHTTPServer(h http.Handler, port, certFile, keyFile string, logger logger.Logger, errs chan error) {
+	p := fmt.Sprintf(":%s", port)
+	if certFile != "" || keyFile != "" {
+		logger.Info(fmt.Sprintf("Gateway started using https, cert %s key %s, exposed port %s", certFile, keyFile, port))
+		errs <- http.ListenAndServeTLS(p, certFile, keyFile, h)
+		return
+	}
+	logger.Info(fmt.Sprintf("Gateway started using http, exposed port %s", port))
+	errs <- http.ListenAndServe(p, h)
+}
@@ -4,10 +4,13 @@
 package main
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
-	"io"
 	"io/ioutil"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -18,48 +21,185 @@ import (
 	"google.golang.org/grpc/credentials"
 
 	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	"github.com/go-redis/redis"
 	"github.com/mainflux/mainflux"
+	certsclient "github.com/mainflux/mainflux/certs/client"
 	adapter "github.com/mainflux/mainflux/http"
 	"github.com/mainflux/mainflux/http/api"
+	grpcapi "github.com/mainflux/mainflux/http/api/grpc"
 	"github.com/mainflux/mainflux/http/nats"
 	"github.com/mainflux/mainflux/logger"
+	mfnats "github.com/mainflux/mainflux/nats"
+	"github.com/mainflux/mainflux/ordering"
+	orderingredis "github.com/mainflux/mainflux/ordering/redis"
+	"github.com/mainflux/mainflux/payload"
+	"github.com/mainflux/mainflux/pkg/events/consumer"
+	mfredis "github.com/mainflux/mainflux/redis"
+	"github.com/mainflux/mainflux/routing"
+	routingnats "github.com/mainflux/mainflux/routing/nats"
+	"github.com/mainflux/mainflux/things/api/auth/cache"
+	cacheredis "github.com/mainflux/mainflux/things/api/auth/cache/redis"
+	"github.com/mainflux/mainflux/things/api/auth/callback"
 	thingsapi "github.com/mainflux/mainflux/things/api/auth/grpc"
-	broker "github.com/nats-io/go-nats"
-	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/mainflux/mainflux/things/uuid"
+	"github.com/mainflux/mainflux/tracing"
+	"github.com/mainflux/mainflux/writers/s3"
+	broker "github.com/nats-io/nats.go"
 	stdprometheus "github.com/prometheus/client_golang/prometheus"
-	jconfig "github.com/uber/jaeger-client-go/config"
 	"google.golang.org/grpc"
 )
 
 const (
-	defClientTLS     = "false"
-	defCACerts       = ""
-	defPort          = "8180"
-	defLogLevel      = "error"
-	defNatsURL       = broker.DefaultURL
-	defThingsURL     = "localhost:8181"
-	defJaegerURL     = ""
-	defThingsTimeout = "1" // in seconds
-
-	envClientTLS     = "MF_HTTP_ADAPTER_CLIENT_TLS"
-	envCACerts       = "MF_HTTP_ADAPTER_CA_CERTS"
-	envPort          = "MF_HTTP_ADAPTER_PORT"
-	envLogLevel      = "MF_HTTP_ADAPTER_LOG_LEVEL"
-	envNatsURL       = "MF_NATS_URL"
-	envThingsURL     = "MF_THINGS_URL"
-	envJaegerURL     = "MF_JAEGER_URL"
-	envThingsTimeout = "MF_HTTP_ADAPTER_THINGS_TIMEOUT"
+	defClientTLS         = "false"
+	defCACerts           = ""
+	defPort              = "8180"
+	defGRPCPort          = "8203"
+	defServerCert        = ""
+	defServerKey         = ""
+	defLogLevel          = "error"
+	defNatsURL           = broker.DefaultURL
+	defNatsCreds         = ""
+	defNatsNKeySeed      = ""
+	defNatsCACerts       = ""
+	defNatsClientCert    = ""
+	defNatsClientKey     = ""
+	defNatsSubjectPrefix = ""
+	defThingsURL         = "localhost:8181"
+	defJaegerURL         = ""
+	defThingsTimeout     = "1" // in seconds
+	defAuthCacheSize     = "0"
+	defAuthCacheTTL      = "1m"
+	defAuthCacheURL      = ""
+	defAuthCachePass     = ""
+	defAuthCacheDB       = "0"
+	defThingsESURL       = "localhost:6379"
+	defThingsESPass      = ""
+	defThingsESDB        = "0"
+	defCallbackURL       = ""
+	defCallbackTimeout   = "1" // in seconds
+	defRegion            = ""
+	defFederationLinks   = ""
+	defRegionRefresh     = "1m"
+	defLinkProbePeriod   = "10s"
+	defSequencerURL      = ""
+	defSequencerPass     = ""
+	defSequencerDB       = "0"
+	defOrderingRefresh   = "1m"
+	defMaxPayload        = "0"
+	defOversizePolicy    = "reject"
+	defMaxBody           = "10485760"
+	defSpillEndpoint     = ""
+	defSpillRegion       = "us-east-1"
+	defSpillBucket       = "spilled-payloads"
+	defSpillAccessKey    = ""
+	defSpillSecretKey    = ""
+	defSpillTimeout      = "10s"
+	defAuthSchemes       = api.HeaderScheme
+	defAuthHeader        = "Authorization"
+	defAuthQueryParam    = "key"
+	defRequestTimeout    = "10s"
+	defMTLSPort          = ""
+	defClientCACerts     = ""
+	defMTLSMode          = api.OptionalCerts
+	defCertsURL          = ""
+	defCertsTimeout      = "1" // in seconds
+
+	envClientTLS         = "MF_HTTP_ADAPTER_CLIENT_TLS"
+	envCACerts           = "MF_HTTP_ADAPTER_CA_CERTS"
+	envPort              = "MF_HTTP_ADAPTER_PORT"
+	envGRPCPort          = "MF_HTTP_ADAPTER_GRPC_PORT"
+	envServerCert        = "MF_HTTP_ADAPTER_SERVER_CERT"
+	envServerKey         = "MF_HTTP_ADAPTER_SERVER_KEY"
+	envLogLevel          = "MF_HTTP_ADAPTER_LOG_LEVEL"
+	envNatsURL           = "MF_NATS_URL"
+	envNatsCreds         = "MF_NATS_CREDS"
+	envNatsNKeySeed      = "MF_NATS_NKEY_SEED"
+	envNatsCACerts       = "MF_NATS_CA_CERTS"
+	envNatsClientCert    = "MF_NATS_CLIENT_CERT"
+	envNatsClientKey     = "MF_NATS_CLIENT_KEY"
+	envNatsSubjectPrefix = "MF_NATS_SUBJECT_PREFIX"
+	envThingsURL         = "MF_THINGS_URL"
+	envJaegerURL         = "MF_JAEGER_URL"
+	envThingsTimeout     = "MF_HTTP_ADAPTER_THINGS_TIMEOUT"
+	envAuthCacheSize     = "MF_HTTP_ADAPTER_AUTH_CACHE_SIZE"
+	envAuthCacheTTL      = "MF_HTTP_ADAPTER_AUTH_CACHE_TTL"
+	envAuthCacheURL      = "MF_HTTP_ADAPTER_AUTH_CACHE_URL"
+	envAuthCachePass     = "MF_HTTP_ADAPTER_AUTH_CACHE_PASS"
+	envAuthCacheDB       = "MF_HTTP_ADAPTER_AUTH_CACHE_DB"
+	envThingsESURL       = "MF_HTTP_ADAPTER_THINGS_ES_URL"
+	envThingsESPass      = "MF_HTTP_ADAPTER_THINGS_ES_PASS"
+	envThingsESDB        = "MF_HTTP_ADAPTER_THINGS_ES_DB"
+	envCallbackURL       = "MF_HTTP_ADAPTER_AUTH_CALLBACK_URL"
+	envCallbackTimeout   = "MF_HTTP_ADAPTER_AUTH_CALLBACK_TIMEOUT"
+	envRegion            = "MF_HTTP_ADAPTER_REGION"
+	envFederationLinks   = "MF_HTTP_ADAPTER_FEDERATION_LINKS"
+	envRegionRefresh     = "MF_HTTP_ADAPTER_REGION_REFRESH"
+	envLinkProbePeriod   = "MF_HTTP_ADAPTER_LINK_PROBE_PERIOD"
+	envSequencerURL      = "MF_HTTP_ADAPTER_SEQUENCER_URL"
+	envSequencerPass     = "MF_HTTP_ADAPTER_SEQUENCER_PASS"
+	envSequencerDB       = "MF_HTTP_ADAPTER_SEQUENCER_DB"
+	envOrderingRefresh   = "MF_HTTP_ADAPTER_ORDERING_REFRESH"
+	envMaxPayload        = "MF_HTTP_ADAPTER_MAX_PAYLOAD"
+	envOversizePolicy    = "MF_HTTP_ADAPTER_OVERSIZE_POLICY"
+	envMaxBody           = "MF_HTTP_ADAPTER_MAX_BODY"
+	envSpillEndpoint     = "MF_HTTP_ADAPTER_SPILL_S3_ENDPOINT"
+	envSpillRegion       = "MF_HTTP_ADAPTER_SPILL_S3_REGION"
+	envSpillBucket       = "MF_HTTP_ADAPTER_SPILL_S3_BUCKET"
+	envSpillAccessKey    = "MF_HTTP_ADAPTER_SPILL_S3_ACCESS_KEY"
+	envSpillSecretKey    = "MF_HTTP_ADAPTER_SPILL_S3_SECRET_KEY"
+	envSpillTimeout      = "MF_HTTP_ADAPTER_SPILL_S3_TIMEOUT"
+	envAuthSchemes       = "MF_HTTP_ADAPTER_AUTH_SCHEMES"
+	envAuthHeader        = "MF_HTTP_ADAPTER_AUTH_HEADER"
+	envAuthQueryParam    = "MF_HTTP_ADAPTER_AUTH_QUERY_PARAM"
+	envRequestTimeout    = "MF_HTTP_ADAPTER_REQUEST_TIMEOUT"
+	envMTLSPort          = "MF_HTTP_ADAPTER_MTLS_PORT"
+	envClientCACerts     = "MF_HTTP_ADAPTER_CLIENT_CA_CERTS"
+	envMTLSMode          = "MF_HTTP_ADAPTER_MTLS_MODE"
+	envCertsURL          = "MF_HTTP_ADAPTER_CERTS_URL"
+	envCertsTimeout      = "MF_HTTP_ADAPTER_CERTS_TIMEOUT"
 )
 
 type config struct {
-	thingsURL     string
-	natsURL       string
-	logLevel      string
-	port          string
-	clientTLS     bool
-	caCerts       string
-	jaegerURL     string
-	thingsTimeout time.Duration
+	thingsURL       string
+	natsConfig      mfnats.Config
+	logLevel        string
+	port            string
+	grpcPort        string
+	serverCert      string
+	serverKey       string
+	clientTLS       bool
+	caCerts         string
+	jaegerURL       string
+	thingsTimeout   time.Duration
+	authCacheSize   int
+	authCacheTTL    time.Duration
+	authCacheURL    string
+	authCachePass   string
+	authCacheDB     string
+	thingsESURL     string
+	thingsESPass    string
+	thingsESDB      string
+	callbackURL     string
+	callbackTimeout time.Duration
+	region          string
+	federationLinks map[string][]string
+	regionRefresh   time.Duration
+	linkProbePeriod time.Duration
+	sequencerURL    string
+	sequencerPass   string
+	sequencerDB     string
+	orderingRefresh time.Duration
+	payload         payload.Config
+	maxBody         int64
+	spillConfig     s3.Config
+	spillTimeout    time.Duration
+	authKey         api.KeyExtractor
+	requestTimeout  time.Duration
+	mtlsPort        string
+	clientCACerts   string
+	clientAuth      tls.ClientAuthType
+	certsURL        string
+	certsTimeout    time.Duration
 }
 
 func main() {
@@ -71,7 +211,7 @@ func main() {
 		log.Fatalf(err.Error())
 	}
 
-	nc, err := broker.Connect(cfg.natsURL)
+	nc, err := mfnats.Connect(cfg.natsConfig)
 	if err != nil {
 		logger.Error(fmt.Sprintf("Failed to connect to NATS: %s", err))
 		os.Exit(1)
@@ -81,16 +221,33 @@ func main() {
 	conn := connectToThings(cfg, logger)
 	defer conn.Close()
 
-	tracer, closer := initJaeger("http_adapter", cfg.jaegerURL, logger)
+	tracer, closer := tracing.Init("http_adapter", cfg.jaegerURL, logger)
 	defer closer.Close()
 
-	thingsTracer, thingsCloser := initJaeger("things", cfg.jaegerURL, logger)
+	thingsTracer, thingsCloser := tracing.Init("things", cfg.jaegerURL, logger)
 	defer thingsCloser.Close()
 
 	cc := thingsapi.NewClient(conn, thingsTracer, cfg.thingsTimeout)
-	pub := nats.NewMessagePublisher(nc)
+	if cfg.authCacheSize > 0 || cfg.authCacheURL != "" {
+		cc = newAuthCache(cfg, cc, logger)
+	}
+	cc = callback.NewClient(cc, cfg.callbackURL, cfg.callbackTimeout)
+	var pub mainflux.MessagePublisher = nats.NewMessagePublisher(nc, cfg.natsConfig.Prefix)
+	if cfg.region != "" {
+		pub = newRouter(cfg, pub, cc, logger)
+	}
+	if cfg.sequencerURL != "" {
+		seqClient := connectToRedis(cfg.sequencerURL, cfg.sequencerPass, cfg.sequencerDB, "sequencer", logger)
+		defer seqClient.Close()
+		pub = ordering.New(pub, cc, orderingredis.NewSequencer(seqClient), cfg.orderingRefresh, logger)
+	}
+	if cfg.payload.MaxSize > 0 {
+		pub = newPayloadPublisher(cfg, pub, logger)
+	}
+	pub = tracing.NewPublisher(pub, tracer, "publish")
 
-	svc := adapter.New(pub, cc)
+	replies := nats.NewReplies(nc, cfg.natsConfig.Prefix)
+	svc := adapter.New(pub, replies, cc, uuid.New())
 	svc = api.LoggingMiddleware(svc, logger)
 	svc = api.MetricsMiddleware(
 		svc,
@@ -108,14 +265,20 @@ func main() {
 		}, []string{"method"}),
 	)
 
-	errs := make(chan error, 2)
+	errs := make(chan error, 4)
 
 	go func() {
 		p := fmt.Sprintf(":%s", cfg.port)
 		logger.Info(fmt.Sprintf("HTTP adapter service started on port %s", cfg.port))
-		errs <- http.ListenAndServe(p, api.MakeHandler(svc, tracer))
+		errs <- http.ListenAndServe(p, api.MakeHandler(svc, tracer, cfg.authKey, cfg.requestTimeout, cfg.maxBody))
 	}()
 
+	if cfg.mtlsPort != "" {
+		go startMTLSServer(api.MakeHandler(svc, tracer, cfg.authKey, cfg.requestTimeout, cfg.maxBody), cfg, logger, errs)
+	}
+
+	go startGRPCServer(svc, cfg, logger, errs)
+
 	go func() {
 		c := make(chan os.Signal)
 		signal.Notify(c, syscall.SIGINT)
@@ -137,40 +300,149 @@ func loadConfig() config {
 		log.Fatalf("Invalid %s value: %s", envThingsTimeout, err.Error())
 	}
 
-	return config{
-		thingsURL:     mainflux.Env(envThingsURL, defThingsURL),
-		natsURL:       mainflux.Env(envNatsURL, defNatsURL),
-		logLevel:      mainflux.Env(envLogLevel, defLogLevel),
-		port:          mainflux.Env(envPort, defPort),
-		clientTLS:     tls,
-		caCerts:       mainflux.Env(envCACerts, defCACerts),
-		jaegerURL:     mainflux.Env(envJaegerURL, defJaegerURL),
-		thingsTimeout: time.Duration(timeout) * time.Second,
+	authCacheSize, err := strconv.Atoi(mainflux.Env(envAuthCacheSize, defAuthCacheSize))
+	if err != nil || authCacheSize < 0 {
+		log.Fatalf("Invalid value passed for %s\n", envAuthCacheSize)
 	}
-}
 
-func initJaeger(svcName, url string, logger logger.Logger) (opentracing.Tracer, io.Closer) {
-	if url == "" {
-		return opentracing.NoopTracer{}, ioutil.NopCloser(nil)
+	authCacheTTL, err := time.ParseDuration(mainflux.Env(envAuthCacheTTL, defAuthCacheTTL))
+	if err != nil || authCacheTTL <= 0 {
+		log.Fatalf("Invalid value passed for %s\n", envAuthCacheTTL)
 	}
 
-	tracer, closer, err := jconfig.Configuration{
-		ServiceName: svcName,
-		Sampler: &jconfig.SamplerConfig{
-			Type:  "const",
-			Param: 1,
-		},
-		Reporter: &jconfig.ReporterConfig{
-			LocalAgentHostPort: url,
-			LogSpans:           true,
-		},
-	}.NewTracer()
+	cbTimeout, err := strconv.ParseInt(mainflux.Env(envCallbackTimeout, defCallbackTimeout), 10, 64)
 	if err != nil {
-		logger.Error(fmt.Sprintf("Failed to init Jaeger client: %s", err))
-		os.Exit(1)
+		log.Fatalf("Invalid %s value: %s", envCallbackTimeout, err.Error())
+	}
+
+	links, err := routing.ParseLinks(mainflux.Env(envFederationLinks, defFederationLinks))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envFederationLinks, err.Error())
+	}
+
+	regionRefresh, err := time.ParseDuration(mainflux.Env(envRegionRefresh, defRegionRefresh))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envRegionRefresh, err.Error())
 	}
 
-	return tracer, closer
+	linkProbePeriod, err := time.ParseDuration(mainflux.Env(envLinkProbePeriod, defLinkProbePeriod))
+	if err != nil || linkProbePeriod <= 0 {
+		log.Fatalf("Invalid value passed for %s\n", envLinkProbePeriod)
+	}
+
+	orderingRefresh, err := time.ParseDuration(mainflux.Env(envOrderingRefresh, defOrderingRefresh))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envOrderingRefresh, err.Error())
+	}
+
+	requestTimeout, err := time.ParseDuration(mainflux.Env(envRequestTimeout, defRequestTimeout))
+	if err != nil || requestTimeout < time.Second {
+		log.Fatalf("Invalid value passed for %s\n", envRequestTimeout)
+	}
+
+	natsConfig := mfnats.Config{
+		URL:        mainflux.Env(envNatsURL, defNatsURL),
+		Creds:      mainflux.Env(envNatsCreds, defNatsCreds),
+		NKeySeed:   mainflux.Env(envNatsNKeySeed, defNatsNKeySeed),
+		CACerts:    mainflux.Env(envNatsCACerts, defNatsCACerts),
+		ClientCert: mainflux.Env(envNatsClientCert, defNatsClientCert),
+		ClientKey:  mainflux.Env(envNatsClientKey, defNatsClientKey),
+		Prefix:     mainflux.Env(envNatsSubjectPrefix, defNatsSubjectPrefix),
+	}
+
+	authKey, err := api.ParseSchemes(
+		mainflux.Env(envAuthSchemes, defAuthSchemes),
+		mainflux.Env(envAuthHeader, defAuthHeader),
+		mainflux.Env(envAuthQueryParam, defAuthQueryParam),
+	)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envAuthSchemes, err.Error())
+	}
+
+	clientAuth, err := api.ClientAuth(mainflux.Env(envMTLSMode, defMTLSMode))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envMTLSMode, err.Error())
+	}
+
+	certsTimeout, err := strconv.ParseInt(mainflux.Env(envCertsTimeout, defCertsTimeout), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envCertsTimeout, err.Error())
+	}
+
+	maxPayload, err := strconv.Atoi(mainflux.Env(envMaxPayload, defMaxPayload))
+	if err != nil || maxPayload < 0 {
+		log.Fatalf("Invalid value passed for %s\n", envMaxPayload)
+	}
+
+	policy, err := payload.ParsePolicy(mainflux.Env(envOversizePolicy, defOversizePolicy))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envOversizePolicy, err.Error())
+	}
+
+	maxBody, err := strconv.ParseInt(mainflux.Env(envMaxBody, defMaxBody), 10, 64)
+	if err != nil || maxBody < 0 {
+		log.Fatalf("Invalid value passed for %s\n", envMaxBody)
+	}
+	// Rejected payloads aren't read past the maximal payload size.
+	if policy == payload.Reject && maxPayload > 0 && (maxBody == 0 || int64(maxPayload) < maxBody) {
+		maxBody = int64(maxPayload)
+	}
+
+	spillTimeout, err := time.ParseDuration(mainflux.Env(envSpillTimeout, defSpillTimeout))
+	if err != nil || spillTimeout <= 0 {
+		log.Fatalf("Invalid value passed for %s\n", envSpillTimeout)
+	}
+
+	spillConfig := s3.Config{
+		Endpoint:  mainflux.Env(envSpillEndpoint, defSpillEndpoint),
+		Region:    mainflux.Env(envSpillRegion, defSpillRegion),
+		Bucket:    mainflux.Env(envSpillBucket, defSpillBucket),
+		AccessKey: mainflux.Env(envSpillAccessKey, defSpillAccessKey),
+		SecretKey: mainflux.Env(envSpillSecretKey, defSpillSecretKey),
+	}
+
+	return config{
+		thingsURL:       mainflux.Env(envThingsURL, defThingsURL),
+		natsConfig:      natsConfig,
+		logLevel:        mainflux.Env(envLogLevel, defLogLevel),
+		port:            mainflux.Env(envPort, defPort),
+		grpcPort:        mainflux.Env(envGRPCPort, defGRPCPort),
+		serverCert:      mainflux.Env(envServerCert, defServerCert),
+		serverKey:       mainflux.Env(envServerKey, defServerKey),
+		clientTLS:       tls,
+		caCerts:         mainflux.Env(envCACerts, defCACerts),
+		jaegerURL:       mainflux.Env(envJaegerURL, defJaegerURL),
+		thingsTimeout:   time.Duration(timeout) * time.Second,
+		authCacheSize:   authCacheSize,
+		authCacheTTL:    authCacheTTL,
+		authCacheURL:    mainflux.Env(envAuthCacheURL, defAuthCacheURL),
+		authCachePass:   mainflux.Env(envAuthCachePass, defAuthCachePass),
+		authCacheDB:     mainflux.Env(envAuthCacheDB, defAuthCacheDB),
+		thingsESURL:     mainflux.Env(envThingsESURL, defThingsESURL),
+		thingsESPass:    mainflux.Env(envThingsESPass, defThingsESPass),
+		thingsESDB:      mainflux.Env(envThingsESDB, defThingsESDB),
+		callbackURL:     mainflux.Env(envCallbackURL, defCallbackURL),
+		callbackTimeout: time.Duration(cbTimeout) * time.Second,
+		region:          mainflux.Env(envRegion, defRegion),
+		federationLinks: links,
+		regionRefresh:   regionRefresh,
+		linkProbePeriod: linkProbePeriod,
+		sequencerURL:    mainflux.Env(envSequencerURL, defSequencerURL),
+		sequencerPass:   mainflux.Env(envSequencerPass, defSequencerPass),
+		sequencerDB:     mainflux.Env(envSequencerDB, defSequencerDB),
+		orderingRefresh: orderingRefresh,
+		payload:         payload.Config{MaxSize: maxPayload, Policy: policy},
+		maxBody:         maxBody,
+		spillConfig:     spillConfig,
+		spillTimeout:    spillTimeout,
+		authKey:         authKey,
+		requestTimeout:  requestTimeout,
+		mtlsPort:        mainflux.Env(envMTLSPort, defMTLSPort),
+		clientCACerts:   mainflux.Env(envClientCACerts, defClientCACerts),
+		clientAuth:      clientAuth,
+		certsURL:        mainflux.Env(envCertsURL, defCertsURL),
+		certsTimeout:    time.Duration(certsTimeout) * time.Second,
+	}
 }
 
 func connectToThings(cfg config, logger logger.Logger) *grpc.ClientConn {
@@ -196,3 +468,166 @@ func connectToThings(cfg config, logger logger.Logger) *grpc.ClientConn {
 	}
 	return conn
 }
+
+func startGRPCServer(svc adapter.Service, cfg config, logger logger.Logger, errs chan error) {
+	p := fmt.Sprintf(":%s", cfg.grpcPort)
+	listener, err := net.Listen("tcp", p)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to listen on port %s: %s", cfg.grpcPort, err))
+		os.Exit(1)
+	}
+
+	var server *grpc.Server
+	if cfg.serverCert != "" || cfg.serverKey != "" {
+		creds, err := credentials.NewServerTLSFromFile(cfg.serverCert, cfg.serverKey)
+		if err != nil {
+			logger.Error(fmt.Sprintf("Failed to load HTTP adapter certificates: %s", err))
+			os.Exit(1)
+		}
+		logger.Info(fmt.Sprintf("HTTP adapter gRPC service started using https on port %s with cert %s key %s",
+			cfg.grpcPort, cfg.serverCert, cfg.serverKey))
+		server = grpc.NewServer(grpc.Creds(creds))
+	} else {
+		logger.Info(fmt.Sprintf("HTTP adapter gRPC service started using http on port %s", cfg.grpcPort))
+		server = grpc.NewServer()
+	}
+
+	mainflux.RegisterPublisherServiceServer(server, grpcapi.NewServer(svc))
+	errs <- server.Serve(listener)
+}
+
+// startMTLSServer serves the HTTP API over mutual TLS, where the things may
+// authenticate using the client certificates instead of the keys.
+func startMTLSServer(handler http.Handler, cfg config, logger logger.Logger, errs chan error) {
+	ca, err := ioutil.ReadFile(cfg.clientCACerts)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to load client CA certificates: %s", err))
+		os.Exit(1)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		logger.Error("Failed to parse client CA certificates")
+		os.Exit(1)
+	}
+
+	var status certsclient.Client
+	if cfg.certsURL != "" {
+		status = certsclient.New(cfg.certsURL, cfg.certsTimeout)
+	} else {
+		logger.Warn("Certs service URL is not set, revoked certificates are accepted")
+	}
+
+	server := &http.Server{
+		Addr:    fmt.Sprintf(":%s", cfg.mtlsPort),
+		Handler: api.CertAuth(handler, status),
+		TLSConfig: &tls.Config{
+			ClientCAs:  pool,
+			ClientAuth: cfg.clientAuth,
+		},
+	}
+
+	logger.Info(fmt.Sprintf("HTTP adapter service started using mutual TLS on port %s", cfg.mtlsPort))
+	errs <- server.ListenAndServeTLS(cfg.serverCert, cfg.serverKey)
+}
+
+func connectToRedis(redisURL, redisPass, redisDB, store string, logger logger.Logger) redis.UniversalClient {
+	db, err := strconv.Atoi(redisDB)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to %s store: %s", store, err))
+		os.Exit(1)
+	}
+
+	client, err := mfredis.Connect(redisURL, redisPass, db)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to %s store: %s", store, err))
+		os.Exit(1)
+	}
+
+	return client
+}
+
+// newAuthCache caches the accesses granted by the things service, keeping the
+// cache up to date by consuming the things events. The shared cache is
+// invalidated by a single instance of the group, while each instance keeping
+// its own cache reads all the events without the consumer group.
+func newAuthCache(cfg config, cc mainflux.ThingsServiceClient, logger logger.Logger) mainflux.ThingsServiceClient {
+	store := cache.NewLRUStore(cfg.authCacheSize, cfg.authCacheTTL)
+	var group, instance string
+	if cfg.authCacheURL != "" {
+		cacheClient := connectToRedis(cfg.authCacheURL, cfg.authCachePass, cfg.authCacheDB, "auth cache", logger)
+		store = cacheredis.NewStore(cacheClient, cfg.authCacheTTL)
+
+		group = "mainflux.http-adapter.auth-cache"
+		var err error
+		if instance, err = os.Hostname(); err != nil {
+			instance = strconv.FormatInt(time.Now().UnixNano(), 10)
+		}
+	}
+	client := cache.NewClient(cc, store)
+
+	esClient := connectToRedis(cfg.thingsESURL, cfg.thingsESPass, cfg.thingsESDB, "things event", logger)
+	c := consumer.New(esClient, consumer.Config{
+		Stream:   consumer.ThingsStream,
+		Group:    group,
+		Consumer: instance,
+	}, logger)
+
+	go func() {
+		if err := c.Consume(context.Background(), cache.Handler(client)); err != nil {
+			logger.Error(fmt.Sprintf("Failed to consume things events: %s", err))
+		}
+	}()
+
+	return client
+}
+
+// newPayloadPublisher enforces the maximal payload size of the published
+// messages. Spill policy stores the oversized payloads to the object store.
+func newPayloadPublisher(cfg config, pub mainflux.MessagePublisher, logger logger.Logger) mainflux.MessagePublisher {
+	var store payload.Store
+	if cfg.payload.Policy == payload.Spill {
+		store = s3.NewClient(cfg.spillConfig, &http.Client{Timeout: cfg.spillTimeout})
+	}
+
+	violations := kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+		Namespace: "http_adapter",
+		Subsystem: "payload",
+		Name:      "oversized_count",
+		Help:      "Number of messages exceeding the maximal payload size.",
+	}, []string{"policy"})
+
+	lp, err := payload.New(pub, cfg.payload, store, violations, logger)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to create payload size limiter: %s", err))
+		os.Exit(1)
+	}
+
+	return lp
+}
+
+func newRouter(cfg config, local mainflux.MessagePublisher, tc mainflux.ThingsServiceClient, logger logger.Logger) routing.Router {
+	links := make(map[string][]routing.Link)
+	for region, urls := range cfg.federationLinks {
+		for _, url := range urls {
+			nc, err := broker.Connect(url)
+			if err != nil {
+				logger.Error(fmt.Sprintf("Failed to connect to NATS of region %s: %s", region, err))
+				os.Exit(1)
+			}
+			links[region] = append(links[region], routingnats.NewLink(nc, cfg.natsConfig.Prefix, cfg.linkProbePeriod))
+		}
+	}
+
+	router := routing.New(cfg.region, local, links, tc, cfg.regionRefresh, logger)
+	go startProbing(router, cfg.linkProbePeriod, logger)
+	return router
+}
+
+func startProbing(router routing.Router, period time.Duration, logger logger.Logger) {
+	logger.Info(fmt.Sprintf("Probing federation links every %s", period))
+	for {
+		router.Probe()
+		time.Sleep(period)
+	}
+}
@@ -13,12 +13,15 @@ import (
 	"time"
 
 	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	"github.com/go-redis/redis"
 	influxdata "github.com/influxdata/influxdb/client/v2"
 	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/logger"
 	"github.com/mainflux/mainflux/readers"
 	"github.com/mainflux/mainflux/readers/api"
 	"github.com/mainflux/mainflux/readers/influxdb"
+	subtopicsredis "github.com/mainflux/mainflux/readers/redis"
+	mfredis "github.com/mainflux/mainflux/redis"
 	thingsapi "github.com/mainflux/mainflux/things/api/auth/grpc"
 	opentracing "github.com/opentracing/opentracing-go"
 	stdprometheus "github.com/prometheus/client_golang/prometheus"
@@ -40,6 +43,9 @@ const (
 	defCACerts       = ""
 	defJaegerURL     = ""
 	defThingsTimeout = "1" // in seconds
+	defSubtopicsURL  = ""
+	defSubtopicsPass = ""
+	defSubtopicsDB   = "0"
 
 	envThingsURL     = "MF_THINGS_URL"
 	envLogLevel      = "MF_INFLUX_READER_LOG_LEVEL"
@@ -53,6 +59,9 @@ const (
 	envCACerts       = "MF_INFLUX_READER_CA_CERTS"
 	envJaegerURL     = "MF_JAEGER_URL"
 	envThingsTimeout = "MF_INFLUX_READER_THINGS_TIMEOUT"
+	envSubtopicsURL  = "MF_INFLUX_READER_SUBTOPICS_URL"
+	envSubtopicsPass = "MF_INFLUX_READER_SUBTOPICS_PASS"
+	envSubtopicsDB   = "MF_INFLUX_READER_SUBTOPICS_DB"
 )
 
 type config struct {
@@ -68,6 +77,9 @@ type config struct {
 	caCerts       string
 	jaegerURL     string
 	thingsTimeout time.Duration
+	subtopicsURL  string
+	subtopicsPass string
+	subtopicsDB   string
 }
 
 func main() {
@@ -93,6 +105,13 @@ func main() {
 
 	repo := newService(client, cfg.dbName, logger)
 
+	var subtopics readers.SubtopicRepository
+	if cfg.subtopicsURL != "" {
+		subtopicsClient := connectToRedis(cfg.subtopicsURL, cfg.subtopicsPass, cfg.subtopicsDB, logger)
+		defer subtopicsClient.Close()
+		subtopics = subtopicsredis.NewSubtopicRepository(subtopicsClient)
+	}
+
 	errs := make(chan error, 2)
 	go func() {
 		c := make(chan os.Signal)
@@ -100,7 +119,7 @@ func main() {
 		errs <- fmt.Errorf("%s", <-c)
 	}()
 
-	go startHTTPServer(repo, tc, cfg.port, logger, errs)
+	go startHTTPServer(repo, subtopics, tc, cfg.port, logger, errs)
 
 	err = <-errs
 	logger.Error(fmt.Sprintf("InfluxDB writer service terminated: %s", err))
@@ -130,6 +149,9 @@ func loadConfigs() (config, influxdata.HTTPConfig) {
 		caCerts:       mainflux.Env(envCACerts, defCACerts),
 		jaegerURL:     mainflux.Env(envJaegerURL, defJaegerURL),
 		thingsTimeout: time.Duration(timeout) * time.Second,
+		subtopicsURL:  mainflux.Env(envSubtopicsURL, defSubtopicsURL),
+		subtopicsPass: mainflux.Env(envSubtopicsPass, defSubtopicsPass),
+		subtopicsDB:   mainflux.Env(envSubtopicsDB, defSubtopicsDB),
 	}
 
 	clientCfg := influxdata.HTTPConfig{
@@ -141,6 +163,22 @@ func loadConfigs() (config, influxdata.HTTPConfig) {
 	return cfg, clientCfg
 }
 
+func connectToRedis(redisURL, redisPass, redisDB string, logger logger.Logger) redis.UniversalClient {
+	db, err := strconv.Atoi(redisDB)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to subtopics store: %s", err))
+		os.Exit(1)
+	}
+
+	client, err := mfredis.Connect(redisURL, redisPass, db)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to subtopics store: %s", err))
+		os.Exit(1)
+	}
+
+	return client
+}
+
 func connectToThings(cfg config, logger logger.Logger) *grpc.ClientConn {
 	var opts []grpc.DialOption
 	if cfg.clientTLS {
@@ -211,8 +249,8 @@ func newService(client influxdata.Client, dbName string, logger logger.Logger) r
 	return repo
 }
 
-func startHTTPServer(repo readers.MessageRepository, tc mainflux.ThingsServiceClient, port string, logger logger.Logger, errs chan error) {
+func startHTTPServer(repo readers.MessageRepository, subtopics readers.SubtopicRepository, tc mainflux.ThingsServiceClient, port string, logger logger.Logger, errs chan error) {
 	p := fmt.Sprintf(":%s", port)
 	logger.Info(fmt.Sprintf("InfluxDB reader service started, exposed port %s", port))
-	errs <- http.ListenAndServe(p, api.MakeHandler(repo, tc, "influxdb-reader"))
+	errs <- http.ListenAndServe(p, api.MakeHandler(repo, subtopics, tc, "influxdb-reader"))
 }
@@ -17,37 +17,51 @@ import (
 	"github.com/mainflux/mainflux/lora/api"
 	pub "github.com/mainflux/mainflux/lora/nats"
 	mqttBroker "github.com/mainflux/mainflux/lora/paho"
+	mfnats "github.com/mainflux/mainflux/nats"
+	mfredis "github.com/mainflux/mainflux/redis"
 
 	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
 	"github.com/mainflux/mainflux/lora/redis"
-	nats "github.com/nats-io/go-nats"
+	nats "github.com/nats-io/nats.go"
 	stdprometheus "github.com/prometheus/client_golang/prometheus"
 )
 
 const (
-	defHTTPPort     = "8180"
-	defLoraMsgURL   = "tcp://localhost:1883"
-	defNatsURL      = nats.DefaultURL
-	defLogLevel     = "error"
-	defESURL        = "localhost:6379"
-	defESPass       = ""
-	defESDB         = "0"
-	defInstanceName = "lora"
-	defRouteMapURL  = "localhost:6379"
-	defRouteMapPass = ""
-	defRouteMapDB   = "0"
-
-	envHTTPPort     = "MF_LORA_ADAPTER_HTTP_PORT"
-	envLoraMsgURL   = "MF_LORA_ADAPTER_MESSAGES_URL"
-	envNatsURL      = "MF_NATS_URL"
-	envLogLevel     = "MF_LORA_ADAPTER_LOG_LEVEL"
-	envESURL        = "MF_THINGS_ES_URL"
-	envESPass       = "MF_THINGS_ES_PASS"
-	envESDB         = "MF_THINGS_ES_DB"
-	envInstanceName = "MF_LORA_ADAPTER_INSTANCE_NAME"
-	envRouteMapURL  = "MF_LORA_ADAPTER_ROUTEMAP_URL"
-	envRouteMapPass = "MF_LORA_ADAPTER_ROUTEMAP_PASS"
-	envRouteMapDB   = "MF_LORA_ADAPTER_ROUTEMAP_DB"
+	defHTTPPort          = "8180"
+	defLoraMsgURL        = "tcp://localhost:1883"
+	defNatsURL           = nats.DefaultURL
+	defNatsCreds         = ""
+	defNatsNKeySeed      = ""
+	defNatsCACerts       = ""
+	defNatsClientCert    = ""
+	defNatsClientKey     = ""
+	defNatsSubjectPrefix = ""
+	defLogLevel          = "error"
+	defESURL             = "localhost:6379"
+	defESPass            = ""
+	defESDB              = "0"
+	defInstanceName      = "lora"
+	defRouteMapURL       = "localhost:6379"
+	defRouteMapPass      = ""
+	defRouteMapDB        = "0"
+
+	envHTTPPort          = "MF_LORA_ADAPTER_HTTP_PORT"
+	envLoraMsgURL        = "MF_LORA_ADAPTER_MESSAGES_URL"
+	envNatsURL           = "MF_NATS_URL"
+	envNatsCreds         = "MF_NATS_CREDS"
+	envNatsNKeySeed      = "MF_NATS_NKEY_SEED"
+	envNatsCACerts       = "MF_NATS_CA_CERTS"
+	envNatsClientCert    = "MF_NATS_CLIENT_CERT"
+	envNatsClientKey     = "MF_NATS_CLIENT_KEY"
+	envNatsSubjectPrefix = "MF_NATS_SUBJECT_PREFIX"
+	envLogLevel          = "MF_LORA_ADAPTER_LOG_LEVEL"
+	envESURL             = "MF_THINGS_ES_URL"
+	envESPass            = "MF_THINGS_ES_PASS"
+	envESDB              = "MF_THINGS_ES_DB"
+	envInstanceName      = "MF_LORA_ADAPTER_INSTANCE_NAME"
+	envRouteMapURL       = "MF_LORA_ADAPTER_ROUTEMAP_URL"
+	envRouteMapPass      = "MF_LORA_ADAPTER_ROUTEMAP_PASS"
+	envRouteMapDB        = "MF_LORA_ADAPTER_ROUTEMAP_DB"
 
 	loraServerTopic = "application/+/device/+/rx"
 
@@ -58,7 +72,7 @@ const (
 type config struct {
 	httpPort     string
 	loraMsgURL   string
-	natsURL      string
+	natsConfig   mfnats.Config
 	logLevel     string
 	esURL        string
 	esPass       string
@@ -77,7 +91,7 @@ func main() {
 		log.Fatalf(err.Error())
 	}
 
-	natsConn := connectToNATS(cfg.natsURL, logger)
+	natsConn := connectToNATS(cfg.natsConfig, logger)
 	defer natsConn.Close()
 
 	rmConn := connectToRedis(cfg.routeMapURL, cfg.routeMapPass, cfg.routeMapDB, logger)
@@ -86,14 +100,15 @@ func main() {
 	esConn := connectToRedis(cfg.esURL, cfg.esPass, cfg.esDB, logger)
 	defer esConn.Close()
 
-	publisher := pub.NewMessagePublisher(natsConn)
+	publisher := pub.NewMessagePublisher(natsConn, cfg.natsConfig.Prefix)
 
 	thingRM := newRouteMapRepositoy(rmConn, thingsRMPrefix, logger)
 	chanRM := newRouteMapRepositoy(rmConn, channelsRMPrefix, logger)
 
 	mqttConn := connectToMQTTBroker(cfg.loraMsgURL, logger)
+	downlinks := mqttBroker.NewDownlinkPublisher(mqttConn)
 
-	svc := lora.New(publisher, thingRM, chanRM)
+	svc := lora.New(publisher, thingRM, chanRM, downlinks)
 	svc = api.LoggingMiddleware(svc, logger)
 	svc = api.MetricsMiddleware(
 		svc,
@@ -112,6 +127,7 @@ func main() {
 	)
 
 	go subscribeToLoRaBroker(svc, mqttConn, logger)
+	subscribeToDownlinks(svc, natsConn, cfg.natsConfig.Prefix, logger)
 	go subscribeToThingsES(svc, esConn, cfg.instanceName, logger)
 
 	errs := make(chan error, 2)
@@ -129,10 +145,20 @@ func main() {
 }
 
 func loadConfig() config {
+	natsConfig := mfnats.Config{
+		URL:        mainflux.Env(envNatsURL, defNatsURL),
+		Creds:      mainflux.Env(envNatsCreds, defNatsCreds),
+		NKeySeed:   mainflux.Env(envNatsNKeySeed, defNatsNKeySeed),
+		CACerts:    mainflux.Env(envNatsCACerts, defNatsCACerts),
+		ClientCert: mainflux.Env(envNatsClientCert, defNatsClientCert),
+		ClientKey:  mainflux.Env(envNatsClientKey, defNatsClientKey),
+		Prefix:     mainflux.Env(envNatsSubjectPrefix, defNatsSubjectPrefix),
+	}
+
 	return config{
 		httpPort:     mainflux.Env(envHTTPPort, defHTTPPort),
 		loraMsgURL:   mainflux.Env(envLoraMsgURL, defLoraMsgURL),
-		natsURL:      mainflux.Env(envNatsURL, defNatsURL),
+		natsConfig:   natsConfig,
 		logLevel:     mainflux.Env(envLogLevel, defLogLevel),
 		esURL:        mainflux.Env(envESURL, defESURL),
 		esPass:       mainflux.Env(envESPass, defESPass),
@@ -144,8 +170,8 @@ func loadConfig() config {
 	}
 }
 
-func connectToNATS(url string, logger logger.Logger) *nats.Conn {
-	conn, err := nats.Connect(url)
+func connectToNATS(cfg mfnats.Config, logger logger.Logger) *nats.Conn {
+	conn, err := mfnats.Connect(cfg)
 	if err != nil {
 		logger.Error(fmt.Sprintf("Failed to connect to NATS: %s", err))
 		os.Exit(1)
@@ -177,18 +203,20 @@ func connectToMQTTBroker(loraURL string, logger logger.Logger) mqtt.Client {
 	return client
 }
 
-func connectToRedis(redisURL, redisPass, redisDB string, logger logger.Logger) *r.Client {
+func connectToRedis(redisURL, redisPass, redisDB string, logger logger.Logger) r.UniversalClient {
 	db, err := strconv.Atoi(redisDB)
 	if err != nil {
 		logger.Error(fmt.Sprintf("Failed to connect to redis: %s", err))
 		os.Exit(1)
 	}
 
-	return r.NewClient(&r.Options{
-		Addr:     redisURL,
-		Password: redisPass,
-		DB:       db,
-	})
+	client, err := mfredis.Connect(redisURL, redisPass, db)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to redis: %s", err))
+		os.Exit(1)
+	}
+
+	return client
 }
 
 func subscribeToLoRaBroker(svc lora.Service, mc mqtt.Client, logger logger.Logger) {
@@ -200,7 +228,15 @@ func subscribeToLoRaBroker(svc lora.Service, mc mqtt.Client, logger logger.Logge
 	}
 }
 
-func subscribeToThingsES(svc lora.Service, client *r.Client, consumer string, logger logger.Logger) {
+func subscribeToDownlinks(svc lora.Service, nc *nats.Conn, subjectPrefix string, logger logger.Logger) {
+	if err := pub.SubscribeDownlinks(nc, subjectPrefix, svc, logger); err != nil {
+		logger.Error(fmt.Sprintf("Failed to subscribe to NATS downlinks: %s", err))
+		os.Exit(1)
+	}
+	logger.Info("Subscribed to NATS downlinks")
+}
+
+func subscribeToThingsES(svc lora.Service, client r.UniversalClient, consumer string, logger logger.Logger) {
 	eventStore := redis.NewEventStore(svc, client, consumer, logger)
 	logger.Info("Subscribed to Redis Event Store")
 	if err := eventStore.Subscribe("mainflux.things"); err != nil {
@@ -208,7 +244,7 @@ func subscribeToThingsES(svc lora.Service, client *r.Client, consumer string, lo
 	}
 }
 
-func newRouteMapRepositoy(client *r.Client, prefix string, logger logger.Logger) lora.RouteMapRepository {
+func newRouteMapRepositoy(client r.UniversalClient, prefix string, logger logger.Logger) lora.RouteMapRepository {
 	logger.Info("Connected to Redis Route map")
 	return redis.NewRouteMapRepository(client, prefix)
 }
@@ -0,0 +1,413 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	"github.com/jmoiron/sqlx"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/http/nats"
+	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/lwm2m"
+	"github.com/mainflux/mainflux/lwm2m/api"
+	"github.com/mainflux/mainflux/lwm2m/postgres"
+	"github.com/mainflux/mainflux/lwm2m/things"
+	mfnats "github.com/mainflux/mainflux/nats"
+	"github.com/mainflux/mainflux/payload"
+	mfsdk "github.com/mainflux/mainflux/sdk/go"
+	thingsapi "github.com/mainflux/mainflux/things/api/auth/grpc"
+	"github.com/mainflux/mainflux/things/uuid"
+	"github.com/mainflux/mainflux/writers/s3"
+	broker "github.com/nats-io/nats.go"
+	opentracing "github.com/opentracing/opentracing-go"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+	jconfig "github.com/uber/jaeger-client-go/config"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+const (
+	defLogLevel          = "error"
+	defPort              = "5685"
+	defHTTPPort          = "8205"
+	defDBHost            = "localhost"
+	defDBPort            = "5432"
+	defDBUser            = "mainflux"
+	defDBPass            = "mainflux"
+	defDBName            = "lwm2m"
+	defDBSSLMode         = "disable"
+	defDBSSLCert         = ""
+	defDBSSLKey          = ""
+	defDBSSLRootCert     = ""
+	defClientTLS         = "false"
+	defCACerts           = ""
+	defThingsURL         = "localhost:8181"
+	defThingsTimeout     = "1" // in seconds
+	defBaseURL           = "http://localhost"
+	defThingsPrefix      = ""
+	defServerURI         = "coap://localhost:5685"
+	defLifetime          = "86400" // in seconds
+	defNatsURL           = broker.DefaultURL
+	defNatsCreds         = ""
+	defNatsNKeySeed      = ""
+	defNatsCACerts       = ""
+	defNatsClientCert    = ""
+	defNatsClientKey     = ""
+	defNatsSubjectPrefix = ""
+	defJaegerURL         = ""
+	defMaxPayload        = "0"
+	defOversizePolicy    = "reject"
+	defSpillEndpoint     = ""
+	defSpillRegion       = "us-east-1"
+	defSpillBucket       = "spilled-payloads"
+	defSpillAccessKey    = ""
+	defSpillSecretKey    = ""
+	defSpillTimeout      = "10s"
+
+	envLogLevel          = "MF_LWM2M_ADAPTER_LOG_LEVEL"
+	envPort              = "MF_LWM2M_ADAPTER_PORT"
+	envHTTPPort          = "MF_LWM2M_ADAPTER_HTTP_PORT"
+	envDBHost            = "MF_LWM2M_ADAPTER_DB_HOST"
+	envDBPort            = "MF_LWM2M_ADAPTER_DB_PORT"
+	envDBUser            = "MF_LWM2M_ADAPTER_DB_USER"
+	envDBPass            = "MF_LWM2M_ADAPTER_DB_PASS"
+	envDBName            = "MF_LWM2M_ADAPTER_DB"
+	envDBSSLMode         = "MF_LWM2M_ADAPTER_DB_SSL_MODE"
+	envDBSSLCert         = "MF_LWM2M_ADAPTER_DB_SSL_CERT"
+	envDBSSLKey          = "MF_LWM2M_ADAPTER_DB_SSL_KEY"
+	envDBSSLRootCert     = "MF_LWM2M_ADAPTER_DB_SSL_ROOT_CERT"
+	envClientTLS         = "MF_LWM2M_ADAPTER_CLIENT_TLS"
+	envCACerts           = "MF_LWM2M_ADAPTER_CA_CERTS"
+	envThingsURL         = "MF_THINGS_URL"
+	envThingsTimeout     = "MF_LWM2M_ADAPTER_THINGS_TIMEOUT"
+	envBaseURL           = "MF_SDK_BASE_URL"
+	envThingsPrefix      = "MF_SDK_THINGS_PREFIX"
+	envServerURI         = "MF_LWM2M_ADAPTER_SERVER_URI"
+	envLifetime          = "MF_LWM2M_ADAPTER_LIFETIME"
+	envNatsURL           = "MF_NATS_URL"
+	envNatsCreds         = "MF_NATS_CREDS"
+	envNatsNKeySeed      = "MF_NATS_NKEY_SEED"
+	envNatsCACerts       = "MF_NATS_CA_CERTS"
+	envNatsClientCert    = "MF_NATS_CLIENT_CERT"
+	envNatsClientKey     = "MF_NATS_CLIENT_KEY"
+	envNatsSubjectPrefix = "MF_NATS_SUBJECT_PREFIX"
+	envJaegerURL         = "MF_JAEGER_URL"
+	envMaxPayload        = "MF_LWM2M_ADAPTER_MAX_PAYLOAD"
+	envOversizePolicy    = "MF_LWM2M_ADAPTER_OVERSIZE_POLICY"
+	envSpillEndpoint     = "MF_LWM2M_ADAPTER_SPILL_S3_ENDPOINT"
+	envSpillRegion       = "MF_LWM2M_ADAPTER_SPILL_S3_REGION"
+	envSpillBucket       = "MF_LWM2M_ADAPTER_SPILL_S3_BUCKET"
+	envSpillAccessKey    = "MF_LWM2M_ADAPTER_SPILL_S3_ACCESS_KEY"
+	envSpillSecretKey    = "MF_LWM2M_ADAPTER_SPILL_S3_SECRET_KEY"
+	envSpillTimeout      = "MF_LWM2M_ADAPTER_SPILL_S3_TIMEOUT"
+)
+
+type config struct {
+	logLevel      string
+	port          string
+	httpPort      string
+	dbConfig      postgres.Config
+	clientTLS     bool
+	caCerts       string
+	thingsURL     string
+	thingsTimeout time.Duration
+	baseURL       string
+	thingsPrefix  string
+	account       lwm2m.Account
+	natsConfig    mfnats.Config
+	jaegerURL     string
+	payload       payload.Config
+	spillConfig   s3.Config
+	spillTimeout  time.Duration
+}
+
+func main() {
+	cfg := loadConfig()
+
+	logger, err := logger.New(os.Stdout, cfg.logLevel)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	db := connectToDB(cfg.dbConfig, logger)
+	defer db.Close()
+
+	nc, err := mfnats.Connect(cfg.natsConfig)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to NATS: %s", err))
+		os.Exit(1)
+	}
+	defer nc.Close()
+
+	conn := connectToThings(cfg, logger)
+	defer conn.Close()
+
+	thingsTracer, thingsCloser := initJaeger("things", cfg.jaegerURL, logger)
+	defer thingsCloser.Close()
+
+	udpConn := listen(cfg.port, logger)
+	defer udpConn.Close()
+
+	server := api.NewServer(udpConn, logger)
+	cc := thingsapi.NewClient(conn, thingsTracer, cfg.thingsTimeout)
+	pub := nats.NewMessagePublisher(nc, cfg.natsConfig.Prefix)
+	if cfg.payload.MaxSize > 0 {
+		pub = newPayloadPublisher(cfg, pub, logger)
+	}
+
+	svc := newService(cc, db, server, pub, cfg, logger)
+
+	errs := make(chan error, 3)
+
+	go startHTTPServer(svc, cfg.httpPort, logger, errs)
+	go startLwM2MServer(server, svc, cfg.port, logger, errs)
+
+	go func() {
+		c := make(chan os.Signal, 1)
+		signal.Notify(c, syscall.SIGINT)
+		errs <- fmt.Errorf("%s", <-c)
+	}()
+
+	err = <-errs
+	logger.Error(fmt.Sprintf("LwM2M adapter terminated: %s", err))
+}
+
+func loadConfig() config {
+	tls, err := strconv.ParseBool(mainflux.Env(envClientTLS, defClientTLS))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envClientTLS)
+	}
+
+	timeout, err := strconv.ParseInt(mainflux.Env(envThingsTimeout, defThingsTimeout), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envThingsTimeout, err.Error())
+	}
+
+	lifetime, err := strconv.ParseUint(mainflux.Env(envLifetime, defLifetime), 10, 32)
+	if err != nil || lifetime == 0 {
+		log.Fatalf("Invalid value passed for %s\n", envLifetime)
+	}
+
+	dbConfig := postgres.Config{
+		Host:        mainflux.Env(envDBHost, defDBHost),
+		Port:        mainflux.Env(envDBPort, defDBPort),
+		User:        mainflux.Env(envDBUser, defDBUser),
+		Pass:        mainflux.Env(envDBPass, defDBPass),
+		Name:        mainflux.Env(envDBName, defDBName),
+		SSLMode:     mainflux.Env(envDBSSLMode, defDBSSLMode),
+		SSLCert:     mainflux.Env(envDBSSLCert, defDBSSLCert),
+		SSLKey:      mainflux.Env(envDBSSLKey, defDBSSLKey),
+		SSLRootCert: mainflux.Env(envDBSSLRootCert, defDBSSLRootCert),
+	}
+
+	// The adapter is the only LwM2M server the clients are bootstrapped
+	// with, so its Short Server ID is always 1.
+	account := lwm2m.Account{
+		ServerURI:     mainflux.Env(envServerURI, defServerURI),
+		ShortServerID: 1,
+		Lifetime:      time.Duration(lifetime) * time.Second,
+		Binding:       "U",
+	}
+
+	natsConfig := mfnats.Config{
+		URL:        mainflux.Env(envNatsURL, defNatsURL),
+		Creds:      mainflux.Env(envNatsCreds, defNatsCreds),
+		NKeySeed:   mainflux.Env(envNatsNKeySeed, defNatsNKeySeed),
+		CACerts:    mainflux.Env(envNatsCACerts, defNatsCACerts),
+		ClientCert: mainflux.Env(envNatsClientCert, defNatsClientCert),
+		ClientKey:  mainflux.Env(envNatsClientKey, defNatsClientKey),
+		Prefix:     mainflux.Env(envNatsSubjectPrefix, defNatsSubjectPrefix),
+	}
+
+	maxPayload, err := strconv.Atoi(mainflux.Env(envMaxPayload, defMaxPayload))
+	if err != nil || maxPayload < 0 {
+		log.Fatalf("Invalid value passed for %s\n", envMaxPayload)
+	}
+
+	policy, err := payload.ParsePolicy(mainflux.Env(envOversizePolicy, defOversizePolicy))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envOversizePolicy, err.Error())
+	}
+
+	spillTimeout, err := time.ParseDuration(mainflux.Env(envSpillTimeout, defSpillTimeout))
+	if err != nil || spillTimeout <= 0 {
+		log.Fatalf("Invalid value passed for %s\n", envSpillTimeout)
+	}
+
+	spillConfig := s3.Config{
+		Endpoint:  mainflux.Env(envSpillEndpoint, defSpillEndpoint),
+		Region:    mainflux.Env(envSpillRegion, defSpillRegion),
+		Bucket:    mainflux.Env(envSpillBucket, defSpillBucket),
+		AccessKey: mainflux.Env(envSpillAccessKey, defSpillAccessKey),
+		SecretKey: mainflux.Env(envSpillSecretKey, defSpillSecretKey),
+	}
+
+	return config{
+		logLevel:      mainflux.Env(envLogLevel, defLogLevel),
+		port:          mainflux.Env(envPort, defPort),
+		httpPort:      mainflux.Env(envHTTPPort, defHTTPPort),
+		dbConfig:      dbConfig,
+		clientTLS:     tls,
+		caCerts:       mainflux.Env(envCACerts, defCACerts),
+		thingsURL:     mainflux.Env(envThingsURL, defThingsURL),
+		thingsTimeout: time.Duration(timeout) * time.Second,
+		baseURL:       mainflux.Env(envBaseURL, defBaseURL),
+		thingsPrefix:  mainflux.Env(envThingsPrefix, defThingsPrefix),
+		account:       account,
+		natsConfig:    natsConfig,
+		jaegerURL:     mainflux.Env(envJaegerURL, defJaegerURL),
+		payload:       payload.Config{MaxSize: maxPayload, Policy: policy},
+		spillConfig:   spillConfig,
+		spillTimeout:  spillTimeout,
+	}
+}
+
+func connectToDB(dbConfig postgres.Config, logger logger.Logger) *sqlx.DB {
+	db, err := postgres.Connect(dbConfig)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to Postgres: %s", err))
+		os.Exit(1)
+	}
+	return db
+}
+
+// newPayloadPublisher enforces the maximal payload size of the published
+// messages. Spill policy stores the oversized payloads to the object store.
+func newPayloadPublisher(cfg config, pub mainflux.MessagePublisher, logger logger.Logger) mainflux.MessagePublisher {
+	var store payload.Store
+	if cfg.payload.Policy == payload.Spill {
+		store = s3.NewClient(cfg.spillConfig, &http.Client{Timeout: cfg.spillTimeout})
+	}
+
+	violations := kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+		Namespace: "lwm2m_adapter",
+		Subsystem: "payload",
+		Name:      "oversized_count",
+		Help:      "Number of messages exceeding the maximal payload size.",
+	}, []string{"policy"})
+
+	lp, err := payload.New(pub, cfg.payload, store, violations, logger)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to create payload size limiter: %s", err))
+		os.Exit(1)
+	}
+
+	return lp
+}
+
+func connectToThings(cfg config, logger logger.Logger) *grpc.ClientConn {
+	var opts []grpc.DialOption
+	if cfg.clientTLS {
+		if cfg.caCerts != "" {
+			tpc, err := credentials.NewClientTLSFromFile(cfg.caCerts, "")
+			if err != nil {
+				logger.Error(fmt.Sprintf("Failed to load certs: %s", err))
+				os.Exit(1)
+			}
+			opts = append(opts, grpc.WithTransportCredentials(tpc))
+		}
+	} else {
+		logger.Info("gRPC communication is not encrypted")
+		opts = append(opts, grpc.WithInsecure())
+	}
+
+	conn, err := grpc.Dial(cfg.thingsURL, opts...)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to things service: %s", err))
+		os.Exit(1)
+	}
+	return conn
+}
+
+func initJaeger(svcName, url string, logger logger.Logger) (opentracing.Tracer, io.Closer) {
+	if url == "" {
+		return opentracing.NoopTracer{}, ioutil.NopCloser(nil)
+	}
+
+	tracer, closer, err := jconfig.Configuration{
+		ServiceName: svcName,
+		Sampler: &jconfig.SamplerConfig{
+			Type:  "const",
+			Param: 1,
+		},
+		Reporter: &jconfig.ReporterConfig{
+			LocalAgentHostPort: url,
+			LogSpans:           true,
+		},
+	}.NewTracer()
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to init Jaeger client: %s", err))
+		os.Exit(1)
+	}
+
+	return tracer, closer
+}
+
+func listen(port string, logger logger.Logger) *net.UDPConn {
+	addr, err := net.ResolveUDPAddr("udp", fmt.Sprintf(":%s", port))
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to resolve LwM2M address: %s", err))
+		os.Exit(1)
+	}
+
+	conn, err := net.ListenUDP("udp", addr)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to listen on port %s: %s", port, err))
+		os.Exit(1)
+	}
+	return conn
+}
+
+func newService(auth mainflux.ThingsServiceClient, db *sqlx.DB, devices lwm2m.Devices, pub mainflux.MessagePublisher, cfg config, logger logger.Logger) lwm2m.Service {
+	sdk := mfsdk.NewSDK(mfsdk.Config{
+		BaseURL:      cfg.baseURL,
+		ThingsPrefix: cfg.thingsPrefix,
+	})
+
+	repo := postgres.New(db)
+	t := things.New(sdk)
+
+	svc := lwm2m.New(auth, t, repo, devices, pub, uuid.New(), cfg.account)
+	svc = api.LoggingMiddleware(svc, logger)
+	svc = api.MetricsMiddleware(
+		svc,
+		kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: "lwm2m_adapter",
+			Subsystem: "api",
+			Name:      "request_count",
+			Help:      "Number of requests received.",
+		}, []string{"method"}),
+		kitprometheus.NewSummaryFrom(stdprometheus.SummaryOpts{
+			Namespace: "lwm2m_adapter",
+			Subsystem: "api",
+			Name:      "request_latency_microseconds",
+			Help:      "Total duration of requests in microseconds.",
+		}, []string{"method"}),
+	)
+
+	return svc
+}
+
+func startHTTPServer(svc lwm2m.Service, port string, logger logger.Logger, errs chan error) {
+	p := fmt.Sprintf(":%s", port)
+	logger.Info(fmt.Sprintf("LwM2M adapter HTTP service started, exposed port %s", port))
+	errs <- http.ListenAndServe(p, api.MakeHandler(svc))
+}
+
+func startLwM2MServer(server *api.Server, svc lwm2m.Service, port string, logger logger.Logger, errs chan error) {
+	logger.Info(fmt.Sprintf("LwM2M adapter service started, exposed port %s", port))
+	errs <- server.Serve(svc)
+}
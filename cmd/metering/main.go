@@ -0,0 +1,365 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	"github.com/jmoiron/sqlx"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/metering"
+	"github.com/mainflux/mainflux/metering/api"
+	"github.com/mainflux/mainflux/metering/nats"
+	"github.com/mainflux/mainflux/metering/postgres"
+	mfnats "github.com/mainflux/mainflux/nats"
+	readers "github.com/mainflux/mainflux/readers/postgres"
+	thingsapi "github.com/mainflux/mainflux/things/api/auth/grpc"
+	usersapi "github.com/mainflux/mainflux/users/api/grpc"
+	broker "github.com/nats-io/nats.go"
+	opentracing "github.com/opentracing/opentracing-go"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+const (
+	defLogLevel          = "error"
+	defHTTPPort          = "8198"
+	defDBHost            = "localhost"
+	defDBPort            = "5432"
+	defDBUser            = "mainflux"
+	defDBPass            = "mainflux"
+	defDBName            = "metering"
+	defDBSSLMode         = "disable"
+	defDBSSLCert         = ""
+	defDBSSLKey          = ""
+	defDBSSLRootCert     = ""
+	defStoreDBHost       = "localhost"
+	defStoreDBPort       = "5432"
+	defStoreDBUser       = "mainflux"
+	defStoreDBPass       = "mainflux"
+	defStoreDBName       = "messages"
+	defStoreDBSSLMode    = "disable"
+	defStoreDBSSLCert    = ""
+	defStoreDBSSLKey     = ""
+	defStoreDBSSLRoot    = ""
+	defUsersURL          = "localhost:8181"
+	defThingsURL         = "localhost:8183"
+	defTimeout           = "1" // in seconds
+	defClientTLS         = "false"
+	defCACerts           = ""
+	defDedupeWindow      = "24h"
+	defOwnerCacheTTL     = "1m"
+	defReconcilePeriod   = "10m"
+	defReconcileDelay    = "5m"
+	defNatsURL           = broker.DefaultURL
+	defNatsCreds         = ""
+	defNatsNKeySeed      = ""
+	defNatsCACerts       = ""
+	defNatsClientCert    = ""
+	defNatsClientKey     = ""
+	defNatsSubjectPrefix = ""
+
+	envLogLevel          = "MF_METERING_LOG_LEVEL"
+	envHTTPPort          = "MF_METERING_HTTP_PORT"
+	envDBHost            = "MF_METERING_DB_HOST"
+	envDBPort            = "MF_METERING_DB_PORT"
+	envDBUser            = "MF_METERING_DB_USER"
+	envDBPass            = "MF_METERING_DB_PASS"
+	envDBName            = "MF_METERING_DB"
+	envDBSSLMode         = "MF_METERING_DB_SSL_MODE"
+	envDBSSLCert         = "MF_METERING_DB_SSL_CERT"
+	envDBSSLKey          = "MF_METERING_DB_SSL_KEY"
+	envDBSSLRootCert     = "MF_METERING_DB_SSL_ROOT_CERT"
+	envStoreDBHost       = "MF_METERING_STORE_DB_HOST"
+	envStoreDBPort       = "MF_METERING_STORE_DB_PORT"
+	envStoreDBUser       = "MF_METERING_STORE_DB_USER"
+	envStoreDBPass       = "MF_METERING_STORE_DB_PASS"
+	envStoreDBName       = "MF_METERING_STORE_DB"
+	envStoreDBSSLMode    = "MF_METERING_STORE_DB_SSL_MODE"
+	envStoreDBSSLCert    = "MF_METERING_STORE_DB_SSL_CERT"
+	envStoreDBSSLKey     = "MF_METERING_STORE_DB_SSL_KEY"
+	envStoreDBSSLRoot    = "MF_METERING_STORE_DB_SSL_ROOT_CERT"
+	envUsersURL          = "MF_USERS_URL"
+	envThingsURL         = "MF_THINGS_URL"
+	envTimeout           = "MF_METERING_TIMEOUT"
+	envClientTLS         = "MF_METERING_CLIENT_TLS"
+	envCACerts           = "MF_METERING_CA_CERTS"
+	envDedupeWindow      = "MF_METERING_DEDUPE_WINDOW"
+	envOwnerCacheTTL     = "MF_METERING_OWNER_CACHE_TTL"
+	envReconcilePeriod   = "MF_METERING_RECONCILE_PERIOD"
+	envReconcileDelay    = "MF_METERING_RECONCILE_DELAY"
+	envNatsURL           = "MF_NATS_URL"
+	envNatsCreds         = "MF_NATS_CREDS"
+	envNatsNKeySeed      = "MF_NATS_NKEY_SEED"
+	envNatsCACerts       = "MF_NATS_CA_CERTS"
+	envNatsClientCert    = "MF_NATS_CLIENT_CERT"
+	envNatsClientKey     = "MF_NATS_CLIENT_KEY"
+	envNatsSubjectPrefix = "MF_NATS_SUBJECT_PREFIX"
+)
+
+type config struct {
+	logLevel        string
+	httpPort        string
+	dbConfig        postgres.Config
+	storeDBConfig   readers.Config
+	usersURL        string
+	thingsURL       string
+	timeout         time.Duration
+	clientTLS       bool
+	caCerts         string
+	dedupeWindow    time.Duration
+	ownerCacheTTL   time.Duration
+	reconcilePeriod time.Duration
+	reconcileDelay  time.Duration
+	natsConfig      mfnats.Config
+}
+
+func main() {
+	cfg := loadConfig()
+
+	logger, err := logger.New(os.Stdout, cfg.logLevel)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	db := connectToDB(cfg.dbConfig, logger)
+	defer db.Close()
+
+	storeDB := connectToStoreDB(cfg.storeDBConfig, logger)
+	defer storeDB.Close()
+
+	usersConn := connectToGRPC(cfg.usersURL, cfg, logger)
+	defer usersConn.Close()
+
+	thingsConn := connectToGRPC(cfg.thingsURL, cfg, logger)
+	defer thingsConn.Close()
+
+	nc, err := mfnats.Connect(cfg.natsConfig)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to NATS: %s", err))
+		os.Exit(1)
+	}
+	defer nc.Close()
+
+	users := usersapi.NewClient(opentracing.NoopTracer{}, usersConn, cfg.timeout)
+	things := thingsapi.NewClient(thingsConn, opentracing.NoopTracer{}, cfg.timeout)
+	svc := newService(users, things, db, storeDB, cfg, logger)
+
+	if err := nats.Start(nc, cfg.natsConfig.Prefix, svc, logger); err != nil {
+		logger.Error(fmt.Sprintf("Failed to subscribe to NATS: %s", err))
+		os.Exit(1)
+	}
+
+	go startReconciliation(svc, cfg.reconcilePeriod, cfg.reconcileDelay, logger)
+	go startForgetting(svc, cfg.dedupeWindow, logger)
+
+	errs := make(chan error, 2)
+
+	go startHTTPServer(svc, cfg.httpPort, logger, errs)
+
+	go func() {
+		c := make(chan os.Signal, 1)
+		signal.Notify(c, syscall.SIGINT)
+		errs <- fmt.Errorf("%s", <-c)
+	}()
+
+	err = <-errs
+	logger.Error(fmt.Sprintf("Metering service terminated: %s", err))
+}
+
+func loadConfig() config {
+	tls, err := strconv.ParseBool(mainflux.Env(envClientTLS, defClientTLS))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envClientTLS)
+	}
+
+	timeout, err := strconv.ParseInt(mainflux.Env(envTimeout, defTimeout), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envTimeout, err.Error())
+	}
+
+	dedupeWindow, err := time.ParseDuration(mainflux.Env(envDedupeWindow, defDedupeWindow))
+	if err != nil || dedupeWindow <= 0 {
+		log.Fatalf("Invalid value passed for %s\n", envDedupeWindow)
+	}
+
+	ownerCacheTTL, err := time.ParseDuration(mainflux.Env(envOwnerCacheTTL, defOwnerCacheTTL))
+	if err != nil || ownerCacheTTL < 0 {
+		log.Fatalf("Invalid value passed for %s\n", envOwnerCacheTTL)
+	}
+
+	reconcilePeriod, err := time.ParseDuration(mainflux.Env(envReconcilePeriod, defReconcilePeriod))
+	if err != nil || reconcilePeriod <= 0 {
+		log.Fatalf("Invalid value passed for %s\n", envReconcilePeriod)
+	}
+
+	reconcileDelay, err := time.ParseDuration(mainflux.Env(envReconcileDelay, defReconcileDelay))
+	if err != nil || reconcileDelay < 0 {
+		log.Fatalf("Invalid value passed for %s\n", envReconcileDelay)
+	}
+
+	dbConfig := postgres.Config{
+		Host:        mainflux.Env(envDBHost, defDBHost),
+		Port:        mainflux.Env(envDBPort, defDBPort),
+		User:        mainflux.Env(envDBUser, defDBUser),
+		Pass:        mainflux.Env(envDBPass, defDBPass),
+		Name:        mainflux.Env(envDBName, defDBName),
+		SSLMode:     mainflux.Env(envDBSSLMode, defDBSSLMode),
+		SSLCert:     mainflux.Env(envDBSSLCert, defDBSSLCert),
+		SSLKey:      mainflux.Env(envDBSSLKey, defDBSSLKey),
+		SSLRootCert: mainflux.Env(envDBSSLRootCert, defDBSSLRootCert),
+	}
+
+	storeDBConfig := readers.Config{
+		Host:        mainflux.Env(envStoreDBHost, defStoreDBHost),
+		Port:        mainflux.Env(envStoreDBPort, defStoreDBPort),
+		User:        mainflux.Env(envStoreDBUser, defStoreDBUser),
+		Pass:        mainflux.Env(envStoreDBPass, defStoreDBPass),
+		Name:        mainflux.Env(envStoreDBName, defStoreDBName),
+		SSLMode:     mainflux.Env(envStoreDBSSLMode, defStoreDBSSLMode),
+		SSLCert:     mainflux.Env(envStoreDBSSLCert, defStoreDBSSLCert),
+		SSLKey:      mainflux.Env(envStoreDBSSLKey, defStoreDBSSLKey),
+		SSLRootCert: mainflux.Env(envStoreDBSSLRoot, defStoreDBSSLRoot),
+	}
+
+	natsConfig := mfnats.Config{
+		URL:        mainflux.Env(envNatsURL, defNatsURL),
+		Creds:      mainflux.Env(envNatsCreds, defNatsCreds),
+		NKeySeed:   mainflux.Env(envNatsNKeySeed, defNatsNKeySeed),
+		CACerts:    mainflux.Env(envNatsCACerts, defNatsCACerts),
+		ClientCert: mainflux.Env(envNatsClientCert, defNatsClientCert),
+		ClientKey:  mainflux.Env(envNatsClientKey, defNatsClientKey),
+		Prefix:     mainflux.Env(envNatsSubjectPrefix, defNatsSubjectPrefix),
+	}
+
+	return config{
+		logLevel:        mainflux.Env(envLogLevel, defLogLevel),
+		httpPort:        mainflux.Env(envHTTPPort, defHTTPPort),
+		dbConfig:        dbConfig,
+		storeDBConfig:   storeDBConfig,
+		usersURL:        mainflux.Env(envUsersURL, defUsersURL),
+		thingsURL:       mainflux.Env(envThingsURL, defThingsURL),
+		timeout:         time.Duration(timeout) * time.Second,
+		clientTLS:       tls,
+		caCerts:         mainflux.Env(envCACerts, defCACerts),
+		dedupeWindow:    dedupeWindow,
+		ownerCacheTTL:   ownerCacheTTL,
+		reconcilePeriod: reconcilePeriod,
+		reconcileDelay:  reconcileDelay,
+		natsConfig:      natsConfig,
+	}
+}
+
+func connectToDB(dbConfig postgres.Config, logger logger.Logger) *sqlx.DB {
+	db, err := postgres.Connect(dbConfig)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to Postgres: %s", err))
+		os.Exit(1)
+	}
+	return db
+}
+
+func connectToStoreDB(dbConfig readers.Config, logger logger.Logger) *sqlx.DB {
+	db, err := readers.Connect(dbConfig)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to writer Postgres: %s", err))
+		os.Exit(1)
+	}
+	return db
+}
+
+func connectToGRPC(url string, cfg config, logger logger.Logger) *grpc.ClientConn {
+	var opts []grpc.DialOption
+	if cfg.clientTLS {
+		if cfg.caCerts != "" {
+			tpc, err := credentials.NewClientTLSFromFile(cfg.caCerts, "")
+			if err != nil {
+				logger.Error(fmt.Sprintf("Failed to create tls credentials: %s", err))
+				os.Exit(1)
+			}
+			opts = append(opts, grpc.WithTransportCredentials(tpc))
+		}
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+		logger.Info("gRPC communication is not encrypted")
+	}
+
+	conn, err := grpc.Dial(url, opts...)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to %s: %s", url, err))
+		os.Exit(1)
+	}
+
+	return conn
+}
+
+func newService(users mainflux.UsersServiceClient, things mainflux.ThingsServiceClient, db, storeDB *sqlx.DB, cfg config, logger logger.Logger) metering.Service {
+	repo := postgres.NewUsageRepository(db)
+	store := postgres.NewMessageStore(storeDB)
+
+	svc := metering.New(users, things, repo, store, cfg.dedupeWindow, cfg.ownerCacheTTL)
+	svc = api.LoggingMiddleware(svc, logger)
+	svc = api.MetricsMiddleware(
+		svc,
+		kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: "metering",
+			Subsystem: "api",
+			Name:      "request_count",
+			Help:      "Number of requests received.",
+		}, []string{"method"}),
+		kitprometheus.NewSummaryFrom(stdprometheus.SummaryOpts{
+			Namespace: "metering",
+			Subsystem: "api",
+			Name:      "request_latency_microseconds",
+			Help:      "Total duration of requests in microseconds.",
+		}, []string{"method"}),
+	)
+
+	return svc
+}
+
+// startReconciliation reconciles the hours that ended at least the delay ago,
+// so that the writer has the time to save their messages.
+func startReconciliation(svc metering.Service, period, delay time.Duration, logger logger.Logger) {
+	logger.Info(fmt.Sprintf("Reconciling usage every %s", period))
+	for {
+		usage, err := svc.Reconcile(context.Background(), time.Now().Add(-delay))
+		if err != nil {
+			logger.Error(fmt.Sprintf("Failed to reconcile usage: %s", err))
+		}
+		for _, u := range usage {
+			if u.Messages != u.Stored {
+				logger.Warn(fmt.Sprintf("Channel %s publisher %s hour %s: counted %d messages, writer stored %d", u.Channel, u.Publisher, u.Hour.Format(time.RFC3339), u.Messages, u.Stored))
+			}
+		}
+		time.Sleep(period)
+	}
+}
+
+func startForgetting(svc metering.Service, window time.Duration, logger logger.Logger) {
+	logger.Info(fmt.Sprintf("Forgetting message IDs older than %s", window))
+	for {
+		if err := svc.Forget(context.Background()); err != nil {
+			logger.Error(fmt.Sprintf("Failed to forget message IDs: %s", err))
+		}
+		time.Sleep(window / 10)
+	}
+}
+
+func startHTTPServer(svc metering.Service, port string, logger logger.Logger, errs chan error) {
+	p := fmt.Sprintf(":%s", port)
+	logger.Info(fmt.Sprintf("Metering service started, exposed port %s", port))
+	errs <- http.ListenAndServe(p, api.MakeHandler(svc))
+}
@@ -17,11 +17,14 @@ import (
 	"time"
 
 	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	"github.com/go-redis/redis"
 	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/logger"
 	"github.com/mainflux/mainflux/readers"
 	"github.com/mainflux/mainflux/readers/api"
 	"github.com/mainflux/mainflux/readers/mongodb"
+	subtopicsredis "github.com/mainflux/mainflux/readers/redis"
+	mfredis "github.com/mainflux/mainflux/redis"
 	thingsapi "github.com/mainflux/mainflux/things/api/auth/grpc"
 	opentracing "github.com/opentracing/opentracing-go"
 	stdprometheus "github.com/prometheus/client_golang/prometheus"
@@ -43,6 +46,9 @@ const (
 	defCACerts       = ""
 	defJaegerURL     = ""
 	defThingsTimeout = "1" // in seconds
+	defSubtopicsURL  = ""
+	defSubtopicsPass = ""
+	defSubtopicsDB   = "0"
 
 	envThingsURL     = "MF_THINGS_URL"
 	envLogLevel      = "MF_MONGO_READER_LOG_LEVEL"
@@ -54,6 +60,9 @@ const (
 	envCACerts       = "MF_MONGO_READER_CA_CERTS"
 	envJaegerURL     = "MF_JAEGER_URL"
 	envThingsTimeout = "MF_MONGO_READER_THINGS_TIMEOUT"
+	envSubtopicsURL  = "MF_MONGO_READER_SUBTOPICS_URL"
+	envSubtopicsPass = "MF_MONGO_READER_SUBTOPICS_PASS"
+	envSubtopicsDB   = "MF_MONGO_READER_SUBTOPICS_DB"
 )
 
 type config struct {
@@ -67,6 +76,9 @@ type config struct {
 	caCerts       string
 	jaegerURL     string
 	thingsTimeout time.Duration
+	subtopicsURL  string
+	subtopicsPass string
+	subtopicsDB   string
 }
 
 func main() {
@@ -88,6 +100,13 @@ func main() {
 
 	repo := newService(db, logger)
 
+	var subtopics readers.SubtopicRepository
+	if cfg.subtopicsURL != "" {
+		subtopicsClient := connectToRedis(cfg.subtopicsURL, cfg.subtopicsPass, cfg.subtopicsDB, logger)
+		defer subtopicsClient.Close()
+		subtopics = subtopicsredis.NewSubtopicRepository(subtopicsClient)
+	}
+
 	errs := make(chan error, 2)
 	go func() {
 		c := make(chan os.Signal)
@@ -95,7 +114,7 @@ func main() {
 		errs <- fmt.Errorf("%s", <-c)
 	}()
 
-	go startHTTPServer(repo, tc, cfg.port, logger, errs)
+	go startHTTPServer(repo, subtopics, tc, cfg.port, logger, errs)
 
 	err = <-errs
 	logger.Error(fmt.Sprintf("MongoDB reader service terminated: %s", err))
@@ -123,6 +142,9 @@ func loadConfigs() config {
 		caCerts:       mainflux.Env(envCACerts, defCACerts),
 		jaegerURL:     mainflux.Env(envJaegerURL, defJaegerURL),
 		thingsTimeout: time.Duration(timeout) * time.Second,
+		subtopicsURL:  mainflux.Env(envSubtopicsURL, defSubtopicsURL),
+		subtopicsPass: mainflux.Env(envSubtopicsPass, defSubtopicsPass),
+		subtopicsDB:   mainflux.Env(envSubtopicsDB, defSubtopicsDB),
 	}
 }
 
@@ -161,6 +183,22 @@ func initJaeger(svcName, url string, logger logger.Logger) (opentracing.Tracer,
 	return tracer, closer
 }
 
+func connectToRedis(redisURL, redisPass, redisDB string, logger logger.Logger) redis.UniversalClient {
+	db, err := strconv.Atoi(redisDB)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to subtopics store: %s", err))
+		os.Exit(1)
+	}
+
+	client, err := mfredis.Connect(redisURL, redisPass, db)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to subtopics store: %s", err))
+		os.Exit(1)
+	}
+
+	return client
+}
+
 func connectToThings(cfg config, logger logger.Logger) *grpc.ClientConn {
 	var opts []grpc.DialOption
 	if cfg.clientTLS {
@@ -207,8 +245,8 @@ func newService(db *mongo.Database, logger logger.Logger) readers.MessageReposit
 	return repo
 }
 
-func startHTTPServer(repo readers.MessageRepository, tc mainflux.ThingsServiceClient, port string, logger logger.Logger, errs chan error) {
+func startHTTPServer(repo readers.MessageRepository, subtopics readers.SubtopicRepository, tc mainflux.ThingsServiceClient, port string, logger logger.Logger, errs chan error) {
 	p := fmt.Sprintf(":%s", port)
 	logger.Info(fmt.Sprintf("Mongo reader service started, exposed port %s", port))
-	errs <- http.ListenAndServe(p, api.MakeHandler(repo, tc, "mongodb-reader"))
+	errs <- http.ListenAndServe(p, api.MakeHandler(repo, subtopics, tc, "mongodb-reader"))
 }
@@ -11,49 +11,131 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
+	"time"
 
 	"github.com/BurntSushi/toml"
 	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	"github.com/go-redis/redis"
 	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/logger"
+	mfnats "github.com/mainflux/mainflux/nats"
+	mfredis "github.com/mainflux/mainflux/redis"
+	thingsapi "github.com/mainflux/mainflux/things/api/auth/grpc"
+	"github.com/mainflux/mainflux/tracing"
 	"github.com/mainflux/mainflux/writers"
 	"github.com/mainflux/mainflux/writers/api"
+	"github.com/mainflux/mainflux/writers/deadletter"
+	"github.com/mainflux/mainflux/writers/enrich"
 	"github.com/mainflux/mainflux/writers/mongodb"
-	nats "github.com/nats-io/go-nats"
+	subtopicsredis "github.com/mainflux/mainflux/writers/redis"
+	nats "github.com/nats-io/nats.go"
+	opentracing "github.com/opentracing/opentracing-go"
 	stdprometheus "github.com/prometheus/client_golang/prometheus"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
 const (
 	svcName = "mongodb-writer"
 
-	defNatsURL     = nats.DefaultURL
-	defLogLevel    = "error"
-	defPort        = "8180"
-	defDBName      = "mainflux"
-	defDBHost      = "localhost"
-	defDBPort      = "27017"
-	defChanCfgPath = "/config/channels.toml"
-
-	envNatsURL     = "MF_NATS_URL"
-	envLogLevel    = "MF_MONGO_WRITER_LOG_LEVEL"
-	envPort        = "MF_MONGO_WRITER_PORT"
-	envDBName      = "MF_MONGO_WRITER_DB_NAME"
-	envDBHost      = "MF_MONGO_WRITER_DB_HOST"
-	envDBPort      = "MF_MONGO_WRITER_DB_PORT"
-	envChanCfgPath = "MF_MONGO_WRITER_CHANNELS_CONFIG"
+	defNatsURL           = nats.DefaultURL
+	defJaegerURL         = ""
+	defNatsCreds         = ""
+	defNatsNKeySeed      = ""
+	defNatsCACerts       = ""
+	defNatsClientCert    = ""
+	defNatsClientKey     = ""
+	defNatsSubjectPrefix = ""
+	defNatsJetStream     = "false"
+	defNatsStream        = "MAINFLUX_MESSAGES"
+	defNatsStreamMaxAge  = "24h"
+	defNatsAckWait       = "30s"
+	defNatsMaxDeliver    = "0"
+	defLogLevel          = "error"
+	defPort              = "8180"
+	defDBName            = "mainflux"
+	defDBHost            = "localhost"
+	defDBPort            = "27017"
+	defChanCfgPath       = "/config/channels.toml"
+	defThingsURL         = ""
+	defClientTLS         = "false"
+	defCACerts           = ""
+	defThingsTimeout     = "1" // in seconds
+	defRetentionRefresh  = "1m"
+	defReapPeriod        = "1h"
+	defEnrichURL         = ""
+	defEnrichTimeout     = "1s"
+	defEnrichBypass      = "true"
+	defDLQSubject        = ""
+	defDLQFile           = ""
+	defSubtopicsURL      = ""
+	defSubtopicsPass     = ""
+	defSubtopicsDB       = "0"
+
+	envNatsURL           = "MF_NATS_URL"
+	envJaegerURL         = "MF_JAEGER_URL"
+	envNatsCreds         = "MF_NATS_CREDS"
+	envNatsNKeySeed      = "MF_NATS_NKEY_SEED"
+	envNatsCACerts       = "MF_NATS_CA_CERTS"
+	envNatsClientCert    = "MF_NATS_CLIENT_CERT"
+	envNatsClientKey     = "MF_NATS_CLIENT_KEY"
+	envNatsSubjectPrefix = "MF_NATS_SUBJECT_PREFIX"
+	envNatsJetStream     = "MF_NATS_JETSTREAM"
+	envNatsStream        = "MF_NATS_JETSTREAM_STREAM"
+	envNatsStreamMaxAge  = "MF_NATS_JETSTREAM_MAX_AGE"
+	envNatsAckWait       = "MF_NATS_JETSTREAM_ACK_WAIT"
+	envNatsMaxDeliver    = "MF_NATS_JETSTREAM_MAX_DELIVER"
+	envLogLevel          = "MF_MONGO_WRITER_LOG_LEVEL"
+	envPort              = "MF_MONGO_WRITER_PORT"
+	envDBName            = "MF_MONGO_WRITER_DB_NAME"
+	envDBHost            = "MF_MONGO_WRITER_DB_HOST"
+	envDBPort            = "MF_MONGO_WRITER_DB_PORT"
+	envChanCfgPath       = "MF_MONGO_WRITER_CHANNELS_CONFIG"
+	envThingsURL         = "MF_THINGS_URL"
+	envClientTLS         = "MF_MONGO_WRITER_CLIENT_TLS"
+	envCACerts           = "MF_MONGO_WRITER_CA_CERTS"
+	envThingsTimeout     = "MF_MONGO_WRITER_THINGS_TIMEOUT"
+	envRetentionRefresh  = "MF_MONGO_WRITER_RETENTION_REFRESH"
+	envReapPeriod        = "MF_MONGO_WRITER_REAP_PERIOD"
+	envEnrichURL         = "MF_MONGO_WRITER_ENRICH_URL"
+	envEnrichTimeout     = "MF_MONGO_WRITER_ENRICH_TIMEOUT"
+	envEnrichBypass      = "MF_MONGO_WRITER_ENRICH_BYPASS"
+	envDLQSubject        = "MF_MONGO_WRITER_DLQ_SUBJECT"
+	envDLQFile           = "MF_MONGO_WRITER_DLQ_FILE"
+	envSubtopicsURL      = "MF_MONGO_WRITER_SUBTOPICS_URL"
+	envSubtopicsPass     = "MF_MONGO_WRITER_SUBTOPICS_PASS"
+	envSubtopicsDB       = "MF_MONGO_WRITER_SUBTOPICS_DB"
 )
 
 type config struct {
-	natsURL  string
-	logLevel string
-	port     string
-	dbName   string
-	dbHost   string
-	dbPort   string
-	channels map[string]bool
+	natsConfig       mfnats.Config
+	jaegerURL        string
+	jetStream        bool
+	jsConfig         writers.JetStreamConfig
+	logLevel         string
+	port             string
+	dbName           string
+	dbHost           string
+	dbPort           string
+	channels         map[string]bool
+	thingsURL        string
+	clientTLS        bool
+	caCerts          string
+	thingsTimeout    time.Duration
+	retentionRefresh time.Duration
+	reapPeriod       time.Duration
+	enrichURL        string
+	enrichTimeout    time.Duration
+	enrichBypass     bool
+	dlqSubject       string
+	dlqFile          string
+	subtopicsURL     string
+	subtopicsPass    string
+	subtopicsDB      string
 }
 
 func main() {
@@ -64,7 +146,7 @@ func main() {
 		log.Fatal(err)
 	}
 
-	nc, err := nats.Connect(cfg.natsURL)
+	nc, err := mfnats.Connect(cfg.natsConfig)
 	if err != nil {
 		logger.Error(fmt.Sprintf("Failed to connect to NATS: %s", err))
 		os.Exit(1)
@@ -84,7 +166,28 @@ func main() {
 	counter, latency := makeMetrics()
 	repo = api.LoggingMiddleware(repo, logger)
 	repo = api.MetricsMiddleware(repo, counter, latency)
-	if err := writers.Start(nc, repo, svcName, cfg.channels, logger); err != nil {
+	if cfg.subtopicsURL != "" {
+		subtopicsClient := connectToRedis(cfg.subtopicsURL, cfg.subtopicsPass, cfg.subtopicsDB, logger)
+		defer subtopicsClient.Close()
+		repo = writers.NewSubtopicTracker(repo, subtopicsredis.NewSubtopicRepository(subtopicsClient), logger)
+	}
+	if cfg.dlqSubject != "" || cfg.dlqFile != "" {
+		repo = writers.NewDeadLetterRepository(repo, newDeadLetters(cfg, nc), logger)
+	}
+	if cfg.enrichURL != "" {
+		repo = newEnrichingRepository(repo, cfg, logger)
+	}
+	var retainer writers.Retainer
+	if cfg.thingsURL != "" {
+		retention := mongodb.NewRetentionRepository(db)
+		retainer = newRetainer(cfg, retention, logger)
+		go startReaping(retention, cfg.reapPeriod, logger)
+	}
+
+	tracer, closer := tracing.Init(svcName, cfg.jaegerURL, logger)
+	defer closer.Close()
+
+	if err := startWriter(nc, repo, retainer, cfg, tracer, logger); err != nil {
 		logger.Error(fmt.Sprintf("Failed to start MongoDB writer: %s", err))
 		os.Exit(1)
 	}
@@ -103,15 +206,99 @@ func main() {
 }
 
 func loadConfigs() config {
+	tls, err := strconv.ParseBool(mainflux.Env(envClientTLS, defClientTLS))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envClientTLS)
+	}
+
+	timeout, err := strconv.ParseInt(mainflux.Env(envThingsTimeout, defThingsTimeout), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envThingsTimeout, err.Error())
+	}
+
+	retentionRefresh, err := time.ParseDuration(mainflux.Env(envRetentionRefresh, defRetentionRefresh))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envRetentionRefresh)
+	}
+
+	reapPeriod, err := time.ParseDuration(mainflux.Env(envReapPeriod, defReapPeriod))
+	if err != nil || reapPeriod <= 0 {
+		log.Fatalf("Invalid value passed for %s\n", envReapPeriod)
+	}
+
+	enrichTimeout, err := time.ParseDuration(mainflux.Env(envEnrichTimeout, defEnrichTimeout))
+	if err != nil || enrichTimeout <= 0 {
+		log.Fatalf("Invalid value passed for %s\n", envEnrichTimeout)
+	}
+
+	enrichBypass, err := strconv.ParseBool(mainflux.Env(envEnrichBypass, defEnrichBypass))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envEnrichBypass)
+	}
+
 	chanCfgPath := mainflux.Env(envChanCfgPath, defChanCfgPath)
+	jetStream, err := strconv.ParseBool(mainflux.Env(envNatsJetStream, defNatsJetStream))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envNatsJetStream)
+	}
+
+	streamMaxAge, err := time.ParseDuration(mainflux.Env(envNatsStreamMaxAge, defNatsStreamMaxAge))
+	if err != nil || streamMaxAge < 0 {
+		log.Fatalf("Invalid value passed for %s\n", envNatsStreamMaxAge)
+	}
+
+	ackWait, err := time.ParseDuration(mainflux.Env(envNatsAckWait, defNatsAckWait))
+	if err != nil || ackWait <= 0 {
+		log.Fatalf("Invalid value passed for %s\n", envNatsAckWait)
+	}
+
+	maxDeliver, err := strconv.Atoi(mainflux.Env(envNatsMaxDeliver, defNatsMaxDeliver))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envNatsMaxDeliver)
+	}
+
+	jsConfig := writers.JetStreamConfig{
+		Stream:     mainflux.Env(envNatsStream, defNatsStream),
+		MaxAge:     streamMaxAge,
+		AckWait:    ackWait,
+		MaxDeliver: maxDeliver,
+	}
+
+	natsConfig := mfnats.Config{
+		URL:        mainflux.Env(envNatsURL, defNatsURL),
+		Creds:      mainflux.Env(envNatsCreds, defNatsCreds),
+		NKeySeed:   mainflux.Env(envNatsNKeySeed, defNatsNKeySeed),
+		CACerts:    mainflux.Env(envNatsCACerts, defNatsCACerts),
+		ClientCert: mainflux.Env(envNatsClientCert, defNatsClientCert),
+		ClientKey:  mainflux.Env(envNatsClientKey, defNatsClientKey),
+		Prefix:     mainflux.Env(envNatsSubjectPrefix, defNatsSubjectPrefix),
+	}
+
 	return config{
-		natsURL:  mainflux.Env(envNatsURL, defNatsURL),
-		logLevel: mainflux.Env(envLogLevel, defLogLevel),
-		port:     mainflux.Env(envPort, defPort),
-		dbName:   mainflux.Env(envDBName, defDBName),
-		dbHost:   mainflux.Env(envDBHost, defDBHost),
-		dbPort:   mainflux.Env(envDBPort, defDBPort),
-		channels: loadChansConfig(chanCfgPath),
+		natsConfig:       natsConfig,
+		jaegerURL:        mainflux.Env(envJaegerURL, defJaegerURL),
+		jetStream:        jetStream,
+		jsConfig:         jsConfig,
+		logLevel:         mainflux.Env(envLogLevel, defLogLevel),
+		port:             mainflux.Env(envPort, defPort),
+		dbName:           mainflux.Env(envDBName, defDBName),
+		dbHost:           mainflux.Env(envDBHost, defDBHost),
+		dbPort:           mainflux.Env(envDBPort, defDBPort),
+		channels:         loadChansConfig(chanCfgPath),
+		thingsURL:        mainflux.Env(envThingsURL, defThingsURL),
+		clientTLS:        tls,
+		caCerts:          mainflux.Env(envCACerts, defCACerts),
+		thingsTimeout:    time.Duration(timeout) * time.Second,
+		retentionRefresh: retentionRefresh,
+		reapPeriod:       reapPeriod,
+		enrichURL:        mainflux.Env(envEnrichURL, defEnrichURL),
+		enrichTimeout:    enrichTimeout,
+		enrichBypass:     enrichBypass,
+		dlqSubject:       mainflux.Env(envDLQSubject, defDLQSubject),
+		dlqFile:          mainflux.Env(envDLQFile, defDLQFile),
+		subtopicsURL:     mainflux.Env(envSubtopicsURL, defSubtopicsURL),
+		subtopicsPass:    mainflux.Env(envSubtopicsPass, defSubtopicsPass),
+		subtopicsDB:      mainflux.Env(envSubtopicsDB, defSubtopicsDB),
 	}
 }
 
@@ -165,3 +352,92 @@ func startHTTPService(port string, logger logger.Logger, errs chan error) {
 	logger.Info(fmt.Sprintf("Mongodb writer service started, exposed port %s", p))
 	errs <- http.ListenAndServe(p, api.MakeHandler(svcName))
 }
+
+func newDeadLetters(cfg config, nc *nats.Conn) writers.DeadLetters {
+	if cfg.dlqSubject != "" {
+		return deadletter.NewNATSDeadLetters(nc, mfnats.Subject(cfg.natsConfig.Prefix, cfg.dlqSubject))
+	}
+
+	return deadletter.NewFileDeadLetters(cfg.dlqFile)
+}
+
+func newEnrichingRepository(repo writers.MessageRepository, cfg config, logger logger.Logger) writers.MessageRepository {
+	enricher, err := enrich.New(cfg.enrichURL, cfg.enrichTimeout)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to create message enricher: %s", err))
+		os.Exit(1)
+	}
+
+	return writers.NewEnrichingRepository(repo, enricher, cfg.enrichTimeout, cfg.enrichBypass, logger)
+}
+
+func newRetainer(cfg config, repo writers.RetentionRepository, logger logger.Logger) writers.Retainer {
+	conn := connectToThings(cfg, logger)
+	tc := thingsapi.NewClient(conn, opentracing.NoopTracer{}, cfg.thingsTimeout)
+	return writers.NewRetainer(tc, repo, cfg.retentionRefresh, logger)
+}
+
+func connectToRedis(redisURL, redisPass, redisDB string, logger logger.Logger) redis.UniversalClient {
+	db, err := strconv.Atoi(redisDB)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to subtopics store: %s", err))
+		os.Exit(1)
+	}
+
+	client, err := mfredis.Connect(redisURL, redisPass, db)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to subtopics store: %s", err))
+		os.Exit(1)
+	}
+
+	return client
+}
+
+func connectToThings(cfg config, logger logger.Logger) *grpc.ClientConn {
+	var opts []grpc.DialOption
+	if cfg.clientTLS {
+		if cfg.caCerts != "" {
+			tpc, err := credentials.NewClientTLSFromFile(cfg.caCerts, "")
+			if err != nil {
+				logger.Error(fmt.Sprintf("Failed to load certs: %s", err))
+				os.Exit(1)
+			}
+			opts = append(opts, grpc.WithTransportCredentials(tpc))
+		}
+	} else {
+		logger.Info("gRPC communication is not encrypted")
+		opts = append(opts, grpc.WithInsecure())
+	}
+
+	conn, err := grpc.Dial(cfg.thingsURL, opts...)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to things service: %s", err))
+		os.Exit(1)
+	}
+	return conn
+}
+
+func startReaping(repo writers.RetentionRepository, period time.Duration, logger logger.Logger) {
+	logger.Info(fmt.Sprintf("Reaping messages outside of channels retention every %s", period))
+	for {
+		if err := repo.Reap(time.Now()); err != nil {
+			logger.Error(fmt.Sprintf("Failed to reap messages: %s", err))
+		}
+		time.Sleep(period)
+	}
+}
+
+func startWriter(nc *nats.Conn, repo writers.MessageRepository, retainer writers.Retainer, cfg config, tracer opentracing.Tracer, logger logger.Logger) error {
+	if !cfg.jetStream {
+		return writers.Start(nc, cfg.natsConfig.Prefix, repo, retainer, svcName, cfg.channels, tracer, logger)
+	}
+
+	lag := kitprometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+		Namespace: "mongodb",
+		Subsystem: "message_writer",
+		Name:      "consumer_lag",
+		Help:      "Number of messages in the stream not delivered to the writer yet.",
+	}, []string{})
+
+	return writers.StartJetStream(nc, cfg.jsConfig, cfg.natsConfig.Prefix, repo, retainer, svcName, cfg.channels, lag, tracer, logger)
+}
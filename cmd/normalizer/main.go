@@ -9,49 +9,120 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
+	"time"
 
 	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/logger"
+	mfnats "github.com/mainflux/mainflux/nats"
 	"github.com/mainflux/mainflux/normalizer"
 	"github.com/mainflux/mainflux/normalizer/api"
 	"github.com/mainflux/mainflux/normalizer/nats"
-	broker "github.com/nats-io/go-nats"
+	"github.com/mainflux/mainflux/normalizer/things"
+	"github.com/mainflux/mainflux/normalizer/transformers"
+	thingsapi "github.com/mainflux/mainflux/things/api/auth/grpc"
+	"github.com/mainflux/mainflux/tracing"
+	broker "github.com/nats-io/nats.go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 
 	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
 	stdprometheus "github.com/prometheus/client_golang/prometheus"
 )
 
 const (
-	defNatsURL  string = broker.DefaultURL
-	defLogLevel string = "error"
-	defPort     string = "8180"
-	envNatsURL  string = "MF_NATS_URL"
-	envLogLevel string = "MF_NORMALIZER_LOG_LEVEL"
-	envPort     string = "MF_NORMALIZER_PORT"
+	defNatsURL           string = broker.DefaultURL
+	defNatsCreds         string = ""
+	defNatsNKeySeed      string = ""
+	defNatsCACerts       string = ""
+	defNatsClientCert    string = ""
+	defNatsClientKey     string = ""
+	defNatsSubjectPrefix string = ""
+	defLogLevel          string = "error"
+	defPort              string = "8180"
+	defMaxAge            string = "0"
+	defMaxSkew           string = "0"
+	defTimePolicy        string = normalizer.Reject
+	defClientTLS         string = "false"
+	defCACerts           string = ""
+	defThingsURL         string = "localhost:8181"
+	defJaegerURL         string = ""
+	defThingsTimeout     string = "1" // in seconds
+	defRefresh           string = "1m"
+	defSchemasDir        string = ""
+	defSchemaRefresh     string = "1m"
+	defDLQSubject        string = ""
+	envNatsURL           string = "MF_NATS_URL"
+	envNatsCreds         string = "MF_NATS_CREDS"
+	envNatsNKeySeed      string = "MF_NATS_NKEY_SEED"
+	envNatsCACerts       string = "MF_NATS_CA_CERTS"
+	envNatsClientCert    string = "MF_NATS_CLIENT_CERT"
+	envNatsClientKey     string = "MF_NATS_CLIENT_KEY"
+	envNatsSubjectPrefix string = "MF_NATS_SUBJECT_PREFIX"
+	envLogLevel          string = "MF_NORMALIZER_LOG_LEVEL"
+	envPort              string = "MF_NORMALIZER_PORT"
+	envMaxAge            string = "MF_NORMALIZER_MAX_AGE"
+	envMaxSkew           string = "MF_NORMALIZER_MAX_SKEW"
+	envTimePolicy        string = "MF_NORMALIZER_TIME_POLICY"
+	envClientTLS         string = "MF_NORMALIZER_CLIENT_TLS"
+	envCACerts           string = "MF_NORMALIZER_CA_CERTS"
+	envThingsURL         string = "MF_THINGS_URL"
+	envJaegerURL         string = "MF_JAEGER_URL"
+	envThingsTimeout     string = "MF_NORMALIZER_THINGS_TIMEOUT"
+	envRefresh           string = "MF_NORMALIZER_TRANSFORMER_REFRESH"
+	envSchemasDir        string = "MF_NORMALIZER_SCHEMAS_DIR"
+	envSchemaRefresh     string = "MF_NORMALIZER_SCHEMA_REFRESH"
+	envDLQSubject        string = "MF_NORMALIZER_DLQ_SUBJECT"
 )
 
 type config struct {
-	NatsURL  string
-	LogLevel string
-	Port     string
+	NatsConfig    mfnats.Config
+	LogLevel      string
+	Port          string
+	Bounds        normalizer.TimeBounds
+	ClientTLS     bool
+	CACerts       string
+	ThingsURL     string
+	JaegerURL     string
+	ThingsTimeout time.Duration
+	Refresh       time.Duration
+	SchemasDir    string
+	SchemaRefresh time.Duration
+	DLQSubject    string
 }
 
 func main() {
-	cfg := loadConfig()
+	cfg, err := loadConfig()
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	logger, err := logger.New(os.Stdout, cfg.LogLevel)
 	if err != nil {
-		log.Fatalf(err.Error())
+		log.Fatal(err)
 	}
-	nc, err := broker.Connect(cfg.NatsURL)
+	nc, err := mfnats.Connect(cfg.NatsConfig)
 	if err != nil {
 		logger.Error(fmt.Sprintf("Failed to connect to NATS: %s", err))
 		os.Exit(1)
 	}
 	defer nc.Close()
 
-	svc := normalizer.New()
+	conn := connectToThings(cfg, logger)
+	defer conn.Close()
+
+	tracer, closer := tracing.Init("normalizer", cfg.JaegerURL, logger)
+	defer closer.Close()
+
+	thingsTracer, thingsCloser := tracing.Init("things", cfg.JaegerURL, logger)
+	defer thingsCloser.Close()
+
+	tc := thingsapi.NewClient(conn, thingsTracer, cfg.ThingsTimeout)
+	channels := things.New(tc, cfg.Refresh, logger)
+	validator := things.NewValidator(tc, cfg.SchemaRefresh, logger)
+
+	svc := normalizer.New(cfg.Bounds, channels, newTransformers(cfg, logger), validator)
 	svc = api.LoggingMiddleware(svc, logger)
 	svc = api.MetricsMiddleware(
 		svc,
@@ -67,6 +138,18 @@ func main() {
 			Name:      "request_latency_microseconds",
 			Help:      "Total duration of requests in microseconds.",
 		}, []string{"method"}),
+		kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: "normalizer",
+			Subsystem: "api",
+			Name:      "skewed_messages_count",
+			Help:      "Number of messages with timestamps out of the configured bounds.",
+		}, []string{"protocol", "direction", "action"}),
+		kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: "normalizer",
+			Subsystem: "api",
+			Name:      "invalid_messages_count",
+			Help:      "Number of messages whose payloads don't satisfy the payload schema.",
+		}, []string{"protocol"}),
 	)
 
 	errs := make(chan error, 2)
@@ -78,21 +161,128 @@ func main() {
 	}()
 
 	go func() {
-		c := make(chan os.Signal)
+		c := make(chan os.Signal, 1)
 		signal.Notify(c, syscall.SIGINT)
 		errs <- fmt.Errorf("%s", <-c)
 	}()
 
-	nats.Subscribe(svc, nc, logger)
+	nats.Subscribe(svc, nc, cfg.NatsConfig.Prefix, cfg.DLQSubject, tracer, logger)
 
 	err = <-errs
 	logger.Error(fmt.Sprintf("Normalizer service terminated: %s", err))
 }
 
-func loadConfig() config {
+func newTransformers(cfg config, logger logger.Logger) map[string]normalizer.Transformer {
+	schemas := map[string][]byte{}
+	if cfg.SchemasDir != "" {
+		var err error
+		if schemas, err = transformers.ReadSchemas(cfg.SchemasDir); err != nil {
+			logger.Error(fmt.Sprintf("Failed to read Protobuf schemas: %s", err))
+			os.Exit(1)
+		}
+	}
+
+	pb, err := transformers.NewProtobuf(schemas)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to load Protobuf schemas: %s", err))
+		os.Exit(1)
+	}
+
+	return map[string]normalizer.Transformer{
+		normalizer.SenML:    transformers.NewSenML(),
+		normalizer.JSON:     transformers.NewJSON(),
+		normalizer.CBOR:     transformers.NewCBOR(),
+		normalizer.Protobuf: pb,
+	}
+}
+
+func loadConfig() (config, error) {
+	maxAge, err := time.ParseDuration(mainflux.Env(envMaxAge, defMaxAge))
+	if err != nil {
+		return config{}, fmt.Errorf("invalid %s value: %s", envMaxAge, err)
+	}
+
+	maxSkew, err := time.ParseDuration(mainflux.Env(envMaxSkew, defMaxSkew))
+	if err != nil {
+		return config{}, fmt.Errorf("invalid %s value: %s", envMaxSkew, err)
+	}
+
+	bounds := normalizer.TimeBounds{
+		MaxAge:  maxAge,
+		MaxSkew: maxSkew,
+		Policy:  mainflux.Env(envTimePolicy, defTimePolicy),
+	}
+	if err := bounds.Validate(); err != nil {
+		return config{}, fmt.Errorf("invalid time bounds: %s", err)
+	}
+
+	tls, err := strconv.ParseBool(mainflux.Env(envClientTLS, defClientTLS))
+	if err != nil {
+		return config{}, fmt.Errorf("invalid %s value: %s", envClientTLS, err)
+	}
+
+	timeout, err := strconv.ParseInt(mainflux.Env(envThingsTimeout, defThingsTimeout), 10, 64)
+	if err != nil {
+		return config{}, fmt.Errorf("invalid %s value: %s", envThingsTimeout, err)
+	}
+
+	refresh, err := time.ParseDuration(mainflux.Env(envRefresh, defRefresh))
+	if err != nil {
+		return config{}, fmt.Errorf("invalid %s value: %s", envRefresh, err)
+	}
+
+	schemaRefresh, err := time.ParseDuration(mainflux.Env(envSchemaRefresh, defSchemaRefresh))
+	if err != nil {
+		return config{}, fmt.Errorf("invalid %s value: %s", envSchemaRefresh, err)
+	}
+
+	natsConfig := mfnats.Config{
+		URL:        mainflux.Env(envNatsURL, defNatsURL),
+		Creds:      mainflux.Env(envNatsCreds, defNatsCreds),
+		NKeySeed:   mainflux.Env(envNatsNKeySeed, defNatsNKeySeed),
+		CACerts:    mainflux.Env(envNatsCACerts, defNatsCACerts),
+		ClientCert: mainflux.Env(envNatsClientCert, defNatsClientCert),
+		ClientKey:  mainflux.Env(envNatsClientKey, defNatsClientKey),
+		Prefix:     mainflux.Env(envNatsSubjectPrefix, defNatsSubjectPrefix),
+	}
+
 	return config{
-		NatsURL:  mainflux.Env(envNatsURL, defNatsURL),
-		LogLevel: mainflux.Env(envLogLevel, defLogLevel),
-		Port:     mainflux.Env(envPort, defPort),
+		NatsConfig:    natsConfig,
+		LogLevel:      mainflux.Env(envLogLevel, defLogLevel),
+		Port:          mainflux.Env(envPort, defPort),
+		Bounds:        bounds,
+		ClientTLS:     tls,
+		CACerts:       mainflux.Env(envCACerts, defCACerts),
+		ThingsURL:     mainflux.Env(envThingsURL, defThingsURL),
+		JaegerURL:     mainflux.Env(envJaegerURL, defJaegerURL),
+		ThingsTimeout: time.Duration(timeout) * time.Second,
+		Refresh:       refresh,
+		SchemasDir:    mainflux.Env(envSchemasDir, defSchemasDir),
+		SchemaRefresh: schemaRefresh,
+		DLQSubject:    mainflux.Env(envDLQSubject, defDLQSubject),
+	}, nil
+}
+
+func connectToThings(cfg config, logger logger.Logger) *grpc.ClientConn {
+	var opts []grpc.DialOption
+	if cfg.ClientTLS {
+		if cfg.CACerts != "" {
+			tpc, err := credentials.NewClientTLSFromFile(cfg.CACerts, "")
+			if err != nil {
+				logger.Error(fmt.Sprintf("Failed to load certs: %s", err))
+				os.Exit(1)
+			}
+			opts = append(opts, grpc.WithTransportCredentials(tpc))
+		}
+	} else {
+		logger.Info("gRPC communication is not encrypted")
+		opts = append(opts, grpc.WithInsecure())
+	}
+
+	conn, err := grpc.Dial(cfg.ThingsURL, opts...)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to things service: %s", err))
+		os.Exit(1)
 	}
+	return conn
 }
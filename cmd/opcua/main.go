@@ -0,0 +1,307 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	"github.com/go-redis/redis"
+	"github.com/jmoiron/sqlx"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/http/nats"
+	"github.com/mainflux/mainflux/logger"
+	mfnats "github.com/mainflux/mainflux/nats"
+	"github.com/mainflux/mainflux/opcua"
+	"github.com/mainflux/mainflux/opcua/api"
+	"github.com/mainflux/mainflux/opcua/gopcua"
+	"github.com/mainflux/mainflux/opcua/postgres"
+	rediscache "github.com/mainflux/mainflux/opcua/redis"
+	mfredis "github.com/mainflux/mainflux/redis"
+	thingsapi "github.com/mainflux/mainflux/things/api/auth/grpc"
+	"github.com/mainflux/mainflux/things/uuid"
+	usersapi "github.com/mainflux/mainflux/users/api/grpc"
+	broker "github.com/nats-io/nats.go"
+	opentracing "github.com/opentracing/opentracing-go"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+const (
+	defLogLevel          = "error"
+	defHTTPPort          = "8188"
+	defDBHost            = "localhost"
+	defDBPort            = "5432"
+	defDBUser            = "mainflux"
+	defDBPass            = "mainflux"
+	defDBName            = "opcua"
+	defDBSSLMode         = "disable"
+	defDBSSLCert         = ""
+	defDBSSLKey          = ""
+	defDBSSLRootCert     = ""
+	defCacheURL          = "localhost:6379"
+	defCachePass         = ""
+	defCacheDB           = "0"
+	defClientTLS         = "false"
+	defCACerts           = ""
+	defUsersURL          = "localhost:8181"
+	defThingsURL         = "localhost:8183"
+	defTimeout           = "1" // in seconds
+	defPolicy            = "None"
+	defMode              = "None"
+	defCertFile          = ""
+	defKeyFile           = ""
+	defNatsURL           = broker.DefaultURL
+	defNatsCreds         = ""
+	defNatsNKeySeed      = ""
+	defNatsCACerts       = ""
+	defNatsClientCert    = ""
+	defNatsClientKey     = ""
+	defNatsSubjectPrefix = ""
+
+	envLogLevel          = "MF_OPCUA_ADAPTER_LOG_LEVEL"
+	envHTTPPort          = "MF_OPCUA_ADAPTER_HTTP_PORT"
+	envDBHost            = "MF_OPCUA_ADAPTER_DB_HOST"
+	envDBPort            = "MF_OPCUA_ADAPTER_DB_PORT"
+	envDBUser            = "MF_OPCUA_ADAPTER_DB_USER"
+	envDBPass            = "MF_OPCUA_ADAPTER_DB_PASS"
+	envDBName            = "MF_OPCUA_ADAPTER_DB"
+	envDBSSLMode         = "MF_OPCUA_ADAPTER_DB_SSL_MODE"
+	envDBSSLCert         = "MF_OPCUA_ADAPTER_DB_SSL_CERT"
+	envDBSSLKey          = "MF_OPCUA_ADAPTER_DB_SSL_KEY"
+	envDBSSLRootCert     = "MF_OPCUA_ADAPTER_DB_SSL_ROOT_CERT"
+	envCacheURL          = "MF_OPCUA_ADAPTER_CACHE_URL"
+	envCachePass         = "MF_OPCUA_ADAPTER_CACHE_PASS"
+	envCacheDB           = "MF_OPCUA_ADAPTER_CACHE_DB"
+	envClientTLS         = "MF_OPCUA_ADAPTER_CLIENT_TLS"
+	envCACerts           = "MF_OPCUA_ADAPTER_CA_CERTS"
+	envUsersURL          = "MF_USERS_URL"
+	envThingsURL         = "MF_THINGS_URL"
+	envTimeout           = "MF_OPCUA_ADAPTER_TIMEOUT"
+	envPolicy            = "MF_OPCUA_ADAPTER_POLICY"
+	envMode              = "MF_OPCUA_ADAPTER_MODE"
+	envCertFile          = "MF_OPCUA_ADAPTER_CERT_FILE"
+	envKeyFile           = "MF_OPCUA_ADAPTER_KEY_FILE"
+	envNatsURL           = "MF_NATS_URL"
+	envNatsCreds         = "MF_NATS_CREDS"
+	envNatsNKeySeed      = "MF_NATS_NKEY_SEED"
+	envNatsCACerts       = "MF_NATS_CA_CERTS"
+	envNatsClientCert    = "MF_NATS_CLIENT_CERT"
+	envNatsClientKey     = "MF_NATS_CLIENT_KEY"
+	envNatsSubjectPrefix = "MF_NATS_SUBJECT_PREFIX"
+)
+
+type config struct {
+	logLevel   string
+	httpPort   string
+	dbConfig   postgres.Config
+	cacheURL   string
+	cachePass  string
+	cacheDB    string
+	clientTLS  bool
+	caCerts    string
+	usersURL   string
+	thingsURL  string
+	timeout    time.Duration
+	opcConfig  gopcua.Config
+	natsConfig mfnats.Config
+}
+
+func main() {
+	cfg := loadConfig()
+
+	logger, err := logger.New(os.Stdout, cfg.logLevel)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	db := connectToDB(cfg.dbConfig, logger)
+	defer db.Close()
+
+	cacheClient := connectToRedis(cfg.cacheURL, cfg.cachePass, cfg.cacheDB, logger)
+
+	usersConn := connectToGRPC(cfg.usersURL, cfg, logger)
+	defer usersConn.Close()
+
+	thingsConn := connectToGRPC(cfg.thingsURL, cfg, logger)
+	defer thingsConn.Close()
+
+	nc, err := mfnats.Connect(cfg.natsConfig)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to NATS: %s", err))
+		os.Exit(1)
+	}
+	defer nc.Close()
+
+	users := usersapi.NewClient(opentracing.NoopTracer{}, usersConn, cfg.timeout)
+	things := thingsapi.NewClient(thingsConn, opentracing.NoopTracer{}, cfg.timeout)
+	svc := newService(users, things, db, cacheClient, nats.NewMessagePublisher(nc, cfg.natsConfig.Prefix), cfg, logger)
+
+	// Nodes of the unreachable servers are monitored once the connections
+	// are re-established, so the adapter starts regardless.
+	if err := svc.Restore(context.Background()); err != nil {
+		logger.Warn(fmt.Sprintf("Failed to restore monitored nodes: %s", err))
+	}
+
+	errs := make(chan error, 2)
+
+	go startHTTPServer(svc, cfg.httpPort, logger, errs)
+
+	go func() {
+		c := make(chan os.Signal, 1)
+		signal.Notify(c, syscall.SIGINT)
+		errs <- fmt.Errorf("%s", <-c)
+	}()
+
+	err = <-errs
+	logger.Error(fmt.Sprintf("OPC-UA adapter terminated: %s", err))
+}
+
+func loadConfig() config {
+	tls, err := strconv.ParseBool(mainflux.Env(envClientTLS, defClientTLS))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envClientTLS)
+	}
+
+	timeout, err := strconv.ParseInt(mainflux.Env(envTimeout, defTimeout), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envTimeout, err.Error())
+	}
+
+	dbConfig := postgres.Config{
+		Host:        mainflux.Env(envDBHost, defDBHost),
+		Port:        mainflux.Env(envDBPort, defDBPort),
+		User:        mainflux.Env(envDBUser, defDBUser),
+		Pass:        mainflux.Env(envDBPass, defDBPass),
+		Name:        mainflux.Env(envDBName, defDBName),
+		SSLMode:     mainflux.Env(envDBSSLMode, defDBSSLMode),
+		SSLCert:     mainflux.Env(envDBSSLCert, defDBSSLCert),
+		SSLKey:      mainflux.Env(envDBSSLKey, defDBSSLKey),
+		SSLRootCert: mainflux.Env(envDBSSLRootCert, defDBSSLRootCert),
+	}
+
+	opcConfig := gopcua.Config{
+		Policy:   mainflux.Env(envPolicy, defPolicy),
+		Mode:     mainflux.Env(envMode, defMode),
+		CertFile: mainflux.Env(envCertFile, defCertFile),
+		KeyFile:  mainflux.Env(envKeyFile, defKeyFile),
+	}
+
+	natsConfig := mfnats.Config{
+		URL:        mainflux.Env(envNatsURL, defNatsURL),
+		Creds:      mainflux.Env(envNatsCreds, defNatsCreds),
+		NKeySeed:   mainflux.Env(envNatsNKeySeed, defNatsNKeySeed),
+		CACerts:    mainflux.Env(envNatsCACerts, defNatsCACerts),
+		ClientCert: mainflux.Env(envNatsClientCert, defNatsClientCert),
+		ClientKey:  mainflux.Env(envNatsClientKey, defNatsClientKey),
+		Prefix:     mainflux.Env(envNatsSubjectPrefix, defNatsSubjectPrefix),
+	}
+
+	return config{
+		logLevel:   mainflux.Env(envLogLevel, defLogLevel),
+		httpPort:   mainflux.Env(envHTTPPort, defHTTPPort),
+		dbConfig:   dbConfig,
+		cacheURL:   mainflux.Env(envCacheURL, defCacheURL),
+		cachePass:  mainflux.Env(envCachePass, defCachePass),
+		cacheDB:    mainflux.Env(envCacheDB, defCacheDB),
+		clientTLS:  tls,
+		caCerts:    mainflux.Env(envCACerts, defCACerts),
+		usersURL:   mainflux.Env(envUsersURL, defUsersURL),
+		thingsURL:  mainflux.Env(envThingsURL, defThingsURL),
+		timeout:    time.Duration(timeout) * time.Second,
+		opcConfig:  opcConfig,
+		natsConfig: natsConfig,
+	}
+}
+
+func connectToDB(dbConfig postgres.Config, logger logger.Logger) *sqlx.DB {
+	db, err := postgres.Connect(dbConfig)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to Postgres: %s", err))
+		os.Exit(1)
+	}
+	return db
+}
+
+func connectToRedis(cacheURL, cachePass, cacheDB string, logger logger.Logger) redis.UniversalClient {
+	db, err := strconv.Atoi(cacheDB)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to cache: %s", err))
+		os.Exit(1)
+	}
+
+	client, err := mfredis.Connect(cacheURL, cachePass, db)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to cache: %s", err))
+		os.Exit(1)
+	}
+
+	return client
+}
+
+func connectToGRPC(url string, cfg config, logger logger.Logger) *grpc.ClientConn {
+	var opts []grpc.DialOption
+	if cfg.clientTLS {
+		if cfg.caCerts != "" {
+			tpc, err := credentials.NewClientTLSFromFile(cfg.caCerts, "")
+			if err != nil {
+				logger.Error(fmt.Sprintf("Failed to create tls credentials: %s", err))
+				os.Exit(1)
+			}
+			opts = append(opts, grpc.WithTransportCredentials(tpc))
+		}
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+		logger.Info("gRPC communication is not encrypted")
+	}
+
+	conn, err := grpc.Dial(url, opts...)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to %s: %s", url, err))
+		os.Exit(1)
+	}
+
+	return conn
+}
+
+func newService(users mainflux.UsersServiceClient, things mainflux.ThingsServiceClient, db *sqlx.DB, cacheClient redis.UniversalClient, pub mainflux.MessagePublisher, cfg config, logger logger.Logger) opcua.Service {
+	repo := postgres.New(db)
+	cache := rediscache.NewRouteCache(cacheClient)
+	client := gopcua.New(cfg.opcConfig, logger)
+
+	svc := opcua.New(users, things, repo, cache, client, client, pub, uuid.New())
+	svc = api.LoggingMiddleware(svc, logger)
+	svc = api.MetricsMiddleware(
+		svc,
+		kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: "opcua_adapter",
+			Subsystem: "api",
+			Name:      "request_count",
+			Help:      "Number of requests received.",
+		}, []string{"method"}),
+		kitprometheus.NewSummaryFrom(stdprometheus.SummaryOpts{
+			Namespace: "opcua_adapter",
+			Subsystem: "api",
+			Name:      "request_latency_microseconds",
+			Help:      "Total duration of requests in microseconds.",
+		}, []string{"method"}),
+	)
+
+	return svc
+}
+
+func startHTTPServer(svc opcua.Service, port string, logger logger.Logger, errs chan error) {
+	p := fmt.Sprintf(":%s", port)
+	logger.Info(fmt.Sprintf("OPC-UA adapter service started, exposed port %s", port))
+	errs <- http.ListenAndServe(p, api.MakeHandler(svc))
+}
@@ -0,0 +1,316 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	"github.com/jmoiron/sqlx"
+	"github.com/mainflux/mainflux"
+	adapter "github.com/mainflux/mainflux/http/nats"
+	"github.com/mainflux/mainflux/logger"
+	mfnats "github.com/mainflux/mainflux/nats"
+	"github.com/mainflux/mainflux/ota"
+	"github.com/mainflux/mainflux/ota/api"
+	"github.com/mainflux/mainflux/ota/nats"
+	"github.com/mainflux/mainflux/ota/postgres"
+	"github.com/mainflux/mainflux/ota/things"
+	mfsdk "github.com/mainflux/mainflux/sdk/go"
+	"github.com/mainflux/mainflux/things/uuid"
+	usersapi "github.com/mainflux/mainflux/users/api/grpc"
+	"github.com/mainflux/mainflux/writers/s3"
+	broker "github.com/nats-io/nats.go"
+	opentracing "github.com/opentracing/opentracing-go"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+const (
+	defLogLevel          = "error"
+	defHTTPPort          = "8211"
+	defNatsURL           = broker.DefaultURL
+	defNatsCreds         = ""
+	defNatsNKeySeed      = ""
+	defNatsCACerts       = ""
+	defNatsClientCert    = ""
+	defNatsClientKey     = ""
+	defNatsSubjectPrefix = ""
+	defDBHost            = "localhost"
+	defDBPort            = "5432"
+	defDBUser            = "mainflux"
+	defDBPass            = "mainflux"
+	defDBName            = "ota"
+	defDBSSLMode         = "disable"
+	defDBSSLCert         = ""
+	defDBSSLKey          = ""
+	defDBSSLRootCert     = ""
+	defUsersURL          = "localhost:8181"
+	defUsersTimeout      = "1" // in seconds
+	defClientTLS         = "false"
+	defCACerts           = ""
+	defBaseURL           = "http://localhost"
+	defThingsPrefix      = ""
+	defS3Endpoint        = "http://minio:9000"
+	defS3Region          = "us-east-1"
+	defS3Bucket          = "firmware"
+	defS3AccessKey       = ""
+	defS3SecretKey       = ""
+	defS3Timeout         = "5m"
+	defURLTTL            = "24h"
+	defMaxSize           = "67108864" // 64MB
+
+	envLogLevel          = "MF_OTA_LOG_LEVEL"
+	envHTTPPort          = "MF_OTA_HTTP_PORT"
+	envNatsURL           = "MF_NATS_URL"
+	envNatsCreds         = "MF_NATS_CREDS"
+	envNatsNKeySeed      = "MF_NATS_NKEY_SEED"
+	envNatsCACerts       = "MF_NATS_CA_CERTS"
+	envNatsClientCert    = "MF_NATS_CLIENT_CERT"
+	envNatsClientKey     = "MF_NATS_CLIENT_KEY"
+	envNatsSubjectPrefix = "MF_NATS_SUBJECT_PREFIX"
+	envDBHost            = "MF_OTA_DB_HOST"
+	envDBPort            = "MF_OTA_DB_PORT"
+	envDBUser            = "MF_OTA_DB_USER"
+	envDBPass            = "MF_OTA_DB_PASS"
+	envDBName            = "MF_OTA_DB"
+	envDBSSLMode         = "MF_OTA_DB_SSL_MODE"
+	envDBSSLCert         = "MF_OTA_DB_SSL_CERT"
+	envDBSSLKey          = "MF_OTA_DB_SSL_KEY"
+	envDBSSLRootCert     = "MF_OTA_DB_SSL_ROOT_CERT"
+	envUsersURL          = "MF_USERS_URL"
+	envUsersTimeout      = "MF_OTA_USERS_TIMEOUT"
+	envClientTLS         = "MF_OTA_CLIENT_TLS"
+	envCACerts           = "MF_OTA_CA_CERTS"
+	envBaseURL           = "MF_SDK_BASE_URL"
+	envThingsPrefix      = "MF_SDK_THINGS_PREFIX"
+	envS3Endpoint        = "MF_OTA_S3_ENDPOINT"
+	envS3Region          = "MF_OTA_S3_REGION"
+	envS3Bucket          = "MF_OTA_S3_BUCKET"
+	envS3AccessKey       = "MF_OTA_S3_ACCESS_KEY"
+	envS3SecretKey       = "MF_OTA_S3_SECRET_KEY"
+	envS3Timeout         = "MF_OTA_S3_TIMEOUT"
+	envURLTTL            = "MF_OTA_URL_TTL"
+	envMaxSize           = "MF_OTA_MAX_SIZE"
+)
+
+type config struct {
+	logLevel     string
+	httpPort     string
+	natsConfig   mfnats.Config
+	dbConfig     postgres.Config
+	usersURL     string
+	usersTimeout time.Duration
+	clientTLS    bool
+	caCerts      string
+	baseURL      string
+	thingsPrefix string
+	s3Config     s3.Config
+	s3Timeout    time.Duration
+	urlTTL       time.Duration
+	maxSize      int64
+}
+
+func main() {
+	cfg := loadConfig()
+
+	logger, err := logger.New(os.Stdout, cfg.logLevel)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	nc := connectToNATS(cfg.natsConfig, logger)
+	defer nc.Close()
+
+	db := connectToDB(cfg.dbConfig, logger)
+	defer db.Close()
+
+	conn := connectToUsers(cfg, logger)
+	defer conn.Close()
+
+	users := usersapi.NewClient(opentracing.NoopTracer{}, conn, cfg.usersTimeout)
+	svc := newService(users, db, nc, cfg, logger)
+
+	if err := nats.Subscribe(svc, nc, cfg.natsConfig.Prefix, logger); err != nil {
+		logger.Error(fmt.Sprintf("Failed to subscribe to NATS: %s", err))
+		os.Exit(1)
+	}
+
+	errs := make(chan error, 2)
+
+	go startHTTPServer(svc, cfg.httpPort, cfg.maxSize, logger, errs)
+
+	go func() {
+		c := make(chan os.Signal, 1)
+		signal.Notify(c, syscall.SIGINT)
+		errs <- fmt.Errorf("%s", <-c)
+	}()
+
+	err = <-errs
+	logger.Error(fmt.Sprintf("OTA service terminated: %s", err))
+}
+
+func loadConfig() config {
+	tls, err := strconv.ParseBool(mainflux.Env(envClientTLS, defClientTLS))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envClientTLS)
+	}
+
+	timeout, err := strconv.ParseInt(mainflux.Env(envUsersTimeout, defUsersTimeout), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envUsersTimeout, err.Error())
+	}
+
+	s3Timeout, err := time.ParseDuration(mainflux.Env(envS3Timeout, defS3Timeout))
+	if err != nil || s3Timeout <= 0 {
+		log.Fatalf("Invalid value passed for %s\n", envS3Timeout)
+	}
+
+	urlTTL, err := time.ParseDuration(mainflux.Env(envURLTTL, defURLTTL))
+	if err != nil || urlTTL < time.Second || urlTTL > 7*24*time.Hour {
+		log.Fatalf("Invalid value passed for %s\n", envURLTTL)
+	}
+
+	maxSize, err := strconv.ParseInt(mainflux.Env(envMaxSize, defMaxSize), 10, 64)
+	if err != nil || maxSize <= 0 {
+		log.Fatalf("Invalid value passed for %s\n", envMaxSize)
+	}
+
+	natsConfig := mfnats.Config{
+		URL:        mainflux.Env(envNatsURL, defNatsURL),
+		Creds:      mainflux.Env(envNatsCreds, defNatsCreds),
+		NKeySeed:   mainflux.Env(envNatsNKeySeed, defNatsNKeySeed),
+		CACerts:    mainflux.Env(envNatsCACerts, defNatsCACerts),
+		ClientCert: mainflux.Env(envNatsClientCert, defNatsClientCert),
+		ClientKey:  mainflux.Env(envNatsClientKey, defNatsClientKey),
+		Prefix:     mainflux.Env(envNatsSubjectPrefix, defNatsSubjectPrefix),
+	}
+
+	dbConfig := postgres.Config{
+		Host:        mainflux.Env(envDBHost, defDBHost),
+		Port:        mainflux.Env(envDBPort, defDBPort),
+		User:        mainflux.Env(envDBUser, defDBUser),
+		Pass:        mainflux.Env(envDBPass, defDBPass),
+		Name:        mainflux.Env(envDBName, defDBName),
+		SSLMode:     mainflux.Env(envDBSSLMode, defDBSSLMode),
+		SSLCert:     mainflux.Env(envDBSSLCert, defDBSSLCert),
+		SSLKey:      mainflux.Env(envDBSSLKey, defDBSSLKey),
+		SSLRootCert: mainflux.Env(envDBSSLRootCert, defDBSSLRootCert),
+	}
+
+	s3Config := s3.Config{
+		Endpoint:  mainflux.Env(envS3Endpoint, defS3Endpoint),
+		Region:    mainflux.Env(envS3Region, defS3Region),
+		Bucket:    mainflux.Env(envS3Bucket, defS3Bucket),
+		AccessKey: mainflux.Env(envS3AccessKey, defS3AccessKey),
+		SecretKey: mainflux.Env(envS3SecretKey, defS3SecretKey),
+	}
+
+	return config{
+		logLevel:     mainflux.Env(envLogLevel, defLogLevel),
+		httpPort:     mainflux.Env(envHTTPPort, defHTTPPort),
+		natsConfig:   natsConfig,
+		dbConfig:     dbConfig,
+		usersURL:     mainflux.Env(envUsersURL, defUsersURL),
+		usersTimeout: time.Duration(timeout) * time.Second,
+		clientTLS:    tls,
+		caCerts:      mainflux.Env(envCACerts, defCACerts),
+		baseURL:      mainflux.Env(envBaseURL, defBaseURL),
+		thingsPrefix: mainflux.Env(envThingsPrefix, defThingsPrefix),
+		s3Config:     s3Config,
+		s3Timeout:    s3Timeout,
+		urlTTL:       urlTTL,
+		maxSize:      maxSize,
+	}
+}
+
+func connectToNATS(cfg mfnats.Config, logger logger.Logger) *broker.Conn {
+	nc, err := mfnats.Connect(cfg)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to NATS: %s", err))
+		os.Exit(1)
+	}
+
+	return nc
+}
+
+func connectToDB(dbConfig postgres.Config, logger logger.Logger) *sqlx.DB {
+	db, err := postgres.Connect(dbConfig)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to Postgres: %s", err))
+		os.Exit(1)
+	}
+	return db
+}
+
+func connectToUsers(cfg config, logger logger.Logger) *grpc.ClientConn {
+	var opts []grpc.DialOption
+	if cfg.clientTLS {
+		if cfg.caCerts != "" {
+			tpc, err := credentials.NewClientTLSFromFile(cfg.caCerts, "")
+			if err != nil {
+				logger.Error(fmt.Sprintf("Failed to create tls credentials: %s", err))
+				os.Exit(1)
+			}
+			opts = append(opts, grpc.WithTransportCredentials(tpc))
+		}
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+		logger.Info("gRPC communication is not encrypted")
+	}
+
+	conn, err := grpc.Dial(cfg.usersURL, opts...)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to users service: %s", err))
+		os.Exit(1)
+	}
+
+	return conn
+}
+
+func newService(users mainflux.UsersServiceClient, db *sqlx.DB, nc *broker.Conn, cfg config, logger logger.Logger) ota.Service {
+	sdk := mfsdk.NewSDK(mfsdk.Config{
+		BaseURL:      cfg.baseURL,
+		ThingsPrefix: cfg.thingsPrefix,
+	})
+
+	firmware := postgres.NewFirmwareRepository(db)
+	campaigns := postgres.NewCampaignRepository(db)
+	storage := s3.NewClient(cfg.s3Config, &http.Client{Timeout: cfg.s3Timeout})
+	publisher := adapter.NewMessagePublisher(nc, cfg.natsConfig.Prefix)
+
+	svc := ota.New(users, things.New(sdk), firmware, campaigns, storage, publisher, uuid.New(), cfg.urlTTL)
+	svc = api.LoggingMiddleware(svc, logger)
+	svc = api.MetricsMiddleware(
+		svc,
+		kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: "ota",
+			Subsystem: "api",
+			Name:      "request_count",
+			Help:      "Number of requests received.",
+		}, []string{"method"}),
+		kitprometheus.NewSummaryFrom(stdprometheus.SummaryOpts{
+			Namespace: "ota",
+			Subsystem: "api",
+			Name:      "request_latency_microseconds",
+			Help:      "Total duration of requests in microseconds.",
+		}, []string{"method"}),
+	)
+
+	return svc
+}
+
+func startHTTPServer(svc ota.Service, port string, maxSize int64, logger logger.Logger, errs chan error) {
+	p := fmt.Sprintf(":%s", port)
+	logger.Info(fmt.Sprintf("OTA service started, exposed port %s", port))
+	errs <- http.ListenAndServe(p, api.MakeHandler(svc, maxSize))
+}
@@ -10,59 +10,144 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
+	"time"
 
 	"github.com/BurntSushi/toml"
 	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
 	"github.com/jmoiron/sqlx"
 	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/logger"
+	mfnats "github.com/mainflux/mainflux/nats"
+	thingsapi "github.com/mainflux/mainflux/things/api/auth/grpc"
+	"github.com/mainflux/mainflux/tracing"
 	"github.com/mainflux/mainflux/writers"
 	"github.com/mainflux/mainflux/writers/api"
+	"github.com/mainflux/mainflux/writers/deadletter"
+	"github.com/mainflux/mainflux/writers/enrich"
 	"github.com/mainflux/mainflux/writers/postgres"
-	nats "github.com/nats-io/go-nats"
+	nats "github.com/nats-io/nats.go"
+	opentracing "github.com/opentracing/opentracing-go"
 	stdprometheus "github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
 const (
 	svcName = "postgres-writer"
 	sep     = ","
 
-	defNatsURL       = nats.DefaultURL
-	defLogLevel      = "error"
-	defPort          = "9104"
-	defDBHost        = "postgres"
-	defDBPort        = "5432"
-	defDBUser        = "mainflux"
-	defDBPass        = "mainflux"
-	defDBName        = "messages"
-	defDBSSLMode     = "disable"
-	defDBSSLCert     = ""
-	defDBSSLKey      = ""
-	defDBSSLRootCert = ""
-	defChanCfgPath   = "/config/channels.toml"
-
-	envNatsURL       = "MF_NATS_URL"
-	envLogLevel      = "MF_POSTGRES_WRITER_LOG_LEVEL"
-	envPort          = "MF_POSTGRES_WRITER_PORT"
-	envDBHost        = "MF_POSTGRES_WRITER_DB_HOST"
-	envDBPort        = "MF_POSTGRES_WRITER_DB_PORT"
-	envDBUser        = "MF_POSTGRES_WRITER_DB_USER"
-	envDBPass        = "MF_POSTGRES_WRITER_DB_PASS"
-	envDBName        = "MF_POSTGRES_WRITER_DB_NAME"
-	envDBSSLMode     = "MF_POSTGRES_WRITER_DB_SSL_MODE"
-	envDBSSLCert     = "MF_POSTGRES_WRITER_DB_SSL_CERT"
-	envDBSSLKey      = "MF_POSTGRES_WRITER_DB_SSL_KEY"
-	envDBSSLRootCert = "MF_POSTGRES_WRITER_DB_SSL_ROOT_CERT"
-	envChanCfgPath   = "MF_POSTGRES_WRITER_CHANNELS_CONFIG"
+	defNatsURL           = nats.DefaultURL
+	defJaegerURL         = ""
+	defNatsCreds         = ""
+	defNatsNKeySeed      = ""
+	defNatsCACerts       = ""
+	defNatsClientCert    = ""
+	defNatsClientKey     = ""
+	defNatsSubjectPrefix = ""
+	defNatsJetStream     = "false"
+	defNatsStream        = "MAINFLUX_MESSAGES"
+	defNatsStreamMaxAge  = "24h"
+	defNatsAckWait       = "30s"
+	defNatsMaxDeliver    = "0"
+	defLogLevel          = "error"
+	defPort              = "9104"
+	defDBHost            = "postgres"
+	defDBPort            = "5432"
+	defDBUser            = "mainflux"
+	defDBPass            = "mainflux"
+	defDBName            = "messages"
+	defDBSSLMode         = "disable"
+	defDBSSLCert         = ""
+	defDBSSLKey          = ""
+	defDBSSLRootCert     = ""
+	defChanCfgPath       = "/config/channels.toml"
+	defRollupAge         = "0"
+	defRollupPeriod      = "1h"
+	defArchiveDir        = "/archive"
+	defThingsURL         = ""
+	defClientTLS         = "false"
+	defCACerts           = ""
+	defThingsTimeout     = "1" // in seconds
+	defRetentionRefresh  = "1m"
+	defReapPeriod        = "1h"
+	defBatchSize         = "1"
+	defBatchInterval     = "1s"
+	defEnrichURL         = ""
+	defEnrichTimeout     = "1s"
+	defEnrichBypass      = "true"
+	defDLQSubject        = ""
+	defDLQFile           = ""
+
+	envNatsURL           = "MF_NATS_URL"
+	envJaegerURL         = "MF_JAEGER_URL"
+	envNatsCreds         = "MF_NATS_CREDS"
+	envNatsNKeySeed      = "MF_NATS_NKEY_SEED"
+	envNatsCACerts       = "MF_NATS_CA_CERTS"
+	envNatsClientCert    = "MF_NATS_CLIENT_CERT"
+	envNatsClientKey     = "MF_NATS_CLIENT_KEY"
+	envNatsSubjectPrefix = "MF_NATS_SUBJECT_PREFIX"
+	envNatsJetStream     = "MF_NATS_JETSTREAM"
+	envNatsStream        = "MF_NATS_JETSTREAM_STREAM"
+	envNatsStreamMaxAge  = "MF_NATS_JETSTREAM_MAX_AGE"
+	envNatsAckWait       = "MF_NATS_JETSTREAM_ACK_WAIT"
+	envNatsMaxDeliver    = "MF_NATS_JETSTREAM_MAX_DELIVER"
+	envLogLevel          = "MF_POSTGRES_WRITER_LOG_LEVEL"
+	envPort              = "MF_POSTGRES_WRITER_PORT"
+	envDBHost            = "MF_POSTGRES_WRITER_DB_HOST"
+	envDBPort            = "MF_POSTGRES_WRITER_DB_PORT"
+	envDBUser            = "MF_POSTGRES_WRITER_DB_USER"
+	envDBPass            = "MF_POSTGRES_WRITER_DB_PASS"
+	envDBName            = "MF_POSTGRES_WRITER_DB_NAME"
+	envDBSSLMode         = "MF_POSTGRES_WRITER_DB_SSL_MODE"
+	envDBSSLCert         = "MF_POSTGRES_WRITER_DB_SSL_CERT"
+	envDBSSLKey          = "MF_POSTGRES_WRITER_DB_SSL_KEY"
+	envDBSSLRootCert     = "MF_POSTGRES_WRITER_DB_SSL_ROOT_CERT"
+	envChanCfgPath       = "MF_POSTGRES_WRITER_CHANNELS_CONFIG"
+	envRollupAge         = "MF_POSTGRES_WRITER_ROLLUP_AGE"
+	envRollupPeriod      = "MF_POSTGRES_WRITER_ROLLUP_PERIOD"
+	envArchiveDir        = "MF_POSTGRES_WRITER_ARCHIVE_DIR"
+	envThingsURL         = "MF_THINGS_URL"
+	envClientTLS         = "MF_POSTGRES_WRITER_CLIENT_TLS"
+	envCACerts           = "MF_POSTGRES_WRITER_CA_CERTS"
+	envThingsTimeout     = "MF_POSTGRES_WRITER_THINGS_TIMEOUT"
+	envRetentionRefresh  = "MF_POSTGRES_WRITER_RETENTION_REFRESH"
+	envReapPeriod        = "MF_POSTGRES_WRITER_REAP_PERIOD"
+	envBatchSize         = "MF_POSTGRES_WRITER_BATCH_SIZE"
+	envBatchInterval     = "MF_POSTGRES_WRITER_BATCH_INTERVAL"
+	envEnrichURL         = "MF_POSTGRES_WRITER_ENRICH_URL"
+	envEnrichTimeout     = "MF_POSTGRES_WRITER_ENRICH_TIMEOUT"
+	envEnrichBypass      = "MF_POSTGRES_WRITER_ENRICH_BYPASS"
+	envDLQSubject        = "MF_POSTGRES_WRITER_DLQ_SUBJECT"
+	envDLQFile           = "MF_POSTGRES_WRITER_DLQ_FILE"
 )
 
 type config struct {
-	natsURL  string
-	logLevel string
-	port     string
-	dbConfig postgres.Config
-	channels map[string]bool
+	natsConfig       mfnats.Config
+	jaegerURL        string
+	jetStream        bool
+	jsConfig         writers.JetStreamConfig
+	logLevel         string
+	port             string
+	dbConfig         postgres.Config
+	channels         map[string]bool
+	rollupAge        time.Duration
+	rollupPeriod     time.Duration
+	archiveDir       string
+	thingsURL        string
+	clientTLS        bool
+	caCerts          string
+	thingsTimeout    time.Duration
+	retentionRefresh time.Duration
+	reapPeriod       time.Duration
+	batchSize        int
+	batchInterval    time.Duration
+	enrichURL        string
+	enrichTimeout    time.Duration
+	enrichBypass     bool
+	dlqSubject       string
+	dlqFile          string
 }
 
 func main() {
@@ -73,19 +158,42 @@ func main() {
 		log.Fatalf(err.Error())
 	}
 
-	nc := connectToNATS(cfg.natsURL, logger)
+	nc := connectToNATS(cfg.natsConfig, logger)
 	defer nc.Close()
 
 	db := connectToDB(cfg.dbConfig, logger)
 	defer db.Close()
 
 	repo := newService(db, logger)
-	if err = writers.Start(nc, repo, svcName, cfg.channels, logger); err != nil {
+	repo = writers.NewSubtopicTracker(repo, postgres.NewSubtopicRepository(db), logger)
+	if cfg.dlqSubject != "" || cfg.dlqFile != "" {
+		repo = writers.NewDeadLetterRepository(repo, newDeadLetters(cfg, nc), logger)
+	}
+	repo = writers.NewBatcher(repo, cfg.batchSize, cfg.batchInterval, logger)
+	if cfg.enrichURL != "" {
+		repo = newEnrichingRepository(repo, cfg, logger)
+	}
+	var retainer writers.Retainer
+	if cfg.thingsURL != "" {
+		retention := postgres.NewRetentionRepository(db)
+		retainer = newRetainer(cfg, retention, logger)
+		go startReaping(retention, cfg.reapPeriod, logger)
+	}
+
+	tracer, closer := tracing.Init(svcName, cfg.jaegerURL, logger)
+	defer closer.Close()
+
+	if err = startWriter(nc, repo, retainer, cfg, tracer, logger); err != nil {
 		logger.Error(fmt.Sprintf("Failed to create Postgres writer: %s", err))
 	}
 
 	errs := make(chan error, 2)
 
+	if cfg.rollupAge > 0 {
+		compactor := postgres.NewCompactor(db, postgres.NewFileArchive(cfg.archiveDir))
+		go startCompaction(compactor, cfg.rollupAge, cfg.rollupPeriod, logger)
+	}
+
 	go startHTTPServer(cfg.port, errs, logger)
 
 	go func() {
@@ -112,12 +220,118 @@ func loadConfig() config {
 		SSLRootCert: mainflux.Env(envDBSSLRootCert, defDBSSLRootCert),
 	}
 
+	rollupAge, err := time.ParseDuration(mainflux.Env(envRollupAge, defRollupAge))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envRollupAge)
+	}
+
+	rollupPeriod, err := time.ParseDuration(mainflux.Env(envRollupPeriod, defRollupPeriod))
+	if err != nil || rollupPeriod <= 0 {
+		log.Fatalf("Invalid value passed for %s\n", envRollupPeriod)
+	}
+
+	tls, err := strconv.ParseBool(mainflux.Env(envClientTLS, defClientTLS))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envClientTLS)
+	}
+
+	timeout, err := strconv.ParseInt(mainflux.Env(envThingsTimeout, defThingsTimeout), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envThingsTimeout, err.Error())
+	}
+
+	retentionRefresh, err := time.ParseDuration(mainflux.Env(envRetentionRefresh, defRetentionRefresh))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envRetentionRefresh)
+	}
+
+	reapPeriod, err := time.ParseDuration(mainflux.Env(envReapPeriod, defReapPeriod))
+	if err != nil || reapPeriod <= 0 {
+		log.Fatalf("Invalid value passed for %s\n", envReapPeriod)
+	}
+
+	batchSize, err := strconv.Atoi(mainflux.Env(envBatchSize, defBatchSize))
+	if err != nil || batchSize <= 0 {
+		log.Fatalf("Invalid value passed for %s\n", envBatchSize)
+	}
+
+	batchInterval, err := time.ParseDuration(mainflux.Env(envBatchInterval, defBatchInterval))
+	if err != nil || batchInterval <= 0 {
+		log.Fatalf("Invalid value passed for %s\n", envBatchInterval)
+	}
+
+	enrichTimeout, err := time.ParseDuration(mainflux.Env(envEnrichTimeout, defEnrichTimeout))
+	if err != nil || enrichTimeout <= 0 {
+		log.Fatalf("Invalid value passed for %s\n", envEnrichTimeout)
+	}
+
+	enrichBypass, err := strconv.ParseBool(mainflux.Env(envEnrichBypass, defEnrichBypass))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envEnrichBypass)
+	}
+
+	jetStream, err := strconv.ParseBool(mainflux.Env(envNatsJetStream, defNatsJetStream))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envNatsJetStream)
+	}
+
+	streamMaxAge, err := time.ParseDuration(mainflux.Env(envNatsStreamMaxAge, defNatsStreamMaxAge))
+	if err != nil || streamMaxAge < 0 {
+		log.Fatalf("Invalid value passed for %s\n", envNatsStreamMaxAge)
+	}
+
+	ackWait, err := time.ParseDuration(mainflux.Env(envNatsAckWait, defNatsAckWait))
+	if err != nil || ackWait <= 0 {
+		log.Fatalf("Invalid value passed for %s\n", envNatsAckWait)
+	}
+
+	maxDeliver, err := strconv.Atoi(mainflux.Env(envNatsMaxDeliver, defNatsMaxDeliver))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envNatsMaxDeliver)
+	}
+
+	jsConfig := writers.JetStreamConfig{
+		Stream:     mainflux.Env(envNatsStream, defNatsStream),
+		MaxAge:     streamMaxAge,
+		AckWait:    ackWait,
+		MaxDeliver: maxDeliver,
+	}
+
+	natsConfig := mfnats.Config{
+		URL:        mainflux.Env(envNatsURL, defNatsURL),
+		Creds:      mainflux.Env(envNatsCreds, defNatsCreds),
+		NKeySeed:   mainflux.Env(envNatsNKeySeed, defNatsNKeySeed),
+		CACerts:    mainflux.Env(envNatsCACerts, defNatsCACerts),
+		ClientCert: mainflux.Env(envNatsClientCert, defNatsClientCert),
+		ClientKey:  mainflux.Env(envNatsClientKey, defNatsClientKey),
+		Prefix:     mainflux.Env(envNatsSubjectPrefix, defNatsSubjectPrefix),
+	}
+
 	return config{
-		natsURL:  mainflux.Env(envNatsURL, defNatsURL),
-		logLevel: mainflux.Env(envLogLevel, defLogLevel),
-		port:     mainflux.Env(envPort, defPort),
-		dbConfig: dbConfig,
-		channels: loadChansConfig(chanCfgPath),
+		natsConfig:       natsConfig,
+		jaegerURL:        mainflux.Env(envJaegerURL, defJaegerURL),
+		jetStream:        jetStream,
+		jsConfig:         jsConfig,
+		logLevel:         mainflux.Env(envLogLevel, defLogLevel),
+		port:             mainflux.Env(envPort, defPort),
+		dbConfig:         dbConfig,
+		channels:         loadChansConfig(chanCfgPath),
+		rollupAge:        rollupAge,
+		rollupPeriod:     rollupPeriod,
+		archiveDir:       mainflux.Env(envArchiveDir, defArchiveDir),
+		thingsURL:        mainflux.Env(envThingsURL, defThingsURL),
+		clientTLS:        tls,
+		caCerts:          mainflux.Env(envCACerts, defCACerts),
+		thingsTimeout:    time.Duration(timeout) * time.Second,
+		retentionRefresh: retentionRefresh,
+		reapPeriod:       reapPeriod,
+		batchSize:        batchSize,
+		batchInterval:    batchInterval,
+		enrichURL:        mainflux.Env(envEnrichURL, defEnrichURL),
+		enrichTimeout:    enrichTimeout,
+		enrichBypass:     enrichBypass,
+		dlqSubject:       mainflux.Env(envDLQSubject, defDLQSubject),
+		dlqFile:          mainflux.Env(envDLQFile, defDLQFile),
 	}
 }
 
@@ -148,8 +362,8 @@ func loadChansConfig(chanConfigPath string) map[string]bool {
 	return chans
 }
 
-func connectToNATS(url string, logger logger.Logger) *nats.Conn {
-	nc, err := nats.Connect(url)
+func connectToNATS(cfg mfnats.Config, logger logger.Logger) *nats.Conn {
+	nc, err := mfnats.Connect(cfg)
 	if err != nil {
 		logger.Error(fmt.Sprintf("Failed to connect to NATS: %s", err))
 		os.Exit(1)
@@ -189,8 +403,95 @@ func newService(db *sqlx.DB, logger logger.Logger) writers.MessageRepository {
 	return svc
 }
 
+func startCompaction(compactor postgres.Compactor, age, period time.Duration, logger logger.Logger) {
+	logger.Info(fmt.Sprintf("Compacting messages older than %s every %s", age, period))
+	for {
+		n, err := compactor.Compact(time.Now().Add(-age))
+		if err != nil {
+			logger.Error(fmt.Sprintf("Failed to compact messages: %s", err))
+		}
+		if n > 0 {
+			logger.Info(fmt.Sprintf("Compacted %d messages", n))
+		}
+		time.Sleep(period)
+	}
+}
+
 func startHTTPServer(port string, errs chan error, logger logger.Logger) {
 	p := fmt.Sprintf(":%s", port)
 	logger.Info(fmt.Sprintf("Postgres writer service started, exposed port %s", port))
 	errs <- http.ListenAndServe(p, api.MakeHandler(svcName))
 }
+
+func newDeadLetters(cfg config, nc *nats.Conn) writers.DeadLetters {
+	if cfg.dlqSubject != "" {
+		return deadletter.NewNATSDeadLetters(nc, mfnats.Subject(cfg.natsConfig.Prefix, cfg.dlqSubject))
+	}
+
+	return deadletter.NewFileDeadLetters(cfg.dlqFile)
+}
+
+func newEnrichingRepository(repo writers.MessageRepository, cfg config, logger logger.Logger) writers.MessageRepository {
+	enricher, err := enrich.New(cfg.enrichURL, cfg.enrichTimeout)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to create message enricher: %s", err))
+		os.Exit(1)
+	}
+
+	return writers.NewEnrichingRepository(repo, enricher, cfg.enrichTimeout, cfg.enrichBypass, logger)
+}
+
+func newRetainer(cfg config, repo writers.RetentionRepository, logger logger.Logger) writers.Retainer {
+	conn := connectToThings(cfg, logger)
+	tc := thingsapi.NewClient(conn, opentracing.NoopTracer{}, cfg.thingsTimeout)
+	return writers.NewRetainer(tc, repo, cfg.retentionRefresh, logger)
+}
+
+func connectToThings(cfg config, logger logger.Logger) *grpc.ClientConn {
+	var opts []grpc.DialOption
+	if cfg.clientTLS {
+		if cfg.caCerts != "" {
+			tpc, err := credentials.NewClientTLSFromFile(cfg.caCerts, "")
+			if err != nil {
+				logger.Error(fmt.Sprintf("Failed to load certs: %s", err))
+				os.Exit(1)
+			}
+			opts = append(opts, grpc.WithTransportCredentials(tpc))
+		}
+	} else {
+		logger.Info("gRPC communication is not encrypted")
+		opts = append(opts, grpc.WithInsecure())
+	}
+
+	conn, err := grpc.Dial(cfg.thingsURL, opts...)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to things service: %s", err))
+		os.Exit(1)
+	}
+	return conn
+}
+
+func startReaping(repo writers.RetentionRepository, period time.Duration, logger logger.Logger) {
+	logger.Info(fmt.Sprintf("Reaping messages outside of channels retention every %s", period))
+	for {
+		if err := repo.Reap(time.Now()); err != nil {
+			logger.Error(fmt.Sprintf("Failed to reap messages: %s", err))
+		}
+		time.Sleep(period)
+	}
+}
+
+func startWriter(nc *nats.Conn, repo writers.MessageRepository, retainer writers.Retainer, cfg config, tracer opentracing.Tracer, logger logger.Logger) error {
+	if !cfg.jetStream {
+		return writers.Start(nc, cfg.natsConfig.Prefix, repo, retainer, svcName, cfg.channels, tracer, logger)
+	}
+
+	lag := kitprometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+		Namespace: "postgres",
+		Subsystem: "message_writer",
+		Name:      "consumer_lag",
+		Help:      "Number of messages in the stream not delivered to the writer yet.",
+	}, []string{})
+
+	return writers.StartJetStream(nc, cfg.jsConfig, cfg.natsConfig.Prefix, repo, retainer, svcName, cfg.channels, lag, tracer, logger)
+}
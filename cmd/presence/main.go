@@ -0,0 +1,173 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+
+	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	r "github.com/go-redis/redis"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/presence"
+	"github.com/mainflux/mainflux/presence/api"
+	"github.com/mainflux/mainflux/presence/redis"
+	"github.com/mainflux/mainflux/presence/redis/consumer"
+	"github.com/mainflux/mainflux/presence/things"
+	mfredis "github.com/mainflux/mainflux/redis"
+	mfsdk "github.com/mainflux/mainflux/sdk/go"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	defLogLevel     = "error"
+	defHTTPPort     = "8200"
+	defBaseURL      = "http://localhost"
+	defThingsPrefix = ""
+	defCacheURL     = "localhost:6379"
+	defCachePass    = ""
+	defCacheDB      = "0"
+	defESURL        = "localhost:6379"
+	defESPass       = ""
+	defESDB         = "0"
+	defInstanceName = "presence"
+
+	envLogLevel     = "MF_PRESENCE_LOG_LEVEL"
+	envHTTPPort     = "MF_PRESENCE_HTTP_PORT"
+	envBaseURL      = "MF_SDK_BASE_URL"
+	envThingsPrefix = "MF_SDK_THINGS_PREFIX"
+	envCacheURL     = "MF_PRESENCE_CACHE_URL"
+	envCachePass    = "MF_PRESENCE_CACHE_PASS"
+	envCacheDB      = "MF_PRESENCE_CACHE_DB"
+	envESURL        = "MF_PRESENCE_ES_URL"
+	envESPass       = "MF_PRESENCE_ES_PASS"
+	envESDB         = "MF_PRESENCE_ES_DB"
+	envInstanceName = "MF_PRESENCE_INSTANCE_NAME"
+)
+
+type config struct {
+	logLevel     string
+	httpPort     string
+	baseURL      string
+	thingsPrefix string
+	cacheURL     string
+	cachePass    string
+	cacheDB      string
+	esURL        string
+	esPass       string
+	esDB         string
+	instanceName string
+}
+
+func main() {
+	cfg := loadConfig()
+
+	logger, err := logger.New(os.Stdout, cfg.logLevel)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	cacheClient := connectToRedis(cfg.cacheURL, cfg.cachePass, cfg.cacheDB, logger)
+	defer cacheClient.Close()
+
+	esClient := connectToRedis(cfg.esURL, cfg.esPass, cfg.esDB, logger)
+	defer esClient.Close()
+
+	svc := newService(cacheClient, cfg, logger)
+
+	errs := make(chan error, 2)
+
+	go startHTTPServer(svc, cfg.httpPort, logger, errs)
+	go subscribeToAdaptersES(svc, esClient, cfg.instanceName, logger)
+
+	go func() {
+		c := make(chan os.Signal, 1)
+		signal.Notify(c, syscall.SIGINT)
+		errs <- fmt.Errorf("%s", <-c)
+	}()
+
+	err = <-errs
+	logger.Error(fmt.Sprintf("Presence service terminated: %s", err))
+}
+
+func loadConfig() config {
+	return config{
+		logLevel:     mainflux.Env(envLogLevel, defLogLevel),
+		httpPort:     mainflux.Env(envHTTPPort, defHTTPPort),
+		baseURL:      mainflux.Env(envBaseURL, defBaseURL),
+		thingsPrefix: mainflux.Env(envThingsPrefix, defThingsPrefix),
+		cacheURL:     mainflux.Env(envCacheURL, defCacheURL),
+		cachePass:    mainflux.Env(envCachePass, defCachePass),
+		cacheDB:      mainflux.Env(envCacheDB, defCacheDB),
+		esURL:        mainflux.Env(envESURL, defESURL),
+		esPass:       mainflux.Env(envESPass, defESPass),
+		esDB:         mainflux.Env(envESDB, defESDB),
+		instanceName: mainflux.Env(envInstanceName, defInstanceName),
+	}
+}
+
+func connectToRedis(redisURL, redisPass, redisDB string, logger logger.Logger) r.UniversalClient {
+	db, err := strconv.Atoi(redisDB)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to redis: %s", err))
+		os.Exit(1)
+	}
+
+	client, err := mfredis.Connect(redisURL, redisPass, db)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to redis: %s", err))
+		os.Exit(1)
+	}
+
+	return client
+}
+
+func newService(cacheClient r.UniversalClient, cfg config, logger logger.Logger) presence.Service {
+	sdk := mfsdk.NewSDK(mfsdk.Config{
+		BaseURL:      cfg.baseURL,
+		ThingsPrefix: cfg.thingsPrefix,
+	})
+
+	repo := redis.NewRepository(cacheClient)
+
+	svc := presence.New(things.New(sdk), repo)
+	svc = api.LoggingMiddleware(svc, logger)
+	svc = api.MetricsMiddleware(
+		svc,
+		kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: "presence",
+			Subsystem: "api",
+			Name:      "request_count",
+			Help:      "Number of requests received.",
+		}, []string{"method"}),
+		kitprometheus.NewSummaryFrom(stdprometheus.SummaryOpts{
+			Namespace: "presence",
+			Subsystem: "api",
+			Name:      "request_latency_microseconds",
+			Help:      "Total duration of requests in microseconds.",
+		}, []string{"method"}),
+	)
+
+	return svc
+}
+
+func startHTTPServer(svc presence.Service, port string, logger logger.Logger, errs chan error) {
+	p := fmt.Sprintf(":%s", port)
+	logger.Info(fmt.Sprintf("Presence service started, exposed port %s", port))
+	errs <- http.ListenAndServe(p, api.MakeHandler(svc))
+}
+
+func subscribeToAdaptersES(svc presence.Service, client r.UniversalClient, name string, logger logger.Logger) {
+	eventStore := consumer.NewEventStore(svc, client, name, logger)
+	logger.Info("Subscribed to Redis Event Store")
+	if err := eventStore.Subscribe(); err != nil {
+		logger.Warn(fmt.Sprintf("Presence service failed to subscribe to event sourcing: %s", err))
+	}
+}
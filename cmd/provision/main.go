@@ -0,0 +1,261 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	"github.com/jmoiron/sqlx"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/provision"
+	"github.com/mainflux/mainflux/provision/api"
+	"github.com/mainflux/mainflux/provision/bootstrap"
+	"github.com/mainflux/mainflux/provision/certs"
+	"github.com/mainflux/mainflux/provision/postgres"
+	"github.com/mainflux/mainflux/provision/things"
+	mfsdk "github.com/mainflux/mainflux/sdk/go"
+	usersapi "github.com/mainflux/mainflux/users/api/grpc"
+	opentracing "github.com/opentracing/opentracing-go"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+const (
+	sep = ","
+
+	defLogLevel      = "error"
+	defHTTPPort      = "8213"
+	defDBHost        = "localhost"
+	defDBPort        = "5432"
+	defDBUser        = "mainflux"
+	defDBPass        = "mainflux"
+	defDBName        = "provision"
+	defDBSSLMode     = "disable"
+	defDBSSLCert     = ""
+	defDBSSLKey      = ""
+	defDBSSLRootCert = ""
+	defUsersURL      = "localhost:8181"
+	defUsersTimeout  = "1" // in seconds
+	defClientTLS     = "false"
+	defCACerts       = ""
+	defBaseURL       = "http://localhost"
+	defThingsPrefix  = ""
+	defBootstrapURL  = "http://localhost:8202"
+	defCertsURL      = ""
+	defTimeout       = "5s"
+	defChannels      = "control,data"
+	defContent       = ""
+
+	envLogLevel      = "MF_PROVISION_LOG_LEVEL"
+	envHTTPPort      = "MF_PROVISION_HTTP_PORT"
+	envDBHost        = "MF_PROVISION_DB_HOST"
+	envDBPort        = "MF_PROVISION_DB_PORT"
+	envDBUser        = "MF_PROVISION_DB_USER"
+	envDBPass        = "MF_PROVISION_DB_PASS"
+	envDBName        = "MF_PROVISION_DB"
+	envDBSSLMode     = "MF_PROVISION_DB_SSL_MODE"
+	envDBSSLCert     = "MF_PROVISION_DB_SSL_CERT"
+	envDBSSLKey      = "MF_PROVISION_DB_SSL_KEY"
+	envDBSSLRootCert = "MF_PROVISION_DB_SSL_ROOT_CERT"
+	envUsersURL      = "MF_USERS_URL"
+	envUsersTimeout  = "MF_PROVISION_USERS_TIMEOUT"
+	envClientTLS     = "MF_PROVISION_CLIENT_TLS"
+	envCACerts       = "MF_PROVISION_CA_CERTS"
+	envBaseURL       = "MF_SDK_BASE_URL"
+	envThingsPrefix  = "MF_SDK_THINGS_PREFIX"
+	envBootstrapURL  = "MF_PROVISION_BOOTSTRAP_URL"
+	envCertsURL      = "MF_PROVISION_CERTS_URL"
+	envTimeout       = "MF_PROVISION_TIMEOUT"
+	envChannels      = "MF_PROVISION_CHANNELS"
+	envContent       = "MF_PROVISION_BOOTSTRAP_CONTENT"
+)
+
+type config struct {
+	logLevel     string
+	httpPort     string
+	dbConfig     postgres.Config
+	usersURL     string
+	usersTimeout time.Duration
+	clientTLS    bool
+	caCerts      string
+	baseURL      string
+	thingsPrefix string
+	bootstrapURL string
+	certsURL     string
+	timeout      time.Duration
+	provision    provision.Config
+}
+
+func main() {
+	cfg := loadConfig()
+
+	logger, err := logger.New(os.Stdout, cfg.logLevel)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	db := connectToDB(cfg.dbConfig, logger)
+	defer db.Close()
+
+	conn := connectToUsers(cfg, logger)
+	defer conn.Close()
+
+	users := usersapi.NewClient(opentracing.NoopTracer{}, conn, cfg.usersTimeout)
+	svc := newService(users, db, cfg, logger)
+
+	errs := make(chan error, 2)
+
+	go startHTTPServer(svc, cfg.httpPort, logger, errs)
+
+	go func() {
+		c := make(chan os.Signal, 1)
+		signal.Notify(c, syscall.SIGINT)
+		errs <- fmt.Errorf("%s", <-c)
+	}()
+
+	err = <-errs
+	logger.Error(fmt.Sprintf("Provision service terminated: %s", err))
+}
+
+func loadConfig() config {
+	tls, err := strconv.ParseBool(mainflux.Env(envClientTLS, defClientTLS))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envClientTLS)
+	}
+
+	usersTimeout, err := strconv.ParseInt(mainflux.Env(envUsersTimeout, defUsersTimeout), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envUsersTimeout, err.Error())
+	}
+
+	timeout, err := time.ParseDuration(mainflux.Env(envTimeout, defTimeout))
+	if err != nil || timeout <= 0 {
+		log.Fatalf("Invalid value passed for %s\n", envTimeout)
+	}
+
+	channels := []string{}
+	for _, ch := range strings.Split(mainflux.Env(envChannels, defChannels), sep) {
+		if ch = strings.TrimSpace(ch); ch != "" {
+			channels = append(channels, ch)
+		}
+	}
+
+	dbConfig := postgres.Config{
+		Host:        mainflux.Env(envDBHost, defDBHost),
+		Port:        mainflux.Env(envDBPort, defDBPort),
+		User:        mainflux.Env(envDBUser, defDBUser),
+		Pass:        mainflux.Env(envDBPass, defDBPass),
+		Name:        mainflux.Env(envDBName, defDBName),
+		SSLMode:     mainflux.Env(envDBSSLMode, defDBSSLMode),
+		SSLCert:     mainflux.Env(envDBSSLCert, defDBSSLCert),
+		SSLKey:      mainflux.Env(envDBSSLKey, defDBSSLKey),
+		SSLRootCert: mainflux.Env(envDBSSLRootCert, defDBSSLRootCert),
+	}
+
+	return config{
+		logLevel:     mainflux.Env(envLogLevel, defLogLevel),
+		httpPort:     mainflux.Env(envHTTPPort, defHTTPPort),
+		dbConfig:     dbConfig,
+		usersURL:     mainflux.Env(envUsersURL, defUsersURL),
+		usersTimeout: time.Duration(usersTimeout) * time.Second,
+		clientTLS:    tls,
+		caCerts:      mainflux.Env(envCACerts, defCACerts),
+		baseURL:      mainflux.Env(envBaseURL, defBaseURL),
+		thingsPrefix: mainflux.Env(envThingsPrefix, defThingsPrefix),
+		bootstrapURL: mainflux.Env(envBootstrapURL, defBootstrapURL),
+		certsURL:     mainflux.Env(envCertsURL, defCertsURL),
+		timeout:      timeout,
+		provision: provision.Config{
+			Channels: channels,
+			Content:  mainflux.Env(envContent, defContent),
+		},
+	}
+}
+
+func connectToDB(dbConfig postgres.Config, logger logger.Logger) *sqlx.DB {
+	db, err := postgres.Connect(dbConfig)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to Postgres: %s", err))
+		os.Exit(1)
+	}
+	return db
+}
+
+func connectToUsers(cfg config, logger logger.Logger) *grpc.ClientConn {
+	var opts []grpc.DialOption
+	if cfg.clientTLS {
+		if cfg.caCerts != "" {
+			tpc, err := credentials.NewClientTLSFromFile(cfg.caCerts, "")
+			if err != nil {
+				logger.Error(fmt.Sprintf("Failed to create tls credentials: %s", err))
+				os.Exit(1)
+			}
+			opts = append(opts, grpc.WithTransportCredentials(tpc))
+		}
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+		logger.Info("gRPC communication is not encrypted")
+	}
+
+	conn, err := grpc.Dial(cfg.usersURL, opts...)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to users service: %s", err))
+		os.Exit(1)
+	}
+
+	return conn
+}
+
+func newService(users mainflux.UsersServiceClient, db *sqlx.DB, cfg config, logger logger.Logger) provision.Service {
+	sdk := mfsdk.NewSDK(mfsdk.Config{
+		BaseURL:      cfg.baseURL,
+		ThingsPrefix: cfg.thingsPrefix,
+	})
+
+	var cc provision.Certs
+	if cfg.certsURL != "" {
+		cc = certs.New(cfg.certsURL, cfg.timeout)
+	} else {
+		logger.Info("Certs service URL is not set, certificates issuing is disabled")
+	}
+
+	claims := postgres.NewClaimRepository(db)
+
+	svc := provision.New(users, things.New(sdk), bootstrap.New(cfg.bootstrapURL, cfg.timeout), cc, claims, cfg.provision)
+	svc = api.LoggingMiddleware(svc, logger)
+	svc = api.MetricsMiddleware(
+		svc,
+		kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: "provision",
+			Subsystem: "api",
+			Name:      "request_count",
+			Help:      "Number of requests received.",
+		}, []string{"method"}),
+		kitprometheus.NewSummaryFrom(stdprometheus.SummaryOpts{
+			Namespace: "provision",
+			Subsystem: "api",
+			Name:      "request_latency_microseconds",
+			Help:      "Total duration of requests in microseconds.",
+		}, []string{"method"}),
+	)
+
+	return svc
+}
+
+func startHTTPServer(svc provision.Service, port string, logger logger.Logger, errs chan error) {
+	p := fmt.Sprintf(":%s", port)
+	logger.Info(fmt.Sprintf("Provision service started, exposed port %s", port))
+	errs <- http.ListenAndServe(p, api.MakeHandler(svc))
+}
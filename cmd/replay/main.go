@@ -0,0 +1,294 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/logger"
+	mfnats "github.com/mainflux/mainflux/nats"
+	"github.com/mainflux/mainflux/replay"
+	"github.com/mainflux/mainflux/replay/api"
+	"github.com/mainflux/mainflux/replay/archive"
+	"github.com/mainflux/mainflux/replay/nats"
+	"github.com/mainflux/mainflux/replay/reader"
+	thingsapi "github.com/mainflux/mainflux/things/api/auth/grpc"
+	"github.com/mainflux/mainflux/things/uuid"
+	usersapi "github.com/mainflux/mainflux/users/api/grpc"
+	"github.com/mainflux/mainflux/writers/s3"
+	broker "github.com/nats-io/nats.go"
+	opentracing "github.com/opentracing/opentracing-go"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+const (
+	defLogLevel          = "error"
+	defHTTPPort          = "8197"
+	defUsersURL          = "localhost:8181"
+	defUsersTimeout      = "1" // in seconds
+	defThingsURL         = "localhost:8181"
+	defThingsTimeout     = "1" // in seconds
+	defClientTLS         = "false"
+	defCACerts           = ""
+	defReaderURL         = "http://localhost:8905"
+	defReaderTimeout     = "30" // in seconds
+	defWindow            = "1h"
+	defS3Endpoint        = "http://localhost:9000"
+	defS3Region          = "us-east-1"
+	defS3Bucket          = "mainflux"
+	defS3AccessKey       = ""
+	defS3SecretKey       = ""
+	defS3Prefix          = "snapshots"
+	defS3Timeout         = "30s"
+	defNatsURL           = broker.DefaultURL
+	defNatsCreds         = ""
+	defNatsNKeySeed      = ""
+	defNatsCACerts       = ""
+	defNatsClientCert    = ""
+	defNatsClientKey     = ""
+	defNatsSubjectPrefix = ""
+
+	envLogLevel          = "MF_REPLAY_LOG_LEVEL"
+	envHTTPPort          = "MF_REPLAY_HTTP_PORT"
+	envUsersURL          = "MF_USERS_URL"
+	envUsersTimeout      = "MF_REPLAY_USERS_TIMEOUT"
+	envThingsURL         = "MF_THINGS_URL"
+	envThingsTimeout     = "MF_REPLAY_THINGS_TIMEOUT"
+	envClientTLS         = "MF_REPLAY_CLIENT_TLS"
+	envCACerts           = "MF_REPLAY_CA_CERTS"
+	envReaderURL         = "MF_REPLAY_READER_URL"
+	envReaderTimeout     = "MF_REPLAY_READER_TIMEOUT"
+	envWindow            = "MF_REPLAY_WINDOW"
+	envS3Endpoint        = "MF_REPLAY_S3_ENDPOINT"
+	envS3Region          = "MF_REPLAY_S3_REGION"
+	envS3Bucket          = "MF_REPLAY_S3_BUCKET"
+	envS3AccessKey       = "MF_REPLAY_S3_ACCESS_KEY"
+	envS3SecretKey       = "MF_REPLAY_S3_SECRET_KEY"
+	envS3Prefix          = "MF_REPLAY_S3_PREFIX"
+	envS3Timeout         = "MF_REPLAY_S3_TIMEOUT"
+	envNatsURL           = "MF_NATS_URL"
+	envNatsCreds         = "MF_NATS_CREDS"
+	envNatsNKeySeed      = "MF_NATS_NKEY_SEED"
+	envNatsCACerts       = "MF_NATS_CA_CERTS"
+	envNatsClientCert    = "MF_NATS_CLIENT_CERT"
+	envNatsClientKey     = "MF_NATS_CLIENT_KEY"
+	envNatsSubjectPrefix = "MF_NATS_SUBJECT_PREFIX"
+)
+
+type config struct {
+	logLevel      string
+	httpPort      string
+	usersURL      string
+	usersTimeout  time.Duration
+	thingsURL     string
+	thingsTimeout time.Duration
+	clientTLS     bool
+	caCerts       string
+	readerURL     string
+	readerTimeout time.Duration
+	window        time.Duration
+	s3Config      s3.Config
+	s3Prefix      string
+	s3Timeout     time.Duration
+	natsConfig    mfnats.Config
+}
+
+func main() {
+	cfg := loadConfig()
+
+	logger, err := logger.New(os.Stdout, cfg.logLevel)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	nc, err := mfnats.Connect(cfg.natsConfig)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to NATS: %s", err))
+		os.Exit(1)
+	}
+	defer nc.Close()
+
+	conn := connectToUsers(cfg, logger)
+	defer conn.Close()
+
+	thingsConn := connectToThings(cfg, logger)
+	defer thingsConn.Close()
+
+	users := usersapi.NewClient(opentracing.NoopTracer{}, conn, cfg.usersTimeout)
+	things := thingsapi.NewClient(thingsConn, opentracing.NoopTracer{}, cfg.thingsTimeout)
+	svc := newService(users, things, nc, cfg, logger)
+
+	errs := make(chan error, 2)
+
+	go startHTTPServer(svc, cfg.httpPort, logger, errs)
+
+	go func() {
+		c := make(chan os.Signal, 1)
+		signal.Notify(c, syscall.SIGINT)
+		errs <- fmt.Errorf("%s", <-c)
+	}()
+
+	err = <-errs
+	logger.Error(fmt.Sprintf("Replay service terminated: %s", err))
+}
+
+func loadConfig() config {
+	tls, err := strconv.ParseBool(mainflux.Env(envClientTLS, defClientTLS))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envClientTLS)
+	}
+
+	timeout, err := strconv.ParseInt(mainflux.Env(envUsersTimeout, defUsersTimeout), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envUsersTimeout, err.Error())
+	}
+
+	thingsTimeout, err := strconv.ParseInt(mainflux.Env(envThingsTimeout, defThingsTimeout), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envThingsTimeout, err.Error())
+	}
+
+	readerTimeout, err := strconv.ParseInt(mainflux.Env(envReaderTimeout, defReaderTimeout), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envReaderTimeout, err.Error())
+	}
+
+	window, err := time.ParseDuration(mainflux.Env(envWindow, defWindow))
+	if err != nil || window <= 0 {
+		log.Fatalf("Invalid value passed for %s\n", envWindow)
+	}
+
+	s3Timeout, err := time.ParseDuration(mainflux.Env(envS3Timeout, defS3Timeout))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envS3Timeout, err.Error())
+	}
+
+	s3Config := s3.Config{
+		Endpoint:  mainflux.Env(envS3Endpoint, defS3Endpoint),
+		Region:    mainflux.Env(envS3Region, defS3Region),
+		Bucket:    mainflux.Env(envS3Bucket, defS3Bucket),
+		AccessKey: mainflux.Env(envS3AccessKey, defS3AccessKey),
+		SecretKey: mainflux.Env(envS3SecretKey, defS3SecretKey),
+	}
+
+	natsConfig := mfnats.Config{
+		URL:        mainflux.Env(envNatsURL, defNatsURL),
+		Creds:      mainflux.Env(envNatsCreds, defNatsCreds),
+		NKeySeed:   mainflux.Env(envNatsNKeySeed, defNatsNKeySeed),
+		CACerts:    mainflux.Env(envNatsCACerts, defNatsCACerts),
+		ClientCert: mainflux.Env(envNatsClientCert, defNatsClientCert),
+		ClientKey:  mainflux.Env(envNatsClientKey, defNatsClientKey),
+		Prefix:     mainflux.Env(envNatsSubjectPrefix, defNatsSubjectPrefix),
+	}
+
+	return config{
+		logLevel:      mainflux.Env(envLogLevel, defLogLevel),
+		httpPort:      mainflux.Env(envHTTPPort, defHTTPPort),
+		usersURL:      mainflux.Env(envUsersURL, defUsersURL),
+		usersTimeout:  time.Duration(timeout) * time.Second,
+		thingsURL:     mainflux.Env(envThingsURL, defThingsURL),
+		thingsTimeout: time.Duration(thingsTimeout) * time.Second,
+		clientTLS:     tls,
+		caCerts:       mainflux.Env(envCACerts, defCACerts),
+		readerURL:     mainflux.Env(envReaderURL, defReaderURL),
+		readerTimeout: time.Duration(readerTimeout) * time.Second,
+		window:        window,
+		s3Config:      s3Config,
+		s3Prefix:      mainflux.Env(envS3Prefix, defS3Prefix),
+		s3Timeout:     s3Timeout,
+		natsConfig:    natsConfig,
+	}
+}
+
+func connectToUsers(cfg config, logger logger.Logger) *grpc.ClientConn {
+	var opts []grpc.DialOption
+	if cfg.clientTLS {
+		if cfg.caCerts != "" {
+			tpc, err := credentials.NewClientTLSFromFile(cfg.caCerts, "")
+			if err != nil {
+				logger.Error(fmt.Sprintf("Failed to create tls credentials: %s", err))
+				os.Exit(1)
+			}
+			opts = append(opts, grpc.WithTransportCredentials(tpc))
+		}
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+		logger.Info("gRPC communication is not encrypted")
+	}
+
+	conn, err := grpc.Dial(cfg.usersURL, opts...)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to users service: %s", err))
+		os.Exit(1)
+	}
+
+	return conn
+}
+
+func connectToThings(cfg config, logger logger.Logger) *grpc.ClientConn {
+	var opts []grpc.DialOption
+	if cfg.clientTLS {
+		if cfg.caCerts != "" {
+			tpc, err := credentials.NewClientTLSFromFile(cfg.caCerts, "")
+			if err != nil {
+				logger.Error(fmt.Sprintf("Failed to create tls credentials: %s", err))
+				os.Exit(1)
+			}
+			opts = append(opts, grpc.WithTransportCredentials(tpc))
+		}
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+	}
+
+	conn, err := grpc.Dial(cfg.thingsURL, opts...)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to things service: %s", err))
+		os.Exit(1)
+	}
+
+	return conn
+}
+
+func newService(users mainflux.UsersServiceClient, things mainflux.ThingsServiceClient, nc *broker.Conn, cfg config, logger logger.Logger) replay.Service {
+	rd := reader.NewHTTP(cfg.readerURL, &http.Client{Timeout: cfg.readerTimeout})
+	pub := nats.NewPublisher(nc, cfg.natsConfig.Prefix)
+	arch := archive.NewS3(s3.NewClient(cfg.s3Config, &http.Client{Timeout: cfg.s3Timeout}), cfg.s3Prefix)
+
+	svc := replay.New(users, things, rd, pub, arch, uuid.New(), cfg.window)
+	svc = api.LoggingMiddleware(svc, logger)
+	svc = api.MetricsMiddleware(
+		svc,
+		kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: "replay",
+			Subsystem: "api",
+			Name:      "request_count",
+			Help:      "Number of requests received.",
+		}, []string{"method"}),
+		kitprometheus.NewSummaryFrom(stdprometheus.SummaryOpts{
+			Namespace: "replay",
+			Subsystem: "api",
+			Name:      "request_latency_microseconds",
+			Help:      "Total duration of requests in microseconds.",
+		}, []string{"method"}),
+	)
+
+	return svc
+}
+
+func startHTTPServer(svc replay.Service, port string, logger logger.Logger, errs chan error) {
+	p := fmt.Sprintf(":%s", port)
+	logger.Info(fmt.Sprintf("Replay service started, exposed port %s", port))
+	errs <- http.ListenAndServe(p, api.MakeHandler(svc))
+}
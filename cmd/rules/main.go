@@ -0,0 +1,346 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	"github.com/jmoiron/sqlx"
+	"github.com/mainflux/mainflux"
+	adapter "github.com/mainflux/mainflux/http/nats"
+	"github.com/mainflux/mainflux/logger"
+	mfnats "github.com/mainflux/mainflux/nats"
+	"github.com/mainflux/mainflux/rules"
+	"github.com/mainflux/mainflux/rules/api"
+	"github.com/mainflux/mainflux/rules/nats"
+	"github.com/mainflux/mainflux/rules/notifiers"
+	"github.com/mainflux/mainflux/rules/postgres"
+	"github.com/mainflux/mainflux/rules/things"
+	mfsdk "github.com/mainflux/mainflux/sdk/go"
+	"github.com/mainflux/mainflux/things/uuid"
+	usersapi "github.com/mainflux/mainflux/users/api/grpc"
+	broker "github.com/nats-io/nats.go"
+	opentracing "github.com/opentracing/opentracing-go"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+const (
+	defLogLevel          = "error"
+	defHTTPPort          = "8206"
+	defNatsURL           = broker.DefaultURL
+	defNatsCreds         = ""
+	defNatsNKeySeed      = ""
+	defNatsCACerts       = ""
+	defNatsClientCert    = ""
+	defNatsClientKey     = ""
+	defNatsSubjectPrefix = ""
+	defDBHost            = "localhost"
+	defDBPort            = "5432"
+	defDBUser            = "mainflux"
+	defDBPass            = "mainflux"
+	defDBName            = "rules"
+	defDBSSLMode         = "disable"
+	defDBSSLCert         = ""
+	defDBSSLKey          = ""
+	defDBSSLRootCert     = ""
+	defUsersURL          = "localhost:8181"
+	defUsersTimeout      = "1" // in seconds
+	defClientTLS         = "false"
+	defCACerts           = ""
+	defBaseURL           = "http://localhost"
+	defThingsPrefix      = ""
+	defReloadInterval    = "30s"
+	defWebhookTimeout    = "5s"
+	defSMTPHost          = ""
+	defSMTPPort          = "25"
+	defSMTPUsername      = ""
+	defSMTPPassword      = ""
+	defSMTPFrom          = "rules@mainflux.io"
+	defSMSURL            = ""
+	defSMSToken          = ""
+	defSMSFrom           = ""
+
+	envLogLevel          = "MF_RULES_LOG_LEVEL"
+	envHTTPPort          = "MF_RULES_HTTP_PORT"
+	envNatsURL           = "MF_NATS_URL"
+	envNatsCreds         = "MF_NATS_CREDS"
+	envNatsNKeySeed      = "MF_NATS_NKEY_SEED"
+	envNatsCACerts       = "MF_NATS_CA_CERTS"
+	envNatsClientCert    = "MF_NATS_CLIENT_CERT"
+	envNatsClientKey     = "MF_NATS_CLIENT_KEY"
+	envNatsSubjectPrefix = "MF_NATS_SUBJECT_PREFIX"
+	envDBHost            = "MF_RULES_DB_HOST"
+	envDBPort            = "MF_RULES_DB_PORT"
+	envDBUser            = "MF_RULES_DB_USER"
+	envDBPass            = "MF_RULES_DB_PASS"
+	envDBName            = "MF_RULES_DB"
+	envDBSSLMode         = "MF_RULES_DB_SSL_MODE"
+	envDBSSLCert         = "MF_RULES_DB_SSL_CERT"
+	envDBSSLKey          = "MF_RULES_DB_SSL_KEY"
+	envDBSSLRootCert     = "MF_RULES_DB_SSL_ROOT_CERT"
+	envUsersURL          = "MF_USERS_URL"
+	envUsersTimeout      = "MF_RULES_USERS_TIMEOUT"
+	envClientTLS         = "MF_RULES_CLIENT_TLS"
+	envCACerts           = "MF_RULES_CA_CERTS"
+	envBaseURL           = "MF_SDK_BASE_URL"
+	envThingsPrefix      = "MF_SDK_THINGS_PREFIX"
+	envReloadInterval    = "MF_RULES_RELOAD_INTERVAL"
+	envWebhookTimeout    = "MF_RULES_WEBHOOK_TIMEOUT"
+	envSMTPHost          = "MF_RULES_SMTP_HOST"
+	envSMTPPort          = "MF_RULES_SMTP_PORT"
+	envSMTPUsername      = "MF_RULES_SMTP_USERNAME"
+	envSMTPPassword      = "MF_RULES_SMTP_PASSWORD"
+	envSMTPFrom          = "MF_RULES_SMTP_FROM"
+	envSMSURL            = "MF_RULES_SMS_URL"
+	envSMSToken          = "MF_RULES_SMS_TOKEN"
+	envSMSFrom           = "MF_RULES_SMS_FROM"
+)
+
+type config struct {
+	logLevel       string
+	httpPort       string
+	natsConfig     mfnats.Config
+	dbConfig       postgres.Config
+	usersURL       string
+	usersTimeout   time.Duration
+	clientTLS      bool
+	caCerts        string
+	baseURL        string
+	thingsPrefix   string
+	reloadInterval time.Duration
+	webhookTimeout time.Duration
+	emailConfig    notifiers.EmailConfig
+	smsURL         string
+	smsToken       string
+	smsFrom        string
+}
+
+func main() {
+	cfg := loadConfig()
+
+	logger, err := logger.New(os.Stdout, cfg.logLevel)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	nc := connectToNATS(cfg.natsConfig, logger)
+	defer nc.Close()
+
+	db := connectToDB(cfg.dbConfig, logger)
+	defer db.Close()
+
+	conn := connectToUsers(cfg, logger)
+	defer conn.Close()
+
+	users := usersapi.NewClient(opentracing.NoopTracer{}, conn, cfg.usersTimeout)
+	svc := newService(users, db, nc, cfg, logger)
+
+	if err := svc.Reload(context.Background()); err != nil {
+		logger.Error(fmt.Sprintf("Failed to load rules: %s", err))
+		os.Exit(1)
+	}
+
+	if err := nats.Subscribe(svc, nc, cfg.natsConfig.Prefix, logger); err != nil {
+		logger.Error(fmt.Sprintf("Failed to subscribe to NATS: %s", err))
+		os.Exit(1)
+	}
+
+	go startReload(svc, cfg.reloadInterval, logger)
+
+	errs := make(chan error, 2)
+
+	go startHTTPServer(svc, cfg.httpPort, logger, errs)
+
+	go func() {
+		c := make(chan os.Signal, 1)
+		signal.Notify(c, syscall.SIGINT)
+		errs <- fmt.Errorf("%s", <-c)
+	}()
+
+	err = <-errs
+	logger.Error(fmt.Sprintf("Rules service terminated: %s", err))
+}
+
+func loadConfig() config {
+	tls, err := strconv.ParseBool(mainflux.Env(envClientTLS, defClientTLS))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envClientTLS)
+	}
+
+	timeout, err := strconv.ParseInt(mainflux.Env(envUsersTimeout, defUsersTimeout), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envUsersTimeout, err.Error())
+	}
+
+	reloadInterval, err := time.ParseDuration(mainflux.Env(envReloadInterval, defReloadInterval))
+	if err != nil || reloadInterval <= 0 {
+		log.Fatalf("Invalid value passed for %s\n", envReloadInterval)
+	}
+
+	webhookTimeout, err := time.ParseDuration(mainflux.Env(envWebhookTimeout, defWebhookTimeout))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envWebhookTimeout, err.Error())
+	}
+
+	natsConfig := mfnats.Config{
+		URL:        mainflux.Env(envNatsURL, defNatsURL),
+		Creds:      mainflux.Env(envNatsCreds, defNatsCreds),
+		NKeySeed:   mainflux.Env(envNatsNKeySeed, defNatsNKeySeed),
+		CACerts:    mainflux.Env(envNatsCACerts, defNatsCACerts),
+		ClientCert: mainflux.Env(envNatsClientCert, defNatsClientCert),
+		ClientKey:  mainflux.Env(envNatsClientKey, defNatsClientKey),
+		Prefix:     mainflux.Env(envNatsSubjectPrefix, defNatsSubjectPrefix),
+	}
+
+	dbConfig := postgres.Config{
+		Host:        mainflux.Env(envDBHost, defDBHost),
+		Port:        mainflux.Env(envDBPort, defDBPort),
+		User:        mainflux.Env(envDBUser, defDBUser),
+		Pass:        mainflux.Env(envDBPass, defDBPass),
+		Name:        mainflux.Env(envDBName, defDBName),
+		SSLMode:     mainflux.Env(envDBSSLMode, defDBSSLMode),
+		SSLCert:     mainflux.Env(envDBSSLCert, defDBSSLCert),
+		SSLKey:      mainflux.Env(envDBSSLKey, defDBSSLKey),
+		SSLRootCert: mainflux.Env(envDBSSLRootCert, defDBSSLRootCert),
+	}
+
+	emailConfig := notifiers.EmailConfig{
+		Host:     mainflux.Env(envSMTPHost, defSMTPHost),
+		Port:     mainflux.Env(envSMTPPort, defSMTPPort),
+		Username: mainflux.Env(envSMTPUsername, defSMTPUsername),
+		Password: mainflux.Env(envSMTPPassword, defSMTPPassword),
+		From:     mainflux.Env(envSMTPFrom, defSMTPFrom),
+	}
+
+	return config{
+		logLevel:       mainflux.Env(envLogLevel, defLogLevel),
+		httpPort:       mainflux.Env(envHTTPPort, defHTTPPort),
+		natsConfig:     natsConfig,
+		dbConfig:       dbConfig,
+		usersURL:       mainflux.Env(envUsersURL, defUsersURL),
+		usersTimeout:   time.Duration(timeout) * time.Second,
+		clientTLS:      tls,
+		caCerts:        mainflux.Env(envCACerts, defCACerts),
+		baseURL:        mainflux.Env(envBaseURL, defBaseURL),
+		thingsPrefix:   mainflux.Env(envThingsPrefix, defThingsPrefix),
+		reloadInterval: reloadInterval,
+		webhookTimeout: webhookTimeout,
+		emailConfig:    emailConfig,
+		smsURL:         mainflux.Env(envSMSURL, defSMSURL),
+		smsToken:       mainflux.Env(envSMSToken, defSMSToken),
+		smsFrom:        mainflux.Env(envSMSFrom, defSMSFrom),
+	}
+}
+
+func connectToNATS(cfg mfnats.Config, logger logger.Logger) *broker.Conn {
+	nc, err := mfnats.Connect(cfg)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to NATS: %s", err))
+		os.Exit(1)
+	}
+
+	return nc
+}
+
+func connectToDB(dbConfig postgres.Config, logger logger.Logger) *sqlx.DB {
+	db, err := postgres.Connect(dbConfig)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to Postgres: %s", err))
+		os.Exit(1)
+	}
+	return db
+}
+
+func connectToUsers(cfg config, logger logger.Logger) *grpc.ClientConn {
+	var opts []grpc.DialOption
+	if cfg.clientTLS {
+		if cfg.caCerts != "" {
+			tpc, err := credentials.NewClientTLSFromFile(cfg.caCerts, "")
+			if err != nil {
+				logger.Error(fmt.Sprintf("Failed to create tls credentials: %s", err))
+				os.Exit(1)
+			}
+			opts = append(opts, grpc.WithTransportCredentials(tpc))
+		}
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+		logger.Info("gRPC communication is not encrypted")
+	}
+
+	conn, err := grpc.Dial(cfg.usersURL, opts...)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to users service: %s", err))
+		os.Exit(1)
+	}
+
+	return conn
+}
+
+func newService(users mainflux.UsersServiceClient, db *sqlx.DB, nc *broker.Conn, cfg config, logger logger.Logger) rules.Service {
+	sdk := mfsdk.NewSDK(mfsdk.Config{
+		BaseURL:      cfg.baseURL,
+		ThingsPrefix: cfg.thingsPrefix,
+	})
+
+	// Email and SMS actions are supported only if configured.
+	ns := map[string]rules.Notifier{}
+	if cfg.emailConfig.Host != "" {
+		ns[rules.Email] = notifiers.NewEmail(cfg.emailConfig)
+	}
+	if cfg.smsURL != "" {
+		ns[rules.SMS] = notifiers.NewSMS(cfg.smsURL, cfg.smsToken, cfg.smsFrom, cfg.webhookTimeout)
+	}
+
+	repo := postgres.New(db)
+	channels := things.New(sdk)
+	publisher := adapter.NewMessagePublisher(nc, cfg.natsConfig.Prefix)
+	caller := notifiers.NewWebhook(cfg.webhookTimeout)
+
+	svc := rules.New(users, channels, repo, publisher, caller, ns, uuid.New())
+	svc = api.LoggingMiddleware(svc, logger)
+	svc = api.MetricsMiddleware(
+		svc,
+		kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: "rules",
+			Subsystem: "api",
+			Name:      "request_count",
+			Help:      "Number of requests received.",
+		}, []string{"method"}),
+		kitprometheus.NewSummaryFrom(stdprometheus.SummaryOpts{
+			Namespace: "rules",
+			Subsystem: "api",
+			Name:      "request_latency_microseconds",
+			Help:      "Total duration of requests in microseconds.",
+		}, []string{"method"}),
+	)
+
+	return svc
+}
+
+func startReload(svc rules.Service, interval time.Duration, logger logger.Logger) {
+	logger.Info(fmt.Sprintf("Reloading rules every %s", interval))
+	for {
+		time.Sleep(interval)
+		if err := svc.Reload(context.Background()); err != nil {
+			logger.Error(fmt.Sprintf("Failed to reload rules: %s", err))
+		}
+	}
+}
+
+func startHTTPServer(svc rules.Service, port string, logger logger.Logger, errs chan error) {
+	p := fmt.Sprintf(":%s", port)
+	logger.Info(fmt.Sprintf("Rules service started, exposed port %s", port))
+	errs <- http.ListenAndServe(p, api.MakeHandler(svc))
+}
@@ -0,0 +1,305 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	"github.com/jmoiron/sqlx"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/sandbox"
+	"github.com/mainflux/mainflux/sandbox/api"
+	"github.com/mainflux/mainflux/sandbox/postgres"
+	"github.com/mainflux/mainflux/sandbox/things"
+	"github.com/mainflux/mainflux/sandbox/users"
+	mfsdk "github.com/mainflux/mainflux/sdk/go"
+	"github.com/mainflux/mainflux/things/uuid"
+	usersapi "github.com/mainflux/mainflux/users/api/grpc"
+	opentracing "github.com/opentracing/opentracing-go"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+const (
+	defLogLevel         = "error"
+	defHTTPPort         = "8201"
+	defDBHost           = "localhost"
+	defDBPort           = "5432"
+	defDBUser           = "mainflux"
+	defDBPass           = "mainflux"
+	defDBName           = "sandbox"
+	defDBSSLMode        = "disable"
+	defDBSSLCert        = ""
+	defDBSSLKey         = ""
+	defDBSSLRootCert    = ""
+	defUsersURL         = "localhost:8181"
+	defTimeout          = "1" // in seconds
+	defClientTLS        = "false"
+	defCACerts          = ""
+	defBaseURL          = "http://localhost"
+	defUsersPrefix      = ""
+	defThingsPrefix     = ""
+	defAdminEmail       = ""
+	defAdminPass        = ""
+	defDomain           = "sandbox.mainflux.io"
+	defDuration         = "24h"
+	defMaxDuration      = "72h"
+	defMaxSandboxes     = "100"
+	defThingsQuota      = "10"
+	defChannelsQuota    = "1"
+	defConnectionsQuota = "10"
+	defCleanupInterval  = "1m"
+
+	envLogLevel         = "MF_SANDBOX_LOG_LEVEL"
+	envHTTPPort         = "MF_SANDBOX_HTTP_PORT"
+	envDBHost           = "MF_SANDBOX_DB_HOST"
+	envDBPort           = "MF_SANDBOX_DB_PORT"
+	envDBUser           = "MF_SANDBOX_DB_USER"
+	envDBPass           = "MF_SANDBOX_DB_PASS"
+	envDBName           = "MF_SANDBOX_DB"
+	envDBSSLMode        = "MF_SANDBOX_DB_SSL_MODE"
+	envDBSSLCert        = "MF_SANDBOX_DB_SSL_CERT"
+	envDBSSLKey         = "MF_SANDBOX_DB_SSL_KEY"
+	envDBSSLRootCert    = "MF_SANDBOX_DB_SSL_ROOT_CERT"
+	envUsersURL         = "MF_USERS_URL"
+	envTimeout          = "MF_SANDBOX_TIMEOUT"
+	envClientTLS        = "MF_SANDBOX_CLIENT_TLS"
+	envCACerts          = "MF_SANDBOX_CA_CERTS"
+	envBaseURL          = "MF_SDK_BASE_URL"
+	envUsersPrefix      = "MF_SDK_USERS_PREFIX"
+	envThingsPrefix     = "MF_SDK_THINGS_PREFIX"
+	envAdminEmail       = "MF_SANDBOX_ADMIN_EMAIL"
+	envAdminPass        = "MF_SANDBOX_ADMIN_PASS"
+	envDomain           = "MF_SANDBOX_DOMAIN"
+	envDuration         = "MF_SANDBOX_DURATION"
+	envMaxDuration      = "MF_SANDBOX_MAX_DURATION"
+	envMaxSandboxes     = "MF_SANDBOX_MAX_SANDBOXES"
+	envThingsQuota      = "MF_SANDBOX_THINGS_QUOTA"
+	envChannelsQuota    = "MF_SANDBOX_CHANNELS_QUOTA"
+	envConnectionsQuota = "MF_SANDBOX_CONNECTIONS_QUOTA"
+	envCleanupInterval  = "MF_SANDBOX_CLEANUP_INTERVAL"
+)
+
+type config struct {
+	logLevel        string
+	httpPort        string
+	dbConfig        postgres.Config
+	usersURL        string
+	timeout         time.Duration
+	clientTLS       bool
+	caCerts         string
+	baseURL         string
+	usersPrefix     string
+	thingsPrefix    string
+	adminEmail      string
+	adminPass       string
+	sandboxConfig   sandbox.Config
+	cleanupInterval time.Duration
+}
+
+func main() {
+	cfg := loadConfig()
+
+	logger, err := logger.New(os.Stdout, cfg.logLevel)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	db := connectToDB(cfg.dbConfig, logger)
+	defer db.Close()
+
+	usersConn := connectToUsers(cfg, logger)
+	defer usersConn.Close()
+
+	auth := usersapi.NewClient(opentracing.NoopTracer{}, usersConn, cfg.timeout)
+	svc := newService(auth, db, cfg, logger)
+
+	go startCleanup(svc, cfg.cleanupInterval, logger)
+
+	errs := make(chan error, 2)
+
+	go startHTTPServer(svc, cfg.httpPort, logger, errs)
+
+	go func() {
+		c := make(chan os.Signal, 1)
+		signal.Notify(c, syscall.SIGINT)
+		errs <- fmt.Errorf("%s", <-c)
+	}()
+
+	err = <-errs
+	logger.Error(fmt.Sprintf("Sandbox service terminated: %s", err))
+}
+
+func loadConfig() config {
+	tls, err := strconv.ParseBool(mainflux.Env(envClientTLS, defClientTLS))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envClientTLS)
+	}
+
+	timeout, err := strconv.ParseInt(mainflux.Env(envTimeout, defTimeout), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envTimeout, err.Error())
+	}
+
+	duration, err := time.ParseDuration(mainflux.Env(envDuration, defDuration))
+	if err != nil || duration <= 0 {
+		log.Fatalf("Invalid value passed for %s\n", envDuration)
+	}
+
+	maxDuration, err := time.ParseDuration(mainflux.Env(envMaxDuration, defMaxDuration))
+	if err != nil || maxDuration < duration {
+		log.Fatalf("Invalid value passed for %s\n", envMaxDuration)
+	}
+
+	cleanupInterval, err := time.ParseDuration(mainflux.Env(envCleanupInterval, defCleanupInterval))
+	if err != nil || cleanupInterval <= 0 {
+		log.Fatalf("Invalid value passed for %s\n", envCleanupInterval)
+	}
+
+	maxSandboxes := parseUint(envMaxSandboxes, defMaxSandboxes)
+	quota := sandbox.Quota{
+		Things:      parseUint(envThingsQuota, defThingsQuota),
+		Channels:    parseUint(envChannelsQuota, defChannelsQuota),
+		Connections: parseUint(envConnectionsQuota, defConnectionsQuota),
+	}
+
+	dbConfig := postgres.Config{
+		Host:        mainflux.Env(envDBHost, defDBHost),
+		Port:        mainflux.Env(envDBPort, defDBPort),
+		User:        mainflux.Env(envDBUser, defDBUser),
+		Pass:        mainflux.Env(envDBPass, defDBPass),
+		Name:        mainflux.Env(envDBName, defDBName),
+		SSLMode:     mainflux.Env(envDBSSLMode, defDBSSLMode),
+		SSLCert:     mainflux.Env(envDBSSLCert, defDBSSLCert),
+		SSLKey:      mainflux.Env(envDBSSLKey, defDBSSLKey),
+		SSLRootCert: mainflux.Env(envDBSSLRootCert, defDBSSLRootCert),
+	}
+
+	sandboxConfig := sandbox.Config{
+		Domain:       mainflux.Env(envDomain, defDomain),
+		Duration:     duration,
+		MaxDuration:  maxDuration,
+		MaxSandboxes: maxSandboxes,
+		Quota:        quota,
+	}
+
+	return config{
+		logLevel:        mainflux.Env(envLogLevel, defLogLevel),
+		httpPort:        mainflux.Env(envHTTPPort, defHTTPPort),
+		dbConfig:        dbConfig,
+		usersURL:        mainflux.Env(envUsersURL, defUsersURL),
+		timeout:         time.Duration(timeout) * time.Second,
+		clientTLS:       tls,
+		caCerts:         mainflux.Env(envCACerts, defCACerts),
+		baseURL:         mainflux.Env(envBaseURL, defBaseURL),
+		usersPrefix:     mainflux.Env(envUsersPrefix, defUsersPrefix),
+		thingsPrefix:    mainflux.Env(envThingsPrefix, defThingsPrefix),
+		adminEmail:      mainflux.Env(envAdminEmail, defAdminEmail),
+		adminPass:       mainflux.Env(envAdminPass, defAdminPass),
+		sandboxConfig:   sandboxConfig,
+		cleanupInterval: cleanupInterval,
+	}
+}
+
+func parseUint(key, fallback string) uint64 {
+	val, err := strconv.ParseUint(mainflux.Env(key, fallback), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", key, err.Error())
+	}
+
+	return val
+}
+
+func connectToDB(dbConfig postgres.Config, logger logger.Logger) *sqlx.DB {
+	db, err := postgres.Connect(dbConfig)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to Postgres: %s", err))
+		os.Exit(1)
+	}
+	return db
+}
+
+func connectToUsers(cfg config, logger logger.Logger) *grpc.ClientConn {
+	var opts []grpc.DialOption
+	if cfg.clientTLS {
+		if cfg.caCerts != "" {
+			tpc, err := credentials.NewClientTLSFromFile(cfg.caCerts, "")
+			if err != nil {
+				logger.Error(fmt.Sprintf("Failed to create tls credentials: %s", err))
+				os.Exit(1)
+			}
+			opts = append(opts, grpc.WithTransportCredentials(tpc))
+		}
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+		logger.Info("gRPC communication is not encrypted")
+	}
+
+	conn, err := grpc.Dial(cfg.usersURL, opts...)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to users service: %s", err))
+		os.Exit(1)
+	}
+
+	return conn
+}
+
+func newService(auth mainflux.UsersServiceClient, db *sqlx.DB, cfg config, logger logger.Logger) sandbox.Service {
+	sdk := mfsdk.NewSDK(mfsdk.Config{
+		BaseURL:      cfg.baseURL,
+		UsersPrefix:  cfg.usersPrefix,
+		ThingsPrefix: cfg.thingsPrefix,
+	})
+
+	repo := postgres.New(db)
+	u := users.New(sdk)
+	t := things.New(sdk, cfg.adminEmail, cfg.adminPass)
+
+	svc := sandbox.New(auth, u, t, repo, uuid.New(), cfg.sandboxConfig)
+	svc = api.LoggingMiddleware(svc, logger)
+	svc = api.MetricsMiddleware(
+		svc,
+		kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: "sandbox",
+			Subsystem: "api",
+			Name:      "request_count",
+			Help:      "Number of requests received.",
+		}, []string{"method"}),
+		kitprometheus.NewSummaryFrom(stdprometheus.SummaryOpts{
+			Namespace: "sandbox",
+			Subsystem: "api",
+			Name:      "request_latency_microseconds",
+			Help:      "Total duration of requests in microseconds.",
+		}, []string{"method"}),
+	)
+
+	return svc
+}
+
+func startCleanup(svc sandbox.Service, interval time.Duration, logger logger.Logger) {
+	logger.Info(fmt.Sprintf("Removing expired sandboxes every %s", interval))
+	for {
+		if _, err := svc.Cleanup(context.Background()); err != nil {
+			logger.Error(fmt.Sprintf("Failed to remove expired sandboxes: %s", err))
+		}
+		time.Sleep(interval)
+	}
+}
+
+func startHTTPServer(svc sandbox.Service, port string, logger logger.Logger, errs chan error) {
+	p := fmt.Sprintf(":%s", port)
+	logger.Info(fmt.Sprintf("Sandbox service started, exposed port %s", port))
+	errs <- http.ListenAndServe(p, api.MakeHandler(svc))
+}
@@ -0,0 +1,169 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/simulator"
+	"github.com/mainflux/mainflux/simulator/api"
+	"github.com/mainflux/mainflux/simulator/publishers"
+	"github.com/mainflux/mainflux/things/uuid"
+	usersapi "github.com/mainflux/mainflux/users/api/grpc"
+	opentracing "github.com/opentracing/opentracing-go"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+const (
+	defLogLevel       = "error"
+	defHTTPPort       = "8196"
+	defUsersURL       = "localhost:8181"
+	defUsersTimeout   = "1" // in seconds
+	defClientTLS      = "false"
+	defCACerts        = ""
+	defPublishTimeout = "5" // in seconds
+
+	envLogLevel       = "MF_SIMULATOR_LOG_LEVEL"
+	envHTTPPort       = "MF_SIMULATOR_HTTP_PORT"
+	envUsersURL       = "MF_USERS_URL"
+	envUsersTimeout   = "MF_SIMULATOR_USERS_TIMEOUT"
+	envClientTLS      = "MF_SIMULATOR_CLIENT_TLS"
+	envCACerts        = "MF_SIMULATOR_CA_CERTS"
+	envPublishTimeout = "MF_SIMULATOR_PUBLISH_TIMEOUT"
+)
+
+type config struct {
+	logLevel       string
+	httpPort       string
+	usersURL       string
+	usersTimeout   time.Duration
+	clientTLS      bool
+	caCerts        string
+	publishTimeout time.Duration
+}
+
+func main() {
+	cfg := loadConfig()
+
+	logger, err := logger.New(os.Stdout, cfg.logLevel)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	conn := connectToUsers(cfg, logger)
+	defer conn.Close()
+
+	users := usersapi.NewClient(opentracing.NoopTracer{}, conn, cfg.usersTimeout)
+	svc := newService(users, cfg.publishTimeout, logger)
+
+	errs := make(chan error, 2)
+
+	go startHTTPServer(svc, cfg.httpPort, logger, errs)
+
+	go func() {
+		c := make(chan os.Signal, 1)
+		signal.Notify(c, syscall.SIGINT)
+		errs <- fmt.Errorf("%s", <-c)
+	}()
+
+	err = <-errs
+	logger.Error(fmt.Sprintf("Simulator service terminated: %s", err))
+}
+
+func loadConfig() config {
+	tls, err := strconv.ParseBool(mainflux.Env(envClientTLS, defClientTLS))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envClientTLS)
+	}
+
+	timeout, err := strconv.ParseInt(mainflux.Env(envUsersTimeout, defUsersTimeout), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envUsersTimeout, err.Error())
+	}
+
+	pubTimeout, err := strconv.ParseInt(mainflux.Env(envPublishTimeout, defPublishTimeout), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envPublishTimeout, err.Error())
+	}
+
+	return config{
+		logLevel:       mainflux.Env(envLogLevel, defLogLevel),
+		httpPort:       mainflux.Env(envHTTPPort, defHTTPPort),
+		usersURL:       mainflux.Env(envUsersURL, defUsersURL),
+		usersTimeout:   time.Duration(timeout) * time.Second,
+		clientTLS:      tls,
+		caCerts:        mainflux.Env(envCACerts, defCACerts),
+		publishTimeout: time.Duration(pubTimeout) * time.Second,
+	}
+}
+
+func connectToUsers(cfg config, logger logger.Logger) *grpc.ClientConn {
+	var opts []grpc.DialOption
+	if cfg.clientTLS {
+		if cfg.caCerts != "" {
+			tpc, err := credentials.NewClientTLSFromFile(cfg.caCerts, "")
+			if err != nil {
+				logger.Error(fmt.Sprintf("Failed to create tls credentials: %s", err))
+				os.Exit(1)
+			}
+			opts = append(opts, grpc.WithTransportCredentials(tpc))
+		}
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+		logger.Info("gRPC communication is not encrypted")
+	}
+
+	conn, err := grpc.Dial(cfg.usersURL, opts...)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to users service: %s", err))
+		os.Exit(1)
+	}
+
+	return conn
+}
+
+func newService(users mainflux.UsersServiceClient, publishTimeout time.Duration, logger logger.Logger) simulator.Service {
+	pubs := map[string]simulator.Publisher{
+		simulator.HTTP: publishers.NewHTTP(publishTimeout),
+		simulator.MQTT: publishers.NewMQTT(),
+	}
+
+	svc := simulator.New(users, pubs, uuid.New())
+	svc = api.LoggingMiddleware(svc, logger)
+	svc = api.MetricsMiddleware(
+		svc,
+		kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: "simulator",
+			Subsystem: "api",
+			Name:      "request_count",
+			Help:      "Number of requests received.",
+		}, []string{"method"}),
+		kitprometheus.NewSummaryFrom(stdprometheus.SummaryOpts{
+			Namespace: "simulator",
+			Subsystem: "api",
+			Name:      "request_latency_microseconds",
+			Help:      "Total duration of requests in microseconds.",
+		}, []string{"method"}),
+	)
+
+	return svc
+}
+
+func startHTTPServer(svc simulator.Service, port string, logger logger.Logger, errs chan error) {
+	p := fmt.Sprintf(":%s", port)
+	logger.Info(fmt.Sprintf("Simulator service started, exposed port %s", port))
+	errs <- http.ListenAndServe(p, api.MakeHandler(svc))
+}
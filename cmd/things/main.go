@@ -4,6 +4,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -13,6 +14,7 @@ import (
 	"os"
 	"os/signal"
 	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
@@ -26,11 +28,15 @@ import (
 	"github.com/go-redis/redis"
 	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/logger"
+	mfredis "github.com/mainflux/mainflux/redis"
 	"github.com/mainflux/mainflux/things"
 	"github.com/mainflux/mainflux/things/api"
 	authgrpcapi "github.com/mainflux/mainflux/things/api/auth/grpc"
 	authhttpapi "github.com/mainflux/mainflux/things/api/auth/http"
 	thhttpapi "github.com/mainflux/mainflux/things/api/things/http"
+	"github.com/mainflux/mainflux/things/hmac"
+	"github.com/mainflux/mainflux/things/keys"
+	"github.com/mainflux/mainflux/things/mongodb"
 	"github.com/mainflux/mainflux/things/postgres"
 	rediscache "github.com/mainflux/mainflux/things/redis"
 	localusers "github.com/mainflux/mainflux/things/users"
@@ -42,7 +48,12 @@ import (
 )
 
 const (
+	dbTypePostgres = "postgres"
+	dbTypeMongoDB  = "mongodb"
+
 	defLogLevel        = "error"
+	defDBType          = dbTypePostgres
+	defMongoURL        = "mongodb://localhost:27017"
 	defDBHost          = "localhost"
 	defDBPort          = "5432"
 	defDBUser          = "mainflux"
@@ -70,8 +81,26 @@ const (
 	defSingleUserToken = ""
 	defJaegerURL       = ""
 	defUsersTimeout    = "1" // in seconds
+	defQuotaThings     = "0"
+	defQuotaChannels   = "0"
+	defQuotaConns      = "0"
+	defAdmins          = ""
+	defKeyGrace        = "3600" // in seconds
+	defKeyOverlap      = "0"    // in seconds
+	defKeySecret       = ""
+	defDBReplicaHost   = ""
+	defDBReplicaPort   = "5432"
+	defDBMaxOpenConns  = ""
+	defDBMaxIdleConns  = ""
+	defDBConnLifetime  = ""  // in seconds
+	defDBConnIdleTime  = ""  // in seconds
+	defConsistencyWin  = "5" // in seconds
+	defKeyLength       = "32"
+	defUsageFlush      = "60" // in seconds
 
 	envLogLevel        = "MF_THINGS_LOG_LEVEL"
+	envDBType          = "MF_THINGS_DB_TYPE"
+	envMongoURL        = "MF_THINGS_MONGO_URL"
 	envDBHost          = "MF_THINGS_DB_HOST"
 	envDBPort          = "MF_THINGS_DB_PORT"
 	envDBUser          = "MF_THINGS_DB_USER"
@@ -99,11 +128,30 @@ const (
 	envSingleUserToken = "MF_THINGS_SINGLE_USER_TOKEN"
 	envJaegerURL       = "MF_JAEGER_URL"
 	envUsersTimeout    = "MF_THINGS_USERS_TIMEOUT"
+	envQuotaThings     = "MF_THINGS_QUOTA_THINGS"
+	envQuotaChannels   = "MF_THINGS_QUOTA_CHANNELS"
+	envQuotaConns      = "MF_THINGS_QUOTA_CONNECTIONS"
+	envAdmins          = "MF_THINGS_ADMINS"
+	envKeyGrace        = "MF_THINGS_KEY_GRACE_PERIOD"
+	envKeyOverlap      = "MF_THINGS_KEY_OVERLAP_PERIOD"
+	envKeySecret       = "MF_THINGS_KEY_SECRET"
+	envDBReplicaHost   = "MF_THINGS_DB_REPLICA_HOST"
+	envDBReplicaPort   = "MF_THINGS_DB_REPLICA_PORT"
+	envDBMaxOpenConns  = "MF_THINGS_DB_MAX_OPEN_CONNS"
+	envDBMaxIdleConns  = "MF_THINGS_DB_MAX_IDLE_CONNS"
+	envDBConnLifetime  = "MF_THINGS_DB_CONN_LIFETIME"
+	envDBConnIdleTime  = "MF_THINGS_DB_CONN_IDLE_TIME"
+	envConsistencyWin  = "MF_THINGS_CONSISTENCY_WINDOW"
+	envKeyLength       = "MF_THINGS_KEY_LENGTH"
+	envUsageFlush      = "MF_THINGS_USAGE_FLUSH_PERIOD"
 )
 
 type config struct {
 	logLevel        string
+	dbType          string
 	dbConfig        postgres.Config
+	dbReplicaConfig postgres.Config
+	mongoURL        string
 	clientTLS       bool
 	caCerts         string
 	cacheURL        string
@@ -122,6 +170,14 @@ type config struct {
 	singleUserToken string
 	jaegerURL       string
 	usersTimeout    time.Duration
+	quota           things.Quota
+	admins          []string
+	keyGrace        time.Duration
+	keyOverlap      time.Duration
+	keySecret       string
+	consistencyWin  time.Duration
+	keyLength       int
+	usageFlush      time.Duration
 }
 
 func main() {
@@ -139,9 +195,6 @@ func main() {
 
 	esClient := connectToRedis(cfg.esURL, cfg.esPass, cfg.esDB, logger)
 
-	db := connectToDB(cfg.dbConfig, logger)
-	defer db.Close()
-
 	usersTracer, usersCloser := initJaeger("users", cfg.jaegerURL, logger)
 	defer usersCloser.Close()
 
@@ -153,13 +206,16 @@ func main() {
 	dbTracer, dbCloser := initJaeger("things_db", cfg.jaegerURL, logger)
 	defer dbCloser.Close()
 
+	thingsRepo, channelsRepo, quotasRepo, schemasRepo, usageRepo, closeDB := newRepositories(cfg, logger)
+	defer closeDB()
+
 	cacheTracer, cacheCloser := initJaeger("things_cache", cfg.jaegerURL, logger)
 	defer cacheCloser.Close()
 
-	svc := newService(users, dbTracer, cacheTracer, db, cacheClient, esClient, logger)
+	svc := newService(users, dbTracer, cacheTracer, thingsRepo, channelsRepo, quotasRepo, schemasRepo, usageRepo, cacheClient, esClient, cfg, logger)
 	errs := make(chan error, 2)
 
-	go startHTTPServer(thhttpapi.MakeHandler(thingsTracer, svc), cfg.httpPort, cfg, logger, errs)
+	go startHTTPServer(thhttpapi.MakeHandler(thingsTracer, svc, cfg.consistencyWin), cfg.httpPort, cfg, logger, errs)
 	go startHTTPServer(authhttpapi.MakeHandler(thingsTracer, svc), cfg.authHTTPPort, cfg, logger, errs)
 	go startGRPCServer(svc, thingsTracer, cfg, logger, errs)
 
@@ -185,20 +241,75 @@ func loadConfig() config {
 	}
 
 	dbConfig := postgres.Config{
-		Host:        mainflux.Env(envDBHost, defDBHost),
-		Port:        mainflux.Env(envDBPort, defDBPort),
-		User:        mainflux.Env(envDBUser, defDBUser),
-		Pass:        mainflux.Env(envDBPass, defDBPass),
-		Name:        mainflux.Env(envDBName, defDBName),
-		SSLMode:     mainflux.Env(envDBSSLMode, defDBSSLMode),
-		SSLCert:     mainflux.Env(envDBSSLCert, defDBSSLCert),
-		SSLKey:      mainflux.Env(envDBSSLKey, defDBSSLKey),
-		SSLRootCert: mainflux.Env(envDBSSLRootCert, defDBSSLRootCert),
+		Host:            mainflux.Env(envDBHost, defDBHost),
+		Port:            mainflux.Env(envDBPort, defDBPort),
+		User:            mainflux.Env(envDBUser, defDBUser),
+		Pass:            mainflux.Env(envDBPass, defDBPass),
+		Name:            mainflux.Env(envDBName, defDBName),
+		SSLMode:         mainflux.Env(envDBSSLMode, defDBSSLMode),
+		SSLCert:         mainflux.Env(envDBSSLCert, defDBSSLCert),
+		SSLKey:          mainflux.Env(envDBSSLKey, defDBSSLKey),
+		SSLRootCert:     mainflux.Env(envDBSSLRootCert, defDBSSLRootCert),
+		MaxOpenConns:    parseCount(envDBMaxOpenConns, defDBMaxOpenConns),
+		MaxIdleConns:    parseCount(envDBMaxIdleConns, defDBMaxIdleConns),
+		ConnMaxLifetime: parseSeconds(envDBConnLifetime, defDBConnLifetime),
+		ConnMaxIdleTime: parseSeconds(envDBConnIdleTime, defDBConnIdleTime),
+	}
+
+	// Replica is accessed using the primary database credentials.
+	dbReplicaConfig := dbConfig
+	dbReplicaConfig.Host = mainflux.Env(envDBReplicaHost, defDBReplicaHost)
+	dbReplicaConfig.Port = mainflux.Env(envDBReplicaPort, defDBReplicaPort)
+
+	consistencyWin, err := strconv.ParseUint(mainflux.Env(envConsistencyWin, defConsistencyWin), 10, 32)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envConsistencyWin, err.Error())
+	}
+
+	quota := things.Quota{
+		Things:      parseQuota(envQuotaThings, defQuotaThings),
+		Channels:    parseQuota(envQuotaChannels, defQuotaChannels),
+		Connections: parseQuota(envQuotaConns, defQuotaConns),
+	}
+
+	var admins []string
+	for _, admin := range strings.Split(mainflux.Env(envAdmins, defAdmins), ",") {
+		if admin = strings.TrimSpace(admin); admin != "" {
+			admins = append(admins, admin)
+		}
+	}
+
+	keyGrace, err := strconv.ParseUint(mainflux.Env(envKeyGrace, defKeyGrace), 10, 32)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envKeyGrace, err.Error())
+	}
+
+	keyOverlap, err := strconv.ParseUint(mainflux.Env(envKeyOverlap, defKeyOverlap), 10, 32)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envKeyOverlap, err.Error())
+	}
+
+	keyLength, err := strconv.ParseUint(mainflux.Env(envKeyLength, defKeyLength), 10, 16)
+	if err != nil || keyLength < keys.MinLength {
+		log.Fatalf("Invalid %s value, must be at least %d", envKeyLength, keys.MinLength)
+	}
+
+	usageFlush, err := strconv.ParseUint(mainflux.Env(envUsageFlush, defUsageFlush), 10, 32)
+	if err != nil || usageFlush == 0 {
+		log.Fatalf("Invalid %s value, must be a positive number of seconds", envUsageFlush)
+	}
+
+	dbType := mainflux.Env(envDBType, defDBType)
+	if dbType != dbTypePostgres && dbType != dbTypeMongoDB {
+		log.Fatalf("Invalid value passed for %s\n", envDBType)
 	}
 
 	return config{
 		logLevel:        mainflux.Env(envLogLevel, defLogLevel),
+		dbType:          dbType,
 		dbConfig:        dbConfig,
+		dbReplicaConfig: dbReplicaConfig,
+		mongoURL:        mainflux.Env(envMongoURL, defMongoURL),
 		clientTLS:       tls,
 		caCerts:         mainflux.Env(envCACerts, defCACerts),
 		cacheURL:        mainflux.Env(envCacheURL, defCacheURL),
@@ -217,7 +328,47 @@ func loadConfig() config {
 		singleUserToken: mainflux.Env(envSingleUserToken, defSingleUserToken),
 		jaegerURL:       mainflux.Env(envJaegerURL, defJaegerURL),
 		usersTimeout:    time.Duration(timeout) * time.Second,
+		quota:           quota,
+		admins:          admins,
+		keyGrace:        time.Duration(keyGrace) * time.Second,
+		keyOverlap:      time.Duration(keyOverlap) * time.Second,
+		keySecret:       mainflux.Env(envKeySecret, defKeySecret),
+		consistencyWin:  time.Duration(consistencyWin) * time.Second,
+		keyLength:       int(keyLength),
+		usageFlush:      time.Duration(usageFlush) * time.Second,
+	}
+}
+
+func parseQuota(key, def string) uint64 {
+	limit, err := strconv.ParseUint(mainflux.Env(key, def), 10, 63)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", key, err.Error())
 	}
+
+	return limit
+}
+
+// parseCount parses the non-negative integer, such as the connection pool
+// size. The unset value is returned as -1, keeping the pool default.
+func parseCount(key, def string) int {
+	val := mainflux.Env(key, def)
+	if val == "" {
+		return -1
+	}
+
+	count, err := strconv.Atoi(val)
+	if err != nil || count < 0 {
+		log.Fatalf("Invalid value passed for %s\n", key)
+	}
+
+	return count
+}
+
+// parseSeconds parses the non-negative number of seconds, such as the
+// connection lifetime. The unset value is returned as negative duration,
+// keeping the pool default.
+func parseSeconds(key, def string) time.Duration {
+	return time.Duration(parseCount(key, def)) * time.Second
 }
 
 func initJaeger(svcName, url string, logger logger.Logger) (opentracing.Tracer, io.Closer) {
@@ -244,18 +395,20 @@ func initJaeger(svcName, url string, logger logger.Logger) (opentracing.Tracer,
 	return tracer, closer
 }
 
-func connectToRedis(cacheURL, cachePass string, cacheDB string, logger logger.Logger) *redis.Client {
+func connectToRedis(cacheURL, cachePass, cacheDB string, logger logger.Logger) redis.UniversalClient {
 	db, err := strconv.Atoi(cacheDB)
 	if err != nil {
 		logger.Error(fmt.Sprintf("Failed to connect to cache: %s", err))
 		os.Exit(1)
 	}
 
-	return redis.NewClient(&redis.Options{
-		Addr:     cacheURL,
-		Password: cachePass,
-		DB:       db,
-	})
+	client, err := mfredis.Connect(cacheURL, cachePass, db)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to cache: %s", err))
+		os.Exit(1)
+	}
+
+	return client
 }
 
 func connectToDB(dbConfig postgres.Config, logger logger.Logger) *sqlx.DB {
@@ -267,6 +420,49 @@ func connectToDB(dbConfig postgres.Config, logger logger.Logger) *sqlx.DB {
 	return db
 }
 
+func connectToReplica(dbConfig postgres.Config, logger logger.Logger) *sqlx.DB {
+	db, err := postgres.ConnectReplica(dbConfig)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to postgres replica: %s", err))
+		os.Exit(1)
+	}
+	return db
+}
+
+func connectToMongoDB(url, name string, logger logger.Logger) mongodb.Database {
+	db, err := mongodb.Connect(context.Background(), url, name)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to MongoDB: %s", err))
+		os.Exit(1)
+	}
+	return db
+}
+
+func newRepositories(cfg config, logger logger.Logger) (things.ThingRepository, things.ChannelRepository, things.QuotaRepository, things.SchemaRepository, things.APIUsageRepository, func() error) {
+	if cfg.dbType == dbTypeMongoDB {
+		db := connectToMongoDB(cfg.mongoURL, cfg.dbConfig.Name, logger)
+		closeDB := func() error {
+			return db.Close(context.Background())
+		}
+		return mongodb.NewThingRepository(db), mongodb.NewChannelRepository(db), mongodb.NewQuotaRepository(db), mongodb.NewSchemaRepository(db), mongodb.NewAPIUsageRepository(db), closeDB
+	}
+
+	db := connectToDB(cfg.dbConfig, logger)
+	database := postgres.NewDatabase(db)
+	closeDB := db.Close
+
+	if cfg.dbReplicaConfig.Host != "" {
+		replica := connectToReplica(cfg.dbReplicaConfig, logger)
+		database = postgres.NewReplicatedDatabase(db, replica)
+		closeDB = func() error {
+			replica.Close()
+			return db.Close()
+		}
+	}
+
+	return postgres.NewThingRepository(database), postgres.NewChannelRepository(database), postgres.NewQuotaRepository(database), postgres.NewSchemaRepository(database), postgres.NewAPIUsageRepository(database), closeDB
+}
+
 func createUsersClient(cfg config, tracer opentracing.Tracer, logger logger.Logger) (mainflux.UsersServiceClient, func() error) {
 	if cfg.singleUserEmail != "" && cfg.singleUserToken != "" {
 		return localusers.NewSingleUserService(cfg.singleUserEmail, cfg.singleUserToken), nil
@@ -301,14 +497,12 @@ func connectToUsers(cfg config, logger logger.Logger) *grpc.ClientConn {
 	return conn
 }
 
-func newService(users mainflux.UsersServiceClient, dbTracer opentracing.Tracer, cacheTracer opentracing.Tracer, db *sqlx.DB, cacheClient *redis.Client, esClient *redis.Client, logger logger.Logger) things.Service {
-	database := postgres.NewDatabase(db)
-
-	thingsRepo := postgres.NewThingRepository(database)
+func newService(users mainflux.UsersServiceClient, dbTracer opentracing.Tracer, cacheTracer opentracing.Tracer, thingsRepo things.ThingRepository, channelsRepo things.ChannelRepository, quotasRepo things.QuotaRepository, schemasRepo things.SchemaRepository, usageRepo things.APIUsageRepository, cacheClient redis.UniversalClient, esClient redis.UniversalClient, cfg config, logger logger.Logger) things.Service {
 	thingsRepo = tracing.ThingRepositoryMiddleware(dbTracer, thingsRepo)
-
-	channelsRepo := postgres.NewChannelRepository(database)
 	channelsRepo = tracing.ChannelRepositoryMiddleware(dbTracer, channelsRepo)
+	quotasRepo = tracing.QuotaRepositoryMiddleware(dbTracer, quotasRepo)
+	schemasRepo = tracing.SchemaRepositoryMiddleware(dbTracer, schemasRepo)
+	usageRepo = tracing.APIUsageRepositoryMiddleware(dbTracer, usageRepo)
 
 	chanCache := rediscache.NewChannelCache(cacheClient)
 	chanCache = tracing.ChannelCacheMiddleware(cacheTracer, chanCache)
@@ -316,9 +510,15 @@ func newService(users mainflux.UsersServiceClient, dbTracer opentracing.Tracer,
 	thingCache := rediscache.NewThingCache(cacheClient)
 	thingCache = tracing.ThingCacheMiddleware(cacheTracer, thingCache)
 	idp := uuid.New()
+	keyProvider := keys.New(cfg.keyLength)
+	hasher := newKeyHasher(thingsRepo, cfg, logger)
+
+	changes := rediscache.NewChangeLog(esClient)
+	changes = tracing.ChangeLogMiddleware(cacheTracer, changes)
 
-	svc := things.New(users, thingsRepo, channelsRepo, chanCache, thingCache, idp)
+	svc := things.New(users, thingsRepo, channelsRepo, quotasRepo, schemasRepo, chanCache, thingCache, idp, keyProvider, cfg.quota, cfg.admins, cfg.keyGrace, cfg.keyOverlap, hasher, changes, usageRepo)
 	svc = rediscache.NewEventStoreMiddleware(svc, esClient)
+	svc = api.UsageMiddleware(svc, users, usageRepo, cfg.usageFlush, logger)
 	svc = api.LoggingMiddleware(svc, logger)
 	svc = api.MetricsMiddleware(
 		svc,
@@ -338,6 +538,22 @@ func newService(users mainflux.UsersServiceClient, dbTracer opentracing.Tracer,
 	return svc
 }
 
+// newKeyHasher creates the thing key hasher if the key secret is set, hashing
+// the keys that are still stored in plain-text.
+func newKeyHasher(thingsRepo things.ThingRepository, cfg config, logger logger.Logger) things.KeyHasher {
+	if cfg.keySecret == "" {
+		return nil
+	}
+
+	hasher := hmac.New(cfg.keySecret)
+	if err := thingsRepo.HashKeys(context.Background(), hasher); err != nil {
+		logger.Error(fmt.Sprintf("Failed to hash thing keys: %s", err))
+		os.Exit(1)
+	}
+
+	return hasher
+}
+
 func startHTTPServer(handler http.Handler, port string, cfg config, logger logger.Logger, errs chan error) {
 	p := fmt.Sprintf(":%s", port)
 	if cfg.serverCert != "" || cfg.serverKey != "" {
@@ -0,0 +1,388 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	"github.com/jmoiron/sqlx"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/logger"
+	mfnats "github.com/mainflux/mainflux/nats"
+	"github.com/mainflux/mainflux/tracing"
+	"github.com/mainflux/mainflux/writers"
+	"github.com/mainflux/mainflux/writers/api"
+	"github.com/mainflux/mainflux/writers/deadletter"
+	"github.com/mainflux/mainflux/writers/enrich"
+	"github.com/mainflux/mainflux/writers/timescale"
+	nats "github.com/nats-io/nats.go"
+	opentracing "github.com/opentracing/opentracing-go"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	svcName = "timescale-writer"
+
+	defNatsURL           = nats.DefaultURL
+	defJaegerURL         = ""
+	defNatsCreds         = ""
+	defNatsNKeySeed      = ""
+	defNatsCACerts       = ""
+	defNatsClientCert    = ""
+	defNatsClientKey     = ""
+	defNatsSubjectPrefix = ""
+	defNatsJetStream     = "false"
+	defNatsStream        = "MAINFLUX_MESSAGES"
+	defNatsStreamMaxAge  = "24h"
+	defNatsAckWait       = "30s"
+	defNatsMaxDeliver    = "0"
+	defLogLevel          = "error"
+	defPort              = "9105"
+	defDBHost            = "timescale"
+	defDBPort            = "5432"
+	defDBUser            = "mainflux"
+	defDBPass            = "mainflux"
+	defDBName            = "messages"
+	defDBSSLMode         = "disable"
+	defDBSSLCert         = ""
+	defDBSSLKey          = ""
+	defDBSSLRootCert     = ""
+	defChanCfgPath       = "/config/channels.toml"
+	defChunkInterval     = "24h"
+	defCompressAfter     = "168h"
+	defRetention         = "0"
+	defBatchSize         = "1"
+	defBatchInterval     = "1s"
+	defEnrichURL         = ""
+	defEnrichTimeout     = "1s"
+	defEnrichBypass      = "true"
+	defDLQSubject        = ""
+	defDLQFile           = ""
+
+	envNatsURL           = "MF_NATS_URL"
+	envJaegerURL         = "MF_JAEGER_URL"
+	envNatsCreds         = "MF_NATS_CREDS"
+	envNatsNKeySeed      = "MF_NATS_NKEY_SEED"
+	envNatsCACerts       = "MF_NATS_CA_CERTS"
+	envNatsClientCert    = "MF_NATS_CLIENT_CERT"
+	envNatsClientKey     = "MF_NATS_CLIENT_KEY"
+	envNatsSubjectPrefix = "MF_NATS_SUBJECT_PREFIX"
+	envNatsJetStream     = "MF_NATS_JETSTREAM"
+	envNatsStream        = "MF_NATS_JETSTREAM_STREAM"
+	envNatsStreamMaxAge  = "MF_NATS_JETSTREAM_MAX_AGE"
+	envNatsAckWait       = "MF_NATS_JETSTREAM_ACK_WAIT"
+	envNatsMaxDeliver    = "MF_NATS_JETSTREAM_MAX_DELIVER"
+	envLogLevel          = "MF_TIMESCALE_WRITER_LOG_LEVEL"
+	envPort              = "MF_TIMESCALE_WRITER_PORT"
+	envDBHost            = "MF_TIMESCALE_WRITER_DB_HOST"
+	envDBPort            = "MF_TIMESCALE_WRITER_DB_PORT"
+	envDBUser            = "MF_TIMESCALE_WRITER_DB_USER"
+	envDBPass            = "MF_TIMESCALE_WRITER_DB_PASS"
+	envDBName            = "MF_TIMESCALE_WRITER_DB_NAME"
+	envDBSSLMode         = "MF_TIMESCALE_WRITER_DB_SSL_MODE"
+	envDBSSLCert         = "MF_TIMESCALE_WRITER_DB_SSL_CERT"
+	envDBSSLKey          = "MF_TIMESCALE_WRITER_DB_SSL_KEY"
+	envDBSSLRootCert     = "MF_TIMESCALE_WRITER_DB_SSL_ROOT_CERT"
+	envChanCfgPath       = "MF_TIMESCALE_WRITER_CHANNELS_CONFIG"
+	envChunkInterval     = "MF_TIMESCALE_WRITER_CHUNK_INTERVAL"
+	envCompressAfter     = "MF_TIMESCALE_WRITER_COMPRESS_AFTER"
+	envRetention         = "MF_TIMESCALE_WRITER_RETENTION"
+	envBatchSize         = "MF_TIMESCALE_WRITER_BATCH_SIZE"
+	envBatchInterval     = "MF_TIMESCALE_WRITER_BATCH_INTERVAL"
+	envEnrichURL         = "MF_TIMESCALE_WRITER_ENRICH_URL"
+	envEnrichTimeout     = "MF_TIMESCALE_WRITER_ENRICH_TIMEOUT"
+	envEnrichBypass      = "MF_TIMESCALE_WRITER_ENRICH_BYPASS"
+	envDLQSubject        = "MF_TIMESCALE_WRITER_DLQ_SUBJECT"
+	envDLQFile           = "MF_TIMESCALE_WRITER_DLQ_FILE"
+)
+
+type config struct {
+	natsConfig    mfnats.Config
+	jaegerURL     string
+	jetStream     bool
+	jsConfig      writers.JetStreamConfig
+	logLevel      string
+	port          string
+	dbConfig      timescale.Config
+	channels      map[string]bool
+	batchSize     int
+	batchInterval time.Duration
+	enrichURL     string
+	enrichTimeout time.Duration
+	enrichBypass  bool
+	dlqSubject    string
+	dlqFile       string
+}
+
+func main() {
+	cfg := loadConfig()
+
+	logger, err := logger.New(os.Stdout, cfg.logLevel)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	nc := connectToNATS(cfg.natsConfig, logger)
+	defer nc.Close()
+
+	db := connectToDB(cfg.dbConfig, logger)
+	defer db.Close()
+
+	repo := newService(db, logger)
+	repo = writers.NewSubtopicTracker(repo, timescale.NewSubtopicRepository(db), logger)
+	if cfg.dlqSubject != "" || cfg.dlqFile != "" {
+		repo = writers.NewDeadLetterRepository(repo, newDeadLetters(cfg, nc), logger)
+	}
+	repo = writers.NewBatcher(repo, cfg.batchSize, cfg.batchInterval, logger)
+	if cfg.enrichURL != "" {
+		repo = newEnrichingRepository(repo, cfg, logger)
+	}
+
+	// Retention is enforced by the TimescaleDB retention policy, since the
+	// messages can't be deleted from the compressed chunks.
+	tracer, closer := tracing.Init(svcName, cfg.jaegerURL, logger)
+	defer closer.Close()
+
+	if err = startWriter(nc, repo, nil, cfg, tracer, logger); err != nil {
+		logger.Error(fmt.Sprintf("Failed to create Timescale writer: %s", err))
+	}
+
+	errs := make(chan error, 2)
+
+	go startHTTPServer(cfg.port, errs, logger)
+
+	go func() {
+		c := make(chan os.Signal, 1)
+		signal.Notify(c, syscall.SIGINT)
+		errs <- fmt.Errorf("%s", <-c)
+	}()
+
+	err = <-errs
+	logger.Error(fmt.Sprintf("Timescale writer service terminated: %s", err))
+}
+
+func loadConfig() config {
+	chunkInterval, err := time.ParseDuration(mainflux.Env(envChunkInterval, defChunkInterval))
+	if err != nil || chunkInterval <= 0 {
+		log.Fatalf("Invalid value passed for %s\n", envChunkInterval)
+	}
+
+	compressAfter, err := time.ParseDuration(mainflux.Env(envCompressAfter, defCompressAfter))
+	if err != nil || compressAfter < 0 {
+		log.Fatalf("Invalid value passed for %s\n", envCompressAfter)
+	}
+
+	retention, err := time.ParseDuration(mainflux.Env(envRetention, defRetention))
+	if err != nil || retention < 0 {
+		log.Fatalf("Invalid value passed for %s\n", envRetention)
+	}
+
+	chanCfgPath := mainflux.Env(envChanCfgPath, defChanCfgPath)
+	dbConfig := timescale.Config{
+		Host:          mainflux.Env(envDBHost, defDBHost),
+		Port:          mainflux.Env(envDBPort, defDBPort),
+		User:          mainflux.Env(envDBUser, defDBUser),
+		Pass:          mainflux.Env(envDBPass, defDBPass),
+		Name:          mainflux.Env(envDBName, defDBName),
+		SSLMode:       mainflux.Env(envDBSSLMode, defDBSSLMode),
+		SSLCert:       mainflux.Env(envDBSSLCert, defDBSSLCert),
+		SSLKey:        mainflux.Env(envDBSSLKey, defDBSSLKey),
+		SSLRootCert:   mainflux.Env(envDBSSLRootCert, defDBSSLRootCert),
+		ChunkInterval: chunkInterval,
+		CompressAfter: compressAfter,
+		RetainFor:     retention,
+	}
+
+	batchSize, err := strconv.Atoi(mainflux.Env(envBatchSize, defBatchSize))
+	if err != nil || batchSize <= 0 {
+		log.Fatalf("Invalid value passed for %s\n", envBatchSize)
+	}
+
+	batchInterval, err := time.ParseDuration(mainflux.Env(envBatchInterval, defBatchInterval))
+	if err != nil || batchInterval <= 0 {
+		log.Fatalf("Invalid value passed for %s\n", envBatchInterval)
+	}
+
+	enrichTimeout, err := time.ParseDuration(mainflux.Env(envEnrichTimeout, defEnrichTimeout))
+	if err != nil || enrichTimeout <= 0 {
+		log.Fatalf("Invalid value passed for %s\n", envEnrichTimeout)
+	}
+
+	enrichBypass, err := strconv.ParseBool(mainflux.Env(envEnrichBypass, defEnrichBypass))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envEnrichBypass)
+	}
+
+	jetStream, err := strconv.ParseBool(mainflux.Env(envNatsJetStream, defNatsJetStream))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envNatsJetStream)
+	}
+
+	streamMaxAge, err := time.ParseDuration(mainflux.Env(envNatsStreamMaxAge, defNatsStreamMaxAge))
+	if err != nil || streamMaxAge < 0 {
+		log.Fatalf("Invalid value passed for %s\n", envNatsStreamMaxAge)
+	}
+
+	ackWait, err := time.ParseDuration(mainflux.Env(envNatsAckWait, defNatsAckWait))
+	if err != nil || ackWait <= 0 {
+		log.Fatalf("Invalid value passed for %s\n", envNatsAckWait)
+	}
+
+	maxDeliver, err := strconv.Atoi(mainflux.Env(envNatsMaxDeliver, defNatsMaxDeliver))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envNatsMaxDeliver)
+	}
+
+	jsConfig := writers.JetStreamConfig{
+		Stream:     mainflux.Env(envNatsStream, defNatsStream),
+		MaxAge:     streamMaxAge,
+		AckWait:    ackWait,
+		MaxDeliver: maxDeliver,
+	}
+
+	natsConfig := mfnats.Config{
+		URL:        mainflux.Env(envNatsURL, defNatsURL),
+		Creds:      mainflux.Env(envNatsCreds, defNatsCreds),
+		NKeySeed:   mainflux.Env(envNatsNKeySeed, defNatsNKeySeed),
+		CACerts:    mainflux.Env(envNatsCACerts, defNatsCACerts),
+		ClientCert: mainflux.Env(envNatsClientCert, defNatsClientCert),
+		ClientKey:  mainflux.Env(envNatsClientKey, defNatsClientKey),
+		Prefix:     mainflux.Env(envNatsSubjectPrefix, defNatsSubjectPrefix),
+	}
+
+	return config{
+		natsConfig:    natsConfig,
+		jaegerURL:     mainflux.Env(envJaegerURL, defJaegerURL),
+		jetStream:     jetStream,
+		jsConfig:      jsConfig,
+		logLevel:      mainflux.Env(envLogLevel, defLogLevel),
+		port:          mainflux.Env(envPort, defPort),
+		dbConfig:      dbConfig,
+		channels:      loadChansConfig(chanCfgPath),
+		batchSize:     batchSize,
+		batchInterval: batchInterval,
+		enrichURL:     mainflux.Env(envEnrichURL, defEnrichURL),
+		enrichTimeout: enrichTimeout,
+		enrichBypass:  enrichBypass,
+		dlqSubject:    mainflux.Env(envDLQSubject, defDLQSubject),
+		dlqFile:       mainflux.Env(envDLQFile, defDLQFile),
+	}
+}
+
+type channels struct {
+	List []string `toml:"filter"`
+}
+
+type chanConfig struct {
+	Channels channels `toml:"channels"`
+}
+
+func loadChansConfig(chanConfigPath string) map[string]bool {
+	data, err := ioutil.ReadFile(chanConfigPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var chanCfg chanConfig
+	if err := toml.Unmarshal(data, &chanCfg); err != nil {
+		log.Fatal(err)
+	}
+
+	chans := map[string]bool{}
+	for _, ch := range chanCfg.Channels.List {
+		chans[ch] = true
+	}
+
+	return chans
+}
+
+func connectToNATS(cfg mfnats.Config, logger logger.Logger) *nats.Conn {
+	nc, err := mfnats.Connect(cfg)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to NATS: %s", err))
+		os.Exit(1)
+	}
+
+	return nc
+}
+
+func connectToDB(dbConfig timescale.Config, logger logger.Logger) *sqlx.DB {
+	db, err := timescale.Connect(dbConfig)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to Timescale: %s", err))
+		os.Exit(1)
+	}
+	return db
+}
+
+func newService(db *sqlx.DB, logger logger.Logger) writers.MessageRepository {
+	svc := timescale.New(db)
+	svc = api.LoggingMiddleware(svc, logger)
+	svc = api.MetricsMiddleware(
+		svc,
+		kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: "timescale",
+			Subsystem: "message_writer",
+			Name:      "request_count",
+			Help:      "Number of requests received.",
+		}, []string{"method"}),
+		kitprometheus.NewSummaryFrom(stdprometheus.SummaryOpts{
+			Namespace: "timescale",
+			Subsystem: "message_writer",
+			Name:      "request_latency_microseconds",
+			Help:      "Total duration of requests in microseconds.",
+		}, []string{"method"}),
+	)
+
+	return svc
+}
+
+func startHTTPServer(port string, errs chan error, logger logger.Logger) {
+	p := fmt.Sprintf(":%s", port)
+	logger.Info(fmt.Sprintf("Timescale writer service started, exposed port %s", port))
+	errs <- http.ListenAndServe(p, api.MakeHandler(svcName))
+}
+
+func newDeadLetters(cfg config, nc *nats.Conn) writers.DeadLetters {
+	if cfg.dlqSubject != "" {
+		return deadletter.NewNATSDeadLetters(nc, mfnats.Subject(cfg.natsConfig.Prefix, cfg.dlqSubject))
+	}
+
+	return deadletter.NewFileDeadLetters(cfg.dlqFile)
+}
+
+func newEnrichingRepository(repo writers.MessageRepository, cfg config, logger logger.Logger) writers.MessageRepository {
+	enricher, err := enrich.New(cfg.enrichURL, cfg.enrichTimeout)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to create message enricher: %s", err))
+		os.Exit(1)
+	}
+
+	return writers.NewEnrichingRepository(repo, enricher, cfg.enrichTimeout, cfg.enrichBypass, logger)
+}
+
+func startWriter(nc *nats.Conn, repo writers.MessageRepository, retainer writers.Retainer, cfg config, tracer opentracing.Tracer, logger logger.Logger) error {
+	if !cfg.jetStream {
+		return writers.Start(nc, cfg.natsConfig.Prefix, repo, retainer, svcName, cfg.channels, tracer, logger)
+	}
+
+	lag := kitprometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+		Namespace: "timescale",
+		Subsystem: "message_writer",
+		Name:      "consumer_lag",
+		Help:      "Number of messages in the stream not delivered to the writer yet.",
+	}, []string{})
+
+	return writers.StartJetStream(nc, cfg.jsConfig, cfg.natsConfig.Prefix, repo, retainer, svcName, cfg.channels, lag, tracer, logger)
+}
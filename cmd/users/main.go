@@ -12,23 +12,33 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
+	"time"
 
 	"github.com/mainflux/mainflux/users/tracing"
 
 	"google.golang.org/grpc/credentials"
 
 	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	"github.com/go-redis/redis"
 	"github.com/jmoiron/sqlx"
 	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/logger"
+	mfredis "github.com/mainflux/mainflux/redis"
 	"github.com/mainflux/mainflux/users"
 	"github.com/mainflux/mainflux/users/api"
 	grpcapi "github.com/mainflux/mainflux/users/api/grpc"
 	httpapi "github.com/mainflux/mainflux/users/api/http"
+	"github.com/mainflux/mainflux/users/argon2"
 	"github.com/mainflux/mainflux/users/bcrypt"
 	"github.com/mainflux/mainflux/users/jwt"
 	"github.com/mainflux/mainflux/users/postgres"
+	"github.com/mainflux/mainflux/users/pwned"
+	rediscache "github.com/mainflux/mainflux/users/redis"
+	"github.com/mainflux/mainflux/users/totp"
+	"github.com/mainflux/mainflux/users/uuid"
 	opentracing "github.com/opentracing/opentracing-go"
 	stdprometheus "github.com/prometheus/client_golang/prometheus"
 	jconfig "github.com/uber/jaeger-client-go/config"
@@ -52,6 +62,24 @@ const (
 	defServerCert    = ""
 	defServerKey     = ""
 	defJaegerURL     = ""
+	defHasher        = "bcrypt"
+	defArgon2Time    = "1"
+	defArgon2Memory  = "65536"
+	defArgon2Threads = "4"
+	defAdmins        = ""
+	defPassMinLength = "0"
+	defPassUpper     = "false"
+	defPassLower     = "false"
+	defPassDigit     = "false"
+	defPassSymbol    = "false"
+	defPwnedURL      = ""
+	defPwnedTimeout  = "2" // in seconds
+	defLockAttempts  = "0"
+	defLockDuration  = "60"   // in seconds
+	defLockMax       = "3600" // in seconds
+	defCacheURL      = "localhost:6379"
+	defCachePass     = ""
+	defCacheDB       = "0"
 
 	envLogLevel      = "MF_USERS_LOG_LEVEL"
 	envDBHost        = "MF_USERS_DB_HOST"
@@ -69,17 +97,48 @@ const (
 	envServerCert    = "MF_USERS_SERVER_CERT"
 	envServerKey     = "MF_USERS_SERVER_KEY"
 	envJaegerURL     = "MF_JAEGER_URL"
+	envHasher        = "MF_USERS_HASHER"
+	envArgon2Time    = "MF_USERS_ARGON2_TIME"
+	envArgon2Memory  = "MF_USERS_ARGON2_MEMORY"
+	envArgon2Threads = "MF_USERS_ARGON2_THREADS"
+	envAdmins        = "MF_USERS_ADMINS"
+	envPassMinLength = "MF_USERS_PASSWORD_MIN_LENGTH"
+	envPassUpper     = "MF_USERS_PASSWORD_REQUIRE_UPPER"
+	envPassLower     = "MF_USERS_PASSWORD_REQUIRE_LOWER"
+	envPassDigit     = "MF_USERS_PASSWORD_REQUIRE_DIGIT"
+	envPassSymbol    = "MF_USERS_PASSWORD_REQUIRE_SYMBOL"
+	envPwnedURL      = "MF_USERS_PWNED_URL"
+	envPwnedTimeout  = "MF_USERS_PWNED_TIMEOUT"
+	envLockAttempts  = "MF_USERS_LOCKOUT_ATTEMPTS"
+	envLockDuration  = "MF_USERS_LOCKOUT_DURATION"
+	envLockMax       = "MF_USERS_LOCKOUT_MAX_DURATION"
+	envCacheURL      = "MF_USERS_CACHE_URL"
+	envCachePass     = "MF_USERS_CACHE_PASS"
+	envCacheDB       = "MF_USERS_CACHE_DB"
+
+	hasherBcrypt   = "bcrypt"
+	hasherArgon2id = "argon2id"
 )
 
 type config struct {
-	logLevel   string
-	dbConfig   postgres.Config
-	httpPort   string
-	grpcPort   string
-	secret     string
-	serverCert string
-	serverKey  string
-	jaegerURL  string
+	logLevel     string
+	dbConfig     postgres.Config
+	httpPort     string
+	grpcPort     string
+	secret       string
+	serverCert   string
+	serverKey    string
+	jaegerURL    string
+	hasher       string
+	argon2       argon2.Config
+	admins       []string
+	policy       users.PasswordPolicy
+	pwnedURL     string
+	pwnedTimeout time.Duration
+	lockout      users.LockoutPolicy
+	cacheURL     string
+	cachePass    string
+	cacheDB      string
 }
 
 func main() {
@@ -99,7 +158,18 @@ func main() {
 	dbTracer, dbCloser := initJaeger("users_db", cfg.jaegerURL, logger)
 	defer dbCloser.Close()
 
-	svc := newService(db, dbTracer, cfg.secret, logger)
+	// Redis is used for tracking the failed logins only, so it's not
+	// required unless the lockout is enabled.
+	var cacheClient redis.UniversalClient
+	if cfg.lockout.Attempts > 0 {
+		cacheClient = connectToRedis(cfg.cacheURL, cfg.cachePass, cfg.cacheDB, logger)
+		defer cacheClient.Close()
+	}
+
+	cacheTracer, cacheCloser := initJaeger("users_cache", cfg.jaegerURL, logger)
+	defer cacheCloser.Close()
+
+	svc := newService(db, dbTracer, cacheClient, cacheTracer, cfg, logger)
 	errs := make(chan error, 2)
 
 	go startHTTPServer(tracer, svc, cfg.httpPort, cfg.serverCert, cfg.serverKey, logger, errs)
@@ -128,6 +198,60 @@ func loadConfig() config {
 		SSLRootCert: mainflux.Env(envDBSSLRootCert, defDBSSLRootCert),
 	}
 
+	hasher := mainflux.Env(envHasher, defHasher)
+	if hasher != hasherBcrypt && hasher != hasherArgon2id {
+		log.Fatalf("Invalid %s value: %s", envHasher, hasher)
+	}
+
+	argon2Time, err := strconv.ParseUint(mainflux.Env(envArgon2Time, defArgon2Time), 10, 32)
+	if err != nil || argon2Time == 0 {
+		log.Fatalf("Invalid %s value", envArgon2Time)
+	}
+
+	argon2Memory, err := strconv.ParseUint(mainflux.Env(envArgon2Memory, defArgon2Memory), 10, 32)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envArgon2Memory, err.Error())
+	}
+
+	argon2Threads, err := strconv.ParseUint(mainflux.Env(envArgon2Threads, defArgon2Threads), 10, 8)
+	if err != nil || argon2Threads == 0 {
+		log.Fatalf("Invalid %s value", envArgon2Threads)
+	}
+
+	var admins []string
+	for _, admin := range strings.Split(mainflux.Env(envAdmins, defAdmins), ",") {
+		if admin = strings.TrimSpace(admin); admin != "" {
+			admins = append(admins, admin)
+		}
+	}
+
+	policy := users.PasswordPolicy{
+		MinLength:     parseInt(envPassMinLength, defPassMinLength),
+		RequireUpper:  parseBool(envPassUpper, defPassUpper),
+		RequireLower:  parseBool(envPassLower, defPassLower),
+		RequireDigit:  parseBool(envPassDigit, defPassDigit),
+		RequireSymbol: parseBool(envPassSymbol, defPassSymbol),
+	}
+
+	pwnedTimeout := parseInt(envPwnedTimeout, defPwnedTimeout)
+
+	lockAttempts, err := strconv.ParseUint(mainflux.Env(envLockAttempts, defLockAttempts), 10, 32)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envLockAttempts, err.Error())
+	}
+
+	lockout := users.LockoutPolicy{
+		Attempts:    lockAttempts,
+		Duration:    time.Duration(parseInt(envLockDuration, defLockDuration)) * time.Second,
+		MaxDuration: time.Duration(parseInt(envLockMax, defLockMax)) * time.Second,
+	}
+	if lockout.Attempts > 0 && lockout.Duration == 0 {
+		log.Fatalf("Invalid %s value", envLockDuration)
+	}
+	if lockout.Attempts > 0 && lockout.MaxDuration < lockout.Duration {
+		log.Fatalf("Invalid %s value: shorter than %s", envLockMax, envLockDuration)
+	}
+
 	return config{
 		logLevel:   mainflux.Env(envLogLevel, defLogLevel),
 		dbConfig:   dbConfig,
@@ -137,9 +261,41 @@ func loadConfig() config {
 		serverCert: mainflux.Env(envServerCert, defServerCert),
 		serverKey:  mainflux.Env(envServerKey, defServerKey),
 		jaegerURL:  mainflux.Env(envJaegerURL, defJaegerURL),
+		hasher:     hasher,
+		argon2: argon2.Config{
+			Time:    uint32(argon2Time),
+			Memory:  uint32(argon2Memory),
+			Threads: uint8(argon2Threads),
+		},
+		admins:       admins,
+		policy:       policy,
+		pwnedURL:     mainflux.Env(envPwnedURL, defPwnedURL),
+		pwnedTimeout: time.Duration(pwnedTimeout) * time.Second,
+		lockout:      lockout,
+		cacheURL:     mainflux.Env(envCacheURL, defCacheURL),
+		cachePass:    mainflux.Env(envCachePass, defCachePass),
+		cacheDB:      mainflux.Env(envCacheDB, defCacheDB),
 	}
 }
 
+func parseInt(key, fallback string) int {
+	value, err := strconv.ParseUint(mainflux.Env(key, fallback), 10, 32)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", key, err.Error())
+	}
+
+	return int(value)
+}
+
+func parseBool(key, fallback string) bool {
+	value, err := strconv.ParseBool(mainflux.Env(key, fallback))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", key, err.Error())
+	}
+
+	return value
+}
+
 func initJaeger(svcName, url string, logger logger.Logger) (opentracing.Tracer, io.Closer) {
 	if url == "" {
 		return opentracing.NoopTracer{}, ioutil.NopCloser(nil)
@@ -173,13 +329,46 @@ func connectToDB(dbConfig postgres.Config, logger logger.Logger) *sqlx.DB {
 	return db
 }
 
-func newService(db *sqlx.DB, tracer opentracing.Tracer, secret string, logger logger.Logger) users.Service {
+func connectToRedis(cacheURL, cachePass, cacheDB string, logger logger.Logger) redis.UniversalClient {
+	db, err := strconv.Atoi(cacheDB)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to cache: %s", err))
+		os.Exit(1)
+	}
+
+	client, err := mfredis.Connect(cacheURL, cachePass, db)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to cache: %s", err))
+		os.Exit(1)
+	}
+
+	return client
+}
+
+func newService(db *sqlx.DB, tracer opentracing.Tracer, cacheClient redis.UniversalClient, cacheTracer opentracing.Tracer, cfg config, logger logger.Logger) users.Service {
 	database := postgres.NewDatabase(db)
 	repo := tracing.UserRepositoryMiddleware(postgres.New(database), tracer)
-	hasher := bcrypt.New()
-	idp := jwt.New(secret)
+	groups := tracing.GroupRepositoryMiddleware(postgres.NewGroupRepository(database), tracer)
+	keys := tracing.KeyRepositoryMiddleware(postgres.NewKeyRepository(database), tracer)
+	roles := tracing.RoleRepositoryMiddleware(postgres.NewRoleRepository(database), tracer)
+	orgs := tracing.OrgRepositoryMiddleware(postgres.NewOrgRepository(database), tracer)
+	totps := tracing.TOTPRepositoryMiddleware(postgres.NewTOTPRepository(database), tracer)
+	hasher := newHasher(cfg)
+	idp := jwt.New(cfg.secret)
+	uuidp := uuid.New()
+	otpp := totp.New()
+
+	var breaches users.BreachChecker
+	if cfg.pwnedURL != "" {
+		breaches = pwned.New(cfg.pwnedURL, cfg.pwnedTimeout)
+	}
 
-	svc := users.New(repo, hasher, idp)
+	var attempts users.LoginAttempts
+	if cacheClient != nil {
+		attempts = tracing.LoginAttemptsMiddleware(rediscache.NewLoginAttempts(cacheClient), cacheTracer)
+	}
+
+	svc := users.New(repo, groups, keys, roles, orgs, totps, cfg.admins, hasher, idp, uuidp, otpp, cfg.policy, breaches, cfg.lockout, attempts)
 	svc = api.LoggingMiddleware(svc, logger)
 	svc = api.MetricsMiddleware(
 		svc,
@@ -199,6 +388,16 @@ func newService(db *sqlx.DB, tracer opentracing.Tracer, secret string, logger lo
 	return svc
 }
 
+// newHasher creates the configured hasher. Argon2id hasher keeps verifying
+// the bcrypt hashes and replaces them on login.
+func newHasher(cfg config) users.Hasher {
+	if cfg.hasher == hasherArgon2id {
+		return argon2.New(cfg.argon2, bcrypt.New())
+	}
+
+	return bcrypt.New()
+}
+
 func startHTTPServer(tracer opentracing.Tracer, svc users.Service, port string, certFile string, keyFile string, logger logger.Logger, errs chan error) {
 	p := fmt.Sprintf(":%s", port)
 	if certFile != "" || keyFile != "" {
@@ -4,9 +4,8 @@
 package main
 
 import (
+	"context"
 	"fmt"
-	"io"
-	"io/ioutil"
 	"log"
 	"net/http"
 	"os"
@@ -16,49 +15,178 @@ import (
 	"time"
 
 	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	"github.com/go-redis/redis"
 	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/logger"
+	mfnats "github.com/mainflux/mainflux/nats"
+	"github.com/mainflux/mainflux/ordering"
+	orderingredis "github.com/mainflux/mainflux/ordering/redis"
+	"github.com/mainflux/mainflux/payload"
+	"github.com/mainflux/mainflux/pkg/events/consumer"
+	"github.com/mainflux/mainflux/presence"
+	"github.com/mainflux/mainflux/presence/redis/producer"
+	mfredis "github.com/mainflux/mainflux/redis"
+	"github.com/mainflux/mainflux/routing"
+	routingnats "github.com/mainflux/mainflux/routing/nats"
+	"github.com/mainflux/mainflux/sessions"
+	sessionsredis "github.com/mainflux/mainflux/sessions/redis"
+	"github.com/mainflux/mainflux/things/api/auth/cache"
+	cacheredis "github.com/mainflux/mainflux/things/api/auth/cache/redis"
+	"github.com/mainflux/mainflux/things/api/auth/callback"
 	thingsapi "github.com/mainflux/mainflux/things/api/auth/grpc"
+	"github.com/mainflux/mainflux/tracing"
+	"github.com/mainflux/mainflux/writers/s3"
 	adapter "github.com/mainflux/mainflux/ws"
 	"github.com/mainflux/mainflux/ws/api"
 	"github.com/mainflux/mainflux/ws/nats"
-	broker "github.com/nats-io/go-nats"
-	opentracing "github.com/opentracing/opentracing-go"
+	broker "github.com/nats-io/nats.go"
 	stdprometheus "github.com/prometheus/client_golang/prometheus"
-	jconfig "github.com/uber/jaeger-client-go/config"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 )
 
 const (
-	defClientTLS     = "false"
-	defCACerts       = ""
-	defPort          = "8180"
-	defLogLevel      = "error"
-	defNatsURL       = broker.DefaultURL
-	defThingsURL     = "localhost:8181"
-	defJaegerURL     = ""
-	defThingsTimeout = "1" // in seconds
-
-	envClientTLS     = "MF_WS_ADAPTER_CLIENT_TLS"
-	envCACerts       = "MF_WS_ADAPTER_CA_CERTS"
-	envPort          = "MF_WS_ADAPTER_PORT"
-	envLogLevel      = "MF_WS_ADAPTER_LOG_LEVEL"
-	envNatsURL       = "MF_NATS_URL"
-	envThingsURL     = "MF_THINGS_URL"
-	envJaegerURL     = "MF_JAEGER_URL"
-	envThingsTimeout = "MF_WS_ADAPTER_THINGS_TIMEOUT"
+	defClientTLS         = "false"
+	defCACerts           = ""
+	defPort              = "8180"
+	defLogLevel          = "error"
+	defNatsURL           = broker.DefaultURL
+	defNatsCreds         = ""
+	defNatsNKeySeed      = ""
+	defNatsCACerts       = ""
+	defNatsClientCert    = ""
+	defNatsClientKey     = ""
+	defNatsSubjectPrefix = ""
+	defThingsURL         = "localhost:8181"
+	defJaegerURL         = ""
+	defThingsTimeout     = "1" // in seconds
+	defAuthCacheSize     = "0"
+	defAuthCacheTTL      = "1m"
+	defAuthCacheURL      = ""
+	defAuthCachePass     = ""
+	defAuthCacheDB       = "0"
+	defThingsESURL       = "localhost:6379"
+	defThingsESPass      = ""
+	defThingsESDB        = "0"
+	defCallbackURL       = ""
+	defCallbackTimeout   = "1" // in seconds
+	defRegion            = ""
+	defFederationLinks   = ""
+	defRegionRefresh     = "1m"
+	defLinkProbePeriod   = "10s"
+	defSequencerURL      = ""
+	defSequencerPass     = ""
+	defSequencerDB       = "0"
+	defOrderingRefresh   = "1m"
+	defMaxPayload        = "0"
+	defOversizePolicy    = "reject"
+	defSpillEndpoint     = ""
+	defSpillRegion       = "us-east-1"
+	defSpillBucket       = "spilled-payloads"
+	defSpillAccessKey    = ""
+	defSpillSecretKey    = ""
+	defSpillTimeout      = "10s"
+	defMaxThingConns     = "0"
+	defMaxOwnerConns     = "0"
+	defSessionsURL       = "localhost:6379"
+	defSessionsPass      = ""
+	defSessionsDB        = "0"
+	defChannelAliases    = "false"
+	defESURL             = ""
+	defESPass            = ""
+	defESDB              = "0"
+
+	envClientTLS         = "MF_WS_ADAPTER_CLIENT_TLS"
+	envCACerts           = "MF_WS_ADAPTER_CA_CERTS"
+	envPort              = "MF_WS_ADAPTER_PORT"
+	envLogLevel          = "MF_WS_ADAPTER_LOG_LEVEL"
+	envNatsURL           = "MF_NATS_URL"
+	envNatsCreds         = "MF_NATS_CREDS"
+	envNatsNKeySeed      = "MF_NATS_NKEY_SEED"
+	envNatsCACerts       = "MF_NATS_CA_CERTS"
+	envNatsClientCert    = "MF_NATS_CLIENT_CERT"
+	envNatsClientKey     = "MF_NATS_CLIENT_KEY"
+	envNatsSubjectPrefix = "MF_NATS_SUBJECT_PREFIX"
+	envThingsURL         = "MF_THINGS_URL"
+	envJaegerURL         = "MF_JAEGER_URL"
+	envThingsTimeout     = "MF_WS_ADAPTER_THINGS_TIMEOUT"
+	envAuthCacheSize     = "MF_WS_ADAPTER_AUTH_CACHE_SIZE"
+	envAuthCacheTTL      = "MF_WS_ADAPTER_AUTH_CACHE_TTL"
+	envAuthCacheURL      = "MF_WS_ADAPTER_AUTH_CACHE_URL"
+	envAuthCachePass     = "MF_WS_ADAPTER_AUTH_CACHE_PASS"
+	envAuthCacheDB       = "MF_WS_ADAPTER_AUTH_CACHE_DB"
+	envThingsESURL       = "MF_WS_ADAPTER_THINGS_ES_URL"
+	envThingsESPass      = "MF_WS_ADAPTER_THINGS_ES_PASS"
+	envThingsESDB        = "MF_WS_ADAPTER_THINGS_ES_DB"
+	envCallbackURL       = "MF_WS_ADAPTER_AUTH_CALLBACK_URL"
+	envCallbackTimeout   = "MF_WS_ADAPTER_AUTH_CALLBACK_TIMEOUT"
+	envRegion            = "MF_WS_ADAPTER_REGION"
+	envFederationLinks   = "MF_WS_ADAPTER_FEDERATION_LINKS"
+	envRegionRefresh     = "MF_WS_ADAPTER_REGION_REFRESH"
+	envLinkProbePeriod   = "MF_WS_ADAPTER_LINK_PROBE_PERIOD"
+	envSequencerURL      = "MF_WS_ADAPTER_SEQUENCER_URL"
+	envSequencerPass     = "MF_WS_ADAPTER_SEQUENCER_PASS"
+	envSequencerDB       = "MF_WS_ADAPTER_SEQUENCER_DB"
+	envOrderingRefresh   = "MF_WS_ADAPTER_ORDERING_REFRESH"
+	envMaxPayload        = "MF_WS_ADAPTER_MAX_PAYLOAD"
+	envOversizePolicy    = "MF_WS_ADAPTER_OVERSIZE_POLICY"
+	envSpillEndpoint     = "MF_WS_ADAPTER_SPILL_S3_ENDPOINT"
+	envSpillRegion       = "MF_WS_ADAPTER_SPILL_S3_REGION"
+	envSpillBucket       = "MF_WS_ADAPTER_SPILL_S3_BUCKET"
+	envSpillAccessKey    = "MF_WS_ADAPTER_SPILL_S3_ACCESS_KEY"
+	envSpillSecretKey    = "MF_WS_ADAPTER_SPILL_S3_SECRET_KEY"
+	envSpillTimeout      = "MF_WS_ADAPTER_SPILL_S3_TIMEOUT"
+	envMaxThingConns     = "MF_WS_ADAPTER_MAX_THING_CONNS"
+	envMaxOwnerConns     = "MF_WS_ADAPTER_MAX_OWNER_CONNS"
+	envSessionsURL       = "MF_WS_ADAPTER_SESSIONS_URL"
+	envSessionsPass      = "MF_WS_ADAPTER_SESSIONS_PASS"
+	envSessionsDB        = "MF_WS_ADAPTER_SESSIONS_DB"
+	envChannelAliases    = "MF_WS_ADAPTER_CHANNEL_ALIASES"
+	envESURL             = "MF_WS_ADAPTER_ES_URL"
+	envESPass            = "MF_WS_ADAPTER_ES_PASS"
+	envESDB              = "MF_WS_ADAPTER_ES_DB"
+
+	eventStream = "mainflux.ws"
 )
 
 type config struct {
-	clientTLS     bool
-	caCerts       string
-	thingsURL     string
-	natsURL       string
-	logLevel      string
-	port          string
-	jaegerURL     string
-	thingsTimeout time.Duration
+	clientTLS       bool
+	caCerts         string
+	thingsURL       string
+	natsConfig      mfnats.Config
+	logLevel        string
+	port            string
+	jaegerURL       string
+	thingsTimeout   time.Duration
+	authCacheSize   int
+	authCacheTTL    time.Duration
+	authCacheURL    string
+	authCachePass   string
+	authCacheDB     string
+	thingsESURL     string
+	thingsESPass    string
+	thingsESDB      string
+	callbackURL     string
+	callbackTimeout time.Duration
+	region          string
+	federationLinks map[string][]string
+	regionRefresh   time.Duration
+	linkProbePeriod time.Duration
+	sequencerURL    string
+	sequencerPass   string
+	sequencerDB     string
+	orderingRefresh time.Duration
+	payload         payload.Config
+	spillConfig     s3.Config
+	spillTimeout    time.Duration
+	limits          sessions.Limits
+	sessionsURL     string
+	sessionsPass    string
+	sessionsDB      string
+	channelAliases  bool
+	esURL           string
+	esPass          string
+	esDB            string
 }
 
 func main() {
@@ -69,7 +197,7 @@ func main() {
 		log.Fatalf(err.Error())
 	}
 
-	nc, err := broker.Connect(cfg.natsURL)
+	nc, err := mfnats.Connect(cfg.natsConfig)
 	if err != nil {
 		logger.Error(fmt.Sprintf("Failed to connect to NATS: %s", err))
 		os.Exit(1)
@@ -79,19 +207,52 @@ func main() {
 	conn := connectToThings(cfg, logger)
 	defer conn.Close()
 
-	thingsTracer, thingsCloser := initJaeger("things", cfg.jaegerURL, logger)
+	tracer, closer := tracing.Init("ws_adapter", cfg.jaegerURL, logger)
+	defer closer.Close()
+
+	thingsTracer, thingsCloser := tracing.Init("things", cfg.jaegerURL, logger)
 	defer thingsCloser.Close()
 
 	cc := thingsapi.NewClient(conn, thingsTracer, cfg.thingsTimeout)
-	pubsub := nats.New(nc, logger)
+	if cfg.authCacheSize > 0 || cfg.authCacheURL != "" {
+		cc = newAuthCache(cfg, cc, logger)
+	}
+	cc = callback.NewClient(cc, cfg.callbackURL, cfg.callbackTimeout)
+	pubsub := nats.New(nc, cfg.natsConfig.Prefix, logger)
+	if cfg.region != "" {
+		pubsub = routedPubSub{Service: pubsub, router: newRouter(cfg, pubsub, cc, logger)}
+	}
+	if cfg.sequencerURL != "" {
+		seqClient := connectToRedis(cfg.sequencerURL, cfg.sequencerPass, cfg.sequencerDB, "sequencer", logger)
+		defer seqClient.Close()
+		pubsub = sequencedPubSub{Service: pubsub, pub: ordering.New(pubsub, cc, orderingredis.NewSequencer(seqClient), cfg.orderingRefresh, logger)}
+	}
+	if cfg.payload.MaxSize > 0 {
+		pubsub = limitedPubSub{Service: pubsub, pub: newPayloadPublisher(cfg, pubsub, logger)}
+	}
+	pubsub = tracedPubSub{Service: pubsub, pub: tracing.NewPublisher(pubsub, tracer, "publish")}
 	svc := newService(pubsub, logger)
 
+	var counter sessions.Counter
+	if cfg.limits.Thing > 0 || cfg.limits.Owner > 0 {
+		sessionsClient := connectToRedis(cfg.sessionsURL, cfg.sessionsPass, cfg.sessionsDB, "sessions", logger)
+		defer sessionsClient.Close()
+		counter = sessionsredis.NewCounter(sessionsClient, cfg.limits)
+	}
+
+	var events presence.EventPublisher
+	if cfg.esURL != "" {
+		esClient := connectToRedis(cfg.esURL, cfg.esPass, cfg.esDB, "event", logger)
+		defer esClient.Close()
+		events = producer.NewPublisher(esClient, eventStream)
+	}
+
 	errs := make(chan error, 2)
 
 	go func() {
 		p := fmt.Sprintf(":%s", cfg.port)
 		logger.Info(fmt.Sprintf("WebSocket adapter service started, exposed port %s", cfg.port))
-		errs <- http.ListenAndServe(p, api.MakeHandler(svc, cc, logger))
+		errs <- http.ListenAndServe(p, api.MakeHandler(svc, cc, counter, events, cfg.channelAliases, logger))
 	}()
 
 	go func() {
@@ -115,15 +276,127 @@ func loadConfig() config {
 		log.Fatalf("Invalid %s value: %s", envThingsTimeout, err.Error())
 	}
 
+	authCacheSize, err := strconv.Atoi(mainflux.Env(envAuthCacheSize, defAuthCacheSize))
+	if err != nil || authCacheSize < 0 {
+		log.Fatalf("Invalid value passed for %s\n", envAuthCacheSize)
+	}
+
+	authCacheTTL, err := time.ParseDuration(mainflux.Env(envAuthCacheTTL, defAuthCacheTTL))
+	if err != nil || authCacheTTL <= 0 {
+		log.Fatalf("Invalid value passed for %s\n", envAuthCacheTTL)
+	}
+
+	cbTimeout, err := strconv.ParseInt(mainflux.Env(envCallbackTimeout, defCallbackTimeout), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envCallbackTimeout, err.Error())
+	}
+
+	maxThingConns, err := strconv.Atoi(mainflux.Env(envMaxThingConns, defMaxThingConns))
+	if err != nil || maxThingConns < 0 {
+		log.Fatalf("Invalid value passed for %s\n", envMaxThingConns)
+	}
+
+	maxOwnerConns, err := strconv.Atoi(mainflux.Env(envMaxOwnerConns, defMaxOwnerConns))
+	if err != nil || maxOwnerConns < 0 {
+		log.Fatalf("Invalid value passed for %s\n", envMaxOwnerConns)
+	}
+
+	links, err := routing.ParseLinks(mainflux.Env(envFederationLinks, defFederationLinks))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envFederationLinks, err.Error())
+	}
+
+	regionRefresh, err := time.ParseDuration(mainflux.Env(envRegionRefresh, defRegionRefresh))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envRegionRefresh, err.Error())
+	}
+
+	linkProbePeriod, err := time.ParseDuration(mainflux.Env(envLinkProbePeriod, defLinkProbePeriod))
+	if err != nil || linkProbePeriod <= 0 {
+		log.Fatalf("Invalid value passed for %s\n", envLinkProbePeriod)
+	}
+
+	orderingRefresh, err := time.ParseDuration(mainflux.Env(envOrderingRefresh, defOrderingRefresh))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envOrderingRefresh, err.Error())
+	}
+
+	aliases, err := strconv.ParseBool(mainflux.Env(envChannelAliases, defChannelAliases))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envChannelAliases)
+	}
+
+	natsConfig := mfnats.Config{
+		URL:        mainflux.Env(envNatsURL, defNatsURL),
+		Creds:      mainflux.Env(envNatsCreds, defNatsCreds),
+		NKeySeed:   mainflux.Env(envNatsNKeySeed, defNatsNKeySeed),
+		CACerts:    mainflux.Env(envNatsCACerts, defNatsCACerts),
+		ClientCert: mainflux.Env(envNatsClientCert, defNatsClientCert),
+		ClientKey:  mainflux.Env(envNatsClientKey, defNatsClientKey),
+		Prefix:     mainflux.Env(envNatsSubjectPrefix, defNatsSubjectPrefix),
+	}
+
+	maxPayload, err := strconv.Atoi(mainflux.Env(envMaxPayload, defMaxPayload))
+	if err != nil || maxPayload < 0 {
+		log.Fatalf("Invalid value passed for %s\n", envMaxPayload)
+	}
+
+	policy, err := payload.ParsePolicy(mainflux.Env(envOversizePolicy, defOversizePolicy))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envOversizePolicy, err.Error())
+	}
+
+	spillTimeout, err := time.ParseDuration(mainflux.Env(envSpillTimeout, defSpillTimeout))
+	if err != nil || spillTimeout <= 0 {
+		log.Fatalf("Invalid value passed for %s\n", envSpillTimeout)
+	}
+
+	spillConfig := s3.Config{
+		Endpoint:  mainflux.Env(envSpillEndpoint, defSpillEndpoint),
+		Region:    mainflux.Env(envSpillRegion, defSpillRegion),
+		Bucket:    mainflux.Env(envSpillBucket, defSpillBucket),
+		AccessKey: mainflux.Env(envSpillAccessKey, defSpillAccessKey),
+		SecretKey: mainflux.Env(envSpillSecretKey, defSpillSecretKey),
+	}
+
 	return config{
-		clientTLS:     tls,
-		caCerts:       mainflux.Env(envCACerts, defCACerts),
-		thingsURL:     mainflux.Env(envThingsURL, defThingsURL),
-		natsURL:       mainflux.Env(envNatsURL, defNatsURL),
-		logLevel:      mainflux.Env(envLogLevel, defLogLevel),
-		port:          mainflux.Env(envPort, defPort),
-		jaegerURL:     mainflux.Env(envJaegerURL, defJaegerURL),
-		thingsTimeout: time.Duration(timeout) * time.Second,
+		clientTLS:       tls,
+		caCerts:         mainflux.Env(envCACerts, defCACerts),
+		thingsURL:       mainflux.Env(envThingsURL, defThingsURL),
+		natsConfig:      natsConfig,
+		logLevel:        mainflux.Env(envLogLevel, defLogLevel),
+		port:            mainflux.Env(envPort, defPort),
+		jaegerURL:       mainflux.Env(envJaegerURL, defJaegerURL),
+		thingsTimeout:   time.Duration(timeout) * time.Second,
+		authCacheSize:   authCacheSize,
+		authCacheTTL:    authCacheTTL,
+		authCacheURL:    mainflux.Env(envAuthCacheURL, defAuthCacheURL),
+		authCachePass:   mainflux.Env(envAuthCachePass, defAuthCachePass),
+		authCacheDB:     mainflux.Env(envAuthCacheDB, defAuthCacheDB),
+		thingsESURL:     mainflux.Env(envThingsESURL, defThingsESURL),
+		thingsESPass:    mainflux.Env(envThingsESPass, defThingsESPass),
+		thingsESDB:      mainflux.Env(envThingsESDB, defThingsESDB),
+		callbackURL:     mainflux.Env(envCallbackURL, defCallbackURL),
+		callbackTimeout: time.Duration(cbTimeout) * time.Second,
+		region:          mainflux.Env(envRegion, defRegion),
+		federationLinks: links,
+		regionRefresh:   regionRefresh,
+		linkProbePeriod: linkProbePeriod,
+		sequencerURL:    mainflux.Env(envSequencerURL, defSequencerURL),
+		sequencerPass:   mainflux.Env(envSequencerPass, defSequencerPass),
+		sequencerDB:     mainflux.Env(envSequencerDB, defSequencerDB),
+		orderingRefresh: orderingRefresh,
+		payload:         payload.Config{MaxSize: maxPayload, Policy: policy},
+		spillConfig:     spillConfig,
+		spillTimeout:    spillTimeout,
+		limits:          sessions.Limits{Thing: maxThingConns, Owner: maxOwnerConns},
+		sessionsURL:     mainflux.Env(envSessionsURL, defSessionsURL),
+		sessionsPass:    mainflux.Env(envSessionsPass, defSessionsPass),
+		sessionsDB:      mainflux.Env(envSessionsDB, defSessionsDB),
+		channelAliases:  aliases,
+		esURL:           mainflux.Env(envESURL, defESURL),
+		esPass:          mainflux.Env(envESPass, defESPass),
+		esDB:            mainflux.Env(envESDB, defESDB),
 	}
 }
 
@@ -151,28 +424,55 @@ func connectToThings(cfg config, logger logger.Logger) *grpc.ClientConn {
 	return conn
 }
 
-func initJaeger(svcName, url string, logger logger.Logger) (opentracing.Tracer, io.Closer) {
-	if url == "" {
-		return opentracing.NoopTracer{}, ioutil.NopCloser(nil)
+func connectToRedis(redisURL, redisPass, redisDB, store string, logger logger.Logger) redis.UniversalClient {
+	db, err := strconv.Atoi(redisDB)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to %s store: %s", store, err))
+		os.Exit(1)
 	}
 
-	tracer, closer, err := jconfig.Configuration{
-		ServiceName: svcName,
-		Sampler: &jconfig.SamplerConfig{
-			Type:  "const",
-			Param: 1,
-		},
-		Reporter: &jconfig.ReporterConfig{
-			LocalAgentHostPort: url,
-			LogSpans:           true,
-		},
-	}.NewTracer()
+	client, err := mfredis.Connect(redisURL, redisPass, db)
 	if err != nil {
-		logger.Error(fmt.Sprintf("Failed to init Jaeger client: %s", err))
+		logger.Error(fmt.Sprintf("Failed to connect to %s store: %s", store, err))
 		os.Exit(1)
 	}
 
-	return tracer, closer
+	return client
+}
+
+// newAuthCache caches the accesses granted by the things service, keeping the
+// cache up to date by consuming the things events. The shared cache is
+// invalidated by a single instance of the group, while each instance keeping
+// its own cache reads all the events without the consumer group.
+func newAuthCache(cfg config, cc mainflux.ThingsServiceClient, logger logger.Logger) mainflux.ThingsServiceClient {
+	store := cache.NewLRUStore(cfg.authCacheSize, cfg.authCacheTTL)
+	var group, instance string
+	if cfg.authCacheURL != "" {
+		cacheClient := connectToRedis(cfg.authCacheURL, cfg.authCachePass, cfg.authCacheDB, "auth cache", logger)
+		store = cacheredis.NewStore(cacheClient, cfg.authCacheTTL)
+
+		group = "mainflux.ws-adapter.auth-cache"
+		var err error
+		if instance, err = os.Hostname(); err != nil {
+			instance = strconv.FormatInt(time.Now().UnixNano(), 10)
+		}
+	}
+	client := cache.NewClient(cc, store)
+
+	esClient := connectToRedis(cfg.thingsESURL, cfg.thingsESPass, cfg.thingsESDB, "things event", logger)
+	c := consumer.New(esClient, consumer.Config{
+		Stream:   consumer.ThingsStream,
+		Group:    group,
+		Consumer: instance,
+	}, logger)
+
+	go func() {
+		if err := c.Consume(context.Background(), cache.Handler(client)); err != nil {
+			logger.Error(fmt.Sprintf("Failed to consume things events: %s", err))
+		}
+	}()
+
+	return client
 }
 
 func newService(pubsub adapter.Service, logger logger.Logger) adapter.Service {
@@ -196,3 +496,97 @@ func newService(pubsub adapter.Service, logger logger.Logger) adapter.Service {
 
 	return svc
 }
+
+// newPayloadPublisher enforces the maximal payload size of the published
+// messages. Spill policy stores the oversized payloads to the object store.
+func newPayloadPublisher(cfg config, pub mainflux.MessagePublisher, logger logger.Logger) mainflux.MessagePublisher {
+	var store payload.Store
+	if cfg.payload.Policy == payload.Spill {
+		store = s3.NewClient(cfg.spillConfig, &http.Client{Timeout: cfg.spillTimeout})
+	}
+
+	violations := kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+		Namespace: "ws_adapter",
+		Subsystem: "payload",
+		Name:      "oversized_count",
+		Help:      "Number of messages exceeding the maximal payload size.",
+	}, []string{"policy"})
+
+	lp, err := payload.New(pub, cfg.payload, store, violations, logger)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to create payload size limiter: %s", err))
+		os.Exit(1)
+	}
+
+	return lp
+}
+
+func newRouter(cfg config, local mainflux.MessagePublisher, tc mainflux.ThingsServiceClient, logger logger.Logger) routing.Router {
+	links := make(map[string][]routing.Link)
+	for region, urls := range cfg.federationLinks {
+		for _, url := range urls {
+			nc, err := broker.Connect(url)
+			if err != nil {
+				logger.Error(fmt.Sprintf("Failed to connect to NATS of region %s: %s", region, err))
+				os.Exit(1)
+			}
+			links[region] = append(links[region], routingnats.NewLink(nc, cfg.natsConfig.Prefix, cfg.linkProbePeriod))
+		}
+	}
+
+	router := routing.New(cfg.region, local, links, tc, cfg.regionRefresh, logger)
+	go startProbing(router, cfg.linkProbePeriod, logger)
+	return router
+}
+
+func startProbing(router routing.Router, period time.Duration, logger logger.Logger) {
+	logger.Info(fmt.Sprintf("Probing federation links every %s", period))
+	for {
+		router.Probe()
+		time.Sleep(period)
+	}
+}
+
+// routedPubSub routes the published messages to the clusters of the channels
+// regions, while the subscriptions are served by the local broker.
+type routedPubSub struct {
+	adapter.Service
+	router routing.Router
+}
+
+func (ps routedPubSub) Publish(ctx context.Context, token string, msg mainflux.RawMessage) error {
+	return ps.router.Publish(ctx, token, msg)
+}
+
+// sequencedPubSub assigns the sequence numbers to the messages published to
+// the ordered channels, while the subscriptions are served unchanged.
+type sequencedPubSub struct {
+	adapter.Service
+	pub mainflux.MessagePublisher
+}
+
+func (ps sequencedPubSub) Publish(ctx context.Context, token string, msg mainflux.RawMessage) error {
+	return ps.pub.Publish(ctx, token, msg)
+}
+
+// limitedPubSub enforces the maximal payload size of the published messages,
+// while the subscriptions are served unchanged.
+type limitedPubSub struct {
+	adapter.Service
+	pub mainflux.MessagePublisher
+}
+
+func (ps limitedPubSub) Publish(ctx context.Context, token string, msg mainflux.RawMessage) error {
+	return ps.pub.Publish(ctx, token, msg)
+}
+
+// tracedPubSub traces the published messages, while the subscriptions are
+// served unchanged.
+type tracedPubSub struct {
+	adapter.Service
+	pub mainflux.MessagePublisher
+}
+
+func (ps tracedPubSub) Publish(ctx context.Context, token string, msg mainflux.RawMessage) error {
+	return ps.pub.Publish(ctx, token, msg)
+}
@@ -13,7 +13,8 @@ import (
 	"time"
 
 	"github.com/mainflux/mainflux"
-	broker "github.com/nats-io/go-nats"
+	"github.com/mainflux/mainflux/payload"
+	broker "github.com/nats-io/nats.go"
 )
 
 const (
@@ -126,6 +127,8 @@ func (svc *adapterService) Publish(ctx context.Context, token string, msg mainfl
 		switch err {
 		case broker.ErrConnectionClosed, broker.ErrInvalidConnection:
 			return ErrFailedConnection
+		case payload.ErrTooLarge:
+			return err
 		default:
 			return ErrFailedMessagePublish
 		}
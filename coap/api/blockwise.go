@@ -0,0 +1,217 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	gocoap "github.com/dustin/go-coap"
+)
+
+const (
+	block2 gocoap.OptionID = 23
+	block1 gocoap.OptionID = 27
+	size2  gocoap.OptionID = 28
+
+	// 2.31 Continue and 4.08 Request Entity Incomplete response codes.
+	continueCode            gocoap.COAPCode = 95
+	requestEntityIncomplete gocoap.COAPCode = 136
+
+	// Block size exponent of 1024 bytes blocks, used for the notifications
+	// unless the client requests smaller blocks.
+	defBlockSZX = 6
+	// maxBodySize limits the size of the payload sent in blocks.
+	maxBodySize = 64 * 1024
+	// exchangeLifetime is the EXCHANGE_LIFETIME of RFC 7252 section 4.8.2.
+	exchangeLifetime = 247 * time.Second
+)
+
+var (
+	errIncompleteEntity = errors.New("incomplete entity")
+	errEntityTooLarge   = errors.New("entity too large")
+)
+
+// block represents the value of the Block1 and Block2 options.
+type block struct {
+	num  uint32
+	more bool
+	szx  uint32
+}
+
+func (b block) size() int {
+	return 1 << (b.szx + 4)
+}
+
+func (b block) value() uint32 {
+	val := b.num<<4 | b.szx
+	if b.more {
+		val |= 0x8
+	}
+	return val
+}
+
+// blockOption returns the block option of the message, if present.
+func blockOption(msg *gocoap.Message, id gocoap.OptionID) (block, bool, error) {
+	val, ok := msg.Option(id).(uint32)
+	if !ok {
+		return block{}, false, nil
+	}
+
+	// Block size exponent 7 is reserved.
+	if val&0x7 == 7 {
+		return block{}, false, errBadOption
+	}
+
+	b := block{
+		num:  val >> 4,
+		more: val&0x8 != 0,
+		szx:  val & 0x7,
+	}
+	return b, true, nil
+}
+
+func exchangeKey(ep endpoint, msg *gocoap.Message) string {
+	return fmt.Sprintf("%s-%s-%v", ep, msg.PathString(), msg.Option(gocoap.URIQuery))
+}
+
+type transfer struct {
+	payload []byte
+	expires time.Time
+}
+
+// transfers keeps the payloads which are being sent in blocks using Block1
+// option, until the last block is received.
+type transfers struct {
+	mu     sync.Mutex
+	items  map[string]*transfer
+	pruned time.Time
+}
+
+func newTransfers() *transfers {
+	return &transfers{
+		items:  make(map[string]*transfer),
+		pruned: time.Now(),
+	}
+}
+
+// append adds the block to the transfer identified by the key, and returns
+// the whole payload once the last block is received.
+func (ts *transfers) append(key string, b block, payload []byte) ([]byte, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	now := time.Now()
+	ts.prune(now)
+
+	t, ok := ts.items[key]
+	switch {
+	case b.num == 0:
+		t = &transfer{}
+		ts.items[key] = t
+	case !ok:
+		return nil, errIncompleteEntity
+	case b.more && len(t.payload) == int(b.num+1)*b.size():
+		// Retransmitted block is already added.
+		return nil, nil
+	case len(t.payload) != int(b.num)*b.size():
+		delete(ts.items, key)
+		return nil, errIncompleteEntity
+	}
+
+	if b.more && len(payload) != b.size() {
+		delete(ts.items, key)
+		return nil, errBadRequest
+	}
+
+	if len(t.payload)+len(payload) > maxBodySize {
+		delete(ts.items, key)
+		return nil, errEntityTooLarge
+	}
+
+	t.payload = append(t.payload, payload...)
+	t.expires = now.Add(exchangeLifetime)
+	if b.more {
+		return nil, nil
+	}
+
+	delete(ts.items, key)
+	return t.payload, nil
+}
+
+func (ts *transfers) prune(now time.Time) {
+	if now.Sub(ts.pruned) < exchangeLifetime {
+		return
+	}
+
+	for key, t := range ts.items {
+		if now.After(t.expires) {
+			delete(ts.items, key)
+		}
+	}
+	ts.pruned = now
+}
+
+type representation struct {
+	payload []byte
+	ct      gocoap.MediaType
+	expires time.Time
+}
+
+// representations keeps the notifications which don't fit a single block,
+// so that the client can retrieve their remaining blocks using Block2
+// option (RFC 7959 section 2.4).
+type representations struct {
+	mu     sync.Mutex
+	items  map[string]representation
+	pruned time.Time
+}
+
+func newRepresentations() *representations {
+	return &representations{
+		items:  make(map[string]representation),
+		pruned: time.Now(),
+	}
+}
+
+func (rs *representations) put(key string, payload []byte, ct gocoap.MediaType) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	now := time.Now()
+	rs.prune(now)
+
+	rs.items[key] = representation{
+		payload: payload,
+		ct:      ct,
+		expires: now.Add(exchangeLifetime),
+	}
+}
+
+func (rs *representations) get(key string) (representation, bool) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	r, ok := rs.items[key]
+	if !ok || time.Now().After(r.expires) {
+		return representation{}, false
+	}
+
+	return r, true
+}
+
+func (rs *representations) prune(now time.Time) {
+	if now.Sub(rs.pruned) < exchangeLifetime {
+		return
+	}
+
+	for key, r := range rs.items {
+		if now.After(r.expires) {
+			delete(rs.items, key)
+		}
+	}
+	rs.pruned = now
+}
@@ -0,0 +1,329 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	gocoap "github.com/dustin/go-coap"
+	"github.com/mainflux/mainflux"
+	"github.com/pion/dtls"
+)
+
+const (
+	// maxDatagramSize is the size of the largest UDP datagram.
+	maxDatagramSize = 65535
+	// maxRecordSize is the size of the largest DTLS record payload.
+	maxRecordSize = 16384
+	// maxIdentityLen limits the length of the PSK identity.
+	maxIdentityLen = 128
+	// handshakeTimeout limits the duration of the DTLS handshake.
+	handshakeTimeout = 30 * time.Second
+	// sessionBuffer is the number of the datagrams buffered per session.
+	sessionBuffer = 32
+
+	// DTLS record content type and handshake message type of ClientHello.
+	handshakeRecord = 22
+	clientHello     = 1
+	recordHeaderLen = 13
+)
+
+var (
+	errSessionClosed  = errors.New("session closed")
+	errSessionExpired = errors.New("session expired")
+	errBadIdentity    = errors.New("bad PSK identity")
+
+	// CoAP mandates TLS_PSK_WITH_AES_128_CCM_8 (RFC 7252 section 9.1.3.1).
+	cipherSuites = []dtls.CipherSuiteID{
+		dtls.TLS_PSK_WITH_AES_128_CCM_8,
+		dtls.TLS_PSK_WITH_AES_128_GCM_SHA256,
+	}
+)
+
+// ListenAndServeDTLS binds to the given UDP address and serves CoAP requests
+// over DTLS 1.2 using the provided handler. Clients authenticate with the
+// pre-shared key, whose identity is the ID of the thing and which is derived
+// from the identity and the current key of the thing using the provided
+// secret, so the requests of the session are authorized as the requests of
+// that thing and don't pass its key. Since the key digest is retrieved from
+// the things service on each handshake, the new sessions of the removed
+// things are rejected and the rotated key invalidates the previous PSK.
+// Sessions already established stay authenticated until they expire.
+func ListenAndServeDTLS(addr string, secret []byte, h Handler) error {
+	uaddr, err := net.ResolveUDPAddr("udp", addr)
+	if err != nil {
+		return err
+	}
+
+	conn, err := net.ListenUDP("udp", uaddr)
+	if err != nil {
+		return err
+	}
+
+	ss := newSessions(conn)
+	buf := make([]byte, maxDatagramSize)
+	for {
+		n, raddr, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			if neterr, ok := err.(net.Error); ok && neterr.Timeout() {
+				time.Sleep(5 * time.Millisecond)
+				continue
+			}
+			return err
+		}
+
+		data := make([]byte, n)
+		copy(data, buf)
+
+		s, ok := ss.get(raddr)
+		if !ok {
+			// Only ClientHello starts the new session, the other records
+			// belong to the sessions which are already closed.
+			if !isClientHello(data) {
+				continue
+			}
+			s = ss.add(raddr)
+			go serveSession(s, secret, h)
+		}
+		s.deliver(data)
+	}
+}
+
+// PSK derives the pre-shared key of the thing identified by the provided ID,
+// which the thing uses to establish the DTLS session. The key is the
+// HMAC-SHA256 of the thing ID and the digest of its current key, separated by
+// colon, keyed by the provided secret.
+func PSK(secret []byte, thingID, keyDigest string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(thingID + ":" + keyDigest))
+	return mac.Sum(nil)
+}
+
+// keyDigest retrieves the digest of the current key of the thing, failing if
+// the thing doesn't exist.
+func keyDigest(thingID string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	res, err := auth.KeyDigest(ctx, &mainflux.ThingID{Value: thingID})
+	if err != nil {
+		return "", err
+	}
+
+	return res.GetValue(), nil
+}
+
+func isClientHello(data []byte) bool {
+	return len(data) > recordHeaderLen && data[0] == handshakeRecord && data[recordHeaderLen] == clientHello
+}
+
+func serveSession(s *session, secret []byte, h Handler) {
+	var (
+		mu       sync.Mutex
+		identity string
+	)
+	cfg := &dtls.Config{
+		PSK: func(id []byte) ([]byte, error) {
+			if len(id) == 0 || len(id) > maxIdentityLen {
+				return nil, errBadIdentity
+			}
+			digest, err := keyDigest(string(id))
+			if err != nil {
+				return nil, err
+			}
+			mu.Lock()
+			identity = string(id)
+			mu.Unlock()
+			return PSK(secret, string(id), digest), nil
+		},
+		CipherSuites:   cipherSuites,
+		ConnectTimeout: dtls.ConnectTimeoutOption(handshakeTimeout),
+	}
+
+	conn, err := dtls.Server(s, cfg)
+	if err != nil {
+		s.Close()
+		logger.Warn(fmt.Sprintf("Failed DTLS handshake with %s: %s", s.addr, err))
+		return
+	}
+	defer conn.Close()
+
+	mu.Lock()
+	ep := dtlsEndpoint{
+		conn:    conn,
+		addr:    s.addr,
+		thingID: identity,
+	}
+	mu.Unlock()
+
+	buf := make([]byte, maxRecordSize)
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			return
+		}
+
+		data := make([]byte, n)
+		copy(data, buf)
+		go serve(ep, data, h)
+	}
+}
+
+type dtlsEndpoint struct {
+	conn    net.Conn
+	addr    *net.UDPAddr
+	thingID string
+}
+
+func (ep dtlsEndpoint) String() string {
+	return fmt.Sprintf("dtls-%s", ep.addr)
+}
+
+func (ep dtlsEndpoint) thing() string {
+	return ep.thingID
+}
+
+func (ep dtlsEndpoint) transmit(msg gocoap.Message) error {
+	data, err := msg.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	_, err = ep.conn.Write(data)
+	return err
+}
+
+// sessions demultiplexes the datagrams received on the shared UDP socket to
+// the DTLS sessions of the client endpoints.
+type sessions struct {
+	mu    sync.Mutex
+	conn  *net.UDPConn
+	items map[string]*session
+}
+
+func newSessions(conn *net.UDPConn) *sessions {
+	return &sessions{
+		conn:  conn,
+		items: make(map[string]*session),
+	}
+}
+
+func (ss *sessions) get(addr *net.UDPAddr) (*session, bool) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	s, ok := ss.items[addr.String()]
+	return s, ok
+}
+
+func (ss *sessions) add(addr *net.UDPAddr) *session {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	s := &session{
+		conn:    ss.conn,
+		addr:    addr,
+		packets: make(chan []byte, sessionBuffer),
+		done:    make(chan struct{}),
+	}
+	s.remove = func() {
+		ss.mu.Lock()
+		defer ss.mu.Unlock()
+		if ss.items[addr.String()] == s {
+			delete(ss.items, addr.String())
+		}
+	}
+	ss.items[addr.String()] = s
+	return s
+}
+
+var _ net.Conn = (*session)(nil)
+
+// session is the datagram connection of the client endpoint, which the DTLS
+// connection is established over. Session expires if no datagram is received
+// within the notification Max-Age, since the observing client acknowledges
+// the pings sent more often than that.
+type session struct {
+	conn    *net.UDPConn
+	addr    *net.UDPAddr
+	packets chan []byte
+	done    chan struct{}
+	once    sync.Once
+	remove  func()
+}
+
+func (s *session) deliver(data []byte) {
+	// Datagrams are dropped if the session doesn't keep up, the same as if
+	// they were lost.
+	select {
+	case s.packets <- data:
+	default:
+	}
+}
+
+func (s *session) Read(b []byte) (int, error) {
+	t := time.NewTimer(time.Duration(maxAge()) * time.Second)
+	defer t.Stop()
+
+	select {
+	case data := <-s.packets:
+		if len(b) < len(data) {
+			return 0, io.ErrShortBuffer
+		}
+		return copy(b, data), nil
+	case <-s.done:
+		return 0, errSessionClosed
+	case <-t.C:
+		return 0, errSessionExpired
+	}
+}
+
+func (s *session) Write(b []byte) (int, error) {
+	select {
+	case <-s.done:
+		return 0, errSessionClosed
+	default:
+	}
+
+	return s.conn.WriteToUDP(b, s.addr)
+}
+
+func (s *session) Close() error {
+	s.once.Do(func() {
+		close(s.done)
+		s.remove()
+	})
+	return nil
+}
+
+func (s *session) LocalAddr() net.Addr {
+	return s.conn.LocalAddr()
+}
+
+func (s *session) RemoteAddr() net.Addr {
+	return s.addr
+}
+
+// Deadlines aren't supported, since the DTLS connection closes once the
+// deadline is exceeded.
+
+func (s *session) SetDeadline(time.Time) error {
+	return nil
+}
+
+func (s *session) SetReadDeadline(time.Time) error {
+	return nil
+}
+
+func (s *session) SetWriteDeadline(time.Time) error {
+	return nil
+}
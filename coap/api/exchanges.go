@@ -0,0 +1,75 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var messageID = uint32(time.Now().UnixNano())
+
+// nextMessageID returns the message ID of the notification or ping sent to
+// the client. Message IDs are shared by all the observations, since they
+// have to be unique per client endpoint.
+func nextMessageID() uint16 {
+	return uint16(atomic.AddUint32(&messageID, 1))
+}
+
+type exchange struct {
+	obsID   string
+	expires time.Time
+}
+
+// exchanges keeps the observations of the notifications and pings sent to
+// the clients, so that the empty ACK and RST messages the clients respond
+// with, which carry the message ID only, are matched with the observation.
+type exchanges struct {
+	mu     sync.Mutex
+	items  map[string]exchange
+	pruned time.Time
+}
+
+func newExchanges() *exchanges {
+	return &exchanges{
+		items:  make(map[string]exchange),
+		pruned: time.Now(),
+	}
+}
+
+func (es *exchanges) put(ep endpoint, msgID uint16, obsID string) {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(es.pruned) >= exchangeLifetime {
+		for key, e := range es.items {
+			if now.After(e.expires) {
+				delete(es.items, key)
+			}
+		}
+		es.pruned = now
+	}
+
+	es.items[fmt.Sprintf("%s-%d", ep, msgID)] = exchange{
+		obsID:   obsID,
+		expires: now.Add(exchangeLifetime),
+	}
+}
+
+func (es *exchanges) pop(ep endpoint, msgID uint16) (string, bool) {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+
+	key := fmt.Sprintf("%s-%d", ep, msgID)
+	e, ok := es.items[key]
+	if !ok {
+		return "", false
+	}
+
+	delete(es.items, key)
+	return e.obsID, true
+}
@@ -0,0 +1,165 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"time"
+
+	gocoap "github.com/dustin/go-coap"
+	"github.com/mainflux/mainflux/coap"
+)
+
+const maxPacketSize = 1500
+
+// Handler handles the CoAP messages received over plain UDP and DTLS.
+type Handler struct {
+	svc       coap.Service
+	responses chan<- string
+}
+
+// endpoint represents the client endpoint the messages are exchanged with.
+type endpoint interface {
+	// String uniquely identifies the endpoint.
+	String() string
+
+	// thing returns the ID of the thing authenticated by the DTLS handshake,
+	// or the empty string if the endpoint isn't authenticated.
+	thing() string
+
+	// transmit sends the message to the endpoint.
+	transmit(gocoap.Message) error
+}
+
+type udpEndpoint struct {
+	conn *net.UDPConn
+	addr *net.UDPAddr
+}
+
+func (ep udpEndpoint) String() string {
+	return ep.addr.String()
+}
+
+func (ep udpEndpoint) thing() string {
+	return ""
+}
+
+func (ep udpEndpoint) transmit(msg gocoap.Message) error {
+	return gocoap.Transmit(ep.conn, ep.addr, msg)
+}
+
+// ListenAndServe binds to the given UDP address and serves plaintext CoAP
+// requests using the provided handler. Unlike the CoAP library server, it
+// keeps the block-wise transfer options (RFC 7959) of the received messages,
+// which the library doesn't recognize and drops.
+func ListenAndServe(addr string, h Handler) error {
+	uaddr, err := net.ResolveUDPAddr("udp", addr)
+	if err != nil {
+		return err
+	}
+
+	conn, err := net.ListenUDP("udp", uaddr)
+	if err != nil {
+		return err
+	}
+
+	buf := make([]byte, maxPacketSize)
+	for {
+		n, raddr, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			if neterr, ok := err.(net.Error); ok && neterr.Timeout() {
+				time.Sleep(5 * time.Millisecond)
+				continue
+			}
+			return err
+		}
+
+		data := make([]byte, n)
+		copy(data, buf)
+		go serve(udpEndpoint{conn: conn, addr: raddr}, data, h)
+	}
+}
+
+func serve(ep endpoint, data []byte, h Handler) {
+	msg, err := gocoap.ParseMessage(data)
+	if err != nil {
+		logger.Warn(fmt.Sprintf("Failed to parse message from %s: %s", ep, err))
+		return
+	}
+
+	if err := addBlockOptions(data, &msg); err != nil {
+		logger.Warn(fmt.Sprintf("Failed to parse block options from %s: %s", ep, err))
+		return
+	}
+
+	if res := h.serve(ep, &msg); res != nil {
+		if err := ep.transmit(*res); err != nil {
+			logger.Warn(fmt.Sprintf("Failed to send response to %s: %s", ep, err))
+		}
+	}
+}
+
+// addBlockOptions adds the Block1, Block2 and Size2 options of the raw
+// message to the parsed one.
+func addBlockOptions(data []byte, msg *gocoap.Message) error {
+	start := 4 + int(data[0]&0xf)
+	if len(data) < start {
+		return errBadOption
+	}
+
+	b := data[start:]
+	id := 0
+	for len(b) > 0 && b[0] != 0xff {
+		delta, length := int(b[0]>>4), int(b[0]&0xf)
+		b = b[1:]
+
+		var err error
+		if delta, b, err = extendOption(delta, b); err != nil {
+			return err
+		}
+		if length, b, err = extendOption(length, b); err != nil {
+			return err
+		}
+		if len(b) < length {
+			return errBadOption
+		}
+
+		id += delta
+		switch gocoap.OptionID(id) {
+		case block1, block2, size2:
+			if length > 4 {
+				return errBadOption
+			}
+			val := make([]byte, 4)
+			copy(val[4-length:], b[:length])
+			msg.AddOption(gocoap.OptionID(id), binary.BigEndian.Uint32(val))
+		}
+		b = b[length:]
+	}
+
+	return nil
+}
+
+// extendOption reads the extended option delta or length (RFC 7252
+// section 3.1).
+func extendOption(val int, b []byte) (int, []byte, error) {
+	switch val {
+	case 13:
+		if len(b) < 1 {
+			return 0, nil, errBadOption
+		}
+		return int(b[0]) + 13, b[1:], nil
+	case 14:
+		if len(b) < 2 {
+			return 0, nil, errBadOption
+		}
+		return int(binary.BigEndian.Uint16(b)) + 269, b[2:], nil
+	case 15:
+		return 0, nil, errBadOption
+	default:
+		return val, b, nil
+	}
+}
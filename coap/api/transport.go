@@ -9,7 +9,6 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
-	"net"
 	"net/http"
 	"net/url"
 	"regexp"
@@ -18,9 +17,14 @@ import (
 
 	gocoap "github.com/dustin/go-coap"
 	"github.com/go-zoo/bone"
+	"github.com/gofrs/uuid"
 	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/coap"
 	log "github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/payload"
+	"github.com/mainflux/mainflux/presence"
+	"github.com/mainflux/mainflux/things"
+	"github.com/mainflux/mainflux/things/keys"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -41,52 +45,68 @@ var (
 
 var (
 	auth       mainflux.ThingsServiceClient
+	events     presence.EventPublisher
 	logger     log.Logger
 	pingPeriod time.Duration
-)
 
-type handler func(conn *net.UDPConn, addr *net.UDPAddr, msg *gocoap.Message) *gocoap.Message
+	uploads       = newTransfers()
+	notifications = newRepresentations()
+	inflight      = newExchanges()
+)
 
-//MakeHTTPHandler creates handler for version endpoint.
+// MakeHTTPHandler creates handler for version endpoint.
 func MakeHTTPHandler() http.Handler {
 	b := bone.New()
 	b.GetFunc("/version", mainflux.Version(protocol))
+	b.GetFunc(mainflux.DiscoveryPath, mainflux.Discovery(protocol, mainflux.Capabilities{
+		ContentTypes: []string{mainflux.SenMLJSON, mainflux.SenMLCBOR},
+	}))
 	b.Handle("/metrics", promhttp.Handler())
 
 	return b
 }
 
-// MakeCOAPHandler creates handler for CoAP messages.
-func MakeCOAPHandler(svc coap.Service, tc mainflux.ThingsServiceClient, l log.Logger, responses chan<- string, pp time.Duration) gocoap.Handler {
+// MakeCOAPHandler creates handler for CoAP messages. If the event publisher
+// is nil, the observe and cancel events aren't published.
+func MakeCOAPHandler(svc coap.Service, tc mainflux.ThingsServiceClient, ep presence.EventPublisher, l log.Logger, responses chan<- string, pp time.Duration) Handler {
 	auth = tc
+	events = ep
 	logger = l
 	pingPeriod = pp
-	return mux(svc, responses)
+	return Handler{
+		svc:       svc,
+		responses: responses,
+	}
 }
 
-func mux(svc coap.Service, responses chan<- string) gocoap.Handler {
-	return gocoap.FuncHandler(func(conn *net.UDPConn, addr *net.UDPAddr, msg *gocoap.Message) *gocoap.Message {
-		path := msg.PathString()
-		if !channelRegExp.Match([]byte(path)) {
-			logger.Info(fmt.Sprintf("path %s not found", path))
-			return &gocoap.Message{
-				Type:      gocoap.NonConfirmable,
-				Code:      gocoap.NotFound,
-				MessageID: msg.MessageID,
-				Token:     msg.Token,
-			}
-		}
-		// Allow "/" to be a part of the path.
-		if strings.HasPrefix(path, "/") {
-			msg.SetPathString(path[1:])
-		}
-		switch msg.Code {
-		case gocoap.GET:
-			return observe(svc, responses)(conn, addr, msg)
-		default:
-			return receive(svc, msg)
+func (h Handler) serve(ep endpoint, msg *gocoap.Message) *gocoap.Message {
+	// Empty ACK and RST messages are the client responses to the
+	// notifications and pings, which don't carry the path.
+	if msg.Code == 0 && (msg.Type == gocoap.Acknowledgement || msg.Type == gocoap.Reset) {
+		respond(h.svc, ep, msg, h.responses)
+		return nil
+	}
+
+	path := msg.PathString()
+	if !channelRegExp.Match([]byte(path)) {
+		logger.Info(fmt.Sprintf("path %s not found", path))
+		return &gocoap.Message{
+			Type:      gocoap.NonConfirmable,
+			Code:      gocoap.NotFound,
+			MessageID: msg.MessageID,
+			Token:     msg.Token,
 		}
-	})
+	}
+	// Allow "/" to be a part of the path.
+	if strings.HasPrefix(path, "/") {
+		msg.SetPathString(path[1:])
+	}
+	switch msg.Code {
+	case gocoap.GET:
+		return observe(h.svc, ep, msg)
+	default:
+		return receive(h.svc, ep, msg)
+	}
 }
 
 func id(msg *gocoap.Message) string {
@@ -111,7 +131,19 @@ func subtopic(msg *gocoap.Message) string {
 	return ""
 }
 
-func authorize(msg *gocoap.Message, res *gocoap.Message, cid string) (string, error) {
+func authorize(ep endpoint, msg *gocoap.Message, res *gocoap.Message, cid, action string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// Thing authenticated by the DTLS handshake doesn't pass its key.
+	if thingID := ep.thing(); thingID != "" {
+		_, err := auth.CanAccessByID(ctx, &mainflux.AccessByIDReq{ThingID: thingID, ChanID: cid, Action: action})
+		if err != nil {
+			return "", accessError(err, res)
+		}
+		return thingID, nil
+	}
+
 	// Device Key is passed as Uri-Query parameter, which option ID is 15 (0xf).
 	query := msg.Option(gocoap.URIQuery)
 	queryStr, ok := query.(string)
@@ -133,28 +165,37 @@ func authorize(msg *gocoap.Message, res *gocoap.Message, cid string) (string, er
 	}
 
 	key := auths[0]
+	if keys.Malformed(key) {
+		res.Code = gocoap.Forbidden
+		return "", things.ErrUnauthorizedAccess
+	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
-	defer cancel()
-
-	id, err := auth.CanAccess(ctx, &mainflux.AccessReq{Token: key, ChanID: cid})
+	id, err := auth.CanAccess(ctx, &mainflux.AccessReq{Token: key, ChanID: cid, Action: action})
 	if err != nil {
-		e, ok := status.FromError(err)
-		if ok {
-			switch e.Code() {
-			case codes.PermissionDenied:
-				res.Code = gocoap.Forbidden
-			default:
-				res.Code = gocoap.ServiceUnavailable
-			}
-			return "", err
-		}
-		res.Code = gocoap.InternalServerError
+		return "", accessError(err, res)
 	}
 
 	return id.GetValue(), nil
 }
 
+// accessError sets the response code corresponding to the access check
+// error.
+func accessError(err error, res *gocoap.Message) error {
+	e, ok := status.FromError(err)
+	if !ok {
+		res.Code = gocoap.InternalServerError
+		return err
+	}
+
+	switch e.Code() {
+	case codes.PermissionDenied:
+		res.Code = gocoap.Forbidden
+	default:
+		res.Code = gocoap.ServiceUnavailable
+	}
+	return err
+}
+
 func fmtSubtopic(msg *gocoap.Message) (string, error) {
 	subtopic := subtopic(msg)
 	if subtopic == "" {
@@ -182,7 +223,23 @@ func fmtSubtopic(msg *gocoap.Message) (string, error) {
 	return subtopic, nil
 }
 
-func receive(svc coap.Service, msg *gocoap.Message) *gocoap.Message {
+func respond(svc coap.Service, ep endpoint, msg *gocoap.Message, responses chan<- string) {
+	obsID, ok := inflight.pop(ep, msg.MessageID)
+	if !ok {
+		return
+	}
+
+	// According to https://tools.ietf.org/html/rfc7641#section-3.6, client
+	// rejects the notification with RST once it's no longer interested.
+	if msg.Type == gocoap.Reset {
+		svc.Unsubscribe(obsID)
+		return
+	}
+
+	responses <- obsID
+}
+
+func receive(svc coap.Service, ep endpoint, msg *gocoap.Message) *gocoap.Message {
 	// By default message is NonConfirmable, so
 	// NonConfirmable response is sent back.
 	res := &gocoap.Message{
@@ -222,12 +279,51 @@ func receive(svc coap.Service, msg *gocoap.Message) *gocoap.Message {
 		ct = ""
 	}
 
-	publisher, err := authorize(msg, res, chanID)
+	publisher, err := authorize(ep, msg, res, chanID, things.Publish)
 	if err != nil {
 		res.Code = gocoap.Forbidden
 		return res
 	}
 
+	if size, ok := msg.Option(gocoap.Size1).(uint32); ok && size > maxBodySize {
+		res.Code = gocoap.RequestEntityTooLarge
+		res.SetOption(gocoap.Size1, uint32(maxBodySize))
+		return res
+	}
+
+	b, ok, err := blockOption(msg, block1)
+	if err != nil {
+		res.Code = gocoap.BadOption
+		return res
+	}
+
+	if ok {
+		payload, err := uploads.append(exchangeKey(ep, msg), b, msg.Payload)
+		switch err {
+		case nil:
+		case errIncompleteEntity:
+			res.Code = requestEntityIncomplete
+			return res
+		case errEntityTooLarge:
+			res.Code = gocoap.RequestEntityTooLarge
+			res.SetOption(gocoap.Size1, uint32(maxBodySize))
+			return res
+		default:
+			res.Code = gocoap.BadRequest
+			return res
+		}
+
+		// According to https://tools.ietf.org/html/rfc7959#section-2.3, the
+		// server acknowledges every block and the message is published once the
+		// last one is received.
+		res.SetOption(block1, b.value())
+		if b.more {
+			res.Code = continueCode
+			return res
+		}
+		msg.Payload = payload
+	}
+
 	rawMsg := mainflux.RawMessage{
 		Channel:     chanID,
 		Subtopic:    subtopic,
@@ -237,83 +333,159 @@ func receive(svc coap.Service, msg *gocoap.Message) *gocoap.Message {
 		Payload:     msg.Payload,
 	}
 
-	if err := svc.Publish(context.Background(), "", rawMsg); err != nil {
+	switch err := svc.Publish(context.Background(), "", rawMsg); err {
+	case nil:
+	case payload.ErrTooLarge:
+		res.Code = gocoap.RequestEntityTooLarge
+	default:
 		res.Code = gocoap.InternalServerError
 	}
 
 	return res
 }
 
-func observe(svc coap.Service, responses chan<- string) handler {
-	return func(conn *net.UDPConn, addr *net.UDPAddr, msg *gocoap.Message) *gocoap.Message {
-		res := &gocoap.Message{
-			Type:      gocoap.Acknowledgement,
-			Code:      gocoap.Content,
-			MessageID: msg.MessageID,
-			Token:     msg.Token,
-			Payload:   []byte{},
-		}
-		res.SetOption(gocoap.ContentFormat, gocoap.AppJSON)
+func observe(svc coap.Service, ep endpoint, msg *gocoap.Message) *gocoap.Message {
+	res := &gocoap.Message{
+		Type:      gocoap.Acknowledgement,
+		Code:      gocoap.Content,
+		MessageID: msg.MessageID,
+		Token:     msg.Token,
+		Payload:   []byte{},
+	}
+	res.SetOption(gocoap.ContentFormat, gocoap.AppJSON)
 
-		chanID := id(msg)
-		if chanID == "" {
-			res.Code = gocoap.NotFound
-			return res
-		}
+	chanID := id(msg)
+	if chanID == "" {
+		res.Code = gocoap.NotFound
+		return res
+	}
 
-		subtopic, err := fmtSubtopic(msg)
-		if err != nil {
-			res.Code = gocoap.BadRequest
-			return res
-		}
+	subtopic, err := fmtSubtopic(msg)
+	if err != nil {
+		res.Code = gocoap.BadRequest
+		return res
+	}
 
-		publisher, err := authorize(msg, res, chanID)
-		if err != nil {
-			res.Code = gocoap.Forbidden
-			logger.Warn(fmt.Sprintf("Failed to authorize: %s", err))
-			return res
-		}
+	publisher, err := authorize(ep, msg, res, chanID, things.Subscribe)
+	if err != nil {
+		res.Code = gocoap.Forbidden
+		logger.Warn(fmt.Sprintf("Failed to authorize: %s", err))
+		return res
+	}
 
-		obsID := fmt.Sprintf("%x-%s-%s", msg.Token, publisher, chanID)
+	b, ok, err := blockOption(msg, block2)
+	if err != nil {
+		res.Code = gocoap.BadOption
+		return res
+	}
 
-		if msg.Type == gocoap.Acknowledgement {
-			responses <- obsID
-			return nil
+	// Client retrieves the remaining blocks of the notification without
+	// the Observe option.
+	if ok && b.num > 0 {
+		return notificationBlock(ep, msg, res, b)
+	}
+
+	obsID := fmt.Sprintf("%x-%s-%s", msg.Token, publisher, chanID)
+
+	if value, ok := msg.Option(gocoap.Observe).(uint32); ok && value == 1 {
+		svc.Unsubscribe(obsID)
+	}
+
+	if value, ok := msg.Option(gocoap.Observe).(uint32); ok && value == 0 {
+		res.AddOption(gocoap.Observe, 1)
+		res.SetOption(gocoap.MaxAge, maxAge())
+		o := coap.NewObserver()
+		if err := svc.Subscribe(chanID, subtopic, obsID, o); err != nil {
+			logger.Warn(fmt.Sprintf("Failed to subscribe to NATS subject: %s", err))
+			res.Code = gocoap.InternalServerError
+			return res
 		}
 
-		if value, ok := msg.Option(gocoap.Observe).(uint32); (ok && value == 1) || msg.Type == gocoap.Reset {
-			svc.Unsubscribe(obsID)
+		// Every observation is the new session of the thing, since the
+		// observation with the same token replaces the previous one.
+		e := presence.Event{
+			Thing:    publisher,
+			Channel:  chanID,
+			Protocol: protocol,
 		}
+		if id, err := uuid.NewV4(); err == nil {
+			e.Session = id.String()
+		}
+		publishEvent(e, presence.Connect)
 
-		if value, ok := msg.Option(gocoap.Observe).(uint32); ok && value == 0 {
-			res.AddOption(gocoap.Observe, 1)
-			o := coap.NewObserver()
-			if err := svc.Subscribe(chanID, subtopic, obsID, o); err != nil {
-				logger.Warn(fmt.Sprintf("Failed to subscribe to NATS subject: %s", err))
-				res.Code = gocoap.InternalServerError
-				return res
-			}
+		go handleMessage(ep, obsID, o, msg)
+		go ping(svc, obsID, ep, o, msg)
+		go cancel(o, e)
+	}
 
-			go handleMessage(conn, addr, o, msg)
-			go ping(svc, obsID, conn, addr, o, msg)
-			go cancel(o)
-		}
+	return res
+}
+
+func notificationBlock(ep endpoint, msg *gocoap.Message, res *gocoap.Message, b block) *gocoap.Message {
+	r, ok := notifications.get(exchangeKey(ep, msg))
+	if !ok {
+		res.Code = gocoap.NotFound
+		return res
+	}
 
+	start := int(b.num) * b.size()
+	if start >= len(r.payload) {
+		res.Code = gocoap.BadOption
 		return res
 	}
+
+	end := start + b.size()
+	b.more = end < len(r.payload)
+	if !b.more {
+		end = len(r.payload)
+	}
+
+	res.Payload = r.payload[start:end]
+	res.SetOption(gocoap.ContentFormat, r.ct)
+	res.SetOption(gocoap.MaxAge, maxAge())
+	res.SetOption(block2, b.value())
+	return res
+}
+
+// maxAge returns the Max-Age of the notifications in seconds. The client
+// should consider the observation lost and register again if it receives
+// neither the notification nor the ping within that time.
+func maxAge() uint32 {
+	return uint32((pingPeriod*time.Hour + exchangeLifetime) / time.Second)
 }
 
-func cancel(observer *coap.Observer) {
+func cancel(observer *coap.Observer, e presence.Event) {
 	<-observer.Cancel
 	close(observer.Messages)
 	observer.StoreExpired(true)
+	publishEvent(e, presence.Disconnect)
+}
+
+// publishEvent publishes the connectivity event of the observation.
+func publishEvent(e presence.Event, eventType string) {
+	if events == nil || e.Session == "" {
+		return
+	}
+
+	e.Type = eventType
+	e.Time = time.Now()
+	if err := events.Publish(e); err != nil {
+		logger.Warn(fmt.Sprintf("Failed to publish %s event: %s", eventType, err))
+	}
 }
 
-func handleMessage(conn *net.UDPConn, addr *net.UDPAddr, o *coap.Observer, msg *gocoap.Message) {
+func handleMessage(ep endpoint, obsID string, o *coap.Observer, msg *gocoap.Message) {
+	key := exchangeKey(ep, msg)
+	b := block{szx: defBlockSZX}
+	if rb, ok, _ := blockOption(msg, block2); ok && rb.szx < b.szx {
+		b.szx = rb.szx
+	}
+
 	notifyMsg := *msg
 	notifyMsg.Type = gocoap.NonConfirmable
 	notifyMsg.Code = gocoap.Content
 	notifyMsg.RemoveOption(gocoap.URIQuery)
+	notifyMsg.SetOption(gocoap.MaxAge, maxAge())
 	for {
 		msg, ok := <-o.Messages
 		if !ok {
@@ -321,9 +493,9 @@ func handleMessage(conn *net.UDPConn, addr *net.UDPAddr, o *coap.Observer, msg *
 		}
 
 		notifyMsg.Payload = msg.Payload
-		notifyMsg.MessageID = o.LoadMessageID()
+		notifyMsg.MessageID = nextMessageID()
 		buff := new(bytes.Buffer)
-		observe := uint64(notifyMsg.MessageID)
+		observe := uint64(o.LoadMessageID())
 		if err := binary.Write(buff, binary.BigEndian, observe); err != nil {
 			logger.Warn(fmt.Sprintf("Failed to generate Observe option value: %s", err))
 			continue
@@ -341,13 +513,26 @@ func handleMessage(conn *net.UDPConn, addr *net.UDPAddr, o *coap.Observer, msg *
 		}
 		notifyMsg.SetOption(gocoap.ContentFormat, coapCT)
 
-		if err := gocoap.Transmit(conn, addr, notifyMsg); err != nil {
+		// According to https://tools.ietf.org/html/rfc7959#section-3.4, only
+		// the first block of the large notification is sent, and the client
+		// retrieves the remaining ones.
+		notifyMsg.RemoveOption(block2)
+		notifyMsg.RemoveOption(size2)
+		if len(msg.Payload) > b.size() {
+			notifications.put(key, msg.Payload, coapCT)
+			notifyMsg.Payload = msg.Payload[:b.size()]
+			notifyMsg.SetOption(block2, block{more: true, szx: b.szx}.value())
+			notifyMsg.SetOption(size2, uint32(len(msg.Payload)))
+		}
+
+		inflight.put(ep, notifyMsg.MessageID, obsID)
+		if err := ep.transmit(notifyMsg); err != nil {
 			logger.Warn(fmt.Sprintf("Failed to send message to observer: %s", err))
 		}
 	}
 }
 
-func ping(svc coap.Service, obsID string, conn *net.UDPConn, addr *net.UDPAddr, o *coap.Observer, msg *gocoap.Message) {
+func ping(svc coap.Service, obsID string, ep endpoint, o *coap.Observer, msg *gocoap.Message) {
 	pingMsg := *msg
 	pingMsg.Payload = []byte{}
 	pingMsg.Type = gocoap.Confirmable
@@ -367,8 +552,9 @@ func ping(svc coap.Service, obsID string, conn *net.UDPConn, addr *net.UDPAddr,
 			timeout := float64(coap.AckTimeout)
 			logger.Info(fmt.Sprintf("Ping client %s.", obsID))
 			for i := 0; i < coap.MaxRetransmit; i++ {
-				pingMsg.MessageID = o.LoadMessageID()
-				gocoap.Transmit(conn, addr, pingMsg)
+				pingMsg.MessageID = nextMessageID()
+				inflight.put(ep, pingMsg.MessageID, obsID)
+				ep.transmit(pingMsg)
 				time.Sleep(time.Duration(timeout * coap.AckRandomFactor))
 				if !o.LoadExpired() {
 					break
@@ -11,7 +11,9 @@ import (
 	"github.com/gogo/protobuf/proto"
 	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/coap"
-	broker "github.com/nats-io/go-nats"
+	mfnats "github.com/mainflux/mainflux/nats"
+	"github.com/mainflux/mainflux/tracing"
+	broker "github.com/nats-io/nats.go"
 )
 
 const prefix = "channel"
@@ -19,12 +21,14 @@ const prefix = "channel"
 var _ mainflux.MessagePublisher = (*natsPublisher)(nil)
 
 type natsPublisher struct {
-	nc *broker.Conn
+	nc            *broker.Conn
+	subjectPrefix string
 }
 
-// New instantiates NATS message publisher.
-func New(nc *broker.Conn) coap.Broker {
-	return &natsPublisher{nc}
+// New instantiates NATS message publisher. The subjects are prefixed with the
+// deployment subject prefix, unless it is empty.
+func New(nc *broker.Conn, subjectPrefix string) coap.Broker {
+	return &natsPublisher{nc, subjectPrefix}
 }
 
 func (pubsub *natsPublisher) fmtSubject(chanID, subtopic string) string {
@@ -32,17 +36,17 @@ func (pubsub *natsPublisher) fmtSubject(chanID, subtopic string) string {
 	if subtopic != "" {
 		subject = fmt.Sprintf("%s.%s", subject, subtopic)
 	}
-	return subject
+	return mfnats.Subject(pubsub.subjectPrefix, subject)
 }
 
-func (pubsub *natsPublisher) Publish(_ context.Context, _ string, msg mainflux.RawMessage) error {
+func (pubsub *natsPublisher) Publish(ctx context.Context, _ string, msg mainflux.RawMessage) error {
 	data, err := proto.Marshal(&msg)
 	if err != nil {
 		return err
 	}
 
 	subject := pubsub.fmtSubject(msg.Channel, msg.Subtopic)
-	return pubsub.nc.Publish(subject, data)
+	return tracing.Publish(ctx, pubsub.nc, subject, data)
 }
 
 func (pubsub *natsPublisher) Subscribe(chanID, subtopic, obsID string, observer *coap.Observer) error {
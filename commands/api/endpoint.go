@@ -0,0 +1,104 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/mainflux/mainflux/commands"
+)
+
+func sendEndpoint(svc commands.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(sendReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		cmd := commands.Command{
+			Thing:   req.thingID,
+			Channel: req.Channel,
+			Name:    req.Name,
+			Payload: req.Payload,
+		}
+
+		saved, err := svc.Send(ctx, req.token, cmd, time.Duration(req.TTL)*time.Second)
+		if err != nil {
+			return nil, err
+		}
+
+		return sendRes{thingID: saved.Thing, id: saved.ID}, nil
+	}
+}
+
+func viewEndpoint(svc commands.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		cmd, err := svc.ViewCommand(ctx, req.token, req.thingID, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		return toCommandRes(cmd), nil
+	}
+}
+
+func listEndpoint(svc commands.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		page, err := svc.ListCommands(ctx, req.token, req.thingID, req.state, req.offset, req.limit)
+		if err != nil {
+			return nil, err
+		}
+
+		res := commandsPageRes{
+			Total:    page.Total,
+			Offset:   page.Offset,
+			Limit:    page.Limit,
+			Commands: []commandRes{},
+		}
+		for _, cmd := range page.Commands {
+			res.Commands = append(res.Commands, toCommandRes(cmd))
+		}
+
+		return res, nil
+	}
+}
+
+func toCommandRes(cmd commands.Command) commandRes {
+	res := commandRes{
+		ID:       cmd.ID,
+		Thing:    cmd.Thing,
+		Channel:  cmd.Channel,
+		Name:     cmd.Name,
+		Payload:  cmd.Payload,
+		State:    cmd.State,
+		Response: cmd.Response,
+		Created:  cmd.Created,
+		Expires:  cmd.Expires,
+	}
+
+	if !cmd.Delivered.IsZero() {
+		res.Delivered = &cmd.Delivered
+	}
+
+	if !cmd.Acked.IsZero() {
+		res.Acked = &cmd.Acked
+	}
+
+	return res
+}
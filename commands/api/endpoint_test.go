@@ -0,0 +1,258 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/commands"
+	"github.com/mainflux/mainflux/commands/api"
+	"github.com/mainflux/mainflux/commands/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	token       = "token"
+	wrongValue  = "wrong-value"
+	email       = "user@example.com"
+	thingID     = "thing"
+	chanID      = "1"
+	contentType = "application/json"
+)
+
+type testRequest struct {
+	client      *http.Client
+	method      string
+	url         string
+	contentType string
+	token       string
+	body        io.Reader
+}
+
+func (tr testRequest) make() (*http.Response, error) {
+	req, err := http.NewRequest(tr.method, tr.url, tr.body)
+	if err != nil {
+		return nil, err
+	}
+
+	if tr.token != "" {
+		req.Header.Set("Authorization", tr.token)
+	}
+
+	if tr.contentType != "" {
+		req.Header.Set("Content-Type", tr.contentType)
+	}
+
+	return tr.client.Do(req)
+}
+
+func newServer() *httptest.Server {
+	users := mocks.NewUsersService(map[string]string{token: email})
+	things := mocks.NewThings(map[string]string{thingID: token}, map[string][]string{thingID: {chanID}})
+	svc := commands.New(users, things, mocks.NewCommandRepository(), mocks.NewPublisher(), mocks.NewIdentityProvider(), time.Minute, time.Hour)
+
+	return httptest.NewServer(api.MakeHandler(svc))
+}
+
+func TestSend(t *testing.T) {
+	ts := newServer()
+	defer ts.Close()
+
+	cases := []struct {
+		desc        string
+		thing       string
+		body        string
+		contentType string
+		token       string
+		status      int
+	}{
+		{
+			desc:        "send command",
+			thing:       thingID,
+			body:        fmt.Sprintf(`{"channel":"%s","name":"reboot","payload":{"delay":5},"ttl":60}`, chanID),
+			contentType: contentType,
+			token:       token,
+			status:      http.StatusCreated,
+		},
+		{
+			desc:        "send command without name",
+			thing:       thingID,
+			body:        fmt.Sprintf(`{"channel":"%s"}`, chanID),
+			contentType: contentType,
+			token:       token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "send command with TTL exceeding maximal TTL",
+			thing:       thingID,
+			body:        fmt.Sprintf(`{"channel":"%s","name":"reboot","ttl":7200}`, chanID),
+			contentType: contentType,
+			token:       token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "send command with malformed JSON",
+			thing:       thingID,
+			body:        `{"channel":`,
+			contentType: contentType,
+			token:       token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "send command to non-existing thing",
+			thing:       wrongValue,
+			body:        fmt.Sprintf(`{"channel":"%s","name":"reboot"}`, chanID),
+			contentType: contentType,
+			token:       token,
+			status:      http.StatusNotFound,
+		},
+		{
+			desc:        "send command with invalid content type",
+			thing:       thingID,
+			body:        fmt.Sprintf(`{"channel":"%s","name":"reboot"}`, chanID),
+			contentType: "text/plain",
+			token:       token,
+			status:      http.StatusUnsupportedMediaType,
+		},
+		{
+			desc:        "send command with invalid credentials",
+			thing:       thingID,
+			body:        fmt.Sprintf(`{"channel":"%s","name":"reboot"}`, chanID),
+			contentType: contentType,
+			token:       wrongValue,
+			status:      http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/things/%s/commands", ts.URL, tc.thing),
+			contentType: tc.contentType,
+			token:       tc.token,
+			body:        strings.NewReader(tc.body),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestViewAndList(t *testing.T) {
+	ts := newServer()
+	defer ts.Close()
+
+	req := testRequest{
+		client:      ts.Client(),
+		method:      http.MethodPost,
+		url:         fmt.Sprintf("%s/things/%s/commands", ts.URL, thingID),
+		contentType: contentType,
+		token:       token,
+		body:        strings.NewReader(fmt.Sprintf(`{"channel":"%s","name":"reboot","payload":{"delay":5}}`, chanID)),
+	}
+	res, err := req.make()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	require.Equal(t, http.StatusCreated, res.StatusCode, "failed to send command")
+	location := res.Header.Get("Location")
+
+	req = testRequest{
+		client: ts.Client(),
+		method: http.MethodGet,
+		url:    fmt.Sprintf("%s%s", ts.URL, location),
+		token:  token,
+	}
+	res, err = req.make()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	require.Equal(t, http.StatusOK, res.StatusCode, "failed to view command")
+
+	var cmd struct {
+		ID        string          `json:"id"`
+		State     string          `json:"state"`
+		Payload   json.RawMessage `json:"payload"`
+		Delivered *time.Time      `json:"delivered"`
+	}
+	err = json.NewDecoder(res.Body).Decode(&cmd)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, commands.StateDelivered, cmd.State, fmt.Sprintf("expected %s got %s", commands.StateDelivered, cmd.State))
+	assert.JSONEq(t, `{"delay":5}`, string(cmd.Payload), fmt.Sprintf("unexpected payload %s", cmd.Payload))
+	assert.NotNil(t, cmd.Delivered, "expected delivery time")
+
+	cases := []struct {
+		desc   string
+		url    string
+		token  string
+		status int
+		total  uint64
+	}{
+		{
+			desc:   "list commands",
+			url:    fmt.Sprintf("/things/%s/commands", thingID),
+			token:  token,
+			status: http.StatusOK,
+			total:  1,
+		},
+		{
+			desc:   "list acked commands",
+			url:    fmt.Sprintf("/things/%s/commands?state=%s", thingID, commands.StateAcked),
+			token:  token,
+			status: http.StatusOK,
+			total:  0,
+		},
+		{
+			desc:   "list commands with invalid state",
+			url:    fmt.Sprintf("/things/%s/commands?state=%s", thingID, wrongValue),
+			token:  token,
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "list commands with invalid limit",
+			url:    fmt.Sprintf("/things/%s/commands?limit=1000", thingID),
+			token:  token,
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "list commands with invalid credentials",
+			url:    fmt.Sprintf("/things/%s/commands", thingID),
+			token:  wrongValue,
+			status: http.StatusForbidden,
+		},
+		{
+			desc:   "view non-existing command",
+			url:    fmt.Sprintf("/things/%s/commands/%s", thingID, wrongValue),
+			token:  token,
+			status: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s%s", ts.URL, tc.url),
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		if tc.status != http.StatusOK {
+			continue
+		}
+
+		var page struct {
+			Total uint64 `json:"total"`
+		}
+		err = json.NewDecoder(res.Body).Decode(&page)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.total, page.Total, fmt.Sprintf("%s: expected total %d got %d", tc.desc, tc.total, page.Total))
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// +build !test
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/commands"
+	log "github.com/mainflux/mainflux/logger"
+)
+
+var _ commands.Service = (*loggingMiddleware)(nil)
+
+type loggingMiddleware struct {
+	logger log.Logger
+	svc    commands.Service
+}
+
+// LoggingMiddleware adds logging facilities to the core service.
+func LoggingMiddleware(svc commands.Service, logger log.Logger) commands.Service {
+	return &loggingMiddleware{logger, svc}
+}
+
+func (lm *loggingMiddleware) Send(ctx context.Context, token string, cmd commands.Command, ttl time.Duration) (saved commands.Command, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method send for token %s and thing %s and command %s took %s to complete", token, cmd.Thing, saved.ID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Send(ctx, token, cmd, ttl)
+}
+
+func (lm *loggingMiddleware) ViewCommand(ctx context.Context, token, thingID, id string) (cmd commands.Command, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method view_command for token %s and thing %s and command %s took %s to complete", token, thingID, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ViewCommand(ctx, token, thingID, id)
+}
+
+func (lm *loggingMiddleware) ListCommands(ctx context.Context, token, thingID, state string, offset, limit uint64) (page commands.CommandsPage, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_commands for token %s and thing %s took %s to complete", token, thingID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListCommands(ctx, token, thingID, state, offset, limit)
+}
+
+func (lm *loggingMiddleware) Acknowledge(ctx context.Context, msg mainflux.RawMessage) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method acknowledge for thing %s took %s to complete", msg.Publisher, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Acknowledge(ctx, msg)
+}
+
+func (lm *loggingMiddleware) Expire(ctx context.Context) (n uint64, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method expire expired %d commands and took %s to complete", n, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Expire(ctx)
+}
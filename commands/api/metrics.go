@@ -0,0 +1,78 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// +build !test
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/commands"
+)
+
+var _ commands.Service = (*metricsMiddleware)(nil)
+
+type metricsMiddleware struct {
+	counter metrics.Counter
+	latency metrics.Histogram
+	svc     commands.Service
+}
+
+// MetricsMiddleware instruments core service by tracking request count and
+// latency.
+func MetricsMiddleware(svc commands.Service, counter metrics.Counter, latency metrics.Histogram) commands.Service {
+	return &metricsMiddleware{
+		counter: counter,
+		latency: latency,
+		svc:     svc,
+	}
+}
+
+func (ms *metricsMiddleware) Send(ctx context.Context, token string, cmd commands.Command, ttl time.Duration) (commands.Command, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "send").Add(1)
+		ms.latency.With("method", "send").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Send(ctx, token, cmd, ttl)
+}
+
+func (ms *metricsMiddleware) ViewCommand(ctx context.Context, token, thingID, id string) (commands.Command, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_command").Add(1)
+		ms.latency.With("method", "view_command").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewCommand(ctx, token, thingID, id)
+}
+
+func (ms *metricsMiddleware) ListCommands(ctx context.Context, token, thingID, state string, offset, limit uint64) (commands.CommandsPage, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_commands").Add(1)
+		ms.latency.With("method", "list_commands").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListCommands(ctx, token, thingID, state, offset, limit)
+}
+
+func (ms *metricsMiddleware) Acknowledge(ctx context.Context, msg mainflux.RawMessage) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "acknowledge").Add(1)
+		ms.latency.With("method", "acknowledge").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Acknowledge(ctx, msg)
+}
+
+func (ms *metricsMiddleware) Expire(ctx context.Context) (uint64, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "expire").Add(1)
+		ms.latency.With("method", "expire").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Expire(ctx)
+}
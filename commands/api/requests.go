@@ -0,0 +1,75 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"encoding/json"
+
+	"github.com/mainflux/mainflux/commands"
+)
+
+const maxLimitSize = 100
+
+type apiReq interface {
+	validate() error
+}
+
+type sendReq struct {
+	token   string
+	thingID string
+	Channel string          `json:"channel"`
+	Name    string          `json:"name"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+	TTL     uint64          `json:"ttl,omitempty"`
+}
+
+func (req sendReq) validate() error {
+	if req.token == "" {
+		return commands.ErrUnauthorizedAccess
+	}
+
+	if req.thingID == "" || req.Channel == "" || req.Name == "" {
+		return commands.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type viewReq struct {
+	token   string
+	thingID string
+	id      string
+}
+
+func (req viewReq) validate() error {
+	if req.token == "" {
+		return commands.ErrUnauthorizedAccess
+	}
+
+	if req.thingID == "" || req.id == "" {
+		return commands.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type listReq struct {
+	token   string
+	thingID string
+	state   string
+	offset  uint64
+	limit   uint64
+}
+
+func (req listReq) validate() error {
+	if req.token == "" {
+		return commands.ErrUnauthorizedAccess
+	}
+
+	if req.thingID == "" || req.limit == 0 || req.limit > maxLimitSize {
+		return commands.ErrMalformedEntity
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/mainflux/mainflux"
+)
+
+var (
+	_ mainflux.Response = (*sendRes)(nil)
+	_ mainflux.Response = (*commandRes)(nil)
+	_ mainflux.Response = (*commandsPageRes)(nil)
+)
+
+type sendRes struct {
+	thingID string
+	id      string
+}
+
+func (res sendRes) Code() int {
+	return http.StatusCreated
+}
+
+func (res sendRes) Headers() map[string]string {
+	return map[string]string{
+		"Location": fmt.Sprintf("/things/%s/commands/%s", res.thingID, res.id),
+	}
+}
+
+func (res sendRes) Empty() bool {
+	return true
+}
+
+type commandRes struct {
+	ID        string          `json:"id"`
+	Thing     string          `json:"thing"`
+	Channel   string          `json:"channel"`
+	Name      string          `json:"name"`
+	Payload   json.RawMessage `json:"payload,omitempty"`
+	State     string          `json:"state"`
+	Response  json.RawMessage `json:"response,omitempty"`
+	Created   time.Time       `json:"created"`
+	Expires   time.Time       `json:"expires"`
+	Delivered *time.Time      `json:"delivered,omitempty"`
+	Acked     *time.Time      `json:"acked,omitempty"`
+}
+
+func (res commandRes) Code() int {
+	return http.StatusOK
+}
+
+func (res commandRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res commandRes) Empty() bool {
+	return false
+}
+
+type commandsPageRes struct {
+	Total    uint64       `json:"total"`
+	Offset   uint64       `json:"offset"`
+	Limit    uint64       `json:"limit"`
+	Commands []commandRes `json:"commands"`
+}
+
+func (res commandsPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res commandsPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res commandsPageRes) Empty() bool {
+	return false
+}
@@ -0,0 +1,97 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"context"
+	"time"
+)
+
+// Command states. Command is pending until it's published, and delivered
+// once it's published to the thing control subtopic. Acknowledged command
+// is acked, and the command which isn't acknowledged in time is expired.
+// Acked and expired states are final.
+const (
+	StatePending   = "pending"
+	StateDelivered = "delivered"
+	StateAcked     = "acked"
+	StateExpired   = "expired"
+)
+
+var states = map[string]bool{
+	StatePending:   true,
+	StateDelivered: true,
+	StateAcked:     true,
+	StateExpired:   true,
+}
+
+// Command represents the instruction sent to the thing.
+type Command struct {
+	ID        string
+	Owner     string
+	Thing     string
+	Channel   string
+	Name      string
+	Payload   []byte
+	State     string
+	Response  []byte
+	Created   time.Time
+	Expires   time.Time
+	Delivered time.Time
+	Acked     time.Time
+}
+
+// CommandsPage contains page related metadata as well as list of commands
+// that belong to this page.
+type CommandsPage struct {
+	Total    uint64
+	Offset   uint64
+	Limit    uint64
+	Commands []Command
+}
+
+// CommandRepository specifies command persistence API. State transitions
+// are conditional, so that the concurrent transitions can't move the
+// command back from the final state.
+type CommandRepository interface {
+	// Save persists the command.
+	Save(context.Context, Command) error
+
+	// RetrieveByID retrieves the command having the provided identifier.
+	RetrieveByID(context.Context, string) (Command, error)
+
+	// RetrieveAll retrieves the subset of the commands of the thing, newest
+	// first. Empty state matches all the states.
+	RetrieveAll(ctx context.Context, thing, state string, offset, limit uint64) (CommandsPage, error)
+
+	// MarkDelivered moves the pending command to the delivered state. The
+	// command which isn't pending anymore is left intact.
+	MarkDelivered(ctx context.Context, id string, at time.Time) error
+
+	// MarkAcked moves the pending or delivered command which hasn't expired
+	// at the given time to the acked state, and stores the response. If the
+	// command can't be acknowledged, ErrInvalidState is returned.
+	MarkAcked(ctx context.Context, id string, response []byte, at time.Time) error
+
+	// Expire moves the pending and delivered commands which have expired
+	// at the given time to the expired state, and returns their number.
+	Expire(ctx context.Context, at time.Time) (uint64, error)
+}
+
+// Things specifies an API for checking the things ownership and connections.
+type Things interface {
+	// Authorize checks whether the user identified by the provided key owns
+	// the thing.
+	Authorize(token, thingID string) error
+
+	// Connected checks whether the user identified by the provided key owns
+	// the thing, and whether the thing is connected to the channel.
+	Connected(token, thingID, chanID string) error
+}
+
+// IdentityProvider specifies an API for generating unique identifiers.
+type IdentityProvider interface {
+	// ID generates the unique identifier.
+	ID() (string, error)
+}
@@ -0,0 +1,9 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package commands contains the domain concept definitions needed to support
+// Mainflux remote commands service functionality. Commands are published to
+// the control subtopic of the thing, and the things acknowledge them over the
+// same channel. Each command is kept along with its delivery state, which
+// forms the command history of the thing.
+package commands
@@ -0,0 +1,139 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mainflux/mainflux/commands"
+)
+
+var _ commands.CommandRepository = (*commandRepositoryMock)(nil)
+
+type commandRepositoryMock struct {
+	mu       sync.Mutex
+	commands map[string]commands.Command
+}
+
+// NewCommandRepository creates in-memory command repository.
+func NewCommandRepository() commands.CommandRepository {
+	return &commandRepositoryMock{
+		commands: make(map[string]commands.Command),
+	}
+}
+
+func (crm *commandRepositoryMock) Save(_ context.Context, cmd commands.Command) error {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	crm.commands[cmd.ID] = cmd
+	return nil
+}
+
+func (crm *commandRepositoryMock) RetrieveByID(_ context.Context, id string) (commands.Command, error) {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	cmd, ok := crm.commands[id]
+	if !ok {
+		return commands.Command{}, commands.ErrNotFound
+	}
+
+	return cmd, nil
+}
+
+func (crm *commandRepositoryMock) RetrieveAll(_ context.Context, thing, state string, offset, limit uint64) (commands.CommandsPage, error) {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	items := []commands.Command{}
+	for _, cmd := range crm.commands {
+		if cmd.Thing == thing && (state == "" || cmd.State == state) {
+			items = append(items, cmd)
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].ID > items[j].ID
+	})
+
+	page := commands.CommandsPage{
+		Total:    uint64(len(items)),
+		Offset:   offset,
+		Limit:    limit,
+		Commands: []commands.Command{},
+	}
+
+	if offset >= uint64(len(items)) {
+		return page, nil
+	}
+
+	end := offset + limit
+	if end > uint64(len(items)) {
+		end = uint64(len(items))
+	}
+	page.Commands = items[offset:end]
+
+	return page, nil
+}
+
+func (crm *commandRepositoryMock) MarkDelivered(_ context.Context, id string, at time.Time) error {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	cmd, ok := crm.commands[id]
+	if !ok {
+		return commands.ErrNotFound
+	}
+
+	if cmd.State == commands.StatePending {
+		cmd.State = commands.StateDelivered
+		cmd.Delivered = at
+		crm.commands[id] = cmd
+	}
+
+	return nil
+}
+
+func (crm *commandRepositoryMock) MarkAcked(_ context.Context, id string, response []byte, at time.Time) error {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	cmd, ok := crm.commands[id]
+	if !ok {
+		return commands.ErrNotFound
+	}
+
+	if cmd.State != commands.StatePending && cmd.State != commands.StateDelivered || !at.Before(cmd.Expires) {
+		return commands.ErrInvalidState
+	}
+
+	cmd.State = commands.StateAcked
+	cmd.Response = response
+	cmd.Acked = at
+	crm.commands[id] = cmd
+
+	return nil
+}
+
+func (crm *commandRepositoryMock) Expire(_ context.Context, at time.Time) (uint64, error) {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	var n uint64
+	for id, cmd := range crm.commands {
+		if cmd.State != commands.StatePending && cmd.State != commands.StateDelivered || at.Before(cmd.Expires) {
+			continue
+		}
+
+		cmd.State = commands.StateExpired
+		crm.commands[id] = cmd
+		n++
+	}
+
+	return n, nil
+}
@@ -0,0 +1,44 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import "github.com/mainflux/mainflux/commands"
+
+var _ commands.Things = (*thingsMock)(nil)
+
+type thingsMock struct {
+	things      map[string]string
+	connections map[string][]string
+}
+
+// NewThings creates mock of things API. Things are mapped to the keys of
+// their owners, and connections map the things to the connected channels.
+func NewThings(things map[string]string, connections map[string][]string) commands.Things {
+	return thingsMock{
+		things:      things,
+		connections: connections,
+	}
+}
+
+func (tm thingsMock) Authorize(token, thingID string) error {
+	if tm.things[thingID] != token {
+		return commands.ErrNotFound
+	}
+
+	return nil
+}
+
+func (tm thingsMock) Connected(token, thingID, chanID string) error {
+	if err := tm.Authorize(token, thingID); err != nil {
+		return err
+	}
+
+	for _, ch := range tm.connections[thingID] {
+		if ch == chanID {
+			return nil
+		}
+	}
+
+	return commands.ErrNotFound
+}
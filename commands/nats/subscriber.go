@@ -0,0 +1,53 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package nats contains NATS subscriber which feeds the command
+// acknowledgments to the commands service.
+package nats
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/commands"
+	log "github.com/mainflux/mainflux/logger"
+	mfnats "github.com/mainflux/mainflux/nats"
+	broker "github.com/nats-io/nats.go"
+)
+
+const queue = "commands"
+
+type subscriber struct {
+	svc    commands.Service
+	logger log.Logger
+}
+
+// Subscribe subscribes to the acknowledgment subtopic of all the channels
+// and feeds the acknowledgments to the commands service. Queue subscription
+// ensures each acknowledgment is recorded by exactly one service instance.
+// The subject is prefixed with the deployment subject prefix, unless it is
+// empty.
+func Subscribe(svc commands.Service, nc *broker.Conn, subjectPrefix string, logger log.Logger) error {
+	s := subscriber{
+		svc:    svc,
+		logger: logger,
+	}
+
+	subject := fmt.Sprintf("channel.*.%s", commands.AckSubtopic)
+	_, err := nc.QueueSubscribe(mfnats.Subject(subjectPrefix, subject), queue, s.handleMsg)
+	return err
+}
+
+func (s subscriber) handleMsg(m *broker.Msg) {
+	var msg mainflux.RawMessage
+	if err := proto.Unmarshal(m.Data, &msg); err != nil {
+		s.logger.Warn(fmt.Sprintf("Failed to unmarshal received message: %s", err))
+		return
+	}
+
+	if err := s.svc.Acknowledge(context.Background(), msg); err != nil {
+		s.logger.Warn(fmt.Sprintf("Failed to record acknowledgment of thing %s: %s", msg.Publisher, err))
+	}
+}
@@ -0,0 +1,227 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq" // required for DB access
+	"github.com/mainflux/mainflux/commands"
+)
+
+const (
+	errDuplicate = "unique_violation"
+	errInvalid   = "invalid_text_representation"
+)
+
+var _ commands.CommandRepository = (*commandRepository)(nil)
+
+type commandRepository struct {
+	db *sqlx.DB
+}
+
+// NewCommandRepository instantiates a PostgreSQL implementation of command
+// repository.
+func NewCommandRepository(db *sqlx.DB) commands.CommandRepository {
+	return &commandRepository{
+		db: db,
+	}
+}
+
+func (cr commandRepository) Save(ctx context.Context, cmd commands.Command) error {
+	q := `INSERT INTO commands (id, owner, thing, channel, name, payload, state, response, created, expires, delivered, acked)
+	      VALUES (:id, :owner, :thing, :channel, :name, :payload, :state, :response, :created, :expires, :delivered, :acked);`
+
+	if _, err := cr.db.NamedExecContext(ctx, q, toDBCommand(cmd)); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code.Name() {
+			case errDuplicate, errInvalid:
+				return commands.ErrMalformedEntity
+			}
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (cr commandRepository) RetrieveByID(ctx context.Context, id string) (commands.Command, error) {
+	q := `SELECT id, owner, thing, channel, name, payload, state, response, created, expires, delivered, acked
+	      FROM commands WHERE id = $1;`
+
+	var dbc dbCommand
+	if err := cr.db.QueryRowxContext(ctx, q, id).StructScan(&dbc); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if err == sql.ErrNoRows || ok && errInvalid == pqErr.Code.Name() {
+			return commands.Command{}, commands.ErrNotFound
+		}
+		return commands.Command{}, err
+	}
+
+	return toCommand(dbc), nil
+}
+
+func (cr commandRepository) RetrieveAll(ctx context.Context, thing, state string, offset, limit uint64) (commands.CommandsPage, error) {
+	q := `SELECT id, owner, thing, channel, name, payload, state, response, created, expires, delivered, acked
+	      FROM commands WHERE thing = $1 AND ($2 = '' OR state = $2)
+	      ORDER BY created DESC, id DESC LIMIT $3 OFFSET $4;`
+
+	rows, err := cr.db.QueryxContext(ctx, q, thing, state, limit, offset)
+	if err != nil {
+		return commands.CommandsPage{}, err
+	}
+	defer rows.Close()
+
+	items := []commands.Command{}
+	for rows.Next() {
+		var dbc dbCommand
+		if err := rows.StructScan(&dbc); err != nil {
+			return commands.CommandsPage{}, err
+		}
+		items = append(items, toCommand(dbc))
+	}
+
+	if err := rows.Err(); err != nil {
+		return commands.CommandsPage{}, err
+	}
+
+	cq := `SELECT COUNT(*) FROM commands WHERE thing = $1 AND ($2 = '' OR state = $2);`
+
+	var total uint64
+	if err := cr.db.GetContext(ctx, &total, cq, thing, state); err != nil {
+		return commands.CommandsPage{}, err
+	}
+
+	page := commands.CommandsPage{
+		Total:    total,
+		Offset:   offset,
+		Limit:    limit,
+		Commands: items,
+	}
+
+	return page, nil
+}
+
+func (cr commandRepository) MarkDelivered(ctx context.Context, id string, at time.Time) error {
+	q := `UPDATE commands SET state = $2, delivered = $3 WHERE id = $1 AND state = $4;`
+
+	if _, err := cr.db.ExecContext(ctx, q, id, commands.StateDelivered, at, commands.StatePending); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == errInvalid {
+			return commands.ErrNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (cr commandRepository) MarkAcked(ctx context.Context, id string, response []byte, at time.Time) error {
+	q := `UPDATE commands SET state = $2, response = $3, acked = $4
+	      WHERE id = $1 AND state IN ($5, $6) AND expires > $4;`
+
+	res, err := cr.db.ExecContext(ctx, q, id, commands.StateAcked, nullJSON(response), at, commands.StatePending, commands.StateDelivered)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == errInvalid {
+			return commands.ErrMalformedEntity
+		}
+		return err
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if cnt == 0 {
+		return commands.ErrInvalidState
+	}
+
+	return nil
+}
+
+func (cr commandRepository) Expire(ctx context.Context, at time.Time) (uint64, error) {
+	q := `UPDATE commands SET state = $1 WHERE state IN ($2, $3) AND expires <= $4;`
+
+	res, err := cr.db.ExecContext(ctx, q, commands.StateExpired, commands.StatePending, commands.StateDelivered, at)
+	if err != nil {
+		return 0, err
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(cnt), nil
+}
+
+type dbCommand struct {
+	ID        string      `db:"id"`
+	Owner     string      `db:"owner"`
+	Thing     string      `db:"thing"`
+	Channel   string      `db:"channel"`
+	Name      string      `db:"name"`
+	Payload   []byte      `db:"payload"`
+	State     string      `db:"state"`
+	Response  []byte      `db:"response"`
+	Created   time.Time   `db:"created"`
+	Expires   time.Time   `db:"expires"`
+	Delivered pq.NullTime `db:"delivered"`
+	Acked     pq.NullTime `db:"acked"`
+}
+
+func toDBCommand(cmd commands.Command) dbCommand {
+	return dbCommand{
+		ID:        cmd.ID,
+		Owner:     cmd.Owner,
+		Thing:     cmd.Thing,
+		Channel:   cmd.Channel,
+		Name:      cmd.Name,
+		Payload:   nullJSON(cmd.Payload),
+		State:     cmd.State,
+		Response:  nullJSON(cmd.Response),
+		Created:   cmd.Created,
+		Expires:   cmd.Expires,
+		Delivered: pq.NullTime{Time: cmd.Delivered, Valid: !cmd.Delivered.IsZero()},
+		Acked:     pq.NullTime{Time: cmd.Acked, Valid: !cmd.Acked.IsZero()},
+	}
+}
+
+func toCommand(dbc dbCommand) commands.Command {
+	cmd := commands.Command{
+		ID:       dbc.ID,
+		Owner:    dbc.Owner,
+		Thing:    dbc.Thing,
+		Channel:  dbc.Channel,
+		Name:     dbc.Name,
+		Payload:  dbc.Payload,
+		State:    dbc.State,
+		Response: dbc.Response,
+		Created:  dbc.Created.UTC(),
+		Expires:  dbc.Expires.UTC(),
+	}
+
+	if dbc.Delivered.Valid {
+		cmd.Delivered = dbc.Delivered.Time.UTC()
+	}
+
+	if dbc.Acked.Valid {
+		cmd.Acked = dbc.Acked.Time.UTC()
+	}
+
+	return cmd
+}
+
+// nullJSON maps the empty JSON document to NULL, since an empty string
+// isn't valid JSONB.
+func nullJSON(data []byte) []byte {
+	if len(data) == 0 {
+		return nil
+	}
+
+	return data
+}
@@ -0,0 +1,262 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/commands"
+	"github.com/mainflux/mainflux/commands/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	cmdID   = "123e4567-e89b-12d3-a456-000000000001"
+	otherID = "123e4567-e89b-12d3-a456-000000000002"
+	wrongID = "123e4567-e89b-12d3-a456-000000000099"
+	owner   = "user@example.com"
+	thingID = "thing"
+	chanID  = "1"
+)
+
+func newCommand(id string, created time.Time, ttl time.Duration) commands.Command {
+	return commands.Command{
+		ID:      id,
+		Owner:   owner,
+		Thing:   thingID,
+		Channel: chanID,
+		Name:    "reboot",
+		Payload: []byte(`{"delay":5}`),
+		State:   commands.StatePending,
+		Created: created,
+		Expires: created.Add(ttl),
+	}
+}
+
+func cleanup() {
+	db.Exec("DELETE FROM commands")
+}
+
+func TestCommandSave(t *testing.T) {
+	defer cleanup()
+	repo := postgres.NewCommandRepository(db)
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	noPayload := newCommand(otherID, now, time.Minute)
+	noPayload.Payload = nil
+
+	cases := []struct {
+		desc string
+		cmd  commands.Command
+		err  error
+	}{
+		{
+			desc: "save new command",
+			cmd:  newCommand(cmdID, now, time.Minute),
+			err:  nil,
+		},
+		{
+			desc: "save command with existing ID",
+			cmd:  newCommand(cmdID, now, time.Minute),
+			err:  commands.ErrMalformedEntity,
+		},
+		{
+			desc: "save command without payload",
+			cmd:  noPayload,
+			err:  nil,
+		},
+	}
+
+	for _, tc := range cases {
+		err := repo.Save(context.Background(), tc.cmd)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestCommandRetrieveByID(t *testing.T) {
+	defer cleanup()
+	repo := postgres.NewCommandRepository(db)
+	cmd := newCommand(cmdID, time.Now().UTC().Truncate(time.Millisecond), time.Minute)
+	err := repo.Save(context.Background(), cmd)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc string
+		id   string
+		cmd  commands.Command
+		err  error
+	}{
+		{
+			desc: "retrieve existing command",
+			id:   cmdID,
+			cmd:  cmd,
+			err:  nil,
+		},
+		{
+			desc: "retrieve non-existing command",
+			id:   wrongID,
+			cmd:  commands.Command{},
+			err:  commands.ErrNotFound,
+		},
+		{
+			desc: "retrieve command with malformed ID",
+			id:   "invalid",
+			cmd:  commands.Command{},
+			err:  commands.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		cmd, err := repo.RetrieveByID(context.Background(), tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.cmd.ID, cmd.ID, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.cmd.ID, cmd.ID))
+		assert.JSONEq(t, string(orEmpty(tc.cmd.Payload)), string(orEmpty(cmd.Payload)), fmt.Sprintf("%s: unexpected payload %s\n", tc.desc, cmd.Payload))
+	}
+}
+
+func TestCommandRetrieveAll(t *testing.T) {
+	defer cleanup()
+	repo := postgres.NewCommandRepository(db)
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	for i, id := range []string{cmdID, otherID} {
+		err := repo.Save(context.Background(), newCommand(id, now.Add(time.Duration(i)*time.Second), time.Minute))
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+	err := repo.MarkDelivered(context.Background(), otherID, now)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		thing  string
+		state  string
+		offset uint64
+		limit  uint64
+		ids    []string
+		total  uint64
+	}{
+		{
+			desc:   "retrieve all commands",
+			thing:  thingID,
+			offset: 0,
+			limit:  10,
+			ids:    []string{otherID, cmdID},
+			total:  2,
+		},
+		{
+			desc:   "retrieve commands page",
+			thing:  thingID,
+			offset: 1,
+			limit:  10,
+			ids:    []string{cmdID},
+			total:  2,
+		},
+		{
+			desc:   "retrieve delivered commands",
+			thing:  thingID,
+			state:  commands.StateDelivered,
+			offset: 0,
+			limit:  10,
+			ids:    []string{otherID},
+			total:  1,
+		},
+		{
+			desc:   "retrieve commands of other thing",
+			thing:  "other",
+			offset: 0,
+			limit:  10,
+			ids:    []string{},
+			total:  0,
+		},
+	}
+
+	for _, tc := range cases {
+		page, err := repo.RetrieveAll(context.Background(), tc.thing, tc.state, tc.offset, tc.limit)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		ids := []string{}
+		for _, cmd := range page.Commands {
+			ids = append(ids, cmd.ID)
+		}
+		assert.Equal(t, tc.ids, ids, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.ids, ids))
+		assert.Equal(t, tc.total, page.Total, fmt.Sprintf("%s: expected %d got %d\n", tc.desc, tc.total, page.Total))
+	}
+}
+
+func TestCommandStateTransitions(t *testing.T) {
+	defer cleanup()
+	repo := postgres.NewCommandRepository(db)
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	err := repo.Save(context.Background(), newCommand(cmdID, now, time.Minute))
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = repo.Save(context.Background(), newCommand(otherID, now, time.Minute))
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	err = repo.MarkDelivered(context.Background(), cmdID, now)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc string
+		id   string
+		at   time.Time
+		err  error
+	}{
+		{
+			desc: "acknowledge expired command",
+			id:   cmdID,
+			at:   now.Add(time.Minute),
+			err:  commands.ErrInvalidState,
+		},
+		{
+			desc: "acknowledge delivered command",
+			id:   cmdID,
+			at:   now,
+			err:  nil,
+		},
+		{
+			desc: "acknowledge acked command",
+			id:   cmdID,
+			at:   now,
+			err:  commands.ErrInvalidState,
+		},
+		{
+			desc: "acknowledge non-existing command",
+			id:   wrongID,
+			at:   now,
+			err:  commands.ErrInvalidState,
+		},
+	}
+
+	for _, tc := range cases {
+		err := repo.MarkAcked(context.Background(), tc.id, []byte(`{"ok":true}`), tc.at)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	// Acked command is neither delivered nor expired again.
+	err = repo.MarkDelivered(context.Background(), cmdID, now)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	n, err := repo.Expire(context.Background(), now.Add(time.Hour))
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, uint64(1), n, fmt.Sprintf("expected 1 expired command got %d", n))
+
+	acked, err := repo.RetrieveByID(context.Background(), cmdID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, commands.StateAcked, acked.State, fmt.Sprintf("expected %s got %s", commands.StateAcked, acked.State))
+	assert.JSONEq(t, `{"ok":true}`, string(acked.Response), fmt.Sprintf("unexpected response %s", acked.Response))
+	assert.Equal(t, now, acked.Acked, fmt.Sprintf("expected %s got %s", now, acked.Acked))
+
+	expired, err := repo.RetrieveByID(context.Background(), otherID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, commands.StateExpired, expired.State, fmt.Sprintf("expected %s got %s", commands.StateExpired, expired.State))
+}
+
+func orEmpty(data []byte) []byte {
+	if len(data) == 0 {
+		return []byte("null")
+	}
+
+	return data
+}
@@ -0,0 +1,264 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mainflux/mainflux"
+)
+
+const (
+	// Protocol is the protocol of the commands published by the service.
+	Protocol = "commands"
+
+	// CommandSubtopic is the prefix of the channel subtopic the commands are
+	// delivered to. The thing ID is appended to it, so that each thing
+	// subscribes only to its own commands.
+	CommandSubtopic = "commands"
+
+	// AckSubtopic is the channel subtopic the things acknowledge the
+	// commands on.
+	AckSubtopic = "commands.ack"
+)
+
+var (
+	// ErrMalformedEntity indicates malformed entity specification.
+	ErrMalformedEntity = errors.New("malformed entity specification")
+
+	// ErrUnauthorizedAccess indicates missing or invalid credentials provided
+	// when accessing a protected resource.
+	ErrUnauthorizedAccess = errors.New("missing or invalid credentials provided")
+
+	// ErrNotFound indicates a non-existent entity request.
+	ErrNotFound = errors.New("non-existent entity")
+
+	// ErrInvalidState indicates that the command can't be acknowledged in
+	// its current state.
+	ErrInvalidState = errors.New("operation not allowed in the current state")
+)
+
+// Message is the payload of the message delivered to the thing.
+type Message struct {
+	ID      string          `json:"id"`
+	Name    string          `json:"name"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+	Expires time.Time       `json:"expires"`
+}
+
+// Ack is the payload of the acknowledgment published by the thing.
+type Ack struct {
+	ID       string          `json:"id"`
+	Response json.RawMessage `json:"response,omitempty"`
+}
+
+// Service specifies an API that must be fulfilled by the domain service
+// implementation, and all of its decorators (e.g. logging & metrics).
+type Service interface {
+	// Send persists the command and publishes it to the thing control
+	// subtopic of the command channel. Command expires after the given TTL,
+	// or after the default TTL if the given one is zero.
+	Send(context.Context, string, Command, time.Duration) (Command, error)
+
+	// ViewCommand retrieves the command identified by the provided ID, that
+	// was sent to the thing owned by the user identified by the provided key.
+	ViewCommand(ctx context.Context, token, thingID, id string) (Command, error)
+
+	// ListCommands retrieves the command history of the thing owned by the
+	// user identified by the provided key. Empty state matches all states.
+	ListCommands(ctx context.Context, token, thingID, state string, offset, limit uint64) (CommandsPage, error)
+
+	// Acknowledge records the acknowledgment published by the thing.
+	Acknowledge(context.Context, mainflux.RawMessage) error
+
+	// Expire moves the commands which weren't acknowledged in time to the
+	// expired state, and returns their number.
+	Expire(context.Context) (uint64, error)
+}
+
+var _ Service = (*commandsService)(nil)
+
+type commandsService struct {
+	users     mainflux.UsersServiceClient
+	things    Things
+	commands  CommandRepository
+	publisher mainflux.MessagePublisher
+	idp       IdentityProvider
+	defTTL    time.Duration
+	maxTTL    time.Duration
+}
+
+// New instantiates the commands service implementation. Commands sent
+// without the TTL expire after defTTL, and the TTL can't exceed maxTTL.
+func New(users mainflux.UsersServiceClient, things Things, commands CommandRepository, publisher mainflux.MessagePublisher, idp IdentityProvider, defTTL, maxTTL time.Duration) Service {
+	return &commandsService{
+		users:     users,
+		things:    things,
+		commands:  commands,
+		publisher: publisher,
+		idp:       idp,
+		defTTL:    defTTL,
+		maxTTL:    maxTTL,
+	}
+}
+
+func (cs *commandsService) Send(ctx context.Context, token string, cmd Command, ttl time.Duration) (Command, error) {
+	owner, err := cs.identify(ctx, token)
+	if err != nil {
+		return Command{}, err
+	}
+
+	if cmd.Thing == "" || cmd.Channel == "" || cmd.Name == "" {
+		return Command{}, ErrMalformedEntity
+	}
+
+	if len(cmd.Payload) > 0 && !json.Valid(cmd.Payload) {
+		return Command{}, ErrMalformedEntity
+	}
+
+	if ttl == 0 {
+		ttl = cs.defTTL
+	}
+
+	if ttl < 0 || ttl > cs.maxTTL {
+		return Command{}, ErrMalformedEntity
+	}
+
+	if err := cs.things.Connected(token, cmd.Thing, cmd.Channel); err != nil {
+		return Command{}, err
+	}
+
+	cmd.ID, err = cs.idp.ID()
+	if err != nil {
+		return Command{}, err
+	}
+
+	now := time.Now().UTC()
+	cmd.Owner = owner
+	cmd.State = StatePending
+	cmd.Response = nil
+	cmd.Created = now
+	cmd.Expires = now.Add(ttl)
+	cmd.Delivered = time.Time{}
+	cmd.Acked = time.Time{}
+
+	if err := cs.commands.Save(ctx, cmd); err != nil {
+		return Command{}, err
+	}
+
+	// Command which couldn't be published stays pending until it expires,
+	// so that the failed attempt is kept in the history.
+	if err := cs.publish(ctx, cmd); err != nil {
+		return Command{}, err
+	}
+
+	if err := cs.commands.MarkDelivered(ctx, cmd.ID, time.Now().UTC()); err != nil {
+		return Command{}, err
+	}
+
+	// Thing may have acknowledged the command already.
+	return cs.commands.RetrieveByID(ctx, cmd.ID)
+}
+
+func (cs *commandsService) ViewCommand(ctx context.Context, token, thingID, id string) (Command, error) {
+	if _, err := cs.identify(ctx, token); err != nil {
+		return Command{}, err
+	}
+
+	if err := cs.things.Authorize(token, thingID); err != nil {
+		return Command{}, err
+	}
+
+	cmd, err := cs.commands.RetrieveByID(ctx, id)
+	if err != nil {
+		return Command{}, err
+	}
+
+	if cmd.Thing != thingID {
+		return Command{}, ErrNotFound
+	}
+
+	return cmd, nil
+}
+
+func (cs *commandsService) ListCommands(ctx context.Context, token, thingID, state string, offset, limit uint64) (CommandsPage, error) {
+	if _, err := cs.identify(ctx, token); err != nil {
+		return CommandsPage{}, err
+	}
+
+	if state != "" && !states[state] {
+		return CommandsPage{}, ErrMalformedEntity
+	}
+
+	// History is kept per thing, so it's visible to the current owner of
+	// the thing, regardless of who sent the commands.
+	if err := cs.things.Authorize(token, thingID); err != nil {
+		return CommandsPage{}, err
+	}
+
+	return cs.commands.RetrieveAll(ctx, thingID, state, offset, limit)
+}
+
+func (cs *commandsService) Acknowledge(ctx context.Context, msg mainflux.RawMessage) error {
+	var ack Ack
+	if err := json.Unmarshal(msg.Payload, &ack); err != nil {
+		return ErrMalformedEntity
+	}
+
+	if ack.ID == "" || msg.Publisher == "" {
+		return ErrMalformedEntity
+	}
+
+	cmd, err := cs.commands.RetrieveByID(ctx, ack.ID)
+	if err != nil {
+		return err
+	}
+
+	// Only the thing the command was sent to can acknowledge it, and only
+	// over the channel it was sent over.
+	if cmd.Thing != msg.Publisher || cmd.Channel != msg.Channel {
+		return ErrNotFound
+	}
+
+	return cs.commands.MarkAcked(ctx, cmd.ID, ack.Response, time.Now().UTC())
+}
+
+func (cs *commandsService) Expire(ctx context.Context) (uint64, error) {
+	return cs.commands.Expire(ctx, time.Now().UTC())
+}
+
+func (cs *commandsService) identify(ctx context.Context, token string) (string, error) {
+	res, err := cs.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return "", ErrUnauthorizedAccess
+	}
+
+	return res.GetValue(), nil
+}
+
+func (cs *commandsService) publish(ctx context.Context, cmd Command) error {
+	payload, err := json.Marshal(Message{
+		ID:      cmd.ID,
+		Name:    cmd.Name,
+		Payload: cmd.Payload,
+		Expires: cmd.Expires,
+	})
+	if err != nil {
+		return err
+	}
+
+	msg := mainflux.RawMessage{
+		Channel:     cmd.Channel,
+		Subtopic:    fmt.Sprintf("%s.%s", CommandSubtopic, cmd.Thing),
+		Protocol:    Protocol,
+		ContentType: "application/json",
+		Payload:     payload,
+	}
+
+	return cs.publisher.Publish(ctx, "", msg)
+}
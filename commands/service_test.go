@@ -0,0 +1,407 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package commands_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/commands"
+	"github.com/mainflux/mainflux/commands/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	wrongValue = "wrong-value"
+	email      = "user@example.com"
+	token      = "token"
+	otherEmail = "other@example.com"
+	otherToken = "other"
+	thingID    = "thing"
+	otherThing = "other-thing"
+	chanID     = "1"
+	otherChan  = "2"
+	defTTL     = time.Minute
+	maxTTL     = time.Hour
+)
+
+var command = commands.Command{
+	Thing:   thingID,
+	Channel: chanID,
+	Name:    "reboot",
+	Payload: []byte(`{"delay":5}`),
+}
+
+func newService() (commands.Service, *mocks.Publisher) {
+	users := mocks.NewUsersService(map[string]string{token: email, otherToken: otherEmail})
+	things := mocks.NewThings(
+		map[string]string{thingID: token, otherThing: otherToken},
+		map[string][]string{thingID: {chanID}, otherThing: {otherChan}},
+	)
+	pub := mocks.NewPublisher()
+
+	return commands.New(users, things, mocks.NewCommandRepository(), pub, mocks.NewIdentityProvider(), defTTL, maxTTL), pub
+}
+
+func ack(thing, chanID, id string) mainflux.RawMessage {
+	payload, _ := json.Marshal(commands.Ack{ID: id, Response: json.RawMessage(`{"ok":true}`)})
+	return mainflux.RawMessage{
+		Channel:   chanID,
+		Subtopic:  commands.AckSubtopic,
+		Publisher: thing,
+		Payload:   payload,
+	}
+}
+
+func TestSend(t *testing.T) {
+	svc, pub := newService()
+
+	noPayload := command
+	noPayload.Payload = nil
+	invalidPayload := command
+	invalidPayload.Payload = []byte("{")
+	noName := command
+	noName.Name = ""
+	notConnected := command
+	notConnected.Channel = otherChan
+	otherUsers := command
+	otherUsers.Thing = otherThing
+	otherUsers.Channel = otherChan
+
+	cases := []struct {
+		desc  string
+		token string
+		cmd   commands.Command
+		ttl   time.Duration
+		err   error
+	}{
+		{
+			desc:  "send command",
+			token: token,
+			cmd:   command,
+			ttl:   0,
+			err:   nil,
+		},
+		{
+			desc:  "send command with TTL",
+			token: token,
+			cmd:   command,
+			ttl:   maxTTL,
+			err:   nil,
+		},
+		{
+			desc:  "send command without payload",
+			token: token,
+			cmd:   noPayload,
+			err:   nil,
+		},
+		{
+			desc:  "send command with TTL exceeding maximal TTL",
+			token: token,
+			cmd:   command,
+			ttl:   maxTTL + time.Second,
+			err:   commands.ErrMalformedEntity,
+		},
+		{
+			desc:  "send command with negative TTL",
+			token: token,
+			cmd:   command,
+			ttl:   -time.Second,
+			err:   commands.ErrMalformedEntity,
+		},
+		{
+			desc:  "send command with invalid payload",
+			token: token,
+			cmd:   invalidPayload,
+			err:   commands.ErrMalformedEntity,
+		},
+		{
+			desc:  "send command without name",
+			token: token,
+			cmd:   noName,
+			err:   commands.ErrMalformedEntity,
+		},
+		{
+			desc:  "send command over not connected channel",
+			token: token,
+			cmd:   notConnected,
+			err:   commands.ErrNotFound,
+		},
+		{
+			desc:  "send command to other user's thing",
+			token: token,
+			cmd:   otherUsers,
+			err:   commands.ErrNotFound,
+		},
+		{
+			desc:  "send command with invalid credentials",
+			token: wrongValue,
+			cmd:   command,
+			err:   commands.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		before := len(pub.Published())
+		cmd, err := svc.Send(context.Background(), tc.token, tc.cmd, tc.ttl)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err != nil {
+			assert.Equal(t, before, len(pub.Published()), fmt.Sprintf("%s: unexpected command published\n", tc.desc))
+			continue
+		}
+
+		ttl := tc.ttl
+		if ttl == 0 {
+			ttl = defTTL
+		}
+		assert.Equal(t, commands.StateDelivered, cmd.State, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, commands.StateDelivered, cmd.State))
+		assert.Equal(t, cmd.Created.Add(ttl), cmd.Expires, fmt.Sprintf("%s: expected expiry %s got %s\n", tc.desc, cmd.Created.Add(ttl), cmd.Expires))
+
+		msgs := pub.Published()
+		msg := msgs[len(msgs)-1]
+		assert.Equal(t, tc.cmd.Channel, msg.Channel, fmt.Sprintf("%s: expected channel %s got %s\n", tc.desc, tc.cmd.Channel, msg.Channel))
+		subtopic := fmt.Sprintf("%s.%s", commands.CommandSubtopic, tc.cmd.Thing)
+		assert.Equal(t, subtopic, msg.Subtopic, fmt.Sprintf("%s: expected subtopic %s got %s\n", tc.desc, subtopic, msg.Subtopic))
+
+		var m commands.Message
+		err = json.Unmarshal(msg.Payload, &m)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		assert.Equal(t, cmd.ID, m.ID, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, cmd.ID, m.ID))
+		assert.Equal(t, string(tc.cmd.Payload), string(m.Payload), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.cmd.Payload, m.Payload))
+	}
+}
+
+func TestSendPublishFailure(t *testing.T) {
+	svc, pub := newService()
+	pubErr := errors.New("broker unavailable")
+	pub.Fail(pubErr)
+
+	_, err := svc.Send(context.Background(), token, command, 0)
+	assert.Equal(t, pubErr, err, fmt.Sprintf("expected %s got %s", pubErr, err))
+
+	page, err := svc.ListCommands(context.Background(), token, thingID, commands.StatePending, 0, 10)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, uint64(1), page.Total, fmt.Sprintf("expected pending command to be kept, got %d", page.Total))
+}
+
+func TestViewCommand(t *testing.T) {
+	svc, _ := newService()
+	cmd, err := svc.Send(context.Background(), token, command, 0)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		token string
+		thing string
+		id    string
+		err   error
+	}{
+		{
+			desc:  "view existing command",
+			token: token,
+			thing: thingID,
+			id:    cmd.ID,
+			err:   nil,
+		},
+		{
+			desc:  "view command of other thing",
+			token: otherToken,
+			thing: otherThing,
+			id:    cmd.ID,
+			err:   commands.ErrNotFound,
+		},
+		{
+			desc:  "view command of other user's thing",
+			token: otherToken,
+			thing: thingID,
+			id:    cmd.ID,
+			err:   commands.ErrNotFound,
+		},
+		{
+			desc:  "view non-existing command",
+			token: token,
+			thing: thingID,
+			id:    wrongValue,
+			err:   commands.ErrNotFound,
+		},
+		{
+			desc:  "view command with invalid credentials",
+			token: wrongValue,
+			thing: thingID,
+			id:    cmd.ID,
+			err:   commands.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := svc.ViewCommand(context.Background(), tc.token, tc.thing, tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestListCommands(t *testing.T) {
+	svc, _ := newService()
+	var last commands.Command
+	for i := 0; i < 5; i++ {
+		cmd, err := svc.Send(context.Background(), token, command, 0)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		last = cmd
+	}
+	err := svc.Acknowledge(context.Background(), ack(thingID, chanID, last.ID))
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		token  string
+		thing  string
+		state  string
+		offset uint64
+		limit  uint64
+		size   int
+		err    error
+	}{
+		{
+			desc:   "list all commands",
+			token:  token,
+			thing:  thingID,
+			offset: 0,
+			limit:  10,
+			size:   5,
+			err:    nil,
+		},
+		{
+			desc:   "list commands page",
+			token:  token,
+			thing:  thingID,
+			offset: 3,
+			limit:  10,
+			size:   2,
+			err:    nil,
+		},
+		{
+			desc:   "list acked commands",
+			token:  token,
+			thing:  thingID,
+			state:  commands.StateAcked,
+			offset: 0,
+			limit:  10,
+			size:   1,
+			err:    nil,
+		},
+		{
+			desc:   "list commands with invalid state",
+			token:  token,
+			thing:  thingID,
+			state:  wrongValue,
+			offset: 0,
+			limit:  10,
+			size:   0,
+			err:    commands.ErrMalformedEntity,
+		},
+		{
+			desc:   "list commands of other user's thing",
+			token:  otherToken,
+			thing:  thingID,
+			offset: 0,
+			limit:  10,
+			size:   0,
+			err:    commands.ErrNotFound,
+		},
+		{
+			desc:   "list commands with invalid credentials",
+			token:  wrongValue,
+			thing:  thingID,
+			offset: 0,
+			limit:  10,
+			size:   0,
+			err:    commands.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		page, err := svc.ListCommands(context.Background(), tc.token, tc.thing, tc.state, tc.offset, tc.limit)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.size, len(page.Commands), fmt.Sprintf("%s: expected %d got %d\n", tc.desc, tc.size, len(page.Commands)))
+	}
+}
+
+func TestAcknowledge(t *testing.T) {
+	svc, _ := newService()
+	cmd, err := svc.Send(context.Background(), token, command, 0)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc string
+		msg  mainflux.RawMessage
+		err  error
+	}{
+		{
+			desc: "acknowledge command by other thing",
+			msg:  ack(otherThing, chanID, cmd.ID),
+			err:  commands.ErrNotFound,
+		},
+		{
+			desc: "acknowledge command over other channel",
+			msg:  ack(thingID, otherChan, cmd.ID),
+			err:  commands.ErrNotFound,
+		},
+		{
+			desc: "acknowledge non-existing command",
+			msg:  ack(thingID, chanID, wrongValue),
+			err:  commands.ErrNotFound,
+		},
+		{
+			desc: "acknowledge malformed message",
+			msg:  mainflux.RawMessage{Channel: chanID, Publisher: thingID, Payload: []byte("{")},
+			err:  commands.ErrMalformedEntity,
+		},
+		{
+			desc: "acknowledge command",
+			msg:  ack(thingID, chanID, cmd.ID),
+			err:  nil,
+		},
+		{
+			desc: "acknowledge acked command",
+			msg:  ack(thingID, chanID, cmd.ID),
+			err:  commands.ErrInvalidState,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.Acknowledge(context.Background(), tc.msg)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	acked, err := svc.ViewCommand(context.Background(), token, thingID, cmd.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, commands.StateAcked, acked.State, fmt.Sprintf("expected %s got %s", commands.StateAcked, acked.State))
+	assert.Equal(t, `{"ok":true}`, string(acked.Response), fmt.Sprintf("unexpected response %s", acked.Response))
+}
+
+func TestExpire(t *testing.T) {
+	svc, _ := newService()
+	expiring, err := svc.Send(context.Background(), token, command, time.Millisecond)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	_, err = svc.Send(context.Background(), token, command, 0)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	time.Sleep(5 * time.Millisecond)
+
+	err = svc.Acknowledge(context.Background(), ack(thingID, chanID, expiring.ID))
+	assert.Equal(t, commands.ErrInvalidState, err, fmt.Sprintf("acknowledge expired command: expected %s got %s", commands.ErrInvalidState, err))
+
+	n, err := svc.Expire(context.Background())
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, uint64(1), n, fmt.Sprintf("expected 1 expired command got %d", n))
+
+	cmd, err := svc.ViewCommand(context.Background(), token, thingID, expiring.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, commands.StateExpired, cmd.State, fmt.Sprintf("expected %s got %s", commands.StateExpired, cmd.State))
+}
@@ -0,0 +1,63 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package things contains the things ownership and connection checks backed
+// by the Mainflux SDK.
+package things
+
+import (
+	"github.com/mainflux/mainflux/commands"
+	mfsdk "github.com/mainflux/mainflux/sdk/go"
+)
+
+const pageSize = 100
+
+var _ commands.Things = (*things)(nil)
+
+type things struct {
+	sdk mfsdk.SDK
+}
+
+// New returns things API client backed by the provided SDK. Since the
+// things service returns only the things owned by the user, the thing is
+// owned if it can be retrieved.
+func New(sdk mfsdk.SDK) commands.Things {
+	return things{sdk: sdk}
+}
+
+func (t things) Authorize(token, thingID string) error {
+	_, err := t.sdk.Thing(thingID, token)
+	return toError(err)
+}
+
+func (t things) Connected(token, thingID, chanID string) error {
+	for offset := uint64(0); ; offset += pageSize {
+		page, err := t.sdk.ChannelsByThing(token, thingID, offset, pageSize)
+		if err != nil {
+			return toError(err)
+		}
+
+		for _, ch := range page.Channels {
+			if ch.ID == chanID {
+				return nil
+			}
+		}
+
+		if len(page.Channels) == 0 || offset+pageSize >= page.Total {
+			return commands.ErrNotFound
+		}
+	}
+}
+
+func toError(err error) error {
+	switch err {
+	case nil:
+		return nil
+	case mfsdk.ErrUnauthorized:
+		return commands.ErrUnauthorizedAccess
+	case mfsdk.ErrNotFound:
+		return commands.ErrNotFound
+	default:
+		return err
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mainflux
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// DiscoveryPath is the path of the endpoint describing the service.
+const DiscoveryPath = "/.well-known/mainflux"
+
+// Limits contains the limits of the service API. Zero value indicates that
+// the limit isn't enforced.
+type Limits struct {
+	// MaxPayload contains the maximal size of the request payload in bytes.
+	MaxPayload int64 `json:"max_payload,omitempty"`
+
+	// MaxPageSize contains the maximal number of the items in a page.
+	MaxPageSize uint64 `json:"max_page_size,omitempty"`
+}
+
+// Capabilities describes how the service is configured in the deployment,
+// so that the clients can adapt to it.
+type Capabilities struct {
+	// Features contains the optional features of the service, and whether
+	// they are enabled or not.
+	Features map[string]bool `json:"features,omitempty"`
+
+	// ContentTypes contains the content types the service accepts.
+	ContentTypes []string `json:"content_types,omitempty"`
+
+	// Limits contains the limits of the service API.
+	Limits Limits `json:"limits"`
+}
+
+// DiscoveryInfo contains discovery endpoint response.
+type DiscoveryInfo struct {
+	VersionInfo
+	Capabilities
+}
+
+// Discovery exposes an HTTP handler for retrieving service version and
+// capabilities.
+func Discovery(service string, caps Capabilities) http.HandlerFunc {
+	res := DiscoveryInfo{
+		VersionInfo:  VersionInfo{service, version},
+		Capabilities: caps,
+	}
+	data, _ := json.Marshal(res)
+
+	return http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		rw.Write(data)
+	})
+}
@@ -0,0 +1,121 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/mainflux/mainflux/egress"
+)
+
+func addRuleEndpoint(svc egress.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(addRuleReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		rule := egress.Rule{
+			Channel: req.Channel,
+			Filter: egress.Filter{
+				Subtopic:  req.Filter.Subtopic,
+				Name:      req.Filter.Name,
+				Operator:  req.Filter.Operator,
+				Threshold: req.Filter.Threshold,
+			},
+			Target: egress.Target{
+				Protocol: req.Target.Protocol,
+				URL:      req.Target.URL,
+				Topic:    req.Target.Topic,
+			},
+		}
+
+		saved, err := svc.AddRule(ctx, req.token, rule)
+		if err != nil {
+			return nil, err
+		}
+
+		return ruleRes{id: saved.ID}, nil
+	}
+}
+
+func viewRuleEndpoint(svc egress.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewRuleReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		rule, err := svc.ViewRule(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		return toRuleRes(rule), nil
+	}
+}
+
+func listRulesEndpoint(svc egress.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listRulesReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		page, err := svc.ListRules(ctx, req.token, req.offset, req.limit)
+		if err != nil {
+			return nil, err
+		}
+
+		res := rulesPageRes{
+			Total:  page.Total,
+			Offset: page.Offset,
+			Limit:  page.Limit,
+			Rules:  []viewRuleRes{},
+		}
+		for _, r := range page.Rules {
+			res.Rules = append(res.Rules, toRuleRes(r))
+		}
+
+		return res, nil
+	}
+}
+
+func removeRuleEndpoint(svc egress.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewRuleReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RemoveRule(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
+func toRuleRes(r egress.Rule) viewRuleRes {
+	return viewRuleRes{
+		ID:      r.ID,
+		Channel: r.Channel,
+		Filter: filterReq{
+			Subtopic:  r.Filter.Subtopic,
+			Name:      r.Filter.Name,
+			Operator:  r.Filter.Operator,
+			Threshold: r.Filter.Threshold,
+		},
+		Target: targetReq{
+			Protocol: r.Target.Protocol,
+			URL:      r.Target.URL,
+			Topic:    r.Target.Topic,
+		},
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// +build !test
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/egress"
+)
+
+var _ egress.Service = (*metricsMiddleware)(nil)
+
+type metricsMiddleware struct {
+	counter metrics.Counter
+	latency metrics.Histogram
+	svc     egress.Service
+}
+
+// MetricsMiddleware instruments core service by tracking request count and
+// latency.
+func MetricsMiddleware(svc egress.Service, counter metrics.Counter, latency metrics.Histogram) egress.Service {
+	return &metricsMiddleware{
+		counter: counter,
+		latency: latency,
+		svc:     svc,
+	}
+}
+
+func (ms *metricsMiddleware) AddRule(ctx context.Context, token string, rule egress.Rule) (egress.Rule, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "add_rule").Add(1)
+		ms.latency.With("method", "add_rule").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.AddRule(ctx, token, rule)
+}
+
+func (ms *metricsMiddleware) ViewRule(ctx context.Context, token, id string) (egress.Rule, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_rule").Add(1)
+		ms.latency.With("method", "view_rule").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewRule(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) ListRules(ctx context.Context, token string, offset, limit uint64) (egress.RulesPage, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_rules").Add(1)
+		ms.latency.With("method", "list_rules").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListRules(ctx, token, offset, limit)
+}
+
+func (ms *metricsMiddleware) RemoveRule(ctx context.Context, token, id string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "remove_rule").Add(1)
+		ms.latency.With("method", "remove_rule").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RemoveRule(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) Forward(ctx context.Context, msg mainflux.Message) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "forward").Add(1)
+		ms.latency.With("method", "forward").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Forward(ctx, msg)
+}
+
+func (ms *metricsMiddleware) Reload(ctx context.Context) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "reload").Add(1)
+		ms.latency.With("method", "reload").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Reload(ctx)
+}
+
+func (ms *metricsMiddleware) SyncRule(ctx context.Context, id string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "sync_rule").Add(1)
+		ms.latency.With("method", "sync_rule").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.SyncRule(ctx, id)
+}
+
+func (ms *metricsMiddleware) RemoveChannel(ctx context.Context, id string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "remove_channel").Add(1)
+		ms.latency.With("method", "remove_channel").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RemoveChannel(ctx, id)
+}
@@ -0,0 +1,79 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import "github.com/mainflux/mainflux/egress"
+
+const maxLimitSize = 100
+
+type apiReq interface {
+	validate() error
+}
+
+type filterReq struct {
+	Subtopic  string  `json:"subtopic,omitempty"`
+	Name      string  `json:"name,omitempty"`
+	Operator  string  `json:"operator,omitempty"`
+	Threshold float64 `json:"threshold,omitempty"`
+}
+
+type targetReq struct {
+	Protocol string `json:"protocol"`
+	URL      string `json:"url"`
+	Topic    string `json:"topic"`
+}
+
+type addRuleReq struct {
+	token   string
+	Channel string    `json:"channel"`
+	Filter  filterReq `json:"filter"`
+	Target  targetReq `json:"target"`
+}
+
+func (req addRuleReq) validate() error {
+	if req.token == "" {
+		return egress.ErrUnauthorizedAccess
+	}
+
+	if req.Channel == "" || req.Target.URL == "" || req.Target.Topic == "" {
+		return egress.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type viewRuleReq struct {
+	token string
+	id    string
+}
+
+func (req viewRuleReq) validate() error {
+	if req.token == "" {
+		return egress.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" {
+		return egress.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type listRulesReq struct {
+	token  string
+	offset uint64
+	limit  uint64
+}
+
+func (req listRulesReq) validate() error {
+	if req.token == "" {
+		return egress.ErrUnauthorizedAccess
+	}
+
+	if req.limit == 0 || req.limit > maxLimitSize {
+		return egress.ErrMalformedEntity
+	}
+
+	return nil
+}
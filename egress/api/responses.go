@@ -0,0 +1,88 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/mainflux/mainflux"
+)
+
+var (
+	_ mainflux.Response = (*ruleRes)(nil)
+	_ mainflux.Response = (*viewRuleRes)(nil)
+	_ mainflux.Response = (*rulesPageRes)(nil)
+	_ mainflux.Response = (*removeRes)(nil)
+)
+
+type ruleRes struct {
+	id string
+}
+
+func (res ruleRes) Code() int {
+	return http.StatusCreated
+}
+
+func (res ruleRes) Headers() map[string]string {
+	return map[string]string{
+		"Location": fmt.Sprintf("/rules/%s", res.id),
+	}
+}
+
+func (res ruleRes) Empty() bool {
+	return true
+}
+
+type viewRuleRes struct {
+	ID      string    `json:"id"`
+	Channel string    `json:"channel"`
+	Filter  filterReq `json:"filter"`
+	Target  targetReq `json:"target"`
+}
+
+func (res viewRuleRes) Code() int {
+	return http.StatusOK
+}
+
+func (res viewRuleRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res viewRuleRes) Empty() bool {
+	return false
+}
+
+type rulesPageRes struct {
+	Total  uint64        `json:"total"`
+	Offset uint64        `json:"offset"`
+	Limit  uint64        `json:"limit"`
+	Rules  []viewRuleRes `json:"rules"`
+}
+
+func (res rulesPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res rulesPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res rulesPageRes) Empty() bool {
+	return false
+}
+
+type removeRes struct{}
+
+func (res removeRes) Code() int {
+	return http.StatusNoContent
+}
+
+func (res removeRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res removeRes) Empty() bool {
+	return true
+}
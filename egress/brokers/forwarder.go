@@ -0,0 +1,158 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package brokers contains forwarder implementation which publishes messages
+// to the external MQTT brokers and NATS servers.
+package brokers
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/mainflux/mainflux/egress"
+	log "github.com/mainflux/mainflux/logger"
+	broker "github.com/nats-io/nats.go"
+)
+
+const (
+	qos            = 1
+	connectTimeout = 5 * time.Second
+	publishTimeout = 5 * time.Second
+	// retryInterval is the time the messages of the unreachable target are
+	// dropped for, before the connection is attempted again.
+	retryInterval = 30 * time.Second
+)
+
+var (
+	errUnsupportedProtocol = errors.New("unsupported target protocol")
+	errQueueFull           = errors.New("target queue is full")
+	errTimeout             = errors.New("timeout while communicating with the target")
+)
+
+var _ egress.Forwarder = (*forwarder)(nil)
+
+type message struct {
+	topic   string
+	payload []byte
+}
+
+type forwarder struct {
+	clientID  string
+	queueSize int
+	logger    log.Logger
+	mu        sync.Mutex
+	queues    map[egress.Target]chan message
+}
+
+// New returns forwarder which delivers the messages asynchronously. Each
+// target has its own connection and the queue of the given size, so that
+// the slow or unreachable target doesn't delay the others. Messages are
+// dropped while the target queue is full.
+func New(clientID string, queueSize int, logger log.Logger) egress.Forwarder {
+	return &forwarder{
+		clientID:  clientID,
+		queueSize: queueSize,
+		logger:    logger,
+		queues:    make(map[egress.Target]chan message),
+	}
+}
+
+func (f *forwarder) Forward(target egress.Target, topic string, payload []byte) error {
+	if target.Protocol != egress.MQTT && target.Protocol != egress.NATS {
+		return errUnsupportedProtocol
+	}
+
+	select {
+	case f.queue(target) <- message{topic: topic, payload: payload}:
+		return nil
+	default:
+		return errQueueFull
+	}
+}
+
+// queue returns the queue of the target, and starts the target worker if
+// the target is seen for the first time. Topic isn't part of the target
+// connection, so the targets are distinguished by protocol and URL only.
+func (f *forwarder) queue(target egress.Target) chan<- message {
+	target.Topic = ""
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if q, ok := f.queues[target]; ok {
+		return q
+	}
+
+	q := make(chan message, f.queueSize)
+	f.queues[target] = q
+
+	switch target.Protocol {
+	case egress.MQTT:
+		go f.publishMQTT(fmt.Sprintf("%s-%d", f.clientID, len(f.queues)), target.URL, q)
+	case egress.NATS:
+		go f.publishNATS(target.URL, q)
+	}
+
+	return q
+}
+
+func (f *forwarder) publishMQTT(clientID, url string, msgs <-chan message) {
+	opts := mqtt.NewClientOptions().
+		AddBroker(url).
+		SetClientID(clientID).
+		SetConnectTimeout(connectTimeout).
+		SetAutoReconnect(true)
+	c := mqtt.NewClient(opts)
+
+	var retryAt time.Time
+	for msg := range msgs {
+		if !c.IsConnected() {
+			if time.Now().Before(retryAt) {
+				continue
+			}
+			if err := wait(c.Connect(), connectTimeout); err != nil {
+				f.logger.Warn(fmt.Sprintf("Failed to connect to MQTT broker %s: %s", url, err))
+				c.Disconnect(0)
+				retryAt = time.Now().Add(retryInterval)
+				continue
+			}
+		}
+
+		if err := wait(c.Publish(msg.topic, qos, false, msg.payload), publishTimeout); err != nil {
+			f.logger.Warn(fmt.Sprintf("Failed to publish to MQTT broker %s: %s", url, err))
+		}
+	}
+}
+
+func (f *forwarder) publishNATS(url string, msgs <-chan message) {
+	var nc *broker.Conn
+	var retryAt time.Time
+	for msg := range msgs {
+		if nc == nil || nc.IsClosed() {
+			if time.Now().Before(retryAt) {
+				continue
+			}
+			conn, err := broker.Connect(url, broker.Timeout(connectTimeout))
+			if err != nil {
+				f.logger.Warn(fmt.Sprintf("Failed to connect to NATS server %s: %s", url, err))
+				retryAt = time.Now().Add(retryInterval)
+				continue
+			}
+			nc = conn
+		}
+
+		if err := nc.Publish(msg.topic, msg.payload); err != nil {
+			f.logger.Warn(fmt.Sprintf("Failed to publish to NATS server %s: %s", url, err))
+		}
+	}
+}
+
+func wait(token mqtt.Token, timeout time.Duration) error {
+	if !token.WaitTimeout(timeout) {
+		return errTimeout
+	}
+	return token.Error()
+}
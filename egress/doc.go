@@ -0,0 +1,8 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package egress contains the domain concept definitions needed to support
+// Mainflux egress service functionality. Egress service republishes
+// normalized messages that match per-channel rules to external MQTT brokers
+// or NATS subjects.
+package egress
@@ -0,0 +1,47 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"sync"
+
+	"github.com/mainflux/mainflux/egress"
+)
+
+// Forwarded represents a payload published to an external broker.
+type Forwarded struct {
+	Target  egress.Target
+	Topic   string
+	Payload []byte
+}
+
+// Forwarder is an in-memory forwarder which records forwarded payloads.
+type Forwarder struct {
+	mu  sync.Mutex
+	out []Forwarded
+}
+
+var _ egress.Forwarder = (*Forwarder)(nil)
+
+// NewForwarder returns forwarder mock.
+func NewForwarder() *Forwarder {
+	return &Forwarder{}
+}
+
+// Forward records the forwarded payload.
+func (f *Forwarder) Forward(target egress.Target, topic string, payload []byte) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.out = append(f.out, Forwarded{Target: target, Topic: topic, Payload: payload})
+	return nil
+}
+
+// Forwarded returns all the payloads forwarded so far.
+func (f *Forwarder) Forwarded() []Forwarded {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]Forwarded{}, f.out...)
+}
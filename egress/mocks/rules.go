@@ -0,0 +1,108 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/mainflux/mainflux/egress"
+)
+
+var _ egress.RuleRepository = (*ruleRepositoryMock)(nil)
+
+type ruleRepositoryMock struct {
+	mu    sync.Mutex
+	rules map[string]egress.Rule
+}
+
+// NewRuleRepository creates in-memory rule repository.
+func NewRuleRepository() egress.RuleRepository {
+	return &ruleRepositoryMock{
+		rules: make(map[string]egress.Rule),
+	}
+}
+
+func (rrm *ruleRepositoryMock) Save(_ context.Context, rule egress.Rule) error {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	rrm.rules[rule.ID] = rule
+	return nil
+}
+
+func (rrm *ruleRepositoryMock) RetrieveByID(_ context.Context, id string) (egress.Rule, error) {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	rule, ok := rrm.rules[id]
+	if !ok {
+		return egress.Rule{}, egress.ErrNotFound
+	}
+
+	return rule, nil
+}
+
+func (rrm *ruleRepositoryMock) RetrieveAll(_ context.Context) ([]egress.Rule, error) {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	return rrm.filter(func(egress.Rule) bool { return true }), nil
+}
+
+func (rrm *ruleRepositoryMock) RetrieveByOwner(_ context.Context, owner string, offset, limit uint64) (egress.RulesPage, error) {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	rules := rrm.filter(func(r egress.Rule) bool { return r.Owner == owner })
+	page := egress.RulesPage{
+		Total:  uint64(len(rules)),
+		Offset: offset,
+		Limit:  limit,
+		Rules:  []egress.Rule{},
+	}
+
+	if offset >= uint64(len(rules)) {
+		return page, nil
+	}
+
+	end := offset + limit
+	if end > uint64(len(rules)) {
+		end = uint64(len(rules))
+	}
+	page.Rules = rules[offset:end]
+
+	return page, nil
+}
+
+func (rrm *ruleRepositoryMock) RetrieveByChannel(_ context.Context, chanID string) ([]egress.Rule, error) {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	return rrm.filter(func(r egress.Rule) bool { return r.Channel == chanID }), nil
+}
+
+func (rrm *ruleRepositoryMock) Remove(_ context.Context, id string) error {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	delete(rrm.rules, id)
+	return nil
+}
+
+func (rrm *ruleRepositoryMock) filter(match func(egress.Rule) bool) []egress.Rule {
+	rules := []egress.Rule{}
+	for _, r := range rrm.rules {
+		if match(r) {
+			rules = append(rules, r)
+		}
+	}
+
+	sort.SliceStable(rules, func(i, j int) bool {
+		return rules[i].ID < rules[j].ID
+	})
+
+	return rules
+}
@@ -0,0 +1,51 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package nats contains NATS subscriber which feeds normalized messages
+// to the egress service.
+package nats
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/egress"
+	log "github.com/mainflux/mainflux/logger"
+	mfnats "github.com/mainflux/mainflux/nats"
+	broker "github.com/nats-io/nats.go"
+)
+
+const queue = "egress"
+
+type subscriber struct {
+	svc    egress.Service
+	logger log.Logger
+}
+
+// Subscribe subscribes to normalized messages and forwards them to the
+// egress service. Queue subscription ensures every message is republished
+// by exactly one service instance. The subject is prefixed with the deployment
+// subject prefix, unless it is empty.
+func Subscribe(svc egress.Service, nc *broker.Conn, subjectPrefix string, logger log.Logger) error {
+	s := subscriber{
+		svc:    svc,
+		logger: logger,
+	}
+
+	_, err := nc.QueueSubscribe(mfnats.Subject(subjectPrefix, mainflux.OutputSenML), queue, s.handleMsg)
+	return err
+}
+
+func (s subscriber) handleMsg(m *broker.Msg) {
+	var msg mainflux.Message
+	if err := proto.Unmarshal(m.Data, &msg); err != nil {
+		s.logger.Warn(fmt.Sprintf("Failed to unmarshal received message: %s", err))
+		return
+	}
+
+	if err := s.svc.Forward(context.Background(), msg); err != nil {
+		s.logger.Warn(fmt.Sprintf("Failed to forward message: %s", err))
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package redis
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-redis/redis"
+	"github.com/mainflux/mainflux/egress"
+	"github.com/mainflux/mainflux/logger"
+)
+
+const (
+	// ThingsStream is the stream things service publishes events to.
+	ThingsStream = "mainflux.things"
+
+	group  = "mainflux.egress"
+	exists = "BUSYGROUP Consumer Group name already exists"
+)
+
+// EventStore represents event source for egress rules.
+type EventStore interface {
+	// Subscribe subscribes to the given stream and handles received events.
+	Subscribe(string) error
+}
+
+type consumer struct {
+	svc      egress.Service
+	client   redis.UniversalClient
+	consumer string
+	logger   logger.Logger
+}
+
+// NewEventStore returns new event store instance. Since all the instances
+// have to keep their active rules up to date, rule events are consumed using
+// consumer group named after the instance. Things events are consumed using
+// shared group, because rule removal only needs to happen once.
+func NewEventStore(svc egress.Service, client redis.UniversalClient, consumerName string, log logger.Logger) EventStore {
+	return consumer{
+		svc:      svc,
+		client:   client,
+		consumer: consumerName,
+		logger:   log,
+	}
+}
+
+func (c consumer) Subscribe(stream string) error {
+	grp := group
+	if stream == RulesStream {
+		grp = fmt.Sprintf("%s.%s", group, c.consumer)
+	}
+
+	err := c.client.XGroupCreateMkStream(stream, grp, "$").Err()
+	if err != nil && err.Error() != exists {
+		return err
+	}
+
+	for {
+		streams, err := c.client.XReadGroup(&redis.XReadGroupArgs{
+			Group:    grp,
+			Consumer: c.consumer,
+			Streams:  []string{stream, ">"},
+			Count:    100,
+		}).Result()
+		if err != nil || len(streams) == 0 {
+			continue
+		}
+
+		for _, msg := range streams[0].Messages {
+			event := msg.Values
+
+			var err error
+			switch event["operation"] {
+			case ruleCreate, ruleRemove:
+				err = c.svc.SyncRule(context.Background(), read(event, "id", ""))
+			case channelRemove:
+				err = c.svc.RemoveChannel(context.Background(), read(event, "id", ""))
+			}
+			if err != nil {
+				c.logger.Warn(fmt.Sprintf("Failed to handle event sourcing: %s", err.Error()))
+				break
+			}
+			c.client.XAck(stream, grp, msg.ID)
+		}
+	}
+}
+
+func read(event map[string]interface{}, key, def string) string {
+	val, ok := event[key].(string)
+	if !ok {
+		return def
+	}
+
+	return val
+}
@@ -0,0 +1,7 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package redis contains Redis specific implementations of the egress rule
+// repository, as well as the event sourcing producer and consumer used to
+// hot-reload rules across service instances.
+package redis
@@ -0,0 +1,45 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package redis
+
+const (
+	rulePrefixOp = "rule."
+	ruleCreate   = rulePrefixOp + "create"
+	ruleRemove   = rulePrefixOp + "remove"
+
+	channelRemove = "channel.remove"
+)
+
+type event interface {
+	Encode() map[string]interface{}
+}
+
+var (
+	_ event = (*createRuleEvent)(nil)
+	_ event = (*removeRuleEvent)(nil)
+)
+
+type createRuleEvent struct {
+	id      string
+	channel string
+}
+
+func (cre createRuleEvent) Encode() map[string]interface{} {
+	return map[string]interface{}{
+		"id":        cre.id,
+		"channel":   cre.channel,
+		"operation": ruleCreate,
+	}
+}
+
+type removeRuleEvent struct {
+	id string
+}
+
+func (rre removeRuleEvent) Encode() map[string]interface{} {
+	return map[string]interface{}{
+		"id":        rre.id,
+		"operation": ruleRemove,
+	}
+}
@@ -0,0 +1,221 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/go-redis/redis"
+	"github.com/mainflux/mainflux/egress"
+)
+
+const (
+	rulePrefix    = "egress:rule"
+	rulesKey      = "egress:rules"
+	ownerPrefix   = "egress:owner"
+	channelPrefix = "egress:channel"
+)
+
+var _ egress.RuleRepository = (*ruleRepository)(nil)
+
+type ruleRepository struct {
+	client redis.UniversalClient
+}
+
+// NewRuleRepository returns Redis implementation of the egress rule
+// repository.
+func NewRuleRepository(client redis.UniversalClient) egress.RuleRepository {
+	return &ruleRepository{
+		client: client,
+	}
+}
+
+func (rr *ruleRepository) Save(_ context.Context, rule egress.Rule) error {
+	data, err := json.Marshal(toDBRule(rule))
+	if err != nil {
+		return err
+	}
+
+	_, err = rr.client.TxPipelined(func(pipe redis.Pipeliner) error {
+		pipe.Set(ruleKey(rule.ID), data, 0)
+		pipe.SAdd(rulesKey, rule.ID)
+		pipe.SAdd(ownerKey(rule.Owner), rule.ID)
+		pipe.SAdd(channelKey(rule.Channel), rule.ID)
+		return nil
+	})
+
+	return err
+}
+
+func (rr *ruleRepository) RetrieveByID(_ context.Context, id string) (egress.Rule, error) {
+	data, err := rr.client.Get(ruleKey(id)).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return egress.Rule{}, egress.ErrNotFound
+		}
+		return egress.Rule{}, err
+	}
+
+	var dbr dbRule
+	if err := json.Unmarshal(data, &dbr); err != nil {
+		return egress.Rule{}, err
+	}
+
+	return toRule(dbr), nil
+}
+
+func (rr *ruleRepository) RetrieveAll(_ context.Context) ([]egress.Rule, error) {
+	return rr.retrieveSet(rulesKey)
+}
+
+func (rr *ruleRepository) RetrieveByOwner(_ context.Context, owner string, offset, limit uint64) (egress.RulesPage, error) {
+	rules, err := rr.retrieveSet(ownerKey(owner))
+	if err != nil {
+		return egress.RulesPage{}, err
+	}
+
+	page := egress.RulesPage{
+		Total:  uint64(len(rules)),
+		Offset: offset,
+		Limit:  limit,
+		Rules:  []egress.Rule{},
+	}
+
+	if offset >= page.Total {
+		return page, nil
+	}
+
+	end := offset + limit
+	if end > page.Total {
+		end = page.Total
+	}
+	page.Rules = rules[offset:end]
+
+	return page, nil
+}
+
+func (rr *ruleRepository) RetrieveByChannel(_ context.Context, chanID string) ([]egress.Rule, error) {
+	return rr.retrieveSet(channelKey(chanID))
+}
+
+func (rr *ruleRepository) Remove(ctx context.Context, id string) error {
+	rule, err := rr.RetrieveByID(ctx, id)
+	if err == egress.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = rr.client.TxPipelined(func(pipe redis.Pipeliner) error {
+		pipe.Del(ruleKey(id))
+		pipe.SRem(rulesKey, id)
+		pipe.SRem(ownerKey(rule.Owner), id)
+		pipe.SRem(channelKey(rule.Channel), id)
+		return nil
+	})
+
+	return err
+}
+
+func (rr *ruleRepository) retrieveSet(key string) ([]egress.Rule, error) {
+	ids, err := rr.client.SMembers(key).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	rules := []egress.Rule{}
+	if len(ids) == 0 {
+		return rules, nil
+	}
+	sort.Strings(ids)
+
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = ruleKey(id)
+	}
+
+	vals, err := rr.client.MGet(keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, val := range vals {
+		// Rule might be removed in the meantime.
+		str, ok := val.(string)
+		if !ok {
+			continue
+		}
+
+		var dbr dbRule
+		if err := json.Unmarshal([]byte(str), &dbr); err != nil {
+			return nil, err
+		}
+		rules = append(rules, toRule(dbr))
+	}
+
+	return rules, nil
+}
+
+func ruleKey(id string) string {
+	return fmt.Sprintf("%s:%s", rulePrefix, id)
+}
+
+func ownerKey(owner string) string {
+	return fmt.Sprintf("%s:%s", ownerPrefix, owner)
+}
+
+func channelKey(chanID string) string {
+	return fmt.Sprintf("%s:%s", channelPrefix, chanID)
+}
+
+type dbRule struct {
+	ID        string  `json:"id"`
+	Owner     string  `json:"owner"`
+	Channel   string  `json:"channel"`
+	Subtopic  string  `json:"subtopic,omitempty"`
+	Name      string  `json:"name,omitempty"`
+	Operator  string  `json:"operator,omitempty"`
+	Threshold float64 `json:"threshold,omitempty"`
+	Protocol  string  `json:"protocol"`
+	URL       string  `json:"url"`
+	Topic     string  `json:"topic"`
+}
+
+func toDBRule(r egress.Rule) dbRule {
+	return dbRule{
+		ID:        r.ID,
+		Owner:     r.Owner,
+		Channel:   r.Channel,
+		Subtopic:  r.Filter.Subtopic,
+		Name:      r.Filter.Name,
+		Operator:  r.Filter.Operator,
+		Threshold: r.Filter.Threshold,
+		Protocol:  r.Target.Protocol,
+		URL:       r.Target.URL,
+		Topic:     r.Target.Topic,
+	}
+}
+
+func toRule(dbr dbRule) egress.Rule {
+	return egress.Rule{
+		ID:      dbr.ID,
+		Owner:   dbr.Owner,
+		Channel: dbr.Channel,
+		Filter: egress.Filter{
+			Subtopic:  dbr.Subtopic,
+			Name:      dbr.Name,
+			Operator:  dbr.Operator,
+			Threshold: dbr.Threshold,
+		},
+		Target: egress.Target{
+			Protocol: dbr.Protocol,
+			URL:      dbr.URL,
+			Topic:    dbr.Topic,
+		},
+	}
+}
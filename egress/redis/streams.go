@@ -0,0 +1,96 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package redis
+
+import (
+	"context"
+
+	"github.com/go-redis/redis"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/egress"
+)
+
+const (
+	// RulesStream is the stream rule events are published to.
+	RulesStream = "mainflux.egress"
+	streamLen   = 1000
+)
+
+var _ egress.Service = (*eventStore)(nil)
+
+type eventStore struct {
+	svc    egress.Service
+	client redis.UniversalClient
+}
+
+// NewEventStoreMiddleware returns wrapper around egress service that sends
+// rule events to event store, so that other instances can reload them.
+func NewEventStoreMiddleware(svc egress.Service, client redis.UniversalClient) egress.Service {
+	return eventStore{
+		svc:    svc,
+		client: client,
+	}
+}
+
+func (es eventStore) AddRule(ctx context.Context, token string, rule egress.Rule) (egress.Rule, error) {
+	saved, err := es.svc.AddRule(ctx, token, rule)
+	if err != nil {
+		return saved, err
+	}
+
+	event := createRuleEvent{
+		id:      saved.ID,
+		channel: saved.Channel,
+	}
+	record := &redis.XAddArgs{
+		Stream:       RulesStream,
+		MaxLenApprox: streamLen,
+		Values:       event.Encode(),
+	}
+	es.client.XAdd(record).Err()
+
+	return saved, nil
+}
+
+func (es eventStore) ViewRule(ctx context.Context, token, id string) (egress.Rule, error) {
+	return es.svc.ViewRule(ctx, token, id)
+}
+
+func (es eventStore) ListRules(ctx context.Context, token string, offset, limit uint64) (egress.RulesPage, error) {
+	return es.svc.ListRules(ctx, token, offset, limit)
+}
+
+func (es eventStore) RemoveRule(ctx context.Context, token, id string) error {
+	if err := es.svc.RemoveRule(ctx, token, id); err != nil {
+		return err
+	}
+
+	event := removeRuleEvent{
+		id: id,
+	}
+	record := &redis.XAddArgs{
+		Stream:       RulesStream,
+		MaxLenApprox: streamLen,
+		Values:       event.Encode(),
+	}
+	es.client.XAdd(record).Err()
+
+	return nil
+}
+
+func (es eventStore) Forward(ctx context.Context, msg mainflux.Message) error {
+	return es.svc.Forward(ctx, msg)
+}
+
+func (es eventStore) Reload(ctx context.Context) error {
+	return es.svc.Reload(ctx)
+}
+
+func (es eventStore) SyncRule(ctx context.Context, id string) error {
+	return es.svc.SyncRule(ctx, id)
+}
+
+func (es eventStore) RemoveChannel(ctx context.Context, id string) error {
+	return es.svc.RemoveChannel(ctx, id)
+}
@@ -0,0 +1,219 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package egress
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/mainflux/mainflux"
+)
+
+const (
+	// MQTT represents target protocol for external MQTT brokers.
+	MQTT = "mqtt"
+
+	// NATS represents target protocol for external NATS servers.
+	NATS = "nats"
+
+	subtopicWildcard = ">"
+)
+
+// Supported filter operators.
+const (
+	OpEq = "eq"
+	OpNe = "ne"
+	OpGt = "gt"
+	OpGe = "ge"
+	OpLt = "lt"
+	OpLe = "le"
+)
+
+var operators = map[string]func(float64, float64) bool{
+	OpEq: func(v, t float64) bool { return v == t },
+	OpNe: func(v, t float64) bool { return v != t },
+	OpGt: func(v, t float64) bool { return v > t },
+	OpGe: func(v, t float64) bool { return v >= t },
+	OpLt: func(v, t float64) bool { return v < t },
+	OpLe: func(v, t float64) bool { return v <= t },
+}
+
+// Rule represents egress rule which describes what messages published to
+// the channel are republished and where. Each rule is owned by one user.
+type Rule struct {
+	ID      string
+	Owner   string
+	Channel string
+	Filter  Filter
+	Target  Target
+}
+
+// Filter describes conditions message has to satisfy in order to be
+// republished. Empty fields match any message.
+type Filter struct {
+	// Subtopic is matched exactly, unless it ends with ">" in which case
+	// it matches all the subtopics with the given prefix.
+	Subtopic string
+
+	// Name is the SenML record name.
+	Name string
+
+	// Operator compares numeric message value with threshold.
+	Operator  string
+	Threshold float64
+}
+
+// Target describes an external broker messages are republished to. Topic is
+// a template which may contain {channel}, {subtopic}, {publisher} and {name}
+// placeholders.
+type Target struct {
+	Protocol string
+	URL      string
+	Topic    string
+}
+
+// Allowed determines whether the target host is one of the given hosts.
+// Hosts starting with `*.` match all their subdomains.
+func (t Target) Allowed(hosts []string) bool {
+	u, err := url.Parse(t.URL)
+	if err != nil {
+		return false
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if host == "" {
+		return false
+	}
+
+	for _, h := range hosts {
+		h = strings.ToLower(h)
+		if host == h || (strings.HasPrefix(h, "*.") && strings.HasSuffix(host, h[1:])) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RulesPage contains page related metadata as well as list of rules that
+// belong to this page.
+type RulesPage struct {
+	Total  uint64
+	Offset uint64
+	Limit  uint64
+	Rules  []Rule
+}
+
+// Validate returns ErrMalformedEntity if rule isn't valid.
+func (r Rule) Validate() error {
+	if r.Channel == "" || r.Target.URL == "" || r.Target.Topic == "" {
+		return ErrMalformedEntity
+	}
+
+	if r.Target.Protocol != MQTT && r.Target.Protocol != NATS {
+		return ErrMalformedEntity
+	}
+
+	if _, ok := operators[r.Filter.Operator]; r.Filter.Operator != "" && !ok {
+		return ErrMalformedEntity
+	}
+
+	return nil
+}
+
+// Matches determines whether the message satisfies rule filter.
+func (r Rule) Matches(msg mainflux.Message) bool {
+	if r.Channel != msg.Channel {
+		return false
+	}
+
+	if !matchSubtopic(r.Filter.Subtopic, msg.Subtopic) {
+		return false
+	}
+
+	if r.Filter.Name != "" && r.Filter.Name != msg.Name {
+		return false
+	}
+
+	if r.Filter.Operator == "" {
+		return true
+	}
+
+	val, ok := msg.Value.(*mainflux.Message_FloatValue)
+	if !ok {
+		return false
+	}
+
+	op, ok := operators[r.Filter.Operator]
+	return ok && op(val.FloatValue, r.Filter.Threshold)
+}
+
+// Topic returns target topic for the given message.
+func (r Rule) Topic(msg mainflux.Message) string {
+	rep := strings.NewReplacer(
+		"{channel}", msg.Channel,
+		"{subtopic}", msg.Subtopic,
+		"{publisher}", msg.Publisher,
+		"{name}", msg.Name,
+	)
+
+	return rep.Replace(r.Target.Topic)
+}
+
+func matchSubtopic(filter, subtopic string) bool {
+	if filter == "" || filter == subtopic {
+		return true
+	}
+
+	if strings.HasSuffix(filter, subtopicWildcard) {
+		return strings.HasPrefix(subtopic, strings.TrimSuffix(filter, subtopicWildcard))
+	}
+
+	return false
+}
+
+// RuleRepository specifies a rule persistence API.
+type RuleRepository interface {
+	// Save persists the rule. A non-nil error is returned to indicate
+	// operation failure.
+	Save(context.Context, Rule) error
+
+	// RetrieveByID retrieves the rule having the provided identifier.
+	RetrieveByID(context.Context, string) (Rule, error)
+
+	// RetrieveAll retrieves all the rules. It is used to populate the
+	// set of active rules.
+	RetrieveAll(context.Context) ([]Rule, error)
+
+	// RetrieveByOwner retrieves the subset of rules owned by the specified
+	// user.
+	RetrieveByOwner(context.Context, string, uint64, uint64) (RulesPage, error)
+
+	// RetrieveByChannel retrieves all the rules defined for the channel.
+	RetrieveByChannel(context.Context, string) ([]Rule, error)
+
+	// Remove removes the rule having the provided identifier.
+	Remove(context.Context, string) error
+}
+
+// Forwarder specifies an API for publishing messages to external brokers.
+type Forwarder interface {
+	// Forward publishes payload to the topic of the given target.
+	Forward(Target, string, []byte) error
+}
+
+// Channels specifies an API for checking the channel ownership, so that the
+// users can't forward the messages of other users.
+type Channels interface {
+	// Authorize returns ErrNotFound if the channel doesn't exist or isn't
+	// owned by the user identified by the provided key.
+	Authorize(token, chanID string) error
+}
+
+// IdentityProvider specifies an API for generating unique identifiers.
+type IdentityProvider interface {
+	// ID generates the unique identifier.
+	ID() (string, error)
+}
@@ -0,0 +1,342 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package egress
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/cisco/senml"
+	"github.com/mainflux/mainflux"
+)
+
+var (
+	// ErrMalformedEntity indicates malformed entity specification.
+	ErrMalformedEntity = errors.New("malformed entity specification")
+
+	// ErrUnauthorizedAccess indicates missing or invalid credentials provided
+	// when accessing a protected resource.
+	ErrUnauthorizedAccess = errors.New("missing or invalid credentials provided")
+
+	// ErrNotFound indicates a non-existent entity request.
+	ErrNotFound = errors.New("non-existent entity")
+
+	// ErrTargetNotAllowed indicates that the rule target host isn't one of
+	// the allowed hosts.
+	ErrTargetNotAllowed = errors.New("target host not allowed")
+)
+
+// Config defines the hosts the rule targets may point to. Targets pointing
+// to the other hosts are rejected, so that the service can't be used to
+// reach the internal hosts of the deployment.
+type Config struct {
+	Hosts []string
+}
+
+// Service specifies an API that must be fulfilled by the domain service
+// implementation, and all of its decorators (e.g. logging & metrics).
+type Service interface {
+	// AddRule adds new egress rule to the user identified by the provided key.
+	AddRule(context.Context, string, Rule) (Rule, error)
+
+	// ViewRule retrieves the rule identified by the provided ID, that
+	// belongs to the user identified by the provided key.
+	ViewRule(context.Context, string, string) (Rule, error)
+
+	// ListRules retrieves subset of rules that belong to the user identified
+	// by the provided key.
+	ListRules(context.Context, string, uint64, uint64) (RulesPage, error)
+
+	// RemoveRule removes the rule identified by the provided ID, that
+	// belongs to the user identified by the provided key.
+	RemoveRule(context.Context, string, string) error
+
+	// Forward republishes message to the targets of all the matching rules.
+	Forward(context.Context, mainflux.Message) error
+
+	// Methods Reload, SyncRule and RemoveChannel are used as handlers
+	// for events. That's why these methods surpass ownership check.
+
+	// Reload replaces the set of active rules with the persisted ones.
+	Reload(context.Context) error
+
+	// SyncRule refreshes the active rule identified by the provided ID.
+	SyncRule(context.Context, string) error
+
+	// RemoveChannel removes all the rules defined for the removed channel.
+	RemoveChannel(context.Context, string) error
+}
+
+var _ Service = (*egressService)(nil)
+
+type egressService struct {
+	users     mainflux.UsersServiceClient
+	channels  Channels
+	rules     RuleRepository
+	forwarder Forwarder
+	idp       IdentityProvider
+	cfg       Config
+
+	mu     sync.RWMutex
+	active map[string]map[string]Rule
+}
+
+// New instantiates the egress service implementation.
+func New(users mainflux.UsersServiceClient, channels Channels, rules RuleRepository, forwarder Forwarder, idp IdentityProvider, cfg Config) Service {
+	return &egressService{
+		users:     users,
+		channels:  channels,
+		rules:     rules,
+		forwarder: forwarder,
+		idp:       idp,
+		cfg:       cfg,
+		active:    make(map[string]map[string]Rule),
+	}
+}
+
+func (es *egressService) AddRule(ctx context.Context, token string, rule Rule) (Rule, error) {
+	owner, err := es.identify(ctx, token)
+	if err != nil {
+		return Rule{}, err
+	}
+
+	if err := rule.Validate(); err != nil {
+		return Rule{}, err
+	}
+
+	if !rule.Target.Allowed(es.cfg.Hosts) {
+		return Rule{}, ErrTargetNotAllowed
+	}
+
+	if err := es.channels.Authorize(token, rule.Channel); err != nil {
+		return Rule{}, err
+	}
+
+	rule.ID, err = es.idp.ID()
+	if err != nil {
+		return Rule{}, err
+	}
+	rule.Owner = owner
+
+	if err := es.rules.Save(ctx, rule); err != nil {
+		return Rule{}, err
+	}
+
+	es.activate(rule)
+	return rule, nil
+}
+
+func (es *egressService) ViewRule(ctx context.Context, token, id string) (Rule, error) {
+	owner, err := es.identify(ctx, token)
+	if err != nil {
+		return Rule{}, err
+	}
+
+	rule, err := es.rules.RetrieveByID(ctx, id)
+	if err != nil {
+		return Rule{}, err
+	}
+
+	if rule.Owner != owner {
+		return Rule{}, ErrNotFound
+	}
+
+	return rule, nil
+}
+
+func (es *egressService) ListRules(ctx context.Context, token string, offset, limit uint64) (RulesPage, error) {
+	owner, err := es.identify(ctx, token)
+	if err != nil {
+		return RulesPage{}, err
+	}
+
+	return es.rules.RetrieveByOwner(ctx, owner, offset, limit)
+}
+
+func (es *egressService) RemoveRule(ctx context.Context, token, id string) error {
+	rule, err := es.ViewRule(ctx, token, id)
+	if err != nil {
+		return err
+	}
+
+	if err := es.rules.Remove(ctx, id); err != nil {
+		return err
+	}
+
+	es.deactivate(rule.Channel, id)
+	return nil
+}
+
+func (es *egressService) Forward(ctx context.Context, msg mainflux.Message) error {
+	es.mu.RLock()
+	rules := make([]Rule, 0, len(es.active[msg.Channel]))
+	for _, r := range es.active[msg.Channel] {
+		rules = append(rules, r)
+	}
+	es.mu.RUnlock()
+
+	var payload []byte
+	var ferr error
+	for _, r := range rules {
+		if !r.Matches(msg) {
+			continue
+		}
+
+		// Rules saved before the allowed hosts were changed.
+		if !r.Target.Allowed(es.cfg.Hosts) {
+			ferr = ErrTargetNotAllowed
+			continue
+		}
+
+		if payload == nil {
+			p, err := encode(msg)
+			if err != nil {
+				return err
+			}
+			payload = p
+		}
+
+		// Failure of a single target must not prevent other targets
+		// from receiving the message.
+		if err := es.forwarder.Forward(r.Target, r.Topic(msg), payload); err != nil {
+			ferr = err
+		}
+	}
+
+	return ferr
+}
+
+func (es *egressService) Reload(ctx context.Context) error {
+	rules, err := es.rules.RetrieveAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	active := make(map[string]map[string]Rule)
+	for _, r := range rules {
+		if _, ok := active[r.Channel]; !ok {
+			active[r.Channel] = make(map[string]Rule)
+		}
+		active[r.Channel][r.ID] = r
+	}
+
+	es.mu.Lock()
+	es.active = active
+	es.mu.Unlock()
+
+	return nil
+}
+
+func (es *egressService) SyncRule(ctx context.Context, id string) error {
+	rule, err := es.rules.RetrieveByID(ctx, id)
+	switch err {
+	case nil:
+		es.activate(rule)
+		return nil
+	case ErrNotFound:
+		es.deactivateByID(id)
+		return nil
+	default:
+		return err
+	}
+}
+
+func (es *egressService) RemoveChannel(ctx context.Context, chanID string) error {
+	rules, err := es.rules.RetrieveByChannel(ctx, chanID)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range rules {
+		if err := es.rules.Remove(ctx, r.ID); err != nil {
+			return err
+		}
+	}
+
+	es.mu.Lock()
+	delete(es.active, chanID)
+	es.mu.Unlock()
+
+	return nil
+}
+
+func (es *egressService) identify(ctx context.Context, token string) (string, error) {
+	res, err := es.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return "", ErrUnauthorizedAccess
+	}
+
+	return res.GetValue(), nil
+}
+
+func (es *egressService) activate(rule Rule) {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+
+	// Rule channel can't be changed, but make sure there are no stale copies.
+	for ch, rules := range es.active {
+		if _, ok := rules[rule.ID]; ok && ch != rule.Channel {
+			delete(rules, rule.ID)
+		}
+	}
+
+	if _, ok := es.active[rule.Channel]; !ok {
+		es.active[rule.Channel] = make(map[string]Rule)
+	}
+	es.active[rule.Channel][rule.ID] = rule
+}
+
+func (es *egressService) deactivate(chanID, id string) {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+
+	delete(es.active[chanID], id)
+	if len(es.active[chanID]) == 0 {
+		delete(es.active, chanID)
+	}
+}
+
+func (es *egressService) deactivateByID(id string) {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+
+	for ch, rules := range es.active {
+		delete(rules, id)
+		if len(rules) == 0 {
+			delete(es.active, ch)
+		}
+	}
+}
+
+// encode converts normalized message back to the SenML JSON, so that the
+// external consumers don't depend on the Mainflux internal representation.
+func encode(msg mainflux.Message) ([]byte, error) {
+	rec := senml.SenMLRecord{
+		BaseName:   msg.Publisher,
+		Name:       msg.Name,
+		Unit:       msg.Unit,
+		Time:       msg.Time,
+		UpdateTime: msg.UpdateTime,
+		Link:       msg.Link,
+	}
+
+	switch v := msg.Value.(type) {
+	case *mainflux.Message_FloatValue:
+		rec.Value = &v.FloatValue
+	case *mainflux.Message_StringValue:
+		rec.StringValue = v.StringValue
+	case *mainflux.Message_BoolValue:
+		rec.BoolValue = &v.BoolValue
+	case *mainflux.Message_DataValue:
+		rec.DataValue = v.DataValue
+	}
+
+	if msg.ValueSum != nil {
+		rec.Sum = &msg.ValueSum.Value
+	}
+
+	s := senml.SenML{Records: []senml.SenMLRecord{rec}}
+	return senml.Encode(s, senml.JSON, senml.OutputOptions{})
+}
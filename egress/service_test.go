@@ -0,0 +1,327 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package egress_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/egress"
+	"github.com/mainflux/mainflux/egress/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	wrongValue = "wrong-value"
+	email      = "user@example.com"
+	token      = "token"
+	chanID     = "1"
+	otherToken = "other"
+	otherChan  = "2"
+)
+
+var rule = egress.Rule{
+	Channel: chanID,
+	Filter: egress.Filter{
+		Subtopic:  "temp.>",
+		Name:      "temperature",
+		Operator:  egress.OpGt,
+		Threshold: 30,
+	},
+	Target: egress.Target{
+		Protocol: egress.MQTT,
+		URL:      "tcp://broker.example.com:1883",
+		Topic:    "alarms/{channel}/{publisher}",
+	},
+}
+
+func newService(tokens map[string]string) (egress.Service, egress.RuleRepository, *mocks.Forwarder) {
+	users := mocks.NewUsersService(tokens)
+	channels := mocks.NewChannels(map[string]string{chanID: token, otherChan: otherToken})
+	repo := mocks.NewRuleRepository()
+	fwd := mocks.NewForwarder()
+	idp := mocks.NewIdentityProvider()
+
+	return egress.New(users, channels, repo, fwd, idp, egress.Config{Hosts: []string{"broker.example.com", "*.example.org"}}), repo, fwd
+}
+
+func TestAddRule(t *testing.T) {
+	svc, _, _ := newService(map[string]string{token: email, otherToken: "other@example.com"})
+
+	invalid := rule
+	invalid.Target.Protocol = "amqp"
+
+	foreign := rule
+	foreign.Channel = otherChan
+
+	subdomain := rule
+	subdomain.Target.URL = "nats://nats.example.org:4222"
+
+	internal := rule
+	internal.Target.URL = "tcp://10.0.0.1:1883"
+
+	suffix := rule
+	suffix.Target.URL = "tcp://broker.example.com.evil.net:1883"
+
+	cases := []struct {
+		desc  string
+		rule  egress.Rule
+		token string
+		err   error
+	}{
+		{
+			desc:  "add valid rule",
+			rule:  rule,
+			token: token,
+			err:   nil,
+		},
+		{
+			desc:  "add rule with wrong credentials",
+			rule:  rule,
+			token: wrongValue,
+			err:   egress.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "add rule with unsupported target protocol",
+			rule:  invalid,
+			token: token,
+			err:   egress.ErrMalformedEntity,
+		},
+		{
+			desc:  "add rule with target on allowed subdomain",
+			rule:  subdomain,
+			token: token,
+			err:   nil,
+		},
+		{
+			desc:  "add rule with target on internal host",
+			rule:  internal,
+			token: token,
+			err:   egress.ErrTargetNotAllowed,
+		},
+		{
+			desc:  "add rule with target on host sharing allowed prefix",
+			rule:  suffix,
+			token: token,
+			err:   egress.ErrTargetNotAllowed,
+		},
+		{
+			desc:  "add rule to channel owned by another user",
+			rule:  foreign,
+			token: token,
+			err:   egress.ErrNotFound,
+		},
+		{
+			desc:  "add rule to non-existing channel",
+			rule:  egress.Rule{Channel: wrongValue, Target: rule.Target},
+			token: token,
+			err:   egress.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := svc.AddRule(context.Background(), tc.token, tc.rule)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestViewRule(t *testing.T) {
+	svc, _, _ := newService(map[string]string{token: email, otherToken: "other@example.com"})
+	saved, err := svc.AddRule(context.Background(), token, rule)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc  string
+		id    string
+		token string
+		err   error
+	}{
+		{
+			desc:  "view existing rule",
+			id:    saved.ID,
+			token: token,
+			err:   nil,
+		},
+		{
+			desc:  "view rule with wrong credentials",
+			id:    saved.ID,
+			token: wrongValue,
+			err:   egress.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "view rule owned by other user",
+			id:    saved.ID,
+			token: otherToken,
+			err:   egress.ErrNotFound,
+		},
+		{
+			desc:  "view non-existing rule",
+			id:    wrongValue,
+			token: token,
+			err:   egress.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := svc.ViewRule(context.Background(), tc.token, tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestListRules(t *testing.T) {
+	svc, _, _ := newService(map[string]string{token: email})
+	n := uint64(5)
+	for i := uint64(0); i < n; i++ {
+		_, err := svc.AddRule(context.Background(), token, rule)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	}
+
+	cases := []struct {
+		desc   string
+		token  string
+		offset uint64
+		limit  uint64
+		size   int
+		err    error
+	}{
+		{
+			desc:   "list all rules",
+			token:  token,
+			offset: 0,
+			limit:  n,
+			size:   int(n),
+		},
+		{
+			desc:   "list last rule",
+			token:  token,
+			offset: n - 1,
+			limit:  n,
+			size:   1,
+		},
+		{
+			desc:   "list rules with wrong credentials",
+			token:  wrongValue,
+			offset: 0,
+			limit:  n,
+			size:   0,
+			err:    egress.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		page, err := svc.ListRules(context.Background(), tc.token, tc.offset, tc.limit)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.size, len(page.Rules), fmt.Sprintf("%s: expected %d got %d\n", tc.desc, tc.size, len(page.Rules)))
+	}
+}
+
+func TestRemoveRule(t *testing.T) {
+	svc, _, fwd := newService(map[string]string{token: email})
+	saved, err := svc.AddRule(context.Background(), token, rule)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	err = svc.RemoveRule(context.Background(), wrongValue, saved.ID)
+	assert.Equal(t, egress.ErrUnauthorizedAccess, err, fmt.Sprintf("remove rule with wrong credentials: expected %s got %s\n", egress.ErrUnauthorizedAccess, err))
+
+	err = svc.RemoveRule(context.Background(), token, saved.ID)
+	assert.Nil(t, err, fmt.Sprintf("remove existing rule: unexpected error: %s\n", err))
+
+	err = svc.Forward(context.Background(), message(35))
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.Empty(t, fwd.Forwarded(), "removed rule must not forward messages")
+}
+
+func TestForward(t *testing.T) {
+	svc, _, fwd := newService(map[string]string{token: email})
+	_, err := svc.AddRule(context.Background(), token, rule)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	other := message(35)
+	other.Subtopic = "humidity"
+
+	cases := []struct {
+		desc      string
+		msg       mainflux.Message
+		forwarded int
+	}{
+		{
+			desc:      "forward message above threshold",
+			msg:       message(35),
+			forwarded: 1,
+		},
+		{
+			desc:      "skip message below threshold",
+			msg:       message(25),
+			forwarded: 0,
+		},
+		{
+			desc:      "skip message with non-matching subtopic",
+			msg:       other,
+			forwarded: 0,
+		},
+	}
+
+	for _, tc := range cases {
+		before := len(fwd.Forwarded())
+		err := svc.Forward(context.Background(), tc.msg)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s\n", tc.desc, err))
+		assert.Equal(t, tc.forwarded, len(fwd.Forwarded())-before, fmt.Sprintf("%s: expected %d forwarded messages\n", tc.desc, tc.forwarded))
+	}
+
+	out := fwd.Forwarded()
+	require.NotEmpty(t, out, "expected forwarded message")
+	assert.Equal(t, "alarms/1/device", out[0].Topic, fmt.Sprintf("expected topic alarms/1/device got %s\n", out[0].Topic))
+}
+
+func TestSyncRule(t *testing.T) {
+	svc, repo, fwd := newService(map[string]string{token: email})
+
+	// Rule created by another service instance is only persisted.
+	r := rule
+	r.ID = "rule"
+	r.Owner = email
+	err := repo.Save(context.Background(), r)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	err = svc.SyncRule(context.Background(), r.ID)
+	assert.Nil(t, err, fmt.Sprintf("sync created rule: unexpected error: %s\n", err))
+	err = svc.Forward(context.Background(), message(35))
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.Len(t, fwd.Forwarded(), 1, "synced rule must forward messages")
+
+	err = repo.Remove(context.Background(), r.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	err = svc.SyncRule(context.Background(), r.ID)
+	assert.Nil(t, err, fmt.Sprintf("sync removed rule: unexpected error: %s\n", err))
+	err = svc.Forward(context.Background(), message(35))
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.Len(t, fwd.Forwarded(), 1, "removed rule must not forward messages")
+}
+
+func TestRemoveChannel(t *testing.T) {
+	svc, repo, _ := newService(map[string]string{token: email})
+	_, err := svc.AddRule(context.Background(), token, rule)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	err = svc.RemoveChannel(context.Background(), chanID)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	rules, err := repo.RetrieveByChannel(context.Background(), chanID)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.Empty(t, rules, "rules of the removed channel must be removed")
+}
+
+func message(val float64) mainflux.Message {
+	return mainflux.Message{
+		Channel:   chanID,
+		Subtopic:  "temp.room",
+		Publisher: "device",
+		Name:      "temperature",
+		Value:     &mainflux.Message_FloatValue{FloatValue: val},
+	}
+}
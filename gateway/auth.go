@@ -0,0 +1,139 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package gateway
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	// ErrUnauthorizedAccess indicates missing or invalid user token.
+	ErrUnauthorizedAccess = errors.New("missing or invalid credentials provided")
+
+	// ErrMalformedRules indicates malformed authentication rules
+	// specification.
+	ErrMalformedRules = errors.New("malformed authentication rules")
+)
+
+// AuthRule tells if the requests whose path starts with the prefix, made
+// with the method or with any method if it's empty, require the valid user
+// token. Public rules exempt the requests from the token validation.
+type AuthRule struct {
+	Method string
+	Prefix string
+	Public bool
+}
+
+// ParseAuthRules parses the comma separated authentication rules in the form
+// [!][METHOD ]prefix, where the exclamation mark marks the public rule, e.g.
+// /things,GET /users,!/things/bootstrap.
+func ParseAuthRules(rules string) ([]AuthRule, error) {
+	var parsed []AuthRule
+	for _, rule := range strings.Split(rules, ",") {
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+		var r AuthRule
+		if strings.HasPrefix(rule, "!") {
+			r.Public = true
+			rule = rule[1:]
+		}
+		fields := strings.Fields(rule)
+		switch len(fields) {
+		case 1:
+			r.Prefix = fields[0]
+		case 2:
+			r.Method, r.Prefix = strings.ToUpper(fields[0]), fields[1]
+		default:
+			return nil, ErrMalformedRules
+		}
+		if !strings.HasPrefix(r.Prefix, "/") {
+			return nil, ErrMalformedRules
+		}
+		parsed = append(parsed, r)
+	}
+	return parsed, nil
+}
+
+type authenticator struct {
+	next    http.Handler
+	users   mainflux.UsersServiceClient
+	rules   []AuthRule
+	timeout time.Duration
+}
+
+// Authenticate returns the handler rejecting the requests which require the
+// user token, but don't carry the one the users service identifies. The
+// request is matched against the rule with the longest prefix, preferring
+// the rules with the method set.
+func Authenticate(next http.Handler, users mainflux.UsersServiceClient, rules []AuthRule, timeout time.Duration) http.Handler {
+	sorted := append([]AuthRule{}, rules...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if len(sorted[i].Prefix) != len(sorted[j].Prefix) {
+			return len(sorted[i].Prefix) > len(sorted[j].Prefix)
+		}
+		return sorted[i].Method != "" && sorted[j].Method == ""
+	})
+	return &authenticator{
+		next:    next,
+		users:   users,
+		rules:   sorted,
+		timeout: timeout,
+	}
+}
+
+func (a *authenticator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !a.protected(r) {
+		a.next.ServeHTTP(w, r)
+		return
+	}
+
+	token := r.Header.Get("Authorization")
+	if token == "" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), a.timeout)
+	defer cancel()
+
+	if _, err := a.users.Identify(ctx, &mainflux.Token{Value: token}); err != nil {
+		w.WriteHeader(identifyStatus(err))
+		return
+	}
+
+	a.next.ServeHTTP(w, r)
+}
+
+func (a *authenticator) protected(r *http.Request) bool {
+	for _, rule := range a.rules {
+		if rule.Method != "" && rule.Method != r.Method {
+			continue
+		}
+		if strings.HasPrefix(r.URL.Path, rule.Prefix) {
+			return !rule.Public
+		}
+	}
+	return false
+}
+
+func identifyStatus(err error) int {
+	if e, ok := status.FromError(err); ok {
+		switch e.Code() {
+		case codes.Unavailable, codes.DeadlineExceeded:
+			return http.StatusServiceUnavailable
+		}
+	}
+	return http.StatusForbidden
+}
@@ -0,0 +1,8 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package gateway contains the building blocks of the Mainflux API gateway.
+// The gateway fronts the HTTP services under one host, routing the requests
+// by their path, validating the user tokens, limiting the request rate and
+// logging the requests.
+package gateway
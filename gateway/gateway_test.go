@@ -0,0 +1,305 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package gateway_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/gateway"
+	"github.com/mainflux/mainflux/gateway/mocks"
+	"github.com/mainflux/mainflux/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	token   = "token"
+	email   = "user@example.com"
+	timeout = time.Second
+)
+
+// newBackend returns the server echoing the service name and the request
+// path.
+func newBackend(name string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "%s %s", name, r.URL.RequestURI())
+	}))
+}
+
+func get(t *testing.T, h http.Handler, method, path, token string) (int, string) {
+	req := httptest.NewRequest(method, path, nil)
+	if token != "" {
+		req.Header.Set("Authorization", token)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	body, err := ioutil.ReadAll(rec.Body)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	return rec.Code, string(body)
+}
+
+func TestParseRoutes(t *testing.T) {
+	cases := []struct {
+		desc   string
+		routes string
+		count  int
+		err    error
+	}{
+		{
+			desc:   "parse valid routes",
+			routes: "/things=http://things:8182; /http/=http://http-adapter:8185/",
+			count:  2,
+			err:    nil,
+		},
+		{
+			desc:   "parse empty routes",
+			routes: "",
+			count:  0,
+			err:    nil,
+		},
+		{
+			desc:   "parse route without target",
+			routes: "/things",
+			err:    gateway.ErrMalformedRoutes,
+		},
+		{
+			desc:   "parse route with relative prefix",
+			routes: "things=http://things:8182",
+			err:    gateway.ErrMalformedRoutes,
+		},
+		{
+			desc:   "parse route with relative target",
+			routes: "/things=things:8182",
+			err:    gateway.ErrMalformedRoutes,
+		},
+	}
+
+	for _, tc := range cases {
+		routes, err := gateway.ParseRoutes(tc.routes)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Len(t, routes, tc.count, fmt.Sprintf("%s: expected %d routes got %d\n", tc.desc, tc.count, len(routes)))
+	}
+}
+
+func TestRouter(t *testing.T) {
+	things := newBackend("things")
+	defer things.Close()
+	bootstrap := newBackend("bootstrap")
+	defer bootstrap.Close()
+	adapter := newBackend("http")
+	defer adapter.Close()
+
+	routes, err := gateway.ParseRoutes(fmt.Sprintf("/things=%s;/things/configs=%s;/http/=%s/;/down=http://localhost:1",
+		things.URL, bootstrap.URL, adapter.URL))
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	router := gateway.NewRouter(routes)
+
+	cases := []struct {
+		desc   string
+		path   string
+		status int
+		body   string
+	}{
+		{
+			desc:   "route request by prefix",
+			path:   "/things/1?offset=1",
+			status: http.StatusOK,
+			body:   "things /things/1?offset=1",
+		},
+		{
+			desc:   "route request by longest prefix",
+			path:   "/things/configs/1",
+			status: http.StatusOK,
+			body:   "bootstrap /things/configs/1",
+		},
+		{
+			desc:   "route request replacing prefix",
+			path:   "/http/channels/1/messages",
+			status: http.StatusOK,
+			body:   "http /channels/1/messages",
+		},
+		{
+			desc:   "route request to unavailable service",
+			path:   "/down",
+			status: http.StatusBadGateway,
+			body:   "",
+		},
+		{
+			desc:   "route request without route",
+			path:   "/users",
+			status: http.StatusNotFound,
+			body:   "",
+		},
+	}
+
+	for _, tc := range cases {
+		status, body := get(t, router, http.MethodGet, tc.path, "")
+		assert.Equal(t, tc.status, status, fmt.Sprintf("%s: expected status %d got %d\n", tc.desc, tc.status, status))
+		assert.Equal(t, tc.body, body, fmt.Sprintf("%s: expected body %s got %s\n", tc.desc, tc.body, body))
+	}
+}
+
+func TestParseAuthRules(t *testing.T) {
+	cases := []struct {
+		desc   string
+		rules  string
+		parsed []gateway.AuthRule
+		err    error
+	}{
+		{
+			desc:  "parse valid rules",
+			rules: "/things, get /users, !/things/bootstrap",
+			parsed: []gateway.AuthRule{
+				{Prefix: "/things"},
+				{Method: http.MethodGet, Prefix: "/users"},
+				{Prefix: "/things/bootstrap", Public: true},
+			},
+			err: nil,
+		},
+		{
+			desc:  "parse rule with relative prefix",
+			rules: "things",
+			err:   gateway.ErrMalformedRules,
+		},
+		{
+			desc:  "parse rule with too many fields",
+			rules: "GET POST /things",
+			err:   gateway.ErrMalformedRules,
+		},
+	}
+
+	for _, tc := range cases {
+		parsed, err := gateway.ParseAuthRules(tc.rules)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.parsed, parsed, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.parsed, parsed))
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	rules, err := gateway.ParseAuthRules("/things,GET /users,!/things/bootstrap")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	users := mocks.NewUsersService(map[string]string{token: email})
+	h := gateway.Authenticate(next, users, rules, timeout)
+
+	cases := []struct {
+		desc   string
+		method string
+		path   string
+		token  string
+		status int
+	}{
+		{
+			desc:   "access protected path with valid token",
+			method: http.MethodGet,
+			path:   "/things",
+			token:  token,
+			status: http.StatusOK,
+		},
+		{
+			desc:   "access protected path with invalid token",
+			method: http.MethodGet,
+			path:   "/things",
+			token:  "invalid",
+			status: http.StatusForbidden,
+		},
+		{
+			desc:   "access protected path without token",
+			method: http.MethodGet,
+			path:   "/things",
+			token:  "",
+			status: http.StatusForbidden,
+		},
+		{
+			desc:   "access public path without token",
+			method: http.MethodGet,
+			path:   "/things/bootstrap/1",
+			token:  "",
+			status: http.StatusOK,
+		},
+		{
+			desc:   "access path protected for method without token",
+			method: http.MethodGet,
+			path:   "/users",
+			token:  "",
+			status: http.StatusForbidden,
+		},
+		{
+			desc:   "access path protected for other method without token",
+			method: http.MethodPost,
+			path:   "/users",
+			token:  "",
+			status: http.StatusOK,
+		},
+		{
+			desc:   "access path without rule without token",
+			method: http.MethodPost,
+			path:   "/tokens",
+			token:  "",
+			status: http.StatusOK,
+		},
+	}
+
+	for _, tc := range cases {
+		status, _ := get(t, h, tc.method, tc.path, tc.token)
+		assert.Equal(t, tc.status, status, fmt.Sprintf("%s: expected status %d got %d\n", tc.desc, tc.status, status))
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	h := gateway.RateLimit(next, 0.001, 2)
+
+	cases := []struct {
+		desc   string
+		addr   string
+		status int
+	}{
+		{
+			desc:   "send first request within burst",
+			addr:   "10.0.0.1:1000",
+			status: http.StatusOK,
+		},
+		{
+			desc:   "send second request within burst from other port",
+			addr:   "10.0.0.1:1001",
+			status: http.StatusOK,
+		},
+		{
+			desc:   "send request over limit",
+			addr:   "10.0.0.1:1000",
+			status: http.StatusTooManyRequests,
+		},
+		{
+			desc:   "send request from other client",
+			addr:   "10.0.0.2:1000",
+			status: http.StatusOK,
+		},
+	}
+
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/things", nil)
+		req.RemoteAddr = tc.addr
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		assert.Equal(t, tc.status, rec.Code, fmt.Sprintf("%s: expected status %d got %d\n", tc.desc, tc.status, rec.Code))
+	}
+}
+
+func TestLog(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+	logger, err := logger.New(ioutil.Discard, "info")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	h := gateway.Log(next, logger)
+
+	status, _ := get(t, h, http.MethodPost, "/things", token)
+	assert.Equal(t, http.StatusCreated, status, fmt.Sprintf("expected status %d got %d\n", http.StatusCreated, status))
+}
@@ -0,0 +1,46 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package gateway
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	log "github.com/mainflux/mainflux/logger"
+)
+
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Flush lets the streamed responses, such as message exports, through.
+func (w *statusWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Log returns the handler logging the method, path, status and duration of
+// each request.
+func Log(next http.Handler, logger log.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		begin := time.Now()
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(sw, r)
+
+		message := fmt.Sprintf("Request %s %s from %s took %s to complete with status %d", r.Method, r.URL.Path, r.RemoteAddr, time.Since(begin), sw.status)
+		if sw.status >= http.StatusInternalServerError {
+			logger.Warn(fmt.Sprintf("%s.", message))
+			return
+		}
+		logger.Info(fmt.Sprintf("%s.", message))
+	})
+}
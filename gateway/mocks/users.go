@@ -0,0 +1,41 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/gateway"
+	"google.golang.org/grpc"
+)
+
+var _ mainflux.UsersServiceClient = (*usersServiceMock)(nil)
+
+type usersServiceMock struct {
+	users map[string]string
+}
+
+// NewUsersService creates mock of users service.
+func NewUsersService(users map[string]string) mainflux.UsersServiceClient {
+	return &usersServiceMock{users}
+}
+
+func (svc usersServiceMock) Identify(ctx context.Context, in *mainflux.Token, opts ...grpc.CallOption) (*mainflux.UserID, error) {
+	if id, ok := svc.users[in.Value]; ok {
+		return &mainflux.UserID{Value: id}, nil
+	}
+	return nil, gateway.ErrUnauthorizedAccess
+}
+
+func (svc usersServiceMock) Groups(ctx context.Context, in *mainflux.Token, opts ...grpc.CallOption) (*mainflux.GroupIDs, error) {
+	if _, ok := svc.users[in.Value]; ok {
+		return &mainflux.GroupIDs{}, nil
+	}
+	return nil, gateway.ErrUnauthorizedAccess
+}
+
+func (svc usersServiceMock) Disabled(ctx context.Context, in *mainflux.UserID, opts ...grpc.CallOption) (*mainflux.UserStatus, error) {
+	return &mainflux.UserStatus{}, nil
+}
@@ -0,0 +1,100 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package gateway
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// ErrMalformedRoutes indicates malformed routes specification.
+var ErrMalformedRoutes = errors.New("malformed routes")
+
+// Route forwards the requests whose path starts with the prefix to the
+// target service. If the target URL has a path, it replaces the prefix of the
+// forwarded request path, otherwise the path is forwarded unchanged.
+type Route struct {
+	Prefix string
+	Target *url.URL
+}
+
+// ParseRoutes parses the semicolon separated routes in the form
+// prefix=target, e.g. /things=http://things:8182;/http/=http://http-adapter:8185/.
+func ParseRoutes(routes string) ([]Route, error) {
+	var parsed []Route
+	for _, route := range strings.Split(routes, ";") {
+		route = strings.TrimSpace(route)
+		if route == "" {
+			continue
+		}
+		parts := strings.SplitN(route, "=", 2)
+		if len(parts) != 2 || !strings.HasPrefix(parts[0], "/") {
+			return nil, ErrMalformedRoutes
+		}
+		target, err := url.Parse(parts[1])
+		if err != nil || target.Scheme == "" || target.Host == "" {
+			return nil, ErrMalformedRoutes
+		}
+		parsed = append(parsed, Route{Prefix: parts[0], Target: target})
+	}
+	return parsed, nil
+}
+
+type router struct {
+	routes  []Route
+	proxies []http.Handler
+}
+
+// NewRouter returns the handler forwarding the requests by the route with the
+// longest prefix matching the request path. Requests not matching any of the
+// routes are rejected.
+func NewRouter(routes []Route) http.Handler {
+	sorted := append([]Route{}, routes...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return len(sorted[i].Prefix) > len(sorted[j].Prefix)
+	})
+
+	r := &router{routes: sorted}
+	for _, route := range sorted {
+		r.proxies = append(r.proxies, newProxy(route))
+	}
+	return r
+}
+
+func (r *router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	for i, route := range r.routes {
+		if strings.HasPrefix(req.URL.Path, route.Prefix) {
+			r.proxies[i].ServeHTTP(w, req)
+			return
+		}
+	}
+	w.WriteHeader(http.StatusNotFound)
+}
+
+func newProxy(route Route) http.Handler {
+	target := route.Target
+	director := func(req *http.Request) {
+		req.URL.Scheme = target.Scheme
+		req.URL.Host = target.Host
+		if target.Path != "" {
+			req.URL.Path = target.Path + strings.TrimPrefix(req.URL.Path, route.Prefix)
+			req.URL.RawPath = ""
+		}
+		if _, ok := req.Header["User-Agent"]; !ok {
+			// Prevents the default user agent from being set.
+			req.Header.Set("User-Agent", "")
+		}
+	}
+
+	return &httputil.ReverseProxy{
+		Director: director,
+		ErrorHandler: func(w http.ResponseWriter, _ *http.Request, _ error) {
+			w.WriteHeader(http.StatusBadGateway)
+		},
+	}
+}
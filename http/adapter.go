@@ -7,35 +7,201 @@ package http
 
 import (
 	"context"
+	"errors"
+	"io"
+	"time"
 
 	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/things"
+	"github.com/mainflux/mainflux/things/keys"
 )
 
-var _ mainflux.MessagePublisher = (*adapterService)(nil)
+const (
+	// ResponsesSubtopic is the subtopic of the command subtopic which the
+	// replies to the commands are published to.
+	ResponsesSubtopic = "responses"
+
+	// streamAuthPeriod is the period after which the stream checks the
+	// access to the channel again.
+	streamAuthPeriod = time.Minute
+)
+
+// ErrRequestTimeout indicates that the reply to the command wasn't received
+// within the request timeout.
+var ErrRequestTimeout = errors.New("request timed out")
+
+// Service specifies HTTP adapter API.
+type Service interface {
+	mainflux.MessagePublisher
+
+	// Request publishes the command message with the new correlation ID, and
+	// waits for the reply published with the same correlation ID to the
+	// responses subtopic of the command subtopic.
+	Request(context.Context, string, mainflux.RawMessage, time.Duration) (mainflux.RawMessage, error)
+
+	// PublishStream publishes the messages received from the stream until
+	// the stream ends, and returns the number of the published messages.
+	// Access to the channel is checked once per channel and authorization
+	// period, rather than per message.
+	PublishStream(context.Context, string, func() (mainflux.RawMessage, error)) (uint64, error)
+}
+
+// Replies receives the replies to the commands.
+type Replies interface {
+	// Subscribe starts receiving the messages published to the channel
+	// subtopic with the given correlation ID. Messages are received from the
+	// returned channel until the returned function cancels the subscription.
+	Subscribe(chanID, subtopic, correlationID string) (<-chan mainflux.RawMessage, func(), error)
+}
+
+// IdentityProvider specifies an API for generating the correlation IDs.
+type IdentityProvider interface {
+	// ID generates the unique identifier.
+	ID() (string, error)
+}
+
+// ResponseSubtopic returns the subtopic the replies to the commands published
+// to the given subtopic are published to.
+func ResponseSubtopic(subtopic string) string {
+	if subtopic == "" {
+		return ResponsesSubtopic
+	}
+	return subtopic + "." + ResponsesSubtopic
+}
+
+var _ Service = (*adapterService)(nil)
 
 type adapterService struct {
-	pub    mainflux.MessagePublisher
-	things mainflux.ThingsServiceClient
+	pub     mainflux.MessagePublisher
+	replies Replies
+	things  mainflux.ThingsServiceClient
+	idp     IdentityProvider
 }
 
 // New instantiates the HTTP adapter implementation.
-func New(pub mainflux.MessagePublisher, things mainflux.ThingsServiceClient) mainflux.MessagePublisher {
+func New(pub mainflux.MessagePublisher, replies Replies, things mainflux.ThingsServiceClient, idp IdentityProvider) Service {
 	return &adapterService{
-		pub:    pub,
-		things: things,
+		pub:     pub,
+		replies: replies,
+		things:  things,
+		idp:     idp,
 	}
 }
 
 func (as *adapterService) Publish(ctx context.Context, token string, msg mainflux.RawMessage) error {
+	thid, err := as.authorize(ctx, token, msg.GetChannel(), things.Publish)
+	if err != nil {
+		return err
+	}
+	msg.Publisher = thid
+
+	return as.pub.Publish(ctx, token, msg)
+}
+
+func (as *adapterService) Request(ctx context.Context, token string, msg mainflux.RawMessage, timeout time.Duration) (mainflux.RawMessage, error) {
+	thid, err := as.authorize(ctx, token, msg.GetChannel(), things.Publish)
+	if err != nil {
+		return mainflux.RawMessage{}, err
+	}
+
+	// The commander has to be allowed to receive the reply as well.
+	if _, err := as.authorize(ctx, token, msg.GetChannel(), things.Subscribe); err != nil {
+		return mainflux.RawMessage{}, err
+	}
+
+	id, err := as.idp.ID()
+	if err != nil {
+		return mainflux.RawMessage{}, err
+	}
+	msg.Publisher = thid
+	msg.CorrelationID = id
+
+	// Subscription precedes the command, so that the reply can't be missed.
+	replies, cancel, err := as.replies.Subscribe(msg.Channel, ResponseSubtopic(msg.Subtopic), id)
+	if err != nil {
+		return mainflux.RawMessage{}, err
+	}
+	defer cancel()
+
+	if err := as.pub.Publish(ctx, token, msg); err != nil {
+		return mainflux.RawMessage{}, err
+	}
+
+	t := time.NewTimer(timeout)
+	defer t.Stop()
+
+	select {
+	case reply := <-replies:
+		return reply, nil
+	case <-t.C:
+		return mainflux.RawMessage{}, ErrRequestTimeout
+	case <-ctx.Done():
+		return mainflux.RawMessage{}, ctx.Err()
+	}
+}
+
+type grant struct {
+	thingID string
+	expires time.Time
+}
+
+func (as *adapterService) PublishStream(ctx context.Context, token string, recv func() (mainflux.RawMessage, error)) (uint64, error) {
+	grants := make(map[string]grant)
+
+	var published uint64
+	for {
+		msg, err := recv()
+		if err == io.EOF {
+			return published, nil
+		}
+		if err != nil {
+			return published, err
+		}
+
+		g, ok := grants[msg.Channel]
+		if now := time.Now(); !ok || now.After(g.expires) {
+			thid, err := as.authorize(ctx, token, msg.Channel, things.Publish)
+			if err != nil {
+				return published, err
+			}
+			g = grant{thingID: thid, expires: now.Add(streamAuthPeriod)}
+			grants[msg.Channel] = g
+		}
+		msg.Publisher = g.thingID
+
+		if err := as.pub.Publish(ctx, token, msg); err != nil {
+			return published, err
+		}
+		published++
+	}
+}
+
+func (as *adapterService) authorize(ctx context.Context, token, chanID, action string) (string, error) {
+	if thid := CertThing(ctx); thid != "" {
+		ar := &mainflux.AccessByIDReq{
+			ThingID: thid,
+			ChanID:  chanID,
+			Action:  action,
+		}
+		if _, err := as.things.CanAccessByID(ctx, ar); err != nil {
+			return "", err
+		}
+		return thid, nil
+	}
+
+	if keys.Malformed(token) {
+		return "", things.ErrUnauthorizedAccess
+	}
+
 	ar := &mainflux.AccessReq{
 		Token:  token,
-		ChanID: msg.GetChannel(),
+		ChanID: chanID,
+		Action: action,
 	}
 	thid, err := as.things.CanAccess(ctx, ar)
 	if err != nil {
-		return err
+		return "", err
 	}
-	msg.Publisher = thid.GetValue()
 
-	return as.pub.Publish(ctx, token, msg)
+	return thid.GetValue(), nil
 }
@@ -0,0 +1,88 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	// HeaderScheme reads the thing key from the request header.
+	HeaderScheme = "header"
+	// BasicScheme reads the thing key from the basic authorization
+	// credentials.
+	BasicScheme = "basic"
+	// QueryScheme reads the thing key from the query parameter.
+	QueryScheme = "query"
+)
+
+// KeyExtractor returns the thing key carried by the request, or an empty
+// string if the request doesn't carry it.
+type KeyExtractor func(r *http.Request) string
+
+// HeaderKey returns the extractor reading the thing key from the header.
+func HeaderKey(header string) KeyExtractor {
+	return func(r *http.Request) string {
+		return r.Header.Get(header)
+	}
+}
+
+// BasicKey returns the extractor reading the thing key from the basic
+// authorization password. Devices that can set the user name only may send
+// the key as the user name with the empty password.
+func BasicKey() KeyExtractor {
+	return func(r *http.Request) string {
+		user, pass, ok := r.BasicAuth()
+		if !ok {
+			return ""
+		}
+		if pass == "" {
+			return user
+		}
+		return pass
+	}
+}
+
+// QueryKey returns the extractor reading the thing key from the query
+// parameter.
+func QueryKey(param string) KeyExtractor {
+	return func(r *http.Request) string {
+		return r.URL.Query().Get(param)
+	}
+}
+
+// FirstKey returns the extractor returning the first key found by the given
+// extractors, in order.
+func FirstKey(extractors ...KeyExtractor) KeyExtractor {
+	return func(r *http.Request) string {
+		for _, extract := range extractors {
+			if key := extract(r); key != "" {
+				return key
+			}
+		}
+		return ""
+	}
+}
+
+// ParseSchemes returns the extractor trying the comma separated
+// authentication schemes in order. The header and query schemes read the key
+// from the given header and query parameter respectively.
+func ParseSchemes(schemes, header, param string) (KeyExtractor, error) {
+	var extractors []KeyExtractor
+	for _, scheme := range strings.Split(schemes, ",") {
+		switch strings.TrimSpace(scheme) {
+		case HeaderScheme:
+			extractors = append(extractors, HeaderKey(header))
+		case BasicScheme:
+			extractors = append(extractors, BasicKey())
+		case QueryScheme:
+			extractors = append(extractors, QueryKey(param))
+		default:
+			return nil, fmt.Errorf("unknown authentication scheme %q", scheme)
+		}
+	}
+	return FirstKey(extractors...), nil
+}
@@ -0,0 +1,87 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+
+	"github.com/mainflux/mainflux/certs"
+	certsclient "github.com/mainflux/mainflux/certs/client"
+	"github.com/mainflux/mainflux/certs/pki"
+	adapter "github.com/mainflux/mainflux/http"
+)
+
+const (
+	// OptionalCerts mode authenticates the clients presenting the
+	// certificate by the certificate, and the others by the thing key.
+	OptionalCerts = "optional"
+	// RequiredCerts mode rejects the clients which don't present the
+	// certificate.
+	RequiredCerts = "require"
+)
+
+// ClientAuth returns the TLS client authentication policy of the mode.
+func ClientAuth(mode string) (tls.ClientAuthType, error) {
+	switch mode {
+	case OptionalCerts:
+		return tls.VerifyClientCertIfGiven, nil
+	case RequiredCerts:
+		return tls.RequireAndVerifyClientCert, nil
+	default:
+		return tls.NoClientCert, fmt.Errorf("unknown client certificate mode %q", mode)
+	}
+}
+
+// CertAuth authenticates the requests carrying the verified client
+// certificate as the thing bound to the certificate. Unless the status
+// client is nil, the certificate is rejected if the certs service doesn't
+// report it as good, so that the revoked certificates are rejected
+// immediately. Requests without the certificate are passed on as they are,
+// to be authenticated by the thing key.
+func CertAuth(next http.Handler, status certsclient.Client) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		cert := r.TLS.VerifiedChains[0][0]
+		thingID := CertThing(cert)
+		if thingID == "" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		if status != nil {
+			s, err := status.Status(r.Context(), pki.FormatSerial(cert.SerialNumber))
+			if err != nil {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			if s != certs.Good {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+		}
+
+		next.ServeHTTP(w, r.WithContext(adapter.WithCertThing(r.Context(), thingID)))
+	})
+}
+
+// CertThing returns the thing ID bound to the certificate, which is the
+// common name or, if it isn't set, the first DNS subject alternative name.
+func CertThing(cert *x509.Certificate) string {
+	if cert.Subject.CommonName != "" {
+		return cert.Subject.CommonName
+	}
+
+	if len(cert.DNSNames) > 0 {
+		return cert.DNSNames[0]
+	}
+
+	return ""
+}
@@ -0,0 +1,161 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/certs"
+	"github.com/mainflux/mainflux/certs/pki"
+	"github.com/mainflux/mainflux/http/api"
+	"github.com/mainflux/mainflux/http/mocks"
+	"github.com/opentracing/opentracing-go/mocktracer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type statusMock map[string]string
+
+func (sm statusMock) Status(_ context.Context, serial string) (string, error) {
+	if s, ok := sm[serial]; ok {
+		return s, nil
+	}
+	return certs.Unknown, nil
+}
+
+func newCA(t *testing.T) (certs.CA, *x509.Certificate) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	tmpl := x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Mainflux CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &tmpl, &tmpl, key.Public(), key)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	cert, err := x509.ParseCertificate(der)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	return pki.New(cert, key, time.Hour), cert
+}
+
+func issue(t *testing.T, ca certs.CA, thingID string) (tls.Certificate, string) {
+	c, err := ca.Issue(context.Background(), thingID, time.Hour)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	pair, err := tls.X509KeyPair([]byte(c.Certificate), []byte(c.PrivateKey))
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	return pair, c.Serial
+}
+
+func TestPublishCertAuth(t *testing.T) {
+	chanID := "1"
+	thingID := "513d02d2-16c1-4f23-98be-9e12f8fee898"
+	token := "auth_token"
+	msg := `[{"n":"current","t":-1,"v":1.6}]`
+	thingsClient := mocks.NewThingsClient(map[string]string{token: thingID})
+	pub := newService(thingsClient)
+
+	ca, caCert := newCA(t)
+	valid, validSerial := issue(t, ca, thingID)
+	revoked, revokedSerial := issue(t, ca, thingID)
+	unknown, unknownSerial := issue(t, ca, "unknown")
+	status := statusMock{
+		validSerial:   certs.Good,
+		revokedSerial: certs.Revoked,
+		unknownSerial: certs.Good,
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(caCert)
+	handler := api.MakeHandler(pub, mocktracer.New(), api.HeaderKey("Authorization"), maxTimeout, maxBody)
+	ts := httptest.NewUnstartedServer(api.CertAuth(handler, status))
+	ts.TLS = &tls.Config{
+		ClientCAs:  pool,
+		ClientAuth: tls.VerifyClientCertIfGiven,
+	}
+	ts.StartTLS()
+	defer ts.Close()
+
+	cases := map[string]struct {
+		certs  []tls.Certificate
+		token  string
+		status int
+	}{
+		"publish message with valid certificate": {
+			certs:  []tls.Certificate{valid},
+			status: http.StatusAccepted,
+		},
+		"publish message with revoked certificate": {
+			certs:  []tls.Certificate{revoked},
+			status: http.StatusForbidden,
+		},
+		"publish message with certificate of unknown thing": {
+			certs:  []tls.Certificate{unknown},
+			status: http.StatusForbidden,
+		},
+		"publish message with key and without certificate": {
+			token:  token,
+			status: http.StatusAccepted,
+		},
+		"publish message without key and certificate": {
+			status: http.StatusForbidden,
+		},
+	}
+
+	for desc, tc := range cases {
+		client := ts.Client()
+		transport := client.Transport.(*http.Transport).Clone()
+		transport.TLSClientConfig.Certificates = tc.certs
+		client.Transport = transport
+
+		req := testRequest{
+			client:      client,
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/channels/%s/messages", ts.URL, chanID),
+			contentType: "application/senml+json",
+			token:       tc.token,
+			body:        strings.NewReader(msg),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestClientAuth(t *testing.T) {
+	cases := map[string]struct {
+		mode string
+		auth tls.ClientAuthType
+		err  bool
+	}{
+		"parse optional mode": {mode: api.OptionalCerts, auth: tls.VerifyClientCertIfGiven},
+		"parse required mode": {mode: api.RequiredCerts, auth: tls.RequireAndVerifyClientCert},
+		"parse unknown mode":  {mode: "verify", auth: tls.NoClientCert, err: true},
+	}
+
+	for desc, tc := range cases {
+		auth, err := api.ClientAuth(tc.mode)
+		assert.Equal(t, tc.err, err != nil, fmt.Sprintf("%s: expected error %t got %s", desc, tc.err, err))
+		assert.Equal(t, tc.auth, auth, fmt.Sprintf("%s: expected %d got %d", desc, tc.auth, auth))
+	}
+}
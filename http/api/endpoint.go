@@ -8,6 +8,7 @@ import (
 
 	"github.com/go-kit/kit/endpoint"
 	"github.com/mainflux/mainflux"
+	adapter "github.com/mainflux/mainflux/http"
 )
 
 func sendMessageEndpoint(svc mainflux.MessagePublisher) endpoint.Endpoint {
@@ -17,3 +18,15 @@ func sendMessageEndpoint(svc mainflux.MessagePublisher) endpoint.Endpoint {
 		return nil, err
 	}
 }
+
+func sendCommandEndpoint(svc adapter.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(commandReq)
+		reply, err := svc.Request(ctx, req.token, req.msg, req.timeout)
+		if err != nil {
+			return nil, err
+		}
+
+		return replyRes{msg: reply}, nil
+	}
+}
@@ -6,10 +6,12 @@ package api_test
 import (
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/opentracing/opentracing-go/mocktracer"
 
@@ -17,16 +19,40 @@ import (
 	adapter "github.com/mainflux/mainflux/http"
 	"github.com/mainflux/mainflux/http/api"
 	"github.com/mainflux/mainflux/http/mocks"
+	"github.com/mainflux/mainflux/things/uuid"
 	"github.com/stretchr/testify/assert"
 )
 
-func newService(cc mainflux.ThingsServiceClient) mainflux.MessagePublisher {
-	pub := mocks.NewPublisher()
-	return adapter.New(pub, cc)
+const (
+	commandsSubtopic = "commands"
+	maxTimeout       = 2 * time.Second
+	maxBody          = 1024
+)
+
+// respond replies to the commands sent to the commands subtopic only.
+func respond(cmd mainflux.RawMessage) (mainflux.RawMessage, bool) {
+	if cmd.Subtopic != commandsSubtopic {
+		return mainflux.RawMessage{}, false
+	}
+
+	reply := mainflux.RawMessage{
+		ContentType: "text/plain",
+		Payload:     append([]byte("ack "), cmd.Payload...),
+	}
+	return reply, true
 }
 
-func newHTTPServer(pub mainflux.MessagePublisher) *httptest.Server {
-	mux := api.MakeHandler(pub, mocktracer.New())
+func newService(cc mainflux.ThingsServiceClient) adapter.Service {
+	pubsub := mocks.NewPubSub(respond)
+	return adapter.New(pubsub, pubsub, cc, uuid.New())
+}
+
+func newHTTPServer(svc adapter.Service) *httptest.Server {
+	return newHTTPServerWithKey(svc, api.HeaderKey("Authorization"))
+}
+
+func newHTTPServerWithKey(svc adapter.Service, key api.KeyExtractor) *httptest.Server {
+	mux := api.MakeHandler(svc, mocktracer.New(), key, maxTimeout, maxBody)
 	return httptest.NewServer(mux)
 }
 
@@ -59,6 +85,8 @@ func TestPublish(t *testing.T) {
 	token := "auth_token"
 	invalidToken := "invalid_token"
 	msg := `[{"n":"current","t":-1,"v":1.6}]`
+	// SenML CBOR encoding of the msg.
+	cborMsg := "\x81\xa3\x00\x67current\x06\x20\x02\xfb\x3f\xf9\x99\x99\x99\x99\x99\x9a"
 	thingsClient := mocks.NewThingsClient(map[string]string{token: chanID})
 	pub := newService(thingsClient)
 	ts := newHTTPServer(pub)
@@ -78,6 +106,20 @@ func TestPublish(t *testing.T) {
 			auth:        token,
 			status:      http.StatusAccepted,
 		},
+		"publish SenML CBOR message": {
+			chanID:      chanID,
+			msg:         cborMsg,
+			contentType: "application/senml+cbor",
+			auth:        token,
+			status:      http.StatusAccepted,
+		},
+		"publish message with content type parameters": {
+			chanID:      chanID,
+			msg:         msg,
+			contentType: "application/senml+json; charset=utf-8",
+			auth:        token,
+			status:      http.StatusAccepted,
+		},
 		"publish message without authorization token": {
 			chanID:      chanID,
 			msg:         msg,
@@ -106,6 +148,13 @@ func TestPublish(t *testing.T) {
 			auth:        token,
 			status:      http.StatusBadRequest,
 		},
+		"publish message exceeding body limit": {
+			chanID:      chanID,
+			msg:         strings.Repeat("a", maxBody+1),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusRequestEntityTooLarge,
+		},
 		"publish message unable to authorize": {
 			chanID:      chanID,
 			msg:         msg,
@@ -129,3 +178,188 @@ func TestPublish(t *testing.T) {
 		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", desc, tc.status, res.StatusCode))
 	}
 }
+
+func TestPublishAuthSchemes(t *testing.T) {
+	chanID := "1"
+	token := "auth_token"
+	msg := `[{"n":"current","t":-1,"v":1.6}]`
+	thingsClient := mocks.NewThingsClient(map[string]string{token: chanID})
+	pub := newService(thingsClient)
+	key, err := api.ParseSchemes("basic,header,query", "X-Thing-Key", "key")
+	assert.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	ts := newHTTPServerWithKey(pub, key)
+	defer ts.Close()
+
+	cases := map[string]struct {
+		url    string
+		header map[string]string
+		basic  []string
+		status int
+	}{
+		"publish message with key in basic auth password": {
+			url:    fmt.Sprintf("%s/channels/%s/messages", ts.URL, chanID),
+			basic:  []string{"thing", token},
+			status: http.StatusAccepted,
+		},
+		"publish message with key in basic auth user name": {
+			url:    fmt.Sprintf("%s/channels/%s/messages", ts.URL, chanID),
+			basic:  []string{token, ""},
+			status: http.StatusAccepted,
+		},
+		"publish message with key in custom header": {
+			url:    fmt.Sprintf("%s/channels/%s/messages", ts.URL, chanID),
+			header: map[string]string{"X-Thing-Key": token},
+			status: http.StatusAccepted,
+		},
+		"publish message with key in query parameter": {
+			url:    fmt.Sprintf("%s/channels/%s/messages?key=%s", ts.URL, chanID, token),
+			status: http.StatusAccepted,
+		},
+		"publish message with key in query parameter to subtopic": {
+			url:    fmt.Sprintf("%s/channels/%s/messages/temperature?key=%s", ts.URL, chanID, token),
+			status: http.StatusAccepted,
+		},
+		"publish message with key in unused header": {
+			url:    fmt.Sprintf("%s/channels/%s/messages", ts.URL, chanID),
+			header: map[string]string{"Authorization": token},
+			status: http.StatusForbidden,
+		},
+		"publish message with invalid key in basic auth": {
+			url:    fmt.Sprintf("%s/channels/%s/messages", ts.URL, chanID),
+			basic:  []string{"thing", "invalid_token"},
+			status: http.StatusForbidden,
+		},
+		"publish message without key": {
+			url:    fmt.Sprintf("%s/channels/%s/messages", ts.URL, chanID),
+			status: http.StatusForbidden,
+		},
+	}
+
+	for desc, tc := range cases {
+		req, err := http.NewRequest(http.MethodPost, tc.url, strings.NewReader(msg))
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", desc, err))
+		for k, v := range tc.header {
+			req.Header.Set(k, v)
+		}
+		if tc.basic != nil {
+			req.SetBasicAuth(tc.basic[0], tc.basic[1])
+		}
+		res, err := ts.Client().Do(req)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestParseSchemes(t *testing.T) {
+	cases := map[string]struct {
+		schemes string
+		err     bool
+	}{
+		"parse default scheme": {schemes: "header"},
+		"parse all schemes":    {schemes: "basic, header, query"},
+		"parse unknown scheme": {schemes: "header,cookie", err: true},
+		"parse empty scheme":   {schemes: "", err: true},
+	}
+
+	for desc, tc := range cases {
+		_, err := api.ParseSchemes(tc.schemes, "Authorization", "key")
+		assert.Equal(t, tc.err, err != nil, fmt.Sprintf("%s: expected error %t got %s", desc, tc.err, err))
+	}
+}
+
+func TestRequest(t *testing.T) {
+	chanID := "1"
+	token := "auth_token"
+	cmd := "reboot"
+	thingsClient := mocks.NewThingsClient(map[string]string{token: chanID})
+	svc := newService(thingsClient)
+	ts := newHTTPServer(svc)
+	defer ts.Close()
+
+	cases := []struct {
+		desc   string
+		query  string
+		auth   string
+		status int
+		reply  string
+	}{
+		{
+			desc:   "send command",
+			query:  fmt.Sprintf("subtopic=%s", commandsSubtopic),
+			auth:   token,
+			status: http.StatusOK,
+			reply:  "ack reboot",
+		},
+		{
+			desc:   "send command with timeout",
+			query:  fmt.Sprintf("subtopic=%s&timeout=1", commandsSubtopic),
+			auth:   token,
+			status: http.StatusOK,
+			reply:  "ack reboot",
+		},
+		{
+			desc:   "send command without reply",
+			query:  "subtopic=silent&timeout=1",
+			auth:   token,
+			status: http.StatusGatewayTimeout,
+			reply:  "",
+		},
+		{
+			desc:   "send command with timeout exceeding max timeout",
+			query:  fmt.Sprintf("subtopic=%s&timeout=3", commandsSubtopic),
+			auth:   token,
+			status: http.StatusBadRequest,
+			reply:  "",
+		},
+		{
+			desc:   "send command with invalid timeout",
+			query:  fmt.Sprintf("subtopic=%s&timeout=0", commandsSubtopic),
+			auth:   token,
+			status: http.StatusBadRequest,
+			reply:  "",
+		},
+		{
+			desc:   "send command with malformed subtopic",
+			query:  "subtopic=commands.*a",
+			auth:   token,
+			status: http.StatusBadRequest,
+			reply:  "",
+		},
+		{
+			desc:   "send command without authorization token",
+			query:  fmt.Sprintf("subtopic=%s", commandsSubtopic),
+			auth:   "",
+			status: http.StatusForbidden,
+			reply:  "",
+		},
+		{
+			desc:   "send command with invalid authorization token",
+			query:  fmt.Sprintf("subtopic=%s", commandsSubtopic),
+			auth:   "invalid_token",
+			status: http.StatusForbidden,
+			reply:  "",
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/channels/%s/messages/sync?%s", ts.URL, chanID, tc.query),
+			contentType: "text/plain",
+			token:       tc.auth,
+			body:        strings.NewReader(cmd),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		if tc.status != http.StatusOK {
+			continue
+		}
+
+		body, err := ioutil.ReadAll(res.Body)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.reply, string(body), fmt.Sprintf("%s: expected reply %s got %s", tc.desc, tc.reply, body))
+		assert.NotEmpty(t, res.Header.Get("X-Correlation-ID"), fmt.Sprintf("%s: expected correlation ID", tc.desc))
+	}
+}
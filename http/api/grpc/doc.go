@@ -0,0 +1,6 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package grpc contains implementation of HTTP adapter gRPC API, which
+// gateways use to stream the messages instead of posting them one by one.
+package grpc
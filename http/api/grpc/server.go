@@ -0,0 +1,130 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package grpc
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/mainflux/mainflux"
+	adapter "github.com/mainflux/mainflux/http"
+	"github.com/mainflux/mainflux/payload"
+	"github.com/mainflux/mainflux/things"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	protocol = "grpc"
+	// authKey is the metadata key carrying the thing key.
+	authKey = "authorization"
+)
+
+var (
+	errMalformedChannel  = errors.New("malformed channel")
+	errMalformedSubtopic = errors.New("malformed subtopic")
+)
+
+var _ mainflux.PublisherServiceServer = (*grpcServer)(nil)
+
+type grpcServer struct {
+	svc adapter.Service
+}
+
+// NewServer returns new PublisherServiceServer instance. The thing key is
+// passed once per stream, using the authorization metadata of the call.
+func NewServer(svc adapter.Service) mainflux.PublisherServiceServer {
+	return &grpcServer{svc: svc}
+}
+
+func (gs *grpcServer) Publish(stream mainflux.PublisherService_PublishServer) error {
+	ctx := stream.Context()
+	recv := func() (mainflux.RawMessage, error) {
+		msg, err := stream.Recv()
+		if err != nil {
+			return mainflux.RawMessage{}, err
+		}
+		return decodeMessage(msg)
+	}
+
+	n, err := gs.svc.PublishStream(ctx, token(ctx), recv)
+	if err != nil {
+		return encodeError(err)
+	}
+
+	return stream.SendAndClose(&mainflux.PublishRes{Accepted: n})
+}
+
+func token(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	if vals := md.Get(authKey); len(vals) > 0 {
+		return vals[0]
+	}
+	return ""
+}
+
+// decodeMessage keeps the fields set by the gateway only, since the publisher
+// and the sequence number are set by the adapter.
+func decodeMessage(msg *mainflux.RawMessage) (mainflux.RawMessage, error) {
+	if msg.GetChannel() == "" {
+		return mainflux.RawMessage{}, errMalformedChannel
+	}
+
+	subtopic, err := parseSubtopic(msg.GetSubtopic())
+	if err != nil {
+		return mainflux.RawMessage{}, err
+	}
+
+	return mainflux.RawMessage{
+		Channel:       msg.GetChannel(),
+		Subtopic:      subtopic,
+		ContentType:   msg.GetContentType(),
+		Payload:       msg.GetPayload(),
+		Metadata:      msg.GetMetadata(),
+		CorrelationID: msg.GetCorrelationID(),
+		Protocol:      protocol,
+	}, nil
+}
+
+func parseSubtopic(subtopic string) (string, error) {
+	if subtopic == "" {
+		return subtopic, nil
+	}
+
+	subtopic = strings.Replace(subtopic, "/", ".", -1)
+	for _, elem := range strings.Split(subtopic, ".") {
+		if elem == "" || strings.ContainsAny(elem, "*>") {
+			return "", errMalformedSubtopic
+		}
+	}
+
+	return subtopic, nil
+}
+
+func encodeError(err error) error {
+	switch err {
+	case errMalformedChannel, errMalformedSubtopic:
+		return status.Error(codes.InvalidArgument, err.Error())
+	case things.ErrUnauthorizedAccess:
+		return status.Error(codes.Unauthenticated, "missing or invalid credentials provided")
+	case payload.ErrTooLarge:
+		return status.Error(codes.ResourceExhausted, err.Error())
+	default:
+		if e, ok := status.FromError(err); ok {
+			switch e.Code() {
+			case codes.PermissionDenied, codes.Canceled, codes.DeadlineExceeded:
+				return err
+			default:
+				return status.Error(codes.Unavailable, e.Message())
+			}
+		}
+		return status.Error(codes.Internal, "internal server error")
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package grpc_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/http/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const chanID = "1"
+
+func TestPublish(t *testing.T) {
+	addr := fmt.Sprintf("localhost:%d", port)
+	conn, err := grpc.Dial(addr, grpc.WithInsecure())
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	defer conn.Close()
+	cli := mainflux.NewPublisherServiceClient(conn)
+
+	msg := mainflux.RawMessage{
+		Channel:     chanID,
+		Subtopic:    "sensors/temperature",
+		ContentType: "application/senml+json",
+		Payload:     []byte(`[{"n":"temperature","v":21}]`),
+	}
+
+	cases := []struct {
+		desc     string
+		key      string
+		msgs     []mainflux.RawMessage
+		accepted uint64
+		code     codes.Code
+	}{
+		{
+			desc:     "publish stream of messages",
+			key:      token,
+			msgs:     []mainflux.RawMessage{msg, msg, msg},
+			accepted: 3,
+			code:     codes.OK,
+		},
+		{
+			desc:     "publish empty stream",
+			key:      token,
+			msgs:     []mainflux.RawMessage{},
+			accepted: 0,
+			code:     codes.OK,
+		},
+		{
+			desc: "publish stream without key",
+			key:  "",
+			msgs: []mainflux.RawMessage{msg},
+			code: codes.Unauthenticated,
+		},
+		{
+			desc: "publish stream with invalid key",
+			key:  "invalid",
+			msgs: []mainflux.RawMessage{msg},
+			code: codes.PermissionDenied,
+		},
+		{
+			desc: "publish stream when things service is unavailable",
+			key:  mocks.ServiceErrToken,
+			msgs: []mainflux.RawMessage{msg},
+			code: codes.Unavailable,
+		},
+		{
+			desc: "publish stream with message without channel",
+			key:  token,
+			msgs: []mainflux.RawMessage{msg, {Payload: msg.Payload}},
+			code: codes.InvalidArgument,
+		},
+		{
+			desc: "publish stream with message with wildcard subtopic",
+			key:  token,
+			msgs: []mainflux.RawMessage{{Channel: chanID, Subtopic: "sensors.*", Payload: msg.Payload}},
+			code: codes.InvalidArgument,
+		},
+		{
+			desc: "publish stream with message with empty subtopic element",
+			key:  token,
+			msgs: []mainflux.RawMessage{{Channel: chanID, Subtopic: "sensors//temperature", Payload: msg.Payload}},
+			code: codes.InvalidArgument,
+		},
+	}
+
+	for _, tc := range cases {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		if tc.key != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, "authorization", tc.key)
+		}
+
+		stream, err := cli.Publish(ctx)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		for i := range tc.msgs {
+			// The server may close the stream before all the messages are
+			// sent, the error is then returned by CloseAndRecv.
+			if err := stream.Send(&tc.msgs[i]); err != nil {
+				break
+			}
+		}
+
+		res, err := stream.CloseAndRecv()
+		cancel()
+		e, ok := status.FromError(err)
+		assert.True(t, ok, fmt.Sprintf("%s: gRPC status not received", tc.desc))
+		assert.Equal(t, tc.code, e.Code(), fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.code, e.Code()))
+		assert.Equal(t, tc.accepted, res.GetAccepted(), fmt.Sprintf("%s: expected %d accepted messages got %d", tc.desc, tc.accepted, res.GetAccepted()))
+	}
+}
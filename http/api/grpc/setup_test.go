@@ -0,0 +1,41 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package grpc_test
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"testing"
+
+	"github.com/mainflux/mainflux"
+	adapter "github.com/mainflux/mainflux/http"
+	grpcapi "github.com/mainflux/mainflux/http/api/grpc"
+	"github.com/mainflux/mainflux/http/mocks"
+	"github.com/mainflux/mainflux/things/uuid"
+	"google.golang.org/grpc"
+)
+
+const (
+	port    = 8082
+	thingID = "1"
+	token   = "token"
+)
+
+func TestMain(m *testing.M) {
+	startServer()
+	code := m.Run()
+	os.Exit(code)
+}
+
+func startServer() {
+	cc := mocks.NewThingsClient(map[string]string{token: thingID})
+	pubsub := mocks.NewPubSub(nil)
+	svc := adapter.New(pubsub, pubsub, cc, uuid.New())
+
+	listener, _ := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	server := grpc.NewServer()
+	mainflux.RegisterPublisherServiceServer(server, grpcapi.NewServer(svc))
+	go server.Serve(listener)
+}
@@ -11,18 +11,19 @@ import (
 	"time"
 
 	"github.com/mainflux/mainflux"
+	adapter "github.com/mainflux/mainflux/http"
 	log "github.com/mainflux/mainflux/logger"
 )
 
-var _ mainflux.MessagePublisher = (*loggingMiddleware)(nil)
+var _ adapter.Service = (*loggingMiddleware)(nil)
 
 type loggingMiddleware struct {
 	logger log.Logger
-	svc    mainflux.MessagePublisher
+	svc    adapter.Service
 }
 
 // LoggingMiddleware adds logging facilities to the adapter.
-func LoggingMiddleware(svc mainflux.MessagePublisher, logger log.Logger) mainflux.MessagePublisher {
+func LoggingMiddleware(svc adapter.Service, logger log.Logger) adapter.Service {
 	return &loggingMiddleware{logger, svc}
 }
 
@@ -42,3 +43,33 @@ func (lm *loggingMiddleware) Publish(ctx context.Context, token string, msg main
 
 	return lm.svc.Publish(ctx, token, msg)
 }
+
+func (lm *loggingMiddleware) Request(ctx context.Context, token string, msg mainflux.RawMessage, timeout time.Duration) (reply mainflux.RawMessage, err error) {
+	defer func(begin time.Time) {
+		destChannel := msg.Channel
+		if msg.Subtopic != "" {
+			destChannel = fmt.Sprintf("%s.%s", destChannel, msg.Subtopic)
+		}
+		message := fmt.Sprintf("Method request to channel %s took %s to complete", destChannel, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Request(ctx, token, msg, timeout)
+}
+
+func (lm *loggingMiddleware) PublishStream(ctx context.Context, token string, recv func() (mainflux.RawMessage, error)) (published uint64, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method publish_stream published %d messages and took %s to complete", published, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.PublishStream(ctx, token, recv)
+}
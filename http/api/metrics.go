@@ -11,18 +11,19 @@ import (
 
 	"github.com/go-kit/kit/metrics"
 	"github.com/mainflux/mainflux"
+	adapter "github.com/mainflux/mainflux/http"
 )
 
-var _ mainflux.MessagePublisher = (*metricsMiddleware)(nil)
+var _ adapter.Service = (*metricsMiddleware)(nil)
 
 type metricsMiddleware struct {
 	counter metrics.Counter
 	latency metrics.Histogram
-	svc     mainflux.MessagePublisher
+	svc     adapter.Service
 }
 
 // MetricsMiddleware instruments adapter by tracking request count and latency.
-func MetricsMiddleware(svc mainflux.MessagePublisher, counter metrics.Counter, latency metrics.Histogram) mainflux.MessagePublisher {
+func MetricsMiddleware(svc adapter.Service, counter metrics.Counter, latency metrics.Histogram) adapter.Service {
 	return &metricsMiddleware{
 		counter: counter,
 		latency: latency,
@@ -38,3 +39,21 @@ func (mm *metricsMiddleware) Publish(ctx context.Context, token string, msg main
 
 	return mm.svc.Publish(ctx, token, msg)
 }
+
+func (mm *metricsMiddleware) Request(ctx context.Context, token string, msg mainflux.RawMessage, timeout time.Duration) (mainflux.RawMessage, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "request").Add(1)
+		mm.latency.With("method", "request").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.Request(ctx, token, msg, timeout)
+}
+
+func (mm *metricsMiddleware) PublishStream(ctx context.Context, token string, recv func() (mainflux.RawMessage, error)) (uint64, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "publish_stream").Add(1)
+		mm.latency.With("method", "publish_stream").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.PublishStream(ctx, token, recv)
+}
@@ -4,6 +4,8 @@
 package api
 
 import (
+	"time"
+
 	"github.com/mainflux/mainflux"
 )
 
@@ -11,3 +13,9 @@ type publishReq struct {
 	msg   mainflux.RawMessage
 	token string
 }
+
+type commandReq struct {
+	msg     mainflux.RawMessage
+	token   string
+	timeout time.Duration
+}
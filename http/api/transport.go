@@ -8,15 +8,20 @@ import (
 	"errors"
 	"io"
 	"io/ioutil"
+	"mime"
 	"net/http"
 	"net/url"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	kitot "github.com/go-kit/kit/tracing/opentracing"
 	kithttp "github.com/go-kit/kit/transport/http"
 	"github.com/go-zoo/bone"
 	"github.com/mainflux/mainflux"
+	adapter "github.com/mainflux/mainflux/http"
+	"github.com/mainflux/mainflux/payload"
 	"github.com/mainflux/mainflux/things"
 	opentracing "github.com/opentracing/opentracing-go"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -24,37 +29,58 @@ import (
 	"google.golang.org/grpc/status"
 )
 
-const protocol = "http"
+const (
+	protocol = "http"
+
+	// correlationHeader carries the correlation ID of the reply to the
+	// command, and of the reply returned by the command request.
+	correlationHeader = "X-Correlation-ID"
+)
 
 var (
 	errMalformedData     = errors.New("malformed request data")
 	errMalformedSubtopic = errors.New("malformed subtopic")
+	errInvalidTimeout    = errors.New("invalid request timeout")
 )
 
 var channelPartRegExp = regexp.MustCompile(`^/channels/([\w\-]+)/messages(/[^?]*)?(\?.*)?$`)
 
-// MakeHandler returns a HTTP handler for API endpoints.
-func MakeHandler(svc mainflux.MessagePublisher, tracer opentracing.Tracer) http.Handler {
+// MakeHandler returns a HTTP handler for API endpoints. The thing key is
+// read from the request by the key extractor, and the command requests wait
+// for the reply at most the max timeout. Request bodies larger than maxBody
+// bytes are rejected, unless it's zero.
+func MakeHandler(svc adapter.Service, tracer opentracing.Tracer, key KeyExtractor, maxTimeout time.Duration, maxBody int64) http.Handler {
 	opts := []kithttp.ServerOption{
 		kithttp.ServerErrorEncoder(encodeError),
 	}
 
 	r := bone.New()
+	// Command route precedes the subtopic route, which would match it too.
+	r.Post("/channels/:id/messages/sync", kithttp.NewServer(
+		kitot.TraceServer(tracer, "request")(sendCommandEndpoint(svc)),
+		decodeCommand(key, maxTimeout, maxBody),
+		encodeReply,
+		opts...,
+	))
+
 	r.Post("/channels/:id/messages", kithttp.NewServer(
 		kitot.TraceServer(tracer, "publish")(sendMessageEndpoint(svc)),
-		decodeRequest,
+		decodeRequest(key, maxBody),
 		encodeResponse,
 		opts...,
 	))
 
 	r.Post("/channels/:id/messages/*", kithttp.NewServer(
 		kitot.TraceServer(tracer, "publish")(sendMessageEndpoint(svc)),
-		decodeRequest,
+		decodeRequest(key, maxBody),
 		encodeResponse,
 		opts...,
 	))
 
 	r.GetFunc("/version", mainflux.Version("http"))
+	r.GetFunc(mainflux.DiscoveryPath, mainflux.Discovery("http", mainflux.Capabilities{
+		ContentTypes: []string{mainflux.SenMLJSON, mainflux.SenMLCBOR},
+	}))
 	r.Handle("/metrics", promhttp.Handler())
 
 	return r
@@ -89,48 +115,118 @@ func parseSubtopic(subtopic string) (string, error) {
 	return subtopic, nil
 }
 
-func decodeRequest(ctx context.Context, r *http.Request) (interface{}, error) {
-	channelParts := channelPartRegExp.FindStringSubmatch(r.RequestURI)
-	if len(channelParts) < 2 {
-		return nil, errMalformedData
-	}
+func decodeRequest(key KeyExtractor, maxBody int64) kithttp.DecodeRequestFunc {
+	return func(_ context.Context, r *http.Request) (interface{}, error) {
+		channelParts := channelPartRegExp.FindStringSubmatch(r.RequestURI)
+		if len(channelParts) < 2 {
+			return nil, errMalformedData
+		}
 
-	chanID := bone.GetValue(r, "id")
-	subtopic, err := parseSubtopic(channelParts[2])
-	if err != nil {
-		return nil, err
+		chanID := bone.GetValue(r, "id")
+		subtopic, err := parseSubtopic(channelParts[2])
+		if err != nil {
+			return nil, err
+		}
+
+		payload, err := decodePayload(r.Body, maxBody)
+		if err != nil {
+			return nil, err
+		}
+
+		msg := mainflux.RawMessage{
+			Protocol:      protocol,
+			ContentType:   contentType(r),
+			Channel:       chanID,
+			Subtopic:      subtopic,
+			Payload:       payload,
+			CorrelationID: r.Header.Get(correlationHeader),
+		}
+
+		req := publishReq{
+			msg:   msg,
+			token: key(r),
+		}
+
+		return req, nil
 	}
+}
 
-	payload, err := decodePayload(r.Body)
-	if err != nil {
-		return nil, err
+func decodeCommand(key KeyExtractor, maxTimeout time.Duration, maxBody int64) kithttp.DecodeRequestFunc {
+	return func(_ context.Context, r *http.Request) (interface{}, error) {
+		chanID := bone.GetValue(r, "id")
+		if chanID == "" {
+			return nil, errMalformedData
+		}
+
+		subtopic, err := parseSubtopic(r.URL.Query().Get("subtopic"))
+		if err != nil {
+			return nil, err
+		}
+
+		timeout := maxTimeout
+		if t := r.URL.Query().Get("timeout"); t != "" {
+			secs, err := strconv.ParseUint(t, 10, 32)
+			if err != nil || secs == 0 || time.Duration(secs)*time.Second > maxTimeout {
+				return nil, errInvalidTimeout
+			}
+			timeout = time.Duration(secs) * time.Second
+		}
+
+		payload, err := decodePayload(r.Body, maxBody)
+		if err != nil {
+			return nil, err
+		}
+
+		msg := mainflux.RawMessage{
+			Protocol:    protocol,
+			ContentType: contentType(r),
+			Channel:     chanID,
+			Subtopic:    subtopic,
+			Payload:     payload,
+		}
+
+		req := commandReq{
+			msg:     msg,
+			token:   key(r),
+			timeout: timeout,
+		}
+
+		return req, nil
 	}
+}
 
-	ct := r.Header.Get("Content-Type")
-	msg := mainflux.RawMessage{
-		Protocol:    protocol,
-		ContentType: ct,
-		Channel:     chanID,
-		Subtopic:    subtopic,
-		Payload:     payload,
+// decodePayload reads the request body, which is limited to maxBody bytes
+// unless it's zero, so that the oversized bodies aren't read into memory.
+func decodePayload(body io.ReadCloser, maxBody int64) ([]byte, error) {
+	defer body.Close()
+
+	r := io.Reader(body)
+	if maxBody > 0 {
+		r = http.MaxBytesReader(nil, body, maxBody)
 	}
 
-	req := publishReq{
-		msg:   msg,
-		token: r.Header.Get("Authorization"),
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		if maxBody > 0 && int64(len(data)) == maxBody {
+			return nil, payload.ErrTooLarge
+		}
+		return nil, errMalformedData
 	}
 
-	return req, nil
+	return data, nil
 }
 
-func decodePayload(body io.ReadCloser) ([]byte, error) {
-	payload, err := ioutil.ReadAll(body)
+// contentType returns the media type of the request, without the parameters
+// such as charset, so that the normalizer recognizes both the SenML JSON and
+// the SenML CBOR payloads.
+func contentType(r *http.Request) string {
+	ct := r.Header.Get("Content-Type")
+	mt, _, err := mime.ParseMediaType(ct)
 	if err != nil {
-		return nil, errMalformedData
+		return ct
 	}
-	defer body.Close()
 
-	return payload, nil
+	return mt
 }
 
 func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
@@ -138,12 +234,28 @@ func encodeResponse(_ context.Context, w http.ResponseWriter, response interface
 	return nil
 }
 
+func encodeReply(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	res := response.(replyRes)
+	if res.msg.ContentType != "" {
+		w.Header().Set("Content-Type", res.msg.ContentType)
+	}
+	w.Header().Set(correlationHeader, res.msg.CorrelationID)
+	w.WriteHeader(http.StatusOK)
+
+	_, err := w.Write(res.msg.Payload)
+	return err
+}
+
 func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 	switch err {
-	case errMalformedData, errMalformedSubtopic:
+	case errMalformedData, errMalformedSubtopic, errInvalidTimeout:
 		w.WriteHeader(http.StatusBadRequest)
 	case things.ErrUnauthorizedAccess:
 		w.WriteHeader(http.StatusForbidden)
+	case adapter.ErrRequestTimeout:
+		w.WriteHeader(http.StatusGatewayTimeout)
+	case payload.ErrTooLarge:
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
 	default:
 		if e, ok := status.FromError(err); ok {
 			switch e.Code() {
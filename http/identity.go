@@ -0,0 +1,22 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package http
+
+import "context"
+
+type certThingKey struct{}
+
+// WithCertThing returns the context carrying the ID of the thing
+// authenticated by the client certificate, in which case the thing key isn't
+// required.
+func WithCertThing(ctx context.Context, thingID string) context.Context {
+	return context.WithValue(ctx, certThingKey{}, thingID)
+}
+
+// CertThing returns the ID of the thing authenticated by the client
+// certificate, or an empty string if the thing isn't authenticated that way.
+func CertThing(ctx context.Context) string {
+	thingID, _ := ctx.Value(certThingKey{}).(string)
+	return thingID
+}
@@ -0,0 +1,87 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/mainflux/mainflux"
+	adapter "github.com/mainflux/mainflux/http"
+)
+
+// Responder returns the reply of the device to the command, if the device
+// replies to it.
+type Responder func(cmd mainflux.RawMessage) (mainflux.RawMessage, bool)
+
+var (
+	_ mainflux.MessagePublisher = (*PubSub)(nil)
+	_ adapter.Replies           = (*PubSub)(nil)
+)
+
+// PubSub is the mock broker delivering the published messages to the replies
+// subscriptions, which replies to the commands using the responder.
+type PubSub struct {
+	mu        sync.Mutex
+	subs      map[string]chan mainflux.RawMessage
+	responder Responder
+}
+
+// NewPubSub returns mock broker replying to the commands using the provided
+// responder, or not replying at all if it's nil.
+func NewPubSub(responder Responder) *PubSub {
+	return &PubSub{
+		subs:      make(map[string]chan mainflux.RawMessage),
+		responder: responder,
+	}
+}
+
+// Publish delivers the message to the subscription of its correlation ID.
+func (ps *PubSub) Publish(_ context.Context, _ string, msg mainflux.RawMessage) error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	ps.deliver(msg)
+	if ps.responder == nil || msg.CorrelationID == "" {
+		return nil
+	}
+
+	if reply, ok := ps.responder(msg); ok {
+		reply.Channel = msg.Channel
+		reply.Subtopic = adapter.ResponseSubtopic(msg.Subtopic)
+		reply.CorrelationID = msg.CorrelationID
+		ps.deliver(reply)
+	}
+
+	return nil
+}
+
+// Subscribe subscribes to the messages with the given correlation ID.
+func (ps *PubSub) Subscribe(chanID, subtopic, correlationID string) (<-chan mainflux.RawMessage, func(), error) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	key := fmt.Sprintf("%s.%s.%s", chanID, subtopic, correlationID)
+	replies := make(chan mainflux.RawMessage, 1)
+	ps.subs[key] = replies
+
+	cancel := func() {
+		ps.mu.Lock()
+		defer ps.mu.Unlock()
+		delete(ps.subs, key)
+	}
+
+	return replies, cancel, nil
+}
+
+func (ps *PubSub) deliver(msg mainflux.RawMessage) {
+	key := fmt.Sprintf("%s.%s.%s", msg.Channel, msg.Subtopic, msg.CorrelationID)
+	if replies, ok := ps.subs[key]; ok {
+		select {
+		case replies <- msg:
+		default:
+		}
+	}
+}
@@ -50,10 +50,56 @@ func (tc thingsClient) CanAccess(ctx context.Context, req *mainflux.AccessReq, o
 	return &mainflux.ThingID{Value: id}, nil
 }
 
-func (tc thingsClient) CanAccessByID(context.Context, *mainflux.AccessByIDReq, ...grpc.CallOption) (*empty.Empty, error) {
-	panic("not implemented")
+func (tc thingsClient) CanAccessByID(ctx context.Context, req *mainflux.AccessByIDReq, opts ...grpc.CallOption) (*empty.Empty, error) {
+	for _, id := range tc.things {
+		if id == req.GetThingID() {
+			return &empty.Empty{}, nil
+		}
+	}
+
+	return nil, status.Error(codes.PermissionDenied, "invalid credentials provided")
 }
 
 func (tc thingsClient) Identify(ctx context.Context, req *mainflux.Token, opts ...grpc.CallOption) (*mainflux.ThingID, error) {
 	panic("not implemented")
 }
+
+func (tc thingsClient) Owner(context.Context, *mainflux.ThingID, ...grpc.CallOption) (*mainflux.UserID, error) {
+	panic("not implemented")
+}
+
+func (tc thingsClient) Retention(context.Context, *mainflux.ChannelID, ...grpc.CallOption) (*mainflux.Retention, error) {
+	panic("not implemented")
+}
+
+func (tc thingsClient) Region(context.Context, *mainflux.ChannelID, ...grpc.CallOption) (*mainflux.Region, error) {
+	panic("not implemented")
+}
+
+func (tc thingsClient) Alias(context.Context, *mainflux.ChannelID, ...grpc.CallOption) (*mainflux.ChannelAlias, error) {
+	panic("not implemented")
+}
+
+func (tc thingsClient) ResolveAlias(context.Context, *mainflux.ChannelAlias, ...grpc.CallOption) (*mainflux.ChannelID, error) {
+	panic("not implemented")
+}
+
+func (tc thingsClient) Ordering(context.Context, *mainflux.ChannelID, ...grpc.CallOption) (*mainflux.Ordering, error) {
+	panic("not implemented")
+}
+
+func (tc thingsClient) Transformer(context.Context, *mainflux.ChannelID, ...grpc.CallOption) (*mainflux.Transformer, error) {
+	panic("not implemented")
+}
+
+func (tc thingsClient) PayloadSchema(context.Context, *mainflux.PayloadSchemaReq, ...grpc.CallOption) (*mainflux.PayloadSchema, error) {
+	panic("not implemented")
+}
+
+func (tc thingsClient) KeyDigest(context.Context, *mainflux.ThingID, ...grpc.CallOption) (*mainflux.KeyDigest, error) {
+	panic("not implemented")
+}
+
+func (tc thingsClient) Changes(context.Context, *mainflux.ChangesReq, ...grpc.CallOption) (mainflux.ThingsService_ChangesClient, error) {
+	panic("not implemented")
+}
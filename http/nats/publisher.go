@@ -10,7 +10,9 @@ import (
 
 	"github.com/gogo/protobuf/proto"
 	"github.com/mainflux/mainflux"
-	broker "github.com/nats-io/go-nats"
+	mfnats "github.com/mainflux/mainflux/nats"
+	"github.com/mainflux/mainflux/tracing"
+	broker "github.com/nats-io/nats.go"
 )
 
 const prefix = "channel"
@@ -18,15 +20,20 @@ const prefix = "channel"
 var _ mainflux.MessagePublisher = (*natsPublisher)(nil)
 
 type natsPublisher struct {
-	nc *broker.Conn
+	nc            *broker.Conn
+	subjectPrefix string
 }
 
-// NewMessagePublisher instantiates NATS message publisher.
-func NewMessagePublisher(nc *broker.Conn) mainflux.MessagePublisher {
-	return &natsPublisher{nc: nc}
+// NewMessagePublisher instantiates NATS message publisher. The subjects are
+// prefixed with the deployment subject prefix, unless it is empty.
+func NewMessagePublisher(nc *broker.Conn, subjectPrefix string) mainflux.MessagePublisher {
+	return &natsPublisher{
+		nc:            nc,
+		subjectPrefix: subjectPrefix,
+	}
 }
 
-func (pub *natsPublisher) Publish(_ context.Context, _ string, msg mainflux.RawMessage) error {
+func (pub *natsPublisher) Publish(ctx context.Context, _ string, msg mainflux.RawMessage) error {
 	data, err := proto.Marshal(&msg)
 	if err != nil {
 		return err
@@ -36,5 +43,5 @@ func (pub *natsPublisher) Publish(_ context.Context, _ string, msg mainflux.RawM
 	if msg.Subtopic != "" {
 		subject = fmt.Sprintf("%s.%s", subject, msg.Subtopic)
 	}
-	return pub.nc.Publish(subject, data)
+	return tracing.Publish(ctx, pub.nc, mfnats.Subject(pub.subjectPrefix, subject), data)
 }
@@ -0,0 +1,57 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package nats
+
+import (
+	"fmt"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/mainflux/mainflux"
+	adapter "github.com/mainflux/mainflux/http"
+	mfnats "github.com/mainflux/mainflux/nats"
+	broker "github.com/nats-io/nats.go"
+)
+
+var _ adapter.Replies = (*natsReplies)(nil)
+
+type natsReplies struct {
+	nc            *broker.Conn
+	subjectPrefix string
+}
+
+// NewReplies instantiates NATS replies subscriber. The subjects are prefixed
+// with the deployment subject prefix, unless it is empty.
+func NewReplies(nc *broker.Conn, subjectPrefix string) adapter.Replies {
+	return &natsReplies{
+		nc:            nc,
+		subjectPrefix: subjectPrefix,
+	}
+}
+
+func (nr *natsReplies) Subscribe(chanID, subtopic, correlationID string) (<-chan mainflux.RawMessage, func(), error) {
+	replies := make(chan mainflux.RawMessage, 1)
+
+	subject := fmt.Sprintf("%s.%s.%s", prefix, chanID, subtopic)
+	sub, err := nr.nc.Subscribe(mfnats.Subject(nr.subjectPrefix, subject), func(m *broker.Msg) {
+		var msg mainflux.RawMessage
+		if err := proto.Unmarshal(m.Data, &msg); err != nil {
+			return
+		}
+
+		// Replies to the other commands share the subject.
+		if msg.CorrelationID != correlationID {
+			return
+		}
+
+		select {
+		case replies <- msg:
+		default:
+		}
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return replies, func() { sub.Unsubscribe() }, nil
+}
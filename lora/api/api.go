@@ -15,6 +15,7 @@ import (
 func MakeHandler() http.Handler {
 	r := bone.New()
 	r.GetFunc("/version", mainflux.Version("lora-adapter"))
+	r.GetFunc(mainflux.DiscoveryPath, mainflux.Discovery("lora-adapter", mainflux.Capabilities{}))
 	r.Handle("/metrics", promhttp.Handler())
 
 	return r
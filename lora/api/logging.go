@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/logger"
 	"github.com/mainflux/mainflux/lora"
 )
@@ -117,3 +118,16 @@ func (lm loggingMiddleware) Publish(ctx context.Context, token string, m lora.Me
 
 	return lm.svc.Publish(ctx, token, m)
 }
+
+func (lm loggingMiddleware) Downlink(ctx context.Context, msg mainflux.RawMessage) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("downlink channel.%s.%s took %s to complete", msg.Channel, msg.Subtopic, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Downlink(ctx, msg)
+}
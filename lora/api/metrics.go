@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/go-kit/kit/metrics"
+	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/lora"
 )
 
@@ -90,3 +91,12 @@ func (mm *metricsMiddleware) Publish(ctx context.Context, token string, m lora.M
 
 	return mm.svc.Publish(ctx, token, m)
 }
+
+func (mm *metricsMiddleware) Downlink(ctx context.Context, msg mainflux.RawMessage) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "downlink").Add(1)
+		mm.latency.With("method", "downlink").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.Downlink(ctx, msg)
+}
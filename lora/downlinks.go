@@ -0,0 +1,10 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package lora
+
+// DownlinkPublisher publishes downlinks to the LoRa Server MQTT broker.
+type DownlinkPublisher interface {
+	// Publish enqueues the downlink for the device of the lora application.
+	Publish(string, string, Downlink) error
+}
@@ -42,3 +42,10 @@ type Message struct {
 	Data                string      `json:"data"`
 	Object              interface{} `json:"object"`
 }
+
+// Downlink lora downlink (www.loraserver.io/lora-app-server/integrate/sending-receiving/mqtt/)
+type Downlink struct {
+	Confirmed bool   `json:"confirmed"`
+	FPort     int    `json:"fPort"`
+	Data      string `json:"data"`
+}
@@ -0,0 +1,45 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/mainflux/mainflux/lora"
+)
+
+var _ lora.DownlinkPublisher = (*Downlinks)(nil)
+
+// Downlinks is an in-memory downlink publisher which records published
+// downlinks by their MQTT topic.
+type Downlinks struct {
+	mu        sync.Mutex
+	downlinks map[string][]lora.Downlink
+}
+
+// NewDownlinks returns downlink publisher mock.
+func NewDownlinks() *Downlinks {
+	return &Downlinks{
+		downlinks: make(map[string][]lora.Downlink),
+	}
+}
+
+// Publish records the published downlink.
+func (d *Downlinks) Publish(appID, devEUI string, dl lora.Downlink) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	topic := fmt.Sprintf("application/%s/device/%s/tx", appID, devEUI)
+	d.downlinks[topic] = append(d.downlinks[topic], dl)
+	return nil
+}
+
+// Downlinks returns the downlinks published to the topic.
+func (d *Downlinks) Downlinks(topic string) []lora.Downlink {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return append([]lora.Downlink{}, d.downlinks[topic]...)
+}
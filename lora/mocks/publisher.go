@@ -0,0 +1,41 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mainflux/mainflux"
+)
+
+// Publisher is an in-memory publisher which records published messages.
+type Publisher struct {
+	mu       sync.Mutex
+	messages []mainflux.RawMessage
+}
+
+var _ mainflux.MessagePublisher = (*Publisher)(nil)
+
+// NewPublisher returns publisher mock.
+func NewPublisher() *Publisher {
+	return &Publisher{}
+}
+
+// Publish records the published message.
+func (p *Publisher) Publish(_ context.Context, _ string, msg mainflux.RawMessage) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.messages = append(p.messages, msg)
+	return nil
+}
+
+// Messages returns the published messages, in the publishing order.
+func (p *Publisher) Messages() []mainflux.RawMessage {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]mainflux.RawMessage{}, p.messages...)
+}
@@ -0,0 +1,74 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/mainflux/mainflux/lora"
+)
+
+var errNotFound = errors.New("route map not found")
+
+var _ lora.RouteMapRepository = (*routeMapMock)(nil)
+
+type routeMapMock struct {
+	mu     sync.Mutex
+	toLoRa map[string]string
+	toMfx  map[string]string
+}
+
+// NewRouteMap returns in-memory route map mock.
+func NewRouteMap() lora.RouteMapRepository {
+	return &routeMapMock{
+		toLoRa: make(map[string]string),
+		toMfx:  make(map[string]string),
+	}
+}
+
+func (rm *routeMapMock) Save(mfxID, loraID string) error {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	rm.toLoRa[mfxID] = loraID
+	rm.toMfx[loraID] = mfxID
+	return nil
+}
+
+func (rm *routeMapMock) Get(loraID string) (string, error) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	id, ok := rm.toMfx[loraID]
+	if !ok {
+		return "", errNotFound
+	}
+	return id, nil
+}
+
+func (rm *routeMapMock) GetLoRa(mfxID string) (string, error) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	id, ok := rm.toLoRa[mfxID]
+	if !ok {
+		return "", errNotFound
+	}
+	return id, nil
+}
+
+func (rm *routeMapMock) Remove(mfxID string) error {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	loraID, ok := rm.toLoRa[mfxID]
+	if !ok {
+		return errNotFound
+	}
+
+	delete(rm.toLoRa, mfxID)
+	delete(rm.toMfx, loraID)
+	return nil
+}
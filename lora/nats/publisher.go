@@ -1,7 +1,8 @@
 // Copyright (c) Mainflux
 // SPDX-License-Identifier: Apache-2.0
 
-// Package nats contains NATS message publisher implementation.
+// Package nats contains NATS message publisher and downlink subscriber
+// implementations.
 package nats
 
 import (
@@ -10,18 +11,21 @@ import (
 
 	"github.com/gogo/protobuf/proto"
 	"github.com/mainflux/mainflux"
-	broker "github.com/nats-io/go-nats"
+	mfnats "github.com/mainflux/mainflux/nats"
+	broker "github.com/nats-io/nats.go"
 )
 
 var _ mainflux.MessagePublisher = (*natsPublisher)(nil)
 
 type natsPublisher struct {
-	nc *broker.Conn
+	nc            *broker.Conn
+	subjectPrefix string
 }
 
-// NewMessagePublisher instantiates NATS message publisher.
-func NewMessagePublisher(nc *broker.Conn) mainflux.MessagePublisher {
-	return &natsPublisher{nc}
+// NewMessagePublisher instantiates NATS message publisher. The subjects are
+// prefixed with the deployment subject prefix, unless it is empty.
+func NewMessagePublisher(nc *broker.Conn, subjectPrefix string) mainflux.MessagePublisher {
+	return &natsPublisher{nc, subjectPrefix}
 }
 
 func (pub *natsPublisher) Publish(_ context.Context, _ string, msg mainflux.RawMessage) error {
@@ -31,5 +35,5 @@ func (pub *natsPublisher) Publish(_ context.Context, _ string, msg mainflux.RawM
 	}
 
 	subject := fmt.Sprintf("channel.%s", msg.Channel)
-	return pub.nc.Publish(subject, data)
+	return pub.nc.Publish(mfnats.Subject(pub.subjectPrefix, subject), data)
 }
@@ -0,0 +1,49 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package nats
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/lora"
+	mfnats "github.com/mainflux/mainflux/nats"
+	broker "github.com/nats-io/nats.go"
+)
+
+const (
+	queue           = "lora"
+	downlinkSubject = "channel.*.downlink.>"
+)
+
+type subscriber struct {
+	svc    lora.Service
+	logger logger.Logger
+}
+
+// SubscribeDownlinks subscribes to the messages published on the downlink
+// subtopics of the channels, and forwards them to the LoRa Server. Adapter
+// instances share the subscription, so each downlink is forwarded once.
+func SubscribeDownlinks(nc *broker.Conn, subjectPrefix string, svc lora.Service, logger logger.Logger) error {
+	s := subscriber{
+		svc:    svc,
+		logger: logger,
+	}
+
+	_, err := nc.QueueSubscribe(mfnats.Subject(subjectPrefix, downlinkSubject), queue, s.handle)
+	return err
+}
+
+func (s subscriber) handle(m *broker.Msg) {
+	msg := mainflux.RawMessage{}
+	if err := proto.Unmarshal(m.Data, &msg); err != nil {
+		s.logger.Warn(fmt.Sprintf("Failed to unmarshal received message: %s", err))
+		return
+	}
+
+	s.svc.Downlink(context.Background(), msg)
+}
@@ -0,0 +1,38 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package paho
+
+import (
+	"encoding/json"
+	"fmt"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/mainflux/mainflux/lora"
+)
+
+const downlinkTopic = "application/%s/device/%s/tx"
+
+var _ lora.DownlinkPublisher = (*downlinkPublisher)(nil)
+
+type downlinkPublisher struct {
+	client mqtt.Client
+}
+
+// NewDownlinkPublisher returns new downlink publisher instance, which
+// publishes downlinks to the LoRa Server MQTT broker.
+func NewDownlinkPublisher(client mqtt.Client) lora.DownlinkPublisher {
+	return downlinkPublisher{client: client}
+}
+
+func (dp downlinkPublisher) Publish(appID, devEUI string, dl lora.Downlink) error {
+	payload, err := json.Marshal(dl)
+	if err != nil {
+		return err
+	}
+
+	token := dp.client.Publish(fmt.Sprintf(downlinkTopic, appID, devEUI), 0, false, payload)
+	token.Wait()
+
+	return token.Error()
+}
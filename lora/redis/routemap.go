@@ -18,12 +18,12 @@ const (
 var _ lora.RouteMapRepository = (*routerMap)(nil)
 
 type routerMap struct {
-	client *redis.Client
+	client redis.UniversalClient
 	prefix string
 }
 
 // NewRouteMapRepository returns redis thing cache implementation.
-func NewRouteMapRepository(client *redis.Client, prefix string) lora.RouteMapRepository {
+func NewRouteMapRepository(client redis.UniversalClient, prefix string) lora.RouteMapRepository {
 	return &routerMap{
 		client: client,
 		prefix: prefix,
@@ -53,6 +53,16 @@ func (mr *routerMap) Get(mfxID string) (string, error) {
 	return mval, nil
 }
 
+func (mr *routerMap) GetLoRa(mfxID string) (string, error) {
+	mKey := fmt.Sprintf("%s:%s:%s", mr.prefix, mfxMapPrefix, mfxID)
+	lval, err := mr.client.Get(mKey).Result()
+	if err != nil {
+		return "", err
+	}
+
+	return lval, nil
+}
+
 func (mr *routerMap) Remove(mfxID string) error {
 	mkey := fmt.Sprintf("%s:%s:%s", mr.prefix, mfxMapPrefix, mfxID)
 	lval, err := mr.client.Get(mkey).Result()
@@ -45,13 +45,13 @@ type EventStore interface {
 
 type eventStore struct {
 	svc      lora.Service
-	client   *redis.Client
+	client   redis.UniversalClient
 	consumer string
 	logger   logger.Logger
 }
 
 // NewEventStore returns new event store instance.
-func NewEventStore(svc lora.Service, client *redis.Client, consumer string, log logger.Logger) EventStore {
+func NewEventStore(svc lora.Service, client redis.UniversalClient, consumer string, log logger.Logger) EventStore {
 	return eventStore{
 		svc:      svc,
 		client:   client,
@@ -11,6 +11,9 @@ type RouteMapRepository interface {
 	// Channel returns mainflux channel for given lora application.
 	Get(string) (string, error)
 
+	// GetLoRa returns lora application or device for given mainflux entity.
+	GetLoRa(string) (string, error)
+
 	// Removes mapping from cache.
 	Remove(string) error
 }
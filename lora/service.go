@@ -5,7 +5,12 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/cisco/senml"
 	"github.com/mainflux/mainflux"
 )
 
@@ -13,6 +18,14 @@ const (
 	protocol      = "lora"
 	thingSuffix   = "thing"
 	channelSuffix = "channel"
+
+	octetStream   = "application/octet-stream"
+	downlinkTopic = "downlink"
+
+	// defFPort is the port of the downlinks, unless the subtopic specifies
+	// otherwise. Ports above 223 are reserved by LoRaWAN.
+	defFPort = 1
+	maxFPort = 223
 )
 
 var (
@@ -28,6 +41,16 @@ var (
 
 	// ErrNotFoundApp indicates a non-existent route map for an application ID.
 	ErrNotFoundApp = errors.New("route map not found for this application ID")
+
+	// ErrNotFoundThing indicates a non-existent route map for a thing ID.
+	ErrNotFoundThing = errors.New("route map not found for this thing ID")
+
+	// ErrNotFoundChannel indicates a non-existent route map for a channel ID.
+	ErrNotFoundChannel = errors.New("route map not found for this channel ID")
+
+	// ErrMalformedDownlink indicates malformed downlink subtopic (e.g.
+	// missing thing ID or invalid port).
+	ErrMalformedDownlink = errors.New("malformed downlink subtopic")
 )
 
 // Service specifies an API that must be fullfiled by the domain service
@@ -53,6 +76,10 @@ type Service interface {
 
 	// Publish forwards messages from the LoRa MQTT broker to Mainflux NATS broker
 	Publish(context.Context, string, Message) error
+
+	// Downlink forwards messages published on the downlink subtopic of the
+	// channel from Mainflux NATS broker to the LoRa MQTT broker
+	Downlink(context.Context, mainflux.RawMessage) error
 }
 
 var _ Service = (*adapterService)(nil)
@@ -61,14 +88,16 @@ type adapterService struct {
 	publisher  mainflux.MessagePublisher
 	thingsRM   RouteMapRepository
 	channelsRM RouteMapRepository
+	downlinks  DownlinkPublisher
 }
 
 // New instantiates the LoRa adapter implementation.
-func New(pub mainflux.MessagePublisher, thingsRM, channelsRM RouteMapRepository) Service {
+func New(pub mainflux.MessagePublisher, thingsRM, channelsRM RouteMapRepository, downlinks DownlinkPublisher) Service {
 	return &adapterService{
 		publisher:  pub,
 		thingsRM:   thingsRM,
 		channelsRM: channelsRM,
+		downlinks:  downlinks,
 	}
 }
 
@@ -86,28 +115,16 @@ func (as *adapterService) Publish(ctx context.Context, token string, m Message)
 		return ErrNotFoundApp
 	}
 
-	// Use the SenML message decoded on LoRa server application if
-	// field Object isn't empty. Otherwise, decode standard field Data.
-	var payload []byte
-	switch m.Object {
-	case nil:
-		payload, err = base64.StdEncoding.DecodeString(m.Data)
-		if err != nil {
-			return ErrMalformedMessage
-		}
-	default:
-		jo, err := json.Marshal(m.Object)
-		if err != nil {
-			return err
-		}
-		payload = []byte(jo)
+	payload, contentType, err := decode(m)
+	if err != nil {
+		return err
 	}
 
 	// Publish on Mainflux NATS broker
 	msg := mainflux.RawMessage{
 		Publisher:   thing,
 		Protocol:    protocol,
-		ContentType: "Content-Type",
+		ContentType: contentType,
 		Channel:     channel,
 		Payload:     payload,
 	}
@@ -115,6 +132,33 @@ func (as *adapterService) Publish(ctx context.Context, token string, m Message)
 	return as.publisher.Publish(ctx, token, msg)
 }
 
+// Downlink forwards messages from Mainflux NATS broker to Lora MQTT broker
+func (as *adapterService) Downlink(ctx context.Context, msg mainflux.RawMessage) error {
+	thing, fPort, err := parseDownlink(msg.Subtopic)
+	if err != nil {
+		return err
+	}
+
+	// Get route map of mainflux channel
+	appID, err := as.channelsRM.GetLoRa(msg.Channel)
+	if err != nil {
+		return ErrNotFoundChannel
+	}
+
+	// Get route map of mainflux thing
+	devEUI, err := as.thingsRM.GetLoRa(thing)
+	if err != nil {
+		return ErrNotFoundThing
+	}
+
+	dl := Downlink{
+		FPort: fPort,
+		Data:  base64.StdEncoding.EncodeToString(msg.Payload),
+	}
+
+	return as.downlinks.Publish(appID, devEUI, dl)
+}
+
 func (as *adapterService) CreateThing(mfxDevID string, loraDevEUI string) error {
 	return as.thingsRM.Save(mfxDevID, loraDevEUI)
 }
@@ -138,3 +182,91 @@ func (as *adapterService) UpdateChannel(mfxChanID string, loraAppID string) erro
 func (as *adapterService) RemoveChannel(mfxChanID string) error {
 	return as.channelsRM.Remove(mfxChanID)
 }
+
+// decode converts the payload of the LoRa message. The object decoded by the
+// application codec is published as SenML, either as is if the codec returns
+// SenML records, or with a record per object field. Otherwise, the frame
+// payload is published as is.
+func decode(m Message) ([]byte, string, error) {
+	switch obj := m.Object.(type) {
+	case nil:
+		payload, err := base64.StdEncoding.DecodeString(m.Data)
+		if err != nil {
+			return nil, "", ErrMalformedMessage
+		}
+		return payload, octetStream, nil
+	case []interface{}:
+		payload, err := json.Marshal(obj)
+		if err != nil {
+			return nil, "", err
+		}
+		return payload, mainflux.SenMLJSON, nil
+	case map[string]interface{}:
+		return toSenML(m, obj)
+	default:
+		return nil, "", ErrMalformedMessage
+	}
+}
+
+func toSenML(m Message, obj map[string]interface{}) ([]byte, string, error) {
+	var t float64
+	if len(m.RxInfo) > 0 {
+		if rt, err := time.Parse(time.RFC3339Nano, m.RxInfo[0].Time); err == nil {
+			t = float64(rt.UnixNano()) / 1e9
+		}
+	}
+
+	names := []string{}
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// Nested fields have no SenML representation, so they are skipped.
+	records := []senml.SenMLRecord{}
+	for _, name := range names {
+		rec := senml.SenMLRecord{Name: name, Time: t}
+		switch v := obj[name].(type) {
+		case float64:
+			rec.Value = &v
+		case bool:
+			rec.BoolValue = &v
+		case string:
+			rec.StringValue = v
+		default:
+			continue
+		}
+		records = append(records, rec)
+	}
+
+	if len(records) == 0 {
+		return nil, "", ErrMalformedMessage
+	}
+
+	payload, err := senml.Encode(senml.SenML{Records: records}, senml.JSON, senml.OutputOptions{})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return payload, mainflux.SenMLJSON, nil
+}
+
+// parseDownlink parses the downlink subtopic, formatted as
+// downlink.<thing_id>[.<port>].
+func parseDownlink(subtopic string) (string, int, error) {
+	parts := strings.Split(subtopic, ".")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] != downlinkTopic || parts[1] == "" {
+		return "", 0, ErrMalformedDownlink
+	}
+
+	if len(parts) == 2 {
+		return parts[1], defFPort, nil
+	}
+
+	port, err := strconv.Atoi(parts[2])
+	if err != nil || port < 1 || port > maxFPort {
+		return "", 0, ErrMalformedDownlink
+	}
+
+	return parts[1], port, nil
+}
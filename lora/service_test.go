@@ -0,0 +1,208 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package lora_test
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/lora"
+	"github.com/mainflux/mainflux/lora/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	thingID   = "123e4567-e89b-12d3-a456-000000000001"
+	chanID    = "123e4567-e89b-12d3-a456-000000000002"
+	otherChan = "123e4567-e89b-12d3-a456-000000000003"
+	devEUI    = "0102030405060708"
+	appID     = "1"
+	rxTime    = "2019-10-16T14:02:47Z"
+	rxUnix    = 1571234567
+)
+
+func newService() (lora.Service, *mocks.Publisher, *mocks.Downlinks) {
+	pub := mocks.NewPublisher()
+	downlinks := mocks.NewDownlinks()
+	thingsRM := mocks.NewRouteMap()
+	channelsRM := mocks.NewRouteMap()
+
+	thingsRM.Save(thingID, devEUI)
+	channelsRM.Save(chanID, appID)
+
+	return lora.New(pub, thingsRM, channelsRM, downlinks), pub, downlinks
+}
+
+func TestPublish(t *testing.T) {
+	svc, pub, _ := newService()
+
+	data := []byte{0x01, 0x02, 0xff}
+	rx := lora.RxInfo{{Time: rxTime}}
+
+	cases := []struct {
+		desc        string
+		msg         lora.Message
+		contentType string
+		payload     string
+		err         error
+	}{
+		{
+			desc:        "publish frame payload",
+			msg:         lora.Message{ApplicationID: appID, DevEUI: devEUI, Data: base64.StdEncoding.EncodeToString(data)},
+			contentType: "application/octet-stream",
+			payload:     string(data),
+		},
+		{
+			desc: "publish decoded object",
+			msg: lora.Message{
+				ApplicationID: appID,
+				DevEUI:        devEUI,
+				RxInfo:        rx,
+				Object:        map[string]interface{}{"temperature": 21.5, "on": true, "status": "ok", "nested": map[string]interface{}{"a": 1.0}},
+			},
+			contentType: mainflux.SenMLJSON,
+			payload:     fmt.Sprintf(`[{"n":"on","t":%d,"vb":true},{"n":"status","t":%d,"vs":"ok"},{"n":"temperature","t":%d,"v":21.5}]`, rxUnix, rxUnix, rxUnix),
+		},
+		{
+			desc: "publish decoded SenML records",
+			msg: lora.Message{
+				ApplicationID: appID,
+				DevEUI:        devEUI,
+				Object:        []interface{}{map[string]interface{}{"n": "temperature", "v": 21.5}},
+			},
+			contentType: mainflux.SenMLJSON,
+			payload:     `[{"n":"temperature","v":21.5}]`,
+		},
+		{
+			desc: "publish object without values",
+			msg: lora.Message{
+				ApplicationID: appID,
+				DevEUI:        devEUI,
+				Object:        map[string]interface{}{"nested": map[string]interface{}{"a": 1.0}},
+			},
+			err: lora.ErrMalformedMessage,
+		},
+		{
+			desc: "publish invalid frame payload",
+			msg:  lora.Message{ApplicationID: appID, DevEUI: devEUI, Data: "!"},
+			err:  lora.ErrMalformedMessage,
+		},
+		{
+			desc: "publish message of unknown device",
+			msg:  lora.Message{ApplicationID: appID, DevEUI: "ffffffffffffffff"},
+			err:  lora.ErrNotFoundDev,
+		},
+		{
+			desc: "publish message of unknown application",
+			msg:  lora.Message{ApplicationID: "2", DevEUI: devEUI},
+			err:  lora.ErrNotFoundApp,
+		},
+	}
+
+	for _, tc := range cases {
+		before := len(pub.Messages())
+		err := svc.Publish(context.Background(), "", tc.msg)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+
+		msgs := pub.Messages()
+		if tc.err != nil {
+			assert.Len(t, msgs, before, fmt.Sprintf("%s: expected no message to be published\n", tc.desc))
+			continue
+		}
+
+		require.Len(t, msgs, before+1, fmt.Sprintf("%s: expected message to be published\n", tc.desc))
+		msg := msgs[before]
+		assert.Equal(t, chanID, msg.Channel, fmt.Sprintf("%s: expected channel %s got %s\n", tc.desc, chanID, msg.Channel))
+		assert.Equal(t, thingID, msg.Publisher, fmt.Sprintf("%s: expected publisher %s got %s\n", tc.desc, thingID, msg.Publisher))
+		assert.Equal(t, tc.contentType, msg.ContentType, fmt.Sprintf("%s: expected content type %s got %s\n", tc.desc, tc.contentType, msg.ContentType))
+		if tc.contentType == mainflux.SenMLJSON {
+			assert.JSONEq(t, tc.payload, string(msg.Payload), fmt.Sprintf("%s: expected payload %s got %s\n", tc.desc, tc.payload, msg.Payload))
+			continue
+		}
+		assert.Equal(t, tc.payload, string(msg.Payload), fmt.Sprintf("%s: expected payload %s got %s\n", tc.desc, tc.payload, msg.Payload))
+	}
+}
+
+func TestDownlink(t *testing.T) {
+	svc, _, downlinks := newService()
+
+	topic := fmt.Sprintf("application/%s/device/%s/tx", appID, devEUI)
+	payload := []byte("on")
+
+	cases := []struct {
+		desc     string
+		channel  string
+		subtopic string
+		fPort    int
+		err      error
+	}{
+		{
+			desc:     "downlink to thing",
+			channel:  chanID,
+			subtopic: fmt.Sprintf("downlink.%s", thingID),
+			fPort:    1,
+		},
+		{
+			desc:     "downlink to thing port",
+			channel:  chanID,
+			subtopic: fmt.Sprintf("downlink.%s.10", thingID),
+			fPort:    10,
+		},
+		{
+			desc:     "downlink to reserved port",
+			channel:  chanID,
+			subtopic: fmt.Sprintf("downlink.%s.224", thingID),
+			err:      lora.ErrMalformedDownlink,
+		},
+		{
+			desc:     "downlink without thing",
+			channel:  chanID,
+			subtopic: "downlink",
+			err:      lora.ErrMalformedDownlink,
+		},
+		{
+			desc:     "downlink on other subtopic",
+			channel:  chanID,
+			subtopic: fmt.Sprintf("uplink.%s", thingID),
+			err:      lora.ErrMalformedDownlink,
+		},
+		{
+			desc:     "downlink to unknown thing",
+			channel:  chanID,
+			subtopic: "downlink.unknown",
+			err:      lora.ErrNotFoundThing,
+		},
+		{
+			desc:     "downlink on unknown channel",
+			channel:  otherChan,
+			subtopic: fmt.Sprintf("downlink.%s", thingID),
+			err:      lora.ErrNotFoundChannel,
+		},
+	}
+
+	for _, tc := range cases {
+		before := len(downlinks.Downlinks(topic))
+		msg := mainflux.RawMessage{
+			Channel:  tc.channel,
+			Subtopic: tc.subtopic,
+			Payload:  payload,
+		}
+		err := svc.Downlink(context.Background(), msg)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+
+		dls := downlinks.Downlinks(topic)
+		if tc.err != nil {
+			assert.Len(t, dls, before, fmt.Sprintf("%s: expected no downlink to be published\n", tc.desc))
+			continue
+		}
+
+		require.Len(t, dls, before+1, fmt.Sprintf("%s: expected downlink to be published\n", tc.desc))
+		expected := lora.Downlink{FPort: tc.fPort, Data: base64.StdEncoding.EncodeToString(payload)}
+		assert.Equal(t, expected, dls[before], fmt.Sprintf("%s: expected %v got %v\n", tc.desc, expected, dls[before]))
+	}
+}
@@ -0,0 +1,205 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"encoding/binary"
+
+	gocoap "github.com/dustin/go-coap"
+	"github.com/mainflux/mainflux/lwm2m"
+)
+
+const (
+	// Objects and resources of the server account (LwM2M 1.0 appendix
+	// E.1 and E.2).
+	securityObject   = 0
+	serverObject     = 1
+	securityInstance = 1
+	serverInstance   = 0
+
+	securityURI           = 0
+	securityBootstrap     = 1
+	securityMode          = 2
+	securityShortServerID = 10
+	securityModeNoSec     = 3
+
+	serverShortServerID = 0
+	serverLifetime      = 1
+	serverStoring       = 6
+	serverBinding       = 7
+
+	// TLV type of the resource with value (LwM2M 1.0 section 6.4.3).
+	tlvResource = 0xc0
+	tlvID16     = 0x20
+	tlvLength8  = 0x08
+	tlvLength16 = 0x10
+)
+
+var (
+	// formats maps the content types to the CoAP content formats.
+	formats = map[string]uint32{
+		lwm2m.TextPlain: 0,
+		lwm2m.Opaque:    42,
+		lwm2m.SenMLJSON: 110,
+		lwm2m.SenMLCBOR: 112,
+		lwm2m.TLV:       11542,
+		lwm2m.JSON:      11543,
+	}
+
+	// types maps the CoAP content formats to the content types.
+	types = map[uint32]string{}
+)
+
+func init() {
+	for typ, format := range formats {
+		types[format] = typ
+	}
+}
+
+// toType returns the content type of the content format. Unknown
+// content formats are treated as opaque.
+func toType(format uint32) string {
+	if typ, ok := types[format]; ok {
+		return typ
+	}
+	return lwm2m.Opaque
+}
+
+// contentFormat returns the content format of the raw message, or the
+// content format of the plain text if the message doesn't have one.
+func contentFormat(data []byte) (uint32, error) {
+	if len(data) < 4 {
+		return 0, errBadOption
+	}
+
+	start := 4 + int(data[0]&0xf)
+	if len(data) < start {
+		return 0, errBadOption
+	}
+
+	b := data[start:]
+	id := 0
+	for len(b) > 0 && b[0] != 0xff {
+		delta, length := int(b[0]>>4), int(b[0]&0xf)
+		b = b[1:]
+
+		var err error
+		if delta, b, err = extendOption(delta, b); err != nil {
+			return 0, err
+		}
+		if length, b, err = extendOption(length, b); err != nil {
+			return 0, err
+		}
+		if len(b) < length {
+			return 0, errBadOption
+		}
+
+		id += delta
+		if gocoap.OptionID(id) == gocoap.ContentFormat {
+			if length > 2 {
+				return 0, errBadOption
+			}
+			val := make([]byte, 4)
+			copy(val[4-length:], b[:length])
+			return binary.BigEndian.Uint32(val), nil
+		}
+		b = b[length:]
+	}
+
+	return 0, nil
+}
+
+// extendOption reads the extended option delta or length (RFC 7252
+// section 3.1).
+func extendOption(val int, b []byte) (int, []byte, error) {
+	switch val {
+	case 13:
+		if len(b) < 1 {
+			return 0, nil, errBadOption
+		}
+		return int(b[0]) + 13, b[1:], nil
+	case 14:
+		if len(b) < 2 {
+			return 0, nil, errBadOption
+		}
+		return int(binary.BigEndian.Uint16(b)) + 269, b[2:], nil
+	case 15:
+		return 0, nil, errBadOption
+	default:
+		return val, b, nil
+	}
+}
+
+// encodeSecurity encodes the resources of the Security object instance of
+// the account, which doesn't use security.
+func encodeSecurity(acc lwm2m.Account) []byte {
+	var b []byte
+	b = appendTLV(b, securityURI, []byte(acc.ServerURI))
+	b = appendTLV(b, securityBootstrap, []byte{0})
+	b = appendTLV(b, securityMode, encodeInt(securityModeNoSec))
+	b = appendTLV(b, securityShortServerID, encodeInt(int64(acc.ShortServerID)))
+	return b
+}
+
+// encodeServer encodes the resources of the Server object instance of the
+// account.
+func encodeServer(acc lwm2m.Account) []byte {
+	var b []byte
+	b = appendTLV(b, serverShortServerID, encodeInt(int64(acc.ShortServerID)))
+	b = appendTLV(b, serverLifetime, encodeInt(int64(acc.Lifetime.Seconds())))
+	b = appendTLV(b, serverStoring, []byte{0})
+	b = appendTLV(b, serverBinding, []byte(acc.Binding))
+	return b
+}
+
+// appendTLV appends the resource TLV, whose value is shorter than 64KiB.
+func appendTLV(b []byte, id uint16, val []byte) []byte {
+	typ := byte(tlvResource)
+	if id > 0xff {
+		typ |= tlvID16
+	}
+	switch {
+	case len(val) < 8:
+		typ |= byte(len(val))
+	case len(val) <= 0xff:
+		typ |= tlvLength8
+	default:
+		typ |= tlvLength16
+	}
+
+	b = append(b, typ)
+	if id > 0xff {
+		b = append(b, byte(id>>8))
+	}
+	b = append(b, byte(id))
+	switch {
+	case len(val) < 8:
+	case len(val) <= 0xff:
+		b = append(b, byte(len(val)))
+	default:
+		b = append(b, byte(len(val)>>8), byte(len(val)))
+	}
+
+	return append(b, val...)
+}
+
+// encodeInt encodes the integer in the shortest of 1, 2, 4 or 8 bytes.
+func encodeInt(v int64) []byte {
+	switch {
+	case v >= -1<<7 && v < 1<<7:
+		return []byte{byte(v)}
+	case v >= -1<<15 && v < 1<<15:
+		b := make([]byte, 2)
+		binary.BigEndian.PutUint16(b, uint16(v))
+		return b
+	case v >= -1<<31 && v < 1<<31:
+		b := make([]byte, 4)
+		binary.BigEndian.PutUint32(b, uint32(v))
+		return b
+	default:
+		b := make([]byte, 8)
+		binary.BigEndian.PutUint64(b, uint64(v))
+		return b
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/mainflux/mainflux/lwm2m"
+)
+
+func viewRegistrationEndpoint(svc lwm2m.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewRegistrationReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		reg, err := svc.View(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		res := viewRegistrationRes{
+			ID:       reg.ID,
+			Endpoint: reg.Endpoint,
+			Channel:  reg.Channel,
+			Version:  reg.Version,
+			Binding:  reg.Binding,
+			Lifetime: uint64(reg.Lifetime.Seconds()),
+			Address:  reg.Address,
+			Objects:  reg.Objects,
+			Observed: reg.Observed,
+			Updated:  reg.Updated,
+		}
+
+		return res, nil
+	}
+}
+
+func readEndpoint(svc lwm2m.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(resourceReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		c, err := svc.Read(ctx, req.token, req.id, req.path)
+		if err != nil {
+			return nil, err
+		}
+
+		return readRes{content: c}, nil
+	}
+}
+
+func writeEndpoint(svc lwm2m.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(writeReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.Write(ctx, req.token, req.id, req.path, req.content); err != nil {
+			return nil, err
+		}
+
+		return changeRes{}, nil
+	}
+}
+
+func observeEndpoint(svc lwm2m.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(resourceReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.Observe(ctx, req.token, req.id, req.path); err != nil {
+			return nil, err
+		}
+
+		return changeRes{}, nil
+	}
+}
+
+func cancelObservationEndpoint(svc lwm2m.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(resourceReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.CancelObservation(ctx, req.token, req.id, req.path); err != nil {
+			return nil, err
+		}
+
+		return changeRes{}, nil
+	}
+}
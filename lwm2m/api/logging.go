@@ -0,0 +1,145 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !test
+// +build !test
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/lwm2m"
+)
+
+var _ lwm2m.Service = (*loggingMiddleware)(nil)
+
+type loggingMiddleware struct {
+	logger log.Logger
+	svc    lwm2m.Service
+}
+
+// LoggingMiddleware adds logging facilities to the core service.
+func LoggingMiddleware(svc lwm2m.Service, logger log.Logger) lwm2m.Service {
+	return &loggingMiddleware{logger, svc}
+}
+
+func (lm *loggingMiddleware) Bootstrap(ctx context.Context, key, addr string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method bootstrap for client %s took %s to complete", addr, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Bootstrap(ctx, key, addr)
+}
+
+func (lm *loggingMiddleware) Register(ctx context.Context, key string, reg lwm2m.Registration) (id string, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method register for endpoint %s at %s on channel %s took %s to complete", reg.Endpoint, reg.Address, reg.Channel, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Register(ctx, key, reg)
+}
+
+func (lm *loggingMiddleware) Update(ctx context.Context, reg lwm2m.Registration) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method update for registration %s at %s took %s to complete", reg.ID, reg.Address, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Update(ctx, reg)
+}
+
+func (lm *loggingMiddleware) Deregister(ctx context.Context, id string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method deregister for registration %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Deregister(ctx, id)
+}
+
+func (lm *loggingMiddleware) View(ctx context.Context, token, thingID string) (reg lwm2m.Registration, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method view for token %s and thing %s took %s to complete", token, thingID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.View(ctx, token, thingID)
+}
+
+func (lm *loggingMiddleware) Read(ctx context.Context, token, thingID, path string) (c lwm2m.Content, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method read for token %s and resource %s of thing %s took %s to complete", token, path, thingID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Read(ctx, token, thingID, path)
+}
+
+func (lm *loggingMiddleware) Write(ctx context.Context, token, thingID, path string, c lwm2m.Content) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method write for token %s and resource %s of thing %s took %s to complete", token, path, thingID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Write(ctx, token, thingID, path, c)
+}
+
+func (lm *loggingMiddleware) Observe(ctx context.Context, token, thingID, path string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method observe for token %s and resource %s of thing %s took %s to complete", token, path, thingID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Observe(ctx, token, thingID, path)
+}
+
+func (lm *loggingMiddleware) CancelObservation(ctx context.Context, token, thingID, path string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method cancel_observation for token %s and resource %s of thing %s took %s to complete", token, path, thingID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.CancelObservation(ctx, token, thingID, path)
+}
@@ -0,0 +1,114 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !test
+// +build !test
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/mainflux/mainflux/lwm2m"
+)
+
+var _ lwm2m.Service = (*metricsMiddleware)(nil)
+
+type metricsMiddleware struct {
+	counter metrics.Counter
+	latency metrics.Histogram
+	svc     lwm2m.Service
+}
+
+// MetricsMiddleware instruments core service by tracking request count and
+// latency.
+func MetricsMiddleware(svc lwm2m.Service, counter metrics.Counter, latency metrics.Histogram) lwm2m.Service {
+	return &metricsMiddleware{
+		counter: counter,
+		latency: latency,
+		svc:     svc,
+	}
+}
+
+func (ms *metricsMiddleware) Bootstrap(ctx context.Context, key, addr string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "bootstrap").Add(1)
+		ms.latency.With("method", "bootstrap").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Bootstrap(ctx, key, addr)
+}
+
+func (ms *metricsMiddleware) Register(ctx context.Context, key string, reg lwm2m.Registration) (string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "register").Add(1)
+		ms.latency.With("method", "register").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Register(ctx, key, reg)
+}
+
+func (ms *metricsMiddleware) Update(ctx context.Context, reg lwm2m.Registration) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "update").Add(1)
+		ms.latency.With("method", "update").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Update(ctx, reg)
+}
+
+func (ms *metricsMiddleware) Deregister(ctx context.Context, id string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "deregister").Add(1)
+		ms.latency.With("method", "deregister").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Deregister(ctx, id)
+}
+
+func (ms *metricsMiddleware) View(ctx context.Context, token, thingID string) (lwm2m.Registration, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view").Add(1)
+		ms.latency.With("method", "view").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.View(ctx, token, thingID)
+}
+
+func (ms *metricsMiddleware) Read(ctx context.Context, token, thingID, path string) (lwm2m.Content, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "read").Add(1)
+		ms.latency.With("method", "read").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Read(ctx, token, thingID, path)
+}
+
+func (ms *metricsMiddleware) Write(ctx context.Context, token, thingID, path string, c lwm2m.Content) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "write").Add(1)
+		ms.latency.With("method", "write").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Write(ctx, token, thingID, path, c)
+}
+
+func (ms *metricsMiddleware) Observe(ctx context.Context, token, thingID, path string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "observe").Add(1)
+		ms.latency.With("method", "observe").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Observe(ctx, token, thingID, path)
+}
+
+func (ms *metricsMiddleware) CancelObservation(ctx context.Context, token, thingID, path string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "cancel_observation").Add(1)
+		ms.latency.With("method", "cancel_observation").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.CancelObservation(ctx, token, thingID, path)
+}
@@ -0,0 +1,224 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	gocoap "github.com/dustin/go-coap"
+	"github.com/mainflux/mainflux/lwm2m"
+)
+
+const (
+	bootstrapPath    = "bs"
+	registrationPath = "rd"
+
+	// Registration query parameters (LwM2M 1.0 section 5.3.1). The thing
+	// key and the channel are passed as the additional parameters.
+	endpointParam = "ep"
+	lifetimeParam = "lt"
+	versionParam  = "lwm2m"
+	bindingParam  = "b"
+	keyParam      = "authorization"
+	channelParam  = "channel"
+
+	defLifetime = 86400 * time.Second
+	defVersion  = "1.0"
+	defBinding  = "U"
+)
+
+// handle handles the requests of the Bootstrap and the Client Registration
+// interfaces.
+func (s *Server) handle(svc lwm2m.Service, addr *net.UDPAddr, msg gocoap.Message, format uint32) {
+	res := gocoap.Message{
+		Type:      gocoap.NonConfirmable,
+		MessageID: msg.MessageID,
+		Token:     msg.Token,
+	}
+	if msg.Type == gocoap.Confirmable {
+		res.Type = gocoap.Acknowledgement
+	}
+
+	path := msg.Path()
+	switch {
+	case msg.Code == gocoap.POST && len(path) == 1 && path[0] == bootstrapPath:
+		res.Code = s.bootstrap(svc, addr, msg)
+	case msg.Code == gocoap.POST && len(path) == 1 && path[0] == registrationPath:
+		var id string
+		if id, res.Code = s.register(svc, addr, msg, format); id != "" {
+			res.SetOption(gocoap.LocationPath, []string{registrationPath, id})
+		}
+	case msg.Code == gocoap.POST && len(path) == 2 && path[0] == registrationPath:
+		res.Code = s.update(svc, addr, path[1], msg, format)
+	case msg.Code == gocoap.DELETE && len(path) == 2 && path[0] == registrationPath:
+		res.Code = s.deregister(svc, path[1])
+	default:
+		res.Code = gocoap.NotFound
+	}
+
+	s.transmit(addr, res)
+}
+
+func (s *Server) bootstrap(svc lwm2m.Service, addr *net.UDPAddr, msg gocoap.Message) gocoap.COAPCode {
+	query := parseQuery(msg)
+	if query[endpointParam] == "" {
+		return gocoap.BadRequest
+	}
+
+	if err := svc.Bootstrap(context.Background(), query[keyParam], addr.String()); err != nil {
+		return toCode(err)
+	}
+
+	return gocoap.Changed
+}
+
+func (s *Server) register(svc lwm2m.Service, addr *net.UDPAddr, msg gocoap.Message, format uint32) (string, gocoap.COAPCode) {
+	query := parseQuery(msg)
+
+	reg := lwm2m.Registration{
+		Endpoint: query[endpointParam],
+		Channel:  query[channelParam],
+		Version:  defVersion,
+		Binding:  defBinding,
+		Lifetime: defLifetime,
+		Address:  addr.String(),
+	}
+	if v, ok := query[versionParam]; ok {
+		reg.Version = v
+	}
+	if b, ok := query[bindingParam]; ok {
+		reg.Binding = b
+	}
+	if lt, ok := query[lifetimeParam]; ok {
+		lifetime, err := parseLifetime(lt)
+		if err != nil {
+			return "", gocoap.BadRequest
+		}
+		reg.Lifetime = lifetime
+	}
+
+	objects, err := parseObjects(msg.Payload, format)
+	if err != nil {
+		return "", gocoap.BadRequest
+	}
+	reg.Objects = objects
+
+	id, err := svc.Register(context.Background(), query[keyParam], reg)
+	if err != nil {
+		return "", toCode(err)
+	}
+
+	return id, gocoap.Created
+}
+
+func (s *Server) update(svc lwm2m.Service, addr *net.UDPAddr, id string, msg gocoap.Message, format uint32) gocoap.COAPCode {
+	query := parseQuery(msg)
+
+	upd := lwm2m.Registration{
+		ID:      id,
+		Binding: query[bindingParam],
+		Address: addr.String(),
+	}
+	if lt, ok := query[lifetimeParam]; ok {
+		lifetime, err := parseLifetime(lt)
+		if err != nil {
+			return gocoap.BadRequest
+		}
+		upd.Lifetime = lifetime
+	}
+
+	objects, err := parseObjects(msg.Payload, format)
+	if err != nil {
+		return gocoap.BadRequest
+	}
+	upd.Objects = objects
+
+	if err := svc.Update(context.Background(), upd); err != nil {
+		return toCode(err)
+	}
+
+	return gocoap.Changed
+}
+
+func (s *Server) deregister(svc lwm2m.Service, id string) gocoap.COAPCode {
+	if err := svc.Deregister(context.Background(), id); err != nil {
+		return toCode(err)
+	}
+
+	return gocoap.Deleted
+}
+
+func parseQuery(msg gocoap.Message) map[string]string {
+	query := map[string]string{}
+	for _, opt := range msg.Options(gocoap.URIQuery) {
+		param, ok := opt.(string)
+		if !ok {
+			continue
+		}
+		kv := strings.SplitN(param, "=", 2)
+		if len(kv) == 2 {
+			query[kv[0]] = kv[1]
+			continue
+		}
+		query[kv[0]] = ""
+	}
+
+	return query
+}
+
+func parseLifetime(lt string) (time.Duration, error) {
+	secs, err := strconv.ParseUint(lt, 10, 32)
+	if err != nil || secs == 0 {
+		return 0, lwm2m.ErrMalformedEntity
+	}
+
+	return time.Duration(secs) * time.Second, nil
+}
+
+// parseObjects parses the object instance links of the CoRE Link Format
+// payload (RFC 6690), e.g. </>;rt="oma.lwm2m",</1/0>,</3/0>.
+func parseObjects(payload []byte, format uint32) ([]string, error) {
+	if len(payload) == 0 {
+		return nil, nil
+	}
+
+	// Clients may omit the content format of the payload.
+	if format != uint32(gocoap.AppLinkFormat) && format != 0 {
+		return nil, lwm2m.ErrMalformedEntity
+	}
+
+	objects := []string{}
+	for _, link := range strings.Split(string(payload), ",") {
+		link = strings.TrimSpace(link)
+		end := strings.Index(link, ">")
+		if !strings.HasPrefix(link, "<") || end < 0 {
+			return nil, lwm2m.ErrMalformedEntity
+		}
+
+		path := link[1:end]
+		if path == "/" {
+			continue
+		}
+		objects = append(objects, path)
+	}
+
+	return objects, nil
+}
+
+func toCode(err error) gocoap.COAPCode {
+	switch err {
+	case lwm2m.ErrMalformedEntity:
+		return gocoap.BadRequest
+	case lwm2m.ErrUnauthorizedAccess:
+		return gocoap.Unauthorized
+	case lwm2m.ErrNotFound:
+		return gocoap.NotFound
+	default:
+		return gocoap.InternalServerError
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import "github.com/mainflux/mainflux/lwm2m"
+
+type apiReq interface {
+	validate() error
+}
+
+type viewRegistrationReq struct {
+	token string
+	id    string
+}
+
+func (req viewRegistrationReq) validate() error {
+	if req.token == "" {
+		return lwm2m.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" {
+		return lwm2m.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type resourceReq struct {
+	token string
+	id    string
+	path  string
+}
+
+func (req resourceReq) validate() error {
+	if req.token == "" {
+		return lwm2m.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" || req.path == "" {
+		return lwm2m.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type writeReq struct {
+	resourceReq
+	content lwm2m.Content
+}
+
+func (req writeReq) validate() error {
+	return req.resourceReq.validate()
+}
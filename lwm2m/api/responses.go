@@ -0,0 +1,61 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/lwm2m"
+)
+
+var (
+	_ mainflux.Response = (*viewRegistrationRes)(nil)
+	_ mainflux.Response = (*changeRes)(nil)
+)
+
+type viewRegistrationRes struct {
+	ID       string    `json:"id"`
+	Endpoint string    `json:"endpoint"`
+	Channel  string    `json:"channel"`
+	Version  string    `json:"version"`
+	Binding  string    `json:"binding"`
+	Lifetime uint64    `json:"lifetime"`
+	Address  string    `json:"address"`
+	Objects  []string  `json:"objects"`
+	Observed []string  `json:"observed"`
+	Updated  time.Time `json:"updated_at"`
+}
+
+func (res viewRegistrationRes) Code() int {
+	return http.StatusOK
+}
+
+func (res viewRegistrationRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res viewRegistrationRes) Empty() bool {
+	return false
+}
+
+// readRes is encoded as the resource value in its content type.
+type readRes struct {
+	content lwm2m.Content
+}
+
+type changeRes struct{}
+
+func (res changeRes) Code() int {
+	return http.StatusNoContent
+}
+
+func (res changeRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res changeRes) Empty() bool {
+	return true
+}
@@ -0,0 +1,450 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	gocoap "github.com/dustin/go-coap"
+	log "github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/lwm2m"
+)
+
+const (
+	maxPacketSize = 1500
+	tokenLen      = 8
+
+	// Transmission parameters (RFC 7252 section 4.8).
+	ackTimeout    = 2 * time.Second
+	maxRetransmit = 4
+
+	// responseTimeout limits the wait for the separate response, once the
+	// request is acknowledged.
+	responseTimeout = 30 * time.Second
+)
+
+var (
+	errBadOption = errors.New("bad option")
+	errReset     = errors.New("request reset by the client")
+)
+
+var _ lwm2m.Devices = (*Server)(nil)
+
+// Server serves the requests of the LwM2M clients over CoAP, and sends the
+// requests of the LwM2M server to the clients over the same UDP socket, so
+// that the clients behind NAT can be reached.
+type Server struct {
+	conn   *net.UDPConn
+	logger log.Logger
+
+	mu        sync.Mutex
+	messageID uint16
+	exchanges map[string]*exchange
+	observers map[string]*observer
+}
+
+// exchange represents the confirmable request sent to the client, awaiting
+// the response.
+type exchange struct {
+	messageID uint16
+	acked     chan struct{}
+	responses chan response
+}
+
+// observer represents the observation of the client resource.
+type observer struct {
+	token  string
+	notify func(lwm2m.Content)
+}
+
+type response struct {
+	msg    gocoap.Message
+	format uint32
+	err    error
+}
+
+// NewServer instantiates the LwM2M server using the provided UDP socket.
+func NewServer(conn *net.UDPConn, logger log.Logger) *Server {
+	s := &Server{
+		conn:      conn,
+		logger:    logger,
+		exchanges: make(map[string]*exchange),
+		observers: make(map[string]*observer),
+	}
+
+	b := make([]byte, 2)
+	rand.Read(b)
+	s.messageID = binary.BigEndian.Uint16(b)
+
+	return s
+}
+
+// Serve serves the client requests using the provided service, until the
+// socket is closed.
+func (s *Server) Serve(svc lwm2m.Service) error {
+	buf := make([]byte, maxPacketSize)
+	for {
+		n, addr, err := s.conn.ReadFromUDP(buf)
+		if err != nil {
+			if neterr, ok := err.(net.Error); ok && neterr.Timeout() {
+				time.Sleep(5 * time.Millisecond)
+				continue
+			}
+			return err
+		}
+
+		data := make([]byte, n)
+		copy(data, buf)
+
+		msg, err := gocoap.ParseMessage(data)
+		if err != nil {
+			s.logger.Warn(fmt.Sprintf("Failed to parse message from %s: %s", addr, err))
+			continue
+		}
+
+		// The library truncates the content format to a byte, which
+		// doesn't fit the LwM2M content formats.
+		format, err := contentFormat(data)
+		if err != nil {
+			s.logger.Warn(fmt.Sprintf("Failed to parse options from %s: %s", addr, err))
+			continue
+		}
+
+		switch {
+		case msg.Code == 0:
+			s.settle(addr, msg)
+		case msg.Code < gocoap.Created:
+			go s.handle(svc, addr, msg, format)
+		default:
+			s.receive(addr, msg, format)
+		}
+	}
+}
+
+// Read reads the value of the resource or object instance.
+func (s *Server) Read(ctx context.Context, addr, path string) (lwm2m.Content, error) {
+	req := gocoap.Message{Code: gocoap.GET}
+	req.SetPathString(path)
+
+	res, err := s.request(ctx, addr, req)
+	if err != nil {
+		return lwm2m.Content{}, err
+	}
+
+	if res.msg.Code != gocoap.Content {
+		return lwm2m.Content{}, toError(res.msg.Code)
+	}
+
+	return toContent(res), nil
+}
+
+// Write replaces the value of the resource.
+func (s *Server) Write(ctx context.Context, addr, path string, c lwm2m.Content) error {
+	format, ok := formats[c.Type]
+	if !ok {
+		return lwm2m.ErrMalformedEntity
+	}
+
+	req := gocoap.Message{Code: gocoap.PUT, Payload: c.Payload}
+	req.SetPathString(path)
+	req.SetOption(gocoap.ContentFormat, format)
+
+	res, err := s.request(ctx, addr, req)
+	if err != nil {
+		return err
+	}
+
+	if res.msg.Code != gocoap.Changed {
+		return toError(res.msg.Code)
+	}
+
+	return nil
+}
+
+// Observe observes the resource. The observation replaces the previous one
+// of the same resource, whose notifications are reset once received.
+func (s *Server) Observe(ctx context.Context, addr, path string, notify func(lwm2m.Content)) error {
+	req := gocoap.Message{Code: gocoap.GET}
+	req.SetPathString(path)
+	req.SetOption(gocoap.Observe, uint32(0))
+
+	ua, err := net.ResolveUDPAddr("udp", addr)
+	if err != nil {
+		return lwm2m.ErrMalformedEntity
+	}
+
+	req.Token = newToken()
+	o := &observer{token: string(req.Token), notify: notify}
+
+	s.mu.Lock()
+	s.observers[observerKey(ua.String(), path)] = o
+	s.mu.Unlock()
+
+	res, err := s.exchange(ctx, ua, req)
+	if err == nil && res.msg.Code != gocoap.Content {
+		err = toError(res.msg.Code)
+	}
+	if err == nil && res.msg.Option(gocoap.Observe) == nil {
+		err = lwm2m.ErrNotAllowed
+	}
+	if err != nil {
+		s.forget(ua.String(), path, o)
+		return err
+	}
+
+	notify(toContent(res))
+	return nil
+}
+
+// Cancel forgets the observation. The client cancels it once its next
+// notification is reset (RFC 7641 section 3.6), so the clients which are
+// gone don't hold the cancellation.
+func (s *Server) Cancel(_ context.Context, addr, path string) error {
+	ua, err := net.ResolveUDPAddr("udp", addr)
+	if err != nil {
+		return lwm2m.ErrMalformedEntity
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.observers, observerKey(ua.String(), path))
+	return nil
+}
+
+// Bootstrap deletes the objects of the client, writes the Security and
+// Server object instances of the account and finishes the bootstrap.
+func (s *Server) Bootstrap(ctx context.Context, addr string, acc lwm2m.Account) error {
+	// Bootstrap-Delete of the root path, which isn't set, deletes all
+	// the objects but the Bootstrap-Server account.
+	del := gocoap.Message{Code: gocoap.DELETE}
+
+	security := gocoap.Message{Code: gocoap.PUT, Payload: encodeSecurity(acc)}
+	security.SetPathString(fmt.Sprintf("/%d/%d", securityObject, securityInstance))
+	security.SetOption(gocoap.ContentFormat, formats[lwm2m.TLV])
+
+	server := gocoap.Message{Code: gocoap.PUT, Payload: encodeServer(acc)}
+	server.SetPathString(fmt.Sprintf("/%d/%d", serverObject, serverInstance))
+	server.SetOption(gocoap.ContentFormat, formats[lwm2m.TLV])
+
+	finish := gocoap.Message{Code: gocoap.POST}
+	finish.SetPathString(bootstrapPath)
+
+	steps := []struct {
+		req  gocoap.Message
+		code gocoap.COAPCode
+	}{
+		{req: del, code: gocoap.Deleted},
+		{req: security, code: gocoap.Changed},
+		{req: server, code: gocoap.Changed},
+		{req: finish, code: gocoap.Changed},
+	}
+
+	for _, step := range steps {
+		res, err := s.request(ctx, addr, step.req)
+		if err != nil {
+			s.logger.Warn(fmt.Sprintf("Failed to bootstrap client %s: %s", addr, err))
+			return err
+		}
+		if res.msg.Code != step.code {
+			err := toError(res.msg.Code)
+			s.logger.Warn(fmt.Sprintf("Failed to bootstrap client %s: %s", addr, err))
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) request(ctx context.Context, addr string, req gocoap.Message) (response, error) {
+	ua, err := net.ResolveUDPAddr("udp", addr)
+	if err != nil {
+		return response{}, lwm2m.ErrMalformedEntity
+	}
+
+	req.Token = newToken()
+	return s.exchange(ctx, ua, req)
+}
+
+// exchange sends the confirmable request, retransmitting it until it's
+// acknowledged (RFC 7252 section 4.2), and waits for its response.
+func (s *Server) exchange(ctx context.Context, addr *net.UDPAddr, req gocoap.Message) (response, error) {
+	ex := &exchange{
+		acked:     make(chan struct{}),
+		responses: make(chan response, 1),
+	}
+
+	s.mu.Lock()
+	s.messageID++
+	ex.messageID = s.messageID
+	key := exchangeKey(addr.String(), string(req.Token))
+	s.exchanges[key] = ex
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.exchanges, key)
+		s.mu.Unlock()
+	}()
+
+	req.Type = gocoap.Confirmable
+	req.MessageID = ex.messageID
+
+	timeout := ackTimeout
+	for i := 0; i <= maxRetransmit; i++ {
+		if err := gocoap.Transmit(s.conn, addr, req); err != nil {
+			return response{}, err
+		}
+
+		select {
+		case res := <-ex.responses:
+			return res, res.err
+		case <-ex.acked:
+			select {
+			case res := <-ex.responses:
+				return res, res.err
+			case <-time.After(responseTimeout):
+				return response{}, lwm2m.ErrUnreachable
+			case <-ctx.Done():
+				return response{}, ctx.Err()
+			}
+		case <-time.After(timeout):
+			timeout *= 2
+		case <-ctx.Done():
+			return response{}, ctx.Err()
+		}
+	}
+
+	return response{}, lwm2m.ErrUnreachable
+}
+
+// settle handles the empty acknowledgements and resets of the requests sent
+// to the client, and the pings of the client.
+func (s *Server) settle(addr *net.UDPAddr, msg gocoap.Message) {
+	if msg.Type == gocoap.Confirmable {
+		s.transmit(addr, gocoap.Message{Type: gocoap.Reset, MessageID: msg.MessageID})
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, ex := range s.exchanges {
+		if ex.messageID != msg.MessageID || !sameAddr(key, addr.String()) {
+			continue
+		}
+		switch msg.Type {
+		case gocoap.Acknowledgement:
+			select {
+			case <-ex.acked:
+			default:
+				close(ex.acked)
+			}
+		case gocoap.Reset:
+			select {
+			case ex.responses <- response{err: errReset}:
+			default:
+			}
+		}
+		return
+	}
+}
+
+// receive handles the responses to the requests sent to the client, and the
+// notifications of the observed resources.
+func (s *Server) receive(addr *net.UDPAddr, msg gocoap.Message, format uint32) {
+	token := string(msg.Token)
+
+	s.mu.Lock()
+	ex, pending := s.exchanges[exchangeKey(addr.String(), token)]
+	var notify func(lwm2m.Content)
+	if !pending {
+		for key, o := range s.observers {
+			if o.token == token && sameAddr(key, addr.String()) {
+				notify = o.notify
+				break
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	switch {
+	case pending:
+		select {
+		case ex.responses <- response{msg: msg, format: format}:
+		default:
+		}
+	case notify != nil:
+		notify(toContent(response{msg: msg, format: format}))
+	default:
+		// Unknown notifications are reset to cancel the observation.
+		s.transmit(addr, gocoap.Message{Type: gocoap.Reset, MessageID: msg.MessageID})
+		return
+	}
+
+	if msg.Type == gocoap.Confirmable {
+		s.transmit(addr, gocoap.Message{Type: gocoap.Acknowledgement, MessageID: msg.MessageID})
+	}
+}
+
+func (s *Server) forget(addr, path string, o *observer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := observerKey(addr, path)
+	if s.observers[key] == o {
+		delete(s.observers, key)
+	}
+}
+
+func (s *Server) transmit(addr *net.UDPAddr, msg gocoap.Message) {
+	if err := gocoap.Transmit(s.conn, addr, msg); err != nil {
+		s.logger.Warn(fmt.Sprintf("Failed to send message to %s: %s", addr, err))
+	}
+}
+
+func exchangeKey(addr, token string) string {
+	return fmt.Sprintf("%s|%x", addr, token)
+}
+
+func observerKey(addr, path string) string {
+	return fmt.Sprintf("%s|%s", addr, path)
+}
+
+func sameAddr(key, addr string) bool {
+	return len(key) > len(addr) && key[:len(addr)] == addr && key[len(addr)] == '|'
+}
+
+func newToken() []byte {
+	token := make([]byte, tokenLen)
+	rand.Read(token)
+	return token
+}
+
+func toContent(res response) lwm2m.Content {
+	return lwm2m.Content{
+		Type:    toType(res.format),
+		Payload: res.msg.Payload,
+	}
+}
+
+func toError(code gocoap.COAPCode) error {
+	switch code {
+	case gocoap.BadRequest, gocoap.NotAcceptable, gocoap.UnsupportedMediaType:
+		return lwm2m.ErrMalformedEntity
+	case gocoap.NotFound:
+		return lwm2m.ErrNotFound
+	case gocoap.Unauthorized, gocoap.Forbidden, gocoap.MethodNotAllowed:
+		return lwm2m.ErrNotAllowed
+	default:
+		return fmt.Errorf("unexpected response %s", code)
+	}
+}
@@ -0,0 +1,400 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	gocoap "github.com/dustin/go-coap"
+	"github.com/mainflux/mainflux"
+	log "github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/lwm2m"
+	"github.com/mainflux/mainflux/lwm2m/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	thingID   = "thing"
+	thingKey  = "thing-key"
+	chanID    = "channel"
+	token     = "token"
+	epName    = "urn:dev:os:0001"
+	path      = "/3303/0/5700"
+	wrongPath = "/3303/0/5701"
+)
+
+var account = lwm2m.Account{
+	ServerURI:     "coap://localhost:5683",
+	ShortServerID: 1,
+	Lifetime:      time.Hour,
+	Binding:       "U",
+}
+
+// client is the LwM2M client, which keeps the values of its resources and
+// responds to the server requests.
+type client struct {
+	conn      *net.UDPConn
+	mu        sync.Mutex
+	messageID uint16
+	resources map[string]string
+	observed  map[string][]byte
+	requests  chan gocoap.Message
+	responses chan gocoap.Message
+}
+
+func newServer(t *testing.T) (lwm2m.Service, *mocks.Publisher, *net.UDPAddr) {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	logger, err := log.New(ioutil.Discard, "info")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	auth := mocks.NewAuth(map[string]string{thingKey: thingID}, map[string][]string{thingID: {chanID}})
+	things := mocks.NewThings(map[string]string{thingID: token})
+	pub := mocks.NewPublisher()
+	srv := NewServer(conn, logger)
+	svc := lwm2m.New(auth, things, mocks.NewRepository(), srv, pub, mocks.NewIdentityProvider(), account)
+	go srv.Serve(svc)
+
+	return svc, pub, conn.LocalAddr().(*net.UDPAddr)
+}
+
+func newClient(t *testing.T, addr *net.UDPAddr) *client {
+	conn, err := net.DialUDP("udp", nil, addr)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	c := &client{
+		conn:      conn,
+		resources: map[string]string{path: "21.5"},
+		observed:  make(map[string][]byte),
+		requests:  make(chan gocoap.Message, 10),
+		responses: make(chan gocoap.Message, 10),
+	}
+	go c.read()
+
+	return c
+}
+
+func (c *client) read() {
+	buf := make([]byte, maxPacketSize)
+	for {
+		n, err := c.conn.Read(buf)
+		if err != nil {
+			return
+		}
+
+		// Parsed message keeps the slices of the datagram.
+		data := make([]byte, n)
+		copy(data, buf[:n])
+		msg, err := gocoap.ParseMessage(data)
+		if err != nil {
+			continue
+		}
+
+		switch {
+		case msg.Code == 0:
+		case msg.Code < gocoap.Created:
+			c.respond(msg)
+			c.requests <- msg
+		default:
+			c.responses <- msg
+		}
+	}
+}
+
+// respond sends the piggybacked response to the server request.
+func (c *client) respond(req gocoap.Message) {
+	res := gocoap.Message{
+		Type:      gocoap.Acknowledgement,
+		MessageID: req.MessageID,
+		Token:     req.Token,
+	}
+
+	c.mu.Lock()
+	p := "/" + req.PathString()
+	val, ok := c.resources[p]
+	switch req.Code {
+	case gocoap.GET:
+		if !ok {
+			res.Code = gocoap.NotFound
+			break
+		}
+		res.Code = gocoap.Content
+		res.Payload = []byte(val)
+		res.SetOption(gocoap.ContentFormat, uint32(0))
+		if req.Option(gocoap.Observe) != nil {
+			c.observed[p] = req.Token
+			res.SetOption(gocoap.Observe, uint32(1))
+		}
+	case gocoap.PUT:
+		res.Code = gocoap.Changed
+		if ok {
+			c.resources[p] = string(req.Payload)
+		}
+	case gocoap.DELETE:
+		res.Code = gocoap.Deleted
+	default:
+		res.Code = gocoap.Changed
+	}
+	c.mu.Unlock()
+
+	c.send(res)
+}
+
+// notify sends the notification of the observed resource change.
+func (c *client) notify(p, val string) {
+	c.mu.Lock()
+	c.resources[p] = val
+	c.messageID++
+	msg := gocoap.Message{
+		Type:      gocoap.NonConfirmable,
+		Code:      gocoap.Content,
+		MessageID: c.messageID,
+		Token:     c.observed[p],
+		Payload:   []byte(val),
+	}
+	c.mu.Unlock()
+
+	msg.SetOption(gocoap.Observe, uint32(2))
+	msg.SetOption(gocoap.ContentFormat, uint32(0))
+	c.send(msg)
+}
+
+func (c *client) request(code gocoap.COAPCode, path []string, query []string, payload string) gocoap.Message {
+	c.mu.Lock()
+	c.messageID++
+	msg := gocoap.Message{
+		Type:      gocoap.Confirmable,
+		Code:      code,
+		MessageID: c.messageID,
+		Token:     []byte{byte(c.messageID)},
+		Payload:   []byte(payload),
+	}
+	c.mu.Unlock()
+
+	msg.SetPath(path)
+	if len(query) > 0 {
+		msg.SetOption(gocoap.URIQuery, query)
+	}
+	c.send(msg)
+
+	select {
+	case res := <-c.responses:
+		return res
+	case <-time.After(time.Second):
+		return gocoap.Message{}
+	}
+}
+
+func (c *client) send(msg gocoap.Message) {
+	data, err := msg.MarshalBinary()
+	if err != nil {
+		return
+	}
+	c.conn.Write(data)
+}
+
+func (c *client) register() string {
+	query := []string{"ep=" + epName, "lt=300", "b=U", "authorization=" + thingKey, "channel=" + chanID}
+	res := c.request(gocoap.POST, []string{registrationPath}, query, "</>;rt=\"oma.lwm2m\",</3/0>,</3303/0>")
+
+	loc := res.Options(gocoap.LocationPath)
+	if res.Code != gocoap.Created || len(loc) != 2 {
+		return ""
+	}
+
+	return loc[1].(string)
+}
+
+func waitMessages(pub *mocks.Publisher, n int) []mainflux.RawMessage {
+	for i := 0; i < 100; i++ {
+		if msgs := pub.Messages(); len(msgs) >= n {
+			return msgs
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return pub.Messages()
+}
+
+func TestRegistration(t *testing.T) {
+	svc, _, addr := newServer(t)
+	c := newClient(t, addr)
+
+	id := c.register()
+	require.NotEmpty(t, id, "expected registration ID")
+
+	reg, err := svc.View(context.Background(), token, thingID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.Equal(t, epName, reg.Endpoint, fmt.Sprintf("expected endpoint %s got %s\n", epName, reg.Endpoint))
+	assert.Equal(t, 300*time.Second, reg.Lifetime, fmt.Sprintf("expected lifetime %s got %s\n", 300*time.Second, reg.Lifetime))
+	assert.Equal(t, []string{"/3/0", "/3303/0"}, reg.Objects, fmt.Sprintf("expected objects %v got %v\n", []string{"/3/0", "/3303/0"}, reg.Objects))
+
+	cases := []struct {
+		desc  string
+		code  gocoap.COAPCode
+		path  []string
+		query []string
+		res   gocoap.COAPCode
+	}{
+		{
+			desc:  "register with invalid key",
+			code:  gocoap.POST,
+			path:  []string{registrationPath},
+			query: []string{"ep=" + epName, "authorization=invalid", "channel=" + chanID},
+			res:   gocoap.Unauthorized,
+		},
+		{
+			desc:  "register without channel",
+			code:  gocoap.POST,
+			path:  []string{registrationPath},
+			query: []string{"ep=" + epName, "authorization=" + thingKey},
+			res:   gocoap.BadRequest,
+		},
+		{
+			desc:  "register with invalid lifetime",
+			code:  gocoap.POST,
+			path:  []string{registrationPath},
+			query: []string{"ep=" + epName, "lt=invalid", "authorization=" + thingKey, "channel=" + chanID},
+			res:   gocoap.BadRequest,
+		},
+		{
+			desc:  "update registration",
+			code:  gocoap.POST,
+			path:  []string{registrationPath, id},
+			query: []string{"lt=600"},
+			res:   gocoap.Changed,
+		},
+		{
+			desc: "update non-existing registration",
+			code: gocoap.POST,
+			path: []string{registrationPath, "invalid"},
+			res:  gocoap.NotFound,
+		},
+		{
+			desc: "deregister",
+			code: gocoap.DELETE,
+			path: []string{registrationPath, id},
+			res:  gocoap.Deleted,
+		},
+		{
+			desc: "deregister deregistered client",
+			code: gocoap.DELETE,
+			path: []string{registrationPath, id},
+			res:  gocoap.NotFound,
+		},
+		{
+			desc: "request unknown path",
+			code: gocoap.GET,
+			path: []string{"unknown"},
+			res:  gocoap.NotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		res := c.request(tc.code, tc.path, tc.query, "")
+		assert.Equal(t, tc.res, res.Code, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.res, res.Code))
+	}
+}
+
+func TestReadWrite(t *testing.T) {
+	svc, _, addr := newServer(t)
+	c := newClient(t, addr)
+	require.NotEmpty(t, c.register(), "expected registration ID")
+
+	cont, err := svc.Read(context.Background(), token, thingID, path)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.Equal(t, lwm2m.Content{Type: lwm2m.TextPlain, Payload: []byte("21.5")}, cont, fmt.Sprintf("expected value 21.5 got %s\n", cont.Payload))
+
+	_, err = svc.Read(context.Background(), token, thingID, wrongPath)
+	assert.Equal(t, lwm2m.ErrNotFound, err, fmt.Sprintf("expected %s got %s\n", lwm2m.ErrNotFound, err))
+
+	err = svc.Write(context.Background(), token, thingID, path, lwm2m.Content{Type: lwm2m.TextPlain, Payload: []byte("25")})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cont, err = svc.Read(context.Background(), token, thingID, path)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.Equal(t, []byte("25"), cont.Payload, fmt.Sprintf("expected value 25 got %s\n", cont.Payload))
+}
+
+func TestObserve(t *testing.T) {
+	svc, pub, addr := newServer(t)
+	c := newClient(t, addr)
+	require.NotEmpty(t, c.register(), "expected registration ID")
+
+	err := svc.Observe(context.Background(), token, thingID, path)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	c.notify(path, "22")
+
+	msgs := waitMessages(pub, 2)
+	require.Len(t, msgs, 2, fmt.Sprintf("expected 2 messages got %d\n", len(msgs)))
+	for i, val := range []string{"21.5", "22"} {
+		assert.Equal(t, "3303.0.5700", msgs[i].Subtopic, fmt.Sprintf("expected subtopic 3303.0.5700 got %s\n", msgs[i].Subtopic))
+		assert.Equal(t, lwm2m.TextPlain, msgs[i].ContentType, fmt.Sprintf("expected content type %s got %s\n", lwm2m.TextPlain, msgs[i].ContentType))
+		assert.Equal(t, []byte(val), msgs[i].Payload, fmt.Sprintf("expected payload %s got %s\n", val, msgs[i].Payload))
+	}
+
+	err = svc.CancelObservation(context.Background(), token, thingID, path)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	c.notify(path, "23")
+	time.Sleep(100 * time.Millisecond)
+	msgs = pub.Messages()
+	assert.Len(t, msgs, 2, fmt.Sprintf("expected 2 messages after cancellation got %d\n", len(msgs)))
+}
+
+func TestBootstrap(t *testing.T) {
+	_, _, addr := newServer(t)
+	c := newClient(t, addr)
+
+	res := c.request(gocoap.POST, []string{bootstrapPath}, []string{"ep=" + epName, "authorization=invalid"}, "")
+	assert.Equal(t, gocoap.Unauthorized, res.Code, fmt.Sprintf("expected %s got %s\n", gocoap.Unauthorized, res.Code))
+
+	res = c.request(gocoap.POST, []string{bootstrapPath}, []string{"ep=" + epName, "authorization=" + thingKey}, "")
+	require.Equal(t, gocoap.Changed, res.Code, fmt.Sprintf("expected %s got %s\n", gocoap.Changed, res.Code))
+
+	steps := []struct {
+		code    gocoap.COAPCode
+		path    string
+		payload []byte
+	}{
+		{code: gocoap.DELETE, path: ""},
+		{code: gocoap.PUT, path: "0/1", payload: encodeSecurity(account)},
+		{code: gocoap.PUT, path: "1/0", payload: encodeServer(account)},
+		{code: gocoap.POST, path: bootstrapPath},
+	}
+
+	for _, step := range steps {
+		select {
+		case req := <-c.requests:
+			assert.Equal(t, step.code, req.Code, fmt.Sprintf("expected %s got %s\n", step.code, req.Code))
+			assert.Equal(t, step.path, req.PathString(), fmt.Sprintf("expected path %s got %s\n", step.path, req.PathString()))
+			assert.Equal(t, step.payload, nilIfEmpty(req.Payload), fmt.Sprintf("expected payload %x got %x\n", step.payload, req.Payload))
+		case <-time.After(time.Second):
+			t.Fatalf("expected %s %s request", step.code, step.path)
+		}
+	}
+}
+
+func TestEncodeServer(t *testing.T) {
+	// Short Server ID 1, lifetime 3600, notification storing false and
+	// binding U.
+	expected := []byte{0xc1, 0x00, 0x01, 0xc2, 0x01, 0x0e, 0x10, 0xc1, 0x06, 0x00, 0xc1, 0x07, 'U'}
+	b := encodeServer(account)
+	assert.Equal(t, expected, b, fmt.Sprintf("expected %x got %x\n", expected, b))
+}
+
+func nilIfEmpty(b []byte) []byte {
+	if len(b) == 0 {
+		return nil
+	}
+	return b
+}
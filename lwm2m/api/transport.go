@@ -0,0 +1,184 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/go-zoo/bone"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/lwm2m"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	protocol    = "lwm2m"
+	contentType = "application/json"
+
+	// maxValueSize limits the size of the written resource value, which is
+	// sent to the client in a single datagram.
+	maxValueSize = 1024
+)
+
+var errUnsupportedContentType = errors.New("unsupported content type")
+
+// MakeHandler returns a HTTP handler for API endpoints.
+func MakeHandler(svc lwm2m.Service) http.Handler {
+	opts := []kithttp.ServerOption{
+		kithttp.ServerErrorEncoder(encodeError),
+	}
+
+	r := bone.New()
+
+	r.Get("/things/:id/registration", kithttp.NewServer(
+		viewRegistrationEndpoint(svc),
+		decodeView,
+		encodeResponse,
+		opts...,
+	))
+
+	read := kithttp.NewServer(
+		readEndpoint(svc),
+		decodeResource,
+		encodeResponse,
+		opts...,
+	)
+	r.Get("/things/:id/objects/:object/:instance", read)
+	r.Get("/things/:id/objects/:object/:instance/:resource", read)
+
+	r.Put("/things/:id/objects/:object/:instance/:resource", kithttp.NewServer(
+		writeEndpoint(svc),
+		decodeWrite,
+		encodeResponse,
+		opts...,
+	))
+
+	observe := kithttp.NewServer(
+		observeEndpoint(svc),
+		decodeResource,
+		encodeResponse,
+		opts...,
+	)
+	r.Post("/things/:id/observations/:object/:instance", observe)
+	r.Post("/things/:id/observations/:object/:instance/:resource", observe)
+
+	cancel := kithttp.NewServer(
+		cancelObservationEndpoint(svc),
+		decodeResource,
+		encodeResponse,
+		opts...,
+	)
+	r.Delete("/things/:id/observations/:object/:instance", cancel)
+	r.Delete("/things/:id/observations/:object/:instance/:resource", cancel)
+
+	r.GetFunc("/version", mainflux.Version(protocol))
+	r.GetFunc(mainflux.DiscoveryPath, mainflux.Discovery(protocol, mainflux.Capabilities{
+		ContentTypes: []string{contentType, lwm2m.TextPlain, lwm2m.Opaque, lwm2m.TLV, lwm2m.JSON, lwm2m.SenMLJSON, lwm2m.SenMLCBOR},
+		Limits:       mainflux.Limits{MaxPayload: maxValueSize},
+	}))
+	r.Handle("/metrics", promhttp.Handler())
+
+	return r
+}
+
+func decodeView(_ context.Context, r *http.Request) (interface{}, error) {
+	req := viewRegistrationReq{
+		token: r.Header.Get("Authorization"),
+		id:    bone.GetValue(r, "id"),
+	}
+
+	return req, nil
+}
+
+func decodeResource(_ context.Context, r *http.Request) (interface{}, error) {
+	req := resourceReq{
+		token: r.Header.Get("Authorization"),
+		id:    bone.GetValue(r, "id"),
+		path:  fmt.Sprintf("/%s/%s", bone.GetValue(r, "object"), bone.GetValue(r, "instance")),
+	}
+
+	if res := bone.GetValue(r, "resource"); res != "" {
+		req.path = fmt.Sprintf("%s/%s", req.path, res)
+	}
+
+	return req, nil
+}
+
+func decodeWrite(ctx context.Context, r *http.Request) (interface{}, error) {
+	ct := strings.TrimSpace(strings.Split(r.Header.Get("Content-Type"), ";")[0])
+	if _, ok := formats[ct]; !ok {
+		return nil, errUnsupportedContentType
+	}
+
+	payload, err := ioutil.ReadAll(http.MaxBytesReader(nil, r.Body, maxValueSize))
+	if err != nil {
+		return nil, lwm2m.ErrMalformedEntity
+	}
+
+	req, err := decodeResource(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	return writeReq{
+		resourceReq: req.(resourceReq),
+		content: lwm2m.Content{
+			Type:    ct,
+			Payload: payload,
+		},
+	}, nil
+}
+
+func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	if rr, ok := response.(readRes); ok {
+		w.Header().Set("Content-Type", rr.content.Type)
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write(rr.content.Payload)
+		return err
+	}
+
+	w.Header().Set("Content-Type", contentType)
+
+	if ar, ok := response.(mainflux.Response); ok {
+		for k, v := range ar.Headers() {
+			w.Header().Set(k, v)
+		}
+
+		w.WriteHeader(ar.Code())
+
+		if ar.Empty() {
+			return nil
+		}
+	}
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+	w.Header().Set("Content-Type", contentType)
+
+	switch err {
+	case lwm2m.ErrMalformedEntity:
+		w.WriteHeader(http.StatusBadRequest)
+	case lwm2m.ErrUnauthorizedAccess:
+		w.WriteHeader(http.StatusForbidden)
+	case lwm2m.ErrNotFound:
+		w.WriteHeader(http.StatusNotFound)
+	case lwm2m.ErrNotAllowed:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	case lwm2m.ErrUnreachable:
+		w.WriteHeader(http.StatusGatewayTimeout)
+	case errUnsupportedContentType:
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,9 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package lwm2m contains the domain concept definitions needed to support
+// Mainflux LwM2M adapter service functionality. LwM2M adapter acts as the
+// LwM2M bootstrap and device management server, which maps the registered
+// LwM2M clients to things, and publishes the values of their observed
+// resources to the channel subtopics named after the resource paths.
+package lwm2m
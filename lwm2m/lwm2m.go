@@ -0,0 +1,126 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package lwm2m
+
+import (
+	"context"
+	"time"
+)
+
+// Content types of the resource values, as defined by the LwM2M
+// specification.
+const (
+	TextPlain = "text/plain"
+	Opaque    = "application/octet-stream"
+	TLV       = "application/vnd.oma.lwm2m+tlv"
+	JSON      = "application/vnd.oma.lwm2m+json"
+	SenMLJSON = "application/senml+json"
+	SenMLCBOR = "application/senml+cbor"
+)
+
+// Registration represents the registration of the LwM2M client, which is
+// mapped to the thing authenticated on registration.
+type Registration struct {
+	ID       string
+	ThingID  string
+	Channel  string
+	Endpoint string
+	Version  string
+	Binding  string
+	Lifetime time.Duration
+	Address  string
+	Updated  time.Time
+
+	// Objects lists the object instances of the client, e.g. "/3/0".
+	Objects []string
+
+	// Observed lists the paths of the observed resources, which are
+	// observed again when the client registers anew.
+	Observed []string
+}
+
+// Expired reports whether the registration lifetime passed at the provided
+// time without the registration being updated.
+func (reg Registration) Expired(t time.Time) bool {
+	return reg.Lifetime > 0 && t.After(reg.Updated.Add(reg.Lifetime))
+}
+
+// Content represents the value of the resource, or of the object instance,
+// in the provided content type.
+type Content struct {
+	Type    string
+	Payload []byte
+}
+
+// Account represents the account of the LwM2M server written to the
+// Security and Server objects of the client on bootstrap.
+type Account struct {
+	// ServerURI is the URI of the LwM2M server, e.g. coap://host:5683.
+	ServerURI string
+
+	// ShortServerID identifies the account on the client.
+	ShortServerID uint16
+
+	// Lifetime is the registration lifetime.
+	Lifetime time.Duration
+
+	// Binding is the transport binding of the client, e.g. "U".
+	Binding string
+}
+
+// Repository specifies a registration persistence API.
+type Repository interface {
+	// Save persists the registration.
+	Save(context.Context, Registration) error
+
+	// Update updates the lifetime, binding, address, objects and observed
+	// resources of the registration.
+	Update(context.Context, Registration) error
+
+	// RetrieveByID retrieves the registration having the provided
+	// identifier.
+	RetrieveByID(context.Context, string) (Registration, error)
+
+	// RetrieveByThing retrieves the registration of the thing having the
+	// provided identifier.
+	RetrieveByThing(context.Context, string) (Registration, error)
+
+	// Remove removes the registration having the provided identifier.
+	Remove(context.Context, string) error
+}
+
+// Devices specifies the API used to send the server requests to the LwM2M
+// clients reachable at the provided addresses.
+type Devices interface {
+	// Read reads the value of the resource or of the object instance.
+	Read(context.Context, string, string) (Content, error)
+
+	// Write writes the value of the resource.
+	Write(context.Context, string, string, Content) error
+
+	// Observe starts observing the resource, passing its value and
+	// every notification of its change to the provided handler.
+	Observe(context.Context, string, string, func(Content)) error
+
+	// Cancel stops observing the resource.
+	Cancel(context.Context, string, string) error
+
+	// Bootstrap writes the provided server account to the client and
+	// finishes the bootstrap.
+	Bootstrap(context.Context, string, Account) error
+}
+
+// Things specifies the API of the things service used to check the thing
+// ownership.
+type Things interface {
+	// Thing checks whether the thing identified by the provided ID belongs
+	// to the user identified by the provided token.
+	Thing(string, string) error
+}
+
+// IdentityProvider specifies an API for generating unique identifiers.
+type IdentityProvider interface {
+	// ID generates the unique identifier.
+	ID() (string, error)
+}
@@ -0,0 +1,156 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/mainflux/mainflux/lwm2m"
+)
+
+// Devices is the in-memory mock of the LwM2M clients, which keeps the
+// resource values of the clients reachable at the added addresses.
+type Devices struct {
+	mu        sync.Mutex
+	resources map[string]map[string]lwm2m.Content
+	observers map[string]map[string]func(lwm2m.Content)
+	accounts  map[string]lwm2m.Account
+}
+
+var _ lwm2m.Devices = (*Devices)(nil)
+
+// NewDevices returns LwM2M clients mock.
+func NewDevices() *Devices {
+	return &Devices{
+		resources: make(map[string]map[string]lwm2m.Content),
+		observers: make(map[string]map[string]func(lwm2m.Content)),
+		accounts:  make(map[string]lwm2m.Account),
+	}
+}
+
+// Add makes the client reachable at the provided address, with the provided
+// resource values.
+func (d *Devices) Add(addr string, resources map[string]lwm2m.Content) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.resources[addr] = resources
+	d.observers[addr] = make(map[string]func(lwm2m.Content))
+}
+
+// Change changes the value of the resource, and notifies its observer.
+func (d *Devices) Change(addr, path string, c lwm2m.Content) {
+	d.mu.Lock()
+	d.resources[addr][path] = c
+	notify, ok := d.observers[addr][path]
+	d.mu.Unlock()
+
+	if ok {
+		notify(c)
+	}
+}
+
+// Observed returns the sorted paths of the resources observed at the
+// provided address.
+func (d *Devices) Observed(addr string) []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	paths := []string{}
+	for path := range d.observers[addr] {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	return paths
+}
+
+// Account returns the server account written to the client at the provided
+// address on bootstrap.
+func (d *Devices) Account(addr string) (lwm2m.Account, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	acc, ok := d.accounts[addr]
+	return acc, ok
+}
+
+// Read returns the value of the resource.
+func (d *Devices) Read(_ context.Context, addr, path string) (lwm2m.Content, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	resources, ok := d.resources[addr]
+	if !ok {
+		return lwm2m.Content{}, lwm2m.ErrUnreachable
+	}
+
+	c, ok := resources[path]
+	if !ok {
+		return lwm2m.Content{}, lwm2m.ErrNotFound
+	}
+
+	return c, nil
+}
+
+// Write changes the value of the existing resource.
+func (d *Devices) Write(_ context.Context, addr, path string, c lwm2m.Content) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	resources, ok := d.resources[addr]
+	if !ok {
+		return lwm2m.ErrUnreachable
+	}
+
+	if _, ok := resources[path]; !ok {
+		return lwm2m.ErrNotFound
+	}
+	resources[path] = c
+
+	return nil
+}
+
+// Observe keeps the handler, and passes it the current resource value.
+func (d *Devices) Observe(ctx context.Context, addr, path string, notify func(lwm2m.Content)) error {
+	c, err := d.Read(ctx, addr, path)
+	if err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	d.observers[addr][path] = notify
+	d.mu.Unlock()
+
+	notify(c)
+	return nil
+}
+
+// Cancel removes the handler.
+func (d *Devices) Cancel(_ context.Context, addr, path string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, ok := d.resources[addr]; !ok {
+		return lwm2m.ErrUnreachable
+	}
+	delete(d.observers[addr], path)
+
+	return nil
+}
+
+// Bootstrap keeps the account.
+func (d *Devices) Bootstrap(_ context.Context, addr string, acc lwm2m.Account) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, ok := d.resources[addr]; !ok {
+		return lwm2m.ErrUnreachable
+	}
+	d.accounts[addr] = acc
+
+	return nil
+}
@@ -0,0 +1,92 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mainflux/mainflux/lwm2m"
+)
+
+var _ lwm2m.Repository = (*repositoryMock)(nil)
+
+type repositoryMock struct {
+	mu            sync.Mutex
+	registrations map[string]lwm2m.Registration
+}
+
+// NewRepository creates in-memory registration repository.
+func NewRepository() lwm2m.Repository {
+	return &repositoryMock{
+		registrations: make(map[string]lwm2m.Registration),
+	}
+}
+
+func (repo *repositoryMock) Save(_ context.Context, reg lwm2m.Registration) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for _, r := range repo.registrations {
+		if r.ID == reg.ID || r.ThingID == reg.ThingID {
+			return lwm2m.ErrMalformedEntity
+		}
+	}
+	repo.registrations[reg.ID] = reg
+
+	return nil
+}
+
+func (repo *repositoryMock) Update(_ context.Context, reg lwm2m.Registration) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	r, ok := repo.registrations[reg.ID]
+	if !ok {
+		return lwm2m.ErrNotFound
+	}
+
+	r.Lifetime = reg.Lifetime
+	r.Binding = reg.Binding
+	r.Address = reg.Address
+	r.Updated = reg.Updated
+	r.Objects = reg.Objects
+	r.Observed = reg.Observed
+	repo.registrations[reg.ID] = r
+
+	return nil
+}
+
+func (repo *repositoryMock) RetrieveByID(_ context.Context, id string) (lwm2m.Registration, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	reg, ok := repo.registrations[id]
+	if !ok {
+		return lwm2m.Registration{}, lwm2m.ErrNotFound
+	}
+
+	return reg, nil
+}
+
+func (repo *repositoryMock) RetrieveByThing(_ context.Context, thingID string) (lwm2m.Registration, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for _, reg := range repo.registrations {
+		if reg.ThingID == thingID {
+			return reg, nil
+		}
+	}
+
+	return lwm2m.Registration{}, lwm2m.ErrNotFound
+}
+
+func (repo *repositoryMock) Remove(_ context.Context, id string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	delete(repo.registrations, id)
+	return nil
+}
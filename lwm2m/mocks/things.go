@@ -0,0 +1,31 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import "github.com/mainflux/mainflux/lwm2m"
+
+var _ lwm2m.Things = (*thingsMock)(nil)
+
+type thingsMock struct {
+	things map[string]string
+}
+
+// NewThings returns things API mock. Things are mapped to the tokens of their
+// owners.
+func NewThings(things map[string]string) lwm2m.Things {
+	return thingsMock{things: things}
+}
+
+func (tm thingsMock) Thing(token, id string) error {
+	if token == "" {
+		return lwm2m.ErrUnauthorizedAccess
+	}
+
+	owner, ok := tm.things[id]
+	if !ok || owner != token {
+		return lwm2m.ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,159 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq" // required for DB access
+	"github.com/mainflux/mainflux/lwm2m"
+)
+
+const (
+	errDuplicate = "unique_violation"
+	errInvalid   = "invalid_text_representation"
+)
+
+var _ lwm2m.Repository = (*registrationRepository)(nil)
+
+type registrationRepository struct {
+	db *sqlx.DB
+}
+
+// New instantiates a PostgreSQL implementation of registration repository.
+func New(db *sqlx.DB) lwm2m.Repository {
+	return &registrationRepository{
+		db: db,
+	}
+}
+
+func (rr registrationRepository) Save(ctx context.Context, reg lwm2m.Registration) error {
+	q := `INSERT INTO registrations (id, thing_id, channel_id, endpoint, version, binding, lifetime, address, objects, observed, updated_at)
+	      VALUES (:id, :thing_id, :channel_id, :endpoint, :version, :binding, :lifetime, :address, :objects, :observed, :updated_at);`
+
+	if _, err := rr.db.NamedExecContext(ctx, q, toDBRegistration(reg)); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code.Name() {
+			case errDuplicate, errInvalid:
+				return lwm2m.ErrMalformedEntity
+			}
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (rr registrationRepository) Update(ctx context.Context, reg lwm2m.Registration) error {
+	q := `UPDATE registrations SET binding = :binding, lifetime = :lifetime, address = :address,
+	      objects = :objects, observed = :observed, updated_at = :updated_at WHERE id = :id;`
+
+	res, err := rr.db.NamedExecContext(ctx, q, toDBRegistration(reg))
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == errInvalid {
+			return lwm2m.ErrNotFound
+		}
+		return err
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if cnt == 0 {
+		return lwm2m.ErrNotFound
+	}
+
+	return nil
+}
+
+func (rr registrationRepository) RetrieveByID(ctx context.Context, id string) (lwm2m.Registration, error) {
+	q := `SELECT id, thing_id, channel_id, endpoint, version, binding, lifetime, address, objects, observed, updated_at
+	      FROM registrations WHERE id = $1;`
+
+	return rr.retrieve(ctx, q, id)
+}
+
+func (rr registrationRepository) RetrieveByThing(ctx context.Context, thingID string) (lwm2m.Registration, error) {
+	q := `SELECT id, thing_id, channel_id, endpoint, version, binding, lifetime, address, objects, observed, updated_at
+	      FROM registrations WHERE thing_id = $1;`
+
+	return rr.retrieve(ctx, q, thingID)
+}
+
+func (rr registrationRepository) Remove(ctx context.Context, id string) error {
+	q := `DELETE FROM registrations WHERE id = $1;`
+
+	if _, err := rr.db.ExecContext(ctx, q, id); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == errInvalid {
+			return nil
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (rr registrationRepository) retrieve(ctx context.Context, q, arg string) (lwm2m.Registration, error) {
+	var dbreg dbRegistration
+	if err := rr.db.QueryRowxContext(ctx, q, arg).StructScan(&dbreg); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if err == sql.ErrNoRows || ok && errInvalid == pqErr.Code.Name() {
+			return lwm2m.Registration{}, lwm2m.ErrNotFound
+		}
+		return lwm2m.Registration{}, err
+	}
+
+	return toRegistration(dbreg), nil
+}
+
+type dbRegistration struct {
+	ID        string         `db:"id"`
+	ThingID   string         `db:"thing_id"`
+	ChannelID string         `db:"channel_id"`
+	Endpoint  string         `db:"endpoint"`
+	Version   string         `db:"version"`
+	Binding   string         `db:"binding"`
+	Lifetime  int64          `db:"lifetime"`
+	Address   string         `db:"address"`
+	Objects   pq.StringArray `db:"objects"`
+	Observed  pq.StringArray `db:"observed"`
+	UpdatedAt time.Time      `db:"updated_at"`
+}
+
+func toDBRegistration(reg lwm2m.Registration) dbRegistration {
+	return dbRegistration{
+		ID:        reg.ID,
+		ThingID:   reg.ThingID,
+		ChannelID: reg.Channel,
+		Endpoint:  reg.Endpoint,
+		Version:   reg.Version,
+		Binding:   reg.Binding,
+		Lifetime:  int64(reg.Lifetime / time.Second),
+		Address:   reg.Address,
+		Objects:   pq.StringArray(reg.Objects),
+		Observed:  pq.StringArray(reg.Observed),
+		UpdatedAt: reg.Updated.UTC(),
+	}
+}
+
+func toRegistration(dbreg dbRegistration) lwm2m.Registration {
+	return lwm2m.Registration{
+		ID:       dbreg.ID,
+		ThingID:  dbreg.ThingID,
+		Channel:  dbreg.ChannelID,
+		Endpoint: dbreg.Endpoint,
+		Version:  dbreg.Version,
+		Binding:  dbreg.Binding,
+		Lifetime: time.Duration(dbreg.Lifetime) * time.Second,
+		Address:  dbreg.Address,
+		Objects:  []string(dbreg.Objects),
+		Observed: []string(dbreg.Observed),
+		Updated:  dbreg.UpdatedAt.UTC(),
+	}
+}
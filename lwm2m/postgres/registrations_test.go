@@ -0,0 +1,216 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/lwm2m"
+	"github.com/mainflux/mainflux/lwm2m/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	id      = "123e4567-e89b-12d3-a456-000000000001"
+	otherID = "123e4567-e89b-12d3-a456-000000000002"
+	thingID = "123e4567-e89b-12d3-a456-000000000011"
+	chanID  = "123e4567-e89b-12d3-a456-000000000021"
+	wrongID = "123e4567-e89b-12d3-a456-000000000099"
+	invalid = "invalid"
+)
+
+func newRegistration(id string) lwm2m.Registration {
+	return lwm2m.Registration{
+		ID:       id,
+		ThingID:  thingID,
+		Channel:  chanID,
+		Endpoint: "urn:dev:os:0001",
+		Version:  "1.0",
+		Binding:  "U",
+		Lifetime: time.Hour,
+		Address:  "192.168.0.1:5683",
+		Updated:  time.Now().UTC().Truncate(time.Microsecond),
+		Objects:  []string{"/1/0", "/3/0"},
+	}
+}
+
+func TestRegistrationSave(t *testing.T) {
+	repo := postgres.New(db)
+
+	invalidThing := newRegistration(otherID)
+	invalidThing.ThingID = invalid
+
+	cases := []struct {
+		desc string
+		reg  lwm2m.Registration
+		err  error
+	}{
+		{
+			desc: "save new registration",
+			reg:  newRegistration(id),
+			err:  nil,
+		},
+		{
+			desc: "save duplicate registration",
+			reg:  newRegistration(id),
+			err:  lwm2m.ErrMalformedEntity,
+		},
+		{
+			desc: "save second registration of the thing",
+			reg:  newRegistration(otherID),
+			err:  lwm2m.ErrMalformedEntity,
+		},
+		{
+			desc: "save registration with invalid thing ID",
+			reg:  invalidThing,
+			err:  lwm2m.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		err := repo.Save(context.Background(), tc.reg)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	repo.Remove(context.Background(), id)
+}
+
+func TestRegistrationUpdate(t *testing.T) {
+	repo := postgres.New(db)
+
+	reg := newRegistration(id)
+	err := repo.Save(context.Background(), reg)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	reg.Lifetime = 2 * time.Hour
+	reg.Address = "192.168.0.2:5683"
+	reg.Observed = []string{"/3303/0/5700"}
+	reg.Updated = reg.Updated.Add(time.Minute)
+
+	cases := []struct {
+		desc string
+		reg  lwm2m.Registration
+		err  error
+	}{
+		{
+			desc: "update existing registration",
+			reg:  reg,
+			err:  nil,
+		},
+		{
+			desc: "update non-existing registration",
+			reg:  newRegistration(wrongID),
+			err:  lwm2m.ErrNotFound,
+		},
+		{
+			desc: "update registration with invalid ID",
+			reg:  newRegistration(invalid),
+			err:  lwm2m.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := repo.Update(context.Background(), tc.reg)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	saved, err := repo.RetrieveByID(context.Background(), id)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.Equal(t, reg, saved, fmt.Sprintf("expected %v got %v\n", reg, saved))
+
+	repo.Remove(context.Background(), id)
+}
+
+func TestRegistrationRetrieve(t *testing.T) {
+	repo := postgres.New(db)
+
+	reg := newRegistration(id)
+	err := repo.Save(context.Background(), reg)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc     string
+		retrieve func(context.Context, string) (lwm2m.Registration, error)
+		id       string
+		err      error
+	}{
+		{
+			desc:     "retrieve registration by ID",
+			retrieve: repo.RetrieveByID,
+			id:       id,
+			err:      nil,
+		},
+		{
+			desc:     "retrieve registration by non-existing ID",
+			retrieve: repo.RetrieveByID,
+			id:       wrongID,
+			err:      lwm2m.ErrNotFound,
+		},
+		{
+			desc:     "retrieve registration by invalid ID",
+			retrieve: repo.RetrieveByID,
+			id:       invalid,
+			err:      lwm2m.ErrNotFound,
+		},
+		{
+			desc:     "retrieve registration by thing",
+			retrieve: repo.RetrieveByThing,
+			id:       thingID,
+			err:      nil,
+		},
+		{
+			desc:     "retrieve registration by unregistered thing",
+			retrieve: repo.RetrieveByThing,
+			id:       wrongID,
+			err:      lwm2m.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		saved, err := tc.retrieve(context.Background(), tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err == nil {
+			assert.Equal(t, reg, saved, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, reg, saved))
+		}
+	}
+
+	repo.Remove(context.Background(), id)
+}
+
+func TestRegistrationRemove(t *testing.T) {
+	repo := postgres.New(db)
+
+	err := repo.Save(context.Background(), newRegistration(id))
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc string
+		id   string
+	}{
+		{
+			desc: "remove existing registration",
+			id:   id,
+		},
+		{
+			desc: "remove removed registration",
+			id:   id,
+		},
+		{
+			desc: "remove registration with invalid ID",
+			id:   invalid,
+		},
+	}
+
+	for _, tc := range cases {
+		err := repo.Remove(context.Background(), tc.id)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s\n", tc.desc, err))
+	}
+
+	_, err = repo.RetrieveByID(context.Background(), id)
+	assert.Equal(t, lwm2m.ErrNotFound, err, fmt.Sprintf("expected %s got %s\n", lwm2m.ErrNotFound, err))
+}
@@ -0,0 +1,368 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package lwm2m
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/things"
+	"github.com/mainflux/mainflux/things/keys"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const protocol = "lwm2m"
+
+var (
+	// ErrMalformedEntity indicates malformed entity specification.
+	ErrMalformedEntity = errors.New("malformed entity specification")
+
+	// ErrUnauthorizedAccess indicates missing or invalid credentials provided
+	// when accessing a protected resource.
+	ErrUnauthorizedAccess = errors.New("missing or invalid credentials provided")
+
+	// ErrNotFound indicates a non-existent entity request.
+	ErrNotFound = errors.New("non-existent entity")
+
+	// ErrNotAllowed indicates that the client doesn't allow the operation
+	// on the resource.
+	ErrNotAllowed = errors.New("operation not allowed by the client")
+
+	// ErrUnreachable indicates that the client didn't respond to the
+	// request.
+	ErrUnreachable = errors.New("client unreachable")
+
+	// Paths of the object instances and resources, whose identifiers are
+	// 16-bit unsigned integers.
+	instanceRegExp = regexp.MustCompile(`^(/\d{1,5}){2,3}$`)
+	resourceRegExp = regexp.MustCompile(`^(/\d{1,5}){3}$`)
+)
+
+// Service specifies an API that must be fulfilled by the domain service
+// implementation, and all of its decorators (e.g. logging & metrics).
+type Service interface {
+	// Bootstrap authenticates the client identified by the provided thing
+	// key, and writes the account of the LwM2M server to the client at
+	// the provided address.
+	Bootstrap(context.Context, string, string) error
+
+	// Register registers the client identified by the provided thing key,
+	// which publishes the values of its resources to the channel of the
+	// registration, and returns the registration ID. The previous
+	// registration of the thing is replaced.
+	Register(context.Context, string, Registration) (string, error)
+
+	// Update updates the registration having the provided ID with the
+	// non-zero lifetime, binding, address and objects of the provided one.
+	Update(context.Context, Registration) error
+
+	// Deregister removes the registration having the provided ID.
+	Deregister(context.Context, string) error
+
+	// View retrieves the registration of the thing identified by the
+	// provided ID, that belongs to the user identified by the provided
+	// token.
+	View(context.Context, string, string) (Registration, error)
+
+	// Read reads the value of the resource or object instance, having the
+	// provided path, of the thing identified by the provided ID, that
+	// belongs to the user identified by the provided token.
+	Read(context.Context, string, string, string) (Content, error)
+
+	// Write writes the value of the resource having the provided path, of
+	// the thing identified by the provided ID, that belongs to the user
+	// identified by the provided token.
+	Write(context.Context, string, string, string, Content) error
+
+	// Observe starts publishing the values of the resource having the
+	// provided path, of the thing identified by the provided ID, that
+	// belongs to the user identified by the provided token.
+	Observe(context.Context, string, string, string) error
+
+	// CancelObservation stops publishing the values of the resource having
+	// the provided path, of the thing identified by the provided ID, that
+	// belongs to the user identified by the provided token.
+	CancelObservation(context.Context, string, string, string) error
+}
+
+var _ Service = (*lwm2mService)(nil)
+
+type lwm2mService struct {
+	auth      mainflux.ThingsServiceClient
+	things    Things
+	repo      Repository
+	devices   Devices
+	publisher mainflux.MessagePublisher
+	idp       IdentityProvider
+	account   Account
+}
+
+// New instantiates the LwM2M adapter service implementation. The provided
+// account is written to the clients on bootstrap.
+func New(auth mainflux.ThingsServiceClient, things Things, repo Repository, devices Devices, publisher mainflux.MessagePublisher, idp IdentityProvider, account Account) Service {
+	return &lwm2mService{
+		auth:      auth,
+		things:    things,
+		repo:      repo,
+		devices:   devices,
+		publisher: publisher,
+		idp:       idp,
+		account:   account,
+	}
+}
+
+func (ls *lwm2mService) Bootstrap(ctx context.Context, key, addr string) error {
+	if keys.Malformed(key) {
+		return ErrUnauthorizedAccess
+	}
+
+	if _, err := ls.auth.Identify(ctx, &mainflux.Token{Value: key}); err != nil {
+		return toAuthError(err)
+	}
+
+	// The account is written once the client receives the response to
+	// its bootstrap request.
+	go ls.devices.Bootstrap(context.Background(), addr, ls.account)
+
+	return nil
+}
+
+func (ls *lwm2mService) Register(ctx context.Context, key string, reg Registration) (string, error) {
+	if reg.Endpoint == "" || reg.Channel == "" || reg.Address == "" || reg.Lifetime < 0 {
+		return "", ErrMalformedEntity
+	}
+
+	if keys.Malformed(key) {
+		return "", ErrUnauthorizedAccess
+	}
+
+	ar := &mainflux.AccessReq{
+		Token:  key,
+		ChanID: reg.Channel,
+		Action: things.Publish,
+	}
+	thid, err := ls.auth.CanAccess(ctx, ar)
+	if err != nil {
+		return "", toAuthError(err)
+	}
+
+	prev, err := ls.repo.RetrieveByThing(ctx, thid.GetValue())
+	switch err {
+	case nil:
+		if err := ls.repo.Remove(ctx, prev.ID); err != nil {
+			return "", err
+		}
+		// Observations are kept only within the same channel.
+		if prev.Channel == reg.Channel {
+			reg.Observed = prev.Observed
+		}
+	case ErrNotFound:
+	default:
+		return "", err
+	}
+
+	id, err := ls.idp.ID()
+	if err != nil {
+		return "", err
+	}
+
+	reg.ID = id
+	reg.ThingID = thid.GetValue()
+	reg.Updated = time.Now().UTC()
+	if err := ls.repo.Save(ctx, reg); err != nil {
+		return "", err
+	}
+
+	// The observations are restored once the client receives the
+	// response to its registration.
+	go ls.restore(reg)
+
+	return id, nil
+}
+
+func (ls *lwm2mService) Update(ctx context.Context, upd Registration) error {
+	reg, err := ls.repo.RetrieveByID(ctx, upd.ID)
+	if err != nil {
+		return err
+	}
+
+	moved := upd.Address != "" && upd.Address != reg.Address
+	if upd.Lifetime > 0 {
+		reg.Lifetime = upd.Lifetime
+	}
+	if upd.Binding != "" {
+		reg.Binding = upd.Binding
+	}
+	if upd.Address != "" {
+		reg.Address = upd.Address
+	}
+	if len(upd.Objects) > 0 {
+		reg.Objects = upd.Objects
+	}
+	reg.Updated = time.Now().UTC()
+
+	if err := ls.repo.Update(ctx, reg); err != nil {
+		return err
+	}
+
+	// Observations are bound to the address of the client.
+	if moved {
+		go ls.restore(reg)
+	}
+
+	return nil
+}
+
+func (ls *lwm2mService) Deregister(ctx context.Context, id string) error {
+	reg, err := ls.repo.RetrieveByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := ls.repo.Remove(ctx, id); err != nil {
+		return err
+	}
+
+	for _, path := range reg.Observed {
+		ls.devices.Cancel(ctx, reg.Address, path)
+	}
+
+	return nil
+}
+
+func (ls *lwm2mService) View(ctx context.Context, token, thingID string) (Registration, error) {
+	if err := ls.things.Thing(token, thingID); err != nil {
+		return Registration{}, err
+	}
+
+	reg, err := ls.repo.RetrieveByThing(ctx, thingID)
+	if err != nil {
+		return Registration{}, err
+	}
+
+	if reg.Expired(time.Now()) {
+		return Registration{}, ErrNotFound
+	}
+
+	return reg, nil
+}
+
+func (ls *lwm2mService) Read(ctx context.Context, token, thingID, path string) (Content, error) {
+	if !instanceRegExp.MatchString(path) {
+		return Content{}, ErrMalformedEntity
+	}
+
+	reg, err := ls.View(ctx, token, thingID)
+	if err != nil {
+		return Content{}, err
+	}
+
+	return ls.devices.Read(ctx, reg.Address, path)
+}
+
+func (ls *lwm2mService) Write(ctx context.Context, token, thingID, path string, c Content) error {
+	if !resourceRegExp.MatchString(path) || c.Type == "" {
+		return ErrMalformedEntity
+	}
+
+	reg, err := ls.View(ctx, token, thingID)
+	if err != nil {
+		return err
+	}
+
+	return ls.devices.Write(ctx, reg.Address, path, c)
+}
+
+func (ls *lwm2mService) Observe(ctx context.Context, token, thingID, path string) error {
+	if !instanceRegExp.MatchString(path) {
+		return ErrMalformedEntity
+	}
+
+	reg, err := ls.View(ctx, token, thingID)
+	if err != nil {
+		return err
+	}
+
+	if err := ls.devices.Observe(ctx, reg.Address, path, ls.notify(reg, path)); err != nil {
+		return err
+	}
+
+	for _, p := range reg.Observed {
+		if p == path {
+			return nil
+		}
+	}
+	reg.Observed = append(reg.Observed, path)
+
+	return ls.repo.Update(ctx, reg)
+}
+
+func (ls *lwm2mService) CancelObservation(ctx context.Context, token, thingID, path string) error {
+	if !instanceRegExp.MatchString(path) {
+		return ErrMalformedEntity
+	}
+
+	reg, err := ls.View(ctx, token, thingID)
+	if err != nil {
+		return err
+	}
+
+	observed := []string{}
+	for _, p := range reg.Observed {
+		if p != path {
+			observed = append(observed, p)
+		}
+	}
+	if len(observed) == len(reg.Observed) {
+		return ErrNotFound
+	}
+	reg.Observed = observed
+
+	if err := ls.repo.Update(ctx, reg); err != nil {
+		return err
+	}
+
+	return ls.devices.Cancel(ctx, reg.Address, path)
+}
+
+// restore observes the resources observed within the previous registration
+// of the client. The resources which can't be observed are skipped, as the
+// client may not have them anymore.
+func (ls *lwm2mService) restore(reg Registration) {
+	for _, path := range reg.Observed {
+		ls.devices.Observe(context.Background(), reg.Address, path, ls.notify(reg, path))
+	}
+}
+
+// notify returns the handler publishing the values of the observed resource
+// to the registration channel. The values which fail to publish are dropped,
+// since the client doesn't wait for the outcome.
+func (ls *lwm2mService) notify(reg Registration, path string) func(Content) {
+	subtopic := strings.Replace(strings.TrimPrefix(path, "/"), "/", ".", -1)
+	return func(c Content) {
+		msg := mainflux.RawMessage{
+			Channel:     reg.Channel,
+			Subtopic:    subtopic,
+			Publisher:   reg.ThingID,
+			Protocol:    protocol,
+			ContentType: c.Type,
+			Payload:     c.Payload,
+		}
+		ls.publisher.Publish(context.Background(), "", msg)
+	}
+}
+
+func toAuthError(err error) error {
+	switch status.Code(err) {
+	case codes.PermissionDenied, codes.NotFound, codes.Unauthenticated:
+		return ErrUnauthorizedAccess
+	default:
+		return err
+	}
+}
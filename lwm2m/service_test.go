@@ -0,0 +1,523 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package lwm2m_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/lwm2m"
+	"github.com/mainflux/mainflux/lwm2m/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	thingID    = "thing"
+	thingKey   = "thing-key"
+	otherID    = "other-thing"
+	otherKey   = "other-thing-key"
+	chanID     = "channel"
+	otherChan  = "other-channel"
+	token      = "token"
+	wrongValue = "wrong-value"
+	addr       = "192.168.0.1:5683"
+	otherAddr  = "192.168.0.2:5683"
+	path       = "/3303/0/5700"
+)
+
+var (
+	account = lwm2m.Account{
+		ServerURI:     "coap://localhost:5683",
+		ShortServerID: 1,
+		Lifetime:      time.Hour,
+		Binding:       "U",
+	}
+	temperature = lwm2m.Content{Type: lwm2m.TextPlain, Payload: []byte("21.5")}
+)
+
+func newService() (lwm2m.Service, *mocks.Devices, *mocks.Publisher) {
+	auth := mocks.NewAuth(
+		map[string]string{thingKey: thingID, otherKey: otherID},
+		map[string][]string{thingID: {chanID, otherChan}},
+	)
+	things := mocks.NewThings(map[string]string{thingID: token, otherID: token})
+	devices := mocks.NewDevices()
+	devices.Add(addr, map[string]lwm2m.Content{path: temperature})
+	devices.Add(otherAddr, map[string]lwm2m.Content{path: temperature})
+	pub := mocks.NewPublisher()
+	svc := lwm2m.New(auth, things, mocks.NewRepository(), devices, pub, mocks.NewIdentityProvider(), account)
+
+	return svc, devices, pub
+}
+
+func newRegistration() lwm2m.Registration {
+	return lwm2m.Registration{
+		Endpoint: "urn:dev:os:0001",
+		Channel:  chanID,
+		Version:  "1.0",
+		Binding:  "U",
+		Lifetime: time.Hour,
+		Address:  addr,
+		Objects:  []string{"/1/0", "/3/0", "/3303/0"},
+	}
+}
+
+// waitObserved waits for the resources observed at the address in the
+// background.
+func waitObserved(devices *mocks.Devices, addr string, n int) []string {
+	for i := 0; i < 100; i++ {
+		if paths := devices.Observed(addr); len(paths) == n {
+			return paths
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return devices.Observed(addr)
+}
+
+func TestBootstrap(t *testing.T) {
+	svc, devices, _ := newService()
+
+	cases := []struct {
+		desc string
+		key  string
+		err  error
+	}{
+		{
+			desc: "bootstrap with valid key",
+			key:  thingKey,
+			err:  nil,
+		},
+		{
+			desc: "bootstrap with invalid key",
+			key:  wrongValue,
+			err:  lwm2m.ErrUnauthorizedAccess,
+		},
+		{
+			desc: "bootstrap with empty key",
+			key:  "",
+			err:  lwm2m.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.Bootstrap(context.Background(), tc.key, addr)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	var acc lwm2m.Account
+	for i := 0; i < 100; i++ {
+		var ok bool
+		if acc, ok = devices.Account(addr); ok {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, account, acc, fmt.Sprintf("expected account %v got %v\n", account, acc))
+}
+
+func TestRegister(t *testing.T) {
+	svc, _, _ := newService()
+
+	noEndpoint := newRegistration()
+	noEndpoint.Endpoint = ""
+
+	noChannel := newRegistration()
+	noChannel.Channel = ""
+
+	cases := []struct {
+		desc string
+		key  string
+		reg  lwm2m.Registration
+		err  error
+	}{
+		{
+			desc: "register client",
+			key:  thingKey,
+			reg:  newRegistration(),
+			err:  nil,
+		},
+		{
+			desc: "register client again",
+			key:  thingKey,
+			reg:  newRegistration(),
+			err:  nil,
+		},
+		{
+			desc: "register client with invalid key",
+			key:  wrongValue,
+			reg:  newRegistration(),
+			err:  lwm2m.ErrUnauthorizedAccess,
+		},
+		{
+			desc: "register client of unconnected thing",
+			key:  otherKey,
+			reg:  newRegistration(),
+			err:  lwm2m.ErrUnauthorizedAccess,
+		},
+		{
+			desc: "register client without endpoint",
+			key:  thingKey,
+			reg:  noEndpoint,
+			err:  lwm2m.ErrMalformedEntity,
+		},
+		{
+			desc: "register client without channel",
+			key:  thingKey,
+			reg:  noChannel,
+			err:  lwm2m.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		id, err := svc.Register(context.Background(), tc.key, tc.reg)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err != nil {
+			continue
+		}
+
+		reg, err := svc.View(context.Background(), token, thingID)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s\n", tc.desc, err))
+		assert.Equal(t, id, reg.ID, fmt.Sprintf("%s: expected registration %s got %s\n", tc.desc, id, reg.ID))
+		assert.Equal(t, tc.reg.Endpoint, reg.Endpoint, fmt.Sprintf("%s: expected endpoint %s got %s\n", tc.desc, tc.reg.Endpoint, reg.Endpoint))
+	}
+}
+
+func TestRegisterRestoresObservations(t *testing.T) {
+	svc, devices, _ := newService()
+
+	_, err := svc.Register(context.Background(), thingKey, newRegistration())
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	err = svc.Observe(context.Background(), token, thingID, path)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	reg := newRegistration()
+	reg.Address = otherAddr
+	_, err = svc.Register(context.Background(), thingKey, reg)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	observed := waitObserved(devices, otherAddr, 1)
+	assert.Equal(t, []string{path}, observed, fmt.Sprintf("expected observed %v got %v\n", []string{path}, observed))
+
+	reg.Channel = otherChan
+	_, err = svc.Register(context.Background(), thingKey, reg)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	saved, err := svc.View(context.Background(), token, thingID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.Empty(t, saved.Observed, fmt.Sprintf("expected no observations on channel change got %v\n", saved.Observed))
+}
+
+func TestUpdate(t *testing.T) {
+	svc, devices, _ := newService()
+
+	id, err := svc.Register(context.Background(), thingKey, newRegistration())
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	err = svc.Observe(context.Background(), token, thingID, path)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc string
+		upd  lwm2m.Registration
+		err  error
+	}{
+		{
+			desc: "update registration lifetime",
+			upd:  lwm2m.Registration{ID: id, Lifetime: 2 * time.Hour},
+			err:  nil,
+		},
+		{
+			desc: "update registration address",
+			upd:  lwm2m.Registration{ID: id, Address: otherAddr},
+			err:  nil,
+		},
+		{
+			desc: "update non-existing registration",
+			upd:  lwm2m.Registration{ID: wrongValue},
+			err:  lwm2m.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.Update(context.Background(), tc.upd)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	reg, err := svc.View(context.Background(), token, thingID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.Equal(t, 2*time.Hour, reg.Lifetime, fmt.Sprintf("expected lifetime %s got %s\n", 2*time.Hour, reg.Lifetime))
+	assert.Equal(t, otherAddr, reg.Address, fmt.Sprintf("expected address %s got %s\n", otherAddr, reg.Address))
+
+	observed := waitObserved(devices, otherAddr, 1)
+	assert.Equal(t, []string{path}, observed, fmt.Sprintf("expected observed %v got %v\n", []string{path}, observed))
+}
+
+func TestDeregister(t *testing.T) {
+	svc, devices, _ := newService()
+
+	id, err := svc.Register(context.Background(), thingKey, newRegistration())
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	err = svc.Observe(context.Background(), token, thingID, path)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc string
+		id   string
+		err  error
+	}{
+		{
+			desc: "deregister client",
+			id:   id,
+			err:  nil,
+		},
+		{
+			desc: "deregister deregistered client",
+			id:   id,
+			err:  lwm2m.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.Deregister(context.Background(), tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	observed := devices.Observed(addr)
+	assert.Empty(t, observed, fmt.Sprintf("expected no observations got %v\n", observed))
+
+	_, err = svc.View(context.Background(), token, thingID)
+	assert.Equal(t, lwm2m.ErrNotFound, err, fmt.Sprintf("expected %s got %s\n", lwm2m.ErrNotFound, err))
+}
+
+func TestView(t *testing.T) {
+	svc, _, _ := newService()
+
+	_, err := svc.Register(context.Background(), thingKey, newRegistration())
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc  string
+		token string
+		id    string
+		err   error
+	}{
+		{
+			desc:  "view registration",
+			token: token,
+			id:    thingID,
+			err:   nil,
+		},
+		{
+			desc:  "view registration with invalid token",
+			token: wrongValue,
+			id:    thingID,
+			err:   lwm2m.ErrNotFound,
+		},
+		{
+			desc:  "view registration with empty token",
+			token: "",
+			id:    thingID,
+			err:   lwm2m.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "view registration of unregistered thing",
+			token: token,
+			id:    otherID,
+			err:   lwm2m.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := svc.View(context.Background(), tc.token, tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestRead(t *testing.T) {
+	svc, _, _ := newService()
+
+	_, err := svc.Register(context.Background(), thingKey, newRegistration())
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc    string
+		token   string
+		path    string
+		content lwm2m.Content
+		err     error
+	}{
+		{
+			desc:    "read resource",
+			token:   token,
+			path:    path,
+			content: temperature,
+			err:     nil,
+		},
+		{
+			desc:  "read non-existing resource",
+			token: token,
+			path:  "/3303/0/5701",
+			err:   lwm2m.ErrNotFound,
+		},
+		{
+			desc:  "read resource with malformed path",
+			token: token,
+			path:  "/3303",
+			err:   lwm2m.ErrMalformedEntity,
+		},
+		{
+			desc:  "read resource with invalid token",
+			token: wrongValue,
+			path:  path,
+			err:   lwm2m.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		c, err := svc.Read(context.Background(), tc.token, thingID, tc.path)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.content, c, fmt.Sprintf("%s: expected content %v got %v\n", tc.desc, tc.content, c))
+	}
+}
+
+func TestWrite(t *testing.T) {
+	svc, devices, _ := newService()
+
+	_, err := svc.Register(context.Background(), thingKey, newRegistration())
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	value := lwm2m.Content{Type: lwm2m.TextPlain, Payload: []byte("25")}
+
+	cases := []struct {
+		desc    string
+		path    string
+		content lwm2m.Content
+		err     error
+	}{
+		{
+			desc:    "write resource",
+			path:    path,
+			content: value,
+			err:     nil,
+		},
+		{
+			desc:    "write non-existing resource",
+			path:    "/3303/0/5701",
+			content: value,
+			err:     lwm2m.ErrNotFound,
+		},
+		{
+			desc:    "write object instance",
+			path:    "/3303/0",
+			content: value,
+			err:     lwm2m.ErrMalformedEntity,
+		},
+		{
+			desc:    "write resource without content type",
+			path:    path,
+			content: lwm2m.Content{Payload: value.Payload},
+			err:     lwm2m.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.Write(context.Background(), token, thingID, tc.path, tc.content)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	c, err := devices.Read(context.Background(), addr, path)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.Equal(t, value, c, fmt.Sprintf("expected content %v got %v\n", value, c))
+}
+
+func TestObserve(t *testing.T) {
+	svc, devices, pub := newService()
+
+	_, err := svc.Register(context.Background(), thingKey, newRegistration())
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc string
+		path string
+		err  error
+	}{
+		{
+			desc: "observe resource",
+			path: path,
+			err:  nil,
+		},
+		{
+			desc: "observe observed resource",
+			path: path,
+			err:  nil,
+		},
+		{
+			desc: "observe non-existing resource",
+			path: "/3303/0/5701",
+			err:  lwm2m.ErrNotFound,
+		},
+		{
+			desc: "observe resource with malformed path",
+			path: "3303/0/5700",
+			err:  lwm2m.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.Observe(context.Background(), token, thingID, tc.path)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	reg, err := svc.View(context.Background(), token, thingID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.Equal(t, []string{path}, reg.Observed, fmt.Sprintf("expected observed %v got %v\n", []string{path}, reg.Observed))
+
+	changed := lwm2m.Content{Type: lwm2m.TextPlain, Payload: []byte("22")}
+	devices.Change(addr, path, changed)
+
+	msgs := pub.Messages()
+	require.Len(t, msgs, 3, fmt.Sprintf("expected 3 messages got %d\n", len(msgs)))
+	msg := msgs[2]
+	assert.Equal(t, chanID, msg.Channel, fmt.Sprintf("expected channel %s got %s\n", chanID, msg.Channel))
+	assert.Equal(t, "3303.0.5700", msg.Subtopic, fmt.Sprintf("expected subtopic 3303.0.5700 got %s\n", msg.Subtopic))
+	assert.Equal(t, thingID, msg.Publisher, fmt.Sprintf("expected publisher %s got %s\n", thingID, msg.Publisher))
+	assert.Equal(t, changed.Type, msg.ContentType, fmt.Sprintf("expected content type %s got %s\n", changed.Type, msg.ContentType))
+	assert.Equal(t, changed.Payload, msg.Payload, fmt.Sprintf("expected payload %s got %s\n", changed.Payload, msg.Payload))
+}
+
+func TestCancelObservation(t *testing.T) {
+	svc, devices, _ := newService()
+
+	_, err := svc.Register(context.Background(), thingKey, newRegistration())
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	err = svc.Observe(context.Background(), token, thingID, path)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc string
+		path string
+		err  error
+	}{
+		{
+			desc: "cancel observation",
+			path: path,
+			err:  nil,
+		},
+		{
+			desc: "cancel cancelled observation",
+			path: path,
+			err:  lwm2m.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.CancelObservation(context.Background(), token, thingID, tc.path)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	observed := devices.Observed(addr)
+	assert.Empty(t, observed, fmt.Sprintf("expected no observations got %v\n", observed))
+}
@@ -0,0 +1,6 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package things contains the things API client which checks the ownership
+// of the things through the Mainflux SDK.
+package things
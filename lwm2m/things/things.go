@@ -0,0 +1,34 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package things
+
+import (
+	"github.com/mainflux/mainflux/lwm2m"
+	mfsdk "github.com/mainflux/mainflux/sdk/go"
+)
+
+var _ lwm2m.Things = (*things)(nil)
+
+type things struct {
+	sdk mfsdk.SDK
+}
+
+// New returns things API client backed by the provided SDK.
+func New(sdk mfsdk.SDK) lwm2m.Things {
+	return things{sdk: sdk}
+}
+
+func (t things) Thing(token, id string) error {
+	_, err := t.sdk.Thing(id, token)
+	switch err {
+	case nil:
+		return nil
+	case mfsdk.ErrUnauthorized:
+		return lwm2m.ErrUnauthorizedAccess
+	case mfsdk.ErrNotFound:
+		return lwm2m.ErrNotFound
+	default:
+		return err
+	}
+}
@@ -24,15 +24,18 @@ const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
 // RawMessage represents a message emitted by the Mainflux adapters layer.
 type RawMessage struct {
-	Channel              string   `protobuf:"bytes,1,opt,name=channel,proto3" json:"channel,omitempty"`
-	Subtopic             string   `protobuf:"bytes,2,opt,name=subtopic,proto3" json:"subtopic,omitempty"`
-	Publisher            string   `protobuf:"bytes,3,opt,name=publisher,proto3" json:"publisher,omitempty"`
-	Protocol             string   `protobuf:"bytes,4,opt,name=protocol,proto3" json:"protocol,omitempty"`
-	ContentType          string   `protobuf:"bytes,5,opt,name=contentType,proto3" json:"contentType,omitempty"`
-	Payload              []byte   `protobuf:"bytes,6,opt,name=payload,proto3" json:"payload,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Channel              string            `protobuf:"bytes,1,opt,name=channel,proto3" json:"channel,omitempty"`
+	Subtopic             string            `protobuf:"bytes,2,opt,name=subtopic,proto3" json:"subtopic,omitempty"`
+	Publisher            string            `protobuf:"bytes,3,opt,name=publisher,proto3" json:"publisher,omitempty"`
+	Protocol             string            `protobuf:"bytes,4,opt,name=protocol,proto3" json:"protocol,omitempty"`
+	ContentType          string            `protobuf:"bytes,5,opt,name=contentType,proto3" json:"contentType,omitempty"`
+	Payload              []byte            `protobuf:"bytes,6,opt,name=payload,proto3" json:"payload,omitempty"`
+	Sequence             uint64            `protobuf:"varint,7,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Metadata             map[string]string `protobuf:"bytes,8,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	CorrelationID        string            `protobuf:"bytes,9,opt,name=correlationID,proto3" json:"correlationID,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
 }
 
 func (m *RawMessage) Reset()         { *m = RawMessage{} }
@@ -110,6 +113,27 @@ func (m *RawMessage) GetPayload() []byte {
 	return nil
 }
 
+func (m *RawMessage) GetSequence() uint64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
+func (m *RawMessage) GetMetadata() map[string]string {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+func (m *RawMessage) GetCorrelationID() string {
+	if m != nil {
+		return m.CorrelationID
+	}
+	return ""
+}
+
 // Message represents a resolved (normalized) raw message.
 type Message struct {
 	Channel   string `protobuf:"bytes,1,opt,name=channel,proto3" json:"channel,omitempty"`
@@ -128,6 +152,7 @@ type Message struct {
 	Time                 float64         `protobuf:"fixed64,12,opt,name=time,proto3" json:"time,omitempty"`
 	UpdateTime           float64         `protobuf:"fixed64,13,opt,name=updateTime,proto3" json:"updateTime,omitempty"`
 	Link                 string          `protobuf:"bytes,14,opt,name=link,proto3" json:"link,omitempty"`
+	Sequence             uint64          `protobuf:"varint,15,opt,name=sequence,proto3" json:"sequence,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
 	XXX_unrecognized     []byte          `json:"-"`
 	XXX_sizecache        int32           `json:"-"`
@@ -295,6 +320,13 @@ func (m *Message) GetLink() string {
 	return ""
 }
 
+func (m *Message) GetSequence() uint64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
 // XXX_OneofFuncs is for the internal use of the proto package.
 func (*Message) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
 	return _Message_OneofMarshaler, _Message_OneofUnmarshaler, _Message_OneofSizer, []interface{}{
@@ -443,6 +475,7 @@ func (m *SumValue) GetValue() float64 {
 
 func init() {
 	proto.RegisterType((*RawMessage)(nil), "mainflux.RawMessage")
+	proto.RegisterMapType((map[string]string)(nil), "mainflux.RawMessage.MetadataEntry")
 	proto.RegisterType((*Message)(nil), "mainflux.Message")
 	proto.RegisterType((*SumValue)(nil), "mainflux.SumValue")
 }
@@ -450,30 +483,36 @@ func init() {
 func init() { proto.RegisterFile("message.proto", fileDescriptor_33c57e4bae7b9afd) }
 
 var fileDescriptor_33c57e4bae7b9afd = []byte{
-	// 368 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x92, 0x4f, 0x8e, 0xda, 0x30,
-	0x18, 0xc5, 0xe3, 0xf2, 0x27, 0xc9, 0x17, 0xa8, 0x2a, 0xab, 0x0b, 0xab, 0xaa, 0x22, 0x2b, 0xab,
-	0xac, 0xb2, 0x68, 0x6f, 0xc0, 0x8a, 0x4d, 0x37, 0x06, 0x75, 0xef, 0x04, 0x03, 0x51, 0x1d, 0x3b,
-	0x22, 0x76, 0x5b, 0x6e, 0x32, 0x47, 0x98, 0x23, 0xcc, 0x11, 0x66, 0x39, 0x47, 0x18, 0x31, 0x17,
-	0x19, 0xd9, 0x21, 0x90, 0x1b, 0xcc, 0xee, 0xbd, 0xdf, 0xfb, 0x3e, 0xfb, 0x19, 0x02, 0xcb, 0x46,
-	0x74, 0x1d, 0x3f, 0x88, 0xa2, 0x3d, 0x69, 0xa3, 0x71, 0xd4, 0xf0, 0x5a, 0xed, 0xa5, 0xfd, 0x9f,
-	0x3d, 0x21, 0x00, 0xc6, 0xff, 0xfd, 0xea, 0x63, 0x4c, 0x20, 0xac, 0x8e, 0x5c, 0x29, 0x21, 0x09,
-	0xa2, 0x28, 0x8f, 0xd9, 0x60, 0xf1, 0x37, 0x88, 0x3a, 0x5b, 0x1a, 0xdd, 0xd6, 0x15, 0xf9, 0xe4,
-	0xa3, 0x9b, 0xc7, 0xdf, 0x21, 0x6e, 0x6d, 0x29, 0xeb, 0xee, 0x28, 0x4e, 0x64, 0xe2, 0xc3, 0x3b,
-	0x70, 0x9b, 0xfe, 0xd6, 0x4a, 0x4b, 0x32, 0xed, 0x37, 0x07, 0x8f, 0x29, 0x24, 0x95, 0x56, 0x46,
-	0x28, 0xb3, 0x3d, 0xb7, 0x82, 0xcc, 0x7c, 0x3c, 0x46, 0xae, 0x51, 0xcb, 0xcf, 0x52, 0xf3, 0x1d,
-	0x99, 0x53, 0x94, 0x2f, 0xd8, 0x60, 0xb3, 0xc7, 0x09, 0x84, 0x1f, 0xd5, 0x1b, 0xc3, 0x54, 0xf1,
-	0x66, 0x28, 0xec, 0xb5, 0x63, 0x56, 0xd5, 0xc6, 0xd7, 0x8c, 0x99, 0xd7, 0x98, 0x02, 0xec, 0xa5,
-	0xe6, 0xe6, 0x37, 0x97, 0x56, 0x90, 0x90, 0xa2, 0x1c, 0xad, 0x03, 0x36, 0x62, 0x38, 0x83, 0xa4,
-	0x33, 0xa7, 0x5a, 0x1d, 0xfa, 0x91, 0xc8, 0x2d, 0xaf, 0x03, 0x36, 0x86, 0x38, 0x85, 0xb8, 0xd4,
-	0x5a, 0xf6, 0x13, 0x31, 0x45, 0x79, 0xb4, 0x0e, 0xd8, 0x1d, 0xb9, 0x7c, 0xc7, 0x0d, 0xef, 0x73,
-	0xb8, 0x9e, 0x70, 0x47, 0xb8, 0x80, 0xe8, 0xaf, 0x13, 0x1b, 0xdb, 0x90, 0x84, 0xa2, 0x3c, 0xf9,
-	0x81, 0x8b, 0xe1, 0x0b, 0x28, 0x36, 0xb6, 0xf1, 0x53, 0xec, 0x36, 0xe3, 0x5e, 0x62, 0xea, 0x46,
-	0x90, 0x85, 0xeb, 0xcb, 0xbc, 0xc6, 0x29, 0x80, 0x6d, 0x77, 0xdc, 0x88, 0xad, 0x4b, 0x96, 0x3e,
-	0x19, 0x11, 0xb7, 0x23, 0x6b, 0xf5, 0x87, 0x7c, 0xee, 0x5f, 0xef, 0xf4, 0x2a, 0x84, 0x99, 0x3f,
-	0x33, 0xa3, 0x10, 0x0d, 0xd7, 0xe0, 0xaf, 0x57, 0xe8, 0xff, 0x28, 0xc4, 0x7a, 0xb3, 0xfa, 0xf2,
-	0x7c, 0x49, 0xd1, 0xcb, 0x25, 0x45, 0xaf, 0x97, 0x14, 0x3d, 0xbc, 0xa5, 0x41, 0x39, 0xf7, 0x3f,
-	0xf6, 0xcf, 0xf7, 0x00, 0x00, 0x00, 0xff, 0xff, 0x21, 0x23, 0xc7, 0x3c, 0xbb, 0x02, 0x00, 0x00,
+	// 458 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x52, 0xb1, 0x6e, 0xdb, 0x30,
+	0x10, 0x35, 0x6d, 0x27, 0x96, 0x4e, 0x71, 0x1b, 0x10, 0x1d, 0x88, 0xa0, 0x10, 0x04, 0xa1, 0x83,
+	0x26, 0x0d, 0xe9, 0x52, 0xb4, 0x40, 0x87, 0xa0, 0x05, 0xdc, 0x21, 0x0b, 0x13, 0x74, 0xa7, 0x65,
+	0x26, 0x11, 0x42, 0x91, 0xaa, 0x44, 0xb6, 0xf5, 0x9f, 0xf4, 0x83, 0x3a, 0x74, 0xec, 0x27, 0x14,
+	0xee, 0x47, 0x74, 0x2d, 0x78, 0x92, 0x2c, 0xfb, 0x0b, 0xb2, 0xdd, 0x7b, 0xef, 0xee, 0x78, 0xf7,
+	0x8e, 0xb0, 0xac, 0x64, 0xdb, 0x8a, 0x7b, 0x99, 0xd7, 0x8d, 0xb1, 0x86, 0x06, 0x95, 0x28, 0xf5,
+	0x9d, 0x72, 0xdf, 0xd3, 0x7f, 0x53, 0x00, 0x2e, 0xbe, 0x5d, 0x77, 0x32, 0x65, 0xb0, 0x28, 0x1e,
+	0x84, 0xd6, 0x52, 0x31, 0x92, 0x90, 0x2c, 0xe4, 0x03, 0xa4, 0x17, 0x10, 0xb4, 0x6e, 0x6d, 0x4d,
+	0x5d, 0x16, 0x6c, 0x8a, 0xd2, 0x1e, 0xd3, 0x97, 0x10, 0xd6, 0x6e, 0xad, 0xca, 0xf6, 0x41, 0x36,
+	0x6c, 0x86, 0xe2, 0x48, 0xf8, 0x4a, 0x7c, 0xb5, 0x30, 0x8a, 0xcd, 0xbb, 0xca, 0x01, 0xd3, 0x04,
+	0xa2, 0xc2, 0x68, 0x2b, 0xb5, 0xbd, 0xdd, 0xd6, 0x92, 0x9d, 0xa0, 0x7c, 0x48, 0xf9, 0x89, 0x6a,
+	0xb1, 0x55, 0x46, 0x6c, 0xd8, 0x69, 0x42, 0xb2, 0x33, 0x3e, 0x40, 0x9c, 0x48, 0x7e, 0x71, 0x52,
+	0x17, 0x92, 0x2d, 0x12, 0x92, 0xcd, 0xf9, 0x1e, 0xd3, 0xf7, 0x10, 0x54, 0xd2, 0x8a, 0x8d, 0xb0,
+	0x82, 0x05, 0xc9, 0x2c, 0x8b, 0x2e, 0xd3, 0x7c, 0xd8, 0x39, 0x1f, 0xf7, 0xcd, 0xaf, 0xfb, 0xa4,
+	0x8f, 0xda, 0x36, 0x5b, 0xbe, 0xaf, 0xa1, 0xaf, 0x60, 0x59, 0x98, 0xa6, 0x91, 0x4a, 0xd8, 0xd2,
+	0xe8, 0x4f, 0x1f, 0x58, 0x88, 0x93, 0x1d, 0x93, 0x17, 0xef, 0x60, 0x79, 0xd4, 0x80, 0x9e, 0xc3,
+	0xec, 0x51, 0x6e, 0x7b, 0xeb, 0x7c, 0x48, 0x5f, 0xc0, 0xc9, 0x57, 0xa1, 0x9c, 0xec, 0x3d, 0xeb,
+	0xc0, 0xdb, 0xe9, 0x1b, 0x92, 0xfe, 0x9c, 0xc1, 0xe2, 0xa9, 0x6c, 0xa7, 0x30, 0xd7, 0xa2, 0x1a,
+	0xfc, 0xc6, 0xd8, 0x73, 0x4e, 0x97, 0x16, 0x5d, 0x0e, 0x39, 0xc6, 0x34, 0x01, 0xb8, 0x53, 0x46,
+	0xd8, 0xcf, 0xb8, 0x82, 0x37, 0x99, 0xac, 0x26, 0xfc, 0x80, 0xa3, 0x29, 0x44, 0xad, 0x6d, 0x4a,
+	0x7d, 0xdf, 0xa5, 0x04, 0xbe, 0x78, 0x35, 0xe1, 0x87, 0x24, 0x8d, 0x21, 0x5c, 0x1b, 0xa3, 0xba,
+	0x0c, 0x6f, 0x64, 0xb0, 0x9a, 0xf0, 0x91, 0xf2, 0xba, 0xb7, 0xb0, 0xd3, 0xa1, 0xef, 0x30, 0x52,
+	0x34, 0x87, 0x00, 0x6d, 0xbb, 0x71, 0x15, 0x8b, 0x12, 0x92, 0x45, 0x97, 0x74, 0x3c, 0xe6, 0x8d,
+	0xab, 0x30, 0x8b, 0xef, 0x73, 0xfc, 0x26, 0xb6, 0xac, 0x24, 0x3b, 0xf3, 0xf3, 0x72, 0x8c, 0x69,
+	0x0c, 0xe0, 0xea, 0x8d, 0xb0, 0xf2, 0xd6, 0x2b, 0x4b, 0x54, 0x0e, 0x18, 0x5f, 0xa3, 0x4a, 0xfd,
+	0xc8, 0x9e, 0x75, 0xdb, 0xfb, 0xf8, 0xe8, 0x83, 0x3d, 0x3f, 0xfe, 0x60, 0x57, 0x8b, 0xfe, 0xae,
+	0x69, 0x02, 0xc1, 0x30, 0xc2, 0x78, 0x6c, 0x82, 0xfd, 0x3b, 0x70, 0x75, 0xfe, 0x6b, 0x17, 0x93,
+	0xdf, 0xbb, 0x98, 0xfc, 0xd9, 0xc5, 0xe4, 0xc7, 0xdf, 0x78, 0xb2, 0x3e, 0xc5, 0x43, 0xbc, 0xfe,
+	0x3f, 0x00, 0x04, 0x3c, 0x36, 0x0b, 0x96, 0x03, 0x00, 0x00,
 }
 
 func (m *RawMessage) Marshal() (dAtA []byte, err error) {
@@ -527,6 +566,34 @@ func (m *RawMessage) MarshalTo(dAtA []byte) (int, error) {
 		i = encodeVarintMessage(dAtA, i, uint64(len(m.Payload)))
 		i += copy(dAtA[i:], m.Payload)
 	}
+	if m.Sequence != 0 {
+		dAtA[i] = 0x38
+		i++
+		i = encodeVarintMessage(dAtA, i, uint64(m.Sequence))
+	}
+	if len(m.Metadata) > 0 {
+		for k, _ := range m.Metadata {
+			dAtA[i] = 0x42
+			i++
+			v := m.Metadata[k]
+			mapSize := 1 + len(k) + sovMessage(uint64(len(k))) + 1 + len(v) + sovMessage(uint64(len(v)))
+			i = encodeVarintMessage(dAtA, i, uint64(mapSize))
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintMessage(dAtA, i, uint64(len(k)))
+			i += copy(dAtA[i:], k)
+			dAtA[i] = 0x12
+			i++
+			i = encodeVarintMessage(dAtA, i, uint64(len(v)))
+			i += copy(dAtA[i:], v)
+		}
+	}
+	if len(m.CorrelationID) > 0 {
+		dAtA[i] = 0x4a
+		i++
+		i = encodeVarintMessage(dAtA, i, uint64(len(m.CorrelationID)))
+		i += copy(dAtA[i:], m.CorrelationID)
+	}
 	if m.XXX_unrecognized != nil {
 		i += copy(dAtA[i:], m.XXX_unrecognized)
 	}
@@ -619,6 +686,11 @@ func (m *Message) MarshalTo(dAtA []byte) (int, error) {
 		i = encodeVarintMessage(dAtA, i, uint64(len(m.Link)))
 		i += copy(dAtA[i:], m.Link)
 	}
+	if m.Sequence != 0 {
+		dAtA[i] = 0x78
+		i++
+		i = encodeVarintMessage(dAtA, i, uint64(m.Sequence))
+	}
 	if m.XXX_unrecognized != nil {
 		i += copy(dAtA[i:], m.XXX_unrecognized)
 	}
@@ -727,6 +799,21 @@ func (m *RawMessage) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovMessage(uint64(l))
 	}
+	if m.Sequence != 0 {
+		n += 1 + sovMessage(uint64(m.Sequence))
+	}
+	if len(m.Metadata) > 0 {
+		for k, v := range m.Metadata {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovMessage(uint64(len(k))) + 1 + len(v) + sovMessage(uint64(len(v)))
+			n += mapEntrySize + 1 + sovMessage(uint64(mapEntrySize))
+		}
+	}
+	l = len(m.CorrelationID)
+	if l > 0 {
+		n += 1 + l + sovMessage(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -780,6 +867,9 @@ func (m *Message) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovMessage(uint64(l))
 	}
+	if m.Sequence != 0 {
+		n += 1 + sovMessage(uint64(m.Sequence))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -1075,6 +1165,184 @@ func (m *RawMessage) Unmarshal(dAtA []byte) error {
 				m.Payload = []byte{}
 			}
 			iNdEx = postIndex
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sequence", wireType)
+			}
+			m.Sequence = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Sequence |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Metadata", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMessage
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMessage
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Metadata == nil {
+				m.Metadata = make(map[string]string)
+			}
+			var mapkey string
+			var mapvalue string
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowMessage
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowMessage
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthMessage
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthMessage
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var stringLenmapvalue uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowMessage
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapvalue |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapvalue := int(stringLenmapvalue)
+					if intStringLenmapvalue < 0 {
+						return ErrInvalidLengthMessage
+					}
+					postStringIndexmapvalue := iNdEx + intStringLenmapvalue
+					if postStringIndexmapvalue < 0 {
+						return ErrInvalidLengthMessage
+					}
+					if postStringIndexmapvalue > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = string(dAtA[iNdEx:postStringIndexmapvalue])
+					iNdEx = postStringIndexmapvalue
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipMessage(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if skippy < 0 {
+						return ErrInvalidLengthMessage
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Metadata[mapkey] = mapvalue
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CorrelationID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMessage
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMessage
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.CorrelationID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipMessage(dAtA[iNdEx:])
@@ -1507,6 +1775,25 @@ func (m *Message) Unmarshal(dAtA []byte) error {
 			}
 			m.Link = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 15:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sequence", wireType)
+			}
+			m.Sequence = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Sequence |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipMessage(dAtA[iNdEx:])
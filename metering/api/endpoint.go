@@ -0,0 +1,40 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/mainflux/mainflux/metering"
+)
+
+func viewUsageEndpoint(svc metering.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewUsageReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		usage, err := svc.ViewUsage(ctx, req.token, req.from, req.to)
+		if err != nil {
+			return nil, err
+		}
+
+		res := usageRes{Usage: []hourRes{}}
+		for _, u := range usage {
+			res.Usage = append(res.Usage, hourRes{
+				Channel:    u.Channel,
+				Publisher:  u.Publisher,
+				Hour:       u.Hour,
+				Messages:   u.Messages,
+				Stored:     u.Stored,
+				Reconciled: u.Reconciled,
+			})
+		}
+
+		return res, nil
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// +build !test
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	log "github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/metering"
+)
+
+var _ metering.Service = (*loggingMiddleware)(nil)
+
+type loggingMiddleware struct {
+	logger log.Logger
+	svc    metering.Service
+}
+
+// LoggingMiddleware adds logging facilities to the core service.
+func LoggingMiddleware(svc metering.Service, logger log.Logger) metering.Service {
+	return &loggingMiddleware{logger, svc}
+}
+
+func (lm *loggingMiddleware) Count(ctx context.Context, id string, msg mainflux.Message) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method count for message %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Count(ctx, id, msg)
+}
+
+func (lm *loggingMiddleware) Forget(ctx context.Context) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method forget took %s to complete", time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Forget(ctx)
+}
+
+func (lm *loggingMiddleware) Reconcile(ctx context.Context, before time.Time) (usage []metering.Usage, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method reconcile for hours before %s took %s to complete", before, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Reconcile(ctx, before)
+}
+
+func (lm *loggingMiddleware) ViewUsage(ctx context.Context, token string, from, to time.Time) (usage []metering.Usage, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method view_usage for token %s took %s to complete", token, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ViewUsage(ctx, token, from, to)
+}
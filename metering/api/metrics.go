@@ -0,0 +1,69 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// +build !test
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/metering"
+)
+
+var _ metering.Service = (*metricsMiddleware)(nil)
+
+type metricsMiddleware struct {
+	counter metrics.Counter
+	latency metrics.Histogram
+	svc     metering.Service
+}
+
+// MetricsMiddleware instruments core service by tracking request count and
+// latency.
+func MetricsMiddleware(svc metering.Service, counter metrics.Counter, latency metrics.Histogram) metering.Service {
+	return &metricsMiddleware{
+		counter: counter,
+		latency: latency,
+		svc:     svc,
+	}
+}
+
+func (ms *metricsMiddleware) Count(ctx context.Context, id string, msg mainflux.Message) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "count").Add(1)
+		ms.latency.With("method", "count").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Count(ctx, id, msg)
+}
+
+func (ms *metricsMiddleware) Forget(ctx context.Context) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "forget").Add(1)
+		ms.latency.With("method", "forget").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Forget(ctx)
+}
+
+func (ms *metricsMiddleware) Reconcile(ctx context.Context, before time.Time) ([]metering.Usage, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "reconcile").Add(1)
+		ms.latency.With("method", "reconcile").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Reconcile(ctx, before)
+}
+
+func (ms *metricsMiddleware) ViewUsage(ctx context.Context, token string, from, to time.Time) ([]metering.Usage, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_usage").Add(1)
+		ms.latency.With("method", "view_usage").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewUsage(ctx, token, from, to)
+}
@@ -0,0 +1,28 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"time"
+
+	"github.com/mainflux/mainflux/metering"
+)
+
+type apiReq interface {
+	validate() error
+}
+
+type viewUsageReq struct {
+	token string
+	from  time.Time
+	to    time.Time
+}
+
+func (req viewUsageReq) validate() error {
+	if req.token == "" {
+		return metering.ErrUnauthorizedAccess
+	}
+
+	return nil
+}
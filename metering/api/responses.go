@@ -0,0 +1,38 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/mainflux/mainflux"
+)
+
+var _ mainflux.Response = (*usageRes)(nil)
+
+type hourRes struct {
+	Channel    string    `json:"channel"`
+	Publisher  string    `json:"publisher"`
+	Hour       time.Time `json:"hour"`
+	Messages   uint64    `json:"messages"`
+	Stored     uint64    `json:"stored,omitempty"`
+	Reconciled bool      `json:"reconciled"`
+}
+
+type usageRes struct {
+	Usage []hourRes `json:"usage"`
+}
+
+func (res usageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res usageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res usageRes) Empty() bool {
+	return false
+}
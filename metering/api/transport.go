@@ -0,0 +1,122 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/go-zoo/bone"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/metering"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	contentType = "application/json"
+
+	from = "from"
+	to   = "to"
+
+	defUsageRange = 24 * time.Hour
+)
+
+var errInvalidQueryParams = errors.New("invalid query params")
+
+// MakeHandler returns a HTTP handler for API endpoints.
+func MakeHandler(svc metering.Service) http.Handler {
+	opts := []kithttp.ServerOption{
+		kithttp.ServerErrorEncoder(encodeError),
+	}
+
+	r := bone.New()
+
+	r.Get("/usage", kithttp.NewServer(
+		viewUsageEndpoint(svc),
+		decodeViewUsage,
+		encodeResponse,
+		opts...,
+	))
+
+	r.GetFunc("/version", mainflux.Version("metering"))
+	r.GetFunc(mainflux.DiscoveryPath, mainflux.Discovery("metering", mainflux.Capabilities{}))
+	r.Handle("/metrics", promhttp.Handler())
+
+	return r
+}
+
+// decodeViewUsage reads the time range of the usage. By default, the usage
+// of the last day, including the current hour, is viewed.
+func decodeViewUsage(_ context.Context, r *http.Request) (interface{}, error) {
+	t, err := readTimeQuery(r, to, time.Now().UTC().Truncate(time.Hour).Add(time.Hour))
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := readTimeQuery(r, from, t.Add(-defUsageRange))
+	if err != nil {
+		return nil, err
+	}
+
+	req := viewUsageReq{
+		token: r.Header.Get("Authorization"),
+		from:  f,
+		to:    t,
+	}
+
+	return req, nil
+}
+
+func readTimeQuery(r *http.Request, key string, def time.Time) (time.Time, error) {
+	vals := bone.GetQuery(r, key)
+	if len(vals) > 1 {
+		return time.Time{}, errInvalidQueryParams
+	}
+
+	if len(vals) == 0 {
+		return def, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, vals[0])
+	if err != nil {
+		return time.Time{}, errInvalidQueryParams
+	}
+
+	return t, nil
+}
+
+func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", contentType)
+
+	if ar, ok := response.(mainflux.Response); ok {
+		for k, v := range ar.Headers() {
+			w.Header().Set(k, v)
+		}
+
+		w.WriteHeader(ar.Code())
+
+		if ar.Empty() {
+			return nil
+		}
+	}
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+	w.Header().Set("Content-Type", contentType)
+
+	switch err {
+	case metering.ErrMalformedEntity, errInvalidQueryParams:
+		w.WriteHeader(http.StatusBadRequest)
+	case metering.ErrUnauthorizedAccess:
+		w.WriteHeader(http.StatusForbidden)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
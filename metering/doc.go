@@ -0,0 +1,9 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package metering contains the domain concept definitions needed to support
+// Mainflux metering service functionality. Metering service counts the
+// messages published by each thing to each channel per hour, exactly once
+// within the dedupe window, and reconciles the counts against the messages
+// saved by the writer, so that the usage reports can be used for billing.
+package metering
@@ -0,0 +1,37 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/metering"
+)
+
+var _ metering.MessageStore = (*messageStoreMock)(nil)
+
+type messageStoreMock struct {
+	messages []mainflux.Message
+}
+
+// NewMessageStore returns message store mock counting the given messages.
+func NewMessageStore(messages []mainflux.Message) metering.MessageStore {
+	return messageStoreMock{
+		messages: messages,
+	}
+}
+
+func (msm messageStoreMock) Count(_ context.Context, channel, publisher string, from, to time.Time) (uint64, error) {
+	var cnt uint64
+	for _, msg := range msm.messages {
+		t := time.Unix(0, int64(msg.Time*float64(time.Second)))
+		if msg.Channel == channel && msg.Publisher == publisher && !t.Before(from) && t.Before(to) {
+			cnt++
+		}
+	}
+
+	return cnt, nil
+}
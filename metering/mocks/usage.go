@@ -0,0 +1,126 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mainflux/mainflux/metering"
+)
+
+var _ metering.UsageRepository = (*usageRepositoryMock)(nil)
+
+type usageRepositoryMock struct {
+	mu      sync.Mutex
+	counted map[string]time.Time
+	usage   map[string]metering.Usage
+}
+
+// NewUsageRepository creates in-memory usage repository.
+func NewUsageRepository() metering.UsageRepository {
+	return &usageRepositoryMock{
+		counted: make(map[string]time.Time),
+		usage:   make(map[string]metering.Usage),
+	}
+}
+
+func (urm *usageRepositoryMock) Count(_ context.Context, id string, u metering.Usage) (bool, error) {
+	urm.mu.Lock()
+	defer urm.mu.Unlock()
+
+	if _, ok := urm.counted[id]; ok {
+		return false, nil
+	}
+	urm.counted[id] = time.Now()
+
+	k := key(u)
+	saved, ok := urm.usage[k]
+	if !ok {
+		saved = u
+	}
+	saved.Messages++
+	saved.Reconciled = false
+	urm.usage[k] = saved
+
+	return true, nil
+}
+
+func (urm *usageRepositoryMock) Forget(_ context.Context, before time.Time) error {
+	urm.mu.Lock()
+	defer urm.mu.Unlock()
+
+	for id, t := range urm.counted {
+		if t.Before(before) {
+			delete(urm.counted, id)
+		}
+	}
+
+	return nil
+}
+
+func (urm *usageRepositoryMock) RetrieveUnreconciled(_ context.Context, before time.Time, limit uint64) ([]metering.Usage, error) {
+	urm.mu.Lock()
+	defer urm.mu.Unlock()
+
+	usage := []metering.Usage{}
+	for _, u := range urm.usage {
+		if !u.Reconciled && !u.Hour.Add(time.Hour).After(before) {
+			usage = append(usage, u)
+		}
+	}
+
+	sortUsage(usage)
+	if uint64(len(usage)) > limit {
+		usage = usage[:limit]
+	}
+
+	return usage, nil
+}
+
+func (urm *usageRepositoryMock) Reconcile(_ context.Context, u metering.Usage) error {
+	urm.mu.Lock()
+	defer urm.mu.Unlock()
+
+	k := key(u)
+	saved, ok := urm.usage[k]
+	if !ok {
+		return nil
+	}
+	saved.Stored = u.Stored
+	saved.Reconciled = true
+	urm.usage[k] = saved
+
+	return nil
+}
+
+func (urm *usageRepositoryMock) RetrieveByOwner(_ context.Context, owner string, from, to time.Time) ([]metering.Usage, error) {
+	urm.mu.Lock()
+	defer urm.mu.Unlock()
+
+	usage := []metering.Usage{}
+	for _, u := range urm.usage {
+		if u.Owner == owner && !u.Hour.Before(from) && u.Hour.Before(to) {
+			usage = append(usage, u)
+		}
+	}
+
+	sortUsage(usage)
+	return usage, nil
+}
+
+func key(u metering.Usage) string {
+	return u.Channel + ":" + u.Publisher + ":" + u.Hour.UTC().String()
+}
+
+func sortUsage(usage []metering.Usage) {
+	sort.Slice(usage, func(i, j int) bool {
+		if !usage[i].Hour.Equal(usage[j].Hour) {
+			return usage[i].Hour.Before(usage[j].Hour)
+		}
+		return key(usage[i]) < key(usage[j])
+	})
+}
@@ -0,0 +1,54 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package nats contains the consumer which counts the normalized messages
+// received from NATS.
+package nats
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/mainflux/mainflux"
+	log "github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/metering"
+	mfnats "github.com/mainflux/mainflux/nats"
+	"github.com/nats-io/nats.go"
+)
+
+const queue = "metering"
+
+type consumer struct {
+	svc    metering.Service
+	logger log.Logger
+}
+
+// Start starts to count the normalized messages received from NATS. The
+// subject is prefixed with the deployment subject prefix, unless it is empty.
+// Message ID is the hash of the message as received, so that the redelivered
+// message has the same ID as the original one.
+func Start(nc *nats.Conn, subjectPrefix string, svc metering.Service, logger log.Logger) error {
+	c := consumer{
+		svc:    svc,
+		logger: logger,
+	}
+
+	_, err := nc.QueueSubscribe(mfnats.Subject(subjectPrefix, mainflux.OutputSenML), queue, c.consume)
+	return err
+}
+
+func (c consumer) consume(m *nats.Msg) {
+	msg := mainflux.Message{}
+	if err := proto.Unmarshal(m.Data, &msg); err != nil {
+		c.logger.Warn(fmt.Sprintf("Failed to unmarshal received message: %s", err))
+		return
+	}
+
+	sum := sha256.Sum256(m.Data)
+	if err := c.svc.Count(context.Background(), hex.EncodeToString(sum[:]), msg); err != nil {
+		c.logger.Warn(fmt.Sprintf("Failed to count message: %s", err))
+	}
+}
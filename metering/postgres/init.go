@@ -0,0 +1,79 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq" // required for SQL access
+	migrate "github.com/rubenv/sql-migrate"
+)
+
+// Config defines the options that are used when connecting to a PostgreSQL instance
+type Config struct {
+	Host        string
+	Port        string
+	User        string
+	Pass        string
+	Name        string
+	SSLMode     string
+	SSLCert     string
+	SSLKey      string
+	SSLRootCert string
+}
+
+// Connect creates a connection to the PostgreSQL instance and applies any
+// unapplied database migrations. A non-nil error is returned to indicate
+// failure.
+func Connect(cfg Config) (*sqlx.DB, error) {
+	url := fmt.Sprintf("host=%s port=%s user=%s dbname=%s password=%s sslmode=%s sslcert=%s sslkey=%s sslrootcert=%s", cfg.Host, cfg.Port, cfg.User, cfg.Name, cfg.Pass, cfg.SSLMode, cfg.SSLCert, cfg.SSLKey, cfg.SSLRootCert)
+
+	db, err := sqlx.Open("postgres", url)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := migrateDB(db); err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+func migrateDB(db *sqlx.DB) error {
+	migrations := &migrate.MemoryMigrationSource{
+		Migrations: []*migrate.Migration{
+			{
+				Id: "metering_1",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS counted_messages (
+						id         VARCHAR(64) PRIMARY KEY,
+						counted_at TIMESTAMP NOT NULL DEFAULT NOW()
+					)`,
+					`CREATE INDEX IF NOT EXISTS counted_messages_time_idx ON counted_messages (counted_at)`,
+					`CREATE TABLE IF NOT EXISTS usage (
+						channel       UUID,
+						publisher     UUID,
+						hour          TIMESTAMP,
+						owner         VARCHAR(254) NOT NULL,
+						messages      BIGINT NOT NULL DEFAULT 0,
+						stored        BIGINT NOT NULL DEFAULT 0,
+						reconciled_at TIMESTAMP,
+						PRIMARY KEY (channel, publisher, hour)
+					)`,
+					`CREATE INDEX IF NOT EXISTS usage_owner_idx ON usage (owner, hour)`,
+					`CREATE INDEX IF NOT EXISTS usage_unreconciled_idx ON usage (hour) WHERE reconciled_at IS NULL`,
+				},
+				Down: []string{
+					`DROP TABLE IF EXISTS usage`,
+					`DROP TABLE IF EXISTS counted_messages`,
+				},
+			},
+		},
+	}
+
+	_, err := migrate.Exec(db.DB, "postgres", migrations, migrate.Up)
+	return err
+}
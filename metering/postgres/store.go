@@ -0,0 +1,47 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/mainflux/mainflux/metering"
+)
+
+var _ metering.MessageStore = (*messageStore)(nil)
+
+type messageStore struct {
+	db *sqlx.DB
+}
+
+// NewMessageStore returns message store which counts the messages saved by
+// the Postgres writer. Messages compacted into the hourly rollups are counted
+// as well, but the rollups keep the numeric messages only, so the hours
+// should be reconciled before they are compacted.
+func NewMessageStore(db *sqlx.DB) metering.MessageStore {
+	return &messageStore{
+		db: db,
+	}
+}
+
+func (ms messageStore) Count(ctx context.Context, channel, publisher string, from, to time.Time) (uint64, error) {
+	q := `SELECT
+	      (SELECT COUNT(*) FROM messages WHERE channel = $1 AND publisher = $2 AND time >= $3 AND time < $4) +
+	      (SELECT COALESCE(SUM(count), 0) FROM rollups WHERE channel = $1 AND publisher = $2 AND hour >= $3 AND hour < $4);`
+
+	var cnt int64
+	if err := ms.db.QueryRowxContext(ctx, q, channel, publisher, seconds(from), seconds(to)).Scan(&cnt); err != nil {
+		return 0, err
+	}
+
+	return uint64(cnt), nil
+}
+
+// seconds returns the time as the Unix timestamp in seconds, which is how
+// the writer saves the message time.
+func seconds(t time.Time) float64 {
+	return float64(t.UnixNano()) / float64(time.Second)
+}
@@ -0,0 +1,75 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/metering/postgres"
+	writers "github.com/mainflux/mainflux/writers/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageStoreCount(t *testing.T) {
+	store := postgres.NewMessageStore(db)
+	writer := writers.New(db)
+
+	channel := newID(t)
+	publisher := newID(t)
+	hour := time.Now().UTC().Truncate(time.Hour).Add(-time.Hour)
+	start := float64(hour.Unix())
+
+	msgs := []mainflux.Message{}
+	for _, ts := range []float64{start, start + 1, start + 3599, start + 3600} {
+		msgs = append(msgs, mainflux.Message{
+			Channel:   channel,
+			Publisher: publisher,
+			Protocol:  "mqtt",
+			Time:      ts,
+		})
+	}
+	err := writer.Save(msgs...)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc      string
+		publisher string
+		from      time.Time
+		to        time.Time
+		count     uint64
+	}{
+		{
+			desc:      "count messages within hour",
+			publisher: publisher,
+			from:      hour,
+			to:        hour.Add(time.Hour),
+			count:     3,
+		},
+		{
+			desc:      "count messages within following hour",
+			publisher: publisher,
+			from:      hour.Add(time.Hour),
+			to:        hour.Add(2 * time.Hour),
+			count:     1,
+		},
+		{
+			desc:      "count messages of other publisher",
+			publisher: newID(t),
+			from:      hour,
+			to:        hour.Add(time.Hour),
+			count:     0,
+		},
+	}
+
+	for _, tc := range cases {
+		count, err := store.Count(context.Background(), channel, tc.publisher, tc.from, tc.to)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		assert.Equal(t, tc.count, count, fmt.Sprintf("%s: expected %d got %d", tc.desc, tc.count, count))
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq" // required for DB access
+	"github.com/mainflux/mainflux/metering"
+)
+
+var _ metering.UsageRepository = (*usageRepository)(nil)
+
+type usageRepository struct {
+	db *sqlx.DB
+}
+
+// NewUsageRepository instantiates a PostgreSQL implementation of usage
+// repository.
+func NewUsageRepository(db *sqlx.DB) metering.UsageRepository {
+	return &usageRepository{
+		db: db,
+	}
+}
+
+func (ur usageRepository) Count(ctx context.Context, id string, u metering.Usage) (bool, error) {
+	// Usage is updated only if the message ID is inserted by the same
+	// statement, so that the message is counted exactly once.
+	q := `WITH counted AS (
+	          INSERT INTO counted_messages (id) VALUES (:id)
+	          ON CONFLICT (id) DO NOTHING RETURNING id
+	      )
+	      INSERT INTO usage (channel, publisher, hour, owner, messages)
+	      SELECT :channel, :publisher, :hour, :owner, 1 FROM counted
+	      ON CONFLICT (channel, publisher, hour) DO UPDATE SET
+	      messages = usage.messages + 1, reconciled_at = NULL;`
+
+	params := map[string]interface{}{
+		"id":        id,
+		"channel":   u.Channel,
+		"publisher": u.Publisher,
+		"hour":      u.Hour.UTC(),
+		"owner":     u.Owner,
+	}
+
+	res, err := ur.db.NamedExecContext(ctx, q, params)
+	if err != nil {
+		return false, err
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return cnt > 0, nil
+}
+
+func (ur usageRepository) Forget(ctx context.Context, before time.Time) error {
+	q := `DELETE FROM counted_messages WHERE counted_at < $1;`
+
+	_, err := ur.db.ExecContext(ctx, q, before.UTC())
+	return err
+}
+
+func (ur usageRepository) RetrieveUnreconciled(ctx context.Context, before time.Time, limit uint64) ([]metering.Usage, error) {
+	q := `SELECT channel, publisher, hour, owner, messages, stored, reconciled_at FROM usage
+	      WHERE reconciled_at IS NULL AND hour + INTERVAL '1 hour' <= $1 ORDER BY hour LIMIT $2;`
+
+	return ur.retrieve(ctx, q, before.UTC(), limit)
+}
+
+func (ur usageRepository) Reconcile(ctx context.Context, u metering.Usage) error {
+	q := `UPDATE usage SET stored = :stored, reconciled_at = NOW()
+	      WHERE channel = :channel AND publisher = :publisher AND hour = :hour;`
+
+	_, err := ur.db.NamedExecContext(ctx, q, toDBUsage(u))
+	return err
+}
+
+func (ur usageRepository) RetrieveByOwner(ctx context.Context, owner string, from, to time.Time) ([]metering.Usage, error) {
+	q := `SELECT channel, publisher, hour, owner, messages, stored, reconciled_at FROM usage
+	      WHERE owner = $1 AND hour >= $2 AND hour < $3 ORDER BY hour, channel, publisher;`
+
+	return ur.retrieve(ctx, q, owner, from.UTC(), to.UTC())
+}
+
+func (ur usageRepository) retrieve(ctx context.Context, q string, args ...interface{}) ([]metering.Usage, error) {
+	rows, err := ur.db.QueryxContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	usage := []metering.Usage{}
+	for rows.Next() {
+		var dbu dbUsage
+		if err := rows.StructScan(&dbu); err != nil {
+			return nil, err
+		}
+		usage = append(usage, toUsage(dbu))
+	}
+
+	return usage, rows.Err()
+}
+
+type dbUsage struct {
+	Channel      string      `db:"channel"`
+	Publisher    string      `db:"publisher"`
+	Hour         time.Time   `db:"hour"`
+	Owner        string      `db:"owner"`
+	Messages     int64       `db:"messages"`
+	Stored       int64       `db:"stored"`
+	ReconciledAt pq.NullTime `db:"reconciled_at"`
+}
+
+func toDBUsage(u metering.Usage) dbUsage {
+	return dbUsage{
+		Channel:   u.Channel,
+		Publisher: u.Publisher,
+		Hour:      u.Hour.UTC(),
+		Owner:     u.Owner,
+		Messages:  int64(u.Messages),
+		Stored:    int64(u.Stored),
+	}
+}
+
+func toUsage(dbu dbUsage) metering.Usage {
+	return metering.Usage{
+		Channel:    dbu.Channel,
+		Publisher:  dbu.Publisher,
+		Hour:       dbu.Hour.UTC(),
+		Owner:      dbu.Owner,
+		Messages:   uint64(dbu.Messages),
+		Stored:     uint64(dbu.Stored),
+		Reconciled: dbu.ReconciledAt.Valid,
+	}
+}
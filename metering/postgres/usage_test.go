@@ -0,0 +1,132 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/mainflux/mainflux/metering"
+	"github.com/mainflux/mainflux/metering/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsageCount(t *testing.T) {
+	repo := postgres.NewUsageRepository(db)
+	hour := time.Now().UTC().Truncate(time.Hour)
+	u := metering.Usage{
+		Owner:     "count@example.com",
+		Channel:   newID(t),
+		Publisher: newID(t),
+		Hour:      hour,
+	}
+
+	cases := []struct {
+		desc    string
+		id      string
+		counted bool
+	}{
+		{
+			desc:    "count new message",
+			id:      "count-1",
+			counted: true,
+		},
+		{
+			desc:    "count another new message",
+			id:      "count-2",
+			counted: true,
+		},
+		{
+			desc:    "count redelivered message",
+			id:      "count-1",
+			counted: false,
+		},
+	}
+
+	for _, tc := range cases {
+		counted, err := repo.Count(context.Background(), tc.id, u)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		assert.Equal(t, tc.counted, counted, fmt.Sprintf("%s: expected %t got %t", tc.desc, tc.counted, counted))
+	}
+
+	usage, err := repo.RetrieveByOwner(context.Background(), u.Owner, hour, hour.Add(time.Hour))
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	require.Len(t, usage, 1, "retrieve usage: expected single usage")
+	assert.Equal(t, uint64(2), usage[0].Messages, fmt.Sprintf("retrieve usage: expected %d messages got %d", 2, usage[0].Messages))
+}
+
+func TestUsageForget(t *testing.T) {
+	repo := postgres.NewUsageRepository(db)
+	u := metering.Usage{
+		Owner:     "forget@example.com",
+		Channel:   newID(t),
+		Publisher: newID(t),
+		Hour:      time.Now().UTC().Truncate(time.Hour),
+	}
+
+	_, err := repo.Count(context.Background(), "forget-1", u)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	err = repo.Forget(context.Background(), time.Now().Add(time.Minute))
+	assert.Nil(t, err, fmt.Sprintf("forget messages: unexpected error: %s", err))
+
+	counted, err := repo.Count(context.Background(), "forget-1", u)
+	assert.Nil(t, err, fmt.Sprintf("count forgotten message: unexpected error: %s", err))
+	assert.True(t, counted, "count forgotten message: expected message to be counted")
+}
+
+func TestUsageReconcile(t *testing.T) {
+	repo := postgres.NewUsageRepository(db)
+	hour := time.Now().UTC().Truncate(time.Hour).Add(-2 * time.Hour)
+	u := metering.Usage{
+		Owner:     "reconcile@example.com",
+		Channel:   newID(t),
+		Publisher: newID(t),
+		Hour:      hour,
+	}
+
+	_, err := repo.Count(context.Background(), newID(t), u)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	usage, err := repo.RetrieveUnreconciled(context.Background(), hour.Add(time.Hour), 100)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Contains(t, channels(usage), u.Channel, "retrieve unreconciled usage of ended hour: expected usage to be retrieved")
+
+	usage, err = repo.RetrieveUnreconciled(context.Background(), hour.Add(time.Minute), 100)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.NotContains(t, channels(usage), u.Channel, "retrieve unreconciled usage of ongoing hour: expected usage not to be retrieved")
+
+	u.Stored = 1
+	err = repo.Reconcile(context.Background(), u)
+	assert.Nil(t, err, fmt.Sprintf("reconcile usage: unexpected error: %s", err))
+
+	usage, err = repo.RetrieveUnreconciled(context.Background(), hour.Add(time.Hour), 100)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.NotContains(t, channels(usage), u.Channel, "retrieve reconciled usage: expected usage not to be retrieved")
+
+	_, err = repo.Count(context.Background(), newID(t), u)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	usage, err = repo.RetrieveUnreconciled(context.Background(), hour.Add(time.Hour), 100)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Contains(t, channels(usage), u.Channel, "retrieve usage counted after reconciliation: expected usage to be retrieved")
+}
+
+func channels(usage []metering.Usage) []string {
+	var chs []string
+	for _, u := range usage {
+		chs = append(chs, u.Channel)
+	}
+	return chs
+}
+
+func newID(t *testing.T) string {
+	id, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	return id.String()
+}
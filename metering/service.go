@@ -0,0 +1,187 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package metering
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/mainflux/mainflux"
+)
+
+// reconcileBatch is the number of usages reconciled at once.
+const reconcileBatch = 100
+
+var (
+	// ErrMalformedEntity indicates malformed entity specification.
+	ErrMalformedEntity = errors.New("malformed entity specification")
+
+	// ErrUnauthorizedAccess indicates missing or invalid credentials provided
+	// when accessing a protected resource.
+	ErrUnauthorizedAccess = errors.New("missing or invalid credentials provided")
+)
+
+// Service specifies an API that must be fulfilled by the domain service
+// implementation, and all of its decorators (e.g. logging & metrics).
+type Service interface {
+	// Count counts the message identified by the provided ID, unless the
+	// message with the same ID has already been counted within the dedupe
+	// window. Message is counted within the hour it was published in.
+	Count(context.Context, string, mainflux.Message) error
+
+	// Forget forgets the IDs of the messages counted before the dedupe
+	// window.
+	Forget(context.Context) error
+
+	// Reconcile compares the counted messages against the messages saved by
+	// the writer, for all the unreconciled hours that ended before the
+	// provided time. Reconciled usages are returned.
+	Reconcile(context.Context, time.Time) ([]Usage, error)
+
+	// ViewUsage retrieves the usage of the things owned by the user
+	// identified by the provided key, within the provided time range.
+	ViewUsage(context.Context, string, time.Time, time.Time) ([]Usage, error)
+}
+
+var _ Service = (*meteringService)(nil)
+
+type meteringService struct {
+	users  mainflux.UsersServiceClient
+	things mainflux.ThingsServiceClient
+	repo   UsageRepository
+	store    MessageStore
+	window   time.Duration
+	ownerTTL time.Duration
+
+	mu     sync.RWMutex
+	owners map[string]cachedOwner
+}
+
+type cachedOwner struct {
+	owner   string
+	expires time.Time
+}
+
+// New instantiates the metering service implementation. Message IDs are
+// remembered for the dedupe window, so redeliveries of the message within
+// the window aren't counted. Owners of the things are cached for the owner
+// TTL.
+func New(users mainflux.UsersServiceClient, things mainflux.ThingsServiceClient, repo UsageRepository, store MessageStore, window, ownerTTL time.Duration) Service {
+	return &meteringService{
+		users:    users,
+		things:   things,
+		repo:     repo,
+		store:    store,
+		window:   window,
+		ownerTTL: ownerTTL,
+		owners:   make(map[string]cachedOwner),
+	}
+}
+
+func (ms *meteringService) Count(ctx context.Context, id string, msg mainflux.Message) error {
+	if id == "" || msg.Channel == "" || msg.Publisher == "" {
+		return ErrMalformedEntity
+	}
+
+	owner, err := ms.owner(ctx, msg.Publisher)
+	if err != nil {
+		return err
+	}
+
+	u := Usage{
+		Owner:     owner,
+		Channel:   msg.Channel,
+		Publisher: msg.Publisher,
+		Hour:      publishedAt(msg).Truncate(time.Hour),
+	}
+
+	_, err = ms.repo.Count(ctx, id, u)
+	return err
+}
+
+func (ms *meteringService) Forget(ctx context.Context) error {
+	return ms.repo.Forget(ctx, time.Now().Add(-ms.window))
+}
+
+func (ms *meteringService) Reconcile(ctx context.Context, before time.Time) ([]Usage, error) {
+	reconciled := []Usage{}
+	for {
+		usage, err := ms.repo.RetrieveUnreconciled(ctx, before, reconcileBatch)
+		if err != nil {
+			return reconciled, err
+		}
+
+		for _, u := range usage {
+			stored, err := ms.store.Count(ctx, u.Channel, u.Publisher, u.Hour, u.Hour.Add(time.Hour))
+			if err != nil {
+				return reconciled, err
+			}
+
+			u.Stored = stored
+			u.Reconciled = true
+			if err := ms.repo.Reconcile(ctx, u); err != nil {
+				return reconciled, err
+			}
+
+			reconciled = append(reconciled, u)
+		}
+
+		if len(usage) < reconcileBatch {
+			return reconciled, nil
+		}
+	}
+}
+
+func (ms *meteringService) ViewUsage(ctx context.Context, token string, from, to time.Time) ([]Usage, error) {
+	res, err := ms.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return nil, ErrUnauthorizedAccess
+	}
+
+	if !to.After(from) {
+		return nil, ErrMalformedEntity
+	}
+
+	return ms.repo.RetrieveByOwner(ctx, res.GetValue(), from, to)
+}
+
+// owner returns the owner of the thing. Owners are cached for the owner TTL,
+// so after the ownership transfer the messages are counted to the previous
+// owner of the thing for at most the TTL.
+func (ms *meteringService) owner(ctx context.Context, thing string) (string, error) {
+	now := time.Now()
+
+	ms.mu.RLock()
+	cached, ok := ms.owners[thing]
+	ms.mu.RUnlock()
+	if ok && now.Before(cached.expires) {
+		return cached.owner, nil
+	}
+
+	res, err := ms.things.Owner(ctx, &mainflux.ThingID{Value: thing})
+	if err != nil {
+		return "", err
+	}
+
+	ms.mu.Lock()
+	ms.owners[thing] = cachedOwner{
+		owner:   res.GetValue(),
+		expires: now.Add(ms.ownerTTL),
+	}
+	ms.mu.Unlock()
+
+	return res.GetValue(), nil
+}
+
+// publishedAt returns the publishing time of the message, or the current
+// time if the message doesn't carry one.
+func publishedAt(msg mainflux.Message) time.Time {
+	if msg.Time == 0 {
+		return time.Now().UTC()
+	}
+
+	return time.Unix(0, int64(msg.Time*float64(time.Second))).UTC()
+}
@@ -0,0 +1,210 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package metering_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/metering"
+	"github.com/mainflux/mainflux/metering/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	wrongValue = "wrong-value"
+	email      = "user@example.com"
+	token      = "token"
+	chanID     = "1"
+	thingID    = "2"
+	window     = time.Hour
+	ownerTTL   = time.Hour
+)
+
+var hour = time.Date(2020, time.January, 1, 10, 0, 0, 0, time.UTC)
+
+func newMessage(t time.Time) mainflux.Message {
+	return mainflux.Message{
+		Channel:   chanID,
+		Publisher: thingID,
+		Protocol:  "http",
+		Time:      float64(t.UnixNano()) / float64(time.Second),
+	}
+}
+
+func newService(stored []mainflux.Message) (metering.Service, *mocks.ThingsClient) {
+	return newServiceWithOwnerTTL(stored, ownerTTL)
+}
+
+func newServiceWithOwnerTTL(stored []mainflux.Message, ttl time.Duration) (metering.Service, *mocks.ThingsClient) {
+	users := mocks.NewUsersService(map[string]string{token: email})
+	things := mocks.NewThingsClient(map[string]string{thingID: email})
+	repo := mocks.NewUsageRepository()
+	store := mocks.NewMessageStore(stored)
+
+	return metering.New(users, things, repo, store, window, ttl), things
+}
+
+func TestCount(t *testing.T) {
+	svc, things := newService(nil)
+	msg := newMessage(hour.Add(time.Minute))
+
+	unknown := msg
+	unknown.Publisher = wrongValue
+
+	cases := []struct {
+		desc string
+		id   string
+		msg  mainflux.Message
+		err  error
+	}{
+		{
+			desc: "count new message",
+			id:   "1",
+			msg:  msg,
+			err:  nil,
+		},
+		{
+			desc: "count redelivered message",
+			id:   "1",
+			msg:  msg,
+			err:  nil,
+		},
+		{
+			desc: "count another message",
+			id:   "2",
+			msg:  msg,
+			err:  nil,
+		},
+		{
+			desc: "count message without ID",
+			id:   "",
+			msg:  msg,
+			err:  metering.ErrMalformedEntity,
+		},
+		{
+			desc: "count message without channel",
+			id:   "3",
+			msg:  mainflux.Message{Publisher: thingID},
+			err:  metering.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.Count(context.Background(), tc.id, tc.msg)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	err := svc.Count(context.Background(), "4", unknown)
+	assert.NotNil(t, err, "count message of unknown thing: expected error")
+
+	usage, err := svc.ViewUsage(context.Background(), token, hour, hour.Add(time.Hour))
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	require.Len(t, usage, 1, "view usage: expected single usage")
+	assert.Equal(t, uint64(2), usage[0].Messages, fmt.Sprintf("view usage: expected %d messages got %d", 2, usage[0].Messages))
+	assert.Equal(t, hour, usage[0].Hour, fmt.Sprintf("view usage: expected hour %s got %s", hour, usage[0].Hour))
+	assert.Equal(t, 2, things.Lookups(), fmt.Sprintf("expected owners to be looked up %d times got %d", 2, things.Lookups()))
+}
+
+func TestCountExpiredOwner(t *testing.T) {
+	svc, things := newServiceWithOwnerTTL(nil, time.Millisecond)
+	msg := newMessage(hour.Add(time.Minute))
+
+	err := svc.Count(context.Background(), "1", msg)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	time.Sleep(2 * time.Millisecond)
+	err = svc.Count(context.Background(), "2", msg)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, 2, things.Lookups(), fmt.Sprintf("expected expired owner to be looked up again, got %d lookups", things.Lookups()))
+}
+
+func TestForget(t *testing.T) {
+	svc, _ := newService(nil)
+	msg := newMessage(hour)
+
+	err := svc.Count(context.Background(), "1", msg)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	// Messages counted within the dedupe window are remembered.
+	err = svc.Forget(context.Background())
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	err = svc.Count(context.Background(), "1", msg)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	usage, err := svc.ViewUsage(context.Background(), token, hour, hour.Add(time.Hour))
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	require.Len(t, usage, 1, "view usage: expected single usage")
+	assert.Equal(t, uint64(1), usage[0].Messages, fmt.Sprintf("view usage: expected %d messages got %d", 1, usage[0].Messages))
+}
+
+func TestReconcile(t *testing.T) {
+	stored := []mainflux.Message{newMessage(hour), newMessage(hour.Add(time.Minute))}
+	svc, _ := newService(stored)
+
+	for i, ts := range []time.Time{hour, hour.Add(time.Minute), hour.Add(2 * time.Minute), hour.Add(time.Hour)} {
+		err := svc.Count(context.Background(), fmt.Sprintf("%d", i), newMessage(ts))
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	reconciled, err := svc.Reconcile(context.Background(), hour.Add(time.Hour))
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	require.Len(t, reconciled, 1, "reconcile usage: expected only the ended hour to be reconciled")
+	assert.Equal(t, uint64(3), reconciled[0].Messages, fmt.Sprintf("reconcile usage: expected %d messages got %d", 3, reconciled[0].Messages))
+	assert.Equal(t, uint64(2), reconciled[0].Stored, fmt.Sprintf("reconcile usage: expected %d stored messages got %d", 2, reconciled[0].Stored))
+
+	reconciled, err = svc.Reconcile(context.Background(), hour.Add(time.Hour))
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Empty(t, reconciled, "reconcile reconciled usage: expected no usage to be reconciled")
+
+	usage, err := svc.ViewUsage(context.Background(), token, hour, hour.Add(2*time.Hour))
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	require.Len(t, usage, 2, "view usage: expected two usages")
+	assert.True(t, usage[0].Reconciled, "view usage: expected ended hour to be reconciled")
+	assert.False(t, usage[1].Reconciled, "view usage: expected ongoing hour not to be reconciled")
+}
+
+func TestViewUsage(t *testing.T) {
+	svc, _ := newService(nil)
+
+	cases := []struct {
+		desc  string
+		token string
+		from  time.Time
+		to    time.Time
+		err   error
+	}{
+		{
+			desc:  "view usage",
+			token: token,
+			from:  hour,
+			to:    hour.Add(time.Hour),
+			err:   nil,
+		},
+		{
+			desc:  "view usage with invalid credentials",
+			token: wrongValue,
+			from:  hour,
+			to:    hour.Add(time.Hour),
+			err:   metering.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "view usage with invalid time range",
+			token: token,
+			from:  hour,
+			to:    hour,
+			err:   metering.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := svc.ViewUsage(context.Background(), tc.token, tc.from, tc.to)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package metering
+
+import (
+	"context"
+	"time"
+)
+
+// Usage represents the number of messages the thing published to the channel
+// within the hour. Usage is reconciled once the number of messages saved by
+// the writer within the same hour is known. Stored is meaningful only for the
+// reconciled usage.
+type Usage struct {
+	Owner      string
+	Channel    string
+	Publisher  string
+	Hour       time.Time
+	Messages   uint64
+	Stored     uint64
+	Reconciled bool
+}
+
+// UsageRepository specifies a usage persistence API.
+type UsageRepository interface {
+	// Count adds the message identified by the provided ID to the usage,
+	// unless the message has already been counted. Message is counted
+	// atomically with remembering its ID, so that it is counted exactly once
+	// as long as the ID is remembered. Returns whether the message has been
+	// counted. Counting the message to the reconciled usage makes it
+	// unreconciled again.
+	Count(context.Context, string, Usage) (bool, error)
+
+	// Forget removes the IDs of the messages counted before the provided
+	// time, so that the repository doesn't grow beyond the dedupe window.
+	Forget(context.Context, time.Time) error
+
+	// RetrieveUnreconciled retrieves at most the provided number of the
+	// unreconciled usages whose hours ended before the provided time.
+	RetrieveUnreconciled(context.Context, time.Time, uint64) ([]Usage, error)
+
+	// Reconcile saves the number of stored messages of the usage and marks
+	// it as reconciled.
+	Reconcile(context.Context, Usage) error
+
+	// RetrieveByOwner retrieves the usage of the things owned by the
+	// specified user, within the hours starting at or after the start and
+	// before the end of the provided time range.
+	RetrieveByOwner(context.Context, string, time.Time, time.Time) ([]Usage, error)
+}
+
+// MessageStore specifies an API for counting the messages saved by the writer.
+type MessageStore interface {
+	// Count returns the number of messages the thing published to the
+	// channel at or after the start and before the end of the provided time
+	// range, that are saved by the writer.
+	Count(context.Context, string, string, time.Time, time.Time) (uint64, error)
+}
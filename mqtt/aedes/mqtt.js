@@ -6,14 +6,21 @@
 const version = '0.9.0';
 
 var http = require('http'),
-    redis = require('redis'),
+    Redis = require('ioredis'),
+    crypto = require('crypto'),
     net = require('net'),
+    tls = require('tls'),
     protobuf = require('protobufjs'),
     websocket = require('websocket-stream'),
     grpc = require('grpc'),
     protoLoader = require('@grpc/proto-loader'),
     fs = require('fs'),
+    request = require('request'),
     bunyan = require('bunyan'),
+    nkeys = require('ts-nkeys'),
+    stream = require('stream'),
+    pg = require('pg'),
+    initTracer = require('jaeger-client').initTracer,
     logging = require('aedes-logging');
 
 // pass a proto file as a buffer/string or pass a parsed protobuf-schema object
@@ -21,22 +28,64 @@ var config = {
         log_level: process.env.MF_MQTT_ADAPTER_LOG_LEVEL || 'error',
         instance_id: process.env.MF_MQTT_INSTANCE_ID || '',
         event_stream: 'mainflux.mqtt',
+        will_stream: 'mainflux.mqtt.will',
+        will_subject: 'mqtt.will',
         mqtt_port: Number(process.env.MF_MQTT_ADAPTER_PORT) || 1883,
         ws_port: Number(process.env.MF_MQTT_ADAPTER_WS_PORT) || 8880,
+        mtls_port: Number(process.env.MF_MQTT_ADAPTER_MTLS_PORT) || 0,
+        server_cert: process.env.MF_MQTT_ADAPTER_SERVER_CERT || '',
+        server_key: process.env.MF_MQTT_ADAPTER_SERVER_KEY || '',
+        client_ca_certs: process.env.MF_MQTT_ADAPTER_CLIENT_CA_CERTS || '',
+        crl_url: process.env.MF_MQTT_ADAPTER_CRL_URL || '',
+        crl_interval: Number(process.env.MF_MQTT_ADAPTER_CRL_INTERVAL) || 300, // in seconds
+        mtls_mode: process.env.MF_MQTT_ADAPTER_MTLS_MODE || 'optional',
+        certs_url: process.env.MF_MQTT_ADAPTER_CERTS_URL || '',
         nats_url: process.env.MF_NATS_URL || 'nats://localhost:4222',
+        nats_creds: process.env.MF_NATS_CREDS || '',
+        nats_nkey_seed: process.env.MF_NATS_NKEY_SEED || '',
+        nats_ca_certs: process.env.MF_NATS_CA_CERTS || '',
+        nats_client_cert: process.env.MF_NATS_CLIENT_CERT || '',
+        nats_client_key: process.env.MF_NATS_CLIENT_KEY || '',
+        nats_subject_prefix: process.env.MF_NATS_SUBJECT_PREFIX || '',
         redis_port: Number(process.env.MF_MQTT_ADAPTER_REDIS_PORT) || 6379,
         redis_host: process.env.MF_MQTT_ADAPTER_REDIS_HOST || 'localhost',
         redis_pass: process.env.MF_MQTT_ADAPTER_REDIS_PASS || 'mqtt',
         redis_db: Number(process.env.MF_MQTT_ADAPTER_REDIS_DB) || 0,
+        redis_url: process.env.MF_MQTT_ADAPTER_REDIS_URL || '',
         message_ttl: Number(process.env.MF_MQTT_ADAPTER_MESSAGE_TTL) || 60, // in seconds
+        session_expiry: Number(process.env.MF_MQTT_ADAPTER_SESSION_EXPIRY) || 86400, // in seconds
+        queue_limit: Number(process.env.MF_MQTT_ADAPTER_QUEUE_LIMIT) || 1000,
+        expiry_interval: 60, // in seconds
         es_port: Number(process.env.MF_MQTT_ADAPTER_ES_PORT) || 6379,
         es_host: process.env.MF_MQTT_ADAPTER_ES_HOST || 'localhost',
         es_pass: process.env.MF_MQTT_ADAPTER_ES_PASS || 'mqtt',
         es_db: Number(process.env.MF_MQTT_ADAPTER_ES_DB) || 0,
+        es_url: process.env.MF_MQTT_ADAPTER_ES_URL || '',
         client_tls: (process.env.MF_MQTT_ADAPTER_CLIENT_TLS == 'true') || false,
         ca_certs: process.env.MF_MQTT_ADAPTER_CA_CERTS || '',
         concurrency: Number(process.env.MF_MQTT_CONCURRENT_MESSAGES) || 100,
         auth_url: process.env.MF_THINGS_URL || 'localhost:8181',
+        auth_callback_url: process.env.MF_MQTT_ADAPTER_AUTH_CALLBACK_URL || '',
+        auth_callback_timeout: Number(process.env.MF_MQTT_ADAPTER_AUTH_CALLBACK_TIMEOUT) || 1, // in seconds
+        max_thing_conns: Number(process.env.MF_MQTT_ADAPTER_MAX_THING_CONNS) || 0,
+        max_owner_conns: Number(process.env.MF_MQTT_ADAPTER_MAX_OWNER_CONNS) || 0,
+        sessions_port: Number(process.env.MF_MQTT_ADAPTER_SESSIONS_PORT) || 6379,
+        sessions_host: process.env.MF_MQTT_ADAPTER_SESSIONS_HOST || 'localhost',
+        sessions_pass: process.env.MF_MQTT_ADAPTER_SESSIONS_PASS || '',
+        sessions_db: Number(process.env.MF_MQTT_ADAPTER_SESSIONS_DB) || 0,
+        sessions_url: process.env.MF_MQTT_ADAPTER_SESSIONS_URL || '',
+        session_ttl: 30, // in seconds, must match the TTL of the other adapters
+        channel_aliases: (process.env.MF_MQTT_ADAPTER_CHANNEL_ALIASES == 'true') || false,
+        alias_ttl: 60, // in seconds
+        retain_store: process.env.MF_MQTT_ADAPTER_RETAIN_STORE || 'redis',
+        retain_db_url: process.env.MF_MQTT_ADAPTER_RETAIN_DB_URL || '',
+        sequencer_url: process.env.MF_MQTT_ADAPTER_SEQUENCER_URL || '',
+        sequencer_pass: process.env.MF_MQTT_ADAPTER_SEQUENCER_PASS || '',
+        sequencer_db: Number(process.env.MF_MQTT_ADAPTER_SEQUENCER_DB) || 0,
+        ordering_ttl: 60, // in seconds
+        jaeger_url: process.env.MF_JAEGER_URL || '',
+        max_payload: Number(process.env.MF_MQTT_ADAPTER_MAX_PAYLOAD) || 0, // in bytes
+        oversize_policy: process.env.MF_MQTT_ADAPTER_OVERSIZE_POLICY || 'reject',
         schema_dir: process.argv[2] || '.',
     },
     logger = bunyan.createLogger({
@@ -56,29 +105,44 @@ var config = {
     thingsSchema = protoDescriptor.mainflux,
     messagesSchema = new protobuf.Root().loadSync(config.schema_dir + '/message.proto'),
     RawMessage = messagesSchema.lookupType('mainflux.RawMessage'),
-    nats = require('nats').connect({
-        servers: [config.nats_url],
-        preserveBuffers: true,
-    }),
-    aedesRedis = require('aedes-persistence-redis')({
-        port: config.redis_port,
-        host: config.redis_host,
-        password: config.redis_pass,
-        db: config.redis_db,
+    sparkplugSchema = new protobuf.Root().loadSync(__dirname + '/sparkplug_b.proto'),
+    SparkplugPayload = sparkplugSchema.lookupType('org.eclipse.tahu.protobuf.Payload'),
+    nats = require('nats').connect(natsOptions()),
+    tracer = (function () {
+        var hostPort;
+        if (!config.jaeger_url) {
+            return null;
+        }
+        hostPort = config.jaeger_url.split(':');
+        return initTracer({
+            serviceName: 'mqtt_adapter',
+            sampler: {
+                type: 'const',
+                param: 1
+            },
+            reporter: {
+                agentHost: hostPort[0],
+                agentPort: Number(hostPort[1]) || 6831,
+                logSpans: true
+            }
+        }, {
+            logger: logger,
+            traceId128bit: true
+        });
+    })(),
+    aedesRedis = require('aedes-persistence-redis')(Object.assign({
         packetTTL: function (packet) {
             return config.message_ttl; // in seconds
-        }
-    }),
-    mqRedis = require('mqemitter-redis')({
-        port: config.redis_port,
-        host: config.redis_host,
-        password: config.redis_pass,
-        db: config.redis_db
-    }),
+        },
+        maxSessionDelivery: config.queue_limit
+    }, redisOptions(config.redis_url, config.redis_host, config.redis_port, config.redis_pass, config.redis_db))),
+    mqRedis = require('mqemitter-redis')(
+        redisOptions(config.redis_url, config.redis_host, config.redis_port, config.redis_pass, config.redis_db)
+    ),
     aedes = require('aedes')({
         id: config.instance_id ? config.instance_id : undefined,
         mq: mqRedis,
-        persistence: aedesRedis,
+        persistence: retainedPersistence(aedesRedis),
         concurrency: config.concurrency
     }),
     things = (function () {
@@ -90,16 +154,32 @@ var config = {
         }
         return new thingsSchema.ThingsService(config.auth_url, certs);
     })(),
-    esclient = redis.createClient({
-        port: config.es_port,
-        host: config.es_host,
-        password: config.es_pass,
-        db: config.es_db
-    }),
+    offlineClient = createRedis(
+        redisOptions(config.redis_url, config.redis_host, config.redis_port, config.redis_pass, config.redis_db)
+    ),
+    esclient = createRedis(
+        redisOptions(config.es_url, config.es_host, config.es_port, config.es_pass, config.es_db)
+    ),
+    sessionsClient = (function () {
+        if (!config.max_thing_conns && !config.max_owner_conns) {
+            return null;
+        }
+        return createRedis(
+            redisOptions(config.sessions_url, config.sessions_host, config.sessions_port, config.sessions_pass, config.sessions_db)
+        );
+    })(),
+    sequencerClient = (function () {
+        if (!config.sequencer_url) {
+            return null;
+        }
+        return createRedis(
+            redisOptions(config.sequencer_url, '', 0, config.sequencer_pass, config.sequencer_db)
+        );
+    })(),
     servers = [
         startMqtt(),
         startWs()
-    ];
+    ].concat(config.mtls_port ? [startMtls()] : []);
 
 logging({
     instance: aedes,
@@ -111,10 +191,440 @@ logging({
 
 logger.level(config.log_level);
 
+if (config.oversize_policy !== 'reject' && config.oversize_policy !== 'truncate') {
+    // Spill policy of the Go adapters needs the object store client.
+    throw new Error('unsupported oversized payloads policy ' + config.oversize_policy);
+}
+
 esclient.on('error', function (err) {
     logger.warn('error on redis connection: %s', err.message);
 });
 
+offlineClient.on('error', function (err) {
+    logger.warn('error on offline sessions redis connection: %s', err.message);
+});
+
+if (sessionsClient) {
+    sessionsClient.on('error', function (err) {
+        logger.warn('error on sessions redis connection: %s', err.message);
+    });
+}
+
+if (sequencerClient) {
+    sequencerClient.on('error', function (err) {
+        logger.warn('error on sequencer redis connection: %s', err.message);
+    });
+}
+
+// Sessions are shared with the other adapters and kept in sorted sets scored
+// by their expiration time. The scripts are the same as in the sessions/redis
+// Go package, so that the limits apply to all the protocols together.
+var acquireScript = `
+local now = tonumber(ARGV[1])
+local expiry = tonumber(ARGV[2])
+for i, key in ipairs(KEYS) do
+	redis.call('ZREMRANGEBYSCORE', key, '-inf', now)
+	local limit = tonumber(ARGV[3 + i])
+	if limit > 0 and not redis.call('ZSCORE', key, ARGV[3]) and redis.call('ZCARD', key) >= limit then
+		return 0
+	end
+end
+for _, key in ipairs(KEYS) do
+	redis.call('ZADD', key, expiry, ARGV[3])
+	redis.call('PEXPIREAT', key, expiry)
+end
+return 1
+`,
+    refreshScript = `
+local expiry = tonumber(ARGV[1])
+for _, key in ipairs(KEYS) do
+	if redis.call('ZADD', key, 'XX', 'CH', expiry, ARGV[2]) > 0 then
+		redis.call('PEXPIREAT', key, expiry)
+	end
+end
+return 1
+`;
+
+// redisOptions converts the Redis URL, in the same format as accepted by the
+// Go services, to the ioredis options. The URL selects the single instance,
+// Sentinel or Cluster topology, turns on TLS and sets the ACL user. If the URL
+// is not set, the single instance at the host and port is used.
+// natsOptions returns the NATS connection options. The user is authenticated
+// either with the credentials file or with the NKey seed file, and TLS is
+// turned on by the CA certificates or the client certificate.
+function natsOptions() {
+    var opts = {
+            servers: [config.nats_url],
+            preserveBuffers: true
+        },
+        seed;
+
+    if (config.nats_creds) {
+        opts.userCreds = config.nats_creds;
+    } else if (config.nats_nkey_seed) {
+        seed = nkeys.fromSeed(Buffer.from(fs.readFileSync(config.nats_nkey_seed, 'utf8').trim()));
+        opts.nkey = seed.getPublicKey().toString();
+        opts.nonceSigner = function (nonce) {
+            return seed.sign(Buffer.from(nonce));
+        };
+    }
+
+    if (config.nats_ca_certs || config.nats_client_cert) {
+        opts.tls = {};
+        if (config.nats_ca_certs) {
+            opts.tls.ca = [fs.readFileSync(config.nats_ca_certs)];
+        }
+        if (config.nats_client_cert) {
+            opts.tls.cert = fs.readFileSync(config.nats_client_cert);
+            opts.tls.key = fs.readFileSync(config.nats_client_key);
+        }
+    }
+
+    return opts;
+}
+
+// startSpan starts the span of the message published to the NATS subject,
+// unless the tracing is turned off.
+function startSpan(channelId, subtopic, subject) {
+    if (!tracer) {
+        return null;
+    }
+    return tracer.startSpan('publish', {
+        tags: {
+            'span.kind': 'producer',
+            'component': 'nats',
+            'message_bus.destination': natsSubject(subject),
+            'channel': channelId,
+            'subtopic': subtopic,
+            'protocol': 'mqtt'
+        }
+    });
+}
+
+function finishSpan(span) {
+    if (span) {
+        span.finish();
+    }
+}
+
+// traceMetadata returns the raw message metadata carrying the span context in
+// the W3C traceparent format. The context is passed in the metadata, since the
+// NATS client doesn't support the message headers.
+function traceMetadata(span) {
+    var ctx;
+    if (!span) {
+        return {};
+    }
+    ctx = span.context();
+    return {
+        traceparent: '00-' + ctx.traceIdStr.padStart(32, '0') + '-' +
+            ctx.spanIdStr.padStart(16, '0') + '-' + (ctx.isSampled() ? '01' : '00')
+    };
+}
+
+// natsSubject returns the subject prefixed with the deployment subject prefix.
+function natsSubject(subject) {
+    return config.nats_subject_prefix ? config.nats_subject_prefix + '.' + subject : subject;
+}
+
+function redisOptions(url, host, port, pass, db) {
+    var opts = {
+            host: host,
+            port: port,
+            password: pass || undefined,
+            db: db
+        },
+        i, scheme, rest, query, master, userinfo, addrs;
+
+    if (!url) {
+        return opts;
+    }
+
+    i = url.indexOf('://');
+    scheme = i < 0 ? 'redis' : url.slice(0, i);
+    rest = i < 0 ? url : url.slice(i + 3);
+
+    i = rest.indexOf('?');
+    if (i >= 0) {
+        query = new URLSearchParams(rest.slice(i + 1));
+        rest = rest.slice(0, i);
+    }
+
+    i = rest.indexOf('/');
+    if (i >= 0) {
+        master = rest.slice(i + 1);
+        rest = rest.slice(0, i);
+    }
+
+    i = rest.lastIndexOf('@');
+    if (i >= 0) {
+        userinfo = rest.slice(0, i).split(':');
+        rest = rest.slice(i + 1);
+        opts.username = userinfo[0] || undefined;
+        opts.password = pass || userinfo.slice(1).join(':') || undefined;
+    }
+
+    if (scheme.indexOf('rediss') === 0) {
+        opts.tls = {};
+        if (query && query.get('ca_certs')) {
+            opts.tls.ca = fs.readFileSync(query.get('ca_certs'));
+        }
+    }
+
+    addrs = rest.split(',').map(function (addr) {
+        var j = addr.lastIndexOf(':');
+        return j < 0 ? {host: addr, port: 6379} : {host: addr.slice(0, j), port: Number(addr.slice(j + 1))};
+    });
+
+    delete opts.host;
+    delete opts.port;
+    switch (scheme.replace('rediss', 'redis')) {
+    case 'redis-sentinel':
+        opts.sentinels = addrs;
+        opts.name = master;
+        break;
+    case 'redis-cluster':
+        delete opts.db;
+        opts.cluster = addrs;
+        break;
+    default:
+        opts.host = addrs[0].host;
+        opts.port = addrs[0].port;
+    }
+
+    return opts;
+}
+
+// createRedis creates the Redis client of the topology described by the
+// ioredis options.
+function createRedis(opts) {
+    if (opts.cluster) {
+        var nodes = opts.cluster;
+        delete opts.cluster;
+        return new Redis.Cluster(nodes, {redisOptions: opts});
+    }
+    return new Redis(opts);
+}
+
+// Owner is the hash tag of both keys, so that they're kept on the same Redis
+// Cluster node. Keys must match the ones used by the Go adapters.
+function sessionKeys(session) {
+    var tag = 'sessions:{' + session.owner + '}';
+    return [tag + ':thing:' + session.thing, tag + ':owner'];
+}
+
+// Registers the session of the thing, failing if either the thing or its
+// owner reached the maximum number of concurrent connections.
+function acquireSession(thingId, done) {
+    if (!sessionsClient) {
+        done(null, null);
+        return;
+    }
+    things.owner({value: thingId}, function (err, res) {
+        if (err) {
+            done(err, null);
+            return;
+        }
+        var now = Date.now(),
+            session = {
+                id: crypto.randomBytes(16).toString('hex'),
+                thing: thingId,
+                owner: res.value
+            },
+            keys = sessionKeys(session);
+        sessionsClient.eval(acquireScript, keys.length, keys[0], keys[1],
+            now, now + config.session_ttl * 1000, session.id,
+            config.max_thing_conns, config.max_owner_conns,
+            function (err, ok) {
+                if (err) {
+                    done(err, null);
+                    return;
+                }
+                if (!ok) {
+                    var limitErr = new Error('maximum number of concurrent sessions exceeded');
+                    limitErr.limitExceeded = true;
+                    done(limitErr, null);
+                    return;
+                }
+                session.refresh = setInterval(function () {
+                    sessionsClient.eval(refreshScript, keys.length, keys[0], keys[1],
+                        Date.now() + config.session_ttl * 1000, session.id,
+                        function (err) {
+                            if (err) {
+                                logger.warn('failed to refresh session: %s', err.message);
+                            }
+                        });
+                }, config.session_ttl * 1000 / 3);
+                done(null, session);
+            });
+    });
+}
+
+function releaseSession(session) {
+    if (!session) {
+        return;
+    }
+    clearInterval(session.refresh);
+    var keys = sessionKeys(session);
+    sessionsClient.multi()
+        .zrem(keys[0], session.id)
+        .zrem(keys[1], session.id)
+        .exec(function (err) {
+            if (err) {
+                logger.warn('failed to release session: %s', err.message);
+            }
+        });
+}
+
+// Retained messages are kept in the mqtt_retained table if the Postgres
+// store is used, holding the last retained message per topic.
+var createRetainedTable = `
+CREATE TABLE IF NOT EXISTS mqtt_retained (
+	topic   TEXT PRIMARY KEY,
+	payload BYTEA NOT NULL,
+	qos     SMALLINT NOT NULL
+)`,
+    upsertRetained = `
+INSERT INTO mqtt_retained (topic, payload, qos) VALUES ($1, $2, $3)
+ON CONFLICT (topic) DO UPDATE SET payload = EXCLUDED.payload, qos = EXCLUDED.qos`,
+    deleteRetained = 'DELETE FROM mqtt_retained WHERE topic = $1',
+    selectRetained = 'SELECT topic, payload, qos FROM mqtt_retained WHERE topic ~ $1';
+
+// retainedPersistence returns the aedes persistence which keeps the retained
+// messages in the configured store. The Redis store is the aedes Redis
+// persistence itself, while the Postgres store replaces its retained messages
+// methods, so that the last value of the topics survives Redis flushes.
+function retainedPersistence(persistence) {
+    switch (config.retain_store) {
+    case 'redis':
+        return persistence;
+    case 'postgres':
+        break;
+    default:
+        throw new Error('unknown retained messages store ' + config.retain_store);
+    }
+
+    var pool = new pg.Pool({connectionString: config.retain_db_url}),
+        ready = pool.query(createRetainedTable);
+
+    pool.on('error', function (err) {
+        logger.warn('error on retained messages database connection: %s', err.message);
+    });
+
+    // Retained message with the empty payload clears the topic.
+    persistence.storeRetained = function (packet, cb) {
+        ready.then(function () {
+            if (!packet.payload || packet.payload.length === 0) {
+                return pool.query(deleteRetained, [packet.topic]);
+            }
+            return pool.query(upsertRetained, [packet.topic, packet.payload, packet.qos]);
+        }).then(function () {
+            cb(null);
+        }, cb);
+    };
+
+    persistence.createRetainedStream = function (pattern) {
+        return persistence.createRetainedStreamCombi([pattern]);
+    };
+
+    persistence.createRetainedStreamCombi = function (patterns) {
+        var retained = new stream.Readable({
+            objectMode: true,
+            read: function () {}
+        });
+        ready.then(function () {
+            return pool.query(selectRetained, [patterns.map(topicRegExp).join('|')]);
+        }).then(function (res) {
+            res.rows.forEach(function (row) {
+                retained.push({
+                    cmd: 'publish',
+                    topic: row.topic,
+                    payload: row.payload,
+                    qos: row.qos,
+                    retain: true
+                });
+            });
+            retained.push(null);
+        }, function (err) {
+            logger.warn('failed to retrieve retained messages: %s', err.message);
+            retained.destroy(err);
+        });
+        return retained;
+    };
+
+    return persistence;
+}
+
+// topicRegExp converts the MQTT topic filter to the anchored POSIX regular
+// expression, matching the topics the same way as the filter.
+function topicRegExp(filter) {
+    var re = filter.split('/').map(function (level, i) {
+        var sep = i > 0 ? '/' : '';
+        switch (level) {
+        case '#':
+            return i > 0 ? '(/.*)?' : '.*';
+        case '+':
+            return sep + '[^/]*';
+        default:
+            return sep + level.replace(/[.*+?^$(){}|[\]\\]/g, '\\$&');
+        }
+    }).join('');
+    return '(^' + re + '$)';
+}
+
+// Persistent sessions of the disconnected clients are kept in the sorted set
+// scored by their expiration time, shared by all the adapter instances. The
+// subscriptions of the sessions which weren't resumed in time are removed, so
+// that the messages are no longer queued for them.
+var offlineKey = 'mqtt:offline';
+
+function parkSession(client) {
+    offlineClient.zadd(offlineKey, Date.now() + config.session_expiry * 1000, client.id, function (err) {
+        if (err) {
+            logger.warn('failed to park session of client %s: %s', client.id, err.message);
+        }
+    });
+}
+
+function resumeSession(client) {
+    offlineClient.zrem(offlineKey, client.id, function (err) {
+        if (err) {
+            logger.warn('failed to resume session of client %s: %s', client.id, err.message);
+        }
+    });
+}
+
+function expireSessions() {
+    offlineClient.zrangebyscore(offlineKey, '-inf', Date.now(), function (err, ids) {
+        if (err) {
+            logger.warn('failed to retrieve expired sessions: %s', err.message);
+            return;
+        }
+        ids.forEach(function (id) {
+            // Only the instance which removed the session from the set
+            // cleans it up.
+            offlineClient.zrem(offlineKey, id, function (err, removed) {
+                if (err) {
+                    logger.warn('failed to expire session of client %s: %s', id, err.message);
+                    return;
+                }
+                if (!removed) {
+                    return;
+                }
+                aedesRedis.cleanSubscriptions({id: id}, function (err) {
+                    if (err) {
+                        logger.warn('failed to clean subscriptions of client %s: %s', id, err.message);
+                        return;
+                    }
+                    logger.info('expired session of client %s', id);
+                });
+            });
+        });
+    });
+}
+
+setInterval(expireSessions, config.expiry_interval * 1000);
+
 // MQTT over WebSocket
 function startWs() {
     var server = http.createServer();
@@ -138,46 +648,358 @@ function startMqtt() {
     return net.createServer(aedes.handle).listen(config.mqtt_port);
 }
 
-nats.subscribe('channel.>', {
+// MQTT over mutual TLS, where the things authenticate using the client
+// certificates issued by the certs service instead of the keys. In the
+// optional mode, the clients which don't present the certificate fall back to
+// the key. Revocation list is fetched from the certs service and refreshed
+// periodically.
+function startMtls() {
+    if (config.mtls_mode !== 'optional' && config.mtls_mode !== 'require') {
+        throw new Error('unknown mutual TLS mode ' + config.mtls_mode);
+    }
+    var opts = {
+            cert: fs.readFileSync(config.server_cert),
+            key: fs.readFileSync(config.server_key),
+            ca: fs.readFileSync(config.client_ca_certs),
+            requestCert: true,
+            rejectUnauthorized: config.mtls_mode === 'require'
+        },
+        server = tls.createServer(opts, aedes.handle),
+        refreshCRL = function (done) {
+            request.get({
+                url: config.crl_url,
+                timeout: config.auth_callback_timeout * 1000
+            }, function (err, res, body) {
+                if (err || res.statusCode !== 200) {
+                    logger.warn('failed to fetch CRL: %s', err ? err.message : res.statusCode);
+                } else {
+                    server.setSecureContext(Object.assign({}, opts, {crl: body}));
+                }
+                done();
+            });
+        };
+
+    if (!config.crl_url) {
+        logger.warn('CRL URL is not set, revoked certificates are accepted');
+        return server.listen(config.mtls_port);
+    }
+
+    refreshCRL(function () {
+        server.listen(config.mtls_port);
+    });
+    setInterval(function () {
+        refreshCRL(function () {});
+    }, config.crl_interval * 1000);
+    return server;
+}
+
+// Returns the client certificate presented on the mutual TLS port, or null if
+// the client didn't present one.
+function peerCertificate(client) {
+    var conn = client.conn;
+    if (!conn || typeof conn.getPeerCertificate !== 'function') {
+        return null;
+    }
+    var cert = conn.getPeerCertificate();
+    return cert && cert.raw ? cert : null;
+}
+
+// Clients are identified by the common name of the verified certificate or,
+// if it isn't set, by the first DNS subject alternative name, which is the
+// thing ID.
+function certificateThing(cert) {
+    if (cert.subject && cert.subject.CN) {
+        return cert.subject.CN;
+    }
+    var dns = (cert.subjectaltname || '').split(', ').filter(function (name) {
+        return name.indexOf('DNS:') === 0;
+    });
+    return dns.length ? dns[0].slice(4) : '';
+}
+
+// Formats the serial number as colon separated hex bytes, like the certs
+// service does.
+function formatSerial(serial) {
+    var hex = serial.toLowerCase().replace(/^0+/, '');
+    if (hex.length % 2) {
+        hex = '0' + hex;
+    }
+    return hex.match(/../g).join(':');
+}
+
+// Unless the certs service URL is set, the certificate is considered good
+// once it is verified against the CA and the CRL.
+function checkCertificate(cert, done) {
+    if (!config.certs_url) {
+        done(null);
+        return;
+    }
+    request.get({
+        url: config.certs_url + '/certs/' + encodeURIComponent(formatSerial(cert.serialNumber)) + '/status',
+        json: true,
+        timeout: config.auth_callback_timeout * 1000
+    }, function (err, res, body) {
+        if (err) {
+            done(err);
+            return;
+        }
+        if (res.statusCode !== 200 || !body || body.status !== 'good') {
+            done(new Error('certificate is not valid'));
+            return;
+        }
+        done(null);
+    });
+}
+
+// Clients authenticated by the certificate have no key, so their access is
+// checked using the thing ID.
+function accessRequest(client, channelId, action) {
+    if (client.certAuth) {
+        return {
+            thingID: client.thingId,
+            chanID: channelId,
+            action: action
+        };
+    }
+    return {
+        token: client.password,
+        chanID: channelId,
+        action: action
+    };
+}
+
+// Channel aliases are cached in both directions, so that the changed aliases
+// take effect after at most alias_ttl seconds.
+var channelIds = {},
+    channelAliases = {},
+    channelOrdering = {};
+
+function cached(cache, key, ttl, lookup, done) {
+    var entry = cache[key],
+        now = Date.now();
+    if (entry && entry.expires > now) {
+        done(null, entry.value);
+        return;
+    }
+    lookup(key, function (err, value) {
+        if (err) {
+            done(err, null);
+            return;
+        }
+        cache[key] = {
+            value: value,
+            expires: now + ttl * 1000
+        };
+        done(null, value);
+    });
+}
+
+// Maps the channel part of the topic to the channel ID. If the channel
+// aliases are used, topics identify the channels by their aliases only.
+function resolveChannel(name, done) {
+    if (!config.channel_aliases) {
+        done(null, name);
+        return;
+    }
+    cached(channelIds, name, config.alias_ttl, function (alias, cb) {
+        things.resolveAlias({value: alias}, function (err, res) {
+            cb(err, res ? res.value : null);
+        });
+    }, done);
+}
+
+// Maps the channel ID to the channel part of the topic. Channels without the
+// alias can't be addressed if the channel aliases are used.
+function topicChannel(id, done) {
+    if (!config.channel_aliases) {
+        done(null, id);
+        return;
+    }
+    cached(channelAliases, id, config.alias_ttl, function (id, cb) {
+        things.alias({value: id}, function (err, res) {
+            cb(err, res ? res.value : null);
+        });
+    }, done);
+}
+
+// Assigns the next number of the channel sequence, shared with the other
+// adapters through the sequencer store, if the channel is ordered. The
+// sequence key is the same as in the ordering/redis package. Zero marks the
+// unsequenced message.
+function nextSequence(channelId, done) {
+    if (!sequencerClient) {
+        done(null, 0);
+        return;
+    }
+    cached(channelOrdering, channelId, config.ordering_ttl, function (id, cb) {
+        things.ordering({value: id}, function (err, res) {
+            cb(err, res ? res.ordered : false);
+        });
+    }, function (err, ordered) {
+        if (err) {
+            logger.warn('failed to retrieve ordering of channel %s: %s', channelId, err.message);
+        }
+        if (err || !ordered) {
+            done(null, 0);
+            return;
+        }
+        sequencerClient.incr('sequence:channel:' + channelId, done);
+    });
+}
+
+nats.subscribe(natsSubject('channel.>'), {
     'queue': 'mqtts'
 }, function (msg) {
-    var m = RawMessage.decode(msg),
-        packet, subtopic, ct;
-    if (m && m.protocol !== 'mqtt') {
-        subtopic = m.subtopic !== '' ? '/' + m.subtopic.replace(/\./g, '/') : '';
-        ct = (m.contentType) ? ('/ct/' + m.contentType.replace('/', '_').replace('+', '-')) : '';
-
-        packet = {
-            cmd: 'publish',
-            qos: 2,
-            topic: 'channels/' + m.channel + '/messages' + subtopic + ct,
-            payload: m.payload,
-            retain: false
-        };
+    var m = RawMessage.decode(msg);
+    if (!m || m.protocol === 'mqtt') {
+        return;
+    }
+    topicChannel(m.channel, function (err, channel) {
+        if (err) {
+            logger.warn('failed to retrieve alias of channel %s: %s', m.channel, err.message);
+            return;
+        }
+        if (!channel) {
+            return;
+        }
+        var subtopic = m.subtopic !== '' ? '/' + m.subtopic.replace(/\./g, '/') : '',
+            ct = (m.contentType) ? ('/ct/' + m.contentType.replace('/', '_').replace('+', '-')) : '',
+            packet = {
+                cmd: 'publish',
+                qos: 2,
+                topic: 'channels/' + channel + '/messages' + subtopic + ct,
+                payload: m.payload,
+                retain: false
+            };
 
         aedes.publish(packet);
-    }
+    });
 });
 
+// Checks access on things service and, if configured, lets the external
+// policy engine make the final decision.
+function canAccess(accessReq, done) {
+    var check = function (cb) {
+        if (accessReq.thingID) {
+            things.canAccessByID(accessReq, function (err) {
+                cb(err, err ? null : {value: accessReq.thingID});
+            });
+            return;
+        }
+        things.canAccess(accessReq, cb);
+    };
+    check(function (err, res) {
+        if (err || !config.auth_callback_url) {
+            done(err, res);
+            return;
+        }
+        request.post({
+            url: config.auth_callback_url,
+            json: {
+                thing_id: res.value,
+                chan_id: accessReq.chanID,
+                action: accessReq.action
+            },
+            timeout: config.auth_callback_timeout * 1000
+        }, function (cbErr, cbRes) {
+            if (cbErr) {
+                done(cbErr, null);
+                return;
+            }
+            if (cbRes.statusCode < 200 || cbRes.statusCode >= 300) {
+                done(new Error('access denied by policy engine'), null);
+                return;
+            }
+            done(null, res);
+        });
+    });
+}
+
 function parseTopic(topic) {
     // Topics are in the form `channels/<channel_id>/messages`
     // Subtopic's are in the form `channels/<channel_id>/messages/<subtopic>`
+    // The channel alias takes place of the channel ID if aliases are used.
     return /^channels\/(.+?)\/messages\/?.*$/.exec(topic);
 }
 
+// Sparkplug B topics are in the form
+// `spBv1.0/<group_id>/<message_type>/<edge_node_id>[/<device_id>]`, where
+// the group takes place of the channel. The subscriptions may use wildcards
+// after the group only.
+var sparkplugTopic = /^spBv1\.0\/([^\/+#]+)\/([ND](?:BIRTH|DEATH|DATA|CMD))\/([^\/+#]+)(?:\/([^\/+#]+))?$/,
+    sparkplugFilter = /^spBv1\.0\/([^\/+#]+)\/.+$/;
+
+function parseSparkplugTopic(topic) {
+    var parts = sparkplugTopic.exec(topic);
+    if (!parts) {
+        return null;
+    }
+    // Device messages are the only ones addressing the device.
+    if ((parts[2][0] === 'D') !== (parts[4] !== undefined)) {
+        return null;
+    }
+    return {
+        group: parts[1],
+        type: parts[2].slice(1),
+        node: parts[3],
+        device: parts[4] || ''
+    };
+}
+
+// limitPayload enforces the maximal payload size in the same way as the
+// payload package of the Go adapters. It returns the error if the oversized
+// payload is rejected, and truncates it otherwise.
+function limitPayload(client, packet) {
+    var size = packet.payload.length;
+    if (!config.max_payload || size <= config.max_payload) {
+        return null;
+    }
+    if (config.oversize_policy === 'truncate') {
+        logger.warn('truncated payload of %d bytes published by %s to topic %s', size, client.thingId, packet.topic);
+        packet.payload = packet.payload.slice(0, config.max_payload);
+        return null;
+    }
+    logger.warn('rejected payload of %d bytes published by %s to topic %s', size, client.thingId, packet.topic);
+    return new Error('message payload too large');
+}
+
 aedes.authorizePublish = function (client, packet, publish) {
-    var channel = parseTopic(packet.topic);
+    var channel = parseTopic(packet.topic),
+        sparkplug = parseSparkplugTopic(packet.topic),
+        tooLarge = limitPayload(client, packet);
+    if (tooLarge) {
+        publish(tooLarge);
+        return;
+    }
+    if (sparkplug) {
+        resolveChannel(sparkplug.group, function (err, channelId) {
+            if (err) {
+                logger.warn('unauthorized publish: %s', err.message);
+                publish(err); // Bad username or password
+                return;
+            }
+            authorizeSparkplugPublish(client, packet, sparkplug, channelId, publish);
+        });
+        return;
+    }
     if (!channel) {
         var err = new Error('unknown topic');
         logger.warn(err);
         publish(err); // Bad username or password
         return;
     }
-    var channelId = channel[1],
-        accessReq = {
-            token: client.password,
-            chanID: channelId
-        },
+    resolveChannel(channel[1], function (err, channelId) {
+        if (err) {
+            logger.warn('unauthorized publish: %s', err.message);
+            publish(err); // Bad username or password
+            return;
+        }
+        authorizeChannelPublish(client, packet, channelId, publish);
+    });
+};
+
+function authorizeChannelPublish(client, packet, channelId, publish) {
+    var accessReq = accessRequest(client, channelId, 'publish'),
         // Parse unlimited subtopics
         baseLength = 3, // First 3 elements which represents the base part of topic.
         isEmpty = function (value) {
@@ -206,53 +1028,269 @@ aedes.authorizePublish = function (client, packet, publish) {
 
     var channelTopic = st.length ? baseTopic + '.' + st.join('.') : baseTopic,
         onAuthorize = function (err, res) {
-            var rawMsg;
-            if (!err) {
+            var rawMsg, span;
+            if (!err && packet.retain && packet.payload.length === 0) {
+                // Clearing the retained message isn't the message itself.
+                publish(null);
+                return;
+            }
+            if (err) {
+                logger.warn('unauthorized publish: %s', err.message);
+                publish(err); // Bad username or password
+                return;
+            }
+            nextSequence(channelId, function (err, seq) {
+                if (err) {
+                    // Ordered channel messages aren't published unsequenced.
+                    logger.warn('failed to sequence message of channel %s: %s', channelId, err.message);
+                    publish(err);
+                    return;
+                }
+                span = startSpan(channelId, st.join('.'), channelTopic);
                 rawMsg = RawMessage.encode({
                     publisher: client.thingId,
                     channel: channelId,
                     subtopic: st.join('.'),
                     contentType: contentType,
                     protocol: 'mqtt',
-                    payload: packet.payload
+                    payload: packet.payload,
+                    sequence: seq,
+                    metadata: traceMetadata(span)
                 }).finish();
 
-                nats.publish(channelTopic, rawMsg);
+                nats.publish(natsSubject(channelTopic), rawMsg);
+                finishSpan(span);
+
+                if (packet === client.will) {
+                    // The last will is published by the broker when the
+                    // client goes away without disconnecting.
+                    publishWillEvent(client.thingId, channelId, st.join('.'));
+                }
 
                 publish(null);
-            } else {
-                logger.warn('unauthorized publish: %s', err.message);
-                publish(err); // Bad username or password
-            }
+            });
         };
 
-    things.CanAccess(accessReq, onAuthorize);
-};
+    canAccess(accessReq, onAuthorize);
+}
+
+// Sparkplug B messages are delivered to the MQTT subscribers as they are,
+// while the metrics of the birth and data messages are forwarded to the
+// channel as SenML, under the `<edge_node_id>[.<device_id>]` subtopic. Births
+// and deaths of the edge nodes and devices are published as the connectivity
+// events of the thing. Commands aren't forwarded, as they address the devices.
+function authorizeSparkplugPublish(client, packet, topic, channelId, publish) {
+    var accessReq = accessRequest(client, channelId, 'publish'),
+        subtopic = topic.device ? topic.node + '.' + topic.device : topic.node,
+        err;
+
+    if (/[.*>]/.test(subtopic)) {
+        err = new Error('invalid subtopic');
+        logger.warn(err);
+        publish(err);
+        return;
+    }
+
+    canAccess(accessReq, function (err, res) {
+        var payload, records, key = channelId + '.' + subtopic;
+        if (err) {
+            logger.warn('unauthorized publish: %s', err.message);
+            publish(err); // Bad username or password
+            return;
+        }
+        if (packet.retain && packet.payload.length === 0) {
+            publish(null);
+            return;
+        }
+        try {
+            payload = SparkplugPayload.decode(packet.payload);
+        } catch (e) {
+            logger.warn('failed to decode Sparkplug payload: %s', e.message);
+            publish(e);
+            return;
+        }
+
+        client.sparkplug = client.sparkplug || {};
+        switch (topic.type) {
+        case 'BIRTH':
+            sparkplugBirth(client, channelId, key, payload);
+            break;
+        case 'DEATH':
+            sparkplugDeath(client, channelId, key);
+            publish(null);
+            return;
+        case 'CMD':
+            publish(null);
+            return;
+        }
+
+        records = sparkplugSenML(payload, client.sparkplug[key] ? client.sparkplug[key].aliases : {});
+        if (!records.length) {
+            publish(null);
+            return;
+        }
+        nextSequence(channelId, function (err, seq) {
+            if (err) {
+                logger.warn('failed to sequence message of channel %s: %s', channelId, err.message);
+                publish(err);
+                return;
+            }
+            var subject = 'channel.' + channelId + '.' + subtopic,
+                span = startSpan(channelId, subtopic, subject),
+                rawMsg = RawMessage.encode({
+                    publisher: client.thingId,
+                    channel: channelId,
+                    subtopic: subtopic,
+                    contentType: 'application/senml+json',
+                    protocol: 'mqtt',
+                    payload: Buffer.from(JSON.stringify(records)),
+                    sequence: seq,
+                    metadata: traceMetadata(span)
+                }).finish();
+
+            nats.publish(natsSubject(subject), rawMsg);
+            finishSpan(span);
+            publish(null);
+        });
+    });
+}
+
+// Keeps the metric aliases declared by the birth certificate, and opens the
+// session of the edge node or device unless it is already born.
+function sparkplugBirth(client, channelId, key, payload) {
+    var state = client.sparkplug[key],
+        aliases = {};
+    payload.metrics.forEach(function (metric) {
+        if (metric.name && metric.hasOwnProperty('alias')) {
+            aliases[metric.alias.toString()] = metric.name;
+        }
+    });
+    if (state) {
+        state.aliases = aliases;
+        return;
+    }
+    client.sparkplug[key] = {
+        channel: channelId,
+        session: crypto.randomBytes(16).toString('hex'),
+        aliases: aliases
+    };
+    publishSparkplugEvent(client.thingId, client.sparkplug[key], 'connect');
+}
+
+// Closes the session of the edge node or device. Death of the edge node
+// implies the death of all of its devices.
+function sparkplugDeath(client, channelId, key) {
+    Object.keys(client.sparkplug).forEach(function (k) {
+        if (k === key || k.indexOf(key + '.') === 0) {
+            publishSparkplugEvent(client.thingId, client.sparkplug[k], 'disconnect');
+            delete client.sparkplug[k];
+        }
+    });
+}
+
+// Sparkplug B data types which are stored in the signed integer values.
+var sparkplugInt8 = 1,
+    sparkplugInt16 = 2,
+    sparkplugInt32 = 3,
+    sparkplugInt64 = 4;
+
+// Converts the 64-bit value, decoded either as the number or as the Long.
+function sparkplugNumber(val) {
+    return typeof val === 'number' ? val : val.toNumber();
+}
+
+// Converts the metrics to the SenML records. Metrics published by their
+// alias are named by the birth certificate. Null metrics, data sets and
+// templates are skipped, as they have no SenML value.
+function sparkplugSenML(payload, aliases) {
+    var records = [];
+    payload.metrics.forEach(function (metric) {
+        var name = metric.name || (metric.hasOwnProperty('alias') ? aliases[metric.alias.toString()] : ''),
+            ts = sparkplugNumber(metric.hasOwnProperty('timestamp') ? metric.timestamp : payload.timestamp),
+            rec = {n: name},
+            val;
+        if (!name || metric.is_null) {
+            return;
+        }
+        switch (metric.value) {
+        case 'int_value':
+            val = metric.int_value;
+            switch (metric.datatype) {
+            case sparkplugInt8:
+                rec.v = val << 24 >> 24;
+                break;
+            case sparkplugInt16:
+                rec.v = val << 16 >> 16;
+                break;
+            case sparkplugInt32:
+                rec.v = val | 0;
+                break;
+            default:
+                rec.v = val;
+            }
+            break;
+        case 'long_value':
+            val = metric.long_value;
+            if (metric.datatype === sparkplugInt64 && typeof val !== 'number') {
+                val = val.toSigned();
+            }
+            rec.v = sparkplugNumber(val);
+            if (metric.datatype === sparkplugInt64 && rec.v >= Math.pow(2, 63)) {
+                rec.v -= Math.pow(2, 64);
+            }
+            break;
+        case 'float_value':
+        case 'double_value':
+            rec.v = metric[metric.value];
+            break;
+        case 'boolean_value':
+            rec.vb = metric.boolean_value;
+            break;
+        case 'string_value':
+            rec.vs = metric.string_value;
+            break;
+        case 'bytes_value':
+            rec.vd = Buffer.from(metric.bytes_value).toString('base64');
+            break;
+        default:
+            return;
+        }
+        if (ts) {
+            // Sparkplug timestamps are in milliseconds.
+            rec.t = ts / 1000;
+        }
+        records.push(rec);
+    });
+    return records;
+}
 
 
 aedes.authorizeSubscribe = function (client, packet, subscribe) {
-    var channel = parseTopic(packet.topic);
+    var channel = parseTopic(packet.topic) || sparkplugFilter.exec(packet.topic);
     if (!channel) {
         logger.warn('unknown topic');
         var err = new Error('unknown topic')
         subscribe(err, null); // Bad username or password
         return;
     }
-    var channelId = channel[1],
-        accessReq = {
-            token: client.password,
-            chanID: channelId
-        },
-        onAuthorize = function (err, res) {
-            if (!err) {
-                subscribe(null, packet);
-            } else {
-                logger.warn('unauthorized subscribe: %s', err.message);
-                subscribe(err, null); // Bad username or password
-            }
-        };
+    resolveChannel(channel[1], function (err, channelId) {
+        if (err) {
+            logger.warn('unauthorized subscribe: %s', err.message);
+            subscribe(err, null); // Bad username or password
+            return;
+        }
+        var accessReq = accessRequest(client, channelId, 'subscribe'),
+            onAuthorize = function (err, res) {
+                if (!err) {
+                    subscribe(null, packet);
+                } else {
+                    logger.warn('unauthorized subscribe: %s', err.message);
+                    subscribe(err, null); // Bad username or password
+                }
+            };
 
-    things.canAccess(accessReq, onAuthorize);
+        canAccess(accessReq, onAuthorize);
+    });
 };
 
 aedes.authenticate = function (client, username, password, acknowledge) {
@@ -260,27 +1298,90 @@ aedes.authenticate = function (client, username, password, acknowledge) {
         identity = {
             value: pass
         },
+        cert = peerCertificate(client),
+        certThing = '',
         onIdentify = function (err, res) {
-            if (!err) {
-                client.thingId = res.value.toString() || '';
-                client.id = client.id || client.thingId;
-                client.password = pass;
-                acknowledge(null, true);
-                publishConnEvent(client.thingId, 'connect');
-            } else {
+            if (err) {
                 logger.warn('failed to authenticate client with key %s', pass);
                 err.responseCode = 4;
                 acknowledge(err, false);
+                return;
             }
+            var thingId = res.value.toString() || '';
+            acquireSession(thingId, function (err, session) {
+                if (err) {
+                    if (err.limitExceeded) {
+                        logger.warn('thing %s exceeded the maximum number of connections', thingId);
+                        err.responseCode = 5;
+                    } else {
+                        logger.warn('failed to acquire session: %s', err.message);
+                        err.responseCode = 3;
+                    }
+                    acknowledge(err, false);
+                    return;
+                }
+                client.thingId = thingId;
+                client.id = client.id || client.thingId;
+                client.password = pass;
+                client.certAuth = certThing !== '';
+                client.session = session;
+                // Connection ID matches the disconnect event with the connect
+                // event, even if the client ID is reused by the new connection.
+                client.connId = crypto.randomBytes(16).toString('hex');
+                acknowledge(null, true);
+                publishConnEvent(client.thingId, client.connId, 'connect');
+            });
         };
 
+    if (cert) {
+        certThing = client.conn.authorized ? certificateThing(cert) : '';
+        if (!certThing) {
+            logger.warn('failed to authenticate client with certificate: %s', client.conn.authorizationError || 'no thing ID');
+            var err = new Error('invalid certificate');
+            err.responseCode = 4;
+            acknowledge(err, false);
+            return;
+        }
+        checkCertificate(cert, function (err) {
+            if (err) {
+                logger.warn('failed to check certificate of thing %s: %s', certThing, err.message);
+                err.responseCode = 4;
+                acknowledge(err, false);
+                return;
+            }
+            onIdentify(null, {value: certThing});
+        });
+        return;
+    }
     things.identify(identity, onIdentify);
 };
 
+aedes.on('client', function (client) {
+    resumeSession(client);
+});
+
 aedes.on('clientDisconnect', function (client) {
     logger.info('disconnect client %s', client.id);
+    if (client.thingId && !client.clean) {
+        // Subscriptions are kept and the messages queued until the client
+        // reconnects or the session expires.
+        parkSession(client);
+    }
     client.password = null;
-    publishConnEvent(client.thingId, 'disconnect');
+    releaseSession(client.session);
+    client.session = null;
+    if (client.connId) {
+        publishConnEvent(client.thingId, client.connId, 'disconnect');
+        client.connId = null;
+    }
+    if (client.sparkplug) {
+        // Edge nodes and devices which didn't publish their death are gone
+        // with the connection.
+        Object.keys(client.sparkplug).forEach(function (key) {
+            publishSparkplugEvent(client.thingId, client.sparkplug[key], 'disconnect');
+        });
+        client.sparkplug = null;
+    }
 });
 
 aedes.on('clientError', function (client, err) {
@@ -295,7 +1396,7 @@ aedes.on('error', function (err) {
     logger.warn('aedes error: %s', err.message);
 });
 
-function publishConnEvent(id, type) {
+function publishConnEvent(id, session, type) {
     var onPublish = function (err) {
         if (err) {
             logger.warn('event publish failed: %s', err);
@@ -303,8 +1404,50 @@ function publishConnEvent(id, type) {
     };
     esclient.xadd(config.event_stream, '*',
         'thing_id', id,
+        'session_id', session,
         'timestamp', Math.round((new Date()).getTime() / 1000),
         'event_type', type,
         'instance', config.instance_id,
         onPublish);
-}
\ No newline at end of file
+}
+
+// Publishes the connectivity event of the Sparkplug B edge node or device to
+// the adapter event stream, in the same format as the connection events.
+function publishSparkplugEvent(thingId, state, type) {
+    esclient.xadd(config.event_stream, '*',
+        'thing_id', thingId,
+        'channel_id', state.channel,
+        'session_id', state.session,
+        'timestamp', Math.round((new Date()).getTime() / 1000),
+        'event_type', type,
+        'instance', config.instance_id,
+        function (err) {
+            if (err) {
+                logger.warn('event publish failed: %s', err);
+            }
+        });
+}
+
+// Publishes the connectivity event of the thing whose last will was sent, so
+// that applications track the state of the devices without parsing topics.
+function publishWillEvent(thingId, channelId, subtopic) {
+    var event = {
+        thing_id: thingId,
+        channel_id: channelId,
+        subtopic: subtopic,
+        timestamp: Math.round((new Date()).getTime() / 1000),
+        instance: config.instance_id
+    };
+    nats.publish(natsSubject(config.will_subject), JSON.stringify(event));
+    esclient.xadd(config.will_stream, '*',
+        'thing_id', event.thing_id,
+        'channel_id', event.channel_id,
+        'subtopic', event.subtopic,
+        'timestamp', event.timestamp,
+        'instance', event.instance,
+        function (err) {
+            if (err) {
+                logger.warn('will event publish failed: %s', err);
+            }
+        });
+}
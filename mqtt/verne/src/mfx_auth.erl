@@ -6,13 +6,21 @@
 -behaviour(on_register_hook).
 -behaviour(on_client_offline_hook).
 -behaviour(on_client_gone_hook).
+-behaviour(auth_on_register_m5_hook).
+-behaviour(auth_on_subscribe_m5_hook).
+-behaviour(auth_on_publish_m5_hook).
+-behaviour(on_register_m5_hook).
 
 -export([auth_on_register/5,
          auth_on_publish/6,
          auth_on_subscribe/3,
          on_register/3,
          on_client_offline/1,
-         on_client_gone/1
+         on_client_gone/1,
+         auth_on_register_m5/6,
+         auth_on_publish_m5/7,
+         auth_on_subscribe_m5/4,
+         on_register_m5/4
         ]).
 
 -include("proto/message.hrl").
@@ -42,9 +50,11 @@ identify(Password) ->
     Result.
 
 
-access(UserName, ChannelId) ->
-    error_logger:info_msg("access: ~p ~p", [UserName, ChannelId]),
-    AccessByIdReq = #{thingID => binary_to_list(UserName), chanID => binary_to_list(ChannelId)},
+%% Checks whether the thing is connected to the channel with the action
+%% allowed, where the action is either "publish" or "subscribe".
+access(UserName, ChannelId, Action) ->
+    error_logger:info_msg("access: ~p ~p ~p", [UserName, ChannelId, Action]),
+    AccessByIdReq = #{thingID => binary_to_list(UserName), chanID => binary_to_list(ChannelId), action => Action},
     Worker = poolboy:checkout(grpc_pool),
     Result = gen_server:call(Worker, {can_access_by_id, AccessByIdReq}),
     poolboy:checkin(grpc_pool, Worker),
@@ -108,17 +118,24 @@ auth_on_publish(UserName, {_MountPoint, _ClientId} = SubscriberId, QoS, Topic, P
     %% 5. return {error, whatever} -> auth chain is stopped, and message is silently dropped (unless it is a Last Will message)
     %%
 
+    publish(UserName, Topic, Payload, #{}, "").
+
+%% Publishes the message to NATS if the thing is allowed to publish to the
+%% channel. The MQTT 5 user properties are carried as the message metadata,
+%% and the content type property is used unless the topic sets it.
+publish(UserName, Topic, Payload, Metadata, DefaultContentType) ->
     % Topic is list of binaries, ex: [<<"channels">>, <<"1">>, <<"messages">>, <<"subtopic_1">>, ...]
     [{chanel_id, ChannelId}, {content_type, ContentType}, {subtopic, Subtopic}, {nats_subject, NatsSubject}] = parseTopic(Topic),
-    case access(UserName, ChannelId) of
+    case access(UserName, ChannelId, "publish") of
         ok ->
             RawMessage = #{
                 channel => ChannelId,
                 subtopic => Subtopic,
                 publisher => UserName,
                 protocol => "mqtt",
-                contentType => ContentType,
-                payload => Payload
+                contentType => content_type(ContentType, DefaultContentType),
+                payload => Payload,
+                metadata => Metadata
             },
             mfx_nats:publish(NatsSubject, message:encode_msg(RawMessage, 'mainflux.RawMessage')),
             ok;
@@ -127,6 +144,11 @@ auth_on_publish(UserName, {_MountPoint, _ClientId} = SubscriberId, QoS, Topic, P
             Other
     end.
 
+content_type("", Default) ->
+    Default;
+content_type(ContentType, _Default) ->
+    ContentType.
+
 auth_on_subscribe(UserName, ClientId, [{Topic, _QoS}|_] = Topics) ->
     error_logger:info_msg("auth_on_subscribe: ~p ~p ~p", [UserName, ClientId, Topics]),
     %% do whatever you like with the params, all that matters
@@ -136,15 +158,69 @@ auth_on_subscribe(UserName, ClientId, [{Topic, _QoS}|_] = Topics) ->
     %% 2. return 'next' -> leave it to other plugins to decide
     %% 3. return {error, whatever} -> auth chain is stopped, and no SUBACK is sent
 
-    [{chanel_id, ChannelId}, _, _, _] = parseTopic(Topic),
-    access(UserName, ChannelId).
+    [{chanel_id, ChannelId}, _, _, _] = parseTopic(shared_topic(Topic)),
+    access(UserName, ChannelId, "subscribe").
+
+%% Shared subscriptions are authorized by the topic shared by the group.
+shared_topic([<<"$share">>, _Group | Topic]) ->
+    Topic;
+shared_topic(Topic) ->
+    Topic.
+
+authorize_topics(_UserName, []) ->
+    ok;
+authorize_topics(UserName, [{Topic, _SubOpts} | Topics]) ->
+    [{chanel_id, ChannelId}, _, _, _] = parseTopic(shared_topic(Topic)),
+    case access(UserName, ChannelId, "subscribe") of
+        ok ->
+            authorize_topics(UserName, Topics);
+        Other ->
+            Other
+    end.
+
+%%% MQTT 5
+%% Topic aliases and message expiry are handled by the broker itself, so the
+%% hooks only map the errors to the reason codes and the properties to the
+%% message fields.
+
+auth_on_register_m5(Peer, SubscriberId, UserName, Password, CleanStart, Properties) ->
+    error_logger:info_msg("auth_on_register_m5: ~p ~p ~p ~p ~p ~p", [Peer, SubscriberId, UserName, Password, CleanStart, Properties]),
+    case identify(Password) of
+        {ok, _Id} ->
+            ok;
+        {error, undefined} ->
+            {error, #{reason_code => bad_username_or_password}};
+        _ ->
+            {error, #{reason_code => not_authorized}}
+    end.
+
+auth_on_publish_m5(UserName, {_MountPoint, _ClientId} = SubscriberId, QoS, Topic, Payload, IsRetain, Properties) ->
+    error_logger:info_msg("auth_on_publish_m5: ~p ~p ~p ~p ~p ~p ~p", [UserName, SubscriberId, QoS, Topic, Payload, IsRetain, Properties]),
+    Metadata = maps:from_list(maps:get(p_user_property, Properties, [])),
+    ContentType = maps:get(p_content_type, Properties, ""),
+    case publish(UserName, Topic, Payload, Metadata, ContentType) of
+        ok ->
+            ok;
+        _ ->
+            {error, #{reason_code => not_authorized}}
+    end.
+
+auth_on_subscribe_m5(UserName, ClientId, Topics, Properties) ->
+    error_logger:info_msg("auth_on_subscribe_m5: ~p ~p ~p ~p", [UserName, ClientId, Topics, Properties]),
+    case authorize_topics(UserName, Topics) of
+        ok ->
+            ok;
+        _ ->
+            {error, #{reason_code => not_authorized}}
+    end.
 
 %%% Redis ES
-publish_event(UserName, Type) ->
+publish_event(UserName, ClientId, Type) ->
     Timestamp = os:system_time(second),
     KeyValuePairs = [
         "mainflux.mqtt", "*",
         "thing_id", binary_to_list(UserName),
+        "session_id", binary_to_list(ClientId),
         "timestamp", integer_to_list(Timestamp),
         "event_type", Type
     ],
@@ -153,7 +229,10 @@ publish_event(UserName, Type) ->
 on_register(_Peer, {_Mountpoint, ClientId} = _SubscriberId, UserName) ->
     error_logger:info_msg("on_register, UserName: ~p, ClientId: ~p", [UserName, ClientId]),
     ets:insert(mfx_client_map, {ClientId, UserName}),
-    publish_event(UserName, "register").
+    publish_event(UserName, ClientId, "register").
+
+on_register_m5(Peer, SubscriberId, UserName, _Properties) ->
+    on_register(Peer, SubscriberId, UserName).
 
 publish_erase(ClientId) ->
     case ets:lookup(mfx_client_map, ClientId) of
@@ -162,7 +241,7 @@ publish_erase(ClientId) ->
             error;
         [{ClientId, UserName}] ->
             ets:delete_object(mfx_client_map, {ClientId, UserName}),
-            publish_event(UserName, "deregister")
+            publish_event(UserName, ClientId, "deregister")
     end.
 
 on_client_offline({_Mountpoint, ClientId} = _SubscriberId) ->
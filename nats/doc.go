@@ -0,0 +1,7 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package nats contains the NATS connection factory shared by the services,
+// which supports NATS 2.x authentication, TLS and the subject prefix that
+// separates the deployments sharing the NATS cluster.
+package nats
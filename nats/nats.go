@@ -0,0 +1,102 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package nats
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	nats "github.com/nats-io/nats.go"
+)
+
+var (
+	// ErrConflictingAuth indicates that both the credentials file and the
+	// NKey seed are set.
+	ErrConflictingAuth = errors.New("credentials file and NKey seed are mutually exclusive")
+
+	// ErrMissingClientKey indicates that only one of the client certificate
+	// and the client key is set.
+	ErrMissingClientKey = errors.New("client certificate and key must be set together")
+
+	// ErrInvalidPrefix indicates that the subject prefix is not a valid
+	// sequence of NATS subject tokens.
+	ErrInvalidPrefix = errors.New("invalid subject prefix")
+)
+
+// Config represents the NATS connection configuration.
+type Config struct {
+	URL        string
+	Creds      string
+	NKeySeed   string
+	CACerts    string
+	ClientCert string
+	ClientKey  string
+	Prefix     string
+}
+
+// Connect connects to NATS. The user is authenticated either with the
+// credentials file, containing the user JWT and NKey seed, or with the NKey
+// seed file, besides the user and password or token set in the URL. TLS is
+// turned on by the tls:// URL scheme or by setting the CA certificates or
+// the client certificate.
+func Connect(cfg Config, opts ...nats.Option) (*nats.Conn, error) {
+	if err := validatePrefix(cfg.Prefix); err != nil {
+		return nil, err
+	}
+
+	if cfg.Creds != "" && cfg.NKeySeed != "" {
+		return nil, ErrConflictingAuth
+	}
+
+	if (cfg.ClientCert == "") != (cfg.ClientKey == "") {
+		return nil, ErrMissingClientKey
+	}
+
+	if cfg.Creds != "" {
+		opts = append(opts, nats.UserCredentials(cfg.Creds))
+	}
+
+	if cfg.NKeySeed != "" {
+		opt, err := nats.NkeyOptionFromSeed(cfg.NKeySeed)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, opt)
+	}
+
+	if cfg.CACerts != "" {
+		opts = append(opts, nats.RootCAs(cfg.CACerts))
+	}
+
+	if cfg.ClientCert != "" {
+		opts = append(opts, nats.ClientCert(cfg.ClientCert, cfg.ClientKey))
+	}
+
+	return nats.Connect(cfg.URL, opts...)
+}
+
+// Subject returns the subject prefixed with the deployment subject prefix,
+// or the subject itself if the prefix is empty.
+func Subject(prefix, subject string) string {
+	if prefix == "" {
+		return subject
+	}
+
+	return fmt.Sprintf("%s.%s", prefix, subject)
+}
+
+func validatePrefix(prefix string) error {
+	if prefix == "" {
+		return nil
+	}
+
+	for _, token := range strings.Split(prefix, ".") {
+		if token == "" || token == "*" || token == ">" || strings.ContainsAny(token, " \t\r\n") {
+			return ErrInvalidPrefix
+		}
+	}
+
+	return nil
+}
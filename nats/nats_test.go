@@ -0,0 +1,84 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package nats_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux/nats"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubject(t *testing.T) {
+	cases := []struct {
+		desc    string
+		prefix  string
+		subject string
+		res     string
+	}{
+		{
+			desc:    "subject without prefix",
+			prefix:  "",
+			subject: "channel.1",
+			res:     "channel.1",
+		},
+		{
+			desc:    "subject with prefix",
+			prefix:  "tenant-1",
+			subject: "channel.1",
+			res:     "tenant-1.channel.1",
+		},
+		{
+			desc:    "wildcard subject with multi-token prefix",
+			prefix:  "eu.tenant-1",
+			subject: "channel.>",
+			res:     "eu.tenant-1.channel.>",
+		},
+	}
+
+	for _, tc := range cases {
+		res := nats.Subject(tc.prefix, tc.subject)
+		assert.Equal(t, tc.res, res, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.res, res))
+	}
+}
+
+func TestConnectInvalidConfig(t *testing.T) {
+	cases := []struct {
+		desc string
+		cfg  nats.Config
+		err  error
+	}{
+		{
+			desc: "connect with wildcard prefix",
+			cfg:  nats.Config{Prefix: "tenant.*"},
+			err:  nats.ErrInvalidPrefix,
+		},
+		{
+			desc: "connect with prefix containing empty token",
+			cfg:  nats.Config{Prefix: "tenant..1"},
+			err:  nats.ErrInvalidPrefix,
+		},
+		{
+			desc: "connect with prefix ending with dot",
+			cfg:  nats.Config{Prefix: "tenant."},
+			err:  nats.ErrInvalidPrefix,
+		},
+		{
+			desc: "connect with both credentials file and NKey seed",
+			cfg:  nats.Config{Creds: "user.creds", NKeySeed: "user.nk"},
+			err:  nats.ErrConflictingAuth,
+		},
+		{
+			desc: "connect with client certificate without key",
+			cfg:  nats.Config{ClientCert: "client.crt"},
+			err:  nats.ErrMissingClientKey,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := nats.Connect(tc.cfg)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
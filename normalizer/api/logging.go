@@ -5,6 +5,7 @@ package api
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/mainflux/mainflux"
@@ -30,11 +31,18 @@ func LoggingMiddleware(svc normalizer.Service, logger logger.Logger) normalizer.
 func (lm loggingMiddleware) Normalize(msg mainflux.RawMessage) (nd normalizer.NormalizedData, err error) {
 	defer func(begin time.Time) {
 		message := fmt.Sprintf("Method normalize took %s to complete", time.Since(begin))
+		if ve, ok := err.(*normalizer.ValidationError); ok {
+			lm.logger.Warn(fmt.Sprintf("Message from %s on channel %s is invalid: %s.", msg.Publisher, msg.Channel, strings.Join(ve.Violations, "; ")))
+			return
+		}
 		if err != nil {
 			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
 			return
 		}
 		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+		for _, s := range nd.Skewed {
+			lm.logger.Warn(fmt.Sprintf("Message from %s on channel %s is %s in the %s; %s.", s.Publisher, s.Channel, s.Offset, s.Direction, s.Action))
+		}
 	}(time.Now())
 
 	return lm.svc.Normalize(msg)
@@ -16,23 +16,34 @@ var _ normalizer.Service = (*metricsMiddleware)(nil)
 type metricsMiddleware struct {
 	counter metrics.Counter
 	latency metrics.Histogram
+	skewed  metrics.Counter
+	invalid metrics.Counter
 	svc     normalizer.Service
 }
 
-// MetricsMiddleware instruments core service by tracking request count and
-// latency.
-func MetricsMiddleware(svc normalizer.Service, counter metrics.Counter, latency metrics.Histogram) normalizer.Service {
+// MetricsMiddleware instruments core service by tracking request count,
+// latency, the number of messages with skewed timestamps and the number of
+// messages whose payloads don't satisfy the payload schema.
+func MetricsMiddleware(svc normalizer.Service, counter metrics.Counter, latency metrics.Histogram, skewed, invalid metrics.Counter) normalizer.Service {
 	return &metricsMiddleware{
 		counter: counter,
 		latency: latency,
+		skewed:  skewed,
+		invalid: invalid,
 		svc:     svc,
 	}
 }
 
-func (mm *metricsMiddleware) Normalize(msg mainflux.RawMessage) (normalizer.NormalizedData, error) {
+func (mm *metricsMiddleware) Normalize(msg mainflux.RawMessage) (nd normalizer.NormalizedData, err error) {
 	defer func(begin time.Time) {
 		mm.counter.With("method", "normalize").Add(1)
 		mm.latency.With("method", "normalize").Observe(time.Since(begin).Seconds())
+		for _, s := range nd.Skewed {
+			mm.skewed.With("protocol", msg.Protocol, "direction", s.Direction, "action", s.Action).Add(1)
+		}
+		if _, ok := err.(*normalizer.ValidationError); ok {
+			mm.invalid.With("protocol", msg.Protocol).Add(1)
+		}
 	}(time.Now())
 
 	return mm.svc.Normalize(msg)
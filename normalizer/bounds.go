@@ -0,0 +1,91 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package normalizer
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	// Reject policy drops messages whose timestamps are out of bounds.
+	Reject = "reject"
+	// Clamp policy moves out of bounds timestamps to the nearest bound.
+	Clamp = "clamp"
+
+	// Past marks timestamps older than the allowed message age.
+	Past = "past"
+	// Future marks timestamps ahead of the allowed clock skew.
+	Future = "future"
+)
+
+// ErrInvalidPolicy indicates unknown time bounds policy.
+var ErrInvalidPolicy = errors.New("invalid time bounds policy")
+
+// TimeBounds specifies the allowed range of message timestamps relative to
+// the time of normalization. Zero duration disables the corresponding bound.
+type TimeBounds struct {
+	// MaxAge is the maximum allowed age of the message.
+	MaxAge time.Duration
+	// MaxSkew is the maximum allowed distance of the message timestamp
+	// ahead of the current time.
+	MaxSkew time.Duration
+	// Policy specifies what happens to out of bounds messages.
+	Policy string
+}
+
+// Validate returns an error if time bounds are not well-formed.
+func (tb TimeBounds) Validate() error {
+	if tb.MaxAge < 0 || tb.MaxSkew < 0 {
+		return ErrInvalidPolicy
+	}
+
+	switch tb.Policy {
+	case Reject, Clamp:
+		return nil
+	default:
+		return ErrInvalidPolicy
+	}
+}
+
+// Skew contains data about message published with a timestamp that is out
+// of the configured time bounds.
+type Skew struct {
+	Channel   string
+	Publisher string
+	Direction string
+	Action    string
+	Offset    time.Duration
+}
+
+// check returns the adjusted message time and the skew description if the
+// time is out of bounds. Message should be dropped if the returned skew
+// action is Reject.
+func (tb TimeBounds) check(t float64, now time.Time) (float64, *Skew) {
+	secs := float64(now.UnixNano()) / 1e9
+
+	if tb.MaxAge > 0 {
+		min := secs - tb.MaxAge.Seconds()
+		if t < min {
+			return min, &Skew{
+				Direction: Past,
+				Action:    tb.Policy,
+				Offset:    time.Duration((secs - t) * 1e9),
+			}
+		}
+	}
+
+	if tb.MaxSkew > 0 {
+		max := secs + tb.MaxSkew.Seconds()
+		if t > max {
+			return max, &Skew{
+				Direction: Future,
+				Action:    tb.Policy,
+				Offset:    time.Duration((t - secs) * 1e9),
+			}
+		}
+	}
+
+	return t, nil
+}
@@ -4,57 +4,90 @@
 package nats
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/gogo/protobuf/proto"
 	"github.com/mainflux/mainflux"
 	log "github.com/mainflux/mainflux/logger"
+	mfnats "github.com/mainflux/mainflux/nats"
 	"github.com/mainflux/mainflux/normalizer"
-	"github.com/nats-io/go-nats"
+	"github.com/mainflux/mainflux/tracing"
+	"github.com/nats-io/nats.go"
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
 )
 
 const (
 	queue         = "normalizers"
 	input         = "channel.>"
 	outputUnknown = "out.unknown"
-	senML         = "application/senml+json"
 )
 
 type pubsub struct {
-	nc     *nats.Conn
-	svc    normalizer.Service
-	logger log.Logger
+	nc            *nats.Conn
+	subjectPrefix string
+	dlq           string
+	svc           normalizer.Service
+	tracer        opentracing.Tracer
+	logger        log.Logger
 }
 
-// Subscribe to appropriate NATS topic and normalizes received messages.
-func Subscribe(svc normalizer.Service, nc *nats.Conn, logger log.Logger) {
+// Subscribe to appropriate NATS topic and normalizes received messages. The
+// subjects are prefixed with the deployment subject prefix, unless it is
+// empty. Messages whose payloads don't satisfy the payload schema are
+// published unchanged to the dead letter subject, unless it is empty, and
+// dropped otherwise. Normalization of each message is traced following the
+// span of its publisher.
+func Subscribe(svc normalizer.Service, nc *nats.Conn, subjectPrefix, dlq string, tracer opentracing.Tracer, logger log.Logger) {
 	ps := pubsub{
-		nc:     nc,
-		svc:    svc,
-		logger: logger,
+		nc:            nc,
+		subjectPrefix: subjectPrefix,
+		dlq:           dlq,
+		svc:           svc,
+		tracer:        tracer,
+		logger:        logger,
 	}
-	ps.nc.QueueSubscribe(input, queue, ps.handleMsg)
+	ps.nc.QueueSubscribe(mfnats.Subject(subjectPrefix, input), queue, ps.handleMsg)
 }
 
 func (ps pubsub) handleMsg(m *nats.Msg) {
 	var msg mainflux.RawMessage
-	if err := proto.Unmarshal(m.Data, &msg); err != nil {
+	err := proto.Unmarshal(m.Data, &msg)
+
+	span := tracing.StartRawSpan(ps.tracer, "normalize", m, msg)
+	defer span.Finish()
+	ctx := opentracing.ContextWithSpan(context.Background(), span)
+
+	if err != nil {
+		ext.Error.Set(span, true)
 		ps.logger.Warn(fmt.Sprintf("Unmarshalling failed: %s", err))
 		return
 	}
+	span.SetTag("channel", msg.GetChannel())
+
+	if err := ps.publish(ctx, msg); err != nil {
+		ext.Error.Set(span, true)
+		span.LogKV("error", err.Error())
+		if _, ok := err.(*normalizer.ValidationError); ok {
+			ps.deadLetter(m.Data)
+			return
+		}
 
-	if err := ps.publish(msg); err != nil {
 		ps.logger.Warn(fmt.Sprintf("Publishing failed: %s", err))
 		return
 	}
 }
 
-func (ps pubsub) publish(msg mainflux.RawMessage) error {
+func (ps pubsub) publish(ctx context.Context, msg mainflux.RawMessage) error {
 	output := mainflux.OutputSenML
 	normalized, err := ps.svc.Normalize(msg)
+	if _, ok := err.(*normalizer.ValidationError); ok {
+		return err
+	}
 	if err != nil {
 		switch ct := msg.ContentType; ct {
-		case senML:
+		case mainflux.SenMLJSON, mainflux.SenMLCBOR:
 			return err
 		case "":
 			output = outputUnknown
@@ -62,7 +95,7 @@ func (ps pubsub) publish(msg mainflux.RawMessage) error {
 			output = fmt.Sprintf("out.%s", ct)
 		}
 
-		if err := ps.nc.Publish(output, msg.GetPayload()); err != nil {
+		if err := tracing.Publish(ctx, ps.nc, mfnats.Subject(ps.subjectPrefix, output), msg.GetPayload()); err != nil {
 			ps.logger.Warn(fmt.Sprintf("Publishing failed: %s", err))
 			return err
 		}
@@ -75,7 +108,7 @@ func (ps pubsub) publish(msg mainflux.RawMessage) error {
 			return err
 		}
 
-		if err := ps.nc.Publish(output, data); err != nil {
+		if err := tracing.Publish(ctx, ps.nc, mfnats.Subject(ps.subjectPrefix, output), data); err != nil {
 			ps.logger.Warn(fmt.Sprintf("Publishing failed: %s", err))
 			return err
 		}
@@ -83,3 +116,15 @@ func (ps pubsub) publish(msg mainflux.RawMessage) error {
 
 	return nil
 }
+
+// deadLetter publishes the raw message to the dead letter subject, so that
+// the invalid messages can be inspected and replayed.
+func (ps pubsub) deadLetter(data []byte) {
+	if ps.dlq == "" {
+		return
+	}
+
+	if err := ps.nc.Publish(mfnats.Subject(ps.subjectPrefix, ps.dlq), data); err != nil {
+		ps.logger.Warn(fmt.Sprintf("Dead lettering failed: %s", err))
+	}
+}
@@ -5,38 +5,71 @@ package normalizer
 
 import (
 	"strings"
+	"time"
 
 	"github.com/cisco/senml"
 	"github.com/mainflux/mainflux"
 )
 
-var formats = map[string]senml.Format{
-	mainflux.SenMLJSON: senml.JSON,
-	mainflux.SenMLCBOR: senml.CBOR,
+type normalizer struct {
+	bounds       TimeBounds
+	channels     Channels
+	transformers map[string]Transformer
+	validator    Validator
 }
 
-type normalizer struct{}
-
-// New returns normalizer service implementation.
-func New() Service {
-	return normalizer{}
+// New returns normalizer service implementation. JSON payloads are validated
+// by the provided validator, unless it's nil. Payloads are converted to SenML
+// by the transformer of the channel payload format, and the records are
+// checked against the provided time bounds.
+func New(bounds TimeBounds, channels Channels, transformers map[string]Transformer, validator Validator) Service {
+	return normalizer{
+		bounds:       bounds,
+		channels:     channels,
+		transformers: transformers,
+		validator:    validator,
+	}
 }
 
 func (n normalizer) Normalize(msg mainflux.RawMessage) (NormalizedData, error) {
-	format, ok := formats[msg.ContentType]
+	cfg := n.channels.Config(msg.Channel)
+	if cfg.Format == "" {
+		cfg.Format = SenML
+	}
+
+	tr, ok := n.transformers[cfg.Format]
 	if !ok {
-		format = senml.JSON
+		return NormalizedData{}, ErrUnknownFormat
+	}
+
+	if n.validator != nil && isJSON(msg, cfg) {
+		if err := n.validator.Validate(msg); err != nil {
+			return NormalizedData{}, err
+		}
 	}
 
-	raw, err := senml.Decode(msg.Payload, format)
+	raw, err := tr.Transform(msg, cfg)
 	if err != nil {
 		return NormalizedData{}, err
 	}
 
 	normalized := senml.Normalize(raw)
+	now := time.Now()
+
+	msgs := []mainflux.Message{}
+	skewed := []Skew{}
+	for _, v := range normalized.Records {
+		t, skew := n.bounds.check(v.Time, now)
+		if skew != nil {
+			skew.Channel = msg.Channel
+			skew.Publisher = msg.Publisher
+			skewed = append(skewed, *skew)
+			if skew.Action == Reject {
+				continue
+			}
+			v.Time = t
+		}
 
-	msgs := make([]mainflux.Message, len(normalized.Records))
-	for k, v := range normalized.Records {
 		m := mainflux.Message{
 			Channel:    msg.Channel,
 			Subtopic:   msg.Subtopic,
@@ -47,6 +80,7 @@ func (n normalizer) Normalize(msg mainflux.RawMessage) (NormalizedData, error) {
 			Time:       v.Time,
 			UpdateTime: v.UpdateTime,
 			Link:       v.Link,
+			Sequence:   msg.Sequence,
 		}
 
 		switch {
@@ -64,7 +98,7 @@ func (n normalizer) Normalize(msg mainflux.RawMessage) (NormalizedData, error) {
 			m.ValueSum = &mainflux.SumValue{Value: *v.Sum}
 		}
 
-		msgs[k] = m
+		msgs = append(msgs, m)
 	}
 
 	output := strings.ToLower(msg.ContentType)
@@ -72,5 +106,20 @@ func (n normalizer) Normalize(msg mainflux.RawMessage) (NormalizedData, error) {
 	return NormalizedData{
 		ContentType: output,
 		Messages:    msgs,
+		Skewed:      skewed,
 	}, nil
 }
+
+// isJSON reports whether the message payload is encoded as JSON, which is
+// the case for the JSON channels and the SenML channels, unless the message
+// is SenML CBOR.
+func isJSON(msg mainflux.RawMessage, cfg Config) bool {
+	switch cfg.Format {
+	case JSON:
+		return true
+	case SenML:
+		return msg.ContentType != mainflux.SenMLCBOR
+	default:
+		return false
+	}
+}
@@ -12,7 +12,10 @@ type Service interface {
 }
 
 // NormalizedData contains normalized messages and their content type.
+// Messages with timestamps out of the configured time bounds are listed
+// in skewed.
 type NormalizedData struct {
 	ContentType string
 	Messages    []mainflux.Message
+	Skewed      []Skew
 }
@@ -0,0 +1,76 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package things contains the normalizer channels and validator
+// implementations, which look up the payload configuration and the payload
+// schemas of the channels in the things service.
+package things
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	log "github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/normalizer"
+)
+
+var _ normalizer.Channels = (*channels)(nil)
+
+type cachedConfig struct {
+	cfg     normalizer.Config
+	checked time.Time
+}
+
+type channels struct {
+	things  mainflux.ThingsServiceClient
+	refresh time.Duration
+	logger  log.Logger
+	mu      sync.RWMutex
+	configs map[string]cachedConfig
+}
+
+// New returns normalizer channels backed by the things service. The channel
+// configuration is looked up at most once per refresh interval.
+func New(things mainflux.ThingsServiceClient, refresh time.Duration, logger log.Logger) normalizer.Channels {
+	return &channels{
+		things:  things,
+		refresh: refresh,
+		logger:  logger,
+		configs: make(map[string]cachedConfig),
+	}
+}
+
+// Config returns the payload configuration of the channel. If the lookup
+// fails, the last known configuration is used, and the channels of the
+// unknown configuration are treated as SenML ones.
+func (c *channels) Config(channel string) normalizer.Config {
+	c.mu.RLock()
+	cached, ok := c.configs[channel]
+	c.mu.RUnlock()
+	if ok && time.Since(cached.checked) < c.refresh {
+		return cached.cfg
+	}
+
+	cached.checked = time.Now()
+
+	res, err := c.things.Transformer(context.Background(), &mainflux.ChannelID{Value: channel})
+	if err != nil {
+		c.logger.Warn(fmt.Sprintf("Failed to retrieve transformer of channel %s: %s", channel, err))
+	} else {
+		cached.cfg = normalizer.Config{
+			Format:  res.GetFormat(),
+			Schema:  res.GetSchema(),
+			Message: res.GetMessage(),
+			Time:    res.GetTime(),
+		}
+	}
+
+	c.mu.Lock()
+	c.configs[channel] = cached
+	c.mu.Unlock()
+
+	return cached.cfg
+}
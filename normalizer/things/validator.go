@@ -0,0 +1,103 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package things
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	log "github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/normalizer"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+var _ normalizer.Validator = (*validator)(nil)
+
+type cachedSchema struct {
+	schema  *gojsonschema.Schema
+	checked time.Time
+}
+
+type validator struct {
+	things  mainflux.ThingsServiceClient
+	refresh time.Duration
+	logger  log.Logger
+	mu      sync.RWMutex
+	schemas map[string]cachedSchema
+}
+
+// NewValidator returns normalizer validator backed by the payload schemas
+// registered in the things service. The schema of the channel subtopic is
+// looked up at most once per refresh interval.
+func NewValidator(things mainflux.ThingsServiceClient, refresh time.Duration, logger log.Logger) normalizer.Validator {
+	return &validator{
+		things:  things,
+		refresh: refresh,
+		logger:  logger,
+		schemas: make(map[string]cachedSchema),
+	}
+}
+
+// Validate validates the payload against the schema of the message channel
+// subtopic. If the lookup fails, the last known schema is used. Payloads that
+// aren't valid JSON don't satisfy any schema.
+func (v *validator) Validate(msg mainflux.RawMessage) error {
+	schema := v.schema(msg.Channel, msg.Subtopic)
+	if schema == nil {
+		return nil
+	}
+
+	res, err := schema.Validate(gojsonschema.NewBytesLoader(msg.Payload))
+	if err != nil {
+		return &normalizer.ValidationError{Violations: []string{"payload isn't valid JSON"}}
+	}
+
+	if res.Valid() {
+		return nil
+	}
+
+	violations := []string{}
+	for _, e := range res.Errors() {
+		violations = append(violations, e.String())
+	}
+
+	return &normalizer.ValidationError{Violations: violations}
+}
+
+func (v *validator) schema(channel, subtopic string) *gojsonschema.Schema {
+	key := fmt.Sprintf("%s:%s", channel, subtopic)
+
+	v.mu.RLock()
+	cached, ok := v.schemas[key]
+	v.mu.RUnlock()
+	if ok && time.Since(cached.checked) < v.refresh {
+		return cached.schema
+	}
+
+	cached.checked = time.Now()
+
+	res, err := v.things.PayloadSchema(context.Background(), &mainflux.PayloadSchemaReq{ChanID: channel, Subtopic: subtopic})
+	switch {
+	case err != nil:
+		v.logger.Warn(fmt.Sprintf("Failed to retrieve payload schema of channel %s: %s", channel, err))
+	case res.GetDefinition() == "":
+		cached.schema = nil
+	default:
+		schema, err := gojsonschema.NewSchema(gojsonschema.NewStringLoader(res.GetDefinition()))
+		if err != nil {
+			v.logger.Warn(fmt.Sprintf("Failed to compile payload schema of channel %s: %s", channel, err))
+			break
+		}
+		cached.schema = schema
+	}
+
+	v.mu.Lock()
+	v.schemas[key] = cached
+	v.mu.Unlock()
+
+	return cached.schema
+}
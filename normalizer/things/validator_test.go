@@ -0,0 +1,96 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package things_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/normalizer"
+	"github.com/mainflux/mainflux/normalizer/things"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+)
+
+const (
+	chanID  = "1"
+	failing = "2"
+	schema  = `{"type":"object","required":["temperature"],"properties":{"temperature":{"type":"number"}}}`
+)
+
+// thingsClient serves the payload schemas keyed by the channel and the
+// subtopic, failing the lookups of the failing channel.
+type thingsClient struct {
+	mainflux.ThingsServiceClient
+	schemas map[string]string
+}
+
+func (tc thingsClient) PayloadSchema(_ context.Context, req *mainflux.PayloadSchemaReq, _ ...grpc.CallOption) (*mainflux.PayloadSchema, error) {
+	if req.GetChanID() == failing {
+		return nil, errors.New("unavailable")
+	}
+
+	return &mainflux.PayloadSchema{Definition: tc.schemas[req.GetChanID()+":"+req.GetSubtopic()]}, nil
+}
+
+func (tc thingsClient) KeyDigest(context.Context, *mainflux.ThingID, ...grpc.CallOption) (*mainflux.KeyDigest, error) {
+	panic("not implemented")
+}
+
+func TestValidate(t *testing.T) {
+	logger, err := logger.New(ioutil.Discard, "info")
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	tc := thingsClient{schemas: map[string]string{chanID + ":devices": schema}}
+	v := things.NewValidator(tc, time.Minute, logger)
+
+	cases := []struct {
+		desc  string
+		msg   mainflux.RawMessage
+		valid bool
+	}{
+		{
+			desc:  "validate valid payload",
+			msg:   mainflux.RawMessage{Channel: chanID, Subtopic: "devices", Payload: []byte(`{"temperature":21.5}`)},
+			valid: true,
+		},
+		{
+			desc:  "validate payload missing required field",
+			msg:   mainflux.RawMessage{Channel: chanID, Subtopic: "devices", Payload: []byte(`{"humidity":40}`)},
+			valid: false,
+		},
+		{
+			desc:  "validate payload with field of wrong type",
+			msg:   mainflux.RawMessage{Channel: chanID, Subtopic: "devices", Payload: []byte(`{"temperature":"hot"}`)},
+			valid: false,
+		},
+		{
+			desc:  "validate malformed payload",
+			msg:   mainflux.RawMessage{Channel: chanID, Subtopic: "devices", Payload: []byte(`{`)},
+			valid: false,
+		},
+		{
+			desc:  "validate payload of subtopic without schema",
+			msg:   mainflux.RawMessage{Channel: chanID, Subtopic: "sensors", Payload: []byte(`{"humidity":40}`)},
+			valid: true,
+		},
+		{
+			desc:  "validate payload of channel with failing lookup",
+			msg:   mainflux.RawMessage{Channel: failing, Subtopic: "devices", Payload: []byte(`{"humidity":40}`)},
+			valid: true,
+		},
+	}
+
+	for _, tc := range cases {
+		err := v.Validate(tc.msg)
+		_, invalid := err.(*normalizer.ValidationError)
+		assert.Equal(t, !tc.valid, invalid, fmt.Sprintf("%s: expected valid %t got error %s", tc.desc, tc.valid, err))
+	}
+}
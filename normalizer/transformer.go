@@ -0,0 +1,65 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package normalizer
+
+import (
+	"errors"
+
+	"github.com/cisco/senml"
+	"github.com/mainflux/mainflux"
+)
+
+const (
+	// SenML format is the SenML pack, encoded as JSON or CBOR depending on
+	// the message content type.
+	SenML = "senml"
+	// JSON format is the arbitrary JSON object or the array of objects.
+	JSON = "json"
+	// CBOR format is the arbitrary CBOR map or the array of maps.
+	CBOR = "cbor"
+	// Protobuf format is the message of the registered Protobuf schema.
+	Protobuf = "protobuf"
+)
+
+var (
+	// ErrUnknownFormat indicates that there is no transformer of the
+	// channel payload format.
+	ErrUnknownFormat = errors.New("unknown payload format")
+
+	// ErrMalformedPayload indicates that the payload can't be decoded in the
+	// channel payload format.
+	ErrMalformedPayload = errors.New("malformed payload")
+
+	// ErrUnknownSchema indicates that the channel refers to the Protobuf
+	// schema or message type which is not registered.
+	ErrUnknownSchema = errors.New("unknown payload schema")
+)
+
+// Config specifies how the payloads of the channel messages are structured.
+// Schema and message name the registered Protobuf schema and its message
+// type, while time names the payload field holding the message time. Empty
+// format stands for SenML.
+type Config struct {
+	Format  string
+	Schema  string
+	Message string
+	Time    string
+}
+
+// Transformer converts the message payload to the SenML pack, so that the
+// records are normalized and checked against the time bounds in the same way
+// regardless of the payload format.
+type Transformer interface {
+	// Transform decodes the payload of the message according to the
+	// channel configuration.
+	Transform(mainflux.RawMessage, Config) (senml.SenML, error)
+}
+
+// Channels specifies an API for retrieving the payload configuration of the
+// channels.
+type Channels interface {
+	// Config returns the configuration of the channel having the provided
+	// ID. Channels of the unknown configuration are treated as SenML ones.
+	Config(string) Config
+}
@@ -0,0 +1,37 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package transformers
+
+import (
+	"reflect"
+
+	"github.com/cisco/senml"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/normalizer"
+	"github.com/ugorji/go/codec"
+)
+
+var _ normalizer.Transformer = (*cborTransformer)(nil)
+
+type cborTransformer struct {
+	handle *codec.CborHandle
+}
+
+// NewCBOR returns the transformer of the CBOR maps and arrays of maps. Map
+// keys have to be text strings.
+func NewCBOR() normalizer.Transformer {
+	h := &codec.CborHandle{}
+	h.MapType = reflect.TypeOf(map[string]interface{}{})
+
+	return cborTransformer{handle: h}
+}
+
+func (ct cborTransformer) Transform(msg mainflux.RawMessage, cfg normalizer.Config) (senml.SenML, error) {
+	var payload interface{}
+	if err := codec.NewDecoderBytes(msg.Payload, ct.handle).Decode(&payload); err != nil {
+		return senml.SenML{}, normalizer.ErrMalformedPayload
+	}
+
+	return toSenML(payload, cfg.Time)
+}
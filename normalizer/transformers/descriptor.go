@@ -0,0 +1,100 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package transformers
+
+import "github.com/golang/protobuf/proto"
+
+// The descriptor types below hold the subset of the google.protobuf
+// FileDescriptorSet needed to decode the messages of the schema.
+
+const (
+	typeDouble   = 1
+	typeFloat    = 2
+	typeInt64    = 3
+	typeUint64   = 4
+	typeInt32    = 5
+	typeFixed64  = 6
+	typeFixed32  = 7
+	typeBool     = 8
+	typeString   = 9
+	typeMessage  = 11
+	typeBytes    = 12
+	typeUint32   = 13
+	typeEnum     = 14
+	typeSfixed32 = 15
+	typeSfixed64 = 16
+	typeSint32   = 17
+	typeSint64   = 18
+
+	labelRepeated = 3
+)
+
+type fileDescriptorSet struct {
+	File []*fileDescriptor `protobuf:"bytes,1,rep,name=file"`
+}
+
+func (m *fileDescriptorSet) Reset()         { *m = fileDescriptorSet{} }
+func (m *fileDescriptorSet) String() string { return proto.CompactTextString(m) }
+func (*fileDescriptorSet) ProtoMessage()    {}
+
+type fileDescriptor struct {
+	Name        string            `protobuf:"bytes,1,opt,name=name,proto3"`
+	Package     string            `protobuf:"bytes,2,opt,name=package,proto3"`
+	MessageType []*descriptor     `protobuf:"bytes,4,rep,name=message_type"`
+	EnumType    []*enumDescriptor `protobuf:"bytes,5,rep,name=enum_type"`
+}
+
+func (m *fileDescriptor) Reset()         { *m = fileDescriptor{} }
+func (m *fileDescriptor) String() string { return proto.CompactTextString(m) }
+func (*fileDescriptor) ProtoMessage()    {}
+
+type descriptor struct {
+	Name       string             `protobuf:"bytes,1,opt,name=name,proto3"`
+	Field      []*fieldDescriptor `protobuf:"bytes,2,rep,name=field"`
+	NestedType []*descriptor      `protobuf:"bytes,3,rep,name=nested_type"`
+	EnumType   []*enumDescriptor  `protobuf:"bytes,4,rep,name=enum_type"`
+	Options    *messageOptions    `protobuf:"bytes,7,opt,name=options"`
+}
+
+func (m *descriptor) Reset()         { *m = descriptor{} }
+func (m *descriptor) String() string { return proto.CompactTextString(m) }
+func (*descriptor) ProtoMessage()    {}
+
+type messageOptions struct {
+	MapEntry bool `protobuf:"varint,7,opt,name=map_entry,proto3"`
+}
+
+func (m *messageOptions) Reset()         { *m = messageOptions{} }
+func (m *messageOptions) String() string { return proto.CompactTextString(m) }
+func (*messageOptions) ProtoMessage()    {}
+
+type fieldDescriptor struct {
+	Name     string `protobuf:"bytes,1,opt,name=name,proto3"`
+	Number   int32  `protobuf:"varint,3,opt,name=number,proto3"`
+	Label    int32  `protobuf:"varint,4,opt,name=label,proto3"`
+	Type     int32  `protobuf:"varint,5,opt,name=type,proto3"`
+	TypeName string `protobuf:"bytes,6,opt,name=type_name,proto3"`
+}
+
+func (m *fieldDescriptor) Reset()         { *m = fieldDescriptor{} }
+func (m *fieldDescriptor) String() string { return proto.CompactTextString(m) }
+func (*fieldDescriptor) ProtoMessage()    {}
+
+type enumDescriptor struct {
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3"`
+	Value []*enumValueDescriptor `protobuf:"bytes,2,rep,name=value"`
+}
+
+func (m *enumDescriptor) Reset()         { *m = enumDescriptor{} }
+func (m *enumDescriptor) String() string { return proto.CompactTextString(m) }
+func (*enumDescriptor) ProtoMessage()    {}
+
+type enumValueDescriptor struct {
+	Name   string `protobuf:"bytes,1,opt,name=name,proto3"`
+	Number int32  `protobuf:"varint,2,opt,name=number,proto3"`
+}
+
+func (m *enumValueDescriptor) Reset()         { *m = enumValueDescriptor{} }
+func (m *enumValueDescriptor) String() string { return proto.CompactTextString(m) }
+func (*enumValueDescriptor) ProtoMessage()    {}
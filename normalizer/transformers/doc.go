@@ -0,0 +1,7 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package transformers contains the payload transformers of the formats
+// supported by the normalizer. Formats other than SenML are converted to
+// SenML records named by the paths of the payload fields.
+package transformers
@@ -0,0 +1,30 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package transformers
+
+import (
+	"encoding/json"
+
+	"github.com/cisco/senml"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/normalizer"
+)
+
+var _ normalizer.Transformer = (*jsonTransformer)(nil)
+
+type jsonTransformer struct{}
+
+// NewJSON returns the transformer of the JSON objects and arrays of objects.
+func NewJSON() normalizer.Transformer {
+	return jsonTransformer{}
+}
+
+func (jsonTransformer) Transform(msg mainflux.RawMessage, cfg normalizer.Config) (senml.SenML, error) {
+	var payload interface{}
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		return senml.SenML{}, normalizer.ErrMalformedPayload
+	}
+
+	return toSenML(payload, cfg.Time)
+}
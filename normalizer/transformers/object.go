@@ -0,0 +1,138 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package transformers
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/cisco/senml"
+	"github.com/mainflux/mainflux/normalizer"
+)
+
+// separator joins the names of the nested fields into the record name.
+const separator = "/"
+
+// toSenML converts the decoded payload, which is either the object or the
+// array of objects, to the SenML pack. Every scalar field of the object is
+// the record named by the path of the field, while the field named by the
+// time field is the time of all the records of the object. Null fields are
+// skipped.
+func toSenML(payload interface{}, timeField string) (senml.SenML, error) {
+	var objs []interface{}
+	switch p := payload.(type) {
+	case map[string]interface{}:
+		objs = []interface{}{p}
+	case []interface{}:
+		objs = p
+	default:
+		return senml.SenML{}, normalizer.ErrMalformedPayload
+	}
+
+	pack := senml.SenML{}
+	for _, o := range objs {
+		obj, ok := o.(map[string]interface{})
+		if !ok {
+			return senml.SenML{}, normalizer.ErrMalformedPayload
+		}
+
+		var t float64
+		if timeField != "" {
+			if v, ok := obj[timeField]; ok {
+				var err error
+				if t, err = toTime(v); err != nil {
+					return senml.SenML{}, err
+				}
+				delete(obj, timeField)
+			}
+		}
+
+		recs := []senml.SenMLRecord{}
+		flatten("", obj, &recs)
+		for i := range recs {
+			recs[i].Time = t
+		}
+		pack.Records = append(pack.Records, recs...)
+	}
+
+	return pack, nil
+}
+
+func flatten(name string, val interface{}, recs *[]senml.SenMLRecord) {
+	switch v := val.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			flatten(join(name, k), v[k], recs)
+		}
+	case []interface{}:
+		for i, e := range v {
+			flatten(join(name, fmt.Sprint(i)), e, recs)
+		}
+	case bool:
+		*recs = append(*recs, senml.SenMLRecord{Name: name, BoolValue: &v})
+	case string:
+		*recs = append(*recs, senml.SenMLRecord{Name: name, StringValue: v})
+	case []byte:
+		*recs = append(*recs, senml.SenMLRecord{Name: name, DataValue: base64.StdEncoding.EncodeToString(v)})
+	default:
+		if f, ok := toFloat(v); ok {
+			*recs = append(*recs, senml.SenMLRecord{Name: name, Value: &f})
+		}
+	}
+}
+
+func join(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + separator + name
+}
+
+func toFloat(val interface{}) (float64, bool) {
+	switch v := val.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
+
+// toTime converts the time field, either the number of seconds since the
+// epoch or the RFC3339 string, to the SenML time.
+func toTime(val interface{}) (float64, error) {
+	if f, ok := toFloat(val); ok {
+		return f, nil
+	}
+
+	s, ok := val.(string)
+	if !ok {
+		return 0, normalizer.ErrMalformedPayload
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return 0, normalizer.ErrMalformedPayload
+	}
+
+	return float64(t.UnixNano()) / 1e9, nil
+}
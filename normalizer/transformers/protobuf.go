@@ -0,0 +1,284 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package transformers
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"path/filepath"
+	"strings"
+
+	"github.com/cisco/senml"
+	"github.com/golang/protobuf/proto"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/normalizer"
+)
+
+// schemaExt is the extension of the schema files, holding the
+// FileDescriptorSet generated by `protoc --include_imports --descriptor_set_out`.
+const schemaExt = ".pb"
+
+var _ normalizer.Transformer = (*protobufTransformer)(nil)
+
+// schema indexes the message and enum types of the descriptor set by their
+// fully qualified names.
+type schema struct {
+	messages map[string]*descriptor
+	enums    map[string]map[int32]string
+}
+
+type protobufTransformer struct {
+	schemas map[string]schema
+}
+
+// ReadSchemas reads the schema files of the directory, by the schema names,
+// which are the names of the files without the extension.
+func ReadSchemas(dir string) (map[string][]byte, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+schemaExt))
+	if err != nil {
+		return nil, err
+	}
+
+	schemas := map[string][]byte{}
+	for _, p := range paths {
+		b, err := ioutil.ReadFile(p)
+		if err != nil {
+			return nil, err
+		}
+		schemas[strings.TrimSuffix(filepath.Base(p), schemaExt)] = b
+	}
+
+	return schemas, nil
+}
+
+// NewProtobuf returns the transformer of the messages of the provided
+// schemas, given by their names as the serialized FileDescriptorSets.
+func NewProtobuf(schemas map[string][]byte) (normalizer.Transformer, error) {
+	pt := protobufTransformer{schemas: map[string]schema{}}
+	for name, b := range schemas {
+		var set fileDescriptorSet
+		if err := proto.Unmarshal(b, &set); err != nil {
+			return nil, fmt.Errorf("invalid schema %s: %s", name, err)
+		}
+
+		s := schema{
+			messages: map[string]*descriptor{},
+			enums:    map[string]map[int32]string{},
+		}
+		for _, f := range set.File {
+			s.index(f.Package, f.MessageType, f.EnumType)
+		}
+		pt.schemas[name] = s
+	}
+
+	return pt, nil
+}
+
+func (pt protobufTransformer) Transform(msg mainflux.RawMessage, cfg normalizer.Config) (senml.SenML, error) {
+	s, ok := pt.schemas[cfg.Schema]
+	if !ok {
+		return senml.SenML{}, normalizer.ErrUnknownSchema
+	}
+
+	desc, ok := s.messages[cfg.Message]
+	if !ok {
+		return senml.SenML{}, normalizer.ErrUnknownSchema
+	}
+
+	obj, err := s.decode(msg.Payload, desc)
+	if err != nil {
+		return senml.SenML{}, normalizer.ErrMalformedPayload
+	}
+
+	return toSenML(obj, cfg.Time)
+}
+
+func (s schema) index(scope string, msgs []*descriptor, enums []*enumDescriptor) {
+	for _, e := range enums {
+		values := map[int32]string{}
+		for _, v := range e.Value {
+			values[v.Number] = v.Name
+		}
+		s.enums[qualify(scope, e.Name)] = values
+	}
+
+	for _, m := range msgs {
+		name := qualify(scope, m.Name)
+		s.messages[name] = m
+		s.index(name, m.NestedType, m.EnumType)
+	}
+}
+
+func qualify(scope, name string) string {
+	if scope == "" {
+		return name
+	}
+	return scope + "." + name
+}
+
+// decode decodes the message of the provided type to the object keyed by
+// the field names. Unknown fields are skipped, while the map fields are
+// decoded to the objects keyed by the map keys.
+func (s schema) decode(data []byte, desc *descriptor) (map[string]interface{}, error) {
+	fields := map[int32]*fieldDescriptor{}
+	for _, f := range desc.Field {
+		fields[f.Number] = f
+	}
+
+	obj := map[string]interface{}{}
+	for len(data) > 0 {
+		key, n := proto.DecodeVarint(data)
+		if n == 0 {
+			return nil, normalizer.ErrMalformedPayload
+		}
+		data = data[n:]
+
+		wire := int(key & 0x7)
+		raw, val, rest, err := readField(data, wire)
+		if err != nil {
+			return nil, err
+		}
+		data = rest
+
+		f, ok := fields[int32(key>>3)]
+		if !ok {
+			continue
+		}
+
+		vals, err := s.values(f, wire, raw, val)
+		if err != nil {
+			return nil, err
+		}
+
+		if f.Type == typeMessage {
+			if entry := s.messages[strings.TrimPrefix(f.TypeName, ".")]; entry.Options != nil && entry.Options.MapEntry {
+				m, _ := obj[f.Name].(map[string]interface{})
+				if m == nil {
+					m = map[string]interface{}{}
+				}
+				for _, v := range vals {
+					e := v.(map[string]interface{})
+					m[fmt.Sprint(e["key"])] = e["value"]
+				}
+				obj[f.Name] = m
+				continue
+			}
+		}
+
+		if f.Label != labelRepeated {
+			obj[f.Name] = vals[len(vals)-1]
+			continue
+		}
+		list, _ := obj[f.Name].([]interface{})
+		obj[f.Name] = append(list, vals...)
+	}
+
+	return obj, nil
+}
+
+// values converts the field read from the wire to its values. Length
+// delimited scalar fields are the packed repeated ones.
+func (s schema) values(f *fieldDescriptor, wire int, raw uint64, val []byte) ([]interface{}, error) {
+	switch f.Type {
+	case typeString:
+		return []interface{}{string(val)}, nil
+	case typeBytes:
+		return []interface{}{val}, nil
+	case typeMessage:
+		desc, ok := s.messages[strings.TrimPrefix(f.TypeName, ".")]
+		if !ok {
+			return nil, normalizer.ErrUnknownSchema
+		}
+		obj, err := s.decode(val, desc)
+		if err != nil {
+			return nil, err
+		}
+		return []interface{}{obj}, nil
+	}
+
+	if wire != proto.WireBytes {
+		return []interface{}{s.scalar(f, raw)}, nil
+	}
+
+	wire = proto.WireVarint
+	switch f.Type {
+	case typeDouble, typeFixed64, typeSfixed64:
+		wire = proto.WireFixed64
+	case typeFloat, typeFixed32, typeSfixed32:
+		wire = proto.WireFixed32
+	}
+
+	vals := []interface{}{}
+	for len(val) > 0 {
+		raw, _, rest, err := readField(val, wire)
+		if err != nil {
+			return nil, err
+		}
+		vals = append(vals, s.scalar(f, raw))
+		val = rest
+	}
+
+	return vals, nil
+}
+
+func (s schema) scalar(f *fieldDescriptor, raw uint64) interface{} {
+	switch f.Type {
+	case typeDouble:
+		return math.Float64frombits(raw)
+	case typeFloat:
+		return float64(math.Float32frombits(uint32(raw)))
+	case typeInt64, typeSfixed64:
+		return float64(int64(raw))
+	case typeInt32, typeSfixed32:
+		return float64(int32(raw))
+	case typeSint32, typeSint64:
+		return float64(int64(raw>>1) ^ -int64(raw&1))
+	case typeUint32, typeFixed32:
+		return float64(uint32(raw))
+	case typeBool:
+		return raw != 0
+	case typeEnum:
+		if name, ok := s.enums[strings.TrimPrefix(f.TypeName, ".")][int32(raw)]; ok {
+			return name
+		}
+		return float64(int32(raw))
+	default:
+		return float64(raw)
+	}
+}
+
+// readField reads the field value of the wire type, returning the numeric
+// value of the varint and fixed size fields, the bytes of the length
+// delimited ones and the rest of the data.
+func readField(data []byte, wire int) (uint64, []byte, []byte, error) {
+	switch wire {
+	case proto.WireVarint:
+		v, n := proto.DecodeVarint(data)
+		if n == 0 {
+			return 0, nil, nil, normalizer.ErrMalformedPayload
+		}
+		return v, nil, data[n:], nil
+	case proto.WireFixed64:
+		if len(data) < 8 {
+			return 0, nil, nil, normalizer.ErrMalformedPayload
+		}
+		return binary.LittleEndian.Uint64(data), nil, data[8:], nil
+	case proto.WireFixed32:
+		if len(data) < 4 {
+			return 0, nil, nil, normalizer.ErrMalformedPayload
+		}
+		return uint64(binary.LittleEndian.Uint32(data)), nil, data[4:], nil
+	case proto.WireBytes:
+		l, n := proto.DecodeVarint(data)
+		if n == 0 || uint64(len(data)-n) < l {
+			return 0, nil, nil, normalizer.ErrMalformedPayload
+		}
+		return 0, data[n : n+int(l)], data[n+int(l):], nil
+	default:
+		// Groups are deprecated and not supported.
+		return 0, nil, nil, normalizer.ErrMalformedPayload
+	}
+}
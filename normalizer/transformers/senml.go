@@ -0,0 +1,140 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package transformers
+
+import (
+	"encoding/base64"
+
+	"github.com/cisco/senml"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/normalizer"
+	"github.com/ugorji/go/codec"
+)
+
+// cborLabels maps the integer labels of the SenML CBOR records, defined by
+// https://tools.ietf.org/html/rfc8428#section-6, to the JSON ones.
+var cborLabels = map[int64]string{
+	-1: "bver",
+	-2: "bn",
+	-3: "bt",
+	-4: "bu",
+	0:  "n",
+	1:  "u",
+	2:  "v",
+	3:  "vs",
+	4:  "vb",
+	5:  "s",
+	6:  "t",
+	7:  "ut",
+	8:  "vd",
+}
+
+var _ normalizer.Transformer = (*senmlTransformer)(nil)
+
+type senmlTransformer struct {
+	handle *codec.CborHandle
+}
+
+// NewSenML returns the transformer of the SenML packs. The pack is decoded
+// from CBOR if the message content type is SenML CBOR, and from JSON
+// otherwise.
+func NewSenML() normalizer.Transformer {
+	return senmlTransformer{handle: &codec.CborHandle{}}
+}
+
+func (st senmlTransformer) Transform(msg mainflux.RawMessage, _ normalizer.Config) (senml.SenML, error) {
+	if msg.ContentType == mainflux.SenMLCBOR {
+		return st.decodeCBOR(msg.Payload)
+	}
+
+	return senml.Decode(msg.Payload, senml.JSON)
+}
+
+// decodeCBOR decodes the SenML CBOR pack. Unlike the SenML package, which
+// expects the JSON labels, the records are keyed by the integer labels, while
+// the JSON labels are accepted as well.
+func (st senmlTransformer) decodeCBOR(payload []byte) (senml.SenML, error) {
+	var pack []map[interface{}]interface{}
+	if err := codec.NewDecoderBytes(payload, st.handle).Decode(&pack); err != nil {
+		return senml.SenML{}, normalizer.ErrMalformedPayload
+	}
+
+	records := []senml.SenMLRecord{}
+	for _, fields := range pack {
+		var r senml.SenMLRecord
+		for k, v := range fields {
+			if err := setField(&r, label(k), v); err != nil {
+				return senml.SenML{}, err
+			}
+		}
+		records = append(records, r)
+	}
+
+	return senml.SenML{Records: records}, nil
+}
+
+func label(key interface{}) string {
+	switch k := key.(type) {
+	case int64:
+		return cborLabels[k]
+	case uint64:
+		return cborLabels[int64(k)]
+	case string:
+		return k
+	default:
+		return ""
+	}
+}
+
+// setField sets the record field of the label. Fields of the unknown labels
+// are skipped.
+func setField(r *senml.SenMLRecord, label string, val interface{}) error {
+	var ok bool
+	switch label {
+	case "bn":
+		r.BaseName, ok = val.(string)
+	case "bu":
+		r.BaseUnit, ok = val.(string)
+	case "n":
+		r.Name, ok = val.(string)
+	case "u":
+		r.Unit, ok = val.(string)
+	case "vs":
+		r.StringValue, ok = val.(string)
+	case "bt":
+		r.BaseTime, ok = toFloat(val)
+	case "t":
+		r.Time, ok = toFloat(val)
+	case "ut":
+		r.UpdateTime, ok = toFloat(val)
+	case "bver":
+		var f float64
+		f, ok = toFloat(val)
+		r.BaseVersion = int(f)
+	case "v":
+		var f float64
+		f, ok = toFloat(val)
+		r.Value = &f
+	case "s":
+		var f float64
+		f, ok = toFloat(val)
+		r.Sum = &f
+	case "vb":
+		var b bool
+		b, ok = val.(bool)
+		r.BoolValue = &b
+	case "vd":
+		var d []byte
+		d, ok = val.([]byte)
+		r.DataValue = base64.RawURLEncoding.EncodeToString(d)
+	default:
+		return nil
+	}
+
+	if !ok {
+		return normalizer.ErrMalformedPayload
+	}
+
+	return nil
+}
@@ -0,0 +1,317 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package transformers_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/cisco/senml"
+	"github.com/golang/protobuf/proto"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/normalizer"
+	"github.com/mainflux/mainflux/normalizer/transformers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ugorji/go/codec"
+)
+
+const (
+	schemasDir = "testdata"
+	schema     = "weather"
+	message    = "weather.Reading"
+	timeField  = "time"
+	ts         = 1584000000
+)
+
+// reading mirrors weather.Reading message of the test schema.
+type reading struct {
+	Time        int64             `protobuf:"varint,1,opt,name=time,proto3"`
+	Temperature float64           `protobuf:"fixed64,2,opt,name=temperature,proto3"`
+	Offset      int32             `protobuf:"zigzag32,3,opt,name=offset,proto3"`
+	Raining     bool              `protobuf:"varint,4,opt,name=raining,proto3"`
+	Station     string            `protobuf:"bytes,5,opt,name=station,proto3"`
+	Status      int32             `protobuf:"varint,6,opt,name=status,proto3"`
+	History     []float32         `protobuf:"fixed32,7,rep,packed,name=history,proto3"`
+	Wind        *wind             `protobuf:"bytes,8,opt,name=wind,proto3"`
+	Counters    map[string]uint32 `protobuf:"bytes,9,rep,name=counters,proto3" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	Raw         []byte            `protobuf:"bytes,10,opt,name=raw,proto3"`
+}
+
+func (m *reading) Reset()         { *m = reading{} }
+func (m *reading) String() string { return proto.CompactTextString(m) }
+func (*reading) ProtoMessage()    {}
+
+type wind struct {
+	Speed     float32 `protobuf:"fixed32,1,opt,name=speed,proto3"`
+	Direction uint32  `protobuf:"varint,2,opt,name=direction,proto3"`
+}
+
+func (m *wind) Reset()         { *m = wind{} }
+func (m *wind) String() string { return proto.CompactTextString(m) }
+func (*wind) ProtoMessage()    {}
+
+func float(v float64) *float64 {
+	return &v
+}
+
+func boolean(v bool) *bool {
+	return &v
+}
+
+func TestJSONTransform(t *testing.T) {
+	tr := transformers.NewJSON()
+
+	cases := []struct {
+		desc    string
+		payload string
+		cfg     normalizer.Config
+		records []senml.SenMLRecord
+		err     error
+	}{
+		{
+			desc:    "transform object",
+			payload: `{"temp": 21.5, "on": true, "name": "lab", "wind": {"speed": 3}, "time": 1584000000}`,
+			cfg:     normalizer.Config{Time: timeField},
+			records: []senml.SenMLRecord{
+				{Name: "name", StringValue: "lab", Time: ts},
+				{Name: "on", BoolValue: boolean(true), Time: ts},
+				{Name: "temp", Value: float(21.5), Time: ts},
+				{Name: "wind/speed", Value: float(3), Time: ts},
+			},
+		},
+		{
+			desc:    "transform array of objects with RFC3339 time",
+			payload: `[{"temp": 21, "time": "2020-03-12T08:00:00Z"}, {"temp": 22, "time": "2020-03-12T08:00:10Z"}]`,
+			cfg:     normalizer.Config{Time: timeField},
+			records: []senml.SenMLRecord{
+				{Name: "temp", Value: float(21), Time: ts},
+				{Name: "temp", Value: float(22), Time: ts + 10},
+			},
+		},
+		{
+			desc:    "transform object with array and null fields",
+			payload: `{"history": [1, 2], "missing": null, "time": 1584000000}`,
+			records: []senml.SenMLRecord{
+				{Name: "history/0", Value: float(1)},
+				{Name: "history/1", Value: float(2)},
+				{Name: "time", Value: float(ts)},
+			},
+		},
+		{
+			desc:    "transform object with invalid time",
+			payload: `{"temp": 21, "time": "yesterday"}`,
+			cfg:     normalizer.Config{Time: timeField},
+			err:     normalizer.ErrMalformedPayload,
+		},
+		{
+			desc:    "transform scalar",
+			payload: `21.5`,
+			err:     normalizer.ErrMalformedPayload,
+		},
+		{
+			desc:    "transform invalid JSON",
+			payload: `{"temp":`,
+			err:     normalizer.ErrMalformedPayload,
+		},
+	}
+
+	for _, tc := range cases {
+		pack, err := tr.Transform(mainflux.RawMessage{Payload: []byte(tc.payload)}, tc.cfg)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.records, pack.Records, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.records, pack.Records))
+	}
+}
+
+func TestCBORTransform(t *testing.T) {
+	tr := transformers.NewCBOR()
+
+	var payload []byte
+	obj := map[string]interface{}{
+		"temp": 21.5,
+		"hum":  40,
+		"time": ts,
+	}
+	err := codec.NewEncoderBytes(&payload, &codec.CborHandle{}).Encode(obj)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc    string
+		payload []byte
+		records []senml.SenMLRecord
+		err     error
+	}{
+		{
+			desc:    "transform map",
+			payload: payload,
+			records: []senml.SenMLRecord{
+				{Name: "hum", Value: float(40), Time: ts},
+				{Name: "temp", Value: float(21.5), Time: ts},
+			},
+		},
+		{
+			desc:    "transform invalid CBOR",
+			payload: []byte{0xbf},
+			err:     normalizer.ErrMalformedPayload,
+		},
+	}
+
+	for _, tc := range cases {
+		pack, err := tr.Transform(mainflux.RawMessage{Payload: tc.payload}, normalizer.Config{Time: timeField})
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.records, pack.Records, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.records, pack.Records))
+	}
+}
+
+func TestProtobufTransform(t *testing.T) {
+	schemas, err := transformers.ReadSchemas(schemasDir)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	tr, err := transformers.NewProtobuf(schemas)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	payload, err := proto.Marshal(&reading{
+		Time:        ts,
+		Temperature: 21.5,
+		Offset:      -3,
+		Raining:     true,
+		Station:     "lab",
+		Status:      2,
+		History:     []float32{1.5, 2},
+		Wind:        &wind{Speed: 3.5, Direction: 270},
+		Counters:    map[string]uint32{"resets": 4},
+		Raw:         []byte{0x01, 0x02},
+	})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cfg := normalizer.Config{
+		Format:  normalizer.Protobuf,
+		Schema:  schema,
+		Message: message,
+		Time:    timeField,
+	}
+
+	cases := []struct {
+		desc    string
+		payload []byte
+		cfg     normalizer.Config
+		records []senml.SenMLRecord
+		err     error
+	}{
+		{
+			desc:    "transform message",
+			payload: payload,
+			cfg:     cfg,
+			records: []senml.SenMLRecord{
+				{Name: "counters/resets", Value: float(4), Time: ts},
+				{Name: "history/0", Value: float(1.5), Time: ts},
+				{Name: "history/1", Value: float(2), Time: ts},
+				{Name: "offset", Value: float(-3), Time: ts},
+				{Name: "raining", BoolValue: boolean(true), Time: ts},
+				{Name: "raw", DataValue: "AQI=", Time: ts},
+				{Name: "station", StringValue: "lab", Time: ts},
+				{Name: "status", StringValue: "FAULT", Time: ts},
+				{Name: "temperature", Value: float(21.5), Time: ts},
+				{Name: "wind/direction", Value: float(270), Time: ts},
+				{Name: "wind/speed", Value: float(3.5), Time: ts},
+			},
+		},
+		{
+			desc:    "transform message of unknown schema",
+			payload: payload,
+			cfg:     normalizer.Config{Format: normalizer.Protobuf, Schema: "unknown", Message: message},
+			err:     normalizer.ErrUnknownSchema,
+		},
+		{
+			desc:    "transform message of unknown type",
+			payload: payload,
+			cfg:     normalizer.Config{Format: normalizer.Protobuf, Schema: schema, Message: "weather.Unknown"},
+			err:     normalizer.ErrUnknownSchema,
+		},
+		{
+			desc:    "transform truncated message",
+			payload: payload[:len(payload)-1],
+			cfg:     cfg,
+			err:     normalizer.ErrMalformedPayload,
+		},
+	}
+
+	for _, tc := range cases {
+		pack, err := tr.Transform(mainflux.RawMessage{Payload: tc.payload}, tc.cfg)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.records, pack.Records, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.records, pack.Records))
+	}
+}
+
+func TestSenMLTransform(t *testing.T) {
+	tr := transformers.NewSenML()
+
+	jsonPayload, err := json.Marshal([]map[string]interface{}{{"n": "temp", "v": 21.5}})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	var cborPayload []byte
+	err = codec.NewEncoderBytes(&cborPayload, &codec.CborHandle{}).Encode([]map[int]interface{}{{0: "temp", 2: 21.5}})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	var labeledPayload []byte
+	err = codec.NewEncoderBytes(&labeledPayload, &codec.CborHandle{}).Encode([]map[string]interface{}{{"n": "temp", "v": 21.5}})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	var invalidPayload []byte
+	err = codec.NewEncoderBytes(&invalidPayload, &codec.CborHandle{}).Encode([]map[int]interface{}{{0: 1, 2: 21.5}})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	records := []senml.SenMLRecord{{Name: "temp", Value: float(21.5)}}
+
+	cases := []struct {
+		desc        string
+		payload     []byte
+		contentType string
+		records     []senml.SenMLRecord
+		err         bool
+	}{
+		{
+			desc:        "transform SenML JSON",
+			payload:     jsonPayload,
+			contentType: mainflux.SenMLJSON,
+			records:     records,
+		},
+		{
+			desc:        "transform SenML CBOR",
+			payload:     cborPayload,
+			contentType: mainflux.SenMLCBOR,
+			records:     records,
+		},
+		{
+			desc:        "transform SenML CBOR with JSON labels",
+			payload:     labeledPayload,
+			contentType: mainflux.SenMLCBOR,
+			records:     records,
+		},
+		{
+			desc:        "transform SenML CBOR with invalid name",
+			payload:     invalidPayload,
+			contentType: mainflux.SenMLCBOR,
+			err:         true,
+		},
+		{
+			desc:        "transform SenML without content type",
+			payload:     jsonPayload,
+			contentType: "",
+			records:     records,
+		},
+		{
+			desc:        "transform SenML CBOR as JSON",
+			payload:     cborPayload,
+			contentType: mainflux.SenMLJSON,
+			err:         true,
+		},
+	}
+
+	for _, tc := range cases {
+		pack, err := tr.Transform(mainflux.RawMessage{Payload: tc.payload, ContentType: tc.contentType}, normalizer.Config{})
+		assert.Equal(t, tc.err, err != nil, fmt.Sprintf("%s: unexpected error: %v", tc.desc, err))
+		assert.Equal(t, tc.records, pack.Records, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.records, pack.Records))
+	}
+}
@@ -0,0 +1,25 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package normalizer
+
+import "github.com/mainflux/mainflux"
+
+// ValidationError indicates that the message payload doesn't satisfy the
+// JSON Schema registered for the channel subtopic.
+type ValidationError struct {
+	Violations []string
+}
+
+func (ve *ValidationError) Error() string {
+	return "payload doesn't satisfy the schema"
+}
+
+// Validator specifies an API for validating the message payloads against
+// the payload schemas registered for the channels.
+type Validator interface {
+	// Validate returns ValidationError if the JSON payload of the message
+	// doesn't satisfy the schema of the message channel subtopic. Messages
+	// published to the subtopics without the schema are valid.
+	Validate(mainflux.RawMessage) error
+}
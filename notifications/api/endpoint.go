@@ -0,0 +1,145 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/mainflux/mainflux/notifications"
+)
+
+func createSubscriptionEndpoint(svc notifications.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(createSubscriptionReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		sub := notifications.Subscription{
+			Channel:  req.Channel,
+			Subtopic: req.Subtopic,
+			Notifier: req.Notifier,
+			Contact:  req.Contact,
+			Throttle: time.Duration(req.Throttle) * time.Second,
+			Dedup:    time.Duration(req.Dedup) * time.Second,
+		}
+
+		saved, err := svc.CreateSubscription(ctx, req.token, sub)
+		if err != nil {
+			return nil, err
+		}
+
+		return subscriptionRes{id: saved.ID}, nil
+	}
+}
+
+func viewSubscriptionEndpoint(svc notifications.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewSubscriptionReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		sub, err := svc.ViewSubscription(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		return toSubscriptionRes(sub), nil
+	}
+}
+
+func listSubscriptionsEndpoint(svc notifications.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listSubscriptionsReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		page, err := svc.ListSubscriptions(ctx, req.token, req.channel, req.offset, req.limit)
+		if err != nil {
+			return nil, err
+		}
+
+		res := subscriptionsPageRes{
+			Total:         page.Total,
+			Offset:        page.Offset,
+			Limit:         page.Limit,
+			Subscriptions: []viewSubscriptionRes{},
+		}
+		for _, s := range page.Subscriptions {
+			res.Subscriptions = append(res.Subscriptions, toSubscriptionRes(s))
+		}
+
+		return res, nil
+	}
+}
+
+func removeSubscriptionEndpoint(svc notifications.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewSubscriptionReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RemoveSubscription(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
+func listDeliveriesEndpoint(svc notifications.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listDeliveriesReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		page, err := svc.ListDeliveries(ctx, req.token, req.id, req.offset, req.limit)
+		if err != nil {
+			return nil, err
+		}
+
+		res := deliveriesPageRes{
+			Total:      page.Total,
+			Offset:     page.Offset,
+			Limit:      page.Limit,
+			Deliveries: []deliveryRes{},
+		}
+		for _, d := range page.Deliveries {
+			res.Deliveries = append(res.Deliveries, deliveryRes{
+				ID:       d.ID,
+				Notifier: d.Notifier,
+				Contact:  d.Contact,
+				Subject:  d.Subject,
+				Status:   d.Status,
+				Error:    d.Error,
+				Created:  d.Created,
+			})
+		}
+
+		return res, nil
+	}
+}
+
+func toSubscriptionRes(s notifications.Subscription) viewSubscriptionRes {
+	return viewSubscriptionRes{
+		ID:       s.ID,
+		Channel:  s.Channel,
+		Subtopic: s.Subtopic,
+		Notifier: s.Notifier,
+		Contact:  s.Contact,
+		Throttle: uint64(s.Throttle / time.Second),
+		Dedup:    uint64(s.Dedup / time.Second),
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// +build !test
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	log "github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/notifications"
+)
+
+var _ notifications.Service = (*loggingMiddleware)(nil)
+
+type loggingMiddleware struct {
+	logger log.Logger
+	svc    notifications.Service
+}
+
+// LoggingMiddleware adds logging facilities to the core service.
+func LoggingMiddleware(svc notifications.Service, logger log.Logger) notifications.Service {
+	return &loggingMiddleware{logger, svc}
+}
+
+func (lm *loggingMiddleware) CreateSubscription(ctx context.Context, token string, sub notifications.Subscription) (saved notifications.Subscription, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method create_subscription for token %s and subscription %s took %s to complete", token, saved.ID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.CreateSubscription(ctx, token, sub)
+}
+
+func (lm *loggingMiddleware) ViewSubscription(ctx context.Context, token, id string) (sub notifications.Subscription, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method view_subscription for token %s and subscription %s took %s to complete", token, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ViewSubscription(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) ListSubscriptions(ctx context.Context, token, channel string, offset, limit uint64) (page notifications.SubscriptionsPage, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_subscriptions for token %s took %s to complete", token, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListSubscriptions(ctx, token, channel, offset, limit)
+}
+
+func (lm *loggingMiddleware) RemoveSubscription(ctx context.Context, token, id string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method remove_subscription for token %s and subscription %s took %s to complete", token, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RemoveSubscription(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) ListDeliveries(ctx context.Context, token, id string, offset, limit uint64) (page notifications.DeliveriesPage, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_deliveries for token %s and subscription %s took %s to complete", token, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListDeliveries(ctx, token, id, offset, limit)
+}
+
+func (lm *loggingMiddleware) Notify(ctx context.Context, msg mainflux.Message) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method notify for channel %s took %s to complete", msg.Channel, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Notify(ctx, msg)
+}
+
+func (lm *loggingMiddleware) Reload(ctx context.Context) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method reload took %s to complete", time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Reload(ctx)
+}
@@ -0,0 +1,96 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// +build !test
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/notifications"
+)
+
+var _ notifications.Service = (*metricsMiddleware)(nil)
+
+type metricsMiddleware struct {
+	counter metrics.Counter
+	latency metrics.Histogram
+	svc     notifications.Service
+}
+
+// MetricsMiddleware instruments core service by tracking request count and
+// latency.
+func MetricsMiddleware(svc notifications.Service, counter metrics.Counter, latency metrics.Histogram) notifications.Service {
+	return &metricsMiddleware{
+		counter: counter,
+		latency: latency,
+		svc:     svc,
+	}
+}
+
+func (ms *metricsMiddleware) CreateSubscription(ctx context.Context, token string, sub notifications.Subscription) (notifications.Subscription, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "create_subscription").Add(1)
+		ms.latency.With("method", "create_subscription").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.CreateSubscription(ctx, token, sub)
+}
+
+func (ms *metricsMiddleware) ViewSubscription(ctx context.Context, token, id string) (notifications.Subscription, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_subscription").Add(1)
+		ms.latency.With("method", "view_subscription").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewSubscription(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) ListSubscriptions(ctx context.Context, token, channel string, offset, limit uint64) (notifications.SubscriptionsPage, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_subscriptions").Add(1)
+		ms.latency.With("method", "list_subscriptions").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListSubscriptions(ctx, token, channel, offset, limit)
+}
+
+func (ms *metricsMiddleware) RemoveSubscription(ctx context.Context, token, id string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "remove_subscription").Add(1)
+		ms.latency.With("method", "remove_subscription").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RemoveSubscription(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) ListDeliveries(ctx context.Context, token, id string, offset, limit uint64) (notifications.DeliveriesPage, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_deliveries").Add(1)
+		ms.latency.With("method", "list_deliveries").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListDeliveries(ctx, token, id, offset, limit)
+}
+
+func (ms *metricsMiddleware) Notify(ctx context.Context, msg mainflux.Message) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "notify").Add(1)
+		ms.latency.With("method", "notify").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Notify(ctx, msg)
+}
+
+func (ms *metricsMiddleware) Reload(ctx context.Context) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "reload").Add(1)
+		ms.latency.With("method", "reload").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Reload(ctx)
+}
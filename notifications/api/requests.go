@@ -0,0 +1,89 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import "github.com/mainflux/mainflux/notifications"
+
+const maxLimitSize = 100
+
+type apiReq interface {
+	validate() error
+}
+
+type createSubscriptionReq struct {
+	token    string
+	Channel  string `json:"channel"`
+	Subtopic string `json:"subtopic,omitempty"`
+	Notifier string `json:"notifier"`
+	Contact  string `json:"contact"`
+	Throttle uint64 `json:"throttle,omitempty"`
+	Dedup    uint64 `json:"dedup,omitempty"`
+}
+
+func (req createSubscriptionReq) validate() error {
+	if req.token == "" {
+		return notifications.ErrUnauthorizedAccess
+	}
+
+	if req.Channel == "" || req.Notifier == "" || req.Contact == "" {
+		return notifications.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type viewSubscriptionReq struct {
+	token string
+	id    string
+}
+
+func (req viewSubscriptionReq) validate() error {
+	if req.token == "" {
+		return notifications.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" {
+		return notifications.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type listSubscriptionsReq struct {
+	token   string
+	channel string
+	offset  uint64
+	limit   uint64
+}
+
+func (req listSubscriptionsReq) validate() error {
+	if req.token == "" {
+		return notifications.ErrUnauthorizedAccess
+	}
+
+	if req.limit == 0 || req.limit > maxLimitSize {
+		return notifications.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type listDeliveriesReq struct {
+	token  string
+	id     string
+	offset uint64
+	limit  uint64
+}
+
+func (req listDeliveriesReq) validate() error {
+	if req.token == "" {
+		return notifications.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" || req.limit == 0 || req.limit > maxLimitSize {
+		return notifications.ErrMalformedEntity
+	}
+
+	return nil
+}
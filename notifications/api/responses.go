@@ -0,0 +1,122 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/mainflux/mainflux"
+)
+
+var (
+	_ mainflux.Response = (*subscriptionRes)(nil)
+	_ mainflux.Response = (*viewSubscriptionRes)(nil)
+	_ mainflux.Response = (*subscriptionsPageRes)(nil)
+	_ mainflux.Response = (*deliveriesPageRes)(nil)
+	_ mainflux.Response = (*removeRes)(nil)
+)
+
+type subscriptionRes struct {
+	id string
+}
+
+func (res subscriptionRes) Code() int {
+	return http.StatusCreated
+}
+
+func (res subscriptionRes) Headers() map[string]string {
+	return map[string]string{
+		"Location": fmt.Sprintf("/subscriptions/%s", res.id),
+	}
+}
+
+func (res subscriptionRes) Empty() bool {
+	return true
+}
+
+type viewSubscriptionRes struct {
+	ID       string `json:"id"`
+	Channel  string `json:"channel"`
+	Subtopic string `json:"subtopic,omitempty"`
+	Notifier string `json:"notifier"`
+	Contact  string `json:"contact"`
+	Throttle uint64 `json:"throttle"`
+	Dedup    uint64 `json:"dedup"`
+}
+
+func (res viewSubscriptionRes) Code() int {
+	return http.StatusOK
+}
+
+func (res viewSubscriptionRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res viewSubscriptionRes) Empty() bool {
+	return false
+}
+
+type subscriptionsPageRes struct {
+	Total         uint64                `json:"total"`
+	Offset        uint64                `json:"offset"`
+	Limit         uint64                `json:"limit"`
+	Subscriptions []viewSubscriptionRes `json:"subscriptions"`
+}
+
+func (res subscriptionsPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res subscriptionsPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res subscriptionsPageRes) Empty() bool {
+	return false
+}
+
+type deliveryRes struct {
+	ID       string    `json:"id"`
+	Notifier string    `json:"notifier"`
+	Contact  string    `json:"contact"`
+	Subject  string    `json:"subject"`
+	Status   string    `json:"status"`
+	Error    string    `json:"error,omitempty"`
+	Created  time.Time `json:"created"`
+}
+
+type deliveriesPageRes struct {
+	Total      uint64        `json:"total"`
+	Offset     uint64        `json:"offset"`
+	Limit      uint64        `json:"limit"`
+	Deliveries []deliveryRes `json:"deliveries"`
+}
+
+func (res deliveriesPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res deliveriesPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res deliveriesPageRes) Empty() bool {
+	return false
+}
+
+type removeRes struct{}
+
+func (res removeRes) Code() int {
+	return http.StatusNoContent
+}
+
+func (res removeRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res removeRes) Empty() bool {
+	return true
+}
@@ -0,0 +1,9 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package notifications contains the domain concept definitions needed to
+// support Mainflux notifications service functionality. Notifications service
+// notifies the subscribed users about the messages published to the channels,
+// such as the alarms republished by the rules service, using the pluggable
+// notifiers, and tracks the status of each delivery.
+package notifications
@@ -0,0 +1,63 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mainflux/mainflux/notifications"
+)
+
+var _ notifications.DeliveryRepository = (*deliveryRepositoryMock)(nil)
+
+type deliveryRepositoryMock struct {
+	mu         sync.Mutex
+	deliveries []notifications.Delivery
+}
+
+// NewDeliveryRepository creates in-memory delivery repository.
+func NewDeliveryRepository() notifications.DeliveryRepository {
+	return &deliveryRepositoryMock{}
+}
+
+func (drm *deliveryRepositoryMock) Save(_ context.Context, d notifications.Delivery) error {
+	drm.mu.Lock()
+	defer drm.mu.Unlock()
+
+	drm.deliveries = append(drm.deliveries, d)
+	return nil
+}
+
+func (drm *deliveryRepositoryMock) RetrieveBySubscription(_ context.Context, sub string, offset, limit uint64) (notifications.DeliveriesPage, error) {
+	drm.mu.Lock()
+	defer drm.mu.Unlock()
+
+	// Deliveries are appended, so the most recent ones are at the end.
+	items := []notifications.Delivery{}
+	for i := len(drm.deliveries) - 1; i >= 0; i-- {
+		if drm.deliveries[i].Subscription == sub {
+			items = append(items, drm.deliveries[i])
+		}
+	}
+
+	page := notifications.DeliveriesPage{
+		Total:      uint64(len(items)),
+		Offset:     offset,
+		Limit:      limit,
+		Deliveries: []notifications.Delivery{},
+	}
+
+	if offset >= uint64(len(items)) {
+		return page, nil
+	}
+
+	end := offset + limit
+	if end > uint64(len(items)) {
+		end = uint64(len(items))
+	}
+	page.Deliveries = items[offset:end]
+
+	return page, nil
+}
@@ -0,0 +1,54 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/mainflux/mainflux/notifications"
+)
+
+// FailingContact is the contact notifications to which always fail.
+const FailingContact = "failing"
+
+var _ notifications.Notifier = (*Notifier)(nil)
+
+// Sent represents a notification sent to the contact.
+type Sent struct {
+	Contact      string
+	Notification notifications.Notification
+}
+
+// Notifier is an in-memory notifier which records the notifications.
+type Notifier struct {
+	mu   sync.Mutex
+	sent []Sent
+}
+
+// NewNotifier returns notifier mock.
+func NewNotifier() *Notifier {
+	return &Notifier{}
+}
+
+// Notify records the notification, unless the contact is failing.
+func (n *Notifier) Notify(contact string, notification notifications.Notification) error {
+	if contact == FailingContact {
+		return errors.New("delivery failed")
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.sent = append(n.sent, Sent{Contact: contact, Notification: notification})
+	return nil
+}
+
+// Sent returns all the notifications sent so far.
+func (n *Notifier) Sent() []Sent {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return append([]Sent{}, n.sent...)
+}
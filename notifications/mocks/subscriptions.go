@@ -0,0 +1,103 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/mainflux/mainflux/notifications"
+)
+
+var _ notifications.SubscriptionRepository = (*subscriptionRepositoryMock)(nil)
+
+type subscriptionRepositoryMock struct {
+	mu   sync.Mutex
+	subs map[string]notifications.Subscription
+}
+
+// NewSubscriptionRepository creates in-memory subscription repository.
+func NewSubscriptionRepository() notifications.SubscriptionRepository {
+	return &subscriptionRepositoryMock{
+		subs: make(map[string]notifications.Subscription),
+	}
+}
+
+func (srm *subscriptionRepositoryMock) Save(_ context.Context, sub notifications.Subscription) error {
+	srm.mu.Lock()
+	defer srm.mu.Unlock()
+
+	srm.subs[sub.ID] = sub
+	return nil
+}
+
+func (srm *subscriptionRepositoryMock) RetrieveByID(_ context.Context, id string) (notifications.Subscription, error) {
+	srm.mu.Lock()
+	defer srm.mu.Unlock()
+
+	sub, ok := srm.subs[id]
+	if !ok {
+		return notifications.Subscription{}, notifications.ErrNotFound
+	}
+
+	return sub, nil
+}
+
+func (srm *subscriptionRepositoryMock) RetrieveAll(_ context.Context) ([]notifications.Subscription, error) {
+	srm.mu.Lock()
+	defer srm.mu.Unlock()
+
+	return srm.filter(func(notifications.Subscription) bool { return true }), nil
+}
+
+func (srm *subscriptionRepositoryMock) RetrieveByOwner(_ context.Context, owner, channel string, offset, limit uint64) (notifications.SubscriptionsPage, error) {
+	srm.mu.Lock()
+	defer srm.mu.Unlock()
+
+	items := srm.filter(func(s notifications.Subscription) bool {
+		return s.Owner == owner && (channel == "" || s.Channel == channel)
+	})
+	page := notifications.SubscriptionsPage{
+		Total:         uint64(len(items)),
+		Offset:        offset,
+		Limit:         limit,
+		Subscriptions: []notifications.Subscription{},
+	}
+
+	if offset >= uint64(len(items)) {
+		return page, nil
+	}
+
+	end := offset + limit
+	if end > uint64(len(items)) {
+		end = uint64(len(items))
+	}
+	page.Subscriptions = items[offset:end]
+
+	return page, nil
+}
+
+func (srm *subscriptionRepositoryMock) Remove(_ context.Context, id string) error {
+	srm.mu.Lock()
+	defer srm.mu.Unlock()
+
+	delete(srm.subs, id)
+	return nil
+}
+
+func (srm *subscriptionRepositoryMock) filter(match func(notifications.Subscription) bool) []notifications.Subscription {
+	items := []notifications.Subscription{}
+	for _, s := range srm.subs {
+		if match(s) {
+			items = append(items, s)
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].ID < items[j].ID
+	})
+
+	return items
+}
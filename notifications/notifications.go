@@ -0,0 +1,187 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package notifications
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// Supported notifier types.
+const (
+	// SMTP notifier sends the notifications by email.
+	SMTP = "smtp"
+
+	// Twilio notifier sends the notifications by SMS using the Twilio API.
+	Twilio = "twilio"
+
+	// Slack notifier posts the notifications to the Slack incoming webhook.
+	Slack = "slack"
+
+	// Webhook notifier posts the notifications as JSON to the HTTP endpoint.
+	Webhook = "webhook"
+)
+
+// Supported delivery statuses.
+const (
+	// Sent status indicates that the notifier accepted the notification.
+	Sent = "sent"
+
+	// Failed status indicates that the notifier failed to send the
+	// notification.
+	Failed = "failed"
+)
+
+const subtopicWildcard = ">"
+
+// Subscription represents user subscription to the messages published to the
+// channel. Each subscription is owned by one user.
+type Subscription struct {
+	ID      string
+	Owner   string
+	Channel string
+
+	// Subtopic is matched exactly, unless it ends with ">" in which case
+	// it matches all the subtopics with the given prefix. Empty subtopic
+	// matches any message.
+	Subtopic string
+
+	// Notifier is the type of the notifier, while contact is the email
+	// address, the phone number or the URL the notifications are sent to.
+	Notifier string
+	Contact  string
+
+	// Throttle is the minimal period between two notifications, while
+	// notifications of the same message content aren't repeated within
+	// the dedup period. Zero period disables the check.
+	Throttle time.Duration
+	Dedup    time.Duration
+}
+
+// SubscriptionsPage contains page related metadata as well as list of
+// subscriptions that belong to this page.
+type SubscriptionsPage struct {
+	Total         uint64
+	Offset        uint64
+	Limit         uint64
+	Subscriptions []Subscription
+}
+
+// Notification represents the notification of the published message.
+type Notification struct {
+	Subject   string
+	Content   string
+	Channel   string
+	Subtopic  string
+	Publisher string
+	Time      time.Time
+}
+
+// Delivery represents the attempt to send the notification to the contact
+// of the subscription.
+type Delivery struct {
+	ID           string
+	Subscription string
+	Notifier     string
+	Contact      string
+	Subject      string
+	Status       string
+	Error        string
+	Created      time.Time
+}
+
+// DeliveriesPage contains page related metadata as well as list of
+// deliveries that belong to this page.
+type DeliveriesPage struct {
+	Total      uint64
+	Offset     uint64
+	Limit      uint64
+	Deliveries []Delivery
+}
+
+// Validate returns ErrMalformedEntity if subscription isn't valid.
+func (s Subscription) Validate() error {
+	if s.Channel == "" || s.Notifier == "" || s.Contact == "" {
+		return ErrMalformedEntity
+	}
+
+	if s.Throttle < 0 || s.Dedup < 0 {
+		return ErrMalformedEntity
+	}
+
+	return nil
+}
+
+// Matches determines whether the message published to the channel subtopic
+// is the subject of the subscription.
+func (s Subscription) Matches(channel, subtopic string) bool {
+	if s.Channel != channel {
+		return false
+	}
+
+	if s.Subtopic == "" || s.Subtopic == subtopic {
+		return true
+	}
+
+	if strings.HasSuffix(s.Subtopic, subtopicWildcard) {
+		return strings.HasPrefix(subtopic, strings.TrimSuffix(s.Subtopic, subtopicWildcard))
+	}
+
+	return false
+}
+
+// SubscriptionRepository specifies a subscription persistence API.
+type SubscriptionRepository interface {
+	// Save persists the subscription. A non-nil error is returned to
+	// indicate operation failure.
+	Save(context.Context, Subscription) error
+
+	// RetrieveByID retrieves the subscription having the provided
+	// identifier.
+	RetrieveByID(context.Context, string) (Subscription, error)
+
+	// RetrieveAll retrieves all the subscriptions. It is used to populate
+	// the set of active subscriptions.
+	RetrieveAll(context.Context) ([]Subscription, error)
+
+	// RetrieveByOwner retrieves the subset of subscriptions owned by the
+	// specified user, optionally limited to the channel.
+	RetrieveByOwner(context.Context, string, string, uint64, uint64) (SubscriptionsPage, error)
+
+	// Remove removes the subscription having the provided identifier,
+	// along with its deliveries.
+	Remove(context.Context, string) error
+}
+
+// DeliveryRepository specifies a delivery persistence API.
+type DeliveryRepository interface {
+	// Save persists the delivery. A non-nil error is returned to indicate
+	// operation failure.
+	Save(context.Context, Delivery) error
+
+	// RetrieveBySubscription retrieves the subset of deliveries of the
+	// subscription, starting with the most recent one.
+	RetrieveBySubscription(context.Context, string, uint64, uint64) (DeliveriesPage, error)
+}
+
+// Notifier specifies an API for sending the notifications.
+type Notifier interface {
+	// Notify sends the notification to the contact.
+	Notify(contact string, n Notification) error
+}
+
+// Channels specifies an API for checking the channel ownership, so that the
+// users can't subscribe to the channels of other users.
+type Channels interface {
+	// Authorize returns ErrNotFound if the channel doesn't exist or isn't
+	// owned by the user identified by the provided key.
+	Authorize(token, chanID string) error
+}
+
+// IdentityProvider specifies an API for generating unique identifiers.
+type IdentityProvider interface {
+	// ID generates the unique identifier.
+	ID() (string, error)
+}
@@ -0,0 +1,7 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package notifiers contains the notifier implementations which send the
+// notifications by email, by SMS using the Twilio API, to Slack, and to the
+// generic webhooks.
+package notifiers
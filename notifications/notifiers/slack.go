@@ -0,0 +1,47 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package notifiers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/mainflux/mainflux/notifications"
+)
+
+var _ notifications.Notifier = (*slackNotifier)(nil)
+
+type slackMsg struct {
+	Text string `json:"text"`
+}
+
+type slackNotifier struct {
+	client *http.Client
+}
+
+// NewSlack returns notifier which posts the notifications to the Slack
+// incoming webhooks. Contact of the subscription is the webhook URL.
+func NewSlack(timeout time.Duration) notifications.Notifier {
+	return slackNotifier{
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (sn slackNotifier) Notify(contact string, n notifications.Notification) error {
+	data, err := json.Marshal(slackMsg{Text: fmt.Sprintf("*%s*\n%s", n.Subject, n.Content)})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, contact, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	return send(sn.client, req)
+}
@@ -0,0 +1,53 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package notifiers
+
+import (
+	"fmt"
+	"net"
+	"net/smtp"
+
+	"github.com/mainflux/mainflux/notifications"
+)
+
+var _ notifications.Notifier = (*smtpNotifier)(nil)
+
+// SMTPConfig contains the SMTP server address and credentials, as well as
+// the sender address of the notifications.
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+type smtpNotifier struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+// NewSMTP returns notifier which sends the plain text emails through the
+// SMTP server. The server is authenticated against only if the username is
+// set.
+func NewSMTP(cfg SMTPConfig) notifications.Notifier {
+	var auth smtp.Auth
+	if cfg.Username != "" {
+		auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+
+	return smtpNotifier{
+		addr: net.JoinHostPort(cfg.Host, cfg.Port),
+		auth: auth,
+		from: cfg.From,
+	}
+}
+
+func (sn smtpNotifier) Notify(contact string, n notifications.Notification) error {
+	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n%s\r\n",
+		sn.from, contact, n.Subject, n.Content)
+
+	return smtp.SendMail(sn.addr, sn.auth, sn.from, []string{contact}, []byte(msg))
+}
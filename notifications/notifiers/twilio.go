@@ -0,0 +1,57 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package notifiers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mainflux/mainflux/notifications"
+)
+
+var _ notifications.Notifier = (*twilioNotifier)(nil)
+
+// TwilioConfig contains the Twilio API URL, the account credentials and the
+// phone number the SMS are sent from.
+type TwilioConfig struct {
+	URL        string
+	AccountSID string
+	AuthToken  string
+	From       string
+}
+
+type twilioNotifier struct {
+	cfg    TwilioConfig
+	client *http.Client
+}
+
+// NewTwilio returns notifier which sends the SMS using the Twilio messages
+// API.
+func NewTwilio(cfg TwilioConfig, timeout time.Duration) notifications.Notifier {
+	return twilioNotifier{
+		cfg:    cfg,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (tn twilioNotifier) Notify(contact string, n notifications.Notification) error {
+	endpoint := fmt.Sprintf("%s/2010-04-01/Accounts/%s/Messages.json", strings.TrimSuffix(tn.cfg.URL, "/"), tn.cfg.AccountSID)
+
+	form := url.Values{}
+	form.Set("From", tn.cfg.From)
+	form.Set("To", contact)
+	form.Set("Body", fmt.Sprintf("%s: %s", n.Subject, n.Content))
+
+	req, err := http.NewRequest(http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(tn.cfg.AccountSID, tn.cfg.AuthToken)
+
+	return send(tn.client, req)
+}
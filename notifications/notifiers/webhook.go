@@ -0,0 +1,74 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package notifiers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/mainflux/mainflux/notifications"
+)
+
+var _ notifications.Notifier = (*webhookNotifier)(nil)
+
+type webhookMsg struct {
+	Subject   string    `json:"subject"`
+	Content   string    `json:"content"`
+	Channel   string    `json:"channel"`
+	Subtopic  string    `json:"subtopic,omitempty"`
+	Publisher string    `json:"publisher"`
+	Time      time.Time `json:"time"`
+}
+
+type webhookNotifier struct {
+	client *http.Client
+}
+
+// NewWebhook returns notifier which posts the notifications as JSON to the
+// generic webhooks. Contact of the subscription is the webhook URL.
+func NewWebhook(timeout time.Duration) notifications.Notifier {
+	return webhookNotifier{
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (wn webhookNotifier) Notify(contact string, n notifications.Notification) error {
+	data, err := json.Marshal(webhookMsg{
+		Subject:   n.Subject,
+		Content:   n.Content,
+		Channel:   n.Channel,
+		Subtopic:  n.Subtopic,
+		Publisher: n.Publisher,
+		Time:      n.Time,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, contact, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	return send(wn.client, req)
+}
+
+// send sends the request, accepting any successful response status.
+func send(client *http.Client, req *http.Request) error {
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("unexpected response status: %s", res.Status)
+	}
+
+	return nil
+}
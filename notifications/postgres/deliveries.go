@@ -0,0 +1,126 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq" // required for DB access
+	"github.com/mainflux/mainflux/notifications"
+)
+
+var _ notifications.DeliveryRepository = (*deliveryRepository)(nil)
+
+type deliveryRepository struct {
+	db *sqlx.DB
+}
+
+// NewDeliveryRepository instantiates a PostgreSQL implementation of delivery
+// repository.
+func NewDeliveryRepository(db *sqlx.DB) notifications.DeliveryRepository {
+	return &deliveryRepository{
+		db: db,
+	}
+}
+
+func (dr deliveryRepository) Save(ctx context.Context, d notifications.Delivery) error {
+	q := `INSERT INTO deliveries (id, subscription, notifier, contact, subject, status, error, created_at)
+	      VALUES (:id, :subscription, :notifier, :contact, :subject, :status, :error, :created_at);`
+
+	if _, err := dr.db.NamedExecContext(ctx, q, toDBDelivery(d)); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code.Name() {
+			case errDuplicate:
+				return notifications.ErrMalformedEntity
+			case errForeignKey, errInvalid:
+				return notifications.ErrNotFound
+			}
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (dr deliveryRepository) RetrieveBySubscription(ctx context.Context, sub string, offset, limit uint64) (notifications.DeliveriesPage, error) {
+	q := `SELECT id, subscription, notifier, contact, subject, status, error, created_at FROM deliveries
+	      WHERE subscription = $1 ORDER BY created_at DESC, id LIMIT $2 OFFSET $3;`
+
+	rows, err := dr.db.QueryxContext(ctx, q, sub, limit, offset)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == errInvalid {
+			return notifications.DeliveriesPage{}, notifications.ErrNotFound
+		}
+		return notifications.DeliveriesPage{}, err
+	}
+	defer rows.Close()
+
+	items := []notifications.Delivery{}
+	for rows.Next() {
+		var dbd dbDelivery
+		if err := rows.StructScan(&dbd); err != nil {
+			return notifications.DeliveriesPage{}, err
+		}
+		items = append(items, toDelivery(dbd))
+	}
+
+	if err := rows.Err(); err != nil {
+		return notifications.DeliveriesPage{}, err
+	}
+
+	cq := `SELECT COUNT(*) FROM deliveries WHERE subscription = $1;`
+
+	var total uint64
+	if err := dr.db.GetContext(ctx, &total, cq, sub); err != nil {
+		return notifications.DeliveriesPage{}, err
+	}
+
+	page := notifications.DeliveriesPage{
+		Total:      total,
+		Offset:     offset,
+		Limit:      limit,
+		Deliveries: items,
+	}
+
+	return page, nil
+}
+
+type dbDelivery struct {
+	ID           string    `db:"id"`
+	Subscription string    `db:"subscription"`
+	Notifier     string    `db:"notifier"`
+	Contact      string    `db:"contact"`
+	Subject      string    `db:"subject"`
+	Status       string    `db:"status"`
+	Error        string    `db:"error"`
+	CreatedAt    time.Time `db:"created_at"`
+}
+
+func toDBDelivery(d notifications.Delivery) dbDelivery {
+	return dbDelivery{
+		ID:           d.ID,
+		Subscription: d.Subscription,
+		Notifier:     d.Notifier,
+		Contact:      d.Contact,
+		Subject:      d.Subject,
+		Status:       d.Status,
+		Error:        d.Error,
+		CreatedAt:    d.Created.UTC(),
+	}
+}
+
+func toDelivery(dbd dbDelivery) notifications.Delivery {
+	return notifications.Delivery{
+		ID:           dbd.ID,
+		Subscription: dbd.Subscription,
+		Notifier:     dbd.Notifier,
+		Contact:      dbd.Contact,
+		Subject:      dbd.Subject,
+		Status:       dbd.Status,
+		Error:        dbd.Error,
+		Created:      dbd.CreatedAt.UTC(),
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/notifications"
+	"github.com/mainflux/mainflux/notifications/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newDelivery(id, sub string, created time.Time) notifications.Delivery {
+	return notifications.Delivery{
+		ID:           id,
+		Subscription: sub,
+		Notifier:     notifications.SMTP,
+		Contact:      owner,
+		Subject:      "Notification from channel 1",
+		Status:       notifications.Sent,
+		Created:      created.Truncate(time.Microsecond),
+	}
+}
+
+func TestDeliverySave(t *testing.T) {
+	subs := postgres.NewSubscriptionRepository(db)
+	err := subs.Save(context.Background(), newSubscription(id, chanID))
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	defer subs.Remove(context.Background(), id)
+
+	repo := postgres.NewDeliveryRepository(db)
+	d := newDelivery(otherID, id, time.Now().UTC())
+
+	cases := []struct {
+		desc     string
+		delivery notifications.Delivery
+		err      error
+	}{
+		{
+			desc:     "save new delivery",
+			delivery: d,
+			err:      nil,
+		},
+		{
+			desc:     "save duplicate delivery",
+			delivery: d,
+			err:      notifications.ErrMalformedEntity,
+		},
+		{
+			desc:     "save delivery of non-existing subscription",
+			delivery: newDelivery(wrongID, wrongID, time.Now().UTC()),
+			err:      notifications.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := repo.Save(context.Background(), tc.delivery)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestDeliveryRetrieveBySubscription(t *testing.T) {
+	subs := postgres.NewSubscriptionRepository(db)
+	err := subs.Save(context.Background(), newSubscription(id, chanID))
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	defer subs.Remove(context.Background(), id)
+
+	repo := postgres.NewDeliveryRepository(db)
+	now := time.Now().UTC()
+	older := newDelivery(otherID, id, now.Add(-time.Minute))
+	older.Status = notifications.Failed
+	older.Error = "unexpected response status: 500 Internal Server Error"
+	recent := newDelivery(wrongID, id, now)
+
+	for _, d := range []notifications.Delivery{older, recent} {
+		err := repo.Save(context.Background(), d)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	page, err := repo.RetrieveBySubscription(context.Background(), id, 0, 10)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, uint64(2), page.Total, fmt.Sprintf("expected total 2 got %d\n", page.Total))
+	assert.Equal(t, []notifications.Delivery{recent, older}, page.Deliveries, fmt.Sprintf("expected most recent deliveries first got %v\n", page.Deliveries))
+
+	page, err = repo.RetrieveBySubscription(context.Background(), id, 1, 10)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, []notifications.Delivery{older}, page.Deliveries, fmt.Sprintf("expected oldest delivery got %v\n", page.Deliveries))
+}
@@ -0,0 +1,84 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq" // required for SQL access
+	migrate "github.com/rubenv/sql-migrate"
+)
+
+// Config defines the options that are used when connecting to a PostgreSQL instance
+type Config struct {
+	Host        string
+	Port        string
+	User        string
+	Pass        string
+	Name        string
+	SSLMode     string
+	SSLCert     string
+	SSLKey      string
+	SSLRootCert string
+}
+
+// Connect creates a connection to the PostgreSQL instance and applies any
+// unapplied database migrations. A non-nil error is returned to indicate
+// failure.
+func Connect(cfg Config) (*sqlx.DB, error) {
+	url := fmt.Sprintf("host=%s port=%s user=%s dbname=%s password=%s sslmode=%s sslcert=%s sslkey=%s sslrootcert=%s", cfg.Host, cfg.Port, cfg.User, cfg.Name, cfg.Pass, cfg.SSLMode, cfg.SSLCert, cfg.SSLKey, cfg.SSLRootCert)
+
+	db, err := sqlx.Open("postgres", url)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := migrateDB(db); err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+func migrateDB(db *sqlx.DB) error {
+	migrations := &migrate.MemoryMigrationSource{
+		Migrations: []*migrate.Migration{
+			{
+				Id: "notifications_1",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS subscriptions (
+						id       UUID PRIMARY KEY,
+						owner    VARCHAR(254) NOT NULL,
+						channel  VARCHAR(254) NOT NULL,
+						subtopic VARCHAR(1024),
+						notifier VARCHAR(32) NOT NULL,
+						contact  VARCHAR(1024) NOT NULL,
+						throttle BIGINT NOT NULL DEFAULT 0,
+						dedup    BIGINT NOT NULL DEFAULT 0
+					)`,
+					`CREATE INDEX IF NOT EXISTS subscriptions_owner_idx ON subscriptions (owner, channel)`,
+					`CREATE TABLE IF NOT EXISTS deliveries (
+						id           UUID PRIMARY KEY,
+						subscription UUID NOT NULL REFERENCES subscriptions (id) ON DELETE CASCADE,
+						notifier     VARCHAR(32) NOT NULL,
+						contact      VARCHAR(1024) NOT NULL,
+						subject      VARCHAR(1024),
+						status       VARCHAR(16) NOT NULL,
+						error        TEXT,
+						created_at   TIMESTAMP NOT NULL
+					)`,
+					`CREATE INDEX IF NOT EXISTS deliveries_subscription_idx ON deliveries (subscription, created_at)`,
+				},
+				Down: []string{
+					`DROP TABLE IF EXISTS deliveries`,
+					`DROP TABLE IF EXISTS subscriptions`,
+				},
+			},
+		},
+	}
+
+	_, err := migrate.Exec(db.DB, "postgres", migrations, migrate.Up)
+	return err
+}
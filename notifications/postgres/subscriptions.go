@@ -0,0 +1,172 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq" // required for DB access
+	"github.com/mainflux/mainflux/notifications"
+)
+
+const (
+	errDuplicate  = "unique_violation"
+	errInvalid    = "invalid_text_representation"
+	errForeignKey = "foreign_key_violation"
+)
+
+var _ notifications.SubscriptionRepository = (*subscriptionRepository)(nil)
+
+type subscriptionRepository struct {
+	db *sqlx.DB
+}
+
+// NewSubscriptionRepository instantiates a PostgreSQL implementation of
+// subscription repository.
+func NewSubscriptionRepository(db *sqlx.DB) notifications.SubscriptionRepository {
+	return &subscriptionRepository{
+		db: db,
+	}
+}
+
+func (sr subscriptionRepository) Save(ctx context.Context, sub notifications.Subscription) error {
+	q := `INSERT INTO subscriptions (id, owner, channel, subtopic, notifier, contact, throttle, dedup)
+	      VALUES (:id, :owner, :channel, :subtopic, :notifier, :contact, :throttle, :dedup);`
+
+	if _, err := sr.db.NamedExecContext(ctx, q, toDBSubscription(sub)); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == errDuplicate {
+			return notifications.ErrMalformedEntity
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (sr subscriptionRepository) RetrieveByID(ctx context.Context, id string) (notifications.Subscription, error) {
+	q := `SELECT id, owner, channel, subtopic, notifier, contact, throttle, dedup FROM subscriptions WHERE id = $1;`
+
+	var dbs dbSubscription
+	if err := sr.db.QueryRowxContext(ctx, q, id).StructScan(&dbs); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if err == sql.ErrNoRows || ok && errInvalid == pqErr.Code.Name() {
+			return notifications.Subscription{}, notifications.ErrNotFound
+		}
+		return notifications.Subscription{}, err
+	}
+
+	return toSubscription(dbs), nil
+}
+
+func (sr subscriptionRepository) RetrieveAll(ctx context.Context) ([]notifications.Subscription, error) {
+	q := `SELECT id, owner, channel, subtopic, notifier, contact, throttle, dedup FROM subscriptions;`
+
+	return sr.retrieve(ctx, q)
+}
+
+func (sr subscriptionRepository) RetrieveByOwner(ctx context.Context, owner, channel string, offset, limit uint64) (notifications.SubscriptionsPage, error) {
+	filter := "WHERE owner = $1"
+	args := []interface{}{owner}
+	if channel != "" {
+		filter = "WHERE owner = $1 AND channel = $2"
+		args = append(args, channel)
+	}
+
+	q := fmt.Sprintf(`SELECT id, owner, channel, subtopic, notifier, contact, throttle, dedup FROM subscriptions
+	      %s ORDER BY id LIMIT %d OFFSET %d;`, filter, limit, offset)
+
+	items, err := sr.retrieve(ctx, q, args...)
+	if err != nil {
+		return notifications.SubscriptionsPage{}, err
+	}
+
+	cq := fmt.Sprintf(`SELECT COUNT(*) FROM subscriptions %s;`, filter)
+
+	var total uint64
+	if err := sr.db.GetContext(ctx, &total, cq, args...); err != nil {
+		return notifications.SubscriptionsPage{}, err
+	}
+
+	page := notifications.SubscriptionsPage{
+		Total:         total,
+		Offset:        offset,
+		Limit:         limit,
+		Subscriptions: items,
+	}
+
+	return page, nil
+}
+
+func (sr subscriptionRepository) Remove(ctx context.Context, id string) error {
+	q := `DELETE FROM subscriptions WHERE id = $1;`
+
+	if _, err := sr.db.ExecContext(ctx, q, id); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == errInvalid {
+			return nil
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (sr subscriptionRepository) retrieve(ctx context.Context, q string, args ...interface{}) ([]notifications.Subscription, error) {
+	rows, err := sr.db.QueryxContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []notifications.Subscription{}
+	for rows.Next() {
+		var dbs dbSubscription
+		if err := rows.StructScan(&dbs); err != nil {
+			return nil, err
+		}
+		items = append(items, toSubscription(dbs))
+	}
+
+	return items, rows.Err()
+}
+
+type dbSubscription struct {
+	ID       string `db:"id"`
+	Owner    string `db:"owner"`
+	Channel  string `db:"channel"`
+	Subtopic string `db:"subtopic"`
+	Notifier string `db:"notifier"`
+	Contact  string `db:"contact"`
+	Throttle int64  `db:"throttle"`
+	Dedup    int64  `db:"dedup"`
+}
+
+func toDBSubscription(s notifications.Subscription) dbSubscription {
+	return dbSubscription{
+		ID:       s.ID,
+		Owner:    s.Owner,
+		Channel:  s.Channel,
+		Subtopic: s.Subtopic,
+		Notifier: s.Notifier,
+		Contact:  s.Contact,
+		Throttle: int64(s.Throttle / time.Second),
+		Dedup:    int64(s.Dedup / time.Second),
+	}
+}
+
+func toSubscription(dbs dbSubscription) notifications.Subscription {
+	return notifications.Subscription{
+		ID:       dbs.ID,
+		Owner:    dbs.Owner,
+		Channel:  dbs.Channel,
+		Subtopic: dbs.Subtopic,
+		Notifier: dbs.Notifier,
+		Contact:  dbs.Contact,
+		Throttle: time.Duration(dbs.Throttle) * time.Second,
+		Dedup:    time.Duration(dbs.Dedup) * time.Second,
+	}
+}
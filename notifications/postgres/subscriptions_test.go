@@ -0,0 +1,154 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/notifications"
+	"github.com/mainflux/mainflux/notifications/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	id      = "123e4567-e89b-12d3-a456-000000000001"
+	otherID = "123e4567-e89b-12d3-a456-000000000002"
+	wrongID = "123e4567-e89b-12d3-a456-000000000099"
+	owner   = "user@example.com"
+	chanID  = "1"
+)
+
+func newSubscription(id, channel string) notifications.Subscription {
+	return notifications.Subscription{
+		ID:       id,
+		Owner:    owner,
+		Channel:  channel,
+		Subtopic: "alarms.>",
+		Notifier: notifications.SMTP,
+		Contact:  owner,
+		Throttle: time.Minute,
+		Dedup:    time.Hour,
+	}
+}
+
+func TestSubscriptionSave(t *testing.T) {
+	repo := postgres.NewSubscriptionRepository(db)
+	sub := newSubscription(id, chanID)
+
+	cases := []struct {
+		desc string
+		sub  notifications.Subscription
+		err  error
+	}{
+		{
+			desc: "save new subscription",
+			sub:  sub,
+			err:  nil,
+		},
+		{
+			desc: "save duplicate subscription",
+			sub:  sub,
+			err:  notifications.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		err := repo.Save(context.Background(), tc.sub)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	repo.Remove(context.Background(), id)
+}
+
+func TestSubscriptionRetrieveByID(t *testing.T) {
+	repo := postgres.NewSubscriptionRepository(db)
+	sub := newSubscription(id, chanID)
+	err := repo.Save(context.Background(), sub)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	defer repo.Remove(context.Background(), id)
+
+	cases := []struct {
+		desc string
+		id   string
+		sub  notifications.Subscription
+		err  error
+	}{
+		{
+			desc: "retrieve existing subscription",
+			id:   id,
+			sub:  sub,
+			err:  nil,
+		},
+		{
+			desc: "retrieve non-existing subscription",
+			id:   wrongID,
+			sub:  notifications.Subscription{},
+			err:  notifications.ErrNotFound,
+		},
+		{
+			desc: "retrieve subscription with malformed ID",
+			id:   "malformed",
+			sub:  notifications.Subscription{},
+			err:  notifications.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		sub, err := repo.RetrieveByID(context.Background(), tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.sub, sub, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.sub, sub))
+	}
+}
+
+func TestSubscriptionRetrieveByOwner(t *testing.T) {
+	repo := postgres.NewSubscriptionRepository(db)
+	for _, s := range []notifications.Subscription{newSubscription(id, chanID), newSubscription(otherID, "2")} {
+		err := repo.Save(context.Background(), s)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		defer repo.Remove(context.Background(), s.ID)
+	}
+
+	cases := []struct {
+		desc    string
+		owner   string
+		channel string
+		size    int
+		total   uint64
+	}{
+		{
+			desc:  "retrieve subscriptions of owner",
+			owner: owner,
+			size:  2,
+			total: 2,
+		},
+		{
+			desc:    "retrieve subscriptions of owner to channel",
+			owner:   owner,
+			channel: chanID,
+			size:    1,
+			total:   1,
+		},
+		{
+			desc:  "retrieve subscriptions of owner without subscriptions",
+			owner: "none@example.com",
+			size:  0,
+			total: 0,
+		},
+	}
+
+	for _, tc := range cases {
+		page, err := repo.RetrieveByOwner(context.Background(), tc.owner, tc.channel, 0, 10)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s\n", tc.desc, err))
+		assert.Equal(t, tc.size, len(page.Subscriptions), fmt.Sprintf("%s: expected %d subscriptions got %d\n", tc.desc, tc.size, len(page.Subscriptions)))
+		assert.Equal(t, tc.total, page.Total, fmt.Sprintf("%s: expected total %d got %d\n", tc.desc, tc.total, page.Total))
+	}
+
+	all, err := repo.RetrieveAll(context.Background())
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, 2, len(all), fmt.Sprintf("expected 2 subscriptions got %d\n", len(all)))
+}
@@ -0,0 +1,359 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package notifications
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mainflux/mainflux"
+)
+
+var (
+	// ErrMalformedEntity indicates malformed entity specification.
+	ErrMalformedEntity = errors.New("malformed entity specification")
+
+	// ErrUnauthorizedAccess indicates missing or invalid credentials provided
+	// when accessing a protected resource.
+	ErrUnauthorizedAccess = errors.New("missing or invalid credentials provided")
+
+	// ErrNotFound indicates a non-existent entity request.
+	ErrNotFound = errors.New("non-existent entity")
+)
+
+// Service specifies an API that must be fulfilled by the domain service
+// implementation, and all of its decorators (e.g. logging & metrics).
+type Service interface {
+	// CreateSubscription adds new subscription to the user identified by
+	// the provided key.
+	CreateSubscription(context.Context, string, Subscription) (Subscription, error)
+
+	// ViewSubscription retrieves the subscription identified by the
+	// provided ID, that belongs to the user identified by the provided key.
+	ViewSubscription(context.Context, string, string) (Subscription, error)
+
+	// ListSubscriptions retrieves subset of subscriptions that belong to
+	// the user identified by the provided key. Subscriptions are limited
+	// to the channel, unless it is empty.
+	ListSubscriptions(context.Context, string, string, uint64, uint64) (SubscriptionsPage, error)
+
+	// RemoveSubscription removes the subscription identified by the
+	// provided ID, that belongs to the user identified by the provided key.
+	RemoveSubscription(context.Context, string, string) error
+
+	// ListDeliveries retrieves subset of deliveries of the subscription
+	// identified by the provided ID, that belongs to the user identified
+	// by the provided key.
+	ListDeliveries(context.Context, string, string, uint64, uint64) (DeliveriesPage, error)
+
+	// Notify sends the notification of the message to the contacts of all
+	// the matching subscriptions, unless the notification is throttled or
+	// duplicated.
+	Notify(context.Context, mainflux.Message) error
+
+	// Reload replaces the set of active subscriptions with the persisted
+	// ones. It surpasses ownership check, since it is used to pick up the
+	// changes made through the other service instances.
+	Reload(context.Context) error
+}
+
+// window tracks the notifications sent for the subscription, so that they
+// can be throttled and deduplicated.
+type window struct {
+	last time.Time
+	seen map[string]time.Time
+}
+
+var _ Service = (*notificationsService)(nil)
+
+type notificationsService struct {
+	users         mainflux.UsersServiceClient
+	channels      Channels
+	subscriptions SubscriptionRepository
+	deliveries    DeliveryRepository
+	notifiers     map[string]Notifier
+	idp           IdentityProvider
+
+	mu      sync.RWMutex
+	active  map[string]map[string]Subscription
+	windows map[string]*window
+}
+
+// New instantiates the notifications service implementation. Notifiers are
+// keyed by their type, so the subscriptions are accepted only for the
+// provided notifiers.
+func New(users mainflux.UsersServiceClient, channels Channels, subscriptions SubscriptionRepository, deliveries DeliveryRepository, notifiers map[string]Notifier, idp IdentityProvider) Service {
+	return &notificationsService{
+		users:         users,
+		channels:      channels,
+		subscriptions: subscriptions,
+		deliveries:    deliveries,
+		notifiers:     notifiers,
+		idp:           idp,
+		active:        make(map[string]map[string]Subscription),
+		windows:       make(map[string]*window),
+	}
+}
+
+func (ns *notificationsService) CreateSubscription(ctx context.Context, token string, sub Subscription) (Subscription, error) {
+	owner, err := ns.identify(ctx, token)
+	if err != nil {
+		return Subscription{}, err
+	}
+
+	if err := sub.Validate(); err != nil {
+		return Subscription{}, err
+	}
+
+	if _, ok := ns.notifiers[sub.Notifier]; !ok {
+		return Subscription{}, ErrMalformedEntity
+	}
+
+	if err := ns.channels.Authorize(token, sub.Channel); err != nil {
+		return Subscription{}, err
+	}
+
+	sub.ID, err = ns.idp.ID()
+	if err != nil {
+		return Subscription{}, err
+	}
+	sub.Owner = owner
+
+	if err := ns.subscriptions.Save(ctx, sub); err != nil {
+		return Subscription{}, err
+	}
+
+	ns.activate(sub)
+	return sub, nil
+}
+
+func (ns *notificationsService) ViewSubscription(ctx context.Context, token, id string) (Subscription, error) {
+	owner, err := ns.identify(ctx, token)
+	if err != nil {
+		return Subscription{}, err
+	}
+
+	sub, err := ns.subscriptions.RetrieveByID(ctx, id)
+	if err != nil {
+		return Subscription{}, err
+	}
+
+	if sub.Owner != owner {
+		return Subscription{}, ErrNotFound
+	}
+
+	return sub, nil
+}
+
+func (ns *notificationsService) ListSubscriptions(ctx context.Context, token, channel string, offset, limit uint64) (SubscriptionsPage, error) {
+	owner, err := ns.identify(ctx, token)
+	if err != nil {
+		return SubscriptionsPage{}, err
+	}
+
+	return ns.subscriptions.RetrieveByOwner(ctx, owner, channel, offset, limit)
+}
+
+func (ns *notificationsService) RemoveSubscription(ctx context.Context, token, id string) error {
+	if _, err := ns.ViewSubscription(ctx, token, id); err != nil {
+		return err
+	}
+
+	if err := ns.subscriptions.Remove(ctx, id); err != nil {
+		return err
+	}
+
+	ns.deactivate(id)
+	return nil
+}
+
+func (ns *notificationsService) ListDeliveries(ctx context.Context, token, id string, offset, limit uint64) (DeliveriesPage, error) {
+	if _, err := ns.ViewSubscription(ctx, token, id); err != nil {
+		return DeliveriesPage{}, err
+	}
+
+	return ns.deliveries.RetrieveBySubscription(ctx, id, offset, limit)
+}
+
+func (ns *notificationsService) Notify(ctx context.Context, msg mainflux.Message) error {
+	ns.mu.RLock()
+	subs := make([]Subscription, 0, len(ns.active[msg.Channel]))
+	for _, s := range ns.active[msg.Channel] {
+		subs = append(subs, s)
+	}
+	ns.mu.RUnlock()
+
+	n := notification(msg)
+	digest := fmt.Sprintf("%s:%s:%v", msg.Publisher, msg.Name, value(msg))
+
+	var err error
+	for _, s := range subs {
+		if !s.Matches(msg.Channel, msg.Subtopic) || ns.suppressed(s, digest, time.Now()) {
+			continue
+		}
+
+		// Failure of a single delivery must not prevent other
+		// subscribers from being notified.
+		if derr := ns.deliver(ctx, s, n); derr != nil {
+			err = derr
+		}
+	}
+
+	return err
+}
+
+func (ns *notificationsService) Reload(ctx context.Context) error {
+	subs, err := ns.subscriptions.RetrieveAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	active := make(map[string]map[string]Subscription)
+	ids := make(map[string]bool)
+	for _, s := range subs {
+		if _, ok := active[s.Channel]; !ok {
+			active[s.Channel] = make(map[string]Subscription)
+		}
+		active[s.Channel][s.ID] = s
+		ids[s.ID] = true
+	}
+
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+
+	ns.active = active
+	for id := range ns.windows {
+		if !ids[id] {
+			delete(ns.windows, id)
+		}
+	}
+
+	return nil
+}
+
+func (ns *notificationsService) identify(ctx context.Context, token string) (string, error) {
+	res, err := ns.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return "", ErrUnauthorizedAccess
+	}
+
+	return res.GetValue(), nil
+}
+
+// suppressed determines whether the notification is throttled or duplicated.
+// Notifications which aren't suppressed are recorded in the subscription
+// window.
+func (ns *notificationsService) suppressed(s Subscription, digest string, now time.Time) bool {
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+
+	w, ok := ns.windows[s.ID]
+	if !ok {
+		w = &window{seen: make(map[string]time.Time)}
+		ns.windows[s.ID] = w
+	}
+
+	if s.Throttle > 0 && now.Sub(w.last) < s.Throttle {
+		return true
+	}
+
+	for d, t := range w.seen {
+		if now.Sub(t) >= s.Dedup {
+			delete(w.seen, d)
+		}
+	}
+
+	if _, ok := w.seen[digest]; ok {
+		return true
+	}
+
+	w.last = now
+	if s.Dedup > 0 {
+		w.seen[digest] = now
+	}
+
+	return false
+}
+
+func (ns *notificationsService) deliver(ctx context.Context, s Subscription, n Notification) error {
+	id, err := ns.idp.ID()
+	if err != nil {
+		return err
+	}
+
+	d := Delivery{
+		ID:           id,
+		Subscription: s.ID,
+		Notifier:     s.Notifier,
+		Contact:      s.Contact,
+		Subject:      n.Subject,
+		Status:       Sent,
+		Created:      time.Now(),
+	}
+
+	notifier, ok := ns.notifiers[s.Notifier]
+	if !ok {
+		d.Status = Failed
+		d.Error = fmt.Sprintf("notifier %s isn't configured", s.Notifier)
+	} else if nerr := notifier.Notify(s.Contact, n); nerr != nil {
+		d.Status = Failed
+		d.Error = nerr.Error()
+	}
+
+	return ns.deliveries.Save(ctx, d)
+}
+
+func (ns *notificationsService) activate(sub Subscription) {
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+
+	if _, ok := ns.active[sub.Channel]; !ok {
+		ns.active[sub.Channel] = make(map[string]Subscription)
+	}
+	ns.active[sub.Channel][sub.ID] = sub
+}
+
+func (ns *notificationsService) deactivate(id string) {
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+
+	for ch, subs := range ns.active {
+		delete(subs, id)
+		if len(subs) == 0 {
+			delete(ns.active, ch)
+		}
+	}
+	delete(ns.windows, id)
+}
+
+func notification(msg mainflux.Message) Notification {
+	t := time.Unix(0, int64(msg.Time*float64(time.Second))).UTC()
+
+	return Notification{
+		Subject:   fmt.Sprintf("Notification from channel %s", msg.Channel),
+		Content:   fmt.Sprintf("%s = %v %s published by %s at %s.", msg.Name, value(msg), msg.Unit, msg.Publisher, t.Format(time.RFC3339)),
+		Channel:   msg.Channel,
+		Subtopic:  msg.Subtopic,
+		Publisher: msg.Publisher,
+		Time:      t,
+	}
+}
+
+func value(msg mainflux.Message) interface{} {
+	switch v := msg.Value.(type) {
+	case *mainflux.Message_FloatValue:
+		return v.FloatValue
+	case *mainflux.Message_StringValue:
+		return v.StringValue
+	case *mainflux.Message_BoolValue:
+		return v.BoolValue
+	case *mainflux.Message_DataValue:
+		return v.DataValue
+	default:
+		return nil
+	}
+}
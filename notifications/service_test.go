@@ -0,0 +1,397 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package notifications_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/notifications"
+	"github.com/mainflux/mainflux/notifications/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	wrongValue = "wrong-value"
+	email      = "user@example.com"
+	token      = "token"
+	otherEmail = "other@example.com"
+	otherToken = "other"
+	chanID     = "1"
+	otherChan  = "2"
+)
+
+var sub = notifications.Subscription{
+	Channel:  chanID,
+	Subtopic: "temp.>",
+	Notifier: notifications.SMTP,
+	Contact:  email,
+}
+
+func newService() (notifications.Service, *mocks.Notifier) {
+	users := mocks.NewUsersService(map[string]string{token: email, otherToken: otherEmail})
+	channels := mocks.NewChannels(map[string]string{chanID: token, otherChan: otherToken})
+	notifier := mocks.NewNotifier()
+	notifiers := map[string]notifications.Notifier{notifications.SMTP: notifier}
+
+	svc := notifications.New(users, channels, mocks.NewSubscriptionRepository(), mocks.NewDeliveryRepository(), notifiers, mocks.NewIdentityProvider())
+	return svc, notifier
+}
+
+func TestCreateSubscription(t *testing.T) {
+	svc, _ := newService()
+
+	noContact := sub
+	noContact.Contact = ""
+
+	negativeThrottle := sub
+	negativeThrottle.Throttle = -time.Second
+
+	unsupported := sub
+	unsupported.Notifier = notifications.Twilio
+
+	otherChannel := sub
+	otherChannel.Channel = otherChan
+
+	cases := []struct {
+		desc  string
+		sub   notifications.Subscription
+		token string
+		err   error
+	}{
+		{
+			desc:  "create valid subscription",
+			sub:   sub,
+			token: token,
+			err:   nil,
+		},
+		{
+			desc:  "create subscription with wrong credentials",
+			sub:   sub,
+			token: wrongValue,
+			err:   notifications.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "create subscription without contact",
+			sub:   noContact,
+			token: token,
+			err:   notifications.ErrMalformedEntity,
+		},
+		{
+			desc:  "create subscription with negative throttle period",
+			sub:   negativeThrottle,
+			token: token,
+			err:   notifications.ErrMalformedEntity,
+		},
+		{
+			desc:  "create subscription with unsupported notifier",
+			sub:   unsupported,
+			token: token,
+			err:   notifications.ErrMalformedEntity,
+		},
+		{
+			desc:  "create subscription to channel owned by other user",
+			sub:   otherChannel,
+			token: token,
+			err:   notifications.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := svc.CreateSubscription(context.Background(), tc.token, tc.sub)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestViewSubscription(t *testing.T) {
+	svc, _ := newService()
+	saved, err := svc.CreateSubscription(context.Background(), token, sub)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc  string
+		id    string
+		token string
+		err   error
+	}{
+		{
+			desc:  "view existing subscription",
+			id:    saved.ID,
+			token: token,
+			err:   nil,
+		},
+		{
+			desc:  "view subscription with wrong credentials",
+			id:    saved.ID,
+			token: wrongValue,
+			err:   notifications.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "view subscription owned by other user",
+			id:    saved.ID,
+			token: otherToken,
+			err:   notifications.ErrNotFound,
+		},
+		{
+			desc:  "view non-existing subscription",
+			id:    wrongValue,
+			token: token,
+			err:   notifications.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := svc.ViewSubscription(context.Background(), tc.token, tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestListSubscriptions(t *testing.T) {
+	svc, _ := newService()
+	n := uint64(5)
+	for i := uint64(0); i < n; i++ {
+		_, err := svc.CreateSubscription(context.Background(), token, sub)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	}
+
+	cases := []struct {
+		desc    string
+		token   string
+		channel string
+		offset  uint64
+		limit   uint64
+		size    int
+		err     error
+	}{
+		{
+			desc:   "list all subscriptions",
+			token:  token,
+			offset: 0,
+			limit:  n,
+			size:   int(n),
+		},
+		{
+			desc:   "list last subscription",
+			token:  token,
+			offset: n - 1,
+			limit:  n,
+			size:   1,
+		},
+		{
+			desc:    "list subscriptions of channel",
+			token:   token,
+			channel: chanID,
+			offset:  0,
+			limit:   n,
+			size:    int(n),
+		},
+		{
+			desc:    "list subscriptions of channel without subscriptions",
+			token:   token,
+			channel: otherChan,
+			offset:  0,
+			limit:   n,
+			size:    0,
+		},
+		{
+			desc:   "list subscriptions of user without subscriptions",
+			token:  otherToken,
+			offset: 0,
+			limit:  n,
+			size:   0,
+		},
+		{
+			desc:   "list subscriptions with wrong credentials",
+			token:  wrongValue,
+			offset: 0,
+			limit:  n,
+			size:   0,
+			err:    notifications.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		page, err := svc.ListSubscriptions(context.Background(), tc.token, tc.channel, tc.offset, tc.limit)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.size, len(page.Subscriptions), fmt.Sprintf("%s: expected %d got %d\n", tc.desc, tc.size, len(page.Subscriptions)))
+	}
+}
+
+func TestRemoveSubscription(t *testing.T) {
+	svc, notifier := newService()
+	saved, err := svc.CreateSubscription(context.Background(), token, sub)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	err = svc.RemoveSubscription(context.Background(), otherToken, saved.ID)
+	assert.Equal(t, notifications.ErrNotFound, err, fmt.Sprintf("remove subscription owned by other user: expected %s got %s\n", notifications.ErrNotFound, err))
+
+	err = svc.RemoveSubscription(context.Background(), token, saved.ID)
+	assert.Nil(t, err, fmt.Sprintf("remove existing subscription: unexpected error: %s\n", err))
+
+	err = svc.Notify(context.Background(), message(35))
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.Empty(t, notifier.Sent(), "removed subscription must not be notified")
+}
+
+func TestListDeliveries(t *testing.T) {
+	svc, _ := newService()
+	saved, err := svc.CreateSubscription(context.Background(), token, sub)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	failing := sub
+	failing.Contact = mocks.FailingContact
+	failed, err := svc.CreateSubscription(context.Background(), token, failing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	n := 3
+	for i := 0; i < n; i++ {
+		err := svc.Notify(context.Background(), message(float64(i)))
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	}
+
+	cases := []struct {
+		desc   string
+		id     string
+		token  string
+		size   int
+		status string
+		err    error
+	}{
+		{
+			desc:   "list deliveries of subscription",
+			id:     saved.ID,
+			token:  token,
+			size:   n,
+			status: notifications.Sent,
+		},
+		{
+			desc:   "list failed deliveries of subscription",
+			id:     failed.ID,
+			token:  token,
+			size:   n,
+			status: notifications.Failed,
+		},
+		{
+			desc:  "list deliveries of subscription owned by other user",
+			id:    saved.ID,
+			token: otherToken,
+			size:  0,
+			err:   notifications.ErrNotFound,
+		},
+		{
+			desc:  "list deliveries with wrong credentials",
+			id:    saved.ID,
+			token: wrongValue,
+			size:  0,
+			err:   notifications.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		page, err := svc.ListDeliveries(context.Background(), tc.token, tc.id, 0, 10)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.size, len(page.Deliveries), fmt.Sprintf("%s: expected %d got %d\n", tc.desc, tc.size, len(page.Deliveries)))
+		for _, d := range page.Deliveries {
+			assert.Equal(t, tc.status, d.Status, fmt.Sprintf("%s: expected status %s got %s\n", tc.desc, tc.status, d.Status))
+		}
+	}
+}
+
+func TestNotify(t *testing.T) {
+	otherSubtopic := message(35)
+	otherSubtopic.Subtopic = "humidity"
+
+	throttled := sub
+	throttled.Throttle = time.Hour
+
+	deduplicated := sub
+	deduplicated.Dedup = time.Hour
+
+	cases := []struct {
+		desc string
+		sub  notifications.Subscription
+		msgs []mainflux.Message
+		sent int
+	}{
+		{
+			desc: "notify about every matching message",
+			sub:  sub,
+			msgs: []mainflux.Message{message(35), message(35), message(36)},
+			sent: 3,
+		},
+		{
+			desc: "skip message with non-matching subtopic",
+			sub:  sub,
+			msgs: []mainflux.Message{otherSubtopic},
+			sent: 0,
+		},
+		{
+			desc: "throttle notifications within period",
+			sub:  throttled,
+			msgs: []mainflux.Message{message(35), message(36), message(37)},
+			sent: 1,
+		},
+		{
+			desc: "deduplicate notifications of the same message",
+			sub:  deduplicated,
+			msgs: []mainflux.Message{message(35), message(35), message(36)},
+			sent: 2,
+		},
+	}
+
+	for _, tc := range cases {
+		svc, notifier := newService()
+		_, err := svc.CreateSubscription(context.Background(), token, tc.sub)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s\n", tc.desc, err))
+
+		for _, msg := range tc.msgs {
+			err := svc.Notify(context.Background(), msg)
+			assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s\n", tc.desc, err))
+		}
+
+		sent := notifier.Sent()
+		assert.Equal(t, tc.sent, len(sent), fmt.Sprintf("%s: expected %d notifications got %d\n", tc.desc, tc.sent, len(sent)))
+	}
+}
+
+func TestReload(t *testing.T) {
+	users := mocks.NewUsersService(map[string]string{token: email})
+	channels := mocks.NewChannels(map[string]string{chanID: token})
+	repo := mocks.NewSubscriptionRepository()
+	deliveries := mocks.NewDeliveryRepository()
+	notifier := mocks.NewNotifier()
+	notifiers := map[string]notifications.Notifier{notifications.SMTP: notifier}
+
+	// Subscription is created through the other service instance.
+	other := notifications.New(users, channels, repo, deliveries, notifiers, mocks.NewIdentityProvider())
+	_, err := other.CreateSubscription(context.Background(), token, sub)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	svc := notifications.New(users, channels, repo, deliveries, notifiers, mocks.NewIdentityProvider())
+	err = svc.Notify(context.Background(), message(35))
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.Empty(t, notifier.Sent(), "subscription must not be active before reload")
+
+	err = svc.Reload(context.Background())
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	err = svc.Notify(context.Background(), message(35))
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.Equal(t, 1, len(notifier.Sent()), fmt.Sprintf("expected 1 notification got %d\n", len(notifier.Sent())))
+}
+
+func message(val float64) mainflux.Message {
+	return mainflux.Message{
+		Channel:   chanID,
+		Subtopic:  "temp.room",
+		Publisher: "device",
+		Name:      "temperature",
+		Time:      float64(time.Now().Unix()),
+		Value:     &mainflux.Message_FloatValue{FloatValue: val},
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package things contains the channels ownership check backed by the
+// Mainflux SDK.
+package things
+
+import (
+	"github.com/mainflux/mainflux/notifications"
+	mfsdk "github.com/mainflux/mainflux/sdk/go"
+)
+
+var _ notifications.Channels = (*channels)(nil)
+
+type channels struct {
+	sdk mfsdk.SDK
+}
+
+// New returns channels API client backed by the provided SDK. Since the
+// things service returns only the channels owned by the user, the channel
+// is owned if it can be retrieved.
+func New(sdk mfsdk.SDK) notifications.Channels {
+	return channels{sdk: sdk}
+}
+
+func (c channels) Authorize(token, chanID string) error {
+	_, err := c.sdk.Channel(chanID, token)
+	switch err {
+	case nil:
+		return nil
+	case mfsdk.ErrUnauthorized:
+		return notifications.ErrUnauthorizedAccess
+	case mfsdk.ErrNotFound:
+		return notifications.ErrNotFound
+	default:
+		return err
+	}
+}
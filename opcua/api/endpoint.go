@@ -0,0 +1,137 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/mainflux/mainflux/opcua"
+)
+
+func browseEndpoint(svc opcua.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(browseReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		nodes, err := svc.Browse(ctx, req.token, req.serverURI, req.nodeID)
+		if err != nil {
+			return nil, err
+		}
+
+		res := browseRes{Nodes: []nodeRes{}}
+		for _, n := range nodes {
+			res.Nodes = append(res.Nodes, nodeRes{
+				NodeID:      n.NodeID,
+				BrowseName:  n.BrowseName,
+				DisplayName: n.DisplayName,
+				Class:       n.Class,
+				DataType:    n.DataType,
+			})
+		}
+
+		return res, nil
+	}
+}
+
+func createRouteEndpoint(svc opcua.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(createRouteReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		route := opcua.Route{
+			ServerURI: req.ServerURI,
+			NodeID:    req.NodeID,
+			ThingID:   req.ThingID,
+			ChannelID: req.ChannelID,
+			Interval:  time.Duration(req.Interval) * time.Millisecond,
+		}
+
+		saved, err := svc.CreateRoute(ctx, req.token, route)
+		if err != nil {
+			return nil, err
+		}
+
+		return routeRes{id: saved.ID}, nil
+	}
+}
+
+func viewRouteEndpoint(svc opcua.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewRouteReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		route, err := svc.ViewRoute(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		return toRouteRes(route), nil
+	}
+}
+
+func listRoutesEndpoint(svc opcua.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listRoutesReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		page, err := svc.ListRoutes(ctx, req.token, req.offset, req.limit)
+		if err != nil {
+			return nil, err
+		}
+
+		res := routesPageRes{
+			Total:  page.Total,
+			Offset: page.Offset,
+			Limit:  page.Limit,
+			Routes: []viewRouteRes{},
+		}
+		for _, r := range page.Routes {
+			res.Routes = append(res.Routes, toRouteRes(r))
+		}
+
+		return res, nil
+	}
+}
+
+func removeRouteEndpoint(svc opcua.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewRouteReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RemoveRoute(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
+func toRouteRes(r opcua.Route) viewRouteRes {
+	return viewRouteRes{
+		ID:        r.ID,
+		ServerURI: r.ServerURI,
+		NodeID:    r.NodeID,
+		ThingID:   r.ThingID,
+		ChannelID: r.ChannelID,
+		Interval:  uint64(r.Interval / time.Millisecond),
+		Created:   r.Created,
+	}
+}
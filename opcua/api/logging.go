@@ -0,0 +1,106 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !test
+// +build !test
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/opcua"
+)
+
+var _ opcua.Service = (*loggingMiddleware)(nil)
+
+type loggingMiddleware struct {
+	logger log.Logger
+	svc    opcua.Service
+}
+
+// LoggingMiddleware adds logging facilities to the core service.
+func LoggingMiddleware(svc opcua.Service, logger log.Logger) opcua.Service {
+	return &loggingMiddleware{logger, svc}
+}
+
+func (lm *loggingMiddleware) Browse(ctx context.Context, token, serverURI, nodeID string) (nodes []opcua.Node, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method browse for token %s and node %s of %s took %s to complete", token, nodeID, serverURI, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Browse(ctx, token, serverURI, nodeID)
+}
+
+func (lm *loggingMiddleware) CreateRoute(ctx context.Context, token string, route opcua.Route) (saved opcua.Route, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method create_route for token %s and route %s took %s to complete", token, saved.ID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.CreateRoute(ctx, token, route)
+}
+
+func (lm *loggingMiddleware) ViewRoute(ctx context.Context, token, id string) (route opcua.Route, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method view_route for token %s and route %s took %s to complete", token, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ViewRoute(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) ListRoutes(ctx context.Context, token string, offset, limit uint64) (page opcua.RoutesPage, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_routes for token %s took %s to complete", token, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListRoutes(ctx, token, offset, limit)
+}
+
+func (lm *loggingMiddleware) RemoveRoute(ctx context.Context, token, id string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method remove_route for token %s and route %s took %s to complete", token, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RemoveRoute(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) Restore(ctx context.Context) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method restore took %s to complete", time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Restore(ctx)
+}
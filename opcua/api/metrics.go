@@ -0,0 +1,87 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !test
+// +build !test
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/mainflux/mainflux/opcua"
+)
+
+var _ opcua.Service = (*metricsMiddleware)(nil)
+
+type metricsMiddleware struct {
+	counter metrics.Counter
+	latency metrics.Histogram
+	svc     opcua.Service
+}
+
+// MetricsMiddleware instruments core service by tracking request count and
+// latency.
+func MetricsMiddleware(svc opcua.Service, counter metrics.Counter, latency metrics.Histogram) opcua.Service {
+	return &metricsMiddleware{
+		counter: counter,
+		latency: latency,
+		svc:     svc,
+	}
+}
+
+func (ms *metricsMiddleware) Browse(ctx context.Context, token, serverURI, nodeID string) ([]opcua.Node, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "browse").Add(1)
+		ms.latency.With("method", "browse").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Browse(ctx, token, serverURI, nodeID)
+}
+
+func (ms *metricsMiddleware) CreateRoute(ctx context.Context, token string, route opcua.Route) (opcua.Route, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "create_route").Add(1)
+		ms.latency.With("method", "create_route").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.CreateRoute(ctx, token, route)
+}
+
+func (ms *metricsMiddleware) ViewRoute(ctx context.Context, token, id string) (opcua.Route, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_route").Add(1)
+		ms.latency.With("method", "view_route").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewRoute(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) ListRoutes(ctx context.Context, token string, offset, limit uint64) (opcua.RoutesPage, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_routes").Add(1)
+		ms.latency.With("method", "list_routes").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListRoutes(ctx, token, offset, limit)
+}
+
+func (ms *metricsMiddleware) RemoveRoute(ctx context.Context, token, id string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "remove_route").Add(1)
+		ms.latency.With("method", "remove_route").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RemoveRoute(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) Restore(ctx context.Context) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "restore").Add(1)
+		ms.latency.With("method", "restore").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Restore(ctx)
+}
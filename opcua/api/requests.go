@@ -0,0 +1,97 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"strings"
+
+	"github.com/mainflux/mainflux/opcua"
+)
+
+const (
+	maxLimitSize = 100
+	schemePrefix = "opc.tcp://"
+)
+
+type apiReq interface {
+	validate() error
+}
+
+type browseReq struct {
+	token     string
+	serverURI string
+	nodeID    string
+}
+
+func (req browseReq) validate() error {
+	if req.token == "" {
+		return opcua.ErrUnauthorizedAccess
+	}
+
+	if !strings.HasPrefix(req.serverURI, schemePrefix) {
+		return opcua.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type createRouteReq struct {
+	token     string
+	ServerURI string `json:"server_uri"`
+	NodeID    string `json:"node_id"`
+	ThingID   string `json:"thing_id"`
+	ChannelID string `json:"channel_id"`
+	Interval  uint64 `json:"interval,omitempty"`
+}
+
+func (req createRouteReq) validate() error {
+	if req.token == "" {
+		return opcua.ErrUnauthorizedAccess
+	}
+
+	if !strings.HasPrefix(req.ServerURI, schemePrefix) {
+		return opcua.ErrMalformedEntity
+	}
+
+	if req.NodeID == "" || req.ThingID == "" || req.ChannelID == "" {
+		return opcua.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type viewRouteReq struct {
+	token string
+	id    string
+}
+
+func (req viewRouteReq) validate() error {
+	if req.token == "" {
+		return opcua.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" {
+		return opcua.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type listRoutesReq struct {
+	token  string
+	offset uint64
+	limit  uint64
+}
+
+func (req listRoutesReq) validate() error {
+	if req.token == "" {
+		return opcua.ErrUnauthorizedAccess
+	}
+
+	if req.limit == 0 || req.limit > maxLimitSize {
+		return opcua.ErrMalformedEntity
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/mainflux/mainflux"
+)
+
+var (
+	_ mainflux.Response = (*browseRes)(nil)
+	_ mainflux.Response = (*routeRes)(nil)
+	_ mainflux.Response = (*viewRouteRes)(nil)
+	_ mainflux.Response = (*routesPageRes)(nil)
+	_ mainflux.Response = (*removeRes)(nil)
+)
+
+type nodeRes struct {
+	NodeID      string `json:"node_id"`
+	BrowseName  string `json:"browse_name"`
+	DisplayName string `json:"display_name"`
+	Class       string `json:"class"`
+	DataType    string `json:"data_type,omitempty"`
+}
+
+type browseRes struct {
+	Nodes []nodeRes `json:"nodes"`
+}
+
+func (res browseRes) Code() int {
+	return http.StatusOK
+}
+
+func (res browseRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res browseRes) Empty() bool {
+	return false
+}
+
+type routeRes struct {
+	id string
+}
+
+func (res routeRes) Code() int {
+	return http.StatusCreated
+}
+
+func (res routeRes) Headers() map[string]string {
+	return map[string]string{
+		"Location": fmt.Sprintf("/routes/%s", res.id),
+	}
+}
+
+func (res routeRes) Empty() bool {
+	return true
+}
+
+type viewRouteRes struct {
+	ID        string    `json:"id"`
+	ServerURI string    `json:"server_uri"`
+	NodeID    string    `json:"node_id"`
+	ThingID   string    `json:"thing_id"`
+	ChannelID string    `json:"channel_id"`
+	Interval  uint64    `json:"interval"`
+	Created   time.Time `json:"created_at"`
+}
+
+func (res viewRouteRes) Code() int {
+	return http.StatusOK
+}
+
+func (res viewRouteRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res viewRouteRes) Empty() bool {
+	return false
+}
+
+type routesPageRes struct {
+	Total  uint64         `json:"total"`
+	Offset uint64         `json:"offset"`
+	Limit  uint64         `json:"limit"`
+	Routes []viewRouteRes `json:"routes"`
+}
+
+func (res routesPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res routesPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res routesPageRes) Empty() bool {
+	return false
+}
+
+type removeRes struct{}
+
+func (res removeRes) Code() int {
+	return http.StatusNoContent
+}
+
+func (res removeRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res removeRes) Empty() bool {
+	return true
+}
@@ -0,0 +1,8 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package opcua contains the domain concept definitions needed to support
+// Mainflux OPC-UA adapter service functionality. OPC-UA adapter connects to
+// the OPC-UA servers, exposes their address space for browsing, and publishes
+// the values of the monitored nodes to the channels they're routed to.
+package opcua
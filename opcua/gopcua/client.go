@@ -0,0 +1,533 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package gopcua
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	opc "github.com/gopcua/opcua"
+	"github.com/gopcua/opcua/id"
+	"github.com/gopcua/opcua/ua"
+	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/opcua"
+)
+
+const (
+	securityNone  = "None"
+	retryInterval = 5 * time.Second
+)
+
+var (
+	_ opcua.Browser    = (*Client)(nil)
+	_ opcua.Subscriber = (*Client)(nil)
+)
+
+// dataTypes contains the names of the built-in data types, reported as the
+// data types of the variable nodes.
+var dataTypes = map[uint32]string{
+	id.Boolean:       "Boolean",
+	id.SByte:         "SByte",
+	id.Byte:          "Byte",
+	id.Int16:         "Int16",
+	id.UInt16:        "UInt16",
+	id.Int32:         "Int32",
+	id.UInt32:        "UInt32",
+	id.Int64:         "Int64",
+	id.UInt64:        "UInt64",
+	id.Float:         "Float",
+	id.Double:        "Double",
+	id.String:        "String",
+	id.DateTime:      "DateTime",
+	id.GUID:          "Guid",
+	id.ByteString:    "ByteString",
+	id.LocalizedText: "LocalizedText",
+}
+
+// Config contains the security settings used to connect to the servers.
+// Policy and mode default to None, in which case the certificate and the
+// private key aren't used.
+type Config struct {
+	Policy   string
+	Mode     string
+	CertFile string
+	KeyFile  string
+}
+
+// item represents the monitored item of the server node.
+type item struct {
+	node     string
+	nodeID   *ua.NodeID
+	handle   uint32
+	id       uint32
+	interval time.Duration
+	handler  func(opcua.Value)
+}
+
+// server contains the connection to the OPC-UA server, and the subscription
+// holding the monitored items of its nodes.
+type server struct {
+	uri     string
+	client  *opc.Client
+	sub     *opc.Subscription
+	cancel  context.CancelFunc
+	items   map[string]*item
+	handles map[uint32]*item
+}
+
+// Client is the OPC-UA client, which keeps a single connection and
+// subscription per monitored server. Lost connections are re-established,
+// and their monitored items recreated, in the background.
+type Client struct {
+	cfg     Config
+	logger  logger.Logger
+	mu      sync.Mutex
+	handle  uint32
+	servers map[string]*server
+}
+
+// New instantiates the OPC-UA client.
+func New(cfg Config, logger logger.Logger) *Client {
+	return &Client{
+		cfg:     cfg,
+		logger:  logger,
+		servers: make(map[string]*server),
+	}
+}
+
+// Browse retrieves the nodes hierarchically referenced by the provided node.
+// Objects folder is browsed if the node isn't specified.
+func (c *Client) Browse(ctx context.Context, serverURI, nodeID string) ([]opcua.Node, error) {
+	nid := ua.NewNumericNodeID(0, id.ObjectsFolder)
+	if nodeID != "" {
+		var err error
+		if nid, err = ua.ParseNodeID(nodeID); err != nil {
+			return nil, opcua.ErrMalformedEntity
+		}
+	}
+
+	oc, err := c.connect(ctx, serverURI)
+	if err != nil {
+		return nil, err
+	}
+	defer oc.Close()
+
+	refs, err := references(oc, nid)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make([]opcua.Node, len(refs))
+	reads := []*ua.ReadValueID{}
+	vars := []int{}
+	for i, ref := range refs {
+		nodes[i] = toNode(ref)
+		if ref.NodeClass == ua.NodeClassVariable && ref.NodeID != nil {
+			reads = append(reads, &ua.ReadValueID{NodeID: ref.NodeID.NodeID, AttributeID: ua.AttributeIDDataType})
+			vars = append(vars, i)
+		}
+	}
+
+	if len(reads) == 0 {
+		return nodes, nil
+	}
+
+	req := &ua.ReadRequest{
+		TimestampsToReturn: ua.TimestampsToReturnNeither,
+		NodesToRead:        reads,
+	}
+	res, err := oc.Read(req)
+	if err != nil {
+		return nil, opcua.ErrUnreachable
+	}
+
+	for i, r := range res.Results {
+		if i >= len(vars) || r.Status != ua.StatusOK || r.Value == nil {
+			continue
+		}
+		if dt, ok := r.Value.Value().(*ua.NodeID); ok {
+			nodes[vars[i]].DataType = dataType(dt)
+		}
+	}
+
+	return nodes, nil
+}
+
+// Subscribe creates the monitored item of the server node. Connection to the
+// server and the subscription are created along with its first monitored item.
+func (c *Client) Subscribe(ctx context.Context, serverURI, nodeID string, interval time.Duration, handler func(opcua.Value)) error {
+	nid, err := ua.ParseNodeID(nodeID)
+	if err != nil {
+		return opcua.ErrMalformedEntity
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	srv, ok := c.servers[serverURI]
+	if ok {
+		if _, ok := srv.items[nodeID]; ok {
+			return nil
+		}
+	}
+
+	if !ok {
+		srv = &server{
+			uri:     serverURI,
+			items:   make(map[string]*item),
+			handles: make(map[uint32]*item),
+		}
+		if err := c.open(ctx, srv); err != nil {
+			return err
+		}
+		c.servers[serverURI] = srv
+	}
+
+	c.handle++
+	it := &item{
+		node:     nodeID,
+		nodeID:   nid,
+		handle:   c.handle,
+		interval: interval,
+		handler:  handler,
+	}
+
+	// Items of the server which is being reconnected to are created once
+	// the connection is re-established.
+	if srv.sub != nil {
+		if err := srv.monitor(it); err != nil {
+			if len(srv.items) == 0 {
+				srv.close()
+				delete(c.servers, serverURI)
+			}
+			return err
+		}
+	}
+
+	srv.items[nodeID] = it
+	srv.handles[it.handle] = it
+
+	return nil
+}
+
+// Unsubscribe deletes the monitored item of the server node. Connection to
+// the server is closed once its last monitored item is deleted.
+func (c *Client) Unsubscribe(ctx context.Context, serverURI, nodeID string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	srv, ok := c.servers[serverURI]
+	if !ok {
+		return nil
+	}
+
+	it, ok := srv.items[nodeID]
+	if !ok {
+		return nil
+	}
+
+	delete(srv.items, nodeID)
+	delete(srv.handles, it.handle)
+
+	if len(srv.items) == 0 {
+		srv.close()
+		delete(c.servers, serverURI)
+		return nil
+	}
+
+	if srv.sub == nil {
+		return nil
+	}
+
+	if _, err := srv.sub.Unmonitor(it.id); err != nil {
+		return opcua.ErrUnreachable
+	}
+
+	return nil
+}
+
+// connect establishes the session with the server, using the configured
+// security policy and mode.
+func (c *Client) connect(ctx context.Context, uri string) (*opc.Client, error) {
+	opts := []opc.Option{}
+
+	if !isNone(c.cfg.Policy) || !isNone(c.cfg.Mode) {
+		eps, err := opc.GetEndpoints(uri)
+		if err != nil {
+			return nil, opcua.ErrUnreachable
+		}
+
+		ep := opc.SelectEndpoint(eps, c.cfg.Policy, ua.MessageSecurityModeFromString(c.cfg.Mode))
+		if ep == nil {
+			c.logger.Warn(fmt.Sprintf("Server %s has no endpoint matching policy %s and mode %s", uri, c.cfg.Policy, c.cfg.Mode))
+			return nil, opcua.ErrUnreachable
+		}
+
+		opts = append(opts,
+			opc.CertificateFile(c.cfg.CertFile),
+			opc.PrivateKeyFile(c.cfg.KeyFile),
+			opc.SecurityFromEndpoint(ep, ua.UserTokenTypeAnonymous),
+		)
+	}
+
+	oc := opc.NewClient(uri, opts...)
+	if err := oc.Connect(ctx); err != nil {
+		c.logger.Warn(fmt.Sprintf("Failed to connect to %s: %s", uri, err))
+		return nil, opcua.ErrUnreachable
+	}
+
+	return oc, nil
+}
+
+// open connects to the server, creates the subscription along with the
+// monitored items of the server, and starts listening for notifications.
+func (c *Client) open(ctx context.Context, srv *server) error {
+	oc, err := c.connect(ctx, srv.uri)
+	if err != nil {
+		return err
+	}
+
+	notifs := make(chan *opc.PublishNotificationData)
+	sub, err := oc.Subscribe(&opc.SubscriptionParameters{}, notifs)
+	if err != nil {
+		oc.Close()
+		return opcua.ErrUnreachable
+	}
+
+	srv.client = oc
+	srv.sub = sub
+
+	for _, it := range srv.items {
+		if err := srv.monitor(it); err != nil {
+			c.logger.Warn(fmt.Sprintf("Failed to monitor node %s of %s: %s", it.node, srv.uri, err))
+		}
+	}
+
+	lctx, cancel := context.WithCancel(context.Background())
+	srv.cancel = cancel
+
+	go sub.Run(lctx)
+	go c.listen(lctx, srv, notifs)
+
+	return nil
+}
+
+// listen passes the reported values to the handlers of the monitored items,
+// until the subscription fails.
+func (c *Client) listen(ctx context.Context, srv *server, notifs <-chan *opc.PublishNotificationData) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case n := <-notifs:
+			if n.Error != nil {
+				c.logger.Warn(fmt.Sprintf("Subscription to %s failed: %s", srv.uri, n.Error))
+				go c.reconnect(srv)
+				return
+			}
+			c.notify(srv, n)
+		}
+	}
+}
+
+func (c *Client) notify(srv *server, n *opc.PublishNotificationData) {
+	dcn, ok := n.Value.(*ua.DataChangeNotification)
+	if !ok {
+		return
+	}
+
+	for _, mi := range dcn.MonitoredItems {
+		c.mu.Lock()
+		it, ok := srv.handles[mi.ClientHandle]
+		c.mu.Unlock()
+
+		if !ok || mi.Value == nil || mi.Value.Value == nil || mi.Value.Status != ua.StatusOK {
+			continue
+		}
+
+		it.handler(opcua.Value{
+			ServerURI: srv.uri,
+			NodeID:    it.node,
+			Value:     toValue(mi.Value.Value.Value()),
+			Time:      timestamp(mi.Value),
+		})
+	}
+}
+
+// reconnect re-establishes the connection to the server, retrying until it
+// succeeds or the server is no longer monitored.
+func (c *Client) reconnect(srv *server) {
+	c.mu.Lock()
+	srv.close()
+	c.mu.Unlock()
+
+	for {
+		time.Sleep(retryInterval)
+
+		c.mu.Lock()
+		if c.servers[srv.uri] != srv {
+			c.mu.Unlock()
+			return
+		}
+		err := c.open(context.Background(), srv)
+		c.mu.Unlock()
+
+		if err == nil {
+			c.logger.Info(fmt.Sprintf("Reconnected to %s", srv.uri))
+			return
+		}
+	}
+}
+
+// monitor creates the monitored item within the subscription of the server.
+func (srv *server) monitor(it *item) error {
+	req := opc.NewMonitoredItemCreateRequestWithDefaults(it.nodeID, ua.AttributeIDValue, it.handle)
+	req.RequestedParameters.SamplingInterval = float64(it.interval / time.Millisecond)
+
+	res, err := srv.sub.Monitor(ua.TimestampsToReturnBoth, req)
+	if err != nil || len(res.Results) == 0 {
+		return opcua.ErrUnreachable
+	}
+
+	if code := res.Results[0].StatusCode; code != ua.StatusOK {
+		return toError(code)
+	}
+
+	it.id = res.Results[0].MonitoredItemID
+	return nil
+}
+
+// close stops listening for notifications, and closes the connection to the
+// server.
+func (srv *server) close() {
+	if srv.sub == nil {
+		return
+	}
+
+	srv.cancel()
+	srv.sub.Cancel()
+	srv.client.Close()
+
+	srv.client = nil
+	srv.sub = nil
+}
+
+// references retrieves the references of the node, following the
+// continuation points.
+func references(oc *opc.Client, nid *ua.NodeID) ([]*ua.ReferenceDescription, error) {
+	req := &ua.BrowseRequest{
+		View: &ua.ViewDescription{
+			ViewID:    ua.NewTwoByteNodeID(0),
+			Timestamp: time.Now(),
+		},
+		NodesToBrowse: []*ua.BrowseDescription{
+			{
+				NodeID:          nid,
+				BrowseDirection: ua.BrowseDirectionForward,
+				ReferenceTypeID: ua.NewNumericNodeID(0, id.HierarchicalReferences),
+				IncludeSubtypes: true,
+				NodeClassMask:   uint32(ua.NodeClassAll),
+				ResultMask:      uint32(ua.BrowseResultMaskAll),
+			},
+		},
+	}
+
+	res, err := oc.Browse(req)
+	if err != nil {
+		return nil, opcua.ErrUnreachable
+	}
+
+	results := res.Results
+	refs := []*ua.ReferenceDescription{}
+	for len(results) > 0 {
+		r := results[0]
+		if r.StatusCode != ua.StatusOK {
+			return nil, toError(r.StatusCode)
+		}
+
+		refs = append(refs, r.References...)
+		if len(r.ContinuationPoint) == 0 {
+			break
+		}
+
+		next, err := oc.BrowseNext(&ua.BrowseNextRequest{ContinuationPoints: [][]byte{r.ContinuationPoint}})
+		if err != nil {
+			return nil, opcua.ErrUnreachable
+		}
+		results = next.Results
+	}
+
+	return refs, nil
+}
+
+func toNode(ref *ua.ReferenceDescription) opcua.Node {
+	n := opcua.Node{
+		Class: strings.TrimPrefix(ref.NodeClass.String(), "NodeClass"),
+	}
+
+	if ref.NodeID != nil {
+		n.NodeID = ref.NodeID.NodeID.String()
+	}
+	if ref.BrowseName != nil {
+		n.BrowseName = ref.BrowseName.Name
+	}
+	if ref.DisplayName != nil {
+		n.DisplayName = ref.DisplayName.Text
+	}
+
+	return n
+}
+
+func dataType(nid *ua.NodeID) string {
+	if nid.Namespace() == 0 && nid.Type() == ua.NodeIDTypeNumeric {
+		if name, ok := dataTypes[nid.IntID()]; ok {
+			return name
+		}
+	}
+
+	return nid.String()
+}
+
+// toValue converts the structured values to their textual representation.
+func toValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case *ua.LocalizedText:
+		return val.Text
+	case *ua.QualifiedName:
+		return val.Name
+	case *ua.NodeID:
+		return val.String()
+	case *ua.ExpandedNodeID:
+		return val.NodeID.String()
+	default:
+		return v
+	}
+}
+
+func timestamp(dv *ua.DataValue) time.Time {
+	switch {
+	case !dv.SourceTimestamp.IsZero():
+		return dv.SourceTimestamp
+	case !dv.ServerTimestamp.IsZero():
+		return dv.ServerTimestamp
+	default:
+		return time.Now()
+	}
+}
+
+func toError(code ua.StatusCode) error {
+	switch code {
+	case ua.StatusBadNodeIDUnknown, ua.StatusBadNodeIDInvalid:
+		return opcua.ErrNotFound
+	default:
+		return code
+	}
+}
+
+func isNone(s string) bool {
+	return s == "" || s == securityNone
+}
@@ -0,0 +1,6 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package gopcua contains the OPC-UA client which browses the address space
+// of the servers and monitors their nodes, backed by the gopcua library.
+package gopcua
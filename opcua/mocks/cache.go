@@ -0,0 +1,71 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mainflux/mainflux/opcua"
+)
+
+var _ opcua.RouteCache = (*RouteCache)(nil)
+
+// RouteCache is an in-memory route cache which records the cache misses.
+type RouteCache struct {
+	mu     sync.Mutex
+	routes map[string][]opcua.Route
+	misses int
+}
+
+// NewRouteCache returns route cache mock.
+func NewRouteCache() *RouteCache {
+	return &RouteCache{
+		routes: make(map[string][]opcua.Route),
+	}
+}
+
+// Misses returns the number of the cache misses.
+func (rc *RouteCache) Misses() int {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	return rc.misses
+}
+
+// Save stores the routes of the node.
+func (rc *RouteCache) Save(_ context.Context, serverURI, nodeID string, routes []opcua.Route) error {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	rc.routes[key(serverURI, nodeID)] = routes
+	return nil
+}
+
+// Retrieve retrieves the routes of the node.
+func (rc *RouteCache) Retrieve(_ context.Context, serverURI, nodeID string) ([]opcua.Route, error) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	routes, ok := rc.routes[key(serverURI, nodeID)]
+	if !ok {
+		rc.misses++
+		return nil, opcua.ErrNotFound
+	}
+
+	return routes, nil
+}
+
+// Remove removes the routes of the node.
+func (rc *RouteCache) Remove(_ context.Context, serverURI, nodeID string) error {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	delete(rc.routes, key(serverURI, nodeID))
+	return nil
+}
+
+func key(serverURI, nodeID string) string {
+	return serverURI + "|" + nodeID
+}
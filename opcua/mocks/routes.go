@@ -0,0 +1,125 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/mainflux/mainflux/opcua"
+)
+
+var _ opcua.RouteRepository = (*routeRepositoryMock)(nil)
+
+type routeRepositoryMock struct {
+	mu     sync.Mutex
+	routes map[string]opcua.Route
+}
+
+// NewRouteRepository creates in-memory route repository.
+func NewRouteRepository() opcua.RouteRepository {
+	return &routeRepositoryMock{
+		routes: make(map[string]opcua.Route),
+	}
+}
+
+func (repo *routeRepositoryMock) Save(_ context.Context, route opcua.Route) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for _, r := range repo.routes {
+		if r.ServerURI == route.ServerURI && r.NodeID == route.NodeID && r.ChannelID == route.ChannelID {
+			return opcua.ErrConflict
+		}
+	}
+	repo.routes[route.ID] = route
+
+	return nil
+}
+
+func (repo *routeRepositoryMock) RetrieveByID(_ context.Context, owner, id string) (opcua.Route, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	r, ok := repo.routes[id]
+	if !ok || r.Owner != owner {
+		return opcua.Route{}, opcua.ErrNotFound
+	}
+
+	return r, nil
+}
+
+func (repo *routeRepositoryMock) RetrieveByOwner(_ context.Context, owner string, offset, limit uint64) (opcua.RoutesPage, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	routes := []opcua.Route{}
+	for _, r := range repo.sorted() {
+		if r.Owner == owner {
+			routes = append(routes, r)
+		}
+	}
+
+	page := opcua.RoutesPage{
+		Total:  uint64(len(routes)),
+		Offset: offset,
+		Limit:  limit,
+		Routes: []opcua.Route{},
+	}
+	if offset >= page.Total {
+		return page, nil
+	}
+
+	end := offset + limit
+	if end > page.Total {
+		end = page.Total
+	}
+	page.Routes = routes[offset:end]
+
+	return page, nil
+}
+
+func (repo *routeRepositoryMock) RetrieveByNode(_ context.Context, serverURI, nodeID string) ([]opcua.Route, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	routes := []opcua.Route{}
+	for _, r := range repo.sorted() {
+		if r.ServerURI == serverURI && r.NodeID == nodeID {
+			routes = append(routes, r)
+		}
+	}
+
+	return routes, nil
+}
+
+func (repo *routeRepositoryMock) RetrieveAll(context.Context) ([]opcua.Route, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	return repo.sorted(), nil
+}
+
+func (repo *routeRepositoryMock) Remove(_ context.Context, owner, id string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if r, ok := repo.routes[id]; ok && r.Owner == owner {
+		delete(repo.routes, id)
+	}
+
+	return nil
+}
+
+// sorted returns the routes sorted by their identifiers.
+func (repo *routeRepositoryMock) sorted() []opcua.Route {
+	routes := []opcua.Route{}
+	for _, r := range repo.routes {
+		routes = append(routes, r)
+	}
+	sort.Slice(routes, func(i, j int) bool { return routes[i].ID < routes[j].ID })
+
+	return routes
+}
@@ -0,0 +1,119 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/mainflux/mainflux/opcua"
+)
+
+var (
+	_ opcua.Browser    = (*Servers)(nil)
+	_ opcua.Subscriber = (*Servers)(nil)
+)
+
+// Servers simulates the OPC-UA servers, which contain the given nodes.
+type Servers struct {
+	mu        sync.Mutex
+	nodes     map[string][]opcua.Node
+	monitored map[string]func(opcua.Value)
+	intervals map[string]time.Duration
+}
+
+// NewServers returns the OPC-UA servers mock. Nodes are mapped to the
+// server URIs. Browsing the node returns all the nodes of the server.
+func NewServers(nodes map[string][]opcua.Node) *Servers {
+	return &Servers{
+		nodes:     nodes,
+		monitored: make(map[string]func(opcua.Value)),
+		intervals: make(map[string]time.Duration),
+	}
+}
+
+// Browse returns the nodes of the server.
+func (s *Servers) Browse(_ context.Context, serverURI, nodeID string) ([]opcua.Node, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	nodes, ok := s.nodes[serverURI]
+	if !ok {
+		return nil, opcua.ErrUnreachable
+	}
+	if !s.contains(serverURI, nodeID) {
+		return nil, opcua.ErrNotFound
+	}
+
+	return nodes, nil
+}
+
+// Subscribe monitors the node of the server.
+func (s *Servers) Subscribe(_ context.Context, serverURI, nodeID string, interval time.Duration, handle func(opcua.Value)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.nodes[serverURI]; !ok {
+		return opcua.ErrUnreachable
+	}
+	if !s.contains(serverURI, nodeID) {
+		return opcua.ErrNotFound
+	}
+
+	k := key(serverURI, nodeID)
+	if _, ok := s.monitored[k]; !ok {
+		s.monitored[k] = handle
+		s.intervals[k] = interval
+	}
+
+	return nil
+}
+
+// Unsubscribe stops monitoring the node of the server.
+func (s *Servers) Unsubscribe(_ context.Context, serverURI, nodeID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k := key(serverURI, nodeID)
+	delete(s.monitored, k)
+	delete(s.intervals, k)
+
+	return nil
+}
+
+// Monitored returns the sampling interval of the node, and whether the node
+// is monitored.
+func (s *Servers) Monitored(serverURI, nodeID string) (time.Duration, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	interval, ok := s.intervals[key(serverURI, nodeID)]
+	return interval, ok
+}
+
+// Report reports the value of the monitored node.
+func (s *Servers) Report(v opcua.Value) {
+	s.mu.Lock()
+	handle, ok := s.monitored[key(v.ServerURI, v.NodeID)]
+	s.mu.Unlock()
+
+	if ok {
+		handle(v)
+	}
+}
+
+func (s *Servers) contains(serverURI, nodeID string) bool {
+	if nodeID == "" {
+		return true
+	}
+
+	for _, n := range s.nodes[serverURI] {
+		if n.NodeID == nodeID {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,93 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/mainflux/mainflux"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var _ mainflux.ThingsServiceClient = (*thingsClient)(nil)
+
+type thingsClient struct {
+	owners map[string]string
+	conns  map[string][]string
+}
+
+// NewThingsClient returns mock things service client that knows the owners
+// of the given things, and the channels they're connected to.
+func NewThingsClient(owners map[string]string, conns map[string][]string) mainflux.ThingsServiceClient {
+	return &thingsClient{
+		owners: owners,
+		conns:  conns,
+	}
+}
+
+func (tc thingsClient) CanAccess(context.Context, *mainflux.AccessReq, ...grpc.CallOption) (*mainflux.ThingID, error) {
+	panic("not implemented")
+}
+
+func (tc thingsClient) CanAccessByID(_ context.Context, req *mainflux.AccessByIDReq, _ ...grpc.CallOption) (*empty.Empty, error) {
+	for _, chanID := range tc.conns[req.GetThingID()] {
+		if chanID == req.GetChanID() {
+			return &empty.Empty{}, nil
+		}
+	}
+
+	return nil, status.Error(codes.PermissionDenied, "thing not connected to channel")
+}
+
+func (tc thingsClient) Identify(context.Context, *mainflux.Token, ...grpc.CallOption) (*mainflux.ThingID, error) {
+	panic("not implemented")
+}
+
+func (tc thingsClient) Owner(_ context.Context, req *mainflux.ThingID, _ ...grpc.CallOption) (*mainflux.UserID, error) {
+	owner, ok := tc.owners[req.GetValue()]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "thing not found")
+	}
+
+	return &mainflux.UserID{Value: owner}, nil
+}
+
+func (tc thingsClient) Retention(context.Context, *mainflux.ChannelID, ...grpc.CallOption) (*mainflux.Retention, error) {
+	panic("not implemented")
+}
+
+func (tc thingsClient) Region(context.Context, *mainflux.ChannelID, ...grpc.CallOption) (*mainflux.Region, error) {
+	panic("not implemented")
+}
+
+func (tc thingsClient) Alias(context.Context, *mainflux.ChannelID, ...grpc.CallOption) (*mainflux.ChannelAlias, error) {
+	panic("not implemented")
+}
+
+func (tc thingsClient) ResolveAlias(context.Context, *mainflux.ChannelAlias, ...grpc.CallOption) (*mainflux.ChannelID, error) {
+	panic("not implemented")
+}
+
+func (tc thingsClient) Ordering(context.Context, *mainflux.ChannelID, ...grpc.CallOption) (*mainflux.Ordering, error) {
+	panic("not implemented")
+}
+
+func (tc thingsClient) Transformer(context.Context, *mainflux.ChannelID, ...grpc.CallOption) (*mainflux.Transformer, error) {
+	panic("not implemented")
+}
+
+func (tc thingsClient) PayloadSchema(context.Context, *mainflux.PayloadSchemaReq, ...grpc.CallOption) (*mainflux.PayloadSchema, error) {
+	panic("not implemented")
+}
+
+func (tc thingsClient) KeyDigest(context.Context, *mainflux.ThingID, ...grpc.CallOption) (*mainflux.KeyDigest, error) {
+	panic("not implemented")
+}
+
+func (tc thingsClient) Changes(context.Context, *mainflux.ChangesReq, ...grpc.CallOption) (mainflux.ThingsService_ChangesClient, error) {
+	panic("not implemented")
+}
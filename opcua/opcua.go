@@ -0,0 +1,114 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package opcua
+
+import (
+	"context"
+	"time"
+)
+
+// Route maps the monitored node of the OPC-UA server to the channel. Values
+// of the node are published to the channel on behalf of the thing, which has
+// to be connected to it.
+type Route struct {
+	ID        string
+	Owner     string
+	ServerURI string
+	NodeID    string
+	ThingID   string
+	ChannelID string
+	Interval  time.Duration
+	Created   time.Time
+}
+
+// RoutesPage contains a page of routes.
+type RoutesPage struct {
+	Total  uint64
+	Offset uint64
+	Limit  uint64
+	Routes []Route
+}
+
+// Node represents the node of the OPC-UA server address space. Data type is
+// set for the variable nodes only.
+type Node struct {
+	NodeID      string
+	BrowseName  string
+	DisplayName string
+	Class       string
+	DataType    string
+}
+
+// Value represents the value of the monitored node, reported by the OPC-UA
+// server.
+type Value struct {
+	ServerURI string
+	NodeID    string
+	Value     interface{}
+	Time      time.Time
+}
+
+// RouteRepository specifies a route persistence API.
+type RouteRepository interface {
+	// Save persists the route. Node of the server can be routed to the
+	// channel only once.
+	Save(context.Context, Route) error
+
+	// RetrieveByID retrieves the route having the provided identifier, that
+	// is owned by the specified user.
+	RetrieveByID(context.Context, string, string) (Route, error)
+
+	// RetrieveByOwner retrieves the subset of the routes owned by the
+	// specified user.
+	RetrieveByOwner(context.Context, string, uint64, uint64) (RoutesPage, error)
+
+	// RetrieveByNode retrieves the routes of the provided server node.
+	RetrieveByNode(context.Context, string, string) ([]Route, error)
+
+	// RetrieveAll retrieves all the routes.
+	RetrieveAll(context.Context) ([]Route, error)
+
+	// Remove removes the route having the provided identifier, that is owned
+	// by the specified user.
+	Remove(context.Context, string, string) error
+}
+
+// RouteCache contains the routes of the monitored nodes, so that the values
+// reported by the servers are routed without querying the repository.
+type RouteCache interface {
+	// Save stores the routes of the provided server node.
+	Save(context.Context, string, string, []Route) error
+
+	// Retrieve retrieves the routes of the provided server node.
+	Retrieve(context.Context, string, string) ([]Route, error)
+
+	// Remove removes the routes of the provided server node from cache.
+	Remove(context.Context, string, string) error
+}
+
+// Browser specifies an API for browsing the address space of the OPC-UA
+// servers.
+type Browser interface {
+	// Browse retrieves the nodes referenced by the provided node of the
+	// server.
+	Browse(context.Context, string, string) ([]Node, error)
+}
+
+// Subscriber specifies an API for monitoring the nodes of the OPC-UA servers.
+type Subscriber interface {
+	// Subscribe creates the monitored item of the provided server node,
+	// sampled at the provided interval. Reported values are passed to the
+	// handler. Subscribing to the node which is already monitored has no
+	// effect.
+	Subscribe(context.Context, string, string, time.Duration, func(Value)) error
+
+	// Unsubscribe deletes the monitored item of the provided server node.
+	Unsubscribe(context.Context, string, string) error
+}
+
+// IdentityProvider specifies an API for generating unique identifiers.
+type IdentityProvider interface {
+	// ID generates the unique identifier.
+	ID() (string, error)
+}
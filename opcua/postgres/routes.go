@@ -0,0 +1,174 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq" // required for DB access
+	"github.com/mainflux/mainflux/opcua"
+)
+
+const (
+	errDuplicate = "unique_violation"
+	errInvalid   = "invalid_text_representation"
+)
+
+var _ opcua.RouteRepository = (*routeRepository)(nil)
+
+type routeRepository struct {
+	db *sqlx.DB
+}
+
+// New instantiates a PostgreSQL implementation of route repository.
+func New(db *sqlx.DB) opcua.RouteRepository {
+	return &routeRepository{
+		db: db,
+	}
+}
+
+func (rr routeRepository) Save(ctx context.Context, r opcua.Route) error {
+	q := `INSERT INTO routes (id, owner, server_uri, node_id, thing_id, channel_id, sampling_interval, created_at)
+	      VALUES (:id, :owner, :server_uri, :node_id, :thing_id, :channel_id, :sampling_interval, :created_at);`
+
+	if _, err := rr.db.NamedExecContext(ctx, q, toDBRoute(r)); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code.Name() {
+			case errDuplicate:
+				return opcua.ErrConflict
+			case errInvalid:
+				return opcua.ErrMalformedEntity
+			}
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (rr routeRepository) RetrieveByID(ctx context.Context, owner, id string) (opcua.Route, error) {
+	q := `SELECT id, owner, server_uri, node_id, thing_id, channel_id, sampling_interval, created_at
+	      FROM routes WHERE owner = $1 AND id = $2;`
+
+	var dbr dbRoute
+	if err := rr.db.QueryRowxContext(ctx, q, owner, id).StructScan(&dbr); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if err == sql.ErrNoRows || ok && errInvalid == pqErr.Code.Name() {
+			return opcua.Route{}, opcua.ErrNotFound
+		}
+		return opcua.Route{}, err
+	}
+
+	return toRoute(dbr), nil
+}
+
+func (rr routeRepository) RetrieveByOwner(ctx context.Context, owner string, offset, limit uint64) (opcua.RoutesPage, error) {
+	q := `SELECT id, owner, server_uri, node_id, thing_id, channel_id, sampling_interval, created_at
+	      FROM routes WHERE owner = $1 ORDER BY id LIMIT $2 OFFSET $3;`
+
+	routes, err := rr.retrieve(ctx, q, owner, limit, offset)
+	if err != nil {
+		return opcua.RoutesPage{}, err
+	}
+
+	var total uint64
+	if err := rr.db.GetContext(ctx, &total, `SELECT COUNT(*) FROM routes WHERE owner = $1;`, owner); err != nil {
+		return opcua.RoutesPage{}, err
+	}
+
+	page := opcua.RoutesPage{
+		Total:  total,
+		Offset: offset,
+		Limit:  limit,
+		Routes: routes,
+	}
+
+	return page, nil
+}
+
+func (rr routeRepository) RetrieveByNode(ctx context.Context, serverURI, nodeID string) ([]opcua.Route, error) {
+	q := `SELECT id, owner, server_uri, node_id, thing_id, channel_id, sampling_interval, created_at
+	      FROM routes WHERE server_uri = $1 AND node_id = $2 ORDER BY id;`
+
+	return rr.retrieve(ctx, q, serverURI, nodeID)
+}
+
+func (rr routeRepository) RetrieveAll(ctx context.Context) ([]opcua.Route, error) {
+	q := `SELECT id, owner, server_uri, node_id, thing_id, channel_id, sampling_interval, created_at
+	      FROM routes ORDER BY id;`
+
+	return rr.retrieve(ctx, q)
+}
+
+func (rr routeRepository) Remove(ctx context.Context, owner, id string) error {
+	q := `DELETE FROM routes WHERE owner = $1 AND id = $2;`
+
+	if _, err := rr.db.ExecContext(ctx, q, owner, id); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == errInvalid {
+			return nil
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (rr routeRepository) retrieve(ctx context.Context, q string, args ...interface{}) ([]opcua.Route, error) {
+	rows, err := rr.db.QueryxContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	routes := []opcua.Route{}
+	for rows.Next() {
+		var dbr dbRoute
+		if err := rows.StructScan(&dbr); err != nil {
+			return nil, err
+		}
+		routes = append(routes, toRoute(dbr))
+	}
+
+	return routes, rows.Err()
+}
+
+type dbRoute struct {
+	ID        string    `db:"id"`
+	Owner     string    `db:"owner"`
+	ServerURI string    `db:"server_uri"`
+	NodeID    string    `db:"node_id"`
+	ThingID   string    `db:"thing_id"`
+	ChannelID string    `db:"channel_id"`
+	Interval  int64     `db:"sampling_interval"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+func toDBRoute(r opcua.Route) dbRoute {
+	return dbRoute{
+		ID:        r.ID,
+		Owner:     r.Owner,
+		ServerURI: r.ServerURI,
+		NodeID:    r.NodeID,
+		ThingID:   r.ThingID,
+		ChannelID: r.ChannelID,
+		Interval:  int64(r.Interval / time.Millisecond),
+		CreatedAt: r.Created.UTC(),
+	}
+}
+
+func toRoute(dbr dbRoute) opcua.Route {
+	return opcua.Route{
+		ID:        dbr.ID,
+		Owner:     dbr.Owner,
+		ServerURI: dbr.ServerURI,
+		NodeID:    dbr.NodeID,
+		ThingID:   dbr.ThingID,
+		ChannelID: dbr.ChannelID,
+		Interval:  time.Duration(dbr.Interval) * time.Millisecond,
+		Created:   dbr.CreatedAt.UTC(),
+	}
+}
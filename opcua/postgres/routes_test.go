@@ -0,0 +1,248 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/opcua"
+	"github.com/mainflux/mainflux/opcua/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	owner      = "user@example.com"
+	otherOwner = "other@example.com"
+	serverURI  = "opc.tcp://localhost:4840"
+	nodeID     = "ns=2;i=2"
+	otherNode  = "ns=2;s=Pressure"
+	thingID    = "123e4567-e89b-12d3-a456-000000000011"
+	chanID     = "123e4567-e89b-12d3-a456-000000000021"
+	otherChan  = "123e4567-e89b-12d3-a456-000000000022"
+	wrongID    = "123e4567-e89b-12d3-a456-000000000099"
+	invalid    = "invalid"
+)
+
+func newRoute(n int, owner, nodeID, chanID string) opcua.Route {
+	return opcua.Route{
+		ID:        fmt.Sprintf("123e4567-e89b-12d3-a456-%012d", n),
+		Owner:     owner,
+		ServerURI: serverURI,
+		NodeID:    nodeID,
+		ThingID:   thingID,
+		ChannelID: chanID,
+		Interval:  500 * time.Millisecond,
+		Created:   time.Now().UTC().Truncate(time.Microsecond),
+	}
+}
+
+func cleanup(t *testing.T) {
+	_, err := db.Exec("DELETE FROM routes")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+}
+
+func TestRouteSave(t *testing.T) {
+	defer cleanup(t)
+	repo := postgres.New(db)
+
+	invalidThing := newRoute(3, owner, otherNode, chanID)
+	invalidThing.ThingID = invalid
+
+	cases := []struct {
+		desc  string
+		route opcua.Route
+		err   error
+	}{
+		{
+			desc:  "save new route",
+			route: newRoute(1, owner, nodeID, chanID),
+			err:   nil,
+		},
+		{
+			desc:  "save route with existing ID",
+			route: newRoute(1, owner, otherNode, chanID),
+			err:   opcua.ErrConflict,
+		},
+		{
+			desc:  "save route of node already routed to the channel",
+			route: newRoute(2, otherOwner, nodeID, chanID),
+			err:   opcua.ErrConflict,
+		},
+		{
+			desc:  "save route with invalid thing ID",
+			route: invalidThing,
+			err:   opcua.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		err := repo.Save(context.Background(), tc.route)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestRouteRetrieveByID(t *testing.T) {
+	defer cleanup(t)
+	repo := postgres.New(db)
+
+	r := newRoute(1, owner, nodeID, chanID)
+	err := repo.Save(context.Background(), r)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc  string
+		owner string
+		id    string
+		route opcua.Route
+		err   error
+	}{
+		{
+			desc:  "retrieve existing route",
+			owner: owner,
+			id:    r.ID,
+			route: r,
+			err:   nil,
+		},
+		{
+			desc:  "retrieve route of other owner",
+			owner: otherOwner,
+			id:    r.ID,
+			err:   opcua.ErrNotFound,
+		},
+		{
+			desc:  "retrieve non-existing route",
+			owner: owner,
+			id:    wrongID,
+			err:   opcua.ErrNotFound,
+		},
+		{
+			desc:  "retrieve route with invalid ID",
+			owner: owner,
+			id:    invalid,
+			err:   opcua.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		route, err := repo.RetrieveByID(context.Background(), tc.owner, tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.route, route, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.route, route))
+	}
+}
+
+func TestRouteRetrieveAll(t *testing.T) {
+	defer cleanup(t)
+	repo := postgres.New(db)
+
+	routes := []opcua.Route{
+		newRoute(1, owner, nodeID, chanID),
+		newRoute(2, owner, otherNode, chanID),
+		newRoute(3, otherOwner, nodeID, otherChan),
+	}
+	for _, r := range routes {
+		err := repo.Save(context.Background(), r)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	}
+
+	ownerCases := []struct {
+		desc   string
+		owner  string
+		offset uint64
+		limit  uint64
+		total  uint64
+		routes []opcua.Route
+	}{
+		{
+			desc:   "retrieve all routes of owner",
+			owner:  owner,
+			offset: 0,
+			limit:  10,
+			total:  2,
+			routes: routes[:2],
+		},
+		{
+			desc:   "retrieve routes of owner with offset",
+			owner:  owner,
+			offset: 1,
+			limit:  10,
+			total:  2,
+			routes: routes[1:2],
+		},
+		{
+			desc:   "retrieve routes of owner without routes",
+			owner:  invalid,
+			offset: 0,
+			limit:  10,
+			total:  0,
+			routes: []opcua.Route{},
+		},
+	}
+
+	for _, tc := range ownerCases {
+		page, err := repo.RetrieveByOwner(context.Background(), tc.owner, tc.offset, tc.limit)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s\n", tc.desc, err))
+		assert.Equal(t, tc.total, page.Total, fmt.Sprintf("%s: expected total %d got %d\n", tc.desc, tc.total, page.Total))
+		assert.Equal(t, tc.routes, page.Routes, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.routes, page.Routes))
+	}
+
+	nodeRoutes, err := repo.RetrieveByNode(context.Background(), serverURI, nodeID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.Equal(t, []opcua.Route{routes[0], routes[2]}, nodeRoutes, fmt.Sprintf("expected routes of node %s got %v\n", nodeID, nodeRoutes))
+
+	all, err := repo.RetrieveAll(context.Background())
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.Equal(t, routes, all, fmt.Sprintf("expected %v got %v\n", routes, all))
+}
+
+func TestRouteRemove(t *testing.T) {
+	defer cleanup(t)
+	repo := postgres.New(db)
+
+	r := newRoute(1, owner, nodeID, chanID)
+	err := repo.Save(context.Background(), r)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc    string
+		owner   string
+		id      string
+		removed bool
+	}{
+		{
+			desc:    "remove route of other owner",
+			owner:   otherOwner,
+			id:      r.ID,
+			removed: false,
+		},
+		{
+			desc:    "remove route with invalid ID",
+			owner:   owner,
+			id:      invalid,
+			removed: false,
+		},
+		{
+			desc:    "remove existing route",
+			owner:   owner,
+			id:      r.ID,
+			removed: true,
+		},
+		{
+			desc:    "remove removed route",
+			owner:   owner,
+			id:      r.ID,
+			removed: true,
+		},
+	}
+
+	for _, tc := range cases {
+		err := repo.Remove(context.Background(), tc.owner, tc.id)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s\n", tc.desc, err))
+		_, err = repo.RetrieveByID(context.Background(), owner, r.ID)
+		assert.Equal(t, tc.removed, err == opcua.ErrNotFound, fmt.Sprintf("%s: expected removed %t got error %v\n", tc.desc, tc.removed, err))
+	}
+}
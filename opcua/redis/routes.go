@@ -0,0 +1,62 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-redis/redis"
+	"github.com/mainflux/mainflux/opcua"
+)
+
+const routesPrefix = "opcua:routes"
+
+var _ opcua.RouteCache = (*routeCache)(nil)
+
+type routeCache struct {
+	client redis.UniversalClient
+}
+
+// NewRouteCache returns redis route cache implementation. Routes of the node
+// are stored as a single JSON encoded value, so that they're retrieved at
+// once for every value reported by the server.
+func NewRouteCache(client redis.UniversalClient) opcua.RouteCache {
+	return &routeCache{client: client}
+}
+
+func (rc *routeCache) Save(_ context.Context, serverURI, nodeID string, routes []opcua.Route) error {
+	data, err := json.Marshal(routes)
+	if err != nil {
+		return err
+	}
+
+	return rc.client.Set(key(serverURI, nodeID), data, 0).Err()
+}
+
+func (rc *routeCache) Retrieve(_ context.Context, serverURI, nodeID string) ([]opcua.Route, error) {
+	data, err := rc.client.Get(key(serverURI, nodeID)).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, opcua.ErrNotFound
+		}
+		return nil, err
+	}
+
+	var routes []opcua.Route
+	if err := json.Unmarshal(data, &routes); err != nil {
+		return nil, err
+	}
+
+	return routes, nil
+}
+
+func (rc *routeCache) Remove(_ context.Context, serverURI, nodeID string) error {
+	return rc.client.Del(key(serverURI, nodeID)).Err()
+}
+
+func key(serverURI, nodeID string) string {
+	return fmt.Sprintf("%s:%s:%s", routesPrefix, serverURI, nodeID)
+}
@@ -0,0 +1,126 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package redis_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/opcua"
+	"github.com/mainflux/mainflux/opcua/redis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	serverURI = "opc.tcp://localhost:4840"
+	nodeID    = "ns=2;i=2"
+	otherNode = "ns=2;s=Pressure"
+)
+
+var route = opcua.Route{
+	ID:        "123e4567-e89b-12d3-a456-000000000001",
+	Owner:     "user@example.com",
+	ServerURI: serverURI,
+	NodeID:    nodeID,
+	ThingID:   "123e4567-e89b-12d3-a456-000000000011",
+	ChannelID: "123e4567-e89b-12d3-a456-000000000021",
+	Interval:  time.Second,
+	Created:   time.Now().UTC().Truncate(time.Microsecond),
+}
+
+func TestRouteCacheSave(t *testing.T) {
+	cache := redis.NewRouteCache(redisClient)
+
+	cases := []struct {
+		desc   string
+		routes []opcua.Route
+	}{
+		{
+			desc:   "save routes of node",
+			routes: []opcua.Route{route},
+		},
+		{
+			desc:   "overwrite routes of node",
+			routes: []opcua.Route{route, route},
+		},
+		{
+			desc:   "save node without routes",
+			routes: []opcua.Route{},
+		},
+	}
+
+	for _, tc := range cases {
+		err := cache.Save(context.Background(), serverURI, nodeID, tc.routes)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s\n", tc.desc, err))
+
+		routes, err := cache.Retrieve(context.Background(), serverURI, nodeID)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s\n", tc.desc, err))
+		assert.Equal(t, tc.routes, routes, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.routes, routes))
+	}
+}
+
+func TestRouteCacheRetrieve(t *testing.T) {
+	cache := redis.NewRouteCache(redisClient)
+
+	err := cache.Save(context.Background(), serverURI, nodeID, []opcua.Route{route})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc   string
+		nodeID string
+		routes []opcua.Route
+		err    error
+	}{
+		{
+			desc:   "retrieve routes of cached node",
+			nodeID: nodeID,
+			routes: []opcua.Route{route},
+			err:    nil,
+		},
+		{
+			desc:   "retrieve routes of node that isn't cached",
+			nodeID: otherNode,
+			routes: nil,
+			err:    opcua.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		routes, err := cache.Retrieve(context.Background(), serverURI, tc.nodeID)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.routes, routes, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.routes, routes))
+	}
+}
+
+func TestRouteCacheRemove(t *testing.T) {
+	cache := redis.NewRouteCache(redisClient)
+
+	err := cache.Save(context.Background(), serverURI, nodeID, []opcua.Route{route})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc   string
+		nodeID string
+	}{
+		{
+			desc:   "remove cached node",
+			nodeID: nodeID,
+		},
+		{
+			desc:   "remove node that isn't cached",
+			nodeID: otherNode,
+		},
+	}
+
+	for _, tc := range cases {
+		err := cache.Remove(context.Background(), serverURI, tc.nodeID)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s\n", tc.desc, err))
+
+		_, err = cache.Retrieve(context.Background(), serverURI, tc.nodeID)
+		assert.Equal(t, opcua.ErrNotFound, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, opcua.ErrNotFound, err))
+	}
+}
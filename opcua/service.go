@@ -0,0 +1,354 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package opcua
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cisco/senml"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/things"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	protocol    = "opcua"
+	contentType = "application/senml+json"
+
+	// defInterval is the sampling interval of the monitored node, unless
+	// the route specifies otherwise.
+	defInterval = time.Second
+)
+
+var (
+	// ErrMalformedEntity indicates malformed entity specification.
+	ErrMalformedEntity = errors.New("malformed entity specification")
+
+	// ErrUnauthorizedAccess indicates missing or invalid credentials provided
+	// when accessing a protected resource.
+	ErrUnauthorizedAccess = errors.New("missing or invalid credentials provided")
+
+	// ErrNotFound indicates a non-existent entity request.
+	ErrNotFound = errors.New("non-existent entity")
+
+	// ErrConflict indicates that the node is already routed to the channel.
+	ErrConflict = errors.New("entity already exists")
+
+	// ErrUnreachable indicates that the OPC-UA server can't be reached.
+	ErrUnreachable = errors.New("OPC-UA server unreachable")
+)
+
+// Service specifies an API that must be fulfilled by the domain service
+// implementation, and all of its decorators (e.g. logging & metrics).
+type Service interface {
+	// Browse retrieves the nodes referenced by the provided node of the
+	// OPC-UA server, on behalf of the user identified by the provided key.
+	Browse(context.Context, string, string, string) ([]Node, error)
+
+	// CreateRoute routes the values of the server node to the channel, and
+	// starts monitoring the node. The user identified by the provided key
+	// has to own the thing, which has to be connected to the channel.
+	CreateRoute(context.Context, string, Route) (Route, error)
+
+	// ViewRoute retrieves the route having the provided identifier, that is
+	// owned by the user identified by the provided key.
+	ViewRoute(context.Context, string, string) (Route, error)
+
+	// ListRoutes retrieves the subset of the routes owned by the user
+	// identified by the provided key.
+	ListRoutes(context.Context, string, uint64, uint64) (RoutesPage, error)
+
+	// RemoveRoute removes the route having the provided identifier, that is
+	// owned by the user identified by the provided key. Node is no longer
+	// monitored once its last route is removed.
+	RemoveRoute(context.Context, string, string) error
+
+	// Restore starts monitoring the nodes of all the persisted routes. The
+	// first error encountered is returned.
+	Restore(context.Context) error
+}
+
+var _ Service = (*opcuaService)(nil)
+
+type opcuaService struct {
+	users      mainflux.UsersServiceClient
+	things     mainflux.ThingsServiceClient
+	repo       RouteRepository
+	cache      RouteCache
+	browser    Browser
+	subscriber Subscriber
+	publisher  mainflux.MessagePublisher
+	idp        IdentityProvider
+}
+
+// New instantiates the OPC-UA adapter service implementation.
+func New(users mainflux.UsersServiceClient, things mainflux.ThingsServiceClient, repo RouteRepository, cache RouteCache, browser Browser, subscriber Subscriber, publisher mainflux.MessagePublisher, idp IdentityProvider) Service {
+	return &opcuaService{
+		users:      users,
+		things:     things,
+		repo:       repo,
+		cache:      cache,
+		browser:    browser,
+		subscriber: subscriber,
+		publisher:  publisher,
+		idp:        idp,
+	}
+}
+
+func (as *opcuaService) Browse(ctx context.Context, token, serverURI, nodeID string) ([]Node, error) {
+	if _, err := as.identify(ctx, token); err != nil {
+		return nil, err
+	}
+
+	return as.browser.Browse(ctx, serverURI, nodeID)
+}
+
+func (as *opcuaService) CreateRoute(ctx context.Context, token string, r Route) (Route, error) {
+	owner, err := as.identify(ctx, token)
+	if err != nil {
+		return Route{}, err
+	}
+
+	if err := as.authorize(ctx, owner, r.ThingID, r.ChannelID); err != nil {
+		return Route{}, err
+	}
+
+	id, err := as.idp.ID()
+	if err != nil {
+		return Route{}, err
+	}
+
+	r.ID = id
+	r.Owner = owner
+	r.Created = time.Now().UTC()
+	if r.Interval == 0 {
+		r.Interval = defInterval
+	}
+
+	if err := as.repo.Save(ctx, r); err != nil {
+		return Route{}, err
+	}
+
+	// Cached routes of the node are dropped, so that the values are
+	// published to the new route as well.
+	if err := as.cache.Remove(ctx, r.ServerURI, r.NodeID); err != nil {
+		return Route{}, err
+	}
+
+	if err := as.subscriber.Subscribe(ctx, r.ServerURI, r.NodeID, r.Interval, as.publish); err != nil {
+		as.repo.Remove(ctx, owner, r.ID)
+		return Route{}, err
+	}
+
+	return r, nil
+}
+
+func (as *opcuaService) ViewRoute(ctx context.Context, token, id string) (Route, error) {
+	owner, err := as.identify(ctx, token)
+	if err != nil {
+		return Route{}, err
+	}
+
+	return as.repo.RetrieveByID(ctx, owner, id)
+}
+
+func (as *opcuaService) ListRoutes(ctx context.Context, token string, offset, limit uint64) (RoutesPage, error) {
+	owner, err := as.identify(ctx, token)
+	if err != nil {
+		return RoutesPage{}, err
+	}
+
+	return as.repo.RetrieveByOwner(ctx, owner, offset, limit)
+}
+
+func (as *opcuaService) RemoveRoute(ctx context.Context, token, id string) error {
+	owner, err := as.identify(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	r, err := as.repo.RetrieveByID(ctx, owner, id)
+	if err != nil {
+		return err
+	}
+
+	if err := as.repo.Remove(ctx, owner, id); err != nil {
+		return err
+	}
+
+	if err := as.cache.Remove(ctx, r.ServerURI, r.NodeID); err != nil {
+		return err
+	}
+
+	routes, err := as.repo.RetrieveByNode(ctx, r.ServerURI, r.NodeID)
+	if err != nil || len(routes) > 0 {
+		return err
+	}
+
+	return as.subscriber.Unsubscribe(ctx, r.ServerURI, r.NodeID)
+}
+
+func (as *opcuaService) Restore(ctx context.Context) error {
+	routes, err := as.repo.RetrieveAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Unreachable servers don't prevent monitoring the nodes of the
+	// remaining ones.
+	var failed error
+	for _, r := range routes {
+		if err := as.subscriber.Subscribe(ctx, r.ServerURI, r.NodeID, r.Interval, as.publish); err != nil && failed == nil {
+			failed = err
+		}
+	}
+
+	return failed
+}
+
+// publish publishes the value to the channels the node is routed to.
+func (as *opcuaService) publish(v Value) {
+	ctx := context.Background()
+
+	routes, err := as.routes(ctx, v.ServerURI, v.NodeID)
+	if err != nil {
+		return
+	}
+
+	payload, err := encode(v)
+	if err != nil {
+		return
+	}
+
+	for _, r := range routes {
+		msg := mainflux.RawMessage{
+			Channel:     r.ChannelID,
+			Publisher:   r.ThingID,
+			Protocol:    protocol,
+			ContentType: contentType,
+			Payload:     payload,
+		}
+		as.publisher.Publish(ctx, "", msg)
+	}
+}
+
+// routes retrieves the routes of the node from cache, falling back to the
+// repository on cache miss.
+func (as *opcuaService) routes(ctx context.Context, serverURI, nodeID string) ([]Route, error) {
+	if routes, err := as.cache.Retrieve(ctx, serverURI, nodeID); err == nil {
+		return routes, nil
+	}
+
+	routes, err := as.repo.RetrieveByNode(ctx, serverURI, nodeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := as.cache.Save(ctx, serverURI, nodeID, routes); err != nil {
+		return nil, err
+	}
+
+	return routes, nil
+}
+
+func (as *opcuaService) identify(ctx context.Context, token string) (string, error) {
+	res, err := as.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return "", ErrUnauthorizedAccess
+	}
+
+	return res.GetValue(), nil
+}
+
+// authorize checks that the user owns the thing, and that the thing is
+// allowed to publish to the channel.
+func (as *opcuaService) authorize(ctx context.Context, owner, thingID, chanID string) error {
+	res, err := as.things.Owner(ctx, &mainflux.ThingID{Value: thingID})
+	if err != nil {
+		return toAuthError(err)
+	}
+
+	if res.GetValue() != owner {
+		return ErrNotFound
+	}
+
+	req := &mainflux.AccessByIDReq{ThingID: thingID, ChanID: chanID, Action: things.Publish}
+	if _, err := as.things.CanAccessByID(ctx, req); err != nil {
+		return toAuthError(err)
+	}
+
+	return nil
+}
+
+func toAuthError(err error) error {
+	switch status.Code(err) {
+	case codes.NotFound:
+		return ErrNotFound
+	case codes.PermissionDenied:
+		return ErrUnauthorizedAccess
+	default:
+		return err
+	}
+}
+
+// encode converts the value to the SenML JSON record named after the node.
+func encode(v Value) ([]byte, error) {
+	rec := senml.SenMLRecord{
+		Name: v.NodeID,
+		Time: float64(v.Time.UnixNano()) / 1e9,
+	}
+
+	switch val := v.Value.(type) {
+	case bool:
+		rec.BoolValue = &val
+	case string:
+		rec.StringValue = val
+	case []byte:
+		rec.DataValue = base64.StdEncoding.EncodeToString(val)
+	case time.Time:
+		rec.StringValue = val.UTC().Format(time.RFC3339Nano)
+	default:
+		f, ok := toFloat(val)
+		if !ok {
+			rec.StringValue = fmt.Sprint(val)
+			break
+		}
+		rec.Value = &f
+	}
+
+	s := senml.SenML{Records: []senml.SenMLRecord{rec}}
+	return senml.Encode(s, senml.JSON, senml.OutputOptions{})
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch val := v.(type) {
+	case float64:
+		return val, true
+	case float32:
+		return float64(val), true
+	case int8:
+		return float64(val), true
+	case int16:
+		return float64(val), true
+	case int32:
+		return float64(val), true
+	case int64:
+		return float64(val), true
+	case uint8:
+		return float64(val), true
+	case uint16:
+		return float64(val), true
+	case uint32:
+		return float64(val), true
+	case uint64:
+		return float64(val), true
+	default:
+		return 0, false
+	}
+}
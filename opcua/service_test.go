@@ -0,0 +1,478 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package opcua_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/opcua"
+	"github.com/mainflux/mainflux/opcua/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	email      = "user@example.com"
+	otherEmail = "other@example.com"
+	token      = "token"
+	otherToken = "other-token"
+	wrongValue = "wrong-value"
+	thingID    = "thing"
+	otherThing = "other-thing"
+	chanID     = "channel"
+	otherChan  = "other-channel"
+	serverURI  = "opc.tcp://localhost:4840"
+	otherURI   = "opc.tcp://localhost:4841"
+	nodeID     = "ns=2;i=2"
+	otherNode  = "ns=2;s=Pressure"
+)
+
+var nodes = []opcua.Node{
+	{NodeID: nodeID, BrowseName: "Temperature", DisplayName: "Temperature", Class: "Variable", DataType: "Double"},
+	{NodeID: otherNode, BrowseName: "Pressure", DisplayName: "Pressure", Class: "Variable", DataType: "Int32"},
+}
+
+func newService() (opcua.Service, *mocks.Servers, *mocks.RouteCache, *mocks.Publisher) {
+	users := mocks.NewUsersService(map[string]string{token: email, otherToken: otherEmail})
+	things := mocks.NewThingsClient(
+		map[string]string{thingID: email, otherThing: otherEmail},
+		map[string][]string{thingID: {chanID}, otherThing: {otherChan}},
+	)
+	servers := mocks.NewServers(map[string][]opcua.Node{serverURI: nodes})
+	cache := mocks.NewRouteCache()
+	pub := mocks.NewPublisher()
+	svc := opcua.New(users, things, mocks.NewRouteRepository(), cache, servers, servers, pub, mocks.NewIdentityProvider())
+
+	return svc, servers, cache, pub
+}
+
+func newRoute() opcua.Route {
+	return opcua.Route{
+		ServerURI: serverURI,
+		NodeID:    nodeID,
+		ThingID:   thingID,
+		ChannelID: chanID,
+		Interval:  500 * time.Millisecond,
+	}
+}
+
+func TestBrowse(t *testing.T) {
+	svc, _, _, _ := newService()
+
+	cases := []struct {
+		desc      string
+		token     string
+		serverURI string
+		nodeID    string
+		nodes     []opcua.Node
+		err       error
+	}{
+		{
+			desc:      "browse server root",
+			token:     token,
+			serverURI: serverURI,
+			nodes:     nodes,
+			err:       nil,
+		},
+		{
+			desc:      "browse server node",
+			token:     token,
+			serverURI: serverURI,
+			nodeID:    nodeID,
+			nodes:     nodes,
+			err:       nil,
+		},
+		{
+			desc:      "browse non-existing node",
+			token:     token,
+			serverURI: serverURI,
+			nodeID:    "ns=2;i=100",
+			err:       opcua.ErrNotFound,
+		},
+		{
+			desc:      "browse unreachable server",
+			token:     token,
+			serverURI: otherURI,
+			err:       opcua.ErrUnreachable,
+		},
+		{
+			desc:      "browse with invalid token",
+			token:     wrongValue,
+			serverURI: serverURI,
+			err:       opcua.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		nodes, err := svc.Browse(context.Background(), tc.token, tc.serverURI, tc.nodeID)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.nodes, nodes, fmt.Sprintf("%s: expected nodes %v got %v\n", tc.desc, tc.nodes, nodes))
+	}
+}
+
+func TestCreateRoute(t *testing.T) {
+	svc, servers, _, _ := newService()
+
+	defaultRoute := newRoute()
+	defaultRoute.NodeID = otherNode
+	defaultRoute.Interval = 0
+
+	unreachable := newRoute()
+	unreachable.ServerURI = otherURI
+
+	notOwned := newRoute()
+	notOwned.ThingID = otherThing
+
+	notConnected := newRoute()
+	notConnected.ChannelID = otherChan
+
+	nonExisting := newRoute()
+	nonExisting.ThingID = wrongValue
+
+	cases := []struct {
+		desc     string
+		token    string
+		route    opcua.Route
+		interval time.Duration
+		err      error
+	}{
+		{
+			desc:     "create route",
+			token:    token,
+			route:    newRoute(),
+			interval: 500 * time.Millisecond,
+			err:      nil,
+		},
+		{
+			desc:     "create route with default interval",
+			token:    token,
+			route:    defaultRoute,
+			interval: time.Second,
+			err:      nil,
+		},
+		{
+			desc:  "create existing route",
+			token: token,
+			route: newRoute(),
+			err:   opcua.ErrConflict,
+		},
+		{
+			desc:  "create route of unreachable server",
+			token: token,
+			route: unreachable,
+			err:   opcua.ErrUnreachable,
+		},
+		{
+			desc:  "create route of thing owned by other user",
+			token: token,
+			route: notOwned,
+			err:   opcua.ErrNotFound,
+		},
+		{
+			desc:  "create route to channel thing isn't connected to",
+			token: token,
+			route: notConnected,
+			err:   opcua.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "create route of non-existing thing",
+			token: token,
+			route: nonExisting,
+			err:   opcua.ErrNotFound,
+		},
+		{
+			desc:  "create route with invalid token",
+			token: wrongValue,
+			route: newRoute(),
+			err:   opcua.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		r, err := svc.CreateRoute(context.Background(), tc.token, tc.route)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err != nil {
+			continue
+		}
+
+		assert.NotEmpty(t, r.ID, fmt.Sprintf("%s: expected route ID", tc.desc))
+		assert.Equal(t, email, r.Owner, fmt.Sprintf("%s: expected owner %s got %s\n", tc.desc, email, r.Owner))
+		assert.Equal(t, tc.interval, r.Interval, fmt.Sprintf("%s: expected interval %s got %s\n", tc.desc, tc.interval, r.Interval))
+
+		interval, ok := servers.Monitored(r.ServerURI, r.NodeID)
+		assert.True(t, ok, fmt.Sprintf("%s: expected node %s to be monitored", tc.desc, r.NodeID))
+		assert.Equal(t, tc.interval, interval, fmt.Sprintf("%s: expected sampling interval %s got %s\n", tc.desc, tc.interval, interval))
+	}
+
+	page, err := svc.ListRoutes(context.Background(), token, 0, 10)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.Equal(t, uint64(2), page.Total, fmt.Sprintf("expected 2 routes got %d\n", page.Total))
+}
+
+func TestViewRoute(t *testing.T) {
+	svc, _, _, _ := newService()
+
+	r, err := svc.CreateRoute(context.Background(), token, newRoute())
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc  string
+		token string
+		id    string
+		route opcua.Route
+		err   error
+	}{
+		{
+			desc:  "view route",
+			token: token,
+			id:    r.ID,
+			route: r,
+			err:   nil,
+		},
+		{
+			desc:  "view route of other user",
+			token: otherToken,
+			id:    r.ID,
+			err:   opcua.ErrNotFound,
+		},
+		{
+			desc:  "view non-existing route",
+			token: token,
+			id:    wrongValue,
+			err:   opcua.ErrNotFound,
+		},
+		{
+			desc:  "view route with invalid token",
+			token: wrongValue,
+			id:    r.ID,
+			err:   opcua.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		route, err := svc.ViewRoute(context.Background(), tc.token, tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.route, route, fmt.Sprintf("%s: expected route %v got %v\n", tc.desc, tc.route, route))
+	}
+}
+
+func TestListRoutes(t *testing.T) {
+	svc, _, _, _ := newService()
+
+	for _, n := range nodes {
+		r := newRoute()
+		r.NodeID = n.NodeID
+		_, err := svc.CreateRoute(context.Background(), token, r)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	}
+
+	cases := []struct {
+		desc   string
+		token  string
+		offset uint64
+		limit  uint64
+		total  uint64
+		size   int
+		err    error
+	}{
+		{
+			desc:   "list all routes",
+			token:  token,
+			offset: 0,
+			limit:  10,
+			total:  2,
+			size:   2,
+			err:    nil,
+		},
+		{
+			desc:   "list routes with offset",
+			token:  token,
+			offset: 1,
+			limit:  10,
+			total:  2,
+			size:   1,
+			err:    nil,
+		},
+		{
+			desc:   "list routes of other user",
+			token:  otherToken,
+			offset: 0,
+			limit:  10,
+			total:  0,
+			size:   0,
+			err:    nil,
+		},
+		{
+			desc:   "list routes with invalid token",
+			token:  wrongValue,
+			offset: 0,
+			limit:  10,
+			err:    opcua.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		page, err := svc.ListRoutes(context.Background(), tc.token, tc.offset, tc.limit)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.total, page.Total, fmt.Sprintf("%s: expected total %d got %d\n", tc.desc, tc.total, page.Total))
+		assert.Len(t, page.Routes, tc.size, fmt.Sprintf("%s: expected %d routes got %d\n", tc.desc, tc.size, len(page.Routes)))
+	}
+}
+
+func TestRemoveRoute(t *testing.T) {
+	svc, servers, _, _ := newService()
+
+	r, err := svc.CreateRoute(context.Background(), token, newRoute())
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	other := newRoute()
+	other.ThingID = otherThing
+	other.ChannelID = otherChan
+	o, err := svc.CreateRoute(context.Background(), otherToken, other)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc      string
+		token     string
+		id        string
+		monitored bool
+		err       error
+	}{
+		{
+			desc:      "remove route with invalid token",
+			token:     wrongValue,
+			id:        r.ID,
+			monitored: true,
+			err:       opcua.ErrUnauthorizedAccess,
+		},
+		{
+			desc:      "remove route of other user",
+			token:     otherToken,
+			id:        r.ID,
+			monitored: true,
+			err:       opcua.ErrNotFound,
+		},
+		{
+			desc:      "remove route of node routed elsewhere",
+			token:     token,
+			id:        r.ID,
+			monitored: true,
+			err:       nil,
+		},
+		{
+			desc:      "remove removed route",
+			token:     token,
+			id:        r.ID,
+			monitored: true,
+			err:       opcua.ErrNotFound,
+		},
+		{
+			desc:      "remove last route of node",
+			token:     otherToken,
+			id:        o.ID,
+			monitored: false,
+			err:       nil,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.RemoveRoute(context.Background(), tc.token, tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		_, ok := servers.Monitored(serverURI, nodeID)
+		assert.Equal(t, tc.monitored, ok, fmt.Sprintf("%s: expected node monitored %t got %t\n", tc.desc, tc.monitored, ok))
+	}
+}
+
+func TestPublish(t *testing.T) {
+	svc, servers, cache, pub := newService()
+
+	r, err := svc.CreateRoute(context.Background(), token, newRoute())
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	other := newRoute()
+	other.ThingID = otherThing
+	other.ChannelID = otherChan
+	_, err = svc.CreateRoute(context.Background(), otherToken, other)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	ts := time.Unix(1600000000, 500000000)
+	cases := []struct {
+		desc    string
+		value   interface{}
+		payload string
+	}{
+		{
+			desc:    "publish float value",
+			value:   21.5,
+			payload: `[{"n":"ns=2;i=2","t":1600000000.5,"v":21.5}]`,
+		},
+		{
+			desc:    "publish integer value",
+			value:   int32(42),
+			payload: `[{"n":"ns=2;i=2","t":1600000000.5,"v":42}]`,
+		},
+		{
+			desc:    "publish bool value",
+			value:   true,
+			payload: `[{"n":"ns=2;i=2","t":1600000000.5,"vb":true}]`,
+		},
+		{
+			desc:    "publish string value",
+			value:   "running",
+			payload: `[{"n":"ns=2;i=2","t":1600000000.5,"vs":"running"}]`,
+		},
+		{
+			desc:    "publish byte string value",
+			value:   []byte{1, 2, 3},
+			payload: `[{"n":"ns=2;i=2","t":1600000000.5,"vd":"AQID"}]`,
+		},
+	}
+
+	for i, tc := range cases {
+		servers.Report(opcua.Value{ServerURI: serverURI, NodeID: nodeID, Value: tc.value, Time: ts})
+
+		msgs := pub.Messages()
+		require.Len(t, msgs, 2*(i+1), fmt.Sprintf("%s: expected %d messages got %d\n", tc.desc, 2*(i+1), len(msgs)))
+		for j, ch := range []string{chanID, otherChan} {
+			msg := msgs[2*i+j]
+			assert.Equal(t, ch, msg.Channel, fmt.Sprintf("%s: expected channel %s got %s\n", tc.desc, ch, msg.Channel))
+			assert.Equal(t, "opcua", msg.Protocol, fmt.Sprintf("%s: expected protocol opcua got %s\n", tc.desc, msg.Protocol))
+			assert.Equal(t, "application/senml+json", msg.ContentType, fmt.Sprintf("%s: expected SenML content type got %s\n", tc.desc, msg.ContentType))
+			assert.JSONEq(t, tc.payload, string(msg.Payload), fmt.Sprintf("%s: expected payload %s got %s\n", tc.desc, tc.payload, msg.Payload))
+		}
+	}
+	assert.Equal(t, 1, cache.Misses(), fmt.Sprintf("expected single cache miss got %d\n", cache.Misses()))
+
+	err = svc.RemoveRoute(context.Background(), token, r.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	servers.Report(opcua.Value{ServerURI: serverURI, NodeID: nodeID, Value: 21.5, Time: ts})
+	msgs := pub.Messages()
+	require.Len(t, msgs, 2*len(cases)+1, fmt.Sprintf("expected %d messages got %d\n", 2*len(cases)+1, len(msgs)))
+	assert.Equal(t, otherChan, msgs[len(msgs)-1].Channel, fmt.Sprintf("expected channel %s got %s\n", otherChan, msgs[len(msgs)-1].Channel))
+}
+
+func TestRestore(t *testing.T) {
+	users := mocks.NewUsersService(map[string]string{token: email})
+	things := mocks.NewThingsClient(map[string]string{thingID: email}, map[string][]string{thingID: {chanID}})
+	repo := mocks.NewRouteRepository()
+	servers := mocks.NewServers(map[string][]opcua.Node{serverURI: nodes})
+	svc := opcua.New(users, things, repo, mocks.NewRouteCache(), servers, servers, mocks.NewPublisher(), mocks.NewIdentityProvider())
+
+	_, err := svc.CreateRoute(context.Background(), token, newRoute())
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	restarted := mocks.NewServers(map[string][]opcua.Node{serverURI: nodes})
+	svc = opcua.New(users, things, repo, mocks.NewRouteCache(), restarted, restarted, mocks.NewPublisher(), mocks.NewIdentityProvider())
+
+	err = svc.Restore(context.Background())
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	interval, ok := restarted.Monitored(serverURI, nodeID)
+	assert.True(t, ok, "expected node to be monitored after restore")
+	assert.Equal(t, 500*time.Millisecond, interval, fmt.Sprintf("expected sampling interval %s got %s\n", 500*time.Millisecond, interval))
+}
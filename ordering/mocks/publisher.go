@@ -0,0 +1,42 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mainflux/mainflux"
+)
+
+var _ mainflux.MessagePublisher = (*Publisher)(nil)
+
+// Publisher is the mock message publisher that records the published
+// messages.
+type Publisher struct {
+	mu       sync.Mutex
+	messages []mainflux.RawMessage
+}
+
+// NewPublisher returns mock message publisher.
+func NewPublisher() *Publisher {
+	return &Publisher{}
+}
+
+// Publish records the message.
+func (p *Publisher) Publish(_ context.Context, _ string, msg mainflux.RawMessage) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.messages = append(p.messages, msg)
+	return nil
+}
+
+// Messages returns the recorded messages.
+func (p *Publisher) Messages() []mainflux.RawMessage {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.messages
+}
@@ -0,0 +1,40 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mainflux/mainflux/ordering"
+)
+
+var _ ordering.Sequencer = (*sequencerMock)(nil)
+
+type sequencerMock struct {
+	mu   sync.Mutex
+	err  error
+	seqs map[string]uint64
+}
+
+// NewSequencer returns in-memory message sequencer. If set, the given error
+// is returned instead of the sequence numbers.
+func NewSequencer(err error) ordering.Sequencer {
+	return &sequencerMock{
+		err:  err,
+		seqs: make(map[string]uint64),
+	}
+}
+
+func (sm *sequencerMock) Next(_ context.Context, channel string) (uint64, error) {
+	if sm.err != nil {
+		return 0, sm.err
+	}
+
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	sm.seqs[channel]++
+	return sm.seqs[channel], nil
+}
@@ -0,0 +1,100 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/mainflux/mainflux"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var _ mainflux.ThingsServiceClient = (*ThingsClient)(nil)
+
+// ThingsClient is the mock things service client that serves the ordering of
+// the channels.
+type ThingsClient struct {
+	mu       sync.Mutex
+	channels map[string]bool
+	lookups  int
+}
+
+// NewThingsClient returns mock things service client that knows the ordering
+// of the given channels.
+func NewThingsClient(channels map[string]bool) *ThingsClient {
+	return &ThingsClient{channels: channels}
+}
+
+// Lookups returns the number of the ordering lookups made.
+func (tc *ThingsClient) Lookups() int {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	return tc.lookups
+}
+
+func (tc *ThingsClient) CanAccess(context.Context, *mainflux.AccessReq, ...grpc.CallOption) (*mainflux.ThingID, error) {
+	panic("not implemented")
+}
+
+func (tc *ThingsClient) CanAccessByID(context.Context, *mainflux.AccessByIDReq, ...grpc.CallOption) (*empty.Empty, error) {
+	panic("not implemented")
+}
+
+func (tc *ThingsClient) Identify(context.Context, *mainflux.Token, ...grpc.CallOption) (*mainflux.ThingID, error) {
+	panic("not implemented")
+}
+
+func (tc *ThingsClient) Owner(context.Context, *mainflux.ThingID, ...grpc.CallOption) (*mainflux.UserID, error) {
+	panic("not implemented")
+}
+
+func (tc *ThingsClient) Retention(context.Context, *mainflux.ChannelID, ...grpc.CallOption) (*mainflux.Retention, error) {
+	panic("not implemented")
+}
+
+func (tc *ThingsClient) Region(context.Context, *mainflux.ChannelID, ...grpc.CallOption) (*mainflux.Region, error) {
+	panic("not implemented")
+}
+
+func (tc *ThingsClient) Alias(context.Context, *mainflux.ChannelID, ...grpc.CallOption) (*mainflux.ChannelAlias, error) {
+	panic("not implemented")
+}
+
+func (tc *ThingsClient) ResolveAlias(context.Context, *mainflux.ChannelAlias, ...grpc.CallOption) (*mainflux.ChannelID, error) {
+	panic("not implemented")
+}
+
+func (tc *ThingsClient) Ordering(_ context.Context, req *mainflux.ChannelID, _ ...grpc.CallOption) (*mainflux.Ordering, error) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	tc.lookups++
+	ordered, ok := tc.channels[req.GetValue()]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "channel not found")
+	}
+
+	return &mainflux.Ordering{Ordered: ordered}, nil
+}
+
+func (tc *ThingsClient) Transformer(context.Context, *mainflux.ChannelID, ...grpc.CallOption) (*mainflux.Transformer, error) {
+	panic("not implemented")
+}
+
+func (tc *ThingsClient) PayloadSchema(context.Context, *mainflux.PayloadSchemaReq, ...grpc.CallOption) (*mainflux.PayloadSchema, error) {
+	panic("not implemented")
+}
+
+func (tc *ThingsClient) KeyDigest(context.Context, *mainflux.ThingID, ...grpc.CallOption) (*mainflux.KeyDigest, error) {
+	panic("not implemented")
+}
+
+func (tc *ThingsClient) Changes(context.Context, *mainflux.ChangesReq, ...grpc.CallOption) (mainflux.ThingsService_ChangesClient, error) {
+	panic("not implemented")
+}
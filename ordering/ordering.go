@@ -0,0 +1,101 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package ordering contains the publisher decorator used by the adapters to
+// sequence the messages of the channels with ordered delivery enabled. Each
+// message published to such channel is assigned the next number of the
+// channel sequence shared between all adapter instances, so that consumers
+// can detect the gaps and reordered messages.
+package ordering
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	log "github.com/mainflux/mainflux/logger"
+)
+
+// Sequencer specifies an API for generating the per-channel message
+// sequence numbers.
+type Sequencer interface {
+	// Next returns the next sequence number of the channel. Sequence
+	// numbers start at 1, as zero marks unsequenced messages.
+	Next(context.Context, string) (uint64, error)
+}
+
+var _ mainflux.MessagePublisher = (*publisher)(nil)
+
+type cachedOrdering struct {
+	ordered bool
+	checked time.Time
+}
+
+type publisher struct {
+	pub      mainflux.MessagePublisher
+	things   mainflux.ThingsServiceClient
+	seq      Sequencer
+	refresh  time.Duration
+	logger   log.Logger
+	mu       sync.RWMutex
+	channels map[string]cachedOrdering
+}
+
+// New returns message publisher that sequences the messages of the ordered
+// channels before passing them to the given publisher. The channels ordering
+// is looked up in the things service at most once per refresh interval.
+func New(pub mainflux.MessagePublisher, things mainflux.ThingsServiceClient, seq Sequencer, refresh time.Duration, logger log.Logger) mainflux.MessagePublisher {
+	return &publisher{
+		pub:      pub,
+		things:   things,
+		seq:      seq,
+		refresh:  refresh,
+		logger:   logger,
+		channels: make(map[string]cachedOrdering),
+	}
+}
+
+func (p *publisher) Publish(ctx context.Context, token string, msg mainflux.RawMessage) error {
+	if !p.ordered(msg.Channel) {
+		return p.pub.Publish(ctx, token, msg)
+	}
+
+	// Ordered channel messages are rejected rather than published without
+	// the sequence number, as the consumers rely on it.
+	seq, err := p.seq.Next(ctx, msg.Channel)
+	if err != nil {
+		return err
+	}
+	msg.Sequence = seq
+
+	return p.pub.Publish(ctx, token, msg)
+}
+
+// ordered reports whether the channel has ordered delivery enabled. If the
+// lookup fails, the last known value is used, and the channels of the unknown
+// ordering are treated as unordered.
+func (p *publisher) ordered(channel string) bool {
+	p.mu.RLock()
+	cached, ok := p.channels[channel]
+	p.mu.RUnlock()
+	if ok && time.Since(cached.checked) < p.refresh {
+		return cached.ordered
+	}
+
+	cached.checked = time.Now()
+
+	res, err := p.things.Ordering(context.Background(), &mainflux.ChannelID{Value: channel})
+	if err != nil {
+		p.logger.Warn(fmt.Sprintf("Failed to retrieve ordering of channel %s: %s", channel, err))
+	} else {
+		cached.ordered = res.GetOrdered()
+	}
+
+	p.mu.Lock()
+	p.channels[channel] = cached
+	p.mu.Unlock()
+
+	return cached.ordered
+}
@@ -0,0 +1,102 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package ordering_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/ordering"
+	"github.com/mainflux/mainflux/ordering/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+const token = "token"
+
+var errSequencer = errors.New("sequencer unavailable")
+
+func newPublisher(things mainflux.ThingsServiceClient, pub mainflux.MessagePublisher, seq ordering.Sequencer) mainflux.MessagePublisher {
+	logger, _ := logger.New(os.Stdout, logger.Info.String())
+	return ordering.New(pub, things, seq, time.Minute, logger)
+}
+
+func TestPublish(t *testing.T) {
+	things := mocks.NewThingsClient(map[string]bool{
+		"ordered":   true,
+		"unordered": false,
+	})
+	pub := mocks.NewPublisher()
+	svc := newPublisher(things, pub, mocks.NewSequencer(nil))
+
+	cases := []struct {
+		desc    string
+		channel string
+		seq     uint64
+	}{
+		{
+			desc:    "publish first message to ordered channel",
+			channel: "ordered",
+			seq:     1,
+		},
+		{
+			desc:    "publish second message to ordered channel",
+			channel: "ordered",
+			seq:     2,
+		},
+		{
+			desc:    "publish to unordered channel",
+			channel: "unordered",
+			seq:     0,
+		},
+		{
+			desc:    "publish to non-existent channel",
+			channel: "non-existent",
+			seq:     0,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.Publish(context.Background(), token, mainflux.RawMessage{Channel: tc.channel})
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		msgs := pub.Messages()
+		seq := msgs[len(msgs)-1].Sequence
+		assert.Equal(t, tc.seq, seq, fmt.Sprintf("%s: expected sequence %d got %d\n", tc.desc, tc.seq, seq))
+	}
+}
+
+func TestPublishSequencerFailure(t *testing.T) {
+	things := mocks.NewThingsClient(map[string]bool{
+		"ordered":   true,
+		"unordered": false,
+	})
+	pub := mocks.NewPublisher()
+	svc := newPublisher(things, pub, mocks.NewSequencer(errSequencer))
+
+	err := svc.Publish(context.Background(), token, mainflux.RawMessage{Channel: "ordered"})
+	assert.Equal(t, errSequencer, err, fmt.Sprintf("publish to ordered channel: expected %s got %s\n", errSequencer, err))
+	assert.Equal(t, 0, len(pub.Messages()), "publish to ordered channel: expected no message published")
+
+	err = svc.Publish(context.Background(), token, mainflux.RawMessage{Channel: "unordered"})
+	assert.Nil(t, err, fmt.Sprintf("publish to unordered channel: unexpected error %s", err))
+	assert.Equal(t, 1, len(pub.Messages()), "publish to unordered channel: expected message published")
+}
+
+func TestOrderingCache(t *testing.T) {
+	things := mocks.NewThingsClient(map[string]bool{"ordered": true})
+	pub := mocks.NewPublisher()
+	svc := newPublisher(things, pub, mocks.NewSequencer(nil))
+
+	for i := 0; i < 3; i++ {
+		err := svc.Publish(context.Background(), token, mainflux.RawMessage{Channel: "ordered"})
+		assert.Nil(t, err, fmt.Sprintf("publish to ordered channel: unexpected error %s", err))
+	}
+	assert.Equal(t, 1, things.Lookups(), fmt.Sprintf("expected 1 ordering lookup got %d", things.Lookups()))
+	assert.Equal(t, 3, len(pub.Messages()), fmt.Sprintf("expected 3 published messages got %d", len(pub.Messages())))
+}
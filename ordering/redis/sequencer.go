@@ -0,0 +1,40 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package redis
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-redis/redis"
+	"github.com/mainflux/mainflux/ordering"
+)
+
+const seqPrefix = "sequence:channel"
+
+var _ ordering.Sequencer = (*sequencer)(nil)
+
+type sequencer struct {
+	client redis.UniversalClient
+}
+
+// NewSequencer returns redis message sequencer implementation. Sequences are
+// kept in counters incremented atomically, so concurrent adapters never
+// assign the same sequence number twice.
+func NewSequencer(client redis.UniversalClient) ordering.Sequencer {
+	return &sequencer{client: client}
+}
+
+func (s *sequencer) Next(_ context.Context, channel string) (uint64, error) {
+	seq, err := s.client.Incr(seqKey(channel)).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(seq), nil
+}
+
+func seqKey(channel string) string {
+	return fmt.Sprintf("%s:%s", seqPrefix, channel)
+}
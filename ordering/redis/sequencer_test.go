@@ -0,0 +1,59 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package redis_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/mainflux/mainflux/ordering/redis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNext(t *testing.T) {
+	redisClient.FlushAll()
+	seq := redis.NewSequencer(redisClient)
+
+	for i := uint64(1); i <= 3; i++ {
+		n, err := seq.Next(context.Background(), "channel")
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		assert.Equal(t, i, n, fmt.Sprintf("expected sequence %d got %d", i, n))
+	}
+
+	n, err := seq.Next(context.Background(), "other")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, uint64(1), n, fmt.Sprintf("other channel: expected sequence 1 got %d", n))
+}
+
+func TestNextConcurrent(t *testing.T) {
+	redisClient.FlushAll()
+	seq := redis.NewSequencer(redisClient)
+
+	const total = 50
+	var mu sync.Mutex
+	seen := make(map[uint64]bool)
+
+	var wg sync.WaitGroup
+	for i := 0; i < total; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			n, err := seq.Next(context.Background(), "channel")
+			assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+			mu.Lock()
+			seen[n] = true
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, total, len(seen), fmt.Sprintf("expected %d distinct sequence numbers got %d", total, len(seen)))
+	for i := uint64(1); i <= total; i++ {
+		assert.True(t, seen[i], fmt.Sprintf("expected sequence %d to be assigned", i))
+	}
+}
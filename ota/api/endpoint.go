@@ -0,0 +1,256 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/mainflux/mainflux/ota"
+)
+
+func uploadFirmwareEndpoint(svc ota.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(uploadFirmwareReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		fw := ota.Firmware{
+			Name:        req.name,
+			Version:     req.version,
+			ContentType: req.contentType,
+		}
+
+		saved, err := svc.UploadFirmware(ctx, req.token, fw, req.image)
+		if err != nil {
+			return nil, err
+		}
+
+		return createRes{location: firmwareLocation(saved.ID)}, nil
+	}
+}
+
+func viewFirmwareEndpoint(svc ota.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		fw, err := svc.ViewFirmware(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		return toFirmwareRes(fw), nil
+	}
+}
+
+func listFirmwareEndpoint(svc ota.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		page, err := svc.ListFirmware(ctx, req.token, req.offset, req.limit)
+		if err != nil {
+			return nil, err
+		}
+
+		res := firmwarePageRes{
+			pageRes: pageRes{
+				Total:  page.Total,
+				Offset: page.Offset,
+				Limit:  page.Limit,
+			},
+			Firmware: []firmwareRes{},
+		}
+		for _, fw := range page.Firmware {
+			res.Firmware = append(res.Firmware, toFirmwareRes(fw))
+		}
+
+		return res, nil
+	}
+}
+
+func removeFirmwareEndpoint(svc ota.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RemoveFirmware(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
+func createCampaignEndpoint(svc ota.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(createCampaignReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		c := ota.Campaign{
+			Name:     req.Name,
+			Firmware: req.Firmware,
+			Group:    req.Group,
+			Channel:  req.Channel,
+		}
+
+		saved, err := svc.CreateCampaign(ctx, req.token, c)
+		if err != nil {
+			return nil, err
+		}
+
+		return createRes{location: campaignLocation(saved.ID)}, nil
+	}
+}
+
+func viewCampaignEndpoint(svc ota.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		c, err := svc.ViewCampaign(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		return toCampaignRes(c), nil
+	}
+}
+
+func listCampaignsEndpoint(svc ota.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		page, err := svc.ListCampaigns(ctx, req.token, req.offset, req.limit)
+		if err != nil {
+			return nil, err
+		}
+
+		res := campaignsPageRes{
+			pageRes: pageRes{
+				Total:  page.Total,
+				Offset: page.Offset,
+				Limit:  page.Limit,
+			},
+			Campaigns: []campaignRes{},
+		}
+		for _, c := range page.Campaigns {
+			res.Campaigns = append(res.Campaigns, toCampaignRes(c))
+		}
+
+		return res, nil
+	}
+}
+
+func startCampaignEndpoint(svc ota.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.StartCampaign(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+
+		return stateRes{}, nil
+	}
+}
+
+func cancelCampaignEndpoint(svc ota.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.CancelCampaign(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+
+		return stateRes{}, nil
+	}
+}
+
+func listUpdatesEndpoint(svc ota.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		page, err := svc.ListUpdates(ctx, req.token, req.id, req.offset, req.limit)
+		if err != nil {
+			return nil, err
+		}
+
+		res := updatesPageRes{
+			pageRes: pageRes{
+				Total:  page.Total,
+				Offset: page.Offset,
+				Limit:  page.Limit,
+			},
+			Updates: []updateRes{},
+		}
+		for _, u := range page.Updates {
+			res.Updates = append(res.Updates, updateRes{
+				Thing:   u.Thing,
+				Status:  u.Status,
+				Message: u.Message,
+				Updated: u.Updated,
+			})
+		}
+
+		return res, nil
+	}
+}
+
+func toFirmwareRes(fw ota.Firmware) firmwareRes {
+	return firmwareRes{
+		ID:          fw.ID,
+		Name:        fw.Name,
+		Version:     fw.Version,
+		ContentType: fw.ContentType,
+		Size:        fw.Size,
+		Checksum:    fw.Checksum,
+		Created:     fw.Created,
+	}
+}
+
+func toCampaignRes(c ota.Campaign) campaignRes {
+	return campaignRes{
+		ID:       c.ID,
+		Name:     c.Name,
+		Firmware: c.Firmware,
+		Group:    c.Group,
+		Channel:  c.Channel,
+		State:    c.State,
+		Created:  c.Created,
+	}
+}
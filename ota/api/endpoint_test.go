@@ -0,0 +1,296 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/ota"
+	"github.com/mainflux/mainflux/ota/api"
+	"github.com/mainflux/mainflux/ota/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	token       = "token"
+	wrongValue  = "wrong-value"
+	email       = "user@example.com"
+	chanID      = "1"
+	contentType = "application/json"
+	maxSize     = 16
+)
+
+type testRequest struct {
+	client      *http.Client
+	method      string
+	url         string
+	contentType string
+	token       string
+	body        io.Reader
+}
+
+func (tr testRequest) make() (*http.Response, error) {
+	req, err := http.NewRequest(tr.method, tr.url, tr.body)
+	if err != nil {
+		return nil, err
+	}
+
+	if tr.token != "" {
+		req.Header.Set("Authorization", tr.token)
+	}
+
+	if tr.contentType != "" {
+		req.Header.Set("Content-Type", tr.contentType)
+	}
+
+	return tr.client.Do(req)
+}
+
+func newServer() *httptest.Server {
+	users := mocks.NewUsersService(map[string]string{token: email})
+	things := mocks.NewThings(map[string]string{chanID: token}, map[string][]string{chanID: {"thing"}})
+	svc := ota.New(users, things, mocks.NewFirmwareRepository(), mocks.NewCampaignRepository(), mocks.NewStorage(), mocks.NewPublisher(), mocks.NewIdentityProvider(), time.Hour)
+
+	return httptest.NewServer(api.MakeHandler(svc, maxSize))
+}
+
+func multipartBody(fields map[string]string, image []byte) (io.Reader, string) {
+	buf := &bytes.Buffer{}
+	w := multipart.NewWriter(buf)
+	for k, v := range fields {
+		w.WriteField(k, v)
+	}
+	if image != nil {
+		fw, _ := w.CreateFormFile("file", "firmware.bin")
+		fw.Write(image)
+	}
+	w.Close()
+
+	return buf, w.FormDataContentType()
+}
+
+func upload(t *testing.T, ts *httptest.Server, version string) string {
+	body, ct := multipartBody(map[string]string{"name": "sensor", "version": version}, []byte("image"))
+	req := testRequest{
+		client:      ts.Client(),
+		method:      http.MethodPost,
+		url:         fmt.Sprintf("%s/firmware", ts.URL),
+		contentType: ct,
+		token:       token,
+		body:        body,
+	}
+	res, err := req.make()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	require.Equal(t, http.StatusCreated, res.StatusCode, "failed to upload firmware")
+
+	return strings.TrimPrefix(res.Header.Get("Location"), "/firmware/")
+}
+
+func TestUploadFirmware(t *testing.T) {
+	ts := newServer()
+	defer ts.Close()
+
+	valid := map[string]string{"name": "sensor", "version": "1.0.0"}
+
+	cases := []struct {
+		desc     string
+		fields   map[string]string
+		image    []byte
+		ct       string
+		token    string
+		status   int
+		location bool
+	}{
+		{
+			desc:     "upload firmware",
+			fields:   valid,
+			image:    []byte("image"),
+			token:    token,
+			status:   http.StatusCreated,
+			location: true,
+		},
+		{
+			desc:   "upload firmware with existing version",
+			fields: valid,
+			image:  []byte("image"),
+			token:  token,
+			status: http.StatusConflict,
+		},
+		{
+			desc:   "upload firmware exceeding size limit",
+			fields: map[string]string{"name": "sensor", "version": "2.0.0"},
+			image:  bytes.Repeat([]byte("x"), maxSize+1),
+			token:  token,
+			status: http.StatusRequestEntityTooLarge,
+		},
+		{
+			desc:   "upload firmware without version",
+			fields: map[string]string{"name": "sensor"},
+			image:  []byte("image"),
+			token:  token,
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "upload firmware without image",
+			fields: map[string]string{"name": "sensor", "version": "3.0.0"},
+			token:  token,
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "upload firmware with invalid content type",
+			fields: valid,
+			image:  []byte("image"),
+			ct:     contentType,
+			token:  token,
+			status: http.StatusUnsupportedMediaType,
+		},
+		{
+			desc:   "upload firmware with invalid credentials",
+			fields: map[string]string{"name": "sensor", "version": "4.0.0"},
+			image:  []byte("image"),
+			token:  wrongValue,
+			status: http.StatusForbidden,
+		},
+		{
+			desc:   "upload firmware without credentials",
+			fields: map[string]string{"name": "sensor", "version": "4.0.0"},
+			image:  []byte("image"),
+			token:  "",
+			status: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range cases {
+		body, ct := multipartBody(tc.fields, tc.image)
+		if tc.ct != "" {
+			ct = tc.ct
+		}
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/firmware", ts.URL),
+			contentType: ct,
+			token:       tc.token,
+			body:        body,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		location := res.Header.Get("Location")
+		assert.Equal(t, tc.location, strings.HasPrefix(location, "/firmware/"), fmt.Sprintf("%s: unexpected location %s", tc.desc, location))
+	}
+}
+
+func TestCampaigns(t *testing.T) {
+	ts := newServer()
+	defer ts.Close()
+
+	fwID := upload(t, ts, "1.0.0")
+	body := fmt.Sprintf(`{"firmware":"%s","channel":"%s"}`, fwID, chanID)
+	req := testRequest{
+		client:      ts.Client(),
+		method:      http.MethodPost,
+		url:         fmt.Sprintf("%s/campaigns", ts.URL),
+		contentType: contentType,
+		token:       token,
+		body:        strings.NewReader(body),
+	}
+	res, err := req.make()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	require.Equal(t, http.StatusCreated, res.StatusCode, "failed to create campaign")
+	location := res.Header.Get("Location")
+
+	cases := []struct {
+		desc   string
+		method string
+		url    string
+		token  string
+		status int
+	}{
+		{
+			desc:   "remove firmware rolled out by campaign",
+			method: http.MethodDelete,
+			url:    fmt.Sprintf("/firmware/%s", fwID),
+			token:  token,
+			status: http.StatusConflict,
+		},
+		{
+			desc:   "view campaign",
+			method: http.MethodGet,
+			url:    location,
+			token:  token,
+			status: http.StatusOK,
+		},
+		{
+			desc:   "start campaign",
+			method: http.MethodPost,
+			url:    fmt.Sprintf("%s/start", location),
+			token:  token,
+			status: http.StatusOK,
+		},
+		{
+			desc:   "start running campaign",
+			method: http.MethodPost,
+			url:    fmt.Sprintf("%s/start", location),
+			token:  token,
+			status: http.StatusConflict,
+		},
+		{
+			desc:   "list campaign updates",
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/updates?offset=0&limit=10", location),
+			token:  token,
+			status: http.StatusOK,
+		},
+		{
+			desc:   "list campaign updates with invalid limit",
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/updates?limit=1000", location),
+			token:  token,
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "cancel campaign with invalid credentials",
+			method: http.MethodPost,
+			url:    fmt.Sprintf("%s/cancel", location),
+			token:  wrongValue,
+			status: http.StatusForbidden,
+		},
+		{
+			desc:   "cancel campaign",
+			method: http.MethodPost,
+			url:    fmt.Sprintf("%s/cancel", location),
+			token:  token,
+			status: http.StatusOK,
+		},
+		{
+			desc:   "view non-existing campaign",
+			method: http.MethodGet,
+			url:    fmt.Sprintf("/campaigns/%s", wrongValue),
+			token:  token,
+			status: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: tc.method,
+			url:    fmt.Sprintf("%s%s", ts.URL, tc.url),
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
@@ -0,0 +1,171 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// +build !test
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	log "github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/ota"
+)
+
+var _ ota.Service = (*loggingMiddleware)(nil)
+
+type loggingMiddleware struct {
+	logger log.Logger
+	svc    ota.Service
+}
+
+// LoggingMiddleware adds logging facilities to the core service.
+func LoggingMiddleware(svc ota.Service, logger log.Logger) ota.Service {
+	return &loggingMiddleware{logger, svc}
+}
+
+func (lm *loggingMiddleware) UploadFirmware(ctx context.Context, token string, fw ota.Firmware, image []byte) (saved ota.Firmware, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method upload_firmware for token %s and firmware %s took %s to complete", token, saved.ID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.UploadFirmware(ctx, token, fw, image)
+}
+
+func (lm *loggingMiddleware) ViewFirmware(ctx context.Context, token, id string) (fw ota.Firmware, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method view_firmware for token %s and firmware %s took %s to complete", token, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ViewFirmware(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) ListFirmware(ctx context.Context, token string, offset, limit uint64) (page ota.FirmwarePage, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_firmware for token %s took %s to complete", token, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListFirmware(ctx, token, offset, limit)
+}
+
+func (lm *loggingMiddleware) RemoveFirmware(ctx context.Context, token, id string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method remove_firmware for token %s and firmware %s took %s to complete", token, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RemoveFirmware(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) CreateCampaign(ctx context.Context, token string, c ota.Campaign) (saved ota.Campaign, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method create_campaign for token %s and campaign %s took %s to complete", token, saved.ID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.CreateCampaign(ctx, token, c)
+}
+
+func (lm *loggingMiddleware) ViewCampaign(ctx context.Context, token, id string) (c ota.Campaign, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method view_campaign for token %s and campaign %s took %s to complete", token, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ViewCampaign(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) ListCampaigns(ctx context.Context, token string, offset, limit uint64) (page ota.CampaignsPage, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_campaigns for token %s took %s to complete", token, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListCampaigns(ctx, token, offset, limit)
+}
+
+func (lm *loggingMiddleware) StartCampaign(ctx context.Context, token, id string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method start_campaign for token %s and campaign %s took %s to complete", token, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.StartCampaign(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) CancelCampaign(ctx context.Context, token, id string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method cancel_campaign for token %s and campaign %s took %s to complete", token, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.CancelCampaign(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) ListUpdates(ctx context.Context, token, id string, offset, limit uint64) (page ota.UpdatesPage, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_updates for token %s and campaign %s took %s to complete", token, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListUpdates(ctx, token, id, offset, limit)
+}
+
+func (lm *loggingMiddleware) ReportStatus(ctx context.Context, msg mainflux.RawMessage) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method report_status for thing %s took %s to complete", msg.Publisher, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ReportStatus(ctx, msg)
+}
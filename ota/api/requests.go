@@ -0,0 +1,88 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import "github.com/mainflux/mainflux/ota"
+
+const maxLimitSize = 100
+
+type apiReq interface {
+	validate() error
+}
+
+type uploadFirmwareReq struct {
+	token       string
+	name        string
+	version     string
+	contentType string
+	image       []byte
+}
+
+func (req uploadFirmwareReq) validate() error {
+	if req.token == "" {
+		return ota.ErrUnauthorizedAccess
+	}
+
+	if req.name == "" || req.version == "" || len(req.image) == 0 {
+		return ota.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type createCampaignReq struct {
+	token    string
+	Name     string `json:"name,omitempty"`
+	Firmware string `json:"firmware"`
+	Group    string `json:"group,omitempty"`
+	Channel  string `json:"channel"`
+}
+
+func (req createCampaignReq) validate() error {
+	if req.token == "" {
+		return ota.ErrUnauthorizedAccess
+	}
+
+	if req.Firmware == "" || req.Channel == "" {
+		return ota.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type viewReq struct {
+	token string
+	id    string
+}
+
+func (req viewReq) validate() error {
+	if req.token == "" {
+		return ota.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" {
+		return ota.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type listReq struct {
+	token  string
+	id     string
+	offset uint64
+	limit  uint64
+}
+
+func (req listReq) validate() error {
+	if req.token == "" {
+		return ota.ErrUnauthorizedAccess
+	}
+
+	if req.limit == 0 || req.limit > maxLimitSize {
+		return ota.ErrMalformedEntity
+	}
+
+	return nil
+}
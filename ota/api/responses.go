@@ -0,0 +1,185 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/mainflux/mainflux"
+)
+
+var (
+	_ mainflux.Response = (*createRes)(nil)
+	_ mainflux.Response = (*firmwareRes)(nil)
+	_ mainflux.Response = (*firmwarePageRes)(nil)
+	_ mainflux.Response = (*campaignRes)(nil)
+	_ mainflux.Response = (*campaignsPageRes)(nil)
+	_ mainflux.Response = (*updatesPageRes)(nil)
+	_ mainflux.Response = (*stateRes)(nil)
+	_ mainflux.Response = (*removeRes)(nil)
+)
+
+type createRes struct {
+	location string
+}
+
+func (res createRes) Code() int {
+	return http.StatusCreated
+}
+
+func (res createRes) Headers() map[string]string {
+	return map[string]string{
+		"Location": res.location,
+	}
+}
+
+func (res createRes) Empty() bool {
+	return true
+}
+
+type firmwareRes struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Version     string    `json:"version"`
+	ContentType string    `json:"content_type"`
+	Size        uint64    `json:"size"`
+	Checksum    string    `json:"checksum"`
+	Created     time.Time `json:"created"`
+}
+
+func (res firmwareRes) Code() int {
+	return http.StatusOK
+}
+
+func (res firmwareRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res firmwareRes) Empty() bool {
+	return false
+}
+
+type pageRes struct {
+	Total  uint64 `json:"total"`
+	Offset uint64 `json:"offset"`
+	Limit  uint64 `json:"limit"`
+}
+
+type firmwarePageRes struct {
+	pageRes
+	Firmware []firmwareRes `json:"firmware"`
+}
+
+func (res firmwarePageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res firmwarePageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res firmwarePageRes) Empty() bool {
+	return false
+}
+
+type campaignRes struct {
+	ID       string    `json:"id"`
+	Name     string    `json:"name,omitempty"`
+	Firmware string    `json:"firmware"`
+	Group    string    `json:"group"`
+	Channel  string    `json:"channel"`
+	State    string    `json:"state"`
+	Created  time.Time `json:"created"`
+}
+
+func (res campaignRes) Code() int {
+	return http.StatusOK
+}
+
+func (res campaignRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res campaignRes) Empty() bool {
+	return false
+}
+
+type campaignsPageRes struct {
+	pageRes
+	Campaigns []campaignRes `json:"campaigns"`
+}
+
+func (res campaignsPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res campaignsPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res campaignsPageRes) Empty() bool {
+	return false
+}
+
+type updateRes struct {
+	Thing   string    `json:"thing"`
+	Status  string    `json:"status"`
+	Message string    `json:"message,omitempty"`
+	Updated time.Time `json:"updated"`
+}
+
+type updatesPageRes struct {
+	pageRes
+	Updates []updateRes `json:"updates"`
+}
+
+func (res updatesPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res updatesPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res updatesPageRes) Empty() bool {
+	return false
+}
+
+type stateRes struct{}
+
+func (res stateRes) Code() int {
+	return http.StatusOK
+}
+
+func (res stateRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res stateRes) Empty() bool {
+	return true
+}
+
+type removeRes struct{}
+
+func (res removeRes) Code() int {
+	return http.StatusNoContent
+}
+
+func (res removeRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res removeRes) Empty() bool {
+	return true
+}
+
+func firmwareLocation(id string) string {
+	return fmt.Sprintf("/firmware/%s", id)
+}
+
+func campaignLocation(id string) string {
+	return fmt.Sprintf("/campaigns/%s", id)
+}
@@ -0,0 +1,299 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/go-zoo/bone"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/ota"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	contentType   = "application/json"
+	multipartType = "multipart/form-data"
+	offset        = "offset"
+	limit         = "limit"
+
+	nameField    = "name"
+	versionField = "version"
+	fileField    = "file"
+	maxFieldSize = 256
+
+	defOffset = 0
+	defLimit  = 10
+)
+
+var (
+	errUnsupportedContentType = errors.New("unsupported content type")
+	errInvalidQueryParams     = errors.New("invalid query params")
+	errImageTooLarge          = errors.New("firmware image too large")
+)
+
+// MakeHandler returns a HTTP handler for API endpoints. Uploaded firmware
+// images larger than maxSize bytes are rejected.
+func MakeHandler(svc ota.Service, maxSize int64) http.Handler {
+	opts := []kithttp.ServerOption{
+		kithttp.ServerErrorEncoder(encodeError),
+	}
+
+	r := bone.New()
+
+	r.Post("/firmware", kithttp.NewServer(
+		uploadFirmwareEndpoint(svc),
+		decodeUpload(maxSize),
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/firmware/:id", kithttp.NewServer(
+		viewFirmwareEndpoint(svc),
+		decodeView,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Delete("/firmware/:id", kithttp.NewServer(
+		removeFirmwareEndpoint(svc),
+		decodeView,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/firmware", kithttp.NewServer(
+		listFirmwareEndpoint(svc),
+		decodeList,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Post("/campaigns", kithttp.NewServer(
+		createCampaignEndpoint(svc),
+		decodeCreateCampaign,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/campaigns/:id", kithttp.NewServer(
+		viewCampaignEndpoint(svc),
+		decodeView,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/campaigns", kithttp.NewServer(
+		listCampaignsEndpoint(svc),
+		decodeList,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Post("/campaigns/:id/start", kithttp.NewServer(
+		startCampaignEndpoint(svc),
+		decodeView,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Post("/campaigns/:id/cancel", kithttp.NewServer(
+		cancelCampaignEndpoint(svc),
+		decodeView,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/campaigns/:id/updates", kithttp.NewServer(
+		listUpdatesEndpoint(svc),
+		decodeList,
+		encodeResponse,
+		opts...,
+	))
+
+	r.GetFunc("/version", mainflux.Version("ota"))
+	r.GetFunc(mainflux.DiscoveryPath, mainflux.Discovery("ota", mainflux.Capabilities{
+		ContentTypes: []string{contentType, multipartType},
+		Limits:       mainflux.Limits{MaxPageSize: maxLimitSize, MaxPayload: maxSize},
+	}))
+	r.Handle("/metrics", promhttp.Handler())
+
+	return r
+}
+
+// decodeUpload reads the multipart form part by part, so that the image
+// doesn't have to be buffered more than once.
+func decodeUpload(maxSize int64) kithttp.DecodeRequestFunc {
+	return func(_ context.Context, r *http.Request) (interface{}, error) {
+		if !strings.Contains(r.Header.Get("Content-Type"), multipartType) {
+			return nil, errUnsupportedContentType
+		}
+
+		mr, err := r.MultipartReader()
+		if err != nil {
+			return nil, ota.ErrMalformedEntity
+		}
+
+		req := uploadFirmwareReq{token: r.Header.Get("Authorization")}
+		for {
+			part, err := mr.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, ota.ErrMalformedEntity
+			}
+
+			switch part.FormName() {
+			case nameField:
+				req.name, err = readField(part)
+			case versionField:
+				req.version, err = readField(part)
+			case fileField:
+				req.contentType = part.Header.Get("Content-Type")
+				req.image, err = ioutil.ReadAll(io.LimitReader(part, maxSize+1))
+				if err == nil && int64(len(req.image)) > maxSize {
+					err = errImageTooLarge
+				}
+			}
+			part.Close()
+
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return req, nil
+	}
+}
+
+func readField(r io.Reader) (string, error) {
+	val, err := ioutil.ReadAll(io.LimitReader(r, maxFieldSize+1))
+	if err != nil || len(val) > maxFieldSize {
+		return "", ota.ErrMalformedEntity
+	}
+
+	return strings.TrimSpace(string(val)), nil
+}
+
+func decodeCreateCampaign(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+
+	req := createCampaignReq{token: r.Header.Get("Authorization")}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func decodeView(_ context.Context, r *http.Request) (interface{}, error) {
+	req := viewReq{
+		token: r.Header.Get("Authorization"),
+		id:    bone.GetValue(r, "id"),
+	}
+
+	return req, nil
+}
+
+func decodeList(_ context.Context, r *http.Request) (interface{}, error) {
+	o, err := readUintQuery(r, offset, defOffset)
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := readUintQuery(r, limit, defLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	req := listReq{
+		token:  r.Header.Get("Authorization"),
+		id:     bone.GetValue(r, "id"),
+		offset: o,
+		limit:  l,
+	}
+
+	return req, nil
+}
+
+func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", contentType)
+
+	if ar, ok := response.(mainflux.Response); ok {
+		for k, v := range ar.Headers() {
+			w.Header().Set(k, v)
+		}
+
+		w.WriteHeader(ar.Code())
+
+		if ar.Empty() {
+			return nil
+		}
+	}
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+	w.Header().Set("Content-Type", contentType)
+
+	switch err {
+	case ota.ErrMalformedEntity:
+		w.WriteHeader(http.StatusBadRequest)
+	case ota.ErrUnauthorizedAccess:
+		w.WriteHeader(http.StatusForbidden)
+	case ota.ErrNotFound:
+		w.WriteHeader(http.StatusNotFound)
+	case ota.ErrConflict, ota.ErrInvalidState:
+		w.WriteHeader(http.StatusConflict)
+	case errImageTooLarge:
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+	case errUnsupportedContentType:
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+	case errInvalidQueryParams:
+		w.WriteHeader(http.StatusBadRequest)
+	case io.ErrUnexpectedEOF:
+		w.WriteHeader(http.StatusBadRequest)
+	case io.EOF:
+		w.WriteHeader(http.StatusBadRequest)
+	default:
+		switch err.(type) {
+		case *json.SyntaxError:
+			w.WriteHeader(http.StatusBadRequest)
+		case *json.UnmarshalTypeError:
+			w.WriteHeader(http.StatusBadRequest)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}
+}
+
+func readUintQuery(r *http.Request, key string, def uint64) (uint64, error) {
+	vals := bone.GetQuery(r, key)
+	if len(vals) > 1 {
+		return 0, errInvalidQueryParams
+	}
+
+	if len(vals) == 0 {
+		return def, nil
+	}
+
+	val, err := strconv.ParseUint(vals[0], 10, 64)
+	if err != nil {
+		return 0, errInvalidQueryParams
+	}
+
+	return val, nil
+}
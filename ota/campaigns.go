@@ -0,0 +1,145 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package ota
+
+import (
+	"context"
+	"time"
+)
+
+// Campaign states.
+const (
+	// StateCreated campaign is defined, but the update commands aren't
+	// sent yet.
+	StateCreated = "created"
+
+	// StateRunning campaign has sent the update commands to the things.
+	StateRunning = "running"
+
+	// StateCancelled campaign doesn't accept the status reports anymore.
+	StateCancelled = "cancelled"
+)
+
+// Update statuses. Pending status is set by the service once the update
+// command is sent, and the cancelled status once the campaign is cancelled.
+// The rest of the statuses are reported by the things.
+const (
+	StatusPending     = "pending"
+	StatusDownloading = "downloading"
+	StatusInstalling  = "installing"
+	StatusSucceeded   = "succeeded"
+	StatusFailed      = "failed"
+	StatusCancelled   = "cancelled"
+)
+
+var reported = map[string]bool{
+	StatusDownloading: true,
+	StatusInstalling:  true,
+	StatusSucceeded:   true,
+	StatusFailed:      true,
+}
+
+// Campaign represents the rollout of the firmware to the group of things.
+// The group is formed by the things connected to the group channel, and
+// only the ones connected to the control channel as well are targeted,
+// since the commands and the status reports are exchanged over it.
+type Campaign struct {
+	ID       string
+	Owner    string
+	Name     string
+	Firmware string
+	Group    string
+	Channel  string
+	State    string
+	Created  time.Time
+}
+
+// CampaignsPage contains page related metadata as well as list of campaigns
+// that belong to this page.
+type CampaignsPage struct {
+	Total     uint64
+	Offset    uint64
+	Limit     uint64
+	Campaigns []Campaign
+}
+
+// Update represents the status of the campaign update of a single thing.
+// Message is the optional status description reported by the thing, e.g.
+// the failure reason.
+type Update struct {
+	Campaign string
+	Thing    string
+	Status   string
+	Message  string
+	Updated  time.Time
+}
+
+// UpdatesPage contains page related metadata as well as list of updates
+// that belong to this page.
+type UpdatesPage struct {
+	Total   uint64
+	Offset  uint64
+	Limit   uint64
+	Updates []Update
+}
+
+// Done determines whether the update reached the final status.
+func (u Update) Done() bool {
+	switch u.Status {
+	case StatusSucceeded, StatusFailed, StatusCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+// CampaignRepository specifies a campaign persistence API.
+type CampaignRepository interface {
+	// Save persists the campaign.
+	Save(context.Context, Campaign) error
+
+	// RetrieveByID retrieves the campaign having the provided identifier.
+	RetrieveByID(context.Context, string) (Campaign, error)
+
+	// RetrieveByOwner retrieves the subset of campaigns owned by the
+	// specified user.
+	RetrieveByOwner(context.Context, string, uint64, uint64) (CampaignsPage, error)
+
+	// CountByFirmware returns the number of campaigns rolling out the
+	// firmware having the provided identifier.
+	CountByFirmware(context.Context, string) (uint64, error)
+
+	// UpdateState changes the state of the campaign having the provided
+	// identifier.
+	UpdateState(context.Context, string, string) error
+
+	// SaveUpdates persists the updates of the campaign things.
+	SaveUpdates(context.Context, []Update) error
+
+	// RetrieveUpdate retrieves the update of the thing within the campaign.
+	RetrieveUpdate(context.Context, string, string) (Update, error)
+
+	// RetrieveUpdates retrieves the subset of the campaign updates.
+	RetrieveUpdates(context.Context, string, uint64, uint64) (UpdatesPage, error)
+
+	// UpdateStatus changes the status and the message of the update.
+	UpdateStatus(context.Context, Update) error
+}
+
+// Things specifies an API for checking the channel ownership and resolving
+// the things connected to the channels.
+type Things interface {
+	// Authorize returns ErrNotFound if the channel doesn't exist or isn't
+	// owned by the user identified by the provided key.
+	Authorize(token, chanID string) error
+
+	// Connected returns the IDs of all the things connected to the channel.
+	Connected(token, chanID string) ([]string, error)
+}
+
+// IdentityProvider specifies an API for generating unique identifiers.
+type IdentityProvider interface {
+	// ID generates the unique identifier.
+	ID() (string, error)
+}
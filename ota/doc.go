@@ -0,0 +1,10 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package ota contains the domain concept definitions needed to support
+// Mainflux over-the-air update service functionality. OTA service stores the
+// firmware images in the object storage and rolls them out to the things in
+// campaigns. Update commands are delivered to the things over the campaign
+// control channel, and the things report the update status back over the
+// same channel.
+package ota
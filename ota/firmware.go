@@ -0,0 +1,66 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package ota
+
+import (
+	"context"
+	"time"
+)
+
+// Firmware represents the firmware image uploaded to the object storage.
+// Each firmware is owned by one user, and the name and the version are
+// unique among the user's firmware.
+type Firmware struct {
+	ID          string
+	Owner       string
+	Name        string
+	Version     string
+	ContentType string
+	Size        uint64
+
+	// Checksum is the hex encoded SHA-256 digest of the image, which the
+	// things use to verify the downloaded image.
+	Checksum string
+	Created  time.Time
+}
+
+// FirmwarePage contains page related metadata as well as list of firmware
+// that belong to this page.
+type FirmwarePage struct {
+	Total    uint64
+	Offset   uint64
+	Limit    uint64
+	Firmware []Firmware
+}
+
+// FirmwareRepository specifies a firmware persistence API.
+type FirmwareRepository interface {
+	// Save persists the firmware. ErrConflict is returned if the user
+	// already has the firmware with the same name and version.
+	Save(context.Context, Firmware) error
+
+	// RetrieveByID retrieves the firmware having the provided identifier.
+	RetrieveByID(context.Context, string) (Firmware, error)
+
+	// RetrieveByOwner retrieves the subset of firmware owned by the
+	// specified user.
+	RetrieveByOwner(context.Context, string, uint64, uint64) (FirmwarePage, error)
+
+	// Remove removes the firmware having the provided identifier.
+	Remove(context.Context, string) error
+}
+
+// Storage specifies an API for storing the firmware images in the object
+// storage, from which the things download them.
+type Storage interface {
+	// Put stores the object under the given key.
+	Put(key, contentType, contentEncoding string, body []byte) error
+
+	// Remove removes the object stored under the given key.
+	Remove(key string) error
+
+	// Presign returns the URL which allows the object stored under the
+	// given key to be downloaded without credentials until the TTL expires.
+	Presign(key string, ttl time.Duration) (string, error)
+}
@@ -0,0 +1,182 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/mainflux/mainflux/ota"
+)
+
+var _ ota.CampaignRepository = (*campaignRepositoryMock)(nil)
+
+type campaignRepositoryMock struct {
+	mu        sync.Mutex
+	campaigns map[string]ota.Campaign
+	updates   map[string]map[string]ota.Update
+}
+
+// NewCampaignRepository creates in-memory campaign repository.
+func NewCampaignRepository() ota.CampaignRepository {
+	return &campaignRepositoryMock{
+		campaigns: make(map[string]ota.Campaign),
+		updates:   make(map[string]map[string]ota.Update),
+	}
+}
+
+func (crm *campaignRepositoryMock) Save(_ context.Context, c ota.Campaign) error {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	crm.campaigns[c.ID] = c
+	return nil
+}
+
+func (crm *campaignRepositoryMock) RetrieveByID(_ context.Context, id string) (ota.Campaign, error) {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	c, ok := crm.campaigns[id]
+	if !ok {
+		return ota.Campaign{}, ota.ErrNotFound
+	}
+
+	return c, nil
+}
+
+func (crm *campaignRepositoryMock) RetrieveByOwner(_ context.Context, owner string, offset, limit uint64) (ota.CampaignsPage, error) {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	items := []ota.Campaign{}
+	for _, c := range crm.campaigns {
+		if c.Owner == owner {
+			items = append(items, c)
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].ID < items[j].ID
+	})
+
+	page := ota.CampaignsPage{
+		Total:     uint64(len(items)),
+		Offset:    offset,
+		Limit:     limit,
+		Campaigns: []ota.Campaign{},
+	}
+
+	if offset >= uint64(len(items)) {
+		return page, nil
+	}
+
+	end := offset + limit
+	if end > uint64(len(items)) {
+		end = uint64(len(items))
+	}
+	page.Campaigns = items[offset:end]
+
+	return page, nil
+}
+
+func (crm *campaignRepositoryMock) CountByFirmware(_ context.Context, id string) (uint64, error) {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	var n uint64
+	for _, c := range crm.campaigns {
+		if c.Firmware == id {
+			n++
+		}
+	}
+
+	return n, nil
+}
+
+func (crm *campaignRepositoryMock) UpdateState(_ context.Context, id, state string) error {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	c, ok := crm.campaigns[id]
+	if !ok {
+		return ota.ErrNotFound
+	}
+
+	c.State = state
+	crm.campaigns[id] = c
+	return nil
+}
+
+func (crm *campaignRepositoryMock) SaveUpdates(_ context.Context, updates []ota.Update) error {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	for _, u := range updates {
+		if _, ok := crm.updates[u.Campaign]; !ok {
+			crm.updates[u.Campaign] = make(map[string]ota.Update)
+		}
+		crm.updates[u.Campaign][u.Thing] = u
+	}
+
+	return nil
+}
+
+func (crm *campaignRepositoryMock) RetrieveUpdate(_ context.Context, campaign, thing string) (ota.Update, error) {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	u, ok := crm.updates[campaign][thing]
+	if !ok {
+		return ota.Update{}, ota.ErrNotFound
+	}
+
+	return u, nil
+}
+
+func (crm *campaignRepositoryMock) RetrieveUpdates(_ context.Context, campaign string, offset, limit uint64) (ota.UpdatesPage, error) {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	items := []ota.Update{}
+	for _, u := range crm.updates[campaign] {
+		items = append(items, u)
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Thing < items[j].Thing
+	})
+
+	page := ota.UpdatesPage{
+		Total:   uint64(len(items)),
+		Offset:  offset,
+		Limit:   limit,
+		Updates: []ota.Update{},
+	}
+
+	if offset >= uint64(len(items)) {
+		return page, nil
+	}
+
+	end := offset + limit
+	if end > uint64(len(items)) {
+		end = uint64(len(items))
+	}
+	page.Updates = items[offset:end]
+
+	return page, nil
+}
+
+func (crm *campaignRepositoryMock) UpdateStatus(_ context.Context, u ota.Update) error {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	if _, ok := crm.updates[u.Campaign][u.Thing]; !ok {
+		return ota.ErrNotFound
+	}
+
+	crm.updates[u.Campaign][u.Thing] = u
+	return nil
+}
@@ -0,0 +1,95 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/mainflux/mainflux/ota"
+)
+
+var _ ota.FirmwareRepository = (*firmwareRepositoryMock)(nil)
+
+type firmwareRepositoryMock struct {
+	mu       sync.Mutex
+	firmware map[string]ota.Firmware
+}
+
+// NewFirmwareRepository creates in-memory firmware repository.
+func NewFirmwareRepository() ota.FirmwareRepository {
+	return &firmwareRepositoryMock{
+		firmware: make(map[string]ota.Firmware),
+	}
+}
+
+func (frm *firmwareRepositoryMock) Save(_ context.Context, fw ota.Firmware) error {
+	frm.mu.Lock()
+	defer frm.mu.Unlock()
+
+	for _, f := range frm.firmware {
+		if f.Owner == fw.Owner && f.Name == fw.Name && f.Version == fw.Version {
+			return ota.ErrConflict
+		}
+	}
+
+	frm.firmware[fw.ID] = fw
+	return nil
+}
+
+func (frm *firmwareRepositoryMock) RetrieveByID(_ context.Context, id string) (ota.Firmware, error) {
+	frm.mu.Lock()
+	defer frm.mu.Unlock()
+
+	fw, ok := frm.firmware[id]
+	if !ok {
+		return ota.Firmware{}, ota.ErrNotFound
+	}
+
+	return fw, nil
+}
+
+func (frm *firmwareRepositoryMock) RetrieveByOwner(_ context.Context, owner string, offset, limit uint64) (ota.FirmwarePage, error) {
+	frm.mu.Lock()
+	defer frm.mu.Unlock()
+
+	items := []ota.Firmware{}
+	for _, fw := range frm.firmware {
+		if fw.Owner == owner {
+			items = append(items, fw)
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].ID < items[j].ID
+	})
+
+	page := ota.FirmwarePage{
+		Total:    uint64(len(items)),
+		Offset:   offset,
+		Limit:    limit,
+		Firmware: []ota.Firmware{},
+	}
+
+	if offset >= uint64(len(items)) {
+		return page, nil
+	}
+
+	end := offset + limit
+	if end > uint64(len(items)) {
+		end = uint64(len(items))
+	}
+	page.Firmware = items[offset:end]
+
+	return page, nil
+}
+
+func (frm *firmwareRepositoryMock) Remove(_ context.Context, id string) error {
+	frm.mu.Lock()
+	defer frm.mu.Unlock()
+
+	delete(frm.firmware, id)
+	return nil
+}
@@ -0,0 +1,56 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mainflux/mainflux"
+)
+
+var _ mainflux.MessagePublisher = (*Publisher)(nil)
+
+// Publisher is an in-memory publisher which records published messages.
+type Publisher struct {
+	mu  sync.Mutex
+	err error
+	out []mainflux.RawMessage
+}
+
+// NewPublisher returns publisher mock.
+func NewPublisher() *Publisher {
+	return &Publisher{}
+}
+
+// Publish records the published message, unless the publisher is set to
+// fail.
+func (p *Publisher) Publish(_ context.Context, _ string, msg mainflux.RawMessage) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.err != nil {
+		return p.err
+	}
+
+	p.out = append(p.out, msg)
+	return nil
+}
+
+// Fail sets the error returned by the subsequent publishing. Nil error
+// restores the publishing.
+func (p *Publisher) Fail(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.err = err
+}
+
+// Published returns all the messages published so far.
+func (p *Publisher) Published() []mainflux.RawMessage {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]mainflux.RawMessage{}, p.out...)
+}
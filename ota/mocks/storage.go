@@ -0,0 +1,57 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mainflux/mainflux/ota"
+)
+
+var _ ota.Storage = (*Storage)(nil)
+
+// Storage is an in-memory object storage.
+type Storage struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+// NewStorage returns object storage mock.
+func NewStorage() *Storage {
+	return &Storage{objects: make(map[string][]byte)}
+}
+
+// Put stores the object.
+func (s *Storage) Put(key, _, _ string, body []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.objects[key] = body
+	return nil
+}
+
+// Remove removes the object.
+func (s *Storage) Remove(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.objects, key)
+	return nil
+}
+
+// Presign returns the fake URL of the object.
+func (s *Storage) Presign(key string, ttl time.Duration) (string, error) {
+	return fmt.Sprintf("http://storage/%s?ttl=%d", key, int64(ttl/time.Second)), nil
+}
+
+// Object returns the object stored under the key.
+func (s *Storage) Object(key string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	obj, ok := s.objects[key]
+	return obj, ok
+}
@@ -0,0 +1,38 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import "github.com/mainflux/mainflux/ota"
+
+var _ ota.Things = (*thingsMock)(nil)
+
+type thingsMock struct {
+	channels    map[string]string
+	connections map[string][]string
+}
+
+// NewThings creates mock of things API. Channels are mapped to the keys of
+// their owners, and connections map the channels to the connected things.
+func NewThings(channels map[string]string, connections map[string][]string) ota.Things {
+	return thingsMock{
+		channels:    channels,
+		connections: connections,
+	}
+}
+
+func (tm thingsMock) Authorize(token, chanID string) error {
+	if tm.channels[chanID] != token {
+		return ota.ErrNotFound
+	}
+
+	return nil
+}
+
+func (tm thingsMock) Connected(token, chanID string) ([]string, error) {
+	if err := tm.Authorize(token, chanID); err != nil {
+		return nil, err
+	}
+
+	return append([]string{}, tm.connections[chanID]...), nil
+}
@@ -0,0 +1,52 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package nats contains NATS subscriber which feeds the update status
+// reports to the OTA service.
+package nats
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/mainflux/mainflux"
+	log "github.com/mainflux/mainflux/logger"
+	mfnats "github.com/mainflux/mainflux/nats"
+	"github.com/mainflux/mainflux/ota"
+	broker "github.com/nats-io/nats.go"
+)
+
+const queue = "ota"
+
+type subscriber struct {
+	svc    ota.Service
+	logger log.Logger
+}
+
+// Subscribe subscribes to the status subtopic of all the channels and feeds
+// the reports to the OTA service. Queue subscription ensures each report is
+// recorded by exactly one service instance. The subject is prefixed with the
+// deployment subject prefix, unless it is empty.
+func Subscribe(svc ota.Service, nc *broker.Conn, subjectPrefix string, logger log.Logger) error {
+	s := subscriber{
+		svc:    svc,
+		logger: logger,
+	}
+
+	subject := fmt.Sprintf("channel.*.%s", ota.StatusSubtopic)
+	_, err := nc.QueueSubscribe(mfnats.Subject(subjectPrefix, subject), queue, s.handleMsg)
+	return err
+}
+
+func (s subscriber) handleMsg(m *broker.Msg) {
+	var msg mainflux.RawMessage
+	if err := proto.Unmarshal(m.Data, &msg); err != nil {
+		s.logger.Warn(fmt.Sprintf("Failed to unmarshal received message: %s", err))
+		return
+	}
+
+	if err := s.svc.ReportStatus(context.Background(), msg); err != nil {
+		s.logger.Warn(fmt.Sprintf("Failed to record status reported by thing %s: %s", msg.Publisher, err))
+	}
+}
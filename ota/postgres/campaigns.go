@@ -0,0 +1,317 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq" // required for DB access
+	"github.com/mainflux/mainflux/ota"
+)
+
+var _ ota.CampaignRepository = (*campaignRepository)(nil)
+
+type campaignRepository struct {
+	db *sqlx.DB
+}
+
+// NewCampaignRepository instantiates a PostgreSQL implementation of campaign
+// repository.
+func NewCampaignRepository(db *sqlx.DB) ota.CampaignRepository {
+	return &campaignRepository{
+		db: db,
+	}
+}
+
+func (cr campaignRepository) Save(ctx context.Context, c ota.Campaign) error {
+	q := `INSERT INTO campaigns (id, owner, name, firmware, grp, channel, state, created)
+	      VALUES (:id, :owner, :name, :firmware, :grp, :channel, :state, :created);`
+
+	if _, err := cr.db.NamedExecContext(ctx, q, toDBCampaign(c)); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code.Name() {
+			case errDuplicate:
+				return ota.ErrConflict
+			case errForeignKey, errInvalid:
+				return ota.ErrNotFound
+			}
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (cr campaignRepository) RetrieveByID(ctx context.Context, id string) (ota.Campaign, error) {
+	q := `SELECT id, owner, name, firmware, grp, channel, state, created FROM campaigns WHERE id = $1;`
+
+	var dbc dbCampaign
+	if err := cr.db.QueryRowxContext(ctx, q, id).StructScan(&dbc); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if err == sql.ErrNoRows || ok && errInvalid == pqErr.Code.Name() {
+			return ota.Campaign{}, ota.ErrNotFound
+		}
+		return ota.Campaign{}, err
+	}
+
+	return toCampaign(dbc), nil
+}
+
+func (cr campaignRepository) RetrieveByOwner(ctx context.Context, owner string, offset, limit uint64) (ota.CampaignsPage, error) {
+	q := `SELECT id, owner, name, firmware, grp, channel, state, created FROM campaigns
+	      WHERE owner = $1 ORDER BY id LIMIT $2 OFFSET $3;`
+
+	rows, err := cr.db.QueryxContext(ctx, q, owner, limit, offset)
+	if err != nil {
+		return ota.CampaignsPage{}, err
+	}
+	defer rows.Close()
+
+	items := []ota.Campaign{}
+	for rows.Next() {
+		var dbc dbCampaign
+		if err := rows.StructScan(&dbc); err != nil {
+			return ota.CampaignsPage{}, err
+		}
+		items = append(items, toCampaign(dbc))
+	}
+
+	if err := rows.Err(); err != nil {
+		return ota.CampaignsPage{}, err
+	}
+
+	cq := `SELECT COUNT(*) FROM campaigns WHERE owner = $1;`
+
+	var total uint64
+	if err := cr.db.GetContext(ctx, &total, cq, owner); err != nil {
+		return ota.CampaignsPage{}, err
+	}
+
+	page := ota.CampaignsPage{
+		Total:     total,
+		Offset:    offset,
+		Limit:     limit,
+		Campaigns: items,
+	}
+
+	return page, nil
+}
+
+func (cr campaignRepository) CountByFirmware(ctx context.Context, id string) (uint64, error) {
+	q := `SELECT COUNT(*) FROM campaigns WHERE firmware = $1;`
+
+	var n uint64
+	if err := cr.db.GetContext(ctx, &n, q, id); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == errInvalid {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	return n, nil
+}
+
+func (cr campaignRepository) UpdateState(ctx context.Context, id, state string) error {
+	q := `UPDATE campaigns SET state = $2 WHERE id = $1;`
+
+	res, err := cr.db.ExecContext(ctx, q, id, state)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == errInvalid {
+			return ota.ErrNotFound
+		}
+		return err
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if cnt == 0 {
+		return ota.ErrNotFound
+	}
+
+	return nil
+}
+
+func (cr campaignRepository) SaveUpdates(ctx context.Context, updates []ota.Update) error {
+	if len(updates) == 0 {
+		return nil
+	}
+
+	q := `INSERT INTO updates (campaign, thing, status, message, updated)
+	      VALUES (:campaign, :thing, :status, :message, :updated);`
+
+	tx, err := cr.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, u := range updates {
+		if _, err := tx.NamedExecContext(ctx, q, toDBUpdate(u)); err != nil {
+			tx.Rollback()
+			if pqErr, ok := err.(*pq.Error); ok {
+				switch pqErr.Code.Name() {
+				case errDuplicate:
+					return ota.ErrConflict
+				case errForeignKey, errInvalid:
+					return ota.ErrNotFound
+				}
+			}
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (cr campaignRepository) RetrieveUpdate(ctx context.Context, campaign, thing string) (ota.Update, error) {
+	q := `SELECT campaign, thing, status, message, updated FROM updates WHERE campaign = $1 AND thing = $2;`
+
+	var dbu dbUpdate
+	if err := cr.db.QueryRowxContext(ctx, q, campaign, thing).StructScan(&dbu); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if err == sql.ErrNoRows || ok && errInvalid == pqErr.Code.Name() {
+			return ota.Update{}, ota.ErrNotFound
+		}
+		return ota.Update{}, err
+	}
+
+	return toUpdate(dbu), nil
+}
+
+func (cr campaignRepository) RetrieveUpdates(ctx context.Context, campaign string, offset, limit uint64) (ota.UpdatesPage, error) {
+	q := `SELECT campaign, thing, status, message, updated FROM updates
+	      WHERE campaign = $1 ORDER BY thing LIMIT $2 OFFSET $3;`
+
+	rows, err := cr.db.QueryxContext(ctx, q, campaign, limit, offset)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == errInvalid {
+			return ota.UpdatesPage{}, ota.ErrNotFound
+		}
+		return ota.UpdatesPage{}, err
+	}
+	defer rows.Close()
+
+	items := []ota.Update{}
+	for rows.Next() {
+		var dbu dbUpdate
+		if err := rows.StructScan(&dbu); err != nil {
+			return ota.UpdatesPage{}, err
+		}
+		items = append(items, toUpdate(dbu))
+	}
+
+	if err := rows.Err(); err != nil {
+		return ota.UpdatesPage{}, err
+	}
+
+	cq := `SELECT COUNT(*) FROM updates WHERE campaign = $1;`
+
+	var total uint64
+	if err := cr.db.GetContext(ctx, &total, cq, campaign); err != nil {
+		return ota.UpdatesPage{}, err
+	}
+
+	page := ota.UpdatesPage{
+		Total:   total,
+		Offset:  offset,
+		Limit:   limit,
+		Updates: items,
+	}
+
+	return page, nil
+}
+
+func (cr campaignRepository) UpdateStatus(ctx context.Context, u ota.Update) error {
+	q := `UPDATE updates SET status = :status, message = :message, updated = :updated
+	      WHERE campaign = :campaign AND thing = :thing;`
+
+	res, err := cr.db.NamedExecContext(ctx, q, toDBUpdate(u))
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == errInvalid {
+			return ota.ErrNotFound
+		}
+		return err
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if cnt == 0 {
+		return ota.ErrNotFound
+	}
+
+	return nil
+}
+
+type dbCampaign struct {
+	ID       string         `db:"id"`
+	Owner    string         `db:"owner"`
+	Name     sql.NullString `db:"name"`
+	Firmware string         `db:"firmware"`
+	Group    string         `db:"grp"`
+	Channel  string         `db:"channel"`
+	State    string         `db:"state"`
+	Created  time.Time      `db:"created"`
+}
+
+func toDBCampaign(c ota.Campaign) dbCampaign {
+	return dbCampaign{
+		ID:       c.ID,
+		Owner:    c.Owner,
+		Name:     sql.NullString{String: c.Name, Valid: c.Name != ""},
+		Firmware: c.Firmware,
+		Group:    c.Group,
+		Channel:  c.Channel,
+		State:    c.State,
+		Created:  c.Created,
+	}
+}
+
+func toCampaign(dbc dbCampaign) ota.Campaign {
+	return ota.Campaign{
+		ID:       dbc.ID,
+		Owner:    dbc.Owner,
+		Name:     dbc.Name.String,
+		Firmware: dbc.Firmware,
+		Group:    dbc.Group,
+		Channel:  dbc.Channel,
+		State:    dbc.State,
+		Created:  dbc.Created.UTC(),
+	}
+}
+
+type dbUpdate struct {
+	Campaign string         `db:"campaign"`
+	Thing    string         `db:"thing"`
+	Status   string         `db:"status"`
+	Message  sql.NullString `db:"message"`
+	Updated  time.Time      `db:"updated"`
+}
+
+func toDBUpdate(u ota.Update) dbUpdate {
+	return dbUpdate{
+		Campaign: u.Campaign,
+		Thing:    u.Thing,
+		Status:   u.Status,
+		Message:  sql.NullString{String: u.Message, Valid: u.Message != ""},
+		Updated:  u.Updated,
+	}
+}
+
+func toUpdate(dbu dbUpdate) ota.Update {
+	return ota.Update{
+		Campaign: dbu.Campaign,
+		Thing:    dbu.Thing,
+		Status:   dbu.Status,
+		Message:  dbu.Message.String,
+		Updated:  dbu.Updated.UTC(),
+	}
+}
@@ -0,0 +1,258 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/ota"
+	"github.com/mainflux/mainflux/ota/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	campaignID = "123e4567-e89b-12d3-a456-000000000011"
+	otherID    = "123e4567-e89b-12d3-a456-000000000012"
+	thingID    = "123e4567-e89b-12d3-a456-000000000021"
+	otherThing = "123e4567-e89b-12d3-a456-000000000022"
+)
+
+func newCampaign(id, fw string) ota.Campaign {
+	return ota.Campaign{
+		ID:       id,
+		Owner:    owner,
+		Name:     "rollout",
+		Firmware: fw,
+		Group:    "1",
+		Channel:  "2",
+		State:    ota.StateCreated,
+		Created:  time.Now().UTC().Truncate(time.Millisecond),
+	}
+}
+
+func newUpdate(thing string) ota.Update {
+	return ota.Update{
+		Campaign: campaignID,
+		Thing:    thing,
+		Status:   ota.StatusPending,
+		Updated:  time.Now().UTC().Truncate(time.Millisecond),
+	}
+}
+
+func saveCampaign(t *testing.T) func() {
+	fwRepo := postgres.NewFirmwareRepository(db)
+	err := fwRepo.Save(context.Background(), newFirmware(fwID, "1.0.0"))
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	repo := postgres.NewCampaignRepository(db)
+	err = repo.Save(context.Background(), newCampaign(campaignID, fwID))
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	return func() {
+		db.Exec("DELETE FROM campaigns WHERE firmware = $1", fwID)
+		fwRepo.Remove(context.Background(), fwID)
+	}
+}
+
+func TestCampaignSave(t *testing.T) {
+	fwRepo := postgres.NewFirmwareRepository(db)
+	err := fwRepo.Save(context.Background(), newFirmware(fwID, "1.0.0"))
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	defer fwRepo.Remove(context.Background(), fwID)
+	defer db.Exec("DELETE FROM campaigns WHERE firmware = $1", fwID)
+
+	repo := postgres.NewCampaignRepository(db)
+
+	cases := []struct {
+		desc     string
+		campaign ota.Campaign
+		err      error
+	}{
+		{
+			desc:     "save new campaign",
+			campaign: newCampaign(campaignID, fwID),
+			err:      nil,
+		},
+		{
+			desc:     "save campaign with existing ID",
+			campaign: newCampaign(campaignID, fwID),
+			err:      ota.ErrConflict,
+		},
+		{
+			desc:     "save campaign of non-existing firmware",
+			campaign: newCampaign(otherID, wrongID),
+			err:      ota.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := repo.Save(context.Background(), tc.campaign)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestCampaignRetrieveByID(t *testing.T) {
+	defer saveCampaign(t)()
+	repo := postgres.NewCampaignRepository(db)
+	c := newCampaign(campaignID, fwID)
+
+	cases := []struct {
+		desc     string
+		id       string
+		campaign ota.Campaign
+		err      error
+	}{
+		{
+			desc:     "retrieve existing campaign",
+			id:       campaignID,
+			campaign: c,
+			err:      nil,
+		},
+		{
+			desc:     "retrieve non-existing campaign",
+			id:       wrongID,
+			campaign: ota.Campaign{},
+			err:      ota.ErrNotFound,
+		},
+		{
+			desc:     "retrieve campaign with malformed ID",
+			id:       "invalid",
+			campaign: ota.Campaign{},
+			err:      ota.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		c, err := repo.RetrieveByID(context.Background(), tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.campaign.ID, c.ID, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.campaign.ID, c.ID))
+		assert.Equal(t, tc.campaign.Group, c.Group, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.campaign.Group, c.Group))
+	}
+}
+
+func TestCampaignRetrieveByOwner(t *testing.T) {
+	defer saveCampaign(t)()
+	repo := postgres.NewCampaignRepository(db)
+
+	page, err := repo.RetrieveByOwner(context.Background(), owner, 0, 10)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, uint64(1), page.Total, fmt.Sprintf("expected total 1 got %d", page.Total))
+
+	page, err = repo.RetrieveByOwner(context.Background(), "other@example.com", 0, 10)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, 0, len(page.Campaigns), fmt.Sprintf("expected no campaigns got %d", len(page.Campaigns)))
+}
+
+func TestCampaignCountByFirmware(t *testing.T) {
+	defer saveCampaign(t)()
+	repo := postgres.NewCampaignRepository(db)
+
+	cases := []struct {
+		desc string
+		id   string
+		n    uint64
+	}{
+		{
+			desc: "count campaigns of rolled out firmware",
+			id:   fwID,
+			n:    1,
+		},
+		{
+			desc: "count campaigns of unused firmware",
+			id:   wrongID,
+			n:    0,
+		},
+	}
+
+	for _, tc := range cases {
+		n, err := repo.CountByFirmware(context.Background(), tc.id)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		assert.Equal(t, tc.n, n, fmt.Sprintf("%s: expected %d got %d\n", tc.desc, tc.n, n))
+	}
+}
+
+func TestCampaignUpdateState(t *testing.T) {
+	defer saveCampaign(t)()
+	repo := postgres.NewCampaignRepository(db)
+
+	cases := []struct {
+		desc string
+		id   string
+		err  error
+	}{
+		{
+			desc: "update state of existing campaign",
+			id:   campaignID,
+			err:  nil,
+		},
+		{
+			desc: "update state of non-existing campaign",
+			id:   wrongID,
+			err:  ota.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := repo.UpdateState(context.Background(), tc.id, ota.StateRunning)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	c, err := repo.RetrieveByID(context.Background(), campaignID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, ota.StateRunning, c.State, fmt.Sprintf("expected %s got %s", ota.StateRunning, c.State))
+}
+
+func TestUpdates(t *testing.T) {
+	defer saveCampaign(t)()
+	repo := postgres.NewCampaignRepository(db)
+
+	updates := []ota.Update{newUpdate(thingID), newUpdate(otherThing)}
+	err := repo.SaveUpdates(context.Background(), updates)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	err = repo.SaveUpdates(context.Background(), updates)
+	assert.Equal(t, ota.ErrConflict, err, fmt.Sprintf("save existing updates: expected %s got %s\n", ota.ErrConflict, err))
+
+	reported := updates[0]
+	reported.Status = ota.StatusFailed
+	reported.Message = "checksum mismatch"
+
+	cases := []struct {
+		desc   string
+		update ota.Update
+		err    error
+	}{
+		{
+			desc:   "update status of existing update",
+			update: reported,
+			err:    nil,
+		},
+		{
+			desc:   "update status of non-existing update",
+			update: ota.Update{Campaign: campaignID, Thing: wrongID, Status: ota.StatusFailed},
+			err:    ota.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := repo.UpdateStatus(context.Background(), tc.update)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	u, err := repo.RetrieveUpdate(context.Background(), campaignID, thingID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, reported, u, fmt.Sprintf("expected %v got %v", reported, u))
+
+	_, err = repo.RetrieveUpdate(context.Background(), campaignID, wrongID)
+	assert.Equal(t, ota.ErrNotFound, err, fmt.Sprintf("retrieve non-existing update: expected %s got %s\n", ota.ErrNotFound, err))
+
+	page, err := repo.RetrieveUpdates(context.Background(), campaignID, 1, 10)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, uint64(2), page.Total, fmt.Sprintf("expected total 2 got %d", page.Total))
+	assert.Equal(t, []ota.Update{updates[1]}, page.Updates, fmt.Sprintf("expected %v got %v", updates[1:], page.Updates))
+}
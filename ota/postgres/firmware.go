@@ -0,0 +1,158 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq" // required for DB access
+	"github.com/mainflux/mainflux/ota"
+)
+
+const (
+	errDuplicate  = "unique_violation"
+	errInvalid    = "invalid_text_representation"
+	errForeignKey = "foreign_key_violation"
+)
+
+var _ ota.FirmwareRepository = (*firmwareRepository)(nil)
+
+type firmwareRepository struct {
+	db *sqlx.DB
+}
+
+// NewFirmwareRepository instantiates a PostgreSQL implementation of firmware
+// repository.
+func NewFirmwareRepository(db *sqlx.DB) ota.FirmwareRepository {
+	return &firmwareRepository{
+		db: db,
+	}
+}
+
+func (fr firmwareRepository) Save(ctx context.Context, fw ota.Firmware) error {
+	q := `INSERT INTO firmware (id, owner, name, version, content_type, size, checksum, created)
+	      VALUES (:id, :owner, :name, :version, :content_type, :size, :checksum, :created);`
+
+	if _, err := fr.db.NamedExecContext(ctx, q, toDBFirmware(fw)); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == errDuplicate {
+			return ota.ErrConflict
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (fr firmwareRepository) RetrieveByID(ctx context.Context, id string) (ota.Firmware, error) {
+	q := `SELECT id, owner, name, version, content_type, size, checksum, created FROM firmware WHERE id = $1;`
+
+	var dbf dbFirmware
+	if err := fr.db.QueryRowxContext(ctx, q, id).StructScan(&dbf); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if err == sql.ErrNoRows || ok && errInvalid == pqErr.Code.Name() {
+			return ota.Firmware{}, ota.ErrNotFound
+		}
+		return ota.Firmware{}, err
+	}
+
+	return toFirmware(dbf), nil
+}
+
+func (fr firmwareRepository) RetrieveByOwner(ctx context.Context, owner string, offset, limit uint64) (ota.FirmwarePage, error) {
+	q := `SELECT id, owner, name, version, content_type, size, checksum, created FROM firmware
+	      WHERE owner = $1 ORDER BY id LIMIT $2 OFFSET $3;`
+
+	rows, err := fr.db.QueryxContext(ctx, q, owner, limit, offset)
+	if err != nil {
+		return ota.FirmwarePage{}, err
+	}
+	defer rows.Close()
+
+	items := []ota.Firmware{}
+	for rows.Next() {
+		var dbf dbFirmware
+		if err := rows.StructScan(&dbf); err != nil {
+			return ota.FirmwarePage{}, err
+		}
+		items = append(items, toFirmware(dbf))
+	}
+
+	if err := rows.Err(); err != nil {
+		return ota.FirmwarePage{}, err
+	}
+
+	cq := `SELECT COUNT(*) FROM firmware WHERE owner = $1;`
+
+	var total uint64
+	if err := fr.db.GetContext(ctx, &total, cq, owner); err != nil {
+		return ota.FirmwarePage{}, err
+	}
+
+	page := ota.FirmwarePage{
+		Total:    total,
+		Offset:   offset,
+		Limit:    limit,
+		Firmware: items,
+	}
+
+	return page, nil
+}
+
+func (fr firmwareRepository) Remove(ctx context.Context, id string) error {
+	q := `DELETE FROM firmware WHERE id = $1;`
+
+	if _, err := fr.db.ExecContext(ctx, q, id); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code.Name() {
+			case errInvalid:
+				return nil
+			case errForeignKey:
+				return ota.ErrConflict
+			}
+		}
+		return err
+	}
+
+	return nil
+}
+
+type dbFirmware struct {
+	ID          string    `db:"id"`
+	Owner       string    `db:"owner"`
+	Name        string    `db:"name"`
+	Version     string    `db:"version"`
+	ContentType string    `db:"content_type"`
+	Size        int64     `db:"size"`
+	Checksum    string    `db:"checksum"`
+	Created     time.Time `db:"created"`
+}
+
+func toDBFirmware(fw ota.Firmware) dbFirmware {
+	return dbFirmware{
+		ID:          fw.ID,
+		Owner:       fw.Owner,
+		Name:        fw.Name,
+		Version:     fw.Version,
+		ContentType: fw.ContentType,
+		Size:        int64(fw.Size),
+		Checksum:    fw.Checksum,
+		Created:     fw.Created,
+	}
+}
+
+func toFirmware(dbf dbFirmware) ota.Firmware {
+	return ota.Firmware{
+		ID:          dbf.ID,
+		Owner:       dbf.Owner,
+		Name:        dbf.Name,
+		Version:     dbf.Version,
+		ContentType: dbf.ContentType,
+		Size:        uint64(dbf.Size),
+		Checksum:    dbf.Checksum,
+		Created:     dbf.Created.UTC(),
+	}
+}
@@ -0,0 +1,196 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/ota"
+	"github.com/mainflux/mainflux/ota/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	fwID     = "123e4567-e89b-12d3-a456-000000000001"
+	otherFW  = "123e4567-e89b-12d3-a456-000000000002"
+	wrongID  = "123e4567-e89b-12d3-a456-000000000099"
+	owner    = "user@example.com"
+	checksum = "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+)
+
+func newFirmware(id, version string) ota.Firmware {
+	return ota.Firmware{
+		ID:          id,
+		Owner:       owner,
+		Name:        "sensor",
+		Version:     version,
+		ContentType: "application/octet-stream",
+		Size:        4,
+		Checksum:    checksum,
+		Created:     time.Now().UTC().Truncate(time.Millisecond),
+	}
+}
+
+func TestFirmwareSave(t *testing.T) {
+	repo := postgres.NewFirmwareRepository(db)
+	defer repo.Remove(context.Background(), fwID)
+
+	cases := []struct {
+		desc string
+		fw   ota.Firmware
+		err  error
+	}{
+		{
+			desc: "save new firmware",
+			fw:   newFirmware(fwID, "1.0.0"),
+			err:  nil,
+		},
+		{
+			desc: "save firmware with existing name and version",
+			fw:   newFirmware(otherFW, "1.0.0"),
+			err:  ota.ErrConflict,
+		},
+	}
+
+	for _, tc := range cases {
+		err := repo.Save(context.Background(), tc.fw)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestFirmwareRetrieveByID(t *testing.T) {
+	repo := postgres.NewFirmwareRepository(db)
+	fw := newFirmware(fwID, "1.0.0")
+	err := repo.Save(context.Background(), fw)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	defer repo.Remove(context.Background(), fwID)
+
+	cases := []struct {
+		desc string
+		id   string
+		fw   ota.Firmware
+		err  error
+	}{
+		{
+			desc: "retrieve existing firmware",
+			id:   fwID,
+			fw:   fw,
+			err:  nil,
+		},
+		{
+			desc: "retrieve non-existing firmware",
+			id:   wrongID,
+			fw:   ota.Firmware{},
+			err:  ota.ErrNotFound,
+		},
+		{
+			desc: "retrieve firmware with malformed ID",
+			id:   "invalid",
+			fw:   ota.Firmware{},
+			err:  ota.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		fw, err := repo.RetrieveByID(context.Background(), tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.fw, fw, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.fw, fw))
+	}
+}
+
+func TestFirmwareRetrieveByOwner(t *testing.T) {
+	repo := postgres.NewFirmwareRepository(db)
+	for i, id := range []string{fwID, otherFW} {
+		err := repo.Save(context.Background(), newFirmware(id, fmt.Sprintf("1.0.%d", i)))
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		defer repo.Remove(context.Background(), id)
+	}
+
+	cases := []struct {
+		desc   string
+		owner  string
+		offset uint64
+		limit  uint64
+		size   int
+		total  uint64
+	}{
+		{
+			desc:   "retrieve all firmware",
+			owner:  owner,
+			offset: 0,
+			limit:  10,
+			size:   2,
+			total:  2,
+		},
+		{
+			desc:   "retrieve firmware page",
+			owner:  owner,
+			offset: 1,
+			limit:  10,
+			size:   1,
+			total:  2,
+		},
+		{
+			desc:   "retrieve firmware of the other owner",
+			owner:  "other@example.com",
+			offset: 0,
+			limit:  10,
+			size:   0,
+			total:  0,
+		},
+	}
+
+	for _, tc := range cases {
+		page, err := repo.RetrieveByOwner(context.Background(), tc.owner, tc.offset, tc.limit)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		assert.Equal(t, tc.size, len(page.Firmware), fmt.Sprintf("%s: expected %d got %d\n", tc.desc, tc.size, len(page.Firmware)))
+		assert.Equal(t, tc.total, page.Total, fmt.Sprintf("%s: expected %d got %d\n", tc.desc, tc.total, page.Total))
+	}
+}
+
+func TestFirmwareRemove(t *testing.T) {
+	repo := postgres.NewFirmwareRepository(db)
+	campaigns := postgres.NewCampaignRepository(db)
+
+	for i, id := range []string{fwID, otherFW} {
+		err := repo.Save(context.Background(), newFirmware(id, fmt.Sprintf("1.0.%d", i)))
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+	defer repo.Remove(context.Background(), otherFW)
+
+	err := campaigns.Save(context.Background(), newCampaign(campaignID, otherFW))
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	defer db.Exec("DELETE FROM campaigns WHERE id = $1", campaignID)
+
+	cases := []struct {
+		desc string
+		id   string
+		err  error
+	}{
+		{
+			desc: "remove existing firmware",
+			id:   fwID,
+			err:  nil,
+		},
+		{
+			desc: "remove removed firmware",
+			id:   fwID,
+			err:  nil,
+		},
+		{
+			desc: "remove firmware used by the campaign",
+			id:   otherFW,
+			err:  ota.ErrConflict,
+		},
+	}
+
+	for _, tc := range cases {
+		err := repo.Remove(context.Background(), tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq" // required for SQL access
+	migrate "github.com/rubenv/sql-migrate"
+)
+
+// Config defines the options that are used when connecting to a PostgreSQL instance
+type Config struct {
+	Host        string
+	Port        string
+	User        string
+	Pass        string
+	Name        string
+	SSLMode     string
+	SSLCert     string
+	SSLKey      string
+	SSLRootCert string
+}
+
+// Connect creates a connection to the PostgreSQL instance and applies any
+// unapplied database migrations. A non-nil error is returned to indicate
+// failure.
+func Connect(cfg Config) (*sqlx.DB, error) {
+	url := fmt.Sprintf("host=%s port=%s user=%s dbname=%s password=%s sslmode=%s sslcert=%s sslkey=%s sslrootcert=%s", cfg.Host, cfg.Port, cfg.User, cfg.Name, cfg.Pass, cfg.SSLMode, cfg.SSLCert, cfg.SSLKey, cfg.SSLRootCert)
+
+	db, err := sqlx.Open("postgres", url)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := migrateDB(db); err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+func migrateDB(db *sqlx.DB) error {
+	migrations := &migrate.MemoryMigrationSource{
+		Migrations: []*migrate.Migration{
+			{
+				Id: "ota_1",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS firmware (
+						id           UUID PRIMARY KEY,
+						owner        VARCHAR(254) NOT NULL,
+						name         VARCHAR(1024) NOT NULL,
+						version      VARCHAR(254) NOT NULL,
+						content_type VARCHAR(254) NOT NULL,
+						size         BIGINT NOT NULL,
+						checksum     CHAR(64) NOT NULL,
+						created      TIMESTAMPTZ NOT NULL,
+						UNIQUE (owner, name, version)
+					)`,
+					`CREATE TABLE IF NOT EXISTS campaigns (
+						id       UUID PRIMARY KEY,
+						owner    VARCHAR(254) NOT NULL,
+						name     VARCHAR(1024),
+						firmware UUID NOT NULL REFERENCES firmware (id),
+						grp      VARCHAR(254) NOT NULL,
+						channel  VARCHAR(254) NOT NULL,
+						state    VARCHAR(32) NOT NULL,
+						created  TIMESTAMPTZ NOT NULL
+					)`,
+					`CREATE TABLE IF NOT EXISTS updates (
+						campaign UUID REFERENCES campaigns (id) ON DELETE CASCADE,
+						thing    VARCHAR(254),
+						status   VARCHAR(32) NOT NULL,
+						message  TEXT,
+						updated  TIMESTAMPTZ NOT NULL,
+						PRIMARY KEY (campaign, thing)
+					)`,
+					`CREATE INDEX IF NOT EXISTS firmware_owner_idx ON firmware (owner)`,
+					`CREATE INDEX IF NOT EXISTS campaigns_owner_idx ON campaigns (owner)`,
+					`CREATE INDEX IF NOT EXISTS campaigns_firmware_idx ON campaigns (firmware)`,
+				},
+				Down: []string{
+					`DROP TABLE IF EXISTS updates`,
+					`DROP TABLE IF EXISTS campaigns`,
+					`DROP TABLE IF EXISTS firmware`,
+				},
+			},
+		},
+	}
+
+	_, err := migrate.Exec(db.DB, "postgres", migrations, migrate.Up)
+	return err
+}
@@ -0,0 +1,548 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package ota
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mainflux/mainflux"
+)
+
+const (
+	// Protocol is the protocol of the commands published by the service.
+	Protocol = "ota"
+
+	// CommandSubtopic is the prefix of the control channel subtopic the
+	// commands are delivered to. The thing ID is appended to it, so that
+	// each thing subscribes only to its own commands.
+	CommandSubtopic = "ota"
+
+	// StatusSubtopic is the control channel subtopic the things report the
+	// update status to.
+	StatusSubtopic = "ota.status"
+
+	// OpUpdate command instructs the thing to download and install the
+	// firmware.
+	OpUpdate = "update"
+
+	// OpCancel command instructs the thing to abandon the update.
+	OpCancel = "cancel"
+
+	defContentType = "application/octet-stream"
+	pageSize       = 100
+)
+
+var (
+	// ErrMalformedEntity indicates malformed entity specification.
+	ErrMalformedEntity = errors.New("malformed entity specification")
+
+	// ErrUnauthorizedAccess indicates missing or invalid credentials provided
+	// when accessing a protected resource.
+	ErrUnauthorizedAccess = errors.New("missing or invalid credentials provided")
+
+	// ErrNotFound indicates a non-existent entity request.
+	ErrNotFound = errors.New("non-existent entity")
+
+	// ErrConflict indicates that the firmware with the same name and
+	// version already exists, or that the firmware is still rolled out.
+	ErrConflict = errors.New("entity already exists or is in use")
+
+	// ErrInvalidState indicates that the operation isn't allowed in the
+	// current state of the campaign or the update.
+	ErrInvalidState = errors.New("operation not allowed in the current state")
+)
+
+// Command is the payload of the message delivered to the thing over the
+// campaign control channel. Cancel commands carry only the campaign.
+type Command struct {
+	Operation string `json:"op"`
+	Campaign  string `json:"campaign"`
+	Firmware  string `json:"firmware,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Version   string `json:"version,omitempty"`
+	URL       string `json:"url,omitempty"`
+	Size      uint64 `json:"size,omitempty"`
+	Checksum  string `json:"checksum,omitempty"`
+}
+
+// Report is the payload of the status message published by the thing.
+type Report struct {
+	Campaign string `json:"campaign"`
+	Status   string `json:"status"`
+	Message  string `json:"message,omitempty"`
+}
+
+// Service specifies an API that must be fulfilled by the domain service
+// implementation, and all of its decorators (e.g. logging & metrics).
+type Service interface {
+	// UploadFirmware stores the firmware image and adds the firmware to
+	// the user identified by the provided key.
+	UploadFirmware(context.Context, string, Firmware, []byte) (Firmware, error)
+
+	// ViewFirmware retrieves the firmware identified by the provided ID,
+	// that belongs to the user identified by the provided key.
+	ViewFirmware(context.Context, string, string) (Firmware, error)
+
+	// ListFirmware retrieves subset of firmware that belong to the user
+	// identified by the provided key.
+	ListFirmware(context.Context, string, uint64, uint64) (FirmwarePage, error)
+
+	// RemoveFirmware removes the firmware identified by the provided ID,
+	// that belongs to the user identified by the provided key, along with
+	// its image. Firmware rolled out by any campaign can't be removed.
+	RemoveFirmware(context.Context, string, string) error
+
+	// CreateCampaign adds new campaign to the user identified by the
+	// provided key.
+	CreateCampaign(context.Context, string, Campaign) (Campaign, error)
+
+	// ViewCampaign retrieves the campaign identified by the provided ID,
+	// that belongs to the user identified by the provided key.
+	ViewCampaign(context.Context, string, string) (Campaign, error)
+
+	// ListCampaigns retrieves subset of campaigns that belong to the user
+	// identified by the provided key.
+	ListCampaigns(context.Context, string, uint64, uint64) (CampaignsPage, error)
+
+	// StartCampaign sends the update commands to the campaign things.
+	StartCampaign(context.Context, string, string) error
+
+	// CancelCampaign cancels the unfinished updates of the campaign and
+	// sends the cancel commands to the things which were sent the update.
+	CancelCampaign(context.Context, string, string) error
+
+	// ListUpdates retrieves subset of the campaign updates.
+	ListUpdates(context.Context, string, string, uint64, uint64) (UpdatesPage, error)
+
+	// ReportStatus records the update status reported by the thing which
+	// published the message.
+	ReportStatus(context.Context, mainflux.RawMessage) error
+}
+
+var _ Service = (*otaService)(nil)
+
+type otaService struct {
+	users     mainflux.UsersServiceClient
+	things    Things
+	firmware  FirmwareRepository
+	campaigns CampaignRepository
+	storage   Storage
+	publisher mainflux.MessagePublisher
+	idp       IdentityProvider
+	urlTTL    time.Duration
+}
+
+// New instantiates the OTA service implementation. Download URLs sent to the
+// things expire after the given TTL.
+func New(users mainflux.UsersServiceClient, things Things, firmware FirmwareRepository, campaigns CampaignRepository, storage Storage, publisher mainflux.MessagePublisher, idp IdentityProvider, urlTTL time.Duration) Service {
+	return &otaService{
+		users:     users,
+		things:    things,
+		firmware:  firmware,
+		campaigns: campaigns,
+		storage:   storage,
+		publisher: publisher,
+		idp:       idp,
+		urlTTL:    urlTTL,
+	}
+}
+
+func (ots *otaService) UploadFirmware(ctx context.Context, token string, fw Firmware, image []byte) (Firmware, error) {
+	owner, err := ots.identify(ctx, token)
+	if err != nil {
+		return Firmware{}, err
+	}
+
+	if fw.Name == "" || fw.Version == "" || len(image) == 0 {
+		return Firmware{}, ErrMalformedEntity
+	}
+
+	fw.ID, err = ots.idp.ID()
+	if err != nil {
+		return Firmware{}, err
+	}
+
+	sum := sha256.Sum256(image)
+	fw.Owner = owner
+	fw.Size = uint64(len(image))
+	fw.Checksum = hex.EncodeToString(sum[:])
+	fw.Created = time.Now().UTC()
+	if fw.ContentType == "" {
+		fw.ContentType = defContentType
+	}
+
+	if err := ots.storage.Put(imageKey(fw.ID), fw.ContentType, "", image); err != nil {
+		return Firmware{}, err
+	}
+
+	if err := ots.firmware.Save(ctx, fw); err != nil {
+		// Don't leave the image of the rejected firmware behind.
+		ots.storage.Remove(imageKey(fw.ID))
+		return Firmware{}, err
+	}
+
+	return fw, nil
+}
+
+func (ots *otaService) ViewFirmware(ctx context.Context, token, id string) (Firmware, error) {
+	owner, err := ots.identify(ctx, token)
+	if err != nil {
+		return Firmware{}, err
+	}
+
+	return ots.ownedFirmware(ctx, owner, id)
+}
+
+func (ots *otaService) ListFirmware(ctx context.Context, token string, offset, limit uint64) (FirmwarePage, error) {
+	owner, err := ots.identify(ctx, token)
+	if err != nil {
+		return FirmwarePage{}, err
+	}
+
+	return ots.firmware.RetrieveByOwner(ctx, owner, offset, limit)
+}
+
+func (ots *otaService) RemoveFirmware(ctx context.Context, token, id string) error {
+	if _, err := ots.ViewFirmware(ctx, token, id); err != nil {
+		return err
+	}
+
+	n, err := ots.campaigns.CountByFirmware(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if n > 0 {
+		return ErrConflict
+	}
+
+	if err := ots.firmware.Remove(ctx, id); err != nil {
+		return err
+	}
+
+	return ots.storage.Remove(imageKey(id))
+}
+
+func (ots *otaService) CreateCampaign(ctx context.Context, token string, c Campaign) (Campaign, error) {
+	owner, err := ots.identify(ctx, token)
+	if err != nil {
+		return Campaign{}, err
+	}
+
+	if c.Firmware == "" || c.Channel == "" {
+		return Campaign{}, ErrMalformedEntity
+	}
+
+	if c.Group == "" {
+		c.Group = c.Channel
+	}
+
+	if _, err := ots.ownedFirmware(ctx, owner, c.Firmware); err != nil {
+		return Campaign{}, err
+	}
+
+	for _, ch := range []string{c.Channel, c.Group} {
+		if err := ots.things.Authorize(token, ch); err != nil {
+			return Campaign{}, err
+		}
+	}
+
+	c.ID, err = ots.idp.ID()
+	if err != nil {
+		return Campaign{}, err
+	}
+	c.Owner = owner
+	c.State = StateCreated
+	c.Created = time.Now().UTC()
+
+	if err := ots.campaigns.Save(ctx, c); err != nil {
+		return Campaign{}, err
+	}
+
+	return c, nil
+}
+
+func (ots *otaService) ViewCampaign(ctx context.Context, token, id string) (Campaign, error) {
+	owner, err := ots.identify(ctx, token)
+	if err != nil {
+		return Campaign{}, err
+	}
+
+	c, err := ots.campaigns.RetrieveByID(ctx, id)
+	if err != nil {
+		return Campaign{}, err
+	}
+
+	if c.Owner != owner {
+		return Campaign{}, ErrNotFound
+	}
+
+	return c, nil
+}
+
+func (ots *otaService) ListCampaigns(ctx context.Context, token string, offset, limit uint64) (CampaignsPage, error) {
+	owner, err := ots.identify(ctx, token)
+	if err != nil {
+		return CampaignsPage{}, err
+	}
+
+	return ots.campaigns.RetrieveByOwner(ctx, owner, offset, limit)
+}
+
+func (ots *otaService) StartCampaign(ctx context.Context, token, id string) error {
+	c, err := ots.ViewCampaign(ctx, token, id)
+	if err != nil {
+		return err
+	}
+
+	if c.State != StateCreated {
+		return ErrInvalidState
+	}
+
+	fw, err := ots.firmware.RetrieveByID(ctx, c.Firmware)
+	if err != nil {
+		return err
+	}
+
+	targets, err := ots.targets(token, c)
+	if err != nil {
+		return err
+	}
+
+	url, err := ots.storage.Presign(imageKey(fw.ID), ots.urlTTL)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	updates := make([]Update, 0, len(targets))
+	for _, thing := range targets {
+		updates = append(updates, Update{
+			Campaign: c.ID,
+			Thing:    thing,
+			Status:   StatusPending,
+			Updated:  now,
+		})
+	}
+
+	if err := ots.campaigns.SaveUpdates(ctx, updates); err != nil {
+		return err
+	}
+
+	if err := ots.campaigns.UpdateState(ctx, c.ID, StateRunning); err != nil {
+		return err
+	}
+
+	cmd := Command{
+		Operation: OpUpdate,
+		Campaign:  c.ID,
+		Firmware:  fw.ID,
+		Name:      fw.Name,
+		Version:   fw.Version,
+		URL:       url,
+		Size:      fw.Size,
+		Checksum:  fw.Checksum,
+	}
+
+	// Delivery failure of a single command must not prevent the rest of
+	// the things from being updated, so it is recorded as the failed update.
+	for _, u := range updates {
+		if err := ots.send(ctx, c.Channel, u.Thing, cmd); err != nil {
+			u.Status = StatusFailed
+			u.Message = fmt.Sprintf("failed to deliver update command: %s", err)
+			u.Updated = time.Now().UTC()
+			if err := ots.campaigns.UpdateStatus(ctx, u); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (ots *otaService) CancelCampaign(ctx context.Context, token, id string) error {
+	c, err := ots.ViewCampaign(ctx, token, id)
+	if err != nil {
+		return err
+	}
+
+	if c.State == StateCancelled {
+		return ErrInvalidState
+	}
+
+	if err := ots.campaigns.UpdateState(ctx, c.ID, StateCancelled); err != nil {
+		return err
+	}
+
+	// Collect the unfinished updates first, so that changing their status
+	// doesn't interfere with the paging.
+	var pending []Update
+	for offset := uint64(0); ; offset += pageSize {
+		page, err := ots.campaigns.RetrieveUpdates(ctx, c.ID, offset, pageSize)
+		if err != nil {
+			return err
+		}
+
+		for _, u := range page.Updates {
+			if !u.Done() {
+				pending = append(pending, u)
+			}
+		}
+
+		if offset+pageSize >= page.Total {
+			break
+		}
+	}
+
+	cmd := Command{
+		Operation: OpCancel,
+		Campaign:  c.ID,
+	}
+
+	var sendErr error
+	for _, u := range pending {
+		u.Status = StatusCancelled
+		u.Message = ""
+		u.Updated = time.Now().UTC()
+		if err := ots.campaigns.UpdateStatus(ctx, u); err != nil {
+			return err
+		}
+
+		if err := ots.send(ctx, c.Channel, u.Thing, cmd); err != nil {
+			sendErr = err
+		}
+	}
+
+	return sendErr
+}
+
+func (ots *otaService) ListUpdates(ctx context.Context, token, id string, offset, limit uint64) (UpdatesPage, error) {
+	if _, err := ots.ViewCampaign(ctx, token, id); err != nil {
+		return UpdatesPage{}, err
+	}
+
+	return ots.campaigns.RetrieveUpdates(ctx, id, offset, limit)
+}
+
+func (ots *otaService) ReportStatus(ctx context.Context, msg mainflux.RawMessage) error {
+	var r Report
+	if err := json.Unmarshal(msg.Payload, &r); err != nil {
+		return ErrMalformedEntity
+	}
+
+	if r.Campaign == "" || msg.Publisher == "" || !reported[r.Status] {
+		return ErrMalformedEntity
+	}
+
+	c, err := ots.campaigns.RetrieveByID(ctx, r.Campaign)
+	if err != nil {
+		return err
+	}
+
+	// Status has to be reported over the control channel of the campaign,
+	// so that the thing can't report the status of the other campaigns.
+	if c.Channel != msg.Channel {
+		return ErrNotFound
+	}
+
+	if c.State != StateRunning {
+		return ErrInvalidState
+	}
+
+	u, err := ots.campaigns.RetrieveUpdate(ctx, c.ID, msg.Publisher)
+	if err != nil {
+		return err
+	}
+
+	if u.Done() {
+		return ErrInvalidState
+	}
+
+	u.Status = r.Status
+	u.Message = r.Message
+	u.Updated = time.Now().UTC()
+
+	return ots.campaigns.UpdateStatus(ctx, u)
+}
+
+func (ots *otaService) identify(ctx context.Context, token string) (string, error) {
+	res, err := ots.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return "", ErrUnauthorizedAccess
+	}
+
+	return res.GetValue(), nil
+}
+
+func (ots *otaService) ownedFirmware(ctx context.Context, owner, id string) (Firmware, error) {
+	fw, err := ots.firmware.RetrieveByID(ctx, id)
+	if err != nil {
+		return Firmware{}, err
+	}
+
+	if fw.Owner != owner {
+		return Firmware{}, ErrNotFound
+	}
+
+	return fw, nil
+}
+
+// targets returns the things of the campaign group which are connected to
+// the control channel as well.
+func (ots *otaService) targets(token string, c Campaign) ([]string, error) {
+	group, err := ots.things.Connected(token, c.Group)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.Group == c.Channel {
+		return group, nil
+	}
+
+	control, err := ots.things.Connected(token, c.Channel)
+	if err != nil {
+		return nil, err
+	}
+
+	connected := make(map[string]bool, len(control))
+	for _, thing := range control {
+		connected[thing] = true
+	}
+
+	targets := []string{}
+	for _, thing := range group {
+		if connected[thing] {
+			targets = append(targets, thing)
+		}
+	}
+
+	return targets, nil
+}
+
+func (ots *otaService) send(ctx context.Context, chanID, thing string, cmd Command) error {
+	payload, err := json.Marshal(cmd)
+	if err != nil {
+		return err
+	}
+
+	msg := mainflux.RawMessage{
+		Channel:     chanID,
+		Subtopic:    fmt.Sprintf("%s.%s", CommandSubtopic, thing),
+		Protocol:    Protocol,
+		ContentType: "application/json",
+		Payload:     payload,
+	}
+
+	return ots.publisher.Publish(ctx, "", msg)
+}
+
+func imageKey(id string) string {
+	return fmt.Sprintf("firmware/%s", id)
+}
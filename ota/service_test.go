@@ -0,0 +1,760 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package ota_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/ota"
+	"github.com/mainflux/mainflux/ota/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	wrongValue = "wrong-value"
+	email      = "user@example.com"
+	token      = "token"
+	otherEmail = "other@example.com"
+	otherToken = "other"
+	groupChan  = "1"
+	ctrlChan   = "2"
+	otherChan  = "3"
+	thing1     = "thing1"
+	thing2     = "thing2"
+	thing3     = "thing3"
+	urlTTL     = time.Hour
+)
+
+var (
+	image    = []byte("firmware")
+	firmware = ota.Firmware{Name: "sensor", Version: "1.0.0"}
+)
+
+type deps struct {
+	storage   *mocks.Storage
+	publisher *mocks.Publisher
+}
+
+func newService() (ota.Service, deps) {
+	users := mocks.NewUsersService(map[string]string{token: email, otherToken: otherEmail})
+	channels := map[string]string{groupChan: token, ctrlChan: token, otherChan: otherToken}
+	connections := map[string][]string{
+		groupChan: {thing1, thing2, thing3},
+		ctrlChan:  {thing1, thing2},
+	}
+	things := mocks.NewThings(channels, connections)
+	d := deps{
+		storage:   mocks.NewStorage(),
+		publisher: mocks.NewPublisher(),
+	}
+	idp := mocks.NewIdentityProvider()
+
+	svc := ota.New(users, things, mocks.NewFirmwareRepository(), mocks.NewCampaignRepository(), d.storage, d.publisher, idp, urlTTL)
+	return svc, d
+}
+
+func createCampaign(t *testing.T, svc ota.Service) ota.Campaign {
+	fw, err := svc.UploadFirmware(context.Background(), token, firmware, image)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	c, err := svc.CreateCampaign(context.Background(), token, ota.Campaign{Firmware: fw.ID, Group: groupChan, Channel: ctrlChan})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	return c
+}
+
+func report(thing, campaign, status string) mainflux.RawMessage {
+	payload, _ := json.Marshal(ota.Report{Campaign: campaign, Status: status})
+	return mainflux.RawMessage{
+		Channel:   ctrlChan,
+		Subtopic:  ota.StatusSubtopic,
+		Publisher: thing,
+		Payload:   payload,
+	}
+}
+
+func TestUploadFirmware(t *testing.T) {
+	svc, d := newService()
+
+	cases := []struct {
+		desc  string
+		token string
+		fw    ota.Firmware
+		image []byte
+		err   error
+	}{
+		{
+			desc:  "upload firmware",
+			token: token,
+			fw:    firmware,
+			image: image,
+			err:   nil,
+		},
+		{
+			desc:  "upload firmware with existing name and version",
+			token: token,
+			fw:    firmware,
+			image: image,
+			err:   ota.ErrConflict,
+		},
+		{
+			desc:  "upload firmware with existing name and version as other user",
+			token: otherToken,
+			fw:    firmware,
+			image: image,
+			err:   nil,
+		},
+		{
+			desc:  "upload firmware without version",
+			token: token,
+			fw:    ota.Firmware{Name: "sensor"},
+			image: image,
+			err:   ota.ErrMalformedEntity,
+		},
+		{
+			desc:  "upload firmware without image",
+			token: token,
+			fw:    ota.Firmware{Name: "sensor", Version: "1.0.1"},
+			image: nil,
+			err:   ota.ErrMalformedEntity,
+		},
+		{
+			desc:  "upload firmware with invalid credentials",
+			token: wrongValue,
+			fw:    firmware,
+			image: image,
+			err:   ota.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		fw, err := svc.UploadFirmware(context.Background(), tc.token, tc.fw, tc.image)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err != nil {
+			continue
+		}
+
+		assert.Equal(t, uint64(len(tc.image)), fw.Size, fmt.Sprintf("%s: expected size %d got %d\n", tc.desc, len(tc.image), fw.Size))
+		assert.Len(t, fw.Checksum, 64, fmt.Sprintf("%s: expected SHA-256 checksum got %s\n", tc.desc, fw.Checksum))
+		obj, ok := d.storage.Object(fmt.Sprintf("firmware/%s", fw.ID))
+		assert.True(t, ok, fmt.Sprintf("%s: expected image to be stored\n", tc.desc))
+		assert.Equal(t, tc.image, obj, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.image, obj))
+	}
+}
+
+func TestViewFirmware(t *testing.T) {
+	svc, _ := newService()
+	fw, err := svc.UploadFirmware(context.Background(), token, firmware, image)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		token string
+		id    string
+		err   error
+	}{
+		{
+			desc:  "view existing firmware",
+			token: token,
+			id:    fw.ID,
+			err:   nil,
+		},
+		{
+			desc:  "view firmware of other user",
+			token: otherToken,
+			id:    fw.ID,
+			err:   ota.ErrNotFound,
+		},
+		{
+			desc:  "view non-existing firmware",
+			token: token,
+			id:    wrongValue,
+			err:   ota.ErrNotFound,
+		},
+		{
+			desc:  "view firmware with invalid credentials",
+			token: wrongValue,
+			id:    fw.ID,
+			err:   ota.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := svc.ViewFirmware(context.Background(), tc.token, tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestListFirmware(t *testing.T) {
+	svc, _ := newService()
+	for i := 0; i < 5; i++ {
+		fw := ota.Firmware{Name: "sensor", Version: fmt.Sprintf("1.0.%d", i)}
+		_, err := svc.UploadFirmware(context.Background(), token, fw, image)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	cases := []struct {
+		desc   string
+		token  string
+		offset uint64
+		limit  uint64
+		size   int
+		err    error
+	}{
+		{
+			desc:   "list all firmware",
+			token:  token,
+			offset: 0,
+			limit:  10,
+			size:   5,
+			err:    nil,
+		},
+		{
+			desc:   "list firmware page",
+			token:  token,
+			offset: 3,
+			limit:  10,
+			size:   2,
+			err:    nil,
+		},
+		{
+			desc:   "list firmware of other user",
+			token:  otherToken,
+			offset: 0,
+			limit:  10,
+			size:   0,
+			err:    nil,
+		},
+		{
+			desc:   "list firmware with invalid credentials",
+			token:  wrongValue,
+			offset: 0,
+			limit:  10,
+			size:   0,
+			err:    ota.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		page, err := svc.ListFirmware(context.Background(), tc.token, tc.offset, tc.limit)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.size, len(page.Firmware), fmt.Sprintf("%s: expected %d got %d\n", tc.desc, tc.size, len(page.Firmware)))
+	}
+}
+
+func TestRemoveFirmware(t *testing.T) {
+	svc, d := newService()
+	fw, err := svc.UploadFirmware(context.Background(), token, ota.Firmware{Name: "sensor", Version: "0.9.0"}, image)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	c := createCampaign(t, svc)
+
+	cases := []struct {
+		desc  string
+		token string
+		id    string
+		err   error
+	}{
+		{
+			desc:  "remove firmware of other user",
+			token: otherToken,
+			id:    fw.ID,
+			err:   ota.ErrNotFound,
+		},
+		{
+			desc:  "remove firmware with invalid credentials",
+			token: wrongValue,
+			id:    fw.ID,
+			err:   ota.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "remove firmware rolled out by campaign",
+			token: token,
+			id:    c.Firmware,
+			err:   ota.ErrConflict,
+		},
+		{
+			desc:  "remove existing firmware",
+			token: token,
+			id:    fw.ID,
+			err:   nil,
+		},
+		{
+			desc:  "remove removed firmware",
+			token: token,
+			id:    fw.ID,
+			err:   ota.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.RemoveFirmware(context.Background(), tc.token, tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	_, ok := d.storage.Object(fmt.Sprintf("firmware/%s", fw.ID))
+	assert.False(t, ok, "expected image of removed firmware to be removed")
+}
+
+func TestCreateCampaign(t *testing.T) {
+	svc, _ := newService()
+	fw, err := svc.UploadFirmware(context.Background(), token, firmware, image)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc     string
+		token    string
+		campaign ota.Campaign
+		group    string
+		err      error
+	}{
+		{
+			desc:     "create campaign",
+			token:    token,
+			campaign: ota.Campaign{Firmware: fw.ID, Group: groupChan, Channel: ctrlChan},
+			group:    groupChan,
+			err:      nil,
+		},
+		{
+			desc:     "create campaign without group",
+			token:    token,
+			campaign: ota.Campaign{Firmware: fw.ID, Channel: ctrlChan},
+			group:    ctrlChan,
+			err:      nil,
+		},
+		{
+			desc:     "create campaign without control channel",
+			token:    token,
+			campaign: ota.Campaign{Firmware: fw.ID, Group: groupChan},
+			err:      ota.ErrMalformedEntity,
+		},
+		{
+			desc:     "create campaign without firmware",
+			token:    token,
+			campaign: ota.Campaign{Group: groupChan, Channel: ctrlChan},
+			err:      ota.ErrMalformedEntity,
+		},
+		{
+			desc:     "create campaign of non-existing firmware",
+			token:    token,
+			campaign: ota.Campaign{Firmware: wrongValue, Channel: ctrlChan},
+			err:      ota.ErrNotFound,
+		},
+		{
+			desc:     "create campaign of other user's firmware",
+			token:    otherToken,
+			campaign: ota.Campaign{Firmware: fw.ID, Channel: otherChan},
+			err:      ota.ErrNotFound,
+		},
+		{
+			desc:     "create campaign with other user's group",
+			token:    token,
+			campaign: ota.Campaign{Firmware: fw.ID, Group: otherChan, Channel: ctrlChan},
+			err:      ota.ErrNotFound,
+		},
+		{
+			desc:     "create campaign with invalid credentials",
+			token:    wrongValue,
+			campaign: ota.Campaign{Firmware: fw.ID, Channel: ctrlChan},
+			err:      ota.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		c, err := svc.CreateCampaign(context.Background(), tc.token, tc.campaign)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err != nil {
+			continue
+		}
+		assert.Equal(t, ota.StateCreated, c.State, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, ota.StateCreated, c.State))
+		assert.Equal(t, tc.group, c.Group, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.group, c.Group))
+	}
+}
+
+func TestViewCampaign(t *testing.T) {
+	svc, _ := newService()
+	c := createCampaign(t, svc)
+
+	cases := []struct {
+		desc  string
+		token string
+		id    string
+		err   error
+	}{
+		{
+			desc:  "view existing campaign",
+			token: token,
+			id:    c.ID,
+			err:   nil,
+		},
+		{
+			desc:  "view campaign of other user",
+			token: otherToken,
+			id:    c.ID,
+			err:   ota.ErrNotFound,
+		},
+		{
+			desc:  "view non-existing campaign",
+			token: token,
+			id:    wrongValue,
+			err:   ota.ErrNotFound,
+		},
+		{
+			desc:  "view campaign with invalid credentials",
+			token: wrongValue,
+			id:    c.ID,
+			err:   ota.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := svc.ViewCampaign(context.Background(), tc.token, tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestListCampaigns(t *testing.T) {
+	svc, _ := newService()
+	c := createCampaign(t, svc)
+	for i := 0; i < 4; i++ {
+		_, err := svc.CreateCampaign(context.Background(), token, ota.Campaign{Firmware: c.Firmware, Channel: ctrlChan})
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	cases := []struct {
+		desc   string
+		token  string
+		offset uint64
+		limit  uint64
+		size   int
+		err    error
+	}{
+		{
+			desc:   "list all campaigns",
+			token:  token,
+			offset: 0,
+			limit:  10,
+			size:   5,
+			err:    nil,
+		},
+		{
+			desc:   "list campaigns page",
+			token:  token,
+			offset: 1,
+			limit:  2,
+			size:   2,
+			err:    nil,
+		},
+		{
+			desc:   "list campaigns of other user",
+			token:  otherToken,
+			offset: 0,
+			limit:  10,
+			size:   0,
+			err:    nil,
+		},
+		{
+			desc:   "list campaigns with invalid credentials",
+			token:  wrongValue,
+			offset: 0,
+			limit:  10,
+			size:   0,
+			err:    ota.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		page, err := svc.ListCampaigns(context.Background(), tc.token, tc.offset, tc.limit)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.size, len(page.Campaigns), fmt.Sprintf("%s: expected %d got %d\n", tc.desc, tc.size, len(page.Campaigns)))
+	}
+}
+
+func TestStartCampaign(t *testing.T) {
+	svc, d := newService()
+	c := createCampaign(t, svc)
+
+	cases := []struct {
+		desc  string
+		token string
+		id    string
+		err   error
+	}{
+		{
+			desc:  "start campaign of other user",
+			token: otherToken,
+			id:    c.ID,
+			err:   ota.ErrNotFound,
+		},
+		{
+			desc:  "start campaign with invalid credentials",
+			token: wrongValue,
+			id:    c.ID,
+			err:   ota.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "start campaign",
+			token: token,
+			id:    c.ID,
+			err:   nil,
+		},
+		{
+			desc:  "start running campaign",
+			token: token,
+			id:    c.ID,
+			err:   ota.ErrInvalidState,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.StartCampaign(context.Background(), tc.token, tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	// Only the things connected to both group and control channel are
+	// targeted.
+	msgs := d.publisher.Published()
+	require.Len(t, msgs, 2, fmt.Sprintf("expected 2 commands got %d", len(msgs)))
+	for i, thing := range []string{thing1, thing2} {
+		msg := msgs[i]
+		assert.Equal(t, ctrlChan, msg.Channel, fmt.Sprintf("expected channel %s got %s", ctrlChan, msg.Channel))
+		assert.Equal(t, fmt.Sprintf("%s.%s", ota.CommandSubtopic, thing), msg.Subtopic, fmt.Sprintf("unexpected subtopic %s", msg.Subtopic))
+
+		var cmd ota.Command
+		err := json.Unmarshal(msg.Payload, &cmd)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		assert.Equal(t, ota.OpUpdate, cmd.Operation, fmt.Sprintf("expected %s got %s", ota.OpUpdate, cmd.Operation))
+		assert.Equal(t, c.ID, cmd.Campaign, fmt.Sprintf("expected %s got %s", c.ID, cmd.Campaign))
+		url := fmt.Sprintf("http://storage/firmware/%s?ttl=%d", c.Firmware, int64(urlTTL/time.Second))
+		assert.Equal(t, url, cmd.URL, fmt.Sprintf("expected %s got %s", url, cmd.URL))
+	}
+
+	page, err := svc.ListUpdates(context.Background(), token, c.ID, 0, 10)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	require.Len(t, page.Updates, 2, fmt.Sprintf("expected 2 updates got %d", len(page.Updates)))
+	for _, u := range page.Updates {
+		assert.Equal(t, ota.StatusPending, u.Status, fmt.Sprintf("expected %s got %s", ota.StatusPending, u.Status))
+	}
+
+	c, err = svc.ViewCampaign(context.Background(), token, c.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, ota.StateRunning, c.State, fmt.Sprintf("expected %s got %s", ota.StateRunning, c.State))
+}
+
+func TestStartCampaignDeliveryFailure(t *testing.T) {
+	svc, d := newService()
+	c := createCampaign(t, svc)
+
+	d.publisher.Fail(errors.New("broker unavailable"))
+	err := svc.StartCampaign(context.Background(), token, c.ID)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	page, err := svc.ListUpdates(context.Background(), token, c.ID, 0, 10)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	for _, u := range page.Updates {
+		assert.Equal(t, ota.StatusFailed, u.Status, fmt.Sprintf("expected %s got %s", ota.StatusFailed, u.Status))
+		assert.NotEmpty(t, u.Message, "expected failure message")
+	}
+}
+
+func TestCancelCampaign(t *testing.T) {
+	svc, d := newService()
+	c := createCampaign(t, svc)
+	err := svc.StartCampaign(context.Background(), token, c.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = svc.ReportStatus(context.Background(), report(thing1, c.ID, ota.StatusSucceeded))
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		token string
+		id    string
+		err   error
+	}{
+		{
+			desc:  "cancel campaign of other user",
+			token: otherToken,
+			id:    c.ID,
+			err:   ota.ErrNotFound,
+		},
+		{
+			desc:  "cancel campaign",
+			token: token,
+			id:    c.ID,
+			err:   nil,
+		},
+		{
+			desc:  "cancel cancelled campaign",
+			token: token,
+			id:    c.ID,
+			err:   ota.ErrInvalidState,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.CancelCampaign(context.Background(), tc.token, tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	page, err := svc.ListUpdates(context.Background(), token, c.ID, 0, 10)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	statuses := map[string]string{}
+	for _, u := range page.Updates {
+		statuses[u.Thing] = u.Status
+	}
+	expected := map[string]string{thing1: ota.StatusSucceeded, thing2: ota.StatusCancelled}
+	assert.Equal(t, expected, statuses, fmt.Sprintf("expected %v got %v", expected, statuses))
+
+	// Finished update of the first thing is not cancelled.
+	msgs := d.publisher.Published()
+	last := msgs[len(msgs)-1]
+	assert.Equal(t, fmt.Sprintf("%s.%s", ota.CommandSubtopic, thing2), last.Subtopic, fmt.Sprintf("unexpected subtopic %s", last.Subtopic))
+	var cmd ota.Command
+	err = json.Unmarshal(last.Payload, &cmd)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, ota.OpCancel, cmd.Operation, fmt.Sprintf("expected %s got %s", ota.OpCancel, cmd.Operation))
+	assert.Len(t, msgs, 3, fmt.Sprintf("expected 3 messages got %d", len(msgs)))
+}
+
+func TestListUpdates(t *testing.T) {
+	svc, _ := newService()
+	c := createCampaign(t, svc)
+	err := svc.StartCampaign(context.Background(), token, c.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		token  string
+		id     string
+		offset uint64
+		limit  uint64
+		size   int
+		err    error
+	}{
+		{
+			desc:   "list all updates",
+			token:  token,
+			id:     c.ID,
+			offset: 0,
+			limit:  10,
+			size:   2,
+			err:    nil,
+		},
+		{
+			desc:   "list updates page",
+			token:  token,
+			id:     c.ID,
+			offset: 1,
+			limit:  10,
+			size:   1,
+			err:    nil,
+		},
+		{
+			desc:   "list updates of other user's campaign",
+			token:  otherToken,
+			id:     c.ID,
+			offset: 0,
+			limit:  10,
+			size:   0,
+			err:    ota.ErrNotFound,
+		},
+		{
+			desc:   "list updates with invalid credentials",
+			token:  wrongValue,
+			id:     c.ID,
+			offset: 0,
+			limit:  10,
+			size:   0,
+			err:    ota.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		page, err := svc.ListUpdates(context.Background(), tc.token, tc.id, tc.offset, tc.limit)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.size, len(page.Updates), fmt.Sprintf("%s: expected %d got %d\n", tc.desc, tc.size, len(page.Updates)))
+	}
+}
+
+func TestReportStatus(t *testing.T) {
+	svc, _ := newService()
+	c := createCampaign(t, svc)
+	created, err := svc.CreateCampaign(context.Background(), token, ota.Campaign{Firmware: c.Firmware, Channel: ctrlChan})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = svc.StartCampaign(context.Background(), token, c.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	otherChanMsg := report(thing1, c.ID, ota.StatusInstalling)
+	otherChanMsg.Channel = groupChan
+
+	cases := []struct {
+		desc string
+		msg  mainflux.RawMessage
+		err  error
+	}{
+		{
+			desc: "report downloading status",
+			msg:  report(thing1, c.ID, ota.StatusDownloading),
+			err:  nil,
+		},
+		{
+			desc: "report failed status",
+			msg:  report(thing2, c.ID, ota.StatusFailed),
+			err:  nil,
+		},
+		{
+			desc: "report status of finished update",
+			msg:  report(thing2, c.ID, ota.StatusSucceeded),
+			err:  ota.ErrInvalidState,
+		},
+		{
+			desc: "report status set by the service",
+			msg:  report(thing1, c.ID, ota.StatusCancelled),
+			err:  ota.ErrMalformedEntity,
+		},
+		{
+			desc: "report status over other channel",
+			msg:  otherChanMsg,
+			err:  ota.ErrNotFound,
+		},
+		{
+			desc: "report status of not targeted thing",
+			msg:  report(thing3, c.ID, ota.StatusInstalling),
+			err:  ota.ErrNotFound,
+		},
+		{
+			desc: "report status of campaign which isn't started",
+			msg:  report(thing1, created.ID, ota.StatusInstalling),
+			err:  ota.ErrInvalidState,
+		},
+		{
+			desc: "report status of non-existing campaign",
+			msg:  report(thing1, wrongValue, ota.StatusInstalling),
+			err:  ota.ErrNotFound,
+		},
+		{
+			desc: "report malformed status",
+			msg:  mainflux.RawMessage{Channel: ctrlChan, Publisher: thing1, Payload: []byte("{")},
+			err:  ota.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.ReportStatus(context.Background(), tc.msg)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	page, err := svc.ListUpdates(context.Background(), token, c.ID, 0, 10)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	expected := []string{ota.StatusDownloading, ota.StatusFailed}
+	for i, u := range page.Updates {
+		assert.Equal(t, expected[i], u.Status, fmt.Sprintf("expected %s got %s", expected[i], u.Status))
+	}
+}
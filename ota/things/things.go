@@ -0,0 +1,62 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package things contains the channels ownership check and the connected
+// things lookup backed by the Mainflux SDK.
+package things
+
+import (
+	"github.com/mainflux/mainflux/ota"
+	mfsdk "github.com/mainflux/mainflux/sdk/go"
+)
+
+const pageSize = 100
+
+var _ ota.Things = (*things)(nil)
+
+type things struct {
+	sdk mfsdk.SDK
+}
+
+// New returns things API client backed by the provided SDK. Since the
+// things service returns only the channels owned by the user, the channel
+// is owned if it can be retrieved.
+func New(sdk mfsdk.SDK) ota.Things {
+	return things{sdk: sdk}
+}
+
+func (t things) Authorize(token, chanID string) error {
+	_, err := t.sdk.Channel(chanID, token)
+	return toError(err)
+}
+
+func (t things) Connected(token, chanID string) ([]string, error) {
+	ids := []string{}
+	for offset := uint64(0); ; offset += pageSize {
+		page, err := t.sdk.ThingsByChannel(token, chanID, offset, pageSize)
+		if err != nil {
+			return nil, toError(err)
+		}
+
+		for _, th := range page.Things {
+			ids = append(ids, th.ID)
+		}
+
+		if len(page.Things) == 0 || offset+pageSize >= page.Total {
+			return ids, nil
+		}
+	}
+}
+
+func toError(err error) error {
+	switch err {
+	case nil:
+		return nil
+	case mfsdk.ErrUnauthorized:
+		return ota.ErrUnauthorizedAccess
+	case mfsdk.ErrNotFound:
+		return ota.ErrNotFound
+	default:
+		return err
+	}
+}
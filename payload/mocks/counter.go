@@ -0,0 +1,63 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/go-kit/kit/metrics"
+)
+
+var _ metrics.Counter = (*Counter)(nil)
+
+// Counter is the mock counter that keeps the counts per label values.
+type Counter struct {
+	mu     sync.Mutex
+	labels []string
+	counts map[string]float64
+}
+
+// NewCounter returns mock counter.
+func NewCounter() *Counter {
+	return &Counter{counts: make(map[string]float64)}
+}
+
+// With returns the counter with the given label values.
+func (c *Counter) With(labelValues ...string) metrics.Counter {
+	return &labeledCounter{parent: c, labels: append(append([]string{}, c.labels...), labelValues...)}
+}
+
+// Add increments the count of the counter without labels.
+func (c *Counter) Add(delta float64) {
+	c.add(nil, delta)
+}
+
+// Value returns the count for the given label values.
+func (c *Counter) Value(labelValues ...string) float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.counts[strings.Join(labelValues, ",")]
+}
+
+func (c *Counter) add(labels []string, delta float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.counts[strings.Join(labels, ",")] += delta
+}
+
+type labeledCounter struct {
+	parent *Counter
+	labels []string
+}
+
+func (lc *labeledCounter) With(labelValues ...string) metrics.Counter {
+	return &labeledCounter{parent: lc.parent, labels: append(append([]string{}, lc.labels...), labelValues...)}
+}
+
+func (lc *labeledCounter) Add(delta float64) {
+	lc.parent.add(lc.labels, delta)
+}
@@ -0,0 +1,50 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"sync"
+
+	"github.com/mainflux/mainflux/payload"
+)
+
+var _ payload.Store = (*Store)(nil)
+
+// Store is the mock object store that keeps the objects in memory.
+type Store struct {
+	mu      sync.Mutex
+	err     error
+	objects map[string][]byte
+}
+
+// NewStore returns mock object store which fails all the uploads with the
+// given error, if any.
+func NewStore(err error) *Store {
+	return &Store{
+		err:     err,
+		objects: make(map[string][]byte),
+	}
+}
+
+// Put stores the object.
+func (s *Store) Put(key, _, _ string, body []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return s.err
+	}
+	s.objects[key] = body
+
+	return nil
+}
+
+// Object returns the stored object.
+func (s *Store) Object(key string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	body, ok := s.objects[key]
+	return body, ok
+}
@@ -0,0 +1,172 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package payload contains the publisher decorator used by the adapters to
+// enforce the maximal message payload size, so that a single device sending
+// huge payloads can't overload the broker, the writers and the rules engine.
+// Oversized messages are rejected, truncated, or spilled to the object store,
+// in which case the message carries the reference to the stored object
+// instead of the payload.
+package payload
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/mainflux/mainflux"
+	log "github.com/mainflux/mainflux/logger"
+)
+
+// Policy defines the handling of the oversized messages.
+type Policy string
+
+const (
+	// Reject policy rejects the oversized messages.
+	Reject Policy = "reject"
+
+	// Truncate policy publishes the oversized messages with the payload cut
+	// to the maximal size. Truncated payloads are usually malformed, so the
+	// policy suits the opaque payloads only.
+	Truncate Policy = "truncate"
+
+	// Spill policy stores the payloads of the oversized messages to the
+	// object store, and publishes the messages with the reference to the
+	// stored object as the payload.
+	Spill Policy = "spill"
+
+	// SpilledContentType is the content type of the messages whose payload
+	// is spilled to the object store. The payload of such message is the
+	// JSON encoded Reference.
+	SpilledContentType = "application/vnd.mainflux.spilled+json"
+)
+
+var (
+	// ErrTooLarge indicates that the message payload exceeds the maximal
+	// size and is rejected.
+	ErrTooLarge = errors.New("message payload too large")
+
+	// ErrInvalidPolicy indicates that the oversized messages policy is
+	// unknown, or that spill policy is used without the object store.
+	ErrInvalidPolicy = errors.New("invalid oversized messages policy")
+)
+
+// Store specifies the object store the spilled payloads are stored to.
+type Store interface {
+	// Put stores the object under the given key.
+	Put(key, contentType, contentEncoding string, body []byte) error
+}
+
+// Reference refers to the spilled payload of the message.
+type Reference struct {
+	Object      string `json:"object"`
+	Size        int    `json:"size"`
+	ContentType string `json:"content_type,omitempty"`
+}
+
+// Config defines the maximal payload size in bytes, and the handling of the
+// messages exceeding it. Zero size disables the limit.
+type Config struct {
+	MaxSize int
+	Policy  Policy
+}
+
+// ParsePolicy parses the oversized messages policy.
+func ParsePolicy(s string) (Policy, error) {
+	switch p := Policy(strings.ToLower(strings.TrimSpace(s))); p {
+	case Reject, Truncate, Spill:
+		return p, nil
+	default:
+		return "", ErrInvalidPolicy
+	}
+}
+
+var _ mainflux.MessagePublisher = (*publisher)(nil)
+
+type publisher struct {
+	pub        mainflux.MessagePublisher
+	cfg        Config
+	store      Store
+	violations metrics.Counter
+	logger     log.Logger
+	seq        uint64
+}
+
+// New returns message publisher that applies the policy to the messages
+// whose payload exceeds the maximal size before passing them to the given
+// publisher. Violations are counted per policy. The store is used by the
+// spill policy only, and may be nil otherwise.
+func New(pub mainflux.MessagePublisher, cfg Config, store Store, violations metrics.Counter, logger log.Logger) (mainflux.MessagePublisher, error) {
+	switch cfg.Policy {
+	case Reject, Truncate:
+	case Spill:
+		if store == nil {
+			return nil, ErrInvalidPolicy
+		}
+	default:
+		return nil, ErrInvalidPolicy
+	}
+
+	if cfg.MaxSize <= 0 {
+		return pub, nil
+	}
+
+	return &publisher{
+		pub:        pub,
+		cfg:        cfg,
+		store:      store,
+		violations: violations,
+		logger:     logger,
+	}, nil
+}
+
+func (p *publisher) Publish(ctx context.Context, token string, msg mainflux.RawMessage) error {
+	size := len(msg.Payload)
+	if size <= p.cfg.MaxSize {
+		return p.pub.Publish(ctx, token, msg)
+	}
+
+	p.violations.With("policy", string(p.cfg.Policy)).Add(1)
+
+	switch p.cfg.Policy {
+	case Truncate:
+		p.logger.Warn(fmt.Sprintf("Truncated payload of %d bytes published by %s to channel %s", size, msg.Publisher, msg.Channel))
+		msg.Payload = msg.Payload[:p.cfg.MaxSize]
+	case Spill:
+		ref, err := p.spill(msg)
+		if err != nil {
+			p.logger.Warn(fmt.Sprintf("Failed to spill payload of %d bytes published by %s to channel %s: %s", size, msg.Publisher, msg.Channel, err))
+			return err
+		}
+		msg.Payload = ref
+		msg.ContentType = SpilledContentType
+	default:
+		p.logger.Warn(fmt.Sprintf("Rejected payload of %d bytes published by %s to channel %s", size, msg.Publisher, msg.Channel))
+		return ErrTooLarge
+	}
+
+	return p.pub.Publish(ctx, token, msg)
+}
+
+// spill stores the payload and returns the encoded reference to it. Objects
+// are grouped by channel, and the keys are unique within the adapter
+// instance thanks to the sequence suffix.
+func (p *publisher) spill(msg mainflux.RawMessage) ([]byte, error) {
+	key := fmt.Sprintf("spilled/%s/%d-%d", msg.Channel, time.Now().UnixNano(), atomic.AddUint64(&p.seq, 1))
+	if err := p.store.Put(key, msg.ContentType, "", msg.Payload); err != nil {
+		return nil, err
+	}
+
+	ref := Reference{
+		Object:      key,
+		Size:        len(msg.Payload),
+		ContentType: msg.ContentType,
+	}
+
+	return json.Marshal(ref)
+}
@@ -0,0 +1,212 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package payload_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/payload"
+	"github.com/mainflux/mainflux/payload/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	token       = "token"
+	maxSize     = 8
+	contentType = "application/senml+json"
+)
+
+var errStore = errors.New("object store unavailable")
+
+func newPublisher(t *testing.T, policy payload.Policy, store payload.Store) (mainflux.MessagePublisher, *mocks.Publisher, *mocks.Counter) {
+	logger, _ := logger.New(os.Stdout, logger.Info.String())
+	pub := mocks.NewPublisher()
+	counter := mocks.NewCounter()
+
+	svc, err := payload.New(pub, payload.Config{MaxSize: maxSize, Policy: policy}, store, counter, logger)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	return svc, pub, counter
+}
+
+func TestParsePolicy(t *testing.T) {
+	cases := []struct {
+		desc   string
+		value  string
+		policy payload.Policy
+		err    error
+	}{
+		{
+			desc:   "parse reject policy",
+			value:  "reject",
+			policy: payload.Reject,
+			err:    nil,
+		},
+		{
+			desc:   "parse truncate policy regardless of case",
+			value:  " Truncate ",
+			policy: payload.Truncate,
+			err:    nil,
+		},
+		{
+			desc:   "parse spill policy",
+			value:  "spill",
+			policy: payload.Spill,
+			err:    nil,
+		},
+		{
+			desc:   "parse unknown policy",
+			value:  "drop",
+			policy: "",
+			err:    payload.ErrInvalidPolicy,
+		},
+	}
+
+	for _, tc := range cases {
+		policy, err := payload.ParsePolicy(tc.value)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected error %s got %s", tc.desc, tc.err, err))
+		assert.Equal(t, tc.policy, policy, fmt.Sprintf("%s: expected policy %s got %s", tc.desc, tc.policy, policy))
+	}
+}
+
+func TestNew(t *testing.T) {
+	logger, _ := logger.New(os.Stdout, logger.Info.String())
+
+	cases := []struct {
+		desc   string
+		policy payload.Policy
+		store  payload.Store
+		err    error
+	}{
+		{
+			desc:   "create publisher with reject policy",
+			policy: payload.Reject,
+			store:  nil,
+			err:    nil,
+		},
+		{
+			desc:   "create publisher with spill policy",
+			policy: payload.Spill,
+			store:  mocks.NewStore(nil),
+			err:    nil,
+		},
+		{
+			desc:   "create publisher with spill policy without store",
+			policy: payload.Spill,
+			store:  nil,
+			err:    payload.ErrInvalidPolicy,
+		},
+		{
+			desc:   "create publisher with unknown policy",
+			policy: payload.Policy("drop"),
+			store:  nil,
+			err:    payload.ErrInvalidPolicy,
+		},
+	}
+
+	for _, tc := range cases {
+		cfg := payload.Config{MaxSize: maxSize, Policy: tc.policy}
+		_, err := payload.New(mocks.NewPublisher(), cfg, tc.store, mocks.NewCounter(), logger)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected error %s got %s", tc.desc, tc.err, err))
+	}
+}
+
+func TestPublish(t *testing.T) {
+	store := mocks.NewStore(nil)
+
+	cases := []struct {
+		desc       string
+		policy     payload.Policy
+		store      payload.Store
+		payload    string
+		err        error
+		published  string
+		violations float64
+	}{
+		{
+			desc:       "publish message within limit",
+			policy:     payload.Reject,
+			payload:    "12345678",
+			err:        nil,
+			published:  "12345678",
+			violations: 0,
+		},
+		{
+			desc:       "publish oversized message with reject policy",
+			policy:     payload.Reject,
+			payload:    "123456789",
+			err:        payload.ErrTooLarge,
+			published:  "",
+			violations: 1,
+		},
+		{
+			desc:       "publish oversized message with truncate policy",
+			policy:     payload.Truncate,
+			payload:    "123456789",
+			err:        nil,
+			published:  "12345678",
+			violations: 1,
+		},
+		{
+			desc:       "publish oversized message with spill policy and failing store",
+			policy:     payload.Spill,
+			store:      mocks.NewStore(errStore),
+			payload:    "123456789",
+			err:        errStore,
+			published:  "",
+			violations: 1,
+		},
+	}
+
+	for _, tc := range cases {
+		if tc.store == nil {
+			tc.store = store
+		}
+		svc, pub, counter := newPublisher(t, tc.policy, tc.store)
+
+		err := svc.Publish(context.Background(), token, mainflux.RawMessage{Channel: "1", Payload: []byte(tc.payload)})
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected error %s got %s", tc.desc, tc.err, err))
+
+		msgs := pub.Messages()
+		published := ""
+		if len(msgs) > 0 {
+			published = string(msgs[0].Payload)
+		}
+		assert.Equal(t, tc.published, published, fmt.Sprintf("%s: expected payload %s got %s", tc.desc, tc.published, published))
+
+		violations := counter.Value("policy", string(tc.policy))
+		assert.Equal(t, tc.violations, violations, fmt.Sprintf("%s: expected %v violations got %v", tc.desc, tc.violations, violations))
+	}
+}
+
+func TestPublishSpill(t *testing.T) {
+	store := mocks.NewStore(nil)
+	svc, pub, _ := newPublisher(t, payload.Spill, store)
+
+	msg := mainflux.RawMessage{Channel: "1", ContentType: contentType, Payload: []byte("123456789")}
+	err := svc.Publish(context.Background(), token, msg)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	msgs := pub.Messages()
+	require.Len(t, msgs, 1, "expected spilled message to be published")
+	assert.Equal(t, payload.SpilledContentType, msgs[0].ContentType, fmt.Sprintf("expected content type %s got %s", payload.SpilledContentType, msgs[0].ContentType))
+
+	var ref payload.Reference
+	err = json.Unmarshal(msgs[0].Payload, &ref)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, len(msg.Payload), ref.Size, fmt.Sprintf("expected size %d got %d", len(msg.Payload), ref.Size))
+	assert.Equal(t, contentType, ref.ContentType, fmt.Sprintf("expected content type %s got %s", contentType, ref.ContentType))
+
+	body, ok := store.Object(ref.Object)
+	assert.True(t, ok, fmt.Sprintf("expected object %s to be stored", ref.Object))
+	assert.Equal(t, msg.Payload, body, fmt.Sprintf("expected stored payload %s got %s", msg.Payload, body))
+}
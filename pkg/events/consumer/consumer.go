@@ -0,0 +1,252 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package consumer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/mainflux/mainflux/logger"
+)
+
+const (
+	defBatch = 100
+	defBlock = time.Second
+	defStart = "$"
+
+	// pendingPos is the position used to read the pending events of the
+	// consumer, while newPos is the position used to read the new events.
+	pendingPos = "0"
+	newPos     = ">"
+
+	exists = "BUSYGROUP Consumer Group name already exists"
+)
+
+// Handler handles the decoded event. The event is acknowledged only if the
+// handler returns nil, otherwise it's retried along with the events following
+// it. Handler which can't handle the event ever should return nil, in order
+// not to block the stream.
+type Handler func(context.Context, Event) error
+
+// Config defines the consumed stream and the consumer group. If the group
+// isn't set, the stream is read without the consumer group, so that each
+// consumer receives all the events, while the position isn't kept between
+// the restarts.
+type Config struct {
+	Stream   string
+	Group    string
+	Consumer string
+
+	// Start is the position the group starts reading from when it's
+	// created, or the consumer without the group starts reading from. It
+	// defaults to "$", which skips the existing events, while "0" replays
+	// the whole stream.
+	Start string
+
+	// Batch is the maximal number of events read at once. It defaults to
+	// 100.
+	Batch int64
+
+	// Block is the time the read waits for new events, as well as the
+	// delay before retrying failed events. It defaults to one second.
+	Block time.Duration
+}
+
+// Consumer consumes the event stream.
+type Consumer interface {
+	// Consume creates the consumer group unless it exists and passes the
+	// events to the handler until the context is canceled. Consumer without
+	// the group doesn't create any.
+	Consume(context.Context, Handler) error
+}
+
+type consumer struct {
+	client redis.UniversalClient
+	cfg    Config
+	logger logger.Logger
+}
+
+// New returns new stream consumer instance.
+func New(client redis.UniversalClient, cfg Config, logger logger.Logger) Consumer {
+	if cfg.Start == "" {
+		cfg.Start = defStart
+	}
+	if cfg.Batch <= 0 {
+		cfg.Batch = defBatch
+	}
+	if cfg.Block <= 0 {
+		cfg.Block = defBlock
+	}
+
+	return consumer{
+		client: client,
+		cfg:    cfg,
+		logger: logger,
+	}
+}
+
+func (c consumer) Consume(ctx context.Context, h Handler) error {
+	if c.cfg.Group == "" {
+		return c.consumeAll(ctx, h)
+	}
+
+	err := c.client.XGroupCreateMkStream(c.cfg.Stream, c.cfg.Group, c.cfg.Start).Err()
+	if err != nil && err.Error() != exists {
+		return err
+	}
+
+	// Pending events are read starting after the position of the last
+	// handled one, until there are no more of them.
+	pos := pendingPos
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		msgs, err := c.read(pos)
+		if err != nil {
+			c.logger.Warn(fmt.Sprintf("Failed to read %s stream: %s", c.cfg.Stream, err))
+			time.Sleep(c.cfg.Block)
+			continue
+		}
+
+		if pos != newPos && len(msgs) == 0 {
+			pos = newPos
+			continue
+		}
+
+		last, err := c.handle(ctx, h, msgs)
+		if err != nil {
+			c.logger.Warn(fmt.Sprintf("Failed to handle %s event: %s", c.cfg.Stream, err))
+			pos = pendingPos
+			time.Sleep(c.cfg.Block)
+			continue
+		}
+
+		if pos != newPos {
+			pos = last
+		}
+	}
+}
+
+// consumeAll reads the stream without the consumer group. The events are
+// retried starting after the position of the last handled one, which is kept
+// in memory only.
+func (c consumer) consumeAll(ctx context.Context, h Handler) error {
+	pos, err := c.start()
+	if err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		msgs, err := c.readAll(pos)
+		if err != nil {
+			c.logger.Warn(fmt.Sprintf("Failed to read %s stream: %s", c.cfg.Stream, err))
+			time.Sleep(c.cfg.Block)
+			continue
+		}
+
+		for _, msg := range msgs {
+			e, err := Decode(msg)
+			if err != nil {
+				c.logger.Warn(fmt.Sprintf("Skipping %s event %s: %s", c.cfg.Stream, msg.ID, err))
+			} else if err := h(ctx, e); err != nil {
+				c.logger.Warn(fmt.Sprintf("Failed to handle %s event: %s", c.cfg.Stream, err))
+				time.Sleep(c.cfg.Block)
+				break
+			}
+			pos = msg.ID
+		}
+	}
+}
+
+// start resolves the "$" start position to the ID of the last event, so that
+// the events added between the reads aren't skipped.
+func (c consumer) start() (string, error) {
+	if c.cfg.Start != defStart {
+		return c.cfg.Start, nil
+	}
+
+	msgs, err := c.client.XRevRangeN(c.cfg.Stream, "+", "-", 1).Result()
+	if err != nil {
+		return "", err
+	}
+	if len(msgs) == 0 {
+		return "0", nil
+	}
+
+	return msgs[0].ID, nil
+}
+
+func (c consumer) readAll(pos string) ([]redis.XMessage, error) {
+	streams, err := c.client.XRead(&redis.XReadArgs{
+		Streams: []string{c.cfg.Stream, pos},
+		Count:   c.cfg.Batch,
+		Block:   c.cfg.Block,
+	}).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(streams) == 0 {
+		return nil, nil
+	}
+
+	return streams[0].Messages, nil
+}
+
+func (c consumer) read(pos string) ([]redis.XMessage, error) {
+	streams, err := c.client.XReadGroup(&redis.XReadGroupArgs{
+		Group:    c.cfg.Group,
+		Consumer: c.cfg.Consumer,
+		Streams:  []string{c.cfg.Stream, pos},
+		Count:    c.cfg.Batch,
+		Block:    c.cfg.Block,
+	}).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(streams) == 0 {
+		return nil, nil
+	}
+
+	return streams[0].Messages, nil
+}
+
+// handle passes the events to the handler and returns the ID of the last
+// acknowledged one. Malformed events are acknowledged without handling, since
+// they can't be handled on retry either.
+func (c consumer) handle(ctx context.Context, h Handler, msgs []redis.XMessage) (string, error) {
+	var last string
+	for _, msg := range msgs {
+		e, err := Decode(msg)
+		if err != nil {
+			c.logger.Warn(fmt.Sprintf("Skipping %s event %s: %s", c.cfg.Stream, msg.ID, err))
+		} else if err := h(ctx, e); err != nil {
+			return last, err
+		}
+
+		if err := c.client.XAck(c.cfg.Stream, c.cfg.Group, msg.ID).Err(); err != nil {
+			return last, err
+		}
+		last = msg.ID
+	}
+
+	return last, nil
+}
@@ -0,0 +1,127 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package consumer_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/pkg/events/consumer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	stream = "mainflux.test"
+	group  = "mainflux.test.consumer"
+)
+
+var errHandle = errors.New("failed to handle event")
+
+func add(t *testing.T, values map[string]interface{}) {
+	err := redisClient.XAdd(&redis.XAddArgs{
+		Stream: stream,
+		Values: values,
+	}).Err()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+}
+
+func TestConsume(t *testing.T) {
+	redisClient.FlushAll()
+	log, err := logger.New(ioutil.Discard, "info")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	add(t, map[string]interface{}{"operation": consumer.ThingCreate, "id": "1"})
+	add(t, map[string]interface{}{"id": "2"})
+	add(t, map[string]interface{}{"operation": consumer.ThingRemove, "id": "1"})
+
+	c := consumer.New(redisClient, consumer.Config{
+		Stream:   stream,
+		Group:    group,
+		Consumer: "c1",
+		Start:    "0",
+		Block:    100 * time.Millisecond,
+	}, log)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// The first attempt of the remove event fails, so it's retried, while
+	// the malformed event is skipped.
+	failed := false
+	ops := []string{}
+	err = c.Consume(ctx, func(_ context.Context, e consumer.Event) error {
+		if e.Operation == consumer.ThingRemove && !failed {
+			failed = true
+			return errHandle
+		}
+
+		ops = append(ops, e.Operation)
+		if len(ops) == 2 {
+			cancel()
+		}
+		return nil
+	})
+	assert.Equal(t, context.Canceled, err, fmt.Sprintf("expected %s got %s\n", context.Canceled, err))
+
+	expected := []string{consumer.ThingCreate, consumer.ThingRemove}
+	assert.Equal(t, expected, ops, fmt.Sprintf("expected %v got %v\n", expected, ops))
+
+	pending, err := redisClient.XPending(stream, group).Result()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, int64(0), pending.Count, fmt.Sprintf("expected no pending events got %d\n", pending.Count))
+}
+
+func TestConsumeWithoutGroup(t *testing.T) {
+	redisClient.FlushAll()
+	log, err := logger.New(ioutil.Discard, "info")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	add(t, map[string]interface{}{"operation": consumer.ThingCreate, "id": "1"})
+
+	c := consumer.New(redisClient, consumer.Config{
+		Stream: stream,
+		Block:  100 * time.Millisecond,
+	}, log)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// The existing event is skipped, while the events added after the
+	// consumer starts are received, including the retried one.
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		add(t, map[string]interface{}{"operation": consumer.ThingUpdate, "id": "1"})
+		add(t, map[string]interface{}{"operation": consumer.ThingRemove, "id": "1"})
+	}()
+
+	failed := false
+	ops := []string{}
+	err = c.Consume(ctx, func(_ context.Context, e consumer.Event) error {
+		if e.Operation == consumer.ThingRemove && !failed {
+			failed = true
+			return errHandle
+		}
+
+		ops = append(ops, e.Operation)
+		if len(ops) == 2 {
+			cancel()
+		}
+		return nil
+	})
+	assert.Equal(t, context.Canceled, err, fmt.Sprintf("expected %s got %s\n", context.Canceled, err))
+
+	expected := []string{consumer.ThingUpdate, consumer.ThingRemove}
+	assert.Equal(t, expected, ops, fmt.Sprintf("expected %v got %v\n", expected, ops))
+
+	groups, err := redisClient.Do("XINFO", "GROUPS", stream).Result()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Empty(t, groups, fmt.Sprintf("expected no consumer groups got %v\n", groups))
+}
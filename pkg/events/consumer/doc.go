@@ -0,0 +1,36 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package consumer contains the consumer of the Mainflux event streams. It
+// reads the Redis stream using the consumer group, decodes the events of the
+// things service and acknowledges each event once it's handled, so that the
+// integrations don't have to implement the stream reading loop themselves.
+//
+// The consumer group keeps the position of the last event read by the group,
+// while the events which were read but not acknowledged are kept pending. On
+// start, the consumer handles its pending events before reading the new ones,
+// so the events read before the restart aren't lost. Consumers sharing the
+// group split the events between themselves. Consumers which keep the
+// process-local state, such as the in-memory caches, read the stream without
+// the group instead, so that each of them receives all the events and no
+// group is left behind once the process exits.
+//
+// Users service doesn't publish events, so only the things service events are
+// decoded. Events of the other streams are delivered with the operation and
+// the raw fields only.
+//
+// Example:
+//
+//	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+//	c := consumer.New(client, consumer.Config{
+//		Stream:   consumer.ThingsStream,
+//		Group:    "inventory",
+//		Consumer: "inventory-1",
+//	}, logger)
+//	err := c.Consume(ctx, func(ctx context.Context, e consumer.Event) error {
+//		if e.Operation == consumer.ThingCreate {
+//			// Handle the created thing.
+//		}
+//		return nil
+//	})
+package consumer
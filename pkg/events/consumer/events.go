@@ -0,0 +1,167 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package consumer
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+// ThingsStream is the stream of the things service events.
+const ThingsStream = "mainflux.things"
+
+// Operations of the things service events.
+const (
+	ThingCreate     = "thing.create"
+	ThingUpdate     = "thing.update"
+	ThingRemove     = "thing.remove"
+	ThingUpdateKey  = "thing.update_key"
+	ThingConnect    = "thing.connect"
+	ThingDisconnect = "thing.disconnect"
+	ChannelCreate   = "channel.create"
+	ChannelUpdate   = "channel.update"
+	ChannelRemove   = "channel.remove"
+	OwnerTransfer   = "owner.transfer"
+)
+
+// ErrMalformedEvent indicates the event which can't be decoded.
+var ErrMalformedEvent = errors.New("malformed event")
+
+// Event represents the decoded event. Depending on the operation, either
+// thing, channel, connection or transfer is set. Update events contain only the updated
+// fields.
+type Event struct {
+	// ID is the stream entry ID, which is also the position of the event
+	// in the stream.
+	ID        string
+	Time      time.Time
+	Version   string
+	Operation string
+	Owner     string
+
+	Thing      *Thing
+	Channel    *Channel
+	Connection *Connection
+	Transfer   *Transfer
+
+	// Fields contains all the raw event fields, including the ones which
+	// aren't decoded.
+	Fields map[string]string
+}
+
+// Thing represents the thing of the thing event.
+type Thing struct {
+	ID       string
+	Name     string
+	Metadata map[string]interface{}
+}
+
+// Channel represents the channel of the channel event.
+type Channel struct {
+	ID       string
+	Name     string
+	Metadata map[string]interface{}
+}
+
+// Transfer represents the ownership transfer of the transfer event. The new
+// owner is the owner of the event.
+type Transfer struct {
+	PreviousOwner string
+}
+
+// Connection represents the connection of the connect or disconnect event.
+type Connection struct {
+	ChannelID string
+	ThingID   string
+	Actions   []string
+}
+
+// Decode decodes the stream entry. It returns ErrMalformedEvent if the event
+// lacks the operation or its metadata isn't valid JSON. Events of the unknown
+// operations are decoded without the entity.
+func Decode(msg redis.XMessage) (Event, error) {
+	e := Event{
+		ID:     msg.ID,
+		Time:   entryTime(msg.ID),
+		Fields: make(map[string]string),
+	}
+	for k, v := range msg.Values {
+		val, _ := v.(string)
+		e.Fields[k] = val
+	}
+
+	e.Operation = e.Fields["operation"]
+	e.Version = e.Fields["version"]
+	e.Owner = e.Fields["owner"]
+	if e.Operation == "" {
+		return Event{}, ErrMalformedEvent
+	}
+
+	switch e.Operation {
+	case ThingCreate, ThingUpdate, ThingRemove, ThingUpdateKey:
+		metadata, err := decodeMetadata(e.Fields)
+		if err != nil {
+			return Event{}, err
+		}
+		e.Thing = &Thing{
+			ID:       e.Fields["id"],
+			Name:     e.Fields["name"],
+			Metadata: metadata,
+		}
+	case ChannelCreate, ChannelUpdate, ChannelRemove:
+		metadata, err := decodeMetadata(e.Fields)
+		if err != nil {
+			return Event{}, err
+		}
+		e.Channel = &Channel{
+			ID:       e.Fields["id"],
+			Name:     e.Fields["name"],
+			Metadata: metadata,
+		}
+	case ThingConnect, ThingDisconnect:
+		e.Connection = &Connection{
+			ChannelID: e.Fields["chan_id"],
+			ThingID:   e.Fields["thing_id"],
+		}
+		if actions := e.Fields["actions"]; actions != "" {
+			e.Connection.Actions = strings.Split(actions, ",")
+		}
+	case OwnerTransfer:
+		e.Transfer = &Transfer{
+			PreviousOwner: e.Fields["previous_owner"],
+		}
+	}
+
+	return e, nil
+}
+
+func decodeMetadata(fields map[string]string) (map[string]interface{}, error) {
+	raw, ok := fields["metadata"]
+	if !ok {
+		return nil, nil
+	}
+
+	var metadata map[string]interface{}
+	if err := json.Unmarshal([]byte(raw), &metadata); err != nil {
+		return nil, ErrMalformedEvent
+	}
+
+	return metadata, nil
+}
+
+// entryTime returns the time of the stream entry, which is the milliseconds
+// part of its ID.
+func entryTime(id string) time.Time {
+	ms, err := strconv.ParseInt(strings.Split(id, "-")[0], 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return time.Unix(0, ms*int64(time.Millisecond))
+}
@@ -0,0 +1,174 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package consumer_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/mainflux/mainflux/pkg/events/consumer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecode(t *testing.T) {
+	cases := []struct {
+		desc  string
+		msg   redis.XMessage
+		event consumer.Event
+		err   error
+	}{
+		{
+			desc: "decode thing create event",
+			msg: redis.XMessage{
+				ID: "1555334740911-0",
+				Values: map[string]interface{}{
+					"operation": consumer.ThingCreate,
+					"version":   "1",
+					"id":        "1",
+					"owner":     "user@example.com",
+					"name":      "a",
+					"metadata":  `{"type":"sensor"}`,
+				},
+			},
+			event: consumer.Event{
+				ID:        "1555334740911-0",
+				Time:      time.Unix(0, 1555334740911*int64(time.Millisecond)),
+				Version:   "1",
+				Operation: consumer.ThingCreate,
+				Owner:     "user@example.com",
+				Thing:     &consumer.Thing{ID: "1", Name: "a", Metadata: map[string]interface{}{"type": "sensor"}},
+				Fields: map[string]string{
+					"operation": consumer.ThingCreate,
+					"version":   "1",
+					"id":        "1",
+					"owner":     "user@example.com",
+					"name":      "a",
+					"metadata":  `{"type":"sensor"}`,
+				},
+			},
+			err: nil,
+		},
+		{
+			desc: "decode thing key update event",
+			msg: redis.XMessage{
+				ID: "1555334740911-1",
+				Values: map[string]interface{}{
+					"operation": consumer.ThingUpdateKey,
+					"id":        "1",
+				},
+			},
+			event: consumer.Event{
+				ID:        "1555334740911-1",
+				Time:      time.Unix(0, 1555334740911*int64(time.Millisecond)),
+				Operation: consumer.ThingUpdateKey,
+				Thing:     &consumer.Thing{ID: "1"},
+				Fields: map[string]string{
+					"operation": consumer.ThingUpdateKey,
+					"id":        "1",
+				},
+			},
+			err: nil,
+		},
+		{
+			desc: "decode ownership transfer event",
+			msg: redis.XMessage{
+				ID: "1555334740911-2",
+				Values: map[string]interface{}{
+					"operation":      consumer.OwnerTransfer,
+					"owner":          "new@example.com",
+					"previous_owner": "user@example.com",
+				},
+			},
+			event: consumer.Event{
+				ID:        "1555334740911-2",
+				Time:      time.Unix(0, 1555334740911*int64(time.Millisecond)),
+				Operation: consumer.OwnerTransfer,
+				Owner:     "new@example.com",
+				Transfer:  &consumer.Transfer{PreviousOwner: "user@example.com"},
+				Fields: map[string]string{
+					"operation":      consumer.OwnerTransfer,
+					"owner":          "new@example.com",
+					"previous_owner": "user@example.com",
+				},
+			},
+			err: nil,
+		},
+		{
+			desc: "decode channel remove event",
+			msg: redis.XMessage{
+				ID: "1555334740912-0",
+				Values: map[string]interface{}{
+					"operation": consumer.ChannelRemove,
+					"id":        "2",
+				},
+			},
+			event: consumer.Event{
+				ID:        "1555334740912-0",
+				Time:      time.Unix(0, 1555334740912*int64(time.Millisecond)),
+				Operation: consumer.ChannelRemove,
+				Channel:   &consumer.Channel{ID: "2"},
+				Fields: map[string]string{
+					"operation": consumer.ChannelRemove,
+					"id":        "2",
+				},
+			},
+			err: nil,
+		},
+		{
+			desc: "decode connect event",
+			msg: redis.XMessage{
+				ID: "1555334740913-0",
+				Values: map[string]interface{}{
+					"operation": consumer.ThingConnect,
+					"chan_id":   "2",
+					"thing_id":  "1",
+					"actions":   "publish,subscribe",
+				},
+			},
+			event: consumer.Event{
+				ID:         "1555334740913-0",
+				Time:       time.Unix(0, 1555334740913*int64(time.Millisecond)),
+				Operation:  consumer.ThingConnect,
+				Connection: &consumer.Connection{ChannelID: "2", ThingID: "1", Actions: []string{"publish", "subscribe"}},
+				Fields: map[string]string{
+					"operation": consumer.ThingConnect,
+					"chan_id":   "2",
+					"thing_id":  "1",
+					"actions":   "publish,subscribe",
+				},
+			},
+			err: nil,
+		},
+		{
+			desc: "decode event without operation",
+			msg: redis.XMessage{
+				ID:     "1555334740914-0",
+				Values: map[string]interface{}{"id": "1"},
+			},
+			event: consumer.Event{},
+			err:   consumer.ErrMalformedEvent,
+		},
+		{
+			desc: "decode event with malformed metadata",
+			msg: redis.XMessage{
+				ID: "1555334740915-0",
+				Values: map[string]interface{}{
+					"operation": consumer.ThingUpdate,
+					"id":        "1",
+					"metadata":  "{",
+				},
+			},
+			event: consumer.Event{},
+			err:   consumer.ErrMalformedEvent,
+		},
+	}
+
+	for _, tc := range cases {
+		event, err := consumer.Decode(tc.msg)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.event, event, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.event, event))
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/mainflux/mainflux/presence"
+)
+
+func viewStatusEndpoint(svc presence.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewStatusReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		status, err := svc.ViewStatus(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		return toStatusRes(status), nil
+	}
+}
+
+func listConnectedEndpoint(svc presence.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewStatusReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		statuses, err := svc.ListConnected(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		res := connectedRes{Things: []statusRes{}}
+		for _, s := range statuses {
+			res.Things = append(res.Things, toStatusRes(s))
+		}
+
+		return res, nil
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// +build !test
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/presence"
+)
+
+var _ presence.Service = (*loggingMiddleware)(nil)
+
+type loggingMiddleware struct {
+	logger log.Logger
+	svc    presence.Service
+}
+
+// LoggingMiddleware adds logging facilities to the core service.
+func LoggingMiddleware(svc presence.Service, logger log.Logger) presence.Service {
+	return &loggingMiddleware{logger, svc}
+}
+
+func (lm *loggingMiddleware) Record(ctx context.Context, e presence.Event) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method record for %s %s event of thing %s took %s to complete", e.Protocol, e.Type, e.Thing, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Record(ctx, e)
+}
+
+func (lm *loggingMiddleware) ViewStatus(ctx context.Context, token, id string) (status presence.Status, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method view_status for token %s and thing %s took %s to complete", token, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ViewStatus(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) ListConnected(ctx context.Context, token, chanID string) (statuses []presence.Status, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_connected for token %s and channel %s took %s to complete", token, chanID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListConnected(ctx, token, chanID)
+}
@@ -0,0 +1,59 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// +build !test
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/mainflux/mainflux/presence"
+)
+
+var _ presence.Service = (*metricsMiddleware)(nil)
+
+type metricsMiddleware struct {
+	counter metrics.Counter
+	latency metrics.Histogram
+	svc     presence.Service
+}
+
+// MetricsMiddleware instruments core service by tracking request count and
+// latency.
+func MetricsMiddleware(svc presence.Service, counter metrics.Counter, latency metrics.Histogram) presence.Service {
+	return &metricsMiddleware{
+		counter: counter,
+		latency: latency,
+		svc:     svc,
+	}
+}
+
+func (ms *metricsMiddleware) Record(ctx context.Context, e presence.Event) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "record").Add(1)
+		ms.latency.With("method", "record").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Record(ctx, e)
+}
+
+func (ms *metricsMiddleware) ViewStatus(ctx context.Context, token, id string) (presence.Status, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_status").Add(1)
+		ms.latency.With("method", "view_status").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewStatus(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) ListConnected(ctx context.Context, token, chanID string) ([]presence.Status, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_connected").Add(1)
+		ms.latency.With("method", "list_connected").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListConnected(ctx, token, chanID)
+}
@@ -0,0 +1,23 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import "github.com/mainflux/mainflux/presence"
+
+type viewStatusReq struct {
+	token string
+	id    string
+}
+
+func (req viewStatusReq) validate() error {
+	if req.token == "" {
+		return presence.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" {
+		return presence.ErrMalformedEntity
+	}
+
+	return nil
+}
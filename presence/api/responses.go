@@ -0,0 +1,74 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/presence"
+)
+
+const (
+	online  = "online"
+	offline = "offline"
+)
+
+var (
+	_ mainflux.Response = (*statusRes)(nil)
+	_ mainflux.Response = (*connectedRes)(nil)
+)
+
+type statusRes struct {
+	ThingID     string     `json:"thing_id"`
+	Status      string     `json:"status"`
+	Connections int        `json:"connections"`
+	LastSeen    *time.Time `json:"last_seen,omitempty"`
+}
+
+func (res statusRes) Code() int {
+	return http.StatusOK
+}
+
+func (res statusRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res statusRes) Empty() bool {
+	return false
+}
+
+type connectedRes struct {
+	Things []statusRes `json:"things"`
+}
+
+func (res connectedRes) Code() int {
+	return http.StatusOK
+}
+
+func (res connectedRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res connectedRes) Empty() bool {
+	return false
+}
+
+func toStatusRes(s presence.Status) statusRes {
+	res := statusRes{
+		ThingID:     s.Thing,
+		Status:      offline,
+		Connections: s.Connections,
+	}
+	if s.Online {
+		res.Status = online
+	}
+	if !s.LastSeen.IsZero() {
+		lastSeen := s.LastSeen.UTC()
+		res.LastSeen = &lastSeen
+	}
+
+	return res
+}
@@ -0,0 +1,9 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package presence contains the domain concept definitions needed to support
+// Mainflux presence service functionality. Presence service records the
+// connect and disconnect events of the things published by the protocol
+// adapters, and tells whether the thing is currently online, how many
+// connections it has open and when it was last seen.
+package presence
@@ -0,0 +1,40 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"sync"
+
+	"github.com/mainflux/mainflux/presence"
+)
+
+// EventPublisher is the event publisher mock which keeps the published events.
+type EventPublisher struct {
+	mu     sync.Mutex
+	events []presence.Event
+}
+
+var _ presence.EventPublisher = (*EventPublisher)(nil)
+
+// NewEventPublisher returns event publisher mock.
+func NewEventPublisher() *EventPublisher {
+	return &EventPublisher{}
+}
+
+// Publish keeps the published event.
+func (ep *EventPublisher) Publish(e presence.Event) error {
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+
+	ep.events = append(ep.events, e)
+	return nil
+}
+
+// Events returns the published events, in the publishing order.
+func (ep *EventPublisher) Events() []presence.Event {
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+
+	return append([]presence.Event{}, ep.events...)
+}
@@ -0,0 +1,69 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/mainflux/mainflux/presence"
+)
+
+var _ presence.Repository = (*repositoryMock)(nil)
+
+type repositoryMock struct {
+	mu       sync.Mutex
+	sessions map[string]map[string]bool
+	lastSeen map[string]time.Time
+}
+
+// NewRepository returns presence repository mock.
+func NewRepository() presence.Repository {
+	return &repositoryMock{
+		sessions: map[string]map[string]bool{},
+		lastSeen: map[string]time.Time{},
+	}
+}
+
+func (rm *repositoryMock) Save(_ context.Context, e presence.Event) error {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	if _, ok := rm.sessions[e.Thing]; !ok {
+		rm.sessions[e.Thing] = map[string]bool{}
+	}
+
+	session := e.Protocol + ":" + e.Session
+	switch e.Type {
+	case presence.Connect:
+		rm.sessions[e.Thing][session] = true
+	case presence.Disconnect:
+		delete(rm.sessions[e.Thing], session)
+	}
+
+	if e.Time.After(rm.lastSeen[e.Thing]) {
+		rm.lastSeen[e.Thing] = e.Time
+	}
+
+	return nil
+}
+
+func (rm *repositoryMock) RetrieveAll(_ context.Context, ids ...string) ([]presence.Status, error) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	statuses := make([]presence.Status, len(ids))
+	for i, id := range ids {
+		conns := len(rm.sessions[id])
+		statuses[i] = presence.Status{
+			Thing:       id,
+			Online:      conns > 0,
+			Connections: conns,
+			LastSeen:    rm.lastSeen[id],
+		}
+	}
+
+	return statuses, nil
+}
@@ -0,0 +1,58 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import "github.com/mainflux/mainflux/presence"
+
+var _ presence.Things = (*thingsMock)(nil)
+
+type thingsMock struct {
+	things   map[string]string
+	channels map[string][]string
+}
+
+// NewThings returns things API mock. Things are mapped to the tokens of their
+// owners, and channels to the IDs of their connected things.
+func NewThings(things map[string]string, channels map[string][]string) presence.Things {
+	return thingsMock{
+		things:   things,
+		channels: channels,
+	}
+}
+
+func (tm thingsMock) Thing(token, id string) error {
+	if token == "" {
+		return presence.ErrUnauthorizedAccess
+	}
+
+	owner, ok := tm.things[id]
+	if !ok || owner != token {
+		return presence.ErrNotFound
+	}
+
+	return nil
+}
+
+func (tm thingsMock) ChannelThings(token, chanID string) ([]string, error) {
+	if token == "" {
+		return nil, presence.ErrUnauthorizedAccess
+	}
+
+	ids, ok := tm.channels[chanID]
+	if !ok {
+		return nil, presence.ErrNotFound
+	}
+
+	owned := []string{}
+	for _, id := range ids {
+		if tm.things[id] == token {
+			owned = append(owned, id)
+		}
+	}
+	if len(owned) == 0 {
+		return nil, presence.ErrNotFound
+	}
+
+	return owned, nil
+}
@@ -0,0 +1,82 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package presence
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// Connect event is published when the thing opens the connection.
+	Connect = "connect"
+	// Disconnect event is published when the connection of the thing is
+	// closed.
+	Disconnect = "disconnect"
+)
+
+// Event represents the connect or disconnect event of the thing. Session
+// identifies the connection within the protocol, so that the disconnect is
+// matched with the connect of the same connection.
+type Event struct {
+	Thing    string
+	Channel  string
+	Session  string
+	Protocol string
+	Type     string
+	Time     time.Time
+}
+
+// Validate returns an error if event is not well-formed.
+func (e Event) Validate() error {
+	if e.Thing == "" || e.Session == "" || e.Protocol == "" {
+		return ErrMalformedEntity
+	}
+
+	if e.Type != Connect && e.Type != Disconnect {
+		return ErrMalformedEntity
+	}
+
+	return nil
+}
+
+// Status represents the presence of the thing. Thing is online while it has
+// at least one open connection. Last seen time is the time of the latest
+// connect or disconnect event of the thing, and is zero if there was none.
+type Status struct {
+	Thing       string
+	Online      bool
+	Connections int
+	LastSeen    time.Time
+}
+
+// Repository specifies the presence persistence API.
+type Repository interface {
+	// Save applies the event to the open connections and the last seen time
+	// of the thing.
+	Save(context.Context, Event) error
+
+	// RetrieveAll retrieves the statuses of the things identified by the
+	// provided IDs, in the same order.
+	RetrieveAll(context.Context, ...string) ([]Status, error)
+}
+
+// Things specifies an API for retrieving the things of the user.
+type Things interface {
+	// Thing checks whether the thing identified by the provided ID belongs
+	// to the user identified by the provided key.
+	Thing(string, string) error
+
+	// ChannelThings retrieves the IDs of the things connected to the channel
+	// identified by the provided ID, that belongs to the user identified by
+	// the provided key.
+	ChannelThings(string, string) ([]string, error)
+}
+
+// EventPublisher specifies an API for publishing the connectivity events of
+// the protocol adapter.
+type EventPublisher interface {
+	// Publish publishes the event.
+	Publish(Event) error
+}
@@ -0,0 +1,125 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package consumer
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/presence"
+)
+
+const (
+	group = "mainflux.presence"
+
+	exists = "BUSYGROUP Consumer Group name already exists"
+)
+
+// Presence event types, by the event types of the adapters. VerneMQ adapter
+// publishes register and deregister events instead of connect and disconnect.
+var eventTypes = map[string]string{
+	"connect":    presence.Connect,
+	"register":   presence.Connect,
+	"disconnect": presence.Disconnect,
+	"deregister": presence.Disconnect,
+}
+
+// Streams of the adapters' connectivity events, by the adapter protocol.
+var streams = map[string]string{
+	"mainflux.mqtt": "mqtt",
+	"mainflux.ws":   "ws",
+	"mainflux.coap": "coap",
+}
+
+// EventStore represents event source for the connectivity events.
+type EventStore interface {
+	// Subscribe consumes the connectivity events of all the adapters.
+	Subscribe() error
+}
+
+type eventStore struct {
+	svc      presence.Service
+	client   redis.UniversalClient
+	consumer string
+	logger   logger.Logger
+}
+
+// NewEventStore returns new event store instance.
+func NewEventStore(svc presence.Service, client redis.UniversalClient, consumer string, log logger.Logger) EventStore {
+	return eventStore{
+		svc:      svc,
+		client:   client,
+		consumer: consumer,
+		logger:   log,
+	}
+}
+
+func (es eventStore) Subscribe() error {
+	args := []string{}
+	for stream := range streams {
+		err := es.client.XGroupCreateMkStream(stream, group, "$").Err()
+		if err != nil && err.Error() != exists {
+			return err
+		}
+		args = append(args, stream)
+	}
+	for range streams {
+		args = append(args, ">")
+	}
+
+	for {
+		res, err := es.client.XReadGroup(&redis.XReadGroupArgs{
+			Group:    group,
+			Consumer: es.consumer,
+			Streams:  args,
+			Count:    100,
+		}).Result()
+		if err != nil || len(res) == 0 {
+			continue
+		}
+
+		for _, stream := range res {
+			for _, msg := range stream.Messages {
+				e := decodeEvent(streams[stream.Stream], msg.Values)
+				if err := es.svc.Record(context.Background(), e); err != nil {
+					es.logger.Warn(fmt.Sprintf("Failed to handle %s event: %s", stream.Stream, err))
+				}
+				es.client.XAck(stream.Stream, group, msg.ID)
+			}
+		}
+	}
+}
+
+func decodeEvent(protocol string, event map[string]interface{}) presence.Event {
+	ts, err := strconv.ParseInt(read(event, "timestamp", ""), 10, 64)
+	if err != nil {
+		ts = time.Now().Unix()
+	}
+
+	// Adapters which don't identify the connections are tracked by their
+	// instances.
+	instance := read(event, "instance", "")
+
+	return presence.Event{
+		Thing:    read(event, "thing_id", ""),
+		Channel:  read(event, "channel_id", ""),
+		Session:  read(event, "session_id", instance),
+		Protocol: protocol,
+		Type:     eventTypes[read(event, "event_type", "")],
+		Time:     time.Unix(ts, 0),
+	}
+}
+
+func read(event map[string]interface{}, key, def string) string {
+	val, ok := event[key].(string)
+	if !ok || val == "" {
+		return def
+	}
+
+	return val
+}
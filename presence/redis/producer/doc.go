@@ -0,0 +1,6 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package producer contains the event publisher which publishes the
+// connectivity events of the protocol adapters to the Redis stream.
+package producer
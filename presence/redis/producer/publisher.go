@@ -0,0 +1,44 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package producer
+
+import (
+	"github.com/go-redis/redis"
+	"github.com/mainflux/mainflux/presence"
+)
+
+const streamLen = 10000
+
+var _ presence.EventPublisher = (*publisher)(nil)
+
+type publisher struct {
+	client redis.UniversalClient
+	stream string
+}
+
+// NewPublisher returns event publisher which adds the events to the stream,
+// in the same format as the events of the MQTT adapter. The stream is capped
+// to the approximate length, since the events are consumed as they come.
+func NewPublisher(client redis.UniversalClient, stream string) presence.EventPublisher {
+	return publisher{
+		client: client,
+		stream: stream,
+	}
+}
+
+func (p publisher) Publish(e presence.Event) error {
+	record := &redis.XAddArgs{
+		Stream:       p.stream,
+		MaxLenApprox: streamLen,
+		Values: map[string]interface{}{
+			"thing_id":   e.Thing,
+			"channel_id": e.Channel,
+			"session_id": e.Session,
+			"timestamp":  e.Time.Unix(),
+			"event_type": e.Type,
+		},
+	}
+
+	return p.client.XAdd(record).Err()
+}
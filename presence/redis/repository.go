@@ -0,0 +1,100 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/mainflux/mainflux/presence"
+)
+
+const (
+	thingPrefix   = "presence:thing"
+	sessionPrefix = "session:"
+	lastSeen      = "last_seen"
+)
+
+// Presence of the thing is kept in a hash holding the field per open
+// connection and the last seen time. Last seen time is only moved forward,
+// so that the events of different adapters can be applied out of order.
+var saveScript = redis.NewScript(`
+local ts = tonumber(ARGV[3])
+if ARGV[1] == 'connect' then
+	redis.call('HSET', KEYS[1], ARGV[2], ts)
+else
+	redis.call('HDEL', KEYS[1], ARGV[2])
+end
+local last = tonumber(redis.call('HGET', KEYS[1], 'last_seen') or '0')
+if ts > last then
+	redis.call('HSET', KEYS[1], 'last_seen', ts)
+end
+return 1
+`)
+
+var _ presence.Repository = (*repository)(nil)
+
+type repository struct {
+	client redis.UniversalClient
+}
+
+// NewRepository returns redis presence repository implementation.
+func NewRepository(client redis.UniversalClient) presence.Repository {
+	return &repository{
+		client: client,
+	}
+}
+
+func (r *repository) Save(_ context.Context, e presence.Event) error {
+	field := fmt.Sprintf("%s%s:%s", sessionPrefix, e.Protocol, e.Session)
+	return saveScript.Run(r.client, []string{thingKey(e.Thing)}, e.Type, field, e.Time.Unix()).Err()
+}
+
+func (r *repository) RetrieveAll(_ context.Context, ids ...string) ([]presence.Status, error) {
+	if len(ids) == 0 {
+		return []presence.Status{}, nil
+	}
+
+	pipe := r.client.Pipeline()
+	cmds := make([]*redis.StringStringMapCmd, len(ids))
+	for i, id := range ids {
+		cmds[i] = pipe.HGetAll(thingKey(id))
+	}
+	if _, err := pipe.Exec(); err != nil {
+		return nil, err
+	}
+
+	statuses := make([]presence.Status, len(ids))
+	for i, cmd := range cmds {
+		statuses[i] = toStatus(ids[i], cmd.Val())
+	}
+
+	return statuses, nil
+}
+
+func toStatus(id string, fields map[string]string) presence.Status {
+	s := presence.Status{Thing: id}
+	for k, v := range fields {
+		if strings.HasPrefix(k, sessionPrefix) {
+			s.Connections++
+			continue
+		}
+		if k == lastSeen {
+			if ts, err := strconv.ParseInt(v, 10, 64); err == nil {
+				s.LastSeen = time.Unix(ts, 0)
+			}
+		}
+	}
+	s.Online = s.Connections > 0
+
+	return s
+}
+
+func thingKey(id string) string {
+	return fmt.Sprintf("%s:%s", thingPrefix, id)
+}
@@ -0,0 +1,62 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package redis_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/presence"
+	r "github.com/mainflux/mainflux/presence/redis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveRetrieveAll(t *testing.T) {
+	redisClient.FlushAll()
+	repo := r.NewRepository(redisClient)
+
+	now := time.Unix(time.Now().Unix(), 0)
+	events := []presence.Event{
+		{Thing: "a", Session: "1", Protocol: "mqtt", Type: presence.Connect, Time: now.Add(-3 * time.Second)},
+		{Thing: "a", Session: "1", Protocol: "ws", Type: presence.Connect, Time: now.Add(-2 * time.Second)},
+		{Thing: "b", Session: "2", Protocol: "coap", Type: presence.Connect, Time: now.Add(-2 * time.Second)},
+		{Thing: "b", Session: "2", Protocol: "coap", Type: presence.Disconnect, Time: now},
+		// Late event doesn't move the last seen time back.
+		{Thing: "a", Session: "3", Protocol: "mqtt", Type: presence.Disconnect, Time: now.Add(-time.Hour)},
+	}
+	for _, e := range events {
+		err := repo.Save(context.Background(), e)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	cases := []struct {
+		desc     string
+		ids      []string
+		statuses []presence.Status
+	}{
+		{
+			desc: "retrieve statuses of online, offline and unknown things",
+			ids:  []string{"a", "b", "c"},
+			statuses: []presence.Status{
+				{Thing: "a", Online: true, Connections: 2, LastSeen: now.Add(-2 * time.Second)},
+				{Thing: "b", Online: false, Connections: 0, LastSeen: now},
+				{Thing: "c"},
+			},
+		},
+		{
+			desc:     "retrieve statuses of no things",
+			ids:      []string{},
+			statuses: []presence.Status{},
+		},
+	}
+
+	for _, tc := range cases {
+		statuses, err := repo.RetrieveAll(context.Background(), tc.ids...)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		assert.Equal(t, tc.statuses, statuses, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.statuses, statuses))
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package presence
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	// ErrMalformedEntity indicates malformed entity specification.
+	ErrMalformedEntity = errors.New("malformed entity specification")
+
+	// ErrUnauthorizedAccess indicates missing or invalid credentials provided
+	// when accessing a protected resource.
+	ErrUnauthorizedAccess = errors.New("missing or invalid credentials provided")
+
+	// ErrNotFound indicates a non-existent entity request.
+	ErrNotFound = errors.New("non-existent entity")
+)
+
+// Service specifies an API that must be fulfilled by the domain service
+// implementation, and all of its decorators (e.g. logging & metrics).
+type Service interface {
+	// Record applies the connectivity event published by the adapter to the
+	// presence of the thing.
+	Record(context.Context, Event) error
+
+	// ViewStatus retrieves the presence status of the thing identified by
+	// the provided ID, that belongs to the user identified by the provided
+	// key.
+	ViewStatus(context.Context, string, string) (Status, error)
+
+	// ListConnected retrieves the statuses of the online things connected
+	// to the channel identified by the provided ID, that belongs to the user
+	// identified by the provided key.
+	ListConnected(context.Context, string, string) ([]Status, error)
+}
+
+var _ Service = (*presenceService)(nil)
+
+type presenceService struct {
+	things Things
+	repo   Repository
+}
+
+// New instantiates the presence service implementation.
+func New(things Things, repo Repository) Service {
+	return &presenceService{
+		things: things,
+		repo:   repo,
+	}
+}
+
+func (ps *presenceService) Record(ctx context.Context, e Event) error {
+	if err := e.Validate(); err != nil {
+		return err
+	}
+
+	return ps.repo.Save(ctx, e)
+}
+
+func (ps *presenceService) ViewStatus(ctx context.Context, token, id string) (Status, error) {
+	if err := ps.things.Thing(token, id); err != nil {
+		return Status{}, err
+	}
+
+	statuses, err := ps.repo.RetrieveAll(ctx, id)
+	if err != nil {
+		return Status{}, err
+	}
+
+	return statuses[0], nil
+}
+
+func (ps *presenceService) ListConnected(ctx context.Context, token, chanID string) ([]Status, error) {
+	ids, err := ps.things.ChannelThings(token, chanID)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses, err := ps.repo.RetrieveAll(ctx, ids...)
+	if err != nil {
+		return nil, err
+	}
+
+	online := []Status{}
+	for _, s := range statuses {
+		if s.Online {
+			online = append(online, s)
+		}
+	}
+
+	return online, nil
+}
@@ -0,0 +1,184 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package presence_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/presence"
+	"github.com/mainflux/mainflux/presence/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	token      = "token"
+	wrongToken = "wrong-token"
+)
+
+func newService() presence.Service {
+	things := mocks.NewThings(map[string]string{
+		"1": token,
+		"2": token,
+		"3": wrongToken,
+	}, map[string][]string{
+		"ch": {"1", "2", "3"},
+	})
+
+	return presence.New(things, mocks.NewRepository())
+}
+
+func TestRecord(t *testing.T) {
+	svc := newService()
+
+	now := time.Now()
+	cases := []struct {
+		desc  string
+		event presence.Event
+		err   error
+	}{
+		{
+			desc:  "record connect event",
+			event: presence.Event{Thing: "1", Session: "s", Protocol: "mqtt", Type: presence.Connect, Time: now},
+			err:   nil,
+		},
+		{
+			desc:  "record disconnect event",
+			event: presence.Event{Thing: "1", Session: "s", Protocol: "mqtt", Type: presence.Disconnect, Time: now},
+			err:   nil,
+		},
+		{
+			desc:  "record event without thing",
+			event: presence.Event{Session: "s", Protocol: "mqtt", Type: presence.Connect, Time: now},
+			err:   presence.ErrMalformedEntity,
+		},
+		{
+			desc:  "record event without session",
+			event: presence.Event{Thing: "1", Protocol: "mqtt", Type: presence.Connect, Time: now},
+			err:   presence.ErrMalformedEntity,
+		},
+		{
+			desc:  "record event of unknown type",
+			event: presence.Event{Thing: "1", Session: "s", Protocol: "mqtt", Type: "publish", Time: now},
+			err:   presence.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.Record(context.Background(), tc.event)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestViewStatus(t *testing.T) {
+	svc := newService()
+
+	now := time.Unix(time.Now().Unix(), 0)
+	events := []presence.Event{
+		{Thing: "1", Session: "a", Protocol: "mqtt", Type: presence.Connect, Time: now.Add(-time.Minute)},
+		{Thing: "1", Session: "b", Protocol: "ws", Type: presence.Connect, Time: now},
+		{Thing: "2", Session: "c", Protocol: "coap", Type: presence.Connect, Time: now.Add(-time.Minute)},
+		{Thing: "2", Session: "c", Protocol: "coap", Type: presence.Disconnect, Time: now},
+	}
+	for _, e := range events {
+		err := svc.Record(context.Background(), e)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	cases := []struct {
+		desc   string
+		token  string
+		id     string
+		status presence.Status
+		err    error
+	}{
+		{
+			desc:   "view status of online thing",
+			token:  token,
+			id:     "1",
+			status: presence.Status{Thing: "1", Online: true, Connections: 2, LastSeen: now},
+			err:    nil,
+		},
+		{
+			desc:   "view status of offline thing",
+			token:  token,
+			id:     "2",
+			status: presence.Status{Thing: "2", Online: false, Connections: 0, LastSeen: now},
+			err:    nil,
+		},
+		{
+			desc:   "view status of thing of another user",
+			token:  token,
+			id:     "3",
+			status: presence.Status{},
+			err:    presence.ErrNotFound,
+		},
+		{
+			desc:   "view status with empty token",
+			token:  "",
+			id:     "1",
+			status: presence.Status{},
+			err:    presence.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		status, err := svc.ViewStatus(context.Background(), tc.token, tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.status, status, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.status, status))
+	}
+}
+
+func TestListConnected(t *testing.T) {
+	svc := newService()
+
+	now := time.Unix(time.Now().Unix(), 0)
+	events := []presence.Event{
+		{Thing: "1", Session: "a", Protocol: "mqtt", Type: presence.Connect, Time: now},
+		{Thing: "3", Session: "b", Protocol: "mqtt", Type: presence.Connect, Time: now},
+	}
+	for _, e := range events {
+		err := svc.Record(context.Background(), e)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	cases := []struct {
+		desc     string
+		token    string
+		channel  string
+		statuses []presence.Status
+		err      error
+	}{
+		{
+			desc:     "list connected things of the channel",
+			token:    token,
+			channel:  "ch",
+			statuses: []presence.Status{{Thing: "1", Online: true, Connections: 1, LastSeen: now}},
+			err:      nil,
+		},
+		{
+			desc:     "list connected things of non-existing channel",
+			token:    token,
+			channel:  "unknown",
+			statuses: nil,
+			err:      presence.ErrNotFound,
+		},
+		{
+			desc:     "list connected things with empty token",
+			token:    "",
+			channel:  "ch",
+			statuses: nil,
+			err:      presence.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		statuses, err := svc.ListConnected(context.Background(), tc.token, tc.channel)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.statuses, statuses, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.statuses, statuses))
+	}
+}
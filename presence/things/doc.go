@@ -0,0 +1,6 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package things contains the things API client which retrieves the things
+// of the user through the Mainflux SDK.
+package things
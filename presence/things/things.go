@@ -0,0 +1,62 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package things
+
+import (
+	"github.com/mainflux/mainflux/presence"
+	mfsdk "github.com/mainflux/mainflux/sdk/go"
+)
+
+const pageLimit = 100
+
+var _ presence.Things = (*things)(nil)
+
+type things struct {
+	sdk mfsdk.SDK
+}
+
+// New returns things API client backed by the provided SDK.
+func New(sdk mfsdk.SDK) presence.Things {
+	return things{sdk: sdk}
+}
+
+func (t things) Thing(token, id string) error {
+	_, err := t.sdk.Thing(id, token)
+	return convertError(err)
+}
+
+func (t things) ChannelThings(token, chanID string) ([]string, error) {
+	// Things of the channel aren't listed as not found if the channel
+	// doesn't belong to the user, so the channel is checked first.
+	if _, err := t.sdk.Channel(chanID, token); err != nil {
+		return nil, convertError(err)
+	}
+
+	ids := []string{}
+	for offset := uint64(0); ; offset += pageLimit {
+		page, err := t.sdk.ThingsByChannel(token, chanID, offset, pageLimit)
+		if err != nil {
+			return nil, convertError(err)
+		}
+
+		for _, th := range page.Things {
+			ids = append(ids, th.ID)
+		}
+
+		if len(page.Things) == 0 || offset+pageLimit >= page.Total {
+			return ids, nil
+		}
+	}
+}
+
+func convertError(err error) error {
+	switch err {
+	case mfsdk.ErrUnauthorized:
+		return presence.ErrUnauthorizedAccess
+	case mfsdk.ErrNotFound:
+		return presence.ErrNotFound
+	default:
+		return err
+	}
+}
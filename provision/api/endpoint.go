@@ -0,0 +1,108 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/mainflux/mainflux/provision"
+)
+
+func provisionEndpoint(svc provision.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(provisionReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		dev := provision.Device{
+			ExternalID:  req.ExternalID,
+			ExternalKey: req.ExternalKey,
+			Name:        req.Name,
+			Cert:        req.Cert,
+		}
+
+		b, err := svc.Provision(ctx, req.token, dev)
+		if err != nil {
+			return nil, err
+		}
+
+		return toProvisionRes(b), nil
+	}
+}
+
+func addClaimsEndpoint(svc provision.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(addClaimsReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		claims := []provision.Claim{}
+		for _, c := range req.Claims {
+			claims = append(claims, provision.Claim{
+				Code:        c.Code,
+				ExternalID:  c.ExternalID,
+				ExternalKey: c.ExternalKey,
+				Name:        c.Name,
+				Cert:        c.Cert,
+			})
+		}
+
+		if err := svc.AddClaims(ctx, req.token, claims); err != nil {
+			return nil, err
+		}
+
+		return addClaimsRes{}, nil
+	}
+}
+
+func listClaimsEndpoint(svc provision.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listClaimsReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		page, err := svc.ListClaims(ctx, req.token, req.offset, req.limit)
+		if err != nil {
+			return nil, err
+		}
+
+		res := claimsPageRes{
+			pageRes: pageRes{
+				Total:  page.Total,
+				Offset: page.Offset,
+				Limit:  page.Limit,
+			},
+			Claims: []claimRes{},
+		}
+		for _, c := range page.Claims {
+			res.Claims = append(res.Claims, toClaimRes(c))
+		}
+
+		return res, nil
+	}
+}
+
+func claimEndpoint(svc provision.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(claimReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		b, err := svc.Claim(ctx, req.token, req.Code)
+		if err != nil {
+			return nil, err
+		}
+
+		return toProvisionRes(b), nil
+	}
+}
@@ -0,0 +1,435 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mainflux/mainflux/provision"
+	"github.com/mainflux/mainflux/provision/api"
+	"github.com/mainflux/mainflux/provision/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	token       = "token"
+	email       = "user@example.com"
+	mfToken     = "manufacturer"
+	mfEmail     = "manufacturer@example.com"
+	wrongValue  = "wrong-value"
+	contentType = "application/json"
+)
+
+type testRequest struct {
+	client      *http.Client
+	method      string
+	url         string
+	contentType string
+	token       string
+	body        io.Reader
+}
+
+func (tr testRequest) make() (*http.Response, error) {
+	req, err := http.NewRequest(tr.method, tr.url, tr.body)
+	if err != nil {
+		return nil, err
+	}
+
+	if tr.token != "" {
+		req.Header.Set("Authorization", tr.token)
+	}
+
+	if tr.contentType != "" {
+		req.Header.Set("Content-Type", tr.contentType)
+	}
+
+	return tr.client.Do(req)
+}
+
+type bundleRes struct {
+	ExternalID string `json:"external_id"`
+	Thing      struct {
+		ID  string `json:"id"`
+		Key string `json:"key"`
+	} `json:"thing"`
+	Channels []struct {
+		ID string `json:"id"`
+	} `json:"channels"`
+	Cert *struct {
+		ClientCert string `json:"client_cert"`
+		ClientKey  string `json:"client_key"`
+	} `json:"cert"`
+}
+
+func newServer() *httptest.Server {
+	cfg := provision.Config{Channels: []string{"control", "data"}}
+	users := mocks.NewUsersService(map[string]string{token: email, mfToken: mfEmail})
+	svc := provision.New(users, mocks.NewThings(token), mocks.NewBootstrap(), mocks.NewCerts(), mocks.NewClaimRepository(), cfg)
+
+	return httptest.NewServer(api.MakeHandler(svc))
+}
+
+type claimsPageRes struct {
+	Total  uint64 `json:"total"`
+	Claims []struct {
+		Code    string `json:"code"`
+		Claimed bool   `json:"claimed"`
+	} `json:"claims"`
+}
+
+func TestProvision(t *testing.T) {
+	ts := newServer()
+	defer ts.Close()
+
+	cases := []struct {
+		desc        string
+		body        string
+		contentType string
+		token       string
+		status      int
+		cert        bool
+	}{
+		{
+			desc:        "provision device",
+			body:        `{"external_id":"ext-1","external_key":"key"}`,
+			contentType: contentType,
+			token:       token,
+			status:      http.StatusCreated,
+		},
+		{
+			desc:        "provision device with certificate",
+			body:        `{"external_id":"ext-2","external_key":"key","cert":true}`,
+			contentType: contentType,
+			token:       token,
+			status:      http.StatusCreated,
+			cert:        true,
+		},
+		{
+			desc:        "provision already provisioned device",
+			body:        `{"external_id":"ext-1","external_key":"key"}`,
+			contentType: contentType,
+			token:       token,
+			status:      http.StatusConflict,
+		},
+		{
+			desc:        "provision device without external key",
+			body:        `{"external_id":"ext-3"}`,
+			contentType: contentType,
+			token:       token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "provision device with malformed JSON",
+			body:        `{"external_id":`,
+			contentType: contentType,
+			token:       token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "provision device with wrong credentials",
+			body:        `{"external_id":"ext-3","external_key":"key"}`,
+			contentType: contentType,
+			token:       wrongValue,
+			status:      http.StatusForbidden,
+		},
+		{
+			desc:        "provision device without credentials",
+			body:        `{"external_id":"ext-3","external_key":"key"}`,
+			contentType: contentType,
+			token:       "",
+			status:      http.StatusForbidden,
+		},
+		{
+			desc:        "provision device with invalid content type",
+			body:        `{"external_id":"ext-3","external_key":"key"}`,
+			contentType: "text/plain",
+			token:       token,
+			status:      http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/provision", ts.URL),
+			contentType: tc.contentType,
+			token:       tc.token,
+			body:        strings.NewReader(tc.body),
+		}
+		res, err := req.make()
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		if tc.status != http.StatusCreated {
+			continue
+		}
+
+		var b bundleRes
+		err = json.NewDecoder(res.Body).Decode(&b)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.NotEmpty(t, b.Thing.ID, fmt.Sprintf("%s: expected thing ID to be set", tc.desc))
+		assert.NotEmpty(t, b.Thing.Key, fmt.Sprintf("%s: expected thing key to be set", tc.desc))
+		assert.Len(t, b.Channels, 2, fmt.Sprintf("%s: expected 2 channels got %d", tc.desc, len(b.Channels)))
+		assert.Equal(t, tc.cert, b.Cert != nil, fmt.Sprintf("%s: expected certificate returned to be %t", tc.desc, tc.cert))
+	}
+}
+
+func TestAddClaims(t *testing.T) {
+	ts := newServer()
+	defer ts.Close()
+
+	claims := []string{}
+	for i := 0; i < 1001; i++ {
+		claims = append(claims, fmt.Sprintf(`{"code":"c-%d","external_id":"e-%d","external_key":"key"}`, i, i))
+	}
+	tooMany := fmt.Sprintf(`{"claims":[%s]}`, strings.Join(claims, ","))
+
+	cases := []struct {
+		desc        string
+		body        string
+		contentType string
+		token       string
+		status      int
+	}{
+		{
+			desc:        "add claims",
+			body:        `{"claims":[{"code":"code-1","external_id":"ext-1","external_key":"key"},{"code":"code-2","external_id":"ext-2","external_key":"key","cert":true}]}`,
+			contentType: contentType,
+			token:       mfToken,
+			status:      http.StatusCreated,
+		},
+		{
+			desc:        "add claim with existing code",
+			body:        `{"claims":[{"code":"code-1","external_id":"ext-3","external_key":"key"}]}`,
+			contentType: contentType,
+			token:       mfToken,
+			status:      http.StatusConflict,
+		},
+		{
+			desc:        "add claim without code",
+			body:        `{"claims":[{"external_id":"ext-3","external_key":"key"}]}`,
+			contentType: contentType,
+			token:       mfToken,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "add no claims",
+			body:        `{"claims":[]}`,
+			contentType: contentType,
+			token:       mfToken,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "add too many claims",
+			body:        tooMany,
+			contentType: contentType,
+			token:       mfToken,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "add claims with malformed JSON",
+			body:        `{"claims":`,
+			contentType: contentType,
+			token:       mfToken,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "add claims with wrong credentials",
+			body:        `{"claims":[{"code":"code-3","external_id":"ext-3","external_key":"key"}]}`,
+			contentType: contentType,
+			token:       wrongValue,
+			status:      http.StatusForbidden,
+		},
+		{
+			desc:        "add claims with invalid content type",
+			body:        `{"claims":[{"code":"code-3","external_id":"ext-3","external_key":"key"}]}`,
+			contentType: "text/plain",
+			token:       mfToken,
+			status:      http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/claims", ts.URL),
+			contentType: tc.contentType,
+			token:       tc.token,
+			body:        strings.NewReader(tc.body),
+		}
+		res, err := req.make()
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestClaim(t *testing.T) {
+	ts := newServer()
+	defer ts.Close()
+
+	req := testRequest{
+		client:      ts.Client(),
+		method:      http.MethodPost,
+		url:         fmt.Sprintf("%s/claims", ts.URL),
+		contentType: contentType,
+		token:       mfToken,
+		body:        strings.NewReader(`{"claims":[{"code":"code","external_id":"ext","external_key":"key","cert":true}]}`),
+	}
+	res, err := req.make()
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	require.Equal(t, http.StatusCreated, res.StatusCode, fmt.Sprintf("expected status code %d got %d", http.StatusCreated, res.StatusCode))
+
+	cases := []struct {
+		desc        string
+		body        string
+		contentType string
+		token       string
+		status      int
+	}{
+		{
+			desc:        "claim device",
+			body:        `{"code":"code"}`,
+			contentType: contentType,
+			token:       token,
+			status:      http.StatusCreated,
+		},
+		{
+			desc:        "claim already claimed device",
+			body:        `{"code":"code"}`,
+			contentType: contentType,
+			token:       token,
+			status:      http.StatusNotFound,
+		},
+		{
+			desc:        "claim device with unknown code",
+			body:        fmt.Sprintf(`{"code":"%s"}`, wrongValue),
+			contentType: contentType,
+			token:       token,
+			status:      http.StatusNotFound,
+		},
+		{
+			desc:        "claim device without code",
+			body:        `{}`,
+			contentType: contentType,
+			token:       token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "claim device with wrong credentials",
+			body:        `{"code":"code"}`,
+			contentType: contentType,
+			token:       wrongValue,
+			status:      http.StatusForbidden,
+		},
+		{
+			desc:        "claim device with invalid content type",
+			body:        `{"code":"code"}`,
+			contentType: "text/plain",
+			token:       token,
+			status:      http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/claim", ts.URL),
+			contentType: tc.contentType,
+			token:       tc.token,
+			body:        strings.NewReader(tc.body),
+		}
+		res, err := req.make()
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		if tc.status != http.StatusCreated {
+			continue
+		}
+
+		var b bundleRes
+		err = json.NewDecoder(res.Body).Decode(&b)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, "ext", b.ExternalID, fmt.Sprintf("%s: expected external ID %s got %s", tc.desc, "ext", b.ExternalID))
+		assert.NotNil(t, b.Cert, fmt.Sprintf("%s: expected certificate to be issued", tc.desc))
+	}
+
+	req = testRequest{
+		client: ts.Client(),
+		method: http.MethodGet,
+		url:    fmt.Sprintf("%s/claims", ts.URL),
+		token:  mfToken,
+	}
+	res, err = req.make()
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	require.Equal(t, http.StatusOK, res.StatusCode, fmt.Sprintf("expected status code %d got %d", http.StatusOK, res.StatusCode))
+
+	var page claimsPageRes
+	err = json.NewDecoder(res.Body).Decode(&page)
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	require.Len(t, page.Claims, 1, fmt.Sprintf("expected 1 claim got %d", len(page.Claims)))
+	assert.True(t, page.Claims[0].Claimed, "expected claim to be claimed")
+}
+
+func TestListClaims(t *testing.T) {
+	ts := newServer()
+	defer ts.Close()
+
+	cases := []struct {
+		desc   string
+		query  string
+		token  string
+		status int
+	}{
+		{
+			desc:   "list claims",
+			query:  "offset=0&limit=10",
+			token:  mfToken,
+			status: http.StatusOK,
+		},
+		{
+			desc:   "list claims with default pagination",
+			query:  "",
+			token:  mfToken,
+			status: http.StatusOK,
+		},
+		{
+			desc:   "list claims with limit exceeding maximal limit",
+			query:  "limit=101",
+			token:  mfToken,
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "list claims with invalid offset",
+			query:  "offset=invalid",
+			token:  mfToken,
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "list claims with wrong credentials",
+			query:  "",
+			token:  wrongValue,
+			status: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/claims?%s", ts.URL, tc.query),
+			token:  tc.token,
+		}
+		res, err := req.make()
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
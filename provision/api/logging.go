@@ -0,0 +1,79 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// +build !test
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/provision"
+)
+
+var _ provision.Service = (*loggingMiddleware)(nil)
+
+type loggingMiddleware struct {
+	logger log.Logger
+	svc    provision.Service
+}
+
+// LoggingMiddleware adds logging facilities to the core service.
+func LoggingMiddleware(svc provision.Service, logger log.Logger) provision.Service {
+	return &loggingMiddleware{logger, svc}
+}
+
+func (lm *loggingMiddleware) Provision(ctx context.Context, token string, dev provision.Device) (b provision.Bundle, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method provision for token %s and external ID %s and thing %s took %s to complete", token, dev.ExternalID, b.Thing.ID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Provision(ctx, token, dev)
+}
+
+func (lm *loggingMiddleware) AddClaims(ctx context.Context, token string, claims []provision.Claim) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method add_claims for token %s and %d claims took %s to complete", token, len(claims), time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.AddClaims(ctx, token, claims)
+}
+
+func (lm *loggingMiddleware) ListClaims(ctx context.Context, token string, offset, limit uint64) (page provision.ClaimsPage, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_claims for token %s took %s to complete", token, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListClaims(ctx, token, offset, limit)
+}
+
+func (lm *loggingMiddleware) Claim(ctx context.Context, token, code string) (b provision.Bundle, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method claim for token %s and thing %s took %s to complete", token, b.Thing.ID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Claim(ctx, token, code)
+}
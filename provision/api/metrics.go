@@ -0,0 +1,68 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// +build !test
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/mainflux/mainflux/provision"
+)
+
+var _ provision.Service = (*metricsMiddleware)(nil)
+
+type metricsMiddleware struct {
+	counter metrics.Counter
+	latency metrics.Histogram
+	svc     provision.Service
+}
+
+// MetricsMiddleware instruments core service by tracking request count and
+// latency.
+func MetricsMiddleware(svc provision.Service, counter metrics.Counter, latency metrics.Histogram) provision.Service {
+	return &metricsMiddleware{
+		counter: counter,
+		latency: latency,
+		svc:     svc,
+	}
+}
+
+func (ms *metricsMiddleware) Provision(ctx context.Context, token string, dev provision.Device) (provision.Bundle, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "provision").Add(1)
+		ms.latency.With("method", "provision").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Provision(ctx, token, dev)
+}
+
+func (ms *metricsMiddleware) AddClaims(ctx context.Context, token string, claims []provision.Claim) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "add_claims").Add(1)
+		ms.latency.With("method", "add_claims").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.AddClaims(ctx, token, claims)
+}
+
+func (ms *metricsMiddleware) ListClaims(ctx context.Context, token string, offset, limit uint64) (provision.ClaimsPage, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_claims").Add(1)
+		ms.latency.With("method", "list_claims").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListClaims(ctx, token, offset, limit)
+}
+
+func (ms *metricsMiddleware) Claim(ctx context.Context, token, code string) (provision.Bundle, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "claim").Add(1)
+		ms.latency.With("method", "claim").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Claim(ctx, token, code)
+}
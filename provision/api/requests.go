@@ -0,0 +1,101 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import "github.com/mainflux/mainflux/provision"
+
+const (
+	maxLimitSize = 100
+	maxClaims    = 1000
+)
+
+type apiReq interface {
+	validate() error
+}
+
+type provisionReq struct {
+	token       string
+	ExternalID  string `json:"external_id"`
+	ExternalKey string `json:"external_key"`
+	Name        string `json:"name,omitempty"`
+	Cert        bool   `json:"cert,omitempty"`
+}
+
+func (req provisionReq) validate() error {
+	if req.token == "" {
+		return provision.ErrUnauthorizedAccess
+	}
+
+	if req.ExternalID == "" || req.ExternalKey == "" {
+		return provision.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type claimItem struct {
+	Code        string `json:"code"`
+	ExternalID  string `json:"external_id"`
+	ExternalKey string `json:"external_key"`
+	Name        string `json:"name,omitempty"`
+	Cert        bool   `json:"cert,omitempty"`
+}
+
+type addClaimsReq struct {
+	token  string
+	Claims []claimItem `json:"claims"`
+}
+
+func (req addClaimsReq) validate() error {
+	if req.token == "" {
+		return provision.ErrUnauthorizedAccess
+	}
+
+	if len(req.Claims) == 0 || len(req.Claims) > maxClaims {
+		return provision.ErrMalformedEntity
+	}
+
+	for _, c := range req.Claims {
+		if c.Code == "" || c.ExternalID == "" || c.ExternalKey == "" {
+			return provision.ErrMalformedEntity
+		}
+	}
+
+	return nil
+}
+
+type listClaimsReq struct {
+	token  string
+	offset uint64
+	limit  uint64
+}
+
+func (req listClaimsReq) validate() error {
+	if req.token == "" {
+		return provision.ErrUnauthorizedAccess
+	}
+
+	if req.limit == 0 || req.limit > maxLimitSize {
+		return provision.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type claimReq struct {
+	token string
+	Code  string `json:"code"`
+}
+
+func (req claimReq) validate() error {
+	if req.token == "" {
+		return provision.ErrUnauthorizedAccess
+	}
+
+	if req.Code == "" {
+		return provision.ErrMalformedEntity
+	}
+
+	return nil
+}
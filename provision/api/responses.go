@@ -0,0 +1,155 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/provision"
+)
+
+var (
+	_ mainflux.Response = (*provisionRes)(nil)
+	_ mainflux.Response = (*addClaimsRes)(nil)
+	_ mainflux.Response = (*claimsPageRes)(nil)
+)
+
+type thingRes struct {
+	ID   string `json:"id"`
+	Key  string `json:"key"`
+	Name string `json:"name"`
+}
+
+type channelRes struct {
+	ID       string                 `json:"id"`
+	Name     string                 `json:"name"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+type certRes struct {
+	Serial     string `json:"serial"`
+	ClientCert string `json:"client_cert"`
+	ClientKey  string `json:"client_key"`
+	CACert     string `json:"ca_cert"`
+}
+
+type provisionRes struct {
+	ExternalID string       `json:"external_id"`
+	Thing      thingRes     `json:"thing"`
+	Channels   []channelRes `json:"channels"`
+	Cert       *certRes     `json:"cert,omitempty"`
+}
+
+func (res provisionRes) Code() int {
+	return http.StatusCreated
+}
+
+func (res provisionRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res provisionRes) Empty() bool {
+	return false
+}
+
+func toProvisionRes(b provision.Bundle) provisionRes {
+	res := provisionRes{
+		ExternalID: b.ExternalID,
+		Thing: thingRes{
+			ID:   b.Thing.ID,
+			Key:  b.Thing.Key,
+			Name: b.Thing.Name,
+		},
+		Channels: []channelRes{},
+	}
+	for _, ch := range b.Channels {
+		res.Channels = append(res.Channels, channelRes{
+			ID:       ch.ID,
+			Name:     ch.Name,
+			Metadata: ch.Metadata,
+		})
+	}
+
+	if b.Cert.Serial != "" {
+		res.Cert = &certRes{
+			Serial:     b.Cert.Serial,
+			ClientCert: b.Cert.Certificate,
+			ClientKey:  b.Cert.PrivateKey,
+			CACert:     b.Cert.IssuingCA,
+		}
+	}
+
+	return res
+}
+
+type addClaimsRes struct{}
+
+func (res addClaimsRes) Code() int {
+	return http.StatusCreated
+}
+
+func (res addClaimsRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res addClaimsRes) Empty() bool {
+	return true
+}
+
+type claimRes struct {
+	Code       string     `json:"code"`
+	ExternalID string     `json:"external_id"`
+	Name       string     `json:"name,omitempty"`
+	Cert       bool       `json:"cert"`
+	Created    time.Time  `json:"created"`
+	Claimed    bool       `json:"claimed"`
+	ClaimedBy  string     `json:"claimed_by,omitempty"`
+	ClaimedAt  *time.Time `json:"claimed_at,omitempty"`
+	ThingID    string     `json:"thing_id,omitempty"`
+}
+
+type pageRes struct {
+	Total  uint64 `json:"total"`
+	Offset uint64 `json:"offset"`
+	Limit  uint64 `json:"limit"`
+}
+
+type claimsPageRes struct {
+	pageRes
+	Claims []claimRes `json:"claims"`
+}
+
+func (res claimsPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res claimsPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res claimsPageRes) Empty() bool {
+	return false
+}
+
+func toClaimRes(c provision.Claim) claimRes {
+	res := claimRes{
+		Code:       c.Code,
+		ExternalID: c.ExternalID,
+		Name:       c.Name,
+		Cert:       c.Cert,
+		Created:    c.Created,
+		Claimed:    c.Claimed(),
+		ClaimedBy:  c.ClaimedBy,
+		ThingID:    c.ThingID,
+	}
+
+	if c.Claimed() {
+		at := c.ClaimedAt
+		res.ClaimedAt = &at
+	}
+
+	return res
+}
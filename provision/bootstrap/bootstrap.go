@@ -0,0 +1,91 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package bootstrap contains the client of the bootstrap service used to
+// store the bootstrap configurations of the provisioned devices.
+package bootstrap
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mainflux/mainflux/provision"
+)
+
+type addReq struct {
+	ThingID     string   `json:"thing_id"`
+	ExternalID  string   `json:"external_id"`
+	ExternalKey string   `json:"external_key"`
+	Channels    []string `json:"channels"`
+	Name        string   `json:"name,omitempty"`
+	Content     string   `json:"content,omitempty"`
+	ClientCert  string   `json:"client_cert,omitempty"`
+	ClientKey   string   `json:"client_key,omitempty"`
+	CACert      string   `json:"ca_cert,omitempty"`
+}
+
+var _ provision.Bootstrap = (*client)(nil)
+
+type client struct {
+	url  string
+	http *http.Client
+}
+
+// New returns the client of the bootstrap service listening on the provided
+// base URL.
+func New(baseURL string, timeout time.Duration) provision.Bootstrap {
+	return client{
+		url:  strings.TrimSuffix(baseURL, "/"),
+		http: &http.Client{Timeout: timeout},
+	}
+}
+
+func (c client) Add(ctx context.Context, token string, cfg provision.BootstrapConfig) error {
+	req := addReq{
+		ThingID:     cfg.ThingID,
+		ExternalID:  cfg.ExternalID,
+		ExternalKey: cfg.ExternalKey,
+		Channels:    cfg.Channels,
+		Name:        cfg.Name,
+		Content:     cfg.Content,
+		ClientCert:  cfg.ClientCert,
+		ClientKey:   cfg.ClientKey,
+		CACert:      cfg.CACert,
+	}
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	r, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/things/configs", c.url), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	r.Header.Set("Authorization", token)
+	r.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.http.Do(r.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusCreated:
+		return nil
+	case http.StatusBadRequest:
+		return provision.ErrMalformedEntity
+	case http.StatusForbidden:
+		return provision.ErrUnauthorizedAccess
+	case http.StatusConflict:
+		return provision.ErrConflict
+	default:
+		return fmt.Errorf("bootstrap service responded with status %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package certs contains the client of the certs service used to issue the
+// client certificates of the provisioned devices.
+package certs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mainflux/mainflux/provision"
+)
+
+type issueReq struct {
+	ThingID string `json:"thing_id"`
+}
+
+type issueRes struct {
+	Serial      string `json:"serial"`
+	Certificate string `json:"certificate"`
+	PrivateKey  string `json:"private_key"`
+	IssuingCA   string `json:"issuing_ca"`
+}
+
+var _ provision.Certs = (*client)(nil)
+
+type client struct {
+	url  string
+	http *http.Client
+}
+
+// New returns the client of the certs service listening on the provided
+// base URL.
+func New(baseURL string, timeout time.Duration) provision.Certs {
+	return client{
+		url:  strings.TrimSuffix(baseURL, "/"),
+		http: &http.Client{Timeout: timeout},
+	}
+}
+
+func (c client) Issue(ctx context.Context, token, thingID string) (provision.Cert, error) {
+	data, err := json.Marshal(issueReq{ThingID: thingID})
+	if err != nil {
+		return provision.Cert{}, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/certs", c.url), bytes.NewReader(data))
+	if err != nil {
+		return provision.Cert{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.do(ctx, req, token)
+	if err != nil {
+		return provision.Cert{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return provision.Cert{}, toError(resp.StatusCode)
+	}
+
+	var res issueRes
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return provision.Cert{}, err
+	}
+
+	cert := provision.Cert{
+		Serial:      res.Serial,
+		Certificate: res.Certificate,
+		PrivateKey:  res.PrivateKey,
+		IssuingCA:   res.IssuingCA,
+	}
+
+	return cert, nil
+}
+
+func (c client) Revoke(ctx context.Context, token, serial string) error {
+	req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/certs/%s", c.url, url.PathEscape(serial)), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.do(ctx, req, token)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return toError(resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (c client) do(ctx context.Context, req *http.Request, token string) (*http.Response, error) {
+	req.Header.Set("Authorization", token)
+	return c.http.Do(req.WithContext(ctx))
+}
+
+func toError(status int) error {
+	switch status {
+	case http.StatusBadRequest:
+		return provision.ErrMalformedEntity
+	case http.StatusForbidden:
+		return provision.ErrUnauthorizedAccess
+	default:
+		return fmt.Errorf("certs service responded with status %d", status)
+	}
+}
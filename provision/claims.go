@@ -0,0 +1,60 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package provision
+
+import (
+	"context"
+	"time"
+)
+
+// Claim represents the claim code preloaded by the manufacturer of the
+// device. User presenting the code provisions the device into their own
+// account. Claim can be used only once.
+type Claim struct {
+	Code        string
+	Owner       string
+	ExternalID  string
+	ExternalKey string
+	Name        string
+	Cert        bool
+	Created     time.Time
+	ClaimedBy   string
+	ClaimedAt   time.Time
+	ThingID     string
+}
+
+// Claimed returns true if the claim is already used.
+func (c Claim) Claimed() bool {
+	return c.ClaimedBy != ""
+}
+
+// ClaimsPage contains page related metadata as well as list of claims that
+// belong to this page.
+type ClaimsPage struct {
+	Total  uint64
+	Offset uint64
+	Limit  uint64
+	Claims []Claim
+}
+
+// ClaimRepository specifies a claim persistence API.
+type ClaimRepository interface {
+	// Save persists all the claims, or none of them if any of the codes or
+	// external IDs is already taken.
+	Save(context.Context, ...Claim) error
+
+	// RetrieveAll retrieves the subset of claims preloaded by the owner.
+	RetrieveAll(ctx context.Context, owner string, offset, limit uint64) (ClaimsPage, error)
+
+	// Reserve marks the unused claim having the provided code as claimed by
+	// the user, and returns it. Unknown and already used claims are not
+	// found.
+	Reserve(ctx context.Context, code, user string, at time.Time) (Claim, error)
+
+	// Release reverts the reservation of the claim, making it usable again.
+	Release(ctx context.Context, code string) error
+
+	// Complete records the thing provisioned using the claim.
+	Complete(ctx context.Context, code, thingID string) error
+}
@@ -0,0 +1,12 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package provision contains the domain concept definitions needed to support
+// Mainflux provision service functionality. Provision service onboards the
+// device in a single call: it creates the thing and its channels, connects
+// them, optionally issues the client certificate, and stores the bootstrap
+// configuration of the device. Entities created before a failed step are
+// removed, so that the device is either fully provisioned or not at all.
+// Devices can also be claimed using the single-use claim codes preloaded by
+// their manufacturer, which provisions them into the claiming user's account.
+package provision
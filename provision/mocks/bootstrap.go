@@ -0,0 +1,48 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mainflux/mainflux/provision"
+)
+
+var _ provision.Bootstrap = (*Bootstrap)(nil)
+
+// Bootstrap is an in-memory bootstrap API which stores the configurations
+// by the external IDs.
+type Bootstrap struct {
+	mu      sync.Mutex
+	configs map[string]provision.BootstrapConfig
+}
+
+// NewBootstrap returns bootstrap API mock.
+func NewBootstrap() *Bootstrap {
+	return &Bootstrap{
+		configs: make(map[string]provision.BootstrapConfig),
+	}
+}
+
+func (b *Bootstrap) Add(_ context.Context, _ string, cfg provision.BootstrapConfig) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.configs[cfg.ExternalID]; ok {
+		return provision.ErrConflict
+	}
+
+	b.configs[cfg.ExternalID] = cfg
+	return nil
+}
+
+// Config returns the configuration stored for the external ID.
+func (b *Bootstrap) Config(externalID string) (provision.BootstrapConfig, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	cfg, ok := b.configs[externalID]
+	return cfg, ok
+}
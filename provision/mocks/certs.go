@@ -0,0 +1,79 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/mainflux/mainflux/provision"
+)
+
+var _ provision.Certs = (*Certs)(nil)
+
+// Certs is an in-memory certs API which records the issued and revoked
+// certificates.
+type Certs struct {
+	mu      sync.Mutex
+	counter uint64
+	err     error
+	issued  map[string]string
+	revoked []string
+}
+
+// NewCerts returns certs API mock.
+func NewCerts() *Certs {
+	return &Certs{
+		issued: make(map[string]string),
+	}
+}
+
+// Issue issues the certificate, unless the mock is set to fail.
+func (c *Certs) Issue(_ context.Context, _, thingID string) (provision.Cert, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.err != nil {
+		return provision.Cert{}, c.err
+	}
+
+	c.counter++
+	serial := fmt.Sprintf("%d", c.counter)
+	c.issued[serial] = thingID
+
+	cert := provision.Cert{
+		Serial:      serial,
+		Certificate: fmt.Sprintf("cert-%s", thingID),
+		PrivateKey:  fmt.Sprintf("key-%s", thingID),
+		IssuingCA:   "ca",
+	}
+
+	return cert, nil
+}
+
+func (c *Certs) Revoke(_ context.Context, _, serial string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.revoked = append(c.revoked, serial)
+	return nil
+}
+
+// Fail sets the error returned by the subsequent issuing. Nil error
+// restores the issuing.
+func (c *Certs) Fail(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.err = err
+}
+
+// Revoked returns the serial numbers of the revoked certificates.
+func (c *Certs) Revoked() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]string{}, c.revoked...)
+}
@@ -0,0 +1,133 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mainflux/mainflux/provision"
+)
+
+var _ provision.ClaimRepository = (*claimRepositoryMock)(nil)
+
+type claimRepositoryMock struct {
+	mu     sync.Mutex
+	claims map[string]provision.Claim
+}
+
+// NewClaimRepository creates in-memory claim repository.
+func NewClaimRepository() provision.ClaimRepository {
+	return &claimRepositoryMock{
+		claims: make(map[string]provision.Claim),
+	}
+}
+
+func (crm *claimRepositoryMock) Save(_ context.Context, claims ...provision.Claim) error {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	for _, c := range claims {
+		if _, ok := crm.claims[c.Code]; ok {
+			return provision.ErrConflict
+		}
+
+		for _, saved := range crm.claims {
+			if saved.ExternalID == c.ExternalID {
+				return provision.ErrConflict
+			}
+		}
+	}
+
+	for _, c := range claims {
+		crm.claims[c.Code] = c
+	}
+
+	return nil
+}
+
+func (crm *claimRepositoryMock) RetrieveAll(_ context.Context, owner string, offset, limit uint64) (provision.ClaimsPage, error) {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	items := []provision.Claim{}
+	for _, c := range crm.claims {
+		if c.Owner == owner {
+			items = append(items, c)
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Code < items[j].Code
+	})
+
+	page := provision.ClaimsPage{
+		Total:  uint64(len(items)),
+		Offset: offset,
+		Limit:  limit,
+		Claims: []provision.Claim{},
+	}
+
+	if offset >= uint64(len(items)) {
+		return page, nil
+	}
+
+	end := offset + limit
+	if end > uint64(len(items)) {
+		end = uint64(len(items))
+	}
+	page.Claims = items[offset:end]
+
+	return page, nil
+}
+
+func (crm *claimRepositoryMock) Reserve(_ context.Context, code, user string, at time.Time) (provision.Claim, error) {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	c, ok := crm.claims[code]
+	if !ok || c.Claimed() {
+		return provision.Claim{}, provision.ErrNotFound
+	}
+
+	c.ClaimedBy = user
+	c.ClaimedAt = at
+	crm.claims[code] = c
+
+	return c, nil
+}
+
+func (crm *claimRepositoryMock) Release(_ context.Context, code string) error {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	c, ok := crm.claims[code]
+	if !ok {
+		return provision.ErrNotFound
+	}
+
+	c.ClaimedBy = ""
+	c.ClaimedAt = time.Time{}
+	c.ThingID = ""
+	crm.claims[code] = c
+
+	return nil
+}
+
+func (crm *claimRepositoryMock) Complete(_ context.Context, code, thingID string) error {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	c, ok := crm.claims[code]
+	if !ok || !c.Claimed() {
+		return provision.ErrNotFound
+	}
+
+	c.ThingID = thingID
+	crm.claims[code] = c
+
+	return nil
+}
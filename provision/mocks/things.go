@@ -0,0 +1,128 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/mainflux/mainflux/provision"
+)
+
+var _ provision.Things = (*Things)(nil)
+
+// Things is an in-memory things API which serves the user identified by
+// the single token.
+type Things struct {
+	mu          sync.Mutex
+	token       string
+	counter     uint64
+	err         error
+	things      map[string]provision.Thing
+	channels    map[string]provision.Channel
+	connections map[string][]string
+}
+
+// NewThings returns things API mock serving the user identified by the
+// provided token.
+func NewThings(token string) *Things {
+	return &Things{
+		token:       token,
+		things:      make(map[string]provision.Thing),
+		channels:    make(map[string]provision.Channel),
+		connections: make(map[string][]string),
+	}
+}
+
+func (t *Things) CreateThing(token string, th provision.Thing) (provision.Thing, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if token != t.token {
+		return provision.Thing{}, provision.ErrUnauthorizedAccess
+	}
+
+	t.counter++
+	th.ID = fmt.Sprintf("%03d", t.counter)
+	th.Key = fmt.Sprintf("key-%s", th.ID)
+	t.things[th.ID] = th
+
+	return th, nil
+}
+
+func (t *Things) RemoveThing(token, id string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.things, id)
+	delete(t.connections, id)
+	return nil
+}
+
+func (t *Things) CreateChannel(token string, ch provision.Channel) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if token != t.token {
+		return "", provision.ErrUnauthorizedAccess
+	}
+
+	t.counter++
+	ch.ID = fmt.Sprintf("%03d", t.counter)
+	t.channels[ch.ID] = ch
+
+	return ch.ID, nil
+}
+
+func (t *Things) RemoveChannel(token, id string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.channels, id)
+	return nil
+}
+
+// Connect connects the thing to the channel, unless the mock is set to
+// fail.
+func (t *Things) Connect(token, thingID, chanID string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.err != nil {
+		return t.err
+	}
+
+	t.connections[thingID] = append(t.connections[thingID], chanID)
+	return nil
+}
+
+// Fail sets the error returned by the subsequent connecting. Nil error
+// restores the connecting.
+func (t *Things) Fail(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.err = err
+}
+
+// Count returns the number of the existing things, channels and
+// connections.
+func (t *Things) Count() (things, channels, connections int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, conns := range t.connections {
+		connections += len(conns)
+	}
+
+	return len(t.things), len(t.channels), connections
+}
+
+// Connected returns the channels the thing is connected to.
+func (t *Things) Connected(thingID string) []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return append([]string{}, t.connections[thingID]...)
+}
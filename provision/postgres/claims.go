@@ -0,0 +1,195 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq" // required for DB access
+	"github.com/mainflux/mainflux/provision"
+)
+
+const errDuplicate = "unique_violation"
+
+var _ provision.ClaimRepository = (*claimRepository)(nil)
+
+type claimRepository struct {
+	db *sqlx.DB
+}
+
+// NewClaimRepository instantiates a PostgreSQL implementation of claim
+// repository.
+func NewClaimRepository(db *sqlx.DB) provision.ClaimRepository {
+	return &claimRepository{
+		db: db,
+	}
+}
+
+func (cr claimRepository) Save(ctx context.Context, claims ...provision.Claim) error {
+	q := `INSERT INTO claims (code, owner, external_id, external_key, name, cert, created, claimed_by, claimed_at, thing)
+	      VALUES (:code, :owner, :external_id, :external_key, :name, :cert, :created, :claimed_by, :claimed_at, :thing);`
+
+	tx, err := cr.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range claims {
+		if _, err := tx.NamedExecContext(ctx, q, toDBClaim(c)); err != nil {
+			tx.Rollback()
+			if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == errDuplicate {
+				return provision.ErrConflict
+			}
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (cr claimRepository) RetrieveAll(ctx context.Context, owner string, offset, limit uint64) (provision.ClaimsPage, error) {
+	q := `SELECT code, owner, external_id, external_key, name, cert, created, claimed_by, claimed_at, thing
+	      FROM claims WHERE owner = $1 ORDER BY code LIMIT $2 OFFSET $3;`
+
+	rows, err := cr.db.QueryxContext(ctx, q, owner, limit, offset)
+	if err != nil {
+		return provision.ClaimsPage{}, err
+	}
+	defer rows.Close()
+
+	items := []provision.Claim{}
+	for rows.Next() {
+		var dbc dbClaim
+		if err := rows.StructScan(&dbc); err != nil {
+			return provision.ClaimsPage{}, err
+		}
+		items = append(items, toClaim(dbc))
+	}
+
+	if err := rows.Err(); err != nil {
+		return provision.ClaimsPage{}, err
+	}
+
+	cq := `SELECT COUNT(*) FROM claims WHERE owner = $1;`
+
+	var total uint64
+	if err := cr.db.GetContext(ctx, &total, cq, owner); err != nil {
+		return provision.ClaimsPage{}, err
+	}
+
+	page := provision.ClaimsPage{
+		Total:  total,
+		Offset: offset,
+		Limit:  limit,
+		Claims: items,
+	}
+
+	return page, nil
+}
+
+func (cr claimRepository) Reserve(ctx context.Context, code, user string, at time.Time) (provision.Claim, error) {
+	q := `UPDATE claims SET claimed_by = $2, claimed_at = $3 WHERE code = $1 AND claimed_by IS NULL
+	      RETURNING code, owner, external_id, external_key, name, cert, created, claimed_by, claimed_at, thing;`
+
+	var dbc dbClaim
+	if err := cr.db.QueryRowxContext(ctx, q, code, user, at).StructScan(&dbc); err != nil {
+		if err == sql.ErrNoRows {
+			return provision.Claim{}, provision.ErrNotFound
+		}
+		return provision.Claim{}, err
+	}
+
+	return toClaim(dbc), nil
+}
+
+func (cr claimRepository) Release(ctx context.Context, code string) error {
+	q := `UPDATE claims SET claimed_by = NULL, claimed_at = NULL, thing = NULL WHERE code = $1;`
+
+	res, err := cr.db.ExecContext(ctx, q, code)
+	if err != nil {
+		return err
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if cnt == 0 {
+		return provision.ErrNotFound
+	}
+
+	return nil
+}
+
+func (cr claimRepository) Complete(ctx context.Context, code, thingID string) error {
+	q := `UPDATE claims SET thing = $2 WHERE code = $1 AND claimed_by IS NOT NULL;`
+
+	res, err := cr.db.ExecContext(ctx, q, code, thingID)
+	if err != nil {
+		return err
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if cnt == 0 {
+		return provision.ErrNotFound
+	}
+
+	return nil
+}
+
+type dbClaim struct {
+	Code        string         `db:"code"`
+	Owner       string         `db:"owner"`
+	ExternalID  string         `db:"external_id"`
+	ExternalKey string         `db:"external_key"`
+	Name        sql.NullString `db:"name"`
+	Cert        bool           `db:"cert"`
+	Created     time.Time      `db:"created"`
+	ClaimedBy   sql.NullString `db:"claimed_by"`
+	ClaimedAt   pq.NullTime    `db:"claimed_at"`
+	Thing       sql.NullString `db:"thing"`
+}
+
+func toDBClaim(c provision.Claim) dbClaim {
+	return dbClaim{
+		Code:        c.Code,
+		Owner:       c.Owner,
+		ExternalID:  c.ExternalID,
+		ExternalKey: c.ExternalKey,
+		Name:        sql.NullString{String: c.Name, Valid: c.Name != ""},
+		Cert:        c.Cert,
+		Created:     c.Created,
+		ClaimedBy:   sql.NullString{String: c.ClaimedBy, Valid: c.ClaimedBy != ""},
+		ClaimedAt:   pq.NullTime{Time: c.ClaimedAt, Valid: !c.ClaimedAt.IsZero()},
+		Thing:       sql.NullString{String: c.ThingID, Valid: c.ThingID != ""},
+	}
+}
+
+func toClaim(dbc dbClaim) provision.Claim {
+	c := provision.Claim{
+		Code:        dbc.Code,
+		Owner:       dbc.Owner,
+		ExternalID:  dbc.ExternalID,
+		ExternalKey: dbc.ExternalKey,
+		Name:        dbc.Name.String,
+		Cert:        dbc.Cert,
+		Created:     dbc.Created.UTC(),
+		ClaimedBy:   dbc.ClaimedBy.String,
+		ThingID:     dbc.Thing.String,
+	}
+
+	if dbc.ClaimedAt.Valid {
+		c.ClaimedAt = dbc.ClaimedAt.Time.UTC()
+	}
+
+	return c
+}
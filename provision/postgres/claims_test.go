@@ -0,0 +1,174 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/provision"
+	"github.com/mainflux/mainflux/provision/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	owner      = "manufacturer@example.com"
+	user       = "user@example.com"
+	wrongValue = "wrong-value"
+)
+
+func newClaim(i int) provision.Claim {
+	return provision.Claim{
+		Code:        fmt.Sprintf("code-%d", i),
+		Owner:       owner,
+		ExternalID:  fmt.Sprintf("ext-%d", i),
+		ExternalKey: "key",
+		Created:     time.Now().UTC().Truncate(time.Millisecond),
+	}
+}
+
+func cleanup() {
+	db.Exec("DELETE FROM claims")
+}
+
+func TestClaimSave(t *testing.T) {
+	defer cleanup()
+	repo := postgres.NewClaimRepository(db)
+
+	existing := newClaim(0)
+	err := repo.Save(context.Background(), existing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	sameExternalID := newClaim(3)
+	sameExternalID.ExternalID = existing.ExternalID
+
+	cases := []struct {
+		desc   string
+		claims []provision.Claim
+		err    error
+	}{
+		{
+			desc:   "save new claims",
+			claims: []provision.Claim{newClaim(1), newClaim(2)},
+			err:    nil,
+		},
+		{
+			desc:   "save claim with existing code",
+			claims: []provision.Claim{newClaim(4), existing},
+			err:    provision.ErrConflict,
+		},
+		{
+			desc:   "save claim with existing external ID",
+			claims: []provision.Claim{sameExternalID},
+			err:    provision.ErrConflict,
+		},
+	}
+
+	for _, tc := range cases {
+		err := repo.Save(context.Background(), tc.claims...)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	page, err := repo.RetrieveAll(context.Background(), owner, 0, 10)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, uint64(3), page.Total, fmt.Sprintf("expected 3 claims got %d\n", page.Total))
+}
+
+func TestClaimRetrieveAll(t *testing.T) {
+	defer cleanup()
+	repo := postgres.NewClaimRepository(db)
+
+	n := 10
+	for i := 0; i < n; i++ {
+		err := repo.Save(context.Background(), newClaim(i))
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	cases := []struct {
+		desc   string
+		owner  string
+		offset uint64
+		limit  uint64
+		size   int
+		total  uint64
+	}{
+		{
+			desc:   "retrieve all claims",
+			owner:  owner,
+			offset: 0,
+			limit:  uint64(n),
+			size:   n,
+			total:  uint64(n),
+		},
+		{
+			desc:   "retrieve subset of claims",
+			owner:  owner,
+			offset: 5,
+			limit:  3,
+			size:   3,
+			total:  uint64(n),
+		},
+		{
+			desc:   "retrieve claims of other owner",
+			owner:  wrongValue,
+			offset: 0,
+			limit:  uint64(n),
+			size:   0,
+			total:  0,
+		},
+	}
+
+	for _, tc := range cases {
+		page, err := repo.RetrieveAll(context.Background(), tc.owner, tc.offset, tc.limit)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		assert.Len(t, page.Claims, tc.size, fmt.Sprintf("%s: expected %d claims got %d\n", tc.desc, tc.size, len(page.Claims)))
+		assert.Equal(t, tc.total, page.Total, fmt.Sprintf("%s: expected total %d got %d\n", tc.desc, tc.total, page.Total))
+	}
+}
+
+func TestClaimReserve(t *testing.T) {
+	defer cleanup()
+	repo := postgres.NewClaimRepository(db)
+
+	c := newClaim(0)
+	err := repo.Save(context.Background(), c)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	reserved, err := repo.Reserve(context.Background(), c.Code, user, now)
+	require.Nil(t, err, fmt.Sprintf("reserve claim: unexpected error: %s", err))
+	assert.Equal(t, c.ExternalKey, reserved.ExternalKey, fmt.Sprintf("reserve claim: expected external key %s got %s\n", c.ExternalKey, reserved.ExternalKey))
+	assert.Equal(t, user, reserved.ClaimedBy, fmt.Sprintf("reserve claim: expected claimed by %s got %s\n", user, reserved.ClaimedBy))
+	assert.Equal(t, now, reserved.ClaimedAt, fmt.Sprintf("reserve claim: expected claimed at %s got %s\n", now, reserved.ClaimedAt))
+
+	_, err = repo.Reserve(context.Background(), c.Code, user, now)
+	assert.Equal(t, provision.ErrNotFound, err, fmt.Sprintf("reserve reserved claim: expected %s got %s\n", provision.ErrNotFound, err))
+
+	_, err = repo.Reserve(context.Background(), wrongValue, user, now)
+	assert.Equal(t, provision.ErrNotFound, err, fmt.Sprintf("reserve unknown claim: expected %s got %s\n", provision.ErrNotFound, err))
+
+	err = repo.Release(context.Background(), c.Code)
+	assert.Nil(t, err, fmt.Sprintf("release claim: unexpected error: %s", err))
+
+	err = repo.Release(context.Background(), wrongValue)
+	assert.Equal(t, provision.ErrNotFound, err, fmt.Sprintf("release unknown claim: expected %s got %s\n", provision.ErrNotFound, err))
+
+	err = repo.Complete(context.Background(), c.Code, "thing")
+	assert.Equal(t, provision.ErrNotFound, err, fmt.Sprintf("complete released claim: expected %s got %s\n", provision.ErrNotFound, err))
+
+	_, err = repo.Reserve(context.Background(), c.Code, user, now)
+	require.Nil(t, err, fmt.Sprintf("reserve released claim: unexpected error: %s", err))
+
+	err = repo.Complete(context.Background(), c.Code, "thing")
+	assert.Nil(t, err, fmt.Sprintf("complete claim: unexpected error: %s", err))
+
+	page, err := repo.RetrieveAll(context.Background(), owner, 0, 10)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	require.Len(t, page.Claims, 1, fmt.Sprintf("expected 1 claim got %d\n", len(page.Claims)))
+	assert.Equal(t, "thing", page.Claims[0].ThingID, fmt.Sprintf("expected claimed thing %s got %s\n", "thing", page.Claims[0].ThingID))
+}
@@ -0,0 +1,74 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq" // required for SQL access
+	migrate "github.com/rubenv/sql-migrate"
+)
+
+// Config defines the options that are used when connecting to a PostgreSQL instance
+type Config struct {
+	Host        string
+	Port        string
+	User        string
+	Pass        string
+	Name        string
+	SSLMode     string
+	SSLCert     string
+	SSLKey      string
+	SSLRootCert string
+}
+
+// Connect creates a connection to the PostgreSQL instance and applies any
+// unapplied database migrations. A non-nil error is returned to indicate
+// failure.
+func Connect(cfg Config) (*sqlx.DB, error) {
+	url := fmt.Sprintf("host=%s port=%s user=%s dbname=%s password=%s sslmode=%s sslcert=%s sslkey=%s sslrootcert=%s", cfg.Host, cfg.Port, cfg.User, cfg.Name, cfg.Pass, cfg.SSLMode, cfg.SSLCert, cfg.SSLKey, cfg.SSLRootCert)
+
+	db, err := sqlx.Open("postgres", url)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := migrateDB(db); err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+func migrateDB(db *sqlx.DB) error {
+	migrations := &migrate.MemoryMigrationSource{
+		Migrations: []*migrate.Migration{
+			{
+				Id: "provision_1",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS claims (
+						code         VARCHAR(254) PRIMARY KEY,
+						owner        VARCHAR(254) NOT NULL,
+						external_id  VARCHAR(254) NOT NULL UNIQUE,
+						external_key VARCHAR(254) NOT NULL,
+						name         VARCHAR(1024),
+						cert         BOOLEAN NOT NULL DEFAULT FALSE,
+						created      TIMESTAMPTZ NOT NULL,
+						claimed_by   VARCHAR(254),
+						claimed_at   TIMESTAMPTZ,
+						thing        VARCHAR(254)
+					)`,
+					`CREATE INDEX IF NOT EXISTS claims_owner_idx ON claims (owner)`,
+				},
+				Down: []string{
+					`DROP TABLE IF EXISTS claims`,
+				},
+			},
+		},
+	}
+
+	_, err := migrate.Exec(db.DB, "postgres", migrations, migrate.Up)
+	return err
+}
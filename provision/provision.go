@@ -0,0 +1,108 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package provision
+
+import "context"
+
+// Device contains the data the device is provisioned with. External ID and
+// key are the credentials the device fetches its bootstrap configuration
+// with.
+type Device struct {
+	ExternalID  string
+	ExternalKey string
+	Name        string
+	Cert        bool
+}
+
+// Thing represents the thing created for the device.
+type Thing struct {
+	ID       string
+	Key      string
+	Name     string
+	Metadata map[string]interface{}
+}
+
+// Channel represents the channel created for the device.
+type Channel struct {
+	ID       string
+	Name     string
+	Metadata map[string]interface{}
+}
+
+// Cert represents the client certificate issued to the thing. Private key
+// and issuing CA are PEM encoded.
+type Cert struct {
+	Serial      string
+	Certificate string
+	PrivateKey  string
+	IssuingCA   string
+}
+
+// BootstrapConfig represents the bootstrap configuration of the device.
+type BootstrapConfig struct {
+	ThingID     string
+	ExternalID  string
+	ExternalKey string
+	Name        string
+	Channels    []string
+	Content     string
+	ClientCert  string
+	ClientKey   string
+	CACert      string
+}
+
+// Bundle contains the credentials of the provisioned device.
+type Bundle struct {
+	ExternalID string
+	Thing      Thing
+	Channels   []Channel
+	Cert       Cert
+}
+
+// Config contains the template of the provisioned entities.
+type Config struct {
+	// Channels contains the types of the channels created for each device.
+	// Type is appended to the device name to form the channel name, and
+	// it's stored in the channel metadata.
+	Channels []string
+
+	// Content is the bootstrap configuration content of the devices.
+	Content string
+}
+
+// Things specifies an API of the things service used to create the thing
+// and the channels of the device.
+type Things interface {
+	// CreateThing creates the thing and returns it with its ID and key.
+	CreateThing(token string, th Thing) (Thing, error)
+
+	// RemoveThing permanently removes the thing.
+	RemoveThing(token, id string) error
+
+	// CreateChannel creates the channel and returns its ID.
+	CreateChannel(token string, ch Channel) (string, error)
+
+	// RemoveChannel permanently removes the channel.
+	RemoveChannel(token, id string) error
+
+	// Connect connects the thing to the channel.
+	Connect(token, thingID, chanID string) error
+}
+
+// Bootstrap specifies an API of the bootstrap service used to store the
+// bootstrap configuration of the device.
+type Bootstrap interface {
+	// Add stores the bootstrap configuration.
+	Add(ctx context.Context, token string, cfg BootstrapConfig) error
+}
+
+// Certs specifies an API of the certs service used to issue the client
+// certificate of the device.
+type Certs interface {
+	// Issue issues the certificate to the thing.
+	Issue(ctx context.Context, token, thingID string) (Cert, error)
+
+	// Revoke revokes the certificate having the provided serial number.
+	Revoke(ctx context.Context, token, serial string) error
+}
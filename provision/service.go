@@ -0,0 +1,286 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package provision
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mainflux/mainflux"
+)
+
+const (
+	externalIDKey = "external_id"
+	typeKey       = "type"
+)
+
+var (
+	// ErrMalformedEntity indicates malformed entity specification.
+	ErrMalformedEntity = errors.New("malformed entity specification")
+
+	// ErrUnauthorizedAccess indicates missing or invalid credentials provided
+	// when accessing a protected resource.
+	ErrUnauthorizedAccess = errors.New("missing or invalid credentials provided")
+
+	// ErrNotFound indicates a non-existent entity request.
+	ErrNotFound = errors.New("non-existent entity")
+
+	// ErrConflict indicates that the device with the same external ID is
+	// already provisioned, or that the claim with the same code or external
+	// ID already exists.
+	ErrConflict = errors.New("entity already exists")
+
+	// ErrCertsDisabled indicates that the certificate is requested, but the
+	// service isn't configured to issue certificates.
+	ErrCertsDisabled = errors.New("certificates issuing is disabled")
+)
+
+// Service specifies an API that must be fulfilled by the domain service
+// implementation, and all of its decorators (e.g. logging & metrics).
+type Service interface {
+	// Provision creates the thing, channels, connections, bootstrap
+	// configuration and optionally the certificate of the device, on behalf
+	// of the user identified by the provided key. Entities created before a
+	// failed step are removed.
+	Provision(ctx context.Context, token string, dev Device) (Bundle, error)
+
+	// AddClaims preloads the claims of the devices on behalf of their
+	// manufacturer, identified by the provided key. Either all the claims
+	// are added, or none of them.
+	AddClaims(ctx context.Context, token string, claims []Claim) error
+
+	// ListClaims retrieves the subset of claims preloaded by the
+	// manufacturer identified by the provided key.
+	ListClaims(ctx context.Context, token string, offset, limit uint64) (ClaimsPage, error)
+
+	// Claim provisions the device having the provided claim code into the
+	// account of the user identified by the provided key. The claim can't
+	// be used again, unless the provisioning fails.
+	Claim(ctx context.Context, token, code string) (Bundle, error)
+}
+
+var _ Service = (*provisionService)(nil)
+
+type provisionService struct {
+	users     mainflux.UsersServiceClient
+	things    Things
+	bootstrap Bootstrap
+	certs     Certs
+	claims    ClaimRepository
+	cfg       Config
+}
+
+// New instantiates the provision service implementation. Certs client can
+// be nil, in which case the certificates can't be requested.
+func New(users mainflux.UsersServiceClient, things Things, bootstrap Bootstrap, certs Certs, claims ClaimRepository, cfg Config) Service {
+	return &provisionService{
+		users:     users,
+		things:    things,
+		bootstrap: bootstrap,
+		certs:     certs,
+		claims:    claims,
+		cfg:       cfg,
+	}
+}
+
+func (ps *provisionService) Provision(ctx context.Context, token string, dev Device) (Bundle, error) {
+	if token == "" {
+		return Bundle{}, ErrUnauthorizedAccess
+	}
+
+	if dev.ExternalID == "" || dev.ExternalKey == "" {
+		return Bundle{}, ErrMalformedEntity
+	}
+
+	if dev.Cert && ps.certs == nil {
+		return Bundle{}, ErrCertsDisabled
+	}
+
+	if dev.Name == "" {
+		dev.Name = dev.ExternalID
+	}
+
+	th := Thing{
+		Name: dev.Name,
+		Metadata: map[string]interface{}{
+			externalIDKey: dev.ExternalID,
+		},
+	}
+
+	th, err := ps.things.CreateThing(token, th)
+	if err != nil {
+		return Bundle{}, err
+	}
+
+	b := Bundle{
+		ExternalID: dev.ExternalID,
+		Thing:      th,
+		Channels:   []Channel{},
+	}
+
+	for _, typ := range ps.cfg.Channels {
+		ch := Channel{
+			Name: fmt.Sprintf("%s-%s", dev.Name, typ),
+			Metadata: map[string]interface{}{
+				externalIDKey: dev.ExternalID,
+				typeKey:       typ,
+			},
+		}
+
+		ch.ID, err = ps.things.CreateChannel(token, ch)
+		if err != nil {
+			ps.rollback(ctx, token, b)
+			return Bundle{}, err
+		}
+		b.Channels = append(b.Channels, ch)
+
+		if err := ps.things.Connect(token, th.ID, ch.ID); err != nil {
+			ps.rollback(ctx, token, b)
+			return Bundle{}, err
+		}
+	}
+
+	if dev.Cert {
+		b.Cert, err = ps.certs.Issue(ctx, token, th.ID)
+		if err != nil {
+			ps.rollback(ctx, token, b)
+			return Bundle{}, err
+		}
+	}
+
+	cfg := BootstrapConfig{
+		ThingID:     th.ID,
+		ExternalID:  dev.ExternalID,
+		ExternalKey: dev.ExternalKey,
+		Name:        dev.Name,
+		Channels:    []string{},
+		Content:     ps.cfg.Content,
+		ClientCert:  b.Cert.Certificate,
+		ClientKey:   b.Cert.PrivateKey,
+		CACert:      b.Cert.IssuingCA,
+	}
+	for _, ch := range b.Channels {
+		cfg.Channels = append(cfg.Channels, ch.ID)
+	}
+
+	if err := ps.bootstrap.Add(ctx, token, cfg); err != nil {
+		ps.rollback(ctx, token, b)
+		return Bundle{}, err
+	}
+
+	return b, nil
+}
+
+func (ps *provisionService) AddClaims(ctx context.Context, token string, claims []Claim) error {
+	owner, err := ps.identify(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	if len(claims) == 0 {
+		return ErrMalformedEntity
+	}
+
+	codes := make(map[string]bool)
+	ids := make(map[string]bool)
+	now := time.Now().UTC()
+	for i, c := range claims {
+		if c.Code == "" || c.ExternalID == "" || c.ExternalKey == "" {
+			return ErrMalformedEntity
+		}
+
+		if c.Cert && ps.certs == nil {
+			return ErrCertsDisabled
+		}
+
+		if codes[c.Code] || ids[c.ExternalID] {
+			return ErrConflict
+		}
+		codes[c.Code] = true
+		ids[c.ExternalID] = true
+
+		claims[i] = Claim{
+			Code:        c.Code,
+			Owner:       owner,
+			ExternalID:  c.ExternalID,
+			ExternalKey: c.ExternalKey,
+			Name:        c.Name,
+			Cert:        c.Cert,
+			Created:     now,
+		}
+	}
+
+	return ps.claims.Save(ctx, claims...)
+}
+
+func (ps *provisionService) ListClaims(ctx context.Context, token string, offset, limit uint64) (ClaimsPage, error) {
+	owner, err := ps.identify(ctx, token)
+	if err != nil {
+		return ClaimsPage{}, err
+	}
+
+	return ps.claims.RetrieveAll(ctx, owner, offset, limit)
+}
+
+func (ps *provisionService) Claim(ctx context.Context, token, code string) (Bundle, error) {
+	user, err := ps.identify(ctx, token)
+	if err != nil {
+		return Bundle{}, err
+	}
+
+	if code == "" {
+		return Bundle{}, ErrMalformedEntity
+	}
+
+	// Claim is reserved before provisioning, so that concurrent requests
+	// can't use the same code twice.
+	c, err := ps.claims.Reserve(ctx, code, user, time.Now().UTC())
+	if err != nil {
+		return Bundle{}, err
+	}
+
+	dev := Device{
+		ExternalID:  c.ExternalID,
+		ExternalKey: c.ExternalKey,
+		Name:        c.Name,
+		Cert:        c.Cert,
+	}
+
+	b, err := ps.Provision(ctx, token, dev)
+	if err != nil {
+		ps.claims.Release(ctx, code)
+		return Bundle{}, err
+	}
+
+	// Device is provisioned at this point, and the claim stays reserved
+	// even if the thing can't be recorded, so the error is not reported.
+	ps.claims.Complete(ctx, code, b.Thing.ID)
+
+	return b, nil
+}
+
+func (ps *provisionService) identify(ctx context.Context, token string) (string, error) {
+	res, err := ps.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return "", ErrUnauthorizedAccess
+	}
+
+	return res.GetValue(), nil
+}
+
+// rollback removes the entities created for the device. Removal fails
+// silently, since the original error is the one reported to the caller.
+func (ps *provisionService) rollback(ctx context.Context, token string, b Bundle) {
+	if b.Cert.Serial != "" {
+		ps.certs.Revoke(ctx, token, b.Cert.Serial)
+	}
+
+	for _, ch := range b.Channels {
+		ps.things.RemoveChannel(token, ch.ID)
+	}
+
+	ps.things.RemoveThing(token, b.Thing.ID)
+}
@@ -0,0 +1,461 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package provision_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux/provision"
+	"github.com/mainflux/mainflux/provision/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	wrongValue = "wrong-value"
+	token      = "token"
+	email      = "user@example.com"
+	mfToken    = "manufacturer"
+	mfEmail    = "manufacturer@example.com"
+	content    = `{"interval":10}`
+)
+
+var (
+	cfg = provision.Config{
+		Channels: []string{"control", "data"},
+		Content:  content,
+	}
+	device = provision.Device{
+		ExternalID:  "02:42:ac:11:00:02",
+		ExternalKey: "external-key",
+		Name:        "gateway",
+	}
+)
+
+type deps struct {
+	things    *mocks.Things
+	bootstrap *mocks.Bootstrap
+	certs     *mocks.Certs
+}
+
+func newService() (provision.Service, deps) {
+	d := deps{
+		things:    mocks.NewThings(token),
+		bootstrap: mocks.NewBootstrap(),
+		certs:     mocks.NewCerts(),
+	}
+
+	users := mocks.NewUsersService(map[string]string{token: email, mfToken: mfEmail})
+
+	return provision.New(users, d.things, d.bootstrap, d.certs, mocks.NewClaimRepository(), cfg), d
+}
+
+func TestProvision(t *testing.T) {
+	svc, d := newService()
+
+	noName := device
+	noName.ExternalID = "no-name"
+	noName.Name = ""
+	withCert := device
+	withCert.ExternalID = "with-cert"
+	withCert.Cert = true
+	noExternalID := device
+	noExternalID.ExternalID = ""
+	noExternalKey := device
+	noExternalKey.ExternalKey = ""
+
+	cases := []struct {
+		desc  string
+		token string
+		dev   provision.Device
+		name  string
+		cert  bool
+		err   error
+	}{
+		{
+			desc:  "provision device",
+			token: token,
+			dev:   device,
+			name:  device.Name,
+			err:   nil,
+		},
+		{
+			desc:  "provision device without name",
+			token: token,
+			dev:   noName,
+			name:  noName.ExternalID,
+			err:   nil,
+		},
+		{
+			desc:  "provision device with certificate",
+			token: token,
+			dev:   withCert,
+			name:  withCert.Name,
+			cert:  true,
+			err:   nil,
+		},
+		{
+			desc:  "provision already provisioned device",
+			token: token,
+			dev:   device,
+			err:   provision.ErrConflict,
+		},
+		{
+			desc:  "provision device with wrong credentials",
+			token: wrongValue,
+			dev:   provision.Device{ExternalID: "other", ExternalKey: "other"},
+			err:   provision.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "provision device with empty token",
+			token: "",
+			dev:   provision.Device{ExternalID: "other", ExternalKey: "other"},
+			err:   provision.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "provision device without external ID",
+			token: token,
+			dev:   noExternalID,
+			err:   provision.ErrMalformedEntity,
+		},
+		{
+			desc:  "provision device without external key",
+			token: token,
+			dev:   noExternalKey,
+			err:   provision.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		b, err := svc.Provision(context.Background(), tc.token, tc.dev)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err != nil {
+			continue
+		}
+
+		assert.Equal(t, tc.dev.ExternalID, b.ExternalID, fmt.Sprintf("%s: expected external ID %s got %s\n", tc.desc, tc.dev.ExternalID, b.ExternalID))
+		assert.Equal(t, tc.name, b.Thing.Name, fmt.Sprintf("%s: expected thing name %s got %s\n", tc.desc, tc.name, b.Thing.Name))
+		assert.NotEmpty(t, b.Thing.Key, fmt.Sprintf("%s: expected thing key to be set\n", tc.desc))
+		require.Len(t, b.Channels, len(cfg.Channels), fmt.Sprintf("%s: expected %d channels got %d\n", tc.desc, len(cfg.Channels), len(b.Channels)))
+
+		ids := []string{}
+		for i, ch := range b.Channels {
+			name := fmt.Sprintf("%s-%s", tc.name, cfg.Channels[i])
+			assert.Equal(t, name, ch.Name, fmt.Sprintf("%s: expected channel name %s got %s\n", tc.desc, name, ch.Name))
+			assert.Equal(t, cfg.Channels[i], ch.Metadata["type"], fmt.Sprintf("%s: expected channel type %s got %v\n", tc.desc, cfg.Channels[i], ch.Metadata["type"]))
+			ids = append(ids, ch.ID)
+		}
+		assert.ElementsMatch(t, ids, d.things.Connected(b.Thing.ID), fmt.Sprintf("%s: expected thing to be connected to the channels\n", tc.desc))
+
+		bc, ok := d.bootstrap.Config(tc.dev.ExternalID)
+		require.True(t, ok, fmt.Sprintf("%s: expected bootstrap config to be stored\n", tc.desc))
+		assert.Equal(t, b.Thing.ID, bc.ThingID, fmt.Sprintf("%s: expected bootstrap thing %s got %s\n", tc.desc, b.Thing.ID, bc.ThingID))
+		assert.Equal(t, tc.dev.ExternalKey, bc.ExternalKey, fmt.Sprintf("%s: expected bootstrap external key %s got %s\n", tc.desc, tc.dev.ExternalKey, bc.ExternalKey))
+		assert.Equal(t, ids, bc.Channels, fmt.Sprintf("%s: expected bootstrap channels %v got %v\n", tc.desc, ids, bc.Channels))
+		assert.Equal(t, content, bc.Content, fmt.Sprintf("%s: expected bootstrap content %s got %s\n", tc.desc, content, bc.Content))
+
+		assert.Equal(t, tc.cert, b.Cert.Serial != "", fmt.Sprintf("%s: expected certificate issued to be %t\n", tc.desc, tc.cert))
+		assert.Equal(t, b.Cert.Certificate, bc.ClientCert, fmt.Sprintf("%s: expected bootstrap client cert %s got %s\n", tc.desc, b.Cert.Certificate, bc.ClientCert))
+		assert.Equal(t, b.Cert.PrivateKey, bc.ClientKey, fmt.Sprintf("%s: expected bootstrap client key %s got %s\n", tc.desc, b.Cert.PrivateKey, bc.ClientKey))
+	}
+}
+
+func TestProvisionRollback(t *testing.T) {
+	errFailed := errors.New("failed")
+
+	cases := []struct {
+		desc    string
+		dev     provision.Device
+		fail    func(deps)
+		revoked int
+		err     error
+	}{
+		{
+			desc: "provision device failing to connect",
+			dev:  device,
+			fail: func(d deps) { d.things.Fail(errFailed) },
+			err:  errFailed,
+		},
+		{
+			desc: "provision device failing to issue certificate",
+			dev:  provision.Device{ExternalID: device.ExternalID, ExternalKey: device.ExternalKey, Cert: true},
+			fail: func(d deps) { d.certs.Fail(errFailed) },
+			err:  errFailed,
+		},
+		{
+			desc: "provision device failing to store bootstrap config",
+			dev:  provision.Device{ExternalID: device.ExternalID, ExternalKey: device.ExternalKey, Cert: true},
+			fail: func(d deps) {
+				d.bootstrap.Add(context.Background(), token, provision.BootstrapConfig{ExternalID: device.ExternalID})
+			},
+			revoked: 1,
+			err:     provision.ErrConflict,
+		},
+	}
+
+	for _, tc := range cases {
+		svc, d := newService()
+		tc.fail(d)
+
+		_, err := svc.Provision(context.Background(), token, tc.dev)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+
+		things, channels, conns := d.things.Count()
+		assert.Zero(t, things, fmt.Sprintf("%s: expected no things got %d\n", tc.desc, things))
+		assert.Zero(t, channels, fmt.Sprintf("%s: expected no channels got %d\n", tc.desc, channels))
+		assert.Zero(t, conns, fmt.Sprintf("%s: expected no connections got %d\n", tc.desc, conns))
+		assert.Len(t, d.certs.Revoked(), tc.revoked, fmt.Sprintf("%s: expected %d revoked certificates got %d\n", tc.desc, tc.revoked, len(d.certs.Revoked())))
+	}
+}
+
+func TestProvisionCertsDisabled(t *testing.T) {
+	users := mocks.NewUsersService(map[string]string{token: email})
+	things := mocks.NewThings(token)
+	svc := provision.New(users, things, mocks.NewBootstrap(), nil, mocks.NewClaimRepository(), cfg)
+
+	dev := device
+	dev.Cert = true
+	_, err := svc.Provision(context.Background(), token, dev)
+	assert.Equal(t, provision.ErrCertsDisabled, err, fmt.Sprintf("provision device with certificate: expected %s got %s\n", provision.ErrCertsDisabled, err))
+
+	n, _, _ := things.Count()
+	assert.Zero(t, n, fmt.Sprintf("provision device with certificate: expected no things got %d\n", n))
+}
+
+func TestAddClaims(t *testing.T) {
+	svc, _ := newService()
+
+	claim := provision.Claim{Code: "code", ExternalID: "ext", ExternalKey: "key"}
+	err := svc.AddClaims(context.Background(), mfToken, []provision.Claim{claim})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc   string
+		token  string
+		claims []provision.Claim
+		err    error
+	}{
+		{
+			desc:  "add claims",
+			token: mfToken,
+			claims: []provision.Claim{
+				{Code: "code-1", ExternalID: "ext-1", ExternalKey: "key"},
+				{Code: "code-2", ExternalID: "ext-2", ExternalKey: "key", Name: "gateway", Cert: true},
+			},
+			err: nil,
+		},
+		{
+			desc:   "add claim with existing code",
+			token:  mfToken,
+			claims: []provision.Claim{{Code: claim.Code, ExternalID: "ext-3", ExternalKey: "key"}},
+			err:    provision.ErrConflict,
+		},
+		{
+			desc:   "add claim with existing external ID",
+			token:  mfToken,
+			claims: []provision.Claim{{Code: "code-3", ExternalID: claim.ExternalID, ExternalKey: "key"}},
+			err:    provision.ErrConflict,
+		},
+		{
+			desc:  "add claims with duplicate codes",
+			token: mfToken,
+			claims: []provision.Claim{
+				{Code: "code-3", ExternalID: "ext-3", ExternalKey: "key"},
+				{Code: "code-3", ExternalID: "ext-4", ExternalKey: "key"},
+			},
+			err: provision.ErrConflict,
+		},
+		{
+			desc:   "add claim without code",
+			token:  mfToken,
+			claims: []provision.Claim{{ExternalID: "ext-3", ExternalKey: "key"}},
+			err:    provision.ErrMalformedEntity,
+		},
+		{
+			desc:   "add claim without external key",
+			token:  mfToken,
+			claims: []provision.Claim{{Code: "code-3", ExternalID: "ext-3"}},
+			err:    provision.ErrMalformedEntity,
+		},
+		{
+			desc:   "add no claims",
+			token:  mfToken,
+			claims: []provision.Claim{},
+			err:    provision.ErrMalformedEntity,
+		},
+		{
+			desc:   "add claims with wrong credentials",
+			token:  wrongValue,
+			claims: []provision.Claim{{Code: "code-3", ExternalID: "ext-3", ExternalKey: "key"}},
+			err:    provision.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.AddClaims(context.Background(), tc.token, tc.claims)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	page, err := svc.ListClaims(context.Background(), mfToken, 0, 10)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.Equal(t, uint64(3), page.Total, fmt.Sprintf("expected 3 claims got %d\n", page.Total))
+}
+
+func TestListClaims(t *testing.T) {
+	svc, _ := newService()
+
+	n := 10
+	claims := []provision.Claim{}
+	for i := 0; i < n; i++ {
+		claims = append(claims, provision.Claim{
+			Code:        fmt.Sprintf("code-%d", i),
+			ExternalID:  fmt.Sprintf("ext-%d", i),
+			ExternalKey: "key",
+		})
+	}
+	err := svc.AddClaims(context.Background(), mfToken, claims)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc   string
+		token  string
+		offset uint64
+		limit  uint64
+		size   int
+		total  uint64
+		err    error
+	}{
+		{
+			desc:   "list all claims",
+			token:  mfToken,
+			offset: 0,
+			limit:  uint64(n),
+			size:   n,
+			total:  uint64(n),
+			err:    nil,
+		},
+		{
+			desc:   "list half of the claims",
+			token:  mfToken,
+			offset: uint64(n / 2),
+			limit:  uint64(n),
+			size:   n / 2,
+			total:  uint64(n),
+			err:    nil,
+		},
+		{
+			desc:   "list claims of other user",
+			token:  token,
+			offset: 0,
+			limit:  uint64(n),
+			size:   0,
+			total:  0,
+			err:    nil,
+		},
+		{
+			desc:   "list claims with wrong credentials",
+			token:  wrongValue,
+			offset: 0,
+			limit:  uint64(n),
+			err:    provision.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		page, err := svc.ListClaims(context.Background(), tc.token, tc.offset, tc.limit)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Len(t, page.Claims, tc.size, fmt.Sprintf("%s: expected %d claims got %d\n", tc.desc, tc.size, len(page.Claims)))
+		assert.Equal(t, tc.total, page.Total, fmt.Sprintf("%s: expected total %d got %d\n", tc.desc, tc.total, page.Total))
+	}
+}
+
+func TestClaim(t *testing.T) {
+	svc, d := newService()
+
+	claim := provision.Claim{Code: "code", ExternalID: "ext", ExternalKey: "key", Name: "gateway", Cert: true}
+	failing := provision.Claim{Code: "failing", ExternalID: "failing", ExternalKey: "key"}
+	err := svc.AddClaims(context.Background(), mfToken, []provision.Claim{claim, failing})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	// Bootstrap config of the failing device already exists, so
+	// provisioning it fails.
+	d.bootstrap.Add(context.Background(), token, provision.BootstrapConfig{ExternalID: failing.ExternalID})
+
+	cases := []struct {
+		desc  string
+		token string
+		code  string
+		err   error
+	}{
+		{
+			desc:  "claim device with wrong credentials",
+			token: wrongValue,
+			code:  claim.Code,
+			err:   provision.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "claim device",
+			token: token,
+			code:  claim.Code,
+			err:   nil,
+		},
+		{
+			desc:  "claim already claimed device",
+			token: token,
+			code:  claim.Code,
+			err:   provision.ErrNotFound,
+		},
+		{
+			desc:  "claim device with unknown code",
+			token: token,
+			code:  wrongValue,
+			err:   provision.ErrNotFound,
+		},
+		{
+			desc:  "claim device without code",
+			token: token,
+			code:  "",
+			err:   provision.ErrMalformedEntity,
+		},
+		{
+			desc:  "claim device failing to provision",
+			token: token,
+			code:  failing.Code,
+			err:   provision.ErrConflict,
+		},
+		{
+			desc:  "claim device failing to provision again",
+			token: token,
+			code:  failing.Code,
+			err:   provision.ErrConflict,
+		},
+	}
+
+	for _, tc := range cases {
+		b, err := svc.Claim(context.Background(), tc.token, tc.code)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err != nil {
+			continue
+		}
+
+		assert.Equal(t, claim.ExternalID, b.ExternalID, fmt.Sprintf("%s: expected external ID %s got %s\n", tc.desc, claim.ExternalID, b.ExternalID))
+		assert.Equal(t, claim.Name, b.Thing.Name, fmt.Sprintf("%s: expected thing name %s got %s\n", tc.desc, claim.Name, b.Thing.Name))
+		assert.NotEmpty(t, b.Cert.Serial, fmt.Sprintf("%s: expected certificate to be issued\n", tc.desc))
+	}
+
+	page, err := svc.ListClaims(context.Background(), mfToken, 0, 10)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	for _, c := range page.Claims {
+		switch c.Code {
+		case claim.Code:
+			assert.Equal(t, email, c.ClaimedBy, fmt.Sprintf("expected claim to be claimed by %s got %s\n", email, c.ClaimedBy))
+			assert.NotEmpty(t, c.ThingID, "expected claimed thing to be recorded\n")
+		case failing.Code:
+			assert.False(t, c.Claimed(), "expected failed claim to be released\n")
+		}
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package things contains the things and channels management backed by the
+// Mainflux SDK.
+package things
+
+import (
+	"github.com/mainflux/mainflux/provision"
+	mfsdk "github.com/mainflux/mainflux/sdk/go"
+)
+
+var _ provision.Things = (*things)(nil)
+
+type things struct {
+	sdk mfsdk.SDK
+}
+
+// New returns things API client backed by the provided SDK.
+func New(sdk mfsdk.SDK) provision.Things {
+	return things{sdk: sdk}
+}
+
+func (t things) CreateThing(token string, th provision.Thing) (provision.Thing, error) {
+	id, err := t.sdk.CreateThing(mfsdk.Thing{Name: th.Name, Metadata: th.Metadata}, token)
+	if err != nil {
+		return provision.Thing{}, toError(err)
+	}
+
+	// Thing key is generated by the things service, so the created thing
+	// is retrieved to obtain it.
+	created, err := t.sdk.Thing(id, token)
+	if err != nil {
+		t.sdk.PurgeThing(id, token)
+		return provision.Thing{}, toError(err)
+	}
+
+	th.ID = created.ID
+	th.Key = created.Key
+	return th, nil
+}
+
+func (t things) RemoveThing(token, id string) error {
+	return toError(t.sdk.PurgeThing(id, token))
+}
+
+func (t things) CreateChannel(token string, ch provision.Channel) (string, error) {
+	id, err := t.sdk.CreateChannel(mfsdk.Channel{Name: ch.Name, Metadata: ch.Metadata}, token)
+	return id, toError(err)
+}
+
+func (t things) RemoveChannel(token, id string) error {
+	return toError(t.sdk.PurgeChannel(id, token))
+}
+
+func (t things) Connect(token, thingID, chanID string) error {
+	return toError(t.sdk.ConnectThing(thingID, chanID, token))
+}
+
+func toError(err error) error {
+	switch err {
+	case nil:
+		return nil
+	case mfsdk.ErrUnauthorized:
+		return provision.ErrUnauthorizedAccess
+	case mfsdk.ErrInvalidArgs:
+		return provision.ErrMalformedEntity
+	default:
+		return err
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 
 	"github.com/go-kit/kit/endpoint"
+	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/readers"
 )
 
@@ -18,16 +19,88 @@ func listMessagesEndpoint(svc readers.MessageRepository) endpoint.Endpoint {
 			return nil, err
 		}
 
+		// The messages are read while the response is encoded, so they are
+		// written out as they arrive instead of being buffered.
+		if req.format != "" {
+			return streamRes{
+				format: req.format,
+				read: func(fn func(mainflux.Message) error) error {
+					return svc.ReadStream(req.chanID, req.query, fn)
+				},
+			}, nil
+		}
+
 		page, err := svc.ReadAll(req.chanID, req.offset, req.limit, req.query)
 		if err != nil {
 			return nil, err
 		}
 
-		return pageRes{
+		res := pageRes{
 			Total:    page.Total,
 			Offset:   page.Offset,
 			Limit:    page.Limit,
 			Messages: page.Messages,
-		}, nil
+		}
+		for _, b := range page.Buckets {
+			res.Buckets = append(res.Buckets, bucketRes{Time: b.Time, Value: b.Value})
+		}
+
+		return res, nil
+	}
+}
+
+func listSubtopicsEndpoint(repo readers.SubtopicRepository) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(listSubtopicsReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		subtopics, err := repo.RetrieveSubtopics(req.chanID)
+		if err != nil {
+			return nil, err
+		}
+
+		res := subtopicsRes{Subtopics: []subtopicRes{}}
+		for _, st := range subtopics {
+			res.Subtopics = append(res.Subtopics, subtopicRes{Name: st.Name, LastSeen: st.LastSeen})
+		}
+
+		return res, nil
+	}
+}
+
+func queryMessagesEndpoint(svc readers.MessageRepository) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(queryMessagesReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		res, err := svc.Query(req.chanID, req.query())
+		if err != nil {
+			return nil, err
+		}
+
+		if req.Aggregation == "" {
+			return queryRes{
+				Offset:   res.Offset,
+				Limit:    res.Limit,
+				Messages: res.Messages,
+			}, nil
+		}
+
+		ar := aggregateRes{
+			Offset: res.Offset,
+			Limit:  res.Limit,
+			Groups: []groupRes{},
+		}
+		for _, g := range res.Groups {
+			ar.Groups = append(ar.Groups, groupRes{Keys: g.Keys, Value: g.Value})
+		}
+
+		return ar, nil
 	}
 }
@@ -4,9 +4,13 @@
 package api_test
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/mainflux/mainflux"
@@ -18,6 +22,7 @@ import (
 
 const (
 	svcName       = "test-service"
+	contentType   = "application/json"
 	token         = "1"
 	invalid       = "invalid"
 	numOfMessages = 42
@@ -57,26 +62,44 @@ func newService() readers.MessageRepository {
 	})
 }
 
+func newSubtopics() readers.SubtopicRepository {
+	return mocks.NewSubtopicRepository(map[string][]readers.Subtopic{
+		chanID: {
+			{Name: "room.temperature", LastSeen: 20},
+			{Name: "room.humidity", LastSeen: 10},
+		},
+	})
+}
+
 func newServer(repo readers.MessageRepository, tc mainflux.ThingsServiceClient) *httptest.Server {
-	mux := api.MakeHandler(repo, tc, svcName)
+	mux := api.MakeHandler(repo, newSubtopics(), tc, svcName)
 	return httptest.NewServer(mux)
 }
 
 type testRequest struct {
-	client *http.Client
-	method string
-	url    string
-	token  string
+	client      *http.Client
+	method      string
+	url         string
+	contentType string
+	accept      string
+	token       string
+	body        io.Reader
 }
 
 func (tr testRequest) make() (*http.Response, error) {
-	req, err := http.NewRequest(tr.method, tr.url, nil)
+	req, err := http.NewRequest(tr.method, tr.url, tr.body)
 	if err != nil {
 		return nil, err
 	}
 	if tr.token != "" {
 		req.Header.Set("Authorization", tr.token)
 	}
+	if tr.contentType != "" {
+		req.Header.Set("Content-Type", tr.contentType)
+	}
+	if tr.accept != "" {
+		req.Header.Set("Accept", tr.accept)
+	}
 
 	return tr.client.Do(req)
 }
@@ -157,6 +180,61 @@ func TestReadAll(t *testing.T) {
 			token:  token,
 			status: http.StatusOK,
 		},
+		"read page with time range": {
+			url:    fmt.Sprintf("%s/channels/%s/messages?from=1565000000&to=1565086400.5", ts.URL, chanID),
+			token:  token,
+			status: http.StatusOK,
+		},
+		"read page with lower time bound only": {
+			url:    fmt.Sprintf("%s/channels/%s/messages?from=1565000000", ts.URL, chanID),
+			token:  token,
+			status: http.StatusOK,
+		},
+		"read page with non-numeric time bound": {
+			url:    fmt.Sprintf("%s/channels/%s/messages?from=abc", ts.URL, chanID),
+			token:  token,
+			status: http.StatusBadRequest,
+		},
+		"read page with lower time bound after upper one": {
+			url:    fmt.Sprintf("%s/channels/%s/messages?from=1565086400&to=1565000000", ts.URL, chanID),
+			token:  token,
+			status: http.StatusBadRequest,
+		},
+		"read page with multiple lower time bounds": {
+			url:    fmt.Sprintf("%s/channels/%s/messages?from=1565000000&from=1565086400", ts.URL, chanID),
+			token:  token,
+			status: http.StatusBadRequest,
+		},
+		"read page aggregated into time buckets": {
+			url:    fmt.Sprintf("%s/channels/%s/messages?aggregation=count&interval=1h", ts.URL, chanID),
+			token:  token,
+			status: http.StatusOK,
+		},
+		"read page with aggregation without interval": {
+			url:    fmt.Sprintf("%s/channels/%s/messages?aggregation=count", ts.URL, chanID),
+			token:  token,
+			status: http.StatusBadRequest,
+		},
+		"read page with interval without aggregation": {
+			url:    fmt.Sprintf("%s/channels/%s/messages?interval=1h", ts.URL, chanID),
+			token:  token,
+			status: http.StatusBadRequest,
+		},
+		"read page with invalid aggregation": {
+			url:    fmt.Sprintf("%s/channels/%s/messages?aggregation=median&interval=1h", ts.URL, chanID),
+			token:  token,
+			status: http.StatusBadRequest,
+		},
+		"read page with interval shorter than second": {
+			url:    fmt.Sprintf("%s/channels/%s/messages?aggregation=count&interval=10ms", ts.URL, chanID),
+			token:  token,
+			status: http.StatusBadRequest,
+		},
+		"read page with aggregation unsupported by database": {
+			url:    fmt.Sprintf("%s/channels/%s/messages?aggregation=avg&interval=1h", ts.URL, chanID),
+			token:  token,
+			status: http.StatusNotImplemented,
+		},
 	}
 
 	for desc, tc := range cases {
@@ -171,3 +249,307 @@ func TestReadAll(t *testing.T) {
 		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected %d got %d", desc, tc.status, res.StatusCode))
 	}
 }
+
+func TestReadAllBuckets(t *testing.T) {
+	svc := newService()
+	tc := mocks.NewThingsService()
+	ts := newServer(svc, tc)
+	defer ts.Close()
+
+	req := testRequest{
+		client: ts.Client(),
+		method: http.MethodGet,
+		url:    fmt.Sprintf("%s/channels/%s/messages?aggregation=count&interval=1h", ts.URL, chanID),
+		token:  token,
+	}
+	res, err := req.make()
+	assert.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+
+	var body struct {
+		Total   uint64 `json:"total"`
+		Buckets []struct {
+			Time  float64 `json:"time"`
+			Value float64 `json:"value"`
+		} `json:"buckets"`
+	}
+	err = json.NewDecoder(res.Body).Decode(&body)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	assert.Equal(t, uint64(1), body.Total, fmt.Sprintf("expected total 1 got %d", body.Total))
+	assert.Equal(t, 1, len(body.Buckets), fmt.Sprintf("expected 1 bucket got %d", len(body.Buckets)))
+	assert.Equal(t, float64(numOfMessages), body.Buckets[0].Value, fmt.Sprintf("expected bucket value %d got %f", numOfMessages, body.Buckets[0].Value))
+}
+
+func TestExport(t *testing.T) {
+	svc := newService()
+	tc := mocks.NewThingsService()
+	ts := newServer(svc, tc)
+	defer ts.Close()
+
+	url := fmt.Sprintf("%s/channels/%s/messages", ts.URL, chanID)
+	cases := map[string]struct {
+		url         string
+		accept      string
+		token       string
+		status      int
+		contentType string
+		lines       int
+	}{
+		"export messages as CSV": {
+			url:         url,
+			accept:      "text/csv",
+			token:       token,
+			status:      http.StatusOK,
+			contentType: "text/csv",
+			lines:       numOfMessages + 1,
+		},
+		"export messages as NDJSON": {
+			url:         url,
+			accept:      "application/x-ndjson",
+			token:       token,
+			status:      http.StatusOK,
+			contentType: "application/x-ndjson",
+			lines:       numOfMessages,
+		},
+		"export messages with multiple accepted types": {
+			url:         url,
+			accept:      "text/html, application/x-ndjson;q=0.9",
+			token:       token,
+			status:      http.StatusOK,
+			contentType: "application/x-ndjson",
+			lines:       numOfMessages,
+		},
+		"export messages of empty time range as CSV": {
+			url:         fmt.Sprintf("%s?from=10&to=20", url),
+			accept:      "text/csv",
+			token:       token,
+			status:      http.StatusOK,
+			contentType: "text/csv",
+			lines:       1,
+		},
+		"export messages ignoring offset and limit": {
+			url:         fmt.Sprintf("%s?offset=10&limit=5", url),
+			accept:      "application/x-ndjson",
+			token:       token,
+			status:      http.StatusOK,
+			contentType: "application/x-ndjson",
+			lines:       numOfMessages,
+		},
+		"export messages with aggregation": {
+			url:    fmt.Sprintf("%s?aggregation=count&interval=1h", url),
+			accept: "text/csv",
+			token:  token,
+			status: http.StatusBadRequest,
+		},
+		"export messages with invalid time range": {
+			url:    fmt.Sprintf("%s?from=invalid", url),
+			accept: "text/csv",
+			token:  token,
+			status: http.StatusBadRequest,
+		},
+		"export messages with invalid token": {
+			url:    url,
+			accept: "text/csv",
+			token:  invalid,
+			status: http.StatusForbidden,
+		},
+	}
+
+	for desc, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    tc.url,
+			accept: tc.accept,
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected %d got %d", desc, tc.status, res.StatusCode))
+		if tc.status != http.StatusOK {
+			continue
+		}
+
+		ct := res.Header.Get("Content-Type")
+		assert.Equal(t, tc.contentType, ct, fmt.Sprintf("%s: expected content type %s got %s", desc, tc.contentType, ct))
+
+		body, err := ioutil.ReadAll(res.Body)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", desc, err))
+		lines := strings.Split(strings.TrimSuffix(string(body), "\n"), "\n")
+		assert.Equal(t, tc.lines, len(lines), fmt.Sprintf("%s: expected %d lines got %d", desc, tc.lines, len(lines)))
+	}
+}
+
+func TestQuery(t *testing.T) {
+	svc := newService()
+	tc := mocks.NewThingsService()
+	ts := newServer(svc, tc)
+	defer ts.Close()
+
+	url := fmt.Sprintf("%s/channels/%s/query", ts.URL, chanID)
+	cases := map[string]struct {
+		req         string
+		contentType string
+		token       string
+		status      int
+	}{
+		"query messages with filters": {
+			req:         `{"filters":[{"field":"name","op":"eq","value":"temperature"},{"field":"value","op":"gt","value":20}],"limit":10}`,
+			contentType: contentType,
+			token:       token,
+			status:      http.StatusOK,
+		},
+		"query messages with default limit": {
+			req:         `{}`,
+			contentType: contentType,
+			token:       token,
+			status:      http.StatusOK,
+		},
+		"query messages with aggregation": {
+			req:         `{"aggregation":"count"}`,
+			contentType: contentType,
+			token:       token,
+			status:      http.StatusOK,
+		},
+		"query messages with aggregation unsupported by database": {
+			req:         `{"aggregation":"avg","group_by":["publisher"]}`,
+			contentType: contentType,
+			token:       token,
+			status:      http.StatusNotImplemented,
+		},
+		"query messages with unknown field": {
+			req:         `{"filters":[{"field":"unknown","op":"eq","value":"temperature"}]}`,
+			contentType: contentType,
+			token:       token,
+			status:      http.StatusBadRequest,
+		},
+		"query messages with invalid operator for field": {
+			req:         `{"filters":[{"field":"name","op":"gt","value":"temperature"}]}`,
+			contentType: contentType,
+			token:       token,
+			status:      http.StatusBadRequest,
+		},
+		"query messages with invalid value type": {
+			req:         `{"filters":[{"field":"value","op":"gt","value":"20"}]}`,
+			contentType: contentType,
+			token:       token,
+			status:      http.StatusBadRequest,
+		},
+		"query messages with group by without aggregation": {
+			req:         `{"group_by":["publisher"]}`,
+			contentType: contentType,
+			token:       token,
+			status:      http.StatusBadRequest,
+		},
+		"query messages with unknown aggregation": {
+			req:         `{"aggregation":"median"}`,
+			contentType: contentType,
+			token:       token,
+			status:      http.StatusBadRequest,
+		},
+		"query messages with limit greater than max": {
+			req:         `{"limit":1000}`,
+			contentType: contentType,
+			token:       token,
+			status:      http.StatusBadRequest,
+		},
+		"query messages with malformed JSON": {
+			req:         `{"filters":`,
+			contentType: contentType,
+			token:       token,
+			status:      http.StatusBadRequest,
+		},
+		"query messages without content type": {
+			req:         `{}`,
+			contentType: "",
+			token:       token,
+			status:      http.StatusUnsupportedMediaType,
+		},
+		"query messages with invalid token": {
+			req:         `{}`,
+			contentType: contentType,
+			token:       invalid,
+			status:      http.StatusForbidden,
+		},
+	}
+
+	for desc, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPost,
+			url:         url,
+			contentType: tc.contentType,
+			token:       tc.token,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected %d got %d", desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestListSubtopics(t *testing.T) {
+	svc := newService()
+	tc := mocks.NewThingsService()
+	ts := newServer(svc, tc)
+	defer ts.Close()
+
+	type subtopic struct {
+		Name     string  `json:"name"`
+		LastSeen float64 `json:"last_seen"`
+	}
+
+	cases := map[string]struct {
+		url       string
+		token     string
+		status    int
+		subtopics []subtopic
+	}{
+		"list subtopics of channel": {
+			url:    fmt.Sprintf("%s/channels/%s/subtopics", ts.URL, chanID),
+			token:  token,
+			status: http.StatusOK,
+			subtopics: []subtopic{
+				{Name: "room.humidity", LastSeen: 10},
+				{Name: "room.temperature", LastSeen: 20},
+			},
+		},
+		"list subtopics of channel without subtopics": {
+			url:       fmt.Sprintf("%s/channels/%s/subtopics", ts.URL, "2"),
+			token:     token,
+			status:    http.StatusOK,
+			subtopics: []subtopic{},
+		},
+		"list subtopics with invalid token": {
+			url:    fmt.Sprintf("%s/channels/%s/subtopics", ts.URL, chanID),
+			token:  invalid,
+			status: http.StatusForbidden,
+		},
+		"list subtopics with empty token": {
+			url:    fmt.Sprintf("%s/channels/%s/subtopics", ts.URL, chanID),
+			token:  "",
+			status: http.StatusForbidden,
+		},
+	}
+
+	for desc, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    tc.url,
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected %d got %d", desc, tc.status, res.StatusCode))
+		if tc.status != http.StatusOK {
+			continue
+		}
+
+		var body struct {
+			Subtopics []subtopic `json:"subtopics"`
+		}
+		err = json.NewDecoder(res.Body).Decode(&body)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", desc, err))
+		assert.Equal(t, tc.subtopics, body.Subtopics, fmt.Sprintf("%s: expected %v got %v", desc, tc.subtopics, body.Subtopics))
+	}
+}
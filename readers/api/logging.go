@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/logger"
 	"github.com/mainflux/mainflux/readers"
 )
@@ -35,3 +36,29 @@ func (lm *loggingMiddleware) ReadAll(chanID string, offset, limit uint64, query
 
 	return lm.svc.ReadAll(chanID, offset, limit, query)
 }
+
+func (lm *loggingMiddleware) ReadStream(chanID string, query map[string]string, fn func(mainflux.Message) error) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method read_stream for channel %s took %s to complete", chanID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ReadStream(chanID, query, fn)
+}
+
+func (lm *loggingMiddleware) Query(chanID string, query readers.Query) (res readers.QueryResult, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method query for channel %s took %s to complete", chanID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Query(chanID, query)
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/go-kit/kit/metrics"
+	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/readers"
 )
 
@@ -38,3 +39,21 @@ func (mm *metricsMiddleware) ReadAll(chanID string, offset, limit uint64, query
 
 	return mm.svc.ReadAll(chanID, offset, limit, query)
 }
+
+func (mm *metricsMiddleware) ReadStream(chanID string, query map[string]string, fn func(mainflux.Message) error) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "read_stream").Add(1)
+		mm.latency.With("method", "read_stream").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.ReadStream(chanID, query, fn)
+}
+
+func (mm *metricsMiddleware) Query(chanID string, query readers.Query) (readers.QueryResult, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "query").Add(1)
+		mm.latency.With("method", "query").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.Query(chanID, query)
+}
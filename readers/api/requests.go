@@ -3,6 +3,10 @@
 
 package api
 
+import "github.com/mainflux/mainflux/readers"
+
+const maxLimit = 100
+
 type apiReq interface {
 	validate() error
 }
@@ -12,6 +16,7 @@ type listMessagesReq struct {
 	offset uint64
 	limit  uint64
 	query  map[string]string
+	format string
 }
 
 func (req listMessagesReq) validate() error {
@@ -19,5 +24,80 @@ func (req listMessagesReq) validate() error {
 		return errInvalidRequest
 	}
 
+	// Exports contain the raw messages only.
+	if req.format != "" {
+		for _, name := range bucketFields {
+			if _, ok := req.query[name]; ok {
+				return errInvalidRequest
+			}
+		}
+	}
+
+	from, to, err := readers.TimeRange(req.query)
+	if err != nil {
+		return errInvalidRequest
+	}
+
+	if from != nil && to != nil && *from > *to {
+		return errInvalidRequest
+	}
+
+	if _, _, err := readers.TimeBuckets(req.query); err != nil {
+		return errInvalidRequest
+	}
+
+	return nil
+}
+
+type listSubtopicsReq struct {
+	chanID string
+}
+
+func (req listSubtopicsReq) validate() error {
+	if req.chanID == "" {
+		return errInvalidRequest
+	}
+
 	return nil
 }
+
+type queryFilter struct {
+	Field    string      `json:"field"`
+	Operator string      `json:"op"`
+	Value    interface{} `json:"value"`
+}
+
+type queryMessagesReq struct {
+	chanID      string
+	Filters     []queryFilter `json:"filters"`
+	Aggregation string        `json:"aggregation"`
+	GroupBy     []string      `json:"group_by"`
+	Offset      uint64        `json:"offset"`
+	Limit       uint64        `json:"limit"`
+}
+
+func (req queryMessagesReq) query() readers.Query {
+	q := readers.Query{
+		Aggregation: req.Aggregation,
+		GroupBy:     req.GroupBy,
+		Offset:      req.Offset,
+		Limit:       req.Limit,
+	}
+	for _, f := range req.Filters {
+		q.Filters = append(q.Filters, readers.Filter{
+			Field:    f.Field,
+			Operator: f.Operator,
+			Value:    f.Value,
+		})
+	}
+
+	return q
+}
+
+func (req queryMessagesReq) validate() error {
+	if req.Limit > maxLimit {
+		return errInvalidRequest
+	}
+
+	return req.query().Validate()
+}
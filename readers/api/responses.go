@@ -16,6 +16,12 @@ type pageRes struct {
 	Offset   uint64             `json:"offset"`
 	Limit    uint64             `json:"limit"`
 	Messages []mainflux.Message `json:"messages"`
+	Buckets  []bucketRes        `json:"buckets,omitempty"`
+}
+
+type bucketRes struct {
+	Time  float64 `json:"time"`
+	Value float64 `json:"value"`
 }
 
 func (res pageRes) Headers() map[string]string {
@@ -29,3 +35,78 @@ func (res pageRes) Code() int {
 func (res pageRes) Empty() bool {
 	return false
 }
+
+var _ mainflux.Response = (*subtopicsRes)(nil)
+
+type subtopicsRes struct {
+	Subtopics []subtopicRes `json:"subtopics"`
+}
+
+type subtopicRes struct {
+	Name     string  `json:"name"`
+	LastSeen float64 `json:"last_seen"`
+}
+
+func (res subtopicsRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res subtopicsRes) Code() int {
+	return http.StatusOK
+}
+
+func (res subtopicsRes) Empty() bool {
+	return false
+}
+
+// streamRes represents the messages export, which is written by the response
+// encoder in the requested format.
+type streamRes struct {
+	format string
+	read   func(func(mainflux.Message) error) error
+}
+
+var _ mainflux.Response = (*queryRes)(nil)
+
+type queryRes struct {
+	Offset   uint64             `json:"offset"`
+	Limit    uint64             `json:"limit"`
+	Messages []mainflux.Message `json:"messages"`
+}
+
+func (res queryRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res queryRes) Code() int {
+	return http.StatusOK
+}
+
+func (res queryRes) Empty() bool {
+	return false
+}
+
+var _ mainflux.Response = (*aggregateRes)(nil)
+
+type groupRes struct {
+	Keys  map[string]string `json:"keys"`
+	Value float64           `json:"value"`
+}
+
+type aggregateRes struct {
+	Offset uint64     `json:"offset"`
+	Limit  uint64     `json:"limit"`
+	Groups []groupRes `json:"groups"`
+}
+
+func (res aggregateRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res aggregateRes) Code() int {
+	return http.StatusOK
+}
+
+func (res aggregateRes) Empty() bool {
+	return false
+}
@@ -5,16 +5,20 @@ package api
 
 import (
 	"context"
+	"encoding/csv"
 	"encoding/json"
 	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	kithttp "github.com/go-kit/kit/transport/http"
 	"github.com/go-zoo/bone"
 	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/readers"
+	"github.com/mainflux/mainflux/things"
+	"github.com/mainflux/mainflux/things/keys"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -22,19 +26,29 @@ import (
 
 const (
 	contentType = "application/json"
+	csvType     = "text/csv"
+	ndjsonType  = "application/x-ndjson"
 	defLimit    = 10
 	defOffset   = 0
+
+	// flushPeriod is the number of exported messages after which the
+	// response is flushed to the client.
+	flushPeriod = 100
 )
 
 var (
-	errInvalidRequest     = errors.New("received invalid request")
-	errUnauthorizedAccess = errors.New("missing or invalid credentials provided")
-	auth                  mainflux.ThingsServiceClient
-	queryFields           = []string{"subtopic", "publisher", "protocol", "name", "value", "v", "vs", "vb", "vd"}
+	errInvalidRequest         = errors.New("received invalid request")
+	errUnauthorizedAccess     = errors.New("missing or invalid credentials provided")
+	errUnsupportedContentType = errors.New("unsupported content type")
+	auth                      mainflux.ThingsServiceClient
+	queryFields               = []string{"subtopic", "publisher", "protocol", "name", "value", "v", "vs", "vb", "vd"}
+	timeFields                = []string{"from", "to"}
+	bucketFields              = []string{"aggregation", "interval"}
 )
 
-// MakeHandler returns a HTTP handler for API endpoints.
-func MakeHandler(svc readers.MessageRepository, tc mainflux.ThingsServiceClient, svcName string) http.Handler {
+// MakeHandler returns a HTTP handler for API endpoints. The subtopics are
+// served only if the subtopic repository is provided.
+func MakeHandler(svc readers.MessageRepository, subtopics readers.SubtopicRepository, tc mainflux.ThingsServiceClient, svcName string) http.Handler {
 	auth = tc
 
 	opts := []kithttp.ServerOption{
@@ -49,7 +63,27 @@ func MakeHandler(svc readers.MessageRepository, tc mainflux.ThingsServiceClient,
 		opts...,
 	))
 
+	mux.Post("/channels/:chanID/query", kithttp.NewServer(
+		queryMessagesEndpoint(svc),
+		decodeQuery,
+		encodeResponse,
+		opts...,
+	))
+
+	if subtopics != nil {
+		mux.Get("/channels/:chanID/subtopics", kithttp.NewServer(
+			listSubtopicsEndpoint(subtopics),
+			decodeListSubtopics,
+			encodeResponse,
+			opts...,
+		))
+	}
+
 	mux.GetFunc("/version", mainflux.Version(svcName))
+	mux.GetFunc(mainflux.DiscoveryPath, mainflux.Discovery(svcName, mainflux.Capabilities{
+		ContentTypes: []string{contentType, ndjsonType},
+		Limits:       mainflux.Limits{MaxPageSize: maxLimit},
+	}))
 	mux.Handle("/metrics", promhttp.Handler())
 
 	return mux
@@ -82,17 +116,85 @@ func decodeList(_ context.Context, r *http.Request) (interface{}, error) {
 		}
 	}
 
+	for _, name := range append(timeFields, bucketFields...) {
+		vals := bone.GetQuery(r, name)
+		if len(vals) == 0 {
+			continue
+		}
+		if len(vals) > 1 {
+			return nil, errInvalidRequest
+		}
+		query[name] = vals[0]
+	}
+
 	req := listMessagesReq{
 		chanID: chanID,
 		offset: offset,
 		limit:  limit,
 		query:  query,
+		format: exportFormat(r.Header.Get("Accept")),
 	}
 
 	return req, nil
 }
 
+func decodeQuery(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+
+	chanID := bone.GetValue(r, "chanID")
+	if chanID == "" {
+		return nil, errInvalidRequest
+	}
+
+	if err := authorize(r, chanID); err != nil {
+		return nil, err
+	}
+
+	req := queryMessagesReq{
+		chanID: chanID,
+		Limit:  defLimit,
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errInvalidRequest
+	}
+
+	return req, nil
+}
+
+func decodeListSubtopics(_ context.Context, r *http.Request) (interface{}, error) {
+	chanID := bone.GetValue(r, "chanID")
+	if chanID == "" {
+		return nil, errInvalidRequest
+	}
+
+	if err := authorize(r, chanID); err != nil {
+		return nil, err
+	}
+
+	return listSubtopicsReq{chanID: chanID}, nil
+}
+
+// exportFormat returns the export content type accepted by the client, or an
+// empty string if the messages page is requested.
+func exportFormat(accept string) string {
+	for _, t := range strings.Split(accept, ",") {
+		t = strings.TrimSpace(strings.SplitN(t, ";", 2)[0])
+		switch t {
+		case csvType, ndjsonType:
+			return t
+		}
+	}
+
+	return ""
+}
+
 func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	if sr, ok := response.(streamRes); ok {
+		return encodeStream(w, sr)
+	}
+
 	w.Header().Set("Content-Type", contentType)
 
 	if ar, ok := response.(mainflux.Response); ok {
@@ -110,13 +212,128 @@ func encodeResponse(_ context.Context, w http.ResponseWriter, response interface
 	return json.NewEncoder(w).Encode(response)
 }
 
+// encodeStream writes the exported messages as they are read. The status is
+// sent with the first message, so the errors that occur before it are
+// reported as usual, while the later ones abort the response, since the
+// client can't otherwise tell that the export is incomplete.
+func encodeStream(w http.ResponseWriter, sr streamRes) error {
+	header := func() error { return nil }
+	var write func(mainflux.Message) error
+	flush := func() error { return nil }
+	switch sr.format {
+	case csvType:
+		cw := csv.NewWriter(w)
+		header = func() error {
+			return cw.Write(csvHeader)
+		}
+		write = func(msg mainflux.Message) error {
+			return cw.Write(csvRecord(msg))
+		}
+		flush = func() error {
+			cw.Flush()
+			return cw.Error()
+		}
+	default:
+		enc := json.NewEncoder(w)
+		write = func(msg mainflux.Message) error {
+			return enc.Encode(msg)
+		}
+	}
+
+	started := false
+	start := func() error {
+		started = true
+		w.Header().Set("Content-Type", sr.format)
+		w.WriteHeader(http.StatusOK)
+		return header()
+	}
+
+	count := 0
+	err := sr.read(func(msg mainflux.Message) error {
+		if !started {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+
+		if err := write(msg); err != nil {
+			return err
+		}
+
+		count++
+		if count%flushPeriod == 0 {
+			if err := flush(); err != nil {
+				return err
+			}
+			if f, ok := w.(http.Flusher); ok {
+				f.Flush()
+			}
+		}
+
+		return nil
+	})
+
+	switch {
+	case err != nil && !started:
+		return err
+	case err != nil:
+		panic(http.ErrAbortHandler)
+	case !started:
+		if err := start(); err != nil {
+			panic(http.ErrAbortHandler)
+		}
+	}
+
+	if err := flush(); err != nil {
+		panic(http.ErrAbortHandler)
+	}
+
+	return nil
+}
+
+var csvHeader = []string{"channel", "subtopic", "publisher", "protocol", "name", "unit", "value",
+	"string_value", "bool_value", "data_value", "value_sum", "time", "update_time", "link", "sequence"}
+
+// csvRecord returns the CSV record of the message, with the columns listed
+// in the header.
+func csvRecord(msg mainflux.Message) []string {
+	rec := []string{msg.Channel, msg.Subtopic, msg.Publisher, msg.Protocol, msg.Name, msg.Unit, "", "", "", "", "",
+		formatFloat(msg.Time), formatFloat(msg.UpdateTime), msg.Link, ""}
+	switch v := msg.Value.(type) {
+	case *mainflux.Message_FloatValue:
+		rec[6] = formatFloat(v.FloatValue)
+	case *mainflux.Message_StringValue:
+		rec[7] = v.StringValue
+	case *mainflux.Message_BoolValue:
+		rec[8] = strconv.FormatBool(v.BoolValue)
+	case *mainflux.Message_DataValue:
+		rec[9] = v.DataValue
+	}
+	if msg.ValueSum != nil {
+		rec[10] = formatFloat(msg.ValueSum.Value)
+	}
+	if msg.Sequence > 0 {
+		rec[14] = strconv.FormatUint(msg.Sequence, 10)
+	}
+
+	return rec
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
 func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 	switch err {
 	case nil:
-	case errInvalidRequest:
+	case errInvalidRequest, readers.ErrInvalidQuery:
 		w.WriteHeader(http.StatusBadRequest)
 	case errUnauthorizedAccess:
 		w.WriteHeader(http.StatusForbidden)
+	case errUnsupportedContentType:
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+	case readers.ErrUnsupportedQuery:
+		w.WriteHeader(http.StatusNotImplemented)
 	default:
 		w.WriteHeader(http.StatusInternalServerError)
 	}
@@ -124,14 +341,14 @@ func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 
 func authorize(r *http.Request, chanID string) error {
 	token := r.Header.Get("Authorization")
-	if token == "" {
+	if keys.Malformed(token) {
 		return errUnauthorizedAccess
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 	defer cancel()
 
-	_, err := auth.CanAccess(ctx, &mainflux.AccessReq{Token: token, ChanID: chanID})
+	_, err := auth.CanAccess(ctx, &mainflux.AccessReq{Token: token, ChanID: chanID, Action: things.ReadHistory})
 	if err != nil {
 		e, ok := status.FromError(err)
 		if ok && e.Code() == codes.PermissionDenied {
@@ -25,12 +25,22 @@ func New(session *gocql.Session) readers.MessageRepository {
 }
 
 func (cr cassandraRepository) ReadAll(chanID string, offset, limit uint64, query map[string]string) (readers.MessagesPage, error) {
-	names := []string{}
-	vals := []interface{}{chanID}
-	for name, val := range query {
-		names = append(names, name)
-		vals = append(vals, val)
+	from, to, err := readers.TimeRange(query)
+	if err != nil {
+		return readers.MessagesPage{}, err
 	}
+
+	// Cassandra groups only by primary key columns, so the messages can't be
+	// aggregated into time buckets.
+	agg, _, err := readers.TimeBuckets(query)
+	if err != nil {
+		return readers.MessagesPage{}, err
+	}
+	if agg != "" {
+		return readers.MessagesPage{}, readers.ErrUnsupportedQuery
+	}
+
+	names, vals := queryValues(chanID, query, from, to)
 	vals = append(vals, offset+limit)
 
 	selectCQL := buildSelectQuery(chanID, offset, limit, names)
@@ -47,39 +57,17 @@ func (cr cassandraRepository) ReadAll(chanID string, offset, limit uint64, query
 		}
 	}
 
-	var floatVal, valueSum *float64
-	var strVal, dataVal *string
-	var boolVal *bool
-
 	page := readers.MessagesPage{
 		Offset:   offset,
 		Limit:    limit,
 		Messages: []mainflux.Message{},
 	}
 	for scanner.Next() {
-		var msg mainflux.Message
-		err := scanner.Scan(&msg.Channel, &msg.Subtopic, &msg.Publisher, &msg.Protocol,
-			&msg.Name, &msg.Unit, &floatVal, &strVal, &boolVal,
-			&dataVal, &valueSum, &msg.Time, &msg.UpdateTime, &msg.Link)
+		msg, err := scanMessage(scanner)
 		if err != nil {
 			return readers.MessagesPage{}, err
 		}
 
-		switch {
-		case floatVal != nil:
-			msg.Value = &mainflux.Message_FloatValue{FloatValue: *floatVal}
-		case strVal != nil:
-			msg.Value = &mainflux.Message_StringValue{StringValue: *strVal}
-		case boolVal != nil:
-			msg.Value = &mainflux.Message_BoolValue{BoolValue: *boolVal}
-		case dataVal != nil:
-			msg.Value = &mainflux.Message_DataValue{DataValue: *dataVal}
-		}
-
-		if valueSum != nil {
-			msg.ValueSum = &mainflux.SumValue{Value: *valueSum}
-		}
-
 		page.Messages = append(page.Messages, msg)
 	}
 
@@ -90,14 +78,117 @@ func (cr cassandraRepository) ReadAll(chanID string, offset, limit uint64, query
 	return page, nil
 }
 
-func buildSelectQuery(chanID string, offset, limit uint64, names []string) string {
-	var condCQL string
-	cql := `SELECT channel, subtopic, publisher, protocol, name, unit,
+func (cr cassandraRepository) ReadStream(chanID string, query map[string]string, fn func(mainflux.Message) error) error {
+	from, to, err := readers.TimeRange(query)
+	if err != nil {
+		return err
+	}
+
+	names, vals := queryValues(chanID, query, from, to)
+	cql := fmt.Sprintf(`SELECT channel, subtopic, publisher, protocol, name, unit,
 	        value, string_value, bool_value, data_value, value_sum, time,
-			update_time, link FROM messages WHERE channel = ? %s LIMIT ?
-			ALLOW FILTERING`
+			update_time, link FROM messages WHERE channel = ? %s
+			ALLOW FILTERING`, buildCondition(names))
 
-	for _, name := range names {
+	// The driver fetches the rows page by page as the scanner advances.
+	iter := cr.session.Query(cql, vals...).Iter()
+	scanner := iter.Scanner()
+	for scanner.Next() {
+		msg, err := scanMessage(scanner)
+		if err != nil {
+			iter.Close()
+			return err
+		}
+
+		if err := fn(msg); err != nil {
+			iter.Close()
+			return err
+		}
+	}
+
+	return iter.Close()
+}
+
+func (cr cassandraRepository) Query(chanID string, query readers.Query) (readers.QueryResult, error) {
+	condCQL, vals, err := buildQueryCondition(chanID, query.Filters)
+	if err != nil {
+		return readers.QueryResult{}, err
+	}
+
+	res := readers.QueryResult{
+		Offset: query.Offset,
+		Limit:  query.Limit,
+	}
+
+	if query.Aggregation == "" {
+		cql := fmt.Sprintf(`SELECT channel, subtopic, publisher, protocol, name, unit,
+	        value, string_value, bool_value, data_value, value_sum, time,
+			update_time, link FROM messages WHERE %s LIMIT ?
+			ALLOW FILTERING`, condCQL)
+
+		iter := cr.session.Query(cql, append(vals, query.Offset+query.Limit)...).Iter()
+		defer iter.Close()
+		scanner := iter.Scanner()
+
+		// skip first OFFSET rows
+		for i := uint64(0); i < query.Offset; i++ {
+			if !scanner.Next() {
+				break
+			}
+		}
+
+		res.Messages = []mainflux.Message{}
+		for scanner.Next() {
+			msg, err := scanMessage(scanner)
+			if err != nil {
+				return readers.QueryResult{}, err
+			}
+			res.Messages = append(res.Messages, msg)
+		}
+
+		return res, nil
+	}
+
+	// Cassandra groups only by primary key columns, which are not exposed
+	// as grouping fields.
+	if len(query.GroupBy) > 0 {
+		return readers.QueryResult{}, readers.ErrUnsupportedQuery
+	}
+
+	res.Groups = []readers.Group{}
+	if query.Offset > 0 {
+		return res, nil
+	}
+
+	cql := fmt.Sprintf(`SELECT count(value), %s(value) FROM messages WHERE %s ALLOW FILTERING`,
+		cqlAggregations[query.Aggregation], condCQL)
+
+	var count int64
+	var val float64
+	if query.Aggregation == readers.AggCount {
+		cql = fmt.Sprintf(`SELECT count(value) FROM messages WHERE %s ALLOW FILTERING`, condCQL)
+		if err := cr.session.Query(cql, vals...).Scan(&count); err != nil {
+			return readers.QueryResult{}, err
+		}
+		val = float64(count)
+	} else if err := cr.session.Query(cql, vals...).Scan(&count, &val); err != nil {
+		return readers.QueryResult{}, err
+	}
+
+	// Aggregation over no values is undefined.
+	if count > 0 {
+		res.Groups = append(res.Groups, readers.Group{Keys: map[string]string{}, Value: val})
+	}
+
+	return res, nil
+}
+
+// queryValues returns the names of the query parameters used as filters and
+// the matching CQL values, starting with the channel ID.
+func queryValues(chanID string, query map[string]string, from, to *float64) ([]string, []interface{}) {
+	names := []string{}
+	vals := []interface{}{chanID}
+	for name, val := range query {
 		switch name {
 		case
 			"channel",
@@ -105,17 +196,22 @@ func buildSelectQuery(chanID string, offset, limit uint64, names []string) strin
 			"publisher",
 			"name",
 			"protocol":
-			condCQL = fmt.Sprintf(`%s AND %s = ?`, condCQL, name)
+			vals = append(vals, val)
+		case "from":
+			vals = append(vals, *from)
+		case "to":
+			vals = append(vals, *to)
+		default:
+			continue
 		}
+		names = append(names, name)
 	}
 
-	return fmt.Sprintf(cql, condCQL)
+	return names, vals
 }
 
-func buildCountQuery(chanID string, names []string) string {
+func buildCondition(names []string) string {
 	var condCQL string
-	cql := `SELECT COUNT(*) FROM messages WHERE channel = ? %s ALLOW FILTERING`
-
 	for _, name := range names {
 		switch name {
 		case
@@ -125,8 +221,90 @@ func buildCountQuery(chanID string, names []string) string {
 			"name",
 			"protocol":
 			condCQL = fmt.Sprintf(`%s AND %s = ?`, condCQL, name)
+		case "from":
+			condCQL = fmt.Sprintf(`%s AND time >= ?`, condCQL)
+		case "to":
+			condCQL = fmt.Sprintf(`%s AND time < ?`, condCQL)
+		}
+	}
+
+	return condCQL
+}
+
+func buildSelectQuery(chanID string, offset, limit uint64, names []string) string {
+	cql := `SELECT channel, subtopic, publisher, protocol, name, unit,
+	        value, string_value, bool_value, data_value, value_sum, time,
+			update_time, link FROM messages WHERE channel = ? %s LIMIT ?
+			ALLOW FILTERING`
+
+	return fmt.Sprintf(cql, buildCondition(names))
+}
+
+func buildCountQuery(chanID string, names []string) string {
+	cql := `SELECT COUNT(*) FROM messages WHERE channel = ? %s ALLOW FILTERING`
+
+	return fmt.Sprintf(cql, buildCondition(names))
+}
+
+var (
+	cqlOperators = map[string]string{
+		readers.OpEq:  "=",
+		readers.OpLt:  "<",
+		readers.OpLte: "<=",
+		readers.OpGt:  ">",
+		readers.OpGte: ">=",
+	}
+	cqlAggregations = map[string]string{
+		readers.AggSum: "sum",
+		readers.AggAvg: "avg",
+		readers.AggMin: "min",
+		readers.AggMax: "max",
+	}
+)
+
+func buildQueryCondition(chanID string, filters []readers.Filter) (string, []interface{}, error) {
+	condCQL := `channel = ?`
+	vals := []interface{}{chanID}
+	for _, f := range filters {
+		// CQL does not support inequality restrictions.
+		op, ok := cqlOperators[f.Operator]
+		if !ok {
+			return "", nil, readers.ErrUnsupportedQuery
 		}
+		condCQL = fmt.Sprintf(`%s AND %s %s ?`, condCQL, f.Field, op)
+		vals = append(vals, f.Value)
+	}
+
+	return condCQL, vals, nil
+}
+
+func scanMessage(scanner gocql.Scanner) (mainflux.Message, error) {
+	var floatVal, valueSum *float64
+	var strVal, dataVal *string
+	var boolVal *bool
+
+	var msg mainflux.Message
+	err := scanner.Scan(&msg.Channel, &msg.Subtopic, &msg.Publisher, &msg.Protocol,
+		&msg.Name, &msg.Unit, &floatVal, &strVal, &boolVal,
+		&dataVal, &valueSum, &msg.Time, &msg.UpdateTime, &msg.Link)
+	if err != nil {
+		return mainflux.Message{}, err
+	}
+
+	switch {
+	case floatVal != nil:
+		msg.Value = &mainflux.Message_FloatValue{FloatValue: *floatVal}
+	case strVal != nil:
+		msg.Value = &mainflux.Message_StringValue{StringValue: *strVal}
+	case boolVal != nil:
+		msg.Value = &mainflux.Message_BoolValue{BoolValue: *boolVal}
+	case dataVal != nil:
+		msg.Value = &mainflux.Message_DataValue{DataValue: *dataVal}
+	}
+
+	if valueSum != nil {
+		msg.ValueSum = &mainflux.SumValue{Value: *valueSum}
 	}
 
-	return fmt.Sprintf(cql, condCQL)
+	return msg, nil
 }
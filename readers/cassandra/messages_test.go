@@ -143,6 +143,18 @@ func TestReadAll(t *testing.T) {
 				Messages: subtopicMsgs[5:],
 			},
 		},
+		"read message with time range": {
+			chanID: chanID,
+			offset: 0,
+			limit:  msgsNum,
+			query:  map[string]string{"from": fmt.Sprintf("%d", now-9), "to": fmt.Sprintf("%d", now-4)},
+			page: readers.MessagesPage{
+				Total:    5,
+				Offset:   0,
+				Limit:    msgsNum,
+				Messages: messages[5:10],
+			},
+		},
 	}
 
 	for desc, tc := range cases {
@@ -11,12 +11,17 @@ import (
 	"github.com/mainflux/mainflux/readers"
 
 	influxdata "github.com/influxdata/influxdb/client/v2"
+	"github.com/influxdata/influxdb/models"
 	"github.com/mainflux/mainflux"
 )
 
 const (
 	maxLimit = 100
 	countCol = "count"
+
+	// streamPage is the number of messages fetched per query while streaming,
+	// since the client buffers the complete response of each query.
+	streamPage = 1000
 )
 
 var _ readers.MessageRepository = (*influxRepository)(nil)
@@ -39,7 +44,22 @@ func (repo *influxRepository) ReadAll(chanID string, offset, limit uint64, query
 		limit = maxLimit
 	}
 
-	condition := fmtCondition(chanID, query)
+	from, to, err := readers.TimeRange(query)
+	if err != nil {
+		return readers.MessagesPage{}, err
+	}
+
+	agg, interval, err := readers.TimeBuckets(query)
+	if err != nil {
+		return readers.MessagesPage{}, err
+	}
+
+	condition := fmtCondition(chanID, query, from, to)
+
+	if agg != "" {
+		return repo.readBuckets(condition, agg, interval, offset, limit)
+	}
+
 	cmd := fmt.Sprintf(`SELECT * FROM messages WHERE %s ORDER BY time DESC LIMIT %d OFFSET %d`, condition, limit, offset)
 	q := influxdata.Query{
 		Command:  cmd,
@@ -78,6 +98,206 @@ func (repo *influxRepository) ReadAll(chanID string, offset, limit uint64, query
 	}, nil
 }
 
+func (repo *influxRepository) ReadStream(chanID string, query map[string]string, fn func(mainflux.Message) error) error {
+	from, to, err := readers.TimeRange(query)
+	if err != nil {
+		return err
+	}
+
+	condition := fmtCondition(chanID, query, from, to)
+	for offset := 0; ; offset += streamPage {
+		cmd := fmt.Sprintf(`SELECT * FROM messages WHERE %s ORDER BY time DESC LIMIT %d OFFSET %d`, condition, streamPage, offset)
+		resp, err := repo.client.Query(influxdata.Query{
+			Command:  cmd,
+			Database: repo.database,
+		})
+		if err != nil {
+			return err
+		}
+		if resp.Error() != nil {
+			return resp.Error()
+		}
+
+		if len(resp.Results) < 1 || len(resp.Results[0].Series) < 1 {
+			return nil
+		}
+
+		result := resp.Results[0].Series[0]
+		for _, v := range result.Values {
+			if err := fn(parseMessage(result.Columns, v)); err != nil {
+				return err
+			}
+		}
+
+		if len(result.Values) < streamPage {
+			return nil
+		}
+	}
+}
+
+func (repo *influxRepository) readBuckets(condition, agg string, interval time.Duration, offset, limit uint64) (readers.MessagesPage, error) {
+	selection := fmt.Sprintf(`SELECT %s(value) AS value FROM messages WHERE %s GROUP BY time(%dms) fill(none)`,
+		influxAggregations[agg], condition, int64(interval/time.Millisecond))
+	cmd := fmt.Sprintf(`%s ORDER BY time DESC LIMIT %d OFFSET %d`, selection, limit, offset)
+
+	resp, err := repo.client.Query(influxdata.Query{
+		Command:  cmd,
+		Database: repo.database,
+	})
+	if err != nil {
+		return readers.MessagesPage{}, err
+	}
+	if resp.Error() != nil {
+		return readers.MessagesPage{}, resp.Error()
+	}
+
+	page := readers.MessagesPage{
+		Offset:   offset,
+		Limit:    limit,
+		Messages: []mainflux.Message{},
+		Buckets:  []readers.Bucket{},
+	}
+	if len(resp.Results) < 1 || len(resp.Results[0].Series) < 1 {
+		return page, nil
+	}
+
+	for _, row := range resp.Results[0].Series[0].Values {
+		if len(row) < 2 {
+			continue
+		}
+
+		t, err := time.Parse(time.RFC3339, fmt.Sprintf("%v", row[0]))
+		if err != nil {
+			return readers.MessagesPage{}, err
+		}
+
+		num, ok := row[1].(json.Number)
+		if !ok {
+			continue
+		}
+		val, err := num.Float64()
+		if err != nil {
+			return readers.MessagesPage{}, err
+		}
+
+		page.Buckets = append(page.Buckets, readers.Bucket{Time: float64(t.Unix()), Value: val})
+	}
+
+	total, err := repo.countBuckets(selection)
+	if err != nil {
+		return readers.MessagesPage{}, err
+	}
+	page.Total = total
+
+	return page, nil
+}
+
+// countBuckets counts the non-empty buckets using the subquery, since the
+// grouped query itself returns the buckets only.
+func (repo *influxRepository) countBuckets(selection string) (uint64, error) {
+	resp, err := repo.client.Query(influxdata.Query{
+		Command:  fmt.Sprintf(`SELECT COUNT(value) FROM (%s)`, selection),
+		Database: repo.database,
+	})
+	if err != nil {
+		return 0, err
+	}
+	if resp.Error() != nil {
+		return 0, resp.Error()
+	}
+
+	if len(resp.Results) < 1 ||
+		len(resp.Results[0].Series) < 1 ||
+		len(resp.Results[0].Series[0].Values) < 1 ||
+		len(resp.Results[0].Series[0].Values[0]) < 2 {
+		return 0, nil
+	}
+
+	count, ok := resp.Results[0].Series[0].Values[0][1].(json.Number)
+	if !ok {
+		return 0, nil
+	}
+
+	return strconv.ParseUint(count.String(), 10, 64)
+}
+
+func (repo *influxRepository) Query(chanID string, query readers.Query) (readers.QueryResult, error) {
+	limit := query.Limit
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+
+	condition := fmtQueryCondition(chanID, query.Filters)
+	cmd := fmt.Sprintf(`SELECT * FROM messages WHERE %s ORDER BY time DESC LIMIT %d OFFSET %d`, condition, limit, query.Offset)
+	if query.Aggregation != "" {
+		groups := []string{}
+		for _, field := range query.GroupBy {
+			groups = append(groups, fmt.Sprintf(`"%s"`, field))
+		}
+
+		cmd = fmt.Sprintf(`SELECT %s(value) FROM messages WHERE %s`, influxAggregations[query.Aggregation], condition)
+		if len(groups) > 0 {
+			cmd = fmt.Sprintf(`%s GROUP BY %s SLIMIT %d SOFFSET %d`, cmd, strings.Join(groups, ","), limit, query.Offset)
+		}
+	}
+
+	resp, err := repo.client.Query(influxdata.Query{
+		Command:  cmd,
+		Database: repo.database,
+	})
+	if err != nil {
+		return readers.QueryResult{}, err
+	}
+	if resp.Error() != nil {
+		return readers.QueryResult{}, resp.Error()
+	}
+
+	res := readers.QueryResult{
+		Offset: query.Offset,
+		Limit:  limit,
+	}
+
+	var series []models.Row
+	if len(resp.Results) > 0 {
+		series = resp.Results[0].Series
+	}
+
+	if query.Aggregation == "" {
+		res.Messages = []mainflux.Message{}
+		if len(series) > 0 {
+			for _, v := range series[0].Values {
+				res.Messages = append(res.Messages, parseMessage(series[0].Columns, v))
+			}
+		}
+		return res, nil
+	}
+
+	res.Groups = []readers.Group{}
+	for _, row := range series {
+		// Aggregated series contain single row with time and value columns.
+		if len(row.Values) < 1 || len(row.Values[0]) < 2 {
+			continue
+		}
+
+		num, ok := row.Values[0][1].(json.Number)
+		if !ok {
+			continue
+		}
+		val, err := num.Float64()
+		if err != nil {
+			return readers.QueryResult{}, err
+		}
+
+		keys := map[string]string{}
+		for _, field := range query.GroupBy {
+			keys[field] = row.Tags[field]
+		}
+		res.Groups = append(res.Groups, readers.Group{Keys: keys, Value: val})
+	}
+
+	return res, nil
+}
+
 func (repo *influxRepository) count(condition string) (uint64, error) {
 	cmd := fmt.Sprintf(`SELECT COUNT(protocol) FROM messages WHERE %s`, condition)
 	q := influxdata.Query{
@@ -120,7 +340,7 @@ func (repo *influxRepository) count(condition string) (uint64, error) {
 	return strconv.ParseUint(count.String(), 10, 64)
 }
 
-func fmtCondition(chanID string, query map[string]string) string {
+func fmtCondition(chanID string, query map[string]string, from, to *float64) string {
 	condition := fmt.Sprintf(`channel='%s'`, chanID)
 	for name, value := range query {
 		switch name {
@@ -137,6 +357,15 @@ func fmtCondition(chanID string, query map[string]string) string {
 				strings.Replace(value, "\"", "\\\"", -1))
 		}
 	}
+
+	// Message time is stored as point timestamp in nanoseconds.
+	if from != nil {
+		condition = fmt.Sprintf(`%s AND time >= %d`, condition, int64(*from*1e9))
+	}
+	if to != nil {
+		condition = fmt.Sprintf(`%s AND time < %d`, condition, int64(*to*1e9))
+	}
+
 	return condition
 }
 
@@ -210,8 +439,62 @@ func parseMessage(names []string, fields []interface{}) mainflux.Message {
 
 			val, _ := strconv.ParseFloat(fields[i].(string), 64)
 			msgField.SetFloat(val)
+		case uint64:
+			if n, ok := fields[i].(json.Number); ok {
+				val, _ := strconv.ParseUint(n.String(), 10, 64)
+				msgField.SetUint(val)
+			}
 		}
 	}
 
 	return m
 }
+
+var (
+	influxOperators = map[string]string{
+		readers.OpEq:  "=",
+		readers.OpNeq: "!=",
+		readers.OpLt:  "<",
+		readers.OpLte: "<=",
+		readers.OpGt:  ">",
+		readers.OpGte: ">=",
+	}
+	influxAggregations = map[string]string{
+		readers.AggCount: "COUNT",
+		readers.AggSum:   "SUM",
+		readers.AggAvg:   "MEAN",
+		readers.AggMin:   "MIN",
+		readers.AggMax:   "MAX",
+	}
+	// influxFields maps query fields to the tag and field keys used by
+	// InfluxDB writer.
+	influxFields = map[string]string{
+		"string_value": "stringValue",
+		"bool_value":   "boolValue",
+	}
+)
+
+func fmtQueryCondition(chanID string, filters []readers.Filter) string {
+	condition := fmt.Sprintf(`channel='%s'`, strings.Replace(chanID, "'", "\\'", -1))
+	for _, f := range filters {
+		op := influxOperators[f.Operator]
+		switch v := f.Value.(type) {
+		case string:
+			key := f.Field
+			if k, ok := influxFields[key]; ok {
+				key = k
+			}
+			condition = fmt.Sprintf(`%s AND "%s" %s '%s'`, condition, key, op, strings.Replace(v, "'", "\\'", -1))
+		case bool:
+			condition = fmt.Sprintf(`%s AND "%s" %s %t`, condition, influxFields[f.Field], op, v)
+		case float64:
+			if f.Field == "time" {
+				// Message time is stored as point timestamp in nanoseconds.
+				condition = fmt.Sprintf(`%s AND time %s %d`, condition, op, int64(v*1e9))
+				continue
+			}
+			condition = fmt.Sprintf(`%s AND "%s" %s %s`, condition, f.Field, op, strconv.FormatFloat(v, 'f', -1, 64))
+		}
+	}
+	return condition
+}
@@ -18,11 +18,12 @@ import (
 )
 
 const (
-	testDB      = "test"
-	chanID      = "1"
-	subtopic    = "topic"
-	msgsNum     = 101
-	valueFields = 6
+	testDB        = "test"
+	chanID        = "1"
+	bucketsChanID = "buckets"
+	subtopic      = "topic"
+	msgsNum       = 101
+	valueFields   = 6
 )
 
 var (
@@ -163,6 +164,18 @@ func TestReadAll(t *testing.T) {
 				Messages: subtopicMsgs[0:10],
 			},
 		},
+		"read message with time range": {
+			chanID: chanID,
+			offset: 0,
+			limit:  msgsNum,
+			query:  map[string]string{"from": fmt.Sprintf("%d", now-9), "to": fmt.Sprintf("%d", now-4)},
+			page: readers.MessagesPage{
+				Total:    5,
+				Offset:   0,
+				Limit:    msgsNum,
+				Messages: messages[5:10],
+			},
+		},
 	}
 
 	for desc, tc := range cases {
@@ -172,3 +185,74 @@ func TestReadAll(t *testing.T) {
 		assert.Equal(t, tc.page.Total, result.Total, fmt.Sprintf("%s: expected %d got %d", desc, tc.page.Total, result.Total))
 	}
 }
+
+func TestReadAllBuckets(t *testing.T) {
+	writer, err := writer.New(client, testDB, 1, time.Second)
+	require.Nil(t, err, fmt.Sprintf("Creating new InfluxDB writer expected to succeed: %s.\n", err))
+	reader := reader.New(client, testDB)
+
+	// Messages are spread over three hours, with three, two and one message
+	// in the consecutive hourly buckets.
+	hour := int64(time.Hour / time.Second)
+	start := time.Now().Unix()/hour*hour - 3*hour
+	for i, count := range []int{3, 2, 1} {
+		for j := 0; j < count; j++ {
+			msg := mainflux.Message{
+				Channel:   bucketsChanID,
+				Publisher: "1",
+				Protocol:  "mqtt",
+				Value:     &mainflux.Message_FloatValue{FloatValue: float64(j + 1)},
+				Time:      float64(start + int64(i)*hour + int64(j)),
+			}
+			err := writer.Save(msg)
+			require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+		}
+	}
+
+	cases := map[string]struct {
+		offset  uint64
+		limit   uint64
+		query   map[string]string
+		total   uint64
+		buckets []readers.Bucket
+	}{
+		"read message count per hour": {
+			offset: 0,
+			limit:  10,
+			query:  map[string]string{"aggregation": readers.AggCount, "interval": "1h"},
+			total:  3,
+			buckets: []readers.Bucket{
+				{Time: float64(start + 2*hour), Value: 1},
+				{Time: float64(start + hour), Value: 2},
+				{Time: float64(start), Value: 3},
+			},
+		},
+		"read average value per hour": {
+			offset: 0,
+			limit:  10,
+			query:  map[string]string{"aggregation": readers.AggAvg, "interval": "1h"},
+			total:  3,
+			buckets: []readers.Bucket{
+				{Time: float64(start + 2*hour), Value: 1},
+				{Time: float64(start + hour), Value: 1.5},
+				{Time: float64(start), Value: 2},
+			},
+		},
+		"read last page of hourly buckets": {
+			offset: 2,
+			limit:  1,
+			query:  map[string]string{"aggregation": readers.AggMax, "interval": "1h"},
+			total:  3,
+			buckets: []readers.Bucket{
+				{Time: float64(start), Value: 3},
+			},
+		},
+	}
+
+	for desc, tc := range cases {
+		result, err := reader.ReadAll(bucketsChanID, tc.offset, tc.limit, tc.query)
+		assert.Nil(t, err, fmt.Sprintf("%s: expected no error got %s", desc, err))
+		assert.Equal(t, tc.buckets, result.Buckets, fmt.Sprintf("%s: expected %v got %v", desc, tc.buckets, result.Buckets))
+		assert.Equal(t, tc.total, result.Total, fmt.Sprintf("%s: expected %v got %v", desc, tc.total, result.Total))
+	}
+}
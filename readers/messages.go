@@ -5,6 +5,8 @@ package readers
 
 import (
 	"errors"
+	"strconv"
+	"time"
 
 	"github.com/mainflux/mainflux"
 )
@@ -15,15 +17,89 @@ var ErrNotFound = errors.New("entity not found")
 // MessageRepository specifies message reader API.
 type MessageRepository interface {
 	// ReadAll skips given number of messages for given channel and returns next
-	// limited number of messages.
+	// limited number of messages. Besides the message fields, the query may
+	// contain "from" and "to" Unix timestamps restricting the messages to the
+	// ones published at or after "from" and before "to". If the query
+	// contains the "aggregation" and "interval", numeric message values are
+	// aggregated into the time buckets of the interval length, which are
+	// returned newest first instead of the messages.
 	ReadAll(string, uint64, uint64, map[string]string) (MessagesPage, error)
+
+	// ReadStream passes the messages of the given channel, newest first, to
+	// the provided function until the messages are exhausted or the function
+	// returns an error, which is then returned. Messages are filtered as in
+	// ReadAll, while the aggregation is ignored.
+	ReadStream(string, map[string]string, func(mainflux.Message) error) error
+
+	// Query executes validated query against messages of the given channel.
+	Query(string, Query) (QueryResult, error)
 }
 
 // MessagesPage contains page related metadata as well as list of messages that
-// belong to this page.
+// belong to this page. Pages of the aggregated messages contain the buckets,
+// and the total is the number of the buckets.
 type MessagesPage struct {
 	Total    uint64
 	Offset   uint64
 	Limit    uint64
 	Messages []mainflux.Message
+	Buckets  []Bucket
+}
+
+// Bucket contains the aggregated value of the messages published within the
+// interval starting at the bucket time.
+type Bucket struct {
+	Time  float64
+	Value float64
+}
+
+// TimeRange parses the optional "from" and "to" bounds of the ReadAll query.
+// Bounds missing from the query are returned as nil.
+func TimeRange(query map[string]string) (*float64, *float64, error) {
+	from, err := parseTime(query, "from")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	to, err := parseTime(query, "to")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return from, to, nil
+}
+
+func parseTime(query map[string]string, name string) (*float64, error) {
+	val, ok := query[name]
+	if !ok {
+		return nil, nil
+	}
+
+	t, err := strconv.ParseFloat(val, 64)
+	if err != nil {
+		return nil, ErrInvalidQuery
+	}
+
+	return &t, nil
+}
+
+// TimeBuckets parses the optional "aggregation" and "interval" of the ReadAll
+// query. Empty aggregation is returned if the messages are not aggregated.
+func TimeBuckets(query map[string]string) (string, time.Duration, error) {
+	agg, aggOK := query["aggregation"]
+	val, intervalOK := query["interval"]
+	if !aggOK && !intervalOK {
+		return "", 0, nil
+	}
+
+	if !aggOK || !intervalOK || !aggregations[agg] {
+		return "", 0, ErrInvalidQuery
+	}
+
+	interval, err := time.ParseDuration(val)
+	if err != nil || interval < time.Second {
+		return "", 0, ErrInvalidQuery
+	}
+
+	return agg, interval, nil
 }
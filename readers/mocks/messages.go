@@ -4,6 +4,8 @@
 package mocks
 
 import (
+	"math"
+	"sort"
 	"sync"
 
 	"github.com/mainflux/mainflux"
@@ -29,9 +31,29 @@ func (repo *messageRepositoryMock) ReadAll(chanID string, offset, limit uint64,
 	repo.mutex.Lock()
 	defer repo.mutex.Unlock()
 
+	from, to, err := readers.TimeRange(query)
+	if err != nil {
+		return readers.MessagesPage{}, err
+	}
+
+	msgs := []mainflux.Message{}
+	for _, m := range repo.messages[chanID] {
+		if (from == nil || m.Time >= *from) && (to == nil || m.Time < *to) {
+			msgs = append(msgs, m)
+		}
+	}
+
+	agg, interval, err := readers.TimeBuckets(query)
+	if err != nil {
+		return readers.MessagesPage{}, err
+	}
+	if agg != "" {
+		return countBuckets(msgs, agg, interval.Seconds(), offset, limit)
+	}
+
 	end := offset + limit
 
-	numOfMessages := uint64(len(repo.messages[chanID]))
+	numOfMessages := uint64(len(msgs))
 	if offset < 0 || offset >= numOfMessages {
 		return readers.MessagesPage{}, nil
 	}
@@ -48,6 +70,97 @@ func (repo *messageRepositoryMock) ReadAll(chanID string, offset, limit uint64,
 		Total:    numOfMessages,
 		Limit:    limit,
 		Offset:   offset,
-		Messages: repo.messages[chanID][offset:end],
+		Messages: msgs[offset:end],
 	}, nil
 }
+
+func (repo *messageRepositoryMock) ReadStream(chanID string, query map[string]string, fn func(mainflux.Message) error) error {
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+
+	from, to, err := readers.TimeRange(query)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range repo.messages[chanID] {
+		if (from != nil && m.Time < *from) || (to != nil && m.Time >= *to) {
+			continue
+		}
+		if err := fn(m); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (repo *messageRepositoryMock) Query(chanID string, query readers.Query) (readers.QueryResult, error) {
+	page, err := repo.ReadAll(chanID, query.Offset, query.Limit, nil)
+	if err != nil {
+		return readers.QueryResult{}, err
+	}
+
+	res := readers.QueryResult{
+		Offset: query.Offset,
+		Limit:  query.Limit,
+	}
+
+	if query.Aggregation == "" {
+		res.Messages = page.Messages
+		return res, nil
+	}
+
+	// Mock aggregation supports counting only, which is sufficient to
+	// exercise the API layer.
+	if query.Aggregation != readers.AggCount || len(query.GroupBy) > 0 {
+		return readers.QueryResult{}, readers.ErrUnsupportedQuery
+	}
+
+	res.Groups = []readers.Group{
+		{
+			Keys:  map[string]string{},
+			Value: float64(len(repo.messages[chanID])),
+		},
+	}
+
+	return res, nil
+}
+
+// countBuckets supports counting only, which is sufficient to exercise the
+// API layer.
+func countBuckets(msgs []mainflux.Message, agg string, interval float64, offset, limit uint64) (readers.MessagesPage, error) {
+	if agg != readers.AggCount {
+		return readers.MessagesPage{}, readers.ErrUnsupportedQuery
+	}
+
+	counts := map[float64]float64{}
+	for _, m := range msgs {
+		counts[math.Floor(m.Time/interval)*interval]++
+	}
+
+	buckets := []readers.Bucket{}
+	for t, c := range counts {
+		buckets = append(buckets, readers.Bucket{Time: t, Value: c})
+	}
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i].Time > buckets[j].Time })
+
+	page := readers.MessagesPage{
+		Total:    uint64(len(buckets)),
+		Offset:   offset,
+		Limit:    limit,
+		Messages: []mainflux.Message{},
+		Buckets:  []readers.Bucket{},
+	}
+	if offset >= page.Total {
+		return page, nil
+	}
+
+	end := offset + limit
+	if end > page.Total {
+		end = page.Total
+	}
+	page.Buckets = buckets[offset:end]
+
+	return page, nil
+}
@@ -0,0 +1,30 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"sort"
+
+	"github.com/mainflux/mainflux/readers"
+)
+
+var _ readers.SubtopicRepository = (*subtopicRepositoryMock)(nil)
+
+type subtopicRepositoryMock struct {
+	subtopics map[string][]readers.Subtopic
+}
+
+// NewSubtopicRepository returns mock implementation of subtopic repository.
+func NewSubtopicRepository(subtopics map[string][]readers.Subtopic) readers.SubtopicRepository {
+	return &subtopicRepositoryMock{subtopics: subtopics}
+}
+
+func (repo *subtopicRepositoryMock) RetrieveSubtopics(chanID string) ([]readers.Subtopic, error) {
+	subtopics := append([]readers.Subtopic{}, repo.subtopics[chanID]...)
+	sort.Slice(subtopics, func(i, j int) bool {
+		return subtopics[i].Name < subtopics[j].Name
+	})
+
+	return subtopics, nil
+}
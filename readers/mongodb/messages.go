@@ -37,6 +37,7 @@ type message struct {
 	Time        float64  `bson:"time,omitempty"`
 	UpdateTime  float64  `bson:"updateTime,omitempty"`
 	Link        string   `bson:"link,omitempty"`
+	Sequence    uint64   `bson:"sequence,omitempty"`
 }
 
 // New returns new MongoDB reader.
@@ -52,7 +53,22 @@ func (repo mongoRepository) ReadAll(chanID string, offset, limit uint64, query m
 		"time": -1,
 	}
 
-	filter := fmtCondition(chanID, query)
+	from, to, err := readers.TimeRange(query)
+	if err != nil {
+		return readers.MessagesPage{}, err
+	}
+
+	agg, interval, err := readers.TimeBuckets(query)
+	if err != nil {
+		return readers.MessagesPage{}, err
+	}
+
+	filter := fmtCondition(chanID, query, from, to)
+
+	if agg != "" {
+		return repo.readBuckets(*filter, agg, interval.Seconds(), offset, limit)
+	}
+
 	cursor, err := col.Find(context.Background(), filter, options.Find().SetSort(sortMap).SetLimit(int64(limit)).SetSkip(int64(offset)))
 	if err != nil {
 		return readers.MessagesPage{}, err
@@ -66,34 +82,7 @@ func (repo mongoRepository) ReadAll(chanID string, offset, limit uint64, query m
 			return readers.MessagesPage{}, err
 		}
 
-		msg := mainflux.Message{
-			Channel:    m.Channel,
-			Subtopic:   m.Subtopic,
-			Publisher:  m.Publisher,
-			Protocol:   m.Protocol,
-			Name:       m.Name,
-			Unit:       m.Unit,
-			Time:       m.Time,
-			UpdateTime: m.UpdateTime,
-			Link:       m.Link,
-		}
-
-		switch {
-		case m.FloatValue != nil:
-			msg.Value = &mainflux.Message_FloatValue{FloatValue: *m.FloatValue}
-		case m.StringValue != nil:
-			msg.Value = &mainflux.Message_StringValue{StringValue: *m.StringValue}
-		case m.DataValue != nil:
-			msg.Value = &mainflux.Message_DataValue{DataValue: *m.DataValue}
-		case m.BoolValue != nil:
-			msg.Value = &mainflux.Message_BoolValue{BoolValue: *m.BoolValue}
-		}
-
-		if m.ValueSum != nil {
-			msg.ValueSum = &mainflux.SumValue{Value: *m.ValueSum}
-		}
-
-		messages = append(messages, msg)
+		messages = append(messages, toMessage(m))
 	}
 
 	total, err := col.CountDocuments(context.Background(), filter)
@@ -112,7 +101,180 @@ func (repo mongoRepository) ReadAll(chanID string, offset, limit uint64, query m
 	}, nil
 }
 
-func fmtCondition(chanID string, query map[string]string) *bson.D {
+func (repo mongoRepository) ReadStream(chanID string, query map[string]string, fn func(mainflux.Message) error) error {
+	col := repo.db.Collection(collection)
+	sortMap := map[string]interface{}{
+		"time": -1,
+	}
+
+	from, to, err := readers.TimeRange(query)
+	if err != nil {
+		return err
+	}
+
+	filter := fmtCondition(chanID, query, from, to)
+	cursor, err := col.Find(context.Background(), filter, options.Find().SetSort(sortMap))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(context.Background())
+
+	for cursor.Next(context.Background()) {
+		var m message
+		if err := cursor.Decode(&m); err != nil {
+			return err
+		}
+
+		if err := fn(toMessage(m)); err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
+
+func (repo mongoRepository) readBuckets(filter bson.D, agg string, interval float64, offset, limit uint64) (readers.MessagesPage, error) {
+	col := repo.db.Collection(collection)
+
+	acc := bson.M{"$" + agg: "$value"}
+	if agg == readers.AggCount {
+		acc = bson.M{"$sum": 1}
+	}
+
+	// Buckets start at the multiples of the interval.
+	bucket := bson.M{"$subtract": bson.A{"$time", bson.M{"$mod": bson.A{"$time", interval}}}}
+	match := append(filter, bson.E{Key: "value", Value: bson.M{"$exists": true}})
+	group := bson.M{"$group": bson.M{"_id": bucket, "value": acc}}
+
+	pipeline := []bson.M{
+		{"$match": match},
+		group,
+		{"$sort": bson.M{"_id": -1}},
+		{"$skip": int64(offset)},
+		{"$limit": int64(limit)},
+	}
+
+	cursor, err := col.Aggregate(context.Background(), pipeline)
+	if err != nil {
+		return readers.MessagesPage{}, err
+	}
+	defer cursor.Close(context.Background())
+
+	page := readers.MessagesPage{
+		Offset:   offset,
+		Limit:    limit,
+		Messages: []mainflux.Message{},
+		Buckets:  []readers.Bucket{},
+	}
+	for cursor.Next(context.Background()) {
+		var b struct {
+			Time  float64 `bson:"_id"`
+			Value float64 `bson:"value"`
+		}
+		if err := cursor.Decode(&b); err != nil {
+			return readers.MessagesPage{}, err
+		}
+		page.Buckets = append(page.Buckets, readers.Bucket{Time: b.Time, Value: b.Value})
+	}
+
+	count, err := col.Aggregate(context.Background(), []bson.M{
+		{"$match": match},
+		group,
+		{"$count": "total"},
+	})
+	if err != nil {
+		return readers.MessagesPage{}, err
+	}
+	defer count.Close(context.Background())
+
+	if count.Next(context.Background()) {
+		var c struct {
+			Total int64 `bson:"total"`
+		}
+		if err := count.Decode(&c); err != nil {
+			return readers.MessagesPage{}, err
+		}
+		page.Total = uint64(c.Total)
+	}
+
+	return page, nil
+}
+
+func (repo mongoRepository) Query(chanID string, query readers.Query) (readers.QueryResult, error) {
+	col := repo.db.Collection(collection)
+	filter := fmtQueryCondition(chanID, query.Filters)
+
+	res := readers.QueryResult{
+		Offset: query.Offset,
+		Limit:  query.Limit,
+	}
+
+	if query.Aggregation == "" {
+		opts := options.Find().SetSort(bson.M{"time": -1}).SetLimit(int64(query.Limit)).SetSkip(int64(query.Offset))
+		cursor, err := col.Find(context.Background(), filter, opts)
+		if err != nil {
+			return readers.QueryResult{}, err
+		}
+		defer cursor.Close(context.Background())
+
+		res.Messages = []mainflux.Message{}
+		for cursor.Next(context.Background()) {
+			var m message
+			if err := cursor.Decode(&m); err != nil {
+				return readers.QueryResult{}, err
+			}
+			res.Messages = append(res.Messages, toMessage(m))
+		}
+
+		return res, nil
+	}
+
+	id := bson.M{}
+	for _, field := range query.GroupBy {
+		id[field] = "$" + field
+	}
+
+	acc := bson.M{"$" + query.Aggregation: "$value"}
+	if query.Aggregation == readers.AggCount {
+		acc = bson.M{"$sum": 1}
+	}
+
+	match := append(*filter, bson.E{Key: "value", Value: bson.M{"$exists": true}})
+	pipeline := []bson.M{
+		{"$match": match},
+		{"$group": bson.M{"_id": id, "value": acc}},
+		{"$sort": bson.M{"_id": 1}},
+		{"$skip": int64(query.Offset)},
+		{"$limit": int64(query.Limit)},
+	}
+
+	cursor, err := col.Aggregate(context.Background(), pipeline)
+	if err != nil {
+		return readers.QueryResult{}, err
+	}
+	defer cursor.Close(context.Background())
+
+	res.Groups = []readers.Group{}
+	for cursor.Next(context.Background()) {
+		var g struct {
+			ID    map[string]string `bson:"_id"`
+			Value float64           `bson:"value"`
+		}
+		if err := cursor.Decode(&g); err != nil {
+			return readers.QueryResult{}, err
+		}
+
+		keys := map[string]string{}
+		for _, field := range query.GroupBy {
+			keys[field] = g.ID[field]
+		}
+		res.Groups = append(res.Groups, readers.Group{Keys: keys, Value: g.Value})
+	}
+
+	return res, nil
+}
+
+func fmtCondition(chanID string, query map[string]string, from, to *float64) *bson.D {
 	filter := bson.D{
 		bson.E{
 			Key:   "channel",
@@ -131,5 +293,82 @@ func fmtCondition(chanID string, query map[string]string) *bson.D {
 		}
 	}
 
+	if from != nil || to != nil {
+		timeRange := bson.M{}
+		if from != nil {
+			timeRange["$gte"] = *from
+		}
+		if to != nil {
+			timeRange["$lt"] = *to
+		}
+		filter = append(filter, bson.E{Key: "time", Value: timeRange})
+	}
+
+	return &filter
+}
+
+var mongoOperators = map[string]string{
+	readers.OpEq:  "$eq",
+	readers.OpNeq: "$ne",
+	readers.OpLt:  "$lt",
+	readers.OpLte: "$lte",
+	readers.OpGt:  "$gt",
+	readers.OpGte: "$gte",
+}
+
+// mongoFields maps query fields to the document keys used by MongoDB writer.
+var mongoFields = map[string]string{
+	"string_value": "stringValue",
+	"bool_value":   "boolValue",
+}
+
+func fmtQueryCondition(chanID string, filters []readers.Filter) *bson.D {
+	filter := bson.D{
+		bson.E{
+			Key:   "channel",
+			Value: chanID,
+		},
+	}
+
+	for _, f := range filters {
+		key := f.Field
+		if k, ok := mongoFields[key]; ok {
+			key = k
+		}
+		filter = append(filter, bson.E{Key: key, Value: bson.M{mongoOperators[f.Operator]: f.Value}})
+	}
+
 	return &filter
 }
+
+func toMessage(m message) mainflux.Message {
+	msg := mainflux.Message{
+		Channel:    m.Channel,
+		Subtopic:   m.Subtopic,
+		Publisher:  m.Publisher,
+		Protocol:   m.Protocol,
+		Name:       m.Name,
+		Unit:       m.Unit,
+		Time:       m.Time,
+		UpdateTime: m.UpdateTime,
+		Link:       m.Link,
+		Sequence:   m.Sequence,
+	}
+
+	switch {
+	case m.FloatValue != nil:
+		msg.Value = &mainflux.Message_FloatValue{FloatValue: *m.FloatValue}
+	case m.StringValue != nil:
+		msg.Value = &mainflux.Message_StringValue{StringValue: *m.StringValue}
+	case m.DataValue != nil:
+		msg.Value = &mainflux.Message_DataValue{DataValue: *m.DataValue}
+	case m.BoolValue != nil:
+		msg.Value = &mainflux.Message_BoolValue{BoolValue: *m.BoolValue}
+	}
+
+	if m.ValueSum != nil {
+		msg.ValueSum = &mainflux.SumValue{Value: *m.ValueSum}
+	}
+
+	return msg
+}
@@ -0,0 +1,90 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/mainflux/mainflux"
+	preader "github.com/mainflux/mainflux/readers/postgres"
+	pwriter "github.com/mainflux/mainflux/writers/postgres"
+	"github.com/stretchr/testify/require"
+)
+
+const benchMsgsNum = 5000
+
+// saveBenchMessages saves the messages of all the value types to the new
+// channel and returns the channel ID.
+func saveBenchMessages(b *testing.B) string {
+	chanID, err := uuid.NewV4()
+	require.Nil(b, err, fmt.Sprintf("got unexpected error: %s", err))
+	pubID, err := uuid.NewV4()
+	require.Nil(b, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	msgs := make([]mainflux.Message, benchMsgsNum)
+	now := time.Now().Unix()
+	for i := range msgs {
+		msg := mainflux.Message{
+			Channel:   chanID.String(),
+			Publisher: pubID.String(),
+			Protocol:  "mqtt",
+			Subtopic:  subtopic,
+			Name:      "temperature",
+			Unit:      "C",
+			Time:      float64(now - int64(i)),
+		}
+		switch i % valueFields {
+		case 0:
+			msg.Value = &mainflux.Message_FloatValue{FloatValue: 5}
+		case 1:
+			msg.Value = &mainflux.Message_BoolValue{BoolValue: false}
+		case 2:
+			msg.Value = &mainflux.Message_StringValue{StringValue: "value"}
+		case 3:
+			msg.Value = &mainflux.Message_DataValue{DataValue: "base64data"}
+		default:
+			msg.ValueSum = &mainflux.SumValue{Value: 45}
+		}
+		msgs[i] = msg
+	}
+
+	err = pwriter.New(db).Save(msgs...)
+	require.Nil(b, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	return chanID.String()
+}
+
+func BenchmarkReadAll(b *testing.B) {
+	chanID := saveBenchMessages(b)
+	reader := preader.New(db)
+
+	for _, limit := range []uint64{100, 1000, benchMsgsNum} {
+		b.Run(fmt.Sprintf("limit %d", limit), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := reader.ReadAll(chanID, 0, limit, nil); err != nil {
+					b.Fatalf("got unexpected error: %s", err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkReadStream(b *testing.B) {
+	chanID := saveBenchMessages(b)
+	reader := preader.New(db)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		err := reader.ReadStream(chanID, nil, func(mainflux.Message) error {
+			return nil
+		})
+		if err != nil {
+			b.Fatalf("got unexpected error: %s", err)
+		}
+	}
+}
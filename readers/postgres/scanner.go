@@ -0,0 +1,128 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"database/sql"
+	"sync"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/mainflux/mainflux"
+)
+
+// messageColumns are the columns decoded into the message. Channel isn't
+// selected, since it's the one the messages are read from.
+const messageColumns = `subtopic, publisher, protocol, name, unit, value, string_value,
+	bool_value, data_value, value_sum, time, update_time, link, sequence`
+
+// maxPrealloc caps the capacity of the preallocated message slice, so that
+// the large limits don't allocate for the messages which may not exist.
+const maxPrealloc = 1000
+
+var scanners = sync.Pool{
+	New: func() interface{} {
+		return newScanner()
+	},
+}
+
+// scanner decodes the rows directly into the message, without the
+// intermediate struct and the reflection based mapping of the columns. The
+// destinations are reused between the rows and the scanners are pooled
+// between the reads, so decoding the row allocates only the message fields.
+type scanner struct {
+	msg         mainflux.Message
+	floatValue  sql.NullFloat64
+	stringValue sql.NullString
+	boolValue   sql.NullBool
+	dataValue   sql.NullString
+	valueSum    sql.NullFloat64
+	dest        []interface{}
+}
+
+func newScanner() *scanner {
+	s := &scanner{}
+	s.dest = []interface{}{
+		&s.msg.Subtopic,
+		&s.msg.Publisher,
+		&s.msg.Protocol,
+		&s.msg.Name,
+		&s.msg.Unit,
+		&s.floatValue,
+		&s.stringValue,
+		&s.boolValue,
+		&s.dataValue,
+		&s.valueSum,
+		&s.msg.Time,
+		&s.msg.UpdateTime,
+		&s.msg.Link,
+		&s.msg.Sequence,
+	}
+
+	return s
+}
+
+func acquireScanner() *scanner {
+	return scanners.Get().(*scanner)
+}
+
+// release returns the scanner to the pool, dropping the references to the
+// last decoded message.
+func (s *scanner) release() {
+	s.msg = mainflux.Message{}
+	s.stringValue = sql.NullString{}
+	s.dataValue = sql.NullString{}
+	scanners.Put(s)
+}
+
+// scan decodes the current row into the message of the channel.
+func (s *scanner) scan(rows *sqlx.Rows, chanID string) (mainflux.Message, error) {
+	s.msg = mainflux.Message{}
+	if err := rows.Scan(s.dest...); err != nil {
+		return mainflux.Message{}, err
+	}
+
+	msg := s.msg
+	msg.Channel = chanID
+
+	switch {
+	case s.floatValue.Valid:
+		msg.Value = &mainflux.Message_FloatValue{FloatValue: s.floatValue.Float64}
+	case s.stringValue.Valid:
+		msg.Value = &mainflux.Message_StringValue{StringValue: s.stringValue.String}
+	case s.boolValue.Valid:
+		msg.Value = &mainflux.Message_BoolValue{BoolValue: s.boolValue.Bool}
+	case s.dataValue.Valid:
+		msg.Value = &mainflux.Message_DataValue{DataValue: s.dataValue.String}
+	case s.valueSum.Valid:
+		msg.ValueSum = &mainflux.SumValue{Value: s.valueSum.Float64}
+	}
+
+	return msg, nil
+}
+
+// scanMessages decodes the remaining rows into the messages, preallocating
+// the slice for the expected number of rows.
+func scanMessages(rows *sqlx.Rows, chanID string, expected uint64) ([]mainflux.Message, error) {
+	if expected > maxPrealloc {
+		expected = maxPrealloc
+	}
+	msgs := make([]mainflux.Message, 0, expected)
+
+	s := acquireScanner()
+	defer s.release()
+
+	for rows.Next() {
+		msg, err := s.scan(rows, chanID)
+		if err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, msg)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return msgs, nil
+}
@@ -0,0 +1,41 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/mainflux/mainflux/readers"
+)
+
+var _ readers.SubtopicRepository = (*subtopicRepository)(nil)
+
+type subtopicRepository struct {
+	db *sqlx.DB
+}
+
+// NewSubtopicRepository returns new PostgreSQL subtopic repository.
+func NewSubtopicRepository(db *sqlx.DB) readers.SubtopicRepository {
+	return &subtopicRepository{db: db}
+}
+
+func (sr subtopicRepository) RetrieveSubtopics(chanID string) ([]readers.Subtopic, error) {
+	q := `SELECT subtopic, last_seen FROM subtopics WHERE channel = $1 ORDER BY subtopic;`
+
+	rows, err := sr.db.Queryx(q, chanID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	subtopics := []readers.Subtopic{}
+	for rows.Next() {
+		var st readers.Subtopic
+		if err := rows.Scan(&st.Name, &st.LastSeen); err != nil {
+			return nil, err
+		}
+		subtopics = append(subtopics, st)
+	}
+
+	return subtopics, rows.Err()
+}
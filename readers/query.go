@@ -0,0 +1,149 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package readers
+
+import (
+	"errors"
+
+	"github.com/mainflux/mainflux"
+)
+
+const (
+	// OpEq matches messages whose field is equal to the filter value.
+	OpEq = "eq"
+	// OpNeq matches messages whose field differs from the filter value.
+	OpNeq = "neq"
+	// OpLt matches messages whose field is less than the filter value.
+	OpLt = "lt"
+	// OpLte matches messages whose field is less than or equal to the
+	// filter value.
+	OpLte = "lte"
+	// OpGt matches messages whose field is greater than the filter value.
+	OpGt = "gt"
+	// OpGte matches messages whose field is greater than or equal to the
+	// filter value.
+	OpGte = "gte"
+)
+
+const (
+	// AggCount counts messages with numeric value.
+	AggCount = "count"
+	// AggSum sums numeric values.
+	AggSum = "sum"
+	// AggAvg averages numeric values.
+	AggAvg = "avg"
+	// AggMin returns the smallest numeric value.
+	AggMin = "min"
+	// AggMax returns the largest numeric value.
+	AggMax = "max"
+)
+
+const maxGroupBy = 3
+
+var (
+	// ErrInvalidQuery indicates malformed query.
+	ErrInvalidQuery = errors.New("invalid query")
+
+	// ErrUnsupportedQuery indicates that the query is valid, but cannot be
+	// executed by the underlying database.
+	ErrUnsupportedQuery = errors.New("query not supported by the database")
+)
+
+// stringFields can be compared for equality only, while numericFields
+// support all of the operators.
+var (
+	stringFields  = map[string]bool{"subtopic": true, "publisher": true, "protocol": true, "name": true, "unit": true, "string_value": true}
+	numericFields = map[string]bool{"value": true, "time": true}
+	boolFields    = map[string]bool{"bool_value": true}
+	groupFields   = map[string]bool{"subtopic": true, "publisher": true, "name": true}
+	operators     = map[string]bool{OpEq: true, OpNeq: true, OpLt: true, OpLte: true, OpGt: true, OpGte: true}
+	aggregations  = map[string]bool{AggCount: true, AggSum: true, AggAvg: true, AggMin: true, AggMax: true}
+)
+
+// Filter restricts the query result to the messages whose field satisfies
+// the operator for the given value.
+type Filter struct {
+	Field    string
+	Operator string
+	Value    interface{}
+}
+
+// Query represents a backend independent message query. If aggregation is
+// set, numeric message values are aggregated per group instead of returning
+// raw messages.
+type Query struct {
+	Filters     []Filter
+	Aggregation string
+	GroupBy     []string
+	Offset      uint64
+	Limit       uint64
+}
+
+// Group contains aggregated value of the messages sharing the same values
+// of the grouping fields.
+type Group struct {
+	Keys  map[string]string
+	Value float64
+}
+
+// QueryResult contains either raw messages or aggregated groups, depending
+// on whether the query requested aggregation.
+type QueryResult struct {
+	Offset   uint64
+	Limit    uint64
+	Messages []mainflux.Message
+	Groups   []Group
+}
+
+// Validate checks if the query can be executed against any of the supported
+// databases.
+func (q Query) Validate() error {
+	if q.Limit < 1 {
+		return ErrInvalidQuery
+	}
+
+	for _, f := range q.Filters {
+		if !operators[f.Operator] {
+			return ErrInvalidQuery
+		}
+
+		switch {
+		case stringFields[f.Field]:
+			if _, ok := f.Value.(string); !ok || (f.Operator != OpEq && f.Operator != OpNeq) {
+				return ErrInvalidQuery
+			}
+		case boolFields[f.Field]:
+			if _, ok := f.Value.(bool); !ok || (f.Operator != OpEq && f.Operator != OpNeq) {
+				return ErrInvalidQuery
+			}
+		case numericFields[f.Field]:
+			if _, ok := f.Value.(float64); !ok {
+				return ErrInvalidQuery
+			}
+		default:
+			return ErrInvalidQuery
+		}
+	}
+
+	if q.Aggregation == "" {
+		if len(q.GroupBy) > 0 {
+			return ErrInvalidQuery
+		}
+		return nil
+	}
+
+	if !aggregations[q.Aggregation] || len(q.GroupBy) > maxGroupBy {
+		return ErrInvalidQuery
+	}
+
+	seen := map[string]bool{}
+	for _, field := range q.GroupBy {
+		if !groupFields[field] || seen[field] {
+			return ErrInvalidQuery
+		}
+		seen[field] = true
+	}
+
+	return nil
+}
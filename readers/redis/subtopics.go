@@ -0,0 +1,50 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package redis
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/go-redis/redis"
+	"github.com/mainflux/mainflux/readers"
+)
+
+// subtopicsPrefix must match the prefix the writers record the subtopics
+// under.
+const subtopicsPrefix = "subtopics:channel"
+
+var _ readers.SubtopicRepository = (*subtopicRepo)(nil)
+
+type subtopicRepo struct {
+	client redis.UniversalClient
+}
+
+// NewSubtopicRepository returns new Redis subtopic repository.
+func NewSubtopicRepository(client redis.UniversalClient) readers.SubtopicRepository {
+	return &subtopicRepo{client: client}
+}
+
+func (sr subtopicRepo) RetrieveSubtopics(chanID string) ([]readers.Subtopic, error) {
+	res, err := sr.client.HGetAll(fmt.Sprintf("%s:%s", subtopicsPrefix, chanID)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	subtopics := make([]readers.Subtopic, 0, len(res))
+	for name, seen := range res {
+		t, err := strconv.ParseFloat(seen, 64)
+		if err != nil {
+			return nil, err
+		}
+		subtopics = append(subtopics, readers.Subtopic{Name: name, LastSeen: t})
+	}
+
+	sort.Slice(subtopics, func(i, j int) bool {
+		return subtopics[i].Name < subtopics[j].Name
+	})
+
+	return subtopics, nil
+}
@@ -0,0 +1,19 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package readers
+
+// Subtopic represents the subtopic of the channel messages were published
+// to, along with the time of the newest of them.
+type Subtopic struct {
+	Name     string
+	LastSeen float64
+}
+
+// SubtopicRepository specifies the subtopics discovery API. The subtopics
+// are recorded by the writers as the messages are saved.
+type SubtopicRepository interface {
+	// RetrieveSubtopics returns the subtopics of the given channel, ordered
+	// by name.
+	RetrieveSubtopics(string) ([]Subtopic, error)
+}
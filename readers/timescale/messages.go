@@ -0,0 +1,392 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package timescale
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx" // required for DB access
+	"github.com/lib/pq"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/readers"
+)
+
+const (
+	errInvalid = "invalid_text_representation"
+
+	// columns converts the timestamp the hypertable is partitioned by back
+	// to the Unix timestamp of the message. The messages stored before the
+	// sequence column was added are unsequenced.
+	columns = `channel, subtopic, publisher, protocol, name, unit, value,
+	string_value, bool_value, data_value, value_sum, EXTRACT(EPOCH FROM time) AS time,
+	update_time, link, COALESCE(sequence, 0) AS sequence`
+
+	// bucket starts the time buckets at the multiples of the interval since
+	// the Unix epoch, as the other readers do.
+	bucket = `time_bucket(make_interval(secs => :interval), time, TIMESTAMPTZ 'epoch')`
+)
+
+var errInvalidMessage = errors.New("invalid message representation")
+
+var _ readers.MessageRepository = (*timescaleRepository)(nil)
+
+type timescaleRepository struct {
+	db *sqlx.DB
+}
+
+// New returns new TimescaleDB reader.
+func New(db *sqlx.DB) readers.MessageRepository {
+	return &timescaleRepository{
+		db: db,
+	}
+}
+
+func (tr timescaleRepository) ReadAll(chanID string, offset, limit uint64, query map[string]string) (readers.MessagesPage, error) {
+	from, to, err := readers.TimeRange(query)
+	if err != nil {
+		return readers.MessagesPage{}, err
+	}
+
+	agg, interval, err := readers.TimeBuckets(query)
+	if err != nil {
+		return readers.MessagesPage{}, err
+	}
+
+	condition, params := fmtCondition(chanID, query, from, to)
+	params["limit"] = limit
+	params["offset"] = offset
+
+	if agg != "" {
+		params["interval"] = interval.Seconds()
+		return tr.readBuckets(condition, params, agg, offset, limit)
+	}
+
+	q := fmt.Sprintf(`SELECT %s FROM messages
+    WHERE %s ORDER BY messages.time DESC
+    LIMIT :limit OFFSET :offset;`, columns, condition)
+
+	rows, err := tr.db.NamedQuery(q, params)
+	if err != nil {
+		return readers.MessagesPage{}, err
+	}
+	defer rows.Close()
+
+	page := readers.MessagesPage{
+		Offset:   offset,
+		Limit:    limit,
+		Messages: []mainflux.Message{},
+	}
+	for rows.Next() {
+		dbm := dbMessage{Channel: chanID}
+		if err := rows.StructScan(&dbm); err != nil {
+			return readers.MessagesPage{}, err
+		}
+
+		msg, err := toMessage(dbm)
+		if err != nil {
+			return readers.MessagesPage{}, err
+		}
+
+		page.Messages = append(page.Messages, msg)
+	}
+
+	q = fmt.Sprintf(`SELECT COUNT(*) FROM messages WHERE %s;`, condition)
+	q, args, err := tr.db.BindNamed(q, params)
+	if err != nil {
+		return readers.MessagesPage{}, err
+	}
+
+	if err := tr.db.QueryRow(q, args...).Scan(&page.Total); err != nil {
+		return readers.MessagesPage{}, err
+	}
+
+	return page, nil
+}
+
+func (tr timescaleRepository) ReadStream(chanID string, query map[string]string, fn func(mainflux.Message) error) error {
+	from, to, err := readers.TimeRange(query)
+	if err != nil {
+		return err
+	}
+
+	condition, params := fmtCondition(chanID, query, from, to)
+	q := fmt.Sprintf(`SELECT %s FROM messages WHERE %s ORDER BY messages.time DESC;`, columns, condition)
+
+	rows, err := tr.db.NamedQuery(q, params)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		dbm := dbMessage{Channel: chanID}
+		if err := rows.StructScan(&dbm); err != nil {
+			return err
+		}
+
+		msg, err := toMessage(dbm)
+		if err != nil {
+			return err
+		}
+
+		if err := fn(msg); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// readBuckets aggregates the messages into the hypertable time buckets.
+func (tr timescaleRepository) readBuckets(condition string, params map[string]interface{}, agg string, offset, limit uint64) (readers.MessagesPage, error) {
+	q := fmt.Sprintf(`SELECT EXTRACT(EPOCH FROM %s) AS bucket, %s(value) AS value
+	FROM messages WHERE %s AND value IS NOT NULL
+	GROUP BY bucket ORDER BY bucket DESC LIMIT :limit OFFSET :offset;`, bucket, sqlAggregations[agg], condition)
+
+	rows, err := tr.db.NamedQuery(q, params)
+	if err != nil {
+		return readers.MessagesPage{}, err
+	}
+	defer rows.Close()
+
+	page := readers.MessagesPage{
+		Offset:   offset,
+		Limit:    limit,
+		Messages: []mainflux.Message{},
+		Buckets:  []readers.Bucket{},
+	}
+	for rows.Next() {
+		var b struct {
+			Time  float64 `db:"bucket"`
+			Value float64 `db:"value"`
+		}
+		if err := rows.StructScan(&b); err != nil {
+			return readers.MessagesPage{}, err
+		}
+
+		page.Buckets = append(page.Buckets, readers.Bucket{Time: b.Time, Value: b.Value})
+	}
+
+	q = fmt.Sprintf(`SELECT COUNT(DISTINCT %s) FROM messages
+	WHERE %s AND value IS NOT NULL;`, bucket, condition)
+	q, args, err := tr.db.BindNamed(q, params)
+	if err != nil {
+		return readers.MessagesPage{}, err
+	}
+
+	if err := tr.db.QueryRow(q, args...).Scan(&page.Total); err != nil {
+		return readers.MessagesPage{}, err
+	}
+
+	return page, nil
+}
+
+func (tr timescaleRepository) Query(chanID string, query readers.Query) (readers.QueryResult, error) {
+	condition, params := fmtQueryCondition(chanID, query.Filters)
+	params["limit"] = query.Limit
+	params["offset"] = query.Offset
+
+	res := readers.QueryResult{
+		Offset: query.Offset,
+		Limit:  query.Limit,
+	}
+
+	if query.Aggregation == "" {
+		q := fmt.Sprintf(`SELECT %s FROM messages WHERE %s ORDER BY messages.time DESC
+		LIMIT :limit OFFSET :offset;`, columns, condition)
+
+		rows, err := tr.db.NamedQuery(q, params)
+		if err != nil {
+			return readers.QueryResult{}, fmtQueryError(err)
+		}
+		defer rows.Close()
+
+		res.Messages = []mainflux.Message{}
+		for rows.Next() {
+			dbm := dbMessage{Channel: chanID}
+			if err := rows.StructScan(&dbm); err != nil {
+				return readers.QueryResult{}, err
+			}
+
+			msg, err := toMessage(dbm)
+			if err != nil {
+				return readers.QueryResult{}, err
+			}
+
+			res.Messages = append(res.Messages, msg)
+		}
+
+		return res, nil
+	}
+
+	groups := strings.Join(query.GroupBy, ", ")
+	selection := fmt.Sprintf(`%s(value) AS value`, sqlAggregations[query.Aggregation])
+	grouping := ""
+	if groups != "" {
+		selection = fmt.Sprintf(`%s, %s`, groups, selection)
+		grouping = fmt.Sprintf(` GROUP BY %s ORDER BY %s`, groups, groups)
+	}
+
+	q := fmt.Sprintf(`SELECT %s FROM messages WHERE %s AND value IS NOT NULL%s
+	LIMIT :limit OFFSET :offset;`, selection, condition, grouping)
+
+	rows, err := tr.db.NamedQuery(q, params)
+	if err != nil {
+		return readers.QueryResult{}, fmtQueryError(err)
+	}
+	defer rows.Close()
+
+	res.Groups = []readers.Group{}
+	for rows.Next() {
+		row := map[string]interface{}{}
+		if err := rows.MapScan(row); err != nil {
+			return readers.QueryResult{}, err
+		}
+
+		// Aggregation over no rows yields a single NULL value.
+		if row["value"] == nil {
+			continue
+		}
+
+		g := readers.Group{Keys: map[string]string{}}
+		for _, field := range query.GroupBy {
+			g.Keys[field] = fmt.Sprintf("%s", row[field])
+		}
+
+		switch v := row["value"].(type) {
+		case float64:
+			g.Value = v
+		case int64:
+			g.Value = float64(v)
+		}
+
+		res.Groups = append(res.Groups, g)
+	}
+
+	return res, nil
+}
+
+func fmtCondition(chanID string, query map[string]string, from, to *float64) (string, map[string]interface{}) {
+	condition := `channel = :channel`
+	params := map[string]interface{}{
+		"channel": chanID,
+	}
+	for name, value := range query {
+		switch name {
+		case
+			"subtopic",
+			"publisher",
+			"name",
+			"protocol":
+			condition = fmt.Sprintf(`%s AND %s = :%s`, condition, name, name)
+			params[name] = value
+		}
+	}
+
+	if from != nil {
+		condition = fmt.Sprintf(`%s AND time >= to_timestamp(:from)`, condition)
+		params["from"] = *from
+	}
+	if to != nil {
+		condition = fmt.Sprintf(`%s AND time < to_timestamp(:to)`, condition)
+		params["to"] = *to
+	}
+
+	return condition, params
+}
+
+type dbMessage struct {
+	Channel     string   `db:"channel"`
+	Subtopic    string   `db:"subtopic"`
+	Publisher   string   `db:"publisher"`
+	Protocol    string   `db:"protocol"`
+	Name        string   `db:"name"`
+	Unit        string   `db:"unit"`
+	FloatValue  *float64 `db:"value"`
+	StringValue *string  `db:"string_value"`
+	BoolValue   *bool    `db:"bool_value"`
+	DataValue   *string  `db:"data_value"`
+	ValueSum    *float64 `db:"value_sum"`
+	Time        float64  `db:"time"`
+	UpdateTime  float64  `db:"update_time"`
+	Link        string   `db:"link"`
+	Sequence    uint64   `db:"sequence"`
+}
+
+func toMessage(dbm dbMessage) (mainflux.Message, error) {
+	msg := mainflux.Message{
+		Channel:    dbm.Channel,
+		Subtopic:   dbm.Subtopic,
+		Publisher:  dbm.Publisher,
+		Protocol:   dbm.Protocol,
+		Name:       dbm.Name,
+		Unit:       dbm.Unit,
+		Time:       dbm.Time,
+		UpdateTime: dbm.UpdateTime,
+		Link:       dbm.Link,
+		Sequence:   dbm.Sequence,
+	}
+
+	switch {
+	case dbm.FloatValue != nil:
+		msg.Value = &mainflux.Message_FloatValue{FloatValue: *dbm.FloatValue}
+	case dbm.StringValue != nil:
+		msg.Value = &mainflux.Message_StringValue{StringValue: *dbm.StringValue}
+	case dbm.BoolValue != nil:
+		msg.Value = &mainflux.Message_BoolValue{BoolValue: *dbm.BoolValue}
+	case dbm.DataValue != nil:
+		msg.Value = &mainflux.Message_DataValue{DataValue: *dbm.DataValue}
+	case dbm.ValueSum != nil:
+		msg.ValueSum = &mainflux.SumValue{Value: *dbm.ValueSum}
+	}
+
+	return msg, nil
+}
+
+var (
+	sqlOperators = map[string]string{
+		readers.OpEq:  "=",
+		readers.OpNeq: "<>",
+		readers.OpLt:  "<",
+		readers.OpLte: "<=",
+		readers.OpGt:  ">",
+		readers.OpGte: ">=",
+	}
+	sqlAggregations = map[string]string{
+		readers.AggCount: "COUNT",
+		readers.AggSum:   "SUM",
+		readers.AggAvg:   "AVG",
+		readers.AggMin:   "MIN",
+		readers.AggMax:   "MAX",
+	}
+)
+
+// fmtQueryCondition relies on query validation to allow only known fields,
+// since field names are interpolated into the statement.
+func fmtQueryCondition(chanID string, filters []readers.Filter) (string, map[string]interface{}) {
+	condition := `channel = :channel`
+	params := map[string]interface{}{
+		"channel": chanID,
+	}
+
+	for i, f := range filters {
+		param := fmt.Sprintf(":f%d", i)
+		if f.Field == "time" {
+			param = fmt.Sprintf("to_timestamp(%s)", param)
+		}
+		condition = fmt.Sprintf(`%s AND %s %s %s`, condition, f.Field, sqlOperators[f.Operator], param)
+		params[fmt.Sprintf("f%d", i)] = f.Value
+	}
+
+	return condition, params
+}
+
+func fmtQueryError(err error) error {
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == errInvalid {
+		return readers.ErrInvalidQuery
+	}
+	return err
+}
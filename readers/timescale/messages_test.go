@@ -0,0 +1,326 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package timescale_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/readers"
+	treader "github.com/mainflux/mainflux/readers/timescale"
+	twriter "github.com/mainflux/mainflux/writers/timescale"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	subtopic    = "subtopic"
+	msgsNum     = 42
+	valueFields = 5
+)
+
+func TestMessageReadAll(t *testing.T) {
+	messageRepo := twriter.New(db)
+
+	chanID, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	pubID, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	wrongID, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	msg := mainflux.Message{
+		Channel:   chanID.String(),
+		Publisher: pubID.String(),
+		Protocol:  "mqtt",
+	}
+
+	messages := []mainflux.Message{}
+	subtopicMsgs := []mainflux.Message{}
+	now := time.Now().Unix()
+	for i := 0; i < msgsNum; i++ {
+		// Mix possible values as well as value sum.
+		count := i % valueFields
+		msg.Subtopic = ""
+		switch count {
+		case 0:
+			msg.Subtopic = subtopic
+			msg.Value = &mainflux.Message_FloatValue{FloatValue: 5}
+		case 1:
+			msg.Value = &mainflux.Message_BoolValue{BoolValue: false}
+		case 2:
+			msg.Value = &mainflux.Message_StringValue{StringValue: "value"}
+		case 3:
+			msg.Value = &mainflux.Message_DataValue{DataValue: "base64data"}
+		case 5:
+			msg.ValueSum = &mainflux.SumValue{Value: 45}
+		}
+		msg.Time = float64(now - int64(i))
+
+		err := messageRepo.Save(msg)
+		assert.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+		messages = append(messages, msg)
+		if count == 0 {
+			subtopicMsgs = append(subtopicMsgs, msg)
+		}
+	}
+
+	reader := treader.New(db)
+
+	// Since messages are not saved in natural order,
+	// cases that return subset of messages are only
+	// checking data result set size, but not content.
+	cases := map[string]struct {
+		chanID string
+		offset uint64
+		limit  uint64
+		query  map[string]string
+		page   readers.MessagesPage
+	}{
+		"read message page for existing channel": {
+			chanID: chanID.String(),
+			offset: 0,
+			limit:  msgsNum,
+			page: readers.MessagesPage{
+				Total:    msgsNum,
+				Offset:   0,
+				Limit:    msgsNum,
+				Messages: messages,
+			},
+		},
+		"read message page for non-existent channel": {
+			chanID: wrongID.String(),
+			offset: 0,
+			limit:  msgsNum,
+			page: readers.MessagesPage{
+				Total:    0,
+				Offset:   0,
+				Limit:    msgsNum,
+				Messages: []mainflux.Message{},
+			},
+		},
+		"read message last page": {
+			chanID: chanID.String(),
+			offset: 40,
+			limit:  5,
+			page: readers.MessagesPage{
+				Total:    msgsNum,
+				Offset:   40,
+				Limit:    5,
+				Messages: messages[40:42],
+			},
+		},
+		"read message with non-existent subtopic": {
+			chanID: chanID.String(),
+			offset: 0,
+			limit:  msgsNum,
+			query:  map[string]string{"subtopic": "not-present"},
+			page: readers.MessagesPage{
+				Total:    0,
+				Offset:   0,
+				Limit:    msgsNum,
+				Messages: []mainflux.Message{},
+			},
+		},
+		"read message with subtopic": {
+			chanID: chanID.String(),
+			offset: 0,
+			limit:  uint64(len(subtopicMsgs)),
+			query:  map[string]string{"subtopic": subtopic},
+			page: readers.MessagesPage{
+				Total:    uint64(len(subtopicMsgs)),
+				Offset:   0,
+				Limit:    uint64(len(subtopicMsgs)),
+				Messages: subtopicMsgs,
+			},
+		},
+		"read message with publisher/protocols": {
+			chanID: chanID.String(),
+			offset: 0,
+			limit:  msgsNum,
+			query:  map[string]string{"publisher": pubID.String(), "protocol": "mqtt"},
+			page: readers.MessagesPage{
+				Total:    msgsNum,
+				Offset:   0,
+				Limit:    msgsNum,
+				Messages: messages,
+			},
+		},
+		"read message with time range": {
+			chanID: chanID.String(),
+			offset: 0,
+			limit:  msgsNum,
+			query:  map[string]string{"from": fmt.Sprintf("%d", now-9), "to": fmt.Sprintf("%d", now-4)},
+			page: readers.MessagesPage{
+				Total:    5,
+				Offset:   0,
+				Limit:    msgsNum,
+				Messages: messages[5:10],
+			},
+		},
+	}
+
+	for desc, tc := range cases {
+		result, err := reader.ReadAll(tc.chanID, tc.offset, tc.limit, tc.query)
+		assert.Nil(t, err, fmt.Sprintf("%s: expected no error got %s", desc, err))
+		assert.ElementsMatch(t, tc.page.Messages, result.Messages, fmt.Sprintf("%s: expected %v got %v", desc, tc.page.Messages, result.Messages))
+		assert.Equal(t, tc.page.Total, result.Total, fmt.Sprintf("%s: expected %v got %v", desc, tc.page.Total, result.Total))
+	}
+}
+
+func TestMessageReadAllBuckets(t *testing.T) {
+	writer := twriter.New(db)
+	reader := treader.New(db)
+
+	chanID, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	// Messages are spread over three hours, with three, two and one message
+	// in the consecutive hourly buckets.
+	hour := int64(time.Hour / time.Second)
+	start := time.Now().Unix()/hour*hour - 3*hour
+	for i, count := range []int{3, 2, 1} {
+		for j := 0; j < count; j++ {
+			msg := mainflux.Message{
+				Channel:   chanID.String(),
+				Publisher: "1",
+				Protocol:  "mqtt",
+				Value:     &mainflux.Message_FloatValue{FloatValue: float64(j + 1)},
+				Time:      float64(start + int64(i)*hour + int64(j)),
+			}
+			err := writer.Save(msg)
+			require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+		}
+	}
+
+	cases := map[string]struct {
+		offset  uint64
+		limit   uint64
+		query   map[string]string
+		total   uint64
+		buckets []readers.Bucket
+	}{
+		"read message count per hour": {
+			offset: 0,
+			limit:  10,
+			query:  map[string]string{"aggregation": readers.AggCount, "interval": "1h"},
+			total:  3,
+			buckets: []readers.Bucket{
+				{Time: float64(start + 2*hour), Value: 1},
+				{Time: float64(start + hour), Value: 2},
+				{Time: float64(start), Value: 3},
+			},
+		},
+		"read average value per hour": {
+			offset: 0,
+			limit:  10,
+			query:  map[string]string{"aggregation": readers.AggAvg, "interval": "1h"},
+			total:  3,
+			buckets: []readers.Bucket{
+				{Time: float64(start + 2*hour), Value: 1},
+				{Time: float64(start + hour), Value: 1.5},
+				{Time: float64(start), Value: 2},
+			},
+		},
+		"read last page of hourly buckets": {
+			offset: 2,
+			limit:  1,
+			query:  map[string]string{"aggregation": readers.AggMax, "interval": "1h"},
+			total:  3,
+			buckets: []readers.Bucket{
+				{Time: float64(start), Value: 3},
+			},
+		},
+	}
+
+	for desc, tc := range cases {
+		result, err := reader.ReadAll(chanID.String(), tc.offset, tc.limit, tc.query)
+		assert.Nil(t, err, fmt.Sprintf("%s: expected no error got %s", desc, err))
+		assert.Equal(t, tc.buckets, result.Buckets, fmt.Sprintf("%s: expected %v got %v", desc, tc.buckets, result.Buckets))
+		assert.Equal(t, tc.total, result.Total, fmt.Sprintf("%s: expected %v got %v", desc, tc.total, result.Total))
+	}
+}
+
+func TestMessageReadStream(t *testing.T) {
+	writer := twriter.New(db)
+	reader := treader.New(db)
+
+	chanID, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	messages := []mainflux.Message{}
+	now := time.Now().Unix()
+	for i := 0; i < msgsNum; i++ {
+		msg := mainflux.Message{
+			Channel:   chanID.String(),
+			Publisher: "1",
+			Protocol:  "mqtt",
+			Value:     &mainflux.Message_FloatValue{FloatValue: float64(i)},
+			Time:      float64(now - int64(i)),
+		}
+		if i%valueFields == 0 {
+			msg.Subtopic = subtopic
+		}
+		err := writer.Save(msg)
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+		messages = append(messages, msg)
+	}
+
+	errStop := errors.New("stop")
+	cases := map[string]struct {
+		query    map[string]string
+		stop     int
+		messages []mainflux.Message
+		err      error
+	}{
+		"stream all messages": {
+			query:    map[string]string{},
+			messages: messages,
+		},
+		"stream messages with subtopic": {
+			query:    map[string]string{"subtopic": subtopic},
+			messages: filterMessages(messages, func(m mainflux.Message) bool { return m.Subtopic == subtopic }),
+		},
+		"stream messages of time range": {
+			query: map[string]string{
+				"from": fmt.Sprintf("%d", now-9),
+				"to":   fmt.Sprintf("%d", now+1),
+			},
+			messages: messages[:10],
+		},
+		"stream messages until callback fails": {
+			query:    map[string]string{},
+			stop:     5,
+			messages: messages[:5],
+			err:      errStop,
+		},
+	}
+
+	for desc, tc := range cases {
+		msgs := []mainflux.Message{}
+		err := reader.ReadStream(chanID.String(), tc.query, func(msg mainflux.Message) error {
+			if tc.stop > 0 && len(msgs) == tc.stop {
+				return errStop
+			}
+			msgs = append(msgs, msg)
+			return nil
+		})
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s", desc, tc.err, err))
+		assert.Equal(t, tc.messages, msgs, fmt.Sprintf("%s: expected %v got %v", desc, tc.messages, msgs))
+	}
+}
+
+func filterMessages(msgs []mainflux.Message, keep func(mainflux.Message) bool) []mainflux.Message {
+	ret := []mainflux.Message{}
+	for _, m := range msgs {
+		if keep(m) {
+			ret = append(ret, m)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,6 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package redis contains the Redis client factory shared by the services,
+// which supports the single instance, Sentinel and Cluster topologies.
+package redis
@@ -0,0 +1,231 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package redis
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io/ioutil"
+	"net/url"
+	"strings"
+
+	"github.com/go-redis/redis"
+)
+
+// Topology represents the Redis deployment topology.
+type Topology string
+
+const (
+	// Single is the topology of the single Redis instance.
+	Single Topology = "single"
+	// Sentinel is the topology of the Redis instances monitored by Sentinel.
+	Sentinel Topology = "sentinel"
+	// Cluster is the topology of the Redis Cluster.
+	Cluster Topology = "cluster"
+)
+
+const caCertsParam = "ca_certs"
+
+var (
+	// ErrMalformedURL indicates malformed Redis URL.
+	ErrMalformedURL = errors.New("malformed Redis URL")
+
+	// ErrMissingMaster indicates that the Sentinel URL lacks the master name.
+	ErrMissingMaster = errors.New("missing Sentinel master name")
+
+	// ErrClusterDB indicates that the database other than 0 is selected in
+	// the Redis Cluster, which supports only the database 0.
+	ErrClusterDB = errors.New("Redis Cluster supports only database 0")
+
+	// ErrInvalidCACerts indicates that the CA certificates can't be loaded.
+	ErrInvalidCACerts = errors.New("invalid CA certificates")
+)
+
+var schemes = map[string]Topology{
+	"redis":           Single,
+	"rediss":          Single,
+	"redis-sentinel":  Sentinel,
+	"rediss-sentinel": Sentinel,
+	"redis-cluster":   Cluster,
+	"rediss-cluster":  Cluster,
+}
+
+// Config represents the Redis client configuration.
+type Config struct {
+	Topology   Topology
+	Addrs      []string
+	User       string
+	Pass       string
+	MasterName string
+	DB         int
+	TLS        bool
+	CACerts    string
+}
+
+// ParseURL parses the Redis URL. Besides the plain host:port address of the
+// single instance, the URL of the form
+//
+//	scheme://[user[:pass]@]host:port[,host:port...][/master][?ca_certs=path]
+//
+// is accepted. The redis, redis-sentinel and redis-cluster schemes select
+// the topology, while their rediss counterparts turn on TLS. The Sentinel
+// URL lists the addresses of the sentinels and the name of the monitored
+// master. The user, if set, is authenticated as Redis ACL user.
+func ParseURL(rawURL string) (Config, error) {
+	i := strings.Index(rawURL, "://")
+	if i < 0 {
+		if rawURL == "" {
+			return Config{}, ErrMalformedURL
+		}
+		return Config{Topology: Single, Addrs: []string{rawURL}}, nil
+	}
+
+	scheme := rawURL[:i]
+	topology, ok := schemes[scheme]
+	if !ok {
+		return Config{}, ErrMalformedURL
+	}
+
+	cfg := Config{
+		Topology: topology,
+		TLS:      strings.HasPrefix(scheme, "rediss"),
+	}
+
+	rest := rawURL[i+len("://"):]
+	if i := strings.Index(rest, "?"); i >= 0 {
+		query, err := url.ParseQuery(rest[i+1:])
+		if err != nil {
+			return Config{}, ErrMalformedURL
+		}
+		cfg.CACerts = query.Get(caCertsParam)
+		rest = rest[:i]
+	}
+
+	if i := strings.Index(rest, "/"); i >= 0 {
+		cfg.MasterName = rest[i+1:]
+		rest = rest[:i]
+	}
+
+	if i := strings.LastIndex(rest, "@"); i >= 0 {
+		userinfo := rest[:i]
+		rest = rest[i+1:]
+
+		cfg.User = userinfo
+		if j := strings.Index(userinfo, ":"); j >= 0 {
+			cfg.User, cfg.Pass = userinfo[:j], userinfo[j+1:]
+		}
+	}
+
+	for _, addr := range strings.Split(rest, ",") {
+		if addr == "" {
+			return Config{}, ErrMalformedURL
+		}
+		cfg.Addrs = append(cfg.Addrs, addr)
+	}
+
+	if topology == Sentinel && cfg.MasterName == "" {
+		return Config{}, ErrMissingMaster
+	}
+
+	if topology != Sentinel && cfg.MasterName != "" {
+		return Config{}, ErrMalformedURL
+	}
+
+	if topology == Single && len(cfg.Addrs) > 1 {
+		return Config{}, ErrMalformedURL
+	}
+
+	return cfg, nil
+}
+
+// Connect creates the Redis client described by the URL. The password and
+// the database override the ones set in the URL, unless they are empty and
+// 0, respectively.
+func Connect(rawURL, pass string, db int) (redis.UniversalClient, error) {
+	cfg, err := ParseURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if pass != "" {
+		cfg.Pass = pass
+	}
+	cfg.DB = db
+
+	return NewClient(cfg)
+}
+
+// NewClient creates the Redis client of the configured topology.
+func NewClient(cfg Config) (redis.UniversalClient, error) {
+	var tlsCfg *tls.Config
+	if cfg.TLS {
+		c, err := loadTLSConfig(cfg.CACerts)
+		if err != nil {
+			return nil, err
+		}
+		tlsCfg = c
+	}
+
+	// The ACL user is authenticated explicitly, since the client sends the
+	// password-only AUTH command, which authenticates the default user.
+	pass := cfg.Pass
+	var onConnect func(*redis.Conn) error
+	if cfg.User != "" {
+		pass = ""
+		onConnect = func(cn *redis.Conn) error {
+			cmd := redis.NewStatusCmd("auth", cfg.User, cfg.Pass)
+			cn.Process(cmd)
+			return cmd.Err()
+		}
+	}
+
+	switch cfg.Topology {
+	case Sentinel:
+		return redis.NewFailoverClient(&redis.FailoverOptions{
+			MasterName:    cfg.MasterName,
+			SentinelAddrs: cfg.Addrs,
+			OnConnect:     onConnect,
+			Password:      pass,
+			DB:            cfg.DB,
+			TLSConfig:     tlsCfg,
+		}), nil
+	case Cluster:
+		if cfg.DB != 0 {
+			return nil, ErrClusterDB
+		}
+		return redis.NewClusterClient(&redis.ClusterOptions{
+			Addrs:     cfg.Addrs,
+			OnConnect: onConnect,
+			Password:  pass,
+			TLSConfig: tlsCfg,
+		}), nil
+	default:
+		return redis.NewClient(&redis.Options{
+			Addr:      cfg.Addrs[0],
+			OnConnect: onConnect,
+			Password:  pass,
+			DB:        cfg.DB,
+			TLSConfig: tlsCfg,
+		}), nil
+	}
+}
+
+func loadTLSConfig(caCerts string) (*tls.Config, error) {
+	if caCerts == "" {
+		return &tls.Config{}, nil
+	}
+
+	pem, err := ioutil.ReadFile(caCerts)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, ErrInvalidCACerts
+	}
+
+	return &tls.Config{RootCAs: pool}, nil
+}
@@ -0,0 +1,209 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/mainflux/mainflux/replay"
+)
+
+func startReplayEndpoint(svc replay.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(startReplayReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		rep := replay.Replay{
+			Channel:  req.Channel,
+			ThingKey: req.ThingKey,
+			From:     req.From,
+			To:       req.To,
+			Speed:    req.Speed,
+			Writer:   req.Writer,
+		}
+
+		saved, err := svc.StartReplay(ctx, req.token, rep)
+		if err != nil {
+			return nil, err
+		}
+
+		return replayRes{id: saved.ID}, nil
+	}
+}
+
+func viewReplayEndpoint(svc replay.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewReplayReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		rep, err := svc.ViewReplay(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		return toRes(rep), nil
+	}
+}
+
+func listReplaysEndpoint(svc replay.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listReplaysReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		reps, err := svc.ListReplays(ctx, req.token)
+		if err != nil {
+			return nil, err
+		}
+
+		res := replaysRes{Replays: []viewReplayRes{}}
+		for _, rep := range reps {
+			res.Replays = append(res.Replays, toRes(rep))
+		}
+
+		return res, nil
+	}
+}
+
+func stopReplayEndpoint(svc replay.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewReplayReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.StopReplay(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+
+		return stopRes{}, nil
+	}
+}
+
+func takeSnapshotEndpoint(svc replay.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(takeSnapshotReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		snap := replay.Snapshot{
+			Channel:  req.Channel,
+			ThingKey: req.ThingKey,
+			From:     req.From,
+			To:       req.To,
+		}
+
+		saved, err := svc.TakeSnapshot(ctx, req.token, snap)
+		if err != nil {
+			return nil, err
+		}
+
+		return snapshotRes{id: saved.ID}, nil
+	}
+}
+
+func viewSnapshotEndpoint(svc replay.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewReplayReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		snap, err := svc.ViewSnapshot(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		return toSnapshotRes(snap), nil
+	}
+}
+
+func listSnapshotsEndpoint(svc replay.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listReplaysReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		snaps, err := svc.ListSnapshots(ctx, req.token)
+		if err != nil {
+			return nil, err
+		}
+
+		res := snapshotsRes{Snapshots: []viewSnapshotRes{}}
+		for _, snap := range snaps {
+			res.Snapshots = append(res.Snapshots, toSnapshotRes(snap))
+		}
+
+		return res, nil
+	}
+}
+
+func restoreSnapshotEndpoint(svc replay.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(restoreSnapshotReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		r := replay.Restore{
+			Channel:  req.Channel,
+			ThingKey: req.ThingKey,
+			Writer:   req.Writer,
+		}
+
+		restored, err := svc.RestoreSnapshot(ctx, req.token, req.id, r)
+		if err != nil {
+			return nil, err
+		}
+
+		return restoreRes{Restored: restored}, nil
+	}
+}
+
+func toRes(rep replay.Replay) viewReplayRes {
+	return viewReplayRes{
+		ID:      rep.ID,
+		Channel: rep.Channel,
+		From:    rep.From,
+		To:      rep.To,
+		Speed:   rep.Speed,
+		Writer:  rep.Writer,
+		Started: rep.Started,
+		State:   rep.State,
+		Error:   rep.Error,
+		Stats: statsRes{
+			Read:      rep.Stats.Read,
+			Published: rep.Stats.Published,
+		},
+	}
+}
+
+func toSnapshotRes(snap replay.Snapshot) viewSnapshotRes {
+	return viewSnapshotRes{
+		ID:       snap.ID,
+		Channel:  snap.Channel,
+		From:     snap.From,
+		To:       snap.To,
+		Created:  snap.Created,
+		State:    snap.State,
+		Error:    snap.Error,
+		Messages: snap.Messages,
+	}
+}
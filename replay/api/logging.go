@@ -0,0 +1,131 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// +build !test
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/replay"
+)
+
+var _ replay.Service = (*loggingMiddleware)(nil)
+
+type loggingMiddleware struct {
+	logger log.Logger
+	svc    replay.Service
+}
+
+// LoggingMiddleware adds logging facilities to the core service.
+func LoggingMiddleware(svc replay.Service, logger log.Logger) replay.Service {
+	return &loggingMiddleware{logger, svc}
+}
+
+func (lm *loggingMiddleware) StartReplay(ctx context.Context, token string, rep replay.Replay) (saved replay.Replay, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method start_replay for token %s and replay %s took %s to complete", token, saved.ID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.StartReplay(ctx, token, rep)
+}
+
+func (lm *loggingMiddleware) ViewReplay(ctx context.Context, token, id string) (rep replay.Replay, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method view_replay for token %s and replay %s took %s to complete", token, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ViewReplay(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) ListReplays(ctx context.Context, token string) (reps []replay.Replay, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_replays for token %s took %s to complete", token, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListReplays(ctx, token)
+}
+
+func (lm *loggingMiddleware) StopReplay(ctx context.Context, token, id string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method stop_replay for token %s and replay %s took %s to complete", token, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.StopReplay(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) TakeSnapshot(ctx context.Context, token string, snap replay.Snapshot) (saved replay.Snapshot, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method take_snapshot for token %s and snapshot %s took %s to complete", token, saved.ID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.TakeSnapshot(ctx, token, snap)
+}
+
+func (lm *loggingMiddleware) ViewSnapshot(ctx context.Context, token, id string) (snap replay.Snapshot, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method view_snapshot for token %s and snapshot %s took %s to complete", token, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ViewSnapshot(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) ListSnapshots(ctx context.Context, token string) (snaps []replay.Snapshot, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_snapshots for token %s took %s to complete", token, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListSnapshots(ctx, token)
+}
+
+func (lm *loggingMiddleware) RestoreSnapshot(ctx context.Context, token, id string, r replay.Restore) (restored uint64, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method restore_snapshot for token %s, snapshot %s and channel %s took %s to complete", token, id, r.Channel, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RestoreSnapshot(ctx, token, id, r)
+}
@@ -0,0 +1,104 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// +build !test
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/mainflux/mainflux/replay"
+)
+
+var _ replay.Service = (*metricsMiddleware)(nil)
+
+type metricsMiddleware struct {
+	counter metrics.Counter
+	latency metrics.Histogram
+	svc     replay.Service
+}
+
+// MetricsMiddleware instruments core service by tracking request count and
+// latency.
+func MetricsMiddleware(svc replay.Service, counter metrics.Counter, latency metrics.Histogram) replay.Service {
+	return &metricsMiddleware{
+		counter: counter,
+		latency: latency,
+		svc:     svc,
+	}
+}
+
+func (ms *metricsMiddleware) StartReplay(ctx context.Context, token string, rep replay.Replay) (replay.Replay, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "start_replay").Add(1)
+		ms.latency.With("method", "start_replay").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.StartReplay(ctx, token, rep)
+}
+
+func (ms *metricsMiddleware) ViewReplay(ctx context.Context, token, id string) (replay.Replay, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_replay").Add(1)
+		ms.latency.With("method", "view_replay").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewReplay(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) ListReplays(ctx context.Context, token string) ([]replay.Replay, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_replays").Add(1)
+		ms.latency.With("method", "list_replays").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListReplays(ctx, token)
+}
+
+func (ms *metricsMiddleware) StopReplay(ctx context.Context, token, id string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "stop_replay").Add(1)
+		ms.latency.With("method", "stop_replay").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.StopReplay(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) TakeSnapshot(ctx context.Context, token string, snap replay.Snapshot) (replay.Snapshot, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "take_snapshot").Add(1)
+		ms.latency.With("method", "take_snapshot").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.TakeSnapshot(ctx, token, snap)
+}
+
+func (ms *metricsMiddleware) ViewSnapshot(ctx context.Context, token, id string) (replay.Snapshot, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_snapshot").Add(1)
+		ms.latency.With("method", "view_snapshot").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewSnapshot(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) ListSnapshots(ctx context.Context, token string) ([]replay.Snapshot, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_snapshots").Add(1)
+		ms.latency.With("method", "list_snapshots").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListSnapshots(ctx, token)
+}
+
+func (ms *metricsMiddleware) RestoreSnapshot(ctx context.Context, token, id string, r replay.Restore) (uint64, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "restore_snapshot").Add(1)
+		ms.latency.With("method", "restore_snapshot").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RestoreSnapshot(ctx, token, id, r)
+}
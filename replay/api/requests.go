@@ -0,0 +1,101 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import "github.com/mainflux/mainflux/replay"
+
+const defSpeed = 1
+
+type apiReq interface {
+	validate() error
+}
+
+type startReplayReq struct {
+	token    string
+	Channel  string  `json:"channel"`
+	ThingKey string  `json:"thing_key"`
+	From     float64 `json:"from"`
+	To       float64 `json:"to"`
+	Speed    float64 `json:"speed,omitempty"`
+	Writer   string  `json:"writer,omitempty"`
+}
+
+// validate sets the default speed as well, which is why it has pointer
+// receiver.
+func (req *startReplayReq) validate() error {
+	if req.token == "" {
+		return replay.ErrUnauthorizedAccess
+	}
+
+	if req.Speed == 0 {
+		req.Speed = defSpeed
+	}
+
+	return nil
+}
+
+type viewReplayReq struct {
+	token string
+	id    string
+}
+
+func (req viewReplayReq) validate() error {
+	if req.token == "" {
+		return replay.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" {
+		return replay.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type takeSnapshotReq struct {
+	token    string
+	Channel  string  `json:"channel"`
+	ThingKey string  `json:"thing_key"`
+	From     float64 `json:"from"`
+	To       float64 `json:"to"`
+}
+
+func (req takeSnapshotReq) validate() error {
+	if req.token == "" {
+		return replay.ErrUnauthorizedAccess
+	}
+
+	return nil
+}
+
+type restoreSnapshotReq struct {
+	token    string
+	id       string
+	Channel  string `json:"channel"`
+	ThingKey string `json:"thing_key"`
+	Writer   string `json:"writer,omitempty"`
+}
+
+func (req restoreSnapshotReq) validate() error {
+	if req.token == "" {
+		return replay.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" {
+		return replay.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type listReplaysReq struct {
+	token string
+}
+
+func (req listReplaysReq) validate() error {
+	if req.token == "" {
+		return replay.ErrUnauthorizedAccess
+	}
+
+	return nil
+}
@@ -0,0 +1,174 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/mainflux/mainflux"
+)
+
+var (
+	_ mainflux.Response = (*replayRes)(nil)
+	_ mainflux.Response = (*viewReplayRes)(nil)
+	_ mainflux.Response = (*replaysRes)(nil)
+	_ mainflux.Response = (*stopRes)(nil)
+	_ mainflux.Response = (*snapshotRes)(nil)
+	_ mainflux.Response = (*viewSnapshotRes)(nil)
+	_ mainflux.Response = (*snapshotsRes)(nil)
+	_ mainflux.Response = (*restoreRes)(nil)
+)
+
+type replayRes struct {
+	id string
+}
+
+func (res replayRes) Code() int {
+	return http.StatusCreated
+}
+
+func (res replayRes) Headers() map[string]string {
+	return map[string]string{
+		"Location": fmt.Sprintf("/replay/%s", res.id),
+	}
+}
+
+func (res replayRes) Empty() bool {
+	return true
+}
+
+type statsRes struct {
+	Read      uint64 `json:"read"`
+	Published uint64 `json:"published"`
+}
+
+type viewReplayRes struct {
+	ID      string    `json:"id"`
+	Channel string    `json:"channel"`
+	From    float64   `json:"from"`
+	To      float64   `json:"to"`
+	Speed   float64   `json:"speed"`
+	Writer  string    `json:"writer,omitempty"`
+	Started time.Time `json:"started"`
+	State   string    `json:"state"`
+	Error   string    `json:"error,omitempty"`
+	Stats   statsRes  `json:"stats"`
+}
+
+func (res viewReplayRes) Code() int {
+	return http.StatusOK
+}
+
+func (res viewReplayRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res viewReplayRes) Empty() bool {
+	return false
+}
+
+type replaysRes struct {
+	Replays []viewReplayRes `json:"replays"`
+}
+
+func (res replaysRes) Code() int {
+	return http.StatusOK
+}
+
+func (res replaysRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res replaysRes) Empty() bool {
+	return false
+}
+
+type stopRes struct{}
+
+func (res stopRes) Code() int {
+	return http.StatusNoContent
+}
+
+func (res stopRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res stopRes) Empty() bool {
+	return true
+}
+
+type snapshotRes struct {
+	id string
+}
+
+func (res snapshotRes) Code() int {
+	return http.StatusCreated
+}
+
+func (res snapshotRes) Headers() map[string]string {
+	return map[string]string{
+		"Location": fmt.Sprintf("/snapshots/%s", res.id),
+	}
+}
+
+func (res snapshotRes) Empty() bool {
+	return true
+}
+
+type viewSnapshotRes struct {
+	ID       string    `json:"id"`
+	Channel  string    `json:"channel"`
+	From     float64   `json:"from"`
+	To       float64   `json:"to"`
+	Created  time.Time `json:"created"`
+	State    string    `json:"state"`
+	Error    string    `json:"error,omitempty"`
+	Messages uint64    `json:"messages"`
+}
+
+func (res viewSnapshotRes) Code() int {
+	return http.StatusOK
+}
+
+func (res viewSnapshotRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res viewSnapshotRes) Empty() bool {
+	return false
+}
+
+type snapshotsRes struct {
+	Snapshots []viewSnapshotRes `json:"snapshots"`
+}
+
+func (res snapshotsRes) Code() int {
+	return http.StatusOK
+}
+
+func (res snapshotsRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res snapshotsRes) Empty() bool {
+	return false
+}
+
+type restoreRes struct {
+	Restored uint64 `json:"restored"`
+}
+
+func (res restoreRes) Code() int {
+	return http.StatusOK
+}
+
+func (res restoreRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res restoreRes) Empty() bool {
+	return false
+}
@@ -0,0 +1,128 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package archive contains the archive store implementation which keeps the
+// snapshots in the S3-compatible object storage, such as AWS S3 or MinIO.
+package archive
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/replay"
+	"github.com/mainflux/mainflux/writers/s3"
+)
+
+const (
+	jsonType        = "application/json"
+	messagesType    = "application/x-ndjson"
+	messagesEncoded = "gzip"
+)
+
+var _ replay.ArchiveStore = (*s3Archive)(nil)
+
+type s3Archive struct {
+	client s3.Client
+	prefix string
+}
+
+// snapshot is the JSON representation of the archived snapshot.
+type snapshot struct {
+	ID       string    `json:"id"`
+	Owner    string    `json:"owner"`
+	Channel  string    `json:"channel"`
+	From     float64   `json:"from"`
+	To       float64   `json:"to"`
+	Created  time.Time `json:"created"`
+	Messages uint64    `json:"messages"`
+}
+
+// NewS3 returns archive store which keeps every snapshot as two objects, the
+// <prefix>/<owner>/<id>.json description and the <prefix>/<owner>/<id>.ndjson.gz
+// messages, encoded in the same format as the messages archived by the S3
+// writer.
+func NewS3(client s3.Client, prefix string) replay.ArchiveStore {
+	return s3Archive{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+func (a s3Archive) Save(_ context.Context, snap replay.Snapshot, msgs []mainflux.Message) error {
+	data, err := s3.Encode(msgs)
+	if err != nil {
+		return err
+	}
+
+	// Description is stored last, so that the snapshot isn't found until
+	// its messages are stored.
+	if err := a.client.Put(a.key(snap.Owner, snap.ID, ".ndjson.gz"), messagesType, messagesEncoded, data); err != nil {
+		return err
+	}
+
+	desc, err := json.Marshal(snapshot{
+		ID:       snap.ID,
+		Owner:    snap.Owner,
+		Channel:  snap.Channel,
+		From:     snap.From,
+		To:       snap.To,
+		Created:  snap.Created,
+		Messages: uint64(len(msgs)),
+	})
+	if err != nil {
+		return err
+	}
+
+	return a.client.Put(a.key(snap.Owner, snap.ID, ".json"), jsonType, "", desc)
+}
+
+func (a s3Archive) Retrieve(_ context.Context, owner, id string) (replay.Snapshot, []mainflux.Message, error) {
+	// ID mustn't address the snapshots of the other owners.
+	if strings.Contains(id, "/") {
+		return replay.Snapshot{}, nil, replay.ErrNotFound
+	}
+
+	desc, err := a.client.Get(a.key(owner, id, ".json"))
+	if err == s3.ErrObjectNotFound {
+		return replay.Snapshot{}, nil, replay.ErrNotFound
+	}
+	if err != nil {
+		return replay.Snapshot{}, nil, err
+	}
+
+	var s snapshot
+	if err := json.Unmarshal(desc, &s); err != nil {
+		return replay.Snapshot{}, nil, err
+	}
+
+	data, err := a.client.Get(a.key(owner, id, ".ndjson.gz"))
+	if err != nil {
+		return replay.Snapshot{}, nil, err
+	}
+
+	msgs, err := s3.Decode(data)
+	if err != nil {
+		return replay.Snapshot{}, nil, err
+	}
+
+	snap := replay.Snapshot{
+		ID:       s.ID,
+		Owner:    s.Owner,
+		Channel:  s.Channel,
+		From:     s.From,
+		To:       s.To,
+		Created:  s.Created,
+		State:    replay.Completed,
+		Messages: s.Messages,
+	}
+
+	return snap, msgs, nil
+}
+
+func (a s3Archive) key(owner, id, ext string) string {
+	return path.Join(a.prefix, owner, id+ext)
+}
@@ -0,0 +1,144 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package archive_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/replay"
+	"github.com/mainflux/mainflux/replay/archive"
+	"github.com/mainflux/mainflux/writers/s3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	owner  = "user@example.com"
+	prefix = "snapshots"
+)
+
+type storage struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (s *storage) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodPut:
+		data, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		s.objects[r.URL.Path] = data
+	case http.MethodGet:
+		data, ok := s.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(data)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestSaveRetrieve(t *testing.T) {
+	st := &storage{objects: map[string][]byte{}}
+	ts := httptest.NewServer(st)
+	defer ts.Close()
+
+	cfg := s3.Config{
+		Endpoint: ts.URL,
+		Region:   "us-east-1",
+		Bucket:   "mainflux",
+	}
+	arch := archive.NewS3(s3.NewClient(cfg, http.DefaultClient), prefix)
+
+	snap := replay.Snapshot{
+		ID:       "1",
+		Owner:    owner,
+		Channel:  "45",
+		From:     1000,
+		To:       1100,
+		Created:  time.Unix(1100, 0).UTC(),
+		State:    replay.Completed,
+		Messages: 10,
+	}
+
+	var msgs []mainflux.Message
+	for i := 0; i < 10; i++ {
+		msgs = append(msgs, mainflux.Message{
+			Channel:   "45",
+			Publisher: "2580",
+			Protocol:  "http",
+			Name:      fmt.Sprintf("name-%d", i),
+			Value:     &mainflux.Message_FloatValue{FloatValue: float64(i)},
+			Time:      float64(1000 + i),
+		})
+	}
+
+	err := arch.Save(context.Background(), snap, msgs)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		owner string
+		id    string
+		snap  replay.Snapshot
+		msgs  []mainflux.Message
+		err   error
+	}{
+		{
+			desc:  "retrieve archived snapshot",
+			owner: owner,
+			id:    snap.ID,
+			snap:  snap,
+			msgs:  msgs,
+			err:   nil,
+		},
+		{
+			desc:  "retrieve snapshot of another owner",
+			owner: "other@example.com",
+			id:    snap.ID,
+			snap:  replay.Snapshot{},
+			msgs:  nil,
+			err:   replay.ErrNotFound,
+		},
+		{
+			desc:  "retrieve snapshot by the path of another owner",
+			owner: "other@example.com",
+			id:    "../" + owner + "/" + snap.ID,
+			snap:  replay.Snapshot{},
+			msgs:  nil,
+			err:   replay.ErrNotFound,
+		},
+		{
+			desc:  "retrieve non-existing snapshot",
+			owner: owner,
+			id:    "2",
+			snap:  replay.Snapshot{},
+			msgs:  nil,
+			err:   replay.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		snap, msgs, err := arch.Retrieve(context.Background(), tc.owner, tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.snap, snap, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.snap, snap))
+		assert.Equal(t, tc.msgs, msgs, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.msgs, msgs))
+	}
+}
@@ -0,0 +1,12 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package replay contains the domain concept definitions needed to support
+// Mainflux replay service functionality. Replay service reads the historical
+// messages of a channel from the reader and republishes them to NATS at the
+// original or accelerated pace, and is meant to be used for testing of the
+// message consumers and reprocessing of the messages they failed to handle.
+// Snapshots of the channel messages are archived in the object storage, and
+// can be restored to the same or another channel, which is used for cloning
+// of the environments and for the incident forensics.
+package replay
@@ -0,0 +1,53 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/replay"
+)
+
+var _ replay.ArchiveStore = (*archiveMock)(nil)
+
+type archived struct {
+	snapshot replay.Snapshot
+	messages []mainflux.Message
+}
+
+type archiveMock struct {
+	mu        sync.Mutex
+	snapshots map[string]archived
+}
+
+// NewArchiveStore returns in-memory archive store mock.
+func NewArchiveStore() replay.ArchiveStore {
+	return &archiveMock{
+		snapshots: make(map[string]archived),
+	}
+}
+
+func (am *archiveMock) Save(_ context.Context, snap replay.Snapshot, msgs []mainflux.Message) error {
+	am.mu.Lock()
+	defer am.mu.Unlock()
+
+	am.snapshots[snap.Owner+"/"+snap.ID] = archived{
+		snapshot: snap,
+		messages: append([]mainflux.Message{}, msgs...),
+	}
+	return nil
+}
+
+func (am *archiveMock) Retrieve(_ context.Context, owner, id string) (replay.Snapshot, []mainflux.Message, error) {
+	am.mu.Lock()
+	defer am.mu.Unlock()
+
+	a, ok := am.snapshots[owner+"/"+id]
+	if !ok {
+		return replay.Snapshot{}, nil, replay.ErrNotFound
+	}
+	return a.snapshot, append([]mainflux.Message{}, a.messages...), nil
+}
@@ -0,0 +1,63 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"sync"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/replay"
+)
+
+// Publisher is an in-memory publisher which records published messages
+// per replay, and restored messages per channel.
+type Publisher struct {
+	mu        sync.Mutex
+	published map[string][]mainflux.Message
+	restored  map[string][]mainflux.Message
+}
+
+var _ replay.Publisher = (*Publisher)(nil)
+
+// NewPublisher returns publisher mock.
+func NewPublisher() *Publisher {
+	return &Publisher{
+		published: make(map[string][]mainflux.Message),
+		restored:  make(map[string][]mainflux.Message),
+	}
+}
+
+// Publish records the published message.
+func (p *Publisher) Publish(r replay.Replay, msg mainflux.Message) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.published[r.ID] = append(p.published[r.ID], msg)
+	return nil
+}
+
+// Published returns all the messages published by the replay.
+func (p *Publisher) Published(id string) []mainflux.Message {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]mainflux.Message{}, p.published[id]...)
+}
+
+// Restore records the restored message.
+func (p *Publisher) Restore(_ string, msg mainflux.Message) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.restored[msg.Channel] = append(p.restored[msg.Channel], msg)
+	return nil
+}
+
+// Restored returns all the messages restored to the channel.
+func (p *Publisher) Restored(channel string) []mainflux.Message {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]mainflux.Message{}, p.restored[channel]...)
+}
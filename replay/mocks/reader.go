@@ -0,0 +1,47 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sort"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/replay"
+)
+
+var _ replay.MessageReader = (*readerMock)(nil)
+
+type readerMock struct {
+	keys     map[string]string
+	messages map[string][]mainflux.Message
+}
+
+// NewReader returns reader mock serving the given messages per channel.
+// Keys map the thing keys to the channels they are allowed to read.
+func NewReader(keys map[string]string, messages map[string][]mainflux.Message) replay.MessageReader {
+	return readerMock{
+		keys:     keys,
+		messages: messages,
+	}
+}
+
+func (rm readerMock) ReadMessages(_ context.Context, r replay.Replay, from, to float64) ([]mainflux.Message, error) {
+	if rm.keys[r.ThingKey] != r.Channel {
+		return nil, replay.ErrUnauthorizedAccess
+	}
+
+	msgs := []mainflux.Message{}
+	for _, msg := range rm.messages[r.Channel] {
+		if msg.Time >= from && msg.Time < to {
+			msgs = append(msgs, msg)
+		}
+	}
+
+	sort.Slice(msgs, func(i, j int) bool {
+		return msgs[i].Time < msgs[j].Time
+	})
+
+	return msgs, nil
+}
@@ -0,0 +1,68 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package nats contains the publisher implementation which republishes the
+// replayed messages to NATS.
+package nats
+
+import (
+	"fmt"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/mainflux/mainflux"
+	mfnats "github.com/mainflux/mainflux/nats"
+	"github.com/mainflux/mainflux/replay"
+	"github.com/mainflux/mainflux/writers"
+	"github.com/nats-io/nats.go"
+)
+
+const replaySubject = "replay"
+
+var _ replay.Publisher = (*publisher)(nil)
+
+type publisher struct {
+	nc            *nats.Conn
+	subjectPrefix string
+}
+
+// NewPublisher returns publisher which republishes the replayed messages to
+// the "replay.<channel_id>" subject, or to the subject the writer consumes the
+// replayed dead letters from, if the replay targets the writer. Restored
+// messages are published to the subject of the normalized messages, so that
+// all the writers save them, or to the subject of the writer, if it is set.
+// The subjects are prefixed with the deployment subject prefix, unless it is
+// empty.
+func NewPublisher(nc *nats.Conn, subjectPrefix string) replay.Publisher {
+	return publisher{
+		nc:            nc,
+		subjectPrefix: subjectPrefix,
+	}
+}
+
+func (p publisher) Publish(r replay.Replay, msg mainflux.Message) error {
+	data, err := proto.Marshal(&msg)
+	if err != nil {
+		return err
+	}
+
+	subject := fmt.Sprintf("%s.%s", replaySubject, msg.Channel)
+	if r.Writer != "" {
+		subject = writers.ReplaySubject(r.Writer)
+	}
+
+	return p.nc.Publish(mfnats.Subject(p.subjectPrefix, subject), data)
+}
+
+func (p publisher) Restore(writer string, msg mainflux.Message) error {
+	data, err := proto.Marshal(&msg)
+	if err != nil {
+		return err
+	}
+
+	subject := mainflux.OutputSenML
+	if writer != "" {
+		subject = writers.ReplaySubject(writer)
+	}
+
+	return p.nc.Publish(mfnats.Subject(p.subjectPrefix, subject), data)
+}
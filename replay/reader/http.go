@@ -0,0 +1,197 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package reader contains the message reader implementation which reads the
+// historical messages from the Mainflux readers HTTP API.
+package reader
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/replay"
+)
+
+const csvType = "text/csv"
+
+var (
+	// ErrFailedRead indicates that the reader responded with the unexpected
+	// status code.
+	ErrFailedRead = errors.New("failed to read messages")
+
+	// ErrMalformedExport indicates that the reader responded with the export
+	// that can't be parsed.
+	ErrMalformedExport = errors.New("malformed messages export")
+)
+
+var _ replay.MessageReader = (*httpReader)(nil)
+
+type httpReader struct {
+	url    string
+	client *http.Client
+}
+
+// NewHTTP returns message reader which exports the messages from the reader
+// located at the given URL, using the thing key of the replay.
+func NewHTTP(url string, client *http.Client) replay.MessageReader {
+	return httpReader{
+		url:    strings.TrimSuffix(url, "/"),
+		client: client,
+	}
+}
+
+func (hr httpReader) ReadMessages(ctx context.Context, r replay.Replay, from, to float64) ([]mainflux.Message, error) {
+	query := url.Values{}
+	query.Set("from", formatFloat(from))
+	query.Set("to", formatFloat(to))
+	u := fmt.Sprintf("%s/channels/%s/messages?%s", hr.url, url.PathEscape(r.Channel), query.Encode())
+
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", r.ThingKey)
+	req.Header.Set("Accept", csvType)
+
+	res, err := hr.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusOK:
+	case http.StatusForbidden:
+		return nil, replay.ErrUnauthorizedAccess
+	default:
+		return nil, ErrFailedRead
+	}
+
+	msgs, err := parseCSV(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	// Reader exports the newest messages first.
+	for i, j := 0, len(msgs)-1; i < j; i, j = i+1, j-1 {
+		msgs[i], msgs[j] = msgs[j], msgs[i]
+	}
+
+	return msgs, nil
+}
+
+// parseCSV parses the messages export. Columns are looked up by the names
+// in the header row, so that their order doesn't matter.
+func parseCSV(r io.Reader) ([]mainflux.Message, error) {
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if err == io.EOF {
+		return []mainflux.Message{}, nil
+	}
+	if err != nil {
+		return nil, ErrMalformedExport
+	}
+
+	cols := make(map[string]int)
+	for i, name := range header {
+		cols[name] = i
+	}
+
+	msgs := []mainflux.Message{}
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			return msgs, nil
+		}
+		if err != nil {
+			return nil, ErrMalformedExport
+		}
+
+		msg, err := parseRecord(cols, rec)
+		if err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, msg)
+	}
+}
+
+func parseRecord(cols map[string]int, rec []string) (mainflux.Message, error) {
+	field := func(name string) string {
+		if i, ok := cols[name]; ok && i < len(rec) {
+			return rec[i]
+		}
+		return ""
+	}
+
+	msg := mainflux.Message{
+		Channel:   field("channel"),
+		Subtopic:  field("subtopic"),
+		Publisher: field("publisher"),
+		Protocol:  field("protocol"),
+		Name:      field("name"),
+		Unit:      field("unit"),
+		Link:      field("link"),
+	}
+
+	var err error
+	if msg.Time, err = parseFloat(field("time")); err != nil {
+		return mainflux.Message{}, err
+	}
+	if msg.UpdateTime, err = parseFloat(field("update_time")); err != nil {
+		return mainflux.Message{}, err
+	}
+
+	switch {
+	case field("value") != "":
+		v, err := parseFloat(field("value"))
+		if err != nil {
+			return mainflux.Message{}, err
+		}
+		msg.Value = &mainflux.Message_FloatValue{FloatValue: v}
+	case field("string_value") != "":
+		msg.Value = &mainflux.Message_StringValue{StringValue: field("string_value")}
+	case field("bool_value") != "":
+		v, err := strconv.ParseBool(field("bool_value"))
+		if err != nil {
+			return mainflux.Message{}, ErrMalformedExport
+		}
+		msg.Value = &mainflux.Message_BoolValue{BoolValue: v}
+	case field("data_value") != "":
+		msg.Value = &mainflux.Message_DataValue{DataValue: field("data_value")}
+	}
+
+	if sum := field("value_sum"); sum != "" {
+		v, err := parseFloat(sum)
+		if err != nil {
+			return mainflux.Message{}, err
+		}
+		msg.ValueSum = &mainflux.SumValue{Value: v}
+	}
+
+	return msg, nil
+}
+
+func parseFloat(s string) (float64, error) {
+	if s == "" {
+		return 0, nil
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, ErrMalformedExport
+	}
+
+	return f, nil
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,140 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package reader_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/replay"
+	"github.com/mainflux/mainflux/replay/reader"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	chanID   = "1"
+	thingKey = "key"
+	export   = `channel,subtopic,publisher,protocol,name,unit,value,string_value,bool_value,data_value,value_sum,time,update_time,link
+1,,2,http,switch,,,,true,,,1003,,
+1,,2,http,status,,,on,,,,1002,,
+1,room,2,http,temperature,C,24.5,,,,24.5,1001.5,,
+`
+)
+
+func readerHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != thingKey {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	if r.URL.Path != fmt.Sprintf("/channels/%s/messages", chanID) || r.Header.Get("Accept") != "text/csv" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	q := r.URL.Query()
+	switch {
+	case q.Get("from") == "1000" && q.Get("to") == "1010":
+		w.Header().Set("Content-Type", "text/csv")
+		fmt.Fprint(w, export)
+	case q.Get("from") == "1010" && q.Get("to") == "1020":
+		w.Header().Set("Content-Type", "text/csv")
+		fmt.Fprint(w, "channel,time\n1,not-a-number\n")
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+func TestReadMessages(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(readerHandler))
+	defer ts.Close()
+
+	r := reader.NewHTTP(ts.URL, http.DefaultClient)
+	rep := replay.Replay{Channel: chanID, ThingKey: thingKey}
+
+	unauthorized := rep
+	unauthorized.ThingKey = "wrong"
+
+	msgs := []mainflux.Message{
+		{
+			Channel:   chanID,
+			Subtopic:  "room",
+			Publisher: "2",
+			Protocol:  "http",
+			Name:      "temperature",
+			Unit:      "C",
+			Value:     &mainflux.Message_FloatValue{FloatValue: 24.5},
+			ValueSum:  &mainflux.SumValue{Value: 24.5},
+			Time:      1001.5,
+		},
+		{
+			Channel:   chanID,
+			Publisher: "2",
+			Protocol:  "http",
+			Name:      "status",
+			Value:     &mainflux.Message_StringValue{StringValue: "on"},
+			Time:      1002,
+		},
+		{
+			Channel:   chanID,
+			Publisher: "2",
+			Protocol:  "http",
+			Name:      "switch",
+			Value:     &mainflux.Message_BoolValue{BoolValue: true},
+			Time:      1003,
+		},
+	}
+
+	cases := []struct {
+		desc string
+		rep  replay.Replay
+		from float64
+		to   float64
+		msgs []mainflux.Message
+		err  error
+	}{
+		{
+			desc: "read messages in publishing order",
+			rep:  rep,
+			from: 1000,
+			to:   1010,
+			msgs: msgs,
+			err:  nil,
+		},
+		{
+			desc: "read messages with wrong thing key",
+			rep:  unauthorized,
+			from: 1000,
+			to:   1010,
+			msgs: nil,
+			err:  replay.ErrUnauthorizedAccess,
+		},
+		{
+			desc: "read malformed export",
+			rep:  rep,
+			from: 1010,
+			to:   1020,
+			msgs: nil,
+			err:  reader.ErrMalformedExport,
+		},
+		{
+			desc: "read messages with failing reader",
+			rep:  rep,
+			from: 1020,
+			to:   1030,
+			msgs: nil,
+			err:  reader.ErrFailedRead,
+		},
+	}
+
+	for _, tc := range cases {
+		msgs, err := r.ReadMessages(context.Background(), tc.rep, tc.from, tc.to)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.msgs, msgs, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.msgs, msgs))
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package replay
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/mainflux/mainflux"
+)
+
+const (
+	// Running replay is reading and publishing the messages.
+	Running = "running"
+	// Completed replay has published all the messages within the range.
+	Completed = "completed"
+	// Failed replay has been interrupted by the reading or publishing error.
+	Failed = "failed"
+)
+
+// Replay represents republishing of the messages that were published to the
+// channel within the time range. Messages are published in the original order,
+// with the original intervals between them divided by the speed factor. If
+// the writer is set, messages are replayed to that writer only, so that the
+// messages it failed to save can be saved again.
+// Time range bounds are Unix timestamps in seconds, where the start is
+// inclusive and the end is exclusive.
+type Replay struct {
+	ID       string
+	Owner    string
+	Channel  string
+	ThingKey string
+	From     float64
+	To       float64
+	Speed    float64
+	Writer   string
+	Started  time.Time
+	State    string
+	Error    string
+	Stats    Stats
+}
+
+// Stats contains replay publishing statistics.
+type Stats struct {
+	Read      uint64
+	Published uint64
+}
+
+// Validate returns an error if replay is not well-formed.
+func (r Replay) Validate() error {
+	if r.Channel == "" || r.ThingKey == "" {
+		return ErrMalformedEntity
+	}
+
+	if r.From < 0 || r.To <= r.From || r.Speed <= 0 {
+		return ErrMalformedEntity
+	}
+
+	// Writer name is used as the NATS subject token.
+	if strings.ContainsAny(r.Writer, ".*> \t") {
+		return ErrMalformedEntity
+	}
+
+	return nil
+}
+
+// MessageReader specifies an API for reading the historical messages.
+type MessageReader interface {
+	// ReadMessages returns the messages of the replayed channel published
+	// at or after the start and before the end of the given time range,
+	// ordered by the publishing time.
+	ReadMessages(context.Context, Replay, float64, float64) ([]mainflux.Message, error)
+}
+
+// Publisher specifies an API for republishing the replayed messages.
+type Publisher interface {
+	// Publish republishes the message read by the replay.
+	Publish(Replay, mainflux.Message) error
+
+	// Restore publishes the restored snapshot message to the writers, or
+	// to the given writer only, if it is set.
+	Restore(string, mainflux.Message) error
+}
+
+// IdentityProvider specifies an API for generating unique identifiers.
+type IdentityProvider interface {
+	// ID generates the unique identifier.
+	ID() (string, error)
+}
@@ -0,0 +1,486 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package replay
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/things"
+)
+
+var (
+	// ErrMalformedEntity indicates malformed entity specification.
+	ErrMalformedEntity = errors.New("malformed entity specification")
+
+	// ErrUnauthorizedAccess indicates missing or invalid credentials provided
+	// when accessing a protected resource.
+	ErrUnauthorizedAccess = errors.New("missing or invalid credentials provided")
+
+	// ErrNotFound indicates a non-existent entity request.
+	ErrNotFound = errors.New("non-existent entity")
+)
+
+// Service specifies an API that must be fulfilled by the domain service
+// implementation, and all of its decorators (e.g. logging & metrics).
+type Service interface {
+	// StartReplay starts new replay owned by the user identified by the
+	// provided key.
+	StartReplay(context.Context, string, Replay) (Replay, error)
+
+	// ViewReplay retrieves the replay identified by the provided ID, that
+	// belongs to the user identified by the provided key.
+	ViewReplay(context.Context, string, string) (Replay, error)
+
+	// ListReplays retrieves all the replays that belong to the user
+	// identified by the provided key.
+	ListReplays(context.Context, string) ([]Replay, error)
+
+	// StopReplay stops the replay identified by the provided ID, that
+	// belongs to the user identified by the provided key, and removes it
+	// from the list of replays.
+	StopReplay(context.Context, string, string) error
+
+	// TakeSnapshot starts taking new snapshot owned by the user identified
+	// by the provided key.
+	TakeSnapshot(context.Context, string, Snapshot) (Snapshot, error)
+
+	// ViewSnapshot retrieves the snapshot identified by the provided ID,
+	// that belongs to the user identified by the provided key.
+	ViewSnapshot(context.Context, string, string) (Snapshot, error)
+
+	// ListSnapshots retrieves the snapshots taken by the user identified by
+	// the provided key since the service started.
+	ListSnapshots(context.Context, string) ([]Snapshot, error)
+
+	// RestoreSnapshot publishes the messages of the completed snapshot
+	// identified by the provided ID, that belongs to the user identified by
+	// the provided key, as the messages of the target channel, and returns
+	// the number of restored messages.
+	RestoreSnapshot(context.Context, string, string, Restore) (uint64, error)
+}
+
+var _ Service = (*replayService)(nil)
+
+type replay struct {
+	Replay
+	read      uint64
+	published uint64
+	cancel    context.CancelFunc
+
+	mu    sync.Mutex
+	state string
+	err   string
+}
+
+func (r *replay) view() Replay {
+	rep := r.Replay
+	rep.Stats = Stats{
+		Read:      atomic.LoadUint64(&r.read),
+		Published: atomic.LoadUint64(&r.published),
+	}
+
+	r.mu.Lock()
+	rep.State = r.state
+	rep.Error = r.err
+	r.mu.Unlock()
+
+	return rep
+}
+
+func (r *replay) finish(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err != nil {
+		r.state = Failed
+		r.err = err.Error()
+		return
+	}
+	r.state = Completed
+}
+
+type snapshot struct {
+	Snapshot
+	messages uint64
+
+	mu    sync.Mutex
+	state string
+	err   string
+}
+
+func (s *snapshot) view() Snapshot {
+	snap := s.Snapshot
+	snap.Messages = atomic.LoadUint64(&s.messages)
+
+	s.mu.Lock()
+	snap.State = s.state
+	snap.Error = s.err
+	s.mu.Unlock()
+
+	return snap
+}
+
+func (s *snapshot) finish(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err != nil {
+		s.state = Failed
+		s.err = err.Error()
+		return
+	}
+	s.state = Completed
+}
+
+type replayService struct {
+	users     mainflux.UsersServiceClient
+	things    mainflux.ThingsServiceClient
+	reader    MessageReader
+	publisher Publisher
+	archive   ArchiveStore
+	idp       IdentityProvider
+	window    time.Duration
+
+	mu        sync.Mutex
+	replays   map[string]*replay
+	snapshots map[string]*snapshot
+}
+
+// New instantiates the replay service implementation. Messages are read in
+// the consecutive parts of the replayed time range of the window length, so
+// that the whole range doesn't have to be kept in memory.
+func New(users mainflux.UsersServiceClient, things mainflux.ThingsServiceClient, reader MessageReader, publisher Publisher, archive ArchiveStore, idp IdentityProvider, window time.Duration) Service {
+	return &replayService{
+		users:     users,
+		things:    things,
+		reader:    reader,
+		publisher: publisher,
+		archive:   archive,
+		idp:       idp,
+		window:    window,
+		replays:   make(map[string]*replay),
+		snapshots: make(map[string]*snapshot),
+	}
+}
+
+func (rs *replayService) StartReplay(ctx context.Context, token string, rep Replay) (Replay, error) {
+	owner, err := rs.identify(ctx, token)
+	if err != nil {
+		return Replay{}, err
+	}
+
+	if err := rep.Validate(); err != nil {
+		return Replay{}, err
+	}
+
+	rep.ID, err = rs.idp.ID()
+	if err != nil {
+		return Replay{}, err
+	}
+	rep.Owner = owner
+	rep.Started = time.Now()
+	rep.State = Running
+	rep.Error = ""
+	rep.Stats = Stats{}
+
+	runCtx, cancel := context.WithCancel(context.Background())
+	r := &replay{
+		Replay: rep,
+		cancel: cancel,
+		state:  Running,
+	}
+
+	rs.mu.Lock()
+	rs.replays[rep.ID] = r
+	rs.mu.Unlock()
+
+	go rs.run(runCtx, r)
+
+	return rep, nil
+}
+
+func (rs *replayService) ViewReplay(ctx context.Context, token, id string) (Replay, error) {
+	owner, err := rs.identify(ctx, token)
+	if err != nil {
+		return Replay{}, err
+	}
+
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	r, ok := rs.replays[id]
+	if !ok || r.Owner != owner {
+		return Replay{}, ErrNotFound
+	}
+
+	return r.view(), nil
+}
+
+func (rs *replayService) ListReplays(ctx context.Context, token string) ([]Replay, error) {
+	owner, err := rs.identify(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	reps := []Replay{}
+	for _, r := range rs.replays {
+		if r.Owner == owner {
+			reps = append(reps, r.view())
+		}
+	}
+
+	sort.Slice(reps, func(i, j int) bool {
+		return reps[i].Started.Before(reps[j].Started)
+	})
+
+	return reps, nil
+}
+
+func (rs *replayService) StopReplay(ctx context.Context, token, id string) error {
+	owner, err := rs.identify(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	r, ok := rs.replays[id]
+	if !ok || r.Owner != owner {
+		return ErrNotFound
+	}
+
+	r.cancel()
+	delete(rs.replays, id)
+
+	return nil
+}
+
+func (rs *replayService) TakeSnapshot(ctx context.Context, token string, snap Snapshot) (Snapshot, error) {
+	owner, err := rs.identify(ctx, token)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	if err := snap.Validate(); err != nil {
+		return Snapshot{}, err
+	}
+
+	snap.ID, err = rs.idp.ID()
+	if err != nil {
+		return Snapshot{}, err
+	}
+	snap.Owner = owner
+	snap.Created = time.Now()
+	snap.State = Running
+	snap.Error = ""
+	snap.Messages = 0
+
+	s := &snapshot{
+		Snapshot: snap,
+		state:    Running,
+	}
+
+	rs.mu.Lock()
+	rs.snapshots[snap.ID] = s
+	rs.mu.Unlock()
+
+	go rs.take(s)
+
+	return snap, nil
+}
+
+func (rs *replayService) ViewSnapshot(ctx context.Context, token, id string) (Snapshot, error) {
+	owner, err := rs.identify(ctx, token)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	rs.mu.Lock()
+	s, ok := rs.snapshots[id]
+	rs.mu.Unlock()
+	if ok && s.Owner == owner {
+		return s.view(), nil
+	}
+
+	// Snapshots taken before the service restarted are only archived.
+	snap, _, err := rs.archive.Retrieve(ctx, owner, id)
+	return snap, err
+}
+
+func (rs *replayService) ListSnapshots(ctx context.Context, token string) ([]Snapshot, error) {
+	owner, err := rs.identify(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	snaps := []Snapshot{}
+	for _, s := range rs.snapshots {
+		if s.Owner == owner {
+			snaps = append(snaps, s.view())
+		}
+	}
+
+	sort.Slice(snaps, func(i, j int) bool {
+		return snaps[i].Created.Before(snaps[j].Created)
+	})
+
+	return snaps, nil
+}
+
+func (rs *replayService) RestoreSnapshot(ctx context.Context, token, id string, r Restore) (uint64, error) {
+	owner, err := rs.identify(ctx, token)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := r.Validate(); err != nil {
+		return 0, err
+	}
+
+	if err := rs.authorize(ctx, r); err != nil {
+		return 0, err
+	}
+
+	_, msgs, err := rs.archive.Retrieve(ctx, owner, id)
+	if err != nil {
+		return 0, err
+	}
+
+	var restored uint64
+	for _, msg := range msgs {
+		msg.Channel = r.Channel
+		if err := rs.publisher.Restore(r.Writer, msg); err != nil {
+			return restored, err
+		}
+		restored++
+	}
+
+	return restored, nil
+}
+
+func (rs *replayService) identify(ctx context.Context, token string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	res, err := rs.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return "", ErrUnauthorizedAccess
+	}
+
+	return res.GetValue(), nil
+}
+
+// authorize checks whether the thing key of the restore is allowed to publish
+// to the target channel.
+func (rs *replayService) authorize(ctx context.Context, r Restore) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	req := &mainflux.AccessReq{Token: r.ThingKey, ChanID: r.Channel, Action: things.Publish}
+	if _, err := rs.things.CanAccess(ctx, req); err != nil {
+		return ErrUnauthorizedAccess
+	}
+
+	return nil
+}
+
+// take reads the messages of the snapshot and stores them in the archive once
+// they are all read. Thing key is used for reading only, and isn't archived.
+func (rs *replayService) take(s *snapshot) {
+	ctx := context.Background()
+	rep := Replay{
+		Channel:  s.Channel,
+		ThingKey: s.ThingKey,
+	}
+
+	msgs := []mainflux.Message{}
+	window := rs.window.Seconds()
+	for from := s.From; from < s.To; from += window {
+		read, err := rs.reader.ReadMessages(ctx, rep, from, math.Min(from+window, s.To))
+		if err != nil {
+			s.finish(err)
+			return
+		}
+		msgs = append(msgs, read...)
+		atomic.AddUint64(&s.messages, uint64(len(read)))
+	}
+
+	snap := s.view()
+	snap.ThingKey = ""
+	snap.State = Completed
+	s.finish(rs.archive.Save(ctx, snap, msgs))
+}
+
+// run publishes the messages of the replay until they are exhausted or the
+// replay is stopped. Publishing times are measured from the first message, so
+// that the replay doesn't idle if the range starts before the first message.
+func (rs *replayService) run(ctx context.Context, r *replay) {
+	var origin float64
+	var start time.Time
+
+	window := rs.window.Seconds()
+	for from := r.From; from < r.To; from += window {
+		msgs, err := rs.reader.ReadMessages(ctx, r.Replay, from, math.Min(from+window, r.To))
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			r.finish(err)
+			return
+		}
+		atomic.AddUint64(&r.read, uint64(len(msgs)))
+
+		for _, msg := range msgs {
+			if start.IsZero() {
+				origin = msg.Time
+				start = time.Now()
+			}
+
+			offset := time.Duration((msg.Time - origin) / r.Speed * float64(time.Second))
+			if !wait(ctx, time.Until(start.Add(offset))) {
+				return
+			}
+
+			if err := rs.publisher.Publish(r.Replay, msg); err != nil {
+				r.finish(err)
+				return
+			}
+			atomic.AddUint64(&r.published, 1)
+		}
+	}
+
+	r.finish(nil)
+}
+
+// wait blocks for the given duration and returns false if the context is
+// canceled in the meantime.
+func wait(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
@@ -0,0 +1,612 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package replay_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/replay"
+	"github.com/mainflux/mainflux/replay/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	wrongValue = "wrong-value"
+	email      = "user@example.com"
+	token      = "token"
+	chanID     = "1"
+	thingKey   = "key"
+	otherChan  = "3"
+	otherKey   = "other-key"
+	window     = 10 * time.Second
+	numMsgs    = 50
+)
+
+var (
+	rep = replay.Replay{
+		Channel:  chanID,
+		ThingKey: thingKey,
+		From:     1000,
+		To:       1100,
+		Speed:    1000,
+	}
+
+	snap = replay.Snapshot{
+		Channel:  chanID,
+		ThingKey: thingKey,
+		From:     1010,
+		To:       1020,
+	}
+)
+
+func newService(tokens map[string]string) (replay.Service, *mocks.Publisher) {
+	users := mocks.NewUsersService(tokens)
+	things := mocks.NewThingsService(map[string]string{thingKey: chanID, otherKey: otherChan})
+	pub := mocks.NewPublisher()
+	idp := mocks.NewIdentityProvider()
+
+	// Messages are published every two seconds, newest first, so that the
+	// replay has to read them in multiple windows and reorder them.
+	var msgs []mainflux.Message
+	for i := numMsgs - 1; i >= 0; i-- {
+		msgs = append(msgs, mainflux.Message{
+			Channel:   chanID,
+			Publisher: "2",
+			Protocol:  "http",
+			Name:      fmt.Sprintf("name-%d", i),
+			Value:     &mainflux.Message_FloatValue{FloatValue: float64(i)},
+			Time:      float64(1000 + 2*i),
+		})
+	}
+	reader := mocks.NewReader(map[string]string{thingKey: chanID}, map[string][]mainflux.Message{chanID: msgs})
+
+	return replay.New(users, things, reader, pub, mocks.NewArchiveStore(), idp, window), pub
+}
+
+func waitState(svc replay.Service, id, state string) replay.Replay {
+	var view replay.Replay
+	for i := 0; i < 100; i++ {
+		view, _ = svc.ViewReplay(context.Background(), token, id)
+		if view.State == state {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return view
+}
+
+func TestStartReplay(t *testing.T) {
+	svc, _ := newService(map[string]string{token: email})
+
+	noChannel := rep
+	noChannel.Channel = ""
+
+	noKey := rep
+	noKey.ThingKey = ""
+
+	emptyRange := rep
+	emptyRange.To = rep.From
+
+	noSpeed := rep
+	noSpeed.Speed = 0
+
+	writer := rep
+	writer.Writer = "influxdb-writer"
+
+	wildcardWriter := rep
+	wildcardWriter.Writer = "writers.>"
+
+	cases := []struct {
+		desc  string
+		rep   replay.Replay
+		token string
+		err   error
+	}{
+		{
+			desc:  "start valid replay",
+			rep:   rep,
+			token: token,
+			err:   nil,
+		},
+		{
+			desc:  "start replay to writer",
+			rep:   writer,
+			token: token,
+			err:   nil,
+		},
+		{
+			desc:  "start replay with wrong credentials",
+			rep:   rep,
+			token: wrongValue,
+			err:   replay.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "start replay without channel",
+			rep:   noChannel,
+			token: token,
+			err:   replay.ErrMalformedEntity,
+		},
+		{
+			desc:  "start replay without thing key",
+			rep:   noKey,
+			token: token,
+			err:   replay.ErrMalformedEntity,
+		},
+		{
+			desc:  "start replay with empty time range",
+			rep:   emptyRange,
+			token: token,
+			err:   replay.ErrMalformedEntity,
+		},
+		{
+			desc:  "start replay without speed",
+			rep:   noSpeed,
+			token: token,
+			err:   replay.ErrMalformedEntity,
+		},
+		{
+			desc:  "start replay to writer with invalid name",
+			rep:   wildcardWriter,
+			token: token,
+			err:   replay.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := svc.StartReplay(context.Background(), tc.token, tc.rep)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestReplayMessages(t *testing.T) {
+	svc, pub := newService(map[string]string{token: email})
+
+	partial := rep
+	partial.From = 1010
+	partial.To = 1020
+
+	wrongKey := rep
+	wrongKey.ThingKey = wrongValue
+
+	cases := []struct {
+		desc  string
+		rep   replay.Replay
+		state string
+		names []string
+	}{
+		{
+			desc:  "replay all messages",
+			rep:   rep,
+			state: replay.Completed,
+			names: names(0, numMsgs),
+		},
+		{
+			desc:  "replay messages within time range",
+			rep:   partial,
+			state: replay.Completed,
+			names: names(5, 10),
+		},
+		{
+			desc:  "replay messages with wrong thing key",
+			rep:   wrongKey,
+			state: replay.Failed,
+			names: []string{},
+		},
+	}
+
+	for _, tc := range cases {
+		saved, err := svc.StartReplay(context.Background(), token, tc.rep)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+
+		view := waitState(svc, saved.ID, tc.state)
+		assert.Equal(t, tc.state, view.State, fmt.Sprintf("%s: expected state %s got %s\n", tc.desc, tc.state, view.State))
+		assert.Equal(t, uint64(len(tc.names)), view.Stats.Published, fmt.Sprintf("%s: expected %d published messages got %d\n", tc.desc, len(tc.names), view.Stats.Published))
+
+		published := []string{}
+		for _, msg := range pub.Published(saved.ID) {
+			published = append(published, msg.Name)
+		}
+		assert.Equal(t, tc.names, published, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.names, published))
+	}
+}
+
+func TestViewReplay(t *testing.T) {
+	svc, _ := newService(map[string]string{token: email, wrongValue: "other@example.com"})
+	saved, err := svc.StartReplay(context.Background(), token, rep)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := map[string]struct {
+		id    string
+		token string
+		err   error
+	}{
+		"view existing replay": {
+			id:    saved.ID,
+			token: token,
+			err:   nil,
+		},
+		"view replay with wrong credentials": {
+			id:    saved.ID,
+			token: "invalid",
+			err:   replay.ErrUnauthorizedAccess,
+		},
+		"view replay owned by another user": {
+			id:    saved.ID,
+			token: wrongValue,
+			err:   replay.ErrNotFound,
+		},
+		"view non-existing replay": {
+			id:    wrongValue,
+			token: token,
+			err:   replay.ErrNotFound,
+		},
+	}
+
+	for desc, tc := range cases {
+		_, err := svc.ViewReplay(context.Background(), tc.token, tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
+
+func TestListReplays(t *testing.T) {
+	svc, _ := newService(map[string]string{token: email, wrongValue: "other@example.com"})
+
+	n := 5
+	for i := 0; i < n; i++ {
+		_, err := svc.StartReplay(context.Background(), token, rep)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	cases := map[string]struct {
+		token string
+		size  int
+		err   error
+	}{
+		"list all replays": {
+			token: token,
+			size:  n,
+			err:   nil,
+		},
+		"list replays of another user": {
+			token: wrongValue,
+			size:  0,
+			err:   nil,
+		},
+		"list replays with wrong credentials": {
+			token: "invalid",
+			size:  0,
+			err:   replay.ErrUnauthorizedAccess,
+		},
+	}
+
+	for desc, tc := range cases {
+		reps, err := svc.ListReplays(context.Background(), tc.token)
+		size := len(reps)
+		assert.Equal(t, tc.size, size, fmt.Sprintf("%s: expected %d got %d\n", desc, tc.size, size))
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
+
+func TestStopReplay(t *testing.T) {
+	svc, pub := newService(map[string]string{token: email})
+
+	// Messages two seconds apart are replayed every 100ms.
+	slow := rep
+	slow.Speed = 20
+	saved, err := svc.StartReplay(context.Background(), token, slow)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	time.Sleep(250 * time.Millisecond)
+
+	cases := []struct {
+		desc  string
+		id    string
+		token string
+		err   error
+	}{
+		{
+			desc:  "stop replay with wrong credentials",
+			id:    saved.ID,
+			token: wrongValue,
+			err:   replay.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "stop running replay",
+			id:    saved.ID,
+			token: token,
+			err:   nil,
+		},
+		{
+			desc:  "stop stopped replay",
+			id:    saved.ID,
+			token: token,
+			err:   replay.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.StopReplay(context.Background(), tc.token, tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	// Let the message that was being published when stopped settle.
+	time.Sleep(20 * time.Millisecond)
+	published := len(pub.Published(saved.ID))
+	assert.True(t, published > 0 && published < numMsgs, fmt.Sprintf("expected partially published replay got %d messages", published))
+
+	time.Sleep(250 * time.Millisecond)
+	after := len(pub.Published(saved.ID))
+	assert.Equal(t, published, after, fmt.Sprintf("expected no messages published after stop, got %d", after-published))
+}
+
+func waitSnapshot(svc replay.Service, id, state string) replay.Snapshot {
+	var view replay.Snapshot
+	for i := 0; i < 100; i++ {
+		view, _ = svc.ViewSnapshot(context.Background(), token, id)
+		if view.State == state {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return view
+}
+
+func TestTakeSnapshot(t *testing.T) {
+	svc, _ := newService(map[string]string{token: email})
+
+	noChannel := snap
+	noChannel.Channel = ""
+
+	noKey := snap
+	noKey.ThingKey = ""
+
+	emptyRange := snap
+	emptyRange.To = snap.From
+
+	wrongKey := snap
+	wrongKey.ThingKey = wrongValue
+
+	cases := []struct {
+		desc     string
+		snap     replay.Snapshot
+		token    string
+		err      error
+		state    string
+		messages uint64
+	}{
+		{
+			desc:     "take valid snapshot",
+			snap:     snap,
+			token:    token,
+			err:      nil,
+			state:    replay.Completed,
+			messages: 5,
+		},
+		{
+			desc:     "take snapshot with wrong thing key",
+			snap:     wrongKey,
+			token:    token,
+			err:      nil,
+			state:    replay.Failed,
+			messages: 0,
+		},
+		{
+			desc:  "take snapshot with wrong credentials",
+			snap:  snap,
+			token: wrongValue,
+			err:   replay.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "take snapshot without channel",
+			snap:  noChannel,
+			token: token,
+			err:   replay.ErrMalformedEntity,
+		},
+		{
+			desc:  "take snapshot without thing key",
+			snap:  noKey,
+			token: token,
+			err:   replay.ErrMalformedEntity,
+		},
+		{
+			desc:  "take snapshot with empty time range",
+			snap:  emptyRange,
+			token: token,
+			err:   replay.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		saved, err := svc.TakeSnapshot(context.Background(), tc.token, tc.snap)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err != nil {
+			continue
+		}
+
+		view := waitSnapshot(svc, saved.ID, tc.state)
+		assert.Equal(t, tc.state, view.State, fmt.Sprintf("%s: expected state %s got %s\n", tc.desc, tc.state, view.State))
+		assert.Equal(t, tc.messages, view.Messages, fmt.Sprintf("%s: expected %d messages got %d\n", tc.desc, tc.messages, view.Messages))
+	}
+}
+
+func TestViewSnapshot(t *testing.T) {
+	svc, _ := newService(map[string]string{token: email, wrongValue: "other@example.com"})
+	saved, err := svc.TakeSnapshot(context.Background(), token, snap)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := map[string]struct {
+		id    string
+		token string
+		err   error
+	}{
+		"view existing snapshot": {
+			id:    saved.ID,
+			token: token,
+			err:   nil,
+		},
+		"view snapshot with wrong credentials": {
+			id:    saved.ID,
+			token: "invalid",
+			err:   replay.ErrUnauthorizedAccess,
+		},
+		"view snapshot owned by another user": {
+			id:    saved.ID,
+			token: wrongValue,
+			err:   replay.ErrNotFound,
+		},
+		"view non-existing snapshot": {
+			id:    wrongValue,
+			token: token,
+			err:   replay.ErrNotFound,
+		},
+	}
+
+	for desc, tc := range cases {
+		_, err := svc.ViewSnapshot(context.Background(), tc.token, tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
+
+func TestListSnapshots(t *testing.T) {
+	svc, _ := newService(map[string]string{token: email, wrongValue: "other@example.com"})
+
+	n := 5
+	for i := 0; i < n; i++ {
+		_, err := svc.TakeSnapshot(context.Background(), token, snap)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	cases := map[string]struct {
+		token string
+		size  int
+		err   error
+	}{
+		"list all snapshots": {
+			token: token,
+			size:  n,
+			err:   nil,
+		},
+		"list snapshots of another user": {
+			token: wrongValue,
+			size:  0,
+			err:   nil,
+		},
+		"list snapshots with wrong credentials": {
+			token: "invalid",
+			size:  0,
+			err:   replay.ErrUnauthorizedAccess,
+		},
+	}
+
+	for desc, tc := range cases {
+		snaps, err := svc.ListSnapshots(context.Background(), tc.token)
+		size := len(snaps)
+		assert.Equal(t, tc.size, size, fmt.Sprintf("%s: expected %d got %d\n", desc, tc.size, size))
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
+
+func TestRestoreSnapshot(t *testing.T) {
+	svc, pub := newService(map[string]string{token: email, wrongValue: "other@example.com"})
+	saved, err := svc.TakeSnapshot(context.Background(), token, snap)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	view := waitSnapshot(svc, saved.ID, replay.Completed)
+	require.Equal(t, replay.Completed, view.State, fmt.Sprintf("expected completed snapshot got %s", view.State))
+
+	cases := []struct {
+		desc     string
+		id       string
+		token    string
+		restore  replay.Restore
+		restored uint64
+		err      error
+	}{
+		{
+			desc:     "restore snapshot to the same channel",
+			id:       saved.ID,
+			token:    token,
+			restore:  replay.Restore{Channel: chanID, ThingKey: thingKey},
+			restored: 5,
+			err:      nil,
+		},
+		{
+			desc:     "restore snapshot to another channel",
+			id:       saved.ID,
+			token:    token,
+			restore:  replay.Restore{Channel: otherChan, ThingKey: otherKey, Writer: "influxdb-writer"},
+			restored: 5,
+			err:      nil,
+		},
+		{
+			desc:    "restore snapshot to channel the key isn't connected to",
+			id:      saved.ID,
+			token:   token,
+			restore: replay.Restore{Channel: otherChan, ThingKey: thingKey},
+			err:     replay.ErrUnauthorizedAccess,
+		},
+		{
+			desc:    "restore snapshot with wrong credentials",
+			id:      saved.ID,
+			token:   "invalid",
+			restore: replay.Restore{Channel: chanID, ThingKey: thingKey},
+			err:     replay.ErrUnauthorizedAccess,
+		},
+		{
+			desc:    "restore snapshot owned by another user",
+			id:      saved.ID,
+			token:   wrongValue,
+			restore: replay.Restore{Channel: chanID, ThingKey: thingKey},
+			err:     replay.ErrNotFound,
+		},
+		{
+			desc:    "restore non-existing snapshot",
+			id:      wrongValue,
+			token:   token,
+			restore: replay.Restore{Channel: chanID, ThingKey: thingKey},
+			err:     replay.ErrNotFound,
+		},
+		{
+			desc:    "restore snapshot without channel",
+			id:      saved.ID,
+			token:   token,
+			restore: replay.Restore{ThingKey: thingKey},
+			err:     replay.ErrMalformedEntity,
+		},
+		{
+			desc:    "restore snapshot to writer with invalid name",
+			id:      saved.ID,
+			token:   token,
+			restore: replay.Restore{Channel: chanID, ThingKey: thingKey, Writer: "writers.>"},
+			err:     replay.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		restored, err := svc.RestoreSnapshot(context.Background(), tc.token, tc.id, tc.restore)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.restored, restored, fmt.Sprintf("%s: expected %d restored messages got %d\n", tc.desc, tc.restored, restored))
+	}
+
+	for _, ch := range []string{chanID, otherChan} {
+		restored := []string{}
+		for _, msg := range pub.Restored(ch) {
+			assert.Equal(t, ch, msg.Channel, fmt.Sprintf("expected message of channel %s got %s\n", ch, msg.Channel))
+			restored = append(restored, msg.Name)
+		}
+		assert.Equal(t, names(5, 10), restored, fmt.Sprintf("expected %v got %v\n", names(5, 10), restored))
+	}
+}
+
+func names(from, to int) []string {
+	var ns []string
+	for i := from; i < to; i++ {
+		ns = append(ns, fmt.Sprintf("name-%d", i))
+	}
+	return ns
+}
@@ -0,0 +1,78 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package replay
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/mainflux/mainflux"
+)
+
+// Snapshot represents the archive of the messages that were published to the
+// channel within the time range. Snapshot is taken in the background, and its
+// messages are kept in the archive store once it is completed, so that they
+// can be restored to the same or another channel later on.
+// Time range bounds are Unix timestamps in seconds, where the start is
+// inclusive and the end is exclusive.
+type Snapshot struct {
+	ID       string
+	Owner    string
+	Channel  string
+	ThingKey string
+	From     float64
+	To       float64
+	Created  time.Time
+	State    string
+	Error    string
+	Messages uint64
+}
+
+// Validate returns an error if snapshot is not well-formed.
+func (s Snapshot) Validate() error {
+	if s.Channel == "" || s.ThingKey == "" {
+		return ErrMalformedEntity
+	}
+
+	if s.From < 0 || s.To <= s.From {
+		return ErrMalformedEntity
+	}
+
+	return nil
+}
+
+// Restore represents publishing of the snapshot messages to the writers as
+// the messages of the target channel. The thing key has to be connected to
+// the target channel. If the writer is set, messages are restored to that
+// writer only.
+type Restore struct {
+	Channel  string
+	ThingKey string
+	Writer   string
+}
+
+// Validate returns an error if restore is not well-formed.
+func (r Restore) Validate() error {
+	if r.Channel == "" || r.ThingKey == "" {
+		return ErrMalformedEntity
+	}
+
+	// Writer name is used as the NATS subject token.
+	if strings.ContainsAny(r.Writer, ".*> \t") {
+		return ErrMalformedEntity
+	}
+
+	return nil
+}
+
+// ArchiveStore specifies an API for keeping the snapshot archives.
+type ArchiveStore interface {
+	// Save stores the completed snapshot together with its messages.
+	Save(context.Context, Snapshot, []mainflux.Message) error
+
+	// Retrieve retrieves the snapshot identified by the provided ID, that
+	// belongs to the provided owner, together with its messages.
+	Retrieve(context.Context, string, string) (Snapshot, []mainflux.Message, error)
+}
@@ -0,0 +1,55 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/routing"
+)
+
+var _ routing.Link = (*Link)(nil)
+
+// Link is the mock federation link that records the published messages. It
+// can be used as the local publisher as well.
+type Link struct {
+	mu       sync.Mutex
+	latency  time.Duration
+	err      error
+	messages []mainflux.RawMessage
+}
+
+// NewLink returns mock link that reports the given latency or, if set, the
+// probe error.
+func NewLink(latency time.Duration, err error) *Link {
+	return &Link{
+		latency: latency,
+		err:     err,
+	}
+}
+
+// Publish records the message.
+func (l *Link) Publish(_ context.Context, _ string, msg mainflux.RawMessage) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.messages = append(l.messages, msg)
+	return nil
+}
+
+// Latency returns the configured latency and probe error.
+func (l *Link) Latency() (time.Duration, error) {
+	return l.latency, l.err
+}
+
+// Messages returns the recorded messages.
+func (l *Link) Messages() []mainflux.RawMessage {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.messages
+}
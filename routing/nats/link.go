@@ -0,0 +1,61 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package nats contains NATS federation link implementation.
+package nats
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/mainflux/mainflux"
+	mfnats "github.com/mainflux/mainflux/nats"
+	"github.com/mainflux/mainflux/routing"
+	broker "github.com/nats-io/nats.go"
+)
+
+const prefix = "channel"
+
+var _ routing.Link = (*natsLink)(nil)
+
+type natsLink struct {
+	nc            *broker.Conn
+	subjectPrefix string
+	timeout       time.Duration
+}
+
+// NewLink instantiates the federation link over the connection to the NATS
+// broker of the remote cluster. The subjects are prefixed with the subject
+// prefix shared by the federated deployments, unless it is empty. Latency
+// probes taking longer than the timeout are considered failed.
+func NewLink(nc *broker.Conn, subjectPrefix string, timeout time.Duration) routing.Link {
+	return &natsLink{
+		nc:            nc,
+		subjectPrefix: subjectPrefix,
+		timeout:       timeout,
+	}
+}
+
+func (link *natsLink) Publish(_ context.Context, _ string, msg mainflux.RawMessage) error {
+	data, err := proto.Marshal(&msg)
+	if err != nil {
+		return err
+	}
+
+	subject := fmt.Sprintf("%s.%s", prefix, msg.Channel)
+	if msg.Subtopic != "" {
+		subject = fmt.Sprintf("%s.%s", subject, msg.Subtopic)
+	}
+	return link.nc.Publish(mfnats.Subject(link.subjectPrefix, subject), data)
+}
+
+func (link *natsLink) Latency() (time.Duration, error) {
+	start := time.Now()
+	if err := link.nc.FlushTimeout(link.timeout); err != nil {
+		return 0, err
+	}
+
+	return time.Since(start), nil
+}
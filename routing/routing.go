@@ -0,0 +1,194 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package routing contains the message router used by the adapters of the
+// geo-distributed deployments. Messages published to the channels homed to
+// the other regions are forwarded to the broker of the owning cluster over
+// the federation link with the lowest measured latency.
+package routing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	log "github.com/mainflux/mainflux/logger"
+)
+
+var (
+	// ErrNoLink indicates that there is no available federation link to the
+	// region of the channel.
+	ErrNoLink = errors.New("no federation link to the channel region")
+
+	// ErrMalformedLinks indicates malformed federation links specification.
+	ErrMalformedLinks = errors.New("malformed federation links")
+)
+
+// Link represents the federation link to the broker of the remote cluster.
+type Link interface {
+	mainflux.MessagePublisher
+
+	// Latency measures the round trip time to the remote broker.
+	Latency() (time.Duration, error)
+}
+
+// Router publishes the messages of the local channels to the local broker,
+// and forwards the messages of the channels homed to the other regions to
+// their owning clusters.
+type Router interface {
+	mainflux.MessagePublisher
+
+	// Probe measures the latency of the federation links and selects the
+	// fastest available link of each region.
+	Probe()
+}
+
+var _ Router = (*router)(nil)
+
+type cachedRegion struct {
+	region  string
+	checked time.Time
+}
+
+type router struct {
+	region  string
+	local   mainflux.MessagePublisher
+	links   map[string][]Link
+	things  mainflux.ThingsServiceClient
+	refresh time.Duration
+	logger  log.Logger
+	mu      sync.RWMutex
+	regions map[string]cachedRegion
+	fastest map[string]Link
+}
+
+// New returns new router serving the given region. The channels regions are
+// looked up in the things service at most once per refresh interval. Until
+// the links are probed, the first link of each region is used.
+func New(region string, local mainflux.MessagePublisher, links map[string][]Link, things mainflux.ThingsServiceClient, refresh time.Duration, logger log.Logger) Router {
+	fastest := make(map[string]Link)
+	for r, ls := range links {
+		if len(ls) > 0 {
+			fastest[r] = ls[0]
+		}
+	}
+
+	return &router{
+		region:  region,
+		local:   local,
+		links:   links,
+		things:  things,
+		refresh: refresh,
+		logger:  logger,
+		regions: make(map[string]cachedRegion),
+		fastest: fastest,
+	}
+}
+
+func (r *router) Publish(ctx context.Context, token string, msg mainflux.RawMessage) error {
+	region := r.channelRegion(msg.Channel)
+	if region == "" || region == r.region {
+		return r.local.Publish(ctx, token, msg)
+	}
+
+	r.mu.RLock()
+	link, ok := r.fastest[region]
+	r.mu.RUnlock()
+	if !ok {
+		return ErrNoLink
+	}
+
+	return link.Publish(ctx, token, msg)
+}
+
+func (r *router) Probe() {
+	for region, links := range r.links {
+		var fastest Link
+		var min time.Duration
+		for _, link := range links {
+			latency, err := link.Latency()
+			if err != nil {
+				r.logger.Warn(fmt.Sprintf("Failed to probe federation link to region %s: %s", region, err))
+				continue
+			}
+			if fastest == nil || latency < min {
+				fastest, min = link, latency
+			}
+		}
+
+		// The previously selected link is kept if none of the links
+		// responded, as the failure may be transient.
+		if fastest == nil {
+			continue
+		}
+
+		r.mu.Lock()
+		r.fastest[region] = fastest
+		r.mu.Unlock()
+	}
+}
+
+// channelRegion returns the region of the channel. If the region lookup
+// fails, the last known region is used, and the channels of the unknown
+// region are treated as local.
+func (r *router) channelRegion(channel string) string {
+	r.mu.RLock()
+	cached, ok := r.regions[channel]
+	r.mu.RUnlock()
+	if ok && time.Since(cached.checked) < r.refresh {
+		return cached.region
+	}
+
+	// Failed lookups are retried after the refresh interval as well, so the
+	// unavailable things service is not queried for every message.
+	cached.checked = time.Now()
+
+	res, err := r.things.Region(context.Background(), &mainflux.ChannelID{Value: channel})
+	if err != nil {
+		r.logger.Warn(fmt.Sprintf("Failed to retrieve region of channel %s: %s", channel, err))
+	} else {
+		cached.region = res.GetValue()
+	}
+
+	r.mu.Lock()
+	r.regions[channel] = cached
+	r.mu.Unlock()
+
+	return cached.region
+}
+
+// ParseLinks parses the federation links specification. Regions are
+// separated by semicolons and each region lists the comma separated broker
+// URLs of its cluster, e.g. "eu-west=nats://a:4222,nats://b:4222;us-east=nats://c:4222".
+func ParseLinks(spec string) (map[string][]string, error) {
+	links := make(map[string][]string)
+	if strings.TrimSpace(spec) == "" {
+		return links, nil
+	}
+
+	for _, entry := range strings.Split(spec, ";") {
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 {
+			return nil, ErrMalformedLinks
+		}
+
+		region := strings.TrimSpace(parts[0])
+		if region == "" {
+			return nil, ErrMalformedLinks
+		}
+
+		for _, url := range strings.Split(parts[1], ",") {
+			url = strings.TrimSpace(url)
+			if url == "" {
+				return nil, ErrMalformedLinks
+			}
+			links[region] = append(links[region], url)
+		}
+	}
+
+	return links, nil
+}
@@ -0,0 +1,167 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package routing_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/routing"
+	"github.com/mainflux/mainflux/routing/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	localRegion  = "eu-west"
+	remoteRegion = "us-east"
+	token        = "token"
+)
+
+func newRouter(things mainflux.ThingsServiceClient, local *mocks.Link, links map[string][]routing.Link) routing.Router {
+	logger, _ := logger.New(os.Stdout, logger.Info.String())
+	return routing.New(localRegion, local, links, things, time.Minute, logger)
+}
+
+func TestPublish(t *testing.T) {
+	things := mocks.NewThingsClient(map[string]string{
+		"unhomed": "",
+		"local":   localRegion,
+		"remote":  remoteRegion,
+		"unknown": "ap-south",
+	})
+	local := mocks.NewLink(0, nil)
+	remote := mocks.NewLink(0, nil)
+	router := newRouter(things, local, map[string][]routing.Link{remoteRegion: {remote}})
+
+	cases := []struct {
+		desc    string
+		channel string
+		pub     *mocks.Link
+		err     error
+	}{
+		{
+			desc:    "publish to channel without region",
+			channel: "unhomed",
+			pub:     local,
+			err:     nil,
+		},
+		{
+			desc:    "publish to channel of local region",
+			channel: "local",
+			pub:     local,
+			err:     nil,
+		},
+		{
+			desc:    "publish to channel of remote region",
+			channel: "remote",
+			pub:     remote,
+			err:     nil,
+		},
+		{
+			desc:    "publish to channel of region without link",
+			channel: "unknown",
+			pub:     nil,
+			err:     routing.ErrNoLink,
+		},
+		{
+			desc:    "publish to non-existent channel",
+			channel: "non-existent",
+			pub:     local,
+			err:     nil,
+		},
+	}
+
+	for _, tc := range cases {
+		msg := mainflux.RawMessage{Channel: tc.channel}
+		err := router.Publish(context.Background(), token, msg)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if tc.pub == nil {
+			continue
+		}
+		msgs := tc.pub.Messages()
+		assert.Equal(t, msg, msgs[len(msgs)-1], fmt.Sprintf("%s: expected message %v got %v\n", tc.desc, msg, msgs[len(msgs)-1]))
+	}
+}
+
+func TestRegionCache(t *testing.T) {
+	things := mocks.NewThingsClient(map[string]string{"remote": remoteRegion})
+	remote := mocks.NewLink(0, nil)
+	router := newRouter(things, mocks.NewLink(0, nil), map[string][]routing.Link{remoteRegion: {remote}})
+
+	for i := 0; i < 3; i++ {
+		err := router.Publish(context.Background(), token, mainflux.RawMessage{Channel: "remote"})
+		assert.Nil(t, err, fmt.Sprintf("publish to remote channel: unexpected error %s", err))
+	}
+	assert.Equal(t, 1, things.Lookups(), fmt.Sprintf("expected 1 region lookup got %d", things.Lookups()))
+	assert.Equal(t, 3, len(remote.Messages()), fmt.Sprintf("expected 3 forwarded messages got %d", len(remote.Messages())))
+}
+
+func TestProbe(t *testing.T) {
+	things := mocks.NewThingsClient(map[string]string{"remote": remoteRegion})
+	slow := mocks.NewLink(100*time.Millisecond, nil)
+	fast := mocks.NewLink(10*time.Millisecond, nil)
+	failed := mocks.NewLink(0, errors.New("timeout"))
+	router := newRouter(things, mocks.NewLink(0, nil), map[string][]routing.Link{remoteRegion: {slow, failed, fast}})
+
+	msg := mainflux.RawMessage{Channel: "remote"}
+
+	err := router.Publish(context.Background(), token, msg)
+	assert.Nil(t, err, fmt.Sprintf("publish before probe: unexpected error %s", err))
+	assert.Equal(t, 1, len(slow.Messages()), "publish before probe: expected message forwarded over the first link")
+
+	router.Probe()
+	err = router.Publish(context.Background(), token, msg)
+	assert.Nil(t, err, fmt.Sprintf("publish after probe: unexpected error %s", err))
+	assert.Equal(t, 1, len(fast.Messages()), "publish after probe: expected message forwarded over the fastest link")
+	assert.Equal(t, 0, len(failed.Messages()), "publish after probe: expected no message forwarded over the failed link")
+}
+
+func TestParseLinks(t *testing.T) {
+	cases := []struct {
+		desc  string
+		spec  string
+		links map[string][]string
+		err   error
+	}{
+		{
+			desc:  "parse empty links",
+			spec:  "",
+			links: map[string][]string{},
+			err:   nil,
+		},
+		{
+			desc: "parse links of multiple regions",
+			spec: "eu-west=nats://a:4222,nats://b:4222;us-east=nats://c:4222",
+			links: map[string][]string{
+				"eu-west": {"nats://a:4222", "nats://b:4222"},
+				"us-east": {"nats://c:4222"},
+			},
+			err: nil,
+		},
+		{
+			desc:  "parse links without region",
+			spec:  "nats://a:4222",
+			links: nil,
+			err:   routing.ErrMalformedLinks,
+		},
+		{
+			desc:  "parse links with empty URL",
+			spec:  "eu-west=nats://a:4222,",
+			links: nil,
+			err:   routing.ErrMalformedLinks,
+		},
+	}
+
+	for _, tc := range cases {
+		links, err := routing.ParseLinks(tc.spec)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.links, links, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.links, links))
+	}
+}
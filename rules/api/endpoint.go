@@ -0,0 +1,165 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/mainflux/mainflux/rules"
+)
+
+func createRuleEndpoint(svc rules.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(ruleReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		saved, err := svc.CreateRule(ctx, req.token, toRule(req))
+		if err != nil {
+			return nil, err
+		}
+
+		return ruleRes{id: saved.ID, created: true}, nil
+	}
+}
+
+func updateRuleEndpoint(svc rules.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(updateRuleReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.UpdateRule(ctx, req.token, toRule(req.ruleReq)); err != nil {
+			return nil, err
+		}
+
+		return ruleRes{id: req.id, created: false}, nil
+	}
+}
+
+func viewRuleEndpoint(svc rules.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewRuleReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		rule, err := svc.ViewRule(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		return toRuleRes(rule), nil
+	}
+}
+
+func listRulesEndpoint(svc rules.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listRulesReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		page, err := svc.ListRules(ctx, req.token, req.offset, req.limit)
+		if err != nil {
+			return nil, err
+		}
+
+		res := rulesPageRes{
+			Total:  page.Total,
+			Offset: page.Offset,
+			Limit:  page.Limit,
+			Rules:  []viewRuleRes{},
+		}
+		for _, r := range page.Rules {
+			res.Rules = append(res.Rules, toRuleRes(r))
+		}
+
+		return res, nil
+	}
+}
+
+func removeRuleEndpoint(svc rules.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewRuleReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RemoveRule(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
+func toRule(req ruleReq) rules.Rule {
+	rule := rules.Rule{
+		ID:       req.id,
+		Name:     req.Name,
+		Channel:  req.Channel,
+		Subtopic: req.Subtopic,
+		Field:    req.Field,
+		Condition: rules.Condition{
+			Type:      req.Condition.Type,
+			Operator:  req.Condition.Operator,
+			Threshold: req.Condition.Threshold,
+			Days:      req.Condition.Days,
+			Start:     req.Condition.Start,
+			End:       req.Condition.End,
+		},
+	}
+
+	for _, a := range req.Actions {
+		rule.Actions = append(rule.Actions, rules.Action{
+			Type:       a.Type,
+			Channel:    a.Channel,
+			Subtopic:   a.Subtopic,
+			URL:        a.URL,
+			Recipients: a.Recipients,
+		})
+	}
+
+	return rule
+}
+
+func toRuleRes(r rules.Rule) viewRuleRes {
+	res := viewRuleRes{
+		ID:       r.ID,
+		Name:     r.Name,
+		Channel:  r.Channel,
+		Subtopic: r.Subtopic,
+		Field:    r.Field,
+		Condition: conditionReq{
+			Type:      r.Condition.Type,
+			Operator:  r.Condition.Operator,
+			Threshold: r.Condition.Threshold,
+			Days:      r.Condition.Days,
+			Start:     r.Condition.Start,
+			End:       r.Condition.End,
+		},
+		Actions: []actionReq{},
+	}
+
+	for _, a := range r.Actions {
+		res.Actions = append(res.Actions, actionReq{
+			Type:       a.Type,
+			Channel:    a.Channel,
+			Subtopic:   a.Subtopic,
+			URL:        a.URL,
+			Recipients: a.Recipients,
+		})
+	}
+
+	return res
+}
@@ -0,0 +1,96 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// +build !test
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/rules"
+)
+
+var _ rules.Service = (*metricsMiddleware)(nil)
+
+type metricsMiddleware struct {
+	counter metrics.Counter
+	latency metrics.Histogram
+	svc     rules.Service
+}
+
+// MetricsMiddleware instruments core service by tracking request count and
+// latency.
+func MetricsMiddleware(svc rules.Service, counter metrics.Counter, latency metrics.Histogram) rules.Service {
+	return &metricsMiddleware{
+		counter: counter,
+		latency: latency,
+		svc:     svc,
+	}
+}
+
+func (ms *metricsMiddleware) CreateRule(ctx context.Context, token string, rule rules.Rule) (rules.Rule, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "create_rule").Add(1)
+		ms.latency.With("method", "create_rule").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.CreateRule(ctx, token, rule)
+}
+
+func (ms *metricsMiddleware) UpdateRule(ctx context.Context, token string, rule rules.Rule) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "update_rule").Add(1)
+		ms.latency.With("method", "update_rule").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.UpdateRule(ctx, token, rule)
+}
+
+func (ms *metricsMiddleware) ViewRule(ctx context.Context, token, id string) (rules.Rule, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_rule").Add(1)
+		ms.latency.With("method", "view_rule").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewRule(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) ListRules(ctx context.Context, token string, offset, limit uint64) (rules.RulesPage, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_rules").Add(1)
+		ms.latency.With("method", "list_rules").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListRules(ctx, token, offset, limit)
+}
+
+func (ms *metricsMiddleware) RemoveRule(ctx context.Context, token, id string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "remove_rule").Add(1)
+		ms.latency.With("method", "remove_rule").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RemoveRule(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) Evaluate(ctx context.Context, msg mainflux.Message) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "evaluate").Add(1)
+		ms.latency.With("method", "evaluate").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Evaluate(ctx, msg)
+}
+
+func (ms *metricsMiddleware) Reload(ctx context.Context) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "reload").Add(1)
+		ms.latency.With("method", "reload").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Reload(ctx)
+}
@@ -0,0 +1,103 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import "github.com/mainflux/mainflux/rules"
+
+const maxLimitSize = 100
+
+type apiReq interface {
+	validate() error
+}
+
+type conditionReq struct {
+	Type      string  `json:"type"`
+	Operator  string  `json:"operator,omitempty"`
+	Threshold float64 `json:"threshold"`
+	Days      []int   `json:"days,omitempty"`
+	Start     string  `json:"start,omitempty"`
+	End       string  `json:"end,omitempty"`
+}
+
+type actionReq struct {
+	Type       string   `json:"type"`
+	Channel    string   `json:"channel,omitempty"`
+	Subtopic   string   `json:"subtopic,omitempty"`
+	URL        string   `json:"url,omitempty"`
+	Recipients []string `json:"recipients,omitempty"`
+}
+
+type ruleReq struct {
+	token     string
+	id        string
+	Name      string       `json:"name,omitempty"`
+	Channel   string       `json:"channel"`
+	Subtopic  string       `json:"subtopic,omitempty"`
+	Field     string       `json:"field,omitempty"`
+	Condition conditionReq `json:"condition"`
+	Actions   []actionReq  `json:"actions"`
+}
+
+func (req ruleReq) validate() error {
+	if req.token == "" {
+		return rules.ErrUnauthorizedAccess
+	}
+
+	if req.Channel == "" || req.Condition.Type == "" || len(req.Actions) == 0 {
+		return rules.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type updateRuleReq struct {
+	ruleReq
+}
+
+func (req updateRuleReq) validate() error {
+	if err := req.ruleReq.validate(); err != nil {
+		return err
+	}
+
+	if req.id == "" {
+		return rules.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type viewRuleReq struct {
+	token string
+	id    string
+}
+
+func (req viewRuleReq) validate() error {
+	if req.token == "" {
+		return rules.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" {
+		return rules.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type listRulesReq struct {
+	token  string
+	offset uint64
+	limit  uint64
+}
+
+func (req listRulesReq) validate() error {
+	if req.token == "" {
+		return rules.ErrUnauthorizedAccess
+	}
+
+	if req.limit == 0 || req.limit > maxLimitSize {
+		return rules.ErrMalformedEntity
+	}
+
+	return nil
+}
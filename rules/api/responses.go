@@ -0,0 +1,100 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/mainflux/mainflux"
+)
+
+var (
+	_ mainflux.Response = (*ruleRes)(nil)
+	_ mainflux.Response = (*viewRuleRes)(nil)
+	_ mainflux.Response = (*rulesPageRes)(nil)
+	_ mainflux.Response = (*removeRes)(nil)
+)
+
+type ruleRes struct {
+	id      string
+	created bool
+}
+
+func (res ruleRes) Code() int {
+	if res.created {
+		return http.StatusCreated
+	}
+
+	return http.StatusOK
+}
+
+func (res ruleRes) Headers() map[string]string {
+	if res.created {
+		return map[string]string{
+			"Location": fmt.Sprintf("/rules/%s", res.id),
+		}
+	}
+
+	return map[string]string{}
+}
+
+func (res ruleRes) Empty() bool {
+	return true
+}
+
+type viewRuleRes struct {
+	ID        string       `json:"id"`
+	Name      string       `json:"name,omitempty"`
+	Channel   string       `json:"channel"`
+	Subtopic  string       `json:"subtopic,omitempty"`
+	Field     string       `json:"field,omitempty"`
+	Condition conditionReq `json:"condition"`
+	Actions   []actionReq  `json:"actions"`
+}
+
+func (res viewRuleRes) Code() int {
+	return http.StatusOK
+}
+
+func (res viewRuleRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res viewRuleRes) Empty() bool {
+	return false
+}
+
+type rulesPageRes struct {
+	Total  uint64        `json:"total"`
+	Offset uint64        `json:"offset"`
+	Limit  uint64        `json:"limit"`
+	Rules  []viewRuleRes `json:"rules"`
+}
+
+func (res rulesPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res rulesPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res rulesPageRes) Empty() bool {
+	return false
+}
+
+type removeRes struct{}
+
+func (res removeRes) Code() int {
+	return http.StatusNoContent
+}
+
+func (res removeRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res removeRes) Empty() bool {
+	return true
+}
@@ -0,0 +1,9 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package rules contains the domain concept definitions needed to support
+// Mainflux rules service functionality. Rules service evaluates user-defined
+// rules against the normalized messages and triggers the rule actions, such
+// as republishing the message, calling the webhook or notifying the users
+// by email or SMS.
+package rules
@@ -0,0 +1,115 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/rules"
+)
+
+var (
+	_ mainflux.MessagePublisher = (*Publisher)(nil)
+	_ rules.Caller              = (*Caller)(nil)
+	_ rules.Notifier            = (*Notifier)(nil)
+)
+
+// Publisher is an in-memory publisher which records published messages.
+type Publisher struct {
+	mu  sync.Mutex
+	out []mainflux.RawMessage
+}
+
+// NewPublisher returns publisher mock.
+func NewPublisher() *Publisher {
+	return &Publisher{}
+}
+
+// Publish records the published message.
+func (p *Publisher) Publish(_ context.Context, _ string, msg mainflux.RawMessage) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.out = append(p.out, msg)
+	return nil
+}
+
+// Published returns all the messages published so far.
+func (p *Publisher) Published() []mainflux.RawMessage {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]mainflux.RawMessage{}, p.out...)
+}
+
+// Call represents a webhook call.
+type Call struct {
+	URL     string
+	Payload []byte
+}
+
+// Caller is an in-memory webhook caller which records the calls.
+type Caller struct {
+	mu    sync.Mutex
+	calls []Call
+}
+
+// NewCaller returns webhook caller mock.
+func NewCaller() *Caller {
+	return &Caller{}
+}
+
+// Call records the webhook call.
+func (c *Caller) Call(url string, payload []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.calls = append(c.calls, Call{URL: url, Payload: payload})
+	return nil
+}
+
+// Calls returns all the webhook calls made so far.
+func (c *Caller) Calls() []Call {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]Call{}, c.calls...)
+}
+
+// Notification represents a notification sent to the recipients.
+type Notification struct {
+	To      []string
+	Subject string
+	Content string
+}
+
+// Notifier is an in-memory notifier which records the notifications.
+type Notifier struct {
+	mu  sync.Mutex
+	out []Notification
+}
+
+// NewNotifier returns notifier mock.
+func NewNotifier() *Notifier {
+	return &Notifier{}
+}
+
+// Notify records the notification.
+func (n *Notifier) Notify(to []string, subject, content string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.out = append(n.out, Notification{To: to, Subject: subject, Content: content})
+	return nil
+}
+
+// Notifications returns all the notifications sent so far.
+func (n *Notifier) Notifications() []Notification {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return append([]Notification{}, n.out...)
+}
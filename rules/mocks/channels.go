@@ -0,0 +1,26 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import "github.com/mainflux/mainflux/rules"
+
+var _ rules.Channels = (*channelsMock)(nil)
+
+type channelsMock struct {
+	channels map[string]string
+}
+
+// NewChannels creates mock of channels API. Channels are mapped to the keys
+// of the users owning them.
+func NewChannels(channels map[string]string) rules.Channels {
+	return channelsMock{channels}
+}
+
+func (cm channelsMock) Authorize(token, chanID string) error {
+	if cm.channels[chanID] != token {
+		return rules.ErrNotFound
+	}
+
+	return nil
+}
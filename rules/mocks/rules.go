@@ -0,0 +1,113 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/mainflux/mainflux/rules"
+)
+
+var _ rules.RuleRepository = (*ruleRepositoryMock)(nil)
+
+type ruleRepositoryMock struct {
+	mu    sync.Mutex
+	rules map[string]rules.Rule
+}
+
+// NewRuleRepository creates in-memory rule repository.
+func NewRuleRepository() rules.RuleRepository {
+	return &ruleRepositoryMock{
+		rules: make(map[string]rules.Rule),
+	}
+}
+
+func (rrm *ruleRepositoryMock) Save(_ context.Context, rule rules.Rule) error {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	rrm.rules[rule.ID] = rule
+	return nil
+}
+
+func (rrm *ruleRepositoryMock) Update(_ context.Context, rule rules.Rule) error {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	if _, ok := rrm.rules[rule.ID]; !ok {
+		return rules.ErrNotFound
+	}
+
+	rrm.rules[rule.ID] = rule
+	return nil
+}
+
+func (rrm *ruleRepositoryMock) RetrieveByID(_ context.Context, id string) (rules.Rule, error) {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	rule, ok := rrm.rules[id]
+	if !ok {
+		return rules.Rule{}, rules.ErrNotFound
+	}
+
+	return rule, nil
+}
+
+func (rrm *ruleRepositoryMock) RetrieveAll(_ context.Context) ([]rules.Rule, error) {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	return rrm.filter(func(rules.Rule) bool { return true }), nil
+}
+
+func (rrm *ruleRepositoryMock) RetrieveByOwner(_ context.Context, owner string, offset, limit uint64) (rules.RulesPage, error) {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	items := rrm.filter(func(r rules.Rule) bool { return r.Owner == owner })
+	page := rules.RulesPage{
+		Total:  uint64(len(items)),
+		Offset: offset,
+		Limit:  limit,
+		Rules:  []rules.Rule{},
+	}
+
+	if offset >= uint64(len(items)) {
+		return page, nil
+	}
+
+	end := offset + limit
+	if end > uint64(len(items)) {
+		end = uint64(len(items))
+	}
+	page.Rules = items[offset:end]
+
+	return page, nil
+}
+
+func (rrm *ruleRepositoryMock) Remove(_ context.Context, id string) error {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	delete(rrm.rules, id)
+	return nil
+}
+
+func (rrm *ruleRepositoryMock) filter(match func(rules.Rule) bool) []rules.Rule {
+	items := []rules.Rule{}
+	for _, r := range rrm.rules {
+		if match(r) {
+			items = append(items, r)
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].ID < items[j].ID
+	})
+
+	return items
+}
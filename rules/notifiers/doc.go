@@ -0,0 +1,6 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package notifiers contains the implementations of the rules actions which
+// call the webhooks and notify the users by email or SMS.
+package notifiers
@@ -0,0 +1,54 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package notifiers
+
+import (
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+
+	"github.com/mainflux/mainflux/rules"
+)
+
+var _ rules.Notifier = (*email)(nil)
+
+// EmailConfig contains the SMTP server address and credentials, as well as
+// the sender address of the notifications.
+type EmailConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+type email struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+// NewEmail returns notifier which sends the plain text emails through the
+// SMTP server. The server is authenticated against only if the username is
+// set.
+func NewEmail(cfg EmailConfig) rules.Notifier {
+	var auth smtp.Auth
+	if cfg.Username != "" {
+		auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+
+	return email{
+		addr: net.JoinHostPort(cfg.Host, cfg.Port),
+		auth: auth,
+		from: cfg.From,
+	}
+}
+
+func (e email) Notify(to []string, subject, content string) error {
+	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n%s\r\n",
+		e.from, strings.Join(to, ", "), subject, content)
+
+	return smtp.SendMail(e.addr, e.auth, e.from, to, []byte(msg))
+}
@@ -0,0 +1,81 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package notifiers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/mainflux/mainflux/rules"
+)
+
+var _ rules.Notifier = (*sms)(nil)
+
+type smsReq struct {
+	From string `json:"from,omitempty"`
+	To   string `json:"to"`
+	Text string `json:"text"`
+}
+
+type sms struct {
+	url    string
+	token  string
+	from   string
+	client *http.Client
+}
+
+// NewSMS returns notifier which sends the SMS through the HTTP gateway
+// located at the provided URL. Each recipient gets a separate JSON request
+// containing the sender, the recipient phone number and the text, which is
+// authorized using the bearer token, unless the token is empty.
+func NewSMS(url, token, from string, timeout time.Duration) rules.Notifier {
+	return sms{
+		url:    url,
+		token:  token,
+		from:   from,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (s sms) Notify(to []string, subject, content string) error {
+	var err error
+	for _, phone := range to {
+		if serr := s.send(phone, content); serr != nil {
+			err = serr
+		}
+	}
+
+	return err
+}
+
+func (s sms) send(phone, text string) error {
+	data, err := json.Marshal(smsReq{From: s.from, To: phone, Text: text})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.token != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.token))
+	}
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("unexpected response status: %s", res.Status)
+	}
+
+	return nil
+}
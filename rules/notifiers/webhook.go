@@ -0,0 +1,48 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package notifiers
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/rules"
+)
+
+var _ rules.Caller = (*webhook)(nil)
+
+type webhook struct {
+	client *http.Client
+}
+
+// NewWebhook returns caller which posts the SenML JSON payloads to the
+// webhooks. Any successful response status is accepted.
+func NewWebhook(timeout time.Duration) rules.Caller {
+	return webhook{
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (wh webhook) Call(url string, payload []byte) error {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", mainflux.SenMLJSON)
+
+	res, err := wh.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("unexpected response status: %s", res.Status)
+	}
+
+	return nil
+}
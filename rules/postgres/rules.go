@@ -0,0 +1,274 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq" // required for DB access
+	"github.com/mainflux/mainflux/rules"
+)
+
+const (
+	errDuplicate = "unique_violation"
+	errInvalid   = "invalid_text_representation"
+)
+
+var _ rules.RuleRepository = (*ruleRepository)(nil)
+
+type ruleRepository struct {
+	db *sqlx.DB
+}
+
+// New instantiates a PostgreSQL implementation of rule repository.
+func New(db *sqlx.DB) rules.RuleRepository {
+	return &ruleRepository{
+		db: db,
+	}
+}
+
+func (rr ruleRepository) Save(ctx context.Context, rule rules.Rule) error {
+	q := `INSERT INTO rules (id, owner, name, channel, subtopic, field, condition, actions)
+	      VALUES (:id, :owner, :name, :channel, :subtopic, :field, :condition, :actions);`
+
+	dbr, err := toDBRule(rule)
+	if err != nil {
+		return err
+	}
+
+	if _, err := rr.db.NamedExecContext(ctx, q, dbr); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == errDuplicate {
+			return rules.ErrMalformedEntity
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (rr ruleRepository) Update(ctx context.Context, rule rules.Rule) error {
+	q := `UPDATE rules SET name = :name, channel = :channel, subtopic = :subtopic, field = :field,
+	      condition = :condition, actions = :actions WHERE id = :id;`
+
+	dbr, err := toDBRule(rule)
+	if err != nil {
+		return err
+	}
+
+	res, err := rr.db.NamedExecContext(ctx, q, dbr)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == errInvalid {
+			return rules.ErrNotFound
+		}
+		return err
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if cnt == 0 {
+		return rules.ErrNotFound
+	}
+
+	return nil
+}
+
+func (rr ruleRepository) RetrieveByID(ctx context.Context, id string) (rules.Rule, error) {
+	q := `SELECT id, owner, name, channel, subtopic, field, condition, actions FROM rules WHERE id = $1;`
+
+	var dbr dbRule
+	if err := rr.db.QueryRowxContext(ctx, q, id).StructScan(&dbr); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if err == sql.ErrNoRows || ok && errInvalid == pqErr.Code.Name() {
+			return rules.Rule{}, rules.ErrNotFound
+		}
+		return rules.Rule{}, err
+	}
+
+	return toRule(dbr)
+}
+
+func (rr ruleRepository) RetrieveAll(ctx context.Context) ([]rules.Rule, error) {
+	q := `SELECT id, owner, name, channel, subtopic, field, condition, actions FROM rules;`
+
+	return rr.retrieve(ctx, q)
+}
+
+func (rr ruleRepository) RetrieveByOwner(ctx context.Context, owner string, offset, limit uint64) (rules.RulesPage, error) {
+	q := `SELECT id, owner, name, channel, subtopic, field, condition, actions FROM rules
+	      WHERE owner = $1 ORDER BY id LIMIT $2 OFFSET $3;`
+
+	items, err := rr.retrieve(ctx, q, owner, limit, offset)
+	if err != nil {
+		return rules.RulesPage{}, err
+	}
+
+	cq := `SELECT COUNT(*) FROM rules WHERE owner = $1;`
+
+	var total uint64
+	if err := rr.db.GetContext(ctx, &total, cq, owner); err != nil {
+		return rules.RulesPage{}, err
+	}
+
+	page := rules.RulesPage{
+		Total:  total,
+		Offset: offset,
+		Limit:  limit,
+		Rules:  items,
+	}
+
+	return page, nil
+}
+
+func (rr ruleRepository) Remove(ctx context.Context, id string) error {
+	q := `DELETE FROM rules WHERE id = $1;`
+
+	if _, err := rr.db.ExecContext(ctx, q, id); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == errInvalid {
+			return nil
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (rr ruleRepository) retrieve(ctx context.Context, q string, args ...interface{}) ([]rules.Rule, error) {
+	rows, err := rr.db.QueryxContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []rules.Rule{}
+	for rows.Next() {
+		var dbr dbRule
+		if err := rows.StructScan(&dbr); err != nil {
+			return nil, err
+		}
+
+		rule, err := toRule(dbr)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, rule)
+	}
+
+	return items, rows.Err()
+}
+
+type dbCondition struct {
+	Type      string  `json:"type"`
+	Operator  string  `json:"operator,omitempty"`
+	Threshold float64 `json:"threshold,omitempty"`
+	Days      []int   `json:"days,omitempty"`
+	Start     string  `json:"start,omitempty"`
+	End       string  `json:"end,omitempty"`
+}
+
+type dbAction struct {
+	Type       string   `json:"type"`
+	Channel    string   `json:"channel,omitempty"`
+	Subtopic   string   `json:"subtopic,omitempty"`
+	URL        string   `json:"url,omitempty"`
+	Recipients []string `json:"recipients,omitempty"`
+}
+
+type dbRule struct {
+	ID        string `db:"id"`
+	Owner     string `db:"owner"`
+	Name      string `db:"name"`
+	Channel   string `db:"channel"`
+	Subtopic  string `db:"subtopic"`
+	Field     string `db:"field"`
+	Condition []byte `db:"condition"`
+	Actions   []byte `db:"actions"`
+}
+
+func toDBRule(r rules.Rule) (dbRule, error) {
+	cond, err := json.Marshal(dbCondition{
+		Type:      r.Condition.Type,
+		Operator:  r.Condition.Operator,
+		Threshold: r.Condition.Threshold,
+		Days:      r.Condition.Days,
+		Start:     r.Condition.Start,
+		End:       r.Condition.End,
+	})
+	if err != nil {
+		return dbRule{}, err
+	}
+
+	actions := []dbAction{}
+	for _, a := range r.Actions {
+		actions = append(actions, dbAction{
+			Type:       a.Type,
+			Channel:    a.Channel,
+			Subtopic:   a.Subtopic,
+			URL:        a.URL,
+			Recipients: a.Recipients,
+		})
+	}
+
+	acts, err := json.Marshal(actions)
+	if err != nil {
+		return dbRule{}, err
+	}
+
+	return dbRule{
+		ID:        r.ID,
+		Owner:     r.Owner,
+		Name:      r.Name,
+		Channel:   r.Channel,
+		Subtopic:  r.Subtopic,
+		Field:     r.Field,
+		Condition: cond,
+		Actions:   acts,
+	}, nil
+}
+
+func toRule(dbr dbRule) (rules.Rule, error) {
+	var cond dbCondition
+	if err := json.Unmarshal(dbr.Condition, &cond); err != nil {
+		return rules.Rule{}, err
+	}
+
+	var actions []dbAction
+	if err := json.Unmarshal(dbr.Actions, &actions); err != nil {
+		return rules.Rule{}, err
+	}
+
+	rule := rules.Rule{
+		ID:       dbr.ID,
+		Owner:    dbr.Owner,
+		Name:     dbr.Name,
+		Channel:  dbr.Channel,
+		Subtopic: dbr.Subtopic,
+		Field:    dbr.Field,
+		Condition: rules.Condition{
+			Type:      cond.Type,
+			Operator:  cond.Operator,
+			Threshold: cond.Threshold,
+			Days:      cond.Days,
+			Start:     cond.Start,
+			End:       cond.End,
+		},
+	}
+
+	for _, a := range actions {
+		rule.Actions = append(rule.Actions, rules.Action{
+			Type:       a.Type,
+			Channel:    a.Channel,
+			Subtopic:   a.Subtopic,
+			URL:        a.URL,
+			Recipients: a.Recipients,
+		})
+	}
+
+	return rule, nil
+}
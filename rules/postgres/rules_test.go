@@ -0,0 +1,189 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux/rules"
+	"github.com/mainflux/mainflux/rules/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	id      = "123e4567-e89b-12d3-a456-000000000001"
+	otherID = "123e4567-e89b-12d3-a456-000000000002"
+	wrongID = "123e4567-e89b-12d3-a456-000000000099"
+	owner   = "user@example.com"
+)
+
+func newRule(id, owner string) rules.Rule {
+	return rules.Rule{
+		ID:       id,
+		Owner:    owner,
+		Name:     "overheating",
+		Channel:  "1",
+		Subtopic: "devices.>",
+		Field:    "temperature",
+		Condition: rules.Condition{
+			Type:      rules.Threshold,
+			Operator:  rules.OpGt,
+			Threshold: 30,
+		},
+		Actions: []rules.Action{
+			{Type: rules.Webhook, URL: "http://example.com/alarms"},
+			{Type: rules.Email, Recipients: []string{owner}},
+		},
+	}
+}
+
+func TestRuleSave(t *testing.T) {
+	repo := postgres.New(db)
+	rule := newRule(id, owner)
+
+	cases := []struct {
+		desc string
+		rule rules.Rule
+		err  error
+	}{
+		{
+			desc: "save new rule",
+			rule: rule,
+			err:  nil,
+		},
+		{
+			desc: "save duplicate rule",
+			rule: rule,
+			err:  rules.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		err := repo.Save(context.Background(), tc.rule)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	repo.Remove(context.Background(), id)
+}
+
+func TestRuleUpdate(t *testing.T) {
+	repo := postgres.New(db)
+	rule := newRule(id, owner)
+	err := repo.Save(context.Background(), rule)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	defer repo.Remove(context.Background(), id)
+
+	updated := rule
+	updated.Condition = rules.Condition{Type: rules.Schedule, Days: []int{1, 5}, Start: "08:00", End: "17:00"}
+	updated.Actions = []rules.Action{{Type: rules.Republish, Subtopic: "alarms"}}
+
+	cases := []struct {
+		desc string
+		rule rules.Rule
+		err  error
+	}{
+		{
+			desc: "update existing rule",
+			rule: updated,
+			err:  nil,
+		},
+		{
+			desc: "update non-existing rule",
+			rule: newRule(wrongID, owner),
+			err:  rules.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := repo.Update(context.Background(), tc.rule)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	saved, err := repo.RetrieveByID(context.Background(), id)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, updated, saved, fmt.Sprintf("expected %v got %v\n", updated, saved))
+}
+
+func TestRuleRetrieveByID(t *testing.T) {
+	repo := postgres.New(db)
+	rule := newRule(id, owner)
+	err := repo.Save(context.Background(), rule)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	defer repo.Remove(context.Background(), id)
+
+	cases := []struct {
+		desc string
+		id   string
+		rule rules.Rule
+		err  error
+	}{
+		{
+			desc: "retrieve existing rule",
+			id:   id,
+			rule: rule,
+			err:  nil,
+		},
+		{
+			desc: "retrieve non-existing rule",
+			id:   wrongID,
+			rule: rules.Rule{},
+			err:  rules.ErrNotFound,
+		},
+		{
+			desc: "retrieve rule with malformed ID",
+			id:   "malformed",
+			rule: rules.Rule{},
+			err:  rules.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		rule, err := repo.RetrieveByID(context.Background(), tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.rule, rule, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.rule, rule))
+	}
+}
+
+func TestRuleRetrieveByOwner(t *testing.T) {
+	repo := postgres.New(db)
+	for _, r := range []rules.Rule{newRule(id, owner), newRule(otherID, "other@example.com")} {
+		err := repo.Save(context.Background(), r)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		defer repo.Remove(context.Background(), r.ID)
+	}
+
+	cases := []struct {
+		desc  string
+		owner string
+		size  int
+		total uint64
+	}{
+		{
+			desc:  "retrieve rules of owner",
+			owner: owner,
+			size:  1,
+			total: 1,
+		},
+		{
+			desc:  "retrieve rules of owner without rules",
+			owner: "none@example.com",
+			size:  0,
+			total: 0,
+		},
+	}
+
+	for _, tc := range cases {
+		page, err := repo.RetrieveByOwner(context.Background(), tc.owner, 0, 10)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s\n", tc.desc, err))
+		assert.Equal(t, tc.size, len(page.Rules), fmt.Sprintf("%s: expected %d rules got %d\n", tc.desc, tc.size, len(page.Rules)))
+		assert.Equal(t, tc.total, page.Total, fmt.Sprintf("%s: expected total %d got %d\n", tc.desc, tc.total, page.Total))
+	}
+
+	all, err := repo.RetrieveAll(context.Background())
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, 2, len(all), fmt.Sprintf("expected 2 rules got %d\n", len(all)))
+}
@@ -0,0 +1,327 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package rules
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/mainflux/mainflux"
+)
+
+// Supported condition types.
+const (
+	// Threshold condition compares the message value with the threshold.
+	Threshold = "threshold"
+
+	// Rate condition compares the change of the value per second, between
+	// two consecutive messages of the same publisher, with the threshold.
+	Rate = "rate"
+
+	// Schedule condition is satisfied by the messages published on the
+	// given days within the given time of the day.
+	Schedule = "schedule"
+)
+
+// Supported action types.
+const (
+	// Republish action publishes the message to the Mainflux channel.
+	Republish = "republish"
+
+	// Webhook action posts the message to the HTTP endpoint.
+	Webhook = "webhook"
+
+	// Email action notifies the recipients by email.
+	Email = "email"
+
+	// SMS action notifies the recipients by SMS.
+	SMS = "sms"
+)
+
+// Supported condition operators.
+const (
+	OpEq = "eq"
+	OpNe = "ne"
+	OpGt = "gt"
+	OpGe = "ge"
+	OpLt = "lt"
+	OpLe = "le"
+)
+
+const (
+	subtopicWildcard = ">"
+	clockLayout      = "15:04"
+)
+
+var operators = map[string]func(float64, float64) bool{
+	OpEq: func(v, t float64) bool { return v == t },
+	OpNe: func(v, t float64) bool { return v != t },
+	OpGt: func(v, t float64) bool { return v > t },
+	OpGe: func(v, t float64) bool { return v >= t },
+	OpLt: func(v, t float64) bool { return v < t },
+	OpLe: func(v, t float64) bool { return v <= t },
+}
+
+// Rule represents a rule which is evaluated against the messages published
+// to the channel. Once the condition is satisfied, all the rule actions are
+// triggered. Each rule is owned by one user.
+type Rule struct {
+	ID      string
+	Owner   string
+	Name    string
+	Channel string
+
+	// Subtopic is matched exactly, unless it ends with ">" in which case
+	// it matches all the subtopics with the given prefix. Empty subtopic
+	// matches any message.
+	Subtopic string
+
+	// Field is the SenML record name. Empty field matches any record.
+	Field string
+
+	Condition Condition
+	Actions   []Action
+}
+
+// Condition describes when the rule is triggered. Operator and threshold are
+// mandatory for the threshold and rate conditions, and optional for the
+// schedule ones.
+type Condition struct {
+	Type      string
+	Operator  string
+	Threshold float64
+
+	// Days are the week days, starting with Sunday as 0, on which the
+	// schedule condition is satisfied. Empty days stand for every day.
+	Days []int
+
+	// Start and End are the UTC times of the day, formatted as HH:MM,
+	// bounding the schedule. The schedule wraps around midnight if the
+	// end precedes the start.
+	Start string
+	End   string
+}
+
+// Action describes what is done once the rule is triggered.
+type Action struct {
+	Type string
+
+	// Channel and Subtopic are where the message is republished to. Empty
+	// channel stands for the rule channel.
+	Channel  string
+	Subtopic string
+
+	// URL is the webhook endpoint.
+	URL string
+
+	// Recipients are the email addresses or the phone numbers notified.
+	Recipients []string
+}
+
+// RulesPage contains page related metadata as well as list of rules that
+// belong to this page.
+type RulesPage struct {
+	Total  uint64
+	Offset uint64
+	Limit  uint64
+	Rules  []Rule
+}
+
+// Validate returns ErrMalformedEntity if rule isn't valid.
+func (r Rule) Validate() error {
+	if r.Channel == "" || len(r.Actions) == 0 {
+		return ErrMalformedEntity
+	}
+
+	if err := r.Condition.validate(); err != nil {
+		return err
+	}
+
+	for _, a := range r.Actions {
+		if err := a.validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Matches determines whether the message is the subject of the rule.
+func (r Rule) Matches(msg mainflux.Message) bool {
+	if r.Channel != msg.Channel {
+		return false
+	}
+
+	if !matchSubtopic(r.Subtopic, msg.Subtopic) {
+		return false
+	}
+
+	return r.Field == "" || r.Field == msg.Name
+}
+
+func (c Condition) validate() error {
+	_, ok := operators[c.Operator]
+
+	switch c.Type {
+	case Threshold, Rate:
+		if !ok {
+			return ErrMalformedEntity
+		}
+	case Schedule:
+		if c.Operator != "" && !ok {
+			return ErrMalformedEntity
+		}
+
+		if _, err := time.Parse(clockLayout, c.Start); err != nil {
+			return ErrMalformedEntity
+		}
+
+		if _, err := time.Parse(clockLayout, c.End); err != nil {
+			return ErrMalformedEntity
+		}
+
+		for _, d := range c.Days {
+			if d < int(time.Sunday) || d > int(time.Saturday) {
+				return ErrMalformedEntity
+			}
+		}
+	default:
+		return ErrMalformedEntity
+	}
+
+	return nil
+}
+
+// compare compares the value with the threshold. Conditions without the
+// operator are satisfied by any value.
+func (c Condition) compare(val float64) bool {
+	if c.Operator == "" {
+		return true
+	}
+
+	op, ok := operators[c.Operator]
+	return ok && op(val, c.Threshold)
+}
+
+// scheduled determines whether the time is within the schedule.
+func (c Condition) scheduled(t time.Time) bool {
+	t = t.UTC()
+
+	if len(c.Days) > 0 {
+		day := false
+		for _, d := range c.Days {
+			if time.Weekday(d) == t.Weekday() {
+				day = true
+				break
+			}
+		}
+		if !day {
+			return false
+		}
+	}
+
+	start, err := time.Parse(clockLayout, c.Start)
+	if err != nil {
+		return false
+	}
+
+	end, err := time.Parse(clockLayout, c.End)
+	if err != nil {
+		return false
+	}
+
+	now := t.Hour()*60 + t.Minute()
+	from := start.Hour()*60 + start.Minute()
+	to := end.Hour()*60 + end.Minute()
+
+	if from <= to {
+		return now >= from && now < to
+	}
+
+	return now >= from || now < to
+}
+
+func (a Action) validate() error {
+	switch a.Type {
+	case Republish:
+		return nil
+	case Webhook:
+		if a.URL == "" {
+			return ErrMalformedEntity
+		}
+	case Email, SMS:
+		if len(a.Recipients) == 0 {
+			return ErrMalformedEntity
+		}
+	default:
+		return ErrMalformedEntity
+	}
+
+	return nil
+}
+
+func matchSubtopic(filter, subtopic string) bool {
+	if filter == "" || filter == subtopic {
+		return true
+	}
+
+	if strings.HasSuffix(filter, subtopicWildcard) {
+		return strings.HasPrefix(subtopic, strings.TrimSuffix(filter, subtopicWildcard))
+	}
+
+	return false
+}
+
+// RuleRepository specifies a rule persistence API.
+type RuleRepository interface {
+	// Save persists the rule. A non-nil error is returned to indicate
+	// operation failure.
+	Save(context.Context, Rule) error
+
+	// Update performs an update of the existing rule. A non-nil error is
+	// returned to indicate operation failure.
+	Update(context.Context, Rule) error
+
+	// RetrieveByID retrieves the rule having the provided identifier.
+	RetrieveByID(context.Context, string) (Rule, error)
+
+	// RetrieveAll retrieves all the rules. It is used to populate the
+	// set of active rules.
+	RetrieveAll(context.Context) ([]Rule, error)
+
+	// RetrieveByOwner retrieves the subset of rules owned by the specified
+	// user.
+	RetrieveByOwner(context.Context, string, uint64, uint64) (RulesPage, error)
+
+	// Remove removes the rule having the provided identifier.
+	Remove(context.Context, string) error
+}
+
+// Channels specifies an API for checking the channel ownership, so that the
+// rules can't read from or republish to the channels of other users.
+type Channels interface {
+	// Authorize returns ErrNotFound if the channel doesn't exist or isn't
+	// owned by the user identified by the provided key.
+	Authorize(token, chanID string) error
+}
+
+// Notifier specifies an API for notifying the recipients that the rule was
+// triggered, e.g. by email or SMS.
+type Notifier interface {
+	// Notify sends the content with the given subject to the recipients.
+	Notify(to []string, subject, content string) error
+}
+
+// Caller specifies an API for calling the webhooks.
+type Caller interface {
+	// Call posts the SenML JSON payload to the URL.
+	Call(url string, payload []byte) error
+}
+
+// IdentityProvider specifies an API for generating unique identifiers.
+type IdentityProvider interface {
+	// ID generates the unique identifier.
+	ID() (string, error)
+}
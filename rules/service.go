@@ -0,0 +1,476 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package rules
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cisco/senml"
+	"github.com/mainflux/mainflux"
+)
+
+// Protocol is the protocol of the messages republished by the rules. Such
+// messages aren't evaluated again, so that the rules can't loop.
+const Protocol = "rules"
+
+var (
+	// ErrMalformedEntity indicates malformed entity specification.
+	ErrMalformedEntity = errors.New("malformed entity specification")
+
+	// ErrUnauthorizedAccess indicates missing or invalid credentials provided
+	// when accessing a protected resource.
+	ErrUnauthorizedAccess = errors.New("missing or invalid credentials provided")
+
+	// ErrNotFound indicates a non-existent entity request.
+	ErrNotFound = errors.New("non-existent entity")
+
+	errUnsupportedAction = errors.New("unsupported action")
+)
+
+// Service specifies an API that must be fulfilled by the domain service
+// implementation, and all of its decorators (e.g. logging & metrics).
+type Service interface {
+	// CreateRule adds new rule to the user identified by the provided key.
+	CreateRule(context.Context, string, Rule) (Rule, error)
+
+	// ViewRule retrieves the rule identified by the provided ID, that
+	// belongs to the user identified by the provided key.
+	ViewRule(context.Context, string, string) (Rule, error)
+
+	// UpdateRule updates the rule identified by the provided ID, that
+	// belongs to the user identified by the provided key.
+	UpdateRule(context.Context, string, Rule) error
+
+	// ListRules retrieves subset of rules that belong to the user identified
+	// by the provided key.
+	ListRules(context.Context, string, uint64, uint64) (RulesPage, error)
+
+	// RemoveRule removes the rule identified by the provided ID, that
+	// belongs to the user identified by the provided key.
+	RemoveRule(context.Context, string, string) error
+
+	// Evaluate evaluates all the rules of the message channel against the
+	// message and triggers the actions of the satisfied ones.
+	Evaluate(context.Context, mainflux.Message) error
+
+	// Reload replaces the set of active rules with the persisted ones. It
+	// surpasses ownership check, since it is used to pick up the changes
+	// made through the other service instances.
+	Reload(context.Context) error
+}
+
+type sample struct {
+	value float64
+	time  time.Time
+}
+
+var _ Service = (*rulesService)(nil)
+
+type rulesService struct {
+	users     mainflux.UsersServiceClient
+	channels  Channels
+	rules     RuleRepository
+	publisher mainflux.MessagePublisher
+	caller    Caller
+	notifiers map[string]Notifier
+	idp       IdentityProvider
+
+	mu      sync.RWMutex
+	active  map[string]map[string]Rule
+	samples map[string]map[string]sample
+}
+
+// New instantiates the rules service implementation. Notifiers are keyed by
+// the type of the action they serve, so the email and SMS actions are
+// accepted only if the corresponding notifier is provided.
+func New(users mainflux.UsersServiceClient, channels Channels, rules RuleRepository, publisher mainflux.MessagePublisher, caller Caller, notifiers map[string]Notifier, idp IdentityProvider) Service {
+	return &rulesService{
+		users:     users,
+		channels:  channels,
+		rules:     rules,
+		publisher: publisher,
+		caller:    caller,
+		notifiers: notifiers,
+		idp:       idp,
+		active:    make(map[string]map[string]Rule),
+		samples:   make(map[string]map[string]sample),
+	}
+}
+
+func (rs *rulesService) CreateRule(ctx context.Context, token string, rule Rule) (Rule, error) {
+	owner, err := rs.identify(ctx, token)
+	if err != nil {
+		return Rule{}, err
+	}
+
+	if err := rs.validate(token, rule); err != nil {
+		return Rule{}, err
+	}
+
+	rule.ID, err = rs.idp.ID()
+	if err != nil {
+		return Rule{}, err
+	}
+	rule.Owner = owner
+
+	if err := rs.rules.Save(ctx, rule); err != nil {
+		return Rule{}, err
+	}
+
+	rs.activate(rule)
+	return rule, nil
+}
+
+func (rs *rulesService) ViewRule(ctx context.Context, token, id string) (Rule, error) {
+	owner, err := rs.identify(ctx, token)
+	if err != nil {
+		return Rule{}, err
+	}
+
+	rule, err := rs.rules.RetrieveByID(ctx, id)
+	if err != nil {
+		return Rule{}, err
+	}
+
+	if rule.Owner != owner {
+		return Rule{}, ErrNotFound
+	}
+
+	return rule, nil
+}
+
+func (rs *rulesService) UpdateRule(ctx context.Context, token string, rule Rule) error {
+	current, err := rs.ViewRule(ctx, token, rule.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := rs.validate(token, rule); err != nil {
+		return err
+	}
+	rule.Owner = current.Owner
+
+	if err := rs.rules.Update(ctx, rule); err != nil {
+		return err
+	}
+
+	rs.activate(rule)
+	return nil
+}
+
+func (rs *rulesService) ListRules(ctx context.Context, token string, offset, limit uint64) (RulesPage, error) {
+	owner, err := rs.identify(ctx, token)
+	if err != nil {
+		return RulesPage{}, err
+	}
+
+	return rs.rules.RetrieveByOwner(ctx, owner, offset, limit)
+}
+
+func (rs *rulesService) RemoveRule(ctx context.Context, token, id string) error {
+	if _, err := rs.ViewRule(ctx, token, id); err != nil {
+		return err
+	}
+
+	if err := rs.rules.Remove(ctx, id); err != nil {
+		return err
+	}
+
+	rs.deactivate(id)
+	return nil
+}
+
+func (rs *rulesService) Evaluate(ctx context.Context, msg mainflux.Message) error {
+	if msg.Protocol == Protocol {
+		return nil
+	}
+
+	rs.mu.RLock()
+	rules := make([]Rule, 0, len(rs.active[msg.Channel]))
+	for _, r := range rs.active[msg.Channel] {
+		rules = append(rules, r)
+	}
+	rs.mu.RUnlock()
+
+	t := time.Unix(0, int64(msg.Time*float64(time.Second)))
+
+	var err error
+	for _, r := range rules {
+		if !r.Matches(msg) || !rs.satisfied(r, msg, t) {
+			continue
+		}
+
+		// Failure of a single action must not prevent other actions
+		// from being triggered.
+		for _, a := range r.Actions {
+			if aerr := rs.act(ctx, r, a, msg); aerr != nil {
+				err = aerr
+			}
+		}
+	}
+
+	return err
+}
+
+func (rs *rulesService) Reload(ctx context.Context) error {
+	rules, err := rs.rules.RetrieveAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	active := make(map[string]map[string]Rule)
+	for _, r := range rules {
+		if _, ok := active[r.Channel]; !ok {
+			active[r.Channel] = make(map[string]Rule)
+		}
+		active[r.Channel][r.ID] = r
+	}
+
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	rs.active = active
+	for id := range rs.samples {
+		if !isActive(active, id) {
+			delete(rs.samples, id)
+		}
+	}
+
+	return nil
+}
+
+func (rs *rulesService) identify(ctx context.Context, token string) (string, error) {
+	res, err := rs.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return "", ErrUnauthorizedAccess
+	}
+
+	return res.GetValue(), nil
+}
+
+// validate checks that the rule is valid, that the actions are supported by
+// this instance, and that the user owns the channels the rule reads from and
+// republishes to.
+func (rs *rulesService) validate(token string, rule Rule) error {
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+
+	chans := map[string]bool{rule.Channel: true}
+	for _, a := range rule.Actions {
+		switch a.Type {
+		case Email, SMS:
+			if _, ok := rs.notifiers[a.Type]; !ok {
+				return ErrMalformedEntity
+			}
+		case Republish:
+			if a.Channel != "" {
+				chans[a.Channel] = true
+			}
+		}
+	}
+
+	for ch := range chans {
+		if err := rs.channels.Authorize(token, ch); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// satisfied determines whether the message satisfies the rule condition.
+// Only the numeric values are compared with the threshold.
+func (rs *rulesService) satisfied(r Rule, msg mainflux.Message, t time.Time) bool {
+	val, ok := msg.Value.(*mainflux.Message_FloatValue)
+
+	switch r.Condition.Type {
+	case Threshold:
+		return ok && r.Condition.compare(val.FloatValue)
+	case Rate:
+		if !ok {
+			return false
+		}
+		rate, ok := rs.rate(r.ID, fmt.Sprintf("%s:%s", msg.Publisher, msg.Name), val.FloatValue, t)
+		return ok && r.Condition.compare(rate)
+	case Schedule:
+		if !r.Condition.scheduled(t) {
+			return false
+		}
+		return r.Condition.Operator == "" || ok && r.Condition.compare(val.FloatValue)
+	default:
+		return false
+	}
+}
+
+// rate returns the change of the value per second since the previous sample
+// of the same series. The first sample and the samples older than the
+// previous one don't have the rate.
+func (rs *rulesService) rate(id, series string, val float64, t time.Time) (float64, bool) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	if _, ok := rs.samples[id]; !ok {
+		rs.samples[id] = make(map[string]sample)
+	}
+
+	prev, ok := rs.samples[id][series]
+	if ok && !t.After(prev.time) {
+		return 0, false
+	}
+	rs.samples[id][series] = sample{value: val, time: t}
+
+	if !ok {
+		return 0, false
+	}
+
+	return (val - prev.value) / t.Sub(prev.time).Seconds(), true
+}
+
+func (rs *rulesService) act(ctx context.Context, r Rule, a Action, msg mainflux.Message) error {
+	switch a.Type {
+	case Republish:
+		payload, err := encode(msg, "")
+		if err != nil {
+			return err
+		}
+
+		ch := a.Channel
+		if ch == "" {
+			ch = r.Channel
+		}
+
+		out := mainflux.RawMessage{
+			Channel:     ch,
+			Subtopic:    a.Subtopic,
+			Publisher:   msg.Publisher,
+			Protocol:    Protocol,
+			ContentType: mainflux.SenMLJSON,
+			Payload:     payload,
+		}
+		return rs.publisher.Publish(ctx, "", out)
+	case Webhook:
+		payload, err := encode(msg, msg.Publisher)
+		if err != nil {
+			return err
+		}
+		return rs.caller.Call(a.URL, payload)
+	case Email, SMS:
+		n, ok := rs.notifiers[a.Type]
+		if !ok {
+			return errUnsupportedAction
+		}
+		return n.Notify(a.Recipients, subject(r), content(r, msg))
+	default:
+		return errUnsupportedAction
+	}
+}
+
+func (rs *rulesService) activate(rule Rule) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	// Rule channel may be changed, so make sure there are no stale copies.
+	for ch, rules := range rs.active {
+		if _, ok := rules[rule.ID]; ok && ch != rule.Channel {
+			delete(rules, rule.ID)
+			if len(rules) == 0 {
+				delete(rs.active, ch)
+			}
+		}
+	}
+
+	if _, ok := rs.active[rule.Channel]; !ok {
+		rs.active[rule.Channel] = make(map[string]Rule)
+	}
+	rs.active[rule.Channel][rule.ID] = rule
+	delete(rs.samples, rule.ID)
+}
+
+func (rs *rulesService) deactivate(id string) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	for ch, rules := range rs.active {
+		delete(rules, id)
+		if len(rules) == 0 {
+			delete(rs.active, ch)
+		}
+	}
+	delete(rs.samples, id)
+}
+
+func isActive(active map[string]map[string]Rule, id string) bool {
+	for _, rules := range active {
+		if _, ok := rules[id]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func subject(r Rule) string {
+	name := r.Name
+	if name == "" {
+		name = r.ID
+	}
+
+	return fmt.Sprintf("Rule %s triggered", name)
+}
+
+func content(r Rule, msg mainflux.Message) string {
+	var val interface{}
+	switch v := msg.Value.(type) {
+	case *mainflux.Message_FloatValue:
+		val = v.FloatValue
+	case *mainflux.Message_StringValue:
+		val = v.StringValue
+	case *mainflux.Message_BoolValue:
+		val = v.BoolValue
+	case *mainflux.Message_DataValue:
+		val = v.DataValue
+	}
+
+	t := time.Unix(0, int64(msg.Time*float64(time.Second))).UTC()
+	return fmt.Sprintf("%s: %s = %v %s published by %s to channel %s at %s.",
+		subject(r), msg.Name, val, msg.Unit, msg.Publisher, msg.Channel, t.Format(time.RFC3339))
+}
+
+// encode converts normalized message back to the SenML JSON, so that the
+// consumers don't depend on the Mainflux internal representation. Republished
+// messages are encoded without the base name, since the normalizer would
+// prepend it to the record name again.
+func encode(msg mainflux.Message, baseName string) ([]byte, error) {
+	rec := senml.SenMLRecord{
+		BaseName:   baseName,
+		Name:       msg.Name,
+		Unit:       msg.Unit,
+		Time:       msg.Time,
+		UpdateTime: msg.UpdateTime,
+		Link:       msg.Link,
+	}
+
+	switch v := msg.Value.(type) {
+	case *mainflux.Message_FloatValue:
+		rec.Value = &v.FloatValue
+	case *mainflux.Message_StringValue:
+		rec.StringValue = v.StringValue
+	case *mainflux.Message_BoolValue:
+		rec.BoolValue = &v.BoolValue
+	case *mainflux.Message_DataValue:
+		rec.DataValue = v.DataValue
+	}
+
+	if msg.ValueSum != nil {
+		rec.Sum = &msg.ValueSum.Value
+	}
+
+	s := senml.SenML{Records: []senml.SenMLRecord{rec}}
+	return senml.Encode(s, senml.JSON, senml.OutputOptions{})
+}
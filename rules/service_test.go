@@ -0,0 +1,555 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package rules_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/rules"
+	"github.com/mainflux/mainflux/rules/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	wrongValue = "wrong-value"
+	email      = "user@example.com"
+	token      = "token"
+	otherEmail = "other@example.com"
+	otherToken = "other"
+	chanID     = "1"
+	otherChan  = "2"
+)
+
+var rule = rules.Rule{
+	Name:     "overheating",
+	Channel:  chanID,
+	Subtopic: "temp.>",
+	Field:    "temperature",
+	Condition: rules.Condition{
+		Type:      rules.Threshold,
+		Operator:  rules.OpGt,
+		Threshold: 30,
+	},
+	Actions: []rules.Action{
+		{Type: rules.Republish, Subtopic: "alarms"},
+		{Type: rules.Webhook, URL: "http://example.com/alarms"},
+		{Type: rules.Email, Recipients: []string{email}},
+	},
+}
+
+type actions struct {
+	publisher *mocks.Publisher
+	caller    *mocks.Caller
+	notifier  *mocks.Notifier
+}
+
+func newService() (rules.Service, rules.RuleRepository, actions) {
+	users := mocks.NewUsersService(map[string]string{token: email, otherToken: otherEmail})
+	channels := mocks.NewChannels(map[string]string{chanID: token, otherChan: otherToken})
+	repo := mocks.NewRuleRepository()
+	acts := actions{
+		publisher: mocks.NewPublisher(),
+		caller:    mocks.NewCaller(),
+		notifier:  mocks.NewNotifier(),
+	}
+	notifiers := map[string]rules.Notifier{rules.Email: acts.notifier}
+
+	svc := rules.New(users, channels, repo, acts.publisher, acts.caller, notifiers, mocks.NewIdentityProvider())
+	return svc, repo, acts
+}
+
+func TestCreateRule(t *testing.T) {
+	svc, _, _ := newService()
+
+	invalidCondition := rule
+	invalidCondition.Condition = rules.Condition{Type: "unknown"}
+
+	invalidOperator := rule
+	invalidOperator.Condition = rules.Condition{Type: rules.Rate, Operator: "unknown"}
+
+	invalidSchedule := rule
+	invalidSchedule.Condition = rules.Condition{Type: rules.Schedule, Days: []int{7}, Start: "08:00", End: "17:00"}
+
+	invalidWindow := rule
+	invalidWindow.Condition = rules.Condition{Type: rules.Schedule, Start: "8am", End: "17:00"}
+
+	invalidWebhook := rule
+	invalidWebhook.Actions = []rules.Action{{Type: rules.Webhook}}
+
+	unsupported := rule
+	unsupported.Actions = []rules.Action{{Type: rules.SMS, Recipients: []string{"+38160000000"}}}
+
+	noActions := rule
+	noActions.Actions = nil
+
+	otherChannel := rule
+	otherChannel.Channel = otherChan
+
+	republishOther := rule
+	republishOther.Actions = []rules.Action{{Type: rules.Republish, Channel: otherChan}}
+
+	cases := []struct {
+		desc  string
+		rule  rules.Rule
+		token string
+		err   error
+	}{
+		{
+			desc:  "create valid rule",
+			rule:  rule,
+			token: token,
+			err:   nil,
+		},
+		{
+			desc:  "create rule with wrong credentials",
+			rule:  rule,
+			token: wrongValue,
+			err:   rules.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "create rule with unknown condition type",
+			rule:  invalidCondition,
+			token: token,
+			err:   rules.ErrMalformedEntity,
+		},
+		{
+			desc:  "create rule with unknown operator",
+			rule:  invalidOperator,
+			token: token,
+			err:   rules.ErrMalformedEntity,
+		},
+		{
+			desc:  "create rule with invalid schedule day",
+			rule:  invalidSchedule,
+			token: token,
+			err:   rules.ErrMalformedEntity,
+		},
+		{
+			desc:  "create rule with invalid schedule window",
+			rule:  invalidWindow,
+			token: token,
+			err:   rules.ErrMalformedEntity,
+		},
+		{
+			desc:  "create rule with webhook without URL",
+			rule:  invalidWebhook,
+			token: token,
+			err:   rules.ErrMalformedEntity,
+		},
+		{
+			desc:  "create rule with unsupported action",
+			rule:  unsupported,
+			token: token,
+			err:   rules.ErrMalformedEntity,
+		},
+		{
+			desc:  "create rule without actions",
+			rule:  noActions,
+			token: token,
+			err:   rules.ErrMalformedEntity,
+		},
+		{
+			desc:  "create rule of channel owned by other user",
+			rule:  otherChannel,
+			token: token,
+			err:   rules.ErrNotFound,
+		},
+		{
+			desc:  "create rule republishing to channel owned by other user",
+			rule:  republishOther,
+			token: token,
+			err:   rules.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := svc.CreateRule(context.Background(), tc.token, tc.rule)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestViewRule(t *testing.T) {
+	svc, _, _ := newService()
+	saved, err := svc.CreateRule(context.Background(), token, rule)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc  string
+		id    string
+		token string
+		err   error
+	}{
+		{
+			desc:  "view existing rule",
+			id:    saved.ID,
+			token: token,
+			err:   nil,
+		},
+		{
+			desc:  "view rule with wrong credentials",
+			id:    saved.ID,
+			token: wrongValue,
+			err:   rules.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "view rule owned by other user",
+			id:    saved.ID,
+			token: otherToken,
+			err:   rules.ErrNotFound,
+		},
+		{
+			desc:  "view non-existing rule",
+			id:    wrongValue,
+			token: token,
+			err:   rules.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := svc.ViewRule(context.Background(), tc.token, tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestUpdateRule(t *testing.T) {
+	svc, _, acts := newService()
+	saved, err := svc.CreateRule(context.Background(), token, rule)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	updated := saved
+	updated.Condition.Threshold = 40
+
+	invalid := saved
+	invalid.Condition.Operator = "unknown"
+
+	nonExisting := saved
+	nonExisting.ID = wrongValue
+
+	cases := []struct {
+		desc  string
+		rule  rules.Rule
+		token string
+		err   error
+	}{
+		{
+			desc:  "update existing rule",
+			rule:  updated,
+			token: token,
+			err:   nil,
+		},
+		{
+			desc:  "update rule with wrong credentials",
+			rule:  updated,
+			token: wrongValue,
+			err:   rules.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "update rule owned by other user",
+			rule:  updated,
+			token: otherToken,
+			err:   rules.ErrNotFound,
+		},
+		{
+			desc:  "update rule with invalid condition",
+			rule:  invalid,
+			token: token,
+			err:   rules.ErrMalformedEntity,
+		},
+		{
+			desc:  "update non-existing rule",
+			rule:  nonExisting,
+			token: token,
+			err:   rules.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.UpdateRule(context.Background(), tc.token, tc.rule)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	err = svc.Evaluate(context.Background(), message(35, 0))
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.Empty(t, acts.caller.Calls(), "updated rule must not be triggered below the new threshold")
+}
+
+func TestListRules(t *testing.T) {
+	svc, _, _ := newService()
+	n := uint64(5)
+	for i := uint64(0); i < n; i++ {
+		_, err := svc.CreateRule(context.Background(), token, rule)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	}
+
+	cases := []struct {
+		desc   string
+		token  string
+		offset uint64
+		limit  uint64
+		size   int
+		err    error
+	}{
+		{
+			desc:   "list all rules",
+			token:  token,
+			offset: 0,
+			limit:  n,
+			size:   int(n),
+		},
+		{
+			desc:   "list last rule",
+			token:  token,
+			offset: n - 1,
+			limit:  n,
+			size:   1,
+		},
+		{
+			desc:   "list rules of user without rules",
+			token:  otherToken,
+			offset: 0,
+			limit:  n,
+			size:   0,
+		},
+		{
+			desc:   "list rules with wrong credentials",
+			token:  wrongValue,
+			offset: 0,
+			limit:  n,
+			size:   0,
+			err:    rules.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		page, err := svc.ListRules(context.Background(), tc.token, tc.offset, tc.limit)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.size, len(page.Rules), fmt.Sprintf("%s: expected %d got %d\n", tc.desc, tc.size, len(page.Rules)))
+	}
+}
+
+func TestRemoveRule(t *testing.T) {
+	svc, _, acts := newService()
+	saved, err := svc.CreateRule(context.Background(), token, rule)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	err = svc.RemoveRule(context.Background(), wrongValue, saved.ID)
+	assert.Equal(t, rules.ErrUnauthorizedAccess, err, fmt.Sprintf("remove rule with wrong credentials: expected %s got %s\n", rules.ErrUnauthorizedAccess, err))
+
+	err = svc.RemoveRule(context.Background(), token, saved.ID)
+	assert.Nil(t, err, fmt.Sprintf("remove existing rule: unexpected error: %s\n", err))
+
+	err = svc.Evaluate(context.Background(), message(35, 0))
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.Empty(t, acts.caller.Calls(), "removed rule must not be triggered")
+}
+
+func TestEvaluateThreshold(t *testing.T) {
+	svc, _, acts := newService()
+	_, err := svc.CreateRule(context.Background(), token, rule)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	otherSubtopic := message(35, 0)
+	otherSubtopic.Subtopic = "humidity"
+
+	otherField := message(35, 0)
+	otherField.Name = "humidity"
+
+	republished := message(35, 0)
+	republished.Protocol = rules.Protocol
+
+	cases := []struct {
+		desc      string
+		msg       mainflux.Message
+		triggered int
+	}{
+		{
+			desc:      "trigger rule by message above threshold",
+			msg:       message(35, 0),
+			triggered: 1,
+		},
+		{
+			desc:      "skip message below threshold",
+			msg:       message(25, 0),
+			triggered: 0,
+		},
+		{
+			desc:      "skip message with non-matching subtopic",
+			msg:       otherSubtopic,
+			triggered: 0,
+		},
+		{
+			desc:      "skip message with non-matching field",
+			msg:       otherField,
+			triggered: 0,
+		},
+		{
+			desc:      "skip message republished by rule",
+			msg:       republished,
+			triggered: 0,
+		},
+	}
+
+	for _, tc := range cases {
+		before := len(acts.caller.Calls())
+		err := svc.Evaluate(context.Background(), tc.msg)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s\n", tc.desc, err))
+		assert.Equal(t, tc.triggered, len(acts.caller.Calls())-before, fmt.Sprintf("%s: expected %d triggers\n", tc.desc, tc.triggered))
+	}
+
+	pub := acts.publisher.Published()
+	require.Len(t, pub, 1, "expected republished message")
+	assert.Equal(t, chanID, pub[0].Channel, fmt.Sprintf("expected channel %s got %s\n", chanID, pub[0].Channel))
+	assert.Equal(t, "alarms", pub[0].Subtopic, fmt.Sprintf("expected subtopic alarms got %s\n", pub[0].Subtopic))
+	assert.Equal(t, rules.Protocol, pub[0].Protocol, fmt.Sprintf("expected protocol %s got %s\n", rules.Protocol, pub[0].Protocol))
+
+	calls := acts.caller.Calls()
+	require.Len(t, calls, 1, "expected webhook call")
+	assert.Equal(t, "http://example.com/alarms", calls[0].URL, fmt.Sprintf("expected webhook URL http://example.com/alarms got %s\n", calls[0].URL))
+
+	notifications := acts.notifier.Notifications()
+	require.Len(t, notifications, 1, "expected email notification")
+	assert.Equal(t, []string{email}, notifications[0].To, fmt.Sprintf("expected recipients %v got %v\n", []string{email}, notifications[0].To))
+}
+
+func TestEvaluateRate(t *testing.T) {
+	svc, _, acts := newService()
+	r := rule
+	r.Condition = rules.Condition{Type: rules.Rate, Operator: rules.OpGt, Threshold: 1}
+	r.Actions = []rules.Action{{Type: rules.Webhook, URL: "http://example.com/alarms"}}
+	_, err := svc.CreateRule(context.Background(), token, r)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	otherPublisher := message(100, 30)
+	otherPublisher.Publisher = "other-device"
+
+	cases := []struct {
+		desc      string
+		msg       mainflux.Message
+		triggered int
+	}{
+		{
+			desc:      "skip first message of series",
+			msg:       message(20, 0),
+			triggered: 0,
+		},
+		{
+			desc:      "skip message with slow change",
+			msg:       message(25, 10),
+			triggered: 0,
+		},
+		{
+			desc:      "trigger rule by message with fast change",
+			msg:       message(50, 20),
+			triggered: 1,
+		},
+		{
+			desc:      "skip out of order message",
+			msg:       message(100, 15),
+			triggered: 0,
+		},
+		{
+			desc:      "skip first message of other publisher",
+			msg:       otherPublisher,
+			triggered: 0,
+		},
+	}
+
+	for _, tc := range cases {
+		before := len(acts.caller.Calls())
+		err := svc.Evaluate(context.Background(), tc.msg)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s\n", tc.desc, err))
+		assert.Equal(t, tc.triggered, len(acts.caller.Calls())-before, fmt.Sprintf("%s: expected %d triggers\n", tc.desc, tc.triggered))
+	}
+}
+
+func TestEvaluateSchedule(t *testing.T) {
+	svc, _, acts := newService()
+	r := rule
+	r.Condition = rules.Condition{Type: rules.Schedule, Days: []int{int(time.Monday)}, Start: "22:00", End: "06:00"}
+	r.Actions = []rules.Action{{Type: rules.Webhook, URL: "http://example.com/alarms"}}
+	_, err := svc.CreateRule(context.Background(), token, r)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	// 2020-01-06 is Monday.
+	monday := time.Date(2020, 1, 6, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		desc      string
+		time      time.Time
+		triggered int
+	}{
+		{
+			desc:      "trigger rule by message within schedule",
+			time:      monday.Add(23 * time.Hour),
+			triggered: 1,
+		},
+		{
+			desc:      "trigger rule by message after midnight within schedule",
+			time:      monday.Add(time.Hour),
+			triggered: 1,
+		},
+		{
+			desc:      "skip message outside of schedule",
+			time:      monday.Add(12 * time.Hour),
+			triggered: 0,
+		},
+		{
+			desc:      "skip message on other day",
+			time:      monday.Add(47 * time.Hour),
+			triggered: 0,
+		},
+	}
+
+	for _, tc := range cases {
+		msg := message(20, 0)
+		msg.Time = float64(tc.time.Unix())
+
+		before := len(acts.caller.Calls())
+		err := svc.Evaluate(context.Background(), msg)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s\n", tc.desc, err))
+		assert.Equal(t, tc.triggered, len(acts.caller.Calls())-before, fmt.Sprintf("%s: expected %d triggers\n", tc.desc, tc.triggered))
+	}
+}
+
+func TestReload(t *testing.T) {
+	svc, repo, acts := newService()
+
+	// Rule created by another service instance is only persisted.
+	r := rule
+	r.ID = "rule"
+	r.Owner = email
+	err := repo.Save(context.Background(), r)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	err = svc.Reload(context.Background())
+	assert.Nil(t, err, fmt.Sprintf("reload created rule: unexpected error: %s\n", err))
+	err = svc.Evaluate(context.Background(), message(35, 0))
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.Len(t, acts.caller.Calls(), 1, "reloaded rule must be triggered")
+
+	err = repo.Remove(context.Background(), r.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	err = svc.Reload(context.Background())
+	assert.Nil(t, err, fmt.Sprintf("reload removed rule: unexpected error: %s\n", err))
+	err = svc.Evaluate(context.Background(), message(35, 0))
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.Len(t, acts.caller.Calls(), 1, "removed rule must not be triggered")
+}
+
+func message(val, t float64) mainflux.Message {
+	return mainflux.Message{
+		Channel:   chanID,
+		Subtopic:  "temp.room",
+		Publisher: "device",
+		Name:      "temperature",
+		Time:      t,
+		Value:     &mainflux.Message_FloatValue{FloatValue: val},
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/mainflux/mainflux/sandbox"
+)
+
+func createSandboxEndpoint(svc sandbox.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(createSandboxReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		duration := time.Duration(req.Hours) * time.Hour
+		sb, err := svc.Create(ctx, duration, req.Things)
+		if err != nil {
+			return nil, err
+		}
+
+		things := []thingRes{}
+		for _, th := range sb.Things {
+			things = append(things, thingRes{ID: th.ID, Key: th.Key})
+		}
+
+		res := createSandboxRes{
+			ID:       sb.ID,
+			Email:    sb.Email,
+			Password: sb.Password,
+			Token:    sb.Token,
+			Channel:  sb.Channel,
+			Things:   things,
+			Expires:  sb.Expires,
+		}
+
+		return res, nil
+	}
+}
+
+func viewSandboxEndpoint(svc sandbox.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewSandboxReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		sb, err := svc.View(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		res := viewSandboxRes{
+			ID:      sb.ID,
+			Email:   sb.Email,
+			Created: sb.Created,
+			Expires: sb.Expires,
+		}
+
+		return res, nil
+	}
+}
+
+func removeSandboxEndpoint(svc sandbox.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewSandboxReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.Remove(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
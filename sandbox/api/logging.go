@@ -0,0 +1,80 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !test
+// +build !test
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/sandbox"
+)
+
+var _ sandbox.Service = (*loggingMiddleware)(nil)
+
+type loggingMiddleware struct {
+	logger log.Logger
+	svc    sandbox.Service
+}
+
+// LoggingMiddleware adds logging facilities to the core service.
+func LoggingMiddleware(svc sandbox.Service, logger log.Logger) sandbox.Service {
+	return &loggingMiddleware{logger, svc}
+}
+
+func (lm *loggingMiddleware) Create(ctx context.Context, duration time.Duration, things uint64) (sb sandbox.Sandbox, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method create for sandbox %s with %d things expiring in %s took %s to complete", sb.ID, things, duration, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Create(ctx, duration, things)
+}
+
+func (lm *loggingMiddleware) View(ctx context.Context, token, id string) (sb sandbox.Sandbox, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method view for token %s and sandbox %s took %s to complete", token, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.View(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) Remove(ctx context.Context, token, id string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method remove for token %s and sandbox %s took %s to complete", token, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Remove(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) Cleanup(ctx context.Context) (removed uint64, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method cleanup removed %d sandboxes and took %s to complete", removed, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Cleanup(ctx)
+}
@@ -0,0 +1,69 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !test
+// +build !test
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/mainflux/mainflux/sandbox"
+)
+
+var _ sandbox.Service = (*metricsMiddleware)(nil)
+
+type metricsMiddleware struct {
+	counter metrics.Counter
+	latency metrics.Histogram
+	svc     sandbox.Service
+}
+
+// MetricsMiddleware instruments core service by tracking request count and
+// latency.
+func MetricsMiddleware(svc sandbox.Service, counter metrics.Counter, latency metrics.Histogram) sandbox.Service {
+	return &metricsMiddleware{
+		counter: counter,
+		latency: latency,
+		svc:     svc,
+	}
+}
+
+func (ms *metricsMiddleware) Create(ctx context.Context, duration time.Duration, things uint64) (sandbox.Sandbox, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "create").Add(1)
+		ms.latency.With("method", "create").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Create(ctx, duration, things)
+}
+
+func (ms *metricsMiddleware) View(ctx context.Context, token, id string) (sandbox.Sandbox, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view").Add(1)
+		ms.latency.With("method", "view").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.View(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) Remove(ctx context.Context, token, id string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "remove").Add(1)
+		ms.latency.With("method", "remove").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Remove(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) Cleanup(ctx context.Context) (uint64, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "cleanup").Add(1)
+		ms.latency.With("method", "cleanup").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Cleanup(ctx)
+}
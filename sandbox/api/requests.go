@@ -0,0 +1,36 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import "github.com/mainflux/mainflux/sandbox"
+
+type apiReq interface {
+	validate() error
+}
+
+type createSandboxReq struct {
+	Hours  uint64 `json:"hours,omitempty"`
+	Things uint64 `json:"things,omitempty"`
+}
+
+func (req createSandboxReq) validate() error {
+	return nil
+}
+
+type viewSandboxReq struct {
+	token string
+	id    string
+}
+
+func (req viewSandboxReq) validate() error {
+	if req.token == "" {
+		return sandbox.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" {
+		return sandbox.ErrMalformedEntity
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/mainflux/mainflux"
+)
+
+var (
+	_ mainflux.Response = (*createSandboxRes)(nil)
+	_ mainflux.Response = (*viewSandboxRes)(nil)
+	_ mainflux.Response = (*removeRes)(nil)
+)
+
+type thingRes struct {
+	ID  string `json:"id"`
+	Key string `json:"key"`
+}
+
+type createSandboxRes struct {
+	ID       string     `json:"id"`
+	Email    string     `json:"email"`
+	Password string     `json:"password"`
+	Token    string     `json:"token"`
+	Channel  string     `json:"channel"`
+	Things   []thingRes `json:"things"`
+	Expires  time.Time  `json:"expires_at"`
+}
+
+func (res createSandboxRes) Code() int {
+	return http.StatusCreated
+}
+
+func (res createSandboxRes) Headers() map[string]string {
+	return map[string]string{
+		"Location": fmt.Sprintf("/sandboxes/%s", res.ID),
+	}
+}
+
+func (res createSandboxRes) Empty() bool {
+	return false
+}
+
+type viewSandboxRes struct {
+	ID      string    `json:"id"`
+	Email   string    `json:"email"`
+	Created time.Time `json:"created_at"`
+	Expires time.Time `json:"expires_at"`
+}
+
+func (res viewSandboxRes) Code() int {
+	return http.StatusOK
+}
+
+func (res viewSandboxRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res viewSandboxRes) Empty() bool {
+	return false
+}
+
+type removeRes struct{}
+
+func (res removeRes) Code() int {
+	return http.StatusNoContent
+}
+
+func (res removeRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res removeRes) Empty() bool {
+	return true
+}
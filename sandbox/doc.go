@@ -0,0 +1,9 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package sandbox contains the domain concept definitions needed to support
+// Mainflux sandbox service functionality. Sandbox service creates the
+// time-boxed tenants for workshops and trials, i.e. the user accounts
+// together with their things and channels, which are removed once the
+// sandbox expires.
+package sandbox
@@ -0,0 +1,87 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mainflux/mainflux/sandbox"
+)
+
+var _ sandbox.Repository = (*repositoryMock)(nil)
+
+type repositoryMock struct {
+	mu        sync.Mutex
+	sandboxes map[string]sandbox.Sandbox
+}
+
+// NewRepository creates in-memory sandbox repository.
+func NewRepository() sandbox.Repository {
+	return &repositoryMock{
+		sandboxes: make(map[string]sandbox.Sandbox),
+	}
+}
+
+func (repo *repositoryMock) Save(_ context.Context, sb sandbox.Sandbox) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	sb.Token = ""
+	sb.Channel = ""
+	sb.Things = nil
+	repo.sandboxes[sb.ID] = sb
+	return nil
+}
+
+func (repo *repositoryMock) RetrieveByID(_ context.Context, id string) (sandbox.Sandbox, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	sb, ok := repo.sandboxes[id]
+	if !ok {
+		return sandbox.Sandbox{}, sandbox.ErrNotFound
+	}
+
+	return sb, nil
+}
+
+func (repo *repositoryMock) RetrieveExpired(_ context.Context, t time.Time, limit uint64) ([]sandbox.Sandbox, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	var sbs []sandbox.Sandbox
+	for _, sb := range repo.sandboxes {
+		if sb.Expires.Before(t) {
+			sbs = append(sbs, sb)
+		}
+	}
+
+	sort.Slice(sbs, func(i, j int) bool {
+		return sbs[i].Expires.Before(sbs[j].Expires)
+	})
+
+	if uint64(len(sbs)) > limit {
+		sbs = sbs[:limit]
+	}
+
+	return sbs, nil
+}
+
+func (repo *repositoryMock) Count(context.Context) (uint64, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	return uint64(len(repo.sandboxes)), nil
+}
+
+func (repo *repositoryMock) Remove(_ context.Context, id string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	delete(repo.sandboxes, id)
+	return nil
+}
@@ -0,0 +1,120 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mainflux/mainflux/sandbox"
+)
+
+var _ sandbox.Things = (*Things)(nil)
+
+// Things is the mock of the things service, which expects the user email as
+// the access token.
+type Things struct {
+	mu       sync.Mutex
+	counter  int
+	quotas   map[string]sandbox.Quota
+	things   map[string][]sandbox.Thing
+	channels map[string][]string
+	conns    map[string]int
+}
+
+// NewThings creates mock of things service.
+func NewThings() *Things {
+	return &Things{
+		quotas:   make(map[string]sandbox.Quota),
+		things:   make(map[string][]sandbox.Thing),
+		channels: make(map[string][]string),
+		conns:    make(map[string]int),
+	}
+}
+
+// SetQuota overrides the quota of the owner.
+func (t *Things) SetQuota(owner string, quota sandbox.Quota) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.quotas[owner] = quota
+	return nil
+}
+
+// RemoveQuota removes the quota of the owner.
+func (t *Things) RemoveQuota(owner string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.quotas, owner)
+	return nil
+}
+
+// CreateChannel creates the channel of the user.
+func (t *Things) CreateChannel(token, name string, retention time.Duration) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.counter++
+	id := fmt.Sprintf("%s%012d", "123e4567-e89b-12d3-a456-", t.counter)
+	t.channels[token] = append(t.channels[token], id)
+	return id, nil
+}
+
+// CreateThing creates the thing of the user.
+func (t *Things) CreateThing(token, name string) (sandbox.Thing, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.counter++
+	th := sandbox.Thing{
+		ID:  fmt.Sprintf("%s%012d", "123e4567-e89b-12d3-a456-", t.counter),
+		Key: fmt.Sprintf("%s%012d", "423e4567-e89b-12d3-a456-", t.counter),
+	}
+	t.things[token] = append(t.things[token], th)
+	return th, nil
+}
+
+// Connect connects the thing to the channel of the user.
+func (t *Things) Connect(token, thingID, chanID string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.conns[token]++
+	return nil
+}
+
+// Clear removes the things and channels of the user.
+func (t *Things) Clear(token string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.things, token)
+	delete(t.channels, token)
+	delete(t.conns, token)
+	return nil
+}
+
+// Quota returns the quota of the owner.
+func (t *Things) Quota(owner string) (sandbox.Quota, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	q, ok := t.quotas[owner]
+	return q, ok
+}
+
+// Entities returns the number of things, channels and connections of the
+// user.
+func (t *Things) Entities(token string) sandbox.Quota {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return sandbox.Quota{
+		Things:      uint64(len(t.things[token])),
+		Channels:    uint64(len(t.channels[token])),
+		Connections: uint64(t.conns[token]),
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/sandbox"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	_ sandbox.Users               = (*Users)(nil)
+	_ mainflux.UsersServiceClient = (*Users)(nil)
+)
+
+// Users is the mock of the users service, which uses the account email as
+// its access token.
+type Users struct {
+	mu       sync.Mutex
+	accounts map[string]string
+}
+
+// NewUsers creates mock of users service.
+func NewUsers() *Users {
+	return &Users{
+		accounts: make(map[string]string),
+	}
+}
+
+// Register creates the account.
+func (u *Users) Register(email, password string) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if _, ok := u.accounts[email]; ok {
+		return sandbox.ErrMalformedEntity
+	}
+
+	u.accounts[email] = password
+	return nil
+}
+
+// Login returns the account token.
+func (u *Users) Login(email, password string) (string, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if pass, ok := u.accounts[email]; !ok || pass != password {
+		return "", sandbox.ErrUnauthorizedAccess
+	}
+
+	return email, nil
+}
+
+// Unregister removes the account.
+func (u *Users) Unregister(token string) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if _, ok := u.accounts[token]; !ok {
+		return sandbox.ErrUnauthorizedAccess
+	}
+
+	delete(u.accounts, token)
+	return nil
+}
+
+// Registered checks if the account with the provided email exists.
+func (u *Users) Registered(email string) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	_, ok := u.accounts[email]
+	return ok
+}
+
+// Identify returns the email of the account identified by the token.
+func (u *Users) Identify(ctx context.Context, in *mainflux.Token, opts ...grpc.CallOption) (*mainflux.UserID, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if _, ok := u.accounts[in.Value]; !ok {
+		return nil, sandbox.ErrUnauthorizedAccess
+	}
+
+	return &mainflux.UserID{Value: in.Value}, nil
+}
+
+// Groups returns no groups of the account identified by the token.
+func (u *Users) Groups(ctx context.Context, in *mainflux.Token, opts ...grpc.CallOption) (*mainflux.GroupIDs, error) {
+	if _, err := u.Identify(ctx, in, opts...); err != nil {
+		return nil, err
+	}
+
+	return &mainflux.GroupIDs{}, nil
+}
+
+// Disabled reports the registered accounts as enabled.
+func (u *Users) Disabled(ctx context.Context, in *mainflux.UserID, opts ...grpc.CallOption) (*mainflux.UserStatus, error) {
+	if !u.Registered(in.GetValue()) {
+		return nil, status.Error(codes.NotFound, "user not found")
+	}
+
+	return &mainflux.UserStatus{}, nil
+}
@@ -0,0 +1,129 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq" // required for DB access
+	"github.com/mainflux/mainflux/sandbox"
+)
+
+const (
+	errDuplicate = "unique_violation"
+	errInvalid   = "invalid_text_representation"
+)
+
+var _ sandbox.Repository = (*sandboxRepository)(nil)
+
+type sandboxRepository struct {
+	db *sqlx.DB
+}
+
+// New instantiates a PostgreSQL implementation of sandbox repository.
+func New(db *sqlx.DB) sandbox.Repository {
+	return &sandboxRepository{
+		db: db,
+	}
+}
+
+func (sr sandboxRepository) Save(ctx context.Context, sb sandbox.Sandbox) error {
+	q := `INSERT INTO sandboxes (id, email, password, created_at, expires_at)
+	      VALUES (:id, :email, :password, :created_at, :expires_at);`
+
+	if _, err := sr.db.NamedExecContext(ctx, q, toDBSandbox(sb)); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == errDuplicate {
+			return sandbox.ErrMalformedEntity
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (sr sandboxRepository) RetrieveByID(ctx context.Context, id string) (sandbox.Sandbox, error) {
+	q := `SELECT id, email, password, created_at, expires_at FROM sandboxes WHERE id = $1;`
+
+	var dbsb dbSandbox
+	if err := sr.db.QueryRowxContext(ctx, q, id).StructScan(&dbsb); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if err == sql.ErrNoRows || ok && errInvalid == pqErr.Code.Name() {
+			return sandbox.Sandbox{}, sandbox.ErrNotFound
+		}
+		return sandbox.Sandbox{}, err
+	}
+
+	return toSandbox(dbsb), nil
+}
+
+func (sr sandboxRepository) RetrieveExpired(ctx context.Context, t time.Time, limit uint64) ([]sandbox.Sandbox, error) {
+	q := `SELECT id, email, password, created_at, expires_at FROM sandboxes
+	      WHERE expires_at < $1 ORDER BY expires_at LIMIT $2;`
+
+	rows, err := sr.db.QueryxContext(ctx, q, t.UTC(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sbs := []sandbox.Sandbox{}
+	for rows.Next() {
+		var dbsb dbSandbox
+		if err := rows.StructScan(&dbsb); err != nil {
+			return nil, err
+		}
+		sbs = append(sbs, toSandbox(dbsb))
+	}
+
+	return sbs, rows.Err()
+}
+
+func (sr sandboxRepository) Count(ctx context.Context) (uint64, error) {
+	q := `SELECT COUNT(*) FROM sandboxes;`
+
+	var count uint64
+	if err := sr.db.GetContext(ctx, &count, q); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (sr sandboxRepository) Remove(ctx context.Context, id string) error {
+	q := `DELETE FROM sandboxes WHERE id = $1;`
+
+	_, err := sr.db.ExecContext(ctx, q, id)
+	return err
+}
+
+type dbSandbox struct {
+	ID        string    `db:"id"`
+	Email     string    `db:"email"`
+	Password  string    `db:"password"`
+	CreatedAt time.Time `db:"created_at"`
+	ExpiresAt time.Time `db:"expires_at"`
+}
+
+func toDBSandbox(sb sandbox.Sandbox) dbSandbox {
+	return dbSandbox{
+		ID:        sb.ID,
+		Email:     sb.Email,
+		Password:  sb.Password,
+		CreatedAt: sb.Created.UTC(),
+		ExpiresAt: sb.Expires.UTC(),
+	}
+}
+
+func toSandbox(dbsb dbSandbox) sandbox.Sandbox {
+	return sandbox.Sandbox{
+		ID:       dbsb.ID,
+		Email:    dbsb.Email,
+		Password: dbsb.Password,
+		Created:  dbsb.CreatedAt.UTC(),
+		Expires:  dbsb.ExpiresAt.UTC(),
+	}
+}
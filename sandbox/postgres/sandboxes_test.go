@@ -0,0 +1,173 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/sandbox"
+	"github.com/mainflux/mainflux/sandbox/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	id      = "123e4567-e89b-12d3-a456-000000000001"
+	otherID = "123e4567-e89b-12d3-a456-000000000002"
+	wrongID = "123e4567-e89b-12d3-a456-000000000099"
+)
+
+func newSandbox(id string, expires time.Time) sandbox.Sandbox {
+	return sandbox.Sandbox{
+		ID:       id,
+		Email:    fmt.Sprintf("%s@sandbox.example.com", id),
+		Password: "password",
+		Created:  expires.Add(-time.Hour).Truncate(time.Microsecond),
+		Expires:  expires.Truncate(time.Microsecond),
+	}
+}
+
+func TestSandboxSave(t *testing.T) {
+	repo := postgres.New(db)
+	sb := newSandbox(id, time.Now().UTC().Add(time.Hour))
+
+	cases := []struct {
+		desc string
+		sb   sandbox.Sandbox
+		err  error
+	}{
+		{
+			desc: "save new sandbox",
+			sb:   sb,
+			err:  nil,
+		},
+		{
+			desc: "save duplicate sandbox",
+			sb:   sb,
+			err:  sandbox.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		err := repo.Save(context.Background(), tc.sb)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	repo.Remove(context.Background(), id)
+}
+
+func TestSandboxRetrieveByID(t *testing.T) {
+	repo := postgres.New(db)
+	sb := newSandbox(id, time.Now().UTC().Add(time.Hour))
+	err := repo.Save(context.Background(), sb)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	defer repo.Remove(context.Background(), id)
+
+	cases := []struct {
+		desc string
+		id   string
+		sb   sandbox.Sandbox
+		err  error
+	}{
+		{
+			desc: "retrieve existing sandbox",
+			id:   id,
+			sb:   sb,
+			err:  nil,
+		},
+		{
+			desc: "retrieve non-existing sandbox",
+			id:   wrongID,
+			sb:   sandbox.Sandbox{},
+			err:  sandbox.ErrNotFound,
+		},
+		{
+			desc: "retrieve sandbox with malformed ID",
+			id:   "malformed",
+			sb:   sandbox.Sandbox{},
+			err:  sandbox.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		sb, err := repo.RetrieveByID(context.Background(), tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.sb, sb, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.sb, sb))
+	}
+}
+
+func TestSandboxRetrieveExpired(t *testing.T) {
+	repo := postgres.New(db)
+	now := time.Now().UTC()
+
+	expired := newSandbox(id, now.Add(-time.Minute))
+	active := newSandbox(otherID, now.Add(time.Hour))
+	for _, sb := range []sandbox.Sandbox{expired, active} {
+		err := repo.Save(context.Background(), sb)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		defer repo.Remove(context.Background(), sb.ID)
+	}
+
+	cases := []struct {
+		desc  string
+		time  time.Time
+		limit uint64
+		sbs   []sandbox.Sandbox
+	}{
+		{
+			desc:  "retrieve expired sandboxes",
+			time:  now,
+			limit: 10,
+			sbs:   []sandbox.Sandbox{expired},
+		},
+		{
+			desc:  "retrieve sandboxes expired in the future",
+			time:  now.Add(2 * time.Hour),
+			limit: 10,
+			sbs:   []sandbox.Sandbox{expired, active},
+		},
+		{
+			desc:  "retrieve limited number of expired sandboxes",
+			time:  now.Add(2 * time.Hour),
+			limit: 1,
+			sbs:   []sandbox.Sandbox{expired},
+		},
+		{
+			desc:  "retrieve sandboxes expired in the past",
+			time:  now.Add(-time.Hour),
+			limit: 10,
+			sbs:   []sandbox.Sandbox{},
+		},
+	}
+
+	for _, tc := range cases {
+		sbs, err := repo.RetrieveExpired(context.Background(), tc.time, tc.limit)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s\n", tc.desc, err))
+		assert.Equal(t, tc.sbs, sbs, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.sbs, sbs))
+	}
+}
+
+func TestSandboxCountRemove(t *testing.T) {
+	repo := postgres.New(db)
+
+	err := repo.Save(context.Background(), newSandbox(id, time.Now().UTC().Add(time.Hour)))
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	count, err := repo.Count(context.Background())
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, uint64(1), count, fmt.Sprintf("expected 1 sandbox got %d", count))
+
+	err = repo.Remove(context.Background(), id)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	count, err = repo.Count(context.Background())
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, uint64(0), count, fmt.Sprintf("expected no sandboxes got %d", count))
+
+	_, err = repo.RetrieveByID(context.Background(), id)
+	assert.Equal(t, sandbox.ErrNotFound, err, fmt.Sprintf("expected %s got %s", sandbox.ErrNotFound, err))
+}
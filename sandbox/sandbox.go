@@ -0,0 +1,124 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package sandbox
+
+import (
+	"context"
+	"time"
+)
+
+// Sandbox represents the ephemeral tenant, i.e. the user account together
+// with its things and channels. The account password is kept, so that the
+// account can be removed once the sandbox expires. Token, channel and things
+// are set on sandbox creation only.
+type Sandbox struct {
+	ID       string
+	Email    string
+	Password string
+	Created  time.Time
+	Expires  time.Time
+	Token    string
+	Channel  string
+	Things   []Thing
+}
+
+// Thing represents the thing provisioned on sandbox creation.
+type Thing struct {
+	ID  string
+	Key string
+}
+
+// Quota limits the number of things, channels and connections of the
+// sandbox. Zero limit means that the number of entities is not limited.
+type Quota struct {
+	Things      uint64
+	Channels    uint64
+	Connections uint64
+}
+
+// Config contains the sandbox lifetime and quota caps.
+type Config struct {
+	// Domain is the email domain of the sandbox accounts.
+	Domain string
+
+	// Duration is the lifetime of the sandbox, unless requested otherwise.
+	Duration time.Duration
+
+	// MaxDuration is the longest lifetime of the sandbox.
+	MaxDuration time.Duration
+
+	// MaxSandboxes limits the number of sandboxes. Zero value imposes no
+	// limit.
+	MaxSandboxes uint64
+
+	// Quota limits the entities of every sandbox.
+	Quota Quota
+}
+
+// Repository specifies a sandbox persistence API.
+type Repository interface {
+	// Save persists the sandbox.
+	Save(context.Context, Sandbox) error
+
+	// RetrieveByID retrieves the sandbox having the provided identifier.
+	RetrieveByID(context.Context, string) (Sandbox, error)
+
+	// RetrieveExpired retrieves at most the provided number of sandboxes
+	// that expired before the provided time, the longest expired first.
+	RetrieveExpired(context.Context, time.Time, uint64) ([]Sandbox, error)
+
+	// Count returns the number of sandboxes, including the expired ones
+	// which aren't removed yet.
+	Count(context.Context) (uint64, error)
+
+	// Remove removes the sandbox having the provided identifier.
+	Remove(context.Context, string) error
+}
+
+// Users specifies the API of the users service used to manage the sandbox
+// accounts.
+type Users interface {
+	// Register creates the user account with the provided credentials.
+	Register(string, string) error
+
+	// Login returns the access token of the account with the provided
+	// credentials.
+	Login(string, string) (string, error)
+
+	// Unregister removes the account identified by the provided token.
+	Unregister(string) error
+}
+
+// Things specifies the API of the things service used to provision the
+// sandbox entities.
+type Things interface {
+	// SetQuota overrides the quota of the provided owner.
+	SetQuota(string, Quota) error
+
+	// RemoveQuota restores the default quota of the provided owner.
+	RemoveQuota(string) error
+
+	// CreateChannel creates the channel with the provided name, whose
+	// messages are kept for the provided period, owned by the user
+	// identified by the provided token, and returns its ID.
+	CreateChannel(string, string, time.Duration) (string, error)
+
+	// CreateThing creates the thing with the provided name, owned by the
+	// user identified by the provided token.
+	CreateThing(string, string) (Thing, error)
+
+	// Connect connects the thing to the channel, both owned by the user
+	// identified by the provided token.
+	Connect(string, string, string) error
+
+	// Clear permanently removes all the things and channels of the user
+	// identified by the provided token.
+	Clear(string) error
+}
+
+// IdentityProvider specifies an API for generating unique identifiers.
+type IdentityProvider interface {
+	// ID generates the unique identifier.
+	ID() (string, error)
+}
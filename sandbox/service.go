@@ -0,0 +1,267 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package sandbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mainflux/mainflux"
+)
+
+const (
+	channelName  = "sandbox"
+	thingName    = "sandbox-thing"
+	cleanupBatch = 100
+)
+
+var (
+	// ErrMalformedEntity indicates malformed entity specification.
+	ErrMalformedEntity = errors.New("malformed entity specification")
+
+	// ErrUnauthorizedAccess indicates missing or invalid credentials provided
+	// when accessing a protected resource.
+	ErrUnauthorizedAccess = errors.New("missing or invalid credentials provided")
+
+	// ErrNotFound indicates a non-existent entity request.
+	ErrNotFound = errors.New("non-existent entity")
+
+	// ErrQuotaExceeded indicates that the maximum number of sandboxes has
+	// been reached.
+	ErrQuotaExceeded = errors.New("quota exceeded")
+)
+
+// Service specifies an API that must be fulfilled by the domain service
+// implementation, and all of its decorators (e.g. logging & metrics).
+type Service interface {
+	// Create creates the sandbox which expires after the provided duration,
+	// or after the default one if the duration is zero, and provisions the
+	// provided number of things connected to the sandbox channel.
+	Create(context.Context, time.Duration, uint64) (Sandbox, error)
+
+	// View retrieves the sandbox identified by the provided ID, if its
+	// account is identified by the provided key.
+	View(context.Context, string, string) (Sandbox, error)
+
+	// Remove removes the sandbox identified by the provided ID before it
+	// expires, if its account is identified by the provided key.
+	Remove(context.Context, string, string) error
+
+	// Cleanup removes the expired sandboxes together with their accounts,
+	// things and channels, and returns the number of removed sandboxes.
+	Cleanup(context.Context) (uint64, error)
+}
+
+var _ Service = (*sandboxService)(nil)
+
+type sandboxService struct {
+	auth   mainflux.UsersServiceClient
+	users  Users
+	things Things
+	repo   Repository
+	idp    IdentityProvider
+	cfg    Config
+
+	// Creations are serialized, so that the number of sandboxes can't
+	// exceed the limit.
+	mu sync.Mutex
+}
+
+// New instantiates the sandbox service implementation.
+func New(auth mainflux.UsersServiceClient, users Users, things Things, repo Repository, idp IdentityProvider, cfg Config) Service {
+	return &sandboxService{
+		auth:   auth,
+		users:  users,
+		things: things,
+		repo:   repo,
+		idp:    idp,
+		cfg:    cfg,
+	}
+}
+
+func (ss *sandboxService) Create(ctx context.Context, duration time.Duration, things uint64) (Sandbox, error) {
+	if duration == 0 {
+		duration = ss.cfg.Duration
+	}
+
+	if duration < 0 || duration > ss.cfg.MaxDuration || exceeds(ss.cfg.Quota.Things, things) || exceeds(ss.cfg.Quota.Connections, things) {
+		return Sandbox{}, ErrMalformedEntity
+	}
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	if ss.cfg.MaxSandboxes > 0 {
+		count, err := ss.repo.Count(ctx)
+		if err != nil {
+			return Sandbox{}, err
+		}
+
+		if count >= ss.cfg.MaxSandboxes {
+			return Sandbox{}, ErrQuotaExceeded
+		}
+	}
+
+	id, err := ss.idp.ID()
+	if err != nil {
+		return Sandbox{}, err
+	}
+
+	password, err := ss.idp.ID()
+	if err != nil {
+		return Sandbox{}, err
+	}
+
+	now := time.Now().UTC()
+	sb := Sandbox{
+		ID:       id,
+		Email:    fmt.Sprintf("%s@%s", id, ss.cfg.Domain),
+		Password: password,
+		Created:  now,
+		Expires:  now.Add(duration),
+	}
+
+	// Sandbox is saved before provisioning, so that the cleanup removes
+	// whatever has been provisioned if the removal below fails.
+	if err := ss.repo.Save(ctx, sb); err != nil {
+		return Sandbox{}, err
+	}
+
+	if err := ss.provision(&sb, duration, things); err != nil {
+		ss.remove(ctx, sb)
+		return Sandbox{}, err
+	}
+
+	return sb, nil
+}
+
+func (ss *sandboxService) View(ctx context.Context, token, id string) (Sandbox, error) {
+	sb, err := ss.owned(ctx, token, id)
+	if err != nil {
+		return Sandbox{}, err
+	}
+
+	sb.Password = ""
+	return sb, nil
+}
+
+func (ss *sandboxService) Remove(ctx context.Context, token, id string) error {
+	sb, err := ss.owned(ctx, token, id)
+	if err != nil {
+		return err
+	}
+
+	return ss.remove(ctx, sb)
+}
+
+func (ss *sandboxService) Cleanup(ctx context.Context) (uint64, error) {
+	sbs, err := ss.repo.RetrieveExpired(ctx, time.Now().UTC(), cleanupBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	// Sandboxes which failed to be removed are retried on the next cleanup,
+	// so the failure doesn't prevent the removal of the others.
+	var removed uint64
+	for _, sb := range sbs {
+		if rerr := ss.remove(ctx, sb); rerr != nil {
+			err = rerr
+			continue
+		}
+		removed++
+	}
+
+	return removed, err
+}
+
+func (ss *sandboxService) provision(sb *Sandbox, duration time.Duration, things uint64) error {
+	if err := ss.users.Register(sb.Email, sb.Password); err != nil {
+		return err
+	}
+
+	token, err := ss.users.Login(sb.Email, sb.Password)
+	if err != nil {
+		return err
+	}
+
+	if err := ss.things.SetQuota(sb.Email, ss.cfg.Quota); err != nil {
+		return err
+	}
+
+	// Channel retention removes the messages older than the sandbox
+	// lifetime, including the ones published before the sandbox expired.
+	chanID, err := ss.things.CreateChannel(token, channelName, duration)
+	if err != nil {
+		return err
+	}
+
+	for i := uint64(1); i <= things; i++ {
+		th, err := ss.things.CreateThing(token, fmt.Sprintf("%s-%d", thingName, i))
+		if err != nil {
+			return err
+		}
+
+		if err := ss.things.Connect(token, th.ID, chanID); err != nil {
+			return err
+		}
+
+		sb.Things = append(sb.Things, th)
+	}
+
+	sb.Token = token
+	sb.Channel = chanID
+	return nil
+}
+
+// remove removes the sandbox entities, account and quota, in the order that
+// allows the removal to be retried after any of the steps fails.
+func (ss *sandboxService) remove(ctx context.Context, sb Sandbox) error {
+	token, err := ss.users.Login(sb.Email, sb.Password)
+	switch err {
+	case nil:
+		if err := ss.things.Clear(token); err != nil {
+			return err
+		}
+
+		if err := ss.users.Unregister(token); err != nil && err != ErrNotFound {
+			return err
+		}
+	case ErrUnauthorizedAccess:
+		// Account is either removed or hasn't been registered.
+	default:
+		return err
+	}
+
+	if err := ss.things.RemoveQuota(sb.Email); err != nil {
+		return err
+	}
+
+	return ss.repo.Remove(ctx, sb.ID)
+}
+
+func (ss *sandboxService) owned(ctx context.Context, token, id string) (Sandbox, error) {
+	res, err := ss.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return Sandbox{}, ErrUnauthorizedAccess
+	}
+
+	sb, err := ss.repo.RetrieveByID(ctx, id)
+	if err != nil {
+		return Sandbox{}, err
+	}
+
+	if sb.Email != res.GetValue() {
+		return Sandbox{}, ErrNotFound
+	}
+
+	return sb, nil
+}
+
+// exceeds checks if the number of entities exceeds the limit.
+func exceeds(limit, n uint64) bool {
+	return limit > 0 && n > limit
+}
@@ -0,0 +1,253 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package sandbox_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/sandbox"
+	"github.com/mainflux/mainflux/sandbox/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	wrongValue = "wrong-value"
+	domain     = "sandbox.example.com"
+)
+
+var cfg = sandbox.Config{
+	Domain:       domain,
+	Duration:     time.Hour,
+	MaxDuration:  3 * time.Hour,
+	MaxSandboxes: 3,
+	Quota: sandbox.Quota{
+		Things:      5,
+		Channels:    2,
+		Connections: 5,
+	},
+}
+
+func newService() (sandbox.Service, *mocks.Users, *mocks.Things) {
+	users := mocks.NewUsers()
+	things := mocks.NewThings()
+	return sandbox.New(users, users, things, mocks.NewRepository(), mocks.NewIdentityProvider(), cfg), users, things
+}
+
+func TestCreate(t *testing.T) {
+	svc, users, things := newService()
+
+	cases := []struct {
+		desc     string
+		duration time.Duration
+		things   uint64
+		expires  time.Duration
+		err      error
+	}{
+		{
+			desc:     "create sandbox with default duration",
+			duration: 0,
+			things:   2,
+			expires:  cfg.Duration,
+			err:      nil,
+		},
+		{
+			desc:     "create sandbox with custom duration",
+			duration: 2 * time.Hour,
+			things:   5,
+			expires:  2 * time.Hour,
+			err:      nil,
+		},
+		{
+			desc:     "create sandbox with negative duration",
+			duration: -time.Hour,
+			things:   1,
+			err:      sandbox.ErrMalformedEntity,
+		},
+		{
+			desc:     "create sandbox exceeding maximum duration",
+			duration: 4 * time.Hour,
+			things:   1,
+			err:      sandbox.ErrMalformedEntity,
+		},
+		{
+			desc:     "create sandbox exceeding things quota",
+			duration: 0,
+			things:   6,
+			err:      sandbox.ErrMalformedEntity,
+		},
+		{
+			desc:     "create sandbox without things",
+			duration: 0,
+			things:   0,
+			expires:  cfg.Duration,
+			err:      nil,
+		},
+		{
+			desc:     "create sandbox exceeding maximum number of sandboxes",
+			duration: 0,
+			things:   1,
+			err:      sandbox.ErrQuotaExceeded,
+		},
+	}
+
+	for _, tc := range cases {
+		sb, err := svc.Create(context.Background(), tc.duration, tc.things)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err != nil {
+			continue
+		}
+
+		assert.Equal(t, tc.expires, sb.Expires.Sub(sb.Created), fmt.Sprintf("%s: expected lifetime %s got %s\n", tc.desc, tc.expires, sb.Expires.Sub(sb.Created)))
+		assert.Equal(t, fmt.Sprintf("%s@%s", sb.ID, domain), sb.Email, fmt.Sprintf("%s: unexpected email %s\n", tc.desc, sb.Email))
+		assert.True(t, users.Registered(sb.Email), fmt.Sprintf("%s: expected account to be registered\n", tc.desc))
+		assert.Len(t, sb.Things, int(tc.things), fmt.Sprintf("%s: expected %d things got %d\n", tc.desc, tc.things, len(sb.Things)))
+
+		quota, ok := things.Quota(sb.Email)
+		assert.True(t, ok, fmt.Sprintf("%s: expected quota to be set\n", tc.desc))
+		assert.Equal(t, cfg.Quota, quota, fmt.Sprintf("%s: expected quota %v got %v\n", tc.desc, cfg.Quota, quota))
+
+		expected := sandbox.Quota{Things: tc.things, Channels: 1, Connections: tc.things}
+		entities := things.Entities(sb.Token)
+		assert.Equal(t, expected, entities, fmt.Sprintf("%s: expected entities %v got %v\n", tc.desc, expected, entities))
+	}
+}
+
+func TestView(t *testing.T) {
+	svc, _, _ := newService()
+
+	sb, err := svc.Create(context.Background(), 0, 1)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	other, err := svc.Create(context.Background(), 0, 1)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		token string
+		id    string
+		err   error
+	}{
+		{
+			desc:  "view existing sandbox",
+			token: sb.Token,
+			id:    sb.ID,
+			err:   nil,
+		},
+		{
+			desc:  "view sandbox with invalid token",
+			token: wrongValue,
+			id:    sb.ID,
+			err:   sandbox.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "view sandbox of another account",
+			token: other.Token,
+			id:    sb.ID,
+			err:   sandbox.ErrNotFound,
+		},
+		{
+			desc:  "view non-existing sandbox",
+			token: sb.Token,
+			id:    wrongValue,
+			err:   sandbox.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		view, err := svc.View(context.Background(), tc.token, tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err != nil {
+			continue
+		}
+
+		assert.Equal(t, sb.Email, view.Email, fmt.Sprintf("%s: expected email %s got %s\n", tc.desc, sb.Email, view.Email))
+		assert.Empty(t, view.Password, fmt.Sprintf("%s: expected password to be hidden\n", tc.desc))
+	}
+}
+
+func TestRemove(t *testing.T) {
+	svc, users, things := newService()
+
+	sb, err := svc.Create(context.Background(), 0, 2)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	other, err := svc.Create(context.Background(), 0, 1)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		token string
+		id    string
+		err   error
+	}{
+		{
+			desc:  "remove sandbox with invalid token",
+			token: wrongValue,
+			id:    sb.ID,
+			err:   sandbox.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "remove sandbox of another account",
+			token: other.Token,
+			id:    sb.ID,
+			err:   sandbox.ErrNotFound,
+		},
+		{
+			desc:  "remove existing sandbox",
+			token: sb.Token,
+			id:    sb.ID,
+			err:   nil,
+		},
+		{
+			desc:  "remove removed sandbox",
+			token: sb.Token,
+			id:    sb.ID,
+			err:   sandbox.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.Remove(context.Background(), tc.token, tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	assert.False(t, users.Registered(sb.Email), "expected account to be removed")
+	_, ok := things.Quota(sb.Email)
+	assert.False(t, ok, "expected quota to be removed")
+	entities := things.Entities(sb.Token)
+	assert.Equal(t, sandbox.Quota{}, entities, fmt.Sprintf("expected entities to be removed got %v", entities))
+	assert.True(t, users.Registered(other.Email), "expected other account to be kept")
+}
+
+func TestCleanup(t *testing.T) {
+	svc, users, things := newService()
+
+	expired, err := svc.Create(context.Background(), 10*time.Millisecond, 1)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	active, err := svc.Create(context.Background(), 0, 1)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	time.Sleep(20 * time.Millisecond)
+
+	removed, err := svc.Cleanup(context.Background())
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, uint64(1), removed, fmt.Sprintf("expected 1 removed sandbox got %d", removed))
+
+	assert.False(t, users.Registered(expired.Email), "expected expired account to be removed")
+	_, ok := things.Quota(expired.Email)
+	assert.False(t, ok, "expected expired quota to be removed")
+	assert.True(t, users.Registered(active.Email), "expected active account to be kept")
+
+	removed, err = svc.Cleanup(context.Background())
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, uint64(0), removed, fmt.Sprintf("expected no removed sandboxes got %d", removed))
+
+	_, err = svc.Create(context.Background(), 0, 1)
+	assert.Nil(t, err, fmt.Sprintf("expected sandbox to be created after cleanup got %s", err))
+}
@@ -0,0 +1,140 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package things contains the things service client backed by the Mainflux
+// SDK.
+package things
+
+import (
+	"time"
+
+	"github.com/mainflux/mainflux/sandbox"
+	mfsdk "github.com/mainflux/mainflux/sdk/go"
+)
+
+const pageLimit = 100
+
+var _ sandbox.Things = (*things)(nil)
+
+type things struct {
+	sdk   mfsdk.SDK
+	admin mfsdk.User
+}
+
+// New returns things API client backed by the provided SDK. Quotas are
+// managed using the credentials of the things service admin.
+func New(sdk mfsdk.SDK, email, password string) sandbox.Things {
+	return things{
+		sdk:   sdk,
+		admin: mfsdk.User{Email: email, Password: password},
+	}
+}
+
+func (t things) SetQuota(owner string, quota sandbox.Quota) error {
+	// Admin logs in on every call, since quotas are managed rarely and
+	// the tokens expire.
+	token, err := t.sdk.CreateToken(t.admin)
+	if err != nil {
+		return convertError(err)
+	}
+
+	q := mfsdk.Quota{
+		Things:      quota.Things,
+		Channels:    quota.Channels,
+		Connections: quota.Connections,
+	}
+	return convertError(t.sdk.UpdateQuota(owner, q, token))
+}
+
+func (t things) RemoveQuota(owner string) error {
+	token, err := t.sdk.CreateToken(t.admin)
+	if err != nil {
+		return convertError(err)
+	}
+
+	if err := t.sdk.RemoveQuota(owner, token); err != nil && err != mfsdk.ErrNotFound {
+		return convertError(err)
+	}
+
+	return nil
+}
+
+func (t things) CreateChannel(token, name string, retention time.Duration) (string, error) {
+	ch := mfsdk.Channel{
+		Name:      name,
+		Retention: &mfsdk.Retention{Period: int64(retention / time.Second)},
+	}
+
+	id, err := t.sdk.CreateChannel(ch, token)
+	return id, convertError(err)
+}
+
+func (t things) CreateThing(token, name string) (sandbox.Thing, error) {
+	id, err := t.sdk.CreateThing(mfsdk.Thing{Name: name}, token)
+	if err != nil {
+		return sandbox.Thing{}, convertError(err)
+	}
+
+	th, err := t.sdk.Thing(id, token)
+	if err != nil {
+		return sandbox.Thing{}, convertError(err)
+	}
+
+	return sandbox.Thing{ID: th.ID, Key: th.Key}, nil
+}
+
+func (t things) Connect(token, thingID, chanID string) error {
+	return convertError(t.sdk.ConnectThing(thingID, chanID, token))
+}
+
+// Clear purges the listed things and channels until none is left. Things
+// and channels which the user removed aren't listed by the SDK, so they are
+// left removed but not purged.
+func (t things) Clear(token string) error {
+	for {
+		page, err := t.sdk.Things(token, 0, pageLimit, "")
+		if err != nil {
+			return convertError(err)
+		}
+
+		if len(page.Things) == 0 {
+			break
+		}
+
+		for _, th := range page.Things {
+			if err := t.sdk.PurgeThing(th.ID, token); err != nil && err != mfsdk.ErrNotFound {
+				return convertError(err)
+			}
+		}
+	}
+
+	for {
+		page, err := t.sdk.Channels(token, 0, pageLimit, "")
+		if err != nil {
+			return convertError(err)
+		}
+
+		if len(page.Channels) == 0 {
+			return nil
+		}
+
+		for _, ch := range page.Channels {
+			if err := t.sdk.PurgeChannel(ch.ID, token); err != nil && err != mfsdk.ErrNotFound {
+				return convertError(err)
+			}
+		}
+	}
+}
+
+func convertError(err error) error {
+	switch err {
+	case mfsdk.ErrInvalidArgs:
+		return sandbox.ErrMalformedEntity
+	case mfsdk.ErrUnauthorized:
+		return sandbox.ErrUnauthorizedAccess
+	case mfsdk.ErrNotFound:
+		return sandbox.ErrNotFound
+	default:
+		return err
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package users contains the users service client backed by the Mainflux SDK.
+package users
+
+import (
+	"github.com/mainflux/mainflux/sandbox"
+	mfsdk "github.com/mainflux/mainflux/sdk/go"
+)
+
+var _ sandbox.Users = (*users)(nil)
+
+type users struct {
+	sdk mfsdk.SDK
+}
+
+// New returns users API client backed by the provided SDK.
+func New(sdk mfsdk.SDK) sandbox.Users {
+	return users{sdk: sdk}
+}
+
+func (u users) Register(email, password string) error {
+	err := u.sdk.CreateUser(mfsdk.User{Email: email, Password: password})
+	return convertError(err)
+}
+
+func (u users) Login(email, password string) (string, error) {
+	token, err := u.sdk.CreateToken(mfsdk.User{Email: email, Password: password})
+	return token, convertError(err)
+}
+
+func (u users) Unregister(token string) error {
+	return convertError(u.sdk.DeleteUser(token))
+}
+
+func convertError(err error) error {
+	switch err {
+	case mfsdk.ErrInvalidArgs, mfsdk.ErrConflict:
+		return sandbox.ErrMalformedEntity
+	case mfsdk.ErrUnauthorized:
+		return sandbox.ErrUnauthorizedAccess
+	case mfsdk.ErrNotFound:
+		return sandbox.ErrNotFound
+	default:
+		return err
+	}
+}
@@ -0,0 +1,298 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package sdk
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+const (
+	configsEndpoint = "things/configs"
+	stateEndpoint   = "things/state"
+)
+
+func (sdk mfSDK) AddBootstrap(cfg BootstrapConfig, token string) (string, error) {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return "", ErrInvalidArgs
+	}
+
+	url := createURL(sdk.bootstrapURL, "", configsEndpoint)
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		switch resp.StatusCode {
+		case http.StatusBadRequest:
+			return "", ErrInvalidArgs
+		case http.StatusForbidden:
+			return "", ErrUnauthorized
+		case http.StatusConflict:
+			return "", ErrConflict
+		default:
+			return "", ErrFailedCreation
+		}
+	}
+
+	id := strings.TrimPrefix(resp.Header.Get("Location"), fmt.Sprintf("/%s/", configsEndpoint))
+	return id, nil
+}
+
+func (sdk mfSDK) BootstrapConfigs(token string, offset, limit uint64) (BootstrapPage, error) {
+	endpoint := fmt.Sprintf("%s?offset=%d&limit=%d", configsEndpoint, offset, limit)
+	url := createURL(sdk.bootstrapURL, "", endpoint)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return BootstrapPage{}, err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return BootstrapPage{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return BootstrapPage{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		switch resp.StatusCode {
+		case http.StatusBadRequest:
+			return BootstrapPage{}, ErrInvalidArgs
+		case http.StatusForbidden:
+			return BootstrapPage{}, ErrUnauthorized
+		default:
+			return BootstrapPage{}, ErrFetchFailed
+		}
+	}
+
+	var p bootstrapPageRes
+	if err := json.Unmarshal(body, &p); err != nil {
+		return BootstrapPage{}, err
+	}
+
+	page := BootstrapPage{
+		Configs: []BootstrapConfig{},
+		Total:   p.Total,
+		Offset:  p.Offset,
+		Limit:   p.Limit,
+	}
+	for _, c := range p.Configs {
+		page.Configs = append(page.Configs, toBootstrapConfig(c))
+	}
+
+	return page, nil
+}
+
+func (sdk mfSDK) BootstrapConfig(id, token string) (BootstrapConfig, error) {
+	endpoint := fmt.Sprintf("%s/%s", configsEndpoint, id)
+	url := createURL(sdk.bootstrapURL, "", endpoint)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return BootstrapConfig{}, err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return BootstrapConfig{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return BootstrapConfig{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		switch resp.StatusCode {
+		case http.StatusForbidden:
+			return BootstrapConfig{}, ErrUnauthorized
+		case http.StatusNotFound:
+			return BootstrapConfig{}, ErrNotFound
+		default:
+			return BootstrapConfig{}, ErrFetchFailed
+		}
+	}
+
+	var c bootstrapConfigRes
+	if err := json.Unmarshal(body, &c); err != nil {
+		return BootstrapConfig{}, err
+	}
+
+	return toBootstrapConfig(c), nil
+}
+
+func (sdk mfSDK) UpdateBootstrap(cfg BootstrapConfig, token string) error {
+	data, err := json.Marshal(map[string]string{
+		"name":    cfg.Name,
+		"content": cfg.Content,
+	})
+	if err != nil {
+		return ErrInvalidArgs
+	}
+
+	endpoint := fmt.Sprintf("%s/%s", configsEndpoint, cfg.ThingID)
+	url := createURL(sdk.bootstrapURL, "", endpoint)
+
+	req, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		switch resp.StatusCode {
+		case http.StatusBadRequest:
+			return ErrInvalidArgs
+		case http.StatusForbidden:
+			return ErrUnauthorized
+		case http.StatusNotFound:
+			return ErrNotFound
+		default:
+			return ErrFailedUpdate
+		}
+	}
+
+	return nil
+}
+
+func (sdk mfSDK) UpdateBootstrapConnections(id string, channels []string, token string) error {
+	data, err := json.Marshal(map[string][]string{"channels": channels})
+	if err != nil {
+		return ErrInvalidArgs
+	}
+
+	endpoint := fmt.Sprintf("%s/connections/%s", configsEndpoint, id)
+	url := createURL(sdk.bootstrapURL, "", endpoint)
+
+	req, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		switch resp.StatusCode {
+		case http.StatusBadRequest:
+			return ErrInvalidArgs
+		case http.StatusForbidden:
+			return ErrUnauthorized
+		case http.StatusNotFound:
+			return ErrNotFound
+		default:
+			return ErrFailedUpdate
+		}
+	}
+
+	return nil
+}
+
+func (sdk mfSDK) RemoveBootstrap(id, token string) error {
+	endpoint := fmt.Sprintf("%s/%s", configsEndpoint, id)
+	url := createURL(sdk.bootstrapURL, "", endpoint)
+
+	req, err := http.NewRequest(http.MethodDelete, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		switch resp.StatusCode {
+		case http.StatusForbidden:
+			return ErrUnauthorized
+		default:
+			return ErrFailedRemoval
+		}
+	}
+
+	return nil
+}
+
+func (sdk mfSDK) UpdateBootstrapState(id string, state int, token string) error {
+	data, err := json.Marshal(map[string]int{"state": state})
+	if err != nil {
+		return ErrInvalidArgs
+	}
+
+	endpoint := fmt.Sprintf("%s/%s", stateEndpoint, id)
+	url := createURL(sdk.bootstrapURL, "", endpoint)
+
+	req, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		switch resp.StatusCode {
+		case http.StatusBadRequest:
+			return ErrInvalidArgs
+		case http.StatusForbidden:
+			return ErrUnauthorized
+		case http.StatusNotFound:
+			return ErrNotFound
+		default:
+			return ErrFailedUpdate
+		}
+	}
+
+	return nil
+}
+
+// toBootstrapConfig converts the configuration returned by bootstrap service,
+// which refers to the things and channels by Mainflux IDs.
+func toBootstrapConfig(c bootstrapConfigRes) BootstrapConfig {
+	cfg := BootstrapConfig{
+		ThingID:     c.ThingID,
+		ExternalID:  c.ExternalID,
+		ExternalKey: c.ExternalKey,
+		Channels:    []string{},
+		Name:        c.Name,
+		Content:     c.Content,
+		State:       c.State,
+	}
+	for _, ch := range c.Channels {
+		cfg.Channels = append(cfg.Channels, ch.ID)
+	}
+
+	return cfg
+}
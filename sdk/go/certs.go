@@ -0,0 +1,169 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package sdk
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+)
+
+const certsEndpoint = "certs"
+
+func (sdk mfSDK) IssueCert(thingID, token string) (Cert, error) {
+	data, err := json.Marshal(map[string]string{"thing_id": thingID})
+	if err != nil {
+		return Cert{}, ErrInvalidArgs
+	}
+
+	url := createURL(sdk.certsURL, "", certsEndpoint)
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return Cert{}, err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return Cert{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return Cert{}, err
+	}
+
+	if resp.StatusCode != http.StatusCreated {
+		switch resp.StatusCode {
+		case http.StatusBadRequest:
+			return Cert{}, ErrInvalidArgs
+		case http.StatusForbidden:
+			return Cert{}, ErrUnauthorized
+		case http.StatusNotFound:
+			return Cert{}, ErrNotFound
+		default:
+			return Cert{}, ErrFailedCreation
+		}
+	}
+
+	var c Cert
+	if err := json.Unmarshal(body, &c); err != nil {
+		return Cert{}, err
+	}
+
+	return c, nil
+}
+
+func (sdk mfSDK) Cert(serial, token string) (Cert, error) {
+	endpoint := fmt.Sprintf("%s/%s", certsEndpoint, serial)
+	url := createURL(sdk.certsURL, "", endpoint)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return Cert{}, err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return Cert{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return Cert{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		switch resp.StatusCode {
+		case http.StatusForbidden:
+			return Cert{}, ErrUnauthorized
+		case http.StatusNotFound:
+			return Cert{}, ErrNotFound
+		default:
+			return Cert{}, ErrFetchFailed
+		}
+	}
+
+	var c Cert
+	if err := json.Unmarshal(body, &c); err != nil {
+		return Cert{}, err
+	}
+
+	return c, nil
+}
+
+func (sdk mfSDK) Certs(token, thingID string, offset, limit uint64) (CertsPage, error) {
+	endpoint := fmt.Sprintf("%s?offset=%d&limit=%d", certsEndpoint, offset, limit)
+	if thingID != "" {
+		endpoint = fmt.Sprintf("%s&thing=%s", endpoint, thingID)
+	}
+	url := createURL(sdk.certsURL, "", endpoint)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return CertsPage{}, err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return CertsPage{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return CertsPage{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		switch resp.StatusCode {
+		case http.StatusBadRequest:
+			return CertsPage{}, ErrInvalidArgs
+		case http.StatusForbidden:
+			return CertsPage{}, ErrUnauthorized
+		default:
+			return CertsPage{}, ErrFetchFailed
+		}
+	}
+
+	var cp CertsPage
+	if err := json.Unmarshal(body, &cp); err != nil {
+		return CertsPage{}, err
+	}
+
+	return cp, nil
+}
+
+func (sdk mfSDK) RevokeCert(serial, token string) error {
+	endpoint := fmt.Sprintf("%s/%s", certsEndpoint, serial)
+	url := createURL(sdk.certsURL, "", endpoint)
+
+	req, err := http.NewRequest(http.MethodDelete, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		switch resp.StatusCode {
+		case http.StatusForbidden:
+			return ErrUnauthorized
+		case http.StatusNotFound:
+			return ErrNotFound
+		default:
+			return ErrFailedRemoval
+		}
+	}
+
+	return nil
+}
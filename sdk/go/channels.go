@@ -235,3 +235,31 @@ func (sdk mfSDK) DeleteChannel(id, token string) error {
 
 	return nil
 }
+
+func (sdk mfSDK) PurgeChannel(id, token string) error {
+	endpoint := fmt.Sprintf("%s/%s/purge", channelsEndpoint, id)
+	url := createURL(sdk.baseURL, sdk.thingsPrefix, endpoint)
+
+	req, err := http.NewRequest(http.MethodDelete, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusNoContent {
+		switch resp.StatusCode {
+		case http.StatusForbidden:
+			return ErrUnauthorized
+		case http.StatusBadRequest:
+			return ErrInvalidArgs
+		default:
+			return ErrFailedRemoval
+		}
+	}
+
+	return nil
+}
@@ -129,7 +129,7 @@ func TestChannel(t *testing.T) {
 		respCh, err := mainfluxSDK.Channel(tc.chanID, tc.token)
 
 		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
-		assert.Equal(t, tc.response, respCh, fmt.Sprintf("%s: expected response channel %s, got %s", tc.desc, tc.response, respCh))
+		assert.Equal(t, tc.response, respCh, fmt.Sprintf("%s: expected response channel %v, got %v", tc.desc, tc.response, respCh))
 	}
 }
 
@@ -222,7 +222,7 @@ func TestChannels(t *testing.T) {
 	for _, tc := range cases {
 		page, err := mainfluxSDK.Channels(tc.token, tc.offset, tc.limit, tc.name)
 		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
-		assert.Equal(t, tc.response, page.Channels, fmt.Sprintf("%s: expected response channel %s, got %s", tc.desc, tc.response, page.Channels))
+		assert.Equal(t, tc.response, page.Channels, fmt.Sprintf("%s: expected response channel %v, got %v", tc.desc, tc.response, page.Channels))
 	}
 }
 
@@ -330,7 +330,7 @@ func TestChannelsByThing(t *testing.T) {
 	for _, tc := range cases {
 		page, err := mainfluxSDK.ChannelsByThing(tc.token, tc.thing, tc.offset, tc.limit)
 		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
-		assert.Equal(t, tc.response, page.Channels, fmt.Sprintf("%s: expected response channel %s, got %s", tc.desc, tc.response, page.Channels))
+		assert.Equal(t, tc.response, page.Channels, fmt.Sprintf("%s: expected response channel %v, got %v", tc.desc, tc.response, page.Channels))
 	}
 }
 
@@ -461,3 +461,61 @@ func TestDeleteChannel(t *testing.T) {
 		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
 	}
 }
+
+func TestPurgeChannel(t *testing.T) {
+	svc := newThingsService(map[string]string{token: email})
+	ts := newThingsServer(svc)
+	defer ts.Close()
+	sdkConf := sdk.Config{
+		BaseURL:           ts.URL,
+		UsersPrefix:       "",
+		ThingsPrefix:      "",
+		HTTPAdapterPrefix: "",
+		MsgContentType:    contentType,
+		TLSVerification:   false,
+	}
+
+	mainfluxSDK := sdk.NewSDK(sdkConf)
+	id, err := mainfluxSDK.CreateChannel(channel, token)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		id    string
+		token string
+		err   error
+	}{
+		{
+			desc:  "purge channel with invalid token",
+			id:    id,
+			token: wrongValue,
+			err:   sdk.ErrUnauthorized,
+		},
+		{
+			desc:  "purge channel with empty token",
+			id:    id,
+			token: "",
+			err:   sdk.ErrUnauthorized,
+		},
+		{
+			desc:  "purge existing channel",
+			id:    id,
+			token: token,
+			err:   nil,
+		},
+		{
+			desc:  "purge purged channel",
+			id:    id,
+			token: token,
+			err:   nil,
+		},
+	}
+
+	for _, tc := range cases {
+		err := mainfluxSDK.PurgeChannel(tc.id, tc.token)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
+	}
+
+	_, err = mainfluxSDK.Channel(id, token)
+	assert.Equal(t, sdk.ErrNotFound, err, fmt.Sprintf("view purged channel: expected error %s, got %s", sdk.ErrNotFound, err))
+}
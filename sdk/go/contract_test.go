@@ -0,0 +1,100 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package sdk_test
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	log "github.com/mainflux/mainflux/logger"
+	sdk "github.com/mainflux/mainflux/sdk/go"
+	"github.com/mainflux/mainflux/tools/contract"
+	"github.com/opentracing/opentracing-go/mocktracer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	httpapi "github.com/mainflux/mainflux/http/api"
+	"github.com/mainflux/mainflux/http/mocks"
+	usersapi "github.com/mainflux/mainflux/users/api/http"
+)
+
+const (
+	consumer = "sdk"
+
+	// envContractsDir is the directory the recorded contracts are saved
+	// to, so that they can be verified against the specifications of the
+	// customized services.
+	envContractsDir = "MF_SDK_CONTRACTS_DIR"
+)
+
+func verifyContract(t *testing.T, provider, specFile string, rec *contract.Recorder) {
+	spec, err := contract.LoadSpec(specFile)
+	require.Nil(t, err, fmt.Sprintf("unexpected error loading specification: %s", err))
+
+	c := contract.Contract{
+		Consumer:     consumer,
+		Provider:     provider,
+		Interactions: rec.Interactions(),
+	}
+	if dir := os.Getenv(envContractsDir); dir != "" {
+		err := c.Save(filepath.Join(dir, fmt.Sprintf("%s-%s.json", consumer, provider)))
+		require.Nil(t, err, fmt.Sprintf("unexpected error saving contract: %s", err))
+	}
+
+	rep := contract.Verify(spec, c)
+	assert.Empty(t, rep.Violations, fmt.Sprintf("%s: contract broken: %v", provider, rep.Violations))
+}
+
+func TestUsersContract(t *testing.T) {
+	logger, _ := log.New(os.Stdout, log.Info.String())
+	rec := contract.NewRecorder(usersapi.MakeHandler(newUserService(), mocktracer.New(), logger))
+	ts := httptest.NewServer(rec)
+	defer ts.Close()
+
+	mainfluxSDK := sdk.NewSDK(sdk.Config{
+		BaseURL:         ts.URL,
+		MsgContentType:  contentType,
+		TLSVerification: false,
+	})
+
+	user := sdk.User{Email: "user@example.com", Password: "password"}
+	err := mainfluxSDK.CreateUser(user)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = mainfluxSDK.CreateUser(user)
+	assert.Equal(t, sdk.ErrConflict, err, fmt.Sprintf("expected error %s got %s", sdk.ErrConflict, err))
+
+	_, err = mainfluxSDK.CreateToken(user)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	_, err = mainfluxSDK.CreateToken(sdk.User{Email: user.Email, Password: "wrong"})
+	assert.Equal(t, sdk.ErrUnauthorized, err, fmt.Sprintf("expected error %s got %s", sdk.ErrUnauthorized, err))
+
+	verifyContract(t, "users", "../../users/swagger.yaml", rec)
+}
+
+func TestHTTPAdapterContract(t *testing.T) {
+	chanID := "1"
+	token := "auth_token"
+	thingsClient := mocks.NewThingsClient(map[string]string{token: chanID})
+	rec := contract.NewRecorder(httpapi.MakeHandler(newMessageService(thingsClient), mocktracer.New(), httpapi.HeaderKey("Authorization"), time.Second, 0))
+	ts := httptest.NewServer(rec)
+	defer ts.Close()
+
+	mainfluxSDK := sdk.NewSDK(sdk.Config{
+		BaseURL:         ts.URL,
+		MsgContentType:  contentType,
+		TLSVerification: false,
+	})
+
+	msg := `[{"n":"current","t":-1,"v":1.6}]`
+	err := mainfluxSDK.SendMessage(chanID, msg, token)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = mainfluxSDK.SendMessage(chanID, msg, "invalid")
+	assert.Equal(t, sdk.ErrUnauthorized, err, fmt.Sprintf("expected error %s got %s", sdk.ErrUnauthorized, err))
+
+	verifyContract(t, "http", "../../http/swagger.yaml", rec)
+}
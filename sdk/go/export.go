@@ -0,0 +1,135 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package sdk
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+)
+
+const connectionsEndpoint = "connections"
+
+func (sdk mfSDK) ExportThings(token string) ([]Thing, error) {
+	things := []Thing{}
+	if err := sdk.export(thingsEndpoint, token, &things); err != nil {
+		return nil, err
+	}
+
+	return things, nil
+}
+
+func (sdk mfSDK) ImportThings(things []Thing, token string) (int, error) {
+	return sdk.importRecords(thingsEndpoint, things, token)
+}
+
+func (sdk mfSDK) ExportChannels(token string) ([]Channel, error) {
+	channels := []Channel{}
+	if err := sdk.export(channelsEndpoint, token, &channels); err != nil {
+		return nil, err
+	}
+
+	return channels, nil
+}
+
+func (sdk mfSDK) ImportChannels(channels []Channel, token string) (int, error) {
+	return sdk.importRecords(channelsEndpoint, channels, token)
+}
+
+func (sdk mfSDK) ExportConnections(token string) ([]Connection, error) {
+	conns := []Connection{}
+	if err := sdk.export(connectionsEndpoint, token, &conns); err != nil {
+		return nil, err
+	}
+
+	return conns, nil
+}
+
+func (sdk mfSDK) ImportConnections(conns []Connection, token string) (int, error) {
+	return sdk.importRecords(connectionsEndpoint, conns, token)
+}
+
+func (sdk mfSDK) export(entities, token string, records interface{}) error {
+	endpoint := fmt.Sprintf("%s/export?format=json", entities)
+	url := createURL(sdk.baseURL, sdk.thingsPrefix, endpoint)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		switch resp.StatusCode {
+		case http.StatusBadRequest:
+			return ErrInvalidArgs
+		case http.StatusForbidden:
+			return ErrUnauthorized
+		default:
+			return ErrFetchFailed
+		}
+	}
+
+	return json.Unmarshal(body, records)
+}
+
+func (sdk mfSDK) importRecords(entities string, records interface{}, token string) (int, error) {
+	data, err := json.Marshal(records)
+	if err != nil {
+		return 0, ErrInvalidArgs
+	}
+
+	endpoint := fmt.Sprintf("%s/import", entities)
+	url := createURL(sdk.baseURL, sdk.thingsPrefix, endpoint)
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return 0, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		switch resp.StatusCode {
+		case http.StatusBadRequest:
+			return 0, ErrInvalidArgs
+		case http.StatusForbidden:
+			return 0, ErrUnauthorized
+		case http.StatusNotFound:
+			return 0, ErrNotFound
+		case http.StatusUnprocessableEntity:
+			return 0, ErrConflict
+		default:
+			return 0, ErrFailedCreation
+		}
+	}
+
+	var r importRes
+	if err := json.Unmarshal(body, &r); err != nil {
+		return 0, err
+	}
+
+	return r.Imported, nil
+}
@@ -0,0 +1,97 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package sdk_test
+
+import (
+	"fmt"
+	"testing"
+
+	sdk "github.com/mainflux/mainflux/sdk/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportImport(t *testing.T) {
+	svc := newThingsService(map[string]string{token: email, otherToken: otherEmail})
+	ts := newThingsServer(svc)
+	defer ts.Close()
+	sdkConf := sdk.Config{
+		BaseURL:           ts.URL,
+		UsersPrefix:       "",
+		ThingsPrefix:      "",
+		HTTPAdapterPrefix: "",
+		MsgContentType:    contentType,
+		TLSVerification:   false,
+	}
+
+	mainfluxSDK := sdk.NewSDK(sdkConf)
+
+	th := sdk.Thing{ID: "1", Name: "test_device", Key: keyPrefix + "000000000001", Metadata: metadata}
+	ch := sdk.Channel{ID: "1", Name: "test_channel", Metadata: metadata}
+	conn := sdk.Connection{ChannelID: ch.ID, ThingID: th.ID, Actions: []string{"publish", "subscribe"}}
+
+	n, err := mainfluxSDK.ImportThings([]sdk.Thing{th}, wrongValue)
+	assert.Equal(t, sdk.ErrUnauthorized, err, fmt.Sprintf("import things with invalid token: expected error %s, got %s", sdk.ErrUnauthorized, err))
+	assert.Equal(t, 0, n, fmt.Sprintf("import things with invalid token: expected 0 imported, got %d", n))
+
+	n, err = mainfluxSDK.ImportThings([]sdk.Thing{th}, token)
+	require.Nil(t, err, fmt.Sprintf("import things: unexpected error: %s", err))
+	assert.Equal(t, 1, n, fmt.Sprintf("import things: expected 1 imported, got %d", n))
+
+	n, err = mainfluxSDK.ImportChannels([]sdk.Channel{ch}, token)
+	require.Nil(t, err, fmt.Sprintf("import channels: unexpected error: %s", err))
+	assert.Equal(t, 1, n, fmt.Sprintf("import channels: expected 1 imported, got %d", n))
+
+	n, err = mainfluxSDK.ImportConnections([]sdk.Connection{conn}, token)
+	require.Nil(t, err, fmt.Sprintf("import connections: unexpected error: %s", err))
+	assert.Equal(t, 1, n, fmt.Sprintf("import connections: expected 1 imported, got %d", n))
+
+	cases := []struct {
+		desc     string
+		token    string
+		things   []sdk.Thing
+		channels []sdk.Channel
+		conns    []sdk.Connection
+		err      error
+	}{
+		{
+			desc:     "export entities of the owner",
+			token:    token,
+			things:   []sdk.Thing{th},
+			channels: []sdk.Channel{ch},
+			conns:    []sdk.Connection{conn},
+			err:      nil,
+		},
+		{
+			desc:     "export entities of other user",
+			token:    otherToken,
+			things:   []sdk.Thing{},
+			channels: []sdk.Channel{},
+			conns:    []sdk.Connection{},
+			err:      nil,
+		},
+		{
+			desc:     "export entities with invalid token",
+			token:    wrongValue,
+			things:   nil,
+			channels: nil,
+			conns:    nil,
+			err:      sdk.ErrUnauthorized,
+		},
+	}
+
+	for _, tc := range cases {
+		things, err := mainfluxSDK.ExportThings(tc.token)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
+		assert.Equal(t, tc.things, things, fmt.Sprintf("%s: expected things %v, got %v", tc.desc, tc.things, things))
+
+		channels, err := mainfluxSDK.ExportChannels(tc.token)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
+		assert.Equal(t, tc.channels, channels, fmt.Sprintf("%s: expected channels %v, got %v", tc.desc, tc.channels, channels))
+
+		conns, err := mainfluxSDK.ExportConnections(tc.token)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
+		assert.Equal(t, tc.conns, conns, fmt.Sprintf("%s: expected connections %v, got %v", tc.desc, tc.conns, conns))
+	}
+}
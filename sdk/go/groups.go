@@ -0,0 +1,215 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package sdk
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+const groupsEndpoint = "groups"
+
+func (sdk mfSDK) CreateGroup(group Group, token string) (string, error) {
+	data, err := json.Marshal(Group{Name: group.Name})
+	if err != nil {
+		return "", ErrInvalidArgs
+	}
+
+	url := createURL(sdk.baseURL, sdk.usersPrefix, groupsEndpoint)
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		switch resp.StatusCode {
+		case http.StatusBadRequest:
+			return "", ErrInvalidArgs
+		case http.StatusForbidden:
+			return "", ErrUnauthorized
+		case http.StatusConflict:
+			return "", ErrConflict
+		default:
+			return "", ErrFailedCreation
+		}
+	}
+
+	id := strings.TrimPrefix(resp.Header.Get("Location"), fmt.Sprintf("/%s/", groupsEndpoint))
+	return id, nil
+}
+
+func (sdk mfSDK) Groups(token string) ([]Group, error) {
+	url := createURL(sdk.baseURL, sdk.usersPrefix, groupsEndpoint)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		switch resp.StatusCode {
+		case http.StatusForbidden:
+			return nil, ErrUnauthorized
+		default:
+			return nil, ErrFetchFailed
+		}
+	}
+
+	var gr groupsRes
+	if err := json.Unmarshal(body, &gr); err != nil {
+		return nil, err
+	}
+
+	return gr.Groups, nil
+}
+
+func (sdk mfSDK) Group(id, token string) (Group, error) {
+	endpoint := fmt.Sprintf("%s/%s", groupsEndpoint, id)
+	url := createURL(sdk.baseURL, sdk.usersPrefix, endpoint)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return Group{}, err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return Group{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return Group{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		switch resp.StatusCode {
+		case http.StatusForbidden:
+			return Group{}, ErrUnauthorized
+		case http.StatusNotFound:
+			return Group{}, ErrNotFound
+		default:
+			return Group{}, ErrFetchFailed
+		}
+	}
+
+	var g Group
+	if err := json.Unmarshal(body, &g); err != nil {
+		return Group{}, err
+	}
+
+	return g, nil
+}
+
+func (sdk mfSDK) DeleteGroup(id, token string) error {
+	endpoint := fmt.Sprintf("%s/%s", groupsEndpoint, id)
+	url := createURL(sdk.baseURL, sdk.usersPrefix, endpoint)
+
+	req, err := http.NewRequest(http.MethodDelete, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		switch resp.StatusCode {
+		case http.StatusForbidden:
+			return ErrUnauthorized
+		case http.StatusNotFound:
+			return ErrNotFound
+		default:
+			return ErrFailedRemoval
+		}
+	}
+
+	return nil
+}
+
+func (sdk mfSDK) AssignMember(id, email, token string) error {
+	endpoint := fmt.Sprintf("%s/%s/members/%s", groupsEndpoint, id, email)
+	url := createURL(sdk.baseURL, sdk.usersPrefix, endpoint)
+
+	req, err := http.NewRequest(http.MethodPut, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		switch resp.StatusCode {
+		case http.StatusForbidden:
+			return ErrUnauthorized
+		case http.StatusNotFound:
+			return ErrNotFound
+		case http.StatusConflict:
+			return ErrConflict
+		default:
+			return ErrFailedUpdate
+		}
+	}
+
+	return nil
+}
+
+func (sdk mfSDK) UnassignMember(id, email, token string) error {
+	endpoint := fmt.Sprintf("%s/%s/members/%s", groupsEndpoint, id, email)
+	url := createURL(sdk.baseURL, sdk.usersPrefix, endpoint)
+
+	req, err := http.NewRequest(http.MethodDelete, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		switch resp.StatusCode {
+		case http.StatusForbidden:
+			return ErrUnauthorized
+		case http.StatusNotFound:
+			return ErrNotFound
+		default:
+			return ErrFailedRemoval
+		}
+	}
+
+	return nil
+}
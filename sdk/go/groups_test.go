@@ -0,0 +1,131 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package sdk_test
+
+import (
+	"fmt"
+	"testing"
+
+	sdk "github.com/mainflux/mainflux/sdk/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateGroup(t *testing.T) {
+	svc := newUserService()
+	ts := newUserServer(svc)
+	defer ts.Close()
+	mainfluxSDK, _, userToken := newAdminSDK(t, ts.URL)
+
+	cases := []struct {
+		desc  string
+		group sdk.Group
+		token string
+		err   error
+	}{
+		{
+			desc:  "create new group",
+			group: sdk.Group{Name: "group"},
+			token: userToken,
+			err:   nil,
+		},
+		{
+			desc:  "create new group with empty token",
+			group: sdk.Group{Name: "group"},
+			token: "",
+			err:   sdk.ErrUnauthorized,
+		},
+	}
+
+	for _, tc := range cases {
+		id, err := mainfluxSDK.CreateGroup(tc.group, tc.token)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
+		if err == nil {
+			assert.NotEmpty(t, id, fmt.Sprintf("%s: expected non-empty group id", tc.desc))
+		}
+	}
+}
+
+func TestGroup(t *testing.T) {
+	svc := newUserService()
+	ts := newUserServer(svc)
+	defer ts.Close()
+	mainfluxSDK, adminToken, userToken := newAdminSDK(t, ts.URL)
+
+	id, err := mainfluxSDK.CreateGroup(sdk.Group{Name: "group"}, userToken)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		id    string
+		token string
+		err   error
+	}{
+		{
+			desc:  "view existing group",
+			id:    id,
+			token: userToken,
+			err:   nil,
+		},
+		{
+			desc:  "view group as non-member",
+			id:    id,
+			token: adminToken,
+			err:   sdk.ErrNotFound,
+		},
+		{
+			desc:  "view non-existent group",
+			id:    wrongValue,
+			token: userToken,
+			err:   sdk.ErrNotFound,
+		},
+		{
+			desc:  "view group with empty token",
+			id:    id,
+			token: "",
+			err:   sdk.ErrUnauthorized,
+		},
+	}
+
+	for _, tc := range cases {
+		g, err := mainfluxSDK.Group(tc.id, tc.token)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
+		if err == nil {
+			assert.Equal(t, "group", g.Name, fmt.Sprintf("%s: expected group name group, got %s", tc.desc, g.Name))
+		}
+	}
+}
+
+func TestGroupMembers(t *testing.T) {
+	svc := newUserService()
+	ts := newUserServer(svc)
+	defer ts.Close()
+	mainfluxSDK, adminToken, userToken := newAdminSDK(t, ts.URL)
+
+	id, err := mainfluxSDK.CreateGroup(sdk.Group{Name: "group"}, userToken)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	err = mainfluxSDK.AssignMember(id, adminEmail, userToken)
+	assert.Nil(t, err, fmt.Sprintf("assign member: unexpected error: %s", err))
+
+	groups, err := mainfluxSDK.Groups(adminToken)
+	assert.Nil(t, err, fmt.Sprintf("list groups: unexpected error: %s", err))
+	assert.Len(t, groups, 1, fmt.Sprintf("list groups: expected 1 group, got %d", len(groups)))
+
+	err = mainfluxSDK.AssignMember(id, adminEmail, adminToken)
+	assert.Equal(t, sdk.ErrNotFound, err, fmt.Sprintf("assign member as non-owner: expected error %s, got %s", sdk.ErrNotFound, err))
+
+	err = mainfluxSDK.UnassignMember(id, adminEmail, userToken)
+	assert.Nil(t, err, fmt.Sprintf("unassign member: unexpected error: %s", err))
+
+	groups, err = mainfluxSDK.Groups(adminToken)
+	assert.Nil(t, err, fmt.Sprintf("list groups: unexpected error: %s", err))
+	assert.Len(t, groups, 0, fmt.Sprintf("list groups: expected no groups, got %d", len(groups)))
+
+	err = mainfluxSDK.DeleteGroup(id, userToken)
+	assert.Nil(t, err, fmt.Sprintf("delete group: unexpected error: %s", err))
+
+	_, err = mainfluxSDK.Group(id, userToken)
+	assert.Equal(t, sdk.ErrNotFound, err, fmt.Sprintf("view deleted group: expected error %s, got %s", sdk.ErrNotFound, err))
+}
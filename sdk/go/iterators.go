@@ -0,0 +1,232 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package sdk
+
+import "github.com/mainflux/mainflux"
+
+// iteratorLimit is the size of the pages fetched by the iterators, equal to
+// the maximal page size of the services.
+const iteratorLimit = 100
+
+// iterator keeps the position of the iteration over the paged entities.
+type iterator struct {
+	offset  uint64
+	total   uint64
+	size    int
+	idx     int
+	err     error
+	fetched bool
+}
+
+// next advances to the next entity, calling fetch to retrieve the next page
+// once the current one is exhausted. Fetch returns the size of the page and
+// the total number of entities.
+func (it *iterator) next(fetch func(offset, limit uint64) (int, uint64, error)) bool {
+	if it.err != nil {
+		return false
+	}
+
+	it.idx++
+	if it.idx < it.size {
+		return true
+	}
+
+	if it.fetched && it.offset >= it.total {
+		return false
+	}
+
+	size, total, err := fetch(it.offset, iteratorLimit)
+	if err != nil {
+		it.err = err
+		return false
+	}
+
+	it.fetched = true
+	it.size, it.total, it.idx = size, total, 0
+	it.offset += uint64(size)
+
+	return size > 0
+}
+
+// Err returns the error which stopped the iteration, if any.
+func (it *iterator) Err() error {
+	return it.err
+}
+
+// ThingsIterator iterates over the things, fetching them page by page.
+//
+//	it := sdk.ThingsIterator(token, "")
+//	for it.Next() {
+//		th := it.Thing()
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type ThingsIterator struct {
+	iterator
+	sdk    SDK
+	token  string
+	name   string
+	things []Thing
+}
+
+// Next advances to the next thing, returning false when there are no more
+// things or the error occurred.
+func (it *ThingsIterator) Next() bool {
+	return it.next(func(offset, limit uint64) (int, uint64, error) {
+		page, err := it.sdk.Things(it.token, offset, limit, it.name)
+		it.things = page.Things
+		return len(page.Things), page.Total, err
+	})
+}
+
+// Thing returns the current thing.
+func (it *ThingsIterator) Thing() Thing {
+	return it.things[it.idx]
+}
+
+// ChannelsIterator iterates over the channels, fetching them page by page.
+type ChannelsIterator struct {
+	iterator
+	sdk      SDK
+	token    string
+	name     string
+	channels []Channel
+}
+
+// Next advances to the next channel, returning false when there are no
+// more channels or the error occurred.
+func (it *ChannelsIterator) Next() bool {
+	return it.next(func(offset, limit uint64) (int, uint64, error) {
+		page, err := it.sdk.Channels(it.token, offset, limit, it.name)
+		it.channels = page.Channels
+		return len(page.Channels), page.Total, err
+	})
+}
+
+// Channel returns the current channel.
+func (it *ChannelsIterator) Channel() Channel {
+	return it.channels[it.idx]
+}
+
+// UsersIterator iterates over the users, fetching them page by page.
+type UsersIterator struct {
+	iterator
+	sdk   SDK
+	token string
+	users []User
+}
+
+// Next advances to the next user, returning false when there are no more
+// users or the error occurred.
+func (it *UsersIterator) Next() bool {
+	return it.next(func(offset, limit uint64) (int, uint64, error) {
+		page, err := it.sdk.Users(it.token, offset, limit)
+		it.users = page.Users
+		return len(page.Users), page.Total, err
+	})
+}
+
+// User returns the current user.
+func (it *UsersIterator) User() User {
+	return it.users[it.idx]
+}
+
+// MessagesIterator iterates over the messages of the channel, fetching
+// them page by page.
+type MessagesIterator struct {
+	iterator
+	sdk      SDK
+	chanID   string
+	token    string
+	messages []mainflux.Message
+}
+
+// Next advances to the next message, returning false when there are no
+// more messages or the error occurred.
+func (it *MessagesIterator) Next() bool {
+	return it.next(func(offset, limit uint64) (int, uint64, error) {
+		page, err := it.sdk.Messages(it.chanID, it.token, offset, limit)
+		it.messages = page.Messages
+		return len(page.Messages), page.Total, err
+	})
+}
+
+// Message returns the current message.
+func (it *MessagesIterator) Message() mainflux.Message {
+	return it.messages[it.idx]
+}
+
+// BootstrapIterator iterates over the bootstrap configurations, fetching
+// them page by page.
+type BootstrapIterator struct {
+	iterator
+	sdk     SDK
+	token   string
+	configs []BootstrapConfig
+}
+
+// Next advances to the next configuration, returning false when there are
+// no more configurations or the error occurred.
+func (it *BootstrapIterator) Next() bool {
+	return it.next(func(offset, limit uint64) (int, uint64, error) {
+		page, err := it.sdk.BootstrapConfigs(it.token, offset, limit)
+		it.configs = page.Configs
+		return len(page.Configs), page.Total, err
+	})
+}
+
+// Config returns the current configuration.
+func (it *BootstrapIterator) Config() BootstrapConfig {
+	return it.configs[it.idx]
+}
+
+// CertsIterator iterates over the certificates, fetching them page by
+// page.
+type CertsIterator struct {
+	iterator
+	sdk     SDK
+	token   string
+	thingID string
+	certs   []Cert
+}
+
+// Next advances to the next certificate, returning false when there are
+// no more certificates or the error occurred.
+func (it *CertsIterator) Next() bool {
+	return it.next(func(offset, limit uint64) (int, uint64, error) {
+		page, err := it.sdk.Certs(it.token, it.thingID, offset, limit)
+		it.certs = page.Certs
+		return len(page.Certs), page.Total, err
+	})
+}
+
+// Cert returns the current certificate.
+func (it *CertsIterator) Cert() Cert {
+	return it.certs[it.idx]
+}
+
+func (sdk *mfSDK) ThingsIterator(token, name string) *ThingsIterator {
+	return &ThingsIterator{sdk: sdk, token: token, name: name}
+}
+
+func (sdk *mfSDK) ChannelsIterator(token, name string) *ChannelsIterator {
+	return &ChannelsIterator{sdk: sdk, token: token, name: name}
+}
+
+func (sdk *mfSDK) UsersIterator(token string) *UsersIterator {
+	return &UsersIterator{sdk: sdk, token: token}
+}
+
+func (sdk *mfSDK) MessagesIterator(chanID, token string) *MessagesIterator {
+	return &MessagesIterator{sdk: sdk, chanID: chanID, token: token}
+}
+
+func (sdk *mfSDK) BootstrapIterator(token string) *BootstrapIterator {
+	return &BootstrapIterator{sdk: sdk, token: token}
+}
+
+func (sdk *mfSDK) CertsIterator(token, thingID string) *CertsIterator {
+	return &CertsIterator{sdk: sdk, token: token, thingID: thingID}
+}
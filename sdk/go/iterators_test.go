@@ -0,0 +1,67 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package sdk_test
+
+import (
+	"fmt"
+	"testing"
+
+	sdk "github.com/mainflux/mainflux/sdk/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestThingsIterator(t *testing.T) {
+	svc := newThingsService(map[string]string{token: email})
+	ts := newThingsServer(svc)
+	defer ts.Close()
+
+	sdkConf := sdk.Config{
+		BaseURL:           ts.URL,
+		UsersPrefix:       "",
+		ThingsPrefix:      "",
+		HTTPAdapterPrefix: "",
+		MsgContentType:    contentType,
+		TLSVerification:   false,
+	}
+
+	mainfluxSDK := sdk.NewSDK(sdkConf)
+
+	n := 250
+	for i := 0; i < n; i++ {
+		th := sdk.Thing{Name: fmt.Sprintf("test_device_%d", i), Metadata: metadata}
+		_, err := mainfluxSDK.CreateThing(th, token)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	cases := []struct {
+		desc  string
+		token string
+		size  int
+		err   error
+	}{
+		{
+			desc:  "iterate over all things",
+			token: token,
+			size:  n,
+			err:   nil,
+		},
+		{
+			desc:  "iterate over things with invalid token",
+			token: wrongValue,
+			size:  0,
+			err:   sdk.ErrUnauthorized,
+		},
+	}
+
+	for _, tc := range cases {
+		it := mainfluxSDK.ThingsIterator(tc.token, "")
+		ids := map[string]bool{}
+		for it.Next() {
+			ids[it.Thing().ID] = true
+		}
+		assert.Equal(t, tc.err, it.Err(), fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, it.Err()))
+		assert.Len(t, ids, tc.size, fmt.Sprintf("%s: expected %d things, got %d", tc.desc, tc.size, len(ids)))
+	}
+}
@@ -48,14 +48,28 @@ func (sdk mfSDK) SendMessage(chanName, msg, token string) error {
 }
 
 func (sdk mfSDK) ReadMessages(chanName, token string) (MessagesPage, error) {
+	return sdk.readMessages(chanName, token, "")
+}
+
+func (sdk mfSDK) Messages(chanName, token string, offset, limit uint64) (MessagesPage, error) {
+	return sdk.readMessages(chanName, token, fmt.Sprintf("offset=%d&limit=%d", offset, limit))
+}
+
+func (sdk mfSDK) readMessages(chanName, token, query string) (MessagesPage, error) {
 	chanNameParts := strings.SplitN(chanName, ".", 2)
 	chanID := chanNameParts[0]
-	subtopicPart := ""
+	params := []string{}
 	if len(chanNameParts) == 2 {
-		subtopicPart = fmt.Sprintf("?subtopic=%s", strings.Replace(chanNameParts[1], ".", "/", -1))
+		params = append(params, fmt.Sprintf("subtopic=%s", strings.Replace(chanNameParts[1], ".", "/", -1)))
+	}
+	if query != "" {
+		params = append(params, query)
 	}
 
-	endpoint := fmt.Sprintf("channels/%s/messages%s", chanID, subtopicPart)
+	endpoint := fmt.Sprintf("channels/%s/messages", chanID)
+	if len(params) > 0 {
+		endpoint = fmt.Sprintf("%s?%s", endpoint, strings.Join(params, "&"))
+	}
 	url := createURL(sdk.readerURL, "", endpoint)
 
 	req, err := http.NewRequest(http.MethodGet, url, nil)
@@ -67,6 +81,7 @@ func (sdk mfSDK) ReadMessages(chanName, token string) (MessagesPage, error) {
 	if err != nil {
 		return MessagesPage{}, err
 	}
+	defer resp.Body.Close()
 
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
@@ -97,8 +112,49 @@ func (sdk mfSDK) ReadMessages(chanName, token string) (MessagesPage, error) {
 	}, nil
 }
 
+func (sdk mfSDK) Subtopics(chanID, token string) ([]Subtopic, error) {
+	endpoint := fmt.Sprintf("channels/%s/subtopics", chanID)
+	url := createURL(sdk.readerURL, "", endpoint)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		switch resp.StatusCode {
+		case http.StatusBadRequest:
+			return nil, ErrInvalidArgs
+		case http.StatusForbidden:
+			return nil, ErrUnauthorized
+		case http.StatusNotFound:
+			return nil, ErrNotFound
+		default:
+			return nil, ErrFailedRead
+		}
+	}
+
+	var sr subtopicsRes
+	if err := json.Unmarshal(body, &sr); err != nil {
+		return nil, err
+	}
+
+	return sr.Subtopics, nil
+}
+
 func (sdk *mfSDK) SetContentType(ct ContentType) error {
-	if ct != CTJSON && ct != CTJSONSenML && ct != CTBinary {
+	if ct != CTJSON && ct != CTJSONSenML && ct != CTCBORSenML && ct != CTBinary {
 		return ErrInvalidContentType
 	}
 
@@ -7,23 +7,25 @@ import (
 	"fmt"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/mainflux/mainflux"
 	adapter "github.com/mainflux/mainflux/http"
 	"github.com/mainflux/mainflux/http/api"
 	"github.com/mainflux/mainflux/http/mocks"
 	sdk "github.com/mainflux/mainflux/sdk/go"
+	"github.com/mainflux/mainflux/things/uuid"
 	"github.com/opentracing/opentracing-go/mocktracer"
 	"github.com/stretchr/testify/assert"
 )
 
-func newMessageService(cc mainflux.ThingsServiceClient) mainflux.MessagePublisher {
-	pub := mocks.NewPublisher()
-	return adapter.New(pub, cc)
+func newMessageService(cc mainflux.ThingsServiceClient) adapter.Service {
+	pubsub := mocks.NewPubSub(nil)
+	return adapter.New(pubsub, pubsub, cc, uuid.New())
 }
 
-func newMessageServer(pub mainflux.MessagePublisher) *httptest.Server {
-	mux := api.MakeHandler(pub, mocktracer.New())
+func newMessageServer(svc adapter.Service) *httptest.Server {
+	mux := api.MakeHandler(svc, mocktracer.New(), api.HeaderKey("Authorization"), time.Second, 0)
 	return httptest.NewServer(mux)
 }
 
@@ -125,6 +127,11 @@ func TestSetContentType(t *testing.T) {
 			cType: "application/senml+json",
 			err:   nil,
 		},
+		{
+			desc:  "set senml+cbor content type",
+			cType: "application/senml+cbor",
+			err:   nil,
+		},
 		{
 			desc:  "set invalid content type",
 			cType: "invalid",
@@ -0,0 +1,141 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package sdk_test
+
+import (
+	"fmt"
+	"testing"
+
+	sdk "github.com/mainflux/mainflux/sdk/go"
+	"github.com/mainflux/mainflux/things"
+	"github.com/mainflux/mainflux/things/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	adminEmail = "admin@example.com"
+	adminToken = "admin_token"
+)
+
+func newQuotasService(tokens map[string]string) things.Service {
+	users := mocks.NewUsersService(tokens)
+	conns := make(chan mocks.Connection)
+	thingsRepo := mocks.NewThingRepository(conns)
+	channelsRepo := mocks.NewChannelRepository(thingsRepo, conns)
+	quotasRepo := mocks.NewQuotaRepository(thingsRepo, channelsRepo)
+	schemasRepo := mocks.NewSchemaRepository()
+	chanCache := mocks.NewChannelCache()
+	thingCache := mocks.NewThingCache()
+	idp := mocks.NewIdentityProvider()
+
+	return things.New(users, thingsRepo, channelsRepo, quotasRepo, schemasRepo, chanCache, thingCache, idp, nil, things.Quota{}, []string{adminEmail}, 0, 0, nil, nil, nil)
+}
+
+func TestUpdateQuota(t *testing.T) {
+	svc := newQuotasService(map[string]string{token: email, adminToken: adminEmail})
+	ts := newThingsServer(svc)
+	defer ts.Close()
+	sdkConf := sdk.Config{
+		BaseURL:           ts.URL,
+		UsersPrefix:       "",
+		ThingsPrefix:      "",
+		HTTPAdapterPrefix: "",
+		MsgContentType:    contentType,
+		TLSVerification:   false,
+	}
+
+	mainfluxSDK := sdk.NewSDK(sdkConf)
+	quota := sdk.Quota{Things: 1}
+
+	cases := []struct {
+		desc  string
+		owner string
+		token string
+		err   error
+	}{
+		{
+			desc:  "update quota as non-admin",
+			owner: email,
+			token: token,
+			err:   sdk.ErrUnauthorized,
+		},
+		{
+			desc:  "update quota with invalid token",
+			owner: email,
+			token: wrongValue,
+			err:   sdk.ErrUnauthorized,
+		},
+		{
+			desc:  "update quota as admin",
+			owner: email,
+			token: adminToken,
+			err:   nil,
+		},
+	}
+
+	for _, tc := range cases {
+		err := mainfluxSDK.UpdateQuota(tc.owner, quota, tc.token)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
+	}
+
+	_, err := mainfluxSDK.CreateThing(thing, token)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	_, err = mainfluxSDK.CreateThing(thing, token)
+	assert.Equal(t, sdk.ErrFailedCreation, err, fmt.Sprintf("create thing above quota: expected error %s, got %s", sdk.ErrFailedCreation, err))
+}
+
+func TestRemoveQuota(t *testing.T) {
+	svc := newQuotasService(map[string]string{token: email, adminToken: adminEmail})
+	ts := newThingsServer(svc)
+	defer ts.Close()
+	sdkConf := sdk.Config{
+		BaseURL:           ts.URL,
+		UsersPrefix:       "",
+		ThingsPrefix:      "",
+		HTTPAdapterPrefix: "",
+		MsgContentType:    contentType,
+		TLSVerification:   false,
+	}
+
+	mainfluxSDK := sdk.NewSDK(sdkConf)
+	err := mainfluxSDK.UpdateQuota(email, sdk.Quota{Things: 1}, adminToken)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		owner string
+		token string
+		err   error
+	}{
+		{
+			desc:  "remove quota as non-admin",
+			owner: email,
+			token: token,
+			err:   sdk.ErrUnauthorized,
+		},
+		{
+			desc:  "remove quota as admin",
+			owner: email,
+			token: adminToken,
+			err:   nil,
+		},
+		{
+			desc:  "remove removed quota",
+			owner: email,
+			token: adminToken,
+			err:   nil,
+		},
+	}
+
+	for _, tc := range cases {
+		err := mainfluxSDK.RemoveQuota(tc.owner, tc.token)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
+	}
+
+	for i := 0; i < 2; i++ {
+		_, err := mainfluxSDK.CreateThing(thing, token)
+		assert.Nil(t, err, fmt.Sprintf("create thing without quota: unexpected error: %s", err))
+	}
+}
@@ -9,6 +9,35 @@ type tokenRes struct {
 	Token string `json:"token,omitempty"`
 }
 
+type roleRes struct {
+	Role string `json:"role"`
+}
+
+type importRes struct {
+	Imported int `json:"imported"`
+}
+
+type bootstrapChannelRes struct {
+	ID string `json:"id"`
+}
+
+type bootstrapConfigRes struct {
+	ThingID     string                `json:"mainflux_id"`
+	Channels    []bootstrapChannelRes `json:"mainflux_channels"`
+	ExternalID  string                `json:"external_id"`
+	ExternalKey string                `json:"external_key"`
+	Content     string                `json:"content"`
+	Name        string                `json:"name"`
+	State       int                   `json:"state"`
+}
+
+type bootstrapPageRes struct {
+	Total   uint64               `json:"total"`
+	Offset  uint64               `json:"offset"`
+	Limit   uint64               `json:"limit"`
+	Configs []bootstrapConfigRes `json:"configs"`
+}
+
 type thingsPageRes struct {
 	Things []Thing `json:"things,omitempty"`
 	Total  uint64  `json:"total"`
@@ -29,3 +58,15 @@ type messagesPageRes struct {
 	Limit    uint64             `json:"limit"`
 	Messages []mainflux.Message `json:"messages,omitempty"`
 }
+
+type simulationsRes struct {
+	Simulations []Simulation `json:"simulations"`
+}
+
+type groupsRes struct {
+	Groups []Group `json:"groups"`
+}
+
+type subtopicsRes struct {
+	Subtopics []Subtopic `json:"subtopics"`
+}
@@ -4,10 +4,14 @@
 package sdk
 
 import (
+	"context"
 	"crypto/tls"
 	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
+	"time"
 
 	"github.com/mainflux/mainflux"
 )
@@ -19,6 +23,9 @@ const (
 	// CTJSONSenML represents JSON SenML content type.
 	CTJSONSenML ContentType = "application/senml+json"
 
+	// CTCBORSenML represents CBOR SenML content type.
+	CTCBORSenML ContentType = "application/senml+cbor"
+
 	// CTBinary represents binary content type.
 	CTBinary ContentType = "application/octet-stream"
 )
@@ -64,6 +71,14 @@ var (
 	// ErrInvalidContentType indicates that nonexistent message content type
 	// was passed.
 	ErrInvalidContentType = errors.New("Unknown Content Type")
+
+	// ErrUnsupportedProtocol indicates that the streaming protocol is
+	// neither WebSocket nor MQTT.
+	ErrUnsupportedProtocol = errors.New("unsupported streaming protocol")
+
+	// ErrSubscriptionClosed indicates that the subscription is already
+	// closed.
+	ErrSubscriptionClosed = errors.New("subscription closed")
 )
 
 // ContentType represents all possible content types.
@@ -73,8 +88,18 @@ var _ SDK = (*mfSDK)(nil)
 
 // User represents mainflux user its credentials.
 type User struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Email    string                 `json:"email"`
+	Password string                 `json:"password,omitempty"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	Disabled bool                   `json:"disabled,omitempty"`
+}
+
+// UsersPage contains list of users in a page with proper metadata.
+type UsersPage struct {
+	Users  []User `json:"users"`
+	Total  uint64 `json:"total"`
+	Offset uint64 `json:"offset"`
+	Limit  uint64 `json:"limit"`
 }
 
 // Thing represents mainflux thing.
@@ -95,9 +120,93 @@ type ThingsPage struct {
 
 // Channel represents mainflux channel.
 type Channel struct {
-	ID       string                 `json:"id,omitempty"`
-	Name     string                 `json:"name"`
-	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	ID        string                 `json:"id,omitempty"`
+	Name      string                 `json:"name"`
+	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+	Retention *Retention             `json:"retention,omitempty"`
+}
+
+// Retention limits the messages of the channel kept by the message writers.
+// Period is expressed in seconds. Zero values impose no limit.
+type Retention struct {
+	Period   int64  `json:"period,omitempty"`
+	Messages uint64 `json:"messages,omitempty"`
+}
+
+// Quota limits the number of things, channels and connections the owner may
+// have. Zero limit means that the number of entities is not limited.
+type Quota struct {
+	Things      uint64 `json:"things"`
+	Channels    uint64 `json:"channels"`
+	Connections uint64 `json:"connections"`
+}
+
+// Group represents the group of users.
+type Group struct {
+	ID      string   `json:"id,omitempty"`
+	Owner   string   `json:"owner,omitempty"`
+	Name    string   `json:"name"`
+	Members []string `json:"members,omitempty"`
+}
+
+// Cert represents the client certificate of the thing. Private key and
+// issuing CA are returned only when the certificate is issued.
+type Cert struct {
+	Serial      string     `json:"serial"`
+	ThingID     string     `json:"thing_id"`
+	Certificate string     `json:"certificate"`
+	PrivateKey  string     `json:"private_key,omitempty"`
+	IssuingCA   string     `json:"issuing_ca,omitempty"`
+	Expires     time.Time  `json:"expires"`
+	Revoked     bool       `json:"revoked"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty"`
+}
+
+// CertsPage contains list of certificates in a page with proper metadata.
+type CertsPage struct {
+	Certs  []Cert `json:"certs"`
+	Total  uint64 `json:"total"`
+	Offset uint64 `json:"offset"`
+	Limit  uint64 `json:"limit"`
+}
+
+// Subtopic represents the subtopic of the channel, along with the time of
+// the last message published to it, expressed in seconds.
+type Subtopic struct {
+	Name     string  `json:"name"`
+	LastSeen float64 `json:"last_seen"`
+}
+
+// Connection represents the connection of the thing to the channel, along
+// with the actions the thing is permitted to perform on the channel.
+type Connection struct {
+	ChannelID string   `json:"channel_id"`
+	ThingID   string   `json:"thing_id"`
+	Actions   []string `json:"actions,omitempty"`
+}
+
+// BootstrapConfig represents the configuration the thing retrieves from the
+// bootstrap service. State is 0 for inactive and 1 for active thing.
+type BootstrapConfig struct {
+	ThingID     string   `json:"thing_id,omitempty"`
+	ExternalID  string   `json:"external_id"`
+	ExternalKey string   `json:"external_key"`
+	Channels    []string `json:"channels,omitempty"`
+	Name        string   `json:"name,omitempty"`
+	Content     string   `json:"content,omitempty"`
+	ClientCert  string   `json:"client_cert,omitempty"`
+	ClientKey   string   `json:"client_key,omitempty"`
+	CACert      string   `json:"ca_cert,omitempty"`
+	State       int      `json:"state"`
+}
+
+// BootstrapPage contains list of bootstrap configurations in a page with
+// proper metadata.
+type BootstrapPage struct {
+	Configs []BootstrapConfig `json:"configs"`
+	Total   uint64            `json:"total"`
+	Offset  uint64            `json:"offset"`
+	Limit   uint64            `json:"limit"`
 }
 
 // ChannelsPage contains list of channels in a page with proper metadata.
@@ -116,6 +225,38 @@ type MessagesPage struct {
 	Messages []mainflux.Message `json:"messages,omitempty"`
 }
 
+// Pattern describes the values published by simulated devices.
+type Pattern struct {
+	Type   string  `json:"type"`
+	Name   string  `json:"name"`
+	Unit   string  `json:"unit,omitempty"`
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+	Period string  `json:"period,omitempty"`
+}
+
+// SimulationStats contains simulation publishing statistics.
+type SimulationStats struct {
+	Published uint64 `json:"published"`
+	Failed    uint64 `json:"failed"`
+}
+
+// Simulation represents group of virtual devices run by the simulator.
+type Simulation struct {
+	ID       string          `json:"id,omitempty"`
+	Protocol string          `json:"protocol"`
+	URL      string          `json:"url"`
+	Channel  string          `json:"channel"`
+	Subtopic string          `json:"subtopic,omitempty"`
+	ThingID  string          `json:"thing_id,omitempty"`
+	ThingKey string          `json:"thing_key,omitempty"`
+	Devices  uint64          `json:"devices"`
+	Interval string          `json:"interval"`
+	Pattern  Pattern         `json:"pattern"`
+	Started  time.Time       `json:"started"`
+	Stats    SimulationStats `json:"stats"`
+}
+
 // SDK contains Mainflux API.
 type SDK interface {
 	// CreateUser registers mainflux user.
@@ -124,6 +265,50 @@ type SDK interface {
 	// CreateToken receives credentials and returns user token.
 	CreateToken(user User) (string, error)
 
+	// DeleteUser removes the account of the user identified by the token.
+	DeleteUser(token string) error
+
+	// Users returns page of all the registered users. Only the admins are
+	// allowed to list users.
+	Users(token string, offset, limit uint64) (UsersPage, error)
+
+	// Role returns the role of the user with the provided email.
+	Role(email, token string) (string, error)
+
+	// AssignRole assigns the role to the user with the provided email. Only
+	// the admins are allowed to assign roles.
+	AssignRole(email, role, token string) error
+
+	// DisableUser disables the user with the provided email. Only the admins
+	// are allowed to disable users.
+	DisableUser(email, token string) error
+
+	// Impersonate returns the token of the user with the provided email.
+	// Only the admins are allowed to impersonate users.
+	Impersonate(email, token string) (string, error)
+
+	// UsersIterator returns the iterator over all the registered users.
+	UsersIterator(token string) *UsersIterator
+
+	// CreateGroup creates new group of users and returns its id.
+	CreateGroup(group Group, token string) (string, error)
+
+	// Groups returns the groups the user belongs to.
+	Groups(token string) ([]Group, error)
+
+	// Group returns group object by id.
+	Group(id, token string) (Group, error)
+
+	// DeleteGroup removes existing group.
+	DeleteGroup(id, token string) error
+
+	// AssignMember adds the user with the provided email to the group.
+	AssignMember(id, email, token string) error
+
+	// UnassignMember removes the user with the provided email from the
+	// group.
+	UnassignMember(id, email, token string) error
+
 	// CreateThing registers new thing and returns its id.
 	CreateThing(thing Thing, token string) (string, error)
 
@@ -134,6 +319,9 @@ type SDK interface {
 	// channel.
 	ThingsByChannel(token, chanID string, offset, limit uint64) (ThingsPage, error)
 
+	// ThingsIterator returns the iterator over all the things.
+	ThingsIterator(token, name string) *ThingsIterator
+
 	// Thing returns thing object by id.
 	Thing(id, token string) (Thing, error)
 
@@ -143,6 +331,9 @@ type SDK interface {
 	// DeleteThing removes existing thing.
 	DeleteThing(id, token string) error
 
+	// PurgeThing permanently removes existing or removed thing.
+	PurgeThing(id, token string) error
+
 	// ConnectThing connects thing to specified channel by id.
 	ConnectThing(thingID, chanID, token string) error
 
@@ -159,6 +350,9 @@ type SDK interface {
 	// thing.
 	ChannelsByThing(token, thingID string, offset, limit uint64) (ChannelsPage, error)
 
+	// ChannelsIterator returns the iterator over all the channels.
+	ChannelsIterator(token, name string) *ChannelsIterator
+
 	// Channel returns channel data by id.
 	Channel(id, token string) (Channel, error)
 
@@ -168,52 +362,202 @@ type SDK interface {
 	// DeleteChannel removes existing channel.
 	DeleteChannel(id, token string) error
 
+	// PurgeChannel permanently removes existing or removed channel.
+	PurgeChannel(id, token string) error
+
+	// ExportThings returns all the things of the user, including their IDs.
+	ExportThings(token string) ([]Thing, error)
+
+	// ImportThings adds the things preserving their IDs, and returns the
+	// number of imported things.
+	ImportThings(things []Thing, token string) (int, error)
+
+	// ExportChannels returns all the channels of the user, including their
+	// IDs.
+	ExportChannels(token string) ([]Channel, error)
+
+	// ImportChannels adds the channels preserving their IDs, and returns the
+	// number of imported channels.
+	ImportChannels(channels []Channel, token string) (int, error)
+
+	// ExportConnections returns all the connections of the user's things.
+	ExportConnections(token string) ([]Connection, error)
+
+	// ImportConnections connects the things to the channels, and returns the
+	// number of imported connections.
+	ImportConnections(conns []Connection, token string) (int, error)
+
+	// UpdateQuota overrides the default quota of the owner. Only the admins
+	// are allowed to update quotas.
+	UpdateQuota(owner string, quota Quota, token string) error
+
+	// RemoveQuota restores the default quota of the owner. Only the admins
+	// are allowed to remove quotas.
+	RemoveQuota(owner, token string) error
+
+	// AddBootstrap adds the bootstrap configuration of the existing thing,
+	// and returns the thing ID.
+	AddBootstrap(cfg BootstrapConfig, token string) (string, error)
+
+	// BootstrapConfigs returns page of bootstrap configurations.
+	BootstrapConfigs(token string, offset, limit uint64) (BootstrapPage, error)
+
+	// UpdateBootstrapState changes the state of the thing bootstrap
+	// configuration.
+	UpdateBootstrapState(id string, state int, token string) error
+
+	// BootstrapIterator returns the iterator over all the bootstrap
+	// configurations.
+	BootstrapIterator(token string) *BootstrapIterator
+
+	// BootstrapConfig returns bootstrap configuration by thing id.
+	BootstrapConfig(id, token string) (BootstrapConfig, error)
+
+	// UpdateBootstrap updates the name and the content of the bootstrap
+	// configuration of the thing.
+	UpdateBootstrap(cfg BootstrapConfig, token string) error
+
+	// UpdateBootstrapConnections updates the channels the thing is
+	// connected to once bootstrapped.
+	UpdateBootstrapConnections(id string, channels []string, token string) error
+
+	// RemoveBootstrap removes the bootstrap configuration of the thing.
+	RemoveBootstrap(id, token string) error
+
+	// IssueCert issues the client certificate of the thing.
+	IssueCert(thingID, token string) (Cert, error)
+
+	// Cert returns the certificate by serial number.
+	Cert(serial, token string) (Cert, error)
+
+	// Certs returns page of certificates, optionally issued to the thing.
+	Certs(token, thingID string, offset, limit uint64) (CertsPage, error)
+
+	// CertsIterator returns the iterator over all the certificates,
+	// optionally issued to the thing.
+	CertsIterator(token, thingID string) *CertsIterator
+
+	// RevokeCert revokes the certificate by serial number.
+	RevokeCert(serial, token string) error
+
 	// SendMessage send message to specified channel.
 	SendMessage(chanID, msg, token string) error
 
+	// Subscribe subscribes the thing to the channel subtopic over the
+	// configured streaming protocol, passing the received messages to the
+	// handler. The connection is reestablished until the subscription is
+	// closed.
+	Subscribe(chanID, subtopic string, thing Thing, handler MessageHandler) (Subscription, error)
+
+	// Publish publishes the message to the channel subtopic on behalf of
+	// the thing over the configured streaming protocol.
+	Publish(chanID, subtopic string, msg []byte, thing Thing) error
+
 	// ReadMessages read messages of specified channel.
 	ReadMessages(chanID, token string) (MessagesPage, error)
 
+	// Messages returns page of messages of specified channel.
+	Messages(chanID, token string, offset, limit uint64) (MessagesPage, error)
+
+	// MessagesIterator returns the iterator over all the messages of
+	// specified channel.
+	MessagesIterator(chanID, token string) *MessagesIterator
+
+	// Subtopics returns the subtopics of specified channel.
+	Subtopics(chanID, token string) ([]Subtopic, error)
+
+	// StartSimulation starts new simulation and returns its id.
+	StartSimulation(sim Simulation, token string) (string, error)
+
+	// Simulations returns all the running simulations.
+	Simulations(token string) ([]Simulation, error)
+
+	// Simulation returns running simulation by id.
+	Simulation(id, token string) (Simulation, error)
+
+	// StopSimulation stops running simulation.
+	StopSimulation(id, token string) error
+
 	// SetContentType sets message content type.
 	SetContentType(ct ContentType) error
 
 	// Version returns used mainflux version.
 	Version() (string, error)
+
+	// WithContext returns the copy of the SDK which binds all the requests
+	// to the provided context.
+	WithContext(ctx context.Context) SDK
 }
 
 type mfSDK struct {
+	ctx               context.Context
 	baseURL           string
 	readerURL         string
 	readerPrefix      string
+	simulatorURL      string
+	bootstrapURL      string
+	certsURL          string
+	wsURL             string
+	mqttURL           string
+	streamProtocol    string
+	streamBuffer      int
 	usersPrefix       string
 	thingsPrefix      string
 	httpAdapterPrefix string
 	msgContentType    ContentType
+	retries           uint
+	retryBackoff      time.Duration
 	client            *http.Client
 }
 
-// Config contains sdk configuration parameters.
+// Config contains sdk configuration parameters. Idempotent requests are
+// retried up to Retries times, doubling the RetryBackoff pause after each
+// attempt. Subscriptions use the StreamProtocol, WebSocket by default, and
+// buffer up to StreamBuffer received messages.
 type Config struct {
 	BaseURL           string
 	ReaderURL         string
 	ReaderPrefix      string
+	SimulatorURL      string
+	BootstrapURL      string
+	CertsURL          string
+	WSURL             string
+	MQTTURL           string
+	StreamProtocol    string
+	StreamBuffer      int
 	UsersPrefix       string
 	ThingsPrefix      string
 	HTTPAdapterPrefix string
 	MsgContentType    ContentType
 	TLSVerification   bool
+	Retries           uint
+	RetryBackoff      time.Duration
 }
 
 // NewSDK returns new mainflux SDK instance.
 func NewSDK(conf Config) SDK {
+	ws := conf.WSURL
+	if ws == "" {
+		ws = defWSURL(conf.BaseURL)
+	}
+
 	return &mfSDK{
 		baseURL:           conf.BaseURL,
 		readerURL:         conf.ReaderURL,
 		readerPrefix:      conf.ReaderPrefix,
+		simulatorURL:      conf.SimulatorURL,
+		bootstrapURL:      conf.BootstrapURL,
+		certsURL:          conf.CertsURL,
+		wsURL:             ws,
+		mqttURL:           conf.MQTTURL,
+		streamProtocol:    conf.StreamProtocol,
+		streamBuffer:      conf.StreamBuffer,
 		usersPrefix:       conf.UsersPrefix,
 		thingsPrefix:      conf.ThingsPrefix,
 		httpAdapterPrefix: conf.HTTPAdapterPrefix,
 		msgContentType:    conf.MsgContentType,
+		retries:           conf.Retries,
+		retryBackoff:      conf.RetryBackoff,
 		client: &http.Client{
 			Transport: &http.Transport{
 				TLSClientConfig: &tls.Config{
@@ -224,16 +568,74 @@ func NewSDK(conf Config) SDK {
 	}
 }
 
+func (sdk *mfSDK) WithContext(ctx context.Context) SDK {
+	c := *sdk
+	c.ctx = ctx
+	return &c
+}
+
+// sendRequest sends the request bound to the SDK context. Idempotent
+// requests are retried with exponential backoff if the service can't be
+// reached or is temporarily unavailable.
 func (sdk mfSDK) sendRequest(req *http.Request, token, contentType string) (*http.Response, error) {
 	if token != "" {
 		req.Header.Set("Authorization", token)
 	}
 
 	if contentType != "" {
-		req.Header.Add("Content-Type", contentType)
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	ctx := sdk.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	req = req.WithContext(ctx)
+
+	backoff := sdk.retryBackoff
+	for attempt := uint(0); ; attempt++ {
+		resp, err := sdk.client.Do(req)
+		if attempt >= sdk.retries || ctx.Err() != nil || !retryable(req, resp, err) {
+			return resp, err
+		}
+
+		if resp != nil {
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+
+		if req.GetBody != nil {
+			if req.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
 	}
+}
 
-	return sdk.client.Do(req)
+func retryable(req *http.Request, resp *http.Response, err error) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+	default:
+		return false
+	}
+
+	if err != nil {
+		return true
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
 }
 
 func createURL(baseURL, prefix, endpoint string) string {
@@ -0,0 +1,109 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package sdk_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	sdk "github.com/mainflux/mainflux/sdk/go"
+	"github.com/stretchr/testify/assert"
+)
+
+// newUnavailableServer returns the server which responds with 503 to the
+// first failures requests, and serves the version afterwards.
+func newUnavailableServer(failures int32, calls *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(calls, 1) <= failures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"version":"0.0.0"}`))
+	}))
+}
+
+func TestRetries(t *testing.T) {
+	cases := []struct {
+		desc     string
+		failures int32
+		retries  uint
+		calls    int32
+		err      bool
+	}{
+		{
+			desc:     "request succeeding without retries",
+			failures: 0,
+			retries:  3,
+			calls:    1,
+			err:      false,
+		},
+		{
+			desc:     "request succeeding after retries",
+			failures: 2,
+			retries:  3,
+			calls:    3,
+			err:      false,
+		},
+		{
+			desc:     "request failing after all the retries",
+			failures: 5,
+			retries:  2,
+			calls:    3,
+			err:      true,
+		},
+		{
+			desc:     "request failing with retries disabled",
+			failures: 1,
+			retries:  0,
+			calls:    1,
+			err:      true,
+		},
+	}
+
+	for _, tc := range cases {
+		var calls int32
+		ts := newUnavailableServer(tc.failures, &calls)
+
+		mainfluxSDK := sdk.NewSDK(sdk.Config{
+			BaseURL:      ts.URL,
+			Retries:      tc.retries,
+			RetryBackoff: time.Millisecond,
+		})
+
+		_, err := mainfluxSDK.Version()
+		assert.Equal(t, tc.err, err != nil, fmt.Sprintf("%s: unexpected error: %v", tc.desc, err))
+		assert.Equal(t, tc.calls, atomic.LoadInt32(&calls), fmt.Sprintf("%s: expected %d calls, got %d", tc.desc, tc.calls, calls))
+		ts.Close()
+	}
+}
+
+func TestWithContext(t *testing.T) {
+	var calls int32
+	ts := newUnavailableServer(100, &calls)
+	defer ts.Close()
+
+	mainfluxSDK := sdk.NewSDK(sdk.Config{
+		BaseURL:      ts.URL,
+		Retries:      100,
+		RetryBackoff: 10 * time.Millisecond,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := mainfluxSDK.WithContext(ctx).Version()
+	assert.Equal(t, context.DeadlineExceeded, err, fmt.Sprintf("expected error %s, got %v", context.DeadlineExceeded, err))
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+
+	_, err = mainfluxSDK.WithContext(ctx).Version()
+	assert.NotNil(t, err, "expected error for cancelled context")
+}
@@ -0,0 +1,152 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package sdk
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+const simulationsEndpoint = "simulations"
+
+func (sdk mfSDK) StartSimulation(sim Simulation, token string) (string, error) {
+	data, err := json.Marshal(sim)
+	if err != nil {
+		return "", ErrInvalidArgs
+	}
+
+	url := createURL(sdk.simulatorURL, "", simulationsEndpoint)
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		switch resp.StatusCode {
+		case http.StatusBadRequest:
+			return "", ErrInvalidArgs
+		case http.StatusForbidden:
+			return "", ErrUnauthorized
+		default:
+			return "", ErrFailedCreation
+		}
+	}
+
+	id := strings.TrimPrefix(resp.Header.Get("Location"), fmt.Sprintf("/%s/", simulationsEndpoint))
+	return id, nil
+}
+
+func (sdk mfSDK) Simulations(token string) ([]Simulation, error) {
+	url := createURL(sdk.simulatorURL, "", simulationsEndpoint)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		switch resp.StatusCode {
+		case http.StatusForbidden:
+			return nil, ErrUnauthorized
+		default:
+			return nil, ErrFetchFailed
+		}
+	}
+
+	var sr simulationsRes
+	if err := json.Unmarshal(body, &sr); err != nil {
+		return nil, err
+	}
+
+	return sr.Simulations, nil
+}
+
+func (sdk mfSDK) Simulation(id, token string) (Simulation, error) {
+	endpoint := fmt.Sprintf("%s/%s", simulationsEndpoint, id)
+	url := createURL(sdk.simulatorURL, "", endpoint)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return Simulation{}, err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return Simulation{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return Simulation{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		switch resp.StatusCode {
+		case http.StatusForbidden:
+			return Simulation{}, ErrUnauthorized
+		case http.StatusNotFound:
+			return Simulation{}, ErrNotFound
+		default:
+			return Simulation{}, ErrFetchFailed
+		}
+	}
+
+	var s Simulation
+	if err := json.Unmarshal(body, &s); err != nil {
+		return Simulation{}, err
+	}
+
+	return s, nil
+}
+
+func (sdk mfSDK) StopSimulation(id, token string) error {
+	endpoint := fmt.Sprintf("%s/%s", simulationsEndpoint, id)
+	url := createURL(sdk.simulatorURL, "", endpoint)
+
+	req, err := http.NewRequest(http.MethodDelete, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusNoContent {
+		switch resp.StatusCode {
+		case http.StatusForbidden:
+			return ErrUnauthorized
+		case http.StatusNotFound:
+			return ErrNotFound
+		default:
+			return ErrFailedRemoval
+		}
+	}
+
+	return nil
+}
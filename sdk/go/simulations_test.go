@@ -0,0 +1,179 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package sdk_test
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	sdk "github.com/mainflux/mainflux/sdk/go"
+	"github.com/mainflux/mainflux/simulator"
+	simapi "github.com/mainflux/mainflux/simulator/api"
+	simmocks "github.com/mainflux/mainflux/simulator/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var simulation = sdk.Simulation{
+	Protocol: simulator.HTTP,
+	URL:      "http://localhost:8185",
+	Channel:  "1",
+	ThingKey: "key",
+	Devices:  2,
+	Interval: "1h",
+	Pattern: sdk.Pattern{
+		Type: simulator.RandomWalk,
+		Name: "temperature",
+		Min:  10,
+		Max:  30,
+	},
+}
+
+func newSimulatorServer(tokens map[string]string) *httptest.Server {
+	users := simmocks.NewUsersService(tokens)
+	pubs := map[string]simulator.Publisher{simulator.HTTP: simmocks.NewPublisher()}
+	svc := simulator.New(users, pubs, simmocks.NewIdentityProvider())
+	return httptest.NewServer(simapi.MakeHandler(svc))
+}
+
+func TestStartSimulation(t *testing.T) {
+	ts := newSimulatorServer(map[string]string{token: email})
+	defer ts.Close()
+
+	mainfluxSDK := sdk.NewSDK(sdk.Config{SimulatorURL: ts.URL})
+
+	invalid := simulation
+	invalid.Interval = "often"
+
+	cases := []struct {
+		desc       string
+		simulation sdk.Simulation
+		token      string
+		err        error
+		empty      bool
+	}{
+		{
+			desc:       "start new simulation",
+			simulation: simulation,
+			token:      token,
+			err:        nil,
+			empty:      false,
+		},
+		{
+			desc:       "start simulation with invalid interval",
+			simulation: invalid,
+			token:      token,
+			err:        sdk.ErrInvalidArgs,
+			empty:      true,
+		},
+		{
+			desc:       "start simulation with wrong credentials",
+			simulation: simulation,
+			token:      wrongValue,
+			err:        sdk.ErrUnauthorized,
+			empty:      true,
+		},
+	}
+
+	for _, tc := range cases {
+		id, err := mainfluxSDK.StartSimulation(tc.simulation, tc.token)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
+		assert.Equal(t, tc.empty, id == "", fmt.Sprintf("%s: unexpected id %s", tc.desc, id))
+	}
+}
+
+func TestSimulation(t *testing.T) {
+	ts := newSimulatorServer(map[string]string{token: email})
+	defer ts.Close()
+
+	mainfluxSDK := sdk.NewSDK(sdk.Config{SimulatorURL: ts.URL})
+
+	id, err := mainfluxSDK.StartSimulation(simulation, token)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	expected := simulation
+	expected.ID = id
+	expected.ThingKey = ""
+	expected.Interval = "1h0m0s"
+
+	cases := map[string]struct {
+		id         string
+		token      string
+		err        error
+		simulation sdk.Simulation
+	}{
+		"get existing simulation": {
+			id:         id,
+			token:      token,
+			err:        nil,
+			simulation: expected,
+		},
+		"get non-existent simulation": {
+			id:         wrongValue,
+			token:      token,
+			err:        sdk.ErrNotFound,
+			simulation: sdk.Simulation{},
+		},
+		"get simulation with wrong credentials": {
+			id:         id,
+			token:      wrongValue,
+			err:        sdk.ErrUnauthorized,
+			simulation: sdk.Simulation{},
+		},
+	}
+
+	for desc, tc := range cases {
+		sim, err := mainfluxSDK.Simulation(tc.id, tc.token)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected error %s, got %s", desc, tc.err, err))
+		sim.Started = time.Time{}
+		assert.Equal(t, tc.simulation, sim, fmt.Sprintf("%s: expected response simulation %v, got %v", desc, tc.simulation, sim))
+	}
+
+	sims, err := mainfluxSDK.Simulations(token)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Len(t, sims, 1, fmt.Sprintf("expected one simulation, got %d", len(sims)))
+}
+
+func TestStopSimulation(t *testing.T) {
+	ts := newSimulatorServer(map[string]string{token: email})
+	defer ts.Close()
+
+	mainfluxSDK := sdk.NewSDK(sdk.Config{SimulatorURL: ts.URL})
+
+	id, err := mainfluxSDK.StartSimulation(simulation, token)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		id    string
+		token string
+		err   error
+	}{
+		{
+			desc:  "stop simulation with wrong credentials",
+			id:    id,
+			token: wrongValue,
+			err:   sdk.ErrUnauthorized,
+		},
+		{
+			desc:  "stop running simulation",
+			id:    id,
+			token: token,
+			err:   nil,
+		},
+		{
+			desc:  "stop non-existent simulation",
+			id:    id,
+			token: token,
+			err:   sdk.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := mainfluxSDK.StopSimulation(tc.id, tc.token)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
+	}
+}
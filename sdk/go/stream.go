@@ -0,0 +1,444 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package sdk
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/eclipse/paho.mqtt.golang/packets"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// ProtocolWS streams the messages over WebSocket adapter.
+	ProtocolWS = "ws"
+
+	// ProtocolMQTT streams the messages over MQTT adapter.
+	ProtocolMQTT = "mqtt"
+
+	defStreamBuffer     = 100
+	defReconnectBackoff = time.Second
+	maxReconnectBackoff = 30 * time.Second
+	mqttTimeout         = 5 * time.Second
+	mqttQuiesce         = 250
+)
+
+// StreamMessage represents the message received over the subscription.
+type StreamMessage struct {
+	Channel  string
+	Subtopic string
+	Payload  []byte
+}
+
+// MessageHandler handles the messages received over the subscription. The
+// handler is called sequentially, and the messages are buffered while it
+// runs. Once the buffer is full, reading from the adapter stops until the
+// handler catches up, so the slow handler pushes back on the adapter instead
+// of dropping the messages.
+type MessageHandler func(msg StreamMessage)
+
+// Subscription represents the live connection to the protocol adapter.
+type Subscription interface {
+	// Publish publishes the message to the subscribed channel subtopic over
+	// the subscription connection.
+	Publish(msg []byte) error
+
+	// Close closes the connection, discarding the buffered messages, and
+	// waits for the handler to return.
+	Close() error
+}
+
+func (sdk mfSDK) Subscribe(chanID, subtopic string, thing Thing, handler MessageHandler) (Subscription, error) {
+	if handler == nil {
+		return nil, ErrInvalidArgs
+	}
+
+	return sdk.connect(chanID, subtopic, thing, handler)
+}
+
+func (sdk mfSDK) Publish(chanID, subtopic string, msg []byte, thing Thing) error {
+	sub, err := sdk.connect(chanID, subtopic, thing, nil)
+	if err != nil {
+		return err
+	}
+	defer sub.Close()
+
+	return sub.Publish(msg)
+}
+
+// connect connects the thing to the protocol adapter. Without the handler the
+// connection is used for publishing only, and the received messages are
+// discarded.
+func (sdk mfSDK) connect(chanID, subtopic string, thing Thing, handler MessageHandler) (Subscription, error) {
+	if chanID == "" {
+		return nil, ErrInvalidArgs
+	}
+
+	size := sdk.streamBuffer
+	if size <= 0 {
+		size = defStreamBuffer
+	}
+
+	backoff := sdk.retryBackoff
+	if backoff <= 0 {
+		backoff = defReconnectBackoff
+	}
+
+	sub := &subscription{
+		chanID:   chanID,
+		subtopic: strings.Trim(strings.Replace(subtopic, ".", "/", -1), "/"),
+		handler:  handler,
+		msgs:     make(chan StreamMessage, size),
+		done:     make(chan struct{}),
+	}
+
+	switch sdk.streamProtocol {
+	case "", ProtocolWS:
+		return sdk.subscribeWS(sub, thing, backoff)
+	case ProtocolMQTT:
+		return sdk.subscribeMQTT(sub, thing)
+	default:
+		return nil, ErrUnsupportedProtocol
+	}
+}
+
+// subscription buffers the received messages and dispatches them to the
+// handler, independently of the protocol.
+type subscription struct {
+	chanID   string
+	subtopic string
+	handler  MessageHandler
+	msgs     chan StreamMessage
+	done     chan struct{}
+	once     sync.Once
+	wg       sync.WaitGroup
+}
+
+func (sub *subscription) topic() string {
+	topic := fmt.Sprintf("channels/%s/messages", sub.chanID)
+	if sub.subtopic != "" {
+		topic = fmt.Sprintf("%s/%s", topic, sub.subtopic)
+	}
+
+	return topic
+}
+
+// deliver buffers the message, blocking while the buffer is full. It returns
+// false once the subscription is closed.
+func (sub *subscription) deliver(subtopic string, payload []byte) bool {
+	if sub.handler == nil {
+		return !sub.closed()
+	}
+
+	msg := StreamMessage{
+		Channel:  sub.chanID,
+		Subtopic: subtopic,
+		Payload:  payload,
+	}
+
+	select {
+	case sub.msgs <- msg:
+		return true
+	case <-sub.done:
+		return false
+	}
+}
+
+// start starts dispatching the buffered messages to the handler.
+func (sub *subscription) start() {
+	if sub.handler == nil {
+		return
+	}
+
+	sub.wg.Add(1)
+	go sub.dispatch()
+}
+
+func (sub *subscription) dispatch() {
+	defer sub.wg.Done()
+
+	for {
+		select {
+		case msg := <-sub.msgs:
+			sub.handler(msg)
+		case <-sub.done:
+			return
+		}
+	}
+}
+
+func (sub *subscription) closed() bool {
+	select {
+	case <-sub.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// close marks the subscription as closed, returning false if it already was.
+func (sub *subscription) close() bool {
+	closed := false
+	sub.once.Do(func() {
+		close(sub.done)
+		closed = true
+	})
+
+	return closed
+}
+
+type wsSubscription struct {
+	*subscription
+	url     string
+	header  http.Header
+	format  int
+	backoff time.Duration
+	mu      sync.Mutex
+	conn    *websocket.Conn
+}
+
+func (sdk mfSDK) subscribeWS(sub *subscription, thing Thing, backoff time.Duration) (Subscription, error) {
+	header := http.Header{}
+	header.Set("Authorization", thing.Key)
+	if sdk.msgContentType != "" {
+		header.Set("Content-Type", string(sdk.msgContentType))
+	}
+
+	ws := &wsSubscription{
+		subscription: sub,
+		url:          fmt.Sprintf("%s/%s", strings.TrimSuffix(sdk.wsURL, "/"), sub.topic()),
+		header:       header,
+		format:       websocket.TextMessage,
+		backoff:      backoff,
+	}
+
+	switch sdk.msgContentType {
+	case CTCBORSenML, CTBinary:
+		ws.format = websocket.BinaryMessage
+	}
+
+	// The first connection is established synchronously, so that the
+	// invalid credentials are reported to the caller.
+	conn, err := ws.dial()
+	if err != nil {
+		return nil, err
+	}
+	ws.conn = conn
+
+	ws.start()
+	ws.wg.Add(1)
+	go ws.read(conn)
+
+	return ws, nil
+}
+
+func (ws *wsSubscription) dial() (*websocket.Conn, error) {
+	conn, resp, err := websocket.DefaultDialer.Dial(ws.url, ws.header)
+	if err == nil {
+		return conn, nil
+	}
+
+	if resp != nil {
+		switch resp.StatusCode {
+		case http.StatusBadRequest:
+			return nil, ErrInvalidArgs
+		case http.StatusForbidden:
+			return nil, ErrUnauthorized
+		}
+	}
+
+	return nil, err
+}
+
+// read reads the messages from the connection, reconnecting with exponential
+// backoff whenever the connection breaks.
+func (ws *wsSubscription) read(conn *websocket.Conn) {
+	defer ws.wg.Done()
+
+	for {
+		for {
+			_, payload, err := conn.ReadMessage()
+			if err != nil {
+				break
+			}
+			if !ws.deliver(ws.subtopic, payload) {
+				return
+			}
+		}
+
+		conn = ws.reconnect()
+		if conn == nil {
+			return
+		}
+	}
+}
+
+// reconnect dials the adapter until it succeeds or the subscription is
+// closed, in which case it returns nil.
+func (ws *wsSubscription) reconnect() *websocket.Conn {
+	backoff := ws.backoff
+	for {
+		select {
+		case <-ws.done:
+			return nil
+		case <-time.After(backoff):
+		}
+
+		if conn, err := ws.dial(); err == nil {
+			ws.mu.Lock()
+			defer ws.mu.Unlock()
+
+			// The subscription may have been closed while dialing.
+			if ws.closed() {
+				conn.Close()
+				return nil
+			}
+			ws.conn = conn
+
+			return conn
+		}
+
+		if backoff *= 2; backoff > maxReconnectBackoff {
+			backoff = maxReconnectBackoff
+		}
+	}
+}
+
+func (ws *wsSubscription) Publish(msg []byte) error {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	if ws.closed() {
+		return ErrSubscriptionClosed
+	}
+
+	if err := ws.conn.WriteMessage(ws.format, msg); err != nil {
+		return ErrFailedPublish
+	}
+
+	return nil
+}
+
+func (ws *wsSubscription) Close() error {
+	ws.mu.Lock()
+	if !ws.close() {
+		ws.mu.Unlock()
+		return ErrSubscriptionClosed
+	}
+
+	closeMsg := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+	ws.conn.WriteControl(websocket.CloseMessage, closeMsg, time.Now().Add(time.Second))
+	err := ws.conn.Close()
+	ws.mu.Unlock()
+
+	ws.wg.Wait()
+
+	return err
+}
+
+type mqttSubscription struct {
+	*subscription
+	client mqtt.Client
+}
+
+func (sdk mfSDK) subscribeMQTT(sub *subscription, thing Thing) (Subscription, error) {
+	if sdk.mqttURL == "" {
+		return nil, ErrInvalidArgs
+	}
+
+	ms := &mqttSubscription{subscription: sub}
+	topic := sub.topic()
+
+	opts := mqtt.NewClientOptions().
+		AddBroker(sdk.mqttURL).
+		SetClientID(fmt.Sprintf("mainflux-sdk-%s-%d", thing.ID, time.Now().UnixNano())).
+		SetUsername(thing.ID).
+		SetPassword(thing.Key).
+		SetCleanSession(true).
+		SetAutoReconnect(true).
+		SetMaxReconnectInterval(maxReconnectBackoff).
+		SetOrderMatters(true)
+
+	// Clean session drops the subscriptions, so the topic is subscribed to
+	// on each connection.
+	if sub.handler != nil {
+		opts.SetOnConnectHandler(func(c mqtt.Client) {
+			c.Subscribe(topic, 0, ms.receive)
+		})
+	}
+
+	ms.client = mqtt.NewClient(opts)
+	token := ms.client.Connect()
+	if !token.WaitTimeout(mqttTimeout) {
+		return nil, ErrFailedConnection
+	}
+	switch err := token.Error(); err {
+	case nil:
+	case packets.ConnErrors[packets.ErrRefusedBadUsernameOrPassword], packets.ConnErrors[packets.ErrRefusedNotAuthorised]:
+		return nil, ErrUnauthorized
+	default:
+		return nil, err
+	}
+
+	ms.start()
+
+	return ms, nil
+}
+
+// receive buffers the message. With ordered delivery paho doesn't read from
+// the connection while the handler blocks, which pushes back on the adapter.
+func (ms *mqttSubscription) receive(_ mqtt.Client, m mqtt.Message) {
+	prefix := fmt.Sprintf("channels/%s/messages", ms.chanID)
+	subtopic := strings.Trim(strings.TrimPrefix(m.Topic(), prefix), "/")
+	ms.deliver(subtopic, m.Payload())
+}
+
+func (ms *mqttSubscription) Publish(msg []byte) error {
+	if ms.closed() {
+		return ErrSubscriptionClosed
+	}
+
+	token := ms.client.Publish(ms.topic(), 0, false, msg)
+	if !token.WaitTimeout(mqttTimeout) || token.Error() != nil {
+		return ErrFailedPublish
+	}
+
+	return nil
+}
+
+func (ms *mqttSubscription) Close() error {
+	if !ms.close() {
+		return ErrSubscriptionClosed
+	}
+
+	ms.client.Disconnect(mqttQuiesce)
+	ms.wg.Wait()
+
+	return nil
+}
+
+// defWSURL derives WebSocket adapter URL from the base URL, since the
+// adapter is exposed at /ws path of the same host.
+func defWSURL(baseURL string) string {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return ""
+	}
+
+	switch u.Scheme {
+	case "https":
+		u.Scheme = "wss"
+	default:
+		u.Scheme = "ws"
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/ws"
+
+	return u.String()
+}
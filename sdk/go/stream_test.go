@@ -0,0 +1,201 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package sdk_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	sdk "github.com/mainflux/mainflux/sdk/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	thingKey      = "thing_key"
+	streamChanID  = "1"
+	streamTimeout = 2 * time.Second
+)
+
+var upgrader = websocket.Upgrader{}
+
+// newEchoServer returns WebSocket server which echoes the messages back to
+// the thing, closing the first drops connections right after the handshake.
+func newEchoServer(drops int32) (*httptest.Server, *int32) {
+	var conns int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != thingKey {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		if atomic.AddInt32(&conns, 1) <= drops {
+			return
+		}
+
+		for {
+			mt, payload, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if err := conn.WriteMessage(mt, payload); err != nil {
+				return
+			}
+		}
+	}))
+
+	return ts, &conns
+}
+
+func newStreamSDK(url string) sdk.SDK {
+	return sdk.NewSDK(sdk.Config{
+		WSURL:        strings.Replace(url, "http", "ws", 1),
+		RetryBackoff: 10 * time.Millisecond,
+	})
+}
+
+func receive(msgs chan sdk.StreamMessage) (sdk.StreamMessage, error) {
+	select {
+	case msg := <-msgs:
+		return msg, nil
+	case <-time.After(streamTimeout):
+		return sdk.StreamMessage{}, fmt.Errorf("timeout while receiving message")
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	ts, _ := newEchoServer(0)
+	defer ts.Close()
+	mainfluxSDK := newStreamSDK(ts.URL)
+
+	cases := []struct {
+		desc     string
+		chanID   string
+		subtopic string
+		thing    sdk.Thing
+		err      error
+	}{
+		{
+			desc:     "subscribe to channel",
+			chanID:   streamChanID,
+			subtopic: "",
+			thing:    sdk.Thing{Key: thingKey},
+			err:      nil,
+		},
+		{
+			desc:     "subscribe to channel subtopic",
+			chanID:   streamChanID,
+			subtopic: "temperature.room",
+			thing:    sdk.Thing{Key: thingKey},
+			err:      nil,
+		},
+		{
+			desc:     "subscribe with invalid key",
+			chanID:   streamChanID,
+			subtopic: "",
+			thing:    sdk.Thing{Key: wrongValue},
+			err:      sdk.ErrUnauthorized,
+		},
+		{
+			desc:     "subscribe to empty channel",
+			chanID:   "",
+			subtopic: "",
+			thing:    sdk.Thing{Key: thingKey},
+			err:      sdk.ErrInvalidArgs,
+		},
+	}
+
+	for _, tc := range cases {
+		msgs := make(chan sdk.StreamMessage, 1)
+		sub, err := mainfluxSDK.Subscribe(tc.chanID, tc.subtopic, tc.thing, func(msg sdk.StreamMessage) {
+			msgs <- msg
+		})
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
+		if err != nil {
+			continue
+		}
+
+		err = sub.Publish([]byte("message"))
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+
+		msg, err := receive(msgs)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		assert.Equal(t, "message", string(msg.Payload), fmt.Sprintf("%s: expected payload message, got %s", tc.desc, msg.Payload))
+		assert.Equal(t, strings.Replace(tc.subtopic, ".", "/", -1), msg.Subtopic, fmt.Sprintf("%s: unexpected subtopic %s", tc.desc, msg.Subtopic))
+
+		err = sub.Close()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		err = sub.Publish([]byte("message"))
+		assert.Equal(t, sdk.ErrSubscriptionClosed, err, fmt.Sprintf("%s: expected error %s, got %s", tc.desc, sdk.ErrSubscriptionClosed, err))
+	}
+}
+
+func TestSubscribeReconnect(t *testing.T) {
+	ts, conns := newEchoServer(2)
+	defer ts.Close()
+	mainfluxSDK := newStreamSDK(ts.URL)
+
+	msgs := make(chan sdk.StreamMessage, 1)
+	sub, err := mainfluxSDK.Subscribe(streamChanID, "", sdk.Thing{Key: thingKey}, func(msg sdk.StreamMessage) {
+		msgs <- msg
+	})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	defer sub.Close()
+
+	// Messages published while the dropped connection is being replaced
+	// are lost, so the message is published until it's echoed back.
+	var msg sdk.StreamMessage
+	received := false
+	for deadline := time.Now().Add(streamTimeout); !received && time.Now().Before(deadline); {
+		sub.Publish([]byte("message"))
+		select {
+		case msg = <-msgs:
+			received = true
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+	require.True(t, received, "expected message to be received after reconnect")
+	assert.Equal(t, "message", string(msg.Payload), fmt.Sprintf("expected payload message, got %s", msg.Payload))
+	assert.Equal(t, int32(3), atomic.LoadInt32(conns), fmt.Sprintf("expected 3 connections, got %d", atomic.LoadInt32(conns)))
+}
+
+func TestPublish(t *testing.T) {
+	ts, _ := newEchoServer(0)
+	defer ts.Close()
+	mainfluxSDK := newStreamSDK(ts.URL)
+
+	cases := []struct {
+		desc  string
+		thing sdk.Thing
+		err   error
+	}{
+		{
+			desc:  "publish message",
+			thing: sdk.Thing{Key: thingKey},
+			err:   nil,
+		},
+		{
+			desc:  "publish message with invalid key",
+			thing: sdk.Thing{Key: wrongValue},
+			err:   sdk.ErrUnauthorized,
+		},
+	}
+
+	for _, tc := range cases {
+		err := mainfluxSDK.Publish(streamChanID, "", []byte("message"), tc.thing)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
+	}
+}
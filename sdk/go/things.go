@@ -238,6 +238,34 @@ func (sdk mfSDK) DeleteThing(id, token string) error {
 	return nil
 }
 
+func (sdk mfSDK) PurgeThing(id, token string) error {
+	endpoint := fmt.Sprintf("%s/%s/purge", thingsEndpoint, id)
+	url := createURL(sdk.baseURL, sdk.thingsPrefix, endpoint)
+
+	req, err := http.NewRequest(http.MethodDelete, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusNoContent {
+		switch resp.StatusCode {
+		case http.StatusForbidden:
+			return ErrUnauthorized
+		case http.StatusBadRequest:
+			return ErrInvalidArgs
+		default:
+			return ErrFailedRemoval
+		}
+	}
+
+	return nil
+}
+
 func (sdk mfSDK) ConnectThing(thingID, chanID, token string) error {
 	endpoint := fmt.Sprintf("%s/%s/%s/%s", channelsEndpoint, chanID, thingsEndpoint, thingID)
 	url := createURL(sdk.baseURL, sdk.thingsPrefix, endpoint)
@@ -42,15 +42,17 @@ func newThingsService(tokens map[string]string) things.Service {
 	conns := make(chan mocks.Connection)
 	thingsRepo := mocks.NewThingRepository(conns)
 	channelsRepo := mocks.NewChannelRepository(thingsRepo, conns)
+	quotasRepo := mocks.NewQuotaRepository(thingsRepo, channelsRepo)
+	schemasRepo := mocks.NewSchemaRepository()
 	chanCache := mocks.NewChannelCache()
 	thingCache := mocks.NewThingCache()
 	idp := mocks.NewIdentityProvider()
 
-	return things.New(users, thingsRepo, channelsRepo, chanCache, thingCache, idp)
+	return things.New(users, thingsRepo, channelsRepo, quotasRepo, schemasRepo, chanCache, thingCache, idp, nil, things.Quota{}, nil, 0, 0, nil, nil, nil)
 }
 
 func newThingsServer(svc things.Service) *httptest.Server {
-	mux := httpapi.MakeHandler(mocktracer.New(), svc)
+	mux := httpapi.MakeHandler(mocktracer.New(), svc, 0)
 	return httptest.NewServer(mux)
 }
 
@@ -527,6 +529,64 @@ func TestDeleteThing(t *testing.T) {
 	}
 }
 
+func TestPurgeThing(t *testing.T) {
+	svc := newThingsService(map[string]string{token: email})
+	ts := newThingsServer(svc)
+	defer ts.Close()
+	sdkConf := sdk.Config{
+		BaseURL:           ts.URL,
+		UsersPrefix:       "",
+		ThingsPrefix:      "",
+		HTTPAdapterPrefix: "",
+		MsgContentType:    contentType,
+		TLSVerification:   false,
+	}
+
+	mainfluxSDK := sdk.NewSDK(sdkConf)
+	id, err := mainfluxSDK.CreateThing(thing, token)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		id    string
+		token string
+		err   error
+	}{
+		{
+			desc:  "purge thing with invalid token",
+			id:    id,
+			token: wrongValue,
+			err:   sdk.ErrUnauthorized,
+		},
+		{
+			desc:  "purge thing with empty token",
+			id:    id,
+			token: "",
+			err:   sdk.ErrUnauthorized,
+		},
+		{
+			desc:  "purge existing thing",
+			id:    id,
+			token: token,
+			err:   nil,
+		},
+		{
+			desc:  "purge purged thing",
+			id:    id,
+			token: token,
+			err:   nil,
+		},
+	}
+
+	for _, tc := range cases {
+		err := mainfluxSDK.PurgeThing(tc.id, tc.token)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
+	}
+
+	_, err = mainfluxSDK.Thing(id, token)
+	assert.Equal(t, sdk.ErrNotFound, err, fmt.Sprintf("view purged thing: expected error %s, got %s", sdk.ErrNotFound, err))
+}
+
 func TestConnectThing(t *testing.T) {
 	svc := newThingsService(map[string]string{
 		token:      email,
@@ -6,10 +6,13 @@ package sdk
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"net/http"
 )
 
+const adminUsersEndpoint = "admin/users"
+
 func (sdk mfSDK) CreateUser(user User) error {
 	data, err := json.Marshal(user)
 	if err != nil {
@@ -18,10 +21,16 @@ func (sdk mfSDK) CreateUser(user User) error {
 
 	url := createURL(sdk.baseURL, sdk.usersPrefix, "users")
 
-	resp, err := sdk.client.Post(url, string(CTJSON), bytes.NewReader(data))
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	resp, err := sdk.sendRequest(req, "", string(CTJSON))
 	if err != nil {
 		return err
 	}
+	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusCreated {
 		switch resp.StatusCode {
@@ -45,7 +54,12 @@ func (sdk mfSDK) CreateToken(user User) (string, error) {
 
 	url := createURL(sdk.baseURL, sdk.usersPrefix, "tokens")
 
-	resp, err := sdk.client.Post(url, string(CTJSON), bytes.NewReader(data))
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := sdk.sendRequest(req, "", string(CTJSON))
 	if err != nil {
 		return "", err
 	}
@@ -74,3 +88,212 @@ func (sdk mfSDK) CreateToken(user User) (string, error) {
 
 	return t.Token, nil
 }
+
+func (sdk mfSDK) DeleteUser(token string) error {
+	url := createURL(sdk.baseURL, sdk.usersPrefix, "users")
+
+	req, err := http.NewRequest(http.MethodDelete, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusNoContent {
+		switch resp.StatusCode {
+		case http.StatusForbidden:
+			return ErrUnauthorized
+		case http.StatusNotFound:
+			return ErrNotFound
+		default:
+			return ErrFailedRemoval
+		}
+	}
+
+	return nil
+}
+
+func (sdk mfSDK) Users(token string, offset, limit uint64) (UsersPage, error) {
+	endpoint := fmt.Sprintf("%s?offset=%d&limit=%d", adminUsersEndpoint, offset, limit)
+	url := createURL(sdk.baseURL, sdk.usersPrefix, endpoint)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return UsersPage{}, err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return UsersPage{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return UsersPage{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		switch resp.StatusCode {
+		case http.StatusBadRequest:
+			return UsersPage{}, ErrInvalidArgs
+		case http.StatusForbidden:
+			return UsersPage{}, ErrUnauthorized
+		default:
+			return UsersPage{}, ErrFetchFailed
+		}
+	}
+
+	var p UsersPage
+	if err := json.Unmarshal(body, &p); err != nil {
+		return UsersPage{}, err
+	}
+
+	return p, nil
+}
+
+func (sdk mfSDK) Role(email, token string) (string, error) {
+	endpoint := fmt.Sprintf("users/%s/role", email)
+	url := createURL(sdk.baseURL, sdk.usersPrefix, endpoint)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		switch resp.StatusCode {
+		case http.StatusForbidden:
+			return "", ErrUnauthorized
+		case http.StatusNotFound:
+			return "", ErrNotFound
+		default:
+			return "", ErrFetchFailed
+		}
+	}
+
+	var r roleRes
+	if err := json.Unmarshal(body, &r); err != nil {
+		return "", err
+	}
+
+	return r.Role, nil
+}
+
+func (sdk mfSDK) AssignRole(email, role, token string) error {
+	data, err := json.Marshal(roleRes{Role: role})
+	if err != nil {
+		return ErrInvalidArgs
+	}
+
+	endpoint := fmt.Sprintf("users/%s/role", email)
+	url := createURL(sdk.baseURL, sdk.usersPrefix, endpoint)
+
+	req, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		switch resp.StatusCode {
+		case http.StatusBadRequest:
+			return ErrInvalidArgs
+		case http.StatusForbidden:
+			return ErrUnauthorized
+		case http.StatusNotFound:
+			return ErrNotFound
+		default:
+			return ErrFailedUpdate
+		}
+	}
+
+	return nil
+}
+
+func (sdk mfSDK) DisableUser(email, token string) error {
+	endpoint := fmt.Sprintf("%s/%s/disable", adminUsersEndpoint, email)
+	url := createURL(sdk.baseURL, sdk.usersPrefix, endpoint)
+
+	req, err := http.NewRequest(http.MethodPut, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		switch resp.StatusCode {
+		case http.StatusBadRequest:
+			return ErrInvalidArgs
+		case http.StatusForbidden:
+			return ErrUnauthorized
+		case http.StatusNotFound:
+			return ErrNotFound
+		default:
+			return ErrFailedUpdate
+		}
+	}
+
+	return nil
+}
+
+func (sdk mfSDK) Impersonate(email, token string) (string, error) {
+	endpoint := fmt.Sprintf("%s/%s/tokens", adminUsersEndpoint, email)
+	url := createURL(sdk.baseURL, sdk.usersPrefix, endpoint)
+
+	req, err := http.NewRequest(http.MethodPost, url, nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	if resp.StatusCode != http.StatusCreated {
+		switch resp.StatusCode {
+		case http.StatusForbidden:
+			return "", ErrUnauthorized
+		case http.StatusNotFound:
+			return "", ErrNotFound
+		default:
+			return "", ErrFailedCreation
+		}
+	}
+
+	var t tokenRes
+	if err := json.Unmarshal(body, &t); err != nil {
+		return "", err
+	}
+
+	return t.Token, nil
+}
@@ -13,6 +13,7 @@ import (
 	sdk "github.com/mainflux/mainflux/sdk/go"
 	"github.com/opentracing/opentracing-go/mocktracer"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	httpapi "github.com/mainflux/mainflux/users/api/http"
 
@@ -26,10 +27,17 @@ const (
 
 func newUserService() users.Service {
 	repo := mocks.NewUserRepository()
+	groups := mocks.NewGroupRepository()
+	keys := mocks.NewKeyRepository()
+	roles := mocks.NewRoleRepository()
+	orgs := mocks.NewOrgRepository()
+	totps := mocks.NewTOTPRepository()
 	hasher := mocks.NewHasher()
 	idp := mocks.NewIdentityProvider()
+	uuidp := mocks.NewIDProvider()
+	otpp := mocks.NewOTPProvider()
 
-	return users.New(repo, hasher, idp)
+	return users.New(repo, groups, keys, roles, orgs, totps, []string{adminEmail}, hasher, idp, uuidp, otpp, users.PasswordPolicy{}, nil, users.LockoutPolicy{}, nil)
 }
 
 func newUserServer(svc users.Service) *httptest.Server {
@@ -148,3 +156,216 @@ func TestCreateToken(t *testing.T) {
 		assert.Equal(t, tc.token, token, fmt.Sprintf("%s: expected response: %s, got:  %s", tc.desc, token, tc.token))
 	}
 }
+
+func TestDeleteUser(t *testing.T) {
+	svc := newUserService()
+	ts := newUserServer(svc)
+	defer ts.Close()
+	sdkConf := sdk.Config{
+		BaseURL:           ts.URL,
+		UsersPrefix:       "",
+		ThingsPrefix:      "",
+		HTTPAdapterPrefix: "",
+		MsgContentType:    contentType,
+		TLSVerification:   false,
+	}
+
+	mainfluxSDK := sdk.NewSDK(sdkConf)
+	user := sdk.User{Email: "user@example.com", Password: "password"}
+	mainfluxSDK.CreateUser(user)
+	token, err := mainfluxSDK.CreateToken(user)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		token string
+		err   error
+	}{
+		{
+			desc:  "delete user with empty token",
+			token: "",
+			err:   sdk.ErrUnauthorized,
+		},
+		{
+			desc:  "delete existing user",
+			token: token,
+			err:   nil,
+		},
+		{
+			desc:  "delete deleted user",
+			token: token,
+			err:   sdk.ErrUnauthorized,
+		},
+	}
+	for _, tc := range cases {
+		err := mainfluxSDK.DeleteUser(tc.token)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
+	}
+}
+
+func newAdminSDK(t *testing.T, url string) (sdk.SDK, string, string) {
+	sdkConf := sdk.Config{
+		BaseURL:           url,
+		UsersPrefix:       "",
+		ThingsPrefix:      "",
+		HTTPAdapterPrefix: "",
+		MsgContentType:    contentType,
+		TLSVerification:   false,
+	}
+
+	mainfluxSDK := sdk.NewSDK(sdkConf)
+	admin := sdk.User{Email: adminEmail, Password: "password"}
+	user := sdk.User{Email: "user@example.com", Password: "password"}
+	for _, u := range []sdk.User{admin, user} {
+		err := mainfluxSDK.CreateUser(u)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	adminToken, err := mainfluxSDK.CreateToken(admin)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	userToken, err := mainfluxSDK.CreateToken(user)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	return mainfluxSDK, adminToken, userToken
+}
+
+func TestUsers(t *testing.T) {
+	svc := newUserService()
+	ts := newUserServer(svc)
+	defer ts.Close()
+	mainfluxSDK, adminToken, userToken := newAdminSDK(t, ts.URL)
+
+	cases := []struct {
+		desc  string
+		token string
+		size  int
+		err   error
+	}{
+		{
+			desc:  "list users as admin",
+			token: adminToken,
+			size:  2,
+			err:   nil,
+		},
+		{
+			desc:  "list users as non-admin",
+			token: userToken,
+			size:  0,
+			err:   sdk.ErrUnauthorized,
+		},
+		{
+			desc:  "list users with invalid token",
+			token: wrongValue,
+			size:  0,
+			err:   sdk.ErrUnauthorized,
+		},
+	}
+
+	for _, tc := range cases {
+		page, err := mainfluxSDK.Users(tc.token, 0, 10)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
+		assert.Len(t, page.Users, tc.size, fmt.Sprintf("%s: expected %d users, got %d", tc.desc, tc.size, len(page.Users)))
+	}
+}
+
+func TestAssignRole(t *testing.T) {
+	svc := newUserService()
+	ts := newUserServer(svc)
+	defer ts.Close()
+	mainfluxSDK, adminToken, userToken := newAdminSDK(t, ts.URL)
+
+	cases := []struct {
+		desc  string
+		email string
+		role  string
+		token string
+		err   error
+	}{
+		{
+			desc:  "assign role as admin",
+			email: "user@example.com",
+			role:  users.RoleViewer,
+			token: adminToken,
+			err:   nil,
+		},
+		{
+			desc:  "assign invalid role",
+			email: "user@example.com",
+			role:  wrongValue,
+			token: adminToken,
+			err:   sdk.ErrInvalidArgs,
+		},
+		{
+			desc:  "assign role to non-existing user",
+			email: "user2@example.com",
+			role:  users.RoleViewer,
+			token: adminToken,
+			err:   sdk.ErrNotFound,
+		},
+		{
+			desc:  "assign role as non-admin",
+			email: "user@example.com",
+			role:  users.RoleAdmin,
+			token: userToken,
+			err:   sdk.ErrUnauthorized,
+		},
+	}
+
+	for _, tc := range cases {
+		err := mainfluxSDK.AssignRole(tc.email, tc.role, tc.token)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
+	}
+
+	role, err := mainfluxSDK.Role("user@example.com", adminToken)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, users.RoleViewer, role, fmt.Sprintf("expected role %s, got %s", users.RoleViewer, role))
+}
+
+func TestImpersonate(t *testing.T) {
+	svc := newUserService()
+	ts := newUserServer(svc)
+	defer ts.Close()
+	mainfluxSDK, adminToken, userToken := newAdminSDK(t, ts.URL)
+
+	cases := []struct {
+		desc  string
+		email string
+		token string
+		res   string
+		err   error
+	}{
+		{
+			desc:  "impersonate user as admin",
+			email: "user@example.com",
+			token: adminToken,
+			res:   userToken,
+			err:   nil,
+		},
+		{
+			desc:  "impersonate non-existing user",
+			email: "user2@example.com",
+			token: adminToken,
+			res:   "",
+			err:   sdk.ErrNotFound,
+		},
+		{
+			desc:  "impersonate user as non-admin",
+			email: adminEmail,
+			token: userToken,
+			res:   "",
+			err:   sdk.ErrUnauthorized,
+		},
+	}
+
+	for _, tc := range cases {
+		res, err := mainfluxSDK.Impersonate(tc.email, tc.token)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
+		assert.Equal(t, tc.res, res, fmt.Sprintf("%s: expected token %s, got %s", tc.desc, tc.res, res))
+	}
+
+	err := mainfluxSDK.DisableUser("user@example.com", adminToken)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	_, err = mainfluxSDK.Impersonate("user@example.com", adminToken)
+	assert.Equal(t, sdk.ErrUnauthorized, err, fmt.Sprintf("impersonate disabled user: expected error %s, got %s", sdk.ErrUnauthorized, err))
+}
@@ -17,7 +17,12 @@ type version struct {
 func (sdk mfSDK) Version() (string, error) {
 	url := fmt.Sprintf("%s/version", sdk.baseURL)
 
-	resp, err := sdk.client.Get(url)
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := sdk.sendRequest(req, "", "")
 	if err != nil {
 		return "", err
 	}
@@ -0,0 +1,69 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mainflux/mainflux/sessions"
+)
+
+var _ sessions.Counter = (*counterMock)(nil)
+
+type counterMock struct {
+	mu       sync.Mutex
+	limits   sessions.Limits
+	sessions map[string]sessions.Session
+}
+
+// NewCounter returns in-memory sessions counter that never expires sessions.
+func NewCounter(limits sessions.Limits) sessions.Counter {
+	return &counterMock{
+		limits:   limits,
+		sessions: make(map[string]sessions.Session),
+	}
+}
+
+func (cm *counterMock) Acquire(_ context.Context, s sessions.Session) error {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	if _, ok := cm.sessions[s.ID]; ok {
+		return nil
+	}
+
+	thingSessions, ownerSessions := 0, 0
+	for _, session := range cm.sessions {
+		if session.Thing == s.Thing {
+			thingSessions++
+		}
+		if session.Owner == s.Owner {
+			ownerSessions++
+		}
+	}
+
+	if exceeded(thingSessions, cm.limits.Thing) || exceeded(ownerSessions, cm.limits.Owner) {
+		return sessions.ErrLimitExceeded
+	}
+
+	cm.sessions[s.ID] = s
+	return nil
+}
+
+func (cm *counterMock) Refresh(context.Context, sessions.Session) error {
+	return nil
+}
+
+func (cm *counterMock) Release(_ context.Context, s sessions.Session) error {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	delete(cm.sessions, s.ID)
+	return nil
+}
+
+func exceeded(count, limit int) bool {
+	return limit > 0 && count >= limit
+}
@@ -0,0 +1,109 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/mainflux/mainflux/sessions"
+)
+
+const prefix = "sessions"
+
+// Sessions are kept in sorted sets scored by their expiration time, so that
+// the expired ones can be pruned before counting. Both sets are checked and
+// updated atomically, meaning that concurrent adapters can't exceed the
+// limits.
+var acquireScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local expiry = tonumber(ARGV[2])
+for i, key in ipairs(KEYS) do
+	redis.call('ZREMRANGEBYSCORE', key, '-inf', now)
+	local limit = tonumber(ARGV[3 + i])
+	if limit > 0 and not redis.call('ZSCORE', key, ARGV[3]) and redis.call('ZCARD', key) >= limit then
+		return 0
+	end
+end
+for _, key in ipairs(KEYS) do
+	redis.call('ZADD', key, expiry, ARGV[3])
+	redis.call('PEXPIREAT', key, expiry)
+end
+return 1
+`)
+
+var refreshScript = redis.NewScript(`
+local expiry = tonumber(ARGV[1])
+for _, key in ipairs(KEYS) do
+	if redis.call('ZADD', key, 'XX', 'CH', expiry, ARGV[2]) > 0 then
+		redis.call('PEXPIREAT', key, expiry)
+	end
+end
+return 1
+`)
+
+var _ sessions.Counter = (*counter)(nil)
+
+type counter struct {
+	client redis.UniversalClient
+	limits sessions.Limits
+}
+
+// NewCounter returns redis sessions counter implementation.
+func NewCounter(client redis.UniversalClient, limits sessions.Limits) sessions.Counter {
+	return &counter{
+		client: client,
+		limits: limits,
+	}
+}
+
+func (c *counter) Acquire(_ context.Context, s sessions.Session) error {
+	now := time.Now()
+	expiry := now.Add(sessions.TTL)
+
+	keys := []string{thingKey(s.Owner, s.Thing), ownerKey(s.Owner)}
+	ok, err := acquireScript.Run(c.client, keys, millis(now), millis(expiry), s.ID, c.limits.Thing, c.limits.Owner).Int()
+	if err != nil {
+		return err
+	}
+
+	if ok == 0 {
+		return sessions.ErrLimitExceeded
+	}
+
+	return nil
+}
+
+func (c *counter) Refresh(_ context.Context, s sessions.Session) error {
+	expiry := time.Now().Add(sessions.TTL)
+
+	keys := []string{thingKey(s.Owner, s.Thing), ownerKey(s.Owner)}
+	return refreshScript.Run(c.client, keys, millis(expiry), s.ID).Err()
+}
+
+func (c *counter) Release(_ context.Context, s sessions.Session) error {
+	pipe := c.client.TxPipeline()
+	pipe.ZRem(thingKey(s.Owner, s.Thing), s.ID)
+	pipe.ZRem(ownerKey(s.Owner), s.ID)
+
+	_, err := pipe.Exec()
+	return err
+}
+
+// Owner is the hash tag of both the thing and the owner key, so that the keys
+// the sessions are counted in are kept on the same cluster node, and can be
+// updated by the single script or transaction.
+func thingKey(owner, id string) string {
+	return fmt.Sprintf("%s:{%s}:thing:%s", prefix, owner, id)
+}
+
+func ownerKey(owner string) string {
+	return fmt.Sprintf("%s:{%s}:owner", prefix, owner)
+}
+
+func millis(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
@@ -0,0 +1,106 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package redis_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux/sessions"
+	r "github.com/mainflux/mainflux/sessions/redis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const owner = "john.doe@email.com"
+
+func TestAcquire(t *testing.T) {
+	redisClient.FlushAll()
+	counter := r.NewCounter(redisClient, sessions.Limits{Thing: 2, Owner: 3})
+
+	cases := []struct {
+		desc    string
+		session sessions.Session
+		err     error
+	}{
+		{
+			desc:    "acquire first session of the thing",
+			session: sessions.Session{ID: "1", Thing: "a", Owner: owner},
+			err:     nil,
+		},
+		{
+			desc:    "acquire already acquired session",
+			session: sessions.Session{ID: "1", Thing: "a", Owner: owner},
+			err:     nil,
+		},
+		{
+			desc:    "acquire second session of the thing",
+			session: sessions.Session{ID: "2", Thing: "a", Owner: owner},
+			err:     nil,
+		},
+		{
+			desc:    "acquire session exceeding thing limit",
+			session: sessions.Session{ID: "3", Thing: "a", Owner: owner},
+			err:     sessions.ErrLimitExceeded,
+		},
+		{
+			desc:    "acquire session of another thing",
+			session: sessions.Session{ID: "4", Thing: "b", Owner: owner},
+			err:     nil,
+		},
+		{
+			desc:    "acquire session exceeding owner limit",
+			session: sessions.Session{ID: "5", Thing: "c", Owner: owner},
+			err:     sessions.ErrLimitExceeded,
+		},
+		{
+			desc:    "acquire session of another owner",
+			session: sessions.Session{ID: "6", Thing: "d", Owner: "jane.doe@email.com"},
+			err:     nil,
+		},
+	}
+
+	for _, tc := range cases {
+		err := counter.Acquire(context.Background(), tc.session)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestAcquireUnlimited(t *testing.T) {
+	redisClient.FlushAll()
+	counter := r.NewCounter(redisClient, sessions.Limits{})
+
+	for i := 0; i < 10; i++ {
+		err := counter.Acquire(context.Background(), sessions.Session{ID: fmt.Sprint(i), Thing: "a", Owner: owner})
+		assert.Nil(t, err, fmt.Sprintf("#%d: got unexpected error: %s\n", i, err))
+	}
+}
+
+func TestRelease(t *testing.T) {
+	redisClient.FlushAll()
+	counter := r.NewCounter(redisClient, sessions.Limits{Thing: 1, Owner: 1})
+
+	first := sessions.Session{ID: "1", Thing: "a", Owner: owner}
+	second := sessions.Session{ID: "2", Thing: "a", Owner: owner}
+
+	err := counter.Acquire(context.Background(), first)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s\n", err))
+
+	err = counter.Refresh(context.Background(), first)
+	assert.Nil(t, err, fmt.Sprintf("refresh session: got unexpected error: %s\n", err))
+
+	err = counter.Acquire(context.Background(), second)
+	assert.Equal(t, sessions.ErrLimitExceeded, err, fmt.Sprintf("acquire before release: expected %s got %s\n", sessions.ErrLimitExceeded, err))
+
+	// show that the release works the same for both acquired and released
+	// session
+	for i := 0; i < 2; i++ {
+		err = counter.Release(context.Background(), first)
+		assert.Nil(t, err, fmt.Sprintf("#%d: release session: got unexpected error: %s\n", i, err))
+	}
+
+	err = counter.Acquire(context.Background(), second)
+	assert.Nil(t, err, fmt.Sprintf("acquire after release: got unexpected error: %s\n", err))
+}
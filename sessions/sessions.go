@@ -0,0 +1,50 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package sessions contains the domain concept definitions needed to cap the
+// number of concurrent connections that protocol adapters accept per thing
+// and per thing owner.
+package sessions
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// TTL is the time after which the session that hasn't been refreshed
+// expires. It makes sure that sessions of the adapter instances that crashed
+// don't hold the slots forever. Adapters refresh open sessions every TTL / 3.
+const TTL = 30 * time.Second
+
+// ErrLimitExceeded indicates that the thing or its owner already has the
+// maximum number of concurrent sessions.
+var ErrLimitExceeded = errors.New("maximum number of concurrent sessions exceeded")
+
+// Limits represents the maximum number of concurrent sessions. Zero value
+// means that the number of sessions isn't limited.
+type Limits struct {
+	Thing int
+	Owner int
+}
+
+// Session represents a single connection opened by the thing.
+type Session struct {
+	ID    string
+	Thing string
+	Owner string
+}
+
+// Counter specifies an API for tracking the number of concurrent sessions
+// shared between all adapter instances.
+type Counter interface {
+	// Acquire registers the session, returning ErrLimitExceeded if either the
+	// thing or its owner have reached the limit.
+	Acquire(context.Context, Session) error
+
+	// Refresh extends the lifetime of the registered session for another TTL.
+	Refresh(context.Context, Session) error
+
+	// Release unregisters the session, freeing its slot.
+	Release(context.Context, Session) error
+}
@@ -0,0 +1,32 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/mainflux/mainflux/shadow"
+)
+
+func lastMessagesEndpoint(svc shadow.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(lastMessagesReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		filter := shadow.Filter{
+			Subtopic:  req.subtopic,
+			Publisher: req.publisher,
+		}
+		msgs, err := svc.LastMessages(ctx, req.key, req.chanID, filter)
+		if err != nil {
+			return nil, err
+		}
+
+		return lastMessagesRes{Messages: msgs}, nil
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// +build !test
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/shadow"
+)
+
+var _ shadow.Service = (*metricsMiddleware)(nil)
+
+type metricsMiddleware struct {
+	counter metrics.Counter
+	latency metrics.Histogram
+	svc     shadow.Service
+}
+
+// MetricsMiddleware instruments core service by tracking request count and
+// latency.
+func MetricsMiddleware(svc shadow.Service, counter metrics.Counter, latency metrics.Histogram) shadow.Service {
+	return &metricsMiddleware{
+		counter: counter,
+		latency: latency,
+		svc:     svc,
+	}
+}
+
+func (ms *metricsMiddleware) Update(ctx context.Context, msg mainflux.Message) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "update").Add(1)
+		ms.latency.With("method", "update").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Update(ctx, msg)
+}
+
+func (ms *metricsMiddleware) LastMessages(ctx context.Context, key, chanID string, filter shadow.Filter) ([]mainflux.Message, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "last_messages").Add(1)
+		ms.latency.With("method", "last_messages").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.LastMessages(ctx, key, chanID, filter)
+}
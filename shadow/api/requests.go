@@ -0,0 +1,25 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import "github.com/mainflux/mainflux/shadow"
+
+type lastMessagesReq struct {
+	key       string
+	chanID    string
+	subtopic  string
+	publisher string
+}
+
+func (req lastMessagesReq) validate() error {
+	if req.key == "" {
+		return shadow.ErrUnauthorizedAccess
+	}
+
+	if req.chanID == "" {
+		return shadow.ErrMalformedEntity
+	}
+
+	return nil
+}
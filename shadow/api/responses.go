@@ -0,0 +1,28 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/mainflux/mainflux"
+)
+
+var _ mainflux.Response = (*lastMessagesRes)(nil)
+
+type lastMessagesRes struct {
+	Messages []mainflux.Message `json:"messages"`
+}
+
+func (res lastMessagesRes) Code() int {
+	return http.StatusOK
+}
+
+func (res lastMessagesRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res lastMessagesRes) Empty() bool {
+	return false
+}
@@ -0,0 +1,8 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package shadow contains the domain concept definitions needed to support
+// Mainflux shadow service functionality. Shadow service keeps the most recent
+// message of each channel series, so that the current values can be read
+// without querying the message history.
+package shadow
@@ -0,0 +1,69 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/shadow"
+)
+
+var _ shadow.Repository = (*repositoryMock)(nil)
+
+type repositoryMock struct {
+	mu       sync.Mutex
+	messages map[string]map[string]mainflux.Message
+}
+
+// NewRepository returns shadow repository mock.
+func NewRepository() shadow.Repository {
+	return &repositoryMock{
+		messages: map[string]map[string]mainflux.Message{},
+	}
+}
+
+func (rm *repositoryMock) Save(_ context.Context, msgs ...mainflux.Message) error {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	for _, msg := range msgs {
+		if _, ok := rm.messages[msg.Channel]; !ok {
+			rm.messages[msg.Channel] = map[string]mainflux.Message{}
+		}
+
+		key := series(msg)
+		if last, ok := rm.messages[msg.Channel][key]; ok && last.Time > msg.Time {
+			continue
+		}
+		rm.messages[msg.Channel][key] = msg
+	}
+
+	return nil
+}
+
+func (rm *repositoryMock) Retrieve(_ context.Context, chanID string, filter shadow.Filter) ([]mainflux.Message, error) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	msgs := []mainflux.Message{}
+	for _, msg := range rm.messages[chanID] {
+		if filter.Matches(msg) {
+			msgs = append(msgs, msg)
+		}
+	}
+
+	sort.Slice(msgs, func(i, j int) bool {
+		return series(msgs[i]) < series(msgs[j])
+	})
+
+	return msgs, nil
+}
+
+func series(msg mainflux.Message) string {
+	return fmt.Sprintf("%s:%s:%s", msg.Subtopic, msg.Publisher, msg.Name)
+}
@@ -0,0 +1,108 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package redis
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/go-redis/redis"
+	"github.com/gogo/protobuf/proto"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/shadow"
+)
+
+const channelPrefix = "shadow:channel"
+
+// Last messages of the channel are kept in the hash mapping the series to
+// the encoded message, along with the hash mapping the series to the message
+// time. The message is replaced only if the new one isn't older, so that the
+// messages saved out of order can't overwrite the more recent ones. Channel
+// ID is the hash tag of both keys, so that they are kept on the same cluster
+// node.
+var saveScript = redis.NewScript(`
+for i = 1, #ARGV, 3 do
+	local t = tonumber(redis.call('HGET', KEYS[2], ARGV[i]))
+	if not t or t <= tonumber(ARGV[i + 1]) then
+		redis.call('HSET', KEYS[1], ARGV[i], ARGV[i + 2])
+		redis.call('HSET', KEYS[2], ARGV[i], ARGV[i + 1])
+	end
+end
+return 1
+`)
+
+var _ shadow.Repository = (*repository)(nil)
+
+type repository struct {
+	client redis.UniversalClient
+}
+
+// NewRepository returns redis shadow repository implementation.
+func NewRepository(client redis.UniversalClient) shadow.Repository {
+	return &repository{
+		client: client,
+	}
+}
+
+func (r *repository) Save(_ context.Context, msgs ...mainflux.Message) error {
+	args := make(map[string][]interface{})
+	for _, msg := range msgs {
+		data, err := proto.Marshal(&msg)
+		if err != nil {
+			return err
+		}
+
+		t := strconv.FormatFloat(msg.Time, 'f', -1, 64)
+		args[msg.Channel] = append(args[msg.Channel], series(msg), t, data)
+	}
+
+	for channel, a := range args {
+		keys := []string{messagesKey(channel), timesKey(channel)}
+		if err := saveScript.Run(r.client, keys, a...).Err(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *repository) Retrieve(_ context.Context, chanID string, filter shadow.Filter) ([]mainflux.Message, error) {
+	res, err := r.client.HGetAll(messagesKey(chanID)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	msgs := []mainflux.Message{}
+	for _, data := range res {
+		var msg mainflux.Message
+		if err := proto.Unmarshal([]byte(data), &msg); err != nil {
+			return nil, err
+		}
+
+		if filter.Matches(msg) {
+			msgs = append(msgs, msg)
+		}
+	}
+
+	sort.Slice(msgs, func(i, j int) bool {
+		return series(msgs[i]) < series(msgs[j])
+	})
+
+	return msgs, nil
+}
+
+// series identifies the messages of the same subtopic, publisher and name.
+func series(msg mainflux.Message) string {
+	return fmt.Sprintf("%s:%s:%s", msg.Subtopic, msg.Publisher, msg.Name)
+}
+
+func messagesKey(channel string) string {
+	return fmt.Sprintf("%s:{%s}:messages", channelPrefix, channel)
+}
+
+func timesKey(channel string) string {
+	return fmt.Sprintf("%s:{%s}:times", channelPrefix, channel)
+}
@@ -0,0 +1,84 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package redis_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/shadow"
+	r "github.com/mainflux/mainflux/shadow/redis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const chanID = "1"
+
+func message(subtopic, publisher, name string, t, v float64) mainflux.Message {
+	return mainflux.Message{
+		Channel:   chanID,
+		Subtopic:  subtopic,
+		Publisher: publisher,
+		Protocol:  "mqtt",
+		Name:      name,
+		Time:      t,
+		Value:     &mainflux.Message_FloatValue{FloatValue: v},
+	}
+}
+
+func TestSaveRetrieve(t *testing.T) {
+	redisClient.FlushAll()
+	repo := r.NewRepository(redisClient)
+
+	msgs := []mainflux.Message{
+		message("", "a", "temperature", 1000, 20),
+		message("", "a", "temperature", 1002, 22),
+		message("", "a", "humidity", 1002, 40),
+		message("room", "b", "temperature", 1001, 25),
+		// Late message doesn't overwrite the more recent one.
+		message("", "a", "temperature", 1001, 21),
+	}
+	err := repo.Save(context.Background(), msgs...)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		chanID string
+		filter shadow.Filter
+		msgs   []mainflux.Message
+	}{
+		{
+			desc:   "retrieve all last messages of the channel",
+			chanID: chanID,
+			filter: shadow.Filter{},
+			msgs:   []mainflux.Message{msgs[2], msgs[1], msgs[3]},
+		},
+		{
+			desc:   "retrieve last messages of the subtopic",
+			chanID: chanID,
+			filter: shadow.Filter{Subtopic: "room"},
+			msgs:   []mainflux.Message{msgs[3]},
+		},
+		{
+			desc:   "retrieve last messages of the publisher",
+			chanID: chanID,
+			filter: shadow.Filter{Publisher: "a"},
+			msgs:   []mainflux.Message{msgs[2], msgs[1]},
+		},
+		{
+			desc:   "retrieve last messages of the channel without messages",
+			chanID: "2",
+			filter: shadow.Filter{},
+			msgs:   []mainflux.Message{},
+		},
+	}
+
+	for _, tc := range cases {
+		msgs, err := repo.Retrieve(context.Background(), tc.chanID, tc.filter)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		assert.Equal(t, tc.msgs, msgs, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.msgs, msgs))
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package shadow
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/things"
+)
+
+var (
+	// ErrMalformedEntity indicates malformed entity specification.
+	ErrMalformedEntity = errors.New("malformed entity specification")
+
+	// ErrUnauthorizedAccess indicates missing or invalid credentials provided
+	// when accessing a protected resource.
+	ErrUnauthorizedAccess = errors.New("missing or invalid credentials provided")
+)
+
+// Service specifies an API that must be fulfilled by the domain service
+// implementation, and all of its decorators (e.g. logging & metrics).
+type Service interface {
+	// Update records the message as the last one of its series, unless a
+	// more recent message of the series is already recorded.
+	Update(context.Context, mainflux.Message) error
+
+	// LastMessages retrieves the last messages of the channel identified by
+	// the provided ID which pass the filter. The thing identified by the
+	// provided key has to be allowed to read the channel history.
+	LastMessages(context.Context, string, string, Filter) ([]mainflux.Message, error)
+}
+
+var _ Service = (*shadowService)(nil)
+
+type shadowService struct {
+	things mainflux.ThingsServiceClient
+	repo   Repository
+}
+
+// New instantiates the shadow service implementation.
+func New(things mainflux.ThingsServiceClient, repo Repository) Service {
+	return &shadowService{
+		things: things,
+		repo:   repo,
+	}
+}
+
+func (ss *shadowService) Update(ctx context.Context, msg mainflux.Message) error {
+	if msg.Channel == "" || msg.Publisher == "" {
+		return ErrMalformedEntity
+	}
+
+	return ss.repo.Save(ctx, msg)
+}
+
+func (ss *shadowService) LastMessages(ctx context.Context, key, chanID string, filter Filter) ([]mainflux.Message, error) {
+	if err := ss.authorize(ctx, key, chanID); err != nil {
+		return nil, err
+	}
+
+	return ss.repo.Retrieve(ctx, chanID, filter)
+}
+
+func (ss *shadowService) authorize(ctx context.Context, key, chanID string) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	req := &mainflux.AccessReq{Token: key, ChanID: chanID, Action: things.ReadHistory}
+	if _, err := ss.things.CanAccess(ctx, req); err != nil {
+		return ErrUnauthorizedAccess
+	}
+
+	return nil
+}
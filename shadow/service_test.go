@@ -0,0 +1,126 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package shadow_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/shadow"
+	"github.com/mainflux/mainflux/shadow/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	chanID     = "1"
+	key        = "key"
+	invalidKey = "invalid"
+)
+
+func newService() shadow.Service {
+	return shadow.New(mocks.NewThingsService(), mocks.NewRepository())
+}
+
+func message(subtopic, publisher string, t, v float64) mainflux.Message {
+	return mainflux.Message{
+		Channel:   chanID,
+		Subtopic:  subtopic,
+		Publisher: publisher,
+		Protocol:  "http",
+		Name:      "temperature",
+		Time:      t,
+		Value:     &mainflux.Message_FloatValue{FloatValue: v},
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	svc := newService()
+
+	cases := []struct {
+		desc string
+		msg  mainflux.Message
+		err  error
+	}{
+		{
+			desc: "update last message",
+			msg:  message("", "a", 1000, 20),
+			err:  nil,
+		},
+		{
+			desc: "update last message without channel",
+			msg:  mainflux.Message{Publisher: "a", Time: 1000},
+			err:  shadow.ErrMalformedEntity,
+		},
+		{
+			desc: "update last message without publisher",
+			msg:  mainflux.Message{Channel: chanID, Time: 1000},
+			err:  shadow.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.Update(context.Background(), tc.msg)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestLastMessages(t *testing.T) {
+	svc := newService()
+
+	msgs := []mainflux.Message{
+		message("", "a", 1001, 21),
+		message("", "a", 1000, 20),
+		message("room", "b", 1000, 25),
+	}
+	for _, msg := range msgs {
+		err := svc.Update(context.Background(), msg)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	cases := []struct {
+		desc   string
+		key    string
+		filter shadow.Filter
+		msgs   []mainflux.Message
+		err    error
+	}{
+		{
+			desc:   "retrieve last messages of the channel",
+			key:    key,
+			filter: shadow.Filter{},
+			msgs:   []mainflux.Message{msgs[0], msgs[2]},
+			err:    nil,
+		},
+		{
+			desc:   "retrieve last messages of the subtopic",
+			key:    key,
+			filter: shadow.Filter{Subtopic: "room"},
+			msgs:   []mainflux.Message{msgs[2]},
+			err:    nil,
+		},
+		{
+			desc:   "retrieve last messages of the publisher",
+			key:    key,
+			filter: shadow.Filter{Publisher: "a"},
+			msgs:   []mainflux.Message{msgs[0]},
+			err:    nil,
+		},
+		{
+			desc:   "retrieve last messages with invalid key",
+			key:    invalidKey,
+			filter: shadow.Filter{},
+			msgs:   nil,
+			err:    shadow.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		msgs, err := svc.LastMessages(context.Background(), tc.key, chanID, tc.filter)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.msgs, msgs, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.msgs, msgs))
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package shadow
+
+import (
+	"context"
+
+	"github.com/mainflux/mainflux"
+)
+
+// Filter limits the last messages of the channel to the ones published to the
+// subtopic or by the publisher. Empty fields match any message.
+type Filter struct {
+	Subtopic  string
+	Publisher string
+}
+
+// Matches determines whether the message passes the filter.
+func (f Filter) Matches(msg mainflux.Message) bool {
+	if f.Subtopic != "" && f.Subtopic != msg.Subtopic {
+		return false
+	}
+
+	if f.Publisher != "" && f.Publisher != msg.Publisher {
+		return false
+	}
+
+	return true
+}
+
+// Repository specifies the last messages persistence API. The most recent
+// message is kept per channel, subtopic, publisher and SenML record name, so
+// that the records of the same pack don't overwrite each other.
+type Repository interface {
+	// Save saves the messages which are more recent than the ones of the
+	// same series. Older messages are ignored, so that the messages can be
+	// saved out of order.
+	Save(context.Context, ...mainflux.Message) error
+
+	// Retrieve retrieves the last messages of the channel which pass the
+	// filter, ordered by subtopic, publisher and name.
+	Retrieve(context.Context, string, Filter) ([]mainflux.Message, error)
+}
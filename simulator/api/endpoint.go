@@ -0,0 +1,133 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/mainflux/mainflux/simulator"
+)
+
+func startSimulationEndpoint(svc simulator.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(startSimulationReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		sim := simulator.Simulation{
+			Protocol: req.Protocol,
+			URL:      req.URL,
+			Channel:  req.Channel,
+			Subtopic: req.Subtopic,
+			ThingID:  req.ThingID,
+			ThingKey: req.ThingKey,
+			Devices:  req.Devices,
+			Interval: req.interval,
+			Pattern: simulator.Pattern{
+				Type:   req.Pattern.Type,
+				Name:   req.Pattern.Name,
+				Unit:   req.Pattern.Unit,
+				Min:    req.Pattern.Min,
+				Max:    req.Pattern.Max,
+				Period: req.Pattern.period,
+			},
+		}
+
+		saved, err := svc.StartSimulation(ctx, req.token, sim)
+		if err != nil {
+			return nil, err
+		}
+
+		return simulationRes{id: saved.ID}, nil
+	}
+}
+
+func viewSimulationEndpoint(svc simulator.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewSimulationReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		sim, err := svc.ViewSimulation(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		return toRes(sim), nil
+	}
+}
+
+func listSimulationsEndpoint(svc simulator.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listSimulationsReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		sims, err := svc.ListSimulations(ctx, req.token)
+		if err != nil {
+			return nil, err
+		}
+
+		res := simulationsRes{Simulations: []viewSimulationRes{}}
+		for _, sim := range sims {
+			res.Simulations = append(res.Simulations, toRes(sim))
+		}
+
+		return res, nil
+	}
+}
+
+func stopSimulationEndpoint(svc simulator.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewSimulationReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.StopSimulation(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+
+		return stopRes{}, nil
+	}
+}
+
+func toRes(sim simulator.Simulation) viewSimulationRes {
+	period := ""
+	if sim.Pattern.Period > 0 {
+		period = sim.Pattern.Period.String()
+	}
+
+	return viewSimulationRes{
+		ID:       sim.ID,
+		Protocol: sim.Protocol,
+		URL:      sim.URL,
+		Channel:  sim.Channel,
+		Subtopic: sim.Subtopic,
+		ThingID:  sim.ThingID,
+		Devices:  sim.Devices,
+		Interval: sim.Interval.String(),
+		Pattern: patternReq{
+			Type:   sim.Pattern.Type,
+			Name:   sim.Pattern.Name,
+			Unit:   sim.Pattern.Unit,
+			Min:    sim.Pattern.Min,
+			Max:    sim.Pattern.Max,
+			Period: period,
+		},
+		Started: sim.Started,
+		Stats: statsRes{
+			Published: sim.Stats.Published,
+			Failed:    sim.Stats.Failed,
+		},
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// +build !test
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/simulator"
+)
+
+var _ simulator.Service = (*loggingMiddleware)(nil)
+
+type loggingMiddleware struct {
+	logger log.Logger
+	svc    simulator.Service
+}
+
+// LoggingMiddleware adds logging facilities to the core service.
+func LoggingMiddleware(svc simulator.Service, logger log.Logger) simulator.Service {
+	return &loggingMiddleware{logger, svc}
+}
+
+func (lm *loggingMiddleware) StartSimulation(ctx context.Context, token string, sim simulator.Simulation) (saved simulator.Simulation, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method start_simulation for token %s and simulation %s took %s to complete", token, saved.ID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.StartSimulation(ctx, token, sim)
+}
+
+func (lm *loggingMiddleware) ViewSimulation(ctx context.Context, token, id string) (sim simulator.Simulation, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method view_simulation for token %s and simulation %s took %s to complete", token, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ViewSimulation(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) ListSimulations(ctx context.Context, token string) (sims []simulator.Simulation, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_simulations for token %s took %s to complete", token, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListSimulations(ctx, token)
+}
+
+func (lm *loggingMiddleware) StopSimulation(ctx context.Context, token, id string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method stop_simulation for token %s and simulation %s took %s to complete", token, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.StopSimulation(ctx, token, id)
+}
@@ -0,0 +1,68 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// +build !test
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/mainflux/mainflux/simulator"
+)
+
+var _ simulator.Service = (*metricsMiddleware)(nil)
+
+type metricsMiddleware struct {
+	counter metrics.Counter
+	latency metrics.Histogram
+	svc     simulator.Service
+}
+
+// MetricsMiddleware instruments core service by tracking request count and
+// latency.
+func MetricsMiddleware(svc simulator.Service, counter metrics.Counter, latency metrics.Histogram) simulator.Service {
+	return &metricsMiddleware{
+		counter: counter,
+		latency: latency,
+		svc:     svc,
+	}
+}
+
+func (ms *metricsMiddleware) StartSimulation(ctx context.Context, token string, sim simulator.Simulation) (simulator.Simulation, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "start_simulation").Add(1)
+		ms.latency.With("method", "start_simulation").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.StartSimulation(ctx, token, sim)
+}
+
+func (ms *metricsMiddleware) ViewSimulation(ctx context.Context, token, id string) (simulator.Simulation, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_simulation").Add(1)
+		ms.latency.With("method", "view_simulation").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewSimulation(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) ListSimulations(ctx context.Context, token string) ([]simulator.Simulation, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_simulations").Add(1)
+		ms.latency.With("method", "list_simulations").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListSimulations(ctx, token)
+}
+
+func (ms *metricsMiddleware) StopSimulation(ctx context.Context, token, id string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "stop_simulation").Add(1)
+		ms.latency.With("method", "stop_simulation").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.StopSimulation(ctx, token, id)
+}
@@ -0,0 +1,90 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"time"
+
+	"github.com/mainflux/mainflux/simulator"
+)
+
+type apiReq interface {
+	validate() error
+}
+
+type patternReq struct {
+	Type   string  `json:"type"`
+	Name   string  `json:"name"`
+	Unit   string  `json:"unit,omitempty"`
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+	Period string  `json:"period,omitempty"`
+	period time.Duration
+}
+
+type startSimulationReq struct {
+	token    string
+	Protocol string     `json:"protocol"`
+	URL      string     `json:"url"`
+	Channel  string     `json:"channel"`
+	Subtopic string     `json:"subtopic,omitempty"`
+	ThingID  string     `json:"thing_id,omitempty"`
+	ThingKey string     `json:"thing_key"`
+	Devices  uint64     `json:"devices"`
+	Interval string     `json:"interval"`
+	Pattern  patternReq `json:"pattern"`
+	interval time.Duration
+}
+
+// validate parses durations as well, which is why it has pointer receiver.
+func (req *startSimulationReq) validate() error {
+	if req.token == "" {
+		return simulator.ErrUnauthorizedAccess
+	}
+
+	interval, err := time.ParseDuration(req.Interval)
+	if err != nil {
+		return simulator.ErrMalformedEntity
+	}
+	req.interval = interval
+
+	if req.Pattern.Period != "" {
+		period, err := time.ParseDuration(req.Pattern.Period)
+		if err != nil {
+			return simulator.ErrMalformedEntity
+		}
+		req.Pattern.period = period
+	}
+
+	return nil
+}
+
+type viewSimulationReq struct {
+	token string
+	id    string
+}
+
+func (req viewSimulationReq) validate() error {
+	if req.token == "" {
+		return simulator.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" {
+		return simulator.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type listSimulationsReq struct {
+	token string
+}
+
+func (req listSimulationsReq) validate() error {
+	if req.token == "" {
+		return simulator.ErrUnauthorizedAccess
+	}
+
+	return nil
+}
@@ -0,0 +1,98 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/mainflux/mainflux"
+)
+
+var (
+	_ mainflux.Response = (*simulationRes)(nil)
+	_ mainflux.Response = (*viewSimulationRes)(nil)
+	_ mainflux.Response = (*simulationsRes)(nil)
+	_ mainflux.Response = (*stopRes)(nil)
+)
+
+type simulationRes struct {
+	id string
+}
+
+func (res simulationRes) Code() int {
+	return http.StatusCreated
+}
+
+func (res simulationRes) Headers() map[string]string {
+	return map[string]string{
+		"Location": fmt.Sprintf("/simulations/%s", res.id),
+	}
+}
+
+func (res simulationRes) Empty() bool {
+	return true
+}
+
+type statsRes struct {
+	Published uint64 `json:"published"`
+	Failed    uint64 `json:"failed"`
+}
+
+type viewSimulationRes struct {
+	ID       string     `json:"id"`
+	Protocol string     `json:"protocol"`
+	URL      string     `json:"url"`
+	Channel  string     `json:"channel"`
+	Subtopic string     `json:"subtopic,omitempty"`
+	ThingID  string     `json:"thing_id,omitempty"`
+	Devices  uint64     `json:"devices"`
+	Interval string     `json:"interval"`
+	Pattern  patternReq `json:"pattern"`
+	Started  time.Time  `json:"started"`
+	Stats    statsRes   `json:"stats"`
+}
+
+func (res viewSimulationRes) Code() int {
+	return http.StatusOK
+}
+
+func (res viewSimulationRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res viewSimulationRes) Empty() bool {
+	return false
+}
+
+type simulationsRes struct {
+	Simulations []viewSimulationRes `json:"simulations"`
+}
+
+func (res simulationsRes) Code() int {
+	return http.StatusOK
+}
+
+func (res simulationsRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res simulationsRes) Empty() bool {
+	return false
+}
+
+type stopRes struct{}
+
+func (res stopRes) Code() int {
+	return http.StatusNoContent
+}
+
+func (res stopRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res stopRes) Empty() bool {
+	return true
+}
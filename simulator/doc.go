@@ -0,0 +1,8 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package simulator contains the domain concept definitions needed to
+// support Mainflux simulator service functionality. Simulator service spawns
+// virtual devices which publish generated SenML messages to the target
+// deployment, and is meant to be used for demos and testing.
+package simulator
@@ -0,0 +1,61 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"sync"
+
+	"github.com/mainflux/mainflux/simulator"
+)
+
+// Publisher is an in-memory publisher which records published payloads
+// per simulation.
+type Publisher struct {
+	mu        sync.Mutex
+	published map[string][][]byte
+	closed    map[string]bool
+}
+
+var _ simulator.Publisher = (*Publisher)(nil)
+
+// NewPublisher returns publisher mock.
+func NewPublisher() *Publisher {
+	return &Publisher{
+		published: make(map[string][][]byte),
+		closed:    make(map[string]bool),
+	}
+}
+
+// Publish records the published payload.
+func (p *Publisher) Publish(sim simulator.Simulation, payload []byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.published[sim.ID] = append(p.published[sim.ID], payload)
+	return nil
+}
+
+// Close marks the simulation as closed.
+func (p *Publisher) Close(sim simulator.Simulation) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.closed[sim.ID] = true
+}
+
+// Published returns all the payloads published by the simulation.
+func (p *Publisher) Published(id string) [][]byte {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([][]byte{}, p.published[id]...)
+}
+
+// Closed returns true if simulation publishing has been closed.
+func (p *Publisher) Closed(id string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.closed[id]
+}
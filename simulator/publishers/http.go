@@ -0,0 +1,59 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package publishers contains publisher implementations which send simulated
+// messages to the Mainflux protocol adapters.
+package publishers
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/simulator"
+)
+
+var _ simulator.Publisher = (*httpPublisher)(nil)
+
+type httpPublisher struct {
+	client *http.Client
+}
+
+// NewHTTP returns publisher which sends messages to the HTTP adapter
+// located at the simulation URL.
+func NewHTTP(timeout time.Duration) simulator.Publisher {
+	return httpPublisher{
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (hp httpPublisher) Publish(sim simulator.Simulation, payload []byte) error {
+	url := fmt.Sprintf("%s/channels/%s/messages", strings.TrimSuffix(sim.URL, "/"), sim.Channel)
+	if sim.Subtopic != "" {
+		url = fmt.Sprintf("%s/%s", url, sim.Subtopic)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", sim.ThingKey)
+	req.Header.Set("Content-Type", mainflux.SenMLJSON)
+
+	res, err := hp.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusAccepted {
+		return fmt.Errorf("unexpected response status: %s", res.Status)
+	}
+
+	return nil
+}
+
+func (hp httpPublisher) Close(simulator.Simulation) {}
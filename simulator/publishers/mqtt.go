@@ -0,0 +1,94 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package publishers
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/mainflux/mainflux/simulator"
+)
+
+const (
+	qos         = 0
+	mqttTimeout = 5 * time.Second
+)
+
+var errTimeout = errors.New("timeout while communicating with MQTT adapter")
+
+var _ simulator.Publisher = (*mqttPublisher)(nil)
+
+type mqttPublisher struct {
+	mu      sync.Mutex
+	clients map[string]mqtt.Client
+}
+
+// NewMQTT returns publisher which sends messages to the MQTT adapter
+// located at the simulation URL. Each simulation uses its own connection,
+// authenticated with the simulation thing credentials.
+func NewMQTT() simulator.Publisher {
+	return &mqttPublisher{
+		clients: make(map[string]mqtt.Client),
+	}
+}
+
+func (mp *mqttPublisher) Publish(sim simulator.Simulation, payload []byte) error {
+	c, err := mp.client(sim)
+	if err != nil {
+		return err
+	}
+
+	topic := fmt.Sprintf("channels/%s/messages", sim.Channel)
+	if sim.Subtopic != "" {
+		topic = fmt.Sprintf("%s/%s", topic, sim.Subtopic)
+	}
+
+	token := c.Publish(topic, qos, false, payload)
+	if !token.WaitTimeout(mqttTimeout) {
+		return errTimeout
+	}
+
+	return token.Error()
+}
+
+func (mp *mqttPublisher) Close(sim simulator.Simulation) {
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+
+	if c, ok := mp.clients[sim.ID]; ok {
+		c.Disconnect(0)
+		delete(mp.clients, sim.ID)
+	}
+}
+
+func (mp *mqttPublisher) client(sim simulator.Simulation) (mqtt.Client, error) {
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+
+	if c, ok := mp.clients[sim.ID]; ok {
+		return c, nil
+	}
+
+	opts := mqtt.NewClientOptions().
+		AddBroker(sim.URL).
+		SetClientID(fmt.Sprintf("simulator-%s", sim.ID)).
+		SetUsername(sim.ThingID).
+		SetPassword(sim.ThingKey).
+		SetAutoReconnect(true)
+
+	c := mqtt.NewClient(opts)
+	token := c.Connect()
+	if !token.WaitTimeout(mqttTimeout) {
+		return nil, errTimeout
+	}
+	if err := token.Error(); err != nil {
+		return nil, err
+	}
+
+	mp.clients[sim.ID] = c
+	return c, nil
+}
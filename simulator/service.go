@@ -0,0 +1,243 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cisco/senml"
+	"github.com/mainflux/mainflux"
+)
+
+var (
+	// ErrMalformedEntity indicates malformed entity specification.
+	ErrMalformedEntity = errors.New("malformed entity specification")
+
+	// ErrUnauthorizedAccess indicates missing or invalid credentials provided
+	// when accessing a protected resource.
+	ErrUnauthorizedAccess = errors.New("missing or invalid credentials provided")
+
+	// ErrNotFound indicates a non-existent entity request.
+	ErrNotFound = errors.New("non-existent entity")
+)
+
+// Service specifies an API that must be fulfilled by the domain service
+// implementation, and all of its decorators (e.g. logging & metrics).
+type Service interface {
+	// StartSimulation starts new simulation owned by the user identified by
+	// the provided key.
+	StartSimulation(context.Context, string, Simulation) (Simulation, error)
+
+	// ViewSimulation retrieves the running simulation identified by the
+	// provided ID, that belongs to the user identified by the provided key.
+	ViewSimulation(context.Context, string, string) (Simulation, error)
+
+	// ListSimulations retrieves all the running simulations that belong to
+	// the user identified by the provided key.
+	ListSimulations(context.Context, string) ([]Simulation, error)
+
+	// StopSimulation stops the simulation identified by the provided ID,
+	// that belongs to the user identified by the provided key.
+	StopSimulation(context.Context, string, string) error
+}
+
+var _ Service = (*simulatorService)(nil)
+
+type simulation struct {
+	Simulation
+	published uint64
+	failed    uint64
+	done      chan struct{}
+}
+
+func (s *simulation) view() Simulation {
+	sim := s.Simulation
+	sim.Stats = Stats{
+		Published: atomic.LoadUint64(&s.published),
+		Failed:    atomic.LoadUint64(&s.failed),
+	}
+	return sim
+}
+
+type simulatorService struct {
+	users      mainflux.UsersServiceClient
+	publishers map[string]Publisher
+	idp        IdentityProvider
+
+	mu          sync.Mutex
+	simulations map[string]*simulation
+}
+
+// New instantiates the simulator service implementation. Publishers are
+// keyed by the protocol they use.
+func New(users mainflux.UsersServiceClient, publishers map[string]Publisher, idp IdentityProvider) Service {
+	return &simulatorService{
+		users:       users,
+		publishers:  publishers,
+		idp:         idp,
+		simulations: make(map[string]*simulation),
+	}
+}
+
+func (ss *simulatorService) StartSimulation(ctx context.Context, token string, sim Simulation) (Simulation, error) {
+	owner, err := ss.identify(ctx, token)
+	if err != nil {
+		return Simulation{}, err
+	}
+
+	if err := sim.Validate(); err != nil {
+		return Simulation{}, err
+	}
+
+	pub, ok := ss.publishers[sim.Protocol]
+	if !ok {
+		return Simulation{}, ErrMalformedEntity
+	}
+
+	sim.ID, err = ss.idp.ID()
+	if err != nil {
+		return Simulation{}, err
+	}
+	sim.Owner = owner
+	sim.Started = time.Now()
+	sim.Stats = Stats{}
+
+	s := &simulation{
+		Simulation: sim,
+		done:       make(chan struct{}),
+	}
+
+	ss.mu.Lock()
+	ss.simulations[sim.ID] = s
+	ss.mu.Unlock()
+
+	go ss.run(s, pub)
+
+	return sim, nil
+}
+
+func (ss *simulatorService) ViewSimulation(ctx context.Context, token, id string) (Simulation, error) {
+	owner, err := ss.identify(ctx, token)
+	if err != nil {
+		return Simulation{}, err
+	}
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	s, ok := ss.simulations[id]
+	if !ok || s.Owner != owner {
+		return Simulation{}, ErrNotFound
+	}
+
+	return s.view(), nil
+}
+
+func (ss *simulatorService) ListSimulations(ctx context.Context, token string) ([]Simulation, error) {
+	owner, err := ss.identify(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	sims := []Simulation{}
+	for _, s := range ss.simulations {
+		if s.Owner == owner {
+			sims = append(sims, s.view())
+		}
+	}
+
+	sort.Slice(sims, func(i, j int) bool {
+		return sims[i].Started.Before(sims[j].Started)
+	})
+
+	return sims, nil
+}
+
+func (ss *simulatorService) StopSimulation(ctx context.Context, token, id string) error {
+	owner, err := ss.identify(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	s, ok := ss.simulations[id]
+	if !ok || s.Owner != owner {
+		return ErrNotFound
+	}
+
+	close(s.done)
+	delete(ss.simulations, id)
+
+	return nil
+}
+
+func (ss *simulatorService) identify(ctx context.Context, token string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	res, err := ss.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return "", ErrUnauthorizedAccess
+	}
+
+	return res.GetValue(), nil
+}
+
+func (ss *simulatorService) run(s *simulation, pub Publisher) {
+	defer pub.Close(s.Simulation)
+
+	gens := make([]*generator, s.Devices)
+	for i := range gens {
+		gens[i] = newGenerator(s.Pattern, uint64(i), s.Devices)
+	}
+
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case t := <-ticker.C:
+			for i, g := range gens {
+				payload, err := encode(s.Simulation, uint64(i), g.next(t), t)
+				if err == nil {
+					err = pub.Publish(s.Simulation, payload)
+				}
+
+				if err != nil {
+					atomic.AddUint64(&s.failed, 1)
+					continue
+				}
+				atomic.AddUint64(&s.published, 1)
+			}
+		}
+	}
+}
+
+// encode creates SenML JSON message of a single device. Base name carries
+// both simulation and device identifiers.
+func encode(sim Simulation, device uint64, value float64, t time.Time) ([]byte, error) {
+	rec := senml.SenMLRecord{
+		BaseName: fmt.Sprintf("%s:%d:", sim.ID, device),
+		Name:     sim.Pattern.Name,
+		Unit:     sim.Pattern.Unit,
+		Value:    &value,
+		Time:     float64(t.UnixNano()) / 1e9,
+	}
+
+	s := senml.SenML{Records: []senml.SenMLRecord{rec}}
+	return senml.Encode(s, senml.JSON, senml.OutputOptions{})
+}
@@ -0,0 +1,270 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/simulator"
+	"github.com/mainflux/mainflux/simulator/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	wrongValue = "wrong-value"
+	email      = "user@example.com"
+	token      = "token"
+)
+
+var sim = simulator.Simulation{
+	Protocol: simulator.HTTP,
+	URL:      "http://localhost:8185",
+	Channel:  "1",
+	ThingKey: "key",
+	Devices:  3,
+	Interval: 10 * time.Millisecond,
+	Pattern: simulator.Pattern{
+		Type:   simulator.Sine,
+		Name:   "temperature",
+		Unit:   "Cel",
+		Min:    10,
+		Max:    30,
+		Period: time.Second,
+	},
+}
+
+func newService(tokens map[string]string) (simulator.Service, *mocks.Publisher) {
+	users := mocks.NewUsersService(tokens)
+	pub := mocks.NewPublisher()
+	idp := mocks.NewIdentityProvider()
+	pubs := map[string]simulator.Publisher{
+		simulator.HTTP: pub,
+		simulator.MQTT: pub,
+	}
+
+	return simulator.New(users, pubs, idp), pub
+}
+
+func TestStartSimulation(t *testing.T) {
+	svc, _ := newService(map[string]string{token: email})
+
+	noThing := sim
+	noThing.Protocol = simulator.MQTT
+
+	unknown := sim
+	unknown.Protocol = "amqp"
+
+	tooMany := sim
+	tooMany.Devices = 1001
+
+	badPattern := sim
+	badPattern.Pattern.Type = "square"
+
+	noPeriod := sim
+	noPeriod.Pattern.Period = 0
+
+	walk := sim
+	walk.Pattern.Type = simulator.RandomWalk
+	walk.Pattern.Period = 0
+
+	cases := []struct {
+		desc  string
+		sim   simulator.Simulation
+		token string
+		err   error
+	}{
+		{
+			desc:  "start valid simulation",
+			sim:   sim,
+			token: token,
+			err:   nil,
+		},
+		{
+			desc:  "start random walk simulation without period",
+			sim:   walk,
+			token: token,
+			err:   nil,
+		},
+		{
+			desc:  "start simulation with wrong credentials",
+			sim:   sim,
+			token: wrongValue,
+			err:   simulator.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "start MQTT simulation without thing ID",
+			sim:   noThing,
+			token: token,
+			err:   simulator.ErrMalformedEntity,
+		},
+		{
+			desc:  "start simulation with unsupported protocol",
+			sim:   unknown,
+			token: token,
+			err:   simulator.ErrMalformedEntity,
+		},
+		{
+			desc:  "start simulation with too many devices",
+			sim:   tooMany,
+			token: token,
+			err:   simulator.ErrMalformedEntity,
+		},
+		{
+			desc:  "start simulation with unknown pattern",
+			sim:   badPattern,
+			token: token,
+			err:   simulator.ErrMalformedEntity,
+		},
+		{
+			desc:  "start sine simulation without period",
+			sim:   noPeriod,
+			token: token,
+			err:   simulator.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := svc.StartSimulation(context.Background(), tc.token, tc.sim)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestViewSimulation(t *testing.T) {
+	svc, _ := newService(map[string]string{token: email, wrongValue: "other@example.com"})
+	saved, err := svc.StartSimulation(context.Background(), token, sim)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := map[string]struct {
+		id    string
+		token string
+		err   error
+	}{
+		"view existing simulation": {
+			id:    saved.ID,
+			token: token,
+			err:   nil,
+		},
+		"view simulation with wrong credentials": {
+			id:    saved.ID,
+			token: "invalid",
+			err:   simulator.ErrUnauthorizedAccess,
+		},
+		"view simulation owned by another user": {
+			id:    saved.ID,
+			token: wrongValue,
+			err:   simulator.ErrNotFound,
+		},
+		"view non-existing simulation": {
+			id:    wrongValue,
+			token: token,
+			err:   simulator.ErrNotFound,
+		},
+	}
+
+	for desc, tc := range cases {
+		_, err := svc.ViewSimulation(context.Background(), tc.token, tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
+
+func TestListSimulations(t *testing.T) {
+	svc, _ := newService(map[string]string{token: email, wrongValue: "other@example.com"})
+
+	n := 5
+	for i := 0; i < n; i++ {
+		_, err := svc.StartSimulation(context.Background(), token, sim)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	cases := map[string]struct {
+		token string
+		size  int
+		err   error
+	}{
+		"list all simulations": {
+			token: token,
+			size:  n,
+			err:   nil,
+		},
+		"list simulations of another user": {
+			token: wrongValue,
+			size:  0,
+			err:   nil,
+		},
+		"list simulations with wrong credentials": {
+			token: "invalid",
+			size:  0,
+			err:   simulator.ErrUnauthorizedAccess,
+		},
+	}
+
+	for desc, tc := range cases {
+		sims, err := svc.ListSimulations(context.Background(), tc.token)
+		size := len(sims)
+		assert.Equal(t, tc.size, size, fmt.Sprintf("%s: expected %d got %d\n", desc, tc.size, size))
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
+
+func TestStopSimulation(t *testing.T) {
+	svc, pub := newService(map[string]string{token: email})
+	saved, err := svc.StartSimulation(context.Background(), token, sim)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	time.Sleep(5 * sim.Interval)
+
+	view, err := svc.ViewSimulation(context.Background(), token, saved.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.True(t, view.Stats.Published > 0, "expected simulation to publish messages")
+
+	for _, payload := range pub.Published(saved.ID) {
+		var recs []map[string]interface{}
+		err := json.Unmarshal(payload, &recs)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		require.Len(t, recs, 1, "expected single SenML record")
+
+		v, ok := recs[0]["v"].(float64)
+		require.True(t, ok, "expected numeric value")
+		assert.True(t, v >= sim.Pattern.Min && v <= sim.Pattern.Max, fmt.Sprintf("value %f out of bounds", v))
+	}
+
+	cases := []struct {
+		desc  string
+		id    string
+		token string
+		err   error
+	}{
+		{
+			desc:  "stop simulation with wrong credentials",
+			id:    saved.ID,
+			token: wrongValue,
+			err:   simulator.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "stop running simulation",
+			id:    saved.ID,
+			token: token,
+			err:   nil,
+		},
+		{
+			desc:  "stop stopped simulation",
+			id:    saved.ID,
+			token: token,
+			err:   simulator.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.StopSimulation(context.Background(), tc.token, tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	time.Sleep(2 * sim.Interval)
+	assert.True(t, pub.Closed(saved.ID), "expected publisher to be closed")
+}
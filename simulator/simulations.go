@@ -0,0 +1,160 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+const (
+	// HTTP protocol publishes messages using HTTP adapter.
+	HTTP = "http"
+	// MQTT protocol publishes messages using MQTT adapter.
+	MQTT = "mqtt"
+
+	// Sine pattern oscillates between the minimum and the maximum value.
+	Sine = "sine"
+	// RandomWalk pattern randomly moves the value within the bounds.
+	RandomWalk = "random_walk"
+	// Step pattern alternates between the minimum and the maximum value.
+	Step = "step"
+
+	maxDevices = 1000
+	minPeriod  = 10 * time.Millisecond
+)
+
+// Pattern describes the values published by virtual devices.
+type Pattern struct {
+	Type   string
+	Name   string
+	Unit   string
+	Min    float64
+	Max    float64
+	Period time.Duration
+}
+
+// Validate returns an error if pattern is not well-formed.
+func (p Pattern) Validate() error {
+	switch p.Type {
+	case Sine, Step:
+		if p.Period < minPeriod {
+			return ErrMalformedEntity
+		}
+	case RandomWalk:
+	default:
+		return ErrMalformedEntity
+	}
+
+	if p.Name == "" || p.Min > p.Max {
+		return ErrMalformedEntity
+	}
+
+	return nil
+}
+
+// Simulation represents a group of virtual devices that periodically
+// publish values following the same pattern to the target deployment.
+// All the devices publish using the same thing credentials and are
+// distinguished by SenML base name.
+type Simulation struct {
+	ID       string
+	Owner    string
+	Protocol string
+	URL      string
+	Channel  string
+	Subtopic string
+	ThingID  string
+	ThingKey string
+	Devices  uint64
+	Interval time.Duration
+	Pattern  Pattern
+	Started  time.Time
+	Stats    Stats
+}
+
+// Stats contains simulation publishing statistics.
+type Stats struct {
+	Published uint64
+	Failed    uint64
+}
+
+// Validate returns an error if simulation is not well-formed.
+func (s Simulation) Validate() error {
+	switch s.Protocol {
+	case HTTP:
+	case MQTT:
+		if s.ThingID == "" {
+			return ErrMalformedEntity
+		}
+	default:
+		return ErrMalformedEntity
+	}
+
+	if s.URL == "" || s.Channel == "" || s.ThingKey == "" {
+		return ErrMalformedEntity
+	}
+
+	if s.Devices == 0 || s.Devices > maxDevices || s.Interval < minPeriod {
+		return ErrMalformedEntity
+	}
+
+	return s.Pattern.Validate()
+}
+
+// Publisher specifies message publishing API for a single protocol.
+type Publisher interface {
+	// Publish sends the payload to the simulation target.
+	Publish(Simulation, []byte) error
+
+	// Close releases resources acquired for the simulation.
+	Close(Simulation)
+}
+
+// IdentityProvider specifies an API for generating unique identifiers.
+type IdentityProvider interface {
+	// ID generates the unique identifier.
+	ID() (string, error)
+}
+
+// generator produces the consecutive values of a single virtual device.
+type generator struct {
+	pattern Pattern
+	phase   float64
+	value   float64
+	rand    *rand.Rand
+}
+
+// newGenerator creates the device generator. Devices are shifted in phase,
+// so that the simulation doesn't publish identical series.
+func newGenerator(p Pattern, device, devices uint64) *generator {
+	r := rand.New(rand.NewSource(time.Now().UnixNano() + int64(device)))
+	return &generator{
+		pattern: p,
+		phase:   float64(device) / float64(devices),
+		value:   p.Min + r.Float64()*(p.Max-p.Min),
+		rand:    r,
+	}
+}
+
+func (g *generator) next(t time.Time) float64 {
+	p := g.pattern
+	switch p.Type {
+	case Sine:
+		x := float64(t.UnixNano())/float64(p.Period) + g.phase
+		return p.Min + (p.Max-p.Min)*(1+math.Sin(2*math.Pi*x))/2
+	case Step:
+		x := float64(t.UnixNano())/float64(p.Period) + g.phase
+		if int64(x)%2 == 0 {
+			return p.Min
+		}
+		return p.Max
+	default:
+		step := (p.Max - p.Min) / 20
+		g.value += (g.rand.Float64()*2 - 1) * step
+		g.value = math.Max(p.Min, math.Min(p.Max, g.value))
+		return g.value
+	}
+}
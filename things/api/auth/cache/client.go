@@ -0,0 +1,160 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package cache provides a things service client decorator that keeps the
+// granted channel accesses in the cache store, so that the adapters don't
+// consult the things service on every published message. The accesses are
+// kept either in the in-process LRU store, or in the store shared by all the
+// adapter instances, such as the one provided by the redis subpackage.
+//
+// Only the granted accesses are cached. The cached accesses are invalidated
+// by the connect, disconnect, key update and removal events of the things
+// service, while the cache TTL bounds the time the accesses revoked without
+// the event remain cached.
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/mainflux/mainflux"
+	"google.golang.org/grpc"
+)
+
+// ErrNotFound indicates the access which isn't cached.
+var ErrNotFound = errors.New("access not cached")
+
+var _ Client = (*cacheClient)(nil)
+
+// Invalidator invalidates the cached accesses.
+type Invalidator interface {
+	// Disconnect invalidates the accesses of the thing to the channel.
+	Disconnect(chanID, thingID string) error
+
+	// RemoveThing invalidates all the accesses of the thing.
+	RemoveThing(thingID string) error
+
+	// RemoveChannel invalidates all the accesses to the channel.
+	RemoveChannel(chanID string) error
+}
+
+// Store keeps the cached accesses until they expire or are invalidated.
+type Store interface {
+	Invalidator
+
+	// Get returns the ID of the thing whose access is cached under the key.
+	// It returns ErrNotFound if there is no such access.
+	Get(key string) (string, error)
+
+	// Add caches the access of the thing to the channel under the key.
+	Add(key, chanID, thingID string) error
+}
+
+// Client represents the things service client caching the granted accesses.
+type Client interface {
+	mainflux.ThingsServiceClient
+	Invalidator
+}
+
+type cacheClient struct {
+	client mainflux.ThingsServiceClient
+	store  Store
+}
+
+// NewClient wraps the things service client so that the accesses it grants
+// are cached in the store. The store failures aren't fatal, the access checks
+// fall back to the things service instead.
+func NewClient(client mainflux.ThingsServiceClient, store Store) Client {
+	return &cacheClient{
+		client: client,
+		store:  store,
+	}
+}
+
+func (cc *cacheClient) CanAccess(ctx context.Context, req *mainflux.AccessReq, opts ...grpc.CallOption) (*mainflux.ThingID, error) {
+	key := fmt.Sprintf("key:%s:%s:%s", req.GetChanID(), req.GetAction(), req.GetToken())
+	if thingID, err := cc.store.Get(key); err == nil {
+		return &mainflux.ThingID{Value: thingID}, nil
+	}
+
+	id, err := cc.client.CanAccess(ctx, req, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	cc.store.Add(key, req.GetChanID(), id.GetValue())
+	return id, nil
+}
+
+func (cc *cacheClient) CanAccessByID(ctx context.Context, req *mainflux.AccessByIDReq, opts ...grpc.CallOption) (*empty.Empty, error) {
+	key := fmt.Sprintf("id:%s:%s:%s", req.GetChanID(), req.GetAction(), req.GetThingID())
+	if _, err := cc.store.Get(key); err == nil {
+		return &empty.Empty{}, nil
+	}
+
+	res, err := cc.client.CanAccessByID(ctx, req, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	cc.store.Add(key, req.GetChanID(), req.GetThingID())
+	return res, nil
+}
+
+func (cc *cacheClient) Identify(ctx context.Context, req *mainflux.Token, opts ...grpc.CallOption) (*mainflux.ThingID, error) {
+	return cc.client.Identify(ctx, req, opts...)
+}
+
+func (cc *cacheClient) Owner(ctx context.Context, req *mainflux.ThingID, opts ...grpc.CallOption) (*mainflux.UserID, error) {
+	return cc.client.Owner(ctx, req, opts...)
+}
+
+func (cc *cacheClient) Retention(ctx context.Context, req *mainflux.ChannelID, opts ...grpc.CallOption) (*mainflux.Retention, error) {
+	return cc.client.Retention(ctx, req, opts...)
+}
+
+func (cc *cacheClient) Region(ctx context.Context, req *mainflux.ChannelID, opts ...grpc.CallOption) (*mainflux.Region, error) {
+	return cc.client.Region(ctx, req, opts...)
+}
+
+func (cc *cacheClient) Alias(ctx context.Context, req *mainflux.ChannelID, opts ...grpc.CallOption) (*mainflux.ChannelAlias, error) {
+	return cc.client.Alias(ctx, req, opts...)
+}
+
+func (cc *cacheClient) ResolveAlias(ctx context.Context, req *mainflux.ChannelAlias, opts ...grpc.CallOption) (*mainflux.ChannelID, error) {
+	return cc.client.ResolveAlias(ctx, req, opts...)
+}
+
+func (cc *cacheClient) Ordering(ctx context.Context, req *mainflux.ChannelID, opts ...grpc.CallOption) (*mainflux.Ordering, error) {
+	return cc.client.Ordering(ctx, req, opts...)
+}
+
+func (cc *cacheClient) Transformer(ctx context.Context, req *mainflux.ChannelID, opts ...grpc.CallOption) (*mainflux.Transformer, error) {
+	return cc.client.Transformer(ctx, req, opts...)
+}
+
+func (cc *cacheClient) PayloadSchema(ctx context.Context, req *mainflux.PayloadSchemaReq, opts ...grpc.CallOption) (*mainflux.PayloadSchema, error) {
+	return cc.client.PayloadSchema(ctx, req, opts...)
+}
+
+func (cc *cacheClient) KeyDigest(ctx context.Context, req *mainflux.ThingID, opts ...grpc.CallOption) (*mainflux.KeyDigest, error) {
+	return cc.client.KeyDigest(ctx, req, opts...)
+}
+
+func (cc *cacheClient) Changes(ctx context.Context, req *mainflux.ChangesReq, opts ...grpc.CallOption) (mainflux.ThingsService_ChangesClient, error) {
+	return cc.client.Changes(ctx, req, opts...)
+}
+
+func (cc *cacheClient) Disconnect(chanID, thingID string) error {
+	return cc.store.Disconnect(chanID, thingID)
+}
+
+func (cc *cacheClient) RemoveThing(thingID string) error {
+	return cc.store.RemoveThing(thingID)
+}
+
+func (cc *cacheClient) RemoveChannel(chanID string) error {
+	return cc.store.RemoveChannel(chanID)
+}
@@ -0,0 +1,266 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package cache_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/pkg/events/consumer"
+	"github.com/mainflux/mainflux/things"
+	"github.com/mainflux/mainflux/things/api/auth/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	key     = "thing-key"
+	thingID = "thing-id"
+	chanID  = "chan-id"
+	ttl     = time.Minute
+)
+
+var _ mainflux.ThingsServiceClient = (*thingsClient)(nil)
+
+// thingsClient grants the access of the thing with the known key or ID to
+// the known channel, counting the access checks.
+type thingsClient struct {
+	calls int
+}
+
+func (tc *thingsClient) CanAccess(_ context.Context, req *mainflux.AccessReq, _ ...grpc.CallOption) (*mainflux.ThingID, error) {
+	tc.calls++
+	if req.GetToken() != key || req.GetChanID() != chanID {
+		return nil, status.Error(codes.PermissionDenied, "invalid credentials provided")
+	}
+	return &mainflux.ThingID{Value: thingID}, nil
+}
+
+func (tc *thingsClient) CanAccessByID(_ context.Context, req *mainflux.AccessByIDReq, _ ...grpc.CallOption) (*empty.Empty, error) {
+	tc.calls++
+	if req.GetThingID() != thingID || req.GetChanID() != chanID {
+		return nil, status.Error(codes.PermissionDenied, "invalid credentials provided")
+	}
+	return &empty.Empty{}, nil
+}
+
+func (tc *thingsClient) Identify(context.Context, *mainflux.Token, ...grpc.CallOption) (*mainflux.ThingID, error) {
+	panic("not implemented")
+}
+
+func (tc *thingsClient) Owner(context.Context, *mainflux.ThingID, ...grpc.CallOption) (*mainflux.UserID, error) {
+	panic("not implemented")
+}
+
+func (tc *thingsClient) Retention(context.Context, *mainflux.ChannelID, ...grpc.CallOption) (*mainflux.Retention, error) {
+	panic("not implemented")
+}
+
+func (tc *thingsClient) Region(context.Context, *mainflux.ChannelID, ...grpc.CallOption) (*mainflux.Region, error) {
+	panic("not implemented")
+}
+
+func (tc *thingsClient) Alias(context.Context, *mainflux.ChannelID, ...grpc.CallOption) (*mainflux.ChannelAlias, error) {
+	panic("not implemented")
+}
+
+func (tc *thingsClient) ResolveAlias(context.Context, *mainflux.ChannelAlias, ...grpc.CallOption) (*mainflux.ChannelID, error) {
+	panic("not implemented")
+}
+
+func (tc *thingsClient) Ordering(context.Context, *mainflux.ChannelID, ...grpc.CallOption) (*mainflux.Ordering, error) {
+	panic("not implemented")
+}
+
+func (tc *thingsClient) Transformer(context.Context, *mainflux.ChannelID, ...grpc.CallOption) (*mainflux.Transformer, error) {
+	panic("not implemented")
+}
+
+func (tc *thingsClient) PayloadSchema(context.Context, *mainflux.PayloadSchemaReq, ...grpc.CallOption) (*mainflux.PayloadSchema, error) {
+	panic("not implemented")
+}
+
+func (tc *thingsClient) KeyDigest(context.Context, *mainflux.ThingID, ...grpc.CallOption) (*mainflux.KeyDigest, error) {
+	panic("not implemented")
+}
+
+func (tc *thingsClient) Changes(context.Context, *mainflux.ChangesReq, ...grpc.CallOption) (mainflux.ThingsService_ChangesClient, error) {
+	panic("not implemented")
+}
+
+func canAccess(cli mainflux.ThingsServiceClient, chanID, key string) (string, codes.Code) {
+	id, err := cli.CanAccess(context.Background(), &mainflux.AccessReq{Token: key, ChanID: chanID, Action: things.Publish})
+	return id.GetValue(), status.Code(err)
+}
+
+func canAccessByID(cli mainflux.ThingsServiceClient, chanID, thingID string) codes.Code {
+	_, err := cli.CanAccessByID(context.Background(), &mainflux.AccessByIDReq{ThingID: thingID, ChanID: chanID, Action: things.Publish})
+	return status.Code(err)
+}
+
+func TestCanAccess(t *testing.T) {
+	svc := &thingsClient{}
+	cli := cache.NewClient(svc, cache.NewLRUStore(10, ttl))
+
+	cases := []struct {
+		desc  string
+		key   string
+		id    string
+		code  codes.Code
+		calls int
+	}{
+		{
+			desc:  "check access of the uncached thing",
+			key:   key,
+			id:    thingID,
+			code:  codes.OK,
+			calls: 1,
+		},
+		{
+			desc:  "check access of the cached thing",
+			key:   key,
+			id:    thingID,
+			code:  codes.OK,
+			calls: 1,
+		},
+		{
+			desc:  "check access with invalid key",
+			key:   "invalid",
+			id:    "",
+			code:  codes.PermissionDenied,
+			calls: 2,
+		},
+		{
+			desc:  "check denied access again",
+			key:   "invalid",
+			id:    "",
+			code:  codes.PermissionDenied,
+			calls: 3,
+		},
+	}
+
+	for _, tc := range cases {
+		id, code := canAccess(cli, chanID, tc.key)
+		assert.Equal(t, tc.code, code, fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.code, code))
+		assert.Equal(t, tc.id, id, fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.id, id))
+		assert.Equal(t, tc.calls, svc.calls, fmt.Sprintf("%s: expected %d calls got %d", tc.desc, tc.calls, svc.calls))
+	}
+}
+
+func TestCanAccessByID(t *testing.T) {
+	svc := &thingsClient{}
+	cli := cache.NewClient(svc, cache.NewLRUStore(10, ttl))
+
+	for i := 0; i < 3; i++ {
+		code := canAccessByID(cli, chanID, thingID)
+		assert.Equal(t, codes.OK, code, fmt.Sprintf("expected %s got %s", codes.OK, code))
+	}
+	assert.Equal(t, 1, svc.calls, fmt.Sprintf("expected 1 call got %d", svc.calls))
+}
+
+func TestExpiration(t *testing.T) {
+	svc := &thingsClient{}
+	cli := cache.NewClient(svc, cache.NewLRUStore(10, 10*time.Millisecond))
+
+	canAccess(cli, chanID, key)
+	time.Sleep(20 * time.Millisecond)
+	canAccess(cli, chanID, key)
+	assert.Equal(t, 2, svc.calls, fmt.Sprintf("expected 2 calls got %d", svc.calls))
+}
+
+func TestEviction(t *testing.T) {
+	svc := &thingsClient{}
+	cli := cache.NewClient(svc, cache.NewLRUStore(1, ttl))
+
+	canAccess(cli, chanID, key)
+	canAccessByID(cli, chanID, thingID)
+	canAccess(cli, chanID, key)
+	assert.Equal(t, 3, svc.calls, fmt.Sprintf("expected 3 calls got %d", svc.calls))
+}
+
+func TestHandler(t *testing.T) {
+	cases := []struct {
+		desc  string
+		event consumer.Event
+		calls int
+	}{
+		{
+			desc: "handle disconnect event",
+			event: consumer.Event{
+				Operation:  consumer.ThingDisconnect,
+				Connection: &consumer.Connection{ChannelID: chanID, ThingID: thingID},
+			},
+			calls: 4,
+		},
+		{
+			desc: "handle connect event",
+			event: consumer.Event{
+				Operation:  consumer.ThingConnect,
+				Connection: &consumer.Connection{ChannelID: chanID, ThingID: thingID},
+			},
+			calls: 4,
+		},
+		{
+			desc: "handle thing remove event",
+			event: consumer.Event{
+				Operation: consumer.ThingRemove,
+				Thing:     &consumer.Thing{ID: thingID},
+			},
+			calls: 4,
+		},
+		{
+			desc: "handle thing key update event",
+			event: consumer.Event{
+				Operation: consumer.ThingUpdateKey,
+				Thing:     &consumer.Thing{ID: thingID},
+			},
+			calls: 4,
+		},
+		{
+			desc: "handle channel remove event",
+			event: consumer.Event{
+				Operation: consumer.ChannelRemove,
+				Channel:   &consumer.Channel{ID: chanID},
+			},
+			calls: 4,
+		},
+		{
+			desc: "handle unrelated connect event",
+			event: consumer.Event{
+				Operation:  consumer.ThingConnect,
+				Connection: &consumer.Connection{ChannelID: "other", ThingID: thingID},
+			},
+			calls: 2,
+		},
+		{
+			desc: "handle channel create event",
+			event: consumer.Event{
+				Operation: consumer.ChannelCreate,
+				Channel:   &consumer.Channel{ID: chanID},
+			},
+			calls: 2,
+		},
+	}
+
+	for _, tc := range cases {
+		svc := &thingsClient{}
+		cli := cache.NewClient(svc, cache.NewLRUStore(10, ttl))
+		canAccess(cli, chanID, key)
+		canAccessByID(cli, chanID, thingID)
+
+		err := cache.Handler(cli)(context.Background(), tc.event)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+
+		canAccess(cli, chanID, key)
+		canAccessByID(cli, chanID, thingID)
+		assert.Equal(t, tc.calls, svc.calls, fmt.Sprintf("%s: expected %d calls got %d", tc.desc, tc.calls, svc.calls))
+	}
+}
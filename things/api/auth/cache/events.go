@@ -0,0 +1,29 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package cache
+
+import (
+	"context"
+
+	"github.com/mainflux/mainflux/pkg/events/consumer"
+)
+
+// Handler returns the things events handler which invalidates the accesses
+// affected by the event. Connecting the connected thing can revoke some of
+// its actions, so the connect events invalidate the accesses too. Events which
+// failed to invalidate the accesses are retried.
+func Handler(inv Invalidator) consumer.Handler {
+	return func(_ context.Context, e consumer.Event) error {
+		switch e.Operation {
+		case consumer.ThingConnect, consumer.ThingDisconnect:
+			return inv.Disconnect(e.Connection.ChannelID, e.Connection.ThingID)
+		case consumer.ThingUpdate, consumer.ThingUpdateKey, consumer.ThingRemove:
+			return inv.RemoveThing(e.Thing.ID)
+		case consumer.ChannelRemove:
+			return inv.RemoveChannel(e.Channel.ID)
+		}
+
+		return nil
+	}
+}
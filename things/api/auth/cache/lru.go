@@ -0,0 +1,123 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+var _ Store = (*lruStore)(nil)
+
+type entry struct {
+	key     string
+	chanID  string
+	thingID string
+	expires time.Time
+}
+
+type lruStore struct {
+	size    int
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+}
+
+// NewLRUStore returns the in-process store keeping up to size accesses for
+// the ttl. Each adapter instance keeps its own accesses, so it has to receive
+// all the invalidation events.
+func NewLRUStore(size int, ttl time.Duration) Store {
+	return &lruStore{
+		size:    size,
+		ttl:     ttl,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+func (ls *lruStore) Get(key string) (string, error) {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+
+	el, ok := ls.entries[key]
+	if !ok {
+		return "", ErrNotFound
+	}
+
+	e := el.Value.(entry)
+	if time.Now().After(e.expires) {
+		ls.remove(el)
+		return "", ErrNotFound
+	}
+
+	ls.lru.MoveToFront(el)
+	return e.thingID, nil
+}
+
+func (ls *lruStore) Add(key, chanID, thingID string) error {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+
+	e := entry{
+		key:     key,
+		chanID:  chanID,
+		thingID: thingID,
+		expires: time.Now().Add(ls.ttl),
+	}
+	if el, ok := ls.entries[key]; ok {
+		el.Value = e
+		ls.lru.MoveToFront(el)
+		return nil
+	}
+
+	ls.entries[key] = ls.lru.PushFront(e)
+	if ls.lru.Len() > ls.size {
+		ls.remove(ls.lru.Back())
+	}
+
+	return nil
+}
+
+func (ls *lruStore) Disconnect(chanID, thingID string) error {
+	ls.removeIf(func(e entry) bool {
+		return e.chanID == chanID && e.thingID == thingID
+	})
+	return nil
+}
+
+func (ls *lruStore) RemoveThing(thingID string) error {
+	ls.removeIf(func(e entry) bool {
+		return e.thingID == thingID
+	})
+	return nil
+}
+
+func (ls *lruStore) RemoveChannel(chanID string) error {
+	ls.removeIf(func(e entry) bool {
+		return e.chanID == chanID
+	})
+	return nil
+}
+
+// removeIf removes the entries matching the predicate. Invalidation walks the
+// whole cache, since the events are rare compared to the access checks.
+func (ls *lruStore) removeIf(match func(entry) bool) {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+
+	for el := ls.lru.Front(); el != nil; {
+		next := el.Next()
+		if match(el.Value.(entry)) {
+			ls.remove(el)
+		}
+		el = next
+	}
+}
+
+func (ls *lruStore) remove(el *list.Element) {
+	ls.lru.Remove(el)
+	delete(ls.entries, el.Value.(entry).key)
+}
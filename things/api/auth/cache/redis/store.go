@@ -0,0 +1,147 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package redis contains the access cache store shared by the adapter
+// instances using the same Redis database.
+package redis
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/mainflux/mainflux/things/api/auth/cache"
+)
+
+const (
+	accessPrefix = "access"
+	chanPrefix   = "access:channel"
+	thingPrefix  = "access:thing"
+)
+
+var _ cache.Store = (*store)(nil)
+
+type store struct {
+	client redis.UniversalClient
+	ttl    time.Duration
+}
+
+// NewStore returns the Redis store keeping the accesses for the ttl. Besides
+// the accesses, the store keeps the sets of the access keys per channel and
+// per thing, used to invalidate them. Since the store is shared, the events
+// need to be handled by a single adapter instance only.
+func NewStore(client redis.UniversalClient, ttl time.Duration) cache.Store {
+	return store{
+		client: client,
+		ttl:    ttl,
+	}
+}
+
+func (s store) Get(key string) (string, error) {
+	thingID, err := s.client.Get(accessKey(key)).Result()
+	if err == redis.Nil {
+		return "", cache.ErrNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return thingID, nil
+}
+
+func (s store) Add(key, chanID, thingID string) error {
+	k := accessKey(key)
+	_, err := s.client.TxPipelined(func(pipe redis.Pipeliner) error {
+		pipe.Set(k, thingID, s.ttl)
+		for _, set := range []string{chanKey(chanID), thingKey(thingID)} {
+			pipe.SAdd(set, k)
+			pipe.Expire(set, s.ttl)
+		}
+		return nil
+	})
+	return err
+}
+
+// Disconnect invalidates the accesses contained in both the channel and the
+// thing set. The sets are intersected and the accesses deleted one by one,
+// since the keys are spread across the Redis Cluster slots.
+func (s store) Disconnect(chanID, thingID string) error {
+	chanKeys, err := s.client.SMembers(chanKey(chanID)).Result()
+	if err != nil || len(chanKeys) == 0 {
+		return err
+	}
+
+	thingKeys, err := s.client.SMembers(thingKey(thingID)).Result()
+	if err != nil {
+		return err
+	}
+
+	inChan := make(map[string]bool, len(chanKeys))
+	for _, k := range chanKeys {
+		inChan[k] = true
+	}
+
+	var members []interface{}
+	for _, k := range thingKeys {
+		if inChan[k] {
+			members = append(members, k)
+		}
+	}
+	if len(members) == 0 {
+		return nil
+	}
+
+	_, err = s.client.TxPipelined(func(pipe redis.Pipeliner) error {
+		for _, k := range members {
+			pipe.Del(k.(string))
+		}
+		pipe.SRem(chanKey(chanID), members...)
+		pipe.SRem(thingKey(thingID), members...)
+		return nil
+	})
+	return err
+}
+
+func (s store) RemoveThing(thingID string) error {
+	return s.removeSet(thingKey(thingID))
+}
+
+func (s store) RemoveChannel(chanID string) error {
+	return s.removeSet(chanKey(chanID))
+}
+
+// removeSet removes the accesses of the set along with the set itself. The
+// removed keys remain in the other sets until they expire, which is harmless
+// since removing the missing keys is a no-op. Keys are deleted one by one, so
+// that no command spans multiple Redis Cluster slots.
+func (s store) removeSet(set string) error {
+	keys, err := s.client.SMembers(set).Result()
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.TxPipelined(func(pipe redis.Pipeliner) error {
+		for _, k := range append(keys, set) {
+			pipe.Del(k)
+		}
+		return nil
+	})
+	return err
+}
+
+// accessKey hashes the cache key, so that the thing keys it contains aren't
+// stored in plain text.
+func accessKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return fmt.Sprintf("%s:%s", accessPrefix, hex.EncodeToString(sum[:]))
+}
+
+func chanKey(chanID string) string {
+	return fmt.Sprintf("%s:%s", chanPrefix, chanID)
+}
+
+func thingKey(thingID string) string {
+	return fmt.Sprintf("%s:%s", thingPrefix, thingID)
+}
@@ -0,0 +1,122 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package redis_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/things/api/auth/cache"
+	"github.com/mainflux/mainflux/things/api/auth/cache/redis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	key     = "key:chan-id:publish:thing-key"
+	idKey   = "id:chan-id:publish:thing-id"
+	thingID = "thing-id"
+	chanID  = "chan-id"
+	ttl     = time.Minute
+)
+
+func TestGet(t *testing.T) {
+	redisClient.FlushAll()
+	store := redis.NewStore(redisClient, ttl)
+
+	err := store.Add(key, chanID, thingID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc string
+		key  string
+		id   string
+		err  error
+	}{
+		{
+			desc: "get cached access",
+			key:  key,
+			id:   thingID,
+			err:  nil,
+		},
+		{
+			desc: "get uncached access",
+			key:  idKey,
+			id:   "",
+			err:  cache.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		id, err := store.Get(tc.key)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.id, id, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.id, id))
+	}
+}
+
+func TestExpiration(t *testing.T) {
+	redisClient.FlushAll()
+	store := redis.NewStore(redisClient, 10*time.Millisecond)
+
+	err := store.Add(key, chanID, thingID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	time.Sleep(20 * time.Millisecond)
+	_, err = store.Get(key)
+	assert.Equal(t, cache.ErrNotFound, err, fmt.Sprintf("expected %s got %s\n", cache.ErrNotFound, err))
+}
+
+func TestInvalidate(t *testing.T) {
+	cases := []struct {
+		desc       string
+		invalidate func(cache.Store) error
+		cached     []string
+	}{
+		{
+			desc:       "disconnect thing",
+			invalidate: func(s cache.Store) error { return s.Disconnect(chanID, thingID) },
+			cached:     []string{"other-channel", "other-thing"},
+		},
+		{
+			desc:       "disconnect unrelated thing",
+			invalidate: func(s cache.Store) error { return s.Disconnect("other-chan-id", "other-thing-id") },
+			cached:     []string{key, idKey, "other-channel", "other-thing"},
+		},
+		{
+			desc:       "remove thing",
+			invalidate: func(s cache.Store) error { return s.RemoveThing(thingID) },
+			cached:     []string{"other-thing"},
+		},
+		{
+			desc:       "remove channel",
+			invalidate: func(s cache.Store) error { return s.RemoveChannel(chanID) },
+			cached:     []string{"other-channel"},
+		},
+	}
+
+	for _, tc := range cases {
+		redisClient.FlushAll()
+		store := redis.NewStore(redisClient, ttl)
+
+		// The other-channel and other-thing keys cache the accesses of
+		// the thing to the other channel and of the other thing to the
+		// channel respectively.
+		require.Nil(t, store.Add(key, chanID, thingID), fmt.Sprintf("%s: unexpected error", tc.desc))
+		require.Nil(t, store.Add(idKey, chanID, thingID), fmt.Sprintf("%s: unexpected error", tc.desc))
+		require.Nil(t, store.Add("other-channel", "other-chan-id", thingID), fmt.Sprintf("%s: unexpected error", tc.desc))
+		require.Nil(t, store.Add("other-thing", chanID, "other-thing-id"), fmt.Sprintf("%s: unexpected error", tc.desc))
+
+		err := tc.invalidate(store)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+
+		cached := []string{}
+		for _, k := range []string{key, idKey, "other-channel", "other-thing"} {
+			if _, err := store.Get(k); err == nil {
+				cached = append(cached, k)
+			}
+		}
+		assert.Equal(t, tc.cached, cached, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.cached, cached))
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package callback provides a things service client decorator that consults
+// an external policy engine over HTTP before granting channel access.
+package callback
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/mainflux/mainflux"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const contentType = "application/json"
+
+var _ mainflux.ThingsServiceClient = (*callbackClient)(nil)
+
+// Request represents the document sent to the policy engine. The engine
+// grants access by responding with any 2xx status code.
+type Request struct {
+	ThingID string `json:"thing_id"`
+	ChanID  string `json:"chan_id"`
+	Action  string `json:"action"`
+}
+
+type callbackClient struct {
+	client mainflux.ThingsServiceClient
+	url    string
+	http   *http.Client
+}
+
+// NewClient wraps the things service client so that every access granted by
+// the things service is additionally authorized by the policy engine
+// listening on the given URL. If the URL is empty, client is returned as is.
+func NewClient(client mainflux.ThingsServiceClient, url string, timeout time.Duration) mainflux.ThingsServiceClient {
+	if url == "" {
+		return client
+	}
+
+	return &callbackClient{
+		client: client,
+		url:    url,
+		http:   &http.Client{Timeout: timeout},
+	}
+}
+
+func (cc callbackClient) CanAccess(ctx context.Context, req *mainflux.AccessReq, opts ...grpc.CallOption) (*mainflux.ThingID, error) {
+	id, err := cc.client.CanAccess(ctx, req, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cc.authorize(ctx, id.GetValue(), req.GetChanID(), req.GetAction()); err != nil {
+		return nil, err
+	}
+
+	return id, nil
+}
+
+func (cc callbackClient) CanAccessByID(ctx context.Context, req *mainflux.AccessByIDReq, opts ...grpc.CallOption) (*empty.Empty, error) {
+	res, err := cc.client.CanAccessByID(ctx, req, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cc.authorize(ctx, req.GetThingID(), req.GetChanID(), req.GetAction()); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (cc callbackClient) Identify(ctx context.Context, req *mainflux.Token, opts ...grpc.CallOption) (*mainflux.ThingID, error) {
+	return cc.client.Identify(ctx, req, opts...)
+}
+
+func (cc callbackClient) Owner(ctx context.Context, req *mainflux.ThingID, opts ...grpc.CallOption) (*mainflux.UserID, error) {
+	return cc.client.Owner(ctx, req, opts...)
+}
+
+func (cc callbackClient) Retention(ctx context.Context, req *mainflux.ChannelID, opts ...grpc.CallOption) (*mainflux.Retention, error) {
+	return cc.client.Retention(ctx, req, opts...)
+}
+
+func (cc callbackClient) Region(ctx context.Context, req *mainflux.ChannelID, opts ...grpc.CallOption) (*mainflux.Region, error) {
+	return cc.client.Region(ctx, req, opts...)
+}
+
+func (cc callbackClient) Alias(ctx context.Context, req *mainflux.ChannelID, opts ...grpc.CallOption) (*mainflux.ChannelAlias, error) {
+	return cc.client.Alias(ctx, req, opts...)
+}
+
+func (cc callbackClient) ResolveAlias(ctx context.Context, req *mainflux.ChannelAlias, opts ...grpc.CallOption) (*mainflux.ChannelID, error) {
+	return cc.client.ResolveAlias(ctx, req, opts...)
+}
+
+func (cc callbackClient) Ordering(ctx context.Context, req *mainflux.ChannelID, opts ...grpc.CallOption) (*mainflux.Ordering, error) {
+	return cc.client.Ordering(ctx, req, opts...)
+}
+
+func (cc callbackClient) Transformer(ctx context.Context, req *mainflux.ChannelID, opts ...grpc.CallOption) (*mainflux.Transformer, error) {
+	return cc.client.Transformer(ctx, req, opts...)
+}
+
+func (cc callbackClient) PayloadSchema(ctx context.Context, req *mainflux.PayloadSchemaReq, opts ...grpc.CallOption) (*mainflux.PayloadSchema, error) {
+	return cc.client.PayloadSchema(ctx, req, opts...)
+}
+
+func (cc callbackClient) KeyDigest(ctx context.Context, req *mainflux.ThingID, opts ...grpc.CallOption) (*mainflux.KeyDigest, error) {
+	return cc.client.KeyDigest(ctx, req, opts...)
+}
+
+func (cc callbackClient) Changes(ctx context.Context, req *mainflux.ChangesReq, opts ...grpc.CallOption) (mainflux.ThingsService_ChangesClient, error) {
+	return cc.client.Changes(ctx, req, opts...)
+}
+
+func (cc callbackClient) authorize(ctx context.Context, thingID, chanID, action string) error {
+	data, err := json.Marshal(Request{
+		ThingID: thingID,
+		ChanID:  chanID,
+		Action:  action,
+	})
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	req, err := http.NewRequest(http.MethodPost, cc.url, bytes.NewReader(data))
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	res, err := cc.http.Do(req.WithContext(ctx))
+	if err != nil {
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	defer res.Body.Close()
+
+	switch {
+	case res.StatusCode >= http.StatusOK && res.StatusCode < http.StatusMultipleChoices:
+		return nil
+	case res.StatusCode >= http.StatusInternalServerError:
+		return status.Error(codes.Unavailable, "policy engine unavailable")
+	default:
+		return status.Error(codes.PermissionDenied, "access denied by policy engine")
+	}
+}
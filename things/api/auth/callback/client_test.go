@@ -0,0 +1,212 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package callback_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/things"
+	"github.com/mainflux/mainflux/things/api/auth/callback"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	key     = "thing-key"
+	thingID = "thing-id"
+	chanID  = "chan-id"
+)
+
+var _ mainflux.ThingsServiceClient = (*thingsClient)(nil)
+
+type thingsClient struct{}
+
+func (tc thingsClient) CanAccess(_ context.Context, req *mainflux.AccessReq, _ ...grpc.CallOption) (*mainflux.ThingID, error) {
+	if req.GetToken() != key {
+		return nil, status.Error(codes.PermissionDenied, "invalid credentials provided")
+	}
+	return &mainflux.ThingID{Value: thingID}, nil
+}
+
+func (tc thingsClient) CanAccessByID(_ context.Context, req *mainflux.AccessByIDReq, _ ...grpc.CallOption) (*empty.Empty, error) {
+	if req.GetThingID() != thingID {
+		return nil, status.Error(codes.PermissionDenied, "invalid credentials provided")
+	}
+	return &empty.Empty{}, nil
+}
+
+func (tc thingsClient) Identify(_ context.Context, req *mainflux.Token, _ ...grpc.CallOption) (*mainflux.ThingID, error) {
+	return &mainflux.ThingID{Value: thingID}, nil
+}
+
+func (tc thingsClient) Owner(context.Context, *mainflux.ThingID, ...grpc.CallOption) (*mainflux.UserID, error) {
+	panic("not implemented")
+}
+
+func (tc thingsClient) Retention(context.Context, *mainflux.ChannelID, ...grpc.CallOption) (*mainflux.Retention, error) {
+	panic("not implemented")
+}
+
+func (tc thingsClient) Region(context.Context, *mainflux.ChannelID, ...grpc.CallOption) (*mainflux.Region, error) {
+	panic("not implemented")
+}
+
+func (tc thingsClient) Alias(context.Context, *mainflux.ChannelID, ...grpc.CallOption) (*mainflux.ChannelAlias, error) {
+	panic("not implemented")
+}
+
+func (tc thingsClient) ResolveAlias(context.Context, *mainflux.ChannelAlias, ...grpc.CallOption) (*mainflux.ChannelID, error) {
+	panic("not implemented")
+}
+
+func (tc thingsClient) Ordering(context.Context, *mainflux.ChannelID, ...grpc.CallOption) (*mainflux.Ordering, error) {
+	panic("not implemented")
+}
+
+func (tc thingsClient) Transformer(context.Context, *mainflux.ChannelID, ...grpc.CallOption) (*mainflux.Transformer, error) {
+	panic("not implemented")
+}
+
+func (tc thingsClient) PayloadSchema(context.Context, *mainflux.PayloadSchemaReq, ...grpc.CallOption) (*mainflux.PayloadSchema, error) {
+	panic("not implemented")
+}
+
+func (tc thingsClient) KeyDigest(context.Context, *mainflux.ThingID, ...grpc.CallOption) (*mainflux.KeyDigest, error) {
+	panic("not implemented")
+}
+
+func (tc thingsClient) Changes(context.Context, *mainflux.ChangesReq, ...grpc.CallOption) (mainflux.ThingsService_ChangesClient, error) {
+	panic("not implemented")
+}
+
+// newPolicyServer returns policy engine that allows publishing only and
+// fails for subscriptions.
+func newPolicyServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req callback.Request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		switch req.Action {
+		case things.Publish:
+			w.WriteHeader(http.StatusOK)
+		case things.Subscribe:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+}
+
+func TestCanAccess(t *testing.T) {
+	ts := newPolicyServer()
+	defer ts.Close()
+
+	cli := callback.NewClient(thingsClient{}, ts.URL, time.Second)
+
+	cases := []struct {
+		desc   string
+		key    string
+		action string
+		id     string
+		code   codes.Code
+	}{
+		{
+			desc:   "check access allowed by policy engine",
+			key:    key,
+			action: things.Publish,
+			id:     thingID,
+			code:   codes.OK,
+		},
+		{
+			desc:   "check access denied by policy engine",
+			key:    key,
+			action: things.ReadHistory,
+			id:     "",
+			code:   codes.PermissionDenied,
+		},
+		{
+			desc:   "check access with unavailable policy engine",
+			key:    key,
+			action: things.Subscribe,
+			id:     "",
+			code:   codes.Unavailable,
+		},
+		{
+			desc:   "check access denied by things service",
+			key:    "wrong",
+			action: things.Publish,
+			id:     "",
+			code:   codes.PermissionDenied,
+		},
+	}
+
+	for _, tc := range cases {
+		id, err := cli.CanAccess(context.Background(), &mainflux.AccessReq{Token: tc.key, ChanID: chanID, Action: tc.action})
+		e, _ := status.FromError(err)
+		assert.Equal(t, tc.id, id.GetValue(), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.id, id.GetValue()))
+		assert.Equal(t, tc.code, e.Code(), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.code, e.Code()))
+	}
+}
+
+func TestCanAccessByID(t *testing.T) {
+	ts := newPolicyServer()
+	defer ts.Close()
+
+	cli := callback.NewClient(thingsClient{}, ts.URL, time.Second)
+
+	cases := []struct {
+		desc    string
+		thingID string
+		action  string
+		code    codes.Code
+	}{
+		{
+			desc:    "check access allowed by policy engine",
+			thingID: thingID,
+			action:  things.Publish,
+			code:    codes.OK,
+		},
+		{
+			desc:    "check access denied by policy engine",
+			thingID: thingID,
+			action:  things.ReadHistory,
+			code:    codes.PermissionDenied,
+		},
+		{
+			desc:    "check access denied by things service",
+			thingID: "wrong",
+			action:  things.Publish,
+			code:    codes.PermissionDenied,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := cli.CanAccessByID(context.Background(), &mainflux.AccessByIDReq{ThingID: tc.thingID, ChanID: chanID, Action: tc.action})
+		e, _ := status.FromError(err)
+		assert.Equal(t, tc.code, e.Code(), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.code, e.Code()))
+	}
+}
+
+func TestUnreachablePolicyEngine(t *testing.T) {
+	ts := newPolicyServer()
+	ts.Close()
+
+	cli := callback.NewClient(thingsClient{}, ts.URL, time.Second)
+	_, err := cli.CanAccess(context.Background(), &mainflux.AccessReq{Token: key, ChanID: chanID, Action: things.Publish})
+	e, _ := status.FromError(err)
+	assert.Equal(t, codes.Unavailable, e.Code(), fmt.Sprintf("unreachable policy engine: expected %s got %s\n", codes.Unavailable, e.Code()))
+}
@@ -11,6 +11,7 @@ import (
 	kitgrpc "github.com/go-kit/kit/transport/grpc"
 	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/things"
 	opentracing "github.com/opentracing/opentracing-go"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
@@ -19,10 +20,20 @@ import (
 var _ mainflux.ThingsServiceClient = (*grpcClient)(nil)
 
 type grpcClient struct {
+	conn          *grpc.ClientConn
 	timeout       time.Duration
 	canAccess     endpoint.Endpoint
 	canAccessByID endpoint.Endpoint
 	identify      endpoint.Endpoint
+	owner         endpoint.Endpoint
+	retention     endpoint.Endpoint
+	region        endpoint.Endpoint
+	alias         endpoint.Endpoint
+	resolveAlias  endpoint.Endpoint
+	ordering      endpoint.Endpoint
+	transformer   endpoint.Endpoint
+	payloadSchema endpoint.Endpoint
+	keyDigest     endpoint.Endpoint
 }
 
 // NewClient returns new gRPC client instance.
@@ -30,6 +41,7 @@ func NewClient(conn *grpc.ClientConn, tracer opentracing.Tracer, timeout time.Du
 	svcName := "mainflux.ThingsService"
 
 	return &grpcClient{
+		conn:    conn,
 		timeout: timeout,
 		canAccess: kitot.TraceClient(tracer, "can_access")(kitgrpc.NewClient(
 			conn,
@@ -55,6 +67,78 @@ func NewClient(conn *grpc.ClientConn, tracer opentracing.Tracer, timeout time.Du
 			decodeIdentityResponse,
 			mainflux.ThingID{},
 		).Endpoint()),
+		owner: kitot.TraceClient(tracer, "owner")(kitgrpc.NewClient(
+			conn,
+			svcName,
+			"Owner",
+			encodeOwnerRequest,
+			decodeOwnerResponse,
+			mainflux.UserID{},
+		).Endpoint()),
+		retention: kitot.TraceClient(tracer, "retention")(kitgrpc.NewClient(
+			conn,
+			svcName,
+			"Retention",
+			encodeRetentionRequest,
+			decodeRetentionResponse,
+			mainflux.Retention{},
+		).Endpoint()),
+		region: kitot.TraceClient(tracer, "region")(kitgrpc.NewClient(
+			conn,
+			svcName,
+			"Region",
+			encodeRegionRequest,
+			decodeRegionResponse,
+			mainflux.Region{},
+		).Endpoint()),
+		alias: kitot.TraceClient(tracer, "alias")(kitgrpc.NewClient(
+			conn,
+			svcName,
+			"Alias",
+			encodeAliasRequest,
+			decodeAliasResponse,
+			mainflux.ChannelAlias{},
+		).Endpoint()),
+		resolveAlias: kitot.TraceClient(tracer, "resolve_alias")(kitgrpc.NewClient(
+			conn,
+			svcName,
+			"ResolveAlias",
+			encodeResolveAliasRequest,
+			decodeResolveAliasResponse,
+			mainflux.ChannelID{},
+		).Endpoint()),
+		ordering: kitot.TraceClient(tracer, "ordering")(kitgrpc.NewClient(
+			conn,
+			svcName,
+			"Ordering",
+			encodeOrderingRequest,
+			decodeOrderingResponse,
+			mainflux.Ordering{},
+		).Endpoint()),
+		transformer: kitot.TraceClient(tracer, "transformer")(kitgrpc.NewClient(
+			conn,
+			svcName,
+			"Transformer",
+			encodeTransformerRequest,
+			decodeTransformerResponse,
+			mainflux.Transformer{},
+		).Endpoint()),
+		payloadSchema: kitot.TraceClient(tracer, "payload_schema")(kitgrpc.NewClient(
+			conn,
+			svcName,
+			"PayloadSchema",
+			encodePayloadSchemaRequest,
+			decodePayloadSchemaResponse,
+			mainflux.PayloadSchema{},
+		).Endpoint()),
+		keyDigest: kitot.TraceClient(tracer, "key_digest")(kitgrpc.NewClient(
+			conn,
+			svcName,
+			"KeyDigest",
+			encodeKeyDigestRequest,
+			decodeKeyDigestResponse,
+			mainflux.KeyDigest{},
+		).Endpoint()),
 	}
 }
 
@@ -65,6 +149,7 @@ func (client grpcClient) CanAccess(ctx context.Context, req *mainflux.AccessReq,
 	ar := accessReq{
 		thingKey: req.GetToken(),
 		chanID:   req.GetChanID(),
+		action:   req.GetAction(),
 	}
 	res, err := client.canAccess(ctx, ar)
 	if err != nil {
@@ -76,7 +161,7 @@ func (client grpcClient) CanAccess(ctx context.Context, req *mainflux.AccessReq,
 }
 
 func (client grpcClient) CanAccessByID(ctx context.Context, req *mainflux.AccessByIDReq, _ ...grpc.CallOption) (*empty.Empty, error) {
-	ar := accessByIDReq{thingID: req.GetThingID(), chanID: req.GetChanID()}
+	ar := accessByIDReq{thingID: req.GetThingID(), chanID: req.GetChanID(), action: req.GetAction()}
 	res, err := client.canAccessByID(ctx, ar)
 	if err != nil {
 		return nil, err
@@ -99,14 +184,142 @@ func (client grpcClient) Identify(ctx context.Context, req *mainflux.Token, _ ..
 	return &mainflux.ThingID{Value: ir.id}, ir.err
 }
 
+func (client grpcClient) Owner(ctx context.Context, req *mainflux.ThingID, _ ...grpc.CallOption) (*mainflux.UserID, error) {
+	ctx, cancel := context.WithTimeout(ctx, client.timeout)
+	defer cancel()
+
+	res, err := client.owner(ctx, ownerReq{thingID: req.GetValue()})
+	if err != nil {
+		return nil, err
+	}
+
+	or := res.(ownerRes)
+	return &mainflux.UserID{Value: or.owner}, or.err
+}
+
+func (client grpcClient) Retention(ctx context.Context, req *mainflux.ChannelID, _ ...grpc.CallOption) (*mainflux.Retention, error) {
+	ctx, cancel := context.WithTimeout(ctx, client.timeout)
+	defer cancel()
+
+	res, err := client.retention(ctx, retentionReq{chanID: req.GetValue()})
+	if err != nil {
+		return nil, err
+	}
+
+	rr := res.(retentionRes)
+	return &mainflux.Retention{Period: rr.period, Messages: rr.messages}, rr.err
+}
+
+func (client grpcClient) Region(ctx context.Context, req *mainflux.ChannelID, _ ...grpc.CallOption) (*mainflux.Region, error) {
+	ctx, cancel := context.WithTimeout(ctx, client.timeout)
+	defer cancel()
+
+	res, err := client.region(ctx, regionReq{chanID: req.GetValue()})
+	if err != nil {
+		return nil, err
+	}
+
+	rr := res.(regionRes)
+	return &mainflux.Region{Value: rr.region}, rr.err
+}
+
+func (client grpcClient) Alias(ctx context.Context, req *mainflux.ChannelID, _ ...grpc.CallOption) (*mainflux.ChannelAlias, error) {
+	ctx, cancel := context.WithTimeout(ctx, client.timeout)
+	defer cancel()
+
+	res, err := client.alias(ctx, aliasReq{chanID: req.GetValue()})
+	if err != nil {
+		return nil, err
+	}
+
+	ar := res.(aliasRes)
+	return &mainflux.ChannelAlias{Value: ar.alias}, ar.err
+}
+
+func (client grpcClient) ResolveAlias(ctx context.Context, req *mainflux.ChannelAlias, _ ...grpc.CallOption) (*mainflux.ChannelID, error) {
+	ctx, cancel := context.WithTimeout(ctx, client.timeout)
+	defer cancel()
+
+	res, err := client.resolveAlias(ctx, resolveAliasReq{alias: req.GetValue()})
+	if err != nil {
+		return nil, err
+	}
+
+	rr := res.(resolveAliasRes)
+	return &mainflux.ChannelID{Value: rr.id}, rr.err
+}
+
+func (client grpcClient) Ordering(ctx context.Context, req *mainflux.ChannelID, _ ...grpc.CallOption) (*mainflux.Ordering, error) {
+	ctx, cancel := context.WithTimeout(ctx, client.timeout)
+	defer cancel()
+
+	res, err := client.ordering(ctx, orderingReq{chanID: req.GetValue()})
+	if err != nil {
+		return nil, err
+	}
+
+	or := res.(orderingRes)
+	return &mainflux.Ordering{Ordered: or.ordered}, or.err
+}
+
+func (client grpcClient) Transformer(ctx context.Context, req *mainflux.ChannelID, _ ...grpc.CallOption) (*mainflux.Transformer, error) {
+	ctx, cancel := context.WithTimeout(ctx, client.timeout)
+	defer cancel()
+
+	res, err := client.transformer(ctx, transformerReq{chanID: req.GetValue()})
+	if err != nil {
+		return nil, err
+	}
+
+	tr := res.(transformerRes)
+	return &mainflux.Transformer{
+		Format:  tr.transformer.Format,
+		Schema:  tr.transformer.Schema,
+		Message: tr.transformer.Message,
+		Time:    tr.transformer.Time,
+	}, tr.err
+}
+
+func (client grpcClient) PayloadSchema(ctx context.Context, req *mainflux.PayloadSchemaReq, _ ...grpc.CallOption) (*mainflux.PayloadSchema, error) {
+	ctx, cancel := context.WithTimeout(ctx, client.timeout)
+	defer cancel()
+
+	res, err := client.payloadSchema(ctx, payloadSchemaReq{chanID: req.GetChanID(), subtopic: req.GetSubtopic()})
+	if err != nil {
+		return nil, err
+	}
+
+	ps := res.(payloadSchemaRes)
+	return &mainflux.PayloadSchema{Definition: ps.definition}, ps.err
+}
+
+func (client grpcClient) KeyDigest(ctx context.Context, req *mainflux.ThingID, _ ...grpc.CallOption) (*mainflux.KeyDigest, error) {
+	ctx, cancel := context.WithTimeout(ctx, client.timeout)
+	defer cancel()
+
+	res, err := client.keyDigest(ctx, keyDigestReq{thingID: req.GetValue()})
+	if err != nil {
+		return nil, err
+	}
+
+	kr := res.(keyDigestRes)
+	return &mainflux.KeyDigest{Value: kr.digest}, kr.err
+}
+
+// Changes opens the changes stream directly, since the stream outlives the
+// request timeout and isn't supported by the go-kit transport.
+func (client grpcClient) Changes(ctx context.Context, req *mainflux.ChangesReq, opts ...grpc.CallOption) (mainflux.ThingsService_ChangesClient, error) {
+	return mainflux.NewThingsServiceClient(client.conn).Changes(ctx, req, opts...)
+}
+
 func encodeCanAccessRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
 	req := grpcReq.(accessReq)
-	return &mainflux.AccessReq{Token: req.thingKey, ChanID: req.chanID}, nil
+	return &mainflux.AccessReq{Token: req.thingKey, ChanID: req.chanID, Action: req.action}, nil
 }
 
 func encodeCanAccessByIDRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
 	req := grpcReq.(accessByIDReq)
-	return &mainflux.AccessByIDReq{ThingID: req.thingID, ChanID: req.chanID}, nil
+	return &mainflux.AccessByIDReq{ThingID: req.thingID, ChanID: req.chanID, Action: req.action}, nil
 }
 
 func encodeIdentifyRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
@@ -114,6 +327,102 @@ func encodeIdentifyRequest(_ context.Context, grpcReq interface{}) (interface{},
 	return &mainflux.Token{Value: req.key}, nil
 }
 
+func encodeOwnerRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(ownerReq)
+	return &mainflux.ThingID{Value: req.thingID}, nil
+}
+
+func decodeOwnerResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
+	res := grpcRes.(*mainflux.UserID)
+	return ownerRes{owner: res.GetValue(), err: nil}, nil
+}
+
+func encodeRetentionRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(retentionReq)
+	return &mainflux.ChannelID{Value: req.chanID}, nil
+}
+
+func decodeRetentionResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
+	res := grpcRes.(*mainflux.Retention)
+	return retentionRes{period: res.GetPeriod(), messages: res.GetMessages(), err: nil}, nil
+}
+
+func encodeRegionRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(regionReq)
+	return &mainflux.ChannelID{Value: req.chanID}, nil
+}
+
+func decodeRegionResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
+	res := grpcRes.(*mainflux.Region)
+	return regionRes{region: res.GetValue(), err: nil}, nil
+}
+
+func encodeAliasRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(aliasReq)
+	return &mainflux.ChannelID{Value: req.chanID}, nil
+}
+
+func decodeAliasResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
+	res := grpcRes.(*mainflux.ChannelAlias)
+	return aliasRes{alias: res.GetValue(), err: nil}, nil
+}
+
+func encodeResolveAliasRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(resolveAliasReq)
+	return &mainflux.ChannelAlias{Value: req.alias}, nil
+}
+
+func decodeResolveAliasResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
+	res := grpcRes.(*mainflux.ChannelID)
+	return resolveAliasRes{id: res.GetValue(), err: nil}, nil
+}
+
+func encodeOrderingRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(orderingReq)
+	return &mainflux.ChannelID{Value: req.chanID}, nil
+}
+
+func decodeOrderingResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
+	res := grpcRes.(*mainflux.Ordering)
+	return orderingRes{ordered: res.GetOrdered(), err: nil}, nil
+}
+
+func encodeTransformerRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(transformerReq)
+	return &mainflux.ChannelID{Value: req.chanID}, nil
+}
+
+func decodeTransformerResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
+	res := grpcRes.(*mainflux.Transformer)
+	tr := things.Transformer{
+		Format:  res.GetFormat(),
+		Schema:  res.GetSchema(),
+		Message: res.GetMessage(),
+		Time:    res.GetTime(),
+	}
+	return transformerRes{transformer: tr, err: nil}, nil
+}
+
+func encodePayloadSchemaRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(payloadSchemaReq)
+	return &mainflux.PayloadSchemaReq{ChanID: req.chanID, Subtopic: req.subtopic}, nil
+}
+
+func decodePayloadSchemaResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
+	res := grpcRes.(*mainflux.PayloadSchema)
+	return payloadSchemaRes{definition: res.GetDefinition(), err: nil}, nil
+}
+
+func encodeKeyDigestRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(keyDigestReq)
+	return &mainflux.ThingID{Value: req.thingID}, nil
+}
+
+func decodeKeyDigestResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
+	res := grpcRes.(*mainflux.KeyDigest)
+	return keyDigestRes{digest: res.GetValue(), err: nil}, nil
+}
+
 func decodeIdentityResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
 	res := grpcRes.(*mainflux.ThingID)
 	return identityRes{id: res.GetValue(), err: nil}, nil
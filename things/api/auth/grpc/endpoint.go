@@ -4,6 +4,9 @@
 package grpc
 
 import (
+	"encoding/json"
+	"time"
+
 	"github.com/go-kit/kit/endpoint"
 	"github.com/mainflux/mainflux/things"
 	context "golang.org/x/net/context"
@@ -16,7 +19,7 @@ func canAccessEndpoint(svc things.Service) endpoint.Endpoint {
 			return nil, err
 		}
 
-		id, err := svc.CanAccess(ctx, req.chanID, req.thingKey)
+		id, err := svc.CanAccess(ctx, req.chanID, req.thingKey, req.action)
 		if err != nil {
 			return identityRes{err: err}, err
 		}
@@ -31,11 +34,182 @@ func canAccessByIDEndpoint(svc things.Service) endpoint.Endpoint {
 			return nil, err
 		}
 
-		err := svc.CanAccessByID(ctx, req.chanID, req.thingID)
+		err := svc.CanAccessByID(ctx, req.chanID, req.thingID, req.action)
 		return emptyRes{err: err}, err
 	}
 }
 
+func ownerEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(ownerReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		owner, err := svc.Owner(ctx, req.thingID)
+		if err != nil {
+			return ownerRes{err: err}, err
+		}
+		return ownerRes{owner: owner, err: nil}, nil
+	}
+}
+
+func retentionEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(retentionReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		r, err := svc.Retention(ctx, req.chanID)
+		if err != nil {
+			return retentionRes{err: err}, err
+		}
+
+		res := retentionRes{
+			period:   int64(r.Period / time.Second),
+			messages: r.Messages,
+		}
+		return res, nil
+	}
+}
+
+func regionEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(regionReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		region, err := svc.Region(ctx, req.chanID)
+		if err != nil {
+			return regionRes{err: err}, err
+		}
+		return regionRes{region: region, err: nil}, nil
+	}
+}
+
+func aliasEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(aliasReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		alias, err := svc.Alias(ctx, req.chanID)
+		if err != nil {
+			return aliasRes{err: err}, err
+		}
+		return aliasRes{alias: alias, err: nil}, nil
+	}
+}
+
+func resolveAliasEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(resolveAliasReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		id, err := svc.ResolveAlias(ctx, req.alias)
+		if err != nil {
+			return resolveAliasRes{err: err}, err
+		}
+		return resolveAliasRes{id: id, err: nil}, nil
+	}
+}
+
+func orderingEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(orderingReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		ordered, err := svc.Ordering(ctx, req.chanID)
+		if err != nil {
+			return orderingRes{err: err}, err
+		}
+		return orderingRes{ordered: ordered, err: nil}, nil
+	}
+}
+
+func transformerEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(transformerReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		tr, err := svc.Transformer(ctx, req.chanID)
+		if err != nil {
+			return transformerRes{err: err}, err
+		}
+		return transformerRes{transformer: tr, err: nil}, nil
+	}
+}
+
+// payloadSchemaEndpoint responds with the empty definition if the channel
+// subtopic has no payload schema, so that the clients can tell the messages
+// that aren't validated from the failed lookups.
+func payloadSchemaEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(payloadSchemaReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		schema, err := svc.PayloadSchema(ctx, req.chanID, req.subtopic)
+		if err == things.ErrNotFound {
+			return payloadSchemaRes{}, nil
+		}
+		if err != nil {
+			return payloadSchemaRes{err: err}, err
+		}
+
+		def, err := json.Marshal(schema.Definition)
+		if err != nil {
+			return payloadSchemaRes{err: err}, err
+		}
+		return payloadSchemaRes{definition: string(def), err: nil}, nil
+	}
+}
+
+func keyDigestEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(keyDigestReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		digest, err := svc.KeyDigest(ctx, req.thingID)
+		if err != nil {
+			return keyDigestRes{err: err}, err
+		}
+		return keyDigestRes{digest: digest, err: nil}, nil
+	}
+}
+
+func changesEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(changesReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		page, err := svc.ListChanges(ctx, req.token, req.since, req.limit)
+		if err != nil {
+			return changesRes{err: err}, err
+		}
+
+		res := changesRes{
+			changes: page.Changes,
+			next:    page.Next,
+		}
+		return res, nil
+	}
+}
+
 func identifyEndpoint(svc things.Service) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(identifyReq)
@@ -5,6 +5,8 @@ package grpc_test
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"testing"
 	"time"
@@ -15,6 +17,7 @@ import (
 	"github.com/mainflux/mainflux/things"
 	grpcapi "github.com/mainflux/mainflux/things/api/auth/grpc"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -31,7 +34,7 @@ func TestCanAccess(t *testing.T) {
 	oth, _ := svc.AddThing(context.Background(), token, thing)
 	cth, _ := svc.AddThing(context.Background(), token, thing)
 	sch, _ := svc.CreateChannel(context.Background(), token, channel)
-	svc.Connect(context.Background(), token, sch.ID, cth.ID)
+	svc.Connect(context.Background(), token, sch.ID, cth.ID, nil)
 
 	usersAddr := fmt.Sprintf("localhost:%d", port)
 	conn, _ := grpc.Dial(usersAddr, grpc.WithInsecure())
@@ -72,7 +75,7 @@ func TestCanAccess(t *testing.T) {
 	}
 
 	for desc, tc := range cases {
-		id, err := cli.CanAccess(ctx, &mainflux.AccessReq{Token: tc.key, ChanID: tc.chanID})
+		id, err := cli.CanAccess(ctx, &mainflux.AccessReq{Token: tc.key, ChanID: tc.chanID, Action: things.Publish})
 		e, ok := status.FromError(err)
 		assert.True(t, ok, "OK expected to be true")
 		assert.Equal(t, tc.thingID, id.GetValue(), fmt.Sprintf("%s: expected %s got %s", desc, tc.thingID, id.GetValue()))
@@ -84,7 +87,7 @@ func TestCanAccessByID(t *testing.T) {
 	oth, _ := svc.AddThing(context.Background(), token, thing)
 	cth, _ := svc.AddThing(context.Background(), token, thing)
 	sch, _ := svc.CreateChannel(context.Background(), token, channel)
-	svc.Connect(context.Background(), token, sch.ID, cth.ID)
+	svc.Connect(context.Background(), token, sch.ID, cth.ID, nil)
 
 	usersAddr := fmt.Sprintf("localhost:%d", port)
 	conn, _ := grpc.Dial(usersAddr, grpc.WithInsecure())
@@ -125,7 +128,7 @@ func TestCanAccessByID(t *testing.T) {
 	}
 
 	for desc, tc := range cases {
-		_, err := cli.CanAccessByID(ctx, &mainflux.AccessByIDReq{ThingID: tc.thingID, ChanID: tc.chanID})
+		_, err := cli.CanAccessByID(ctx, &mainflux.AccessByIDReq{ThingID: tc.thingID, ChanID: tc.chanID, Action: things.Publish})
 		e, ok := status.FromError(err)
 		assert.True(t, ok, "OK expected to be true")
 		assert.Equal(t, tc.code, e.Code(), fmt.Sprintf("%s: expected %s got %s", desc, tc.code, e.Code()))
@@ -166,3 +169,467 @@ func TestIdentify(t *testing.T) {
 		assert.Equal(t, tc.code, e.Code(), fmt.Sprintf("%s: expected %s got %s", desc, tc.code, e.Code()))
 	}
 }
+
+func TestOwner(t *testing.T) {
+	sth, _ := svc.AddThing(context.Background(), token, thing)
+
+	usersAddr := fmt.Sprintf("localhost:%d", port)
+	conn, _ := grpc.Dial(usersAddr, grpc.WithInsecure())
+	cli := grpcapi.NewClient(conn, mocktracer.New(), time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	cases := map[string]struct {
+		id    string
+		owner string
+		code  codes.Code
+	}{
+		"retrieve owner of existing thing": {
+			id:    sth.ID,
+			owner: email,
+			code:  codes.OK,
+		},
+		"retrieve owner of non-existent thing": {
+			id:    wrong,
+			owner: "",
+			code:  codes.NotFound,
+		},
+		"retrieve owner of thing with empty id": {
+			id:    wrongID,
+			owner: "",
+			code:  codes.InvalidArgument,
+		},
+	}
+
+	for desc, tc := range cases {
+		owner, err := cli.Owner(ctx, &mainflux.ThingID{Value: tc.id})
+		e, ok := status.FromError(err)
+		assert.True(t, ok, "OK expected to be true")
+		assert.Equal(t, tc.owner, owner.GetValue(), fmt.Sprintf("%s: expected %s got %s", desc, tc.owner, owner.GetValue()))
+		assert.Equal(t, tc.code, e.Code(), fmt.Sprintf("%s: expected %s got %s", desc, tc.code, e.Code()))
+	}
+}
+
+func TestKeyDigest(t *testing.T) {
+	sth, _ := svc.AddThing(context.Background(), token, thing)
+	sum := sha256.Sum256([]byte(sth.Key))
+
+	usersAddr := fmt.Sprintf("localhost:%d", port)
+	conn, _ := grpc.Dial(usersAddr, grpc.WithInsecure())
+	cli := grpcapi.NewClient(conn, mocktracer.New(), time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	cases := map[string]struct {
+		id     string
+		digest string
+		code   codes.Code
+	}{
+		"retrieve key digest of existing thing": {
+			id:     sth.ID,
+			digest: hex.EncodeToString(sum[:]),
+			code:   codes.OK,
+		},
+		"retrieve key digest of non-existent thing": {
+			id:     wrong,
+			digest: "",
+			code:   codes.NotFound,
+		},
+		"retrieve key digest of thing with empty id": {
+			id:     wrongID,
+			digest: "",
+			code:   codes.InvalidArgument,
+		},
+	}
+
+	for desc, tc := range cases {
+		digest, err := cli.KeyDigest(ctx, &mainflux.ThingID{Value: tc.id})
+		e, ok := status.FromError(err)
+		assert.True(t, ok, "OK expected to be true")
+		assert.Equal(t, tc.digest, digest.GetValue(), fmt.Sprintf("%s: expected %s got %s", desc, tc.digest, digest.GetValue()))
+		assert.Equal(t, tc.code, e.Code(), fmt.Sprintf("%s: expected %s got %s", desc, tc.code, e.Code()))
+	}
+}
+
+func TestRetention(t *testing.T) {
+	ch := channel
+	ch.Retention = things.Retention{Period: time.Hour, Messages: 100}
+	sch, _ := svc.CreateChannel(context.Background(), token, ch)
+
+	usersAddr := fmt.Sprintf("localhost:%d", port)
+	conn, _ := grpc.Dial(usersAddr, grpc.WithInsecure())
+	cli := grpcapi.NewClient(conn, mocktracer.New(), time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	cases := map[string]struct {
+		id       string
+		period   int64
+		messages uint64
+		code     codes.Code
+	}{
+		"retrieve retention of existing channel": {
+			id:       sch.ID,
+			period:   3600,
+			messages: 100,
+			code:     codes.OK,
+		},
+		"retrieve retention of non-existent channel": {
+			id:   wrong,
+			code: codes.NotFound,
+		},
+		"retrieve retention of channel with empty id": {
+			id:   wrongID,
+			code: codes.InvalidArgument,
+		},
+	}
+
+	for desc, tc := range cases {
+		r, err := cli.Retention(ctx, &mainflux.ChannelID{Value: tc.id})
+		e, ok := status.FromError(err)
+		assert.True(t, ok, "OK expected to be true")
+		assert.Equal(t, tc.period, r.GetPeriod(), fmt.Sprintf("%s: expected period %d got %d", desc, tc.period, r.GetPeriod()))
+		assert.Equal(t, tc.messages, r.GetMessages(), fmt.Sprintf("%s: expected messages %d got %d", desc, tc.messages, r.GetMessages()))
+		assert.Equal(t, tc.code, e.Code(), fmt.Sprintf("%s: expected %s got %s", desc, tc.code, e.Code()))
+	}
+}
+
+func TestRegion(t *testing.T) {
+	ch := channel
+	ch.Region = "eu-west-1"
+	sch, _ := svc.CreateChannel(context.Background(), token, ch)
+
+	usersAddr := fmt.Sprintf("localhost:%d", port)
+	conn, _ := grpc.Dial(usersAddr, grpc.WithInsecure())
+	cli := grpcapi.NewClient(conn, mocktracer.New(), time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	cases := map[string]struct {
+		id     string
+		region string
+		code   codes.Code
+	}{
+		"retrieve region of existing channel": {
+			id:     sch.ID,
+			region: "eu-west-1",
+			code:   codes.OK,
+		},
+		"retrieve region of non-existent channel": {
+			id:   wrong,
+			code: codes.NotFound,
+		},
+		"retrieve region of channel with empty id": {
+			id:   wrongID,
+			code: codes.InvalidArgument,
+		},
+	}
+
+	for desc, tc := range cases {
+		r, err := cli.Region(ctx, &mainflux.ChannelID{Value: tc.id})
+		e, ok := status.FromError(err)
+		assert.True(t, ok, "OK expected to be true")
+		assert.Equal(t, tc.region, r.GetValue(), fmt.Sprintf("%s: expected region %s got %s", desc, tc.region, r.GetValue()))
+		assert.Equal(t, tc.code, e.Code(), fmt.Sprintf("%s: expected %s got %s", desc, tc.code, e.Code()))
+	}
+}
+
+func TestAlias(t *testing.T) {
+	ch := channel
+	ch.Alias = "temp"
+	sch, _ := svc.CreateChannel(context.Background(), token, ch)
+
+	usersAddr := fmt.Sprintf("localhost:%d", port)
+	conn, _ := grpc.Dial(usersAddr, grpc.WithInsecure())
+	cli := grpcapi.NewClient(conn, mocktracer.New(), time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	cases := map[string]struct {
+		id    string
+		alias string
+		code  codes.Code
+	}{
+		"retrieve alias of existing channel": {
+			id:    sch.ID,
+			alias: "temp",
+			code:  codes.OK,
+		},
+		"retrieve alias of non-existent channel": {
+			id:   wrong,
+			code: codes.NotFound,
+		},
+		"retrieve alias of channel with empty id": {
+			id:   wrongID,
+			code: codes.InvalidArgument,
+		},
+	}
+
+	for desc, tc := range cases {
+		a, err := cli.Alias(ctx, &mainflux.ChannelID{Value: tc.id})
+		e, ok := status.FromError(err)
+		assert.True(t, ok, "OK expected to be true")
+		assert.Equal(t, tc.alias, a.GetValue(), fmt.Sprintf("%s: expected alias %s got %s", desc, tc.alias, a.GetValue()))
+		assert.Equal(t, tc.code, e.Code(), fmt.Sprintf("%s: expected %s got %s", desc, tc.code, e.Code()))
+	}
+}
+
+func TestResolveAlias(t *testing.T) {
+	ch := channel
+	ch.Alias = "humidity"
+	sch, _ := svc.CreateChannel(context.Background(), token, ch)
+
+	usersAddr := fmt.Sprintf("localhost:%d", port)
+	conn, _ := grpc.Dial(usersAddr, grpc.WithInsecure())
+	cli := grpcapi.NewClient(conn, mocktracer.New(), time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	cases := map[string]struct {
+		alias string
+		id    string
+		code  codes.Code
+	}{
+		"resolve existing alias": {
+			alias: "humidity",
+			id:    sch.ID,
+			code:  codes.OK,
+		},
+		"resolve non-existent alias": {
+			alias: "pressure",
+			code:  codes.NotFound,
+		},
+		"resolve empty alias": {
+			alias: "",
+			code:  codes.InvalidArgument,
+		},
+	}
+
+	for desc, tc := range cases {
+		id, err := cli.ResolveAlias(ctx, &mainflux.ChannelAlias{Value: tc.alias})
+		e, ok := status.FromError(err)
+		assert.True(t, ok, "OK expected to be true")
+		assert.Equal(t, tc.id, id.GetValue(), fmt.Sprintf("%s: expected id %s got %s", desc, tc.id, id.GetValue()))
+		assert.Equal(t, tc.code, e.Code(), fmt.Sprintf("%s: expected %s got %s", desc, tc.code, e.Code()))
+	}
+}
+
+func TestOrdering(t *testing.T) {
+	ch := channel
+	ch.Ordered = true
+	sch, _ := svc.CreateChannel(context.Background(), token, ch)
+	uch, _ := svc.CreateChannel(context.Background(), token, channel)
+
+	usersAddr := fmt.Sprintf("localhost:%d", port)
+	conn, _ := grpc.Dial(usersAddr, grpc.WithInsecure())
+	cli := grpcapi.NewClient(conn, mocktracer.New(), time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	cases := map[string]struct {
+		id      string
+		ordered bool
+		code    codes.Code
+	}{
+		"retrieve ordering of ordered channel": {
+			id:      sch.ID,
+			ordered: true,
+			code:    codes.OK,
+		},
+		"retrieve ordering of unordered channel": {
+			id:      uch.ID,
+			ordered: false,
+			code:    codes.OK,
+		},
+		"retrieve ordering of non-existent channel": {
+			id:   wrong,
+			code: codes.NotFound,
+		},
+		"retrieve ordering of channel with empty id": {
+			id:   wrongID,
+			code: codes.InvalidArgument,
+		},
+	}
+
+	for desc, tc := range cases {
+		o, err := cli.Ordering(ctx, &mainflux.ChannelID{Value: tc.id})
+		e, ok := status.FromError(err)
+		assert.True(t, ok, "OK expected to be true")
+		assert.Equal(t, tc.ordered, o.GetOrdered(), fmt.Sprintf("%s: expected ordered %t got %t", desc, tc.ordered, o.GetOrdered()))
+		assert.Equal(t, tc.code, e.Code(), fmt.Sprintf("%s: expected %s got %s", desc, tc.code, e.Code()))
+	}
+}
+
+func TestTransformer(t *testing.T) {
+	ch := channel
+	ch.Metadata = map[string]interface{}{
+		"transformer": map[string]interface{}{
+			"format":  "protobuf",
+			"schema":  "weather",
+			"message": "weather.Reading",
+			"time":    "ts",
+		},
+	}
+	tch, _ := svc.CreateChannel(context.Background(), token, ch)
+	ch.Metadata = map[string]interface{}{"transformer": "json"}
+	mch, _ := svc.CreateChannel(context.Background(), token, ch)
+	uch, _ := svc.CreateChannel(context.Background(), token, channel)
+
+	usersAddr := fmt.Sprintf("localhost:%d", port)
+	conn, _ := grpc.Dial(usersAddr, grpc.WithInsecure())
+	cli := grpcapi.NewClient(conn, mocktracer.New(), time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	cases := map[string]struct {
+		id          string
+		transformer mainflux.Transformer
+		code        codes.Code
+	}{
+		"retrieve transformer of channel with transformer": {
+			id: tch.ID,
+			transformer: mainflux.Transformer{
+				Format:  "protobuf",
+				Schema:  "weather",
+				Message: "weather.Reading",
+				Time:    "ts",
+			},
+			code: codes.OK,
+		},
+		"retrieve transformer of channel with malformed transformer": {
+			id:   mch.ID,
+			code: codes.OK,
+		},
+		"retrieve transformer of channel without transformer": {
+			id:   uch.ID,
+			code: codes.OK,
+		},
+		"retrieve transformer of non-existent channel": {
+			id:   wrong,
+			code: codes.NotFound,
+		},
+		"retrieve transformer of channel with empty id": {
+			id:   wrongID,
+			code: codes.InvalidArgument,
+		},
+	}
+
+	for desc, tc := range cases {
+		tr, err := cli.Transformer(ctx, &mainflux.ChannelID{Value: tc.id})
+		e, ok := status.FromError(err)
+		assert.True(t, ok, "OK expected to be true")
+		assert.Equal(t, tc.transformer.Format, tr.GetFormat(), fmt.Sprintf("%s: expected format %s got %s", desc, tc.transformer.Format, tr.GetFormat()))
+		assert.Equal(t, tc.transformer.Schema, tr.GetSchema(), fmt.Sprintf("%s: expected schema %s got %s", desc, tc.transformer.Schema, tr.GetSchema()))
+		assert.Equal(t, tc.transformer.Message, tr.GetMessage(), fmt.Sprintf("%s: expected message %s got %s", desc, tc.transformer.Message, tr.GetMessage()))
+		assert.Equal(t, tc.transformer.Time, tr.GetTime(), fmt.Sprintf("%s: expected time %s got %s", desc, tc.transformer.Time, tr.GetTime()))
+		assert.Equal(t, tc.code, e.Code(), fmt.Sprintf("%s: expected %s got %s", desc, tc.code, e.Code()))
+	}
+}
+
+func TestPayloadSchema(t *testing.T) {
+	sch, _ := svc.CreateChannel(context.Background(), token, channel)
+	def := map[string]interface{}{"type": "object"}
+	svc.SavePayloadSchema(context.Background(), token, things.PayloadSchema{Channel: sch.ID, Subtopic: "devices", Definition: def})
+
+	usersAddr := fmt.Sprintf("localhost:%d", port)
+	conn, _ := grpc.Dial(usersAddr, grpc.WithInsecure())
+	cli := grpcapi.NewClient(conn, mocktracer.New(), time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	cases := map[string]struct {
+		id         string
+		subtopic   string
+		definition string
+		code       codes.Code
+	}{
+		"retrieve payload schema of subtopic with schema": {
+			id:         sch.ID,
+			subtopic:   "devices",
+			definition: `{"type":"object"}`,
+			code:       codes.OK,
+		},
+		"retrieve payload schema of subtopic without schema": {
+			id:         sch.ID,
+			subtopic:   "sensors",
+			definition: "",
+			code:       codes.OK,
+		},
+		"retrieve payload schema of non-existent channel": {
+			id:         wrong,
+			definition: "",
+			code:       codes.OK,
+		},
+		"retrieve payload schema of channel with empty id": {
+			id:         wrongID,
+			definition: "",
+			code:       codes.InvalidArgument,
+		},
+	}
+
+	for desc, tc := range cases {
+		ps, err := cli.PayloadSchema(ctx, &mainflux.PayloadSchemaReq{ChanID: tc.id, Subtopic: tc.subtopic})
+		e, ok := status.FromError(err)
+		assert.True(t, ok, "OK expected to be true")
+		assert.Equal(t, tc.definition, ps.GetDefinition(), fmt.Sprintf("%s: expected definition %s got %s", desc, tc.definition, ps.GetDefinition()))
+		assert.Equal(t, tc.code, e.Code(), fmt.Sprintf("%s: expected %s got %s", desc, tc.code, e.Code()))
+	}
+}
+
+func TestChanges(t *testing.T) {
+	usersAddr := fmt.Sprintf("localhost:%d", port)
+	conn, _ := grpc.Dial(usersAddr, grpc.WithInsecure())
+	cli := grpcapi.NewClient(conn, mocktracer.New(), time.Second)
+
+	cases := map[string]struct {
+		token   string
+		since   string
+		changes []things.Change
+		code    codes.Code
+	}{
+		"stream all changes": {
+			token:   token,
+			since:   "",
+			changes: []things.Change{changes[0], changes[2]},
+			code:    codes.OK,
+		},
+		"stream changes since position": {
+			token:   token,
+			since:   "1-0",
+			changes: []things.Change{changes[2]},
+			code:    codes.OK,
+		},
+		"stream changes since expired position": {
+			token: token,
+			since: "0-1",
+			code:  codes.OutOfRange,
+		},
+		"stream changes with invalid token": {
+			token: wrong,
+			since: "",
+			code:  codes.PermissionDenied,
+		},
+	}
+
+	for desc, tc := range cases {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		stream, err := cli.Changes(ctx, &mainflux.ChangesReq{Token: tc.token, Since: tc.since})
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", desc, err))
+
+		var received []things.Change
+		for range tc.changes {
+			msg, err := stream.Recv()
+			require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", desc, err))
+			received = append(received, things.Change{ID: msg.GetId(), Operation: msg.GetOperation(), Fields: msg.GetFields()})
+		}
+		assert.Equal(t, tc.changes, received, fmt.Sprintf("%s: expected %v got %v", desc, tc.changes, received))
+
+		if tc.code != codes.OK {
+			_, err := stream.Recv()
+			e, ok := status.FromError(err)
+			assert.True(t, ok, "OK expected to be true")
+			assert.Equal(t, tc.code, e.Code(), fmt.Sprintf("%s: expected %s got %s", desc, tc.code, e.Code()))
+		}
+		cancel()
+	}
+}
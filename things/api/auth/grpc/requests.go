@@ -8,10 +8,11 @@ import "github.com/mainflux/mainflux/things"
 type accessReq struct {
 	thingKey string
 	chanID   string
+	action   string
 }
 
 func (req accessReq) validate() error {
-	if req.chanID == "" || req.thingKey == "" {
+	if req.chanID == "" || req.thingKey == "" || req.action == "" {
 		return things.ErrMalformedEntity
 	}
 
@@ -21,16 +22,140 @@ func (req accessReq) validate() error {
 type accessByIDReq struct {
 	thingID string
 	chanID  string
+	action  string
 }
 
 func (req accessByIDReq) validate() error {
-	if req.thingID == "" || req.chanID == "" {
+	if req.thingID == "" || req.chanID == "" || req.action == "" {
 		return things.ErrMalformedEntity
 	}
 
 	return nil
 }
 
+type ownerReq struct {
+	thingID string
+}
+
+func (req ownerReq) validate() error {
+	if req.thingID == "" {
+		return things.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type retentionReq struct {
+	chanID string
+}
+
+func (req retentionReq) validate() error {
+	if req.chanID == "" {
+		return things.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type regionReq struct {
+	chanID string
+}
+
+func (req regionReq) validate() error {
+	if req.chanID == "" {
+		return things.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type aliasReq struct {
+	chanID string
+}
+
+func (req aliasReq) validate() error {
+	if req.chanID == "" {
+		return things.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type resolveAliasReq struct {
+	alias string
+}
+
+func (req resolveAliasReq) validate() error {
+	if req.alias == "" {
+		return things.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type orderingReq struct {
+	chanID string
+}
+
+func (req orderingReq) validate() error {
+	if req.chanID == "" {
+		return things.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type transformerReq struct {
+	chanID string
+}
+
+func (req transformerReq) validate() error {
+	if req.chanID == "" {
+		return things.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type payloadSchemaReq struct {
+	chanID   string
+	subtopic string
+}
+
+func (req payloadSchemaReq) validate() error {
+	if req.chanID == "" {
+		return things.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type keyDigestReq struct {
+	thingID string
+}
+
+func (req keyDigestReq) validate() error {
+	if req.thingID == "" {
+		return things.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type changesReq struct {
+	token string
+	since string
+	limit uint64
+}
+
+func (req changesReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	return nil
+}
+
 type identifyReq struct {
 	key string
 }
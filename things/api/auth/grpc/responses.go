@@ -3,11 +3,65 @@
 
 package grpc
 
+import "github.com/mainflux/mainflux/things"
+
 type identityRes struct {
 	id  string
 	err error
 }
 
+type ownerRes struct {
+	owner string
+	err   error
+}
+
+type retentionRes struct {
+	period   int64
+	messages uint64
+	err      error
+}
+
+type regionRes struct {
+	region string
+	err    error
+}
+
+type aliasRes struct {
+	alias string
+	err   error
+}
+
+type resolveAliasRes struct {
+	id  string
+	err error
+}
+
+type orderingRes struct {
+	ordered bool
+	err     error
+}
+
+type transformerRes struct {
+	transformer things.Transformer
+	err         error
+}
+
+type payloadSchemaRes struct {
+	definition string
+	err        error
+}
+
+type keyDigestRes struct {
+	digest string
+	err    error
+}
+
+type changesRes struct {
+	changes []things.Change
+	next    string
+	err     error
+}
+
 type emptyRes struct {
 	err error
 }
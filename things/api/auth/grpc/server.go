@@ -4,6 +4,9 @@
 package grpc
 
 import (
+	"time"
+
+	"github.com/go-kit/kit/endpoint"
 	kitot "github.com/go-kit/kit/tracing/opentracing"
 	kitgrpc "github.com/go-kit/kit/transport/grpc"
 	"github.com/golang/protobuf/ptypes/empty"
@@ -15,12 +18,31 @@ import (
 	"google.golang.org/grpc/status"
 )
 
+const (
+	// changesLimit is the number of changes retrieved at once while
+	// streaming the changes.
+	changesLimit = 100
+	// changesPoll is the interval between two retrievals of the changes once
+	// the recorded changes are streamed.
+	changesPoll = time.Second
+)
+
 var _ mainflux.ThingsServiceServer = (*grpcServer)(nil)
 
 type grpcServer struct {
 	canAccess     kitgrpc.Handler
 	canAccessByID kitgrpc.Handler
 	identify      kitgrpc.Handler
+	owner         kitgrpc.Handler
+	retention     kitgrpc.Handler
+	region        kitgrpc.Handler
+	alias         kitgrpc.Handler
+	resolveAlias  kitgrpc.Handler
+	ordering      kitgrpc.Handler
+	transformer   kitgrpc.Handler
+	payloadSchema kitgrpc.Handler
+	keyDigest     kitgrpc.Handler
+	changes       endpoint.Endpoint
 }
 
 // NewServer returns new ThingsServiceServer instance.
@@ -41,6 +63,52 @@ func NewServer(tracer opentracing.Tracer, svc things.Service) mainflux.ThingsSer
 			decodeIdentifyRequest,
 			encodeIdentityResponse,
 		),
+		owner: kitgrpc.NewServer(
+			kitot.TraceServer(tracer, "owner")(ownerEndpoint(svc)),
+			decodeOwnerRequest,
+			encodeOwnerResponse,
+		),
+		retention: kitgrpc.NewServer(
+			kitot.TraceServer(tracer, "retention")(retentionEndpoint(svc)),
+			decodeRetentionRequest,
+			encodeRetentionResponse,
+		),
+		region: kitgrpc.NewServer(
+			kitot.TraceServer(tracer, "region")(regionEndpoint(svc)),
+			decodeRegionRequest,
+			encodeRegionResponse,
+		),
+		alias: kitgrpc.NewServer(
+			kitot.TraceServer(tracer, "alias")(aliasEndpoint(svc)),
+			decodeAliasRequest,
+			encodeAliasResponse,
+		),
+		resolveAlias: kitgrpc.NewServer(
+			kitot.TraceServer(tracer, "resolve_alias")(resolveAliasEndpoint(svc)),
+			decodeResolveAliasRequest,
+			encodeResolveAliasResponse,
+		),
+		ordering: kitgrpc.NewServer(
+			kitot.TraceServer(tracer, "ordering")(orderingEndpoint(svc)),
+			decodeOrderingRequest,
+			encodeOrderingResponse,
+		),
+		transformer: kitgrpc.NewServer(
+			kitot.TraceServer(tracer, "transformer")(transformerEndpoint(svc)),
+			decodeTransformerRequest,
+			encodeTransformerResponse,
+		),
+		payloadSchema: kitgrpc.NewServer(
+			kitot.TraceServer(tracer, "payload_schema")(payloadSchemaEndpoint(svc)),
+			decodePayloadSchemaRequest,
+			encodePayloadSchemaResponse,
+		),
+		keyDigest: kitgrpc.NewServer(
+			kitot.TraceServer(tracer, "key_digest")(keyDigestEndpoint(svc)),
+			decodeKeyDigestRequest,
+			encodeKeyDigestResponse,
+		),
+		changes: kitot.TraceServer(tracer, "changes")(changesEndpoint(svc)),
 	}
 }
 
@@ -71,14 +139,133 @@ func (gs *grpcServer) Identify(ctx context.Context, req *mainflux.Token) (*mainf
 	return res.(*mainflux.ThingID), nil
 }
 
+func (gs *grpcServer) Owner(ctx context.Context, req *mainflux.ThingID) (*mainflux.UserID, error) {
+	_, res, err := gs.owner.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, encodeError(err)
+	}
+
+	return res.(*mainflux.UserID), nil
+}
+
+func (gs *grpcServer) Retention(ctx context.Context, req *mainflux.ChannelID) (*mainflux.Retention, error) {
+	_, res, err := gs.retention.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, encodeError(err)
+	}
+
+	return res.(*mainflux.Retention), nil
+}
+
+func (gs *grpcServer) Region(ctx context.Context, req *mainflux.ChannelID) (*mainflux.Region, error) {
+	_, res, err := gs.region.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, encodeError(err)
+	}
+
+	return res.(*mainflux.Region), nil
+}
+
+func (gs *grpcServer) Alias(ctx context.Context, req *mainflux.ChannelID) (*mainflux.ChannelAlias, error) {
+	_, res, err := gs.alias.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, encodeError(err)
+	}
+
+	return res.(*mainflux.ChannelAlias), nil
+}
+
+func (gs *grpcServer) ResolveAlias(ctx context.Context, req *mainflux.ChannelAlias) (*mainflux.ChannelID, error) {
+	_, res, err := gs.resolveAlias.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, encodeError(err)
+	}
+
+	return res.(*mainflux.ChannelID), nil
+}
+
+func (gs *grpcServer) Ordering(ctx context.Context, req *mainflux.ChannelID) (*mainflux.Ordering, error) {
+	_, res, err := gs.ordering.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, encodeError(err)
+	}
+
+	return res.(*mainflux.Ordering), nil
+}
+
+func (gs *grpcServer) Transformer(ctx context.Context, req *mainflux.ChannelID) (*mainflux.Transformer, error) {
+	_, res, err := gs.transformer.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, encodeError(err)
+	}
+
+	return res.(*mainflux.Transformer), nil
+}
+
+func (gs *grpcServer) PayloadSchema(ctx context.Context, req *mainflux.PayloadSchemaReq) (*mainflux.PayloadSchema, error) {
+	_, res, err := gs.payloadSchema.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, encodeError(err)
+	}
+
+	return res.(*mainflux.PayloadSchema), nil
+}
+
+func (gs *grpcServer) KeyDigest(ctx context.Context, req *mainflux.ThingID) (*mainflux.KeyDigest, error) {
+	_, res, err := gs.keyDigest.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, encodeError(err)
+	}
+
+	return res.(*mainflux.KeyDigest), nil
+}
+
+// Changes streams the changes recorded after the requested position. Once
+// the recorded changes are streamed, the new ones are polled for until the
+// client closes the stream.
+func (gs *grpcServer) Changes(req *mainflux.ChangesReq, stream mainflux.ThingsService_ChangesServer) error {
+	ctx := stream.Context()
+	since := req.GetSince()
+
+	for {
+		res, err := gs.changes(ctx, changesReq{token: req.GetToken(), since: since, limit: changesLimit})
+		if err != nil {
+			return encodeError(err)
+		}
+
+		cr := res.(changesRes)
+		for _, change := range cr.changes {
+			msg := &mainflux.Change{
+				Id:        change.ID,
+				Operation: change.Operation,
+				Fields:    change.Fields,
+			}
+			if err := stream.Send(msg); err != nil {
+				return err
+			}
+		}
+		since = cr.next
+
+		if len(cr.changes) == changesLimit {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(changesPoll):
+		}
+	}
+}
+
 func decodeCanAccessRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
 	req := grpcReq.(*mainflux.AccessReq)
-	return accessReq{thingKey: req.GetToken(), chanID: req.GetChanID()}, nil
+	return accessReq{thingKey: req.GetToken(), chanID: req.GetChanID(), action: req.GetAction()}, nil
 }
 
 func decodeCanAccessByIDRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
 	req := grpcReq.(*mainflux.AccessByIDReq)
-	return accessByIDReq{thingID: req.GetThingID(), chanID: req.GetChanID()}, nil
+	return accessByIDReq{thingID: req.GetThingID(), chanID: req.GetChanID(), action: req.GetAction()}, nil
 }
 
 func decodeIdentifyRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
@@ -86,11 +273,102 @@ func decodeIdentifyRequest(_ context.Context, grpcReq interface{}) (interface{},
 	return identifyReq{key: req.GetValue()}, nil
 }
 
+func decodeOwnerRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*mainflux.ThingID)
+	return ownerReq{thingID: req.GetValue()}, nil
+}
+
+func decodeRetentionRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*mainflux.ChannelID)
+	return retentionReq{chanID: req.GetValue()}, nil
+}
+
+func decodeRegionRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*mainflux.ChannelID)
+	return regionReq{chanID: req.GetValue()}, nil
+}
+
+func decodeAliasRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*mainflux.ChannelID)
+	return aliasReq{chanID: req.GetValue()}, nil
+}
+
+func decodeResolveAliasRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*mainflux.ChannelAlias)
+	return resolveAliasReq{alias: req.GetValue()}, nil
+}
+
+func decodeOrderingRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*mainflux.ChannelID)
+	return orderingReq{chanID: req.GetValue()}, nil
+}
+
+func decodeTransformerRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*mainflux.ChannelID)
+	return transformerReq{chanID: req.GetValue()}, nil
+}
+
+func decodePayloadSchemaRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*mainflux.PayloadSchemaReq)
+	return payloadSchemaReq{chanID: req.GetChanID(), subtopic: req.GetSubtopic()}, nil
+}
+
+func decodeKeyDigestRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*mainflux.ThingID)
+	return keyDigestReq{thingID: req.GetValue()}, nil
+}
+
 func encodeIdentityResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
 	res := grpcRes.(identityRes)
 	return &mainflux.ThingID{Value: res.id}, encodeError(res.err)
 }
 
+func encodeOwnerResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
+	res := grpcRes.(ownerRes)
+	return &mainflux.UserID{Value: res.owner}, encodeError(res.err)
+}
+
+func encodeRetentionResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
+	res := grpcRes.(retentionRes)
+	return &mainflux.Retention{Period: res.period, Messages: res.messages}, encodeError(res.err)
+}
+
+func encodeRegionResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
+	res := grpcRes.(regionRes)
+	return &mainflux.Region{Value: res.region}, encodeError(res.err)
+}
+
+func encodeAliasResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
+	res := grpcRes.(aliasRes)
+	return &mainflux.ChannelAlias{Value: res.alias}, encodeError(res.err)
+}
+
+func encodeResolveAliasResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
+	res := grpcRes.(resolveAliasRes)
+	return &mainflux.ChannelID{Value: res.id}, encodeError(res.err)
+}
+
+func encodeOrderingResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
+	res := grpcRes.(orderingRes)
+	return &mainflux.Ordering{Ordered: res.ordered}, encodeError(res.err)
+}
+
+func encodeTransformerResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
+	res := grpcRes.(transformerRes)
+	tr := res.transformer
+	return &mainflux.Transformer{Format: tr.Format, Schema: tr.Schema, Message: tr.Message, Time: tr.Time}, encodeError(res.err)
+}
+
+func encodePayloadSchemaResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
+	res := grpcRes.(payloadSchemaRes)
+	return &mainflux.PayloadSchema{Definition: res.definition}, encodeError(res.err)
+}
+
+func encodeKeyDigestResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
+	res := grpcRes.(keyDigestRes)
+	return &mainflux.KeyDigest{Value: res.digest}, encodeError(res.err)
+}
+
 func encodeEmptyResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
 	res := grpcRes.(emptyRes)
 	return &empty.Empty{}, encodeError(res.err)
@@ -104,6 +382,10 @@ func encodeError(err error) error {
 		return status.Error(codes.InvalidArgument, "received invalid can access request")
 	case things.ErrUnauthorizedAccess:
 		return status.Error(codes.PermissionDenied, "missing or invalid credentials provided")
+	case things.ErrNotFound:
+		return status.Error(codes.NotFound, "entity does not exist")
+	case things.ErrChangesExpired:
+		return status.Error(codes.OutOfRange, "changes are no longer available")
 	default:
 		return status.Error(codes.Internal, "internal server error")
 	}
@@ -24,7 +24,15 @@ const (
 	email = "john.doe@email.com"
 )
 
-var svc things.Service
+var (
+	svc     things.Service
+	changes = []things.Change{
+		{ID: "1-0", Operation: "thing.create", Fields: map[string]string{"id": "1"}},
+		{ID: "2-0", Operation: "thing.create", Fields: map[string]string{"id": "2"}},
+		{ID: "3-0", Operation: "thing.remove", Fields: map[string]string{"id": "1"}},
+	}
+	owners = []string{email, "jane.doe@email.com", email}
+)
 
 func TestMain(m *testing.M) {
 	startServer()
@@ -45,9 +53,12 @@ func newService(tokens map[string]string) things.Service {
 	conns := make(chan mocks.Connection)
 	thingsRepo := mocks.NewThingRepository(conns)
 	channelsRepo := mocks.NewChannelRepository(thingsRepo, conns)
+	quotasRepo := mocks.NewQuotaRepository(thingsRepo, channelsRepo)
+	schemasRepo := mocks.NewSchemaRepository()
 	chanCache := mocks.NewChannelCache()
 	thingCache := mocks.NewThingCache()
 	idp := mocks.NewIdentityProvider()
+	changeLog := mocks.NewChangeLog(owners, changes)
 
-	return things.New(users, thingsRepo, channelsRepo, chanCache, thingCache, idp)
+	return things.New(users, thingsRepo, channelsRepo, quotasRepo, schemasRepo, chanCache, thingCache, idp, nil, things.Quota{}, nil, 0, 0, nil, changeLog, nil)
 }
@@ -70,11 +70,13 @@ func newService(tokens map[string]string) things.Service {
 	conns := make(chan mocks.Connection)
 	thingsRepo := mocks.NewThingRepository(conns)
 	channelsRepo := mocks.NewChannelRepository(thingsRepo, conns)
+	quotasRepo := mocks.NewQuotaRepository(thingsRepo, channelsRepo)
+	schemasRepo := mocks.NewSchemaRepository()
 	chanCache := mocks.NewChannelCache()
 	thingCache := mocks.NewThingCache()
 	idp := mocks.NewIdentityProvider()
 
-	return things.New(users, thingsRepo, channelsRepo, chanCache, thingCache, idp)
+	return things.New(users, thingsRepo, channelsRepo, quotasRepo, schemasRepo, chanCache, thingCache, idp, nil, things.Quota{}, nil, 0, 0, nil, nil, nil)
 }
 
 func newServer(svc things.Service) *httptest.Server {
@@ -152,11 +154,12 @@ func TestCanAccess(t *testing.T) {
 	sch, err := svc.CreateChannel(context.Background(), token, channel)
 	require.Nil(t, err, fmt.Sprintf("failed to create channel: %s", err))
 
-	err = svc.Connect(context.Background(), token, sch.ID, sth.ID)
+	err = svc.Connect(context.Background(), token, sch.ID, sth.ID, nil)
 	require.Nil(t, err, fmt.Sprintf("failed to connect thing and channel: %s", err))
 
 	car := canAccessReq{
-		Token: sth.Key,
+		Token:  sth.Key,
+		Action: things.Publish,
 	}
 	data := toJSON(car)
 
@@ -229,11 +232,12 @@ func TestCanAccessByID(t *testing.T) {
 	sch, err := svc.CreateChannel(context.Background(), token, channel)
 	require.Nil(t, err, fmt.Sprintf("failed to create channel: %s", err))
 
-	err = svc.Connect(context.Background(), token, sch.ID, sth.ID)
+	err = svc.Connect(context.Background(), token, sch.ID, sth.ID, nil)
 	require.Nil(t, err, fmt.Sprintf("failed to connect thing and channel: %s", err))
 
 	car := canAccessByIDReq{
 		ThingID: sth.ID,
+		Action:  things.Publish,
 	}
 	data := toJSON(car)
 
@@ -300,9 +304,11 @@ type identifyReq struct {
 }
 
 type canAccessReq struct {
-	Token string `json:"token"`
+	Token  string `json:"token"`
+	Action string `json:"action"`
 }
 
 type canAccessByIDReq struct {
 	ThingID string `json:"thing_id"`
+	Action  string `json:"action"`
 }
@@ -26,6 +26,7 @@ func (req identifyReq) validate() error {
 type canAccessReq struct {
 	chanID string
 	Token  string `json:"token"`
+	Action string `json:"action"`
 }
 
 func (req canAccessReq) validate() error {
@@ -33,12 +34,17 @@ func (req canAccessReq) validate() error {
 		return things.ErrUnauthorizedAccess
 	}
 
+	if req.Action == "" {
+		return things.ErrMalformedEntity
+	}
+
 	return nil
 }
 
 type canAccessByIDReq struct {
 	chanID  string
 	ThingID string `json:"thing_id"`
+	Action  string `json:"action"`
 }
 
 func (req canAccessByIDReq) validate() error {
@@ -46,5 +52,9 @@ func (req canAccessByIDReq) validate() error {
 		return things.ErrUnauthorizedAccess
 	}
 
+	if req.Action == "" {
+		return things.ErrMalformedEntity
+	}
+
 	return nil
 }
@@ -65,6 +65,19 @@ func (lm *loggingMiddleware) UpdateKey(ctx context.Context, token, id, key strin
 	return lm.svc.UpdateKey(ctx, token, id, key)
 }
 
+func (lm *loggingMiddleware) RotateKey(ctx context.Context, token, id string) (key string, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method rotate_key for token %s and thing %s took %s to complete", token, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RotateKey(ctx, token, id)
+}
+
 func (lm *loggingMiddleware) ViewThing(ctx context.Context, token, id string) (thing things.Thing, err error) {
 	defer func(begin time.Time) {
 		message := fmt.Sprintf("Method view_thing for token %s and thing %s took %s to complete", token, id, time.Since(begin))
@@ -78,11 +91,11 @@ func (lm *loggingMiddleware) ViewThing(ctx context.Context, token, id string) (t
 	return lm.svc.ViewThing(ctx, token, id)
 }
 
-func (lm *loggingMiddleware) ListThings(ctx context.Context, token string, offset, limit uint64, name string, metadata things.Metadata) (_ things.ThingsPage, err error) {
+func (lm *loggingMiddleware) ListThings(ctx context.Context, token string, pq things.PageQuery) (_ things.ThingsPage, err error) {
 	defer func(begin time.Time) {
 		nlog := ""
-		if name != "" {
-			nlog = fmt.Sprintf("with name %s ", name)
+		if pq.Name != "" {
+			nlog = fmt.Sprintf("with name %s ", pq.Name)
 		}
 		message := fmt.Sprintf("Method list_things %sfor token %s took %s to complete", nlog, token, time.Since(begin))
 		if err != nil {
@@ -92,7 +105,20 @@ func (lm *loggingMiddleware) ListThings(ctx context.Context, token string, offse
 		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
 	}(time.Now())
 
-	return lm.svc.ListThings(ctx, token, offset, limit, name, metadata)
+	return lm.svc.ListThings(ctx, token, pq)
+}
+
+func (lm *loggingMiddleware) SearchThings(ctx context.Context, token, query string, offset, limit uint64) (_ things.ThingsPage, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method search_things for token %s and query %s took %s to complete", token, query, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.SearchThings(ctx, token, query, offset, limit)
 }
 
 func (lm *loggingMiddleware) ListThingsByChannel(ctx context.Context, token, id string, offset, limit uint64) (_ things.ThingsPage, err error) {
@@ -120,6 +146,110 @@ func (lm *loggingMiddleware) RemoveThing(ctx context.Context, token, id string)
 	return lm.svc.RemoveThing(ctx, token, id)
 }
 
+func (lm *loggingMiddleware) RestoreThing(ctx context.Context, token, id string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method restore_thing for token %s and thing %s took %s to complete", token, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RestoreThing(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) PurgeThing(ctx context.Context, token, id string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method purge_thing for token %s and thing %s took %s to complete", token, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.PurgeThing(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) ShareThing(ctx context.Context, token, id, group, permission string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method share_thing for token %s, thing %s and group %s took %s to complete", token, id, group, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ShareThing(ctx, token, id, group, permission)
+}
+
+func (lm *loggingMiddleware) UnshareThing(ctx context.Context, token, id, group string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method unshare_thing for token %s, thing %s and group %s took %s to complete", token, id, group, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.UnshareThing(ctx, token, id, group)
+}
+
+func (lm *loggingMiddleware) ShareThingWithUser(ctx context.Context, token, id, email, permission string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method share_thing_with_user for token %s, thing %s and user %s took %s to complete", token, id, email, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ShareThingWithUser(ctx, token, id, email, permission)
+}
+
+func (lm *loggingMiddleware) UnshareThingWithUser(ctx context.Context, token, id, email string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method unshare_thing_with_user for token %s, thing %s and user %s took %s to complete", token, id, email, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.UnshareThingWithUser(ctx, token, id, email)
+}
+
+func (lm *loggingMiddleware) ExportThings(ctx context.Context, token string, fn func(things.Thing) error) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method export_things for token %s took %s to complete", token, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ExportThings(ctx, token, fn)
+}
+
+func (lm *loggingMiddleware) ImportThings(ctx context.Context, token string, ths []things.Thing) (imported []things.Thing, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method import_things of %d things for token %s took %s to complete", len(ths), token, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ImportThings(ctx, token, ths)
+}
+
 func (lm *loggingMiddleware) CreateChannel(ctx context.Context, token string, channel things.Channel) (saved things.Channel, err error) {
 	defer func(begin time.Time) {
 		message := fmt.Sprintf("Method create_channel for token %s and channel %s took %s to complete", token, channel.ID, time.Since(begin))
@@ -133,6 +263,19 @@ func (lm *loggingMiddleware) CreateChannel(ctx context.Context, token string, ch
 	return lm.svc.CreateChannel(ctx, token, channel)
 }
 
+func (lm *loggingMiddleware) CreateDirectChannel(ctx context.Context, token, thing1, thing2 string) (saved things.Channel, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method create_direct_channel for token %s and things %s and %s took %s to complete", token, thing1, thing2, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.CreateDirectChannel(ctx, token, thing1, thing2)
+}
+
 func (lm *loggingMiddleware) UpdateChannel(ctx context.Context, token string, channel things.Channel) (err error) {
 	defer func(begin time.Time) {
 		message := fmt.Sprintf("Method update_channel for token %s and channel %s took %s to complete", token, channel.ID, time.Since(begin))
@@ -159,11 +302,11 @@ func (lm *loggingMiddleware) ViewChannel(ctx context.Context, token, id string)
 	return lm.svc.ViewChannel(ctx, token, id)
 }
 
-func (lm *loggingMiddleware) ListChannels(ctx context.Context, token string, offset, limit uint64, name string, metadata things.Metadata) (_ things.ChannelsPage, err error) {
+func (lm *loggingMiddleware) ListChannels(ctx context.Context, token string, pq things.PageQuery) (_ things.ChannelsPage, err error) {
 	defer func(begin time.Time) {
 		nlog := ""
-		if name != "" {
-			nlog = fmt.Sprintf("with name %s ", name)
+		if pq.Name != "" {
+			nlog = fmt.Sprintf("with name %s ", pq.Name)
 		}
 		message := fmt.Sprintf("Method list_channels %sfor token %s took %s to complete", nlog, token, time.Since(begin))
 		if err != nil {
@@ -173,7 +316,20 @@ func (lm *loggingMiddleware) ListChannels(ctx context.Context, token string, off
 		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
 	}(time.Now())
 
-	return lm.svc.ListChannels(ctx, token, offset, limit, name, metadata)
+	return lm.svc.ListChannels(ctx, token, pq)
+}
+
+func (lm *loggingMiddleware) SearchChannels(ctx context.Context, token, query string, offset, limit uint64) (_ things.ChannelsPage, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method search_channels for token %s and query %s took %s to complete", token, query, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.SearchChannels(ctx, token, query, offset, limit)
 }
 
 func (lm *loggingMiddleware) ListChannelsByThing(ctx context.Context, token, id string, offset, limit uint64) (_ things.ChannelsPage, err error) {
@@ -201,9 +357,35 @@ func (lm *loggingMiddleware) RemoveChannel(ctx context.Context, token, id string
 	return lm.svc.RemoveChannel(ctx, token, id)
 }
 
-func (lm *loggingMiddleware) Connect(ctx context.Context, token, chanID, thingID string) (err error) {
+func (lm *loggingMiddleware) RestoreChannel(ctx context.Context, token, id string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method restore_channel for token %s and channel %s took %s to complete", token, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RestoreChannel(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) PurgeChannel(ctx context.Context, token, id string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method purge_channel for token %s and channel %s took %s to complete", token, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.PurgeChannel(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) ShareChannel(ctx context.Context, token, id, group, permission string) (err error) {
 	defer func(begin time.Time) {
-		message := fmt.Sprintf("Method connect for token %s, channel %s and thing %s took %s to complete", token, chanID, thingID, time.Since(begin))
+		message := fmt.Sprintf("Method share_channel for token %s, channel %s and group %s took %s to complete", token, id, group, time.Since(begin))
 		if err != nil {
 			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
 			return
@@ -211,7 +393,85 @@ func (lm *loggingMiddleware) Connect(ctx context.Context, token, chanID, thingID
 		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
 	}(time.Now())
 
-	return lm.svc.Connect(ctx, token, chanID, thingID)
+	return lm.svc.ShareChannel(ctx, token, id, group, permission)
+}
+
+func (lm *loggingMiddleware) UnshareChannel(ctx context.Context, token, id, group string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method unshare_channel for token %s, channel %s and group %s took %s to complete", token, id, group, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.UnshareChannel(ctx, token, id, group)
+}
+
+func (lm *loggingMiddleware) ShareChannelWithUser(ctx context.Context, token, id, email, permission string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method share_channel_with_user for token %s, channel %s and user %s took %s to complete", token, id, email, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ShareChannelWithUser(ctx, token, id, email, permission)
+}
+
+func (lm *loggingMiddleware) UnshareChannelWithUser(ctx context.Context, token, id, email string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method unshare_channel_with_user for token %s, channel %s and user %s took %s to complete", token, id, email, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.UnshareChannelWithUser(ctx, token, id, email)
+}
+
+func (lm *loggingMiddleware) ExportChannels(ctx context.Context, token string, fn func(things.Channel) error) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method export_channels for token %s took %s to complete", token, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ExportChannels(ctx, token, fn)
+}
+
+func (lm *loggingMiddleware) ImportChannels(ctx context.Context, token string, chs []things.Channel) (imported []things.Channel, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method import_channels of %d channels for token %s took %s to complete", len(chs), token, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ImportChannels(ctx, token, chs)
+}
+
+func (lm *loggingMiddleware) Connect(ctx context.Context, token, chanID, thingID string, actions []string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method connect for token %s, channel %s, thing %s and actions %v took %s to complete", token, chanID, thingID, actions, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Connect(ctx, token, chanID, thingID, actions)
 }
 
 func (lm *loggingMiddleware) Disconnect(ctx context.Context, token, chanID, thingID string) (err error) {
@@ -227,9 +487,35 @@ func (lm *loggingMiddleware) Disconnect(ctx context.Context, token, chanID, thin
 	return lm.svc.Disconnect(ctx, token, chanID, thingID)
 }
 
-func (lm *loggingMiddleware) CanAccess(ctx context.Context, id, key string) (thing string, err error) {
+func (lm *loggingMiddleware) ExportConnections(ctx context.Context, token string, fn func(things.Connection) error) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method export_connections for token %s took %s to complete", token, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ExportConnections(ctx, token, fn)
+}
+
+func (lm *loggingMiddleware) ImportConnections(ctx context.Context, token string, conns []things.Connection) (imported []things.Connection, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method import_connections of %d connections for token %s took %s to complete", len(conns), token, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ImportConnections(ctx, token, conns)
+}
+
+func (lm *loggingMiddleware) CanAccess(ctx context.Context, id, key, action string) (thing string, err error) {
 	defer func(begin time.Time) {
-		message := fmt.Sprintf("Method can_access for channel %s and thing %s took %s to complete", id, thing, time.Since(begin))
+		message := fmt.Sprintf("Method can_access for channel %s, thing %s and action %s took %s to complete", id, thing, action, time.Since(begin))
 		if err != nil {
 			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
 			return
@@ -237,12 +523,12 @@ func (lm *loggingMiddleware) CanAccess(ctx context.Context, id, key string) (thi
 		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
 	}(time.Now())
 
-	return lm.svc.CanAccess(ctx, id, key)
+	return lm.svc.CanAccess(ctx, id, key, action)
 }
 
-func (lm *loggingMiddleware) CanAccessByID(ctx context.Context, chanID, thingID string) (err error) {
+func (lm *loggingMiddleware) CanAccessByID(ctx context.Context, chanID, thingID, action string) (err error) {
 	defer func(begin time.Time) {
-		message := fmt.Sprintf("Method can_access_by_id for channel %s and thing %s took %s to complete", chanID, thingID, time.Since(begin))
+		message := fmt.Sprintf("Method can_access_by_id for channel %s, thing %s and action %s took %s to complete", chanID, thingID, action, time.Since(begin))
 		if err != nil {
 			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
 			return
@@ -250,7 +536,7 @@ func (lm *loggingMiddleware) CanAccessByID(ctx context.Context, chanID, thingID
 		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
 	}(time.Now())
 
-	return lm.svc.CanAccessByID(ctx, chanID, thingID)
+	return lm.svc.CanAccessByID(ctx, chanID, thingID, action)
 }
 func (lm *loggingMiddleware) Identify(ctx context.Context, key string) (id string, err error) {
 	defer func(begin time.Time) {
@@ -264,3 +550,276 @@ func (lm *loggingMiddleware) Identify(ctx context.Context, key string) (id strin
 
 	return lm.svc.Identify(ctx, key)
 }
+
+func (lm *loggingMiddleware) Owner(ctx context.Context, id string) (owner string, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method owner for thing %s and owner %s took %s to complete", id, owner, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Owner(ctx, id)
+}
+
+func (lm *loggingMiddleware) KeyDigest(ctx context.Context, id string) (_ string, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method key_digest for thing %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.KeyDigest(ctx, id)
+}
+
+func (lm *loggingMiddleware) Retention(ctx context.Context, id string) (_ things.Retention, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method retention for channel %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Retention(ctx, id)
+}
+
+func (lm *loggingMiddleware) Region(ctx context.Context, id string) (_ string, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method region for channel %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Region(ctx, id)
+}
+
+func (lm *loggingMiddleware) Alias(ctx context.Context, id string) (_ string, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method alias for channel %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Alias(ctx, id)
+}
+
+func (lm *loggingMiddleware) ResolveAlias(ctx context.Context, alias string) (_ string, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method resolve_alias for alias %s took %s to complete", alias, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ResolveAlias(ctx, alias)
+}
+
+func (lm *loggingMiddleware) Ordering(ctx context.Context, id string) (_ bool, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method ordering for channel %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Ordering(ctx, id)
+}
+
+func (lm *loggingMiddleware) Transformer(ctx context.Context, id string) (_ things.Transformer, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method transformer for channel %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Transformer(ctx, id)
+}
+
+func (lm *loggingMiddleware) UpdateQuota(ctx context.Context, token string, quota things.Quota) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method update_quota for token %s and owner %s took %s to complete", token, quota.Owner, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.UpdateQuota(ctx, token, quota)
+}
+
+func (lm *loggingMiddleware) ViewQuota(ctx context.Context, token, owner string) (_ things.Quota, _ things.Usage, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method view_quota for token %s and owner %s took %s to complete", token, owner, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ViewQuota(ctx, token, owner)
+}
+
+func (lm *loggingMiddleware) RemoveQuota(ctx context.Context, token, owner string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method remove_quota for token %s and owner %s took %s to complete", token, owner, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RemoveQuota(ctx, token, owner)
+}
+
+func (lm *loggingMiddleware) TransferOwnership(ctx context.Context, token, from, to string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method transfer_ownership for token %s from owner %s to owner %s took %s to complete", token, from, to, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.TransferOwnership(ctx, token, from, to)
+}
+
+func (lm *loggingMiddleware) SaveSchema(ctx context.Context, token string, schema things.MetadataSchema) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method save_schema for token %s and entity %s took %s to complete", token, schema.Entity, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.SaveSchema(ctx, token, schema)
+}
+
+func (lm *loggingMiddleware) ViewSchema(ctx context.Context, token, entity string) (_ things.MetadataSchema, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method view_schema for token %s and entity %s took %s to complete", token, entity, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ViewSchema(ctx, token, entity)
+}
+
+func (lm *loggingMiddleware) RemoveSchema(ctx context.Context, token, entity string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method remove_schema for token %s and entity %s took %s to complete", token, entity, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RemoveSchema(ctx, token, entity)
+}
+
+func (lm *loggingMiddleware) SavePayloadSchema(ctx context.Context, token string, schema things.PayloadSchema) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method save_payload_schema for token %s, channel %s and subtopic %s took %s to complete", token, schema.Channel, schema.Subtopic, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.SavePayloadSchema(ctx, token, schema)
+}
+
+func (lm *loggingMiddleware) ListPayloadSchemas(ctx context.Context, token, chanID string) (_ []things.PayloadSchema, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_payload_schemas for token %s and channel %s took %s to complete", token, chanID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListPayloadSchemas(ctx, token, chanID)
+}
+
+func (lm *loggingMiddleware) RemovePayloadSchema(ctx context.Context, token, chanID, subtopic string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method remove_payload_schema for token %s, channel %s and subtopic %s took %s to complete", token, chanID, subtopic, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RemovePayloadSchema(ctx, token, chanID, subtopic)
+}
+
+func (lm *loggingMiddleware) PayloadSchema(ctx context.Context, chanID, subtopic string) (_ things.PayloadSchema, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method payload_schema for channel %s and subtopic %s took %s to complete", chanID, subtopic, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.PayloadSchema(ctx, chanID, subtopic)
+}
+
+func (lm *loggingMiddleware) ListChanges(ctx context.Context, token, since string, limit uint64) (_ things.ChangesPage, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_changes for token %s since %s took %s to complete", token, since, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListChanges(ctx, token, since, limit)
+}
+
+func (lm *loggingMiddleware) ViewAPIUsage(ctx context.Context, token string, from, to time.Time) (_ []things.APIUsage, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method view_api_usage for token %s took %s to complete", token, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ViewAPIUsage(ctx, token, from, to)
+}
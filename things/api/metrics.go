@@ -58,6 +58,15 @@ func (ms *metricsMiddleware) UpdateKey(ctx context.Context, token, id, key strin
 	return ms.svc.UpdateKey(ctx, token, id, key)
 }
 
+func (ms *metricsMiddleware) RotateKey(ctx context.Context, token, id string) (string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "rotate_key").Add(1)
+		ms.latency.With("method", "rotate_key").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RotateKey(ctx, token, id)
+}
+
 func (ms *metricsMiddleware) ViewThing(ctx context.Context, token, id string) (things.Thing, error) {
 	defer func(begin time.Time) {
 		ms.counter.With("method", "view_thing").Add(1)
@@ -67,13 +76,22 @@ func (ms *metricsMiddleware) ViewThing(ctx context.Context, token, id string) (t
 	return ms.svc.ViewThing(ctx, token, id)
 }
 
-func (ms *metricsMiddleware) ListThings(ctx context.Context, token string, offset, limit uint64, name string, metadata things.Metadata) (things.ThingsPage, error) {
+func (ms *metricsMiddleware) ListThings(ctx context.Context, token string, pq things.PageQuery) (things.ThingsPage, error) {
 	defer func(begin time.Time) {
 		ms.counter.With("method", "list_things").Add(1)
 		ms.latency.With("method", "list_things").Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
-	return ms.svc.ListThings(ctx, token, offset, limit, name, metadata)
+	return ms.svc.ListThings(ctx, token, pq)
+}
+
+func (ms *metricsMiddleware) SearchThings(ctx context.Context, token, query string, offset, limit uint64) (things.ThingsPage, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "search_things").Add(1)
+		ms.latency.With("method", "search_things").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.SearchThings(ctx, token, query, offset, limit)
 }
 
 func (ms *metricsMiddleware) ListThingsByChannel(ctx context.Context, token, id string, offset, limit uint64) (things.ThingsPage, error) {
@@ -94,6 +112,78 @@ func (ms *metricsMiddleware) RemoveThing(ctx context.Context, token, id string)
 	return ms.svc.RemoveThing(ctx, token, id)
 }
 
+func (ms *metricsMiddleware) RestoreThing(ctx context.Context, token, id string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "restore_thing").Add(1)
+		ms.latency.With("method", "restore_thing").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RestoreThing(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) PurgeThing(ctx context.Context, token, id string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "purge_thing").Add(1)
+		ms.latency.With("method", "purge_thing").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.PurgeThing(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) ShareThing(ctx context.Context, token, id, group, permission string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "share_thing").Add(1)
+		ms.latency.With("method", "share_thing").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ShareThing(ctx, token, id, group, permission)
+}
+
+func (ms *metricsMiddleware) UnshareThing(ctx context.Context, token, id, group string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "unshare_thing").Add(1)
+		ms.latency.With("method", "unshare_thing").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.UnshareThing(ctx, token, id, group)
+}
+
+func (ms *metricsMiddleware) ShareThingWithUser(ctx context.Context, token, id, email, permission string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "share_thing_with_user").Add(1)
+		ms.latency.With("method", "share_thing_with_user").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ShareThingWithUser(ctx, token, id, email, permission)
+}
+
+func (ms *metricsMiddleware) UnshareThingWithUser(ctx context.Context, token, id, email string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "unshare_thing_with_user").Add(1)
+		ms.latency.With("method", "unshare_thing_with_user").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.UnshareThingWithUser(ctx, token, id, email)
+}
+
+func (ms *metricsMiddleware) ExportThings(ctx context.Context, token string, fn func(things.Thing) error) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "export_things").Add(1)
+		ms.latency.With("method", "export_things").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ExportThings(ctx, token, fn)
+}
+
+func (ms *metricsMiddleware) ImportThings(ctx context.Context, token string, ths []things.Thing) ([]things.Thing, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "import_things").Add(1)
+		ms.latency.With("method", "import_things").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ImportThings(ctx, token, ths)
+}
+
 func (ms *metricsMiddleware) CreateChannel(ctx context.Context, token string, channel things.Channel) (things.Channel, error) {
 	defer func(begin time.Time) {
 		ms.counter.With("method", "create_channel").Add(1)
@@ -103,6 +193,15 @@ func (ms *metricsMiddleware) CreateChannel(ctx context.Context, token string, ch
 	return ms.svc.CreateChannel(ctx, token, channel)
 }
 
+func (ms *metricsMiddleware) CreateDirectChannel(ctx context.Context, token, thing1, thing2 string) (things.Channel, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "create_direct_channel").Add(1)
+		ms.latency.With("method", "create_direct_channel").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.CreateDirectChannel(ctx, token, thing1, thing2)
+}
+
 func (ms *metricsMiddleware) UpdateChannel(ctx context.Context, token string, channel things.Channel) error {
 	defer func(begin time.Time) {
 		ms.counter.With("method", "update_channel").Add(1)
@@ -121,13 +220,22 @@ func (ms *metricsMiddleware) ViewChannel(ctx context.Context, token, id string)
 	return ms.svc.ViewChannel(ctx, token, id)
 }
 
-func (ms *metricsMiddleware) ListChannels(ctx context.Context, token string, offset, limit uint64, name string, metadata things.Metadata) (things.ChannelsPage, error) {
+func (ms *metricsMiddleware) ListChannels(ctx context.Context, token string, pq things.PageQuery) (things.ChannelsPage, error) {
 	defer func(begin time.Time) {
 		ms.counter.With("method", "list_channels").Add(1)
 		ms.latency.With("method", "list_channels").Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
-	return ms.svc.ListChannels(ctx, token, offset, limit, name, metadata)
+	return ms.svc.ListChannels(ctx, token, pq)
+}
+
+func (ms *metricsMiddleware) SearchChannels(ctx context.Context, token, query string, offset, limit uint64) (things.ChannelsPage, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "search_channels").Add(1)
+		ms.latency.With("method", "search_channels").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.SearchChannels(ctx, token, query, offset, limit)
 }
 
 func (ms *metricsMiddleware) ListChannelsByThing(ctx context.Context, token, id string, offset, limit uint64) (things.ChannelsPage, error) {
@@ -148,13 +256,85 @@ func (ms *metricsMiddleware) RemoveChannel(ctx context.Context, token, id string
 	return ms.svc.RemoveChannel(ctx, token, id)
 }
 
-func (ms *metricsMiddleware) Connect(ctx context.Context, token, chanID, thingID string) error {
+func (ms *metricsMiddleware) RestoreChannel(ctx context.Context, token, id string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "restore_channel").Add(1)
+		ms.latency.With("method", "restore_channel").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RestoreChannel(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) PurgeChannel(ctx context.Context, token, id string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "purge_channel").Add(1)
+		ms.latency.With("method", "purge_channel").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.PurgeChannel(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) ShareChannel(ctx context.Context, token, id, group, permission string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "share_channel").Add(1)
+		ms.latency.With("method", "share_channel").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ShareChannel(ctx, token, id, group, permission)
+}
+
+func (ms *metricsMiddleware) UnshareChannel(ctx context.Context, token, id, group string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "unshare_channel").Add(1)
+		ms.latency.With("method", "unshare_channel").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.UnshareChannel(ctx, token, id, group)
+}
+
+func (ms *metricsMiddleware) ShareChannelWithUser(ctx context.Context, token, id, email, permission string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "share_channel_with_user").Add(1)
+		ms.latency.With("method", "share_channel_with_user").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ShareChannelWithUser(ctx, token, id, email, permission)
+}
+
+func (ms *metricsMiddleware) UnshareChannelWithUser(ctx context.Context, token, id, email string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "unshare_channel_with_user").Add(1)
+		ms.latency.With("method", "unshare_channel_with_user").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.UnshareChannelWithUser(ctx, token, id, email)
+}
+
+func (ms *metricsMiddleware) ExportChannels(ctx context.Context, token string, fn func(things.Channel) error) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "export_channels").Add(1)
+		ms.latency.With("method", "export_channels").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ExportChannels(ctx, token, fn)
+}
+
+func (ms *metricsMiddleware) ImportChannels(ctx context.Context, token string, chs []things.Channel) ([]things.Channel, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "import_channels").Add(1)
+		ms.latency.With("method", "import_channels").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ImportChannels(ctx, token, chs)
+}
+
+func (ms *metricsMiddleware) Connect(ctx context.Context, token, chanID, thingID string, actions []string) error {
 	defer func(begin time.Time) {
 		ms.counter.With("method", "connect").Add(1)
 		ms.latency.With("method", "connect").Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
-	return ms.svc.Connect(ctx, token, chanID, thingID)
+	return ms.svc.Connect(ctx, token, chanID, thingID, actions)
 }
 
 func (ms *metricsMiddleware) Disconnect(ctx context.Context, token, chanID, thingID string) error {
@@ -166,22 +346,40 @@ func (ms *metricsMiddleware) Disconnect(ctx context.Context, token, chanID, thin
 	return ms.svc.Disconnect(ctx, token, chanID, thingID)
 }
 
-func (ms *metricsMiddleware) CanAccess(ctx context.Context, id, key string) (string, error) {
+func (ms *metricsMiddleware) ExportConnections(ctx context.Context, token string, fn func(things.Connection) error) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "export_connections").Add(1)
+		ms.latency.With("method", "export_connections").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ExportConnections(ctx, token, fn)
+}
+
+func (ms *metricsMiddleware) ImportConnections(ctx context.Context, token string, conns []things.Connection) ([]things.Connection, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "import_connections").Add(1)
+		ms.latency.With("method", "import_connections").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ImportConnections(ctx, token, conns)
+}
+
+func (ms *metricsMiddleware) CanAccess(ctx context.Context, id, key, action string) (string, error) {
 	defer func(begin time.Time) {
 		ms.counter.With("method", "can_access").Add(1)
 		ms.latency.With("method", "can_access").Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
-	return ms.svc.CanAccess(ctx, id, key)
+	return ms.svc.CanAccess(ctx, id, key, action)
 }
 
-func (ms *metricsMiddleware) CanAccessByID(ctx context.Context, chanID, thingID string) error {
+func (ms *metricsMiddleware) CanAccessByID(ctx context.Context, chanID, thingID, action string) error {
 	defer func(begin time.Time) {
 		ms.counter.With("method", "can_access_by_id").Add(1)
 		ms.latency.With("method", "can_access_by_id").Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
-	return ms.svc.CanAccessByID(ctx, chanID, thingID)
+	return ms.svc.CanAccessByID(ctx, chanID, thingID, action)
 }
 
 func (ms *metricsMiddleware) Identify(ctx context.Context, key string) (string, error) {
@@ -192,3 +390,192 @@ func (ms *metricsMiddleware) Identify(ctx context.Context, key string) (string,
 
 	return ms.svc.Identify(ctx, key)
 }
+
+func (ms *metricsMiddleware) Owner(ctx context.Context, id string) (string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "owner").Add(1)
+		ms.latency.With("method", "owner").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Owner(ctx, id)
+}
+
+func (ms *metricsMiddleware) KeyDigest(ctx context.Context, id string) (string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "key_digest").Add(1)
+		ms.latency.With("method", "key_digest").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.KeyDigest(ctx, id)
+}
+
+func (ms *metricsMiddleware) Retention(ctx context.Context, id string) (things.Retention, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "retention").Add(1)
+		ms.latency.With("method", "retention").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Retention(ctx, id)
+}
+
+func (ms *metricsMiddleware) Region(ctx context.Context, id string) (string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "region").Add(1)
+		ms.latency.With("method", "region").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Region(ctx, id)
+}
+
+func (ms *metricsMiddleware) Alias(ctx context.Context, id string) (string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "alias").Add(1)
+		ms.latency.With("method", "alias").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Alias(ctx, id)
+}
+
+func (ms *metricsMiddleware) ResolveAlias(ctx context.Context, alias string) (string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "resolve_alias").Add(1)
+		ms.latency.With("method", "resolve_alias").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ResolveAlias(ctx, alias)
+}
+
+func (ms *metricsMiddleware) Ordering(ctx context.Context, id string) (bool, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "ordering").Add(1)
+		ms.latency.With("method", "ordering").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Ordering(ctx, id)
+}
+
+func (ms *metricsMiddleware) Transformer(ctx context.Context, id string) (things.Transformer, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "transformer").Add(1)
+		ms.latency.With("method", "transformer").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Transformer(ctx, id)
+}
+
+func (ms *metricsMiddleware) UpdateQuota(ctx context.Context, token string, quota things.Quota) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "update_quota").Add(1)
+		ms.latency.With("method", "update_quota").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.UpdateQuota(ctx, token, quota)
+}
+
+func (ms *metricsMiddleware) ViewQuota(ctx context.Context, token, owner string) (things.Quota, things.Usage, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_quota").Add(1)
+		ms.latency.With("method", "view_quota").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewQuota(ctx, token, owner)
+}
+
+func (ms *metricsMiddleware) RemoveQuota(ctx context.Context, token, owner string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "remove_quota").Add(1)
+		ms.latency.With("method", "remove_quota").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RemoveQuota(ctx, token, owner)
+}
+
+func (ms *metricsMiddleware) TransferOwnership(ctx context.Context, token, from, to string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "transfer_ownership").Add(1)
+		ms.latency.With("method", "transfer_ownership").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.TransferOwnership(ctx, token, from, to)
+}
+
+func (ms *metricsMiddleware) SaveSchema(ctx context.Context, token string, schema things.MetadataSchema) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "save_schema").Add(1)
+		ms.latency.With("method", "save_schema").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.SaveSchema(ctx, token, schema)
+}
+
+func (ms *metricsMiddleware) ViewSchema(ctx context.Context, token, entity string) (things.MetadataSchema, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_schema").Add(1)
+		ms.latency.With("method", "view_schema").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewSchema(ctx, token, entity)
+}
+
+func (ms *metricsMiddleware) RemoveSchema(ctx context.Context, token, entity string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "remove_schema").Add(1)
+		ms.latency.With("method", "remove_schema").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RemoveSchema(ctx, token, entity)
+}
+
+func (ms *metricsMiddleware) SavePayloadSchema(ctx context.Context, token string, schema things.PayloadSchema) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "save_payload_schema").Add(1)
+		ms.latency.With("method", "save_payload_schema").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.SavePayloadSchema(ctx, token, schema)
+}
+
+func (ms *metricsMiddleware) ListPayloadSchemas(ctx context.Context, token, chanID string) ([]things.PayloadSchema, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_payload_schemas").Add(1)
+		ms.latency.With("method", "list_payload_schemas").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListPayloadSchemas(ctx, token, chanID)
+}
+
+func (ms *metricsMiddleware) RemovePayloadSchema(ctx context.Context, token, chanID, subtopic string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "remove_payload_schema").Add(1)
+		ms.latency.With("method", "remove_payload_schema").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RemovePayloadSchema(ctx, token, chanID, subtopic)
+}
+
+func (ms *metricsMiddleware) PayloadSchema(ctx context.Context, chanID, subtopic string) (things.PayloadSchema, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "payload_schema").Add(1)
+		ms.latency.With("method", "payload_schema").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.PayloadSchema(ctx, chanID, subtopic)
+}
+
+func (ms *metricsMiddleware) ListChanges(ctx context.Context, token, since string, limit uint64) (things.ChangesPage, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_changes").Add(1)
+		ms.latency.With("method", "list_changes").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListChanges(ctx, token, since, limit)
+}
+
+func (ms *metricsMiddleware) ViewAPIUsage(ctx context.Context, token string, from, to time.Time) ([]things.APIUsage, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_api_usage").Add(1)
+		ms.latency.With("method", "view_api_usage").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewAPIUsage(ctx, token, from, to)
+}
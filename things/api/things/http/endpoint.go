@@ -5,6 +5,7 @@ package http
 
 import (
 	"context"
+	"io"
 
 	"github.com/go-kit/kit/endpoint"
 	"github.com/mainflux/mainflux/things"
@@ -31,6 +32,7 @@ func addThingEndpoint(svc things.Service) endpoint.Endpoint {
 		res := thingRes{
 			id:      saved.ID,
 			created: true,
+			Key:     saved.Key,
 		}
 		return res, nil
 	}
@@ -76,6 +78,23 @@ func updateKeyEndpoint(svc things.Service) endpoint.Endpoint {
 	}
 }
 
+func rotateKeyEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewResourceReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		key, err := svc.RotateKey(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		return rotateKeyRes{Key: key}, nil
+	}
+}
+
 func viewThingEndpoint(svc things.Service) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(viewResourceReq)
@@ -108,7 +127,45 @@ func listThingsEndpoint(svc things.Service) endpoint.Endpoint {
 			return nil, err
 		}
 
-		page, err := svc.ListThings(ctx, req.token, req.offset, req.limit, req.name, req.metadata)
+		page, err := svc.ListThings(ctx, req.token, req.pageQuery())
+		if err != nil {
+			return nil, err
+		}
+
+		res := thingsPageRes{
+			pageRes: pageRes{
+				Total:      page.Total,
+				Offset:     page.Offset,
+				Limit:      page.Limit,
+				NextCursor: page.NextCursor,
+			},
+			Things: []viewThingRes{},
+		}
+		for _, thing := range page.Things {
+			view := viewThingRes{
+				ID:        thing.ID,
+				Owner:     thing.Owner,
+				Name:      thing.Name,
+				Key:       thing.Key,
+				Metadata:  thing.Metadata,
+				DeletedAt: deletedAt(thing.DeletedAt),
+			}
+			res.Things = append(res.Things, view)
+		}
+
+		return res, nil
+	}
+}
+
+func searchThingsEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(searchReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		page, err := svc.SearchThings(ctx, req.token, req.query, req.offset, req.limit)
 		if err != nil {
 			return nil, err
 		}
@@ -193,6 +250,43 @@ func removeThingEndpoint(svc things.Service) endpoint.Endpoint {
 	}
 }
 
+func restoreThingEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewResourceReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RestoreThing(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+
+		return thingRes{id: req.id, created: false}, nil
+	}
+}
+
+func purgeThingEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewResourceReq)
+
+		err := req.validate()
+		if err == things.ErrNotFound {
+			return removeRes{}, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if err := svc.PurgeThing(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
 func createChannelEndpoint(svc things.Service) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(createChannelReq)
@@ -201,7 +295,14 @@ func createChannelEndpoint(svc things.Service) endpoint.Endpoint {
 			return nil, err
 		}
 
-		channel := things.Channel{Name: req.Name, Metadata: req.Metadata}
+		channel := things.Channel{
+			Name:      req.Name,
+			Metadata:  req.Metadata,
+			Retention: req.Retention.retention(),
+			Region:    req.Region,
+			Alias:     req.Alias,
+			Ordered:   req.Ordered,
+		}
 		saved, err := svc.CreateChannel(ctx, req.token, channel)
 		if err != nil {
 			return nil, err
@@ -215,6 +316,27 @@ func createChannelEndpoint(svc things.Service) endpoint.Endpoint {
 	}
 }
 
+func createDirectChannelEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(createDirectChannelReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		saved, err := svc.CreateDirectChannel(ctx, req.token, req.Things[0], req.Things[1])
+		if err != nil {
+			return nil, err
+		}
+
+		res := channelRes{
+			id:      saved.ID,
+			created: true,
+		}
+		return res, nil
+	}
+}
+
 func updateChannelEndpoint(svc things.Service) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(updateChannelReq)
@@ -224,9 +346,13 @@ func updateChannelEndpoint(svc things.Service) endpoint.Endpoint {
 		}
 
 		channel := things.Channel{
-			ID:       req.id,
-			Name:     req.Name,
-			Metadata: req.Metadata,
+			ID:        req.id,
+			Name:      req.Name,
+			Metadata:  req.Metadata,
+			Retention: req.Retention.retention(),
+			Region:    req.Region,
+			Alias:     req.Alias,
+			Ordered:   req.Ordered,
 		}
 		if err := svc.UpdateChannel(ctx, req.token, channel); err != nil {
 			return nil, err
@@ -254,10 +380,16 @@ func viewChannelEndpoint(svc things.Service) endpoint.Endpoint {
 		}
 
 		res := viewChannelRes{
-			ID:       channel.ID,
-			Owner:    channel.Owner,
-			Name:     channel.Name,
-			Metadata: channel.Metadata,
+			ID:        channel.ID,
+			Owner:     channel.Owner,
+			Name:      channel.Name,
+			Metadata:  channel.Metadata,
+			Retention: retention(channel.Retention),
+			Region:    channel.Region,
+			Alias:     channel.Alias,
+			Ordered:   channel.Ordered,
+			Type:      channel.Type,
+			Peers:     channel.Peers,
 		}
 
 		return res, nil
@@ -272,7 +404,52 @@ func listChannelsEndpoint(svc things.Service) endpoint.Endpoint {
 			return nil, err
 		}
 
-		page, err := svc.ListChannels(ctx, req.token, req.offset, req.limit, req.name, req.metadata)
+		page, err := svc.ListChannels(ctx, req.token, req.pageQuery())
+		if err != nil {
+			return nil, err
+		}
+
+		res := channelsPageRes{
+			pageRes: pageRes{
+				Total:      page.Total,
+				Offset:     page.Offset,
+				Limit:      page.Limit,
+				NextCursor: page.NextCursor,
+			},
+			Channels: []viewChannelRes{},
+		}
+		// Cast channels
+		for _, channel := range page.Channels {
+			view := viewChannelRes{
+				ID:        channel.ID,
+				Owner:     channel.Owner,
+				Name:      channel.Name,
+				Metadata:  channel.Metadata,
+				Retention: retention(channel.Retention),
+				Region:    channel.Region,
+				Alias:     channel.Alias,
+				Ordered:   channel.Ordered,
+				Type:      channel.Type,
+				Peers:     channel.Peers,
+				DeletedAt: deletedAt(channel.DeletedAt),
+			}
+
+			res.Channels = append(res.Channels, view)
+		}
+
+		return res, nil
+	}
+}
+
+func searchChannelsEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(searchReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		page, err := svc.SearchChannels(ctx, req.token, req.query, req.offset, req.limit)
 		if err != nil {
 			return nil, err
 		}
@@ -285,7 +462,6 @@ func listChannelsEndpoint(svc things.Service) endpoint.Endpoint {
 			},
 			Channels: []viewChannelRes{},
 		}
-		// Cast channels
 		for _, channel := range page.Channels {
 			view := viewChannelRes{
 				ID:       channel.ID,
@@ -293,7 +469,6 @@ func listChannelsEndpoint(svc things.Service) endpoint.Endpoint {
 				Name:     channel.Name,
 				Metadata: channel.Metadata,
 			}
-
 			res.Channels = append(res.Channels, view)
 		}
 
@@ -355,6 +530,169 @@ func removeChannelEndpoint(svc things.Service) endpoint.Endpoint {
 	}
 }
 
+func restoreChannelEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewResourceReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RestoreChannel(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+
+		return channelRes{id: req.id, created: false}, nil
+	}
+}
+
+func purgeChannelEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewResourceReq)
+
+		if err := req.validate(); err != nil {
+			if err == things.ErrNotFound {
+				return removeRes{}, nil
+			}
+			return nil, err
+		}
+
+		if err := svc.PurgeChannel(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
+func shareThingEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(shareReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.ShareThing(ctx, req.token, req.id, req.groupID, req.Permission); err != nil {
+			return nil, err
+		}
+
+		return shareRes{}, nil
+	}
+}
+
+func unshareThingEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(unshareReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.UnshareThing(ctx, req.token, req.id, req.groupID); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
+func shareThingWithUserEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(userShareReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.ShareThingWithUser(ctx, req.token, req.id, req.Email, req.Permission); err != nil {
+			return nil, err
+		}
+
+		return shareRes{}, nil
+	}
+}
+
+func unshareThingWithUserEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(userUnshareReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.UnshareThingWithUser(ctx, req.token, req.id, req.email); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
+func shareChannelEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(shareReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.ShareChannel(ctx, req.token, req.id, req.groupID, req.Permission); err != nil {
+			return nil, err
+		}
+
+		return shareRes{}, nil
+	}
+}
+
+func unshareChannelEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(unshareReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.UnshareChannel(ctx, req.token, req.id, req.groupID); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
+func shareChannelWithUserEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(userShareReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.ShareChannelWithUser(ctx, req.token, req.id, req.Email, req.Permission); err != nil {
+			return nil, err
+		}
+
+		return shareRes{}, nil
+	}
+}
+
+func unshareChannelWithUserEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(userUnshareReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.UnshareChannelWithUser(ctx, req.token, req.id, req.email); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
 func connectEndpoint(svc things.Service) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		cr := request.(connectionReq)
@@ -363,7 +701,7 @@ func connectEndpoint(svc things.Service) endpoint.Endpoint {
 			return nil, err
 		}
 
-		if err := svc.Connect(ctx, cr.token, cr.chanID, cr.thingID); err != nil {
+		if err := svc.Connect(ctx, cr.token, cr.chanID, cr.thingID, cr.Actions); err != nil {
 			return nil, err
 		}
 
@@ -386,3 +724,522 @@ func disconnectEndpoint(svc things.Service) endpoint.Endpoint {
 		return disconnectionRes{}, nil
 	}
 }
+
+const importBatchSize = 100
+
+func exportThingsEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(exportReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		res := exportRes{
+			name:   "things",
+			format: req.format,
+			header: thingFields,
+			export: func(write func(record) error) error {
+				return svc.ExportThings(ctx, req.token, func(th things.Thing) error {
+					return write(&thingRecord{
+						ID:       th.ID,
+						Name:     th.Name,
+						Key:      th.Key,
+						Metadata: th.Metadata,
+					})
+				})
+			},
+		}
+
+		return res, nil
+	}
+}
+
+func importThingsEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(importReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		rr, err := newRecordReader(req.format, req.body, thingFields)
+		if err != nil {
+			return nil, err
+		}
+
+		res := importRes{}
+		batch := []things.Thing{}
+		flush := func() error {
+			imported, err := svc.ImportThings(ctx, req.token, batch)
+			res.Imported += len(imported)
+			batch = batch[:0]
+			return err
+		}
+
+		for {
+			var rec thingRecord
+			err := rr.next(&rec)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			if len(rec.Name) > maxNameSize {
+				return nil, things.ErrMalformedEntity
+			}
+
+			batch = append(batch, things.Thing{
+				ID:       rec.ID,
+				Name:     rec.Name,
+				Key:      rec.Key,
+				Metadata: rec.Metadata,
+			})
+
+			if len(batch) == importBatchSize {
+				if err := flush(); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		if err := flush(); err != nil {
+			return nil, err
+		}
+
+		return res, nil
+	}
+}
+
+func exportChannelsEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(exportReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		res := exportRes{
+			name:   "channels",
+			format: req.format,
+			header: channelFields,
+			export: func(write func(record) error) error {
+				return svc.ExportChannels(ctx, req.token, func(ch things.Channel) error {
+					return write(&channelRecord{
+						ID:       ch.ID,
+						Name:     ch.Name,
+						Metadata: ch.Metadata,
+					})
+				})
+			},
+		}
+
+		return res, nil
+	}
+}
+
+func importChannelsEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(importReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		rr, err := newRecordReader(req.format, req.body, channelFields)
+		if err != nil {
+			return nil, err
+		}
+
+		res := importRes{}
+		batch := []things.Channel{}
+		flush := func() error {
+			imported, err := svc.ImportChannels(ctx, req.token, batch)
+			res.Imported += len(imported)
+			batch = batch[:0]
+			return err
+		}
+
+		for {
+			var rec channelRecord
+			err := rr.next(&rec)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			if len(rec.Name) > maxNameSize {
+				return nil, things.ErrMalformedEntity
+			}
+
+			batch = append(batch, things.Channel{
+				ID:       rec.ID,
+				Name:     rec.Name,
+				Metadata: rec.Metadata,
+			})
+
+			if len(batch) == importBatchSize {
+				if err := flush(); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		if err := flush(); err != nil {
+			return nil, err
+		}
+
+		return res, nil
+	}
+}
+
+func exportConnectionsEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(exportReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		res := exportRes{
+			name:   "connections",
+			format: req.format,
+			header: connectionFields,
+			export: func(write func(record) error) error {
+				return svc.ExportConnections(ctx, req.token, func(conn things.Connection) error {
+					return write(&connectionRecord{
+						ChannelID: conn.ChannelID,
+						ThingID:   conn.ThingID,
+						Actions:   conn.Actions,
+					})
+				})
+			},
+		}
+
+		return res, nil
+	}
+}
+
+func importConnectionsEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(importReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		rr, err := newRecordReader(req.format, req.body, connectionFields)
+		if err != nil {
+			return nil, err
+		}
+
+		res := importRes{}
+		batch := []things.Connection{}
+		flush := func() error {
+			imported, err := svc.ImportConnections(ctx, req.token, batch)
+			res.Imported += len(imported)
+			batch = batch[:0]
+			return err
+		}
+
+		for {
+			var rec connectionRecord
+			err := rr.next(&rec)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			if rec.ChannelID == "" || rec.ThingID == "" {
+				return nil, things.ErrMalformedEntity
+			}
+
+			batch = append(batch, things.Connection{
+				ChannelID: rec.ChannelID,
+				ThingID:   rec.ThingID,
+				Actions:   rec.Actions,
+			})
+
+			if len(batch) == importBatchSize {
+				if err := flush(); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		if err := flush(); err != nil {
+			return nil, err
+		}
+
+		return res, nil
+	}
+}
+
+func updateQuotaEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(updateQuotaReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		quota := things.Quota{
+			Owner:       req.owner,
+			Things:      req.Things,
+			Channels:    req.Channels,
+			Connections: req.Connections,
+		}
+
+		if err := svc.UpdateQuota(ctx, req.token, quota); err != nil {
+			return nil, err
+		}
+
+		return updateQuotaRes{}, nil
+	}
+}
+
+func viewQuotaEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(quotaReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		quota, usage, err := svc.ViewQuota(ctx, req.token, req.owner)
+		if err != nil {
+			return nil, err
+		}
+
+		res := viewQuotaRes{
+			Owner:       quota.Owner,
+			Things:      quota.Things,
+			Channels:    quota.Channels,
+			Connections: quota.Connections,
+			Usage: usageRes{
+				Things:      usage.Things,
+				Channels:    usage.Channels,
+				Connections: usage.Connections,
+			},
+		}
+
+		return res, nil
+	}
+}
+
+func removeQuotaEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(quotaReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RemoveQuota(ctx, req.token, req.owner); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
+func transferOwnershipEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(transferReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.TransferOwnership(ctx, req.token, req.from, req.To); err != nil {
+			return nil, err
+		}
+
+		return transferRes{}, nil
+	}
+}
+
+func saveSchemaEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(saveSchemaReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		schema := things.MetadataSchema{
+			Entity:     req.entity,
+			Definition: req.schema,
+		}
+
+		if err := svc.SaveSchema(ctx, req.token, schema); err != nil {
+			return nil, err
+		}
+
+		return saveSchemaRes{}, nil
+	}
+}
+
+func viewSchemaEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(schemaReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		schema, err := svc.ViewSchema(ctx, req.token, req.entity)
+		if err != nil {
+			return nil, err
+		}
+
+		return viewSchemaRes(schema.Definition), nil
+	}
+}
+
+func removeSchemaEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(schemaReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RemoveSchema(ctx, req.token, req.entity); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
+func savePayloadSchemaEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(savePayloadSchemaReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		schema := things.PayloadSchema{
+			Channel:    req.chanID,
+			Subtopic:   req.subtopic,
+			Definition: req.schema,
+		}
+
+		if err := svc.SavePayloadSchema(ctx, req.token, schema); err != nil {
+			return nil, err
+		}
+
+		return saveSchemaRes{}, nil
+	}
+}
+
+func listPayloadSchemasEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(payloadSchemaReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		schemas, err := svc.ListPayloadSchemas(ctx, req.token, req.chanID)
+		if err != nil {
+			return nil, err
+		}
+
+		res := payloadSchemasRes{Schemas: []payloadSchemaRes{}}
+		for _, s := range schemas {
+			res.Schemas = append(res.Schemas, payloadSchemaRes{
+				Subtopic: s.Subtopic,
+				Schema:   s.Definition,
+			})
+		}
+
+		return res, nil
+	}
+}
+
+func removePayloadSchemaEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(payloadSchemaReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RemovePayloadSchema(ctx, req.token, req.chanID, req.subtopic); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
+func listChangesEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listChangesReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		page, err := svc.ListChanges(ctx, req.token, req.since, req.limit)
+		if err != nil {
+			return nil, err
+		}
+
+		res := changesPageRes{
+			Changes: []changeRes{},
+			Next:    page.Next,
+		}
+		for _, change := range page.Changes {
+			res.Changes = append(res.Changes, changeRes{
+				ID:        change.ID,
+				Operation: change.Operation,
+				Fields:    change.Fields,
+			})
+		}
+
+		return res, nil
+	}
+}
+
+func viewAPIUsageEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(apiUsageReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		usage, err := svc.ViewAPIUsage(ctx, req.token, req.from, req.to)
+		if err != nil {
+			return nil, err
+		}
+
+		res := apiUsagePageRes{
+			From:  req.from,
+			To:    req.to,
+			Usage: []apiUsageRes{},
+		}
+		for _, u := range usage {
+			res.Calls += u.Calls
+			res.Errors += u.Errors
+			res.Usage = append(res.Usage, apiUsageRes{
+				Method: u.Method,
+				Hour:   u.Hour,
+				Calls:  u.Calls,
+				Errors: u.Errors,
+			})
+		}
+
+		return res, nil
+	}
+}
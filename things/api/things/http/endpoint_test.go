@@ -5,6 +5,7 @@ package http_test
 
 import (
 	"context"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -16,6 +17,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/things"
 	httpapi "github.com/mainflux/mainflux/things/api/things/http"
 	"github.com/mainflux/mainflux/things/mocks"
@@ -25,12 +27,17 @@ import (
 )
 
 const (
-	contentType = "application/json"
-	email       = "user@example.com"
-	token       = "token"
-	wrongValue  = "wrong_value"
-	wrongID     = 0
-	maxNameSize = 1024
+	contentType    = "application/json"
+	csvContentType = "text/csv"
+	email          = "user@example.com"
+	token          = "token"
+	wrongValue     = "wrong_value"
+	wrongID        = 0
+	maxNameSize    = 1024
+	group          = "123e4567-e89b-12d3-a456-000000000001"
+	keyGrace       = time.Hour
+	window         = time.Minute
+	tokenHeader    = "X-Consistency-Token"
 )
 
 var (
@@ -52,6 +59,7 @@ type testRequest struct {
 	contentType string
 	token       string
 	body        io.Reader
+	headers     map[string]string
 }
 
 func (tr testRequest) make() (*http.Response, error) {
@@ -65,6 +73,9 @@ func (tr testRequest) make() (*http.Response, error) {
 	if tr.contentType != "" {
 		req.Header.Set("Content-Type", tr.contentType)
 	}
+	for k, v := range tr.headers {
+		req.Header.Set(k, v)
+	}
 	return tr.client.Do(req)
 }
 
@@ -73,15 +84,31 @@ func newService(tokens map[string]string) things.Service {
 	conns := make(chan mocks.Connection)
 	thingsRepo := mocks.NewThingRepository(conns)
 	channelsRepo := mocks.NewChannelRepository(thingsRepo, conns)
+	quotasRepo := mocks.NewQuotaRepository(thingsRepo, channelsRepo)
+	schemasRepo := mocks.NewSchemaRepository()
+	chanCache := mocks.NewChannelCache()
+	thingCache := mocks.NewThingCache()
+	idp := mocks.NewIdentityProvider()
+
+	return things.New(users, thingsRepo, channelsRepo, quotasRepo, schemasRepo, chanCache, thingCache, idp, nil, things.Quota{}, nil, keyGrace, 0, nil, nil, nil)
+}
+
+func newSharingService() things.Service {
+	users := mocks.NewUsersServiceWithGroups(map[string]string{token: email}, map[string][]string{email: {group}})
+	conns := make(chan mocks.Connection)
+	thingsRepo := mocks.NewThingRepository(conns)
+	channelsRepo := mocks.NewChannelRepository(thingsRepo, conns)
+	quotasRepo := mocks.NewQuotaRepository(thingsRepo, channelsRepo)
+	schemasRepo := mocks.NewSchemaRepository()
 	chanCache := mocks.NewChannelCache()
 	thingCache := mocks.NewThingCache()
 	idp := mocks.NewIdentityProvider()
 
-	return things.New(users, thingsRepo, channelsRepo, chanCache, thingCache, idp)
+	return things.New(users, thingsRepo, channelsRepo, quotasRepo, schemasRepo, chanCache, thingCache, idp, nil, things.Quota{}, nil, keyGrace, 0, nil, nil, nil)
 }
 
 func newServer(svc things.Service) *httptest.Server {
-	mux := httpapi.MakeHandler(mocktracer.New(), svc)
+	mux := httpapi.MakeHandler(mocktracer.New(), svc, window)
 	return httptest.NewServer(mux)
 }
 
@@ -199,6 +226,16 @@ func TestAddThing(t *testing.T) {
 		location := res.Header.Get("Location")
 		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
 		assert.Equal(t, tc.location, location, fmt.Sprintf("%s: expected location %s got %s", tc.desc, tc.location, location))
+
+		if tc.status != http.StatusCreated {
+			continue
+		}
+
+		var body struct {
+			Key string `json:"key"`
+		}
+		json.NewDecoder(res.Body).Decode(&body)
+		assert.NotEmpty(t, body.Key, fmt.Sprintf("%s: expected key in response", tc.desc))
 	}
 }
 
@@ -333,6 +370,11 @@ func TestUpdateKey(t *testing.T) {
 	sth.Key = "key"
 	dummyData := toJSON(sth)
 
+	other := thing
+	other.Key = "other-key"
+	oth, _ := svc.AddThing(context.Background(), token, other)
+	conflictData := toJSON(oth)
+
 	cases := []struct {
 		desc        string
 		req         string
@@ -350,11 +392,19 @@ func TestUpdateKey(t *testing.T) {
 			status:      http.StatusOK,
 		},
 		{
-			desc:        "update thing with conflicting key",
+			desc:        "retry key update for an existing thing",
 			req:         data,
 			id:          sth.ID,
 			contentType: contentType,
 			auth:        token,
+			status:      http.StatusOK,
+		},
+		{
+			desc:        "update thing with conflicting key",
+			req:         conflictData,
+			id:          sth.ID,
+			contentType: contentType,
+			auth:        token,
 			status:      http.StatusUnprocessableEntity,
 		},
 		{
@@ -438,6 +488,168 @@ func TestUpdateKey(t *testing.T) {
 	}
 }
 
+func TestRotateKey(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	sth, _ := svc.AddThing(context.Background(), token, thing)
+
+	cases := []struct {
+		desc   string
+		id     string
+		auth   string
+		status int
+	}{
+		{
+			desc:   "rotate key of an existing thing",
+			id:     sth.ID,
+			auth:   token,
+			status: http.StatusOK,
+		},
+		{
+			desc:   "rotate key of non-existent thing",
+			id:     strconv.FormatUint(wrongID, 10),
+			auth:   token,
+			status: http.StatusNotFound,
+		},
+		{
+			desc:   "rotate key with invalid user token",
+			id:     sth.ID,
+			auth:   wrongValue,
+			status: http.StatusForbidden,
+		},
+		{
+			desc:   "rotate key with empty user token",
+			id:     sth.ID,
+			auth:   "",
+			status: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodPost,
+			url:    fmt.Sprintf("%s/things/%s/key/rotate", ts.URL, tc.id),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+
+		if tc.status != http.StatusOK {
+			continue
+		}
+
+		var body struct {
+			Key string `json:"key"`
+		}
+		json.NewDecoder(res.Body).Decode(&body)
+		assert.NotEmpty(t, body.Key, fmt.Sprintf("%s: expected new key in response", tc.desc))
+		assert.NotEqual(t, sth.Key, body.Key, fmt.Sprintf("%s: expected key to differ from the previous one", tc.desc))
+	}
+}
+
+// replicaReadsSpy records whether the thing views permit replica reads.
+type replicaReadsSpy struct {
+	things.Service
+	replicaReads bool
+}
+
+func (rrs *replicaReadsSpy) ViewThing(ctx context.Context, token, id string) (things.Thing, error) {
+	rrs.replicaReads = things.ReplicaReads(ctx)
+	return rrs.Service.ViewThing(ctx, token, id)
+}
+
+func TestReadConsistency(t *testing.T) {
+	spy := &replicaReadsSpy{Service: newService(map[string]string{token: email})}
+	ts := newServer(spy)
+	defer ts.Close()
+
+	req := testRequest{
+		client:      ts.Client(),
+		method:      http.MethodPost,
+		url:         fmt.Sprintf("%s/things", ts.URL),
+		contentType: contentType,
+		token:       token,
+		body:        strings.NewReader(toJSON(thing)),
+	}
+	res, err := req.make()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	require.Equal(t, http.StatusCreated, res.StatusCode, fmt.Sprintf("expected status code %d got %d", http.StatusCreated, res.StatusCode))
+
+	issued := res.Header.Get(tokenHeader)
+	assert.NotEmpty(t, issued, "add thing: expected consistency token in response")
+	location := res.Header.Get("Location")
+	expired := strconv.FormatInt(time.Now().Add(-window).UnixNano(), 10)
+
+	cases := []struct {
+		desc         string
+		token        string
+		replicaReads bool
+	}{
+		{
+			desc:         "view thing without consistency token",
+			token:        "",
+			replicaReads: true,
+		},
+		{
+			desc:         "view thing with consistency token",
+			token:        issued,
+			replicaReads: false,
+		},
+		{
+			desc:         "view thing with expired consistency token",
+			token:        expired,
+			replicaReads: true,
+		},
+		{
+			desc:         "view thing with malformed consistency token",
+			token:        wrongValue,
+			replicaReads: true,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:  ts.Client(),
+			method:  http.MethodGet,
+			url:     fmt.Sprintf("%s%s", ts.URL, location),
+			token:   token,
+			headers: map[string]string{tokenHeader: tc.token},
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, http.StatusOK, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, http.StatusOK, res.StatusCode))
+		assert.Equal(t, tc.replicaReads, spy.replicaReads, fmt.Sprintf("%s: expected replica reads %t got %t", tc.desc, tc.replicaReads, spy.replicaReads))
+		assert.Empty(t, res.Header.Get(tokenHeader), fmt.Sprintf("%s: unexpected consistency token in read response", tc.desc))
+	}
+}
+
+func TestDiscovery(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	req := testRequest{
+		client: ts.Client(),
+		method: http.MethodGet,
+		url:    fmt.Sprintf("%s%s", ts.URL, mainflux.DiscoveryPath),
+	}
+	res, err := req.make()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, http.StatusOK, res.StatusCode, fmt.Sprintf("expected status code %d got %d", http.StatusOK, res.StatusCode))
+
+	var info mainflux.DiscoveryInfo
+	err = json.NewDecoder(res.Body).Decode(&info)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, "things", info.Service, fmt.Sprintf("expected service things got %s", info.Service))
+	assert.True(t, info.Features["read_consistency"], "expected read consistency to be enabled")
+	assert.Equal(t, []string{contentType}, info.ContentTypes, fmt.Sprintf("expected content types %v got %v", []string{contentType}, info.ContentTypes))
+	assert.Equal(t, uint64(100), info.Limits.MaxPageSize, fmt.Sprintf("expected max page size 100 got %d", info.Limits.MaxPageSize))
+}
+
 func TestViewThing(t *testing.T) {
 	svc := newService(map[string]string{token: email})
 	ts := newServer(svc)
@@ -639,6 +851,55 @@ func TestListThings(t *testing.T) {
 			url:    fmt.Sprintf("%s%s", thingURL, "?offset=5&limit=e"),
 			res:    nil,
 		},
+		{
+			desc:   "get a list of things with invalid cursor",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s?limit=%d&cursor=%s", thingURL, 5, "%25"),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of things sorted by name in descending order",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s?limit=%d&order=%s&dir=%s", thingURL, 5, "name", "desc"),
+			res:    data[0:5],
+		},
+		{
+			desc:   "get a list of things with invalid order",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s?limit=%d&order=%s", thingURL, 5, "key"),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of things with invalid direction",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s?limit=%d&dir=%s", thingURL, 5, "up"),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of things filtered by metadata query",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s?limit=%d&metadata_query=%s&metadata_query=%s", thingURL, 5, "firmware.version%3C1.4.0", "type%3Dsensor"),
+			res:    data[0:5],
+		},
+		{
+			desc:   "get a list of things with invalid metadata query",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s?limit=%d&metadata_query=%s", thingURL, 5, "firmware..version%3C1.4.0"),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of things with metadata query without operator",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s?limit=%d&metadata_query=%s", thingURL, 5, "firmware.version"),
+			res:    nil,
+		},
 		{
 			desc:   "get a list of things filtering with invalid name",
 			auth:   token,
@@ -664,21 +925,17 @@ func TestListThings(t *testing.T) {
 	}
 }
 
-func TestListThingsByChannel(t *testing.T) {
+func TestSearchThings(t *testing.T) {
 	svc := newService(map[string]string{token: email})
 	ts := newServer(svc)
 	defer ts.Close()
 
-	sch, err := svc.CreateChannel(context.Background(), token, channel)
-	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
-
 	data := []thingRes{}
-	for i := 0; i < 101; i++ {
-		sth, err := svc.AddThing(context.Background(), token, thing)
-		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
-		err = svc.Connect(context.Background(), token, sch.ID, sth.ID)
+	for i := 0; i < 20; i++ {
+		th := thing
+		th.Name = fmt.Sprintf("sensor-%d", i)
+		sth, err := svc.AddThing(context.Background(), token, th)
 		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
-
 		thres := thingRes{
 			ID:       sth.ID,
 			Name:     sth.Name,
@@ -687,11 +944,8 @@ func TestListThingsByChannel(t *testing.T) {
 		}
 		data = append(data, thres)
 	}
-	thingURL := fmt.Sprintf("%s/channels", ts.URL)
-
-	// Wait for things and channels to connect.
-	time.Sleep(time.Second)
 
+	searchURL := fmt.Sprintf("%s/things/search", ts.URL)
 	cases := []struct {
 		desc   string
 		auth   string
@@ -700,102 +954,172 @@ func TestListThingsByChannel(t *testing.T) {
 		res    []thingRes
 	}{
 		{
-			desc:   "get a list of things by channel",
+			desc:   "search things by name",
 			auth:   token,
 			status: http.StatusOK,
-			url:    fmt.Sprintf("%s/%s/things?offset=%d&limit=%d", thingURL, sch.ID, 0, 5),
+			url:    fmt.Sprintf("%s?q=%s", searchURL, "sensor-7"),
+			res:    data[7:8],
+		},
+		{
+			desc:   "search things by metadata",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s?q=%s&offset=%d&limit=%d", searchURL, "data", 0, 5),
 			res:    data[0:5],
 		},
 		{
-			desc:   "get a list of things by channel with invalid token",
+			desc:   "search things without match",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s?q=%s", searchURL, "unknown"),
+			res:    []thingRes{},
+		},
+		{
+			desc:   "search things with invalid token",
 			auth:   wrongValue,
 			status: http.StatusForbidden,
-			url:    fmt.Sprintf("%s/%s/things?offset=%d&limit=%d", thingURL, sch.ID, 0, 1),
+			url:    fmt.Sprintf("%s?q=%s", searchURL, "sensor"),
 			res:    nil,
 		},
 		{
-			desc:   "get a list of things by channel with empty token",
+			desc:   "search things with empty token",
 			auth:   "",
 			status: http.StatusForbidden,
-			url:    fmt.Sprintf("%s/%s/things?offset=%d&limit=%d", thingURL, sch.ID, 0, 1),
+			url:    fmt.Sprintf("%s?q=%s", searchURL, "sensor"),
 			res:    nil,
 		},
 		{
-			desc:   "get a list of things by channel with negative offset",
+			desc:   "search things without query",
 			auth:   token,
 			status: http.StatusBadRequest,
-			url:    fmt.Sprintf("%s/%s/things?offset=%d&limit=%d", thingURL, sch.ID, -1, 5),
+			url:    searchURL,
 			res:    nil,
 		},
 		{
-			desc:   "get a list of things by channel with negative limit",
+			desc:   "search things with invalid query",
 			auth:   token,
 			status: http.StatusBadRequest,
-			url:    fmt.Sprintf("%s/%s/things?offset=%d&limit=%d", thingURL, sch.ID, 1, -5),
+			url:    fmt.Sprintf("%s?q=%s", searchURL, invalidName),
 			res:    nil,
 		},
 		{
-			desc:   "get a list of things by channel with zero limit",
+			desc:   "search things with limit greater than max",
 			auth:   token,
 			status: http.StatusBadRequest,
-			url:    fmt.Sprintf("%s/%s/things?offset=%d&limit=%d", thingURL, sch.ID, 1, 0),
+			url:    fmt.Sprintf("%s?q=%s&limit=%d", searchURL, "sensor", 110),
 			res:    nil,
 		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    tc.url,
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		var data thingsPageRes
+		json.NewDecoder(res.Body).Decode(&data)
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		assert.ElementsMatch(t, tc.res, data.Things, fmt.Sprintf("%s: expected body %v got %v", tc.desc, tc.res, data.Things))
+	}
+}
+
+func TestListChanges(t *testing.T) {
+	changes := []things.Change{
+		{ID: "1-0", Operation: "thing.create", Fields: map[string]string{"id": "1"}},
+		{ID: "2-0", Operation: "thing.create", Fields: map[string]string{"id": "2"}},
+		{ID: "3-0", Operation: "thing.remove", Fields: map[string]string{"id": "1"}},
+	}
+	owners := []string{email, "other@example.com", email}
+
+	users := mocks.NewUsersService(map[string]string{token: email})
+	conns := make(chan mocks.Connection)
+	thingsRepo := mocks.NewThingRepository(conns)
+	channelsRepo := mocks.NewChannelRepository(thingsRepo, conns)
+	quotasRepo := mocks.NewQuotaRepository(thingsRepo, channelsRepo)
+	changeLog := mocks.NewChangeLog(owners, changes)
+	svc := things.New(users, thingsRepo, channelsRepo, quotasRepo, mocks.NewSchemaRepository(), mocks.NewChannelCache(), mocks.NewThingCache(), mocks.NewIdentityProvider(), nil, things.Quota{}, nil, keyGrace, 0, nil, changeLog, nil)
+	ts := newServer(svc)
+	defer ts.Close()
+
+	data := []changeRes{}
+	for _, change := range []things.Change{changes[0], changes[2]} {
+		data = append(data, changeRes{ID: change.ID, Operation: change.Operation, Fields: change.Fields})
+	}
+
+	changesURL := fmt.Sprintf("%s/things/changes", ts.URL)
+	cases := []struct {
+		desc   string
+		auth   string
+		status int
+		url    string
+		res    changesPageRes
+	}{
 		{
-			desc:   "get a list of things by channel without offset",
+			desc:   "list all changes",
 			auth:   token,
 			status: http.StatusOK,
-			url:    fmt.Sprintf("%s/%s/things?limit=%d", thingURL, sch.ID, 5),
-			res:    data[0:5],
+			url:    changesURL,
+			res:    changesPageRes{Changes: data, Next: "3-0"},
 		},
 		{
-			desc:   "get a list of things by channel without limit",
+			desc:   "list changes since position",
 			auth:   token,
 			status: http.StatusOK,
-			url:    fmt.Sprintf("%s/%s/things?offset=%d", thingURL, sch.ID, 1),
-			res:    data[1:11],
+			url:    fmt.Sprintf("%s?since=%s", changesURL, "1-0"),
+			res:    changesPageRes{Changes: data[1:], Next: "3-0"},
 		},
 		{
-			desc:   "get a list of things by channel with redundant query params",
+			desc:   "list limited changes",
 			auth:   token,
 			status: http.StatusOK,
-			url:    fmt.Sprintf("%s/%s/things?offset=%d&limit=%d&value=something", thingURL, sch.ID, 0, 5),
-			res:    data[0:5],
+			url:    fmt.Sprintf("%s?limit=%d", changesURL, 1),
+			res:    changesPageRes{Changes: data[:1], Next: "1-0"},
 		},
 		{
-			desc:   "get a list of things by channel with limit greater than max",
+			desc:   "list changes since last position",
 			auth:   token,
-			status: http.StatusBadRequest,
-			url:    fmt.Sprintf("%s/%s/things?offset=%d&limit=%d", thingURL, sch.ID, 0, 110),
-			res:    nil,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s?since=%s", changesURL, "3-0"),
+			res:    changesPageRes{Changes: []changeRes{}, Next: "3-0"},
 		},
 		{
-			desc:   "get a list of things by channel with default URL",
+			desc:   "list changes since expired position",
 			auth:   token,
-			status: http.StatusOK,
-			url:    fmt.Sprintf("%s/%s/things", thingURL, sch.ID),
-			res:    data[0:10],
+			status: http.StatusGone,
+			url:    fmt.Sprintf("%s?since=%s", changesURL, "0-1"),
+			res:    changesPageRes{},
 		},
 		{
-			desc:   "get a list of things by channel with invalid number of params",
-			auth:   token,
-			status: http.StatusBadRequest,
-			url:    fmt.Sprintf("%s/%s/things%s", thingURL, sch.ID, "?offset=4&limit=4&limit=5&offset=5"),
-			res:    nil,
+			desc:   "list changes with invalid token",
+			auth:   wrongValue,
+			status: http.StatusForbidden,
+			url:    changesURL,
+			res:    changesPageRes{},
 		},
 		{
-			desc:   "get a list of things by channel with invalid offset",
+			desc:   "list changes with empty token",
+			auth:   "",
+			status: http.StatusForbidden,
+			url:    changesURL,
+			res:    changesPageRes{},
+		},
+		{
+			desc:   "list changes with limit greater than max",
 			auth:   token,
 			status: http.StatusBadRequest,
-			url:    fmt.Sprintf("%s/%s/things%s", thingURL, sch.ID, "?offset=e&limit=5"),
-			res:    nil,
+			url:    fmt.Sprintf("%s?limit=%d", changesURL, 110),
+			res:    changesPageRes{},
 		},
 		{
-			desc:   "get a list of things by channel with invalid limit",
+			desc:   "list changes with invalid limit",
 			auth:   token,
 			status: http.StatusBadRequest,
-			url:    fmt.Sprintf("%s/%s/things%s", thingURL, sch.ID, "?offset=5&limit=e"),
-			res:    nil,
+			url:    fmt.Sprintf("%s?limit=%s", changesURL, "invalid"),
+			res:    changesPageRes{},
 		},
 	}
 
@@ -808,47 +1132,3022 @@ func TestListThingsByChannel(t *testing.T) {
 		}
 		res, err := req.make()
 		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
-		var data thingsPageRes
+		var data changesPageRes
 		json.NewDecoder(res.Body).Decode(&data)
 		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
-		assert.ElementsMatch(t, tc.res, data.Things, fmt.Sprintf("%s: expected body %v got %v", tc.desc, tc.res, data.Things))
+		assert.Equal(t, tc.res, data, fmt.Sprintf("%s: expected body %v got %v", tc.desc, tc.res, data))
 	}
 }
 
-func TestRemoveThing(t *testing.T) {
-	svc := newService(map[string]string{token: email})
-	ts := newServer(svc)
-	defer ts.Close()
+func TestViewAPIUsage(t *testing.T) {
+	hour := time.Date(2020, time.January, 1, 10, 0, 0, 0, time.UTC)
+	usage := []things.APIUsage{
+		{Owner: email, Method: "add_thing", Hour: hour, Calls: 5, Errors: 1},
+		{Owner: email, Method: "view_thing", Hour: hour, Calls: 3, Errors: 0},
+		{Owner: email, Method: "add_thing", Hour: hour.Add(time.Hour), Calls: 2, Errors: 2},
+		{Owner: "other@example.com", Method: "add_thing", Hour: hour, Calls: 7, Errors: 0},
+	}
+	usageRepo := mocks.NewAPIUsageRepository()
+	err := usageRepo.Save(context.Background(), usage...)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	users := mocks.NewUsersService(map[string]string{token: email})
+	conns := make(chan mocks.Connection)
+	thingsRepo := mocks.NewThingRepository(conns)
+	channelsRepo := mocks.NewChannelRepository(thingsRepo, conns)
+	quotasRepo := mocks.NewQuotaRepository(thingsRepo, channelsRepo)
+	svc := things.New(users, thingsRepo, channelsRepo, quotasRepo, mocks.NewSchemaRepository(), mocks.NewChannelCache(), mocks.NewThingCache(), mocks.NewIdentityProvider(), nil, things.Quota{}, nil, keyGrace, 0, nil, nil, usageRepo)
+	ts := newServer(svc)
+	defer ts.Close()
+
+	data := []apiUsageRes{}
+	for _, u := range usage[:3] {
+		data = append(data, apiUsageRes{Method: u.Method, Hour: u.Hour, Calls: u.Calls, Errors: u.Errors})
+	}
+
+	from, to := hour.Add(-time.Hour), hour.Add(23*time.Hour)
+	usageURL := fmt.Sprintf("%s/usage", ts.URL)
+	rangeURL := fmt.Sprintf("%s?from=%s&to=%s", usageURL, from.Format(time.RFC3339), to.Format(time.RFC3339))
+	cases := []struct {
+		desc   string
+		auth   string
+		status int
+		url    string
+		res    apiUsagePageRes
+	}{
+		{
+			desc:   "view API usage of time range",
+			auth:   token,
+			status: http.StatusOK,
+			url:    rangeURL,
+			res:    apiUsagePageRes{From: from, To: to, Calls: 10, Errors: 3, Usage: data},
+		},
+		{
+			desc:   "view API usage of the day before the end",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s?to=%s", usageURL, hour.Add(time.Hour).Format(time.RFC3339)),
+			res:    apiUsagePageRes{From: hour.Add(-23 * time.Hour), To: hour.Add(time.Hour), Calls: 8, Errors: 1, Usage: data[:2]},
+		},
+		{
+			desc:   "view API usage with end before start",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s?from=%s&to=%s", usageURL, to.Format(time.RFC3339), from.Format(time.RFC3339)),
+			res:    apiUsagePageRes{},
+		},
+		{
+			desc:   "view API usage with invalid time",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s?from=%s", usageURL, "invalid"),
+			res:    apiUsagePageRes{},
+		},
+		{
+			desc:   "view API usage with invalid token",
+			auth:   wrongValue,
+			status: http.StatusForbidden,
+			url:    rangeURL,
+			res:    apiUsagePageRes{},
+		},
+		{
+			desc:   "view API usage with empty token",
+			auth:   "",
+			status: http.StatusForbidden,
+			url:    rangeURL,
+			res:    apiUsagePageRes{},
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    tc.url,
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		var data apiUsagePageRes
+		json.NewDecoder(res.Body).Decode(&data)
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		assert.Equal(t, tc.res, data, fmt.Sprintf("%s: expected body %v got %v", tc.desc, tc.res, data))
+	}
+}
+
+func TestListThingsByChannel(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	sch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	data := []thingRes{}
+	for i := 0; i < 101; i++ {
+		sth, err := svc.AddThing(context.Background(), token, thing)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		err = svc.Connect(context.Background(), token, sch.ID, sth.ID, nil)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+		thres := thingRes{
+			ID:       sth.ID,
+			Name:     sth.Name,
+			Key:      sth.Key,
+			Metadata: sth.Metadata,
+		}
+		data = append(data, thres)
+	}
+	thingURL := fmt.Sprintf("%s/channels", ts.URL)
+
+	// Wait for things and channels to connect.
+	time.Sleep(time.Second)
+
+	cases := []struct {
+		desc   string
+		auth   string
+		status int
+		url    string
+		res    []thingRes
+	}{
+		{
+			desc:   "get a list of things by channel",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s/%s/things?offset=%d&limit=%d", thingURL, sch.ID, 0, 5),
+			res:    data[0:5],
+		},
+		{
+			desc:   "get a list of things by channel with invalid token",
+			auth:   wrongValue,
+			status: http.StatusForbidden,
+			url:    fmt.Sprintf("%s/%s/things?offset=%d&limit=%d", thingURL, sch.ID, 0, 1),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of things by channel with empty token",
+			auth:   "",
+			status: http.StatusForbidden,
+			url:    fmt.Sprintf("%s/%s/things?offset=%d&limit=%d", thingURL, sch.ID, 0, 1),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of things by channel with negative offset",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s/%s/things?offset=%d&limit=%d", thingURL, sch.ID, -1, 5),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of things by channel with negative limit",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s/%s/things?offset=%d&limit=%d", thingURL, sch.ID, 1, -5),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of things by channel with zero limit",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s/%s/things?offset=%d&limit=%d", thingURL, sch.ID, 1, 0),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of things by channel without offset",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s/%s/things?limit=%d", thingURL, sch.ID, 5),
+			res:    data[0:5],
+		},
+		{
+			desc:   "get a list of things by channel without limit",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s/%s/things?offset=%d", thingURL, sch.ID, 1),
+			res:    data[1:11],
+		},
+		{
+			desc:   "get a list of things by channel with redundant query params",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s/%s/things?offset=%d&limit=%d&value=something", thingURL, sch.ID, 0, 5),
+			res:    data[0:5],
+		},
+		{
+			desc:   "get a list of things by channel with limit greater than max",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s/%s/things?offset=%d&limit=%d", thingURL, sch.ID, 0, 110),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of things by channel with default URL",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s/%s/things", thingURL, sch.ID),
+			res:    data[0:10],
+		},
+		{
+			desc:   "get a list of things by channel with invalid number of params",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s/%s/things%s", thingURL, sch.ID, "?offset=4&limit=4&limit=5&offset=5"),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of things by channel with invalid offset",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s/%s/things%s", thingURL, sch.ID, "?offset=e&limit=5"),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of things by channel with invalid limit",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s/%s/things%s", thingURL, sch.ID, "?offset=5&limit=e"),
+			res:    nil,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    tc.url,
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		var data thingsPageRes
+		json.NewDecoder(res.Body).Decode(&data)
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		assert.ElementsMatch(t, tc.res, data.Things, fmt.Sprintf("%s: expected body %v got %v", tc.desc, tc.res, data.Things))
+	}
+}
+
+func TestRemoveThing(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	sth, _ := svc.AddThing(context.Background(), token, thing)
+
+	cases := []struct {
+		desc   string
+		id     string
+		auth   string
+		status int
+	}{
+		{
+			desc:   "delete existing thing",
+			id:     sth.ID,
+			auth:   token,
+			status: http.StatusNoContent,
+		},
+		{
+			desc:   "delete non-existent thing",
+			id:     strconv.FormatUint(wrongID, 10),
+			auth:   token,
+			status: http.StatusNoContent,
+		},
+		{
+			desc:   "delete thing with invalid token",
+			id:     sth.ID,
+			auth:   wrongValue,
+			status: http.StatusForbidden,
+		},
+		{
+			desc:   "delete thing with empty token",
+			id:     sth.ID,
+			auth:   "",
+			status: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodDelete,
+			url:    fmt.Sprintf("%s/things/%s", ts.URL, tc.id),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestShareThing(t *testing.T) {
+	svc := newSharingService()
+	ts := newServer(svc)
+	defer ts.Close()
+
+	s, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	data := toJSON(map[string]string{"permission": things.ViewPermission})
+	invalidData := toJSON(map[string]string{"permission": wrongValue})
+
+	cases := []struct {
+		desc        string
+		id          string
+		group       string
+		req         string
+		contentType string
+		auth        string
+		status      int
+	}{
+		{
+			desc:        "share thing with group",
+			id:          s.ID,
+			group:       group,
+			req:         data,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusOK,
+		},
+		{
+			desc:        "share thing with invalid permission",
+			id:          s.ID,
+			group:       group,
+			req:         invalidData,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "share thing with empty request",
+			id:          s.ID,
+			group:       group,
+			req:         "",
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "share thing with group the user is not member of",
+			id:          s.ID,
+			group:       wrongValue,
+			req:         data,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusNotFound,
+		},
+		{
+			desc:        "share non-existent thing",
+			id:          strconv.FormatUint(wrongID, 10),
+			group:       group,
+			req:         data,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusNotFound,
+		},
+		{
+			desc:        "share thing with invalid token",
+			id:          s.ID,
+			group:       group,
+			req:         data,
+			contentType: contentType,
+			auth:        wrongValue,
+			status:      http.StatusForbidden,
+		},
+		{
+			desc:        "share thing with empty token",
+			id:          s.ID,
+			group:       group,
+			req:         data,
+			contentType: contentType,
+			auth:        "",
+			status:      http.StatusForbidden,
+		},
+		{
+			desc:        "share thing without content type",
+			id:          s.ID,
+			group:       group,
+			req:         data,
+			contentType: "",
+			auth:        token,
+			status:      http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPut,
+			url:         fmt.Sprintf("%s/things/%s/shares/%s", ts.URL, tc.id, tc.group),
+			contentType: tc.contentType,
+			token:       tc.auth,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestUnshareThing(t *testing.T) {
+	svc := newSharingService()
+	ts := newServer(svc)
+	defer ts.Close()
+
+	s, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = svc.ShareThing(context.Background(), token, s.ID, group, things.ViewPermission)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		id     string
+		auth   string
+		status int
+	}{
+		{
+			desc:   "unshare shared thing",
+			id:     s.ID,
+			auth:   token,
+			status: http.StatusNoContent,
+		},
+		{
+			desc:   "unshare non-existent thing",
+			id:     strconv.FormatUint(wrongID, 10),
+			auth:   token,
+			status: http.StatusNoContent,
+		},
+		{
+			desc:   "unshare thing with invalid token",
+			id:     s.ID,
+			auth:   wrongValue,
+			status: http.StatusForbidden,
+		},
+		{
+			desc:   "unshare thing with empty token",
+			id:     s.ID,
+			auth:   "",
+			status: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodDelete,
+			url:    fmt.Sprintf("%s/things/%s/shares/%s", ts.URL, tc.id, group),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestShareThingWithUser(t *testing.T) {
+	svc := newSharingService()
+	ts := newServer(svc)
+	defer ts.Close()
+
+	s, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	member := "member@example.com"
+	data := toJSON(map[string]string{"email": member, "permission": things.EditPermission})
+	ownerData := toJSON(map[string]string{"email": email, "permission": things.EditPermission})
+	invalidData := toJSON(map[string]string{"email": member, "permission": wrongValue})
+	missingEmailData := toJSON(map[string]string{"permission": things.EditPermission})
+
+	cases := []struct {
+		desc        string
+		id          string
+		req         string
+		contentType string
+		auth        string
+		status      int
+	}{
+		{
+			desc:        "share thing with user",
+			id:          s.ID,
+			req:         data,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusOK,
+		},
+		{
+			desc:        "share thing with its owner",
+			id:          s.ID,
+			req:         ownerData,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "share thing with invalid permission",
+			id:          s.ID,
+			req:         invalidData,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "share thing without email",
+			id:          s.ID,
+			req:         missingEmailData,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "share thing with invalid request format",
+			id:          s.ID,
+			req:         "{",
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "share non-existent thing",
+			id:          strconv.FormatUint(wrongID, 10),
+			req:         data,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusNotFound,
+		},
+		{
+			desc:        "share thing with invalid token",
+			id:          s.ID,
+			req:         data,
+			contentType: contentType,
+			auth:        wrongValue,
+			status:      http.StatusForbidden,
+		},
+		{
+			desc:        "share thing without content type",
+			id:          s.ID,
+			req:         data,
+			contentType: "",
+			auth:        token,
+			status:      http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/things/%s/share", ts.URL, tc.id),
+			contentType: tc.contentType,
+			token:       tc.auth,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestUnshareThingWithUser(t *testing.T) {
+	svc := newSharingService()
+	ts := newServer(svc)
+	defer ts.Close()
+
+	member := "member@example.com"
+	s, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = svc.ShareThingWithUser(context.Background(), token, s.ID, member, things.ViewPermission)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		id     string
+		auth   string
+		status int
+	}{
+		{
+			desc:   "unshare thing shared with user",
+			id:     s.ID,
+			auth:   token,
+			status: http.StatusNoContent,
+		},
+		{
+			desc:   "unshare non-existent thing",
+			id:     strconv.FormatUint(wrongID, 10),
+			auth:   token,
+			status: http.StatusNoContent,
+		},
+		{
+			desc:   "unshare thing with invalid token",
+			id:     s.ID,
+			auth:   wrongValue,
+			status: http.StatusForbidden,
+		},
+		{
+			desc:   "unshare thing with empty token",
+			id:     s.ID,
+			auth:   "",
+			status: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodDelete,
+			url:    fmt.Sprintf("%s/things/%s/share/%s", ts.URL, tc.id, member),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestExportThings(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	n := 3
+	for i := 0; i < n; i++ {
+		_, err := svc.AddThing(context.Background(), token, thing)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	cases := []struct {
+		desc        string
+		format      string
+		auth        string
+		status      int
+		contentType string
+		size        int
+	}{
+		{
+			desc:        "export things as JSON by default",
+			format:      "",
+			auth:        token,
+			status:      http.StatusOK,
+			contentType: contentType,
+			size:        n,
+		},
+		{
+			desc:        "export things as JSON",
+			format:      "?format=json",
+			auth:        token,
+			status:      http.StatusOK,
+			contentType: contentType,
+			size:        n,
+		},
+		{
+			desc:        "export things as CSV",
+			format:      "?format=csv",
+			auth:        token,
+			status:      http.StatusOK,
+			contentType: csvContentType,
+			size:        n,
+		},
+		{
+			desc:        "export things with unsupported format",
+			format:      "?format=xml",
+			auth:        token,
+			status:      http.StatusBadRequest,
+			contentType: contentType,
+		},
+		{
+			desc:        "export things with invalid token",
+			format:      "",
+			auth:        wrongValue,
+			status:      http.StatusForbidden,
+			contentType: contentType,
+		},
+		{
+			desc:        "export things with empty token",
+			format:      "",
+			auth:        "",
+			status:      http.StatusForbidden,
+			contentType: contentType,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/things/export%s", ts.URL, tc.format),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		assert.Equal(t, tc.contentType, res.Header.Get("Content-Type"), fmt.Sprintf("%s: expected content type %s got %s", tc.desc, tc.contentType, res.Header.Get("Content-Type")))
+		if tc.status != http.StatusOK {
+			continue
+		}
+
+		if tc.contentType == csvContentType {
+			rows, err := csv.NewReader(res.Body).ReadAll()
+			assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+			require.Equal(t, tc.size+1, len(rows), fmt.Sprintf("%s: expected %d rows got %d", tc.desc, tc.size+1, len(rows)))
+			assert.Equal(t, []string{"id", "name", "key", "metadata"}, rows[0], fmt.Sprintf("%s: expected header %v got %v", tc.desc, []string{"id", "name", "key", "metadata"}, rows[0]))
+			continue
+		}
+
+		var body []map[string]interface{}
+		err = json.NewDecoder(res.Body).Decode(&body)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.size, len(body), fmt.Sprintf("%s: expected %d records got %d", tc.desc, tc.size, len(body)))
+	}
+}
+
+func TestImportThings(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	cases := []struct {
+		desc        string
+		req         string
+		contentType string
+		auth        string
+		status      int
+		imported    int
+	}{
+		{
+			desc:        "import things as JSON",
+			req:         `[{"id":"a","name":"a","key":"key-a","metadata":{"test":"data"}},{"name":"b"}]`,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusOK,
+			imported:    2,
+		},
+		{
+			desc:        "import things as CSV",
+			req:         "id,name,key,metadata\nc,c,key-c,\"{\"\"test\"\":\"\"data\"\"}\"\n,d,,\n",
+			contentType: csvContentType,
+			auth:        token,
+			status:      http.StatusOK,
+			imported:    2,
+		},
+		{
+			desc:        "import empty JSON snapshot",
+			req:         "[]",
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusOK,
+			imported:    0,
+		},
+		{
+			desc:        "import things with invalid token",
+			req:         `[{"id":"a","name":"a","key":"key-a","metadata":{"test":"data"}},{"name":"b"}]`,
+			contentType: contentType,
+			auth:        wrongValue,
+			status:      http.StatusForbidden,
+		},
+		{
+			desc:        "import things with empty token",
+			req:         `[{"id":"a","name":"a","key":"key-a","metadata":{"test":"data"}},{"name":"b"}]`,
+			contentType: contentType,
+			auth:        "",
+			status:      http.StatusForbidden,
+		},
+		{
+			desc:        "import things with invalid JSON snapshot",
+			req:         "{}",
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "import things with malformed JSON snapshot",
+			req:         "[{",
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "import things with empty request",
+			req:         "",
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "import things with invalid CSV header",
+			req:         "a,b,c,d\n",
+			contentType: csvContentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "import things with malformed CSV row",
+			req:         "id,name,key,metadata\nc,c,key-c,\"{\"\"test\"\":\"\"data\"\"}\"\n,d,,\n" + "x\n",
+			contentType: csvContentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "import things with unsupported content type",
+			req:         `[{"id":"a","name":"a","key":"key-a","metadata":{"test":"data"}},{"name":"b"}]`,
+			contentType: "application/xml",
+			auth:        token,
+			status:      http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/things/import", ts.URL),
+			contentType: tc.contentType,
+			token:       tc.auth,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		if tc.status != http.StatusOK {
+			continue
+		}
+
+		var body importRes
+		err = json.NewDecoder(res.Body).Decode(&body)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.imported, body.Imported, fmt.Sprintf("%s: expected %d imported things got %d", tc.desc, tc.imported, body.Imported))
+	}
+}
+
+func TestCreateChannel(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	data := toJSON(channel)
+
+	th := channel
+	th.Name = invalidName
+	invalidData := toJSON(th)
+
+	cases := []struct {
+		desc        string
+		req         string
+		contentType string
+		auth        string
+		status      int
+		location    string
+	}{
+		{
+			desc:        "create new channel",
+			req:         data,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusCreated,
+			location:    "/channels/1",
+		},
+		{
+			desc:        "create new channel with invalid token",
+			req:         data,
+			contentType: contentType,
+			auth:        wrongValue,
+			status:      http.StatusForbidden,
+			location:    "",
+		},
+		{
+			desc:        "create new channel with empty token",
+			req:         data,
+			contentType: contentType,
+			auth:        "",
+			status:      http.StatusForbidden,
+			location:    "",
+		},
+		{
+			desc:        "create new channel with invalid data format",
+			req:         "{",
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+			location:    "",
+		},
+		{
+			desc:        "create new channel with empty JSON request",
+			req:         "{}",
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusCreated,
+			location:    "/channels/2",
+		},
+		{
+			desc:        "create new channel with empty request",
+			req:         "",
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+			location:    "",
+		},
+		{
+			desc:        "create new channel without content type",
+			req:         data,
+			contentType: "",
+			auth:        token,
+			status:      http.StatusUnsupportedMediaType,
+			location:    "",
+		},
+		{
+			desc:        "create new channel with invalid name",
+			req:         invalidData,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+			location:    "",
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/channels", ts.URL),
+			contentType: tc.contentType,
+			token:       tc.auth,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+
+		location := res.Header.Get("Location")
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		assert.Equal(t, tc.location, location, fmt.Sprintf("%s: expected location %s got %s", tc.desc, tc.location, location))
+	}
+}
+
+func TestUpdateChannel(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	sch, _ := svc.CreateChannel(context.Background(), token, channel)
+
+	ch := channel
+	ch.Name = "updated_channel"
+	updateData := toJSON(ch)
+
+	ch.Name = invalidName
+	invalidData := toJSON(ch)
+
+	cases := []struct {
+		desc        string
+		req         string
+		id          string
+		contentType string
+		auth        string
+		status      int
+	}{
+		{
+			desc:        "update existing channel",
+			req:         updateData,
+			id:          sch.ID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusOK,
+		},
+		{
+			desc:        "update non-existing channel",
+			req:         updateData,
+			id:          strconv.FormatUint(wrongID, 10),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusNotFound,
+		},
+		{
+			desc:        "update channel with invalid id",
+			req:         updateData,
+			id:          "invalid",
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusNotFound,
+		},
+		{
+			desc:        "update channel with invalid token",
+			req:         updateData,
+			id:          sch.ID,
+			contentType: contentType,
+			auth:        wrongValue,
+			status:      http.StatusForbidden,
+		},
+		{
+			desc:        "update channel with empty token",
+			req:         updateData,
+			id:          sch.ID,
+			contentType: contentType,
+			auth:        "",
+			status:      http.StatusForbidden,
+		},
+		{
+			desc:        "update channel with invalid data format",
+			req:         "}",
+			id:          sch.ID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "update channel with empty JSON object",
+			req:         "{}",
+			id:          sch.ID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusOK,
+		},
+		{
+			desc:        "update channel with empty request",
+			req:         "",
+			id:          sch.ID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "update channel with missing content type",
+			req:         updateData,
+			id:          sch.ID,
+			contentType: "",
+			auth:        token,
+			status:      http.StatusUnsupportedMediaType,
+		},
+		{
+			desc:        "update channel with invalid name",
+			req:         invalidData,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPut,
+			url:         fmt.Sprintf("%s/channels/%s", ts.URL, tc.id),
+			contentType: tc.contentType,
+			token:       tc.auth,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestViewChannel(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	sch, _ := svc.CreateChannel(context.Background(), token, channel)
+
+	sth, _ := svc.AddThing(context.Background(), token, thing)
+	svc.Connect(context.Background(), token, sch.ID, sth.ID, nil)
+
+	chres := channelRes{
+		ID:       sch.ID,
+		Name:     sch.Name,
+		Metadata: sch.Metadata,
+	}
+	data := toJSON(chres)
+
+	cases := []struct {
+		desc   string
+		id     string
+		auth   string
+		status int
+		res    string
+	}{
+		{
+			desc:   "view existing channel",
+			id:     sch.ID,
+			auth:   token,
+			status: http.StatusOK,
+			res:    data,
+		},
+		{
+			desc:   "view non-existent channel",
+			id:     strconv.FormatUint(wrongID, 10),
+			auth:   token,
+			status: http.StatusNotFound,
+			res:    "",
+		},
+		{
+			desc:   "view channel with invalid token",
+			id:     sch.ID,
+			auth:   wrongValue,
+			status: http.StatusForbidden,
+			res:    "",
+		},
+		{
+			desc:   "view channel with empty token",
+			id:     sch.ID,
+			auth:   "",
+			status: http.StatusForbidden,
+			res:    "",
+		},
+		{
+			desc:   "view channel with invalid id",
+			id:     "invalid",
+			auth:   token,
+			status: http.StatusNotFound,
+			res:    "",
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/channels/%s", ts.URL, tc.id),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		data, err := ioutil.ReadAll(res.Body)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		body := strings.Trim(string(data), "\n")
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		assert.Equal(t, tc.res, body, fmt.Sprintf("%s: expected body %s got %s", tc.desc, tc.res, body))
+	}
+}
+
+func TestChannelRetention(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	cases := []struct {
+		desc      string
+		req       string
+		status    int
+		retention string
+	}{
+		{
+			desc:      "create channel with retention period and message count",
+			req:       `{"name":"test","retention":{"period":86400,"messages":1000}}`,
+			status:    http.StatusCreated,
+			retention: `{"period":86400,"messages":1000}`,
+		},
+		{
+			desc:      "create channel with retention message count",
+			req:       `{"name":"test","retention":{"messages":1000}}`,
+			status:    http.StatusCreated,
+			retention: `{"messages":1000}`,
+		},
+		{
+			desc:      "create channel without retention",
+			req:       `{"name":"test"}`,
+			status:    http.StatusCreated,
+			retention: "",
+		},
+		{
+			desc:      "create channel with negative retention period",
+			req:       `{"name":"test","retention":{"period":-1}}`,
+			status:    http.StatusBadRequest,
+			retention: "",
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/channels", ts.URL),
+			contentType: contentType,
+			token:       token,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		if res.StatusCode != http.StatusCreated {
+			continue
+		}
+
+		req = testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s%s", ts.URL, res.Header.Get("Location")),
+			token:  token,
+		}
+		res, err = req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+
+		var body struct {
+			Retention json.RawMessage `json:"retention"`
+		}
+		err = json.NewDecoder(res.Body).Decode(&body)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.retention, string(body.Retention), fmt.Sprintf("%s: expected retention %s got %s", tc.desc, tc.retention, body.Retention))
+	}
+}
+
+func TestChannelRegion(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	cases := []struct {
+		desc   string
+		req    string
+		status int
+		region string
+	}{
+		{
+			desc:   "create channel with region",
+			req:    `{"name":"test","region":"eu-west-1"}`,
+			status: http.StatusCreated,
+			region: "eu-west-1",
+		},
+		{
+			desc:   "create channel without region",
+			req:    `{"name":"test"}`,
+			status: http.StatusCreated,
+			region: "",
+		},
+		{
+			desc:   "create channel with invalid region",
+			req:    `{"name":"test","region":"EU West"}`,
+			status: http.StatusBadRequest,
+			region: "",
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/channels", ts.URL),
+			contentType: contentType,
+			token:       token,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		if res.StatusCode != http.StatusCreated {
+			continue
+		}
+
+		req = testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s%s", ts.URL, res.Header.Get("Location")),
+			token:  token,
+		}
+		res, err = req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+
+		var body struct {
+			Region string `json:"region"`
+		}
+		err = json.NewDecoder(res.Body).Decode(&body)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.region, body.Region, fmt.Sprintf("%s: expected region %s got %s", tc.desc, tc.region, body.Region))
+	}
+}
+
+func TestChannelOrdering(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	cases := []struct {
+		desc    string
+		req     string
+		status  int
+		ordered bool
+	}{
+		{
+			desc:    "create ordered channel",
+			req:     `{"name":"test","ordered":true}`,
+			status:  http.StatusCreated,
+			ordered: true,
+		},
+		{
+			desc:    "create unordered channel",
+			req:     `{"name":"test"}`,
+			status:  http.StatusCreated,
+			ordered: false,
+		},
+		{
+			desc:    "create channel with invalid ordering flag",
+			req:     `{"name":"test","ordered":"yes"}`,
+			status:  http.StatusBadRequest,
+			ordered: false,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/channels", ts.URL),
+			contentType: contentType,
+			token:       token,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		if res.StatusCode != http.StatusCreated {
+			continue
+		}
+
+		req = testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s%s", ts.URL, res.Header.Get("Location")),
+			token:  token,
+		}
+		res, err = req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+
+		var body struct {
+			Ordered bool `json:"ordered"`
+		}
+		err = json.NewDecoder(res.Body).Decode(&body)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.ordered, body.Ordered, fmt.Sprintf("%s: expected ordered %t got %t", tc.desc, tc.ordered, body.Ordered))
+	}
+}
+
+func TestChannelAlias(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	cases := []struct {
+		desc   string
+		req    string
+		status int
+		alias  string
+	}{
+		{
+			desc:   "create channel with alias",
+			req:    `{"name":"test","alias":"temp"}`,
+			status: http.StatusCreated,
+			alias:  "temp",
+		},
+		{
+			desc:   "create channel without alias",
+			req:    `{"name":"test"}`,
+			status: http.StatusCreated,
+			alias:  "",
+		},
+		{
+			desc:   "create channel with existing alias",
+			req:    `{"name":"test","alias":"temp"}`,
+			status: http.StatusUnprocessableEntity,
+			alias:  "",
+		},
+		{
+			desc:   "create channel with invalid alias",
+			req:    `{"name":"test","alias":"temp/room"}`,
+			status: http.StatusBadRequest,
+			alias:  "",
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/channels", ts.URL),
+			contentType: contentType,
+			token:       token,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		if res.StatusCode != http.StatusCreated {
+			continue
+		}
+
+		req = testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s%s", ts.URL, res.Header.Get("Location")),
+			token:  token,
+		}
+		res, err = req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+
+		var body struct {
+			Alias string `json:"alias"`
+		}
+		err = json.NewDecoder(res.Body).Decode(&body)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.alias, body.Alias, fmt.Sprintf("%s: expected alias %s got %s", tc.desc, tc.alias, body.Alias))
+	}
+}
+
+func TestCreateDirectChannel(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	th1, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	th2, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	th3, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	direct, err := svc.CreateDirectChannel(context.Background(), token, th1.ID, th2.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	location := fmt.Sprintf("/channels/%s", direct.ID)
+
+	cases := []struct {
+		desc        string
+		req         string
+		contentType string
+		auth        string
+		status      int
+		location    string
+	}{
+		{
+			desc:        "create direct channel for paired things",
+			req:         toJSON(map[string][]string{"things": {th2.ID, th1.ID}}),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusCreated,
+			location:    location,
+		},
+		{
+			desc:        "create direct channel for single thing",
+			req:         toJSON(map[string][]string{"things": {th1.ID}}),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+			location:    "",
+		},
+		{
+			desc:        "create direct channel for the same thing",
+			req:         toJSON(map[string][]string{"things": {th1.ID, th1.ID}}),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+			location:    "",
+		},
+		{
+			desc:        "create direct channel for non-existing thing",
+			req:         toJSON(map[string][]string{"things": {th1.ID, wrongValue}}),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusNotFound,
+			location:    "",
+		},
+		{
+			desc:        "create direct channel with invalid token",
+			req:         toJSON(map[string][]string{"things": {th1.ID, th2.ID}}),
+			contentType: contentType,
+			auth:        wrongValue,
+			status:      http.StatusForbidden,
+			location:    "",
+		},
+		{
+			desc:        "create direct channel without content type",
+			req:         toJSON(map[string][]string{"things": {th1.ID, th2.ID}}),
+			contentType: "",
+			auth:        token,
+			status:      http.StatusUnsupportedMediaType,
+			location:    "",
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/channels/direct", ts.URL),
+			contentType: tc.contentType,
+			token:       tc.auth,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+
+		location := res.Header.Get("Location")
+		assert.Equal(t, tc.location, location, fmt.Sprintf("%s: expected location %s got %s", tc.desc, tc.location, location))
+	}
+
+	req := testRequest{
+		client:      ts.Client(),
+		method:      http.MethodPut,
+		url:         fmt.Sprintf("%s/channels/%s/things/%s", ts.URL, direct.ID, th3.ID),
+		contentType: contentType,
+		token:       token,
+	}
+	res, err := req.make()
+	assert.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	assert.Equal(t, http.StatusConflict, res.StatusCode, fmt.Sprintf("connecting other thing to direct channel: expected status code %d got %d", http.StatusConflict, res.StatusCode))
+
+	req = testRequest{
+		client: ts.Client(),
+		method: http.MethodGet,
+		url:    fmt.Sprintf("%s%s", ts.URL, location),
+		token:  token,
+	}
+	res, err = req.make()
+	assert.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+
+	var body struct {
+		Type  string   `json:"type"`
+		Peers []string `json:"peers"`
+	}
+	err = json.NewDecoder(res.Body).Decode(&body)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	assert.Equal(t, things.DirectChannel, body.Type, fmt.Sprintf("expected type %s got %s", things.DirectChannel, body.Type))
+	assert.ElementsMatch(t, []string{th1.ID, th2.ID}, body.Peers, fmt.Sprintf("expected peers %s and %s got %v", th1.ID, th2.ID, body.Peers))
+}
+
+func TestListChannels(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	channels := []channelRes{}
+	for i := 0; i < 101; i++ {
+		sch, err := svc.CreateChannel(context.Background(), token, channel)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		sth, err := svc.AddThing(context.Background(), token, thing)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		svc.Connect(context.Background(), token, sch.ID, sth.ID, nil)
+
+		chres := channelRes{
+			ID:       sch.ID,
+			Name:     sch.Name,
+			Metadata: sch.Metadata,
+		}
+		channels = append(channels, chres)
+	}
+	channelURL := fmt.Sprintf("%s/channels", ts.URL)
+
+	cases := []struct {
+		desc   string
+		auth   string
+		status int
+		url    string
+		res    []channelRes
+	}{
+		{
+			desc:   "get a list of channels",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s?offset=%d&limit=%d", channelURL, 0, 6),
+			res:    channels[0:6],
+		},
+		{
+			desc:   "get a list of channels with invalid token",
+			auth:   wrongValue,
+			status: http.StatusForbidden,
+			url:    fmt.Sprintf("%s?offset=%d&limit=%d", channelURL, 0, 1),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of channels with empty token",
+			auth:   "",
+			status: http.StatusForbidden,
+			url:    fmt.Sprintf("%s?offset=%d&limit=%d", channelURL, 0, 1),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of channels with negative offset",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s?offset=%d&limit=%d", channelURL, -1, 5),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of channels with negative limit",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s?offset=%d&limit=%d", channelURL, -1, 5),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of channels with zero limit",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s?offset=%d&limit=%d", channelURL, 1, 0),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of channels with no offset provided",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s?limit=%d", channelURL, 5),
+			res:    channels[0:5],
+		},
+		{
+			desc:   "get a list of channels with no limit provided",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s?offset=%d", channelURL, 1),
+			res:    channels[1:11],
+		},
+		{
+			desc:   "get a list of channels with redundant query params",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s?offset=%d&limit=%d&value=something", channelURL, 0, 5),
+			res:    channels[0:5],
+		},
+		{
+			desc:   "get a list of channels with limit greater than max",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s?offset=%d&limit=%d", channelURL, 0, 110),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of channels with default URL",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s%s", channelURL, ""),
+			res:    channels[0:10],
+		},
+		{
+			desc:   "get a list of channels with invalid number of params",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s%s", channelURL, "?offset=4&limit=4&limit=5&offset=5"),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of channels with invalid offset",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s%s", channelURL, "?offset=e&limit=5"),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of channels with invalid limit",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s%s", channelURL, "?offset=5&limit=e"),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of channels with invalid cursor",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s?limit=%d&cursor=%s", channelURL, 5, "%25"),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of channels sorted by name in descending order",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s?limit=%d&order=%s&dir=%s", channelURL, 5, "name", "desc"),
+			res:    channels[0:5],
+		},
+		{
+			desc:   "get a list of channels with invalid order",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s?limit=%d&order=%s", channelURL, 5, "key"),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of channels with invalid direction",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s?limit=%d&dir=%s", channelURL, 5, "up"),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of channels filtered by metadata query",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s?limit=%d&metadata_query=%s&metadata_query=%s", channelURL, 5, "firmware.version%3C1.4.0", "type%3Dsensor"),
+			res:    channels[0:5],
+		},
+		{
+			desc:   "get a list of channels with invalid metadata query",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s?limit=%d&metadata_query=%s", channelURL, 5, "firmware..version%3C1.4.0"),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of channels with metadata query without operator",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s?limit=%d&metadata_query=%s", channelURL, 5, "firmware.version"),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of channels with invalid name",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s?offset=%d&limit=%d&name=%s", channelURL, 0, 10, invalidName),
+			res:    nil,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    tc.url,
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		var body channelsPageRes
+		json.NewDecoder(res.Body).Decode(&body)
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		assert.ElementsMatch(t, tc.res, body.Channels, fmt.Sprintf("%s: expected body %v got %v", tc.desc, tc.res, body.Channels))
+	}
+}
+
+func TestListChannelsByThing(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	sth, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	channels := []channelRes{}
+	for i := 0; i < 101; i++ {
+		sch, err := svc.CreateChannel(context.Background(), token, channel)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		err = svc.Connect(context.Background(), token, sch.ID, sth.ID, nil)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+		chres := channelRes{
+			ID:       sch.ID,
+			Name:     sch.Name,
+			Metadata: sch.Metadata,
+		}
+		channels = append(channels, chres)
+	}
+	channelURL := fmt.Sprintf("%s/things", ts.URL)
+
+	cases := []struct {
+		desc   string
+		auth   string
+		status int
+		url    string
+		res    []channelRes
+	}{
+		{
+			desc:   "get a list of channels by thing",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s/%s/channels?offset=%d&limit=%d", channelURL, sth.ID, 0, 6),
+			res:    channels[0:6],
+		},
+		{
+			desc:   "get a list of channels by thing with invalid token",
+			auth:   wrongValue,
+			status: http.StatusForbidden,
+			url:    fmt.Sprintf("%s/%s/channels?offset=%d&limit=%d", channelURL, sth.ID, 0, 1),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of channels by thing with empty token",
+			auth:   "",
+			status: http.StatusForbidden,
+			url:    fmt.Sprintf("%s/%s/channels?offset=%d&limit=%d", channelURL, sth.ID, 0, 1),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of channels by thing with negative offset",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s/%s/channels?offset=%d&limit=%d", channelURL, sth.ID, -1, 5),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of channels by thing with negative limit",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s/%s/channels?offset=%d&limit=%d", channelURL, sth.ID, -1, 5),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of channels by thing with zero limit",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s/%s/channels?offset=%d&limit=%d", channelURL, sth.ID, 1, 0),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of channels by thing with no offset provided",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s/%s/channels?limit=%d", channelURL, sth.ID, 5),
+			res:    channels[0:5],
+		},
+		{
+			desc:   "get a list of channels by thing with no limit provided",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s/%s/channels?offset=%d", channelURL, sth.ID, 1),
+			res:    channels[1:11],
+		},
+		{
+			desc:   "get a list of channels by thing with redundant query params",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s/%s/channels?offset=%d&limit=%d&value=something", channelURL, sth.ID, 0, 5),
+			res:    channels[0:5],
+		},
+		{
+			desc:   "get a list of channels by thing with limit greater than max",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s/%s/channels?offset=%d&limit=%d", channelURL, sth.ID, 0, 110),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of channels by thing with default URL",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s/%s/channels", channelURL, sth.ID),
+			res:    channels[0:10],
+		},
+		{
+			desc:   "get a list of channels by thing with invalid number of params",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s/%s/channels%s", channelURL, sth.ID, "?offset=4&limit=4&limit=5&offset=5"),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of channels by thing with invalid offset",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s/%s/channels%s", channelURL, sth.ID, "?offset=e&limit=5"),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of channels by thing with invalid limit",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s/%s/channels%s", channelURL, sth.ID, "?offset=5&limit=e"),
+			res:    nil,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    tc.url,
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		var body channelsPageRes
+		json.NewDecoder(res.Body).Decode(&body)
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		assert.ElementsMatch(t, tc.res, body.Channels, fmt.Sprintf("%s: expected body %v got %v", tc.desc, tc.res, body.Channels))
+	}
+}
+
+func TestRemoveChannel(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	sch, _ := svc.CreateChannel(context.Background(), token, channel)
+
+	cases := []struct {
+		desc   string
+		id     string
+		auth   string
+		status int
+	}{
+		{
+			desc:   "remove channel with invalid token",
+			id:     sch.ID,
+			auth:   wrongValue,
+			status: http.StatusForbidden,
+		},
+		{
+			desc:   "remove existing channel",
+			id:     sch.ID,
+			auth:   token,
+			status: http.StatusNoContent,
+		},
+		{
+			desc:   "remove removed channel",
+			id:     sch.ID,
+			auth:   token,
+			status: http.StatusNoContent,
+		},
+		{
+			desc:   "remove channel with invalid token",
+			id:     sch.ID,
+			auth:   wrongValue,
+			status: http.StatusForbidden,
+		},
+		{
+			desc:   "remove channel with empty token",
+			id:     sch.ID,
+			auth:   "",
+			status: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodDelete,
+			url:    fmt.Sprintf("%s/channels/%s", ts.URL, tc.id),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestShareChannel(t *testing.T) {
+	svc := newSharingService()
+	ts := newServer(svc)
+	defer ts.Close()
+
+	s, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	data := toJSON(map[string]string{"permission": things.ViewPermission})
+	invalidData := toJSON(map[string]string{"permission": wrongValue})
+
+	cases := []struct {
+		desc        string
+		id          string
+		group       string
+		req         string
+		contentType string
+		auth        string
+		status      int
+	}{
+		{
+			desc:        "share channel with group",
+			id:          s.ID,
+			group:       group,
+			req:         data,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusOK,
+		},
+		{
+			desc:        "share channel with invalid permission",
+			id:          s.ID,
+			group:       group,
+			req:         invalidData,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "share channel with empty request",
+			id:          s.ID,
+			group:       group,
+			req:         "",
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "share channel with group the user is not member of",
+			id:          s.ID,
+			group:       wrongValue,
+			req:         data,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusNotFound,
+		},
+		{
+			desc:        "share non-existent channel",
+			id:          strconv.FormatUint(wrongID, 10),
+			group:       group,
+			req:         data,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusNotFound,
+		},
+		{
+			desc:        "share channel with invalid token",
+			id:          s.ID,
+			group:       group,
+			req:         data,
+			contentType: contentType,
+			auth:        wrongValue,
+			status:      http.StatusForbidden,
+		},
+		{
+			desc:        "share channel with empty token",
+			id:          s.ID,
+			group:       group,
+			req:         data,
+			contentType: contentType,
+			auth:        "",
+			status:      http.StatusForbidden,
+		},
+		{
+			desc:        "share channel without content type",
+			id:          s.ID,
+			group:       group,
+			req:         data,
+			contentType: "",
+			auth:        token,
+			status:      http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPut,
+			url:         fmt.Sprintf("%s/channels/%s/shares/%s", ts.URL, tc.id, tc.group),
+			contentType: tc.contentType,
+			token:       tc.auth,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestShareChannelWithUser(t *testing.T) {
+	svc := newSharingService()
+	ts := newServer(svc)
+	defer ts.Close()
+
+	s, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	member := "member@example.com"
+	data := toJSON(map[string]string{"email": member, "permission": things.ViewPermission})
+	invalidData := toJSON(map[string]string{"email": member, "permission": wrongValue})
+
+	cases := []struct {
+		desc        string
+		method      string
+		url         string
+		req         string
+		contentType string
+		auth        string
+		status      int
+	}{
+		{
+			desc:        "share channel with user",
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/channels/%s/share", ts.URL, s.ID),
+			req:         data,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusOK,
+		},
+		{
+			desc:        "share channel with invalid permission",
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/channels/%s/share", ts.URL, s.ID),
+			req:         invalidData,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "share non-existent channel",
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/channels/%d/share", ts.URL, wrongID),
+			req:         data,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusNotFound,
+		},
+		{
+			desc:        "share channel with invalid token",
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/channels/%s/share", ts.URL, s.ID),
+			req:         data,
+			contentType: contentType,
+			auth:        wrongValue,
+			status:      http.StatusForbidden,
+		},
+		{
+			desc:   "unshare channel with user",
+			method: http.MethodDelete,
+			url:    fmt.Sprintf("%s/channels/%s/share/%s", ts.URL, s.ID, member),
+			auth:   token,
+			status: http.StatusNoContent,
+		},
+		{
+			desc:   "unshare channel with invalid token",
+			method: http.MethodDelete,
+			url:    fmt.Sprintf("%s/channels/%s/share/%s", ts.URL, s.ID, member),
+			auth:   wrongValue,
+			status: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      tc.method,
+			url:         tc.url,
+			contentType: tc.contentType,
+			token:       tc.auth,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestUnshareChannel(t *testing.T) {
+	svc := newSharingService()
+	ts := newServer(svc)
+	defer ts.Close()
+
+	s, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = svc.ShareChannel(context.Background(), token, s.ID, group, things.ViewPermission)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		id     string
+		auth   string
+		status int
+	}{
+		{
+			desc:   "unshare shared channel",
+			id:     s.ID,
+			auth:   token,
+			status: http.StatusNoContent,
+		},
+		{
+			desc:   "unshare non-existent channel",
+			id:     strconv.FormatUint(wrongID, 10),
+			auth:   token,
+			status: http.StatusNoContent,
+		},
+		{
+			desc:   "unshare channel with invalid token",
+			id:     s.ID,
+			auth:   wrongValue,
+			status: http.StatusForbidden,
+		},
+		{
+			desc:   "unshare channel with empty token",
+			id:     s.ID,
+			auth:   "",
+			status: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodDelete,
+			url:    fmt.Sprintf("%s/channels/%s/shares/%s", ts.URL, tc.id, group),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestExportChannels(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	n := 3
+	for i := 0; i < n; i++ {
+		_, err := svc.CreateChannel(context.Background(), token, channel)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	cases := []struct {
+		desc        string
+		format      string
+		auth        string
+		status      int
+		contentType string
+		size        int
+	}{
+		{
+			desc:        "export channels as JSON by default",
+			format:      "",
+			auth:        token,
+			status:      http.StatusOK,
+			contentType: contentType,
+			size:        n,
+		},
+		{
+			desc:        "export channels as JSON",
+			format:      "?format=json",
+			auth:        token,
+			status:      http.StatusOK,
+			contentType: contentType,
+			size:        n,
+		},
+		{
+			desc:        "export channels as CSV",
+			format:      "?format=csv",
+			auth:        token,
+			status:      http.StatusOK,
+			contentType: csvContentType,
+			size:        n,
+		},
+		{
+			desc:        "export channels with unsupported format",
+			format:      "?format=xml",
+			auth:        token,
+			status:      http.StatusBadRequest,
+			contentType: contentType,
+		},
+		{
+			desc:        "export channels with invalid token",
+			format:      "",
+			auth:        wrongValue,
+			status:      http.StatusForbidden,
+			contentType: contentType,
+		},
+		{
+			desc:        "export channels with empty token",
+			format:      "",
+			auth:        "",
+			status:      http.StatusForbidden,
+			contentType: contentType,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/channels/export%s", ts.URL, tc.format),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		assert.Equal(t, tc.contentType, res.Header.Get("Content-Type"), fmt.Sprintf("%s: expected content type %s got %s", tc.desc, tc.contentType, res.Header.Get("Content-Type")))
+		if tc.status != http.StatusOK {
+			continue
+		}
+
+		if tc.contentType == csvContentType {
+			rows, err := csv.NewReader(res.Body).ReadAll()
+			assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+			require.Equal(t, tc.size+1, len(rows), fmt.Sprintf("%s: expected %d rows got %d", tc.desc, tc.size+1, len(rows)))
+			assert.Equal(t, []string{"id", "name", "metadata"}, rows[0], fmt.Sprintf("%s: expected header %v got %v", tc.desc, []string{"id", "name", "metadata"}, rows[0]))
+			continue
+		}
+
+		var body []map[string]interface{}
+		err = json.NewDecoder(res.Body).Decode(&body)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.size, len(body), fmt.Sprintf("%s: expected %d records got %d", tc.desc, tc.size, len(body)))
+	}
+}
+
+func TestImportChannels(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	cases := []struct {
+		desc        string
+		req         string
+		contentType string
+		auth        string
+		status      int
+		imported    int
+	}{
+		{
+			desc:        "import channels as JSON",
+			req:         `[{"id":"a","name":"a","metadata":{"test":"data"}},{"name":"b"}]`,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusOK,
+			imported:    2,
+		},
+		{
+			desc:        "import channels as CSV",
+			req:         "id,name,metadata\nc,c,\"{\"\"test\"\":\"\"data\"\"}\"\n,d,\n",
+			contentType: csvContentType,
+			auth:        token,
+			status:      http.StatusOK,
+			imported:    2,
+		},
+		{
+			desc:        "import empty JSON snapshot",
+			req:         "[]",
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusOK,
+			imported:    0,
+		},
+		{
+			desc:        "import channels with invalid token",
+			req:         `[{"id":"a","name":"a","metadata":{"test":"data"}},{"name":"b"}]`,
+			contentType: contentType,
+			auth:        wrongValue,
+			status:      http.StatusForbidden,
+		},
+		{
+			desc:        "import channels with empty token",
+			req:         `[{"id":"a","name":"a","metadata":{"test":"data"}},{"name":"b"}]`,
+			contentType: contentType,
+			auth:        "",
+			status:      http.StatusForbidden,
+		},
+		{
+			desc:        "import channels with invalid JSON snapshot",
+			req:         "{}",
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "import channels with malformed JSON snapshot",
+			req:         "[{",
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "import channels with empty request",
+			req:         "",
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "import channels with invalid CSV header",
+			req:         "a,b,c,d\n",
+			contentType: csvContentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "import channels with malformed CSV row",
+			req:         "id,name,metadata\nc,c,\"{\"\"test\"\":\"\"data\"\"}\"\n,d,\n" + "x\n",
+			contentType: csvContentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "import channels with unsupported content type",
+			req:         `[{"id":"a","name":"a","metadata":{"test":"data"}},{"name":"b"}]`,
+			contentType: "application/xml",
+			auth:        token,
+			status:      http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/channels/import", ts.URL),
+			contentType: tc.contentType,
+			token:       tc.auth,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		if tc.status != http.StatusOK {
+			continue
+		}
+
+		var body importRes
+		err = json.NewDecoder(res.Body).Decode(&body)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.imported, body.Imported, fmt.Sprintf("%s: expected %d imported channels got %d", tc.desc, tc.imported, body.Imported))
+	}
+}
+
+func TestConnect(t *testing.T) {
+	otherToken := "other_token"
+	otherEmail := "other_user@example.com"
+	svc := newService(map[string]string{
+		token:      email,
+		otherToken: otherEmail,
+	})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	ath, _ := svc.AddThing(context.Background(), token, thing)
+	ach, _ := svc.CreateChannel(context.Background(), token, channel)
+	bch, _ := svc.CreateChannel(context.Background(), otherToken, channel)
+
+	cases := []struct {
+		desc    string
+		chanID  string
+		thingID string
+		auth    string
+		status  int
+	}{
+		{
+			desc:    "connect existing thing to existing channel",
+			chanID:  ach.ID,
+			thingID: ath.ID,
+			auth:    token,
+			status:  http.StatusOK,
+		},
+		{
+			desc:    "connect existing thing to non-existent channel",
+			chanID:  strconv.FormatUint(wrongID, 10),
+			thingID: ath.ID,
+			auth:    token,
+			status:  http.StatusNotFound,
+		},
+		{
+			desc:    "connect non-existing thing to existing channel",
+			chanID:  ach.ID,
+			thingID: strconv.FormatUint(wrongID, 10),
+			auth:    token,
+			status:  http.StatusNotFound,
+		},
+		{
+			desc:    "connect existing thing to channel with invalid id",
+			chanID:  "invalid",
+			thingID: ath.ID,
+			auth:    token,
+			status:  http.StatusNotFound,
+		},
+		{
+			desc:    "connect thing with invalid id to existing channel",
+			chanID:  ach.ID,
+			thingID: "invalid",
+			auth:    token,
+			status:  http.StatusNotFound,
+		},
+		{
+			desc:    "connect existing thing to existing channel with invalid token",
+			chanID:  ach.ID,
+			thingID: ath.ID,
+			auth:    wrongValue,
+			status:  http.StatusForbidden,
+		},
+		{
+			desc:    "connect existing thing to existing channel with empty token",
+			chanID:  ach.ID,
+			thingID: ath.ID,
+			auth:    "",
+			status:  http.StatusForbidden,
+		},
+		{
+			desc:    "connect thing from owner to channel of other user",
+			chanID:  bch.ID,
+			thingID: ath.ID,
+			auth:    token,
+			status:  http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodPut,
+			url:    fmt.Sprintf("%s/channels/%s/things/%s", ts.URL, tc.chanID, tc.thingID),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestDisconnnect(t *testing.T) {
+	otherToken := "other_token"
+	otherEmail := "other_user@example.com"
+	svc := newService(map[string]string{
+		token:      email,
+		otherToken: otherEmail,
+	})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	ath, _ := svc.AddThing(context.Background(), token, thing)
+	ach, _ := svc.CreateChannel(context.Background(), token, channel)
+	svc.Connect(context.Background(), token, ach.ID, ath.ID, nil)
+	bch, _ := svc.CreateChannel(context.Background(), otherToken, channel)
+
+	cases := []struct {
+		desc    string
+		chanID  string
+		thingID string
+		auth    string
+		status  int
+	}{
+		{
+			desc:    "disconnect connected thing from channel",
+			chanID:  ach.ID,
+			thingID: ath.ID,
+			auth:    token,
+			status:  http.StatusNoContent,
+		},
+		{
+			desc:    "disconnect non-connected thing from channel",
+			chanID:  ach.ID,
+			thingID: ath.ID,
+			auth:    token,
+			status:  http.StatusNotFound,
+		},
+		{
+			desc:    "disconnect non-existent thing from channel",
+			chanID:  ach.ID,
+			thingID: strconv.FormatUint(wrongID, 10),
+			auth:    token,
+			status:  http.StatusNotFound,
+		},
+		{
+			desc:    "disconnect thing from non-existent channel",
+			chanID:  strconv.FormatUint(wrongID, 10),
+			thingID: ath.ID,
+			auth:    token,
+			status:  http.StatusNotFound,
+		},
+		{
+			desc:    "disconnect thing from channel with invalid token",
+			chanID:  ach.ID,
+			thingID: ath.ID,
+			auth:    wrongValue,
+			status:  http.StatusForbidden,
+		},
+		{
+			desc:    "disconnect thing from channel with empty token",
+			chanID:  ach.ID,
+			thingID: ath.ID,
+			auth:    "",
+			status:  http.StatusForbidden,
+		},
+		{
+			desc:    "disconnect owner's thing from someone elses channel",
+			chanID:  bch.ID,
+			thingID: ath.ID,
+			auth:    token,
+			status:  http.StatusNotFound,
+		},
+		{
+			desc:    "disconnect thing with invalid id from channel",
+			chanID:  ach.ID,
+			thingID: "invalid",
+			auth:    token,
+			status:  http.StatusNotFound,
+		},
+		{
+			desc:    "disconnect thing from channel with invalid id",
+			chanID:  "invalid",
+			thingID: ath.ID,
+			auth:    token,
+			status:  http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodDelete,
+			url:    fmt.Sprintf("%s/channels/%s/things/%s", ts.URL, tc.chanID, tc.thingID),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestExportConnections(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	n := 3
+	ch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	for i := 0; i < n; i++ {
+		th, err := svc.AddThing(context.Background(), token, thing)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		err = svc.Connect(context.Background(), token, ch.ID, th.ID, nil)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	cases := []struct {
+		desc        string
+		format      string
+		auth        string
+		status      int
+		contentType string
+		size        int
+	}{
+		{
+			desc:        "export connections as JSON by default",
+			format:      "",
+			auth:        token,
+			status:      http.StatusOK,
+			contentType: contentType,
+			size:        n,
+		},
+		{
+			desc:        "export connections as JSON",
+			format:      "?format=json",
+			auth:        token,
+			status:      http.StatusOK,
+			contentType: contentType,
+			size:        n,
+		},
+		{
+			desc:        "export connections as CSV",
+			format:      "?format=csv",
+			auth:        token,
+			status:      http.StatusOK,
+			contentType: csvContentType,
+			size:        n,
+		},
+		{
+			desc:        "export connections with unsupported format",
+			format:      "?format=xml",
+			auth:        token,
+			status:      http.StatusBadRequest,
+			contentType: contentType,
+		},
+		{
+			desc:        "export connections with invalid token",
+			format:      "",
+			auth:        wrongValue,
+			status:      http.StatusForbidden,
+			contentType: contentType,
+		},
+		{
+			desc:        "export connections with empty token",
+			format:      "",
+			auth:        "",
+			status:      http.StatusForbidden,
+			contentType: contentType,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/connections/export%s", ts.URL, tc.format),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		assert.Equal(t, tc.contentType, res.Header.Get("Content-Type"), fmt.Sprintf("%s: expected content type %s got %s", tc.desc, tc.contentType, res.Header.Get("Content-Type")))
+		if tc.status != http.StatusOK {
+			continue
+		}
+
+		if tc.contentType == csvContentType {
+			rows, err := csv.NewReader(res.Body).ReadAll()
+			assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+			require.Equal(t, tc.size+1, len(rows), fmt.Sprintf("%s: expected %d rows got %d", tc.desc, tc.size+1, len(rows)))
+			assert.Equal(t, []string{"channel_id", "thing_id", "actions"}, rows[0], fmt.Sprintf("%s: expected header %v got %v", tc.desc, []string{"channel_id", "thing_id", "actions"}, rows[0]))
+			continue
+		}
+
+		var body []map[string]interface{}
+		err = json.NewDecoder(res.Body).Decode(&body)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.size, len(body), fmt.Sprintf("%s: expected %d records got %d", tc.desc, tc.size, len(body)))
+	}
+}
+
+func TestImportConnections(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	ths := []things.Thing{}
+	for i := 0; i < 2; i++ {
+		th, err := svc.AddThing(context.Background(), token, thing)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		ths = append(ths, th)
+	}
+	ch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	data := toJSON([]map[string]interface{}{
+		{"channel_id": ch.ID, "thing_id": ths[0].ID},
+		{"channel_id": ch.ID, "thing_id": ths[1].ID, "actions": []string{"publish"}},
+	})
+	csvData := fmt.Sprintf("channel_id,thing_id,actions\n%s,%s,\n%s,%s,publish subscribe\n", ch.ID, ths[0].ID, ch.ID, ths[1].ID)
+
+	cases := []struct {
+		desc        string
+		req         string
+		contentType string
+		auth        string
+		status      int
+		imported    int
+	}{
+		{
+			desc:        "import connections as JSON",
+			req:         data,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusOK,
+			imported:    2,
+		},
+		{
+			desc:        "import connections as CSV",
+			req:         csvData,
+			contentType: csvContentType,
+			auth:        token,
+			status:      http.StatusOK,
+			imported:    2,
+		},
+		{
+			desc:        "import empty JSON snapshot",
+			req:         "[]",
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusOK,
+			imported:    0,
+		},
+		{
+			desc:        "import connections with invalid token",
+			req:         data,
+			contentType: contentType,
+			auth:        wrongValue,
+			status:      http.StatusForbidden,
+		},
+		{
+			desc:        "import connections with empty token",
+			req:         data,
+			contentType: contentType,
+			auth:        "",
+			status:      http.StatusForbidden,
+		},
+		{
+			desc:        "import connections with invalid JSON snapshot",
+			req:         "{}",
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "import connections with malformed JSON snapshot",
+			req:         "[{",
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "import connections with empty request",
+			req:         "",
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "import connections with invalid CSV header",
+			req:         "a,b,c,d\n",
+			contentType: csvContentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "import connections with malformed CSV row",
+			req:         csvData + "x\n",
+			contentType: csvContentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "import connections with unsupported content type",
+			req:         data,
+			contentType: "application/xml",
+			auth:        token,
+			status:      http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/connections/import", ts.URL),
+			contentType: tc.contentType,
+			token:       tc.auth,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		if tc.status != http.StatusOK {
+			continue
+		}
+
+		var body importRes
+		err = json.NewDecoder(res.Body).Decode(&body)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.imported, body.Imported, fmt.Sprintf("%s: expected %d imported connections got %d", tc.desc, tc.imported, body.Imported))
+	}
+}
+
+const (
+	adminEmail = "admin@example.com"
+	adminToken = "admin-token"
+)
+
+const (
+	newEmail      = "new@example.com"
+	newToken      = "new-token"
+	disabledEmail = "disabled@example.com"
+	disabledToken = "disabled-token"
+)
+
+func newQuotaService(quota things.Quota) things.Service {
+	tokens := map[string]string{token: email, adminToken: adminEmail, newToken: newEmail, disabledToken: disabledEmail}
+	users := mocks.NewUsersServiceWithDisabled(tokens, []string{disabledEmail})
+	conns := make(chan mocks.Connection)
+	thingsRepo := mocks.NewThingRepository(conns)
+	channelsRepo := mocks.NewChannelRepository(thingsRepo, conns)
+	quotasRepo := mocks.NewQuotaRepository(thingsRepo, channelsRepo)
+	schemasRepo := mocks.NewSchemaRepository()
+	chanCache := mocks.NewChannelCache()
+	thingCache := mocks.NewThingCache()
+	idp := mocks.NewIdentityProvider()
+
+	return things.New(users, thingsRepo, channelsRepo, quotasRepo, schemasRepo, chanCache, thingCache, idp, nil, quota, []string{adminEmail}, keyGrace, 0, nil, nil, nil)
+}
+
+func TestUpdateQuota(t *testing.T) {
+	svc := newQuotaService(things.Quota{})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	data := `{"things":1,"channels":2,"connections":3}`
+
+	cases := []struct {
+		desc        string
+		req         string
+		owner       string
+		contentType string
+		auth        string
+		status      int
+	}{
+		{
+			desc:        "update quota",
+			req:         data,
+			owner:       email,
+			contentType: contentType,
+			auth:        adminToken,
+			status:      http.StatusOK,
+		},
+		{
+			desc:        "update quota as non-admin",
+			req:         data,
+			owner:       email,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusForbidden,
+		},
+		{
+			desc:        "update quota with invalid token",
+			req:         data,
+			owner:       email,
+			contentType: contentType,
+			auth:        wrongValue,
+			status:      http.StatusForbidden,
+		},
+		{
+			desc:        "update quota with empty token",
+			req:         data,
+			owner:       email,
+			contentType: contentType,
+			auth:        "",
+			status:      http.StatusForbidden,
+		},
+		{
+			desc:        "update quota with negative limit",
+			req:         `{"things":-1}`,
+			owner:       email,
+			contentType: contentType,
+			auth:        adminToken,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "update quota with too large limit",
+			req:         `{"things":18446744073709551615}`,
+			owner:       email,
+			contentType: contentType,
+			auth:        adminToken,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "update quota with invalid request format",
+			req:         "}",
+			owner:       email,
+			contentType: contentType,
+			auth:        adminToken,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "update quota with empty request",
+			req:         "",
+			owner:       email,
+			contentType: contentType,
+			auth:        adminToken,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "update quota without content type",
+			req:         data,
+			owner:       email,
+			contentType: "",
+			auth:        adminToken,
+			status:      http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPut,
+			url:         fmt.Sprintf("%s/quotas/%s", ts.URL, tc.owner),
+			contentType: tc.contentType,
+			token:       tc.auth,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
 
-	sth, _ := svc.AddThing(context.Background(), token, thing)
+func TestViewQuota(t *testing.T) {
+	svc := newQuotaService(things.Quota{Things: 10})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	_, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = svc.UpdateQuota(context.Background(), adminToken, things.Quota{Owner: adminEmail, Channels: 1})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
 		desc   string
-		id     string
+		owner  string
 		auth   string
 		status int
+		res    quotaRes
 	}{
 		{
-			desc:   "delete existing thing",
-			id:     sth.ID,
+			desc:   "view own default quota",
+			owner:  email,
 			auth:   token,
-			status: http.StatusNoContent,
+			status: http.StatusOK,
+			res:    quotaRes{Owner: email, Things: 10, Usage: usageRes{Things: 1}},
 		},
 		{
-			desc:   "delete non-existent thing",
-			id:     strconv.FormatUint(wrongID, 10),
+			desc:   "view quota of other owner as admin",
+			owner:  email,
+			auth:   adminToken,
+			status: http.StatusOK,
+			res:    quotaRes{Owner: email, Things: 10, Usage: usageRes{Things: 1}},
+		},
+		{
+			desc:   "view own overridden quota",
+			owner:  adminEmail,
+			auth:   adminToken,
+			status: http.StatusOK,
+			res:    quotaRes{Owner: adminEmail, Channels: 1},
+		},
+		{
+			desc:   "view quota of other owner as non-admin",
+			owner:  adminEmail,
 			auth:   token,
-			status: http.StatusNoContent,
+			status: http.StatusForbidden,
 		},
 		{
-			desc:   "delete thing with invalid token",
-			id:     sth.ID,
+			desc:   "view quota with invalid token",
+			owner:  email,
 			auth:   wrongValue,
 			status: http.StatusForbidden,
 		},
 		{
-			desc:   "delete thing with empty token",
-			id:     sth.ID,
+			desc:   "view quota with empty token",
+			owner:  email,
 			auth:   "",
 			status: http.StatusForbidden,
 		},
@@ -857,98 +4156,166 @@ func TestRemoveThing(t *testing.T) {
 	for _, tc := range cases {
 		req := testRequest{
 			client: ts.Client(),
-			method: http.MethodDelete,
-			url:    fmt.Sprintf("%s/things/%s", ts.URL, tc.id),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/quotas/%s", ts.URL, tc.owner),
 			token:  tc.auth,
 		}
 		res, err := req.make()
 		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
 		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+
+		var body quotaRes
+		json.NewDecoder(res.Body).Decode(&body)
+		assert.Equal(t, tc.res, body, fmt.Sprintf("%s: expected body %v got %v", tc.desc, tc.res, body))
 	}
 }
 
-func TestCreateChannel(t *testing.T) {
-	svc := newService(map[string]string{token: email})
+func TestRemoveQuota(t *testing.T) {
+	svc := newQuotaService(things.Quota{})
 	ts := newServer(svc)
 	defer ts.Close()
 
-	data := toJSON(channel)
+	err := svc.UpdateQuota(context.Background(), adminToken, things.Quota{Owner: email, Things: 1})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
-	th := channel
-	th.Name = invalidName
-	invalidData := toJSON(th)
+	cases := []struct {
+		desc   string
+		owner  string
+		auth   string
+		status int
+	}{
+		{
+			desc:   "remove quota as non-admin",
+			owner:  email,
+			auth:   token,
+			status: http.StatusForbidden,
+		},
+		{
+			desc:   "remove quota with invalid token",
+			owner:  email,
+			auth:   wrongValue,
+			status: http.StatusForbidden,
+		},
+		{
+			desc:   "remove quota with empty token",
+			owner:  email,
+			auth:   "",
+			status: http.StatusForbidden,
+		},
+		{
+			desc:   "remove quota",
+			owner:  email,
+			auth:   adminToken,
+			status: http.StatusNoContent,
+		},
+		{
+			desc:   "remove non-existing quota",
+			owner:  email,
+			auth:   adminToken,
+			status: http.StatusNoContent,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodDelete,
+			url:    fmt.Sprintf("%s/quotas/%s", ts.URL, tc.owner),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestTransferOwnership(t *testing.T) {
+	svc := newQuotaService(things.Quota{})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	_, err := svc.AddThing(context.Background(), disabledToken, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
 		desc        string
+		owner       string
 		req         string
 		contentType string
 		auth        string
 		status      int
-		location    string
 	}{
 		{
-			desc:        "create new channel",
-			req:         data,
+			desc:        "transfer ownership as non-admin",
+			owner:       disabledEmail,
+			req:         `{"owner": "new@example.com"}`,
 			contentType: contentType,
 			auth:        token,
-			status:      http.StatusCreated,
-			location:    "/channels/1",
-		},
-		{
-			desc:        "create new channel with invalid token",
-			req:         data,
-			contentType: contentType,
-			auth:        wrongValue,
 			status:      http.StatusForbidden,
-			location:    "",
 		},
 		{
-			desc:        "create new channel with empty token",
-			req:         data,
+			desc:        "transfer ownership with empty token",
+			owner:       disabledEmail,
+			req:         `{"owner": "new@example.com"}`,
 			contentType: contentType,
 			auth:        "",
 			status:      http.StatusForbidden,
-			location:    "",
 		},
 		{
-			desc:        "create new channel with invalid data format",
-			req:         "{",
+			desc:        "transfer ownership without new owner",
+			owner:       disabledEmail,
+			req:         `{}`,
 			contentType: contentType,
-			auth:        token,
+			auth:        adminToken,
 			status:      http.StatusBadRequest,
-			location:    "",
 		},
 		{
-			desc:        "create new channel with empty JSON request",
-			req:         "{}",
+			desc:        "transfer ownership to the same owner",
+			owner:       disabledEmail,
+			req:         fmt.Sprintf(`{"owner": "%s"}`, disabledEmail),
 			contentType: contentType,
-			auth:        token,
-			status:      http.StatusCreated,
-			location:    "/channels/2",
+			auth:        adminToken,
+			status:      http.StatusBadRequest,
 		},
 		{
-			desc:        "create new channel with empty request",
-			req:         "",
+			desc:        "transfer ownership with invalid request format",
+			owner:       disabledEmail,
+			req:         "{",
 			contentType: contentType,
-			auth:        token,
+			auth:        adminToken,
 			status:      http.StatusBadRequest,
-			location:    "",
 		},
 		{
-			desc:        "create new channel without content type",
-			req:         data,
+			desc:        "transfer ownership without content type",
+			owner:       disabledEmail,
+			req:         `{"owner": "new@example.com"}`,
 			contentType: "",
-			auth:        token,
+			auth:        adminToken,
 			status:      http.StatusUnsupportedMediaType,
-			location:    "",
 		},
 		{
-			desc:        "create new channel with invalid name",
-			req:         invalidData,
+			desc:        "transfer ownership of enabled owner",
+			owner:       email,
+			req:         `{"owner": "new@example.com"}`,
 			contentType: contentType,
-			auth:        token,
-			status:      http.StatusBadRequest,
-			location:    "",
+			auth:        adminToken,
+			status:      http.StatusConflict,
+		},
+		{
+			desc:        "transfer ownership to non-existing owner",
+			owner:       disabledEmail,
+			req:         `{"owner": "unknown@example.com"}`,
+			contentType: contentType,
+			auth:        adminToken,
+			status:      http.StatusNotFound,
+		},
+		{
+			desc:        "transfer ownership",
+			owner:       disabledEmail,
+			req:         `{"owner": "new@example.com"}`,
+			contentType: contentType,
+			auth:        adminToken,
+			status:      http.StatusOK,
 		},
 	}
 
@@ -956,128 +4323,161 @@ func TestCreateChannel(t *testing.T) {
 		req := testRequest{
 			client:      ts.Client(),
 			method:      http.MethodPost,
-			url:         fmt.Sprintf("%s/channels", ts.URL),
+			url:         fmt.Sprintf("%s/owners/%s/transfer", ts.URL, tc.owner),
 			contentType: tc.contentType,
 			token:       tc.auth,
 			body:        strings.NewReader(tc.req),
 		}
 		res, err := req.make()
 		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
-
-		location := res.Header.Get("Location")
 		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
-		assert.Equal(t, tc.location, location, fmt.Sprintf("%s: expected location %s got %s", tc.desc, tc.location, location))
 	}
 }
 
-func TestUpdateChannel(t *testing.T) {
-	svc := newService(map[string]string{token: email})
+func TestQuotaExceeded(t *testing.T) {
+	svc := newQuotaService(things.Quota{Things: 1, Channels: 1})
 	ts := newServer(svc)
 	defer ts.Close()
 
-	sch, _ := svc.CreateChannel(context.Background(), token, channel)
+	_, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	_, err = svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
-	ch := channel
-	ch.Name = "updated_channel"
-	updateData := toJSON(ch)
+	cases := []struct {
+		desc string
+		url  string
+		req  string
+	}{
+		{
+			desc: "add thing above quota",
+			url:  fmt.Sprintf("%s/things", ts.URL),
+			req:  toJSON(thing),
+		},
+		{
+			desc: "create channel above quota",
+			url:  fmt.Sprintf("%s/channels", ts.URL),
+			req:  toJSON(channel),
+		},
+	}
 
-	ch.Name = invalidName
-	invalidData := toJSON(ch)
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPost,
+			url:         tc.url,
+			contentType: contentType,
+			token:       token,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, http.StatusTooManyRequests, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, http.StatusTooManyRequests, res.StatusCode))
+	}
+}
+
+var metadataSchema = map[string]interface{}{
+	"type":     "object",
+	"required": []interface{}{"serial"},
+	"properties": map[string]interface{}{
+		"serial": map[string]interface{}{"type": "string"},
+	},
+}
+
+func TestSaveSchema(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	data := toJSON(metadataSchema)
 
 	cases := []struct {
 		desc        string
+		entity      string
 		req         string
-		id          string
 		contentType string
 		auth        string
 		status      int
 	}{
 		{
-			desc:        "update existing channel",
-			req:         updateData,
-			id:          sch.ID,
+			desc:        "save things schema",
+			entity:      things.ThingsEntity,
+			req:         data,
 			contentType: contentType,
 			auth:        token,
 			status:      http.StatusOK,
 		},
 		{
-			desc:        "update non-existing channel",
-			req:         updateData,
-			id:          strconv.FormatUint(wrongID, 10),
+			desc:        "save channels schema",
+			entity:      things.ChannelsEntity,
+			req:         data,
 			contentType: contentType,
 			auth:        token,
-			status:      http.StatusNotFound,
+			status:      http.StatusOK,
 		},
 		{
-			desc:        "update channel with invalid id",
-			req:         updateData,
-			id:          "invalid",
+			desc:        "save schema of unknown entity",
+			entity:      "users",
+			req:         data,
 			contentType: contentType,
 			auth:        token,
-			status:      http.StatusNotFound,
+			status:      http.StatusBadRequest,
 		},
 		{
-			desc:        "update channel with invalid token",
-			req:         updateData,
-			id:          sch.ID,
+			desc:        "save invalid schema",
+			entity:      things.ThingsEntity,
+			req:         `{"type":5}`,
 			contentType: contentType,
-			auth:        wrongValue,
-			status:      http.StatusForbidden,
+			auth:        token,
+			status:      http.StatusBadRequest,
 		},
 		{
-			desc:        "update channel with empty token",
-			req:         updateData,
-			id:          sch.ID,
+			desc:        "save schema with invalid request format",
+			entity:      things.ThingsEntity,
+			req:         "}",
 			contentType: contentType,
-			auth:        "",
-			status:      http.StatusForbidden,
+			auth:        token,
+			status:      http.StatusBadRequest,
 		},
 		{
-			desc:        "update channel with invalid data format",
-			req:         "}",
-			id:          sch.ID,
+			desc:        "save schema with empty request",
+			entity:      things.ThingsEntity,
+			req:         "",
 			contentType: contentType,
 			auth:        token,
 			status:      http.StatusBadRequest,
 		},
 		{
-			desc:        "update channel with empty JSON object",
-			req:         "{}",
-			id:          sch.ID,
+			desc:        "save schema with invalid token",
+			entity:      things.ThingsEntity,
+			req:         data,
 			contentType: contentType,
-			auth:        token,
-			status:      http.StatusOK,
+			auth:        wrongValue,
+			status:      http.StatusForbidden,
 		},
 		{
-			desc:        "update channel with empty request",
-			req:         "",
-			id:          sch.ID,
+			desc:        "save schema with empty token",
+			entity:      things.ThingsEntity,
+			req:         data,
 			contentType: contentType,
-			auth:        token,
-			status:      http.StatusBadRequest,
+			auth:        "",
+			status:      http.StatusForbidden,
 		},
 		{
-			desc:        "update channel with missing content type",
-			req:         updateData,
-			id:          sch.ID,
+			desc:        "save schema without content type",
+			entity:      things.ThingsEntity,
+			req:         data,
 			contentType: "",
 			auth:        token,
 			status:      http.StatusUnsupportedMediaType,
 		},
-		{
-			desc:        "update channel with invalid name",
-			req:         invalidData,
-			contentType: contentType,
-			auth:        token,
-			status:      http.StatusBadRequest,
-		},
 	}
 
 	for _, tc := range cases {
 		req := testRequest{
 			client:      ts.Client(),
 			method:      http.MethodPut,
-			url:         fmt.Sprintf("%s/channels/%s", ts.URL, tc.id),
+			url:         fmt.Sprintf("%s/schemas/%s", ts.URL, tc.entity),
 			contentType: tc.contentType,
 			token:       tc.auth,
 			body:        strings.NewReader(tc.req),
@@ -1088,525 +4488,347 @@ func TestUpdateChannel(t *testing.T) {
 	}
 }
 
-func TestViewChannel(t *testing.T) {
+func TestViewSchema(t *testing.T) {
 	svc := newService(map[string]string{token: email})
 	ts := newServer(svc)
 	defer ts.Close()
 
-	sch, _ := svc.CreateChannel(context.Background(), token, channel)
-
-	sth, _ := svc.AddThing(context.Background(), token, thing)
-	svc.Connect(context.Background(), token, sch.ID, sth.ID)
-
-	chres := channelRes{
-		ID:       sch.ID,
-		Name:     sch.Name,
-		Metadata: sch.Metadata,
-	}
-	data := toJSON(chres)
+	err := svc.SaveSchema(context.Background(), token, things.MetadataSchema{Entity: things.ThingsEntity, Definition: metadataSchema})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
 		desc   string
-		id     string
+		entity string
 		auth   string
 		status int
 		res    string
 	}{
 		{
-			desc:   "view existing channel",
-			id:     sch.ID,
+			desc:   "view things schema",
+			entity: things.ThingsEntity,
 			auth:   token,
 			status: http.StatusOK,
-			res:    data,
+			res:    toJSON(metadataSchema),
 		},
 		{
-			desc:   "view non-existent channel",
-			id:     strconv.FormatUint(wrongID, 10),
+			desc:   "view non-existing channels schema",
+			entity: things.ChannelsEntity,
+			auth:   token,
+			status: http.StatusNotFound,
+			res:    "",
+		},
+		{
+			desc:   "view schema of unknown entity",
+			entity: "users",
 			auth:   token,
-			status: http.StatusNotFound,
+			status: http.StatusBadRequest,
 			res:    "",
 		},
 		{
-			desc:   "view channel with invalid token",
-			id:     sch.ID,
+			desc:   "view schema with invalid token",
+			entity: things.ThingsEntity,
 			auth:   wrongValue,
 			status: http.StatusForbidden,
 			res:    "",
 		},
 		{
-			desc:   "view channel with empty token",
-			id:     sch.ID,
+			desc:   "view schema with empty token",
+			entity: things.ThingsEntity,
 			auth:   "",
 			status: http.StatusForbidden,
 			res:    "",
 		},
-		{
-			desc:   "view channel with invalid id",
-			id:     "invalid",
-			auth:   token,
-			status: http.StatusNotFound,
-			res:    "",
-		},
 	}
 
 	for _, tc := range cases {
 		req := testRequest{
 			client: ts.Client(),
 			method: http.MethodGet,
-			url:    fmt.Sprintf("%s/channels/%s", ts.URL, tc.id),
+			url:    fmt.Sprintf("%s/schemas/%s", ts.URL, tc.entity),
 			token:  tc.auth,
 		}
 		res, err := req.make()
 		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
-		data, err := ioutil.ReadAll(res.Body)
-		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
-		body := strings.Trim(string(data), "\n")
 		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
-		assert.Equal(t, tc.res, body, fmt.Sprintf("%s: expected body %s got %s", tc.desc, tc.res, body))
+
+		body, err := ioutil.ReadAll(res.Body)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		data := strings.Trim(string(body), "\n")
+		assert.Equal(t, tc.res, data, fmt.Sprintf("%s: expected body %s got %s", tc.desc, tc.res, data))
 	}
 }
 
-func TestListChannels(t *testing.T) {
+func TestRemoveSchema(t *testing.T) {
 	svc := newService(map[string]string{token: email})
 	ts := newServer(svc)
 	defer ts.Close()
 
-	channels := []channelRes{}
-	for i := 0; i < 101; i++ {
-		sch, err := svc.CreateChannel(context.Background(), token, channel)
-		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
-		sth, err := svc.AddThing(context.Background(), token, thing)
-		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
-		svc.Connect(context.Background(), token, sch.ID, sth.ID)
-
-		chres := channelRes{
-			ID:       sch.ID,
-			Name:     sch.Name,
-			Metadata: sch.Metadata,
-		}
-		channels = append(channels, chres)
-	}
-	channelURL := fmt.Sprintf("%s/channels", ts.URL)
+	err := svc.SaveSchema(context.Background(), token, things.MetadataSchema{Entity: things.ThingsEntity, Definition: metadataSchema})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
 		desc   string
+		entity string
 		auth   string
 		status int
-		url    string
-		res    []channelRes
 	}{
 		{
-			desc:   "get a list of channels",
-			auth:   token,
-			status: http.StatusOK,
-			url:    fmt.Sprintf("%s?offset=%d&limit=%d", channelURL, 0, 6),
-			res:    channels[0:6],
-		},
-		{
-			desc:   "get a list of channels with invalid token",
+			desc:   "remove schema with invalid token",
+			entity: things.ThingsEntity,
 			auth:   wrongValue,
 			status: http.StatusForbidden,
-			url:    fmt.Sprintf("%s?offset=%d&limit=%d", channelURL, 0, 1),
-			res:    nil,
 		},
 		{
-			desc:   "get a list of channels with empty token",
+			desc:   "remove schema with empty token",
+			entity: things.ThingsEntity,
 			auth:   "",
 			status: http.StatusForbidden,
-			url:    fmt.Sprintf("%s?offset=%d&limit=%d", channelURL, 0, 1),
-			res:    nil,
-		},
-		{
-			desc:   "get a list of channels with negative offset",
-			auth:   token,
-			status: http.StatusBadRequest,
-			url:    fmt.Sprintf("%s?offset=%d&limit=%d", channelURL, -1, 5),
-			res:    nil,
-		},
-		{
-			desc:   "get a list of channels with negative limit",
-			auth:   token,
-			status: http.StatusBadRequest,
-			url:    fmt.Sprintf("%s?offset=%d&limit=%d", channelURL, -1, 5),
-			res:    nil,
-		},
-		{
-			desc:   "get a list of channels with zero limit",
-			auth:   token,
-			status: http.StatusBadRequest,
-			url:    fmt.Sprintf("%s?offset=%d&limit=%d", channelURL, 1, 0),
-			res:    nil,
-		},
-		{
-			desc:   "get a list of channels with no offset provided",
-			auth:   token,
-			status: http.StatusOK,
-			url:    fmt.Sprintf("%s?limit=%d", channelURL, 5),
-			res:    channels[0:5],
-		},
-		{
-			desc:   "get a list of channels with no limit provided",
-			auth:   token,
-			status: http.StatusOK,
-			url:    fmt.Sprintf("%s?offset=%d", channelURL, 1),
-			res:    channels[1:11],
-		},
-		{
-			desc:   "get a list of channels with redundant query params",
-			auth:   token,
-			status: http.StatusOK,
-			url:    fmt.Sprintf("%s?offset=%d&limit=%d&value=something", channelURL, 0, 5),
-			res:    channels[0:5],
-		},
-		{
-			desc:   "get a list of channels with limit greater than max",
-			auth:   token,
-			status: http.StatusBadRequest,
-			url:    fmt.Sprintf("%s?offset=%d&limit=%d", channelURL, 0, 110),
-			res:    nil,
-		},
-		{
-			desc:   "get a list of channels with default URL",
-			auth:   token,
-			status: http.StatusOK,
-			url:    fmt.Sprintf("%s%s", channelURL, ""),
-			res:    channels[0:10],
 		},
 		{
-			desc:   "get a list of channels with invalid number of params",
-			auth:   token,
-			status: http.StatusBadRequest,
-			url:    fmt.Sprintf("%s%s", channelURL, "?offset=4&limit=4&limit=5&offset=5"),
-			res:    nil,
-		},
-		{
-			desc:   "get a list of channels with invalid offset",
+			desc:   "remove schema of unknown entity",
+			entity: "users",
 			auth:   token,
 			status: http.StatusBadRequest,
-			url:    fmt.Sprintf("%s%s", channelURL, "?offset=e&limit=5"),
-			res:    nil,
 		},
 		{
-			desc:   "get a list of channels with invalid limit",
+			desc:   "remove things schema",
+			entity: things.ThingsEntity,
 			auth:   token,
-			status: http.StatusBadRequest,
-			url:    fmt.Sprintf("%s%s", channelURL, "?offset=5&limit=e"),
-			res:    nil,
+			status: http.StatusNoContent,
 		},
 		{
-			desc:   "get a list of channels with invalid name",
+			desc:   "remove non-existing schema",
+			entity: things.ThingsEntity,
 			auth:   token,
-			status: http.StatusBadRequest,
-			url:    fmt.Sprintf("%s?offset=%d&limit=%d&name=%s", channelURL, 0, 10, invalidName),
-			res:    nil,
+			status: http.StatusNoContent,
 		},
 	}
 
 	for _, tc := range cases {
 		req := testRequest{
 			client: ts.Client(),
-			method: http.MethodGet,
-			url:    tc.url,
+			method: http.MethodDelete,
+			url:    fmt.Sprintf("%s/schemas/%s", ts.URL, tc.entity),
 			token:  tc.auth,
 		}
 		res, err := req.make()
 		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
-		var body channelsPageRes
-		json.NewDecoder(res.Body).Decode(&body)
 		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
-		assert.ElementsMatch(t, tc.res, body.Channels, fmt.Sprintf("%s: expected body %v got %v", tc.desc, tc.res, body.Channels))
 	}
 }
 
-func TestListChannelsByThing(t *testing.T) {
+func TestSavePayloadSchema(t *testing.T) {
 	svc := newService(map[string]string{token: email})
 	ts := newServer(svc)
 	defer ts.Close()
 
-	sth, err := svc.AddThing(context.Background(), token, thing)
+	sch, err := svc.CreateChannel(context.Background(), token, channel)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
-	channels := []channelRes{}
-	for i := 0; i < 101; i++ {
-		sch, err := svc.CreateChannel(context.Background(), token, channel)
-		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
-		err = svc.Connect(context.Background(), token, sch.ID, sth.ID)
-		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
-
-		chres := channelRes{
-			ID:       sch.ID,
-			Name:     sch.Name,
-			Metadata: sch.Metadata,
-		}
-		channels = append(channels, chres)
-	}
-	channelURL := fmt.Sprintf("%s/things", ts.URL)
+	data := toJSON(metadataSchema)
 
 	cases := []struct {
-		desc   string
-		auth   string
-		status int
-		url    string
-		res    []channelRes
+		desc        string
+		id          string
+		subtopic    string
+		req         string
+		contentType string
+		auth        string
+		status      int
 	}{
 		{
-			desc:   "get a list of channels by thing",
-			auth:   token,
-			status: http.StatusOK,
-			url:    fmt.Sprintf("%s/%s/channels?offset=%d&limit=%d", channelURL, sth.ID, 0, 6),
-			res:    channels[0:6],
-		},
-		{
-			desc:   "get a list of channels by thing with invalid token",
-			auth:   wrongValue,
-			status: http.StatusForbidden,
-			url:    fmt.Sprintf("%s/%s/channels?offset=%d&limit=%d", channelURL, sth.ID, 0, 1),
-			res:    nil,
-		},
-		{
-			desc:   "get a list of channels by thing with empty token",
-			auth:   "",
-			status: http.StatusForbidden,
-			url:    fmt.Sprintf("%s/%s/channels?offset=%d&limit=%d", channelURL, sth.ID, 0, 1),
-			res:    nil,
-		},
-		{
-			desc:   "get a list of channels by thing with negative offset",
-			auth:   token,
-			status: http.StatusBadRequest,
-			url:    fmt.Sprintf("%s/%s/channels?offset=%d&limit=%d", channelURL, sth.ID, -1, 5),
-			res:    nil,
-		},
-		{
-			desc:   "get a list of channels by thing with negative limit",
-			auth:   token,
-			status: http.StatusBadRequest,
-			url:    fmt.Sprintf("%s/%s/channels?offset=%d&limit=%d", channelURL, sth.ID, -1, 5),
-			res:    nil,
-		},
-		{
-			desc:   "get a list of channels by thing with zero limit",
-			auth:   token,
-			status: http.StatusBadRequest,
-			url:    fmt.Sprintf("%s/%s/channels?offset=%d&limit=%d", channelURL, sth.ID, 1, 0),
-			res:    nil,
-		},
-		{
-			desc:   "get a list of channels by thing with no offset provided",
-			auth:   token,
-			status: http.StatusOK,
-			url:    fmt.Sprintf("%s/%s/channels?limit=%d", channelURL, sth.ID, 5),
-			res:    channels[0:5],
+			desc:        "save channel payload schema",
+			id:          sch.ID,
+			req:         data,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusOK,
 		},
 		{
-			desc:   "get a list of channels by thing with no limit provided",
-			auth:   token,
-			status: http.StatusOK,
-			url:    fmt.Sprintf("%s/%s/channels?offset=%d", channelURL, sth.ID, 1),
-			res:    channels[1:11],
+			desc:        "save subtopic payload schema",
+			id:          sch.ID,
+			subtopic:    "devices.temperature",
+			req:         data,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusOK,
 		},
 		{
-			desc:   "get a list of channels by thing with redundant query params",
-			auth:   token,
-			status: http.StatusOK,
-			url:    fmt.Sprintf("%s/%s/channels?offset=%d&limit=%d&value=something", channelURL, sth.ID, 0, 5),
-			res:    channels[0:5],
+			desc:        "save payload schema of wildcard subtopic",
+			id:          sch.ID,
+			subtopic:    "devices.*",
+			req:         data,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
 		},
 		{
-			desc:   "get a list of channels by thing with limit greater than max",
-			auth:   token,
-			status: http.StatusBadRequest,
-			url:    fmt.Sprintf("%s/%s/channels?offset=%d&limit=%d", channelURL, sth.ID, 0, 110),
-			res:    nil,
+			desc:        "save payload schema of non-existing channel",
+			id:          strconv.FormatUint(wrongID, 10),
+			req:         data,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusNotFound,
 		},
 		{
-			desc:   "get a list of channels by thing with default URL",
-			auth:   token,
-			status: http.StatusOK,
-			url:    fmt.Sprintf("%s/%s/channels", channelURL, sth.ID),
-			res:    channels[0:10],
+			desc:        "save invalid payload schema",
+			id:          sch.ID,
+			req:         `{"type":5}`,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
 		},
 		{
-			desc:   "get a list of channels by thing with invalid number of params",
-			auth:   token,
-			status: http.StatusBadRequest,
-			url:    fmt.Sprintf("%s/%s/channels%s", channelURL, sth.ID, "?offset=4&limit=4&limit=5&offset=5"),
-			res:    nil,
+			desc:        "save payload schema with invalid request format",
+			id:          sch.ID,
+			req:         "}",
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
 		},
 		{
-			desc:   "get a list of channels by thing with invalid offset",
-			auth:   token,
-			status: http.StatusBadRequest,
-			url:    fmt.Sprintf("%s/%s/channels%s", channelURL, sth.ID, "?offset=e&limit=5"),
-			res:    nil,
+			desc:        "save payload schema with invalid token",
+			id:          sch.ID,
+			req:         data,
+			contentType: contentType,
+			auth:        wrongValue,
+			status:      http.StatusForbidden,
 		},
 		{
-			desc:   "get a list of channels by thing with invalid limit",
-			auth:   token,
-			status: http.StatusBadRequest,
-			url:    fmt.Sprintf("%s/%s/channels%s", channelURL, sth.ID, "?offset=5&limit=e"),
-			res:    nil,
+			desc:        "save payload schema without content type",
+			id:          sch.ID,
+			req:         data,
+			contentType: "",
+			auth:        token,
+			status:      http.StatusUnsupportedMediaType,
 		},
 	}
 
 	for _, tc := range cases {
 		req := testRequest{
-			client: ts.Client(),
-			method: http.MethodGet,
-			url:    tc.url,
-			token:  tc.auth,
+			client:      ts.Client(),
+			method:      http.MethodPut,
+			url:         fmt.Sprintf("%s/channels/%s/schemas?subtopic=%s", ts.URL, tc.id, tc.subtopic),
+			contentType: tc.contentType,
+			token:       tc.auth,
+			body:        strings.NewReader(tc.req),
 		}
 		res, err := req.make()
 		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
-		var body channelsPageRes
-		json.NewDecoder(res.Body).Decode(&body)
 		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
-		assert.ElementsMatch(t, tc.res, body.Channels, fmt.Sprintf("%s: expected body %v got %v", tc.desc, tc.res, body.Channels))
 	}
 }
 
-func TestRemoveChannel(t *testing.T) {
+func TestListPayloadSchemas(t *testing.T) {
 	svc := newService(map[string]string{token: email})
 	ts := newServer(svc)
 	defer ts.Close()
 
-	sch, _ := svc.CreateChannel(context.Background(), token, channel)
+	sch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = svc.SavePayloadSchema(context.Background(), token, things.PayloadSchema{Channel: sch.ID, Subtopic: "devices", Definition: metadataSchema})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
 		desc   string
 		id     string
 		auth   string
 		status int
+		res    string
 	}{
 		{
-			desc:   "remove channel with invalid token",
-			id:     sch.ID,
-			auth:   wrongValue,
-			status: http.StatusForbidden,
-		},
-		{
-			desc:   "remove existing channel",
+			desc:   "list payload schemas",
 			id:     sch.ID,
 			auth:   token,
-			status: http.StatusNoContent,
+			status: http.StatusOK,
+			res:    toJSON(payloadSchemasRes{Schemas: []payloadSchemaRes{{Subtopic: "devices", Schema: metadataSchema}}}),
 		},
 		{
-			desc:   "remove removed channel",
-			id:     sch.ID,
+			desc:   "list payload schemas of non-existing channel",
+			id:     strconv.FormatUint(wrongID, 10),
 			auth:   token,
-			status: http.StatusNoContent,
+			status: http.StatusNotFound,
+			res:    "",
 		},
 		{
-			desc:   "remove channel with invalid token",
+			desc:   "list payload schemas with invalid token",
 			id:     sch.ID,
 			auth:   wrongValue,
 			status: http.StatusForbidden,
-		},
-		{
-			desc:   "remove channel with empty token",
-			id:     sch.ID,
-			auth:   "",
-			status: http.StatusForbidden,
+			res:    "",
 		},
 	}
 
 	for _, tc := range cases {
 		req := testRequest{
 			client: ts.Client(),
-			method: http.MethodDelete,
-			url:    fmt.Sprintf("%s/channels/%s", ts.URL, tc.id),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/channels/%s/schemas", ts.URL, tc.id),
 			token:  tc.auth,
 		}
 		res, err := req.make()
 		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
 		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+
+		body, err := ioutil.ReadAll(res.Body)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		data := strings.Trim(string(body), "\n")
+		assert.Equal(t, tc.res, data, fmt.Sprintf("%s: expected body %s got %s", tc.desc, tc.res, data))
 	}
 }
 
-func TestConnect(t *testing.T) {
-	otherToken := "other_token"
-	otherEmail := "other_user@example.com"
-	svc := newService(map[string]string{
-		token:      email,
-		otherToken: otherEmail,
-	})
+func TestRemovePayloadSchema(t *testing.T) {
+	svc := newService(map[string]string{token: email})
 	ts := newServer(svc)
 	defer ts.Close()
 
-	ath, _ := svc.AddThing(context.Background(), token, thing)
-	ach, _ := svc.CreateChannel(context.Background(), token, channel)
-	bch, _ := svc.CreateChannel(context.Background(), otherToken, channel)
+	sch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = svc.SavePayloadSchema(context.Background(), token, things.PayloadSchema{Channel: sch.ID, Subtopic: "devices", Definition: metadataSchema})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
-		desc    string
-		chanID  string
-		thingID string
-		auth    string
-		status  int
+		desc     string
+		id       string
+		subtopic string
+		auth     string
+		status   int
 	}{
 		{
-			desc:    "connect existing thing to existing channel",
-			chanID:  ach.ID,
-			thingID: ath.ID,
-			auth:    token,
-			status:  http.StatusOK,
-		},
-		{
-			desc:    "connect existing thing to non-existent channel",
-			chanID:  strconv.FormatUint(wrongID, 10),
-			thingID: ath.ID,
-			auth:    token,
-			status:  http.StatusNotFound,
-		},
-		{
-			desc:    "connect non-existing thing to existing channel",
-			chanID:  ach.ID,
-			thingID: strconv.FormatUint(wrongID, 10),
-			auth:    token,
-			status:  http.StatusNotFound,
-		},
-		{
-			desc:    "connect existing thing to channel with invalid id",
-			chanID:  "invalid",
-			thingID: ath.ID,
-			auth:    token,
-			status:  http.StatusNotFound,
-		},
-		{
-			desc:    "connect thing with invalid id to existing channel",
-			chanID:  ach.ID,
-			thingID: "invalid",
-			auth:    token,
-			status:  http.StatusNotFound,
+			desc:     "remove payload schema with invalid token",
+			id:       sch.ID,
+			subtopic: "devices",
+			auth:     wrongValue,
+			status:   http.StatusForbidden,
 		},
 		{
-			desc:    "connect existing thing to existing channel with invalid token",
-			chanID:  ach.ID,
-			thingID: ath.ID,
-			auth:    wrongValue,
-			status:  http.StatusForbidden,
+			desc:     "remove payload schema of non-existing channel",
+			id:       strconv.FormatUint(wrongID, 10),
+			subtopic: "devices",
+			auth:     token,
+			status:   http.StatusNotFound,
 		},
 		{
-			desc:    "connect existing thing to existing channel with empty token",
-			chanID:  ach.ID,
-			thingID: ath.ID,
-			auth:    "",
-			status:  http.StatusForbidden,
+			desc:     "remove payload schema",
+			id:       sch.ID,
+			subtopic: "devices",
+			auth:     token,
+			status:   http.StatusNoContent,
 		},
 		{
-			desc:    "connect thing from owner to channel of other user",
-			chanID:  bch.ID,
-			thingID: ath.ID,
-			auth:    token,
-			status:  http.StatusNotFound,
+			desc:     "remove non-existing payload schema",
+			id:       sch.ID,
+			subtopic: "devices",
+			auth:     token,
+			status:   http.StatusNoContent,
 		},
 	}
 
 	for _, tc := range cases {
 		req := testRequest{
 			client: ts.Client(),
-			method: http.MethodPut,
-			url:    fmt.Sprintf("%s/channels/%s/things/%s", ts.URL, tc.chanID, tc.thingID),
+			method: http.MethodDelete,
+			url:    fmt.Sprintf("%s/channels/%s/schemas?subtopic=%s", ts.URL, tc.id, tc.subtopic),
 			token:  tc.auth,
 		}
 		res, err := req.make()
@@ -1615,103 +4837,111 @@ func TestConnect(t *testing.T) {
 	}
 }
 
-func TestDisconnnect(t *testing.T) {
-	otherToken := "other_token"
-	otherEmail := "other_user@example.com"
-	svc := newService(map[string]string{
-		token:      email,
-		otherToken: otherEmail,
-	})
+func TestMetadataValidation(t *testing.T) {
+	svc := newService(map[string]string{token: email})
 	ts := newServer(svc)
 	defer ts.Close()
 
-	ath, _ := svc.AddThing(context.Background(), token, thing)
-	ach, _ := svc.CreateChannel(context.Background(), token, channel)
-	svc.Connect(context.Background(), token, ach.ID, ath.ID)
-	bch, _ := svc.CreateChannel(context.Background(), otherToken, channel)
+	sth, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	sch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	for _, entity := range []string{things.ThingsEntity, things.ChannelsEntity} {
+		err := svc.SaveSchema(context.Background(), token, things.MetadataSchema{Entity: entity, Definition: metadataSchema})
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	valid := toJSON(map[string]interface{}{"metadata": map[string]interface{}{"serial": "abc"}})
+	invalid := toJSON(map[string]interface{}{"metadata": map[string]interface{}{"serial": 1}})
+	violations := []violationRes{{Field: "serial", Description: "Invalid type. Expected: string, given: integer"}}
 
 	cases := []struct {
-		desc    string
-		chanID  string
-		thingID string
-		auth    string
-		status  int
+		desc       string
+		method     string
+		url        string
+		req        string
+		status     int
+		violations []violationRes
 	}{
 		{
-			desc:    "disconnect connected thing from channel",
-			chanID:  ach.ID,
-			thingID: ath.ID,
-			auth:    token,
-			status:  http.StatusNoContent,
-		},
-		{
-			desc:    "disconnect non-connected thing from channel",
-			chanID:  ach.ID,
-			thingID: ath.ID,
-			auth:    token,
-			status:  http.StatusNotFound,
+			desc:   "add thing with valid metadata",
+			method: http.MethodPost,
+			url:    fmt.Sprintf("%s/things", ts.URL),
+			req:    valid,
+			status: http.StatusCreated,
 		},
 		{
-			desc:    "disconnect non-existent thing from channel",
-			chanID:  ach.ID,
-			thingID: strconv.FormatUint(wrongID, 10),
-			auth:    token,
-			status:  http.StatusNotFound,
+			desc:       "add thing with invalid metadata",
+			method:     http.MethodPost,
+			url:        fmt.Sprintf("%s/things", ts.URL),
+			req:        invalid,
+			status:     http.StatusBadRequest,
+			violations: violations,
 		},
 		{
-			desc:    "disconnect thing from non-existent channel",
-			chanID:  strconv.FormatUint(wrongID, 10),
-			thingID: ath.ID,
-			auth:    token,
-			status:  http.StatusNotFound,
+			desc:   "update thing with valid metadata",
+			method: http.MethodPut,
+			url:    fmt.Sprintf("%s/things/%s", ts.URL, sth.ID),
+			req:    valid,
+			status: http.StatusOK,
 		},
 		{
-			desc:    "disconnect thing from channel with invalid token",
-			chanID:  ach.ID,
-			thingID: ath.ID,
-			auth:    wrongValue,
-			status:  http.StatusForbidden,
+			desc:       "update thing with invalid metadata",
+			method:     http.MethodPut,
+			url:        fmt.Sprintf("%s/things/%s", ts.URL, sth.ID),
+			req:        invalid,
+			status:     http.StatusBadRequest,
+			violations: violations,
 		},
 		{
-			desc:    "disconnect thing from channel with empty token",
-			chanID:  ach.ID,
-			thingID: ath.ID,
-			auth:    "",
-			status:  http.StatusForbidden,
+			desc:   "create channel with valid metadata",
+			method: http.MethodPost,
+			url:    fmt.Sprintf("%s/channels", ts.URL),
+			req:    valid,
+			status: http.StatusCreated,
 		},
 		{
-			desc:    "disconnect owner's thing from someone elses channel",
-			chanID:  bch.ID,
-			thingID: ath.ID,
-			auth:    token,
-			status:  http.StatusNotFound,
+			desc:       "create channel with invalid metadata",
+			method:     http.MethodPost,
+			url:        fmt.Sprintf("%s/channels", ts.URL),
+			req:        invalid,
+			status:     http.StatusBadRequest,
+			violations: violations,
 		},
 		{
-			desc:    "disconnect thing with invalid id from channel",
-			chanID:  ach.ID,
-			thingID: "invalid",
-			auth:    token,
-			status:  http.StatusNotFound,
+			desc:   "update channel with valid metadata",
+			method: http.MethodPut,
+			url:    fmt.Sprintf("%s/channels/%s", ts.URL, sch.ID),
+			req:    valid,
+			status: http.StatusOK,
 		},
 		{
-			desc:    "disconnect thing from channel with invalid id",
-			chanID:  "invalid",
-			thingID: ath.ID,
-			auth:    token,
-			status:  http.StatusNotFound,
+			desc:       "update channel with invalid metadata",
+			method:     http.MethodPut,
+			url:        fmt.Sprintf("%s/channels/%s", ts.URL, sch.ID),
+			req:        invalid,
+			status:     http.StatusBadRequest,
+			violations: violations,
 		},
 	}
 
 	for _, tc := range cases {
 		req := testRequest{
-			client: ts.Client(),
-			method: http.MethodDelete,
-			url:    fmt.Sprintf("%s/channels/%s/things/%s", ts.URL, tc.chanID, tc.thingID),
-			token:  tc.auth,
+			client:      ts.Client(),
+			method:      tc.method,
+			url:         tc.url,
+			contentType: contentType,
+			token:       token,
+			body:        strings.NewReader(tc.req),
 		}
 		res, err := req.make()
 		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
 		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+
+		var body validationErrorRes
+		json.NewDecoder(res.Body).Decode(&body)
+		assert.Equal(t, tc.violations, body.Violations, fmt.Sprintf("%s: expected violations %v got %v", tc.desc, tc.violations, body.Violations))
 	}
 }
 
@@ -1735,9 +4965,72 @@ type thingsPageRes struct {
 	Limit  uint64     `json:"limit"`
 }
 
+type usageRes struct {
+	Things      uint64 `json:"things"`
+	Channels    uint64 `json:"channels"`
+	Connections uint64 `json:"connections"`
+}
+
+type quotaRes struct {
+	Owner       string   `json:"owner"`
+	Things      uint64   `json:"things"`
+	Channels    uint64   `json:"channels"`
+	Connections uint64   `json:"connections"`
+	Usage       usageRes `json:"usage"`
+}
+
+type importRes struct {
+	Imported int `json:"imported"`
+}
+
 type channelsPageRes struct {
 	Channels []channelRes `json:"channels"`
 	Total    uint64       `json:"total"`
 	Offset   uint64       `json:"offset"`
 	Limit    uint64       `json:"limit"`
 }
+
+type payloadSchemaRes struct {
+	Subtopic string                 `json:"subtopic"`
+	Schema   map[string]interface{} `json:"schema"`
+}
+
+type payloadSchemasRes struct {
+	Schemas []payloadSchemaRes `json:"schemas"`
+}
+
+type violationRes struct {
+	Field       string `json:"field"`
+	Description string `json:"description"`
+}
+
+type validationErrorRes struct {
+	Error      string         `json:"error"`
+	Violations []violationRes `json:"violations"`
+}
+
+type changeRes struct {
+	ID        string            `json:"id"`
+	Operation string            `json:"operation"`
+	Fields    map[string]string `json:"fields,omitempty"`
+}
+
+type changesPageRes struct {
+	Changes []changeRes `json:"changes"`
+	Next    string      `json:"next,omitempty"`
+}
+
+type apiUsageRes struct {
+	Method string    `json:"method"`
+	Hour   time.Time `json:"hour"`
+	Calls  uint64    `json:"calls"`
+	Errors uint64    `json:"errors"`
+}
+
+type apiUsagePageRes struct {
+	From   time.Time     `json:"from"`
+	To     time.Time     `json:"to"`
+	Calls  uint64        `json:"calls"`
+	Errors uint64        `json:"errors"`
+	Usage  []apiUsageRes `json:"usage"`
+}
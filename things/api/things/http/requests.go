@@ -3,7 +3,13 @@
 
 package http
 
-import "github.com/mainflux/mainflux/things"
+import (
+	"io"
+	"math"
+	"time"
+
+	"github.com/mainflux/mainflux/things"
+)
 
 const maxLimitSize = 100
 const maxNameSize = 1024
@@ -72,10 +78,34 @@ func (req updateKeyReq) validate() error {
 	return nil
 }
 
+type retentionReq struct {
+	Period   int64  `json:"period,omitempty"`
+	Messages uint64 `json:"messages,omitempty"`
+}
+
+func (req retentionReq) validate() error {
+	if req.Period < 0 || req.Period > math.MaxInt64/int64(time.Second) || req.Messages > math.MaxInt64 {
+		return things.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+func (req retentionReq) retention() things.Retention {
+	return things.Retention{
+		Period:   time.Duration(req.Period) * time.Second,
+		Messages: req.Messages,
+	}
+}
+
 type createChannelReq struct {
-	token    string
-	Name     string                 `json:"name,omitempty"`
-	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	token     string
+	Name      string                 `json:"name,omitempty"`
+	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+	Retention retentionReq           `json:"retention,omitempty"`
+	Region    string                 `json:"region,omitempty"`
+	Alias     string                 `json:"alias,omitempty"`
+	Ordered   bool                   `json:"ordered,omitempty"`
 }
 
 func (req createChannelReq) validate() error {
@@ -87,14 +117,35 @@ func (req createChannelReq) validate() error {
 		return things.ErrMalformedEntity
 	}
 
+	return req.Retention.validate()
+}
+
+type createDirectChannelReq struct {
+	token  string
+	Things []string `json:"things"`
+}
+
+func (req createDirectChannelReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	if len(req.Things) != 2 || req.Things[0] == "" || req.Things[1] == "" {
+		return things.ErrMalformedEntity
+	}
+
 	return nil
 }
 
 type updateChannelReq struct {
-	token    string
-	id       string
-	Name     string                 `json:"name,omitempty"`
-	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	token     string
+	id        string
+	Name      string                 `json:"name,omitempty"`
+	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+	Retention retentionReq           `json:"retention,omitempty"`
+	Region    string                 `json:"region,omitempty"`
+	Alias     string                 `json:"alias,omitempty"`
+	Ordered   bool                   `json:"ordered,omitempty"`
 }
 
 func (req updateChannelReq) validate() error {
@@ -110,7 +161,7 @@ func (req updateChannelReq) validate() error {
 		return things.ErrMalformedEntity
 	}
 
-	return nil
+	return req.Retention.validate()
 }
 
 type viewResourceReq struct {
@@ -134,8 +185,14 @@ type listResourcesReq struct {
 	token    string
 	offset   uint64
 	limit    uint64
+	cursor   string
 	name     string
+	order    string
+	dir      string
 	metadata map[string]interface{}
+	query    []things.MetadataQuery
+	deleted  bool
+	shared   bool
 }
 
 func (req *listResourcesReq) validate() error {
@@ -154,6 +211,44 @@ func (req *listResourcesReq) validate() error {
 	return nil
 }
 
+func (req listResourcesReq) pageQuery() things.PageQuery {
+	return things.PageQuery{
+		Offset:   req.offset,
+		Limit:    req.limit,
+		Cursor:   req.cursor,
+		Name:     req.name,
+		Order:    req.order,
+		Dir:      req.dir,
+		Metadata: req.metadata,
+		Query:    req.query,
+		Deleted:  req.deleted,
+		Shared:   req.shared,
+	}
+}
+
+type searchReq struct {
+	token  string
+	query  string
+	offset uint64
+	limit  uint64
+}
+
+func (req searchReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	if req.query == "" || len(req.query) > maxNameSize {
+		return things.ErrMalformedEntity
+	}
+
+	if req.limit == 0 || req.limit > maxLimitSize {
+		return things.ErrMalformedEntity
+	}
+
+	return nil
+}
+
 type listByConnectionReq struct {
 	token  string
 	id     string
@@ -177,10 +272,85 @@ func (req listByConnectionReq) validate() error {
 	return nil
 }
 
+type shareReq struct {
+	token      string
+	id         string
+	groupID    string
+	Permission string `json:"permission"`
+}
+
+func (req shareReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" || req.groupID == "" || req.Permission == "" {
+		return things.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type unshareReq struct {
+	token   string
+	id      string
+	groupID string
+}
+
+func (req unshareReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" || req.groupID == "" {
+		return things.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type userShareReq struct {
+	token      string
+	id         string
+	Email      string `json:"email"`
+	Permission string `json:"permission"`
+}
+
+func (req userShareReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" || req.Email == "" || req.Permission == "" {
+		return things.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type userUnshareReq struct {
+	token string
+	id    string
+	email string
+}
+
+func (req userUnshareReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" || req.email == "" {
+		return things.ErrMalformedEntity
+	}
+
+	return nil
+}
+
 type connectionReq struct {
 	token   string
 	chanID  string
 	thingID string
+	Actions []string `json:"actions,omitempty"`
 }
 
 func (req connectionReq) validate() error {
@@ -194,3 +364,197 @@ func (req connectionReq) validate() error {
 
 	return nil
 }
+
+type exportReq struct {
+	token  string
+	format string
+}
+
+func (req exportReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	if req.format != jsonFormat && req.format != csvFormat {
+		return things.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type importReq struct {
+	token  string
+	format string
+	body   io.Reader
+}
+
+func (req importReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	if req.body == nil {
+		return things.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type updateQuotaReq struct {
+	token       string
+	owner       string
+	Things      uint64 `json:"things"`
+	Channels    uint64 `json:"channels"`
+	Connections uint64 `json:"connections"`
+}
+
+func (req updateQuotaReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	if req.owner == "" {
+		return things.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type quotaReq struct {
+	token string
+	owner string
+}
+
+func (req quotaReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	if req.owner == "" {
+		return things.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type transferReq struct {
+	token string
+	from  string
+	To    string `json:"owner"`
+}
+
+func (req transferReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	if req.from == "" || req.To == "" {
+		return things.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type saveSchemaReq struct {
+	token  string
+	entity string
+	schema map[string]interface{}
+}
+
+func (req saveSchemaReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	if req.entity == "" || req.schema == nil {
+		return things.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type schemaReq struct {
+	token  string
+	entity string
+}
+
+func (req schemaReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	if req.entity == "" {
+		return things.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type savePayloadSchemaReq struct {
+	token    string
+	chanID   string
+	subtopic string
+	schema   map[string]interface{}
+}
+
+func (req savePayloadSchemaReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	if req.chanID == "" || req.schema == nil {
+		return things.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type payloadSchemaReq struct {
+	token    string
+	chanID   string
+	subtopic string
+}
+
+func (req payloadSchemaReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	if req.chanID == "" {
+		return things.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type listChangesReq struct {
+	token string
+	since string
+	limit uint64
+}
+
+func (req listChangesReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	if req.limit == 0 || req.limit > maxLimitSize {
+		return things.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type apiUsageReq struct {
+	token string
+	from  time.Time
+	to    time.Time
+}
+
+func (req apiUsageReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	return nil
+}
@@ -6,8 +6,10 @@ package http
 import (
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/things"
 )
 
 var (
@@ -20,6 +22,13 @@ var (
 	_ mainflux.Response = (*channelsPageRes)(nil)
 	_ mainflux.Response = (*connectionRes)(nil)
 	_ mainflux.Response = (*disconnectionRes)(nil)
+	_ mainflux.Response = (*shareRes)(nil)
+	_ mainflux.Response = (*updateQuotaRes)(nil)
+	_ mainflux.Response = (*viewQuotaRes)(nil)
+	_ mainflux.Response = (*transferRes)(nil)
+	_ mainflux.Response = (*saveSchemaRes)(nil)
+	_ mainflux.Response = (*viewSchemaRes)(nil)
+	_ mainflux.Response = (*payloadSchemasRes)(nil)
 )
 
 type removeRes struct{}
@@ -39,6 +48,9 @@ func (res removeRes) Empty() bool {
 type thingRes struct {
 	id      string
 	created bool
+	// Key is returned on creation only, since it may not be retrievable
+	// afterwards if keys are stored hashed.
+	Key string `json:"key,omitempty"`
 }
 
 func (res thingRes) Code() int {
@@ -60,15 +72,32 @@ func (res thingRes) Headers() map[string]string {
 }
 
 func (res thingRes) Empty() bool {
-	return true
+	return res.Key == ""
+}
+
+type rotateKeyRes struct {
+	Key string `json:"key"`
+}
+
+func (res rotateKeyRes) Code() int {
+	return http.StatusOK
+}
+
+func (res rotateKeyRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res rotateKeyRes) Empty() bool {
+	return false
 }
 
 type viewThingRes struct {
-	ID       string                 `json:"id"`
-	Owner    string                 `json:"-"`
-	Name     string                 `json:"name,omitempty"`
-	Key      string                 `json:"key"`
-	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	ID        string                 `json:"id"`
+	Owner     string                 `json:"-"`
+	Name      string                 `json:"name,omitempty"`
+	Key       string                 `json:"key,omitempty"`
+	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+	DeletedAt *time.Time             `json:"deleted_at,omitempty"`
 }
 
 func (res viewThingRes) Code() int {
@@ -128,11 +157,34 @@ func (res channelRes) Empty() bool {
 }
 
 type viewChannelRes struct {
-	ID       string                 `json:"id"`
-	Owner    string                 `json:"-"`
-	Name     string                 `json:"name,omitempty"`
-	Things   []viewThingRes         `json:"connected,omitempty"`
-	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	ID        string                 `json:"id"`
+	Owner     string                 `json:"-"`
+	Name      string                 `json:"name,omitempty"`
+	Things    []viewThingRes         `json:"connected,omitempty"`
+	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+	Retention *retentionRes          `json:"retention,omitempty"`
+	Region    string                 `json:"region,omitempty"`
+	Alias     string                 `json:"alias,omitempty"`
+	Ordered   bool                   `json:"ordered,omitempty"`
+	Type      string                 `json:"type,omitempty"`
+	Peers     []string               `json:"peers,omitempty"`
+	DeletedAt *time.Time             `json:"deleted_at,omitempty"`
+}
+
+type retentionRes struct {
+	Period   int64  `json:"period,omitempty"`
+	Messages uint64 `json:"messages,omitempty"`
+}
+
+func retention(r things.Retention) *retentionRes {
+	if r == (things.Retention{}) {
+		return nil
+	}
+
+	return &retentionRes{
+		Period:   int64(r.Period / time.Second),
+		Messages: r.Messages,
+	}
 }
 
 func (res viewChannelRes) Code() int {
@@ -192,8 +244,220 @@ func (res disconnectionRes) Empty() bool {
 	return true
 }
 
+type shareRes struct{}
+
+func (res shareRes) Code() int {
+	return http.StatusOK
+}
+
+func (res shareRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res shareRes) Empty() bool {
+	return true
+}
+
+// exportRes streams the exported records when encoded, so that they are not
+// loaded into memory at once.
+type exportRes struct {
+	name   string
+	format string
+	header []string
+	export func(func(record) error) error
+}
+
+type importRes struct {
+	Imported int `json:"imported"`
+}
+
+func (res importRes) Code() int {
+	return http.StatusOK
+}
+
+func (res importRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res importRes) Empty() bool {
+	return false
+}
+
+func deletedAt(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+
+	return &t
+}
+
 type pageRes struct {
-	Total  uint64 `json:"total"`
-	Offset uint64 `json:"offset"`
-	Limit  uint64 `json:"limit"`
+	Total      uint64 `json:"total"`
+	Offset     uint64 `json:"offset"`
+	Limit      uint64 `json:"limit"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+type updateQuotaRes struct{}
+
+func (res updateQuotaRes) Code() int {
+	return http.StatusOK
+}
+
+func (res updateQuotaRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res updateQuotaRes) Empty() bool {
+	return true
+}
+
+type transferRes struct{}
+
+func (res transferRes) Code() int {
+	return http.StatusOK
+}
+
+func (res transferRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res transferRes) Empty() bool {
+	return true
+}
+
+type usageRes struct {
+	Things      uint64 `json:"things"`
+	Channels    uint64 `json:"channels"`
+	Connections uint64 `json:"connections"`
+}
+
+type viewQuotaRes struct {
+	Owner       string   `json:"owner"`
+	Things      uint64   `json:"things"`
+	Channels    uint64   `json:"channels"`
+	Connections uint64   `json:"connections"`
+	Usage       usageRes `json:"usage"`
+}
+
+func (res viewQuotaRes) Code() int {
+	return http.StatusOK
+}
+
+func (res viewQuotaRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res viewQuotaRes) Empty() bool {
+	return false
+}
+
+type saveSchemaRes struct{}
+
+func (res saveSchemaRes) Code() int {
+	return http.StatusOK
+}
+
+func (res saveSchemaRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res saveSchemaRes) Empty() bool {
+	return true
+}
+
+// viewSchemaRes is the registered JSON Schema document itself.
+type viewSchemaRes map[string]interface{}
+
+func (res viewSchemaRes) Code() int {
+	return http.StatusOK
+}
+
+func (res viewSchemaRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res viewSchemaRes) Empty() bool {
+	return false
+}
+
+type payloadSchemaRes struct {
+	Subtopic string                 `json:"subtopic"`
+	Schema   map[string]interface{} `json:"schema"`
+}
+
+type payloadSchemasRes struct {
+	Schemas []payloadSchemaRes `json:"schemas"`
+}
+
+func (res payloadSchemasRes) Code() int {
+	return http.StatusOK
+}
+
+func (res payloadSchemasRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res payloadSchemasRes) Empty() bool {
+	return false
+}
+
+type violationRes struct {
+	Field       string `json:"field"`
+	Description string `json:"description"`
+}
+
+type validationErrorRes struct {
+	Error      string         `json:"error"`
+	Violations []violationRes `json:"violations"`
+}
+
+type changeRes struct {
+	ID        string            `json:"id"`
+	Operation string            `json:"operation"`
+	Fields    map[string]string `json:"fields,omitempty"`
+}
+
+type changesPageRes struct {
+	Changes []changeRes `json:"changes"`
+	Next    string      `json:"next,omitempty"`
+}
+
+func (res changesPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res changesPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res changesPageRes) Empty() bool {
+	return false
+}
+
+type apiUsageRes struct {
+	Method string    `json:"method"`
+	Hour   time.Time `json:"hour"`
+	Calls  uint64    `json:"calls"`
+	Errors uint64    `json:"errors"`
+}
+
+type apiUsagePageRes struct {
+	From   time.Time     `json:"from"`
+	To     time.Time     `json:"to"`
+	Calls  uint64        `json:"calls"`
+	Errors uint64        `json:"errors"`
+	Usage  []apiUsageRes `json:"usage"`
+}
+
+func (res apiUsagePageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res apiUsagePageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res apiUsagePageRes) Empty() bool {
+	return false
 }
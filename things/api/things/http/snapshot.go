@@ -0,0 +1,268 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package http
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/mainflux/mainflux/things"
+)
+
+const (
+	jsonFormat = "json"
+	csvFormat  = "csv"
+
+	csvContentType = "text/csv"
+	actionsSep     = " "
+)
+
+var (
+	thingFields      = []string{"id", "name", "key", "metadata"}
+	channelFields    = []string{"id", "name", "metadata"}
+	connectionFields = []string{"channel_id", "thing_id", "actions"}
+)
+
+// record represents single exported entity. Records are encoded as elements
+// of JSON array or as CSV rows, in which case metadata is JSON encoded.
+type record interface {
+	fields() ([]string, error)
+	parse([]string) error
+}
+
+type thingRecord struct {
+	ID       string                 `json:"id"`
+	Name     string                 `json:"name,omitempty"`
+	Key      string                 `json:"key"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+func (rec thingRecord) fields() ([]string, error) {
+	m, err := encodeMetadata(rec.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	return []string{rec.ID, rec.Name, rec.Key, m}, nil
+}
+
+func (rec *thingRecord) parse(fields []string) error {
+	rec.ID, rec.Name, rec.Key = fields[0], fields[1], fields[2]
+	return decodeMetadata(fields[3], &rec.Metadata)
+}
+
+type channelRecord struct {
+	ID       string                 `json:"id"`
+	Name     string                 `json:"name,omitempty"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+func (rec channelRecord) fields() ([]string, error) {
+	m, err := encodeMetadata(rec.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	return []string{rec.ID, rec.Name, m}, nil
+}
+
+func (rec *channelRecord) parse(fields []string) error {
+	rec.ID, rec.Name = fields[0], fields[1]
+	return decodeMetadata(fields[2], &rec.Metadata)
+}
+
+type connectionRecord struct {
+	ChannelID string   `json:"channel_id"`
+	ThingID   string   `json:"thing_id"`
+	Actions   []string `json:"actions,omitempty"`
+}
+
+func (rec connectionRecord) fields() ([]string, error) {
+	return []string{rec.ChannelID, rec.ThingID, strings.Join(rec.Actions, actionsSep)}, nil
+}
+
+func (rec *connectionRecord) parse(fields []string) error {
+	rec.ChannelID, rec.ThingID = fields[0], fields[1]
+	rec.Actions = strings.Fields(fields[2])
+	return nil
+}
+
+func encodeMetadata(m map[string]interface{}) (string, error) {
+	if len(m) == 0 {
+		return "", nil
+	}
+
+	b, err := json.Marshal(m)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+func decodeMetadata(s string, m *map[string]interface{}) error {
+	if s == "" {
+		return nil
+	}
+
+	if err := json.Unmarshal([]byte(s), m); err != nil {
+		return things.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+// recordWriter streams records to the response in the requested format.
+// Response headers are written along with the first record, so that the
+// errors which occur before it are reported with the proper status code.
+type recordWriter struct {
+	w       http.ResponseWriter
+	format  string
+	name    string
+	header  []string
+	csv     *csv.Writer
+	written int
+}
+
+func newRecordWriter(w http.ResponseWriter, format, name string, header []string) *recordWriter {
+	return &recordWriter{
+		w:      w,
+		format: format,
+		name:   name,
+		header: header,
+	}
+}
+
+func (rw *recordWriter) start() error {
+	ct, ext := contentType, jsonFormat
+	if rw.format == csvFormat {
+		ct, ext = csvContentType, csvFormat
+	}
+
+	rw.w.Header().Set("Content-Type", ct)
+	rw.w.Header().Set("Content-Disposition", "attachment; filename=\""+rw.name+"."+ext+"\"")
+	rw.w.WriteHeader(http.StatusOK)
+
+	if rw.format == csvFormat {
+		rw.csv = csv.NewWriter(rw.w)
+		return rw.csv.Write(rw.header)
+	}
+
+	_, err := io.WriteString(rw.w, "[")
+	return err
+}
+
+func (rw *recordWriter) write(rec record) error {
+	if rw.written == 0 {
+		if err := rw.start(); err != nil {
+			return err
+		}
+	}
+	rw.written++
+
+	if rw.format == csvFormat {
+		fields, err := rec.fields()
+		if err != nil {
+			return err
+		}
+		if err := rw.csv.Write(fields); err != nil {
+			return err
+		}
+		// Flush each row, so that the records are streamed to the client.
+		rw.csv.Flush()
+		return rw.csv.Error()
+	}
+
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	if rw.written > 1 {
+		b = append([]byte(","), b...)
+	}
+
+	_, err = rw.w.Write(b)
+	return err
+}
+
+func (rw *recordWriter) close() error {
+	if rw.written == 0 {
+		if err := rw.start(); err != nil {
+			return err
+		}
+	}
+
+	if rw.format == csvFormat {
+		rw.csv.Flush()
+		return rw.csv.Error()
+	}
+
+	_, err := io.WriteString(rw.w, "]\n")
+	return err
+}
+
+// recordReader reads records from the request body one by one, so that the
+// whole snapshot is never loaded into memory.
+type recordReader struct {
+	json *json.Decoder
+	csv  *csv.Reader
+}
+
+func newRecordReader(format string, r io.Reader, header []string) (*recordReader, error) {
+	if format == csvFormat {
+		cr := csv.NewReader(r)
+		cr.FieldsPerRecord = len(header)
+
+		h, err := cr.Read()
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range header {
+			if h[i] != header[i] {
+				return nil, things.ErrMalformedEntity
+			}
+		}
+
+		return &recordReader{csv: cr}, nil
+	}
+
+	dec := json.NewDecoder(r)
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return nil, things.ErrMalformedEntity
+	}
+
+	return &recordReader{json: dec}, nil
+}
+
+// next reads the next record. It returns io.EOF when there are no more
+// records.
+func (rr *recordReader) next(rec record) error {
+	if rr.csv != nil {
+		fields, err := rr.csv.Read()
+		if err != nil {
+			return err
+		}
+
+		return rec.parse(fields)
+	}
+
+	if !rr.json.More() {
+		if _, err := rr.json.Token(); err != nil {
+			return err
+		}
+		return io.EOF
+	}
+
+	return rr.json.Decode(rec)
+}
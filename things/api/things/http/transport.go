@@ -5,12 +5,14 @@ package http
 
 import (
 	"context"
+	"encoding/csv"
 	"encoding/json"
 	"errors"
 	"io"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	kitot "github.com/go-kit/kit/tracing/opentracing"
 	kithttp "github.com/go-kit/kit/transport/http"
@@ -22,14 +24,29 @@ import (
 )
 
 const (
-	contentType = "application/json"
-	offset      = "offset"
-	limit       = "limit"
-	name        = "name"
-	metadata    = "metadata"
-
-	defOffset = 0
-	defLimit  = 10
+	contentType   = "application/json"
+	offset        = "offset"
+	limit         = "limit"
+	cursor        = "cursor"
+	name          = "name"
+	order         = "order"
+	dir           = "dir"
+	metadata      = "metadata"
+	metadataQuery = "metadata_query"
+	deleted       = "include_deleted"
+	shared        = "shared"
+	query         = "q"
+	format        = "format"
+	since         = "since"
+	from          = "from"
+	to            = "to"
+	subtopic      = "subtopic"
+
+	defOffset     = 0
+	defLimit      = 10
+	defUsageRange = 24 * time.Hour
+
+	consistencyHeader = "X-Consistency-Token"
 )
 
 var (
@@ -37,10 +54,15 @@ var (
 	errInvalidQueryParams     = errors.New("invalid query params")
 )
 
-// MakeHandler returns a HTTP handler for API endpoints.
-func MakeHandler(tracer opentracing.Tracer, svc things.Service) http.Handler {
+// MakeHandler returns a HTTP handler for API endpoints. Successful write
+// requests are answered with the consistency token. Read requests may be
+// served from the read replicas, unless they carry the consistency token that
+// was issued within the provided window.
+func MakeHandler(tracer opentracing.Tracer, svc things.Service, window time.Duration) http.Handler {
 	opts := []kithttp.ServerOption{
 		kithttp.ServerErrorEncoder(encodeError),
+		kithttp.ServerBefore(kithttp.PopulateRequestContext, readConsistency(window)),
+		kithttp.ServerAfter(issueConsistencyToken),
 	}
 
 	r := bone.New()
@@ -59,6 +81,13 @@ func MakeHandler(tracer opentracing.Tracer, svc things.Service) http.Handler {
 		opts...,
 	))
 
+	r.Post("/things/:id/key/rotate", kithttp.NewServer(
+		kitot.TraceServer(tracer, "rotate_key")(rotateKeyEndpoint(svc)),
+		decodeView,
+		encodeResponse,
+		opts...,
+	))
+
 	r.Put("/things/:id", kithttp.NewServer(
 		kitot.TraceServer(tracer, "update_thing")(updateThingEndpoint(svc)),
 		decodeThingUpdate,
@@ -73,6 +102,76 @@ func MakeHandler(tracer opentracing.Tracer, svc things.Service) http.Handler {
 		opts...,
 	))
 
+	r.Get("/things/export", kithttp.NewServer(
+		kitot.TraceServer(tracer, "export_things")(exportThingsEndpoint(svc)),
+		decodeExport,
+		encodeExport,
+		opts...,
+	))
+
+	r.Post("/things/import", kithttp.NewServer(
+		kitot.TraceServer(tracer, "import_things")(importThingsEndpoint(svc)),
+		decodeImport,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/things/search", kithttp.NewServer(
+		kitot.TraceServer(tracer, "search_things")(searchThingsEndpoint(svc)),
+		decodeSearch,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/things/changes", kithttp.NewServer(
+		kitot.TraceServer(tracer, "list_changes")(listChangesEndpoint(svc)),
+		decodeListChanges,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Post("/things/:id/restore", kithttp.NewServer(
+		kitot.TraceServer(tracer, "restore_thing")(restoreThingEndpoint(svc)),
+		decodeView,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Delete("/things/:id/purge", kithttp.NewServer(
+		kitot.TraceServer(tracer, "purge_thing")(purgeThingEndpoint(svc)),
+		decodeView,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Put("/things/:id/shares/:groupId", kithttp.NewServer(
+		kitot.TraceServer(tracer, "share_thing")(shareThingEndpoint(svc)),
+		decodeShare,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Delete("/things/:id/shares/:groupId", kithttp.NewServer(
+		kitot.TraceServer(tracer, "unshare_thing")(unshareThingEndpoint(svc)),
+		decodeUnshare,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Post("/things/:id/share", kithttp.NewServer(
+		kitot.TraceServer(tracer, "share_thing_with_user")(shareThingWithUserEndpoint(svc)),
+		decodeUserShare,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Delete("/things/:id/share/:email", kithttp.NewServer(
+		kitot.TraceServer(tracer, "unshare_thing_with_user")(unshareThingWithUserEndpoint(svc)),
+		decodeUserUnshare,
+		encodeResponse,
+		opts...,
+	))
+
 	r.Get("/things/:id", kithttp.NewServer(
 		kitot.TraceServer(tracer, "view_thing")(viewThingEndpoint(svc)),
 		decodeView,
@@ -115,6 +214,97 @@ func MakeHandler(tracer opentracing.Tracer, svc things.Service) http.Handler {
 		opts...,
 	))
 
+	r.Get("/channels/export", kithttp.NewServer(
+		kitot.TraceServer(tracer, "export_channels")(exportChannelsEndpoint(svc)),
+		decodeExport,
+		encodeExport,
+		opts...,
+	))
+
+	r.Post("/channels/import", kithttp.NewServer(
+		kitot.TraceServer(tracer, "import_channels")(importChannelsEndpoint(svc)),
+		decodeImport,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Post("/channels/direct", kithttp.NewServer(
+		kitot.TraceServer(tracer, "create_direct_channel")(createDirectChannelEndpoint(svc)),
+		decodeDirectChannelCreation,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/channels/search", kithttp.NewServer(
+		kitot.TraceServer(tracer, "search_channels")(searchChannelsEndpoint(svc)),
+		decodeSearch,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Post("/channels/:id/restore", kithttp.NewServer(
+		kitot.TraceServer(tracer, "restore_channel")(restoreChannelEndpoint(svc)),
+		decodeView,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Delete("/channels/:id/purge", kithttp.NewServer(
+		kitot.TraceServer(tracer, "purge_channel")(purgeChannelEndpoint(svc)),
+		decodeView,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Put("/channels/:id/shares/:groupId", kithttp.NewServer(
+		kitot.TraceServer(tracer, "share_channel")(shareChannelEndpoint(svc)),
+		decodeShare,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Delete("/channels/:id/shares/:groupId", kithttp.NewServer(
+		kitot.TraceServer(tracer, "unshare_channel")(unshareChannelEndpoint(svc)),
+		decodeUnshare,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Post("/channels/:id/share", kithttp.NewServer(
+		kitot.TraceServer(tracer, "share_channel_with_user")(shareChannelWithUserEndpoint(svc)),
+		decodeUserShare,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Delete("/channels/:id/share/:email", kithttp.NewServer(
+		kitot.TraceServer(tracer, "unshare_channel_with_user")(unshareChannelWithUserEndpoint(svc)),
+		decodeUserUnshare,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Put("/channels/:id/schemas", kithttp.NewServer(
+		kitot.TraceServer(tracer, "save_payload_schema")(savePayloadSchemaEndpoint(svc)),
+		decodePayloadSchemaSave,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/channels/:id/schemas", kithttp.NewServer(
+		kitot.TraceServer(tracer, "list_payload_schemas")(listPayloadSchemasEndpoint(svc)),
+		decodePayloadSchema,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Delete("/channels/:id/schemas", kithttp.NewServer(
+		kitot.TraceServer(tracer, "remove_payload_schema")(removePayloadSchemaEndpoint(svc)),
+		decodePayloadSchema,
+		encodeResponse,
+		opts...,
+	))
+
 	r.Get("/channels/:id", kithttp.NewServer(
 		kitot.TraceServer(tracer, "view_channel")(viewChannelEndpoint(svc)),
 		decodeView,
@@ -138,7 +328,7 @@ func MakeHandler(tracer opentracing.Tracer, svc things.Service) http.Handler {
 
 	r.Put("/channels/:chanId/things/:thingId", kithttp.NewServer(
 		kitot.TraceServer(tracer, "connect")(connectEndpoint(svc)),
-		decodeConnection,
+		decodeConnect,
 		encodeResponse,
 		opts...,
 	))
@@ -150,7 +340,84 @@ func MakeHandler(tracer opentracing.Tracer, svc things.Service) http.Handler {
 		opts...,
 	))
 
+	r.Get("/connections/export", kithttp.NewServer(
+		kitot.TraceServer(tracer, "export_connections")(exportConnectionsEndpoint(svc)),
+		decodeExport,
+		encodeExport,
+		opts...,
+	))
+
+	r.Post("/connections/import", kithttp.NewServer(
+		kitot.TraceServer(tracer, "import_connections")(importConnectionsEndpoint(svc)),
+		decodeImport,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Put("/quotas/:owner", kithttp.NewServer(
+		kitot.TraceServer(tracer, "update_quota")(updateQuotaEndpoint(svc)),
+		decodeQuotaUpdate,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/quotas/:owner", kithttp.NewServer(
+		kitot.TraceServer(tracer, "view_quota")(viewQuotaEndpoint(svc)),
+		decodeQuota,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Delete("/quotas/:owner", kithttp.NewServer(
+		kitot.TraceServer(tracer, "remove_quota")(removeQuotaEndpoint(svc)),
+		decodeQuota,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Post("/owners/:owner/transfer", kithttp.NewServer(
+		kitot.TraceServer(tracer, "transfer_ownership")(transferOwnershipEndpoint(svc)),
+		decodeTransfer,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Put("/schemas/:entity", kithttp.NewServer(
+		kitot.TraceServer(tracer, "save_schema")(saveSchemaEndpoint(svc)),
+		decodeSchemaSave,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/schemas/:entity", kithttp.NewServer(
+		kitot.TraceServer(tracer, "view_schema")(viewSchemaEndpoint(svc)),
+		decodeSchema,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Delete("/schemas/:entity", kithttp.NewServer(
+		kitot.TraceServer(tracer, "remove_schema")(removeSchemaEndpoint(svc)),
+		decodeSchema,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/usage", kithttp.NewServer(
+		kitot.TraceServer(tracer, "view_api_usage")(viewAPIUsageEndpoint(svc)),
+		decodeAPIUsage,
+		encodeResponse,
+		opts...,
+	))
+
 	r.GetFunc("/version", mainflux.Version("things"))
+	r.GetFunc(mainflux.DiscoveryPath, mainflux.Discovery("things", mainflux.Capabilities{
+		Features: map[string]bool{
+			"read_consistency": window > 0,
+		},
+		ContentTypes: []string{contentType},
+		Limits:       mainflux.Limits{MaxPageSize: maxLimitSize},
+	}))
 	r.Handle("/metrics", promhttp.Handler())
 
 	return r
@@ -214,6 +481,19 @@ func decodeChannelCreation(_ context.Context, r *http.Request) (interface{}, err
 	return req, nil
 }
 
+func decodeDirectChannelCreation(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+
+	req := createDirectChannelReq{token: r.Header.Get("Authorization")}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
 func decodeChannelUpdate(_ context.Context, r *http.Request) (interface{}, error) {
 	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
 		return nil, errUnsupportedContentType
@@ -250,22 +530,84 @@ func decodeList(_ context.Context, r *http.Request) (interface{}, error) {
 		return nil, err
 	}
 
+	c, err := readStringQuery(r, cursor)
+	if err != nil {
+		return nil, err
+	}
+
 	n, err := readStringQuery(r, name)
 	if err != nil {
 		return nil, err
 	}
 
+	or, err := readStringQuery(r, order)
+	if err != nil {
+		return nil, err
+	}
+
+	dr, err := readStringQuery(r, dir)
+	if err != nil {
+		return nil, err
+	}
+
 	m, err := readMetadataQuery(r, "metadata")
 	if err != nil {
 		return nil, err
 	}
 
+	mq, err := readComparisonQuery(r, metadataQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := readBoolQuery(r, deleted)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := readBoolQuery(r, shared)
+	if err != nil {
+		return nil, err
+	}
+
 	req := listResourcesReq{
 		token:    r.Header.Get("Authorization"),
 		offset:   o,
 		limit:    l,
+		cursor:   c,
 		name:     n,
+		order:    or,
+		dir:      dr,
 		metadata: m,
+		query:    mq,
+		deleted:  d,
+		shared:   s,
+	}
+
+	return req, nil
+}
+
+func decodeSearch(_ context.Context, r *http.Request) (interface{}, error) {
+	o, err := readUintQuery(r, offset, defOffset)
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := readUintQuery(r, limit, defLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	q, err := readStringQuery(r, query)
+	if err != nil {
+		return nil, err
+	}
+
+	req := searchReq{
+		token:  r.Header.Get("Authorization"),
+		query:  q,
+		offset: o,
+		limit:  l,
 	}
 
 	return req, nil
@@ -292,6 +634,82 @@ func decodeListByConnection(_ context.Context, r *http.Request) (interface{}, er
 	return req, nil
 }
 
+func decodeShare(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+
+	req := shareReq{
+		token:   r.Header.Get("Authorization"),
+		id:      bone.GetValue(r, "id"),
+		groupID: bone.GetValue(r, "groupId"),
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func decodeUnshare(_ context.Context, r *http.Request) (interface{}, error) {
+	req := unshareReq{
+		token:   r.Header.Get("Authorization"),
+		id:      bone.GetValue(r, "id"),
+		groupID: bone.GetValue(r, "groupId"),
+	}
+
+	return req, nil
+}
+
+func decodeUserShare(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+
+	req := userShareReq{
+		token: r.Header.Get("Authorization"),
+		id:    bone.GetValue(r, "id"),
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func decodeUserUnshare(_ context.Context, r *http.Request) (interface{}, error) {
+	req := userUnshareReq{
+		token: r.Header.Get("Authorization"),
+		id:    bone.GetValue(r, "id"),
+		email: bone.GetValue(r, "email"),
+	}
+
+	return req, nil
+}
+
+func decodeConnect(_ context.Context, r *http.Request) (interface{}, error) {
+	req := connectionReq{
+		token:   r.Header.Get("Authorization"),
+		chanID:  bone.GetValue(r, "chanId"),
+		thingID: bone.GetValue(r, "thingId"),
+	}
+
+	// Allowed actions are optional, all the actions are allowed by default.
+	if r.ContentLength == 0 {
+		return req, nil
+	}
+
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
 func decodeConnection(_ context.Context, r *http.Request) (interface{}, error) {
 	req := connectionReq{
 		token:   r.Header.Get("Authorization"),
@@ -302,6 +720,218 @@ func decodeConnection(_ context.Context, r *http.Request) (interface{}, error) {
 	return req, nil
 }
 
+func decodeQuotaUpdate(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+
+	req := updateQuotaReq{
+		token: r.Header.Get("Authorization"),
+		owner: bone.GetValue(r, "owner"),
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func decodeQuota(_ context.Context, r *http.Request) (interface{}, error) {
+	req := quotaReq{
+		token: r.Header.Get("Authorization"),
+		owner: bone.GetValue(r, "owner"),
+	}
+
+	return req, nil
+}
+
+func decodeTransfer(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+
+	req := transferReq{
+		token: r.Header.Get("Authorization"),
+		from:  bone.GetValue(r, "owner"),
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func decodeSchemaSave(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+
+	req := saveSchemaReq{
+		token:  r.Header.Get("Authorization"),
+		entity: bone.GetValue(r, "entity"),
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req.schema); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func decodeSchema(_ context.Context, r *http.Request) (interface{}, error) {
+	req := schemaReq{
+		token:  r.Header.Get("Authorization"),
+		entity: bone.GetValue(r, "entity"),
+	}
+
+	return req, nil
+}
+
+func decodePayloadSchemaSave(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+
+	s, err := readStringQuery(r, subtopic)
+	if err != nil {
+		return nil, err
+	}
+
+	req := savePayloadSchemaReq{
+		token:    r.Header.Get("Authorization"),
+		chanID:   bone.GetValue(r, "id"),
+		subtopic: s,
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req.schema); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func decodePayloadSchema(_ context.Context, r *http.Request) (interface{}, error) {
+	s, err := readStringQuery(r, subtopic)
+	if err != nil {
+		return nil, err
+	}
+
+	req := payloadSchemaReq{
+		token:    r.Header.Get("Authorization"),
+		chanID:   bone.GetValue(r, "id"),
+		subtopic: s,
+	}
+
+	return req, nil
+}
+
+func decodeListChanges(_ context.Context, r *http.Request) (interface{}, error) {
+	l, err := readUintQuery(r, limit, defLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := readStringQuery(r, since)
+	if err != nil {
+		return nil, err
+	}
+
+	req := listChangesReq{
+		token: r.Header.Get("Authorization"),
+		since: s,
+		limit: l,
+	}
+
+	return req, nil
+}
+
+// decodeAPIUsage reads the time range of the API usage. By default, the
+// usage of the last day, including the current hour, is viewed.
+func decodeAPIUsage(_ context.Context, r *http.Request) (interface{}, error) {
+	t, err := readTimeQuery(r, to, time.Now().UTC().Truncate(time.Hour).Add(time.Hour))
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := readTimeQuery(r, from, t.Add(-defUsageRange))
+	if err != nil {
+		return nil, err
+	}
+
+	req := apiUsageReq{
+		token: r.Header.Get("Authorization"),
+		from:  f,
+		to:    t,
+	}
+
+	return req, nil
+}
+
+func decodeExport(_ context.Context, r *http.Request) (interface{}, error) {
+	f, err := readStringQuery(r, format)
+	if err != nil {
+		return nil, err
+	}
+
+	if f == "" {
+		f = jsonFormat
+	}
+
+	req := exportReq{
+		token:  r.Header.Get("Authorization"),
+		format: f,
+	}
+
+	return req, nil
+}
+
+func decodeImport(_ context.Context, r *http.Request) (interface{}, error) {
+	ct := r.Header.Get("Content-Type")
+
+	f := jsonFormat
+	switch {
+	case strings.Contains(ct, contentType):
+	case strings.Contains(ct, csvContentType):
+		f = csvFormat
+	default:
+		return nil, errUnsupportedContentType
+	}
+
+	req := importReq{
+		token:  r.Header.Get("Authorization"),
+		format: f,
+		body:   r.Body,
+	}
+
+	return req, nil
+}
+
+// readConsistency permits replica reads for the read requests, unless they
+// carry the consistency token issued within the window, so that the clients
+// can read their own writes.
+func readConsistency(window time.Duration) kithttp.RequestFunc {
+	return func(ctx context.Context, r *http.Request) context.Context {
+		if r.Method != http.MethodGet {
+			return ctx
+		}
+
+		issued, err := strconv.ParseInt(r.Header.Get(consistencyHeader), 10, 64)
+		if err == nil && time.Since(time.Unix(0, issued)) < window {
+			return ctx
+		}
+
+		return things.WithReplicaReads(ctx)
+	}
+}
+
+// issueConsistencyToken sets the consistency token header of the successful
+// write response. Token is the time of the write.
+func issueConsistencyToken(ctx context.Context, w http.ResponseWriter) context.Context {
+	if method, _ := ctx.Value(kithttp.ContextKeyRequestMethod).(string); method != http.MethodGet {
+		w.Header().Set(consistencyHeader, strconv.FormatInt(time.Now().UnixNano(), 10))
+	}
+
+	return ctx
+}
+
 func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
 	w.Header().Set("Content-Type", contentType)
 
@@ -320,6 +950,17 @@ func encodeResponse(_ context.Context, w http.ResponseWriter, response interface
 	return json.NewEncoder(w).Encode(response)
 }
 
+func encodeExport(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	res := response.(exportRes)
+
+	rw := newRecordWriter(w, res.format, res.name, res.header)
+	if err := res.export(rw.write); err != nil {
+		return err
+	}
+
+	return rw.close()
+}
+
 func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 	w.Header().Set("Content-Type", contentType)
 
@@ -332,6 +973,12 @@ func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 		w.WriteHeader(http.StatusNotFound)
 	case things.ErrConflict:
 		w.WriteHeader(http.StatusUnprocessableEntity)
+	case things.ErrDirectChannel, things.ErrActiveOwner:
+		w.WriteHeader(http.StatusConflict)
+	case things.ErrQuotaExceeded:
+		w.WriteHeader(http.StatusTooManyRequests)
+	case things.ErrChangesExpired:
+		w.WriteHeader(http.StatusGone)
 	case errUnsupportedContentType:
 		w.WriteHeader(http.StatusUnsupportedMediaType)
 	case errInvalidQueryParams:
@@ -341,17 +988,39 @@ func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 	case io.EOF:
 		w.WriteHeader(http.StatusBadRequest)
 	default:
-		switch err.(type) {
+		switch e := err.(type) {
+		case *things.ValidationError:
+			encodeValidationError(e, w)
 		case *json.SyntaxError:
 			w.WriteHeader(http.StatusBadRequest)
 		case *json.UnmarshalTypeError:
 			w.WriteHeader(http.StatusBadRequest)
+		case *csv.ParseError:
+			w.WriteHeader(http.StatusBadRequest)
 		default:
 			w.WriteHeader(http.StatusInternalServerError)
 		}
 	}
 }
 
+// encodeValidationError responds with the violations of the metadata schema,
+// so that clients can tell which metadata fields are invalid.
+func encodeValidationError(err *things.ValidationError, w http.ResponseWriter) {
+	res := validationErrorRes{
+		Error:      err.Error(),
+		Violations: []violationRes{},
+	}
+	for _, v := range err.Violations {
+		res.Violations = append(res.Violations, violationRes{
+			Field:       v.Field,
+			Description: v.Description,
+		})
+	}
+
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(res)
+}
+
 func readUintQuery(r *http.Request, key string, def uint64) (uint64, error) {
 	vals := bone.GetQuery(r, key)
 	if len(vals) > 1 {
@@ -384,6 +1053,43 @@ func readStringQuery(r *http.Request, key string) (string, error) {
 	return vals[0], nil
 }
 
+func readBoolQuery(r *http.Request, key string) (bool, error) {
+	vals := bone.GetQuery(r, key)
+	if len(vals) > 1 {
+		return false, errInvalidQueryParams
+	}
+
+	if len(vals) == 0 {
+		return false, nil
+	}
+
+	b, err := strconv.ParseBool(vals[0])
+	if err != nil {
+		return false, errInvalidQueryParams
+	}
+
+	return b, nil
+}
+
+// readTimeQuery parses the RFC 3339 timestamp query parameter.
+func readTimeQuery(r *http.Request, key string, def time.Time) (time.Time, error) {
+	vals := bone.GetQuery(r, key)
+	if len(vals) > 1 {
+		return time.Time{}, errInvalidQueryParams
+	}
+
+	if len(vals) == 0 {
+		return def, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, vals[0])
+	if err != nil {
+		return time.Time{}, errInvalidQueryParams
+	}
+
+	return t, nil
+}
+
 func readMetadataQuery(r *http.Request, key string) (map[string]interface{}, error) {
 	vals := bone.GetQuery(r, key)
 	if len(vals) > 1 {
@@ -402,3 +1108,23 @@ func readMetadataQuery(r *http.Request, key string) (map[string]interface{}, err
 
 	return m, nil
 }
+
+// readComparisonQuery parses all the values of the query parameter, since
+// the parameter can be repeated to combine multiple comparisons.
+func readComparisonQuery(r *http.Request, key string) ([]things.MetadataQuery, error) {
+	vals := bone.GetQuery(r, key)
+	if len(vals) == 0 {
+		return nil, nil
+	}
+
+	mqs := make([]things.MetadataQuery, len(vals))
+	for i, v := range vals {
+		q, err := things.ParseMetadataQuery(v)
+		if err != nil {
+			return nil, errInvalidQueryParams
+		}
+		mqs[i] = q
+	}
+
+	return mqs, nil
+}
@@ -0,0 +1,588 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// +build !test
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	log "github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/things"
+)
+
+// ownerTTL is the period after which the owner of the token is identified
+// again, so that the tokens that are no longer used are forgotten.
+const ownerTTL = 10 * time.Minute
+
+var _ things.Service = (*usageMiddleware)(nil)
+
+type usageKey struct {
+	owner  string
+	method string
+	hour   time.Time
+}
+
+type cachedOwner struct {
+	id      string
+	expires time.Time
+}
+
+type usageMiddleware struct {
+	users  mainflux.UsersServiceClient
+	repo   things.APIUsageRepository
+	logger log.Logger
+	mu     sync.Mutex
+	owners map[string]cachedOwner
+	usage  map[usageKey]things.APIUsage
+	svc    things.Service
+}
+
+// UsageMiddleware counts the API calls of the users, and the calls that
+// failed, per method and hour. The counts are aggregated in memory and added
+// to the repository once per the provided period. The calls of the things,
+// and the calls with invalid tokens, are not counted.
+func UsageMiddleware(svc things.Service, users mainflux.UsersServiceClient, repo things.APIUsageRepository, period time.Duration, logger log.Logger) things.Service {
+	um := &usageMiddleware{
+		users:  users,
+		repo:   repo,
+		logger: logger,
+		owners: make(map[string]cachedOwner),
+		usage:  make(map[usageKey]things.APIUsage),
+		svc:    svc,
+	}
+
+	go func() {
+		for range time.Tick(period) {
+			um.flush()
+		}
+	}()
+
+	return um
+}
+
+func (um *usageMiddleware) record(ctx context.Context, token, method string, err error) {
+	owner := um.owner(ctx, token)
+	if owner == "" {
+		return
+	}
+
+	key := usageKey{
+		owner:  owner,
+		method: method,
+		hour:   time.Now().UTC().Truncate(time.Hour),
+	}
+
+	um.mu.Lock()
+	defer um.mu.Unlock()
+
+	u := um.usage[key]
+	u.Calls++
+	if err != nil {
+		u.Errors++
+	}
+	um.usage[key] = u
+}
+
+func (um *usageMiddleware) owner(ctx context.Context, token string) string {
+	um.mu.Lock()
+	cached, ok := um.owners[token]
+	um.mu.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		return cached.id
+	}
+
+	res, err := um.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return ""
+	}
+
+	um.mu.Lock()
+	um.owners[token] = cachedOwner{id: res.GetValue(), expires: time.Now().Add(ownerTTL)}
+	um.mu.Unlock()
+
+	return res.GetValue()
+}
+
+// flush saves the aggregated usage. If saving fails, the usage is kept to
+// be saved along with the calls counted during the next period.
+func (um *usageMiddleware) flush() {
+	um.mu.Lock()
+	pending := um.usage
+	um.usage = make(map[usageKey]things.APIUsage)
+	now := time.Now()
+	for token, cached := range um.owners {
+		if now.After(cached.expires) {
+			delete(um.owners, token)
+		}
+	}
+	um.mu.Unlock()
+
+	if len(pending) == 0 {
+		return
+	}
+
+	usage := make([]things.APIUsage, 0, len(pending))
+	for key, u := range pending {
+		u.Owner, u.Method, u.Hour = key.owner, key.method, key.hour
+		usage = append(usage, u)
+	}
+
+	if err := um.repo.Save(context.Background(), usage...); err != nil {
+		um.logger.Warn(fmt.Sprintf("Failed to save API usage: %s", err))
+
+		um.mu.Lock()
+		for key, u := range pending {
+			cur := um.usage[key]
+			cur.Calls += u.Calls
+			cur.Errors += u.Errors
+			um.usage[key] = cur
+		}
+		um.mu.Unlock()
+	}
+}
+
+func (um *usageMiddleware) AddThing(ctx context.Context, token string, thing things.Thing) (_ things.Thing, err error) {
+	defer func() {
+		um.record(ctx, token, "add_thing", err)
+	}()
+
+	return um.svc.AddThing(ctx, token, thing)
+}
+
+func (um *usageMiddleware) UpdateThing(ctx context.Context, token string, thing things.Thing) (err error) {
+	defer func() {
+		um.record(ctx, token, "update_thing", err)
+	}()
+
+	return um.svc.UpdateThing(ctx, token, thing)
+}
+
+func (um *usageMiddleware) UpdateKey(ctx context.Context, token, id, key string) (err error) {
+	defer func() {
+		um.record(ctx, token, "update_key", err)
+	}()
+
+	return um.svc.UpdateKey(ctx, token, id, key)
+}
+
+func (um *usageMiddleware) RotateKey(ctx context.Context, token, id string) (_ string, err error) {
+	defer func() {
+		um.record(ctx, token, "rotate_key", err)
+	}()
+
+	return um.svc.RotateKey(ctx, token, id)
+}
+
+func (um *usageMiddleware) ViewThing(ctx context.Context, token, id string) (_ things.Thing, err error) {
+	defer func() {
+		um.record(ctx, token, "view_thing", err)
+	}()
+
+	return um.svc.ViewThing(ctx, token, id)
+}
+
+func (um *usageMiddleware) ListThings(ctx context.Context, token string, pq things.PageQuery) (_ things.ThingsPage, err error) {
+	defer func() {
+		um.record(ctx, token, "list_things", err)
+	}()
+
+	return um.svc.ListThings(ctx, token, pq)
+}
+
+func (um *usageMiddleware) SearchThings(ctx context.Context, token, query string, offset, limit uint64) (_ things.ThingsPage, err error) {
+	defer func() {
+		um.record(ctx, token, "search_things", err)
+	}()
+
+	return um.svc.SearchThings(ctx, token, query, offset, limit)
+}
+
+func (um *usageMiddleware) ListThingsByChannel(ctx context.Context, token, id string, offset, limit uint64) (_ things.ThingsPage, err error) {
+	defer func() {
+		um.record(ctx, token, "list_things_by_channel", err)
+	}()
+
+	return um.svc.ListThingsByChannel(ctx, token, id, offset, limit)
+}
+
+func (um *usageMiddleware) RemoveThing(ctx context.Context, token, id string) (err error) {
+	defer func() {
+		um.record(ctx, token, "remove_thing", err)
+	}()
+
+	return um.svc.RemoveThing(ctx, token, id)
+}
+
+func (um *usageMiddleware) RestoreThing(ctx context.Context, token, id string) (err error) {
+	defer func() {
+		um.record(ctx, token, "restore_thing", err)
+	}()
+
+	return um.svc.RestoreThing(ctx, token, id)
+}
+
+func (um *usageMiddleware) PurgeThing(ctx context.Context, token, id string) (err error) {
+	defer func() {
+		um.record(ctx, token, "purge_thing", err)
+	}()
+
+	return um.svc.PurgeThing(ctx, token, id)
+}
+
+func (um *usageMiddleware) ShareThing(ctx context.Context, token, id, group, permission string) (err error) {
+	defer func() {
+		um.record(ctx, token, "share_thing", err)
+	}()
+
+	return um.svc.ShareThing(ctx, token, id, group, permission)
+}
+
+func (um *usageMiddleware) UnshareThing(ctx context.Context, token, id, group string) (err error) {
+	defer func() {
+		um.record(ctx, token, "unshare_thing", err)
+	}()
+
+	return um.svc.UnshareThing(ctx, token, id, group)
+}
+
+func (um *usageMiddleware) ShareThingWithUser(ctx context.Context, token, id, email, permission string) (err error) {
+	defer func() {
+		um.record(ctx, token, "share_thing_with_user", err)
+	}()
+
+	return um.svc.ShareThingWithUser(ctx, token, id, email, permission)
+}
+
+func (um *usageMiddleware) UnshareThingWithUser(ctx context.Context, token, id, email string) (err error) {
+	defer func() {
+		um.record(ctx, token, "unshare_thing_with_user", err)
+	}()
+
+	return um.svc.UnshareThingWithUser(ctx, token, id, email)
+}
+
+func (um *usageMiddleware) ExportThings(ctx context.Context, token string, fn func(things.Thing) error) (err error) {
+	defer func() {
+		um.record(ctx, token, "export_things", err)
+	}()
+
+	return um.svc.ExportThings(ctx, token, fn)
+}
+
+func (um *usageMiddleware) ImportThings(ctx context.Context, token string, ths []things.Thing) (_ []things.Thing, err error) {
+	defer func() {
+		um.record(ctx, token, "import_things", err)
+	}()
+
+	return um.svc.ImportThings(ctx, token, ths)
+}
+
+func (um *usageMiddleware) CreateChannel(ctx context.Context, token string, channel things.Channel) (_ things.Channel, err error) {
+	defer func() {
+		um.record(ctx, token, "create_channel", err)
+	}()
+
+	return um.svc.CreateChannel(ctx, token, channel)
+}
+
+func (um *usageMiddleware) CreateDirectChannel(ctx context.Context, token, thing1, thing2 string) (_ things.Channel, err error) {
+	defer func() {
+		um.record(ctx, token, "create_direct_channel", err)
+	}()
+
+	return um.svc.CreateDirectChannel(ctx, token, thing1, thing2)
+}
+
+func (um *usageMiddleware) UpdateChannel(ctx context.Context, token string, channel things.Channel) (err error) {
+	defer func() {
+		um.record(ctx, token, "update_channel", err)
+	}()
+
+	return um.svc.UpdateChannel(ctx, token, channel)
+}
+
+func (um *usageMiddleware) ViewChannel(ctx context.Context, token, id string) (_ things.Channel, err error) {
+	defer func() {
+		um.record(ctx, token, "view_channel", err)
+	}()
+
+	return um.svc.ViewChannel(ctx, token, id)
+}
+
+func (um *usageMiddleware) ListChannels(ctx context.Context, token string, pq things.PageQuery) (_ things.ChannelsPage, err error) {
+	defer func() {
+		um.record(ctx, token, "list_channels", err)
+	}()
+
+	return um.svc.ListChannels(ctx, token, pq)
+}
+
+func (um *usageMiddleware) SearchChannels(ctx context.Context, token, query string, offset, limit uint64) (_ things.ChannelsPage, err error) {
+	defer func() {
+		um.record(ctx, token, "search_channels", err)
+	}()
+
+	return um.svc.SearchChannels(ctx, token, query, offset, limit)
+}
+
+func (um *usageMiddleware) ListChannelsByThing(ctx context.Context, token, id string, offset, limit uint64) (_ things.ChannelsPage, err error) {
+	defer func() {
+		um.record(ctx, token, "list_channels_by_thing", err)
+	}()
+
+	return um.svc.ListChannelsByThing(ctx, token, id, offset, limit)
+}
+
+func (um *usageMiddleware) RemoveChannel(ctx context.Context, token, id string) (err error) {
+	defer func() {
+		um.record(ctx, token, "remove_channel", err)
+	}()
+
+	return um.svc.RemoveChannel(ctx, token, id)
+}
+
+func (um *usageMiddleware) RestoreChannel(ctx context.Context, token, id string) (err error) {
+	defer func() {
+		um.record(ctx, token, "restore_channel", err)
+	}()
+
+	return um.svc.RestoreChannel(ctx, token, id)
+}
+
+func (um *usageMiddleware) PurgeChannel(ctx context.Context, token, id string) (err error) {
+	defer func() {
+		um.record(ctx, token, "purge_channel", err)
+	}()
+
+	return um.svc.PurgeChannel(ctx, token, id)
+}
+
+func (um *usageMiddleware) ShareChannel(ctx context.Context, token, id, group, permission string) (err error) {
+	defer func() {
+		um.record(ctx, token, "share_channel", err)
+	}()
+
+	return um.svc.ShareChannel(ctx, token, id, group, permission)
+}
+
+func (um *usageMiddleware) UnshareChannel(ctx context.Context, token, id, group string) (err error) {
+	defer func() {
+		um.record(ctx, token, "unshare_channel", err)
+	}()
+
+	return um.svc.UnshareChannel(ctx, token, id, group)
+}
+
+func (um *usageMiddleware) ShareChannelWithUser(ctx context.Context, token, id, email, permission string) (err error) {
+	defer func() {
+		um.record(ctx, token, "share_channel_with_user", err)
+	}()
+
+	return um.svc.ShareChannelWithUser(ctx, token, id, email, permission)
+}
+
+func (um *usageMiddleware) UnshareChannelWithUser(ctx context.Context, token, id, email string) (err error) {
+	defer func() {
+		um.record(ctx, token, "unshare_channel_with_user", err)
+	}()
+
+	return um.svc.UnshareChannelWithUser(ctx, token, id, email)
+}
+
+func (um *usageMiddleware) ExportChannels(ctx context.Context, token string, fn func(things.Channel) error) (err error) {
+	defer func() {
+		um.record(ctx, token, "export_channels", err)
+	}()
+
+	return um.svc.ExportChannels(ctx, token, fn)
+}
+
+func (um *usageMiddleware) ImportChannels(ctx context.Context, token string, chs []things.Channel) (_ []things.Channel, err error) {
+	defer func() {
+		um.record(ctx, token, "import_channels", err)
+	}()
+
+	return um.svc.ImportChannels(ctx, token, chs)
+}
+
+func (um *usageMiddleware) Connect(ctx context.Context, token, chanID, thingID string, actions []string) (err error) {
+	defer func() {
+		um.record(ctx, token, "connect", err)
+	}()
+
+	return um.svc.Connect(ctx, token, chanID, thingID, actions)
+}
+
+func (um *usageMiddleware) Disconnect(ctx context.Context, token, chanID, thingID string) (err error) {
+	defer func() {
+		um.record(ctx, token, "disconnect", err)
+	}()
+
+	return um.svc.Disconnect(ctx, token, chanID, thingID)
+}
+
+func (um *usageMiddleware) ExportConnections(ctx context.Context, token string, fn func(things.Connection) error) (err error) {
+	defer func() {
+		um.record(ctx, token, "export_connections", err)
+	}()
+
+	return um.svc.ExportConnections(ctx, token, fn)
+}
+
+func (um *usageMiddleware) ImportConnections(ctx context.Context, token string, conns []things.Connection) (_ []things.Connection, err error) {
+	defer func() {
+		um.record(ctx, token, "import_connections", err)
+	}()
+
+	return um.svc.ImportConnections(ctx, token, conns)
+}
+
+func (um *usageMiddleware) CanAccess(ctx context.Context, id, key, action string) (string, error) {
+	return um.svc.CanAccess(ctx, id, key, action)
+}
+
+func (um *usageMiddleware) CanAccessByID(ctx context.Context, chanID, thingID, action string) error {
+	return um.svc.CanAccessByID(ctx, chanID, thingID, action)
+}
+
+func (um *usageMiddleware) Identify(ctx context.Context, key string) (string, error) {
+	return um.svc.Identify(ctx, key)
+}
+
+func (um *usageMiddleware) Owner(ctx context.Context, id string) (string, error) {
+	return um.svc.Owner(ctx, id)
+}
+
+func (um *usageMiddleware) KeyDigest(ctx context.Context, id string) (string, error) {
+	return um.svc.KeyDigest(ctx, id)
+}
+
+func (um *usageMiddleware) Retention(ctx context.Context, id string) (things.Retention, error) {
+	return um.svc.Retention(ctx, id)
+}
+
+func (um *usageMiddleware) Region(ctx context.Context, id string) (string, error) {
+	return um.svc.Region(ctx, id)
+}
+
+func (um *usageMiddleware) Alias(ctx context.Context, id string) (string, error) {
+	return um.svc.Alias(ctx, id)
+}
+
+func (um *usageMiddleware) ResolveAlias(ctx context.Context, alias string) (string, error) {
+	return um.svc.ResolveAlias(ctx, alias)
+}
+
+func (um *usageMiddleware) Ordering(ctx context.Context, id string) (bool, error) {
+	return um.svc.Ordering(ctx, id)
+}
+
+func (um *usageMiddleware) Transformer(ctx context.Context, id string) (things.Transformer, error) {
+	return um.svc.Transformer(ctx, id)
+}
+
+func (um *usageMiddleware) UpdateQuota(ctx context.Context, token string, quota things.Quota) (err error) {
+	defer func() {
+		um.record(ctx, token, "update_quota", err)
+	}()
+
+	return um.svc.UpdateQuota(ctx, token, quota)
+}
+
+func (um *usageMiddleware) ViewQuota(ctx context.Context, token, owner string) (_ things.Quota, _ things.Usage, err error) {
+	defer func() {
+		um.record(ctx, token, "view_quota", err)
+	}()
+
+	return um.svc.ViewQuota(ctx, token, owner)
+}
+
+func (um *usageMiddleware) RemoveQuota(ctx context.Context, token, owner string) (err error) {
+	defer func() {
+		um.record(ctx, token, "remove_quota", err)
+	}()
+
+	return um.svc.RemoveQuota(ctx, token, owner)
+}
+
+func (um *usageMiddleware) TransferOwnership(ctx context.Context, token, from, to string) (err error) {
+	defer func() {
+		um.record(ctx, token, "transfer_ownership", err)
+	}()
+
+	return um.svc.TransferOwnership(ctx, token, from, to)
+}
+
+func (um *usageMiddleware) SaveSchema(ctx context.Context, token string, schema things.MetadataSchema) (err error) {
+	defer func() {
+		um.record(ctx, token, "save_schema", err)
+	}()
+
+	return um.svc.SaveSchema(ctx, token, schema)
+}
+
+func (um *usageMiddleware) ViewSchema(ctx context.Context, token, entity string) (_ things.MetadataSchema, err error) {
+	defer func() {
+		um.record(ctx, token, "view_schema", err)
+	}()
+
+	return um.svc.ViewSchema(ctx, token, entity)
+}
+
+func (um *usageMiddleware) RemoveSchema(ctx context.Context, token, entity string) (err error) {
+	defer func() {
+		um.record(ctx, token, "remove_schema", err)
+	}()
+
+	return um.svc.RemoveSchema(ctx, token, entity)
+}
+
+func (um *usageMiddleware) SavePayloadSchema(ctx context.Context, token string, schema things.PayloadSchema) (err error) {
+	defer func() {
+		um.record(ctx, token, "save_payload_schema", err)
+	}()
+
+	return um.svc.SavePayloadSchema(ctx, token, schema)
+}
+
+func (um *usageMiddleware) ListPayloadSchemas(ctx context.Context, token, chanID string) (_ []things.PayloadSchema, err error) {
+	defer func() {
+		um.record(ctx, token, "list_payload_schemas", err)
+	}()
+
+	return um.svc.ListPayloadSchemas(ctx, token, chanID)
+}
+
+func (um *usageMiddleware) RemovePayloadSchema(ctx context.Context, token, chanID, subtopic string) (err error) {
+	defer func() {
+		um.record(ctx, token, "remove_payload_schema", err)
+	}()
+
+	return um.svc.RemovePayloadSchema(ctx, token, chanID, subtopic)
+}
+
+func (um *usageMiddleware) PayloadSchema(ctx context.Context, chanID, subtopic string) (things.PayloadSchema, error) {
+	return um.svc.PayloadSchema(ctx, chanID, subtopic)
+}
+
+func (um *usageMiddleware) ListChanges(ctx context.Context, token, since string, limit uint64) (_ things.ChangesPage, err error) {
+	defer func() {
+		um.record(ctx, token, "list_changes", err)
+	}()
+
+	return um.svc.ListChanges(ctx, token, since, limit)
+}
+
+func (um *usageMiddleware) ViewAPIUsage(ctx context.Context, token string, from, to time.Time) (_ []things.APIUsage, err error) {
+	defer func() {
+		um.record(ctx, token, "view_api_usage", err)
+	}()
+
+	return um.svc.ViewAPIUsage(ctx, token, from, to)
+}
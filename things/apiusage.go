@@ -0,0 +1,36 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package things
+
+import (
+	"context"
+	"time"
+)
+
+// maxUsageRange is the longest time range of the API usage that can be
+// viewed at once.
+const maxUsageRange = 31 * 24 * time.Hour
+
+// APIUsage contains the number of calls of the API method the owner made
+// during the hour starting at the provided time, and the number of the calls
+// that failed.
+type APIUsage struct {
+	Owner  string
+	Method string
+	Hour   time.Time
+	Calls  uint64
+	Errors uint64
+}
+
+// APIUsageRepository specifies an API usage persistence API.
+type APIUsageRepository interface {
+	// Save adds the calls and errors of the provided usage to the ones that
+	// are already stored for the same owner, method and hour.
+	Save(context.Context, ...APIUsage) error
+
+	// RetrieveByOwner retrieves the usage of the owner during the hours
+	// starting at or after the first, and before the second provided time,
+	// ordered by hour and method.
+	RetrieveByOwner(context.Context, string, time.Time, time.Time) ([]APIUsage, error)
+}
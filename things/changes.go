@@ -0,0 +1,40 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package things
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrChangesExpired indicates that the changes following the provided
+// position are no longer retained, so the entities have to be listed anew.
+var ErrChangesExpired = errors.New("changes are no longer available")
+
+// Change represents the change of the entity recorded in the event stream.
+type Change struct {
+	// ID is the position of the change in the change log. Changes are
+	// ordered by their IDs, so the ID is used to resume the retrieval.
+	ID        string
+	Operation string
+	Fields    map[string]string
+}
+
+// ChangesPage contains the page of changes. Next holds the position to
+// resume the retrieval from and is equal to the requested position if no
+// changes were recorded since.
+type ChangesPage struct {
+	Changes []Change
+	Next    string
+}
+
+// ChangeLog specifies an API for the retrieval of recorded entity changes.
+type ChangeLog interface {
+	// RetrieveAll retrieves at most limit changes of the entities belonging
+	// to the owner, recorded after the provided position. Empty position
+	// retrieves the changes from the oldest retained one. ErrChangesExpired
+	// is returned if the changes following the position are not retained
+	// anymore.
+	RetrieveAll(ctx context.Context, owner, since string, limit uint64) (ChangesPage, error)
+}
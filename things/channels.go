@@ -3,15 +3,134 @@
 
 package things
 
-import "context"
+import (
+	"context"
+	"errors"
+	"math"
+	"regexp"
+	"time"
+)
+
+const (
+	// Publish action allows thing to publish messages to the channel.
+	Publish = "publish"
+	// Subscribe action allows thing to receive messages published to the
+	// channel.
+	Subscribe = "subscribe"
+	// ReadHistory action allows thing to read the stored channel messages.
+	ReadHistory = "read_history"
+
+	// DirectChannel is the type of the channel connecting exactly two things,
+	// such as the device and its companion app.
+	DirectChannel = "direct"
+
+	transformerKey = "transformer"
+)
+
+// ErrDirectChannel indicates that the operation isn't allowed on the direct
+// channel, such as connecting a thing other than its peers.
+var ErrDirectChannel = errors.New("operation not allowed on direct channel")
+
+var (
+	regionRegexp = regexp.MustCompile(`^([a-z0-9]([a-z0-9\-]{0,62}[a-z0-9])?)?$`)
+	aliasRegexp  = regexp.MustCompile(`^[A-Za-z0-9_\-]{0,32}$`)
+)
+
+// Actions contains all the actions that can be allowed to the connected
+// thing. Connections allow all the actions unless specified otherwise.
+var Actions = []string{Publish, Subscribe, ReadHistory}
 
 // Channel represents a Mainflux "communication group". This group contains the
-// things that can exchange messages between eachother.
+// things that can exchange messages between eachother. Channels homed to the
+// region are served by the cluster of that region, while the channels without
+// the region are served by any cluster. The alias is a short, unique name
+// that the protocol adapters can accept in place of the channel ID. Direct
+// channels are provisioned for the pair of things, their peers, and can't be
+// connected to any other thing nor shared. Messages of the ordered channels
+// are numbered by the adapters, so that the consumers can detect the gaps and
+// the reordering. Removed channels keep the time of removal until they are
+// purged.
 type Channel struct {
-	ID       string
-	Owner    string
-	Name     string
-	Metadata map[string]interface{}
+	ID        string
+	Owner     string
+	Name      string
+	Metadata  map[string]interface{}
+	Retention Retention
+	Region    string
+	Alias     string
+	Ordered   bool
+	Type      string
+	Peers     []string
+	DeletedAt time.Time
+}
+
+// isPeer determines whether the thing is allowed to be connected to the
+// channel. Any thing can be connected to the channel that isn't direct.
+func (ch Channel) isPeer(thingID string) bool {
+	if ch.Type != DirectChannel {
+		return true
+	}
+
+	for _, p := range ch.Peers {
+		if p == thingID {
+			return true
+		}
+	}
+
+	return false
+}
+
+func validRegion(region string) bool {
+	return regionRegexp.MatchString(region)
+}
+
+func validAlias(alias string) bool {
+	return aliasRegexp.MatchString(alias)
+}
+
+// Retention limits the messages of the channel kept by the message writers.
+// Messages older than the period, as well as the messages exceeding the
+// count, are removed from the message stores. Zero values impose no limit.
+type Retention struct {
+	Period   time.Duration
+	Messages uint64
+}
+
+func (r Retention) valid() bool {
+	return r.Period >= 0 && r.Period%time.Second == 0 && r.Messages <= math.MaxInt64
+}
+
+// Transformer specifies how the normalizer structures the payloads of the
+// channel messages which aren't SenML. It is set by the transformer object of
+// the channel metadata. The schema and the message name the registered
+// Protobuf schema and its message type, while the time names the payload
+// field holding the message time.
+type Transformer struct {
+	Format  string
+	Schema  string
+	Message string
+	Time    string
+}
+
+// transformer extracts the transformer from the channel metadata. Missing
+// or malformed transformer is the empty one.
+func transformer(md Metadata) Transformer {
+	obj, ok := md[transformerKey].(map[string]interface{})
+	if !ok {
+		return Transformer{}
+	}
+
+	str := func(key string) string {
+		s, _ := obj[key].(string)
+		return s
+	}
+
+	return Transformer{
+		Format:  str("format"),
+		Schema:  str("schema"),
+		Message: str("message"),
+		Time:    str("time"),
+	}
 }
 
 // ChannelsPage contains page related metadata as well as list of channels that
@@ -21,6 +140,14 @@ type ChannelsPage struct {
 	Channels []Channel
 }
 
+// Connection represents connection between the channel and the thing, along
+// with the actions the thing is allowed to perform on the channel.
+type Connection struct {
+	ChannelID string
+	ThingID   string
+	Actions   []string
+}
+
 // ChannelRepository specifies a channel persistence API.
 type ChannelRepository interface {
 	// Save persists the channel. Successful operation is indicated by unique
@@ -36,44 +163,132 @@ type ChannelRepository interface {
 	// by the specified user.
 	RetrieveByID(context.Context, string, string) (Channel, error)
 
-	// RetrieveAll retrieves the subset of channels owned by the specified user.
-	RetrieveAll(context.Context, string, uint64, uint64, string, Metadata) (ChannelsPage, error)
+	// RetrieveShared retrieves the channel having the provided identifier,
+	// that is shared with the specified user or any of the provided groups,
+	// along with the widest permission granted to them.
+	RetrieveShared(context.Context, string, string, []string) (Channel, string, error)
+
+	// RetrieveRetention retrieves the retention of the channel having the
+	// provided identifier.
+	RetrieveRetention(context.Context, string) (Retention, error)
+
+	// RetrieveRegion retrieves the region of the channel having the provided
+	// identifier.
+	RetrieveRegion(context.Context, string) (string, error)
+
+	// RetrieveAlias retrieves the alias of the channel having the provided
+	// identifier.
+	RetrieveAlias(context.Context, string) (string, error)
+
+	// RetrieveOrdering retrieves whether the channel having the provided
+	// identifier is ordered.
+	RetrieveOrdering(context.Context, string) (bool, error)
+
+	// RetrieveMetadata retrieves the metadata of the channel having the
+	// provided identifier.
+	RetrieveMetadata(context.Context, string) (Metadata, error)
+
+	// RetrieveByAlias retrieves the identifier of the channel having the
+	// provided alias.
+	RetrieveByAlias(context.Context, string) (string, error)
+
+	// RetrieveDirect retrieves the direct channel, owned by the specified
+	// user, whose peers are the provided things in ascending order.
+	RetrieveDirect(context.Context, string, []string) (Channel, error)
+
+	// RetrieveAll retrieves the subset of channels owned by the specified
+	// user, as specified by the page query. If cursor is provided, only
+	// channels following the cursor ID in the given direction are retrieved.
+	// If shared channels are requested, channels shared with the user or with
+	// any of the provided groups are retrieved as well.
+	RetrieveAll(context.Context, string, PageQuery, []string) (ChannelsPage, error)
+
+	// Search retrieves the subset of channels owned by the specified user
+	// whose name or metadata match the provided full-text query.
+	Search(context.Context, string, string, uint64, uint64) (ChannelsPage, error)
 
 	// RetrieveByThing retrieves the subset of channels owned by the specified
 	// user and have specified thing connected to them.
 	RetrieveByThing(context.Context, string, string, uint64, uint64) (ChannelsPage, error)
 
-	// Remove removes the channel having the provided identifier, that is owned
-	// by the specified user.
+	// Export invokes the callback for each of the channels owned by the
+	// specified user that are not removed. Channels are not loaded into
+	// memory at once, and iteration stops at the first error returned by the
+	// callback.
+	Export(context.Context, string, func(Channel) error) error
+
+	// ExportConnections invokes the callback for each of the connections
+	// between the channels and the things owned by the specified user, where
+	// neither of them is removed. Iteration stops at the first error returned
+	// by the callback.
+	ExportConnections(context.Context, string, func(Connection) error) error
+
+	// Remove marks the channel having the provided identifier, that is owned
+	// by the specified user, as removed. Removed channel can be restored.
 	Remove(context.Context, string, string) error
 
-	// Connect adds thing to the channel's list of connected things.
-	Connect(context.Context, string, string, string) error
+	// Restore restores the removed channel having the provided identifier,
+	// that is owned by the specified user.
+	Restore(context.Context, string, string) error
+
+	// Purge permanently removes the channel having the provided identifier,
+	// that is owned by the specified user.
+	Purge(context.Context, string, string) error
+
+	// Share grants the permission on the channel having the provided
+	// identifier, that is owned by the specified user, to the group.
+	// Sharing already shared channel replaces the granted permission.
+	Share(context.Context, string, string, string, string) error
+
+	// Unshare revokes the access to the channel having the provided
+	// identifier, that is owned by the specified user, from the group.
+	Unshare(context.Context, string, string, string) error
+
+	// ShareWithUser grants the permission on the channel having the provided
+	// identifier, that is owned by the specified user, to the other user.
+	// Sharing already shared channel replaces the granted permission.
+	ShareWithUser(context.Context, string, string, string, string) error
+
+	// UnshareWithUser revokes the access to the channel having the provided
+	// identifier, that is owned by the specified user, from the other user.
+	UnshareWithUser(context.Context, string, string, string) error
+
+	// Transfer transfers all the things and channels owned by the first
+	// specified user, including the removed ones, along with their
+	// connections and shares, to the second one. Either all the entities
+	// are transferred or none.
+	Transfer(context.Context, string, string) error
+
+	// Connect adds thing to the channel's list of connected things, allowing
+	// it to perform the provided actions. Connecting already connected thing
+	// replaces its allowed actions.
+	Connect(context.Context, string, string, string, []string) error
 
 	// Disconnect removes thing from the channel's list of connected
 	// things.
 	Disconnect(context.Context, string, string, string) error
 
 	// HasThing determines whether the thing with the provided access key, is
-	// "connected" to the specified channel. If that's the case, it returns
-	// thing's ID.
-	HasThing(context.Context, string, string) (string, error)
+	// "connected" to the specified channel and allowed to perform the
+	// provided action. If that's the case, it returns thing's ID.
+	HasThing(context.Context, string, string, string) (string, error)
 
 	// HasThingByID determines whether the thing with the provided ID, is
-	// "connected" to the specified channel. If that's the case, then
-	// returned error will be nil.
-	HasThingByID(context.Context, string, string) error
+	// "connected" to the specified channel and allowed to perform the
+	// provided action. If that's the case, then returned error will be nil.
+	HasThingByID(context.Context, string, string, string) error
 }
 
 // ChannelCache contains channel-thing connection caching interface.
 type ChannelCache interface {
-	// Connect channel thing connection.
-	Connect(context.Context, string, string) error
+	// Connect caches the action allowed to the thing connected to channel.
+	Connect(context.Context, string, string, string) error
 
-	// HasThing checks if thing is connected to channel.
-	HasThing(context.Context, string, string) bool
+	// HasThing checks if thing is connected to channel and allowed to
+	// perform the action.
+	HasThing(context.Context, string, string, string) bool
 
-	// Disconnects thing from channel.
+	// Disconnects thing from channel, removing all of its allowed actions.
 	Disconnect(context.Context, string, string) error
 
 	// Removes channel from cache.
@@ -0,0 +1,22 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package things
+
+import "context"
+
+type replicaReadsKey struct{}
+
+// WithReplicaReads returns the context permitting the repositories to read
+// from the read replicas. Since replicas may lag behind the primary database,
+// reads shouldn't be permitted when the caller expects to see its own writes.
+func WithReplicaReads(ctx context.Context) context.Context {
+	return context.WithValue(ctx, replicaReadsKey{}, true)
+}
+
+// ReplicaReads checks whether the context permits reads from the read
+// replicas.
+func ReplicaReads(ctx context.Context) bool {
+	permitted, _ := ctx.Value(replicaReadsKey{}).(bool)
+	return permitted
+}
@@ -0,0 +1,16 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package things
+
+// KeyHasher specifies an API for hashing thing keys. Since things are
+// identified by their keys, hashing must be deterministic so that the hashed
+// keys can be looked up.
+type KeyHasher interface {
+	// Hash generates the hashed key from the plain-text one.
+	Hash(string) string
+
+	// Hashed checks whether the stored key is already hashed, which is used
+	// to migrate plain-text keys.
+	Hashed(string) bool
+}
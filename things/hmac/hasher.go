@@ -0,0 +1,40 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package hmac provides a thing key hasher implementation utilising
+// HMAC-SHA256.
+package hmac
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/mainflux/mainflux/things"
+)
+
+// prefix distinguishes hashed keys from the plain-text ones.
+const prefix = "hmac-sha256:"
+
+var _ things.KeyHasher = (*hmacHasher)(nil)
+
+type hmacHasher struct {
+	secret []byte
+}
+
+// New instantiates a HMAC-SHA256 based key hasher implementation using the
+// provided secret.
+func New(secret string) things.KeyHasher {
+	return &hmacHasher{secret: []byte(secret)}
+}
+
+func (hh *hmacHasher) Hash(key string) string {
+	mac := hmac.New(sha256.New, hh.secret)
+	mac.Write([]byte(key))
+	return prefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+func (hh *hmacHasher) Hashed(key string) bool {
+	return strings.HasPrefix(key, prefix)
+}
@@ -0,0 +1,14 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package things
+
+// KeyProvider specifies an API for generating thing keys.
+type KeyProvider interface {
+	// Key generates the new thing key.
+	Key() (string, error)
+
+	// Malformed checks whether the key can't be the valid thing key, so
+	// that it can be rejected without looking it up.
+	Malformed(string) bool
+}
@@ -0,0 +1,130 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package keys provides a thing key provider generating high-entropy keys
+// consisting of the prefix, the random part and its checksum. The checksum
+// allows the rejection of mistyped and guessed keys without looking them up.
+package keys
+
+import (
+	"crypto/rand"
+	"hash/crc32"
+	"strings"
+
+	"github.com/mainflux/mainflux/things"
+)
+
+const (
+	// Prefix identifies the keys generated by the provider.
+	Prefix = "mfx_k_"
+
+	// MinLength is the minimal length of the random part of the key, which
+	// corresponds to the entropy of 130 bits.
+	MinLength = 22
+
+	// maxLegacyLength is the maximal length of keys not generated by the
+	// provider, such as the UUID keys and the keys set by the users.
+	maxLegacyLength = 4096
+
+	alphabet    = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	checksumLen = 6
+)
+
+var _ things.KeyProvider = (*keyProvider)(nil)
+
+type keyProvider struct {
+	length int
+}
+
+// New instantiates a key provider generating keys whose random part has the
+// given length. Lengths lesser than MinLength are replaced by it.
+func New(length int) things.KeyProvider {
+	if length < MinLength {
+		length = MinLength
+	}
+
+	return &keyProvider{length: length}
+}
+
+func (kp *keyProvider) Key() (string, error) {
+	random, err := randomString(kp.length)
+	if err != nil {
+		return "", err
+	}
+
+	return Prefix + random + checksum(random), nil
+}
+
+func (kp *keyProvider) Malformed(key string) bool {
+	return Malformed(key)
+}
+
+// Malformed checks whether the key can't be the valid thing key. Keys
+// carrying the prefix must have the valid checksum, while the others are
+// accepted for compatibility as long as they fit the key storage. Since
+// the check doesn't depend on the provider configuration, it's used by the
+// protocol adapters to reject the keys before authorizing them.
+func Malformed(key string) bool {
+	if key == "" || len(key) > maxLegacyLength {
+		return true
+	}
+
+	if !strings.HasPrefix(key, Prefix) {
+		return false
+	}
+
+	body := strings.TrimPrefix(key, Prefix)
+	if len(body) < MinLength+checksumLen {
+		return true
+	}
+
+	for i := 0; i < len(body); i++ {
+		if strings.IndexByte(alphabet, body[i]) < 0 {
+			return true
+		}
+	}
+
+	random := body[:len(body)-checksumLen]
+	return body[len(random):] != checksum(random)
+}
+
+// randomString generates the string of the given length consisting of the
+// alphabet characters. Bytes outside of the largest multiple of the alphabet
+// size are discarded, so that the characters are uniformly distributed.
+func randomString(length int) (string, error) {
+	limit := byte(256 - 256%len(alphabet))
+
+	res := make([]byte, 0, length)
+	buf := make([]byte, length)
+	for len(res) < length {
+		if _, err := rand.Read(buf); err != nil {
+			return "", err
+		}
+
+		for _, b := range buf {
+			if b >= limit {
+				continue
+			}
+			res = append(res, alphabet[int(b)%len(alphabet)])
+			if len(res) == length {
+				break
+			}
+		}
+	}
+
+	return string(res), nil
+}
+
+// checksum encodes the CRC32 checksum of the random part using fixed number
+// of alphabet characters.
+func checksum(random string) string {
+	sum := crc32.ChecksumIEEE([]byte(random))
+
+	res := make([]byte, checksumLen)
+	for i := checksumLen - 1; i >= 0; i-- {
+		res[i] = alphabet[sum%uint32(len(alphabet))]
+		sum /= uint32(len(alphabet))
+	}
+
+	return string(res)
+}
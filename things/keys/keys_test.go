@@ -0,0 +1,73 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package keys_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mainflux/mainflux/things/keys"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKey(t *testing.T) {
+	cases := map[string]struct {
+		length int
+		size   int
+	}{
+		"generate key of configured length":  {40, len(keys.Prefix) + 40 + 6},
+		"generate key of too small length":   {8, len(keys.Prefix) + keys.MinLength + 6},
+		"generate key of the minimal length": {keys.MinLength, len(keys.Prefix) + keys.MinLength + 6},
+	}
+
+	for desc, tc := range cases {
+		kp := keys.New(tc.length)
+		key, err := kp.Key()
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", desc, err))
+		assert.True(t, strings.HasPrefix(key, keys.Prefix), fmt.Sprintf("%s: expected prefix %s in %s", desc, keys.Prefix, key))
+		assert.Len(t, key, tc.size, fmt.Sprintf("%s: expected key of length %d got %s", desc, tc.size, key))
+		assert.False(t, kp.Malformed(key), fmt.Sprintf("%s: expected generated key %s to be valid", desc, key))
+	}
+
+	kp := keys.New(keys.MinLength)
+	key1, _ := kp.Key()
+	key2, _ := kp.Key()
+	assert.NotEqual(t, key1, key2, "expected generated keys to differ")
+}
+
+func TestMalformed(t *testing.T) {
+	key, err := keys.New(keys.MinLength).Key()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	// Changing a single character of the random part invalidates the checksum.
+	random := []byte(key)
+	i := len(keys.Prefix)
+	if random[i] == 'a' {
+		random[i] = 'b'
+	} else {
+		random[i] = 'a'
+	}
+
+	cases := map[string]struct {
+		key       string
+		malformed bool
+	}{
+		"valid key":                         {key, false},
+		"legacy UUID key":                   {"3b8dc4d4-04d7-4a0d-8e1b-a6f5f6a3c5b8", false},
+		"custom key":                        {"my-custom-key", false},
+		"empty key":                         {"", true},
+		"too long legacy key":               {strings.Repeat("k", 4097), true},
+		"key with invalid checksum":         {string(random), true},
+		"truncated key":                     {key[:len(key)-1], true},
+		"key with invalid characters":       {keys.Prefix + strings.Repeat("-", keys.MinLength+6), true},
+		"key consisting of the prefix only": {keys.Prefix, true},
+	}
+
+	for desc, tc := range cases {
+		malformed := keys.Malformed(tc.key)
+		assert.Equal(t, tc.malformed, malformed, fmt.Sprintf("%s: expected %t got %t", desc, tc.malformed, malformed))
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mainflux/mainflux/things"
+)
+
+var _ things.APIUsageRepository = (*apiUsageRepositoryMock)(nil)
+
+type apiUsageRepositoryMock struct {
+	mu    sync.Mutex
+	usage []things.APIUsage
+}
+
+// NewAPIUsageRepository creates in-memory API usage repository.
+func NewAPIUsageRepository() things.APIUsageRepository {
+	return &apiUsageRepositoryMock{}
+}
+
+func (urm *apiUsageRepositoryMock) Save(_ context.Context, usage ...things.APIUsage) error {
+	urm.mu.Lock()
+	defer urm.mu.Unlock()
+
+	for _, u := range usage {
+		saved := false
+		for i, cur := range urm.usage {
+			if cur.Owner == u.Owner && cur.Method == u.Method && cur.Hour.Equal(u.Hour) {
+				urm.usage[i].Calls += u.Calls
+				urm.usage[i].Errors += u.Errors
+				saved = true
+				break
+			}
+		}
+		if !saved {
+			urm.usage = append(urm.usage, u)
+		}
+	}
+
+	return nil
+}
+
+func (urm *apiUsageRepositoryMock) RetrieveByOwner(_ context.Context, owner string, from, to time.Time) ([]things.APIUsage, error) {
+	urm.mu.Lock()
+	defer urm.mu.Unlock()
+
+	usage := []things.APIUsage{}
+	for _, u := range urm.usage {
+		if u.Owner == owner && !u.Hour.Before(from) && u.Hour.Before(to) {
+			usage = append(usage, u)
+		}
+	}
+
+	sort.Slice(usage, func(i, j int) bool {
+		if !usage[i].Hour.Equal(usage[j].Hour) {
+			return usage[i].Hour.Before(usage[j].Hour)
+		}
+		return usage[i].Method < usage[j].Method
+	})
+
+	return usage, nil
+}
@@ -0,0 +1,56 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+
+	"github.com/mainflux/mainflux/things"
+)
+
+var _ things.ChangeLog = (*changeLogMock)(nil)
+
+type changeLogMock struct {
+	owners  []string
+	changes []things.Change
+}
+
+// NewChangeLog creates in-memory change log containing the provided changes,
+// which are attributed to the owners at the same indexes.
+func NewChangeLog(owners []string, changes []things.Change) things.ChangeLog {
+	return changeLogMock{
+		owners:  owners,
+		changes: changes,
+	}
+}
+
+func (clm changeLogMock) RetrieveAll(_ context.Context, owner, since string, limit uint64) (things.ChangesPage, error) {
+	page := things.ChangesPage{
+		Changes: []things.Change{},
+		Next:    since,
+	}
+
+	start := 0
+	if since != "" {
+		start = -1
+		for i, change := range clm.changes {
+			if change.ID == since {
+				start = i + 1
+				break
+			}
+		}
+		if start < 0 {
+			return things.ChangesPage{}, things.ErrChangesExpired
+		}
+	}
+
+	for i := start; i < len(clm.changes) && uint64(len(page.Changes)) < limit; i++ {
+		page.Next = clm.changes[i].ID
+		if clm.owners[i] == owner {
+			page.Changes = append(page.Changes, clm.changes[i])
+		}
+	}
+
+	return page, nil
+}
@@ -10,6 +10,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/mainflux/mainflux/things"
 )
@@ -28,18 +29,24 @@ type channelRepositoryMock struct {
 	mu       sync.Mutex
 	counter  uint64
 	channels map[string]things.Channel
+	removed  map[string]things.Channel
 	tconns   chan Connection                      // used for syncronization with thing repo
 	cconns   map[string]map[string]things.Channel // used to track connections
+	actions  map[string][]string                  // used to track allowed actions
 	things   things.ThingRepository
+	shares   shares
 }
 
 // NewChannelRepository creates in-memory channel repository.
 func NewChannelRepository(repo things.ThingRepository, tconns chan Connection) things.ChannelRepository {
 	return &channelRepositoryMock{
 		channels: make(map[string]things.Channel),
+		removed:  make(map[string]things.Channel),
 		tconns:   tconns,
 		cconns:   make(map[string]map[string]things.Channel),
+		actions:  make(map[string][]string),
 		things:   repo,
+		shares:   make(shares),
 	}
 }
 
@@ -47,6 +54,10 @@ func (crm *channelRepositoryMock) Save(_ context.Context, channel things.Channel
 	crm.mu.Lock()
 	defer crm.mu.Unlock()
 
+	if crm.aliasTaken(channel.ID, channel.Alias) || crm.paired(channel) {
+		return "", things.ErrConflict
+	}
+
 	crm.counter++
 	channel.ID = strconv.FormatUint(crm.counter, 10)
 	crm.channels[key(channel.Owner, channel.ID)] = channel
@@ -64,10 +75,46 @@ func (crm *channelRepositoryMock) Update(_ context.Context, channel things.Chann
 		return things.ErrNotFound
 	}
 
+	if crm.aliasTaken(channel.ID, channel.Alias) {
+		return things.ErrConflict
+	}
+
 	crm.channels[dbKey] = channel
 	return nil
 }
 
+func (crm *channelRepositoryMock) aliasTaken(id, alias string) bool {
+	if alias == "" {
+		return false
+	}
+
+	for _, ch := range crm.channels {
+		if ch.Alias == alias && ch.ID != id {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (crm *channelRepositoryMock) paired(channel things.Channel) bool {
+	if channel.Type != things.DirectChannel {
+		return false
+	}
+
+	for _, ch := range crm.channels {
+		if ch.Owner == channel.Owner && ch.Type == things.DirectChannel && samePeers(ch.Peers, channel.Peers) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func samePeers(a, b []string) bool {
+	return strings.Join(a, ",") == strings.Join(b, ",")
+}
+
 func (crm *channelRepositoryMock) RetrieveByID(_ context.Context, owner, id string) (things.Channel, error) {
 	if c, ok := crm.channels[key(owner, id)]; ok {
 		return c, nil
@@ -76,34 +123,198 @@ func (crm *channelRepositoryMock) RetrieveByID(_ context.Context, owner, id stri
 	return things.Channel{}, things.ErrNotFound
 }
 
-func (crm *channelRepositoryMock) RetrieveAll(_ context.Context, owner string, offset, limit uint64, name string, metadata things.Metadata) (things.ChannelsPage, error) {
+func (crm *channelRepositoryMock) RetrieveShared(_ context.Context, id, email string, groups []string) (things.Channel, string, error) {
+	permission, ok := crm.shares.permission(id, append(groups, email))
+	if !ok {
+		return things.Channel{}, "", things.ErrNotFound
+	}
+
+	for _, ch := range crm.channels {
+		if ch.ID == id {
+			return ch, permission, nil
+		}
+	}
+
+	return things.Channel{}, "", things.ErrNotFound
+}
+
+func (crm *channelRepositoryMock) RetrieveRetention(_ context.Context, id string) (things.Retention, error) {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	for _, ch := range crm.channels {
+		if ch.ID == id {
+			return ch.Retention, nil
+		}
+	}
+
+	return things.Retention{}, things.ErrNotFound
+}
+
+func (crm *channelRepositoryMock) RetrieveRegion(_ context.Context, id string) (string, error) {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	for _, ch := range crm.channels {
+		if ch.ID == id {
+			return ch.Region, nil
+		}
+	}
+
+	return "", things.ErrNotFound
+}
+
+func (crm *channelRepositoryMock) RetrieveAlias(_ context.Context, id string) (string, error) {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	for _, ch := range crm.channels {
+		if ch.ID == id {
+			return ch.Alias, nil
+		}
+	}
+
+	return "", things.ErrNotFound
+}
+
+func (crm *channelRepositoryMock) RetrieveOrdering(_ context.Context, id string) (bool, error) {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	for _, ch := range crm.channels {
+		if ch.ID == id {
+			return ch.Ordered, nil
+		}
+	}
+
+	return false, things.ErrNotFound
+}
+
+func (crm *channelRepositoryMock) RetrieveMetadata(_ context.Context, id string) (things.Metadata, error) {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	for _, ch := range crm.channels {
+		if ch.ID == id {
+			return ch.Metadata, nil
+		}
+	}
+
+	return nil, things.ErrNotFound
+}
+
+func (crm *channelRepositoryMock) RetrieveByAlias(_ context.Context, alias string) (string, error) {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	for _, ch := range crm.channels {
+		if alias != "" && ch.Alias == alias {
+			return ch.ID, nil
+		}
+	}
+
+	return "", things.ErrNotFound
+}
+
+func (crm *channelRepositoryMock) RetrieveDirect(_ context.Context, owner string, peers []string) (things.Channel, error) {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	for _, ch := range crm.channels {
+		if ch.Owner == owner && ch.Type == things.DirectChannel && samePeers(ch.Peers, peers) {
+			return ch, nil
+		}
+	}
+
+	return things.Channel{}, things.ErrNotFound
+}
+
+func (crm *channelRepositoryMock) RetrieveAll(_ context.Context, owner string, pq things.PageQuery, groups []string) (things.ChannelsPage, error) {
 	channels := make([]things.Channel, 0)
 
-	if offset < 0 || limit <= 0 {
+	if pq.Offset < 0 || pq.Limit <= 0 {
 		return things.ChannelsPage{}, nil
 	}
 
-	first := uint64(offset) + 1
-	last := first + uint64(limit)
+	first := uint64(pq.Offset) + 1
+	if pq.Cursor != "" {
+		id, err := strconv.ParseUint(pq.Cursor, 10, 64)
+		if err != nil {
+			return things.ChannelsPage{}, things.ErrMalformedEntity
+		}
+		first = id + 1
+	}
+	last := first + uint64(pq.Limit)
 
 	// This obscure way to examine map keys is enforced by the key structure
 	// itself (see mocks/commons.go).
 	prefix := fmt.Sprintf("%s-", owner)
+	var grantees []string
+	if pq.Shared {
+		grantees = append(groups, owner)
+	}
 	for k, v := range crm.channels {
 		id, _ := strconv.ParseUint(v.ID, 10, 64)
-		if strings.HasPrefix(k, prefix) && id >= first && id < last {
+		_, ok := crm.shares.permission(v.ID, grantees)
+		if (strings.HasPrefix(k, prefix) || ok) && id >= first && id < last {
 			channels = append(channels, v)
 		}
 	}
 
+	if pq.Deleted {
+		for k, v := range crm.removed {
+			id, _ := strconv.ParseUint(v.ID, 10, 64)
+			_, ok := crm.shares.permission(v.ID, grantees)
+			if (strings.HasPrefix(k, prefix) || ok) && id >= first && id < last {
+				channels = append(channels, v)
+			}
+		}
+	}
+
 	sort.SliceStable(channels, func(i, j int) bool {
-		return channels[i].ID < channels[j].ID
+		return less(pq.Order, pq.Dir, channels[i].ID, channels[j].ID, channels[i].Name, channels[j].Name)
 	})
 
 	page := things.ChannelsPage{
 		Channels: channels,
 		PageMetadata: things.PageMetadata{
 			Total:  crm.counter,
+			Offset: pq.Offset,
+			Limit:  pq.Limit,
+		},
+	}
+
+	return page, nil
+}
+
+func (crm *channelRepositoryMock) Search(_ context.Context, owner, query string, offset, limit uint64) (things.ChannelsPage, error) {
+	prefix := fmt.Sprintf("%s-", owner)
+	channels := make([]things.Channel, 0)
+	for k, v := range crm.channels {
+		if strings.HasPrefix(k, prefix) && matches(query, v.Name, v.Metadata) {
+			channels = append(channels, v)
+		}
+	}
+
+	sort.SliceStable(channels, func(i, j int) bool {
+		ii, _ := strconv.ParseUint(channels[i].ID, 10, 64)
+		ij, _ := strconv.ParseUint(channels[j].ID, 10, 64)
+		return ii < ij
+	})
+
+	total := uint64(len(channels))
+	if offset > total {
+		offset = total
+	}
+	end := offset + limit
+	if end > total {
+		end = total
+	}
+
+	page := things.ChannelsPage{
+		Channels: channels[offset:end],
+		PageMetadata: things.PageMetadata{
+			Total:  total,
 			Offset: offset,
 			Limit:  limit,
 		},
@@ -145,8 +356,72 @@ func (crm *channelRepositoryMock) RetrieveByThing(_ context.Context, owner, thin
 	return page, nil
 }
 
+func (crm *channelRepositoryMock) Export(_ context.Context, owner string, fn func(things.Channel) error) error {
+	crm.mu.Lock()
+	items := make([]things.Channel, 0)
+	for k, v := range crm.channels {
+		if strings.HasPrefix(k, key(owner, "")) {
+			items = append(items, v)
+		}
+	}
+	crm.mu.Unlock()
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].ID < items[j].ID
+	})
+
+	for _, ch := range items {
+		if err := fn(ch); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (crm *channelRepositoryMock) ExportConnections(ctx context.Context, owner string, fn func(things.Connection) error) error {
+	conns := make([]things.Connection, 0)
+	for thingID, chans := range crm.cconns {
+		if _, err := crm.things.RetrieveByID(ctx, owner, thingID); err != nil {
+			continue
+		}
+
+		for chanID := range chans {
+			if _, ok := crm.channels[key(owner, chanID)]; !ok {
+				continue
+			}
+
+			conns = append(conns, things.Connection{
+				ChannelID: chanID,
+				ThingID:   thingID,
+				Actions:   crm.actions[key(chanID, thingID)],
+			})
+		}
+	}
+
+	sort.SliceStable(conns, func(i, j int) bool {
+		if conns[i].ChannelID == conns[j].ChannelID {
+			return conns[i].ThingID < conns[j].ThingID
+		}
+		return conns[i].ChannelID < conns[j].ChannelID
+	})
+
+	for _, conn := range conns {
+		if err := fn(conn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (crm *channelRepositoryMock) Remove(_ context.Context, owner, id string) error {
-	delete(crm.channels, key(owner, id))
+	dbKey := key(owner, id)
+	if ch, ok := crm.channels[dbKey]; ok {
+		ch.DeletedAt = time.Now()
+		crm.removed[dbKey] = ch
+		delete(crm.channels, dbKey)
+	}
 	// delete channel from any thing list
 	for thk := range crm.cconns {
 		delete(crm.cconns[thk], key(owner, id))
@@ -158,7 +433,91 @@ func (crm *channelRepositoryMock) Remove(_ context.Context, owner, id string) er
 	return nil
 }
 
-func (crm *channelRepositoryMock) Connect(_ context.Context, owner, chanID, thingID string) error {
+func (crm *channelRepositoryMock) Restore(_ context.Context, owner, id string) error {
+	dbKey := key(owner, id)
+	ch, ok := crm.removed[dbKey]
+	if !ok {
+		return things.ErrNotFound
+	}
+
+	ch.DeletedAt = time.Time{}
+	crm.channels[dbKey] = ch
+	delete(crm.removed, dbKey)
+
+	return nil
+}
+
+func (crm *channelRepositoryMock) Purge(_ context.Context, owner, id string) error {
+	delete(crm.channels, key(owner, id))
+	delete(crm.removed, key(owner, id))
+	delete(crm.shares, id)
+	return nil
+}
+
+func (crm *channelRepositoryMock) Share(_ context.Context, owner, id, group, permission string) error {
+	if _, ok := crm.channels[key(owner, id)]; !ok {
+		return things.ErrNotFound
+	}
+
+	crm.shares.share(id, group, permission)
+	return nil
+}
+
+func (crm *channelRepositoryMock) Unshare(_ context.Context, owner, id, group string) error {
+	if _, ok := crm.channels[key(owner, id)]; ok {
+		crm.shares.unshare(id, group)
+	}
+	return nil
+}
+
+func (crm *channelRepositoryMock) ShareWithUser(_ context.Context, owner, id, email, permission string) error {
+	if _, ok := crm.channels[key(owner, id)]; !ok {
+		return things.ErrNotFound
+	}
+
+	crm.shares.share(id, email, permission)
+	return nil
+}
+
+func (crm *channelRepositoryMock) UnshareWithUser(_ context.Context, owner, id, email string) error {
+	if _, ok := crm.channels[key(owner, id)]; ok {
+		crm.shares.unshare(id, email)
+	}
+	return nil
+}
+
+func (crm *channelRepositoryMock) Transfer(_ context.Context, from, to string) error {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	if trm, ok := crm.things.(*thingRepositoryMock); ok {
+		trm.transfer(from, to)
+	}
+
+	for _, chs := range []map[string]things.Channel{crm.channels, crm.removed} {
+		for dbKey, ch := range chs {
+			if ch.Owner != from {
+				continue
+			}
+			delete(chs, dbKey)
+			ch.Owner = to
+			chs[key(to, ch.ID)] = ch
+		}
+	}
+
+	for _, chs := range crm.cconns {
+		for id, ch := range chs {
+			if ch.Owner == from {
+				ch.Owner = to
+				chs[id] = ch
+			}
+		}
+	}
+
+	return nil
+}
+
+func (crm *channelRepositoryMock) Connect(_ context.Context, owner, chanID, thingID string, actions []string) error {
 	channel, err := crm.RetrieveByID(context.Background(), owner, chanID)
 	if err != nil {
 		return err
@@ -178,6 +537,7 @@ func (crm *channelRepositoryMock) Connect(_ context.Context, owner, chanID, thin
 		crm.cconns[thingID] = make(map[string]things.Channel)
 	}
 	crm.cconns[thingID][chanID] = channel
+	crm.actions[key(chanID, thingID)] = actions
 	return nil
 }
 
@@ -196,28 +556,24 @@ func (crm *channelRepositoryMock) Disconnect(_ context.Context, owner, chanID, t
 		connected: false,
 	}
 	delete(crm.cconns[thingID], chanID)
+	delete(crm.actions, key(chanID, thingID))
 	return nil
 }
 
-func (crm *channelRepositoryMock) HasThing(_ context.Context, chanID, token string) (string, error) {
+func (crm *channelRepositoryMock) HasThing(_ context.Context, chanID, token, action string) (string, error) {
 	tid, err := crm.things.RetrieveByKey(context.Background(), token)
 	if err != nil {
 		return "", things.ErrNotFound
 	}
 
-	chans, ok := crm.cconns[tid]
-	if !ok {
-		return "", things.ErrNotFound
-	}
-
-	if _, ok := chans[chanID]; !ok {
-		return "", things.ErrNotFound
+	if err := crm.HasThingByID(context.Background(), chanID, tid, action); err != nil {
+		return "", err
 	}
 
 	return tid, nil
 }
 
-func (crm *channelRepositoryMock) HasThingByID(_ context.Context, chanID, thingID string) error {
+func (crm *channelRepositoryMock) HasThingByID(_ context.Context, chanID, thingID, action string) error {
 	chans, ok := crm.cconns[thingID]
 	if !ok {
 		return things.ErrNotFound
@@ -227,34 +583,43 @@ func (crm *channelRepositoryMock) HasThingByID(_ context.Context, chanID, thingI
 		return things.ErrNotFound
 	}
 
-	return nil
+	for _, a := range crm.actions[key(chanID, thingID)] {
+		if a == action {
+			return nil
+		}
+	}
+
+	return things.ErrUnauthorizedAccess
 }
 
 type channelCacheMock struct {
 	mu       sync.Mutex
-	channels map[string]string
+	channels map[string]map[string]string
 }
 
 // NewChannelCache returns mock cache instance.
 func NewChannelCache() things.ChannelCache {
 	return &channelCacheMock{
-		channels: make(map[string]string),
+		channels: make(map[string]map[string]string),
 	}
 }
 
-func (ccm *channelCacheMock) Connect(_ context.Context, chanID, thingID string) error {
+func (ccm *channelCacheMock) Connect(_ context.Context, chanID, thingID, action string) error {
 	ccm.mu.Lock()
 	defer ccm.mu.Unlock()
 
-	ccm.channels[chanID] = thingID
+	if _, ok := ccm.channels[chanID]; !ok {
+		ccm.channels[chanID] = make(map[string]string)
+	}
+	ccm.channels[chanID][action] = thingID
 	return nil
 }
 
-func (ccm *channelCacheMock) HasThing(_ context.Context, chanID, thingID string) bool {
+func (ccm *channelCacheMock) HasThing(_ context.Context, chanID, thingID, action string) bool {
 	ccm.mu.Lock()
 	defer ccm.mu.Unlock()
 
-	return ccm.channels[chanID] == thingID
+	return ccm.channels[chanID][action] == thingID
 }
 
 func (ccm *channelCacheMock) Disconnect(_ context.Context, chanID, thingID string) error {
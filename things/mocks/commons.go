@@ -3,7 +3,13 @@
 
 package mocks
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mainflux/mainflux/things"
+)
 
 // Since mocks will store data in map, and they need to resemble the real
 // identifiers as much as possible, a key will be created as combination of
@@ -12,3 +18,72 @@ import "fmt"
 func key(owner string, id string) string {
 	return fmt.Sprintf("%s-%s", owner, id)
 }
+
+// less emulates ordering of the listed entities. Since mocks don't keep the
+// creation time and assign incremental IDs, ordering by creation time is the
+// same as ordering by ID.
+func less(order, dir, id1, id2, name1, name2 string) bool {
+	a, b := id1, id2
+	if order == things.OrderByName && name1 != name2 {
+		a, b = name1, name2
+	}
+
+	if dir == things.DescDir {
+		return a > b
+	}
+	return a < b
+}
+
+// matches emulates full-text search by checking if all the query terms are
+// contained in the name or the metadata.
+func matches(query, name string, metadata map[string]interface{}) bool {
+	doc := strings.ToLower(name)
+	if b, err := json.Marshal(metadata); err == nil {
+		doc = fmt.Sprintf("%s %s", doc, strings.ToLower(string(b)))
+	}
+
+	terms := strings.Fields(strings.ToLower(query))
+	if len(terms) == 0 {
+		return false
+	}
+
+	for _, t := range terms {
+		if !strings.Contains(doc, t) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// shares emulates granting access to the entities to groups and users by
+// mapping the entity IDs to the permissions granted to each of them. Group
+// IDs and user emails never clash, so they are kept in the same map.
+type shares map[string]map[string]string
+
+func (s shares) share(id, grantee, permission string) {
+	if _, ok := s[id]; !ok {
+		s[id] = make(map[string]string)
+	}
+	s[id][grantee] = permission
+}
+
+func (s shares) unshare(id, grantee string) {
+	delete(s[id], grantee)
+}
+
+// permission returns the widest permission on the entity granted to any of
+// the provided groups or users.
+func (s shares) permission(id string, grantees []string) (string, bool) {
+	permission := ""
+	for _, g := range grantees {
+		switch s[id][g] {
+		case things.EditPermission:
+			return things.EditPermission, true
+		case things.ViewPermission:
+			permission = things.ViewPermission
+		}
+	}
+
+	return permission, permission != ""
+}
@@ -0,0 +1,88 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mainflux/mainflux/things"
+)
+
+var _ things.QuotaRepository = (*quotaRepositoryMock)(nil)
+
+type quotaRepositoryMock struct {
+	mu       sync.Mutex
+	quotas   map[string]things.Quota
+	things   things.ThingRepository
+	channels things.ChannelRepository
+}
+
+// NewQuotaRepository creates in-memory quota repository. Usage is counted
+// by exporting the entities from the provided repositories.
+func NewQuotaRepository(thingsRepo things.ThingRepository, channelsRepo things.ChannelRepository) things.QuotaRepository {
+	return &quotaRepositoryMock{
+		quotas:   make(map[string]things.Quota),
+		things:   thingsRepo,
+		channels: channelsRepo,
+	}
+}
+
+func (qrm *quotaRepositoryMock) Save(_ context.Context, quota things.Quota) error {
+	qrm.mu.Lock()
+	defer qrm.mu.Unlock()
+
+	qrm.quotas[quota.Owner] = quota
+	return nil
+}
+
+func (qrm *quotaRepositoryMock) RetrieveByOwner(_ context.Context, owner string) (things.Quota, error) {
+	qrm.mu.Lock()
+	defer qrm.mu.Unlock()
+
+	quota, ok := qrm.quotas[owner]
+	if !ok {
+		return things.Quota{}, things.ErrNotFound
+	}
+
+	return quota, nil
+}
+
+func (qrm *quotaRepositoryMock) Remove(_ context.Context, owner string) error {
+	qrm.mu.Lock()
+	defer qrm.mu.Unlock()
+
+	delete(qrm.quotas, owner)
+	return nil
+}
+
+func (qrm *quotaRepositoryMock) RetrieveUsage(ctx context.Context, owner string) (things.Usage, error) {
+	usage := things.Usage{}
+
+	err := qrm.things.Export(ctx, owner, func(things.Thing) error {
+		usage.Things++
+		return nil
+	})
+	if err != nil {
+		return things.Usage{}, err
+	}
+
+	err = qrm.channels.Export(ctx, owner, func(things.Channel) error {
+		usage.Channels++
+		return nil
+	})
+	if err != nil {
+		return things.Usage{}, err
+	}
+
+	err = qrm.channels.ExportConnections(ctx, owner, func(things.Connection) error {
+		usage.Connections++
+		return nil
+	})
+	if err != nil {
+		return things.Usage{}, err
+	}
+
+	return usage, nil
+}
@@ -0,0 +1,103 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/mainflux/mainflux/things"
+)
+
+var _ things.SchemaRepository = (*schemaRepositoryMock)(nil)
+
+type schemaRepositoryMock struct {
+	mu       sync.Mutex
+	schemas  map[string]things.MetadataSchema
+	payloads map[string]map[string]things.PayloadSchema
+}
+
+// NewSchemaRepository creates in-memory metadata and payload schema
+// repository.
+func NewSchemaRepository() things.SchemaRepository {
+	return &schemaRepositoryMock{
+		schemas:  make(map[string]things.MetadataSchema),
+		payloads: make(map[string]map[string]things.PayloadSchema),
+	}
+}
+
+func (srm *schemaRepositoryMock) Save(_ context.Context, schema things.MetadataSchema) error {
+	srm.mu.Lock()
+	defer srm.mu.Unlock()
+
+	srm.schemas[key(schema.Owner, schema.Entity)] = schema
+	return nil
+}
+
+func (srm *schemaRepositoryMock) RetrieveByEntity(_ context.Context, owner, entity string) (things.MetadataSchema, error) {
+	srm.mu.Lock()
+	defer srm.mu.Unlock()
+
+	schema, ok := srm.schemas[key(owner, entity)]
+	if !ok {
+		return things.MetadataSchema{}, things.ErrNotFound
+	}
+
+	return schema, nil
+}
+
+func (srm *schemaRepositoryMock) Remove(_ context.Context, owner, entity string) error {
+	srm.mu.Lock()
+	defer srm.mu.Unlock()
+
+	delete(srm.schemas, key(owner, entity))
+	return nil
+}
+
+func (srm *schemaRepositoryMock) SavePayload(_ context.Context, schema things.PayloadSchema) error {
+	srm.mu.Lock()
+	defer srm.mu.Unlock()
+
+	if _, ok := srm.payloads[schema.Channel]; !ok {
+		srm.payloads[schema.Channel] = make(map[string]things.PayloadSchema)
+	}
+	srm.payloads[schema.Channel][schema.Subtopic] = schema
+	return nil
+}
+
+func (srm *schemaRepositoryMock) RetrievePayload(_ context.Context, chanID, subtopic string) (things.PayloadSchema, error) {
+	srm.mu.Lock()
+	defer srm.mu.Unlock()
+
+	schema, ok := srm.payloads[chanID][subtopic]
+	if !ok {
+		return things.PayloadSchema{}, things.ErrNotFound
+	}
+
+	return schema, nil
+}
+
+func (srm *schemaRepositoryMock) RetrievePayloads(_ context.Context, chanID string) ([]things.PayloadSchema, error) {
+	srm.mu.Lock()
+	defer srm.mu.Unlock()
+
+	schemas := []things.PayloadSchema{}
+	for _, schema := range srm.payloads[chanID] {
+		schemas = append(schemas, schema)
+	}
+	sort.Slice(schemas, func(i, j int) bool {
+		return schemas[i].Subtopic < schemas[j].Subtopic
+	})
+
+	return schemas, nil
+}
+
+func (srm *schemaRepositoryMock) RemovePayload(_ context.Context, chanID, subtopic string) error {
+	srm.mu.Lock()
+	defer srm.mu.Unlock()
+
+	delete(srm.payloads[chanID], subtopic)
+	return nil
+}
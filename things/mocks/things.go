@@ -10,6 +10,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/mainflux/mainflux/things"
 )
@@ -22,14 +23,27 @@ type thingRepositoryMock struct {
 	conns   chan Connection
 	tconns  map[string]map[string]things.Thing
 	things  map[string]things.Thing
+	removed map[string]things.Thing
+	shares  shares
+	keys    map[string]previousKey
+}
+
+// previousKey represents the previous key of the thing that is valid until
+// it expires.
+type previousKey struct {
+	dbKey     string
+	expiresAt time.Time
 }
 
 // NewThingRepository creates in-memory thing repository.
 func NewThingRepository(conns chan Connection) things.ThingRepository {
 	repo := &thingRepositoryMock{
-		conns:  conns,
-		things: make(map[string]things.Thing),
-		tconns: make(map[string]map[string]things.Thing),
+		conns:   conns,
+		things:  make(map[string]things.Thing),
+		removed: make(map[string]things.Thing),
+		shares:  make(shares),
+		tconns:  make(map[string]map[string]things.Thing),
+		keys:    make(map[string]previousKey),
 	}
 	go func(conns chan Connection, repo *thingRepositoryMock) {
 		for conn := range conns {
@@ -76,16 +90,10 @@ func (trm *thingRepositoryMock) Update(_ context.Context, thing things.Thing) er
 	return nil
 }
 
-func (trm *thingRepositoryMock) UpdateKey(_ context.Context, owner, id, val string) error {
+func (trm *thingRepositoryMock) UpdateKey(_ context.Context, owner, id, val string, overlap time.Duration) error {
 	trm.mu.Lock()
 	defer trm.mu.Unlock()
 
-	for _, th := range trm.things {
-		if th.Key == val {
-			return things.ErrConflict
-		}
-	}
-
 	dbKey := key(owner, id)
 
 	th, ok := trm.things[dbKey]
@@ -93,12 +101,44 @@ func (trm *thingRepositoryMock) UpdateKey(_ context.Context, owner, id, val stri
 		return things.ErrNotFound
 	}
 
+	if th.Key == val {
+		return nil
+	}
+
+	for _, th := range trm.things {
+		if th.Key == val {
+			return things.ErrConflict
+		}
+	}
+
+	trm.keys[th.Key] = previousKey{dbKey: dbKey, expiresAt: time.Now().Add(overlap)}
 	th.Key = val
 	trm.things[dbKey] = th
 
 	return nil
 }
 
+func (trm *thingRepositoryMock) HashKeys(_ context.Context, hasher things.KeyHasher) error {
+	trm.mu.Lock()
+	defer trm.mu.Unlock()
+
+	for k, th := range trm.things {
+		if !hasher.Hashed(th.Key) {
+			th.Key = hasher.Hash(th.Key)
+			trm.things[k] = th
+		}
+	}
+
+	for val, rk := range trm.keys {
+		if !hasher.Hashed(val) {
+			delete(trm.keys, val)
+			trm.keys[hasher.Hash(val)] = rk
+		}
+	}
+
+	return nil
+}
+
 func (trm *thingRepositoryMock) RetrieveByID(_ context.Context, owner, id string) (things.Thing, error) {
 	trm.mu.Lock()
 	defer trm.mu.Unlock()
@@ -110,37 +150,116 @@ func (trm *thingRepositoryMock) RetrieveByID(_ context.Context, owner, id string
 	return things.Thing{}, things.ErrNotFound
 }
 
-func (trm *thingRepositoryMock) RetrieveAll(_ context.Context, owner string, offset, limit uint64, name string, metadata things.Metadata) (things.ThingsPage, error) {
+func (trm *thingRepositoryMock) RetrieveShared(_ context.Context, id, email string, groups []string) (things.Thing, string, error) {
+	trm.mu.Lock()
+	defer trm.mu.Unlock()
+
+	permission, ok := trm.shares.permission(id, append(groups, email))
+	if !ok {
+		return things.Thing{}, "", things.ErrNotFound
+	}
+
+	for _, th := range trm.things {
+		if th.ID == id {
+			return th, permission, nil
+		}
+	}
+
+	return things.Thing{}, "", things.ErrNotFound
+}
+
+func (trm *thingRepositoryMock) RetrieveAll(_ context.Context, owner string, pq things.PageQuery, groups []string) (things.ThingsPage, error) {
 	trm.mu.Lock()
 	defer trm.mu.Unlock()
 
 	items := make([]things.Thing, 0)
 
-	if offset < 0 || limit <= 0 {
+	if pq.Offset < 0 || pq.Limit <= 0 {
 		return things.ThingsPage{}, nil
 	}
 
-	first := uint64(offset) + 1
-	last := first + uint64(limit)
+	first := uint64(pq.Offset) + 1
+	if pq.Cursor != "" {
+		id, err := strconv.ParseUint(pq.Cursor, 10, 64)
+		if err != nil {
+			return things.ThingsPage{}, things.ErrMalformedEntity
+		}
+		first = id + 1
+	}
+	last := first + uint64(pq.Limit)
 
 	// This obscure way to examine map keys is enforced by the key structure
 	// itself (see mocks/commons.go).
 	prefix := fmt.Sprintf("%s-", owner)
+	var grantees []string
+	if pq.Shared {
+		grantees = append(groups, owner)
+	}
 	for k, v := range trm.things {
 		id, _ := strconv.ParseUint(v.ID, 10, 64)
-		if strings.HasPrefix(k, prefix) && id >= first && id < last {
+		_, ok := trm.shares.permission(v.ID, grantees)
+		if (strings.HasPrefix(k, prefix) || ok) && id >= first && id < last {
 			items = append(items, v)
 		}
 	}
 
+	if pq.Deleted {
+		for k, v := range trm.removed {
+			id, _ := strconv.ParseUint(v.ID, 10, 64)
+			_, ok := trm.shares.permission(v.ID, grantees)
+			if (strings.HasPrefix(k, prefix) || ok) && id >= first && id < last {
+				items = append(items, v)
+			}
+		}
+	}
+
 	sort.SliceStable(items, func(i, j int) bool {
-		return items[i].ID < items[j].ID
+		return less(pq.Order, pq.Dir, items[i].ID, items[j].ID, items[i].Name, items[j].Name)
 	})
 
 	page := things.ThingsPage{
 		Things: items,
 		PageMetadata: things.PageMetadata{
 			Total:  trm.counter,
+			Offset: pq.Offset,
+			Limit:  pq.Limit,
+		},
+	}
+
+	return page, nil
+}
+
+func (trm *thingRepositoryMock) Search(_ context.Context, owner, query string, offset, limit uint64) (things.ThingsPage, error) {
+	trm.mu.Lock()
+	defer trm.mu.Unlock()
+
+	prefix := fmt.Sprintf("%s-", owner)
+	items := make([]things.Thing, 0)
+	for k, v := range trm.things {
+		if strings.HasPrefix(k, prefix) && matches(query, v.Name, v.Metadata) {
+			items = append(items, v)
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		ii, _ := strconv.ParseUint(items[i].ID, 10, 64)
+		ij, _ := strconv.ParseUint(items[j].ID, 10, 64)
+		return ii < ij
+	})
+
+	total := uint64(len(items))
+	if offset > total {
+		offset = total
+	}
+	end := offset + limit
+	if end > total {
+		end = total
+	}
+
+	page := things.ThingsPage{
+		Things: items[offset:end],
+		PageMetadata: things.PageMetadata{
+			Total:  total,
 			Offset: offset,
 			Limit:  limit,
 		},
@@ -149,6 +268,29 @@ func (trm *thingRepositoryMock) RetrieveAll(_ context.Context, owner string, off
 	return page, nil
 }
 
+func (trm *thingRepositoryMock) Export(_ context.Context, owner string, fn func(things.Thing) error) error {
+	trm.mu.Lock()
+	items := make([]things.Thing, 0)
+	for k, v := range trm.things {
+		if strings.HasPrefix(k, key(owner, "")) {
+			items = append(items, v)
+		}
+	}
+	trm.mu.Unlock()
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].ID < items[j].ID
+	})
+
+	for _, th := range items {
+		if err := fn(th); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (trm *thingRepositoryMock) RetrieveByChannel(_ context.Context, owner, chanID string, offset, limit uint64) (things.ThingsPage, error) {
 	trm.mu.Lock()
 	defer trm.mu.Unlock()
@@ -193,10 +335,123 @@ func (trm *thingRepositoryMock) RetrieveByChannel(_ context.Context, owner, chan
 func (trm *thingRepositoryMock) Remove(_ context.Context, owner, id string) error {
 	trm.mu.Lock()
 	defer trm.mu.Unlock()
+
+	dbKey := key(owner, id)
+	if th, ok := trm.things[dbKey]; ok {
+		th.DeletedAt = time.Now()
+		trm.removed[dbKey] = th
+		delete(trm.things, dbKey)
+	}
+
+	return nil
+}
+
+func (trm *thingRepositoryMock) Restore(_ context.Context, owner, id string) error {
+	trm.mu.Lock()
+	defer trm.mu.Unlock()
+
+	dbKey := key(owner, id)
+	th, ok := trm.removed[dbKey]
+	if !ok {
+		return things.ErrNotFound
+	}
+
+	th.DeletedAt = time.Time{}
+	trm.things[dbKey] = th
+	delete(trm.removed, dbKey)
+
+	return nil
+}
+
+func (trm *thingRepositoryMock) Purge(_ context.Context, owner, id string) error {
+	trm.mu.Lock()
+	defer trm.mu.Unlock()
+
 	delete(trm.things, key(owner, id))
+	delete(trm.removed, key(owner, id))
+	delete(trm.shares, id)
+	return nil
+}
+
+func (trm *thingRepositoryMock) Share(_ context.Context, owner, id, group, permission string) error {
+	trm.mu.Lock()
+	defer trm.mu.Unlock()
+
+	if _, ok := trm.things[key(owner, id)]; !ok {
+		return things.ErrNotFound
+	}
+
+	trm.shares.share(id, group, permission)
+	return nil
+}
+
+func (trm *thingRepositoryMock) Unshare(_ context.Context, owner, id, group string) error {
+	trm.mu.Lock()
+	defer trm.mu.Unlock()
+
+	if _, ok := trm.things[key(owner, id)]; ok {
+		trm.shares.unshare(id, group)
+	}
+	return nil
+}
+
+func (trm *thingRepositoryMock) ShareWithUser(_ context.Context, owner, id, email, permission string) error {
+	trm.mu.Lock()
+	defer trm.mu.Unlock()
+
+	if _, ok := trm.things[key(owner, id)]; !ok {
+		return things.ErrNotFound
+	}
+
+	trm.shares.share(id, email, permission)
+	return nil
+}
+
+func (trm *thingRepositoryMock) UnshareWithUser(_ context.Context, owner, id, email string) error {
+	trm.mu.Lock()
+	defer trm.mu.Unlock()
+
+	if _, ok := trm.things[key(owner, id)]; ok {
+		trm.shares.unshare(id, email)
+	}
 	return nil
 }
 
+// transfer transfers the things as part of the channel repository transfer.
+func (trm *thingRepositoryMock) transfer(from, to string) {
+	trm.mu.Lock()
+	defer trm.mu.Unlock()
+
+	moved := make(map[string]string)
+	for _, ths := range []map[string]things.Thing{trm.things, trm.removed} {
+		for dbKey, th := range ths {
+			if th.Owner != from {
+				continue
+			}
+			delete(ths, dbKey)
+			th.Owner = to
+			ths[key(to, th.ID)] = th
+			moved[dbKey] = key(to, th.ID)
+		}
+	}
+
+	for k, rk := range trm.keys {
+		if dbKey, ok := moved[rk.dbKey]; ok {
+			rk.dbKey = dbKey
+			trm.keys[k] = rk
+		}
+	}
+
+	for _, ths := range trm.tconns {
+		for id, th := range ths {
+			if th.Owner == from {
+				th.Owner = to
+				ths[id] = th
+			}
+		}
+	}
+}
+
 func (trm *thingRepositoryMock) RetrieveByKey(_ context.Context, key string) (string, error) {
 	trm.mu.Lock()
 	defer trm.mu.Unlock()
@@ -207,6 +462,25 @@ func (trm *thingRepositoryMock) RetrieveByKey(_ context.Context, key string) (st
 		}
 	}
 
+	if rk, ok := trm.keys[key]; ok && time.Now().Before(rk.expiresAt) {
+		if thing, ok := trm.things[rk.dbKey]; ok {
+			return thing.ID, nil
+		}
+	}
+
+	return "", things.ErrNotFound
+}
+
+func (trm *thingRepositoryMock) RetrieveOwner(_ context.Context, id string) (string, error) {
+	trm.mu.Lock()
+	defer trm.mu.Unlock()
+
+	for _, thing := range trm.things {
+		if thing.ID == id && thing.DeletedAt.IsZero() {
+			return thing.Owner, nil
+		}
+	}
+
 	return "", things.ErrNotFound
 }
 
@@ -9,22 +9,71 @@ import (
 	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/users"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 var _ mainflux.UsersServiceClient = (*usersServiceMock)(nil)
 
 type usersServiceMock struct {
-	users map[string]string
+	users    map[string]string
+	groups   map[string][]string
+	roles    map[string]string
+	disabled map[string]bool
 }
 
 // NewUsersService creates mock of users service.
 func NewUsersService(users map[string]string) mainflux.UsersServiceClient {
-	return &usersServiceMock{users}
+	return &usersServiceMock{users: users}
+}
+
+// NewUsersServiceWithGroups creates mock of users service whose users are
+// members of the provided groups, which are mapped by the user ID.
+func NewUsersServiceWithGroups(users map[string]string, groups map[string][]string) mainflux.UsersServiceClient {
+	return &usersServiceMock{users: users, groups: groups}
+}
+
+// NewUsersServiceWithRoles creates mock of users service whose users are
+// assigned the provided roles, which are mapped by the user ID.
+func NewUsersServiceWithRoles(users map[string]string, roles map[string]string) mainflux.UsersServiceClient {
+	return &usersServiceMock{users: users, roles: roles}
+}
+
+// NewUsersServiceWithDisabled creates mock of users service whose provided
+// users are disabled. Tokens of the disabled users aren't rejected, so that
+// their entities can be created.
+func NewUsersServiceWithDisabled(users map[string]string, disabled []string) mainflux.UsersServiceClient {
+	svc := &usersServiceMock{users: users, disabled: make(map[string]bool)}
+	for _, id := range disabled {
+		svc.disabled[id] = true
+	}
+	return svc
 }
 
 func (svc usersServiceMock) Identify(ctx context.Context, in *mainflux.Token, opts ...grpc.CallOption) (*mainflux.UserID, error) {
 	if id, ok := svc.users[in.Value]; ok {
-		return &mainflux.UserID{Value: id}, nil
+		return &mainflux.UserID{Value: id, Role: svc.roles[id]}, nil
+	}
+	return nil, users.ErrUnauthorizedAccess
+}
+
+func (svc usersServiceMock) Groups(ctx context.Context, in *mainflux.Token, opts ...grpc.CallOption) (*mainflux.GroupIDs, error) {
+	if id, ok := svc.users[in.Value]; ok {
+		return &mainflux.GroupIDs{Value: svc.groups[id]}, nil
 	}
 	return nil, users.ErrUnauthorizedAccess
 }
+
+func (svc usersServiceMock) Disabled(ctx context.Context, in *mainflux.UserID, opts ...grpc.CallOption) (*mainflux.UserStatus, error) {
+	if svc.disabled[in.Value] {
+		return &mainflux.UserStatus{Disabled: true}, nil
+	}
+
+	for _, id := range svc.users {
+		if id == in.Value {
+			return &mainflux.UserStatus{}, nil
+		}
+	}
+
+	return nil, status.Error(codes.NotFound, "user not found")
+}
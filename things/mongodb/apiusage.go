@@ -0,0 +1,84 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/mainflux/mainflux/things"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var _ things.APIUsageRepository = (*apiUsageRepository)(nil)
+
+type apiUsageRepository struct {
+	db Database
+}
+
+// NewAPIUsageRepository instantiates a MongoDB implementation of API usage
+// repository.
+func NewAPIUsageRepository(db Database) things.APIUsageRepository {
+	return &apiUsageRepository{
+		db: db,
+	}
+}
+
+func (ur apiUsageRepository) Save(ctx context.Context, usage ...things.APIUsage) error {
+	if len(usage) == 0 {
+		return nil
+	}
+
+	models := make([]mongo.WriteModel, 0, len(usage))
+	for _, u := range usage {
+		filter := bson.M{"owner": u.Owner, "hour": u.Hour.UTC(), "method": u.Method}
+		update := bson.M{"$inc": bson.M{"calls": int64(u.Calls), "errors": int64(u.Errors)}}
+		models = append(models, mongo.NewUpdateOneModel().SetFilter(filter).SetUpdate(update).SetUpsert(true))
+	}
+
+	_, err := ur.db.Collection(apiUsageCollection).BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+func (ur apiUsageRepository) RetrieveByOwner(ctx context.Context, owner string, from, to time.Time) ([]things.APIUsage, error) {
+	filter := bson.M{
+		"owner": owner,
+		"hour":  bson.M{"$gte": from.UTC(), "$lt": to.UTC()},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "hour", Value: 1}, {Key: "method", Value: 1}})
+
+	cur, err := ur.db.Collection(apiUsageCollection).Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	usage := []things.APIUsage{}
+	for cur.Next(ctx) {
+		var dbu dbAPIUsage
+		if err := cur.Decode(&dbu); err != nil {
+			return nil, err
+		}
+
+		usage = append(usage, things.APIUsage{
+			Owner:  dbu.Owner,
+			Hour:   dbu.Hour.UTC(),
+			Method: dbu.Method,
+			Calls:  uint64(dbu.Calls),
+			Errors: uint64(dbu.Errors),
+		})
+	}
+
+	return usage, cur.Err()
+}
+
+type dbAPIUsage struct {
+	Owner  string    `bson:"owner"`
+	Hour   time.Time `bson:"hour"`
+	Method string    `bson:"method"`
+	Calls  int64     `bson:"calls"`
+	Errors int64     `bson:"errors"`
+}
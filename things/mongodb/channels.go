@@ -0,0 +1,924 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mongodb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/mainflux/mainflux/things"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	errDuplicate = 11000
+
+	// Entities are shared with the groups and the users the same way, the
+	// grantee is identified by the respective field of the share.
+	groupField = "group_id"
+	emailField = "email"
+)
+
+var _ things.ChannelRepository = (*channelRepository)(nil)
+
+type channelRepository struct {
+	db Database
+}
+
+// NewChannelRepository instantiates a MongoDB implementation of channel
+// repository.
+func NewChannelRepository(db Database) things.ChannelRepository {
+	return &channelRepository{
+		db: db,
+	}
+}
+
+func (cr channelRepository) Save(ctx context.Context, channel things.Channel) (string, error) {
+	dbch, err := toDBChannel(channel)
+	if err != nil {
+		return "", err
+	}
+	dbch.CreatedAt = time.Now().UTC()
+
+	if _, err := cr.db.Collection(channelsCollection).InsertOne(ctx, dbch); err != nil {
+		if isDuplicate(err) {
+			return "", things.ErrConflict
+		}
+		return "", err
+	}
+
+	return channel.ID, nil
+}
+
+func (cr channelRepository) Update(ctx context.Context, channel things.Channel) error {
+	dbch, err := toDBChannel(channel)
+	if err != nil {
+		return err
+	}
+
+	filter := bson.M{"_id": channel.ID, "owner": channel.Owner, "deleted_at": nil}
+	update := bson.M{"$set": bson.M{
+		"name":      dbch.Name,
+		"metadata":  dbch.Metadata,
+		"retention": dbch.Retention,
+		"region":    dbch.Region,
+		"alias":     dbch.Alias,
+		"ordered":   dbch.Ordered,
+		"search":    dbch.Search,
+	}}
+
+	res, err := cr.db.Collection(channelsCollection).UpdateOne(ctx, filter, update)
+	if err != nil {
+		if isDuplicate(err) {
+			return things.ErrConflict
+		}
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		return things.ErrNotFound
+	}
+
+	return nil
+}
+
+func (cr channelRepository) RetrieveByID(ctx context.Context, owner, id string) (things.Channel, error) {
+	filter := bson.M{"_id": id, "owner": owner, "deleted_at": nil}
+
+	var dbch dbChannel
+	if err := cr.db.Collection(channelsCollection).FindOne(ctx, filter).Decode(&dbch); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return things.Channel{}, things.ErrNotFound
+		}
+		return things.Channel{}, err
+	}
+
+	return toChannel(dbch), nil
+}
+
+func (cr channelRepository) RetrieveShared(ctx context.Context, id, email string, groups []string) (things.Channel, string, error) {
+	filter := bson.M{"_id": id, "deleted_at": nil, "$or": sharedFilter(email, groups)}
+
+	var dbch dbChannel
+	if err := cr.db.Collection(channelsCollection).FindOne(ctx, filter).Decode(&dbch); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return things.Channel{}, "", things.ErrNotFound
+		}
+		return things.Channel{}, "", err
+	}
+
+	return toChannel(dbch), sharedPermission(dbch.Shares, email, groups), nil
+}
+
+func (cr channelRepository) RetrieveRetention(ctx context.Context, id string) (things.Retention, error) {
+	filter := bson.M{"_id": id, "deleted_at": nil}
+
+	var dbch dbChannel
+	if err := cr.db.Collection(channelsCollection).FindOne(ctx, filter).Decode(&dbch); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return things.Retention{}, things.ErrNotFound
+		}
+		return things.Retention{}, err
+	}
+
+	return toChannel(dbch).Retention, nil
+}
+
+func (cr channelRepository) RetrieveRegion(ctx context.Context, id string) (string, error) {
+	filter := bson.M{"_id": id, "deleted_at": nil}
+
+	var dbch dbChannel
+	if err := cr.db.Collection(channelsCollection).FindOne(ctx, filter).Decode(&dbch); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return "", things.ErrNotFound
+		}
+		return "", err
+	}
+
+	return dbch.Region, nil
+}
+
+func (cr channelRepository) RetrieveAlias(ctx context.Context, id string) (string, error) {
+	filter := bson.M{"_id": id, "deleted_at": nil}
+
+	var dbch dbChannel
+	if err := cr.db.Collection(channelsCollection).FindOne(ctx, filter).Decode(&dbch); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return "", things.ErrNotFound
+		}
+		return "", err
+	}
+
+	return dbch.Alias, nil
+}
+
+func (cr channelRepository) RetrieveOrdering(ctx context.Context, id string) (bool, error) {
+	filter := bson.M{"_id": id, "deleted_at": nil}
+
+	var dbch dbChannel
+	if err := cr.db.Collection(channelsCollection).FindOne(ctx, filter).Decode(&dbch); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return false, things.ErrNotFound
+		}
+		return false, err
+	}
+
+	return dbch.Ordered, nil
+}
+
+func (cr channelRepository) RetrieveMetadata(ctx context.Context, id string) (things.Metadata, error) {
+	filter := bson.M{"_id": id, "deleted_at": nil}
+
+	var dbch dbChannel
+	if err := cr.db.Collection(channelsCollection).FindOne(ctx, filter).Decode(&dbch); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, things.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return toChannel(dbch).Metadata, nil
+}
+
+func (cr channelRepository) RetrieveByAlias(ctx context.Context, alias string) (string, error) {
+	if alias == "" {
+		return "", things.ErrNotFound
+	}
+
+	filter := bson.M{"alias": alias, "deleted_at": nil}
+
+	var dbch dbChannel
+	if err := cr.db.Collection(channelsCollection).FindOne(ctx, filter).Decode(&dbch); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return "", things.ErrNotFound
+		}
+		return "", err
+	}
+
+	return dbch.ID, nil
+}
+
+func (cr channelRepository) RetrieveDirect(ctx context.Context, owner string, peers []string) (things.Channel, error) {
+	filter := bson.M{"owner": owner, "type": things.DirectChannel, "peers": peers, "deleted_at": nil}
+
+	var dbch dbChannel
+	if err := cr.db.Collection(channelsCollection).FindOne(ctx, filter).Decode(&dbch); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return things.Channel{}, things.ErrNotFound
+		}
+		return things.Channel{}, err
+	}
+
+	return toChannel(dbch), nil
+}
+
+func (cr channelRepository) RetrieveAll(ctx context.Context, owner string, pq things.PageQuery, groups []string) (things.ChannelsPage, error) {
+	filter := listFilter(owner, pq, groups)
+	total, err := cr.db.Collection(channelsCollection).CountDocuments(ctx, filter)
+	if err != nil {
+		return things.ChannelsPage{}, err
+	}
+
+	offset := cursorFilter(filter, pq.Cursor, pq.Dir, pq.Offset)
+	opts := options.Find().
+		SetSort(sortOrder(pq.Order, pq.Dir)).
+		SetSkip(int64(offset)).
+		SetLimit(int64(pq.Limit))
+
+	items, err := cr.find(ctx, filter, opts)
+	if err != nil {
+		return things.ChannelsPage{}, err
+	}
+
+	return things.ChannelsPage{
+		Channels: items,
+		PageMetadata: things.PageMetadata{
+			Total:  uint64(total),
+			Offset: offset,
+			Limit:  pq.Limit,
+		},
+	}, nil
+}
+
+func (cr channelRepository) Search(ctx context.Context, owner, query string, offset, limit uint64) (things.ChannelsPage, error) {
+	filter := searchFilter(owner, query)
+	opts := options.Find().
+		SetProjection(bson.M{"score": bson.M{"$meta": "textScore"}}).
+		SetSort(bson.D{{Key: "score", Value: bson.M{"$meta": "textScore"}}, {Key: "_id", Value: 1}}).
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit))
+
+	items, err := cr.find(ctx, filter, opts)
+	if err != nil {
+		return things.ChannelsPage{}, err
+	}
+
+	total, err := cr.db.Collection(channelsCollection).CountDocuments(ctx, filter)
+	if err != nil {
+		return things.ChannelsPage{}, err
+	}
+
+	return things.ChannelsPage{
+		Channels: items,
+		PageMetadata: things.PageMetadata{
+			Total:  uint64(total),
+			Offset: offset,
+			Limit:  limit,
+		},
+	}, nil
+}
+
+func (cr channelRepository) RetrieveByThing(ctx context.Context, owner, thing string, offset, limit uint64) (things.ChannelsPage, error) {
+	ids, err := connected(ctx, cr.db, bson.M{"thing_id": thing, "owner": owner}, "channel_id")
+	if err != nil {
+		return things.ChannelsPage{}, err
+	}
+
+	filter := bson.M{"_id": bson.M{"$in": ids}, "owner": owner, "deleted_at": nil}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: 1}}).
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit))
+
+	items, err := cr.find(ctx, filter, opts)
+	if err != nil {
+		return things.ChannelsPage{}, err
+	}
+
+	total, err := cr.db.Collection(channelsCollection).CountDocuments(ctx, filter)
+	if err != nil {
+		return things.ChannelsPage{}, err
+	}
+
+	return things.ChannelsPage{
+		Channels: items,
+		PageMetadata: things.PageMetadata{
+			Total:  uint64(total),
+			Offset: offset,
+			Limit:  limit,
+		},
+	}, nil
+}
+
+func (cr channelRepository) Export(ctx context.Context, owner string, fn func(things.Channel) error) error {
+	filter := bson.M{"owner": owner, "deleted_at": nil}
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+
+	cur, err := cr.db.Collection(channelsCollection).Find(ctx, filter, opts)
+	if err != nil {
+		return err
+	}
+	defer cur.Close(ctx)
+
+	for cur.Next(ctx) {
+		var dbch dbChannel
+		if err := cur.Decode(&dbch); err != nil {
+			return err
+		}
+
+		if err := fn(toChannel(dbch)); err != nil {
+			return err
+		}
+	}
+
+	return cur.Err()
+}
+
+func (cr channelRepository) ExportConnections(ctx context.Context, owner string, fn func(things.Connection) error) error {
+	// Connections are kept when the channel or the thing is removed, so
+	// they're skipped here instead.
+	removed := map[string]bool{}
+	for _, coll := range []string{channelsCollection, thingsCollection} {
+		ids, err := cr.db.Collection(coll).Distinct(ctx, "_id", bson.M{"owner": owner, "deleted_at": bson.M{"$ne": nil}})
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			removed[fmt.Sprintf("%s:%v", coll, id)] = true
+		}
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "channel_id", Value: 1}, {Key: "thing_id", Value: 1}})
+	cur, err := cr.db.Collection(connectionsCollection).Find(ctx, bson.M{"owner": owner}, opts)
+	if err != nil {
+		return err
+	}
+	defer cur.Close(ctx)
+
+	for cur.Next(ctx) {
+		var dbconn dbConnection
+		if err := cur.Decode(&dbconn); err != nil {
+			return err
+		}
+
+		if removed[fmt.Sprintf("%s:%s", channelsCollection, dbconn.Channel)] ||
+			removed[fmt.Sprintf("%s:%s", thingsCollection, dbconn.Thing)] {
+			continue
+		}
+
+		conn := things.Connection{
+			ChannelID: dbconn.Channel,
+			ThingID:   dbconn.Thing,
+			Actions:   dbconn.Actions,
+		}
+		if err := fn(conn); err != nil {
+			return err
+		}
+	}
+
+	return cur.Err()
+}
+
+func (cr channelRepository) Remove(ctx context.Context, owner, id string) error {
+	filter := bson.M{"_id": id, "owner": owner, "deleted_at": nil}
+	update := bson.M{"$set": bson.M{"deleted_at": time.Now().UTC()}}
+	cr.db.Collection(channelsCollection).UpdateOne(ctx, filter, update)
+	return nil
+}
+
+func (cr channelRepository) Restore(ctx context.Context, owner, id string) error {
+	filter := bson.M{"_id": id, "owner": owner, "deleted_at": bson.M{"$ne": nil}}
+	update := bson.M{"$unset": bson.M{"deleted_at": ""}}
+
+	res, err := cr.db.Collection(channelsCollection).UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		return things.ErrNotFound
+	}
+
+	return nil
+}
+
+func (cr channelRepository) Purge(ctx context.Context, owner, id string) error {
+	return cr.db.Transaction(ctx, func(ctx context.Context) error {
+		res, err := cr.db.Collection(channelsCollection).DeleteOne(ctx, bson.M{"_id": id, "owner": owner})
+		if err != nil || res.DeletedCount == 0 {
+			return err
+		}
+
+		if _, err := cr.db.Collection(connectionsCollection).DeleteMany(ctx, bson.M{"channel_id": id, "owner": owner}); err != nil {
+			return err
+		}
+
+		_, err = cr.db.Collection(payloadsCollection).DeleteMany(ctx, bson.M{"channel_id": id})
+		return err
+	})
+}
+
+func (cr channelRepository) Share(ctx context.Context, owner, id, group, permission string) error {
+	return share(ctx, cr.db, channelsCollection, owner, id, groupField, group, permission)
+}
+
+func (cr channelRepository) Unshare(ctx context.Context, owner, id, group string) error {
+	return unshare(ctx, cr.db, channelsCollection, owner, id, groupField, group)
+}
+
+func (cr channelRepository) ShareWithUser(ctx context.Context, owner, id, email, permission string) error {
+	return share(ctx, cr.db, channelsCollection, owner, id, emailField, email, permission)
+}
+
+func (cr channelRepository) UnshareWithUser(ctx context.Context, owner, id, email string) error {
+	return unshare(ctx, cr.db, channelsCollection, owner, id, emailField, email)
+}
+
+func (cr channelRepository) Transfer(ctx context.Context, from, to string) error {
+	// Connections store the owner of the connected channel and thing, so
+	// they're transferred along with the things and channels.
+	return cr.db.Transaction(ctx, func(ctx context.Context) error {
+		update := bson.M{"$set": bson.M{"owner": to}}
+
+		for _, coll := range []string{thingsCollection, channelsCollection, connectionsCollection} {
+			if _, err := cr.db.Collection(coll).UpdateMany(ctx, bson.M{"owner": from}, update); err != nil {
+				if isDuplicate(err) {
+					return things.ErrConflict
+				}
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (cr channelRepository) Connect(ctx context.Context, owner, chanID, thingID string, actions []string) error {
+	// Since there are no foreign keys, both the channel and the thing are
+	// checked in the same transaction the connection is stored in.
+	return cr.db.Transaction(ctx, func(ctx context.Context) error {
+		for coll, id := range map[string]string{channelsCollection: chanID, thingsCollection: thingID} {
+			n, err := cr.db.Collection(coll).CountDocuments(ctx, bson.M{"_id": id, "owner": owner})
+			if err != nil {
+				return err
+			}
+			if n == 0 {
+				return things.ErrNotFound
+			}
+		}
+
+		// connect is idempotent, connecting again only replaces allowed actions
+		filter := bson.M{"channel_id": chanID, "thing_id": thingID}
+		update := bson.M{"$set": bson.M{"owner": owner, "actions": actions}}
+		opts := options.Update().SetUpsert(true)
+
+		_, err := cr.db.Collection(connectionsCollection).UpdateOne(ctx, filter, update, opts)
+		return err
+	})
+}
+
+func (cr channelRepository) Disconnect(ctx context.Context, owner, chanID, thingID string) error {
+	filter := bson.M{"channel_id": chanID, "thing_id": thingID, "owner": owner}
+
+	res, err := cr.db.Collection(connectionsCollection).DeleteOne(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	if res.DeletedCount == 0 {
+		return things.ErrNotFound
+	}
+
+	return nil
+}
+
+func (cr channelRepository) HasThing(ctx context.Context, chanID, key, action string) (string, error) {
+	thingID, err := retrieveIDByKey(ctx, cr.db, key)
+	if err != nil {
+		return "", err
+	}
+
+	if err := cr.hasThing(ctx, chanID, thingID, action); err != nil {
+		return "", err
+	}
+
+	return thingID, nil
+}
+
+func (cr channelRepository) HasThingByID(ctx context.Context, chanID, thingID, action string) error {
+	return cr.hasThing(ctx, chanID, thingID, action)
+}
+
+func (cr channelRepository) hasThing(ctx context.Context, chanID, thingID, action string) error {
+	var conn dbConnection
+	filter := bson.M{"channel_id": chanID, "thing_id": thingID, "actions": action}
+	if err := cr.db.Collection(connectionsCollection).FindOne(ctx, filter).Decode(&conn); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return things.ErrUnauthorizedAccess
+		}
+		return err
+	}
+
+	filter = bson.M{"_id": chanID, "owner": conn.Owner, "deleted_at": nil}
+	n, err := cr.db.Collection(channelsCollection).CountDocuments(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return things.ErrUnauthorizedAccess
+	}
+
+	return nil
+}
+
+func (cr channelRepository) find(ctx context.Context, filter bson.M, opts *options.FindOptions) ([]things.Channel, error) {
+	cur, err := cr.db.Collection(channelsCollection).Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	items := []things.Channel{}
+	for cur.Next(ctx) {
+		var dbch dbChannel
+		if err := cur.Decode(&dbch); err != nil {
+			return nil, err
+		}
+		items = append(items, toChannel(dbch))
+	}
+
+	return items, cur.Err()
+}
+
+// connected returns values of the given field of all the connections
+// matching the filter.
+func connected(ctx context.Context, db Database, filter bson.M, field string) ([]string, error) {
+	opts := options.Find().SetProjection(bson.M{field: 1})
+	cur, err := db.Collection(connectionsCollection).Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	ids := []string{}
+	for cur.Next(ctx) {
+		var conn dbConnection
+		if err := cur.Decode(&conn); err != nil {
+			return nil, err
+		}
+
+		switch field {
+		case "thing_id":
+			ids = append(ids, conn.Thing)
+		default:
+			ids = append(ids, conn.Channel)
+		}
+	}
+
+	return ids, cur.Err()
+}
+
+func listFilter(owner string, pq things.PageQuery, groups []string) bson.M {
+	filter := bson.M{"owner": owner}
+	if pq.Shared {
+		delete(filter, "owner")
+		filter["$or"] = append(bson.A{bson.M{"owner": owner}}, sharedFilter(owner, groups)...)
+	}
+	if pq.Name != "" {
+		filter["name"] = primitive.Regex{Pattern: regexp.QuoteMeta(pq.Name), Options: "i"}
+	}
+	if !pq.Deleted {
+		filter["deleted_at"] = nil
+	}
+
+	// Metadata is matched by containment, the same way as JSONB @> operator
+	// does, so every nested field is compared separately.
+	flatten("metadata", pq.Metadata, filter)
+
+	if len(pq.Query) > 0 {
+		conds := bson.A{}
+		for _, q := range pq.Query {
+			conds = append(conds, queryFilter(q))
+		}
+		filter["$and"] = conds
+	}
+
+	return filter
+}
+
+// share grants the permission on the entity stored in the collection to the
+// group. Permission of the group the entity is already shared with is
+// replaced.
+// share grants the permission to the grantee, i.e. the group or the user,
+// identified by the value of the provided shares field.
+func share(ctx context.Context, db Database, coll, owner, id, field, grantee, permission string) error {
+	key := "shares." + field
+	filter := bson.M{"_id": id, "owner": owner, "deleted_at": nil, key: grantee}
+	update := bson.M{"$set": bson.M{"shares.$.permission": permission}}
+
+	res, err := db.Collection(coll).UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if res.MatchedCount > 0 {
+		return nil
+	}
+
+	filter[key] = bson.M{"$ne": grantee}
+	update = bson.M{"$push": bson.M{"shares": bson.M{field: grantee, "permission": permission}}}
+
+	res, err = db.Collection(coll).UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		return things.ErrNotFound
+	}
+
+	return nil
+}
+
+func unshare(ctx context.Context, db Database, coll, owner, id, field, grantee string) error {
+	filter := bson.M{"_id": id, "owner": owner}
+	update := bson.M{"$pull": bson.M{"shares": bson.M{field: grantee}}}
+
+	_, err := db.Collection(coll).UpdateOne(ctx, filter, update)
+	return err
+}
+
+// sharedFilter returns the conditions matching the entities shared with the
+// user or with any of the provided groups.
+func sharedFilter(email string, groups []string) bson.A {
+	conds := bson.A{bson.M{"shares." + emailField: email}}
+	if len(groups) > 0 {
+		conds = append(conds, bson.M{"shares." + groupField: bson.M{"$in": groups}})
+	}
+
+	return conds
+}
+
+// sharedPermission returns the widest permission granted to the user or any
+// of the provided groups.
+func sharedPermission(shares []dbShare, email string, groups []string) string {
+	for _, s := range shares {
+		if s.Permission != things.EditPermission {
+			continue
+		}
+
+		if s.Email != "" && s.Email == email {
+			return things.EditPermission
+		}
+
+		for _, g := range groups {
+			if s.Group == g {
+				return things.EditPermission
+			}
+		}
+	}
+
+	return things.ViewPermission
+}
+
+var comparisons = map[string]string{
+	things.EqualOp:        "$eq",
+	things.NotEqualOp:     "$ne",
+	things.LessOp:         "$lt",
+	things.LessEqualOp:    "$lte",
+	things.GreaterOp:      "$gt",
+	things.GreaterEqualOp: "$gte",
+}
+
+// queryFilter returns the condition matching the metadata query. Values of
+// a different type than the query value never match, the same way as in
+// the Postgres repositories.
+func queryFilter(q things.MetadataQuery) bson.M {
+	field := fmt.Sprintf("metadata.%s", strings.Join(q.Path, "."))
+	op := comparisons[q.Operator]
+
+	if n, ok := q.Number(); ok {
+		return bson.M{field: bson.M{op: n, "$type": "number"}}
+	}
+
+	v, ok := q.Version()
+	if !ok {
+		return bson.M{field: bson.M{op: q.Value, "$type": "string"}}
+	}
+
+	// Versions are compared part by part, as arrays of numbers.
+	parts := bson.M{"$map": bson.M{
+		"input": bson.M{"$split": bson.A{"$" + field, "."}},
+		"in":    bson.M{"$convert": bson.M{"input": "$$this", "to": "long", "onError": int64(-1)}},
+	}}
+
+	return bson.M{"$and": bson.A{
+		bson.M{field: primitive.Regex{Pattern: `^[0-9]+(\.[0-9]+)*$`}},
+		bson.M{"$expr": bson.M{op: bson.A{parts, v}}},
+	}}
+}
+
+func flatten(prefix string, m map[string]interface{}, filter bson.M) {
+	for k, v := range m {
+		key := fmt.Sprintf("%s.%s", prefix, k)
+		if nested, ok := v.(map[string]interface{}); ok && len(nested) > 0 {
+			flatten(key, nested, filter)
+			continue
+		}
+		filter[key] = v
+	}
+}
+
+// cursorFilter adds keyset condition for the provided cursor to the filter.
+// Since the cursor already marks the page start, offset is reset when set.
+func cursorFilter(filter bson.M, cursor, dir string, offset uint64) uint64 {
+	if cursor == "" {
+		return offset
+	}
+
+	op := "$gt"
+	if dir == things.DescDir {
+		op = "$lt"
+	}
+	filter["_id"] = bson.M{op: cursor}
+
+	return 0
+}
+
+// sortOrder returns sort document for the provided order and direction. ID
+// is used as a tie-breaker to keep pagination stable for non-unique fields.
+func sortOrder(order, dir string) bson.D {
+	d := 1
+	if dir == things.DescDir {
+		d = -1
+	}
+
+	switch order {
+	case things.OrderByName, things.OrderByCreatedAt:
+		return bson.D{{Key: order, Value: d}, {Key: "_id", Value: d}}
+	default:
+		return bson.D{{Key: "_id", Value: d}}
+	}
+}
+
+// searchFilter matches documents containing all of the query terms, the
+// same as plainto_tsquery does, by quoting each of them.
+func searchFilter(owner, query string) bson.M {
+	terms := strings.Fields(strings.Replace(query, `"`, " ", -1))
+	for i, t := range terms {
+		terms[i] = fmt.Sprintf(`"%s"`, t)
+	}
+
+	return bson.M{
+		"owner":      owner,
+		"deleted_at": nil,
+		"$text":      bson.M{"$search": strings.Join(terms, " ")},
+	}
+}
+
+// searchText returns the text indexed for full-text search, consisting of
+// the name and the serialized metadata.
+func searchText(name string, metadata map[string]interface{}) (string, error) {
+	if len(metadata) == 0 {
+		return name, nil
+	}
+
+	b, err := json.Marshal(metadata)
+	if err != nil {
+		return "", things.ErrMalformedEntity
+	}
+
+	return fmt.Sprintf("%s %s", name, b), nil
+}
+
+// toMetadata converts decoded BSON documents and arrays to the types
+// produced by JSON decoding.
+func toMetadata(m map[string]interface{}) map[string]interface{} {
+	if m == nil {
+		return nil
+	}
+
+	res := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		res[k] = fromBSON(v)
+	}
+
+	return res
+}
+
+func fromBSON(v interface{}) interface{} {
+	switch val := v.(type) {
+	case primitive.D:
+		return toMetadata(val.Map())
+	case primitive.M:
+		return toMetadata(val)
+	case map[string]interface{}:
+		return toMetadata(val)
+	case primitive.A:
+		arr := make([]interface{}, len(val))
+		for i, e := range val {
+			arr[i] = fromBSON(e)
+		}
+		return arr
+	case int32:
+		return float64(val)
+	case int64:
+		return float64(val)
+	default:
+		return v
+	}
+}
+
+func isDuplicate(err error) bool {
+	if we, ok := err.(mongo.WriteException); ok {
+		for _, e := range we.WriteErrors {
+			if e.Code == errDuplicate {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+type dbChannel struct {
+	ID        string                 `bson:"_id"`
+	Owner     string                 `bson:"owner"`
+	Name      string                 `bson:"name"`
+	Metadata  map[string]interface{} `bson:"metadata"`
+	Retention dbRetention            `bson:"retention"`
+	Region    string                 `bson:"region"`
+	Alias     string                 `bson:"alias"`
+	Ordered   bool                   `bson:"ordered"`
+	Type      string                 `bson:"type,omitempty"`
+	Peers     []string               `bson:"peers,omitempty"`
+	Search    string                 `bson:"search"`
+	Shares    []dbShare              `bson:"shares,omitempty"`
+	CreatedAt time.Time              `bson:"created_at"`
+	DeletedAt *time.Time             `bson:"deleted_at,omitempty"`
+}
+
+type dbRetention struct {
+	Period   int64 `bson:"period"`
+	Messages int64 `bson:"messages"`
+}
+
+type dbShare struct {
+	Group      string `bson:"group_id,omitempty"`
+	Email      string `bson:"email,omitempty"`
+	Permission string `bson:"permission"`
+}
+
+type dbConnection struct {
+	Channel string   `bson:"channel_id"`
+	Thing   string   `bson:"thing_id"`
+	Owner   string   `bson:"owner"`
+	Actions []string `bson:"actions"`
+}
+
+func toDBChannel(ch things.Channel) (dbChannel, error) {
+	search, err := searchText(ch.Name, ch.Metadata)
+	if err != nil {
+		return dbChannel{}, err
+	}
+
+	return dbChannel{
+		ID:       ch.ID,
+		Owner:    ch.Owner,
+		Name:     ch.Name,
+		Metadata: ch.Metadata,
+		Retention: dbRetention{
+			Period:   int64(ch.Retention.Period / time.Second),
+			Messages: int64(ch.Retention.Messages),
+		},
+		Region:  ch.Region,
+		Alias:   ch.Alias,
+		Ordered: ch.Ordered,
+		Type:    ch.Type,
+		Peers:   ch.Peers,
+		Search:  search,
+	}, nil
+}
+
+func toChannel(ch dbChannel) things.Channel {
+	var deletedAt time.Time
+	if ch.DeletedAt != nil {
+		deletedAt = *ch.DeletedAt
+	}
+
+	return things.Channel{
+		ID:       ch.ID,
+		Owner:    ch.Owner,
+		Name:     ch.Name,
+		Metadata: toMetadata(ch.Metadata),
+		Retention: things.Retention{
+			Period:   time.Duration(ch.Retention.Period) * time.Second,
+			Messages: uint64(ch.Retention.Messages),
+		},
+		Region:    ch.Region,
+		Alias:     ch.Alias,
+		Ordered:   ch.Ordered,
+		Type:      ch.Type,
+		Peers:     ch.Peers,
+		DeletedAt: deletedAt,
+	}
+}
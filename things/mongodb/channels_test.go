@@ -0,0 +1,226 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mongodb_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/things"
+	"github.com/mainflux/mainflux/things/mongodb"
+	"github.com/mainflux/mainflux/things/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newChannel(t *testing.T, owner string) things.Channel {
+	id, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	return things.Channel{
+		ID:    id,
+		Owner: owner,
+	}
+}
+
+func TestChannelSaveAndRetrieve(t *testing.T) {
+	chanRepo := mongodb.NewChannelRepository(db)
+	email := "channel-save@example.com"
+
+	channel := newChannel(t, email)
+	channel.Name = "channel"
+	channel.Metadata = map[string]interface{}{"tags": []interface{}{"a", "b"}}
+
+	id, err := chanRepo.Save(context.Background(), channel)
+	assert.Nil(t, err, fmt.Sprintf("create new channel: expected no error got %s\n", err))
+	assert.Equal(t, channel.ID, id, fmt.Sprintf("create new channel: expected %s got %s\n", channel.ID, id))
+
+	ch, err := chanRepo.RetrieveByID(context.Background(), email, channel.ID)
+	assert.Nil(t, err, fmt.Sprintf("retrieve channel: expected no error got %s\n", err))
+	assert.Equal(t, channel, ch, fmt.Sprintf("retrieve channel: expected %v got %v\n", channel, ch))
+
+	_, err = chanRepo.RetrieveByID(context.Background(), "wrong", channel.ID)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("retrieve channel with wrong owner: expected %s got %s\n", things.ErrNotFound, err))
+}
+
+func TestChannelRetrieveRetention(t *testing.T) {
+	chanRepo := mongodb.NewChannelRepository(db)
+	email := "channel-retention@example.com"
+
+	channel := newChannel(t, email)
+	channel.Retention = things.Retention{Period: time.Hour, Messages: 100}
+	_, err := chanRepo.Save(context.Background(), channel)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	r, err := chanRepo.RetrieveRetention(context.Background(), channel.ID)
+	assert.Nil(t, err, fmt.Sprintf("retrieve retention: expected no error got %s\n", err))
+	assert.Equal(t, channel.Retention, r, fmt.Sprintf("retrieve retention: expected %v got %v\n", channel.Retention, r))
+
+	err = chanRepo.Remove(context.Background(), email, channel.ID)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	_, err = chanRepo.RetrieveRetention(context.Background(), channel.ID)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("retrieve retention of removed channel: expected %s got %s\n", things.ErrNotFound, err))
+}
+
+func TestChannelRetrieveRegion(t *testing.T) {
+	chanRepo := mongodb.NewChannelRepository(db)
+	email := "channel-region@example.com"
+
+	channel := newChannel(t, email)
+	channel.Region = "eu-west-1"
+	_, err := chanRepo.Save(context.Background(), channel)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	r, err := chanRepo.RetrieveRegion(context.Background(), channel.ID)
+	assert.Nil(t, err, fmt.Sprintf("retrieve region: expected no error got %s\n", err))
+	assert.Equal(t, channel.Region, r, fmt.Sprintf("retrieve region: expected %s got %s\n", channel.Region, r))
+
+	err = chanRepo.Remove(context.Background(), email, channel.ID)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	_, err = chanRepo.RetrieveRegion(context.Background(), channel.ID)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("retrieve region of removed channel: expected %s got %s\n", things.ErrNotFound, err))
+}
+
+func TestChannelRetrieveOrdering(t *testing.T) {
+	chanRepo := mongodb.NewChannelRepository(db)
+	email := "channel-ordering@example.com"
+
+	channel := newChannel(t, email)
+	channel.Ordered = true
+	_, err := chanRepo.Save(context.Background(), channel)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	o, err := chanRepo.RetrieveOrdering(context.Background(), channel.ID)
+	assert.Nil(t, err, fmt.Sprintf("retrieve ordering: expected no error got %s\n", err))
+	assert.Equal(t, channel.Ordered, o, fmt.Sprintf("retrieve ordering: expected %t got %t\n", channel.Ordered, o))
+
+	err = chanRepo.Remove(context.Background(), email, channel.ID)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	_, err = chanRepo.RetrieveOrdering(context.Background(), channel.ID)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("retrieve ordering of removed channel: expected %s got %s\n", things.ErrNotFound, err))
+}
+
+func TestChannelAliases(t *testing.T) {
+	chanRepo := mongodb.NewChannelRepository(db)
+	email := "channel-alias@example.com"
+
+	channel := newChannel(t, email)
+	channel.Alias = "temp"
+	_, err := chanRepo.Save(context.Background(), channel)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	dup := newChannel(t, email)
+	dup.Alias = channel.Alias
+	_, err = chanRepo.Save(context.Background(), dup)
+	assert.Equal(t, things.ErrConflict, err, fmt.Sprintf("save channel with existing alias: expected %s got %s\n", things.ErrConflict, err))
+
+	a, err := chanRepo.RetrieveAlias(context.Background(), channel.ID)
+	assert.Nil(t, err, fmt.Sprintf("retrieve alias: expected no error got %s\n", err))
+	assert.Equal(t, channel.Alias, a, fmt.Sprintf("retrieve alias: expected %s got %s\n", channel.Alias, a))
+
+	id, err := chanRepo.RetrieveByAlias(context.Background(), channel.Alias)
+	assert.Nil(t, err, fmt.Sprintf("retrieve by alias: expected no error got %s\n", err))
+	assert.Equal(t, channel.ID, id, fmt.Sprintf("retrieve by alias: expected %s got %s\n", channel.ID, id))
+
+	err = chanRepo.Remove(context.Background(), email, channel.ID)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	_, err = chanRepo.RetrieveByAlias(context.Background(), channel.Alias)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("retrieve by alias of removed channel: expected %s got %s\n", things.ErrNotFound, err))
+}
+
+func TestChannelRetrieveDirect(t *testing.T) {
+	chanRepo := mongodb.NewChannelRepository(db)
+	email := "channel-direct@example.com"
+
+	channel := newChannel(t, email)
+	channel.Type = things.DirectChannel
+	channel.Peers = []string{"thing-1", "thing-2"}
+	_, err := chanRepo.Save(context.Background(), channel)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	dup := newChannel(t, email)
+	dup.Type = things.DirectChannel
+	dup.Peers = channel.Peers
+	_, err = chanRepo.Save(context.Background(), dup)
+	assert.Equal(t, things.ErrConflict, err, fmt.Sprintf("save direct channel of paired things: expected %s got %s\n", things.ErrConflict, err))
+
+	ch, err := chanRepo.RetrieveDirect(context.Background(), email, channel.Peers)
+	assert.Nil(t, err, fmt.Sprintf("retrieve direct channel: expected no error got %s\n", err))
+	assert.Equal(t, channel.ID, ch.ID, fmt.Sprintf("retrieve direct channel: expected %s got %s\n", channel.ID, ch.ID))
+
+	err = chanRepo.Remove(context.Background(), email, channel.ID)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	_, err = chanRepo.RetrieveDirect(context.Background(), email, channel.Peers)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("retrieve removed direct channel: expected %s got %s\n", things.ErrNotFound, err))
+}
+
+func TestConnections(t *testing.T) {
+	chanRepo := mongodb.NewChannelRepository(db)
+	thingRepo := mongodb.NewThingRepository(db)
+	email := "channel-connect@example.com"
+
+	thing := newThing(t, email, "", nil)
+	_, err := thingRepo.Save(context.Background(), thing)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	channel := newChannel(t, email)
+	_, err = chanRepo.Save(context.Background(), channel)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	err = chanRepo.Connect(context.Background(), email, channel.ID, "wrong", things.Actions)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("connect non-existing thing: expected %s got %s\n", things.ErrNotFound, err))
+
+	err = chanRepo.Connect(context.Background(), "wrong", channel.ID, thing.ID, things.Actions)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("connect with wrong owner: expected %s got %s\n", things.ErrNotFound, err))
+
+	err = chanRepo.Connect(context.Background(), email, channel.ID, thing.ID, things.Actions)
+	assert.Nil(t, err, fmt.Sprintf("connect thing: expected no error got %s\n", err))
+
+	err = chanRepo.Connect(context.Background(), email, channel.ID, thing.ID, []string{things.Subscribe})
+	assert.Nil(t, err, fmt.Sprintf("reconnect thing: expected no error got %s\n", err))
+
+	id, err := chanRepo.HasThing(context.Background(), channel.ID, thing.Key, things.Subscribe)
+	assert.Nil(t, err, fmt.Sprintf("check allowed action: expected no error got %s\n", err))
+	assert.Equal(t, thing.ID, id, fmt.Sprintf("check allowed action: expected %s got %s\n", thing.ID, id))
+
+	err = chanRepo.HasThingByID(context.Background(), channel.ID, thing.ID, things.Publish)
+	assert.Equal(t, things.ErrUnauthorizedAccess, err, fmt.Sprintf("check replaced action: expected %s got %s\n", things.ErrUnauthorizedAccess, err))
+
+	chs, err := chanRepo.RetrieveByThing(context.Background(), email, thing.ID, 0, 10)
+	assert.Nil(t, err, fmt.Sprintf("retrieve channels by thing: expected no error got %s\n", err))
+	assert.Equal(t, uint64(1), chs.Total, fmt.Sprintf("retrieve channels by thing: expected %d got %d\n", 1, chs.Total))
+
+	ths, err := thingRepo.RetrieveByChannel(context.Background(), email, channel.ID, 0, 10)
+	assert.Nil(t, err, fmt.Sprintf("retrieve things by channel: expected no error got %s\n", err))
+	assert.Equal(t, uint64(1), ths.Total, fmt.Sprintf("retrieve things by channel: expected %d got %d\n", 1, ths.Total))
+
+	err = chanRepo.Remove(context.Background(), email, channel.ID)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	err = chanRepo.HasThingByID(context.Background(), channel.ID, thing.ID, things.Subscribe)
+	assert.Equal(t, things.ErrUnauthorizedAccess, err, fmt.Sprintf("check removed channel access: expected %s got %s\n", things.ErrUnauthorizedAccess, err))
+
+	err = chanRepo.Restore(context.Background(), email, channel.ID)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	err = chanRepo.Disconnect(context.Background(), email, channel.ID, thing.ID)
+	assert.Nil(t, err, fmt.Sprintf("disconnect thing: expected no error got %s\n", err))
+
+	err = chanRepo.Disconnect(context.Background(), email, channel.ID, thing.ID)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("disconnect disconnected thing: expected %s got %s\n", things.ErrNotFound, err))
+
+	err = chanRepo.Connect(context.Background(), email, channel.ID, thing.ID, things.Actions)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	err = chanRepo.Purge(context.Background(), email, channel.ID)
+	assert.Nil(t, err, fmt.Sprintf("purge channel: expected no error got %s\n", err))
+
+	ths, err = thingRepo.RetrieveByChannel(context.Background(), email, channel.ID, 0, 10)
+	assert.Nil(t, err, fmt.Sprintf("retrieve things by purged channel: expected no error got %s\n", err))
+	assert.Equal(t, uint64(0), ths.Total, fmt.Sprintf("retrieve things by purged channel: expected %d got %d\n", 0, ths.Total))
+}
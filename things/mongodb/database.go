@@ -0,0 +1,68 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mongodb
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Database provides access to the collections and executes operations
+// spanning multiple documents atomically when the deployment supports it.
+type Database interface {
+	// Collection returns the collection with the given name.
+	Collection(string) *mongo.Collection
+
+	// Transaction executes the provided function in a transaction. If the
+	// deployment is not a replica set or a sharded cluster, the function is
+	// executed without transaction.
+	Transaction(context.Context, func(context.Context) error) error
+
+	// Close disconnects from the database.
+	Close(context.Context) error
+}
+
+var _ Database = (*database)(nil)
+
+type database struct {
+	db           *mongo.Database
+	transactions bool
+}
+
+// NewDatabase instantiates database wrapper. Transactions are used only if
+// explicitly enabled, since standalone instances don't support them.
+func NewDatabase(db *mongo.Database, transactions bool) Database {
+	return &database{
+		db:           db,
+		transactions: transactions,
+	}
+}
+
+func (d database) Collection(name string) *mongo.Collection {
+	return d.db.Collection(name)
+}
+
+func (d database) Transaction(ctx context.Context, fn func(context.Context) error) error {
+	if !d.transactions {
+		return fn(ctx)
+	}
+
+	return d.db.Client().UseSession(ctx, func(sc mongo.SessionContext) error {
+		if err := sc.StartTransaction(); err != nil {
+			return err
+		}
+
+		if err := fn(sc); err != nil {
+			sc.AbortTransaction(sc)
+			return err
+		}
+
+		return sc.CommitTransaction(sc)
+	})
+}
+
+func (d database) Close(ctx context.Context) error {
+	return d.db.Client().Disconnect(ctx)
+}
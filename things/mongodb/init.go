@@ -0,0 +1,166 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/mainflux/mainflux/things"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	thingsCollection      = "things"
+	channelsCollection    = "channels"
+	connectionsCollection = "connections"
+	quotasCollection      = "quotas"
+	schemasCollection     = "metadata_schemas"
+	payloadsCollection    = "payload_schemas"
+	keyHistoryCollection  = "key_history"
+	apiUsageCollection    = "api_usage"
+
+	// oldThingKeysCollection contains the previous keys of the things
+	// rotated before the key history was introduced.
+	oldThingKeysCollection = "thing_keys"
+)
+
+// Connect creates a connection to the MongoDB instance, creates the indexes
+// required by the repositories and enables transactions if the deployment
+// supports them. A non-nil error is returned to indicate failure.
+func Connect(ctx context.Context, url, name string) (Database, error) {
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(url))
+	if err != nil {
+		return nil, err
+	}
+
+	db := client.Database(name)
+	if err := createIndexes(ctx, db); err != nil {
+		client.Disconnect(ctx)
+		return nil, err
+	}
+
+	if err := migrateThingKeys(ctx, db); err != nil {
+		client.Disconnect(ctx)
+		return nil, err
+	}
+
+	tx, err := supportsTransactions(ctx, db)
+	if err != nil {
+		client.Disconnect(ctx)
+		return nil, err
+	}
+
+	return NewDatabase(db, tx), nil
+}
+
+func createIndexes(ctx context.Context, db *mongo.Database) error {
+	text := options.Index().SetDefaultLanguage("none")
+	alias := options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{"alias": bson.M{"$gt": ""}})
+	direct := options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{"type": things.DirectChannel})
+
+	indexes := map[string][]mongo.IndexModel{
+		thingsCollection: {
+			{Keys: bson.D{{Key: "key", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "owner", Value: 1}, {Key: "_id", Value: 1}}},
+			{Keys: bson.D{{Key: "owner", Value: 1}, {Key: "name", Value: 1}}},
+			{Keys: bson.D{{Key: "owner", Value: 1}, {Key: "created_at", Value: 1}}},
+			{Keys: bson.D{{Key: "owner", Value: 1}, {Key: "search", Value: "text"}}, Options: text},
+			{Keys: bson.D{{Key: "shares.group_id", Value: 1}}},
+			{Keys: bson.D{{Key: "shares.email", Value: 1}}},
+		},
+		channelsCollection: {
+			{Keys: bson.D{{Key: "owner", Value: 1}, {Key: "_id", Value: 1}}},
+			{Keys: bson.D{{Key: "owner", Value: 1}, {Key: "name", Value: 1}}},
+			{Keys: bson.D{{Key: "owner", Value: 1}, {Key: "created_at", Value: 1}}},
+			{Keys: bson.D{{Key: "owner", Value: 1}, {Key: "search", Value: "text"}}, Options: text},
+			{Keys: bson.D{{Key: "shares.group_id", Value: 1}}},
+			{Keys: bson.D{{Key: "shares.email", Value: 1}}},
+			{Keys: bson.D{{Key: "alias", Value: 1}}, Options: alias},
+			{Keys: bson.D{{Key: "owner", Value: 1}, {Key: "peers", Value: 1}}, Options: direct},
+		},
+		connectionsCollection: {
+			{Keys: bson.D{{Key: "channel_id", Value: 1}, {Key: "thing_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "thing_id", Value: 1}}},
+		},
+		schemasCollection: {
+			{Keys: bson.D{{Key: "owner", Value: 1}, {Key: "entity", Value: 1}}, Options: options.Index().SetUnique(true)},
+		},
+		payloadsCollection: {
+			{Keys: bson.D{{Key: "channel_id", Value: 1}, {Key: "subtopic", Value: 1}}, Options: options.Index().SetUnique(true)},
+		},
+		keyHistoryCollection: {
+			{Keys: bson.D{{Key: "key", Value: 1}}},
+			{Keys: bson.D{{Key: "thing_id", Value: 1}}},
+		},
+		apiUsageCollection: {
+			{Keys: bson.D{{Key: "owner", Value: 1}, {Key: "hour", Value: 1}, {Key: "method", Value: 1}}, Options: options.Index().SetUnique(true)},
+		},
+	}
+
+	for coll, models := range indexes {
+		if _, err := db.Collection(coll).Indexes().CreateMany(ctx, models); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// migrateThingKeys moves the previous keys of the rotated things, which were
+// kept until they expired, to the key history.
+func migrateThingKeys(ctx context.Context, db *mongo.Database) error {
+	coll := db.Collection(oldThingKeysCollection)
+
+	cur, err := coll.Find(ctx, bson.M{})
+	if err != nil {
+		return err
+	}
+	defer cur.Close(ctx)
+
+	now := time.Now()
+	for cur.Next(ctx) {
+		var dbk struct {
+			Key       string    `bson:"_id"`
+			ThingID   string    `bson:"thing_id"`
+			ExpiresAt time.Time `bson:"expires_at"`
+		}
+		if err := cur.Decode(&dbk); err != nil {
+			return err
+		}
+
+		prev := dbPreviousKey{
+			Key:        dbk.Key,
+			ThingID:    dbk.ThingID,
+			ReplacedAt: now,
+			ExpiresAt:  dbk.ExpiresAt,
+		}
+		if _, err := db.Collection(keyHistoryCollection).InsertOne(ctx, prev); err != nil {
+			return err
+		}
+	}
+
+	if err := cur.Err(); err != nil {
+		return err
+	}
+
+	return coll.Drop(ctx)
+}
+
+// supportsTransactions checks if the deployment is a replica set or a
+// sharded cluster, since standalone instances don't support transactions.
+func supportsTransactions(ctx context.Context, db *mongo.Database) (bool, error) {
+	var res struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}
+
+	if err := db.RunCommand(ctx, bson.D{{Key: "isMaster", Value: 1}}).Decode(&res); err != nil {
+		return false, err
+	}
+
+	return res.SetName != "" || res.Msg == "isdbgrid", nil
+}
@@ -0,0 +1,123 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mongodb
+
+import (
+	"context"
+
+	"github.com/mainflux/mainflux/things"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var _ things.QuotaRepository = (*quotaRepository)(nil)
+
+type quotaRepository struct {
+	db Database
+}
+
+// NewQuotaRepository instantiates a MongoDB implementation of quota
+// repository.
+func NewQuotaRepository(db Database) things.QuotaRepository {
+	return &quotaRepository{
+		db: db,
+	}
+}
+
+func (qr quotaRepository) Save(ctx context.Context, quota things.Quota) error {
+	dbq := toDBQuota(quota)
+	opts := options.Replace().SetUpsert(true)
+
+	_, err := qr.db.Collection(quotasCollection).ReplaceOne(ctx, bson.M{"_id": dbq.Owner}, dbq, opts)
+	return err
+}
+
+func (qr quotaRepository) RetrieveByOwner(ctx context.Context, owner string) (things.Quota, error) {
+	var dbq dbQuota
+	if err := qr.db.Collection(quotasCollection).FindOne(ctx, bson.M{"_id": owner}).Decode(&dbq); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return things.Quota{}, things.ErrNotFound
+		}
+		return things.Quota{}, err
+	}
+
+	return toQuota(dbq), nil
+}
+
+func (qr quotaRepository) Remove(ctx context.Context, owner string) error {
+	_, err := qr.db.Collection(quotasCollection).DeleteOne(ctx, bson.M{"_id": owner})
+	return err
+}
+
+func (qr quotaRepository) RetrieveUsage(ctx context.Context, owner string) (things.Usage, error) {
+	filter := bson.M{"owner": owner, "deleted_at": nil}
+
+	ths, err := qr.db.Collection(thingsCollection).CountDocuments(ctx, filter)
+	if err != nil {
+		return things.Usage{}, err
+	}
+
+	chs, err := qr.db.Collection(channelsCollection).CountDocuments(ctx, filter)
+	if err != nil {
+		return things.Usage{}, err
+	}
+
+	// Connections are kept when the channel or the thing is removed, so
+	// they're excluded here instead.
+	removed := bson.M{"owner": owner, "deleted_at": bson.M{"$ne": nil}}
+	removedChs, err := qr.db.Collection(channelsCollection).Distinct(ctx, "_id", removed)
+	if err != nil {
+		return things.Usage{}, err
+	}
+
+	removedThs, err := qr.db.Collection(thingsCollection).Distinct(ctx, "_id", removed)
+	if err != nil {
+		return things.Usage{}, err
+	}
+
+	filter = bson.M{"owner": owner}
+	if len(removedChs) > 0 {
+		filter["channel_id"] = bson.M{"$nin": removedChs}
+	}
+	if len(removedThs) > 0 {
+		filter["thing_id"] = bson.M{"$nin": removedThs}
+	}
+
+	conns, err := qr.db.Collection(connectionsCollection).CountDocuments(ctx, filter)
+	if err != nil {
+		return things.Usage{}, err
+	}
+
+	return things.Usage{
+		Things:      uint64(ths),
+		Channels:    uint64(chs),
+		Connections: uint64(conns),
+	}, nil
+}
+
+type dbQuota struct {
+	Owner       string `bson:"_id"`
+	Things      int64  `bson:"things"`
+	Channels    int64  `bson:"channels"`
+	Connections int64  `bson:"connections"`
+}
+
+func toDBQuota(quota things.Quota) dbQuota {
+	return dbQuota{
+		Owner:       quota.Owner,
+		Things:      int64(quota.Things),
+		Channels:    int64(quota.Channels),
+		Connections: int64(quota.Connections),
+	}
+}
+
+func toQuota(dbq dbQuota) things.Quota {
+	return things.Quota{
+		Owner:       dbq.Owner,
+		Things:      uint64(dbq.Things),
+		Channels:    uint64(dbq.Channels),
+		Connections: uint64(dbq.Connections),
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mongodb_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux/things"
+	"github.com/mainflux/mainflux/things/mongodb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuotaSave(t *testing.T) {
+	quotaRepo := mongodb.NewQuotaRepository(db)
+	email := "quota-save@example.com"
+
+	_, err := quotaRepo.RetrieveByOwner(context.Background(), email)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("retrieve non-existing quota: expected %s got %s\n", things.ErrNotFound, err))
+
+	for _, quota := range []things.Quota{
+		{Owner: email, Things: 10, Channels: 5, Connections: 50},
+		{Owner: email, Things: 20},
+	} {
+		err := quotaRepo.Save(context.Background(), quota)
+		assert.Nil(t, err, fmt.Sprintf("save quota: got unexpected error: %s\n", err))
+
+		saved, err := quotaRepo.RetrieveByOwner(context.Background(), email)
+		assert.Nil(t, err, fmt.Sprintf("retrieve quota: got unexpected error: %s\n", err))
+		assert.Equal(t, quota, saved, fmt.Sprintf("retrieve quota: expected %v got %v\n", quota, saved))
+	}
+
+	err = quotaRepo.Remove(context.Background(), email)
+	assert.Nil(t, err, fmt.Sprintf("remove quota: got unexpected error: %s\n", err))
+
+	_, err = quotaRepo.RetrieveByOwner(context.Background(), email)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("retrieve removed quota: expected %s got %s\n", things.ErrNotFound, err))
+}
+
+func TestUsageRetrieval(t *testing.T) {
+	thingRepo := mongodb.NewThingRepository(db)
+	chanRepo := mongodb.NewChannelRepository(db)
+	quotaRepo := mongodb.NewQuotaRepository(db)
+	email := "quota-usage@example.com"
+
+	channel := newChannel(t, email)
+	_, err := chanRepo.Save(context.Background(), channel)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	ths := []things.Thing{}
+	for i := 0; i < 3; i++ {
+		th := newThing(t, email, "", nil)
+		_, err := thingRepo.Save(context.Background(), th)
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+		err = chanRepo.Connect(context.Background(), email, channel.ID, th.ID, things.Actions)
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+		ths = append(ths, th)
+	}
+
+	err = thingRepo.Remove(context.Background(), email, ths[0].ID)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	expected := things.Usage{Things: 2, Channels: 1, Connections: 2}
+	usage, err := quotaRepo.RetrieveUsage(context.Background(), email)
+	assert.Nil(t, err, fmt.Sprintf("retrieve usage: got unexpected error: %s\n", err))
+	assert.Equal(t, expected, usage, fmt.Sprintf("retrieve usage: expected %v got %v\n", expected, usage))
+}
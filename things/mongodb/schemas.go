@@ -0,0 +1,168 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mongodb
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/mainflux/mainflux/things"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var _ things.SchemaRepository = (*schemaRepository)(nil)
+
+type schemaRepository struct {
+	db Database
+}
+
+// NewSchemaRepository instantiates a MongoDB implementation of metadata
+// schema repository.
+func NewSchemaRepository(db Database) things.SchemaRepository {
+	return &schemaRepository{
+		db: db,
+	}
+}
+
+func (sr schemaRepository) Save(ctx context.Context, schema things.MetadataSchema) error {
+	def, err := json.Marshal(schema.Definition)
+	if err != nil {
+		return things.ErrMalformedEntity
+	}
+
+	dbs := dbSchema{
+		Owner:      schema.Owner,
+		Entity:     schema.Entity,
+		Definition: string(def),
+	}
+	opts := options.Replace().SetUpsert(true)
+
+	_, err = sr.db.Collection(schemasCollection).ReplaceOne(ctx, schemaFilter(schema.Owner, schema.Entity), dbs, opts)
+	return err
+}
+
+func (sr schemaRepository) RetrieveByEntity(ctx context.Context, owner, entity string) (things.MetadataSchema, error) {
+	var dbs dbSchema
+	if err := sr.db.Collection(schemasCollection).FindOne(ctx, schemaFilter(owner, entity)).Decode(&dbs); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return things.MetadataSchema{}, things.ErrNotFound
+		}
+		return things.MetadataSchema{}, err
+	}
+
+	var def map[string]interface{}
+	if err := json.Unmarshal([]byte(dbs.Definition), &def); err != nil {
+		return things.MetadataSchema{}, things.ErrScanMetadata
+	}
+
+	return things.MetadataSchema{
+		Owner:      dbs.Owner,
+		Entity:     dbs.Entity,
+		Definition: def,
+	}, nil
+}
+
+func (sr schemaRepository) Remove(ctx context.Context, owner, entity string) error {
+	_, err := sr.db.Collection(schemasCollection).DeleteOne(ctx, schemaFilter(owner, entity))
+	return err
+}
+
+func (sr schemaRepository) SavePayload(ctx context.Context, schema things.PayloadSchema) error {
+	def, err := json.Marshal(schema.Definition)
+	if err != nil {
+		return things.ErrMalformedEntity
+	}
+
+	dbps := dbPayloadSchema{
+		Channel:    schema.Channel,
+		Owner:      schema.Owner,
+		Subtopic:   schema.Subtopic,
+		Definition: string(def),
+	}
+	opts := options.Replace().SetUpsert(true)
+
+	_, err = sr.db.Collection(payloadsCollection).ReplaceOne(ctx, payloadFilter(schema.Channel, schema.Subtopic), dbps, opts)
+	return err
+}
+
+func (sr schemaRepository) RetrievePayload(ctx context.Context, chanID, subtopic string) (things.PayloadSchema, error) {
+	var dbps dbPayloadSchema
+	if err := sr.db.Collection(payloadsCollection).FindOne(ctx, payloadFilter(chanID, subtopic)).Decode(&dbps); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return things.PayloadSchema{}, things.ErrNotFound
+		}
+		return things.PayloadSchema{}, err
+	}
+
+	return toPayloadSchema(dbps)
+}
+
+func (sr schemaRepository) RetrievePayloads(ctx context.Context, chanID string) ([]things.PayloadSchema, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "subtopic", Value: 1}})
+	cur, err := sr.db.Collection(payloadsCollection).Find(ctx, bson.M{"channel_id": chanID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	schemas := []things.PayloadSchema{}
+	for cur.Next(ctx) {
+		var dbps dbPayloadSchema
+		if err := cur.Decode(&dbps); err != nil {
+			return nil, err
+		}
+
+		schema, err := toPayloadSchema(dbps)
+		if err != nil {
+			return nil, err
+		}
+		schemas = append(schemas, schema)
+	}
+
+	return schemas, cur.Err()
+}
+
+func (sr schemaRepository) RemovePayload(ctx context.Context, chanID, subtopic string) error {
+	_, err := sr.db.Collection(payloadsCollection).DeleteOne(ctx, payloadFilter(chanID, subtopic))
+	return err
+}
+
+func payloadFilter(chanID, subtopic string) bson.M {
+	return bson.M{"channel_id": chanID, "subtopic": subtopic}
+}
+
+func toPayloadSchema(dbps dbPayloadSchema) (things.PayloadSchema, error) {
+	var def map[string]interface{}
+	if err := json.Unmarshal([]byte(dbps.Definition), &def); err != nil {
+		return things.PayloadSchema{}, things.ErrScanMetadata
+	}
+
+	return things.PayloadSchema{
+		Owner:      dbps.Owner,
+		Channel:    dbps.Channel,
+		Subtopic:   dbps.Subtopic,
+		Definition: def,
+	}, nil
+}
+
+func schemaFilter(owner, entity string) bson.M {
+	return bson.M{"owner": owner, "entity": entity}
+}
+
+// Schema definition is stored as JSON text, since JSON Schema keywords such
+// as $ref aren't valid document field names.
+type dbSchema struct {
+	Owner      string `bson:"owner"`
+	Entity     string `bson:"entity"`
+	Definition string `bson:"definition"`
+}
+
+type dbPayloadSchema struct {
+	Channel    string `bson:"channel_id"`
+	Owner      string `bson:"channel_owner"`
+	Subtopic   string `bson:"subtopic"`
+	Definition string `bson:"definition"`
+}
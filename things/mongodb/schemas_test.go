@@ -0,0 +1,104 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mongodb_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux/things"
+	"github.com/mainflux/mainflux/things/mongodb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemaSave(t *testing.T) {
+	email := "schema-save@example.com"
+	schemaRepo := mongodb.NewSchemaRepository(db)
+
+	_, err := schemaRepo.RetrieveByEntity(context.Background(), email, things.ThingsEntity)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("retrieve non-existing schema: expected %s got %s\n", things.ErrNotFound, err))
+
+	for _, schema := range []things.MetadataSchema{
+		{
+			Owner:  email,
+			Entity: things.ThingsEntity,
+			Definition: map[string]interface{}{
+				"$schema": "http://json-schema.org/draft-07/schema#",
+				"type":    "object",
+			},
+		},
+		{
+			Owner:      email,
+			Entity:     things.ThingsEntity,
+			Definition: map[string]interface{}{"required": []interface{}{"serial"}},
+		},
+		{
+			Owner:      email,
+			Entity:     things.ChannelsEntity,
+			Definition: map[string]interface{}{"type": "object"},
+		},
+	} {
+		err := schemaRepo.Save(context.Background(), schema)
+		assert.Nil(t, err, fmt.Sprintf("save schema: got unexpected error: %s\n", err))
+
+		saved, err := schemaRepo.RetrieveByEntity(context.Background(), email, schema.Entity)
+		assert.Nil(t, err, fmt.Sprintf("retrieve schema: got unexpected error: %s\n", err))
+		assert.Equal(t, schema, saved, fmt.Sprintf("retrieve schema: expected %v got %v\n", schema, saved))
+	}
+
+	err = schemaRepo.Remove(context.Background(), email, things.ThingsEntity)
+	assert.Nil(t, err, fmt.Sprintf("remove schema: got unexpected error: %s\n", err))
+
+	_, err = schemaRepo.RetrieveByEntity(context.Background(), email, things.ThingsEntity)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("retrieve removed schema: expected %s got %s\n", things.ErrNotFound, err))
+
+	_, err = schemaRepo.RetrieveByEntity(context.Background(), email, things.ChannelsEntity)
+	assert.Nil(t, err, fmt.Sprintf("retrieve channels schema: got unexpected error: %s\n", err))
+}
+
+func TestPayloadSchemaSave(t *testing.T) {
+	chanID := "payload-schema-save"
+	schemaRepo := mongodb.NewSchemaRepository(db)
+
+	_, err := schemaRepo.RetrievePayload(context.Background(), chanID, "")
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("retrieve non-existing payload schema: expected %s got %s\n", things.ErrNotFound, err))
+
+	for _, schema := range []things.PayloadSchema{
+		{
+			Owner:      "payload-schema-save@example.com",
+			Channel:    chanID,
+			Definition: map[string]interface{}{"type": "object"},
+		},
+		{
+			Owner:      "payload-schema-save@example.com",
+			Channel:    chanID,
+			Subtopic:   "temp",
+			Definition: map[string]interface{}{"type": "object"},
+		},
+		{
+			Owner:      "payload-schema-save@example.com",
+			Channel:    chanID,
+			Subtopic:   "temp",
+			Definition: map[string]interface{}{"required": []interface{}{"temp"}},
+		},
+	} {
+		err := schemaRepo.SavePayload(context.Background(), schema)
+		assert.Nil(t, err, fmt.Sprintf("save payload schema: got unexpected error: %s\n", err))
+
+		saved, err := schemaRepo.RetrievePayload(context.Background(), chanID, schema.Subtopic)
+		assert.Nil(t, err, fmt.Sprintf("retrieve payload schema: got unexpected error: %s\n", err))
+		assert.Equal(t, schema, saved, fmt.Sprintf("retrieve payload schema: expected %v got %v\n", schema, saved))
+	}
+
+	schemas, err := schemaRepo.RetrievePayloads(context.Background(), chanID)
+	assert.Nil(t, err, fmt.Sprintf("retrieve payload schemas: got unexpected error: %s\n", err))
+	assert.Equal(t, 2, len(schemas), fmt.Sprintf("retrieve payload schemas: expected 2 got %d\n", len(schemas)))
+
+	err = schemaRepo.RemovePayload(context.Background(), chanID, "temp")
+	assert.Nil(t, err, fmt.Sprintf("remove payload schema: got unexpected error: %s\n", err))
+
+	_, err = schemaRepo.RetrievePayload(context.Background(), chanID, "temp")
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("retrieve removed payload schema: expected %s got %s\n", things.ErrNotFound, err))
+}
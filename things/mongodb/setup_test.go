@@ -0,0 +1,48 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package mongodb_test contains tests for MongoDB repository
+// implementations.
+package mongodb_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/mainflux/mainflux/things/mongodb"
+	dockertest "gopkg.in/ory-am/dockertest.v3"
+)
+
+var db mongodb.Database
+
+func TestMain(m *testing.M) {
+	pool, err := dockertest.NewPool("")
+	if err != nil {
+		log.Fatalf("Could not connect to docker: %s", err)
+	}
+
+	container, err := pool.Run("mongo", "4.0", []string{})
+	if err != nil {
+		log.Fatalf("Could not start container: %s", err)
+	}
+
+	url := fmt.Sprintf("mongodb://localhost:%s", container.GetPort("27017/tcp"))
+	if err := pool.Retry(func() error {
+		db, err = mongodb.Connect(context.Background(), url, "test")
+		return err
+	}); err != nil {
+		log.Fatalf("Could not connect to docker: %s", err)
+	}
+
+	code := m.Run()
+
+	db.Close(context.Background())
+	if err := pool.Purge(container); err != nil {
+		log.Fatalf("Could not purge container: %s", err)
+	}
+
+	os.Exit(code)
+}
@@ -0,0 +1,506 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/mainflux/mainflux/things"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var _ things.ThingRepository = (*thingRepository)(nil)
+
+type thingRepository struct {
+	db Database
+}
+
+// NewThingRepository instantiates a MongoDB implementation of thing
+// repository.
+func NewThingRepository(db Database) things.ThingRepository {
+	return &thingRepository{
+		db: db,
+	}
+}
+
+func (tr thingRepository) Save(ctx context.Context, thing things.Thing) (string, error) {
+	dbth, err := toDBThing(thing)
+	if err != nil {
+		return "", err
+	}
+	dbth.CreatedAt = time.Now().UTC()
+
+	if _, err := tr.db.Collection(thingsCollection).InsertOne(ctx, dbth); err != nil {
+		if isDuplicate(err) {
+			return "", things.ErrConflict
+		}
+		return "", err
+	}
+
+	return dbth.ID, nil
+}
+
+func (tr thingRepository) Update(ctx context.Context, thing things.Thing) error {
+	dbth, err := toDBThing(thing)
+	if err != nil {
+		return err
+	}
+
+	filter := bson.M{"_id": thing.ID, "owner": thing.Owner, "deleted_at": nil}
+	update := bson.M{"$set": bson.M{
+		"name":     dbth.Name,
+		"metadata": dbth.Metadata,
+		"search":   dbth.Search,
+	}}
+
+	res, err := tr.db.Collection(thingsCollection).UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		return things.ErrNotFound
+	}
+
+	return nil
+}
+
+func (tr thingRepository) UpdateKey(ctx context.Context, owner, id, key string, overlap time.Duration) error {
+	return tr.db.Transaction(ctx, func(ctx context.Context) error {
+		filter := bson.M{"_id": id, "owner": owner, "deleted_at": nil}
+		update := bson.M{"$set": bson.M{"key": key}}
+		opts := options.FindOneAndUpdate().
+			SetProjection(bson.M{"key": 1}).
+			SetReturnDocument(options.Before)
+
+		var prev dbThing
+		if err := tr.db.Collection(thingsCollection).FindOneAndUpdate(ctx, filter, update, opts).Decode(&prev); err != nil {
+			switch {
+			case err == mongo.ErrNoDocuments:
+				return things.ErrNotFound
+			case isDuplicate(err):
+				return things.ErrConflict
+			}
+			return err
+		}
+
+		// Retried update leaves the key intact, so there is nothing to
+		// record.
+		if prev.Key == key {
+			return nil
+		}
+
+		now := time.Now()
+		dbk := dbPreviousKey{
+			Key:        prev.Key,
+			ThingID:    id,
+			ReplacedAt: now,
+			ExpiresAt:  now.Add(overlap),
+		}
+		_, err := tr.db.Collection(keyHistoryCollection).InsertOne(ctx, dbk)
+		return err
+	})
+}
+
+func (tr thingRepository) HashKeys(ctx context.Context, hasher things.KeyHasher) error {
+	ths, err := tr.unhashedThings(ctx, hasher)
+	if err != nil {
+		return err
+	}
+
+	for _, th := range ths {
+		update := bson.M{"$set": bson.M{"key": hasher.Hash(th.Key)}}
+		if _, err := tr.db.Collection(thingsCollection).UpdateOne(ctx, bson.M{"_id": th.ID}, update); err != nil {
+			return err
+		}
+	}
+
+	keys, err := tr.unhashedKeys(ctx, hasher)
+	if err != nil {
+		return err
+	}
+
+	for _, dbk := range keys {
+		update := bson.M{"$set": bson.M{"key": hasher.Hash(dbk.Key)}}
+		if _, err := tr.db.Collection(keyHistoryCollection).UpdateOne(ctx, bson.M{"_id": dbk.ID}, update); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// unhashedThings retrieves the things having plain-text keys. Things are
+// collected before the update, so that they aren't modified while they're
+// being read.
+func (tr thingRepository) unhashedThings(ctx context.Context, hasher things.KeyHasher) ([]dbThing, error) {
+	opts := options.Find().SetProjection(bson.M{"key": 1})
+
+	cur, err := tr.db.Collection(thingsCollection).Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var ths []dbThing
+	for cur.Next(ctx) {
+		var dbth dbThing
+		if err := cur.Decode(&dbth); err != nil {
+			return nil, err
+		}
+
+		if !hasher.Hashed(dbth.Key) {
+			ths = append(ths, dbth)
+		}
+	}
+
+	return ths, cur.Err()
+}
+
+// unhashedKeys retrieves the plain-text previous keys from the key history.
+func (tr thingRepository) unhashedKeys(ctx context.Context, hasher things.KeyHasher) ([]dbPreviousKey, error) {
+	cur, err := tr.db.Collection(keyHistoryCollection).Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var keys []dbPreviousKey
+	for cur.Next(ctx) {
+		var dbk dbPreviousKey
+		if err := cur.Decode(&dbk); err != nil {
+			return nil, err
+		}
+
+		if !hasher.Hashed(dbk.Key) {
+			keys = append(keys, dbk)
+		}
+	}
+
+	return keys, cur.Err()
+}
+
+func (tr thingRepository) RetrieveByID(ctx context.Context, owner, id string) (things.Thing, error) {
+	filter := bson.M{"_id": id, "owner": owner, "deleted_at": nil}
+
+	var dbth dbThing
+	if err := tr.db.Collection(thingsCollection).FindOne(ctx, filter).Decode(&dbth); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return things.Thing{}, things.ErrNotFound
+		}
+		return things.Thing{}, err
+	}
+
+	return toThing(dbth), nil
+}
+
+func (tr thingRepository) RetrieveByKey(ctx context.Context, key string) (string, error) {
+	return retrieveIDByKey(ctx, tr.db, key)
+}
+
+func (tr thingRepository) RetrieveOwner(ctx context.Context, id string) (string, error) {
+	filter := bson.M{"_id": id, "deleted_at": nil}
+
+	var dbth dbThing
+	if err := tr.db.Collection(thingsCollection).FindOne(ctx, filter).Decode(&dbth); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return "", things.ErrNotFound
+		}
+		return "", err
+	}
+
+	return dbth.Owner, nil
+}
+
+func (tr thingRepository) RetrieveShared(ctx context.Context, id, email string, groups []string) (things.Thing, string, error) {
+	filter := bson.M{"_id": id, "deleted_at": nil, "$or": sharedFilter(email, groups)}
+
+	var dbth dbThing
+	if err := tr.db.Collection(thingsCollection).FindOne(ctx, filter).Decode(&dbth); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return things.Thing{}, "", things.ErrNotFound
+		}
+		return things.Thing{}, "", err
+	}
+
+	return toThing(dbth), sharedPermission(dbth.Shares, email, groups), nil
+}
+
+func (tr thingRepository) RetrieveAll(ctx context.Context, owner string, pq things.PageQuery, groups []string) (things.ThingsPage, error) {
+	filter := listFilter(owner, pq, groups)
+	total, err := tr.db.Collection(thingsCollection).CountDocuments(ctx, filter)
+	if err != nil {
+		return things.ThingsPage{}, err
+	}
+
+	offset := cursorFilter(filter, pq.Cursor, pq.Dir, pq.Offset)
+	opts := options.Find().
+		SetSort(sortOrder(pq.Order, pq.Dir)).
+		SetSkip(int64(offset)).
+		SetLimit(int64(pq.Limit))
+
+	items, err := tr.find(ctx, filter, opts)
+	if err != nil {
+		return things.ThingsPage{}, err
+	}
+
+	return things.ThingsPage{
+		Things: items,
+		PageMetadata: things.PageMetadata{
+			Total:  uint64(total),
+			Offset: offset,
+			Limit:  pq.Limit,
+		},
+	}, nil
+}
+
+func (tr thingRepository) Search(ctx context.Context, owner, query string, offset, limit uint64) (things.ThingsPage, error) {
+	filter := searchFilter(owner, query)
+	opts := options.Find().
+		SetProjection(bson.M{"score": bson.M{"$meta": "textScore"}}).
+		SetSort(bson.D{{Key: "score", Value: bson.M{"$meta": "textScore"}}, {Key: "_id", Value: 1}}).
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit))
+
+	items, err := tr.find(ctx, filter, opts)
+	if err != nil {
+		return things.ThingsPage{}, err
+	}
+
+	total, err := tr.db.Collection(thingsCollection).CountDocuments(ctx, filter)
+	if err != nil {
+		return things.ThingsPage{}, err
+	}
+
+	return things.ThingsPage{
+		Things: items,
+		PageMetadata: things.PageMetadata{
+			Total:  uint64(total),
+			Offset: offset,
+			Limit:  limit,
+		},
+	}, nil
+}
+
+func (tr thingRepository) RetrieveByChannel(ctx context.Context, owner, channel string, offset, limit uint64) (things.ThingsPage, error) {
+	ids, err := connected(ctx, tr.db, bson.M{"channel_id": channel, "owner": owner}, "thing_id")
+	if err != nil {
+		return things.ThingsPage{}, err
+	}
+
+	filter := bson.M{"_id": bson.M{"$in": ids}, "owner": owner, "deleted_at": nil}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: 1}}).
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit))
+
+	items, err := tr.find(ctx, filter, opts)
+	if err != nil {
+		return things.ThingsPage{}, err
+	}
+
+	total, err := tr.db.Collection(thingsCollection).CountDocuments(ctx, filter)
+	if err != nil {
+		return things.ThingsPage{}, err
+	}
+
+	return things.ThingsPage{
+		Things: items,
+		PageMetadata: things.PageMetadata{
+			Total:  uint64(total),
+			Offset: offset,
+			Limit:  limit,
+		},
+	}, nil
+}
+
+func (tr thingRepository) Export(ctx context.Context, owner string, fn func(things.Thing) error) error {
+	filter := bson.M{"owner": owner, "deleted_at": nil}
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+
+	cur, err := tr.db.Collection(thingsCollection).Find(ctx, filter, opts)
+	if err != nil {
+		return err
+	}
+	defer cur.Close(ctx)
+
+	for cur.Next(ctx) {
+		var dbth dbThing
+		if err := cur.Decode(&dbth); err != nil {
+			return err
+		}
+
+		if err := fn(toThing(dbth)); err != nil {
+			return err
+		}
+	}
+
+	return cur.Err()
+}
+
+func (tr thingRepository) Remove(ctx context.Context, owner, id string) error {
+	filter := bson.M{"_id": id, "owner": owner, "deleted_at": nil}
+	update := bson.M{"$set": bson.M{"deleted_at": time.Now().UTC()}}
+	tr.db.Collection(thingsCollection).UpdateOne(ctx, filter, update)
+	return nil
+}
+
+func (tr thingRepository) Restore(ctx context.Context, owner, id string) error {
+	filter := bson.M{"_id": id, "owner": owner, "deleted_at": bson.M{"$ne": nil}}
+	update := bson.M{"$unset": bson.M{"deleted_at": ""}}
+
+	res, err := tr.db.Collection(thingsCollection).UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		return things.ErrNotFound
+	}
+
+	return nil
+}
+
+func (tr thingRepository) Purge(ctx context.Context, owner, id string) error {
+	return tr.db.Transaction(ctx, func(ctx context.Context) error {
+		res, err := tr.db.Collection(thingsCollection).DeleteOne(ctx, bson.M{"_id": id, "owner": owner})
+		if err != nil || res.DeletedCount == 0 {
+			return err
+		}
+
+		_, err = tr.db.Collection(connectionsCollection).DeleteMany(ctx, bson.M{"thing_id": id, "owner": owner})
+		if err != nil {
+			return err
+		}
+
+		_, err = tr.db.Collection(keyHistoryCollection).DeleteMany(ctx, bson.M{"thing_id": id})
+		return err
+	})
+}
+
+func (tr thingRepository) Share(ctx context.Context, owner, id, group, permission string) error {
+	return share(ctx, tr.db, thingsCollection, owner, id, groupField, group, permission)
+}
+
+func (tr thingRepository) Unshare(ctx context.Context, owner, id, group string) error {
+	return unshare(ctx, tr.db, thingsCollection, owner, id, groupField, group)
+}
+
+func (tr thingRepository) ShareWithUser(ctx context.Context, owner, id, email, permission string) error {
+	return share(ctx, tr.db, thingsCollection, owner, id, emailField, email, permission)
+}
+
+func (tr thingRepository) UnshareWithUser(ctx context.Context, owner, id, email string) error {
+	return unshare(ctx, tr.db, thingsCollection, owner, id, emailField, email)
+}
+
+func (tr thingRepository) find(ctx context.Context, filter bson.M, opts *options.FindOptions) ([]things.Thing, error) {
+	cur, err := tr.db.Collection(thingsCollection).Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	items := []things.Thing{}
+	for cur.Next(ctx) {
+		var dbth dbThing
+		if err := cur.Decode(&dbth); err != nil {
+			return nil, err
+		}
+		items = append(items, toThing(dbth))
+	}
+
+	return items, cur.Err()
+}
+
+// retrieveIDByKey returns the ID of the thing having the provided current
+// key, or the previous key that didn't expire yet.
+func retrieveIDByKey(ctx context.Context, db Database, key string) (string, error) {
+	filter := bson.M{"key": key, "deleted_at": nil}
+	opts := options.FindOne().SetProjection(bson.M{"_id": 1})
+
+	var dbth dbThing
+	err := db.Collection(thingsCollection).FindOne(ctx, filter, opts).Decode(&dbth)
+	if err == nil {
+		return dbth.ID, nil
+	}
+	if err != mongo.ErrNoDocuments {
+		return "", err
+	}
+
+	var dbk dbPreviousKey
+	filter = bson.M{"key": key, "expires_at": bson.M{"$gt": time.Now()}}
+	if err := db.Collection(keyHistoryCollection).FindOne(ctx, filter).Decode(&dbk); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return "", things.ErrNotFound
+		}
+		return "", err
+	}
+
+	filter = bson.M{"_id": dbk.ThingID, "deleted_at": nil}
+	if err := db.Collection(thingsCollection).FindOne(ctx, filter, opts).Decode(&dbth); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return "", things.ErrNotFound
+		}
+		return "", err
+	}
+
+	return dbth.ID, nil
+}
+
+type dbPreviousKey struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty"`
+	Key        string             `bson:"key"`
+	ThingID    string             `bson:"thing_id"`
+	ReplacedAt time.Time          `bson:"replaced_at"`
+	ExpiresAt  time.Time          `bson:"expires_at"`
+}
+
+type dbThing struct {
+	ID        string                 `bson:"_id"`
+	Owner     string                 `bson:"owner"`
+	Name      string                 `bson:"name"`
+	Key       string                 `bson:"key"`
+	Metadata  map[string]interface{} `bson:"metadata"`
+	Search    string                 `bson:"search"`
+	Shares    []dbShare              `bson:"shares,omitempty"`
+	CreatedAt time.Time              `bson:"created_at"`
+	DeletedAt *time.Time             `bson:"deleted_at,omitempty"`
+}
+
+func toDBThing(th things.Thing) (dbThing, error) {
+	search, err := searchText(th.Name, th.Metadata)
+	if err != nil {
+		return dbThing{}, err
+	}
+
+	return dbThing{
+		ID:       th.ID,
+		Owner:    th.Owner,
+		Name:     th.Name,
+		Key:      th.Key,
+		Metadata: th.Metadata,
+		Search:   search,
+	}, nil
+}
+
+func toThing(dbth dbThing) things.Thing {
+	var deletedAt time.Time
+	if dbth.DeletedAt != nil {
+		deletedAt = *dbth.DeletedAt
+	}
+
+	return things.Thing{
+		ID:        dbth.ID,
+		Owner:     dbth.Owner,
+		Name:      dbth.Name,
+		Key:       dbth.Key,
+		Metadata:  toMetadata(dbth.Metadata),
+		DeletedAt: deletedAt,
+	}
+}
@@ -0,0 +1,445 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mongodb_test
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/things"
+	"github.com/mainflux/mainflux/things/hmac"
+	"github.com/mainflux/mainflux/things/mongodb"
+	"github.com/mainflux/mainflux/things/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newThing(t *testing.T, owner, name string, metadata things.Metadata) things.Thing {
+	id, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	key, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	return things.Thing{
+		ID:       id,
+		Owner:    owner,
+		Name:     name,
+		Key:      key,
+		Metadata: metadata,
+	}
+}
+
+func TestThingSave(t *testing.T) {
+	thingRepo := mongodb.NewThingRepository(db)
+	email := "thing-save@example.com"
+	thing := newThing(t, email, "", nil)
+
+	cases := []struct {
+		desc  string
+		thing things.Thing
+		err   error
+	}{
+		{
+			desc:  "create new thing",
+			thing: thing,
+			err:   nil,
+		},
+		{
+			desc:  "create thing with conflicting key",
+			thing: newThing(t, email, "", nil),
+			err:   things.ErrConflict,
+		},
+	}
+	cases[1].thing.Key = thing.Key
+
+	for _, tc := range cases {
+		_, err := thingRepo.Save(context.Background(), tc.thing)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestThingKeyOverlap(t *testing.T) {
+	thingRepo := mongodb.NewThingRepository(db)
+	email := "thing-key-overlap@example.com"
+
+	thing := newThing(t, email, "", nil)
+	_, err := thingRepo.Save(context.Background(), thing)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	overlapKey, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	expiredKey, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	err = thingRepo.UpdateKey(context.Background(), email, thing.ID, overlapKey, time.Hour)
+	assert.Nil(t, err, fmt.Sprintf("update key: got unexpected error: %s", err))
+
+	err = thingRepo.UpdateKey(context.Background(), email, thing.ID, expiredKey, 0)
+	assert.Nil(t, err, fmt.Sprintf("update key without overlap period: got unexpected error: %s", err))
+
+	err = thingRepo.UpdateKey(context.Background(), "wrong@example.com", thing.ID, "wrong-key", time.Hour)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("update key of non-existing thing: expected %s got %s", things.ErrNotFound, err))
+
+	cases := map[string]struct {
+		key string
+		id  string
+		err error
+	}{
+		"retrieve thing by current key": {
+			key: expiredKey,
+			id:  thing.ID,
+			err: nil,
+		},
+		"retrieve thing by previous key during overlap period": {
+			key: thing.Key,
+			id:  thing.ID,
+			err: nil,
+		},
+		"retrieve thing by previous key after overlap period": {
+			key: overlapKey,
+			id:  "",
+			err: things.ErrNotFound,
+		},
+	}
+
+	for desc, tc := range cases {
+		id, err := thingRepo.RetrieveByKey(context.Background(), tc.key)
+		assert.Equal(t, tc.id, id, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.id, id))
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
+
+func TestThingHashKeys(t *testing.T) {
+	thingRepo := mongodb.NewThingRepository(db)
+	email := "thing-hash-keys@example.com"
+	hasher := hmac.New("secret")
+
+	thing := newThing(t, email, "", nil)
+	_, err := thingRepo.Save(context.Background(), thing)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	newKey, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	err = thingRepo.UpdateKey(context.Background(), email, thing.ID, newKey, time.Hour)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	err = thingRepo.HashKeys(context.Background(), hasher)
+	assert.Nil(t, err, fmt.Sprintf("hash keys: got unexpected error: %s", err))
+
+	err = thingRepo.HashKeys(context.Background(), hasher)
+	assert.Nil(t, err, fmt.Sprintf("hash already hashed keys: got unexpected error: %s", err))
+
+	cases := map[string]struct {
+		key string
+		id  string
+		err error
+	}{
+		"retrieve thing by hashed key": {
+			key: hasher.Hash(newKey),
+			id:  thing.ID,
+			err: nil,
+		},
+		"retrieve thing by hashed previous key": {
+			key: hasher.Hash(thing.Key),
+			id:  thing.ID,
+			err: nil,
+		},
+		"retrieve thing by plain-text key": {
+			key: newKey,
+			id:  "",
+			err: things.ErrNotFound,
+		},
+		"retrieve thing by plain-text previous key": {
+			key: thing.Key,
+			id:  "",
+			err: things.ErrNotFound,
+		},
+	}
+
+	for desc, tc := range cases {
+		id, err := thingRepo.RetrieveByKey(context.Background(), tc.key)
+		assert.Equal(t, tc.id, id, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.id, id))
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
+
+func TestThingUpdate(t *testing.T) {
+	thingRepo := mongodb.NewThingRepository(db)
+	email := "thing-update@example.com"
+	thing := newThing(t, email, "", nil)
+	_, err := thingRepo.Save(context.Background(), thing)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	other := newThing(t, email, "", nil)
+	_, err = thingRepo.Save(context.Background(), other)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	thing.Name = "updated"
+	thing.Metadata = things.Metadata{"location": map[string]interface{}{"floor": float64(1)}}
+	err = thingRepo.Update(context.Background(), thing)
+	assert.Nil(t, err, fmt.Sprintf("update existing thing: expected no error got %s\n", err))
+
+	th, err := thingRepo.RetrieveByID(context.Background(), email, thing.ID)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.Equal(t, thing, th, fmt.Sprintf("update existing thing: expected %v got %v\n", thing, th))
+
+	err = thingRepo.Update(context.Background(), newThing(t, email, "", nil))
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("update non-existing thing: expected %s got %s\n", things.ErrNotFound, err))
+
+	err = thingRepo.UpdateKey(context.Background(), email, thing.ID, other.Key, 0)
+	assert.Equal(t, things.ErrConflict, err, fmt.Sprintf("update thing with conflicting key: expected %s got %s\n", things.ErrConflict, err))
+
+	err = thingRepo.UpdateKey(context.Background(), email, thing.ID, "new-key", 0)
+	assert.Nil(t, err, fmt.Sprintf("update thing key: expected no error got %s\n", err))
+
+	err = thingRepo.UpdateKey(context.Background(), email, thing.ID, "new-key", 0)
+	assert.Nil(t, err, fmt.Sprintf("update thing key to the current key: expected no error got %s\n", err))
+
+	id, err := thingRepo.RetrieveByKey(context.Background(), "new-key")
+	assert.Nil(t, err, fmt.Sprintf("retrieve thing by key: expected no error got %s\n", err))
+	assert.Equal(t, thing.ID, id, fmt.Sprintf("retrieve thing by key: expected %s got %s\n", thing.ID, id))
+}
+
+func TestThingRemoveAndRestore(t *testing.T) {
+	thingRepo := mongodb.NewThingRepository(db)
+	email := "thing-remove@example.com"
+	thing := newThing(t, email, "", nil)
+	_, err := thingRepo.Save(context.Background(), thing)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	err = thingRepo.Remove(context.Background(), email, thing.ID)
+	assert.Nil(t, err, fmt.Sprintf("remove thing: expected no error got %s\n", err))
+
+	_, err = thingRepo.RetrieveByID(context.Background(), email, thing.ID)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("retrieve removed thing: expected %s got %s\n", things.ErrNotFound, err))
+
+	_, err = thingRepo.RetrieveByKey(context.Background(), thing.Key)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("retrieve removed thing by key: expected %s got %s\n", things.ErrNotFound, err))
+
+	err = thingRepo.Restore(context.Background(), email, thing.ID)
+	assert.Nil(t, err, fmt.Sprintf("restore thing: expected no error got %s\n", err))
+
+	err = thingRepo.Restore(context.Background(), email, thing.ID)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("restore active thing: expected %s got %s\n", things.ErrNotFound, err))
+
+	err = thingRepo.Purge(context.Background(), email, thing.ID)
+	assert.Nil(t, err, fmt.Sprintf("purge thing: expected no error got %s\n", err))
+
+	_, err = thingRepo.RetrieveByID(context.Background(), email, thing.ID)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("retrieve purged thing: expected %s got %s\n", things.ErrNotFound, err))
+}
+
+func TestMultiThingRetrieval(t *testing.T) {
+	thingRepo := mongodb.NewThingRepository(db)
+	email := "thing-multi-retrieval@example.com"
+	name := "thing_name"
+	metadata := things.Metadata{"field": "value"}
+
+	n := uint64(10)
+	ids := []string{}
+	for i := uint64(0); i < n; i++ {
+		th := newThing(t, email, "", things.Metadata{
+			"field":    metadata["field"],
+			"level":    int(i),
+			"firmware": map[string]interface{}{"version": fmt.Sprintf("1.%d.0", i+2)},
+		})
+		// Create first two things with name.
+		if i < 2 {
+			th.Name = name
+		}
+
+		_, err := thingRepo.Save(context.Background(), th)
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+		ids = append(ids, th.ID)
+	}
+	sort.Strings(ids)
+
+	cases := map[string]struct {
+		owner    string
+		offset   uint64
+		limit    uint64
+		cursor   string
+		name     string
+		order    string
+		dir      string
+		first    string
+		size     uint64
+		total    uint64
+		metadata things.Metadata
+		query    []things.MetadataQuery
+	}{
+		"retrieve all things with existing owner": {
+			owner:  email,
+			offset: 0,
+			limit:  n,
+			first:  ids[0],
+			size:   n,
+			total:  n,
+		},
+		"retrieve subset of things with existing owner": {
+			owner:  email,
+			offset: n / 2,
+			limit:  n,
+			first:  ids[n/2],
+			size:   n / 2,
+			total:  n,
+		},
+		"retrieve things after cursor with existing owner": {
+			owner:  email,
+			offset: n,
+			limit:  n,
+			cursor: ids[n/2-1],
+			first:  ids[n/2],
+			size:   n / 2,
+			total:  n,
+		},
+		"retrieve things before cursor in descending order with existing owner": {
+			owner:  email,
+			offset: n,
+			limit:  n,
+			cursor: ids[n/2],
+			dir:    things.DescDir,
+			first:  ids[n/2-1],
+			size:   n / 2,
+			total:  n,
+		},
+		"retrieve things ordered by name with existing owner": {
+			owner:  email,
+			offset: 0,
+			limit:  n,
+			order:  things.OrderByName,
+			dir:    things.DescDir,
+			size:   n,
+			total:  n,
+		},
+		"retrieve things with non-existing owner": {
+			owner:  "wrong",
+			offset: 0,
+			limit:  n,
+			size:   0,
+			total:  0,
+		},
+		"retrieve things with existing name": {
+			owner:  email,
+			offset: 1,
+			limit:  n,
+			name:   "THING",
+			size:   1,
+			total:  2,
+		},
+		"retrieve things with non-existing name": {
+			owner:  email,
+			offset: 0,
+			limit:  n,
+			name:   "wrong",
+			size:   0,
+			total:  0,
+		},
+		"retrieve things with metadata": {
+			owner:    email,
+			offset:   0,
+			limit:    n,
+			size:     n,
+			total:    n,
+			metadata: metadata,
+		},
+		"retrieve things with non-existing metadata": {
+			owner:    email,
+			offset:   0,
+			limit:    n,
+			size:     0,
+			total:    0,
+			metadata: things.Metadata{"field": "wrong"},
+		},
+		"retrieve things with version below the query version": {
+			owner:  email,
+			offset: 0,
+			limit:  n,
+			size:   2,
+			total:  2,
+			query:  []things.MetadataQuery{{Path: []string{"firmware", "version"}, Operator: things.LessOp, Value: "1.4.0"}},
+		},
+		"retrieve things with number greater than or equal to the query number": {
+			owner:  email,
+			offset: 0,
+			limit:  n,
+			size:   n / 2,
+			total:  n / 2,
+			query:  []things.MetadataQuery{{Path: []string{"level"}, Operator: things.GreaterEqualOp, Value: "5"}},
+		},
+		"retrieve things with text not equal to the query text": {
+			owner:  email,
+			offset: 0,
+			limit:  n,
+			size:   0,
+			total:  0,
+			query:  []things.MetadataQuery{{Path: []string{"field"}, Operator: things.NotEqualOp, Value: "value"}},
+		},
+		"retrieve things matching multiple queries": {
+			owner:  email,
+			offset: 0,
+			limit:  n,
+			size:   3,
+			total:  3,
+			query: []things.MetadataQuery{
+				{Path: []string{"level"}, Operator: things.GreaterEqualOp, Value: "5"},
+				{Path: []string{"firmware", "version"}, Operator: things.LessOp, Value: "1.10.0"},
+			},
+		},
+	}
+
+	for desc, tc := range cases {
+		page, err := thingRepo.RetrieveAll(context.Background(), tc.owner, things.PageQuery{
+			Offset:   tc.offset,
+			Limit:    tc.limit,
+			Cursor:   tc.cursor,
+			Name:     tc.name,
+			Order:    tc.order,
+			Dir:      tc.dir,
+			Metadata: tc.metadata,
+			Query:    tc.query,
+		}, nil)
+		size := uint64(len(page.Things))
+		if tc.first != "" && size > 0 {
+			assert.Equal(t, tc.first, page.Things[0].ID, fmt.Sprintf("%s: expected first %s got %s\n", desc, tc.first, page.Things[0].ID))
+		}
+		assert.Equal(t, tc.size, size, fmt.Sprintf("%s: expected %d got %d\n", desc, tc.size, size))
+		assert.Equal(t, tc.total, page.Total, fmt.Sprintf("%s: expected %d got %d\n", desc, tc.total, page.Total))
+		assert.Nil(t, err, fmt.Sprintf("%s: expected no error got %s\n", desc, err))
+	}
+}
+
+func TestThingsSearch(t *testing.T) {
+	thingRepo := mongodb.NewThingRepository(db)
+	email := "thing-search@example.com"
+
+	sensor := newThing(t, email, "temperature sensor", things.Metadata{"room": "kitchen"})
+	actuator := newThing(t, email, "window actuator", things.Metadata{"room": "kitchen"})
+	for _, th := range []things.Thing{sensor, actuator} {
+		_, err := thingRepo.Save(context.Background(), th)
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	}
+
+	cases := map[string]struct {
+		query string
+		size  uint64
+	}{
+		"search things by name":              {query: "sensor", size: 1},
+		"search things by metadata":          {query: "kitchen", size: 2},
+		"search things by name and metadata": {query: "kitchen window", size: 1},
+		"search things with no match":        {query: "garage", size: 0},
+	}
+
+	for desc, tc := range cases {
+		page, err := thingRepo.Search(context.Background(), email, tc.query, 0, 10)
+		size := uint64(len(page.Things))
+		assert.Nil(t, err, fmt.Sprintf("%s: expected no error got %s\n", desc, err))
+		assert.Equal(t, tc.size, size, fmt.Sprintf("%s: expected %d got %d\n", desc, tc.size, size))
+		assert.Equal(t, tc.size, page.Total, fmt.Sprintf("%s: expected total %d got %d\n", desc, tc.size, page.Total))
+	}
+}
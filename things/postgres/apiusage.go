@@ -0,0 +1,113 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/mainflux/mainflux/things"
+)
+
+// usageBatch is the number of rows saved by a single statement, which keeps
+// the statements below the limit of the bound parameters.
+const usageBatch = 1000
+
+var _ things.APIUsageRepository = (*apiUsageRepository)(nil)
+
+type apiUsageRepository struct {
+	db Database
+}
+
+// NewAPIUsageRepository instantiates a PostgreSQL implementation of API
+// usage repository.
+func NewAPIUsageRepository(db Database) things.APIUsageRepository {
+	return &apiUsageRepository{
+		db: db,
+	}
+}
+
+func (ur apiUsageRepository) Save(ctx context.Context, usage ...things.APIUsage) error {
+	q := `INSERT INTO api_usage (owner, hour, method, calls, errors)
+	      VALUES (:owner, :hour, :method, :calls, :errors)
+	      ON CONFLICT (owner, hour, method) DO UPDATE SET
+	      calls = api_usage.calls + excluded.calls, errors = api_usage.errors + excluded.errors;`
+
+	for len(usage) > 0 {
+		n := len(usage)
+		if n > usageBatch {
+			n = usageBatch
+		}
+
+		dbus := make([]dbAPIUsage, 0, n)
+		for _, u := range usage[:n] {
+			dbus = append(dbus, toDBAPIUsage(u))
+		}
+
+		if _, err := ur.db.NamedExecContext(ctx, q, dbus); err != nil {
+			return err
+		}
+
+		usage = usage[n:]
+	}
+
+	return nil
+}
+
+func (ur apiUsageRepository) RetrieveByOwner(ctx context.Context, owner string, from, to time.Time) ([]things.APIUsage, error) {
+	q := `SELECT owner, hour, method, calls, errors FROM api_usage
+	      WHERE owner = :owner AND hour >= :from AND hour < :to ORDER BY hour, method;`
+
+	params := map[string]interface{}{
+		"owner": owner,
+		"from":  from.UTC(),
+		"to":    to.UTC(),
+	}
+
+	rows, err := ur.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	usage := []things.APIUsage{}
+	for rows.Next() {
+		var dbu dbAPIUsage
+		if err := rows.StructScan(&dbu); err != nil {
+			return nil, err
+		}
+
+		usage = append(usage, toAPIUsage(dbu))
+	}
+
+	return usage, rows.Err()
+}
+
+type dbAPIUsage struct {
+	Owner  string    `db:"owner"`
+	Hour   time.Time `db:"hour"`
+	Method string    `db:"method"`
+	Calls  int64     `db:"calls"`
+	Errors int64     `db:"errors"`
+}
+
+func toDBAPIUsage(u things.APIUsage) dbAPIUsage {
+	return dbAPIUsage{
+		Owner:  u.Owner,
+		Hour:   u.Hour.UTC(),
+		Method: u.Method,
+		Calls:  int64(u.Calls),
+		Errors: int64(u.Errors),
+	}
+}
+
+func toAPIUsage(dbu dbAPIUsage) things.APIUsage {
+	return things.APIUsage{
+		Owner:  dbu.Owner,
+		Hour:   dbu.Hour.UTC(),
+		Method: dbu.Method,
+		Calls:  uint64(dbu.Calls),
+		Errors: uint64(dbu.Errors),
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/things"
+	"github.com/mainflux/mainflux/things/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIUsageSave(t *testing.T) {
+	email := "api-usage@example.com"
+	dbMiddleware := postgres.NewDatabase(db)
+	usageRepo := postgres.NewAPIUsageRepository(dbMiddleware)
+
+	hour := time.Date(2020, time.January, 1, 10, 0, 0, 0, time.UTC)
+	saved := []things.APIUsage{
+		{Owner: email, Method: "view_thing", Hour: hour, Calls: 3, Errors: 0},
+		{Owner: email, Method: "add_thing", Hour: hour, Calls: 5, Errors: 1},
+		{Owner: email, Method: "add_thing", Hour: hour.Add(time.Hour), Calls: 2, Errors: 2},
+		{Owner: "other@example.com", Method: "add_thing", Hour: hour, Calls: 7, Errors: 0},
+	}
+	err := usageRepo.Save(context.Background(), saved...)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	// Saving the usage of the same owner, method and hour adds the counts.
+	err = usageRepo.Save(context.Background(), things.APIUsage{Owner: email, Method: "add_thing", Hour: hour, Calls: 1, Errors: 1})
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		from  time.Time
+		to    time.Time
+		usage []things.APIUsage
+	}{
+		{
+			desc: "retrieve usage of the day",
+			from: hour.Add(-10 * time.Hour),
+			to:   hour.Add(14 * time.Hour),
+			usage: []things.APIUsage{
+				{Owner: email, Method: "add_thing", Hour: hour, Calls: 6, Errors: 2},
+				saved[0],
+				saved[2],
+			},
+		},
+		{
+			desc: "retrieve usage of single hour",
+			from: hour.Add(time.Hour),
+			to:   hour.Add(2 * time.Hour),
+			usage: []things.APIUsage{
+				saved[2],
+			},
+		},
+		{
+			desc:  "retrieve usage of the hours without calls",
+			from:  hour.Add(-2 * time.Hour),
+			to:    hour,
+			usage: []things.APIUsage{},
+		},
+	}
+
+	for _, tc := range cases {
+		usage, err := usageRepo.RetrieveByOwner(context.Background(), email, tc.from, tc.to)
+		assert.Nil(t, err, fmt.Sprintf("%s: got unexpected error: %s", tc.desc, err))
+		assert.Equal(t, tc.usage, usage, fmt.Sprintf("%s: expected %v got %v", tc.desc, tc.usage, usage))
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/mainflux/mainflux/things"
+	"github.com/mainflux/mainflux/things/postgres"
+	"github.com/mainflux/mainflux/things/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// unpreparedDatabase executes the hot path queries without preparing them,
+// which is the baseline the prepared statements are compared against.
+type unpreparedDatabase struct {
+	postgres.Database
+}
+
+func (db unpreparedDatabase) PreparedQueryRowxContext(ctx context.Context, query string, args ...interface{}) *sqlx.Row {
+	return db.QueryRowxContext(ctx, query, args...)
+}
+
+func benchmarkDatabases() map[string]postgres.Database {
+	return map[string]postgres.Database{
+		"prepared":   postgres.NewDatabase(db),
+		"unprepared": unpreparedDatabase{postgres.NewDatabase(db)},
+	}
+}
+
+// saveConnection saves the thing connected to the channel and returns the
+// thing key and the channel ID.
+func saveConnection(b *testing.B, email string) (string, string, string) {
+	database := postgres.NewDatabase(db)
+	thingRepo := postgres.NewThingRepository(database)
+	chanRepo := postgres.NewChannelRepository(database)
+
+	thid, err := uuid.New().ID()
+	require.Nil(b, err, fmt.Sprintf("got unexpected error: %s", err))
+	thkey, err := uuid.New().ID()
+	require.Nil(b, err, fmt.Sprintf("got unexpected error: %s", err))
+	chid, err := uuid.New().ID()
+	require.Nil(b, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	thingID, err := thingRepo.Save(context.Background(), things.Thing{ID: thid, Owner: email, Key: thkey})
+	require.Nil(b, err, fmt.Sprintf("got unexpected error: %s", err))
+	chanID, err := chanRepo.Save(context.Background(), things.Channel{ID: chid, Owner: email})
+	require.Nil(b, err, fmt.Sprintf("got unexpected error: %s", err))
+	err = chanRepo.Connect(context.Background(), email, chanID, thingID, things.Actions)
+	require.Nil(b, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	return thkey, thingID, chanID
+}
+
+func BenchmarkRetrieveByKey(b *testing.B) {
+	key, _, _ := saveConnection(b, "bench-retrieve-by-key@example.com")
+
+	for name, database := range benchmarkDatabases() {
+		repo := postgres.NewThingRepository(database)
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := repo.RetrieveByKey(context.Background(), key); err != nil {
+					b.Fatalf("got unexpected error: %s", err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkHasThing(b *testing.B) {
+	key, _, chanID := saveConnection(b, "bench-has-thing@example.com")
+
+	for name, database := range benchmarkDatabases() {
+		repo := postgres.NewChannelRepository(database)
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := repo.HasThing(context.Background(), chanID, key, things.Publish); err != nil {
+					b.Fatalf("got unexpected error: %s", err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkHasThingByID(b *testing.B) {
+	_, thingID, chanID := saveConnection(b, "bench-has-thing-by-id@example.com")
+
+	for name, database := range benchmarkDatabases() {
+		repo := postgres.NewChannelRepository(database)
+		b.Run(name, func(b *testing.B) {
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					if err := repo.HasThingByID(context.Background(), chanID, thingID, things.Publish); err != nil {
+						b.Errorf("got unexpected error: %s", err)
+					}
+				}
+			})
+		})
+	}
+}
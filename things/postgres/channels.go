@@ -10,6 +10,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/gofrs/uuid"
 	"github.com/lib/pq"
@@ -31,8 +32,8 @@ func NewChannelRepository(db Database) things.ChannelRepository {
 }
 
 func (cr channelRepository) Save(ctx context.Context, channel things.Channel) (string, error) {
-	q := `INSERT INTO channels (id, owner, name, metadata)
-		VALUES (:id, :owner, :name, :metadata);`
+	q := `INSERT INTO channels (id, owner, name, metadata, retention_period, retention_messages, region, alias, ordered, type, peers)
+		VALUES (:id, :owner, :name, :metadata, :retention_period, :retention_messages, :region, :alias, :ordered, :type, :peers);`
 
 	dbch := toDBChannel(channel)
 
@@ -42,6 +43,8 @@ func (cr channelRepository) Save(ctx context.Context, channel things.Channel) (s
 			switch pqErr.Code.Name() {
 			case errInvalid, errTruncation:
 				return "", things.ErrMalformedEntity
+			case errDuplicate:
+				return "", things.ErrConflict
 			}
 		}
 
@@ -52,7 +55,9 @@ func (cr channelRepository) Save(ctx context.Context, channel things.Channel) (s
 }
 
 func (cr channelRepository) Update(ctx context.Context, channel things.Channel) error {
-	q := `UPDATE channels SET name = :name, metadata = :metadata WHERE owner = :owner AND id = :id;`
+	q := `UPDATE channels SET name = :name, metadata = :metadata, retention_period = :retention_period,
+	      retention_messages = :retention_messages, region = :region, alias = :alias, ordered = :ordered
+	      WHERE owner = :owner AND id = :id AND deleted_at IS NULL;`
 
 	dbch := toDBChannel(channel)
 
@@ -63,6 +68,8 @@ func (cr channelRepository) Update(ctx context.Context, channel things.Channel)
 			switch pqErr.Code.Name() {
 			case errInvalid, errTruncation:
 				return things.ErrMalformedEntity
+			case errDuplicate:
+				return things.ErrConflict
 			}
 		}
 
@@ -82,7 +89,8 @@ func (cr channelRepository) Update(ctx context.Context, channel things.Channel)
 }
 
 func (cr channelRepository) RetrieveByID(ctx context.Context, owner, id string) (things.Channel, error) {
-	q := `SELECT name, metadata FROM channels WHERE id = $1 AND owner = $2;`
+	q := `SELECT name, metadata, retention_period, retention_messages, region, alias, ordered, type, peers FROM channels
+	      WHERE id = $1 AND owner = $2 AND deleted_at IS NULL;`
 
 	dbch := dbChannel{
 		ID:    id,
@@ -100,25 +108,191 @@ func (cr channelRepository) RetrieveByID(ctx context.Context, owner, id string)
 	return toChannel(dbch), nil
 }
 
-func (cr channelRepository) RetrieveAll(ctx context.Context, owner string, offset, limit uint64, name string, metadata things.Metadata) (things.ChannelsPage, error) {
-	nq, name := getNameQuery(name)
-	m, mq, err := getMetadataQuery(metadata)
+func (cr channelRepository) RetrieveShared(ctx context.Context, id, email string, groups []string) (things.Channel, string, error) {
+	// Permissions are ordered so that the widest permission granted to the
+	// user or any of the groups is the smallest one.
+	q := `SELECT c.id, c.owner, c.name, c.metadata, c.retention_period, c.retention_messages, c.region, c.alias,
+	      c.ordered, c.type, c.peers, MIN(s.permission) AS permission FROM channels c
+	      INNER JOIN (
+	          SELECT channel_id, channel_owner, permission FROM channel_shares
+	          WHERE channel_id = :id AND group_id = ANY(CAST(:groups AS UUID[]))
+	          UNION ALL
+	          SELECT channel_id, channel_owner, permission FROM channel_user_shares
+	          WHERE channel_id = :id AND email = :email
+	      ) s ON s.channel_id = c.id AND s.channel_owner = c.owner
+	      WHERE c.id = :id AND c.deleted_at IS NULL
+	      GROUP BY c.id, c.owner;`
+
+	params := map[string]interface{}{
+		"id":     id,
+		"email":  email,
+		"groups": pq.StringArray(groups),
+	}
+
+	rows, err := cr.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && errInvalid == pqErr.Code.Name() {
+			return things.Channel{}, "", things.ErrNotFound
+		}
+		return things.Channel{}, "", err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return things.Channel{}, "", things.ErrNotFound
+	}
+
+	var dbch dbSharedChannel
+	if err := rows.StructScan(&dbch); err != nil {
+		return things.Channel{}, "", err
+	}
+
+	return toChannel(dbch.dbChannel), dbch.Permission, nil
+}
+
+func (cr channelRepository) RetrieveRetention(ctx context.Context, id string) (things.Retention, error) {
+	q := `SELECT retention_period, retention_messages FROM channels WHERE id = $1 AND deleted_at IS NULL;`
+
+	var dbch dbChannel
+	if err := cr.db.QueryRowxContext(ctx, q, id).StructScan(&dbch); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if err == sql.ErrNoRows || ok && errInvalid == pqErr.Code.Name() {
+			return things.Retention{}, things.ErrNotFound
+		}
+		return things.Retention{}, err
+	}
+
+	return toChannel(dbch).Retention, nil
+}
+
+func (cr channelRepository) RetrieveRegion(ctx context.Context, id string) (string, error) {
+	q := `SELECT region FROM channels WHERE id = $1 AND deleted_at IS NULL;`
+
+	var region string
+	if err := cr.db.QueryRowxContext(ctx, q, id).Scan(&region); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if err == sql.ErrNoRows || ok && errInvalid == pqErr.Code.Name() {
+			return "", things.ErrNotFound
+		}
+		return "", err
+	}
+
+	return region, nil
+}
+
+func (cr channelRepository) RetrieveAlias(ctx context.Context, id string) (string, error) {
+	q := `SELECT alias FROM channels WHERE id = $1 AND deleted_at IS NULL;`
+
+	var alias string
+	if err := cr.db.QueryRowxContext(ctx, q, id).Scan(&alias); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if err == sql.ErrNoRows || ok && errInvalid == pqErr.Code.Name() {
+			return "", things.ErrNotFound
+		}
+		return "", err
+	}
+
+	return alias, nil
+}
+
+func (cr channelRepository) RetrieveOrdering(ctx context.Context, id string) (bool, error) {
+	q := `SELECT ordered FROM channels WHERE id = $1 AND deleted_at IS NULL;`
+
+	var ordered bool
+	if err := cr.db.QueryRowxContext(ctx, q, id).Scan(&ordered); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if err == sql.ErrNoRows || ok && errInvalid == pqErr.Code.Name() {
+			return false, things.ErrNotFound
+		}
+		return false, err
+	}
+
+	return ordered, nil
+}
+
+func (cr channelRepository) RetrieveMetadata(ctx context.Context, id string) (things.Metadata, error) {
+	q := `SELECT metadata FROM channels WHERE id = $1 AND deleted_at IS NULL;`
+
+	var md dbMetadata
+	if err := cr.db.QueryRowxContext(ctx, q, id).Scan(&md); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if err == sql.ErrNoRows || ok && errInvalid == pqErr.Code.Name() {
+			return nil, things.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return things.Metadata(md), nil
+}
+
+func (cr channelRepository) RetrieveByAlias(ctx context.Context, alias string) (string, error) {
+	q := `SELECT id FROM channels WHERE alias = $1 AND alias <> '' AND deleted_at IS NULL;`
+
+	var id string
+	if err := cr.db.QueryRowxContext(ctx, q, alias).Scan(&id); err != nil {
+		if err == sql.ErrNoRows {
+			return "", things.ErrNotFound
+		}
+		return "", err
+	}
+
+	return id, nil
+}
+
+func (cr channelRepository) RetrieveDirect(ctx context.Context, owner string, peers []string) (things.Channel, error) {
+	q := `SELECT id, owner, name, metadata, retention_period, retention_messages, region, alias, ordered, type, peers FROM channels
+	      WHERE owner = $1 AND type = $2 AND peers = $3 AND deleted_at IS NULL;`
+
+	var dbch dbChannel
+	if err := cr.db.QueryRowxContext(ctx, q, owner, things.DirectChannel, pq.StringArray(peers)).StructScan(&dbch); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if err == sql.ErrNoRows || ok && errInvalid == pqErr.Code.Name() {
+			return things.Channel{}, things.ErrNotFound
+		}
+		return things.Channel{}, err
+	}
+
+	return toChannel(dbch), nil
+}
+
+func (cr channelRepository) RetrieveAll(ctx context.Context, owner string, pageQuery things.PageQuery, groups []string) (things.ChannelsPage, error) {
+	nq, name := getNameQuery(pageQuery.Name)
+	m, mq, err := getMetadataQuery(pageQuery.Metadata)
 	if err != nil {
 		return things.ChannelsPage{}, err
 	}
+	cmpq, cmpp, err := getComparisonQuery(pageQuery.Query)
+	if err != nil {
+		return things.ChannelsPage{}, err
+	}
+	dq := getDeletedQuery(pageQuery.Deleted)
+	kq, offset := getCursorQuery(pageQuery.Cursor, pageQuery.Dir, pageQuery.Offset)
+	oq := getOrderQuery(pageQuery.Order, pageQuery.Dir)
+	sq := getOwnerQuery("channel", pageQuery.Shared)
 
-	q := fmt.Sprintf(`SELECT id, name, metadata FROM channels
-	      WHERE owner = :owner %s%s ORDER BY id LIMIT :limit OFFSET :offset;`, mq, nq)
+	q := fmt.Sprintf(`SELECT id, owner, name, metadata, retention_period, retention_messages, region, alias, ordered, type, peers, deleted_at FROM channels
+	      WHERE %s%s%s%s%s%s %s LIMIT :limit OFFSET :offset;`, sq, mq, cmpq, nq, dq, kq, oq)
 
 	params := map[string]interface{}{
 		"owner":    owner,
-		"limit":    limit,
+		"limit":    pageQuery.Limit,
 		"offset":   offset,
+		"cursor":   pageQuery.Cursor,
 		"name":     name,
 		"metadata": m,
+		"groups":   pq.StringArray(groups),
 	}
+	for k, v := range cmpp {
+		params[k] = v
+	}
+
 	rows, err := cr.db.NamedQueryContext(ctx, q, params)
 	if err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && pqErr.Code.Name() == errInvalid {
+			return things.ChannelsPage{}, things.ErrMalformedEntity
+		}
 		return things.ChannelsPage{}, err
 	}
 	defer rows.Close()
@@ -134,35 +308,70 @@ func (cr channelRepository) RetrieveAll(ctx context.Context, owner string, offse
 		items = append(items, ch)
 	}
 
-	cq := ""
-	if name != "" {
-		cq = `AND LOWER(name) LIKE $2`
+	q = fmt.Sprintf(`SELECT COUNT(*) FROM channels WHERE %s%s%s;`, sq, nq, dq)
+
+	total, err := getTotal(ctx, cr.db, q, params)
+	if err != nil {
+		return things.ChannelsPage{}, err
+	}
+
+	page := things.ChannelsPage{
+		Channels: items,
+		PageMetadata: things.PageMetadata{
+			Total:  total,
+			Offset: offset,
+			Limit:  pageQuery.Limit,
+		},
 	}
 
-	q = fmt.Sprintf(`SELECT COUNT(*) FROM channels WHERE owner = $1 %s;`, cq)
+	return page, nil
+}
 
-	total := uint64(0)
-	switch name {
-	case "":
-		if err := cr.db.GetContext(ctx, &total, q, owner); err != nil {
-			return things.ChannelsPage{}, err
-		}
-	default:
-		if err := cr.db.GetContext(ctx, &total, q, owner, name); err != nil {
+func (cr channelRepository) Search(ctx context.Context, owner, query string, offset, limit uint64) (things.ChannelsPage, error) {
+	q := fmt.Sprintf(`SELECT id, name, metadata FROM channels
+	      WHERE owner = :owner AND deleted_at IS NULL AND %s @@ plainto_tsquery('simple', :query)
+	      ORDER BY ts_rank(%s, plainto_tsquery('simple', :query)) DESC, id
+	      LIMIT :limit OFFSET :offset;`, searchVector, searchVector)
+
+	params := map[string]interface{}{
+		"owner":  owner,
+		"query":  query,
+		"limit":  limit,
+		"offset": offset,
+	}
+
+	rows, err := cr.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return things.ChannelsPage{}, err
+	}
+	defer rows.Close()
+
+	items := []things.Channel{}
+	for rows.Next() {
+		dbch := dbChannel{Owner: owner}
+		if err := rows.StructScan(&dbch); err != nil {
 			return things.ChannelsPage{}, err
 		}
+
+		items = append(items, toChannel(dbch))
 	}
 
-	page := things.ChannelsPage{
+	q = fmt.Sprintf(`SELECT COUNT(*) FROM channels
+	     WHERE owner = $1 AND deleted_at IS NULL AND %s @@ plainto_tsquery('simple', $2);`, searchVector)
+
+	var total uint64
+	if err := cr.db.GetContext(ctx, &total, q, owner, query); err != nil {
+		return things.ChannelsPage{}, err
+	}
+
+	return things.ChannelsPage{
 		Channels: items,
 		PageMetadata: things.PageMetadata{
 			Total:  total,
 			Offset: offset,
 			Limit:  limit,
 		},
-	}
-
-	return page, nil
+	}, nil
 }
 
 func (cr channelRepository) RetrieveByThing(ctx context.Context, owner, thing string, offset, limit uint64) (things.ChannelsPage, error) {
@@ -175,7 +384,7 @@ func (cr channelRepository) RetrieveByThing(ctx context.Context, owner, thing st
 	      FROM channels ch
 	      INNER JOIN connections co
 		  ON ch.id = co.channel_id
-		  WHERE ch.owner = :owner AND co.thing_id = :thing
+		  WHERE ch.owner = :owner AND co.thing_id = :thing AND ch.deleted_at IS NULL
 		  ORDER BY ch.id
 		  LIMIT :limit
 		  OFFSET :offset`
@@ -208,7 +417,7 @@ func (cr channelRepository) RetrieveByThing(ctx context.Context, owner, thing st
 	     FROM channels ch
 	     INNER JOIN connections co
 	     ON ch.id = co.channel_id
-	     WHERE ch.owner = $1 AND co.thing_id = $2`
+	     WHERE ch.owner = $1 AND co.thing_id = $2 AND ch.deleted_at IS NULL`
 
 	var total uint64
 	if err := cr.db.GetContext(ctx, &total, q, owner, thing); err != nil {
@@ -225,7 +434,103 @@ func (cr channelRepository) RetrieveByThing(ctx context.Context, owner, thing st
 	}, nil
 }
 
+func (cr channelRepository) Export(ctx context.Context, owner string, fn func(things.Channel) error) error {
+	q := `SELECT id, owner, name, metadata, retention_period, retention_messages, region, alias, ordered, type, peers FROM channels
+	      WHERE owner = :owner AND deleted_at IS NULL ORDER BY id;`
+
+	rows, err := cr.db.NamedQueryContext(ctx, q, map[string]interface{}{"owner": owner})
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var dbch dbChannel
+		if err := rows.StructScan(&dbch); err != nil {
+			return err
+		}
+
+		if err := fn(toChannel(dbch)); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+func (cr channelRepository) ExportConnections(ctx context.Context, owner string, fn func(things.Connection) error) error {
+	q := `SELECT co.channel_id AS channel, co.thing_id AS thing, co.actions
+	      FROM connections co
+	      INNER JOIN channels ch ON ch.id = co.channel_id AND ch.owner = co.channel_owner
+	      INNER JOIN things th ON th.id = co.thing_id AND th.owner = co.thing_owner
+	      WHERE co.channel_owner = :owner AND ch.deleted_at IS NULL AND th.deleted_at IS NULL
+	      ORDER BY co.channel_id, co.thing_id;`
+
+	rows, err := cr.db.NamedQueryContext(ctx, q, map[string]interface{}{"owner": owner})
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var dbco dbConnection
+		if err := rows.StructScan(&dbco); err != nil {
+			return err
+		}
+
+		conn := things.Connection{
+			ChannelID: dbco.Channel,
+			ThingID:   dbco.Thing,
+			Actions:   []string(dbco.Actions),
+		}
+		if err := fn(conn); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
 func (cr channelRepository) Remove(ctx context.Context, owner, id string) error {
+	dbch := dbChannel{
+		ID:    id,
+		Owner: owner,
+	}
+	q := `UPDATE channels SET deleted_at = NOW() WHERE id = :id AND owner = :owner AND deleted_at IS NULL`
+	cr.db.NamedExecContext(ctx, q, dbch)
+	return nil
+}
+
+func (cr channelRepository) Restore(ctx context.Context, owner, id string) error {
+	q := `UPDATE channels SET deleted_at = NULL WHERE id = :id AND owner = :owner AND deleted_at IS NOT NULL`
+
+	dbch := dbChannel{
+		ID:    id,
+		Owner: owner,
+	}
+
+	res, err := cr.db.NamedExecContext(ctx, q, dbch)
+	if err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && pqErr.Code.Name() == errInvalid {
+			return things.ErrNotFound
+		}
+		return err
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if cnt == 0 {
+		return things.ErrNotFound
+	}
+
+	return nil
+}
+
+func (cr channelRepository) Purge(ctx context.Context, owner, id string) error {
 	dbch := dbChannel{
 		ID:    id,
 		Owner: owner,
@@ -235,14 +540,158 @@ func (cr channelRepository) Remove(ctx context.Context, owner, id string) error
 	return nil
 }
 
-func (cr channelRepository) Connect(ctx context.Context, owner, chanID, thingID string) error {
-	q := `INSERT INTO connections (channel_id, channel_owner, thing_id, thing_owner)
-	      VALUES (:channel, :owner, :thing, :owner);`
+func (cr channelRepository) Share(ctx context.Context, owner, id, group, permission string) error {
+	q := `INSERT INTO channel_shares (channel_id, channel_owner, group_id, permission)
+	      SELECT id, owner, CAST(:group AS UUID), :permission FROM channels
+	      WHERE id = :id AND owner = :owner AND deleted_at IS NULL
+	      ON CONFLICT (channel_id, channel_owner, group_id) DO UPDATE SET permission = excluded.permission;`
+
+	params := map[string]interface{}{
+		"id":         id,
+		"owner":      owner,
+		"group":      group,
+		"permission": permission,
+	}
+
+	res, err := cr.db.NamedExecContext(ctx, q, params)
+	if err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && errInvalid == pqErr.Code.Name() {
+			return things.ErrNotFound
+		}
+		return err
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if cnt == 0 {
+		return things.ErrNotFound
+	}
+
+	return nil
+}
+
+func (cr channelRepository) Unshare(ctx context.Context, owner, id, group string) error {
+	q := `DELETE FROM channel_shares WHERE channel_id = :id AND channel_owner = :owner AND group_id = :group;`
+
+	params := map[string]interface{}{
+		"id":    id,
+		"owner": owner,
+		"group": group,
+	}
+
+	if _, err := cr.db.NamedExecContext(ctx, q, params); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && errInvalid == pqErr.Code.Name() {
+			return nil
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (cr channelRepository) ShareWithUser(ctx context.Context, owner, id, email, permission string) error {
+	q := `INSERT INTO channel_user_shares (channel_id, channel_owner, email, permission)
+	      SELECT id, owner, :email, :permission FROM channels
+	      WHERE id = :id AND owner = :owner AND deleted_at IS NULL
+	      ON CONFLICT (channel_id, channel_owner, email) DO UPDATE SET permission = excluded.permission;`
+
+	params := map[string]interface{}{
+		"id":         id,
+		"owner":      owner,
+		"email":      email,
+		"permission": permission,
+	}
+
+	res, err := cr.db.NamedExecContext(ctx, q, params)
+	if err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && errInvalid == pqErr.Code.Name() {
+			return things.ErrNotFound
+		}
+		return err
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if cnt == 0 {
+		return things.ErrNotFound
+	}
+
+	return nil
+}
+
+func (cr channelRepository) UnshareWithUser(ctx context.Context, owner, id, email string) error {
+	q := `DELETE FROM channel_user_shares WHERE channel_id = :id AND channel_owner = :owner AND email = :email;`
+
+	params := map[string]interface{}{
+		"id":    id,
+		"owner": owner,
+		"email": email,
+	}
+
+	if _, err := cr.db.NamedExecContext(ctx, q, params); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && errInvalid == pqErr.Code.Name() {
+			return nil
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (cr channelRepository) Transfer(ctx context.Context, from, to string) error {
+	// Connections and shares reference the owners of the things and the
+	// channels, so they're transferred by the cascading updates.
+	qs := []string{
+		`UPDATE things SET owner = :to WHERE owner = :from;`,
+		`UPDATE channels SET owner = :to WHERE owner = :from;`,
+	}
+
+	params := map[string]interface{}{
+		"from": from,
+		"to":   to,
+	}
+
+	tx, err := cr.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, q := range qs {
+		if _, err := tx.NamedExecContext(ctx, q, params); err != nil {
+			tx.Rollback()
+			pqErr, ok := err.(*pq.Error)
+			if ok && errDuplicate == pqErr.Code.Name() {
+				return things.ErrConflict
+			}
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (cr channelRepository) Connect(ctx context.Context, owner, chanID, thingID string, actions []string) error {
+	// connect is idempotent, connecting again only replaces allowed actions
+	q := `INSERT INTO connections (channel_id, channel_owner, thing_id, thing_owner, actions)
+	      VALUES (:channel, :owner, :thing, :owner, :actions)
+	      ON CONFLICT (channel_id, channel_owner, thing_id, thing_owner)
+	      DO UPDATE SET actions = EXCLUDED.actions;`
 
 	conn := dbConnection{
 		Channel: chanID,
 		Thing:   thingID,
 		Owner:   owner,
+		Actions: pq.StringArray(actions),
 	}
 
 	if _, err := cr.db.NamedExecContext(ctx, q, conn); err != nil {
@@ -252,11 +701,6 @@ func (cr channelRepository) Connect(ctx context.Context, owner, chanID, thingID
 			return things.ErrNotFound
 		}
 
-		// connect is idempotent
-		if ok && errDuplicate == pqErr.Code.Name() {
-			return nil
-		}
-
 		return err
 	}
 
@@ -291,29 +735,30 @@ func (cr channelRepository) Disconnect(ctx context.Context, owner, chanID, thing
 	return nil
 }
 
-func (cr channelRepository) HasThing(ctx context.Context, chanID, key string) (string, error) {
-	var thingID string
-	q := `SELECT id FROM things WHERE key = $1`
-	if err := cr.db.QueryRowxContext(ctx, q, key).Scan(&thingID); err != nil {
+func (cr channelRepository) HasThing(ctx context.Context, chanID, key, action string) (string, error) {
+	thingID, err := retrieveIDByKey(ctx, cr.db, key)
+	if err != nil {
 		return "", err
-
 	}
 
-	if err := cr.hasThing(ctx, chanID, thingID); err != nil {
+	if err := cr.hasThing(ctx, chanID, thingID, action); err != nil {
 		return "", err
 	}
 
 	return thingID, nil
 }
 
-func (cr channelRepository) HasThingByID(ctx context.Context, chanID, thingID string) error {
-	return cr.hasThing(ctx, chanID, thingID)
+func (cr channelRepository) HasThingByID(ctx context.Context, chanID, thingID, action string) error {
+	return cr.hasThing(ctx, chanID, thingID, action)
 }
 
-func (cr channelRepository) hasThing(ctx context.Context, chanID, thingID string) error {
-	q := `SELECT EXISTS (SELECT 1 FROM connections WHERE channel_id = $1 AND thing_id = $2);`
+func (cr channelRepository) hasThing(ctx context.Context, chanID, thingID, action string) error {
+	q := `SELECT EXISTS (SELECT 1 FROM connections co
+	      INNER JOIN channels ch ON ch.id = co.channel_id AND ch.owner = co.channel_owner
+	      WHERE co.channel_id = $1 AND co.thing_id = $2 AND $3 = ANY(co.actions)
+	      AND ch.deleted_at IS NULL);`
 	exists := false
-	if err := cr.db.QueryRowxContext(ctx, q, chanID, thingID).Scan(&exists); err != nil {
+	if err := cr.db.PreparedQueryRowxContext(ctx, q, chanID, thingID, action).Scan(&exists); err != nil {
 		return err
 	}
 
@@ -362,27 +807,62 @@ func (m dbMetadata) Value() (driver.Value, error) {
 }
 
 type dbChannel struct {
-	ID       string     `db:"id"`
-	Owner    string     `db:"owner"`
-	Name     string     `db:"name"`
-	Metadata dbMetadata `db:"metadata"`
+	ID                string         `db:"id"`
+	Owner             string         `db:"owner"`
+	Name              string         `db:"name"`
+	Metadata          dbMetadata     `db:"metadata"`
+	RetentionPeriod   int64          `db:"retention_period"`
+	RetentionMessages int64          `db:"retention_messages"`
+	Region            string         `db:"region"`
+	Alias             string         `db:"alias"`
+	Ordered           bool           `db:"ordered"`
+	Type              string         `db:"type"`
+	Peers             pq.StringArray `db:"peers"`
+	DeletedAt         pq.NullTime    `db:"deleted_at"`
+}
+
+type dbSharedChannel struct {
+	dbChannel
+	Permission string `db:"permission"`
 }
 
 func toDBChannel(ch things.Channel) dbChannel {
 	return dbChannel{
-		ID:       ch.ID,
-		Owner:    ch.Owner,
-		Name:     ch.Name,
-		Metadata: ch.Metadata,
+		ID:                ch.ID,
+		Owner:             ch.Owner,
+		Name:              ch.Name,
+		Metadata:          ch.Metadata,
+		RetentionPeriod:   int64(ch.Retention.Period / time.Second),
+		RetentionMessages: int64(ch.Retention.Messages),
+		Region:            ch.Region,
+		Alias:             ch.Alias,
+		Ordered:           ch.Ordered,
+		Type:              ch.Type,
+		Peers:             pq.StringArray(ch.Peers),
 	}
 }
 
 func toChannel(ch dbChannel) things.Channel {
+	var deletedAt time.Time
+	if ch.DeletedAt.Valid {
+		deletedAt = ch.DeletedAt.Time
+	}
+
 	return things.Channel{
 		ID:       ch.ID,
 		Owner:    ch.Owner,
 		Name:     ch.Name,
 		Metadata: ch.Metadata,
+		Retention: things.Retention{
+			Period:   time.Duration(ch.RetentionPeriod) * time.Second,
+			Messages: uint64(ch.RetentionMessages),
+		},
+		Region:    ch.Region,
+		Alias:     ch.Alias,
+		Ordered:   ch.Ordered,
+		Type:      ch.Type,
+		Peers:     []string(ch.Peers),
+		DeletedAt: deletedAt,
 	}
 }
 
@@ -396,6 +876,77 @@ func getNameQuery(name string) (string, string) {
 	return nq, name
 }
 
+// searchVector must match the expression of the full-text search indexes
+// in order for them to be used.
+const searchVector = `to_tsvector('simple', COALESCE(name, '') || ' ' || COALESCE(metadata::text, ''))`
+
+// getCursorQuery returns keyset condition for the provided cursor. Since
+// the cursor already marks the page start, offset is reset when set.
+func getCursorQuery(cursor, dir string, offset uint64) (string, uint64) {
+	if cursor == "" {
+		return "", offset
+	}
+	if dir == things.DescDir {
+		return ` AND id < :cursor`, 0
+	}
+	return ` AND id > :cursor`, 0
+}
+
+// getOrderQuery returns ORDER BY clause for the provided order and direction.
+// ID is used as a tie-breaker to keep pagination stable for non-unique
+// columns. Unknown values fall back to ascending order by ID, since they are
+// interpolated into the query.
+func getOrderQuery(order, dir string) string {
+	d := "ASC"
+	if dir == things.DescDir {
+		d = "DESC"
+	}
+
+	switch order {
+	case things.OrderByName, things.OrderByCreatedAt:
+		return fmt.Sprintf(`ORDER BY %s %s, id %s`, order, d, d)
+	default:
+		return fmt.Sprintf(`ORDER BY id %s`, d)
+	}
+}
+
+// getOwnerQuery returns condition matching the entities owned by the user
+// and, if shared entities are requested, the entities shared with the user
+// or with any of the provided groups.
+func getOwnerQuery(entity string, shared bool) string {
+	if !shared {
+		return `owner = :owner`
+	}
+	return fmt.Sprintf(`(owner = :owner OR (id, owner) IN
+	      (SELECT %[1]s_id, %[1]s_owner FROM %[1]s_shares WHERE group_id = ANY(CAST(:groups AS UUID[]))
+	       UNION SELECT %[1]s_id, %[1]s_owner FROM %[1]s_user_shares WHERE email = :owner))`, entity)
+}
+
+// getTotal returns the result of the named count query.
+func getTotal(ctx context.Context, db Database, query string, params interface{}) (uint64, error) {
+	rows, err := db.NamedQueryContext(ctx, query, params)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	total := uint64(0)
+	if rows.Next() {
+		if err := rows.Scan(&total); err != nil {
+			return 0, err
+		}
+	}
+
+	return total, nil
+}
+
+func getDeletedQuery(deleted bool) string {
+	if deleted {
+		return ""
+	}
+	return ` AND deleted_at IS NULL`
+}
+
 func getMetadataQuery(m things.Metadata) ([]byte, string, error) {
 	mq := ""
 	mb := []byte("{}")
@@ -411,8 +962,56 @@ func getMetadataQuery(m things.Metadata) ([]byte, string, error) {
 	return mb, mq, nil
 }
 
+var comparisons = map[string]string{
+	things.EqualOp:        "=",
+	things.NotEqualOp:     "<>",
+	things.LessOp:         "<",
+	things.LessEqualOp:    "<=",
+	things.GreaterOp:      ">",
+	things.GreaterEqualOp: ">=",
+}
+
+// getComparisonQuery returns conditions and their parameters for the
+// provided metadata queries. Values of a different type than the query value
+// never match, so that non-numeric values don't break the casts.
+func getComparisonQuery(query []things.MetadataQuery) (string, map[string]interface{}, error) {
+	cq := ""
+	params := map[string]interface{}{}
+	for i, q := range query {
+		op, ok := comparisons[q.Operator]
+		if !ok || len(q.Path) == 0 {
+			return "", nil, things.ErrMalformedEntity
+		}
+
+		path := fmt.Sprintf("mqpath%d", i)
+		val := fmt.Sprintf("mqval%d", i)
+		params[path] = pq.StringArray(q.Path)
+
+		if n, ok := q.Number(); ok {
+			cq += fmt.Sprintf(` AND CASE WHEN jsonb_typeof(metadata #> :%s) = 'number'
+			       THEN CAST(metadata #>> :%s AS NUMERIC) %s :%s ELSE FALSE END`, path, path, op, val)
+			params[val] = n
+			continue
+		}
+
+		if v, ok := q.Version(); ok {
+			// Versions are compared part by part, as arrays of numbers.
+			cq += fmt.Sprintf(` AND CASE WHEN metadata #>> :%s ~ '^[0-9]+(\.[0-9]+)*$'
+			       THEN CAST(string_to_array(metadata #>> :%s, '.') AS BIGINT[]) %s :%s ELSE FALSE END`, path, path, op, val)
+			params[val] = pq.Int64Array(v)
+			continue
+		}
+
+		cq += fmt.Sprintf(` AND jsonb_typeof(metadata #> :%s) = 'string' AND metadata #>> :%s %s :%s`, path, path, op, val)
+		params[val] = q.Value
+	}
+
+	return cq, params, nil
+}
+
 type dbConnection struct {
-	Channel string `db:"channel"`
-	Thing   string `db:"thing"`
-	Owner   string `db:"owner"`
+	Channel string         `db:"channel"`
+	Thing   string         `db:"thing"`
+	Owner   string         `db:"owner"`
+	Actions pq.StringArray `db:"actions"`
 }
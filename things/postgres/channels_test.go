@@ -6,7 +6,9 @@ package postgres_test
 import (
 	"context"
 	"fmt"
+	"sort"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -151,7 +153,7 @@ func TestSingleChannelRetrieval(t *testing.T) {
 	}
 
 	c.ID, _ = chanRepo.Save(context.Background(), c)
-	chanRepo.Connect(context.Background(), email, c.ID, th.ID)
+	chanRepo.Connect(context.Background(), email, c.ID, th.ID, things.Actions)
 
 	nonexistentChanID, err := uuid.New().ID()
 	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
@@ -189,6 +191,266 @@ func TestSingleChannelRetrieval(t *testing.T) {
 	}
 }
 
+func TestChannelRetrieveRetention(t *testing.T) {
+	email := "channel-retention@example.com"
+	chanRepo := postgres.NewChannelRepository(postgres.NewDatabase(db))
+
+	chid, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	c := things.Channel{
+		ID:        chid,
+		Owner:     email,
+		Retention: things.Retention{Period: time.Hour, Messages: 100},
+	}
+	_, err = chanRepo.Save(context.Background(), c)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	nonexistentChanID, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cases := map[string]struct {
+		ID        string
+		retention things.Retention
+		err       error
+	}{
+		"retrieve retention of existing channel": {
+			ID:        c.ID,
+			retention: c.Retention,
+			err:       nil,
+		},
+		"retrieve retention of non-existing channel": {
+			ID:        nonexistentChanID,
+			retention: things.Retention{},
+			err:       things.ErrNotFound,
+		},
+		"retrieve retention of channel with malformed ID": {
+			ID:        wrongValue,
+			retention: things.Retention{},
+			err:       things.ErrNotFound,
+		},
+	}
+
+	for desc, tc := range cases {
+		r, err := chanRepo.RetrieveRetention(context.Background(), tc.ID)
+		assert.Equal(t, tc.retention, r, fmt.Sprintf("%s: expected %v got %v\n", desc, tc.retention, r))
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
+
+func TestChannelRetrieveRegion(t *testing.T) {
+	email := "channel-region@example.com"
+	chanRepo := postgres.NewChannelRepository(postgres.NewDatabase(db))
+
+	chid, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	c := things.Channel{
+		ID:     chid,
+		Owner:  email,
+		Region: "eu-west-1",
+	}
+	_, err = chanRepo.Save(context.Background(), c)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	nonexistentChanID, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cases := map[string]struct {
+		ID     string
+		region string
+		err    error
+	}{
+		"retrieve region of existing channel": {
+			ID:     c.ID,
+			region: c.Region,
+			err:    nil,
+		},
+		"retrieve region of non-existing channel": {
+			ID:     nonexistentChanID,
+			region: "",
+			err:    things.ErrNotFound,
+		},
+		"retrieve region of channel with malformed ID": {
+			ID:     wrongValue,
+			region: "",
+			err:    things.ErrNotFound,
+		},
+	}
+
+	for desc, tc := range cases {
+		r, err := chanRepo.RetrieveRegion(context.Background(), tc.ID)
+		assert.Equal(t, tc.region, r, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.region, r))
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
+
+func TestChannelRetrieveOrdering(t *testing.T) {
+	email := "channel-ordering@example.com"
+	chanRepo := postgres.NewChannelRepository(postgres.NewDatabase(db))
+
+	chid, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	c := things.Channel{
+		ID:      chid,
+		Owner:   email,
+		Ordered: true,
+	}
+	_, err = chanRepo.Save(context.Background(), c)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	nonexistentChanID, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cases := map[string]struct {
+		ID      string
+		ordered bool
+		err     error
+	}{
+		"retrieve ordering of existing channel": {
+			ID:      c.ID,
+			ordered: c.Ordered,
+			err:     nil,
+		},
+		"retrieve ordering of non-existing channel": {
+			ID:      nonexistentChanID,
+			ordered: false,
+			err:     things.ErrNotFound,
+		},
+		"retrieve ordering of channel with malformed ID": {
+			ID:      wrongValue,
+			ordered: false,
+			err:     things.ErrNotFound,
+		},
+	}
+
+	for desc, tc := range cases {
+		o, err := chanRepo.RetrieveOrdering(context.Background(), tc.ID)
+		assert.Equal(t, tc.ordered, o, fmt.Sprintf("%s: expected %t got %t\n", desc, tc.ordered, o))
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
+
+func TestChannelAliases(t *testing.T) {
+	email := "channel-alias@example.com"
+	chanRepo := postgres.NewChannelRepository(postgres.NewDatabase(db))
+
+	chid, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	c := things.Channel{
+		ID:    chid,
+		Owner: email,
+		Alias: "temp",
+	}
+	_, err = chanRepo.Save(context.Background(), c)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	dupID, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	_, err = chanRepo.Save(context.Background(), things.Channel{ID: dupID, Owner: email, Alias: c.Alias})
+	assert.Equal(t, things.ErrConflict, err, fmt.Sprintf("save channel with existing alias: expected %s got %s\n", things.ErrConflict, err))
+
+	a, err := chanRepo.RetrieveAlias(context.Background(), c.ID)
+	assert.Nil(t, err, fmt.Sprintf("retrieve alias: expected no error got %s\n", err))
+	assert.Equal(t, c.Alias, a, fmt.Sprintf("retrieve alias: expected %s got %s\n", c.Alias, a))
+
+	cases := map[string]struct {
+		alias string
+		id    string
+		err   error
+	}{
+		"retrieve channel by existing alias": {
+			alias: c.Alias,
+			id:    c.ID,
+			err:   nil,
+		},
+		"retrieve channel by non-existing alias": {
+			alias: "humidity",
+			id:    "",
+			err:   things.ErrNotFound,
+		},
+		"retrieve channel by empty alias": {
+			alias: "",
+			id:    "",
+			err:   things.ErrNotFound,
+		},
+	}
+
+	for desc, tc := range cases {
+		id, err := chanRepo.RetrieveByAlias(context.Background(), tc.alias)
+		assert.Equal(t, tc.id, id, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.id, id))
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
+
+func TestChannelRetrieveDirect(t *testing.T) {
+	email := "channel-direct@example.com"
+	chanRepo := postgres.NewChannelRepository(postgres.NewDatabase(db))
+
+	var peers []string
+	for i := 0; i < 2; i++ {
+		id, err := uuid.New().ID()
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+		peers = append(peers, id)
+	}
+	sort.Strings(peers)
+
+	chid, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	c := things.Channel{
+		ID:    chid,
+		Owner: email,
+		Type:  things.DirectChannel,
+		Peers: peers,
+	}
+	_, err = chanRepo.Save(context.Background(), c)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	dupID, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	_, err = chanRepo.Save(context.Background(), things.Channel{ID: dupID, Owner: email, Type: things.DirectChannel, Peers: peers})
+	assert.Equal(t, things.ErrConflict, err, fmt.Sprintf("save direct channel of paired things: expected %s got %s\n", things.ErrConflict, err))
+
+	cases := map[string]struct {
+		owner string
+		peers []string
+		id    string
+		err   error
+	}{
+		"retrieve direct channel of paired things": {
+			owner: email,
+			peers: peers,
+			id:    c.ID,
+			err:   nil,
+		},
+		"retrieve direct channel of things in wrong order": {
+			owner: email,
+			peers: []string{peers[1], peers[0]},
+			id:    "",
+			err:   things.ErrNotFound,
+		},
+		"retrieve direct channel of other owner": {
+			owner: wrongValue,
+			peers: peers,
+			id:    "",
+			err:   things.ErrNotFound,
+		},
+		"retrieve direct channel of malformed peers": {
+			owner: email,
+			peers: []string{wrongValue, wrongValue},
+			id:    "",
+			err:   things.ErrNotFound,
+		},
+	}
+
+	for desc, tc := range cases {
+		ch, err := chanRepo.RetrieveDirect(context.Background(), tc.owner, tc.peers)
+		assert.Equal(t, tc.id, ch.ID, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.id, ch.ID))
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+		if err == nil {
+			assert.Equal(t, tc.peers, ch.Peers, fmt.Sprintf("%s: expected peers %v got %v\n", desc, tc.peers, ch.Peers))
+		}
+	}
+}
+
 func TestMultiChannelRetrieval(t *testing.T) {
 	email := "channel-multi-retrieval@example.com"
 	dbMiddleware := postgres.NewDatabase(db)
@@ -200,6 +462,7 @@ func TestMultiChannelRetrieval(t *testing.T) {
 	wrongMeta["wrong"] = "wrong"
 
 	n := uint64(10)
+	ids := []string{}
 	for i := uint64(0); i < n; i++ {
 		chid, err := uuid.New().ID()
 		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
@@ -220,17 +483,71 @@ func TestMultiChannelRetrieval(t *testing.T) {
 		}
 
 		chanRepo.Save(context.Background(), c)
+		ids = append(ids, chid)
 	}
+	sort.Strings(ids)
 
 	cases := map[string]struct {
 		owner    string
 		offset   uint64
 		limit    uint64
+		cursor   string
+		order    string
+		dir      string
+		first    string
 		name     string
 		size     uint64
 		total    uint64
 		metadata things.Metadata
+		query    []things.MetadataQuery
 	}{
+		"retrieve channels in descending order with existing owner": {
+			owner:  email,
+			offset: 0,
+			limit:  n,
+			order:  things.OrderByID,
+			dir:    things.DescDir,
+			first:  ids[n-1],
+			size:   n,
+			total:  n,
+		},
+		"retrieve channels before cursor in descending order with existing owner": {
+			owner:  email,
+			offset: n,
+			limit:  n,
+			cursor: ids[n/2],
+			order:  things.OrderByID,
+			dir:    things.DescDir,
+			first:  ids[n/2-1],
+			size:   n / 2,
+			total:  n,
+		},
+		"retrieve channels ordered by name with existing owner": {
+			owner:  email,
+			offset: 0,
+			limit:  n,
+			order:  things.OrderByName,
+			dir:    things.AscDir,
+			size:   n,
+			total:  n,
+		},
+		"retrieve channels ordered by creation time with existing owner": {
+			owner:  email,
+			offset: 0,
+			limit:  n,
+			order:  things.OrderByCreatedAt,
+			dir:    things.DescDir,
+			size:   n,
+			total:  n,
+		},
+		"retrieve channels after cursor with existing owner": {
+			owner:  email,
+			offset: n,
+			limit:  n,
+			cursor: ids[n/2-1],
+			size:   n / 2,
+			total:  n,
+		},
 		"retrieve all channels with existing owner": {
 			owner:  email,
 			offset: 0,
@@ -284,11 +601,39 @@ func TestMultiChannelRetrieval(t *testing.T) {
 			total:    n,
 			metadata: wrongMeta,
 		},
+		"retrieve all channels with text equal to the query text": {
+			owner:  email,
+			offset: 0,
+			limit:  n,
+			size:   2,
+			total:  n,
+			query:  []things.MetadataQuery{{Path: []string{"name"}, Operator: things.EqualOp, Value: "test-channel"}},
+		},
+		"retrieve all channels with text greater than the query text": {
+			owner:  email,
+			offset: 0,
+			limit:  n,
+			size:   0,
+			total:  n,
+			query:  []things.MetadataQuery{{Path: []string{"name"}, Operator: things.GreaterOp, Value: "test-channel"}},
+		},
 	}
 
 	for desc, tc := range cases {
-		page, err := chanRepo.RetrieveAll(context.Background(), tc.owner, tc.offset, tc.limit, tc.name, tc.metadata)
+		page, err := chanRepo.RetrieveAll(context.Background(), tc.owner, things.PageQuery{
+			Offset:   tc.offset,
+			Limit:    tc.limit,
+			Cursor:   tc.cursor,
+			Name:     tc.name,
+			Order:    tc.order,
+			Dir:      tc.dir,
+			Metadata: tc.metadata,
+			Query:    tc.query,
+		}, nil)
 		size := uint64(len(page.Channels))
+		if tc.first != "" && size > 0 {
+			assert.Equal(t, tc.first, page.Channels[0].ID, fmt.Sprintf("%s: expected first %s got %s\n", desc, tc.first, page.Channels[0].ID))
+		}
 		assert.Equal(t, tc.size, size, fmt.Sprintf("%s: expected %d got %d\n", desc, tc.size, size))
 		assert.Equal(t, tc.total, page.Total, fmt.Sprintf("%s: expected %d got %d\n", desc, tc.total, page.Total))
 		assert.Nil(t, err, fmt.Sprintf("%s: expected no error got %d\n", desc, err))
@@ -320,7 +665,7 @@ func TestMultiChannelRetrievalByThing(t *testing.T) {
 		}
 		cid, err := chanRepo.Save(context.Background(), c)
 		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
-		err = chanRepo.Connect(context.Background(), email, cid, tid)
+		err = chanRepo.Connect(context.Background(), email, cid, tid, things.Actions)
 		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 	}
 
@@ -404,6 +749,143 @@ func TestChannelRemoval(t *testing.T) {
 	}
 }
 
+func TestChannelExport(t *testing.T) {
+	email := "channel-export@example.com"
+	dbMiddleware := postgres.NewDatabase(db)
+	chanRepo := postgres.NewChannelRepository(dbMiddleware)
+	thingRepo := postgres.NewThingRepository(dbMiddleware)
+
+	thid, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	thkey, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	thingID, err := thingRepo.Save(context.Background(), things.Thing{
+		ID:    thid,
+		Owner: email,
+		Key:   thkey,
+	})
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	n := 3
+	ids := []string{}
+	for i := 0; i < n; i++ {
+		chid, err := uuid.New().ID()
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+		id, err := chanRepo.Save(context.Background(), things.Channel{
+			ID:    chid,
+			Owner: email,
+		})
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+		ids = append(ids, id)
+
+		err = chanRepo.Connect(context.Background(), email, id, thingID, []string{things.Publish})
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	}
+
+	err = chanRepo.Remove(context.Background(), email, ids[0])
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	exported := []things.Channel{}
+	err = chanRepo.Export(context.Background(), email, func(ch things.Channel) error {
+		exported = append(exported, ch)
+		return nil
+	})
+	assert.Nil(t, err, fmt.Sprintf("export channels: got unexpected error: %s", err))
+	assert.Equal(t, n-1, len(exported), fmt.Sprintf("export channels: expected %d got %d", n-1, len(exported)))
+
+	conns := []things.Connection{}
+	err = chanRepo.ExportConnections(context.Background(), email, func(conn things.Connection) error {
+		conns = append(conns, conn)
+		return nil
+	})
+	assert.Nil(t, err, fmt.Sprintf("export connections: got unexpected error: %s", err))
+	assert.Equal(t, n-1, len(conns), fmt.Sprintf("export connections: expected %d got %d", n-1, len(conns)))
+	for _, conn := range conns {
+		assert.NotEqual(t, ids[0], conn.ChannelID, "export connections: expected connections of removed channel to be skipped")
+		assert.Equal(t, thingID, conn.ThingID, fmt.Sprintf("export connections: expected thing %s got %s", thingID, conn.ThingID))
+		assert.Equal(t, []string{things.Publish}, conn.Actions, fmt.Sprintf("export connections: expected actions %v got %v", []string{things.Publish}, conn.Actions))
+	}
+}
+
+func TestChannelSharing(t *testing.T) {
+	email := "channel-sharing@example.com"
+	dbMiddleware := postgres.NewDatabase(db)
+	chanRepo := postgres.NewChannelRepository(dbMiddleware)
+
+	chid, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	group, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	chanID, err := chanRepo.Save(context.Background(), things.Channel{
+		ID:    chid,
+		Owner: email,
+	})
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	err = chanRepo.Share(context.Background(), "wrong@example.com", chanID, group, things.EditPermission)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("share not owned channel: expected %s got %s", things.ErrNotFound, err))
+
+	err = chanRepo.Share(context.Background(), email, chanID, group, things.EditPermission)
+	assert.Nil(t, err, fmt.Sprintf("share channel: got unexpected error: %s", err))
+
+	ch, permission, err := chanRepo.RetrieveShared(context.Background(), chanID, "member@example.com", []string{group})
+	assert.Nil(t, err, fmt.Sprintf("retrieve shared channel: got unexpected error: %s", err))
+	assert.Equal(t, email, ch.Owner, fmt.Sprintf("retrieve shared channel: expected owner %s got %s", email, ch.Owner))
+	assert.Equal(t, things.EditPermission, permission, fmt.Sprintf("retrieve shared channel: expected permission %s got %s", things.EditPermission, permission))
+
+	page, err := chanRepo.RetrieveAll(context.Background(), "member@example.com", things.PageQuery{Limit: 10, Shared: true}, []string{group})
+	assert.Nil(t, err, fmt.Sprintf("retrieve shared channels: got unexpected error: %s", err))
+	assert.Equal(t, uint64(1), page.Total, fmt.Sprintf("retrieve shared channels: expected total %d got %d", 1, page.Total))
+
+	err = chanRepo.Unshare(context.Background(), email, chanID, group)
+	assert.Nil(t, err, fmt.Sprintf("unshare channel: got unexpected error: %s", err))
+
+	_, _, err = chanRepo.RetrieveShared(context.Background(), chanID, "member@example.com", []string{group})
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("retrieve unshared channel: expected %s got %s", things.ErrNotFound, err))
+}
+
+func TestChannelUserSharing(t *testing.T) {
+	email := "channel-user-sharing@example.com"
+	member := "channel-member@example.com"
+	dbMiddleware := postgres.NewDatabase(db)
+	chanRepo := postgres.NewChannelRepository(dbMiddleware)
+
+	chid, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	chanID, err := chanRepo.Save(context.Background(), things.Channel{
+		ID:    chid,
+		Owner: email,
+	})
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	err = chanRepo.ShareWithUser(context.Background(), "wrong@example.com", chanID, member, things.ViewPermission)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("share not owned channel: expected %s got %s", things.ErrNotFound, err))
+
+	err = chanRepo.ShareWithUser(context.Background(), email, chanID, member, things.ViewPermission)
+	assert.Nil(t, err, fmt.Sprintf("share channel with user: got unexpected error: %s", err))
+
+	ch, permission, err := chanRepo.RetrieveShared(context.Background(), chanID, member, nil)
+	assert.Nil(t, err, fmt.Sprintf("retrieve channel shared with user: got unexpected error: %s", err))
+	assert.Equal(t, email, ch.Owner, fmt.Sprintf("retrieve channel shared with user: expected owner %s got %s", email, ch.Owner))
+	assert.Equal(t, things.ViewPermission, permission, fmt.Sprintf("retrieve channel shared with user: expected permission %s got %s", things.ViewPermission, permission))
+
+	page, err := chanRepo.RetrieveAll(context.Background(), member, things.PageQuery{Limit: 10, Shared: true}, nil)
+	assert.Nil(t, err, fmt.Sprintf("retrieve channels shared with user: got unexpected error: %s", err))
+	assert.Equal(t, uint64(1), page.Total, fmt.Sprintf("retrieve channels shared with user: expected total %d got %d", 1, page.Total))
+
+	page, err = chanRepo.RetrieveAll(context.Background(), member, things.PageQuery{Limit: 10}, nil)
+	assert.Nil(t, err, fmt.Sprintf("retrieve owned channels: got unexpected error: %s", err))
+	assert.Equal(t, uint64(0), page.Total, fmt.Sprintf("retrieve owned channels: expected total %d got %d", 0, page.Total))
+
+	err = chanRepo.UnshareWithUser(context.Background(), email, chanID, member)
+	assert.Nil(t, err, fmt.Sprintf("unshare channel with user: got unexpected error: %s", err))
+
+	_, _, err = chanRepo.RetrieveShared(context.Background(), chanID, member, nil)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("retrieve channel unshared with user: expected %s got %s", things.ErrNotFound, err))
+}
+
 func TestConnect(t *testing.T) {
 	email := "channel-connect@example.com"
 	dbMiddleware := postgres.NewDatabase(db)
@@ -482,7 +964,7 @@ func TestConnect(t *testing.T) {
 	}
 
 	for _, tc := range cases {
-		err := chanRepo.Connect(context.Background(), tc.owner, tc.chanID, tc.thingID)
+		err := chanRepo.Connect(context.Background(), tc.owner, tc.chanID, tc.thingID, things.Actions)
 		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
 	}
 }
@@ -512,7 +994,7 @@ func TestDisconnect(t *testing.T) {
 		ID:    chid,
 		Owner: email,
 	})
-	chanRepo.Connect(context.Background(), email, chanID, thingID)
+	chanRepo.Connect(context.Background(), email, chanID, thingID, things.Actions)
 
 	nonexistentThingID, err := uuid.New().ID()
 	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
@@ -594,7 +1076,7 @@ func TestHasThing(t *testing.T) {
 		ID:    chid,
 		Owner: email,
 	})
-	chanRepo.Connect(context.Background(), email, chanID, thingID)
+	chanRepo.Connect(context.Background(), email, chanID, thingID, things.Actions)
 
 	nonexistentChanID, err := uuid.New().ID()
 	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
@@ -622,7 +1104,7 @@ func TestHasThing(t *testing.T) {
 	}
 
 	for desc, tc := range cases {
-		_, err := chanRepo.HasThing(context.Background(), tc.chanID, tc.key)
+		_, err := chanRepo.HasThing(context.Background(), tc.chanID, tc.key, things.Publish)
 		hasAccess := err == nil
 		assert.Equal(t, tc.hasAccess, hasAccess, fmt.Sprintf("%s: expected %t got %t\n", desc, tc.hasAccess, hasAccess))
 	}
@@ -664,7 +1146,7 @@ func TestHasThingByID(t *testing.T) {
 		ID:    chid,
 		Owner: email,
 	})
-	chanRepo.Connect(context.Background(), email, chanID, thingID)
+	chanRepo.Connect(context.Background(), email, chanID, thingID, things.Actions)
 
 	nonexistentChanID, err := uuid.New().ID()
 	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
@@ -697,8 +1179,55 @@ func TestHasThingByID(t *testing.T) {
 	}
 
 	for desc, tc := range cases {
-		err := chanRepo.HasThingByID(context.Background(), tc.chanID, tc.thingID)
+		err := chanRepo.HasThingByID(context.Background(), tc.chanID, tc.thingID, things.Publish)
 		hasAccess := err == nil
 		assert.Equal(t, tc.hasAccess, hasAccess, fmt.Sprintf("%s: expected %t got %t\n", desc, tc.hasAccess, hasAccess))
 	}
 }
+
+func TestTransfer(t *testing.T) {
+	email := "transfer-from@example.com"
+	newOwner := "transfer-to@example.com"
+	dbMiddleware := postgres.NewDatabase(db)
+	thingRepo := postgres.NewThingRepository(dbMiddleware)
+	chanRepo := postgres.NewChannelRepository(dbMiddleware)
+
+	thid, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	thkey, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	thingID, err := thingRepo.Save(context.Background(), things.Thing{
+		ID:       thid,
+		Owner:    email,
+		Key:      thkey,
+		Metadata: things.Metadata{},
+	})
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	chid, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	chanID, err := chanRepo.Save(context.Background(), things.Channel{
+		ID:    chid,
+		Owner: email,
+	})
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	err = chanRepo.Connect(context.Background(), email, chanID, thingID, things.Actions)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	err = chanRepo.Transfer(context.Background(), email, newOwner)
+	assert.Nil(t, err, fmt.Sprintf("transfer things and channels: got unexpected error: %s", err))
+
+	_, err = thingRepo.RetrieveByID(context.Background(), email, thingID)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("retrieve thing as previous owner: expected %s got %s", things.ErrNotFound, err))
+	_, err = thingRepo.RetrieveByID(context.Background(), newOwner, thingID)
+	assert.Nil(t, err, fmt.Sprintf("retrieve thing as new owner: got unexpected error: %s", err))
+	_, err = chanRepo.RetrieveByID(context.Background(), newOwner, chanID)
+	assert.Nil(t, err, fmt.Sprintf("retrieve channel as new owner: got unexpected error: %s", err))
+
+	err = chanRepo.HasThingByID(context.Background(), chanID, thingID, things.Publish)
+	assert.Nil(t, err, fmt.Sprintf("check transferred connection: got unexpected error: %s", err))
+
+	err = chanRepo.Transfer(context.Background(), email, newOwner)
+	assert.Nil(t, err, fmt.Sprintf("transfer owner without channels: got unexpected error: %s", err))
+}
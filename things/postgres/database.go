@@ -3,15 +3,20 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"strings"
+	"sync"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/mainflux/mainflux/things"
 	"github.com/opentracing/opentracing-go"
 )
 
 var _ Database = (*database)(nil)
 
 type database struct {
-	db *sqlx.DB
+	db      *sqlx.DB
+	replica *sqlx.DB
+	stmts   *statements
 }
 
 // Database provides a database interface
@@ -20,12 +25,31 @@ type Database interface {
 	QueryRowxContext(context.Context, string, ...interface{}) *sqlx.Row
 	NamedQueryContext(context.Context, string, interface{}) (*sqlx.Rows, error)
 	GetContext(context.Context, interface{}, string, ...interface{}) error
+	BeginTxx(context.Context, *sql.TxOptions) (*sqlx.Tx, error)
+
+	// PreparedQueryRowxContext executes the query using the prepared
+	// statement. The statement is prepared on the first execution of the
+	// query and reused afterwards, so it's meant for the static queries on
+	// the hot path only.
+	PreparedQueryRowxContext(context.Context, string, ...interface{}) *sqlx.Row
 }
 
 // NewDatabase creates a ThingDatabase instance
 func NewDatabase(db *sqlx.DB) Database {
 	return &database{
-		db: db,
+		db:    db,
+		stmts: newStatements(),
+	}
+}
+
+// NewReplicatedDatabase creates a ThingDatabase instance that executes the
+// read-only queries against the read replica if the context permits replica
+// reads. All the other queries are executed against the primary database.
+func NewReplicatedDatabase(db, replica *sqlx.DB) Database {
+	return &database{
+		db:      db,
+		replica: replica,
+		stmts:   newStatements(),
 	}
 }
 
@@ -36,17 +60,49 @@ func (dm database) NamedExecContext(ctx context.Context, query string, args inte
 
 func (dm database) QueryRowxContext(ctx context.Context, query string, args ...interface{}) *sqlx.Row {
 	addSpanTags(ctx, query)
-	return dm.db.QueryRowxContext(ctx, query, args...)
+	return dm.reader(ctx, query).QueryRowxContext(ctx, query, args...)
 }
 
 func (dm database) NamedQueryContext(ctx context.Context, query string, args interface{}) (*sqlx.Rows, error) {
 	addSpanTags(ctx, query)
-	return dm.db.NamedQueryContext(ctx, query, args)
+	return dm.reader(ctx, query).NamedQueryContext(ctx, query, args)
 }
 
 func (dm database) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
 	addSpanTags(ctx, query)
-	return dm.db.GetContext(ctx, dest, query, args...)
+	return dm.reader(ctx, query).GetContext(ctx, dest, query, args...)
+}
+
+func (dm database) BeginTxx(ctx context.Context, opts *sql.TxOptions) (*sqlx.Tx, error) {
+	return dm.db.BeginTxx(ctx, opts)
+}
+
+func (dm database) PreparedQueryRowxContext(ctx context.Context, query string, args ...interface{}) *sqlx.Row {
+	addSpanTags(ctx, query)
+	db := dm.reader(ctx, query)
+
+	stmt, err := dm.stmts.prepare(ctx, db, query)
+	if err != nil {
+		// Failure to prepare the statement is reported by the query itself.
+		return db.QueryRowxContext(ctx, query, args...)
+	}
+
+	return stmt.QueryRowxContext(ctx, args...)
+}
+
+// reader returns the database the query should be executed against. Queries
+// modifying data, including the ones locking rows, require the primary.
+func (dm database) reader(ctx context.Context, query string) *sqlx.DB {
+	if dm.replica == nil || !things.ReplicaReads(ctx) {
+		return dm.db
+	}
+
+	q := strings.ToUpper(strings.TrimSpace(query))
+	if !strings.HasPrefix(q, "SELECT") || strings.Contains(q, "FOR UPDATE") {
+		return dm.db
+	}
+
+	return dm.replica
 }
 
 func addSpanTags(ctx context.Context, query string) {
@@ -58,3 +114,52 @@ func addSpanTags(ctx context.Context, query string) {
 		span.SetTag("db.type", "sql")
 	}
 }
+
+type stmtKey struct {
+	db    *sqlx.DB
+	query string
+}
+
+// statements keeps the prepared statements of both the primary and the
+// replica database. Statements are prepared on each connection they are
+// executed on by the database/sql package.
+type statements struct {
+	mu    sync.RWMutex
+	stmts map[stmtKey]*sqlx.Stmt
+}
+
+func newStatements() *statements {
+	return &statements{
+		stmts: make(map[stmtKey]*sqlx.Stmt),
+	}
+}
+
+func (s *statements) prepare(ctx context.Context, db *sqlx.DB, query string) (*sqlx.Stmt, error) {
+	key := stmtKey{db: db, query: query}
+
+	s.mu.RLock()
+	stmt, ok := s.stmts[key]
+	s.mu.RUnlock()
+	if ok {
+		return stmt, nil
+	}
+
+	// The statement is prepared without holding the lock, so that the slow
+	// preparation doesn't block the callers using the prepared statements.
+	stmt, err := db.PreparexContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// The statement prepared concurrently by another caller is kept.
+	if prepared, ok := s.stmts[key]; ok {
+		stmt.Close()
+		return prepared, nil
+	}
+	s.stmts[key] = stmt
+
+	return stmt, nil
+}
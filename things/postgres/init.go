@@ -5,6 +5,7 @@ package postgres
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq" // required for SQL access
@@ -22,15 +23,20 @@ type Config struct {
 	SSLCert     string
 	SSLKey      string
 	SSLRootCert string
+
+	// Connection pool settings, applied as by the database/sql package.
+	// Negative values leave the settings unset, keeping their defaults.
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
 }
 
 // Connect creates a connection to the PostgreSQL instance and applies any
 // unapplied database migrations. A non-nil error is returned to indicate
 // failure.
 func Connect(cfg Config) (*sqlx.DB, error) {
-	url := fmt.Sprintf("host=%s port=%s user=%s dbname=%s password=%s sslmode=%s sslcert=%s sslkey=%s sslrootcert=%s", cfg.Host, cfg.Port, cfg.User, cfg.Name, cfg.Pass, cfg.SSLMode, cfg.SSLCert, cfg.SSLKey, cfg.SSLRootCert)
-
-	db, err := sqlx.Open("postgres", url)
+	db, err := open(cfg)
 	if err != nil {
 		return nil, err
 	}
@@ -42,11 +48,47 @@ func Connect(cfg Config) (*sqlx.DB, error) {
 	return db, nil
 }
 
+// ConnectReplica creates a connection to the read replica of the PostgreSQL
+// instance. Migrations aren't applied, since they are replicated from the
+// primary instance.
+func ConnectReplica(cfg Config) (*sqlx.DB, error) {
+	return open(cfg)
+}
+
+func open(cfg Config) (*sqlx.DB, error) {
+	url := fmt.Sprintf("host=%s port=%s user=%s dbname=%s password=%s sslmode=%s sslcert=%s sslkey=%s sslrootcert=%s", cfg.Host, cfg.Port, cfg.User, cfg.Name, cfg.Pass, cfg.SSLMode, cfg.SSLCert, cfg.SSLKey, cfg.SSLRootCert)
+	db, err := sqlx.Open("postgres", url)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.MaxOpenConns >= 0 {
+		db.SetMaxOpenConns(cfg.MaxOpenConns)
+	}
+	if cfg.MaxIdleConns >= 0 {
+		db.SetMaxIdleConns(cfg.MaxIdleConns)
+	}
+	if cfg.ConnMaxLifetime >= 0 {
+		db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	}
+	if cfg.ConnMaxIdleTime >= 0 {
+		db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+	}
+
+	return db, nil
+}
+
 func migrateDB(db *sqlx.DB) error {
+	if err := padMigrationIDs(db); err != nil {
+		return err
+	}
+
+	// Migration IDs are zero padded, since the migrations are applied in
+	// the order of their IDs compared as strings.
 	migrations := &migrate.MemoryMigrationSource{
 		Migrations: []*migrate.Migration{
 			{
-				Id: "things_1",
+				Id: "things_01",
 				Up: []string{
 					`CREATE TABLE IF NOT EXISTS things (
 						id       UUID,
@@ -80,7 +122,7 @@ func migrateDB(db *sqlx.DB) error {
 				},
 			},
 			{
-				Id: "things_2",
+				Id: "things_02",
 				Up: []string{
 					`ALTER TABLE IF EXISTS things ALTER COLUMN
 					 metadata TYPE JSONB using metadata::text::jsonb
@@ -88,16 +130,277 @@ func migrateDB(db *sqlx.DB) error {
 				},
 			},
 			{
-				Id: "things_3",
+				Id: "things_03",
 				Up: []string{
 					`ALTER TABLE IF EXISTS channels ALTER COLUMN
 					 metadata TYPE JSONB using metadata::text::jsonb
 					`,
 				},
 			},
+			{
+				Id: "things_04",
+				Up: []string{
+					`ALTER TABLE IF EXISTS things ADD COLUMN deleted_at TIMESTAMP`,
+					`ALTER TABLE IF EXISTS channels ADD COLUMN deleted_at TIMESTAMP`,
+				},
+				Down: []string{
+					`ALTER TABLE IF EXISTS things DROP COLUMN deleted_at`,
+					`ALTER TABLE IF EXISTS channels DROP COLUMN deleted_at`,
+				},
+			},
+			{
+				Id: "things_05",
+				Up: []string{
+					`CREATE INDEX IF NOT EXISTS things_search_idx ON things
+					 USING GIN (to_tsvector('simple', COALESCE(name, '') || ' ' || COALESCE(metadata::text, '')))`,
+					`CREATE INDEX IF NOT EXISTS channels_search_idx ON channels
+					 USING GIN (to_tsvector('simple', COALESCE(name, '') || ' ' || COALESCE(metadata::text, '')))`,
+				},
+				Down: []string{
+					`DROP INDEX IF EXISTS things_search_idx`,
+					`DROP INDEX IF EXISTS channels_search_idx`,
+				},
+			},
+			{
+				Id: "things_06",
+				Up: []string{
+					`ALTER TABLE IF EXISTS connections ADD COLUMN
+					 actions TEXT[] NOT NULL DEFAULT '{publish,subscribe,read_history}'`,
+				},
+				Down: []string{
+					`ALTER TABLE IF EXISTS connections DROP COLUMN actions`,
+				},
+			},
+			{
+				Id: "things_07",
+				Up: []string{
+					`ALTER TABLE IF EXISTS things ADD COLUMN
+					 created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP`,
+					`ALTER TABLE IF EXISTS channels ADD COLUMN
+					 created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP`,
+				},
+				Down: []string{
+					`ALTER TABLE IF EXISTS things DROP COLUMN created_at`,
+					`ALTER TABLE IF EXISTS channels DROP COLUMN created_at`,
+				},
+			},
+			{
+				Id: "things_08",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS thing_shares (
+						thing_id    UUID,
+						thing_owner VARCHAR(254),
+						group_id    UUID,
+						permission  VARCHAR(16) NOT NULL,
+						FOREIGN KEY (thing_id, thing_owner) REFERENCES things (id, owner) ON DELETE CASCADE ON UPDATE CASCADE,
+						PRIMARY KEY (thing_id, thing_owner, group_id)
+					)`,
+					`CREATE TABLE IF NOT EXISTS channel_shares (
+						channel_id    UUID,
+						channel_owner VARCHAR(254),
+						group_id      UUID,
+						permission    VARCHAR(16) NOT NULL,
+						FOREIGN KEY (channel_id, channel_owner) REFERENCES channels (id, owner) ON DELETE CASCADE ON UPDATE CASCADE,
+						PRIMARY KEY (channel_id, channel_owner, group_id)
+					)`,
+					`CREATE INDEX IF NOT EXISTS thing_shares_group_idx ON thing_shares (group_id)`,
+					`CREATE INDEX IF NOT EXISTS channel_shares_group_idx ON channel_shares (group_id)`,
+				},
+				Down: []string{
+					`DROP TABLE IF EXISTS thing_shares`,
+					`DROP TABLE IF EXISTS channel_shares`,
+				},
+			},
+			{
+				Id: "things_09",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS quotas (
+						owner       VARCHAR(254) PRIMARY KEY,
+						things      BIGINT NOT NULL DEFAULT 0 CHECK (things >= 0),
+						channels    BIGINT NOT NULL DEFAULT 0 CHECK (channels >= 0),
+						connections BIGINT NOT NULL DEFAULT 0 CHECK (connections >= 0)
+					)`,
+				},
+				Down: []string{
+					`DROP TABLE IF EXISTS quotas`,
+				},
+			},
+			{
+				Id: "things_10",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS metadata_schemas (
+						owner      VARCHAR(254),
+						entity     VARCHAR(32),
+						definition JSONB NOT NULL,
+						PRIMARY KEY (owner, entity)
+					)`,
+				},
+				Down: []string{
+					`DROP TABLE IF EXISTS metadata_schemas`,
+				},
+			},
+			{
+				Id: "things_11",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS thing_keys (
+						key         VARCHAR(4096) PRIMARY KEY,
+						thing_id    UUID NOT NULL,
+						thing_owner VARCHAR(254) NOT NULL,
+						expires_at  TIMESTAMP NOT NULL,
+						FOREIGN KEY (thing_id, thing_owner) REFERENCES things (id, owner) ON DELETE CASCADE ON UPDATE CASCADE
+					)`,
+				},
+				Down: []string{
+					`DROP TABLE IF EXISTS thing_keys`,
+				},
+			},
+			{
+				Id: "things_12",
+				Up: []string{
+					`ALTER TABLE IF EXISTS channels ADD COLUMN IF NOT EXISTS retention_period BIGINT NOT NULL DEFAULT 0`,
+					`ALTER TABLE IF EXISTS channels ADD COLUMN IF NOT EXISTS retention_messages BIGINT NOT NULL DEFAULT 0`,
+				},
+				Down: []string{
+					`ALTER TABLE IF EXISTS channels DROP COLUMN IF EXISTS retention_period`,
+					`ALTER TABLE IF EXISTS channels DROP COLUMN IF EXISTS retention_messages`,
+				},
+			},
+			{
+				Id: "things_13",
+				Up: []string{
+					`ALTER TABLE IF EXISTS channels ADD COLUMN IF NOT EXISTS region VARCHAR(64) NOT NULL DEFAULT ''`,
+				},
+				Down: []string{
+					`ALTER TABLE IF EXISTS channels DROP COLUMN IF EXISTS region`,
+				},
+			},
+			{
+				Id: "things_14",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS api_usage (
+						owner  VARCHAR(254),
+						hour   TIMESTAMP,
+						method VARCHAR(64),
+						calls  BIGINT NOT NULL DEFAULT 0,
+						errors BIGINT NOT NULL DEFAULT 0,
+						PRIMARY KEY (owner, hour, method)
+					)`,
+				},
+				Down: []string{
+					`DROP TABLE IF EXISTS api_usage`,
+				},
+			},
+			{
+				Id: "things_15",
+				Up: []string{
+					`ALTER TABLE IF EXISTS channels ADD COLUMN IF NOT EXISTS alias VARCHAR(32) NOT NULL DEFAULT ''`,
+					`CREATE UNIQUE INDEX IF NOT EXISTS channels_alias_idx ON channels (alias) WHERE alias <> ''`,
+				},
+				Down: []string{
+					`DROP INDEX IF EXISTS channels_alias_idx`,
+					`ALTER TABLE IF EXISTS channels DROP COLUMN IF EXISTS alias`,
+				},
+			},
+			{
+				Id: "things_16",
+				Up: []string{
+					`ALTER TABLE IF EXISTS channels ADD COLUMN IF NOT EXISTS type VARCHAR(16) NOT NULL DEFAULT ''`,
+					`ALTER TABLE IF EXISTS channels ADD COLUMN IF NOT EXISTS peers UUID[]`,
+					`CREATE UNIQUE INDEX IF NOT EXISTS channels_direct_idx ON channels (owner, peers) WHERE type = 'direct'`,
+				},
+				Down: []string{
+					`DROP INDEX IF EXISTS channels_direct_idx`,
+					`ALTER TABLE IF EXISTS channels DROP COLUMN IF EXISTS peers`,
+					`ALTER TABLE IF EXISTS channels DROP COLUMN IF EXISTS type`,
+				},
+			},
+			{
+				Id: "things_17",
+				Up: []string{
+					`ALTER TABLE IF EXISTS thing_keys RENAME TO key_history`,
+					`ALTER TABLE IF EXISTS key_history DROP CONSTRAINT IF EXISTS thing_keys_pkey`,
+					`ALTER TABLE IF EXISTS key_history ADD COLUMN IF NOT EXISTS replaced_at TIMESTAMP NOT NULL DEFAULT NOW()`,
+					`CREATE INDEX IF NOT EXISTS key_history_key_idx ON key_history (key)`,
+					`CREATE INDEX IF NOT EXISTS key_history_thing_idx ON key_history (thing_id, thing_owner)`,
+				},
+				Down: []string{
+					`DROP INDEX IF EXISTS key_history_thing_idx`,
+					`DROP INDEX IF EXISTS key_history_key_idx`,
+					`DELETE FROM key_history WHERE expires_at <= NOW()`,
+					`ALTER TABLE IF EXISTS key_history DROP COLUMN IF EXISTS replaced_at`,
+					`ALTER TABLE IF EXISTS key_history ADD PRIMARY KEY (key)`,
+					`ALTER TABLE IF EXISTS key_history RENAME TO thing_keys`,
+				},
+			},
+			{
+				Id: "things_18",
+				Up: []string{
+					`ALTER TABLE IF EXISTS channels ADD COLUMN IF NOT EXISTS ordered BOOLEAN NOT NULL DEFAULT FALSE`,
+				},
+				Down: []string{
+					`ALTER TABLE IF EXISTS channels DROP COLUMN IF EXISTS ordered`,
+				},
+			},
+			{
+				Id: "things_19",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS payload_schemas (
+						channel_id    UUID,
+						channel_owner VARCHAR(254) NOT NULL,
+						subtopic      VARCHAR(1024),
+						definition    JSONB NOT NULL,
+						FOREIGN KEY (channel_id, channel_owner) REFERENCES channels (id, owner) ON DELETE CASCADE ON UPDATE CASCADE,
+						PRIMARY KEY (channel_id, subtopic)
+					)`,
+				},
+				Down: []string{
+					`DROP TABLE IF EXISTS payload_schemas`,
+				},
+			},
+			{
+				Id: "things_20",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS thing_user_shares (
+						thing_id    UUID,
+						thing_owner VARCHAR(254),
+						email       VARCHAR(254),
+						permission  VARCHAR(16) NOT NULL,
+						FOREIGN KEY (thing_id, thing_owner) REFERENCES things (id, owner) ON DELETE CASCADE ON UPDATE CASCADE,
+						PRIMARY KEY (thing_id, thing_owner, email)
+					)`,
+					`CREATE TABLE IF NOT EXISTS channel_user_shares (
+						channel_id    UUID,
+						channel_owner VARCHAR(254),
+						email         VARCHAR(254),
+						permission    VARCHAR(16) NOT NULL,
+						FOREIGN KEY (channel_id, channel_owner) REFERENCES channels (id, owner) ON DELETE CASCADE ON UPDATE CASCADE,
+						PRIMARY KEY (channel_id, channel_owner, email)
+					)`,
+					`CREATE INDEX IF NOT EXISTS thing_user_shares_email_idx ON thing_user_shares (email)`,
+					`CREATE INDEX IF NOT EXISTS channel_user_shares_email_idx ON channel_user_shares (email)`,
+				},
+				Down: []string{
+					`DROP TABLE IF EXISTS thing_user_shares`,
+					`DROP TABLE IF EXISTS channel_user_shares`,
+				},
+			},
 		},
 	}
 
 	_, err := migrate.Exec(db.DB, "postgres", migrations, migrate.Up)
 	return err
 }
+
+// padMigrationIDs renames the applied migrations recorded before their IDs
+// were zero padded, so that they aren't reported as unknown.
+func padMigrationIDs(db *sqlx.DB) error {
+	q := `DO $$
+	      BEGIN
+	          IF to_regclass('gorp_migrations') IS NOT NULL THEN
+	              UPDATE gorp_migrations SET id = 'things_0' || substring(id from 8) WHERE id ~ '^things_[0-9]$';
+	          END IF;
+	      END $$`
+
+	_, err := db.Exec(q)
+	return err
+}
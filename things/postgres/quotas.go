@@ -0,0 +1,121 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+	"github.com/mainflux/mainflux/things"
+)
+
+var _ things.QuotaRepository = (*quotaRepository)(nil)
+
+type quotaRepository struct {
+	db Database
+}
+
+// NewQuotaRepository instantiates a PostgreSQL implementation of quota
+// repository.
+func NewQuotaRepository(db Database) things.QuotaRepository {
+	return &quotaRepository{
+		db: db,
+	}
+}
+
+func (qr quotaRepository) Save(ctx context.Context, quota things.Quota) error {
+	q := `INSERT INTO quotas (owner, things, channels, connections)
+	      VALUES (:owner, :things, :channels, :connections)
+	      ON CONFLICT (owner) DO UPDATE SET things = excluded.things,
+	      channels = excluded.channels, connections = excluded.connections;`
+
+	if _, err := qr.db.NamedExecContext(ctx, q, toDBQuota(quota)); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok {
+			switch pqErr.Code.Name() {
+			case errInvalid, errTruncation:
+				return things.ErrMalformedEntity
+			}
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (qr quotaRepository) RetrieveByOwner(ctx context.Context, owner string) (things.Quota, error) {
+	q := `SELECT owner, things, channels, connections FROM quotas WHERE owner = $1;`
+
+	var dbq dbQuota
+	if err := qr.db.QueryRowxContext(ctx, q, owner).StructScan(&dbq); err != nil {
+		if err == sql.ErrNoRows {
+			return things.Quota{}, things.ErrNotFound
+		}
+
+		return things.Quota{}, err
+	}
+
+	return toQuota(dbq), nil
+}
+
+func (qr quotaRepository) Remove(ctx context.Context, owner string) error {
+	q := `DELETE FROM quotas WHERE owner = :owner;`
+
+	_, err := qr.db.NamedExecContext(ctx, q, dbQuota{Owner: owner})
+	return err
+}
+
+func (qr quotaRepository) RetrieveUsage(ctx context.Context, owner string) (things.Usage, error) {
+	q := `SELECT
+	      (SELECT COUNT(*) FROM things WHERE owner = $1 AND deleted_at IS NULL) AS things,
+	      (SELECT COUNT(*) FROM channels WHERE owner = $1 AND deleted_at IS NULL) AS channels,
+	      (SELECT COUNT(*) FROM connections co
+	       INNER JOIN channels ch ON ch.id = co.channel_id AND ch.owner = co.channel_owner
+	       INNER JOIN things th ON th.id = co.thing_id AND th.owner = co.thing_owner
+	       WHERE co.channel_owner = $1 AND ch.deleted_at IS NULL AND th.deleted_at IS NULL) AS connections;`
+
+	var dbu dbUsage
+	if err := qr.db.QueryRowxContext(ctx, q, owner).StructScan(&dbu); err != nil {
+		return things.Usage{}, err
+	}
+
+	return things.Usage{
+		Things:      uint64(dbu.Things),
+		Channels:    uint64(dbu.Channels),
+		Connections: uint64(dbu.Connections),
+	}, nil
+}
+
+type dbQuota struct {
+	Owner       string `db:"owner"`
+	Things      int64  `db:"things"`
+	Channels    int64  `db:"channels"`
+	Connections int64  `db:"connections"`
+}
+
+func toDBQuota(quota things.Quota) dbQuota {
+	return dbQuota{
+		Owner:       quota.Owner,
+		Things:      int64(quota.Things),
+		Channels:    int64(quota.Channels),
+		Connections: int64(quota.Connections),
+	}
+}
+
+func toQuota(dbq dbQuota) things.Quota {
+	return things.Quota{
+		Owner:       dbq.Owner,
+		Things:      uint64(dbq.Things),
+		Channels:    uint64(dbq.Channels),
+		Connections: uint64(dbq.Connections),
+	}
+}
+
+type dbUsage struct {
+	Things      int64 `db:"things"`
+	Channels    int64 `db:"channels"`
+	Connections int64 `db:"connections"`
+}
@@ -0,0 +1,106 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux/things"
+	"github.com/mainflux/mainflux/things/postgres"
+	"github.com/mainflux/mainflux/things/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuotaSave(t *testing.T) {
+	email := "quota-save@example.com"
+	dbMiddleware := postgres.NewDatabase(db)
+	quotaRepo := postgres.NewQuotaRepository(dbMiddleware)
+
+	cases := []struct {
+		desc  string
+		quota things.Quota
+	}{
+		{
+			desc:  "save new quota",
+			quota: things.Quota{Owner: email, Things: 10, Channels: 5, Connections: 50},
+		},
+		{
+			desc:  "save existing quota",
+			quota: things.Quota{Owner: email, Things: 20},
+		},
+	}
+
+	for _, tc := range cases {
+		err := quotaRepo.Save(context.Background(), tc.quota)
+		assert.Nil(t, err, fmt.Sprintf("%s: got unexpected error: %s", tc.desc, err))
+
+		quota, err := quotaRepo.RetrieveByOwner(context.Background(), email)
+		assert.Nil(t, err, fmt.Sprintf("%s: got unexpected error: %s", tc.desc, err))
+		assert.Equal(t, tc.quota, quota, fmt.Sprintf("%s: expected %v got %v", tc.desc, tc.quota, quota))
+	}
+}
+
+func TestQuotaRemoval(t *testing.T) {
+	email := "quota-removal@example.com"
+	dbMiddleware := postgres.NewDatabase(db)
+	quotaRepo := postgres.NewQuotaRepository(dbMiddleware)
+
+	err := quotaRepo.Save(context.Background(), things.Quota{Owner: email, Things: 1})
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	// show that the removal works the same for both existing and non-existing
+	// (removed) quota
+	for i := 0; i < 2; i++ {
+		err := quotaRepo.Remove(context.Background(), email)
+		require.Nil(t, err, fmt.Sprintf("#%d: failed to remove quota due to: %s", i, err))
+
+		_, err = quotaRepo.RetrieveByOwner(context.Background(), email)
+		require.Equal(t, things.ErrNotFound, err, fmt.Sprintf("#%d: expected %s got %s", i, things.ErrNotFound, err))
+	}
+}
+
+func TestUsageRetrieval(t *testing.T) {
+	email := "quota-usage@example.com"
+	dbMiddleware := postgres.NewDatabase(db)
+	thingRepo := postgres.NewThingRepository(dbMiddleware)
+	chanRepo := postgres.NewChannelRepository(dbMiddleware)
+	quotaRepo := postgres.NewQuotaRepository(dbMiddleware)
+
+	usage, err := quotaRepo.RetrieveUsage(context.Background(), email)
+	assert.Nil(t, err, fmt.Sprintf("retrieve empty usage: got unexpected error: %s", err))
+	assert.Equal(t, things.Usage{}, usage, fmt.Sprintf("retrieve empty usage: expected %v got %v", things.Usage{}, usage))
+
+	ths := []string{}
+	for i := 0; i < 3; i++ {
+		thid, err := uuid.New().ID()
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+		thkey, err := uuid.New().ID()
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+		id, err := thingRepo.Save(context.Background(), things.Thing{ID: thid, Owner: email, Key: thkey})
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+		ths = append(ths, id)
+	}
+
+	chid, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	chanID, err := chanRepo.Save(context.Background(), things.Channel{ID: chid, Owner: email})
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	for _, th := range ths {
+		err := chanRepo.Connect(context.Background(), email, chanID, th, things.Actions)
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	}
+
+	err = thingRepo.Remove(context.Background(), email, ths[0])
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	expected := things.Usage{Things: 2, Channels: 1, Connections: 2}
+	usage, err = quotaRepo.RetrieveUsage(context.Background(), email)
+	assert.Nil(t, err, fmt.Sprintf("retrieve usage: got unexpected error: %s", err))
+	assert.Equal(t, expected, usage, fmt.Sprintf("retrieve usage: expected %v got %v", expected, usage))
+}
@@ -0,0 +1,215 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/lib/pq"
+	"github.com/mainflux/mainflux/things"
+)
+
+var _ things.SchemaRepository = (*schemaRepository)(nil)
+
+type schemaRepository struct {
+	db Database
+}
+
+// NewSchemaRepository instantiates a PostgreSQL implementation of metadata
+// schema repository.
+func NewSchemaRepository(db Database) things.SchemaRepository {
+	return &schemaRepository{
+		db: db,
+	}
+}
+
+func (sr schemaRepository) Save(ctx context.Context, schema things.MetadataSchema) error {
+	q := `INSERT INTO metadata_schemas (owner, entity, definition)
+	      VALUES (:owner, :entity, :definition)
+	      ON CONFLICT (owner, entity) DO UPDATE SET definition = excluded.definition;`
+
+	dbs, err := toDBSchema(schema)
+	if err != nil {
+		return err
+	}
+
+	if _, err := sr.db.NamedExecContext(ctx, q, dbs); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok {
+			switch pqErr.Code.Name() {
+			case errInvalid, errTruncation:
+				return things.ErrMalformedEntity
+			}
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (sr schemaRepository) RetrieveByEntity(ctx context.Context, owner, entity string) (things.MetadataSchema, error) {
+	q := `SELECT owner, entity, definition FROM metadata_schemas WHERE owner = $1 AND entity = $2;`
+
+	var dbs dbSchema
+	if err := sr.db.QueryRowxContext(ctx, q, owner, entity).StructScan(&dbs); err != nil {
+		if err == sql.ErrNoRows {
+			return things.MetadataSchema{}, things.ErrNotFound
+		}
+
+		return things.MetadataSchema{}, err
+	}
+
+	return toSchema(dbs)
+}
+
+func (sr schemaRepository) Remove(ctx context.Context, owner, entity string) error {
+	q := `DELETE FROM metadata_schemas WHERE owner = :owner AND entity = :entity;`
+
+	_, err := sr.db.NamedExecContext(ctx, q, dbSchema{Owner: owner, Entity: entity})
+	return err
+}
+
+func (sr schemaRepository) SavePayload(ctx context.Context, schema things.PayloadSchema) error {
+	q := `INSERT INTO payload_schemas (channel_id, channel_owner, subtopic, definition)
+	      VALUES (:channel_id, :channel_owner, :subtopic, :definition)
+	      ON CONFLICT (channel_id, subtopic) DO UPDATE SET definition = excluded.definition;`
+
+	def, err := json.Marshal(schema.Definition)
+	if err != nil {
+		return things.ErrMalformedEntity
+	}
+
+	dbps := dbPayloadSchema{
+		Channel:    schema.Channel,
+		Owner:      schema.Owner,
+		Subtopic:   schema.Subtopic,
+		Definition: def,
+	}
+
+	if _, err := sr.db.NamedExecContext(ctx, q, dbps); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok {
+			switch pqErr.Code.Name() {
+			case errInvalid, errTruncation:
+				return things.ErrMalformedEntity
+			case errFK:
+				return things.ErrNotFound
+			}
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (sr schemaRepository) RetrievePayload(ctx context.Context, chanID, subtopic string) (things.PayloadSchema, error) {
+	q := `SELECT channel_id, channel_owner, subtopic, definition FROM payload_schemas WHERE channel_id = $1 AND subtopic = $2;`
+
+	var dbps dbPayloadSchema
+	if err := sr.db.QueryRowxContext(ctx, q, chanID, subtopic).StructScan(&dbps); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if err == sql.ErrNoRows || ok && errInvalid == pqErr.Code.Name() {
+			return things.PayloadSchema{}, things.ErrNotFound
+		}
+
+		return things.PayloadSchema{}, err
+	}
+
+	return toPayloadSchema(dbps)
+}
+
+func (sr schemaRepository) RetrievePayloads(ctx context.Context, chanID string) ([]things.PayloadSchema, error) {
+	q := `SELECT channel_id, channel_owner, subtopic, definition FROM payload_schemas WHERE channel_id = :channel_id ORDER BY subtopic;`
+
+	rows, err := sr.db.NamedQueryContext(ctx, q, map[string]interface{}{"channel_id": chanID})
+	if err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && errInvalid == pqErr.Code.Name() {
+			return []things.PayloadSchema{}, nil
+		}
+
+		return nil, err
+	}
+	defer rows.Close()
+
+	schemas := []things.PayloadSchema{}
+	for rows.Next() {
+		var dbps dbPayloadSchema
+		if err := rows.StructScan(&dbps); err != nil {
+			return nil, err
+		}
+
+		schema, err := toPayloadSchema(dbps)
+		if err != nil {
+			return nil, err
+		}
+		schemas = append(schemas, schema)
+	}
+
+	return schemas, nil
+}
+
+func (sr schemaRepository) RemovePayload(ctx context.Context, chanID, subtopic string) error {
+	q := `DELETE FROM payload_schemas WHERE channel_id = :channel_id AND subtopic = :subtopic;`
+
+	_, err := sr.db.NamedExecContext(ctx, q, dbPayloadSchema{Channel: chanID, Subtopic: subtopic})
+	return err
+}
+
+type dbSchema struct {
+	Owner      string `db:"owner"`
+	Entity     string `db:"entity"`
+	Definition []byte `db:"definition"`
+}
+
+func toDBSchema(schema things.MetadataSchema) (dbSchema, error) {
+	def, err := json.Marshal(schema.Definition)
+	if err != nil {
+		return dbSchema{}, things.ErrMalformedEntity
+	}
+
+	return dbSchema{
+		Owner:      schema.Owner,
+		Entity:     schema.Entity,
+		Definition: def,
+	}, nil
+}
+
+func toSchema(dbs dbSchema) (things.MetadataSchema, error) {
+	var def map[string]interface{}
+	if err := json.Unmarshal(dbs.Definition, &def); err != nil {
+		return things.MetadataSchema{}, things.ErrScanMetadata
+	}
+
+	return things.MetadataSchema{
+		Owner:      dbs.Owner,
+		Entity:     dbs.Entity,
+		Definition: def,
+	}, nil
+}
+
+type dbPayloadSchema struct {
+	Channel    string `db:"channel_id"`
+	Owner      string `db:"channel_owner"`
+	Subtopic   string `db:"subtopic"`
+	Definition []byte `db:"definition"`
+}
+
+func toPayloadSchema(dbps dbPayloadSchema) (things.PayloadSchema, error) {
+	var def map[string]interface{}
+	if err := json.Unmarshal(dbps.Definition, &def); err != nil {
+		return things.PayloadSchema{}, things.ErrScanMetadata
+	}
+
+	return things.PayloadSchema{
+		Owner:      dbps.Owner,
+		Channel:    dbps.Channel,
+		Subtopic:   dbps.Subtopic,
+		Definition: def,
+	}, nil
+}
@@ -0,0 +1,200 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux/things"
+	"github.com/mainflux/mainflux/things/postgres"
+	"github.com/mainflux/mainflux/things/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaSave(t *testing.T) {
+	email := "schema-save@example.com"
+	dbMiddleware := postgres.NewDatabase(db)
+	schemaRepo := postgres.NewSchemaRepository(dbMiddleware)
+
+	cases := []struct {
+		desc   string
+		schema things.MetadataSchema
+	}{
+		{
+			desc: "save new things schema",
+			schema: things.MetadataSchema{
+				Owner:  email,
+				Entity: things.ThingsEntity,
+				Definition: map[string]interface{}{
+					"$schema": "http://json-schema.org/draft-07/schema#",
+					"type":    "object",
+				},
+			},
+		},
+		{
+			desc: "save existing things schema",
+			schema: things.MetadataSchema{
+				Owner:      email,
+				Entity:     things.ThingsEntity,
+				Definition: map[string]interface{}{"required": []interface{}{"serial"}},
+			},
+		},
+		{
+			desc: "save new channels schema",
+			schema: things.MetadataSchema{
+				Owner:      email,
+				Entity:     things.ChannelsEntity,
+				Definition: map[string]interface{}{"type": "object"},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		err := schemaRepo.Save(context.Background(), tc.schema)
+		assert.Nil(t, err, fmt.Sprintf("%s: got unexpected error: %s", tc.desc, err))
+
+		schema, err := schemaRepo.RetrieveByEntity(context.Background(), email, tc.schema.Entity)
+		assert.Nil(t, err, fmt.Sprintf("%s: got unexpected error: %s", tc.desc, err))
+		assert.Equal(t, tc.schema, schema, fmt.Sprintf("%s: expected %v got %v", tc.desc, tc.schema, schema))
+	}
+}
+
+func TestSchemaRemoval(t *testing.T) {
+	email := "schema-removal@example.com"
+	dbMiddleware := postgres.NewDatabase(db)
+	schemaRepo := postgres.NewSchemaRepository(dbMiddleware)
+
+	for _, entity := range []string{things.ThingsEntity, things.ChannelsEntity} {
+		schema := things.MetadataSchema{Owner: email, Entity: entity, Definition: map[string]interface{}{"type": "object"}}
+		err := schemaRepo.Save(context.Background(), schema)
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	}
+
+	// show that the removal works the same for both existing and non-existing
+	// (removed) schema
+	for i := 0; i < 2; i++ {
+		err := schemaRepo.Remove(context.Background(), email, things.ThingsEntity)
+		require.Nil(t, err, fmt.Sprintf("#%d: failed to remove schema due to: %s", i, err))
+
+		_, err = schemaRepo.RetrieveByEntity(context.Background(), email, things.ThingsEntity)
+		require.Equal(t, things.ErrNotFound, err, fmt.Sprintf("#%d: expected %s got %s", i, things.ErrNotFound, err))
+	}
+
+	_, err := schemaRepo.RetrieveByEntity(context.Background(), email, things.ChannelsEntity)
+	assert.Nil(t, err, fmt.Sprintf("retrieve channels schema: got unexpected error: %s", err))
+}
+
+func TestPayloadSchemaSave(t *testing.T) {
+	email := "payload-schema-save@example.com"
+	dbMiddleware := postgres.NewDatabase(db)
+	chanRepo := postgres.NewChannelRepository(dbMiddleware)
+	schemaRepo := postgres.NewSchemaRepository(dbMiddleware)
+
+	chid, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	_, err = chanRepo.Save(context.Background(), things.Channel{ID: chid, Owner: email})
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	nonexistentChanID, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		schema things.PayloadSchema
+		err    error
+	}{
+		{
+			desc: "save new channel payload schema",
+			schema: things.PayloadSchema{
+				Owner:      email,
+				Channel:    chid,
+				Definition: map[string]interface{}{"type": "object"},
+			},
+			err: nil,
+		},
+		{
+			desc: "save new subtopic payload schema",
+			schema: things.PayloadSchema{
+				Owner:      email,
+				Channel:    chid,
+				Subtopic:   "temp",
+				Definition: map[string]interface{}{"type": "object"},
+			},
+			err: nil,
+		},
+		{
+			desc: "save existing subtopic payload schema",
+			schema: things.PayloadSchema{
+				Owner:      email,
+				Channel:    chid,
+				Subtopic:   "temp",
+				Definition: map[string]interface{}{"required": []interface{}{"temp"}},
+			},
+			err: nil,
+		},
+		{
+			desc: "save payload schema of non-existing channel",
+			schema: things.PayloadSchema{
+				Owner:      email,
+				Channel:    nonexistentChanID,
+				Definition: map[string]interface{}{"type": "object"},
+			},
+			err: things.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := schemaRepo.SavePayload(context.Background(), tc.schema)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+		if tc.err != nil {
+			continue
+		}
+
+		schema, err := schemaRepo.RetrievePayload(context.Background(), tc.schema.Channel, tc.schema.Subtopic)
+		assert.Nil(t, err, fmt.Sprintf("%s: got unexpected error: %s", tc.desc, err))
+		assert.Equal(t, tc.schema, schema, fmt.Sprintf("%s: expected %v got %v", tc.desc, tc.schema, schema))
+	}
+
+	schemas, err := schemaRepo.RetrievePayloads(context.Background(), chid)
+	assert.Nil(t, err, fmt.Sprintf("retrieve payload schemas: got unexpected error: %s", err))
+	assert.Equal(t, 2, len(schemas), fmt.Sprintf("retrieve payload schemas: expected 2 got %d", len(schemas)))
+}
+
+func TestPayloadSchemaRemoval(t *testing.T) {
+	email := "payload-schema-removal@example.com"
+	dbMiddleware := postgres.NewDatabase(db)
+	chanRepo := postgres.NewChannelRepository(dbMiddleware)
+	schemaRepo := postgres.NewSchemaRepository(dbMiddleware)
+
+	chid, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	_, err = chanRepo.Save(context.Background(), things.Channel{ID: chid, Owner: email})
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	for _, subtopic := range []string{"", "temp"} {
+		schema := things.PayloadSchema{Owner: email, Channel: chid, Subtopic: subtopic, Definition: map[string]interface{}{"type": "object"}}
+		err := schemaRepo.SavePayload(context.Background(), schema)
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	}
+
+	// show that the removal works the same for both existing and non-existing
+	// (removed) schema
+	for i := 0; i < 2; i++ {
+		err := schemaRepo.RemovePayload(context.Background(), chid, "temp")
+		require.Nil(t, err, fmt.Sprintf("#%d: failed to remove payload schema due to: %s", i, err))
+
+		_, err = schemaRepo.RetrievePayload(context.Background(), chid, "temp")
+		require.Equal(t, things.ErrNotFound, err, fmt.Sprintf("#%d: expected %s got %s", i, things.ErrNotFound, err))
+	}
+
+	// purging the channel removes its payload schemas
+	err = chanRepo.Purge(context.Background(), email, chid)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	_, err = schemaRepo.RetrievePayload(context.Background(), chid, "")
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("retrieve payload schema of purged channel: expected %s got %s", things.ErrNotFound, err))
+}
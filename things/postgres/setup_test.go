@@ -66,6 +66,11 @@ func TestMain(m *testing.M) {
 		SSLCert:     "",
 		SSLKey:      "",
 		SSLRootCert: "",
+
+		MaxOpenConns:    -1,
+		MaxIdleConns:    -1,
+		ConnMaxLifetime: -1,
+		ConnMaxIdleTime: -1,
 	}
 
 	if db, err = postgres.Connect(dbConfig); err != nil {
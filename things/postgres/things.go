@@ -8,6 +8,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/gofrs/uuid"
 	"github.com/lib/pq" // required for DB access
@@ -63,7 +64,8 @@ func (tr thingRepository) Save(ctx context.Context, thing things.Thing) (string,
 }
 
 func (tr thingRepository) Update(ctx context.Context, thing things.Thing) error {
-	q := `UPDATE things SET name = :name, metadata = :metadata WHERE owner = :owner AND id = :id;`
+	q := `UPDATE things SET name = :name, metadata = :metadata
+	      WHERE owner = :owner AND id = :id AND deleted_at IS NULL;`
 
 	dbth, err := toDBThing(thing)
 	if err != nil {
@@ -95,22 +97,31 @@ func (tr thingRepository) Update(ctx context.Context, thing things.Thing) error
 	return nil
 }
 
-func (tr thingRepository) UpdateKey(ctx context.Context, owner, id, key string) error {
-	q := `UPDATE things SET key = :key WHERE owner = :owner AND id = :id;`
-
-	dbth := dbThing{
-		ID:    id,
-		Owner: owner,
-		Key:   key,
-	}
+func (tr thingRepository) UpdateKey(ctx context.Context, owner, id, key string, overlap time.Duration) error {
+	// The previous key is moved to the key history in the same statement,
+	// unless the key isn't changed. Expired keys are kept in the history.
+	q := `WITH previous AS (
+	          SELECT id, owner, key FROM things WHERE id = $1 AND owner = $2 AND deleted_at IS NULL FOR UPDATE
+	      ), updated AS (
+	          UPDATE things t SET key = $3 FROM previous p WHERE t.id = p.id AND t.owner = p.owner AND p.key <> $3
+	          RETURNING p.key AS previous_key, t.id, t.owner
+	      ), recorded AS (
+	          INSERT INTO key_history (key, thing_id, thing_owner, replaced_at, expires_at)
+	          SELECT previous_key, id, owner, NOW(), NOW() + $4 * INTERVAL '1 millisecond' FROM updated
+	      )
+	      SELECT id FROM previous;`
+
+	var thingID string
+	if err := tr.db.QueryRowxContext(ctx, q, id, owner, key, int64(overlap/time.Millisecond)).Scan(&thingID); err != nil {
+		if err == sql.ErrNoRows {
+			return things.ErrNotFound
+		}
 
-	res, err := tr.db.NamedExecContext(ctx, q, dbth)
-	if err != nil {
 		pqErr, ok := err.(*pq.Error)
 		if ok {
 			switch pqErr.Code.Name() {
 			case errInvalid:
-				return things.ErrMalformedEntity
+				return things.ErrNotFound
 			case errDuplicate:
 				return things.ErrConflict
 			}
@@ -119,20 +130,23 @@ func (tr thingRepository) UpdateKey(ctx context.Context, owner, id, key string)
 		return err
 	}
 
-	cnt, err := res.RowsAffected()
-	if err != nil {
-		return err
-	}
+	return nil
+}
 
-	if cnt == 0 {
-		return things.ErrNotFound
+func (tr thingRepository) HashKeys(ctx context.Context, hasher things.KeyHasher) error {
+	// Previous keys are hashed as well, since they remain valid during the
+	// overlap period.
+	for _, table := range []string{"things", "key_history"} {
+		if err := hashKeys(ctx, tr.db, table, hasher); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
 func (tr thingRepository) RetrieveByID(ctx context.Context, owner, id string) (things.Thing, error) {
-	q := `SELECT name, key, metadata FROM things WHERE id = $1 AND owner = $2;`
+	q := `SELECT name, key, metadata FROM things WHERE id = $1 AND owner = $2 AND deleted_at IS NULL;`
 
 	dbth := dbThing{
 		ID:    id,
@@ -154,39 +168,113 @@ func (tr thingRepository) RetrieveByID(ctx context.Context, owner, id string) (t
 }
 
 func (tr thingRepository) RetrieveByKey(ctx context.Context, key string) (string, error) {
-	q := `SELECT id FROM things WHERE key = $1;`
+	id, err := retrieveIDByKey(ctx, tr.db, key)
+	if err == sql.ErrNoRows {
+		return "", things.ErrNotFound
+	}
 
-	var id string
-	if err := tr.db.QueryRowxContext(ctx, q, key).Scan(&id); err != nil {
-		if err == sql.ErrNoRows {
+	return id, err
+}
+
+func (tr thingRepository) RetrieveOwner(ctx context.Context, id string) (string, error) {
+	q := `SELECT owner FROM things WHERE id = $1 AND deleted_at IS NULL;`
+
+	var owner string
+	if err := tr.db.QueryRowxContext(ctx, q, id).Scan(&owner); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if err == sql.ErrNoRows || ok && errInvalid == pqErr.Code.Name() {
 			return "", things.ErrNotFound
 		}
 		return "", err
 	}
 
-	return id, nil
+	return owner, nil
+}
+
+func (tr thingRepository) RetrieveShared(ctx context.Context, id, email string, groups []string) (things.Thing, string, error) {
+	// Permissions are ordered so that the widest permission granted to the
+	// user or any of the groups is the smallest one.
+	q := `SELECT t.id, t.owner, t.name, t.key, t.metadata, MIN(s.permission) AS permission FROM things t
+	      INNER JOIN (
+	          SELECT thing_id, thing_owner, permission FROM thing_shares
+	          WHERE thing_id = :id AND group_id = ANY(CAST(:groups AS UUID[]))
+	          UNION ALL
+	          SELECT thing_id, thing_owner, permission FROM thing_user_shares
+	          WHERE thing_id = :id AND email = :email
+	      ) s ON s.thing_id = t.id AND s.thing_owner = t.owner
+	      WHERE t.id = :id AND t.deleted_at IS NULL
+	      GROUP BY t.id, t.owner;`
+
+	params := map[string]interface{}{
+		"id":     id,
+		"email":  email,
+		"groups": pq.StringArray(groups),
+	}
+
+	rows, err := tr.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && errInvalid == pqErr.Code.Name() {
+			return things.Thing{}, "", things.ErrNotFound
+		}
+		return things.Thing{}, "", err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return things.Thing{}, "", things.ErrNotFound
+	}
+
+	var dbth dbSharedThing
+	if err := rows.StructScan(&dbth); err != nil {
+		return things.Thing{}, "", err
+	}
+
+	th, err := toThing(dbth.dbThing)
+	if err != nil {
+		return things.Thing{}, "", err
+	}
+
+	return th, dbth.Permission, nil
 }
 
-func (tr thingRepository) RetrieveAll(ctx context.Context, owner string, offset, limit uint64, name string, metadata things.Metadata) (things.ThingsPage, error) {
-	nq, name := getNameQuery(name)
-	m, mq, err := getMetadataQuery(metadata)
+func (tr thingRepository) RetrieveAll(ctx context.Context, owner string, pageQuery things.PageQuery, groups []string) (things.ThingsPage, error) {
+	nq, name := getNameQuery(pageQuery.Name)
+	m, mq, err := getMetadataQuery(pageQuery.Metadata)
 	if err != nil {
 		return things.ThingsPage{}, err
 	}
+	cmpq, cmpp, err := getComparisonQuery(pageQuery.Query)
+	if err != nil {
+		return things.ThingsPage{}, err
+	}
+	dq := getDeletedQuery(pageQuery.Deleted)
+	kq, offset := getCursorQuery(pageQuery.Cursor, pageQuery.Dir, pageQuery.Offset)
+	oq := getOrderQuery(pageQuery.Order, pageQuery.Dir)
+	sq := getOwnerQuery("thing", pageQuery.Shared)
 
-	q := fmt.Sprintf(`SELECT id, name, key, metadata FROM things
-		  WHERE owner = :owner %s%s ORDER BY id LIMIT :limit OFFSET :offset;`, mq, nq)
+	q := fmt.Sprintf(`SELECT id, owner, name, key, metadata, deleted_at FROM things
+		  WHERE %s%s%s%s%s%s %s LIMIT :limit OFFSET :offset;`, sq, mq, cmpq, nq, dq, kq, oq)
 
 	params := map[string]interface{}{
 		"owner":    owner,
-		"limit":    limit,
+		"limit":    pageQuery.Limit,
 		"offset":   offset,
+		"cursor":   pageQuery.Cursor,
 		"name":     name,
 		"metadata": m,
+		"groups":   pq.StringArray(groups),
+	}
+	for k, v := range cmpp {
+		params[k] = v
 	}
 
 	rows, err := tr.db.NamedQueryContext(ctx, q, params)
 	if err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && pqErr.Code.Name() == errInvalid {
+			return things.ThingsPage{}, things.ErrMalformedEntity
+		}
 		return things.ThingsPage{}, err
 	}
 	defer rows.Close()
@@ -206,35 +294,75 @@ func (tr thingRepository) RetrieveAll(ctx context.Context, owner string, offset,
 		items = append(items, th)
 	}
 
-	cq := ""
-	if name != "" {
-		cq = `AND LOWER(name) LIKE $2`
+	q = fmt.Sprintf(`SELECT COUNT(*) FROM things WHERE %s%s%s;`, sq, nq, dq)
+
+	total, err := getTotal(ctx, tr.db, q, params)
+	if err != nil {
+		return things.ThingsPage{}, err
 	}
 
-	q = fmt.Sprintf(`SELECT COUNT(*) FROM things WHERE owner = $1 %s;`, cq)
+	page := things.ThingsPage{
+		Things: items,
+		PageMetadata: things.PageMetadata{
+			Total:  total,
+			Offset: offset,
+			Limit:  pageQuery.Limit,
+		},
+	}
+
+	return page, nil
+}
+
+func (tr thingRepository) Search(ctx context.Context, owner, query string, offset, limit uint64) (things.ThingsPage, error) {
+	q := fmt.Sprintf(`SELECT id, name, key, metadata FROM things
+	      WHERE owner = :owner AND deleted_at IS NULL AND %s @@ plainto_tsquery('simple', :query)
+	      ORDER BY ts_rank(%s, plainto_tsquery('simple', :query)) DESC, id
+	      LIMIT :limit OFFSET :offset;`, searchVector, searchVector)
+
+	params := map[string]interface{}{
+		"owner":  owner,
+		"query":  query,
+		"limit":  limit,
+		"offset": offset,
+	}
+
+	rows, err := tr.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return things.ThingsPage{}, err
+	}
+	defer rows.Close()
 
-	total := uint64(0)
-	switch name {
-	case "":
-		if err := tr.db.GetContext(ctx, &total, q, owner); err != nil {
+	items := []things.Thing{}
+	for rows.Next() {
+		dbth := dbThing{Owner: owner}
+		if err := rows.StructScan(&dbth); err != nil {
 			return things.ThingsPage{}, err
 		}
-	default:
-		if err := tr.db.GetContext(ctx, &total, q, owner, name); err != nil {
+
+		th, err := toThing(dbth)
+		if err != nil {
 			return things.ThingsPage{}, err
 		}
+
+		items = append(items, th)
 	}
 
-	page := things.ThingsPage{
+	q = fmt.Sprintf(`SELECT COUNT(*) FROM things
+	     WHERE owner = $1 AND deleted_at IS NULL AND %s @@ plainto_tsquery('simple', $2);`, searchVector)
+
+	var total uint64
+	if err := tr.db.GetContext(ctx, &total, q, owner, query); err != nil {
+		return things.ThingsPage{}, err
+	}
+
+	return things.ThingsPage{
 		Things: items,
 		PageMetadata: things.PageMetadata{
 			Total:  total,
 			Offset: offset,
 			Limit:  limit,
 		},
-	}
-
-	return page, nil
+	}, nil
 }
 
 func (tr thingRepository) RetrieveByChannel(ctx context.Context, owner, channel string, offset, limit uint64) (things.ThingsPage, error) {
@@ -247,7 +375,7 @@ func (tr thingRepository) RetrieveByChannel(ctx context.Context, owner, channel
 	      FROM things th
 	      INNER JOIN connections co
 		  ON th.id = co.thing_id
-		  WHERE th.owner = :owner AND co.channel_id = :channel
+		  WHERE th.owner = :owner AND co.channel_id = :channel AND th.deleted_at IS NULL
 		  ORDER BY th.id
 		  LIMIT :limit
 		  OFFSET :offset;`
@@ -284,7 +412,7 @@ func (tr thingRepository) RetrieveByChannel(ctx context.Context, owner, channel
 	     FROM things th
 	     INNER JOIN connections co
 	     ON th.id = co.thing_id
-	     WHERE th.owner = $1 AND co.channel_id = $2;`
+	     WHERE th.owner = $1 AND co.channel_id = $2 AND th.deleted_at IS NULL;`
 
 	var total uint64
 	if err := tr.db.GetContext(ctx, &total, q, owner, channel); err != nil {
@@ -301,7 +429,75 @@ func (tr thingRepository) RetrieveByChannel(ctx context.Context, owner, channel
 	}, nil
 }
 
+func (tr thingRepository) Export(ctx context.Context, owner string, fn func(things.Thing) error) error {
+	q := `SELECT id, owner, name, key, metadata FROM things
+	      WHERE owner = :owner AND deleted_at IS NULL ORDER BY id;`
+
+	rows, err := tr.db.NamedQueryContext(ctx, q, map[string]interface{}{"owner": owner})
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var dbth dbThing
+		if err := rows.StructScan(&dbth); err != nil {
+			return err
+		}
+
+		th, err := toThing(dbth)
+		if err != nil {
+			return err
+		}
+
+		if err := fn(th); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
 func (tr thingRepository) Remove(ctx context.Context, owner, id string) error {
+	dbth := dbThing{
+		ID:    id,
+		Owner: owner,
+	}
+	q := `UPDATE things SET deleted_at = NOW() WHERE id = :id AND owner = :owner AND deleted_at IS NULL;`
+	tr.db.NamedExecContext(ctx, q, dbth)
+	return nil
+}
+
+func (tr thingRepository) Restore(ctx context.Context, owner, id string) error {
+	q := `UPDATE things SET deleted_at = NULL WHERE id = :id AND owner = :owner AND deleted_at IS NOT NULL;`
+
+	dbth := dbThing{
+		ID:    id,
+		Owner: owner,
+	}
+
+	res, err := tr.db.NamedExecContext(ctx, q, dbth)
+	if err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && pqErr.Code.Name() == errInvalid {
+			return things.ErrNotFound
+		}
+		return err
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if cnt == 0 {
+		return things.ErrNotFound
+	}
+
+	return nil
+}
+
+func (tr thingRepository) Purge(ctx context.Context, owner, id string) error {
 	dbth := dbThing{
 		ID:    id,
 		Owner: owner,
@@ -311,12 +507,186 @@ func (tr thingRepository) Remove(ctx context.Context, owner, id string) error {
 	return nil
 }
 
+func (tr thingRepository) Share(ctx context.Context, owner, id, group, permission string) error {
+	q := `INSERT INTO thing_shares (thing_id, thing_owner, group_id, permission)
+	      SELECT id, owner, CAST(:group AS UUID), :permission FROM things
+	      WHERE id = :id AND owner = :owner AND deleted_at IS NULL
+	      ON CONFLICT (thing_id, thing_owner, group_id) DO UPDATE SET permission = excluded.permission;`
+
+	params := map[string]interface{}{
+		"id":         id,
+		"owner":      owner,
+		"group":      group,
+		"permission": permission,
+	}
+
+	res, err := tr.db.NamedExecContext(ctx, q, params)
+	if err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && errInvalid == pqErr.Code.Name() {
+			return things.ErrNotFound
+		}
+		return err
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if cnt == 0 {
+		return things.ErrNotFound
+	}
+
+	return nil
+}
+
+func (tr thingRepository) Unshare(ctx context.Context, owner, id, group string) error {
+	q := `DELETE FROM thing_shares WHERE thing_id = :id AND thing_owner = :owner AND group_id = :group;`
+
+	params := map[string]interface{}{
+		"id":    id,
+		"owner": owner,
+		"group": group,
+	}
+
+	if _, err := tr.db.NamedExecContext(ctx, q, params); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && errInvalid == pqErr.Code.Name() {
+			return nil
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (tr thingRepository) ShareWithUser(ctx context.Context, owner, id, email, permission string) error {
+	q := `INSERT INTO thing_user_shares (thing_id, thing_owner, email, permission)
+	      SELECT id, owner, :email, :permission FROM things
+	      WHERE id = :id AND owner = :owner AND deleted_at IS NULL
+	      ON CONFLICT (thing_id, thing_owner, email) DO UPDATE SET permission = excluded.permission;`
+
+	params := map[string]interface{}{
+		"id":         id,
+		"owner":      owner,
+		"email":      email,
+		"permission": permission,
+	}
+
+	res, err := tr.db.NamedExecContext(ctx, q, params)
+	if err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && errInvalid == pqErr.Code.Name() {
+			return things.ErrNotFound
+		}
+		return err
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if cnt == 0 {
+		return things.ErrNotFound
+	}
+
+	return nil
+}
+
+func (tr thingRepository) UnshareWithUser(ctx context.Context, owner, id, email string) error {
+	q := `DELETE FROM thing_user_shares WHERE thing_id = :id AND thing_owner = :owner AND email = :email;`
+
+	params := map[string]interface{}{
+		"id":    id,
+		"owner": owner,
+		"email": email,
+	}
+
+	if _, err := tr.db.NamedExecContext(ctx, q, params); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && errInvalid == pqErr.Code.Name() {
+			return nil
+		}
+		return err
+	}
+
+	return nil
+}
+
 type dbThing struct {
-	ID       string `db:"id"`
-	Owner    string `db:"owner"`
-	Name     string `db:"name"`
-	Key      string `db:"key"`
-	Metadata []byte `db:"metadata"`
+	ID        string      `db:"id"`
+	Owner     string      `db:"owner"`
+	Name      string      `db:"name"`
+	Key       string      `db:"key"`
+	Metadata  []byte      `db:"metadata"`
+	DeletedAt pq.NullTime `db:"deleted_at"`
+}
+
+type dbSharedThing struct {
+	dbThing
+	Permission string `db:"permission"`
+}
+
+// hashKeys replaces the plain-text keys stored in the table with their hashes.
+// Keys are collected before the update, so that the rows aren't modified while
+// they're being read.
+func hashKeys(ctx context.Context, db Database, table string, hasher things.KeyHasher) error {
+	q := fmt.Sprintf(`SELECT key FROM %s;`, table)
+
+	rows, err := db.NamedQueryContext(ctx, q, map[string]interface{}{})
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return err
+		}
+
+		if !hasher.Hashed(key) {
+			keys = append(keys, key)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	q = fmt.Sprintf(`UPDATE %s SET key = :hash WHERE key = :key;`, table)
+	for _, key := range keys {
+		params := map[string]interface{}{
+			"key":  key,
+			"hash": hasher.Hash(key),
+		}
+
+		if _, err := db.NamedExecContext(ctx, q, params); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// retrieveIDByKey returns the ID of the thing having the provided current
+// key, or the previous key that didn't expire yet.
+func retrieveIDByKey(ctx context.Context, db Database, key string) (string, error) {
+	q := `SELECT id FROM things WHERE key = $1 AND deleted_at IS NULL
+	      UNION
+	      SELECT t.id FROM key_history kh
+	      INNER JOIN things t ON t.id = kh.thing_id AND t.owner = kh.thing_owner
+	      WHERE kh.key = $1 AND kh.expires_at > NOW() AND t.deleted_at IS NULL;`
+
+	var id string
+	if err := db.PreparedQueryRowxContext(ctx, q, key).Scan(&id); err != nil {
+		return "", err
+	}
+
+	return id, nil
 }
 
 func toDBThing(th things.Thing) (dbThing, error) {
@@ -344,11 +714,17 @@ func toThing(dbth dbThing) (things.Thing, error) {
 		return things.Thing{}, err
 	}
 
+	var deletedAt time.Time
+	if dbth.DeletedAt.Valid {
+		deletedAt = dbth.DeletedAt.Time
+	}
+
 	return things.Thing{
-		ID:       dbth.ID,
-		Owner:    dbth.Owner,
-		Name:     dbth.Name,
-		Key:      dbth.Key,
-		Metadata: metadata,
+		ID:        dbth.ID,
+		Owner:     dbth.Owner,
+		Name:      dbth.Name,
+		Key:       dbth.Key,
+		Metadata:  metadata,
+		DeletedAt: deletedAt,
 	}, nil
 }
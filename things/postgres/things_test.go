@@ -6,12 +6,15 @@ package postgres_test
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/mainflux/mainflux/things"
+	"github.com/mainflux/mainflux/things/hmac"
 	"github.com/mainflux/mainflux/things/postgres"
 	"github.com/mainflux/mainflux/things/uuid"
 	"github.com/stretchr/testify/assert"
@@ -228,6 +231,13 @@ func TestUpdateKey(t *testing.T) {
 			key:   newKey,
 			err:   nil,
 		},
+		{
+			desc:  "update key of an existing thing to the current key",
+			owner: thing.Owner,
+			id:    thing.ID,
+			key:   newKey,
+			err:   nil,
+		},
 		{
 			desc:  "update key of a non-existing thing with existing user",
 			owner: thing.Owner,
@@ -259,7 +269,7 @@ func TestUpdateKey(t *testing.T) {
 	}
 
 	for _, tc := range cases {
-		err := thingRepo.UpdateKey(context.Background(), tc.owner, tc.id, tc.key)
+		err := thingRepo.UpdateKey(context.Background(), tc.owner, tc.id, tc.key, 0)
 		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
 	}
 }
@@ -362,6 +372,174 @@ func TestThingRetrieveByKey(t *testing.T) {
 	}
 }
 
+func TestThingKeyOverlap(t *testing.T) {
+	email := "thing-key-overlap@example.com"
+	dbMiddleware := postgres.NewDatabase(db)
+	thingRepo := postgres.NewThingRepository(dbMiddleware)
+
+	thid, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	thkey, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	overlapKey, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	expiredKey, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	nonexistentThingID, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	thing := things.Thing{
+		ID:    thid,
+		Owner: email,
+		Key:   thkey,
+	}
+	id, _ := thingRepo.Save(context.Background(), thing)
+
+	err = thingRepo.UpdateKey(context.Background(), email, id, overlapKey, time.Hour)
+	assert.Nil(t, err, fmt.Sprintf("update key: got unexpected error: %s", err))
+
+	err = thingRepo.UpdateKey(context.Background(), email, id, expiredKey, 0)
+	assert.Nil(t, err, fmt.Sprintf("update key without overlap period: got unexpected error: %s", err))
+
+	err = thingRepo.UpdateKey(context.Background(), email, nonexistentThingID, wrongValue, time.Hour)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("update key of non-existing thing: expected %s got %s", things.ErrNotFound, err))
+
+	cases := map[string]struct {
+		key string
+		ID  string
+		err error
+	}{
+		"retrieve thing by current key": {
+			key: expiredKey,
+			ID:  id,
+			err: nil,
+		},
+		"retrieve thing by previous key during overlap period": {
+			key: thkey,
+			ID:  id,
+			err: nil,
+		},
+		"retrieve thing by previous key after overlap period": {
+			key: overlapKey,
+			ID:  "",
+			err: things.ErrNotFound,
+		},
+	}
+
+	for desc, tc := range cases {
+		id, err := thingRepo.RetrieveByKey(context.Background(), tc.key)
+		assert.Equal(t, tc.ID, id, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.ID, id))
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
+
+func TestThingHashKeys(t *testing.T) {
+	email := "thing-hash-keys@example.com"
+	dbMiddleware := postgres.NewDatabase(db)
+	thingRepo := postgres.NewThingRepository(dbMiddleware)
+	hasher := hmac.New("secret")
+
+	thid, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	thkey, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	newKey, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	thing := things.Thing{
+		ID:    thid,
+		Owner: email,
+		Key:   thkey,
+	}
+	id, _ := thingRepo.Save(context.Background(), thing)
+
+	err = thingRepo.UpdateKey(context.Background(), email, id, newKey, time.Hour)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	err = thingRepo.HashKeys(context.Background(), hasher)
+	assert.Nil(t, err, fmt.Sprintf("hash keys: got unexpected error: %s", err))
+
+	err = thingRepo.HashKeys(context.Background(), hasher)
+	assert.Nil(t, err, fmt.Sprintf("hash already hashed keys: got unexpected error: %s", err))
+
+	cases := map[string]struct {
+		key string
+		ID  string
+		err error
+	}{
+		"retrieve thing by hashed key": {
+			key: hasher.Hash(newKey),
+			ID:  id,
+			err: nil,
+		},
+		"retrieve thing by hashed previous key": {
+			key: hasher.Hash(thkey),
+			ID:  id,
+			err: nil,
+		},
+		"retrieve thing by plain-text key": {
+			key: newKey,
+			ID:  "",
+			err: things.ErrNotFound,
+		},
+		"retrieve thing by plain-text previous key": {
+			key: thkey,
+			ID:  "",
+			err: things.ErrNotFound,
+		},
+	}
+
+	for desc, tc := range cases {
+		id, err := thingRepo.RetrieveByKey(context.Background(), tc.key)
+		assert.Equal(t, tc.ID, id, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.ID, id))
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
+
+func TestThingRetrieveOwner(t *testing.T) {
+	email := "thing-retrieved-owner@example.com"
+	dbMiddleware := postgres.NewDatabase(db)
+	thingRepo := postgres.NewThingRepository(dbMiddleware)
+
+	thid, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	thkey, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	nonexistentThingID, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	thing := things.Thing{
+		ID:    thid,
+		Owner: email,
+		Key:   thkey,
+	}
+
+	id, _ := thingRepo.Save(context.Background(), thing)
+
+	cases := map[string]struct {
+		ID    string
+		owner string
+		err   error
+	}{
+		"retrieve owner of existing thing": {
+			ID:    id,
+			owner: email,
+			err:   nil,
+		},
+		"retrieve owner of non-existent thing": {
+			ID:    nonexistentThingID,
+			owner: "",
+			err:   things.ErrNotFound,
+		},
+	}
+
+	for desc, tc := range cases {
+		owner, err := thingRepo.RetrieveOwner(context.Background(), tc.ID)
+		assert.Equal(t, tc.owner, owner, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.owner, owner))
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
+
 func TestMultiThingRetrieval(t *testing.T) {
 	email := "thing-multi-retrieval@example.com"
 	name := "mainflux"
@@ -373,6 +551,7 @@ func TestMultiThingRetrieval(t *testing.T) {
 	thingRepo := postgres.NewThingRepository(dbMiddleware)
 
 	n := uint64(10)
+	ids := []string{}
 	for i := uint64(0); i < n; i++ {
 		thid, err := idp.ID()
 		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
@@ -380,10 +559,15 @@ func TestMultiThingRetrieval(t *testing.T) {
 		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
 
 		th := things.Thing{
-			Owner:    email,
-			ID:       thid,
-			Key:      thkey,
-			Metadata: metadata,
+			Owner: email,
+			ID:    thid,
+			Key:   thkey,
+			Metadata: things.Metadata{
+				"serial":   metadata["serial"],
+				"type":     metadata["type"],
+				"level":    i,
+				"firmware": map[string]interface{}{"version": fmt.Sprintf("1.%d.0", i+2)},
+			},
 		}
 
 		// Create first two Things with name.
@@ -392,16 +576,23 @@ func TestMultiThingRetrieval(t *testing.T) {
 		}
 
 		thingRepo.Save(context.Background(), th)
+		ids = append(ids, thid)
 	}
+	sort.Strings(ids)
 
 	cases := map[string]struct {
 		owner    string
 		offset   uint64
 		limit    uint64
+		cursor   string
+		order    string
+		dir      string
+		first    string
 		name     string
 		size     uint64
 		total    uint64
 		metadata map[string]interface{}
+		query    []things.MetadataQuery
 	}{
 		"retrieve all things with existing owner": {
 			owner:  email,
@@ -417,6 +608,53 @@ func TestMultiThingRetrieval(t *testing.T) {
 			size:   n / 2,
 			total:  n,
 		},
+		"retrieve things in descending order with existing owner": {
+			owner:  email,
+			offset: 0,
+			limit:  n,
+			order:  things.OrderByID,
+			dir:    things.DescDir,
+			first:  ids[n-1],
+			size:   n,
+			total:  n,
+		},
+		"retrieve things before cursor in descending order with existing owner": {
+			owner:  email,
+			offset: n,
+			limit:  n,
+			cursor: ids[n/2],
+			order:  things.OrderByID,
+			dir:    things.DescDir,
+			first:  ids[n/2-1],
+			size:   n / 2,
+			total:  n,
+		},
+		"retrieve things ordered by name with existing owner": {
+			owner:  email,
+			offset: 0,
+			limit:  n,
+			order:  things.OrderByName,
+			dir:    things.AscDir,
+			size:   n,
+			total:  n,
+		},
+		"retrieve things ordered by creation time with existing owner": {
+			owner:  email,
+			offset: 0,
+			limit:  n,
+			order:  things.OrderByCreatedAt,
+			dir:    things.DescDir,
+			size:   n,
+			total:  n,
+		},
+		"retrieve things after cursor with existing owner": {
+			owner:  email,
+			offset: n,
+			limit:  n,
+			cursor: ids[n/2-1],
+			size:   n / 2,
+			total:  n,
+		},
 		"retrieve things with non-existing owner": {
 			owner:  wrongValue,
 			offset: 0,
@@ -448,10 +686,158 @@ func TestMultiThingRetrieval(t *testing.T) {
 			total:    n,
 			metadata: metadata,
 		},
+		"retrieve things with version below the query version": {
+			owner:  email,
+			offset: 0,
+			limit:  n,
+			size:   2,
+			total:  n,
+			query:  []things.MetadataQuery{{Path: []string{"firmware", "version"}, Operator: things.LessOp, Value: "1.4.0"}},
+		},
+		"retrieve things with number greater than or equal to the query number": {
+			owner:  email,
+			offset: 0,
+			limit:  n,
+			size:   n / 2,
+			total:  n,
+			query:  []things.MetadataQuery{{Path: []string{"level"}, Operator: things.GreaterEqualOp, Value: "5"}},
+		},
+		"retrieve things with text equal to the query text": {
+			owner:  email,
+			offset: 0,
+			limit:  n,
+			size:   n,
+			total:  n,
+			query:  []things.MetadataQuery{{Path: []string{"type"}, Operator: things.EqualOp, Value: "test"}},
+		},
+		"retrieve things with text not equal to the query text": {
+			owner:  email,
+			offset: 0,
+			limit:  n,
+			size:   0,
+			total:  n,
+			query:  []things.MetadataQuery{{Path: []string{"type"}, Operator: things.NotEqualOp, Value: "test"}},
+		},
+		"retrieve things with number compared to text": {
+			owner:  email,
+			offset: 0,
+			limit:  n,
+			size:   0,
+			total:  n,
+			query:  []things.MetadataQuery{{Path: []string{"type"}, Operator: things.GreaterOp, Value: "1"}},
+		},
+		"retrieve things matching multiple queries": {
+			owner:  email,
+			offset: 0,
+			limit:  n,
+			size:   3,
+			total:  n,
+			query: []things.MetadataQuery{
+				{Path: []string{"level"}, Operator: things.GreaterEqualOp, Value: "5"},
+				{Path: []string{"firmware", "version"}, Operator: things.LessOp, Value: "1.10.0"},
+			},
+		},
 	}
 
 	for desc, tc := range cases {
-		page, err := thingRepo.RetrieveAll(context.Background(), tc.owner, tc.offset, tc.limit, tc.name, tc.metadata)
+		page, err := thingRepo.RetrieveAll(context.Background(), tc.owner, things.PageQuery{
+			Offset:   tc.offset,
+			Limit:    tc.limit,
+			Cursor:   tc.cursor,
+			Name:     tc.name,
+			Order:    tc.order,
+			Dir:      tc.dir,
+			Metadata: tc.metadata,
+			Query:    tc.query,
+		}, nil)
+		size := uint64(len(page.Things))
+		if tc.first != "" && size > 0 {
+			assert.Equal(t, tc.first, page.Things[0].ID, fmt.Sprintf("%s: expected first %s got %s\n", desc, tc.first, page.Things[0].ID))
+		}
+		assert.Equal(t, tc.size, size, fmt.Sprintf("%s: expected %d got %d\n", desc, tc.size, size))
+		assert.Equal(t, tc.total, page.Total, fmt.Sprintf("%s: expected %d got %d\n", desc, tc.total, page.Total))
+		assert.Nil(t, err, fmt.Sprintf("%s: expected no error got %d\n", desc, err))
+	}
+}
+
+func TestThingsSearch(t *testing.T) {
+	dbMiddleware := postgres.NewDatabase(db)
+	thingRepo := postgres.NewThingRepository(dbMiddleware)
+
+	email := "thing-search@example.com"
+	n := uint64(10)
+	for i := uint64(0); i < n; i++ {
+		thid, err := uuid.New().ID()
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+		thkey, err := uuid.New().ID()
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+		th := things.Thing{
+			Owner:    email,
+			ID:       thid,
+			Key:      thkey,
+			Name:     fmt.Sprintf("sensor %d", i),
+			Metadata: map[string]interface{}{"building": "north"},
+		}
+		if i%2 == 0 {
+			th.Metadata = map[string]interface{}{"building": "south"}
+		}
+
+		thingRepo.Save(context.Background(), th)
+	}
+
+	cases := map[string]struct {
+		owner  string
+		query  string
+		offset uint64
+		limit  uint64
+		size   uint64
+		total  uint64
+	}{
+		"search things by name": {
+			owner:  email,
+			query:  "sensor",
+			offset: 0,
+			limit:  n,
+			size:   n,
+			total:  n,
+		},
+		"search things by metadata": {
+			owner:  email,
+			query:  "south",
+			offset: 0,
+			limit:  n,
+			size:   n / 2,
+			total:  n / 2,
+		},
+		"search subset of things by name and metadata": {
+			owner:  email,
+			query:  "sensor north",
+			offset: 1,
+			limit:  n,
+			size:   n/2 - 1,
+			total:  n / 2,
+		},
+		"search things with non-existing owner": {
+			owner:  wrongValue,
+			query:  "sensor",
+			offset: 0,
+			limit:  n,
+			size:   0,
+			total:  0,
+		},
+		"search things without match": {
+			owner:  email,
+			query:  "east",
+			offset: 0,
+			limit:  n,
+			size:   0,
+			total:  0,
+		},
+	}
+
+	for desc, tc := range cases {
+		page, err := thingRepo.Search(context.Background(), tc.owner, tc.query, tc.offset, tc.limit)
 		size := uint64(len(page.Things))
 		assert.Equal(t, tc.size, size, fmt.Sprintf("%s: expected %d got %d\n", desc, tc.size, size))
 		assert.Equal(t, tc.total, page.Total, fmt.Sprintf("%s: expected %d got %d\n", desc, tc.total, page.Total))
@@ -489,7 +875,7 @@ func TestMultiThingRetrievalByChannel(t *testing.T) {
 
 		tid, err := thingRepo.Save(context.Background(), th)
 		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
-		err = channelRepo.Connect(context.Background(), email, cid, tid)
+		err = channelRepo.Connect(context.Background(), email, cid, tid, things.Actions)
 		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 	}
 
@@ -579,3 +965,201 @@ func TestThingRemoval(t *testing.T) {
 		require.Equal(t, things.ErrNotFound, err, fmt.Sprintf("#%d: expected %s got %s", i, things.ErrNotFound, err))
 	}
 }
+
+func TestThingExport(t *testing.T) {
+	email := "thing-export@example.com"
+	dbMiddleware := postgres.NewDatabase(db)
+	thingRepo := postgres.NewThingRepository(dbMiddleware)
+
+	n := 5
+	ids := []string{}
+	for i := 0; i < n; i++ {
+		thid, err := uuid.New().ID()
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+		thkey, err := uuid.New().ID()
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+		id, err := thingRepo.Save(context.Background(), things.Thing{
+			ID:       thid,
+			Owner:    email,
+			Key:      thkey,
+			Metadata: things.Metadata{"index": float64(i)},
+		})
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+		ids = append(ids, id)
+	}
+
+	err := thingRepo.Remove(context.Background(), email, ids[0])
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	exported := []things.Thing{}
+	err = thingRepo.Export(context.Background(), email, func(th things.Thing) error {
+		exported = append(exported, th)
+		return nil
+	})
+	assert.Nil(t, err, fmt.Sprintf("export things: got unexpected error: %s", err))
+	assert.Equal(t, n-1, len(exported), fmt.Sprintf("export things: expected %d got %d", n-1, len(exported)))
+	assert.True(t, sort.SliceIsSorted(exported, func(i, j int) bool { return exported[i].ID < exported[j].ID }), "export things: expected things sorted by ID")
+	for _, th := range exported {
+		assert.NotEqual(t, ids[0], th.ID, "export things: expected removed thing to be skipped")
+		assert.NotEmpty(t, th.Key, "export things: expected thing key to be exported")
+	}
+
+	err = thingRepo.Export(context.Background(), email, func(th things.Thing) error {
+		return things.ErrMalformedEntity
+	})
+	assert.Equal(t, things.ErrMalformedEntity, err, fmt.Sprintf("export things with failing callback: expected %s got %s", things.ErrMalformedEntity, err))
+}
+
+func TestThingSharing(t *testing.T) {
+	email := "thing-sharing@example.com"
+	dbMiddleware := postgres.NewDatabase(db)
+	thingRepo := postgres.NewThingRepository(dbMiddleware)
+
+	thid, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	thkey, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	group, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	otherGroup, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	thing := things.Thing{
+		ID:    thid,
+		Owner: email,
+		Key:   thkey,
+	}
+	_, err = thingRepo.Save(context.Background(), thing)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	_, _, err = thingRepo.RetrieveShared(context.Background(), thing.ID, "member@example.com", []string{group})
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("retrieve not shared thing: expected %s got %s", things.ErrNotFound, err))
+
+	err = thingRepo.Share(context.Background(), "wrong@example.com", thing.ID, group, things.ViewPermission)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("share not owned thing: expected %s got %s", things.ErrNotFound, err))
+
+	err = thingRepo.Share(context.Background(), email, thing.ID, group, things.ViewPermission)
+	assert.Nil(t, err, fmt.Sprintf("share thing: got unexpected error: %s", err))
+	err = thingRepo.Share(context.Background(), email, thing.ID, otherGroup, things.EditPermission)
+	assert.Nil(t, err, fmt.Sprintf("share thing: got unexpected error: %s", err))
+
+	cases := map[string]struct {
+		groups     []string
+		permission string
+		err        error
+	}{
+		"retrieve thing shared with group": {
+			groups:     []string{group},
+			permission: things.ViewPermission,
+			err:        nil,
+		},
+		"retrieve thing shared with multiple groups": {
+			groups:     []string{group, otherGroup},
+			permission: things.EditPermission,
+			err:        nil,
+		},
+		"retrieve thing with invalid group": {
+			groups: []string{"invalid"},
+			err:    things.ErrNotFound,
+		},
+	}
+
+	for desc, tc := range cases {
+		th, permission, err := thingRepo.RetrieveShared(context.Background(), thing.ID, "member@example.com", tc.groups)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s", desc, tc.err, err))
+		assert.Equal(t, tc.permission, permission, fmt.Sprintf("%s: expected permission %s got %s", desc, tc.permission, permission))
+		if err == nil {
+			assert.Equal(t, email, th.Owner, fmt.Sprintf("%s: expected owner %s got %s", desc, email, th.Owner))
+		}
+	}
+
+	page, err := thingRepo.RetrieveAll(context.Background(), "member@example.com", things.PageQuery{Limit: 10, Shared: true}, []string{group})
+	assert.Nil(t, err, fmt.Sprintf("retrieve shared things: got unexpected error: %s", err))
+	assert.Equal(t, uint64(1), page.Total, fmt.Sprintf("retrieve shared things: expected total %d got %d", 1, page.Total))
+
+	// show that unsharing works the same for both shared and non-shared thing
+	for i := 0; i < 2; i++ {
+		err := thingRepo.Unshare(context.Background(), email, thing.ID, group)
+		require.Nil(t, err, fmt.Sprintf("#%d: failed to unshare thing due to: %s", i, err))
+
+		_, _, err = thingRepo.RetrieveShared(context.Background(), thing.ID, "member@example.com", []string{group})
+		require.Equal(t, things.ErrNotFound, err, fmt.Sprintf("#%d: expected %s got %s", i, things.ErrNotFound, err))
+	}
+}
+
+func TestThingUserSharing(t *testing.T) {
+	email := "thing-user-sharing@example.com"
+	member := "thing-member@example.com"
+	dbMiddleware := postgres.NewDatabase(db)
+	thingRepo := postgres.NewThingRepository(dbMiddleware)
+
+	thid, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	thkey, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	group, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	thing := things.Thing{
+		ID:    thid,
+		Owner: email,
+		Key:   thkey,
+	}
+	_, err = thingRepo.Save(context.Background(), thing)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	err = thingRepo.ShareWithUser(context.Background(), "wrong@example.com", thing.ID, member, things.ViewPermission)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("share not owned thing: expected %s got %s", things.ErrNotFound, err))
+
+	err = thingRepo.ShareWithUser(context.Background(), email, thing.ID, member, things.ViewPermission)
+	assert.Nil(t, err, fmt.Sprintf("share thing with user: got unexpected error: %s", err))
+	err = thingRepo.Share(context.Background(), email, thing.ID, group, things.EditPermission)
+	assert.Nil(t, err, fmt.Sprintf("share thing with group: got unexpected error: %s", err))
+
+	cases := []struct {
+		desc       string
+		email      string
+		groups     []string
+		permission string
+		err        error
+	}{
+		{
+			desc:       "retrieve thing shared with user",
+			email:      member,
+			groups:     nil,
+			permission: things.ViewPermission,
+			err:        nil,
+		},
+		{
+			desc:       "retrieve thing shared with user and user's group",
+			email:      member,
+			groups:     []string{group},
+			permission: things.EditPermission,
+			err:        nil,
+		},
+		{
+			desc:       "retrieve thing not shared with user",
+			email:      "other@example.com",
+			groups:     nil,
+			permission: "",
+			err:        things.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		_, permission, err := thingRepo.RetrieveShared(context.Background(), thing.ID, tc.email, tc.groups)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+		assert.Equal(t, tc.permission, permission, fmt.Sprintf("%s: expected permission %s got %s", tc.desc, tc.permission, permission))
+	}
+
+	page, err := thingRepo.RetrieveAll(context.Background(), member, things.PageQuery{Limit: 10, Shared: true}, nil)
+	assert.Nil(t, err, fmt.Sprintf("retrieve things shared with user: got unexpected error: %s", err))
+	assert.Equal(t, uint64(1), page.Total, fmt.Sprintf("retrieve things shared with user: expected total %d got %d", 1, page.Total))
+
+	err = thingRepo.UnshareWithUser(context.Background(), email, thing.ID, member)
+	assert.Nil(t, err, fmt.Sprintf("unshare thing with user: got unexpected error: %s", err))
+
+	_, _, err = thingRepo.RetrieveShared(context.Background(), thing.ID, member, nil)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("retrieve thing unshared with user: expected %s got %s", things.ErrNotFound, err))
+}
@@ -0,0 +1,69 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package things
+
+import (
+	"context"
+	"errors"
+	"math"
+)
+
+// maxLimit is the highest limit the repositories are able to store.
+const maxLimit = math.MaxInt64
+
+// ErrQuotaExceeded indicates that the owner has reached the maximum number
+// of entities allowed by their quota.
+var ErrQuotaExceeded = errors.New("quota exceeded")
+
+// Quota limits the number of things, channels and connections the owner may
+// have. Zero limit means that the number of entities is not limited.
+type Quota struct {
+	Owner       string
+	Things      uint64
+	Channels    uint64
+	Connections uint64
+}
+
+// Usage contains the number of things, channels and connections the owner
+// has.
+type Usage struct {
+	Things      uint64
+	Channels    uint64
+	Connections uint64
+}
+
+// QuotaRepository specifies a quota persistence API.
+type QuotaRepository interface {
+	// Save persists the quota, overriding the default quota of its owner.
+	// Saving the quota of the owner that already has one replaces it.
+	Save(context.Context, Quota) error
+
+	// RetrieveByOwner retrieves the quota of the owner. If the default quota
+	// isn't overridden for the owner, ErrNotFound is returned.
+	RetrieveByOwner(context.Context, string) (Quota, error)
+
+	// Remove removes the quota of the owner, restoring the default quota.
+	Remove(context.Context, string) error
+
+	// RetrieveUsage counts the things, channels and connections between
+	// them that belong to the owner. Removed entities are not counted.
+	RetrieveUsage(context.Context, string) (Usage, error)
+}
+
+// valid checks if the quota can be persisted.
+func (q Quota) valid() bool {
+	return q.Owner != "" && q.Things <= maxLimit && q.Channels <= maxLimit && q.Connections <= maxLimit
+}
+
+// limited checks if adding the provided number of entities is limited.
+func limited(limit, added uint64) bool {
+	return limit > 0 && added > 0
+}
+
+// exceeds checks if adding the provided number of entities to the used ones
+// exceeds the limit. Owners above the limit, e.g. after the quota has been
+// lowered, can still add entities of other kinds.
+func exceeds(limit, used, added uint64) bool {
+	return limited(limit, added) && used+added > limit
+}
@@ -0,0 +1,142 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package redis
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/go-redis/redis"
+	"github.com/mainflux/mainflux/things"
+)
+
+// changesBatch is the number of events scanned at once while looking for the
+// changes of the owner.
+const changesBatch = 100
+
+var _ things.ChangeLog = (*changeLog)(nil)
+
+type changeLog struct {
+	client redis.UniversalClient
+}
+
+// NewChangeLog returns change log backed by the things event stream. Since
+// the stream is capped, only the most recent changes are retained.
+func NewChangeLog(client redis.UniversalClient) things.ChangeLog {
+	return changeLog{client: client}
+}
+
+func (cl changeLog) RetrieveAll(_ context.Context, owner, since string, limit uint64) (things.ChangesPage, error) {
+	page := things.ChangesPage{
+		Changes: []things.Change{},
+		Next:    since,
+	}
+
+	start := "-"
+	if since != "" {
+		expired, err := cl.expired(since)
+		if err != nil {
+			return things.ChangesPage{}, err
+		}
+		if expired {
+			return things.ChangesPage{}, things.ErrChangesExpired
+		}
+		start = since
+	}
+
+	for uint64(len(page.Changes)) < limit {
+		msgs, err := cl.client.XRangeN(streamID, start, "+", changesBatch).Result()
+		if err != nil {
+			return things.ChangesPage{}, err
+		}
+
+		for _, msg := range msgs {
+			// Range start is inclusive, while the changes are retrieved
+			// after the provided position.
+			if msg.ID == since {
+				continue
+			}
+
+			page.Next = msg.ID
+			if msg.Values["owner"] != owner {
+				continue
+			}
+
+			page.Changes = append(page.Changes, toChange(msg))
+			if uint64(len(page.Changes)) == limit {
+				return page, nil
+			}
+		}
+
+		if len(msgs) < changesBatch {
+			break
+		}
+		start = page.Next
+		since = page.Next
+	}
+
+	return page, nil
+}
+
+// expired checks whether the event at the provided position was trimmed from
+// the stream, in which case the events following it might be trimmed too.
+func (cl changeLog) expired(since string) (bool, error) {
+	pos, ok := parseID(since)
+	if !ok {
+		return false, things.ErrMalformedEntity
+	}
+
+	msgs, err := cl.client.XRangeN(streamID, "-", "+", 1).Result()
+	if err != nil {
+		return false, err
+	}
+	if len(msgs) == 0 {
+		return false, nil
+	}
+
+	oldest, _ := parseID(msgs[0].ID)
+	return pos[0] < oldest[0] || (pos[0] == oldest[0] && pos[1] < oldest[1]), nil
+}
+
+// parseID parses the stream entry ID consisting of the milliseconds time and
+// the sequence number.
+func parseID(id string) ([2]uint64, bool) {
+	var pos [2]uint64
+
+	parts := strings.Split(id, "-")
+	if len(parts) != 2 {
+		return pos, false
+	}
+
+	for i, part := range parts {
+		n, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return pos, false
+		}
+		pos[i] = n
+	}
+
+	return pos, true
+}
+
+func toChange(msg redis.XMessage) things.Change {
+	change := things.Change{
+		ID:     msg.ID,
+		Fields: make(map[string]string),
+	}
+
+	for k, v := range msg.Values {
+		val, _ := v.(string)
+		switch k {
+		case "owner":
+		case "operation":
+			change.Operation = val
+		default:
+			change.Fields[k] = val
+		}
+	}
+
+	return change
+}
@@ -0,0 +1,110 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package redis_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux/things"
+	"github.com/mainflux/mainflux/things/redis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangesRetrieveAll(t *testing.T) {
+	redisClient.FlushAll().Err()
+
+	otherToken := "other-token"
+	svc := newService(map[string]string{token: email, otherToken: "other@example.com"})
+	svc = redis.NewEventStoreMiddleware(svc, redisClient)
+	changeLog := redis.NewChangeLog(redisClient)
+
+	sth, err := svc.AddThing(context.Background(), token, things.Thing{Name: "a"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	_, err = svc.AddThing(context.Background(), otherToken, things.Thing{Name: "b"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = svc.RemoveThing(context.Background(), token, sth.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	all, err := changeLog.RetrieveAll(context.Background(), email, "", 10)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	require.Len(t, all.Changes, 2, "expected changes of the owner")
+	assert.Equal(t, thingCreate, all.Changes[0].Operation, fmt.Sprintf("expected %s got %s", thingCreate, all.Changes[0].Operation))
+	assert.Equal(t, thingRemove, all.Changes[1].Operation, fmt.Sprintf("expected %s got %s", thingRemove, all.Changes[1].Operation))
+	assert.Equal(t, sth.ID, all.Changes[1].Fields["id"], fmt.Sprintf("expected %s got %s", sth.ID, all.Changes[1].Fields["id"]))
+
+	cases := []struct {
+		desc  string
+		owner string
+		since string
+		limit uint64
+		size  int
+		next  string
+		err   error
+	}{
+		{
+			desc:  "retrieve changes since position",
+			owner: email,
+			since: all.Changes[0].ID,
+			limit: 10,
+			size:  1,
+			next:  all.Changes[1].ID,
+			err:   nil,
+		},
+		{
+			desc:  "retrieve limited changes",
+			owner: email,
+			since: "",
+			limit: 1,
+			size:  1,
+			next:  all.Changes[0].ID,
+			err:   nil,
+		},
+		{
+			desc:  "retrieve changes since last position",
+			owner: email,
+			since: all.Next,
+			limit: 10,
+			size:  0,
+			next:  all.Next,
+			err:   nil,
+		},
+		{
+			desc:  "retrieve changes of owner without changes",
+			owner: "unknown@example.com",
+			since: "",
+			limit: 10,
+			size:  0,
+			next:  all.Next,
+			err:   nil,
+		},
+		{
+			desc:  "retrieve changes since expired position",
+			owner: email,
+			since: "0-1",
+			limit: 10,
+			size:  0,
+			next:  "",
+			err:   things.ErrChangesExpired,
+		},
+		{
+			desc:  "retrieve changes since malformed position",
+			owner: email,
+			since: wrongValue,
+			limit: 10,
+			size:  0,
+			next:  "",
+			err:   things.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		page, err := changeLog.RetrieveAll(context.Background(), tc.owner, tc.since, tc.limit)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Len(t, page.Changes, tc.size, fmt.Sprintf("%s: expected %d changes got %d\n", tc.desc, tc.size, len(page.Changes)))
+		assert.Equal(t, tc.next, page.Next, fmt.Sprintf("%s: expected next %s got %s\n", tc.desc, tc.next, page.Next))
+	}
+}
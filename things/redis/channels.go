@@ -16,36 +16,43 @@ const chanPrefix = "channel"
 var _ things.ChannelCache = (*channelCache)(nil)
 
 type channelCache struct {
-	client *redis.Client
+	client redis.UniversalClient
 }
 
 // NewChannelCache returns redis channel cache implementation.
-func NewChannelCache(client *redis.Client) things.ChannelCache {
+func NewChannelCache(client redis.UniversalClient) things.ChannelCache {
 	return channelCache{client: client}
 }
 
-func (cc channelCache) Connect(_ context.Context, chanID, thingID string) error {
-	cid, tid := kv(chanID, thingID)
-	return cc.client.SAdd(cid, tid).Err()
+func (cc channelCache) Connect(_ context.Context, chanID, thingID, action string) error {
+	return cc.client.SAdd(key(chanID, action), thingID).Err()
 }
 
-func (cc channelCache) HasThing(_ context.Context, chanID, thingID string) bool {
-	cid, tid := kv(chanID, thingID)
-	return cc.client.SIsMember(cid, tid).Val()
+func (cc channelCache) HasThing(_ context.Context, chanID, thingID, action string) bool {
+	return cc.client.SIsMember(key(chanID, action), thingID).Val()
 }
 
 func (cc channelCache) Disconnect(_ context.Context, chanID, thingID string) error {
-	cid, tid := kv(chanID, thingID)
-	return cc.client.SRem(cid, tid).Err()
+	_, err := cc.client.TxPipelined(func(pipe redis.Pipeliner) error {
+		for _, action := range things.Actions {
+			pipe.SRem(key(chanID, action), thingID)
+		}
+		return nil
+	})
+	return err
 }
 
 func (cc channelCache) Remove(_ context.Context, chanID string) error {
-	cid, _ := kv(chanID, "0")
-	return cc.client.Del(cid).Err()
+	keys := []string{}
+	for _, action := range things.Actions {
+		keys = append(keys, key(chanID, action))
+	}
+
+	return cc.client.Del(keys...).Err()
 }
 
-// Generates key-value pair
-func kv(chanID, thingID string) (string, string) {
-	cid := fmt.Sprintf("%s:%s", chanPrefix, chanID)
-	return cid, thingID
+// Generates key of the set containing things allowed to perform the action
+// on the channel.
+func key(chanID, action string) string {
+	return fmt.Sprintf("%s:%s:%s", chanPrefix, chanID, action)
 }
@@ -1,12 +1,22 @@
 package redis
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"strings"
+)
+
+// eventVersion is the version of the event schema, carried by every event in
+// the version field. It is incremented whenever the existing fields are
+// changed or removed, while the new fields are added without changing the
+// version, so the consumers should ignore the fields they don't know.
+const eventVersion = "1"
 
 const (
 	thingPrefix     = "thing."
 	thingCreate     = thingPrefix + "create"
 	thingUpdate     = thingPrefix + "update"
 	thingRemove     = thingPrefix + "remove"
+	thingUpdateKey  = thingPrefix + "update_key"
 	thingConnect    = thingPrefix + "connect"
 	thingDisconnect = thingPrefix + "disconnect"
 
@@ -14,6 +24,8 @@ const (
 	channelCreate = channelPrefix + "create"
 	channelUpdate = channelPrefix + "update"
 	channelRemove = channelPrefix + "remove"
+
+	ownerTransfer = "owner.transfer"
 )
 
 type event interface {
@@ -24,11 +36,13 @@ var (
 	_ event = (*createThingEvent)(nil)
 	_ event = (*updateThingEvent)(nil)
 	_ event = (*removeThingEvent)(nil)
+	_ event = (*updateKeyEvent)(nil)
 	_ event = (*createChannelEvent)(nil)
 	_ event = (*updateChannelEvent)(nil)
 	_ event = (*removeChannelEvent)(nil)
 	_ event = (*connectThingEvent)(nil)
 	_ event = (*disconnectThingEvent)(nil)
+	_ event = (*transferOwnershipEvent)(nil)
 )
 
 type createThingEvent struct {
@@ -43,6 +57,7 @@ func (cte createThingEvent) Encode() map[string]interface{} {
 		"id":        cte.id,
 		"owner":     cte.owner,
 		"operation": thingCreate,
+		"version":   eventVersion,
 	}
 
 	if cte.name != "" {
@@ -63,6 +78,7 @@ func (cte createThingEvent) Encode() map[string]interface{} {
 
 type updateThingEvent struct {
 	id       string
+	owner    string
 	name     string
 	metadata map[string]interface{}
 }
@@ -70,7 +86,9 @@ type updateThingEvent struct {
 func (ute updateThingEvent) Encode() map[string]interface{} {
 	val := map[string]interface{}{
 		"id":        ute.id,
+		"owner":     ute.owner,
 		"operation": thingUpdate,
+		"version":   eventVersion,
 	}
 
 	if ute.name != "" {
@@ -90,13 +108,31 @@ func (ute updateThingEvent) Encode() map[string]interface{} {
 }
 
 type removeThingEvent struct {
-	id string
+	id    string
+	owner string
 }
 
 func (rte removeThingEvent) Encode() map[string]interface{} {
 	return map[string]interface{}{
 		"id":        rte.id,
+		"owner":     rte.owner,
 		"operation": thingRemove,
+		"version":   eventVersion,
+	}
+}
+
+// updateKeyEvent announces the changed thing key, without the key itself.
+type updateKeyEvent struct {
+	id    string
+	owner string
+}
+
+func (uke updateKeyEvent) Encode() map[string]interface{} {
+	return map[string]interface{}{
+		"id":        uke.id,
+		"owner":     uke.owner,
+		"operation": thingUpdateKey,
+		"version":   eventVersion,
 	}
 }
 
@@ -112,6 +148,7 @@ func (cce createChannelEvent) Encode() map[string]interface{} {
 		"id":        cce.id,
 		"owner":     cce.owner,
 		"operation": channelCreate,
+		"version":   eventVersion,
 	}
 
 	if cce.name != "" {
@@ -132,6 +169,7 @@ func (cce createChannelEvent) Encode() map[string]interface{} {
 
 type updateChannelEvent struct {
 	id       string
+	owner    string
 	name     string
 	metadata map[string]interface{}
 }
@@ -139,7 +177,9 @@ type updateChannelEvent struct {
 func (uce updateChannelEvent) Encode() map[string]interface{} {
 	val := map[string]interface{}{
 		"id":        uce.id,
+		"owner":     uce.owner,
 		"operation": channelUpdate,
+		"version":   eventVersion,
 	}
 
 	if uce.name != "" {
@@ -159,38 +199,65 @@ func (uce updateChannelEvent) Encode() map[string]interface{} {
 }
 
 type removeChannelEvent struct {
-	id string
+	id    string
+	owner string
 }
 
 func (rce removeChannelEvent) Encode() map[string]interface{} {
 	return map[string]interface{}{
 		"id":        rce.id,
+		"owner":     rce.owner,
 		"operation": channelRemove,
+		"version":   eventVersion,
 	}
 }
 
 type connectThingEvent struct {
 	chanID  string
 	thingID string
+	owner   string
+	actions []string
 }
 
 func (cte connectThingEvent) Encode() map[string]interface{} {
 	return map[string]interface{}{
 		"chan_id":   cte.chanID,
 		"thing_id":  cte.thingID,
+		"owner":     cte.owner,
+		"actions":   strings.Join(cte.actions, ","),
 		"operation": thingConnect,
+		"version":   eventVersion,
 	}
 }
 
 type disconnectThingEvent struct {
 	chanID  string
 	thingID string
+	owner   string
 }
 
 func (dte disconnectThingEvent) Encode() map[string]interface{} {
 	return map[string]interface{}{
 		"chan_id":   dte.chanID,
 		"thing_id":  dte.thingID,
+		"owner":     dte.owner,
 		"operation": thingDisconnect,
+		"version":   eventVersion,
+	}
+}
+
+// transferOwnershipEvent announces that all the things and channels of the
+// previous owner, along with their connections, belong to the new owner.
+type transferOwnershipEvent struct {
+	from string
+	to   string
+}
+
+func (toe transferOwnershipEvent) Encode() map[string]interface{} {
+	return map[string]interface{}{
+		"owner":          toe.to,
+		"previous_owner": toe.from,
+		"operation":      ownerTransfer,
+		"version":        eventVersion,
 	}
 }
@@ -5,6 +5,7 @@ package redis
 
 import (
 	"context"
+	"time"
 
 	"github.com/go-redis/redis"
 	"github.com/mainflux/mainflux/things"
@@ -19,12 +20,12 @@ var _ things.Service = (*eventStore)(nil)
 
 type eventStore struct {
 	svc    things.Service
-	client *redis.Client
+	client redis.UniversalClient
 }
 
 // NewEventStoreMiddleware returns wrapper around things service that sends
 // events to event store.
-func NewEventStoreMiddleware(svc things.Service, client *redis.Client) things.Service {
+func NewEventStoreMiddleware(svc things.Service, client redis.UniversalClient) things.Service {
 	return eventStore{
 		svc:    svc,
 		client: client,
@@ -60,6 +61,7 @@ func (es eventStore) UpdateThing(ctx context.Context, token string, thing things
 
 	event := updateThingEvent{
 		id:       thing.ID,
+		owner:    es.thingOwner(ctx, token, thing.ID),
 		name:     thing.Name,
 		metadata: thing.Metadata,
 	}
@@ -73,19 +75,38 @@ func (es eventStore) UpdateThing(ctx context.Context, token string, thing things
 	return nil
 }
 
-// UpdateKey doesn't send event because key shouldn't be sent over stream.
-// Maybe we can start publishing this event at some point, without key value
-// in order to notify adapters to disconnect connected things after key update.
+// UpdateKey announces the key update without the key value, so that the
+// adapters drop the accesses granted using the old key.
 func (es eventStore) UpdateKey(ctx context.Context, token, id, key string) error {
-	return es.svc.UpdateKey(ctx, token, id, key)
+	if err := es.svc.UpdateKey(ctx, token, id, key); err != nil {
+		return err
+	}
+
+	es.updateKey(ctx, token, id)
+	return nil
+}
+
+// RotateKey announces the rotated key the same way as UpdateKey.
+func (es eventStore) RotateKey(ctx context.Context, token, id string) (string, error) {
+	key, err := es.svc.RotateKey(ctx, token, id)
+	if err != nil {
+		return key, err
+	}
+
+	es.updateKey(ctx, token, id)
+	return key, nil
 }
 
 func (es eventStore) ViewThing(ctx context.Context, token, id string) (things.Thing, error) {
 	return es.svc.ViewThing(ctx, token, id)
 }
 
-func (es eventStore) ListThings(ctx context.Context, token string, offset, limit uint64, name string, metadata things.Metadata) (things.ThingsPage, error) {
-	return es.svc.ListThings(ctx, token, offset, limit, name, metadata)
+func (es eventStore) ListThings(ctx context.Context, token string, pq things.PageQuery) (things.ThingsPage, error) {
+	return es.svc.ListThings(ctx, token, pq)
+}
+
+func (es eventStore) SearchThings(ctx context.Context, token, query string, offset, limit uint64) (things.ThingsPage, error) {
+	return es.svc.SearchThings(ctx, token, query, offset, limit)
 }
 
 func (es eventStore) ListThingsByChannel(ctx context.Context, token, id string, offset, limit uint64) (things.ThingsPage, error) {
@@ -93,12 +114,42 @@ func (es eventStore) ListThingsByChannel(ctx context.Context, token, id string,
 }
 
 func (es eventStore) RemoveThing(ctx context.Context, token, id string) error {
+	owner := es.thingOwner(ctx, token, id)
 	if err := es.svc.RemoveThing(ctx, token, id); err != nil {
 		return err
 	}
 
 	event := removeThingEvent{
-		id: id,
+		id:    id,
+		owner: owner,
+	}
+	record := &redis.XAddArgs{
+		Stream:       streamID,
+		MaxLenApprox: streamLen,
+		Values:       event.Encode(),
+	}
+	es.client.XAdd(record).Err()
+
+	return nil
+}
+
+// RestoreThing announces the restored thing as a newly created one, so that
+// the consumers which dropped it on removal can rebuild their state.
+func (es eventStore) RestoreThing(ctx context.Context, token, id string) error {
+	if err := es.svc.RestoreThing(ctx, token, id); err != nil {
+		return err
+	}
+
+	th, err := es.svc.ViewThing(ctx, token, id)
+	if err != nil {
+		return nil
+	}
+
+	event := createThingEvent{
+		id:       th.ID,
+		owner:    th.Owner,
+		name:     th.Name,
+		metadata: th.Metadata,
 	}
 	record := &redis.XAddArgs{
 		Stream:       streamID,
@@ -110,6 +161,51 @@ func (es eventStore) RemoveThing(ctx context.Context, token, id string) error {
 	return nil
 }
 
+func (es eventStore) PurgeThing(ctx context.Context, token, id string) error {
+	return es.svc.PurgeThing(ctx, token, id)
+}
+
+func (es eventStore) ShareThing(ctx context.Context, token, id, group, permission string) error {
+	return es.svc.ShareThing(ctx, token, id, group, permission)
+}
+
+func (es eventStore) UnshareThing(ctx context.Context, token, id, group string) error {
+	return es.svc.UnshareThing(ctx, token, id, group)
+}
+
+func (es eventStore) ShareThingWithUser(ctx context.Context, token, id, email, permission string) error {
+	return es.svc.ShareThingWithUser(ctx, token, id, email, permission)
+}
+
+func (es eventStore) UnshareThingWithUser(ctx context.Context, token, id, email string) error {
+	return es.svc.UnshareThingWithUser(ctx, token, id, email)
+}
+
+func (es eventStore) ExportThings(ctx context.Context, token string, fn func(things.Thing) error) error {
+	return es.svc.ExportThings(ctx, token, fn)
+}
+
+func (es eventStore) ImportThings(ctx context.Context, token string, ths []things.Thing) ([]things.Thing, error) {
+	imported, err := es.svc.ImportThings(ctx, token, ths)
+
+	for _, th := range imported {
+		event := createThingEvent{
+			id:       th.ID,
+			owner:    th.Owner,
+			name:     th.Name,
+			metadata: th.Metadata,
+		}
+		record := &redis.XAddArgs{
+			Stream:       streamID,
+			MaxLenApprox: streamLen,
+			Values:       event.Encode(),
+		}
+		es.client.XAdd(record).Err()
+	}
+
+	return imported, err
+}
+
 func (es eventStore) CreateChannel(ctx context.Context, token string, channel things.Channel) (things.Channel, error) {
 	sch, err := es.svc.CreateChannel(ctx, token, channel)
 	if err != nil {
@@ -132,6 +228,44 @@ func (es eventStore) CreateChannel(ctx context.Context, token string, channel th
 	return sch, err
 }
 
+// CreateDirectChannel publishes the channel creation and the connections of
+// both peers even if the things were already paired, since the same
+// channel is returned in that case.
+func (es eventStore) CreateDirectChannel(ctx context.Context, token, thing1, thing2 string) (things.Channel, error) {
+	sch, err := es.svc.CreateDirectChannel(ctx, token, thing1, thing2)
+	if err != nil {
+		return sch, err
+	}
+
+	events := []event{
+		createChannelEvent{
+			id:       sch.ID,
+			owner:    sch.Owner,
+			name:     sch.Name,
+			metadata: sch.Metadata,
+		},
+	}
+	for _, peer := range sch.Peers {
+		events = append(events, connectThingEvent{
+			chanID:  sch.ID,
+			thingID: peer,
+			owner:   sch.Owner,
+			actions: things.Actions,
+		})
+	}
+
+	for _, e := range events {
+		record := &redis.XAddArgs{
+			Stream:       streamID,
+			MaxLenApprox: streamLen,
+			Values:       e.Encode(),
+		}
+		es.client.XAdd(record).Err()
+	}
+
+	return sch, nil
+}
+
 func (es eventStore) UpdateChannel(ctx context.Context, token string, channel things.Channel) error {
 	if err := es.svc.UpdateChannel(ctx, token, channel); err != nil {
 		return err
@@ -139,6 +273,7 @@ func (es eventStore) UpdateChannel(ctx context.Context, token string, channel th
 
 	event := updateChannelEvent{
 		id:       channel.ID,
+		owner:    es.channelOwner(ctx, token, channel.ID),
 		name:     channel.Name,
 		metadata: channel.Metadata,
 	}
@@ -156,8 +291,12 @@ func (es eventStore) ViewChannel(ctx context.Context, token, id string) (things.
 	return es.svc.ViewChannel(ctx, token, id)
 }
 
-func (es eventStore) ListChannels(ctx context.Context, token string, offset, limit uint64, name string, metadata things.Metadata) (things.ChannelsPage, error) {
-	return es.svc.ListChannels(ctx, token, offset, limit, name, metadata)
+func (es eventStore) ListChannels(ctx context.Context, token string, pq things.PageQuery) (things.ChannelsPage, error) {
+	return es.svc.ListChannels(ctx, token, pq)
+}
+
+func (es eventStore) SearchChannels(ctx context.Context, token, query string, offset, limit uint64) (things.ChannelsPage, error) {
+	return es.svc.SearchChannels(ctx, token, query, offset, limit)
 }
 
 func (es eventStore) ListChannelsByThing(ctx context.Context, token, id string, offset, limit uint64) (things.ChannelsPage, error) {
@@ -165,12 +304,14 @@ func (es eventStore) ListChannelsByThing(ctx context.Context, token, id string,
 }
 
 func (es eventStore) RemoveChannel(ctx context.Context, token, id string) error {
+	owner := es.channelOwner(ctx, token, id)
 	if err := es.svc.RemoveChannel(ctx, token, id); err != nil {
 		return err
 	}
 
 	event := removeChannelEvent{
-		id: id,
+		id:    id,
+		owner: owner,
 	}
 	record := &redis.XAddArgs{
 		Stream:       streamID,
@@ -182,14 +323,93 @@ func (es eventStore) RemoveChannel(ctx context.Context, token, id string) error
 	return nil
 }
 
-func (es eventStore) Connect(ctx context.Context, token, chanID, thingID string) error {
-	if err := es.svc.Connect(ctx, token, chanID, thingID); err != nil {
+// RestoreChannel announces the restored channel as a newly created one, so
+// that the consumers which dropped it on removal can rebuild their state.
+func (es eventStore) RestoreChannel(ctx context.Context, token, id string) error {
+	if err := es.svc.RestoreChannel(ctx, token, id); err != nil {
 		return err
 	}
 
+	ch, err := es.svc.ViewChannel(ctx, token, id)
+	if err != nil {
+		return nil
+	}
+
+	event := createChannelEvent{
+		id:       ch.ID,
+		owner:    ch.Owner,
+		name:     ch.Name,
+		metadata: ch.Metadata,
+	}
+	record := &redis.XAddArgs{
+		Stream:       streamID,
+		MaxLenApprox: streamLen,
+		Values:       event.Encode(),
+	}
+	es.client.XAdd(record).Err()
+
+	return nil
+}
+
+func (es eventStore) PurgeChannel(ctx context.Context, token, id string) error {
+	return es.svc.PurgeChannel(ctx, token, id)
+}
+
+func (es eventStore) ShareChannel(ctx context.Context, token, id, group, permission string) error {
+	return es.svc.ShareChannel(ctx, token, id, group, permission)
+}
+
+func (es eventStore) UnshareChannel(ctx context.Context, token, id, group string) error {
+	return es.svc.UnshareChannel(ctx, token, id, group)
+}
+
+func (es eventStore) ShareChannelWithUser(ctx context.Context, token, id, email, permission string) error {
+	return es.svc.ShareChannelWithUser(ctx, token, id, email, permission)
+}
+
+func (es eventStore) UnshareChannelWithUser(ctx context.Context, token, id, email string) error {
+	return es.svc.UnshareChannelWithUser(ctx, token, id, email)
+}
+
+func (es eventStore) ExportChannels(ctx context.Context, token string, fn func(things.Channel) error) error {
+	return es.svc.ExportChannels(ctx, token, fn)
+}
+
+func (es eventStore) ImportChannels(ctx context.Context, token string, chs []things.Channel) ([]things.Channel, error) {
+	imported, err := es.svc.ImportChannels(ctx, token, chs)
+
+	for _, ch := range imported {
+		event := createChannelEvent{
+			id:       ch.ID,
+			owner:    ch.Owner,
+			name:     ch.Name,
+			metadata: ch.Metadata,
+		}
+		record := &redis.XAddArgs{
+			Stream:       streamID,
+			MaxLenApprox: streamLen,
+			Values:       event.Encode(),
+		}
+		es.client.XAdd(record).Err()
+	}
+
+	return imported, err
+}
+
+func (es eventStore) Connect(ctx context.Context, token, chanID, thingID string, actions []string) error {
+	if err := es.svc.Connect(ctx, token, chanID, thingID, actions); err != nil {
+		return err
+	}
+
+	if len(actions) == 0 {
+		actions = things.Actions
+	}
+
 	event := connectThingEvent{
 		chanID:  chanID,
 		thingID: thingID,
+		owner:   es.channelOwner(ctx, token, chanID),
+		actions: actions,
 	}
 	record := &redis.XAddArgs{
 		Stream:       streamID,
@@ -209,6 +429,7 @@ func (es eventStore) Disconnect(ctx context.Context, token, chanID, thingID stri
 	event := disconnectThingEvent{
 		chanID:  chanID,
 		thingID: thingID,
+		owner:   es.channelOwner(ctx, token, chanID),
 	}
 	record := &redis.XAddArgs{
 		Stream:       streamID,
@@ -220,14 +441,183 @@ func (es eventStore) Disconnect(ctx context.Context, token, chanID, thingID stri
 	return nil
 }
 
-func (es eventStore) CanAccess(ctx context.Context, chanID string, key string) (string, error) {
-	return es.svc.CanAccess(ctx, chanID, key)
+func (es eventStore) ExportConnections(ctx context.Context, token string, fn func(things.Connection) error) error {
+	return es.svc.ExportConnections(ctx, token, fn)
+}
+
+func (es eventStore) ImportConnections(ctx context.Context, token string, conns []things.Connection) ([]things.Connection, error) {
+	imported, err := es.svc.ImportConnections(ctx, token, conns)
+
+	owners := make(map[string]string)
+	for _, conn := range imported {
+		owner, ok := owners[conn.ChannelID]
+		if !ok {
+			owner = es.channelOwner(ctx, token, conn.ChannelID)
+			owners[conn.ChannelID] = owner
+		}
+
+		event := connectThingEvent{
+			chanID:  conn.ChannelID,
+			thingID: conn.ThingID,
+			owner:   owner,
+			actions: conn.Actions,
+		}
+		record := &redis.XAddArgs{
+			Stream:       streamID,
+			MaxLenApprox: streamLen,
+			Values:       event.Encode(),
+		}
+		es.client.XAdd(record).Err()
+	}
+
+	return imported, err
+}
+
+func (es eventStore) CanAccess(ctx context.Context, chanID, key, action string) (string, error) {
+	return es.svc.CanAccess(ctx, chanID, key, action)
 }
 
-func (es eventStore) CanAccessByID(ctx context.Context, chanID string, thingID string) error {
-	return es.svc.CanAccessByID(ctx, chanID, thingID)
+func (es eventStore) CanAccessByID(ctx context.Context, chanID, thingID, action string) error {
+	return es.svc.CanAccessByID(ctx, chanID, thingID, action)
 }
 
 func (es eventStore) Identify(ctx context.Context, key string) (string, error) {
 	return es.svc.Identify(ctx, key)
 }
+
+func (es eventStore) Owner(ctx context.Context, id string) (string, error) {
+	return es.svc.Owner(ctx, id)
+}
+
+func (es eventStore) KeyDigest(ctx context.Context, id string) (string, error) {
+	return es.svc.KeyDigest(ctx, id)
+}
+
+func (es eventStore) Retention(ctx context.Context, id string) (things.Retention, error) {
+	return es.svc.Retention(ctx, id)
+}
+
+func (es eventStore) Region(ctx context.Context, id string) (string, error) {
+	return es.svc.Region(ctx, id)
+}
+
+func (es eventStore) Alias(ctx context.Context, id string) (string, error) {
+	return es.svc.Alias(ctx, id)
+}
+
+func (es eventStore) ResolveAlias(ctx context.Context, alias string) (string, error) {
+	return es.svc.ResolveAlias(ctx, alias)
+}
+
+func (es eventStore) Ordering(ctx context.Context, id string) (bool, error) {
+	return es.svc.Ordering(ctx, id)
+}
+
+func (es eventStore) Transformer(ctx context.Context, id string) (things.Transformer, error) {
+	return es.svc.Transformer(ctx, id)
+}
+
+func (es eventStore) UpdateQuota(ctx context.Context, token string, quota things.Quota) error {
+	return es.svc.UpdateQuota(ctx, token, quota)
+}
+
+func (es eventStore) ViewQuota(ctx context.Context, token, owner string) (things.Quota, things.Usage, error) {
+	return es.svc.ViewQuota(ctx, token, owner)
+}
+
+func (es eventStore) RemoveQuota(ctx context.Context, token, owner string) error {
+	return es.svc.RemoveQuota(ctx, token, owner)
+}
+
+// TransferOwnership announces the transfer using the single event, since the
+// transferred entities aren't known to the middleware.
+func (es eventStore) TransferOwnership(ctx context.Context, token, from, to string) error {
+	if err := es.svc.TransferOwnership(ctx, token, from, to); err != nil {
+		return err
+	}
+
+	event := transferOwnershipEvent{
+		from: from,
+		to:   to,
+	}
+	record := &redis.XAddArgs{
+		Stream:       streamID,
+		MaxLenApprox: streamLen,
+		Values:       event.Encode(),
+	}
+	es.client.XAdd(record).Err()
+
+	return nil
+}
+
+func (es eventStore) SaveSchema(ctx context.Context, token string, schema things.MetadataSchema) error {
+	return es.svc.SaveSchema(ctx, token, schema)
+}
+
+func (es eventStore) ViewSchema(ctx context.Context, token, entity string) (things.MetadataSchema, error) {
+	return es.svc.ViewSchema(ctx, token, entity)
+}
+
+func (es eventStore) RemoveSchema(ctx context.Context, token, entity string) error {
+	return es.svc.RemoveSchema(ctx, token, entity)
+}
+
+func (es eventStore) SavePayloadSchema(ctx context.Context, token string, schema things.PayloadSchema) error {
+	return es.svc.SavePayloadSchema(ctx, token, schema)
+}
+
+func (es eventStore) ListPayloadSchemas(ctx context.Context, token, chanID string) ([]things.PayloadSchema, error) {
+	return es.svc.ListPayloadSchemas(ctx, token, chanID)
+}
+
+func (es eventStore) RemovePayloadSchema(ctx context.Context, token, chanID, subtopic string) error {
+	return es.svc.RemovePayloadSchema(ctx, token, chanID, subtopic)
+}
+
+func (es eventStore) PayloadSchema(ctx context.Context, chanID, subtopic string) (things.PayloadSchema, error) {
+	return es.svc.PayloadSchema(ctx, chanID, subtopic)
+}
+
+func (es eventStore) ListChanges(ctx context.Context, token, since string, limit uint64) (things.ChangesPage, error) {
+	return es.svc.ListChanges(ctx, token, since, limit)
+}
+
+func (es eventStore) ViewAPIUsage(ctx context.Context, token string, from, to time.Time) ([]things.APIUsage, error) {
+	return es.svc.ViewAPIUsage(ctx, token, from, to)
+}
+
+func (es eventStore) updateKey(ctx context.Context, token, id string) {
+	event := updateKeyEvent{
+		id:    id,
+		owner: es.thingOwner(ctx, token, id),
+	}
+	record := &redis.XAddArgs{
+		Stream:       streamID,
+		MaxLenApprox: streamLen,
+		Values:       event.Encode(),
+	}
+	es.client.XAdd(record).Err()
+}
+
+// thingOwner resolves the owner of the thing, so that the events which don't
+// carry the whole entity can be attributed to the owner in the change log.
+func (es eventStore) thingOwner(ctx context.Context, token, id string) string {
+	th, err := es.svc.ViewThing(ctx, token, id)
+	if err != nil {
+		return ""
+	}
+
+	return th.Owner
+}
+
+// channelOwner resolves the owner of the channel, so that the events which
+// don't carry the whole entity can be attributed to the owner in the change
+// log.
+func (es eventStore) channelOwner(ctx context.Context, token, id string) string {
+	ch, err := es.svc.ViewChannel(ctx, token, id)
+	if err != nil {
+		return ""
+	}
+
+	return ch.Owner
+}
@@ -21,12 +21,14 @@ import (
 
 const (
 	streamID        = "mainflux.things"
+	version         = "1"
 	email           = "user@example.com"
 	token           = "token"
 	thingPrefix     = "thing."
 	thingCreate     = thingPrefix + "create"
 	thingUpdate     = thingPrefix + "update"
 	thingRemove     = thingPrefix + "remove"
+	thingUpdateKey  = thingPrefix + "update_key"
 	thingConnect    = thingPrefix + "connect"
 	thingDisconnect = thingPrefix + "disconnect"
 
@@ -34,6 +36,8 @@ const (
 	channelCreate = channelPrefix + "create"
 	channelUpdate = channelPrefix + "update"
 	channelRemove = channelPrefix + "remove"
+
+	ownerTransfer = "owner.transfer"
 )
 
 func newService(tokens map[string]string) things.Service {
@@ -41,11 +45,13 @@ func newService(tokens map[string]string) things.Service {
 	conns := make(chan mocks.Connection)
 	thingsRepo := mocks.NewThingRepository(conns)
 	channelsRepo := mocks.NewChannelRepository(thingsRepo, conns)
+	quotasRepo := mocks.NewQuotaRepository(thingsRepo, channelsRepo)
+	schemasRepo := mocks.NewSchemaRepository()
 	chanCache := mocks.NewChannelCache()
 	thingCache := mocks.NewThingCache()
 	idp := mocks.NewIdentityProvider()
 
-	return things.New(users, thingsRepo, channelsRepo, chanCache, thingCache, idp)
+	return things.New(users, thingsRepo, channelsRepo, quotasRepo, schemasRepo, chanCache, thingCache, idp, nil, things.Quota{}, nil, 0, 0, nil, nil, nil)
 }
 
 func TestAddThing(t *testing.T) {
@@ -75,6 +81,7 @@ func TestAddThing(t *testing.T) {
 				"owner":     email,
 				"metadata":  "{\"test\":\"test\"}",
 				"operation": thingCreate,
+				"version":   version,
 			},
 		},
 	}
@@ -130,9 +137,11 @@ func TestUpdateThing(t *testing.T) {
 			err: nil,
 			event: map[string]interface{}{
 				"id":        sth.ID,
+				"owner":     email,
 				"name":      "a",
 				"metadata":  "{\"test\":\"test\"}",
 				"operation": thingUpdate,
+				"version":   version,
 			},
 		},
 	}
@@ -159,6 +168,129 @@ func TestUpdateThing(t *testing.T) {
 	}
 }
 
+func TestUpdateKey(t *testing.T) {
+	redisClient.FlushAll().Err()
+
+	svc := newService(map[string]string{token: email})
+	// Create thing without sending event.
+	sth, err := svc.AddThing(context.Background(), token, things.Thing{Name: "a"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+
+	svc = redis.NewEventStoreMiddleware(svc, redisClient)
+
+	cases := []struct {
+		desc   string
+		id     string
+		key    string
+		newKey string
+		err    error
+		event  map[string]interface{}
+	}{
+		{
+			desc:   "update key of existing thing successfully",
+			id:     sth.ID,
+			key:    token,
+			newKey: "new-key",
+			err:    nil,
+			event: map[string]interface{}{
+				"id":        sth.ID,
+				"owner":     email,
+				"operation": thingUpdateKey,
+				"version":   version,
+			},
+		},
+		{
+			desc:   "update key of thing with invalid credentials",
+			id:     sth.ID,
+			key:    "",
+			newKey: "other-key",
+			err:    things.ErrUnauthorizedAccess,
+			event:  nil,
+		},
+	}
+
+	lastID := "0"
+	for _, tc := range cases {
+		err := svc.UpdateKey(context.Background(), tc.key, tc.id, tc.newKey)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+
+		streams := redisClient.XRead(&r.XReadArgs{
+			Streams: []string{streamID, lastID},
+			Count:   1,
+			Block:   time.Second,
+		}).Val()
+
+		var event map[string]interface{}
+		if len(streams) > 0 && len(streams[0].Messages) > 0 {
+			msg := streams[0].Messages[0]
+			event = msg.Values
+			lastID = msg.ID
+		}
+
+		assert.Equal(t, tc.event, event, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.event, event))
+	}
+}
+
+func TestRotateKey(t *testing.T) {
+	redisClient.FlushAll().Err()
+
+	svc := newService(map[string]string{token: email})
+	// Create thing without sending event.
+	sth, err := svc.AddThing(context.Background(), token, things.Thing{Name: "a"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+
+	svc = redis.NewEventStoreMiddleware(svc, redisClient)
+
+	cases := []struct {
+		desc  string
+		id    string
+		key   string
+		err   error
+		event map[string]interface{}
+	}{
+		{
+			desc: "rotate key of existing thing successfully",
+			id:   sth.ID,
+			key:  token,
+			err:  nil,
+			event: map[string]interface{}{
+				"id":        sth.ID,
+				"owner":     email,
+				"operation": thingUpdateKey,
+				"version":   version,
+			},
+		},
+		{
+			desc:  "rotate key of thing with invalid credentials",
+			id:    sth.ID,
+			key:   "",
+			err:   things.ErrUnauthorizedAccess,
+			event: nil,
+		},
+	}
+
+	lastID := "0"
+	for _, tc := range cases {
+		_, err := svc.RotateKey(context.Background(), tc.key, tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+
+		streams := redisClient.XRead(&r.XReadArgs{
+			Streams: []string{streamID, lastID},
+			Count:   1,
+			Block:   time.Second,
+		}).Val()
+
+		var event map[string]interface{}
+		if len(streams) > 0 && len(streams[0].Messages) > 0 {
+			msg := streams[0].Messages[0]
+			event = msg.Values
+			lastID = msg.ID
+		}
+
+		assert.Equal(t, tc.event, event, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.event, event))
+	}
+}
+
 func TestViewThing(t *testing.T) {
 	redisClient.FlushAll().Err()
 
@@ -183,8 +315,8 @@ func TestListThings(t *testing.T) {
 	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
 
 	essvc := redis.NewEventStoreMiddleware(svc, redisClient)
-	esths, eserr := essvc.ListThings(context.Background(), token, 0, 10, "", nil)
-	ths, err := svc.ListThings(context.Background(), token, 0, 10, "", nil)
+	esths, eserr := essvc.ListThings(context.Background(), token, things.PageQuery{Limit: 10})
+	ths, err := svc.ListThings(context.Background(), token, things.PageQuery{Limit: 10})
 	assert.Equal(t, ths, esths, fmt.Sprintf("event sourcing changed service behaviour: expected %v got %v", ths, esths))
 	assert.Equal(t, err, eserr, fmt.Sprintf("event sourcing changed service behaviour: expected %v got %v", err, eserr))
 }
@@ -198,7 +330,7 @@ func TestListThingsByChannel(t *testing.T) {
 	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
 	sch, err := svc.CreateChannel(context.Background(), token, things.Channel{Name: "a"})
 	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
-	err = svc.Connect(context.Background(), token, sch.ID, sth.ID)
+	err = svc.Connect(context.Background(), token, sch.ID, sth.ID, nil)
 	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
 
 	essvc := redis.NewEventStoreMiddleware(svc, redisClient)
@@ -232,7 +364,9 @@ func TestRemoveThing(t *testing.T) {
 			err:  nil,
 			event: map[string]interface{}{
 				"id":        sth.ID,
+				"owner":     email,
 				"operation": thingRemove,
+				"version":   version,
 			},
 		},
 		{
@@ -290,6 +424,7 @@ func TestCreateChannel(t *testing.T) {
 				"metadata":  "{\"test\":\"test\"}",
 				"owner":     email,
 				"operation": channelCreate,
+				"version":   version,
 			},
 		},
 		{
@@ -351,9 +486,11 @@ func TestUpdateChannel(t *testing.T) {
 			err: nil,
 			event: map[string]interface{}{
 				"id":        sch.ID,
+				"owner":     email,
 				"name":      "b",
 				"metadata":  "{\"test\":\"test\"}",
 				"operation": channelUpdate,
+				"version":   version,
 			},
 		},
 		{
@@ -414,8 +551,8 @@ func TestListChannels(t *testing.T) {
 	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
 
 	essvc := redis.NewEventStoreMiddleware(svc, redisClient)
-	eschs, eserr := essvc.ListChannels(context.Background(), token, 0, 10, "", nil)
-	chs, err := svc.ListChannels(context.Background(), token, 0, 10, "", nil)
+	eschs, eserr := essvc.ListChannels(context.Background(), token, things.PageQuery{Limit: 10})
+	chs, err := svc.ListChannels(context.Background(), token, things.PageQuery{Limit: 10})
 	assert.Equal(t, chs, eschs, fmt.Sprintf("event sourcing changed service behaviour: expected %v got %v", chs, eschs))
 	assert.Equal(t, err, eserr, fmt.Sprintf("event sourcing changed service behaviour: expected %v got %v", err, eserr))
 }
@@ -429,7 +566,7 @@ func TestListChannelsByThing(t *testing.T) {
 	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
 	sch, err := svc.CreateChannel(context.Background(), token, things.Channel{Name: "a"})
 	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
-	err = svc.Connect(context.Background(), token, sch.ID, sth.ID)
+	err = svc.Connect(context.Background(), token, sch.ID, sth.ID, nil)
 	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
 
 	essvc := redis.NewEventStoreMiddleware(svc, redisClient)
@@ -463,7 +600,9 @@ func TestRemoveChannel(t *testing.T) {
 			err:  nil,
 			event: map[string]interface{}{
 				"id":        sch.ID,
+				"owner":     email,
 				"operation": channelRemove,
+				"version":   version,
 			},
 		},
 		{
@@ -526,7 +665,10 @@ func TestConnectEvent(t *testing.T) {
 			event: map[string]interface{}{
 				"chan_id":   sch.ID,
 				"thing_id":  sth.ID,
+				"owner":     email,
+				"actions":   "publish,subscribe,read_history",
 				"operation": thingConnect,
+				"version":   version,
 			},
 		},
 		{
@@ -541,7 +683,7 @@ func TestConnectEvent(t *testing.T) {
 
 	lastID := "0"
 	for _, tc := range cases {
-		err := svc.Connect(context.Background(), tc.key, tc.chanID, tc.thingID)
+		err := svc.Connect(context.Background(), tc.key, tc.chanID, tc.thingID, nil)
 		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
 
 		streams := redisClient.XRead(&r.XReadArgs{
@@ -570,7 +712,7 @@ func TestDisconnectEvent(t *testing.T) {
 	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
 	sch, err := svc.CreateChannel(context.Background(), token, things.Channel{Name: "a"})
 	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
-	err = svc.Connect(context.Background(), token, sch.ID, sth.ID)
+	err = svc.Connect(context.Background(), token, sch.ID, sth.ID, nil)
 	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
 
 	svc = redis.NewEventStoreMiddleware(svc, redisClient)
@@ -592,7 +734,9 @@ func TestDisconnectEvent(t *testing.T) {
 			event: map[string]interface{}{
 				"chan_id":   sch.ID,
 				"thing_id":  sth.ID,
+				"owner":     email,
 				"operation": thingDisconnect,
+				"version":   version,
 			},
 		},
 		{
@@ -626,3 +770,78 @@ func TestDisconnectEvent(t *testing.T) {
 		assert.Equal(t, tc.event, event, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.event, event))
 	}
 }
+
+func TestTransferOwnershipEvent(t *testing.T) {
+	redisClient.FlushAll().Err()
+
+	adminEmail := "admin@example.com"
+	adminToken := "admin-token"
+	disabledEmail := "disabled@example.com"
+	newEmail := "new@example.com"
+
+	tokens := map[string]string{token: email, adminToken: adminEmail, "new-token": newEmail}
+	users := mocks.NewUsersServiceWithDisabled(tokens, []string{disabledEmail})
+	conns := make(chan mocks.Connection)
+	thingsRepo := mocks.NewThingRepository(conns)
+	channelsRepo := mocks.NewChannelRepository(thingsRepo, conns)
+	quotasRepo := mocks.NewQuotaRepository(thingsRepo, channelsRepo)
+	schemasRepo := mocks.NewSchemaRepository()
+	chanCache := mocks.NewChannelCache()
+	thingCache := mocks.NewThingCache()
+	idp := mocks.NewIdentityProvider()
+
+	svc := things.New(users, thingsRepo, channelsRepo, quotasRepo, schemasRepo, chanCache, thingCache, idp, nil, things.Quota{}, []string{adminEmail}, 0, 0, nil, nil, nil)
+	svc = redis.NewEventStoreMiddleware(svc, redisClient)
+
+	cases := []struct {
+		desc  string
+		token string
+		from  string
+		to    string
+		err   error
+		event map[string]interface{}
+	}{
+		{
+			desc:  "transfer ownership successfully",
+			token: adminToken,
+			from:  disabledEmail,
+			to:    newEmail,
+			err:   nil,
+			event: map[string]interface{}{
+				"owner":          newEmail,
+				"previous_owner": disabledEmail,
+				"operation":      ownerTransfer,
+				"version":        version,
+			},
+		},
+		{
+			desc:  "transfer ownership as non-admin",
+			token: token,
+			from:  disabledEmail,
+			to:    newEmail,
+			err:   things.ErrUnauthorizedAccess,
+			event: nil,
+		},
+	}
+
+	lastID := "0"
+	for _, tc := range cases {
+		err := svc.TransferOwnership(context.Background(), tc.token, tc.from, tc.to)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+
+		streams := redisClient.XRead(&r.XReadArgs{
+			Streams: []string{streamID, lastID},
+			Count:   1,
+			Block:   time.Second,
+		}).Val()
+
+		var event map[string]interface{}
+		if len(streams) > 0 && len(streams[0].Messages) > 0 {
+			msg := streams[0].Messages[0]
+			event = msg.Values
+			lastID = msg.ID
+		}
+
+		assert.Equal(t, tc.event, event, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.event, event))
+	}
+}
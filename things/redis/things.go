@@ -19,11 +19,11 @@ const (
 var _ things.ThingCache = (*thingCache)(nil)
 
 type thingCache struct {
-	client *redis.Client
+	client redis.UniversalClient
 }
 
 // NewThingCache returns redis thing cache implementation.
-func NewThingCache(client *redis.Client) things.ThingCache {
+func NewThingCache(client redis.UniversalClient) things.ThingCache {
 	return &thingCache{
 		client: client,
 	}
@@ -0,0 +1,32 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package things
+
+const (
+	// RoleAdmin allows managing quotas of all the owners.
+	RoleAdmin = "admin"
+
+	// RoleEditor allows managing things, channels and connections.
+	RoleEditor = "editor"
+
+	// RoleViewer allows listing and viewing things and channels.
+	RoleViewer = "viewer"
+)
+
+var ranks = map[string]int{
+	RoleViewer: 1,
+	RoleEditor: 2,
+	RoleAdmin:  3,
+}
+
+// hasRole checks if the role the users service assigned to the user grants
+// the required role. Users services unaware of roles don't send any, so the
+// missing role is treated as editor, which keeps the previous behaviour.
+func hasRole(role, required string) bool {
+	if role == "" {
+		role = RoleEditor
+	}
+
+	return ranks[role] >= ranks[required]
+}
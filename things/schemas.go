@@ -0,0 +1,149 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package things
+
+import (
+	"context"
+	"strings"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+const (
+	// ThingsEntity identifies the schema of things metadata.
+	ThingsEntity = "things"
+	// ChannelsEntity identifies the schema of channels metadata.
+	ChannelsEntity = "channels"
+)
+
+// MetadataSchema represents JSON Schema that the metadata of the owner's
+// things or channels must satisfy.
+type MetadataSchema struct {
+	Owner      string
+	Entity     string
+	Definition map[string]interface{}
+}
+
+// PayloadSchema represents JSON Schema that the payloads of the messages
+// published to the channel subtopic must satisfy. Schema of the empty
+// subtopic applies to the messages published without the subtopic, and to
+// the subtopics without the schema of their own. Payload schemas are removed
+// along with the purged channel.
+type PayloadSchema struct {
+	Owner      string
+	Channel    string
+	Subtopic   string
+	Definition map[string]interface{}
+}
+
+// Violation describes the metadata field that doesn't satisfy the schema.
+type Violation struct {
+	Field       string
+	Description string
+}
+
+// ValidationError indicates that the metadata doesn't satisfy the schema
+// registered by its owner.
+type ValidationError struct {
+	Violations []Violation
+}
+
+func (ve *ValidationError) Error() string {
+	return "metadata doesn't satisfy the schema"
+}
+
+// SchemaRepository specifies a metadata schema persistence API.
+type SchemaRepository interface {
+	// Save persists the schema. Saving the schema of the entity that already
+	// has one replaces it.
+	Save(context.Context, MetadataSchema) error
+
+	// RetrieveByEntity retrieves the schema of the owner's entities. If the
+	// owner hasn't registered the schema, ErrNotFound is returned.
+	RetrieveByEntity(context.Context, string, string) (MetadataSchema, error)
+
+	// Remove removes the schema of the owner's entities.
+	Remove(context.Context, string, string) error
+
+	// SavePayload persists the payload schema. Saving the schema of the
+	// channel subtopic that already has one replaces it.
+	SavePayload(context.Context, PayloadSchema) error
+
+	// RetrievePayload retrieves the payload schema of the channel subtopic.
+	// If there is no such schema, ErrNotFound is returned.
+	RetrievePayload(context.Context, string, string) (PayloadSchema, error)
+
+	// RetrievePayloads retrieves all the payload schemas of the channel,
+	// sorted by their subtopics.
+	RetrievePayloads(context.Context, string) ([]PayloadSchema, error)
+
+	// RemovePayload removes the payload schema of the channel subtopic.
+	RemovePayload(context.Context, string, string) error
+}
+
+func validEntity(entity string) bool {
+	return entity == ThingsEntity || entity == ChannelsEntity
+}
+
+// compile parses the schema definition, returning ErrMalformedEntity if it
+// isn't a valid JSON Schema.
+func (ms MetadataSchema) compile() (*gojsonschema.Schema, error) {
+	return compile(ms.Definition)
+}
+
+// validSubtopic reports whether the subtopic is the dot separated sequence
+// of the subtopic tokens, without the wildcards.
+func validSubtopic(subtopic string) bool {
+	if subtopic == "" {
+		return true
+	}
+
+	for _, elem := range strings.Split(subtopic, ".") {
+		if elem == "" || strings.ContainsAny(elem, "*> ") {
+			return false
+		}
+	}
+
+	return true
+}
+
+func compile(def map[string]interface{}) (*gojsonschema.Schema, error) {
+	if def == nil {
+		return nil, ErrMalformedEntity
+	}
+
+	schema, err := gojsonschema.NewSchema(gojsonschema.NewGoLoader(def))
+	if err != nil {
+		return nil, ErrMalformedEntity
+	}
+
+	return schema, nil
+}
+
+// validate checks the metadata against the compiled schema. Missing metadata
+// is validated as an empty object.
+func validate(schema *gojsonschema.Schema, metadata Metadata) error {
+	if metadata == nil {
+		metadata = Metadata{}
+	}
+
+	res, err := schema.Validate(gojsonschema.NewGoLoader(metadata))
+	if err != nil {
+		return ErrMalformedEntity
+	}
+
+	if res.Valid() {
+		return nil
+	}
+
+	violations := []Violation{}
+	for _, re := range res.Errors() {
+		violations = append(violations, Violation{
+			Field:       re.Field(),
+			Description: re.Description(),
+		})
+	}
+
+	return &ValidationError{Violations: violations}
+}
@@ -5,9 +5,16 @@ package things
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
+	"sort"
+	"time"
 
 	"github.com/mainflux/mainflux"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 var (
@@ -25,31 +32,53 @@ var (
 	// ErrConflict indicates that entity already exists.
 	ErrConflict = errors.New("entity already exists")
 
+	// ErrActiveOwner indicates that the entities of the user who isn't
+	// disabled are being transferred.
+	ErrActiveOwner = errors.New("owner is not disabled")
+
 	// ErrScanMetadata indicates problem with metadata in db
 	ErrScanMetadata = errors.New("Failed to scan metadata")
 )
 
 // Service specifies an API that must be fullfiled by the domain service
-// implementation, and all of its decorators (e.g. logging & metrics).
+// implementation, and all of its decorators (e.g. logging & metrics). The
+// operations which only read the entities require the viewer role, while
+// the ones modifying them require the editor role.
 type Service interface {
 	// AddThing adds new thing to the user identified by the provided key.
 	AddThing(context.Context, string, Thing) (Thing, error)
 
 	// UpdateThing updates the thing identified by the provided ID, that
-	// belongs to the user identified by the provided key.
+	// belongs to the user identified by the provided key or is shared with
+	// the user or the user's group with the edit permission.
 	UpdateThing(context.Context, string, Thing) error
 
-	// UpdateKey updates key value of the existing thing. A non-nil error is
-	// returned to indicate operation failure.
+	// UpdateKey updates key value of the existing thing. The previous key
+	// remains valid during the key overlap period. Updating the thing to its
+	// current key succeeds, so that the update can be safely retried.
 	UpdateKey(context.Context, string, string, string) error
 
+	// RotateKey replaces the key of the existing thing, that belongs to the
+	// user identified by the provided key, with the generated one and returns
+	// it. The previous key remains valid during the key grace period.
+	RotateKey(context.Context, string, string) (string, error)
+
 	// ViewThing retrieves data about the thing identified with the provided
-	// ID, that belongs to the user identified by the provided key.
+	// ID, that belongs to the user identified by the provided key or is
+	// shared with the user or the user's group.
 	ViewThing(context.Context, string, string) (Thing, error)
 
 	// ListThings retrieves data about subset of things that belongs to the
-	// user identified by the provided key.
-	ListThings(context.Context, string, uint64, uint64, string, Metadata) (ThingsPage, error)
+	// user identified by the provided key, as specified by the page query.
+	// Cursor continues listing after the last thing of the previous page and
+	// can be used only if things are ordered by ID. Things shared with the
+	// user or the user's groups are listed only if explicitly requested.
+	ListThings(context.Context, string, PageQuery) (ThingsPage, error)
+
+	// SearchThings retrieves data about subset of things that belong to the
+	// user identified by the provided key and whose name or metadata match
+	// the provided full-text query.
+	SearchThings(context.Context, string, string, uint64, uint64) (ThingsPage, error)
 
 	// ListThingsByChannel retrieves data about subset of things that are
 	// connected to specified channel and belong to the user identified by
@@ -57,23 +86,80 @@ type Service interface {
 	ListThingsByChannel(context.Context, string, string, uint64, uint64) (ThingsPage, error)
 
 	// RemoveThing removes the thing identified with the provided ID, that
-	// belongs to the user identified by the provided key.
+	// belongs to the user identified by the provided key. Removed thing can
+	// be restored until it's purged.
 	RemoveThing(context.Context, string, string) error
 
+	// RestoreThing restores the removed thing identified with the provided
+	// ID, that belongs to the user identified by the provided key.
+	RestoreThing(context.Context, string, string) error
+
+	// PurgeThing permanently removes the thing identified with the provided
+	// ID, that belongs to the user identified by the provided key.
+	PurgeThing(context.Context, string, string) error
+
+	// ShareThing grants the permission on the thing identified by the
+	// provided ID, that belongs to the user identified by the provided key,
+	// to the members of the group the user is member of.
+	ShareThing(context.Context, string, string, string, string) error
+
+	// UnshareThing revokes the access to the thing identified by the
+	// provided ID, that belongs to the user identified by the provided key,
+	// from the members of the group.
+	UnshareThing(context.Context, string, string, string) error
+
+	// ShareThingWithUser grants the permission on the thing identified by the
+	// provided ID, that belongs to the user identified by the provided key,
+	// to the user having the provided email.
+	ShareThingWithUser(context.Context, string, string, string, string) error
+
+	// UnshareThingWithUser revokes the access to the thing identified by the
+	// provided ID, that belongs to the user identified by the provided key,
+	// from the user having the provided email.
+	UnshareThingWithUser(context.Context, string, string, string) error
+
+	// ExportThings invokes the callback for each of the things, including
+	// their keys unless the keys are hashed, that belong to the user
+	// identified by the provided key. Removed things are not exported.
+	ExportThings(context.Context, string, func(Thing) error) error
+
+	// ImportThings adds the things to the user identified by the provided
+	// key, preserving their IDs and keys. Missing IDs and keys are generated.
+	// Import stops at the first failure, keeping the already imported things,
+	// which are returned along with the error.
+	ImportThings(context.Context, string, []Thing) ([]Thing, error)
+
 	// CreateChannel adds new channel to the user identified by the provided key.
 	CreateChannel(context.Context, string, Channel) (Channel, error)
 
+	// CreateDirectChannel provisions the direct channel for the pair of
+	// things that belong to the user identified by the provided key, and
+	// connects both of them to it. If the things are already paired, their
+	// existing direct channel is returned.
+	CreateDirectChannel(context.Context, string, string, string) (Channel, error)
+
 	// UpdateChannel updates the channel identified by the provided ID, that
-	// belongs to the user identified by the provided key.
+	// belongs to the user identified by the provided key or is shared with
+	// the user or the user's group with the edit permission.
 	UpdateChannel(context.Context, string, Channel) error
 
 	// ViewChannel retrieves data about the channel identified by the provided
-	// ID, that belongs to the user identified by the provided key.
+	// ID, that belongs to the user identified by the provided key or is
+	// shared with the user or the user's group.
 	ViewChannel(context.Context, string, string) (Channel, error)
 
 	// ListChannels retrieves data about subset of channels that belongs to the
-	// user identified by the provided key.
-	ListChannels(context.Context, string, uint64, uint64, string, Metadata) (ChannelsPage, error)
+	// user identified by the provided key, as specified by the page query.
+	// Cursor continues listing after the last channel of the previous page
+	// and can be used only if channels are ordered by ID. Channels shared
+	// with the user or the user's groups are listed only if explicitly
+	// requested.
+	ListChannels(context.Context, string, PageQuery) (ChannelsPage, error)
+
+	// SearchChannels retrieves data about subset of channels that belong to
+	// the user identified by the provided key and whose name or metadata
+	// match the provided full-text query.
+	SearchChannels(context.Context, string, string, uint64, uint64) (ChannelsPage, error)
 
 	// ListChannelsByThing retrieves data about subset of channels that have
 	// specified thing connected to them and belong to the user identified by
@@ -81,34 +167,216 @@ type Service interface {
 	ListChannelsByThing(context.Context, string, string, uint64, uint64) (ChannelsPage, error)
 
 	// RemoveChannel removes the thing identified by the provided ID, that
-	// belongs to the user identified by the provided key.
+	// belongs to the user identified by the provided key. Removed channel
+	// can be restored until it's purged.
 	RemoveChannel(context.Context, string, string) error
 
-	// Connect adds thing to the channel's list of connected things.
-	Connect(context.Context, string, string, string) error
+	// RestoreChannel restores the removed channel identified by the provided
+	// ID, that belongs to the user identified by the provided key.
+	RestoreChannel(context.Context, string, string) error
+
+	// PurgeChannel permanently removes the channel identified by the provided
+	// ID, that belongs to the user identified by the provided key.
+	PurgeChannel(context.Context, string, string) error
+
+	// ShareChannel grants the permission on the channel identified by the
+	// provided ID, that belongs to the user identified by the provided key,
+	// to the members of the group the user is member of.
+	ShareChannel(context.Context, string, string, string, string) error
+
+	// UnshareChannel revokes the access to the channel identified by the
+	// provided ID, that belongs to the user identified by the provided key,
+	// from the members of the group.
+	UnshareChannel(context.Context, string, string, string) error
+
+	// ShareChannelWithUser grants the permission on the channel identified by the
+	// provided ID, that belongs to the user identified by the provided key,
+	// to the user having the provided email.
+	ShareChannelWithUser(context.Context, string, string, string, string) error
+
+	// UnshareChannelWithUser revokes the access to the channel identified by the
+	// provided ID, that belongs to the user identified by the provided key,
+	// from the user having the provided email.
+	UnshareChannelWithUser(context.Context, string, string, string) error
+
+	// ExportChannels invokes the callback for each of the channels that
+	// belong to the user identified by the provided key. Removed channels
+	// are not exported.
+	ExportChannels(context.Context, string, func(Channel) error) error
+
+	// ImportChannels adds the channels to the user identified by the
+	// provided key, preserving their IDs. Missing IDs are generated. Import
+	// stops at the first failure, keeping the already imported channels,
+	// which are returned along with the error.
+	ImportChannels(context.Context, string, []Channel) ([]Channel, error)
+
+	// Connect adds thing to the channel's list of connected things, allowing
+	// it to perform the provided actions. All the actions are allowed if
+	// none are provided.
+	Connect(context.Context, string, string, string, []string) error
 
 	// Disconnect removes thing from the channel's list of connected
 	// things.
 	Disconnect(context.Context, string, string, string) error
 
-	// CanAccess determines whether the channel can be accessed using the
-	// provided key and returns thing's id if access is allowed.
-	CanAccess(context.Context, string, string) (string, error)
+	// ExportConnections invokes the callback for each of the connections
+	// between the channels and the things that belong to the user identified
+	// by the provided key.
+	ExportConnections(context.Context, string, func(Connection) error) error
+
+	// ImportConnections connects the things to the channels that belong to
+	// the user identified by the provided key, allowing them to perform the
+	// provided actions. All the actions are allowed if none are provided.
+	// Import stops at the first failure, keeping the already imported
+	// connections, which are returned along with the error.
+	ImportConnections(context.Context, string, []Connection) ([]Connection, error)
 
-	// CanAccessByID determines whether the channnel can be accessed by
-	// the given thing and returns error if it cannot.
-	CanAccessByID(context.Context, string, string) error
+	// CanAccess determines whether the provided action can be performed on
+	// the channel using the provided key and returns thing's id if access is
+	// allowed.
+	CanAccess(context.Context, string, string, string) (string, error)
+
+	// CanAccessByID determines whether the provided action can be performed
+	// on the channnel by the given thing and returns error if it cannot.
+	CanAccessByID(context.Context, string, string, string) error
 
 	// Identify returns thing ID for given thing key.
 	Identify(context.Context, string) (string, error)
+
+	// Owner returns the owner of the thing having the provided ID.
+	Owner(context.Context, string) (string, error)
+
+	// KeyDigest returns the hex encoded SHA-256 digest of the stored key of
+	// the thing having the provided ID, which changes whenever the key is
+	// updated.
+	KeyDigest(context.Context, string) (string, error)
+
+	// Retention returns the retention of the channel having the provided ID.
+	Retention(context.Context, string) (Retention, error)
+
+	// Region returns the region of the channel having the provided ID.
+	Region(context.Context, string) (string, error)
+
+	// Alias returns the alias of the channel having the provided ID.
+	Alias(context.Context, string) (string, error)
+
+	// ResolveAlias returns the ID of the channel having the provided alias.
+	ResolveAlias(context.Context, string) (string, error)
+
+	// Ordering returns whether the channel having the provided ID is ordered.
+	Ordering(context.Context, string) (bool, error)
+
+	// Transformer returns the payload transformer of the channel having the
+	// provided ID.
+	Transformer(context.Context, string) (Transformer, error)
+
+	// UpdateQuota overrides the default quota of the quota owner. Only
+	// admins, either configured or assigned the admin role, can update
+	// quotas.
+	UpdateQuota(context.Context, string, Quota) error
+
+	// ViewQuota retrieves the quota of the provided owner along with the
+	// number of entities the owner has. Users can view their own quota,
+	// while admins can view the quota of any owner.
+	ViewQuota(context.Context, string, string) (Quota, Usage, error)
+
+	// RemoveQuota removes the quota of the provided owner, restoring the
+	// default quota. Only admins can remove quotas.
+	RemoveQuota(context.Context, string, string) error
+
+	// TransferOwnership transfers all the things and channels of the first
+	// provided owner, along with their connections and shares, to the
+	// second one. It is intended for handing over the resources of the
+	// disabled or removed users to the enabled ones, and the transferred
+	// entities count against the quota of the new owner. Only admins can
+	// transfer ownership.
+	TransferOwnership(context.Context, string, string, string) error
+
+	// SaveSchema registers JSON Schema that the metadata of the things or
+	// channels of the user identified by the provided key must satisfy.
+	// Existing entities aren't validated against the new schema.
+	SaveSchema(context.Context, string, MetadataSchema) error
+
+	// ViewSchema retrieves the schema of the provided entity registered by
+	// the user identified by the provided key.
+	ViewSchema(context.Context, string, string) (MetadataSchema, error)
+
+	// RemoveSchema removes the schema of the provided entity registered by
+	// the user identified by the provided key, disabling the validation.
+	RemoveSchema(context.Context, string, string) error
+
+	// SavePayloadSchema registers JSON Schema that the payloads of the
+	// messages published to the channel subtopic must satisfy. The channel
+	// has to belong to the user identified by the provided key.
+	SavePayloadSchema(context.Context, string, PayloadSchema) error
+
+	// ListPayloadSchemas retrieves the payload schemas of the channel
+	// identified by the provided ID, that belongs to the user identified by
+	// the provided key.
+	ListPayloadSchemas(context.Context, string, string) ([]PayloadSchema, error)
+
+	// RemovePayloadSchema removes the payload schema of the provided
+	// subtopic of the channel identified by the provided ID, that belongs to
+	// the user identified by the provided key.
+	RemovePayloadSchema(context.Context, string, string, string) error
+
+	// PayloadSchema returns the payload schema of the provided subtopic of
+	// the channel having the provided ID, falling back to the schema of the
+	// channel without the subtopic.
+	PayloadSchema(context.Context, string, string) (PayloadSchema, error)
+
+	// ListChanges retrieves the page of changes of the entities belonging to
+	// the user identified by the provided key, recorded after the provided
+	// position. Empty position lists the changes from the oldest retained
+	// one.
+	ListChanges(context.Context, string, string, uint64) (ChangesPage, error)
+
+	// ViewAPIUsage retrieves the API usage of the user identified by the
+	// provided key during the hours starting at or after the first, and
+	// before the second provided time.
+	ViewAPIUsage(context.Context, string, time.Time, time.Time) ([]APIUsage, error)
 }
 
+const (
+	// OrderByID sorts listed entities by their identifiers.
+	OrderByID = "id"
+	// OrderByName sorts listed entities by their names.
+	OrderByName = "name"
+	// OrderByCreatedAt sorts listed entities by the time of their creation.
+	OrderByCreatedAt = "created_at"
+
+	// AscDir sorts listed entities in ascending order.
+	AscDir = "asc"
+	// DescDir sorts listed entities in descending order.
+	DescDir = "desc"
+)
+
 // PageMetadata contains page metadata that helps navigation.
 type PageMetadata struct {
-	Total  uint64
-	Offset uint64
-	Limit  uint64
-	Name   string
+	Total      uint64
+	Offset     uint64
+	Limit      uint64
+	Name       string
+	NextCursor string
+}
+
+// PageQuery contains the parameters of the listed page of things or
+// channels. Entities are sorted by the provided order and direction. If
+// cursor is provided, only entities following it are listed and offset is
+// ignored. Only entities whose metadata contain the provided metadata and
+// satisfy all the metadata queries are listed. Removed and shared entities
+// are listed only if explicitly requested.
+type PageQuery struct {
+	Offset   uint64
+	Limit    uint64
+	Cursor   string
+	Name     string
+	Order    string
+	Dir      string
+	Metadata Metadata
+	Query    []MetadataQuery
+	Deleted  bool
+	Shared   bool
 }
 
 var _ Service = (*thingsService)(nil)
@@ -117,25 +385,58 @@ type thingsService struct {
 	users        mainflux.UsersServiceClient
 	things       ThingRepository
 	channels     ChannelRepository
+	quotas       QuotaRepository
+	schemas      SchemaRepository
 	channelCache ChannelCache
 	thingCache   ThingCache
 	idp          IdentityProvider
+	keys         KeyProvider
+	defQuota     Quota
+	admins       map[string]bool
+	keyGrace     time.Duration
+	keyOverlap   time.Duration
+	hasher       KeyHasher
+	changes      ChangeLog
+	usage        APIUsageRepository
 }
 
-// New instantiates the things service implementation.
-func New(users mainflux.UsersServiceClient, things ThingRepository, channels ChannelRepository, ccache ChannelCache, tcache ThingCache, idp IdentityProvider) Service {
+// New instantiates the things service implementation. Thing keys are generated
+// by the key provider, or by the identity provider if the former is missing.
+// Default quota applies to the owners whose quota isn't overridden by one of
+// the admins. Previous
+// keys of rotated things remain valid for the key grace period, and previous
+// keys of updated things for the key overlap period. If key hasher
+// is provided, thing keys are stored and cached hashed, and they're revealed
+// only when they're generated. Without the change log, no changes are listed,
+// and without the API usage repository, no API usage is listed.
+func New(users mainflux.UsersServiceClient, things ThingRepository, channels ChannelRepository, quotas QuotaRepository, schemas SchemaRepository, ccache ChannelCache, tcache ThingCache, idp IdentityProvider, keys KeyProvider, defQuota Quota, admins []string, keyGrace, keyOverlap time.Duration, hasher KeyHasher, changes ChangeLog, usage APIUsageRepository) Service {
+	adm := make(map[string]bool, len(admins))
+	for _, admin := range admins {
+		adm[admin] = true
+	}
+
 	return &thingsService{
 		users:        users,
 		things:       things,
 		channels:     channels,
+		quotas:       quotas,
+		schemas:      schemas,
 		channelCache: ccache,
 		thingCache:   tcache,
 		idp:          idp,
+		keys:         keys,
+		defQuota:     defQuota,
+		admins:       adm,
+		keyGrace:     keyGrace,
+		keyOverlap:   keyOverlap,
+		hasher:       hasher,
+		changes:      changes,
+		usage:        usage,
 	}
 }
 
 func (ts *thingsService) AddThing(ctx context.Context, token string, thing Thing) (Thing, error) {
-	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	res, err := ts.identify(ctx, token, RoleEditor)
 	if err != nil {
 		return Thing{}, ErrUnauthorizedAccess
 	}
@@ -148,13 +449,28 @@ func (ts *thingsService) AddThing(ctx context.Context, token string, thing Thing
 	thing.Owner = res.GetValue()
 
 	if thing.Key == "" {
-		thing.Key, err = ts.idp.ID()
+		thing.Key, err = ts.newKey()
 		if err != nil {
 			return Thing{}, err
 		}
 	}
 
-	id, err := ts.things.Save(ctx, thing)
+	if ts.malformedKey(thing.Key) {
+		return Thing{}, ErrMalformedEntity
+	}
+
+	if err := ts.validateMetadata(ctx, thing.Owner, ThingsEntity, thing.Metadata); err != nil {
+		return Thing{}, err
+	}
+
+	if err := ts.checkQuota(ctx, thing.Owner, Usage{Things: 1}); err != nil {
+		return Thing{}, err
+	}
+
+	stored := thing
+	stored.Key = ts.storedKey(thing.Key)
+
+	id, err := ts.things.Save(ctx, stored)
 	if err != nil {
 		return Thing{}, err
 	}
@@ -164,57 +480,178 @@ func (ts *thingsService) AddThing(ctx context.Context, token string, thing Thing
 }
 
 func (ts *thingsService) UpdateThing(ctx context.Context, token string, thing Thing) error {
-	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	res, err := ts.identify(ctx, token, RoleEditor)
 	if err != nil {
 		return ErrUnauthorizedAccess
 	}
 
 	thing.Owner = res.GetValue()
 
+	if _, err := ts.things.RetrieveByID(ctx, thing.Owner, thing.ID); err != nil {
+		if err != ErrNotFound {
+			return err
+		}
+
+		shared, permission, err := ts.sharedThing(ctx, token, res.GetValue(), thing.ID)
+		if err != nil {
+			return err
+		}
+
+		if permission != EditPermission {
+			return ErrUnauthorizedAccess
+		}
+
+		thing.Owner = shared.Owner
+	}
+
+	// Metadata is validated against the schema of the thing owner, even if
+	// the thing is updated by the user or the group member it is shared with.
+	if err := ts.validateMetadata(ctx, thing.Owner, ThingsEntity, thing.Metadata); err != nil {
+		return err
+	}
+
 	return ts.things.Update(ctx, thing)
 }
 
 func (ts *thingsService) UpdateKey(ctx context.Context, token, id, key string) error {
-	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	res, err := ts.identify(ctx, token, RoleEditor)
 	if err != nil {
 		return ErrUnauthorizedAccess
 	}
 
+	if ts.malformedKey(key) {
+		return ErrMalformedEntity
+	}
+
 	owner := res.GetValue()
 
-	return ts.things.UpdateKey(ctx, owner, id, key)
+	if err := ts.things.UpdateKey(ctx, owner, id, ts.storedKey(key), ts.keyOverlap); err != nil {
+		return err
+	}
+
+	// The previous key is resolved by the repository from now on, so that
+	// it stops working once the overlap period expires.
+	ts.thingCache.Remove(ctx, id)
+	return nil
+}
+
+func (ts *thingsService) RotateKey(ctx context.Context, token, id string) (string, error) {
+	res, err := ts.identify(ctx, token, RoleEditor)
+	if err != nil {
+		return "", ErrUnauthorizedAccess
+	}
+
+	key, err := ts.newKey()
+	if err != nil {
+		return "", err
+	}
+
+	if err := ts.things.UpdateKey(ctx, res.GetValue(), id, ts.storedKey(key), ts.keyGrace); err != nil {
+		return "", err
+	}
 
+	// The previous key is resolved by the repository from now on, so that
+	// it stops working once the grace period expires.
+	ts.thingCache.Remove(ctx, id)
+	return key, nil
 }
 
 func (ts *thingsService) ViewThing(ctx context.Context, token, id string) (Thing, error) {
-	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	res, err := ts.identify(ctx, token, RoleViewer)
 	if err != nil {
 		return Thing{}, ErrUnauthorizedAccess
 	}
 
-	return ts.things.RetrieveByID(ctx, res.GetValue(), id)
+	thing, err := ts.things.RetrieveByID(ctx, res.GetValue(), id)
+	if err != ErrNotFound {
+		return ts.hideKey(thing), err
+	}
+
+	thing, _, err = ts.sharedThing(ctx, token, res.GetValue(), id)
+	return thing, err
 }
 
-func (ts *thingsService) ListThings(ctx context.Context, token string, offset, limit uint64, name string, metadata Metadata) (ThingsPage, error) {
-	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+func (ts *thingsService) ListThings(ctx context.Context, token string, pq PageQuery) (ThingsPage, error) {
+	res, err := ts.identify(ctx, token, RoleViewer)
+	if err != nil {
+		return ThingsPage{}, ErrUnauthorizedAccess
+	}
+
+	pq.Order, pq.Dir, err = sorting(pq.Order, pq.Dir, pq.Cursor)
+	if err != nil {
+		return ThingsPage{}, err
+	}
+
+	// Repositories are provided with the ID the cursor points to.
+	pq.Cursor, err = decodeCursor(pq.Cursor)
+	if err != nil {
+		return ThingsPage{}, err
+	}
+
+	var groups []string
+	if pq.Shared {
+		if groups, err = ts.groups(ctx, token); err != nil {
+			return ThingsPage{}, err
+		}
+	}
+
+	page, err := ts.things.RetrieveAll(ctx, res.GetValue(), pq, groups)
+	if err != nil {
+		return ThingsPage{}, err
+	}
+
+	for i := range page.Things {
+		if page.Things[i].Owner != res.GetValue() {
+			page.Things[i].Key = ""
+		}
+		page.Things[i] = ts.hideKey(page.Things[i])
+	}
+
+	if n := len(page.Things); n > 0 && uint64(n) == pq.Limit && pq.Order == OrderByID {
+		page.NextCursor = encodeCursor(page.Things[n-1].ID)
+	}
+
+	return page, nil
+}
+
+func (ts *thingsService) SearchThings(ctx context.Context, token, query string, offset, limit uint64) (ThingsPage, error) {
+	res, err := ts.identify(ctx, token, RoleViewer)
 	if err != nil {
 		return ThingsPage{}, ErrUnauthorizedAccess
 	}
 
-	return ts.things.RetrieveAll(ctx, res.GetValue(), offset, limit, name, metadata)
+	page, err := ts.things.Search(ctx, res.GetValue(), query, offset, limit)
+	if err != nil {
+		return ThingsPage{}, err
+	}
+
+	for i := range page.Things {
+		page.Things[i] = ts.hideKey(page.Things[i])
+	}
+
+	return page, nil
 }
 
 func (ts *thingsService) ListThingsByChannel(ctx context.Context, token, channel string, offset, limit uint64) (ThingsPage, error) {
-	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	res, err := ts.identify(ctx, token, RoleViewer)
 	if err != nil {
 		return ThingsPage{}, ErrUnauthorizedAccess
 	}
 
-	return ts.things.RetrieveByChannel(ctx, res.GetValue(), channel, offset, limit)
+	page, err := ts.things.RetrieveByChannel(ctx, res.GetValue(), channel, offset, limit)
+	if err != nil {
+		return ThingsPage{}, err
+	}
+
+	for i := range page.Things {
+		page.Things[i] = ts.hideKey(page.Things[i])
+	}
+
+	return page, nil
 }
 
 func (ts *thingsService) RemoveThing(ctx context.Context, token, id string) error {
-	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	res, err := ts.identify(ctx, token, RoleEditor)
 	if err != nil {
 		return ErrUnauthorizedAccess
 	}
@@ -223,147 +660,1260 @@ func (ts *thingsService) RemoveThing(ctx context.Context, token, id string) erro
 	return ts.things.Remove(ctx, res.GetValue(), id)
 }
 
-func (ts *thingsService) CreateChannel(ctx context.Context, token string, channel Channel) (Channel, error) {
-	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+func (ts *thingsService) RestoreThing(ctx context.Context, token, id string) error {
+	res, err := ts.identify(ctx, token, RoleEditor)
 	if err != nil {
-		return Channel{}, ErrUnauthorizedAccess
+		return ErrUnauthorizedAccess
 	}
 
-	channel.ID, err = ts.idp.ID()
-	if err != nil {
-		return Channel{}, err
+	if err := ts.checkQuota(ctx, res.GetValue(), Usage{Things: 1}); err != nil {
+		return err
 	}
 
-	channel.Owner = res.GetValue()
+	return ts.things.Restore(ctx, res.GetValue(), id)
+}
 
-	id, err := ts.channels.Save(ctx, channel)
+func (ts *thingsService) PurgeThing(ctx context.Context, token, id string) error {
+	res, err := ts.identify(ctx, token, RoleEditor)
 	if err != nil {
-		return Channel{}, err
+		return ErrUnauthorizedAccess
 	}
 
-	channel.ID = id
-	return channel, nil
+	ts.thingCache.Remove(ctx, id)
+	return ts.things.Purge(ctx, res.GetValue(), id)
 }
 
-func (ts *thingsService) UpdateChannel(ctx context.Context, token string, channel Channel) error {
-	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+func (ts *thingsService) ShareThing(ctx context.Context, token, id, group, permission string) error {
+	res, err := ts.identify(ctx, token, RoleEditor)
 	if err != nil {
 		return ErrUnauthorizedAccess
 	}
 
-	channel.Owner = res.GetValue()
-	return ts.channels.Update(ctx, channel)
-}
+	if !validPermission(permission) {
+		return ErrMalformedEntity
+	}
 
-func (ts *thingsService) ViewChannel(ctx context.Context, token, id string) (Channel, error) {
-	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
-	if err != nil {
-		return Channel{}, ErrUnauthorizedAccess
+	if err := ts.isMember(ctx, token, group); err != nil {
+		return err
 	}
 
-	return ts.channels.RetrieveByID(ctx, res.GetValue(), id)
+	return ts.things.Share(ctx, res.GetValue(), id, group, permission)
 }
 
-func (ts *thingsService) ListChannels(ctx context.Context, token string, offset, limit uint64, name string, m Metadata) (ChannelsPage, error) {
-	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+func (ts *thingsService) UnshareThing(ctx context.Context, token, id, group string) error {
+	res, err := ts.identify(ctx, token, RoleEditor)
 	if err != nil {
-		return ChannelsPage{}, ErrUnauthorizedAccess
+		return ErrUnauthorizedAccess
 	}
 
-	return ts.channels.RetrieveAll(ctx, res.GetValue(), offset, limit, name, m)
+	return ts.things.Unshare(ctx, res.GetValue(), id, group)
 }
 
-func (ts *thingsService) ListChannelsByThing(ctx context.Context, token, thing string, offset, limit uint64) (ChannelsPage, error) {
-	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+func (ts *thingsService) ShareThingWithUser(ctx context.Context, token, id, email, permission string) error {
+	res, err := ts.identify(ctx, token, RoleEditor)
 	if err != nil {
-		return ChannelsPage{}, ErrUnauthorizedAccess
+		return ErrUnauthorizedAccess
 	}
 
-	return ts.channels.RetrieveByThing(ctx, res.GetValue(), thing, offset, limit)
+	if !validPermission(permission) || email == res.GetValue() {
+		return ErrMalformedEntity
+	}
+
+	return ts.things.ShareWithUser(ctx, res.GetValue(), id, email, permission)
 }
 
-func (ts *thingsService) RemoveChannel(ctx context.Context, token, id string) error {
-	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+func (ts *thingsService) UnshareThingWithUser(ctx context.Context, token, id, email string) error {
+	res, err := ts.identify(ctx, token, RoleEditor)
 	if err != nil {
 		return ErrUnauthorizedAccess
 	}
 
-	ts.channelCache.Remove(ctx, id)
-	return ts.channels.Remove(ctx, res.GetValue(), id)
+	return ts.things.UnshareWithUser(ctx, res.GetValue(), id, email)
 }
 
-func (ts *thingsService) Connect(ctx context.Context, token, chanID, thingID string) error {
-	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+func (ts *thingsService) ExportThings(ctx context.Context, token string, fn func(Thing) error) error {
+	res, err := ts.identify(ctx, token, RoleViewer)
 	if err != nil {
 		return ErrUnauthorizedAccess
 	}
 
-	return ts.channels.Connect(ctx, res.GetValue(), chanID, thingID)
+	return ts.things.Export(ctx, res.GetValue(), func(th Thing) error {
+		return fn(ts.hideKey(th))
+	})
 }
 
-func (ts *thingsService) Disconnect(ctx context.Context, token, chanID, thingID string) error {
-	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+func (ts *thingsService) ImportThings(ctx context.Context, token string, ths []Thing) ([]Thing, error) {
+	res, err := ts.identify(ctx, token, RoleEditor)
 	if err != nil {
-		return ErrUnauthorizedAccess
+		return nil, ErrUnauthorizedAccess
 	}
 
-	ts.channelCache.Disconnect(ctx, chanID, thingID)
-	return ts.channels.Disconnect(ctx, res.GetValue(), chanID, thingID)
+	validate, err := ts.validator(ctx, res.GetValue(), ThingsEntity)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, th := range ths {
+		if err := validate(th.Metadata); err != nil {
+			return nil, err
+		}
+
+		if th.Key != "" && ts.malformedKey(th.Key) {
+			return nil, ErrMalformedEntity
+		}
+	}
+
+	if err := ts.checkQuota(ctx, res.GetValue(), Usage{Things: uint64(len(ths))}); err != nil {
+		return nil, err
+	}
+
+	imported := []Thing{}
+	for _, th := range ths {
+		th.Owner = res.GetValue()
+
+		if th.ID == "" {
+			if th.ID, err = ts.idp.ID(); err != nil {
+				return imported, err
+			}
+		}
+
+		if th.Key == "" {
+			if th.Key, err = ts.newKey(); err != nil {
+				return imported, err
+			}
+		}
+
+		stored := th
+		stored.Key = ts.storedKey(th.Key)
+
+		if th.ID, err = ts.things.Save(ctx, stored); err != nil {
+			return imported, err
+		}
+
+		imported = append(imported, th)
+	}
+
+	return imported, nil
 }
 
-func (ts *thingsService) CanAccess(ctx context.Context, chanID, key string) (string, error) {
-	thingID, err := ts.hasThing(ctx, chanID, key)
-	if err == nil {
-		return thingID, nil
+func (ts *thingsService) CreateChannel(ctx context.Context, token string, channel Channel) (Channel, error) {
+	res, err := ts.identify(ctx, token, RoleEditor)
+	if err != nil {
+		return Channel{}, ErrUnauthorizedAccess
 	}
 
-	thingID, err = ts.channels.HasThing(ctx, chanID, key)
+	channel.ID, err = ts.idp.ID()
 	if err != nil {
-		return "", ErrUnauthorizedAccess
+		return Channel{}, err
 	}
 
-	ts.thingCache.Save(ctx, key, thingID)
-	ts.channelCache.Connect(ctx, chanID, thingID)
-	return thingID, nil
-}
+	channel.Owner = res.GetValue()
 
-func (ts *thingsService) CanAccessByID(ctx context.Context, chanID, thingID string) error {
-	if connected := ts.channelCache.HasThing(ctx, chanID, thingID); connected {
-		return nil
+	if !channel.Retention.valid() || !validRegion(channel.Region) || !validAlias(channel.Alias) || channel.Type != "" || len(channel.Peers) > 0 {
+		return Channel{}, ErrMalformedEntity
 	}
 
-	if err := ts.channels.HasThingByID(ctx, chanID, thingID); err != nil {
-		return ErrUnauthorizedAccess
+	if err := ts.validateMetadata(ctx, channel.Owner, ChannelsEntity, channel.Metadata); err != nil {
+		return Channel{}, err
 	}
 
-	ts.channelCache.Connect(ctx, chanID, thingID)
-	return nil
-}
+	if err := ts.checkQuota(ctx, channel.Owner, Usage{Channels: 1}); err != nil {
+		return Channel{}, err
+	}
 
-func (ts *thingsService) Identify(ctx context.Context, key string) (string, error) {
-	id, err := ts.thingCache.ID(ctx, key)
-	if err == nil {
-		return id, nil
+	id, err := ts.channels.Save(ctx, channel)
+	if err != nil {
+		return Channel{}, err
 	}
 
-	id, err = ts.things.RetrieveByKey(ctx, key)
+	channel.ID = id
+	return channel, nil
+}
+
+func (ts *thingsService) CreateDirectChannel(ctx context.Context, token, thing1, thing2 string) (Channel, error) {
+	res, err := ts.identify(ctx, token, RoleEditor)
 	if err != nil {
-		return "", ErrUnauthorizedAccess
+		return Channel{}, ErrUnauthorizedAccess
 	}
+	owner := res.GetValue()
 
-	ts.thingCache.Save(ctx, key, id)
-	return id, nil
+	if thing1 == "" || thing2 == "" || thing1 == thing2 {
+		return Channel{}, ErrMalformedEntity
+	}
+
+	peers := []string{thing1, thing2}
+	sort.Strings(peers)
+
+	for _, id := range peers {
+		if _, err := ts.things.RetrieveByID(ctx, owner, id); err != nil {
+			return Channel{}, err
+		}
+	}
+
+	channel, err := ts.channels.RetrieveDirect(ctx, owner, peers)
+	if err != ErrNotFound {
+		return channel, err
+	}
+
+	conns := []Connection{}
+	for _, id := range peers {
+		conns = append(conns, Connection{ThingID: id, Actions: Actions})
+	}
+
+	if err := ts.checkQuota(ctx, owner, Usage{Channels: 1, Connections: uint64(len(conns))}); err != nil {
+		return Channel{}, err
+	}
+
+	channel = Channel{
+		Owner: owner,
+		Type:  DirectChannel,
+		Peers: peers,
+	}
+	if channel.ID, err = ts.idp.ID(); err != nil {
+		return Channel{}, err
+	}
+
+	if channel.ID, err = ts.channels.Save(ctx, channel); err != nil {
+		// The things may have been paired by the concurrent request, unless
+		// their direct channel is removed and not purged yet.
+		if err == ErrConflict {
+			if ch, err := ts.channels.RetrieveDirect(ctx, owner, peers); err != ErrNotFound {
+				return ch, err
+			}
+		}
+		return Channel{}, err
+	}
+
+	for _, conn := range conns {
+		if err := ts.channels.Connect(ctx, owner, channel.ID, conn.ThingID, conn.Actions); err != nil {
+			return Channel{}, err
+		}
+	}
+
+	return channel, nil
 }
 
-func (ts *thingsService) hasThing(ctx context.Context, chanID, key string) (string, error) {
-	thingID, err := ts.thingCache.ID(ctx, key)
+func (ts *thingsService) UpdateChannel(ctx context.Context, token string, channel Channel) error {
+	res, err := ts.identify(ctx, token, RoleEditor)
 	if err != nil {
-		return "", err
+		return ErrUnauthorizedAccess
 	}
 
-	if connected := ts.channelCache.HasThing(ctx, chanID, thingID); !connected {
-		return "", ErrUnauthorizedAccess
+	channel.Owner = res.GetValue()
+
+	if !channel.Retention.valid() || !validRegion(channel.Region) || !validAlias(channel.Alias) {
+		return ErrMalformedEntity
 	}
 
-	return thingID, nil
+	if _, err := ts.channels.RetrieveByID(ctx, channel.Owner, channel.ID); err != nil {
+		if err != ErrNotFound {
+			return err
+		}
+
+		shared, permission, err := ts.sharedChannel(ctx, token, res.GetValue(), channel.ID)
+		if err != nil {
+			return err
+		}
+
+		if permission != EditPermission {
+			return ErrUnauthorizedAccess
+		}
+
+		channel.Owner = shared.Owner
+	}
+
+	if err := ts.validateMetadata(ctx, channel.Owner, ChannelsEntity, channel.Metadata); err != nil {
+		return err
+	}
+
+	return ts.channels.Update(ctx, channel)
+}
+
+func (ts *thingsService) ViewChannel(ctx context.Context, token, id string) (Channel, error) {
+	res, err := ts.identify(ctx, token, RoleViewer)
+	if err != nil {
+		return Channel{}, ErrUnauthorizedAccess
+	}
+
+	channel, err := ts.channels.RetrieveByID(ctx, res.GetValue(), id)
+	if err != ErrNotFound {
+		return channel, err
+	}
+
+	channel, _, err = ts.sharedChannel(ctx, token, res.GetValue(), id)
+	return channel, err
+}
+
+func (ts *thingsService) ListChannels(ctx context.Context, token string, pq PageQuery) (ChannelsPage, error) {
+	res, err := ts.identify(ctx, token, RoleViewer)
+	if err != nil {
+		return ChannelsPage{}, ErrUnauthorizedAccess
+	}
+
+	pq.Order, pq.Dir, err = sorting(pq.Order, pq.Dir, pq.Cursor)
+	if err != nil {
+		return ChannelsPage{}, err
+	}
+
+	// Repositories are provided with the ID the cursor points to.
+	pq.Cursor, err = decodeCursor(pq.Cursor)
+	if err != nil {
+		return ChannelsPage{}, err
+	}
+
+	var groups []string
+	if pq.Shared {
+		if groups, err = ts.groups(ctx, token); err != nil {
+			return ChannelsPage{}, err
+		}
+	}
+
+	page, err := ts.channels.RetrieveAll(ctx, res.GetValue(), pq, groups)
+	if err != nil {
+		return ChannelsPage{}, err
+	}
+
+	if n := len(page.Channels); n > 0 && uint64(n) == pq.Limit && pq.Order == OrderByID {
+		page.NextCursor = encodeCursor(page.Channels[n-1].ID)
+	}
+
+	return page, nil
+}
+
+func (ts *thingsService) SearchChannels(ctx context.Context, token, query string, offset, limit uint64) (ChannelsPage, error) {
+	res, err := ts.identify(ctx, token, RoleViewer)
+	if err != nil {
+		return ChannelsPage{}, ErrUnauthorizedAccess
+	}
+
+	return ts.channels.Search(ctx, res.GetValue(), query, offset, limit)
+}
+
+func (ts *thingsService) ListChannelsByThing(ctx context.Context, token, thing string, offset, limit uint64) (ChannelsPage, error) {
+	res, err := ts.identify(ctx, token, RoleViewer)
+	if err != nil {
+		return ChannelsPage{}, ErrUnauthorizedAccess
+	}
+
+	return ts.channels.RetrieveByThing(ctx, res.GetValue(), thing, offset, limit)
+}
+
+func (ts *thingsService) RemoveChannel(ctx context.Context, token, id string) error {
+	res, err := ts.identify(ctx, token, RoleEditor)
+	if err != nil {
+		return ErrUnauthorizedAccess
+	}
+
+	ts.channelCache.Remove(ctx, id)
+	return ts.channels.Remove(ctx, res.GetValue(), id)
+}
+
+func (ts *thingsService) RestoreChannel(ctx context.Context, token, id string) error {
+	res, err := ts.identify(ctx, token, RoleEditor)
+	if err != nil {
+		return ErrUnauthorizedAccess
+	}
+
+	if err := ts.checkQuota(ctx, res.GetValue(), Usage{Channels: 1}); err != nil {
+		return err
+	}
+
+	return ts.channels.Restore(ctx, res.GetValue(), id)
+}
+
+func (ts *thingsService) PurgeChannel(ctx context.Context, token, id string) error {
+	res, err := ts.identify(ctx, token, RoleEditor)
+	if err != nil {
+		return ErrUnauthorizedAccess
+	}
+
+	ts.channelCache.Remove(ctx, id)
+	return ts.channels.Purge(ctx, res.GetValue(), id)
+}
+
+func (ts *thingsService) ShareChannel(ctx context.Context, token, id, group, permission string) error {
+	res, err := ts.identify(ctx, token, RoleEditor)
+	if err != nil {
+		return ErrUnauthorizedAccess
+	}
+
+	if !validPermission(permission) {
+		return ErrMalformedEntity
+	}
+
+	if err := ts.isMember(ctx, token, group); err != nil {
+		return err
+	}
+
+	channel, err := ts.channels.RetrieveByID(ctx, res.GetValue(), id)
+	if err != nil {
+		return err
+	}
+
+	if channel.Type == DirectChannel {
+		return ErrDirectChannel
+	}
+
+	return ts.channels.Share(ctx, res.GetValue(), id, group, permission)
+}
+
+func (ts *thingsService) UnshareChannel(ctx context.Context, token, id, group string) error {
+	res, err := ts.identify(ctx, token, RoleEditor)
+	if err != nil {
+		return ErrUnauthorizedAccess
+	}
+
+	return ts.channels.Unshare(ctx, res.GetValue(), id, group)
+}
+
+func (ts *thingsService) ShareChannelWithUser(ctx context.Context, token, id, email, permission string) error {
+	res, err := ts.identify(ctx, token, RoleEditor)
+	if err != nil {
+		return ErrUnauthorizedAccess
+	}
+
+	if !validPermission(permission) || email == res.GetValue() {
+		return ErrMalformedEntity
+	}
+
+	channel, err := ts.channels.RetrieveByID(ctx, res.GetValue(), id)
+	if err != nil {
+		return err
+	}
+
+	if channel.Type == DirectChannel {
+		return ErrDirectChannel
+	}
+
+	return ts.channels.ShareWithUser(ctx, res.GetValue(), id, email, permission)
+}
+
+func (ts *thingsService) UnshareChannelWithUser(ctx context.Context, token, id, email string) error {
+	res, err := ts.identify(ctx, token, RoleEditor)
+	if err != nil {
+		return ErrUnauthorizedAccess
+	}
+
+	return ts.channels.UnshareWithUser(ctx, res.GetValue(), id, email)
+}
+
+func (ts *thingsService) ExportChannels(ctx context.Context, token string, fn func(Channel) error) error {
+	res, err := ts.identify(ctx, token, RoleViewer)
+	if err != nil {
+		return ErrUnauthorizedAccess
+	}
+
+	return ts.channels.Export(ctx, res.GetValue(), fn)
+}
+
+func (ts *thingsService) ImportChannels(ctx context.Context, token string, chs []Channel) ([]Channel, error) {
+	res, err := ts.identify(ctx, token, RoleEditor)
+	if err != nil {
+		return nil, ErrUnauthorizedAccess
+	}
+
+	validate, err := ts.validator(ctx, res.GetValue(), ChannelsEntity)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ch := range chs {
+		if !ch.Retention.valid() || !validRegion(ch.Region) || !validAlias(ch.Alias) || ch.Type != "" || len(ch.Peers) > 0 {
+			return nil, ErrMalformedEntity
+		}
+
+		if err := validate(ch.Metadata); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := ts.checkQuota(ctx, res.GetValue(), Usage{Channels: uint64(len(chs))}); err != nil {
+		return nil, err
+	}
+
+	imported := []Channel{}
+	for _, ch := range chs {
+		ch.Owner = res.GetValue()
+
+		if ch.ID == "" {
+			if ch.ID, err = ts.idp.ID(); err != nil {
+				return imported, err
+			}
+		}
+
+		if ch.ID, err = ts.channels.Save(ctx, ch); err != nil {
+			return imported, err
+		}
+
+		imported = append(imported, ch)
+	}
+
+	return imported, nil
+}
+
+func (ts *thingsService) Connect(ctx context.Context, token, chanID, thingID string, actions []string) error {
+	res, err := ts.identify(ctx, token, RoleEditor)
+	if err != nil {
+		return ErrUnauthorizedAccess
+	}
+
+	if len(actions) == 0 {
+		actions = Actions
+	}
+
+	for _, action := range actions {
+		if !validAction(action) {
+			return ErrMalformedEntity
+		}
+	}
+
+	if err := ts.checkPeer(ctx, res.GetValue(), chanID, thingID); err != nil {
+		return err
+	}
+
+	conn := Connection{ChannelID: chanID, ThingID: thingID}
+	if err := ts.checkConnectionsQuota(ctx, res.GetValue(), []Connection{conn}); err != nil {
+		return err
+	}
+
+	if err := ts.channels.Connect(ctx, res.GetValue(), chanID, thingID, actions); err != nil {
+		return err
+	}
+
+	// Connecting already connected thing can revoke some of its actions.
+	ts.channelCache.Disconnect(ctx, chanID, thingID)
+	return nil
+}
+
+func (ts *thingsService) Disconnect(ctx context.Context, token, chanID, thingID string) error {
+	res, err := ts.identify(ctx, token, RoleEditor)
+	if err != nil {
+		return ErrUnauthorizedAccess
+	}
+
+	ts.channelCache.Disconnect(ctx, chanID, thingID)
+	return ts.channels.Disconnect(ctx, res.GetValue(), chanID, thingID)
+}
+
+func (ts *thingsService) ExportConnections(ctx context.Context, token string, fn func(Connection) error) error {
+	res, err := ts.identify(ctx, token, RoleViewer)
+	if err != nil {
+		return ErrUnauthorizedAccess
+	}
+
+	return ts.channels.ExportConnections(ctx, res.GetValue(), fn)
+}
+
+func (ts *thingsService) ImportConnections(ctx context.Context, token string, conns []Connection) ([]Connection, error) {
+	res, err := ts.identify(ctx, token, RoleEditor)
+	if err != nil {
+		return nil, ErrUnauthorizedAccess
+	}
+
+	if err := ts.checkConnectionsQuota(ctx, res.GetValue(), conns); err != nil {
+		return nil, err
+	}
+
+	imported := []Connection{}
+	for _, conn := range conns {
+		if len(conn.Actions) == 0 {
+			conn.Actions = Actions
+		}
+
+		for _, action := range conn.Actions {
+			if !validAction(action) {
+				return imported, ErrMalformedEntity
+			}
+		}
+
+		if err := ts.checkPeer(ctx, res.GetValue(), conn.ChannelID, conn.ThingID); err != nil {
+			return imported, err
+		}
+
+		if err := ts.channels.Connect(ctx, res.GetValue(), conn.ChannelID, conn.ThingID, conn.Actions); err != nil {
+			return imported, err
+		}
+
+		// Connecting already connected thing can revoke some of its actions.
+		ts.channelCache.Disconnect(ctx, conn.ChannelID, conn.ThingID)
+		imported = append(imported, conn)
+	}
+
+	return imported, nil
+}
+
+func (ts *thingsService) CanAccess(ctx context.Context, chanID, key, action string) (string, error) {
+	if !validAction(action) {
+		return "", ErrMalformedEntity
+	}
+
+	if ts.malformedKey(key) {
+		return "", ErrUnauthorizedAccess
+	}
+
+	key = ts.storedKey(key)
+	thingID, err := ts.hasThing(ctx, chanID, key, action)
+	if err == nil {
+		return thingID, nil
+	}
+
+	thingID, err = ts.channels.HasThing(ctx, chanID, key, action)
+	if err != nil {
+		return "", ErrUnauthorizedAccess
+	}
+
+	ts.cacheKey(ctx, key, thingID)
+	ts.channelCache.Connect(ctx, chanID, thingID, action)
+	return thingID, nil
+}
+
+func (ts *thingsService) CanAccessByID(ctx context.Context, chanID, thingID, action string) error {
+	if !validAction(action) {
+		return ErrMalformedEntity
+	}
+
+	if connected := ts.channelCache.HasThing(ctx, chanID, thingID, action); connected {
+		return nil
+	}
+
+	if err := ts.channels.HasThingByID(ctx, chanID, thingID, action); err != nil {
+		return ErrUnauthorizedAccess
+	}
+
+	ts.channelCache.Connect(ctx, chanID, thingID, action)
+	return nil
+}
+
+func (ts *thingsService) Identify(ctx context.Context, key string) (string, error) {
+	if ts.malformedKey(key) {
+		return "", ErrUnauthorizedAccess
+	}
+
+	key = ts.storedKey(key)
+	id, err := ts.thingCache.ID(ctx, key)
+	if err == nil {
+		return id, nil
+	}
+
+	id, err = ts.things.RetrieveByKey(ctx, key)
+	if err != nil {
+		return "", ErrUnauthorizedAccess
+	}
+
+	ts.cacheKey(ctx, key, id)
+	return id, nil
+}
+
+func (ts *thingsService) Owner(ctx context.Context, id string) (string, error) {
+	return ts.things.RetrieveOwner(ctx, id)
+}
+
+func (ts *thingsService) KeyDigest(ctx context.Context, id string) (string, error) {
+	owner, err := ts.things.RetrieveOwner(ctx, id)
+	if err != nil {
+		return "", err
+	}
+
+	thing, err := ts.things.RetrieveByID(ctx, owner, id)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256([]byte(thing.Key))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func (ts *thingsService) Retention(ctx context.Context, id string) (Retention, error) {
+	return ts.channels.RetrieveRetention(ctx, id)
+}
+
+func (ts *thingsService) Region(ctx context.Context, id string) (string, error) {
+	return ts.channels.RetrieveRegion(ctx, id)
+}
+
+func (ts *thingsService) Alias(ctx context.Context, id string) (string, error) {
+	return ts.channels.RetrieveAlias(ctx, id)
+}
+
+func (ts *thingsService) ResolveAlias(ctx context.Context, alias string) (string, error) {
+	return ts.channels.RetrieveByAlias(ctx, alias)
+}
+
+func (ts *thingsService) Ordering(ctx context.Context, id string) (bool, error) {
+	return ts.channels.RetrieveOrdering(ctx, id)
+}
+
+func (ts *thingsService) Transformer(ctx context.Context, id string) (Transformer, error) {
+	md, err := ts.channels.RetrieveMetadata(ctx, id)
+	if err != nil {
+		return Transformer{}, err
+	}
+
+	return transformer(md), nil
+}
+
+func (ts *thingsService) UpdateQuota(ctx context.Context, token string, quota Quota) error {
+	if err := ts.identifyAdmin(ctx, token); err != nil {
+		return err
+	}
+
+	if !quota.valid() {
+		return ErrMalformedEntity
+	}
+
+	return ts.quotas.Save(ctx, quota)
+}
+
+func (ts *thingsService) ViewQuota(ctx context.Context, token, owner string) (Quota, Usage, error) {
+	res, err := ts.identify(ctx, token, RoleViewer)
+	if err != nil {
+		return Quota{}, Usage{}, ErrUnauthorizedAccess
+	}
+
+	if res.GetValue() != owner && !ts.isAdmin(res) {
+		return Quota{}, Usage{}, ErrUnauthorizedAccess
+	}
+
+	quota, err := ts.quota(ctx, owner)
+	if err != nil {
+		return Quota{}, Usage{}, err
+	}
+
+	usage, err := ts.quotas.RetrieveUsage(ctx, owner)
+	if err != nil {
+		return Quota{}, Usage{}, err
+	}
+
+	return quota, usage, nil
+}
+
+func (ts *thingsService) RemoveQuota(ctx context.Context, token, owner string) error {
+	if err := ts.identifyAdmin(ctx, token); err != nil {
+		return err
+	}
+
+	return ts.quotas.Remove(ctx, owner)
+}
+
+func (ts *thingsService) TransferOwnership(ctx context.Context, token, from, to string) error {
+	if err := ts.identifyAdmin(ctx, token); err != nil {
+		return err
+	}
+
+	if from == "" || to == "" || from == to {
+		return ErrMalformedEntity
+	}
+
+	disabled, err := ts.disabled(ctx, from)
+	switch {
+	case err == ErrNotFound:
+	case err != nil:
+		return err
+	case !disabled:
+		return ErrActiveOwner
+	}
+
+	disabled, err = ts.disabled(ctx, to)
+	if err != nil {
+		return err
+	}
+	if disabled {
+		return ErrMalformedEntity
+	}
+
+	usage, err := ts.quotas.RetrieveUsage(ctx, from)
+	if err != nil {
+		return err
+	}
+
+	if err := ts.checkQuota(ctx, to, usage); err != nil {
+		return err
+	}
+
+	return ts.channels.Transfer(ctx, from, to)
+}
+
+func (ts *thingsService) SaveSchema(ctx context.Context, token string, schema MetadataSchema) error {
+	res, err := ts.identify(ctx, token, RoleEditor)
+	if err != nil {
+		return ErrUnauthorizedAccess
+	}
+
+	if !validEntity(schema.Entity) {
+		return ErrMalformedEntity
+	}
+
+	if _, err := schema.compile(); err != nil {
+		return err
+	}
+
+	schema.Owner = res.GetValue()
+	return ts.schemas.Save(ctx, schema)
+}
+
+func (ts *thingsService) ViewSchema(ctx context.Context, token, entity string) (MetadataSchema, error) {
+	res, err := ts.identify(ctx, token, RoleViewer)
+	if err != nil {
+		return MetadataSchema{}, ErrUnauthorizedAccess
+	}
+
+	if !validEntity(entity) {
+		return MetadataSchema{}, ErrMalformedEntity
+	}
+
+	return ts.schemas.RetrieveByEntity(ctx, res.GetValue(), entity)
+}
+
+func (ts *thingsService) RemoveSchema(ctx context.Context, token, entity string) error {
+	res, err := ts.identify(ctx, token, RoleEditor)
+	if err != nil {
+		return ErrUnauthorizedAccess
+	}
+
+	if !validEntity(entity) {
+		return ErrMalformedEntity
+	}
+
+	return ts.schemas.Remove(ctx, res.GetValue(), entity)
+}
+
+func (ts *thingsService) SavePayloadSchema(ctx context.Context, token string, schema PayloadSchema) error {
+	res, err := ts.identify(ctx, token, RoleEditor)
+	if err != nil {
+		return ErrUnauthorizedAccess
+	}
+
+	if !validSubtopic(schema.Subtopic) {
+		return ErrMalformedEntity
+	}
+
+	if _, err := compile(schema.Definition); err != nil {
+		return err
+	}
+
+	if _, err := ts.channels.RetrieveByID(ctx, res.GetValue(), schema.Channel); err != nil {
+		return err
+	}
+
+	schema.Owner = res.GetValue()
+	return ts.schemas.SavePayload(ctx, schema)
+}
+
+func (ts *thingsService) ListPayloadSchemas(ctx context.Context, token, chanID string) ([]PayloadSchema, error) {
+	res, err := ts.identify(ctx, token, RoleViewer)
+	if err != nil {
+		return nil, ErrUnauthorizedAccess
+	}
+
+	if _, err := ts.channels.RetrieveByID(ctx, res.GetValue(), chanID); err != nil {
+		return nil, err
+	}
+
+	return ts.schemas.RetrievePayloads(ctx, chanID)
+}
+
+func (ts *thingsService) RemovePayloadSchema(ctx context.Context, token, chanID, subtopic string) error {
+	res, err := ts.identify(ctx, token, RoleEditor)
+	if err != nil {
+		return ErrUnauthorizedAccess
+	}
+
+	if _, err := ts.channels.RetrieveByID(ctx, res.GetValue(), chanID); err != nil {
+		return err
+	}
+
+	return ts.schemas.RemovePayload(ctx, chanID, subtopic)
+}
+
+func (ts *thingsService) PayloadSchema(ctx context.Context, chanID, subtopic string) (PayloadSchema, error) {
+	schema, err := ts.schemas.RetrievePayload(ctx, chanID, subtopic)
+	if err != ErrNotFound || subtopic == "" {
+		return schema, err
+	}
+
+	return ts.schemas.RetrievePayload(ctx, chanID, "")
+}
+
+func (ts *thingsService) ListChanges(ctx context.Context, token, since string, limit uint64) (ChangesPage, error) {
+	res, err := ts.identify(ctx, token, RoleViewer)
+	if err != nil {
+		return ChangesPage{}, ErrUnauthorizedAccess
+	}
+
+	if ts.changes == nil {
+		return ChangesPage{Changes: []Change{}, Next: since}, nil
+	}
+
+	return ts.changes.RetrieveAll(ctx, res.GetValue(), since, limit)
+}
+
+func (ts *thingsService) ViewAPIUsage(ctx context.Context, token string, from, to time.Time) ([]APIUsage, error) {
+	res, err := ts.identify(ctx, token, RoleViewer)
+	if err != nil {
+		return nil, ErrUnauthorizedAccess
+	}
+
+	if !from.Before(to) || to.Sub(from) > maxUsageRange {
+		return nil, ErrMalformedEntity
+	}
+
+	if ts.usage == nil {
+		return []APIUsage{}, nil
+	}
+
+	return ts.usage.RetrieveByOwner(ctx, res.GetValue(), from, to)
+}
+
+func (ts *thingsService) hasThing(ctx context.Context, chanID, key, action string) (string, error) {
+	thingID, err := ts.thingCache.ID(ctx, key)
+	if err != nil {
+		return "", err
+	}
+
+	if connected := ts.channelCache.HasThing(ctx, chanID, thingID, action); !connected {
+		return "", ErrUnauthorizedAccess
+	}
+
+	return thingID, nil
+}
+
+// cacheKey caches the key only if it's the current key of the thing. Previous
+// keys of rotated things aren't cached, since the cache doesn't expire them.
+func (ts *thingsService) cacheKey(ctx context.Context, key, id string) {
+	owner, err := ts.things.RetrieveOwner(ctx, id)
+	if err != nil {
+		return
+	}
+
+	thing, err := ts.things.RetrieveByID(ctx, owner, id)
+	if err != nil || thing.Key != key {
+		return
+	}
+
+	ts.thingCache.Save(ctx, key, id)
+}
+
+// newKey generates the key of the new or rotated thing.
+func (ts *thingsService) newKey() (string, error) {
+	if ts.keys == nil {
+		return ts.idp.ID()
+	}
+
+	return ts.keys.Key()
+}
+
+// malformedKey checks whether the key can be rejected without looking it up.
+func (ts *thingsService) malformedKey(key string) bool {
+	return ts.keys != nil && ts.keys.Malformed(key)
+}
+
+// storedKey returns the key in the form it's stored and cached in.
+func (ts *thingsService) storedKey(key string) string {
+	if ts.hasher == nil {
+		return key
+	}
+
+	return ts.hasher.Hash(key)
+}
+
+// hideKey hides the hashed key of the thing, since it can't be used to
+// authenticate the thing.
+func (ts *thingsService) hideKey(thing Thing) Thing {
+	if ts.hasher != nil {
+		thing.Key = ""
+	}
+
+	return thing
+}
+
+func (ts *thingsService) groups(ctx context.Context, token string) ([]string, error) {
+	res, err := ts.users.Groups(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return nil, ErrUnauthorizedAccess
+	}
+
+	return res.GetValue(), nil
+}
+
+// isMember checks if the user identified by the provided key is member of
+// the group, since entities can be shared only with user's own groups.
+func (ts *thingsService) isMember(ctx context.Context, token, group string) error {
+	groups, err := ts.groups(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	for _, g := range groups {
+		if g == group {
+			return nil
+		}
+	}
+
+	return ErrNotFound
+}
+
+// sharedThing retrieves the thing shared with the user identified by the
+// provided key or with the user's groups. Thing key is hidden from the users
+// it is shared with.
+func (ts *thingsService) sharedThing(ctx context.Context, token, user, id string) (Thing, string, error) {
+	groups, err := ts.groups(ctx, token)
+	if err != nil {
+		return Thing{}, "", err
+	}
+
+	thing, permission, err := ts.things.RetrieveShared(ctx, id, user, groups)
+	if err != nil {
+		return Thing{}, "", err
+	}
+
+	thing.Key = ""
+	return thing, permission, nil
+}
+
+// sharedChannel retrieves the channel shared with the user identified by the
+// provided key or with the user's groups.
+func (ts *thingsService) sharedChannel(ctx context.Context, token, user, id string) (Channel, string, error) {
+	groups, err := ts.groups(ctx, token)
+	if err != nil {
+		return Channel{}, "", err
+	}
+
+	return ts.channels.RetrieveShared(ctx, id, user, groups)
+}
+
+// identify identifies the user by the provided token, given that the user's
+// role grants the required one.
+func (ts *thingsService) identify(ctx context.Context, token, role string) (*mainflux.UserID, error) {
+	res, err := ts.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return nil, ErrUnauthorizedAccess
+	}
+
+	if !hasRole(res.GetRole(), role) {
+		return nil, ErrUnauthorizedAccess
+	}
+
+	return res, nil
+}
+
+// identifyAdmin checks if the user identified by the provided key is one of
+// the admins, either configured or assigned the admin role.
+func (ts *thingsService) identifyAdmin(ctx context.Context, token string) error {
+	res, err := ts.identify(ctx, token, RoleViewer)
+	if err != nil {
+		return ErrUnauthorizedAccess
+	}
+
+	if !ts.isAdmin(res) {
+		return ErrUnauthorizedAccess
+	}
+
+	return nil
+}
+
+// isAdmin checks if the identified user is one of the configured admins or
+// is assigned the admin role.
+func (ts *thingsService) isAdmin(res *mainflux.UserID) bool {
+	return ts.admins[res.GetValue()] || res.GetRole() == RoleAdmin
+}
+
+// disabled checks if the user with the provided email is disabled. It
+// returns ErrNotFound if the user doesn't exist.
+func (ts *thingsService) disabled(ctx context.Context, email string) (bool, error) {
+	res, err := ts.users.Disabled(ctx, &mainflux.UserID{Value: email})
+	if status.Code(err) == codes.NotFound {
+		return false, ErrNotFound
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return res.GetDisabled(), nil
+}
+
+// quota retrieves the quota of the owner, falling back to the default quota
+// if it isn't overridden.
+func (ts *thingsService) quota(ctx context.Context, owner string) (Quota, error) {
+	quota, err := ts.quotas.RetrieveByOwner(ctx, owner)
+	if err == ErrNotFound {
+		quota = ts.defQuota
+		quota.Owner = owner
+		return quota, nil
+	}
+
+	return quota, err
+}
+
+// checkQuota checks if the owner is allowed to add the provided number of
+// things, channels and connections. Since the check and the addition aren't
+// atomic, concurrent requests may slightly exceed the quota.
+func (ts *thingsService) checkQuota(ctx context.Context, owner string, added Usage) error {
+	quota, err := ts.quota(ctx, owner)
+	if err != nil {
+		return err
+	}
+
+	return ts.checkUsage(ctx, quota, added)
+}
+
+// checkUsage checks if adding the provided number of entities to the ones
+// the quota owner has exceeds the quota. Entities are counted only if some
+// of the added ones are limited.
+func (ts *thingsService) checkUsage(ctx context.Context, quota Quota, added Usage) error {
+	if !limited(quota.Things, added.Things) &&
+		!limited(quota.Channels, added.Channels) &&
+		!limited(quota.Connections, added.Connections) {
+		return nil
+	}
+
+	used, err := ts.quotas.RetrieveUsage(ctx, quota.Owner)
+	if err != nil {
+		return err
+	}
+
+	if exceeds(quota.Things, used.Things, added.Things) ||
+		exceeds(quota.Channels, used.Channels, added.Channels) ||
+		exceeds(quota.Connections, used.Connections, added.Connections) {
+		return ErrQuotaExceeded
+	}
+
+	return nil
+}
+
+// checkConnectionsQuota checks if the owner is allowed to add the provided
+// connections. Connecting already connected things doesn't add new
+// connections, so they're not counted.
+func (ts *thingsService) checkConnectionsQuota(ctx context.Context, owner string, conns []Connection) error {
+	quota, err := ts.quota(ctx, owner)
+	if err != nil {
+		return err
+	}
+
+	if quota.Connections == 0 {
+		return nil
+	}
+
+	added := Usage{}
+	for _, conn := range conns {
+		if !ts.connected(ctx, conn.ChannelID, conn.ThingID) {
+			added.Connections++
+		}
+	}
+
+	return ts.checkUsage(ctx, quota, added)
+}
+
+// checkPeer checks if the thing is allowed to be connected to the channel
+// owned by the owner. Only the peers can be connected to the direct channel.
+func (ts *thingsService) checkPeer(ctx context.Context, owner, chanID, thingID string) error {
+	channel, err := ts.channels.RetrieveByID(ctx, owner, chanID)
+	if err != nil {
+		return err
+	}
+
+	if !channel.isPeer(thingID) {
+		return ErrDirectChannel
+	}
+
+	return nil
+}
+
+// connected checks if the thing is connected to the channel, regardless of
+// the actions it is allowed to perform.
+func (ts *thingsService) connected(ctx context.Context, chanID, thingID string) bool {
+	for _, action := range Actions {
+		if err := ts.channels.HasThingByID(ctx, chanID, thingID, action); err == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// validator returns the function that checks the metadata against the
+// schema of the entities registered by the owner. Metadata of the owners
+// that haven't registered the schema isn't validated.
+func (ts *thingsService) validator(ctx context.Context, owner, entity string) (func(Metadata) error, error) {
+	ms, err := ts.schemas.RetrieveByEntity(ctx, owner, entity)
+	if err == ErrNotFound {
+		return func(Metadata) error { return nil }, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	schema, err := ms.compile()
+	if err != nil {
+		return nil, err
+	}
+
+	return func(metadata Metadata) error {
+		return validate(schema, metadata)
+	}, nil
+}
+
+func (ts *thingsService) validateMetadata(ctx context.Context, owner, entity string, metadata Metadata) error {
+	validate, err := ts.validator(ctx, owner, entity)
+	if err != nil {
+		return err
+	}
+
+	return validate(metadata)
+}
+
+func validAction(action string) bool {
+	for _, a := range Actions {
+		if a == action {
+			return true
+		}
+	}
+
+	return false
+}
+
+// encodeCursor hides the ID of the last retrieved entity behind an opaque
+// value, so that clients do not rely on its format.
+func encodeCursor(id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(id))
+}
+
+// sorting validates listing order and direction, replacing empty values
+// with the defaults. Since cursor is an entity ID, it can't be combined with
+// any other order.
+func sorting(order, dir, cursor string) (string, string, error) {
+	if order == "" {
+		order = OrderByID
+	}
+	if dir == "" {
+		dir = AscDir
+	}
+
+	switch order {
+	case OrderByID, OrderByName, OrderByCreatedAt:
+	default:
+		return "", "", ErrMalformedEntity
+	}
+
+	if dir != AscDir && dir != DescDir {
+		return "", "", ErrMalformedEntity
+	}
+
+	if cursor != "" && order != OrderByID {
+		return "", "", ErrMalformedEntity
+	}
+
+	return order, dir, nil
+}
+
+func decodeCursor(cursor string) (string, error) {
+	if cursor == "" {
+		return "", nil
+	}
+
+	id, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || len(id) == 0 {
+		return "", ErrMalformedEntity
+	}
+
+	return string(id), nil
 }
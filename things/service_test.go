@@ -5,11 +5,17 @@ package things_test
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"math"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/mainflux/mainflux/things"
+	"github.com/mainflux/mainflux/things/hmac"
+	"github.com/mainflux/mainflux/things/keys"
 	"github.com/mainflux/mainflux/things/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -20,6 +26,8 @@ const (
 	wrongValue = "wrong-value"
 	email      = "user@example.com"
 	token      = "token"
+	keyGrace   = time.Hour
+	keySecret  = "secret"
 )
 
 var (
@@ -32,11 +40,37 @@ func newService(tokens map[string]string) things.Service {
 	conns := make(chan mocks.Connection)
 	thingsRepo := mocks.NewThingRepository(conns)
 	channelsRepo := mocks.NewChannelRepository(thingsRepo, conns)
+	quotasRepo := mocks.NewQuotaRepository(thingsRepo, channelsRepo)
+	schemasRepo := mocks.NewSchemaRepository()
 	chanCache := mocks.NewChannelCache()
 	thingCache := mocks.NewThingCache()
 	idp := mocks.NewIdentityProvider()
 
-	return things.New(users, thingsRepo, channelsRepo, chanCache, thingCache, idp)
+	return things.New(users, thingsRepo, channelsRepo, quotasRepo, schemasRepo, chanCache, thingCache, idp, nil, things.Quota{}, nil, keyGrace, 0, nil, nil, nil)
+}
+
+const (
+	group       = "123e4567-e89b-12d3-a456-000000000001"
+	memberEmail = "member@example.com"
+	memberToken = "member-token"
+	otherEmail  = "other@example.com"
+	otherToken  = "other-token"
+)
+
+func newSharingService() things.Service {
+	tokens := map[string]string{token: email, memberToken: memberEmail, otherToken: otherEmail}
+	groups := map[string][]string{email: {group}, memberEmail: {group}}
+	users := mocks.NewUsersServiceWithGroups(tokens, groups)
+	conns := make(chan mocks.Connection)
+	thingsRepo := mocks.NewThingRepository(conns)
+	channelsRepo := mocks.NewChannelRepository(thingsRepo, conns)
+	quotasRepo := mocks.NewQuotaRepository(thingsRepo, channelsRepo)
+	schemasRepo := mocks.NewSchemaRepository()
+	chanCache := mocks.NewChannelCache()
+	thingCache := mocks.NewThingCache()
+	idp := mocks.NewIdentityProvider()
+
+	return things.New(users, thingsRepo, channelsRepo, quotasRepo, schemasRepo, chanCache, thingCache, idp, nil, things.Quota{}, nil, keyGrace, 0, nil, nil, nil)
 }
 
 func TestAddThing(t *testing.T) {
@@ -132,6 +166,13 @@ func TestUpdateKey(t *testing.T) {
 			key:   key,
 			err:   things.ErrUnauthorizedAccess,
 		},
+		{
+			desc:  "update key of an existing thing to the current key",
+			token: token,
+			id:    saved.ID,
+			key:   key,
+			err:   nil,
+		},
 		{
 			desc:  "update key of non-existing thing",
 			token: token,
@@ -147,6 +188,257 @@ func TestUpdateKey(t *testing.T) {
 	}
 }
 
+func TestRotateKey(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	saved, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc  string
+		token string
+		id    string
+		err   error
+	}{
+		{
+			desc:  "rotate key of an existing thing",
+			token: token,
+			id:    saved.ID,
+			err:   nil,
+		},
+		{
+			desc:  "rotate key with invalid credentials",
+			token: wrongValue,
+			id:    saved.ID,
+			err:   things.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "rotate key of non-existing thing",
+			token: token,
+			id:    wrongID,
+			err:   things.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := svc.RotateKey(context.Background(), tc.token, tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestRotatedKeyIdentify(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	saved, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	key, err := svc.RotateKey(context.Background(), token, saved.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.NotEqual(t, saved.Key, key, "rotate key: expected new key to differ from the previous one")
+
+	for desc, k := range map[string]string{
+		"identify thing with new key":                   key,
+		"identify thing with previous key during grace": saved.Key,
+	} {
+		id, err := svc.Identify(context.Background(), k)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s\n", desc, err))
+		assert.Equal(t, saved.ID, id, fmt.Sprintf("%s: expected %s got %s\n", desc, saved.ID, id))
+	}
+}
+
+func newKeyOverlapService(tokens map[string]string, overlap time.Duration) things.Service {
+	users := mocks.NewUsersService(tokens)
+	conns := make(chan mocks.Connection)
+	thingsRepo := mocks.NewThingRepository(conns)
+	channelsRepo := mocks.NewChannelRepository(thingsRepo, conns)
+	quotasRepo := mocks.NewQuotaRepository(thingsRepo, channelsRepo)
+	schemasRepo := mocks.NewSchemaRepository()
+	chanCache := mocks.NewChannelCache()
+	thingCache := mocks.NewThingCache()
+	idp := mocks.NewIdentityProvider()
+
+	return things.New(users, thingsRepo, channelsRepo, quotasRepo, schemasRepo, chanCache, thingCache, idp, nil, things.Quota{}, nil, keyGrace, overlap, nil, nil, nil)
+}
+
+func TestUpdatedKeyIdentify(t *testing.T) {
+	cases := []struct {
+		desc    string
+		overlap time.Duration
+		err     error
+	}{
+		{
+			desc:    "identify thing with previous key without overlap period",
+			overlap: 0,
+			err:     things.ErrUnauthorizedAccess,
+		},
+		{
+			desc:    "identify thing with previous key during overlap period",
+			overlap: time.Hour,
+			err:     nil,
+		},
+	}
+
+	for _, tc := range cases {
+		svc := newKeyOverlapService(map[string]string{token: email}, tc.overlap)
+		saved, err := svc.AddThing(context.Background(), token, thing)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s\n", tc.desc, err))
+
+		// Previous key is cached before the update, so that the update has
+		// to invalidate it.
+		_, err = svc.Identify(context.Background(), saved.Key)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s\n", tc.desc, err))
+
+		err = svc.UpdateKey(context.Background(), token, saved.ID, "new-key")
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s\n", tc.desc, err))
+
+		id, err := svc.Identify(context.Background(), "new-key")
+		assert.Nil(t, err, fmt.Sprintf("%s: identify thing with new key: unexpected error: %s\n", tc.desc, err))
+		assert.Equal(t, saved.ID, id, fmt.Sprintf("%s: identify thing with new key: expected %s got %s\n", tc.desc, saved.ID, id))
+
+		_, err = svc.Identify(context.Background(), saved.Key)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func newHashingService(tokens map[string]string) things.Service {
+	users := mocks.NewUsersService(tokens)
+	conns := make(chan mocks.Connection)
+	thingsRepo := mocks.NewThingRepository(conns)
+	channelsRepo := mocks.NewChannelRepository(thingsRepo, conns)
+	quotasRepo := mocks.NewQuotaRepository(thingsRepo, channelsRepo)
+	schemasRepo := mocks.NewSchemaRepository()
+	chanCache := mocks.NewChannelCache()
+	thingCache := mocks.NewThingCache()
+	idp := mocks.NewIdentityProvider()
+
+	return things.New(users, thingsRepo, channelsRepo, quotasRepo, schemasRepo, chanCache, thingCache, idp, nil, things.Quota{}, nil, keyGrace, 0, hmac.New(keySecret), nil, nil)
+}
+
+func TestHashedKeys(t *testing.T) {
+	svc := newHashingService(map[string]string{token: email})
+	saved, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.NotEmpty(t, saved.Key, "add thing: expected generated key to be returned")
+
+	sch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	err = svc.Connect(context.Background(), token, sch.ID, saved.ID, nil)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	viewed, err := svc.ViewThing(context.Background(), token, saved.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.Empty(t, viewed.Key, "view thing: expected hashed key to be hidden")
+
+	page, err := svc.ListThings(context.Background(), token, things.PageQuery{Limit: 10})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	for _, th := range page.Things {
+		assert.Empty(t, th.Key, "list things: expected hashed key to be hidden")
+	}
+
+	cases := map[string]struct {
+		key string
+		id  string
+		err error
+	}{
+		"identify thing with plain-text key": {
+			key: saved.Key,
+			id:  saved.ID,
+			err: nil,
+		},
+		"identify thing with hashed key": {
+			key: hmac.New(keySecret).Hash(saved.Key),
+			id:  "",
+			err: things.ErrUnauthorizedAccess,
+		},
+	}
+
+	// Identification is repeated to cover cached keys as well.
+	for i := 0; i < 2; i++ {
+		for desc, tc := range cases {
+			id, err := svc.Identify(context.Background(), tc.key)
+			assert.Equal(t, tc.id, id, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.id, id))
+			assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+
+			id, err = svc.CanAccess(context.Background(), sch.ID, tc.key, things.Publish)
+			assert.Equal(t, tc.id, id, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.id, id))
+			assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+		}
+	}
+
+	key, err := svc.RotateKey(context.Background(), token, saved.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	for desc, k := range map[string]string{
+		"identify thing with rotated key":               key,
+		"identify thing with previous key during grace": saved.Key,
+	} {
+		id, err := svc.Identify(context.Background(), k)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s\n", desc, err))
+		assert.Equal(t, saved.ID, id, fmt.Sprintf("%s: expected %s got %s\n", desc, saved.ID, id))
+	}
+}
+
+func newKeyProvidingService(tokens map[string]string) things.Service {
+	users := mocks.NewUsersService(tokens)
+	conns := make(chan mocks.Connection)
+	thingsRepo := mocks.NewThingRepository(conns)
+	channelsRepo := mocks.NewChannelRepository(thingsRepo, conns)
+	quotasRepo := mocks.NewQuotaRepository(thingsRepo, channelsRepo)
+	schemasRepo := mocks.NewSchemaRepository()
+	chanCache := mocks.NewChannelCache()
+	thingCache := mocks.NewThingCache()
+	idp := mocks.NewIdentityProvider()
+
+	return things.New(users, thingsRepo, channelsRepo, quotasRepo, schemasRepo, chanCache, thingCache, idp, keys.New(keys.MinLength), things.Quota{}, nil, keyGrace, 0, nil, nil, nil)
+}
+
+func TestProvidedKeys(t *testing.T) {
+	svc := newKeyProvidingService(map[string]string{token: email})
+	saved, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.False(t, keys.Malformed(saved.Key), fmt.Sprintf("add thing: expected generated key %s to be valid", saved.Key))
+	assert.True(t, strings.HasPrefix(saved.Key, keys.Prefix), fmt.Sprintf("add thing: expected generated key %s to be prefixed", saved.Key))
+
+	rotated, err := svc.RotateKey(context.Background(), token, saved.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.False(t, keys.Malformed(rotated), fmt.Sprintf("rotate key: expected generated key %s to be valid", rotated))
+
+	custom := things.Thing{Name: "custom", Key: "custom-key"}
+	_, err = svc.AddThing(context.Background(), token, custom)
+	assert.Nil(t, err, fmt.Sprintf("add thing with custom key: unexpected error: %s\n", err))
+
+	malformed := things.Thing{Name: "malformed", Key: keys.Prefix + "malformed"}
+	_, err = svc.AddThing(context.Background(), token, malformed)
+	assert.Equal(t, things.ErrMalformedEntity, err, fmt.Sprintf("add thing with malformed key: expected %s got %s\n", things.ErrMalformedEntity, err))
+
+	err = svc.UpdateKey(context.Background(), token, saved.ID, keys.Prefix+"malformed")
+	assert.Equal(t, things.ErrMalformedEntity, err, fmt.Sprintf("update key to malformed key: expected %s got %s\n", things.ErrMalformedEntity, err))
+
+	_, err = svc.ImportThings(context.Background(), token, []things.Thing{{Name: "imported"}, malformed})
+	assert.Equal(t, things.ErrMalformedEntity, err, fmt.Sprintf("import thing with malformed key: expected %s got %s\n", things.ErrMalformedEntity, err))
+
+	cases := map[string]struct {
+		key string
+		id  string
+		err error
+	}{
+		"identify thing with generated key": {
+			key: rotated,
+			id:  saved.ID,
+			err: nil,
+		},
+		"identify thing with malformed key": {
+			key: keys.Prefix + "malformed",
+			id:  "",
+			err: things.ErrUnauthorizedAccess,
+		},
+	}
+
+	for desc, tc := range cases {
+		id, err := svc.Identify(context.Background(), tc.key)
+		assert.Equal(t, tc.id, id, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.id, id))
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
+
 func TestViewThing(t *testing.T) {
 	svc := newService(map[string]string{token: email})
 	saved, _ := svc.AddThing(context.Background(), token, thing)
@@ -195,6 +487,9 @@ func TestListThings(t *testing.T) {
 		token    string
 		offset   uint64
 		limit    uint64
+		cursor   string
+		order    string
+		dir      string
 		name     string
 		size     uint64
 		metadata map[string]interface{}
@@ -235,6 +530,56 @@ func TestListThings(t *testing.T) {
 			size:   0,
 			err:    nil,
 		},
+		"list with invalid cursor": {
+			token:  token,
+			offset: 0,
+			limit:  n,
+			cursor: "%",
+			size:   0,
+			err:    things.ErrMalformedEntity,
+		},
+		"list ordered by name in descending direction": {
+			token:  token,
+			offset: 0,
+			limit:  n,
+			order:  things.OrderByName,
+			dir:    things.DescDir,
+			size:   n,
+			err:    nil,
+		},
+		"list ordered by creation time": {
+			token:  token,
+			offset: 0,
+			limit:  n,
+			order:  things.OrderByCreatedAt,
+			size:   n,
+			err:    nil,
+		},
+		"list with invalid order": {
+			token:  token,
+			offset: 0,
+			limit:  n,
+			order:  "key",
+			size:   0,
+			err:    things.ErrMalformedEntity,
+		},
+		"list with invalid direction": {
+			token:  token,
+			offset: 0,
+			limit:  n,
+			dir:    "up",
+			size:   0,
+			err:    things.ErrMalformedEntity,
+		},
+		"list by cursor ordered by name": {
+			token:  token,
+			offset: 0,
+			limit:  n,
+			cursor: "MQ",
+			order:  things.OrderByName,
+			size:   0,
+			err:    things.ErrMalformedEntity,
+		},
 		"list with wrong credentials": {
 			token:  wrongValue,
 			offset: 0,
@@ -253,63 +598,178 @@ func TestListThings(t *testing.T) {
 	}
 
 	for desc, tc := range cases {
-		page, err := svc.ListThings(context.Background(), tc.token, tc.offset, tc.limit, tc.name, tc.metadata)
+		page, err := svc.ListThings(context.Background(), tc.token, things.PageQuery{
+			Offset:   tc.offset,
+			Limit:    tc.limit,
+			Cursor:   tc.cursor,
+			Name:     tc.name,
+			Order:    tc.order,
+			Dir:      tc.dir,
+			Metadata: tc.metadata,
+		})
 		size := uint64(len(page.Things))
 		assert.Equal(t, tc.size, size, fmt.Sprintf("%s: expected %d got %d\n", desc, tc.size, size))
 		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
 	}
 }
 
-func TestListThingsByChannel(t *testing.T) {
+func TestListThingsByCursor(t *testing.T) {
 	svc := newService(map[string]string{token: email})
 
-	sch, err := svc.CreateChannel(context.Background(), token, channel)
-	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 	n := uint64(10)
 	for i := uint64(0); i < n; i++ {
-		sth, err := svc.AddThing(context.Background(), token, thing)
-		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
-		svc.Connect(context.Background(), token, sch.ID, sth.ID)
+		svc.AddThing(context.Background(), token, thing)
 	}
 
-	// Wait for things and channels to connect
-	time.Sleep(time.Second)
+	first, err := svc.ListThings(context.Background(), token, things.PageQuery{Limit: n / 2})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.NotEmpty(t, first.NextCursor, "expected next cursor for full page")
+
+	second, err := svc.ListThings(context.Background(), token, things.PageQuery{Offset: n, Limit: n / 2, Cursor: first.NextCursor})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	ids := map[string]bool{}
+	for _, th := range append(first.Things, second.Things...) {
+		ids[th.ID] = true
+	}
+	assert.Equal(t, int(n), len(ids), fmt.Sprintf("listing by cursor: expected %d distinct things got %d\n", n, len(ids)))
+
+	last, err := svc.ListThings(context.Background(), token, things.PageQuery{Limit: n + 1})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Empty(t, last.NextCursor, "expected no next cursor for partial page")
+}
+
+func TestSearchThings(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	n := uint64(10)
+	for i := uint64(0); i < n; i++ {
+		thing := things.Thing{
+			Name:     fmt.Sprintf("sensor-%d", i),
+			Metadata: map[string]interface{}{"building": "north"},
+		}
+		if i%2 == 0 {
+			thing.Metadata = map[string]interface{}{"building": "south"}
+		}
+		svc.AddThing(context.Background(), token, thing)
+	}
 
 	cases := map[string]struct {
-		token   string
-		channel string
-		offset  uint64
-		limit   uint64
-		size    uint64
-		err     error
+		token  string
+		query  string
+		offset uint64
+		limit  uint64
+		size   uint64
+		err    error
 	}{
-		"list all things by existing channel": {
-			token:   token,
-			channel: sch.ID,
-			offset:  0,
-			limit:   n,
-			size:    n,
-			err:     nil,
-		},
-		"list half of things by existing channel": {
-			token:   token,
-			channel: sch.ID,
-			offset:  n / 2,
-			limit:   n,
-			size:    n / 2,
-			err:     nil,
+		"search things by name": {
+			token:  token,
+			query:  "sensor-3",
+			offset: 0,
+			limit:  n,
+			size:   1,
+			err:    nil,
 		},
-		"list last thing by existing channel": {
-			token:   token,
-			channel: sch.ID,
-			offset:  n - 1,
-			limit:   n,
-			size:    1,
-			err:     nil,
+		"search things by metadata": {
+			token:  token,
+			query:  "south",
+			offset: 0,
+			limit:  n,
+			size:   n / 2,
+			err:    nil,
 		},
-		"list empty set of things by existing channel": {
-			token:   token,
-			channel: sch.ID,
+		"search things by name and metadata": {
+			token:  token,
+			query:  "sensor north",
+			offset: 0,
+			limit:  n,
+			size:   n / 2,
+			err:    nil,
+		},
+		"search things with offset": {
+			token:  token,
+			query:  "sensor",
+			offset: n - 1,
+			limit:  n,
+			size:   1,
+			err:    nil,
+		},
+		"search things without match": {
+			token:  token,
+			query:  "east",
+			offset: 0,
+			limit:  n,
+			size:   0,
+			err:    nil,
+		},
+		"search things with wrong credentials": {
+			token:  wrongValue,
+			query:  "sensor",
+			offset: 0,
+			limit:  n,
+			size:   0,
+			err:    things.ErrUnauthorizedAccess,
+		},
+	}
+
+	for desc, tc := range cases {
+		page, err := svc.SearchThings(context.Background(), tc.token, tc.query, tc.offset, tc.limit)
+		size := uint64(len(page.Things))
+		assert.Equal(t, tc.size, size, fmt.Sprintf("%s: expected %d got %d\n", desc, tc.size, size))
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
+
+func TestListThingsByChannel(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	sch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	n := uint64(10)
+	for i := uint64(0); i < n; i++ {
+		sth, err := svc.AddThing(context.Background(), token, thing)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		svc.Connect(context.Background(), token, sch.ID, sth.ID, nil)
+	}
+
+	// Wait for things and channels to connect
+	time.Sleep(time.Second)
+
+	cases := map[string]struct {
+		token   string
+		channel string
+		offset  uint64
+		limit   uint64
+		size    uint64
+		err     error
+	}{
+		"list all things by existing channel": {
+			token:   token,
+			channel: sch.ID,
+			offset:  0,
+			limit:   n,
+			size:    n,
+			err:     nil,
+		},
+		"list half of things by existing channel": {
+			token:   token,
+			channel: sch.ID,
+			offset:  n / 2,
+			limit:   n,
+			size:    n / 2,
+			err:     nil,
+		},
+		"list last thing by existing channel": {
+			token:   token,
+			channel: sch.ID,
+			offset:  n - 1,
+			limit:   n,
+			size:    1,
+			err:     nil,
+		},
+		"list empty set of things by existing channel": {
+			token:   token,
+			channel: sch.ID,
 			offset:  n + 1,
 			limit:   n,
 			size:    0,
@@ -391,6 +851,106 @@ func TestRemoveThing(t *testing.T) {
 	}
 }
 
+func TestRestoreThing(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	saved, _ := svc.AddThing(context.Background(), token, thing)
+	active, _ := svc.AddThing(context.Background(), token, thing)
+	svc.RemoveThing(context.Background(), token, saved.ID)
+
+	cases := []struct {
+		desc  string
+		id    string
+		token string
+		err   error
+	}{
+		{
+			desc:  "restore thing with wrong credentials",
+			id:    saved.ID,
+			token: wrongValue,
+			err:   things.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "restore removed thing",
+			id:    saved.ID,
+			token: token,
+			err:   nil,
+		},
+		{
+			desc:  "restore restored thing",
+			id:    saved.ID,
+			token: token,
+			err:   things.ErrNotFound,
+		},
+		{
+			desc:  "restore thing that is not removed",
+			id:    active.ID,
+			token: token,
+			err:   things.ErrNotFound,
+		},
+		{
+			desc:  "restore non-existing thing",
+			id:    wrongID,
+			token: token,
+			err:   things.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.RestoreThing(context.Background(), tc.token, tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	_, err := svc.ViewThing(context.Background(), token, saved.ID)
+	assert.Nil(t, err, fmt.Sprintf("view restored thing: expected no error got %s\n", err))
+}
+
+func TestPurgeThing(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	saved, _ := svc.AddThing(context.Background(), token, thing)
+	removed, _ := svc.AddThing(context.Background(), token, thing)
+	svc.RemoveThing(context.Background(), token, removed.ID)
+
+	cases := []struct {
+		desc  string
+		id    string
+		token string
+		err   error
+	}{
+		{
+			desc:  "purge thing with wrong credentials",
+			id:    saved.ID,
+			token: wrongValue,
+			err:   things.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "purge existing thing",
+			id:    saved.ID,
+			token: token,
+			err:   nil,
+		},
+		{
+			desc:  "purge removed thing",
+			id:    removed.ID,
+			token: token,
+			err:   nil,
+		},
+		{
+			desc:  "purge non-existing thing",
+			id:    wrongID,
+			token: token,
+			err:   nil,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.PurgeThing(context.Background(), tc.token, tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	err := svc.RestoreThing(context.Background(), token, removed.ID)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("restore purged thing: expected %s got %s\n", things.ErrNotFound, err))
+}
+
 func TestCreateChannel(t *testing.T) {
 	svc := newService(map[string]string{token: email})
 
@@ -420,6 +980,110 @@ func TestCreateChannel(t *testing.T) {
 	}
 }
 
+func TestCreateDirectChannel(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	th1, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	th2, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	direct, err := svc.CreateDirectChannel(context.Background(), token, th1.ID, th2.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.Equal(t, things.DirectChannel, direct.Type, fmt.Sprintf("expected type %s got %s\n", things.DirectChannel, direct.Type))
+
+	cases := []struct {
+		desc   string
+		thing1 string
+		thing2 string
+		token  string
+		id     string
+		err    error
+	}{
+		{
+			desc:   "create direct channel for paired things",
+			thing1: th1.ID,
+			thing2: th2.ID,
+			token:  token,
+			id:     direct.ID,
+			err:    nil,
+		},
+		{
+			desc:   "create direct channel for paired things in reverse order",
+			thing1: th2.ID,
+			thing2: th1.ID,
+			token:  token,
+			id:     direct.ID,
+			err:    nil,
+		},
+		{
+			desc:   "create direct channel for the same thing",
+			thing1: th1.ID,
+			thing2: th1.ID,
+			token:  token,
+			id:     "",
+			err:    things.ErrMalformedEntity,
+		},
+		{
+			desc:   "create direct channel for non-existing thing",
+			thing1: th1.ID,
+			thing2: wrongValue,
+			token:  token,
+			id:     "",
+			err:    things.ErrNotFound,
+		},
+		{
+			desc:   "create direct channel with wrong credentials",
+			thing1: th1.ID,
+			thing2: th2.ID,
+			token:  wrongValue,
+			id:     "",
+			err:    things.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		ch, err := svc.CreateDirectChannel(context.Background(), tc.token, tc.thing1, tc.thing2)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.id, ch.ID, fmt.Sprintf("%s: expected channel %s got %s\n", tc.desc, tc.id, ch.ID))
+	}
+}
+
+func TestDirectChannelScope(t *testing.T) {
+	svc := newSharingService()
+
+	th1, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	th2, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	th3, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	direct, err := svc.CreateDirectChannel(context.Background(), token, th1.ID, th2.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	_, err = svc.CanAccess(context.Background(), direct.ID, th1.Key, things.Publish)
+	assert.Nil(t, err, fmt.Sprintf("publishing to direct channel by peer: expected no error got %s\n", err))
+	_, err = svc.CanAccess(context.Background(), direct.ID, th2.Key, things.Subscribe)
+	assert.Nil(t, err, fmt.Sprintf("subscribing to direct channel by peer: expected no error got %s\n", err))
+
+	err = svc.Connect(context.Background(), token, direct.ID, th2.ID, []string{things.Subscribe})
+	assert.Nil(t, err, fmt.Sprintf("connecting peer to direct channel: expected no error got %s\n", err))
+
+	err = svc.Connect(context.Background(), token, direct.ID, th3.ID, nil)
+	assert.Equal(t, things.ErrDirectChannel, err, fmt.Sprintf("connecting other thing to direct channel: expected %s got %s\n", things.ErrDirectChannel, err))
+
+	conns := []things.Connection{{ChannelID: direct.ID, ThingID: th3.ID}}
+	_, err = svc.ImportConnections(context.Background(), token, conns)
+	assert.Equal(t, things.ErrDirectChannel, err, fmt.Sprintf("importing connection to direct channel: expected %s got %s\n", things.ErrDirectChannel, err))
+
+	err = svc.ShareChannel(context.Background(), token, direct.ID, group, things.ViewPermission)
+	assert.Equal(t, things.ErrDirectChannel, err, fmt.Sprintf("sharing direct channel: expected %s got %s\n", things.ErrDirectChannel, err))
+
+	_, err = svc.CreateChannel(context.Background(), token, things.Channel{Type: things.DirectChannel})
+	assert.Equal(t, things.ErrMalformedEntity, err, fmt.Sprintf("creating channel of direct type: expected %s got %s\n", things.ErrMalformedEntity, err))
+}
+
 func TestUpdateChannel(t *testing.T) {
 	svc := newService(map[string]string{token: email})
 	saved, _ := svc.CreateChannel(context.Background(), token, channel)
@@ -508,6 +1172,9 @@ func TestListChannels(t *testing.T) {
 		token    string
 		offset   uint64
 		limit    uint64
+		cursor   string
+		order    string
+		dir      string
 		size     uint64
 		name     string
 		err      error
@@ -548,6 +1215,56 @@ func TestListChannels(t *testing.T) {
 			size:   0,
 			err:    nil,
 		},
+		"list with invalid cursor": {
+			token:  token,
+			offset: 0,
+			limit:  n,
+			cursor: "%",
+			size:   0,
+			err:    things.ErrMalformedEntity,
+		},
+		"list ordered by name in descending direction": {
+			token:  token,
+			offset: 0,
+			limit:  n,
+			order:  things.OrderByName,
+			dir:    things.DescDir,
+			size:   n,
+			err:    nil,
+		},
+		"list ordered by creation time": {
+			token:  token,
+			offset: 0,
+			limit:  n,
+			order:  things.OrderByCreatedAt,
+			size:   n,
+			err:    nil,
+		},
+		"list with invalid order": {
+			token:  token,
+			offset: 0,
+			limit:  n,
+			order:  "key",
+			size:   0,
+			err:    things.ErrMalformedEntity,
+		},
+		"list with invalid direction": {
+			token:  token,
+			offset: 0,
+			limit:  n,
+			dir:    "up",
+			size:   0,
+			err:    things.ErrMalformedEntity,
+		},
+		"list by cursor ordered by name": {
+			token:  token,
+			offset: 0,
+			limit:  n,
+			cursor: "MQ",
+			order:  things.OrderByName,
+			size:   0,
+			err:    things.ErrMalformedEntity,
+		},
 		"list with wrong credentials": {
 			token:  wrongValue,
 			offset: 0,
@@ -582,34 +1299,149 @@ func TestListChannels(t *testing.T) {
 	}
 
 	for desc, tc := range cases {
-		page, err := svc.ListChannels(context.Background(), tc.token, tc.offset, tc.limit, tc.name, tc.metadata)
+		page, err := svc.ListChannels(context.Background(), tc.token, things.PageQuery{
+			Offset:   tc.offset,
+			Limit:    tc.limit,
+			Cursor:   tc.cursor,
+			Name:     tc.name,
+			Order:    tc.order,
+			Dir:      tc.dir,
+			Metadata: tc.metadata,
+		})
 		size := uint64(len(page.Channels))
 		assert.Equal(t, tc.size, size, fmt.Sprintf("%s: expected %d got %d\n", desc, tc.size, size))
 		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
 	}
 }
 
-func TestListChannelsByThing(t *testing.T) {
+func TestListChannelsByCursor(t *testing.T) {
 	svc := newService(map[string]string{token: email})
 
-	sth, err := svc.AddThing(context.Background(), token, thing)
-	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 	n := uint64(10)
 	for i := uint64(0); i < n; i++ {
-		sch, err := svc.CreateChannel(context.Background(), token, channel)
-		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
-		svc.Connect(context.Background(), token, sch.ID, sth.ID)
+		svc.CreateChannel(context.Background(), token, channel)
 	}
 
-	// Wait for things and channels to connect.
-	time.Sleep(time.Second)
+	first, err := svc.ListChannels(context.Background(), token, things.PageQuery{Limit: n / 2})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.NotEmpty(t, first.NextCursor, "expected next cursor for full page")
 
-	cases := map[string]struct {
-		token  string
-		thing  string
-		offset uint64
-		limit  uint64
-		size   uint64
+	second, err := svc.ListChannels(context.Background(), token, things.PageQuery{Offset: n, Limit: n / 2, Cursor: first.NextCursor})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	ids := map[string]bool{}
+	for _, ch := range append(first.Channels, second.Channels...) {
+		ids[ch.ID] = true
+	}
+	assert.Equal(t, int(n), len(ids), fmt.Sprintf("listing by cursor: expected %d distinct channels got %d\n", n, len(ids)))
+
+	last, err := svc.ListChannels(context.Background(), token, things.PageQuery{Limit: n + 1})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Empty(t, last.NextCursor, "expected no next cursor for partial page")
+}
+
+func TestSearchChannels(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	n := uint64(10)
+	for i := uint64(0); i < n; i++ {
+		channel := things.Channel{
+			Name:     fmt.Sprintf("sensor-%d", i),
+			Metadata: map[string]interface{}{"building": "north"},
+		}
+		if i%2 == 0 {
+			channel.Metadata = map[string]interface{}{"building": "south"}
+		}
+		svc.CreateChannel(context.Background(), token, channel)
+	}
+
+	cases := map[string]struct {
+		token  string
+		query  string
+		offset uint64
+		limit  uint64
+		size   uint64
+		err    error
+	}{
+		"search channels by name": {
+			token:  token,
+			query:  "sensor-3",
+			offset: 0,
+			limit:  n,
+			size:   1,
+			err:    nil,
+		},
+		"search channels by metadata": {
+			token:  token,
+			query:  "south",
+			offset: 0,
+			limit:  n,
+			size:   n / 2,
+			err:    nil,
+		},
+		"search channels by name and metadata": {
+			token:  token,
+			query:  "sensor north",
+			offset: 0,
+			limit:  n,
+			size:   n / 2,
+			err:    nil,
+		},
+		"search channels with offset": {
+			token:  token,
+			query:  "sensor",
+			offset: n - 1,
+			limit:  n,
+			size:   1,
+			err:    nil,
+		},
+		"search channels without match": {
+			token:  token,
+			query:  "east",
+			offset: 0,
+			limit:  n,
+			size:   0,
+			err:    nil,
+		},
+		"search channels with wrong credentials": {
+			token:  wrongValue,
+			query:  "sensor",
+			offset: 0,
+			limit:  n,
+			size:   0,
+			err:    things.ErrUnauthorizedAccess,
+		},
+	}
+
+	for desc, tc := range cases {
+		page, err := svc.SearchChannels(context.Background(), tc.token, tc.query, tc.offset, tc.limit)
+		size := uint64(len(page.Channels))
+		assert.Equal(t, tc.size, size, fmt.Sprintf("%s: expected %d got %d\n", desc, tc.size, size))
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
+
+func TestListChannelsByThing(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	sth, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	n := uint64(10)
+	for i := uint64(0); i < n; i++ {
+		sch, err := svc.CreateChannel(context.Background(), token, channel)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		svc.Connect(context.Background(), token, sch.ID, sth.ID, nil)
+	}
+
+	// Wait for things and channels to connect.
+	time.Sleep(time.Second)
+
+	cases := map[string]struct {
+		token  string
+		thing  string
+		offset uint64
+		limit  uint64
+		size   uint64
 		err    error
 	}{
 		"list all channels by existing thing": {
@@ -720,6 +1552,106 @@ func TestRemoveChannel(t *testing.T) {
 	}
 }
 
+func TestRestoreChannel(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	saved, _ := svc.CreateChannel(context.Background(), token, channel)
+	active, _ := svc.CreateChannel(context.Background(), token, channel)
+	svc.RemoveChannel(context.Background(), token, saved.ID)
+
+	cases := []struct {
+		desc  string
+		id    string
+		token string
+		err   error
+	}{
+		{
+			desc:  "restore channel with wrong credentials",
+			id:    saved.ID,
+			token: wrongValue,
+			err:   things.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "restore removed channel",
+			id:    saved.ID,
+			token: token,
+			err:   nil,
+		},
+		{
+			desc:  "restore restored channel",
+			id:    saved.ID,
+			token: token,
+			err:   things.ErrNotFound,
+		},
+		{
+			desc:  "restore channel that is not removed",
+			id:    active.ID,
+			token: token,
+			err:   things.ErrNotFound,
+		},
+		{
+			desc:  "restore non-existing channel",
+			id:    wrongID,
+			token: token,
+			err:   things.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.RestoreChannel(context.Background(), tc.token, tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	_, err := svc.ViewChannel(context.Background(), token, saved.ID)
+	assert.Nil(t, err, fmt.Sprintf("view restored channel: expected no error got %s\n", err))
+}
+
+func TestPurgeChannel(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	saved, _ := svc.CreateChannel(context.Background(), token, channel)
+	removed, _ := svc.CreateChannel(context.Background(), token, channel)
+	svc.RemoveChannel(context.Background(), token, removed.ID)
+
+	cases := []struct {
+		desc  string
+		id    string
+		token string
+		err   error
+	}{
+		{
+			desc:  "purge channel with wrong credentials",
+			id:    saved.ID,
+			token: wrongValue,
+			err:   things.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "purge existing channel",
+			id:    saved.ID,
+			token: token,
+			err:   nil,
+		},
+		{
+			desc:  "purge removed channel",
+			id:    removed.ID,
+			token: token,
+			err:   nil,
+		},
+		{
+			desc:  "purge non-existing channel",
+			id:    wrongID,
+			token: token,
+			err:   nil,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.PurgeChannel(context.Background(), tc.token, tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	err := svc.RestoreChannel(context.Background(), token, removed.ID)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("restore purged channel: expected %s got %s\n", things.ErrNotFound, err))
+}
+
 func TestConnect(t *testing.T) {
 	svc := newService(map[string]string{token: email})
 
@@ -731,6 +1663,7 @@ func TestConnect(t *testing.T) {
 		token   string
 		chanID  string
 		thingID string
+		actions []string
 		err     error
 	}{
 		{
@@ -740,6 +1673,22 @@ func TestConnect(t *testing.T) {
 			thingID: sth.ID,
 			err:     nil,
 		},
+		{
+			desc:    "connect thing with allowed actions",
+			token:   token,
+			chanID:  sch.ID,
+			thingID: sth.ID,
+			actions: []string{things.Subscribe, things.ReadHistory},
+			err:     nil,
+		},
+		{
+			desc:    "connect thing with invalid action",
+			token:   token,
+			chanID:  sch.ID,
+			thingID: sth.ID,
+			actions: []string{things.Publish, wrongValue},
+			err:     things.ErrMalformedEntity,
+		},
 		{
 			desc:    "connect thing with wrong credentials",
 			token:   wrongValue,
@@ -764,7 +1713,7 @@ func TestConnect(t *testing.T) {
 	}
 
 	for _, tc := range cases {
-		err := svc.Connect(context.Background(), tc.token, tc.chanID, tc.thingID)
+		err := svc.Connect(context.Background(), tc.token, tc.chanID, tc.thingID, tc.actions)
 		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
 	}
 }
@@ -774,7 +1723,7 @@ func TestDisconnect(t *testing.T) {
 
 	sth, _ := svc.AddThing(context.Background(), token, thing)
 	sch, _ := svc.CreateChannel(context.Background(), token, channel)
-	svc.Connect(context.Background(), token, sch.ID, sth.ID)
+	svc.Connect(context.Background(), token, sch.ID, sth.ID, nil)
 
 	cases := []struct {
 		desc    string
@@ -832,32 +1781,57 @@ func TestCanAccess(t *testing.T) {
 
 	sth, _ := svc.AddThing(context.Background(), token, thing)
 	sch, _ := svc.CreateChannel(context.Background(), token, channel)
-	svc.Connect(context.Background(), token, sch.ID, sth.ID)
+	svc.Connect(context.Background(), token, sch.ID, sth.ID, nil)
+
+	wth, _ := svc.AddThing(context.Background(), token, thing)
+	svc.Connect(context.Background(), token, sch.ID, wth.ID, []string{things.Publish})
 
 	cases := map[string]struct {
 		token   string
 		channel string
+		action  string
 		err     error
 	}{
 		"allowed access": {
 			token:   sth.Key,
 			channel: sch.ID,
+			action:  things.Subscribe,
+			err:     nil,
+		},
+		"allowed publish-only access": {
+			token:   wth.Key,
+			channel: sch.ID,
+			action:  things.Publish,
 			err:     nil,
 		},
+		"denied action access": {
+			token:   wth.Key,
+			channel: sch.ID,
+			action:  things.Subscribe,
+			err:     things.ErrUnauthorizedAccess,
+		},
+		"access with invalid action": {
+			token:   sth.Key,
+			channel: sch.ID,
+			action:  wrongValue,
+			err:     things.ErrMalformedEntity,
+		},
 		"not-connected cannot access": {
 			token:   wrongValue,
 			channel: sch.ID,
+			action:  things.Publish,
 			err:     things.ErrUnauthorizedAccess,
 		},
 		"access to non-existing channel": {
 			token:   sth.Key,
 			channel: wrongID,
+			action:  things.Publish,
 			err:     things.ErrUnauthorizedAccess,
 		},
 	}
 
 	for desc, tc := range cases {
-		_, err := svc.CanAccess(context.Background(), tc.channel, tc.token)
+		_, err := svc.CanAccess(context.Background(), tc.channel, tc.token, tc.action)
 		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
 	}
 }
@@ -867,32 +1841,57 @@ func TestCanAccessByID(t *testing.T) {
 
 	sth, _ := svc.AddThing(context.Background(), token, thing)
 	sch, _ := svc.CreateChannel(context.Background(), token, channel)
-	svc.Connect(context.Background(), token, sch.ID, sth.ID)
+	svc.Connect(context.Background(), token, sch.ID, sth.ID, nil)
+
+	rth, _ := svc.AddThing(context.Background(), token, thing)
+	svc.Connect(context.Background(), token, sch.ID, rth.ID, []string{things.Subscribe, things.ReadHistory})
 
 	cases := map[string]struct {
 		thingID string
 		channel string
+		action  string
 		err     error
 	}{
 		"allowed access": {
 			thingID: sth.ID,
 			channel: sch.ID,
+			action:  things.Publish,
+			err:     nil,
+		},
+		"allowed read-only access": {
+			thingID: rth.ID,
+			channel: sch.ID,
+			action:  things.ReadHistory,
 			err:     nil,
 		},
+		"denied action access": {
+			thingID: rth.ID,
+			channel: sch.ID,
+			action:  things.Publish,
+			err:     things.ErrUnauthorizedAccess,
+		},
+		"access with invalid action": {
+			thingID: sth.ID,
+			channel: sch.ID,
+			action:  wrongValue,
+			err:     things.ErrMalformedEntity,
+		},
 		"not-connected cannot access": {
 			thingID: wrongValue,
 			channel: sch.ID,
+			action:  things.Publish,
 			err:     things.ErrUnauthorizedAccess,
 		},
 		"access to non-existing channel": {
 			thingID: sth.ID,
 			channel: wrongID,
+			action:  things.Publish,
 			err:     things.ErrUnauthorizedAccess,
 		},
 	}
 
 	for desc, tc := range cases {
-		err := svc.CanAccessByID(context.Background(), tc.channel, tc.thingID)
+		err := svc.CanAccessByID(context.Background(), tc.channel, tc.thingID, tc.action)
 		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
 	}
 }
@@ -925,3 +1924,1832 @@ func TestIdentify(t *testing.T) {
 		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
 	}
 }
+
+func TestOwner(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	sth, _ := svc.AddThing(context.Background(), token, thing)
+
+	cases := map[string]struct {
+		id    string
+		owner string
+		err   error
+	}{
+		"retrieve owner of existing thing": {
+			id:    sth.ID,
+			owner: email,
+			err:   nil,
+		},
+		"retrieve owner of non-existing thing": {
+			id:    wrongID,
+			owner: "",
+			err:   things.ErrNotFound,
+		},
+	}
+
+	for desc, tc := range cases {
+		owner, err := svc.Owner(context.Background(), tc.id)
+		assert.Equal(t, tc.owner, owner, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.owner, owner))
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
+
+func TestKeyDigest(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	sth, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	uth, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = svc.UpdateKey(context.Background(), token, uth.ID, "updated-key")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := map[string]struct {
+		id     string
+		digest string
+		err    error
+	}{
+		"retrieve key digest of existing thing": {
+			id:     sth.ID,
+			digest: digest(sth.Key),
+			err:    nil,
+		},
+		"retrieve key digest of thing with updated key": {
+			id:     uth.ID,
+			digest: digest("updated-key"),
+			err:    nil,
+		},
+		"retrieve key digest of non-existing thing": {
+			id:     wrongID,
+			digest: "",
+			err:    things.ErrNotFound,
+		},
+	}
+
+	for desc, tc := range cases {
+		d, err := svc.KeyDigest(context.Background(), tc.id)
+		assert.Equal(t, tc.digest, d, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.digest, d))
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
+
+func digest(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestRetention(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	retention := things.Retention{Period: time.Hour, Messages: 100}
+	ch := channel
+	ch.Retention = retention
+	sch, err := svc.CreateChannel(context.Background(), token, ch)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	invalid := channel
+	invalid.Retention = things.Retention{Period: -time.Hour}
+	_, err = svc.CreateChannel(context.Background(), token, invalid)
+	assert.Equal(t, things.ErrMalformedEntity, err, fmt.Sprintf("create channel with negative retention: expected %s got %s\n", things.ErrMalformedEntity, err))
+
+	cases := map[string]struct {
+		id        string
+		retention things.Retention
+		err       error
+	}{
+		"retrieve retention of existing channel": {
+			id:        sch.ID,
+			retention: retention,
+			err:       nil,
+		},
+		"retrieve retention of non-existing channel": {
+			id:        wrongID,
+			retention: things.Retention{},
+			err:       things.ErrNotFound,
+		},
+	}
+
+	for desc, tc := range cases {
+		r, err := svc.Retention(context.Background(), tc.id)
+		assert.Equal(t, tc.retention, r, fmt.Sprintf("%s: expected %v got %v\n", desc, tc.retention, r))
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
+
+func newChangesService(tokens map[string]string, owners []string, changes []things.Change) things.Service {
+	users := mocks.NewUsersService(tokens)
+	conns := make(chan mocks.Connection)
+	thingsRepo := mocks.NewThingRepository(conns)
+	channelsRepo := mocks.NewChannelRepository(thingsRepo, conns)
+	quotasRepo := mocks.NewQuotaRepository(thingsRepo, channelsRepo)
+	schemasRepo := mocks.NewSchemaRepository()
+	chanCache := mocks.NewChannelCache()
+	thingCache := mocks.NewThingCache()
+	idp := mocks.NewIdentityProvider()
+	changeLog := mocks.NewChangeLog(owners, changes)
+
+	return things.New(users, thingsRepo, channelsRepo, quotasRepo, schemasRepo, chanCache, thingCache, idp, nil, things.Quota{}, nil, keyGrace, 0, nil, changeLog, nil)
+}
+
+func TestListChanges(t *testing.T) {
+	otherEmail := "other@example.com"
+	changes := []things.Change{
+		{ID: "1-0", Operation: "thing.create", Fields: map[string]string{"id": "1"}},
+		{ID: "2-0", Operation: "thing.create", Fields: map[string]string{"id": "2"}},
+		{ID: "3-0", Operation: "thing.update", Fields: map[string]string{"id": "1"}},
+		{ID: "4-0", Operation: "thing.remove", Fields: map[string]string{"id": "2"}},
+	}
+	owners := []string{email, otherEmail, email, otherEmail}
+	svc := newChangesService(map[string]string{token: email}, owners, changes)
+
+	cases := []struct {
+		desc  string
+		token string
+		since string
+		limit uint64
+		page  things.ChangesPage
+		err   error
+	}{
+		{
+			desc:  "list all changes",
+			token: token,
+			since: "",
+			limit: 10,
+			page:  things.ChangesPage{Changes: []things.Change{changes[0], changes[2]}, Next: "4-0"},
+			err:   nil,
+		},
+		{
+			desc:  "list changes since position",
+			token: token,
+			since: "1-0",
+			limit: 10,
+			page:  things.ChangesPage{Changes: []things.Change{changes[2]}, Next: "4-0"},
+			err:   nil,
+		},
+		{
+			desc:  "list limited changes",
+			token: token,
+			since: "",
+			limit: 1,
+			page:  things.ChangesPage{Changes: []things.Change{changes[0]}, Next: "1-0"},
+			err:   nil,
+		},
+		{
+			desc:  "list changes since last position",
+			token: token,
+			since: "4-0",
+			limit: 10,
+			page:  things.ChangesPage{Changes: []things.Change{}, Next: "4-0"},
+			err:   nil,
+		},
+		{
+			desc:  "list changes since expired position",
+			token: token,
+			since: "0-1",
+			limit: 10,
+			page:  things.ChangesPage{},
+			err:   things.ErrChangesExpired,
+		},
+		{
+			desc:  "list changes with wrong credentials",
+			token: wrongValue,
+			since: "",
+			limit: 10,
+			page:  things.ChangesPage{},
+			err:   things.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		page, err := svc.ListChanges(context.Background(), tc.token, tc.since, tc.limit)
+		assert.Equal(t, tc.page, page, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.page, page))
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func newAPIUsageService(tokens map[string]string, usageRepo things.APIUsageRepository) things.Service {
+	users := mocks.NewUsersService(tokens)
+	conns := make(chan mocks.Connection)
+	thingsRepo := mocks.NewThingRepository(conns)
+	channelsRepo := mocks.NewChannelRepository(thingsRepo, conns)
+	quotasRepo := mocks.NewQuotaRepository(thingsRepo, channelsRepo)
+	schemasRepo := mocks.NewSchemaRepository()
+	chanCache := mocks.NewChannelCache()
+	thingCache := mocks.NewThingCache()
+	idp := mocks.NewIdentityProvider()
+
+	return things.New(users, thingsRepo, channelsRepo, quotasRepo, schemasRepo, chanCache, thingCache, idp, nil, things.Quota{}, nil, keyGrace, 0, nil, nil, usageRepo)
+}
+
+func TestViewAPIUsage(t *testing.T) {
+	otherEmail := "other@example.com"
+	hour := time.Date(2020, time.January, 1, 10, 0, 0, 0, time.UTC)
+	usage := []things.APIUsage{
+		{Owner: email, Method: "add_thing", Hour: hour, Calls: 5, Errors: 1},
+		{Owner: email, Method: "view_thing", Hour: hour, Calls: 3, Errors: 0},
+		{Owner: email, Method: "add_thing", Hour: hour.Add(time.Hour), Calls: 2, Errors: 2},
+		{Owner: otherEmail, Method: "add_thing", Hour: hour, Calls: 7, Errors: 0},
+	}
+	usageRepo := mocks.NewAPIUsageRepository()
+	err := usageRepo.Save(context.Background(), usage...)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	svc := newAPIUsageService(map[string]string{token: email}, usageRepo)
+
+	cases := []struct {
+		desc  string
+		token string
+		from  time.Time
+		to    time.Time
+		usage []things.APIUsage
+		err   error
+	}{
+		{
+			desc:  "view API usage of the day",
+			token: token,
+			from:  hour.Add(-10 * time.Hour),
+			to:    hour.Add(14 * time.Hour),
+			usage: usage[:3],
+			err:   nil,
+		},
+		{
+			desc:  "view API usage of single hour",
+			token: token,
+			from:  hour,
+			to:    hour.Add(time.Hour),
+			usage: usage[:2],
+			err:   nil,
+		},
+		{
+			desc:  "view API usage of the hours without calls",
+			token: token,
+			from:  hour.Add(-2 * time.Hour),
+			to:    hour,
+			usage: []things.APIUsage{},
+			err:   nil,
+		},
+		{
+			desc:  "view API usage with end before start",
+			token: token,
+			from:  hour,
+			to:    hour.Add(-time.Hour),
+			usage: nil,
+			err:   things.ErrMalformedEntity,
+		},
+		{
+			desc:  "view API usage of too long range",
+			token: token,
+			from:  hour.Add(-32 * 24 * time.Hour),
+			to:    hour,
+			usage: nil,
+			err:   things.ErrMalformedEntity,
+		},
+		{
+			desc:  "view API usage with wrong credentials",
+			token: wrongValue,
+			from:  hour,
+			to:    hour.Add(time.Hour),
+			usage: nil,
+			err:   things.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		usage, err := svc.ViewAPIUsage(context.Background(), tc.token, tc.from, tc.to)
+		assert.Equal(t, tc.usage, usage, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.usage, usage))
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestViewAPIUsageWithoutRepository(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	now := time.Now()
+	usage, err := svc.ViewAPIUsage(context.Background(), token, now.Add(-time.Hour), now)
+	assert.Nil(t, err, fmt.Sprintf("view API usage without repository: unexpected error %s", err))
+	assert.Equal(t, []things.APIUsage{}, usage, fmt.Sprintf("view API usage without repository: expected no usage got %v", usage))
+}
+
+func TestShareThing(t *testing.T) {
+	svc := newSharingService()
+	saved, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc       string
+		id         string
+		group      string
+		permission string
+		token      string
+		err        error
+	}{
+		{
+			desc:       "share thing with group",
+			id:         saved.ID,
+			group:      group,
+			permission: things.ViewPermission,
+			token:      token,
+			err:        nil,
+		},
+		{
+			desc:       "share shared thing with another permission",
+			id:         saved.ID,
+			group:      group,
+			permission: things.EditPermission,
+			token:      token,
+			err:        nil,
+		},
+		{
+			desc:       "share thing with invalid permission",
+			id:         saved.ID,
+			group:      group,
+			permission: wrongValue,
+			token:      token,
+			err:        things.ErrMalformedEntity,
+		},
+		{
+			desc:       "share thing with group the user is not member of",
+			id:         saved.ID,
+			group:      wrongValue,
+			permission: things.ViewPermission,
+			token:      token,
+			err:        things.ErrNotFound,
+		},
+		{
+			desc:       "share thing not owned by the user",
+			id:         saved.ID,
+			group:      group,
+			permission: things.ViewPermission,
+			token:      memberToken,
+			err:        things.ErrNotFound,
+		},
+		{
+			desc:       "share non-existing thing",
+			id:         wrongID,
+			group:      group,
+			permission: things.ViewPermission,
+			token:      token,
+			err:        things.ErrNotFound,
+		},
+		{
+			desc:       "share thing with wrong credentials",
+			id:         saved.ID,
+			group:      group,
+			permission: things.ViewPermission,
+			token:      wrongValue,
+			err:        things.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.ShareThing(context.Background(), tc.token, tc.id, tc.group, tc.permission)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestSharedThingAccess(t *testing.T) {
+	svc := newSharingService()
+	viewed, err := svc.AddThing(context.Background(), token, things.Thing{Name: "viewed"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	edited, err := svc.AddThing(context.Background(), token, things.Thing{Name: "edited"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	_, err = svc.AddThing(context.Background(), memberToken, things.Thing{Name: "owned"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	err = svc.ShareThing(context.Background(), token, viewed.ID, group, things.ViewPermission)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	err = svc.ShareThing(context.Background(), token, edited.ID, group, things.EditPermission)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	th, err := svc.ViewThing(context.Background(), memberToken, viewed.ID)
+	assert.Nil(t, err, fmt.Sprintf("view shared thing: unexpected error: %s\n", err))
+	assert.Equal(t, viewed.Name, th.Name, fmt.Sprintf("view shared thing: expected name %s got %s\n", viewed.Name, th.Name))
+	assert.Empty(t, th.Key, "view shared thing: expected key to be hidden\n")
+
+	_, err = svc.ViewThing(context.Background(), otherToken, viewed.ID)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("view thing shared with another group: expected %s got %s\n", things.ErrNotFound, err))
+
+	err = svc.UpdateThing(context.Background(), memberToken, things.Thing{ID: viewed.ID, Name: "updated"})
+	assert.Equal(t, things.ErrUnauthorizedAccess, err, fmt.Sprintf("update thing shared with view permission: expected %s got %s\n", things.ErrUnauthorizedAccess, err))
+
+	err = svc.UpdateThing(context.Background(), memberToken, things.Thing{ID: edited.ID, Name: "updated"})
+	assert.Nil(t, err, fmt.Sprintf("update thing shared with edit permission: unexpected error: %s\n", err))
+	th, err = svc.ViewThing(context.Background(), token, edited.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.Equal(t, "updated", th.Name, fmt.Sprintf("update thing shared with edit permission: expected name %s got %s\n", "updated", th.Name))
+	assert.Equal(t, email, th.Owner, fmt.Sprintf("update thing shared with edit permission: expected owner %s got %s\n", email, th.Owner))
+
+	page, err := svc.ListThings(context.Background(), memberToken, things.PageQuery{Limit: 10})
+	assert.Nil(t, err, fmt.Sprintf("list owned things: unexpected error: %s\n", err))
+	assert.Equal(t, 1, len(page.Things), fmt.Sprintf("list owned things: expected %d got %d\n", 1, len(page.Things)))
+
+	page, err = svc.ListThings(context.Background(), memberToken, things.PageQuery{Limit: 10, Shared: true})
+	assert.Nil(t, err, fmt.Sprintf("list shared things: unexpected error: %s\n", err))
+	assert.Equal(t, 3, len(page.Things), fmt.Sprintf("list shared things: expected %d got %d\n", 3, len(page.Things)))
+	for _, th := range page.Things {
+		if th.Owner != memberEmail {
+			assert.Empty(t, th.Key, "list shared things: expected key of shared thing to be hidden\n")
+		}
+	}
+
+	err = svc.UnshareThing(context.Background(), token, viewed.ID, group)
+	assert.Nil(t, err, fmt.Sprintf("unshare thing: unexpected error: %s\n", err))
+	_, err = svc.ViewThing(context.Background(), memberToken, viewed.ID)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("view unshared thing: expected %s got %s\n", things.ErrNotFound, err))
+}
+
+func TestShareChannel(t *testing.T) {
+	svc := newSharingService()
+	saved, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc       string
+		id         string
+		group      string
+		permission string
+		token      string
+		err        error
+	}{
+		{
+			desc:       "share channel with group",
+			id:         saved.ID,
+			group:      group,
+			permission: things.ViewPermission,
+			token:      token,
+			err:        nil,
+		},
+		{
+			desc:       "share channel with invalid permission",
+			id:         saved.ID,
+			group:      group,
+			permission: wrongValue,
+			token:      token,
+			err:        things.ErrMalformedEntity,
+		},
+		{
+			desc:       "share channel with group the user is not member of",
+			id:         saved.ID,
+			group:      wrongValue,
+			permission: things.ViewPermission,
+			token:      token,
+			err:        things.ErrNotFound,
+		},
+		{
+			desc:       "share channel not owned by the user",
+			id:         saved.ID,
+			group:      group,
+			permission: things.ViewPermission,
+			token:      memberToken,
+			err:        things.ErrNotFound,
+		},
+		{
+			desc:       "share channel with wrong credentials",
+			id:         saved.ID,
+			group:      group,
+			permission: things.ViewPermission,
+			token:      wrongValue,
+			err:        things.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.ShareChannel(context.Background(), tc.token, tc.id, tc.group, tc.permission)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestSharedChannelAccess(t *testing.T) {
+	svc := newSharingService()
+	viewed, err := svc.CreateChannel(context.Background(), token, things.Channel{Name: "viewed"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	edited, err := svc.CreateChannel(context.Background(), token, things.Channel{Name: "edited"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	err = svc.ShareChannel(context.Background(), token, viewed.ID, group, things.ViewPermission)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	err = svc.ShareChannel(context.Background(), token, edited.ID, group, things.EditPermission)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	_, err = svc.ViewChannel(context.Background(), memberToken, viewed.ID)
+	assert.Nil(t, err, fmt.Sprintf("view shared channel: unexpected error: %s\n", err))
+
+	_, err = svc.ViewChannel(context.Background(), otherToken, viewed.ID)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("view channel shared with another group: expected %s got %s\n", things.ErrNotFound, err))
+
+	err = svc.UpdateChannel(context.Background(), memberToken, things.Channel{ID: viewed.ID, Name: "updated"})
+	assert.Equal(t, things.ErrUnauthorizedAccess, err, fmt.Sprintf("update channel shared with view permission: expected %s got %s\n", things.ErrUnauthorizedAccess, err))
+
+	err = svc.UpdateChannel(context.Background(), memberToken, things.Channel{ID: edited.ID, Name: "updated"})
+	assert.Nil(t, err, fmt.Sprintf("update channel shared with edit permission: unexpected error: %s\n", err))
+
+	page, err := svc.ListChannels(context.Background(), memberToken, things.PageQuery{Limit: 10, Shared: true})
+	assert.Nil(t, err, fmt.Sprintf("list shared channels: unexpected error: %s\n", err))
+	assert.Equal(t, 2, len(page.Channels), fmt.Sprintf("list shared channels: expected %d got %d\n", 2, len(page.Channels)))
+
+	err = svc.UnshareChannel(context.Background(), token, viewed.ID, group)
+	assert.Nil(t, err, fmt.Sprintf("unshare channel: unexpected error: %s\n", err))
+	_, err = svc.ViewChannel(context.Background(), memberToken, viewed.ID)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("view unshared channel: expected %s got %s\n", things.ErrNotFound, err))
+}
+
+func TestShareThingWithUser(t *testing.T) {
+	svc := newSharingService()
+	saved, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc       string
+		id         string
+		email      string
+		permission string
+		token      string
+		err        error
+	}{
+		{
+			desc:       "share thing with user",
+			id:         saved.ID,
+			email:      otherEmail,
+			permission: things.ViewPermission,
+			token:      token,
+			err:        nil,
+		},
+		{
+			desc:       "share shared thing with another permission",
+			id:         saved.ID,
+			email:      otherEmail,
+			permission: things.EditPermission,
+			token:      token,
+			err:        nil,
+		},
+		{
+			desc:       "share thing with invalid permission",
+			id:         saved.ID,
+			email:      otherEmail,
+			permission: wrongValue,
+			token:      token,
+			err:        things.ErrMalformedEntity,
+		},
+		{
+			desc:       "share thing with its owner",
+			id:         saved.ID,
+			email:      email,
+			permission: things.ViewPermission,
+			token:      token,
+			err:        things.ErrMalformedEntity,
+		},
+		{
+			desc:       "share thing not owned by the user",
+			id:         saved.ID,
+			email:      otherEmail,
+			permission: things.ViewPermission,
+			token:      memberToken,
+			err:        things.ErrNotFound,
+		},
+		{
+			desc:       "share non-existing thing",
+			id:         wrongID,
+			email:      otherEmail,
+			permission: things.ViewPermission,
+			token:      token,
+			err:        things.ErrNotFound,
+		},
+		{
+			desc:       "share thing with wrong credentials",
+			id:         saved.ID,
+			email:      otherEmail,
+			permission: things.ViewPermission,
+			token:      wrongValue,
+			err:        things.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.ShareThingWithUser(context.Background(), tc.token, tc.id, tc.email, tc.permission)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestUserSharedThingAccess(t *testing.T) {
+	svc := newSharingService()
+	viewed, err := svc.AddThing(context.Background(), token, things.Thing{Name: "viewed"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	edited, err := svc.AddThing(context.Background(), token, things.Thing{Name: "edited"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	err = svc.ShareThingWithUser(context.Background(), token, viewed.ID, otherEmail, things.ViewPermission)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	err = svc.ShareThingWithUser(context.Background(), token, edited.ID, otherEmail, things.EditPermission)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	th, err := svc.ViewThing(context.Background(), otherToken, viewed.ID)
+	assert.Nil(t, err, fmt.Sprintf("view thing shared with user: unexpected error: %s\n", err))
+	assert.Empty(t, th.Key, "view thing shared with user: expected key to be hidden\n")
+
+	_, err = svc.ViewThing(context.Background(), memberToken, viewed.ID)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("view thing shared with another user: expected %s got %s\n", things.ErrNotFound, err))
+
+	err = svc.UpdateThing(context.Background(), otherToken, things.Thing{ID: viewed.ID, Name: "updated"})
+	assert.Equal(t, things.ErrUnauthorizedAccess, err, fmt.Sprintf("update thing shared with view permission: expected %s got %s\n", things.ErrUnauthorizedAccess, err))
+
+	err = svc.UpdateThing(context.Background(), otherToken, things.Thing{ID: edited.ID, Name: "updated"})
+	assert.Nil(t, err, fmt.Sprintf("update thing shared with edit permission: unexpected error: %s\n", err))
+
+	page, err := svc.ListThings(context.Background(), otherToken, things.PageQuery{Limit: 10, Shared: true})
+	assert.Nil(t, err, fmt.Sprintf("list shared things: unexpected error: %s\n", err))
+	assert.Equal(t, 2, len(page.Things), fmt.Sprintf("list shared things: expected %d got %d\n", 2, len(page.Things)))
+
+	err = svc.UnshareThingWithUser(context.Background(), token, viewed.ID, otherEmail)
+	assert.Nil(t, err, fmt.Sprintf("unshare thing with user: unexpected error: %s\n", err))
+	_, err = svc.ViewThing(context.Background(), otherToken, viewed.ID)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("view thing unshared with user: expected %s got %s\n", things.ErrNotFound, err))
+}
+
+func TestUserSharedChannelAccess(t *testing.T) {
+	svc := newSharingService()
+	viewed, err := svc.CreateChannel(context.Background(), token, things.Channel{Name: "viewed"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	edited, err := svc.CreateChannel(context.Background(), token, things.Channel{Name: "edited"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	err = svc.ShareChannelWithUser(context.Background(), token, viewed.ID, otherEmail, wrongValue)
+	assert.Equal(t, things.ErrMalformedEntity, err, fmt.Sprintf("share channel with invalid permission: expected %s got %s\n", things.ErrMalformedEntity, err))
+	err = svc.ShareChannelWithUser(context.Background(), memberToken, viewed.ID, otherEmail, things.ViewPermission)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("share channel not owned by the user: expected %s got %s\n", things.ErrNotFound, err))
+
+	err = svc.ShareChannelWithUser(context.Background(), token, viewed.ID, otherEmail, things.ViewPermission)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	err = svc.ShareChannelWithUser(context.Background(), token, edited.ID, otherEmail, things.EditPermission)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	_, err = svc.ViewChannel(context.Background(), otherToken, viewed.ID)
+	assert.Nil(t, err, fmt.Sprintf("view channel shared with user: unexpected error: %s\n", err))
+
+	err = svc.UpdateChannel(context.Background(), otherToken, things.Channel{ID: viewed.ID, Name: "updated"})
+	assert.Equal(t, things.ErrUnauthorizedAccess, err, fmt.Sprintf("update channel shared with view permission: expected %s got %s\n", things.ErrUnauthorizedAccess, err))
+
+	err = svc.UpdateChannel(context.Background(), otherToken, things.Channel{ID: edited.ID, Name: "updated"})
+	assert.Nil(t, err, fmt.Sprintf("update channel shared with edit permission: unexpected error: %s\n", err))
+
+	page, err := svc.ListChannels(context.Background(), otherToken, things.PageQuery{Limit: 10, Shared: true})
+	assert.Nil(t, err, fmt.Sprintf("list shared channels: unexpected error: %s\n", err))
+	assert.Equal(t, 2, len(page.Channels), fmt.Sprintf("list shared channels: expected %d got %d\n", 2, len(page.Channels)))
+
+	err = svc.UnshareChannelWithUser(context.Background(), token, viewed.ID, otherEmail)
+	assert.Nil(t, err, fmt.Sprintf("unshare channel with user: unexpected error: %s\n", err))
+	_, err = svc.ViewChannel(context.Background(), otherToken, viewed.ID)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("view channel unshared with user: expected %s got %s\n", things.ErrNotFound, err))
+}
+
+func TestExportThings(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	n := 5
+	for i := 0; i < n; i++ {
+		_, err := svc.AddThing(context.Background(), token, thing)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	}
+
+	removed, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	err = svc.RemoveThing(context.Background(), token, removed.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := map[string]struct {
+		token string
+		size  int
+		err   error
+	}{
+		"export things": {
+			token: token,
+			size:  n,
+			err:   nil,
+		},
+		"export things with wrong credentials": {
+			token: wrongValue,
+			size:  0,
+			err:   things.ErrUnauthorizedAccess,
+		},
+	}
+
+	for desc, tc := range cases {
+		exported := []things.Thing{}
+		err := svc.ExportThings(context.Background(), tc.token, func(th things.Thing) error {
+			exported = append(exported, th)
+			return nil
+		})
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+		assert.Equal(t, tc.size, len(exported), fmt.Sprintf("%s: expected %d got %d\n", desc, tc.size, len(exported)))
+		for _, th := range exported {
+			assert.NotEmpty(t, th.Key, fmt.Sprintf("%s: expected thing key to be exported\n", desc))
+		}
+	}
+
+	err = svc.ExportThings(context.Background(), token, func(th things.Thing) error {
+		return things.ErrMalformedEntity
+	})
+	assert.Equal(t, things.ErrMalformedEntity, err, fmt.Sprintf("export things with failing callback: expected %s got %s\n", things.ErrMalformedEntity, err))
+}
+
+func TestImportThings(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	cases := []struct {
+		desc     string
+		things   []things.Thing
+		token    string
+		imported int
+		err      error
+	}{
+		{
+			desc:     "import things",
+			things:   []things.Thing{{ID: "a", Key: "key-a", Name: "a"}, {Name: "b"}},
+			token:    token,
+			imported: 2,
+			err:      nil,
+		},
+		{
+			desc:     "import things with existing key",
+			things:   []things.Thing{{Key: "key-c"}, {Key: "key-a"}},
+			token:    token,
+			imported: 1,
+			err:      things.ErrConflict,
+		},
+		{
+			desc:     "import things with wrong credentials",
+			things:   []things.Thing{{Name: "d"}},
+			token:    wrongValue,
+			imported: 0,
+			err:      things.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		imported, err := svc.ImportThings(context.Background(), tc.token, tc.things)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.imported, len(imported), fmt.Sprintf("%s: expected %d got %d\n", tc.desc, tc.imported, len(imported)))
+		for _, th := range imported {
+			assert.Equal(t, email, th.Owner, fmt.Sprintf("%s: expected owner %s got %s\n", tc.desc, email, th.Owner))
+			assert.NotEmpty(t, th.Key, fmt.Sprintf("%s: expected thing key to be set\n", tc.desc))
+		}
+	}
+
+	page, err := svc.ListThings(context.Background(), token, things.PageQuery{Limit: 10})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.Equal(t, uint64(3), page.Total, fmt.Sprintf("expected %d imported things got %d\n", 3, page.Total))
+}
+
+func TestExportChannels(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	n := 5
+	for i := 0; i < n; i++ {
+		_, err := svc.CreateChannel(context.Background(), token, channel)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	}
+
+	cases := map[string]struct {
+		token string
+		size  int
+		err   error
+	}{
+		"export channels": {
+			token: token,
+			size:  n,
+			err:   nil,
+		},
+		"export channels with wrong credentials": {
+			token: wrongValue,
+			size:  0,
+			err:   things.ErrUnauthorizedAccess,
+		},
+	}
+
+	for desc, tc := range cases {
+		exported := []things.Channel{}
+		err := svc.ExportChannels(context.Background(), tc.token, func(ch things.Channel) error {
+			exported = append(exported, ch)
+			return nil
+		})
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+		assert.Equal(t, tc.size, len(exported), fmt.Sprintf("%s: expected %d got %d\n", desc, tc.size, len(exported)))
+	}
+}
+
+func TestImportChannels(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	cases := []struct {
+		desc     string
+		channels []things.Channel
+		token    string
+		imported int
+		err      error
+	}{
+		{
+			desc:     "import channels",
+			channels: []things.Channel{{ID: "a", Name: "a"}, {Name: "b"}},
+			token:    token,
+			imported: 2,
+			err:      nil,
+		},
+		{
+			desc:     "import channels with wrong credentials",
+			channels: []things.Channel{{Name: "c"}},
+			token:    wrongValue,
+			imported: 0,
+			err:      things.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		imported, err := svc.ImportChannels(context.Background(), tc.token, tc.channels)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.imported, len(imported), fmt.Sprintf("%s: expected %d got %d\n", tc.desc, tc.imported, len(imported)))
+	}
+}
+
+func TestExportImportConnections(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	th, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	ch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc     string
+		conns    []things.Connection
+		token    string
+		imported int
+		err      error
+	}{
+		{
+			desc:     "import connection with default actions",
+			conns:    []things.Connection{{ChannelID: ch.ID, ThingID: th.ID}},
+			token:    token,
+			imported: 1,
+			err:      nil,
+		},
+		{
+			desc:     "import connection with invalid action",
+			conns:    []things.Connection{{ChannelID: ch.ID, ThingID: th.ID, Actions: []string{wrongValue}}},
+			token:    token,
+			imported: 0,
+			err:      things.ErrMalformedEntity,
+		},
+		{
+			desc:     "import connection of non-existing thing",
+			conns:    []things.Connection{{ChannelID: ch.ID, ThingID: wrongValue}},
+			token:    token,
+			imported: 0,
+			err:      things.ErrNotFound,
+		},
+		{
+			desc:     "import connection with wrong credentials",
+			conns:    []things.Connection{{ChannelID: ch.ID, ThingID: th.ID}},
+			token:    wrongValue,
+			imported: 0,
+			err:      things.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		imported, err := svc.ImportConnections(context.Background(), tc.token, tc.conns)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.imported, len(imported), fmt.Sprintf("%s: expected %d got %d\n", tc.desc, tc.imported, len(imported)))
+	}
+
+	exported := []things.Connection{}
+	err = svc.ExportConnections(context.Background(), token, func(conn things.Connection) error {
+		exported = append(exported, conn)
+		return nil
+	})
+	assert.Nil(t, err, fmt.Sprintf("export connections: unexpected error: %s\n", err))
+	require.Equal(t, 1, len(exported), fmt.Sprintf("export connections: expected %d got %d\n", 1, len(exported)))
+	assert.Equal(t, things.Connection{ChannelID: ch.ID, ThingID: th.ID, Actions: things.Actions}, exported[0], fmt.Sprintf("export connections: expected %v got %v\n", things.Connection{ChannelID: ch.ID, ThingID: th.ID, Actions: things.Actions}, exported[0]))
+
+	err = svc.ExportConnections(context.Background(), wrongValue, func(conn things.Connection) error {
+		return nil
+	})
+	assert.Equal(t, things.ErrUnauthorizedAccess, err, fmt.Sprintf("export connections with wrong credentials: expected %s got %s\n", things.ErrUnauthorizedAccess, err))
+}
+
+const (
+	adminEmail = "admin@example.com"
+	adminToken = "admin-token"
+)
+
+const (
+	disabledEmail = "disabled@example.com"
+	disabledToken = "disabled-token"
+)
+
+func newQuotaService(quota things.Quota) things.Service {
+	tokens := map[string]string{token: email, adminToken: adminEmail, otherToken: otherEmail, disabledToken: disabledEmail}
+	users := mocks.NewUsersServiceWithDisabled(tokens, []string{disabledEmail})
+	conns := make(chan mocks.Connection)
+	thingsRepo := mocks.NewThingRepository(conns)
+	channelsRepo := mocks.NewChannelRepository(thingsRepo, conns)
+	quotasRepo := mocks.NewQuotaRepository(thingsRepo, channelsRepo)
+	schemasRepo := mocks.NewSchemaRepository()
+	chanCache := mocks.NewChannelCache()
+	thingCache := mocks.NewThingCache()
+	idp := mocks.NewIdentityProvider()
+
+	return things.New(users, thingsRepo, channelsRepo, quotasRepo, schemasRepo, chanCache, thingCache, idp, nil, quota, []string{adminEmail}, keyGrace, 0, nil, nil, nil)
+}
+
+func TestThingsQuota(t *testing.T) {
+	svc := newQuotaService(things.Quota{Things: 2})
+
+	th, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	_, err = svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	_, err = svc.AddThing(context.Background(), token, thing)
+	assert.Equal(t, things.ErrQuotaExceeded, err, fmt.Sprintf("add thing above quota: expected %s got %s\n", things.ErrQuotaExceeded, err))
+
+	_, err = svc.CreateChannel(context.Background(), token, channel)
+	assert.Nil(t, err, fmt.Sprintf("create channel with things quota reached: unexpected error: %s\n", err))
+
+	_, err = svc.AddThing(context.Background(), otherToken, thing)
+	assert.Nil(t, err, fmt.Sprintf("add thing of other owner: unexpected error: %s\n", err))
+
+	err = svc.RemoveThing(context.Background(), token, th.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	imported, err := svc.ImportThings(context.Background(), token, []things.Thing{{Name: "a"}, {Name: "b"}})
+	assert.Equal(t, things.ErrQuotaExceeded, err, fmt.Sprintf("import things above quota: expected %s got %s\n", things.ErrQuotaExceeded, err))
+	assert.Equal(t, 0, len(imported), fmt.Sprintf("import things above quota: expected %d got %d\n", 0, len(imported)))
+
+	_, err = svc.AddThing(context.Background(), token, thing)
+	assert.Nil(t, err, fmt.Sprintf("add thing after removal: unexpected error: %s\n", err))
+
+	err = svc.RestoreThing(context.Background(), token, th.ID)
+	assert.Equal(t, things.ErrQuotaExceeded, err, fmt.Sprintf("restore thing above quota: expected %s got %s\n", things.ErrQuotaExceeded, err))
+}
+
+func TestChannelsQuota(t *testing.T) {
+	svc := newQuotaService(things.Quota{Channels: 1})
+
+	ch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	_, err = svc.CreateChannel(context.Background(), token, channel)
+	assert.Equal(t, things.ErrQuotaExceeded, err, fmt.Sprintf("create channel above quota: expected %s got %s\n", things.ErrQuotaExceeded, err))
+
+	imported, err := svc.ImportChannels(context.Background(), token, []things.Channel{{Name: "a"}})
+	assert.Equal(t, things.ErrQuotaExceeded, err, fmt.Sprintf("import channels above quota: expected %s got %s\n", things.ErrQuotaExceeded, err))
+	assert.Equal(t, 0, len(imported), fmt.Sprintf("import channels above quota: expected %d got %d\n", 0, len(imported)))
+
+	err = svc.RemoveChannel(context.Background(), token, ch.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	_, err = svc.CreateChannel(context.Background(), token, channel)
+	assert.Nil(t, err, fmt.Sprintf("create channel after removal: unexpected error: %s\n", err))
+
+	err = svc.RestoreChannel(context.Background(), token, ch.ID)
+	assert.Equal(t, things.ErrQuotaExceeded, err, fmt.Sprintf("restore channel above quota: expected %s got %s\n", things.ErrQuotaExceeded, err))
+}
+
+func TestConnectionsQuota(t *testing.T) {
+	svc := newQuotaService(things.Quota{Connections: 1})
+
+	th1, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	th2, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	ch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	err = svc.Connect(context.Background(), token, ch.ID, th1.ID, nil)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc    string
+		thingID string
+		actions []string
+		err     error
+	}{
+		{
+			desc:    "connect already connected thing with quota reached",
+			thingID: th1.ID,
+			actions: []string{things.Subscribe},
+			err:     nil,
+		},
+		{
+			desc:    "connect thing above quota",
+			thingID: th2.ID,
+			err:     things.ErrQuotaExceeded,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.Connect(context.Background(), token, ch.ID, tc.thingID, tc.actions)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+
+		conns := []things.Connection{{ChannelID: ch.ID, ThingID: tc.thingID}}
+		_, err = svc.ImportConnections(context.Background(), token, conns)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("import %s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestUpdateQuota(t *testing.T) {
+	svc := newQuotaService(things.Quota{})
+
+	cases := []struct {
+		desc  string
+		token string
+		quota things.Quota
+		err   error
+	}{
+		{
+			desc:  "update quota",
+			token: adminToken,
+			quota: things.Quota{Owner: email, Things: 1},
+			err:   nil,
+		},
+		{
+			desc:  "update quota with empty owner",
+			token: adminToken,
+			quota: things.Quota{Things: 1},
+			err:   things.ErrMalformedEntity,
+		},
+		{
+			desc:  "update quota with too large limit",
+			token: adminToken,
+			quota: things.Quota{Owner: email, Things: math.MaxUint64},
+			err:   things.ErrMalformedEntity,
+		},
+		{
+			desc:  "update quota as non-admin",
+			token: token,
+			quota: things.Quota{Owner: email},
+			err:   things.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "update quota with wrong credentials",
+			token: wrongValue,
+			quota: things.Quota{Owner: email},
+			err:   things.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.UpdateQuota(context.Background(), tc.token, tc.quota)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	_, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	_, err = svc.AddThing(context.Background(), token, thing)
+	assert.Equal(t, things.ErrQuotaExceeded, err, fmt.Sprintf("add thing above updated quota: expected %s got %s\n", things.ErrQuotaExceeded, err))
+}
+
+func TestViewQuota(t *testing.T) {
+	defQuota := things.Quota{Things: 10, Channels: 5}
+	svc := newQuotaService(defQuota)
+
+	_, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	override := things.Quota{Owner: adminEmail, Connections: 1}
+	err = svc.UpdateQuota(context.Background(), adminToken, override)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc  string
+		token string
+		owner string
+		quota things.Quota
+		usage things.Usage
+		err   error
+	}{
+		{
+			desc:  "view own default quota",
+			token: token,
+			owner: email,
+			quota: things.Quota{Owner: email, Things: 10, Channels: 5},
+			usage: things.Usage{Things: 1},
+			err:   nil,
+		},
+		{
+			desc:  "view quota of other owner as admin",
+			token: adminToken,
+			owner: email,
+			quota: things.Quota{Owner: email, Things: 10, Channels: 5},
+			usage: things.Usage{Things: 1},
+			err:   nil,
+		},
+		{
+			desc:  "view own overridden quota",
+			token: adminToken,
+			owner: adminEmail,
+			quota: override,
+			err:   nil,
+		},
+		{
+			desc:  "view quota of other owner as non-admin",
+			token: otherToken,
+			owner: email,
+			err:   things.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "view quota with wrong credentials",
+			token: wrongValue,
+			owner: email,
+			err:   things.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		quota, usage, err := svc.ViewQuota(context.Background(), tc.token, tc.owner)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.quota, quota, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.quota, quota))
+		assert.Equal(t, tc.usage, usage, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.usage, usage))
+	}
+}
+
+func TestRemoveQuota(t *testing.T) {
+	svc := newQuotaService(things.Quota{Things: 10})
+
+	err := svc.UpdateQuota(context.Background(), adminToken, things.Quota{Owner: email, Things: 1})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc  string
+		token string
+		owner string
+		err   error
+	}{
+		{
+			desc:  "remove quota as non-admin",
+			token: token,
+			owner: email,
+			err:   things.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "remove quota with wrong credentials",
+			token: wrongValue,
+			owner: email,
+			err:   things.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "remove quota",
+			token: adminToken,
+			owner: email,
+			err:   nil,
+		},
+		{
+			desc:  "remove non-existing quota",
+			token: adminToken,
+			owner: email,
+			err:   nil,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.RemoveQuota(context.Background(), tc.token, tc.owner)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	quota, _, err := svc.ViewQuota(context.Background(), token, email)
+	assert.Nil(t, err, fmt.Sprintf("view removed quota: unexpected error: %s\n", err))
+	assert.Equal(t, uint64(10), quota.Things, fmt.Sprintf("view removed quota: expected default limit %d got %d\n", 10, quota.Things))
+}
+
+func TestTransferOwnership(t *testing.T) {
+	svc := newQuotaService(things.Quota{Things: 10, Channels: 10, Connections: 10})
+
+	th, err := svc.AddThing(context.Background(), disabledToken, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	ch, err := svc.CreateChannel(context.Background(), disabledToken, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	err = svc.Connect(context.Background(), disabledToken, ch.ID, th.ID, nil)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	err = svc.UpdateQuota(context.Background(), adminToken, things.Quota{Owner: adminEmail, Things: 1})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	_, err = svc.AddThing(context.Background(), adminToken, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc  string
+		token string
+		from  string
+		to    string
+		err   error
+	}{
+		{
+			desc:  "transfer ownership as non-admin",
+			token: token,
+			from:  disabledEmail,
+			to:    otherEmail,
+			err:   things.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "transfer ownership with wrong credentials",
+			token: wrongValue,
+			from:  disabledEmail,
+			to:    otherEmail,
+			err:   things.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "transfer ownership to the same owner",
+			token: adminToken,
+			from:  disabledEmail,
+			to:    disabledEmail,
+			err:   things.ErrMalformedEntity,
+		},
+		{
+			desc:  "transfer ownership to empty owner",
+			token: adminToken,
+			from:  disabledEmail,
+			to:    "",
+			err:   things.ErrMalformedEntity,
+		},
+		{
+			desc:  "transfer ownership of enabled owner",
+			token: adminToken,
+			from:  email,
+			to:    otherEmail,
+			err:   things.ErrActiveOwner,
+		},
+		{
+			desc:  "transfer ownership to non-existing owner",
+			token: adminToken,
+			from:  disabledEmail,
+			to:    "unknown@example.com",
+			err:   things.ErrNotFound,
+		},
+		{
+			desc:  "transfer ownership to disabled owner",
+			token: adminToken,
+			from:  "removed@example.com",
+			to:    disabledEmail,
+			err:   things.ErrMalformedEntity,
+		},
+		{
+			desc:  "transfer ownership above quota of new owner",
+			token: adminToken,
+			from:  disabledEmail,
+			to:    adminEmail,
+			err:   things.ErrQuotaExceeded,
+		},
+		{
+			desc:  "transfer ownership",
+			token: adminToken,
+			from:  disabledEmail,
+			to:    otherEmail,
+			err:   nil,
+		},
+		{
+			desc:  "transfer ownership of owner without entities",
+			token: adminToken,
+			from:  disabledEmail,
+			to:    otherEmail,
+			err:   nil,
+		},
+		{
+			desc:  "transfer ownership of removed owner",
+			token: adminToken,
+			from:  "removed@example.com",
+			to:    otherEmail,
+			err:   nil,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.TransferOwnership(context.Background(), tc.token, tc.from, tc.to)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	_, err = svc.ViewThing(context.Background(), disabledToken, th.ID)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("view transferred thing as previous owner: expected %s got %s\n", things.ErrNotFound, err))
+	_, err = svc.ViewThing(context.Background(), otherToken, th.ID)
+	assert.Nil(t, err, fmt.Sprintf("view transferred thing as new owner: unexpected error: %s\n", err))
+	_, err = svc.ViewChannel(context.Background(), otherToken, ch.ID)
+	assert.Nil(t, err, fmt.Sprintf("view transferred channel as new owner: unexpected error: %s\n", err))
+}
+
+const (
+	viewerEmail = "viewer@example.com"
+	viewerToken = "viewer-token"
+)
+
+func newRolesService() things.Service {
+	tokens := map[string]string{token: email, viewerToken: viewerEmail, adminToken: adminEmail}
+	roles := map[string]string{email: things.RoleEditor, viewerEmail: things.RoleViewer, adminEmail: things.RoleAdmin}
+	users := mocks.NewUsersServiceWithRoles(tokens, roles)
+	conns := make(chan mocks.Connection)
+	thingsRepo := mocks.NewThingRepository(conns)
+	channelsRepo := mocks.NewChannelRepository(thingsRepo, conns)
+	quotasRepo := mocks.NewQuotaRepository(thingsRepo, channelsRepo)
+	schemasRepo := mocks.NewSchemaRepository()
+	chanCache := mocks.NewChannelCache()
+	thingCache := mocks.NewThingCache()
+	idp := mocks.NewIdentityProvider()
+
+	return things.New(users, thingsRepo, channelsRepo, quotasRepo, schemasRepo, chanCache, thingCache, idp, nil, things.Quota{}, nil, keyGrace, 0, nil, nil, nil)
+}
+
+func TestRoles(t *testing.T) {
+	svc := newRolesService()
+
+	cases := []struct {
+		desc  string
+		token string
+		op    func(token string) error
+		err   error
+	}{
+		{
+			desc:  "add thing as editor",
+			token: token,
+			op: func(token string) error {
+				_, err := svc.AddThing(context.Background(), token, thing)
+				return err
+			},
+			err: nil,
+		},
+		{
+			desc:  "add thing as viewer",
+			token: viewerToken,
+			op: func(token string) error {
+				_, err := svc.AddThing(context.Background(), token, thing)
+				return err
+			},
+			err: things.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "create channel as viewer",
+			token: viewerToken,
+			op: func(token string) error {
+				_, err := svc.CreateChannel(context.Background(), token, channel)
+				return err
+			},
+			err: things.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "list things as viewer",
+			token: viewerToken,
+			op: func(token string) error {
+				_, err := svc.ListThings(context.Background(), token, things.PageQuery{Limit: 10})
+				return err
+			},
+			err: nil,
+		},
+		{
+			desc:  "list channels as viewer",
+			token: viewerToken,
+			op: func(token string) error {
+				_, err := svc.ListChannels(context.Background(), token, things.PageQuery{Limit: 10})
+				return err
+			},
+			err: nil,
+		},
+		{
+			desc:  "update quota as editor",
+			token: token,
+			op: func(token string) error {
+				return svc.UpdateQuota(context.Background(), token, things.Quota{Owner: email, Things: 1})
+			},
+			err: things.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "update quota as admin",
+			token: adminToken,
+			op: func(token string) error {
+				return svc.UpdateQuota(context.Background(), token, things.Quota{Owner: email, Things: 1})
+			},
+			err: nil,
+		},
+		{
+			desc:  "view other owner quota as admin",
+			token: adminToken,
+			op: func(token string) error {
+				_, _, err := svc.ViewQuota(context.Background(), token, email)
+				return err
+			},
+			err: nil,
+		},
+	}
+
+	for _, tc := range cases {
+		err := tc.op(tc.token)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+var metadataSchema = map[string]interface{}{
+	"type":     "object",
+	"required": []interface{}{"serial"},
+	"properties": map[string]interface{}{
+		"serial": map[string]interface{}{"type": "string"},
+	},
+}
+
+func TestSaveSchema(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	cases := []struct {
+		desc   string
+		token  string
+		schema things.MetadataSchema
+		err    error
+	}{
+		{
+			desc:   "save things schema",
+			token:  token,
+			schema: things.MetadataSchema{Entity: things.ThingsEntity, Definition: metadataSchema},
+			err:    nil,
+		},
+		{
+			desc:   "save channels schema",
+			token:  token,
+			schema: things.MetadataSchema{Entity: things.ChannelsEntity, Definition: metadataSchema},
+			err:    nil,
+		},
+		{
+			desc:   "save schema of unknown entity",
+			token:  token,
+			schema: things.MetadataSchema{Entity: "users", Definition: metadataSchema},
+			err:    things.ErrMalformedEntity,
+		},
+		{
+			desc:   "save invalid schema",
+			token:  token,
+			schema: things.MetadataSchema{Entity: things.ThingsEntity, Definition: map[string]interface{}{"type": 5}},
+			err:    things.ErrMalformedEntity,
+		},
+		{
+			desc:   "save empty schema",
+			token:  token,
+			schema: things.MetadataSchema{Entity: things.ThingsEntity},
+			err:    things.ErrMalformedEntity,
+		},
+		{
+			desc:   "save schema with wrong credentials",
+			token:  wrongValue,
+			schema: things.MetadataSchema{Entity: things.ThingsEntity, Definition: metadataSchema},
+			err:    things.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.SaveSchema(context.Background(), tc.token, tc.schema)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestViewSchema(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	err := svc.SaveSchema(context.Background(), token, things.MetadataSchema{Entity: things.ThingsEntity, Definition: metadataSchema})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc   string
+		token  string
+		entity string
+		schema things.MetadataSchema
+		err    error
+	}{
+		{
+			desc:   "view things schema",
+			token:  token,
+			entity: things.ThingsEntity,
+			schema: things.MetadataSchema{Owner: email, Entity: things.ThingsEntity, Definition: metadataSchema},
+			err:    nil,
+		},
+		{
+			desc:   "view non-existing channels schema",
+			token:  token,
+			entity: things.ChannelsEntity,
+			schema: things.MetadataSchema{},
+			err:    things.ErrNotFound,
+		},
+		{
+			desc:   "view schema of unknown entity",
+			token:  token,
+			entity: "users",
+			schema: things.MetadataSchema{},
+			err:    things.ErrMalformedEntity,
+		},
+		{
+			desc:   "view schema with wrong credentials",
+			token:  wrongValue,
+			entity: things.ThingsEntity,
+			schema: things.MetadataSchema{},
+			err:    things.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		schema, err := svc.ViewSchema(context.Background(), tc.token, tc.entity)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.schema, schema, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.schema, schema))
+	}
+}
+
+func TestRemoveSchema(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	err := svc.SaveSchema(context.Background(), token, things.MetadataSchema{Entity: things.ThingsEntity, Definition: metadataSchema})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc   string
+		token  string
+		entity string
+		err    error
+	}{
+		{
+			desc:   "remove schema with wrong credentials",
+			token:  wrongValue,
+			entity: things.ThingsEntity,
+			err:    things.ErrUnauthorizedAccess,
+		},
+		{
+			desc:   "remove schema of unknown entity",
+			token:  token,
+			entity: "users",
+			err:    things.ErrMalformedEntity,
+		},
+		{
+			desc:   "remove things schema",
+			token:  token,
+			entity: things.ThingsEntity,
+			err:    nil,
+		},
+		{
+			desc:   "remove non-existing schema",
+			token:  token,
+			entity: things.ThingsEntity,
+			err:    nil,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.RemoveSchema(context.Background(), tc.token, tc.entity)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	_, err = svc.AddThing(context.Background(), token, thing)
+	assert.Nil(t, err, fmt.Sprintf("add thing after removing schema: unexpected error: %s\n", err))
+}
+
+func TestThingsMetadataValidation(t *testing.T) {
+	svc := newSharingService()
+
+	sth, err := svc.AddThing(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	err = svc.ShareThing(context.Background(), token, sth.ID, group, things.EditPermission)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	err = svc.SaveSchema(context.Background(), token, things.MetadataSchema{Entity: things.ThingsEntity, Definition: metadataSchema})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	valid := things.Metadata{"serial": "abc"}
+	invalid := things.Metadata{"serial": 1}
+
+	_, err = svc.AddThing(context.Background(), token, things.Thing{Metadata: valid})
+	assert.Nil(t, err, fmt.Sprintf("add thing with valid metadata: unexpected error: %s\n", err))
+
+	_, err = svc.AddThing(context.Background(), token, things.Thing{Metadata: invalid})
+	verr, ok := err.(*things.ValidationError)
+	require.True(t, ok, fmt.Sprintf("add thing with invalid metadata: expected validation error got %s\n", err))
+	require.Len(t, verr.Violations, 1, fmt.Sprintf("add thing with invalid metadata: expected 1 violation got %d\n", len(verr.Violations)))
+	assert.Equal(t, "serial", verr.Violations[0].Field, fmt.Sprintf("add thing with invalid metadata: expected field %s got %s\n", "serial", verr.Violations[0].Field))
+
+	_, err = svc.AddThing(context.Background(), token, things.Thing{})
+	_, ok = err.(*things.ValidationError)
+	assert.True(t, ok, fmt.Sprintf("add thing without required metadata: expected validation error got %s\n", err))
+
+	_, err = svc.AddThing(context.Background(), memberToken, things.Thing{})
+	assert.Nil(t, err, fmt.Sprintf("add thing of the owner without schema: unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc     string
+		token    string
+		metadata things.Metadata
+		valid    bool
+	}{
+		{
+			desc:     "update thing with valid metadata",
+			token:    token,
+			metadata: valid,
+			valid:    true,
+		},
+		{
+			desc:     "update thing with invalid metadata",
+			token:    token,
+			metadata: invalid,
+			valid:    false,
+		},
+		{
+			desc:     "update shared thing with valid metadata",
+			token:    memberToken,
+			metadata: valid,
+			valid:    true,
+		},
+		{
+			desc:     "update shared thing with invalid metadata",
+			token:    memberToken,
+			metadata: invalid,
+			valid:    false,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.UpdateThing(context.Background(), tc.token, things.Thing{ID: sth.ID, Metadata: tc.metadata})
+		_, invalid := err.(*things.ValidationError)
+		assert.Equal(t, !tc.valid, invalid, fmt.Sprintf("%s: unexpected error: %v\n", tc.desc, err))
+		if tc.valid {
+			assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s\n", tc.desc, err))
+		}
+	}
+
+	imported, err := svc.ImportThings(context.Background(), token, []things.Thing{{Metadata: valid}, {Metadata: invalid}})
+	_, ok = err.(*things.ValidationError)
+	assert.True(t, ok, fmt.Sprintf("import things with invalid metadata: expected validation error got %s\n", err))
+	assert.Empty(t, imported, fmt.Sprintf("import things with invalid metadata: expected no imported things got %d\n", len(imported)))
+}
+
+func TestChannelsMetadataValidation(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	sch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	err = svc.SaveSchema(context.Background(), token, things.MetadataSchema{Entity: things.ChannelsEntity, Definition: metadataSchema})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	_, err = svc.AddThing(context.Background(), token, thing)
+	assert.Nil(t, err, fmt.Sprintf("add thing validated by channels schema: unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc     string
+		metadata things.Metadata
+		valid    bool
+	}{
+		{
+			desc:     "channel with valid metadata",
+			metadata: things.Metadata{"serial": "abc"},
+			valid:    true,
+		},
+		{
+			desc:     "channel with invalid metadata",
+			metadata: things.Metadata{"serial": 1},
+			valid:    false,
+		},
+		{
+			desc:     "channel without metadata",
+			metadata: nil,
+			valid:    false,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := svc.CreateChannel(context.Background(), token, things.Channel{Metadata: tc.metadata})
+		_, invalid := err.(*things.ValidationError)
+		assert.Equal(t, !tc.valid, invalid, fmt.Sprintf("create %s: unexpected error: %v\n", tc.desc, err))
+
+		err = svc.UpdateChannel(context.Background(), token, things.Channel{ID: sch.ID, Metadata: tc.metadata})
+		_, invalid = err.(*things.ValidationError)
+		assert.Equal(t, !tc.valid, invalid, fmt.Sprintf("update %s: unexpected error: %v\n", tc.desc, err))
+
+		_, err = svc.ImportChannels(context.Background(), token, []things.Channel{{Metadata: tc.metadata}})
+		_, invalid = err.(*things.ValidationError)
+		assert.Equal(t, !tc.valid, invalid, fmt.Sprintf("import %s: unexpected error: %v\n", tc.desc, err))
+	}
+}
+
+func TestSavePayloadSchema(t *testing.T) {
+	svc := newService(map[string]string{token: email, otherToken: "other@example.com"})
+
+	sch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc   string
+		token  string
+		schema things.PayloadSchema
+		err    error
+	}{
+		{
+			desc:   "save channel payload schema",
+			token:  token,
+			schema: things.PayloadSchema{Channel: sch.ID, Definition: metadataSchema},
+			err:    nil,
+		},
+		{
+			desc:   "save subtopic payload schema",
+			token:  token,
+			schema: things.PayloadSchema{Channel: sch.ID, Subtopic: "devices.temperature", Definition: metadataSchema},
+			err:    nil,
+		},
+		{
+			desc:   "save payload schema of wildcard subtopic",
+			token:  token,
+			schema: things.PayloadSchema{Channel: sch.ID, Subtopic: "devices.>", Definition: metadataSchema},
+			err:    things.ErrMalformedEntity,
+		},
+		{
+			desc:   "save payload schema of subtopic with empty token",
+			token:  token,
+			schema: things.PayloadSchema{Channel: sch.ID, Subtopic: "devices..temperature", Definition: metadataSchema},
+			err:    things.ErrMalformedEntity,
+		},
+		{
+			desc:   "save invalid payload schema",
+			token:  token,
+			schema: things.PayloadSchema{Channel: sch.ID, Definition: map[string]interface{}{"type": 5}},
+			err:    things.ErrMalformedEntity,
+		},
+		{
+			desc:   "save payload schema of other user's channel",
+			token:  otherToken,
+			schema: things.PayloadSchema{Channel: sch.ID, Definition: metadataSchema},
+			err:    things.ErrNotFound,
+		},
+		{
+			desc:   "save payload schema with wrong credentials",
+			token:  wrongValue,
+			schema: things.PayloadSchema{Channel: sch.ID, Definition: metadataSchema},
+			err:    things.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.SavePayloadSchema(context.Background(), tc.token, tc.schema)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	schemas, err := svc.ListPayloadSchemas(context.Background(), token, sch.ID)
+	assert.Nil(t, err, fmt.Sprintf("list payload schemas: unexpected error: %s\n", err))
+	assert.Equal(t, 2, len(schemas), fmt.Sprintf("list payload schemas: expected 2 schemas got %d\n", len(schemas)))
+
+	_, err = svc.ListPayloadSchemas(context.Background(), otherToken, sch.ID)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("list payload schemas of other user's channel: expected %s got %s\n", things.ErrNotFound, err))
+}
+
+func TestPayloadSchema(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	sch, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	other, err := svc.CreateChannel(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	sub := map[string]interface{}{"type": "array"}
+	err = svc.SavePayloadSchema(context.Background(), token, things.PayloadSchema{Channel: sch.ID, Definition: metadataSchema})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	err = svc.SavePayloadSchema(context.Background(), token, things.PayloadSchema{Channel: sch.ID, Subtopic: "devices", Definition: sub})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc     string
+		chanID   string
+		subtopic string
+		schema   map[string]interface{}
+		err      error
+	}{
+		{
+			desc:   "retrieve channel payload schema",
+			chanID: sch.ID,
+			schema: metadataSchema,
+			err:    nil,
+		},
+		{
+			desc:     "retrieve subtopic payload schema",
+			chanID:   sch.ID,
+			subtopic: "devices",
+			schema:   sub,
+			err:      nil,
+		},
+		{
+			desc:     "retrieve payload schema of subtopic without schema",
+			chanID:   sch.ID,
+			subtopic: "sensors",
+			schema:   metadataSchema,
+			err:      nil,
+		},
+		{
+			desc:     "retrieve payload schema of channel without schema",
+			chanID:   other.ID,
+			subtopic: "devices",
+			err:      things.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		schema, err := svc.PayloadSchema(context.Background(), tc.chanID, tc.subtopic)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.schema, schema.Definition, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.schema, schema.Definition))
+	}
+
+	err = svc.RemovePayloadSchema(context.Background(), token, sch.ID, "devices")
+	assert.Nil(t, err, fmt.Sprintf("remove payload schema: unexpected error: %s\n", err))
+	schema, err := svc.PayloadSchema(context.Background(), sch.ID, "devices")
+	assert.Nil(t, err, fmt.Sprintf("retrieve payload schema after removal: unexpected error: %s\n", err))
+	assert.Equal(t, metadataSchema, schema.Definition, fmt.Sprintf("retrieve payload schema after removal: expected %v got %v\n", metadataSchema, schema.Definition))
+}
@@ -0,0 +1,19 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package things
+
+const (
+	// ViewPermission allows the user, or members of the group, the entity is
+	// shared with to view the shared entity.
+	ViewPermission = "view"
+	// EditPermission allows the user, or members of the group, the entity is
+	// shared with to view and update the shared entity.
+	EditPermission = "edit"
+)
+
+// validPermission checks if the permission can be granted to a user or a
+// group.
+func validPermission(permission string) bool {
+	return permission == ViewPermission || permission == EditPermission
+}
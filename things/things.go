@@ -3,20 +3,125 @@
 
 package things
 
-import "context"
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
 
 // Metadata to be used for mainflux thing or channel for customized
 // describing of particular thing or channel.
 type Metadata map[string]interface{}
 
+const (
+	// EqualOp matches metadata values equal to the query value.
+	EqualOp = "="
+	// NotEqualOp matches metadata values different from the query value.
+	NotEqualOp = "!="
+	// LessOp matches metadata values less than the query value.
+	LessOp = "<"
+	// LessEqualOp matches metadata values less than or equal to the query value.
+	LessEqualOp = "<="
+	// GreaterOp matches metadata values greater than the query value.
+	GreaterOp = ">"
+	// GreaterEqualOp matches metadata values greater than or equal to the
+	// query value.
+	GreaterEqualOp = ">="
+)
+
+// Two-character operators go first, so that "<=" isn't parsed as "<".
+var operators = []string{LessEqualOp, GreaterEqualOp, NotEqualOp, LessOp, GreaterOp, EqualOp}
+
+var (
+	pathKeyRegexp = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+	versionRegexp = regexp.MustCompile(`^[0-9]+(\.[0-9]+)+$`)
+)
+
+// MetadataQuery represents a comparison of the metadata value found on the
+// path against the query value. Depending on the query value, values are
+// compared as numbers, as dotted versions (e.g. 1.4.0) or as text.
+type MetadataQuery struct {
+	Path     []string
+	Operator string
+	Value    string
+}
+
+// ParseMetadataQuery parses comparison in the form of path, operator and
+// value, e.g. "firmware.version<1.4.0". Path keys are separated by dots.
+func ParseMetadataQuery(query string) (MetadataQuery, error) {
+	i := strings.IndexAny(query, "<>!=")
+	if i < 0 {
+		return MetadataQuery{}, ErrMalformedEntity
+	}
+
+	op := ""
+	for _, o := range operators {
+		if strings.HasPrefix(query[i:], o) {
+			op = o
+			break
+		}
+	}
+	if op == "" {
+		return MetadataQuery{}, ErrMalformedEntity
+	}
+
+	path := strings.Split(query[:i], ".")
+	for _, k := range path {
+		if !pathKeyRegexp.MatchString(k) {
+			return MetadataQuery{}, ErrMalformedEntity
+		}
+	}
+
+	value := query[i+len(op):]
+	if value == "" {
+		return MetadataQuery{}, ErrMalformedEntity
+	}
+
+	return MetadataQuery{
+		Path:     path,
+		Operator: op,
+		Value:    value,
+	}, nil
+}
+
+// Number returns query value as a number, if it represents one.
+func (mq MetadataQuery) Number() (float64, bool) {
+	n, err := strconv.ParseFloat(mq.Value, 64)
+	return n, err == nil
+}
+
+// Version returns parts of the query value, if it represents a dotted
+// version with at least two parts. Values such as "1.4" are numbers.
+func (mq MetadataQuery) Version() ([]int64, bool) {
+	if _, ok := mq.Number(); ok || !versionRegexp.MatchString(mq.Value) {
+		return nil, false
+	}
+
+	parts := strings.Split(mq.Value, ".")
+	version := make([]int64, len(parts))
+	for i, p := range parts {
+		v, err := strconv.ParseInt(p, 10, 64)
+		if err != nil {
+			return nil, false
+		}
+		version[i] = v
+	}
+
+	return version, true
+}
+
 // Thing represents a Mainflux thing. Each thing is owned by one user, and
 // it is assigned with the unique identifier and (temporary) access key.
+// Removed things keep the time of removal until they are purged.
 type Thing struct {
-	ID       string
-	Owner    string
-	Name     string
-	Key      string
-	Metadata Metadata
+	ID        string
+	Owner     string
+	Name      string
+	Key       string
+	Metadata  Metadata
+	DeletedAt time.Time
 }
 
 // ThingsPage contains page related metadata as well as list of things that
@@ -36,27 +141,84 @@ type ThingRepository interface {
 	// returned to indicate operation failure.
 	Update(context.Context, Thing) error
 
-	// UpdateKey updates key value of the existing thing. A non-nil error is
-	// returned to indicate operation failure.
-	UpdateKey(context.Context, string, string, string) error
+	// UpdateKey replaces the key of the existing thing, recording the previous
+	// key in the key history, where it remains valid for the provided overlap
+	// period. Updating the thing to its current key has no effect, so that
+	// retried updates succeed.
+	UpdateKey(context.Context, string, string, string, time.Duration) error
+
+	// HashKeys replaces the plain-text keys of all the things, including the
+	// previous keys from the key history, with their hashes. Already hashed
+	// keys are left intact.
+	HashKeys(context.Context, KeyHasher) error
 
 	// RetrieveByID retrieves the thing having the provided identifier, that is owned
 	// by the specified user.
 	RetrieveByID(context.Context, string, string) (Thing, error)
 
-	// RetrieveByKey returns thing ID for given thing key.
+	// RetrieveByKey returns thing ID for given thing key. Previous keys of
+	// the things are accepted until their overlap period expires.
 	RetrieveByKey(context.Context, string) (string, error)
 
-	// RetrieveAll retrieves the subset of things owned by the specified user.
-	RetrieveAll(context.Context, string, uint64, uint64, string, Metadata) (ThingsPage, error)
+	// RetrieveOwner returns the owner of the thing having the provided
+	// identifier.
+	RetrieveOwner(context.Context, string) (string, error)
+
+	// RetrieveShared retrieves the thing having the provided identifier, that
+	// is shared with the specified user or any of the provided groups, along
+	// with the widest permission granted to them.
+	RetrieveShared(context.Context, string, string, []string) (Thing, string, error)
+
+	// RetrieveAll retrieves the subset of things owned by the specified user,
+	// as specified by the page query. If cursor is provided, only things
+	// following the cursor ID in the given direction are retrieved. If shared
+	// things are requested, things shared with the user or with any of the
+	// provided groups are retrieved as well.
+	RetrieveAll(context.Context, string, PageQuery, []string) (ThingsPage, error)
+
+	// Search retrieves the subset of things owned by the specified user whose
+	// name or metadata match the provided full-text query.
+	Search(context.Context, string, string, uint64, uint64) (ThingsPage, error)
 
 	// RetrieveByChannel retrieves the subset of things owned by the specified
 	// user and connected to specified channel.
 	RetrieveByChannel(context.Context, string, string, uint64, uint64) (ThingsPage, error)
 
-	// Remove removes the thing having the provided identifier, that is owned
-	// by the specified user.
+	// Export invokes the callback for each of the things owned by the
+	// specified user that are not removed. Things are not loaded into memory
+	// at once, and iteration stops at the first error returned by the
+	// callback.
+	Export(context.Context, string, func(Thing) error) error
+
+	// Remove marks the thing having the provided identifier, that is owned
+	// by the specified user, as removed. Removed thing can be restored.
 	Remove(context.Context, string, string) error
+
+	// Restore restores the removed thing having the provided identifier,
+	// that is owned by the specified user.
+	Restore(context.Context, string, string) error
+
+	// Purge permanently removes the thing having the provided identifier,
+	// that is owned by the specified user.
+	Purge(context.Context, string, string) error
+
+	// Share grants the permission on the thing having the provided
+	// identifier, that is owned by the specified user, to the group.
+	// Sharing already shared thing replaces the granted permission.
+	Share(context.Context, string, string, string, string) error
+
+	// Unshare revokes the access to the thing having the provided
+	// identifier, that is owned by the specified user, from the group.
+	Unshare(context.Context, string, string, string) error
+
+	// ShareWithUser grants the permission on the thing having the provided
+	// identifier, that is owned by the specified user, to the other user.
+	// Sharing already shared thing replaces the granted permission.
+	ShareWithUser(context.Context, string, string, string, string) error
+
+	// UnshareWithUser revokes the access to the thing having the provided
+	// identifier, that is owned by the specified user, from the other user.
+	UnshareWithUser(context.Context, string, string, string) error
 }
 
 // ThingCache contains thing caching interface.
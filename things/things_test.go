@@ -0,0 +1,93 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package things_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux/things"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMetadataQuery(t *testing.T) {
+	cases := map[string]struct {
+		query string
+		mq    things.MetadataQuery
+		err   error
+	}{
+		"parse query with nested path": {
+			query: "firmware.version<1.4.0",
+			mq:    things.MetadataQuery{Path: []string{"firmware", "version"}, Operator: things.LessOp, Value: "1.4.0"},
+			err:   nil,
+		},
+		"parse query with two-character operator": {
+			query: "level>=5",
+			mq:    things.MetadataQuery{Path: []string{"level"}, Operator: things.GreaterEqualOp, Value: "5"},
+			err:   nil,
+		},
+		"parse query with not equal operator": {
+			query: "type!=sensor",
+			mq:    things.MetadataQuery{Path: []string{"type"}, Operator: things.NotEqualOp, Value: "sensor"},
+			err:   nil,
+		},
+		"parse query with operator in value": {
+			query: "formula=a<b",
+			mq:    things.MetadataQuery{Path: []string{"formula"}, Operator: things.EqualOp, Value: "a<b"},
+			err:   nil,
+		},
+		"parse query without operator": {
+			query: "firmware.version",
+			err:   things.ErrMalformedEntity,
+		},
+		"parse query without path": {
+			query: "<1.4.0",
+			err:   things.ErrMalformedEntity,
+		},
+		"parse query with empty path key": {
+			query: "firmware..version<1.4.0",
+			err:   things.ErrMalformedEntity,
+		},
+		"parse query with invalid path key": {
+			query: "firmware.$version<1.4.0",
+			err:   things.ErrMalformedEntity,
+		},
+		"parse query without value": {
+			query: "level>",
+			err:   things.ErrMalformedEntity,
+		},
+		"parse query with invalid operator": {
+			query: "level!5",
+			err:   things.ErrMalformedEntity,
+		},
+	}
+
+	for desc, tc := range cases {
+		mq, err := things.ParseMetadataQuery(tc.query)
+		assert.Equal(t, tc.mq, mq, fmt.Sprintf("%s: expected %v got %v\n", desc, tc.mq, mq))
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
+
+func TestMetadataQueryValue(t *testing.T) {
+	cases := map[string]struct {
+		value   string
+		number  bool
+		version []int64
+	}{
+		"number value":          {value: "1.4", number: true},
+		"version value":         {value: "1.10.0", version: []int64{1, 10, 0}},
+		"text value":            {value: "sensor"},
+		"text value with dots":  {value: "1.4.x"},
+		"negative number value": {value: "-5", number: true},
+	}
+
+	for desc, tc := range cases {
+		mq := things.MetadataQuery{Value: tc.value}
+		_, number := mq.Number()
+		version, _ := mq.Version()
+		assert.Equal(t, tc.number, number, fmt.Sprintf("%s: expected %t got %t\n", desc, tc.number, number))
+		assert.Equal(t, tc.version, version, fmt.Sprintf("%s: expected %v got %v\n", desc, tc.version, version))
+	}
+}
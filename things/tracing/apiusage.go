@@ -0,0 +1,49 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package tracing
+
+import (
+	"context"
+	"time"
+
+	"github.com/mainflux/mainflux/things"
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+const (
+	saveAPIUsageOp            = "save_api_usage"
+	retrieveAPIUsageByOwnerOp = "retrieve_api_usage_by_owner"
+)
+
+var _ things.APIUsageRepository = (*apiUsageRepositoryMiddleware)(nil)
+
+type apiUsageRepositoryMiddleware struct {
+	tracer opentracing.Tracer
+	repo   things.APIUsageRepository
+}
+
+// APIUsageRepositoryMiddleware tracks request and their latency, and adds
+// spans to context.
+func APIUsageRepositoryMiddleware(tracer opentracing.Tracer, repo things.APIUsageRepository) things.APIUsageRepository {
+	return apiUsageRepositoryMiddleware{
+		tracer: tracer,
+		repo:   repo,
+	}
+}
+
+func (urm apiUsageRepositoryMiddleware) Save(ctx context.Context, usage ...things.APIUsage) error {
+	span := createSpan(ctx, urm.tracer, saveAPIUsageOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return urm.repo.Save(ctx, usage...)
+}
+
+func (urm apiUsageRepositoryMiddleware) RetrieveByOwner(ctx context.Context, owner string, from, to time.Time) ([]things.APIUsage, error) {
+	span := createSpan(ctx, urm.tracer, retrieveAPIUsageByOwnerOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return urm.repo.RetrieveByOwner(ctx, owner, from, to)
+}
@@ -0,0 +1,37 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package tracing
+
+import (
+	"context"
+
+	"github.com/mainflux/mainflux/things"
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+const retrieveChangesOp = "retrieve_changes"
+
+var _ things.ChangeLog = (*changeLogMiddleware)(nil)
+
+type changeLogMiddleware struct {
+	tracer opentracing.Tracer
+	log    things.ChangeLog
+}
+
+// ChangeLogMiddleware tracks request and their latency, and adds spans to
+// context.
+func ChangeLogMiddleware(tracer opentracing.Tracer, log things.ChangeLog) things.ChangeLog {
+	return changeLogMiddleware{
+		tracer: tracer,
+		log:    log,
+	}
+}
+
+func (clm changeLogMiddleware) RetrieveAll(ctx context.Context, owner, since string, limit uint64) (things.ChangesPage, error) {
+	span := createSpan(ctx, clm.tracer, retrieveChangesOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return clm.log.RetrieveAll(ctx, owner, since, limit)
+}
@@ -14,9 +14,27 @@ const (
 	saveChannelOp             = "save_channel"
 	updateChannelOp           = "update_channel"
 	retrieveChannelByIDOp     = "retrieve_channel_by_id"
+	retrieveSharedChannelOp   = "retrieve_shared_channel"
+	retrieveRetentionOp       = "retrieve_retention"
+	retrieveRegionOp          = "retrieve_region"
+	retrieveAliasOp           = "retrieve_alias"
+	retrieveOrderingOp        = "retrieve_ordering"
+	retrieveMetadataOp        = "retrieve_metadata"
+	retrieveByAliasOp         = "retrieve_by_alias"
+	retrieveDirectOp          = "retrieve_direct"
 	retrieveAllChannelsOp     = "retrieve_all_channels"
+	searchChannelsOp          = "search_channels"
 	retrieveChannelsByThingOp = "retrieve_channels_by_thing"
+	exportChannelsOp          = "export_channels"
+	exportConnectionsOp       = "export_connections"
 	removeChannelOp           = "retrieve_channel"
+	restoreChannelOp          = "restore_channel"
+	purgeChannelOp            = "purge_channel"
+	shareChannelOp            = "share_channel"
+	unshareChannelOp          = "unshare_channel"
+	shareChannelWithUserOp    = "share_channel_with_user"
+	unshareChannelWithUserOp  = "unshare_channel_with_user"
+	transferOp                = "transfer"
 	connectOp                 = "connect"
 	disconnectOp              = "disconnect"
 	hasThingOp                = "has_thing"
@@ -66,12 +84,84 @@ func (crm channelRepositoryMiddleware) RetrieveByID(ctx context.Context, owner,
 	return crm.repo.RetrieveByID(ctx, owner, id)
 }
 
-func (crm channelRepositoryMiddleware) RetrieveAll(ctx context.Context, owner string, offset, limit uint64, name string, metadata things.Metadata) (things.ChannelsPage, error) {
+func (crm channelRepositoryMiddleware) RetrieveShared(ctx context.Context, id, email string, groups []string) (things.Channel, string, error) {
+	span := createSpan(ctx, crm.tracer, retrieveSharedChannelOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return crm.repo.RetrieveShared(ctx, id, email, groups)
+}
+
+func (crm channelRepositoryMiddleware) RetrieveRetention(ctx context.Context, id string) (things.Retention, error) {
+	span := createSpan(ctx, crm.tracer, retrieveRetentionOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return crm.repo.RetrieveRetention(ctx, id)
+}
+
+func (crm channelRepositoryMiddleware) RetrieveRegion(ctx context.Context, id string) (string, error) {
+	span := createSpan(ctx, crm.tracer, retrieveRegionOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return crm.repo.RetrieveRegion(ctx, id)
+}
+
+func (crm channelRepositoryMiddleware) RetrieveAlias(ctx context.Context, id string) (string, error) {
+	span := createSpan(ctx, crm.tracer, retrieveAliasOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return crm.repo.RetrieveAlias(ctx, id)
+}
+
+func (crm channelRepositoryMiddleware) RetrieveOrdering(ctx context.Context, id string) (bool, error) {
+	span := createSpan(ctx, crm.tracer, retrieveOrderingOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return crm.repo.RetrieveOrdering(ctx, id)
+}
+
+func (crm channelRepositoryMiddleware) RetrieveMetadata(ctx context.Context, id string) (things.Metadata, error) {
+	span := createSpan(ctx, crm.tracer, retrieveMetadataOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return crm.repo.RetrieveMetadata(ctx, id)
+}
+
+func (crm channelRepositoryMiddleware) RetrieveByAlias(ctx context.Context, alias string) (string, error) {
+	span := createSpan(ctx, crm.tracer, retrieveByAliasOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return crm.repo.RetrieveByAlias(ctx, alias)
+}
+
+func (crm channelRepositoryMiddleware) RetrieveDirect(ctx context.Context, owner string, peers []string) (things.Channel, error) {
+	span := createSpan(ctx, crm.tracer, retrieveDirectOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return crm.repo.RetrieveDirect(ctx, owner, peers)
+}
+
+func (crm channelRepositoryMiddleware) RetrieveAll(ctx context.Context, owner string, pq things.PageQuery, groups []string) (things.ChannelsPage, error) {
 	span := createSpan(ctx, crm.tracer, retrieveAllChannelsOp)
 	defer span.Finish()
 	ctx = opentracing.ContextWithSpan(ctx, span)
 
-	return crm.repo.RetrieveAll(ctx, owner, offset, limit, name, metadata)
+	return crm.repo.RetrieveAll(ctx, owner, pq, groups)
+}
+
+func (crm channelRepositoryMiddleware) Search(ctx context.Context, owner, query string, offset, limit uint64) (things.ChannelsPage, error) {
+	span := createSpan(ctx, crm.tracer, searchChannelsOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return crm.repo.Search(ctx, owner, query, offset, limit)
 }
 
 func (crm channelRepositoryMiddleware) RetrieveByThing(ctx context.Context, owner, thing string, offset, limit uint64) (things.ChannelsPage, error) {
@@ -82,6 +172,22 @@ func (crm channelRepositoryMiddleware) RetrieveByThing(ctx context.Context, owne
 	return crm.repo.RetrieveByThing(ctx, owner, thing, offset, limit)
 }
 
+func (crm channelRepositoryMiddleware) Export(ctx context.Context, owner string, fn func(things.Channel) error) error {
+	span := createSpan(ctx, crm.tracer, exportChannelsOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return crm.repo.Export(ctx, owner, fn)
+}
+
+func (crm channelRepositoryMiddleware) ExportConnections(ctx context.Context, owner string, fn func(things.Connection) error) error {
+	span := createSpan(ctx, crm.tracer, exportConnectionsOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return crm.repo.ExportConnections(ctx, owner, fn)
+}
+
 func (crm channelRepositoryMiddleware) Remove(ctx context.Context, owner, id string) error {
 	span := createSpan(ctx, crm.tracer, removeChannelOp)
 	defer span.Finish()
@@ -90,12 +196,68 @@ func (crm channelRepositoryMiddleware) Remove(ctx context.Context, owner, id str
 	return crm.repo.Remove(ctx, owner, id)
 }
 
-func (crm channelRepositoryMiddleware) Connect(ctx context.Context, owner, chanID, thingID string) error {
+func (crm channelRepositoryMiddleware) Restore(ctx context.Context, owner, id string) error {
+	span := createSpan(ctx, crm.tracer, restoreChannelOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return crm.repo.Restore(ctx, owner, id)
+}
+
+func (crm channelRepositoryMiddleware) Purge(ctx context.Context, owner, id string) error {
+	span := createSpan(ctx, crm.tracer, purgeChannelOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return crm.repo.Purge(ctx, owner, id)
+}
+
+func (crm channelRepositoryMiddleware) Share(ctx context.Context, owner, id, group, permission string) error {
+	span := createSpan(ctx, crm.tracer, shareChannelOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return crm.repo.Share(ctx, owner, id, group, permission)
+}
+
+func (crm channelRepositoryMiddleware) Unshare(ctx context.Context, owner, id, group string) error {
+	span := createSpan(ctx, crm.tracer, unshareChannelOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return crm.repo.Unshare(ctx, owner, id, group)
+}
+
+func (crm channelRepositoryMiddleware) ShareWithUser(ctx context.Context, owner, id, email, permission string) error {
+	span := createSpan(ctx, crm.tracer, shareChannelWithUserOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return crm.repo.ShareWithUser(ctx, owner, id, email, permission)
+}
+
+func (crm channelRepositoryMiddleware) UnshareWithUser(ctx context.Context, owner, id, email string) error {
+	span := createSpan(ctx, crm.tracer, unshareChannelWithUserOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return crm.repo.UnshareWithUser(ctx, owner, id, email)
+}
+
+func (crm channelRepositoryMiddleware) Transfer(ctx context.Context, from, to string) error {
+	span := createSpan(ctx, crm.tracer, transferOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return crm.repo.Transfer(ctx, from, to)
+}
+
+func (crm channelRepositoryMiddleware) Connect(ctx context.Context, owner, chanID, thingID string, actions []string) error {
 	span := createSpan(ctx, crm.tracer, connectOp)
 	defer span.Finish()
 	ctx = opentracing.ContextWithSpan(ctx, span)
 
-	return crm.repo.Connect(ctx, owner, chanID, thingID)
+	return crm.repo.Connect(ctx, owner, chanID, thingID, actions)
 }
 
 func (crm channelRepositoryMiddleware) Disconnect(ctx context.Context, owner, chanID, thingID string) error {
@@ -106,20 +268,20 @@ func (crm channelRepositoryMiddleware) Disconnect(ctx context.Context, owner, ch
 	return crm.repo.Disconnect(ctx, owner, chanID, thingID)
 }
 
-func (crm channelRepositoryMiddleware) HasThing(ctx context.Context, chanID, key string) (string, error) {
+func (crm channelRepositoryMiddleware) HasThing(ctx context.Context, chanID, key, action string) (string, error) {
 	span := createSpan(ctx, crm.tracer, hasThingOp)
 	defer span.Finish()
 	ctx = opentracing.ContextWithSpan(ctx, span)
 
-	return crm.repo.HasThing(ctx, chanID, key)
+	return crm.repo.HasThing(ctx, chanID, key, action)
 }
 
-func (crm channelRepositoryMiddleware) HasThingByID(ctx context.Context, chanID, thingID string) error {
+func (crm channelRepositoryMiddleware) HasThingByID(ctx context.Context, chanID, thingID, action string) error {
 	span := createSpan(ctx, crm.tracer, hasThingByIDOp)
 	defer span.Finish()
 	ctx = opentracing.ContextWithSpan(ctx, span)
 
-	return crm.repo.HasThingByID(ctx, chanID, thingID)
+	return crm.repo.HasThingByID(ctx, chanID, thingID, action)
 }
 
 type channelCacheMiddleware struct {
@@ -136,20 +298,20 @@ func ChannelCacheMiddleware(tracer opentracing.Tracer, cache things.ChannelCache
 	}
 }
 
-func (ccm channelCacheMiddleware) Connect(ctx context.Context, chanID, thingID string) error {
+func (ccm channelCacheMiddleware) Connect(ctx context.Context, chanID, thingID, action string) error {
 	span := createSpan(ctx, ccm.tracer, connectOp)
 	defer span.Finish()
 	ctx = opentracing.ContextWithSpan(ctx, span)
 
-	return ccm.cache.Connect(ctx, chanID, thingID)
+	return ccm.cache.Connect(ctx, chanID, thingID, action)
 }
 
-func (ccm channelCacheMiddleware) HasThing(ctx context.Context, chanID, thingID string) bool {
+func (ccm channelCacheMiddleware) HasThing(ctx context.Context, chanID, thingID, action string) bool {
 	span := createSpan(ctx, ccm.tracer, hasThingOp)
 	defer span.Finish()
 	ctx = opentracing.ContextWithSpan(ctx, span)
 
-	return ccm.cache.HasThing(ctx, chanID, thingID)
+	return ccm.cache.HasThing(ctx, chanID, thingID, action)
 }
 
 func (ccm channelCacheMiddleware) Disconnect(ctx context.Context, chanID, thingID string) error {
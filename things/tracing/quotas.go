@@ -0,0 +1,66 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package tracing
+
+import (
+	"context"
+
+	"github.com/mainflux/mainflux/things"
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+const (
+	saveQuotaOp            = "save_quota"
+	retrieveQuotaByOwnerOp = "retrieve_quota_by_owner"
+	removeQuotaOp          = "remove_quota"
+	retrieveUsageOp        = "retrieve_usage"
+)
+
+var _ things.QuotaRepository = (*quotaRepositoryMiddleware)(nil)
+
+type quotaRepositoryMiddleware struct {
+	tracer opentracing.Tracer
+	repo   things.QuotaRepository
+}
+
+// QuotaRepositoryMiddleware tracks request and their latency, and adds spans
+// to context.
+func QuotaRepositoryMiddleware(tracer opentracing.Tracer, repo things.QuotaRepository) things.QuotaRepository {
+	return quotaRepositoryMiddleware{
+		tracer: tracer,
+		repo:   repo,
+	}
+}
+
+func (qrm quotaRepositoryMiddleware) Save(ctx context.Context, quota things.Quota) error {
+	span := createSpan(ctx, qrm.tracer, saveQuotaOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return qrm.repo.Save(ctx, quota)
+}
+
+func (qrm quotaRepositoryMiddleware) RetrieveByOwner(ctx context.Context, owner string) (things.Quota, error) {
+	span := createSpan(ctx, qrm.tracer, retrieveQuotaByOwnerOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return qrm.repo.RetrieveByOwner(ctx, owner)
+}
+
+func (qrm quotaRepositoryMiddleware) Remove(ctx context.Context, owner string) error {
+	span := createSpan(ctx, qrm.tracer, removeQuotaOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return qrm.repo.Remove(ctx, owner)
+}
+
+func (qrm quotaRepositoryMiddleware) RetrieveUsage(ctx context.Context, owner string) (things.Usage, error) {
+	span := createSpan(ctx, qrm.tracer, retrieveUsageOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return qrm.repo.RetrieveUsage(ctx, owner)
+}
@@ -0,0 +1,93 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package tracing
+
+import (
+	"context"
+
+	"github.com/mainflux/mainflux/things"
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+const (
+	saveSchemaOp             = "save_schema"
+	retrieveSchemaByEntityOp = "retrieve_schema_by_entity"
+	removeSchemaOp           = "remove_schema"
+	savePayloadSchemaOp      = "save_payload_schema"
+	retrievePayloadSchemaOp  = "retrieve_payload_schema"
+	retrievePayloadSchemasOp = "retrieve_payload_schemas"
+	removePayloadSchemaOp    = "remove_payload_schema"
+)
+
+var _ things.SchemaRepository = (*schemaRepositoryMiddleware)(nil)
+
+type schemaRepositoryMiddleware struct {
+	tracer opentracing.Tracer
+	repo   things.SchemaRepository
+}
+
+// SchemaRepositoryMiddleware tracks request and their latency, and adds
+// spans to context.
+func SchemaRepositoryMiddleware(tracer opentracing.Tracer, repo things.SchemaRepository) things.SchemaRepository {
+	return schemaRepositoryMiddleware{
+		tracer: tracer,
+		repo:   repo,
+	}
+}
+
+func (srm schemaRepositoryMiddleware) Save(ctx context.Context, schema things.MetadataSchema) error {
+	span := createSpan(ctx, srm.tracer, saveSchemaOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return srm.repo.Save(ctx, schema)
+}
+
+func (srm schemaRepositoryMiddleware) RetrieveByEntity(ctx context.Context, owner, entity string) (things.MetadataSchema, error) {
+	span := createSpan(ctx, srm.tracer, retrieveSchemaByEntityOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return srm.repo.RetrieveByEntity(ctx, owner, entity)
+}
+
+func (srm schemaRepositoryMiddleware) Remove(ctx context.Context, owner, entity string) error {
+	span := createSpan(ctx, srm.tracer, removeSchemaOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return srm.repo.Remove(ctx, owner, entity)
+}
+
+func (srm schemaRepositoryMiddleware) SavePayload(ctx context.Context, schema things.PayloadSchema) error {
+	span := createSpan(ctx, srm.tracer, savePayloadSchemaOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return srm.repo.SavePayload(ctx, schema)
+}
+
+func (srm schemaRepositoryMiddleware) RetrievePayload(ctx context.Context, chanID, subtopic string) (things.PayloadSchema, error) {
+	span := createSpan(ctx, srm.tracer, retrievePayloadSchemaOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return srm.repo.RetrievePayload(ctx, chanID, subtopic)
+}
+
+func (srm schemaRepositoryMiddleware) RetrievePayloads(ctx context.Context, chanID string) ([]things.PayloadSchema, error) {
+	span := createSpan(ctx, srm.tracer, retrievePayloadSchemasOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return srm.repo.RetrievePayloads(ctx, chanID)
+}
+
+func (srm schemaRepositoryMiddleware) RemovePayload(ctx context.Context, chanID, subtopic string) error {
+	span := createSpan(ctx, srm.tracer, removePayloadSchemaOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return srm.repo.RemovePayload(ctx, chanID, subtopic)
+}
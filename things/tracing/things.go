@@ -5,6 +5,7 @@ package tracing
 
 import (
 	"context"
+	"time"
 
 	"github.com/mainflux/mainflux/things"
 	opentracing "github.com/opentracing/opentracing-go"
@@ -14,11 +15,22 @@ const (
 	saveThingOp               = "save_thing"
 	updateThingOp             = "update_thing"
 	updateThingKeyOp          = "update_thing_by_key"
+	hashThingKeysOp           = "hash_thing_keys"
 	retrieveThingByIDOp       = "retrieve_thing_by_id"
 	retrieveThingByKeyOp      = "retrieve_thing_by_key"
+	retrieveThingOwnerOp      = "retrieve_thing_owner"
+	retrieveSharedThingOp     = "retrieve_shared_thing"
 	retrieveAllThingsOp       = "retrieve_all_things"
+	searchThingsOp            = "search_things"
 	retrieveThingsByChannelOp = "retrieve_things_by_chan"
+	exportThingsOp            = "export_things"
 	removeThingOp             = "remove_thing"
+	restoreThingOp            = "restore_thing"
+	purgeThingOp              = "purge_thing"
+	shareThingOp              = "share_thing"
+	unshareThingOp            = "unshare_thing"
+	shareThingWithUserOp      = "share_thing_with_user"
+	unshareThingWithUserOp    = "unshare_thing_with_user"
 	retrieveThingIDByKeyOp    = "retrieve_id_by_key"
 )
 
@@ -57,12 +69,20 @@ func (trm thingRepositoryMiddleware) Update(ctx context.Context, th things.Thing
 	return trm.repo.Update(ctx, th)
 }
 
-func (trm thingRepositoryMiddleware) UpdateKey(ctx context.Context, owner, id, key string) error {
+func (trm thingRepositoryMiddleware) UpdateKey(ctx context.Context, owner, id, key string, overlap time.Duration) error {
 	span := createSpan(ctx, trm.tracer, updateThingKeyOp)
 	defer span.Finish()
 	ctx = opentracing.ContextWithSpan(ctx, span)
 
-	return trm.repo.UpdateKey(ctx, owner, id, key)
+	return trm.repo.UpdateKey(ctx, owner, id, key, overlap)
+}
+
+func (trm thingRepositoryMiddleware) HashKeys(ctx context.Context, hasher things.KeyHasher) error {
+	span := createSpan(ctx, trm.tracer, hashThingKeysOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return trm.repo.HashKeys(ctx, hasher)
 }
 
 func (trm thingRepositoryMiddleware) RetrieveByID(ctx context.Context, owner, id string) (things.Thing, error) {
@@ -81,12 +101,36 @@ func (trm thingRepositoryMiddleware) RetrieveByKey(ctx context.Context, key stri
 	return trm.repo.RetrieveByKey(ctx, key)
 }
 
-func (trm thingRepositoryMiddleware) RetrieveAll(ctx context.Context, owner string, offset, limit uint64, name string, metadata things.Metadata) (things.ThingsPage, error) {
+func (trm thingRepositoryMiddleware) RetrieveOwner(ctx context.Context, id string) (string, error) {
+	span := createSpan(ctx, trm.tracer, retrieveThingOwnerOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return trm.repo.RetrieveOwner(ctx, id)
+}
+
+func (trm thingRepositoryMiddleware) RetrieveShared(ctx context.Context, id, email string, groups []string) (things.Thing, string, error) {
+	span := createSpan(ctx, trm.tracer, retrieveSharedThingOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return trm.repo.RetrieveShared(ctx, id, email, groups)
+}
+
+func (trm thingRepositoryMiddleware) RetrieveAll(ctx context.Context, owner string, pq things.PageQuery, groups []string) (things.ThingsPage, error) {
 	span := createSpan(ctx, trm.tracer, retrieveAllThingsOp)
 	defer span.Finish()
 	ctx = opentracing.ContextWithSpan(ctx, span)
 
-	return trm.repo.RetrieveAll(ctx, owner, offset, limit, name, metadata)
+	return trm.repo.RetrieveAll(ctx, owner, pq, groups)
+}
+
+func (trm thingRepositoryMiddleware) Search(ctx context.Context, owner, query string, offset, limit uint64) (things.ThingsPage, error) {
+	span := createSpan(ctx, trm.tracer, searchThingsOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return trm.repo.Search(ctx, owner, query, offset, limit)
 }
 
 func (trm thingRepositoryMiddleware) RetrieveByChannel(ctx context.Context, owner, channel string, offset, limit uint64) (things.ThingsPage, error) {
@@ -97,6 +141,14 @@ func (trm thingRepositoryMiddleware) RetrieveByChannel(ctx context.Context, owne
 	return trm.repo.RetrieveByChannel(ctx, owner, channel, offset, limit)
 }
 
+func (trm thingRepositoryMiddleware) Export(ctx context.Context, owner string, fn func(things.Thing) error) error {
+	span := createSpan(ctx, trm.tracer, exportThingsOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return trm.repo.Export(ctx, owner, fn)
+}
+
 func (trm thingRepositoryMiddleware) Remove(ctx context.Context, owner, id string) error {
 	span := createSpan(ctx, trm.tracer, removeThingOp)
 	defer span.Finish()
@@ -105,6 +157,54 @@ func (trm thingRepositoryMiddleware) Remove(ctx context.Context, owner, id strin
 	return trm.repo.Remove(ctx, owner, id)
 }
 
+func (trm thingRepositoryMiddleware) Restore(ctx context.Context, owner, id string) error {
+	span := createSpan(ctx, trm.tracer, restoreThingOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return trm.repo.Restore(ctx, owner, id)
+}
+
+func (trm thingRepositoryMiddleware) Purge(ctx context.Context, owner, id string) error {
+	span := createSpan(ctx, trm.tracer, purgeThingOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return trm.repo.Purge(ctx, owner, id)
+}
+
+func (trm thingRepositoryMiddleware) Share(ctx context.Context, owner, id, group, permission string) error {
+	span := createSpan(ctx, trm.tracer, shareThingOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return trm.repo.Share(ctx, owner, id, group, permission)
+}
+
+func (trm thingRepositoryMiddleware) Unshare(ctx context.Context, owner, id, group string) error {
+	span := createSpan(ctx, trm.tracer, unshareThingOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return trm.repo.Unshare(ctx, owner, id, group)
+}
+
+func (trm thingRepositoryMiddleware) ShareWithUser(ctx context.Context, owner, id, email, permission string) error {
+	span := createSpan(ctx, trm.tracer, shareThingWithUserOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return trm.repo.ShareWithUser(ctx, owner, id, email, permission)
+}
+
+func (trm thingRepositoryMiddleware) UnshareWithUser(ctx context.Context, owner, id, email string) error {
+	span := createSpan(ctx, trm.tracer, unshareThingWithUserOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return trm.repo.UnshareWithUser(ctx, owner, id, email)
+}
+
 type thingCacheMiddleware struct {
 	tracer opentracing.Tracer
 	cache  things.ThingCache
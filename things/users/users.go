@@ -13,6 +13,8 @@ import (
 
 	"github.com/mainflux/mainflux"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 var _ mainflux.UsersServiceClient = (*singleUserRepo)(nil)
@@ -40,3 +42,19 @@ func (repo singleUserRepo) Identify(ctx context.Context, token *mainflux.Token,
 
 	return &mainflux.UserID{Value: repo.email}, nil
 }
+
+func (repo singleUserRepo) Groups(ctx context.Context, token *mainflux.Token, opts ...grpc.CallOption) (*mainflux.GroupIDs, error) {
+	if repo.token != token.GetValue() {
+		return nil, things.ErrUnauthorizedAccess
+	}
+
+	return &mainflux.GroupIDs{}, nil
+}
+
+func (repo singleUserRepo) Disabled(ctx context.Context, id *mainflux.UserID, opts ...grpc.CallOption) (*mainflux.UserStatus, error) {
+	if repo.email != id.GetValue() {
+		return nil, status.Error(codes.NotFound, "user not found")
+	}
+
+	return &mainflux.UserStatus{}, nil
+}
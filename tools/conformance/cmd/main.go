@@ -0,0 +1,71 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"log"
+	"math/rand"
+	"os"
+	"time"
+
+	"github.com/mainflux/mainflux/tools/conformance"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func main() {
+	confFile := ""
+	cconf := conformance.Config{}
+
+	var rootCmd = &cobra.Command{
+		Use:   "conformance",
+		Short: "conformance is protocol conformance tool for Mainflux",
+		Long: `Tool for checking protocol behaviour of MQTT, CoAP and WebSocket adapters of a running Mainflux deployment.
+Complete documentation is available at https://mainflux.readthedocs.io`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if confFile != "" {
+				viper.SetConfigFile(confFile)
+
+				if err := viper.ReadInConfig(); err != nil {
+					log.Fatalf("Failed to load config - %s", err.Error())
+				}
+
+				if err := viper.Unmarshal(&cconf); err != nil {
+					log.Fatalf("Unable to decode into struct, %v", err)
+				}
+			}
+
+			rand.Seed(time.Now().UnixNano())
+			rep := conformance.Run(cconf)
+			if err := rep.Write(os.Stdout, cconf.Format); err != nil {
+				log.Fatalf("Failed to write report - %s", err.Error())
+			}
+
+			if !rep.Compliant() {
+				os.Exit(1)
+			}
+		},
+	}
+
+	// Adapters
+	rootCmd.PersistentFlags().StringVarP(&cconf.MQTT.URL, "mqtt", "", "tcp://localhost:1883", "MQTT broker URL, empty to skip MQTT checks")
+	rootCmd.PersistentFlags().StringVarP(&cconf.CoAP.Addr, "coap", "", "localhost:5683", "CoAP adapter address, empty to skip CoAP checks")
+	rootCmd.PersistentFlags().StringVarP(&cconf.WS.URL, "ws", "", "ws://localhost:8186", "WebSocket adapter URL, empty to skip WebSocket checks")
+
+	// Mainflux connection
+	rootCmd.PersistentFlags().StringVarP(&cconf.Thing.ID, "thing", "", "", "ID of the thing connected to the channel")
+	rootCmd.PersistentFlags().StringVarP(&cconf.Thing.Key, "key", "k", "", "Key of the thing connected to the channel")
+	rootCmd.PersistentFlags().StringVarP(&cconf.Thing.ChannelID, "channel", "", "", "ID of the channel")
+
+	// Test params
+	rootCmd.PersistentFlags().IntVarP(&cconf.Timeout, "timeout", "t", 5, "Timeout of a single operation in seconds")
+	rootCmd.PersistentFlags().StringVarP(&cconf.Format, "format", "f", "text", "Output format: text|json")
+
+	// Config file
+	rootCmd.PersistentFlags().StringVarP(&confFile, "config", "c", "", "config file for conformance")
+
+	if err := rootCmd.Execute(); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package conformance
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"time"
+
+	gocoap "github.com/dustin/go-coap"
+)
+
+const (
+	observeRegister   = 0
+	observeDeregister = 1
+)
+
+var coapChecks = []check{
+	{name: "publish confirmable message", run: coapPublish},
+	{name: "reject invalid key", run: coapInvalidKey},
+	{name: "reject unknown path", run: coapUnknownPath},
+	{name: "observe channel", run: coapObserve},
+}
+
+func coapMessage(code gocoap.COAPCode, path, key string) gocoap.Message {
+	msg := gocoap.Message{
+		Type:      gocoap.Confirmable,
+		Code:      code,
+		MessageID: uint16(rand.Intn(1 << 16)),
+		Token:     []byte(fmt.Sprintf("%08x", rand.Uint32())),
+	}
+	msg.SetPathString(path)
+	msg.AddOption(gocoap.URIQuery, fmt.Sprintf("authorization=%s", key))
+	return msg
+}
+
+func coapChannel(cfg Config) string {
+	return fmt.Sprintf("channels/%s/messages", cfg.Thing.ChannelID)
+}
+
+func coapSend(cfg Config, req gocoap.Message) (*gocoap.Message, error) {
+	conn, err := gocoap.Dial("udp", cfg.CoAP.Addr)
+	if err != nil {
+		return nil, err
+	}
+	return conn.Send(req)
+}
+
+func coapExpect(cfg Config, req gocoap.Message, code gocoap.COAPCode) error {
+	res, err := coapSend(cfg, req)
+	if err != nil {
+		return err
+	}
+	if res.Code != code {
+		return fmt.Errorf("expected response code %s, got %s", code, res.Code)
+	}
+	if res.MessageID != req.MessageID {
+		return fmt.Errorf("expected message ID %d, got %d", req.MessageID, res.MessageID)
+	}
+	return nil
+}
+
+func coapPublish(cfg Config) error {
+	req := coapMessage(gocoap.POST, coapChannel(cfg), cfg.Thing.Key)
+	req.Payload = payload("coap")
+	return coapExpect(cfg, req, gocoap.Changed)
+}
+
+func coapInvalidKey(cfg Config) error {
+	req := coapMessage(gocoap.POST, coapChannel(cfg), "invalid")
+	req.Payload = payload("coap")
+	return coapExpect(cfg, req, gocoap.Forbidden)
+}
+
+func coapUnknownPath(cfg Config) error {
+	req := coapMessage(gocoap.POST, "unknown", cfg.Thing.Key)
+	req.Payload = payload("coap")
+	return coapExpect(cfg, req, gocoap.NotFound)
+}
+
+// coapObserve registers an observer of the channel and checks that the
+// message published to the channel is delivered as a notification.
+func coapObserve(cfg Config) error {
+	conn, err := gocoap.Dial("udp", cfg.CoAP.Addr)
+	if err != nil {
+		return err
+	}
+
+	req := coapMessage(gocoap.GET, coapChannel(cfg), cfg.Thing.Key)
+	req.SetOption(gocoap.Observe, observeRegister)
+	res, err := conn.Send(req)
+	if err != nil {
+		return err
+	}
+	if res.Code != gocoap.Content {
+		return fmt.Errorf("expected response code %s, got %s", gocoap.Content, res.Code)
+	}
+	if res.Option(gocoap.Observe) == nil {
+		return skip("server does not support observe")
+	}
+	defer func() {
+		req.MessageID++
+		req.SetOption(gocoap.Observe, observeDeregister)
+		conn.Send(req)
+	}()
+
+	pub := coapMessage(gocoap.POST, coapChannel(cfg), cfg.Thing.Key)
+	pub.Payload = payload("coap")
+	if err := coapExpect(cfg, pub, gocoap.Changed); err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(timeout(cfg))
+	for time.Now().Before(deadline) {
+		n, err := conn.Receive()
+		if e, ok := err.(net.Error); ok && e.Timeout() {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(n.Token, req.Token) || !bytes.Equal(n.Payload, pub.Payload) {
+			continue
+		}
+		if n.Code != gocoap.Content {
+			return fmt.Errorf("expected notification code %s, got %s", gocoap.Content, n.Code)
+		}
+		if n.Option(gocoap.Observe) == nil {
+			return errors.New("notification is missing observe option")
+		}
+		return nil
+	}
+
+	return errors.New("published message was not delivered to the observer")
+}
@@ -0,0 +1,34 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package conformance
+
+// Keep struct names exported, otherwise Viper unmarshalling won't work
+type mqttConfig struct {
+	URL string `toml:"url" mapstructure:"url"`
+}
+
+type coapConfig struct {
+	Addr string `toml:"addr" mapstructure:"addr"`
+}
+
+type wsConfig struct {
+	URL string `toml:"url" mapstructure:"url"`
+}
+
+type thingConfig struct {
+	ID        string `toml:"thing_id" mapstructure:"thing_id"`
+	Key       string `toml:"thing_key" mapstructure:"thing_key"`
+	ChannelID string `toml:"channel_id" mapstructure:"channel_id"`
+}
+
+// Config struct holds conformance harness configuration. Adapters with an
+// empty address are not checked.
+type Config struct {
+	MQTT    mqttConfig  `toml:"mqtt" mapstructure:"mqtt"`
+	CoAP    coapConfig  `toml:"coap" mapstructure:"coap"`
+	WS      wsConfig    `toml:"ws" mapstructure:"ws"`
+	Thing   thingConfig `toml:"mainflux" mapstructure:"mainflux"`
+	Timeout int         `toml:"timeout" mapstructure:"timeout"`
+	Format  string      `toml:"format" mapstructure:"format"`
+}
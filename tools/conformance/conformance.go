@@ -0,0 +1,118 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package conformance contains a harness which exercises protocol behaviour
+// of the MQTT, CoAP and WebSocket adapters of a running Mainflux deployment
+// and produces a compliance report.
+package conformance
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	statusPass = "pass"
+	statusFail = "fail"
+	statusSkip = "skip"
+)
+
+// skipError marks a check which could not be performed because the
+// deployment does not support the checked feature.
+type skipError struct {
+	reason string
+}
+
+func (e skipError) Error() string {
+	return e.reason
+}
+
+func skip(format string, args ...interface{}) error {
+	return skipError{reason: fmt.Sprintf(format, args...)}
+}
+
+type check struct {
+	name string
+	run  func(Config) error
+}
+
+// Result represents outcome of a single conformance check.
+type Result struct {
+	Protocol string  `json:"protocol"`
+	Check    string  `json:"check"`
+	Status   string  `json:"status"`
+	Reason   string  `json:"reason,omitempty"`
+	Duration float64 `json:"duration"`
+}
+
+// Report represents compliance report of a deployment.
+type Report struct {
+	Results []Result `json:"results"`
+	Passed  int      `json:"passed"`
+	Failed  int      `json:"failed"`
+	Skipped int      `json:"skipped"`
+}
+
+// Compliant returns true if none of the performed checks failed.
+func (r Report) Compliant() bool {
+	return r.Failed == 0
+}
+
+// Run performs checks of all the configured adapters.
+func Run(cfg Config) Report {
+	rep := Report{}
+
+	if cfg.MQTT.URL != "" {
+		rep.run("mqtt", mqttChecks, cfg)
+	}
+	if cfg.CoAP.Addr != "" {
+		rep.run("coap", coapChecks, cfg)
+	}
+	if cfg.WS.URL != "" {
+		rep.run("ws", wsChecks, cfg)
+	}
+
+	return rep
+}
+
+func (r *Report) run(protocol string, checks []check, cfg Config) {
+	for _, c := range checks {
+		res := Result{
+			Protocol: protocol,
+			Check:    c.name,
+			Status:   statusPass,
+		}
+
+		start := time.Now()
+		err := c.run(cfg)
+		res.Duration = time.Since(start).Seconds()
+
+		switch err.(type) {
+		case nil:
+			r.Passed++
+		case skipError:
+			res.Status = statusSkip
+			res.Reason = err.Error()
+			r.Skipped++
+		default:
+			res.Status = statusFail
+			res.Reason = err.Error()
+			r.Failed++
+		}
+
+		r.Results = append(r.Results, res)
+	}
+}
+
+func timeout(cfg Config) time.Duration {
+	if cfg.Timeout <= 0 {
+		return 5 * time.Second
+	}
+	return time.Duration(cfg.Timeout) * time.Second
+}
+
+// payload returns unique SenML payload, so that checks don't match messages
+// published by the previous ones.
+func payload(name string) []byte {
+	return []byte(fmt.Sprintf(`[{"bn":"conformance:","n":"%s","v":%d}]`, name, time.Now().UnixNano()))
+}
@@ -0,0 +1,243 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package conformance
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/eclipse/paho.mqtt.golang/packets"
+)
+
+const (
+	mqttV5                  = 5
+	mqttConnAck             = 0x20
+	mqttSuccess             = 0x00
+	mqttV3Unacceptable      = 0x01
+	mqttUnsupportedProtocol = 0x84
+)
+
+var mqttChecks = []check{
+	{name: "connect with valid credentials", run: mqttConnect},
+	{name: "reject invalid credentials", run: mqttInvalidCredentials},
+	{name: "publish and subscribe with QoS 0", run: mqttPubSub(0, "")},
+	{name: "publish and subscribe with QoS 1", run: mqttPubSub(1, "")},
+	{name: "subtopic wildcard subscription", run: mqttPubSub(1, "conformance")},
+	{name: "MQTT 5 connect", run: mqtt5Connect},
+}
+
+func mqttClient(cfg Config, id, password string) (mqtt.Client, error) {
+	opts := mqtt.NewClientOptions().
+		AddBroker(cfg.MQTT.URL).
+		SetClientID(id).
+		SetUsername(cfg.Thing.ID).
+		SetPassword(password).
+		SetCleanSession(true).
+		SetAutoReconnect(false).
+		SetConnectTimeout(timeout(cfg)).
+		SetProtocolVersion(4)
+
+	client := mqtt.NewClient(opts)
+	token := client.Connect()
+	if !token.WaitTimeout(timeout(cfg)) {
+		return nil, errors.New("connect timed out")
+	}
+	if err := token.Error(); err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+func mqttTopic(cfg Config, subtopic string) string {
+	topic := fmt.Sprintf("channels/%s/messages", cfg.Thing.ChannelID)
+	if subtopic != "" {
+		topic = fmt.Sprintf("%s/%s", topic, subtopic)
+	}
+	return topic
+}
+
+func mqttConnect(cfg Config) error {
+	client, err := mqttClient(cfg, "conformance-connect", cfg.Thing.Key)
+	if err != nil {
+		return err
+	}
+	client.Disconnect(0)
+	return nil
+}
+
+func mqttInvalidCredentials(cfg Config) error {
+	client, err := mqttClient(cfg, "conformance-invalid", "invalid")
+	switch err {
+	case nil:
+		client.Disconnect(0)
+		return errors.New("broker accepted invalid credentials")
+	case packets.ConnErrors[packets.ErrRefusedBadUsernameOrPassword],
+		packets.ConnErrors[packets.ErrRefusedNotAuthorised]:
+		return nil
+	default:
+		return fmt.Errorf("expected connection to be refused, got %s", err)
+	}
+}
+
+// mqttPubSub checks that the message published to the channel is delivered
+// to the subscriber. If subtopic is set, message is published to the
+// subtopic and subscription is made using multi-level wildcard.
+func mqttPubSub(qos byte, subtopic string) func(Config) error {
+	return func(cfg Config) error {
+		sub, err := mqttClient(cfg, "conformance-sub", cfg.Thing.Key)
+		if err != nil {
+			return err
+		}
+		defer sub.Disconnect(0)
+
+		pub, err := mqttClient(cfg, "conformance-pub", cfg.Thing.Key)
+		if err != nil {
+			return err
+		}
+		defer pub.Disconnect(0)
+
+		filter, topic := mqttTopic(cfg, ""), mqttTopic(cfg, "")
+		if subtopic != "" {
+			filter, topic = mqttTopic(cfg, "#"), mqttTopic(cfg, subtopic)
+		}
+
+		msgs := make(chan mqtt.Message, 1)
+		token := sub.Subscribe(filter, qos, func(_ mqtt.Client, m mqtt.Message) {
+			select {
+			case msgs <- m:
+			default:
+			}
+		})
+		if !token.WaitTimeout(timeout(cfg)) {
+			return errors.New("subscribe timed out")
+		}
+		if err := token.Error(); err != nil {
+			return err
+		}
+
+		p := payload("mqtt")
+		token = pub.Publish(topic, qos, false, p)
+		if !token.WaitTimeout(timeout(cfg)) {
+			return errors.New("publish timed out")
+		}
+		if err := token.Error(); err != nil {
+			return err
+		}
+
+		deadline := time.After(timeout(cfg))
+		for {
+			select {
+			case m := <-msgs:
+				if !bytes.Equal(m.Payload(), p) {
+					continue
+				}
+				if m.Topic() != topic {
+					return fmt.Errorf("expected message on topic %s, got %s", topic, m.Topic())
+				}
+				if m.Qos() > qos {
+					return fmt.Errorf("expected QoS at most %d, got %d", qos, m.Qos())
+				}
+				return nil
+			case <-deadline:
+				return errors.New("published message was not delivered")
+			}
+		}
+	}
+}
+
+// mqtt5Connect sends MQTT 5 CONNECT packet and checks that the broker
+// acknowledges it. Paho client supports only MQTT 3.1.1, so the packet is
+// encoded by hand.
+func mqtt5Connect(cfg Config) error {
+	conn, err := mqttDial(cfg)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(timeout(cfg)))
+
+	var vh bytes.Buffer
+	writeString(&vh, "MQTT")
+	// Protocol level, flags (username, password, clean start), keep alive
+	// and empty properties.
+	vh.Write([]byte{mqttV5, 0xC2, 0x00, 0x3C, 0x00})
+	writeString(&vh, "conformance-mqtt5")
+	writeString(&vh, cfg.Thing.ID)
+	writeString(&vh, cfg.Thing.Key)
+
+	pkt := []byte{0x10}
+	pkt = append(pkt, remainingLength(vh.Len())...)
+	pkt = append(pkt, vh.Bytes()...)
+	if _, err := conn.Write(pkt); err != nil {
+		return err
+	}
+
+	r := bufio.NewReader(conn)
+	typ, err := r.ReadByte()
+	if err == io.EOF {
+		return skip("broker closed connection on MQTT 5 connect")
+	}
+	if err != nil {
+		return err
+	}
+	if typ&0xF0 != mqttConnAck {
+		return fmt.Errorf("expected CONNACK, got packet type %d", typ>>4)
+	}
+	if _, err := binary.ReadUvarint(r); err != nil {
+		return err
+	}
+	ack := make([]byte, 2)
+	if _, err := io.ReadFull(r, ack); err != nil {
+		return err
+	}
+
+	switch ack[1] {
+	case mqttSuccess:
+		conn.Write([]byte{0xE0, 0x00})
+		return nil
+	case mqttV3Unacceptable, mqttUnsupportedProtocol:
+		return skip("broker does not support MQTT 5")
+	default:
+		return fmt.Errorf("connect refused with reason code 0x%02x", ack[1])
+	}
+}
+
+func mqttDial(cfg Config) (net.Conn, error) {
+	u, err := url.Parse(cfg.MQTT.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &net.Dialer{Timeout: timeout(cfg)}
+	switch u.Scheme {
+	case "ssl", "tls", "tcps":
+		return tls.DialWithDialer(d, "tcp", u.Host, &tls.Config{})
+	case "tcp", "mqtt":
+		return d.Dial("tcp", u.Host)
+	default:
+		return nil, skip("raw connection over %s is not supported", u.Scheme)
+	}
+}
+
+func writeString(b *bytes.Buffer, s string) {
+	binary.Write(b, binary.BigEndian, uint16(len(s)))
+	b.WriteString(s)
+}
+
+// remainingLength encodes packet length as variable byte integer, which
+// matches unsigned varint encoding.
+func remainingLength(n int) []byte {
+	buf := make([]byte, binary.MaxVarintLen32)
+	return buf[:binary.PutUvarint(buf, uint64(n))]
+}
@@ -0,0 +1,32 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package conformance
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// Write writes the report to the writer in the given format. Supported
+// formats are text and json.
+func (r Report) Write(w io.Writer, format string) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	case "text", "":
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "PROTOCOL\tCHECK\tSTATUS\tTIME (s)\tREASON")
+		for _, res := range r.Results {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%.3f\t%s\n", res.Protocol, res.Check, res.Status, res.Duration, res.Reason)
+		}
+		fmt.Fprintf(tw, "\nPassed: %d, failed: %d, skipped: %d\n", r.Passed, r.Failed, r.Skipped)
+		return tw.Flush()
+	default:
+		return fmt.Errorf("unknown report format %s", format)
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package conformance
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+var wsChecks = []check{
+	{name: "handshake with key in query", run: wsHandshake(false)},
+	{name: "handshake with key in header", run: wsHandshake(true)},
+	{name: "reject invalid key", run: wsInvalidKey},
+	{name: "publish and subscribe", run: wsPubSub("")},
+	{name: "publish and subscribe on subtopic", run: wsPubSub("conformance")},
+}
+
+func wsDial(cfg Config, subtopic, key string, header bool) (*websocket.Conn, *http.Response, error) {
+	url := fmt.Sprintf("%s/channels/%s/messages", cfg.WS.URL, cfg.Thing.ChannelID)
+	if subtopic != "" {
+		url = fmt.Sprintf("%s/%s", url, subtopic)
+	}
+
+	h := http.Header{}
+	if header {
+		h.Set("Authorization", key)
+	} else {
+		url = fmt.Sprintf("%s?authorization=%s", url, key)
+	}
+
+	d := websocket.Dialer{HandshakeTimeout: timeout(cfg)}
+	return d.Dial(url, h)
+}
+
+func wsHandshake(header bool) func(Config) error {
+	return func(cfg Config) error {
+		conn, _, err := wsDial(cfg, "", cfg.Thing.Key, header)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+}
+
+func wsInvalidKey(cfg Config) error {
+	conn, res, err := wsDial(cfg, "", "invalid", false)
+	if err == nil {
+		conn.Close()
+		return errors.New("adapter accepted invalid key")
+	}
+	if res == nil {
+		return err
+	}
+	if res.StatusCode != http.StatusForbidden {
+		return fmt.Errorf("expected status %d, got %d", http.StatusForbidden, res.StatusCode)
+	}
+	return nil
+}
+
+func wsPubSub(subtopic string) func(Config) error {
+	return func(cfg Config) error {
+		sub, _, err := wsDial(cfg, subtopic, cfg.Thing.Key, false)
+		if err != nil {
+			return err
+		}
+		defer sub.Close()
+
+		pub, _, err := wsDial(cfg, subtopic, cfg.Thing.Key, false)
+		if err != nil {
+			return err
+		}
+		defer pub.Close()
+
+		p := payload("ws")
+		if err := pub.WriteMessage(websocket.TextMessage, p); err != nil {
+			return err
+		}
+
+		sub.SetReadDeadline(time.Now().Add(timeout(cfg)))
+		for {
+			_, msg, err := sub.ReadMessage()
+			if err != nil {
+				return fmt.Errorf("published message was not delivered: %s", err)
+			}
+			if bytes.Equal(msg, p) {
+				return nil
+			}
+		}
+	}
+}
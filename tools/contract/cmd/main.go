@@ -0,0 +1,125 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/mainflux/mainflux/tools/contract"
+	"github.com/spf13/cobra"
+)
+
+func main() {
+	var specFile, format string
+
+	var verifyCmd = &cobra.Command{
+		Use:   "verify <contract_file>",
+		Short: "Verify recorded contract",
+		Long:  `Verifies the interactions of the recorded contract against the service specification.`,
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			spec := loadSpec(specFile)
+
+			c, err := contract.Load(args[0])
+			if err != nil {
+				log.Fatalf("Failed to load contract - %s", err.Error())
+			}
+
+			report(contract.Verify(spec, c), format)
+		},
+	}
+
+	var target, addr, consumer, provider, output string
+
+	var proxyCmd = &cobra.Command{
+		Use:   "proxy",
+		Short: "Record contract of live traffic",
+		Long: `Proxies the requests to the running service and records the interactions.
+The interactions are verified against the service specification on interrupt.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			spec := loadSpec(specFile)
+
+			u, err := url.Parse(target)
+			if err != nil {
+				log.Fatalf("Invalid target URL - %s", err.Error())
+			}
+
+			rec := contract.NewRecorder(httputil.NewSingleHostReverseProxy(u))
+			go func() {
+				if err := http.ListenAndServe(addr, rec); err != nil {
+					log.Fatalf("Proxy terminated - %s", err.Error())
+				}
+			}()
+			log.Printf("Recording interactions with %s on %s", target, addr)
+
+			c := make(chan os.Signal, 1)
+			signal.Notify(c, syscall.SIGINT, syscall.SIGTERM)
+			<-c
+
+			ct := contract.Contract{
+				Consumer:     consumer,
+				Provider:     provider,
+				Interactions: rec.Interactions(),
+			}
+			if output != "" {
+				if err := ct.Save(output); err != nil {
+					log.Fatalf("Failed to save contract - %s", err.Error())
+				}
+			}
+
+			report(contract.Verify(spec, ct), format)
+		},
+	}
+
+	proxyCmd.Flags().StringVarP(&target, "target", "", "http://localhost:8180", "URL of the service")
+	proxyCmd.Flags().StringVarP(&addr, "addr", "a", ":8090", "Address the proxy listens on")
+	proxyCmd.Flags().StringVarP(&consumer, "consumer", "", "", "Name of the consumer")
+	proxyCmd.Flags().StringVarP(&provider, "provider", "", "", "Name of the service")
+	proxyCmd.Flags().StringVarP(&output, "output", "o", "", "File the recorded contract is saved to")
+
+	var rootCmd = &cobra.Command{
+		Use:   "contract",
+		Short: "contract is contract testing tool for Mainflux",
+		Long: `Tool for verifying interactions of the SDK, CLI and other consumers with Mainflux HTTP services against their specifications.
+Complete documentation is available at https://mainflux.readthedocs.io`,
+	}
+
+	rootCmd.PersistentFlags().StringVarP(&specFile, "spec", "s", "", "Swagger specification of the service")
+	rootCmd.PersistentFlags().StringVarP(&format, "format", "f", "text", "Output format: text|json")
+	rootCmd.MarkPersistentFlagRequired("spec")
+
+	rootCmd.AddCommand(verifyCmd, proxyCmd)
+
+	if err := rootCmd.Execute(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func loadSpec(path string) contract.Spec {
+	spec, err := contract.LoadSpec(path)
+	if err != nil {
+		log.Fatalf("Failed to load specification - %s", err.Error())
+	}
+
+	return spec
+}
+
+func report(rep contract.Report, format string) {
+	if err := rep.Write(os.Stdout, format); err != nil {
+		log.Fatalf("Failed to write report - %s", err.Error())
+	}
+
+	if !rep.Compliant() {
+		fmt.Fprintln(os.Stderr, "Contract is broken")
+		os.Exit(1)
+	}
+}
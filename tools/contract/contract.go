@@ -0,0 +1,156 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package contract contains the consumer-driven contract testing of the
+// Mainflux HTTP services. Interactions of the consumers, such as the SDK and
+// the CLI, with the services are recorded and verified against the Swagger
+// specifications of the services, so that the breaking changes of the
+// services are caught before the consumers fail.
+package contract
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sync"
+)
+
+// Request contains the request made by the consumer.
+type Request struct {
+	Method  string          `json:"method"`
+	Path    string          `json:"path"`
+	Headers []string        `json:"headers,omitempty"`
+	Body    json.RawMessage `json:"body,omitempty"`
+}
+
+// ProviderResponse contains the response of the provider to the request.
+type ProviderResponse struct {
+	Status int             `json:"status"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+// Interaction contains the request made by the consumer and the response of
+// the provider.
+type Interaction struct {
+	Request  Request          `json:"request"`
+	Response ProviderResponse `json:"response"`
+}
+
+// Contract contains the interactions of the consumer with the provider.
+type Contract struct {
+	Consumer     string        `json:"consumer"`
+	Provider     string        `json:"provider"`
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Load reads the contract from the file.
+func Load(path string) (Contract, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Contract{}, err
+	}
+
+	var c Contract
+	if err := json.Unmarshal(data, &c); err != nil {
+		return Contract{}, err
+	}
+
+	return c, nil
+}
+
+// Save writes the contract to the file.
+func (c Contract) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, data, os.FileMode(0644))
+}
+
+var _ http.Handler = (*Recorder)(nil)
+
+// Recorder is the HTTP handler which records the interactions served by
+// the wrapped handler of the provider.
+type Recorder struct {
+	next http.Handler
+
+	mu           sync.Mutex
+	interactions []Interaction
+}
+
+// NewRecorder returns the recorder of the interactions served by the
+// handler.
+func NewRecorder(next http.Handler) *Recorder {
+	return &Recorder{next: next}
+}
+
+func (rec *Recorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	r.Body.Close()
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	req := Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Body:   jsonBody(body),
+	}
+	for name := range r.Header {
+		req.Headers = append(req.Headers, name)
+	}
+
+	rw := &responseWriter{ResponseWriter: w, status: http.StatusOK}
+	rec.next.ServeHTTP(rw, r)
+
+	in := Interaction{
+		Request: req,
+		Response: ProviderResponse{
+			Status: rw.status,
+			Body:   jsonBody(rw.body.Bytes()),
+		},
+	}
+
+	rec.mu.Lock()
+	rec.interactions = append(rec.interactions, in)
+	rec.mu.Unlock()
+}
+
+// Interactions returns the recorded interactions.
+func (rec *Recorder) Interactions() []Interaction {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	return append([]Interaction{}, rec.interactions...)
+}
+
+// jsonBody returns the body if it contains JSON, since other bodies aren't
+// verified.
+func jsonBody(body []byte) json.RawMessage {
+	if !json.Valid(body) {
+		return nil
+	}
+
+	return json.RawMessage(body)
+}
+
+type responseWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (rw *responseWriter) WriteHeader(status int) {
+	rw.status = status
+	rw.ResponseWriter.WriteHeader(status)
+}
+
+func (rw *responseWriter) Write(data []byte) (int, error) {
+	rw.body.Write(data)
+	return rw.ResponseWriter.Write(data)
+}
@@ -0,0 +1,240 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package contract
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+const refPrefix = "#/"
+
+// Spec contains the operations of the service HTTP API described by its
+// Swagger 2.0 specification.
+type Spec struct {
+	Title      string      `json:"title"`
+	Operations []Operation `json:"operations"`
+}
+
+// Operation describes a single operation of the service HTTP API.
+type Operation struct {
+	Method    string           `json:"method"`
+	Path      string           `json:"path"`
+	Headers   []string         `json:"headers,omitempty"`
+	Required  []string         `json:"required,omitempty"`
+	Responses map[int]Response `json:"responses"`
+}
+
+// Response describes a documented response of the operation.
+type Response struct {
+	Required []string `json:"required,omitempty"`
+}
+
+type swagger struct {
+	Info struct {
+		Title string `yaml:"title"`
+	} `yaml:"info"`
+	BasePath    string                     `yaml:"basePath"`
+	Paths       map[string]swaggerPath     `yaml:"paths"`
+	Parameters  map[string]swaggerParam    `yaml:"parameters"`
+	Responses   map[string]swaggerResponse `yaml:"responses"`
+	Definitions map[string]swaggerSchema   `yaml:"definitions"`
+}
+
+type swaggerPath struct {
+	Parameters []swaggerParam `yaml:"parameters"`
+	Get        *swaggerOp     `yaml:"get"`
+	Put        *swaggerOp     `yaml:"put"`
+	Post       *swaggerOp     `yaml:"post"`
+	Patch      *swaggerOp     `yaml:"patch"`
+	Delete     *swaggerOp     `yaml:"delete"`
+	Head       *swaggerOp     `yaml:"head"`
+}
+
+type swaggerOp struct {
+	Parameters []swaggerParam             `yaml:"parameters"`
+	Responses  map[string]swaggerResponse `yaml:"responses"`
+}
+
+type swaggerParam struct {
+	Ref      string         `yaml:"$ref"`
+	Name     string         `yaml:"name"`
+	In       string         `yaml:"in"`
+	Required bool           `yaml:"required"`
+	Schema   *swaggerSchema `yaml:"schema"`
+}
+
+type swaggerResponse struct {
+	Ref    string         `yaml:"$ref"`
+	Schema *swaggerSchema `yaml:"schema"`
+}
+
+type swaggerSchema struct {
+	Ref      string   `yaml:"$ref"`
+	Required []string `yaml:"required"`
+}
+
+// LoadSpec reads the Swagger 2.0 specification from the file.
+func LoadSpec(path string) (Spec, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Spec{}, err
+	}
+
+	return ParseSpec(data)
+}
+
+// ParseSpec parses the Swagger 2.0 specification. References to the shared
+// parameters, responses and definitions are resolved.
+func ParseSpec(data []byte) (Spec, error) {
+	var sw swagger
+	if err := yaml.Unmarshal(data, &sw); err != nil {
+		return Spec{}, err
+	}
+
+	spec := Spec{Title: sw.Info.Title}
+	for path, p := range sw.Paths {
+		methods := map[string]*swaggerOp{
+			http.MethodGet:    p.Get,
+			http.MethodPut:    p.Put,
+			http.MethodPost:   p.Post,
+			http.MethodPatch:  p.Patch,
+			http.MethodDelete: p.Delete,
+			http.MethodHead:   p.Head,
+		}
+		for method, op := range methods {
+			if op == nil {
+				continue
+			}
+
+			o, err := sw.operation(method, sw.BasePath+path, append(p.Parameters, op.Parameters...), op.Responses)
+			if err != nil {
+				return Spec{}, err
+			}
+			spec.Operations = append(spec.Operations, o)
+		}
+	}
+
+	sort.Slice(spec.Operations, func(i, j int) bool {
+		if spec.Operations[i].Path == spec.Operations[j].Path {
+			return spec.Operations[i].Method < spec.Operations[j].Method
+		}
+		return spec.Operations[i].Path < spec.Operations[j].Path
+	})
+
+	return spec, nil
+}
+
+func (sw swagger) operation(method, path string, params []swaggerParam, responses map[string]swaggerResponse) (Operation, error) {
+	op := Operation{
+		Method:    method,
+		Path:      path,
+		Responses: make(map[int]Response),
+	}
+
+	for _, p := range params {
+		if p.Ref != "" {
+			name := strings.TrimPrefix(p.Ref, refPrefix+"parameters/")
+			ref, ok := sw.Parameters[name]
+			if !ok {
+				return Operation{}, fmt.Errorf("unknown parameter %s", p.Ref)
+			}
+			p = ref
+		}
+
+		switch {
+		case p.In == "header" && p.Required:
+			op.Headers = append(op.Headers, p.Name)
+		case p.In == "body" && p.Schema != nil:
+			req, err := sw.required(*p.Schema)
+			if err != nil {
+				return Operation{}, err
+			}
+			op.Required = req
+		}
+	}
+
+	for code, res := range responses {
+		// The default response doesn't document a particular status.
+		status, err := strconv.Atoi(code)
+		if err != nil {
+			continue
+		}
+
+		if res.Ref != "" {
+			name := strings.TrimPrefix(res.Ref, refPrefix+"responses/")
+			ref, ok := sw.Responses[name]
+			if !ok {
+				return Operation{}, fmt.Errorf("unknown response %s", res.Ref)
+			}
+			res = ref
+		}
+
+		var r Response
+		if res.Schema != nil {
+			req, err := sw.required(*res.Schema)
+			if err != nil {
+				return Operation{}, err
+			}
+			r.Required = req
+		}
+		op.Responses[status] = r
+	}
+
+	return op, nil
+}
+
+func (sw swagger) required(schema swaggerSchema) ([]string, error) {
+	if schema.Ref == "" {
+		return schema.Required, nil
+	}
+
+	name := strings.TrimPrefix(schema.Ref, refPrefix+"definitions/")
+	def, ok := sw.Definitions[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown definition %s", schema.Ref)
+	}
+
+	return def.Required, nil
+}
+
+// Operation returns the operation which serves the request with the given
+// method and path.
+func (s Spec) Operation(method, path string) (Operation, bool) {
+	for _, op := range s.Operations {
+		if op.Method == method && op.matches(path) {
+			return op, true
+		}
+	}
+
+	return Operation{}, false
+}
+
+func (op Operation) matches(path string) bool {
+	tmpl := strings.Split(strings.Trim(op.Path, "/"), "/")
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(tmpl) != len(parts) {
+		return false
+	}
+
+	for i, t := range tmpl {
+		if strings.HasPrefix(t, "{") && strings.HasSuffix(t, "}") {
+			if parts[i] == "" {
+				return false
+			}
+			continue
+		}
+		if t != parts[i] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,157 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package contract
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"text/tabwriter"
+)
+
+// Violation describes the interaction which breaks the specification.
+type Violation struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Status int    `json:"status"`
+	Reason string `json:"reason"`
+}
+
+// Report contains the result of the contract verification.
+type Report struct {
+	Provider     string      `json:"provider"`
+	Interactions int         `json:"interactions"`
+	Violations   []Violation `json:"violations"`
+	Uncovered    []string    `json:"uncovered,omitempty"`
+}
+
+// Verify verifies the interactions against the specification of the
+// provider. Interaction breaks the specification if the operation or the
+// response status isn't documented, or if the request or the response lacks
+// a required header or field. Operations which none of the interactions
+// covered are reported as well, but aren't considered violations.
+func Verify(spec Spec, c Contract) Report {
+	rep := Report{
+		Provider:     c.Provider,
+		Interactions: len(c.Interactions),
+		Violations:   []Violation{},
+	}
+
+	covered := make(map[string]bool)
+	for _, in := range c.Interactions {
+		req, res := in.Request, in.Response
+		op, ok := spec.Operation(req.Method, req.Path)
+		if !ok {
+			rep.Violations = append(rep.Violations, violation(in, "undocumented operation"))
+			continue
+		}
+		covered[op.Method+" "+op.Path] = true
+
+		for _, reason := range verifyRequest(op, req) {
+			rep.Violations = append(rep.Violations, violation(in, reason))
+		}
+
+		r, ok := op.Responses[res.Status]
+		if !ok {
+			rep.Violations = append(rep.Violations, violation(in, "undocumented response status"))
+			continue
+		}
+		for _, field := range missing(res.Body, r.Required) {
+			rep.Violations = append(rep.Violations, violation(in, fmt.Sprintf("response lacks required field %s", field)))
+		}
+	}
+
+	for _, op := range spec.Operations {
+		if !covered[op.Method+" "+op.Path] {
+			rep.Uncovered = append(rep.Uncovered, op.Method+" "+op.Path)
+		}
+	}
+
+	return rep
+}
+
+func verifyRequest(op Operation, req Request) []string {
+	var reasons []string
+
+	headers := make(map[string]bool)
+	for _, h := range req.Headers {
+		headers[http.CanonicalHeaderKey(h)] = true
+	}
+	for _, h := range op.Headers {
+		if !headers[http.CanonicalHeaderKey(h)] {
+			reasons = append(reasons, fmt.Sprintf("request lacks required header %s", h))
+		}
+	}
+
+	for _, field := range missing(req.Body, op.Required) {
+		reasons = append(reasons, fmt.Sprintf("request lacks required field %s", field))
+	}
+
+	return reasons
+}
+
+// missing returns the required fields the JSON object lacks. Bodies which
+// aren't JSON objects aren't verified.
+func missing(body json.RawMessage, required []string) []string {
+	if len(required) == 0 || len(body) == 0 {
+		return nil
+	}
+
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(body, &obj); err != nil {
+		return nil
+	}
+
+	var fields []string
+	for _, f := range required {
+		if _, ok := obj[f]; !ok {
+			fields = append(fields, f)
+		}
+	}
+
+	return fields
+}
+
+func violation(in Interaction, reason string) Violation {
+	return Violation{
+		Method: in.Request.Method,
+		Path:   in.Request.Path,
+		Status: in.Response.Status,
+		Reason: reason,
+	}
+}
+
+// Compliant returns true if none of the interactions broke the
+// specification.
+func (r Report) Compliant() bool {
+	return len(r.Violations) == 0
+}
+
+// Write writes the report to the writer in the given format. Supported
+// formats are text and json.
+func (r Report) Write(w io.Writer, format string) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	case "text", "":
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "METHOD\tPATH\tSTATUS\tREASON")
+		for _, v := range r.Violations {
+			fmt.Fprintf(tw, "%s\t%s\t%d\t%s\n", v.Method, v.Path, v.Status, v.Reason)
+		}
+		if len(r.Uncovered) > 0 {
+			fmt.Fprintln(tw, "\nNOT COVERED")
+			for _, op := range r.Uncovered {
+				fmt.Fprintln(tw, op)
+			}
+		}
+		fmt.Fprintf(tw, "\nInteractions: %d, violations: %d, uncovered operations: %d\n", r.Interactions, len(r.Violations), len(r.Uncovered))
+		return tw.Flush()
+	default:
+		return fmt.Errorf("unknown report format %s", format)
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package tracing
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/mainflux/mainflux/logger"
+	opentracing "github.com/opentracing/opentracing-go"
+	jconfig "github.com/uber/jaeger-client-go/config"
+)
+
+// Init returns the tracer reporting the spans of the service to the Jaeger
+// agent at the url, or the no-op tracer if the url is empty. The tracer
+// generates 128-bit trace IDs and propagates the span context in the W3C
+// Trace Context format too, so that the traces are joined with the ones of
+// the OpenTelemetry instrumented services.
+func Init(svcName, url string, logger logger.Logger) (opentracing.Tracer, io.Closer) {
+	if url == "" {
+		return opentracing.NoopTracer{}, ioutil.NopCloser(nil)
+	}
+
+	tracer, closer, err := jconfig.Configuration{
+		ServiceName: svcName,
+		Sampler: &jconfig.SamplerConfig{
+			Type:  "const",
+			Param: 1,
+		},
+		Reporter: &jconfig.ReporterConfig{
+			LocalAgentHostPort: url,
+			LogSpans:           true,
+		},
+	}.NewTracer(
+		jconfig.Gen128Bit(true),
+		jconfig.Injector(TraceContext, TraceContextPropagator{}),
+		jconfig.Extractor(TraceContext, TraceContextPropagator{}),
+	)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to init Jaeger client: %s", err))
+		os.Exit(1)
+	}
+
+	return tracer, closer
+}
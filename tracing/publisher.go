@@ -0,0 +1,54 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package tracing
+
+import (
+	"context"
+
+	"github.com/mainflux/mainflux"
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+)
+
+var _ mainflux.MessagePublisher = (*publisher)(nil)
+
+type publisher struct {
+	pub       mainflux.MessagePublisher
+	tracer    opentracing.Tracer
+	operation string
+}
+
+// NewPublisher returns the publisher which traces the published messages.
+// The span is the child of the span carried by the context, if any, such as
+// the span of the HTTP request the message is received with.
+func NewPublisher(pub mainflux.MessagePublisher, tracer opentracing.Tracer, operation string) mainflux.MessagePublisher {
+	return publisher{
+		pub:       pub,
+		tracer:    tracer,
+		operation: operation,
+	}
+}
+
+func (p publisher) Publish(ctx context.Context, token string, msg mainflux.RawMessage) error {
+	var opts []opentracing.StartSpanOption
+	if parent := opentracing.SpanFromContext(ctx); parent != nil {
+		opts = append(opts, opentracing.ChildOf(parent.Context()))
+	}
+	opts = append(opts, ext.SpanKindProducer)
+
+	span := p.tracer.StartSpan(p.operation, opts...)
+	defer span.Finish()
+
+	span.SetTag("channel", msg.GetChannel())
+	span.SetTag("subtopic", msg.GetSubtopic())
+	span.SetTag("protocol", msg.GetProtocol())
+
+	err := p.pub.Publish(opentracing.ContextWithSpan(ctx, span), token, msg)
+	if err != nil {
+		ext.Error.Set(span, true)
+		span.LogKV("error", err.Error())
+	}
+
+	return err
+}
@@ -0,0 +1,112 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package tracing
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/uber/jaeger-client-go"
+)
+
+// TraceParent is the header carrying the span context in the W3C Trace
+// Context format.
+const TraceParent = "traceparent"
+
+const (
+	traceContextVersion = "00"
+	sampledFlag         = 0x01
+)
+
+// TraceContext is the W3C Trace Context propagation format, which is the
+// default propagation format of OpenTelemetry. Tracers returned by Init
+// support it besides the Jaeger format.
+var TraceContext = traceContextFormat{}
+
+var (
+	_ jaeger.Injector  = (*TraceContextPropagator)(nil)
+	_ jaeger.Extractor = (*TraceContextPropagator)(nil)
+)
+
+type traceContextFormat struct{}
+
+// TraceContextPropagator propagates the Jaeger span context in the W3C Trace
+// Context traceparent header of the text map carrier. The trace state and the
+// baggage aren't propagated.
+type TraceContextPropagator struct{}
+
+// Inject injects the span context to the carrier.
+func (TraceContextPropagator) Inject(sc jaeger.SpanContext, carrier interface{}) error {
+	w, ok := carrier.(opentracing.TextMapWriter)
+	if !ok {
+		return opentracing.ErrInvalidCarrier
+	}
+
+	var flags byte
+	if sc.IsSampled() {
+		flags = sampledFlag
+	}
+	traceID := sc.TraceID()
+	w.Set(TraceParent, fmt.Sprintf("%s-%016x%016x-%016x-%02x", traceContextVersion, traceID.High, traceID.Low, uint64(sc.SpanID()), flags))
+
+	return nil
+}
+
+// Extract extracts the span context from the carrier. The header is looked up
+// regardless of its case, since some carriers canonicalize the header keys.
+func (TraceContextPropagator) Extract(carrier interface{}) (jaeger.SpanContext, error) {
+	r, ok := carrier.(opentracing.TextMapReader)
+	if !ok {
+		return jaeger.SpanContext{}, opentracing.ErrInvalidCarrier
+	}
+
+	var header string
+	r.ForeachKey(func(key, val string) error {
+		if strings.EqualFold(key, TraceParent) {
+			header = val
+		}
+		return nil
+	})
+	if header == "" {
+		return jaeger.SpanContext{}, opentracing.ErrSpanContextNotFound
+	}
+
+	return parseTraceParent(header)
+}
+
+// parseTraceParent parses the traceparent header value, which consists of
+// the version, the 128-bit trace ID, the 64-bit parent span ID and the flags.
+func parseTraceParent(header string) (jaeger.SpanContext, error) {
+	parts := strings.Split(header, "-")
+	if len(parts) < 4 || parts[0] != traceContextVersion || len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return jaeger.SpanContext{}, opentracing.ErrSpanContextCorrupted
+	}
+
+	high, err := strconv.ParseUint(parts[1][:16], 16, 64)
+	if err != nil {
+		return jaeger.SpanContext{}, opentracing.ErrSpanContextCorrupted
+	}
+	low, err := strconv.ParseUint(parts[1][16:], 16, 64)
+	if err != nil {
+		return jaeger.SpanContext{}, opentracing.ErrSpanContextCorrupted
+	}
+	spanID, err := strconv.ParseUint(parts[2], 16, 64)
+	if err != nil {
+		return jaeger.SpanContext{}, opentracing.ErrSpanContextCorrupted
+	}
+	flags, err := strconv.ParseUint(parts[3], 16, 8)
+	if err != nil {
+		return jaeger.SpanContext{}, opentracing.ErrSpanContextCorrupted
+	}
+
+	// All zero IDs are invalid.
+	traceID := jaeger.TraceID{High: high, Low: low}
+	if !traceID.IsValid() || spanID == 0 {
+		return jaeger.SpanContext{}, opentracing.ErrSpanContextCorrupted
+	}
+
+	return jaeger.NewSpanContext(traceID, jaeger.SpanID(spanID), 0, flags&sampledFlag != 0, nil), nil
+}
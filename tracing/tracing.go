@@ -0,0 +1,117 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package tracing propagates the trace context through the NATS message
+// headers, so that the message can be traced from the adapter it's
+// published to, through the normalizer, to the writer saving it. Services
+// consuming the messages start their spans following the span of the
+// publisher, since the messages are processed asynchronously.
+//
+// The span context is propagated both in the format of the tracer and in the
+// W3C Trace Context format, which is the default format of OpenTelemetry, so
+// the messages published by the OpenTelemetry instrumented services are traced
+// too. NATS servers older than 2.2 don't support headers, in which case the
+// messages are published without the trace context.
+//
+// The services themselves still use the OpenTracing API with the Jaeger
+// tracer. Adopting the OpenTelemetry SDK and exporter, and propagating the
+// W3C Trace Context over HTTP and gRPC, is not done yet.
+package tracing
+
+import (
+	"context"
+
+	"github.com/mainflux/mainflux"
+	"github.com/nats-io/nats.go"
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+)
+
+const component = "nats"
+
+var (
+	_ opentracing.TextMapWriter = (*headerCarrier)(nil)
+	_ opentracing.TextMapReader = (*headerCarrier)(nil)
+)
+
+// headerCarrier carries the trace context in the NATS message headers.
+type headerCarrier nats.Header
+
+func (hc headerCarrier) Set(key, val string) {
+	nats.Header(hc).Set(key, val)
+}
+
+func (hc headerCarrier) ForeachKey(handler func(key, val string) error) error {
+	for key, vals := range hc {
+		for _, val := range vals {
+			if err := handler(key, val); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Publish publishes the data to the subject, injecting the context of the
+// span carried by the context to the message headers.
+func Publish(ctx context.Context, nc *nats.Conn, subject string, data []byte) error {
+	msg := nats.NewMsg(subject)
+	msg.Data = data
+
+	if span := opentracing.SpanFromContext(ctx); span != nil && nc.HeadersSupported() {
+		ext.MessageBusDestination.Set(span, subject)
+		// Injection failure leaves the message untraced, but published.
+		span.Tracer().Inject(span.Context(), opentracing.TextMap, headerCarrier(msg.Header))
+		span.Tracer().Inject(span.Context(), TraceContext, headerCarrier(msg.Header))
+	}
+
+	return nc.PublishMsg(msg)
+}
+
+// StartSpan starts the span of the consumer of the received message, which
+// follows the span of its publisher, if any. The W3C Trace Context is
+// preferred to the format of the tracer.
+func StartSpan(tracer opentracing.Tracer, operation string, msg *nats.Msg) opentracing.Span {
+	opts := []opentracing.StartSpanOption{
+		ext.SpanKindConsumer,
+		opentracing.Tag{Key: string(ext.Component), Value: component},
+		opentracing.Tag{Key: string(ext.MessageBusDestination), Value: msg.Subject},
+	}
+
+	if msg.Header != nil {
+		if sc, ok := extract(tracer, headerCarrier(msg.Header)); ok {
+			opts = append(opts, opentracing.FollowsFrom(sc))
+		}
+	}
+
+	return tracer.StartSpan(operation, opts...)
+}
+
+// StartRawSpan starts the span of the consumer of the received raw message the
+// same way as StartSpan. Publishers which can't set the NATS headers, such as
+// the MQTT adapter, pass the traceparent in the raw message metadata instead.
+func StartRawSpan(tracer opentracing.Tracer, operation string, msg *nats.Msg, raw mainflux.RawMessage) opentracing.Span {
+	tp, ok := raw.GetMetadata()[TraceParent]
+	if !ok {
+		return StartSpan(tracer, operation, msg)
+	}
+
+	traced := nats.NewMsg(msg.Subject)
+	for key, vals := range msg.Header {
+		traced.Header[key] = vals
+	}
+	traced.Header.Set(TraceParent, tp)
+
+	return StartSpan(tracer, operation, traced)
+}
+
+func extract(tracer opentracing.Tracer, carrier headerCarrier) (opentracing.SpanContext, bool) {
+	for _, format := range []interface{}{TraceContext, opentracing.TextMap} {
+		if sc, err := tracer.Extract(format, carrier); err == nil {
+			return sc, true
+		}
+	}
+
+	return nil, false
+}
@@ -0,0 +1,242 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/tracing"
+	"github.com/nats-io/nats.go"
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/mocktracer"
+	"github.com/stretchr/testify/assert"
+	"github.com/uber/jaeger-client-go"
+)
+
+var errPublish = errors.New("failed to publish")
+
+type mockPublisher struct {
+	err  error
+	span opentracing.Span
+}
+
+func (pub *mockPublisher) Publish(ctx context.Context, token string, msg mainflux.RawMessage) error {
+	pub.span = opentracing.SpanFromContext(ctx)
+	return pub.err
+}
+
+func TestPublisher(t *testing.T) {
+	msg := mainflux.RawMessage{Channel: "1", Subtopic: "sub", Protocol: "http"}
+
+	cases := []struct {
+		desc   string
+		parent bool
+		err    error
+	}{
+		{
+			desc:   "publish message with parent span",
+			parent: true,
+		},
+		{
+			desc: "publish message without parent span",
+		},
+		{
+			desc: "publish message with error",
+			err:  errPublish,
+		},
+	}
+
+	for _, tc := range cases {
+		tracer := mocktracer.New()
+		pub := &mockPublisher{err: tc.err}
+		svc := tracing.NewPublisher(pub, tracer, "publish")
+
+		ctx := context.Background()
+		var parent *mocktracer.MockSpan
+		if tc.parent {
+			parent = tracer.StartSpan("request").(*mocktracer.MockSpan)
+			ctx = opentracing.ContextWithSpan(ctx, parent)
+		}
+
+		err := svc.Publish(ctx, "token", msg)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+
+		spans := tracer.FinishedSpans()
+		assert.Len(t, spans, 1, fmt.Sprintf("%s: expected single finished span\n", tc.desc))
+		span := spans[0]
+		assert.Equal(t, span, pub.span, fmt.Sprintf("%s: expected span to be passed to the publisher\n", tc.desc))
+		assert.Equal(t, msg.Channel, span.Tag("channel"), fmt.Sprintf("%s: expected channel tag\n", tc.desc))
+		assert.Equal(t, tc.err != nil, span.Tag("error") == true, fmt.Sprintf("%s: unexpected error tag\n", tc.desc))
+		if parent != nil {
+			assert.Equal(t, parent.SpanContext.SpanID, span.ParentID, fmt.Sprintf("%s: expected span to be child of the request span\n", tc.desc))
+		}
+	}
+}
+
+func TestStartSpan(t *testing.T) {
+	tracer := mocktracer.New()
+	producer := tracer.StartSpan("publish").(*mocktracer.MockSpan)
+
+	traced := nats.NewMsg("channels.1")
+	carrier := opentracing.TextMapCarrier{}
+	err := tracer.Inject(producer.Context(), opentracing.TextMap, carrier)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	for key, val := range carrier {
+		traced.Header.Set(key, val)
+	}
+
+	cases := []struct {
+		desc     string
+		msg      *nats.Msg
+		parentID int
+	}{
+		{
+			desc:     "start span of traced message",
+			msg:      traced,
+			parentID: producer.SpanContext.SpanID,
+		},
+		{
+			desc: "start span of untraced message",
+			msg:  &nats.Msg{Subject: "channels.1"},
+		},
+	}
+
+	for _, tc := range cases {
+		span := tracing.StartSpan(tracer, "save", tc.msg).(*mocktracer.MockSpan)
+		span.Finish()
+		assert.Equal(t, tc.parentID, span.ParentID, fmt.Sprintf("%s: expected parent %d got %d\n", tc.desc, tc.parentID, span.ParentID))
+		assert.Equal(t, tc.msg.Subject, span.Tag("message_bus.destination"), fmt.Sprintf("%s: expected destination tag\n", tc.desc))
+	}
+}
+
+func newJaegerTracer() (opentracing.Tracer, io.Closer) {
+	return jaeger.NewTracer("test",
+		jaeger.NewConstSampler(true),
+		jaeger.NewInMemoryReporter(),
+		jaeger.TracerOptions.Gen128Bit(true),
+		jaeger.TracerOptions.Injector(tracing.TraceContext, tracing.TraceContextPropagator{}),
+		jaeger.TracerOptions.Extractor(tracing.TraceContext, tracing.TraceContextPropagator{}),
+	)
+}
+
+func TestTraceContext(t *testing.T) {
+	tracer, closer := newJaegerTracer()
+	defer closer.Close()
+
+	producer := tracer.StartSpan("publish")
+	defer producer.Finish()
+	psc := producer.Context().(jaeger.SpanContext)
+
+	carrier := opentracing.TextMapCarrier{}
+	err := tracer.Inject(producer.Context(), tracing.TraceContext, carrier)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	expected := fmt.Sprintf("00-%032s-%016x-01", psc.TraceID(), uint64(psc.SpanID()))
+	assert.Equal(t, expected, carrier[tracing.TraceParent], fmt.Sprintf("expected traceparent %s got %s\n", expected, carrier[tracing.TraceParent]))
+
+	cases := []struct {
+		desc    string
+		header  string
+		traceID string
+		spanID  uint64
+		sampled bool
+		err     error
+	}{
+		{
+			desc:    "extract injected span context",
+			header:  carrier[tracing.TraceParent],
+			traceID: psc.TraceID().String(),
+			spanID:  uint64(psc.SpanID()),
+			sampled: true,
+		},
+		{
+			desc:    "extract span context of OpenTelemetry span",
+			header:  "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00",
+			traceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+			spanID:  0x00f067aa0ba902b7,
+			sampled: false,
+		},
+		{
+			desc:   "extract span context of unsupported version",
+			header: "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			err:    opentracing.ErrSpanContextCorrupted,
+		},
+		{
+			desc:   "extract span context with invalid trace ID",
+			header: "00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+			err:    opentracing.ErrSpanContextCorrupted,
+		},
+		{
+			desc:   "extract span context with malformed span ID",
+			header: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902bx-01",
+			err:    opentracing.ErrSpanContextCorrupted,
+		},
+		{
+			desc:   "extract missing span context",
+			header: "",
+			err:    opentracing.ErrSpanContextNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		c := opentracing.TextMapCarrier{}
+		if tc.header != "" {
+			c["Traceparent"] = tc.header
+		}
+
+		sc, err := tracer.Extract(tracing.TraceContext, c)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err != nil {
+			continue
+		}
+
+		jsc := sc.(jaeger.SpanContext)
+		assert.Equal(t, tc.traceID, fmt.Sprintf("%032s", jsc.TraceID()), fmt.Sprintf("%s: unexpected trace ID %s\n", tc.desc, jsc.TraceID()))
+		assert.Equal(t, tc.spanID, uint64(jsc.SpanID()), fmt.Sprintf("%s: unexpected span ID %s\n", tc.desc, jsc.SpanID()))
+		assert.Equal(t, tc.sampled, jsc.IsSampled(), fmt.Sprintf("%s: unexpected sampling flag\n", tc.desc))
+	}
+}
+
+func TestStartRawSpan(t *testing.T) {
+	tracer, closer := newJaegerTracer()
+	defer closer.Close()
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	raw := mainflux.RawMessage{
+		Channel:  "1",
+		Protocol: "mqtt",
+		Metadata: map[string]string{tracing.TraceParent: "00-" + traceID + "-00f067aa0ba902b7-01"},
+	}
+
+	cases := []struct {
+		desc    string
+		raw     mainflux.RawMessage
+		traceID string
+	}{
+		{
+			desc:    "start span of message traced in metadata",
+			raw:     raw,
+			traceID: traceID,
+		},
+		{
+			desc: "start span of untraced message",
+			raw:  mainflux.RawMessage{Channel: "1"},
+		},
+	}
+
+	for _, tc := range cases {
+		span := tracing.StartRawSpan(tracer, "normalize", nats.NewMsg("channels.1"), tc.raw)
+		span.Finish()
+		sc := span.Context().(jaeger.SpanContext)
+		if tc.traceID == "" {
+			assert.NotEqual(t, traceID, fmt.Sprintf("%032s", sc.TraceID()), fmt.Sprintf("%s: expected new trace\n", tc.desc))
+			continue
+		}
+		assert.Equal(t, tc.traceID, fmt.Sprintf("%032s", sc.TraceID()), fmt.Sprintf("%s: expected trace %s got %s\n", tc.desc, tc.traceID, sc.TraceID()))
+	}
+}
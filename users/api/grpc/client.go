@@ -20,12 +20,14 @@ var _ mainflux.UsersServiceClient = (*grpcClient)(nil)
 
 type grpcClient struct {
 	identify endpoint.Endpoint
+	groups   endpoint.Endpoint
+	disabled endpoint.Endpoint
 	timeout  time.Duration
 }
 
 // NewClient returns new gRPC client instance.
 func NewClient(tracer opentracing.Tracer, conn *grpc.ClientConn, timeout time.Duration) mainflux.UsersServiceClient {
-	endpoint := kitot.TraceClient(tracer, "identify")(kitgrpc.NewClient(
+	identify := kitot.TraceClient(tracer, "identify")(kitgrpc.NewClient(
 		conn,
 		"mainflux.UsersService",
 		"Identify",
@@ -34,8 +36,28 @@ func NewClient(tracer opentracing.Tracer, conn *grpc.ClientConn, timeout time.Du
 		mainflux.UserID{},
 	).Endpoint())
 
+	groups := kitot.TraceClient(tracer, "groups")(kitgrpc.NewClient(
+		conn,
+		"mainflux.UsersService",
+		"Groups",
+		encodeIdentifyRequest,
+		decodeGroupsResponse,
+		mainflux.GroupIDs{},
+	).Endpoint())
+
+	disabled := kitot.TraceClient(tracer, "disabled")(kitgrpc.NewClient(
+		conn,
+		"mainflux.UsersService",
+		"Disabled",
+		encodeDisabledRequest,
+		decodeDisabledResponse,
+		mainflux.UserStatus{},
+	).Endpoint())
+
 	return &grpcClient{
-		identify: endpoint,
+		identify: identify,
+		groups:   groups,
+		disabled: disabled,
 		timeout:  timeout,
 	}
 }
@@ -50,7 +72,33 @@ func (client grpcClient) Identify(ctx context.Context, token *mainflux.Token, _
 	}
 
 	ir := res.(identityRes)
-	return &mainflux.UserID{Value: ir.id}, ir.err
+	return &mainflux.UserID{Value: ir.id, Role: ir.role}, ir.err
+}
+
+func (client grpcClient) Groups(ctx context.Context, token *mainflux.Token, _ ...grpc.CallOption) (*mainflux.GroupIDs, error) {
+	ctx, close := context.WithTimeout(ctx, client.timeout)
+	defer close()
+
+	res, err := client.groups(ctx, identityReq{token.GetValue()})
+	if err != nil {
+		return nil, err
+	}
+
+	gr := res.(groupsRes)
+	return &mainflux.GroupIDs{Value: gr.ids}, gr.err
+}
+
+func (client grpcClient) Disabled(ctx context.Context, id *mainflux.UserID, _ ...grpc.CallOption) (*mainflux.UserStatus, error) {
+	ctx, close := context.WithTimeout(ctx, client.timeout)
+	defer close()
+
+	res, err := client.disabled(ctx, userReq{id.GetValue()})
+	if err != nil {
+		return nil, err
+	}
+
+	dr := res.(disabledRes)
+	return &mainflux.UserStatus{Disabled: dr.disabled}, dr.err
 }
 
 func encodeIdentifyRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
@@ -60,5 +108,20 @@ func encodeIdentifyRequest(_ context.Context, grpcReq interface{}) (interface{},
 
 func decodeIdentifyResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
 	res := grpcRes.(*mainflux.UserID)
-	return identityRes{res.GetValue(), nil}, nil
+	return identityRes{res.GetValue(), res.GetRole(), nil}, nil
+}
+
+func decodeGroupsResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
+	res := grpcRes.(*mainflux.GroupIDs)
+	return groupsRes{res.GetValue(), nil}, nil
+}
+
+func encodeDisabledRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(userReq)
+	return &mainflux.UserID{Value: req.email}, nil
+}
+
+func decodeDisabledResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
+	res := grpcRes.(*mainflux.UserStatus)
+	return disabledRes{res.GetDisabled(), nil}, nil
 }
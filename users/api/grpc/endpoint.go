@@ -16,10 +16,45 @@ func identifyEndpoint(svc users.Service) endpoint.Endpoint {
 			return nil, err
 		}
 
-		id, err := svc.Identify(req.token)
+		id, err := svc.Authorize(ctx, req.token)
 		if err != nil {
 			return identityRes{}, err
 		}
-		return identityRes{id, nil}, nil
+		return identityRes{id.ID, id.Role, nil}, nil
+	}
+}
+
+func groupsEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(identityReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		groups, err := svc.ListGroups(ctx, req.token)
+		if err != nil {
+			return groupsRes{}, err
+		}
+
+		ids := make([]string, len(groups))
+		for i, g := range groups {
+			ids[i] = g.ID
+		}
+		return groupsRes{ids, nil}, nil
+	}
+}
+
+func disabledEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(userReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		disabled, err := svc.Disabled(ctx, req.email)
+		if err != nil {
+			return disabledRes{}, err
+		}
+		return disabledRes{disabled, nil}, nil
 	}
 }
@@ -28,15 +28,26 @@ var (
 		Email:    "john.doe@email.com",
 		Password: "pass",
 	}
+	admin = users.User{
+		Email:    "admin@email.com",
+		Password: "pass",
+	}
 	svc users.Service
 )
 
 func newService() users.Service {
 	repo := mocks.NewUserRepository()
+	groups := mocks.NewGroupRepository()
+	keys := mocks.NewKeyRepository()
+	roles := mocks.NewRoleRepository()
+	orgs := mocks.NewOrgRepository()
+	totps := mocks.NewTOTPRepository()
 	hasher := mocks.NewHasher()
 	idp := mocks.NewIdentityProvider()
+	uuidp := mocks.NewIDProvider()
+	otpp := mocks.NewOTPProvider()
 
-	return users.New(repo, hasher, idp)
+	return users.New(repo, groups, keys, roles, orgs, totps, []string{admin.Email}, hasher, idp, uuidp, otpp, users.PasswordPolicy{}, nil, users.LockoutPolicy{}, nil)
 }
 
 func startGRPCServer(svc users.Service, port int) {
@@ -48,6 +59,7 @@ func startGRPCServer(svc users.Service, port int) {
 
 func TestIdentify(t *testing.T) {
 	svc.Register(context.Background(), user)
+	svc.Register(context.Background(), admin)
 
 	usersAddr := fmt.Sprintf("localhost:%d", port)
 	conn, _ := grpc.Dial(usersAddr, grpc.WithInsecure())
@@ -56,15 +68,76 @@ func TestIdentify(t *testing.T) {
 	cases := map[string]struct {
 		token string
 		id    string
+		role  string
 		err   error
 	}{
-		"identify user with valid token":   {user.Email, user.Email, nil},
-		"identify user that doesn't exist": {"", "", status.Error(codes.InvalidArgument, "received invalid token request")},
+		"identify user with valid token":   {user.Email, user.Email, users.DefaultRole, nil},
+		"identify admin with valid token":  {admin.Email, admin.Email, users.RoleAdmin, nil},
+		"identify user that doesn't exist": {"", "", "", status.Error(codes.InvalidArgument, "received invalid token request")},
 	}
 
 	for desc, tc := range cases {
 		id, err := client.Identify(context.Background(), &mainflux.Token{Value: tc.token})
 		assert.Equal(t, tc.id, id.GetValue(), fmt.Sprintf("%s: expected %s got %s", desc, tc.id, id.GetValue()))
+		assert.Equal(t, tc.role, id.GetRole(), fmt.Sprintf("%s: expected role %s got %s", desc, tc.role, id.GetRole()))
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s", desc, tc.err, err))
+	}
+}
+
+func TestGroups(t *testing.T) {
+	member := users.User{Email: "jane.doe@email.com", Password: "pass"}
+	svc.Register(context.Background(), user)
+	svc.Register(context.Background(), member)
+	id, err := svc.CreateGroup(context.Background(), user.Email, users.Group{Name: "group"})
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	usersAddr := fmt.Sprintf("localhost:%d", port)
+	conn, _ := grpc.Dial(usersAddr, grpc.WithInsecure())
+	client := grpcapi.NewClient(mocktracer.New(), conn, time.Second)
+
+	cases := map[string]struct {
+		token string
+		ids   []string
+		err   error
+	}{
+		"retrieve groups of group owner":         {user.Email, []string{id}, nil},
+		"retrieve groups of user without groups": {member.Email, []string{}, nil},
+		"retrieve groups with empty token":       {"", nil, status.Error(codes.InvalidArgument, "received invalid token request")},
+	}
+
+	for desc, tc := range cases {
+		ids, err := client.Groups(context.Background(), &mainflux.Token{Value: tc.token})
+		assert.ElementsMatch(t, tc.ids, ids.GetValue(), fmt.Sprintf("%s: expected %v got %v", desc, tc.ids, ids.GetValue()))
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s", desc, tc.err, err))
+	}
+}
+
+func TestDisabled(t *testing.T) {
+	disabled := users.User{Email: "disabled@email.com", Password: "pass"}
+	svc.Register(context.Background(), user)
+	svc.Register(context.Background(), admin)
+	svc.Register(context.Background(), disabled)
+	err := svc.DisableUser(context.Background(), admin.Email, disabled.Email)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	usersAddr := fmt.Sprintf("localhost:%d", port)
+	conn, _ := grpc.Dial(usersAddr, grpc.WithInsecure())
+	client := grpcapi.NewClient(mocktracer.New(), conn, time.Second)
+
+	cases := map[string]struct {
+		email    string
+		disabled bool
+		err      error
+	}{
+		"check enabled user":            {user.Email, false, nil},
+		"check disabled user":           {disabled.Email, true, nil},
+		"check user that doesn't exist": {"unknown@email.com", false, status.Error(codes.NotFound, "user not found")},
+		"check user with empty email":   {"", false, status.Error(codes.InvalidArgument, "received invalid token request")},
+	}
+
+	for desc, tc := range cases {
+		st, err := client.Disabled(context.Background(), &mainflux.UserID{Value: tc.email})
+		assert.Equal(t, tc.disabled, st.GetDisabled(), fmt.Sprintf("%s: expected %t got %t", desc, tc.disabled, st.GetDisabled()))
 		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s", desc, tc.err, err))
 	}
 }
@@ -15,3 +15,14 @@ func (req identityReq) validate() error {
 	}
 	return nil
 }
+
+type userReq struct {
+	email string
+}
+
+func (req userReq) validate() error {
+	if req.email == "" {
+		return users.ErrMalformedEntity
+	}
+	return nil
+}
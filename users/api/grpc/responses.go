@@ -4,6 +4,17 @@
 package grpc
 
 type identityRes struct {
-	id  string
+	id   string
+	role string
+	err  error
+}
+
+type groupsRes struct {
+	ids []string
 	err error
 }
+
+type disabledRes struct {
+	disabled bool
+	err      error
+}
@@ -17,27 +17,56 @@ import (
 var _ mainflux.UsersServiceServer = (*grpcServer)(nil)
 
 type grpcServer struct {
-	handler kitgrpc.Handler
+	identify kitgrpc.Handler
+	groups   kitgrpc.Handler
+	disabled kitgrpc.Handler
 }
 
 // NewServer returns new UsersServiceServer instance.
 func NewServer(tracer opentracing.Tracer, svc users.Service) mainflux.UsersServiceServer {
-	handler := kitgrpc.NewServer(
-		kitot.TraceServer(tracer, "identify")(identifyEndpoint(svc)),
-		decodeIdentifyRequest,
-		encodeIdentifyResponse,
-	)
-	return &grpcServer{handler}
+	return &grpcServer{
+		identify: kitgrpc.NewServer(
+			kitot.TraceServer(tracer, "identify")(identifyEndpoint(svc)),
+			decodeIdentifyRequest,
+			encodeIdentifyResponse,
+		),
+		groups: kitgrpc.NewServer(
+			kitot.TraceServer(tracer, "groups")(groupsEndpoint(svc)),
+			decodeIdentifyRequest,
+			encodeGroupsResponse,
+		),
+		disabled: kitgrpc.NewServer(
+			kitot.TraceServer(tracer, "disabled")(disabledEndpoint(svc)),
+			decodeDisabledRequest,
+			encodeDisabledResponse,
+		),
+	}
 }
 
 func (s *grpcServer) Identify(ctx context.Context, token *mainflux.Token) (*mainflux.UserID, error) {
-	_, res, err := s.handler.ServeGRPC(ctx, token)
+	_, res, err := s.identify.ServeGRPC(ctx, token)
 	if err != nil {
 		return nil, encodeError(err)
 	}
 	return res.(*mainflux.UserID), nil
 }
 
+func (s *grpcServer) Groups(ctx context.Context, token *mainflux.Token) (*mainflux.GroupIDs, error) {
+	_, res, err := s.groups.ServeGRPC(ctx, token)
+	if err != nil {
+		return nil, encodeError(err)
+	}
+	return res.(*mainflux.GroupIDs), nil
+}
+
+func (s *grpcServer) Disabled(ctx context.Context, id *mainflux.UserID) (*mainflux.UserStatus, error) {
+	_, res, err := s.disabled.ServeGRPC(ctx, id)
+	if err != nil {
+		return nil, encodeError(err)
+	}
+	return res.(*mainflux.UserStatus), nil
+}
+
 func decodeIdentifyRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
 	req := grpcReq.(*mainflux.Token)
 	return identityReq{req.GetValue()}, nil
@@ -45,7 +74,22 @@ func decodeIdentifyRequest(_ context.Context, grpcReq interface{}) (interface{},
 
 func encodeIdentifyResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
 	res := grpcRes.(identityRes)
-	return &mainflux.UserID{Value: res.id}, encodeError(res.err)
+	return &mainflux.UserID{Value: res.id, Role: res.role}, encodeError(res.err)
+}
+
+func encodeGroupsResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
+	res := grpcRes.(groupsRes)
+	return &mainflux.GroupIDs{Value: res.ids}, encodeError(res.err)
+}
+
+func decodeDisabledRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*mainflux.UserID)
+	return userReq{req.GetValue()}, nil
+}
+
+func encodeDisabledResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
+	res := grpcRes.(disabledRes)
+	return &mainflux.UserStatus{Disabled: res.disabled}, encodeError(res.err)
 }
 
 func encodeError(err error) error {
@@ -58,6 +102,8 @@ func encodeError(err error) error {
 		return status.Error(codes.InvalidArgument, "received invalid token request")
 	case users.ErrUnauthorizedAccess:
 		return status.Error(codes.Unauthenticated, "failed to identify user from token")
+	case users.ErrNotFound:
+		return status.Error(codes.NotFound, "user not found")
 	default:
 		return status.Error(codes.Internal, "internal server error")
 	}
@@ -40,15 +40,538 @@ func userInfoEndpoint(svc users.Service) endpoint.Endpoint {
 	}
 }
 
+func unregisterEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewUserInfoReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.Unregister(ctx, req.token); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
+func enrollTOTPEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewUserInfoReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		secret, uri, err := svc.EnrollTOTP(ctx, req.token)
+		if err != nil {
+			return nil, err
+		}
+
+		return totpRes{Secret: secret, URI: uri}, nil
+	}
+}
+
+func verifyTOTPEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(otpReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		codes, err := svc.VerifyTOTP(ctx, req.token, req.OTP)
+		if err != nil {
+			return nil, err
+		}
+
+		return recoveryCodesRes{Codes: codes}, nil
+	}
+}
+
+func changePasswordEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(changePasswordReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.ChangePassword(ctx, req.token, req.OldPassword, req.Password); err != nil {
+			return nil, err
+		}
+
+		return passwordRes{}, nil
+	}
+}
+
 func loginEndpoint(svc users.Service) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
-		req := request.(userReq)
+		req := request.(loginReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		token, err := svc.Login(ctx, req.user, req.otp)
+		if err != nil {
+			return nil, err
+		}
+
+		return tokenRes{token}, nil
+	}
+}
+
+func createGroupEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(createGroupReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		id, err := svc.CreateGroup(ctx, req.token, users.Group{Name: req.Name})
+		if err != nil {
+			return nil, err
+		}
+
+		return groupRes{id}, nil
+	}
+}
+
+func viewGroupEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(groupReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		g, err := svc.ViewGroup(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		return viewGroupRes{
+			ID:      g.ID,
+			Owner:   g.Owner,
+			Name:    g.Name,
+			Members: g.Members,
+		}, nil
+	}
+}
+
+func listGroupsEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewUserInfoReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		groups, err := svc.ListGroups(ctx, req.token)
+		if err != nil {
+			return nil, err
+		}
+
+		res := groupsRes{Groups: []viewGroupRes{}}
+		for _, g := range groups {
+			res.Groups = append(res.Groups, viewGroupRes{
+				ID:    g.ID,
+				Owner: g.Owner,
+				Name:  g.Name,
+			})
+		}
+
+		return res, nil
+	}
+}
+
+func removeGroupEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(groupReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RemoveGroup(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
+func assignUserEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(memberReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.AssignUser(ctx, req.token, req.id, req.email); err != nil {
+			return nil, err
+		}
+
+		return memberRes{}, nil
+	}
+}
+
+func unassignUserEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(memberReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.UnassignUser(ctx, req.token, req.id, req.email); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
+func issueKeyEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(issueKeyReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		key := users.Key{
+			Name:   req.Name,
+			Scopes: req.Scopes,
+		}
+		if req.ExpiresAt != nil {
+			key.ExpiresAt = *req.ExpiresAt
+		}
+
+		issued, token, err := svc.IssueKey(ctx, req.token, key)
+		if err != nil {
+			return nil, err
+		}
+
+		res := toKeyRes(issued)
+		res.Key = token
+		res.created = true
+		return res, nil
+	}
+}
+
+func listKeysEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewUserInfoReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		keys, err := svc.ListKeys(ctx, req.token)
+		if err != nil {
+			return nil, err
+		}
+
+		res := keysRes{Keys: []keyRes{}}
+		for _, k := range keys {
+			res.Keys = append(res.Keys, toKeyRes(k))
+		}
+
+		return res, nil
+	}
+}
+
+func revokeKeyEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(keyReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RevokeKey(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
+func toKeyRes(key users.Key) keyRes {
+	res := keyRes{
+		ID:       key.ID,
+		Name:     key.Name,
+		Scopes:   key.Scopes,
+		IssuedAt: key.IssuedAt,
+	}
+	if !key.ExpiresAt.IsZero() {
+		expiresAt := key.ExpiresAt
+		res.ExpiresAt = &expiresAt
+	}
+
+	return res
+}
+
+func assignRoleEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(assignRoleReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.AssignRole(ctx, req.token, req.email, req.Role); err != nil {
+			return nil, err
+		}
+
+		return roleRes{Role: req.Role}, nil
+	}
+}
+
+func viewRoleEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(roleReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		role, err := svc.ViewRole(ctx, req.token, req.email)
+		if err != nil {
+			return nil, err
+		}
+
+		return roleRes{Role: role}, nil
+	}
+}
+
+func listUsersEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listUsersReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		page, err := svc.ListUsers(ctx, req.token, req.offset, req.limit)
+		if err != nil {
+			return nil, err
+		}
+
+		res := usersPageRes{
+			Total:  page.Total,
+			Offset: page.Offset,
+			Limit:  page.Limit,
+			Users:  []userView{},
+		}
+		for _, u := range page.Users {
+			res.Users = append(res.Users, userView{
+				Email:    u.Email,
+				Metadata: u.Metadata,
+				Disabled: u.Disabled,
+			})
+		}
+
+		return res, nil
+	}
+}
+
+func disableUserEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(adminUserReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.DisableUser(ctx, req.token, req.email); err != nil {
+			return nil, err
+		}
+
+		return userStatusRes{}, nil
+	}
+}
+
+func enableUserEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(adminUserReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.EnableUser(ctx, req.token, req.email); err != nil {
+			return nil, err
+		}
+
+		return userStatusRes{}, nil
+	}
+}
+
+func removeUserEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(adminUserReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RemoveUser(ctx, req.token, req.email); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
+func impersonateEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(adminUserReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		token, err := svc.Impersonate(ctx, req.token, req.email)
+		if err != nil {
+			return nil, err
+		}
+
+		return tokenRes{Token: token}, nil
+	}
+}
+
+func createOrgEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(createOrgReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		id, err := svc.CreateOrg(ctx, req.token, users.Org{Name: req.Name})
+		if err != nil {
+			return nil, err
+		}
+
+		return orgRes{id}, nil
+	}
+}
+
+func viewOrgEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(orgReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		o, err := svc.ViewOrg(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		res := viewOrgRes{
+			ID:    o.ID,
+			Owner: o.Owner,
+			Name:  o.Name,
+		}
+		for _, m := range o.Members {
+			res.Members = append(res.Members, memberView{Email: m.Email, Role: m.Role})
+		}
+
+		return res, nil
+	}
+}
+
+func listOrgsEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewUserInfoReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		orgs, err := svc.ListOrgs(ctx, req.token)
+		if err != nil {
+			return nil, err
+		}
+
+		res := orgsRes{Orgs: []viewOrgRes{}}
+		for _, o := range orgs {
+			res.Orgs = append(res.Orgs, viewOrgRes{
+				ID:    o.ID,
+				Owner: o.Owner,
+				Name:  o.Name,
+			})
+		}
+
+		return res, nil
+	}
+}
+
+func removeOrgEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(orgReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RemoveOrg(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
+func assignOrgMemberEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(orgMemberReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.AssignOrgMember(ctx, req.token, req.id, req.email, req.Role); err != nil {
+			return nil, err
+		}
+
+		return memberRes{}, nil
+	}
+}
+
+func unassignOrgMemberEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(orgMemberReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.UnassignOrgMember(ctx, req.token, req.id, req.email); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
+func issueOrgTokenEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(orgReq)
 
 		if err := req.validate(); err != nil {
 			return nil, err
 		}
 
-		token, err := svc.Login(ctx, req.user)
+		token, err := svc.IssueOrgToken(ctx, req.token, req.id)
 		if err != nil {
 			return nil, err
 		}
@@ -14,6 +14,7 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	log "github.com/mainflux/mainflux/logger"
 	"github.com/mainflux/mainflux/users"
@@ -30,7 +31,10 @@ const (
 	id           = "123e4567-e89b-12d3-a456-000000000001"
 )
 
-var user = users.User{Email: "user@example.com", Password: "password"}
+var (
+	user  = users.User{Email: "user@example.com", Password: "password"}
+	admin = users.User{Email: "admin@example.com", Password: "password"}
+)
 
 type testRequest struct {
 	client      *http.Client
@@ -57,10 +61,17 @@ func (tr testRequest) make() (*http.Response, error) {
 
 func newService() users.Service {
 	repo := mocks.NewUserRepository()
+	groups := mocks.NewGroupRepository()
+	keys := mocks.NewKeyRepository()
+	roles := mocks.NewRoleRepository()
+	orgs := mocks.NewOrgRepository()
+	totps := mocks.NewTOTPRepository()
 	hasher := mocks.NewHasher()
 	idp := mocks.NewIdentityProvider()
+	uuidp := mocks.NewIDProvider()
+	otpp := mocks.NewOTPProvider()
 
-	return users.New(repo, hasher, idp)
+	return users.New(repo, groups, keys, roles, orgs, totps, []string{admin.Email}, hasher, idp, uuidp, otpp, users.PasswordPolicy{}, nil, users.LockoutPolicy{}, nil)
 }
 
 func newServer(svc users.Service) *httptest.Server {
@@ -171,3 +182,974 @@ func TestLogin(t *testing.T) {
 		assert.Equal(t, tc.res, token, fmt.Sprintf("%s: expected body %s got %s", tc.desc, tc.res, token))
 	}
 }
+
+func TestLoginLockout(t *testing.T) {
+	lockout := users.LockoutPolicy{Attempts: 2, Duration: time.Minute, MaxDuration: time.Hour}
+	svc := users.New(mocks.NewUserRepository(), mocks.NewGroupRepository(), mocks.NewKeyRepository(), mocks.NewRoleRepository(), mocks.NewOrgRepository(), mocks.NewTOTPRepository(), nil, mocks.NewHasher(), mocks.NewIdentityProvider(), mocks.NewIDProvider(), mocks.NewOTPProvider(), users.PasswordPolicy{}, nil, lockout, mocks.NewLoginAttempts())
+	ts := newServer(svc)
+	defer ts.Close()
+	client := ts.Client()
+	svc.Register(context.Background(), user)
+
+	data := toJSON(user)
+	invalidData := toJSON(users.User{Email: user.Email, Password: "invalid_password"})
+
+	cases := []struct {
+		desc   string
+		req    string
+		status int
+	}{
+		{"login with invalid credentials", invalidData, http.StatusForbidden},
+		{"login with invalid credentials again", invalidData, http.StatusForbidden},
+		{"login to locked account", data, http.StatusTooManyRequests},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      client,
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/tokens", ts.URL),
+			contentType: contentType,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestChangePassword(t *testing.T) {
+	policy := users.PasswordPolicy{MinLength: 8}
+	breaches := mocks.NewBreachChecker("breached")
+	svc := users.New(mocks.NewUserRepository(), mocks.NewGroupRepository(), mocks.NewKeyRepository(), mocks.NewRoleRepository(), mocks.NewOrgRepository(), mocks.NewTOTPRepository(), nil, mocks.NewHasher(), mocks.NewIdentityProvider(), mocks.NewIDProvider(), mocks.NewOTPProvider(), policy, breaches, users.LockoutPolicy{}, nil)
+	ts := newServer(svc)
+	defer ts.Close()
+	client := ts.Client()
+	svc.Register(context.Background(), user)
+
+	data := toJSON(map[string]string{"old_password": user.Password, "password": "new-password"})
+	wrongData := toJSON(map[string]string{"old_password": "invalid_password", "password": "new-password"})
+	weakData := toJSON(map[string]string{"old_password": user.Password, "password": "short"})
+	breachedData := toJSON(map[string]string{"old_password": user.Password, "password": "breached"})
+	missingData := toJSON(map[string]string{"old_password": user.Password})
+
+	cases := []struct {
+		desc        string
+		req         string
+		contentType string
+		token       string
+		status      int
+	}{
+		{"change password with empty token", data, contentType, "", http.StatusForbidden},
+		{"change password with wrong current password", wrongData, contentType, user.Email, http.StatusForbidden},
+		{"change password to weak password", weakData, contentType, user.Email, http.StatusBadRequest},
+		{"change password to breached password", breachedData, contentType, user.Email, http.StatusBadRequest},
+		{"change password with missing password", missingData, contentType, user.Email, http.StatusBadRequest},
+		{"change password with invalid request format", "{", contentType, user.Email, http.StatusBadRequest},
+		{"change password with missing content type", data, "", user.Email, http.StatusUnsupportedMediaType},
+		{"change password", data, contentType, user.Email, http.StatusOK},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      client,
+			method:      http.MethodPatch,
+			url:         fmt.Sprintf("%s/password", ts.URL),
+			contentType: tc.contentType,
+			token:       tc.token,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestEnrollVerifyTOTP(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+	client := ts.Client()
+	svc.Register(context.Background(), user)
+
+	enrollCases := []struct {
+		desc   string
+		token  string
+		status int
+	}{
+		{"enroll second factor with empty token", "", http.StatusForbidden},
+		{"enroll second factor", user.Email, http.StatusCreated},
+	}
+
+	var enrollment struct {
+		Secret string `json:"secret"`
+		URI    string `json:"uri"`
+	}
+	for _, tc := range enrollCases {
+		req := testRequest{
+			client: client,
+			method: http.MethodPost,
+			url:    fmt.Sprintf("%s/totp", ts.URL),
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		if res.StatusCode == http.StatusCreated {
+			json.NewDecoder(res.Body).Decode(&enrollment)
+		}
+	}
+	assert.NotEmpty(t, enrollment.URI, "expected provisioning URI")
+
+	data := toJSON(map[string]string{"otp": enrollment.Secret})
+	wrongData := toJSON(map[string]string{"otp": "000000"})
+
+	verifyCases := []struct {
+		desc        string
+		req         string
+		contentType string
+		token       string
+		status      int
+		codes       int
+	}{
+		{"verify second factor with empty token", data, contentType, "", http.StatusForbidden, 0},
+		{"verify second factor with wrong password", wrongData, contentType, user.Email, http.StatusForbidden, 0},
+		{"verify second factor with empty password", "{}", contentType, user.Email, http.StatusBadRequest, 0},
+		{"verify second factor with invalid request format", "{", contentType, user.Email, http.StatusBadRequest, 0},
+		{"verify second factor with missing content type", data, "", user.Email, http.StatusUnsupportedMediaType, 0},
+		{"verify second factor", data, contentType, user.Email, http.StatusOK, users.RecoveryCodes},
+		{"verify enabled second factor", data, contentType, user.Email, http.StatusConflict, 0},
+	}
+
+	for _, tc := range verifyCases {
+		req := testRequest{
+			client:      client,
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/totp/verify", ts.URL),
+			contentType: tc.contentType,
+			token:       tc.token,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+
+		var body struct {
+			Codes []string `json:"recovery_codes"`
+		}
+		json.NewDecoder(res.Body).Decode(&body)
+		assert.Len(t, body.Codes, tc.codes, fmt.Sprintf("%s: expected %d recovery codes got %d", tc.desc, tc.codes, len(body.Codes)))
+	}
+}
+
+func TestLoginTOTP(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+	client := ts.Client()
+	svc.Register(context.Background(), user)
+	secret, _, _ := svc.EnrollTOTP(context.Background(), user.Email)
+	svc.VerifyTOTP(context.Background(), user.Email, secret)
+
+	// The mock password of the first period is used for the verification.
+	data := toJSON(map[string]string{"email": user.Email, "password": user.Password, "otp": secret + "-2"})
+	missingData := toJSON(user)
+	wrongData := toJSON(map[string]string{"email": user.Email, "password": user.Password, "otp": "000000"})
+
+	cases := []struct {
+		desc   string
+		req    string
+		status int
+	}{
+		{"login without one-time password", missingData, http.StatusUnauthorized},
+		{"login with wrong one-time password", wrongData, http.StatusForbidden},
+		{"login with valid one-time password", data, http.StatusCreated},
+		{"login with used one-time password", data, http.StatusForbidden},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      client,
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/tokens", ts.URL),
+			contentType: contentType,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestUnregister(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+	client := ts.Client()
+	svc.Register(context.Background(), user)
+
+	cases := []struct {
+		desc   string
+		token  string
+		status int
+	}{
+		{"unregister user with empty token", "", http.StatusForbidden},
+		{"unregister existing user", user.Email, http.StatusNoContent},
+		{"unregister unregistered user", user.Email, http.StatusForbidden},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: client,
+			method: http.MethodDelete,
+			url:    fmt.Sprintf("%s/users", ts.URL),
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestCreateGroup(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+	client := ts.Client()
+	svc.Register(context.Background(), user)
+
+	data := toJSON(map[string]string{"name": "group"})
+	invalidData := toJSON(map[string]string{"name": ""})
+
+	cases := []struct {
+		desc        string
+		req         string
+		contentType string
+		token       string
+		status      int
+		location    string
+	}{
+		{"create new group", data, contentType, user.Email, http.StatusCreated, fmt.Sprintf("/groups/%s", id)},
+		{"create group with invalid name", invalidData, contentType, user.Email, http.StatusBadRequest, ""},
+		{"create group with invalid request format", "{", contentType, user.Email, http.StatusBadRequest, ""},
+		{"create group with empty request", "", contentType, user.Email, http.StatusBadRequest, ""},
+		{"create group with missing content type", data, "", user.Email, http.StatusUnsupportedMediaType, ""},
+		{"create group with empty token", data, contentType, "", http.StatusForbidden, ""},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      client,
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/groups", ts.URL),
+			contentType: tc.contentType,
+			token:       tc.token,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		location := res.Header.Get("Location")
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		assert.Equal(t, tc.location, location, fmt.Sprintf("%s: expected location %s got %s", tc.desc, tc.location, location))
+	}
+}
+
+func TestViewGroup(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+	client := ts.Client()
+	svc.Register(context.Background(), user)
+	gid, err := svc.CreateGroup(context.Background(), user.Email, users.Group{Name: "group"})
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	data := fmt.Sprintf(`{"id":"%s","owner":"%s","name":"group","members":["%s"]}`, gid, user.Email, user.Email)
+
+	cases := []struct {
+		desc   string
+		id     string
+		token  string
+		status int
+		res    string
+	}{
+		{"view existing group", gid, user.Email, http.StatusOK, data},
+		{"view non-existent group", wrongID, user.Email, http.StatusNotFound, ""},
+		{"view group with empty token", gid, "", http.StatusForbidden, ""},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: client,
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/groups/%s", ts.URL, tc.id),
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		body, err := ioutil.ReadAll(res.Body)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		data := strings.Trim(string(body), "\n")
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		assert.Equal(t, tc.res, data, fmt.Sprintf("%s: expected body %s got %s", tc.desc, tc.res, data))
+	}
+}
+
+func TestListGroups(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+	client := ts.Client()
+	svc.Register(context.Background(), user)
+
+	n := 3
+	for i := 0; i < n; i++ {
+		_, err := svc.CreateGroup(context.Background(), user.Email, users.Group{Name: "group"})
+		assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	cases := []struct {
+		desc   string
+		token  string
+		status int
+		size   int
+	}{
+		{"list groups", user.Email, http.StatusOK, n},
+		{"list groups with empty token", "", http.StatusForbidden, 0},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: client,
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/groups", ts.URL),
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		var body struct {
+			Groups []interface{} `json:"groups"`
+		}
+		json.NewDecoder(res.Body).Decode(&body)
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		assert.Equal(t, tc.size, len(body.Groups), fmt.Sprintf("%s: expected %d groups got %d", tc.desc, tc.size, len(body.Groups)))
+	}
+}
+
+func TestRemoveGroup(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+	client := ts.Client()
+	svc.Register(context.Background(), user)
+	gid, err := svc.CreateGroup(context.Background(), user.Email, users.Group{Name: "group"})
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		id     string
+		token  string
+		status int
+	}{
+		{"remove group with empty token", gid, "", http.StatusForbidden},
+		{"remove existing group", gid, user.Email, http.StatusNoContent},
+		{"remove removed group", gid, user.Email, http.StatusNoContent},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: client,
+			method: http.MethodDelete,
+			url:    fmt.Sprintf("%s/groups/%s", ts.URL, tc.id),
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestAssignUser(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+	client := ts.Client()
+	member := users.User{Email: "member@example.com", Password: "password"}
+	svc.Register(context.Background(), user)
+	svc.Register(context.Background(), member)
+	gid, err := svc.CreateGroup(context.Background(), user.Email, users.Group{Name: "group"})
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		id     string
+		email  string
+		token  string
+		status int
+	}{
+		{"assign user to group", gid, member.Email, user.Email, http.StatusOK},
+		{"assign assigned user to group", gid, member.Email, user.Email, http.StatusConflict},
+		{"assign non-existent user to group", gid, invalidEmail, user.Email, http.StatusNotFound},
+		{"assign user to non-existent group", wrongID, member.Email, user.Email, http.StatusNotFound},
+		{"assign user to group as non-owner", gid, member.Email, member.Email, http.StatusNotFound},
+		{"assign user to group with empty token", gid, member.Email, "", http.StatusForbidden},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: client,
+			method: http.MethodPut,
+			url:    fmt.Sprintf("%s/groups/%s/members/%s", ts.URL, tc.id, tc.email),
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestUnassignUser(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+	client := ts.Client()
+	member := users.User{Email: "member@example.com", Password: "password"}
+	svc.Register(context.Background(), user)
+	svc.Register(context.Background(), member)
+	gid, err := svc.CreateGroup(context.Background(), user.Email, users.Group{Name: "group"})
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = svc.AssignUser(context.Background(), user.Email, gid, member.Email)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		id     string
+		email  string
+		token  string
+		status int
+	}{
+		{"unassign owner from group", gid, user.Email, user.Email, http.StatusBadRequest},
+		{"unassign user from group with empty token", gid, member.Email, "", http.StatusForbidden},
+		{"unassign user from group", gid, member.Email, user.Email, http.StatusNoContent},
+		{"unassign unassigned user from group", gid, member.Email, user.Email, http.StatusNotFound},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: client,
+			method: http.MethodDelete,
+			url:    fmt.Sprintf("%s/groups/%s/members/%s", ts.URL, tc.id, tc.email),
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestIssueKey(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+	client := ts.Client()
+	svc.Register(context.Background(), user)
+
+	data := `{"name": "ci", "scopes": ["services"]}`
+	expiringData := `{"name": "ci", "scopes": ["services"], "expires_at": "2100-01-01T00:00:00Z"}`
+	invalidScopeData := `{"name": "ci", "scopes": ["admin"]}`
+
+	cases := []struct {
+		desc        string
+		req         string
+		contentType string
+		token       string
+		status      int
+	}{
+		{"issue key", data, contentType, user.Email, http.StatusCreated},
+		{"issue key with expiration", expiringData, contentType, user.Email, http.StatusCreated},
+		{"issue key with unknown scope", invalidScopeData, contentType, user.Email, http.StatusBadRequest},
+		{"issue key with invalid request format", "{", contentType, user.Email, http.StatusBadRequest},
+		{"issue key with empty token", data, contentType, "", http.StatusForbidden},
+		{"issue key with missing content type", data, "", user.Email, http.StatusUnsupportedMediaType},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      client,
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/keys", ts.URL),
+			contentType: tc.contentType,
+			token:       tc.token,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		var body struct {
+			Key string `json:"key"`
+		}
+		json.NewDecoder(res.Body).Decode(&body)
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		if tc.status == http.StatusCreated {
+			assert.True(t, strings.HasPrefix(body.Key, users.KeyPrefix), fmt.Sprintf("%s: expected key token got %s", tc.desc, body.Key))
+		}
+	}
+}
+
+func TestListRevokeKeys(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+	client := ts.Client()
+	svc.Register(context.Background(), user)
+	key, _, err := svc.IssueKey(context.Background(), user.Email, users.Key{Name: "ci", Scopes: []string{users.ScopeServices}})
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	listCases := []struct {
+		desc   string
+		token  string
+		status int
+		size   int
+	}{
+		{"list keys", user.Email, http.StatusOK, 1},
+		{"list keys with empty token", "", http.StatusForbidden, 0},
+	}
+
+	for _, tc := range listCases {
+		req := testRequest{
+			client: client,
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/keys", ts.URL),
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		var body struct {
+			Keys []interface{} `json:"keys"`
+		}
+		json.NewDecoder(res.Body).Decode(&body)
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		assert.Equal(t, tc.size, len(body.Keys), fmt.Sprintf("%s: expected %d keys got %d", tc.desc, tc.size, len(body.Keys)))
+	}
+
+	revokeCases := []struct {
+		desc   string
+		id     string
+		token  string
+		status int
+	}{
+		{"revoke key with empty token", key.ID, "", http.StatusForbidden},
+		{"revoke existing key", key.ID, user.Email, http.StatusNoContent},
+		{"revoke revoked key", key.ID, user.Email, http.StatusNotFound},
+	}
+
+	for _, tc := range revokeCases {
+		req := testRequest{
+			client: client,
+			method: http.MethodDelete,
+			url:    fmt.Sprintf("%s/keys/%s", ts.URL, tc.id),
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestAssignViewRole(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+	client := ts.Client()
+	svc.Register(context.Background(), user)
+	svc.Register(context.Background(), admin)
+
+	assignCases := []struct {
+		desc        string
+		email       string
+		req         string
+		contentType string
+		token       string
+		status      int
+	}{
+		{"assign role as admin", user.Email, `{"role": "viewer"}`, contentType, admin.Email, http.StatusOK},
+		{"assign unknown role", user.Email, `{"role": "owner"}`, contentType, admin.Email, http.StatusBadRequest},
+		{"assign role to non-existing user", "none@example.com", `{"role": "viewer"}`, contentType, admin.Email, http.StatusNotFound},
+		{"assign role as non-admin", user.Email, `{"role": "admin"}`, contentType, user.Email, http.StatusForbidden},
+		{"assign role with empty token", user.Email, `{"role": "viewer"}`, contentType, "", http.StatusForbidden},
+		{"assign role with invalid request format", user.Email, "{", contentType, admin.Email, http.StatusBadRequest},
+		{"assign role with missing content type", user.Email, `{"role": "viewer"}`, "", admin.Email, http.StatusUnsupportedMediaType},
+	}
+
+	for _, tc := range assignCases {
+		req := testRequest{
+			client:      client,
+			method:      http.MethodPut,
+			url:         fmt.Sprintf("%s/users/%s/role", ts.URL, tc.email),
+			contentType: tc.contentType,
+			token:       tc.token,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+
+	viewCases := []struct {
+		desc   string
+		email  string
+		token  string
+		status int
+		role   string
+	}{
+		{"view own role", user.Email, user.Email, http.StatusOK, users.RoleViewer},
+		{"view other user role as admin", user.Email, admin.Email, http.StatusOK, users.RoleViewer},
+		{"view other user role as non-admin", admin.Email, user.Email, http.StatusForbidden, ""},
+		{"view role with empty token", user.Email, "", http.StatusForbidden, ""},
+	}
+
+	for _, tc := range viewCases {
+		req := testRequest{
+			client: client,
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/users/%s/role", ts.URL, tc.email),
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		var body struct {
+			Role string `json:"role"`
+		}
+		json.NewDecoder(res.Body).Decode(&body)
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		assert.Equal(t, tc.role, body.Role, fmt.Sprintf("%s: expected role %s got %s", tc.desc, tc.role, body.Role))
+	}
+}
+
+func TestListUsers(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+	client := ts.Client()
+	svc.Register(context.Background(), user)
+	svc.Register(context.Background(), admin)
+
+	cases := []struct {
+		desc   string
+		query  string
+		token  string
+		status int
+		size   int
+	}{
+		{"list users as admin", "", admin.Email, http.StatusOK, 2},
+		{"list users with limit", "?offset=0&limit=1", admin.Email, http.StatusOK, 1},
+		{"list users with offset", "?offset=1&limit=10", admin.Email, http.StatusOK, 1},
+		{"list users with zero limit", "?limit=0", admin.Email, http.StatusBadRequest, 0},
+		{"list users with limit greater than max", "?limit=110", admin.Email, http.StatusBadRequest, 0},
+		{"list users with invalid offset", "?offset=e", admin.Email, http.StatusBadRequest, 0},
+		{"list users as non-admin", "", user.Email, http.StatusForbidden, 0},
+		{"list users with empty token", "", "", http.StatusForbidden, 0},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: client,
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/admin/users%s", ts.URL, tc.query),
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		var body struct {
+			Users []struct {
+				Email    string `json:"email"`
+				Disabled bool   `json:"disabled"`
+			} `json:"users"`
+		}
+		json.NewDecoder(res.Body).Decode(&body)
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		assert.Equal(t, tc.size, len(body.Users), fmt.Sprintf("%s: expected %d users got %d", tc.desc, tc.size, len(body.Users)))
+	}
+}
+
+func TestDisableEnableUser(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+	client := ts.Client()
+	svc.Register(context.Background(), user)
+	svc.Register(context.Background(), admin)
+
+	cases := []struct {
+		desc   string
+		action string
+		email  string
+		token  string
+		status int
+	}{
+		{"disable user as non-admin", "disable", user.Email, user.Email, http.StatusForbidden},
+		{"disable user with empty token", "disable", user.Email, "", http.StatusForbidden},
+		{"disable own account", "disable", admin.Email, admin.Email, http.StatusBadRequest},
+		{"disable non-existing user", "disable", "none@example.com", admin.Email, http.StatusNotFound},
+		{"disable user as admin", "disable", user.Email, admin.Email, http.StatusOK},
+		{"enable user as disabled user", "enable", user.Email, user.Email, http.StatusForbidden},
+		{"enable user as admin", "enable", user.Email, admin.Email, http.StatusOK},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: client,
+			method: http.MethodPut,
+			url:    fmt.Sprintf("%s/admin/users/%s/%s", ts.URL, tc.email, tc.action),
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestRemoveUser(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+	client := ts.Client()
+	svc.Register(context.Background(), user)
+	svc.Register(context.Background(), admin)
+
+	cases := []struct {
+		desc   string
+		email  string
+		token  string
+		status int
+	}{
+		{"remove user as non-admin", admin.Email, user.Email, http.StatusForbidden},
+		{"remove own account", admin.Email, admin.Email, http.StatusBadRequest},
+		{"remove user as admin", user.Email, admin.Email, http.StatusNoContent},
+		{"remove removed user", user.Email, admin.Email, http.StatusNotFound},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: client,
+			method: http.MethodDelete,
+			url:    fmt.Sprintf("%s/admin/users/%s", ts.URL, tc.email),
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestImpersonate(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+	client := ts.Client()
+	svc.Register(context.Background(), user)
+	svc.Register(context.Background(), admin)
+
+	cases := []struct {
+		desc   string
+		email  string
+		token  string
+		status int
+		res    string
+	}{
+		{"impersonate user as admin", user.Email, admin.Email, http.StatusCreated, user.Email},
+		{"impersonate non-existing user", "none@example.com", admin.Email, http.StatusNotFound, ""},
+		{"impersonate user as non-admin", admin.Email, user.Email, http.StatusForbidden, ""},
+		{"impersonate user with empty token", user.Email, "", http.StatusForbidden, ""},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: client,
+			method: http.MethodPost,
+			url:    fmt.Sprintf("%s/admin/users/%s/tokens", ts.URL, tc.email),
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		var body struct {
+			Token string `json:"token"`
+		}
+		json.NewDecoder(res.Body).Decode(&body)
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		assert.Equal(t, tc.res, body.Token, fmt.Sprintf("%s: expected token %s got %s", tc.desc, tc.res, body.Token))
+	}
+}
+
+func TestCreateOrg(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+	client := ts.Client()
+	svc.Register(context.Background(), user)
+
+	data := toJSON(map[string]string{"name": "org"})
+	invalidData := toJSON(map[string]string{"name": ""})
+
+	cases := []struct {
+		desc        string
+		req         string
+		contentType string
+		token       string
+		status      int
+		location    string
+	}{
+		{"create new org", data, contentType, user.Email, http.StatusCreated, fmt.Sprintf("/orgs/%s", id)},
+		{"create org with invalid name", invalidData, contentType, user.Email, http.StatusBadRequest, ""},
+		{"create org with invalid request format", "{", contentType, user.Email, http.StatusBadRequest, ""},
+		{"create org with missing content type", data, "", user.Email, http.StatusUnsupportedMediaType, ""},
+		{"create org with empty token", data, contentType, "", http.StatusForbidden, ""},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      client,
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/orgs", ts.URL),
+			contentType: tc.contentType,
+			token:       tc.token,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		location := res.Header.Get("Location")
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		assert.Equal(t, tc.location, location, fmt.Sprintf("%s: expected location %s got %s", tc.desc, tc.location, location))
+	}
+}
+
+func TestOrgMembers(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+	client := ts.Client()
+	svc.Register(context.Background(), user)
+	svc.Register(context.Background(), admin)
+	oid, err := svc.CreateOrg(context.Background(), user.Email, users.Org{Name: "org"})
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	assignCases := []struct {
+		desc        string
+		id          string
+		email       string
+		req         string
+		contentType string
+		token       string
+		status      int
+	}{
+		{"assign org member", oid, admin.Email, `{"role": "viewer"}`, contentType, user.Email, http.StatusOK},
+		{"assign org member with unknown role", oid, admin.Email, `{"role": "owner"}`, contentType, user.Email, http.StatusBadRequest},
+		{"assign non-existing user", oid, "none@example.com", `{"role": "viewer"}`, contentType, user.Email, http.StatusNotFound},
+		{"assign member to non-existing org", wrongID, admin.Email, `{"role": "viewer"}`, contentType, user.Email, http.StatusNotFound},
+		{"assign member as non-admin member", oid, admin.Email, `{"role": "admin"}`, contentType, admin.Email, http.StatusForbidden},
+		{"assign member with empty token", oid, admin.Email, `{"role": "viewer"}`, contentType, "", http.StatusForbidden},
+		{"assign member with invalid request format", oid, admin.Email, "{", contentType, user.Email, http.StatusBadRequest},
+		{"assign member with missing content type", oid, admin.Email, `{"role": "viewer"}`, "", user.Email, http.StatusUnsupportedMediaType},
+	}
+
+	for _, tc := range assignCases {
+		req := testRequest{
+			client:      client,
+			method:      http.MethodPut,
+			url:         fmt.Sprintf("%s/orgs/%s/members/%s", ts.URL, tc.id, tc.email),
+			contentType: tc.contentType,
+			token:       tc.token,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+
+	data := fmt.Sprintf(`{"id":"%s","owner":"%s","name":"org","members":[{"email":"%s","role":"viewer"},{"email":"%s","role":"admin"}]}`, oid, user.Email, admin.Email, user.Email)
+
+	viewCases := []struct {
+		desc   string
+		id     string
+		token  string
+		status int
+		res    string
+	}{
+		{"view org as member", oid, admin.Email, http.StatusOK, data},
+		{"view non-existent org", wrongID, user.Email, http.StatusNotFound, ""},
+		{"view org with empty token", oid, "", http.StatusForbidden, ""},
+	}
+
+	for _, tc := range viewCases {
+		req := testRequest{
+			client: client,
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/orgs/%s", ts.URL, tc.id),
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		body, err := ioutil.ReadAll(res.Body)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		data := strings.Trim(string(body), "\n")
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		assert.Equal(t, tc.res, data, fmt.Sprintf("%s: expected body %s got %s", tc.desc, tc.res, data))
+	}
+
+	unassignCases := []struct {
+		desc   string
+		email  string
+		token  string
+		status int
+	}{
+		{"unassign member as non-admin member", user.Email, admin.Email, http.StatusForbidden},
+		{"unassign owner", user.Email, user.Email, http.StatusBadRequest},
+		{"unassign member", admin.Email, user.Email, http.StatusNoContent},
+		{"unassign non-member", admin.Email, user.Email, http.StatusNotFound},
+	}
+
+	for _, tc := range unassignCases {
+		req := testRequest{
+			client: client,
+			method: http.MethodDelete,
+			url:    fmt.Sprintf("%s/orgs/%s/members/%s", ts.URL, oid, tc.email),
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestIssueOrgToken(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+	client := ts.Client()
+	svc.Register(context.Background(), user)
+	svc.Register(context.Background(), admin)
+	oid, err := svc.CreateOrg(context.Background(), user.Email, users.Org{Name: "org"})
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		id     string
+		token  string
+		status int
+	}{
+		{"issue org token as member", oid, user.Email, http.StatusCreated},
+		{"issue org token as non-member", oid, admin.Email, http.StatusNotFound},
+		{"issue token for non-existing org", wrongID, user.Email, http.StatusNotFound},
+		{"issue org token with empty token", oid, "", http.StatusForbidden},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: client,
+			method: http.MethodPost,
+			url:    fmt.Sprintf("%s/orgs/%s/tokens", ts.URL, tc.id),
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
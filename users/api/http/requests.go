@@ -3,7 +3,13 @@
 
 package http
 
-import "github.com/mainflux/mainflux/users"
+import (
+	"time"
+
+	"github.com/mainflux/mainflux/users"
+)
+
+const maxLimitSize = 100
 
 type apiReq interface {
 	validate() error
@@ -17,6 +23,32 @@ func (req userReq) validate() error {
 	return req.user.Validate()
 }
 
+type loginReq struct {
+	user users.User
+	otp  string
+}
+
+func (req loginReq) validate() error {
+	return req.user.Validate()
+}
+
+type otpReq struct {
+	token string
+	OTP   string `json:"otp"`
+}
+
+func (req otpReq) validate() error {
+	if req.token == "" {
+		return users.ErrUnauthorizedAccess
+	}
+
+	if req.OTP == "" {
+		return users.ErrMalformedEntity
+	}
+
+	return nil
+}
+
 type viewUserInfoReq struct {
 	token string
 }
@@ -27,3 +59,220 @@ func (req viewUserInfoReq) validate() error {
 	}
 	return nil
 }
+
+type changePasswordReq struct {
+	token       string
+	OldPassword string `json:"old_password"`
+	Password    string `json:"password"`
+}
+
+func (req changePasswordReq) validate() error {
+	if req.token == "" {
+		return users.ErrUnauthorizedAccess
+	}
+
+	if req.OldPassword == "" || req.Password == "" {
+		return users.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type createGroupReq struct {
+	token string
+	Name  string `json:"name"`
+}
+
+func (req createGroupReq) validate() error {
+	if req.token == "" {
+		return users.ErrUnauthorizedAccess
+	}
+
+	return users.Group{Name: req.Name}.Validate()
+}
+
+type groupReq struct {
+	token string
+	id    string
+}
+
+func (req groupReq) validate() error {
+	if req.token == "" {
+		return users.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" {
+		return users.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type memberReq struct {
+	token string
+	id    string
+	email string
+}
+
+func (req memberReq) validate() error {
+	if req.token == "" {
+		return users.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" || req.email == "" {
+		return users.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type issueKeyReq struct {
+	token     string
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+func (req issueKeyReq) validate() error {
+	if req.token == "" {
+		return users.ErrUnauthorizedAccess
+	}
+
+	return users.Key{Name: req.Name, Scopes: req.Scopes}.Validate()
+}
+
+type keyReq struct {
+	token string
+	id    string
+}
+
+func (req keyReq) validate() error {
+	if req.token == "" {
+		return users.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" {
+		return users.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type assignRoleReq struct {
+	token string
+	email string
+	Role  string `json:"role"`
+}
+
+func (req assignRoleReq) validate() error {
+	if req.token == "" {
+		return users.ErrUnauthorizedAccess
+	}
+
+	if req.email == "" || !users.ValidRole(req.Role) {
+		return users.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type roleReq struct {
+	token string
+	email string
+}
+
+func (req roleReq) validate() error {
+	if req.token == "" {
+		return users.ErrUnauthorizedAccess
+	}
+
+	if req.email == "" {
+		return users.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type createOrgReq struct {
+	token string
+	Name  string `json:"name"`
+}
+
+func (req createOrgReq) validate() error {
+	if req.token == "" {
+		return users.ErrUnauthorizedAccess
+	}
+
+	return users.Org{Name: req.Name}.Validate()
+}
+
+type orgReq struct {
+	token string
+	id    string
+}
+
+func (req orgReq) validate() error {
+	if req.token == "" {
+		return users.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" {
+		return users.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type orgMemberReq struct {
+	token string
+	id    string
+	email string
+	Role  string `json:"role"`
+}
+
+func (req orgMemberReq) validate() error {
+	if req.token == "" {
+		return users.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" || req.email == "" {
+		return users.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type listUsersReq struct {
+	token  string
+	offset uint64
+	limit  uint64
+}
+
+func (req listUsersReq) validate() error {
+	if req.token == "" {
+		return users.ErrUnauthorizedAccess
+	}
+
+	if req.limit == 0 || req.limit > maxLimitSize {
+		return users.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type adminUserReq struct {
+	token string
+	email string
+}
+
+func (req adminUserReq) validate() error {
+	if req.token == "" {
+		return users.ErrUnauthorizedAccess
+	}
+
+	if req.email == "" {
+		return users.ErrMalformedEntity
+	}
+
+	return nil
+}
@@ -4,7 +4,9 @@
 package http
 
 import (
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/mainflux/mainflux"
 )
@@ -12,6 +14,15 @@ import (
 var (
 	_ mainflux.Response = (*tokenRes)(nil)
 	_ mainflux.Response = (*identityRes)(nil)
+	_ mainflux.Response = (*groupRes)(nil)
+	_ mainflux.Response = (*viewGroupRes)(nil)
+	_ mainflux.Response = (*groupsRes)(nil)
+	_ mainflux.Response = (*removeRes)(nil)
+	_ mainflux.Response = (*memberRes)(nil)
+	_ mainflux.Response = (*keyRes)(nil)
+	_ mainflux.Response = (*keysRes)(nil)
+	_ mainflux.Response = (*usersPageRes)(nil)
+	_ mainflux.Response = (*userStatusRes)(nil)
 )
 
 type tokenRes struct {
@@ -46,3 +57,292 @@ func (res identityRes) Headers() map[string]string {
 func (res identityRes) Empty() bool {
 	return false
 }
+
+type groupRes struct {
+	id string
+}
+
+func (res groupRes) Code() int {
+	return http.StatusCreated
+}
+
+func (res groupRes) Headers() map[string]string {
+	return map[string]string{
+		"Location": fmt.Sprintf("/groups/%s", res.id),
+	}
+}
+
+func (res groupRes) Empty() bool {
+	return true
+}
+
+type viewGroupRes struct {
+	ID      string   `json:"id"`
+	Owner   string   `json:"owner"`
+	Name    string   `json:"name"`
+	Members []string `json:"members,omitempty"`
+}
+
+func (res viewGroupRes) Code() int {
+	return http.StatusOK
+}
+
+func (res viewGroupRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res viewGroupRes) Empty() bool {
+	return false
+}
+
+type groupsRes struct {
+	Groups []viewGroupRes `json:"groups"`
+}
+
+func (res groupsRes) Code() int {
+	return http.StatusOK
+}
+
+func (res groupsRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res groupsRes) Empty() bool {
+	return false
+}
+
+type removeRes struct{}
+
+func (res removeRes) Code() int {
+	return http.StatusNoContent
+}
+
+func (res removeRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res removeRes) Empty() bool {
+	return true
+}
+
+type memberRes struct{}
+
+func (res memberRes) Code() int {
+	return http.StatusOK
+}
+
+func (res memberRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res memberRes) Empty() bool {
+	return true
+}
+
+type passwordRes struct{}
+
+func (res passwordRes) Code() int {
+	return http.StatusOK
+}
+
+func (res passwordRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res passwordRes) Empty() bool {
+	return true
+}
+
+type totpRes struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+func (res totpRes) Code() int {
+	return http.StatusCreated
+}
+
+func (res totpRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res totpRes) Empty() bool {
+	return false
+}
+
+type recoveryCodesRes struct {
+	Codes []string `json:"recovery_codes"`
+}
+
+func (res recoveryCodesRes) Code() int {
+	return http.StatusOK
+}
+
+func (res recoveryCodesRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res recoveryCodesRes) Empty() bool {
+	return false
+}
+
+type keyRes struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	IssuedAt  time.Time  `json:"issued_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Key       string     `json:"key,omitempty"`
+	created   bool
+}
+
+func (res keyRes) Code() int {
+	if res.created {
+		return http.StatusCreated
+	}
+
+	return http.StatusOK
+}
+
+func (res keyRes) Headers() map[string]string {
+	if res.created {
+		return map[string]string{
+			"Location": fmt.Sprintf("/keys/%s", res.ID),
+		}
+	}
+
+	return map[string]string{}
+}
+
+func (res keyRes) Empty() bool {
+	return false
+}
+
+type keysRes struct {
+	Keys []keyRes `json:"keys"`
+}
+
+func (res keysRes) Code() int {
+	return http.StatusOK
+}
+
+func (res keysRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res keysRes) Empty() bool {
+	return false
+}
+
+type roleRes struct {
+	Role string `json:"role"`
+}
+
+func (res roleRes) Code() int {
+	return http.StatusOK
+}
+
+func (res roleRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res roleRes) Empty() bool {
+	return false
+}
+
+type orgRes struct {
+	id string
+}
+
+func (res orgRes) Code() int {
+	return http.StatusCreated
+}
+
+func (res orgRes) Headers() map[string]string {
+	return map[string]string{
+		"Location": fmt.Sprintf("/orgs/%s", res.id),
+	}
+}
+
+func (res orgRes) Empty() bool {
+	return true
+}
+
+type memberView struct {
+	Email string `json:"email"`
+	Role  string `json:"role"`
+}
+
+type viewOrgRes struct {
+	ID      string       `json:"id"`
+	Owner   string       `json:"owner"`
+	Name    string       `json:"name"`
+	Members []memberView `json:"members,omitempty"`
+}
+
+func (res viewOrgRes) Code() int {
+	return http.StatusOK
+}
+
+func (res viewOrgRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res viewOrgRes) Empty() bool {
+	return false
+}
+
+type orgsRes struct {
+	Orgs []viewOrgRes `json:"orgs"`
+}
+
+func (res orgsRes) Code() int {
+	return http.StatusOK
+}
+
+func (res orgsRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res orgsRes) Empty() bool {
+	return false
+}
+
+type userView struct {
+	Email    string                 `json:"email"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	Disabled bool                   `json:"disabled"`
+}
+
+type usersPageRes struct {
+	Total  uint64     `json:"total"`
+	Offset uint64     `json:"offset"`
+	Limit  uint64     `json:"limit"`
+	Users  []userView `json:"users"`
+}
+
+func (res usersPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res usersPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res usersPageRes) Empty() bool {
+	return false
+}
+
+type userStatusRes struct{}
+
+func (res userStatusRes) Code() int {
+	return http.StatusOK
+}
+
+func (res userStatusRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res userStatusRes) Empty() bool {
+	return true
+}
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
 
 	kitot "github.com/go-kit/kit/tracing/opentracing"
@@ -22,10 +23,18 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
-const contentType = "application/json"
+const (
+	contentType = "application/json"
+
+	offset    = "offset"
+	limit     = "limit"
+	defOffset = 0
+	defLimit  = 10
+)
 
 var (
 	errUnsupportedContentType = errors.New("unsupported content type")
+	errInvalidQueryParams     = errors.New("invalid query params")
 	logger                    log.Logger
 )
 
@@ -53,14 +62,206 @@ func MakeHandler(svc users.Service, tracer opentracing.Tracer, l log.Logger) htt
 		opts...,
 	))
 
+	mux.Delete("/users", kithttp.NewServer(
+		kitot.TraceServer(tracer, "unregister")(unregisterEndpoint(svc)),
+		decodeViewInfo,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Patch("/password", kithttp.NewServer(
+		kitot.TraceServer(tracer, "change_password")(changePasswordEndpoint(svc)),
+		decodeChangePassword,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Post("/totp", kithttp.NewServer(
+		kitot.TraceServer(tracer, "enroll_totp")(enrollTOTPEndpoint(svc)),
+		decodeViewInfo,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Post("/totp/verify", kithttp.NewServer(
+		kitot.TraceServer(tracer, "verify_totp")(verifyTOTPEndpoint(svc)),
+		decodeOTP,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Put("/users/:email/role", kithttp.NewServer(
+		kitot.TraceServer(tracer, "assign_role")(assignRoleEndpoint(svc)),
+		decodeAssignRole,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Get("/users/:email/role", kithttp.NewServer(
+		kitot.TraceServer(tracer, "view_role")(viewRoleEndpoint(svc)),
+		decodeRole,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Get("/admin/users", kithttp.NewServer(
+		kitot.TraceServer(tracer, "list_users")(listUsersEndpoint(svc)),
+		decodeListUsers,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Put("/admin/users/:email/disable", kithttp.NewServer(
+		kitot.TraceServer(tracer, "disable_user")(disableUserEndpoint(svc)),
+		decodeAdminUser,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Put("/admin/users/:email/enable", kithttp.NewServer(
+		kitot.TraceServer(tracer, "enable_user")(enableUserEndpoint(svc)),
+		decodeAdminUser,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Delete("/admin/users/:email", kithttp.NewServer(
+		kitot.TraceServer(tracer, "remove_user")(removeUserEndpoint(svc)),
+		decodeAdminUser,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Post("/admin/users/:email/tokens", kithttp.NewServer(
+		kitot.TraceServer(tracer, "impersonate")(impersonateEndpoint(svc)),
+		decodeAdminUser,
+		encodeResponse,
+		opts...,
+	))
+
 	mux.Post("/tokens", kithttp.NewServer(
 		kitot.TraceServer(tracer, "login")(loginEndpoint(svc)),
-		decodeCredentials,
+		decodeLogin,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Post("/groups", kithttp.NewServer(
+		kitot.TraceServer(tracer, "create_group")(createGroupEndpoint(svc)),
+		decodeCreateGroup,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Get("/groups", kithttp.NewServer(
+		kitot.TraceServer(tracer, "list_groups")(listGroupsEndpoint(svc)),
+		decodeViewInfo,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Get("/groups/:id", kithttp.NewServer(
+		kitot.TraceServer(tracer, "view_group")(viewGroupEndpoint(svc)),
+		decodeGroup,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Delete("/groups/:id", kithttp.NewServer(
+		kitot.TraceServer(tracer, "remove_group")(removeGroupEndpoint(svc)),
+		decodeGroup,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Put("/groups/:id/members/:email", kithttp.NewServer(
+		kitot.TraceServer(tracer, "assign_user")(assignUserEndpoint(svc)),
+		decodeMember,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Delete("/groups/:id/members/:email", kithttp.NewServer(
+		kitot.TraceServer(tracer, "unassign_user")(unassignUserEndpoint(svc)),
+		decodeMember,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Post("/keys", kithttp.NewServer(
+		kitot.TraceServer(tracer, "issue_key")(issueKeyEndpoint(svc)),
+		decodeIssueKey,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Get("/keys", kithttp.NewServer(
+		kitot.TraceServer(tracer, "list_keys")(listKeysEndpoint(svc)),
+		decodeViewInfo,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Delete("/keys/:id", kithttp.NewServer(
+		kitot.TraceServer(tracer, "revoke_key")(revokeKeyEndpoint(svc)),
+		decodeKey,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Post("/orgs", kithttp.NewServer(
+		kitot.TraceServer(tracer, "create_org")(createOrgEndpoint(svc)),
+		decodeCreateOrg,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Get("/orgs", kithttp.NewServer(
+		kitot.TraceServer(tracer, "list_orgs")(listOrgsEndpoint(svc)),
+		decodeViewInfo,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Get("/orgs/:id", kithttp.NewServer(
+		kitot.TraceServer(tracer, "view_org")(viewOrgEndpoint(svc)),
+		decodeOrg,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Delete("/orgs/:id", kithttp.NewServer(
+		kitot.TraceServer(tracer, "remove_org")(removeOrgEndpoint(svc)),
+		decodeOrg,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Put("/orgs/:id/members/:email", kithttp.NewServer(
+		kitot.TraceServer(tracer, "assign_org_member")(assignOrgMemberEndpoint(svc)),
+		decodeAssignOrgMember,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Delete("/orgs/:id/members/:email", kithttp.NewServer(
+		kitot.TraceServer(tracer, "unassign_org_member")(unassignOrgMemberEndpoint(svc)),
+		decodeOrgMember,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Post("/orgs/:id/tokens", kithttp.NewServer(
+		kitot.TraceServer(tracer, "issue_org_token")(issueOrgTokenEndpoint(svc)),
+		decodeOrg,
 		encodeResponse,
 		opts...,
 	))
 
 	mux.GetFunc("/version", mainflux.Version("users"))
+	mux.GetFunc(mainflux.DiscoveryPath, mainflux.Discovery("users", mainflux.Capabilities{
+		ContentTypes: []string{contentType},
+	}))
 	mux.Handle("/metrics", promhttp.Handler())
 
 	return mux
@@ -88,6 +289,221 @@ func decodeCredentials(_ context.Context, r *http.Request) (interface{}, error)
 	return userReq{user}, nil
 }
 
+func decodeLogin(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		logger.Warn("Invalid or missing content type.")
+		return nil, errUnsupportedContentType
+	}
+
+	var req struct {
+		users.User
+		OTP string `json:"otp"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn(fmt.Sprintf("Failed to decode user credentials: %s", err))
+		return nil, err
+	}
+
+	return loginReq{user: req.User, otp: req.OTP}, nil
+}
+
+func decodeOTP(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		logger.Warn("Invalid or missing content type.")
+		return nil, errUnsupportedContentType
+	}
+
+	req := otpReq{token: r.Header.Get("Authorization")}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn(fmt.Sprintf("Failed to decode one-time password: %s", err))
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func decodeChangePassword(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		logger.Warn("Invalid or missing content type.")
+		return nil, errUnsupportedContentType
+	}
+
+	req := changePasswordReq{token: r.Header.Get("Authorization")}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn(fmt.Sprintf("Failed to decode passwords: %s", err))
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func decodeAssignRole(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		logger.Warn("Invalid or missing content type.")
+		return nil, errUnsupportedContentType
+	}
+
+	req := assignRoleReq{
+		token: r.Header.Get("Authorization"),
+		email: bone.GetValue(r, "email"),
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn(fmt.Sprintf("Failed to decode role: %s", err))
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func decodeRole(_ context.Context, r *http.Request) (interface{}, error) {
+	req := roleReq{
+		token: r.Header.Get("Authorization"),
+		email: bone.GetValue(r, "email"),
+	}
+
+	return req, nil
+}
+
+func decodeListUsers(_ context.Context, r *http.Request) (interface{}, error) {
+	o, err := readUintQuery(r, offset, defOffset)
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := readUintQuery(r, limit, defLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	req := listUsersReq{
+		token:  r.Header.Get("Authorization"),
+		offset: o,
+		limit:  l,
+	}
+
+	return req, nil
+}
+
+func decodeAdminUser(_ context.Context, r *http.Request) (interface{}, error) {
+	req := adminUserReq{
+		token: r.Header.Get("Authorization"),
+		email: bone.GetValue(r, "email"),
+	}
+
+	return req, nil
+}
+
+func decodeCreateGroup(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		logger.Warn("Invalid or missing content type.")
+		return nil, errUnsupportedContentType
+	}
+
+	req := createGroupReq{token: r.Header.Get("Authorization")}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn(fmt.Sprintf("Failed to decode group: %s", err))
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func decodeGroup(_ context.Context, r *http.Request) (interface{}, error) {
+	req := groupReq{
+		token: r.Header.Get("Authorization"),
+		id:    bone.GetValue(r, "id"),
+	}
+
+	return req, nil
+}
+
+func decodeMember(_ context.Context, r *http.Request) (interface{}, error) {
+	req := memberReq{
+		token: r.Header.Get("Authorization"),
+		id:    bone.GetValue(r, "id"),
+		email: bone.GetValue(r, "email"),
+	}
+
+	return req, nil
+}
+
+func decodeIssueKey(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		logger.Warn("Invalid or missing content type.")
+		return nil, errUnsupportedContentType
+	}
+
+	req := issueKeyReq{token: r.Header.Get("Authorization")}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn(fmt.Sprintf("Failed to decode key: %s", err))
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func decodeKey(_ context.Context, r *http.Request) (interface{}, error) {
+	req := keyReq{
+		token: r.Header.Get("Authorization"),
+		id:    bone.GetValue(r, "id"),
+	}
+
+	return req, nil
+}
+
+func decodeCreateOrg(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		logger.Warn("Invalid or missing content type.")
+		return nil, errUnsupportedContentType
+	}
+
+	req := createOrgReq{token: r.Header.Get("Authorization")}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn(fmt.Sprintf("Failed to decode organization: %s", err))
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func decodeOrg(_ context.Context, r *http.Request) (interface{}, error) {
+	req := orgReq{
+		token: r.Header.Get("Authorization"),
+		id:    bone.GetValue(r, "id"),
+	}
+
+	return req, nil
+}
+
+func decodeAssignOrgMember(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		logger.Warn("Invalid or missing content type.")
+		return nil, errUnsupportedContentType
+	}
+
+	req := orgMemberReq{
+		token: r.Header.Get("Authorization"),
+		id:    bone.GetValue(r, "id"),
+		email: bone.GetValue(r, "email"),
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn(fmt.Sprintf("Failed to decode member: %s", err))
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func decodeOrgMember(_ context.Context, r *http.Request) (interface{}, error) {
+	req := orgMemberReq{
+		token: r.Header.Get("Authorization"),
+		id:    bone.GetValue(r, "id"),
+		email: bone.GetValue(r, "email"),
+	}
+
+	return req, nil
+}
+
 func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
 	w.Header().Set("Content-Type", contentType)
 
@@ -112,12 +528,24 @@ func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 	switch err {
 	case users.ErrMalformedEntity:
 		w.WriteHeader(http.StatusBadRequest)
+	case users.ErrWeakPassword:
+		w.WriteHeader(http.StatusBadRequest)
+	case users.ErrBreachedPassword:
+		w.WriteHeader(http.StatusBadRequest)
 	case users.ErrUnauthorizedAccess:
 		w.WriteHeader(http.StatusForbidden)
+	case users.ErrOTPRequired:
+		w.WriteHeader(http.StatusUnauthorized)
+	case users.ErrNotFound:
+		w.WriteHeader(http.StatusNotFound)
 	case users.ErrConflict:
 		w.WriteHeader(http.StatusConflict)
+	case users.ErrAccountLocked:
+		w.WriteHeader(http.StatusTooManyRequests)
 	case errUnsupportedContentType:
 		w.WriteHeader(http.StatusUnsupportedMediaType)
+	case errInvalidQueryParams:
+		w.WriteHeader(http.StatusBadRequest)
 	case io.ErrUnexpectedEOF:
 		w.WriteHeader(http.StatusBadRequest)
 	case io.EOF:
@@ -133,3 +561,21 @@ func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 		}
 	}
 }
+
+func readUintQuery(r *http.Request, key string, def uint64) (uint64, error) {
+	vals := bone.GetQuery(r, key)
+	if len(vals) > 1 {
+		return 0, errInvalidQueryParams
+	}
+
+	if len(vals) == 0 {
+		return def, nil
+	}
+
+	val, err := strconv.ParseUint(vals[0], 10, 64)
+	if err != nil {
+		return 0, errInvalidQueryParams
+	}
+
+	return val, nil
+}
@@ -38,7 +38,7 @@ func (lm *loggingMiddleware) Register(ctx context.Context, user users.User) (err
 	return lm.svc.Register(ctx, user)
 }
 
-func (lm *loggingMiddleware) Login(ctx context.Context, user users.User) (token string, err error) {
+func (lm *loggingMiddleware) Login(ctx context.Context, user users.User, otp string) (token string, err error) {
 	defer func(begin time.Time) {
 		message := fmt.Sprintf("Method login for user %s took %s to complete", user.Email, time.Since(begin))
 		if err != nil {
@@ -48,7 +48,33 @@ func (lm *loggingMiddleware) Login(ctx context.Context, user users.User) (token
 		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
 	}(time.Now())
 
-	return lm.svc.Login(ctx, user)
+	return lm.svc.Login(ctx, user, otp)
+}
+
+func (lm *loggingMiddleware) EnrollTOTP(ctx context.Context, token string) (secret, uri string, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method enroll_totp took %s to complete", time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.EnrollTOTP(ctx, token)
+}
+
+func (lm *loggingMiddleware) VerifyTOTP(ctx context.Context, token, otp string) (codes []string, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method verify_totp took %s to complete", time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.VerifyTOTP(ctx, token, otp)
 }
 
 func (lm *loggingMiddleware) Identify(key string) (id string, err error) {
@@ -76,3 +102,367 @@ func (lm *loggingMiddleware) UserInfo(ctx context.Context, key string) (u users.
 
 	return lm.svc.UserInfo(ctx, key)
 }
+
+func (lm *loggingMiddleware) ChangePassword(ctx context.Context, token, oldPassword, password string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method change_password took %s to complete", time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ChangePassword(ctx, token, oldPassword, password)
+}
+
+func (lm *loggingMiddleware) Unregister(ctx context.Context, token string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method unregister took %s to complete", time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Unregister(ctx, token)
+}
+
+func (lm *loggingMiddleware) CreateGroup(ctx context.Context, token string, group users.Group) (id string, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method create_group for group %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.CreateGroup(ctx, token, group)
+}
+
+func (lm *loggingMiddleware) ViewGroup(ctx context.Context, token, id string) (_ users.Group, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method view_group for group %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ViewGroup(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) ListGroups(ctx context.Context, token string) (_ []users.Group, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_groups took %s to complete", time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListGroups(ctx, token)
+}
+
+func (lm *loggingMiddleware) RemoveGroup(ctx context.Context, token, id string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method remove_group for group %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RemoveGroup(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) AssignUser(ctx context.Context, token, id, email string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method assign_user for user %s and group %s took %s to complete", email, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.AssignUser(ctx, token, id, email)
+}
+
+func (lm *loggingMiddleware) UnassignUser(ctx context.Context, token, id, email string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method unassign_user for user %s and group %s took %s to complete", email, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.UnassignUser(ctx, token, id, email)
+}
+
+func (lm *loggingMiddleware) IssueKey(ctx context.Context, token string, key users.Key) (issued users.Key, _ string, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method issue_key for key %s took %s to complete", issued.ID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.IssueKey(ctx, token, key)
+}
+
+func (lm *loggingMiddleware) ListKeys(ctx context.Context, token string) (_ []users.Key, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_keys took %s to complete", time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListKeys(ctx, token)
+}
+
+func (lm *loggingMiddleware) RevokeKey(ctx context.Context, token, id string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method revoke_key for key %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RevokeKey(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) Role(ctx context.Context, email string) (role string, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method role for user %s took %s to complete", email, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Role(ctx, email)
+}
+
+func (lm *loggingMiddleware) AssignRole(ctx context.Context, token, email, role string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method assign_role %s for user %s took %s to complete", role, email, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.AssignRole(ctx, token, email, role)
+}
+
+func (lm *loggingMiddleware) ViewRole(ctx context.Context, token, email string) (role string, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method view_role for user %s took %s to complete", email, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ViewRole(ctx, token, email)
+}
+
+func (lm *loggingMiddleware) Authorize(ctx context.Context, token string) (id users.Identity, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method authorize for owner %s took %s to complete", id.ID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Authorize(ctx, token)
+}
+
+func (lm *loggingMiddleware) CreateOrg(ctx context.Context, token string, org users.Org) (id string, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method create_org for org %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.CreateOrg(ctx, token, org)
+}
+
+func (lm *loggingMiddleware) ViewOrg(ctx context.Context, token, id string) (_ users.Org, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method view_org for org %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ViewOrg(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) ListOrgs(ctx context.Context, token string) (_ []users.Org, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_orgs took %s to complete", time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListOrgs(ctx, token)
+}
+
+func (lm *loggingMiddleware) RemoveOrg(ctx context.Context, token, id string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method remove_org for org %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RemoveOrg(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) AssignOrgMember(ctx context.Context, token, id, email, role string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method assign_org_member for org %s and member %s with role %s took %s to complete", id, email, role, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.AssignOrgMember(ctx, token, id, email, role)
+}
+
+func (lm *loggingMiddleware) UnassignOrgMember(ctx context.Context, token, id, email string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method unassign_org_member for org %s and member %s took %s to complete", id, email, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.UnassignOrgMember(ctx, token, id, email)
+}
+
+func (lm *loggingMiddleware) IssueOrgToken(ctx context.Context, token, id string) (_ string, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method issue_org_token for org %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.IssueOrgToken(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) ListUsers(ctx context.Context, token string, offset, limit uint64) (page users.UserPage, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_users for users with offset %d and limit %d took %s to complete", offset, limit, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListUsers(ctx, token, offset, limit)
+}
+
+func (lm *loggingMiddleware) DisableUser(ctx context.Context, token, email string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method disable_user for user %s took %s to complete", email, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.DisableUser(ctx, token, email)
+}
+
+func (lm *loggingMiddleware) EnableUser(ctx context.Context, token, email string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method enable_user for user %s took %s to complete", email, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.EnableUser(ctx, token, email)
+}
+
+func (lm *loggingMiddleware) Disabled(ctx context.Context, email string) (disabled bool, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method disabled for user %s took %s to complete", email, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Disabled(ctx, email)
+}
+
+func (lm *loggingMiddleware) RemoveUser(ctx context.Context, token, email string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method remove_user for user %s took %s to complete", email, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RemoveUser(ctx, token, email)
+}
+
+func (lm *loggingMiddleware) Impersonate(ctx context.Context, token, email string) (_ string, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method impersonate for user %s took %s to complete", email, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Impersonate(ctx, token, email)
+}
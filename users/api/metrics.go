@@ -38,13 +38,31 @@ func (ms *metricsMiddleware) Register(ctx context.Context, user users.User) erro
 	return ms.svc.Register(ctx, user)
 }
 
-func (ms *metricsMiddleware) Login(ctx context.Context, user users.User) (string, error) {
+func (ms *metricsMiddleware) Login(ctx context.Context, user users.User, otp string) (string, error) {
 	defer func(begin time.Time) {
 		ms.counter.With("method", "login").Add(1)
 		ms.latency.With("method", "login").Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
-	return ms.svc.Login(ctx, user)
+	return ms.svc.Login(ctx, user, otp)
+}
+
+func (ms *metricsMiddleware) EnrollTOTP(ctx context.Context, token string) (string, string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "enroll_totp").Add(1)
+		ms.latency.With("method", "enroll_totp").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.EnrollTOTP(ctx, token)
+}
+
+func (ms *metricsMiddleware) VerifyTOTP(ctx context.Context, token, otp string) ([]string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "verify_totp").Add(1)
+		ms.latency.With("method", "verify_totp").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.VerifyTOTP(ctx, token, otp)
 }
 
 func (ms *metricsMiddleware) Identify(key string) (string, error) {
@@ -64,3 +82,255 @@ func (ms *metricsMiddleware) UserInfo(ctx context.Context, key string) (users.Us
 
 	return ms.svc.UserInfo(ctx, key)
 }
+
+func (ms *metricsMiddleware) ChangePassword(ctx context.Context, token, oldPassword, password string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "change_password").Add(1)
+		ms.latency.With("method", "change_password").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ChangePassword(ctx, token, oldPassword, password)
+}
+
+func (ms *metricsMiddleware) Unregister(ctx context.Context, token string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "unregister").Add(1)
+		ms.latency.With("method", "unregister").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Unregister(ctx, token)
+}
+
+func (ms *metricsMiddleware) CreateGroup(ctx context.Context, token string, group users.Group) (string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "create_group").Add(1)
+		ms.latency.With("method", "create_group").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.CreateGroup(ctx, token, group)
+}
+
+func (ms *metricsMiddleware) ViewGroup(ctx context.Context, token, id string) (users.Group, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_group").Add(1)
+		ms.latency.With("method", "view_group").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewGroup(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) ListGroups(ctx context.Context, token string) ([]users.Group, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_groups").Add(1)
+		ms.latency.With("method", "list_groups").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListGroups(ctx, token)
+}
+
+func (ms *metricsMiddleware) RemoveGroup(ctx context.Context, token, id string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "remove_group").Add(1)
+		ms.latency.With("method", "remove_group").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RemoveGroup(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) AssignUser(ctx context.Context, token, id, email string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "assign_user").Add(1)
+		ms.latency.With("method", "assign_user").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.AssignUser(ctx, token, id, email)
+}
+
+func (ms *metricsMiddleware) UnassignUser(ctx context.Context, token, id, email string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "unassign_user").Add(1)
+		ms.latency.With("method", "unassign_user").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.UnassignUser(ctx, token, id, email)
+}
+
+func (ms *metricsMiddleware) IssueKey(ctx context.Context, token string, key users.Key) (users.Key, string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "issue_key").Add(1)
+		ms.latency.With("method", "issue_key").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.IssueKey(ctx, token, key)
+}
+
+func (ms *metricsMiddleware) ListKeys(ctx context.Context, token string) ([]users.Key, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_keys").Add(1)
+		ms.latency.With("method", "list_keys").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListKeys(ctx, token)
+}
+
+func (ms *metricsMiddleware) RevokeKey(ctx context.Context, token, id string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "revoke_key").Add(1)
+		ms.latency.With("method", "revoke_key").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RevokeKey(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) Role(ctx context.Context, email string) (string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "role").Add(1)
+		ms.latency.With("method", "role").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Role(ctx, email)
+}
+
+func (ms *metricsMiddleware) AssignRole(ctx context.Context, token, email, role string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "assign_role").Add(1)
+		ms.latency.With("method", "assign_role").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.AssignRole(ctx, token, email, role)
+}
+
+func (ms *metricsMiddleware) ViewRole(ctx context.Context, token, email string) (string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_role").Add(1)
+		ms.latency.With("method", "view_role").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewRole(ctx, token, email)
+}
+
+func (ms *metricsMiddleware) Authorize(ctx context.Context, token string) (users.Identity, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "authorize").Add(1)
+		ms.latency.With("method", "authorize").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Authorize(ctx, token)
+}
+
+func (ms *metricsMiddleware) CreateOrg(ctx context.Context, token string, org users.Org) (string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "create_org").Add(1)
+		ms.latency.With("method", "create_org").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.CreateOrg(ctx, token, org)
+}
+
+func (ms *metricsMiddleware) ViewOrg(ctx context.Context, token, id string) (users.Org, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_org").Add(1)
+		ms.latency.With("method", "view_org").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewOrg(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) ListOrgs(ctx context.Context, token string) ([]users.Org, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_orgs").Add(1)
+		ms.latency.With("method", "list_orgs").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListOrgs(ctx, token)
+}
+
+func (ms *metricsMiddleware) RemoveOrg(ctx context.Context, token, id string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "remove_org").Add(1)
+		ms.latency.With("method", "remove_org").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RemoveOrg(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) AssignOrgMember(ctx context.Context, token, id, email, role string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "assign_org_member").Add(1)
+		ms.latency.With("method", "assign_org_member").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.AssignOrgMember(ctx, token, id, email, role)
+}
+
+func (ms *metricsMiddleware) UnassignOrgMember(ctx context.Context, token, id, email string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "unassign_org_member").Add(1)
+		ms.latency.With("method", "unassign_org_member").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.UnassignOrgMember(ctx, token, id, email)
+}
+
+func (ms *metricsMiddleware) IssueOrgToken(ctx context.Context, token, id string) (string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "issue_org_token").Add(1)
+		ms.latency.With("method", "issue_org_token").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.IssueOrgToken(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) ListUsers(ctx context.Context, token string, offset, limit uint64) (users.UserPage, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_users").Add(1)
+		ms.latency.With("method", "list_users").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListUsers(ctx, token, offset, limit)
+}
+
+func (ms *metricsMiddleware) DisableUser(ctx context.Context, token, email string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "disable_user").Add(1)
+		ms.latency.With("method", "disable_user").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.DisableUser(ctx, token, email)
+}
+
+func (ms *metricsMiddleware) EnableUser(ctx context.Context, token, email string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "enable_user").Add(1)
+		ms.latency.With("method", "enable_user").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.EnableUser(ctx, token, email)
+}
+
+func (ms *metricsMiddleware) Disabled(ctx context.Context, email string) (bool, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "disabled").Add(1)
+		ms.latency.With("method", "disabled").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Disabled(ctx, email)
+}
+
+func (ms *metricsMiddleware) RemoveUser(ctx context.Context, token, email string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "remove_user").Add(1)
+		ms.latency.With("method", "remove_user").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RemoveUser(ctx, token, email)
+}
+
+func (ms *metricsMiddleware) Impersonate(ctx context.Context, token, email string) (string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "impersonate").Add(1)
+		ms.latency.With("method", "impersonate").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Impersonate(ctx, token, email)
+}
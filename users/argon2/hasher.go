@@ -0,0 +1,141 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package argon2 provides a hasher implementation utilising Argon2id.
+package argon2
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mainflux/mainflux/users"
+	"golang.org/x/crypto/argon2"
+)
+
+const (
+	prefix  = "$argon2id$"
+	saltLen = 16
+	keyLen  = 32
+)
+
+var (
+	errMalformedHash = errors.New("malformed hash")
+	errMismatch      = errors.New("hash and password mismatch")
+)
+
+var _ users.Hasher = (*argon2Hasher)(nil)
+
+// Config defines the Argon2id parameters used to generate new hashes.
+type Config struct {
+	// Time is the number of passes over the memory.
+	Time uint32
+
+	// Memory is the size of the memory in KiB.
+	Memory uint32
+
+	// Threads is the number of threads used to compute the hash.
+	Threads uint8
+}
+
+type argon2Hasher struct {
+	cfg    Config
+	legacy users.Hasher
+}
+
+// New instantiates an Argon2id-based hasher implementation. Hashes that were
+// not generated by Argon2id are compared using the legacy hasher and reported
+// as in need of rehashing, which allows the gradual migration of the stored
+// hashes as users log in.
+func New(cfg Config, legacy users.Hasher) users.Hasher {
+	return &argon2Hasher{
+		cfg:    cfg,
+		legacy: legacy,
+	}
+}
+
+func (ah *argon2Hasher) Hash(pwd string) (string, error) {
+	salt := make([]byte, saltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(pwd), salt, ah.cfg.Time, ah.cfg.Memory, ah.cfg.Threads, keyLen)
+	return encode(ah.cfg, salt, key), nil
+}
+
+func (ah *argon2Hasher) Compare(plain, hashed string) error {
+	if !strings.HasPrefix(hashed, prefix) {
+		if ah.legacy == nil {
+			return errMalformedHash
+		}
+		return ah.legacy.Compare(plain, hashed)
+	}
+
+	cfg, salt, key, err := decode(hashed)
+	if err != nil {
+		return err
+	}
+
+	other := argon2.IDKey([]byte(plain), salt, cfg.Time, cfg.Memory, cfg.Threads, uint32(len(key)))
+	if subtle.ConstantTimeCompare(key, other) != 1 {
+		return errMismatch
+	}
+
+	return nil
+}
+
+func (ah *argon2Hasher) NeedsRehash(hashed string) bool {
+	cfg, _, _, err := decode(hashed)
+	return err != nil || cfg != ah.cfg
+}
+
+// encode formats the hash using the PHC string format, which is understood by
+// the other Argon2 implementations as well.
+func encode(cfg Config, salt, key []byte) string {
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s",
+		prefix,
+		argon2.Version,
+		cfg.Memory,
+		cfg.Time,
+		cfg.Threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	)
+}
+
+func decode(hashed string) (Config, []byte, []byte, error) {
+	if !strings.HasPrefix(hashed, prefix) {
+		return Config{}, nil, nil, errMalformedHash
+	}
+
+	parts := strings.Split(strings.TrimPrefix(hashed, prefix), "$")
+	if len(parts) != 4 {
+		return Config{}, nil, nil, errMalformedHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[0], "v=%d", &version); err != nil || version != argon2.Version {
+		return Config{}, nil, nil, errMalformedHash
+	}
+
+	var cfg Config
+	if _, err := fmt.Sscanf(parts[1], "m=%d,t=%d,p=%d", &cfg.Memory, &cfg.Time, &cfg.Threads); err != nil || cfg.Time == 0 || cfg.Threads == 0 {
+		return Config{}, nil, nil, errMalformedHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return Config{}, nil, nil, errMalformedHash
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[3])
+	if err != nil || len(key) == 0 {
+		return Config{}, nil, nil, errMalformedHash
+	}
+
+	return cfg, salt, key, nil
+}
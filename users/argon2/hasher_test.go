@@ -0,0 +1,110 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package argon2_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mainflux/mainflux/users/argon2"
+	"github.com/mainflux/mainflux/users/bcrypt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const password = "password"
+
+var cfg = argon2.Config{Time: 1, Memory: 1024, Threads: 1}
+
+func TestHash(t *testing.T) {
+	hasher := argon2.New(cfg, nil)
+
+	hash, err := hasher.Hash(password)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=1024,t=1,p=1$"), fmt.Sprintf("unexpected hash format: %s", hash))
+
+	other, err := hasher.Hash(password)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.NotEqual(t, hash, other, "expected hashes of the same password to be salted")
+}
+
+func TestCompare(t *testing.T) {
+	legacy := bcrypt.New()
+	hasher := argon2.New(cfg, legacy)
+
+	hash, err := hasher.Hash(password)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	legacyHash, err := legacy.Hash(password)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		plain  string
+		hashed string
+		valid  bool
+	}{
+		{
+			desc:   "compare valid password",
+			plain:  password,
+			hashed: hash,
+			valid:  true,
+		},
+		{
+			desc:   "compare invalid password",
+			plain:  "wrong",
+			hashed: hash,
+			valid:  false,
+		},
+		{
+			desc:   "compare valid password with legacy hash",
+			plain:  password,
+			hashed: legacyHash,
+			valid:  true,
+		},
+		{
+			desc:   "compare invalid password with legacy hash",
+			plain:  "wrong",
+			hashed: legacyHash,
+			valid:  false,
+		},
+		{
+			desc:   "compare password with malformed hash",
+			plain:  password,
+			hashed: "$argon2id$v=19$m=1024,t=0,p=1$c2FsdA$a2V5",
+			valid:  false,
+		},
+	}
+
+	for _, tc := range cases {
+		err := hasher.Compare(tc.plain, tc.hashed)
+		assert.Equal(t, tc.valid, err == nil, fmt.Sprintf("%s: expected valid %t got %s\n", tc.desc, tc.valid, err))
+	}
+}
+
+func TestNeedsRehash(t *testing.T) {
+	legacy := bcrypt.New()
+	hasher := argon2.New(cfg, legacy)
+
+	hash, err := hasher.Hash(password)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	legacyHash, err := legacy.Hash(password)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	outdatedHash, err := argon2.New(argon2.Config{Time: 2, Memory: 1024, Threads: 1}, nil).Hash(password)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := map[string]struct {
+		hashed string
+		rehash bool
+	}{
+		"hash with current parameters":  {hash, false},
+		"hash with outdated parameters": {outdatedHash, true},
+		"legacy hash":                   {legacyHash, true},
+	}
+
+	for desc, tc := range cases {
+		rehash := hasher.NeedsRehash(tc.hashed)
+		assert.Equal(t, tc.rehash, rehash, fmt.Sprintf("%s: expected %t got %t\n", desc, tc.rehash, rehash))
+	}
+}
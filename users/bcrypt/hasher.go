@@ -32,3 +32,8 @@ func (bh *bcryptHasher) Hash(pwd string) (string, error) {
 func (bh *bcryptHasher) Compare(plain, hashed string) error {
 	return bcrypt.CompareHashAndPassword([]byte(hashed), []byte(plain))
 }
+
+func (bh *bcryptHasher) NeedsRehash(hashed string) bool {
+	c, err := bcrypt.Cost([]byte(hashed))
+	return err != nil || c != cost
+}
@@ -0,0 +1,53 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package users
+
+import "context"
+
+const maxNameSize = 1024
+
+// Group represents a named set of users. Each group is owned by the user
+// that created it, who is also its first member.
+type Group struct {
+	ID      string
+	Owner   string
+	Name    string
+	Members []string
+}
+
+// Validate returns an error if group representation is invalid.
+func (g Group) Validate() error {
+	if g.Name == "" || len(g.Name) > maxNameSize {
+		return ErrMalformedEntity
+	}
+
+	return nil
+}
+
+// GroupRepository specifies a group persistence API.
+type GroupRepository interface {
+	// Save persists the group and assigns its owner as the first member. A
+	// non-nil error is returned to indicate operation failure.
+	Save(context.Context, Group) error
+
+	// RetrieveByID retrieves the group having the provided identifier,
+	// together with its members.
+	RetrieveByID(context.Context, string) (Group, error)
+
+	// RetrieveAll retrieves all the groups the specified user is member of.
+	// Members of the retrieved groups are not populated.
+	RetrieveAll(context.Context, string) ([]Group, error)
+
+	// Remove removes the group having the provided identifier, that is
+	// owned by the specified user.
+	Remove(context.Context, string, string) error
+
+	// AssignMember adds the user to the group having the provided
+	// identifier.
+	AssignMember(context.Context, string, string) error
+
+	// UnassignMember removes the user from the group having the provided
+	// identifier.
+	UnassignMember(context.Context, string, string) error
+}
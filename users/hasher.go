@@ -12,4 +12,8 @@ type Hasher interface {
 	// Compare compares plain-text version to the hashed one. An error should
 	// indicate failed comparison.
 	Compare(string, string) error
+
+	// NeedsRehash checks whether the hashed string was generated using an
+	// outdated algorithm or parameters, and should be hashed anew.
+	NeedsRehash(string) bool
 }
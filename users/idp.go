@@ -12,3 +12,9 @@ type IdentityProvider interface {
 	// Identity extracts the entity identifier given its secret key.
 	Identity(string) (string, error)
 }
+
+// IDProvider specifies an API for generating unique identifiers.
+type IDProvider interface {
+	// ID generates the unique identifier.
+	ID() (string, error)
+}
@@ -0,0 +1,94 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package users
+
+import (
+	"context"
+	"time"
+)
+
+// KeyPrefix prefixes the personal access tokens, which distinguishes them
+// from the JWT login tokens. The token consists of the prefix, the key ID and
+// the secret separated by the dot.
+const KeyPrefix = "mfpat_"
+
+// Scopes of the personal access tokens.
+const (
+	// ScopeUsers allows viewing the account of the key owner.
+	ScopeUsers = "users"
+
+	// ScopeGroups allows managing the groups of the key owner.
+	ScopeGroups = "groups"
+
+	// ScopeServices allows accessing the other Mainflux services, such as
+	// things, on behalf of the key owner.
+	ScopeServices = "services"
+)
+
+var scopes = map[string]bool{
+	ScopeUsers:    true,
+	ScopeGroups:   true,
+	ScopeServices: true,
+}
+
+// Key represents the personal access token of the user. Only the hash of the
+// key secret is persisted, so the token is revealed only on key issuing. Key
+// without expiration time never expires.
+type Key struct {
+	ID        string
+	Owner     string
+	Name      string
+	Scopes    []string
+	Secret    string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
+
+// Validate returns an error if key representation is invalid.
+func (k Key) Validate() error {
+	if k.Name == "" || len(k.Name) > maxNameSize || len(k.Scopes) == 0 {
+		return ErrMalformedEntity
+	}
+
+	for _, s := range k.Scopes {
+		if !scopes[s] {
+			return ErrMalformedEntity
+		}
+	}
+
+	return nil
+}
+
+// Expired determines whether the key expired at the provided time.
+func (k Key) Expired(now time.Time) bool {
+	return !k.ExpiresAt.IsZero() && !now.Before(k.ExpiresAt)
+}
+
+// HasScope determines whether the key is granted the provided scope.
+func (k Key) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+// KeyRepository specifies a personal access token persistence API.
+type KeyRepository interface {
+	// Save persists the key. A non-nil error is returned to indicate
+	// operation failure.
+	Save(context.Context, Key) error
+
+	// RetrieveByID retrieves the key having the provided identifier.
+	RetrieveByID(context.Context, string) (Key, error)
+
+	// RetrieveAll retrieves all the keys of the specified user.
+	RetrieveAll(context.Context, string) ([]Key, error)
+
+	// Remove removes the key having the provided identifier, that is owned
+	// by the specified user.
+	Remove(context.Context, string, string) error
+}
@@ -0,0 +1,63 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package users
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrAccountLocked indicates that the account is temporarily locked due to
+// too many failed logins.
+var ErrAccountLocked = errors.New("account temporarily locked")
+
+// LockoutPolicy specifies when the accounts are locked due to the failed
+// logins. After Attempts consecutive failed logins the account is locked
+// for Duration, and each further failed login doubles the lock duration up
+// to MaxDuration. Zero Attempts disables the lockout.
+type LockoutPolicy struct {
+	Attempts    uint64
+	Duration    time.Duration
+	MaxDuration time.Duration
+}
+
+// lockDuration returns the duration the account is locked for after the
+// provided number of consecutive failed logins.
+func (lp LockoutPolicy) lockDuration(failures uint64) time.Duration {
+	if lp.Attempts == 0 || failures < lp.Attempts {
+		return 0
+	}
+
+	d := lp.Duration
+	for i := lp.Attempts; i < failures && d < lp.MaxDuration; i++ {
+		d *= 2
+	}
+
+	if d > lp.MaxDuration {
+		return lp.MaxDuration
+	}
+
+	return d
+}
+
+// LoginAttempts specifies the API for tracking the failed logins.
+type LoginAttempts interface {
+	// Fail records the failed login for the provided email and returns the
+	// number of consecutive failed logins. The record expires after the
+	// provided duration without further failed logins.
+	Fail(ctx context.Context, email string, ttl time.Duration) (uint64, error)
+
+	// Lock locks the account with the provided email for the provided
+	// duration.
+	Lock(ctx context.Context, email string, d time.Duration) error
+
+	// Locked returns the remaining lock duration of the account with the
+	// provided email, or zero if the account isn't locked.
+	Locked(ctx context.Context, email string) (time.Duration, error)
+
+	// Reset removes the failed logins and the lock of the account with the
+	// provided email.
+	Reset(ctx context.Context, email string) error
+}
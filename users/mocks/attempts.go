@@ -0,0 +1,66 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/mainflux/mainflux/users"
+)
+
+var _ users.LoginAttempts = (*loginAttemptsMock)(nil)
+
+type loginAttemptsMock struct {
+	mu       sync.Mutex
+	failures map[string]uint64
+	locks    map[string]time.Time
+}
+
+// NewLoginAttempts creates in-memory login attempts tracker. Expiration of
+// the failed logins isn't simulated.
+func NewLoginAttempts() users.LoginAttempts {
+	return &loginAttemptsMock{
+		failures: make(map[string]uint64),
+		locks:    make(map[string]time.Time),
+	}
+}
+
+func (lam *loginAttemptsMock) Fail(_ context.Context, email string, _ time.Duration) (uint64, error) {
+	lam.mu.Lock()
+	defer lam.mu.Unlock()
+
+	lam.failures[email]++
+	return lam.failures[email], nil
+}
+
+func (lam *loginAttemptsMock) Lock(_ context.Context, email string, d time.Duration) error {
+	lam.mu.Lock()
+	defer lam.mu.Unlock()
+
+	lam.locks[email] = time.Now().Add(d)
+	return nil
+}
+
+func (lam *loginAttemptsMock) Locked(_ context.Context, email string) (time.Duration, error) {
+	lam.mu.Lock()
+	defer lam.mu.Unlock()
+
+	d := time.Until(lam.locks[email])
+	if d < 0 {
+		return 0, nil
+	}
+
+	return d, nil
+}
+
+func (lam *loginAttemptsMock) Reset(_ context.Context, email string) error {
+	lam.mu.Lock()
+	defer lam.mu.Unlock()
+
+	delete(lam.failures, email)
+	delete(lam.locks, email)
+	return nil
+}
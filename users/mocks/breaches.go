@@ -0,0 +1,31 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+
+	"github.com/mainflux/mainflux/users"
+)
+
+var _ users.BreachChecker = (*breachCheckerMock)(nil)
+
+type breachCheckerMock struct {
+	breached map[string]bool
+}
+
+// NewBreachChecker creates breach checker which reports the provided
+// passwords as breached.
+func NewBreachChecker(passwords ...string) users.BreachChecker {
+	breached := make(map[string]bool, len(passwords))
+	for _, pwd := range passwords {
+		breached[pwd] = true
+	}
+
+	return breachCheckerMock{breached: breached}
+}
+
+func (bcm breachCheckerMock) Breached(_ context.Context, password string) (bool, error) {
+	return bcm.breached[password], nil
+}
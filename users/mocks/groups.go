@@ -0,0 +1,119 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/mainflux/mainflux/users"
+)
+
+var _ users.GroupRepository = (*groupRepositoryMock)(nil)
+
+type groupRepositoryMock struct {
+	mu      sync.Mutex
+	groups  map[string]users.Group
+	members map[string]map[string]bool
+}
+
+// NewGroupRepository creates in-memory group repository.
+func NewGroupRepository() users.GroupRepository {
+	return &groupRepositoryMock{
+		groups:  make(map[string]users.Group),
+		members: make(map[string]map[string]bool),
+	}
+}
+
+func (grm *groupRepositoryMock) Save(_ context.Context, group users.Group) error {
+	grm.mu.Lock()
+	defer grm.mu.Unlock()
+
+	if _, ok := grm.groups[group.ID]; ok {
+		return users.ErrConflict
+	}
+
+	grm.groups[group.ID] = group
+	grm.members[group.ID] = map[string]bool{group.Owner: true}
+	return nil
+}
+
+func (grm *groupRepositoryMock) RetrieveByID(_ context.Context, id string) (users.Group, error) {
+	grm.mu.Lock()
+	defer grm.mu.Unlock()
+
+	group, ok := grm.groups[id]
+	if !ok {
+		return users.Group{}, users.ErrNotFound
+	}
+
+	group.Members = []string{}
+	for m := range grm.members[id] {
+		group.Members = append(group.Members, m)
+	}
+	sort.Strings(group.Members)
+
+	return group, nil
+}
+
+func (grm *groupRepositoryMock) RetrieveAll(_ context.Context, member string) ([]users.Group, error) {
+	grm.mu.Lock()
+	defer grm.mu.Unlock()
+
+	groups := []users.Group{}
+	for id, members := range grm.members {
+		if members[member] {
+			groups = append(groups, grm.groups[id])
+		}
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].ID < groups[j].ID
+	})
+
+	return groups, nil
+}
+
+func (grm *groupRepositoryMock) Remove(_ context.Context, owner, id string) error {
+	grm.mu.Lock()
+	defer grm.mu.Unlock()
+
+	if group, ok := grm.groups[id]; ok && group.Owner == owner {
+		delete(grm.groups, id)
+		delete(grm.members, id)
+	}
+
+	return nil
+}
+
+func (grm *groupRepositoryMock) AssignMember(_ context.Context, id, member string) error {
+	grm.mu.Lock()
+	defer grm.mu.Unlock()
+
+	members, ok := grm.members[id]
+	if !ok {
+		return users.ErrNotFound
+	}
+
+	if members[member] {
+		return users.ErrConflict
+	}
+
+	members[member] = true
+	return nil
+}
+
+func (grm *groupRepositoryMock) UnassignMember(_ context.Context, id, member string) error {
+	grm.mu.Lock()
+	defer grm.mu.Unlock()
+
+	members, ok := grm.members[id]
+	if !ok || !members[member] {
+		return users.ErrNotFound
+	}
+
+	delete(members, member)
+	return nil
+}
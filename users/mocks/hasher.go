@@ -29,3 +29,7 @@ func (hm *hasherMock) Compare(plain, hashed string) error {
 
 	return nil
 }
+
+func (hm *hasherMock) NeedsRehash(hashed string) bool {
+	return false
+}
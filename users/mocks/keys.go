@@ -0,0 +1,81 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/mainflux/mainflux/users"
+)
+
+var _ users.KeyRepository = (*keyRepositoryMock)(nil)
+
+type keyRepositoryMock struct {
+	mu   sync.Mutex
+	keys map[string]users.Key
+}
+
+// NewKeyRepository creates in-memory key repository.
+func NewKeyRepository() users.KeyRepository {
+	return &keyRepositoryMock{
+		keys: make(map[string]users.Key),
+	}
+}
+
+func (krm *keyRepositoryMock) Save(_ context.Context, key users.Key) error {
+	krm.mu.Lock()
+	defer krm.mu.Unlock()
+
+	if _, ok := krm.keys[key.ID]; ok {
+		return users.ErrConflict
+	}
+
+	krm.keys[key.ID] = key
+	return nil
+}
+
+func (krm *keyRepositoryMock) RetrieveByID(_ context.Context, id string) (users.Key, error) {
+	krm.mu.Lock()
+	defer krm.mu.Unlock()
+
+	key, ok := krm.keys[id]
+	if !ok {
+		return users.Key{}, users.ErrNotFound
+	}
+
+	return key, nil
+}
+
+func (krm *keyRepositoryMock) RetrieveAll(_ context.Context, owner string) ([]users.Key, error) {
+	krm.mu.Lock()
+	defer krm.mu.Unlock()
+
+	keys := []users.Key{}
+	for _, k := range krm.keys {
+		if k.Owner == owner {
+			k.Secret = ""
+			keys = append(keys, k)
+		}
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].ID < keys[j].ID
+	})
+
+	return keys, nil
+}
+
+func (krm *keyRepositoryMock) Remove(_ context.Context, owner, id string) error {
+	krm.mu.Lock()
+	defer krm.mu.Unlock()
+
+	if key, ok := krm.keys[id]; !ok || key.Owner != owner {
+		return users.ErrNotFound
+	}
+
+	delete(krm.keys, id)
+	return nil
+}
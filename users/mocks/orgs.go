@@ -0,0 +1,133 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/mainflux/mainflux/users"
+)
+
+var _ users.OrgRepository = (*orgRepositoryMock)(nil)
+
+type orgRepositoryMock struct {
+	mu      sync.Mutex
+	orgs    map[string]users.Org
+	members map[string]map[string]string
+}
+
+// NewOrgRepository creates in-memory organization repository.
+func NewOrgRepository() users.OrgRepository {
+	return &orgRepositoryMock{
+		orgs:    make(map[string]users.Org),
+		members: make(map[string]map[string]string),
+	}
+}
+
+func (orm *orgRepositoryMock) Save(_ context.Context, org users.Org) error {
+	orm.mu.Lock()
+	defer orm.mu.Unlock()
+
+	if _, ok := orm.orgs[org.ID]; ok {
+		return users.ErrConflict
+	}
+
+	orm.orgs[org.ID] = org
+	orm.members[org.ID] = map[string]string{org.Owner: users.RoleAdmin}
+	return nil
+}
+
+func (orm *orgRepositoryMock) RetrieveByID(_ context.Context, id string) (users.Org, error) {
+	orm.mu.Lock()
+	defer orm.mu.Unlock()
+
+	org, ok := orm.orgs[id]
+	if !ok {
+		return users.Org{}, users.ErrNotFound
+	}
+
+	org.Members = []users.Member{}
+	for email, role := range orm.members[id] {
+		org.Members = append(org.Members, users.Member{Email: email, Role: role})
+	}
+	sort.Slice(org.Members, func(i, j int) bool {
+		return org.Members[i].Email < org.Members[j].Email
+	})
+
+	return org, nil
+}
+
+func (orm *orgRepositoryMock) RetrieveAll(_ context.Context, email string) ([]users.Org, error) {
+	orm.mu.Lock()
+	defer orm.mu.Unlock()
+
+	orgs := []users.Org{}
+	for id, members := range orm.members {
+		if _, ok := members[email]; ok {
+			orgs = append(orgs, orm.orgs[id])
+		}
+	}
+
+	sort.Slice(orgs, func(i, j int) bool {
+		return orgs[i].ID < orgs[j].ID
+	})
+
+	return orgs, nil
+}
+
+func (orm *orgRepositoryMock) Remove(_ context.Context, owner, id string) error {
+	orm.mu.Lock()
+	defer orm.mu.Unlock()
+
+	if org, ok := orm.orgs[id]; ok && org.Owner == owner {
+		delete(orm.orgs, id)
+		delete(orm.members, id)
+	}
+
+	return nil
+}
+
+func (orm *orgRepositoryMock) SaveMember(_ context.Context, id, email, role string) error {
+	orm.mu.Lock()
+	defer orm.mu.Unlock()
+
+	members, ok := orm.members[id]
+	if !ok {
+		return users.ErrNotFound
+	}
+
+	members[email] = role
+	return nil
+}
+
+func (orm *orgRepositoryMock) RemoveMember(_ context.Context, id, email string) error {
+	orm.mu.Lock()
+	defer orm.mu.Unlock()
+
+	members, ok := orm.members[id]
+	if !ok {
+		return users.ErrNotFound
+	}
+
+	if _, ok := members[email]; !ok {
+		return users.ErrNotFound
+	}
+
+	delete(members, email)
+	return nil
+}
+
+func (orm *orgRepositoryMock) RetrieveRole(_ context.Context, id, email string) (string, error) {
+	orm.mu.Lock()
+	defer orm.mu.Unlock()
+
+	role, ok := orm.members[id][email]
+	if !ok {
+		return "", users.ErrNotFound
+	}
+
+	return role, nil
+}
@@ -0,0 +1,57 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mainflux/mainflux/users"
+)
+
+var _ users.OTPProvider = (*otpProviderMock)(nil)
+
+type otpProviderMock struct {
+	mu      sync.Mutex
+	counter int
+}
+
+// NewOTPProvider creates "mirror" one-time password provider, i.e. the
+// secret itself is the valid password of the first period, while the secret
+// suffixed with the period, e.g. "000001-2", is the password of the others.
+func NewOTPProvider() users.OTPProvider {
+	return &otpProviderMock{}
+}
+
+func (opm *otpProviderMock) Secret() (string, error) {
+	opm.mu.Lock()
+	defer opm.mu.Unlock()
+
+	opm.counter++
+	return fmt.Sprintf("%06d", opm.counter), nil
+}
+
+func (opm *otpProviderMock) URI(secret, account string) string {
+	return fmt.Sprintf("otpauth://totp/%s?secret=%s", account, secret)
+}
+
+func (opm *otpProviderMock) Validate(secret, otp string, _ time.Time) (int64, bool) {
+	if otp == secret {
+		return 1, true
+	}
+
+	if !strings.HasPrefix(otp, secret+"-") {
+		return 0, false
+	}
+
+	counter, err := strconv.ParseInt(strings.TrimPrefix(otp, secret+"-"), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return counter, true
+}
@@ -0,0 +1,45 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mainflux/mainflux/users"
+)
+
+var _ users.RoleRepository = (*roleRepositoryMock)(nil)
+
+type roleRepositoryMock struct {
+	mu    sync.Mutex
+	roles map[string]string
+}
+
+// NewRoleRepository creates in-memory role repository.
+func NewRoleRepository() users.RoleRepository {
+	return &roleRepositoryMock{
+		roles: make(map[string]string),
+	}
+}
+
+func (rrm *roleRepositoryMock) Save(_ context.Context, email, role string) error {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	rrm.roles[email] = role
+	return nil
+}
+
+func (rrm *roleRepositoryMock) RetrieveByID(_ context.Context, email string) (string, error) {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	role, ok := rrm.roles[email]
+	if !ok {
+		return "", users.ErrNotFound
+	}
+
+	return role, nil
+}
@@ -0,0 +1,95 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mainflux/mainflux/users"
+)
+
+var _ users.TOTPRepository = (*totpRepositoryMock)(nil)
+
+type totpRepositoryMock struct {
+	mu       sync.Mutex
+	totps    map[string]users.TOTP
+	codes    map[string]map[string]bool
+	counters map[string]int64
+}
+
+// NewTOTPRepository creates in-memory second factor repository.
+func NewTOTPRepository() users.TOTPRepository {
+	return &totpRepositoryMock{
+		totps:    make(map[string]users.TOTP),
+		codes:    make(map[string]map[string]bool),
+		counters: make(map[string]int64),
+	}
+}
+
+func (trm *totpRepositoryMock) Save(_ context.Context, totp users.TOTP) error {
+	trm.mu.Lock()
+	defer trm.mu.Unlock()
+
+	trm.totps[totp.Email] = totp
+	delete(trm.codes, totp.Email)
+	delete(trm.counters, totp.Email)
+	return nil
+}
+
+func (trm *totpRepositoryMock) RetrieveByID(_ context.Context, email string) (users.TOTP, error) {
+	trm.mu.Lock()
+	defer trm.mu.Unlock()
+
+	totp, ok := trm.totps[email]
+	if !ok {
+		return users.TOTP{}, users.ErrNotFound
+	}
+
+	return totp, nil
+}
+
+func (trm *totpRepositoryMock) Enable(_ context.Context, email string, codes []string) error {
+	trm.mu.Lock()
+	defer trm.mu.Unlock()
+
+	totp, ok := trm.totps[email]
+	if !ok {
+		return users.ErrNotFound
+	}
+
+	totp.Enabled = true
+	trm.totps[email] = totp
+
+	trm.codes[email] = make(map[string]bool, len(codes))
+	for _, code := range codes {
+		trm.codes[email][code] = true
+	}
+
+	return nil
+}
+
+func (trm *totpRepositoryMock) RemoveRecoveryCode(_ context.Context, email, code string) error {
+	trm.mu.Lock()
+	defer trm.mu.Unlock()
+
+	if !trm.codes[email][code] {
+		return users.ErrNotFound
+	}
+
+	delete(trm.codes[email], code)
+	return nil
+}
+
+func (trm *totpRepositoryMock) UpdateCounter(_ context.Context, email string, counter int64) error {
+	trm.mu.Lock()
+	defer trm.mu.Unlock()
+
+	if _, ok := trm.totps[email]; !ok || counter <= trm.counters[email] {
+		return users.ErrNotFound
+	}
+
+	trm.counters[email] = counter
+	return nil
+}
@@ -5,6 +5,7 @@ package mocks
 
 import (
 	"context"
+	"sort"
 	"sync"
 
 	"github.com/mainflux/mainflux/users"
@@ -47,3 +48,77 @@ func (urm *userRepositoryMock) RetrieveByID(ctx context.Context, email string) (
 
 	return val, nil
 }
+
+func (urm *userRepositoryMock) RetrieveAll(ctx context.Context, offset, limit uint64) (users.UserPage, error) {
+	urm.mu.Lock()
+	defer urm.mu.Unlock()
+
+	items := []users.User{}
+	for _, user := range urm.users {
+		user.Password = ""
+		items = append(items, user)
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Email < items[j].Email
+	})
+
+	page := users.UserPage{
+		Total:  uint64(len(items)),
+		Offset: offset,
+		Limit:  limit,
+		Users:  []users.User{},
+	}
+
+	if offset >= uint64(len(items)) {
+		return page, nil
+	}
+
+	end := offset + limit
+	if end > uint64(len(items)) {
+		end = uint64(len(items))
+	}
+
+	page.Users = items[offset:end]
+	return page, nil
+}
+
+func (urm *userRepositoryMock) UpdateDisabled(ctx context.Context, email string, disabled bool) error {
+	urm.mu.Lock()
+	defer urm.mu.Unlock()
+
+	user, ok := urm.users[email]
+	if !ok {
+		return users.ErrNotFound
+	}
+
+	user.Disabled = disabled
+	urm.users[email] = user
+	return nil
+}
+
+func (urm *userRepositoryMock) UpdatePassword(ctx context.Context, email, password string) error {
+	urm.mu.Lock()
+	defer urm.mu.Unlock()
+
+	user, ok := urm.users[email]
+	if !ok {
+		return users.ErrNotFound
+	}
+
+	user.Password = password
+	urm.users[email] = user
+	return nil
+}
+
+func (urm *userRepositoryMock) Remove(ctx context.Context, email string) error {
+	urm.mu.Lock()
+	defer urm.mu.Unlock()
+
+	if _, ok := urm.users[email]; !ok {
+		return users.ErrNotFound
+	}
+
+	delete(urm.users, email)
+	return nil
+}
@@ -0,0 +1,31 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/mainflux/mainflux/users"
+)
+
+var _ users.IDProvider = (*idProviderMock)(nil)
+
+type idProviderMock struct {
+	mu      sync.Mutex
+	counter int
+}
+
+// NewIDProvider creates ID provider that generates incremental UUIDs.
+func NewIDProvider() users.IDProvider {
+	return &idProviderMock{}
+}
+
+func (idp *idProviderMock) ID() (string, error) {
+	idp.mu.Lock()
+	defer idp.mu.Unlock()
+
+	idp.counter++
+	return fmt.Sprintf("%s%012d", "123e4567-e89b-12d3-a456-", idp.counter), nil
+}
@@ -0,0 +1,90 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package users
+
+import (
+	"context"
+	"strings"
+)
+
+// orgSeparator separates the organization ID from the member's email in the
+// subject of the organization tokens. Since it isn't allowed in the local
+// part of the email, personal tokens can't be mistaken for organization ones.
+const orgSeparator = ":"
+
+// Org represents an organization, i.e. a workspace which owns things and
+// channels on behalf of its members. The user that created the organization
+// is its owner and its first admin. The other services don't store the
+// organization separately, but use its ID as the owner of the entities
+// created with the organization tokens, instead of the member's email.
+type Org struct {
+	ID      string
+	Owner   string
+	Name    string
+	Members []Member
+}
+
+// Member represents the membership of a user in an organization.
+type Member struct {
+	Email string
+	Role  string
+}
+
+// Validate returns an error if organization representation is invalid.
+func (o Org) Validate() error {
+	if o.Name == "" || len(o.Name) > maxNameSize {
+		return ErrMalformedEntity
+	}
+
+	return nil
+}
+
+// OrgRepository specifies an organization persistence API.
+type OrgRepository interface {
+	// Save persists the organization and assigns its owner as the first
+	// member, having the admin role.
+	Save(context.Context, Org) error
+
+	// RetrieveByID retrieves the organization having the provided
+	// identifier, together with its members.
+	RetrieveByID(context.Context, string) (Org, error)
+
+	// RetrieveAll retrieves all the organizations the specified user is
+	// member of. Members of the retrieved organizations are not populated.
+	RetrieveAll(context.Context, string) ([]Org, error)
+
+	// Remove removes the organization having the provided identifier, that
+	// is owned by the specified user.
+	Remove(ctx context.Context, owner, id string) error
+
+	// SaveMember adds the user to the organization having the provided
+	// identifier, or replaces the role of the existing member.
+	SaveMember(ctx context.Context, id, email, role string) error
+
+	// RemoveMember removes the user from the organization having the
+	// provided identifier.
+	RemoveMember(ctx context.Context, id, email string) error
+
+	// RetrieveRole retrieves the role of the member of the organization
+	// having the provided identifier. If the user isn't its member,
+	// ErrNotFound is returned.
+	RetrieveRole(ctx context.Context, id, email string) (string, error)
+}
+
+// orgSubject returns the subject of the organization token.
+func orgSubject(id, email string) string {
+	return id + orgSeparator + email
+}
+
+// parseSubject splits the subject of the organization token into the
+// organization ID and the member's email. Subjects of the personal tokens
+// are returned as the email only.
+func parseSubject(subject string) (string, string) {
+	parts := strings.SplitN(subject, orgSeparator, 2)
+	if len(parts) != 2 {
+		return "", subject
+	}
+
+	return parts[0], parts[1]
+}
@@ -0,0 +1,69 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package users
+
+import (
+	"context"
+	"errors"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	// ErrWeakPassword indicates that the password doesn't satisfy the
+	// password policy.
+	ErrWeakPassword = errors.New("password does not satisfy the password policy")
+
+	// ErrBreachedPassword indicates that the password appeared in a known
+	// data breach.
+	ErrBreachedPassword = errors.New("password appeared in a data breach")
+)
+
+// PasswordPolicy represents the complexity rules the passwords have to
+// satisfy. The zero value accepts any non-empty password.
+type PasswordPolicy struct {
+	MinLength    int
+	RequireUpper bool
+	RequireLower bool
+	RequireDigit bool
+	// RequireSymbol requires a character that is neither a letter nor a
+	// digit, e.g. punctuation or whitespace.
+	RequireSymbol bool
+}
+
+// Validate returns an error if the password doesn't satisfy the policy.
+func (pp PasswordPolicy) Validate(password string) error {
+	if utf8.RuneCountInString(password) < pp.MinLength {
+		return ErrWeakPassword
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case !unicode.IsLetter(r):
+			symbol = true
+		}
+	}
+
+	if (pp.RequireUpper && !upper) || (pp.RequireLower && !lower) ||
+		(pp.RequireDigit && !digit) || (pp.RequireSymbol && !symbol) {
+		return ErrWeakPassword
+	}
+
+	return nil
+}
+
+// BreachChecker specifies the API for checking the passwords against the
+// known data breaches.
+type BreachChecker interface {
+	// Breached reports whether the password appeared in a known data
+	// breach.
+	Breached(context.Context, string) (bool, error)
+}
@@ -0,0 +1,74 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package users_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux/users"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidatePassword(t *testing.T) {
+	policy := users.PasswordPolicy{
+		MinLength:     8,
+		RequireUpper:  true,
+		RequireLower:  true,
+		RequireDigit:  true,
+		RequireSymbol: true,
+	}
+
+	cases := map[string]struct {
+		policy   users.PasswordPolicy
+		password string
+		err      error
+	}{
+		"validate password against empty policy": {
+			policy:   users.PasswordPolicy{},
+			password: "a",
+			err:      nil,
+		},
+		"validate password satisfying policy": {
+			policy:   policy,
+			password: "Pa$$w0rd",
+			err:      nil,
+		},
+		"validate too short password": {
+			policy:   policy,
+			password: "Pa$w0rd",
+			err:      users.ErrWeakPassword,
+		},
+		"validate password counting characters instead of bytes": {
+			policy:   users.PasswordPolicy{MinLength: 4},
+			password: "ššš",
+			err:      users.ErrWeakPassword,
+		},
+		"validate password without upper case letter": {
+			policy:   policy,
+			password: "pa$$w0rd",
+			err:      users.ErrWeakPassword,
+		},
+		"validate password without lower case letter": {
+			policy:   policy,
+			password: "PA$$W0RD",
+			err:      users.ErrWeakPassword,
+		},
+		"validate password without digit": {
+			policy:   policy,
+			password: "Pa$$word",
+			err:      users.ErrWeakPassword,
+		},
+		"validate password without symbol": {
+			policy:   policy,
+			password: "Passw0rd",
+			err:      users.ErrWeakPassword,
+		},
+	}
+
+	for desc, tc := range cases {
+		err := tc.policy.Validate(tc.password)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s", desc, tc.err, err))
+	}
+}
@@ -0,0 +1,207 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+	"github.com/mainflux/mainflux/users"
+)
+
+var _ users.GroupRepository = (*groupRepository)(nil)
+
+const (
+	errFK      = "foreign_key_violation"
+	errInvalid = "invalid_text_representation"
+)
+
+type groupRepository struct {
+	db Database
+}
+
+// NewGroupRepository instantiates a PostgreSQL implementation of group
+// repository.
+func NewGroupRepository(db Database) users.GroupRepository {
+	return &groupRepository{
+		db: db,
+	}
+}
+
+func (gr groupRepository) Save(ctx context.Context, group users.Group) error {
+	// The owner is assigned in the same statement, so the group never
+	// exists without members.
+	q := `WITH g AS (
+		      INSERT INTO groups (id, owner, name) VALUES (:id, :owner, :name) RETURNING id, owner
+		  )
+		  INSERT INTO group_members (group_id, member) SELECT id, owner FROM g`
+
+	dbg := dbGroup{
+		ID:    group.ID,
+		Owner: group.Owner,
+		Name:  group.Name,
+	}
+
+	if _, err := gr.db.NamedExecContext(ctx, q, dbg); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code.Name() {
+			case errDuplicate:
+				return users.ErrConflict
+			case errInvalid:
+				return users.ErrMalformedEntity
+			case errFK:
+				return users.ErrNotFound
+			}
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (gr groupRepository) RetrieveByID(ctx context.Context, id string) (users.Group, error) {
+	q := `SELECT id, owner, name FROM groups WHERE id = $1`
+
+	var dbg dbGroup
+	if err := gr.db.QueryRowxContext(ctx, q, id).StructScan(&dbg); err != nil {
+		if err == sql.ErrNoRows {
+			return users.Group{}, users.ErrNotFound
+		}
+		if pqErr, ok := err.(*pq.Error); ok && errInvalid == pqErr.Code.Name() {
+			return users.Group{}, users.ErrNotFound
+		}
+		return users.Group{}, err
+	}
+
+	q = `SELECT member FROM group_members WHERE group_id = :id ORDER BY member`
+
+	rows, err := gr.db.NamedQueryContext(ctx, q, map[string]interface{}{"id": id})
+	if err != nil {
+		return users.Group{}, err
+	}
+	defer rows.Close()
+
+	group := toGroup(dbg)
+	for rows.Next() {
+		var member string
+		if err := rows.Scan(&member); err != nil {
+			return users.Group{}, err
+		}
+		group.Members = append(group.Members, member)
+	}
+
+	return group, nil
+}
+
+func (gr groupRepository) RetrieveAll(ctx context.Context, member string) ([]users.Group, error) {
+	q := `SELECT g.id, g.owner, g.name FROM groups g
+		  INNER JOIN group_members m ON m.group_id = g.id
+		  WHERE m.member = :member ORDER BY g.id`
+
+	rows, err := gr.db.NamedQueryContext(ctx, q, map[string]interface{}{"member": member})
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	groups := []users.Group{}
+	for rows.Next() {
+		var dbg dbGroup
+		if err := rows.StructScan(&dbg); err != nil {
+			return nil, err
+		}
+		groups = append(groups, toGroup(dbg))
+	}
+
+	return groups, nil
+}
+
+func (gr groupRepository) Remove(ctx context.Context, owner, id string) error {
+	q := `DELETE FROM groups WHERE id = :id AND owner = :owner`
+
+	dbg := dbGroup{
+		ID:    id,
+		Owner: owner,
+	}
+
+	if _, err := gr.db.NamedExecContext(ctx, q, dbg); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && errInvalid == pqErr.Code.Name() {
+			return users.ErrNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (gr groupRepository) AssignMember(ctx context.Context, id, member string) error {
+	q := `INSERT INTO group_members (group_id, member) VALUES (:group_id, :member)`
+
+	dbm := dbMember{
+		GroupID: id,
+		Member:  member,
+	}
+
+	if _, err := gr.db.NamedExecContext(ctx, q, dbm); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code.Name() {
+			case errDuplicate:
+				return users.ErrConflict
+			case errFK, errInvalid:
+				return users.ErrNotFound
+			}
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (gr groupRepository) UnassignMember(ctx context.Context, id, member string) error {
+	q := `DELETE FROM group_members WHERE group_id = :group_id AND member = :member`
+
+	dbm := dbMember{
+		GroupID: id,
+		Member:  member,
+	}
+
+	res, err := gr.db.NamedExecContext(ctx, q, dbm)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && errInvalid == pqErr.Code.Name() {
+			return users.ErrNotFound
+		}
+		return err
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if cnt == 0 {
+		return users.ErrNotFound
+	}
+
+	return nil
+}
+
+type dbGroup struct {
+	ID    string `db:"id"`
+	Owner string `db:"owner"`
+	Name  string `db:"name"`
+}
+
+type dbMember struct {
+	GroupID string `db:"group_id"`
+	Member  string `db:"member"`
+}
+
+func toGroup(dbg dbGroup) users.Group {
+	return users.Group{
+		ID:    dbg.ID,
+		Owner: dbg.Owner,
+		Name:  dbg.Name,
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/mainflux/mainflux/users"
+	"github.com/mainflux/mainflux/users/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupSave(t *testing.T) {
+	owner := "group-save@example.com"
+
+	dbMiddleware := postgres.NewDatabase(db)
+	userRepo := postgres.New(dbMiddleware)
+	groupRepo := postgres.NewGroupRepository(dbMiddleware)
+
+	err := userRepo.Save(context.Background(), users.User{Email: owner, Password: "pass"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	id, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		group users.Group
+		err   error
+	}{
+		{
+			desc:  "save new group",
+			group: users.Group{ID: id.String(), Owner: owner, Name: "group"},
+			err:   nil,
+		},
+		{
+			desc:  "save existing group",
+			group: users.Group{ID: id.String(), Owner: owner, Name: "group"},
+			err:   users.ErrConflict,
+		},
+		{
+			desc:  "save group with invalid ID",
+			group: users.Group{ID: "invalid", Owner: owner, Name: "group"},
+			err:   users.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		err := groupRepo.Save(context.Background(), tc.group)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestGroupMembers(t *testing.T) {
+	owner := "group-owner@example.com"
+	member := "group-member@example.com"
+
+	dbMiddleware := postgres.NewDatabase(db)
+	userRepo := postgres.New(dbMiddleware)
+	groupRepo := postgres.NewGroupRepository(dbMiddleware)
+
+	for _, email := range []string{owner, member} {
+		err := userRepo.Save(context.Background(), users.User{Email: email, Password: "pass"})
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	id, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	gid := id.String()
+	err = groupRepo.Save(context.Background(), users.Group{ID: gid, Owner: owner, Name: "group"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	err = groupRepo.AssignMember(context.Background(), gid, member)
+	assert.Nil(t, err, fmt.Sprintf("assign member: unexpected error: %s", err))
+	err = groupRepo.AssignMember(context.Background(), gid, member)
+	assert.Equal(t, users.ErrConflict, err, fmt.Sprintf("assign assigned member: expected %s got %s\n", users.ErrConflict, err))
+	err = groupRepo.AssignMember(context.Background(), gid, "unknown@example.com")
+	assert.Equal(t, users.ErrNotFound, err, fmt.Sprintf("assign non-existing user: expected %s got %s\n", users.ErrNotFound, err))
+
+	group, err := groupRepo.RetrieveByID(context.Background(), gid)
+	assert.Nil(t, err, fmt.Sprintf("retrieve group: unexpected error: %s", err))
+	assert.ElementsMatch(t, []string{owner, member}, group.Members, fmt.Sprintf("retrieve group: expected %v got %v\n", []string{owner, member}, group.Members))
+
+	groups, err := groupRepo.RetrieveAll(context.Background(), member)
+	assert.Nil(t, err, fmt.Sprintf("retrieve member groups: unexpected error: %s", err))
+	assert.Equal(t, 1, len(groups), fmt.Sprintf("retrieve member groups: expected %d got %d\n", 1, len(groups)))
+
+	err = groupRepo.UnassignMember(context.Background(), gid, member)
+	assert.Nil(t, err, fmt.Sprintf("unassign member: unexpected error: %s", err))
+	err = groupRepo.UnassignMember(context.Background(), gid, member)
+	assert.Equal(t, users.ErrNotFound, err, fmt.Sprintf("unassign unassigned member: expected %s got %s\n", users.ErrNotFound, err))
+
+	err = groupRepo.Remove(context.Background(), owner, gid)
+	assert.Nil(t, err, fmt.Sprintf("remove group: unexpected error: %s", err))
+	_, err = groupRepo.RetrieveByID(context.Background(), gid)
+	assert.Equal(t, users.ErrNotFound, err, fmt.Sprintf("retrieve removed group: expected %s got %s\n", users.ErrNotFound, err))
+}
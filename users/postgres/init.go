@@ -43,10 +43,16 @@ func Connect(cfg Config) (*sqlx.DB, error) {
 }
 
 func migrateDB(db *sqlx.DB) error {
+	if err := padMigrationIDs(db); err != nil {
+		return err
+	}
+
+	// Migration IDs are zero padded, since the migrations are applied in
+	// the order of their IDs compared as strings.
 	migrations := &migrate.MemoryMigrationSource{
 		Migrations: []*migrate.Migration{
 			{
-				Id: "users_1",
+				Id: "users_01",
 				Up: []string{
 					`CREATE TABLE IF NOT EXISTS users (
 						email	 VARCHAR(254) PRIMARY KEY,
@@ -56,14 +62,138 @@ func migrateDB(db *sqlx.DB) error {
 				Down: []string{"DROP TABLE users"},
 			},
 			{
-				Id: "users_2",
+				Id: "users_02",
 				Up: []string{
 					`ALTER TABLE IF EXISTS users ADD COLUMN IF NOT EXISTS metadata JSONB`,
 				},
 			},
+			{
+				Id: "users_03",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS groups (
+						id    UUID PRIMARY KEY,
+						owner VARCHAR(254) NOT NULL REFERENCES users (email) ON DELETE CASCADE,
+						name  VARCHAR(1024) NOT NULL
+					)`,
+					`CREATE TABLE IF NOT EXISTS group_members (
+						group_id UUID REFERENCES groups (id) ON DELETE CASCADE,
+						member   VARCHAR(254) REFERENCES users (email) ON DELETE CASCADE,
+						PRIMARY KEY (group_id, member)
+					)`,
+					`CREATE INDEX IF NOT EXISTS group_members_member_idx ON group_members (member)`,
+				},
+				Down: []string{
+					"DROP TABLE group_members",
+					"DROP TABLE groups",
+				},
+			},
+			{
+				Id: "users_04",
+				Up: []string{
+					`ALTER TABLE IF EXISTS users ALTER COLUMN password TYPE VARCHAR(254)`,
+				},
+			},
+			{
+				Id: "users_05",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS keys (
+						id         UUID PRIMARY KEY,
+						owner      VARCHAR(254) NOT NULL REFERENCES users (email) ON DELETE CASCADE,
+						name       VARCHAR(1024) NOT NULL,
+						scopes     TEXT[] NOT NULL,
+						secret     CHAR(64) NOT NULL,
+						issued_at  TIMESTAMPTZ NOT NULL,
+						expires_at TIMESTAMPTZ
+					)`,
+					`CREATE INDEX IF NOT EXISTS keys_owner_idx ON keys (owner)`,
+				},
+				Down: []string{"DROP TABLE keys"},
+			},
+			{
+				Id: "users_06",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS roles (
+						email VARCHAR(254) PRIMARY KEY REFERENCES users (email) ON DELETE CASCADE,
+						role  VARCHAR(16) NOT NULL
+					)`,
+				},
+				Down: []string{"DROP TABLE roles"},
+			},
+			{
+				Id: "users_07",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS orgs (
+						id    UUID PRIMARY KEY,
+						owner VARCHAR(254) NOT NULL REFERENCES users (email) ON DELETE CASCADE,
+						name  VARCHAR(1024) NOT NULL
+					)`,
+					`CREATE TABLE IF NOT EXISTS org_members (
+						org_id UUID NOT NULL REFERENCES orgs (id) ON DELETE CASCADE,
+						email  VARCHAR(254) NOT NULL REFERENCES users (email) ON DELETE CASCADE,
+						role   VARCHAR(16) NOT NULL,
+						PRIMARY KEY (org_id, email)
+					)`,
+					`CREATE INDEX IF NOT EXISTS org_members_email_idx ON org_members (email)`,
+				},
+				Down: []string{
+					"DROP TABLE org_members",
+					"DROP TABLE orgs",
+				},
+			},
+			{
+				Id: "users_08",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS totp (
+						email   VARCHAR(254) PRIMARY KEY REFERENCES users (email) ON DELETE CASCADE,
+						secret  VARCHAR(254) NOT NULL,
+						enabled BOOLEAN NOT NULL DEFAULT FALSE
+					)`,
+					`CREATE TABLE IF NOT EXISTS recovery_codes (
+						email VARCHAR(254) REFERENCES totp (email) ON DELETE CASCADE,
+						code  CHAR(64),
+						PRIMARY KEY (email, code)
+					)`,
+				},
+				Down: []string{
+					"DROP TABLE recovery_codes",
+					"DROP TABLE totp",
+				},
+			},
+			{
+				Id: "users_09",
+				Up: []string{
+					`ALTER TABLE IF EXISTS users ADD COLUMN IF NOT EXISTS disabled BOOLEAN NOT NULL DEFAULT FALSE`,
+				},
+				Down: []string{
+					"ALTER TABLE IF EXISTS users DROP COLUMN IF EXISTS disabled",
+				},
+			},
+			{
+				Id: "users_10",
+				Up: []string{
+					`ALTER TABLE IF EXISTS totp ADD COLUMN IF NOT EXISTS counter BIGINT NOT NULL DEFAULT 0`,
+				},
+				Down: []string{
+					"ALTER TABLE IF EXISTS totp DROP COLUMN IF EXISTS counter",
+				},
+			},
 		},
 	}
 
 	_, err := migrate.Exec(db.DB, "postgres", migrations, migrate.Up)
 	return err
 }
+
+// padMigrationIDs renames the applied migrations recorded before their IDs
+// were zero padded, so that they aren't reported as unknown.
+func padMigrationIDs(db *sqlx.DB) error {
+	q := `DO $$
+	      BEGIN
+	          IF to_regclass('gorp_migrations') IS NOT NULL THEN
+	              UPDATE gorp_migrations SET id = 'users_0' || substring(id from 7) WHERE id ~ '^users_[0-9]$';
+	          END IF;
+	      END $$`
+
+	_, err := db.Exec(q)
+	return err
+}
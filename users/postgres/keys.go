@@ -0,0 +1,151 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/mainflux/mainflux/users"
+)
+
+var _ users.KeyRepository = (*keyRepository)(nil)
+
+type keyRepository struct {
+	db Database
+}
+
+// NewKeyRepository instantiates a PostgreSQL implementation of key
+// repository.
+func NewKeyRepository(db Database) users.KeyRepository {
+	return &keyRepository{
+		db: db,
+	}
+}
+
+func (kr keyRepository) Save(ctx context.Context, key users.Key) error {
+	q := `INSERT INTO keys (id, owner, name, scopes, secret, issued_at, expires_at)
+	      VALUES (:id, :owner, :name, :scopes, :secret, :issued_at, :expires_at)`
+
+	if _, err := kr.db.NamedExecContext(ctx, q, toDBKey(key)); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code.Name() {
+			case errDuplicate:
+				return users.ErrConflict
+			case errInvalid:
+				return users.ErrMalformedEntity
+			case errFK:
+				return users.ErrNotFound
+			}
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (kr keyRepository) RetrieveByID(ctx context.Context, id string) (users.Key, error) {
+	q := `SELECT id, owner, name, scopes, secret, issued_at, expires_at FROM keys WHERE id = $1`
+
+	var dbk dbKey
+	if err := kr.db.QueryRowxContext(ctx, q, id).StructScan(&dbk); err != nil {
+		if err == sql.ErrNoRows {
+			return users.Key{}, users.ErrNotFound
+		}
+		if pqErr, ok := err.(*pq.Error); ok && errInvalid == pqErr.Code.Name() {
+			return users.Key{}, users.ErrNotFound
+		}
+		return users.Key{}, err
+	}
+
+	return toKey(dbk), nil
+}
+
+func (kr keyRepository) RetrieveAll(ctx context.Context, owner string) ([]users.Key, error) {
+	q := `SELECT id, owner, name, scopes, issued_at, expires_at FROM keys WHERE owner = :owner ORDER BY issued_at, id`
+
+	rows, err := kr.db.NamedQueryContext(ctx, q, map[string]interface{}{"owner": owner})
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []users.Key{}
+	for rows.Next() {
+		var dbk dbKey
+		if err := rows.StructScan(&dbk); err != nil {
+			return nil, err
+		}
+		keys = append(keys, toKey(dbk))
+	}
+
+	return keys, nil
+}
+
+func (kr keyRepository) Remove(ctx context.Context, owner, id string) error {
+	q := `DELETE FROM keys WHERE id = :id AND owner = :owner`
+
+	res, err := kr.db.NamedExecContext(ctx, q, dbKey{ID: id, Owner: owner})
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && errInvalid == pqErr.Code.Name() {
+			return users.ErrNotFound
+		}
+		return err
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if cnt == 0 {
+		return users.ErrNotFound
+	}
+
+	return nil
+}
+
+type dbKey struct {
+	ID        string         `db:"id"`
+	Owner     string         `db:"owner"`
+	Name      string         `db:"name"`
+	Scopes    pq.StringArray `db:"scopes"`
+	Secret    string         `db:"secret"`
+	IssuedAt  time.Time      `db:"issued_at"`
+	ExpiresAt *time.Time     `db:"expires_at"`
+}
+
+func toDBKey(key users.Key) dbKey {
+	dbk := dbKey{
+		ID:       key.ID,
+		Owner:    key.Owner,
+		Name:     key.Name,
+		Scopes:   key.Scopes,
+		Secret:   key.Secret,
+		IssuedAt: key.IssuedAt,
+	}
+	if !key.ExpiresAt.IsZero() {
+		dbk.ExpiresAt = &key.ExpiresAt
+	}
+
+	return dbk
+}
+
+func toKey(dbk dbKey) users.Key {
+	key := users.Key{
+		ID:       dbk.ID,
+		Owner:    dbk.Owner,
+		Name:     dbk.Name,
+		Scopes:   []string(dbk.Scopes),
+		Secret:   dbk.Secret,
+		IssuedAt: dbk.IssuedAt.UTC(),
+	}
+	if dbk.ExpiresAt != nil {
+		key.ExpiresAt = dbk.ExpiresAt.UTC()
+	}
+
+	return key
+}
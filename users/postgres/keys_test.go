@@ -0,0 +1,90 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/mainflux/mainflux/users"
+	"github.com/mainflux/mainflux/users/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeySaveRetrieve(t *testing.T) {
+	owner := "key-owner@example.com"
+
+	dbMiddleware := postgres.NewDatabase(db)
+	userRepo := postgres.New(dbMiddleware)
+	keyRepo := postgres.NewKeyRepository(dbMiddleware)
+
+	err := userRepo.Save(context.Background(), users.User{Email: owner, Password: "pass"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	id, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	now := time.Now().UTC().Truncate(time.Second)
+	key := users.Key{
+		ID:        id.String(),
+		Owner:     owner,
+		Name:      "ci",
+		Scopes:    []string{users.ScopeServices},
+		Secret:    fmt.Sprintf("%064d", 1),
+		IssuedAt:  now,
+		ExpiresAt: now.Add(time.Hour),
+	}
+
+	cases := []struct {
+		desc string
+		key  users.Key
+		err  error
+	}{
+		{
+			desc: "save new key",
+			key:  key,
+			err:  nil,
+		},
+		{
+			desc: "save existing key",
+			key:  key,
+			err:  users.ErrConflict,
+		},
+		{
+			desc: "save key of non-existing user",
+			key:  users.Key{ID: uuid.Must(uuid.NewV4()).String(), Owner: "none@example.com", Name: "ci", Scopes: key.Scopes, Secret: key.Secret, IssuedAt: now},
+			err:  users.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := keyRepo.Save(context.Background(), tc.key)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	saved, err := keyRepo.RetrieveByID(context.Background(), key.ID)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, key, saved, fmt.Sprintf("expected %v got %v\n", key, saved))
+
+	_, err = keyRepo.RetrieveByID(context.Background(), "invalid")
+	assert.Equal(t, users.ErrNotFound, err, fmt.Sprintf("expected %s got %s\n", users.ErrNotFound, err))
+
+	keys, err := keyRepo.RetrieveAll(context.Background(), owner)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, 1, len(keys), fmt.Sprintf("expected 1 key got %d\n", len(keys)))
+	assert.Empty(t, keys[0].Secret, "expected key secret not to be retrieved")
+
+	err = keyRepo.Remove(context.Background(), "other@example.com", key.ID)
+	assert.Equal(t, users.ErrNotFound, err, fmt.Sprintf("expected %s got %s\n", users.ErrNotFound, err))
+
+	err = keyRepo.Remove(context.Background(), owner, key.ID)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	_, err = keyRepo.RetrieveByID(context.Background(), key.ID)
+	assert.Equal(t, users.ErrNotFound, err, fmt.Sprintf("expected %s got %s\n", users.ErrNotFound, err))
+}
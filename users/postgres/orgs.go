@@ -0,0 +1,221 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+	"github.com/mainflux/mainflux/users"
+)
+
+var _ users.OrgRepository = (*orgRepository)(nil)
+
+type orgRepository struct {
+	db Database
+}
+
+// NewOrgRepository instantiates a PostgreSQL implementation of organization
+// repository.
+func NewOrgRepository(db Database) users.OrgRepository {
+	return &orgRepository{
+		db: db,
+	}
+}
+
+func (or orgRepository) Save(ctx context.Context, org users.Org) error {
+	// The owner is assigned in the same statement, so the organization
+	// never exists without an admin.
+	q := `WITH o AS (
+		      INSERT INTO orgs (id, owner, name) VALUES (:id, :owner, :name) RETURNING id, owner
+		  )
+		  INSERT INTO org_members (org_id, email, role) SELECT id, owner, :role FROM o`
+
+	params := map[string]interface{}{
+		"id":    org.ID,
+		"owner": org.Owner,
+		"name":  org.Name,
+		"role":  users.RoleAdmin,
+	}
+
+	if _, err := or.db.NamedExecContext(ctx, q, params); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code.Name() {
+			case errDuplicate:
+				return users.ErrConflict
+			case errInvalid:
+				return users.ErrMalformedEntity
+			case errFK:
+				return users.ErrNotFound
+			}
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (or orgRepository) RetrieveByID(ctx context.Context, id string) (users.Org, error) {
+	q := `SELECT id, owner, name FROM orgs WHERE id = $1`
+
+	var dbo dbOrg
+	if err := or.db.QueryRowxContext(ctx, q, id).StructScan(&dbo); err != nil {
+		if err == sql.ErrNoRows {
+			return users.Org{}, users.ErrNotFound
+		}
+		if pqErr, ok := err.(*pq.Error); ok && errInvalid == pqErr.Code.Name() {
+			return users.Org{}, users.ErrNotFound
+		}
+		return users.Org{}, err
+	}
+
+	q = `SELECT email, role FROM org_members WHERE org_id = :id ORDER BY email`
+
+	rows, err := or.db.NamedQueryContext(ctx, q, map[string]interface{}{"id": id})
+	if err != nil {
+		return users.Org{}, err
+	}
+	defer rows.Close()
+
+	org := toOrg(dbo)
+	for rows.Next() {
+		var m users.Member
+		if err := rows.Scan(&m.Email, &m.Role); err != nil {
+			return users.Org{}, err
+		}
+		org.Members = append(org.Members, m)
+	}
+
+	return org, nil
+}
+
+func (or orgRepository) RetrieveAll(ctx context.Context, email string) ([]users.Org, error) {
+	q := `SELECT o.id, o.owner, o.name FROM orgs o
+		  INNER JOIN org_members m ON m.org_id = o.id
+		  WHERE m.email = :email ORDER BY o.id`
+
+	rows, err := or.db.NamedQueryContext(ctx, q, map[string]interface{}{"email": email})
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	orgs := []users.Org{}
+	for rows.Next() {
+		var dbo dbOrg
+		if err := rows.StructScan(&dbo); err != nil {
+			return nil, err
+		}
+		orgs = append(orgs, toOrg(dbo))
+	}
+
+	return orgs, nil
+}
+
+func (or orgRepository) Remove(ctx context.Context, owner, id string) error {
+	q := `DELETE FROM orgs WHERE id = :id AND owner = :owner`
+
+	dbo := dbOrg{
+		ID:    id,
+		Owner: owner,
+	}
+
+	if _, err := or.db.NamedExecContext(ctx, q, dbo); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && errInvalid == pqErr.Code.Name() {
+			return users.ErrNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (or orgRepository) SaveMember(ctx context.Context, id, email, role string) error {
+	q := `INSERT INTO org_members (org_id, email, role) VALUES (:org_id, :email, :role)
+	      ON CONFLICT (org_id, email) DO UPDATE SET role = EXCLUDED.role`
+
+	dbm := dbOrgMember{
+		OrgID: id,
+		Email: email,
+		Role:  role,
+	}
+
+	if _, err := or.db.NamedExecContext(ctx, q, dbm); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code.Name() {
+			case errFK, errInvalid:
+				return users.ErrNotFound
+			}
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (or orgRepository) RemoveMember(ctx context.Context, id, email string) error {
+	q := `DELETE FROM org_members WHERE org_id = :org_id AND email = :email`
+
+	dbm := dbOrgMember{
+		OrgID: id,
+		Email: email,
+	}
+
+	res, err := or.db.NamedExecContext(ctx, q, dbm)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && errInvalid == pqErr.Code.Name() {
+			return users.ErrNotFound
+		}
+		return err
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if cnt == 0 {
+		return users.ErrNotFound
+	}
+
+	return nil
+}
+
+func (or orgRepository) RetrieveRole(ctx context.Context, id, email string) (string, error) {
+	q := `SELECT role FROM org_members WHERE org_id = $1 AND email = $2`
+
+	var role string
+	if err := or.db.QueryRowxContext(ctx, q, id, email).Scan(&role); err != nil {
+		if err == sql.ErrNoRows {
+			return "", users.ErrNotFound
+		}
+		if pqErr, ok := err.(*pq.Error); ok && errInvalid == pqErr.Code.Name() {
+			return "", users.ErrNotFound
+		}
+		return "", err
+	}
+
+	return role, nil
+}
+
+type dbOrg struct {
+	ID    string `db:"id"`
+	Owner string `db:"owner"`
+	Name  string `db:"name"`
+}
+
+type dbOrgMember struct {
+	OrgID string `db:"org_id"`
+	Email string `db:"email"`
+	Role  string `db:"role"`
+}
+
+func toOrg(dbo dbOrg) users.Org {
+	return users.Org{
+		ID:    dbo.ID,
+		Owner: dbo.Owner,
+		Name:  dbo.Name,
+	}
+}
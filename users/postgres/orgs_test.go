@@ -0,0 +1,99 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/mainflux/mainflux/users"
+	"github.com/mainflux/mainflux/users/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrgSaveRetrieve(t *testing.T) {
+	owner := "org-owner@example.com"
+	member := "org-member@example.com"
+
+	dbMiddleware := postgres.NewDatabase(db)
+	userRepo := postgres.New(dbMiddleware)
+	orgRepo := postgres.NewOrgRepository(dbMiddleware)
+
+	for _, email := range []string{owner, member} {
+		err := userRepo.Save(context.Background(), users.User{Email: email, Password: "pass"})
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	id, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	org := users.Org{ID: id.String(), Owner: owner, Name: "org"}
+
+	err = orgRepo.Save(context.Background(), org)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	role, err := orgRepo.RetrieveRole(context.Background(), org.ID, owner)
+	assert.Nil(t, err, fmt.Sprintf("retrieve owner role: unexpected error: %s", err))
+	assert.Equal(t, users.RoleAdmin, role, fmt.Sprintf("retrieve owner role: expected %s got %s", users.RoleAdmin, role))
+
+	cases := []struct {
+		desc  string
+		email string
+		role  string
+		err   error
+	}{
+		{
+			desc:  "add member",
+			email: member,
+			role:  users.RoleViewer,
+			err:   nil,
+		},
+		{
+			desc:  "replace member role",
+			email: member,
+			role:  users.RoleEditor,
+			err:   nil,
+		},
+		{
+			desc:  "add non-existing user",
+			email: "none@example.com",
+			role:  users.RoleViewer,
+			err:   users.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := orgRepo.SaveMember(context.Background(), org.ID, tc.email, tc.role)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+		if err != nil {
+			continue
+		}
+
+		role, err := orgRepo.RetrieveRole(context.Background(), org.ID, tc.email)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		assert.Equal(t, tc.role, role, fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.role, role))
+	}
+
+	saved, err := orgRepo.RetrieveByID(context.Background(), org.ID)
+	assert.Nil(t, err, fmt.Sprintf("retrieve org: unexpected error: %s", err))
+	assert.Len(t, saved.Members, 2, fmt.Sprintf("retrieve org: expected 2 members got %d", len(saved.Members)))
+
+	orgs, err := orgRepo.RetrieveAll(context.Background(), member)
+	assert.Nil(t, err, fmt.Sprintf("retrieve member orgs: unexpected error: %s", err))
+	assert.Len(t, orgs, 1, fmt.Sprintf("retrieve member orgs: expected 1 org got %d", len(orgs)))
+
+	err = orgRepo.RemoveMember(context.Background(), org.ID, member)
+	assert.Nil(t, err, fmt.Sprintf("remove member: unexpected error: %s", err))
+
+	_, err = orgRepo.RetrieveRole(context.Background(), org.ID, member)
+	assert.Equal(t, users.ErrNotFound, err, fmt.Sprintf("retrieve removed member role: expected %s got %s", users.ErrNotFound, err))
+
+	err = orgRepo.Remove(context.Background(), owner, org.ID)
+	assert.Nil(t, err, fmt.Sprintf("remove org: unexpected error: %s", err))
+
+	_, err = orgRepo.RetrieveByID(context.Background(), org.ID)
+	assert.Equal(t, users.ErrNotFound, err, fmt.Sprintf("retrieve removed org: expected %s got %s", users.ErrNotFound, err))
+}
@@ -0,0 +1,64 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+	"github.com/mainflux/mainflux/users"
+)
+
+var _ users.RoleRepository = (*roleRepository)(nil)
+
+type roleRepository struct {
+	db Database
+}
+
+// NewRoleRepository instantiates a PostgreSQL implementation of role
+// repository.
+func NewRoleRepository(db Database) users.RoleRepository {
+	return &roleRepository{
+		db: db,
+	}
+}
+
+func (rr roleRepository) Save(ctx context.Context, email, role string) error {
+	q := `INSERT INTO roles (email, role) VALUES (:email, :role)
+	      ON CONFLICT (email) DO UPDATE SET role = EXCLUDED.role`
+
+	params := map[string]interface{}{
+		"email": email,
+		"role":  role,
+	}
+
+	if _, err := rr.db.NamedExecContext(ctx, q, params); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code.Name() {
+			case errInvalid:
+				return users.ErrMalformedEntity
+			case errFK:
+				return users.ErrNotFound
+			}
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (rr roleRepository) RetrieveByID(ctx context.Context, email string) (string, error) {
+	q := `SELECT role FROM roles WHERE email = $1`
+
+	var role string
+	if err := rr.db.QueryRowxContext(ctx, q, email).Scan(&role); err != nil {
+		if err == sql.ErrNoRows {
+			return "", users.ErrNotFound
+		}
+		return "", err
+	}
+
+	return role, nil
+}
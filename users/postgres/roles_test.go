@@ -0,0 +1,67 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux/users"
+	"github.com/mainflux/mainflux/users/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoleSaveRetrieve(t *testing.T) {
+	email := "role-user@example.com"
+
+	dbMiddleware := postgres.NewDatabase(db)
+	userRepo := postgres.New(dbMiddleware)
+	roleRepo := postgres.NewRoleRepository(dbMiddleware)
+
+	err := userRepo.Save(context.Background(), users.User{Email: email, Password: "pass"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	_, err = roleRepo.RetrieveByID(context.Background(), email)
+	assert.Equal(t, users.ErrNotFound, err, fmt.Sprintf("retrieve unassigned role: expected %s got %s", users.ErrNotFound, err))
+
+	cases := []struct {
+		desc  string
+		email string
+		role  string
+		err   error
+	}{
+		{
+			desc:  "assign role",
+			email: email,
+			role:  users.RoleViewer,
+			err:   nil,
+		},
+		{
+			desc:  "replace assigned role",
+			email: email,
+			role:  users.RoleAdmin,
+			err:   nil,
+		},
+		{
+			desc:  "assign role to non-existing user",
+			email: "none@example.com",
+			role:  users.RoleViewer,
+			err:   users.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := roleRepo.Save(context.Background(), tc.email, tc.role)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+		if err != nil {
+			continue
+		}
+
+		role, err := roleRepo.RetrieveByID(context.Background(), tc.email)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		assert.Equal(t, tc.role, role, fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.role, role))
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+	"github.com/mainflux/mainflux/users"
+)
+
+var _ users.TOTPRepository = (*totpRepository)(nil)
+
+type totpRepository struct {
+	db Database
+}
+
+// NewTOTPRepository instantiates a PostgreSQL implementation of second
+// factor repository.
+func NewTOTPRepository(db Database) users.TOTPRepository {
+	return &totpRepository{
+		db: db,
+	}
+}
+
+func (tr totpRepository) Save(ctx context.Context, totp users.TOTP) error {
+	// Replacing the second factor removes the recovery codes and resets
+	// the counter of the replaced one.
+	q := `WITH r AS (
+		      DELETE FROM recovery_codes WHERE email = :email
+		  )
+		  INSERT INTO totp (email, secret, enabled) VALUES (:email, :secret, :enabled)
+		  ON CONFLICT (email) DO UPDATE SET secret = EXCLUDED.secret, enabled = EXCLUDED.enabled, counter = 0`
+
+	params := map[string]interface{}{
+		"email":   totp.Email,
+		"secret":  totp.Secret,
+		"enabled": totp.Enabled,
+	}
+
+	if _, err := tr.db.NamedExecContext(ctx, q, params); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code.Name() {
+			case errInvalid:
+				return users.ErrMalformedEntity
+			case errFK:
+				return users.ErrNotFound
+			}
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (tr totpRepository) RetrieveByID(ctx context.Context, email string) (users.TOTP, error) {
+	q := `SELECT email, secret, enabled FROM totp WHERE email = $1`
+
+	var dbt dbTOTP
+	if err := tr.db.QueryRowxContext(ctx, q, email).StructScan(&dbt); err != nil {
+		if err == sql.ErrNoRows {
+			return users.TOTP{}, users.ErrNotFound
+		}
+		return users.TOTP{}, err
+	}
+
+	return users.TOTP{
+		Email:   dbt.Email,
+		Secret:  dbt.Secret,
+		Enabled: dbt.Enabled,
+	}, nil
+}
+
+func (tr totpRepository) Enable(ctx context.Context, email string, codes []string) error {
+	// The second factor is enabled together with storing its recovery
+	// codes, so that it's never enabled without them.
+	q := `WITH t AS (
+		      UPDATE totp SET enabled = TRUE WHERE email = :email RETURNING email
+		  ), r AS (
+		      DELETE FROM recovery_codes WHERE email = :email
+		  )
+		  INSERT INTO recovery_codes (email, code) SELECT t.email, c FROM t, unnest(CAST(:codes AS TEXT[])) AS c`
+
+	params := map[string]interface{}{
+		"email": email,
+		"codes": pq.Array(codes),
+	}
+
+	res, err := tr.db.NamedExecContext(ctx, q, params)
+	if err != nil {
+		return err
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if cnt == 0 {
+		return users.ErrNotFound
+	}
+
+	return nil
+}
+
+func (tr totpRepository) RemoveRecoveryCode(ctx context.Context, email, code string) error {
+	q := `DELETE FROM recovery_codes WHERE email = :email AND code = :code`
+
+	params := map[string]interface{}{
+		"email": email,
+		"code":  code,
+	}
+
+	res, err := tr.db.NamedExecContext(ctx, q, params)
+	if err != nil {
+		return err
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if cnt == 0 {
+		return users.ErrNotFound
+	}
+
+	return nil
+}
+
+func (tr totpRepository) UpdateCounter(ctx context.Context, email string, counter int64) error {
+	// The counter is compared and updated at once, so that the concurrent
+	// logins can't accept the same password.
+	q := `UPDATE totp SET counter = :counter WHERE email = :email AND counter < :counter`
+
+	params := map[string]interface{}{
+		"email":   email,
+		"counter": counter,
+	}
+
+	res, err := tr.db.NamedExecContext(ctx, q, params)
+	if err != nil {
+		return err
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if cnt == 0 {
+		return users.ErrNotFound
+	}
+
+	return nil
+}
+
+type dbTOTP struct {
+	Email   string `db:"email"`
+	Secret  string `db:"secret"`
+	Enabled bool   `db:"enabled"`
+}
@@ -0,0 +1,180 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux/users"
+	"github.com/mainflux/mainflux/users/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTOTPSaveRetrieve(t *testing.T) {
+	email := "totp-user@example.com"
+
+	dbMiddleware := postgres.NewDatabase(db)
+	userRepo := postgres.New(dbMiddleware)
+	totpRepo := postgres.NewTOTPRepository(dbMiddleware)
+
+	err := userRepo.Save(context.Background(), users.User{Email: email, Password: "pass"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	_, err = totpRepo.RetrieveByID(context.Background(), email)
+	assert.Equal(t, users.ErrNotFound, err, fmt.Sprintf("retrieve missing second factor: expected %s got %s", users.ErrNotFound, err))
+
+	cases := []struct {
+		desc string
+		totp users.TOTP
+		err  error
+	}{
+		{
+			desc: "save second factor",
+			totp: users.TOTP{Email: email, Secret: "secret"},
+			err:  nil,
+		},
+		{
+			desc: "replace second factor",
+			totp: users.TOTP{Email: email, Secret: "other-secret"},
+			err:  nil,
+		},
+		{
+			desc: "save second factor of non-existing user",
+			totp: users.TOTP{Email: "none@example.com", Secret: "secret"},
+			err:  users.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := totpRepo.Save(context.Background(), tc.totp)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+		if err != nil {
+			continue
+		}
+
+		totp, err := totpRepo.RetrieveByID(context.Background(), tc.totp.Email)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		assert.Equal(t, tc.totp, totp, fmt.Sprintf("%s: expected %v got %v", tc.desc, tc.totp, totp))
+	}
+}
+
+func TestTOTPEnable(t *testing.T) {
+	email := "totp-enable@example.com"
+	codes := []string{"code-1", "code-2"}
+
+	dbMiddleware := postgres.NewDatabase(db)
+	userRepo := postgres.New(dbMiddleware)
+	totpRepo := postgres.NewTOTPRepository(dbMiddleware)
+
+	err := userRepo.Save(context.Background(), users.User{Email: email, Password: "pass"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	err = totpRepo.Enable(context.Background(), email, codes)
+	assert.Equal(t, users.ErrNotFound, err, fmt.Sprintf("enable missing second factor: expected %s got %s", users.ErrNotFound, err))
+
+	err = totpRepo.Save(context.Background(), users.TOTP{Email: email, Secret: "secret"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = totpRepo.Enable(context.Background(), email, codes)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	totp, err := totpRepo.RetrieveByID(context.Background(), email)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.True(t, totp.Enabled, "expected second factor to be enabled")
+
+	cases := []struct {
+		desc string
+		code string
+		err  error
+	}{
+		{
+			desc: "use recovery code",
+			code: codes[0],
+			err:  nil,
+		},
+		{
+			desc: "use already used recovery code",
+			code: codes[0],
+			err:  users.ErrNotFound,
+		},
+		{
+			desc: "use non-existing recovery code",
+			code: wrong,
+			err:  users.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := totpRepo.RemoveRecoveryCode(context.Background(), email, tc.code)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+	}
+
+	err = totpRepo.Save(context.Background(), users.TOTP{Email: email, Secret: "other-secret"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = totpRepo.RemoveRecoveryCode(context.Background(), email, codes[1])
+	assert.Equal(t, users.ErrNotFound, err, fmt.Sprintf("use recovery code of replaced second factor: expected %s got %s", users.ErrNotFound, err))
+}
+
+func TestTOTPUpdateCounter(t *testing.T) {
+	email := "totp-counter@example.com"
+
+	dbMiddleware := postgres.NewDatabase(db)
+	userRepo := postgres.New(dbMiddleware)
+	totpRepo := postgres.NewTOTPRepository(dbMiddleware)
+
+	err := userRepo.Save(context.Background(), users.User{Email: email, Password: "pass"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = totpRepo.Save(context.Background(), users.TOTP{Email: email, Secret: "secret"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc    string
+		email   string
+		counter int64
+		err     error
+	}{
+		{
+			desc:    "update counter",
+			email:   email,
+			counter: 10,
+			err:     nil,
+		},
+		{
+			desc:    "update counter to the same value",
+			email:   email,
+			counter: 10,
+			err:     users.ErrNotFound,
+		},
+		{
+			desc:    "update counter to the lower value",
+			email:   email,
+			counter: 9,
+			err:     users.ErrNotFound,
+		},
+		{
+			desc:    "update counter to the greater value",
+			email:   email,
+			counter: 11,
+			err:     nil,
+		},
+		{
+			desc:    "update counter of non-existing second factor",
+			email:   "none@example.com",
+			counter: 12,
+			err:     users.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := totpRepo.UpdateCounter(context.Background(), tc.email, tc.counter)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+	}
+
+	err = totpRepo.Save(context.Background(), users.TOTP{Email: email, Secret: "other-secret"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = totpRepo.UpdateCounter(context.Background(), email, 1)
+	assert.Nil(t, err, fmt.Sprintf("update counter of replaced second factor: unexpected error: %s", err))
+}
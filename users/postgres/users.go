@@ -44,7 +44,7 @@ func (ur userRepository) Save(ctx context.Context, user users.User) error {
 }
 
 func (ur userRepository) RetrieveByID(ctx context.Context, email string) (users.User, error) {
-	q := `SELECT password, metadata FROM users WHERE email = $1`
+	q := `SELECT password, metadata, disabled FROM users WHERE email = $1`
 
 	dbu := dbUser{
 		Email: email,
@@ -61,6 +61,112 @@ func (ur userRepository) RetrieveByID(ctx context.Context, email string) (users.
 	return user, nil
 }
 
+func (ur userRepository) RetrieveAll(ctx context.Context, offset, limit uint64) (users.UserPage, error) {
+	q := `SELECT email, metadata, disabled FROM users ORDER BY email LIMIT :limit OFFSET :offset`
+
+	params := map[string]interface{}{
+		"limit":  limit,
+		"offset": offset,
+	}
+
+	rows, err := ur.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return users.UserPage{}, err
+	}
+	defer rows.Close()
+
+	items := []users.User{}
+	for rows.Next() {
+		var dbu dbUser
+		if err := rows.StructScan(&dbu); err != nil {
+			return users.UserPage{}, err
+		}
+		items = append(items, toUser(dbu))
+	}
+
+	var total uint64
+	if err := ur.db.GetContext(ctx, &total, `SELECT COUNT(*) FROM users`); err != nil {
+		return users.UserPage{}, err
+	}
+
+	return users.UserPage{
+		Total:  total,
+		Offset: offset,
+		Limit:  limit,
+		Users:  items,
+	}, nil
+}
+
+func (ur userRepository) UpdateDisabled(ctx context.Context, email string, disabled bool) error {
+	q := `UPDATE users SET disabled = :disabled WHERE email = :email`
+
+	dbu := dbUser{
+		Email:    email,
+		Disabled: disabled,
+	}
+
+	res, err := ur.db.NamedExecContext(ctx, q, dbu)
+	if err != nil {
+		return err
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if cnt == 0 {
+		return users.ErrNotFound
+	}
+
+	return nil
+}
+
+func (ur userRepository) UpdatePassword(ctx context.Context, email, password string) error {
+	q := `UPDATE users SET password = :password WHERE email = :email`
+
+	dbu := dbUser{
+		Email:    email,
+		Password: password,
+	}
+
+	res, err := ur.db.NamedExecContext(ctx, q, dbu)
+	if err != nil {
+		return err
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if cnt == 0 {
+		return users.ErrNotFound
+	}
+
+	return nil
+}
+
+func (ur userRepository) Remove(ctx context.Context, email string) error {
+	q := `DELETE FROM users WHERE email = :email`
+
+	res, err := ur.db.NamedExecContext(ctx, q, dbUser{Email: email})
+	if err != nil {
+		return err
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if cnt == 0 {
+		return users.ErrNotFound
+	}
+
+	return nil
+}
+
 // dbMetadata type for handling metadata properly in database/sql
 type dbMetadata map[string]interface{}
 
@@ -102,6 +208,7 @@ type dbUser struct {
 	Email    string     `db:"email"`
 	Password string     `db:"password"`
 	Metadata dbMetadata `db:"metadata"`
+	Disabled bool       `db:"disabled"`
 }
 
 func toDBUser(u users.User) dbUser {
@@ -109,6 +216,7 @@ func toDBUser(u users.User) dbUser {
 		Email:    u.Email,
 		Password: u.Password,
 		Metadata: u.Metadata,
+		Disabled: u.Disabled,
 	}
 }
 
@@ -117,5 +225,6 @@ func toUser(dbu dbUser) users.User {
 		Email:    dbu.Email,
 		Password: dbu.Password,
 		Metadata: dbu.Metadata,
+		Disabled: dbu.Disabled,
 	}
 }
@@ -73,3 +73,129 @@ func TestSingleUserRetrieval(t *testing.T) {
 		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
 	}
 }
+
+func TestUserUpdatePassword(t *testing.T) {
+	email := "user-update-password@example.com"
+
+	dbMiddleware := postgres.NewDatabase(db)
+	repo := postgres.New(dbMiddleware)
+	err := repo.Save(context.Background(), users.User{
+		Email:    email,
+		Password: "pass",
+	})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := map[string]struct {
+		email    string
+		password string
+		err      error
+	}{
+		"update password of existing user":     {email, "new-pass", nil},
+		"update password of non-existing user": {"unknown@example.com", "new-pass", users.ErrNotFound},
+	}
+
+	for desc, tc := range cases {
+		err := repo.UpdatePassword(context.Background(), tc.email, tc.password)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+		if err == nil {
+			u, _ := repo.RetrieveByID(context.Background(), tc.email)
+			assert.Equal(t, tc.password, u.Password, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.password, u.Password))
+		}
+	}
+}
+
+func TestUserRetrieveAll(t *testing.T) {
+	dbMiddleware := postgres.NewDatabase(db)
+	repo := postgres.New(dbMiddleware)
+
+	n := 5
+	for i := 0; i < n; i++ {
+		err := repo.Save(context.Background(), users.User{
+			Email:    fmt.Sprintf("user-retrieve-all-%d@example.com", i),
+			Password: "pass",
+		})
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	all, err := repo.RetrieveAll(context.Background(), 0, 1000)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	require.True(t, all.Total >= uint64(n), fmt.Sprintf("expected at least %d users got %d", n, all.Total))
+
+	cases := map[string]struct {
+		offset uint64
+		limit  uint64
+		size   int
+	}{
+		"retrieve all users":                      {0, all.Total, int(all.Total)},
+		"retrieve subset of users":                {0, 2, 2},
+		"retrieve last user":                      {all.Total - 1, 10, 1},
+		"retrieve users with offset out of range": {all.Total, 10, 0},
+	}
+
+	for desc, tc := range cases {
+		page, err := repo.RetrieveAll(context.Background(), tc.offset, tc.limit)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", desc, err))
+		assert.Equal(t, tc.size, len(page.Users), fmt.Sprintf("%s: expected %d users got %d\n", desc, tc.size, len(page.Users)))
+		for _, u := range page.Users {
+			assert.Empty(t, u.Password, fmt.Sprintf("%s: unexpected password of user %s\n", desc, u.Email))
+		}
+	}
+}
+
+func TestUserUpdateDisabled(t *testing.T) {
+	email := "user-update-disabled@example.com"
+
+	dbMiddleware := postgres.NewDatabase(db)
+	repo := postgres.New(dbMiddleware)
+	err := repo.Save(context.Background(), users.User{
+		Email:    email,
+		Password: "pass",
+	})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc     string
+		email    string
+		disabled bool
+		err      error
+	}{
+		{"disable existing user", email, true, nil},
+		{"enable existing user", email, false, nil},
+		{"disable non-existing user", "unknown@example.com", true, users.ErrNotFound},
+	}
+
+	for _, tc := range cases {
+		err := repo.UpdateDisabled(context.Background(), tc.email, tc.disabled)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err == nil {
+			u, _ := repo.RetrieveByID(context.Background(), tc.email)
+			assert.Equal(t, tc.disabled, u.Disabled, fmt.Sprintf("%s: expected %t got %t\n", tc.desc, tc.disabled, u.Disabled))
+		}
+	}
+}
+
+func TestUserRemove(t *testing.T) {
+	email := "user-remove@example.com"
+
+	dbMiddleware := postgres.NewDatabase(db)
+	repo := postgres.New(dbMiddleware)
+	err := repo.Save(context.Background(), users.User{
+		Email:    email,
+		Password: "pass",
+	})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		email string
+		err   error
+	}{
+		{"remove existing user", email, nil},
+		{"remove removed user", email, users.ErrNotFound},
+	}
+
+	for _, tc := range cases {
+		err := repo.Remove(context.Background(), tc.email)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package pwned provides a breach checker implementation utilising the
+// Pwned Passwords API of the Have I Been Pwned service.
+package pwned
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mainflux/mainflux/users"
+)
+
+// prefixLen is the length of the hash prefix sent to the API. Only the
+// prefix leaves the service, so the API can't learn the password.
+const prefixLen = 5
+
+var (
+	_ users.BreachChecker = (*breachChecker)(nil)
+
+	errUnexpectedStatus = errors.New("unexpected response status")
+)
+
+type breachChecker struct {
+	url    string
+	client *http.Client
+}
+
+// New instantiates the breach checker which queries the range API available
+// at the provided URL using the k-anonymity model.
+func New(url string, timeout time.Duration) users.BreachChecker {
+	return &breachChecker{
+		url:    strings.TrimSuffix(url, "/"),
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (bc *breachChecker) Breached(ctx context.Context, password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:prefixLen], hash[prefixLen:]
+
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/range/%s", bc.url, prefix), nil)
+	if err != nil {
+		return false, err
+	}
+	// Padding hides the number of the suffixes sharing the prefix.
+	req.Header.Set("Add-Padding", "true")
+
+	res, err := bc.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return false, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return false, errUnexpectedStatus
+	}
+
+	// Each line of the response is formatted as SUFFIX:COUNT. The padding
+	// entries have zero count.
+	scanner := bufio.NewScanner(res.Body)
+	for scanner.Scan() {
+		parts := strings.SplitN(strings.TrimSpace(scanner.Text()), ":", 2)
+		if len(parts) == 2 && strings.EqualFold(parts[0], suffix) {
+			return parts[1] != "0", nil
+		}
+	}
+
+	return false, scanner.Err()
+}
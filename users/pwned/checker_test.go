@@ -0,0 +1,78 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package pwned_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/users/pwned"
+	"github.com/stretchr/testify/assert"
+)
+
+// SHA-1 of "password" is 5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8.
+const rangeRes = "003D68EB55068C33ACE09247EE4C639306B:3\r\n" +
+	"1E4C9B93F3F0682250B6CF8331B7EE68FD8:3730471\r\n" +
+	"0123456789ABCDEF0123456789ABCDEF012:0\r\n"
+
+func newServer(prefixes map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		res, ok := prefixes[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(res))
+	}))
+}
+
+func TestBreached(t *testing.T) {
+	ts := newServer(map[string]string{
+		"/range/5BAA6": rangeRes,
+		// SHA-1 of "padded" is 35B1AC6F9CC1A7D2B46D057C6858B3AF47086AE9.
+		"/range/35B1A": "C6F9CC1A7D2B46D057C6858B3AF47086AE9:0\r\n",
+		// SHA-1 of "unique" is 58037C0078D5F54E15E638CC0DD882A570B13C50.
+		"/range/58037": rangeRes,
+	})
+	defer ts.Close()
+
+	cases := []struct {
+		desc     string
+		password string
+		breached bool
+		err      bool
+	}{
+		{
+			desc:     "check breached password",
+			password: "password",
+			breached: true,
+		},
+		{
+			desc:     "check password matching padding entry",
+			password: "padded",
+			breached: false,
+		},
+		{
+			desc:     "check password missing from range",
+			password: "unique",
+			breached: false,
+		},
+		{
+			desc:     "check password with failing API",
+			password: "failing",
+			err:      true,
+		},
+	}
+
+	checker := pwned.New(ts.URL, time.Second)
+	for _, tc := range cases {
+		breached, err := checker.Breached(context.Background(), tc.password)
+		assert.Equal(t, tc.err, err != nil, fmt.Sprintf("%s: unexpected error %v", tc.desc, err))
+		assert.Equal(t, tc.breached, breached, fmt.Sprintf("%s: expected %t got %t", tc.desc, tc.breached, breached))
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/mainflux/mainflux/users"
+)
+
+const (
+	failuresPrefix = "login_failures"
+	lockPrefix     = "login_lock"
+)
+
+var _ users.LoginAttempts = (*loginAttempts)(nil)
+
+type loginAttempts struct {
+	client redis.UniversalClient
+}
+
+// NewLoginAttempts returns redis login attempts tracker implementation.
+func NewLoginAttempts(client redis.UniversalClient) users.LoginAttempts {
+	return &loginAttempts{
+		client: client,
+	}
+}
+
+func (la *loginAttempts) Fail(_ context.Context, email string, ttl time.Duration) (uint64, error) {
+	pipe := la.client.Pipeline()
+	incr := pipe.Incr(failuresKey(email))
+	pipe.Expire(failuresKey(email), ttl)
+	if _, err := pipe.Exec(); err != nil {
+		return 0, err
+	}
+
+	return uint64(incr.Val()), nil
+}
+
+func (la *loginAttempts) Lock(_ context.Context, email string, d time.Duration) error {
+	return la.client.Set(lockKey(email), "", d).Err()
+}
+
+func (la *loginAttempts) Locked(_ context.Context, email string) (time.Duration, error) {
+	d, err := la.client.PTTL(lockKey(email)).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	// Negative values indicate the missing lock.
+	if d < 0 {
+		return 0, nil
+	}
+
+	return d, nil
+}
+
+func (la *loginAttempts) Reset(_ context.Context, email string) error {
+	return la.client.Del(failuresKey(email), lockKey(email)).Err()
+}
+
+// The email is used as the hash tag, so that the keys of the same account
+// belong to the same Redis Cluster slot.
+func failuresKey(email string) string {
+	return fmt.Sprintf("%s:{%s}", failuresPrefix, email)
+}
+
+func lockKey(email string) string {
+	return fmt.Sprintf("%s:{%s}", lockPrefix, email)
+}
@@ -0,0 +1,58 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package redis_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/users/redis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const email = "user@example.com"
+
+func TestLoginAttemptsFail(t *testing.T) {
+	attempts := redis.NewLoginAttempts(redisClient)
+	defer attempts.Reset(context.Background(), email)
+
+	for i := uint64(1); i <= 3; i++ {
+		failures, err := attempts.Fail(context.Background(), email, time.Minute)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		assert.Equal(t, i, failures, fmt.Sprintf("failed login %d: expected %d failures got %d", i, i, failures))
+	}
+
+	err := attempts.Reset(context.Background(), email)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	failures, err := attempts.Fail(context.Background(), email, time.Minute)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, uint64(1), failures, fmt.Sprintf("failed login after reset: expected 1 failure got %d", failures))
+}
+
+func TestLoginAttemptsLock(t *testing.T) {
+	attempts := redis.NewLoginAttempts(redisClient)
+	defer attempts.Reset(context.Background(), email)
+
+	d, err := attempts.Locked(context.Background(), email)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Zero(t, d, fmt.Sprintf("check missing lock: expected zero duration got %s", d))
+
+	err = attempts.Lock(context.Background(), email, time.Minute)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	d, err = attempts.Locked(context.Background(), email)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.True(t, d > 0 && d <= time.Minute, fmt.Sprintf("check lock: expected duration up to %s got %s", time.Minute, d))
+
+	err = attempts.Reset(context.Background(), email)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	d, err = attempts.Locked(context.Background(), email)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Zero(t, d, fmt.Sprintf("check removed lock: expected zero duration got %s", d))
+}
@@ -0,0 +1,51 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package users
+
+import "context"
+
+const (
+	// RoleAdmin allows managing users and quotas, in addition to everything
+	// the editor is allowed to do.
+	RoleAdmin = "admin"
+
+	// RoleEditor allows managing things, channels and groups, in addition to
+	// everything the viewer is allowed to do.
+	RoleEditor = "editor"
+
+	// RoleViewer allows listing things and channels and reading messages.
+	RoleViewer = "viewer"
+
+	// DefaultRole is the role of the users that aren't assigned one.
+	DefaultRole = RoleEditor
+)
+
+var ranks = map[string]int{
+	RoleViewer: 1,
+	RoleEditor: 2,
+	RoleAdmin:  3,
+}
+
+// ValidRole checks if the provided role is one of the known roles.
+func ValidRole(role string) bool {
+	_, ok := ranks[role]
+	return ok
+}
+
+// HasRole checks if the role grants everything the required role does.
+func HasRole(role, required string) bool {
+	return ranks[role] >= ranks[required]
+}
+
+// RoleRepository specifies a role persistence API.
+type RoleRepository interface {
+	// Save assigns the role to the user identified by the provided email,
+	// replacing the previously assigned one.
+	Save(ctx context.Context, email, role string) error
+
+	// RetrieveByID retrieves the role assigned to the user identified by
+	// the provided email. If the user isn't assigned a role, ErrNotFound is
+	// returned.
+	RetrieveByID(context.Context, string) (string, error)
+}
@@ -5,7 +5,12 @@ package users
 
 import (
 	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
 	"errors"
+	"strings"
+	"time"
 )
 
 var (
@@ -37,32 +42,215 @@ type Service interface {
 
 	// Login authenticates the user given its credentials. Successful
 	// authentication generates new access token. Failed invocations are
-	// identified by the non-nil error values in the response.
-	Login(context.Context, User) (string, error)
+	// identified by the non-nil error values in the response. The one-time
+	// password, or one of the recovery codes, is required if the user
+	// enabled the second factor.
+	Login(ctx context.Context, user User, otp string) (string, error)
+
+	// EnrollTOTP generates new second factor secret for the user identified
+	// by the provided login token. The secret is returned together with its
+	// provisioning URI, and the second factor is enforced once the
+	// enrollment is verified.
+	EnrollTOTP(context.Context, string) (string, string, error)
+
+	// VerifyTOTP verifies the enrollment of the second factor of the user
+	// identified by the provided login token using the one-time password,
+	// and enables the second factor. The generated recovery codes are
+	// returned, and they can't be retrieved later.
+	VerifyTOTP(ctx context.Context, token, otp string) ([]string, error)
 
 	// Identify validates user's token. If token is valid, user's id
 	// is returned. If token is invalid, or invocation failed for some
-	// other reason, non-nil error values are returned in response.
+	// other reason, non-nil error values are returned in response. For
+	// the organization tokens, the organization ID is returned.
 	Identify(string) (string, error)
 
+	// Authorize identifies the owner of the entities the provided token
+	// grants access to, together with the role of the token holder. For the
+	// organization tokens, the owner is the organization and the role is
+	// the member's role in it.
+	Authorize(context.Context, string) (Identity, error)
+
 	// Get authenticated user info for the given token.
 	UserInfo(ctx context.Context, token string) (User, error)
+
+	// ChangePassword changes the password of the user identified by the
+	// provided login token, given the current password.
+	ChangePassword(ctx context.Context, token, oldPassword, password string) error
+
+	// Unregister removes the account of the user identified by the provided
+	// login token, together with the groups the user owns.
+	Unregister(context.Context, string) error
+
+	// CreateGroup creates new group owned by the user identified by the
+	// provided token. Identifier of the created group is returned.
+	CreateGroup(context.Context, string, Group) (string, error)
+
+	// ViewGroup retrieves data about the group identified with the provided
+	// ID, if the user identified by the provided token is its member.
+	ViewGroup(context.Context, string, string) (Group, error)
+
+	// ListGroups retrieves all the groups the user identified by the
+	// provided token is member of.
+	ListGroups(context.Context, string) ([]Group, error)
+
+	// RemoveGroup removes the group identified with the provided ID, that
+	// is owned by the user identified by the provided token.
+	RemoveGroup(context.Context, string, string) error
+
+	// AssignUser adds the user with the provided email to the group
+	// identified with the provided ID. Only the group owner can assign
+	// users.
+	AssignUser(context.Context, string, string, string) error
+
+	// UnassignUser removes the user with the provided email from the group
+	// identified with the provided ID. Only the group owner can unassign
+	// users, and the owner can't be removed from the group.
+	UnassignUser(context.Context, string, string, string) error
+
+	// IssueKey issues new personal access token to the user identified by
+	// the provided token. The issued key is returned along with the token,
+	// which can't be retrieved later. Keys are managed using the login
+	// tokens only, so that the leaked key can't be used to issue new ones.
+	IssueKey(context.Context, string, Key) (Key, string, error)
+
+	// ListKeys retrieves all the keys of the user identified by the
+	// provided login token.
+	ListKeys(context.Context, string) ([]Key, error)
+
+	// RevokeKey removes the key identified with the provided ID, that
+	// belongs to the user identified by the provided login token.
+	RevokeKey(context.Context, string, string) error
+
+	// CreateOrg creates new organization owned by the user identified by
+	// the provided token. Identifier of the created organization is
+	// returned.
+	CreateOrg(context.Context, string, Org) (string, error)
+
+	// ViewOrg retrieves the organization identified with the provided ID,
+	// together with its members, if the user identified by the provided
+	// token is its member.
+	ViewOrg(context.Context, string, string) (Org, error)
+
+	// ListOrgs retrieves all the organizations the user identified by the
+	// provided token is member of.
+	ListOrgs(context.Context, string) ([]Org, error)
+
+	// RemoveOrg removes the organization identified with the provided ID,
+	// that is owned by the user identified by the provided token.
+	RemoveOrg(context.Context, string, string) error
+
+	// AssignOrgMember adds the user with the provided email to the
+	// organization identified with the provided ID, or changes the role of
+	// the existing member. Only organization admins can assign members, and
+	// the role of the owner can't be changed.
+	AssignOrgMember(ctx context.Context, token, id, email, role string) error
+
+	// UnassignOrgMember removes the user with the provided email from the
+	// organization identified with the provided ID. Only organization
+	// admins can unassign members, and the owner can't be removed.
+	UnassignOrgMember(ctx context.Context, token, id, email string) error
+
+	// IssueOrgToken issues the token which the user identified by the
+	// provided login token uses to act on behalf of the organization
+	// identified with the provided ID.
+	IssueOrgToken(context.Context, string, string) (string, error)
+
+	// Role retrieves the role of the user with the provided email. It is
+	// used by the services that have already identified the user.
+	Role(context.Context, string) (string, error)
+
+	// AssignRole assigns the role to the user with the provided email. Only
+	// admins can assign roles.
+	AssignRole(ctx context.Context, token, email, role string) error
+
+	// ViewRole retrieves the role of the user with the provided email. Users
+	// can view their own role, while admins can view the role of any user.
+	ViewRole(ctx context.Context, token, email string) (string, error)
+
+	// ListUsers retrieves the subset of all the registered users. Only
+	// admins can list the users.
+	ListUsers(ctx context.Context, token string, offset, limit uint64) (UserPage, error)
+
+	// DisableUser disables the user with the provided email. Disabled users
+	// can't log in, and their existing tokens are rejected. Only admins can
+	// disable the users.
+	DisableUser(ctx context.Context, token, email string) error
+
+	// EnableUser enables the previously disabled user with the provided
+	// email. Only admins can enable the users.
+	EnableUser(ctx context.Context, token, email string) error
+
+	// Disabled checks if the user with the provided email is disabled. It
+	// is used by the services that manage the resources of the users, and
+	// returns ErrNotFound if the user doesn't exist.
+	Disabled(context.Context, string) (bool, error)
+
+	// RemoveUser removes the account of the user with the provided email.
+	// Only admins can remove the other users' accounts.
+	RemoveUser(ctx context.Context, token, email string) error
+
+	// Impersonate issues the login token of the user with the provided
+	// email, letting admins act on behalf of the user. Only admins can
+	// impersonate the users, and disabled users can't be impersonated.
+	Impersonate(ctx context.Context, token, email string) (string, error)
 }
 
 var _ Service = (*usersService)(nil)
 
 type usersService struct {
-	users  UserRepository
-	hasher Hasher
-	idp    IdentityProvider
+	users    UserRepository
+	groups   GroupRepository
+	keys     KeyRepository
+	roles    RoleRepository
+	orgs     OrgRepository
+	totps    TOTPRepository
+	admins   map[string]bool
+	hasher   Hasher
+	idp      IdentityProvider
+	uuidp    IDProvider
+	otpp     OTPProvider
+	policy   PasswordPolicy
+	breaches BreachChecker
+	lockout  LockoutPolicy
+	attempts LoginAttempts
 }
 
-// New instantiates the users service implementation.
-func New(users UserRepository, hasher Hasher, idp IdentityProvider) Service {
-	return &usersService{users: users, hasher: hasher, idp: idp}
+// New instantiates the users service implementation. The provided admins
+// are assigned the admin role regardless of the role stored for them, so
+// that the first admin can assign roles to the other users. Nil breach
+// checker disables the breach checks, and nil login attempts disable the
+// lockout.
+func New(users UserRepository, groups GroupRepository, keys KeyRepository, roles RoleRepository, orgs OrgRepository, totps TOTPRepository, admins []string, hasher Hasher, idp IdentityProvider, uuidp IDProvider, otpp OTPProvider, policy PasswordPolicy, breaches BreachChecker, lockout LockoutPolicy, attempts LoginAttempts) Service {
+	adm := make(map[string]bool, len(admins))
+	for _, admin := range admins {
+		adm[admin] = true
+	}
+
+	return &usersService{
+		users:    users,
+		groups:   groups,
+		keys:     keys,
+		roles:    roles,
+		orgs:     orgs,
+		totps:    totps,
+		admins:   adm,
+		hasher:   hasher,
+		idp:      idp,
+		uuidp:    uuidp,
+		otpp:     otpp,
+		policy:   policy,
+		breaches: breaches,
+		lockout:  lockout,
+		attempts: attempts,
+	}
 }
 
 func (svc usersService) Register(ctx context.Context, user User) error {
+	if err := svc.checkPassword(ctx, user.Password); err != nil {
+		return err
+	}
+
 	hash, err := svc.hasher.Hash(user.Password)
 	if err != nil {
 		return ErrMalformedEntity
@@ -72,31 +260,200 @@ func (svc usersService) Register(ctx context.Context, user User) error {
 	return svc.users.Save(ctx, user)
 }
 
-func (svc usersService) Login(ctx context.Context, user User) (string, error) {
+func (svc usersService) Login(ctx context.Context, user User, otp string) (string, error) {
+	if svc.locked(ctx, user.Email) {
+		return "", ErrAccountLocked
+	}
+
 	dbUser, err := svc.users.RetrieveByID(ctx, user.Email)
 	if err != nil {
+		svc.loginFailed(ctx, user.Email)
 		return "", ErrUnauthorizedAccess
 	}
 
 	if err := svc.hasher.Compare(user.Password, dbUser.Password); err != nil {
+		svc.loginFailed(ctx, user.Email)
+		return "", ErrUnauthorizedAccess
+	}
+
+	if dbUser.Disabled {
 		return "", ErrUnauthorizedAccess
 	}
 
+	if err := svc.checkOTP(ctx, user.Email, otp); err != nil {
+		if err == ErrUnauthorizedAccess {
+			svc.loginFailed(ctx, user.Email)
+		}
+		return "", err
+	}
+
+	if svc.attempts != nil {
+		svc.attempts.Reset(ctx, user.Email)
+	}
+
+	// Since the plain-text password is known only on login, outdated hashes
+	// are replaced here. Failure to do so must not prevent the login.
+	if svc.hasher.NeedsRehash(dbUser.Password) {
+		if hash, err := svc.hasher.Hash(user.Password); err == nil {
+			svc.users.UpdatePassword(ctx, user.Email, hash)
+		}
+	}
+
 	return svc.idp.TemporaryKey(user.Email)
 }
 
+func (svc usersService) EnrollTOTP(ctx context.Context, token string) (string, string, error) {
+	email, err := svc.identifyLogin(ctx, token)
+	if err != nil {
+		return "", "", err
+	}
+
+	totp, err := svc.totps.RetrieveByID(ctx, email)
+	if err != nil && err != ErrNotFound {
+		return "", "", err
+	}
+
+	// Enabled second factor can't be replaced, since that would allow
+	// bypassing it using the stolen login token.
+	if totp.Enabled {
+		return "", "", ErrConflict
+	}
+
+	secret, err := svc.otpp.Secret()
+	if err != nil {
+		return "", "", err
+	}
+
+	if err := svc.totps.Save(ctx, TOTP{Email: email, Secret: secret}); err != nil {
+		return "", "", err
+	}
+
+	return secret, svc.otpp.URI(secret, email), nil
+}
+
+func (svc usersService) VerifyTOTP(ctx context.Context, token, otp string) ([]string, error) {
+	email, err := svc.identifyLogin(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	totp, err := svc.totps.RetrieveByID(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+
+	if totp.Enabled {
+		return nil, ErrConflict
+	}
+
+	counter, ok := svc.otpp.Validate(totp.Secret, otp, time.Now())
+	if !ok {
+		return nil, ErrUnauthorizedAccess
+	}
+
+	// The password used for the verification can't be used for the login.
+	if err := svc.acceptOTP(ctx, email, counter); err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, RecoveryCodes)
+	hashes := make([]string, RecoveryCodes)
+	for i := range codes {
+		if codes[i], err = svc.uuidp.ID(); err != nil {
+			return nil, err
+		}
+		hashes[i] = hashSecret(codes[i])
+	}
+
+	if err := svc.totps.Enable(ctx, email, hashes); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+func (svc usersService) ChangePassword(ctx context.Context, token, oldPassword, password string) error {
+	email, err := svc.identifyLogin(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	dbUser, err := svc.users.RetrieveByID(ctx, email)
+	if err != nil {
+		return err
+	}
+
+	if err := svc.hasher.Compare(oldPassword, dbUser.Password); err != nil {
+		return ErrUnauthorizedAccess
+	}
+
+	if err := svc.checkPassword(ctx, password); err != nil {
+		return err
+	}
+
+	hash, err := svc.hasher.Hash(password)
+	if err != nil {
+		return ErrMalformedEntity
+	}
+
+	return svc.users.UpdatePassword(ctx, email, hash)
+}
+
 func (svc usersService) Identify(token string) (string, error) {
-	id, err := svc.idp.Identity(token)
+	id, err := svc.Authorize(context.Background(), token)
 	if err != nil {
-		return "", ErrUnauthorizedAccess
+		return "", err
 	}
-	return id, nil
+
+	return id.ID, nil
+}
+
+func (svc usersService) Authorize(ctx context.Context, token string) (Identity, error) {
+	if strings.HasPrefix(token, KeyPrefix) {
+		email, err := svc.identify(ctx, token, ScopeServices)
+		if err != nil {
+			return Identity{}, err
+		}
+
+		return svc.personalIdentity(ctx, email)
+	}
+
+	subject, err := svc.idp.Identity(token)
+	if err != nil {
+		return Identity{}, ErrUnauthorizedAccess
+	}
+
+	org, email := parseSubject(subject)
+	disabled, err := svc.disabled(ctx, email)
+	if err != nil {
+		return Identity{}, err
+	}
+	if disabled {
+		return Identity{}, ErrUnauthorizedAccess
+	}
+
+	if org == "" {
+		return svc.personalIdentity(ctx, email)
+	}
+
+	role, err := svc.orgs.RetrieveRole(ctx, org, email)
+	if err != nil {
+		return Identity{}, ErrUnauthorizedAccess
+	}
+
+	// Organization admins manage the organization members only, so
+	// they aren't granted the platform admin role in the other services.
+	if role == RoleAdmin {
+		role = RoleEditor
+	}
+
+	return Identity{ID: org, Role: role}, nil
 }
 
 func (svc usersService) UserInfo(ctx context.Context, token string) (User, error) {
-	id, err := svc.idp.Identity(token)
+	id, err := svc.identify(ctx, token, ScopeUsers)
 	if err != nil {
-		return User{}, ErrUnauthorizedAccess
+		return User{}, err
 	}
 
 	dbUser, err := svc.users.RetrieveByID(ctx, id)
@@ -110,3 +467,660 @@ func (svc usersService) UserInfo(ctx context.Context, token string) (User, error
 		Metadata: dbUser.Metadata,
 	}, nil
 }
+
+func (svc usersService) Unregister(ctx context.Context, token string) error {
+	id, err := svc.identifyLogin(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	return svc.users.Remove(ctx, id)
+}
+
+func (svc usersService) CreateGroup(ctx context.Context, token string, group Group) (string, error) {
+	id, err := svc.identify(ctx, token, ScopeGroups)
+	if err != nil {
+		return "", err
+	}
+
+	if err := svc.authorize(ctx, id, RoleEditor); err != nil {
+		return "", err
+	}
+
+	group.ID, err = svc.uuidp.ID()
+	if err != nil {
+		return "", err
+	}
+	group.Owner = id
+
+	if err := svc.groups.Save(ctx, group); err != nil {
+		return "", err
+	}
+
+	return group.ID, nil
+}
+
+func (svc usersService) ViewGroup(ctx context.Context, token, id string) (Group, error) {
+	user, err := svc.identify(ctx, token, ScopeGroups)
+	if err != nil {
+		return Group{}, err
+	}
+
+	group, err := svc.groups.RetrieveByID(ctx, id)
+	if err != nil {
+		return Group{}, err
+	}
+
+	// Groups are visible to their members only, so the existence of the
+	// group isn't revealed to other users.
+	for _, m := range group.Members {
+		if m == user {
+			return group, nil
+		}
+	}
+
+	return Group{}, ErrNotFound
+}
+
+func (svc usersService) ListGroups(ctx context.Context, token string) ([]Group, error) {
+	// Organizations aren't members of any groups, so nothing is shared
+	// with them.
+	if subject, err := svc.idp.Identity(token); err == nil {
+		if org, _ := parseSubject(subject); org != "" {
+			return []Group{}, nil
+		}
+	}
+
+	id, err := svc.identify(ctx, token, ScopeGroups)
+	if err != nil {
+		return nil, err
+	}
+
+	return svc.groups.RetrieveAll(ctx, id)
+}
+
+func (svc usersService) RemoveGroup(ctx context.Context, token, id string) error {
+	owner, err := svc.identify(ctx, token, ScopeGroups)
+	if err != nil {
+		return err
+	}
+
+	if err := svc.authorize(ctx, owner, RoleEditor); err != nil {
+		return err
+	}
+
+	return svc.groups.Remove(ctx, owner, id)
+}
+
+func (svc usersService) AssignUser(ctx context.Context, token, id, email string) error {
+	if _, err := svc.ownedGroup(ctx, token, id); err != nil {
+		return err
+	}
+
+	if _, err := svc.users.RetrieveByID(ctx, email); err != nil {
+		return err
+	}
+
+	return svc.groups.AssignMember(ctx, id, email)
+}
+
+func (svc usersService) UnassignUser(ctx context.Context, token, id, email string) error {
+	group, err := svc.ownedGroup(ctx, token, id)
+	if err != nil {
+		return err
+	}
+
+	if group.Owner == email {
+		return ErrMalformedEntity
+	}
+
+	return svc.groups.UnassignMember(ctx, id, email)
+}
+
+func (svc usersService) ownedGroup(ctx context.Context, token, id string) (Group, error) {
+	owner, err := svc.identify(ctx, token, ScopeGroups)
+	if err != nil {
+		return Group{}, err
+	}
+
+	if err := svc.authorize(ctx, owner, RoleEditor); err != nil {
+		return Group{}, err
+	}
+
+	group, err := svc.groups.RetrieveByID(ctx, id)
+	if err != nil {
+		return Group{}, err
+	}
+
+	if group.Owner != owner {
+		return Group{}, ErrNotFound
+	}
+
+	return group, nil
+}
+
+func (svc usersService) IssueKey(ctx context.Context, token string, key Key) (Key, string, error) {
+	owner, err := svc.identifyLogin(ctx, token)
+	if err != nil {
+		return Key{}, "", err
+	}
+
+	if err := key.Validate(); err != nil {
+		return Key{}, "", err
+	}
+
+	key.IssuedAt = time.Now().UTC()
+	if key.Expired(key.IssuedAt) {
+		return Key{}, "", ErrMalformedEntity
+	}
+
+	key.ID, err = svc.uuidp.ID()
+	if err != nil {
+		return Key{}, "", err
+	}
+
+	secret, err := svc.uuidp.ID()
+	if err != nil {
+		return Key{}, "", err
+	}
+
+	key.Owner = owner
+	key.Secret = hashSecret(secret)
+	if err := svc.keys.Save(ctx, key); err != nil {
+		return Key{}, "", err
+	}
+
+	key.Secret = ""
+	return key, KeyPrefix + key.ID + "." + secret, nil
+}
+
+func (svc usersService) ListKeys(ctx context.Context, token string) ([]Key, error) {
+	owner, err := svc.identifyLogin(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	return svc.keys.RetrieveAll(ctx, owner)
+}
+
+func (svc usersService) RevokeKey(ctx context.Context, token, id string) error {
+	owner, err := svc.identifyLogin(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	return svc.keys.Remove(ctx, owner, id)
+}
+
+func (svc usersService) Role(ctx context.Context, email string) (string, error) {
+	if svc.admins[email] {
+		return RoleAdmin, nil
+	}
+
+	role, err := svc.roles.RetrieveByID(ctx, email)
+	if err == ErrNotFound {
+		return DefaultRole, nil
+	}
+
+	return role, err
+}
+
+func (svc usersService) AssignRole(ctx context.Context, token, email, role string) error {
+	if !ValidRole(role) {
+		return ErrMalformedEntity
+	}
+
+	id, err := svc.identify(ctx, token, ScopeUsers)
+	if err != nil {
+		return err
+	}
+
+	if err := svc.authorize(ctx, id, RoleAdmin); err != nil {
+		return err
+	}
+
+	if _, err := svc.users.RetrieveByID(ctx, email); err != nil {
+		return err
+	}
+
+	return svc.roles.Save(ctx, email, role)
+}
+
+func (svc usersService) ViewRole(ctx context.Context, token, email string) (string, error) {
+	id, err := svc.identify(ctx, token, ScopeUsers)
+	if err != nil {
+		return "", err
+	}
+
+	if id != email {
+		if err := svc.authorize(ctx, id, RoleAdmin); err != nil {
+			return "", err
+		}
+
+		if _, err := svc.users.RetrieveByID(ctx, email); err != nil {
+			return "", err
+		}
+	}
+
+	return svc.Role(ctx, email)
+}
+
+func (svc usersService) CreateOrg(ctx context.Context, token string, org Org) (string, error) {
+	email, err := svc.identify(ctx, token, ScopeUsers)
+	if err != nil {
+		return "", err
+	}
+
+	if err := svc.authorize(ctx, email, RoleEditor); err != nil {
+		return "", err
+	}
+
+	org.ID, err = svc.uuidp.ID()
+	if err != nil {
+		return "", err
+	}
+	org.Owner = email
+
+	if err := svc.orgs.Save(ctx, org); err != nil {
+		return "", err
+	}
+
+	return org.ID, nil
+}
+
+func (svc usersService) ViewOrg(ctx context.Context, token, id string) (Org, error) {
+	email, err := svc.identify(ctx, token, ScopeUsers)
+	if err != nil {
+		return Org{}, err
+	}
+
+	// Organizations are visible to their members only, so the existence
+	// of the organization isn't revealed to other users.
+	if _, err := svc.orgs.RetrieveRole(ctx, id, email); err != nil {
+		return Org{}, err
+	}
+
+	return svc.orgs.RetrieveByID(ctx, id)
+}
+
+func (svc usersService) ListOrgs(ctx context.Context, token string) ([]Org, error) {
+	email, err := svc.identify(ctx, token, ScopeUsers)
+	if err != nil {
+		return nil, err
+	}
+
+	return svc.orgs.RetrieveAll(ctx, email)
+}
+
+func (svc usersService) RemoveOrg(ctx context.Context, token, id string) error {
+	email, err := svc.identify(ctx, token, ScopeUsers)
+	if err != nil {
+		return err
+	}
+
+	return svc.orgs.Remove(ctx, email, id)
+}
+
+func (svc usersService) AssignOrgMember(ctx context.Context, token, id, email, role string) error {
+	if !ValidRole(role) {
+		return ErrMalformedEntity
+	}
+
+	org, err := svc.administeredOrg(ctx, token, id)
+	if err != nil {
+		return err
+	}
+
+	if org.Owner == email {
+		return ErrMalformedEntity
+	}
+
+	if _, err := svc.users.RetrieveByID(ctx, email); err != nil {
+		return err
+	}
+
+	return svc.orgs.SaveMember(ctx, id, email, role)
+}
+
+func (svc usersService) UnassignOrgMember(ctx context.Context, token, id, email string) error {
+	org, err := svc.administeredOrg(ctx, token, id)
+	if err != nil {
+		return err
+	}
+
+	if org.Owner == email {
+		return ErrMalformedEntity
+	}
+
+	return svc.orgs.RemoveMember(ctx, id, email)
+}
+
+func (svc usersService) IssueOrgToken(ctx context.Context, token, id string) (string, error) {
+	email, err := svc.identifyLogin(ctx, token)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := svc.orgs.RetrieveRole(ctx, id, email); err != nil {
+		return "", err
+	}
+
+	return svc.idp.TemporaryKey(orgSubject(id, email))
+}
+
+// administeredOrg retrieves the organization identified with the provided
+// ID, if the user identified by the provided token is its admin.
+func (svc usersService) administeredOrg(ctx context.Context, token, id string) (Org, error) {
+	email, err := svc.identify(ctx, token, ScopeUsers)
+	if err != nil {
+		return Org{}, err
+	}
+
+	role, err := svc.orgs.RetrieveRole(ctx, id, email)
+	if err != nil {
+		return Org{}, err
+	}
+
+	if role != RoleAdmin {
+		return Org{}, ErrUnauthorizedAccess
+	}
+
+	return svc.orgs.RetrieveByID(ctx, id)
+}
+
+func (svc usersService) ListUsers(ctx context.Context, token string, offset, limit uint64) (UserPage, error) {
+	if _, err := svc.identifyAdmin(ctx, token); err != nil {
+		return UserPage{}, err
+	}
+
+	return svc.users.RetrieveAll(ctx, offset, limit)
+}
+
+func (svc usersService) DisableUser(ctx context.Context, token, email string) error {
+	id, err := svc.identifyAdmin(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	// Admins can't lock themselves out.
+	if id == email {
+		return ErrMalformedEntity
+	}
+
+	return svc.users.UpdateDisabled(ctx, email, true)
+}
+
+func (svc usersService) EnableUser(ctx context.Context, token, email string) error {
+	if _, err := svc.identifyAdmin(ctx, token); err != nil {
+		return err
+	}
+
+	return svc.users.UpdateDisabled(ctx, email, false)
+}
+
+func (svc usersService) Disabled(ctx context.Context, email string) (bool, error) {
+	user, err := svc.users.RetrieveByID(ctx, email)
+	if err != nil {
+		return false, err
+	}
+
+	return user.Disabled, nil
+}
+
+func (svc usersService) RemoveUser(ctx context.Context, token, email string) error {
+	id, err := svc.identifyAdmin(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	// Admins remove their own accounts using the unregister operation.
+	if id == email {
+		return ErrMalformedEntity
+	}
+
+	if _, err := svc.users.RetrieveByID(ctx, email); err != nil {
+		return err
+	}
+
+	return svc.users.Remove(ctx, email)
+}
+
+func (svc usersService) Impersonate(ctx context.Context, token, email string) (string, error) {
+	if _, err := svc.identifyAdmin(ctx, token); err != nil {
+		return "", err
+	}
+
+	user, err := svc.users.RetrieveByID(ctx, email)
+	if err != nil {
+		return "", err
+	}
+
+	if user.Disabled {
+		return "", ErrUnauthorizedAccess
+	}
+
+	return svc.idp.TemporaryKey(email)
+}
+
+// identifyAdmin resolves the user identified by the login token and checks
+// that the user is an admin.
+func (svc usersService) identifyAdmin(ctx context.Context, token string) (string, error) {
+	id, err := svc.identifyLogin(ctx, token)
+	if err != nil {
+		return "", err
+	}
+
+	if err := svc.authorize(ctx, id, RoleAdmin); err != nil {
+		return "", err
+	}
+
+	return id, nil
+}
+
+// personalIdentity returns the identity of the user acting on their own
+// behalf.
+func (svc usersService) personalIdentity(ctx context.Context, email string) (Identity, error) {
+	role, err := svc.Role(ctx, email)
+	if err != nil {
+		return Identity{}, err
+	}
+
+	return Identity{ID: email, Role: role}, nil
+}
+
+// authorize checks if the role of the user with the provided email grants
+// the required role.
+func (svc usersService) authorize(ctx context.Context, email, required string) error {
+	role, err := svc.Role(ctx, email)
+	if err != nil {
+		return err
+	}
+
+	if !HasRole(role, required) {
+		return ErrUnauthorizedAccess
+	}
+
+	return nil
+}
+
+// identify resolves the user identified by the login token or the personal
+// access token. Personal access token has to be granted the provided scope.
+func (svc usersService) identify(ctx context.Context, token, scope string) (string, error) {
+	if !strings.HasPrefix(token, KeyPrefix) {
+		return svc.identifyLogin(ctx, token)
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(token, KeyPrefix), ".", 2)
+	if len(parts) != 2 {
+		return "", ErrUnauthorizedAccess
+	}
+
+	key, err := svc.keys.RetrieveByID(ctx, parts[0])
+	if err != nil {
+		return "", ErrUnauthorizedAccess
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hashSecret(parts[1])), []byte(key.Secret)) != 1 {
+		return "", ErrUnauthorizedAccess
+	}
+
+	if key.Expired(time.Now()) || !key.HasScope(scope) {
+		return "", ErrUnauthorizedAccess
+	}
+
+	disabled, err := svc.disabled(ctx, key.Owner)
+	if err != nil {
+		return "", err
+	}
+	if disabled {
+		return "", ErrUnauthorizedAccess
+	}
+
+	return key.Owner, nil
+}
+
+// identifyLogin resolves the user identified by the login token. It is used
+// by the operations which aren't allowed using the personal access tokens.
+func (svc usersService) identifyLogin(ctx context.Context, token string) (string, error) {
+	if strings.HasPrefix(token, KeyPrefix) {
+		return "", ErrUnauthorizedAccess
+	}
+
+	id, err := svc.idp.Identity(token)
+	if err != nil {
+		return "", ErrUnauthorizedAccess
+	}
+
+	// Organization tokens are used for accessing the other services only.
+	if org, _ := parseSubject(id); org != "" {
+		return "", ErrUnauthorizedAccess
+	}
+
+	disabled, err := svc.disabled(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	if disabled {
+		return "", ErrUnauthorizedAccess
+	}
+
+	return id, nil
+}
+
+// disabled reports whether the user with the provided email is disabled.
+// The removed user is treated as disabled, so that the tokens issued to it
+// are rejected, while the failure to retrieve the user is returned.
+func (svc usersService) disabled(ctx context.Context, email string) (bool, error) {
+	user, err := svc.users.RetrieveByID(ctx, email)
+	if err == ErrNotFound {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return user.Disabled, nil
+}
+
+// hashSecret hashes the key secret or the recovery code. Since they are
+// random, unlike the passwords, the plain hash is sufficient and keeps the
+// check cheap.
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// checkPassword verifies that the password satisfies the password policy
+// and that it didn't appear in a known data breach. Unavailability of the
+// breach check must not prevent the users from setting the password.
+func (svc usersService) checkPassword(ctx context.Context, password string) error {
+	if err := svc.policy.Validate(password); err != nil {
+		return err
+	}
+
+	if svc.breaches == nil {
+		return nil
+	}
+
+	if breached, err := svc.breaches.Breached(ctx, password); err == nil && breached {
+		return ErrBreachedPassword
+	}
+
+	return nil
+}
+
+// checkOTP verifies the second factor of the user with the provided email,
+// if the user enabled it. Each recovery code is accepted in place of the
+// one-time password once.
+func (svc usersService) checkOTP(ctx context.Context, email, otp string) error {
+	totp, err := svc.totps.RetrieveByID(ctx, email)
+	if err == ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if !totp.Enabled {
+		return nil
+	}
+
+	if otp == "" {
+		return ErrOTPRequired
+	}
+
+	if counter, ok := svc.otpp.Validate(totp.Secret, otp, time.Now()); ok {
+		return svc.acceptOTP(ctx, email, counter)
+	}
+
+	err = svc.totps.RemoveRecoveryCode(ctx, email, hashSecret(otp))
+	if err == ErrNotFound {
+		return ErrUnauthorizedAccess
+	}
+
+	return err
+}
+
+// acceptOTP accepts the one-time password generated for the provided
+// counter. The password is rejected if it, or the later one, has already
+// been accepted, so that the intercepted password can't be replayed.
+func (svc usersService) acceptOTP(ctx context.Context, email string, counter int64) error {
+	err := svc.totps.UpdateCounter(ctx, email, counter)
+	if err == ErrNotFound {
+		return ErrUnauthorizedAccess
+	}
+
+	return err
+}
+
+// locked reports whether the account with the provided email is locked.
+// The lockout is best effort, so the failure to check the lock doesn't
+// prevent the login.
+func (svc usersService) locked(ctx context.Context, email string) bool {
+	if svc.attempts == nil {
+		return false
+	}
+
+	d, err := svc.attempts.Locked(ctx, email)
+	return err == nil && d > 0
+}
+
+// loginFailed records the failed login and locks the account once the
+// lockout policy is violated. The failures are recorded for the unknown
+// emails too, so that the lockout doesn't reveal the registered accounts.
+func (svc usersService) loginFailed(ctx context.Context, email string) {
+	if svc.attempts == nil {
+		return
+	}
+
+	// Failures are kept longer than the longest lock, so that the lock
+	// duration keeps growing for the repeated failures after the lock.
+	failures, err := svc.attempts.Fail(ctx, email, 2*svc.lockout.MaxDuration)
+	if err != nil {
+		return
+	}
+
+	if d := svc.lockout.lockDuration(failures); d > 0 {
+		svc.attempts.Lock(ctx, email, d)
+	}
+}
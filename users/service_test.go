@@ -5,24 +5,39 @@ package users_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/mainflux/mainflux/users"
+	"github.com/mainflux/mainflux/users/argon2"
+	"github.com/mainflux/mainflux/users/bcrypt"
 	"github.com/mainflux/mainflux/users/mocks"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 const wrong string = "wrong-value"
 
-var user = users.User{Email: "user@example.com", Password: "password"}
+var (
+	user  = users.User{Email: "user@example.com", Password: "password"}
+	admin = users.User{Email: "admin@example.com", Password: "password"}
+)
 
 func newService() users.Service {
 	repo := mocks.NewUserRepository()
+	groups := mocks.NewGroupRepository()
+	keys := mocks.NewKeyRepository()
+	roles := mocks.NewRoleRepository()
+	orgs := mocks.NewOrgRepository()
+	totps := mocks.NewTOTPRepository()
 	hasher := mocks.NewHasher()
 	idp := mocks.NewIdentityProvider()
+	uuidp := mocks.NewIDProvider()
+	otpp := mocks.NewOTPProvider()
 
-	return users.New(repo, hasher, idp)
+	return users.New(repo, groups, keys, roles, orgs, totps, []string{admin.Email}, hasher, idp, uuidp, otpp, users.PasswordPolicy{}, nil, users.LockoutPolicy{}, nil)
 }
 
 func TestRegister(t *testing.T) {
@@ -88,15 +103,404 @@ func TestLogin(t *testing.T) {
 	}
 
 	for desc, tc := range cases {
-		_, err := svc.Login(context.Background(), tc.user)
+		_, err := svc.Login(context.Background(), tc.user, "")
 		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
 	}
 }
 
+func TestLoginRehash(t *testing.T) {
+	repo := mocks.NewUserRepository()
+	legacy := bcrypt.New()
+	hasher := argon2.New(argon2.Config{Time: 1, Memory: 1024, Threads: 1}, legacy)
+	svc := users.New(repo, mocks.NewGroupRepository(), mocks.NewKeyRepository(), mocks.NewRoleRepository(), mocks.NewOrgRepository(), mocks.NewTOTPRepository(), nil, hasher, mocks.NewIdentityProvider(), mocks.NewIDProvider(), mocks.NewOTPProvider(), users.PasswordPolicy{}, nil, users.LockoutPolicy{}, nil)
+
+	hash, err := legacy.Hash(user.Password)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = repo.Save(context.Background(), users.User{Email: user.Email, Password: hash})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	_, err = svc.Login(context.Background(), users.User{Email: user.Email, Password: wrong}, "")
+	assert.Equal(t, users.ErrUnauthorizedAccess, err, fmt.Sprintf("login with wrong password: expected %s got %s\n", users.ErrUnauthorizedAccess, err))
+	dbUser, _ := repo.RetrieveByID(context.Background(), user.Email)
+	assert.Equal(t, hash, dbUser.Password, "expected hash to be kept after failed login")
+
+	_, err = svc.Login(context.Background(), user, "")
+	assert.Nil(t, err, fmt.Sprintf("login with legacy hash: unexpected error: %s", err))
+	dbUser, _ = repo.RetrieveByID(context.Background(), user.Email)
+	assert.False(t, hasher.NeedsRehash(dbUser.Password), "expected hash to be replaced after login")
+
+	_, err = svc.Login(context.Background(), user, "")
+	assert.Nil(t, err, fmt.Sprintf("login with rehashed password: unexpected error: %s", err))
+}
+
+func TestRegisterPasswordPolicy(t *testing.T) {
+	policy := users.PasswordPolicy{MinLength: 8, RequireDigit: true}
+	breaches := mocks.NewBreachChecker("passw0rd")
+	svc := users.New(mocks.NewUserRepository(), mocks.NewGroupRepository(), mocks.NewKeyRepository(), mocks.NewRoleRepository(), mocks.NewOrgRepository(), mocks.NewTOTPRepository(), nil, mocks.NewHasher(), mocks.NewIdentityProvider(), mocks.NewIDProvider(), mocks.NewOTPProvider(), policy, breaches, users.LockoutPolicy{}, nil)
+
+	cases := []struct {
+		desc     string
+		password string
+		err      error
+	}{
+		{
+			desc:     "register user with too short password",
+			password: "pa55",
+			err:      users.ErrWeakPassword,
+		},
+		{
+			desc:     "register user with password without digit",
+			password: "password",
+			err:      users.ErrWeakPassword,
+		},
+		{
+			desc:     "register user with breached password",
+			password: "passw0rd",
+			err:      users.ErrBreachedPassword,
+		},
+		{
+			desc:     "register user with valid password",
+			password: "pa55word",
+			err:      nil,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.Register(context.Background(), users.User{Email: user.Email, Password: tc.password})
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestLoginLockout(t *testing.T) {
+	attempts := mocks.NewLoginAttempts()
+	lockout := users.LockoutPolicy{Attempts: 3, Duration: time.Minute, MaxDuration: 4 * time.Minute}
+	svc := users.New(mocks.NewUserRepository(), mocks.NewGroupRepository(), mocks.NewKeyRepository(), mocks.NewRoleRepository(), mocks.NewOrgRepository(), mocks.NewTOTPRepository(), nil, mocks.NewHasher(), mocks.NewIdentityProvider(), mocks.NewIDProvider(), mocks.NewOTPProvider(), users.PasswordPolicy{}, nil, lockout, attempts)
+	svc.Register(context.Background(), user)
+
+	// Cases are executed in order. Expiration of the lock is simulated by
+	// replacing the lock with the expired one.
+	cases := []struct {
+		desc     string
+		password string
+		expire   bool
+		err      error
+		lock     time.Duration
+	}{
+		{
+			desc:     "first failed login",
+			password: wrong,
+			err:      users.ErrUnauthorizedAccess,
+			lock:     0,
+		},
+		{
+			desc:     "second failed login",
+			password: wrong,
+			err:      users.ErrUnauthorizedAccess,
+			lock:     0,
+		},
+		{
+			desc:     "third failed login",
+			password: wrong,
+			err:      users.ErrUnauthorizedAccess,
+			lock:     time.Minute,
+		},
+		{
+			desc:     "login to locked account",
+			password: user.Password,
+			err:      users.ErrAccountLocked,
+			lock:     time.Minute,
+		},
+		{
+			desc:     "failed login after lock expiration",
+			password: wrong,
+			expire:   true,
+			err:      users.ErrUnauthorizedAccess,
+			lock:     2 * time.Minute,
+		},
+		{
+			desc:     "another failed login after lock expiration",
+			password: wrong,
+			expire:   true,
+			err:      users.ErrUnauthorizedAccess,
+			lock:     4 * time.Minute,
+		},
+		{
+			desc:     "failed login exceeding max lock duration",
+			password: wrong,
+			expire:   true,
+			err:      users.ErrUnauthorizedAccess,
+			lock:     4 * time.Minute,
+		},
+		{
+			desc:     "login after lock expiration",
+			password: user.Password,
+			expire:   true,
+			err:      nil,
+			lock:     0,
+		},
+		{
+			desc:     "failed login after successful login",
+			password: wrong,
+			err:      users.ErrUnauthorizedAccess,
+			lock:     0,
+		},
+	}
+
+	for _, tc := range cases {
+		if tc.expire {
+			attempts.Lock(context.Background(), user.Email, 0)
+		}
+
+		_, err := svc.Login(context.Background(), users.User{Email: user.Email, Password: tc.password}, "")
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+
+		lock, _ := attempts.Locked(context.Background(), user.Email)
+		assert.True(t, lock <= tc.lock && lock > tc.lock-time.Second, fmt.Sprintf("%s: expected lock of %s got %s\n", tc.desc, tc.lock, lock))
+	}
+}
+
+func TestLoginLockoutUnknownEmail(t *testing.T) {
+	attempts := mocks.NewLoginAttempts()
+	lockout := users.LockoutPolicy{Attempts: 1, Duration: time.Minute, MaxDuration: time.Minute}
+	svc := users.New(mocks.NewUserRepository(), mocks.NewGroupRepository(), mocks.NewKeyRepository(), mocks.NewRoleRepository(), mocks.NewOrgRepository(), mocks.NewTOTPRepository(), nil, mocks.NewHasher(), mocks.NewIdentityProvider(), mocks.NewIDProvider(), mocks.NewOTPProvider(), users.PasswordPolicy{}, nil, lockout, attempts)
+
+	_, err := svc.Login(context.Background(), user, "")
+	assert.Equal(t, users.ErrUnauthorizedAccess, err, fmt.Sprintf("login with unknown email: expected %s got %s\n", users.ErrUnauthorizedAccess, err))
+
+	_, err = svc.Login(context.Background(), user, "")
+	assert.Equal(t, users.ErrAccountLocked, err, fmt.Sprintf("login with locked unknown email: expected %s got %s\n", users.ErrAccountLocked, err))
+}
+
+func TestChangePassword(t *testing.T) {
+	policy := users.PasswordPolicy{MinLength: 8}
+	breaches := mocks.NewBreachChecker("breached")
+	svc := users.New(mocks.NewUserRepository(), mocks.NewGroupRepository(), mocks.NewKeyRepository(), mocks.NewRoleRepository(), mocks.NewOrgRepository(), mocks.NewTOTPRepository(), nil, mocks.NewHasher(), mocks.NewIdentityProvider(), mocks.NewIDProvider(), mocks.NewOTPProvider(), policy, breaches, users.LockoutPolicy{}, nil)
+	svc.Register(context.Background(), user)
+	token, _ := svc.Login(context.Background(), user, "")
+	_, pat, _ := svc.IssueKey(context.Background(), token, users.Key{Name: "ci", Scopes: []string{users.ScopeServices}})
+	newPassword := "new-password"
+
+	cases := []struct {
+		desc        string
+		token       string
+		oldPassword string
+		password    string
+		err         error
+	}{
+		{
+			desc:        "change password with invalid token",
+			token:       "",
+			oldPassword: user.Password,
+			password:    newPassword,
+			err:         users.ErrUnauthorizedAccess,
+		},
+		{
+			desc:        "change password with personal access token",
+			token:       pat,
+			oldPassword: user.Password,
+			password:    newPassword,
+			err:         users.ErrUnauthorizedAccess,
+		},
+		{
+			desc:        "change password with wrong current password",
+			token:       token,
+			oldPassword: wrong,
+			password:    newPassword,
+			err:         users.ErrUnauthorizedAccess,
+		},
+		{
+			desc:        "change password to weak password",
+			token:       token,
+			oldPassword: user.Password,
+			password:    "short",
+			err:         users.ErrWeakPassword,
+		},
+		{
+			desc:        "change password to breached password",
+			token:       token,
+			oldPassword: user.Password,
+			password:    "breached",
+			err:         users.ErrBreachedPassword,
+		},
+		{
+			desc:        "change password",
+			token:       token,
+			oldPassword: user.Password,
+			password:    newPassword,
+			err:         nil,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.ChangePassword(context.Background(), tc.token, tc.oldPassword, tc.password)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	_, err := svc.Login(context.Background(), user, "")
+	assert.Equal(t, users.ErrUnauthorizedAccess, err, fmt.Sprintf("login with old password: expected %s got %s\n", users.ErrUnauthorizedAccess, err))
+
+	_, err = svc.Login(context.Background(), users.User{Email: user.Email, Password: newPassword}, "")
+	assert.Nil(t, err, fmt.Sprintf("login with new password: unexpected error: %s", err))
+}
+
+func TestEnrollVerifyTOTP(t *testing.T) {
+	svc := newService()
+	svc.Register(context.Background(), user)
+	token, _ := svc.Login(context.Background(), user, "")
+	_, pat, _ := svc.IssueKey(context.Background(), token, users.Key{Name: "ci", Scopes: []string{users.ScopeServices}})
+
+	_, _, err := svc.EnrollTOTP(context.Background(), "")
+	assert.Equal(t, users.ErrUnauthorizedAccess, err, fmt.Sprintf("enroll with invalid token: expected %s got %s\n", users.ErrUnauthorizedAccess, err))
+	_, _, err = svc.EnrollTOTP(context.Background(), pat)
+	assert.Equal(t, users.ErrUnauthorizedAccess, err, fmt.Sprintf("enroll with personal access token: expected %s got %s\n", users.ErrUnauthorizedAccess, err))
+
+	replaced, _, err := svc.EnrollTOTP(context.Background(), token)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	secret, uri, err := svc.EnrollTOTP(context.Background(), token)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.NotEmpty(t, uri, "expected provisioning URI")
+
+	// The mock secret is the valid one-time password of the first period.
+	cases := []struct {
+		desc  string
+		token string
+		otp   string
+		codes int
+		err   error
+	}{
+		{
+			desc:  "verify enrollment with invalid token",
+			token: "",
+			otp:   secret,
+			err:   users.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "verify enrollment with wrong password",
+			token: token,
+			otp:   wrong,
+			err:   users.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "verify enrollment with password of replaced secret",
+			token: token,
+			otp:   replaced,
+			err:   users.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "verify enrollment",
+			token: token,
+			otp:   secret,
+			codes: users.RecoveryCodes,
+			err:   nil,
+		},
+		{
+			desc:  "verify enabled second factor",
+			token: token,
+			otp:   secret,
+			err:   users.ErrConflict,
+		},
+	}
+
+	for _, tc := range cases {
+		codes, err := svc.VerifyTOTP(context.Background(), tc.token, tc.otp)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Len(t, codes, tc.codes, fmt.Sprintf("%s: expected %d recovery codes got %d\n", tc.desc, tc.codes, len(codes)))
+	}
+
+	_, _, err = svc.EnrollTOTP(context.Background(), token)
+	assert.Equal(t, users.ErrConflict, err, fmt.Sprintf("enroll with enabled second factor: expected %s got %s\n", users.ErrConflict, err))
+}
+
+func TestLoginTOTP(t *testing.T) {
+	svc := newService()
+	svc.Register(context.Background(), user)
+	token, _ := svc.Login(context.Background(), user, "")
+	secret, _, _ := svc.EnrollTOTP(context.Background(), token)
+
+	_, err := svc.Login(context.Background(), user, "")
+	assert.Nil(t, err, fmt.Sprintf("login with pending second factor: unexpected error: %s", err))
+
+	codes, err := svc.VerifyTOTP(context.Background(), token, secret)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc     string
+		password string
+		otp      string
+		err      error
+	}{
+		{
+			desc:     "login without one-time password",
+			password: user.Password,
+			otp:      "",
+			err:      users.ErrOTPRequired,
+		},
+		{
+			desc:     "login with wrong password and valid one-time password",
+			password: wrong,
+			otp:      secret,
+			err:      users.ErrUnauthorizedAccess,
+		},
+		{
+			desc:     "login with wrong one-time password",
+			password: user.Password,
+			otp:      wrong,
+			err:      users.ErrUnauthorizedAccess,
+		},
+		{
+			desc:     "login with one-time password used for verification",
+			password: user.Password,
+			otp:      secret,
+			err:      users.ErrUnauthorizedAccess,
+		},
+		{
+			desc:     "login with valid one-time password",
+			password: user.Password,
+			otp:      secret + "-3",
+			err:      nil,
+		},
+		{
+			desc:     "login with used one-time password",
+			password: user.Password,
+			otp:      secret + "-3",
+			err:      users.ErrUnauthorizedAccess,
+		},
+		{
+			desc:     "login with one-time password of earlier period",
+			password: user.Password,
+			otp:      secret + "-2",
+			err:      users.ErrUnauthorizedAccess,
+		},
+		{
+			desc:     "login with recovery code",
+			password: user.Password,
+			otp:      codes[0],
+			err:      nil,
+		},
+		{
+			desc:     "login with used recovery code",
+			password: user.Password,
+			otp:      codes[0],
+			err:      users.ErrUnauthorizedAccess,
+		},
+		{
+			desc:     "login with another recovery code",
+			password: user.Password,
+			otp:      codes[1],
+			err:      nil,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := svc.Login(context.Background(), users.User{Email: user.Email, Password: tc.password}, tc.otp)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
 func TestIdentify(t *testing.T) {
 	svc := newService()
 	svc.Register(context.Background(), user)
-	key, _ := svc.Login(context.Background(), user)
+	key, _ := svc.Login(context.Background(), user, "")
 
 	cases := map[string]struct {
 		key string
@@ -111,3 +515,1055 @@ func TestIdentify(t *testing.T) {
 		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
 	}
 }
+
+func TestUnregister(t *testing.T) {
+	svc := newService()
+	svc.Register(context.Background(), user)
+	key, _ := svc.Login(context.Background(), user, "")
+
+	cases := []struct {
+		desc string
+		key  string
+		err  error
+	}{
+		{
+			desc: "unregister user with invalid token",
+			key:  "",
+			err:  users.ErrUnauthorizedAccess,
+		},
+		{
+			desc: "unregister existing user",
+			key:  key,
+			err:  nil,
+		},
+		{
+			desc: "unregister unregistered user",
+			key:  key,
+			err:  users.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.Unregister(context.Background(), tc.key)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	_, err := svc.Login(context.Background(), user, "")
+	assert.Equal(t, users.ErrUnauthorizedAccess, err, fmt.Sprintf("login unregistered user: expected %s got %s\n", users.ErrUnauthorizedAccess, err))
+}
+
+var member = users.User{Email: "member@example.com", Password: "password"}
+
+func TestCreateGroup(t *testing.T) {
+	svc := newService()
+	svc.Register(context.Background(), user)
+
+	cases := map[string]struct {
+		group users.Group
+		token string
+		err   error
+	}{
+		"create new group": {
+			group: users.Group{Name: "group"},
+			token: user.Email,
+			err:   nil,
+		},
+		"create new group with wrong credentials": {
+			group: users.Group{Name: "group"},
+			token: "",
+			err:   users.ErrUnauthorizedAccess,
+		},
+	}
+
+	for desc, tc := range cases {
+		_, err := svc.CreateGroup(context.Background(), tc.token, tc.group)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
+
+func TestViewGroup(t *testing.T) {
+	svc := newService()
+	svc.Register(context.Background(), user)
+	svc.Register(context.Background(), member)
+	id, err := svc.CreateGroup(context.Background(), user.Email, users.Group{Name: "group"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := map[string]struct {
+		id      string
+		token   string
+		members []string
+		err     error
+	}{
+		"view group as owner": {
+			id:      id,
+			token:   user.Email,
+			members: []string{user.Email},
+			err:     nil,
+		},
+		"view group as non-member": {
+			id:    id,
+			token: member.Email,
+			err:   users.ErrNotFound,
+		},
+		"view non-existing group": {
+			id:    wrong,
+			token: user.Email,
+			err:   users.ErrNotFound,
+		},
+		"view group with wrong credentials": {
+			id:    id,
+			token: "",
+			err:   users.ErrUnauthorizedAccess,
+		},
+	}
+
+	for desc, tc := range cases {
+		group, err := svc.ViewGroup(context.Background(), tc.token, tc.id)
+		assert.Equal(t, tc.members, group.Members, fmt.Sprintf("%s: expected %v got %v\n", desc, tc.members, group.Members))
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
+
+func TestListGroups(t *testing.T) {
+	svc := newService()
+	svc.Register(context.Background(), user)
+	svc.Register(context.Background(), member)
+
+	n := 3
+	for i := 0; i < n; i++ {
+		id, err := svc.CreateGroup(context.Background(), user.Email, users.Group{Name: "group"})
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		if i == 0 {
+			err := svc.AssignUser(context.Background(), user.Email, id, member.Email)
+			require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		}
+	}
+
+	cases := map[string]struct {
+		token string
+		size  int
+		err   error
+	}{
+		"list groups as owner": {
+			token: user.Email,
+			size:  n,
+			err:   nil,
+		},
+		"list groups as member": {
+			token: member.Email,
+			size:  1,
+			err:   nil,
+		},
+		"list groups with wrong credentials": {
+			token: "",
+			size:  0,
+			err:   users.ErrUnauthorizedAccess,
+		},
+	}
+
+	for desc, tc := range cases {
+		groups, err := svc.ListGroups(context.Background(), tc.token)
+		assert.Equal(t, tc.size, len(groups), fmt.Sprintf("%s: expected %d got %d\n", desc, tc.size, len(groups)))
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
+
+func TestRemoveGroup(t *testing.T) {
+	svc := newService()
+	svc.Register(context.Background(), user)
+	svc.Register(context.Background(), member)
+	id, err := svc.CreateGroup(context.Background(), user.Email, users.Group{Name: "group"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		id    string
+		token string
+		err   error
+	}{
+		{
+			desc:  "remove group with wrong credentials",
+			id:    id,
+			token: "",
+			err:   users.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "remove group as non-owner",
+			id:    id,
+			token: member.Email,
+			err:   nil,
+		},
+		{
+			desc:  "remove existing group",
+			id:    id,
+			token: user.Email,
+			err:   nil,
+		},
+		{
+			desc:  "remove removed group",
+			id:    id,
+			token: user.Email,
+			err:   nil,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.RemoveGroup(context.Background(), tc.token, tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	_, err = svc.ViewGroup(context.Background(), user.Email, id)
+	assert.Equal(t, users.ErrNotFound, err, fmt.Sprintf("view removed group: expected %s got %s\n", users.ErrNotFound, err))
+}
+
+func TestAssignUser(t *testing.T) {
+	svc := newService()
+	svc.Register(context.Background(), user)
+	svc.Register(context.Background(), member)
+	id, err := svc.CreateGroup(context.Background(), user.Email, users.Group{Name: "group"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		id    string
+		token string
+		email string
+		err   error
+	}{
+		{
+			desc:  "assign user to group",
+			id:    id,
+			token: user.Email,
+			email: member.Email,
+			err:   nil,
+		},
+		{
+			desc:  "assign already assigned user to group",
+			id:    id,
+			token: user.Email,
+			email: member.Email,
+			err:   users.ErrConflict,
+		},
+		{
+			desc:  "assign non-existing user to group",
+			id:    id,
+			token: user.Email,
+			email: wrong,
+			err:   users.ErrNotFound,
+		},
+		{
+			desc:  "assign user to group as non-owner",
+			id:    id,
+			token: member.Email,
+			email: member.Email,
+			err:   users.ErrNotFound,
+		},
+		{
+			desc:  "assign user to non-existing group",
+			id:    wrong,
+			token: user.Email,
+			email: member.Email,
+			err:   users.ErrNotFound,
+		},
+		{
+			desc:  "assign user to group with wrong credentials",
+			id:    id,
+			token: "",
+			email: member.Email,
+			err:   users.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.AssignUser(context.Background(), tc.token, tc.id, tc.email)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestUnassignUser(t *testing.T) {
+	svc := newService()
+	svc.Register(context.Background(), user)
+	svc.Register(context.Background(), member)
+	id, err := svc.CreateGroup(context.Background(), user.Email, users.Group{Name: "group"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = svc.AssignUser(context.Background(), user.Email, id, member.Email)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		id    string
+		token string
+		email string
+		err   error
+	}{
+		{
+			desc:  "unassign user from group as non-owner",
+			id:    id,
+			token: member.Email,
+			email: member.Email,
+			err:   users.ErrNotFound,
+		},
+		{
+			desc:  "unassign owner from group",
+			id:    id,
+			token: user.Email,
+			email: user.Email,
+			err:   users.ErrMalformedEntity,
+		},
+		{
+			desc:  "unassign user from group with wrong credentials",
+			id:    id,
+			token: "",
+			email: member.Email,
+			err:   users.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "unassign user from group",
+			id:    id,
+			token: user.Email,
+			email: member.Email,
+			err:   nil,
+		},
+		{
+			desc:  "unassign unassigned user from group",
+			id:    id,
+			token: user.Email,
+			email: member.Email,
+			err:   users.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.UnassignUser(context.Background(), tc.token, tc.id, tc.email)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestIssueKey(t *testing.T) {
+	svc := newService()
+	svc.Register(context.Background(), user)
+	token, _ := svc.Login(context.Background(), user, "")
+
+	_, pat, err := svc.IssueKey(context.Background(), token, users.Key{Name: "ci", Scopes: []string{users.ScopeServices}})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		token string
+		key   users.Key
+		err   error
+	}{
+		{
+			desc:  "issue key",
+			token: token,
+			key:   users.Key{Name: "ci", Scopes: []string{users.ScopeServices}},
+			err:   nil,
+		},
+		{
+			desc:  "issue key with expiration",
+			token: token,
+			key:   users.Key{Name: "ci", Scopes: []string{users.ScopeServices}, ExpiresAt: time.Now().Add(time.Hour)},
+			err:   nil,
+		},
+		{
+			desc:  "issue expired key",
+			token: token,
+			key:   users.Key{Name: "ci", Scopes: []string{users.ScopeServices}, ExpiresAt: time.Now().Add(-time.Hour)},
+			err:   users.ErrMalformedEntity,
+		},
+		{
+			desc:  "issue key without scopes",
+			token: token,
+			key:   users.Key{Name: "ci"},
+			err:   users.ErrMalformedEntity,
+		},
+		{
+			desc:  "issue key with unknown scope",
+			token: token,
+			key:   users.Key{Name: "ci", Scopes: []string{"admin"}},
+			err:   users.ErrMalformedEntity,
+		},
+		{
+			desc:  "issue key using key",
+			token: pat,
+			key:   users.Key{Name: "ci", Scopes: []string{users.ScopeServices}},
+			err:   users.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "issue key with invalid token",
+			token: "",
+			key:   users.Key{Name: "ci", Scopes: []string{users.ScopeServices}},
+			err:   users.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		key, pat, err := svc.IssueKey(context.Background(), tc.token, tc.key)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err == nil {
+			assert.Equal(t, user.Email, key.Owner, fmt.Sprintf("%s: expected owner %s got %s\n", tc.desc, user.Email, key.Owner))
+			assert.Empty(t, key.Secret, fmt.Sprintf("%s: expected key secret not to be returned\n", tc.desc))
+			assert.NotEmpty(t, pat, fmt.Sprintf("%s: expected key token\n", tc.desc))
+		}
+	}
+}
+
+func TestIdentifyKey(t *testing.T) {
+	svc := newService()
+	svc.Register(context.Background(), user)
+	token, _ := svc.Login(context.Background(), user, "")
+
+	_, services, err := svc.IssueKey(context.Background(), token, users.Key{Name: "ci", Scopes: []string{users.ScopeServices}})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	_, groups, err := svc.IssueKey(context.Background(), token, users.Key{Name: "admin", Scopes: []string{users.ScopeGroups, users.ScopeUsers}})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	revoked, pat, err := svc.IssueKey(context.Background(), token, users.Key{Name: "old", Scopes: []string{users.ScopeServices}})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = svc.RevokeKey(context.Background(), token, revoked.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		token string
+		err   error
+	}{
+		{
+			desc:  "identify with key",
+			token: services,
+			err:   nil,
+		},
+		{
+			desc:  "identify with key without services scope",
+			token: groups,
+			err:   users.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "identify with revoked key",
+			token: pat,
+			err:   users.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "identify with key with wrong secret",
+			token: services + "0",
+			err:   users.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "identify with malformed key",
+			token: users.KeyPrefix + "malformed",
+			err:   users.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		id, err := svc.Identify(tc.token)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err == nil {
+			assert.Equal(t, user.Email, id, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, user.Email, id))
+		}
+	}
+
+	_, err = svc.ListGroups(context.Background(), groups)
+	assert.Nil(t, err, fmt.Sprintf("listing groups with groups scope: unexpected error: %s", err))
+
+	_, err = svc.ListGroups(context.Background(), services)
+	assert.Equal(t, users.ErrUnauthorizedAccess, err, fmt.Sprintf("listing groups without groups scope: expected %s got %s\n", users.ErrUnauthorizedAccess, err))
+
+	err = svc.Unregister(context.Background(), groups)
+	assert.Equal(t, users.ErrUnauthorizedAccess, err, fmt.Sprintf("unregistering with key: expected %s got %s\n", users.ErrUnauthorizedAccess, err))
+}
+
+func TestListRevokeKeys(t *testing.T) {
+	svc := newService()
+	svc.Register(context.Background(), user)
+	svc.Register(context.Background(), users.User{Email: "other@example.com", Password: "password"})
+	token, _ := svc.Login(context.Background(), user, "")
+
+	key, _, err := svc.IssueKey(context.Background(), token, users.Key{Name: "ci", Scopes: []string{users.ScopeServices}})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	keys, err := svc.ListKeys(context.Background(), token)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, []users.Key{key}, keys, fmt.Sprintf("expected %v got %v\n", []users.Key{key}, keys))
+
+	cases := []struct {
+		desc  string
+		token string
+		id    string
+		err   error
+	}{
+		{
+			desc:  "revoke key with invalid token",
+			token: "",
+			id:    key.ID,
+			err:   users.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "revoke key of other user",
+			token: "other@example.com",
+			id:    key.ID,
+			err:   users.ErrNotFound,
+		},
+		{
+			desc:  "revoke key",
+			token: token,
+			id:    key.ID,
+			err:   nil,
+		},
+		{
+			desc:  "revoke non-existing key",
+			token: token,
+			id:    key.ID,
+			err:   users.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.RevokeKey(context.Background(), tc.token, tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestAssignRole(t *testing.T) {
+	svc := newService()
+	svc.Register(context.Background(), user)
+	svc.Register(context.Background(), admin)
+
+	cases := map[string]struct {
+		token string
+		email string
+		role  string
+		err   error
+	}{
+		"assign role as admin": {
+			token: admin.Email,
+			email: user.Email,
+			role:  users.RoleViewer,
+			err:   nil,
+		},
+		"assign unknown role": {
+			token: admin.Email,
+			email: user.Email,
+			role:  "owner",
+			err:   users.ErrMalformedEntity,
+		},
+		"assign role to non-existing user": {
+			token: admin.Email,
+			email: "none@example.com",
+			role:  users.RoleViewer,
+			err:   users.ErrNotFound,
+		},
+		"assign role as non-admin": {
+			token: user.Email,
+			email: user.Email,
+			role:  users.RoleAdmin,
+			err:   users.ErrUnauthorizedAccess,
+		},
+		"assign role with wrong credentials": {
+			token: "",
+			email: user.Email,
+			role:  users.RoleViewer,
+			err:   users.ErrUnauthorizedAccess,
+		},
+	}
+
+	for desc, tc := range cases {
+		err := svc.AssignRole(context.Background(), tc.token, tc.email, tc.role)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
+
+func TestViewRole(t *testing.T) {
+	svc := newService()
+	svc.Register(context.Background(), user)
+	svc.Register(context.Background(), admin)
+	other := users.User{Email: "other@example.com", Password: "password"}
+	svc.Register(context.Background(), other)
+	err := svc.AssignRole(context.Background(), admin.Email, other.Email, users.RoleViewer)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := map[string]struct {
+		token string
+		email string
+		role  string
+		err   error
+	}{
+		"view own default role": {
+			token: user.Email,
+			email: user.Email,
+			role:  users.DefaultRole,
+			err:   nil,
+		},
+		"view own assigned role": {
+			token: other.Email,
+			email: other.Email,
+			role:  users.RoleViewer,
+			err:   nil,
+		},
+		"view configured admin role": {
+			token: admin.Email,
+			email: admin.Email,
+			role:  users.RoleAdmin,
+			err:   nil,
+		},
+		"view other user role as admin": {
+			token: admin.Email,
+			email: other.Email,
+			role:  users.RoleViewer,
+			err:   nil,
+		},
+		"view other user role as non-admin": {
+			token: user.Email,
+			email: other.Email,
+			role:  "",
+			err:   users.ErrUnauthorizedAccess,
+		},
+		"view non-existing user role as admin": {
+			token: admin.Email,
+			email: "none@example.com",
+			role:  "",
+			err:   users.ErrNotFound,
+		},
+	}
+
+	for desc, tc := range cases {
+		role, err := svc.ViewRole(context.Background(), tc.token, tc.email)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+		assert.Equal(t, tc.role, role, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.role, role))
+	}
+}
+
+func TestListUsers(t *testing.T) {
+	svc := newService()
+	svc.Register(context.Background(), user)
+	svc.Register(context.Background(), admin)
+
+	cases := map[string]struct {
+		token  string
+		offset uint64
+		limit  uint64
+		size   int
+		err    error
+	}{
+		"list users as admin": {
+			token:  admin.Email,
+			offset: 0,
+			limit:  10,
+			size:   2,
+			err:    nil,
+		},
+		"list last page of users": {
+			token:  admin.Email,
+			offset: 1,
+			limit:  10,
+			size:   1,
+			err:    nil,
+		},
+		"list users with offset out of range": {
+			token:  admin.Email,
+			offset: 5,
+			limit:  10,
+			size:   0,
+			err:    nil,
+		},
+		"list users as non-admin": {
+			token:  user.Email,
+			offset: 0,
+			limit:  10,
+			size:   0,
+			err:    users.ErrUnauthorizedAccess,
+		},
+		"list users with wrong credentials": {
+			token:  "",
+			offset: 0,
+			limit:  10,
+			size:   0,
+			err:    users.ErrUnauthorizedAccess,
+		},
+	}
+
+	for desc, tc := range cases {
+		page, err := svc.ListUsers(context.Background(), tc.token, tc.offset, tc.limit)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+		assert.Equal(t, tc.size, len(page.Users), fmt.Sprintf("%s: expected %d users got %d\n", desc, tc.size, len(page.Users)))
+		for _, u := range page.Users {
+			assert.Empty(t, u.Password, fmt.Sprintf("%s: unexpected password of user %s\n", desc, u.Email))
+		}
+	}
+}
+
+func TestDisableUser(t *testing.T) {
+	svc := newService()
+	svc.Register(context.Background(), user)
+	svc.Register(context.Background(), admin)
+	_, key, err := svc.IssueKey(context.Background(), user.Email, users.Key{Name: "ci", Scopes: []string{users.ScopeServices}})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		token string
+		email string
+		err   error
+	}{
+		{
+			desc:  "disable user as non-admin",
+			token: user.Email,
+			email: user.Email,
+			err:   users.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "disable user with wrong credentials",
+			token: "",
+			email: user.Email,
+			err:   users.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "disable own account",
+			token: admin.Email,
+			email: admin.Email,
+			err:   users.ErrMalformedEntity,
+		},
+		{
+			desc:  "disable non-existing user",
+			token: admin.Email,
+			email: "none@example.com",
+			err:   users.ErrNotFound,
+		},
+		{
+			desc:  "disable user as admin",
+			token: admin.Email,
+			email: user.Email,
+			err:   nil,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.DisableUser(context.Background(), tc.token, tc.email)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	_, err = svc.Login(context.Background(), user, "")
+	assert.Equal(t, users.ErrUnauthorizedAccess, err, fmt.Sprintf("login as disabled user: expected %s got %s\n", users.ErrUnauthorizedAccess, err))
+	_, err = svc.Identify(user.Email)
+	assert.Equal(t, users.ErrUnauthorizedAccess, err, fmt.Sprintf("identify disabled user: expected %s got %s\n", users.ErrUnauthorizedAccess, err))
+	_, err = svc.Identify(key)
+	assert.Equal(t, users.ErrUnauthorizedAccess, err, fmt.Sprintf("identify disabled user's key: expected %s got %s\n", users.ErrUnauthorizedAccess, err))
+
+	err = svc.EnableUser(context.Background(), user.Email, user.Email)
+	assert.Equal(t, users.ErrUnauthorizedAccess, err, fmt.Sprintf("enable user as disabled user: expected %s got %s\n", users.ErrUnauthorizedAccess, err))
+	err = svc.EnableUser(context.Background(), admin.Email, user.Email)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	_, err = svc.Login(context.Background(), user, "")
+	assert.Nil(t, err, fmt.Sprintf("login as enabled user: unexpected error: %s", err))
+	_, err = svc.Identify(key)
+	assert.Nil(t, err, fmt.Sprintf("identify enabled user's key: unexpected error: %s", err))
+}
+
+func TestRemoveUser(t *testing.T) {
+	svc := newService()
+	svc.Register(context.Background(), user)
+	svc.Register(context.Background(), admin)
+	_, key, err := svc.IssueKey(context.Background(), user.Email, users.Key{Name: "ci", Scopes: []string{users.ScopeServices}})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		token string
+		email string
+		err   error
+	}{
+		{
+			desc:  "remove user as non-admin",
+			token: user.Email,
+			email: user.Email,
+			err:   users.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "remove own account",
+			token: admin.Email,
+			email: admin.Email,
+			err:   users.ErrMalformedEntity,
+		},
+		{
+			desc:  "remove user as admin",
+			token: admin.Email,
+			email: user.Email,
+			err:   nil,
+		},
+		{
+			desc:  "remove removed user",
+			token: admin.Email,
+			email: user.Email,
+			err:   users.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.RemoveUser(context.Background(), tc.token, tc.email)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	_, err = svc.Identify(user.Email)
+	assert.Equal(t, users.ErrUnauthorizedAccess, err, fmt.Sprintf("identify removed user: expected %s got %s\n", users.ErrUnauthorizedAccess, err))
+	_, err = svc.Identify(key)
+	assert.Equal(t, users.ErrUnauthorizedAccess, err, fmt.Sprintf("identify removed user's key: expected %s got %s\n", users.ErrUnauthorizedAccess, err))
+	_, err = svc.Authorize(context.Background(), user.Email)
+	assert.Equal(t, users.ErrUnauthorizedAccess, err, fmt.Sprintf("authorize removed user: expected %s got %s\n", users.ErrUnauthorizedAccess, err))
+}
+
+var errRetrieve = errors.New("failed to retrieve user")
+
+// failingUserRepository fails to retrieve the users, as the unavailable
+// database does.
+type failingUserRepository struct {
+	users.UserRepository
+}
+
+func (repo failingUserRepository) RetrieveByID(context.Context, string) (users.User, error) {
+	return users.User{}, errRetrieve
+}
+
+func TestIdentifyRetrieveFailure(t *testing.T) {
+	repo := failingUserRepository{mocks.NewUserRepository()}
+	svc := users.New(repo, mocks.NewGroupRepository(), mocks.NewKeyRepository(), mocks.NewRoleRepository(), mocks.NewOrgRepository(), mocks.NewTOTPRepository(), []string{admin.Email}, mocks.NewHasher(), mocks.NewIdentityProvider(), mocks.NewIDProvider(), mocks.NewOTPProvider(), users.PasswordPolicy{}, nil, users.LockoutPolicy{}, nil)
+
+	_, err := svc.Identify(user.Email)
+	assert.Equal(t, errRetrieve, err, fmt.Sprintf("identify user: expected %s got %s\n", errRetrieve, err))
+	_, err = svc.Authorize(context.Background(), user.Email)
+	assert.Equal(t, errRetrieve, err, fmt.Sprintf("authorize user: expected %s got %s\n", errRetrieve, err))
+}
+
+func TestImpersonate(t *testing.T) {
+	svc := newService()
+	svc.Register(context.Background(), user)
+	svc.Register(context.Background(), admin)
+	disabled := users.User{Email: "disabled@example.com", Password: "password"}
+	svc.Register(context.Background(), disabled)
+	err := svc.DisableUser(context.Background(), admin.Email, disabled.Email)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := map[string]struct {
+		token string
+		email string
+		err   error
+	}{
+		"impersonate user as admin": {
+			token: admin.Email,
+			email: user.Email,
+			err:   nil,
+		},
+		"impersonate disabled user": {
+			token: admin.Email,
+			email: disabled.Email,
+			err:   users.ErrUnauthorizedAccess,
+		},
+		"impersonate non-existing user": {
+			token: admin.Email,
+			email: "none@example.com",
+			err:   users.ErrNotFound,
+		},
+		"impersonate user as non-admin": {
+			token: user.Email,
+			email: admin.Email,
+			err:   users.ErrUnauthorizedAccess,
+		},
+	}
+
+	for desc, tc := range cases {
+		token, err := svc.Impersonate(context.Background(), tc.token, tc.email)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+		if err != nil {
+			continue
+		}
+
+		id, err := svc.Identify(token)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", desc, err))
+		assert.Equal(t, tc.email, id, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.email, id))
+	}
+}
+
+func TestViewerGroups(t *testing.T) {
+	svc := newService()
+	svc.Register(context.Background(), user)
+	svc.Register(context.Background(), admin)
+
+	id, err := svc.CreateGroup(context.Background(), user.Email, users.Group{Name: "group"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	err = svc.AssignRole(context.Background(), admin.Email, user.Email, users.RoleViewer)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	_, err = svc.CreateGroup(context.Background(), user.Email, users.Group{Name: "group"})
+	assert.Equal(t, users.ErrUnauthorizedAccess, err, fmt.Sprintf("create group as viewer: expected %s got %s\n", users.ErrUnauthorizedAccess, err))
+
+	err = svc.AssignUser(context.Background(), user.Email, id, admin.Email)
+	assert.Equal(t, users.ErrUnauthorizedAccess, err, fmt.Sprintf("assign user as viewer: expected %s got %s\n", users.ErrUnauthorizedAccess, err))
+
+	err = svc.RemoveGroup(context.Background(), user.Email, id)
+	assert.Equal(t, users.ErrUnauthorizedAccess, err, fmt.Sprintf("remove group as viewer: expected %s got %s\n", users.ErrUnauthorizedAccess, err))
+
+	_, err = svc.ViewGroup(context.Background(), user.Email, id)
+	assert.Nil(t, err, fmt.Sprintf("view group as viewer: unexpected error: %s", err))
+}
+
+func TestOrgMembers(t *testing.T) {
+	svc := newService()
+	svc.Register(context.Background(), user)
+	member := users.User{Email: "member@example.com", Password: "password"}
+	svc.Register(context.Background(), member)
+
+	id, err := svc.CreateOrg(context.Background(), user.Email, users.Org{Name: "org"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		token string
+		id    string
+		email string
+		role  string
+		err   error
+	}{
+		{
+			desc:  "assign member as org admin",
+			token: user.Email,
+			id:    id,
+			email: member.Email,
+			role:  users.RoleViewer,
+			err:   nil,
+		},
+		{
+			desc:  "assign member with unknown role",
+			token: user.Email,
+			id:    id,
+			email: member.Email,
+			role:  "owner",
+			err:   users.ErrMalformedEntity,
+		},
+		{
+			desc:  "assign non-existing user",
+			token: user.Email,
+			id:    id,
+			email: "none@example.com",
+			role:  users.RoleViewer,
+			err:   users.ErrNotFound,
+		},
+		{
+			desc:  "change owner role",
+			token: user.Email,
+			id:    id,
+			email: user.Email,
+			role:  users.RoleViewer,
+			err:   users.ErrMalformedEntity,
+		},
+		{
+			desc:  "assign member as non-admin member",
+			token: member.Email,
+			id:    id,
+			email: member.Email,
+			role:  users.RoleAdmin,
+			err:   users.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "assign member to non-existing org",
+			token: user.Email,
+			id:    wrong,
+			email: member.Email,
+			role:  users.RoleViewer,
+			err:   users.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.AssignOrgMember(context.Background(), tc.token, tc.id, tc.email, tc.role)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	org, err := svc.ViewOrg(context.Background(), member.Email, id)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	expected := []users.Member{{Email: member.Email, Role: users.RoleViewer}, {Email: user.Email, Role: users.RoleAdmin}}
+	assert.Equal(t, expected, org.Members, fmt.Sprintf("view org: expected %v got %v\n", expected, org.Members))
+
+	orgs, err := svc.ListOrgs(context.Background(), member.Email)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Len(t, orgs, 1, fmt.Sprintf("list orgs: expected 1 org got %d\n", len(orgs)))
+
+	err = svc.UnassignOrgMember(context.Background(), user.Email, id, user.Email)
+	assert.Equal(t, users.ErrMalformedEntity, err, fmt.Sprintf("unassign owner: expected %s got %s\n", users.ErrMalformedEntity, err))
+
+	err = svc.UnassignOrgMember(context.Background(), user.Email, id, member.Email)
+	assert.Nil(t, err, fmt.Sprintf("unassign member: unexpected error: %s\n", err))
+
+	_, err = svc.ViewOrg(context.Background(), member.Email, id)
+	assert.Equal(t, users.ErrNotFound, err, fmt.Sprintf("view org as non-member: expected %s got %s\n", users.ErrNotFound, err))
+
+	err = svc.RemoveOrg(context.Background(), user.Email, id)
+	assert.Nil(t, err, fmt.Sprintf("remove org: unexpected error: %s\n", err))
+
+	_, err = svc.ViewOrg(context.Background(), user.Email, id)
+	assert.Equal(t, users.ErrNotFound, err, fmt.Sprintf("view removed org: expected %s got %s\n", users.ErrNotFound, err))
+}
+
+func TestAuthorizeOrg(t *testing.T) {
+	svc := newService()
+	svc.Register(context.Background(), user)
+	member := users.User{Email: "member@example.com", Password: "password"}
+	svc.Register(context.Background(), member)
+	svc.Register(context.Background(), users.User{Email: "other@example.com", Password: "password"})
+
+	id, err := svc.CreateOrg(context.Background(), user.Email, users.Org{Name: "org"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = svc.AssignOrgMember(context.Background(), user.Email, id, member.Email, users.RoleViewer)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	ownerToken, err := svc.IssueOrgToken(context.Background(), user.Email, id)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	memberToken, err := svc.IssueOrgToken(context.Background(), member.Email, id)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	_, err = svc.IssueOrgToken(context.Background(), "other@example.com", id)
+	assert.Equal(t, users.ErrNotFound, err, fmt.Sprintf("issue org token as non-member: expected %s got %s\n", users.ErrNotFound, err))
+
+	_, err = svc.IssueOrgToken(context.Background(), ownerToken, id)
+	assert.Equal(t, users.ErrUnauthorizedAccess, err, fmt.Sprintf("issue org token using org token: expected %s got %s\n", users.ErrUnauthorizedAccess, err))
+
+	cases := map[string]struct {
+		token    string
+		identity users.Identity
+		err      error
+	}{
+		"authorize personal token": {
+			token:    user.Email,
+			identity: users.Identity{ID: user.Email, Role: users.DefaultRole},
+			err:      nil,
+		},
+		"authorize org admin token": {
+			token:    ownerToken,
+			identity: users.Identity{ID: id, Role: users.RoleEditor},
+			err:      nil,
+		},
+		"authorize org viewer token": {
+			token:    memberToken,
+			identity: users.Identity{ID: id, Role: users.RoleViewer},
+			err:      nil,
+		},
+	}
+
+	for desc, tc := range cases {
+		identity, err := svc.Authorize(context.Background(), tc.token)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+		assert.Equal(t, tc.identity, identity, fmt.Sprintf("%s: expected %v got %v\n", desc, tc.identity, identity))
+	}
+
+	groups, err := svc.ListGroups(context.Background(), memberToken)
+	assert.Nil(t, err, fmt.Sprintf("list groups using org token: unexpected error: %s\n", err))
+	assert.Empty(t, groups, fmt.Sprintf("list groups using org token: expected no groups got %v\n", groups))
+
+	_, err = svc.UserInfo(context.Background(), memberToken)
+	assert.Equal(t, users.ErrUnauthorizedAccess, err, fmt.Sprintf("user info using org token: expected %s got %s\n", users.ErrUnauthorizedAccess, err))
+
+	err = svc.UnassignOrgMember(context.Background(), user.Email, id, member.Email)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	_, err = svc.Authorize(context.Background(), memberToken)
+	assert.Equal(t, users.ErrUnauthorizedAccess, err, fmt.Sprintf("authorize token of removed member: expected %s got %s\n", users.ErrUnauthorizedAccess, err))
+}
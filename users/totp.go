@@ -0,0 +1,66 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package users
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// RecoveryCodes is the number of the recovery codes generated when the
+// second factor is enabled.
+const RecoveryCodes = 10
+
+// ErrOTPRequired indicates that the login requires the one-time password,
+// since the user enabled the second factor.
+var ErrOTPRequired = errors.New("one-time password required")
+
+// TOTP represents the time-based one-time password second factor of the
+// user. The second factor is enforced only once it's enabled, i.e. once
+// the user verified the enrollment.
+type TOTP struct {
+	Email   string
+	Secret  string
+	Enabled bool
+}
+
+// TOTPRepository specifies a second factor persistence API.
+type TOTPRepository interface {
+	// Save persists the pending second factor of the user, replacing the
+	// existing one.
+	Save(context.Context, TOTP) error
+
+	// RetrieveByID retrieves the second factor of the user with the
+	// provided email.
+	RetrieveByID(context.Context, string) (TOTP, error)
+
+	// Enable enables the second factor of the user with the provided email,
+	// and replaces the recovery codes of the user with the provided hashes.
+	Enable(ctx context.Context, email string, codes []string) error
+
+	// RemoveRecoveryCode removes the recovery code with the provided hash,
+	// so that each code can be used once.
+	RemoveRecoveryCode(ctx context.Context, email, code string) error
+
+	// UpdateCounter stores the counter of the last accepted one-time
+	// password of the user, if it's greater than the stored one, so that
+	// each password can be used once. Otherwise, ErrNotFound is returned.
+	UpdateCounter(ctx context.Context, email string, counter int64) error
+}
+
+// OTPProvider specifies an API for the time-based one-time passwords.
+type OTPProvider interface {
+	// Secret generates new random secret.
+	Secret() (string, error)
+
+	// URI returns the provisioning URI of the secret, used for adding the
+	// account to the authenticator apps.
+	URI(secret, account string) string
+
+	// Validate reports whether the one-time password is valid for the
+	// secret at the provided time, returning the counter of the period
+	// the password is generated for.
+	Validate(secret, otp string, t time.Time) (int64, bool)
+}
@@ -0,0 +1,94 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package totp provides the time-based one-time password implementation
+// as specified by RFC 6238, compatible with the common authenticator apps.
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mainflux/mainflux/users"
+)
+
+const (
+	issuer     = "Mainflux"
+	secretSize = 20
+	period     = 30
+	digits     = 6
+	modulo     = 1000000
+	// skew is the number of periods before and after the current one the
+	// passwords are accepted for, to allow for the clock drift.
+	skew = 1
+)
+
+var (
+	_ users.OTPProvider = (*totpProvider)(nil)
+
+	encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+)
+
+type totpProvider struct{}
+
+// New instantiates the TOTP provider, using HMAC-SHA1 and six digit
+// passwords changed every 30 seconds.
+func New() users.OTPProvider {
+	return &totpProvider{}
+}
+
+func (tp *totpProvider) Secret() (string, error) {
+	secret := make([]byte, secretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return encoding.EncodeToString(secret), nil
+}
+
+func (tp *totpProvider) URI(secret, account string) string {
+	params := url.Values{}
+	params.Set("secret", secret)
+	params.Set("issuer", issuer)
+
+	label := url.PathEscape(fmt.Sprintf("%s:%s", issuer, account))
+	return fmt.Sprintf("otpauth://totp/%s?%s", label, params.Encode())
+}
+
+func (tp *totpProvider) Validate(secret, otp string, t time.Time) (int64, bool) {
+	key, err := encoding.DecodeString(strings.ToUpper(strings.TrimRight(secret, "=")))
+	if err != nil || len(otp) != digits {
+		return 0, false
+	}
+
+	counter := t.Unix() / period
+	for i := int64(-skew); i <= skew; i++ {
+		if subtle.ConstantTimeCompare([]byte(generate(key, counter+i)), []byte(otp)) == 1 {
+			return counter + i, true
+		}
+	}
+
+	return 0, false
+}
+
+// generate generates the password for the counter as specified by RFC 4226.
+func generate(key []byte, counter int64) string {
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, uint64(counter))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0xf
+	code := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", digits, code%modulo)
+}
@@ -0,0 +1,116 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package totp_test
+
+import (
+	"encoding/base32"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/users/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test vectors are the last six digits of the SHA-1 vectors of RFC 6238.
+var secret = base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+func TestValidate(t *testing.T) {
+	provider := totp.New()
+
+	cases := []struct {
+		desc  string
+		otp   string
+		time  int64
+		valid bool
+	}{
+		{
+			desc:  "validate password at 59",
+			otp:   "287082",
+			time:  59,
+			valid: true,
+		},
+		{
+			desc:  "validate password at 1111111109",
+			otp:   "081804",
+			time:  1111111109,
+			valid: true,
+		},
+		{
+			desc:  "validate password at 1111111111",
+			otp:   "050471",
+			time:  1111111111,
+			valid: true,
+		},
+		{
+			desc:  "validate password at 1234567890",
+			otp:   "005924",
+			time:  1234567890,
+			valid: true,
+		},
+		{
+			desc:  "validate password at 2000000000",
+			otp:   "279037",
+			time:  2000000000,
+			valid: true,
+		},
+		{
+			desc:  "validate password of the previous period",
+			otp:   "081804",
+			time:  1111111109 + 30,
+			valid: true,
+		},
+		{
+			desc:  "validate expired password",
+			otp:   "081804",
+			time:  1111111109 + 60,
+			valid: false,
+		},
+		{
+			desc:  "validate wrong password",
+			otp:   "123456",
+			time:  59,
+			valid: false,
+		},
+		{
+			desc:  "validate password with wrong number of digits",
+			otp:   "94287082",
+			time:  59,
+			valid: false,
+		},
+	}
+
+	for _, tc := range cases {
+		_, valid := provider.Validate(secret, tc.otp, time.Unix(tc.time, 0))
+		assert.Equal(t, tc.valid, valid, fmt.Sprintf("%s: expected %t got %t", tc.desc, tc.valid, valid))
+	}
+}
+
+func TestValidateCounter(t *testing.T) {
+	provider := totp.New()
+
+	counter, valid := provider.Validate(secret, "081804", time.Unix(1111111109, 0))
+	assert.True(t, valid, "expected valid password")
+	assert.Equal(t, int64(1111111109/30), counter, fmt.Sprintf("expected counter %d got %d", 1111111109/30, counter))
+
+	// Password of the previous period keeps its counter.
+	counter, valid = provider.Validate(secret, "081804", time.Unix(1111111109+30, 0))
+	assert.True(t, valid, "expected valid password")
+	assert.Equal(t, int64(1111111109/30), counter, fmt.Sprintf("expected counter %d got %d", 1111111109/30, counter))
+}
+
+func TestSecret(t *testing.T) {
+	provider := totp.New()
+
+	secret, err := provider.Secret()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	other, err := provider.Secret()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.NotEqual(t, secret, other, "expected random secrets")
+
+	uri := provider.URI(secret, "user@example.com")
+	expected := fmt.Sprintf("otpauth://totp/Mainflux:user@example.com?issuer=Mainflux&secret=%s", secret)
+	assert.Equal(t, expected, uri, fmt.Sprintf("expected URI %s got %s", expected, uri))
+}
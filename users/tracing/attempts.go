@@ -0,0 +1,67 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package tracing
+
+import (
+	"context"
+	"time"
+
+	"github.com/mainflux/mainflux/users"
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+const (
+	failLoginOp     = "fail_login"
+	lockAccountOp   = "lock_account"
+	checkLockOp     = "check_lock"
+	resetAttemptsOp = "reset_login_attempts"
+)
+
+var _ users.LoginAttempts = (*loginAttemptsMiddleware)(nil)
+
+type loginAttemptsMiddleware struct {
+	tracer   opentracing.Tracer
+	attempts users.LoginAttempts
+}
+
+// LoginAttemptsMiddleware tracks request and their latency, and adds spans
+// to context.
+func LoginAttemptsMiddleware(attempts users.LoginAttempts, tracer opentracing.Tracer) users.LoginAttempts {
+	return loginAttemptsMiddleware{
+		tracer:   tracer,
+		attempts: attempts,
+	}
+}
+
+func (lam loginAttemptsMiddleware) Fail(ctx context.Context, email string, ttl time.Duration) (uint64, error) {
+	span := createSpan(ctx, lam.tracer, failLoginOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return lam.attempts.Fail(ctx, email, ttl)
+}
+
+func (lam loginAttemptsMiddleware) Lock(ctx context.Context, email string, d time.Duration) error {
+	span := createSpan(ctx, lam.tracer, lockAccountOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return lam.attempts.Lock(ctx, email, d)
+}
+
+func (lam loginAttemptsMiddleware) Locked(ctx context.Context, email string) (time.Duration, error) {
+	span := createSpan(ctx, lam.tracer, checkLockOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return lam.attempts.Locked(ctx, email)
+}
+
+func (lam loginAttemptsMiddleware) Reset(ctx context.Context, email string) error {
+	span := createSpan(ctx, lam.tracer, resetAttemptsOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return lam.attempts.Reset(ctx, email)
+}
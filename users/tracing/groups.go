@@ -0,0 +1,84 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package tracing
+
+import (
+	"context"
+
+	"github.com/mainflux/mainflux/users"
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+const (
+	saveGroupOp         = "save_group"
+	retrieveGroupByIDOp = "retrieve_group_by_id"
+	retrieveAllGroupsOp = "retrieve_all_groups"
+	removeGroupOp       = "remove_group"
+	assignMemberOp      = "assign_member"
+	unassignMemberOp    = "unassign_member"
+)
+
+var _ users.GroupRepository = (*groupRepositoryMiddleware)(nil)
+
+type groupRepositoryMiddleware struct {
+	tracer opentracing.Tracer
+	repo   users.GroupRepository
+}
+
+// GroupRepositoryMiddleware tracks request and their latency, and adds spans
+// to context.
+func GroupRepositoryMiddleware(repo users.GroupRepository, tracer opentracing.Tracer) users.GroupRepository {
+	return groupRepositoryMiddleware{
+		tracer: tracer,
+		repo:   repo,
+	}
+}
+
+func (grm groupRepositoryMiddleware) Save(ctx context.Context, group users.Group) error {
+	span := createSpan(ctx, grm.tracer, saveGroupOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return grm.repo.Save(ctx, group)
+}
+
+func (grm groupRepositoryMiddleware) RetrieveByID(ctx context.Context, id string) (users.Group, error) {
+	span := createSpan(ctx, grm.tracer, retrieveGroupByIDOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return grm.repo.RetrieveByID(ctx, id)
+}
+
+func (grm groupRepositoryMiddleware) RetrieveAll(ctx context.Context, member string) ([]users.Group, error) {
+	span := createSpan(ctx, grm.tracer, retrieveAllGroupsOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return grm.repo.RetrieveAll(ctx, member)
+}
+
+func (grm groupRepositoryMiddleware) Remove(ctx context.Context, owner, id string) error {
+	span := createSpan(ctx, grm.tracer, removeGroupOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return grm.repo.Remove(ctx, owner, id)
+}
+
+func (grm groupRepositoryMiddleware) AssignMember(ctx context.Context, id, member string) error {
+	span := createSpan(ctx, grm.tracer, assignMemberOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return grm.repo.AssignMember(ctx, id, member)
+}
+
+func (grm groupRepositoryMiddleware) UnassignMember(ctx context.Context, id, member string) error {
+	span := createSpan(ctx, grm.tracer, unassignMemberOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return grm.repo.UnassignMember(ctx, id, member)
+}
@@ -0,0 +1,66 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package tracing
+
+import (
+	"context"
+
+	"github.com/mainflux/mainflux/users"
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+const (
+	saveKeyOp         = "save_key"
+	retrieveKeyByIDOp = "retrieve_key_by_id"
+	retrieveAllKeysOp = "retrieve_all_keys"
+	removeKeyOp       = "remove_key"
+)
+
+var _ users.KeyRepository = (*keyRepositoryMiddleware)(nil)
+
+type keyRepositoryMiddleware struct {
+	tracer opentracing.Tracer
+	repo   users.KeyRepository
+}
+
+// KeyRepositoryMiddleware tracks request and their latency, and adds spans
+// to context.
+func KeyRepositoryMiddleware(repo users.KeyRepository, tracer opentracing.Tracer) users.KeyRepository {
+	return keyRepositoryMiddleware{
+		tracer: tracer,
+		repo:   repo,
+	}
+}
+
+func (krm keyRepositoryMiddleware) Save(ctx context.Context, key users.Key) error {
+	span := createSpan(ctx, krm.tracer, saveKeyOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return krm.repo.Save(ctx, key)
+}
+
+func (krm keyRepositoryMiddleware) RetrieveByID(ctx context.Context, id string) (users.Key, error) {
+	span := createSpan(ctx, krm.tracer, retrieveKeyByIDOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return krm.repo.RetrieveByID(ctx, id)
+}
+
+func (krm keyRepositoryMiddleware) RetrieveAll(ctx context.Context, owner string) ([]users.Key, error) {
+	span := createSpan(ctx, krm.tracer, retrieveAllKeysOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return krm.repo.RetrieveAll(ctx, owner)
+}
+
+func (krm keyRepositoryMiddleware) Remove(ctx context.Context, owner, id string) error {
+	span := createSpan(ctx, krm.tracer, removeKeyOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return krm.repo.Remove(ctx, owner, id)
+}
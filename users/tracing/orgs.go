@@ -0,0 +1,93 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package tracing
+
+import (
+	"context"
+
+	"github.com/mainflux/mainflux/users"
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+const (
+	saveOrgOp         = "save_org"
+	retrieveOrgByIDOp = "retrieve_org_by_id"
+	retrieveAllOrgsOp = "retrieve_all_orgs"
+	removeOrgOp       = "remove_org"
+	saveOrgMemberOp   = "save_org_member"
+	removeOrgMemberOp = "remove_org_member"
+	retrieveOrgRoleOp = "retrieve_org_role"
+)
+
+var _ users.OrgRepository = (*orgRepositoryMiddleware)(nil)
+
+type orgRepositoryMiddleware struct {
+	tracer opentracing.Tracer
+	repo   users.OrgRepository
+}
+
+// OrgRepositoryMiddleware tracks request and their latency, and adds spans
+// to context.
+func OrgRepositoryMiddleware(repo users.OrgRepository, tracer opentracing.Tracer) users.OrgRepository {
+	return orgRepositoryMiddleware{
+		tracer: tracer,
+		repo:   repo,
+	}
+}
+
+func (orm orgRepositoryMiddleware) Save(ctx context.Context, org users.Org) error {
+	span := createSpan(ctx, orm.tracer, saveOrgOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return orm.repo.Save(ctx, org)
+}
+
+func (orm orgRepositoryMiddleware) RetrieveByID(ctx context.Context, id string) (users.Org, error) {
+	span := createSpan(ctx, orm.tracer, retrieveOrgByIDOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return orm.repo.RetrieveByID(ctx, id)
+}
+
+func (orm orgRepositoryMiddleware) RetrieveAll(ctx context.Context, email string) ([]users.Org, error) {
+	span := createSpan(ctx, orm.tracer, retrieveAllOrgsOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return orm.repo.RetrieveAll(ctx, email)
+}
+
+func (orm orgRepositoryMiddleware) Remove(ctx context.Context, owner, id string) error {
+	span := createSpan(ctx, orm.tracer, removeOrgOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return orm.repo.Remove(ctx, owner, id)
+}
+
+func (orm orgRepositoryMiddleware) SaveMember(ctx context.Context, id, email, role string) error {
+	span := createSpan(ctx, orm.tracer, saveOrgMemberOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return orm.repo.SaveMember(ctx, id, email, role)
+}
+
+func (orm orgRepositoryMiddleware) RemoveMember(ctx context.Context, id, email string) error {
+	span := createSpan(ctx, orm.tracer, removeOrgMemberOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return orm.repo.RemoveMember(ctx, id, email)
+}
+
+func (orm orgRepositoryMiddleware) RetrieveRole(ctx context.Context, id, email string) (string, error) {
+	span := createSpan(ctx, orm.tracer, retrieveOrgRoleOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return orm.repo.RetrieveRole(ctx, id, email)
+}
@@ -0,0 +1,48 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package tracing
+
+import (
+	"context"
+
+	"github.com/mainflux/mainflux/users"
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+const (
+	saveRoleOp         = "save_role"
+	retrieveRoleByIDOp = "retrieve_role_by_id"
+)
+
+var _ users.RoleRepository = (*roleRepositoryMiddleware)(nil)
+
+type roleRepositoryMiddleware struct {
+	tracer opentracing.Tracer
+	repo   users.RoleRepository
+}
+
+// RoleRepositoryMiddleware tracks request and their latency, and adds spans
+// to context.
+func RoleRepositoryMiddleware(repo users.RoleRepository, tracer opentracing.Tracer) users.RoleRepository {
+	return roleRepositoryMiddleware{
+		tracer: tracer,
+		repo:   repo,
+	}
+}
+
+func (rrm roleRepositoryMiddleware) Save(ctx context.Context, email, role string) error {
+	span := createSpan(ctx, rrm.tracer, saveRoleOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return rrm.repo.Save(ctx, email, role)
+}
+
+func (rrm roleRepositoryMiddleware) RetrieveByID(ctx context.Context, email string) (string, error) {
+	span := createSpan(ctx, rrm.tracer, retrieveRoleByIDOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return rrm.repo.RetrieveByID(ctx, email)
+}
@@ -0,0 +1,75 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package tracing
+
+import (
+	"context"
+
+	"github.com/mainflux/mainflux/users"
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+const (
+	saveTOTPOp           = "save_totp"
+	retrieveTOTPByIDOp   = "retrieve_totp_by_id"
+	enableTOTPOp         = "enable_totp"
+	removeRecoveryCodeOp = "remove_recovery_code"
+	updateTOTPCounterOp  = "update_totp_counter"
+)
+
+var _ users.TOTPRepository = (*totpRepositoryMiddleware)(nil)
+
+type totpRepositoryMiddleware struct {
+	tracer opentracing.Tracer
+	repo   users.TOTPRepository
+}
+
+// TOTPRepositoryMiddleware tracks request and their latency, and adds spans
+// to context.
+func TOTPRepositoryMiddleware(repo users.TOTPRepository, tracer opentracing.Tracer) users.TOTPRepository {
+	return totpRepositoryMiddleware{
+		tracer: tracer,
+		repo:   repo,
+	}
+}
+
+func (trm totpRepositoryMiddleware) Save(ctx context.Context, totp users.TOTP) error {
+	span := createSpan(ctx, trm.tracer, saveTOTPOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return trm.repo.Save(ctx, totp)
+}
+
+func (trm totpRepositoryMiddleware) RetrieveByID(ctx context.Context, email string) (users.TOTP, error) {
+	span := createSpan(ctx, trm.tracer, retrieveTOTPByIDOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return trm.repo.RetrieveByID(ctx, email)
+}
+
+func (trm totpRepositoryMiddleware) Enable(ctx context.Context, email string, codes []string) error {
+	span := createSpan(ctx, trm.tracer, enableTOTPOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return trm.repo.Enable(ctx, email, codes)
+}
+
+func (trm totpRepositoryMiddleware) RemoveRecoveryCode(ctx context.Context, email, code string) error {
+	span := createSpan(ctx, trm.tracer, removeRecoveryCodeOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return trm.repo.RemoveRecoveryCode(ctx, email, code)
+}
+
+func (trm totpRepositoryMiddleware) UpdateCounter(ctx context.Context, email string, counter int64) error {
+	span := createSpan(ctx, trm.tracer, updateTOTPCounterOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return trm.repo.UpdateCounter(ctx, email, counter)
+}
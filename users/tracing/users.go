@@ -15,6 +15,10 @@ import (
 const (
 	saveOp         = "save_op"
 	retrieveByIDOp = "retrieve_by_id"
+	retrieveAllOp  = "retrieve_all"
+	updatePassOp   = "update_password"
+	updateDisOp    = "update_disabled"
+	removeOp       = "remove"
 )
 
 var _ users.UserRepository = (*userRepositoryMiddleware)(nil)
@@ -49,6 +53,38 @@ func (urm userRepositoryMiddleware) RetrieveByID(ctx context.Context, id string)
 	return urm.repo.RetrieveByID(ctx, id)
 }
 
+func (urm userRepositoryMiddleware) RetrieveAll(ctx context.Context, offset, limit uint64) (users.UserPage, error) {
+	span := createSpan(ctx, urm.tracer, retrieveAllOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return urm.repo.RetrieveAll(ctx, offset, limit)
+}
+
+func (urm userRepositoryMiddleware) UpdateDisabled(ctx context.Context, email string, disabled bool) error {
+	span := createSpan(ctx, urm.tracer, updateDisOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return urm.repo.UpdateDisabled(ctx, email, disabled)
+}
+
+func (urm userRepositoryMiddleware) UpdatePassword(ctx context.Context, email, password string) error {
+	span := createSpan(ctx, urm.tracer, updatePassOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return urm.repo.UpdatePassword(ctx, email, password)
+}
+
+func (urm userRepositoryMiddleware) Remove(ctx context.Context, email string) error {
+	span := createSpan(ctx, urm.tracer, removeOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return urm.repo.Remove(ctx, email)
+}
+
 func createSpan(ctx context.Context, tracer opentracing.Tracer, opName string) opentracing.Span {
 	if parentSpan := opentracing.SpanFromContext(ctx); parentSpan != nil {
 		return tracer.StartSpan(
@@ -16,11 +16,22 @@ var (
 )
 
 // User represents a Mainflux user account. Each user is identified given its
-// email and password.
+// email and password. Disabled users can't log in, and their tokens are
+// rejected.
 type User struct {
 	Email    string
 	Password string
 	Metadata map[string]interface{}
+	Disabled bool
+}
+
+// UserPage contains page related metadata as well as the list of users that
+// belong to this page.
+type UserPage struct {
+	Total  uint64
+	Offset uint64
+	Limit  uint64
+	Users  []User
 }
 
 // Validate returns an error if user representation is invalid.
@@ -36,6 +47,14 @@ func (u User) Validate() error {
 	return nil
 }
 
+// Identity represents the owner of the entities the token grants access to,
+// i.e. the user or the organization, together with the role of the token
+// holder.
+type Identity struct {
+	ID   string
+	Role string
+}
+
 // UserRepository specifies an account persistence API.
 type UserRepository interface {
 	// Save persists the user account. A non-nil error is returned to indicate
@@ -44,6 +63,21 @@ type UserRepository interface {
 
 	// RetrieveByID retrieves user by its unique identifier (i.e. email).
 	RetrieveByID(context.Context, string) (User, error)
+
+	// RetrieveAll retrieves the subset of all the users, ordered by email.
+	// Passwords of the retrieved users are omitted.
+	RetrieveAll(ctx context.Context, offset, limit uint64) (UserPage, error)
+
+	// UpdatePassword updates the hashed password of the user identified by
+	// the provided email.
+	UpdatePassword(ctx context.Context, email, password string) error
+
+	// UpdateDisabled disables or enables the user identified by the
+	// provided email.
+	UpdateDisabled(ctx context.Context, email string, disabled bool) error
+
+	// Remove removes the user identified by the provided email.
+	Remove(context.Context, string) error
 }
 
 func isEmail(email string) bool {
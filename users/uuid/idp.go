@@ -0,0 +1,28 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package uuid provides a UUID identity provider.
+package uuid
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/mainflux/mainflux/users"
+)
+
+var _ users.IDProvider = (*uuidProvider)(nil)
+
+type uuidProvider struct{}
+
+// New instantiates a UUID provider.
+func New() users.IDProvider {
+	return &uuidProvider{}
+}
+
+func (up *uuidProvider) ID() (string, error) {
+	id, err := uuid.NewV4()
+	if err != nil {
+		return "", err
+	}
+
+	return id.String(), nil
+}
@@ -0,0 +1,139 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/mainflux/mainflux/webhooks"
+)
+
+func createWebhookEndpoint(svc webhooks.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(createWebhookReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		wh := webhooks.Webhook{
+			Channel:  req.Channel,
+			Subtopic: req.Subtopic,
+			URL:      req.URL,
+			Secret:   req.Secret,
+		}
+
+		saved, err := svc.CreateWebhook(ctx, req.token, wh)
+		if err != nil {
+			return nil, err
+		}
+
+		return webhookRes{id: saved.ID}, nil
+	}
+}
+
+func viewWebhookEndpoint(svc webhooks.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewWebhookReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		wh, err := svc.ViewWebhook(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		return toWebhookRes(wh), nil
+	}
+}
+
+func listWebhooksEndpoint(svc webhooks.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listWebhooksReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		page, err := svc.ListWebhooks(ctx, req.token, req.offset, req.limit)
+		if err != nil {
+			return nil, err
+		}
+
+		res := webhooksPageRes{
+			Total:    page.Total,
+			Offset:   page.Offset,
+			Limit:    page.Limit,
+			Webhooks: []viewWebhookRes{},
+		}
+		for _, wh := range page.Webhooks {
+			res.Webhooks = append(res.Webhooks, toWebhookRes(wh))
+		}
+
+		return res, nil
+	}
+}
+
+func removeWebhookEndpoint(svc webhooks.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewWebhookReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RemoveWebhook(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
+func listDeliveriesEndpoint(svc webhooks.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listDeliveriesReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		page, err := svc.ListDeliveries(ctx, req.token, req.id, req.offset, req.limit)
+		if err != nil {
+			return nil, err
+		}
+
+		res := deliveriesPageRes{
+			Total:      page.Total,
+			Offset:     page.Offset,
+			Limit:      page.Limit,
+			Deliveries: []deliveryRes{},
+		}
+		for _, d := range page.Deliveries {
+			res.Deliveries = append(res.Deliveries, deliveryRes{
+				ID:       d.ID,
+				Status:   d.Status,
+				Code:     d.Code,
+				Attempts: d.Attempts,
+				Error:    d.Error,
+				Created:  d.Created,
+			})
+		}
+
+		return res, nil
+	}
+}
+
+func toWebhookRes(wh webhooks.Webhook) viewWebhookRes {
+	return viewWebhookRes{
+		ID:       wh.ID,
+		Channel:  wh.Channel,
+		Subtopic: wh.Subtopic,
+		URL:      wh.URL,
+		Secret:   wh.Secret,
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// +build !test
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/webhooks"
+)
+
+var _ webhooks.Service = (*metricsMiddleware)(nil)
+
+type metricsMiddleware struct {
+	counter metrics.Counter
+	latency metrics.Histogram
+	svc     webhooks.Service
+}
+
+// MetricsMiddleware instruments core service by tracking request count and
+// latency.
+func MetricsMiddleware(svc webhooks.Service, counter metrics.Counter, latency metrics.Histogram) webhooks.Service {
+	return &metricsMiddleware{
+		counter: counter,
+		latency: latency,
+		svc:     svc,
+	}
+}
+
+func (ms *metricsMiddleware) CreateWebhook(ctx context.Context, token string, wh webhooks.Webhook) (webhooks.Webhook, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "create_webhook").Add(1)
+		ms.latency.With("method", "create_webhook").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.CreateWebhook(ctx, token, wh)
+}
+
+func (ms *metricsMiddleware) ViewWebhook(ctx context.Context, token, id string) (webhooks.Webhook, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_webhook").Add(1)
+		ms.latency.With("method", "view_webhook").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewWebhook(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) ListWebhooks(ctx context.Context, token string, offset, limit uint64) (webhooks.WebhooksPage, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_webhooks").Add(1)
+		ms.latency.With("method", "list_webhooks").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListWebhooks(ctx, token, offset, limit)
+}
+
+func (ms *metricsMiddleware) RemoveWebhook(ctx context.Context, token, id string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "remove_webhook").Add(1)
+		ms.latency.With("method", "remove_webhook").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RemoveWebhook(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) ListDeliveries(ctx context.Context, token, id string, offset, limit uint64) (webhooks.DeliveriesPage, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_deliveries").Add(1)
+		ms.latency.With("method", "list_deliveries").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListDeliveries(ctx, token, id, offset, limit)
+}
+
+func (ms *metricsMiddleware) Forward(ctx context.Context, msg mainflux.Message) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "forward").Add(1)
+		ms.latency.With("method", "forward").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Forward(ctx, msg)
+}
+
+func (ms *metricsMiddleware) Reload(ctx context.Context) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "reload").Add(1)
+		ms.latency.With("method", "reload").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Reload(ctx)
+}
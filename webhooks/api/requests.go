@@ -0,0 +1,86 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import "github.com/mainflux/mainflux/webhooks"
+
+const maxLimitSize = 100
+
+type apiReq interface {
+	validate() error
+}
+
+type createWebhookReq struct {
+	token    string
+	Channel  string `json:"channel"`
+	Subtopic string `json:"subtopic,omitempty"`
+	URL      string `json:"url"`
+	Secret   string `json:"secret,omitempty"`
+}
+
+func (req createWebhookReq) validate() error {
+	if req.token == "" {
+		return webhooks.ErrUnauthorizedAccess
+	}
+
+	if req.Channel == "" || req.URL == "" {
+		return webhooks.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type viewWebhookReq struct {
+	token string
+	id    string
+}
+
+func (req viewWebhookReq) validate() error {
+	if req.token == "" {
+		return webhooks.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" {
+		return webhooks.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type listWebhooksReq struct {
+	token  string
+	offset uint64
+	limit  uint64
+}
+
+func (req listWebhooksReq) validate() error {
+	if req.token == "" {
+		return webhooks.ErrUnauthorizedAccess
+	}
+
+	if req.limit == 0 || req.limit > maxLimitSize {
+		return webhooks.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type listDeliveriesReq struct {
+	token  string
+	id     string
+	offset uint64
+	limit  uint64
+}
+
+func (req listDeliveriesReq) validate() error {
+	if req.token == "" {
+		return webhooks.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" || req.limit == 0 || req.limit > maxLimitSize {
+		return webhooks.ErrMalformedEntity
+	}
+
+	return nil
+}
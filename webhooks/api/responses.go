@@ -0,0 +1,119 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/mainflux/mainflux"
+)
+
+var (
+	_ mainflux.Response = (*webhookRes)(nil)
+	_ mainflux.Response = (*viewWebhookRes)(nil)
+	_ mainflux.Response = (*webhooksPageRes)(nil)
+	_ mainflux.Response = (*deliveriesPageRes)(nil)
+	_ mainflux.Response = (*removeRes)(nil)
+)
+
+type webhookRes struct {
+	id string
+}
+
+func (res webhookRes) Code() int {
+	return http.StatusCreated
+}
+
+func (res webhookRes) Headers() map[string]string {
+	return map[string]string{
+		"Location": fmt.Sprintf("/webhooks/%s", res.id),
+	}
+}
+
+func (res webhookRes) Empty() bool {
+	return true
+}
+
+type viewWebhookRes struct {
+	ID       string `json:"id"`
+	Channel  string `json:"channel"`
+	Subtopic string `json:"subtopic,omitempty"`
+	URL      string `json:"url"`
+	Secret   string `json:"secret"`
+}
+
+func (res viewWebhookRes) Code() int {
+	return http.StatusOK
+}
+
+func (res viewWebhookRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res viewWebhookRes) Empty() bool {
+	return false
+}
+
+type webhooksPageRes struct {
+	Total    uint64           `json:"total"`
+	Offset   uint64           `json:"offset"`
+	Limit    uint64           `json:"limit"`
+	Webhooks []viewWebhookRes `json:"webhooks"`
+}
+
+func (res webhooksPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res webhooksPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res webhooksPageRes) Empty() bool {
+	return false
+}
+
+type deliveryRes struct {
+	ID       string    `json:"id"`
+	Status   string    `json:"status"`
+	Code     int       `json:"code"`
+	Attempts uint64    `json:"attempts"`
+	Error    string    `json:"error,omitempty"`
+	Created  time.Time `json:"created"`
+}
+
+type deliveriesPageRes struct {
+	Total      uint64        `json:"total"`
+	Offset     uint64        `json:"offset"`
+	Limit      uint64        `json:"limit"`
+	Deliveries []deliveryRes `json:"deliveries"`
+}
+
+func (res deliveriesPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res deliveriesPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res deliveriesPageRes) Empty() bool {
+	return false
+}
+
+type removeRes struct{}
+
+func (res removeRes) Code() int {
+	return http.StatusNoContent
+}
+
+func (res removeRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res removeRes) Empty() bool {
+	return true
+}
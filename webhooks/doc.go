@@ -0,0 +1,9 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package webhooks contains the domain concept definitions needed to support
+// Mainflux webhooks service functionality. Webhooks service forwards the
+// messages published to the channels to the user-configured HTTP endpoints,
+// signs the payloads, retries the failed deliveries with exponential backoff
+// and logs the outcome of each delivery.
+package webhooks
@@ -0,0 +1,63 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mainflux/mainflux/webhooks"
+)
+
+var _ webhooks.DeliveryRepository = (*deliveryRepositoryMock)(nil)
+
+type deliveryRepositoryMock struct {
+	mu         sync.Mutex
+	deliveries []webhooks.Delivery
+}
+
+// NewDeliveryRepository creates in-memory delivery repository.
+func NewDeliveryRepository() webhooks.DeliveryRepository {
+	return &deliveryRepositoryMock{}
+}
+
+func (drm *deliveryRepositoryMock) Save(_ context.Context, d webhooks.Delivery) error {
+	drm.mu.Lock()
+	defer drm.mu.Unlock()
+
+	drm.deliveries = append(drm.deliveries, d)
+	return nil
+}
+
+func (drm *deliveryRepositoryMock) RetrieveByWebhook(_ context.Context, id string, offset, limit uint64) (webhooks.DeliveriesPage, error) {
+	drm.mu.Lock()
+	defer drm.mu.Unlock()
+
+	// Deliveries are appended, so the most recent ones are at the end.
+	items := []webhooks.Delivery{}
+	for i := len(drm.deliveries) - 1; i >= 0; i-- {
+		if drm.deliveries[i].Webhook == id {
+			items = append(items, drm.deliveries[i])
+		}
+	}
+
+	page := webhooks.DeliveriesPage{
+		Total:      uint64(len(items)),
+		Offset:     offset,
+		Limit:      limit,
+		Deliveries: []webhooks.Delivery{},
+	}
+
+	if offset >= uint64(len(items)) {
+		return page, nil
+	}
+
+	end := offset + limit
+	if end > uint64(len(items)) {
+		end = uint64(len(items))
+	}
+	page.Deliveries = items[offset:end]
+
+	return page, nil
+}
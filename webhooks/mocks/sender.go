@@ -0,0 +1,76 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+
+	"github.com/mainflux/mainflux/webhooks"
+)
+
+var _ webhooks.Sender = (*Sender)(nil)
+
+// Request represents a payload sent to the webhook.
+type Request struct {
+	URL     string
+	Headers map[string]string
+	Payload []byte
+}
+
+// Sender is an in-memory sender which records the requests and replies with
+// the predefined status codes.
+type Sender struct {
+	mu        sync.Mutex
+	requests  []Request
+	responses map[string][]int
+}
+
+// NewSender returns sender mock.
+func NewSender() *Sender {
+	return &Sender{
+		responses: make(map[string][]int),
+	}
+}
+
+// Respond sets the status codes returned for the successive requests sent to
+// the URL. The last code is repeated once the others are used up, while zero
+// code stands for the failed request. Requests are accepted by default.
+func (s *Sender) Respond(url string, codes ...int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.responses[url] = codes
+}
+
+// Send records the request and returns the next status code of the URL.
+func (s *Sender) Send(url string, headers map[string]string, payload []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, Request{URL: url, Headers: headers, Payload: payload})
+
+	code := http.StatusOK
+	if codes := s.responses[url]; len(codes) > 0 {
+		code = codes[0]
+		if len(codes) > 1 {
+			s.responses[url] = codes[1:]
+		}
+	}
+
+	if code == 0 {
+		return 0, errors.New("connection refused")
+	}
+
+	return code, nil
+}
+
+// Requests returns all the requests sent so far.
+func (s *Sender) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Request{}, s.requests...)
+}
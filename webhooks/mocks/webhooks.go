@@ -0,0 +1,103 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/mainflux/mainflux/webhooks"
+)
+
+var _ webhooks.WebhookRepository = (*webhookRepositoryMock)(nil)
+
+type webhookRepositoryMock struct {
+	mu       sync.Mutex
+	webhooks map[string]webhooks.Webhook
+}
+
+// NewWebhookRepository creates in-memory webhook repository.
+func NewWebhookRepository() webhooks.WebhookRepository {
+	return &webhookRepositoryMock{
+		webhooks: make(map[string]webhooks.Webhook),
+	}
+}
+
+func (wrm *webhookRepositoryMock) Save(_ context.Context, wh webhooks.Webhook) error {
+	wrm.mu.Lock()
+	defer wrm.mu.Unlock()
+
+	wrm.webhooks[wh.ID] = wh
+	return nil
+}
+
+func (wrm *webhookRepositoryMock) RetrieveByID(_ context.Context, id string) (webhooks.Webhook, error) {
+	wrm.mu.Lock()
+	defer wrm.mu.Unlock()
+
+	wh, ok := wrm.webhooks[id]
+	if !ok {
+		return webhooks.Webhook{}, webhooks.ErrNotFound
+	}
+
+	return wh, nil
+}
+
+func (wrm *webhookRepositoryMock) RetrieveAll(_ context.Context) ([]webhooks.Webhook, error) {
+	wrm.mu.Lock()
+	defer wrm.mu.Unlock()
+
+	return wrm.filter(func(webhooks.Webhook) bool { return true }), nil
+}
+
+func (wrm *webhookRepositoryMock) RetrieveByOwner(_ context.Context, owner string, offset, limit uint64) (webhooks.WebhooksPage, error) {
+	wrm.mu.Lock()
+	defer wrm.mu.Unlock()
+
+	items := wrm.filter(func(w webhooks.Webhook) bool {
+		return w.Owner == owner
+	})
+	page := webhooks.WebhooksPage{
+		Total:    uint64(len(items)),
+		Offset:   offset,
+		Limit:    limit,
+		Webhooks: []webhooks.Webhook{},
+	}
+
+	if offset >= uint64(len(items)) {
+		return page, nil
+	}
+
+	end := offset + limit
+	if end > uint64(len(items)) {
+		end = uint64(len(items))
+	}
+	page.Webhooks = items[offset:end]
+
+	return page, nil
+}
+
+func (wrm *webhookRepositoryMock) Remove(_ context.Context, id string) error {
+	wrm.mu.Lock()
+	defer wrm.mu.Unlock()
+
+	delete(wrm.webhooks, id)
+	return nil
+}
+
+func (wrm *webhookRepositoryMock) filter(match func(webhooks.Webhook) bool) []webhooks.Webhook {
+	items := []webhooks.Webhook{}
+	for _, w := range wrm.webhooks {
+		if match(w) {
+			items = append(items, w)
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].ID < items[j].ID
+	})
+
+	return items
+}
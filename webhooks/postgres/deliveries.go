@@ -0,0 +1,123 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq" // required for DB access
+	"github.com/mainflux/mainflux/webhooks"
+)
+
+var _ webhooks.DeliveryRepository = (*deliveryRepository)(nil)
+
+type deliveryRepository struct {
+	db *sqlx.DB
+}
+
+// NewDeliveryRepository instantiates a PostgreSQL implementation of delivery
+// repository.
+func NewDeliveryRepository(db *sqlx.DB) webhooks.DeliveryRepository {
+	return &deliveryRepository{
+		db: db,
+	}
+}
+
+func (dr deliveryRepository) Save(ctx context.Context, d webhooks.Delivery) error {
+	q := `INSERT INTO deliveries (id, webhook, status, code, attempts, error, created_at)
+	      VALUES (:id, :webhook, :status, :code, :attempts, :error, :created_at);`
+
+	if _, err := dr.db.NamedExecContext(ctx, q, toDBDelivery(d)); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code.Name() {
+			case errDuplicate:
+				return webhooks.ErrMalformedEntity
+			case errForeignKey, errInvalid:
+				return webhooks.ErrNotFound
+			}
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (dr deliveryRepository) RetrieveByWebhook(ctx context.Context, id string, offset, limit uint64) (webhooks.DeliveriesPage, error) {
+	q := `SELECT id, webhook, status, code, attempts, error, created_at FROM deliveries
+	      WHERE webhook = $1 ORDER BY created_at DESC, id LIMIT $2 OFFSET $3;`
+
+	rows, err := dr.db.QueryxContext(ctx, q, id, limit, offset)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == errInvalid {
+			return webhooks.DeliveriesPage{}, webhooks.ErrNotFound
+		}
+		return webhooks.DeliveriesPage{}, err
+	}
+	defer rows.Close()
+
+	items := []webhooks.Delivery{}
+	for rows.Next() {
+		var dbd dbDelivery
+		if err := rows.StructScan(&dbd); err != nil {
+			return webhooks.DeliveriesPage{}, err
+		}
+		items = append(items, toDelivery(dbd))
+	}
+
+	if err := rows.Err(); err != nil {
+		return webhooks.DeliveriesPage{}, err
+	}
+
+	cq := `SELECT COUNT(*) FROM deliveries WHERE webhook = $1;`
+
+	var total uint64
+	if err := dr.db.GetContext(ctx, &total, cq, id); err != nil {
+		return webhooks.DeliveriesPage{}, err
+	}
+
+	page := webhooks.DeliveriesPage{
+		Total:      total,
+		Offset:     offset,
+		Limit:      limit,
+		Deliveries: items,
+	}
+
+	return page, nil
+}
+
+type dbDelivery struct {
+	ID        string    `db:"id"`
+	Webhook   string    `db:"webhook"`
+	Status    string    `db:"status"`
+	Code      int       `db:"code"`
+	Attempts  uint64    `db:"attempts"`
+	Error     string    `db:"error"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+func toDBDelivery(d webhooks.Delivery) dbDelivery {
+	return dbDelivery{
+		ID:        d.ID,
+		Webhook:   d.Webhook,
+		Status:    d.Status,
+		Code:      d.Code,
+		Attempts:  d.Attempts,
+		Error:     d.Error,
+		CreatedAt: d.Created.UTC(),
+	}
+}
+
+func toDelivery(dbd dbDelivery) webhooks.Delivery {
+	return webhooks.Delivery{
+		ID:       dbd.ID,
+		Webhook:  dbd.Webhook,
+		Status:   dbd.Status,
+		Code:     dbd.Code,
+		Attempts: dbd.Attempts,
+		Error:    dbd.Error,
+		Created:  dbd.CreatedAt.UTC(),
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/webhooks"
+	"github.com/mainflux/mainflux/webhooks/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newDelivery(id, webhook string, created time.Time) webhooks.Delivery {
+	return webhooks.Delivery{
+		ID:       id,
+		Webhook:  webhook,
+		Status:   webhooks.Delivered,
+		Code:     200,
+		Attempts: 1,
+		Created:  created.Truncate(time.Microsecond),
+	}
+}
+
+func TestDeliverySave(t *testing.T) {
+	whs := postgres.NewWebhookRepository(db)
+	err := whs.Save(context.Background(), newWebhook(id, owner))
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	defer whs.Remove(context.Background(), id)
+
+	repo := postgres.NewDeliveryRepository(db)
+	d := newDelivery(otherID, id, time.Now().UTC())
+
+	cases := []struct {
+		desc     string
+		delivery webhooks.Delivery
+		err      error
+	}{
+		{
+			desc:     "save new delivery",
+			delivery: d,
+			err:      nil,
+		},
+		{
+			desc:     "save duplicate delivery",
+			delivery: d,
+			err:      webhooks.ErrMalformedEntity,
+		},
+		{
+			desc:     "save delivery of non-existing webhook",
+			delivery: newDelivery(wrongID, wrongID, time.Now().UTC()),
+			err:      webhooks.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := repo.Save(context.Background(), tc.delivery)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestDeliveryRetrieveByWebhook(t *testing.T) {
+	whs := postgres.NewWebhookRepository(db)
+	err := whs.Save(context.Background(), newWebhook(id, owner))
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	defer whs.Remove(context.Background(), id)
+
+	repo := postgres.NewDeliveryRepository(db)
+	now := time.Now().UTC()
+	older := newDelivery(otherID, id, now.Add(-time.Minute))
+	older.Status = webhooks.Failed
+	older.Code = 503
+	older.Attempts = 4
+	older.Error = "unexpected response status: 503"
+	recent := newDelivery(wrongID, id, now)
+
+	for _, d := range []webhooks.Delivery{older, recent} {
+		err := repo.Save(context.Background(), d)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	page, err := repo.RetrieveByWebhook(context.Background(), id, 0, 10)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, uint64(2), page.Total, fmt.Sprintf("expected total 2 got %d\n", page.Total))
+	assert.Equal(t, []webhooks.Delivery{recent, older}, page.Deliveries, fmt.Sprintf("expected most recent deliveries first got %v\n", page.Deliveries))
+
+	page, err = repo.RetrieveByWebhook(context.Background(), id, 1, 10)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, []webhooks.Delivery{older}, page.Deliveries, fmt.Sprintf("expected oldest delivery got %v\n", page.Deliveries))
+}
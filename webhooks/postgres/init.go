@@ -0,0 +1,81 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq" // required for SQL access
+	migrate "github.com/rubenv/sql-migrate"
+)
+
+// Config defines the options that are used when connecting to a PostgreSQL instance
+type Config struct {
+	Host        string
+	Port        string
+	User        string
+	Pass        string
+	Name        string
+	SSLMode     string
+	SSLCert     string
+	SSLKey      string
+	SSLRootCert string
+}
+
+// Connect creates a connection to the PostgreSQL instance and applies any
+// unapplied database migrations. A non-nil error is returned to indicate
+// failure.
+func Connect(cfg Config) (*sqlx.DB, error) {
+	url := fmt.Sprintf("host=%s port=%s user=%s dbname=%s password=%s sslmode=%s sslcert=%s sslkey=%s sslrootcert=%s", cfg.Host, cfg.Port, cfg.User, cfg.Name, cfg.Pass, cfg.SSLMode, cfg.SSLCert, cfg.SSLKey, cfg.SSLRootCert)
+
+	db, err := sqlx.Open("postgres", url)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := migrateDB(db); err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+func migrateDB(db *sqlx.DB) error {
+	migrations := &migrate.MemoryMigrationSource{
+		Migrations: []*migrate.Migration{
+			{
+				Id: "webhooks_1",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS webhooks (
+						id       UUID PRIMARY KEY,
+						owner    VARCHAR(254) NOT NULL,
+						channel  VARCHAR(254) NOT NULL,
+						subtopic VARCHAR(1024),
+						url      VARCHAR(1024) NOT NULL,
+						secret   VARCHAR(254) NOT NULL
+					)`,
+					`CREATE INDEX IF NOT EXISTS webhooks_owner_idx ON webhooks (owner)`,
+					`CREATE TABLE IF NOT EXISTS deliveries (
+						id         UUID PRIMARY KEY,
+						webhook    UUID NOT NULL REFERENCES webhooks (id) ON DELETE CASCADE,
+						status     VARCHAR(16) NOT NULL,
+						code       INTEGER NOT NULL DEFAULT 0,
+						attempts   BIGINT NOT NULL DEFAULT 0,
+						error      TEXT,
+						created_at TIMESTAMP NOT NULL
+					)`,
+					`CREATE INDEX IF NOT EXISTS deliveries_webhook_idx ON deliveries (webhook, created_at)`,
+				},
+				Down: []string{
+					`DROP TABLE IF EXISTS deliveries`,
+					`DROP TABLE IF EXISTS webhooks`,
+				},
+			},
+		},
+	}
+
+	_, err := migrate.Exec(db.DB, "postgres", migrations, migrate.Up)
+	return err
+}
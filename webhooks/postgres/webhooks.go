@@ -0,0 +1,157 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq" // required for DB access
+	"github.com/mainflux/mainflux/webhooks"
+)
+
+const (
+	errDuplicate  = "unique_violation"
+	errInvalid    = "invalid_text_representation"
+	errForeignKey = "foreign_key_violation"
+)
+
+var _ webhooks.WebhookRepository = (*webhookRepository)(nil)
+
+type webhookRepository struct {
+	db *sqlx.DB
+}
+
+// NewWebhookRepository instantiates a PostgreSQL implementation of webhook
+// repository.
+func NewWebhookRepository(db *sqlx.DB) webhooks.WebhookRepository {
+	return &webhookRepository{
+		db: db,
+	}
+}
+
+func (wr webhookRepository) Save(ctx context.Context, wh webhooks.Webhook) error {
+	q := `INSERT INTO webhooks (id, owner, channel, subtopic, url, secret)
+	      VALUES (:id, :owner, :channel, :subtopic, :url, :secret);`
+
+	if _, err := wr.db.NamedExecContext(ctx, q, toDBWebhook(wh)); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == errDuplicate {
+			return webhooks.ErrMalformedEntity
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (wr webhookRepository) RetrieveByID(ctx context.Context, id string) (webhooks.Webhook, error) {
+	q := `SELECT id, owner, channel, subtopic, url, secret FROM webhooks WHERE id = $1;`
+
+	var dbw dbWebhook
+	if err := wr.db.QueryRowxContext(ctx, q, id).StructScan(&dbw); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if err == sql.ErrNoRows || ok && errInvalid == pqErr.Code.Name() {
+			return webhooks.Webhook{}, webhooks.ErrNotFound
+		}
+		return webhooks.Webhook{}, err
+	}
+
+	return toWebhook(dbw), nil
+}
+
+func (wr webhookRepository) RetrieveAll(ctx context.Context) ([]webhooks.Webhook, error) {
+	q := `SELECT id, owner, channel, subtopic, url, secret FROM webhooks;`
+
+	return wr.retrieve(ctx, q)
+}
+
+func (wr webhookRepository) RetrieveByOwner(ctx context.Context, owner string, offset, limit uint64) (webhooks.WebhooksPage, error) {
+	q := `SELECT id, owner, channel, subtopic, url, secret FROM webhooks
+	      WHERE owner = $1 ORDER BY id LIMIT $2 OFFSET $3;`
+
+	items, err := wr.retrieve(ctx, q, owner, limit, offset)
+	if err != nil {
+		return webhooks.WebhooksPage{}, err
+	}
+
+	cq := `SELECT COUNT(*) FROM webhooks WHERE owner = $1;`
+
+	var total uint64
+	if err := wr.db.GetContext(ctx, &total, cq, owner); err != nil {
+		return webhooks.WebhooksPage{}, err
+	}
+
+	page := webhooks.WebhooksPage{
+		Total:    total,
+		Offset:   offset,
+		Limit:    limit,
+		Webhooks: items,
+	}
+
+	return page, nil
+}
+
+func (wr webhookRepository) Remove(ctx context.Context, id string) error {
+	q := `DELETE FROM webhooks WHERE id = $1;`
+
+	if _, err := wr.db.ExecContext(ctx, q, id); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == errInvalid {
+			return nil
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (wr webhookRepository) retrieve(ctx context.Context, q string, args ...interface{}) ([]webhooks.Webhook, error) {
+	rows, err := wr.db.QueryxContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []webhooks.Webhook{}
+	for rows.Next() {
+		var dbw dbWebhook
+		if err := rows.StructScan(&dbw); err != nil {
+			return nil, err
+		}
+		items = append(items, toWebhook(dbw))
+	}
+
+	return items, rows.Err()
+}
+
+type dbWebhook struct {
+	ID       string `db:"id"`
+	Owner    string `db:"owner"`
+	Channel  string `db:"channel"`
+	Subtopic string `db:"subtopic"`
+	URL      string `db:"url"`
+	Secret   string `db:"secret"`
+}
+
+func toDBWebhook(wh webhooks.Webhook) dbWebhook {
+	return dbWebhook{
+		ID:       wh.ID,
+		Owner:    wh.Owner,
+		Channel:  wh.Channel,
+		Subtopic: wh.Subtopic,
+		URL:      wh.URL,
+		Secret:   wh.Secret,
+	}
+}
+
+func toWebhook(dbw dbWebhook) webhooks.Webhook {
+	return webhooks.Webhook{
+		ID:       dbw.ID,
+		Owner:    dbw.Owner,
+		Channel:  dbw.Channel,
+		Subtopic: dbw.Subtopic,
+		URL:      dbw.URL,
+		Secret:   dbw.Secret,
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux/webhooks"
+	"github.com/mainflux/mainflux/webhooks/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	id      = "123e4567-e89b-12d3-a456-000000000001"
+	otherID = "123e4567-e89b-12d3-a456-000000000002"
+	wrongID = "123e4567-e89b-12d3-a456-000000000099"
+	owner   = "user@example.com"
+	chanID  = "1"
+)
+
+func newWebhook(id, owner string) webhooks.Webhook {
+	return webhooks.Webhook{
+		ID:       id,
+		Owner:    owner,
+		Channel:  chanID,
+		Subtopic: "temp.>",
+		URL:      "https://example.com/hooks",
+		Secret:   "secret",
+	}
+}
+
+func TestWebhookSave(t *testing.T) {
+	repo := postgres.NewWebhookRepository(db)
+	wh := newWebhook(id, owner)
+
+	cases := []struct {
+		desc    string
+		webhook webhooks.Webhook
+		err     error
+	}{
+		{
+			desc:    "save new webhook",
+			webhook: wh,
+			err:     nil,
+		},
+		{
+			desc:    "save duplicate webhook",
+			webhook: wh,
+			err:     webhooks.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		err := repo.Save(context.Background(), tc.webhook)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	repo.Remove(context.Background(), id)
+}
+
+func TestWebhookRetrieveByID(t *testing.T) {
+	repo := postgres.NewWebhookRepository(db)
+	wh := newWebhook(id, owner)
+	err := repo.Save(context.Background(), wh)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	defer repo.Remove(context.Background(), id)
+
+	cases := []struct {
+		desc    string
+		id      string
+		webhook webhooks.Webhook
+		err     error
+	}{
+		{
+			desc:    "retrieve existing webhook",
+			id:      id,
+			webhook: wh,
+			err:     nil,
+		},
+		{
+			desc:    "retrieve non-existing webhook",
+			id:      wrongID,
+			webhook: webhooks.Webhook{},
+			err:     webhooks.ErrNotFound,
+		},
+		{
+			desc:    "retrieve webhook with malformed ID",
+			id:      "malformed",
+			webhook: webhooks.Webhook{},
+			err:     webhooks.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		wh, err := repo.RetrieveByID(context.Background(), tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.webhook, wh, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.webhook, wh))
+	}
+}
+
+func TestWebhookRetrieveByOwner(t *testing.T) {
+	repo := postgres.NewWebhookRepository(db)
+	for _, wh := range []webhooks.Webhook{newWebhook(id, owner), newWebhook(otherID, "other@example.com")} {
+		err := repo.Save(context.Background(), wh)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		defer repo.Remove(context.Background(), wh.ID)
+	}
+
+	cases := []struct {
+		desc  string
+		owner string
+		size  int
+		total uint64
+	}{
+		{
+			desc:  "retrieve webhooks of owner",
+			owner: owner,
+			size:  1,
+			total: 1,
+		},
+		{
+			desc:  "retrieve webhooks of owner without webhooks",
+			owner: "none@example.com",
+			size:  0,
+			total: 0,
+		},
+	}
+
+	for _, tc := range cases {
+		page, err := repo.RetrieveByOwner(context.Background(), tc.owner, 0, 10)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s\n", tc.desc, err))
+		assert.Equal(t, tc.size, len(page.Webhooks), fmt.Sprintf("%s: expected %d webhooks got %d\n", tc.desc, tc.size, len(page.Webhooks)))
+		assert.Equal(t, tc.total, page.Total, fmt.Sprintf("%s: expected total %d got %d\n", tc.desc, tc.total, page.Total))
+	}
+
+	all, err := repo.RetrieveAll(context.Background())
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, 2, len(all), fmt.Sprintf("expected 2 webhooks got %d\n", len(all)))
+}
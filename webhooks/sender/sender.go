@@ -0,0 +1,51 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package sender
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/webhooks"
+)
+
+var _ webhooks.Sender = (*sender)(nil)
+
+type sender struct {
+	client *http.Client
+}
+
+// New returns sender which posts the SenML JSON payloads to the webhooks.
+func New(timeout time.Duration) webhooks.Sender {
+	return sender{
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (s sender) Send(url string, headers map[string]string, payload []byte) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", mainflux.SenMLJSON)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	// Response body is drained, so that the connection can be reused.
+	io.Copy(ioutil.Discard, res.Body)
+
+	return res.StatusCode, nil
+}
@@ -0,0 +1,357 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/cisco/senml"
+	"github.com/mainflux/mainflux"
+)
+
+var (
+	// ErrMalformedEntity indicates malformed entity specification.
+	ErrMalformedEntity = errors.New("malformed entity specification")
+
+	// ErrUnauthorizedAccess indicates missing or invalid credentials provided
+	// when accessing a protected resource.
+	ErrUnauthorizedAccess = errors.New("missing or invalid credentials provided")
+
+	// ErrNotFound indicates a non-existent entity request.
+	ErrNotFound = errors.New("non-existent entity")
+)
+
+// Service specifies an API that must be fulfilled by the domain service
+// implementation, and all of its decorators (e.g. logging & metrics).
+type Service interface {
+	// CreateWebhook adds new webhook to the user identified by the provided
+	// key. The secret is generated unless provided.
+	CreateWebhook(context.Context, string, Webhook) (Webhook, error)
+
+	// ViewWebhook retrieves the webhook identified by the provided ID, that
+	// belongs to the user identified by the provided key.
+	ViewWebhook(context.Context, string, string) (Webhook, error)
+
+	// ListWebhooks retrieves subset of webhooks that belong to the user
+	// identified by the provided key.
+	ListWebhooks(context.Context, string, uint64, uint64) (WebhooksPage, error)
+
+	// RemoveWebhook removes the webhook identified by the provided ID, that
+	// belongs to the user identified by the provided key.
+	RemoveWebhook(context.Context, string, string) error
+
+	// ListDeliveries retrieves subset of deliveries of the webhook
+	// identified by the provided ID, that belongs to the user identified by
+	// the provided key.
+	ListDeliveries(context.Context, string, string, uint64, uint64) (DeliveriesPage, error)
+
+	// Forward sends the message to all the matching webhooks, retrying the
+	// failed attempts, and logs the delivery outcome.
+	Forward(context.Context, mainflux.Message) error
+
+	// Reload replaces the set of active webhooks with the persisted ones.
+	// It surpasses ownership check, since it is used to pick up the changes
+	// made through the other service instances.
+	Reload(context.Context) error
+}
+
+var _ Service = (*webhooksService)(nil)
+
+type webhooksService struct {
+	users      mainflux.UsersServiceClient
+	channels   Channels
+	webhooks   WebhookRepository
+	deliveries DeliveryRepository
+	sender     Sender
+	idp        IdentityProvider
+	cfg        Config
+
+	mu     sync.RWMutex
+	active map[string]map[string]Webhook
+}
+
+// New instantiates the webhooks service implementation.
+func New(users mainflux.UsersServiceClient, channels Channels, webhooks WebhookRepository, deliveries DeliveryRepository, sender Sender, idp IdentityProvider, cfg Config) Service {
+	return &webhooksService{
+		users:      users,
+		channels:   channels,
+		webhooks:   webhooks,
+		deliveries: deliveries,
+		sender:     sender,
+		idp:        idp,
+		cfg:        cfg,
+		active:     make(map[string]map[string]Webhook),
+	}
+}
+
+func (ws *webhooksService) CreateWebhook(ctx context.Context, token string, wh Webhook) (Webhook, error) {
+	owner, err := ws.identify(ctx, token)
+	if err != nil {
+		return Webhook{}, err
+	}
+
+	if err := wh.Validate(); err != nil {
+		return Webhook{}, err
+	}
+
+	if err := ws.channels.Authorize(token, wh.Channel); err != nil {
+		return Webhook{}, err
+	}
+
+	wh.ID, err = ws.idp.ID()
+	if err != nil {
+		return Webhook{}, err
+	}
+	wh.Owner = owner
+
+	if wh.Secret == "" {
+		wh.Secret, err = ws.idp.ID()
+		if err != nil {
+			return Webhook{}, err
+		}
+	}
+
+	if err := ws.webhooks.Save(ctx, wh); err != nil {
+		return Webhook{}, err
+	}
+
+	ws.activate(wh)
+	return wh, nil
+}
+
+func (ws *webhooksService) ViewWebhook(ctx context.Context, token, id string) (Webhook, error) {
+	owner, err := ws.identify(ctx, token)
+	if err != nil {
+		return Webhook{}, err
+	}
+
+	wh, err := ws.webhooks.RetrieveByID(ctx, id)
+	if err != nil {
+		return Webhook{}, err
+	}
+
+	if wh.Owner != owner {
+		return Webhook{}, ErrNotFound
+	}
+
+	return wh, nil
+}
+
+func (ws *webhooksService) ListWebhooks(ctx context.Context, token string, offset, limit uint64) (WebhooksPage, error) {
+	owner, err := ws.identify(ctx, token)
+	if err != nil {
+		return WebhooksPage{}, err
+	}
+
+	return ws.webhooks.RetrieveByOwner(ctx, owner, offset, limit)
+}
+
+func (ws *webhooksService) RemoveWebhook(ctx context.Context, token, id string) error {
+	if _, err := ws.ViewWebhook(ctx, token, id); err != nil {
+		return err
+	}
+
+	if err := ws.webhooks.Remove(ctx, id); err != nil {
+		return err
+	}
+
+	ws.deactivate(id)
+	return nil
+}
+
+func (ws *webhooksService) ListDeliveries(ctx context.Context, token, id string, offset, limit uint64) (DeliveriesPage, error) {
+	if _, err := ws.ViewWebhook(ctx, token, id); err != nil {
+		return DeliveriesPage{}, err
+	}
+
+	return ws.deliveries.RetrieveByWebhook(ctx, id, offset, limit)
+}
+
+func (ws *webhooksService) Forward(ctx context.Context, msg mainflux.Message) error {
+	ws.mu.RLock()
+	var whs []Webhook
+	for _, wh := range ws.active[msg.Channel] {
+		if wh.Matches(msg.Channel, msg.Subtopic) {
+			whs = append(whs, wh)
+		}
+	}
+	ws.mu.RUnlock()
+
+	if len(whs) == 0 {
+		return nil
+	}
+
+	payload, err := encode(msg)
+	if err != nil {
+		return err
+	}
+
+	// Webhooks are called concurrently, so that the slow or unavailable
+	// endpoint doesn't delay the others.
+	var wg sync.WaitGroup
+	errs := make(chan error, len(whs))
+	for _, wh := range whs {
+		wg.Add(1)
+		go func(wh Webhook) {
+			defer wg.Done()
+			if err := ws.deliver(ctx, wh, payload); err != nil {
+				errs <- err
+			}
+		}(wh)
+	}
+	wg.Wait()
+	close(errs)
+
+	return <-errs
+}
+
+func (ws *webhooksService) Reload(ctx context.Context) error {
+	whs, err := ws.webhooks.RetrieveAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	active := make(map[string]map[string]Webhook)
+	for _, wh := range whs {
+		if _, ok := active[wh.Channel]; !ok {
+			active[wh.Channel] = make(map[string]Webhook)
+		}
+		active[wh.Channel][wh.ID] = wh
+	}
+
+	ws.mu.Lock()
+	ws.active = active
+	ws.mu.Unlock()
+
+	return nil
+}
+
+func (ws *webhooksService) identify(ctx context.Context, token string) (string, error) {
+	res, err := ws.users.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return "", ErrUnauthorizedAccess
+	}
+
+	return res.GetValue(), nil
+}
+
+// deliver sends the payload to the webhook, retrying the attempts which fail
+// due to the transport errors, server errors or rate limiting, and logs the
+// delivery outcome.
+func (ws *webhooksService) deliver(ctx context.Context, wh Webhook, payload []byte) error {
+	id, err := ws.idp.ID()
+	if err != nil {
+		return err
+	}
+
+	headers := map[string]string{
+		SignatureHeader: Sign(wh.Secret, payload),
+		WebhookHeader:   wh.ID,
+		DeliveryHeader:  id,
+	}
+
+	d := Delivery{
+		ID:      id,
+		Webhook: wh.ID,
+		Status:  Failed,
+		Created: time.Now(),
+	}
+
+	for retry := uint64(0); retry <= ws.cfg.Retries; retry++ {
+		if retry > 0 && !ws.wait(ctx, ws.cfg.Delay(retry)) {
+			break
+		}
+
+		d.Attempts++
+		code, err := ws.sender.Send(wh.URL, headers, payload)
+		d.Code = code
+		d.Error = ""
+		if err != nil {
+			d.Error = err.Error()
+			continue
+		}
+
+		if code >= http.StatusOK && code < http.StatusMultipleChoices {
+			d.Status = Delivered
+			break
+		}
+
+		d.Error = fmt.Sprintf("unexpected response status: %d", code)
+		if code != http.StatusTooManyRequests && code < http.StatusInternalServerError {
+			break
+		}
+	}
+
+	return ws.deliveries.Save(ctx, d)
+}
+
+// wait returns false if the context is done before the delay expires.
+func (ws *webhooksService) wait(ctx context.Context, delay time.Duration) bool {
+	t := time.NewTimer(delay)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
+
+func (ws *webhooksService) activate(wh Webhook) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	if _, ok := ws.active[wh.Channel]; !ok {
+		ws.active[wh.Channel] = make(map[string]Webhook)
+	}
+	ws.active[wh.Channel][wh.ID] = wh
+}
+
+func (ws *webhooksService) deactivate(id string) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	for ch, whs := range ws.active {
+		delete(whs, id)
+		if len(whs) == 0 {
+			delete(ws.active, ch)
+		}
+	}
+}
+
+// encode converts normalized message back to the SenML JSON, so that the
+// endpoints don't depend on the Mainflux internal representation.
+func encode(msg mainflux.Message) ([]byte, error) {
+	rec := senml.SenMLRecord{
+		Name:       msg.Name,
+		Unit:       msg.Unit,
+		Time:       msg.Time,
+		UpdateTime: msg.UpdateTime,
+		Link:       msg.Link,
+	}
+
+	switch v := msg.Value.(type) {
+	case *mainflux.Message_FloatValue:
+		rec.Value = &v.FloatValue
+	case *mainflux.Message_StringValue:
+		rec.StringValue = v.StringValue
+	case *mainflux.Message_BoolValue:
+		rec.BoolValue = &v.BoolValue
+	case *mainflux.Message_DataValue:
+		rec.DataValue = v.DataValue
+	}
+
+	if msg.ValueSum != nil {
+		rec.Sum = &msg.ValueSum.Value
+	}
+
+	s := senml.SenML{Records: []senml.SenMLRecord{rec}}
+	return senml.Encode(s, senml.JSON, senml.OutputOptions{})
+}
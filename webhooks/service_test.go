@@ -0,0 +1,401 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package webhooks_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/webhooks"
+	"github.com/mainflux/mainflux/webhooks/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	wrongValue = "wrong-value"
+	email      = "user@example.com"
+	token      = "token"
+	otherEmail = "other@example.com"
+	otherToken = "other"
+	chanID     = "1"
+	otherChan  = "2"
+	url        = "https://example.com/hooks"
+	secret     = "secret"
+)
+
+var (
+	webhook = webhooks.Webhook{
+		Channel:  chanID,
+		Subtopic: "temp.>",
+		URL:      url,
+		Secret:   secret,
+	}
+
+	cfg = webhooks.Config{
+		Retries:    3,
+		Backoff:    time.Millisecond,
+		MaxBackoff: 2 * time.Millisecond,
+	}
+)
+
+func newService() (webhooks.Service, *mocks.Sender) {
+	users := mocks.NewUsersService(map[string]string{token: email, otherToken: otherEmail})
+	channels := mocks.NewChannels(map[string]string{chanID: token, otherChan: otherToken})
+	sender := mocks.NewSender()
+
+	svc := webhooks.New(users, channels, mocks.NewWebhookRepository(), mocks.NewDeliveryRepository(), sender, mocks.NewIdentityProvider(), cfg)
+	return svc, sender
+}
+
+func TestCreateWebhook(t *testing.T) {
+	svc, _ := newService()
+
+	noURL := webhook
+	noURL.URL = ""
+
+	invalidURL := webhook
+	invalidURL.URL = "ftp://example.com/hooks"
+
+	otherChannel := webhook
+	otherChannel.Channel = otherChan
+
+	cases := []struct {
+		desc    string
+		webhook webhooks.Webhook
+		token   string
+		err     error
+	}{
+		{
+			desc:    "create valid webhook",
+			webhook: webhook,
+			token:   token,
+			err:     nil,
+		},
+		{
+			desc:    "create webhook with wrong credentials",
+			webhook: webhook,
+			token:   wrongValue,
+			err:     webhooks.ErrUnauthorizedAccess,
+		},
+		{
+			desc:    "create webhook without URL",
+			webhook: noURL,
+			token:   token,
+			err:     webhooks.ErrMalformedEntity,
+		},
+		{
+			desc:    "create webhook with non-HTTP URL",
+			webhook: invalidURL,
+			token:   token,
+			err:     webhooks.ErrMalformedEntity,
+		},
+		{
+			desc:    "create webhook of channel owned by other user",
+			webhook: otherChannel,
+			token:   token,
+			err:     webhooks.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := svc.CreateWebhook(context.Background(), tc.token, tc.webhook)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	noSecret := webhook
+	noSecret.Secret = ""
+	saved, err := svc.CreateWebhook(context.Background(), token, noSecret)
+	assert.Nil(t, err, fmt.Sprintf("create webhook without secret: unexpected error: %s\n", err))
+	assert.NotEmpty(t, saved.Secret, "create webhook without secret: expected generated secret")
+}
+
+func TestViewWebhook(t *testing.T) {
+	svc, _ := newService()
+	saved, err := svc.CreateWebhook(context.Background(), token, webhook)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc  string
+		id    string
+		token string
+		err   error
+	}{
+		{
+			desc:  "view existing webhook",
+			id:    saved.ID,
+			token: token,
+			err:   nil,
+		},
+		{
+			desc:  "view webhook with wrong credentials",
+			id:    saved.ID,
+			token: wrongValue,
+			err:   webhooks.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "view webhook owned by other user",
+			id:    saved.ID,
+			token: otherToken,
+			err:   webhooks.ErrNotFound,
+		},
+		{
+			desc:  "view non-existing webhook",
+			id:    wrongValue,
+			token: token,
+			err:   webhooks.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := svc.ViewWebhook(context.Background(), tc.token, tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestListWebhooks(t *testing.T) {
+	svc, _ := newService()
+	n := uint64(5)
+	for i := uint64(0); i < n; i++ {
+		_, err := svc.CreateWebhook(context.Background(), token, webhook)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	}
+
+	cases := []struct {
+		desc   string
+		token  string
+		offset uint64
+		limit  uint64
+		size   int
+		err    error
+	}{
+		{
+			desc:   "list all webhooks",
+			token:  token,
+			offset: 0,
+			limit:  n,
+			size:   int(n),
+		},
+		{
+			desc:   "list last webhook",
+			token:  token,
+			offset: n - 1,
+			limit:  n,
+			size:   1,
+		},
+		{
+			desc:   "list webhooks of user without webhooks",
+			token:  otherToken,
+			offset: 0,
+			limit:  n,
+			size:   0,
+		},
+		{
+			desc:   "list webhooks with wrong credentials",
+			token:  wrongValue,
+			offset: 0,
+			limit:  n,
+			size:   0,
+			err:    webhooks.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		page, err := svc.ListWebhooks(context.Background(), tc.token, tc.offset, tc.limit)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.size, len(page.Webhooks), fmt.Sprintf("%s: expected %d got %d\n", tc.desc, tc.size, len(page.Webhooks)))
+	}
+}
+
+func TestRemoveWebhook(t *testing.T) {
+	svc, sender := newService()
+	saved, err := svc.CreateWebhook(context.Background(), token, webhook)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	err = svc.RemoveWebhook(context.Background(), otherToken, saved.ID)
+	assert.Equal(t, webhooks.ErrNotFound, err, fmt.Sprintf("remove webhook owned by other user: expected %s got %s\n", webhooks.ErrNotFound, err))
+
+	err = svc.RemoveWebhook(context.Background(), token, saved.ID)
+	assert.Nil(t, err, fmt.Sprintf("remove existing webhook: unexpected error: %s\n", err))
+
+	err = svc.Forward(context.Background(), message())
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.Empty(t, sender.Requests(), "removed webhook must not be called")
+}
+
+func TestForward(t *testing.T) {
+	otherSubtopic := message()
+	otherSubtopic.Subtopic = "humidity"
+
+	cases := []struct {
+		desc      string
+		msg       mainflux.Message
+		responses []int
+		requests  int
+		status    string
+		code      int
+	}{
+		{
+			desc:     "forward message to webhook",
+			msg:      message(),
+			requests: 1,
+			status:   webhooks.Delivered,
+			code:     http.StatusOK,
+		},
+		{
+			desc:     "skip message with non-matching subtopic",
+			msg:      otherSubtopic,
+			requests: 0,
+		},
+		{
+			desc:      "retry delivery after server error",
+			msg:       message(),
+			responses: []int{http.StatusServiceUnavailable, 0, http.StatusAccepted},
+			requests:  3,
+			status:    webhooks.Delivered,
+			code:      http.StatusAccepted,
+		},
+		{
+			desc:      "retry delivery after rate limiting",
+			msg:       message(),
+			responses: []int{http.StatusTooManyRequests, http.StatusOK},
+			requests:  2,
+			status:    webhooks.Delivered,
+			code:      http.StatusOK,
+		},
+		{
+			desc:      "fail delivery after all retries",
+			msg:       message(),
+			responses: []int{http.StatusInternalServerError},
+			requests:  int(cfg.Retries) + 1,
+			status:    webhooks.Failed,
+			code:      http.StatusInternalServerError,
+		},
+		{
+			desc:      "fail delivery rejected by client error without retries",
+			msg:       message(),
+			responses: []int{http.StatusBadRequest},
+			requests:  1,
+			status:    webhooks.Failed,
+			code:      http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range cases {
+		svc, sender := newService()
+		saved, err := svc.CreateWebhook(context.Background(), token, webhook)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s\n", tc.desc, err))
+		sender.Respond(url, tc.responses...)
+
+		err = svc.Forward(context.Background(), tc.msg)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s\n", tc.desc, err))
+
+		reqs := sender.Requests()
+		assert.Equal(t, tc.requests, len(reqs), fmt.Sprintf("%s: expected %d requests got %d\n", tc.desc, tc.requests, len(reqs)))
+
+		page, err := svc.ListDeliveries(context.Background(), token, saved.ID, 0, 10)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s\n", tc.desc, err))
+		if tc.requests == 0 {
+			assert.Empty(t, page.Deliveries, fmt.Sprintf("%s: expected no deliveries\n", tc.desc))
+			continue
+		}
+
+		require.Equal(t, 1, len(page.Deliveries), fmt.Sprintf("%s: expected 1 delivery got %d\n", tc.desc, len(page.Deliveries)))
+		d := page.Deliveries[0]
+		assert.Equal(t, tc.status, d.Status, fmt.Sprintf("%s: expected status %s got %s\n", tc.desc, tc.status, d.Status))
+		assert.Equal(t, tc.code, d.Code, fmt.Sprintf("%s: expected code %d got %d\n", tc.desc, tc.code, d.Code))
+		assert.Equal(t, uint64(tc.requests), d.Attempts, fmt.Sprintf("%s: expected %d attempts got %d\n", tc.desc, tc.requests, d.Attempts))
+
+		for _, req := range reqs {
+			sig := webhooks.Sign(secret, req.Payload)
+			assert.Equal(t, sig, req.Headers[webhooks.SignatureHeader], fmt.Sprintf("%s: expected signature %s got %s\n", tc.desc, sig, req.Headers[webhooks.SignatureHeader]))
+			assert.Equal(t, d.ID, req.Headers[webhooks.DeliveryHeader], fmt.Sprintf("%s: expected delivery %s got %s\n", tc.desc, d.ID, req.Headers[webhooks.DeliveryHeader]))
+		}
+	}
+}
+
+func TestListDeliveries(t *testing.T) {
+	svc, _ := newService()
+	saved, err := svc.CreateWebhook(context.Background(), token, webhook)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	n := 3
+	for i := 0; i < n; i++ {
+		err := svc.Forward(context.Background(), message())
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	}
+
+	cases := []struct {
+		desc  string
+		id    string
+		token string
+		size  int
+		err   error
+	}{
+		{
+			desc:  "list deliveries of webhook",
+			id:    saved.ID,
+			token: token,
+			size:  n,
+		},
+		{
+			desc:  "list deliveries of webhook owned by other user",
+			id:    saved.ID,
+			token: otherToken,
+			size:  0,
+			err:   webhooks.ErrNotFound,
+		},
+		{
+			desc:  "list deliveries with wrong credentials",
+			id:    saved.ID,
+			token: wrongValue,
+			size:  0,
+			err:   webhooks.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		page, err := svc.ListDeliveries(context.Background(), tc.token, tc.id, 0, 10)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.size, len(page.Deliveries), fmt.Sprintf("%s: expected %d got %d\n", tc.desc, tc.size, len(page.Deliveries)))
+	}
+}
+
+func TestDelay(t *testing.T) {
+	cfg := webhooks.Config{
+		Backoff:    time.Second,
+		MaxBackoff: 5 * time.Second,
+	}
+
+	cases := []struct {
+		retry uint64
+		delay time.Duration
+	}{
+		{retry: 1, delay: time.Second},
+		{retry: 2, delay: 2 * time.Second},
+		{retry: 3, delay: 4 * time.Second},
+		{retry: 4, delay: 5 * time.Second},
+		{retry: 10, delay: 5 * time.Second},
+	}
+
+	for _, tc := range cases {
+		delay := cfg.Delay(tc.retry)
+		assert.Equal(t, tc.delay, delay, fmt.Sprintf("retry %d: expected delay %s got %s\n", tc.retry, tc.delay, delay))
+	}
+}
+
+func message() mainflux.Message {
+	return mainflux.Message{
+		Channel:   chanID,
+		Subtopic:  "temp.room",
+		Publisher: "device",
+		Name:      "temperature",
+		Time:      float64(time.Now().Unix()),
+		Value:     &mainflux.Message_FloatValue{FloatValue: 35},
+	}
+}
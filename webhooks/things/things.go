@@ -0,0 +1,38 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package things contains the channels ownership check backed by the
+// Mainflux SDK.
+package things
+
+import (
+	mfsdk "github.com/mainflux/mainflux/sdk/go"
+	"github.com/mainflux/mainflux/webhooks"
+)
+
+var _ webhooks.Channels = (*channels)(nil)
+
+type channels struct {
+	sdk mfsdk.SDK
+}
+
+// New returns channels API client backed by the provided SDK. Since the
+// things service returns only the channels owned by the user, the channel
+// is owned if it can be retrieved.
+func New(sdk mfsdk.SDK) webhooks.Channels {
+	return channels{sdk: sdk}
+}
+
+func (c channels) Authorize(token, chanID string) error {
+	_, err := c.sdk.Channel(chanID, token)
+	switch err {
+	case nil:
+		return nil
+	case mfsdk.ErrUnauthorized:
+		return webhooks.ErrUnauthorizedAccess
+	case mfsdk.ErrNotFound:
+		return webhooks.ErrNotFound
+	default:
+		return err
+	}
+}
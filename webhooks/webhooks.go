@@ -0,0 +1,210 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package webhooks
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Supported delivery statuses.
+const (
+	// Delivered status indicates that the endpoint accepted the message.
+	Delivered = "delivered"
+
+	// Failed status indicates that the endpoint didn't accept the message
+	// after all the attempts.
+	Failed = "failed"
+)
+
+// Headers sent along with every webhook request.
+const (
+	// SignatureHeader contains the HMAC-SHA256 signature of the payload,
+	// computed using the webhook secret.
+	SignatureHeader = "X-Mainflux-Signature"
+
+	// WebhookHeader contains the webhook identifier.
+	WebhookHeader = "X-Mainflux-Webhook"
+
+	// DeliveryHeader contains the delivery identifier, which is the same for
+	// all the attempts of the delivery.
+	DeliveryHeader = "X-Mainflux-Delivery"
+)
+
+const (
+	subtopicWildcard = ">"
+	signaturePrefix  = "sha256="
+)
+
+// Webhook represents the HTTP endpoint the messages published to the channel
+// are forwarded to. Each webhook is owned by one user.
+type Webhook struct {
+	ID      string
+	Owner   string
+	Channel string
+
+	// Subtopic is matched exactly, unless it ends with ">" in which case
+	// it matches all the subtopics with the given prefix. Empty subtopic
+	// matches any message.
+	Subtopic string
+
+	// URL is the HTTP endpoint, while secret is used to sign the payloads,
+	// so that the endpoint can verify their origin.
+	URL    string
+	Secret string
+}
+
+// WebhooksPage contains page related metadata as well as list of webhooks
+// that belong to this page.
+type WebhooksPage struct {
+	Total    uint64
+	Offset   uint64
+	Limit    uint64
+	Webhooks []Webhook
+}
+
+// Delivery represents the outcome of forwarding the message to the webhook.
+// Code and error are the ones of the last attempt.
+type Delivery struct {
+	ID       string
+	Webhook  string
+	Status   string
+	Code     int
+	Attempts uint64
+	Error    string
+	Created  time.Time
+}
+
+// DeliveriesPage contains page related metadata as well as list of
+// deliveries that belong to this page.
+type DeliveriesPage struct {
+	Total      uint64
+	Offset     uint64
+	Limit      uint64
+	Deliveries []Delivery
+}
+
+// Config contains the retry policy of the deliveries.
+type Config struct {
+	// Retries is the number of attempts made after the first one fails.
+	Retries uint64
+
+	// Backoff is the delay before the first retry, which is doubled before
+	// each of the next ones, up to the max backoff.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+}
+
+// Validate returns ErrMalformedEntity if webhook isn't valid.
+func (w Webhook) Validate() error {
+	if w.Channel == "" || w.URL == "" {
+		return ErrMalformedEntity
+	}
+
+	u, err := url.Parse(w.URL)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return ErrMalformedEntity
+	}
+
+	return nil
+}
+
+// Matches determines whether the message published to the channel subtopic
+// should be forwarded to the webhook.
+func (w Webhook) Matches(channel, subtopic string) bool {
+	if w.Channel != channel {
+		return false
+	}
+
+	if w.Subtopic == "" || w.Subtopic == subtopic {
+		return true
+	}
+
+	if strings.HasSuffix(w.Subtopic, subtopicWildcard) {
+		return strings.HasPrefix(subtopic, strings.TrimSuffix(w.Subtopic, subtopicWildcard))
+	}
+
+	return false
+}
+
+// Delay returns the backoff before the provided retry, starting with 1.
+func (cfg Config) Delay(retry uint64) time.Duration {
+	d := cfg.Backoff
+	for i := uint64(1); i < retry; i++ {
+		d *= 2
+		if cfg.MaxBackoff > 0 && d >= cfg.MaxBackoff {
+			return cfg.MaxBackoff
+		}
+	}
+
+	return d
+}
+
+// Sign returns the value of the signature header of the payload. Endpoints
+// verify the payload by computing the same value using the webhook secret.
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// WebhookRepository specifies a webhook persistence API.
+type WebhookRepository interface {
+	// Save persists the webhook. A non-nil error is returned to indicate
+	// operation failure.
+	Save(context.Context, Webhook) error
+
+	// RetrieveByID retrieves the webhook having the provided identifier.
+	RetrieveByID(context.Context, string) (Webhook, error)
+
+	// RetrieveAll retrieves all the webhooks. It is used to populate the
+	// set of active webhooks.
+	RetrieveAll(context.Context) ([]Webhook, error)
+
+	// RetrieveByOwner retrieves the subset of webhooks owned by the
+	// specified user.
+	RetrieveByOwner(context.Context, string, uint64, uint64) (WebhooksPage, error)
+
+	// Remove removes the webhook having the provided identifier, along
+	// with its deliveries.
+	Remove(context.Context, string) error
+}
+
+// DeliveryRepository specifies a delivery log persistence API.
+type DeliveryRepository interface {
+	// Save persists the delivery. A non-nil error is returned to indicate
+	// operation failure.
+	Save(context.Context, Delivery) error
+
+	// RetrieveByWebhook retrieves the subset of deliveries of the webhook,
+	// starting with the most recent one.
+	RetrieveByWebhook(context.Context, string, uint64, uint64) (DeliveriesPage, error)
+}
+
+// Sender specifies an API for sending the payloads to the HTTP endpoints.
+type Sender interface {
+	// Send posts the payload along with the headers to the URL, and
+	// returns the response status code. A non-nil error is returned if the
+	// response isn't received.
+	Send(url string, headers map[string]string, payload []byte) (int, error)
+}
+
+// Channels specifies an API for checking the channel ownership, so that the
+// users can't forward the messages of other users.
+type Channels interface {
+	// Authorize returns ErrNotFound if the channel doesn't exist or isn't
+	// owned by the user identified by the provided key.
+	Authorize(token, chanID string) error
+}
+
+// IdentityProvider specifies an API for generating unique identifiers.
+type IdentityProvider interface {
+	// ID generates the unique identifier.
+	ID() (string, error)
+}
@@ -27,10 +27,10 @@ func MetricsMiddleware(repo writers.MessageRepository, counter metrics.Counter,
 	}
 }
 
-func (mm *metricsMiddleware) Save(msg mainflux.Message) error {
+func (mm *metricsMiddleware) Save(msgs ...mainflux.Message) error {
 	defer func(begin time.Time) {
-		mm.counter.With("method", "handle_message").Add(1)
+		mm.counter.With("method", "handle_message").Add(float64(len(msgs)))
 		mm.latency.With("method", "handle_message").Observe(time.Since(begin).Seconds())
 	}(time.Now())
-	return mm.repo.Save(msg)
+	return mm.repo.Save(msgs...)
 }
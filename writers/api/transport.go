@@ -1,6 +1,7 @@
 // Copyright (c) Mainflux
 // SPDX-License-Identifier: Apache-2.0
 
+//go:build !test
 // +build !test
 
 package api
@@ -17,6 +18,7 @@ import (
 func MakeHandler(svcName string) http.Handler {
 	r := bone.New()
 	r.GetFunc("/version", mainflux.Version(svcName))
+	r.GetFunc(mainflux.DiscoveryPath, mainflux.Discovery(svcName, mainflux.Capabilities{}))
 	r.Handle("/metrics", promhttp.Handler())
 
 	return r
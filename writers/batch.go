@@ -0,0 +1,84 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package writers
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	log "github.com/mainflux/mainflux/logger"
+)
+
+var _ MessageRepository = (*batcher)(nil)
+
+type batcher struct {
+	repo   MessageRepository
+	size   int
+	logger log.Logger
+	mu     sync.Mutex
+	batch  []mainflux.Message
+}
+
+// NewBatcher returns message repository that buffers the saved messages and
+// passes them on to the provided repository in batches. The batch is saved
+// by the Save call that fills it up, returning the error of the underlying
+// repository, and by the periodic flush, which only logs the errors. Batches
+// of size one, or smaller, disable the buffering.
+func NewBatcher(repo MessageRepository, size int, interval time.Duration, logger log.Logger) MessageRepository {
+	if size <= 1 {
+		return repo
+	}
+
+	b := &batcher{
+		repo:   repo,
+		size:   size,
+		logger: logger,
+		batch:  make([]mainflux.Message, 0, size),
+	}
+
+	go func() {
+		for range time.Tick(interval) {
+			b.flush()
+		}
+	}()
+
+	return b
+}
+
+func (b *batcher) Save(msgs ...mainflux.Message) error {
+	b.mu.Lock()
+	b.batch = append(b.batch, msgs...)
+	if len(b.batch) < b.size {
+		b.mu.Unlock()
+		return nil
+	}
+	batch := b.swap()
+	b.mu.Unlock()
+
+	return b.repo.Save(batch...)
+}
+
+func (b *batcher) flush() {
+	b.mu.Lock()
+	batch := b.swap()
+	b.mu.Unlock()
+
+	if len(batch) == 0 {
+		return
+	}
+
+	if err := b.repo.Save(batch...); err != nil {
+		b.logger.Warn(fmt.Sprintf("Failed to save batch of %d messages: %s", len(batch), err))
+	}
+}
+
+// swap replaces the buffered batch with the empty one and returns it. It must
+// be called with the lock held.
+func (b *batcher) swap() []mainflux.Message {
+	batch := b.batch
+	b.batch = make([]mainflux.Message, 0, b.size)
+	return batch
+}
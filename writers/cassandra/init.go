@@ -24,6 +24,13 @@ const table = `CREATE TABLE IF NOT EXISTS messages (
         PRIMARY KEY (channel, time, id)
 	) WITH CLUSTERING ORDER BY (time DESC)`
 
+const retentionTable = `CREATE TABLE IF NOT EXISTS retention (
+        channel text,
+        period bigint,
+        messages bigint,
+        PRIMARY KEY (channel)
+	)`
+
 // DBConfig contains Cassandra DB specific parameters.
 type DBConfig struct {
 	Hosts    []string
@@ -49,8 +56,10 @@ func Connect(cfg DBConfig) (*gocql.Session, error) {
 		return nil, err
 	}
 
-	if err := session.Query(table).Exec(); err != nil {
-		return nil, err
+	for _, t := range []string{table, retentionTable} {
+		if err := session.Query(t).Exec(); err != nil {
+			return nil, err
+		}
 	}
 
 	return session, nil
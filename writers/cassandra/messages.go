@@ -9,6 +9,17 @@ import (
 	"github.com/mainflux/mainflux/writers"
 )
 
+const (
+	insertQuery = `INSERT INTO messages (id, channel, subtopic, publisher, protocol,
+		name, unit, value, string_value, bool_value, data_value, value_sum,
+		time, update_time, link)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	// maxBatch limits the number of messages inserted by a single batch,
+	// keeping the batches below the batch size thresholds of Cassandra.
+	maxBatch = 100
+)
+
 var _ writers.MessageRepository = (*cassandraRepository)(nil)
 
 type cassandraRepository struct {
@@ -20,11 +31,45 @@ func New(session *gocql.Session) writers.MessageRepository {
 	return &cassandraRepository{session}
 }
 
-func (cr *cassandraRepository) Save(msg mainflux.Message) error {
-	cql := `INSERT INTO messages (id, channel, subtopic, publisher, protocol,
-			name, unit, value, string_value, bool_value, data_value, value_sum,
-			time, update_time, link)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+// Save inserts the messages using unlogged batches. Messages are grouped by
+// the channel, so that each batch writes to a single partition.
+func (cr *cassandraRepository) Save(msgs ...mainflux.Message) error {
+	if len(msgs) == 1 {
+		return cr.session.Query(insertQuery, values(msgs[0])...).Exec()
+	}
+
+	var channels []string
+	groups := map[string][]mainflux.Message{}
+	for _, msg := range msgs {
+		if _, ok := groups[msg.Channel]; !ok {
+			channels = append(channels, msg.Channel)
+		}
+		groups[msg.Channel] = append(groups[msg.Channel], msg)
+	}
+
+	for _, ch := range channels {
+		group := groups[ch]
+		for len(group) > 0 {
+			n := len(group)
+			if n > maxBatch {
+				n = maxBatch
+			}
+
+			batch := cr.session.NewBatch(gocql.UnloggedBatch)
+			for _, msg := range group[:n] {
+				batch.Query(insertQuery, values(msg)...)
+			}
+			if err := cr.session.ExecuteBatch(batch); err != nil {
+				return err
+			}
+			group = group[n:]
+		}
+	}
+
+	return nil
+}
+
+func values(msg mainflux.Message) []interface{} {
 	id := gocql.TimeUUID()
 
 	var floatVal, valSum *float64
@@ -50,7 +95,7 @@ func (cr *cassandraRepository) Save(msg mainflux.Message) error {
 		valSum = &v
 	}
 
-	return cr.session.Query(cql, id, msg.GetChannel(), msg.GetSubtopic(), msg.GetPublisher(),
+	return []interface{}{id, msg.GetChannel(), msg.GetSubtopic(), msg.GetPublisher(),
 		msg.GetProtocol(), msg.GetName(), msg.GetUnit(), floatVal,
-		strVal, boolVal, dataVal, valSum, msg.GetTime(), msg.GetUpdateTime(), msg.GetLink()).Exec()
+		strVal, boolVal, dataVal, valSum, msg.GetTime(), msg.GetUpdateTime(), msg.GetLink()}
 }
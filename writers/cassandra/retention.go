@@ -0,0 +1,79 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package cassandra
+
+import (
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/mainflux/mainflux/writers"
+)
+
+var _ writers.RetentionRepository = (*retentionRepository)(nil)
+
+type retentionRepository struct {
+	session *gocql.Session
+}
+
+// NewRetentionRepository instantiates Cassandra retention repository.
+func NewRetentionRepository(session *gocql.Session) writers.RetentionRepository {
+	return &retentionRepository{session}
+}
+
+func (rr *retentionRepository) Retain(channel string, retention writers.Retention) error {
+	if retention == (writers.Retention{}) {
+		return rr.session.Query(`DELETE FROM retention WHERE channel = ?`, channel).Exec()
+	}
+
+	cql := `INSERT INTO retention (channel, period, messages) VALUES (?, ?, ?)`
+	return rr.session.Query(cql, channel, int64(retention.Period/time.Second), int64(retention.Messages)).Exec()
+}
+
+func (rr *retentionRepository) Reap(now time.Time) error {
+	iter := rr.session.Query(`SELECT channel, period, messages FROM retention`).Iter()
+
+	var channel string
+	var period, messages int64
+	for iter.Scan(&channel, &period, &messages) {
+		if err := rr.reap(channel, period, messages, now); err != nil {
+			iter.Close()
+			return err
+		}
+	}
+
+	return iter.Close()
+}
+
+// reap relies on the messages of the channel being clustered by time in
+// descending order, so expired messages are removed by a range deletion.
+func (rr *retentionRepository) reap(channel string, period, messages int64, now time.Time) error {
+	del := `DELETE FROM messages WHERE channel = ? AND time < ?`
+
+	if period > 0 {
+		if err := rr.session.Query(del, channel, float64(now.Unix()-period)).Exec(); err != nil {
+			return err
+		}
+	}
+
+	if messages <= 0 {
+		return nil
+	}
+
+	// Messages older than the oldest of the retained messages are removed.
+	// Messages sent at the same time as the oldest retained one are kept.
+	var oldest float64
+	iter := rr.session.Query(`SELECT time FROM messages WHERE channel = ? LIMIT ?`, channel, messages).Iter()
+	n := 0
+	for iter.Scan(&oldest) {
+		n++
+	}
+	if err := iter.Close(); err != nil {
+		return err
+	}
+	if int64(n) < messages {
+		return nil
+	}
+
+	return rr.session.Query(del, channel, oldest).Exec()
+}
@@ -0,0 +1,56 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package writers
+
+import (
+	"fmt"
+
+	"github.com/mainflux/mainflux"
+	log "github.com/mainflux/mainflux/logger"
+)
+
+// DeadLetters keeps the messages that failed to be saved, so that they can
+// be replayed once the message store recovers.
+type DeadLetters interface {
+	// Push stores the messages that failed to be saved.
+	Push(...mainflux.Message) error
+}
+
+// ReplaySubject returns the NATS subject the writer consumes the replayed
+// dead letters from.
+func ReplaySubject(writer string) string {
+	return fmt.Sprintf("%s.replay.%s", mainflux.OutputSenML, writer)
+}
+
+var _ MessageRepository = (*deadLetterRepository)(nil)
+
+type deadLetterRepository struct {
+	repo        MessageRepository
+	deadLetters DeadLetters
+	logger      log.Logger
+}
+
+// NewDeadLetterRepository returns message repository that pushes the
+// messages the provided repository failed to save to the dead letters. The
+// save error is returned regardless of the push outcome.
+func NewDeadLetterRepository(repo MessageRepository, deadLetters DeadLetters, logger log.Logger) MessageRepository {
+	return &deadLetterRepository{
+		repo:        repo,
+		deadLetters: deadLetters,
+		logger:      logger,
+	}
+}
+
+func (dr *deadLetterRepository) Save(msgs ...mainflux.Message) error {
+	err := dr.repo.Save(msgs...)
+	if err == nil {
+		return nil
+	}
+
+	if perr := dr.deadLetters.Push(msgs...); perr != nil {
+		dr.logger.Error(fmt.Sprintf("Failed to push %d messages to dead letters: %s", len(msgs), perr))
+	}
+
+	return err
+}
@@ -0,0 +1,6 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package deadletter contains dead letters implementations that keep the
+// messages the writers failed to save in a NATS subject or a spill file.
+package deadletter
@@ -0,0 +1,103 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package deadletter
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/writers"
+)
+
+// lenSize is the size of the record length prefix in bytes.
+const lenSize = 4
+
+var _ writers.DeadLetters = (*fileDeadLetters)(nil)
+
+type fileDeadLetters struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileDeadLetters returns dead letters that append every message that
+// failed to be saved to the spill file at the given path. Each message is
+// stored as the protobuf record prefixed with its big-endian length. The
+// file is reopened on every push, so it can be safely moved away in order
+// to be replayed.
+func NewFileDeadLetters(path string) writers.DeadLetters {
+	return &fileDeadLetters{path: path}
+}
+
+func (fd *fileDeadLetters) Push(msgs ...mainflux.Message) error {
+	var buf []byte
+	for _, msg := range msgs {
+		data, err := proto.Marshal(&msg)
+		if err != nil {
+			return err
+		}
+
+		var l [lenSize]byte
+		binary.BigEndian.PutUint32(l[:], uint32(len(data)))
+		buf = append(buf, l[:]...)
+		buf = append(buf, data...)
+	}
+
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	f, err := os.OpenFile(fd.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(buf); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// ReadFile reads all the messages stored in the spill file at the given path.
+func ReadFile(path string) ([]mainflux.Message, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Read(f)
+}
+
+// Read reads the messages from the reader in the spill file format.
+func Read(r io.Reader) ([]mainflux.Message, error) {
+	br := bufio.NewReader(r)
+
+	var msgs []mainflux.Message
+	for {
+		var l [lenSize]byte
+		if _, err := io.ReadFull(br, l[:]); err != nil {
+			if err == io.EOF {
+				return msgs, nil
+			}
+			return msgs, err
+		}
+
+		data := make([]byte, binary.BigEndian.Uint32(l[:]))
+		if _, err := io.ReadFull(br, data); err != nil {
+			return msgs, err
+		}
+
+		var msg mainflux.Message
+		if err := proto.Unmarshal(data, &msg); err != nil {
+			return msgs, err
+		}
+		msgs = append(msgs, msg)
+	}
+}
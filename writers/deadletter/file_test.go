@@ -0,0 +1,57 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package deadletter_test
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/writers/deadletter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileDeadLetters(t *testing.T) {
+	dir, err := ioutil.TempDir("", "deadletter")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "spill")
+	dl := deadletter.NewFileDeadLetters(path)
+
+	var msgs []mainflux.Message
+	for i := 0; i < 10; i++ {
+		msgs = append(msgs, mainflux.Message{
+			Channel:   "1",
+			Publisher: "2",
+			Protocol:  "http",
+			Name:      fmt.Sprintf("name-%d", i),
+			Value:     &mainflux.Message_FloatValue{FloatValue: float64(i)},
+			Time:      float64(i),
+		})
+	}
+
+	err = dl.Push(msgs[:3]...)
+	assert.Nil(t, err, fmt.Sprintf("pushing messages expected to succeed: %s", err))
+	err = dl.Push(msgs[3:]...)
+	assert.Nil(t, err, fmt.Sprintf("pushing messages expected to succeed: %s", err))
+
+	read, err := deadletter.ReadFile(path)
+	assert.Nil(t, err, fmt.Sprintf("reading messages expected to succeed: %s", err))
+	assert.Equal(t, msgs, read, fmt.Sprintf("expected %v got %v", msgs, read))
+
+	info, err := os.Stat(path)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = os.Truncate(path, info.Size()-1)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	read, err = deadletter.ReadFile(path)
+	assert.Equal(t, io.ErrUnexpectedEOF, err, fmt.Sprintf("reading truncated file: expected %s got %s", io.ErrUnexpectedEOF, err))
+	assert.Equal(t, msgs[:len(msgs)-1], read, fmt.Sprintf("expected %v got %v", msgs[:len(msgs)-1], read))
+}
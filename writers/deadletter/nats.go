@@ -0,0 +1,42 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package deadletter
+
+import (
+	"github.com/gogo/protobuf/proto"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/writers"
+	nats "github.com/nats-io/nats.go"
+)
+
+var _ writers.DeadLetters = (*natsDeadLetters)(nil)
+
+type natsDeadLetters struct {
+	nc      *nats.Conn
+	subject string
+}
+
+// NewNATSDeadLetters returns dead letters that publish every message that
+// failed to be saved to the given NATS subject.
+func NewNATSDeadLetters(nc *nats.Conn, subject string) writers.DeadLetters {
+	return natsDeadLetters{
+		nc:      nc,
+		subject: subject,
+	}
+}
+
+func (nd natsDeadLetters) Push(msgs ...mainflux.Message) error {
+	for _, msg := range msgs {
+		data, err := proto.Marshal(&msg)
+		if err != nil {
+			return err
+		}
+
+		if err := nd.nc.Publish(nd.subject, data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,6 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package enrich contains the HTTP and gRPC clients of the external services
+// that enrich the messages before they are saved.
+package enrich
@@ -0,0 +1,41 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package enrich
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/mainflux/mainflux/writers"
+	"google.golang.org/grpc"
+)
+
+// ErrUnsupportedScheme indicates that the enricher URL scheme is neither
+// HTTP nor gRPC.
+var ErrUnsupportedScheme = errors.New("unsupported enricher URL scheme")
+
+// New returns the enricher reachable at the given URL. URLs with the http
+// and https schemes are served by the HTTP enricher, while URLs with the
+// grpc scheme are served by the gRPC enricher.
+func New(rawURL string, timeout time.Duration) (writers.Enricher, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	switch u.Scheme {
+	case "http", "https":
+		return NewHTTPEnricher(rawURL, &http.Client{Timeout: timeout}), nil
+	case "grpc":
+		conn, err := grpc.Dial(u.Host, grpc.WithInsecure())
+		if err != nil {
+			return nil, err
+		}
+		return NewGRPCEnricher(conn), nil
+	default:
+		return nil, ErrUnsupportedScheme
+	}
+}
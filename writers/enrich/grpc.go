@@ -0,0 +1,37 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package enrich
+
+import (
+	"context"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/writers"
+	"google.golang.org/grpc"
+)
+
+// enrichMethod is the full name of the Enrich method of the Enricher service
+// described in enricher.proto.
+const enrichMethod = "/mainflux.Enricher/Enrich"
+
+var _ writers.Enricher = (*grpcEnricher)(nil)
+
+type grpcEnricher struct {
+	conn *grpc.ClientConn
+}
+
+// NewGRPCEnricher returns enricher that calls the Enricher gRPC service over
+// the provided connection.
+func NewGRPCEnricher(conn *grpc.ClientConn) writers.Enricher {
+	return grpcEnricher{conn: conn}
+}
+
+func (ge grpcEnricher) Enrich(ctx context.Context, msg mainflux.Message) (mainflux.Message, error) {
+	var res mainflux.Message
+	if err := ge.conn.Invoke(ctx, enrichMethod, &msg, &res); err != nil {
+		return mainflux.Message{}, err
+	}
+
+	return res, nil
+}
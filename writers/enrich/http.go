@@ -0,0 +1,160 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package enrich
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/writers"
+)
+
+const contentType = "application/json"
+
+// ErrFailedEnrichment indicates that the enricher responded with the
+// unexpected status code.
+var ErrFailedEnrichment = errors.New("failed to enrich message")
+
+var _ writers.Enricher = (*httpEnricher)(nil)
+
+type httpEnricher struct {
+	url    string
+	client *http.Client
+}
+
+// message is the JSON representation of the Mainflux message exchanged with
+// the HTTP enricher.
+type message struct {
+	Channel     string   `json:"channel,omitempty"`
+	Subtopic    string   `json:"subtopic,omitempty"`
+	Publisher   string   `json:"publisher,omitempty"`
+	Protocol    string   `json:"protocol,omitempty"`
+	Name        string   `json:"name,omitempty"`
+	Unit        string   `json:"unit,omitempty"`
+	FloatValue  *float64 `json:"floatValue,omitempty"`
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	DataValue   *string  `json:"dataValue,omitempty"`
+	ValueSum    *float64 `json:"valueSum,omitempty"`
+	Time        float64  `json:"time,omitempty"`
+	UpdateTime  float64  `json:"updateTime,omitempty"`
+	Link        string   `json:"link,omitempty"`
+	Sequence    uint64   `json:"sequence,omitempty"`
+}
+
+// NewHTTPEnricher returns enricher that posts the messages in JSON format to
+// the given URL. The enricher responds either with 200 OK and the enriched
+// message in the body, or with 204 No Content if the message is left
+// unchanged.
+func NewHTTPEnricher(url string, client *http.Client) writers.Enricher {
+	return httpEnricher{
+		url:    url,
+		client: client,
+	}
+}
+
+func (he httpEnricher) Enrich(ctx context.Context, msg mainflux.Message) (mainflux.Message, error) {
+	data, err := json.Marshal(toJSON(msg))
+	if err != nil {
+		return mainflux.Message{}, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, he.url, bytes.NewReader(data))
+	if err != nil {
+		return mainflux.Message{}, err
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	res, err := he.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return mainflux.Message{}, err
+	}
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusOK:
+		var m message
+		if err := json.NewDecoder(res.Body).Decode(&m); err != nil {
+			return mainflux.Message{}, err
+		}
+		return fromJSON(m), nil
+	case http.StatusNoContent:
+		return msg, nil
+	default:
+		return mainflux.Message{}, ErrFailedEnrichment
+	}
+}
+
+func toJSON(msg mainflux.Message) message {
+	m := message{
+		Channel:    msg.Channel,
+		Subtopic:   msg.Subtopic,
+		Publisher:  msg.Publisher,
+		Protocol:   msg.Protocol,
+		Name:       msg.Name,
+		Unit:       msg.Unit,
+		Time:       msg.Time,
+		UpdateTime: msg.UpdateTime,
+		Link:       msg.Link,
+		Sequence:   msg.Sequence,
+	}
+
+	switch msg.Value.(type) {
+	case *mainflux.Message_FloatValue:
+		v := msg.GetFloatValue()
+		m.FloatValue = &v
+	case *mainflux.Message_StringValue:
+		v := msg.GetStringValue()
+		m.StringValue = &v
+	case *mainflux.Message_DataValue:
+		v := msg.GetDataValue()
+		m.DataValue = &v
+	case *mainflux.Message_BoolValue:
+		v := msg.GetBoolValue()
+		m.BoolValue = &v
+	}
+
+	if msg.GetValueSum() != nil {
+		v := msg.GetValueSum().GetValue()
+		m.ValueSum = &v
+	}
+
+	return m
+}
+
+func fromJSON(m message) mainflux.Message {
+	msg := mainflux.Message{
+		Channel:    m.Channel,
+		Subtopic:   m.Subtopic,
+		Publisher:  m.Publisher,
+		Protocol:   m.Protocol,
+		Name:       m.Name,
+		Unit:       m.Unit,
+		Time:       m.Time,
+		UpdateTime: m.UpdateTime,
+		Link:       m.Link,
+		Sequence:   m.Sequence,
+	}
+
+	switch {
+	case m.FloatValue != nil:
+		msg.Value = &mainflux.Message_FloatValue{FloatValue: *m.FloatValue}
+	case m.StringValue != nil:
+		msg.Value = &mainflux.Message_StringValue{StringValue: *m.StringValue}
+	case m.DataValue != nil:
+		msg.Value = &mainflux.Message_DataValue{DataValue: *m.DataValue}
+	case m.BoolValue != nil:
+		msg.Value = &mainflux.Message_BoolValue{BoolValue: *m.BoolValue}
+	}
+
+	if m.ValueSum != nil {
+		msg.ValueSum = &mainflux.SumValue{Value: *m.ValueSum}
+	}
+
+	return msg
+}
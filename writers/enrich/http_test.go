@@ -0,0 +1,96 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package enrich_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/writers/enrich"
+	"github.com/stretchr/testify/assert"
+)
+
+const assetName = "pump-1"
+
+func enricherHandler(w http.ResponseWriter, r *http.Request) {
+	var m map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&m); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	switch m["channel"] {
+	case "unchanged":
+		w.WriteHeader(http.StatusNoContent)
+	case "failing":
+		w.WriteHeader(http.StatusInternalServerError)
+	default:
+		m["name"] = assetName
+		json.NewEncoder(w).Encode(m)
+	}
+}
+
+func TestHTTPEnrich(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(enricherHandler))
+	defer ts.Close()
+
+	enricher := enrich.NewHTTPEnricher(ts.URL, http.DefaultClient)
+
+	msg := mainflux.Message{
+		Channel:   "1",
+		Publisher: "2",
+		Protocol:  "http",
+		Name:      "temperature",
+		Unit:      "C",
+		Value:     &mainflux.Message_FloatValue{FloatValue: 24},
+		ValueSum:  &mainflux.SumValue{Value: 24},
+		Time:      13451312,
+	}
+
+	enriched := msg
+	enriched.Name = assetName
+
+	unchanged := msg
+	unchanged.Channel = "unchanged"
+
+	failing := msg
+	failing.Channel = "failing"
+
+	cases := []struct {
+		desc string
+		msg  mainflux.Message
+		res  mainflux.Message
+		err  error
+	}{
+		{
+			desc: "enrich message",
+			msg:  msg,
+			res:  enriched,
+			err:  nil,
+		},
+		{
+			desc: "enrich message left unchanged",
+			msg:  unchanged,
+			res:  unchanged,
+			err:  nil,
+		},
+		{
+			desc: "enrich message with failing enricher",
+			msg:  failing,
+			res:  mainflux.Message{},
+			err:  enrich.ErrFailedEnrichment,
+		},
+	}
+
+	for _, tc := range cases {
+		res, err := enricher.Enrich(context.Background(), tc.msg)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.res, res, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.res, res))
+	}
+}
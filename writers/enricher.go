@@ -0,0 +1,69 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package writers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	log "github.com/mainflux/mainflux/logger"
+)
+
+// Enricher augments the messages with the external context, such as asset
+// names or geofences, before they are saved.
+type Enricher interface {
+	// Enrich returns the message augmented with the external context.
+	Enrich(context.Context, mainflux.Message) (mainflux.Message, error)
+}
+
+var _ MessageRepository = (*enrichingRepository)(nil)
+
+type enrichingRepository struct {
+	repo     MessageRepository
+	enricher Enricher
+	timeout  time.Duration
+	bypass   bool
+	logger   log.Logger
+}
+
+// NewEnrichingRepository returns message repository that passes every saved
+// message through the enricher before passing it on to the provided
+// repository. Each message is enriched within the timeout. If bypass is set,
+// messages that failed to be enriched are saved unchanged, otherwise the
+// save fails with the enrichment error.
+func NewEnrichingRepository(repo MessageRepository, enricher Enricher, timeout time.Duration, bypass bool, logger log.Logger) MessageRepository {
+	return &enrichingRepository{
+		repo:     repo,
+		enricher: enricher,
+		timeout:  timeout,
+		bypass:   bypass,
+		logger:   logger,
+	}
+}
+
+func (er *enrichingRepository) Save(msgs ...mainflux.Message) error {
+	enriched := make([]mainflux.Message, 0, len(msgs))
+	for _, msg := range msgs {
+		m, err := er.enrich(msg)
+		if err != nil {
+			if !er.bypass {
+				return err
+			}
+			er.logger.Warn(fmt.Sprintf("Failed to enrich message, saving it unchanged: %s", err))
+			m = msg
+		}
+		enriched = append(enriched, m)
+	}
+
+	return er.repo.Save(enriched...)
+}
+
+func (er *enrichingRepository) enrich(msg mainflux.Message) (mainflux.Message, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), er.timeout)
+	defer cancel()
+
+	return er.enricher.Enrich(ctx, msg)
+}
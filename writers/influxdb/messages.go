@@ -107,18 +107,24 @@ func (repo *influxRepo) savePoint(point *influxdata.Point) error {
 	return nil
 }
 
-func (repo *influxRepo) Save(msg mainflux.Message) error {
-	tgs, flds := repo.tagsOf(&msg), repo.fieldsOf(&msg)
+func (repo *influxRepo) Save(msgs ...mainflux.Message) error {
+	for _, msg := range msgs {
+		tgs, flds := repo.tagsOf(&msg), repo.fieldsOf(&msg)
 
-	sec, dec := math.Modf(msg.Time)
-	t := time.Unix(int64(sec), int64(dec*(1e9)))
+		sec, dec := math.Modf(msg.Time)
+		t := time.Unix(int64(sec), int64(dec*(1e9)))
 
-	pt, err := influxdata.NewPoint(pointName, tgs, flds, t)
-	if err != nil {
-		return err
+		pt, err := influxdata.NewPoint(pointName, tgs, flds, t)
+		if err != nil {
+			return err
+		}
+
+		if err := repo.savePoint(pt); err != nil {
+			return err
+		}
 	}
 
-	return repo.savePoint(pt)
+	return nil
 }
 
 func (repo *influxRepo) tagsOf(msg *mainflux.Message) tags {
@@ -154,5 +160,9 @@ func (repo *influxRepo) fieldsOf(msg *mainflux.Message) fields {
 		ret["valueSum"] = msg.GetValueSum().GetValue()
 	}
 
+	if msg.Sequence > 0 {
+		ret["sequence"] = int64(msg.Sequence)
+	}
+
 	return ret
 }
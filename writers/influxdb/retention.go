@@ -0,0 +1,158 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package influxdb
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	influxdata "github.com/influxdata/influxdb/client/v2"
+	"github.com/mainflux/mainflux/writers"
+)
+
+const retentionPointName = "retention"
+
+var _ writers.RetentionRepository = (*retentionRepo)(nil)
+
+type retentionRepo struct {
+	client   influxdata.Client
+	database string
+}
+
+// NewRetentionRepository returns new InfluxDB retention repository.
+func NewRetentionRepository(client influxdata.Client, database string) writers.RetentionRepository {
+	return &retentionRepo{
+		client:   client,
+		database: database,
+	}
+}
+
+func (repo *retentionRepo) Retain(channel string, retention writers.Retention) error {
+	if retention == (writers.Retention{}) {
+		return repo.exec(fmt.Sprintf("DROP SERIES FROM %s WHERE channel = %s", retentionPointName, quote(channel)))
+	}
+
+	bp, err := influxdata.NewBatchPoints(influxdata.BatchPointsConfig{Database: repo.database})
+	if err != nil {
+		return err
+	}
+
+	// Retention of the channel is a single point at the fixed time, so
+	// recording the retention again overwrites it.
+	flds := fields{
+		"period":   int64(retention.Period / time.Second),
+		"messages": int64(retention.Messages),
+	}
+	pt, err := influxdata.NewPoint(retentionPointName, tags{"channel": channel}, flds, time.Unix(0, 0))
+	if err != nil {
+		return err
+	}
+	bp.AddPoint(pt)
+
+	return repo.client.Write(bp)
+}
+
+func (repo *retentionRepo) Reap(now time.Time) error {
+	rows, err := repo.query(fmt.Sprintf("SELECT channel, period, messages FROM %s", retentionPointName))
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		if len(row) < 4 {
+			continue
+		}
+		channel, _ := row[1].(string)
+		period, err := toInt(row[2])
+		if err != nil {
+			return err
+		}
+		messages, err := toInt(row[3])
+		if err != nil {
+			return err
+		}
+
+		if err := repo.reap(channel, period, messages, now); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (repo *retentionRepo) reap(channel string, period, messages int64, now time.Time) error {
+	del := "DELETE FROM %s WHERE channel = %s AND time < %d"
+
+	if period > 0 {
+		cutoff := now.Add(-time.Duration(period) * time.Second).UnixNano()
+		if err := repo.exec(fmt.Sprintf(del, pointName, quote(channel), cutoff)); err != nil {
+			return err
+		}
+	}
+
+	if messages <= 0 {
+		return nil
+	}
+
+	// Messages older than the oldest of the retained messages are removed.
+	// Messages sent at the same time as the oldest retained one are kept.
+	q := fmt.Sprintf("SELECT * FROM %s WHERE channel = %s ORDER BY time DESC LIMIT 1 OFFSET %d", pointName, quote(channel), messages-1)
+	rows, err := repo.query(q)
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	oldest, err := toInt(rows[0][0])
+	if err != nil {
+		return err
+	}
+
+	return repo.exec(fmt.Sprintf(del, pointName, quote(channel), oldest))
+}
+
+func (repo *retentionRepo) query(cmd string) ([][]interface{}, error) {
+	q := influxdata.Query{
+		Command:   cmd,
+		Database:  repo.database,
+		Precision: "ns",
+	}
+
+	resp, err := repo.client.Query(q)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Error() != nil {
+		return nil, resp.Error()
+	}
+	if len(resp.Results) == 0 || len(resp.Results[0].Series) == 0 {
+		return nil, nil
+	}
+
+	return resp.Results[0].Series[0].Values, nil
+}
+
+func (repo *retentionRepo) exec(cmd string) error {
+	_, err := repo.query(cmd)
+	return err
+}
+
+func toInt(v interface{}) (int64, error) {
+	switch n := v.(type) {
+	case json.Number:
+		return n.Int64()
+	case nil:
+		return 0, nil
+	default:
+		return 0, fmt.Errorf("unexpected value %v", v)
+	}
+}
+
+func quote(s string) string {
+	return fmt.Sprintf("'%s'", strings.Replace(strings.Replace(s, `\`, `\\`, -1), "'", `\'`, -1))
+}
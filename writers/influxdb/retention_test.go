@@ -0,0 +1,87 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package influxdb_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/writers"
+	writer "github.com/mainflux/mainflux/writers/influxdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReap(t *testing.T) {
+	// Set batch size to 1 to save messages immediately.
+	messageRepo, err := writer.New(client, testDB, 1, saveTimeout)
+	require.Nil(t, err, fmt.Sprintf("Creating new InfluxDB repo expected to succeed: %s.\n", err))
+	retentionRepo := writer.NewRetentionRepository(client, testDB)
+
+	now := time.Now().Truncate(time.Second)
+
+	cases := []struct {
+		desc      string
+		channel   string
+		retention writers.Retention
+		count     int
+	}{
+		{
+			desc:      "reap messages of channel with retention period",
+			channel:   "retention-period",
+			retention: writers.Retention{Period: time.Hour},
+			count:     3,
+		},
+		{
+			desc:      "reap messages of channel with retention message count",
+			channel:   "retention-messages",
+			retention: writers.Retention{Messages: 2},
+			count:     2,
+		},
+		{
+			desc:      "reap messages of channel with retention period and message count",
+			channel:   "retention-both",
+			retention: writers.Retention{Period: time.Hour, Messages: 1},
+			count:     1,
+		},
+		{
+			desc:      "reap messages of channel without retention",
+			channel:   "retention-none",
+			retention: writers.Retention{},
+			count:     5,
+		},
+	}
+
+	for _, tc := range cases {
+		// Messages are sent every half an hour, the newest one now.
+		for i := 0; i < 5; i++ {
+			msg := mainflux.Message{
+				Channel:   tc.channel,
+				Publisher: "1",
+				Name:      "temperature",
+				Value:     &mainflux.Message_FloatValue{FloatValue: float64(i)},
+				Time:      float64(now.Add(-time.Duration(i) * 30 * time.Minute).Unix()),
+			}
+			err := messageRepo.Save(msg)
+			require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+		}
+
+		err := retentionRepo.Retain(tc.channel, tc.retention)
+		require.Nil(t, err, fmt.Sprintf("%s: got unexpected error: %s", tc.desc, err))
+	}
+
+	err = retentionRepo.Reap(now)
+	assert.Nil(t, err, fmt.Sprintf("reap messages: expected no error got %s\n", err))
+
+	for _, tc := range cases {
+		row, err := queryDB(fmt.Sprintf("SELECT * FROM test..messages WHERE channel = '%s'", tc.channel))
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+		assert.Equal(t, tc.count, len(row), fmt.Sprintf("%s: expected %d messages got %d\n", tc.desc, tc.count, len(row)))
+	}
+
+	_, err = queryDB(dropMsgs)
+	require.Nil(t, err, fmt.Sprintf("Cleaning data from InfluxDB expected to succeed: %s.\n", err))
+}
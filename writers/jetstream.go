@@ -0,0 +1,132 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package writers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/gogo/protobuf/proto"
+	"github.com/mainflux/mainflux"
+	log "github.com/mainflux/mainflux/logger"
+	mfnats "github.com/mainflux/mainflux/nats"
+	nats "github.com/nats-io/nats.go"
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+// JetStreamConfig represents the configuration of the JetStream stream the
+// normalized messages are persisted to, and of the durable consumer of the
+// writer.
+type JetStreamConfig struct {
+	// Stream contains the name of the stream, which is created unless it
+	// already exists.
+	Stream string
+
+	// MaxAge contains the time the messages are kept in the stream for.
+	MaxAge time.Duration
+
+	// AckWait contains the time the message is redelivered after, unless
+	// the writer acknowledged it.
+	AckWait time.Duration
+
+	// MaxDeliver contains the number of the delivery attempts of the
+	// message. Non-positive value indicates unlimited attempts.
+	MaxDeliver int
+}
+
+type jsConsumer struct {
+	consumer
+	lag metrics.Gauge
+}
+
+// StartJetStream starts to consume normalized messages persisted to the
+// JetStream stream, using the durable consumer named after the queue, so
+// that the messages published while the writer was down are delivered once
+// it is up again. Message is acknowledged once it is saved, and redelivered
+// if saving failed. The dead letters replayed to the queue subject are
+// consumed directly from NATS, as in Start. The number of the messages the
+// consumer didn't receive yet is reported by the lag gauge.
+func StartJetStream(nc *nats.Conn, cfg JetStreamConfig, subjectPrefix string, repo MessageRepository, retainer Retainer, queue string, channels map[string]bool, lag metrics.Gauge, tracer opentracing.Tracer, logger log.Logger) error {
+	c := jsConsumer{
+		consumer: consumer{
+			nc:       nc,
+			channels: channels,
+			repo:     repo,
+			retainer: retainer,
+			tracer:   tracer,
+			logger:   logger,
+		},
+		lag: lag,
+	}
+
+	js, err := nc.JetStream()
+	if err != nil {
+		return err
+	}
+
+	subject := mfnats.Subject(subjectPrefix, mainflux.OutputSenML)
+	if err := createStream(js, cfg, subject); err != nil {
+		return err
+	}
+
+	opts := []nats.SubOpt{
+		nats.Durable(queue),
+		nats.BindStream(cfg.Stream),
+		nats.DeliverAll(),
+		nats.ManualAck(),
+		nats.AckWait(cfg.AckWait),
+	}
+	if cfg.MaxDeliver > 0 {
+		opts = append(opts, nats.MaxDeliver(cfg.MaxDeliver))
+	}
+
+	if _, err := js.QueueSubscribe(subject, queue, c.consume, opts...); err != nil {
+		return err
+	}
+
+	_, err = nc.QueueSubscribe(mfnats.Subject(subjectPrefix, ReplaySubject(queue)), queue, c.consumer.consume)
+	return err
+}
+
+// createStream creates the stream of the messages published to the subject,
+// unless it already exists.
+func createStream(js nats.JetStreamContext, cfg JetStreamConfig, subject string) error {
+	if _, err := js.StreamInfo(cfg.Stream); err == nil {
+		return nil
+	}
+
+	_, err := js.AddStream(&nats.StreamConfig{
+		Name:     cfg.Stream,
+		Subjects: []string{subject},
+		MaxAge:   cfg.MaxAge,
+		Storage:  nats.FileStorage,
+	})
+
+	return err
+}
+
+func (c *jsConsumer) consume(m *nats.Msg) {
+	if meta, err := m.Metadata(); err == nil {
+		c.lag.Set(float64(meta.NumPending))
+	}
+
+	msg := &mainflux.Message{}
+	if err := proto.Unmarshal(m.Data, msg); err != nil {
+		c.logger.Warn(fmt.Sprintf("Failed to unmarshal received message: %s", err))
+		// Malformed message can't be saved, so it isn't redelivered.
+		m.Term()
+		return
+	}
+
+	if err := c.trace(m, *msg); err != nil {
+		c.logger.Warn(fmt.Sprintf("Failed to save message: %s", err))
+		m.Nak()
+		return
+	}
+
+	if err := m.Ack(); err != nil {
+		c.logger.Warn(fmt.Sprintf("Failed to acknowledge message: %s", err))
+	}
+}
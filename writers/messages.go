@@ -8,7 +8,7 @@ import "github.com/mainflux/mainflux"
 // MessageRepository specifies message writing API.
 type MessageRepository interface {
 
-	// Save method is used to save published message. A non-nil
+	// Save method is used to save published messages. A non-nil
 	// error is returned to indicate  operation failure.
-	Save(mainflux.Message) error
+	Save(...mainflux.Message) error
 }
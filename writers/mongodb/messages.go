@@ -36,6 +36,7 @@ type message struct {
 	Time        float64  `bson:"time,omitempty"`
 	UpdateTime  float64  `bson:"updateTime,omitempty"`
 	Link        string   `bson:"link,omitempty"`
+	Sequence    uint64   `bson:"sequence,omitempty"`
 }
 
 // New returns new MongoDB writer.
@@ -43,8 +44,22 @@ func New(db *mongo.Database) writers.MessageRepository {
 	return &mongoRepo{db}
 }
 
-func (repo *mongoRepo) Save(msg mainflux.Message) error {
+func (repo *mongoRepo) Save(msgs ...mainflux.Message) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	docs := make([]interface{}, 0, len(msgs))
+	for _, msg := range msgs {
+		docs = append(docs, toMessage(msg))
+	}
+
 	coll := repo.db.Collection(collectionName)
+	_, err := coll.InsertMany(context.Background(), docs)
+	return err
+}
+
+func toMessage(msg mainflux.Message) message {
 	m := message{
 		Channel:    msg.Channel,
 		Subtopic:   msg.Subtopic,
@@ -55,6 +70,7 @@ func (repo *mongoRepo) Save(msg mainflux.Message) error {
 		Time:       msg.Time,
 		UpdateTime: msg.UpdateTime,
 		Link:       msg.Link,
+		Sequence:   msg.Sequence,
 	}
 
 	switch msg.Value.(type) {
@@ -77,6 +93,5 @@ func (repo *mongoRepo) Save(msg mainflux.Message) error {
 		m.ValueSum = &valueSum
 	}
 
-	_, err := coll.InsertOne(context.Background(), m)
-	return err
+	return m
 }
@@ -0,0 +1,111 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/mainflux/mainflux/writers"
+)
+
+const retentionCollection = "retention"
+
+var _ writers.RetentionRepository = (*retentionRepo)(nil)
+
+type retentionRepo struct {
+	db *mongo.Database
+}
+
+type retention struct {
+	Channel  string `bson:"_id"`
+	Period   int64  `bson:"period"`
+	Messages int64  `bson:"messages"`
+}
+
+// NewRetentionRepository returns new MongoDB retention repository.
+func NewRetentionRepository(db *mongo.Database) writers.RetentionRepository {
+	return &retentionRepo{db}
+}
+
+func (repo *retentionRepo) Retain(channel string, r writers.Retention) error {
+	coll := repo.db.Collection(retentionCollection)
+	filter := bson.M{"_id": channel}
+
+	if r == (writers.Retention{}) {
+		_, err := coll.DeleteOne(context.Background(), filter)
+		return err
+	}
+
+	doc := retention{
+		Channel:  channel,
+		Period:   int64(r.Period / time.Second),
+		Messages: int64(r.Messages),
+	}
+	_, err := coll.ReplaceOne(context.Background(), filter, doc, options.Replace().SetUpsert(true))
+	return err
+}
+
+func (repo *retentionRepo) Reap(now time.Time) error {
+	ctx := context.Background()
+	cursor, err := repo.db.Collection(retentionCollection).Find(ctx, bson.M{})
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	var rs []retention
+	for cursor.Next(ctx) {
+		var r retention
+		if err := cursor.Decode(&r); err != nil {
+			return err
+		}
+		rs = append(rs, r)
+	}
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+
+	for _, r := range rs {
+		if err := repo.reap(ctx, r, now); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (repo *retentionRepo) reap(ctx context.Context, r retention, now time.Time) error {
+	coll := repo.db.Collection(collectionName)
+
+	if r.Period > 0 {
+		filter := bson.M{"channel": r.Channel, "time": bson.M{"$lt": float64(now.Unix() - r.Period)}}
+		if _, err := coll.DeleteMany(ctx, filter); err != nil {
+			return err
+		}
+	}
+
+	if r.Messages <= 0 {
+		return nil
+	}
+
+	// Messages older than the oldest of the retained messages are removed.
+	// Messages sent at the same time as the oldest retained one are kept.
+	opts := options.FindOne().SetSort(bson.M{"time": -1}).SetSkip(r.Messages - 1)
+	var oldest message
+	if err := coll.FindOne(ctx, bson.M{"channel": r.Channel}, opts).Decode(&oldest); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil
+		}
+		return err
+	}
+
+	filter := bson.M{"channel": r.Channel, "time": bson.M{"$lt": oldest.Time}}
+	_, err := coll.DeleteMany(ctx, filter)
+	return err
+}
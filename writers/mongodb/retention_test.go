@@ -0,0 +1,90 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mongodb_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/writers"
+	"github.com/mainflux/mainflux/writers/mongodb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestReap(t *testing.T) {
+	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI(addr))
+	require.Nil(t, err, fmt.Sprintf("Creating new MongoDB client expected to succeed: %s.\n", err))
+
+	db := client.Database(testDB)
+	messageRepo := mongodb.New(db)
+	retentionRepo := mongodb.NewRetentionRepository(db)
+
+	now := time.Now().Truncate(time.Second)
+
+	cases := []struct {
+		desc      string
+		channel   string
+		retention writers.Retention
+		count     int64
+	}{
+		{
+			desc:      "reap messages of channel with retention period",
+			channel:   "retention-period",
+			retention: writers.Retention{Period: time.Hour},
+			count:     3,
+		},
+		{
+			desc:      "reap messages of channel with retention message count",
+			channel:   "retention-messages",
+			retention: writers.Retention{Messages: 2},
+			count:     2,
+		},
+		{
+			desc:      "reap messages of channel with retention period and message count",
+			channel:   "retention-both",
+			retention: writers.Retention{Period: time.Hour, Messages: 1},
+			count:     1,
+		},
+		{
+			desc:      "reap messages of channel without retention",
+			channel:   "retention-none",
+			retention: writers.Retention{},
+			count:     5,
+		},
+	}
+
+	for _, tc := range cases {
+		// Messages are sent every half an hour, the newest one now.
+		for i := 0; i < 5; i++ {
+			msg := mainflux.Message{
+				Channel:   tc.channel,
+				Publisher: "1",
+				Name:      "temperature",
+				Value:     &mainflux.Message_FloatValue{FloatValue: float64(i)},
+				Time:      float64(now.Add(-time.Duration(i) * 30 * time.Minute).Unix()),
+			}
+			err := messageRepo.Save(msg)
+			require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+		}
+
+		err := retentionRepo.Retain(tc.channel, tc.retention)
+		require.Nil(t, err, fmt.Sprintf("%s: got unexpected error: %s", tc.desc, err))
+	}
+
+	err = retentionRepo.Reap(now)
+	assert.Nil(t, err, fmt.Sprintf("reap messages: expected no error got %s\n", err))
+
+	for _, tc := range cases {
+		count, err := db.Collection(collection).CountDocuments(context.Background(), bson.M{"channel": tc.channel})
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+		assert.Equal(t, tc.count, count, fmt.Sprintf("%s: expected %d messages got %d\n", tc.desc, tc.count, count))
+	}
+}
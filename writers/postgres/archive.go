@@ -0,0 +1,76 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"compress/gzip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/mainflux/mainflux"
+)
+
+var _ Archive = (*fileArchive)(nil)
+
+type fileArchive struct {
+	dir string
+}
+
+// NewFileArchive returns archive that stores every hour of messages in a
+// separate gzipped file inside the given directory. Messages are encoded as
+// a stream of length-delimited protocol buffers.
+func NewFileArchive(dir string) Archive {
+	return &fileArchive{dir: dir}
+}
+
+func (fa fileArchive) Save(start time.Time, msgs []mainflux.Message) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	if err := os.MkdirAll(fa.dir, 0755); err != nil {
+		return err
+	}
+
+	buf := proto.NewBuffer(nil)
+	for i := range msgs {
+		data, err := proto.Marshal(&msgs[i])
+		if err != nil {
+			return err
+		}
+		if err := buf.EncodeRawBytes(data); err != nil {
+			return err
+		}
+	}
+
+	// Messages of the same hour may be compacted more than once if they
+	// arrive late, so the archive files are never overwritten.
+	name := fmt.Sprintf("messages-%d-%d.pb.gz", start.Unix(), time.Now().UnixNano())
+	f, err := os.OpenFile(filepath.Join(fa.dir, name), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	zw := gzip.NewWriter(f)
+	if _, err := zw.Write(buf.Bytes()); err != nil {
+		f.Close()
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		f.Close()
+		return err
+	}
+
+	// Raw messages are removed from the database once archived, so make
+	// sure they reached the disk.
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
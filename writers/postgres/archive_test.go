@@ -0,0 +1,62 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres_test
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/writers/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileArchiveSave(t *testing.T) {
+	dir, err := ioutil.TempDir("", "archive")
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	defer os.RemoveAll(dir)
+
+	archive := postgres.NewFileArchive(dir)
+	start := time.Unix(3600, 0)
+
+	msgs := []mainflux.Message{
+		{Channel: "1", Name: "temperature", Value: &mainflux.Message_FloatValue{FloatValue: 5}, Time: 3601},
+		{Channel: "1", Name: "status", Value: &mainflux.Message_StringValue{StringValue: "on"}, Time: 3602},
+	}
+
+	err = archive.Save(start, []mainflux.Message{})
+	assert.Nil(t, err, fmt.Sprintf("save empty archive: expected no error got %s\n", err))
+	files, err := filepath.Glob(filepath.Join(dir, "*"))
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.Equal(t, 0, len(files), fmt.Sprintf("save empty archive: expected %d files got %d\n", 0, len(files)))
+
+	err = archive.Save(start, msgs)
+	assert.Nil(t, err, fmt.Sprintf("save archive: expected no error got %s\n", err))
+	files, err = filepath.Glob(filepath.Join(dir, "messages-3600-*.pb.gz"))
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	require.Equal(t, 1, len(files), fmt.Sprintf("save archive: expected %d files got %d\n", 1, len(files)))
+
+	f, err := os.Open(files[0])
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	data, err := ioutil.ReadAll(zr)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	buf := proto.NewBuffer(data)
+	for _, expected := range msgs {
+		var msg mainflux.Message
+		err := buf.DecodeMessage(&msg)
+		assert.Nil(t, err, fmt.Sprintf("decode archived message: expected no error got %s\n", err))
+		assert.True(t, proto.Equal(&expected, &msg), fmt.Sprintf("decode archived message: expected %v got %v\n", expected, msg))
+	}
+}
@@ -71,6 +71,69 @@ func migrateDB(db *sqlx.DB) error {
 					"DROP TABLE messages",
 				},
 			},
+			{
+				Id: "messages_2",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS rollups (
+            channel       UUID,
+            subtopic      VARCHAR(254),
+            publisher     UUID,
+            protocol      TEXT,
+            name          TEXT,
+            unit          TEXT,
+            hour          FLOAT,
+            count         BIGINT,
+            sum           FLOAT,
+            min           FLOAT,
+            max           FLOAT,
+            PRIMARY KEY (channel, subtopic, publisher, protocol, name, unit, hour)
+					)`,
+					`CREATE INDEX IF NOT EXISTS messages_time_idx ON messages (time)`,
+				},
+				Down: []string{
+					"DROP INDEX messages_time_idx",
+					"DROP TABLE rollups",
+				},
+			},
+			{
+				Id: "messages_3",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS retention (
+            channel       UUID,
+            period        BIGINT,
+            messages      BIGINT,
+            PRIMARY KEY (channel)
+					)`,
+					`CREATE INDEX IF NOT EXISTS messages_channel_time_idx ON messages (channel, time)`,
+				},
+				Down: []string{
+					"DROP INDEX messages_channel_time_idx",
+					"DROP TABLE retention",
+				},
+			},
+			{
+				Id: "messages_4",
+				Up: []string{
+					`ALTER TABLE IF EXISTS messages ADD COLUMN IF NOT EXISTS sequence BIGINT NOT NULL DEFAULT 0`,
+				},
+				Down: []string{
+					"ALTER TABLE IF EXISTS messages DROP COLUMN IF EXISTS sequence",
+				},
+			},
+			{
+				Id: "messages_5",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS subtopics (
+            channel       UUID,
+            subtopic      VARCHAR(254),
+            last_seen     FLOAT,
+            PRIMARY KEY (channel, subtopic)
+					)`,
+				},
+				Down: []string{
+					"DROP TABLE subtopics",
+				},
+			},
 		},
 	}
 
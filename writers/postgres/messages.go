@@ -5,6 +5,8 @@ package postgres
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/gofrs/uuid"
 
@@ -14,7 +16,15 @@ import (
 	"github.com/mainflux/mainflux/writers"
 )
 
-const errInvalid = "invalid_text_representation"
+const (
+	errInvalid = "invalid_text_representation"
+
+	// columns is the number of the inserted message columns.
+	columns = 16
+	// maxRows keeps the parameters of the insert query within the limit of
+	// 65535 parameters imposed by Postgres.
+	maxRows = 4000
+)
 
 // ErrInvalidMessage indicates that service received message that
 // doesn't fit required format.
@@ -31,20 +41,74 @@ func New(db *sqlx.DB) writers.MessageRepository {
 	return &postgresRepo{db: db}
 }
 
-func (pr postgresRepo) Save(msg mainflux.Message) error {
+func (pr postgresRepo) Save(msgs ...mainflux.Message) error {
+	for len(msgs) > 0 {
+		n := len(msgs)
+		if n > maxRows {
+			n = maxRows
+		}
+
+		if err := pr.saveBatch(msgs[:n]); err != nil {
+			return err
+		}
+		msgs = msgs[n:]
+	}
+
+	return nil
+}
+
+// saveBatch inserts the messages using a single multi-row insert. Since the
+// invalid message fails the whole insert, the messages of the rejected batch
+// are inserted one by one, so that the valid ones are saved.
+func (pr postgresRepo) saveBatch(msgs []mainflux.Message) error {
+	err := pr.insert(msgs)
+	if err != ErrInvalidMessage || len(msgs) == 1 {
+		return err
+	}
+
+	var invalid bool
+	for _, msg := range msgs {
+		switch err := pr.insert([]mainflux.Message{msg}); err {
+		case nil:
+		case ErrInvalidMessage:
+			invalid = true
+		default:
+			return err
+		}
+	}
+
+	if invalid {
+		return ErrInvalidMessage
+	}
+
+	return nil
+}
+
+func (pr postgresRepo) insert(msgs []mainflux.Message) error {
 	q := `INSERT INTO messages (id, channel, subtopic, publisher, protocol,
     name, unit, value, string_value, bool_value, data_value, value_sum,
-    time, update_time, link)
-    VALUES (:id, :channel, :subtopic, :publisher, :protocol, :name, :unit,
-    :value, :string_value, :bool_value, :data_value, :value_sum,
-    :time, :update_time, :link);`
+    time, update_time, link, sequence)
+    VALUES %s;`
+
+	rows := make([]string, 0, len(msgs))
+	args := make([]interface{}, 0, len(msgs)*columns)
+	params := make([]string, columns)
+	for i, msg := range msgs {
+		dbm, err := toDBMessage(msg)
+		if err != nil {
+			return err
+		}
 
-	dbth, err := toDBMessage(msg)
-	if err != nil {
-		return err
+		for j := range params {
+			params[j] = fmt.Sprintf("$%d", i*columns+j+1)
+		}
+		rows = append(rows, fmt.Sprintf("(%s)", strings.Join(params, ", ")))
+		args = append(args, dbm.ID, dbm.Channel, dbm.Subtopic, dbm.Publisher, dbm.Protocol,
+			dbm.Name, dbm.Unit, dbm.FloatValue, dbm.StringValue, dbm.BoolValue, dbm.DataValue,
+			dbm.ValueSum, dbm.Time, dbm.UpdateTime, dbm.Link, dbm.Sequence)
 	}
 
-	if _, err := pr.db.NamedExec(q, dbth); err != nil {
+	if _, err := pr.db.Exec(fmt.Sprintf(q, strings.Join(rows, ", ")), args...); err != nil {
 		pqErr, ok := err.(*pq.Error)
 		if ok {
 			switch pqErr.Code.Name() {
@@ -75,6 +139,7 @@ type dbMessage struct {
 	Time        float64  `db:"time"`
 	UpdateTime  float64  `db:"update_time"`
 	Link        string   `db:"link"`
+	Sequence    uint64   `db:"sequence"`
 }
 
 func toDBMessage(msg mainflux.Message) (dbMessage, error) {
@@ -123,5 +188,6 @@ func toDBMessage(msg mainflux.Message) (dbMessage, error) {
 		Time:        msg.Time,
 		UpdateTime:  msg.UpdateTime,
 		Link:        msg.Link,
+		Sequence:    msg.Sequence,
 	}, nil
 }
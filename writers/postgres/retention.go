@@ -0,0 +1,60 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/mainflux/mainflux/writers"
+)
+
+var _ writers.RetentionRepository = (*retentionRepo)(nil)
+
+type retentionRepo struct {
+	db *sqlx.DB
+}
+
+// NewRetentionRepository returns new PostgreSQL retention repository.
+func NewRetentionRepository(db *sqlx.DB) writers.RetentionRepository {
+	return &retentionRepo{db: db}
+}
+
+func (rr retentionRepo) Retain(channel string, retention writers.Retention) error {
+	if retention == (writers.Retention{}) {
+		_, err := rr.db.Exec(`DELETE FROM retention WHERE channel = $1`, channel)
+		return err
+	}
+
+	q := `INSERT INTO retention (channel, period, messages) VALUES (:channel, :period, :messages)
+	      ON CONFLICT (channel) DO UPDATE SET period = :period, messages = :messages;`
+
+	params := map[string]interface{}{
+		"channel":  channel,
+		"period":   int64(retention.Period / time.Second),
+		"messages": int64(retention.Messages),
+	}
+	_, err := rr.db.NamedExec(q, params)
+	return err
+}
+
+func (rr retentionRepo) Reap(now time.Time) error {
+	q := `DELETE FROM messages m USING retention r
+	      WHERE m.channel = r.channel AND r.period > 0 AND m.time < $1 - r.period;`
+	if _, err := rr.db.Exec(q, now.Unix()); err != nil {
+		return err
+	}
+
+	// Only the newest messages of the channel, up to the retained count,
+	// are kept.
+	q = `DELETE FROM messages WHERE id IN (
+	       SELECT id FROM (
+	         SELECT m.id, r.messages, ROW_NUMBER() OVER (PARTITION BY m.channel ORDER BY m.time DESC) AS n
+	         FROM messages m INNER JOIN retention r ON r.channel = m.channel
+	         WHERE r.messages > 0
+	       ) ranked WHERE n > messages
+	     );`
+	_, err := rr.db.Exec(q)
+	return err
+}
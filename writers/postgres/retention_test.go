@@ -0,0 +1,87 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/writers"
+	"github.com/mainflux/mainflux/writers/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReap(t *testing.T) {
+	messageRepo := postgres.New(db)
+	retentionRepo := postgres.NewRetentionRepository(db)
+
+	pubid, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	now := time.Now().Truncate(time.Second)
+
+	cases := []struct {
+		desc      string
+		retention writers.Retention
+		count     int
+	}{
+		{
+			desc:      "reap messages of channel with retention period",
+			retention: writers.Retention{Period: time.Hour},
+			count:     3,
+		},
+		{
+			desc:      "reap messages of channel with retention message count",
+			retention: writers.Retention{Messages: 2},
+			count:     2,
+		},
+		{
+			desc:      "reap messages of channel with retention period and message count",
+			retention: writers.Retention{Period: time.Hour, Messages: 1},
+			count:     1,
+		},
+		{
+			desc:      "reap messages of channel without retention",
+			retention: writers.Retention{},
+			count:     5,
+		},
+	}
+
+	chans := []string{}
+	for _, tc := range cases {
+		chid, err := uuid.NewV4()
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+		chans = append(chans, chid.String())
+
+		// Messages are sent every half an hour, the newest one now.
+		for i := 0; i < 5; i++ {
+			msg := mainflux.Message{
+				Channel:   chid.String(),
+				Publisher: pubid.String(),
+				Name:      "temperature",
+				Value:     &mainflux.Message_FloatValue{FloatValue: float64(i)},
+				Time:      float64(now.Add(-time.Duration(i) * 30 * time.Minute).Unix()),
+			}
+			err := messageRepo.Save(msg)
+			require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+		}
+
+		err = retentionRepo.Retain(chid.String(), tc.retention)
+		require.Nil(t, err, fmt.Sprintf("%s: got unexpected error: %s", tc.desc, err))
+	}
+
+	err = retentionRepo.Reap(now)
+	assert.Nil(t, err, fmt.Sprintf("reap messages: expected no error got %s\n", err))
+
+	for i, tc := range cases {
+		var count int
+		err := db.Get(&count, `SELECT COUNT(*) FROM messages WHERE channel = $1`, chans[i])
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+		assert.Equal(t, tc.count, count, fmt.Sprintf("%s: expected %d messages got %d\n", tc.desc, tc.count, count))
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/mainflux/mainflux/writers"
+)
+
+var _ writers.SubtopicRepository = (*subtopicRepo)(nil)
+
+type subtopicRepo struct {
+	db *sqlx.DB
+}
+
+// NewSubtopicRepository returns new PostgreSQL subtopic repository.
+func NewSubtopicRepository(db *sqlx.DB) writers.SubtopicRepository {
+	return &subtopicRepo{db: db}
+}
+
+func (sr subtopicRepo) Save(subtopics ...writers.Subtopic) error {
+	if len(subtopics) == 0 {
+		return nil
+	}
+
+	q := `INSERT INTO subtopics (channel, subtopic, last_seen) VALUES %s
+	      ON CONFLICT (channel, subtopic) DO UPDATE
+	      SET last_seen = GREATEST(subtopics.last_seen, EXCLUDED.last_seen);`
+
+	rows := make([]string, 0, len(subtopics))
+	args := make([]interface{}, 0, len(subtopics)*3)
+	for i, st := range subtopics {
+		rows = append(rows, fmt.Sprintf("($%d, $%d, $%d)", i*3+1, i*3+2, i*3+3))
+		args = append(args, st.Channel, st.Name, st.LastSeen)
+	}
+
+	_, err := sr.db.Exec(fmt.Sprintf(q, strings.Join(rows, ", ")), args...)
+	return err
+}
@@ -0,0 +1,70 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/mainflux/mainflux/writers"
+	"github.com/mainflux/mainflux/writers/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveSubtopics(t *testing.T) {
+	repo := postgres.NewSubtopicRepository(db)
+
+	chid, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	channel := chid.String()
+
+	cases := []struct {
+		desc      string
+		subtopics []writers.Subtopic
+		lastSeen  map[string]float64
+	}{
+		{
+			desc: "save new subtopics",
+			subtopics: []writers.Subtopic{
+				{Channel: channel, Name: "room.temperature", LastSeen: 10},
+				{Channel: channel, Name: "room.humidity", LastSeen: 20},
+			},
+			lastSeen: map[string]float64{"room.temperature": 10, "room.humidity": 20},
+		},
+		{
+			desc: "save newer subtopic",
+			subtopics: []writers.Subtopic{
+				{Channel: channel, Name: "room.temperature", LastSeen: 30},
+			},
+			lastSeen: map[string]float64{"room.temperature": 30, "room.humidity": 20},
+		},
+		{
+			desc: "save older subtopic",
+			subtopics: []writers.Subtopic{
+				{Channel: channel, Name: "room.humidity", LastSeen: 5},
+			},
+			lastSeen: map[string]float64{"room.temperature": 30, "room.humidity": 20},
+		},
+	}
+
+	for _, tc := range cases {
+		err := repo.Save(tc.subtopics...)
+		assert.Nil(t, err, fmt.Sprintf("%s: expected no error got %s\n", tc.desc, err))
+
+		lastSeen := map[string]float64{}
+		rows, err := db.Queryx(`SELECT subtopic, last_seen FROM subtopics WHERE channel = $1`, channel)
+		require.Nil(t, err, fmt.Sprintf("%s: got unexpected error: %s", tc.desc, err))
+		for rows.Next() {
+			var name string
+			var seen float64
+			err := rows.Scan(&name, &seen)
+			require.Nil(t, err, fmt.Sprintf("%s: got unexpected error: %s", tc.desc, err))
+			lastSeen[name] = seen
+		}
+		rows.Close()
+		assert.Equal(t, tc.lastSeen, lastSeen, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.lastSeen, lastSeen))
+	}
+}
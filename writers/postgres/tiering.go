@@ -0,0 +1,170 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"math"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/mainflux/mainflux"
+)
+
+const hour = float64(time.Hour / time.Second)
+
+// Archive represents cold storage for the raw messages removed from the
+// database during compaction.
+type Archive interface {
+	// Save persists raw messages sent during the hour starting at the
+	// given time. A non-nil error is returned to indicate operation failure.
+	Save(time.Time, []mainflux.Message) error
+}
+
+// Compactor replaces raw messages with their hourly rollups.
+type Compactor interface {
+	// Compact archives and removes raw messages sent before the given time,
+	// keeping count, sum, min and max of their numeric values per hour. Only
+	// complete hours are compacted. Number of removed messages is returned.
+	Compact(time.Time) (uint64, error)
+}
+
+var _ Compactor = (*compactor)(nil)
+
+type compactor struct {
+	db      *sqlx.DB
+	archive Archive
+}
+
+// NewCompactor returns new PostgreSQL compactor that moves raw messages to
+// the given archive.
+func NewCompactor(db *sqlx.DB, archive Archive) Compactor {
+	return &compactor{
+		db:      db,
+		archive: archive,
+	}
+}
+
+func (c compactor) Compact(before time.Time) (uint64, error) {
+	cutoff := math.Floor(float64(before.Unix())/hour) * hour
+
+	var total uint64
+	for {
+		var first *float64
+		if err := c.db.Get(&first, `SELECT MIN(time) FROM messages WHERE time < $1`, cutoff); err != nil {
+			return total, err
+		}
+		if first == nil {
+			return total, nil
+		}
+
+		start := math.Floor(*first/hour) * hour
+		n, err := c.compactHour(start)
+		if err != nil {
+			return total, err
+		}
+		total += n
+	}
+}
+
+// compactHour moves messages sent during the hour starting at the given time
+// to the archive and replaces them with rollups in a single transaction, so
+// the messages are never removed before they are archived.
+func (c compactor) compactHour(start float64) (uint64, error) {
+	tx, err := c.db.Beginx()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	q := `SELECT channel, subtopic, publisher, protocol, name, unit, value,
+    string_value, bool_value, data_value, value_sum, time, update_time, link,
+    sequence FROM messages WHERE time >= $1 AND time < $2 FOR UPDATE`
+
+	rows, err := tx.Queryx(q, start, start+hour)
+	if err != nil {
+		return 0, err
+	}
+
+	msgs := []mainflux.Message{}
+	for rows.Next() {
+		dbm := dbMessage{}
+		if err := rows.StructScan(&dbm); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		msgs = append(msgs, toMessage(dbm))
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	if err := c.archive.Save(time.Unix(int64(start), 0), msgs); err != nil {
+		return 0, err
+	}
+
+	q = `INSERT INTO rollups (channel, subtopic, publisher, protocol, name,
+    unit, hour, count, sum, min, max)
+    SELECT channel, subtopic, publisher, protocol, name, unit, $1, COUNT(value),
+    SUM(value), MIN(value), MAX(value)
+    FROM messages WHERE time >= $1 AND time < $2 AND value IS NOT NULL
+    GROUP BY channel, subtopic, publisher, protocol, name, unit
+    ON CONFLICT (channel, subtopic, publisher, protocol, name, unit, hour)
+    DO UPDATE SET count = rollups.count + excluded.count,
+    sum = rollups.sum + excluded.sum,
+    min = LEAST(rollups.min, excluded.min),
+    max = GREATEST(rollups.max, excluded.max)`
+
+	if _, err := tx.Exec(q, start, start+hour); err != nil {
+		return 0, err
+	}
+
+	res, err := tx.Exec(`DELETE FROM messages WHERE time >= $1 AND time < $2`, start, start+hour)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return uint64(n), nil
+}
+
+func toMessage(dbm dbMessage) mainflux.Message {
+	msg := mainflux.Message{
+		Channel:    dbm.Channel,
+		Subtopic:   dbm.Subtopic,
+		Publisher:  dbm.Publisher,
+		Protocol:   dbm.Protocol,
+		Name:       dbm.Name,
+		Unit:       dbm.Unit,
+		Time:       dbm.Time,
+		UpdateTime: dbm.UpdateTime,
+		Link:       dbm.Link,
+		Sequence:   dbm.Sequence,
+	}
+
+	switch {
+	case dbm.FloatValue != nil:
+		msg.Value = &mainflux.Message_FloatValue{FloatValue: *dbm.FloatValue}
+	case dbm.StringValue != nil:
+		msg.Value = &mainflux.Message_StringValue{StringValue: *dbm.StringValue}
+	case dbm.BoolValue != nil:
+		msg.Value = &mainflux.Message_BoolValue{BoolValue: *dbm.BoolValue}
+	case dbm.DataValue != nil:
+		msg.Value = &mainflux.Message_DataValue{DataValue: *dbm.DataValue}
+	}
+
+	if dbm.ValueSum != nil {
+		msg.ValueSum = &mainflux.SumValue{Value: *dbm.ValueSum}
+	}
+
+	return msg
+}
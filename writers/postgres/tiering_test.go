@@ -0,0 +1,102 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/writers/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type archiveMock struct {
+	msgs []mainflux.Message
+}
+
+func (am *archiveMock) Save(_ time.Time, msgs []mainflux.Message) error {
+	am.msgs = append(am.msgs, msgs...)
+	return nil
+}
+
+func TestCompact(t *testing.T) {
+	messageRepo := postgres.New(db)
+	archive := &archiveMock{}
+	compactor := postgres.NewCompactor(db, archive)
+
+	chid, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	pubid, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	now := time.Now().Truncate(time.Hour)
+	old := now.Add(-2 * time.Hour)
+
+	// Two complete hours of numeric values, one string value and
+	// messages from the current hour which must not be compacted.
+	for i := 0; i < 20; i++ {
+		msg := mainflux.Message{
+			Channel:   chid.String(),
+			Publisher: pubid.String(),
+			Name:      "temperature",
+			Value:     &mainflux.Message_FloatValue{FloatValue: float64(i)},
+			Time:      float64(old.Unix() + int64(i*360)),
+		}
+		err := messageRepo.Save(msg)
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	}
+	strMsg := mainflux.Message{
+		Channel:   chid.String(),
+		Publisher: pubid.String(),
+		Name:      "status",
+		Value:     &mainflux.Message_StringValue{StringValue: "on"},
+		Time:      float64(old.Unix()),
+	}
+	err = messageRepo.Save(strMsg)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	recent := mainflux.Message{
+		Channel:   chid.String(),
+		Publisher: pubid.String(),
+		Name:      "temperature",
+		Value:     &mainflux.Message_FloatValue{FloatValue: 100},
+		Time:      float64(now.Unix() + 1),
+	}
+	err = messageRepo.Save(recent)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	n, err := compactor.Compact(now.Add(30 * time.Minute))
+	assert.Nil(t, err, fmt.Sprintf("compact messages: expected no error got %s\n", err))
+	assert.Equal(t, uint64(21), n, fmt.Sprintf("compact messages: expected %d got %d\n", 21, n))
+	assert.Equal(t, 21, len(archive.msgs), fmt.Sprintf("compact messages: expected %d archived got %d\n", 21, len(archive.msgs)))
+
+	var count int
+	err = db.Get(&count, `SELECT COUNT(*) FROM messages WHERE channel = $1`, chid.String())
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.Equal(t, 1, count, fmt.Sprintf("compact messages: expected %d raw messages got %d\n", 1, count))
+
+	type rollup struct {
+		Hour  float64 `db:"hour"`
+		Count uint64  `db:"count"`
+		Sum   float64 `db:"sum"`
+		Min   float64 `db:"min"`
+		Max   float64 `db:"max"`
+	}
+	rollups := []rollup{}
+	err = db.Select(&rollups, `SELECT hour, count, sum, min, max FROM rollups WHERE channel = $1 ORDER BY hour`, chid.String())
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	expected := []rollup{
+		{Hour: float64(old.Unix()), Count: 10, Sum: 45, Min: 0, Max: 9},
+		{Hour: float64(old.Add(time.Hour).Unix()), Count: 10, Sum: 145, Min: 10, Max: 19},
+	}
+	assert.Equal(t, expected, rollups, fmt.Sprintf("compact messages: expected %v got %v\n", expected, rollups))
+
+	n, err = compactor.Compact(now.Add(30 * time.Minute))
+	assert.Nil(t, err, fmt.Sprintf("compact compacted messages: expected no error got %s\n", err))
+	assert.Equal(t, uint64(0), n, fmt.Sprintf("compact compacted messages: expected %d got %d\n", 0, n))
+}
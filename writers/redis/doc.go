@@ -0,0 +1,6 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package redis contains subtopic repository implementation using Redis as
+// the underlying database, which can be used along with any message store.
+package redis
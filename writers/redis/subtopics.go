@@ -0,0 +1,56 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package redis
+
+import (
+	"fmt"
+
+	"github.com/go-redis/redis"
+	"github.com/mainflux/mainflux/writers"
+)
+
+const subtopicsPrefix = "subtopics:channel"
+
+// Subtopics of the channel are kept in the hash mapping the subtopic to its
+// last seen time. The time is updated only if it is newer, so the writers
+// saving the messages out of order can't move it back.
+var saveScript = redis.NewScript(`
+for i = 1, #ARGV, 2 do
+	local seen = tonumber(redis.call('HGET', KEYS[1], ARGV[i]))
+	if not seen or seen < tonumber(ARGV[i + 1]) then
+		redis.call('HSET', KEYS[1], ARGV[i], ARGV[i + 1])
+	end
+end
+return 1
+`)
+
+var _ writers.SubtopicRepository = (*subtopicRepo)(nil)
+
+type subtopicRepo struct {
+	client redis.UniversalClient
+}
+
+// NewSubtopicRepository returns new Redis subtopic repository.
+func NewSubtopicRepository(client redis.UniversalClient) writers.SubtopicRepository {
+	return &subtopicRepo{client: client}
+}
+
+func (sr subtopicRepo) Save(subtopics ...writers.Subtopic) error {
+	args := make(map[string][]interface{})
+	for _, st := range subtopics {
+		args[st.Channel] = append(args[st.Channel], st.Name, st.LastSeen)
+	}
+
+	for channel, a := range args {
+		if err := saveScript.Run(sr.client, []string{subtopicsKey(channel)}, a...).Err(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func subtopicsKey(channel string) string {
+	return fmt.Sprintf("%s:%s", subtopicsPrefix, channel)
+}
@@ -0,0 +1,59 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package redis_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux/writers"
+	"github.com/mainflux/mainflux/writers/redis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const channel = "1"
+
+func TestSave(t *testing.T) {
+	redisClient.FlushAll()
+	repo := redis.NewSubtopicRepository(redisClient)
+
+	cases := []struct {
+		desc      string
+		subtopics []writers.Subtopic
+		lastSeen  map[string]string
+	}{
+		{
+			desc: "save new subtopics",
+			subtopics: []writers.Subtopic{
+				{Channel: channel, Name: "room.temperature", LastSeen: 10},
+				{Channel: channel, Name: "room.humidity", LastSeen: 20},
+			},
+			lastSeen: map[string]string{"room.temperature": "10", "room.humidity": "20"},
+		},
+		{
+			desc: "save newer subtopic",
+			subtopics: []writers.Subtopic{
+				{Channel: channel, Name: "room.temperature", LastSeen: 30.5},
+			},
+			lastSeen: map[string]string{"room.temperature": "30.5", "room.humidity": "20"},
+		},
+		{
+			desc: "save older subtopic",
+			subtopics: []writers.Subtopic{
+				{Channel: channel, Name: "room.humidity", LastSeen: 5},
+			},
+			lastSeen: map[string]string{"room.temperature": "30.5", "room.humidity": "20"},
+		},
+	}
+
+	for _, tc := range cases {
+		err := repo.Save(tc.subtopics...)
+		assert.Nil(t, err, fmt.Sprintf("%s: expected no error got %s\n", tc.desc, err))
+
+		lastSeen, err := redisClient.HGetAll(fmt.Sprintf("subtopics:channel:%s", channel)).Result()
+		require.Nil(t, err, fmt.Sprintf("%s: got unexpected error: %s", tc.desc, err))
+		assert.Equal(t, tc.lastSeen, lastSeen, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.lastSeen, lastSeen))
+	}
+}
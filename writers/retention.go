@@ -0,0 +1,92 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package writers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	log "github.com/mainflux/mainflux/logger"
+)
+
+// Retention limits the stored messages of the channel. Messages older than
+// the period, as well as the messages exceeding the count, are removed.
+// Zero values impose no limit.
+type Retention struct {
+	Period   time.Duration
+	Messages uint64
+}
+
+// RetentionRepository specifies the retention enforcement API of the message
+// store.
+type RetentionRepository interface {
+	// Retain records the retention of the channel messages. Zero retention
+	// removes the limits of the channel.
+	Retain(string, Retention) error
+
+	// Reap removes the stored messages that, at the given time, fall outside
+	// of the retention of their channel.
+	Reap(time.Time) error
+}
+
+// Retainer keeps the retention of the channels recorded in the message store
+// up to date with the retention configured in the things service.
+type Retainer interface {
+	// Track refreshes the recorded retention of the channel if it was not
+	// refreshed within the refresh interval.
+	Track(string)
+}
+
+var _ Retainer = (*retainer)(nil)
+
+type retainer struct {
+	things  mainflux.ThingsServiceClient
+	repo    RetentionRepository
+	refresh time.Duration
+	logger  log.Logger
+	mu      sync.Mutex
+	checked map[string]time.Time
+}
+
+// NewRetainer returns new retainer that looks up the channels retention in
+// the things service at most once per refresh interval.
+func NewRetainer(things mainflux.ThingsServiceClient, repo RetentionRepository, refresh time.Duration, logger log.Logger) Retainer {
+	return &retainer{
+		things:  things,
+		repo:    repo,
+		refresh: refresh,
+		logger:  logger,
+		checked: make(map[string]time.Time),
+	}
+}
+
+func (r *retainer) Track(channel string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if checked, ok := r.checked[channel]; ok && time.Since(checked) < r.refresh {
+		return
+	}
+
+	// Failed lookups are retried after the refresh interval as well, so the
+	// unavailable things service is not queried for every message.
+	r.checked[channel] = time.Now()
+
+	res, err := r.things.Retention(context.Background(), &mainflux.ChannelID{Value: channel})
+	if err != nil {
+		r.logger.Warn(fmt.Sprintf("Failed to retrieve retention of channel %s: %s", channel, err))
+		return
+	}
+
+	retention := Retention{
+		Period:   time.Duration(res.GetPeriod()) * time.Second,
+		Messages: res.GetMessages(),
+	}
+	if err := r.repo.Retain(channel, retention); err != nil {
+		r.logger.Warn(fmt.Sprintf("Failed to record retention of channel %s: %s", channel, err))
+	}
+}
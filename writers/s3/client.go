@@ -0,0 +1,314 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package s3
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	service   = "s3"
+	algorithm = "AWS4-HMAC-SHA256"
+	dateFmt   = "20060102"
+	stampFmt  = "20060102T150405Z"
+
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+	// maxPresignTTL is the longest validity of the presigned URL accepted
+	// by the Signature Version 4.
+	maxPresignTTL = 7 * 24 * time.Hour
+)
+
+var (
+	// ErrFailedUpload indicates that the object storage responded with the
+	// unexpected status code.
+	ErrFailedUpload = errors.New("failed to upload object")
+
+	// ErrFailedDownload indicates that the object storage responded with the
+	// unexpected status code to the object retrieval.
+	ErrFailedDownload = errors.New("failed to download object")
+
+	// ErrObjectNotFound indicates that the object doesn't exist.
+	ErrObjectNotFound = errors.New("object not found")
+
+	// ErrFailedRemove indicates that the object storage responded with the
+	// unexpected status code to the object removal.
+	ErrFailedRemove = errors.New("failed to remove object")
+
+	// ErrInvalidTTL indicates that the presigned URL validity is out of the
+	// range supported by the object storage.
+	ErrInvalidTTL = errors.New("invalid presigned URL validity")
+)
+
+// Config defines the options that are used when connecting to the object
+// storage.
+type Config struct {
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+}
+
+// Client uploads the objects to the bucket and downloads them back.
+type Client interface {
+	// Put stores the object under the given key. Content type and encoding
+	// of the object are set to the given values.
+	Put(key, contentType, contentEncoding string, body []byte) error
+
+	// Get retrieves the object stored under the given key.
+	Get(key string) ([]byte, error)
+
+	// Remove removes the object stored under the given key. Removal of the
+	// non-existent object succeeds.
+	Remove(key string) error
+
+	// Presign returns the URL which allows anyone holding it to download
+	// the object stored under the given key until the TTL expires.
+	Presign(key string, ttl time.Duration) (string, error)
+}
+
+var _ Client = (*client)(nil)
+
+type client struct {
+	cfg  Config
+	http *http.Client
+}
+
+// NewClient returns the client that uploads the objects using path-style
+// requests signed with AWS Signature Version 4, which are supported by both
+// AWS S3 and MinIO.
+func NewClient(cfg Config, httpClient *http.Client) Client {
+	return client{
+		cfg:  cfg,
+		http: httpClient,
+	}
+}
+
+func (c client) Put(key, contentType, contentEncoding string, body []byte) error {
+	u, err := c.objectURL(key)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPut, u, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	if contentEncoding != "" {
+		req.Header.Set("Content-Encoding", contentEncoding)
+	}
+	c.sign(req, body, time.Now().UTC())
+
+	res, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return ErrFailedUpload
+	}
+
+	return nil
+}
+
+func (c client) Get(key string) ([]byte, error) {
+	u, err := c.objectURL(key)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	c.sign(req, nil, time.Now().UTC())
+
+	res, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, ErrObjectNotFound
+	default:
+		return nil, ErrFailedDownload
+	}
+
+	return ioutil.ReadAll(res.Body)
+}
+
+func (c client) Remove(key string) error {
+	u, err := c.objectURL(key)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodDelete, u, nil)
+	if err != nil {
+		return err
+	}
+	c.sign(req, nil, time.Now().UTC())
+
+	res, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusOK, http.StatusNoContent, http.StatusNotFound:
+		return nil
+	default:
+		return ErrFailedRemove
+	}
+}
+
+func (c client) Presign(key string, ttl time.Duration) (string, error) {
+	if ttl < time.Second || ttl > maxPresignTTL {
+		return "", ErrInvalidTTL
+	}
+
+	u, err := c.objectURL(key)
+	if err != nil {
+		return "", err
+	}
+
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return "", err
+	}
+
+	return c.presign(parsed, ttl, time.Now().UTC()), nil
+}
+
+// objectURL returns the path-style URL of the object stored under the key.
+func (c client) objectURL(key string) (string, error) {
+	u, err := url.Parse(c.cfg.Endpoint)
+	if err != nil {
+		return "", err
+	}
+	u.Path = fmt.Sprintf("/%s/%s", c.cfg.Bucket, key)
+	u.RawPath = escapePath(u.Path)
+
+	return u.String(), nil
+}
+
+// sign adds the AWS Signature Version 4 authorization header to the request.
+// Only the host, date and payload hash headers are signed.
+func (c client) sign(req *http.Request, body []byte, now time.Time) {
+	payloadHash := hashHex(body)
+	stamp := now.Format(stampFmt)
+	req.Header.Set("X-Amz-Date", stamp)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalHeaders := fmt.Sprintf("host:%s\nx-amz-content-sha256:%s\nx-amz-date:%s\n", req.URL.Host, payloadHash, stamp)
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		"",
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := c.scope(now)
+	signature := c.signature(now, scope, canonicalRequest)
+
+	auth := fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s", algorithm, c.cfg.AccessKey, scope, signedHeaders, signature)
+	req.Header.Set("Authorization", auth)
+}
+
+// presign returns the URL of the GET request authenticated by the query
+// parameters. Only the host header is signed and the payload isn't.
+func (c client) presign(u *url.URL, ttl time.Duration, now time.Time) string {
+	scope := c.scope(now)
+	query := url.Values{
+		"X-Amz-Algorithm":     {algorithm},
+		"X-Amz-Credential":    {fmt.Sprintf("%s/%s", c.cfg.AccessKey, scope)},
+		"X-Amz-Date":          {now.Format(stampFmt)},
+		"X-Amz-Expires":       {strconv.FormatInt(int64(ttl/time.Second), 10)},
+		"X-Amz-SignedHeaders": {"host"},
+	}
+	// Encode sorts the parameters by key, as required by the canonical
+	// request, but encodes spaces as plus signs.
+	canonicalQuery := strings.Replace(query.Encode(), "+", "%20", -1)
+
+	canonicalRequest := strings.Join([]string{
+		http.MethodGet,
+		u.EscapedPath(),
+		canonicalQuery,
+		fmt.Sprintf("host:%s\n", u.Host),
+		"host",
+		unsignedPayload,
+	}, "\n")
+
+	u.RawQuery = fmt.Sprintf("%s&X-Amz-Signature=%s", canonicalQuery, c.signature(now, scope, canonicalRequest))
+	return u.String()
+}
+
+func (c client) scope(now time.Time) string {
+	return fmt.Sprintf("%s/%s/%s/aws4_request", now.Format(dateFmt), c.cfg.Region, service)
+}
+
+// signature signs the canonical request with the key derived from the
+// secret key, the date and the scope of the request.
+func (c client) signature(now time.Time, scope, canonicalRequest string) string {
+	stringToSign := strings.Join([]string{
+		algorithm,
+		now.Format(stampFmt),
+		scope,
+		hashHex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+c.cfg.SecretKey), now.Format(dateFmt))
+	key = hmacSHA256(key, c.cfg.Region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+// escapePath encodes every byte of the path except the unreserved
+// characters and slashes, as required by the canonical request.
+func escapePath(path string) string {
+	var sb strings.Builder
+	for i := 0; i < len(path); i++ {
+		b := path[i]
+		switch {
+		case 'a' <= b && b <= 'z', 'A' <= b && b <= 'Z', '0' <= b && b <= '9',
+			b == '-', b == '_', b == '.', b == '~', b == '/':
+			sb.WriteByte(b)
+		default:
+			fmt.Fprintf(&sb, "%%%02X", b)
+		}
+	}
+	return sb.String()
+}
+
+func hashHex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,6 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package s3 contains repository implementation archiving the messages to
+// the S3-compatible object storage, such as AWS S3 or MinIO.
+package s3
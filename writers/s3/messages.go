@@ -0,0 +1,243 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package s3
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/writers"
+)
+
+const (
+	contentType     = "application/x-ndjson"
+	contentEncoding = "gzip"
+	dayFmt          = "2006-01-02"
+)
+
+var _ writers.MessageRepository = (*s3Repo)(nil)
+
+type s3Repo struct {
+	client Client
+	prefix string
+}
+
+// message is the JSON representation of the archived Mainflux message.
+type message struct {
+	Channel     string   `json:"channel"`
+	Subtopic    string   `json:"subtopic,omitempty"`
+	Publisher   string   `json:"publisher"`
+	Protocol    string   `json:"protocol"`
+	Name        string   `json:"name"`
+	Unit        string   `json:"unit,omitempty"`
+	FloatValue  *float64 `json:"floatValue,omitempty"`
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	DataValue   *string  `json:"dataValue,omitempty"`
+	ValueSum    *float64 `json:"valueSum,omitempty"`
+	Time        float64  `json:"time"`
+	UpdateTime  float64  `json:"updateTime,omitempty"`
+	Link        string   `json:"link,omitempty"`
+	Sequence    uint64   `json:"sequence,omitempty"`
+}
+
+// partition identifies the messages of a single channel sent on the same day.
+type partition struct {
+	channel string
+	day     string
+}
+
+// New returns new S3 message repository. Every save uploads the messages of
+// each channel and day as a separate gzipped NDJSON object, whose key is
+// partitioned as <prefix>/channel=<channel>/date=<yyyy-mm-dd>/<name>.ndjson.gz
+// so that the archive can be queried by the tools such as Athena or Presto.
+func New(client Client, prefix string) writers.MessageRepository {
+	return &s3Repo{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+func (repo s3Repo) Save(msgs ...mainflux.Message) error {
+	parts := map[partition][]mainflux.Message{}
+	for _, msg := range msgs {
+		p := partition{
+			channel: msg.Channel,
+			day:     msgTime(msg).Format(dayFmt),
+		}
+		parts[p] = append(parts[p], msg)
+	}
+
+	keys := make([]partition, 0, len(parts))
+	for p := range parts {
+		keys = append(keys, p)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].channel != keys[j].channel {
+			return keys[i].channel < keys[j].channel
+		}
+		return keys[i].day < keys[j].day
+	})
+
+	for _, p := range keys {
+		data, err := Encode(parts[p])
+		if err != nil {
+			return err
+		}
+
+		key, err := repo.objectKey(p)
+		if err != nil {
+			return err
+		}
+
+		if err := repo.client.Put(key, contentType, contentEncoding, data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// objectKey returns the unique key of the new object in the partition. The
+// objects are never overwritten, since the messages of the same partition
+// are saved by many batches, possibly by many writer instances.
+func (repo s3Repo) objectKey(p partition) (string, error) {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+
+	name := fmt.Sprintf("%d-%s.ndjson.gz", time.Now().UnixNano(), hex.EncodeToString(b[:]))
+	return path.Join(repo.prefix, "channel="+p.channel, "date="+p.day, name), nil
+}
+
+func msgTime(msg mainflux.Message) time.Time {
+	if msg.Time == 0 {
+		return time.Now().UTC()
+	}
+
+	sec := int64(msg.Time)
+	nsec := int64((msg.Time - float64(sec)) * float64(time.Second))
+	return time.Unix(sec, nsec).UTC()
+}
+
+// Encode encodes the messages in the archive format, as the gzipped NDJSON
+// holding one message per line.
+func Encode(msgs []mainflux.Message) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	enc := json.NewEncoder(zw)
+	for _, msg := range msgs {
+		if err := enc.Encode(toJSON(msg)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Decode decodes the messages encoded in the archive format.
+func Decode(data []byte) ([]mainflux.Message, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	msgs := []mainflux.Message{}
+	dec := json.NewDecoder(zr)
+	for {
+		var m message
+		err := dec.Decode(&m)
+		if err == io.EOF {
+			return msgs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, fromJSON(m))
+	}
+}
+
+func toJSON(msg mainflux.Message) message {
+	m := message{
+		Channel:    msg.Channel,
+		Subtopic:   msg.Subtopic,
+		Publisher:  msg.Publisher,
+		Protocol:   msg.Protocol,
+		Name:       msg.Name,
+		Unit:       msg.Unit,
+		Time:       msg.Time,
+		UpdateTime: msg.UpdateTime,
+		Link:       msg.Link,
+		Sequence:   msg.Sequence,
+	}
+
+	switch msg.Value.(type) {
+	case *mainflux.Message_FloatValue:
+		v := msg.GetFloatValue()
+		m.FloatValue = &v
+	case *mainflux.Message_StringValue:
+		v := msg.GetStringValue()
+		m.StringValue = &v
+	case *mainflux.Message_DataValue:
+		v := msg.GetDataValue()
+		m.DataValue = &v
+	case *mainflux.Message_BoolValue:
+		v := msg.GetBoolValue()
+		m.BoolValue = &v
+	}
+
+	if msg.GetValueSum() != nil {
+		v := msg.GetValueSum().GetValue()
+		m.ValueSum = &v
+	}
+
+	return m
+}
+
+func fromJSON(m message) mainflux.Message {
+	msg := mainflux.Message{
+		Channel:    m.Channel,
+		Subtopic:   m.Subtopic,
+		Publisher:  m.Publisher,
+		Protocol:   m.Protocol,
+		Name:       m.Name,
+		Unit:       m.Unit,
+		Time:       m.Time,
+		UpdateTime: m.UpdateTime,
+		Link:       m.Link,
+		Sequence:   m.Sequence,
+	}
+
+	switch {
+	case m.FloatValue != nil:
+		msg.Value = &mainflux.Message_FloatValue{FloatValue: *m.FloatValue}
+	case m.StringValue != nil:
+		msg.Value = &mainflux.Message_StringValue{StringValue: *m.StringValue}
+	case m.DataValue != nil:
+		msg.Value = &mainflux.Message_DataValue{DataValue: *m.DataValue}
+	case m.BoolValue != nil:
+		msg.Value = &mainflux.Message_BoolValue{BoolValue: *m.BoolValue}
+	}
+
+	if m.ValueSum != nil {
+		msg.ValueSum = &mainflux.SumValue{Value: *m.ValueSum}
+	}
+
+	return msg
+}
@@ -0,0 +1,353 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package s3_test
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/writers/s3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	bucket    = "archive"
+	prefix    = "messages"
+	accessKey = "access"
+	secretKey = "secret"
+	day       = 86400
+)
+
+type storage struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (s *storage) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	presigned := r.Method == http.MethodGet && r.URL.Query().Get("X-Amz-Signature") != "" &&
+		strings.HasPrefix(r.URL.Query().Get("X-Amz-Credential"), accessKey+"/")
+	if !presigned && !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential="+accessKey+"/") {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	if r.Method == http.MethodDelete {
+		s.mu.Lock()
+		delete(s.objects, r.URL.EscapedPath())
+		s.mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	if r.Method == http.MethodGet {
+		s.mu.Lock()
+		data, ok := s.objects[r.URL.EscapedPath()]
+		s.mu.Unlock()
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(data)
+		return
+	}
+
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	s.objects[r.URL.EscapedPath()] = data
+	s.mu.Unlock()
+}
+
+func TestSave(t *testing.T) {
+	st := &storage{objects: map[string][]byte{}}
+	ts := httptest.NewServer(st)
+	defer ts.Close()
+
+	cfg := s3.Config{
+		Endpoint:  ts.URL,
+		Region:    "us-east-1",
+		Bucket:    bucket,
+		AccessKey: accessKey,
+		SecretKey: secretKey,
+	}
+	repo := s3.New(s3.NewClient(cfg, http.DefaultClient), prefix)
+
+	msg := mainflux.Message{
+		Channel:   "45",
+		Publisher: "2580",
+		Protocol:  "http",
+		Name:      "name",
+		Unit:      "U",
+		Value:     &mainflux.Message_FloatValue{FloatValue: 5},
+		ValueSum:  &mainflux.SumValue{Value: 45},
+		Time:      day,
+	}
+
+	var msgs []mainflux.Message
+	for i := 0; i < 10; i++ {
+		m := msg
+		m.Time += float64(i % 2 * day)
+		msgs = append(msgs, m)
+	}
+	other := msg
+	other.Channel = "46"
+	msgs = append(msgs, other)
+
+	err := repo.Save(msgs...)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+
+	counts := map[string]int{}
+	for key, data := range st.objects {
+		parts := strings.Split(key, "/")
+		require.Len(t, parts, 6, fmt.Sprintf("unexpected object key %s", key))
+		partition := strings.Join(parts[:5], "/")
+
+		zr, err := gzip.NewReader(strings.NewReader(string(data)))
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		sc := bufio.NewScanner(zr)
+		for sc.Scan() {
+			var m map[string]interface{}
+			err := json.Unmarshal(sc.Bytes(), &m)
+			require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+			assert.Equal(t, float64(5), m["floatValue"], fmt.Sprintf("%s: expected value 5 got %v\n", key, m["floatValue"]))
+			counts[partition]++
+		}
+	}
+
+	expected := map[string]int{
+		"/archive/messages/channel%3D45/date%3D1970-01-02": 5,
+		"/archive/messages/channel%3D45/date%3D1970-01-03": 5,
+		"/archive/messages/channel%3D46/date%3D1970-01-02": 1,
+	}
+	assert.Equal(t, expected, counts, fmt.Sprintf("expected %v got %v\n", expected, counts))
+}
+
+func TestSaveFailedUpload(t *testing.T) {
+	st := &storage{objects: map[string][]byte{}}
+	ts := httptest.NewServer(st)
+	defer ts.Close()
+
+	cfg := s3.Config{
+		Endpoint:  ts.URL,
+		Region:    "us-east-1",
+		Bucket:    bucket,
+		AccessKey: "unknown",
+		SecretKey: secretKey,
+	}
+	repo := s3.New(s3.NewClient(cfg, http.DefaultClient), prefix)
+
+	err := repo.Save(mainflux.Message{Channel: "45", Time: day})
+	assert.Equal(t, s3.ErrFailedUpload, err, fmt.Sprintf("expected %s got %s\n", s3.ErrFailedUpload, err))
+}
+
+func TestGet(t *testing.T) {
+	st := &storage{objects: map[string][]byte{}}
+	ts := httptest.NewServer(st)
+	defer ts.Close()
+
+	cfg := s3.Config{
+		Endpoint:  ts.URL,
+		Region:    "us-east-1",
+		Bucket:    bucket,
+		AccessKey: accessKey,
+		SecretKey: secretKey,
+	}
+	client := s3.NewClient(cfg, http.DefaultClient)
+
+	err := client.Put("snapshots/user@example.com/1.json", "application/json", "", []byte("{}"))
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+
+	cases := []struct {
+		desc string
+		key  string
+		data []byte
+		err  error
+	}{
+		{
+			desc: "get existing object",
+			key:  "snapshots/user@example.com/1.json",
+			data: []byte("{}"),
+			err:  nil,
+		},
+		{
+			desc: "get non-existing object",
+			key:  "snapshots/user@example.com/2.json",
+			data: nil,
+			err:  s3.ErrObjectNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		data, err := client.Get(tc.key)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.data, data, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.data, data))
+	}
+}
+
+func TestRemove(t *testing.T) {
+	st := &storage{objects: map[string][]byte{}}
+	ts := httptest.NewServer(st)
+	defer ts.Close()
+
+	cfg := s3.Config{
+		Endpoint:  ts.URL,
+		Region:    "us-east-1",
+		Bucket:    bucket,
+		AccessKey: accessKey,
+		SecretKey: secretKey,
+	}
+	client := s3.NewClient(cfg, http.DefaultClient)
+
+	key := "firmware/1.bin"
+	err := client.Put(key, "application/octet-stream", "", []byte("image"))
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+
+	cases := []struct {
+		desc string
+		key  string
+		err  error
+	}{
+		{
+			desc: "remove existing object",
+			key:  key,
+			err:  nil,
+		},
+		{
+			desc: "remove non-existing object",
+			key:  key,
+			err:  nil,
+		},
+	}
+
+	for _, tc := range cases {
+		err := client.Remove(tc.key)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	_, err = client.Get(key)
+	assert.Equal(t, s3.ErrObjectNotFound, err, fmt.Sprintf("expected %s got %s\n", s3.ErrObjectNotFound, err))
+}
+
+func TestPresign(t *testing.T) {
+	st := &storage{objects: map[string][]byte{}}
+	ts := httptest.NewServer(st)
+	defer ts.Close()
+
+	cfg := s3.Config{
+		Endpoint:  ts.URL,
+		Region:    "us-east-1",
+		Bucket:    bucket,
+		AccessKey: accessKey,
+		SecretKey: secretKey,
+	}
+	client := s3.NewClient(cfg, http.DefaultClient)
+
+	key := "firmware/1.bin"
+	err := client.Put(key, "application/octet-stream", "", []byte("image"))
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+
+	cases := []struct {
+		desc string
+		ttl  time.Duration
+		err  error
+	}{
+		{
+			desc: "presign with valid TTL",
+			ttl:  time.Hour,
+			err:  nil,
+		},
+		{
+			desc: "presign with too short TTL",
+			ttl:  time.Millisecond,
+			err:  s3.ErrInvalidTTL,
+		},
+		{
+			desc: "presign with too long TTL",
+			ttl:  8 * 24 * time.Hour,
+			err:  s3.ErrInvalidTTL,
+		},
+	}
+
+	for _, tc := range cases {
+		u, err := client.Presign(key, tc.ttl)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err != nil {
+			continue
+		}
+
+		res, err := http.Get(u)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s\n", tc.desc, err))
+		data, err := ioutil.ReadAll(res.Body)
+		res.Body.Close()
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s\n", tc.desc, err))
+		assert.Equal(t, http.StatusOK, res.StatusCode, fmt.Sprintf("%s: expected status %d got %d\n", tc.desc, http.StatusOK, res.StatusCode))
+		assert.Equal(t, []byte("image"), data, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, "image", data))
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	msgs := []mainflux.Message{
+		{
+			Channel:   "45",
+			Publisher: "2580",
+			Protocol:  "http",
+			Name:      "float",
+			Unit:      "U",
+			Value:     &mainflux.Message_FloatValue{FloatValue: 5},
+			ValueSum:  &mainflux.SumValue{Value: 45},
+			Time:      day,
+		},
+		{
+			Channel:   "45",
+			Subtopic:  "engine",
+			Publisher: "2580",
+			Protocol:  "mqtt",
+			Name:      "string",
+			Value:     &mainflux.Message_StringValue{StringValue: "value"},
+			Time:      day + 1,
+			Sequence:  7,
+		},
+		{
+			Channel:   "45",
+			Publisher: "2580",
+			Protocol:  "coap",
+			Name:      "bool",
+			Value:     &mainflux.Message_BoolValue{BoolValue: false},
+			Time:      day + 2,
+		},
+		{
+			Channel:   "45",
+			Publisher: "2580",
+			Protocol:  "http",
+			Name:      "data",
+			Value:     &mainflux.Message_DataValue{DataValue: "data"},
+			Time:      day + 3,
+		},
+	}
+
+	data, err := s3.Encode(msgs)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+
+	decoded, err := s3.Decode(data)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+	assert.Equal(t, msgs, decoded, fmt.Sprintf("expected %v got %v\n", msgs, decoded))
+
+	_, err = s3.Decode([]byte("malformed"))
+	assert.NotNil(t, err, "expected error decoding malformed archive")
+}
@@ -0,0 +1,78 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package writers
+
+import (
+	"fmt"
+
+	"github.com/mainflux/mainflux"
+	log "github.com/mainflux/mainflux/logger"
+)
+
+// Subtopic represents the subtopic of the channel messages were published
+// to, along with the time of the newest of them.
+type Subtopic struct {
+	Channel  string
+	Name     string
+	LastSeen float64
+}
+
+// SubtopicRepository specifies the subtopics tracking API.
+type SubtopicRepository interface {
+	// Save records the subtopics. The last seen time of the already
+	// recorded subtopic is updated only if the given one is newer.
+	Save(...Subtopic) error
+}
+
+var _ MessageRepository = (*subtopicTracker)(nil)
+
+type subtopicTracker struct {
+	repo      MessageRepository
+	subtopics SubtopicRepository
+	logger    log.Logger
+}
+
+// NewSubtopicTracker returns message repository that records the subtopics
+// of the messages saved by the provided repository. Failing to record the
+// subtopics doesn't fail the save, as the messages are already stored.
+func NewSubtopicTracker(repo MessageRepository, subtopics SubtopicRepository, logger log.Logger) MessageRepository {
+	return &subtopicTracker{
+		repo:      repo,
+		subtopics: subtopics,
+		logger:    logger,
+	}
+}
+
+func (st *subtopicTracker) Save(msgs ...mainflux.Message) error {
+	if err := st.repo.Save(msgs...); err != nil {
+		return err
+	}
+
+	type key struct{ channel, subtopic string }
+	seen := make(map[key]float64)
+	for _, msg := range msgs {
+		if msg.Subtopic == "" {
+			continue
+		}
+		k := key{msg.Channel, msg.Subtopic}
+		if t, ok := seen[k]; !ok || msg.Time > t {
+			seen[k] = msg.Time
+		}
+	}
+
+	if len(seen) == 0 {
+		return nil
+	}
+
+	subtopics := make([]Subtopic, 0, len(seen))
+	for k, t := range seen {
+		subtopics = append(subtopics, Subtopic{Channel: k.channel, Name: k.subtopic, LastSeen: t})
+	}
+
+	if err := st.subtopics.Save(subtopics...); err != nil {
+		st.logger.Warn(fmt.Sprintf("Failed to record %d subtopics: %s", len(subtopics), err))
+	}
+
+	return nil
+}
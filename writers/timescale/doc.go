@@ -0,0 +1,6 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package timescale contains repository implementations using TimescaleDB as
+// the underlying database.
+package timescale
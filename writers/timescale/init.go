@@ -0,0 +1,155 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package timescale
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq" // required for SQL access
+	migrate "github.com/rubenv/sql-migrate"
+)
+
+// Config defines the options that are used when connecting to a TimescaleDB
+// instance, as well as the chunking, compression and retention policies of
+// the messages hypertable. Zero compression and retention ages disable the
+// corresponding policies.
+type Config struct {
+	Host          string
+	Port          string
+	User          string
+	Pass          string
+	Name          string
+	SSLMode       string
+	SSLCert       string
+	SSLKey        string
+	SSLRootCert   string
+	ChunkInterval time.Duration
+	CompressAfter time.Duration
+	RetainFor     time.Duration
+}
+
+// Connect creates a connection to the TimescaleDB instance, applies any
+// unapplied database migrations and updates the hypertable policies. A
+// non-nil error is returned to indicate failure.
+func Connect(cfg Config) (*sqlx.DB, error) {
+	url := fmt.Sprintf("host=%s port=%s user=%s dbname=%s password=%s sslmode=%s sslcert=%s sslkey=%s sslrootcert=%s", cfg.Host, cfg.Port, cfg.User, cfg.Name, cfg.Pass, cfg.SSLMode, cfg.SSLCert, cfg.SSLKey, cfg.SSLRootCert)
+
+	db, err := sqlx.Open("postgres", url)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := migrateDB(db); err != nil {
+		return nil, err
+	}
+
+	if err := applyPolicies(db, cfg); err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+func migrateDB(db *sqlx.DB) error {
+	migrations := &migrate.MemoryMigrationSource{
+		Migrations: []*migrate.Migration{
+			{
+				Id: "messages_1",
+				Up: []string{
+					`CREATE EXTENSION IF NOT EXISTS timescaledb`,
+					`CREATE TABLE IF NOT EXISTS messages (
+            channel       UUID,
+            subtopic      VARCHAR(254),
+            publisher     UUID,
+            protocol      TEXT,
+            name          TEXT,
+            unit          TEXT,
+            value         FLOAT,
+            string_value  TEXT,
+            bool_value    BOOL,
+            data_value    TEXT,
+            value_sum     FLOAT,
+            time          TIMESTAMPTZ NOT NULL,
+            update_time   FLOAT,
+            link          TEXT
+					)`,
+					`SELECT create_hypertable('messages', 'time', if_not_exists => TRUE)`,
+					`CREATE INDEX IF NOT EXISTS messages_channel_time_idx ON messages (channel, time DESC)`,
+					`ALTER TABLE messages SET (
+            timescaledb.compress,
+            timescaledb.compress_segmentby = 'channel',
+            timescaledb.compress_orderby = 'time DESC'
+					)`,
+				},
+				Down: []string{
+					"DROP TABLE messages",
+				},
+			},
+			{
+				// Compressed hypertables accept only the nullable columns
+				// without the default value.
+				Id: "messages_2",
+				Up: []string{
+					`ALTER TABLE messages ADD COLUMN IF NOT EXISTS sequence BIGINT`,
+				},
+				Down: []string{
+					"ALTER TABLE messages DROP COLUMN IF EXISTS sequence",
+				},
+			},
+			{
+				Id: "messages_3",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS subtopics (
+            channel       UUID,
+            subtopic      VARCHAR(254),
+            last_seen     FLOAT,
+            PRIMARY KEY (channel, subtopic)
+					)`,
+				},
+				Down: []string{
+					"DROP TABLE subtopics",
+				},
+			},
+		},
+	}
+
+	_, err := migrate.Exec(db.DB, "postgres", migrations, migrate.Up)
+	return err
+}
+
+// applyPolicies replaces the policies of the messages hypertable with the
+// configured ones. The chunk interval applies to the chunks created from now
+// on.
+func applyPolicies(db *sqlx.DB, cfg Config) error {
+	if cfg.ChunkInterval > 0 {
+		q := `SELECT set_chunk_time_interval('messages', make_interval(secs => $1))`
+		if _, err := db.Exec(q, cfg.ChunkInterval.Seconds()); err != nil {
+			return err
+		}
+	}
+
+	if _, err := db.Exec(`SELECT remove_compression_policy('messages', if_exists => TRUE)`); err != nil {
+		return err
+	}
+	if cfg.CompressAfter > 0 {
+		q := `SELECT add_compression_policy('messages', make_interval(secs => $1))`
+		if _, err := db.Exec(q, cfg.CompressAfter.Seconds()); err != nil {
+			return err
+		}
+	}
+
+	if _, err := db.Exec(`SELECT remove_retention_policy('messages', if_exists => TRUE)`); err != nil {
+		return err
+	}
+	if cfg.RetainFor > 0 {
+		q := `SELECT add_retention_policy('messages', make_interval(secs => $1))`
+		if _, err := db.Exec(q, cfg.RetainFor.Seconds()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
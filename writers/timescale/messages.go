@@ -0,0 +1,185 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package timescale
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq" // required for DB access
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/writers"
+)
+
+const (
+	errInvalid = "invalid_text_representation"
+
+	// columns is the number of the inserted message columns.
+	columns = 15
+	// timeColumn is the index of the time column, whose Unix timestamp is
+	// converted to the timestamp the hypertable is partitioned by.
+	timeColumn = 11
+	// maxRows keeps the parameters of the insert query within the limit of
+	// 65535 parameters imposed by Postgres.
+	maxRows = 4000
+)
+
+// ErrInvalidMessage indicates that service received message that
+// doesn't fit required format.
+var ErrInvalidMessage = errors.New("invalid message representation")
+
+var _ writers.MessageRepository = (*timescaleRepo)(nil)
+
+type timescaleRepo struct {
+	db *sqlx.DB
+}
+
+// New returns new TimescaleDB writer.
+func New(db *sqlx.DB) writers.MessageRepository {
+	return &timescaleRepo{db: db}
+}
+
+func (tr timescaleRepo) Save(msgs ...mainflux.Message) error {
+	for len(msgs) > 0 {
+		n := len(msgs)
+		if n > maxRows {
+			n = maxRows
+		}
+
+		if err := tr.saveBatch(msgs[:n]); err != nil {
+			return err
+		}
+		msgs = msgs[n:]
+	}
+
+	return nil
+}
+
+// saveBatch inserts the messages using a single multi-row insert. Since the
+// invalid message fails the whole insert, the messages of the rejected batch
+// are inserted one by one, so that the valid ones are saved.
+func (tr timescaleRepo) saveBatch(msgs []mainflux.Message) error {
+	err := tr.insert(msgs)
+	if err != ErrInvalidMessage || len(msgs) == 1 {
+		return err
+	}
+
+	var invalid bool
+	for _, msg := range msgs {
+		switch err := tr.insert([]mainflux.Message{msg}); err {
+		case nil:
+		case ErrInvalidMessage:
+			invalid = true
+		default:
+			return err
+		}
+	}
+
+	if invalid {
+		return ErrInvalidMessage
+	}
+
+	return nil
+}
+
+func (tr timescaleRepo) insert(msgs []mainflux.Message) error {
+	q := `INSERT INTO messages (channel, subtopic, publisher, protocol, name,
+    unit, value, string_value, bool_value, data_value, value_sum, time,
+    update_time, link, sequence)
+    VALUES %s;`
+
+	rows := make([]string, 0, len(msgs))
+	args := make([]interface{}, 0, len(msgs)*columns)
+	params := make([]string, columns)
+	for i, msg := range msgs {
+		dbm := toDBMessage(msg)
+
+		for j := range params {
+			params[j] = fmt.Sprintf("$%d", i*columns+j+1)
+		}
+		params[timeColumn] = fmt.Sprintf("to_timestamp(%s)", params[timeColumn])
+		rows = append(rows, fmt.Sprintf("(%s)", strings.Join(params, ", ")))
+		args = append(args, dbm.Channel, dbm.Subtopic, dbm.Publisher, dbm.Protocol, dbm.Name,
+			dbm.Unit, dbm.FloatValue, dbm.StringValue, dbm.BoolValue, dbm.DataValue, dbm.ValueSum,
+			dbm.Time, dbm.UpdateTime, dbm.Link, dbm.Sequence)
+	}
+
+	if _, err := tr.db.Exec(fmt.Sprintf(q, strings.Join(rows, ", ")), args...); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok {
+			switch pqErr.Code.Name() {
+			case errInvalid:
+				return ErrInvalidMessage
+			}
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+type dbMessage struct {
+	Channel     string
+	Subtopic    string
+	Publisher   string
+	Protocol    string
+	Name        string
+	Unit        string
+	FloatValue  *float64
+	StringValue *string
+	BoolValue   *bool
+	DataValue   *string
+	ValueSum    *float64
+	Time        float64
+	UpdateTime  float64
+	Link        string
+	Sequence    uint64
+}
+
+func toDBMessage(msg mainflux.Message) dbMessage {
+	var floatVal, valSum *float64
+	var strVal, dataVal *string
+	var boolVal *bool
+
+	switch msg.Value.(type) {
+	case *mainflux.Message_FloatValue:
+		v := msg.GetFloatValue()
+		floatVal = &v
+	case *mainflux.Message_StringValue:
+		v := msg.GetStringValue()
+		strVal = &v
+	case *mainflux.Message_DataValue:
+		v := msg.GetDataValue()
+		dataVal = &v
+	case *mainflux.Message_BoolValue:
+		v := msg.GetBoolValue()
+		boolVal = &v
+	}
+
+	if msg.GetValueSum() != nil {
+		v := msg.GetValueSum().GetValue()
+		valSum = &v
+	}
+
+	return dbMessage{
+		Channel:     msg.Channel,
+		Subtopic:    msg.Subtopic,
+		Publisher:   msg.Publisher,
+		Protocol:    msg.Protocol,
+		Name:        msg.Name,
+		Unit:        msg.Unit,
+		FloatValue:  floatVal,
+		StringValue: strVal,
+		BoolValue:   boolVal,
+		DataValue:   dataVal,
+		ValueSum:    valSum,
+		Time:        msg.Time,
+		UpdateTime:  msg.UpdateTime,
+		Link:        msg.Link,
+		Sequence:    msg.Sequence,
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package timescale_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/writers/timescale"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	msgsNum     = 42
+	valueFields = 6
+)
+
+func TestMessageSave(t *testing.T) {
+	messageRepo := timescale.New(db)
+
+	chid, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	pubid, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	msg := mainflux.Message{
+		Channel:   chid.String(),
+		Publisher: pubid.String(),
+	}
+
+	var msgs []mainflux.Message
+	now := time.Now().Unix()
+	for i := 0; i < msgsNum; i++ {
+		// Mix possible values as well as value sum.
+		count := i % valueFields
+		switch count {
+		case 0:
+			msg.Value = &mainflux.Message_FloatValue{FloatValue: 5}
+		case 1:
+			msg.Value = &mainflux.Message_BoolValue{BoolValue: false}
+		case 2:
+			msg.Value = &mainflux.Message_StringValue{StringValue: "value"}
+		case 3:
+			msg.Value = &mainflux.Message_DataValue{DataValue: "base64data"}
+		case 4:
+			msg.ValueSum = nil
+		case 5:
+			msg.ValueSum = &mainflux.SumValue{Value: 45}
+		}
+		msg.Time = float64(now + int64(i))
+		msgs = append(msgs, msg)
+
+		err := messageRepo.Save(msg)
+		assert.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+	}
+
+	err = messageRepo.Save(msgs...)
+	assert.Nil(t, err, fmt.Sprintf("saving batch of messages: expected no error got %s\n", err))
+
+	var count int
+	err = db.QueryRow(`SELECT COUNT(*) FROM messages WHERE channel = $1`, msg.Channel).Scan(&count)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.Equal(t, 2*msgsNum, count, fmt.Sprintf("expected %d saved messages got %d\n", 2*msgsNum, count))
+
+	msg.Channel = "invalid"
+	err = messageRepo.Save(msgs[0], msg)
+	assert.Equal(t, timescale.ErrInvalidMessage, err, fmt.Sprintf("saving invalid message: expected %s got %s\n", timescale.ErrInvalidMessage, err))
+}
@@ -9,26 +9,43 @@ import (
 	"github.com/gogo/protobuf/proto"
 	"github.com/mainflux/mainflux"
 	log "github.com/mainflux/mainflux/logger"
-	nats "github.com/nats-io/go-nats"
+	mfnats "github.com/mainflux/mainflux/nats"
+	"github.com/mainflux/mainflux/tracing"
+	nats "github.com/nats-io/nats.go"
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
 )
 
 type consumer struct {
 	nc       *nats.Conn
 	channels map[string]bool
 	repo     MessageRepository
+	retainer Retainer
+	tracer   opentracing.Tracer
 	logger   log.Logger
 }
 
-// Start method starts to consume normalized messages received from NATS.
-func Start(nc *nats.Conn, repo MessageRepository, queue string, channels map[string]bool, logger log.Logger) error {
+// Start method starts to consume normalized messages received from NATS,
+// as well as the dead letters replayed to the queue subject. The subjects are
+// prefixed with the deployment subject prefix, unless it is empty. Retention
+// of the channels whose messages are saved is tracked by the provided
+// retainer, unless it is nil. Saving of each message is traced following
+// the span of its publisher.
+func Start(nc *nats.Conn, subjectPrefix string, repo MessageRepository, retainer Retainer, queue string, channels map[string]bool, tracer opentracing.Tracer, logger log.Logger) error {
 	c := consumer{
 		nc:       nc,
 		channels: channels,
 		repo:     repo,
+		retainer: retainer,
+		tracer:   tracer,
 		logger:   logger,
 	}
 
-	_, err := nc.QueueSubscribe(mainflux.OutputSenML, queue, c.consume)
+	if _, err := nc.QueueSubscribe(mfnats.Subject(subjectPrefix, mainflux.OutputSenML), queue, c.consume); err != nil {
+		return err
+	}
+
+	_, err := nc.QueueSubscribe(mfnats.Subject(subjectPrefix, ReplaySubject(queue)), queue, c.consume)
 	return err
 }
 
@@ -39,14 +56,42 @@ func (c *consumer) consume(m *nats.Msg) {
 		return
 	}
 
+	if err := c.trace(m, *msg); err != nil {
+		c.logger.Warn(fmt.Sprintf("Failed to save message: %s", err))
+	}
+}
+
+// trace saves the message received as the NATS message, tracing the save.
+func (c *consumer) trace(m *nats.Msg, msg mainflux.Message) error {
+	span := tracing.StartSpan(c.tracer, "save", m)
+	defer span.Finish()
+	span.SetTag("channel", msg.GetChannel())
+
+	err := c.save(msg)
+	if err != nil {
+		ext.Error.Set(span, true)
+		span.LogKV("error", err.Error())
+	}
+
+	return err
+}
+
+// save saves the message, unless the writer doesn't save the messages of
+// its channel.
+func (c *consumer) save(msg mainflux.Message) error {
 	if !c.channelExists(msg.GetChannel()) {
-		return
+		return nil
 	}
 
-	if err := c.repo.Save(*msg); err != nil {
-		c.logger.Warn(fmt.Sprintf("Failed to save message: %s", err))
-		return
+	if err := c.repo.Save(msg); err != nil {
+		return err
+	}
+
+	if c.retainer != nil {
+		c.retainer.Track(msg.GetChannel())
 	}
+
+	return nil
 }
 
 func (c *consumer) channelExists(channel string) bool {
@@ -11,7 +11,8 @@ import (
 	"sync"
 
 	"github.com/mainflux/mainflux"
-	broker "github.com/nats-io/go-nats"
+	"github.com/mainflux/mainflux/payload"
+	broker "github.com/nats-io/nats.go"
 )
 
 var (
@@ -88,6 +89,8 @@ func (as *adapterService) Publish(ctx context.Context, token string, msg mainflu
 		switch err {
 		case broker.ErrConnectionClosed, broker.ErrInvalidConnection:
 			return ErrFailedConnection
+		case payload.ErrTooLarge:
+			return err
 		default:
 			return ErrFailedMessagePublish
 		}
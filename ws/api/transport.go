@@ -14,10 +14,14 @@ import (
 	"time"
 
 	"github.com/go-zoo/bone"
+	"github.com/gofrs/uuid"
 	"github.com/gorilla/websocket"
 	"github.com/mainflux/mainflux"
 	log "github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/presence"
+	"github.com/mainflux/mainflux/sessions"
 	"github.com/mainflux/mainflux/things"
+	"github.com/mainflux/mainflux/things/keys"
 	"github.com/mainflux/mainflux/ws"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"google.golang.org/grpc/codes"
@@ -42,6 +46,9 @@ var (
 		},
 	}
 	auth              mainflux.ThingsServiceClient
+	counter           sessions.Counter
+	events            presence.EventPublisher
+	aliases           bool
 	logger            log.Logger
 	channelPartRegExp = regexp.MustCompile(`^/channels/([\w\-]+)/messages(/[^?]*)?(\?.*)?$`)
 )
@@ -51,15 +58,29 @@ var contentTypes = map[string]int{
 	mainflux.SenMLCBOR: websocket.BinaryMessage,
 }
 
-// MakeHandler returns http handler with handshake endpoint.
-func MakeHandler(svc ws.Service, tc mainflux.ThingsServiceClient, l log.Logger) http.Handler {
+// MakeHandler returns http handler with handshake endpoint. If the sessions
+// counter is nil, the number of concurrent connections isn't limited. If the
+// event publisher is nil, connect and disconnect events aren't published. If
+// the channel aliases are used, the channels are identified by their aliases
+// in the URL instead of the channel IDs.
+func MakeHandler(svc ws.Service, tc mainflux.ThingsServiceClient, sc sessions.Counter, ep presence.EventPublisher, useAliases bool, l log.Logger) http.Handler {
 	auth = tc
+	counter = sc
+	events = ep
+	aliases = useAliases
 	logger = l
 
 	mux := bone.New()
 	mux.GetFunc("/channels/:id/messages", handshake(svc))
 	mux.GetFunc("/channels/:id/messages/*", handshake(svc))
 	mux.GetFunc("/version", mainflux.Version("websocket"))
+	mux.GetFunc(mainflux.DiscoveryPath, mainflux.Discovery("websocket", mainflux.Capabilities{
+		Features: map[string]bool{
+			"channel_aliases": useAliases,
+			"session_limit":   sc != nil,
+		},
+		ContentTypes: []string{mainflux.SenMLJSON, mainflux.SenMLCBOR},
+	}))
 	mux.Handle("/metrics", promhttp.Handler())
 
 	return mux
@@ -96,29 +117,69 @@ func handshake(svc ws.Service) http.HandlerFunc {
 			return
 		}
 
+		sub.counter = counter
+		sub.session, err = acquireSession(sub.pubID)
+		if err != nil {
+			switch err {
+			case sessions.ErrLimitExceeded:
+				logger.Warn(fmt.Sprintf("Thing %s exceeded the maximum number of connections", sub.pubID))
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			default:
+				logger.Warn(fmt.Sprintf("Failed to acquire session: %s", err))
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+		}
+
+		connID, err := uuid.NewV4()
+		if err != nil {
+			logger.Warn(fmt.Sprintf("Failed to generate connection ID: %s", err))
+			w.WriteHeader(http.StatusInternalServerError)
+			sub.releaseSession()
+			return
+		}
+		sub.connID = connID.String()
+		sub.events = events
+
 		// Create new ws connection.
 		conn, err := upgrader.Upgrade(w, r, nil)
 		if err != nil {
 			logger.Warn(fmt.Sprintf("Failed to upgrade connection to websocket: %s", err))
+			sub.releaseSession()
 			return
 		}
 		sub.conn = conn
 
 		logger.Debug(fmt.Sprintf("Successfully upgraded communication to WS on channel %s", sub.chanID))
 
-		sub.channel = ws.NewChannel()
-		if err := svc.Subscribe(sub.chanID, sub.subtopic, sub.channel); err != nil {
-			logger.Warn(fmt.Sprintf("Failed to subscribe to NATS subject: %s", err))
-			conn.Close()
-			return
+		// Messages aren't delivered to the things that can only publish.
+		if sub.canSubscribe {
+			sub.channel = ws.NewChannel()
+			if err := svc.Subscribe(sub.chanID, sub.subtopic, sub.channel); err != nil {
+				logger.Warn(fmt.Sprintf("Failed to subscribe to NATS subject: %s", err))
+				conn.Close()
+				sub.releaseSession()
+				return
+			}
+
+			logger.Debug(fmt.Sprintf("Successfully subscribed to NATS channel %s", sub.chanID))
+
+			go sub.listen()
 		}
 
-		logger.Debug(fmt.Sprintf("Successfully subscribed to NATS channel %s", sub.chanID))
+		sub.publishEvent(presence.Connect)
 
-		go sub.listen()
+		done := make(chan struct{})
+		go sub.refreshSession(done)
 
 		// Start listening for messages from NATS.
-		go sub.broadcast(svc, ct)
+		go func() {
+			sub.broadcast(svc, ct)
+			close(done)
+			sub.releaseSession()
+			sub.publishEvent(presence.Disconnect)
+		}()
 	}
 }
 
@@ -164,29 +225,122 @@ func authorize(r *http.Request) (subscription, error) {
 		authKey = authKeys[0]
 	}
 
+	if keys.Malformed(authKey) {
+		logger.Debug("Malformed authorization key.")
+		return subscription{}, things.ErrUnauthorizedAccess
+	}
+
 	chanID := bone.GetValue(r, "id")
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 	defer cancel()
 
-	id, err := auth.CanAccess(ctx, &mainflux.AccessReq{Token: authKey, ChanID: chanID})
-	if err != nil {
-		e, ok := status.FromError(err)
-		if ok && e.Code() == codes.PermissionDenied {
-			return subscription{}, things.ErrUnauthorizedAccess
+	if aliases {
+		var err error
+		if chanID, err = resolveAlias(ctx, chanID); err != nil {
+			return subscription{}, err
 		}
+	}
+
+	// Thing has to be allowed either to subscribe or to publish, so that the
+	// publish-only keys of the sensors can't be used to receive the commands
+	// sent to the channel, and the subscribe-only keys can't be used to send
+	// them.
+	subID, err := canAccess(ctx, authKey, chanID, things.Subscribe)
+	if err != nil && err != things.ErrUnauthorizedAccess {
 		return subscription{}, err
 	}
-	logger.Debug(fmt.Sprintf("Successfully authorized client %s on channel %s", id.GetValue(), chanID))
+	canSubscribe := err == nil
+
+	pubID, err := canAccess(ctx, authKey, chanID, things.Publish)
+	if err != nil && err != things.ErrUnauthorizedAccess {
+		return subscription{}, err
+	}
+	canPublish := err == nil
+
+	if !canSubscribe && !canPublish {
+		return subscription{}, things.ErrUnauthorizedAccess
+	}
+
+	id := subID
+	if !canSubscribe {
+		id = pubID
+	}
+	logger.Debug(fmt.Sprintf("Successfully authorized client %s on channel %s", id, chanID))
 
 	sub := subscription{
-		pubID:  id.GetValue(),
-		chanID: chanID,
+		pubID:        id,
+		chanID:       chanID,
+		canPublish:   canPublish,
+		canSubscribe: canSubscribe,
 	}
 
 	return sub, nil
 }
 
+// canAccess returns the ID of the thing identified by the provided key if it
+// is allowed the action on the channel.
+func canAccess(ctx context.Context, key, chanID, action string) (string, error) {
+	id, err := auth.CanAccess(ctx, &mainflux.AccessReq{Token: key, ChanID: chanID, Action: action})
+	if err != nil {
+		e, ok := status.FromError(err)
+		if ok && e.Code() == codes.PermissionDenied {
+			return "", things.ErrUnauthorizedAccess
+		}
+		return "", err
+	}
+
+	return id.GetValue(), nil
+}
+
+// resolveAlias returns the ID of the channel having the provided alias.
+// Unknown aliases are rejected the same way as the channels the thing
+// isn't connected to.
+func resolveAlias(ctx context.Context, alias string) (string, error) {
+	id, err := auth.ResolveAlias(ctx, &mainflux.ChannelAlias{Value: alias})
+	if err != nil {
+		e, ok := status.FromError(err)
+		if ok && (e.Code() == codes.NotFound || e.Code() == codes.InvalidArgument) {
+			return "", things.ErrUnauthorizedAccess
+		}
+		return "", err
+	}
+
+	return id.GetValue(), nil
+}
+
+// acquireSession registers the new connection of the thing, making sure that
+// neither the thing nor its owner exceeded the connection limits.
+func acquireSession(thingID string) (*sessions.Session, error) {
+	if counter == nil {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	owner, err := auth.Owner(ctx, &mainflux.ThingID{Value: thingID})
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+
+	session := sessions.Session{
+		ID:    id.String(),
+		Thing: thingID,
+		Owner: owner.GetValue(),
+	}
+	if err := counter.Acquire(ctx, session); err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
 func contentType(r *http.Request) string {
 	ct := r.Header.Get("Content-Type")
 	if ct == "" {
@@ -201,11 +355,67 @@ func contentType(r *http.Request) string {
 }
 
 type subscription struct {
-	pubID    string
-	chanID   string
-	subtopic string
-	conn     *websocket.Conn
-	channel  *ws.Channel
+	pubID        string
+	chanID       string
+	subtopic     string
+	canPublish   bool
+	canSubscribe bool
+	conn         *websocket.Conn
+	channel      *ws.Channel
+	counter      sessions.Counter
+	session      *sessions.Session
+	connID       string
+	events       presence.EventPublisher
+}
+
+// refreshSession keeps the session alive until the connection is closed.
+func (sub subscription) refreshSession(done chan struct{}) {
+	if sub.session == nil {
+		return
+	}
+
+	ticker := time.NewTicker(sessions.TTL / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := sub.counter.Refresh(context.Background(), *sub.session); err != nil {
+				logger.Warn(fmt.Sprintf("Failed to refresh session: %s", err))
+			}
+		case <-done:
+			return
+		}
+	}
+}
+
+func (sub subscription) releaseSession() {
+	if sub.session == nil {
+		return
+	}
+
+	if err := sub.counter.Release(context.Background(), *sub.session); err != nil {
+		logger.Warn(fmt.Sprintf("Failed to release session: %s", err))
+	}
+}
+
+// publishEvent publishes the connectivity event of the connection.
+func (sub subscription) publishEvent(eventType string) {
+	if sub.events == nil {
+		return
+	}
+
+	e := presence.Event{
+		Thing:    sub.pubID,
+		Channel:  sub.chanID,
+		Session:  sub.connID,
+		Protocol: protocol,
+		Type:     eventType,
+		Time:     time.Now(),
+	}
+	if err := sub.events.Publish(e); err != nil {
+		logger.Warn(fmt.Sprintf("Failed to publish %s event: %s", eventType, err))
+	}
 }
 
 func (sub subscription) broadcast(svc ws.Service, contentType string) {
@@ -213,13 +423,19 @@ func (sub subscription) broadcast(svc ws.Service, contentType string) {
 		_, payload, err := sub.conn.ReadMessage()
 		if websocket.IsUnexpectedCloseError(err) {
 			logger.Debug(fmt.Sprintf("Closing WS connection: %s", err.Error()))
-			sub.channel.Close()
+			if sub.channel != nil {
+				sub.channel.Close()
+			}
 			return
 		}
 		if err != nil {
 			logger.Warn(fmt.Sprintf("Failed to read message: %s", err))
 			return
 		}
+		if !sub.canPublish {
+			logger.Warn(fmt.Sprintf("Thing %s is not allowed to publish to the channel %s", sub.pubID, sub.chanID))
+			continue
+		}
 		msg := mainflux.RawMessage{
 			Channel:     sub.chanID,
 			Subtopic:    sub.subtopic,
@@ -232,7 +448,9 @@ func (sub subscription) broadcast(svc ws.Service, contentType string) {
 			logger.Warn(fmt.Sprintf("Failed to publish message to NATS: %s", err))
 			if err == ws.ErrFailedConnection {
 				sub.conn.Close()
-				sub.channel.Closed <- true
+				if sub.channel != nil {
+					sub.channel.Closed <- true
+				}
 				return
 			}
 		}
@@ -10,19 +10,26 @@ import (
 	"net/url"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/mainflux/mainflux"
 	log "github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/presence"
+	pmocks "github.com/mainflux/mainflux/presence/mocks"
+	"github.com/mainflux/mainflux/sessions"
+	smocks "github.com/mainflux/mainflux/sessions/mocks"
+	"github.com/mainflux/mainflux/things"
 	"github.com/mainflux/mainflux/ws"
 	"github.com/mainflux/mainflux/ws/api"
 	"github.com/mainflux/mainflux/ws/mocks"
-	broker "github.com/nats-io/go-nats"
+	broker "github.com/nats-io/nats.go"
 	"github.com/stretchr/testify/assert"
 )
 
 const (
 	id       = "1"
+	alias    = "temp"
 	token    = "token"
 	protocol = "ws"
 )
@@ -38,9 +45,9 @@ func newService() ws.Service {
 	return ws.New(pubsub)
 }
 
-func newHTTPServer(svc ws.Service, tc mainflux.ThingsServiceClient) *httptest.Server {
+func newHTTPServer(svc ws.Service, tc mainflux.ThingsServiceClient, sc sessions.Counter, ep presence.EventPublisher, aliases bool) *httptest.Server {
 	logger, _ := log.New(os.Stdout, log.Info.String())
-	mux := api.MakeHandler(svc, tc, logger)
+	mux := api.MakeHandler(svc, tc, sc, ep, aliases, logger)
 	return httptest.NewServer(mux)
 }
 
@@ -73,7 +80,7 @@ func handshake(tsURL, chanID, subtopic, token string, addHeader bool) (*websocke
 func TestHandshake(t *testing.T) {
 	thingsClient := newThingsClient()
 	svc := newService()
-	ts := newHTTPServer(svc, thingsClient)
+	ts := newHTTPServer(svc, thingsClient, nil, nil, false)
 	defer ts.Close()
 
 	cases := []struct {
@@ -109,3 +116,132 @@ func TestHandshake(t *testing.T) {
 		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s\n", tc.desc, err))
 	}
 }
+
+func TestHandshakeWithAliases(t *testing.T) {
+	thingsClient := mocks.NewThingsClientWithAliases(map[string]string{token: id}, map[string]string{alias: id})
+	svc := newService()
+	ts := newHTTPServer(svc, thingsClient, nil, nil, true)
+	defer ts.Close()
+
+	cases := []struct {
+		desc   string
+		chanID string
+		token  string
+		status int
+		msg    []byte
+	}{
+		{"connect using channel alias and send message", alias, token, http.StatusSwitchingProtocols, msg},
+		{"connect using channel id", id, token, http.StatusForbidden, []byte{}},
+		{"connect using non-existent channel alias", "unknown", token, http.StatusForbidden, []byte{}},
+		{"connect using channel alias with invalid token", alias, "invalid", http.StatusForbidden, []byte{}},
+	}
+
+	for _, tc := range cases {
+		conn, res, err := handshake(ts.URL, tc.chanID, "", tc.token, true)
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d\n", tc.desc, tc.status, res.StatusCode))
+		if err != nil {
+			continue
+		}
+		err = conn.WriteMessage(websocket.TextMessage, tc.msg)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s\n", tc.desc, err))
+	}
+}
+
+func TestConnectionLimits(t *testing.T) {
+	thingsClient := newThingsClient()
+	svc := newService()
+	counter := smocks.NewCounter(sessions.Limits{Thing: 2})
+	ts := newHTTPServer(svc, thingsClient, counter, nil, false)
+	defer ts.Close()
+
+	cases := []struct {
+		desc   string
+		status int
+	}{
+		{"connect first time", http.StatusSwitchingProtocols},
+		{"connect second time", http.StatusSwitchingProtocols},
+		{"connect exceeding the limit", http.StatusTooManyRequests},
+	}
+
+	for _, tc := range cases {
+		conn, res, err := handshake(ts.URL, id, "", token, true)
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d\n", tc.desc, tc.status, res.StatusCode))
+		if err != nil {
+			continue
+		}
+		defer conn.Close()
+	}
+}
+
+func TestConnectivityEvents(t *testing.T) {
+	thingsClient := newThingsClient()
+	svc := newService()
+	events := pmocks.NewEventPublisher()
+	ts := newHTTPServer(svc, thingsClient, nil, events, false)
+	defer ts.Close()
+
+	conn, _, err := handshake(ts.URL, id, "", token, true)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error %s\n", err))
+	conn.Close()
+
+	// Disconnect event is published once the closed connection is read.
+	for i := 0; i < 100 && len(events.Events()) < 2; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	published := events.Events()
+	assert.Len(t, published, 2, fmt.Sprintf("expected 2 events got %d\n", len(published)))
+	if len(published) != 2 {
+		return
+	}
+
+	cases := []struct {
+		desc      string
+		event     presence.Event
+		eventType string
+	}{
+		{"publish connect event", published[0], presence.Connect},
+		{"publish disconnect event", published[1], presence.Disconnect},
+	}
+
+	for _, tc := range cases {
+		assert.Equal(t, tc.eventType, tc.event.Type, fmt.Sprintf("%s: expected type %s got %s\n", tc.desc, tc.eventType, tc.event.Type))
+		assert.Equal(t, protocol, tc.event.Protocol, fmt.Sprintf("%s: expected protocol %s got %s\n", tc.desc, protocol, tc.event.Protocol))
+		assert.Equal(t, id, tc.event.Channel, fmt.Sprintf("%s: expected channel %s got %s\n", tc.desc, id, tc.event.Channel))
+		assert.Equal(t, published[0].Session, tc.event.Session, fmt.Sprintf("%s: expected session %s got %s\n", tc.desc, published[0].Session, tc.event.Session))
+	}
+}
+
+func TestHandshakeWithActions(t *testing.T) {
+	thingsClient := mocks.NewThingsClientWithActions(map[string]string{
+		"pub":  "2",
+		"sub":  "3",
+		"none": "4",
+	}, map[string][]string{
+		"pub":  {things.Publish},
+		"sub":  {things.Subscribe},
+		"none": {things.ReadHistory},
+	})
+	svc := newService()
+	ts := newHTTPServer(svc, thingsClient, nil, nil, false)
+	defer ts.Close()
+
+	cases := []struct {
+		desc   string
+		token  string
+		status int
+	}{
+		{"connect with publish-only key", "pub", http.StatusSwitchingProtocols},
+		{"connect with subscribe-only key", "sub", http.StatusSwitchingProtocols},
+		{"connect with key allowed neither to publish nor to subscribe", "none", http.StatusForbidden},
+	}
+
+	for _, tc := range cases {
+		conn, res, err := handshake(ts.URL, id, "", tc.token, true)
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d\n", tc.desc, tc.status, res.StatusCode))
+		if err != nil {
+			continue
+		}
+		conn.Close()
+	}
+}
@@ -43,5 +43,10 @@ func (svc *mockService) Subscribe(chanID, subtopic string, channel *ws.Channel)
 	}
 	svc.subscriptions[chanID] = channel
 
+	// Closing the channel waits for the subscription to be closed.
+	go func() {
+		<-channel.Closed
+	}()
+
 	return nil
 }
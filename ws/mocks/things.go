@@ -18,16 +18,36 @@ import (
 
 var _ mainflux.ThingsServiceClient = (*thingsClient)(nil)
 
-// ServiceErrToken is used to simulate internal server error.
-const ServiceErrToken = "unavailable"
+const (
+	// ServiceErrToken is used to simulate internal server error.
+	ServiceErrToken = "unavailable"
+
+	// Owner is the owner of all the things known to the mock.
+	Owner = "john.doe@email.com"
+)
 
 type thingsClient struct {
-	things map[string]string
+	things  map[string]string
+	aliases map[string]string
+	actions map[string][]string
 }
 
 // NewThingsClient returns mock implementation of things service client.
 func NewThingsClient(data map[string]string) mainflux.ThingsServiceClient {
-	return &thingsClient{data}
+	return &thingsClient{things: data}
+}
+
+// NewThingsClientWithAliases returns mock implementation of things service
+// client that resolves the provided channel aliases to the channel IDs.
+func NewThingsClientWithAliases(data, aliases map[string]string) mainflux.ThingsServiceClient {
+	return &thingsClient{things: data, aliases: aliases}
+}
+
+// NewThingsClientWithActions returns mock implementation of things service
+// client that allows the things identified by the provided keys only the
+// provided actions. Things whose keys aren't listed are allowed all actions.
+func NewThingsClientWithActions(data map[string]string, actions map[string][]string) mainflux.ThingsServiceClient {
+	return &thingsClient{things: data, actions: actions}
 }
 
 func (tc thingsClient) CanAccess(ctx context.Context, req *mainflux.AccessReq, opts ...grpc.CallOption) (*mainflux.ThingID, error) {
@@ -48,9 +68,23 @@ func (tc thingsClient) CanAccess(ctx context.Context, req *mainflux.AccessReq, o
 		return nil, status.Error(codes.PermissionDenied, "invalid credentials provided")
 	}
 
+	if actions, ok := tc.actions[key]; ok && !contains(actions, req.GetAction()) {
+		return nil, status.Error(codes.PermissionDenied, "action not allowed")
+	}
+
 	return &mainflux.ThingID{Value: id}, nil
 }
 
+func contains(actions []string, action string) bool {
+	for _, a := range actions {
+		if a == action {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (tc thingsClient) CanAccessByID(context.Context, *mainflux.AccessByIDReq, ...grpc.CallOption) (*empty.Empty, error) {
 	panic("not implemented")
 }
@@ -58,3 +92,54 @@ func (tc thingsClient) CanAccessByID(context.Context, *mainflux.AccessByIDReq, .
 func (tc thingsClient) Identify(context.Context, *mainflux.Token, ...grpc.CallOption) (*mainflux.ThingID, error) {
 	panic("not implemented")
 }
+
+func (tc thingsClient) Owner(_ context.Context, req *mainflux.ThingID, _ ...grpc.CallOption) (*mainflux.UserID, error) {
+	for _, id := range tc.things {
+		if id == req.GetValue() {
+			return &mainflux.UserID{Value: Owner}, nil
+		}
+	}
+
+	return nil, status.Error(codes.NotFound, "entity does not exist")
+}
+
+func (tc thingsClient) Retention(context.Context, *mainflux.ChannelID, ...grpc.CallOption) (*mainflux.Retention, error) {
+	panic("not implemented")
+}
+
+func (tc thingsClient) Region(context.Context, *mainflux.ChannelID, ...grpc.CallOption) (*mainflux.Region, error) {
+	panic("not implemented")
+}
+
+func (tc thingsClient) Alias(context.Context, *mainflux.ChannelID, ...grpc.CallOption) (*mainflux.ChannelAlias, error) {
+	panic("not implemented")
+}
+
+func (tc thingsClient) ResolveAlias(_ context.Context, req *mainflux.ChannelAlias, _ ...grpc.CallOption) (*mainflux.ChannelID, error) {
+	id, ok := tc.aliases[req.GetValue()]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "entity does not exist")
+	}
+
+	return &mainflux.ChannelID{Value: id}, nil
+}
+
+func (tc thingsClient) Ordering(context.Context, *mainflux.ChannelID, ...grpc.CallOption) (*mainflux.Ordering, error) {
+	panic("not implemented")
+}
+
+func (tc thingsClient) Transformer(context.Context, *mainflux.ChannelID, ...grpc.CallOption) (*mainflux.Transformer, error) {
+	panic("not implemented")
+}
+
+func (tc thingsClient) PayloadSchema(context.Context, *mainflux.PayloadSchemaReq, ...grpc.CallOption) (*mainflux.PayloadSchema, error) {
+	panic("not implemented")
+}
+
+func (tc thingsClient) KeyDigest(context.Context, *mainflux.ThingID, ...grpc.CallOption) (*mainflux.KeyDigest, error) {
+	panic("not implemented")
+}
+
+func (tc thingsClient) Changes(context.Context, *mainflux.ChangesReq, ...grpc.CallOption) (mainflux.ThingsService_ChangesClient, error) {
+	panic("not implemented")
+}
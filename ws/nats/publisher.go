@@ -13,8 +13,10 @@ import (
 	"github.com/gogo/protobuf/proto"
 	"github.com/mainflux/mainflux"
 	log "github.com/mainflux/mainflux/logger"
+	mfnats "github.com/mainflux/mainflux/nats"
+	"github.com/mainflux/mainflux/tracing"
 	"github.com/mainflux/mainflux/ws"
-	broker "github.com/nats-io/go-nats"
+	broker "github.com/nats-io/nats.go"
 )
 
 const (
@@ -26,13 +28,15 @@ const (
 var _ ws.Service = (*natsPubSub)(nil)
 
 type natsPubSub struct {
-	nc     *broker.Conn
-	cb     *gobreaker.CircuitBreaker
-	logger log.Logger
+	nc            *broker.Conn
+	subjectPrefix string
+	cb            *gobreaker.CircuitBreaker
+	logger        log.Logger
 }
 
-// New instantiates NATS message publisher.
-func New(nc *broker.Conn, logger log.Logger) ws.Service {
+// New instantiates NATS message publisher. The subjects are prefixed with the
+// deployment subject prefix, unless it is empty.
+func New(nc *broker.Conn, subjectPrefix string, logger log.Logger) ws.Service {
 	st := gobreaker.Settings{
 		Name: "NATS",
 		ReadyToTrip: func(counts gobreaker.Counts) bool {
@@ -42,9 +46,10 @@ func New(nc *broker.Conn, logger log.Logger) ws.Service {
 	}
 	cb := gobreaker.NewCircuitBreaker(st)
 	return &natsPubSub{
-		nc:     nc,
-		cb:     cb,
-		logger: logger,
+		nc:            nc,
+		subjectPrefix: subjectPrefix,
+		cb:            cb,
+		logger:        logger,
 	}
 }
 
@@ -53,17 +58,17 @@ func (pubsub *natsPubSub) fmtSubject(chanID, subtopic string) string {
 	if subtopic != "" {
 		subject = fmt.Sprintf("%s.%s", subject, subtopic)
 	}
-	return subject
+	return mfnats.Subject(pubsub.subjectPrefix, subject)
 }
 
-func (pubsub *natsPubSub) Publish(_ context.Context, _ string, msg mainflux.RawMessage) error {
+func (pubsub *natsPubSub) Publish(ctx context.Context, _ string, msg mainflux.RawMessage) error {
 	data, err := proto.Marshal(&msg)
 	if err != nil {
 		return err
 	}
 
 	subject := pubsub.fmtSubject(msg.Channel, msg.Subtopic)
-	return pubsub.nc.Publish(subject, data)
+	return tracing.Publish(ctx, pubsub.nc, subject, data)
 }
 
 func (pubsub *natsPubSub) Subscribe(chanID, subtopic string, channel *ws.Channel) error {